@@ -77,6 +77,29 @@ Examples:
 	},
 }
 
+// validateCommitCmd represents the validate command
+var validateCommitCmd = &cobra.Command{
+	Use:   "validate [repo/branch/commit]",
+	Short: "Validate the schema of a commit",
+	Long: `Validate the schema of a commit before deploying it. Checks that foreign keys, indexes and
+constraints reference existing tables and columns and that column types are known, and applies
+lint rules (naming_convention, missing_primary_key, unindexed_foreign_key).
+	
+Examples:
+  # Validate with the default lint rules
+  redb commits validate myrepo/main/abc123
+  
+  # Only check consistency
+  redb commits validate myrepo/main/abc123 --no-lint
+  
+  # Require primary keys and camelCase names
+  redb commits validate myrepo/main/abc123 --naming camel_case --severity missing_primary_key=critical`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return commits.ValidateCommit(args[0], cmd.Flags())
+	},
+}
+
 // forkCmd represents the fork command
 var forkCmd = &cobra.Command{
 	Use:   "fork [repo/branch/commit]",
@@ -118,11 +141,18 @@ func init() {
 	forkCmd.Flags().String("name", "", "Target repository name (required)")
 	forkCmd.Flags().String("db-type", "", "Target database type for schema conversion (optional, e.g., postgres, mysql, mongodb)")
 
+	// Add flags to validateCommitCmd
+	validateCommitCmd.Flags().Bool("no-lint", false, "Only run the consistency checks")
+	validateCommitCmd.Flags().StringSlice("rules", nil, "Lint rules to apply (default all)")
+	validateCommitCmd.Flags().String("naming", "", "Naming convention: snake_case (default), upper_snake_case, camel_case or pascal_case")
+	validateCommitCmd.Flags().StringToString("severity", nil, "Severity per lint rule, e.g. missing_primary_key=critical")
+
 	// Add subcommands to commits command
 	commitsCmd.AddCommand(showCommitCmd)
 	commitsCmd.AddCommand(branchCommitCmd)
 	commitsCmd.AddCommand(mergeCommitCmd)
 	commitsCmd.AddCommand(deployCommitCmd)
 	commitsCmd.AddCommand(deploySchemaCmd)
+	commitsCmd.AddCommand(validateCommitCmd)
 	commitsCmd.AddCommand(forkCmd)
 }
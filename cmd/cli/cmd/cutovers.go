@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/cutovers"
+	"github.com/spf13/cobra"
+)
+
+// cutoversCmd represents the cutovers command
+var cutoversCmd = &cobra.Command{
+	Use:   "cutovers",
+	Short: "Manage cutovers of applications between databases",
+	Long: `Manage cutovers, which move an application from the source database of a
+relationship to its target database.
+
+A cutover runs these steps in order:
+  1. stop_writes:       call the stop writes hook
+  2. drain_cdc:         wait until the relationship has no pending CDC events
+  3. reconcile:         compare the row counts of the source and target tables
+  4. switch_connection: point the application connection to the target database
+  5. unpause:           call the unpause hook
+
+Failing steps are retried, and gated steps wait for an approval before they run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// addCutoverCmd represents the add cutover command
+var addCutoverCmd = &cobra.Command{
+	Use:   "add [cutover-name] --relationship [relationship-name] --connection [connection-name]",
+	Short: "Add a new cutover",
+	Long: `Add a pending cutover of an application connection to the target database of a
+relationship. The connection is created, pointing to the source database, if it
+does not exist yet.
+
+Hooks are URLs receiving a POST with a JSON event once the cutover reaches their
+step; they answer with a 2xx status once the applications stopped or resumed
+their writes. Without hooks, their steps are skipped.
+
+switch_connection is gated by default; pass --gate to gate other steps, or
+--gate "" to run all steps without approval.
+
+Examples:
+  # Add a cutover with hooks, approving the switch by hand
+  redb cutovers add orders-to-aurora --relationship orders-replication --connection orders-app \
+    --stop-writes-hook https://orders.internal/maintenance/on \
+    --unpause-hook https://orders.internal/maintenance/off
+
+  # Also approve the stop of the writes, and allow the CDC 30 minutes to drain
+  redb cutovers add orders-to-aurora --relationship orders-replication --connection orders-app \
+    --gate stop_writes --gate switch_connection --drain-timeout 30m`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var options cutovers.AddCutoverOptions
+		options.Description, _ = cmd.Flags().GetString("description")
+		options.Relationship, _ = cmd.Flags().GetString("relationship")
+		options.Connection, _ = cmd.Flags().GetString("connection")
+		options.StopWritesHook, _ = cmd.Flags().GetString("stop-writes-hook")
+		options.UnpauseHook, _ = cmd.Flags().GetString("unpause-hook")
+		options.MaxRetries, _ = cmd.Flags().GetInt32("max-retries")
+		options.DrainTimeout, _ = cmd.Flags().GetDuration("drain-timeout")
+		gates, _ := cmd.Flags().GetStringArray("gate")
+
+		if options.Relationship == "" || options.Connection == "" {
+			return fmt.Errorf("--relationship and --connection flags are required")
+		}
+		for _, gate := range gates {
+			if gate != "" {
+				options.Gates = append(options.Gates, gate)
+			}
+		}
+
+		return cutovers.AddCutover(args[0], options)
+	},
+}
+
+// listCutoversCmd represents the list cutovers command
+var listCutoversCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all cutovers",
+	Long:  `Display a formatted list of all cutovers in the active workspace with their state and current step.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cutovers.ListCutovers()
+	},
+}
+
+// showCutoverCmd represents the show cutover command
+var showCutoverCmd = &cobra.Command{
+	Use:   "show [cutover-name]",
+	Short: "Show cutover details",
+	Long:  `Display detailed information about a cutover, including the status, attempts and outcome of each step.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cutovers.ShowCutover(args[0])
+	},
+}
+
+// startCutoverCmd represents the start cutover command
+var startCutoverCmd = &cobra.Command{
+	Use:   "start [cutover-name]",
+	Short: "Start a cutover",
+	Long: `Start a pending cutover. Its steps run in the background of the core service;
+the command follows them until the cutover completes, fails or reaches a gate.
+
+Examples:
+  # Start a cutover and follow it
+  redb cutovers start orders-to-aurora
+
+  # Start a cutover without waiting
+  redb cutovers start orders-to-aurora --wait=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wait, _ := cmd.Flags().GetBool("wait")
+		return cutovers.StartCutover(args[0], wait)
+	},
+}
+
+// approveCutoverCmd represents the approve cutover command
+var approveCutoverCmd = &cobra.Command{
+	Use:   "approve [cutover-name]",
+	Short: "Approve the step a cutover waits at",
+	Long: `Approve the gated step a cutover waits at, and follow the steps that follow.
+
+Examples:
+  # Approve the switch of the connection
+  redb cutovers approve orders-to-aurora --step switch_connection`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		step, _ := cmd.Flags().GetString("step")
+		wait, _ := cmd.Flags().GetBool("wait")
+		return cutovers.ApproveCutover(args[0], step, wait)
+	},
+}
+
+// retryCutoverCmd represents the retry cutover command
+var retryCutoverCmd = &cobra.Command{
+	Use:   "retry [cutover-name]",
+	Short: "Retry the step a cutover failed at",
+	Long:  `Run the step a failed cutover stopped at again, with its retries reset, and follow the cutover.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wait, _ := cmd.Flags().GetBool("wait")
+		return cutovers.RetryCutover(args[0], wait)
+	},
+}
+
+// abortCutoverCmd represents the abort cutover command
+var abortCutoverCmd = &cobra.Command{
+	Use:   "abort [cutover-name]",
+	Short: "Abort a cutover",
+	Long: `Stop the steps of a cutover and abort it. Writes that were stopped are resumed
+through the unpause hook. A connection that was already switched is not switched back.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cutovers.AbortCutover(args[0])
+	},
+}
+
+// deleteCutoverCmd represents the delete cutover command
+var deleteCutoverCmd = &cobra.Command{
+	Use:   "delete [cutover-name]",
+	Short: "Delete a cutover",
+	Long:  `Delete a cutover that is not running. Running cutovers must be aborted first.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cutovers.DeleteCutover(args[0])
+	},
+}
+
+// connectionsCutoverCmd represents the connections command
+var connectionsCutoverCmd = &cobra.Command{
+	Use:   "connections",
+	Short: "List application connections",
+	Long: `Display the application connections of the active workspace with the database
+they point to, and the cutover that last switched them.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cutovers.ListConnections()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cutoversCmd)
+
+	// Add subcommands
+	cutoversCmd.AddCommand(addCutoverCmd)
+	cutoversCmd.AddCommand(listCutoversCmd)
+	cutoversCmd.AddCommand(showCutoverCmd)
+	cutoversCmd.AddCommand(startCutoverCmd)
+	cutoversCmd.AddCommand(approveCutoverCmd)
+	cutoversCmd.AddCommand(retryCutoverCmd)
+	cutoversCmd.AddCommand(abortCutoverCmd)
+	cutoversCmd.AddCommand(deleteCutoverCmd)
+	cutoversCmd.AddCommand(connectionsCutoverCmd)
+
+	// Add flags to addCutoverCmd
+	addCutoverCmd.Flags().String("description", "", "Description of the cutover")
+	addCutoverCmd.Flags().String("relationship", "", "Relationship replicating the source database to the target database (required)")
+	addCutoverCmd.Flags().String("connection", "", "Application connection to switch (required)")
+	addCutoverCmd.Flags().String("stop-writes-hook", "", "URL called to stop the writes of the applications")
+	addCutoverCmd.Flags().String("unpause-hook", "", "URL called to resume the writes of the applications")
+	addCutoverCmd.Flags().StringArray("gate", []string{"switch_connection"}, "Step approved by hand before it runs (repeatable)")
+	addCutoverCmd.Flags().Int32("max-retries", 3, "Automatic retries of a failing step")
+	addCutoverCmd.Flags().Duration("drain-timeout", 10*time.Minute, "How long to wait for the CDC to drain")
+	addCutoverCmd.MarkFlagRequired("relationship")
+	addCutoverCmd.MarkFlagRequired("connection")
+
+	// Add flags to the commands that follow a cutover
+	startCutoverCmd.Flags().Bool("wait", true, "Follow the cutover until it completes, fails or reaches a gate")
+	approveCutoverCmd.Flags().Bool("wait", true, "Follow the cutover until it completes, fails or reaches a gate")
+	approveCutoverCmd.Flags().String("step", "", "Step the cutover waits at, guarding against approving another one")
+	retryCutoverCmd.Flags().Bool("wait", true, "Follow the cutover until it completes, fails or reaches a gate")
+}
@@ -10,16 +10,40 @@ var databasesCmd = &cobra.Command{
 	Use:   "databases",
 	Short: "Manage databases",
 	Long: "Commands for managing databases including listing, showing details, creating, modifying, connecting, " +
-		"reconnecting, disconnecting, wiping, dropping, and cloning table data.",
+		"reconnecting, disconnecting, wiping, dropping, and cloning table data, and showing the operations supported by each database type.",
 }
 
 // listDatabasesCmd represents the list command
 var listDatabasesCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all databases",
-	Long:  `Display a formatted list of all databases with their basic information.`,
+	Long: `Display a formatted list of all databases with their basic information.
+
+Examples:
+  # List the databases of the analytics team that are not in the gold tier
+  redb databases list --selector team=analytics,tier!=gold`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector, _ := cmd.Flags().GetString("selector")
+		return databases.ListDatabases(selector)
+	},
+}
+
+// labelDatabaseCmd represents the label command
+var labelDatabaseCmd = &cobra.Command{
+	Use:   "label [database-name] [key=value | key-]...",
+	Short: "Set or remove labels of a database",
+	Long: `Set labels of a database with key=value and remove them with key-. Labels group
+databases, e.g. by team, so that commands like "schemas convert" can select them.
+
+Examples:
+  # Label a database as owned by the analytics team
+  redb databases label events_db team=analytics tier=silver
+
+  # Remove the tier label
+  redb databases label events_db tier-`,
+	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return databases.ListDatabases()
+		return databases.LabelDatabase(args[0], args[1:])
 	},
 }
 
@@ -57,6 +81,22 @@ var createDatabaseCmd = &cobra.Command{
 	},
 }
 
+// supportDatabaseCmd represents the support command
+var supportDatabaseCmd = &cobra.Command{
+	Use:   "support [database-type]",
+	Short: "Show the operations supported by database types",
+	Long: "Display the support matrix of the database adapters: for every database type, the operations it supports. " +
+		"With a database type, display each operation with its caveats and mechanisms.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		databaseType := ""
+		if len(args) > 0 {
+			databaseType = args[0]
+		}
+		return databases.ShowSupport(databaseType)
+	},
+}
+
 // modifyDatabaseCmd represents the modify command
 var modifyDatabaseCmd = &cobra.Command{
 	Use:   "modify [database-name]",
@@ -185,14 +225,100 @@ Examples:
   redb databases clone-database prod_app --database staging_app --wipe --with-data
   
   # Clone across nodes
-  redb databases clone-database prod_app --instance test-mysql --db-name test_app --source-node 1 --target-node 2`,
+  redb databases clone-database prod_app --instance test-mysql --db-name test_app --source-node 1 --target-node 2
+
+  # Remap owners and grantees, and drop comments, when converting to another database type
+  redb databases clone-database prod_app --instance test-mysql --db-name test_app \
+    --owner-handling remap --grant-handling remap --comment-handling drop \
+    --owner-mapping app_owner=svc_app --owner-mapping reporting=bi_reader`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return databases.CloneDatabase(args[0], cmd.Flags())
 	},
 }
 
+// planMigrationCmd represents the plan-migration command
+var planMigrationCmd = &cobra.Command{
+	Use:   "plan-migration [database-name]",
+	Short: "Plan the DDL migrating a database to another schema",
+	Long: `Compare the schema of a database with the schema of another database, or with a unified
+model file, and show the ordered CREATE, ALTER and DROP statements migrating the database to it.
+Nothing is executed unless --dry-run=false is given; destructive plans then ask for confirmation
+unless --yes is given. Tables and columns that look renamed are dropped and created again, with a
+warning, unless --apply-renames is given. Plans are generated for PostgreSQL, MySQL and MariaDB
+databases.
+
+Examples:
+  # Show the statements migrating staging_app to the schema of prod_app
+  redb databases plan-migration staging_app --to-database prod_app
+
+  # Migrate to a schema converted by "schemas convert", and save the script
+  redb databases plan-migration app_mysql --to-schema-file out/app/mysql.schema.json --output migrate.sql
+
+  # Execute the migration
+  redb databases plan-migration staging_app --to-database prod_app --dry-run=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options := databases.MigrationOptions{}
+		options.TargetDatabase, _ = cmd.Flags().GetString("to-database")
+		options.SchemaFile, _ = cmd.Flags().GetString("to-schema-file")
+		options.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		options.ApplyRenames, _ = cmd.Flags().GetBool("apply-renames")
+		options.Yes, _ = cmd.Flags().GetBool("yes")
+		options.Output, _ = cmd.Flags().GetString("output")
+		return databases.PlanMigration(args[0], options)
+	},
+}
+
+// schemaHistoryCmd represents the schema-history command
+var schemaHistoryCmd = &cobra.Command{
+	Use:   "schema-history [database-name]",
+	Short: "List the schema snapshots of a database",
+	Long: `List the snapshots kept of the schema of a database, newest first. A snapshot is kept each
+time the discovered schema of the database changes.
+
+Examples:
+  # List the schema snapshots of prod_app
+  redb databases schema-history prod_app
+
+  # Only the last 5 snapshots
+  redb databases schema-history prod_app --limit 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt32("limit")
+		return databases.ShowSchemaHistory(args[0], limit)
+	},
+}
+
+// schemaDiffCmd represents the schema-diff command
+var schemaDiffCmd = &cobra.Command{
+	Use:   "schema-diff [database-name]",
+	Short: "Show how the schema of a database changed between two points in time",
+	Long: `Compare two schema snapshots of a database and show the changes between them, marking the
+breaking ones with "!". --from and --to take a snapshot version, an RFC3339 time, a date, or a
+duration ago such as 72h or 7d; a time selects the latest snapshot taken at or before it. Without
+--to the latest snapshot is used.
+
+Examples:
+  # What changed in the schema of prod_app in the last 3 days
+  redb databases schema-diff prod_app --from 72h
+
+  # Compare two versions
+  redb databases schema-diff prod_app --from 3 --to 5
+
+  # Compare the schema as of two dates
+  redb databases schema-diff prod_app --from 2026-10-01 --to 2026-10-13`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		return databases.DiffSchemaSnapshots(args[0], from, to)
+	},
+}
+
 func init() {
+	listDatabasesCmd.Flags().String("selector", "", "Only list databases whose labels match, e.g. team=analytics,tier!=gold")
+
 	// Add flags to showDatabaseCmd
 	showDatabaseCmd.Flags().Bool("schema", false, "Show database schema information")
 	showDatabaseCmd.Flags().Bool("tables", false, "Show database tables information")
@@ -220,11 +346,32 @@ func init() {
 	cloneDatabaseCmd.Flags().Uint64("source-node", 0, "Source node ID")
 	cloneDatabaseCmd.Flags().Uint64("target-node", 0, "Target node ID")
 
+	// Owner, grant and comment handling for schema conversion
+	cloneDatabaseCmd.Flags().String("owner-handling", "", "Object owners on the target: preserve (default), remap or drop")
+	cloneDatabaseCmd.Flags().String("grant-handling", "", "Grants on the target: preserve, remap or drop (default)")
+	cloneDatabaseCmd.Flags().String("comment-handling", "", "Comments on the target: preserve (default) or drop")
+	cloneDatabaseCmd.Flags().StringSlice("owner-mapping", nil, "Owner or grantee mapping for remap as source=target; an empty target drops it (repeatable)")
+
 	// Add subcommands to databases command
+	// Add flags for plan-migration command
+	planMigrationCmd.Flags().String("to-database", "", "Migrate to the schema of this database")
+	planMigrationCmd.Flags().String("to-schema-file", "", "Migrate to the unified model in this JSON file")
+	planMigrationCmd.Flags().Bool("dry-run", true, "Only show the plan; set to false to execute it")
+	planMigrationCmd.Flags().Bool("apply-renames", false, "Rename the tables and columns that look renamed instead of dropping and creating them again")
+	planMigrationCmd.Flags().Bool("yes", false, "Execute destructive plans without confirmation")
+	planMigrationCmd.Flags().String("output", "", "Write the migration script to this file")
+
+	// Add flags for schema history commands
+	schemaHistoryCmd.Flags().Int32("limit", 0, "Maximum number of snapshots to list")
+	schemaDiffCmd.Flags().String("from", "", "Snapshot to compare from: version, time, date or duration ago (required)")
+	schemaDiffCmd.Flags().String("to", "", "Snapshot to compare to: version, time, date or duration ago (default latest)")
+	schemaDiffCmd.MarkFlagRequired("from")
+
 	databasesCmd.AddCommand(listDatabasesCmd)
 	databasesCmd.AddCommand(showDatabaseCmd)
 	databasesCmd.AddCommand(createDatabaseCmd)
 	databasesCmd.AddCommand(modifyDatabaseCmd)
+	databasesCmd.AddCommand(labelDatabaseCmd)
 	databasesCmd.AddCommand(deleteDatabaseCmd)
 	databasesCmd.AddCommand(connectDatabaseCmd)
 	databasesCmd.AddCommand(reconnectDatabaseCmd)
@@ -233,4 +380,8 @@ func init() {
 	databasesCmd.AddCommand(dropDatabaseCmd)
 	databasesCmd.AddCommand(cloneTableDataCmd)
 	databasesCmd.AddCommand(cloneDatabaseCmd)
+	databasesCmd.AddCommand(supportDatabaseCmd)
+	databasesCmd.AddCommand(planMigrationCmd)
+	databasesCmd.AddCommand(schemaHistoryCmd)
+	databasesCmd.AddCommand(schemaDiffCmd)
 }
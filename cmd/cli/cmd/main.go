@@ -7,6 +7,7 @@ import (
 
 	"github.com/redbco/redb-open/cmd/cli/internal/config"
 	"github.com/redbco/redb-open/cmd/cli/internal/interactive"
+	"github.com/redbco/redb-open/cmd/cli/internal/locale"
 	"github.com/spf13/cobra"
 )
 
@@ -45,11 +46,18 @@ var rootCmd = &cobra.Command{
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	// Localize the CLI before any output
+	if err := locale.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load translations: %v\n", err)
+	}
+	locale.LocalizeCommands(rootCmd)
+	rootCmd.SetErrPrefix(locale.T("Error:"))
+
 	// If no arguments provided (just the binary name), enter interactive mode
 	if len(os.Args) == 1 {
 		err := interactive.StartInteractiveMode(rootCmd)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
 			os.Exit(1)
 		}
 		return
@@ -71,7 +79,7 @@ func init() {
 	// Initialize config when the command is executed
 	cobra.OnInitialize(func() {
 		if err := config.Init(configFile); err != nil {
-			fmt.Printf("Error initializing config: %v\n", err)
+			fmt.Println(locale.Sprintf("Error initializing config: %v", err))
 			os.Exit(1)
 		}
 	})
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/redbco/redb-open/cmd/cli/internal/mappings"
 	"github.com/spf13/cobra"
 )
@@ -26,10 +28,15 @@ var listMappingsCmd = &cobra.Command{
 var showMappingCmd = &cobra.Command{
 	Use:   "show [mapping-name]",
 	Short: "Show mapping details",
-	Long:  `Display detailed information about a specific mapping.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Display detailed information about a specific mapping.
+
+Use --explain to show why the matcher proposed each generated rule: how similar the names are,
+whether the data types are compatible and how the classifications agree, from the factor that
+contributed most to the match score.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return mappings.ShowMapping(args[0])
+		explain, _ := cmd.Flags().GetBool("explain")
+		return mappings.ShowMapping(args[0], explain)
 	},
 }
 
@@ -49,6 +56,18 @@ Examples:
   # Add table mapping with custom name only (description auto-generated)
   redb mappings add --scope table --source mydb.users --target targetdb.profiles --name user-profile-mapping
   
+  # Add table mapping generating only the rules of close column matches
+  redb mappings add --scope table --source mydb.users --target targetdb.profiles --match-profile strict
+  
+  # Add table mapping generating rules for looser matches than its match profile allows
+  redb mappings add --scope table --source mydb.users --target targetdb.profiles --min-rule-score 0.35 --name-weight 0.6
+  
+  # Add table mapping writing only the orders of European customers
+  redb mappings add --scope table --source mydb.orders --target eudb.orders --filter "region = 'EU' AND status <> 'cancelled'"
+  
+  # Add database mapping pairing orders with sales_orders, and never customers with archive_customers
+  redb mappings add --scope database --source mydb --target targetdb --pin orders:sales_orders --never customers:archive_customers
+  
   # Add table-to-MCP resource mapping
   redb mappings add --scope table --source mydb.users --target mcp://users_resource
   
@@ -61,9 +80,22 @@ Examples:
 		name, _ := cmd.Flags().GetString("name")
 		description, _ := cmd.Flags().GetString("description")
 		policyID, _ := cmd.Flags().GetString("policy-id")
+		matchProfile, _ := cmd.Flags().GetString("match-profile")
+		rowFilter, _ := cmd.Flags().GetString("filter")
+		pinValues, _ := cmd.Flags().GetStringArray("pin")
+		neverValues, _ := cmd.Flags().GetStringArray("never")
 		clean, _ := cmd.Flags().GetBool("clean")
 
-		return mappings.AddMapping(scope, source, target, name, description, policyID, clean)
+		tablePins, err := mappings.ParseTablePins(pinValues, false)
+		if err != nil {
+			return err
+		}
+		neverPins, err := mappings.ParseTablePins(neverValues, true)
+		if err != nil {
+			return err
+		}
+
+		return mappings.AddMapping(scope, source, target, name, description, policyID, matchProfile, rowFilter, append(tablePins, neverPins...), matchOverridesFromFlags(cmd), clean)
 	},
 }
 
@@ -81,9 +113,21 @@ Examples:
   
   # Copy data with custom batch size and parallel workers
   redb mappings copy-data user-mapping --batch-size 2000 --parallel-workers 8
+
+  # Copy with exactly 2 parallel workers, without adjusting them to the throughput observed
+  redb mappings copy-data user-mapping --parallel-workers 2 --adaptive-parallelism=false
   
   # Perform a dry run to validate the mapping without copying data
   redb mappings copy-data user-mapping --dry-run
+
+  # Copy into temporary copies of the target tables and show the rows written
+  redb mappings copy-data user-mapping --sandbox --sample-size 5
+
+  # Copy embeddings into a vector store without validating their nearest neighbors afterwards
+  redb mappings copy-data embeddings-mapping --validate-vectors=false
+
+  # Copy relationships from Neo4j and compare the structure of up to 50000 edges on both sides
+  redb mappings copy-data social-graph-mapping --edge-samples 50000
   
   # Copy data with progress updates
   redb mappings copy-data user-mapping --progress`,
@@ -93,9 +137,16 @@ Examples:
 		batchSize, _ := cmd.Flags().GetInt32("batch-size")
 		parallelWorkers, _ := cmd.Flags().GetInt32("parallel-workers")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sandbox, _ := cmd.Flags().GetBool("sandbox")
+		sampleSize, _ := cmd.Flags().GetInt32("sample-size")
+		validateVectors, _ := cmd.Flags().GetBool("validate-vectors")
+		vectorSamples, _ := cmd.Flags().GetInt32("vector-samples")
+		verifyGraph, _ := cmd.Flags().GetBool("verify-graph")
+		edgeSamples, _ := cmd.Flags().GetInt32("edge-samples")
+		adaptiveParallelism, _ := cmd.Flags().GetBool("adaptive-parallelism")
 		progress, _ := cmd.Flags().GetBool("progress")
 
-		return mappings.CopyMappingData(mappingName, batchSize, parallelWorkers, dryRun, progress)
+		return mappings.CopyMappingData(mappingName, batchSize, parallelWorkers, adaptiveParallelism, dryRun, sandbox, sampleSize, validateVectors, vectorSamples, verifyGraph, edgeSamples, progress)
 	},
 }
 
@@ -103,7 +154,12 @@ Examples:
 var modifyRuleCmd = &cobra.Command{
 	Use:   "modify-rule",
 	Short: "Modify an existing mapping rule",
-	Long: `Modify source column, target column, transformation, or order of a mapping rule.
+	Long: `Modify source column, target column, transformation, row filter, or order of a mapping rule.
+
+Before changing the source, target or transformation, the impact of the change is
+shown: the mappings using the rule, the relationships running them, the downstream
+targets of the rule and the transformation policies it would no longer satisfy.
+The change is then applied after confirmation, or directly with --yes.
 
 Examples:
   # Modify source column
@@ -115,6 +171,15 @@ Examples:
   # Modify transformation
   redb mappings modify-rule --mapping user-mapping --rule name_rule --transformation uppercase
   
+  # Replace the transformation with a chain applied in order
+  redb mappings modify-rule --mapping user-mapping --rule email_rule --chain trim,lowercase,hash_sha256
+  
+  # Map only the rows of US customers with the rule
+  redb mappings modify-rule --mapping orders-mapping --rule price_rule --filter "region = 'US'"
+  
+  # Remove the row filter of the rule
+  redb mappings modify-rule --mapping orders-mapping --rule price_rule --filter ""
+  
   # Modify order
   redb mappings modify-rule --mapping user-mapping --rule name_rule --order 5
   
@@ -126,9 +191,19 @@ Examples:
 		source, _ := cmd.Flags().GetString("source")
 		target, _ := cmd.Flags().GetString("target")
 		transformation, _ := cmd.Flags().GetString("transformation")
+		chain, _ := cmd.Flags().GetStringSlice("chain")
 		order, _ := cmd.Flags().GetInt32("order")
+		yes, _ := cmd.Flags().GetBool("yes")
 
-		return mappings.ModifyMappingRule(mappingName, ruleName, source, target, transformation, order)
+		if transformation != "" && len(chain) > 0 {
+			return fmt.Errorf("use either --transformation or --chain, not both")
+		}
+		var rowFilter *string
+		if cmd.Flags().Changed("filter") {
+			filter, _ := cmd.Flags().GetString("filter")
+			rowFilter = &filter
+		}
+		return mappings.ModifyMappingRule(mappingName, ruleName, source, target, transformation, chain, rowFilter, order, yes)
 	},
 }
 
@@ -138,6 +213,15 @@ var addRuleCmd = &cobra.Command{
 	Short: "Add a new mapping rule to a mapping",
 	Long: `Create a new mapping rule and attach it to a mapping.
 
+With --filter, the rule maps only the source rows matching the condition. When rules of a
+table have filters, each source row is written only if it matches at least one of them, so
+rules with different filters can route the rows of one table to different targets.
+
+Several comma-separated sources make a many-to-one rule, whose transformation merges their
+values (concat); several targets make a one-to-many rule, whose transformation splits the
+value (split) or, without one, writes it to every target. --options sets the options of the
+transformation as JSON, e.g. the separator of concat and split.
+
 Examples:
   # Add a rule with direct mapping
   redb mappings add-rule --mapping user-mapping --rule user_id_rule --source sourcedb.users.user_id --target targetdb.profiles.profile_id --transformation direct_mapping
@@ -145,17 +229,40 @@ Examples:
   # Add a rule with transformation
   redb mappings add-rule --mapping user-mapping --rule name_rule --source sourcedb.users.name --target targetdb.profiles.full_name --transformation uppercase
   
+  # Add a rule applying a chain of transformations in order
+  redb mappings add-rule --mapping user-mapping --rule email_rule --source sourcedb.users.email --target targetdb.profiles.email_hash --chain trim,lowercase,hash_sha256
+  
+  # Add rules routing the prices of European and US orders to different columns
+  redb mappings add-rule --mapping orders-mapping --rule eu_price_rule --source sourcedb.orders.price --target targetdb.orders.price_eur --filter "region = 'EU'"
+  redb mappings add-rule --mapping orders-mapping --rule us_price_rule --source sourcedb.orders.price --target targetdb.orders.price_usd --filter "region = 'US'"
+  
+  # Join first and last name into a full name, and split an address into street and city
+  redb mappings add-rule --mapping user-mapping --rule full_name_rule --source sourcedb.users.first_name,sourcedb.users.last_name --target targetdb.profiles.full_name --transformation concat
+  redb mappings add-rule --mapping user-mapping --rule address_rule --source sourcedb.users.address --target targetdb.profiles.street,targetdb.profiles.city --transformation split --options '{"separator": ", "}'
+  
+  # Map legacy country codes to ISO codes through a lookup table
+  redb mappings add-rule --mapping customer-mapping --rule country_rule --source sourcedb.customers.country --target targetdb.customers.country_code --transformation lookup --options '{"table": "country_codes"}'
+  
   # Add a rule with specific order
   redb mappings add-rule --mapping user-mapping --rule email_rule --source sourcedb.users.email --target targetdb.profiles.email --transformation lowercase --order 2`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		mappingName, _ := cmd.Flags().GetString("mapping")
 		ruleName, _ := cmd.Flags().GetString("rule")
-		source, _ := cmd.Flags().GetString("source")
-		target, _ := cmd.Flags().GetString("target")
+		sources, _ := cmd.Flags().GetStringSlice("source")
+		targets, _ := cmd.Flags().GetStringSlice("target")
 		transformation, _ := cmd.Flags().GetString("transformation")
+		chain, _ := cmd.Flags().GetStringSlice("chain")
+		options, _ := cmd.Flags().GetString("options")
+		rowFilter, _ := cmd.Flags().GetString("filter")
 		order, _ := cmd.Flags().GetInt32("order")
 
-		return mappings.AddMappingRule(mappingName, ruleName, source, target, transformation, order)
+		if len(chain) > 0 {
+			if cmd.Flags().Changed("transformation") {
+				return fmt.Errorf("use either --transformation or --chain, not both")
+			}
+			transformation = ""
+		}
+		return mappings.AddMappingRule(mappingName, ruleName, sources, targets, transformation, chain, options, rowFilter, order)
 	},
 }
 
@@ -165,6 +272,9 @@ var removeRuleCmd = &cobra.Command{
 	Short: "Remove a mapping rule from a mapping",
 	Long: `Detach and optionally delete a mapping rule from a mapping.
 
+With --delete, the impact of deleting the rule is shown and the deletion is
+confirmed first, unless --yes is given.
+
 Examples:
   # Remove a rule from a mapping (detach only)
   redb mappings remove-rule --mapping user-mapping --rule email_rule
@@ -175,8 +285,9 @@ Examples:
 		mappingName, _ := cmd.Flags().GetString("mapping")
 		ruleName, _ := cmd.Flags().GetString("rule")
 		deleteRule, _ := cmd.Flags().GetBool("delete")
+		yes, _ := cmd.Flags().GetBool("yes")
 
-		return mappings.RemoveMappingRule(mappingName, ruleName, deleteRule)
+		return mappings.RemoveMappingRule(mappingName, ruleName, deleteRule, yes)
 	},
 }
 
@@ -202,6 +313,314 @@ Examples:
 	},
 }
 
+// setFilterCmd represents the set-filter command
+var setFilterCmd = &cobra.Command{
+	Use:   "set-filter [mapping-name] [expression]",
+	Short: "Set the row filter of a mapping",
+	Long: `Set the condition source rows must match to be written to the target by a mapping,
+when copying data and during replication. The filter applies to every source table of the
+mapping; rules can further restrict the rows they map with add-rule --filter.
+
+Filters are SQL-like conditions on source columns: comparisons (=, <>, <, <=, >, >=),
+IN, LIKE, IS [NOT] NULL, combined with AND, OR, NOT and parentheses. Comparisons with
+NULL or missing columns are false.
+
+Replications that are running pick up the new filter when they are restarted.
+
+Examples:
+  # Replicate only the orders of European customers
+  redb mappings set-filter orders-mapping "region = 'EU'"
+  
+  # Skip test accounts and small orders
+  redb mappings set-filter orders-mapping "email NOT LIKE '%@example.com' AND total >= 10"
+  
+  # Remove the filter
+  redb mappings set-filter orders-mapping --clear`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clear, _ := cmd.Flags().GetBool("clear")
+		if clear == (len(args) == 2) {
+			return fmt.Errorf("provide either a filter expression or --clear")
+		}
+		rowFilter := ""
+		if len(args) == 2 {
+			rowFilter = args[1]
+		}
+		return mappings.SetMappingRowFilter(args[0], rowFilter)
+	},
+}
+
+// pinTablesCmd represents the pin command
+var pinTablesCmd = &cobra.Command{
+	Use:   "pin [mapping-name] [source-table] [target-table]",
+	Short: "Pin a table pair of a database mapping for the matcher",
+	Long: `Pin a source table of a database mapping to a target table: the matcher pairs them whatever
+the score of their match, and pairs the other tables around them. With --never, the matcher
+never pairs them and matches the source table with the other target tables.
+
+A source or a target table is pinned to match a single table; a new pin replaces the previous
+one. Pins are kept with the mapping, the rules change when the mapping is rematched.
+
+Examples:
+  # Always pair orders with sales_orders
+  redb mappings pin shop-to-warehouse orders sales_orders
+  
+  # Never pair customers with archive_customers
+  redb mappings pin shop-to-warehouse customers archive_customers --never
+  
+  # Apply the pins
+  redb mappings rematch shop-to-warehouse`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		never, _ := cmd.Flags().GetBool("never")
+		return mappings.PinTables(args[0], args[1], args[2], never)
+	},
+}
+
+// unpinTablesCmd represents the unpin command
+var unpinTablesCmd = &cobra.Command{
+	Use:   "unpin [mapping-name] [source-table] [target-table]",
+	Short: "Remove table pins of a database mapping",
+	Long: `Remove the pins of a source table of a database mapping, or only its pin to a target table.
+The rules change when the mapping is rematched.
+
+Examples:
+  # Remove the pins of orders
+  redb mappings unpin shop-to-warehouse orders
+  
+  # Remove all the pins
+  redb mappings unpin shop-to-warehouse --all`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			if len(args) > 1 {
+				return fmt.Errorf("--all removes every pin, do not name tables")
+			}
+			return mappings.ClearTablePins(args[0])
+		}
+		if len(args) < 2 {
+			return fmt.Errorf("provide a source table or --all")
+		}
+		targetTable := ""
+		if len(args) == 3 {
+			targetTable = args[2]
+		}
+		return mappings.UnpinTables(args[0], args[1], targetTable)
+	},
+}
+
+// rematchMappingCmd represents the rematch command
+var rematchMappingCmd = &cobra.Command{
+	Use:   "rematch [mapping-name]",
+	Short: "Re-run the matcher of a database mapping",
+	Long: `Re-run the matcher of a database mapping with its table pins and the current schemas of its
+databases. The rules the matcher generated are replaced; rules added with add-rule are kept, and
+no rule is generated for the target columns they write.
+
+Examples:
+  # Rematch after pinning tables
+  redb mappings rematch shop-to-warehouse
+  
+  # Rematch with another match profile, without confirmation
+  redb mappings rematch shop-to-warehouse --match-profile strict --yes
+  
+  # Rematch generating rules for matches scoring 0.4 or more
+  redb mappings rematch shop-to-warehouse --min-rule-score 0.4`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		matchProfile, _ := cmd.Flags().GetString("match-profile")
+		yes, _ := cmd.Flags().GetBool("yes")
+		return mappings.RematchMapping(args[0], matchProfile, matchOverridesFromFlags(cmd), yes)
+	},
+}
+
+// matchReportCmd represents the match-report command
+var matchReportCmd = &cobra.Command{
+	Use:   "match-report [mapping-name]",
+	Short: "Show how the matcher generated the rules of a mapping",
+	Long: `Show the match report of the last run of the matcher generating the rules of a mapping, when it
+was added or rematched: the options of the matcher, the number of column matches of each outcome,
+and the column matches that got no rule with the reason, such as a score below the minimum rule
+score or a transformation policy refusing the rule.
+
+Examples:
+  # Show why columns of a mapping got no rule
+  redb mappings match-report users-to-profiles
+  
+  # Show all column matches, with the rules they got
+  redb mappings match-report users-to-profiles --all`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		return mappings.ShowMatchReport(args[0], all)
+	},
+}
+
+// exportMappingCmd represents the export command
+var exportMappingCmd = &cobra.Command{
+	Use:   "export [mapping-name]",
+	Short: "Export a mapping and its rules as a YAML or JSON document",
+	Long: `Export a table or database mapping and its rules, in order, as a mapping document that can be
+kept in git and imported in other workspaces. Databases are referenced by name rather than by ID,
+and the columns of rules as database.table.column. Policies, cardinalities and match scores are
+not exported.
+
+Examples:
+  # Write the mapping document to the standard output
+  redb mappings export users-to-profiles
+
+  # Write it as JSON to a file
+  redb mappings export users-to-profiles --format json --output users-to-profiles.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		return mappings.ExportMapping(args[0], format, output)
+	},
+}
+
+// importMappingCmd represents the import command
+var importMappingCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Create a mapping and its rules from a YAML or JSON document",
+	Long: `Create a mapping and its rules from a mapping document exported by 'redb mappings export'. The
+matcher does not run: the rules are added as the document defines them, and rematching keeps them.
+Rules whose names other rules of the workspace use are renamed, prefixed with the mapping name.
+The mapping is only created, or replaced, once every rule is added; a rule that cannot be added
+fails the import and leaves the workspace as it was.
+
+Examples:
+  # Import a mapping document
+  redb mappings import users-to-profiles.yaml
+
+  # Import it in a workspace whose databases are named differently, under another name
+  redb mappings import users-to-profiles.yaml --name users-to-profiles-staging --database shop=shop_staging
+
+  # Replace the mapping of the same name, with its rules
+  redb mappings import users-to-profiles.yaml --replace`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		databases, _ := cmd.Flags().GetStringToString("database")
+		replace, _ := cmd.Flags().GetBool("replace")
+		return mappings.ImportMapping(args[0], name, databases, replace)
+	},
+}
+
+// matchOverrideFlags are the flags overriding options of the match profile for a single match
+var matchOverrideFlags = map[string]string{
+	"name-similarity-threshold": "Override the name similarity a column match needs, between 0 and 1",
+	"poor-match-threshold":      "Override the score below which a match is poor, between 0 and 1",
+	"name-weight":               "Override the weight of name similarity",
+	"type-weight":               "Override the weight of data type compatibility",
+	"classification-weight":     "Override the weight of data classification",
+	"privileged-data-weight":    "Override the weight of privileged data detection",
+	"table-structure-weight":    "Override the weight of table structure similarity",
+	"min-rule-score":            "Override the score a column match needs for a rule to be generated, between 0 and 1",
+}
+
+// addMatchOverrideFlags adds the flags overriding options of the match profile to a command
+func addMatchOverrideFlags(cmd *cobra.Command) {
+	for flag, usage := range matchOverrideFlags {
+		cmd.Flags().Float64(flag, 0, usage)
+	}
+	cmd.Flags().Bool("cross-table", false, "Override whether columns match columns of other tables")
+}
+
+// matchOverridesFromFlags returns the options of the match profile the flags override, or nil
+func matchOverridesFromFlags(cmd *cobra.Command) *mappings.MatchOverrides {
+	var overrides mappings.MatchOverrides
+	changed := false
+	for flag, option := range map[string]**float64{
+		"name-similarity-threshold": &overrides.NameSimilarityThreshold,
+		"poor-match-threshold":      &overrides.PoorMatchThreshold,
+		"name-weight":               &overrides.NameWeight,
+		"type-weight":               &overrides.TypeWeight,
+		"classification-weight":     &overrides.ClassificationWeight,
+		"privileged-data-weight":    &overrides.PrivilegedDataWeight,
+		"table-structure-weight":    &overrides.TableStructureWeight,
+		"min-rule-score":            &overrides.MinRuleScore,
+	} {
+		if cmd.Flags().Changed(flag) {
+			value, _ := cmd.Flags().GetFloat64(flag)
+			*option = &value
+			changed = true
+		}
+	}
+	if cmd.Flags().Changed("cross-table") {
+		crossTable, _ := cmd.Flags().GetBool("cross-table")
+		overrides.EnableCrossTableMatching = &crossTable
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return &overrides
+}
+
+// refreshSuggestionsCmd represents the refresh-suggestions command
+var refreshSuggestionsCmd = &cobra.Command{
+	Use:   "refresh-suggestions [mapping-name]",
+	Short: "Suggest rule changes after the schemas of a mapping changed",
+	Long: `Re-run the matcher of a table or database mapping on the current schemas of its databases and
+compare the result with the rules of the mapping. Rules mapping columns or tables that no longer
+exist are suggested for removal; matches of columns no rule maps, such as added columns, are
+suggested as new rules. The mapping is left as it is unless --apply is given.
+
+Examples:
+  # Show the suggested changes after a schema change
+  redb mappings refresh-suggestions users-to-people
+  
+  # Apply the suggested changes
+  redb mappings refresh-suggestions users-to-people --apply`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		matchProfile, _ := cmd.Flags().GetString("match-profile")
+		apply, _ := cmd.Flags().GetBool("apply")
+		return mappings.RefreshMappingSuggestions(args[0], matchProfile, apply)
+	},
+}
+
+// listBrokenRulesCmd represents the list-broken-rules command
+var listBrokenRulesCmd = &cobra.Command{
+	Use:   "list-broken-rules",
+	Short: "List mapping rules referencing objects that no longer exist",
+	Long: `List the mapping rules of the workspace that reference tables or columns a schema change
+dropped. Broken rules are kept with their mappings but skipped by data copies, relationships and
+transformations until they are repaired.
+
+Examples:
+  redb mappings list-broken-rules`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mappings.ListBrokenMappingRules()
+	},
+}
+
+// repairRuleCmd represents the repair-rule command
+var repairRuleCmd = &cobra.Command{
+	Use:   "repair-rule [rule-name]",
+	Short: "Suggest the current objects to re-point a broken mapping rule to",
+	Long: `Match each missing object of a broken mapping rule against the current schema of its database
+and suggest the closest current object, such as the new name of a renamed column. The rule is
+left as it is unless --apply is given; then it is re-pointed to the suggestions scoring at least
+the minimum rule score of the match profile.
+
+Examples:
+  # Show the suggested repairs
+  redb mappings repair-rule users_email_to_people_email
+  
+  # Re-point the rule
+  redb mappings repair-rule users_email_to_people_email --apply`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		matchProfile, _ := cmd.Flags().GetString("match-profile")
+		apply, _ := cmd.Flags().GetBool("apply")
+		return mappings.RepairMappingRule(args[0], matchProfile, apply)
+	},
+}
+
 // listRulesCmd represents the list-rules command
 var listRulesCmd = &cobra.Command{
 	Use:   "list-rules",
@@ -223,22 +642,96 @@ var validateMappingCmd = &cobra.Command{
 	Use:   "validate [mapping-name]",
 	Short: "Validate a mapping",
 	Long: `Validate that a mapping is correctly configured with all required target columns mapped,
-valid transformations, and compatible data types.
+valid transformations, and compatible data types. Nothing is run: the report lists the
+issues found for each rule and for the mapping as a whole, and the command exits with an
+error when any of them is an error.
 
 Examples:
   # Validate a mapping
   redb mappings validate user-profile-mapping
   
   # The validation checks:
-  # - All non-nullable target columns have mapping rules
-  # - Transformations are valid with correct inputs/outputs
-  # - Data types are compatible between source and target`,
+  # - The tables and columns of every rule still exist
+  # - Transformations and transformation chains resolve, and support the cardinality of the rule
+  # - The cardinality of every rule matches its sources and targets
+  # - Data types are compatible between source and target, or the output of the transformation and target
+  # - No target column is written by several rules, and all non-nullable target columns have mapping rules`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return mappings.ValidateMapping(args[0])
 	},
 }
 
+// planRunCmd represents the plan command
+var planRunCmd = &cobra.Command{
+	Use:   "plan [mapping-name]",
+	Short: "Show the stages a copy of a mapping would run",
+	Long: `Show the execution plan of a copy of a mapping without running it: its stages in the order
+they run, the tables and adapters each copy stage reads and writes, the rows and batches estimated
+from the source row counts, and the nodes of the mesh the rows pass through. Warnings report what
+would make the copy fail, such as databases connected to another node.
+
+Examples:
+  # Plan a copy with default settings
+  redb mappings plan user-mapping
+
+  # Plan a sandbox copy with larger batches
+  redb mappings plan user-mapping --sandbox --batch-size 5000`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batchSize, _ := cmd.Flags().GetInt32("batch-size")
+		parallelWorkers, _ := cmd.Flags().GetInt32("parallel-workers")
+		sandbox, _ := cmd.Flags().GetBool("sandbox")
+		validateVectors, _ := cmd.Flags().GetBool("validate-vectors")
+		verifyGraph, _ := cmd.Flags().GetBool("verify-graph")
+		return mappings.PlanRun(args[0], batchSize, parallelWorkers, sandbox, validateVectors, verifyGraph)
+	},
+}
+
+// previewMappingCmd represents the preview command
+var previewMappingCmd = &cobra.Command{
+	Use:   "preview [mapping-name]",
+	Short: "Show sample rows transformed by a mapping",
+	Long: `Read sample rows of the source tables of a mapping and transform them with its rules as a
+copy would, without writing anything. Each source row is shown next to the target row it would
+become, with the rules failing on it. Rows the row filters exclude are marked filtered out.
+
+Examples:
+  # Preview 10 rows of each source table
+  redb mappings preview user-mapping
+
+  # Preview more rows
+  redb mappings preview user-mapping --rows 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rows, _ := cmd.Flags().GetInt32("rows")
+		return mappings.PreviewMapping(args[0], rows)
+	},
+}
+
+// indexRecommendationsCmd represents the index-recommendations command
+var indexRecommendationsCmd = &cobra.Command{
+	Use:   "index-recommendations [mapping-name]",
+	Short: "Recommend indexes for the lookups of a mapping",
+	Long: `Recommend the indexes that are missing on the source and target tables of a mapping:
+target columns mapped from the source primary key, used to apply CDC updates and deletes
+and upserts, and source columns tested by mapping filters.
+
+Recommended indexes are only created when approved by name with --create.
+
+Examples:
+  # Show the recommended indexes
+  redb mappings index-recommendations orders-mapping
+
+  # Create approved indexes
+  redb mappings index-recommendations orders-mapping --create idx_orders_order_id`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		create, _ := cmd.Flags().GetStringSlice("create")
+		return mappings.IndexRecommendations(args[0], create)
+	},
+}
+
 // addStreamToTableCmd represents the add-stream-to-table command
 var addStreamToTableCmd = &cobra.Command{
 	Use:   "add-stream-to-table",
@@ -362,6 +855,147 @@ Examples:
 	},
 }
 
+// matchProfilesCmd represents the match-profiles command
+var matchProfilesCmd = &cobra.Command{
+	Use:   "match-profiles",
+	Short: "Manage the match profiles tuning the rules generated for new mappings",
+	Long: `Manage the match profiles of the workspace. A match profile holds the options of the
+column matcher and the minimum score a column match needs for a rule to be generated.
+
+The built-in profiles are table, database, strict, lenient and cross-table. Mappings created
+without --match-profile use the default profile of the workspace, or the built-in profile of
+their scope when it has none.`,
+}
+
+// listMatchProfilesCmd represents the match-profiles list command
+var listMatchProfilesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the match profiles of the workspace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mappings.ListMatchProfiles()
+	},
+}
+
+// setMatchProfileCmd represents the match-profiles set command
+var setMatchProfileCmd = &cobra.Command{
+	Use:   "set [profile-name]",
+	Short: "Create or replace a match profile",
+	Long: `Create or replace a match profile of the workspace. The options not given are those of the
+--based-on profile, else of the profile being replaced, else of the built-in table profile.
+
+Examples:
+  # Require closer name matches than the built-in strict profile
+  redb mappings match-profiles set strict-names --based-on strict --name-weight 0.7
+
+  # Generate rules for matches scoring 0.6 or more
+  redb mappings match-profiles set picky --min-rule-score 0.6`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var changes mappings.MatchProfileChanges
+		if cmd.Flags().Changed("description") {
+			description, _ := cmd.Flags().GetString("description")
+			changes.ProfileDescription = &description
+		}
+		if cmd.Flags().Changed("based-on") {
+			basedOn, _ := cmd.Flags().GetString("based-on")
+			changes.BasedOn = &basedOn
+		}
+		for flag, option := range map[string]**float64{
+			"name-similarity-threshold": &changes.NameSimilarityThreshold,
+			"poor-match-threshold":      &changes.PoorMatchThreshold,
+			"name-weight":               &changes.NameWeight,
+			"type-weight":               &changes.TypeWeight,
+			"classification-weight":     &changes.ClassificationWeight,
+			"privileged-data-weight":    &changes.PrivilegedDataWeight,
+			"table-structure-weight":    &changes.TableStructureWeight,
+			"min-rule-score":            &changes.MinRuleScore,
+		} {
+			if cmd.Flags().Changed(flag) {
+				value, _ := cmd.Flags().GetFloat64(flag)
+				*option = &value
+			}
+		}
+		if cmd.Flags().Changed("cross-table") {
+			crossTable, _ := cmd.Flags().GetBool("cross-table")
+			changes.EnableCrossTableMatching = &crossTable
+		}
+
+		return mappings.SetMatchProfile(args[0], changes)
+	},
+}
+
+// deleteMatchProfileCmd represents the match-profiles delete command
+var deleteMatchProfileCmd = &cobra.Command{
+	Use:   "delete [profile-name]",
+	Short: "Delete a match profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mappings.DeleteMatchProfile(args[0])
+	},
+}
+
+// defaultMatchProfileCmd represents the match-profiles default command
+var defaultMatchProfileCmd = &cobra.Command{
+	Use:   "default [profile-name]",
+	Short: "Set the match profile new mappings use by default",
+	Long: `Set the match profile new mappings of the workspace use when created without --match-profile.
+Use --clear to use the built-in profile of the mapping scope again.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clearDefault, _ := cmd.Flags().GetBool("clear")
+		if clearDefault == (len(args) == 1) {
+			return fmt.Errorf("give either a profile name or --clear")
+		}
+		profileName := ""
+		if len(args) == 1 {
+			profileName = args[0]
+		}
+
+		return mappings.SetDefaultMatchProfile(profileName)
+	},
+}
+
+// matchFeedbackCmd represents the match-feedback command
+var matchFeedbackCmd = &cobra.Command{
+	Use:   "match-feedback",
+	Short: "Manage what the matcher learned from corrections of generated rules",
+	Long: `Manage the match feedback of the workspace. Removing a rule generated for a new mapping, or
+re-pointing its source or target column with modify-rule, rejects the column match it was
+generated for, and the columns it is re-pointed to are accepted. The matcher adds 0.15 per
+acceptance and removes 0.15 per rejection from the scores of later matches of columns of the same
+names, up to 0.45 either way.`,
+}
+
+// listMatchFeedbackCmd represents the match-feedback list command
+var listMatchFeedbackCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the corrected column matches of the workspace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mappings.ListMatchFeedback()
+	},
+}
+
+// resetMatchFeedbackCmd represents the match-feedback reset command
+var resetMatchFeedbackCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Forget the match feedback of the workspace",
+	Long: `Forget the match feedback of the workspace, or only that on the matches of a source and/or a
+target column.
+
+Examples:
+  # Forget everything the matcher learned
+  redb mappings match-feedback reset
+
+  # Forget the corrections of the matches of the email column
+  redb mappings match-feedback reset --source-column email`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sourceColumn, _ := cmd.Flags().GetString("source-column")
+		targetColumn, _ := cmd.Flags().GetString("target-column")
+		return mappings.ResetMatchFeedback(sourceColumn, targetColumn)
+	},
+}
+
 func init() {
 	// Add flags to addMappingCmd
 	addMappingCmd.Flags().String("scope", "", "Mapping scope: 'database' or 'table' (required)")
@@ -370,7 +1004,12 @@ func init() {
 	addMappingCmd.Flags().String("name", "", "Mapping name (optional, auto-generated if not provided)")
 	addMappingCmd.Flags().String("description", "", "Mapping description (optional, auto-generated if not provided)")
 	addMappingCmd.Flags().String("policy-id", "", "Policy ID (optional)")
+	addMappingCmd.Flags().String("match-profile", "", "Match profile tuning the generated rules (optional, defaults to the workspace default)")
+	addMappingCmd.Flags().String("filter", "", "Condition source rows must match to be written to the target (optional, e.g. \"region = 'EU'\")")
+	addMappingCmd.Flags().StringArray("pin", nil, "Table pair the matcher of a database mapping always pairs, as source_table:target_table (repeatable)")
+	addMappingCmd.Flags().StringArray("never", nil, "Table pair the matcher of a database mapping never pairs, as source_table:target_table (repeatable)")
 	addMappingCmd.Flags().Bool("clean", false, "Create empty mapping without auto-generating rules (default: false)")
+	addMatchOverrideFlags(addMappingCmd)
 
 	// Mark required flags
 	addMappingCmd.MarkFlagRequired("scope")
@@ -379,26 +1018,55 @@ func init() {
 
 	// Add flags to copyDataCmd
 	copyDataCmd.Flags().Int32("batch-size", 1000, "Number of rows to process in each batch")
-	copyDataCmd.Flags().Int32("parallel-workers", 4, "Number of parallel workers for data copying")
+	copyDataCmd.Flags().Int32("parallel-workers", 4, "Number of parallel workers for data copying, within the bounds set by the admin")
+	copyDataCmd.Flags().Bool("adaptive-parallelism", true, "Adjust the parallel workers to the throughput and errors observed during the copy")
 	copyDataCmd.Flags().Bool("dry-run", false, "Validate mapping and show what would be copied without actually copying data")
+	copyDataCmd.Flags().Bool("sandbox", false, "Copy into temporary copies of the target tables, dropped afterwards, and show the rows written")
+	copyDataCmd.Flags().Int32("sample-size", 10, "Number of rows of each sandbox table to show")
+	copyDataCmd.Flags().Bool("validate-vectors", true, "Search the nearest neighbors of sampled vectors copied into vector stores again, and report their recall")
+	copyDataCmd.Flags().Int32("vector-samples", 20, "Number of vectors of each table the validation samples")
+	copyDataCmd.Flags().Bool("verify-graph", true, "Compare the degree distributions and paths of the relationships copied from graph databases with the source")
+	copyDataCmd.Flags().Int32("edge-samples", 10000, "Number of edges of each relationship the verification samples, up to 100000")
 	copyDataCmd.Flags().Bool("progress", false, "Show detailed progress information during copying")
 
+	// Add flags to planRunCmd
+	planRunCmd.Flags().Int32("batch-size", 1000, "Number of rows to process in each batch")
+	planRunCmd.Flags().Int32("parallel-workers", 4, "Number of parallel workers the copy starts with")
+	planRunCmd.Flags().Bool("sandbox", false, "Plan a copy into temporary copies of the target tables")
+	planRunCmd.Flags().Bool("validate-vectors", true, "Plan the validation of the vectors copied into vector stores")
+	planRunCmd.Flags().Bool("verify-graph", true, "Plan the verification of the relationships copied from graph databases")
+
+	// Add flags to previewMappingCmd
+	previewMappingCmd.Flags().Int32("rows", 10, "Number of sample rows of each source table (at most 100)")
+
+	// Add flags to showMappingCmd
+	showMappingCmd.Flags().Bool("explain", false, "Show why the matcher proposed each generated rule")
+
+	// Add flags to indexRecommendationsCmd
+	indexRecommendationsCmd.Flags().StringSlice("create", nil, "Recommended indexes to create, by name")
+
 	// Add flags to modifyRuleCmd
 	modifyRuleCmd.Flags().String("mapping", "", "Mapping name (required)")
 	modifyRuleCmd.Flags().String("rule", "", "Rule name (required)")
 	modifyRuleCmd.Flags().String("source", "", "Source column in format 'database.table.column'")
 	modifyRuleCmd.Flags().String("target", "", "Target column in format 'database.table.column'")
 	modifyRuleCmd.Flags().String("transformation", "", "Transformation name")
+	modifyRuleCmd.Flags().StringSlice("chain", nil, "Transformations to apply in order, replacing the transformation (e.g. trim,lowercase,hash_sha256)")
+	modifyRuleCmd.Flags().String("filter", "", "Condition source rows must match to be mapped by the rule, replacing the current one (\"\" removes it)")
 	modifyRuleCmd.Flags().Int32("order", -1, "Rule order (position in mapping)")
+	modifyRuleCmd.Flags().Bool("yes", false, "Apply the change without confirmation")
 	modifyRuleCmd.MarkFlagRequired("mapping")
 	modifyRuleCmd.MarkFlagRequired("rule")
 
 	// Add flags to addRuleCmd
 	addRuleCmd.Flags().String("mapping", "", "Mapping name (required)")
 	addRuleCmd.Flags().String("rule", "", "Rule name (required)")
-	addRuleCmd.Flags().String("source", "", "Source column in format 'database.table.column' (required)")
-	addRuleCmd.Flags().String("target", "", "Target column in format 'database.table.column' (required)")
+	addRuleCmd.Flags().StringSlice("source", nil, "Source columns in format 'database.table.column', comma-separated for a many-to-one rule (required)")
+	addRuleCmd.Flags().StringSlice("target", nil, "Target columns in format 'database.table.column', comma-separated for a one-to-many rule (required)")
 	addRuleCmd.Flags().String("transformation", "direct_mapping", "Transformation name (default: direct_mapping)")
+	addRuleCmd.Flags().StringSlice("chain", nil, "Transformations to apply in order, instead of --transformation (e.g. trim,lowercase,hash_sha256)")
+	addRuleCmd.Flags().String("options", "", "Transformation options as JSON (optional, e.g. '{\"separator\": \", \"}')")
+	addRuleCmd.Flags().String("filter", "", "Condition source rows must match to be mapped by the rule (optional, e.g. \"region = 'EU'\")")
 	addRuleCmd.Flags().Int32("order", -1, "Rule order (position in mapping, auto-assigned if not specified)")
 	addRuleCmd.MarkFlagRequired("mapping")
 	addRuleCmd.MarkFlagRequired("rule")
@@ -409,12 +1077,35 @@ func init() {
 	removeRuleCmd.Flags().String("mapping", "", "Mapping name (required)")
 	removeRuleCmd.Flags().String("rule", "", "Rule name (required)")
 	removeRuleCmd.Flags().Bool("delete", false, "Delete the rule after detaching (default: false)")
+	removeRuleCmd.Flags().Bool("yes", false, "Delete the rule without confirmation")
 	removeRuleCmd.MarkFlagRequired("mapping")
 	removeRuleCmd.MarkFlagRequired("rule")
 
 	// Add flags to removeMappingCmd
 	removeMappingCmd.Flags().Bool("keep-rules", false, "Keep rules after removing mapping (default: false)")
 
+	// Add flags to setFilterCmd
+	setFilterCmd.Flags().Bool("clear", false, "Remove the row filter of the mapping")
+
+	// Add flags to the table pin commands
+	pinTablesCmd.Flags().Bool("never", false, "Pin the tables never to match")
+	unpinTablesCmd.Flags().Bool("all", false, "Remove all the table pins of the mapping")
+	rematchMappingCmd.Flags().String("match-profile", "", "Match profile of the matcher (optional, defaults to the workspace default)")
+	rematchMappingCmd.Flags().Bool("yes", false, "Replace the generated rules without confirmation")
+	addMatchOverrideFlags(rematchMappingCmd)
+	matchReportCmd.Flags().Bool("all", false, "Show the column matches that got a rule too")
+	exportMappingCmd.Flags().String("format", "yaml", "Format of the mapping document (yaml, json)")
+	exportMappingCmd.Flags().String("output", "", "File to write the mapping document to (default standard output)")
+	importMappingCmd.Flags().String("name", "", "Name of the mapping (optional, defaults to the name of the document)")
+	importMappingCmd.Flags().StringToString("database", nil, "Rename a database of the document as document=workspace (repeatable)")
+	importMappingCmd.Flags().Bool("replace", false, "Replace the mapping of the same name, with its rules, once every rule is added")
+	refreshSuggestionsCmd.Flags().String("match-profile", "", "Match profile of the matcher (optional, defaults to the workspace default)")
+	refreshSuggestionsCmd.Flags().Bool("apply", false, "Apply the suggested rule changes")
+
+	// Add flags to repairRuleCmd
+	repairRuleCmd.Flags().String("match-profile", "", "Match profile of the matcher (optional, defaults to the workspace default)")
+	repairRuleCmd.Flags().Bool("apply", false, "Re-point the rule to the suggested objects")
+
 	// Add flags to listRulesCmd
 	listRulesCmd.Flags().String("mapping", "", "Mapping name (required)")
 	listRulesCmd.MarkFlagRequired("mapping")
@@ -444,6 +1135,34 @@ func init() {
 	addStreamToStreamCmd.MarkFlagRequired("source")
 	addStreamToStreamCmd.MarkFlagRequired("target")
 
+	// Add flags to setMatchProfileCmd
+	setMatchProfileCmd.Flags().String("description", "", "Profile description")
+	setMatchProfileCmd.Flags().String("based-on", "", "Profile to take the options not given from")
+	setMatchProfileCmd.Flags().Float64("name-similarity-threshold", 0, "Name similarity a column match needs, between 0 and 1")
+	setMatchProfileCmd.Flags().Float64("poor-match-threshold", 0, "Score below which a match is poor, between 0 and 1")
+	setMatchProfileCmd.Flags().Float64("name-weight", 0, "Weight of name similarity")
+	setMatchProfileCmd.Flags().Float64("type-weight", 0, "Weight of data type compatibility")
+	setMatchProfileCmd.Flags().Float64("classification-weight", 0, "Weight of data classification")
+	setMatchProfileCmd.Flags().Float64("privileged-data-weight", 0, "Weight of privileged data detection")
+	setMatchProfileCmd.Flags().Float64("table-structure-weight", 0, "Weight of table structure similarity")
+	setMatchProfileCmd.Flags().Bool("cross-table", false, "Match columns with columns of other tables")
+	setMatchProfileCmd.Flags().Float64("min-rule-score", 0, "Score a column match needs for a rule to be generated, between 0 and 1")
+
+	// Add flags to defaultMatchProfileCmd
+	defaultMatchProfileCmd.Flags().Bool("clear", false, "Clear the default match profile")
+
+	matchProfilesCmd.AddCommand(listMatchProfilesCmd)
+	matchProfilesCmd.AddCommand(setMatchProfileCmd)
+	matchProfilesCmd.AddCommand(deleteMatchProfileCmd)
+	matchProfilesCmd.AddCommand(defaultMatchProfileCmd)
+
+	// Add flags to resetMatchFeedbackCmd
+	resetMatchFeedbackCmd.Flags().String("source-column", "", "Only forget the feedback on matches of this source column")
+	resetMatchFeedbackCmd.Flags().String("target-column", "", "Only forget the feedback on matches of this target column")
+
+	matchFeedbackCmd.AddCommand(listMatchFeedbackCmd)
+	matchFeedbackCmd.AddCommand(resetMatchFeedbackCmd)
+
 	// Add subcommands to mappings command
 	mappingsCmd.AddCommand(listMappingsCmd)
 	mappingsCmd.AddCommand(showMappingCmd)
@@ -452,10 +1171,25 @@ func init() {
 	mappingsCmd.AddCommand(addTableToStreamCmd)
 	mappingsCmd.AddCommand(addStreamToStreamCmd)
 	mappingsCmd.AddCommand(copyDataCmd)
+	mappingsCmd.AddCommand(planRunCmd)
+	mappingsCmd.AddCommand(previewMappingCmd)
 	mappingsCmd.AddCommand(validateMappingCmd)
+	mappingsCmd.AddCommand(indexRecommendationsCmd)
 	mappingsCmd.AddCommand(modifyRuleCmd)
 	mappingsCmd.AddCommand(addRuleCmd)
 	mappingsCmd.AddCommand(removeRuleCmd)
 	mappingsCmd.AddCommand(removeMappingCmd)
+	mappingsCmd.AddCommand(setFilterCmd)
+	mappingsCmd.AddCommand(pinTablesCmd)
+	mappingsCmd.AddCommand(unpinTablesCmd)
+	mappingsCmd.AddCommand(rematchMappingCmd)
+	mappingsCmd.AddCommand(refreshSuggestionsCmd)
+	mappingsCmd.AddCommand(matchReportCmd)
+	mappingsCmd.AddCommand(exportMappingCmd)
+	mappingsCmd.AddCommand(importMappingCmd)
+	mappingsCmd.AddCommand(listBrokenRulesCmd)
+	mappingsCmd.AddCommand(repairRuleCmd)
 	mappingsCmd.AddCommand(listRulesCmd)
+	mappingsCmd.AddCommand(matchProfilesCmd)
+	mappingsCmd.AddCommand(matchFeedbackCmd)
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/proxies"
+	"github.com/spf13/cobra"
+)
+
+// proxiesCmd represents the proxies command
+var proxiesCmd = &cobra.Command{
+	Use:   "proxies",
+	Short: "Manage query proxies for gradual cutovers",
+	Long: `Manage query proxies, which route the statements of applications between the
+source and target database of a relationship during a gradual cutover.
+
+Applications connect to a proxy as they would to the database (PostgreSQL or
+MySQL wire protocol). The proxy runs each statement on one of the databases:
+  - tables routed to source or target always run there
+  - otherwise, the reads and writes of a share of the sessions run on the target
+
+Routes can be changed while applications are connected.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// addProxyCmd represents the add proxy command
+var addProxyCmd = &cobra.Command{
+	Use:   "add [proxy-name] --relationship [relationship-name] --port [port] --username [username]",
+	Short: "Add a new query proxy",
+	Long: `Add a stopped query proxy between the source and target database of a
+relationship. Both databases must speak the same wire protocol and be connected
+by the same node, which runs the proxy. The password is prompted for when
+--password is not given.
+
+Examples:
+  # Add a proxy sending all statements to the source database
+  redb proxies add orders-proxy --relationship orders-replication --port 6432 --username orders_app
+
+  # Send the reads of a quarter of the sessions to the target, keeping the audit log on the source
+  redb proxies add orders-proxy --relationship orders-replication --port 6432 --username orders_app \
+    --reads 25 --table audit_log=source`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var options proxies.AddProxyOptions
+		options.Description, _ = cmd.Flags().GetString("description")
+		options.Relationship, _ = cmd.Flags().GetString("relationship")
+		options.Port, _ = cmd.Flags().GetInt32("port")
+		options.Username, _ = cmd.Flags().GetString("username")
+		options.Password, _ = cmd.Flags().GetString("password")
+		options.ReadPercent, _ = cmd.Flags().GetInt32("reads")
+		options.WritePercent, _ = cmd.Flags().GetInt32("writes")
+		tables, _ := cmd.Flags().GetStringArray("table")
+
+		if options.Relationship == "" || options.Port == 0 || options.Username == "" {
+			return fmt.Errorf("--relationship, --port and --username flags are required")
+		}
+		tableRoutes, err := proxies.ParseTableRoutes(tables)
+		if err != nil {
+			return err
+		}
+		options.TableRoutes = tableRoutes
+
+		return proxies.AddProxy(args[0], options)
+	},
+}
+
+// listProxiesCmd represents the list proxies command
+var listProxiesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all query proxies",
+	Long:  `Display a formatted list of all query proxies in the active workspace with their routes and status.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxies.ListProxies()
+	},
+}
+
+// showProxyCmd represents the show proxy command
+var showProxyCmd = &cobra.Command{
+	Use:   "show [proxy-name]",
+	Short: "Show query proxy details",
+	Long:  `Display detailed information about a query proxy, including its routes and, when it runs, its sessions and statements.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxies.ShowProxy(args[0])
+	},
+}
+
+// startProxyCmd represents the start proxy command
+var startProxyCmd = &cobra.Command{
+	Use:   "start [proxy-name]",
+	Short: "Start a query proxy",
+	Long: `Start a query proxy on its node. The proxy is started again when the node
+restarts, until it is stopped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxies.StartProxy(args[0])
+	},
+}
+
+// stopProxyCmd represents the stop proxy command
+var stopProxyCmd = &cobra.Command{
+	Use:   "stop [proxy-name]",
+	Short: "Stop a query proxy",
+	Long:  `Stop a query proxy, closing the sessions of the applications connected to it.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxies.StopProxy(args[0])
+	},
+}
+
+// routeProxyCmd represents the route proxy command
+var routeProxyCmd = &cobra.Command{
+	Use:   "route [proxy-name]",
+	Short: "Change the routes of a query proxy",
+	Long: `Change where a query proxy runs statements. A running proxy applies the routes
+without closing its sessions, from their next statement outside a transaction.
+
+Examples:
+  # Run the reads of all sessions and the writes of 10% of them on the target
+  redb proxies route orders-proxy --reads 100 --writes 10
+
+  # Pin the invoices table to the target, and remove the route of the audit log
+  redb proxies route orders-proxy --table invoices=target --table audit_log=
+
+  # Remove all table routes
+  redb proxies route orders-proxy --clear-tables`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var options proxies.RouteOptions
+		if cmd.Flags().Changed("reads") {
+			reads, _ := cmd.Flags().GetInt32("reads")
+			options.ReadPercent = &reads
+		}
+		if cmd.Flags().Changed("writes") {
+			writes, _ := cmd.Flags().GetInt32("writes")
+			options.WritePercent = &writes
+		}
+		options.ClearTableRoutes, _ = cmd.Flags().GetBool("clear-tables")
+		tables, _ := cmd.Flags().GetStringArray("table")
+
+		tableRoutes, err := proxies.ParseTableRoutes(tables)
+		if err != nil {
+			return err
+		}
+		options.TableRoutes = tableRoutes
+
+		return proxies.RouteProxy(args[0], options)
+	},
+}
+
+// deleteProxyCmd represents the delete proxy command
+var deleteProxyCmd = &cobra.Command{
+	Use:   "delete [proxy-name]",
+	Short: "Delete a query proxy",
+	Long:  `Delete a query proxy that is not running. Running proxies must be stopped first.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return proxies.DeleteProxy(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(proxiesCmd)
+
+	// Add subcommands
+	proxiesCmd.AddCommand(addProxyCmd)
+	proxiesCmd.AddCommand(listProxiesCmd)
+	proxiesCmd.AddCommand(showProxyCmd)
+	proxiesCmd.AddCommand(startProxyCmd)
+	proxiesCmd.AddCommand(stopProxyCmd)
+	proxiesCmd.AddCommand(routeProxyCmd)
+	proxiesCmd.AddCommand(deleteProxyCmd)
+
+	// Add flags to addProxyCmd
+	addProxyCmd.Flags().String("description", "", "Description of the query proxy")
+	addProxyCmd.Flags().String("relationship", "", "Relationship replicating the source database to the target database (required)")
+	addProxyCmd.Flags().Int32("port", 0, "Port the proxy listens on (required)")
+	addProxyCmd.Flags().String("username", "", "Username applications connect with (required)")
+	addProxyCmd.Flags().String("password", "", "Password applications connect with; prompted for if not set")
+	addProxyCmd.Flags().Int32("reads", 0, "Percentage of sessions whose reads run on the target database")
+	addProxyCmd.Flags().Int32("writes", 0, "Percentage of sessions whose writes run on the target database")
+	addProxyCmd.Flags().StringArray("table", nil, "Table routed to a database, as table=source or table=target (repeatable)")
+	addProxyCmd.MarkFlagRequired("relationship")
+	addProxyCmd.MarkFlagRequired("port")
+	addProxyCmd.MarkFlagRequired("username")
+
+	// Add flags to routeProxyCmd
+	routeProxyCmd.Flags().Int32("reads", 0, "Percentage of sessions whose reads run on the target database")
+	routeProxyCmd.Flags().Int32("writes", 0, "Percentage of sessions whose writes run on the target database")
+	routeProxyCmd.Flags().StringArray("table", nil, "Table routed to a database, as table=source or table=target; table= removes its route (repeatable)")
+	routeProxyCmd.Flags().Bool("clear-tables", false, "Remove the current table routes before applying --table")
+}
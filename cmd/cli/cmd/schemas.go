@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/schemas"
+	"github.com/spf13/cobra"
+)
+
+// schemasCmd represents the schemas command
+var schemasCmd = &cobra.Command{
+	Use:   "schemas",
+	Short: "Work with the schemas of many databases at once",
+	Long:  `Work with the schemas of many databases at once, e.g. convert them to another database type.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// convertSchemasCmd represents the convert schemas command
+var convertSchemasCmd = &cobra.Command{
+	Use:   "convert [database-name]... --to [database-type]",
+	Short: "Convert the schemas of databases to another database type",
+	Long: `Convert the current schemas of databases to another database type without
+deploying them. Conversions run in parallel; each produces the DDL creating the
+converted schema, the converted unified model and a report of its warnings.
+
+Results are written to --output-dir as <database>/<type>.sql, .schema.json and
+.report.json, with summary.json listing all conversions, and/or kept as export
+artifacts in the blob store with --store-artifacts. The command ends with a
+summary of the lossy conversions and fails if any conversion failed.
+
+Select databases by name, or with --all-databases, narrowed down by their type
+with --from and by their labels with --selector (see "databases label").
+
+Examples:
+  # Convert all PostgreSQL databases of the analytics team to Snowflake
+  redb schemas convert --from postgres --to snowflake --all-databases \
+    --selector team=analytics --output-dir ./snowflake
+
+  # Convert two databases to MySQL, keeping the results in the blob store
+  redb schemas convert orders_db users_db --to mysql --store-artifacts`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var options schemas.ConvertOptions
+		options.Databases = args
+		options.From, _ = cmd.Flags().GetString("from")
+		options.To, _ = cmd.Flags().GetString("to")
+		options.AllDatabases, _ = cmd.Flags().GetBool("all-databases")
+		options.Selector, _ = cmd.Flags().GetString("selector")
+		options.OutputDir, _ = cmd.Flags().GetString("output-dir")
+		options.StoreArtifacts, _ = cmd.Flags().GetBool("store-artifacts")
+		options.Parallel, _ = cmd.Flags().GetInt("parallel")
+		ownerHandling, _ := cmd.Flags().GetString("owner-handling")
+		grantHandling, _ := cmd.Flags().GetString("grant-handling")
+		commentHandling, _ := cmd.Flags().GetString("comment-handling")
+		ownerMappings, _ := cmd.Flags().GetStringSlice("owner-mapping")
+
+		if options.Selector != "" && !options.AllDatabases && len(options.Databases) == 0 {
+			return fmt.Errorf("--selector selects among all databases, pass --all-databases")
+		}
+
+		// Owner, grant and comment handling applied when the schemas are converted
+		options.TransformationOptions = map[string]string{}
+		if ownerHandling != "" {
+			options.TransformationOptions["owner_handling"] = ownerHandling
+		}
+		if grantHandling != "" {
+			options.TransformationOptions["grant_handling"] = grantHandling
+		}
+		if commentHandling != "" {
+			options.TransformationOptions["comment_handling"] = commentHandling
+		}
+		if len(ownerMappings) > 0 {
+			options.TransformationOptions["owner_mappings"] = strings.Join(ownerMappings, ",")
+		}
+
+		return schemas.ConvertSchemas(options)
+	},
+}
+
+func init() {
+	convertSchemasCmd.Flags().String("from", "", "Only convert databases of this type, e.g. postgres")
+	convertSchemasCmd.Flags().String("to", "", "Database type to convert to, e.g. snowflake (required)")
+	convertSchemasCmd.Flags().Bool("all-databases", false, "Convert all databases matching --from and --selector")
+	convertSchemasCmd.Flags().String("selector", "", "Only convert databases whose labels match, e.g. team=analytics,tier!=gold")
+	convertSchemasCmd.Flags().String("output-dir", "", "Directory to write the DDL, converted schemas and reports to")
+	convertSchemasCmd.Flags().Bool("store-artifacts", false, "Keep the DDL and reports as export artifacts in the blob store")
+	convertSchemasCmd.Flags().Int("parallel", 4, "Conversions running at once")
+
+	// Owner, grant and comment handling for schema conversion
+	convertSchemasCmd.Flags().String("owner-handling", "", "Object owners on the target: preserve (default), remap or drop")
+	convertSchemasCmd.Flags().String("grant-handling", "", "Grants on the target: preserve, remap or drop (default)")
+	convertSchemasCmd.Flags().String("comment-handling", "", "Comments on the target: preserve (default) or drop")
+	convertSchemasCmd.Flags().StringSlice("owner-mapping", nil, "Owner or grantee mapping for remap as source=target; an empty target drops it (repeatable)")
+
+	schemasCmd.AddCommand(convertSchemasCmd)
+	rootCmd.AddCommand(schemasCmd)
+}
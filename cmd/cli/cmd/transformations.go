@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/redbco/redb-open/cmd/cli/internal/locale"
 	"github.com/redbco/redb-open/cmd/cli/internal/transformations"
 	"github.com/spf13/cobra"
 )
@@ -39,7 +40,257 @@ Examples:
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
 		if err := transformations.ListTransformations(verbose); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// pluginsCmd represents the transformations plugins command
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Manage transformation plugins",
+	Long: `Register, list and delete user-defined transformations served by external gRPC endpoints.
+
+A plugin endpoint implements the TransformationPlugin service of the transformation API. Once
+registered, a plugin is used by name in mapping rules and transformation chains like a built-in
+transformation.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// listPluginsCmd represents the transformations plugins list command
+var listPluginsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the transformation plugins of the tenant",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.ListPlugins(); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// registerPluginCmd represents the transformations plugins register command
+var registerPluginCmd = &cobra.Command{
+	Use:   "register [name]",
+	Short: "Register a transformation plugin",
+	Long: `Register a transformation served by an external gRPC endpoint.
+
+The type places the plugin in transformation chains like the built-in transformations of the
+type: passthrough (one value to one), merge (several source columns to one, first step only),
+split (one value to several target columns, last step only), generator or null_returning.
+
+Examples:
+  # Register a plugin normalizing phone numbers
+  redb transformations plugins register normalize_phone --endpoint cleansing-plugins:50051
+
+  # Register a plugin merging address columns, with a 5 second deadline per call
+  redb transformations plugins register format_address --endpoint cleansing-plugins:50051 --type merge --timeout 5`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		options := transformations.PluginOptions{}
+		options.Description, _ = cmd.Flags().GetString("description")
+		options.Kind, _ = cmd.Flags().GetString("kind")
+		options.Endpoint, _ = cmd.Flags().GetString("endpoint")
+		options.Type, _ = cmd.Flags().GetString("type")
+		options.InputType, _ = cmd.Flags().GetString("input-type")
+		options.OutputType, _ = cmd.Flags().GetString("output-type")
+		options.TimeoutSeconds, _ = cmd.Flags().GetInt32("timeout")
+
+		if err := transformations.RegisterPlugin(args[0], options); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// deletePluginCmd represents the transformations plugins delete command
+var deletePluginCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a transformation plugin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.DeletePlugin(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// lookupTablesCmd represents the transformations lookup-tables command
+var lookupTablesCmd = &cobra.Command{
+	Use:   "lookup-tables",
+	Short: "Manage lookup tables",
+	Long: `Add, list, change, refresh and delete the lookup tables the lookup transformation maps values
+through, e.g. legacy country codes to ISO codes during a migration.
+
+Mapping rules name the lookup table in the options of the lookup transformation:
+  redb mappings add-rule --mapping legacy_to_new --rule country_iso \
+    --source legacy_erp.customers.country --target crm.customers.country_code \
+    --transformation lookup --options '{"table":"country_codes"}'
+
+Options of the lookup transformation: table (required), case_insensitive, default, and
+on_missing ("keep", the default, writes missing values unchanged; "error" fails them).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// listLookupTablesCmd represents the transformations lookup-tables list command
+var listLookupTablesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the lookup tables of the tenant",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.ListLookupTables(); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// showLookupTableCmd represents the transformations lookup-tables show command
+var showLookupTableCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a lookup table with its entries",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.ShowLookupTable(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// addLookupTableCmd represents the transformations lookup-tables add command
+var addLookupTableCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a lookup table",
+	Long: `Add a lookup table whose entries are given, or loaded from a table of an attached database.
+
+Entries are given with --entry key=value flags and/or an --entries-file: a JSON object of keys
+and values (.json), or CSV records of a key and a value without header. The rows of a source
+table are loaded at once and again with "lookup-tables refresh"; lookups do not read the
+database. A lookup table holds at most 100000 entries.
+
+Examples:
+  # Map legacy country codes to ISO codes
+  redb transformations lookup-tables add country_codes --entry UK=GB --entry EL=GR --entry YU=RS
+
+  # Load the entries from a CSV file
+  redb transformations lookup-tables add country_codes --entries-file country_codes.csv
+
+  # Load the entries from a table of a database of the active workspace
+  redb transformations lookup-tables add country_codes --database legacy_erp --table country_map \
+    --key-column legacy_code --value-column iso_code`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entryValues, _ := cmd.Flags().GetStringArray("entry")
+		entriesFile, _ := cmd.Flags().GetString("entries-file")
+		databaseName, _ := cmd.Flags().GetString("database")
+
+		options := transformations.LookupTableOptions{}
+		options.Description, _ = cmd.Flags().GetString("description")
+		if cmd.Flags().Changed("cache-ttl") {
+			ttl, _ := cmd.Flags().GetInt32("cache-ttl")
+			options.CacheTTLSeconds = &ttl
+		}
+
+		var err error
+		if databaseName != "" {
+			source := &transformations.LookupTableSource{DatabaseName: databaseName}
+			source.WorkspaceName, _ = cmd.Flags().GetString("workspace")
+			source.TableName, _ = cmd.Flags().GetString("table")
+			source.KeyColumn, _ = cmd.Flags().GetString("key-column")
+			source.ValueColumn, _ = cmd.Flags().GetString("value-column")
+			if source.TableName == "" || source.KeyColumn == "" || source.ValueColumn == "" {
+				fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", "--table, --key-column and --value-column are required with --database"))
+				os.Exit(1)
+			}
+			options.Source = source
+		}
+		if len(entryValues) > 0 || entriesFile != "" {
+			options.Entries, err = transformations.ReadLookupEntries(entryValues, entriesFile)
+		}
+		if err == nil {
+			err = transformations.AddLookupTable(args[0], options)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// modifyLookupTableCmd represents the transformations lookup-tables modify command
+var modifyLookupTableCmd = &cobra.Command{
+	Use:   "modify [name]",
+	Short: "Change the entries, description or cache TTL of a lookup table",
+	Long: `Change the entries, description or cache TTL of a lookup table. The entries cached by the
+transformation service are dropped, so that the next lookups use the changes.
+
+Examples:
+  # Add or change entries and remove others
+  redb transformations lookup-tables modify country_codes --entry SU=RU --remove YU
+
+  # Replace all the entries with those of a file
+  redb transformations lookup-tables modify country_codes --entries-file country_codes.json --replace`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entryValues, _ := cmd.Flags().GetStringArray("entry")
+		entriesFile, _ := cmd.Flags().GetString("entries-file")
+
+		changes := transformations.LookupTableChanges{}
+		changes.RemoveKeys, _ = cmd.Flags().GetStringArray("remove")
+		changes.ReplaceEntries, _ = cmd.Flags().GetBool("replace")
+		if cmd.Flags().Changed("description") {
+			description, _ := cmd.Flags().GetString("description")
+			changes.Description = &description
+		}
+		if cmd.Flags().Changed("cache-ttl") {
+			ttl, _ := cmd.Flags().GetInt32("cache-ttl")
+			changes.CacheTTLSeconds = &ttl
+		}
+
+		var err error
+		if len(entryValues) > 0 || entriesFile != "" {
+			changes.SetEntries, err = transformations.ReadLookupEntries(entryValues, entriesFile)
+		}
+		if err == nil {
+			err = transformations.ModifyLookupTable(args[0], changes)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// deleteLookupTableCmd represents the transformations lookup-tables delete command
+var deleteLookupTableCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a lookup table",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.DeleteLookupTable(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// refreshLookupTableCmd represents the transformations lookup-tables refresh command
+var refreshLookupTableCmd = &cobra.Command{
+	Use:   "refresh [name]",
+	Short: "Reload a lookup table from its source table and drop its cached entries",
+	Long: `Reload the entries of a lookup table from its source table, if it has one, and drop the
+entries cached by the transformation service. Other instances of the transformation service use
+the new entries when the cache TTL of the table expires.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := transformations.RefreshLookupTable(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
 			os.Exit(1)
 		}
 	},
@@ -51,4 +302,42 @@ func init() {
 
 	// Add flags to list command
 	listTransformationsCmd.Flags().BoolP("verbose", "v", false, "Show detailed information for each transformation")
+
+	transformationsCmd.AddCommand(pluginsCmd)
+	pluginsCmd.AddCommand(listPluginsCmd)
+	pluginsCmd.AddCommand(registerPluginCmd)
+	pluginsCmd.AddCommand(deletePluginCmd)
+
+	registerPluginCmd.Flags().String("endpoint", "", "host:port of the gRPC endpoint serving the plugin")
+	registerPluginCmd.Flags().String("kind", "grpc", "Plugin kind: grpc (wasm is not supported yet)")
+	registerPluginCmd.Flags().String("description", "", "Description of the plugin")
+	registerPluginCmd.Flags().String("type", "passthrough", "Transformation type: passthrough, merge, split, generator or null_returning")
+	registerPluginCmd.Flags().String("input-type", "", "Data type the plugin reads (default string)")
+	registerPluginCmd.Flags().String("output-type", "", "Data type the plugin returns (default string)")
+	registerPluginCmd.Flags().Int32("timeout", 0, "Deadline of each call in seconds (default 10)")
+
+	transformationsCmd.AddCommand(lookupTablesCmd)
+	lookupTablesCmd.AddCommand(listLookupTablesCmd)
+	lookupTablesCmd.AddCommand(showLookupTableCmd)
+	lookupTablesCmd.AddCommand(addLookupTableCmd)
+	lookupTablesCmd.AddCommand(modifyLookupTableCmd)
+	lookupTablesCmd.AddCommand(deleteLookupTableCmd)
+	lookupTablesCmd.AddCommand(refreshLookupTableCmd)
+
+	addLookupTableCmd.Flags().String("description", "", "Description of the lookup table")
+	addLookupTableCmd.Flags().StringArray("entry", nil, "Entry written key=value (repeatable)")
+	addLookupTableCmd.Flags().String("entries-file", "", "File of entries: a JSON object, or CSV records of a key and a value")
+	addLookupTableCmd.Flags().String("database", "", "Database whose table the entries are loaded from")
+	addLookupTableCmd.Flags().String("workspace", "", "Workspace of the database (default the active workspace)")
+	addLookupTableCmd.Flags().String("table", "", "Table the entries are loaded from")
+	addLookupTableCmd.Flags().String("key-column", "", "Column of the source table holding the keys")
+	addLookupTableCmd.Flags().String("value-column", "", "Column of the source table holding the values")
+	addLookupTableCmd.Flags().Int32("cache-ttl", 300, "Seconds the transformation service caches the entries; 0 until refreshed")
+
+	modifyLookupTableCmd.Flags().String("description", "", "Description of the lookup table")
+	modifyLookupTableCmd.Flags().StringArray("entry", nil, "Entry to add or change, written key=value (repeatable)")
+	modifyLookupTableCmd.Flags().String("entries-file", "", "File of entries to add or change: a JSON object, or CSV records of a key and a value")
+	modifyLookupTableCmd.Flags().StringArray("remove", nil, "Key of an entry to remove (repeatable)")
+	modifyLookupTableCmd.Flags().Bool("replace", false, "Replace all the entries with the given ones")
+	modifyLookupTableCmd.Flags().Int32("cache-ttl", 0, "Seconds the transformation service caches the entries; 0 until refreshed")
 }
@@ -66,6 +66,18 @@ var deleteWorkspaceCmd = &cobra.Command{
 	},
 }
 
+// statusWorkspaceCmd represents the status command
+var statusWorkspaceCmd = &cobra.Command{
+	Use:   "status [workspace-name]",
+	Short: "Show workspace health",
+	Long: "Display the health score of a workspace with its checks: database connectivity, CDC lag, " +
+		"failing runs, schema drift and policy violations.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return workspaces.WorkspaceStatus(args[0])
+	},
+}
+
 func init() {
 	// Add subcommands to workspaces command
 	workspacesCmd.AddCommand(listWorkspacesCmd)
@@ -73,4 +85,5 @@ func init() {
 	workspacesCmd.AddCommand(addWorkspaceCmd)
 	workspacesCmd.AddCommand(modifyWorkspaceCmd)
 	workspacesCmd.AddCommand(deleteWorkspaceCmd)
+	workspacesCmd.AddCommand(statusWorkspaceCmd)
 }
@@ -461,3 +461,94 @@ func ForkCommit(repoBranchCommitStr string, flags interface{}) error {
 
 	return nil
 }
+
+// ValidateCommit validates the schema of a commit and lists the issues found
+func ValidateCommit(repoBranchCommitStr string, flags interface{}) error {
+	repoName, branchName, commitCode, err := parseRepoBranchCommit(repoBranchCommitStr)
+	if err != nil {
+		return err
+	}
+
+	if repoName == "" || branchName == "" || commitCode == "" {
+		return fmt.Errorf("repository name, branch name, and commit code are required")
+	}
+
+	// Parse flags
+	flagSet, ok := flags.(*pflag.FlagSet)
+	if !ok {
+		return fmt.Errorf("invalid flags type")
+	}
+
+	noLint, _ := flagSet.GetBool("no-lint")
+	rules, _ := flagSet.GetStringSlice("rules")
+	naming, _ := flagSet.GetString("naming")
+	severities, _ := flagSet.GetStringToString("severity")
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	// Build the request payload based on parsed flags
+	requestPayload := map[string]interface{}{
+		"disable_lint": noLint,
+	}
+	if len(rules) > 0 {
+		requestPayload["lint_rules"] = rules
+	}
+	if naming != "" {
+		requestPayload["naming_convention"] = naming
+	}
+	if len(severities) > 0 {
+		requestPayload["severities"] = severities
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/repos/%s/branches/%s/commits/%s/validate", repoName, branchName, commitCode))
+	if err != nil {
+		return err
+	}
+
+	var validateResponse struct {
+		Message string `json:"message"`
+		Success bool   `json:"success"`
+		Valid   bool   `json:"valid"`
+		Issues  []struct {
+			Severity   string `json:"severity"`
+			Rule       string `json:"rule"`
+			Field      string `json:"field"`
+			Message    string `json:"message"`
+			Suggestion string `json:"suggestion"`
+		} `json:"issues"`
+		CriticalCount int `json:"critical_count"`
+		WarningCount  int `json:"warning_count"`
+		InfoCount     int `json:"info_count"`
+	}
+	if err := client.Post(url, requestPayload, &validateResponse); err != nil {
+		return fmt.Errorf("failed to validate commit: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Schema validation for commit '%s' in branch '%s' of repository '%s'\n", commitCode, branchName, repoName)
+	fmt.Println(strings.Repeat("=", 60))
+	for _, issue := range validateResponse.Issues {
+		fmt.Printf("[%s] %s (%s): %s\n", strings.ToUpper(issue.Severity), issue.Field, issue.Rule, issue.Message)
+		if issue.Suggestion != "" {
+			fmt.Printf("    %s\n", issue.Suggestion)
+		}
+	}
+	if len(validateResponse.Issues) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%d critical, %d warnings, %d info\n", validateResponse.CriticalCount, validateResponse.WarningCount, validateResponse.InfoCount)
+
+	if !validateResponse.Valid {
+		return fmt.Errorf("commit schema has critical issues and cannot be deployed")
+	}
+	fmt.Println("Schema is valid")
+	return nil
+}
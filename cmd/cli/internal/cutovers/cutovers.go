@@ -0,0 +1,446 @@
+package cutovers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+	"github.com/redbco/redb-open/cmd/cli/internal/httpclient"
+)
+
+// pollInterval is how often a running cutover is shown again while waiting for it
+const pollInterval = 2 * time.Second
+
+type step struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	Gated    bool   `json:"gated"`
+	Approved bool   `json:"approved"`
+	Message  string `json:"message"`
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+}
+
+type cutover struct {
+	CutoverID           string   `json:"cutover_id"`
+	CutoverName         string   `json:"cutover_name"`
+	CutoverDescription  string   `json:"cutover_description"`
+	RelationshipName    string   `json:"relationship_name"`
+	ConnectionName      string   `json:"connection_name"`
+	StopWritesHook      string   `json:"stop_writes_hook"`
+	UnpauseHook         string   `json:"unpause_hook"`
+	Gates               []string `json:"gates"`
+	MaxRetries          int32    `json:"max_retries"`
+	DrainTimeoutSeconds int32    `json:"drain_timeout_seconds"`
+	State               string   `json:"state"`
+	CurrentStep         string   `json:"current_step"`
+	Steps               []step   `json:"steps"`
+	Created             string   `json:"created"`
+	Updated             string   `json:"updated"`
+}
+
+type cutoverResponse struct {
+	Message string  `json:"message"`
+	Success bool    `json:"success"`
+	Cutover cutover `json:"cutover"`
+}
+
+// AddCutoverOptions are the settings of a new cutover
+type AddCutoverOptions struct {
+	Description    string
+	Relationship   string
+	Connection     string
+	StopWritesHook string
+	UnpauseHook    string
+	Gates          []string
+	MaxRetries     int32
+	DrainTimeout   time.Duration
+}
+
+// AddCutover adds a pending cutover
+func AddCutover(cutoverName string, options AddCutoverOptions) error {
+	cutoverName = strings.TrimSpace(cutoverName)
+	if cutoverName == "" {
+		return fmt.Errorf("cutover name is required")
+	}
+	if options.DrainTimeout < time.Second {
+		return fmt.Errorf("drain timeout must be at least one second")
+	}
+
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/cutovers")
+	if err != nil {
+		return err
+	}
+
+	drainTimeoutSeconds := int32(options.DrainTimeout / time.Second)
+	req := struct {
+		CutoverName         string   `json:"cutover_name"`
+		CutoverDescription  string   `json:"cutover_description,omitempty"`
+		RelationshipName    string   `json:"relationship_name"`
+		ConnectionName      string   `json:"connection_name"`
+		StopWritesHook      string   `json:"stop_writes_hook,omitempty"`
+		UnpauseHook         string   `json:"unpause_hook,omitempty"`
+		Gates               []string `json:"gates"`
+		MaxRetries          *int32   `json:"max_retries"`
+		DrainTimeoutSeconds *int32   `json:"drain_timeout_seconds"`
+	}{
+		CutoverName:         cutoverName,
+		CutoverDescription:  options.Description,
+		RelationshipName:    options.Relationship,
+		ConnectionName:      options.Connection,
+		StopWritesHook:      options.StopWritesHook,
+		UnpauseHook:         options.UnpauseHook,
+		Gates:               options.Gates,
+		MaxRetries:          &options.MaxRetries,
+		DrainTimeoutSeconds: &drainTimeoutSeconds,
+	}
+
+	var response cutoverResponse
+	if err := client.Post(url, req, &response); err != nil {
+		return fmt.Errorf("failed to add cutover: %v", err)
+	}
+
+	fmt.Printf("✓ Cutover '%s' added\n", cutoverName)
+	if options.StopWritesHook == "" || options.UnpauseHook == "" {
+		fmt.Println("\nWithout hooks, stop the writes of the applications before the cutover and resume them after it.")
+	}
+	fmt.Printf("\nTo start it, run:\n  redb cutovers start %s\n", cutoverName)
+	return nil
+}
+
+// ListCutovers lists the cutovers of the active workspace
+func ListCutovers() error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/cutovers")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Cutovers []cutover `json:"cutovers"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list cutovers: %v", err)
+	}
+
+	if len(response.Cutovers) == 0 {
+		fmt.Println("No cutovers found in this workspace.")
+		fmt.Println("\nTo add a cutover, run:")
+		fmt.Println("  redb cutovers add <cutover-name> --relationship <relationship-name> --connection <connection-name>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Name\tRelationship\tConnection\tState\tStep")
+	fmt.Fprintln(w, "----\t------------\t----------\t-----\t----")
+	for _, c := range response.Cutovers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.CutoverName, c.RelationshipName, c.ConnectionName, c.State, orDash(c.CurrentStep))
+	}
+	_ = w.Flush()
+	fmt.Println()
+
+	return nil
+}
+
+// ShowCutover shows a cutover and the progress of its steps
+func ShowCutover(cutoverName string) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	c, err := getCutover(client, profileInfo, cutoverName)
+	if err != nil {
+		return err
+	}
+	printCutover(c)
+	return nil
+}
+
+// StartCutover starts a pending cutover and, when wait is set, follows it until it stops running
+func StartCutover(cutoverName string, wait bool) error {
+	return operation(cutoverName, "start", nil, wait)
+}
+
+// ApproveCutover approves the step a cutover waits at and, when wait is set, follows it until it
+// stops running
+func ApproveCutover(cutoverName, stepName string, wait bool) error {
+	body := struct {
+		Step string `json:"step,omitempty"`
+	}{Step: stepName}
+	return operation(cutoverName, "approve", body, wait)
+}
+
+// RetryCutover runs the step a failed cutover stopped at again and, when wait is set, follows it
+// until it stops running
+func RetryCutover(cutoverName string, wait bool) error {
+	return operation(cutoverName, "retry", nil, wait)
+}
+
+// AbortCutover aborts a cutover, resuming the writes it stopped
+func AbortCutover(cutoverName string) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/cutovers/%s/abort", cutoverName))
+	if err != nil {
+		return err
+	}
+
+	var response cutoverResponse
+	if err := client.Post(url, nil, &response); err != nil {
+		return fmt.Errorf("failed to abort cutover: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("%s; resume the writes of the applications by hand", response.Message)
+	}
+
+	fmt.Printf("✓ Cutover '%s' aborted\n", cutoverName)
+	if connectionSwitched(response.Cutover) {
+		fmt.Printf("\nConnection '%s' was already switched to the target database and still points to it.\n", response.Cutover.ConnectionName)
+	}
+	return nil
+}
+
+// DeleteCutover deletes a cutover that is not running
+func DeleteCutover(cutoverName string) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/cutovers/%s", cutoverName))
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to delete cutover: %v", err)
+	}
+
+	fmt.Printf("✓ Cutover '%s' deleted\n", cutoverName)
+	return nil
+}
+
+// ListConnections lists the application connections of the active workspace
+func ListConnections() error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/application-connections")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Connections []struct {
+			ConnectionName        string `json:"connection_name"`
+			DatabaseName          string `json:"database_name"`
+			DatabaseType          string `json:"database_type"`
+			Host                  string `json:"host"`
+			Port                  int32  `json:"port"`
+			DatabaseDBName        string `json:"database_db_name"`
+			PreviousDatabaseName  string `json:"previous_database_name"`
+			SwitchedByCutoverName string `json:"switched_by_cutover_name"`
+		} `json:"connections"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list application connections: %v", err)
+	}
+
+	if len(response.Connections) == 0 {
+		fmt.Println("No application connections found in this workspace.")
+		fmt.Println("\nApplication connections are created with the cutovers that switch them.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Name\tDatabase\tType\tAddress\tPrevious\tCutover")
+	fmt.Fprintln(w, "----\t--------\t----\t-------\t--------\t-------")
+	for _, a := range response.Connections {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s:%d/%s\t%s\t%s\n",
+			a.ConnectionName,
+			a.DatabaseName,
+			a.DatabaseType,
+			a.Host, a.Port, a.DatabaseDBName,
+			orDash(a.PreviousDatabaseName),
+			orDash(a.SwitchedByCutoverName))
+	}
+	_ = w.Flush()
+	fmt.Println()
+
+	return nil
+}
+
+// operation moves a cutover to running and follows it
+func operation(cutoverName, action string, body interface{}, wait bool) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/cutovers/%s/%s", cutoverName, action))
+	if err != nil {
+		return err
+	}
+
+	var response cutoverResponse
+	if err := client.Post(url, body, &response); err != nil {
+		return fmt.Errorf("failed to %s cutover: %v", action, err)
+	}
+	fmt.Printf("✓ %s\n", response.Message)
+
+	if !wait {
+		fmt.Printf("\nTo follow it, run:\n  redb cutovers show %s\n", cutoverName)
+		return nil
+	}
+	return follow(client, profileInfo, cutoverName)
+}
+
+// follow prints the steps of a cutover as they finish, until it stops running
+func follow(client *httpclient.ProfileHTTPClient, profileInfo *common.ProfileInfo, cutoverName string) error {
+	printed := make(map[string]string) // Last status printed by step
+	for {
+		c, err := getCutover(client, profileInfo, cutoverName)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range c.Steps {
+			if s.Status == "pending" || printed[s.Name] == s.Status+s.Message {
+				continue
+			}
+			printed[s.Name] = s.Status + s.Message
+			fmt.Printf("  %-18s %-10s %s\n", s.Name, s.Status, s.Message)
+		}
+
+		switch c.State {
+		case "running":
+			time.Sleep(pollInterval)
+			continue
+		case "awaiting_approval":
+			fmt.Printf("\nCutover '%s' awaits approval of %s. To approve it, run:\n", cutoverName, c.CurrentStep)
+			fmt.Printf("  redb cutovers approve %s --step %s\n", cutoverName, c.CurrentStep)
+			return nil
+		case "failed":
+			return fmt.Errorf("cutover '%s' failed at step %s; retry it with 'redb cutovers retry %s' or abort it with 'redb cutovers abort %s'",
+				cutoverName, c.CurrentStep, cutoverName, cutoverName)
+		default:
+			fmt.Printf("\nCutover '%s' is %s\n", cutoverName, c.State)
+			return nil
+		}
+	}
+}
+
+func getCutover(client *httpclient.ProfileHTTPClient, profileInfo *common.ProfileInfo, cutoverName string) (*cutover, error) {
+	cutoverName = strings.TrimSpace(cutoverName)
+	if cutoverName == "" {
+		return nil, fmt.Errorf("cutover name is required")
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/cutovers/%s", cutoverName))
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Cutover cutover `json:"cutover"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return nil, fmt.Errorf("failed to get cutover: %v", err)
+	}
+	return &response.Cutover, nil
+}
+
+func printCutover(c *cutover) {
+	fmt.Printf("\nCutover Details: %s\n", c.CutoverName)
+	fmt.Printf("=====================================\n\n")
+	fmt.Printf("ID:            %s\n", c.CutoverID)
+	if c.CutoverDescription != "" {
+		fmt.Printf("Description:   %s\n", c.CutoverDescription)
+	}
+	fmt.Printf("Relationship:  %s\n", c.RelationshipName)
+	fmt.Printf("Connection:    %s\n", c.ConnectionName)
+	fmt.Printf("State:         %s\n", c.State)
+	if c.CurrentStep != "" {
+		fmt.Printf("Current step:  %s\n", c.CurrentStep)
+	}
+	fmt.Printf("\nStop writes:   %s\n", orDash(c.StopWritesHook))
+	fmt.Printf("Unpause:       %s\n", orDash(c.UnpauseHook))
+	fmt.Printf("Gates:         %s\n", orDash(strings.Join(c.Gates, ", ")))
+	fmt.Printf("Max retries:   %d\n", c.MaxRetries)
+	fmt.Printf("Drain timeout: %s\n", time.Duration(c.DrainTimeoutSeconds)*time.Second)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Step\tStatus\tAttempts\tGate\tFinished\tMessage")
+	fmt.Fprintln(w, "----\t------\t--------\t----\t--------\t-------")
+	for _, s := range c.Steps {
+		gate := "-"
+		if s.Gated {
+			gate = "gated"
+			if s.Approved {
+				gate = "approved"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", s.Name, s.Status, s.Attempts, gate, orDash(s.Finished), s.Message)
+	}
+	_ = w.Flush()
+
+	fmt.Printf("\nCreated:       %s\n", c.Created)
+	fmt.Printf("Updated:       %s\n", c.Updated)
+	fmt.Println()
+}
+
+// connectionSwitched reports whether the switch_connection step of a cutover succeeded
+func connectionSwitched(c cutover) bool {
+	for _, s := range c.Steps {
+		if s.Name == "switch_connection" {
+			return s.Status == "succeeded"
+		}
+	}
+	return false
+}
+
+func workspaceClient() (*common.ProfileInfo, *httpclient.ProfileHTTPClient, error) {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := common.ValidateWorkspace(profileInfo); err != nil {
+		return nil, nil, err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return profileInfo, client, nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -49,6 +51,8 @@ type Database struct {
 	InstanceSSL           bool     `json:"instance_ssl"`
 	InstanceStatusMessage string   `json:"instance_status_message"`
 	InstanceStatus        string   `json:"instance_status"`
+
+	DatabaseLabels map[string]string `json:"database_labels,omitempty"`
 }
 
 type CreateDatabaseRequest struct {
@@ -359,7 +363,7 @@ func formatTablesData(tablesJSON string) error {
 }
 
 // ListDatabases lists all databases using profile-based authentication
-func ListDatabases() error {
+func ListDatabases(selector string) error {
 	profileInfo, err := common.GetActiveProfileInfo()
 	if err != nil {
 		return err
@@ -370,7 +374,11 @@ func ListDatabases() error {
 		return err
 	}
 
-	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/databases")
+	path := "/databases"
+	if selector != "" {
+		path += "?selector=" + url.QueryEscape(selector)
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, path)
 	if err != nil {
 		return err
 	}
@@ -378,7 +386,7 @@ func ListDatabases() error {
 	var databasesResponse struct {
 		Databases []Database `json:"databases"`
 	}
-	if err := client.Get(url, &databasesResponse); err != nil {
+	if err := client.Get(apiURL, &databasesResponse); err != nil {
 		return fmt.Errorf("failed to list databases: %v", err)
 	}
 
@@ -458,6 +466,9 @@ func ShowDatabase(databaseName string, args []string) error {
 	if db.EnvironmentID != "" {
 		fmt.Printf("Environment ID:        %s\n", db.EnvironmentID)
 	}
+	if len(db.DatabaseLabels) > 0 {
+		fmt.Printf("Labels:                %s\n", formatLabels(db.DatabaseLabels))
+	}
 	fmt.Printf("Connected to Node ID:  %s\n", db.ConnectedToNodeID)
 	fmt.Printf("Instance ID:           %s\n", db.InstanceID)
 	fmt.Printf("Instance Name:         %s\n", db.InstanceName)
@@ -926,6 +937,91 @@ func ModifyDatabase(databaseName string, args []string) error {
 	return nil
 }
 
+// LabelDatabase sets labels of a database given as key=value and removes labels given as key-
+func LabelDatabase(databaseName string, changes []string) error {
+	databaseName = strings.TrimSpace(databaseName)
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("at least one label change (key=value or key-) is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/databases/%s", databaseName))
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Database Database `json:"database"`
+	}
+	if err := client.Get(apiURL, &response); err != nil {
+		return fmt.Errorf("failed to get database: %v", err)
+	}
+
+	labels, err := applyLabelChanges(response.Database.DatabaseLabels, changes)
+	if err != nil {
+		return err
+	}
+
+	var updateResponse struct {
+		Database Database `json:"database"`
+	}
+	if err := client.Put(apiURL, map[string]interface{}{"labels": labels}, &updateResponse); err != nil {
+		return fmt.Errorf("failed to update database labels: %v", err)
+	}
+
+	if len(updateResponse.Database.DatabaseLabels) == 0 {
+		fmt.Printf("Database '%s' has no labels\n", databaseName)
+	} else {
+		fmt.Printf("Labels of database '%s': %s\n", databaseName, formatLabels(updateResponse.Database.DatabaseLabels))
+	}
+	return nil
+}
+
+// applyLabelChanges returns a copy of labels with the changes applied: key=value sets a label
+// and key- removes it
+func applyLabelChanges(labels map[string]string, changes []string) (map[string]string, error) {
+	result := make(map[string]string, len(labels))
+	for key, value := range labels {
+		result[key] = value
+	}
+	for _, change := range changes {
+		if key, value, ok := strings.Cut(change, "="); ok {
+			if key = strings.TrimSpace(key); key == "" {
+				return nil, fmt.Errorf("invalid label %q: the key is empty", change)
+			}
+			result[key] = strings.TrimSpace(value)
+			continue
+		}
+		if key := strings.TrimSuffix(change, "-"); key != change && key != "" {
+			delete(result, key)
+			continue
+		}
+		return nil, fmt.Errorf("invalid label change %q: use key=value to set a label or key- to remove it", change)
+	}
+	return result, nil
+}
+
+// formatLabels formats labels as key=value pairs sorted by key
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
 func DeleteDatabase(databaseName string, args []string) error {
 	databaseName = strings.TrimSpace(databaseName)
 	if databaseName == "" {
@@ -1459,6 +1555,10 @@ func CloneDatabase(sourceDatabaseName string, flags interface{}) error {
 	merge, _ := flagSet.GetBool("merge")
 	sourceNodeID, _ := flagSet.GetUint64("source-node")
 	targetNodeID, _ := flagSet.GetUint64("target-node")
+	ownerHandling, _ := flagSet.GetString("owner-handling")
+	grantHandling, _ := flagSet.GetString("grant-handling")
+	commentHandling, _ := flagSet.GetString("comment-handling")
+	ownerMappings, _ := flagSet.GetStringSlice("owner-mapping")
 
 	// Validate target options
 	if instanceName != "" && databaseName != "" {
@@ -1483,14 +1583,33 @@ func CloneDatabase(sourceDatabaseName string, flags interface{}) error {
 		return err
 	}
 
+	// Owner, grant and comment handling applied when the schema is converted
+	transformationOptions := map[string]string{}
+	if ownerHandling != "" {
+		transformationOptions["owner_handling"] = ownerHandling
+	}
+	if grantHandling != "" {
+		transformationOptions["grant_handling"] = grantHandling
+	}
+	if commentHandling != "" {
+		transformationOptions["comment_handling"] = commentHandling
+	}
+	if len(ownerMappings) > 0 {
+		transformationOptions["owner_mappings"] = strings.Join(ownerMappings, ",")
+	}
+
 	// Build the request payload based on parsed flags
+	options := map[string]interface{}{
+		"with_data": withData,
+		"wipe":      wipe,
+		"merge":     merge,
+	}
+	if len(transformationOptions) > 0 {
+		options["transformation_options"] = transformationOptions
+	}
 	requestPayload := map[string]interface{}{
 		"source_database_name": sourceDatabaseName,
-		"options": map[string]interface{}{
-			"with_data": withData,
-			"wipe":      wipe,
-			"merge":     merge,
-		},
+		"options":              options,
 	}
 
 	// Set target based on flags
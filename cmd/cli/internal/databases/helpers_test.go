@@ -305,3 +305,23 @@ func TestSSLSetup(t *testing.T) {
 		}
 	})
 }
+
+func TestApplyLabelChanges(t *testing.T) {
+	current := map[string]string{"team": "sales", "tier": "gold"}
+	got, err := applyLabelChanges(current, []string{"team=analytics", "tier-", "region=eu"})
+	if err != nil {
+		t.Fatalf("applyLabelChanges: %v", err)
+	}
+	if formatLabels(got) != "region=eu, team=analytics" {
+		t.Fatalf("got %s", formatLabels(got))
+	}
+	if current["team"] != "sales" {
+		t.Fatal("the current labels must not be modified")
+	}
+
+	for _, change := range []string{"team", "=x", "-"} {
+		if _, err := applyLabelChanges(nil, []string{change}); err == nil {
+			t.Errorf("applyLabelChanges(%q) should fail", change)
+		}
+	}
+}
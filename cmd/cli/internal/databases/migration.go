@@ -0,0 +1,174 @@
+package databases
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// MigrationOptions selects the schema a database is migrated to, and whether to execute the plan
+type MigrationOptions struct {
+	TargetDatabase string // Migrate to the schema of this database
+	SchemaFile     string // Or to the unified model in this file, such as a .schema.json of "schemas convert"
+	DryRun         bool   // Only show the plan
+	ApplyRenames   bool   // Rename the tables and columns that look renamed instead of dropping them
+	Yes            bool   // Execute destructive plans without asking
+	Output         string // Write the script of the plan to this file
+}
+
+// migrationStep is a statement of a migration plan
+type migrationStep struct {
+	Phase       string `json:"phase"`
+	Statement   string `json:"statement"`
+	Destructive bool   `json:"destructive"`
+	Description string `json:"description"`
+	Executed    bool   `json:"executed"`
+	Error       string `json:"error"`
+}
+
+// migrationPlan is the response of the plan-migration endpoint
+type migrationPlan struct {
+	Message       string          `json:"message"`
+	Success       bool            `json:"success"`
+	DatabaseType  string          `json:"database_type"`
+	HasChanges    bool            `json:"has_changes"`
+	Steps         []migrationStep `json:"steps"`
+	Script        string          `json:"script"`
+	Warnings      []string        `json:"warnings"`
+	Destructive   bool            `json:"destructive"`
+	DryRun        bool            `json:"dry_run"`
+	ExecutedSteps int32           `json:"executed_steps"`
+}
+
+// PlanMigration plans the statements migrating a database to the schema of another database, or
+// to a unified model, and executes them unless it is a dry run. Destructive plans are executed
+// after confirmation only.
+func PlanMigration(databaseName string, options MigrationOptions) error {
+	databaseName = strings.TrimSpace(databaseName)
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+	if (options.TargetDatabase == "") == (options.SchemaFile == "") {
+		return fmt.Errorf("specify either --to-database or --to-schema-file")
+	}
+
+	request := map[string]interface{}{"dry_run": true, "apply_renames": options.ApplyRenames}
+	if options.TargetDatabase != "" {
+		request["target_database_name"] = options.TargetDatabase
+	} else {
+		data, err := os.ReadFile(options.SchemaFile)
+		if err != nil {
+			return fmt.Errorf("failed to read schema file: %v", err)
+		}
+		if !json.Valid(data) {
+			return fmt.Errorf("schema file %s is not valid JSON", options.SchemaFile)
+		}
+		request["target_schema"] = json.RawMessage(data)
+	}
+
+	// The plan is always shown before it is executed
+	plan, err := requestMigration(databaseName, request)
+	if err != nil {
+		return err
+	}
+	printMigrationPlan(databaseName, plan)
+
+	if options.Output != "" {
+		if err := os.WriteFile(options.Output, []byte(plan.Script), 0o644); err != nil {
+			return fmt.Errorf("failed to write the migration script: %v", err)
+		}
+		fmt.Printf("Migration script written to %s\n", options.Output)
+	}
+
+	if options.DryRun || len(plan.Steps) == 0 {
+		if len(plan.Steps) > 0 {
+			fmt.Println("Dry run: no statements were executed. Run with --dry-run=false to execute them.")
+		}
+		return nil
+	}
+
+	if plan.Destructive && !options.Yes {
+		reader := bufio.NewReader(os.Stdin)
+		fmt.Printf("The migration of database '%s' has destructive steps that may lose data. Execute it? (y/N): ", databaseName)
+		confirmation, _ := reader.ReadString('\n')
+		confirmation = strings.TrimSpace(strings.ToLower(confirmation))
+		if confirmation != "y" && confirmation != "yes" {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	request["dry_run"] = false
+	request["allow_destructive"] = plan.Destructive
+	result, err := requestMigration(databaseName, request)
+	if err != nil {
+		return err
+	}
+
+	for i, step := range result.Steps {
+		switch {
+		case step.Executed:
+			fmt.Printf("  %-9s %d. %s\n", "[ok]", i+1, step.Description)
+		case step.Error != "":
+			fmt.Printf("  %-9s %d. %s: %s\n", "[failed]", i+1, step.Description, step.Error)
+		default:
+			fmt.Printf("  %-9s %d. %s\n", "[skipped]", i+1, step.Description)
+		}
+	}
+	fmt.Println()
+	if !result.Success {
+		return fmt.Errorf("%s", result.Message)
+	}
+	fmt.Printf("Successfully migrated database '%s' (%d steps)\n", databaseName, result.ExecutedSteps)
+	return nil
+}
+
+func requestMigration(databaseName string, request map[string]interface{}) (*migrationPlan, error) {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return nil, err
+	}
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/databases/%s/plan-migration", databaseName))
+	if err != nil {
+		return nil, err
+	}
+
+	var plan migrationPlan
+	if err := client.Post(url, request, &plan); err != nil {
+		return nil, fmt.Errorf("failed to plan migration: %v", err)
+	}
+	return &plan, nil
+}
+
+// printMigrationPlan shows the script of a migration plan and its warnings
+func printMigrationPlan(databaseName string, plan *migrationPlan) {
+	fmt.Println()
+	if !plan.HasChanges {
+		fmt.Printf("Database '%s' already has the target schema, nothing to migrate\n", databaseName)
+	} else {
+		destructive := 0
+		for _, step := range plan.Steps {
+			if step.Destructive {
+				destructive++
+			}
+		}
+		fmt.Printf("Migration plan for database '%s' (%s): %d steps, %d destructive\n\n", databaseName, plan.DatabaseType, len(plan.Steps), destructive)
+		fmt.Println(plan.Script)
+	}
+
+	for _, w := range plan.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	if len(plan.Warnings) > 0 {
+		fmt.Println()
+	}
+}
@@ -0,0 +1,202 @@
+package databases
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// schemaSnapshot is a schema of a database as discovered at a point in time
+type schemaSnapshot struct {
+	SnapshotID string `json:"snapshot_id"`
+	Version    int32  `json:"version"`
+	Created    string `json:"created"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+// schemaChange is a change between two schema snapshots
+type schemaChange struct {
+	ChangeType  string `json:"change_type"`
+	ObjectType  string `json:"object_type"`
+	ObjectName  string `json:"object_name"`
+	Severity    string `json:"severity"`
+	Breaking    bool   `json:"breaking"`
+	Description string `json:"description"`
+}
+
+// schemaDiff is the response of the schema diff endpoint
+type schemaDiff struct {
+	Message    string         `json:"message"`
+	Success    bool           `json:"success"`
+	From       schemaSnapshot `json:"from"`
+	To         schemaSnapshot `json:"to"`
+	HasChanges bool           `json:"has_changes"`
+	Changes    []schemaChange `json:"change_records"`
+	Warnings   []string       `json:"warnings"`
+}
+
+// ShowSchemaHistory lists the schema snapshots of a database, newest first
+func ShowSchemaHistory(databaseName string, limit int32) error {
+	databaseName = strings.TrimSpace(databaseName)
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/databases/%s/schema/snapshots", databaseName)
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Snapshots []schemaSnapshot `json:"snapshots"`
+	}
+	if err := client.Get(apiURL, &response); err != nil {
+		return fmt.Errorf("failed to list schema snapshots: %v", err)
+	}
+
+	if len(response.Snapshots) == 0 {
+		fmt.Printf("No schema snapshots found for database '%s'.\n", databaseName)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Version\tCreated\tSize")
+	fmt.Fprintln(w, "-------\t-------\t----")
+	for _, snapshot := range response.Snapshots {
+		fmt.Fprintf(w, "%d\t%s\t%d bytes\n", snapshot.Version, snapshot.Created, snapshot.SizeBytes)
+	}
+	_ = w.Flush()
+	fmt.Println()
+	return nil
+}
+
+// DiffSchemaSnapshots shows the changes of the schema of a database between two points in time.
+// An empty to compares with the latest snapshot.
+func DiffSchemaSnapshots(databaseName, from, to string) error {
+	databaseName = strings.TrimSpace(databaseName)
+	if databaseName == "" {
+		return fmt.Errorf("database name is required")
+	}
+
+	now := time.Now()
+	fromSelector, err := resolveSnapshotSelector(from, now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %v", err)
+	}
+	if fromSelector == "" {
+		return fmt.Errorf("--from is required")
+	}
+	toSelector, err := resolveSnapshotSelector(to, now)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %v", err)
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/databases/%s/schema/diff?from=%s", databaseName, url.QueryEscape(fromSelector))
+	if toSelector != "" {
+		path += "&to=" + url.QueryEscape(toSelector)
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	var diff schemaDiff
+	if err := client.Get(apiURL, &diff); err != nil {
+		return fmt.Errorf("failed to diff schema snapshots: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Schema of database '%s' from version %d (%s) to version %d (%s)\n\n",
+		databaseName, diff.From.Version, diff.From.Created, diff.To.Version, diff.To.Created)
+	if !diff.HasChanges {
+		fmt.Println("No changes")
+	} else {
+		breaking := 0
+		for _, change := range diff.Changes {
+			marker := " "
+			if change.Breaking {
+				marker = "!"
+				breaking++
+			}
+			fmt.Printf("  %s %-8s %s\n", marker, change.Severity, change.Description)
+		}
+		fmt.Printf("\n%d changes, %d breaking\n", len(diff.Changes), breaking)
+	}
+	for _, w := range diff.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	fmt.Println()
+	return nil
+}
+
+// resolveSnapshotSelector turns a version, an RFC3339 time, a date or a duration ago such as 72h
+// or 7d into the version or time the schema diff endpoint accepts
+func resolveSnapshotSelector(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if version, err := strconv.Atoi(value); err == nil {
+		if version < 1 {
+			return "", fmt.Errorf("version must be at least 1, got %d", version)
+		}
+		return value, nil
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at.Format(time.RFC3339Nano), nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day.Format(time.RFC3339), nil
+	}
+
+	// Durations ago; Go durations have no days, so a d suffix is handled here
+	var ago time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a version, time, date or duration", value)
+		}
+		ago = time.Duration(n * float64(24*time.Hour))
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a version, time, date or duration", value)
+		}
+		ago = d
+	}
+	if ago <= 0 {
+		return "", fmt.Errorf("duration %q must be positive", value)
+	}
+	return now.Add(-ago).Format(time.RFC3339), nil
+}
@@ -0,0 +1,34 @@
+package databases
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveSnapshotSelector(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		"":                          "",
+		"3":                         "3",
+		"2026-10-13T09:00:00+02:00": "2026-10-13T09:00:00+02:00",
+		"2026-10-13":                "2026-10-13T00:00:00Z",
+		"72h":                       "2026-10-13T12:00:00Z",
+		"7d":                        "2026-10-09T12:00:00Z",
+		"1.5d":                      "2026-10-15T00:00:00Z",
+	}
+	for value, want := range cases {
+		got, err := resolveSnapshotSelector(value, now)
+		if err != nil {
+			t.Fatalf("resolveSnapshotSelector(%q): %v", value, err)
+		}
+		if got != want {
+			t.Fatalf("resolveSnapshotSelector(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"0", "-1h", "yesterday", "xd"} {
+		if _, err := resolveSnapshotSelector(value, now); err == nil {
+			t.Fatalf("resolveSnapshotSelector(%q): expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package databases
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// ShowSupport displays the operations supported by the database adapters of the node, or by the
+// adapter of a single database type
+func ShowSupport(databaseType string) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	if databaseType != "" {
+		apiURL := common.BuildGlobalAPIURL(profileInfo, "/support-matrix/"+url.PathEscape(databaseType))
+
+		var support dbcapabilities.AdapterSupport
+		if err := client.Get(apiURL, &support); err != nil {
+			return fmt.Errorf("failed to get support of %s: %v", databaseType, err)
+		}
+		printAdapterSupport(support)
+		return nil
+	}
+
+	var matrix dbcapabilities.SupportMatrix
+	if err := client.Get(common.BuildGlobalAPIURL(profileInfo, "/support-matrix"), &matrix); err != nil {
+		return fmt.Errorf("failed to get support matrix: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Type\tName\tEdition\tSupported\tPartial\tUnsupported")
+	fmt.Fprintln(w, "----\t----\t-------\t---------\t-------\t-----------")
+	for _, support := range matrix.Databases {
+		counts := map[dbcapabilities.SupportStatus]int{}
+		for _, op := range matrix.Operations {
+			counts[support.Operations[op].Status]++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n",
+			support.ID,
+			support.Name,
+			support.Edition,
+			counts[dbcapabilities.Supported],
+			counts[dbcapabilities.Partial],
+			counts[dbcapabilities.Unsupported])
+	}
+	_ = w.Flush()
+	fmt.Println()
+	fmt.Println("Use 'redb-cli databases support <type>' for the operations of a database type.")
+	fmt.Println()
+	return nil
+}
+
+func printAdapterSupport(support dbcapabilities.AdapterSupport) {
+	fmt.Println()
+	fmt.Printf("%s (%s), %s edition\n", support.Name, support.ID, support.Edition)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "Operation\tStatus\tNotes")
+	fmt.Fprintln(w, "---------\t------\t-----")
+	for _, op := range dbcapabilities.Operations {
+		operation, ok := support.Operations[op]
+		if !ok {
+			continue
+		}
+		var notes []string
+		if len(operation.Mechanisms) > 0 {
+			notes = append(notes, "mechanisms: "+strings.Join(operation.Mechanisms, ", "))
+		}
+		notes = append(notes, operation.Caveats...)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", op, operation.Status, strings.Join(notes, "; "))
+	}
+	_ = w.Flush()
+	fmt.Println()
+}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/redbco/redb-open/cmd/cli/internal/config"
+	"github.com/redbco/redb-open/cmd/cli/internal/locale"
 )
 
 type HTTPClient struct {
@@ -16,10 +18,11 @@ type HTTPClient struct {
 }
 
 type APIError struct {
-	Status   int    `json:"status"`
-	Message  string `json:"message"`
-	Code     string `json:"code"`
-	ErrorMsg string `json:"error"`
+	Status      int      `json:"-"` // HTTP status code of the response
+	Message     string   `json:"message"`
+	Code        string   `json:"code"` // Error catalog code, e.g. REDB-2001
+	ErrorMsg    string   `json:"error"`
+	Remediation []string `json:"remediation"`
 }
 
 func (e APIError) Error() string {
@@ -29,7 +32,15 @@ func (e APIError) Error() string {
 		message = e.ErrorMsg
 	}
 	if message == "" {
-		message = fmt.Sprintf("HTTP %d error", e.Status)
+		message = locale.Sprintf("HTTP %d error", e.Status)
+	}
+	if e.Code != "" && !strings.Contains(message, e.Code) {
+		message = e.Code + ": " + message
+	}
+
+	// Show the remediation steps of the error catalog below the message
+	for _, step := range e.Remediation {
+		message += "\n  - " + step
 	}
 	return message
 }
@@ -65,6 +76,7 @@ func (c *HTTPClient) makeRequest(method, url string, body interface{}, requireAu
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", locale.Current())
 
 	// Add authentication if required and available
 	if requireAuth {
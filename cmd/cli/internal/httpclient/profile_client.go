@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/redbco/redb-open/cmd/cli/internal/locale"
 	"github.com/redbco/redb-open/cmd/cli/internal/profile"
 )
 
@@ -15,6 +17,9 @@ import (
 type ProfileHTTPClient struct {
 	client         *http.Client
 	profileManager *profile.ProfileManager
+
+	// refreshMu makes concurrent requests refresh an expired access token only once
+	refreshMu sync.Mutex
 }
 
 // NewProfileClient creates a new profile-aware HTTP client
@@ -34,34 +39,9 @@ func NewProfileClient() (*ProfileHTTPClient, error) {
 
 // makeAuthenticatedRequest performs an HTTP request with profile-based authentication
 func (c *ProfileHTTPClient) makeAuthenticatedRequest(method, url string, body interface{}) (*http.Response, error) {
-	// Get active profile
-	activeProfileName, err := c.profileManager.GetActiveProfile()
-	if err != nil {
-		return nil, fmt.Errorf("no active profile found: %v", err)
-	}
-
-	prof, err := c.profileManager.GetProfile(activeProfileName)
+	prof, err := c.activeProfileWithFreshToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active profile '%s': %v", activeProfileName, err)
-	}
-
-	// Check if logged in
-	if !prof.IsLoggedIn() {
-		return nil, fmt.Errorf("profile '%s' is not logged in or session has expired", activeProfileName)
-	}
-
-	// Check if access token needs refresh
-	if prof.IsAccessTokenExpired() && !prof.IsRefreshTokenExpired() {
-		// Attempt to refresh the token
-		if err := c.profileManager.RefreshTokens(activeProfileName); err != nil {
-			return nil, fmt.Errorf("failed to refresh access token: %v", err)
-		}
-
-		// Reload the profile with updated tokens
-		prof, err = c.profileManager.GetProfile(activeProfileName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to reload profile after token refresh: %v", err)
-		}
+		return nil, err
 	}
 
 	// Prepare request body
@@ -83,6 +63,7 @@ func (c *ProfileHTTPClient) makeAuthenticatedRequest(method, url string, body in
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", locale.Current())
 	req.Header.Set("Authorization", "Bearer "+prof.AccessToken)
 
 	// Perform request
@@ -94,6 +75,43 @@ func (c *ProfileHTTPClient) makeAuthenticatedRequest(method, url string, body in
 	return resp, nil
 }
 
+// activeProfileWithFreshToken returns the active profile, refreshing its access token if it expired
+func (c *ProfileHTTPClient) activeProfileWithFreshToken() (*profile.Profile, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	activeProfileName, err := c.profileManager.GetActiveProfile()
+	if err != nil {
+		return nil, fmt.Errorf("no active profile found: %v", err)
+	}
+
+	prof, err := c.profileManager.GetProfile(activeProfileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active profile '%s': %v", activeProfileName, err)
+	}
+
+	// Check if logged in
+	if !prof.IsLoggedIn() {
+		return nil, fmt.Errorf("profile '%s' is not logged in or session has expired", activeProfileName)
+	}
+
+	// Check if access token needs refresh
+	if prof.IsAccessTokenExpired() && !prof.IsRefreshTokenExpired() {
+		// Attempt to refresh the token
+		if err := c.profileManager.RefreshTokens(activeProfileName); err != nil {
+			return nil, fmt.Errorf("failed to refresh access token: %v", err)
+		}
+
+		// Reload the profile with updated tokens
+		prof, err = c.profileManager.GetProfile(activeProfileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reload profile after token refresh: %v", err)
+		}
+	}
+
+	return prof, nil
+}
+
 // handleResponse processes the HTTP response and handles errors
 func (c *ProfileHTTPClient) handleResponse(resp *http.Response, result interface{}) error {
 	defer func(Body io.ReadCloser) {
@@ -3,6 +3,7 @@ package instances
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -124,6 +125,45 @@ type DisconnectInstanceRequest struct {
 	DeleteInstance bool `json:"delete_instance,omitempty"`
 }
 
+// InstanceDatabase is a database on an instance
+type InstanceDatabase struct {
+	DBName       string `json:"db_name"`
+	Attached     bool   `json:"attached"`
+	DatabaseName string `json:"database_name"`
+}
+
+// ListInstanceDatabasesResponse wraps the API response for listing the databases on an instance
+type ListInstanceDatabasesResponse struct {
+	Message   string             `json:"message"`
+	Success   bool               `json:"success"`
+	Databases []InstanceDatabase `json:"databases"`
+	Status    string             `json:"status"`
+}
+
+type AttachInstanceDatabasesRequest struct {
+	Filter        string   `json:"filter,omitempty"`
+	Exclude       []string `json:"exclude,omitempty"`
+	NamePrefix    string   `json:"name_prefix,omitempty"`
+	EnvironmentID string   `json:"environment_id,omitempty"`
+	Enabled       bool     `json:"enabled"`
+}
+
+// AttachInstanceDatabasesResponse wraps the API response for attaching the databases on an instance
+type AttachInstanceDatabasesResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Results []struct {
+		DBName       string `json:"db_name"`
+		DatabaseName string `json:"database_name"`
+		Success      bool   `json:"success"`
+		Message      string `json:"message"`
+	} `json:"results"`
+	AttachedCount int32  `json:"attached_count"`
+	SkippedCount  int32  `json:"skipped_count"`
+	FailedCount   int32  `json:"failed_count"`
+	Status        string `json:"status"`
+}
+
 // ListInstances lists all instances using profile-based authentication
 func ListInstances() error {
 	profileInfo, err := common.GetActiveProfileInfo()
@@ -537,6 +577,104 @@ func ReconnectInstance(instanceName string, _ []string) error {
 	return nil
 }
 
+// ListInstanceDatabases lists the databases on an instance, optionally those matching a glob
+func ListInstanceDatabases(instanceName, filter string) error {
+	instanceName = strings.TrimSpace(instanceName)
+	if instanceName == "" {
+		return fmt.Errorf("instance name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/instances/%s/databases", instanceName)
+	if filter != "" {
+		path += "?filter=" + url.QueryEscape(filter)
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	var response ListInstanceDatabasesResponse
+	if err := client.Get(apiURL, &response); err != nil {
+		return fmt.Errorf("failed to list instance databases: %v", err)
+	}
+
+	if len(response.Databases) == 0 {
+		fmt.Println("No databases found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+
+	fmt.Println()
+	fmt.Fprintln(w, "Database\tAttached\tAttached As")
+	fmt.Fprintln(w, "--------\t--------\t-----------")
+	for _, db := range response.Databases {
+		attached := "No"
+		if db.Attached {
+			attached = "Yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", db.DBName, attached, db.DatabaseName)
+	}
+
+	_ = w.Flush()
+	fmt.Println()
+	return nil
+}
+
+// AttachInstanceDatabases connects all databases on an instance, or those matching a glob, to
+// the workspace
+func AttachInstanceDatabases(instanceName string, request AttachInstanceDatabasesRequest) error {
+	instanceName = strings.TrimSpace(instanceName)
+	if instanceName == "" {
+		return fmt.Errorf("instance name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/instances/%s/attach-databases", instanceName))
+	if err != nil {
+		return err
+	}
+
+	var response AttachInstanceDatabasesResponse
+	if err := client.Post(apiURL, request, &response); err != nil {
+		return fmt.Errorf("failed to attach instance databases: %v", err)
+	}
+
+	for _, result := range response.Results {
+		if result.Success {
+			fmt.Printf("Attached database '%s' as '%s'\n", result.DBName, result.DatabaseName)
+		} else {
+			fmt.Printf("Failed to attach database '%s': %s\n", result.DBName, result.Message)
+		}
+	}
+
+	fmt.Printf("Attached %d databases of instance '%s', %d already attached, %d failed\n",
+		response.AttachedCount, instanceName, response.SkippedCount, response.FailedCount)
+	if response.FailedCount > 0 {
+		return fmt.Errorf("%d databases failed to attach", response.FailedCount)
+	}
+	return nil
+}
+
 // ModifyInstance updates an existing instance
 func ModifyInstance(instanceName string, args []string) error {
 	// Trim whitespace from instance name
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/chzyer/readline"
+	"github.com/redbco/redb-open/cmd/cli/internal/locale"
 	"github.com/redbco/redb-open/cmd/cli/internal/profile"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -72,13 +73,13 @@ func StartInteractiveMode(rootCmd *cobra.Command) error {
 
 		// Check for exit commands
 		if line == "exit" || line == "quit" {
-			fmt.Println("Goodbye!")
+			fmt.Println(locale.T("Goodbye!"))
 			break
 		}
 
 		// Execute command through Cobra
 		if err := parseAndExecuteCommand(line, rootCmd); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, locale.Sprintf("Error: %v", err))
 		}
 	}
 
@@ -0,0 +1,69 @@
+// Package locale localizes the messages of the CLI.
+package locale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/redbco/redb-open/pkg/i18n"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	translations = i18n.NewCatalog()
+	current      = i18n.DefaultLocale
+)
+
+// Init selects the locale of the CLI from REDB_LANG, LC_ALL, LC_MESSAGES or LANG, and loads
+// the translation bundles of ~/.redb/locales.
+func Init() error {
+	current = i18n.LocaleFromEnv("REDB_LANG", "LC_ALL", "LC_MESSAGES", "LANG")
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return translations.LoadDir(filepath.Join(homeDir, ".redb", "locales"))
+}
+
+// Current returns the locale of the CLI. It is the locale requested from the API, even if
+// the CLI has no bundle for it.
+func Current() string {
+	return current
+}
+
+// T returns the translation of a message
+func T(message string) string {
+	return translations.Translate(current, message)
+}
+
+// Sprintf formats the translation of a format string
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// LocalizeCommands translates the descriptions of a command, its subcommands and their flags
+func LocalizeCommands(cmd *cobra.Command) {
+	localizeCommands(cmd, make(map[*pflag.Flag]bool))
+}
+
+func localizeCommands(cmd *cobra.Command, seen map[*pflag.Flag]bool) {
+	cmd.Short = T(cmd.Short)
+	cmd.Long = T(cmd.Long)
+
+	// Persistent flags are shared with the subcommands; translate each flag once
+	localizeFlag := func(flag *pflag.Flag) {
+		if !seen[flag] {
+			seen[flag] = true
+			flag.Usage = T(flag.Usage)
+		}
+	}
+	cmd.Flags().VisitAll(localizeFlag)
+	cmd.PersistentFlags().VisitAll(localizeFlag)
+
+	for _, sub := range cmd.Commands() {
+		localizeCommands(sub, seen)
+	}
+}
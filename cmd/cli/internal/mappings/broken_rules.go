@@ -0,0 +1,134 @@
+package mappings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// BrokenMappingRule is a rule referencing tables or columns that no longer exist
+type BrokenMappingRule struct {
+	MappingRuleName string   `json:"mapping_rule_name"`
+	BrokenReason    string   `json:"broken_reason"`
+	MissingObjects  []string `json:"missing_objects"`
+	MappingCount    int32    `json:"mapping_count"`
+}
+
+// MappingRuleRepair is the current object suggested for a missing object of a broken rule
+type MappingRuleRepair struct {
+	Side            string   `json:"side"`
+	MissingURI      string   `json:"missing_uri"`
+	SuggestedURI    string   `json:"suggested_uri"`
+	SuggestedTable  string   `json:"suggested_table"`
+	SuggestedColumn string   `json:"suggested_column"`
+	Score           float64  `json:"score"`
+	Reasons         []string `json:"reasons"`
+}
+
+// ListBrokenMappingRules lists the rules of the workspace that runs skip because objects they
+// reference no longer exist
+func ListBrokenMappingRules() error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/mapping-rules/broken")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		MappingRules []BrokenMappingRule `json:"mapping_rules"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list broken mapping rules: %v", err)
+	}
+
+	if len(response.MappingRules) == 0 {
+		fmt.Println("No mapping rules of the workspace are broken")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-40s %-9s %s\n", "Rule Name", "Mappings", "Reason")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, rule := range response.MappingRules {
+		fmt.Printf("%-40s %-9d %s\n", rule.MappingRuleName, rule.MappingCount, rule.BrokenReason)
+	}
+	fmt.Println()
+	return nil
+}
+
+// RepairMappingRule shows the current objects closest to the missing objects of a broken rule,
+// re-pointing the rule to them with apply
+func RepairMappingRule(ruleName, matchProfile string, apply bool) error {
+	if ruleName == "" {
+		return fmt.Errorf("mapping rule name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mapping-rules/%s/repair", ruleName))
+	if err != nil {
+		return err
+	}
+
+	repairReq := struct {
+		MatchProfile string `json:"match_profile,omitempty"`
+		Apply        bool   `json:"apply,omitempty"`
+	}{
+		MatchProfile: matchProfile,
+		Apply:        apply,
+	}
+
+	var response struct {
+		Message  string              `json:"message"`
+		Success  bool                `json:"success"`
+		Repairs  []MappingRuleRepair `json:"repairs"`
+		Applied  bool                `json:"applied"`
+		Warnings []string            `json:"warnings"`
+	}
+	if err := client.Post(url, repairReq, &response); err != nil {
+		return fmt.Errorf("failed to repair mapping rule: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to repair mapping rule: %s", response.Message)
+	}
+
+	for _, repair := range response.Repairs {
+		fmt.Printf("  %s: %s\n", repair.Side, repair.MissingURI)
+		if repair.SuggestedURI == "" {
+			fmt.Println("    -> no suggestion")
+			continue
+		}
+		fmt.Printf("    -> %s (score: %.2f)\n", repair.SuggestedURI, repair.Score)
+		if len(repair.Reasons) > 0 {
+			fmt.Printf("       %s\n", strings.Join(repair.Reasons, ", "))
+		}
+	}
+	for _, warning := range response.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	if response.Applied {
+		fmt.Println(response.Message)
+	} else if len(response.Repairs) > 0 {
+		fmt.Printf("Run 'redb mappings repair-rule %s --apply' to re-point the rule\n", ruleName)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mappings
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// ExportMapping writes a mapping and its rules as a mapping document, in YAML or JSON, to a file,
+// or to the standard output when no file is given
+func ExportMapping(mappingName, format, outputPath string) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/mappings/%s/export", mappingName)
+	if format != "" {
+		path += "?format=" + url.QueryEscape(format)
+	}
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Document  string `json:"document"`
+		RuleCount int32  `json:"rule_count"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to export mapping: %v", err)
+	}
+
+	if outputPath == "" {
+		fmt.Print(response.Document)
+		return nil
+	}
+	if err := os.WriteFile(outputPath, []byte(response.Document), 0o644); err != nil {
+		return fmt.Errorf("failed to write mapping document: %v", err)
+	}
+	fmt.Printf("Exported mapping '%s' with %d rules to %s\n", mappingName, response.RuleCount, outputPath)
+	return nil
+}
+
+// ImportMapping creates a mapping and its rules from a mapping document file. The mapping name
+// replaces the name of the document, and the database names rename its databases to those of the
+// workspace; replace deletes a mapping of the same name, with its rules, first.
+func ImportMapping(path, mappingName string, databaseNames map[string]string, replace bool) error {
+	document, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read mapping document: %v", err)
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/mappings/import")
+	if err != nil {
+		return err
+	}
+
+	importReq := map[string]interface{}{
+		"document": string(document),
+		"replace":  replace,
+	}
+	if mappingName != "" {
+		importReq["mapping_name"] = mappingName
+	}
+	if len(databaseNames) > 0 {
+		importReq["database_names"] = databaseNames
+	}
+
+	var response struct {
+		Message string `json:"message"`
+		Success bool   `json:"success"`
+		Mapping struct {
+			MappingName string `json:"mapping_name"`
+		} `json:"mapping"`
+		RuleCount    int32             `json:"rule_count"`
+		Replaced     bool              `json:"replaced"`
+		RenamedRules map[string]string `json:"renamed_rules"`
+	}
+	if err := client.Post(url, importReq, &response); err != nil {
+		return fmt.Errorf("failed to import mapping: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to import mapping: %s", response.Message)
+	}
+
+	action := "Imported"
+	if response.Replaced {
+		action = "Replaced"
+	}
+	fmt.Printf("%s mapping '%s' with %d rules\n", action, response.Mapping.MappingName, response.RuleCount)
+	if len(response.RenamedRules) > 0 {
+		rules := make([]string, 0, len(response.RenamedRules))
+		for rule := range response.RenamedRules {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+		fmt.Println("Rules renamed, as rules of the workspace use their names:")
+		for _, rule := range rules {
+			fmt.Printf("  %s -> %s\n", rule, response.RenamedRules[rule])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package mappings
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// ruleImpactRequest proposes a change or the deletion of a mapping rule
+type ruleImpactRequest struct {
+	Delete                        bool   `json:"delete,omitempty"`
+	MappingRuleSource             string `json:"mapping_rule_source,omitempty"`
+	MappingRuleTarget             string `json:"mapping_rule_target,omitempty"`
+	MappingRuleTransformationName string `json:"mapping_rule_transformation_name,omitempty"`
+}
+
+// RuleImpact is what a proposed change or deletion of a mapping rule would affect
+type RuleImpact struct {
+	MappingRuleName  string   `json:"mapping_rule_name"`
+	Delete           bool     `json:"delete"`
+	Destructive      bool     `json:"destructive"`
+	ChangedFields    []string `json:"changed_fields"`
+	AffectedMappings []struct {
+		MappingName      string `json:"mapping_name"`
+		MappingRuleCount int32  `json:"mapping_rule_count"`
+	} `json:"affected_mappings"`
+	Runs []struct {
+		RelationshipName string `json:"relationship_name"`
+		MappingName      string `json:"mapping_name"`
+		Status           string `json:"status"`
+		Active           bool   `json:"active"`
+	} `json:"runs"`
+	DownstreamTargets []struct {
+		Kind  string `json:"kind"`
+		Name  string `json:"name"`
+		Via   string `json:"via"`
+		Depth int32  `json:"depth"`
+	} `json:"downstream_targets"`
+	FailingPolicies []struct {
+		PolicyName string `json:"policy_name"`
+		Reason     string `json:"reason"`
+	} `json:"failing_policies"`
+}
+
+// analyzeRuleImpact asks the server what a proposed change or deletion of a mapping rule would affect
+func analyzeRuleImpact(ruleName string, req ruleImpactRequest) (*RuleImpact, []string, error) {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mapping-rules/%s/impact", ruleName))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var response struct {
+		Impact   RuleImpact `json:"impact"`
+		Warnings []string   `json:"warnings"`
+	}
+	if err := client.Post(url, req, &response); err != nil {
+		return nil, nil, err
+	}
+	return &response.Impact, response.Warnings, nil
+}
+
+// confirmRuleChange shows the impact of a proposed change or deletion of a mapping rule and, when
+// the change is destructive, asks for confirmation unless yes is set. It returns whether to go on.
+func confirmRuleChange(ruleName string, req ruleImpactRequest, yes bool) (bool, error) {
+	impact, warnings, err := analyzeRuleImpact(ruleName, req)
+	if err != nil {
+		if !yes {
+			return false, fmt.Errorf("failed to analyze the impact of the change: %v (use --yes to apply it without the analysis)", err)
+		}
+		fmt.Printf("Warning: failed to analyze the impact of the change: %v\n", err)
+		return true, nil
+	}
+
+	printRuleImpact(impact, warnings)
+	if !impact.Destructive || yes {
+		return true, nil
+	}
+
+	action := "change"
+	if impact.Delete {
+		action = "delete"
+	}
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("Are you sure you want to %s mapping rule '%s'? (y/N): ", action, ruleName)
+	confirmation, _ := reader.ReadString('\n')
+	confirmation = strings.TrimSpace(strings.ToLower(confirmation))
+	if confirmation != "y" && confirmation != "yes" {
+		fmt.Println("Operation cancelled")
+		fmt.Println()
+		return false, nil
+	}
+	return true, nil
+}
+
+// printRuleImpact shows the impact of a proposed change or deletion of a mapping rule
+func printRuleImpact(impact *RuleImpact, warnings []string) {
+	fmt.Println()
+	if impact.Delete {
+		fmt.Printf("Impact of deleting mapping rule '%s':\n", impact.MappingRuleName)
+	} else {
+		fmt.Printf("Impact of changing the %s of mapping rule '%s':\n", strings.Join(impact.ChangedFields, ", "), impact.MappingRuleName)
+	}
+
+	fmt.Printf("\n  Affected mappings (%d):\n", len(impact.AffectedMappings))
+	for _, m := range impact.AffectedMappings {
+		fmt.Printf("    - %s (%d rules)\n", m.MappingName, m.MappingRuleCount)
+	}
+
+	fmt.Printf("\n  Relationships running them (%d):\n", len(impact.Runs))
+	for _, run := range impact.Runs {
+		state := "not running"
+		if run.Active {
+			state = "running"
+		}
+		fmt.Printf("    - %s via %s (%s, %s)\n", run.RelationshipName, run.MappingName, state, run.Status)
+	}
+
+	fmt.Printf("\n  Downstream targets (%d):\n", len(impact.DownstreamTargets))
+	for _, target := range impact.DownstreamTargets {
+		fmt.Printf("    - %s%s [%s via %s]\n", strings.Repeat("  ", int(target.Depth-1)), target.Name, strings.ReplaceAll(target.Kind, "_", " "), target.Via)
+	}
+
+	if len(impact.FailingPolicies) > 0 {
+		fmt.Printf("\n  Policies that would fail (%d):\n", len(impact.FailingPolicies))
+		for _, p := range impact.FailingPolicies {
+			if p.PolicyName != "" {
+				fmt.Printf("    - %s: %s\n", p.PolicyName, p.Reason)
+			} else {
+				fmt.Printf("    - %s\n", p.Reason)
+			}
+		}
+	}
+
+	for _, w := range warnings {
+		fmt.Printf("\nWarning: %s\n", w)
+	}
+	fmt.Println()
+}
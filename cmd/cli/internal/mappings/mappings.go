@@ -2,8 +2,10 @@ package mappings
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,14 +13,15 @@ import (
 )
 
 type MappingRuleMetadata struct {
-	GeneratedAt    string  `json:"generated_at"`
-	MatchScore     float64 `json:"match_score"`
-	MatchType      string  `json:"match_type"`
-	SourceColumn   string  `json:"source_column"`
-	SourceTable    string  `json:"source_table"`
-	TargetColumn   string  `json:"target_column"`
-	TargetTable    string  `json:"target_table"`
-	TypeCompatible bool    `json:"type_compatible"`
+	GeneratedAt    string   `json:"generated_at"`
+	MatchScore     float64  `json:"match_score"`
+	MatchType      string   `json:"match_type"`
+	MatchReasons   []string `json:"match_reasons,omitempty"`
+	SourceColumn   string   `json:"source_column"`
+	SourceTable    string   `json:"source_table"`
+	TargetColumn   string   `json:"target_column"`
+	TargetTable    string   `json:"target_table"`
+	TypeCompatible bool     `json:"type_compatible"`
 }
 
 type MappingRule struct {
@@ -31,6 +34,8 @@ type MappingRule struct {
 	MappingRuleTransformationID      string              `json:"mapping_rule_transformation_id"`
 	MappingRuleTransformationName    string              `json:"mapping_rule_transformation_name"`
 	MappingRuleTransformationOptions string              `json:"mapping_rule_transformation_options"`
+	MappingRuleRowFilter             string              `json:"mapping_rule_row_filter"`
+	RecommendedTransformation        string              `json:"recommended_transformation"`
 }
 
 type Mapping struct {
@@ -48,10 +53,14 @@ type Mapping struct {
 	ValidatedAt        string        `json:"validated_at"`
 	ValidationErrors   []string      `json:"validation_errors"`
 	ValidationWarnings []string      `json:"validation_warnings"`
+	RowFilter          string        `json:"row_filter"`
+	TablePins          []TablePin    `json:"table_pins"`
 }
 
-// AddMapping creates a new mapping with specified scope
-func AddMapping(scope, source, target, name, description, policyID string, clean bool) error {
+// AddMapping creates a new mapping with specified scope. A row filter restricts the source rows
+// the mapping writes to the target, and table pins fix the tables the matcher of a database
+// mapping pairs.
+func AddMapping(scope, source, target, name, description, policyID, matchProfile, rowFilter string, tablePins []TablePin, overrides *MatchOverrides, clean bool) error {
 	// Validate scope
 	if scope != "database" && scope != "table" {
 		return fmt.Errorf("invalid scope '%s': must be 'database' or 'table'", scope)
@@ -90,6 +99,10 @@ func AddMapping(scope, source, target, name, description, policyID string, clean
 		}
 	}
 
+	if len(tablePins) > 0 && (scope != "database" || isMCPTarget) {
+		return fmt.Errorf("table pins require a database scope mapping to a database")
+	}
+
 	// Generate name and description if not provided
 	if name == "" {
 		if isMCPTarget {
@@ -109,13 +122,17 @@ func AddMapping(scope, source, target, name, description, policyID string, clean
 
 	// Create the mapping request
 	mappingReq := struct {
-		MappingName        string `json:"mapping_name"`
-		MappingDescription string `json:"mapping_description"`
-		Scope              string `json:"scope"`
-		Source             string `json:"source"`
-		Target             string `json:"target"`
-		PolicyID           string `json:"policy_id,omitempty"`
-		GenerateRules      bool   `json:"generate_rules"`
+		MappingName        string          `json:"mapping_name"`
+		MappingDescription string          `json:"mapping_description"`
+		Scope              string          `json:"scope"`
+		Source             string          `json:"source"`
+		Target             string          `json:"target"`
+		PolicyID           string          `json:"policy_id,omitempty"`
+		GenerateRules      bool            `json:"generate_rules"`
+		MatchProfile       string          `json:"match_profile,omitempty"`
+		RowFilter          string          `json:"row_filter,omitempty"`
+		TablePins          []TablePin      `json:"table_pins,omitempty"`
+		MatchOverrides     *MatchOverrides `json:"match_overrides,omitempty"`
 	}{
 		MappingName:        name,
 		MappingDescription: description,
@@ -124,6 +141,10 @@ func AddMapping(scope, source, target, name, description, policyID string, clean
 		Target:             target,
 		PolicyID:           policyID,
 		GenerateRules:      !clean, // If clean is true, don't generate rules
+		MatchProfile:       matchProfile,
+		RowFilter:          rowFilter,
+		TablePins:          tablePins,
+		MatchOverrides:     overrides,
 	}
 
 	profileInfo, err := common.GetActiveProfileInfo()
@@ -142,16 +163,18 @@ func AddMapping(scope, source, target, name, description, policyID string, clean
 	}
 
 	var response struct {
-		Message string  `json:"message"`
-		Success bool    `json:"success"`
-		Mapping Mapping `json:"mapping"`
-		Status  string  `json:"status"`
+		Message     string       `json:"message"`
+		Success     bool         `json:"success"`
+		Mapping     Mapping      `json:"mapping"`
+		Status      string       `json:"status"`
+		MatchReport *MatchReport `json:"match_report"`
 	}
 	if err := client.Post(url, mappingReq, &response); err != nil {
 		return fmt.Errorf("failed to create mapping: %v", err)
 	}
 
 	fmt.Printf("Successfully created %s mapping '%s' (ID: %s)\n", scope, response.Mapping.MappingName, response.Mapping.MappingID)
+	printMatchSummary(response.MatchReport)
 	return nil
 }
 
@@ -375,8 +398,9 @@ func ListMappings() error {
 	return nil
 }
 
-// ShowMapping displays details of a specific mapping
-func ShowMapping(mappingName string) error {
+// ShowMapping displays details of a specific mapping. With explain, the reasons the matcher
+// proposed each generated rule are shown under it.
+func ShowMapping(mappingName string, explain bool) error {
 	mappingName = strings.TrimSpace(mappingName)
 	if mappingName == "" {
 		return fmt.Errorf("mapping name is required")
@@ -417,6 +441,12 @@ func ShowMapping(mappingName string) error {
 	if mapping.PolicyID != "" {
 		fmt.Printf("Policy ID:   %s\n", mapping.PolicyID)
 	}
+	if mapping.RowFilter != "" {
+		fmt.Printf("Row Filter:  %s\n", mapping.RowFilter)
+	}
+	for _, pin := range mapping.TablePins {
+		fmt.Printf("Table Pin:   %s\n", pin)
+	}
 
 	// Display validation information
 	if mapping.ValidatedAt != "" {
@@ -507,6 +537,14 @@ func ShowMapping(mappingName string) error {
 				targetCol,
 				transformName,
 				matchIndicator)
+			if rule.MappingRuleRowFilter != "" {
+				fmt.Printf("    where %s\n", rule.MappingRuleRowFilter)
+			}
+			if explain {
+				for _, reason := range rule.MappingRuleMetadata.MatchReasons {
+					fmt.Printf("    - %s\n", reason)
+				}
+			}
 		}
 		fmt.Println()
 	} else {
@@ -576,7 +614,10 @@ func generateMCPMappingDescription(scope, sourceDB, sourceTable, mcpResourceName
 }
 
 // CopyMappingData copies data from source to target using the specified mapping
-func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRun, progress bool) error {
+// Vectors copied into vector stores are validated unless validateVectors is false, and edges
+// copied from graph databases verified unless verifyGraph is false. The parallel workers are
+// adjusted to the throughput and errors observed unless adaptiveParallelism is false.
+func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, adaptiveParallelism, dryRun, sandbox bool, sampleSize int32, validateVectors bool, vectorSamples int32, verifyGraph bool, edgeSamples int32, progress bool) error {
 	mappingName = strings.TrimSpace(mappingName)
 	if mappingName == "" {
 		return fmt.Errorf("mapping name is required")
@@ -610,11 +651,25 @@ func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRu
 		BatchSize       int32 `json:"batch_size"`
 		ParallelWorkers int32 `json:"parallel_workers"`
 		DryRun          bool  `json:"dry_run"`
+		Sandbox         bool  `json:"sandbox"`
+		SampleSize      int32 `json:"sample_size"`
+		ValidateVectors bool  `json:"validate_vectors"`
+		VectorSamples   int32 `json:"vector_samples"`
+		VerifyGraph     bool  `json:"verify_graph"`
+		EdgeSamples     int32 `json:"graph_edge_samples"`
+		Adaptive        bool  `json:"adaptive_parallelism"`
 		Progress        bool  `json:"progress"`
 	}{
 		BatchSize:       batchSize,
 		ParallelWorkers: parallelWorkers,
 		DryRun:          dryRun,
+		Sandbox:         sandbox,
+		SampleSize:      sampleSize,
+		ValidateVectors: validateVectors,
+		VectorSamples:   vectorSamples,
+		VerifyGraph:     verifyGraph,
+		EdgeSamples:     edgeSamples,
+		Adaptive:        adaptiveParallelism,
 		Progress:        progress,
 	}
 
@@ -622,20 +677,33 @@ func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRu
 	if dryRun {
 		fmt.Println("DRY RUN MODE: No data will be actually copied")
 	}
-	fmt.Printf("Configuration: batch_size=%d, parallel_workers=%d\n", batchSize, parallelWorkers)
+	if sandbox {
+		fmt.Println("SANDBOX MODE: Data is copied into temporary copies of the target tables, which are dropped afterwards")
+	}
+	fmt.Printf("Configuration: batch_size=%d, parallel_workers=%d, adaptive_parallelism=%t\n", batchSize, parallelWorkers, adaptiveParallelism)
 	fmt.Println()
 
 	// For now, make a simple POST request. In the future, this should be a streaming request
 	// to handle real-time progress updates
 	var response struct {
-		Message       string   `json:"message"`
-		Success       bool     `json:"success"`
-		Status        string   `json:"status"`
-		RowsProcessed int64    `json:"rows_processed"`
-		TotalRows     int64    `json:"total_rows"`
-		CurrentTable  string   `json:"current_table"`
-		Errors        []string `json:"errors"`
-		OperationID   string   `json:"operation_id"`
+		Message        string                       `json:"message"`
+		Success        bool                         `json:"success"`
+		Status         string                       `json:"status"`
+		RowsProcessed  int64                        `json:"rows_processed"`
+		TotalRows      int64                        `json:"total_rows"`
+		CurrentTable   string                       `json:"current_table"`
+		Errors         []string                     `json:"errors"`
+		OperationID    string                       `json:"operation_id"`
+		Sandbox        string                       `json:"sandbox"`
+		SandboxSamples map[string][]json.RawMessage `json:"sandbox_samples"`
+		RowsRejected   int64                        `json:"rows_rejected"`
+		DeadLetterIDs  []string                     `json:"dead_letter_artifact_ids"`
+		Validations    []vectorValidation           `json:"vector_validations"`
+		Verifications  []graphVerification          `json:"graph_verifications"`
+		StageTimings   *stageTimings                `json:"stage_timings"`
+		TableTimings   []stageTimings               `json:"table_pair_timings"`
+		Parallelism    int32                        `json:"parallelism"`
+		Decisions      []parallelismDecision        `json:"parallelism_decisions"`
 	}
 
 	if err := client.Post(url, copyDataReq, &response); err != nil {
@@ -644,6 +712,8 @@ func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRu
 
 	if !response.Success {
 		fmt.Printf("Data copy failed: %s\n", response.Message)
+		printVectorValidations(response.Validations)
+		printGraphVerifications(response.Verifications)
 		if len(response.Errors) > 0 {
 			fmt.Println("Errors:")
 			for _, errMsg := range response.Errors {
@@ -661,6 +731,32 @@ func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRu
 	if response.CurrentTable != "" {
 		fmt.Printf("Last table processed: %s\n", response.CurrentTable)
 	}
+	if response.RowsRejected > 0 {
+		fmt.Printf("Rows rejected by the target: %d\n", response.RowsRejected)
+		if len(response.DeadLetterIDs) > 0 {
+			fmt.Printf("Rejected rows and their errors are in the dead-letter artifacts: %s\n", strings.Join(response.DeadLetterIDs, ", "))
+		}
+	}
+
+	printVectorValidations(response.Validations)
+	printGraphVerifications(response.Verifications)
+	printStageTimings(response.StageTimings, response.TableTimings)
+	printParallelismDecisions(response.Parallelism, response.Decisions)
+
+	if response.Sandbox != "" {
+		fmt.Printf("\nRows written into sandbox %s:\n", response.Sandbox)
+		tables := make([]string, 0, len(response.SandboxSamples))
+		for table := range response.SandboxSamples {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			fmt.Printf("  %s\n", table)
+			for _, row := range response.SandboxSamples[table] {
+				fmt.Printf("    %s\n", row)
+			}
+		}
+	}
 
 	if len(response.Errors) > 0 {
 		fmt.Println("\nWarnings/Non-fatal errors:")
@@ -672,8 +768,144 @@ func CopyMappingData(mappingName string, batchSize, parallelWorkers int32, dryRu
 	return nil
 }
 
-// ModifyMappingRule modifies an existing mapping rule
-func ModifyMappingRule(mappingName, ruleName, source, target, transformation string, order int32) error {
+// vectorValidation is the validation of the vectors a copy wrote into a vector store
+type vectorValidation struct {
+	TablePair      string   `json:"table_pair"`
+	Samples        int32    `json:"samples"`
+	TopK           int32    `json:"top_k"`
+	Dimensions     []int32  `json:"dimensions"`
+	SelfMatchRate  float64  `json:"self_match_rate"`
+	SourceCompared bool     `json:"source_compared"`
+	Recall         float64  `json:"recall"`
+	MinRecall      float64  `json:"min_recall"`
+	FailedSearches int32    `json:"failed_searches"`
+	Issues         []string `json:"issues"`
+	Passed         bool     `json:"passed"`
+}
+
+func printVectorValidations(validations []vectorValidation) {
+	if len(validations) == 0 {
+		return
+	}
+	fmt.Println("\nVector validation:")
+	for _, v := range validations {
+		result := "passed"
+		if !v.Passed {
+			result = "FAILED"
+		}
+		dimensions := make([]string, len(v.Dimensions))
+		for i, dimension := range v.Dimensions {
+			dimensions[i] = fmt.Sprint(dimension)
+		}
+		fmt.Printf("  %s: %s (%d vectors of dimension %s)\n", v.TablePair, result, v.Samples, strings.Join(dimensions, ", "))
+		fmt.Printf("    Self match rate: %.0f%%\n", v.SelfMatchRate*100)
+		if v.SourceCompared {
+			fmt.Printf("    Recall@%d against the source: %.0f%% (lowest %.0f%%)\n", v.TopK, v.Recall*100, v.MinRecall*100)
+		}
+		for _, issue := range v.Issues {
+			fmt.Printf("    - %s\n", issue)
+		}
+	}
+}
+
+// stageTimings is the time a copy spent in each stage of processing its batches
+type stageTimings struct {
+	TablePair       string  `json:"table_pair"`
+	Batches         int64   `json:"batches"`
+	FetchMs         int64   `json:"fetch_ms"`
+	TransformMs     int64   `json:"transform_ms"`
+	NetworkMs       int64   `json:"network_ms"`
+	WriteMs         int64   `json:"write_ms"`
+	Bottleneck      string  `json:"bottleneck"`
+	BottleneckShare float64 `json:"bottleneck_share"`
+	Summary         string  `json:"summary"`
+}
+
+func printStageTimings(total *stageTimings, tablePairs []stageTimings) {
+	if total == nil {
+		return
+	}
+	fmt.Println("\nStage timings:")
+	printTimings := func(label string, t stageTimings) {
+		fmt.Printf("  %s: %d batches, fetch %dms, transform %dms, network %dms, write %dms\n",
+			label, t.Batches, t.FetchMs, t.TransformMs, t.NetworkMs, t.WriteMs)
+	}
+	printTimings("Total", *total)
+	if len(tablePairs) > 1 {
+		for _, t := range tablePairs {
+			printTimings(t.TablePair, t)
+		}
+	}
+	if total.Summary != "" {
+		fmt.Printf("  Bottleneck: %s\n", total.Summary)
+	}
+}
+
+// parallelismDecision is a change of the parallel workers of a copy
+type parallelismDecision struct {
+	TablePair     string  `json:"table_pair"`
+	DecidedAt     string  `json:"decided_at"`
+	From          int32   `json:"from"`
+	To            int32   `json:"to"`
+	RowsPerSecond float64 `json:"rows_per_second"`
+	ErrorRate     float64 `json:"error_rate"`
+	Reason        string  `json:"reason"`
+}
+
+func printParallelismDecisions(parallelism int32, decisions []parallelismDecision) {
+	if len(decisions) == 0 {
+		return
+	}
+	fmt.Printf("\nParallel workers adjusted %d times, ending at %d:\n", len(decisions), parallelism)
+	for _, d := range decisions {
+		fmt.Printf("  %s %s: %d -> %d (%s)\n", d.DecidedAt, d.TablePair, d.From, d.To, d.Reason)
+	}
+}
+
+// graphVerification is the verification of the edges a copy read from a graph database
+type graphVerification struct {
+	TablePair           string   `json:"table_pair"`
+	SourceEdges         int32    `json:"source_edges"`
+	TargetEdges         int32    `json:"target_edges"`
+	SourceNodes         int32    `json:"source_nodes"`
+	TargetNodes         int32    `json:"target_nodes"`
+	Complete            bool     `json:"complete"`
+	OutDegreeDivergence float64  `json:"out_degree_divergence"`
+	InDegreeDivergence  float64  `json:"in_degree_divergence"`
+	PathDivergence      float64  `json:"path_divergence"`
+	Issues              []string `json:"issues"`
+	Passed              bool     `json:"passed"`
+}
+
+func printGraphVerifications(verifications []graphVerification) {
+	if len(verifications) == 0 {
+		return
+	}
+	fmt.Println("\nGraph verification:")
+	for _, v := range verifications {
+		result := "passed"
+		if !v.Passed {
+			result = "FAILED"
+		}
+		sample := "sampled"
+		if v.Complete {
+			sample = "all"
+		}
+		fmt.Printf("  %s: %s (%s edges: %d between %d nodes in the source, %d between %d in the target)\n",
+			v.TablePair, result, sample, v.SourceEdges, v.SourceNodes, v.TargetEdges, v.TargetNodes)
+		fmt.Printf("    Degree divergence: %.3f outgoing, %.3f incoming\n", v.OutDegreeDivergence, v.InDegreeDivergence)
+		fmt.Printf("    Path divergence: %.3f\n", v.PathDivergence)
+		for _, issue := range v.Issues {
+			fmt.Printf("    - %s\n", issue)
+		}
+	}
+}
+
+// ModifyMappingRule modifies an existing mapping rule. A chain of transformations replaces the
+// transformation of the rule, and a row filter, when set, replaces that of the rule ("" removes
+// it). Changes of the source, target or transformation are confirmed after showing their impact,
+// unless yes is set.
+func ModifyMappingRule(mappingName, ruleName, source, target, transformation string, chain []string, rowFilter *string, order int32, yes bool) error {
 	if mappingName == "" {
 		return fmt.Errorf("mapping name is required")
 	}
@@ -682,8 +914,8 @@ func ModifyMappingRule(mappingName, ruleName, source, target, transformation str
 	}
 
 	// At least one modification parameter must be provided
-	if source == "" && target == "" && transformation == "" && order == -1 {
-		return fmt.Errorf("at least one modification parameter must be provided (source, target, transformation, or order)")
+	if source == "" && target == "" && transformation == "" && len(chain) == 0 && rowFilter == nil && order == -1 {
+		return fmt.Errorf("at least one modification parameter must be provided (source, target, transformation, chain, filter, or order)")
 	}
 
 	profileInfo, err := common.GetActiveProfileInfo()
@@ -701,13 +933,36 @@ func ModifyMappingRule(mappingName, ruleName, source, target, transformation str
 		return err
 	}
 
+	// A transformation chain is stored as the name of its steps joined with "|"
+	proposedTransformation := transformation
+	if len(chain) > 0 {
+		proposedTransformation = strings.Join(chain, "|")
+	}
+
+	// The order only places the rule within the mapping
+	if source != "" || target != "" || proposedTransformation != "" {
+		proceed, err := confirmRuleChange(ruleName, ruleImpactRequest{
+			MappingRuleSource:             source,
+			MappingRuleTarget:             target,
+			MappingRuleTransformationName: proposedTransformation,
+		}, yes)
+		if err != nil || !proceed {
+			return err
+		}
+	}
+
 	// Build the request
 	modifyReq := struct {
-		Source         *string `json:"source,omitempty"`
-		Target         *string `json:"target,omitempty"`
-		Transformation *string `json:"transformation,omitempty"`
-		Order          *int32  `json:"order,omitempty"`
-	}{}
+		Source              *string  `json:"source,omitempty"`
+		Target              *string  `json:"target,omitempty"`
+		Transformation      *string  `json:"transformation,omitempty"`
+		TransformationChain []string `json:"transformation_chain,omitempty"`
+		RowFilter           *string  `json:"row_filter,omitempty"`
+		Order               *int32   `json:"order,omitempty"`
+	}{
+		TransformationChain: chain,
+		RowFilter:           rowFilter,
+	}
 
 	if source != "" {
 		modifyReq.Source = &source
@@ -741,23 +996,29 @@ func ModifyMappingRule(mappingName, ruleName, source, target, transformation str
 	return nil
 }
 
-// AddMappingRule creates a new mapping rule and attaches it to a mapping
-func AddMappingRule(mappingName, ruleName, source, target, transformation string, order int32) error {
+// AddMappingRule creates a new mapping rule and attaches it to a mapping. A chain of
+// transformations, applied in order, replaces the single transformation. With a row filter, the
+// rule maps only the source rows matching it. Several sources make a many-to-one rule and
+// several targets a one-to-many rule; options are the JSON options of the transformation.
+func AddMappingRule(mappingName, ruleName string, sources, targets []string, transformation string, chain []string, options, rowFilter string, order int32) error {
 	if mappingName == "" {
 		return fmt.Errorf("mapping name is required")
 	}
 	if ruleName == "" {
 		return fmt.Errorf("rule name is required")
 	}
-	if source == "" {
+	if len(sources) == 0 {
 		return fmt.Errorf("source column is required")
 	}
-	if target == "" {
+	if len(targets) == 0 {
 		return fmt.Errorf("target column is required")
 	}
-	if transformation == "" {
+	if transformation == "" && len(chain) == 0 {
 		transformation = "direct_mapping"
 	}
+	if options != "" && !json.Valid([]byte(options)) {
+		return fmt.Errorf("transformation options must be valid JSON")
+	}
 
 	profileInfo, err := common.GetActiveProfileInfo()
 	if err != nil {
@@ -776,16 +1037,22 @@ func AddMappingRule(mappingName, ruleName, source, target, transformation string
 
 	// Build the request
 	addReq := struct {
-		RuleName       string `json:"rule_name"`
-		Source         string `json:"source"`
-		Target         string `json:"target"`
-		Transformation string `json:"transformation"`
-		Order          *int32 `json:"order,omitempty"`
+		RuleName              string   `json:"rule_name"`
+		Sources               []string `json:"sources"`
+		Targets               []string `json:"targets"`
+		Transformation        string   `json:"transformation,omitempty"`
+		TransformationChain   []string `json:"transformation_chain,omitempty"`
+		TransformationOptions string   `json:"transformation_options,omitempty"`
+		RowFilter             string   `json:"row_filter,omitempty"`
+		Order                 *int32   `json:"order,omitempty"`
 	}{
-		RuleName:       ruleName,
-		Source:         source,
-		Target:         target,
-		Transformation: transformation,
+		RuleName:              ruleName,
+		Sources:               sources,
+		Targets:               targets,
+		Transformation:        transformation,
+		TransformationChain:   chain,
+		TransformationOptions: options,
+		RowFilter:             rowFilter,
 	}
 
 	if order >= 0 {
@@ -811,8 +1078,9 @@ func AddMappingRule(mappingName, ruleName, source, target, transformation string
 	return nil
 }
 
-// RemoveMappingRule removes a mapping rule from a mapping
-func RemoveMappingRule(mappingName, ruleName string, deleteRule bool) error {
+// RemoveMappingRule removes a mapping rule from a mapping. Deleting the rule is confirmed after
+// showing its impact, unless yes is set.
+func RemoveMappingRule(mappingName, ruleName string, deleteRule, yes bool) error {
 	if mappingName == "" {
 		return fmt.Errorf("mapping name is required")
 	}
@@ -837,6 +1105,10 @@ func RemoveMappingRule(mappingName, ruleName string, deleteRule bool) error {
 
 	// Add delete query parameter if requested
 	if deleteRule {
+		proceed, err := confirmRuleChange(ruleName, ruleImpactRequest{Delete: true}, yes)
+		if err != nil || !proceed {
+			return err
+		}
 		url += "?delete=true"
 	}
 
@@ -852,6 +1124,55 @@ func RemoveMappingRule(mappingName, ruleName string, deleteRule bool) error {
 	return nil
 }
 
+// SetMappingRowFilter replaces the row filter of a mapping, the condition source rows must match
+// to be written to the target. An empty filter removes it.
+func SetMappingRowFilter(mappingName, rowFilter string) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s", mappingName))
+	if err != nil {
+		return err
+	}
+
+	modifyReq := struct {
+		RowFilter *string `json:"row_filter"`
+	}{
+		RowFilter: &rowFilter,
+	}
+
+	var response struct {
+		Message string  `json:"message"`
+		Success bool    `json:"success"`
+		Mapping Mapping `json:"mapping"`
+		Status  string  `json:"status"`
+	}
+	if err := client.Put(url, modifyReq, &response); err != nil {
+		return fmt.Errorf("failed to set row filter: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to set row filter: %s", response.Message)
+	}
+
+	if rowFilter == "" {
+		fmt.Printf("Removed the row filter of mapping '%s'\n", mappingName)
+	} else {
+		fmt.Printf("Mapping '%s' now writes only the rows where %s\n", mappingName, response.Mapping.RowFilter)
+	}
+	return nil
+}
+
 // RemoveMapping removes a mapping and optionally deletes associated rules
 func RemoveMapping(mappingName string, keepRules bool) error {
 	if mappingName == "" {
@@ -956,6 +1277,13 @@ func ListMappingRules(mappingName string) error {
 	}
 	fmt.Println()
 
+	// Rules copying privileged data unchanged are recommended a masking transformation
+	for _, rule := range response.Rules {
+		if rule.RecommendedTransformation != "" {
+			fmt.Printf("Rule '%s' copies privileged data unchanged, recommended transformation: %s\n", rule.MappingRuleName, rule.RecommendedTransformation)
+		}
+	}
+
 	return nil
 }
 
@@ -1067,6 +1395,36 @@ func ValidateMapping(mappingName string) error {
 
 	fmt.Println("╚═══════════════════════════════════════════════════════════════╝")
 
+	// Show the checks of each rule
+	ruleValidations, _ := data["rule_validations"].([]interface{})
+	if len(ruleValidations) > 0 {
+		fmt.Println("\nRules:")
+	}
+	for _, raw := range ruleValidations {
+		validation, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ruleName, _ := validation["mapping_rule_name"].(string)
+		cardinality, _ := validation["cardinality"].(string)
+		transformationName, _ := validation["transformation_name"].(string)
+		valid, _ := validation["valid"].(bool)
+		mark := "✓"
+		if !valid {
+			mark = "✗"
+		}
+		if transformationName == "" {
+			transformationName = "direct"
+		}
+		fmt.Printf("  %s %s (%s, %s)\n", mark, ruleName, cardinality, transformationName)
+		issues, _ := validation["issues"].([]interface{})
+		printValidationIssues(issues, "      ")
+	}
+	if mappingIssues, _ := data["mapping_issues"].([]interface{}); len(mappingIssues) > 0 {
+		fmt.Println("\nMapping:")
+		printValidationIssues(mappingIssues, "  ")
+	}
+
 	if !isValid {
 		// Exit with error code but don't return error to avoid showing usage
 		os.Exit(1)
@@ -1075,6 +1433,97 @@ func ValidateMapping(mappingName string) error {
 	return nil
 }
 
+// printValidationIssues prints the issues found by the checks of a mapping validation
+func printValidationIssues(issues []interface{}, indent string) {
+	for _, raw := range issues {
+		issue, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		check, _ := issue["check"].(string)
+		severity, _ := issue["severity"].(string)
+		message, _ := issue["message"].(string)
+		fmt.Printf("%s%s [%s] %s\n", indent, severity, check, message)
+	}
+}
+
+// IndexRecommendations shows the indexes recommended to support the lookups of a mapping
+// and creates the recommended indexes named in create
+func IndexRecommendations(mappingName string, create []string) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/index-recommendations", mappingName))
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Recommendations []struct {
+			IndexName    string   `json:"index_name"`
+			Side         string   `json:"side"`
+			DatabaseName string   `json:"database_name"`
+			TableName    string   `json:"table_name"`
+			Columns      []string `json:"columns"`
+			Unique       bool     `json:"unique"`
+			Reason       string   `json:"reason"`
+			Statement    string   `json:"statement"`
+			Created      bool     `json:"created"`
+			Error        string   `json:"error"`
+		} `json:"recommendations"`
+		Warnings []string `json:"warnings"`
+	}
+
+	if len(create) > 0 {
+		fmt.Printf("Creating %d indexes for mapping '%s'...\n", len(create), mappingName)
+		req := map[string]interface{}{"index_names": create}
+		if err := client.Post(url, req, &response); err != nil {
+			return fmt.Errorf("failed to create mapping indexes: %v", err)
+		}
+	} else if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to get index recommendations: %v", err)
+	}
+
+	if len(response.Recommendations) == 0 {
+		fmt.Println("No missing indexes found.")
+	}
+	for _, rec := range response.Recommendations {
+		kind := "index"
+		if rec.Unique {
+			kind = "unique index"
+		}
+		fmt.Println()
+		fmt.Printf("%s (%s, %s)\n", rec.IndexName, rec.Side, kind)
+		fmt.Printf("  Table:   %s.%s (%s)\n", rec.DatabaseName, rec.TableName, strings.Join(rec.Columns, ", "))
+		fmt.Printf("  Reason:  %s\n", rec.Reason)
+		if rec.Statement != "" {
+			fmt.Printf("  DDL:     %s\n", rec.Statement)
+		}
+		switch {
+		case rec.Created:
+			fmt.Println("  Status:  created")
+		case rec.Error != "":
+			fmt.Printf("  Status:  failed: %s\n", rec.Error)
+		}
+	}
+	for _, w := range response.Warnings {
+		fmt.Printf("\nWarning: %s\n", w)
+	}
+	fmt.Println()
+
+	if len(create) == 0 && len(response.Recommendations) > 0 {
+		fmt.Printf("Create indexes with: redb mappings index-recommendations %s --create <index_name>[,<index_name>...]\n", mappingName)
+	}
+	return nil
+}
+
 // wrapText wraps text to specified width
 func wrapText(text string, width int) []string {
 	words := strings.Fields(text)
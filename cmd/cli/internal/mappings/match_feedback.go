@@ -0,0 +1,98 @@
+package mappings
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// MatchFeedback is what the matcher of the workspace learned from corrections of the rules it
+// generated for the match of two columns
+type MatchFeedback struct {
+	SourceColumn string  `json:"source_column"`
+	TargetColumn string  `json:"target_column"`
+	Accepted     int32   `json:"accepted"`
+	Rejected     int32   `json:"rejected"`
+	Adjustment   float64 `json:"adjustment"`
+	Updated      string  `json:"updated"`
+}
+
+// ListMatchFeedback lists the column matches users of the workspace corrected
+func ListMatchFeedback() error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/match-feedback")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Feedback []MatchFeedback `json:"feedback"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list match feedback: %v", err)
+	}
+
+	if len(response.Feedback) == 0 {
+		fmt.Println("No generated rules of the workspace have been corrected")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-30s %-30s %-9s %-9s %-11s %-20s\n", "Source Column", "Target Column", "Accepted", "Rejected", "Adjustment", "Updated")
+	fmt.Println(strings.Repeat("-", 114))
+	for _, f := range response.Feedback {
+		fmt.Printf("%-30s %-30s %-9d %-9d %+-11.2f %-20s\n",
+			f.SourceColumn, f.TargetColumn, f.Accepted, f.Rejected, f.Adjustment, f.Updated)
+	}
+	fmt.Println()
+	return nil
+}
+
+// ResetMatchFeedback forgets what the matcher of the workspace learned: all of it, or the
+// feedback on the matches of a source and/or a target column
+func ResetMatchFeedback(sourceColumn, targetColumn string) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if sourceColumn = strings.TrimSpace(sourceColumn); sourceColumn != "" {
+		query.Set("source_column", sourceColumn)
+	}
+	if targetColumn = strings.TrimSpace(targetColumn); targetColumn != "" {
+		query.Set("target_column", targetColumn)
+	}
+	path := "/match-feedback"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to reset match feedback: %v", err)
+	}
+
+	fmt.Println("Successfully reset the match feedback of the workspace")
+	return nil
+}
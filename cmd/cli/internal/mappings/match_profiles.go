@@ -0,0 +1,201 @@
+package mappings
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// MatchProfile is a set of matcher options new mappings of a workspace can be created with
+type MatchProfile struct {
+	ProfileName              string  `json:"profile_name"`
+	ProfileDescription       string  `json:"profile_description,omitempty"`
+	NameSimilarityThreshold  float64 `json:"name_similarity_threshold"`
+	PoorMatchThreshold       float64 `json:"poor_match_threshold"`
+	NameWeight               float64 `json:"name_weight"`
+	TypeWeight               float64 `json:"type_weight"`
+	ClassificationWeight     float64 `json:"classification_weight"`
+	PrivilegedDataWeight     float64 `json:"privileged_data_weight"`
+	TableStructureWeight     float64 `json:"table_structure_weight"`
+	EnableCrossTableMatching bool    `json:"enable_cross_table_matching"`
+	MinRuleScore             float64 `json:"min_rule_score"`
+	BuiltIn                  bool    `json:"built_in"`
+	IsDefault                bool    `json:"is_default"`
+	Updated                  string  `json:"updated,omitempty"`
+}
+
+// MatchProfileChanges are the options of a match profile to set; nil options keep those of the
+// profile it is based on
+type MatchProfileChanges struct {
+	ProfileDescription       *string  `json:"profile_description,omitempty"`
+	BasedOn                  *string  `json:"based_on,omitempty"`
+	NameSimilarityThreshold  *float64 `json:"name_similarity_threshold,omitempty"`
+	PoorMatchThreshold       *float64 `json:"poor_match_threshold,omitempty"`
+	NameWeight               *float64 `json:"name_weight,omitempty"`
+	TypeWeight               *float64 `json:"type_weight,omitempty"`
+	ClassificationWeight     *float64 `json:"classification_weight,omitempty"`
+	PrivilegedDataWeight     *float64 `json:"privileged_data_weight,omitempty"`
+	TableStructureWeight     *float64 `json:"table_structure_weight,omitempty"`
+	EnableCrossTableMatching *bool    `json:"enable_cross_table_matching,omitempty"`
+	MinRuleScore             *float64 `json:"min_rule_score,omitempty"`
+}
+
+// ListMatchProfiles lists the built-in match profiles and the match profiles of the workspace
+func ListMatchProfiles() error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/match-profiles")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Profiles []MatchProfile `json:"profiles"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list match profiles: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("%-20s %-9s %-8s %-8s %-8s %-8s %-8s %-8s %-8s %-6s %-9s\n",
+		"Name", "Default", "NameSim", "Poor", "Name", "Type", "Class", "Priv", "Struct", "Cross", "MinScore")
+	fmt.Println(strings.Repeat("-", 106))
+	for _, p := range response.Profiles {
+		name := p.ProfileName
+		if p.BuiltIn {
+			name += " *"
+		}
+		isDefault := ""
+		if p.IsDefault {
+			isDefault = "yes"
+		}
+		cross := "no"
+		if p.EnableCrossTableMatching {
+			cross = "yes"
+		}
+		fmt.Printf("%-20s %-9s %-8.2f %-8.2f %-8.2f %-8.2f %-8.2f %-8.2f %-8.2f %-6s %-9.2f\n",
+			name, isDefault, p.NameSimilarityThreshold, p.PoorMatchThreshold, p.NameWeight, p.TypeWeight,
+			p.ClassificationWeight, p.PrivilegedDataWeight, p.TableStructureWeight, cross, p.MinRuleScore)
+	}
+	fmt.Println()
+	fmt.Println("* built-in profile")
+	fmt.Println()
+	return nil
+}
+
+// SetMatchProfile creates or replaces a match profile of the workspace
+func SetMatchProfile(profileName string, changes MatchProfileChanges) error {
+	profileName = strings.TrimSpace(profileName)
+	if profileName == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/match-profiles/%s", url.PathEscape(profileName)))
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Message string       `json:"message"`
+		Success bool         `json:"success"`
+		Profile MatchProfile `json:"profile"`
+		Status  string       `json:"status"`
+	}
+	if err := client.Put(url, changes, &response); err != nil {
+		return fmt.Errorf("failed to set match profile: %v", err)
+	}
+
+	p := response.Profile
+	fmt.Printf("Successfully set match profile '%s'\n", p.ProfileName)
+	fmt.Printf("  Name similarity threshold: %.2f\n", p.NameSimilarityThreshold)
+	fmt.Printf("  Poor match threshold:      %.2f\n", p.PoorMatchThreshold)
+	fmt.Printf("  Weights:                   name %.2f, type %.2f, classification %.2f, privileged data %.2f, table structure %.2f\n",
+		p.NameWeight, p.TypeWeight, p.ClassificationWeight, p.PrivilegedDataWeight, p.TableStructureWeight)
+	fmt.Printf("  Cross-table matching:      %t\n", p.EnableCrossTableMatching)
+	fmt.Printf("  Minimum rule score:        %.2f\n", p.MinRuleScore)
+	return nil
+}
+
+// DeleteMatchProfile deletes a match profile of the workspace
+func DeleteMatchProfile(profileName string) error {
+	profileName = strings.TrimSpace(profileName)
+	if profileName == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/match-profiles/%s", url.PathEscape(profileName)))
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to delete match profile: %v", err)
+	}
+
+	fmt.Printf("Successfully deleted match profile '%s'\n", profileName)
+	return nil
+}
+
+// SetDefaultMatchProfile sets the match profile new mappings of the workspace use by default; an
+// empty profile name clears it
+func SetDefaultMatchProfile(profileName string) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/default-match-profile")
+	if err != nil {
+		return err
+	}
+
+	req := struct {
+		ProfileName string `json:"profile_name"`
+	}{ProfileName: strings.TrimSpace(profileName)}
+	var response struct {
+		Message string `json:"message"`
+		Success bool   `json:"success"`
+		Status  string `json:"status"`
+	}
+	if err := client.Put(url, req, &response); err != nil {
+		return fmt.Errorf("failed to set the default match profile: %v", err)
+	}
+
+	fmt.Println(response.Message)
+	return nil
+}
@@ -0,0 +1,163 @@
+package mappings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// MatchOverrides replace options of the match profile for a single run of the matcher; nil
+// options keep those of the profile
+type MatchOverrides struct {
+	NameSimilarityThreshold  *float64 `json:"name_similarity_threshold,omitempty"`
+	PoorMatchThreshold       *float64 `json:"poor_match_threshold,omitempty"`
+	NameWeight               *float64 `json:"name_weight,omitempty"`
+	TypeWeight               *float64 `json:"type_weight,omitempty"`
+	ClassificationWeight     *float64 `json:"classification_weight,omitempty"`
+	PrivilegedDataWeight     *float64 `json:"privileged_data_weight,omitempty"`
+	TableStructureWeight     *float64 `json:"table_structure_weight,omitempty"`
+	EnableCrossTableMatching *bool    `json:"enable_cross_table_matching,omitempty"`
+	MinRuleScore             *float64 `json:"min_rule_score,omitempty"`
+}
+
+// MatchReport is the outcome of the last run of the matcher generating the rules of a mapping
+type MatchReport struct {
+	MappingName      string              `json:"mapping_name"`
+	Options          MatchProfile        `json:"options"`
+	Overridden       []string            `json:"overridden"`
+	GeneratedAt      string              `json:"generated_at"`
+	OverallScore     float64             `json:"overall_score"`
+	Tables           []MatchReportTable  `json:"tables"`
+	UnmatchedColumns []MatchReportColumn `json:"unmatched_columns"`
+	Warnings         []string            `json:"warnings"`
+	Outcomes         map[string]int32    `json:"outcomes"`
+	RulesCreated     int32               `json:"rules_created"`
+}
+
+// MatchReportTable is the match of a source table with a target table
+type MatchReportTable struct {
+	SourceTable string              `json:"source_table"`
+	TargetTable string              `json:"target_table"`
+	Score       float64             `json:"score"`
+	PoorMatch   bool                `json:"poor_match"`
+	Pinned      bool                `json:"pinned"`
+	Columns     []MatchReportColumn `json:"columns"`
+}
+
+// MatchReportColumn is the match of a source column, with the rule it got or why it got none
+type MatchReportColumn struct {
+	SourceTable  string  `json:"source_table"`
+	SourceColumn string  `json:"source_column"`
+	TargetTable  string  `json:"target_table"`
+	TargetColumn string  `json:"target_column"`
+	Score        float64 `json:"score"`
+	Outcome      string  `json:"outcome"`
+	RuleName     string  `json:"rule_name"`
+	Detail       string  `json:"detail"`
+}
+
+// ShowMatchReport shows the match report of a mapping: the options of the matcher, the outcome
+// counts, and the column matches that got no rule, with all column matches when all is set
+func ShowMatchReport(mappingName string, all bool) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/match-report", mappingName))
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		MatchReport MatchReport `json:"match_report"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to get match report: %v", err)
+	}
+	report := response.MatchReport
+
+	options := report.Options
+	fmt.Printf("Match report of mapping '%s' (generated %s)\n", mappingName, report.GeneratedAt)
+	fmt.Printf("Match profile: %s, min rule score: %.2f, poor match threshold: %.2f, name similarity threshold: %.2f\n",
+		options.ProfileName, options.MinRuleScore, options.PoorMatchThreshold, options.NameSimilarityThreshold)
+	fmt.Printf("Weights: name %.2f, type %.2f, classification %.2f, privileged data %.2f, table structure %.2f; cross-table: %t\n",
+		options.NameWeight, options.TypeWeight, options.ClassificationWeight, options.PrivilegedDataWeight, options.TableStructureWeight, options.EnableCrossTableMatching)
+	if len(report.Overridden) > 0 {
+		fmt.Printf("Overridden: %s\n", strings.Join(report.Overridden, ", "))
+	}
+	fmt.Printf("Overall score: %.2f\n", report.OverallScore)
+
+	outcomes := make([]string, 0, len(report.Outcomes))
+	for outcome := range report.Outcomes {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	fmt.Println()
+	fmt.Println("Outcomes:")
+	for _, outcome := range outcomes {
+		fmt.Printf("  %-22s %d\n", outcome, report.Outcomes[outcome])
+	}
+
+	var columns []MatchReportColumn
+	for _, table := range report.Tables {
+		for _, column := range table.Columns {
+			if all || column.Outcome != "rule_created" {
+				columns = append(columns, column)
+			}
+		}
+	}
+	columns = append(columns, report.UnmatchedColumns...)
+	if len(columns) > 0 {
+		fmt.Println()
+		fmt.Printf("%-35s %-35s %-7s %-22s %s\n", "Source", "Target", "Score", "Outcome", "Rule / Detail")
+		fmt.Println(strings.Repeat("-", 130))
+		for _, column := range columns {
+			target := "-"
+			if column.TargetColumn != "" {
+				target = column.TargetTable + "." + column.TargetColumn
+			}
+			detail := column.RuleName
+			if column.Detail != "" {
+				detail = strings.TrimSpace(detail + " " + column.Detail)
+			}
+			fmt.Printf("%-35s %-35s %-7.2f %-22s %s\n", column.SourceTable+"."+column.SourceColumn, target, column.Score, column.Outcome, detail)
+		}
+	}
+
+	for _, warning := range report.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	return nil
+}
+
+// printMatchSummary prints the outcome counts of the match report of a mapping just matched
+func printMatchSummary(report *MatchReport) {
+	if report == nil {
+		return
+	}
+	var skipped []string
+	for outcome, count := range report.Outcomes {
+		if outcome != "rule_created" && count > 0 {
+			skipped = append(skipped, fmt.Sprintf("%s: %d", outcome, count))
+		}
+	}
+	sort.Strings(skipped)
+	if len(skipped) == 0 {
+		fmt.Printf("Matcher created %d rules\n", report.RulesCreated)
+		return
+	}
+	fmt.Printf("Matcher created %d rules; column matches without a rule: %s\n", report.RulesCreated, strings.Join(skipped, ", "))
+	fmt.Printf("Run 'redb mappings match-report %s' for details\n", report.MappingName)
+}
@@ -0,0 +1,163 @@
+package mappings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// MeshHop is a node of the mesh the rows of a stage pass through
+type MeshHop struct {
+	NodeID   string `json:"node_id"`
+	NodeName string `json:"node_name"`
+}
+
+// RunPlanStage is a stage of a run plan, which starts when the stages it depends on are completed
+type RunPlanStage struct {
+	StageID          string    `json:"stage_id"`
+	Kind             string    `json:"kind"`
+	Description      string    `json:"description"`
+	DependsOn        []string  `json:"depends_on"`
+	SourceTable      string    `json:"source_table"`
+	TargetTable      string    `json:"target_table"`
+	SourceDatabase   string    `json:"source_database"`
+	TargetDatabase   string    `json:"target_database"`
+	SourceAdapter    string    `json:"source_adapter"`
+	TargetAdapter    string    `json:"target_adapter"`
+	NodeID           string    `json:"node_id"`
+	MeshHops         []MeshHop `json:"mesh_hops"`
+	EstimatedRows    int64     `json:"estimated_rows"`
+	EstimatedBatches int64     `json:"estimated_batches"`
+	BatchSize        int32     `json:"batch_size"`
+	Parallelism      int32     `json:"parallelism"`
+	Rules            []string  `json:"rules"`
+	Transformations  []string  `json:"transformations"`
+}
+
+// RunPlan is the execution plan of a copy of a mapping
+type RunPlan struct {
+	MappingName   string         `json:"mapping_name"`
+	NodeID        string         `json:"node_id"`
+	NodeName      string         `json:"node_name"`
+	BatchSize     int32          `json:"batch_size"`
+	Parallelism   int32          `json:"parallelism"`
+	EstimatedRows int64          `json:"estimated_rows"`
+	Adapters      []string       `json:"adapters"`
+	RowFilter     string         `json:"row_filter"`
+	Stages        []RunPlanStage `json:"stages"`
+	SkippedRules  []string       `json:"skipped_rules"`
+}
+
+// PlanRun shows the stages a copy of the mapping would run, without running it
+func PlanRun(mappingName string, batchSize, parallelWorkers int32, sandbox, validateVectors, verifyGraph bool) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/plan", mappingName))
+	if err != nil {
+		return err
+	}
+
+	planReq := struct {
+		BatchSize       int32 `json:"batch_size,omitempty"`
+		ParallelWorkers int32 `json:"parallel_workers,omitempty"`
+		Sandbox         bool  `json:"sandbox,omitempty"`
+		ValidateVectors bool  `json:"validate_vectors"`
+		VerifyGraph     bool  `json:"verify_graph"`
+	}{
+		BatchSize:       batchSize,
+		ParallelWorkers: parallelWorkers,
+		Sandbox:         sandbox,
+		ValidateVectors: validateVectors,
+		VerifyGraph:     verifyGraph,
+	}
+
+	var response struct {
+		Message  string   `json:"message"`
+		Success  bool     `json:"success"`
+		Plan     RunPlan  `json:"plan"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := client.Post(url, planReq, &response); err != nil {
+		return fmt.Errorf("failed to plan run: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to plan run: %s", response.Message)
+	}
+
+	plan := response.Plan
+	fmt.Printf("Run plan of mapping '%s' on node %s\n", plan.MappingName, nodeLabel(plan.NodeID, plan.NodeName))
+	fmt.Printf("  Batch size: %d, parallelism: %d, estimated rows: %s\n", plan.BatchSize, plan.Parallelism, estimate(plan.EstimatedRows))
+	if len(plan.Adapters) > 0 {
+		fmt.Printf("  Adapters: %s\n", strings.Join(plan.Adapters, ", "))
+	}
+	if plan.RowFilter != "" {
+		fmt.Printf("  Row filter: %s\n", plan.RowFilter)
+	}
+	if len(plan.SkippedRules) > 0 {
+		fmt.Printf("  Skipped broken rules: %s\n", strings.Join(plan.SkippedRules, ", "))
+	}
+
+	fmt.Println()
+	for _, stage := range plan.Stages {
+		after := "start"
+		if len(stage.DependsOn) > 0 {
+			after = strings.Join(stage.DependsOn, ", ")
+		}
+		fmt.Printf("%s [%s] after %s\n", stage.StageID, stage.Kind, after)
+		fmt.Printf("    %s\n", stage.Description)
+		if stage.Kind == "copy_table" {
+			fmt.Printf("    %s (%s) -> %s (%s)\n", stage.SourceDatabase, stage.SourceAdapter, stage.TargetDatabase, stage.TargetAdapter)
+			fmt.Printf("    %s rows in %s batches of %d, parallelism %d\n",
+				estimate(stage.EstimatedRows), estimate(stage.EstimatedBatches), stage.BatchSize, stage.Parallelism)
+			if len(stage.Transformations) > 0 {
+				fmt.Printf("    Transformations: %s\n", strings.Join(stage.Transformations, ", "))
+			}
+			if len(stage.MeshHops) > 0 {
+				hops := make([]string, len(stage.MeshHops))
+				for i, hop := range stage.MeshHops {
+					hops[i] = nodeLabel(hop.NodeID, hop.NodeName)
+				}
+				fmt.Printf("    Mesh hops: %s\n", strings.Join(hops, " -> "))
+			} else {
+				fmt.Println("    Mesh hops: unreachable")
+			}
+		}
+	}
+
+	if len(response.Warnings) > 0 {
+		fmt.Println()
+		for _, warning := range response.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+	return nil
+}
+
+// nodeLabel returns the name of a node, or its ID when it has none
+func nodeLabel(id, name string) string {
+	if name == "" {
+		return id
+	}
+	return name
+}
+
+// estimate formats an estimated count, which is -1 when unknown
+func estimate(count int64) string {
+	if count < 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d", count)
+}
@@ -0,0 +1,135 @@
+package mappings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// PreviewRow is a sample source row with the target row the mapping would write for it
+type PreviewRow struct {
+	SourceRow   map[string]interface{} `json:"source_row"`
+	TargetRow   map[string]interface{} `json:"target_row"`
+	FilteredOut bool                   `json:"filtered_out"`
+	Errors      []string               `json:"errors"`
+}
+
+// PreviewTableSample is the sample of a table pair of a mapping preview
+type PreviewTableSample struct {
+	SourceTable string       `json:"source_table"`
+	TargetTable string       `json:"target_table"`
+	Rules       []string     `json:"rules"`
+	Rows        []PreviewRow `json:"rows"`
+}
+
+// maxPreviewValueWidth bounds the width of the source column of a preview
+const maxPreviewValueWidth = 40
+
+// PreviewMapping shows sample source rows next to the target rows the mapping would write for them
+func PreviewMapping(mappingName string, sampleSize int32) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/preview", mappingName))
+	if err != nil {
+		return err
+	}
+
+	previewReq := struct {
+		SampleSize int32 `json:"sample_size,omitempty"`
+	}{
+		SampleSize: sampleSize,
+	}
+
+	var response struct {
+		Message  string               `json:"message"`
+		Success  bool                 `json:"success"`
+		Tables   []PreviewTableSample `json:"tables"`
+		Warnings []string             `json:"warnings"`
+	}
+	if err := client.Post(url, previewReq, &response); err != nil {
+		return fmt.Errorf("failed to preview mapping: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to preview mapping: %s", response.Message)
+	}
+
+	for _, table := range response.Tables {
+		fmt.Printf("%s -> %s (%d rules)\n", table.SourceTable, table.TargetTable, len(table.Rules))
+		if len(table.Rows) == 0 {
+			fmt.Println("  The source table has no rows")
+		}
+		for i, row := range table.Rows {
+			fmt.Printf("\n  Row %d\n", i+1)
+			sourceLines := previewLines(row.SourceRow)
+			var targetLines []string
+			if row.FilteredOut {
+				targetLines = []string{"(filtered out)"}
+			} else {
+				targetLines = previewLines(row.TargetRow)
+			}
+
+			width := len("Source")
+			for _, line := range sourceLines {
+				width = max(width, min(len(line), maxPreviewValueWidth))
+			}
+			fmt.Printf("    %-*s  %s\n", width, "Source", "Target")
+			for j := 0; j < max(len(sourceLines), len(targetLines)); j++ {
+				var source, target string
+				if j < len(sourceLines) {
+					source = sourceLines[j]
+				}
+				if j < len(targetLines) {
+					target = targetLines[j]
+				}
+				fmt.Printf("    %-*s  %s\n", width, source, target)
+			}
+			for _, rowErr := range row.Errors {
+				fmt.Printf("    ! %s\n", rowErr)
+			}
+		}
+		fmt.Println()
+	}
+
+	for _, warning := range response.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	return nil
+}
+
+// previewLines formats the columns of a row as "column: value" lines, ordered by column
+func previewLines(row map[string]interface{}) []string {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	lines := make([]string, 0, len(columns))
+	for _, column := range columns {
+		value, err := json.Marshal(row[column])
+		if err != nil {
+			value = []byte(fmt.Sprintf("%v", row[column]))
+		}
+		line := fmt.Sprintf("%s: %s", column, value)
+		if len(line) > maxPreviewValueWidth {
+			line = line[:maxPreviewValueWidth-3] + "..."
+		}
+		lines = append(lines, strings.ReplaceAll(line, "\n", " "))
+	}
+	return lines
+}
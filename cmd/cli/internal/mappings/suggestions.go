@@ -0,0 +1,94 @@
+package mappings
+
+import (
+	"fmt"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// RuleSuggestion is a proposed change to the rules of a mapping after its schemas changed
+type RuleSuggestion struct {
+	Action       string  `json:"action"`
+	RuleName     string  `json:"rule_name"`
+	SourceTable  string  `json:"source_table"`
+	SourceColumn string  `json:"source_column"`
+	TargetTable  string  `json:"target_table"`
+	TargetColumn string  `json:"target_column"`
+	Score        float64 `json:"score"`
+	Reason       string  `json:"reason"`
+}
+
+// RefreshMappingSuggestions re-runs the matcher of a mapping on the current schemas of its
+// databases and shows the rules to add and remove, applying them with apply
+func RefreshMappingSuggestions(mappingName, matchProfile string, apply bool) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/refresh-suggestions", mappingName))
+	if err != nil {
+		return err
+	}
+
+	refreshReq := struct {
+		MatchProfile string `json:"match_profile,omitempty"`
+		Apply        bool   `json:"apply,omitempty"`
+	}{
+		MatchProfile: matchProfile,
+		Apply:        apply,
+	}
+
+	var response struct {
+		Message          string           `json:"message"`
+		Success          bool             `json:"success"`
+		Additions        []RuleSuggestion `json:"additions"`
+		Removals         []RuleSuggestion `json:"removals"`
+		Applied          bool             `json:"applied"`
+		AddedRuleCount   int32            `json:"added_rule_count"`
+		RemovedRuleCount int32            `json:"removed_rule_count"`
+		Warnings         []string         `json:"warnings"`
+	}
+	if err := client.Post(url, refreshReq, &response); err != nil {
+		return fmt.Errorf("failed to refresh mapping suggestions: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to refresh mapping suggestions: %s", response.Message)
+	}
+
+	if len(response.Additions) == 0 && len(response.Removals) == 0 {
+		fmt.Printf("Mapping '%s' is up to date with the schemas of its databases\n", mappingName)
+	}
+	if len(response.Removals) > 0 {
+		fmt.Println("Rules to remove:")
+		for _, removal := range response.Removals {
+			fmt.Printf("  - %s: %s\n", removal.RuleName, removal.Reason)
+		}
+	}
+	if len(response.Additions) > 0 {
+		fmt.Println("Rules to add:")
+		for _, addition := range response.Additions {
+			fmt.Printf("  + %s.%s -> %s.%s (score: %.2f)\n",
+				addition.SourceTable, addition.SourceColumn, addition.TargetTable, addition.TargetColumn, addition.Score)
+		}
+	}
+	for _, warning := range response.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	if response.Applied {
+		fmt.Printf("Updated mapping '%s': removed %d rules, added %d\n", mappingName, response.RemovedRuleCount, response.AddedRuleCount)
+	} else if len(response.Additions) > 0 || len(response.Removals) > 0 {
+		fmt.Printf("Run 'redb mappings refresh-suggestions %s --apply' to apply the suggestions\n", mappingName)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package mappings
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// TablePin fixes the pairing of a source and a target table of a database mapping for the
+// matcher: the source table always matches the target table, or, with never, never matches it
+type TablePin struct {
+	SourceTable string `json:"source_table"`
+	TargetTable string `json:"target_table"`
+	Never       bool   `json:"never,omitempty"`
+}
+
+// String returns the pin as shown by mappings show
+func (p TablePin) String() string {
+	if p.Never {
+		return fmt.Sprintf("%s never matches %s", p.SourceTable, p.TargetTable)
+	}
+	return fmt.Sprintf("%s matches %s", p.SourceTable, p.TargetTable)
+}
+
+// ParseTablePins parses table pins written "source_table:target_table"
+func ParseTablePins(values []string, never bool) ([]TablePin, error) {
+	pins := make([]TablePin, 0, len(values))
+	for _, value := range values {
+		source, target, found := strings.Cut(value, ":")
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		if !found || source == "" || target == "" {
+			return nil, fmt.Errorf("invalid table pin '%s': expected source_table:target_table", value)
+		}
+		pins = append(pins, TablePin{SourceTable: source, TargetTable: target, Never: never})
+	}
+	return pins, nil
+}
+
+// PinTables pins a source table of a database mapping to a target table, or, with never, pins
+// them never to match. A match pin replaces the other match pins of either table. The rules of
+// the mapping change when it is rematched.
+func PinTables(mappingName, sourceTable, targetTable string, never bool) error {
+	pins, err := getTablePins(mappingName)
+	if err != nil {
+		return err
+	}
+
+	pin := TablePin{SourceTable: sourceTable, TargetTable: targetTable, Never: never}
+	updated := make([]TablePin, 0, len(pins)+1)
+	for _, existing := range pins {
+		samePair := existing.SourceTable == sourceTable && existing.TargetTable == targetTable
+		replaced := !never && !existing.Never && (existing.SourceTable == sourceTable || existing.TargetTable == targetTable)
+		if !samePair && !replaced {
+			updated = append(updated, existing)
+		}
+	}
+	updated = append(updated, pin)
+
+	if err := setTablePins(mappingName, updated); err != nil {
+		return err
+	}
+	fmt.Printf("Pinned %s in mapping '%s', rematch it to apply the pin\n", pin, mappingName)
+	return nil
+}
+
+// UnpinTables removes the pins of a source table of a database mapping, or only its pin to a
+// target table when given
+func UnpinTables(mappingName, sourceTable, targetTable string) error {
+	pins, err := getTablePins(mappingName)
+	if err != nil {
+		return err
+	}
+
+	updated := make([]TablePin, 0, len(pins))
+	for _, pin := range pins {
+		if pin.SourceTable != sourceTable || (targetTable != "" && pin.TargetTable != targetTable) {
+			updated = append(updated, pin)
+		}
+	}
+	if len(updated) == len(pins) {
+		return fmt.Errorf("mapping '%s' has no pin of table %s", mappingName, sourceTable)
+	}
+
+	if err := setTablePins(mappingName, updated); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d table pins from mapping '%s', rematch it to apply the change\n", len(pins)-len(updated), mappingName)
+	return nil
+}
+
+// ClearTablePins removes all the table pins of a database mapping
+func ClearTablePins(mappingName string) error {
+	if err := setTablePins(mappingName, []TablePin{}); err != nil {
+		return err
+	}
+	fmt.Printf("Removed the table pins of mapping '%s', rematch it to apply the change\n", mappingName)
+	return nil
+}
+
+// RematchMapping re-runs the matcher of a database mapping with its table pins, replacing the
+// rules it generated. Changes made to generated rules are lost, so it asks for confirmation
+// unless yes is set.
+func RematchMapping(mappingName, matchProfile string, overrides *MatchOverrides, yes bool) error {
+	if mappingName == "" {
+		return fmt.Errorf("mapping name is required")
+	}
+
+	if !yes {
+		fmt.Printf("Rematching replaces the generated rules of mapping '%s', with any changes made to them. Continue? (y/N): ", mappingName)
+		reader := bufio.NewReader(os.Stdin)
+		confirmation, _ := reader.ReadString('\n')
+		confirmation = strings.TrimSpace(strings.ToLower(confirmation))
+		if confirmation != "y" && confirmation != "yes" {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s/rematch", mappingName))
+	if err != nil {
+		return err
+	}
+
+	rematchReq := struct {
+		MatchProfile   string          `json:"match_profile,omitempty"`
+		MatchOverrides *MatchOverrides `json:"match_overrides,omitempty"`
+	}{
+		MatchProfile:   matchProfile,
+		MatchOverrides: overrides,
+	}
+
+	var response struct {
+		Message          string       `json:"message"`
+		Success          bool         `json:"success"`
+		RemovedRuleCount int32        `json:"removed_rule_count"`
+		AddedRuleCount   int32        `json:"added_rule_count"`
+		Warnings         []string     `json:"warnings"`
+		MatchReport      *MatchReport `json:"match_report"`
+	}
+	if err := client.Post(url, rematchReq, &response); err != nil {
+		return fmt.Errorf("failed to rematch mapping: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to rematch mapping: %s", response.Message)
+	}
+
+	fmt.Printf("Rematched mapping '%s': removed %d generated rules, added %d\n", mappingName, response.RemovedRuleCount, response.AddedRuleCount)
+	printMatchSummary(response.MatchReport)
+	for _, warning := range response.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	return nil
+}
+
+// getTablePins returns the table pins of a mapping
+func getTablePins(mappingName string) ([]TablePin, error) {
+	if mappingName == "" {
+		return nil, fmt.Errorf("mapping name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s", mappingName))
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Mapping Mapping `json:"mapping"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return nil, fmt.Errorf("failed to get mapping: %v", err)
+	}
+	return response.Mapping.TablePins, nil
+}
+
+// setTablePins replaces the table pins of a mapping
+func setTablePins(mappingName string, pins []TablePin) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/mappings/%s", mappingName))
+	if err != nil {
+		return err
+	}
+
+	modifyReq := struct {
+		TablePins []TablePin `json:"table_pins"`
+	}{
+		TablePins: pins,
+	}
+
+	var response struct {
+		Message string `json:"message"`
+		Success bool   `json:"success"`
+	}
+	if err := client.Put(url, modifyReq, &response); err != nil {
+		return fmt.Errorf("failed to set table pins: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to set table pins: %s", response.Message)
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package proxies
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+	"github.com/redbco/redb-open/cmd/cli/internal/httpclient"
+	"golang.org/x/term"
+)
+
+type stats struct {
+	ListenAddress    string `json:"listen_address"`
+	ActiveSessions   int64  `json:"active_sessions"`
+	TotalSessions    int64  `json:"total_sessions"`
+	SourceStatements int64  `json:"source_statements"`
+	TargetStatements int64  `json:"target_statements"`
+	Started          string `json:"started"`
+}
+
+type proxy struct {
+	ProxyID            string            `json:"proxy_id"`
+	ProxyName          string            `json:"proxy_name"`
+	ProxyDescription   string            `json:"proxy_description"`
+	RelationshipName   string            `json:"relationship_name"`
+	SourceDatabaseName string            `json:"source_database_name"`
+	TargetDatabaseName string            `json:"target_database_name"`
+	Protocol           string            `json:"protocol"`
+	NodeID             string            `json:"node_id"`
+	ListenPort         int32             `json:"listen_port"`
+	Username           string            `json:"username"`
+	ReadPercent        int32             `json:"read_percent"`
+	WritePercent       int32             `json:"write_percent"`
+	TableRoutes        map[string]string `json:"table_routes"`
+	Status             string            `json:"status"`
+	StatusMessage      string            `json:"status_message"`
+	Stats              *stats            `json:"stats"`
+	Created            string            `json:"created"`
+	Updated            string            `json:"updated"`
+}
+
+type proxyResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Proxy   proxy  `json:"proxy"`
+}
+
+// AddProxyOptions are the settings of a new query proxy
+type AddProxyOptions struct {
+	Description  string
+	Relationship string
+	Port         int32
+	Username     string
+	Password     string
+	ReadPercent  int32
+	WritePercent int32
+	TableRoutes  map[string]string
+}
+
+// RouteOptions are the changes to the routes of a query proxy. Nil percentages are kept.
+type RouteOptions struct {
+	ReadPercent      *int32
+	WritePercent     *int32
+	TableRoutes      map[string]string
+	ClearTableRoutes bool
+}
+
+// ParseTableRoutes parses table routes given as table=source or table=target. A table without a
+// database, as table=, removes its route.
+func ParseTableRoutes(values []string) (map[string]string, error) {
+	routes := make(map[string]string, len(values))
+	for _, value := range values {
+		table, db, ok := strings.Cut(value, "=")
+		table = strings.TrimSpace(table)
+		if !ok || table == "" {
+			return nil, fmt.Errorf("invalid table route %q, expected table=source or table=target", value)
+		}
+		db = strings.TrimSpace(db)
+		if db != "" && db != "source" && db != "target" {
+			return nil, fmt.Errorf("table %s is routed to %q, expected source or target", table, db)
+		}
+		routes[table] = db
+	}
+	return routes, nil
+}
+
+// AddProxy adds a stopped query proxy
+func AddProxy(proxyName string, options AddProxyOptions) error {
+	proxyName = strings.TrimSpace(proxyName)
+	if proxyName == "" {
+		return fmt.Errorf("proxy name is required")
+	}
+	for table, db := range options.TableRoutes {
+		if db == "" {
+			return fmt.Errorf("table %s needs a database to be routed to", table)
+		}
+	}
+
+	if options.Password == "" {
+		fmt.Print("Password applications connect with: ")
+		bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			return fmt.Errorf("failed to read password: %v", err)
+		}
+		fmt.Println()
+		options.Password = string(bytePassword)
+		if options.Password == "" {
+			return fmt.Errorf("password is required")
+		}
+	}
+
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/query-proxies")
+	if err != nil {
+		return err
+	}
+
+	req := struct {
+		ProxyName        string            `json:"proxy_name"`
+		ProxyDescription string            `json:"proxy_description,omitempty"`
+		RelationshipName string            `json:"relationship_name"`
+		ListenPort       int32             `json:"listen_port"`
+		Username         string            `json:"username"`
+		Password         string            `json:"password"`
+		ReadPercent      int32             `json:"read_percent"`
+		WritePercent     int32             `json:"write_percent"`
+		TableRoutes      map[string]string `json:"table_routes,omitempty"`
+	}{
+		ProxyName:        proxyName,
+		ProxyDescription: options.Description,
+		RelationshipName: options.Relationship,
+		ListenPort:       options.Port,
+		Username:         options.Username,
+		Password:         options.Password,
+		ReadPercent:      options.ReadPercent,
+		WritePercent:     options.WritePercent,
+		TableRoutes:      options.TableRoutes,
+	}
+
+	var response proxyResponse
+	if err := client.Post(url, req, &response); err != nil {
+		return fmt.Errorf("failed to add query proxy: %v", err)
+	}
+
+	fmt.Printf("✓ Query proxy '%s' added (%s protocol, port %d)\n", proxyName, response.Proxy.Protocol, response.Proxy.ListenPort)
+	fmt.Printf("\nTo start it, run:\n  redb proxies start %s\n", proxyName)
+	return nil
+}
+
+// ListProxies lists the query proxies of the active workspace
+func ListProxies() error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, "/query-proxies")
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Proxies []proxy `json:"proxies"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list query proxies: %v", err)
+	}
+
+	if len(response.Proxies) == 0 {
+		fmt.Println("No query proxies found in this workspace.")
+		fmt.Println("\nTo add a query proxy, run:")
+		fmt.Println("  redb proxies add <proxy-name> --relationship <relationship-name> --port <port> --username <username>")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Name\tRelationship\tProtocol\tPort\tReads\tWrites\tTables\tStatus")
+	fmt.Fprintln(w, "----\t------------\t--------\t----\t-----\t------\t------\t------")
+	for _, p := range response.Proxies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d%%\t%d%%\t%d\t%s\n",
+			p.ProxyName, p.RelationshipName, p.Protocol, p.ListenPort,
+			p.ReadPercent, p.WritePercent, len(p.TableRoutes), p.Status)
+	}
+	_ = w.Flush()
+	fmt.Println()
+
+	return nil
+}
+
+// ShowProxy shows a query proxy, its routes and, when it runs, its activity
+func ShowProxy(proxyName string) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	p, err := getProxy(client, profileInfo, proxyName)
+	if err != nil {
+		return err
+	}
+	printProxy(p)
+	return nil
+}
+
+// StartProxy starts a query proxy
+func StartProxy(proxyName string) error {
+	return operation(proxyName, "start")
+}
+
+// StopProxy stops a query proxy, closing the sessions of applications
+func StopProxy(proxyName string) error {
+	return operation(proxyName, "stop")
+}
+
+// RouteProxy changes the routes of a query proxy
+func RouteProxy(proxyName string, options RouteOptions) error {
+	proxyName = strings.TrimSpace(proxyName)
+	if proxyName == "" {
+		return fmt.Errorf("proxy name is required")
+	}
+	if options.ReadPercent == nil && options.WritePercent == nil && len(options.TableRoutes) == 0 && !options.ClearTableRoutes {
+		return fmt.Errorf("nothing to change: pass --reads, --writes, --table or --clear-tables")
+	}
+
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/query-proxies/%s/routes", proxyName))
+	if err != nil {
+		return err
+	}
+
+	req := struct {
+		ReadPercent      *int32            `json:"read_percent,omitempty"`
+		WritePercent     *int32            `json:"write_percent,omitempty"`
+		TableRoutes      map[string]string `json:"table_routes,omitempty"`
+		ClearTableRoutes bool              `json:"clear_table_routes,omitempty"`
+	}{
+		ReadPercent:      options.ReadPercent,
+		WritePercent:     options.WritePercent,
+		TableRoutes:      options.TableRoutes,
+		ClearTableRoutes: options.ClearTableRoutes,
+	}
+
+	var response proxyResponse
+	if err := client.Put(url, req, &response); err != nil {
+		return fmt.Errorf("failed to update query proxy routes: %v", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("%s", response.Message)
+	}
+
+	fmt.Printf("✓ %s\n", response.Message)
+	fmt.Printf("  Reads on target:  %d%%\n", response.Proxy.ReadPercent)
+	fmt.Printf("  Writes on target: %d%%\n", response.Proxy.WritePercent)
+	if len(response.Proxy.TableRoutes) > 0 {
+		fmt.Printf("  Tables:           %s\n", formatTableRoutes(response.Proxy.TableRoutes))
+	}
+	return nil
+}
+
+// DeleteProxy deletes a query proxy that is not running
+func DeleteProxy(proxyName string) error {
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/query-proxies/%s", proxyName))
+	if err != nil {
+		return err
+	}
+
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to delete query proxy: %v", err)
+	}
+
+	fmt.Printf("✓ Query proxy '%s' deleted\n", proxyName)
+	return nil
+}
+
+// operation starts or stops a query proxy
+func operation(proxyName, action string) error {
+	proxyName = strings.TrimSpace(proxyName)
+	if proxyName == "" {
+		return fmt.Errorf("proxy name is required")
+	}
+
+	profileInfo, client, err := workspaceClient()
+	if err != nil {
+		return err
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/query-proxies/%s/%s", proxyName, action))
+	if err != nil {
+		return err
+	}
+
+	var response proxyResponse
+	if err := client.Post(url, nil, &response); err != nil {
+		return fmt.Errorf("failed to %s query proxy: %v", action, err)
+	}
+
+	fmt.Printf("✓ %s\n", response.Message)
+	return nil
+}
+
+func getProxy(client *httpclient.ProfileHTTPClient, profileInfo *common.ProfileInfo, proxyName string) (*proxy, error) {
+	proxyName = strings.TrimSpace(proxyName)
+	if proxyName == "" {
+		return nil, fmt.Errorf("proxy name is required")
+	}
+
+	url, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/query-proxies/%s", proxyName))
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Proxy proxy `json:"proxy"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return nil, fmt.Errorf("failed to get query proxy: %v", err)
+	}
+	return &response.Proxy, nil
+}
+
+func printProxy(p *proxy) {
+	fmt.Printf("\nQuery Proxy Details: %s\n", p.ProxyName)
+	fmt.Printf("=====================================\n\n")
+	fmt.Printf("ID:                %s\n", p.ProxyID)
+	if p.ProxyDescription != "" {
+		fmt.Printf("Description:       %s\n", p.ProxyDescription)
+	}
+	fmt.Printf("Relationship:      %s\n", p.RelationshipName)
+	fmt.Printf("Source database:   %s\n", p.SourceDatabaseName)
+	fmt.Printf("Target database:   %s\n", p.TargetDatabaseName)
+	fmt.Printf("Protocol:          %s\n", p.Protocol)
+	fmt.Printf("Node:              %s\n", p.NodeID)
+	fmt.Printf("Port:              %d\n", p.ListenPort)
+	fmt.Printf("Username:          %s\n", p.Username)
+	fmt.Printf("Status:            %s\n", p.Status)
+	if p.StatusMessage != "" {
+		fmt.Printf("Status message:    %s\n", p.StatusMessage)
+	}
+
+	fmt.Printf("\nReads on target:   %d%%\n", p.ReadPercent)
+	fmt.Printf("Writes on target:  %d%%\n", p.WritePercent)
+	if len(p.TableRoutes) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Println()
+		fmt.Fprintln(w, "Table\tDatabase")
+		fmt.Fprintln(w, "-----\t--------")
+		for _, table := range sortedTables(p.TableRoutes) {
+			fmt.Fprintf(w, "%s\t%s\n", table, p.TableRoutes[table])
+		}
+		_ = w.Flush()
+	}
+
+	if p.Stats != nil {
+		fmt.Printf("\nListening on:      %s\n", p.Stats.ListenAddress)
+		fmt.Printf("Running since:     %s\n", p.Stats.Started)
+		fmt.Printf("Sessions:          %d open, %d in total\n", p.Stats.ActiveSessions, p.Stats.TotalSessions)
+		fmt.Printf("Statements:        %d on source, %d on target\n", p.Stats.SourceStatements, p.Stats.TargetStatements)
+	}
+
+	fmt.Printf("\nCreated:           %s\n", p.Created)
+	fmt.Printf("Updated:           %s\n", p.Updated)
+	fmt.Println()
+}
+
+func formatTableRoutes(routes map[string]string) string {
+	tables := sortedTables(routes)
+	for i, table := range tables {
+		tables[i] = table + "=" + routes[table]
+	}
+	return strings.Join(tables, ", ")
+}
+
+func sortedTables(routes map[string]string) []string {
+	tables := make([]string, 0, len(routes))
+	for table := range routes {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+func workspaceClient() (*common.ProfileInfo, *httpclient.ProfileHTTPClient, error) {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := common.ValidateWorkspace(profileInfo); err != nil {
+		return nil, nil, err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return profileInfo, client, nil
+}
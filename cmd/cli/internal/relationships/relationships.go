@@ -265,6 +265,13 @@ func StartRelationship(relationshipName string, batchSize, parallelWorkers int32
 			fmt.Printf("   %s\n", message)
 		}
 
+		// Breaking changes to the source schema that the schema change policies warn about
+		if warnings, ok := event["warnings"].([]interface{}); ok {
+			for _, w := range warnings {
+				fmt.Printf("   ⚠️  %v\n", w)
+			}
+		}
+
 		// Show progress details
 		if rowsCopied, ok := event["rows_copied"].(float64); ok && rowsCopied > 0 {
 			if totalRows, ok := event["total_rows"].(float64); ok && totalRows > 0 {
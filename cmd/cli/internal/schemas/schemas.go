@@ -0,0 +1,434 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+	"github.com/redbco/redb-open/cmd/cli/internal/httpclient"
+)
+
+// ConvertOptions are the options of a batch schema conversion
+type ConvertOptions struct {
+	From                  string   // Only convert databases of this type, all types when empty
+	To                    string   // The database type to convert to
+	Databases             []string // The databases to convert, unless AllDatabases is set
+	AllDatabases          bool     // Convert all databases matching From and Selector
+	Selector              string   // Only convert databases whose labels match, e.g. team=analytics
+	OutputDir             string   // Write the DDL, converted schemas and reports to this directory
+	StoreArtifacts        bool     // Keep the DDL and reports as export artifacts in the blob store
+	Parallel              int      // Conversions running at once
+	TransformationOptions map[string]string
+}
+
+type database struct {
+	DatabaseName string `json:"database_name"`
+	DatabaseType string `json:"database_type"`
+}
+
+// Warning is a warning of a schema conversion
+type Warning struct {
+	WarningType string `json:"warning_type"`
+	ObjectType  string `json:"object_type,omitempty"`
+	ObjectName  string `json:"object_name,omitempty"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity,omitempty"`
+	Suggestion  string `json:"suggestion,omitempty"`
+}
+
+// Metrics are the object counts of a schema conversion
+type Metrics struct {
+	ObjectsProcessed int32 `json:"objects_processed"`
+	ObjectsConverted int32 `json:"objects_converted"`
+	ObjectsSkipped   int32 `json:"objects_skipped"`
+	ObjectsDropped   int32 `json:"objects_dropped"`
+	TypesConverted   int32 `json:"types_converted"`
+	LossyConversions int32 `json:"lossy_conversions"`
+}
+
+// Conversion is the result of converting the schema of one database
+type Conversion struct {
+	DatabaseName    string          `json:"database_name"`
+	SourceType      string          `json:"source_type"`
+	TargetType      string          `json:"target_type"`
+	ConvertedSchema json.RawMessage `json:"converted_schema,omitempty"`
+	Statements      []string        `json:"statements,omitempty"`
+	GenerationError string          `json:"generation_error,omitempty"`
+	Warnings        []Warning       `json:"warnings,omitempty"`
+	Metrics         *Metrics        `json:"metrics,omitempty"`
+	Lossy           bool            `json:"lossy"`
+	ArtifactIDs     []string        `json:"artifact_ids,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// report is the report of a conversion written next to its DDL, without the converted schema
+type report struct {
+	DatabaseName    string    `json:"database_name"`
+	SourceType      string    `json:"source_type"`
+	TargetType      string    `json:"target_type"`
+	Lossy           bool      `json:"lossy"`
+	Statements      int       `json:"statements"`
+	GenerationError string    `json:"generation_error,omitempty"`
+	Metrics         *Metrics  `json:"metrics,omitempty"`
+	Warnings        []Warning `json:"warnings,omitempty"`
+	ArtifactIDs     []string  `json:"artifact_ids,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// ConvertSchemas converts the schemas of many databases to another database type in parallel,
+// writes the results to a directory or the blob store and summarizes the lossy conversions
+func ConvertSchemas(options ConvertOptions) error {
+	if options.To == "" {
+		return fmt.Errorf("--to is required")
+	}
+	if !options.AllDatabases && len(options.Databases) == 0 {
+		return fmt.Errorf("name the databases to convert or pass --all-databases")
+	}
+	if options.AllDatabases && len(options.Databases) > 0 {
+		return fmt.Errorf("cannot name databases together with --all-databases")
+	}
+	if options.OutputDir == "" && !options.StoreArtifacts {
+		return fmt.Errorf("--output-dir or --store-artifacts is required")
+	}
+	if options.Parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectDatabases(client, profileInfo, options)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no databases match")
+	}
+
+	if options.OutputDir != "" {
+		if err := os.MkdirAll(options.OutputDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+	}
+
+	fmt.Printf("Converting %d databases to %s, %d at a time\n", len(selected), options.To, min(options.Parallel, len(selected)))
+
+	conversions := make([]Conversion, len(selected))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(options.Parallel, len(selected)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				conversion := convertSchema(client, profileInfo, selected[i], options)
+				if conversion.Error == "" && options.OutputDir != "" {
+					if err := writeConversion(options.OutputDir, conversion); err != nil {
+						conversion.Error = err.Error()
+					}
+				}
+				conversions[i] = conversion
+			}
+		}()
+	}
+	for i := range selected {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if options.OutputDir != "" {
+		if err := writeSummary(options.OutputDir, conversions); err != nil {
+			return err
+		}
+	}
+
+	printSummary(conversions)
+	if options.OutputDir != "" {
+		fmt.Printf("Results written to %s\n", options.OutputDir)
+	}
+
+	failed := 0
+	for _, conversion := range conversions {
+		if conversion.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d conversions failed", failed, len(conversions))
+	}
+	return nil
+}
+
+// selectDatabases returns the databases to convert: the named ones, or all matching the
+// selector, in both cases only those of the source type
+func selectDatabases(client *httpclient.ProfileHTTPClient, profileInfo *common.ProfileInfo, options ConvertOptions) ([]database, error) {
+	path := "/databases"
+	if options.Selector != "" {
+		path += "?selector=" + url.QueryEscape(options.Selector)
+	}
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Databases []database `json:"databases"`
+	}
+	if err := client.Get(apiURL, &response); err != nil {
+		return nil, fmt.Errorf("failed to list databases: %v", err)
+	}
+
+	return filterDatabases(response.Databases, options)
+}
+
+// filterDatabases keeps the named databases, or all databases with AllDatabases, of the source
+// type. A named database that is missing or of another type is an error.
+func filterDatabases(databases []database, options ConvertOptions) ([]database, error) {
+	matchesType := func(db database) bool {
+		return options.From == "" || strings.EqualFold(db.DatabaseType, options.From)
+	}
+
+	var selected []database
+	if options.AllDatabases {
+		for _, db := range databases {
+			if matchesType(db) {
+				selected = append(selected, db)
+			}
+		}
+	} else {
+		byName := make(map[string]database, len(databases))
+		for _, db := range databases {
+			byName[db.DatabaseName] = db
+		}
+		for _, name := range options.Databases {
+			db, ok := byName[name]
+			if !ok {
+				if options.Selector != "" {
+					return nil, fmt.Errorf("database %s not found or its labels do not match %s", name, options.Selector)
+				}
+				return nil, fmt.Errorf("database %s not found", name)
+			}
+			if !matchesType(db) {
+				return nil, fmt.Errorf("database %s is of type %s, not %s", name, db.DatabaseType, options.From)
+			}
+			selected = append(selected, db)
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].DatabaseName < selected[j].DatabaseName })
+	return selected, nil
+}
+
+// convertSchema converts the schema of one database, recording a failure in the conversion
+func convertSchema(client *httpclient.ProfileHTTPClient, profileInfo *common.ProfileInfo, db database, options ConvertOptions) Conversion {
+	conversion := Conversion{DatabaseName: db.DatabaseName, SourceType: db.DatabaseType, TargetType: options.To}
+
+	apiURL, err := common.BuildWorkspaceAPIURL(profileInfo, fmt.Sprintf("/databases/%s/convert-schema", url.PathEscape(db.DatabaseName)))
+	if err != nil {
+		conversion.Error = err.Error()
+		return conversion
+	}
+
+	request := map[string]interface{}{
+		"target_type":     options.To,
+		"store_artifacts": options.StoreArtifacts,
+	}
+	if len(options.TransformationOptions) > 0 {
+		request["transformation_options"] = options.TransformationOptions
+	}
+	if err := client.Post(apiURL, request, &conversion); err != nil {
+		conversion.Error = err.Error()
+	}
+	return conversion
+}
+
+// writeConversion writes the DDL, the converted schema and the report of a conversion to
+// <dir>/<database>/<target>.sql, .schema.json and .report.json
+func writeConversion(dir string, conversion Conversion) error {
+	databaseDir := filepath.Join(dir, fileName(conversion.DatabaseName))
+	if err := os.MkdirAll(databaseDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %v", conversion.DatabaseName, err)
+	}
+	base := filepath.Join(databaseDir, fileName(conversion.TargetType))
+
+	if len(conversion.Statements) > 0 {
+		if err := os.WriteFile(base+".sql", []byte(ddlScript(conversion.Statements)), 0o644); err != nil {
+			return fmt.Errorf("failed to write DDL of %s: %v", conversion.DatabaseName, err)
+		}
+	}
+	if len(conversion.ConvertedSchema) > 0 {
+		if err := writeJSON(base+".schema.json", conversion.ConvertedSchema); err != nil {
+			return fmt.Errorf("failed to write converted schema of %s: %v", conversion.DatabaseName, err)
+		}
+	}
+	if err := writeJSON(base+".report.json", reportOf(conversion)); err != nil {
+		return fmt.Errorf("failed to write report of %s: %v", conversion.DatabaseName, err)
+	}
+	return nil
+}
+
+// writeSummary writes the reports of all conversions, failed ones included, to summary.json
+func writeSummary(dir string, conversions []Conversion) error {
+	reports := make([]report, len(conversions))
+	for i, conversion := range conversions {
+		reports[i] = reportOf(conversion)
+	}
+	if err := writeJSON(filepath.Join(dir, "summary.json"), reports); err != nil {
+		return fmt.Errorf("failed to write summary: %v", err)
+	}
+	return nil
+}
+
+func reportOf(conversion Conversion) report {
+	return report{
+		DatabaseName:    conversion.DatabaseName,
+		SourceType:      conversion.SourceType,
+		TargetType:      conversion.TargetType,
+		Lossy:           conversion.Lossy,
+		Statements:      len(conversion.Statements),
+		GenerationError: conversion.GenerationError,
+		Metrics:         conversion.Metrics,
+		Warnings:        conversion.Warnings,
+		ArtifactIDs:     conversion.ArtifactIDs,
+		Error:           conversion.Error,
+	}
+}
+
+// printSummary prints a table of the conversions, followed by what the lossy conversions lose
+// and why conversions failed
+func printSummary(conversions []Conversion) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Println()
+	fmt.Fprintln(w, "Database\tSource\tResult\tStatements\tWarnings\tLossy Conversions\tDropped Objects")
+	fmt.Fprintln(w, "--------\t------\t------\t----------\t--------\t-----------------\t---------------")
+	for _, conversion := range conversions {
+		var lossyConversions, dropped int32
+		if conversion.Metrics != nil {
+			lossyConversions = conversion.Metrics.LossyConversions
+			dropped = conversion.Metrics.ObjectsDropped
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+			conversion.DatabaseName,
+			conversion.SourceType,
+			result(conversion),
+			len(conversion.Statements),
+			len(conversion.Warnings),
+			lossyConversions,
+			dropped)
+	}
+	_ = w.Flush()
+	fmt.Println()
+
+	lossy := 0
+	for _, conversion := range conversions {
+		if conversion.Error == "" && conversion.Lossy {
+			lossy++
+		}
+	}
+	if lossy > 0 {
+		fmt.Printf("Lossy conversions (%d):\n", lossy)
+		for _, conversion := range conversions {
+			if conversion.Error != "" || !conversion.Lossy {
+				continue
+			}
+			fmt.Printf("  %s:\n", conversion.DatabaseName)
+			if m := conversion.Metrics; m != nil && (m.LossyConversions > 0 || m.ObjectsDropped > 0) {
+				fmt.Printf("    %d lossy type conversions, %d dropped objects\n", m.LossyConversions, m.ObjectsDropped)
+			}
+			for _, warning := range lossWarnings(conversion.Warnings) {
+				object := warning.ObjectName
+				if warning.ObjectType != "" {
+					object = warning.ObjectType + " " + object
+				}
+				fmt.Printf("    - %s: %s\n", strings.TrimSpace(object), warning.Message)
+			}
+		}
+		fmt.Println()
+	}
+
+	for _, conversion := range conversions {
+		switch {
+		case conversion.Error != "":
+			fmt.Printf("%s failed: %s\n", conversion.DatabaseName, conversion.Error)
+		case conversion.GenerationError != "":
+			fmt.Printf("%s: no DDL generated: %s\n", conversion.DatabaseName, conversion.GenerationError)
+		}
+	}
+}
+
+func result(conversion Conversion) string {
+	switch {
+	case conversion.Error != "":
+		return "failed"
+	case conversion.Lossy:
+		return "lossy"
+	case conversion.GenerationError != "":
+		return "no DDL"
+	default:
+		return "converted"
+	}
+}
+
+// lossWarnings returns the warnings about lost data and features
+func lossWarnings(warnings []Warning) []Warning {
+	var losses []Warning
+	for _, warning := range warnings {
+		if warning.WarningType == "data_loss" || warning.WarningType == "feature_loss" {
+			losses = append(losses, warning)
+		}
+	}
+	return losses
+}
+
+// ddlScript joins DDL statements into a script, terminating each statement
+func ddlScript(statements []string) string {
+	var sb strings.Builder
+	for _, statement := range statements {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		sb.WriteString(statement)
+		if !strings.HasSuffix(statement, ";") {
+			sb.WriteString(";")
+		}
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// fileName makes a database name or type safe to use as a file name
+func fileName(name string) string {
+	if name == "." || name == ".." {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == 0 {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
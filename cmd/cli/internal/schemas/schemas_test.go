@@ -0,0 +1,70 @@
+package schemas
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilterDatabases(t *testing.T) {
+	databases := []database{
+		{DatabaseName: "orders", DatabaseType: "postgres"},
+		{DatabaseName: "events", DatabaseType: "postgres"},
+		{DatabaseName: "cache", DatabaseType: "redis"},
+	}
+
+	selected, err := filterDatabases(databases, ConvertOptions{From: "postgres", AllDatabases: true})
+	if err != nil {
+		t.Fatalf("filterDatabases: %v", err)
+	}
+	if len(selected) != 2 || selected[0].DatabaseName != "events" || selected[1].DatabaseName != "orders" {
+		t.Fatalf("got %v, want events and orders", selected)
+	}
+
+	if _, err := filterDatabases(databases, ConvertOptions{From: "postgres", Databases: []string{"cache"}}); err == nil {
+		t.Error("a named database of another type should fail")
+	}
+	if _, err := filterDatabases(databases, ConvertOptions{Databases: []string{"missing"}}); err == nil {
+		t.Error("a missing database should fail")
+	}
+}
+
+func TestWriteConversion(t *testing.T) {
+	dir := t.TempDir()
+	conversion := Conversion{
+		DatabaseName:    "orders",
+		SourceType:      "postgres",
+		TargetType:      "mysql",
+		ConvertedSchema: json.RawMessage(`{"database_type":"mysql"}`),
+		Statements:      []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT);"},
+		Warnings:        []Warning{{WarningType: "data_loss", Message: "array converted to JSON"}},
+		Lossy:           true,
+	}
+	if err := writeConversion(dir, conversion); err != nil {
+		t.Fatalf("writeConversion: %v", err)
+	}
+
+	ddl, err := os.ReadFile(filepath.Join(dir, "orders", "mysql.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ddl) != "CREATE TABLE a (id INT);\n\nCREATE TABLE b (id INT);\n\n" {
+		t.Errorf("DDL = %q", ddl)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "orders", "mysql.report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written report
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if !written.Lossy || written.Statements != 2 || len(written.Warnings) != 1 {
+		t.Errorf("report = %+v", written)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "orders", "mysql.schema.json")); err != nil {
+		t.Errorf("converted schema not written: %v", err)
+	}
+}
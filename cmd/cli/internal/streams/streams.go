@@ -205,7 +205,7 @@ func ConnectStream(args []string) error {
 	streamDescription := getArgOrPrompt(reader, argsMap, "description", "Description (optional): ", false)
 
 	// Get platform
-	platform := getArgOrPrompt(reader, argsMap, "platform", "Platform (kafka, kinesis, pubsub, eventhubs, pulsar, rabbitmq, nats, mqtt, sqs, sns): ", true)
+	platform := getArgOrPrompt(reader, argsMap, "platform", "Platform (kafka, kinesis, pubsub, eventhubs, pulsar, rabbitmq, nats, nats_jetstream, mqtt, sqs, sns): ", true)
 	if platform == "" {
 		return fmt.Errorf("platform is required")
 	}
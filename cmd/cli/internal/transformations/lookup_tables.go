@@ -0,0 +1,384 @@
+package transformations
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// LookupTableSource is the database table the entries of a lookup table are loaded from
+type LookupTableSource struct {
+	WorkspaceName string `json:"workspace_name"`
+	DatabaseName  string `json:"database_name"`
+	TableName     string `json:"table_name"`
+	KeyColumn     string `json:"key_column"`
+	ValueColumn   string `json:"value_column"`
+}
+
+// LookupTable represents the reference data the lookup transformation maps values through
+type LookupTable struct {
+	LookupTableName        string             `json:"lookup_table_name"`
+	LookupTableDescription string             `json:"lookup_table_description,omitempty"`
+	Entries                map[string]string  `json:"entries,omitempty"`
+	EntryCount             int32              `json:"entry_count"`
+	Source                 *LookupTableSource `json:"source,omitempty"`
+	CacheTTLSeconds        int32              `json:"cache_ttl_seconds"`
+	Refreshed              string             `json:"refreshed,omitempty"`
+}
+
+// LookupTableOptions are the settings of a lookup table to add
+type LookupTableOptions struct {
+	Description     string
+	Entries         map[string]string
+	Source          *LookupTableSource
+	CacheTTLSeconds *int32
+}
+
+// LookupTableChanges are the changes to a lookup table
+type LookupTableChanges struct {
+	Description     *string
+	SetEntries      map[string]string
+	RemoveKeys      []string
+	ReplaceEntries  bool
+	CacheTTLSeconds *int32
+}
+
+// ParseLookupEntries parses entries written "key=value"
+func ParseLookupEntries(values []string) (map[string]string, error) {
+	entries := make(map[string]string, len(values))
+	for _, value := range values {
+		key, mapped, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid entry '%s': expected key=value", value)
+		}
+		entries[key] = mapped
+	}
+	return entries, nil
+}
+
+// ParseLookupEntriesFile parses the entries of a lookup table from a file: a JSON object of keys
+// and values for .json files, otherwise CSV records of a key and a value, without header
+func ParseLookupEntriesFile(path string, data []byte) (map[string]string, error) {
+	entries := make(map[string]string)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid entries file %s: expected a JSON object of string keys and values: %v", path, err)
+		}
+		return entries, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid entries file %s: expected CSV records of a key and a value: %v", path, err)
+	}
+	for _, record := range records {
+		entries[record[0]] = record[1]
+	}
+	return entries, nil
+}
+
+// ReadLookupEntries returns the entries given by --entry flags and an entries file, the flags
+// overriding the file
+func ReadLookupEntries(values []string, path string) (map[string]string, error) {
+	entries := make(map[string]string)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read entries file: %v", err)
+		}
+		if entries, err = ParseLookupEntriesFile(path, data); err != nil {
+			return nil, err
+		}
+	}
+	flagEntries, err := ParseLookupEntries(values)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range flagEntries {
+		entries[key] = value
+	}
+	return entries, nil
+}
+
+// AddLookupTable adds a lookup table with the given entries, or loaded from a database table
+func AddLookupTable(name string, options LookupTableOptions) error {
+	if name == "" {
+		return fmt.Errorf("lookup table name is required")
+	}
+	if options.Source == nil && len(options.Entries) == 0 {
+		return fmt.Errorf("give the entries of the lookup table with --entry or --entries-file, or a source table with --database and --table")
+	}
+	if options.Source != nil && len(options.Entries) > 0 {
+		return fmt.Errorf("a lookup table has either entries or a source table, not both")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	// A source database is looked up in the active workspace unless another is given
+	if options.Source != nil && options.Source.WorkspaceName == "" {
+		if err := common.ValidateWorkspace(profileInfo); err != nil {
+			return err
+		}
+		options.Source.WorkspaceName = profileInfo.Workspace
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables", profileInfo.TenantURL)
+
+	addReq := struct {
+		LookupTableName        string             `json:"lookup_table_name"`
+		LookupTableDescription string             `json:"lookup_table_description,omitempty"`
+		Entries                map[string]string  `json:"entries,omitempty"`
+		Source                 *LookupTableSource `json:"source,omitempty"`
+		CacheTTLSeconds        *int32             `json:"cache_ttl_seconds,omitempty"`
+	}{
+		LookupTableName:        name,
+		LookupTableDescription: options.Description,
+		Entries:                options.Entries,
+		Source:                 options.Source,
+		CacheTTLSeconds:        options.CacheTTLSeconds,
+	}
+
+	var response struct {
+		Message     string      `json:"message"`
+		Success     bool        `json:"success"`
+		LookupTable LookupTable `json:"lookup_table"`
+	}
+	if err := client.Post(url, addReq, &response); err != nil {
+		return fmt.Errorf("failed to add lookup table: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to add lookup table: %s", response.Message)
+	}
+
+	fmt.Printf("Added lookup table '%s' with %d entries\n", response.LookupTable.LookupTableName, response.LookupTable.EntryCount)
+	fmt.Printf("Use it in mapping rules with --transformation lookup --options '{\"table\":\"%s\"}'\n", response.LookupTable.LookupTableName)
+	return nil
+}
+
+// ListLookupTables lists the lookup tables of the tenant
+func ListLookupTables() error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables", profileInfo.TenantURL)
+
+	var response struct {
+		LookupTables []LookupTable `json:"lookup_tables"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list lookup tables: %w", err)
+	}
+
+	if len(response.LookupTables) == 0 {
+		fmt.Println("No lookup tables found.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-25s %-10s %-35s %-10s %-25s\n", "Name", "Entries", "Source", "Cache TTL", "Refreshed")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, table := range response.LookupTables {
+		fmt.Printf("%-25s %-10d %-35s %-10s %-25s\n",
+			table.LookupTableName,
+			table.EntryCount,
+			lookupSourceString(table.Source),
+			fmt.Sprintf("%ds", table.CacheTTLSeconds),
+			table.Refreshed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// ShowLookupTable shows a lookup table with its entries
+func ShowLookupTable(name string) error {
+	if name == "" {
+		return fmt.Errorf("lookup table name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables/%s", profileInfo.TenantURL, name)
+
+	var response struct {
+		LookupTable LookupTable `json:"lookup_table"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to show lookup table: %w", err)
+	}
+
+	table := response.LookupTable
+	fmt.Println()
+	fmt.Printf("Lookup Table: %s\n", table.LookupTableName)
+	if table.LookupTableDescription != "" {
+		fmt.Printf("Description:  %s\n", table.LookupTableDescription)
+	}
+	fmt.Printf("Source:       %s\n", lookupSourceString(table.Source))
+	fmt.Printf("Cache TTL:    %ds\n", table.CacheTTLSeconds)
+	if table.Refreshed != "" {
+		fmt.Printf("Refreshed:    %s\n", table.Refreshed)
+	}
+	fmt.Printf("Entries:      %d\n", table.EntryCount)
+	fmt.Println()
+
+	keys := make([]string, 0, len(table.Entries))
+	for key := range table.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Printf("%-30s %-30s\n", "Key", "Value")
+	fmt.Println(strings.Repeat("-", 61))
+	for _, key := range keys {
+		fmt.Printf("%-30s %-30s\n", key, table.Entries[key])
+	}
+	fmt.Println()
+	return nil
+}
+
+// ModifyLookupTable changes the description, entries or cache TTL of a lookup table
+func ModifyLookupTable(name string, changes LookupTableChanges) error {
+	if name == "" {
+		return fmt.Errorf("lookup table name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables/%s", profileInfo.TenantURL, name)
+
+	modifyReq := struct {
+		LookupTableDescription *string           `json:"lookup_table_description,omitempty"`
+		SetEntries             map[string]string `json:"set_entries,omitempty"`
+		RemoveKeys             []string          `json:"remove_keys,omitempty"`
+		ReplaceEntries         bool              `json:"replace_entries,omitempty"`
+		CacheTTLSeconds        *int32            `json:"cache_ttl_seconds,omitempty"`
+	}{
+		LookupTableDescription: changes.Description,
+		SetEntries:             changes.SetEntries,
+		RemoveKeys:             changes.RemoveKeys,
+		ReplaceEntries:         changes.ReplaceEntries,
+		CacheTTLSeconds:        changes.CacheTTLSeconds,
+	}
+
+	var response struct {
+		Message     string      `json:"message"`
+		Success     bool        `json:"success"`
+		LookupTable LookupTable `json:"lookup_table"`
+	}
+	if err := client.Put(url, modifyReq, &response); err != nil {
+		return fmt.Errorf("failed to modify lookup table: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to modify lookup table: %s", response.Message)
+	}
+
+	fmt.Printf("Modified lookup table '%s', which now has %d entries\n", response.LookupTable.LookupTableName, response.LookupTable.EntryCount)
+	return nil
+}
+
+// DeleteLookupTable deletes a lookup table
+func DeleteLookupTable(name string) error {
+	if name == "" {
+		return fmt.Errorf("lookup table name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables/%s", profileInfo.TenantURL, name)
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to delete lookup table: %w", err)
+	}
+
+	fmt.Printf("Deleted lookup table '%s'; mapping rules looking values up in it fail until it is added again\n", name)
+	return nil
+}
+
+// RefreshLookupTable reloads the entries of a lookup table from its source table and drops the
+// entries cached by the transformation service
+func RefreshLookupTable(name string) error {
+	if name == "" {
+		return fmt.Errorf("lookup table name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/lookup-tables/%s/refresh", profileInfo.TenantURL, name)
+
+	var response struct {
+		Message     string      `json:"message"`
+		Success     bool        `json:"success"`
+		LookupTable LookupTable `json:"lookup_table"`
+	}
+	if err := client.Post(url, struct{}{}, &response); err != nil {
+		return fmt.Errorf("failed to refresh lookup table: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to refresh lookup table: %s", response.Message)
+	}
+
+	fmt.Println(response.Message)
+	return nil
+}
+
+// lookupSourceString returns the source table of a lookup table as shown in lists
+func lookupSourceString(source *LookupTableSource) string {
+	if source == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s.%s (%s -> %s)", source.DatabaseName, source.TableName, source.KeyColumn, source.ValueColumn)
+}
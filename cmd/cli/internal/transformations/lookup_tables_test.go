@@ -0,0 +1,42 @@
+package transformations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLookupEntries(t *testing.T) {
+	entries, err := ParseLookupEntries([]string{"UK=GB", "EL=GR", "NONE="})
+	if err != nil {
+		t.Fatalf("ParseLookupEntries: %v", err)
+	}
+	if want := map[string]string{"UK": "GB", "EL": "GR", "NONE": ""}; !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries = %v, want %v", entries, want)
+	}
+	for _, invalid := range []string{"UK", "=GB"} {
+		if _, err := ParseLookupEntries([]string{invalid}); err == nil {
+			t.Errorf("expected entry %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestParseLookupEntriesFile(t *testing.T) {
+	want := map[string]string{"UK": "GB", "YU": "RS, ME"}
+
+	entries, err := ParseLookupEntriesFile("codes.json", []byte(`{"UK": "GB", "YU": "RS, ME"}`))
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Fatalf("JSON entries = %v, %v, want %v", entries, err, want)
+	}
+
+	entries, err = ParseLookupEntriesFile("codes.csv", []byte("UK,GB\nYU,\"RS, ME\"\n"))
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Fatalf("CSV entries = %v, %v, want %v", entries, err, want)
+	}
+
+	if _, err := ParseLookupEntriesFile("codes.csv", []byte("UK,GB,extra\n")); err == nil {
+		t.Error("expected a CSV record of three fields to be rejected")
+	}
+	if _, err := ParseLookupEntriesFile("codes.json", []byte(`["UK", "GB"]`)); err == nil {
+		t.Error("expected a JSON array to be rejected")
+	}
+}
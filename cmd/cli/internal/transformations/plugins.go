@@ -0,0 +1,158 @@
+package transformations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/cmd/cli/internal/common"
+)
+
+// TransformationPlugin represents a user-defined transformation served by an external gRPC
+// endpoint
+type TransformationPlugin struct {
+	TenantID           string `json:"tenant_id"`
+	PluginID           string `json:"plugin_id"`
+	PluginName         string `json:"plugin_name"`
+	PluginDescription  string `json:"plugin_description,omitempty"`
+	PluginKind         string `json:"plugin_kind"`
+	Endpoint           string `json:"endpoint,omitempty"`
+	TransformationType string `json:"transformation_type"`
+	InputType          string `json:"input_type,omitempty"`
+	OutputType         string `json:"output_type,omitempty"`
+	Cardinality        string `json:"cardinality"`
+	TimeoutSeconds     int32  `json:"timeout_seconds"`
+	OwnerID            string `json:"owner_id,omitempty"`
+}
+
+// PluginOptions are the settings of a plugin to register
+type PluginOptions struct {
+	Description    string
+	Kind           string
+	Endpoint       string
+	Type           string
+	InputType      string
+	OutputType     string
+	TimeoutSeconds int32
+}
+
+// RegisterPlugin registers a transformation plugin, usable by name in mapping rules once
+// registered
+func RegisterPlugin(name string, options PluginOptions) error {
+	if name == "" {
+		return fmt.Errorf("plugin name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/plugins", profileInfo.TenantURL)
+
+	registerReq := struct {
+		PluginName         string `json:"plugin_name"`
+		PluginDescription  string `json:"plugin_description,omitempty"`
+		PluginKind         string `json:"plugin_kind,omitempty"`
+		Endpoint           string `json:"endpoint,omitempty"`
+		TransformationType string `json:"transformation_type,omitempty"`
+		InputType          string `json:"input_type,omitempty"`
+		OutputType         string `json:"output_type,omitempty"`
+		TimeoutSeconds     int32  `json:"timeout_seconds,omitempty"`
+	}{
+		PluginName:         name,
+		PluginDescription:  options.Description,
+		PluginKind:         options.Kind,
+		Endpoint:           options.Endpoint,
+		TransformationType: options.Type,
+		InputType:          options.InputType,
+		OutputType:         options.OutputType,
+		TimeoutSeconds:     options.TimeoutSeconds,
+	}
+
+	var response struct {
+		Message string               `json:"message"`
+		Success bool                 `json:"success"`
+		Plugin  TransformationPlugin `json:"plugin"`
+	}
+	if err := client.Post(url, registerReq, &response); err != nil {
+		return fmt.Errorf("failed to register transformation plugin: %w", err)
+	}
+	if !response.Success {
+		return fmt.Errorf("failed to register transformation plugin: %s", response.Message)
+	}
+
+	fmt.Printf("Registered transformation plugin '%s' (%s, %s) at %s\n", response.Plugin.PluginName, response.Plugin.TransformationType, response.Plugin.Cardinality, response.Plugin.Endpoint)
+	return nil
+}
+
+// ListPlugins lists the transformation plugins of the tenant
+func ListPlugins() error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/plugins", profileInfo.TenantURL)
+
+	var response struct {
+		Plugins []TransformationPlugin `json:"plugins"`
+	}
+	if err := client.Get(url, &response); err != nil {
+		return fmt.Errorf("failed to list transformation plugins: %w", err)
+	}
+
+	if len(response.Plugins) == 0 {
+		fmt.Println("No transformation plugins found.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-25s %-15s %-30s %-20s %-8s\n", "Name", "Type", "Endpoint", "Types", "Timeout")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, plugin := range response.Plugins {
+		types := plugin.InputType + " -> " + plugin.OutputType
+		fmt.Printf("%-25s %-15s %-30s %-20s %-8s\n",
+			plugin.PluginName,
+			plugin.TransformationType,
+			plugin.Endpoint,
+			types,
+			fmt.Sprintf("%ds", plugin.TimeoutSeconds))
+	}
+	fmt.Println()
+	return nil
+}
+
+// DeletePlugin deletes a transformation plugin
+func DeletePlugin(name string) error {
+	if name == "" {
+		return fmt.Errorf("plugin name is required")
+	}
+
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/transformations/plugins/%s", profileInfo.TenantURL, name)
+	if err := client.Delete(url); err != nil {
+		return fmt.Errorf("failed to delete transformation plugin: %w", err)
+	}
+
+	fmt.Printf("Deleted transformation plugin '%s'; mapping rules using it fail until it is registered again\n", name)
+	return nil
+}
@@ -152,6 +152,9 @@ func ListTransformations(verbose bool) error {
 			case "null_returning":
 				typeDisplay = "Null-Returning"
 			}
+			if !t.IsBuiltin {
+				typeDisplay += " (plugin)"
+			}
 
 			fmt.Printf("%-25s %-45s %-15s\n",
 				t.TransformationName,
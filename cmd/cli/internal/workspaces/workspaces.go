@@ -49,6 +49,26 @@ type UpdateWorkspaceResponse struct {
 	Status    string    `json:"status"`
 }
 
+// HealthCheck is one factor of the health score of a workspace
+type HealthCheck struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Score   int      `json:"score"`
+	Weight  int      `json:"weight"`
+	Message string   `json:"message"`
+	Items   []string `json:"items"`
+}
+
+// HealthResponse wraps the API response for the health of a workspace
+type HealthResponse struct {
+	WorkspaceName string        `json:"workspace_name"`
+	Score         int           `json:"score"`
+	Status        string        `json:"status"`
+	Ready         bool          `json:"ready"`
+	Checks        []HealthCheck `json:"checks"`
+	Computed      string        `json:"computed"`
+}
+
 type CreateWorkspaceRequest struct {
 	Name        string `json:"workspace_name"`
 	Description string `json:"workspace_description,omitempty"`
@@ -150,6 +170,53 @@ func ShowWorkspace(workspaceName string) error {
 	return nil
 }
 
+// WorkspaceStatus displays the health score and checks of a workspace
+func WorkspaceStatus(workspaceName string) error {
+	profileInfo, err := common.GetActiveProfileInfo()
+	if err != nil {
+		return err
+	}
+
+	client, err := common.GetProfileClient()
+	if err != nil {
+		return err
+	}
+	url := common.BuildAPIURL(profileInfo, fmt.Sprintf("/workspaces/%s/health", workspaceName))
+
+	var health HealthResponse
+	if err := client.Get(url, &health); err != nil {
+		return fmt.Errorf("failed to get workspace health: %v", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Workspace: %s\n", health.WorkspaceName)
+	fmt.Printf("Score: %d/100 (%s)\n", health.Score, health.Status)
+	fmt.Printf("Ready: %t\n", health.Ready)
+	fmt.Printf("Computed: %s\n", health.Computed)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "Check\tStatus\tScore\tWeight\tDetails")
+	fmt.Fprintln(w, "-----\t------\t-----\t------\t-------")
+	for _, check := range health.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", check.Name, check.Status, check.Score, check.Weight, check.Message)
+	}
+	_ = w.Flush()
+
+	for _, check := range health.Checks {
+		if len(check.Items) == 0 {
+			continue
+		}
+		fmt.Println()
+		fmt.Printf("%s:\n", check.Name)
+		for _, item := range check.Items {
+			fmt.Printf("  - %s\n", item)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
 // AddWorkspace creates a new workspace
 func AddWorkspace(args []string) error {
 	reader := bufio.NewReader(os.Stdin)
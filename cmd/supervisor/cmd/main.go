@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -22,8 +23,13 @@ import (
 	"github.com/redbco/redb-open/cmd/supervisor/internal/initialize"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/logger"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/manager"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/scaling"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/status"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
 	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/redact"
+	"github.com/redbco/redb-open/pkg/spiffe"
 )
 
 var (
@@ -38,6 +44,7 @@ var (
 	initializeFlag     = flag.Bool("initialize", false, "Initialize the reDB node (database, keys, etc.)")
 	autoInitializeFlag = flag.Bool("autoinitialize", false, "Auto-initialize the reDB node without prompts (for Docker/headless environments)")
 	versionFlag        = flag.Bool("version", false, "Show version information and exit")
+	printConfigFlag    = flag.Bool("print-effective-config", false, "Print the configuration with defaults applied and exit")
 )
 
 func printVersionInfo() {
@@ -70,6 +77,17 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Handle print effective config flag
+	if *printConfigFlag {
+		data, err := cfg.EffectiveYAML()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print config: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
+		os.Exit(0)
+	}
+
 	// Set environment variables so they're available to all services and during initialization
 	os.Setenv("REDB_DATABASE_NAME", cfg.Database.Name)
 	if cfg.Database.User != "" {
@@ -84,6 +102,15 @@ func main() {
 	if cfg.InstanceGroup.GroupID != "" {
 		os.Setenv("REDB_INSTANCE_GROUP_ID", cfg.InstanceGroup.GroupID)
 	}
+	if cfg.Security.SPIFFE.Enabled {
+		os.Setenv(spiffe.EnvEnabled, "true")
+		os.Setenv(spiffe.EnvSocketPath, cfg.Security.SPIFFE.SocketPath)
+		os.Setenv(spiffe.EnvTrustDomain, cfg.Security.SPIFFE.TrustDomain)
+		os.Setenv(spiffe.EnvAllowedIDs, strings.Join(cfg.Security.SPIFFE.AllowedIDs, ","))
+	}
+	if capabilities := cfg.DatabaseCapabilitiesJSON(); capabilities != "" {
+		os.Setenv(dbcapabilities.EnvConfig, capabilities)
+	}
 
 	// Handle initialization mode
 	if *initializeFlag {
@@ -174,6 +201,7 @@ func main() {
 		serviceManager:   serviceManager,
 		healthMonitor:    health.NewMonitor(log),
 		logStore:         logger.NewStore(cfg.Logging.RetentionDays),
+		recommender:      scaling.New(cfg, serviceManager),
 		readinessManager: manager.NewReadinessManager(log, serviceManager),
 		shutdownCh:       make(chan struct{}),
 	}
@@ -191,8 +219,10 @@ type Supervisor struct {
 	serviceManager   *manager.ServiceManager
 	healthMonitor    *health.Monitor
 	logStore         *logger.Store
+	recommender      *scaling.Recommender
 	readinessManager *manager.ReadinessManager
 	grpcServer       *grpc.Server
+	statusServer     *status.Server
 	shutdownCh       chan struct{}
 	wg               sync.WaitGroup
 	backgroundCtx    context.Context
@@ -211,11 +241,33 @@ func (s *Supervisor) Run(ctx context.Context) error {
 	// Create a separate context for background routines that we can cancel during shutdown
 	s.backgroundCtx, s.backgroundCancel = context.WithCancel(context.Background())
 
+	// Obtain the workload identity used for mTLS with the services
+	spiffeCtx, spiffeCancel := context.WithTimeout(ctx, 30*time.Second)
+	err := spiffe.Init(spiffeCtx, spiffe.ConfigFromEnv())
+	spiffeCancel()
+	if err != nil {
+		return fmt.Errorf("failed to obtain SPIFFE identity: %w", err)
+	}
+	if identity := spiffe.Current(); identity != nil {
+		if id, err := identity.ID(); err == nil {
+			s.logger.Infof("Using SPIFFE identity %s for service connections", id)
+		}
+	}
+
 	// Start gRPC server
 	if err := s.startGRPCServer(); err != nil {
 		return fmt.Errorf("failed to start gRPC server: %w", err)
 	}
 
+	// Start the status page of the node if enabled
+	if s.config.Supervisor.StatusPage.Enabled {
+		s.statusServer = status.New(s.config, s.healthMonitor, s.recommender, Version, s.logger)
+		if err := s.statusServer.Start(); err != nil {
+			s.logger.Errorf("Failed to start status page: %v", err)
+			s.statusServer = nil
+		}
+	}
+
 	// Start health monitor with background context
 	s.wg.Add(1)
 	go func() {
@@ -285,6 +337,11 @@ func (s *Supervisor) startGRPCServer() error {
 		MinTime:             5 * time.Second,
 		PermitWithoutStream: true,
 	}))
+	opts = append(opts, spiffe.ServerOptions()...)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(redact.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(redact.StreamServerInterceptor()),
+	)
 
 	s.grpcServer = grpc.NewServer(opts...)
 
@@ -293,6 +350,7 @@ func (s *Supervisor) startGRPCServer() error {
 		s.serviceManager,
 		s.healthMonitor,
 		s.logStore,
+		s.recommender,
 		s.logger,
 	)
 	supervisorv1.RegisterSupervisorServiceServer(s.grpcServer, supervisorServer)
@@ -395,6 +453,13 @@ func (s *Supervisor) shutdown(_ context.Context) error {
 		}
 	}
 
+	// Stop the status page
+	if s.statusServer != nil {
+		if err := s.statusServer.Shutdown(shutdownCtx); err != nil {
+			s.logger.Warnf("Error stopping status page: %v", err)
+		}
+	}
+
 	// Step 4: Signal shutdown to background routines
 	s.logger.Info("Stopping background routines...")
 	s.backgroundCancel() // Cancel the background context first
@@ -415,6 +480,7 @@ func (s *Supervisor) shutdown(_ context.Context) error {
 		s.logger.Warn("Background routines shutdown timeout (5s) - forcing shutdown")
 	}
 
+	spiffe.Shutdown()
 	s.logger.Info("Supervisor shutdown complete")
 
 	// Close logger if it supports it
@@ -551,6 +617,9 @@ func (s *Supervisor) initializeDatabaseConnection(ctx context.Context) error {
 			ConnectionTimeout: 5 * time.Second,
 		}
 	}
+	if s.config.Database.MaxConnections > 0 {
+		dbConfig.MaxConnections = int32(s.config.Database.MaxConnections)
+	}
 
 	// Initialize database connection
 	db, err := database.New(ctx, dbConfig)
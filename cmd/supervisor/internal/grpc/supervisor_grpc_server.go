@@ -10,6 +10,9 @@ import (
 	"github.com/redbco/redb-open/cmd/supervisor/internal/health"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/logger"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/manager"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/scaling"
+	"github.com/redbco/redb-open/pkg/redact"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type SupervisorServer struct {
@@ -18,6 +21,7 @@ type SupervisorServer struct {
 	serviceManager *manager.ServiceManager
 	healthMonitor  *health.Monitor
 	logStore       *logger.Store
+	recommender    *scaling.Recommender
 	logger         logger.LoggerInterface
 }
 
@@ -25,12 +29,14 @@ func NewSupervisorServer(
 	serviceManager *manager.ServiceManager,
 	healthMonitor *health.Monitor,
 	logStore *logger.Store,
+	recommender *scaling.Recommender,
 	log logger.LoggerInterface,
 ) *SupervisorServer {
 	return &SupervisorServer{
 		serviceManager: serviceManager,
 		healthMonitor:  healthMonitor,
 		logStore:       logStore,
+		recommender:    recommender,
 		logger:         log,
 	}
 }
@@ -135,6 +141,12 @@ func (s *SupervisorServer) StreamLogs(stream supervisorv1.SupervisorService_Stre
 			return err
 		}
 
+		// Scrub the entry again, as not all services log through pkg/logger
+		if req.Entry != nil {
+			req.Entry.Message = redact.String(req.Entry.Message)
+			req.Entry.Fields = redact.Fields(req.Entry.Fields)
+		}
+
 		// Store log entry
 		s.logStore.Store(req.Entry)
 
@@ -192,3 +204,23 @@ func (s *SupervisorServer) WatchServiceHealth(req *supervisorv1.WatchServiceHeal
 		}
 	}
 }
+
+func (s *SupervisorServer) GetScalingRecommendations(ctx context.Context, req *supervisorv1.GetScalingRecommendationsRequest) (*supervisorv1.GetScalingRecommendationsResponse, error) {
+	resp := &supervisorv1.GetScalingRecommendationsResponse{}
+	for _, rec := range s.recommender.Recommend(time.Now(), req.ServiceNames...) {
+		resp.Recommendations = append(resp.Recommendations, &supervisorv1.ScalingRecommendation{
+			ServiceName:         rec.Service,
+			CurrentReplicas:     int32(rec.CurrentReplicas),
+			HealthyReplicas:     int32(rec.HealthyReplicas),
+			RecommendedReplicas: int32(rec.RecommendedReplicas),
+			MinReplicas:         int32(rec.MinReplicas),
+			MaxReplicas:         int32(rec.MaxReplicas),
+			CpuUsagePercent:     rec.CPUUsagePercent,
+			QueueDepth:          rec.QueueDepth,
+			PendingRuns:         rec.PendingRuns,
+			Reason:              rec.Reason,
+			ComputedAt:          timestamppb.New(rec.ComputedAt),
+		})
+	}
+	return resp, nil
+}
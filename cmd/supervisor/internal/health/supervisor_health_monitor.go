@@ -17,14 +17,35 @@ type ServiceHealth struct {
 	Status      commonv1.HealthStatus
 	LastUpdate  time.Time
 	LastHealthy time.Time
+	// HealthySince is when the service last became healthy, zero while it is not healthy
+	HealthySince time.Time
 }
 
+// Incident is a period in which a service was not healthy
+type Incident struct {
+	ServiceID   string
+	ServiceName string
+	Status      commonv1.HealthStatus // Worst status of the service during the incident
+	StartedAt   time.Time
+	ResolvedAt  time.Time // Zero while the incident is ongoing
+}
+
+// Ongoing returns true if the service has not recovered yet
+func (i Incident) Ongoing() bool {
+	return i.ResolvedAt.IsZero()
+}
+
+// DefaultIncidentHistory is the number of incidents a monitor keeps unless configured
+const DefaultIncidentHistory = 100
+
 type Monitor struct {
 	mu          sync.RWMutex
 	services    map[string]*ServiceHealth
 	logger      logger.LoggerInterface
 	subscribers map[chan *supervisorv1.ServiceHealthUpdate][]string
 	commands    map[string][]*supervisorv1.ServiceCommand
+	incidents   []*Incident // Oldest first
+	maxHistory  int
 }
 
 func NewMonitor(log logger.LoggerInterface) *Monitor {
@@ -33,9 +54,22 @@ func NewMonitor(log logger.LoggerInterface) *Monitor {
 		logger:      log,
 		subscribers: make(map[chan *supervisorv1.ServiceHealthUpdate][]string),
 		commands:    make(map[string][]*supervisorv1.ServiceCommand),
+		maxHistory:  DefaultIncidentHistory,
 	}
 }
 
+// SetIncidentHistory sets the number of incidents kept, older incidents are dropped
+func (m *Monitor) SetIncidentHistory(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if size <= 0 {
+		size = DefaultIncidentHistory
+	}
+	m.maxHistory = size
+	m.trimIncidents()
+}
+
 func (m *Monitor) Start(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -69,6 +103,11 @@ func (m *Monitor) RemoveService(serviceID string) {
 
 	delete(m.services, serviceID)
 	delete(m.commands, serviceID)
+
+	// A service that unregisters was stopped, its incident ends with it
+	if incident := m.openIncident(serviceID); incident != nil {
+		incident.ResolvedAt = time.Now()
+	}
 }
 
 func (m *Monitor) UpdateHealth(serviceID string, status commonv1.HealthStatus) {
@@ -80,20 +119,93 @@ func (m *Monitor) UpdateHealth(serviceID string, status commonv1.HealthStatus) {
 		return
 	}
 
+	now := time.Now()
 	oldStatus := health.Status
 	health.Status = status
-	health.LastUpdate = time.Now()
+	health.LastUpdate = now
 
 	if status == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY {
-		health.LastHealthy = time.Now()
+		health.LastHealthy = now
+		if oldStatus != status {
+			health.HealthySince = now
+		}
+	} else {
+		health.HealthySince = time.Time{}
 	}
 
 	// Notify subscribers if status changed
 	if oldStatus != status {
+		m.recordIncident(health, now)
 		m.notifySubscribers(serviceID, oldStatus, status)
 	}
 }
 
+// Services returns a copy of the health of the registered services
+func (m *Monitor) Services() []ServiceHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	services := make([]ServiceHealth, 0, len(m.services))
+	for _, svc := range m.services {
+		services = append(services, *svc)
+	}
+	return services
+}
+
+// Incidents returns a copy of the incident history, most recent first
+func (m *Monitor) Incidents() []Incident {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	incidents := make([]Incident, 0, len(m.incidents))
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		incidents = append(incidents, *m.incidents[i])
+	}
+	return incidents
+}
+
+// recordIncident opens an incident when a service stops being healthy, escalates it while
+// the service gets worse and resolves it when the service is healthy again. Services that are
+// still starting have no incidents.
+func (m *Monitor) recordIncident(svc *ServiceHealth, now time.Time) {
+	incident := m.openIncident(svc.ServiceID)
+
+	switch svc.Status {
+	case commonv1.HealthStatus_HEALTH_STATUS_HEALTHY:
+		if incident != nil {
+			incident.ResolvedAt = now
+		}
+	case commonv1.HealthStatus_HEALTH_STATUS_DEGRADED, commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY:
+		if incident == nil {
+			m.incidents = append(m.incidents, &Incident{
+				ServiceID:   svc.ServiceID,
+				ServiceName: svc.ServiceName,
+				Status:      svc.Status,
+				StartedAt:   now,
+			})
+			m.trimIncidents()
+		} else if svc.Status == commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY {
+			incident.Status = svc.Status
+		}
+	}
+}
+
+// openIncident returns the ongoing incident of a service, or nil
+func (m *Monitor) openIncident(serviceID string) *Incident {
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		if m.incidents[i].ServiceID == serviceID && m.incidents[i].Ongoing() {
+			return m.incidents[i]
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) trimIncidents() {
+	if excess := len(m.incidents) - m.maxHistory; excess > 0 {
+		m.incidents = append([]*Incident(nil), m.incidents[excess:]...)
+	}
+}
+
 func (m *Monitor) Subscribe(serviceIDs []string) chan *supervisorv1.ServiceHealthUpdate {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -404,6 +404,11 @@ func (i *Initializer) AutoInitialize(ctx context.Context) error {
 	}
 
 	if isComplete {
+		// Existing installations still need the schema changes of newer versions
+		if err := i.createDatabaseSchema(ctx, prodCreds); err != nil {
+			return fmt.Errorf("failed to upgrade database schema: %w", err)
+		}
+
 		i.logger.Info("System is already fully initialized, skipping initialization steps")
 		i.logger.Info("Node auto-initialization completed successfully!")
 		i.logger.Info("Database schema and node setup completed.")
@@ -966,7 +971,7 @@ func (i *Initializer) createDatabaseSchema(ctx context.Context, creds *DatabaseC
 
 	if schemaExists {
 		i.logger.Info("Database schema already exists, skipping schema creation")
-		return nil
+		return i.applySchemaUpgrades(ctx, conn)
 	}
 
 	// Create the ulid domain separately (may fail on shared PostgreSQL instances)
@@ -986,6 +991,16 @@ func (i *Initializer) createDatabaseSchema(ctx context.Context, creds *DatabaseC
 	}
 
 	i.logger.Info("Successfully created database schema and indexes")
+	return i.applySchemaUpgrades(ctx, conn)
+}
+
+// applySchemaUpgrades adds the tables and columns introduced since an existing schema was created
+func (i *Initializer) applySchemaUpgrades(ctx context.Context, conn *pgx.Conn) error {
+	if _, err := conn.Exec(ctx, DatabaseUpgrades); err != nil {
+		return fmt.Errorf("failed to apply database schema upgrades: %w", err)
+	}
+
+	i.logger.Info("Database schema is up to date")
 	return nil
 }
 
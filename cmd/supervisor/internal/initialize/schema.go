@@ -372,6 +372,17 @@ CREATE TABLE streams (
     updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Checkpoints of consumers on stream platforms that leave checkpointing to the application
+CREATE TABLE stream_checkpoints (
+    stream_id ulid NOT NULL REFERENCES streams(stream_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    consumer_id VARCHAR(255) NOT NULL,
+    topic_name VARCHAR(255) NOT NULL,
+    shard_id VARCHAR(255) NOT NULL,
+    position TEXT NOT NULL,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stream_id, consumer_id, topic_name, shard_id)
+);
+
 -- =============================================================================
 -- WORKSPACE AND DEVELOPMENT STRUCTURE
 -- =============================================================================
@@ -449,6 +460,10 @@ CREATE TABLE instances (
     instance_ssl_cert VARCHAR(255),
     instance_ssl_key VARCHAR(255),
     instance_ssl_root_cert VARCHAR(255),
+    instance_ssh_tunnel JSONB,
+    instance_iam_auth JSONB,
+    instance_socket_path VARCHAR(255),
+    instance_kerberos_auth JSONB,
     policy_ids ulid[] NOT NULL DEFAULT '{}',
     instance_metadata JSONB NOT NULL DEFAULT '{}',
     instance_databases JSONB NOT NULL DEFAULT '{}',
@@ -476,6 +491,10 @@ CREATE TABLE databases (
     database_password TEXT NOT NULL,
     database_db_name VARCHAR(255) NOT NULL,
     database_enabled BOOLEAN DEFAULT true,
+    database_replicas JSONB NOT NULL DEFAULT '[]',
+    database_routing_policy VARCHAR(50) NOT NULL DEFAULT 'primary' CHECK (database_routing_policy IN ('primary', 'reads-from-replica')),
+    database_rate_limits JSONB,
+    database_labels JSONB NOT NULL DEFAULT '{}',
     policy_ids ulid[] NOT NULL DEFAULT '{}',
     database_metadata JSONB NOT NULL DEFAULT '{}',
     database_schema JSONB NOT NULL DEFAULT '{}',
@@ -674,6 +693,67 @@ CREATE TABLE replication_sources (
     UNIQUE(workspace_id, database_id, table_name)
 );
 
+-- Cutovers of applications from the source to the target database of a relationship
+CREATE TABLE cutovers (
+    cutover_id ulid PRIMARY KEY DEFAULT generate_ulid('cutover'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    cutover_name VARCHAR(255) NOT NULL,
+    cutover_description TEXT DEFAULT '',
+    relationship_id ulid NOT NULL REFERENCES relationships(relationship_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    connection_name VARCHAR(255) NOT NULL,
+    stop_writes_hook TEXT NOT NULL DEFAULT '',
+    unpause_hook TEXT NOT NULL DEFAULT '',
+    gates TEXT[] NOT NULL DEFAULT '{}',
+    max_retries INTEGER NOT NULL DEFAULT 3 CHECK (max_retries >= 0),
+    drain_timeout_seconds INTEGER NOT NULL DEFAULT 600 CHECK (drain_timeout_seconds > 0),
+    cutover_state VARCHAR(32) NOT NULL DEFAULT 'pending',
+    current_step VARCHAR(32) NOT NULL DEFAULT '',
+    cutover_steps JSONB NOT NULL DEFAULT '[]',
+    owner_id ulid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workspace_id, cutover_name)
+);
+
+-- Connection info of applications, pointing to the database they use; switched by cutovers
+CREATE TABLE application_connections (
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    connection_name VARCHAR(255) NOT NULL,
+    database_id ulid NOT NULL REFERENCES databases(database_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    previous_database_id ulid REFERENCES databases(database_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    switched_by_cutover_id ulid REFERENCES cutovers(cutover_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, connection_name)
+);
+
+-- Query proxies routing the statements of applications between the source and target database of a relationship
+CREATE TABLE query_proxies (
+    proxy_id ulid PRIMARY KEY DEFAULT generate_ulid('proxy'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    proxy_name VARCHAR(255) NOT NULL,
+    proxy_description TEXT DEFAULT '',
+    relationship_id ulid NOT NULL REFERENCES relationships(relationship_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    proxy_protocol VARCHAR(32) NOT NULL,
+    node_id BIGINT NOT NULL REFERENCES nodes(node_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    listen_port INTEGER NOT NULL CHECK (listen_port > 0 AND listen_port < 65536),
+    proxy_username VARCHAR(255) NOT NULL,
+    proxy_password TEXT NOT NULL,
+    read_percent INTEGER NOT NULL DEFAULT 0 CHECK (read_percent >= 0 AND read_percent <= 100),
+    write_percent INTEGER NOT NULL DEFAULT 0 CHECK (write_percent >= 0 AND write_percent <= 100),
+    table_routes JSONB NOT NULL DEFAULT '{}',
+    owner_id ulid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    status_message VARCHAR(255) DEFAULT '',
+    status status_enum DEFAULT 'STATUS_STOPPED',
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workspace_id, proxy_name),
+    UNIQUE(node_id, listen_port)
+);
+
 -- Data transformations
 CREATE TABLE transformations (
     transformation_id ulid PRIMARY KEY DEFAULT generate_ulid('transform'),
@@ -737,6 +817,13 @@ CREATE TABLE transformation_workflow_edges (
     UNIQUE(mapping_rule_id, source_node_id, source_output_name, target_node_id, target_input_name)
 );
 
+-- Protobuf descriptor sets registered for payload decoding, addressed by content hash
+CREATE TABLE transformation_descriptor_sets (
+    descriptor_id VARCHAR(64) PRIMARY KEY,
+    descriptor_set BYTEA NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- =============================================================================
 -- INTEGRATIONS
 -- =============================================================================
@@ -778,6 +865,59 @@ CREATE TABLE integration_jobs (
     completed TIMESTAMP
 );
 
+-- Users and groups managed by LDAP sync integrations
+CREATE TABLE integration_directory_objects (
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    object_type VARCHAR(16) NOT NULL,
+    object_id ulid NOT NULL,
+    directory_dn TEXT NOT NULL DEFAULT '',
+    -- Stable identifier of the directory entry (entryUUID, objectGUID) the object is linked to
+    directory_id TEXT NOT NULL DEFAULT '',
+    synced TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (integration_id, object_type, object_id)
+);
+
+-- Files handled by file drop integrations, used to skip duplicates across restarts
+CREATE TABLE integration_file_arrivals (
+    arrival_id BIGSERIAL PRIMARY KEY,
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    file_name TEXT NOT NULL,
+    remote_path TEXT NOT NULL DEFAULT '',
+    file_size BIGINT NOT NULL DEFAULT 0,
+    file_mtime TIMESTAMP,
+    sha256 VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    moved_to TEXT NOT NULL DEFAULT '',
+    rows_processed BIGINT NOT NULL DEFAULT 0,
+    error_message TEXT NOT NULL DEFAULT '',
+    received TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Runs of integrations, e.g. LDAP syncs; pruned by the run_history retention policy
+CREATE TABLE integration_runs (
+    run_id ulid PRIMARY KEY DEFAULT generate_ulid('intrun'),
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    operation VARCHAR(255) NOT NULL,
+    status VARCHAR(64) NOT NULL,
+    summary JSONB NOT NULL DEFAULT '{}',
+    error_message TEXT NOT NULL DEFAULT '',
+    started TIMESTAMP NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Log lines of integration runs; pruned by the run_log retention policy
+CREATE TABLE integration_run_logs (
+    log_id BIGSERIAL PRIMARY KEY,
+    run_id ulid NOT NULL REFERENCES integration_runs(run_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    log_level VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- =============================================================================
 -- MCP (MODEL CONTEXT PROTOCOL) SYSTEM
 -- =============================================================================
@@ -1281,6 +1421,35 @@ CREATE TABLE data_product_items (
     PRIMARY KEY (product_id, resource_item_id)
 );
 
+-- =============================================================================
+-- ARTIFACTS
+-- =============================================================================
+
+-- Large artifacts (schema snapshots, dead-letter payloads, exports, diagnostics bundles)
+-- kept in the blob store, with their metadata here
+CREATE TABLE artifacts (
+    artifact_id ulid PRIMARY KEY DEFAULT generate_ulid('artifact'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+    artifact_kind VARCHAR(50) NOT NULL,
+    artifact_name VARCHAR(255) NOT NULL,
+    blob_key TEXT NOT NULL UNIQUE,
+    content_type VARCHAR(255) DEFAULT 'application/octet-stream',
+    size_bytes BIGINT DEFAULT 0,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Retention policies of tenants; resource types without a policy use the configured default
+CREATE TABLE retention_policies (
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    resource_type VARCHAR(50) NOT NULL CHECK (resource_type IN ('run_history', 'run_log', 'dead_letter', 'schema_version')),
+    keep_days INTEGER NOT NULL DEFAULT 0 CHECK (keep_days >= 0),
+    keep_versions INTEGER NOT NULL DEFAULT 0 CHECK (keep_versions >= 0),
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, resource_type)
+);
+
 `
 
 // DatabaseIndexes contains the performance indexes for the database
@@ -1378,6 +1547,12 @@ CREATE INDEX idx_integrations_type ON integrations(integration_type);
 CREATE INDEX idx_integrations_name ON integrations(integration_name);
 CREATE INDEX idx_integration_jobs_integration ON integration_jobs(integration_id);
 CREATE INDEX idx_integration_jobs_status ON integration_jobs(status);
+CREATE UNIQUE INDEX idx_integration_directory_objects_entry ON integration_directory_objects(integration_id, object_type, directory_id) WHERE directory_id <> '';
+CREATE INDEX idx_integration_runs_tenant_integration ON integration_runs(tenant_id, integration_id, created);
+CREATE INDEX idx_integration_run_logs_tenant_integration ON integration_run_logs(tenant_id, integration_id, created);
+CREATE INDEX idx_integration_run_logs_run_id ON integration_run_logs(run_id);
+CREATE INDEX idx_integration_file_arrivals_file ON integration_file_arrivals(integration_id, remote_path, file_size, file_mtime);
+CREATE INDEX idx_integration_file_arrivals_hash ON integration_file_arrivals(integration_id, sha256);
 
 -- Authorization system queries
 CREATE INDEX idx_groups_tenant_id ON groups(tenant_id);
@@ -1539,6 +1714,10 @@ CREATE INDEX idx_data_product_items_product_id ON data_product_items(product_id)
 CREATE INDEX idx_data_product_items_resource_item_id ON data_product_items(resource_item_id);
 CREATE INDEX idx_data_product_items_order ON data_product_items(product_id, item_order);
 
+-- Artifact listing and retention
+CREATE INDEX idx_artifacts_tenant_workspace ON artifacts(tenant_id, workspace_id);
+CREATE INDEX idx_artifacts_kind_created ON artifacts(artifact_kind, created);
+
 -- Stream queries
 CREATE INDEX idx_streams_tenant_id ON streams(tenant_id);
 CREATE INDEX idx_streams_node_id ON streams(connected_to_node_id);
@@ -1548,3 +1727,286 @@ CREATE INDEX idx_streams_config_gin ON streams USING gin(connection_config);
 CREATE INDEX idx_streams_metadata_gin ON streams USING gin(stream_metadata);
 
 `
+
+// DatabaseUpgrades brings the schema of an existing installation up to date. The statements are
+// idempotent and run on every initialization, after DatabaseSchema on a fresh database.
+const DatabaseUpgrades = `
+-- Read replica routing
+ALTER TABLE databases ADD COLUMN IF NOT EXISTS database_replicas JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE databases ADD COLUMN IF NOT EXISTS database_routing_policy VARCHAR(50) NOT NULL DEFAULT 'primary' CHECK (database_routing_policy IN ('primary', 'reads-from-replica'));
+
+-- File drop arrival ledger
+CREATE TABLE IF NOT EXISTS integration_file_arrivals (
+    arrival_id BIGSERIAL PRIMARY KEY,
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    file_name TEXT NOT NULL,
+    remote_path TEXT NOT NULL DEFAULT '',
+    file_size BIGINT NOT NULL DEFAULT 0,
+    file_mtime TIMESTAMP,
+    sha256 VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    moved_to TEXT NOT NULL DEFAULT '',
+    rows_processed BIGINT NOT NULL DEFAULT 0,
+    error_message TEXT NOT NULL DEFAULT '',
+    received TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_integration_file_arrivals_file ON integration_file_arrivals(integration_id, remote_path, file_size, file_mtime);
+CREATE INDEX IF NOT EXISTS idx_integration_file_arrivals_hash ON integration_file_arrivals(integration_id, sha256);
+
+-- Protobuf descriptor sets for payload decoding
+CREATE TABLE IF NOT EXISTS transformation_descriptor_sets (
+    descriptor_id VARCHAR(64) PRIMARY KEY,
+    descriptor_set BYTEA NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- LDAP sync links by stable directory identifier
+ALTER TABLE integration_directory_objects ADD COLUMN IF NOT EXISTS directory_id TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_integration_directory_objects_entry ON integration_directory_objects(integration_id, object_type, directory_id) WHERE directory_id <> '';
+
+-- Integration run history and logs
+CREATE TABLE IF NOT EXISTS integration_runs (
+    run_id ulid PRIMARY KEY DEFAULT generate_ulid('intrun'),
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    operation VARCHAR(255) NOT NULL,
+    status VARCHAR(64) NOT NULL,
+    summary JSONB NOT NULL DEFAULT '{}',
+    error_message TEXT NOT NULL DEFAULT '',
+    started TIMESTAMP NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS integration_run_logs (
+    log_id BIGSERIAL PRIMARY KEY,
+    run_id ulid NOT NULL REFERENCES integration_runs(run_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    integration_id ulid NOT NULL REFERENCES integrations(integration_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    log_level VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_integration_runs_tenant_integration ON integration_runs(tenant_id, integration_id, created);
+CREATE INDEX IF NOT EXISTS idx_integration_run_logs_tenant_integration ON integration_run_logs(tenant_id, integration_id, created);
+CREATE INDEX IF NOT EXISTS idx_integration_run_logs_run_id ON integration_run_logs(run_id);
+
+-- Retention applies to integration run logs instead of the audit log
+CREATE TABLE IF NOT EXISTS retention_policies (
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    resource_type VARCHAR(50) NOT NULL,
+    keep_days INTEGER NOT NULL DEFAULT 0 CHECK (keep_days >= 0),
+    keep_versions INTEGER NOT NULL DEFAULT 0 CHECK (keep_versions >= 0),
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, resource_type)
+);
+DELETE FROM retention_policies WHERE resource_type = 'audit_log';
+ALTER TABLE retention_policies DROP CONSTRAINT IF EXISTS retention_policies_resource_type_check;
+ALTER TABLE retention_policies ADD CONSTRAINT retention_policies_resource_type_check
+    CHECK (resource_type IN ('run_history', 'run_log', 'dead_letter', 'schema_version'));
+
+-- SSH tunnels through jump hosts
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS instance_ssh_tunnel JSONB;
+
+-- Cloud IAM authentication with short-lived tokens
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS instance_iam_auth JSONB;
+
+-- Per-database rate limits
+ALTER TABLE databases ADD COLUMN IF NOT EXISTS database_rate_limits JSONB;
+
+-- Cutovers and the application connections they switch
+CREATE TABLE IF NOT EXISTS cutovers (
+    cutover_id ulid PRIMARY KEY DEFAULT generate_ulid('cutover'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    cutover_name VARCHAR(255) NOT NULL,
+    cutover_description TEXT DEFAULT '',
+    relationship_id ulid NOT NULL REFERENCES relationships(relationship_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    connection_name VARCHAR(255) NOT NULL,
+    stop_writes_hook TEXT NOT NULL DEFAULT '',
+    unpause_hook TEXT NOT NULL DEFAULT '',
+    gates TEXT[] NOT NULL DEFAULT '{}',
+    max_retries INTEGER NOT NULL DEFAULT 3 CHECK (max_retries >= 0),
+    drain_timeout_seconds INTEGER NOT NULL DEFAULT 600 CHECK (drain_timeout_seconds > 0),
+    cutover_state VARCHAR(32) NOT NULL DEFAULT 'pending',
+    current_step VARCHAR(32) NOT NULL DEFAULT '',
+    cutover_steps JSONB NOT NULL DEFAULT '[]',
+    owner_id ulid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workspace_id, cutover_name)
+);
+
+-- Connection info of applications, pointing to the database they use; switched by cutovers
+CREATE TABLE IF NOT EXISTS application_connections (
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    connection_name VARCHAR(255) NOT NULL,
+    database_id ulid NOT NULL REFERENCES databases(database_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    previous_database_id ulid REFERENCES databases(database_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    switched_by_cutover_id ulid REFERENCES cutovers(cutover_id) ON DELETE SET NULL ON UPDATE CASCADE,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, connection_name)
+);
+
+-- Query proxies routing the statements of applications between the source and target database of a relationship
+CREATE TABLE IF NOT EXISTS query_proxies (
+    proxy_id ulid PRIMARY KEY DEFAULT generate_ulid('proxy'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    proxy_name VARCHAR(255) NOT NULL,
+    proxy_description TEXT DEFAULT '',
+    relationship_id ulid NOT NULL REFERENCES relationships(relationship_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    proxy_protocol VARCHAR(32) NOT NULL,
+    node_id BIGINT NOT NULL REFERENCES nodes(node_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    listen_port INTEGER NOT NULL CHECK (listen_port > 0 AND listen_port < 65536),
+    proxy_username VARCHAR(255) NOT NULL,
+    proxy_password TEXT NOT NULL,
+    read_percent INTEGER NOT NULL DEFAULT 0 CHECK (read_percent >= 0 AND read_percent <= 100),
+    write_percent INTEGER NOT NULL DEFAULT 0 CHECK (write_percent >= 0 AND write_percent <= 100),
+    table_routes JSONB NOT NULL DEFAULT '{}',
+    owner_id ulid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    status_message VARCHAR(255) DEFAULT '',
+    status status_enum DEFAULT 'STATUS_STOPPED',
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workspace_id, proxy_name),
+    UNIQUE(node_id, listen_port)
+);
+
+-- Unix sockets and named pipes of co-located instances
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS instance_socket_path VARCHAR(255);
+
+-- Kerberos authentication with keytabs or credential caches
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS instance_kerberos_auth JSONB;
+
+-- Checkpoints of consumers on stream platforms that leave checkpointing to the application
+CREATE TABLE IF NOT EXISTS stream_checkpoints (
+    stream_id ulid NOT NULL REFERENCES streams(stream_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    consumer_id VARCHAR(255) NOT NULL,
+    topic_name VARCHAR(255) NOT NULL,
+    shard_id VARCHAR(255) NOT NULL,
+    position TEXT NOT NULL,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stream_id, consumer_id, topic_name, shard_id)
+);
+
+-- Labels selecting groups of databases
+ALTER TABLE databases ADD COLUMN IF NOT EXISTS database_labels JSONB NOT NULL DEFAULT '{}';
+
+-- Every distinct schema discovered for a database, to compare the schema of a database between
+-- two points in time; the trigger keeps them for every writer of database_schema
+CREATE TABLE IF NOT EXISTS database_schema_snapshots (
+    snapshot_id ulid PRIMARY KEY DEFAULT generate_ulid('snapshot'),
+    database_id ulid NOT NULL REFERENCES databases(database_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    snapshot_version INTEGER NOT NULL CHECK (snapshot_version > 0),
+    schema_structure JSONB NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(database_id, snapshot_version)
+);
+CREATE INDEX IF NOT EXISTS idx_database_schema_snapshots_created ON database_schema_snapshots(database_id, created);
+
+CREATE OR REPLACE FUNCTION snapshot_database_schema()
+RETURNS TRIGGER AS $$
+DECLARE
+    latest_version INTEGER;
+    latest_schema JSONB;
+BEGIN
+    IF NEW.database_schema IS NULL OR NEW.database_schema = '{}'::jsonb THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT snapshot_version, schema_structure INTO latest_version, latest_schema
+    FROM database_schema_snapshots
+    WHERE database_id = NEW.database_id
+    ORDER BY snapshot_version DESC
+    LIMIT 1;
+
+    -- Discoveries finding the same schema again are not new snapshots
+    IF latest_schema IS NOT NULL AND latest_schema = NEW.database_schema THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO database_schema_snapshots (database_id, snapshot_version, schema_structure)
+    VALUES (NEW.database_id, COALESCE(latest_version, 0) + 1, NEW.database_schema);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS databases_schema_snapshot ON databases;
+CREATE TRIGGER databases_schema_snapshot
+    AFTER INSERT OR UPDATE OF database_schema ON databases
+    FOR EACH ROW EXECUTE FUNCTION snapshot_database_schema();
+
+-- Schemas stored before snapshots were kept are the first snapshots of their databases
+INSERT INTO database_schema_snapshots (database_id, snapshot_version, schema_structure)
+SELECT d.database_id, 1, d.database_schema
+FROM databases d
+WHERE d.database_schema <> '{}'::jsonb
+    AND NOT EXISTS (SELECT 1 FROM database_schema_snapshots s WHERE s.database_id = d.database_id);
+
+-- Schema snapshot version of the source of a relationship when it was last deployed, to find
+-- the schema changes since then under the schema change policies
+ALTER TABLE relationships ADD COLUMN IF NOT EXISTS relationship_source_schema_version INTEGER;
+
+-- Match profiles of workspaces, tuning the matcher generating the rules of new mappings; the
+-- default profile of a workspace is a built-in or a workspace profile
+CREATE TABLE IF NOT EXISTS match_profiles (
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    profile_name VARCHAR(255) NOT NULL,
+    profile_description TEXT DEFAULT '',
+    profile_options JSONB NOT NULL DEFAULT '{}',
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, profile_name)
+);
+ALTER TABLE workspaces ADD COLUMN IF NOT EXISTS workspace_match_profile VARCHAR(255);
+
+-- Match feedback of workspaces: how often users accepted and rejected the match of a source and a
+-- target column when correcting generated rules, adjusting the scores of later matches
+CREATE TABLE IF NOT EXISTS match_feedback (
+    workspace_id ulid NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    source_column VARCHAR(255) NOT NULL,
+    target_column VARCHAR(255) NOT NULL,
+    accepted INTEGER NOT NULL DEFAULT 0,
+    rejected INTEGER NOT NULL DEFAULT 0,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (workspace_id, source_column, target_column)
+);
+
+-- Lookup tables of tenants, the reference data the lookup transformation maps values through.
+-- Names are unique across tenants, as transformation requests carry no tenant. The entries of a
+-- table with a source are a snapshot of a database table, reloaded when the table is refreshed.
+CREATE TABLE IF NOT EXISTS transformation_lookup_tables (
+    lookup_table_id ulid PRIMARY KEY DEFAULT generate_ulid('lookup'),
+    tenant_id ulid NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    lookup_table_name VARCHAR(255) NOT NULL UNIQUE,
+    lookup_table_description TEXT DEFAULT '',
+    lookup_entries JSONB NOT NULL DEFAULT '{}',
+    lookup_source JSONB,
+    cache_ttl_seconds INTEGER NOT NULL DEFAULT 300 CHECK (cache_ttl_seconds >= 0),
+    owner_id ulid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    refreshed TIMESTAMP,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_transformation_lookup_tables_tenant ON transformation_lookup_tables(tenant_id);
+
+-- Masking salts of tenants, the keys of the masking, tokenization and salted hash transformations,
+-- so masked values are stable within a tenant and cannot be recomputed elsewhere. A salt is
+-- created when the rules of the tenant are first masked.
+CREATE TABLE IF NOT EXISTS transformation_masking_salts (
+    tenant_id ulid PRIMARY KEY REFERENCES tenants(tenant_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    salt BYTEA NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Match reports of mappings: the table and column matches of the last run of the matcher
+-- generating the rules of a mapping, with the rule each column match got or why it got none
+CREATE TABLE IF NOT EXISTS mapping_match_reports (
+    mapping_id ulid PRIMARY KEY REFERENCES mappings(mapping_id) ON DELETE CASCADE ON UPDATE CASCADE,
+    match_report JSONB NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+`
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/redact"
 	"github.com/redbco/redb-open/pkg/syslog"
 )
 
@@ -168,6 +169,8 @@ func (l *UnifiedLogger) logToConsoleAndFile(service string, level syslog.LogLeve
 		return
 	}
 
+	message = redact.String(message)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -90,8 +91,16 @@ func (p *ServiceProcess) Start(ctx context.Context) error {
 		if p.globalConfig.InstanceGroup.GroupID != "" {
 			p.cmd.Env = append(p.cmd.Env, fmt.Sprintf("REDB_INSTANCE_GROUP_ID=%s", p.globalConfig.InstanceGroup.GroupID))
 		}
+
+		// Pass the connection pool size of the internal database
+		if p.globalConfig.Database.MaxConnections > 0 {
+			p.cmd.Env = append(p.cmd.Env, fmt.Sprintf("REDB_DATABASE_MAX_CONNECTIONS=%d", p.globalConfig.Database.MaxConnections))
+		}
 	}
 
+	// Apply resource limits through the Go runtime, unless the environment already sets them
+	p.cmd.Env = append(p.cmd.Env, p.resourceEnvironment()...)
+
 	// Also check environment variables as fallback
 	if databaseName := os.Getenv("REDB_DATABASE_NAME"); databaseName != "" {
 		p.cmd.Env = append(p.cmd.Env, fmt.Sprintf("REDB_DATABASE_NAME=%s", databaseName))
@@ -124,6 +133,36 @@ func (p *ServiceProcess) Start(ctx context.Context) error {
 	return nil
 }
 
+// resourceEnvironment returns the Go runtime settings that hold the service to its resources:
+// a soft memory limit, and a number of threads running Go code proportional to its CPU share
+func (p *ServiceProcess) resourceEnvironment() []string {
+	var env []string
+	resources := p.config.Resources
+
+	if resources.MaxMemoryMB > 0 && !p.hasEnvironment("GOMEMLIMIT") {
+		env = append(env, fmt.Sprintf("GOMEMLIMIT=%dMiB", resources.MaxMemoryMB))
+	}
+
+	if resources.MaxCPUPercent > 0 && resources.MaxCPUPercent < 100 && !p.hasEnvironment("GOMAXPROCS") {
+		procs := runtime.NumCPU() * resources.MaxCPUPercent / 100
+		if procs < 1 {
+			procs = 1
+		}
+		env = append(env, fmt.Sprintf("GOMAXPROCS=%d", procs))
+	}
+
+	return env
+}
+
+// hasEnvironment reports whether the supervisor or the service configuration sets a variable
+func (p *ServiceProcess) hasEnvironment(key string) bool {
+	if _, ok := p.config.Environment[key]; ok {
+		return true
+	}
+	_, ok := os.LookupEnv(key)
+	return ok
+}
+
 func (p *ServiceProcess) Stop(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
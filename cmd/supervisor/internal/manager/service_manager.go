@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -21,6 +21,7 @@ import (
 	"github.com/redbco/redb-open/cmd/supervisor/internal/logger"
 	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
 	pkgdatabase "github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/spiffe"
 )
 
 type ServiceInfo struct {
@@ -432,19 +433,79 @@ func (m *ServiceManager) ListServices(stateFilter commonv1.ServiceState, namePat
 	return results
 }
 
+// IsServiceHealthy returns true if an instance of the service is healthy
 func (m *ServiceManager) IsServiceHealthy(name string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	for _, svc := range m.services {
-		if svc.Name == name {
-			return svc.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY
+		if svc.Name == name && svc.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY {
+			return true
 		}
 	}
 
 	return false
 }
 
+// Instance is a snapshot of a registered instance of a service. Several instances of a
+// service register when it runs as replicas, each with its own instance ID.
+type Instance struct {
+	ID            string
+	Name          string
+	InstanceID    string
+	State         commonv1.ServiceState
+	Health        commonv1.HealthStatus
+	StartedAt     time.Time
+	LastHeartbeat time.Time
+	Metrics       *supervisorv1.ServiceMetrics
+}
+
+// Operational returns true if the instance is running and healthy or degraded
+func (i Instance) Operational() bool {
+	return (i.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY ||
+		i.Health == commonv1.HealthStatus_HEALTH_STATUS_DEGRADED) &&
+		i.State == commonv1.ServiceState_SERVICE_STATE_RUNNING
+}
+
+// Instances returns the registered instances of a service, of all services if name is empty,
+// ordered by service name and instance ID
+func (m *ServiceManager) Instances(name string) []Instance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var instances []Instance
+	for _, svc := range m.services {
+		if name != "" && svc.Name != name {
+			continue
+		}
+		instances = append(instances, svc.instance())
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Name != instances[j].Name {
+			return instances[i].Name < instances[j].Name
+		}
+		return instances[i].InstanceID < instances[j].InstanceID
+	})
+	return instances
+}
+
+func (svc *ServiceInfo) instance() Instance {
+	instance := Instance{
+		ID:            svc.ID,
+		Name:          svc.Name,
+		State:         svc.State,
+		Health:        svc.Health,
+		StartedAt:     svc.StartedAt,
+		LastHeartbeat: svc.LastHeartbeat,
+		Metrics:       svc.Metrics,
+	}
+	if svc.Info != nil {
+		instance.InstanceID = svc.Info.InstanceId
+	}
+	return instance
+}
+
 func (m *ServiceManager) GetService(serviceID string) (*ServiceInfo, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -457,7 +518,7 @@ func (m *ServiceManager) connectToService(ctx context.Context, host string, port
 	addr := fmt.Sprintf("%s:%d", host, port)
 
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, spiffe.DialOption())
 	opts = append(opts, grpc.WithBlock())
 
 	// Add keepalive options for more robust connections
@@ -512,18 +573,17 @@ func (m *ServiceManager) AreAllConfiguredServicesHealthy() bool {
 			continue
 		}
 
-		// Find the service by name
+		// The service is operational if any of its instances is. DEGRADED means some health
+		// checks failed but the service is still functional.
 		var found bool
 		var isHealthy bool
 		for _, svc := range m.services {
 			if svc.Name == serviceName {
 				found = true
-				// Accept both HEALTHY and DEGRADED services as operational
-				// DEGRADED means some health checks failed but the service is still functional
-				isHealthy = (svc.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY ||
-					svc.Health == commonv1.HealthStatus_HEALTH_STATUS_DEGRADED) &&
-					svc.State == commonv1.ServiceState_SERVICE_STATE_RUNNING
-				break
+				if svc.instance().Operational() {
+					isHealthy = true
+					break
+				}
 			}
 		}
 
@@ -554,25 +614,34 @@ func (m *ServiceManager) GetConfiguredServiceStatus() map[string]string {
 			continue
 		}
 
-		// Find the service by name
+		// Report the best instance of the service, and how many instances are registered
 		var found bool
 		var svcStatus string
+		var best *ServiceInfo
+		instances := 0
 		for _, svc := range m.services {
-			if svc.Name == serviceName {
-				found = true
-				if (svc.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY ||
-					svc.Health == commonv1.HealthStatus_HEALTH_STATUS_DEGRADED) &&
-					svc.State == commonv1.ServiceState_SERVICE_STATE_RUNNING {
-					if svc.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY {
-						svcStatus = "healthy"
-					} else {
-						svcStatus = "degraded but operational"
-					}
+			if svc.Name != serviceName {
+				continue
+			}
+			found = true
+			instances++
+			if best == nil || statusRank(svc) > statusRank(best) {
+				best = svc
+			}
+		}
+		if best != nil {
+			if best.instance().Operational() {
+				if best.Health == commonv1.HealthStatus_HEALTH_STATUS_HEALTHY {
+					svcStatus = "healthy"
 				} else {
-					svcStatus = fmt.Sprintf("unhealthy (state: %s, health: %s)",
-						svc.State.String(), svc.Health.String())
+					svcStatus = "degraded but operational"
 				}
-				break
+			} else {
+				svcStatus = fmt.Sprintf("unhealthy (state: %s, health: %s)",
+					best.State.String(), best.Health.String())
+			}
+			if instances > 1 {
+				svcStatus = fmt.Sprintf("%s, %d instances", svcStatus, instances)
 			}
 		}
 
@@ -590,6 +659,17 @@ func (m *ServiceManager) GetConfiguredServiceStatus() map[string]string {
 	return status
 }
 
+// statusRank orders instances from not operational to healthy
+func statusRank(svc *ServiceInfo) int {
+	if !svc.instance().Operational() {
+		return 0
+	}
+	if svc.Health == commonv1.HealthStatus_HEALTH_STATUS_DEGRADED {
+		return 1
+	}
+	return 2
+}
+
 // enhanceMeshConfig fetches node_id and mesh_id from database and updates mesh service configuration
 func (m *ServiceManager) enhanceMeshConfig(ctx context.Context, config superconfig.ServiceConfig) (superconfig.ServiceConfig, error) {
 	if m.db == nil {
@@ -0,0 +1,237 @@
+// Package scaling recommends the number of replicas of the services of the node to external
+// orchestrators, such as an autoscaler or a Kubernetes operator. Recommendations are derived
+// from what the replicas report in their heartbeats: their CPU usage, the requests they are
+// working on and the runs they accepted but have not started. The supervisor does not start or
+// stop replicas itself.
+package scaling
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/manager"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
+	"github.com/redbco/redb-open/pkg/service"
+)
+
+// Defaults of the scaling limits of a service
+const (
+	DefaultMinReplicas      = 1
+	DefaultTargetCPUPercent = 70
+	DefaultScaleDownDelay   = 5 * time.Minute
+)
+
+// tolerance is the deviation of the load from its target that does not change the replicas,
+// so that recommendations do not flap around the target
+const tolerance = 0.1
+
+// Lister lists the registered instances of a service, as the service manager does
+type Lister interface {
+	Instances(name string) []manager.Instance
+}
+
+// Recommendation is the number of replicas recommended for a service, and the load it is
+// based on
+type Recommendation struct {
+	Service             string    `json:"service"`
+	CurrentReplicas     int       `json:"current_replicas"` // Instances with a recent heartbeat
+	HealthyReplicas     int       `json:"healthy_replicas"`
+	RecommendedReplicas int       `json:"recommended_replicas"`
+	MinReplicas         int       `json:"min_replicas"`
+	MaxReplicas         int       `json:"max_replicas"`
+	CPUUsagePercent     float64   `json:"cpu_usage_percent"` // Average of the current replicas
+	QueueDepth          int64     `json:"queue_depth"`       // Total of the current replicas
+	PendingRuns         int64     `json:"pending_runs"`      // Total of the current replicas
+	Reason              string    `json:"reason"`
+	ComputedAt          time.Time `json:"computed_at"`
+}
+
+// Recommender computes the recommendations of the services that have scaling limits
+type Recommender struct {
+	mu               sync.Mutex
+	services         map[string]superconfig.ServiceConfig
+	heartbeatTimeout time.Duration
+	lister           Lister
+	history          map[string][]sample // Replicas wanted by the load of a service, oldest first
+}
+
+// sample is the number of replicas the load of a service wanted at a time
+type sample struct {
+	at       time.Time
+	replicas int
+}
+
+func New(cfg *superconfig.Config, lister Lister) *Recommender {
+	return &Recommender{
+		services:         cfg.Services,
+		heartbeatTimeout: cfg.Supervisor.HeartbeatTimeout,
+		lister:           lister,
+		history:          make(map[string][]sample),
+	}
+}
+
+// Recommend returns the recommendations at the given time for the named services, or for all
+// services if no name is given. Services that are disabled or have no max_replicas are left out.
+func (r *Recommender) Recommend(now time.Time, names ...string) []Recommendation {
+	names = append([]string(nil), names...)
+	if len(names) == 0 {
+		for name := range r.services {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recommendations := []Recommendation{}
+	for _, name := range names {
+		cfg, ok := r.services[name]
+		if !ok || !cfg.Enabled || cfg.Scaling.MaxReplicas <= 0 {
+			continue
+		}
+		recommendations = append(recommendations, r.recommend(now, name, withDefaults(cfg.Scaling)))
+	}
+	return recommendations
+}
+
+// recommend sizes a service like a horizontal autoscaler: each signal wants the replicas that
+// bring it back to its target, the largest want wins, and scaling down waits until the load
+// has wanted fewer replicas for the whole scale down delay.
+func (r *Recommender) recommend(now time.Time, name string, limits superconfig.ScalingConfig) Recommendation {
+	rec := Recommendation{
+		Service:     name,
+		MinReplicas: limits.MinReplicas,
+		MaxReplicas: limits.MaxReplicas,
+		ComputedAt:  now.UTC(),
+	}
+
+	var cpu float64
+	reporting := 0
+	for _, instance := range r.lister.Instances(name) {
+		if !r.live(instance, now) {
+			continue
+		}
+		rec.CurrentReplicas++
+		if instance.Operational() {
+			rec.HealthyReplicas++
+		}
+		if instance.Metrics != nil {
+			reporting++
+			cpu += instance.Metrics.CpuUsagePercent
+			rec.QueueDepth += instance.Metrics.CustomMetrics[service.MetricQueueDepth]
+			rec.PendingRuns += instance.Metrics.CustomMetrics[service.MetricPendingRuns]
+		}
+	}
+
+	wanted := limits.MinReplicas
+	var reasons []string
+	switch {
+	case rec.CurrentReplicas == 0:
+		reasons = append(reasons, "no replica is running")
+	case reporting == 0:
+		// Replicas that just registered have not reported their load yet
+		wanted = rec.CurrentReplicas
+		reasons = append(reasons, "no replica has reported its load yet")
+	default:
+		rec.CPUUsagePercent = cpu / float64(reporting)
+		wanted = desired(rec.CurrentReplicas, rec.CPUUsagePercent/limits.TargetCPUPercent)
+		if wanted != rec.CurrentReplicas {
+			reasons = append(reasons, fmt.Sprintf("average CPU usage of %.0f%% against a target of %.0f%% wants %d replicas",
+				rec.CPUUsagePercent, limits.TargetCPUPercent, wanted))
+		}
+
+		if limits.TargetQueueDepth > 0 {
+			load := rec.QueueDepth + rec.PendingRuns
+			byQueue := desired(rec.CurrentReplicas, float64(load)/float64(rec.CurrentReplicas*limits.TargetQueueDepth))
+			if byQueue != rec.CurrentReplicas {
+				reasons = append(reasons, fmt.Sprintf("%d queued requests and pending runs against a target of %d per replica want %d replicas",
+					load, limits.TargetQueueDepth, byQueue))
+			}
+			if byQueue > wanted {
+				wanted = byQueue
+			}
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "load is within its targets")
+		}
+	}
+	if wanted > limits.MaxReplicas {
+		reasons = append(reasons, fmt.Sprintf("capped at %d replicas", limits.MaxReplicas))
+	}
+	wanted = clamp(wanted, limits.MinReplicas, limits.MaxReplicas)
+
+	// Scale down to the most replicas the load wanted during the delay, but start a service
+	// that is not running at once
+	history := append(r.history[name], sample{at: now, replicas: wanted})
+	for len(history) > 1 && now.Sub(history[0].at) > limits.ScaleDownDelay {
+		history = history[1:]
+	}
+	r.history[name] = history
+
+	rec.RecommendedReplicas = wanted
+	if rec.CurrentReplicas > 0 {
+		for _, s := range history {
+			if s.replicas > rec.RecommendedReplicas {
+				rec.RecommendedReplicas = s.replicas
+			}
+		}
+	}
+
+	if rec.RecommendedReplicas > wanted {
+		reasons = append(reasons, fmt.Sprintf("scaling down is delayed until the load wanted fewer replicas for %s", limits.ScaleDownDelay))
+	}
+	rec.Reason = strings.Join(reasons, "; ")
+	return rec
+}
+
+// live returns true if the instance heartbeats and is not stopping
+func (r *Recommender) live(instance manager.Instance, now time.Time) bool {
+	switch instance.State {
+	case commonv1.ServiceState_SERVICE_STATE_STOPPING, commonv1.ServiceState_SERVICE_STATE_STOPPED,
+		commonv1.ServiceState_SERVICE_STATE_ERROR:
+		return false
+	}
+	return r.heartbeatTimeout <= 0 || now.Sub(instance.LastHeartbeat) <= r.heartbeatTimeout
+}
+
+// desired returns the replicas that bring a signal back to its target, given the ratio of the
+// signal to its target
+func desired(current int, ratio float64) int {
+	if math.Abs(ratio-1) <= tolerance {
+		return current
+	}
+	return int(math.Ceil(float64(current) * ratio))
+}
+
+func clamp(replicas, min, max int) int {
+	if replicas > max {
+		replicas = max
+	}
+	if replicas < min {
+		replicas = min
+	}
+	return replicas
+}
+
+// withDefaults returns the limits of a service with defaults for what it leaves unset
+func withDefaults(limits superconfig.ScalingConfig) superconfig.ScalingConfig {
+	if limits.MinReplicas <= 0 {
+		limits.MinReplicas = DefaultMinReplicas
+	}
+	if limits.MinReplicas > limits.MaxReplicas {
+		limits.MinReplicas = limits.MaxReplicas
+	}
+	if limits.TargetCPUPercent <= 0 {
+		limits.TargetCPUPercent = DefaultTargetCPUPercent
+	}
+	if limits.ScaleDownDelay <= 0 {
+		limits.ScaleDownDelay = DefaultScaleDownDelay
+	}
+	return limits
+}
@@ -0,0 +1,108 @@
+package scaling
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	supervisorv1 "github.com/redbco/redb-open/api/proto/supervisor/v1"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/manager"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
+)
+
+type fakeLister map[string][]manager.Instance
+
+func (f fakeLister) Instances(name string) []manager.Instance {
+	return f[name]
+}
+
+func replica(id string, heartbeat time.Time, cpu float64, queueDepth, pendingRuns int64) manager.Instance {
+	return manager.Instance{
+		ID:            id,
+		Name:          "transformation",
+		InstanceID:    id,
+		State:         commonv1.ServiceState_SERVICE_STATE_RUNNING,
+		Health:        commonv1.HealthStatus_HEALTH_STATUS_HEALTHY,
+		LastHeartbeat: heartbeat,
+		Metrics: &supervisorv1.ServiceMetrics{
+			CpuUsagePercent: cpu,
+			CustomMetrics:   map[string]int64{"queue_depth": queueDepth, "pending_runs": pendingRuns},
+		},
+	}
+}
+
+func newTestRecommender(lister fakeLister) *Recommender {
+	return New(&superconfig.Config{
+		Supervisor: superconfig.SupervisorConfig{HeartbeatTimeout: 30 * time.Second},
+		Services: map[string]superconfig.ServiceConfig{
+			"transformation": {Enabled: true, Scaling: superconfig.ScalingConfig{
+				MaxReplicas: 4, TargetQueueDepth: 10, ScaleDownDelay: time.Minute,
+			}},
+			"anchor": {Enabled: true},
+		},
+	}, lister)
+}
+
+func TestRecommendFollowsLoad(t *testing.T) {
+	now := time.Now()
+	lister := fakeLister{}
+	recommender := newTestRecommender(lister)
+
+	lister["transformation"] = []manager.Instance{
+		replica("a", now, 70, 10, 0),
+		replica("b", now, 72, 8, 2),
+	}
+	recs := recommender.Recommend(now)
+	if len(recs) != 1 || recs[0].Service != "transformation" {
+		t.Fatalf("recommendations = %+v, want only transformation", recs)
+	}
+	if rec := recs[0]; rec.RecommendedReplicas != 2 || rec.QueueDepth != 18 || rec.PendingRuns != 2 || rec.Reason != "load is within its targets" {
+		t.Fatalf("recommendation at target load = %+v", rec)
+	}
+
+	// 30 queued requests and pending runs want 3 replicas of 10, CPU wants 5 and is capped at 4
+	lister["transformation"] = []manager.Instance{
+		replica("a", now, 140, 15, 0),
+		replica("b", now, 150, 10, 5),
+	}
+	rec := recommender.Recommend(now.Add(10 * time.Second))[0]
+	if rec.RecommendedReplicas != 4 || rec.CurrentReplicas != 2 {
+		t.Fatalf("recommendation under load = %+v, want 4 replicas", rec)
+	}
+
+	// Idle replicas want 1, but scaling down waits for the delay
+	lister["transformation"] = []manager.Instance{
+		replica("a", now, 5, 0, 0),
+		replica("b", now, 5, 0, 0),
+	}
+	rec = recommender.Recommend(now.Add(30 * time.Second))[0]
+	if rec.RecommendedReplicas != 4 || !strings.Contains(rec.Reason, "delayed") {
+		t.Fatalf("recommendation right after the load dropped = %+v, want 4 replicas", rec)
+	}
+	rec = recommender.Recommend(now.Add(2 * time.Minute))[0]
+	if rec.RecommendedReplicas != 1 {
+		t.Fatalf("recommendation after the scale down delay = %+v, want 1 replica", rec)
+	}
+}
+
+func TestRecommendIgnoresStaleReplicas(t *testing.T) {
+	now := time.Now()
+	lister := fakeLister{"transformation": {
+		replica("a", now, 60, 0, 0),
+		replica("b", now.Add(-time.Minute), 100, 50, 0),
+	}}
+	stopping := replica("c", now, 100, 50, 0)
+	stopping.State = commonv1.ServiceState_SERVICE_STATE_STOPPING
+	lister["transformation"] = append(lister["transformation"], stopping)
+
+	rec := newTestRecommender(lister).Recommend(now, "transformation")[0]
+	if rec.CurrentReplicas != 1 || rec.QueueDepth != 0 || rec.RecommendedReplicas != 1 {
+		t.Fatalf("recommendation = %+v, want only the live replica to count", rec)
+	}
+
+	rec = newTestRecommender(fakeLister{}).Recommend(now, "transformation")[0]
+	if rec.CurrentReplicas != 0 || rec.RecommendedReplicas != 1 {
+		t.Fatalf("recommendation without replicas = %+v, want the minimum", rec)
+	}
+}
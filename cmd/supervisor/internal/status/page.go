@@ -0,0 +1,71 @@
+package status
+
+import (
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// pageTemplate renders the report as a self-contained HTML page that refreshes itself
+var pageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"time":     func(t time.Time) string { return t.Format("2006-01-02 15:04:05 UTC") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="30">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { font-size: 1.5rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; padding: .4rem .5rem; border-bottom: 1px solid #ddd; }
+.banner { padding: .8rem 1rem; border-radius: .3rem; color: #fff; font-weight: bold; }
+.operational, .healthy { background: #2e7d32; }
+.degraded, .starting { background: #f9a825; }
+.outage, .unhealthy, .not_running, .stopping, .unknown { background: #c62828; }
+.badge { padding: .1rem .4rem; border-radius: .2rem; color: #fff; font-size: .85rem; }
+.muted { color: #777; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.Status}}">{{if eq .Status "operational"}}All services operational{{else if eq .Status "degraded"}}Degraded performance{{else}}Service outage{{end}}</div>
+<p class="muted">Node up for {{duration .UptimeSeconds}} &middot; version {{.Version}} &middot; updated {{time .GeneratedAt}}</p>
+
+<h2>Services</h2>
+<table>
+<tr><th>Service</th><th>Status</th><th>Uptime</th></tr>
+{{range .Services}}<tr><td>{{.Name}}{{if .Required}} <span class="muted">required</span>{{end}}</td><td><span class="badge {{.Status}}">{{.Status}}</span></td><td>{{if .HealthySince}}{{duration .UptimeSeconds}}{{else}}&ndash;{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Incidents</h2>
+{{if .Incidents}}<table>
+<tr><th>Service</th><th>Status</th><th>Started</th><th>Duration</th></tr>
+{{range .Incidents}}<tr><td>{{.Service}}</td><td><span class="badge {{.Status}}">{{.Status}}</span></td><td>{{time .StartedAt}}</td><td>{{duration .DurationSeconds}}{{if .Ongoing}} (ongoing){{end}}</td></tr>
+{{end}}</table>{{else}}<p>No incidents since the node started.</p>{{end}}
+</body>
+</html>
+`))
+
+// formatDuration formats a number of seconds as days, hours and minutes
+func formatDuration(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int64(d / (24 * time.Hour))
+	hours := int64(d % (24 * time.Hour) / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
@@ -0,0 +1,352 @@
+// Package status serves the status page of the node: the health, uptime and incident history
+// of its services, as JSON for monitoring and as a minimal HTML page for people. The page is
+// public unless a bearer token is configured. It also serves the replicas recommended for the
+// services, for orchestrators that scale them over HTTP.
+package status
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/health"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/logger"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/scaling"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
+)
+
+// Overall status of the node
+const (
+	StatusOperational = "operational" // All services are healthy
+	StatusDegraded    = "degraded"    // Some services are not healthy, the required ones are running
+	StatusOutage      = "outage"      // A required service is down
+)
+
+// Report is the status of the node
+type Report struct {
+	Title         string          `json:"title"`
+	Status        string          `json:"status"`
+	Version       string          `json:"version"`
+	StartedAt     time.Time       `json:"started_at"`
+	UptimeSeconds int64           `json:"uptime_seconds"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Services      []ServiceStatus `json:"services"`
+	Incidents     []Incident      `json:"incidents"`
+}
+
+// ServiceStatus is the status of a service of the node
+type ServiceStatus struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Required      bool       `json:"required"`
+	Instances     int        `json:"instances"` // Registered instances, the status is that of the best one
+	HealthySince  *time.Time `json:"healthy_since,omitempty"`
+	UptimeSeconds int64      `json:"uptime_seconds"`
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+}
+
+// Incident is a period in which a service was not healthy
+type Incident struct {
+	Service         string     `json:"service"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Ongoing         bool       `json:"ongoing"`
+}
+
+// Server serves the status page
+type Server struct {
+	config    superconfig.StatusPageConfig
+	services  map[string]superconfig.ServiceConfig
+	monitor   *health.Monitor
+	scaling   *scaling.Recommender
+	version   string
+	startedAt time.Time
+	logger    logger.LoggerInterface
+	server    *http.Server
+}
+
+// New creates the status page of the node. The replicas recommended for its services are only
+// served if recommender is not nil.
+func New(cfg *superconfig.Config, monitor *health.Monitor, recommender *scaling.Recommender, version string, log logger.LoggerInterface) *Server {
+	monitor.SetIncidentHistory(cfg.Supervisor.StatusPage.IncidentHistory)
+
+	return &Server{
+		config:    cfg.Supervisor.StatusPage,
+		services:  cfg.Services,
+		monitor:   monitor,
+		scaling:   recommender,
+		version:   version,
+		startedAt: time.Now(),
+		logger:    log,
+	}
+}
+
+// Start listens on the port of the status page and serves it in the background
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf("Status page server error: %v", err)
+		}
+	}()
+
+	if s.config.Token == "" {
+		s.logger.Infof("Serving public status page on port %d", s.config.Port)
+	} else {
+		s.logger.Infof("Serving status page on port %d", s.config.Port)
+	}
+	return nil
+}
+
+// Shutdown stops serving the status page
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
+
+// Handler returns the handler of the status page: the HTML page at / and the JSON report at
+// /status. Both answer 503 during an outage, so either can be used as a health check. The
+// scaling recommendations are served as JSON at /scaling, or /scaling/{service} for one service.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/status", s.handleReport)
+	if s.scaling != nil {
+		mux.HandleFunc("/scaling", s.handleScaling)
+		mux.HandleFunc("/scaling/", s.handleScaling)
+	}
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.config.Token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Browsers of stakeholders cannot send headers, so the token is also accepted in the query
+		token := r.URL.Query().Get("token")
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			token = strings.TrimPrefix(header, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="status"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := s.Report(time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(httpStatus(report))
+	json.NewEncoder(w).Encode(report)
+}
+
+// handleScaling serves the recommendations of all services, or the recommendation of one
+// service as a single object so that autoscalers can read its fields by path
+func (s *Server) handleScaling(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body interface{}
+	if name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/scaling"), "/"); name != "" {
+		recommendations := s.scaling.Recommend(time.Now(), name)
+		if len(recommendations) == 0 {
+			http.Error(w, fmt.Sprintf("Service %s has no scaling limits", name), http.StatusNotFound)
+			return
+		}
+		body = recommendations[0]
+	} else {
+		body = map[string]interface{}{"recommendations": s.scaling.Recommend(time.Now())}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := s.Report(time.Now())
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(httpStatus(report))
+	if err := pageTemplate.Execute(w, report); err != nil {
+		s.logger.Errorf("Failed to render status page: %v", err)
+	}
+}
+
+// Report builds the status of the node at the given time. Every enabled service is listed,
+// including those that have not registered with the supervisor.
+func (s *Server) Report(now time.Time) Report {
+	report := Report{
+		Title:         s.config.Title,
+		Status:        StatusOperational,
+		Version:       s.version,
+		StartedAt:     s.startedAt.UTC(),
+		UptimeSeconds: int64(now.Sub(s.startedAt).Seconds()),
+		GeneratedAt:   now.UTC(),
+		Services:      []ServiceStatus{},
+		Incidents:     []Incident{},
+	}
+
+	// Services running as several instances are as healthy as their healthiest instance
+	registered := make(map[string]health.ServiceHealth)
+	instances := make(map[string]int)
+	for _, svc := range s.monitor.Services() {
+		instances[svc.ServiceName]++
+		if best, ok := registered[svc.ServiceName]; !ok || healthRank(svc.Status) > healthRank(best.Status) {
+			registered[svc.ServiceName] = svc
+		}
+	}
+
+	names := make([]string, 0, len(s.services))
+	for name, svc := range s.services {
+		if svc.Enabled {
+			names = append(names, name)
+		}
+	}
+	for name := range registered {
+		if _, configured := s.services[name]; !configured {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := ServiceStatus{
+			Name:      name,
+			Status:    "not_running",
+			Required:  s.services[name].Required,
+			Instances: instances[name],
+		}
+		if svc, ok := registered[name]; ok {
+			service.Status = healthStatus(svc.Status)
+			lastHeartbeat := svc.LastUpdate.UTC()
+			service.LastHeartbeat = &lastHeartbeat
+			if !svc.HealthySince.IsZero() {
+				healthySince := svc.HealthySince.UTC()
+				service.HealthySince = &healthySince
+				service.UptimeSeconds = int64(now.Sub(svc.HealthySince).Seconds())
+			}
+		}
+
+		switch service.Status {
+		case "healthy":
+		case "degraded", "starting":
+			report.Status = worseStatus(report.Status, StatusDegraded)
+		default:
+			if service.Required {
+				report.Status = StatusOutage
+			} else {
+				report.Status = worseStatus(report.Status, StatusDegraded)
+			}
+		}
+		report.Services = append(report.Services, service)
+	}
+
+	for _, incident := range s.monitor.Incidents() {
+		item := Incident{
+			Service:   incident.ServiceName,
+			Status:    healthStatus(incident.Status),
+			StartedAt: incident.StartedAt.UTC(),
+			Ongoing:   incident.Ongoing(),
+		}
+		end := now
+		if !incident.Ongoing() {
+			resolvedAt := incident.ResolvedAt.UTC()
+			item.ResolvedAt = &resolvedAt
+			end = incident.ResolvedAt
+		}
+		item.DurationSeconds = int64(end.Sub(incident.StartedAt).Seconds())
+		report.Incidents = append(report.Incidents, item)
+	}
+
+	return report
+}
+
+func worseStatus(current, status string) string {
+	if current == StatusOutage {
+		return current
+	}
+	return status
+}
+
+func httpStatus(report Report) int {
+	if report.Status == StatusOutage {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// healthRank orders health statuses from the worst to the best
+func healthRank(status commonv1.HealthStatus) int {
+	switch status {
+	case commonv1.HealthStatus_HEALTH_STATUS_HEALTHY:
+		return 3
+	case commonv1.HealthStatus_HEALTH_STATUS_DEGRADED:
+		return 2
+	case commonv1.HealthStatus_HEALTH_STATUS_STARTING:
+		return 1
+	}
+	return 0
+}
+
+func healthStatus(status commonv1.HealthStatus) string {
+	switch status {
+	case commonv1.HealthStatus_HEALTH_STATUS_HEALTHY:
+		return "healthy"
+	case commonv1.HealthStatus_HEALTH_STATUS_DEGRADED:
+		return "degraded"
+	case commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY:
+		return "unhealthy"
+	case commonv1.HealthStatus_HEALTH_STATUS_STARTING:
+		return "starting"
+	case commonv1.HealthStatus_HEALTH_STATUS_STOPPING:
+		return "stopping"
+	default:
+		return "unknown"
+	}
+}
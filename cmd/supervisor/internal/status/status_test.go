@@ -0,0 +1,155 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/health"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/logger"
+	"github.com/redbco/redb-open/cmd/supervisor/internal/superconfig"
+)
+
+func newTestServer(token string) (*Server, *health.Monitor) {
+	log := logger.New("supervisor", "test")
+	monitor := health.NewMonitor(log)
+	cfg := &superconfig.Config{
+		Supervisor: superconfig.SupervisorConfig{
+			StatusPage: superconfig.StatusPageConfig{Enabled: true, Title: "Test Node", Token: token},
+		},
+		Services: map[string]superconfig.ServiceConfig{
+			"core":    {Enabled: true, Required: true},
+			"webhook": {Enabled: true},
+			"mesh":    {Enabled: false},
+		},
+	}
+	return New(cfg, monitor, nil, "test", log), monitor
+}
+
+func TestReportTracksHealthAndIncidents(t *testing.T) {
+	server, monitor := newTestServer("")
+
+	monitor.AddService("core-1", "core")
+	monitor.AddService("webhook-1", "webhook")
+	monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+	monitor.UpdateHealth("webhook-1", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+
+	report := server.Report(time.Now())
+	if report.Status != StatusOperational || len(report.Services) != 2 || len(report.Incidents) != 0 {
+		t.Fatalf("report of healthy node: %+v", report)
+	}
+
+	monitor.UpdateHealth("webhook-1", commonv1.HealthStatus_HEALTH_STATUS_DEGRADED)
+	monitor.UpdateHealth("webhook-1", commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY)
+	report = server.Report(time.Now())
+	if report.Status != StatusDegraded {
+		t.Fatalf("status with an unhealthy optional service = %s, want %s", report.Status, StatusDegraded)
+	}
+	if len(report.Incidents) != 1 || !report.Incidents[0].Ongoing || report.Incidents[0].Status != "unhealthy" {
+		t.Fatalf("incidents = %+v, want one ongoing unhealthy incident", report.Incidents)
+	}
+	if report.Services[1].HealthySince != nil {
+		t.Fatalf("unhealthy service has uptime: %+v", report.Services[1])
+	}
+
+	monitor.UpdateHealth("webhook-1", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+	monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY)
+	report = server.Report(time.Now())
+	if report.Status != StatusOutage {
+		t.Fatalf("status with an unhealthy required service = %s, want %s", report.Status, StatusOutage)
+	}
+	if len(report.Incidents) != 2 || report.Incidents[0].Service != "core" || report.Incidents[1].Ongoing {
+		t.Fatalf("incidents = %+v, want the resolved webhook incident after the core incident", report.Incidents)
+	}
+}
+
+func TestIncidentHistoryIsBounded(t *testing.T) {
+	monitor := health.NewMonitor(logger.New("supervisor", "test"))
+	monitor.SetIncidentHistory(2)
+	monitor.AddService("core-1", "core")
+
+	for i := 0; i < 5; i++ {
+		monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY)
+		monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+	}
+	if incidents := monitor.Incidents(); len(incidents) != 2 {
+		t.Fatalf("kept %d incidents, want 2", len(incidents))
+	}
+}
+
+func TestHandlerServesJSONAndHTML(t *testing.T) {
+	server, monitor := newTestServer("")
+	monitor.AddService("core-1", "core")
+	monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /status = %d", rec.Code)
+	}
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	// The webhook service never registered
+	if report.Status != StatusDegraded || report.Services[1].Status != "not_running" {
+		t.Fatalf("report = %+v", report)
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Test Node</title>") {
+		t.Fatalf("GET / = %d\n%s", rec.Code, rec.Body.String())
+	}
+
+	monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("GET /status during an outage = %d, want 503", rec.Code)
+	}
+}
+
+func TestHandlerRequiresConfiguredToken(t *testing.T) {
+	server, _ := newTestServer("s3cret")
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("GET /status without token = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	if rec.Code == http.StatusUnauthorized {
+		t.Fatal("GET /status with bearer token was unauthorized")
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?token=s3cret", nil))
+	if rec.Code == http.StatusUnauthorized {
+		t.Fatal("GET / with token in the query was unauthorized")
+	}
+}
+
+func TestReportShowsHealthiestInstance(t *testing.T) {
+	server, monitor := newTestServer("")
+	monitor.AddService("core-1", "core")
+	monitor.AddService("core-2", "core")
+	monitor.UpdateHealth("core-1", commonv1.HealthStatus_HEALTH_STATUS_UNHEALTHY)
+	monitor.UpdateHealth("core-2", commonv1.HealthStatus_HEALTH_STATUS_HEALTHY)
+
+	report := server.Report(time.Now())
+	if core := report.Services[0]; core.Name != "core" || core.Status != "healthy" || core.Instances != 2 {
+		t.Fatalf("core = %+v, want healthy with 2 instances", core)
+	}
+	if report.Status == StatusOutage {
+		t.Fatal("node is in an outage while a replica of core is healthy")
+	}
+}
@@ -0,0 +1,40 @@
+package superconfig
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadDatabaseCapabilities(t *testing.T) {
+	var config Config
+	err := yaml.Unmarshal([]byte(`
+database_capabilities:
+  databases:
+    - id: yugabytedb
+      name: YugabyteDB
+      aliases: [yugabyte]
+      defaultPort: 5433
+  overrides:
+    postgres:
+      systemDatabases: [postgres, rdsadmin]
+    mysql:
+      supportsCDC: false
+`), &config)
+	if err != nil {
+		t.Fatalf("yaml.Unmarshal failed: %v", err)
+	}
+	if err := config.loadDatabaseCapabilities(); err != nil {
+		t.Fatalf("loadDatabaseCapabilities failed: %v", err)
+	}
+	if config.DatabaseCapabilitiesJSON() == "" {
+		t.Error("Expected the database capabilities to be exported as JSON")
+	}
+
+	config.DatabaseCapabilities = map[string]interface{}{
+		"overrides": map[string]interface{}{"unknown-db": map[string]interface{}{"supportsCDC": false}},
+	}
+	if err := config.loadDatabaseCapabilities(); err == nil {
+		t.Error("Expected an override of an unknown database to fail")
+	}
+}
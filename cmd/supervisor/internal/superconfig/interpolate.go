@@ -0,0 +1,184 @@
+package superconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// Values of the configuration file may reference the environment and secrets, which are
+// replaced when the file is loaded, before it is checked against the schema:
+//
+//	${VAR}, ${env:VAR}   the environment variable VAR, which must be set
+//	${VAR:-default}      VAR, or default if VAR is unset or empty
+//	${file:/path}        the content of a file without its trailing newline, such as a Docker
+//	                     or Kubernetes secret; relative paths are relative to the config file
+//	$$                   a literal $
+//
+// Only values are interpolated, keys are not. A plain value is typed after interpolation, so
+// `port: ${PORT}` is an integer, while a quoted one stays a string.
+
+// interpolator replaces references in the values of a document
+type interpolator struct {
+	doc       *document
+	dir       string // Directory of the config file, for relative secret files
+	lookupEnv func(string) (string, bool)
+	readFile  func(string) ([]byte, error)
+	secrets   map[string]bool // paths of the values that contain a secret file
+}
+
+func newInterpolator(doc *document) *interpolator {
+	return &interpolator{
+		doc:       doc,
+		dir:       filepath.Dir(doc.file),
+		lookupEnv: os.LookupEnv,
+		readFile:  os.ReadFile,
+		secrets:   make(map[string]bool),
+	}
+}
+
+// interpolateFile replaces the references in the document root of a file
+func (in *interpolator) interpolateFile(root *yaml.Node) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		in.interpolate("", root.Content[0])
+	}
+}
+
+func (in *interpolator) interpolate(path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Tag == "!!merge" {
+				in.interpolate(path, value)
+				continue
+			}
+			in.interpolate(joinPath(path, key.Value), value)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			in.interpolate(fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	case yaml.ScalarNode:
+		in.interpolateScalar(path, node)
+	}
+	// Aliases are interpolated at their anchor
+}
+
+// interpolateScalar replaces the references in a value, recording a problem for each one that
+// cannot be resolved
+func (in *interpolator) interpolateScalar(path string, node *yaml.Node) {
+	if !strings.Contains(node.Value, "$") {
+		return
+	}
+
+	var b strings.Builder
+	value, secret, ok := node.Value, false, true
+	for {
+		i := strings.IndexByte(value, '$')
+		if i < 0 {
+			b.WriteString(value)
+			break
+		}
+		b.WriteString(value[:i])
+		value = value[i:]
+
+		switch {
+		case strings.HasPrefix(value, "$$"):
+			b.WriteByte('$')
+			value = value[2:]
+			continue
+		case !strings.HasPrefix(value, "${"):
+			b.WriteByte('$')
+			value = value[1:]
+			continue
+		}
+
+		end := strings.IndexByte(value, '}')
+		if end < 0 {
+			in.doc.problem(path, node, "unterminated reference %q", value)
+			return
+		}
+		reference := value[2:end]
+		value = value[end+1:]
+
+		resolved, isSecret, err := in.resolve(reference)
+		if err != nil {
+			in.doc.problem(path, node, "%v", err)
+			ok = false
+			continue
+		}
+		secret = secret || isSecret
+		b.WriteString(resolved)
+	}
+	if !ok {
+		return
+	}
+
+	node.Value = b.String()
+	// A plain value takes the type of what it was replaced with, unless it was explicitly tagged
+	if node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		node.Tag = ""
+	}
+	if secret {
+		in.secrets[path] = true
+		redact.Register(node.Value)
+	}
+}
+
+// resolve returns the value of the reference between ${ and }, and whether it is a secret
+func (in *interpolator) resolve(reference string) (string, bool, error) {
+	kind, name, found := strings.Cut(reference, ":")
+	if !found || strings.HasPrefix(name, "-") {
+		kind, name = "env", reference
+	}
+
+	switch kind {
+	case "env":
+		name, def, hasDefault := strings.Cut(name, ":-")
+		if !isEnvName(name) {
+			return "", false, fmt.Errorf("invalid environment variable name in ${%s}", reference)
+		}
+		value, set := in.lookupEnv(name)
+		if hasDefault && value == "" {
+			return def, false, nil
+		}
+		if !set {
+			return "", false, fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, false, nil
+	case "file":
+		if name == "" {
+			return "", false, fmt.Errorf("missing file path in ${%s}", reference)
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(in.dir, name)
+		}
+		data, err := in.readFile(name)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read secret: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	}
+	return "", false, fmt.Errorf("unknown reference type %q in ${%s}, expected env or file", kind, reference)
+}
+
+// isEnvName reports whether name is a valid environment variable name
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package superconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInterpolatesReferences(t *testing.T) {
+	t.Setenv("REDB_TEST_DB_NAME", "redb_prod")
+	t.Setenv("REDB_TEST_STATUS_PORT", "9070")
+	t.Setenv("REDB_TEST_EMPTY", "")
+
+	path := writeConfig(t, `supervisor:
+  status_page:
+    port: ${REDB_TEST_STATUS_PORT}
+database:
+  name: ${env:REDB_TEST_DB_NAME}
+  user: ${REDB_TEST_EMPTY:-redb_user}
+logging:
+  level: "${REDB_TEST_UNSET:-debug}"
+keyring:
+  path: $$HOME/keyring-${REDB_TEST_DB_NAME}.json
+services:
+  core:
+    enabled: true
+    executable: ./redb-core
+    args:
+      - --token=${file:core.token}
+`)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "core.token"), []byte("s3cr3t-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Supervisor.StatusPage.Port != 9070 {
+		t.Errorf("status page port = %d, want 9070", config.Supervisor.StatusPage.Port)
+	}
+	if config.Database.Name != "redb_prod" || config.Database.User != "redb_user" {
+		t.Errorf("database = %+v", config.Database)
+	}
+	if config.Logging.Level != "debug" {
+		t.Errorf("logging level = %q, want debug", config.Logging.Level)
+	}
+	if config.Keyring.Path != "$HOME/keyring-redb_prod.json" {
+		t.Errorf("keyring path = %q", config.Keyring.Path)
+	}
+	if args := config.Services["core"].Args; len(args) != 1 || args[0] != "--token=s3cr3t-token" {
+		t.Errorf("core args = %q", args)
+	}
+
+	data, err := config.EffectiveYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cr3t-token") {
+		t.Errorf("effective configuration shows a secret:\n%s", data)
+	}
+}
+
+func TestLoadReportsUnresolvedReferences(t *testing.T) {
+	path := writeConfig(t, `database:
+  name: ${REDB_TEST_UNSET}
+  user: ${REDB_TEST_UNSET
+keyring:
+  master_key: ${file:missing.secret}
+  backend: ${vault:redb/keyring}
+`)
+
+	_, err := Load(path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Load() = %v, want a ValidationError", err)
+	}
+
+	want := []struct {
+		line    int
+		path    string
+		message string
+	}{
+		{2, "database.name", "environment variable REDB_TEST_UNSET is not set"},
+		{3, "database.user", "unterminated reference"},
+		{5, "keyring.master_key", "failed to read secret"},
+		{6, "keyring.backend", `unknown reference type "vault"`},
+	}
+	if len(validation.Problems) != len(want) {
+		t.Fatalf("problems = %+v", validation.Problems)
+	}
+	for i, p := range validation.Problems {
+		if p.Line != want[i].line || p.Path != want[i].path || !strings.Contains(p.Message, want[i].message) {
+			t.Errorf("problem %d = %+v, want %s at line %d: %s", i, p, want[i].path, want[i].line, want[i].message)
+		}
+	}
+}
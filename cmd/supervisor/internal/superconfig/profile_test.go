@@ -0,0 +1,128 @@
+package superconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyProfileStandard(t *testing.T) {
+	config := &Config{
+		Services: map[string]ServiceConfig{
+			"anchor": {Enabled: true, Required: true},
+		},
+	}
+
+	if err := config.applyProfile(); err != nil {
+		t.Fatalf("applyProfile failed: %v", err)
+	}
+	if config.Profile != ProfileStandard {
+		t.Errorf("Expected profile '%s', got '%s'", ProfileStandard, config.Profile)
+	}
+	if config.Database.MaxConnections != 0 {
+		t.Errorf("Expected no database pool size, got %d", config.Database.MaxConnections)
+	}
+	if config.Services["anchor"].Resources.MaxMemoryMB != 0 {
+		t.Errorf("Expected no memory ceiling, got %d", config.Services["anchor"].Resources.MaxMemoryMB)
+	}
+}
+
+func TestApplyProfileEdge(t *testing.T) {
+	config := &Config{
+		Profile: ProfileEdge,
+		Edge: EdgeConfig{
+			DisabledServices:  []string{"integration"},
+			OffloadedServices: map[string]string{"unifiedmodel": "hub.example.org:50052"},
+		},
+		Services: map[string]ServiceConfig{
+			"unifiedmodel": {Enabled: true, Required: true},
+			"integration":  {Enabled: true, Required: true},
+			"mesh": {
+				Enabled:      true,
+				Required:     true,
+				Dependencies: []string{"security", "unifiedmodel"},
+			},
+			"anchor": {
+				Enabled:   true,
+				Required:  true,
+				Resources: ResourceConfig{MaxMemoryMB: 512},
+				Config: map[string]string{
+					"services.anchor.initial_sync_workers_per_database": "2",
+				},
+			},
+			"core": {Enabled: true, Required: true, Dependencies: []string{"integration"}},
+		},
+	}
+
+	if err := config.applyProfile(); err != nil {
+		t.Fatalf("applyProfile failed: %v", err)
+	}
+
+	for _, name := range []string{"unifiedmodel", "integration"} {
+		if svc := config.Services[name]; svc.Enabled || svc.Required {
+			t.Errorf("Expected service '%s' to be disabled", name)
+		}
+	}
+
+	mesh := config.Services["mesh"]
+	if !reflect.DeepEqual(mesh.Dependencies, []string{"security"}) {
+		t.Errorf("Expected mesh dependencies [security], got %v", mesh.Dependencies)
+	}
+	if addr := mesh.Config["services.unifiedmodel.grpc_address"]; addr != "hub.example.org:50052" {
+		t.Errorf("Expected offloaded unifiedmodel address, got '%s'", addr)
+	}
+	if mesh.Resources.MaxMemoryMB != edgeDefaultMaxMemoryMB {
+		t.Errorf("Expected default memory ceiling %d, got %d", edgeDefaultMaxMemoryMB, mesh.Resources.MaxMemoryMB)
+	}
+	if len(config.Services["core"].Dependencies) != 0 {
+		t.Errorf("Expected no core dependencies, got %v", config.Services["core"].Dependencies)
+	}
+
+	anchor := config.Services["anchor"]
+	if anchor.Resources.MaxMemoryMB != 512 {
+		t.Errorf("Expected configured memory ceiling 512, got %d", anchor.Resources.MaxMemoryMB)
+	}
+	if workers := anchor.Config["services.anchor.initial_sync_workers_per_database"]; workers != "2" {
+		t.Errorf("Expected configured initial sync workers '2', got '%s'", workers)
+	}
+
+	if config.Database.MaxConnections != edgeDatabaseMaxConnections {
+		t.Errorf("Expected database pool size %d, got %d", edgeDatabaseMaxConnections, config.Database.MaxConnections)
+	}
+	if addr := config.GetServiceGRPCAddress("unifiedmodel"); addr != "hub.example.org:50052" {
+		t.Errorf("Expected offloaded unifiedmodel gRPC address, got '%s'", addr)
+	}
+	if order := config.GetServiceStartupOrder(); len(order) != 3 {
+		t.Errorf("Expected 3 services to start, got %v", order)
+	}
+}
+
+func TestApplyProfileEdgeDefaults(t *testing.T) {
+	config := &Config{
+		Profile: ProfileEdge,
+		Services: map[string]ServiceConfig{
+			"anchor": {Enabled: true, Required: true},
+		},
+	}
+
+	if err := config.applyProfile(); err != nil {
+		t.Fatalf("applyProfile failed: %v", err)
+	}
+	if workers := config.Services["anchor"].Config["services.anchor.initial_sync_workers_per_database"]; workers != "1" {
+		t.Errorf("Expected edge initial sync workers '1', got '%s'", workers)
+	}
+}
+
+func TestApplyProfileInvalid(t *testing.T) {
+	config := &Config{Profile: "tiny"}
+	if err := config.applyProfile(); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+
+	config = &Config{
+		Profile: ProfileEdge,
+		Edge:    EdgeConfig{OffloadedServices: map[string]string{"unifiedmodel": ""}},
+	}
+	if err := config.applyProfile(); err == nil {
+		t.Error("Expected an error for an offloaded service without address")
+	}
+}
@@ -0,0 +1,451 @@
+package superconfig
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/redact"
+	"gopkg.in/yaml.v3"
+)
+
+// The schema of the configuration file is the Config struct: sections and keys are its fields,
+// named by their yaml tags. Fields tagged `required:"true"` must be set, and fields tagged
+// `default:"..."` take the tag, parsed as YAML, when the file leaves them empty.
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Problem is a mistake in a configuration file
+type Problem struct {
+	Line    int    // 0 if the problem is not at a position of the file, such as a missing section
+	Column  int    // Column of the key or value at fault
+	Path    string // Dotted path of the key, e.g. supervisor.status_page.port
+	Message string
+}
+
+// ValidationError lists the mistakes found in a configuration file, in the order of the file
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration file %s:", e.File)
+	for _, p := range e.Problems {
+		if p.Line > 0 {
+			fmt.Fprintf(&b, "\n  %s:%d:%d: %s: %s", e.File, p.Line, p.Column, p.Path, p.Message)
+		} else {
+			fmt.Fprintf(&b, "\n  %s: %s: %s", e.File, p.Path, p.Message)
+		}
+	}
+	return b.String()
+}
+
+// document validates a parsed configuration file against the schema, keeping the node of each
+// path so that checks of the decoded configuration can point at the file
+type document struct {
+	file     string
+	nodes    map[string]*yaml.Node
+	problems []Problem
+}
+
+func newDocument(file string) *document {
+	return &document{file: file, nodes: make(map[string]*yaml.Node)}
+}
+
+// problem records a mistake at the position of node, which may be nil
+func (d *document) problem(path string, node *yaml.Node, format string, args ...interface{}) {
+	p := Problem{Path: path, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		p.Line, p.Column = node.Line, node.Column
+	}
+	d.problems = append(d.problems, p)
+}
+
+// err returns the problems found so far as a ValidationError, or nil if there are none
+func (d *document) err() error {
+	if len(d.problems) == 0 {
+		return nil
+	}
+	problems := append([]Problem(nil), d.problems...)
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return &ValidationError{File: d.file, Problems: problems}
+}
+
+// checkFile checks the document root of a file against the schema of Config
+func (d *document) checkFile(root *yaml.Node) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		d.check("", root.Content[0], reflect.TypeOf(Config{}))
+		return
+	}
+	// An empty file sets nothing, so only required keys are missing
+	d.check("", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, reflect.TypeOf(Config{}))
+}
+
+// check checks the node at path against the type of the field it is decoded into
+func (d *document) check(path string, node *yaml.Node, t reflect.Type) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if path != "" {
+		d.nodes[path] = node
+	}
+	if isNull(node) {
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Interface:
+		// Free-form sections, such as database_capabilities, are checked where they are used
+	case t.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			d.mismatch(path, node, t)
+			return
+		}
+		d.checkStruct(path, node, t)
+	case t.Kind() == reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			d.mismatch(path, node, t)
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			d.check(joinPath(path, node.Content[i].Value), node.Content[i+1], t.Elem())
+		}
+	case t.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			d.mismatch(path, node, t)
+			return
+		}
+		for i, item := range node.Content {
+			d.check(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())
+		}
+	default:
+		// Scalars are checked by decoding them, which accepts what loading the file accepts
+		if node.Kind != yaml.ScalarNode || node.Decode(reflect.New(t).Interface()) != nil {
+			d.mismatch(path, node, t)
+		}
+	}
+}
+
+// checkStruct checks the keys of a section, and that its required keys are set
+func (d *document) checkStruct(path string, node *yaml.Node, t reflect.Type) {
+	fields := schemaFields(t)
+	set := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			d.checkMerge(path, value, t)
+			continue
+		}
+		field, ok := fields[key.Value]
+		if !ok {
+			d.unknownKey(joinPath(path, key.Value), key, fields)
+			continue
+		}
+		d.check(joinPath(path, key.Value), value, field.Type)
+		if !isEmpty(value) {
+			set[key.Value] = true
+		}
+	}
+
+	var at *yaml.Node
+	if path != "" {
+		at = node
+	}
+	for _, name := range sortedKeys(fields) {
+		if set[name] {
+			continue
+		}
+		if fields[name].Tag.Get("required") == "true" {
+			d.problem(joinPath(path, name), at, "is required")
+		} else if fields[name].Type.Kind() == reflect.Struct {
+			d.missingRequired(joinPath(path, name), fields[name].Type)
+		}
+	}
+}
+
+// missingRequired records the required keys of a section that is not in the file
+func (d *document) missingRequired(path string, t reflect.Type) {
+	fields := schemaFields(t)
+	for _, name := range sortedKeys(fields) {
+		if fields[name].Tag.Get("required") == "true" {
+			d.problem(joinPath(path, name), nil, "is required")
+		} else if fields[name].Type.Kind() == reflect.Struct {
+			d.missingRequired(joinPath(path, name), fields[name].Type)
+		}
+	}
+}
+
+// checkMerge checks the mappings merged into a section with <<
+func (d *document) checkMerge(path string, node *yaml.Node, t reflect.Type) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			d.check(path, item, t)
+		}
+		return
+	}
+	d.check(path, node, t)
+}
+
+// unknownKey records a key the section does not have, suggesting the key it is closest to
+func (d *document) unknownKey(path string, key *yaml.Node, fields map[string]reflect.StructField) {
+	best, bestDistance := "", 0
+	for name := range fields {
+		distance := editDistance(key.Value, name)
+		if best == "" || distance < bestDistance || distance == bestDistance && name < best {
+			best, bestDistance = name, distance
+		}
+	}
+	if best != "" && (bestDistance <= 2 || bestDistance <= len(key.Value)/3) {
+		d.problem(path, key, "unknown key, did you mean %q?", best)
+		return
+	}
+	d.problem(path, key, "unknown key")
+}
+
+// mismatch records a value of the wrong type
+func (d *document) mismatch(path string, node *yaml.Node, t reflect.Type) {
+	got := "a mapping"
+	switch node.Kind {
+	case yaml.SequenceNode:
+		got = "a list"
+	case yaml.ScalarNode:
+		got = fmt.Sprintf("%q", node.Value)
+	}
+	d.problem(path, node, "expected %s, got %s", describeType(t), got)
+}
+
+// describeType names the values of a type for users
+func describeType(t reflect.Type) string {
+	if t == durationType {
+		return "a duration such as 30s or 5m"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "a mapping"
+	}
+	return "a value"
+}
+
+// schemaFields returns the fields of a section by key
+func schemaFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := yamlName(field); name != "" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// yamlName returns the key of a field in the file, or "" if the field is not read from it
+func yamlName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// isEmpty reports whether a node sets nothing: a null or an empty string
+func isEmpty(node *yaml.Node) bool {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node.Kind == yaml.ScalarNode && (node.Tag == "!!null" || node.Value == "")
+}
+
+func sortedKeys(fields map[string]reflect.StructField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// editDistance returns the Levenshtein distance of two keys
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// applyDefaults sets the fields the file left empty to the default of their schema, and records
+// their paths so that the effective configuration shows which values are defaults. Sections
+// keyed by name, such as services, have no defaults.
+func (c *Config) applyDefaults() error {
+	return c.applyStructDefaults("", reflect.ValueOf(c).Elem())
+}
+
+func (c *Config) applyStructDefaults(path string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := yamlName(field)
+		if name == "" {
+			continue
+		}
+		value := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := c.applyStructDefaults(joinPath(path, name), value); err != nil {
+				return err
+			}
+			continue
+		}
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !value.IsZero() {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(def), value.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid default %q of %s: %w", def, joinPath(path, name), err)
+		}
+		c.markDefault(joinPath(path, name))
+	}
+	return nil
+}
+
+// markDefault records that the value at path was not set in the file
+func (c *Config) markDefault(path string) {
+	if c.defaults == nil {
+		c.defaults = make(map[string]bool)
+	}
+	c.defaults[path] = true
+}
+
+// checkServices checks the services of the loaded configuration: enabled services need an
+// executable, and may only depend on services that are configured
+func (d *document) checkServices(c *Config) {
+	names := make([]string, 0, len(c.Services))
+	for name := range c.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		svc := c.Services[name]
+		if !svc.Enabled {
+			continue
+		}
+		path := joinPath("services", name)
+		if svc.Executable == "" {
+			d.problem(path+".executable", d.nodes[path], "is required for an enabled service")
+		}
+		for _, dep := range svc.Dependencies {
+			if _, ok := c.Services[dep]; !ok {
+				d.problem(path+".dependencies", d.nodes[path+".dependencies"], "depends on %q, which is not configured under services", dep)
+			}
+		}
+	}
+}
+
+// EffectiveYAML returns the configuration as the services run with it, defaults and profile
+// applied, as YAML. Values that were not set in the file are commented as defaults, and
+// sensitive values and those read from secret files are masked.
+func (c *Config) EffectiveYAML() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	c.annotate("", &node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotate comments the defaults under a node and masks its sensitive values
+func (c *Config) annotate(path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinPath(path, key.Value)
+			if c.secrets[keyPath] || key.Value == "master_key" || redact.IsSensitiveKey(key.Value) {
+				mask(value)
+			}
+			if c.defaults[keyPath] {
+				value.LineComment = "default"
+			}
+			c.annotate(keyPath, value)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if c.secrets[itemPath] {
+				mask(item)
+			}
+			c.annotate(itemPath, item)
+		}
+	}
+}
+
+// mask replaces a value that is set with the mask of redacted values
+func mask(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Value != "" {
+		node.Value = redact.Mask
+		node.Style = yaml.DoubleQuotedStyle
+	}
+}
@@ -0,0 +1,140 @@
+package superconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSampleConfigs(t *testing.T) {
+	paths, err := filepath.Glob("../../../../sample_config/*.yaml")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no sample configs found: %v", err)
+	}
+	for _, path := range paths {
+		if _, err := Load(path); err != nil {
+			t.Errorf("Load(%s) failed: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestLoadReportsProblems(t *testing.T) {
+	path := writeConfig(t, `supervisor:
+  helth_check_interval: 10s
+  heartbeat_timeout: 30
+  status_page:
+    port: http
+database:
+  user: redb
+services:
+  core:
+    enabled: true
+    dependencies: [security]
+`)
+
+	_, err := Load(path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Load() = %v, want a ValidationError", err)
+	}
+
+	want := []Problem{
+		{Line: 2, Column: 3, Path: "supervisor.helth_check_interval", Message: `unknown key, did you mean "health_check_interval"?`},
+		{Line: 3, Column: 22, Path: "supervisor.heartbeat_timeout", Message: `expected a duration such as 30s or 5m, got "30"`},
+		{Line: 5, Column: 11, Path: "supervisor.status_page.port", Message: `expected an integer, got "http"`},
+		{Line: 7, Column: 3, Path: "database.name", Message: "is required"},
+	}
+	if len(validation.Problems) != len(want) {
+		t.Fatalf("problems = %+v, want %+v", validation.Problems, want)
+	}
+	for i, p := range validation.Problems {
+		if p != want[i] {
+			t.Errorf("problem %d = %+v, want %+v", i, p, want[i])
+		}
+	}
+	if !strings.Contains(err.Error(), path+":2:3: supervisor.helth_check_interval") {
+		t.Errorf("error does not point at the file position:\n%v", err)
+	}
+}
+
+func TestLoadChecksServices(t *testing.T) {
+	path := writeConfig(t, `database:
+  name: redb
+services:
+  core:
+    enabled: true
+    dependencies: [security]
+  security:
+    enabled: false
+`)
+
+	_, err := Load(path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Load() = %v, want a ValidationError", err)
+	}
+	if len(validation.Problems) != 1 || validation.Problems[0].Path != "services.core.executable" || validation.Problems[0].Line != 5 {
+		t.Errorf("problems = %+v, want the missing executable of core", validation.Problems)
+	}
+
+	path = writeConfig(t, `database:
+  name: redb
+services:
+  core:
+    enabled: true
+    executable: ./redb-core
+    dependencies: [security]
+`)
+	_, err = Load(path)
+	if !errors.As(err, &validation) || validation.Problems[0].Path != "services.core.dependencies" || validation.Problems[0].Line != 7 {
+		t.Errorf("Load() = %v, want the unconfigured dependency of core", err)
+	}
+}
+
+func TestLoadAppliesDefaults(t *testing.T) {
+	path := writeConfig(t, `database:
+  name: redb
+keyring:
+  backend: file
+  master_key: not-for-printing
+`)
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Supervisor.HealthCheckInterval != 10*time.Second || config.Supervisor.StatusPage.Port != 8070 {
+		t.Errorf("supervisor = %+v, want the defaults", config.Supervisor)
+	}
+	if config.Keyring.Backend != "file" || config.Keyring.ServiceName != "redb" || config.Profile != ProfileStandard {
+		t.Errorf("keyring = %+v, profile = %q", config.Keyring, config.Profile)
+	}
+
+	data, err := config.EffectiveYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	effective := string(data)
+	for _, line := range []string{
+		"health_check_interval: 10s # default",
+		"backend: file\n",
+		`master_key: "******"`,
+		"user: redb # default",
+	} {
+		if !strings.Contains(effective, line) {
+			t.Errorf("effective configuration does not contain %q:\n%s", line, effective)
+		}
+	}
+}
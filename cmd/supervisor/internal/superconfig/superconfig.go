@@ -1,6 +1,8 @@
 package superconfig
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,13 +23,54 @@ type Config struct {
 	Global        GlobalConfig             `yaml:"global"`
 	Keyring       KeyringConfig            `yaml:"keyring"`
 	InstanceGroup InstanceGroupConfig      `yaml:"instance_group"`
+	Security      SecurityConfig           `yaml:"security"`
+	Profile       string                   `yaml:"profile" default:"standard"` // Runtime profile: "standard" or "edge"
+	Edge          EdgeConfig               `yaml:"edge"`
+
+	// Databases registered and capabilities overridden for this deployment, with the keys of
+	// dbcapabilities.Config. Exported to the services as JSON, see DatabaseCapabilitiesJSON.
+	DatabaseCapabilities map[string]interface{} `yaml:"database_capabilities"`
+	databaseCapabilities string
+
+	defaults map[string]bool // paths of the values not set in the file, see markDefault
+	secrets  map[string]bool // paths of the values read from secret files, masked when printed
+}
+
+// Runtime profiles
+const (
+	ProfileStandard = "standard"
+	ProfileEdge     = "edge"
+)
+
+// Defaults of the edge profile, sized for small ARM devices
+const (
+	edgeDatabaseMaxConnections = 4
+	edgeDefaultMaxMemoryMB     = 256
+)
+
+// edgeServiceConfig holds the service configuration the edge profile applies unless set
+var edgeServiceConfig = map[string]map[string]string{
+	"anchor": {
+		"services.anchor.initial_sync_workers_per_database": "1",
+	},
 }
 
 type SupervisorConfig struct {
-	Port                int           `yaml:"port"`
-	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
-	HeartbeatTimeout    time.Duration `yaml:"heartbeat_timeout"`
-	ShutdownTimeout     time.Duration `yaml:"shutdown_timeout"`
+	Port                int              `yaml:"port" default:"50000"`
+	HealthCheckInterval time.Duration    `yaml:"health_check_interval" default:"10s"`
+	HeartbeatTimeout    time.Duration    `yaml:"heartbeat_timeout" default:"30s"`
+	ShutdownTimeout     time.Duration    `yaml:"shutdown_timeout" default:"60s"`
+	StatusPage          StatusPageConfig `yaml:"status_page"`
+}
+
+// StatusPageConfig enables the status page of the node, an HTTP endpoint showing the health,
+// uptime and incident history of its services as HTML and JSON
+type StatusPageConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Port            int    `yaml:"port" default:"8070"`              // HTTP port of the status page
+	Title           string `yaml:"title" default:"reDB Node Status"` // Title shown on the HTML page
+	Token           string `yaml:"token"`                            // Bearer token required to view the page, public if empty
+	IncidentHistory int    `yaml:"incident_history" default:"100"`   // Number of incidents kept
 }
 
 type ServiceConfig struct {
@@ -39,11 +83,31 @@ type ServiceConfig struct {
 	Config       map[string]string `yaml:"config"`
 	ExternalPort int               `yaml:"external_port"`
 	RestAPIPort  int               `yaml:"rest_api_port"` // REST API port for services that provide HTTP endpoints
+	Resources    ResourceConfig    `yaml:"resources"`
+	Scaling      ScalingConfig     `yaml:"scaling"`
+}
+
+// ResourceConfig limits the resources of a service process
+type ResourceConfig struct {
+	MaxMemoryMB   int `yaml:"max_memory_mb"`   // Soft memory limit of the Go runtime (GOMEMLIMIT)
+	MaxCPUPercent int `yaml:"max_cpu_percent"` // Share of the host CPUs the service runs on (GOMAXPROCS)
+}
+
+// ScalingConfig bounds the number of replicas the supervisor recommends for a service to
+// external orchestrators. Services without max_replicas get no recommendations.
+type ScalingConfig struct {
+	MinReplicas      int           `yaml:"min_replicas"`       // Fewest replicas recommended, 1 if not set
+	MaxReplicas      int           `yaml:"max_replicas"`       // Most replicas recommended
+	TargetCPUPercent float64       `yaml:"target_cpu_percent"` // Average CPU usage of a replica, 70 if not set
+	TargetQueueDepth int           `yaml:"target_queue_depth"` // Queued requests and pending runs per replica, CPU only if not set
+	ScaleDownDelay   time.Duration `yaml:"scale_down_delay"`   // Time the load must stay low before fewer replicas are recommended, 5m if not set
 }
 
 type DatabaseConfig struct {
-	Name string `yaml:"name"`
-	User string `yaml:"user"` // Database username for this instance
+	Name string `yaml:"name" required:"true"`
+	User string `yaml:"user" default:"redb"` // Database username for this instance
+
+	MaxConnections int `yaml:"max_connections"` // Connection pool size of each service, 0 for the service default
 }
 
 type LoggingConfig struct {
@@ -53,7 +117,7 @@ type LoggingConfig struct {
 }
 
 type LicenseConfig struct {
-	Distribution string `yaml:"distribution"`
+	Distribution string `yaml:"distribution" default:"open-source"`
 }
 
 type GlobalConfig struct {
@@ -61,98 +125,206 @@ type GlobalConfig struct {
 }
 
 type MultiTenancyConfig struct {
-	Mode              string `yaml:"mode"`                // "single-tenant" or "multi-tenant"
-	DefaultTenantID   string `yaml:"default_tenant_id"`   // Used in single-tenant mode
-	DefaultTenantName string `yaml:"default_tenant_name"` // Used in single-tenant mode
-	DefaultTenantURL  string `yaml:"default_tenant_url"`  // Used in single-tenant mode
+	Mode              string `yaml:"mode" default:"single-tenant"`                 // "single-tenant" or "multi-tenant"
+	DefaultTenantID   string `yaml:"default_tenant_id" default:"default-tenant"`   // Used in single-tenant mode
+	DefaultTenantName string `yaml:"default_tenant_name" default:"Default Tenant"` // Used in single-tenant mode
+	DefaultTenantURL  string `yaml:"default_tenant_url" default:"default"`         // Used in single-tenant mode
 }
 
 type KeyringConfig struct {
-	Backend     string `yaml:"backend"`      // "system" or "file"
-	Path        string `yaml:"path"`         // Path for file-based keyring
-	MasterKey   string `yaml:"master_key"`   // Master key for encryption (use env var in production)
-	ServiceName string `yaml:"service_name"` // Service name prefix for system keyring
+	Backend     string `yaml:"backend" default:"auto"`      // "auto" (system, falling back to file), "system" or "file"
+	Path        string `yaml:"path"`                        // Path for file-based keyring
+	MasterKey   string `yaml:"master_key"`                  // Master key for encryption (use env var in production)
+	ServiceName string `yaml:"service_name" default:"redb"` // Service name prefix for system keyring
 }
 
 type InstanceGroupConfig struct {
-	GroupID    string `yaml:"group_id"`    // Unique identifier for this instance group
-	PortOffset int    `yaml:"port_offset"` // Port offset to avoid conflicts
+	GroupID    string `yaml:"group_id" default:"default"` // Unique identifier for this instance group
+	PortOffset int    `yaml:"port_offset"`                // Port offset to avoid conflicts
+}
+
+type SecurityConfig struct {
+	SPIFFE SPIFFEConfig `yaml:"spiffe"`
+}
+
+// EdgeConfig tunes the edge profile, which runs a node on small devices collecting data at the
+// edge. Heavy services can be disabled, or offloaded to a remote node reachable through the mesh.
+type EdgeConfig struct {
+	DisabledServices   []string          `yaml:"disabled_services"`     // Services not run on this node
+	OffloadedServices  map[string]string `yaml:"offloaded_services"`    // Services used on a remote node, by gRPC address
+	DefaultMaxMemoryMB int               `yaml:"default_max_memory_mb"` // Memory ceiling of services without resources of their own
+}
+
+// SPIFFEConfig enables mTLS between services with workload identities issued by SPIRE
+type SPIFFEConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	SocketPath  string   `yaml:"socket_path"`  // Workload API socket of the SPIRE agent
+	TrustDomain string   `yaml:"trust_domain"` // Peers must belong to this trust domain
+	AllowedIDs  []string `yaml:"allowed_ids"`  // Optional list of accepted peer SPIFFE IDs
 }
 
+// Load reads a configuration file and checks it against the schema of Config, returning a
+// ValidationError with the position of every unknown key, value of the wrong type and missing
+// required setting. References to environment variables and secret files are replaced first,
+// see interpolate.go. Settings left empty take their defaults, then the runtime profile is applied.
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
-
-	// Set defaults
-	if config.Supervisor.Port == 0 {
-		config.Supervisor.Port = 50000
+	doc := newDocument(path)
+	interpolator := newInterpolator(doc)
+	interpolator.interpolateFile(&root)
+	if err := doc.err(); err != nil {
+		return nil, err
 	}
-	if config.Supervisor.HealthCheckInterval == 0 {
-		config.Supervisor.HealthCheckInterval = 10 * time.Second
+	doc.checkFile(&root)
+	if err := doc.err(); err != nil {
+		return nil, err
 	}
-	if config.Supervisor.HeartbeatTimeout == 0 {
-		config.Supervisor.HeartbeatTimeout = 30 * time.Second
+
+	var config Config
+	if err := root.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
-	if config.Supervisor.ShutdownTimeout == 0 {
-		config.Supervisor.ShutdownTimeout = 60 * time.Second
+	config.secrets = interpolator.secrets
+	if err := config.applyDefaults(); err != nil {
+		return nil, err
 	}
 
-	// Set defaults for new configuration sections
-	if config.License.Distribution == "" {
-		config.License.Distribution = "open-source"
+	// Apply port offset to supervisor port for multi-instance support
+	// This must be done after all defaults are set, but we need to avoid double-applying
+	// We'll apply the offset directly here since ApplyPortOffset would add it again
+	basePort := 50000 // The original default port before any offset
+	config.Supervisor.Port = basePort + config.InstanceGroup.PortOffset
+
+	if err := config.applyProfile(); err != nil {
+		return nil, err
 	}
 
-	if config.Global.MultiTenancy.Mode == "" {
-		config.Global.MultiTenancy.Mode = "single-tenant"
+	doc.checkServices(&config)
+	if err := config.loadDatabaseCapabilities(); err != nil {
+		doc.problem("database_capabilities", doc.nodes["database_capabilities"], "%v", errors.Unwrap(err))
 	}
-	if config.Global.MultiTenancy.DefaultTenantID == "" {
-		config.Global.MultiTenancy.DefaultTenantID = "default-tenant"
+	if err := doc.err(); err != nil {
+		return nil, err
 	}
-	if config.Global.MultiTenancy.DefaultTenantName == "" {
-		config.Global.MultiTenancy.DefaultTenantName = "Default Tenant"
+
+	return &config, nil
+}
+
+// loadDatabaseCapabilities checks the database_capabilities section against the built-in
+// registry, so a misspelled field or an unknown database stops the node before its services start
+func (c *Config) loadDatabaseCapabilities() error {
+	if len(c.DatabaseCapabilities) == 0 {
+		return nil
 	}
-	if config.Global.MultiTenancy.DefaultTenantURL == "" {
-		config.Global.MultiTenancy.DefaultTenantURL = "default"
+	data, err := json.Marshal(c.DatabaseCapabilities)
+	if err != nil {
+		return fmt.Errorf("invalid database_capabilities: %w", err)
 	}
-
-	if config.Keyring.Backend == "" {
-		config.Keyring.Backend = "auto" // auto-detect system keyring, fallback to file
+	capabilities, err := dbcapabilities.ParseConfig(data)
+	if err != nil {
+		return fmt.Errorf("invalid database_capabilities: %w", err)
 	}
-	if config.Keyring.ServiceName == "" {
-		config.Keyring.ServiceName = "redb"
+	if err := capabilities.Validate(); err != nil {
+		return fmt.Errorf("invalid database_capabilities: %w", err)
+	}
+	c.databaseCapabilities = string(data)
+	return nil
+}
+
+// DatabaseCapabilitiesJSON returns the database_capabilities section as the JSON the services
+// read from dbcapabilities.EnvConfig, or "" if it is not set
+func (c *Config) DatabaseCapabilitiesJSON() string {
+	return c.databaseCapabilities
+}
+
+// applyProfile applies the runtime profile to the configuration. The edge profile reduces pool
+// sizes, sets memory ceilings and disables the services that are not run on the node; the
+// other services reach offloaded services at their remote address.
+func (c *Config) applyProfile() error {
+	switch c.Profile {
+	case "", ProfileStandard:
+		c.Profile = ProfileStandard
+		return nil
+	case ProfileEdge:
+	default:
+		return fmt.Errorf("unknown profile %q, expected %q or %q", c.Profile, ProfileStandard, ProfileEdge)
 	}
 
-	if config.InstanceGroup.GroupID == "" {
-		config.InstanceGroup.GroupID = "default"
+	if c.Database.MaxConnections == 0 {
+		c.Database.MaxConnections = edgeDatabaseMaxConnections
+		c.markDefault("database.max_connections")
 	}
-	if config.InstanceGroup.PortOffset == 0 {
-		config.InstanceGroup.PortOffset = 0 // Default no offset
+	if c.Edge.DefaultMaxMemoryMB == 0 {
+		c.Edge.DefaultMaxMemoryMB = edgeDefaultMaxMemoryMB
+		c.markDefault("edge.default_max_memory_mb")
 	}
 
-	// Set database defaults
-	if config.Database.User == "" {
-		config.Database.User = "redb" // Default database user
+	removed := make(map[string]bool)
+	for _, name := range c.Edge.DisabledServices {
+		removed[name] = true
+	}
+	for name, address := range c.Edge.OffloadedServices {
+		if address == "" {
+			return fmt.Errorf("edge.offloaded_services.%s requires the gRPC address of the remote service", name)
+		}
+		removed[name] = true
 	}
 
-	// Apply port offset to supervisor port for multi-instance support
-	// This must be done after all defaults are set, but we need to avoid double-applying
-	// We'll apply the offset directly here since ApplyPortOffset would add it again
-	basePort := 50000 // The original default port before any offset
-	config.Supervisor.Port = basePort + config.InstanceGroup.PortOffset
+	for name, svc := range c.Services {
+		if removed[name] {
+			svc.Enabled = false
+			svc.Required = false
+			c.Services[name] = svc
+			continue
+		}
 
-	// Validate required configuration
-	if config.Database.Name == "" {
-		return nil, fmt.Errorf("database.name is required in configuration file")
+		dependencies := make([]string, 0, len(svc.Dependencies))
+		for _, dep := range svc.Dependencies {
+			if !removed[dep] {
+				dependencies = append(dependencies, dep)
+			}
+		}
+		svc.Dependencies = dependencies
+
+		if svc.Resources.MaxMemoryMB == 0 {
+			svc.Resources.MaxMemoryMB = c.Edge.DefaultMaxMemoryMB
+			c.markDefault(fmt.Sprintf("services.%s.resources.max_memory_mb", name))
+		}
+
+		if svc.Config == nil {
+			svc.Config = make(map[string]string)
+		}
+		for offloaded, address := range c.Edge.OffloadedServices {
+			key := fmt.Sprintf("services.%s.grpc_address", offloaded)
+			if _, ok := svc.Config[key]; !ok {
+				svc.Config[key] = address
+				c.markDefault(fmt.Sprintf("services.%s.config.%s", name, key))
+			}
+		}
+		for key, value := range edgeServiceConfig[name] {
+			if _, ok := svc.Config[key]; !ok {
+				svc.Config[key] = value
+				c.markDefault(fmt.Sprintf("services.%s.config.%s", name, key))
+			}
+		}
+
+		c.Services[name] = svc
 	}
 
-	return &config, nil
+	return nil
+}
+
+// IsEdge returns true if the node runs the edge profile
+func (c *Config) IsEdge() bool {
+	return c.Profile == ProfileEdge
 }
 
 func (c *Config) GetServiceStartupOrder() []string {
@@ -289,6 +461,13 @@ func (c *Config) GetPortOffset() int {
 
 // GetServiceGRPCAddress implements GRPCServiceProvider
 func (c *Config) GetServiceGRPCAddress(serviceName string) string {
+	// Offloaded services run on a remote node
+	if c.IsEdge() {
+		if address := c.Edge.OffloadedServices[serviceName]; address != "" {
+			return address
+		}
+	}
+
 	basePort := c.GetServiceBaseGRPCPort(serviceName)
 	if basePort == 0 {
 		return "" // Service not found or not configured
@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BulkInserter is implemented by data operators that can load rows through the
+// database's native bulk load primitive (Postgres COPY, MySQL LOAD DATA, Snowflake
+// PUT and COPY INTO). Bulk loads are much faster than Insert for large batches,
+// such as the initial sync of a mapping. Databases with a bulk load primitive
+// report it in dbcapabilities (SupportsBulkLoad).
+type BulkInserter interface {
+	// BulkInsert loads rows into a table and returns the number of rows loaded.
+	// The columns of the first row are loaded; missing values are loaded as NULL.
+	BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error)
+}
+
+// BulkInsert loads rows with the native bulk load primitive of the database if the
+// data operator has one, and with Insert otherwise.
+func BulkInsert(ctx context.Context, ops DataOperator, table string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if bulk, ok := ops.(BulkInserter); ok {
+		return bulk.BulkInsert(ctx, table, data)
+	}
+	return ops.Insert(ctx, table, data)
+}
+
+// BulkColumns returns the columns a bulk load of data loads: the columns of the
+// first row, sorted so that the order is stable.
+func BulkColumns(data []map[string]interface{}) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	columns := make([]string, 0, len(data[0]))
+	for column := range data[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// FormatBulkValue formats a non-nil value as text for the delimited files used by
+// bulk loads. Times use a format all supported databases parse, and maps, slices
+// and other composite values are encoded as JSON.
+func FormatBulkValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999-07:00"), nil
+	case json.Number:
+		return v.String(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode value of type %T: %w", value, err)
+		}
+		return string(encoded), nil
+	}
+}
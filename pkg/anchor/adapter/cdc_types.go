@@ -87,10 +87,27 @@ type TransformationRule struct {
 	TransformationName string                 `json:"transformation_name,omitempty"` // Name of transformation function (e.g., "reverse", "uppercase")
 	Parameters         map[string]interface{} `json:"parameters,omitempty"`
 
+	// Condition on source columns, the rule only maps rows matching it (see pkg/rowfilter)
+	RowFilter string `json:"row_filter,omitempty"`
+
+	// Columns of a many-to-one or one-to-many rule, in order. SourceColumn and TargetColumn are
+	// the first of them.
+	SourceColumns []string `json:"source_columns,omitempty"`
+	TargetColumns []string `json:"target_columns,omitempty"`
+
+	// Tenant of the rule, whose salt the masking transformations are keyed with
+	TenantID string `json:"tenant_id,omitempty"`
+
 	// Metadata
 	Description string `json:"description,omitempty"`
 }
 
+// IsComposite reports whether the rule reads or writes several columns, which the
+// transformation service transforms at once
+func (r *TransformationRule) IsComposite() bool {
+	return len(r.SourceColumns) > 1 || len(r.TargetColumns) > 1
+}
+
 // TransformationType constants
 const (
 	// TransformDirect - direct field mapping with no transformation
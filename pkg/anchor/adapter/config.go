@@ -25,6 +25,22 @@ type ConnectionConfig struct {
 	Password     string `json:"password,omitempty"`
 	DatabaseName string `json:"databaseName"`
 
+	// Unix domain socket or Windows named pipe of a database on the anchor node, connected to
+	// in place of Host and Port; SocketPathAuto discovers it, see DiscoverSocketPath
+	SocketPath string `json:"socketPath,omitempty"`
+
+	// Local emulator of a cloud database, such as DynamoDB Local, connected to in place of the
+	// cloud service; see EmulatorConfig
+	Emulator bool `json:"emulator,omitempty"`
+
+	// Cloud IAM authentication in place of Password, see IAMAuth. Tokens is set when the
+	// registry connects and shared by the physical connections of the adapter.
+	IAMAuth *IAMAuth    `json:"iamAuth,omitempty"`
+	Tokens  TokenSource `json:"-"`
+
+	// Kerberos authentication in place of Password, see KerberosAuth
+	KerberosAuth *KerberosAuth `json:"kerberosAuth,omitempty"`
+
 	// SSL/TLS configuration
 	SSL                   bool    `json:"ssl,omitempty"`
 	SSLMode               string  `json:"sslMode,omitempty"` // verify-full, require, etc.
@@ -33,6 +49,17 @@ type ConnectionConfig struct {
 	SSLKey                *string `json:"sslKey,omitempty"`
 	SSLRootCert           *string `json:"sslRootCert,omitempty"`
 
+	// TLS client certificate, CA bundle and verification, as PEM contents or paths of PEM
+	// files on the anchor node; see TLSConfig
+	TLSClientCert string `json:"tlsClientCert,omitempty"`
+	TLSClientKey  string `json:"tlsClientKey,omitempty"`
+	TLSCABundle   string `json:"tlsCaBundle,omitempty"`
+	TLSVerifyMode string `json:"tlsVerifyMode,omitempty"` // tlsconfig.VerifyFull (default), VerifyCA or VerifyNone
+	TLSServerName string `json:"tlsServerName,omitempty"` // Host name verified against the server certificate, Host if empty
+
+	// Jump host through which the database is reached
+	SSHTunnel *SSHTunnel `json:"sshTunnel,omitempty"`
+
 	// Additional options
 	Role              string `json:"role,omitempty"`
 	ConnectedToNodeID string `json:"connectedToNodeId,omitempty"`
@@ -59,10 +86,59 @@ type ConnectionConfig struct {
 	// Azure specific
 	ConnectionString string `json:"connectionString,omitempty"`
 
+	// Read replicas
+	Replicas      []ReplicaEndpoint `json:"replicas,omitempty"`
+	RoutingPolicy string            `json:"routingPolicy,omitempty"` // RoutingPolicyPrimary (default) or RoutingPolicyReadsFromReplica
+
+	// Load guardrails of the database, see Throttle
+	RateLimits *RateLimits `json:"rateLimits,omitempty"`
+
 	// Database-specific options (use sparingly)
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
+// Routing policies of connections with read replicas.
+const (
+	// RoutingPolicyPrimary sends all operations to the primary; replicas are not used.
+	RoutingPolicyPrimary = "primary"
+
+	// RoutingPolicyReadsFromReplica sends schema discovery and data reads to the replicas
+	// and writes to the primary. Reads fall back to the primary when no replica is healthy.
+	RoutingPolicyReadsFromReplica = "reads-from-replica"
+)
+
+// ReplicaEndpoint is a read replica of a database. The replica is connected to with the
+// settings of the primary, except for its address and, if set, its credentials.
+type ReplicaEndpoint struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// RateLimits caps the load the anchor puts on a database, whatever the operations running on
+// it: schema discovery, replication, queries and data copies. Zero fields are unlimited.
+type RateLimits struct {
+	RowsPerSecond           float64 `json:"rowsPerSecond,omitempty"`           // Rows read and written
+	QueriesPerSecond        float64 `json:"queriesPerSecond,omitempty"`        // Statements started
+	MaxConcurrentStatements int     `json:"maxConcurrentStatements,omitempty"` // Statements running at once
+}
+
+// IsZero reports whether the limits leave the database unlimited.
+func (l *RateLimits) IsZero() bool {
+	return l == nil || (l.RowsPerSecond <= 0 && l.QueriesPerSecond <= 0 && l.MaxConcurrentStatements <= 0)
+}
+
+// Secrets returns the credentials of the configuration, to be scrubbed from logs and errors.
+func (c ConnectionConfig) Secrets() []string {
+	secrets := []string{c.Password, c.SecretAccessKey, c.SessionToken, c.CredentialsJSON, c.Token, c.ConnectionString, GetString(c.SSLKey), c.TLSClientKey}
+	secrets = append(secrets, c.SSHTunnel.secrets()...)
+	for _, replica := range c.Replicas {
+		secrets = append(secrets, replica.Password)
+	}
+	return secrets
+}
+
 // InstanceConfig contains the configuration for a database instance connection.
 type InstanceConfig struct {
 	// Core identifiers
@@ -90,6 +166,22 @@ type InstanceConfig struct {
 	Password     string `json:"password,omitempty"`
 	DatabaseName string `json:"databaseName"` // System database for connection
 
+	// Unix domain socket or Windows named pipe of an instance on the anchor node, connected to
+	// in place of Host and Port; SocketPathAuto discovers it, see DiscoverSocketPath
+	SocketPath string `json:"socketPath,omitempty"`
+
+	// Local emulator of a cloud database, such as DynamoDB Local, connected to in place of the
+	// cloud service; see EmulatorConfig
+	Emulator bool `json:"emulator,omitempty"`
+
+	// Cloud IAM authentication in place of Password, see IAMAuth. Tokens is set when the
+	// registry connects and shared by the physical connections of the adapter.
+	IAMAuth *IAMAuth    `json:"iamAuth,omitempty"`
+	Tokens  TokenSource `json:"-"`
+
+	// Kerberos authentication in place of Password, see KerberosAuth
+	KerberosAuth *KerberosAuth `json:"kerberosAuth,omitempty"`
+
 	// SSL/TLS configuration
 	SSL                   bool    `json:"ssl,omitempty"`
 	SSLMode               string  `json:"sslMode,omitempty"`
@@ -98,6 +190,17 @@ type InstanceConfig struct {
 	SSLKey                *string `json:"sslKey,omitempty"`
 	SSLRootCert           *string `json:"sslRootCert,omitempty"`
 
+	// TLS client certificate, CA bundle and verification, as PEM contents or paths of PEM
+	// files on the anchor node; see TLSConfig
+	TLSClientCert string `json:"tlsClientCert,omitempty"`
+	TLSClientKey  string `json:"tlsClientKey,omitempty"`
+	TLSCABundle   string `json:"tlsCaBundle,omitempty"`
+	TLSVerifyMode string `json:"tlsVerifyMode,omitempty"` // tlsconfig.VerifyFull (default), VerifyCA or VerifyNone
+	TLSServerName string `json:"tlsServerName,omitempty"` // Host name verified against the server certificate, Host if empty
+
+	// Jump host through which the database is reached
+	SSHTunnel *SSHTunnel `json:"sshTunnel,omitempty"`
+
 	// Additional options
 	Role              string `json:"role,omitempty"`
 	ConnectedToNodeID string `json:"connectedToNodeId,omitempty"`
@@ -128,6 +231,12 @@ type InstanceConfig struct {
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
+// Secrets returns the credentials of the configuration, to be scrubbed from logs and errors.
+func (c InstanceConfig) Secrets() []string {
+	secrets := []string{c.Password, c.SecretAccessKey, c.SessionToken, c.CredentialsJSON, c.Token, c.ConnectionString, GetString(c.SSLKey), c.TLSClientKey}
+	return append(secrets, c.SSHTunnel.secrets()...)
+}
+
 // ReplicationConfig contains the configuration for a replication connection.
 type ReplicationConfig struct {
 	// Core identifiers
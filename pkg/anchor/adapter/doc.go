@@ -60,6 +60,130 @@
 //	    }
 //	}
 //
+// # Connection Pooling
+//
+// The registry pools connections. Connect and ConnectInstance return a handle on a
+// shared connection: configurations that only differ in their IDs and metadata
+// (name, description, owner, ...) share one physical connection, and concurrent
+// callers wait for a single dial. Closing a handle releases it; once the last
+// handle is closed the connection is kept open for the idle timeout
+// (DefaultPoolIdleTimeout) so that short-lived operations don't re-dial. A
+// connection that is no longer connected is replaced on the next Connect.
+//
+//	registry.SetPoolIdleTimeout(time.Minute)
+//	stats := registry.PoolStats()
+//	registry.CloseIdleConnections()
+//
+// # Read Replicas
+//
+// A configuration can list read replicas of the database. With the
+// reads-from-replica routing policy, Connect returns a connection that sends
+// schema discovery and data reads to the replicas in turn and writes to the
+// primary. A replica that fails a read and does not answer a ping is skipped for
+// ReplicaRetryInterval, and the read is retried on the next replica or, when no
+// replica is healthy, on the primary:
+//
+//	config.Replicas = []adapter.ReplicaEndpoint{{Host: "replica-1", Port: 5432}}
+//	config.RoutingPolicy = adapter.RoutingPolicyReadsFromReplica
+//	conn, err := registry.Connect(ctx, config)
+//
+// # TLS
+//
+// Adapters connect with TLS when SSL is set, using the configuration of TLSConfig:
+// TLSClientCert and TLSClientKey are presented to servers that require mutual TLS,
+// TLSCABundle replaces the system pool for verifying the server, and TLSVerifyMode
+// selects whether the chain and host name (tlsconfig.VerifyFull), only the chain
+// (tlsconfig.VerifyCA) or nothing (tlsconfig.VerifyNone) is verified; without it the
+// verification follows SSLMode. Certificates and keys are PEM contents or paths of PEM
+// files on the anchor node. Connections without any of these fields keep using the
+// SSL fields:
+//
+//	config.SSL = true
+//	config.TLSClientCert, config.TLSClientKey = certPEM, keyPEM
+//	config.TLSCABundle = "/etc/redb/db-ca.pem"
+//	tlsConfig, err := config.TLSConfig()
+//
+// # SSH Tunnels
+//
+// A database behind a jump host is reached by setting SSHTunnel. Connect and
+// ConnectInstance open an SSH connection to the jump host, forward a local port to
+// the Host and Port of the database from there and connect the adapter to that
+// port; the tunnel is closed with the pooled connection. The jump host is verified
+// against HostKey and authenticated to with PrivateKey (PEM contents or a path),
+// Password or the keys of the SSH agent at SSH_AUTH_SOCK. TLS certificates are still
+// verified against the host name of the database, see TLSServerName. Replicas are
+// reached through the same jump host:
+//
+//	config.SSHTunnel = &adapter.SSHTunnel{
+//	    Host:       "bastion.example.com",
+//	    Username:   "redb",
+//	    PrivateKey: "/etc/redb/bastion_ed25519",
+//	    HostKey:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5...",
+//	}
+//	conn, err := registry.Connect(ctx, config)
+//
+// # IAM Authentication
+//
+// Managed databases are authenticated to with short-lived tokens of the cloud
+// provider in place of a password by setting IAMAuth: RDS IAM tokens (aws-rds),
+// Cloud SQL IAM tokens (gcp-cloudsql) or Microsoft Entra ID tokens (azure-ad). The
+// anchor registers the providers, see RegisterTokenProvider, which use the
+// credentials of its environment. Connect and ConnectInstance set the token source
+// of the configuration, whose tokens are cached and refreshed before they expire;
+// adapters supporting IAM authentication call AuthToken for every new physical
+// connection:
+//
+//	config.IAMAuth = &adapter.IAMAuth{Provider: adapter.IAMProviderAWSRDS}
+//	poolConfig.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+//	    token, err := config.AuthToken(ctx)
+//	    cc.Password = token
+//	    return err
+//	}
+//
+// # Kerberos Authentication
+//
+// Enterprise databases that only accept domain accounts are authenticated to with
+// Kerberos (GSSAPI) in place of a password by setting KerberosAuth (PostgreSQL, SQL
+// Server, Oracle). Tickets come from a keytab of the principal or from a credential
+// cache that kinit or k5start keeps renewed, both files on the anchor node. The
+// service principal defaults to the one the database type registers on the host,
+// see KerberosSPN. New physical connections of the pool obtain their tickets from a
+// client that renews the ticket-granting ticket of a keytab, and reloads a credential
+// cache once it was renewed:
+//
+//	config.KerberosAuth = &adapter.KerberosAuth{
+//	    Principal: "redb@EXAMPLE.COM",
+//	    Keytab:    "/etc/redb/redb.keytab",
+//	}
+//	conn, err := registry.Connect(ctx, config)
+//
+// # Local Sockets
+//
+// Databases running on the anchor node, whose servers may not listen on TCP, are
+// connected to through a Unix domain socket (PostgreSQL, MySQL, MariaDB) or a Windows
+// named pipe (SQL Server) by setting SocketPath in place of Host and Port. With
+// SocketPathAuto, Connect and ConnectInstance use the first of the DefaultSocketPaths
+// of the database type that exists, e.g. /var/run/postgresql/.s.PGSQL.5432. Sockets
+// are not encrypted, so SSL settings are ignored, and cannot be combined with an SSH
+// tunnel:
+//
+//	config.SocketPath = adapter.SocketPathAuto
+//	conn, err := registry.Connect(ctx, config)
+//
+// # Rate Limits
+//
+// Throttle wraps a connection so that its operations stay within the RateLimits of
+// the configuration: statements started per second, rows read and written per second
+// and statements running at once. The limits apply to all operations on the
+// connection together, whether schema discovery, replication, queries or data
+// copies. Operations wait for the limits, and fail with ErrRateLimited when their
+// context would expire first. The anchor throttles the connection of every database
+// with the limits of its configuration:
+//
+//	config.RateLimits = &adapter.RateLimits{RowsPerSecond: 5000, MaxConcurrentStatements: 2}
+//	conn, err := registry.Connect(ctx, config)
+//	conn = adapter.Throttle(conn, config.RateLimits)
+//
 // # Capability-Based Design
 //
 // The adapter system is designed around database capabilities. Not all databases
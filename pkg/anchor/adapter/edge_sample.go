@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxEdgeSample is the most edges an edge sample returns.
+const MaxEdgeSample = 100000
+
+// Edge is a relationship of a graph, as the IDs of the nodes it connects.
+type Edge struct {
+	From string
+	To   string
+}
+
+// EdgeSampler is implemented by data operators of graph databases that can read the
+// relationships of a type as edges.
+type EdgeSampler interface {
+	// SampleEdges returns up to limit relationships of a type, with the IDs the database gives
+	// their start and end nodes. IDs are only comparable within a database.
+	SampleEdges(ctx context.Context, relationship string, limit int) ([]Edge, error)
+}
+
+// ValidateEdgeSample returns an error if the arguments of an edge sample are invalid.
+func ValidateEdgeSample(relationship string, limit int) error {
+	if relationship == "" {
+		return fmt.Errorf("%w: relationship type is empty", ErrInvalidQuery)
+	}
+	if limit < 1 || limit > MaxEdgeSample {
+		return fmt.Errorf("%w: limit must be between 1 and %d, got %d", ErrInvalidQuery, MaxEdgeSample, limit)
+	}
+	return nil
+}
+
+// AsEdgeSampler returns the edge sampler of a data operator, if it has one.
+func AsEdgeSampler(ops DataOperator) (EdgeSampler, bool) {
+	// The instrumented, routed and throttled operators always have the method; they sample
+	// edges only if the operator they wrap does
+	if instrumented, ok := ops.(*instrumentedDataOperator); ok {
+		if _, ok := AsEdgeSampler(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledDataOperator); ok {
+		if _, ok := AsEdgeSampler(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedDataOperator); ok {
+		if _, ok := AsEdgeSampler(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	sampler, ok := ops.(EdgeSampler)
+	return sampler, ok
+}
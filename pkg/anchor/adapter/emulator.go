@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// Well-known credentials of the emulators, published with them and only accepted by them.
+// Adapters use them when an emulator configuration leaves its credentials empty.
+const (
+	// DynamoDB Local accepts any key made of letters and digits
+	DynamoDBEmulatorAccessKey = "redb"
+	DynamoDBEmulatorSecretKey = "redb"
+
+	AzuriteAccountName = "devstoreaccount1"
+	AzuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+	CosmosDBEmulatorKey = "C2y6yDjf5/R+ob0N8A7Cgv30VRDJIWEHLM+4QDU5DE2nQ9nDuVTqobD4b8mGGyPMbIZnqyMsEcaGQy67XIw/Jw=="
+
+	// The BigQuery emulator serves the project it is started with, --project=test in the
+	// documented setup
+	BigQueryEmulatorProject = "test"
+)
+
+// emulators are the environment variables pointing the emulator tests of the adapters at a
+// running emulator, and the default port of the emulator
+var emulators = map[dbcapabilities.DatabaseType]struct {
+	env  string
+	port int
+}{
+	dbcapabilities.DynamoDB:  {"REDB_TEST_DYNAMODB_EMULATOR", 8000},
+	dbcapabilities.AzureBlob: {"REDB_TEST_AZURITE", 10000},
+	dbcapabilities.CosmosDB:  {"REDB_TEST_COSMOSDB_EMULATOR", 8081},
+	dbcapabilities.BigQuery:  {"REDB_TEST_BIGQUERY_EMULATOR", 9050},
+}
+
+// EmulatorEnv returns the environment variable pointing the emulator tests of an adapter at a
+// running emulator, or an empty string if the database type has no supported emulator.
+func EmulatorEnv(dbType dbcapabilities.DatabaseType) string {
+	return emulators[dbType].env
+}
+
+// EmulatorConfig returns the configuration connecting to the emulator of a database type that
+// its environment variable points at, as host:port or as an http or https URL. It returns
+// false if the variable is not set, so that tests needing the emulator can be skipped.
+//
+// The configuration only sets the address and Emulator; adapters fill in the well-known
+// credentials of the emulator and work around its differences from the cloud service.
+func EmulatorConfig(dbType dbcapabilities.DatabaseType) (ConnectionConfig, bool, error) {
+	emulator, ok := emulators[dbType]
+	if !ok {
+		return ConnectionConfig{}, false, fmt.Errorf("%w: %s has no supported emulator", ErrInvalidConfiguration, dbType)
+	}
+	address := strings.TrimSpace(os.Getenv(emulator.env))
+	if address == "" {
+		return ConnectionConfig{}, false, nil
+	}
+
+	ssl := false
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ConnectionConfig{}, false, fmt.Errorf("%w: %s must be host:port or an http or https URL, got %q",
+				ErrInvalidConfiguration, emulator.env, address)
+		}
+		ssl = u.Scheme == "https"
+		address = u.Host
+	}
+
+	host, port := address, emulator.port
+	if h, p, err := net.SplitHostPort(address); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return ConnectionConfig{}, false, fmt.Errorf("%w: invalid port in %s: %q", ErrInvalidConfiguration, emulator.env, p)
+		}
+		host, port = h, n
+	}
+
+	cfg := ConnectionConfig{
+		DatabaseID:     "emulator-" + string(dbType),
+		TenantID:       "emulator",
+		WorkspaceID:    "emulator",
+		Name:           string(dbType) + " emulator",
+		ConnectionType: string(dbType),
+		DatabaseVendor: "emulator",
+		Host:           host,
+		Port:           port,
+		SSL:            ssl,
+		Emulator:       true,
+	}
+	if dbType == dbcapabilities.BigQuery {
+		cfg.ProjectID = BigQueryEmulatorProject
+	}
+	return cfg, true, nil
+}
+
+// EmulatorEndpoint returns the base URL of an emulator. Emulators serve plain HTTP unless
+// they are configured with SSL.
+func EmulatorEndpoint(host string, port int, ssl bool) string {
+	scheme := "http"
+	if ssl {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(port)))
+}
@@ -0,0 +1,42 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func TestEmulatorConfig(t *testing.T) {
+	t.Setenv("REDB_TEST_DYNAMODB_EMULATOR", "")
+	if _, ok, err := EmulatorConfig(dbcapabilities.DynamoDB); ok || err != nil {
+		t.Errorf("EmulatorConfig without its variable = %v, %v, want not set", ok, err)
+	}
+
+	t.Setenv("REDB_TEST_DYNAMODB_EMULATOR", "localhost")
+	cfg, ok, err := EmulatorConfig(dbcapabilities.DynamoDB)
+	if !ok || err != nil || cfg.Host != "localhost" || cfg.Port != 8000 || cfg.SSL || !cfg.Emulator {
+		t.Errorf("EmulatorConfig(localhost) = %+v, %v, %v", cfg, ok, err)
+	}
+
+	t.Setenv("REDB_TEST_COSMOSDB_EMULATOR", "https://127.0.0.1:8082")
+	cfg, ok, err = EmulatorConfig(dbcapabilities.CosmosDB)
+	if !ok || err != nil || cfg.Host != "127.0.0.1" || cfg.Port != 8082 || !cfg.SSL {
+		t.Errorf("EmulatorConfig(https URL) = %+v, %v, %v", cfg, ok, err)
+	}
+	if endpoint := EmulatorEndpoint(cfg.Host, cfg.Port, cfg.SSL); endpoint != "https://127.0.0.1:8082" {
+		t.Errorf("EmulatorEndpoint = %q", endpoint)
+	}
+
+	t.Setenv("REDB_TEST_BIGQUERY_EMULATOR", "bigquery:9050")
+	if cfg, _, _ := EmulatorConfig(dbcapabilities.BigQuery); cfg.ProjectID != BigQueryEmulatorProject {
+		t.Errorf("EmulatorConfig(bigquery).ProjectID = %q", cfg.ProjectID)
+	}
+
+	t.Setenv("REDB_TEST_AZURITE", "ftp://azurite")
+	if _, _, err := EmulatorConfig(dbcapabilities.AzureBlob); err == nil {
+		t.Error("EmulatorConfig should reject URLs that are not http or https")
+	}
+	if _, _, err := EmulatorConfig(dbcapabilities.PostgreSQL); err == nil {
+		t.Error("EmulatorConfig should reject databases without an emulator")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/redact"
 )
 
 // Standard adapter errors
@@ -50,6 +51,10 @@ var (
 
 	// ErrConfigurationError is returned when there's a configuration error
 	ErrConfigurationError = errors.New("configuration error")
+
+	// ErrRateLimited is returned when the rate limits of a database do not let an operation
+	// start before its deadline
+	ErrRateLimited = errors.New("rate limit of the database exceeded")
 )
 
 // DatabaseError wraps database-specific errors with additional context.
@@ -61,12 +66,13 @@ type DatabaseError struct {
 	Context      map[string]interface{}
 }
 
-// Error implements the error interface.
+// Error implements the error interface. Credentials in the message, e.g. in a connection
+// string quoted by the driver, are scrubbed.
 func (e *DatabaseError) Error() string {
 	if len(e.Context) > 0 {
-		return fmt.Sprintf("[%s] %s: %v (context: %v)", e.DatabaseType, e.Operation, e.Cause, e.Context)
+		return redact.String(fmt.Sprintf("[%s] %s: %v (context: %v)", e.DatabaseType, e.Operation, e.Cause, e.Context))
 	}
-	return fmt.Sprintf("[%s] %s: %v", e.DatabaseType, e.Operation, e.Cause)
+	return redact.String(fmt.Sprintf("[%s] %s: %v", e.DatabaseType, e.Operation, e.Cause))
 }
 
 // Unwrap returns the underlying error.
@@ -135,9 +141,9 @@ type ConnectionError struct {
 	Cause        error
 }
 
-// Error implements the error interface.
+// Error implements the error interface. Credentials in the message are scrubbed.
 func (e *ConnectionError) Error() string {
-	return fmt.Sprintf("failed to connect to %s at %s:%d: %v", e.DatabaseType, e.Host, e.Port, e.Cause)
+	return redact.String(fmt.Sprintf("failed to connect to %s at %s:%d: %v", e.DatabaseType, e.Host, e.Port, e.Cause))
 }
 
 // Unwrap returns the underlying error.
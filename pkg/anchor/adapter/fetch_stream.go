@@ -0,0 +1,91 @@
+package adapter
+
+import (
+	"context"
+	"io"
+)
+
+// DefaultFetchBatchSize is the batch size used by FetchStream when none is given.
+const DefaultFetchBatchSize = 1000
+
+// RowBatchIterator iterates over the rows of a table in batches, so that tables of any
+// size can be read with bounded memory. It is not safe for concurrent use.
+//
+//	it, err := conn.DataOperations().FetchStream(ctx, "events", 5000)
+//	if err != nil {
+//	    return err
+//	}
+//	defer it.Close()
+//	for {
+//	    rows, err := it.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(rows)
+//	}
+type RowBatchIterator interface {
+	// Next returns the next batch of at most the batch size rows, or io.EOF once
+	// all rows were returned.
+	Next() ([]map[string]interface{}, error)
+
+	// Close releases the resources held by the iterator, such as a server-side
+	// cursor. It is safe to call Close more than once.
+	Close() error
+}
+
+// NewOffsetBatchIterator returns an iterator that reads a table through the Stream
+// method of a data operator, one batch per call with an increasing offset. It is the
+// FetchStream implementation of databases without server-side cursors.
+func NewOffsetBatchIterator(ctx context.Context, ops DataOperator, table string, batchSize int) RowBatchIterator {
+	if batchSize <= 0 {
+		batchSize = DefaultFetchBatchSize
+	}
+	return &offsetBatchIterator{ctx: ctx, ops: ops, table: table, batchSize: batchSize}
+}
+
+type offsetBatchIterator struct {
+	ctx       context.Context
+	ops       DataOperator
+	table     string
+	batchSize int
+	offset    int64
+	done      bool
+}
+
+func (it *offsetBatchIterator) Next() ([]map[string]interface{}, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := it.ops.Stream(it.ctx, StreamParams{
+		Table:     it.table,
+		BatchSize: int32(it.batchSize),
+		Offset:    it.offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// HasMore is not reported consistently by all databases, so a short batch ends
+	// the iteration instead
+	if len(result.Data) < it.batchSize {
+		it.done = true
+	}
+	if len(result.Data) == 0 {
+		return nil, io.EOF
+	}
+
+	it.offset += int64(len(result.Data))
+	return result.Data, nil
+}
+
+func (it *offsetBatchIterator) Close() error {
+	it.done = true
+	return nil
+}
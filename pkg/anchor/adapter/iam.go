@@ -0,0 +1,198 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// IAM token providers
+const (
+	IAMProviderAWSRDS      = "aws-rds"      // AWS RDS and Aurora IAM database authentication
+	IAMProviderGCPCloudSQL = "gcp-cloudsql" // Cloud SQL IAM database authentication
+	IAMProviderAzureAD     = "azure-ad"     // Microsoft Entra ID (Azure AD) tokens for Azure SQL and the Azure flexible servers
+)
+
+// DefaultTokenRefreshWindow is how long before they expire IAM tokens are refreshed
+const DefaultTokenRefreshWindow = 5 * time.Minute
+
+// IAMAuth configures authentication with short-lived tokens of a cloud identity provider in
+// place of a password. The anchor authenticates to the provider with the credentials of its
+// environment: the default credential chain of AWS, the application default credentials of
+// GCP or the default Azure credential.
+type IAMAuth struct {
+	Provider string `json:"provider"`           // IAMProviderAWSRDS, IAMProviderGCPCloudSQL or IAMProviderAzureAD
+	Region   string `json:"region,omitempty"`   // AWS region of the database, taken from the RDS host name if empty
+	Scope    string `json:"scope,omitempty"`    // Scope of GCP and Azure tokens, the database scope of the provider if empty
+	ClientID string `json:"clientId,omitempty"` // Client ID of a user-assigned Azure managed identity
+}
+
+// TokenRequest describes the database connection a token is requested for.
+type TokenRequest struct {
+	ConnectionType string
+	Host           string
+	Port           int
+	Username       string
+}
+
+// AuthToken is a token authenticating a connection, used as its password until Expiry.
+type AuthToken struct {
+	Value  string
+	Expiry time.Time
+}
+
+// TokenSource returns authentication tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (AuthToken, error)
+}
+
+// TokenProvider creates the token source of a connection for an IAM provider.
+type TokenProvider func(ctx context.Context, auth IAMAuth, request TokenRequest) (TokenSource, error)
+
+var tokenProviders = struct {
+	sync.RWMutex
+	providers map[string]TokenProvider
+}{providers: make(map[string]TokenProvider)}
+
+// RegisterTokenProvider registers the token provider of an IAM provider, replacing any
+// provider registered under the same name.
+func RegisterTokenProvider(name string, provider TokenProvider) {
+	tokenProviders.Lock()
+	defer tokenProviders.Unlock()
+	tokenProviders.providers[name] = provider
+}
+
+// NewTokenSource returns the token source of a connection. Tokens are cached and refreshed
+// DefaultTokenRefreshWindow before they expire, or halfway through their lifetime if that is
+// shorter.
+func NewTokenSource(ctx context.Context, auth IAMAuth, request TokenRequest) (TokenSource, error) {
+	tokenProviders.RLock()
+	provider, ok := tokenProviders.providers[auth.Provider]
+	tokenProviders.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown IAM provider %q, known: %v", auth.Provider, registeredTokenProviders())
+	}
+
+	source, err := provider(ctx, auth, request)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", auth.Provider, err)
+	}
+	return newRefreshingTokenSource(source, DefaultTokenRefreshWindow), nil
+}
+
+// registeredTokenProviders returns the names of the registered token providers
+func registeredTokenProviders() []string {
+	tokenProviders.RLock()
+	defer tokenProviders.RUnlock()
+	names := make([]string, 0, len(tokenProviders.providers))
+	for name := range tokenProviders.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// refreshingTokenSource caches the tokens of a source and refreshes them before they expire
+type refreshingTokenSource struct {
+	source TokenSource
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	token     AuthToken
+	refreshAt time.Time
+}
+
+func newRefreshingTokenSource(source TokenSource, window time.Duration) *refreshingTokenSource {
+	return &refreshingTokenSource{source: source, window: window, now: time.Now}
+}
+
+// Token returns the cached token, or a new one once the cached token is due for refresh. If
+// the refresh fails, the cached token is returned for as long as it is valid.
+func (s *refreshingTokenSource) Token(ctx context.Context) (AuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token.Value != "" && now.Before(s.refreshAt) {
+		return s.token, nil
+	}
+
+	token, err := s.source.Token(ctx)
+	if err != nil {
+		if s.token.Value != "" && now.Before(s.token.Expiry) {
+			return s.token, nil
+		}
+		return AuthToken{}, fmt.Errorf("error getting IAM token: %w", err)
+	}
+
+	window := s.window
+	if lifetime := token.Expiry.Sub(now); lifetime < 2*window {
+		window = lifetime / 2
+	}
+	s.token = token
+	s.refreshAt = token.Expiry.Add(-window)
+	return token, nil
+}
+
+// AuthToken returns the IAM token to authenticate the connection with. Adapters supporting
+// IAM authentication call it for every new physical connection, in place of decrypting
+// Password, so that tokens are refreshed as connections are recycled.
+func (c ConnectionConfig) AuthToken(ctx context.Context) (string, error) {
+	return authToken(ctx, c.IAMAuth, c.Tokens, TokenRequest{ConnectionType: c.ConnectionType, Host: c.Host, Port: c.Port, Username: c.Username})
+}
+
+// AuthToken returns the IAM token to authenticate the instance connection with, see
+// ConnectionConfig.AuthToken.
+func (c InstanceConfig) AuthToken(ctx context.Context) (string, error) {
+	return authToken(ctx, c.IAMAuth, c.Tokens, TokenRequest{ConnectionType: c.ConnectionType, Host: c.Host, Port: c.Port, Username: c.Username})
+}
+
+// authToken returns a token of tokens, or of a new token source if it is nil
+func authToken(ctx context.Context, auth *IAMAuth, tokens TokenSource, request TokenRequest) (string, error) {
+	if auth == nil {
+		return "", fmt.Errorf("IAM authentication is not configured")
+	}
+	if tokens == nil {
+		var err error
+		if tokens, err = NewTokenSource(ctx, *auth, request); err != nil {
+			return "", err
+		}
+	}
+	token, err := tokens.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	return token.Value, nil
+}
+
+// withTokenSource sets the token source of a configuration with IAM authentication. Tokens
+// are signed for the host of the database, so this happens before an SSH tunnel replaces it.
+func (c *ConnectionConfig) withTokenSource(ctx context.Context) error {
+	if c.IAMAuth == nil || c.Tokens != nil {
+		return nil
+	}
+	tokens, err := NewTokenSource(ctx, *c.IAMAuth, TokenRequest{ConnectionType: c.ConnectionType, Host: c.Host, Port: c.Port, Username: c.Username})
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "iamAuth", err.Error())
+	}
+	c.Tokens = tokens
+	return nil
+}
+
+// withTokenSource sets the token source of an instance configuration with IAM authentication.
+func (c *InstanceConfig) withTokenSource(ctx context.Context) error {
+	if c.IAMAuth == nil || c.Tokens != nil {
+		return nil
+	}
+	tokens, err := NewTokenSource(ctx, *c.IAMAuth, TokenRequest{ConnectionType: c.ConnectionType, Host: c.Host, Port: c.Port, Username: c.Username})
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "iamAuth", err.Error())
+	}
+	c.Tokens = tokens
+	return nil
+}
@@ -0,0 +1,121 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingTokenSource issues numbered tokens valid for lifetime, or fails with err
+type countingTokenSource struct {
+	now      func() time.Time
+	lifetime time.Duration
+	issued   int
+	err      error
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (AuthToken, error) {
+	if s.err != nil {
+		return AuthToken{}, s.err
+	}
+	s.issued++
+	return AuthToken{Value: "token-" + strconv.Itoa(s.issued), Expiry: s.now().Add(s.lifetime)}, nil
+}
+
+func TestRefreshingTokenSourceRefreshesBeforeExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	source := &countingTokenSource{now: clock, lifetime: 15 * time.Minute}
+	tokens := newRefreshingTokenSource(source, DefaultTokenRefreshWindow)
+	tokens.now = clock
+
+	token := func() string {
+		t.Helper()
+		token, err := tokens.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token.Value
+	}
+
+	if got := token(); got != "token-1" {
+		t.Fatalf("first token %s", got)
+	}
+	now = now.Add(9 * time.Minute)
+	if got := token(); got != "token-1" {
+		t.Fatalf("token %s after 9 minutes, want the cached token", got)
+	}
+	now = now.Add(time.Minute)
+	if got := token(); got != "token-2" {
+		t.Fatalf("token %s 5 minutes before expiry, want a refreshed token", got)
+	}
+
+	// A failed refresh falls back to the cached token until it expires
+	now = now.Add(12 * time.Minute)
+	source.err = errors.New("metadata server unavailable")
+	if got := token(); got != "token-2" {
+		t.Fatalf("token %s after a failed refresh, want the cached token", got)
+	}
+	now = now.Add(4 * time.Minute)
+	if _, err := tokens.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "metadata server unavailable") {
+		t.Fatalf("expired token with failed refresh = %v, want the refresh error", err)
+	}
+}
+
+func TestRefreshingTokenSourceShortLivedTokens(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	source := &countingTokenSource{now: clock, lifetime: 4 * time.Minute}
+	tokens := newRefreshingTokenSource(source, DefaultTokenRefreshWindow)
+	tokens.now = clock
+
+	tokens.Token(context.Background())
+	now = now.Add(time.Minute)
+	if token, _ := tokens.Token(context.Background()); token.Value != "token-1" {
+		t.Fatalf("token %s after a minute, want tokens shorter than the window cached for half their lifetime", token.Value)
+	}
+	now = now.Add(time.Minute)
+	if token, _ := tokens.Token(context.Background()); token.Value != "token-2" {
+		t.Fatalf("token %s halfway through its lifetime, want a refreshed token", token.Value)
+	}
+}
+
+func TestDialConnectionSignsTokensForDatabaseHost(t *testing.T) {
+	var requests []TokenRequest
+	RegisterTokenProvider("test-iam", func(ctx context.Context, auth IAMAuth, request TokenRequest) (TokenSource, error) {
+		requests = append(requests, request)
+		return &countingTokenSource{now: time.Now, lifetime: time.Hour}, nil
+	})
+	defer func() {
+		tokenProviders.Lock()
+		delete(tokenProviders.providers, "test-iam")
+		tokenProviders.Unlock()
+	}()
+
+	config := ConnectionConfig{ConnectionType: "postgres", Host: "db.example.com", Port: 5432, Username: "redb", IAMAuth: &IAMAuth{Provider: "test-iam"}}
+	if err := config.withTokenSource(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// Connections through a tunnel reach the database at a local port
+	config.Host, config.Port = "127.0.0.1", 40000
+	token, err := config.AuthToken(context.Background())
+	if err != nil || token != "token-1" {
+		t.Fatalf("AuthToken = %s, %v", token, err)
+	}
+	if len(requests) != 1 || requests[0].Host != "db.example.com" || requests[0].Port != 5432 || requests[0].Username != "redb" {
+		t.Fatalf("token requests %+v, want one for the database host", requests)
+	}
+
+	replica := replicaConfig(config, ReplicaEndpoint{Host: "replica.example.com", Port: 5432})
+	if replica.Tokens != nil {
+		t.Fatal("replica shares the token source of the primary")
+	}
+
+	config = ConnectionConfig{ConnectionType: "postgres", IAMAuth: &IAMAuth{Provider: "unknown"}}
+	if err := config.withTokenSource(context.Background()); !IsConfigurationError(err) {
+		t.Fatalf("unknown IAM provider = %v, want a configuration error", err)
+	}
+}
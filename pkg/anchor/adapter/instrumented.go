@@ -0,0 +1,522 @@
+package adapter
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Instrument wraps a connection so that the latency and outcome of every
+// operation is recorded in rec under the connection's database type and ID.
+// Operation categories that are not supported stay nil.
+func Instrument(conn Connection, rec *MetricsRecorder) Connection {
+	if conn == nil || rec == nil {
+		return conn
+	}
+	if _, ok := conn.(*instrumentedConnection); ok {
+		return conn
+	}
+	return &instrumentedConnection{Connection: conn, rec: rec}
+}
+
+// InstrumentInstance wraps an instance connection like Instrument. Instance
+// operations are recorded under the instance ID.
+func InstrumentInstance(conn InstanceConnection, rec *MetricsRecorder) InstanceConnection {
+	if conn == nil || rec == nil {
+		return conn
+	}
+	if _, ok := conn.(*instrumentedInstanceConnection); ok {
+		return conn
+	}
+	return &instrumentedInstanceConnection{InstanceConnection: conn, rec: rec}
+}
+
+type instrumentedConnection struct {
+	Connection
+	rec *MetricsRecorder
+}
+
+func (c *instrumentedConnection) observe(operation string, start time.Time, err error) {
+	c.rec.Observe(c.Type(), c.ID(), operation, time.Since(start), err)
+}
+
+func (c *instrumentedConnection) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.Connection.Ping(ctx)
+	c.observe("ping", start, err)
+	return err
+}
+
+func (c *instrumentedConnection) SchemaOperations() SchemaOperator {
+	ops := c.Connection.SchemaOperations()
+	if ops == nil {
+		return nil
+	}
+	return &instrumentedSchemaOperator{ops: ops, observe: c.observe}
+}
+
+func (c *instrumentedConnection) DataOperations() DataOperator {
+	ops := c.Connection.DataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &instrumentedDataOperator{ops: ops, observe: c.observe, capturePlan: c.capturePlan}
+}
+
+func (c *instrumentedConnection) ReplicationOperations() ReplicationOperator {
+	ops := c.Connection.ReplicationOperations()
+	if ops == nil {
+		return nil
+	}
+	return &instrumentedReplicationOperator{ops: ops, observe: c.observe}
+}
+
+func (c *instrumentedConnection) MetadataOperations() MetadataOperator {
+	ops := c.Connection.MetadataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &instrumentedMetadataOperator{ops: ops, observe: c.observe}
+}
+
+type instrumentedSchemaOperator struct {
+	ops     SchemaOperator
+	observe func(operation string, start time.Time, err error)
+}
+
+func (s *instrumentedSchemaOperator) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	start := time.Now()
+	model, err := s.ops.DiscoverSchema(ctx)
+	s.observe("discover_schema", start, err)
+	return model, err
+}
+
+// DiscoverSchemaIncremental is only reached through DiscoverSchemaSince, which checks that
+// the wrapped operator discovers incrementally.
+func (s *instrumentedSchemaOperator) DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	start := time.Now()
+	model, err := s.ops.(IncrementalSchemaDiscoverer).DiscoverSchemaIncremental(ctx, previous)
+	s.observe("discover_schema_incremental", start, err)
+	return model, err
+}
+
+func (s *instrumentedSchemaOperator) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	start := time.Now()
+	err := s.ops.CreateStructure(ctx, model)
+	s.observe("create_structure", start, err)
+	return err
+}
+
+func (s *instrumentedSchemaOperator) ListTables(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	tables, err := s.ops.ListTables(ctx)
+	s.observe("list_tables", start, err)
+	return tables, err
+}
+
+func (s *instrumentedSchemaOperator) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	start := time.Now()
+	table, err := s.ops.GetTableSchema(ctx, tableName)
+	s.observe("get_table_schema", start, err)
+	return table, err
+}
+
+type instrumentedDataOperator struct {
+	ops         DataOperator
+	observe     func(operation string, start time.Time, err error)
+	capturePlan func(ops DataOperator, operation, table string, limit int, duration time.Duration)
+}
+
+func (d *instrumentedDataOperator) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	start := time.Now()
+	rows, err := d.ops.Fetch(ctx, table, limit)
+	d.observe("fetch", start, err)
+	if err == nil {
+		d.capturePlan(d.ops, "fetch", table, limit, time.Since(start))
+	}
+	return rows, err
+}
+
+func (d *instrumentedDataOperator) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	start := time.Now()
+	rows, err := d.ops.FetchWithColumns(ctx, table, columns, limit)
+	d.observe("fetch_with_columns", start, err)
+	if err == nil {
+		d.capturePlan(d.ops, "fetch_with_columns", table, limit, time.Since(start))
+	}
+	return rows, err
+}
+
+func (d *instrumentedDataOperator) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.Insert(ctx, table, data)
+	d.observe("insert", start, err)
+	return n, err
+}
+
+// BulkInsert uses the bulk load of the wrapped operator if it has one, so that
+// BulkInsert on an instrumented connection behaves like on the connection itself.
+func (d *instrumentedDataOperator) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	bulk, ok := d.ops.(BulkInserter)
+	if !ok {
+		return d.Insert(ctx, table, data)
+	}
+
+	start := time.Now()
+	n, err := bulk.BulkInsert(ctx, table, data)
+	d.observe("bulk_insert", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.Update(ctx, table, data, whereColumns)
+	d.observe("update", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.Upsert(ctx, table, data, uniqueColumns)
+	d.observe("upsert", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.Delete(ctx, table, conditions)
+	d.observe("delete", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) Stream(ctx context.Context, params StreamParams) (StreamResult, error) {
+	start := time.Now()
+	result, err := d.ops.Stream(ctx, params)
+	d.observe("stream", start, err)
+	return result, err
+}
+
+func (d *instrumentedDataOperator) FetchStream(ctx context.Context, table string, batchSize int) (RowBatchIterator, error) {
+	start := time.Now()
+	it, err := d.ops.FetchStream(ctx, table, batchSize)
+	d.observe("fetch_stream", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedBatchIterator{it: it, observe: d.observe}, nil
+}
+
+// KeyBounds and FetchKeyRange are only reached through AsKeyRangeReader, which checks
+// that the wrapped operator reads key ranges.
+func (d *instrumentedDataOperator) KeyBounds(ctx context.Context, table string, keyColumn string) (KeyRange, error) {
+	start := time.Now()
+	keyRange, err := d.ops.(KeyRangeReader).KeyBounds(ctx, table, keyColumn)
+	d.observe("key_bounds", start, err)
+	return keyRange, err
+}
+
+func (d *instrumentedDataOperator) FetchKeyRange(ctx context.Context, table string, keyRange KeyRange, batchSize int) (RowBatchIterator, error) {
+	start := time.Now()
+	it, err := d.ops.(KeyRangeReader).FetchKeyRange(ctx, table, keyRange, batchSize)
+	d.observe("fetch_key_range", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedBatchIterator{it: it, observe: d.observe}, nil
+}
+
+// CreateSandbox, InsertSandbox, FetchSandbox and DropSandbox are only reached through
+// AsSandboxer, which checks that the wrapped operator writes into sandboxes.
+func (d *instrumentedDataOperator) CreateSandbox(ctx context.Context, sandbox string, tables []string) error {
+	start := time.Now()
+	err := d.ops.(Sandboxer).CreateSandbox(ctx, sandbox, tables)
+	d.observe("create_sandbox", start, err)
+	return err
+}
+
+func (d *instrumentedDataOperator) InsertSandbox(ctx context.Context, sandbox string, table string, data []map[string]interface{}) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.(Sandboxer).InsertSandbox(ctx, sandbox, table, data)
+	d.observe("insert_sandbox", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) FetchSandbox(ctx context.Context, sandbox string, table string, limit int) ([]map[string]interface{}, error) {
+	start := time.Now()
+	rows, err := d.ops.(Sandboxer).FetchSandbox(ctx, sandbox, table, limit)
+	d.observe("fetch_sandbox", start, err)
+	return rows, err
+}
+
+func (d *instrumentedDataOperator) DropSandbox(ctx context.Context, sandbox string) error {
+	start := time.Now()
+	err := d.ops.(Sandboxer).DropSandbox(ctx, sandbox)
+	d.observe("drop_sandbox", start, err)
+	return err
+}
+
+// ExecuteReadOnlyQuery is only reached through asReadOnlyQueryExecutor, which checks that
+// the wrapped operator runs read-only transactions.
+func (d *instrumentedDataOperator) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*QueryResult, error) {
+	start := time.Now()
+	result, err := d.ops.(ReadOnlyQueryExecutor).ExecuteReadOnlyQuery(ctx, query, maxRows)
+	d.observe("execute_read_only_query", start, err)
+	return result, err
+}
+
+// SearchVectors is only reached through AsVectorSearcher, which checks that the wrapped
+// operator searches vectors.
+func (d *instrumentedDataOperator) SearchVectors(ctx context.Context, table string, vector []float32, topK int) ([]VectorMatch, error) {
+	start := time.Now()
+	matches, err := d.ops.(VectorSearcher).SearchVectors(ctx, table, vector, topK)
+	d.observe("search_vectors", start, err)
+	return matches, err
+}
+
+// SampleEdges is only reached through AsEdgeSampler, which checks that the wrapped operator
+// samples edges.
+func (d *instrumentedDataOperator) SampleEdges(ctx context.Context, relationship string, limit int) ([]Edge, error) {
+	start := time.Now()
+	edges, err := d.ops.(EdgeSampler).SampleEdges(ctx, relationship, limit)
+	d.observe("sample_edges", start, err)
+	return edges, err
+}
+
+// instrumentedBatchIterator records the latency and outcome of every batch.
+type instrumentedBatchIterator struct {
+	it      RowBatchIterator
+	observe func(operation string, start time.Time, err error)
+}
+
+func (i *instrumentedBatchIterator) Next() ([]map[string]interface{}, error) {
+	start := time.Now()
+	rows, err := i.it.Next()
+	if err == io.EOF {
+		return rows, err
+	}
+	i.observe("fetch_stream_batch", start, err)
+	return rows, err
+}
+
+func (i *instrumentedBatchIterator) Close() error {
+	return i.it.Close()
+}
+
+func (d *instrumentedDataOperator) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	start := time.Now()
+	rows, err := d.ops.ExecuteQuery(ctx, query, args...)
+	d.observe("execute_query", start, err)
+	return rows, err
+}
+
+func (d *instrumentedDataOperator) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	start := time.Now()
+	n, err := d.ops.ExecuteCountQuery(ctx, query)
+	d.observe("execute_count_query", start, err)
+	return n, err
+}
+
+func (d *instrumentedDataOperator) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	start := time.Now()
+	n, exact, err := d.ops.GetRowCount(ctx, table, whereClause)
+	d.observe("get_row_count", start, err)
+	return n, exact, err
+}
+
+func (d *instrumentedDataOperator) Wipe(ctx context.Context) error {
+	start := time.Now()
+	err := d.ops.Wipe(ctx)
+	d.observe("wipe", start, err)
+	return err
+}
+
+type instrumentedReplicationOperator struct {
+	ops     ReplicationOperator
+	observe func(operation string, start time.Time, err error)
+}
+
+func (r *instrumentedReplicationOperator) IsSupported() bool {
+	return r.ops.IsSupported()
+}
+
+func (r *instrumentedReplicationOperator) GetSupportedMechanisms() []string {
+	return r.ops.GetSupportedMechanisms()
+}
+
+func (r *instrumentedReplicationOperator) CheckPrerequisites(ctx context.Context) error {
+	start := time.Now()
+	err := r.ops.CheckPrerequisites(ctx)
+	r.observe("check_prerequisites", start, err)
+	return err
+}
+
+func (r *instrumentedReplicationOperator) Connect(ctx context.Context, config ReplicationConfig) (ReplicationSource, error) {
+	start := time.Now()
+	source, err := r.ops.Connect(ctx, config)
+	r.observe("replication_connect", start, err)
+	return source, err
+}
+
+func (r *instrumentedReplicationOperator) GetStatus(ctx context.Context) (map[string]interface{}, error) {
+	start := time.Now()
+	status, err := r.ops.GetStatus(ctx)
+	r.observe("get_replication_status", start, err)
+	return status, err
+}
+
+func (r *instrumentedReplicationOperator) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	start := time.Now()
+	lag, err := r.ops.GetLag(ctx)
+	r.observe("get_replication_lag", start, err)
+	return lag, err
+}
+
+func (r *instrumentedReplicationOperator) ListSlots(ctx context.Context) ([]map[string]interface{}, error) {
+	start := time.Now()
+	slots, err := r.ops.ListSlots(ctx)
+	r.observe("list_slots", start, err)
+	return slots, err
+}
+
+func (r *instrumentedReplicationOperator) DropSlot(ctx context.Context, slotName string) error {
+	start := time.Now()
+	err := r.ops.DropSlot(ctx, slotName)
+	r.observe("drop_slot", start, err)
+	return err
+}
+
+func (r *instrumentedReplicationOperator) ListPublications(ctx context.Context) ([]map[string]interface{}, error) {
+	start := time.Now()
+	publications, err := r.ops.ListPublications(ctx)
+	r.observe("list_publications", start, err)
+	return publications, err
+}
+
+func (r *instrumentedReplicationOperator) DropPublication(ctx context.Context, publicationName string) error {
+	start := time.Now()
+	err := r.ops.DropPublication(ctx, publicationName)
+	r.observe("drop_publication", start, err)
+	return err
+}
+
+func (r *instrumentedReplicationOperator) ParseEvent(ctx context.Context, rawEvent map[string]interface{}) (*CDCEvent, error) {
+	start := time.Now()
+	event, err := r.ops.ParseEvent(ctx, rawEvent)
+	r.observe("parse_cdc_event", start, err)
+	return event, err
+}
+
+func (r *instrumentedReplicationOperator) ApplyCDCEvent(ctx context.Context, event *CDCEvent) error {
+	start := time.Now()
+	err := r.ops.ApplyCDCEvent(ctx, event)
+	r.observe("apply_cdc_event", start, err)
+	return err
+}
+
+func (r *instrumentedReplicationOperator) TransformData(ctx context.Context, data map[string]interface{}, rules []TransformationRule, transformationServiceEndpoint string) (map[string]interface{}, error) {
+	start := time.Now()
+	transformed, err := r.ops.TransformData(ctx, data, rules, transformationServiceEndpoint)
+	r.observe("transform_data", start, err)
+	return transformed, err
+}
+
+type instrumentedMetadataOperator struct {
+	ops     MetadataOperator
+	observe func(operation string, start time.Time, err error)
+}
+
+func (m *instrumentedMetadataOperator) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	start := time.Now()
+	metadata, err := m.ops.CollectDatabaseMetadata(ctx)
+	m.observe("collect_database_metadata", start, err)
+	return metadata, err
+}
+
+func (m *instrumentedMetadataOperator) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	start := time.Now()
+	metadata, err := m.ops.CollectInstanceMetadata(ctx)
+	m.observe("collect_instance_metadata", start, err)
+	return metadata, err
+}
+
+func (m *instrumentedMetadataOperator) GetVersion(ctx context.Context) (string, error) {
+	start := time.Now()
+	version, err := m.ops.GetVersion(ctx)
+	m.observe("get_version", start, err)
+	return version, err
+}
+
+func (m *instrumentedMetadataOperator) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	start := time.Now()
+	id, err := m.ops.GetUniqueIdentifier(ctx)
+	m.observe("get_unique_identifier", start, err)
+	return id, err
+}
+
+func (m *instrumentedMetadataOperator) GetDatabaseSize(ctx context.Context) (int64, error) {
+	start := time.Now()
+	size, err := m.ops.GetDatabaseSize(ctx)
+	m.observe("get_database_size", start, err)
+	return size, err
+}
+
+func (m *instrumentedMetadataOperator) GetTableCount(ctx context.Context) (int, error) {
+	start := time.Now()
+	count, err := m.ops.GetTableCount(ctx)
+	m.observe("get_table_count", start, err)
+	return count, err
+}
+
+func (m *instrumentedMetadataOperator) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	start := time.Now()
+	output, err := m.ops.ExecuteCommand(ctx, command)
+	m.observe("execute_command", start, err)
+	return output, err
+}
+
+type instrumentedInstanceConnection struct {
+	InstanceConnection
+	rec *MetricsRecorder
+}
+
+func (c *instrumentedInstanceConnection) observe(operation string, start time.Time, err error) {
+	c.rec.Observe(c.Type(), c.ID(), operation, time.Since(start), err)
+}
+
+func (c *instrumentedInstanceConnection) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.InstanceConnection.Ping(ctx)
+	c.observe("ping", start, err)
+	return err
+}
+
+func (c *instrumentedInstanceConnection) ListDatabases(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	databases, err := c.InstanceConnection.ListDatabases(ctx)
+	c.observe("list_databases", start, err)
+	return databases, err
+}
+
+func (c *instrumentedInstanceConnection) CreateDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	start := time.Now()
+	err := c.InstanceConnection.CreateDatabase(ctx, name, options)
+	c.observe("create_database", start, err)
+	return err
+}
+
+func (c *instrumentedInstanceConnection) DropDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	start := time.Now()
+	err := c.InstanceConnection.DropDatabase(ctx, name, options)
+	c.observe("drop_database", start, err)
+	return err
+}
+
+func (c *instrumentedInstanceConnection) MetadataOperations() MetadataOperator {
+	ops := c.InstanceConnection.MetadataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &instrumentedMetadataOperator{ops: ops, observe: c.observe}
+}
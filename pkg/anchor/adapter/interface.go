@@ -113,6 +113,7 @@ type DataOperator interface {
 
 	// Streaming for large datasets
 	Stream(ctx context.Context, params StreamParams) (StreamResult, error)
+	FetchStream(ctx context.Context, table string, batchSize int) (RowBatchIterator, error)
 
 	// Query execution (for databases supporting query languages)
 	ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error)
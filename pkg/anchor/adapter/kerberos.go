@@ -0,0 +1,130 @@
+package adapter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DefaultKrb5Conf is the Kerberos configuration read when KerberosAuth does not name one
+const DefaultKrb5Conf = "/etc/krb5.conf"
+
+// KerberosAuth configures Kerberos (GSSAPI) authentication in place of a password, for
+// enterprise databases that only accept domain accounts. The anchor obtains tickets with a
+// keytab, or reads them from a credential cache that kinit or k5start keeps renewed. Both are
+// files on the anchor node.
+type KerberosAuth struct {
+	Principal string `json:"principal,omitempty"` // Client principal, user or user@REALM; Username if empty
+	Realm     string `json:"realm,omitempty"`     // Realm of the principal, the default realm of Krb5Conf if empty
+	Keytab    string `json:"keytab,omitempty"`    // Keytab of the principal
+	CCache    string `json:"ccache,omitempty"`    // Credential cache holding a ticket-granting ticket of the principal
+	Krb5Conf  string `json:"krb5Conf,omitempty"`  // Kerberos configuration, DefaultKrb5Conf if empty
+
+	// Service principal of the database server; the default service of the database type
+	// (see KerberosService) on the host of the configuration if empty
+	SPN string `json:"spn,omitempty"`
+}
+
+// kerberosServices are the service names database servers register their principal under
+var kerberosServices = map[dbcapabilities.DatabaseType]string{
+	dbcapabilities.PostgreSQL: "postgres",
+	dbcapabilities.SQLServer:  "MSSQLSvc",
+	dbcapabilities.Oracle:     "oracle",
+}
+
+// KerberosService returns the service name of the principal of a database server, or "" if
+// its adapter does not support Kerberos authentication.
+func KerberosService(dbType dbcapabilities.DatabaseType) string {
+	return kerberosServices[dbType]
+}
+
+// KerberosSPN returns the service principal name of a database server: postgres/host,
+// MSSQLSvc/host:port or oracle/host.
+func KerberosSPN(dbType dbcapabilities.DatabaseType, host string, port int) string {
+	service := KerberosService(dbType)
+	if dbType == dbcapabilities.SQLServer {
+		return service + "/" + host + ":" + strconv.Itoa(port)
+	}
+	return service + "/" + host
+}
+
+// Validate checks that the authentication has exactly one source of tickets.
+func (a KerberosAuth) Validate() error {
+	switch {
+	case a.Keytab == "" && a.CCache == "":
+		return fmt.Errorf("a keytab or a credential cache is required")
+	case a.Keytab != "" && a.CCache != "":
+		return fmt.Errorf("a keytab and a credential cache cannot both be set")
+	}
+	return nil
+}
+
+// ClientPrincipal returns the user and realm of the client principal, taken from Principal,
+// or from username if it is empty. The realm is empty when neither names one.
+func (a KerberosAuth) ClientPrincipal(username string) (user, realm string) {
+	principal := a.Principal
+	if principal == "" {
+		principal = username
+	}
+	user, realm, _ = strings.Cut(principal, "@")
+	if a.Realm != "" {
+		realm = a.Realm
+	}
+	return user, realm
+}
+
+// Krb5ConfPath returns the path of the Kerberos configuration.
+func (a KerberosAuth) Krb5ConfPath() string {
+	if a.Krb5Conf == "" {
+		return DefaultKrb5Conf
+	}
+	return a.Krb5Conf
+}
+
+// withKerberosSPN checks the Kerberos authentication of a configuration and sets its service
+// principal. It names the host of the database, so this happens before an SSH tunnel
+// replaces it.
+func (c *ConnectionConfig) withKerberosSPN() error {
+	auth, err := resolveKerberosAuth(c.KerberosAuth, c.ConnectionType, c.Host, c.Port, c.IAMAuth != nil)
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "kerberosAuth", err.Error())
+	}
+	c.KerberosAuth = auth
+	return nil
+}
+
+// withKerberosSPN checks the Kerberos authentication of an instance configuration and sets
+// its service principal.
+func (c *InstanceConfig) withKerberosSPN() error {
+	auth, err := resolveKerberosAuth(c.KerberosAuth, c.ConnectionType, c.Host, c.Port, c.IAMAuth != nil)
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "kerberosAuth", err.Error())
+	}
+	c.KerberosAuth = auth
+	return nil
+}
+
+// resolveKerberosAuth returns a copy of auth with its service principal set, so the
+// configuration it was shared with keeps its own
+func resolveKerberosAuth(auth *KerberosAuth, connectionType, host string, port int, iam bool) (*KerberosAuth, error) {
+	if auth == nil {
+		return nil, nil
+	}
+	if iam {
+		return nil, fmt.Errorf("kerberos and iam authentication cannot both be set")
+	}
+	dbType, _ := dbcapabilities.ParseID(connectionType)
+	if KerberosService(dbType) == "" {
+		return nil, fmt.Errorf("%s databases do not support kerberos authentication", connectionType)
+	}
+	if err := auth.Validate(); err != nil {
+		return nil, err
+	}
+	resolved := *auth
+	if resolved.SPN == "" {
+		resolved.SPN = KerberosSPN(dbType, host, port)
+	}
+	return &resolved, nil
+}
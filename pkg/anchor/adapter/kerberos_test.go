@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func TestKerberosSPN(t *testing.T) {
+	tests := []struct {
+		dbType dbcapabilities.DatabaseType
+		want   string
+	}{
+		{dbcapabilities.PostgreSQL, "postgres/db.example.com"},
+		{dbcapabilities.SQLServer, "MSSQLSvc/db.example.com:1433"},
+		{dbcapabilities.Oracle, "oracle/db.example.com"},
+	}
+	for _, tt := range tests {
+		if got := KerberosSPN(tt.dbType, "db.example.com", 1433); got != tt.want {
+			t.Errorf("KerberosSPN(%s) = %q, want %q", tt.dbType, got, tt.want)
+		}
+	}
+}
+
+func TestKerberosClientPrincipal(t *testing.T) {
+	user, realm := KerberosAuth{}.ClientPrincipal("redb@EXAMPLE.COM")
+	if user != "redb" || realm != "EXAMPLE.COM" {
+		t.Errorf("ClientPrincipal = %q, %q", user, realm)
+	}
+	user, realm = KerberosAuth{Principal: "svc", Realm: "CORP.EXAMPLE.COM"}.ClientPrincipal("redb")
+	if user != "svc" || realm != "CORP.EXAMPLE.COM" {
+		t.Errorf("ClientPrincipal = %q, %q", user, realm)
+	}
+}
+
+func TestResolveKerberosAuth(t *testing.T) {
+	auth := &KerberosAuth{Keytab: "/etc/redb/redb.keytab"}
+	config := ConnectionConfig{ConnectionType: "postgres", Host: "db.example.com", Port: 5432, KerberosAuth: auth}
+	if err := config.withKerberosSPN(); err != nil || config.KerberosAuth.SPN != "postgres/db.example.com" {
+		t.Errorf("withKerberosSPN = %+v, %v", config.KerberosAuth, err)
+	}
+	if auth.SPN != "" {
+		t.Error("withKerberosSPN should not change the shared authentication")
+	}
+
+	tests := []struct {
+		name   string
+		config ConnectionConfig
+	}{
+		{"no tickets", ConnectionConfig{ConnectionType: "postgres", KerberosAuth: &KerberosAuth{}}},
+		{"keytab and cache", ConnectionConfig{ConnectionType: "postgres", KerberosAuth: &KerberosAuth{Keytab: "a", CCache: "b"}}},
+		{"unsupported database", ConnectionConfig{ConnectionType: "mysql", KerberosAuth: &KerberosAuth{Keytab: "a"}}},
+		{"iam", ConnectionConfig{ConnectionType: "postgres", KerberosAuth: &KerberosAuth{Keytab: "a"}, IAMAuth: &IAMAuth{Provider: IAMProviderAWSRDS}}},
+	}
+	for _, tt := range tests {
+		if err := tt.config.withKerberosSPN(); err == nil {
+			t.Errorf("%s: withKerberosSPN should fail", tt.name)
+		}
+	}
+}
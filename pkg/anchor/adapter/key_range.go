@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+)
+
+// KeyRange is a half-open range [Lower, Upper) of the values of an integer key column.
+type KeyRange struct {
+	Column string
+	Lower  int64
+	Upper  int64
+}
+
+// IsEmpty reports whether the range holds no values
+func (r KeyRange) IsEmpty() bool {
+	return r.Upper <= r.Lower
+}
+
+func (r KeyRange) String() string {
+	return fmt.Sprintf("%s in [%d, %d)", r.Column, r.Lower, r.Upper)
+}
+
+// KeyRangeReader is implemented by data operators that can read a table by ranges of an
+// integer key column. Readers of separate ranges of a table can run concurrently, which
+// lets the initial sync copy a large table in parallel.
+type KeyRangeReader interface {
+	// KeyBounds returns the range holding all values of the key column of a table. The
+	// range is empty if the table has no rows.
+	KeyBounds(ctx context.Context, table string, keyColumn string) (KeyRange, error)
+
+	// FetchKeyRange returns the rows of a table whose key is in a range, in batches.
+	FetchKeyRange(ctx context.Context, table string, keyRange KeyRange, batchSize int) (RowBatchIterator, error)
+}
+
+// AsKeyRangeReader returns the key range reader of a data operator, if it has one.
+func AsKeyRangeReader(ops DataOperator) (KeyRangeReader, bool) {
+	// The instrumented, routed and throttled operators always have the methods; they read
+	// ranges only if the operator they wrap does
+	if instrumented, ok := ops.(*instrumentedDataOperator); ok {
+		if _, ok := AsKeyRangeReader(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledDataOperator); ok {
+		if _, ok := AsKeyRangeReader(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedDataOperator); ok {
+		if _, ok := AsKeyRangeReader(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	reader, ok := ops.(KeyRangeReader)
+	return reader, ok
+}
+
+// SplitKeyRange splits a range into at most parts ranges of about the same size. Ranges
+// smaller than parts values are split into ranges of one value.
+func SplitKeyRange(keyRange KeyRange, parts int) []KeyRange {
+	if keyRange.IsEmpty() {
+		return nil
+	}
+	if parts < 1 {
+		parts = 1
+	}
+
+	// Compute the size in uint64 so that ranges spanning most of int64 do not overflow
+	size := uint64(keyRange.Upper - keyRange.Lower)
+	if uint64(parts) > size {
+		parts = int(size)
+	}
+	step := size / uint64(parts)
+
+	ranges := make([]KeyRange, 0, parts)
+	lower := keyRange.Lower
+	for i := 0; i < parts; i++ {
+		upper := lower + int64(step)
+		if i == parts-1 {
+			upper = keyRange.Upper
+		}
+		ranges = append(ranges, KeyRange{Column: keyRange.Column, Lower: lower, Upper: upper})
+		lower = upper
+	}
+	return ranges
+}
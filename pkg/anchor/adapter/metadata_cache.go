@@ -0,0 +1,244 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// CacheMetadata wraps a connection so that the metadata of its database (the collected
+// metadata, version, size and table count) is read from the database at most once per ttl.
+// Monitoring and the UI ask for it far more often than it changes, and on a production source
+// every read is a round of catalog queries. Creating structure through the connection clears
+// the cache, and so should InvalidateMetadata once discovery finds that the schema changed.
+// Connections are returned as they are when ttl is not positive.
+func CacheMetadata(conn Connection, ttl time.Duration) Connection {
+	if conn == nil || ttl <= 0 {
+		return conn
+	}
+	if _, ok := conn.(*cachedConnection); ok {
+		return conn
+	}
+	return &cachedConnection{Connection: conn, cache: newMetadataCache(ttl)}
+}
+
+// CacheInstanceMetadata wraps an instance connection like CacheMetadata. Creating or dropping
+// a database through the connection clears the cache.
+func CacheInstanceMetadata(conn InstanceConnection, ttl time.Duration) InstanceConnection {
+	if conn == nil || ttl <= 0 {
+		return conn
+	}
+	if _, ok := conn.(*cachedInstanceConnection); ok {
+		return conn
+	}
+	return &cachedInstanceConnection{InstanceConnection: conn, cache: newMetadataCache(ttl)}
+}
+
+// InvalidateMetadata clears the metadata cached for a connection or instance connection
+// wrapped by CacheMetadata or CacheInstanceMetadata, so that the next reads query the
+// database. It reports whether the connection caches metadata.
+func InvalidateMetadata(conn interface{}) bool {
+	switch c := conn.(type) {
+	case *cachedConnection:
+		c.cache.invalidate()
+		return true
+	case *cachedInstanceConnection:
+		c.cache.invalidate()
+		return true
+	}
+	return false
+}
+
+// metadataCache holds the metadata read through a connection, by operation. Only successful
+// reads are cached.
+type metadataCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu         sync.Mutex
+	generation uint64 // incremented by invalidate
+	entries    map[string]cachedMetadata
+}
+
+type cachedMetadata struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	return &metadataCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedMetadata),
+	}
+}
+
+func (c *metadataCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[string]cachedMetadata)
+}
+
+// cached returns the value cached under key, or reads and caches it when missing or expired.
+func cached[T any](c *metadataCache, key string, read func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.now().Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.value.(T), nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := read()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	// A value read while the cache was invalidated may predate the change that invalidated it
+	if c.generation == generation {
+		c.entries[key] = cachedMetadata{value: value, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// copyMetadata returns a copy of collected metadata, so that callers adding to the map they
+// receive do not change the cached one.
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return copied
+}
+
+type cachedConnection struct {
+	Connection
+	cache *metadataCache
+}
+
+func (c *cachedConnection) SchemaOperations() SchemaOperator {
+	ops := c.Connection.SchemaOperations()
+	if ops == nil {
+		return nil
+	}
+	return &cachedSchemaOperator{ops: ops, cache: c.cache}
+}
+
+func (c *cachedConnection) MetadataOperations() MetadataOperator {
+	ops := c.Connection.MetadataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &cachedMetadataOperator{ops: ops, cache: c.cache}
+}
+
+// cachedSchemaOperator clears the metadata cache of its connection when structure is created,
+// whether or not that succeeded. Discovery leaves the cache alone, as it runs far more often
+// than the schema changes.
+type cachedSchemaOperator struct {
+	ops   SchemaOperator
+	cache *metadataCache
+}
+
+func (s *cachedSchemaOperator) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	return s.ops.DiscoverSchema(ctx)
+}
+
+// DiscoverSchemaIncremental is only reached through DiscoverSchemaSince, which checks that
+// the wrapped operator discovers incrementally.
+func (s *cachedSchemaOperator) DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	return s.ops.(IncrementalSchemaDiscoverer).DiscoverSchemaIncremental(ctx, previous)
+}
+
+func (s *cachedSchemaOperator) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	defer s.cache.invalidate()
+	return s.ops.CreateStructure(ctx, model)
+}
+
+func (s *cachedSchemaOperator) ListTables(ctx context.Context) ([]string, error) {
+	return s.ops.ListTables(ctx)
+}
+
+func (s *cachedSchemaOperator) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	return s.ops.GetTableSchema(ctx, tableName)
+}
+
+type cachedMetadataOperator struct {
+	ops   MetadataOperator
+	cache *metadataCache
+}
+
+func (m *cachedMetadataOperator) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	metadata, err := cached(m.cache, "database_metadata", func() (map[string]interface{}, error) {
+		return m.ops.CollectDatabaseMetadata(ctx)
+	})
+	return copyMetadata(metadata), err
+}
+
+func (m *cachedMetadataOperator) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	metadata, err := cached(m.cache, "instance_metadata", func() (map[string]interface{}, error) {
+		return m.ops.CollectInstanceMetadata(ctx)
+	})
+	return copyMetadata(metadata), err
+}
+
+func (m *cachedMetadataOperator) GetVersion(ctx context.Context) (string, error) {
+	return cached(m.cache, "version", func() (string, error) {
+		return m.ops.GetVersion(ctx)
+	})
+}
+
+func (m *cachedMetadataOperator) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return cached(m.cache, "unique_identifier", func() (string, error) {
+		return m.ops.GetUniqueIdentifier(ctx)
+	})
+}
+
+func (m *cachedMetadataOperator) GetDatabaseSize(ctx context.Context) (int64, error) {
+	return cached(m.cache, "size", func() (int64, error) {
+		return m.ops.GetDatabaseSize(ctx)
+	})
+}
+
+func (m *cachedMetadataOperator) GetTableCount(ctx context.Context) (int, error) {
+	return cached(m.cache, "table_count", func() (int, error) {
+		return m.ops.GetTableCount(ctx)
+	})
+}
+
+// ExecuteCommand is not cached, and clears the cache as the command may change the database.
+func (m *cachedMetadataOperator) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	defer m.cache.invalidate()
+	return m.ops.ExecuteCommand(ctx, command)
+}
+
+type cachedInstanceConnection struct {
+	InstanceConnection
+	cache *metadataCache
+}
+
+func (c *cachedInstanceConnection) CreateDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	defer c.cache.invalidate()
+	return c.InstanceConnection.CreateDatabase(ctx, name, options)
+}
+
+func (c *cachedInstanceConnection) DropDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	defer c.cache.invalidate()
+	return c.InstanceConnection.DropDatabase(ctx, name, options)
+}
+
+func (c *cachedInstanceConnection) MetadataOperations() MetadataOperator {
+	ops := c.InstanceConnection.MetadataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &cachedMetadataOperator{ops: ops, cache: c.cache}
+}
@@ -0,0 +1,137 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// cacheTestConnection is a connection with only schema and metadata operations
+type cacheTestConnection struct {
+	Connection
+	schema   *cacheTestSchema
+	metadata *cacheTestMetadata
+}
+
+func (c *cacheTestConnection) SchemaOperations() SchemaOperator     { return c.schema }
+func (c *cacheTestConnection) MetadataOperations() MetadataOperator { return c.metadata }
+
+type cacheTestSchema struct {
+	SchemaOperator
+}
+
+func (s *cacheTestSchema) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	return nil
+}
+
+// cacheTestMetadata counts the reads of the database size, and fails them while err is set
+type cacheTestMetadata struct {
+	MetadataOperator
+	reads int
+	err   error
+}
+
+func (m *cacheTestMetadata) GetDatabaseSize(ctx context.Context) (int64, error) {
+	m.reads++
+	if m.err != nil {
+		return 0, m.err
+	}
+	return int64(m.reads) * 1024, nil
+}
+
+func (m *cacheTestMetadata) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	m.reads++
+	return map[string]interface{}{"version": "16.2"}, nil
+}
+
+func newCacheTestConnection(ttl time.Duration) (Connection, *cacheTestMetadata, *time.Time) {
+	metadata := &cacheTestMetadata{}
+	conn := CacheMetadata(&cacheTestConnection{schema: &cacheTestSchema{}, metadata: metadata}, ttl)
+	now := time.Now()
+	conn.(*cachedConnection).cache.now = func() time.Time { return now }
+	return conn, metadata, &now
+}
+
+func TestCacheMetadataWithoutTTLReturnsConnection(t *testing.T) {
+	conn := &cacheTestConnection{metadata: &cacheTestMetadata{}}
+	if CacheMetadata(conn, 0) != Connection(conn) {
+		t.Fatal("connection without a TTL was wrapped")
+	}
+	if InvalidateMetadata(conn) {
+		t.Fatal("InvalidateMetadata reported a cache on an unwrapped connection")
+	}
+}
+
+func TestCacheMetadataExpires(t *testing.T) {
+	ctx := context.Background()
+	conn, metadata, now := newCacheTestConnection(time.Minute)
+
+	first, _ := conn.MetadataOperations().GetDatabaseSize(ctx)
+	second, _ := conn.MetadataOperations().GetDatabaseSize(ctx)
+	if first != second || metadata.reads != 1 {
+		t.Fatalf("sizes %d and %d after %d reads, want the first read cached", first, second, metadata.reads)
+	}
+
+	*now = now.Add(time.Minute)
+	if size, _ := conn.MetadataOperations().GetDatabaseSize(ctx); size == first || metadata.reads != 2 {
+		t.Fatalf("size %d after %d reads, want the expired size read again", size, metadata.reads)
+	}
+}
+
+func TestCacheMetadataDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	conn, metadata, _ := newCacheTestConnection(time.Minute)
+
+	metadata.err = errors.New("connection reset")
+	if _, err := conn.MetadataOperations().GetDatabaseSize(ctx); err == nil {
+		t.Fatal("error of the database was not returned")
+	}
+	metadata.err = nil
+	if _, err := conn.MetadataOperations().GetDatabaseSize(ctx); err != nil || metadata.reads != 2 {
+		t.Fatalf("err = %v after %d reads, want the failed read retried", err, metadata.reads)
+	}
+}
+
+func TestCacheMetadataInvalidation(t *testing.T) {
+	ctx := context.Background()
+	conn, metadata, _ := newCacheTestConnection(time.Minute)
+
+	conn.MetadataOperations().GetDatabaseSize(ctx)
+	if err := conn.SchemaOperations().CreateStructure(ctx, &unifiedmodel.UnifiedModel{}); err != nil {
+		t.Fatal(err)
+	}
+	conn.MetadataOperations().GetDatabaseSize(ctx)
+	if metadata.reads != 2 {
+		t.Fatalf("%d reads, want the size read again after creating structure", metadata.reads)
+	}
+
+	if !InvalidateMetadata(conn) {
+		t.Fatal("InvalidateMetadata did not find the cache")
+	}
+	conn.MetadataOperations().GetDatabaseSize(ctx)
+	if metadata.reads != 3 {
+		t.Fatalf("%d reads, want the size read again after InvalidateMetadata", metadata.reads)
+	}
+}
+
+func TestCacheMetadataReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	conn, _, _ := newCacheTestConnection(time.Minute)
+
+	collected, _ := conn.MetadataOperations().CollectDatabaseMetadata(ctx)
+	collected["version"] = "changed"
+	again, _ := conn.MetadataOperations().CollectDatabaseMetadata(ctx)
+	if again["version"] != "16.2" {
+		t.Fatalf("version = %v after changing a returned map", again["version"])
+	}
+}
+
+func TestCachedSchemaOperatorKeepsIncrementalDiscovery(t *testing.T) {
+	conn, _, _ := newCacheTestConnection(time.Minute)
+	if _, ok := asIncrementalSchemaDiscoverer(conn.SchemaOperations()); ok {
+		t.Fatal("cached operator discovers incrementally although the wrapped one does not")
+	}
+}
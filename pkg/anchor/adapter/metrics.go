@@ -0,0 +1,227 @@
+package adapter
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// latencyBuckets are the upper bounds of the operation latency histogram.
+var latencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// LatencyBuckets returns the upper bounds of the buckets of OperationMetrics.Buckets.
+func LatencyBuckets() []time.Duration {
+	return append([]time.Duration(nil), latencyBuckets...)
+}
+
+// OperationMetrics aggregates the calls of one operation on one database.
+type OperationMetrics struct {
+	DatabaseType dbcapabilities.DatabaseType
+	DatabaseID   string
+	Operation    string
+
+	Count         int64
+	Errors        int64
+	TotalDuration time.Duration
+	MinDuration   time.Duration
+	MaxDuration   time.Duration
+	LastDuration  time.Duration
+	LastCalledAt  time.Time
+	LastError     string
+	LastErrorAt   time.Time
+
+	// Buckets counts calls by latency. Buckets[i] counts the calls that took at most
+	// LatencyBuckets()[i] and were slower than the previous bound; the extra last
+	// entry counts the calls slower than every bound.
+	Buckets []int64
+}
+
+// AverageDuration returns the mean latency of the operation.
+func (m OperationMetrics) AverageDuration() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.TotalDuration / time.Duration(m.Count)
+}
+
+// ErrorRate returns the fraction of calls that failed.
+func (m OperationMetrics) ErrorRate() float64 {
+	if m.Count == 0 {
+		return 0
+	}
+	return float64(m.Errors) / float64(m.Count)
+}
+
+// Percentile estimates the latency below which the fraction p of the calls completed.
+// The estimate is the upper bound of the histogram bucket holding the percentile,
+// capped at the slowest observed call.
+func (m OperationMetrics) Percentile(p float64) time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	target := int64(p*float64(m.Count) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	var seen int64
+	for i, n := range m.Buckets {
+		seen += n
+		if seen >= target {
+			if i < len(latencyBuckets) && latencyBuckets[i] < m.MaxDuration {
+				return latencyBuckets[i]
+			}
+			return m.MaxDuration
+		}
+	}
+	return m.MaxDuration
+}
+
+type operationKey struct {
+	dbType     dbcapabilities.DatabaseType
+	databaseID string
+	operation  string
+}
+
+// MetricsRecorder collects latency and error metrics of adapter operations,
+// labelled by database type, database ID and operation.
+type MetricsRecorder struct {
+	mu         sync.RWMutex
+	operations map[operationKey]*OperationMetrics
+
+	// Query plans captured for slow fetches
+	planConfig PlanCaptureConfig
+	plans      map[string][]QueryPlanCapture
+	lastPlanAt map[planKey]time.Time
+}
+
+// NewMetricsRecorder creates an empty recorder.
+func NewMetricsRecorder() *MetricsRecorder {
+	return &MetricsRecorder{
+		operations: make(map[operationKey]*OperationMetrics),
+		planConfig: DefaultPlanCaptureConfig(),
+		plans:      make(map[string][]QueryPlanCapture),
+		lastPlanAt: make(map[planKey]time.Time),
+	}
+}
+
+// Observe records one call of an operation. Unsupported operation errors are
+// counted as calls but not as errors, since they do not indicate a faulty database.
+func (r *MetricsRecorder) Observe(dbType dbcapabilities.DatabaseType, databaseID, operation string, duration time.Duration, err error) {
+	key := operationKey{dbType: dbType, databaseID: databaseID, operation: operation}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, exists := r.operations[key]
+	if !exists {
+		m = &OperationMetrics{
+			DatabaseType: dbType,
+			DatabaseID:   databaseID,
+			Operation:    operation,
+			MinDuration:  duration,
+			Buckets:      make([]int64, len(latencyBuckets)+1),
+		}
+		r.operations[key] = m
+	}
+
+	m.Count++
+	m.TotalDuration += duration
+	m.LastDuration = duration
+	m.LastCalledAt = now
+	if duration < m.MinDuration {
+		m.MinDuration = duration
+	}
+	if duration > m.MaxDuration {
+		m.MaxDuration = duration
+	}
+	m.Buckets[sort.Search(len(latencyBuckets), func(i int) bool { return duration <= latencyBuckets[i] })]++
+
+	if err != nil && !IsUnsupported(err) {
+		m.Errors++
+		m.LastError = err.Error()
+		m.LastErrorAt = now
+	}
+}
+
+// Snapshot returns a copy of the metrics of the given databases, or of all databases
+// when none are given, ordered by database ID and operation.
+func (r *MetricsRecorder) Snapshot(databaseIDs ...string) []OperationMetrics {
+	filter := make(map[string]bool, len(databaseIDs))
+	for _, id := range databaseIDs {
+		filter[id] = true
+	}
+
+	r.mu.RLock()
+	result := make([]OperationMetrics, 0, len(r.operations))
+	for _, m := range r.operations {
+		if len(filter) > 0 && !filter[m.DatabaseID] {
+			continue
+		}
+		copied := *m
+		copied.Buckets = append([]int64(nil), m.Buckets...)
+		result = append(result, copied)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].DatabaseID != result[j].DatabaseID {
+			return result[i].DatabaseID < result[j].DatabaseID
+		}
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
+
+// Totals returns the number of calls and errors recorded over all databases.
+func (r *MetricsRecorder) Totals() (calls int64, errors int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.operations {
+		calls += m.Count
+		errors += m.Errors
+	}
+	return calls, errors
+}
+
+// Reset discards the metrics and captured query plans of a database.
+func (r *MetricsRecorder) Reset(databaseID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.operations {
+		if key.databaseID == databaseID {
+			delete(r.operations, key)
+		}
+	}
+	delete(r.plans, databaseID)
+	for key := range r.lastPlanAt {
+		if key.databaseID == databaseID {
+			delete(r.lastPlanAt, key)
+		}
+	}
+}
+
+// defaultMetrics is the recorder used by the anchor service.
+var defaultMetrics = NewMetricsRecorder()
+
+// DefaultMetrics returns the global metrics recorder.
+func DefaultMetrics() *MetricsRecorder {
+	return defaultMetrics
+}
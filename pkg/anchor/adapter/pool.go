@@ -0,0 +1,311 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPoolIdleTimeout is how long the registry keeps a pooled connection open after its
+// last user closed it.
+const DefaultPoolIdleTimeout = 5 * time.Minute
+
+// DefaultPoolDialTimeout bounds how long the registry waits for a pooled connection to be dialed.
+const DefaultPoolDialTimeout = time.Minute
+
+// PoolStats describes the connections held by a registry pool.
+type PoolStats struct {
+	Open       int // Open connections
+	Idle       int // Open connections without users, closed after the idle timeout
+	References int // Handles in use across all connections
+}
+
+// pooledResource is a connection that can be shared through a connectionPool
+type pooledResource interface {
+	IsConnected() bool
+	Close() error
+}
+
+// connectionPool shares one connection per key between reference-counted users. A connection is
+// dialed when its key is first acquired; concurrent users of the same key wait for that dial
+// instead of dialing themselves. When the last user releases it, the connection is kept for the
+// idle timeout so that short-lived users don't re-dial.
+type connectionPool[T pooledResource] struct {
+	mu          sync.Mutex
+	entries     map[string]*poolEntry[T]
+	idleTimeout time.Duration
+	dialTimeout time.Duration
+}
+
+// poolEntry is a pooled connection. ready is closed once the dial finished, after which conn or
+// err is set.
+type poolEntry[T pooledResource] struct {
+	key   string
+	conn  T
+	err   error
+	ready chan struct{}
+	refs  int
+	idle  *time.Timer
+}
+
+func newConnectionPool[T pooledResource](idleTimeout time.Duration) *connectionPool[T] {
+	return &connectionPool[T]{
+		entries:     make(map[string]*poolEntry[T]),
+		idleTimeout: idleTimeout,
+		dialTimeout: DefaultPoolDialTimeout,
+	}
+}
+
+// dialed returns whether the entry's dial finished and connected
+func (e *poolEntry[T]) dialed() bool {
+	select {
+	case <-e.ready:
+		return e.err == nil
+	default:
+		return false
+	}
+}
+
+// setIdleTimeout changes the idle timeout of connections released from now on
+func (p *connectionPool[T]) setIdleTimeout(idleTimeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idleTimeout = idleTimeout
+}
+
+// acquire returns the connection for key with a reference held, dialing it if there is none.
+// A connection that is no longer connected is replaced; its current users keep it until they
+// release it. A caller whose ctx ends while it waits gives up its reference without affecting
+// the dial, see dial.
+func (p *connectionPool[T]) acquire(ctx context.Context, key string, dial func(context.Context) (T, error)) (*poolEntry[T], error) {
+	p.mu.Lock()
+	entry, ok := p.entries[key]
+	if ok && entry.dialed() && !entry.conn.IsConnected() {
+		delete(p.entries, key)
+		ok = false
+	}
+	if !ok {
+		entry = &poolEntry[T]{key: key, ready: make(chan struct{})}
+		p.entries[key] = entry
+		go p.dial(ctx, entry, dial)
+	}
+	entry.refs++
+	if entry.idle != nil {
+		entry.idle.Stop()
+		entry.idle = nil
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		p.release(entry)
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		p.release(entry)
+		return nil, entry.err
+	}
+	return entry, nil
+}
+
+// dial connects the connection of an entry. The dial is detached from the cancellation of the
+// caller that started it, which would otherwise fail every caller waiting for the same
+// connection, and is bounded by the dial timeout instead. If all callers gave up meanwhile, the
+// connection is handled as released.
+func (p *connectionPool[T]) dial(ctx context.Context, entry *poolEntry[T], dial func(context.Context) (T, error)) {
+	p.mu.Lock()
+	timeout := p.dialTimeout
+	p.mu.Unlock()
+
+	dialCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	conn, err := dial(dialCtx)
+
+	p.mu.Lock()
+	entry.conn, entry.err = conn, err
+	if err != nil && p.entries[entry.key] == entry {
+		delete(p.entries, entry.key)
+	}
+	close(entry.ready)
+	closeNow := err == nil && entry.refs == 0 && p.unusedLocked(entry)
+	p.mu.Unlock()
+
+	if closeNow {
+		conn.Close()
+	}
+}
+
+// release drops a reference to a connection. The last reference schedules the connection to be
+// closed after the idle timeout, or closes it right away if it has been replaced or there is no
+// idle timeout. A connection still being dialed is handled by dial once it is connected.
+func (p *connectionPool[T]) release(entry *poolEntry[T]) error {
+	p.mu.Lock()
+	entry.refs--
+	if entry.refs > 0 || !entry.dialed() {
+		p.mu.Unlock()
+		return nil
+	}
+	closeNow := p.unusedLocked(entry)
+	p.mu.Unlock()
+
+	if closeNow {
+		return entry.conn.Close()
+	}
+	return nil
+}
+
+// unusedLocked handles a connected entry that lost its last reference: it either schedules it to
+// be evicted after the idle timeout, or removes it from the pool and reports that the caller must
+// close it. p.mu must be held.
+func (p *connectionPool[T]) unusedLocked(entry *poolEntry[T]) bool {
+	current := p.entries[entry.key] == entry
+	if current && p.idleTimeout > 0 {
+		entry.idle = time.AfterFunc(p.idleTimeout, func() { p.evict(entry) })
+		return false
+	}
+	if current {
+		delete(p.entries, entry.key)
+	}
+	return true
+}
+
+// evict closes a connection whose idle timeout expired, unless it was acquired again meanwhile
+func (p *connectionPool[T]) evict(entry *poolEntry[T]) {
+	p.mu.Lock()
+	if entry.refs > 0 || p.entries[entry.key] != entry {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.entries, entry.key)
+	p.mu.Unlock()
+
+	entry.conn.Close()
+}
+
+// closeIdle closes the connections that have no users and returns the first error
+func (p *connectionPool[T]) closeIdle() error {
+	p.mu.Lock()
+	var idle []*poolEntry[T]
+	for key, entry := range p.entries {
+		if entry.refs == 0 && entry.dialed() {
+			if entry.idle != nil {
+				entry.idle.Stop()
+				entry.idle = nil
+			}
+			delete(p.entries, key)
+			idle = append(idle, entry)
+		}
+	}
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, entry := range idle {
+		if err := entry.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// stats returns the number of open and idle connections and of references held
+func (p *connectionPool[T]) stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var stats PoolStats
+	for _, entry := range p.entries {
+		if !entry.dialed() {
+			continue
+		}
+		stats.Open++
+		stats.References += entry.refs
+		if entry.refs == 0 {
+			stats.Idle++
+		}
+	}
+	return stats
+}
+
+// poolKey hashes a configuration into a pool key. The key covers the whole configuration,
+// identifiers and metadata included: adapters keep the configuration a connection was dialed
+// with and their operators report its database ID, so a connection is only shared between
+// callers with identical configurations.
+func poolKey(config interface{}) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pooledConnection is a handle on a pooled database connection. It reports the configuration it
+// was requested with, and closing it releases the handle's reference instead of closing the
+// shared connection.
+type pooledConnection struct {
+	Connection
+	config ConnectionConfig
+	pool   *connectionPool[Connection]
+	entry  *poolEntry[Connection]
+	closed int32
+}
+
+// ID returns the database ID the handle was requested for.
+func (c *pooledConnection) ID() string {
+	return c.config.DatabaseID
+}
+
+// Config returns the configuration the handle was requested with.
+func (c *pooledConnection) Config() ConnectionConfig {
+	return c.config
+}
+
+// IsConnected returns whether the handle is open and the shared connection active.
+func (c *pooledConnection) IsConnected() bool {
+	return atomic.LoadInt32(&c.closed) == 0 && c.Connection.IsConnected()
+}
+
+// Close releases the handle. The shared connection is closed once it has no users left.
+func (c *pooledConnection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return ErrConnectionClosed
+	}
+	return c.pool.release(c.entry)
+}
+
+// pooledInstanceConnection is a handle on a pooled instance connection.
+type pooledInstanceConnection struct {
+	InstanceConnection
+	config InstanceConfig
+	pool   *connectionPool[InstanceConnection]
+	entry  *poolEntry[InstanceConnection]
+	closed int32
+}
+
+// ID returns the instance ID the handle was requested for.
+func (c *pooledInstanceConnection) ID() string {
+	return c.config.InstanceID
+}
+
+// Config returns the configuration the handle was requested with.
+func (c *pooledInstanceConnection) Config() InstanceConfig {
+	return c.config
+}
+
+// IsConnected returns whether the handle is open and the shared connection active.
+func (c *pooledInstanceConnection) IsConnected() bool {
+	return atomic.LoadInt32(&c.closed) == 0 && c.InstanceConnection.IsConnected()
+}
+
+// Close releases the handle. The shared connection is closed once it has no users left.
+func (c *pooledInstanceConnection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return ErrConnectionClosed
+	}
+	return c.pool.release(c.entry)
+}
@@ -0,0 +1,221 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeResource struct {
+	closed atomic.Int32
+}
+
+func (r *fakeResource) IsConnected() bool { return r.closed.Load() == 0 }
+
+func (r *fakeResource) Close() error {
+	r.closed.Add(1)
+	return nil
+}
+
+// countingDial returns a dial function that creates fakeResources and counts its calls
+func countingDial(dials *atomic.Int32) func(context.Context) (*fakeResource, error) {
+	return func(ctx context.Context) (*fakeResource, error) {
+		dials.Add(1)
+		return &fakeResource{}, nil
+	}
+}
+
+func TestPoolSharesConnectionUntilLastRelease(t *testing.T) {
+	pool := newConnectionPool[*fakeResource](0)
+	var dials atomic.Int32
+	ctx := context.Background()
+
+	first, err := pool.acquire(ctx, "db", countingDial(&dials))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pool.acquire(ctx, "db", countingDial(&dials))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || dials.Load() != 1 {
+		t.Fatalf("got %d dials and distinct entries %v, want one shared connection", dials.Load(), first != second)
+	}
+	if stats := pool.stats(); stats != (PoolStats{Open: 1, References: 2}) {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	if err := pool.release(first); err != nil {
+		t.Fatal(err)
+	}
+	if first.conn.closed.Load() != 0 {
+		t.Fatal("connection closed while still referenced")
+	}
+	if err := pool.release(second); err != nil {
+		t.Fatal(err)
+	}
+	if first.conn.closed.Load() != 1 {
+		t.Fatalf("connection closed %d times after the last release, want 1", first.conn.closed.Load())
+	}
+	if stats := pool.stats(); stats != (PoolStats{}) {
+		t.Fatalf("stats after release = %+v, want empty", stats)
+	}
+}
+
+func TestPoolEvictsIdleConnections(t *testing.T) {
+	pool := newConnectionPool[*fakeResource](20 * time.Millisecond)
+	var dials atomic.Int32
+	ctx := context.Background()
+
+	entry, err := pool.acquire(ctx, "db", countingDial(&dials))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.release(entry)
+	if stats := pool.stats(); stats != (PoolStats{Open: 1, Idle: 1}) {
+		t.Fatalf("stats after release = %+v, want one idle connection", stats)
+	}
+
+	// Acquiring within the idle timeout reuses the connection and cancels its eviction
+	again, err := pool.acquire(ctx, "db", countingDial(&dials))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != entry || dials.Load() != 1 {
+		t.Fatal("idle connection was not reused")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if entry.conn.closed.Load() != 0 {
+		t.Fatal("connection in use was evicted")
+	}
+
+	pool.release(again)
+	deadline := time.Now().Add(time.Second)
+	for entry.conn.closed.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("idle connection was not evicted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if stats := pool.stats(); stats != (PoolStats{}) {
+		t.Fatalf("stats after eviction = %+v, want empty", stats)
+	}
+}
+
+func TestPoolDialSurvivesCanceledCaller(t *testing.T) {
+	pool := newConnectionPool[*fakeResource](time.Minute)
+	unblock := make(chan struct{})
+	dial := func(ctx context.Context) (*fakeResource, error) {
+		select {
+		case <-unblock:
+			return &fakeResource{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := pool.acquire(firstCtx, "db", dial)
+		firstErr <- err
+	}()
+	waiter := make(chan error, 1)
+	go func() {
+		entry, err := pool.acquire(context.Background(), "db", dial)
+		if err == nil && !entry.conn.IsConnected() {
+			err = fmt.Errorf("got a closed connection")
+		}
+		waiter <- err
+	}()
+
+	// The caller that started the dial gives up; the other caller must still get the connection
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled caller got %v, want context.Canceled", err)
+	}
+	close(unblock)
+	if err := <-waiter; err != nil {
+		t.Fatalf("waiting caller failed: %v", err)
+	}
+	if stats := pool.stats(); stats != (PoolStats{Open: 1, References: 1}) {
+		t.Fatalf("stats = %+v, want one connection held by the waiting caller", stats)
+	}
+}
+
+func TestPoolDialTimeout(t *testing.T) {
+	pool := newConnectionPool[*fakeResource](time.Minute)
+	pool.dialTimeout = 10 * time.Millisecond
+	dial := func(ctx context.Context) (*fakeResource, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	if _, err := pool.acquire(context.Background(), "db", dial); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire = %v, want the dial timeout", err)
+	}
+	if stats := pool.stats(); stats != (PoolStats{}) {
+		t.Fatalf("stats after failed dial = %+v, want empty", stats)
+	}
+}
+
+func TestPoolConcurrentAcquireAndRelease(t *testing.T) {
+	pool := newConnectionPool[*fakeResource](time.Millisecond)
+	var dials atomic.Int32
+	dial := countingDial(&dials)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("db%d", i%2)
+			for j := 0; j < 200; j++ {
+				entry, err := pool.acquire(context.Background(), key, dial)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !entry.conn.IsConnected() {
+					t.Error("acquired a closed connection")
+				}
+				if j%50 == 0 {
+					// Let the idle timers fire between acquires
+					time.Sleep(2 * time.Millisecond)
+				}
+				pool.release(entry)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := pool.closeIdle(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := pool.stats(); stats != (PoolStats{}) {
+		t.Fatalf("stats after closing idle connections = %+v, want empty", stats)
+	}
+}
+
+func TestPoolKeyCoversIdentity(t *testing.T) {
+	base := ConnectionConfig{ConnectionType: "postgres", Host: "db", Port: 5432, DatabaseName: "app"}
+
+	other := base
+	other.DatabaseID = "db_2"
+	first, err := poolKey(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := poolKey(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatal("configurations for different databases share a pool key")
+	}
+}
@@ -0,0 +1,143 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultQueryMaxRows is the number of rows returned by a passthrough query unless asked
+// otherwise, and MaxQueryRows the most it can return.
+const (
+	DefaultQueryMaxRows = 1000
+	MaxQueryRows        = 10000
+)
+
+// QueryResult is the result of an ad-hoc query passed through to a database.
+type QueryResult struct {
+	Columns   []string
+	Rows      []map[string]interface{}
+	Truncated bool // The query returned more rows than requested; the others were dropped
+}
+
+// ReadOnlyQueryExecutor is implemented by data operators that can run a query in a read-only
+// transaction or session, so that the database rejects any modification whatever the text
+// of the query.
+type ReadOnlyQueryExecutor interface {
+	// ExecuteReadOnlyQuery runs a query in a read-only transaction or session and returns at
+	// most maxRows rows.
+	ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*QueryResult, error)
+}
+
+// ExecuteQuery passes an ad-hoc read query through to the database of a connection and
+// returns at most maxRows rows. The database must support query passthrough (see
+// dbcapabilities) and its data operator must run the query read-only (see
+// ReadOnlyQueryExecutor); the text of a query is not inspected, so other databases only
+// accept commands.
+func ExecuteQuery(ctx context.Context, conn Connection, query string, maxRows int) (*QueryResult, error) {
+	if err := checkQueryPassthrough(conn, "execute query"); err != nil {
+		return nil, err
+	}
+	executor, ok := asReadOnlyQueryExecutor(conn.DataOperations())
+	if !ok {
+		return nil, NewUnsupportedOperationError(conn.Type(), "execute query", "queries cannot be run read-only on this database, run the statement as a command")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%w: query is empty", ErrInvalidQuery)
+	}
+	return executor.ExecuteReadOnlyQuery(ctx, query, clampQueryMaxRows(maxRows))
+}
+
+// ExecuteCommand passes an ad-hoc command, which may modify the database, through to the
+// database of a connection and returns at most maxRows of the rows it returns. The database
+// must support query passthrough (see dbcapabilities).
+func ExecuteCommand(ctx context.Context, conn Connection, command string, maxRows int) (*QueryResult, error) {
+	if err := checkQueryPassthrough(conn, "execute command"); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(command) == "" {
+		return nil, fmt.Errorf("%w: command is empty", ErrInvalidQuery)
+	}
+
+	rows, err := conn.DataOperations().ExecuteQuery(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	return newQueryResult(rows, clampQueryMaxRows(maxRows)), nil
+}
+
+// checkQueryPassthrough returns an unsupported operation error if the database of a
+// connection does not support query passthrough.
+func checkQueryPassthrough(conn Connection, operation string) error {
+	if !conn.Adapter().Capabilities().SupportsQueryPassthrough || conn.DataOperations() == nil {
+		return NewUnsupportedOperationError(conn.Type(), operation, "query passthrough is not supported by this database")
+	}
+	return nil
+}
+
+func clampQueryMaxRows(maxRows int) int {
+	if maxRows <= 0 {
+		return DefaultQueryMaxRows
+	}
+	if maxRows > MaxQueryRows {
+		return MaxQueryRows
+	}
+	return maxRows
+}
+
+// asReadOnlyQueryExecutor returns the read-only query executor of a data operator, if it
+// has one.
+func asReadOnlyQueryExecutor(ops DataOperator) (ReadOnlyQueryExecutor, bool) {
+	// The instrumented, routed and throttled operators always have the method; they run
+	// read-only transactions only if the operator they wrap does
+	if instrumented, ok := ops.(*instrumentedDataOperator); ok {
+		if _, ok := asReadOnlyQueryExecutor(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledDataOperator); ok {
+		if _, ok := asReadOnlyQueryExecutor(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedDataOperator); ok {
+		if _, ok := asReadOnlyQueryExecutor(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	executor, ok := ops.(ReadOnlyQueryExecutor)
+	return executor, ok
+}
+
+// newQueryResult builds the result of the rows returned by DataOperator.ExecuteQuery. The
+// rows carry no column order, so columns are sorted by name.
+func newQueryResult(rows []interface{}, maxRows int) *QueryResult {
+	result := &QueryResult{}
+	if len(rows) > maxRows {
+		rows = rows[:maxRows]
+		result.Truncated = true
+	}
+
+	columns := make(map[string]bool)
+	result.Rows = make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		record, ok := row.(map[string]interface{})
+		if !ok {
+			record = map[string]interface{}{"value": row}
+		}
+		for column := range record {
+			columns[column] = true
+		}
+		result.Rows = append(result.Rows, record)
+	}
+
+	for column := range columns {
+		result.Columns = append(result.Columns, column)
+	}
+	sort.Strings(result.Columns)
+	return result
+}
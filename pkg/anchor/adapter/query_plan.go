@@ -0,0 +1,165 @@
+package adapter
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// QueryPlan is the execution plan of a query as reported by the database.
+type QueryPlan struct {
+	Query    string // Query or command that was explained
+	Format   string // "json" or "text"
+	Plan     string
+	Analyzed bool // The plan holds actual run statistics (EXPLAIN ANALYZE, executionStats)
+}
+
+// QueryPlanExplainer is implemented by data operators that can explain the
+// query they run for Fetch.
+type QueryPlanExplainer interface {
+	// ExplainFetch returns the plan of the query behind Fetch(table, limit).
+	// With analyze the query is executed to collect actual run statistics.
+	ExplainFetch(ctx context.Context, table string, limit int, analyze bool) (*QueryPlan, error)
+}
+
+// PlanCaptureConfig controls the capture of query plans for slow fetches.
+type PlanCaptureConfig struct {
+	// Threshold is the fetch latency from which the plan is captured; zero disables capture.
+	Threshold time.Duration
+	// Analyze captures the plan with actual run statistics, which runs the query again.
+	Analyze bool
+	// Cooldown is the minimum time between two captures for the same table.
+	Cooldown time.Duration
+}
+
+// DefaultPlanCaptureConfig returns the plan capture settings used unless configured otherwise.
+func DefaultPlanCaptureConfig() PlanCaptureConfig {
+	return PlanCaptureConfig{
+		Threshold: 5 * time.Second,
+		Cooldown:  10 * time.Minute,
+	}
+}
+
+// QueryPlanCapture is a query plan captured for a slow fetch.
+type QueryPlanCapture struct {
+	DatabaseType dbcapabilities.DatabaseType
+	DatabaseID   string
+	Operation    string
+	Table        string
+	Limit        int
+	Duration     time.Duration // Latency of the slow fetch
+	CapturedAt   time.Time
+	Plan         *QueryPlan
+	Error        string // Set when the plan could not be captured
+}
+
+// maxPlansPerDatabase bounds the number of plans kept per database; older plans are dropped.
+const maxPlansPerDatabase = 20
+
+// planCaptureTimeout bounds the time spent explaining a query.
+const planCaptureTimeout = 30 * time.Second
+
+type planKey struct {
+	databaseID string
+	table      string
+}
+
+// SetPlanCapture replaces the plan capture settings.
+func (r *MetricsRecorder) SetPlanCapture(cfg PlanCaptureConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.planConfig = cfg
+}
+
+// PlanCapture returns the plan capture settings.
+func (r *MetricsRecorder) PlanCapture() PlanCaptureConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.planConfig
+}
+
+// shouldCapturePlan reports whether a fetch of the given duration needs its plan
+// captured, and if so reserves the capture so that concurrent slow fetches of the
+// same table do not explain it again within the cooldown.
+func (r *MetricsRecorder) shouldCapturePlan(databaseID, table string, duration time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.planConfig.Threshold <= 0 || duration < r.planConfig.Threshold {
+		return false
+	}
+	key := planKey{databaseID: databaseID, table: table}
+	now := time.Now()
+	if last, ok := r.lastPlanAt[key]; ok && now.Sub(last) < r.planConfig.Cooldown {
+		return false
+	}
+	r.lastPlanAt[key] = now
+	return true
+}
+
+// RecordQueryPlan stores a captured plan.
+func (r *MetricsRecorder) RecordQueryPlan(capture QueryPlanCapture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	plans := append(r.plans[capture.DatabaseID], capture)
+	if len(plans) > maxPlansPerDatabase {
+		plans = plans[len(plans)-maxPlansPerDatabase:]
+	}
+	r.plans[capture.DatabaseID] = plans
+}
+
+// QueryPlans returns the plans captured for the given databases, or for all databases
+// when none are given, newest first.
+func (r *MetricsRecorder) QueryPlans(databaseIDs ...string) []QueryPlanCapture {
+	r.mu.RLock()
+	var result []QueryPlanCapture
+	if len(databaseIDs) == 0 {
+		for _, plans := range r.plans {
+			result = append(result, plans...)
+		}
+	} else {
+		for _, id := range databaseIDs {
+			result = append(result, r.plans[id]...)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CapturedAt.After(result[j].CapturedAt)
+	})
+	return result
+}
+
+// capturePlan explains a slow fetch in the background when the data operator supports it.
+func (c *instrumentedConnection) capturePlan(ops DataOperator, operation, table string, limit int, duration time.Duration) {
+	explainer, ok := ops.(QueryPlanExplainer)
+	if !ok || !c.rec.shouldCapturePlan(c.ID(), table, duration) {
+		return
+	}
+	analyze := c.rec.PlanCapture().Analyze
+	capture := QueryPlanCapture{
+		DatabaseType: c.Type(),
+		DatabaseID:   c.ID(),
+		Operation:    operation,
+		Table:        table,
+		Limit:        limit,
+		Duration:     duration,
+	}
+
+	go func() {
+		// The fetch context may already be done; the plan is captured on its own deadline
+		ctx, cancel := context.WithTimeout(context.Background(), planCaptureTimeout)
+		defer cancel()
+
+		plan, err := explainer.ExplainFetch(ctx, table, limit, analyze)
+		capture.CapturedAt = time.Now()
+		capture.Plan = plan
+		if err != nil {
+			capture.Error = err.Error()
+		}
+		c.rec.RecordQueryPlan(capture)
+	}()
+}
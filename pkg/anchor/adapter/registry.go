@@ -4,20 +4,32 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/redact"
 )
 
 // Registry manages the registration and retrieval of database adapters.
+//
+// Connections opened through the registry are pooled: Connect and ConnectInstance share one
+// connection between all callers with the same configuration and return a handle per
+// caller. Closing a handle releases it; the shared connection is closed once it has been
+// unused for the pool idle timeout.
 type Registry struct {
 	adapters map[dbcapabilities.DatabaseType]DatabaseAdapter
 	mu       sync.RWMutex
+
+	connections *connectionPool[Connection]
+	instances   *connectionPool[InstanceConnection]
 }
 
 // NewRegistry creates a new adapter registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		adapters: make(map[dbcapabilities.DatabaseType]DatabaseAdapter),
+		adapters:    make(map[dbcapabilities.DatabaseType]DatabaseAdapter),
+		connections: newConnectionPool[Connection](DefaultPoolIdleTimeout),
+		instances:   newConnectionPool[InstanceConnection](DefaultPoolIdleTimeout),
 	}
 }
 
@@ -94,7 +106,12 @@ func (r *Registry) Clear() {
 	r.adapters = make(map[dbcapabilities.DatabaseType]DatabaseAdapter)
 }
 
-// Connect creates a new database connection using the registered adapter.
+// Connect returns a connection to the database using the registered adapter. Callers with the
+// same configuration share one connection, which is only dialed if none is open. The returned
+// connection must be closed to release it.
+//
+// With the reads-from-replica routing policy, the returned connection sends reads to the
+// replicas of the configuration and writes to the primary, see routedConnection.
 func (r *Registry) Connect(ctx context.Context, config ConnectionConfig) (Connection, error) {
 	dbType, ok := dbcapabilities.ParseID(config.ConnectionType)
 	if !ok {
@@ -105,20 +122,52 @@ func (r *Registry) Connect(ctx context.Context, config ConnectionConfig) (Connec
 		)
 	}
 
+	switch config.RoutingPolicy {
+	case "", RoutingPolicyPrimary:
+		return r.connect(ctx, dbType, config)
+	case RoutingPolicyReadsFromReplica:
+		if len(config.Replicas) == 0 {
+			return r.connect(ctx, dbType, config)
+		}
+		return r.connectRouted(ctx, dbType, config)
+	default:
+		return nil, NewConfigurationError(dbType, "routingPolicy", fmt.Sprintf("unknown routing policy: %s", config.RoutingPolicy))
+	}
+}
+
+// connect returns a pooled connection to the database of a configuration, ignoring its
+// replicas.
+func (r *Registry) connect(ctx context.Context, dbType dbcapabilities.DatabaseType, config ConnectionConfig) (Connection, error) {
 	adapter, err := r.Get(dbType)
 	if err != nil {
 		return nil, err
 	}
 
-	conn, err := adapter.Connect(ctx, config)
+	// Keep the credentials out of logs and errors, including those of the driver
+	redact.Register(config.Secrets()...)
+
+	key, err := poolKey(config)
+	if err != nil {
+		return nil, WrapError(dbType, "connect", err)
+	}
+
+	entry, err := r.connections.acquire(ctx, key, func(ctx context.Context) (Connection, error) {
+		return dialConnection(ctx, adapter, config)
+	})
 	if err != nil {
 		return nil, WrapError(dbType, "connect", err)
 	}
 
-	return conn, nil
+	return &pooledConnection{
+		Connection: entry.conn,
+		config:     config,
+		pool:       r.connections,
+		entry:      entry,
+	}, nil
 }
 
-// ConnectInstance creates a new instance connection using the registered adapter.
+// ConnectInstance returns an instance connection using the registered adapter. Instance
+// connections are pooled like database connections, see Connect.
 func (r *Registry) ConnectInstance(ctx context.Context, config InstanceConfig) (InstanceConnection, error) {
 	dbType, ok := dbcapabilities.ParseID(config.ConnectionType)
 	if !ok {
@@ -134,12 +183,52 @@ func (r *Registry) ConnectInstance(ctx context.Context, config InstanceConfig) (
 		return nil, err
 	}
 
-	conn, err := adapter.ConnectInstance(ctx, config)
+	redact.Register(config.Secrets()...)
+
+	key, err := poolKey(config)
 	if err != nil {
 		return nil, WrapError(dbType, "connect_instance", err)
 	}
 
-	return conn, nil
+	entry, err := r.instances.acquire(ctx, key, func(ctx context.Context) (InstanceConnection, error) {
+		return dialInstance(ctx, adapter, config)
+	})
+	if err != nil {
+		return nil, WrapError(dbType, "connect_instance", err)
+	}
+
+	return &pooledInstanceConnection{
+		InstanceConnection: entry.conn,
+		config:             config,
+		pool:               r.instances,
+		entry:              entry,
+	}, nil
+}
+
+// SetPoolIdleTimeout sets how long unused pooled connections are kept open. With a timeout of
+// zero, connections are closed as soon as their last user closes them.
+func (r *Registry) SetPoolIdleTimeout(idleTimeout time.Duration) {
+	r.connections.setIdleTimeout(idleTimeout)
+	r.instances.setIdleTimeout(idleTimeout)
+}
+
+// CloseIdleConnections closes the pooled database and instance connections that have no users.
+func (r *Registry) CloseIdleConnections() error {
+	connErr := r.connections.closeIdle()
+	if err := r.instances.closeIdle(); err != nil && connErr == nil {
+		return err
+	}
+	return connErr
+}
+
+// PoolStats returns the state of the database connection pool.
+func (r *Registry) PoolStats() PoolStats {
+	return r.connections.stats()
+}
+
+// InstancePoolStats returns the state of the instance connection pool.
+func (r *Registry) InstancePoolStats() PoolStats {
+	return r.instances.stats()
 }
 
 // GetCapabilities returns the capabilities for a database type.
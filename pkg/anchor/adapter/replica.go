@@ -0,0 +1,481 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// ReplicaRetryInterval is how long a replica that failed is skipped before reads are sent to
+// it again.
+const ReplicaRetryInterval = 30 * time.Second
+
+// replicaPingTimeout bounds the health check of a replica whose read failed
+const replicaPingTimeout = 5 * time.Second
+
+// connectRouted returns a connection that sends the reads of a database to its replicas and
+// the writes to the primary. Only the primary is connected right away; a replica is
+// connected on its first read, so that an unreachable replica does not fail the connection.
+func (r *Registry) connectRouted(ctx context.Context, dbType dbcapabilities.DatabaseType, config ConnectionConfig) (Connection, error) {
+	primary, err := r.connect(ctx, dbType, config)
+	if err != nil {
+		return nil, err
+	}
+
+	routed := &routedConnection{
+		Connection: primary,
+		registry:   r,
+		dbType:     dbType,
+	}
+	for _, endpoint := range config.Replicas {
+		routed.replicas = append(routed.replicas, &replica{config: replicaConfig(config, endpoint)})
+	}
+	return routed, nil
+}
+
+// replicaConfig returns the configuration of a replica of the database of config
+func replicaConfig(config ConnectionConfig, endpoint ReplicaEndpoint) ConnectionConfig {
+	config.Host = endpoint.Host
+	config.Port = endpoint.Port
+	if endpoint.Username != "" {
+		config.Username = endpoint.Username
+		config.Password = endpoint.Password
+	}
+	// IAM tokens are signed for the host and user they authenticate
+	config.Tokens = nil
+	// Replicas are reached at their address, not through the socket of the primary
+	config.SocketPath = ""
+	// Kerberos tickets are issued for the service principal on the host of the replica
+	if config.KerberosAuth != nil && config.KerberosAuth.SPN != "" {
+		auth := *config.KerberosAuth
+		auth.SPN = ""
+		config.KerberosAuth = &auth
+	}
+	config.Replicas = nil
+	config.RoutingPolicy = ""
+	return config
+}
+
+// replica is a read replica of a routed connection. A replica whose connection failed is
+// skipped until retryAt.
+type replica struct {
+	config ConnectionConfig
+
+	mu      sync.Mutex
+	conn    Connection
+	dialing bool
+	retryAt time.Time
+}
+
+// routedConnection is a connection with the reads-from-replica routing policy. Schema
+// discovery and data reads are sent to the replicas in turn; writes, replication and
+// metadata operations to the primary. A replica that fails a read and does not answer a ping
+// is taken out of rotation for ReplicaRetryInterval and the read is retried on the next one,
+// and on the primary when no replica is healthy. Reads from replicas may lag behind writes
+// to the primary.
+type routedConnection struct {
+	Connection // The primary
+	registry   *Registry
+	dbType     dbcapabilities.DatabaseType
+	replicas   []*replica
+	next       uint32
+	closed     int32
+}
+
+// IsConnected returns whether the connection is open and the primary active.
+func (c *routedConnection) IsConnected() bool {
+	return atomic.LoadInt32(&c.closed) == 0 && c.Connection.IsConnected()
+}
+
+// Close releases the connections to the primary and the replicas.
+func (c *routedConnection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return ErrConnectionClosed
+	}
+	for _, rep := range c.replicas {
+		rep.mu.Lock()
+		if rep.conn != nil {
+			rep.conn.Close()
+			rep.conn = nil
+		}
+		rep.mu.Unlock()
+	}
+	return c.Connection.Close()
+}
+
+func (c *routedConnection) SchemaOperations() SchemaOperator {
+	ops := c.Connection.SchemaOperations()
+	if ops == nil {
+		return nil
+	}
+	return &routedSchemaOperator{conn: c, primary: ops}
+}
+
+func (c *routedConnection) DataOperations() DataOperator {
+	ops := c.Connection.DataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &routedDataOperator{conn: c, primary: ops}
+}
+
+// read runs a read on a healthy replica, picked round-robin so that reads are spread over
+// the replicas, and on the primary when no replica is healthy.
+func (c *routedConnection) read(ctx context.Context, op func(conn Connection) error) error {
+	start := int(atomic.AddUint32(&c.next, 1))
+	for i := range c.replicas {
+		rep := c.replicas[(start+i)%len(c.replicas)]
+		conn := c.replicaConnection(ctx, rep)
+		if conn == nil {
+			continue
+		}
+		err := op(conn)
+		if err == nil || !c.replicaFailed(ctx, rep, conn, err) {
+			return err
+		}
+	}
+	return op(c.Connection)
+}
+
+func (c *routedConnection) readSchema(ctx context.Context, op func(ops SchemaOperator) error) error {
+	return c.read(ctx, func(conn Connection) error {
+		ops := conn.SchemaOperations()
+		if ops == nil {
+			return ErrOperationNotSupported
+		}
+		return op(ops)
+	})
+}
+
+func (c *routedConnection) readData(ctx context.Context, op func(ops DataOperator) error) error {
+	return c.read(ctx, func(conn Connection) error {
+		ops := conn.DataOperations()
+		if ops == nil {
+			return ErrOperationNotSupported
+		}
+		return op(ops)
+	})
+}
+
+// replicaConnection returns the connection to a replica, connecting it if it has none. It
+// returns nil while the replica is being connected by another read or is out of rotation.
+func (c *routedConnection) replicaConnection(ctx context.Context, rep *replica) Connection {
+	rep.mu.Lock()
+	if rep.conn != nil && rep.conn.IsConnected() {
+		conn := rep.conn
+		rep.mu.Unlock()
+		return conn
+	}
+	if rep.dialing || time.Now().Before(rep.retryAt) {
+		rep.mu.Unlock()
+		return nil
+	}
+	if rep.conn != nil {
+		rep.conn.Close()
+		rep.conn = nil
+	}
+	rep.dialing = true
+	rep.mu.Unlock()
+
+	conn, err := c.registry.connect(ctx, c.dbType, rep.config)
+
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	rep.dialing = false
+	if err != nil {
+		// A read canceled while connecting says nothing about the replica
+		if ctx.Err() == nil {
+			rep.retryAt = time.Now().Add(ReplicaRetryInterval)
+		}
+		return nil
+	}
+	if atomic.LoadInt32(&c.closed) == 1 {
+		conn.Close()
+		return nil
+	}
+	rep.conn = conn
+	return conn
+}
+
+// replicaFailed reports whether a read failed because the replica is unhealthy rather than
+// because of the read itself. An unhealthy replica is taken out of rotation.
+func (c *routedConnection) replicaFailed(ctx context.Context, rep *replica, conn Connection, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if !IsConnectionError(err) && conn.IsConnected() {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		pingErr := conn.Ping(pingCtx)
+		cancel()
+		if pingErr == nil {
+			return false
+		}
+	}
+
+	rep.mu.Lock()
+	if rep.conn == conn {
+		rep.conn = nil
+		rep.retryAt = time.Now().Add(ReplicaRetryInterval)
+		rep.mu.Unlock()
+		conn.Close()
+		return true
+	}
+	rep.mu.Unlock()
+	return true
+}
+
+// routedSchemaOperator sends schema discovery to the replicas and schema changes to the
+// primary.
+type routedSchemaOperator struct {
+	conn    *routedConnection
+	primary SchemaOperator
+}
+
+func (s *routedSchemaOperator) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	var model *unifiedmodel.UnifiedModel
+	err := s.conn.readSchema(ctx, func(ops SchemaOperator) error {
+		var err error
+		model, err = ops.DiscoverSchema(ctx)
+		return err
+	})
+	return model, err
+}
+
+// DiscoverSchemaIncremental is only reached through DiscoverSchemaSince, which checks that
+// the primary discovers incrementally.
+func (s *routedSchemaOperator) DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	var model *unifiedmodel.UnifiedModel
+	err := s.conn.readSchema(ctx, func(ops SchemaOperator) error {
+		discoverer, ok := asIncrementalSchemaDiscoverer(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		model, err = discoverer.DiscoverSchemaIncremental(ctx, previous)
+		return err
+	})
+	return model, err
+}
+
+func (s *routedSchemaOperator) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	return s.primary.CreateStructure(ctx, model)
+}
+
+func (s *routedSchemaOperator) ListTables(ctx context.Context) ([]string, error) {
+	var tables []string
+	err := s.conn.readSchema(ctx, func(ops SchemaOperator) error {
+		var err error
+		tables, err = ops.ListTables(ctx)
+		return err
+	})
+	return tables, err
+}
+
+func (s *routedSchemaOperator) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	var table *unifiedmodel.Table
+	err := s.conn.readSchema(ctx, func(ops SchemaOperator) error {
+		var err error
+		table, err = ops.GetTableSchema(ctx, tableName)
+		return err
+	})
+	return table, err
+}
+
+// routedDataOperator sends data reads to the replicas and writes and queries to the primary.
+// ExecuteQuery goes to the primary since its statements may write.
+type routedDataOperator struct {
+	conn    *routedConnection
+	primary DataOperator
+}
+
+func (d *routedDataOperator) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		rows, err = ops.Fetch(ctx, table, limit)
+		return err
+	})
+	return rows, err
+}
+
+func (d *routedDataOperator) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		rows, err = ops.FetchWithColumns(ctx, table, columns, limit)
+		return err
+	})
+	return rows, err
+}
+
+func (d *routedDataOperator) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return d.primary.Insert(ctx, table, data)
+}
+
+// BulkInsert uses the bulk load of the primary if it has one, like BulkInsert on the primary.
+func (d *routedDataOperator) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return BulkInsert(ctx, d.primary, table, data)
+}
+
+func (d *routedDataOperator) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return d.primary.Update(ctx, table, data, whereColumns)
+}
+
+func (d *routedDataOperator) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	return d.primary.Upsert(ctx, table, data, uniqueColumns)
+}
+
+func (d *routedDataOperator) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	return d.primary.Delete(ctx, table, conditions)
+}
+
+func (d *routedDataOperator) Stream(ctx context.Context, params StreamParams) (StreamResult, error) {
+	var result StreamResult
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		result, err = ops.Stream(ctx, params)
+		return err
+	})
+	return result, err
+}
+
+// FetchStream reads the whole table from one replica; batches are not failed over.
+func (d *routedDataOperator) FetchStream(ctx context.Context, table string, batchSize int) (RowBatchIterator, error) {
+	var it RowBatchIterator
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		it, err = ops.FetchStream(ctx, table, batchSize)
+		return err
+	})
+	return it, err
+}
+
+// KeyBounds and FetchKeyRange are only reached through AsKeyRangeReader, which checks that
+// the primary reads key ranges.
+func (d *routedDataOperator) KeyBounds(ctx context.Context, table string, keyColumn string) (KeyRange, error) {
+	var keyRange KeyRange
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		reader, ok := AsKeyRangeReader(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		keyRange, err = reader.KeyBounds(ctx, table, keyColumn)
+		return err
+	})
+	return keyRange, err
+}
+
+func (d *routedDataOperator) FetchKeyRange(ctx context.Context, table string, keyRange KeyRange, batchSize int) (RowBatchIterator, error) {
+	var it RowBatchIterator
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		reader, ok := AsKeyRangeReader(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		it, err = reader.FetchKeyRange(ctx, table, keyRange, batchSize)
+		return err
+	})
+	return it, err
+}
+
+// ExecuteReadOnlyQuery is only reached through asReadOnlyQueryExecutor, which checks that
+// the primary runs read-only transactions.
+func (d *routedDataOperator) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*QueryResult, error) {
+	var result *QueryResult
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		executor, ok := asReadOnlyQueryExecutor(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		result, err = executor.ExecuteReadOnlyQuery(ctx, query, maxRows)
+		return err
+	})
+	return result, err
+}
+
+// SearchVectors is only reached through AsVectorSearcher, which checks that the primary
+// searches vectors.
+func (d *routedDataOperator) SearchVectors(ctx context.Context, table string, vector []float32, topK int) ([]VectorMatch, error) {
+	var matches []VectorMatch
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		searcher, ok := AsVectorSearcher(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		matches, err = searcher.SearchVectors(ctx, table, vector, topK)
+		return err
+	})
+	return matches, err
+}
+
+// SampleEdges is only reached through AsEdgeSampler, which checks that the primary samples
+// edges.
+func (d *routedDataOperator) SampleEdges(ctx context.Context, relationship string, limit int) ([]Edge, error) {
+	var edges []Edge
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		sampler, ok := AsEdgeSampler(ops)
+		if !ok {
+			return ErrOperationNotSupported
+		}
+		var err error
+		edges, err = sampler.SampleEdges(ctx, relationship, limit)
+		return err
+	})
+	return edges, err
+}
+
+// CreateSandbox, InsertSandbox, FetchSandbox and DropSandbox are only reached through
+// AsSandboxer, which checks that the primary writes into sandboxes. Sandboxes live on the
+// primary, so that their rows are read back as soon as they are written.
+func (d *routedDataOperator) CreateSandbox(ctx context.Context, sandbox string, tables []string) error {
+	return d.primary.(Sandboxer).CreateSandbox(ctx, sandbox, tables)
+}
+
+func (d *routedDataOperator) InsertSandbox(ctx context.Context, sandbox string, table string, data []map[string]interface{}) (int64, error) {
+	return d.primary.(Sandboxer).InsertSandbox(ctx, sandbox, table, data)
+}
+
+func (d *routedDataOperator) FetchSandbox(ctx context.Context, sandbox string, table string, limit int) ([]map[string]interface{}, error) {
+	return d.primary.(Sandboxer).FetchSandbox(ctx, sandbox, table, limit)
+}
+
+func (d *routedDataOperator) DropSandbox(ctx context.Context, sandbox string) error {
+	return d.primary.(Sandboxer).DropSandbox(ctx, sandbox)
+}
+
+func (d *routedDataOperator) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	return d.primary.ExecuteQuery(ctx, query, args...)
+}
+
+func (d *routedDataOperator) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	var count int64
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		count, err = ops.ExecuteCountQuery(ctx, query)
+		return err
+	})
+	return count, err
+}
+
+func (d *routedDataOperator) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	var count int64
+	var exact bool
+	err := d.conn.readData(ctx, func(ops DataOperator) error {
+		var err error
+		count, exact, err = ops.GetRowCount(ctx, table, whereClause)
+		return err
+	})
+	return count, exact, err
+}
+
+func (d *routedDataOperator) Wipe(ctx context.Context) error {
+	return d.primary.Wipe(ctx)
+}
@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sandboxer is implemented by data operators that can write into a sandbox: a temporary
+// namespace of the database (a schema, for instance) holding empty copies of tables, with the
+// columns, defaults and constraints of the tables themselves. Writing into a sandbox shows the
+// output of a mapping, and the constraint violations it causes, without touching the tables.
+type Sandboxer interface {
+	// CreateSandbox creates a sandbox holding empty copies of tables. Nothing is created if
+	// a table cannot be copied.
+	CreateSandbox(ctx context.Context, sandbox string, tables []string) error
+
+	// InsertSandbox inserts rows into the copy of a table in a sandbox, in a transaction.
+	InsertSandbox(ctx context.Context, sandbox string, table string, data []map[string]interface{}) (int64, error)
+
+	// FetchSandbox returns at most limit rows of the copy of a table in a sandbox.
+	FetchSandbox(ctx context.Context, sandbox string, table string, limit int) ([]map[string]interface{}, error)
+
+	// DropSandbox drops a sandbox and everything in it. Dropping a sandbox that does not
+	// exist is not an error.
+	DropSandbox(ctx context.Context, sandbox string) error
+}
+
+// sandboxNamePattern is the form of sandbox names, valid unquoted in every database
+var sandboxNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// ValidateSandboxName returns an error if a sandbox name is not a lower case identifier of at
+// most 63 characters.
+func ValidateSandboxName(sandbox string) error {
+	if !sandboxNamePattern.MatchString(sandbox) {
+		return fmt.Errorf("%w: invalid sandbox name %q", ErrInvalidConfiguration, sandbox)
+	}
+	return nil
+}
+
+// SandboxPrefix starts the names of the sandboxes of copy runs, followed by the time the
+// sandbox was created at, in Unix nanoseconds
+const SandboxPrefix = "redb_sandbox_"
+
+// StaleSandboxAge is the age after which a sandbox is taken for one left behind by a copy run
+// that could not drop it, because core or the anchor stopped during the run
+const StaleSandboxAge = 24 * time.Hour
+
+// IsStaleSandbox reports whether a name is that of a sandbox created more than StaleSandboxAge
+// before now
+func IsStaleSandbox(name string, now time.Time) bool {
+	created, ok := strings.CutPrefix(name, SandboxPrefix)
+	if !ok {
+		return false
+	}
+	nanos, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return false
+	}
+	return now.Sub(time.Unix(0, nanos)) > StaleSandboxAge
+}
+
+// AsSandboxer returns the sandboxer of a data operator, if it has one.
+func AsSandboxer(ops DataOperator) (Sandboxer, bool) {
+	// The instrumented, routed and throttled operators always have the methods; they write
+	// into sandboxes only if the operator they wrap does
+	if instrumented, ok := ops.(*instrumentedDataOperator); ok {
+		if _, ok := AsSandboxer(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledDataOperator); ok {
+		if _, ok := AsSandboxer(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedDataOperator); ok {
+		if _, ok := AsSandboxer(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	sandboxer, ok := ops.(Sandboxer)
+	return sandboxer, ok
+}
@@ -0,0 +1,39 @@
+package adapter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSandboxName(t *testing.T) {
+	for _, name := range []string{"redb_sandbox_1760000000", "_sandbox"} {
+		if err := ValidateSandboxName(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Sandbox", "1sandbox", "sandbox; DROP SCHEMA public", `"sandbox"`, strings.Repeat("s", 64)} {
+		if err := ValidateSandboxName(name); err == nil {
+			t.Errorf("%q was accepted", name)
+		}
+	}
+}
+
+func TestIsStaleSandbox(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{fmt.Sprintf("redb_sandbox_%d", now.Add(-StaleSandboxAge-time.Minute).UnixNano()), true},
+		{fmt.Sprintf("redb_sandbox_%d", now.Add(-time.Hour).UnixNano()), false},
+		{"redb_sandbox_test", false},
+		{"public", false},
+	}
+	for _, tt := range tests {
+		if got := IsStaleSandbox(tt.name, now); got != tt.want {
+			t.Errorf("IsStaleSandbox(%q) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// CatalogVersionIdentifier is the provenance source identifier under which incremental
+// discovery records the catalog version of an object. The version changes whenever the
+// catalog entries describing the object change.
+const CatalogVersionIdentifier = "catalog_version"
+
+// IncrementalSchemaDiscoverer is implemented by schema operators that can refresh a
+// previously discovered schema, re-reading only the objects whose catalog entries changed.
+// Schemas discovered by such operators record the catalog version of their objects in
+// their provenance.
+type IncrementalSchemaDiscoverer interface {
+	// DiscoverSchemaIncremental returns the current schema of the database. Objects of
+	// previous whose catalog version did not change are reused as they are.
+	DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error)
+}
+
+// DiscoverSchemaSince returns the current schema of a database, discovered incrementally
+// from a previously discovered schema when the schema operator supports it. previousHash is
+// the content hash of previous when it was captured; if previous no longer has that content,
+// or either is missing, the schema is discovered in full.
+func DiscoverSchemaSince(ctx context.Context, ops SchemaOperator, previous *unifiedmodel.UnifiedModel, previousHash string) (*unifiedmodel.UnifiedModel, error) {
+	discoverer, ok := asIncrementalSchemaDiscoverer(ops)
+	if !ok || previous == nil || previousHash == "" {
+		return ops.DiscoverSchema(ctx)
+	}
+
+	// A previous schema modified since it was captured cannot be trusted to match the
+	// catalog versions it records
+	if hash, err := unifiedmodel.ContentHash(previous); err != nil || hash != previousHash {
+		return ops.DiscoverSchema(ctx)
+	}
+	return discoverer.DiscoverSchemaIncremental(ctx, previous)
+}
+
+// asIncrementalSchemaDiscoverer returns the incremental discoverer of a schema operator, if
+// it has one.
+func asIncrementalSchemaDiscoverer(ops SchemaOperator) (IncrementalSchemaDiscoverer, bool) {
+	// The instrumented, routed, throttled and cached operators always have the method; they
+	// discover incrementally only if the operator they wrap does
+	if cached, ok := ops.(*cachedSchemaOperator); ok {
+		if _, ok := asIncrementalSchemaDiscoverer(cached.ops); !ok {
+			return nil, false
+		}
+		return cached, true
+	}
+	if instrumented, ok := ops.(*instrumentedSchemaOperator); ok {
+		if _, ok := asIncrementalSchemaDiscoverer(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledSchemaOperator); ok {
+		if _, ok := asIncrementalSchemaDiscoverer(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedSchemaOperator); ok {
+		if _, ok := asIncrementalSchemaDiscoverer(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	discoverer, ok := ops.(IncrementalSchemaDiscoverer)
+	return discoverer, ok
+}
@@ -0,0 +1,141 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// SocketPathAuto discovers the socket of a database on the anchor node among the default
+// socket paths of its type, see DefaultSocketPaths.
+const SocketPathAuto = "auto"
+
+// defaultSocketPaths are the places database servers put their socket by default, most common
+// first. {port} is replaced with the port of the configuration.
+var defaultSocketPaths = map[dbcapabilities.DatabaseType][]string{
+	dbcapabilities.PostgreSQL: {
+		"/var/run/postgresql/.s.PGSQL.{port}",
+		"/run/postgresql/.s.PGSQL.{port}",
+		"/tmp/.s.PGSQL.{port}",
+	},
+	dbcapabilities.MySQL: {
+		"/var/run/mysqld/mysqld.sock",
+		"/run/mysqld/mysqld.sock",
+		"/var/lib/mysql/mysql.sock",
+		"/tmp/mysql.sock",
+	},
+	dbcapabilities.MariaDB: {
+		"/run/mysqld/mysqld.sock",
+		"/var/run/mysqld/mysqld.sock",
+		"/var/lib/mysql/mysql.sock",
+		"/tmp/mysql.sock",
+	},
+	dbcapabilities.SQLServer: {
+		`\\.\pipe\sql\query`,
+		`\\.\pipe\MSSQL$SQLEXPRESS\sql\query`,
+	},
+}
+
+// DefaultSocketPaths returns the default socket paths of a database type for a port, most
+// common first, or nil if its adapter does not connect through sockets.
+func DefaultSocketPaths(dbType dbcapabilities.DatabaseType, port int) []string {
+	if port == 0 {
+		if c, ok := dbcapabilities.Get(dbType); ok {
+			port = c.DefaultPort
+		}
+	}
+	paths := make([]string, 0, len(defaultSocketPaths[dbType]))
+	for _, path := range defaultSocketPaths[dbType] {
+		paths = append(paths, strings.ReplaceAll(path, "{port}", strconv.Itoa(port)))
+	}
+	return paths
+}
+
+// DiscoverSocketPath returns the first default socket path of a database type that exists on
+// this node.
+func DiscoverSocketPath(dbType dbcapabilities.DatabaseType, port int) (string, error) {
+	paths := DefaultSocketPaths(dbType, port)
+	if len(paths) == 0 {
+		return "", fmt.Errorf("%s databases cannot be connected to through a socket", dbType)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no socket of a local %s server found, tried %s", dbType, strings.Join(paths, ", "))
+}
+
+// IsNamedPipe reports whether a socket path is a Windows named pipe, e.g. \\.\pipe\sql\query.
+func IsNamedPipe(path string) bool {
+	return strings.HasPrefix(path, `\\`)
+}
+
+// SplitNamedPipe splits the path of a named pipe into its host, "." for the local one, and the
+// name of the pipe, e.g. \\.\pipe\sql\query into "." and "sql\query".
+func SplitNamedPipe(path string) (host, pipe string, err error) {
+	rest, ok := strings.CutPrefix(path, `\\`)
+	if ok {
+		host, pipe, ok = strings.Cut(rest, `\pipe\`)
+	}
+	if !ok || host == "" || pipe == "" {
+		return "", "", fmt.Errorf(`invalid named pipe %q, expected \\host\pipe\name`, path)
+	}
+	return host, pipe, nil
+}
+
+// withSocketPath checks the socket path of a configuration and replaces SocketPathAuto with
+// the socket it discovers.
+func (c *ConnectionConfig) withSocketPath() error {
+	path, err := resolveSocketPath(c.ConnectionType, c.Port, c.SocketPath, c.SSHTunnel != nil)
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "socketPath", err.Error())
+	}
+	c.SocketPath = path
+	return nil
+}
+
+// withSocketPath checks the socket path of an instance configuration and replaces
+// SocketPathAuto with the socket it discovers.
+func (c *InstanceConfig) withSocketPath() error {
+	path, err := resolveSocketPath(c.ConnectionType, c.Port, c.SocketPath, c.SSHTunnel != nil)
+	if err != nil {
+		return NewConfigurationError(dbcapabilities.DatabaseType(c.ConnectionType), "socketPath", err.Error())
+	}
+	c.SocketPath = path
+	return nil
+}
+
+func resolveSocketPath(connectionType string, port int, path string, tunneled bool) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	if tunneled {
+		return "", fmt.Errorf("a socket cannot be connected to through an ssh tunnel")
+	}
+	dbType, _ := dbcapabilities.ParseID(connectionType)
+	if _, ok := defaultSocketPaths[dbType]; !ok {
+		return "", fmt.Errorf("%s databases cannot be connected to through a socket", connectionType)
+	}
+	if path == SocketPathAuto {
+		return DiscoverSocketPath(dbType, port)
+	}
+
+	pipe := IsNamedPipe(path)
+	switch {
+	case dbType == dbcapabilities.SQLServer && !pipe:
+		return "", fmt.Errorf("sql server is connected to through a named pipe, e.g. %s", defaultSocketPaths[dbType][0])
+	case dbType == dbcapabilities.SQLServer && runtime.GOOS != "windows":
+		return "", fmt.Errorf("named pipes can only be connected to by anchors running on windows")
+	case dbType == dbcapabilities.SQLServer:
+		_, _, err := SplitNamedPipe(path)
+		return path, err
+	case pipe:
+		return "", fmt.Errorf("%s databases are connected to through a unix socket, not a named pipe", connectionType)
+	}
+	return path, nil
+}
@@ -0,0 +1,83 @@
+package adapter
+
+import (
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func TestDefaultSocketPaths(t *testing.T) {
+	paths := DefaultSocketPaths(dbcapabilities.PostgreSQL, 0)
+	if len(paths) == 0 || paths[0] != "/var/run/postgresql/.s.PGSQL.5432" {
+		t.Errorf("DefaultSocketPaths(postgres, 0) = %v", paths)
+	}
+	if paths := DefaultSocketPaths(dbcapabilities.PostgreSQL, 5433); paths[0] != "/var/run/postgresql/.s.PGSQL.5433" {
+		t.Errorf("DefaultSocketPaths(postgres, 5433) = %v", paths)
+	}
+	if paths := DefaultSocketPaths(dbcapabilities.MongoDB, 0); len(paths) != 0 {
+		t.Errorf("DefaultSocketPaths(mongodb) = %v, want none", paths)
+	}
+}
+
+func TestSplitNamedPipe(t *testing.T) {
+	host, pipe, err := SplitNamedPipe(`\\.\pipe\MSSQL$SQLEXPRESS\sql\query`)
+	if err != nil || host != "." || pipe != `MSSQL$SQLEXPRESS\sql\query` {
+		t.Errorf("SplitNamedPipe = %q, %q, %v", host, pipe, err)
+	}
+	for _, path := range []string{`\\.\sql\query`, `\\\pipe\sql\query`, `/tmp/mysql.sock`} {
+		if _, _, err := SplitNamedPipe(path); err == nil {
+			t.Errorf("SplitNamedPipe(%q) should fail", path)
+		}
+	}
+}
+
+func TestResolveSocketPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+
+	// Discovery picks the first default path that exists
+	dir := t.TempDir()
+	socket := filepath.Join(dir, ".s.PGSQL.5432")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	saved := defaultSocketPaths[dbcapabilities.PostgreSQL]
+	defaultSocketPaths[dbcapabilities.PostgreSQL] = []string{filepath.Join(dir, "missing"), filepath.Join(dir, ".s.PGSQL.{port}")}
+	defer func() { defaultSocketPaths[dbcapabilities.PostgreSQL] = saved }()
+
+	config := ConnectionConfig{ConnectionType: "postgres", Port: 5432, SocketPath: SocketPathAuto}
+	if err := config.withSocketPath(); err != nil || config.SocketPath != socket {
+		t.Errorf("withSocketPath = %q, %v, want %q", config.SocketPath, err, socket)
+	}
+	config = ConnectionConfig{ConnectionType: "postgres", Port: 6000, SocketPath: SocketPathAuto}
+	if err := config.withSocketPath(); err == nil {
+		t.Error("discovery should fail without a socket for the port")
+	}
+
+	tests := []struct {
+		name   string
+		config ConnectionConfig
+	}{
+		{"ssh tunnel", ConnectionConfig{ConnectionType: "postgres", SocketPath: "/tmp/.s.PGSQL.5432", SSHTunnel: &SSHTunnel{Host: "bastion"}}},
+		{"unsupported database", ConnectionConfig{ConnectionType: "mongodb", SocketPath: "/tmp/mongodb-27017.sock"}},
+		{"named pipe for mysql", ConnectionConfig{ConnectionType: "mysql", SocketPath: `\\.\pipe\mysql`}},
+		{"unix socket for sql server", ConnectionConfig{ConnectionType: "mssql", SocketPath: "/tmp/mssql.sock"}},
+	}
+	for _, tt := range tests {
+		if err := tt.config.withSocketPath(); err == nil {
+			t.Errorf("%s: withSocketPath should fail", tt.name)
+		}
+	}
+
+	config = ConnectionConfig{ConnectionType: "mysql", SocketPath: "/var/run/mysqld/mysqld.sock"}
+	if err := config.withSocketPath(); err != nil || config.SocketPath != "/var/run/mysqld/mysqld.sock" {
+		t.Errorf("withSocketPath = %q, %v", config.SocketPath, err)
+	}
+}
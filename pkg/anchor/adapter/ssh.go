@@ -0,0 +1,416 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// DefaultSSHPort is the port of jump hosts without one
+const DefaultSSHPort = 22
+
+// SSHTunnel is a jump host through which a database is reached. The anchor opens an SSH
+// connection to the jump host and forwards the connections of the adapter to the Host and Port
+// of the database from there.
+type SSHTunnel struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"` // DefaultSSHPort if zero
+	Username string `json:"username"`
+
+	// Authentication with a private key (PEM contents or the path of a key file on the anchor
+	// node), a password and/or the keys of the SSH agent at SSH_AUTH_SOCK, tried in that order
+	PrivateKey           string `json:"privateKey,omitempty"`
+	PrivateKeyPassphrase string `json:"privateKeyPassphrase,omitempty"`
+	Password             string `json:"password,omitempty"`
+	UseAgent             bool   `json:"useAgent,omitempty"`
+
+	// Host key of the jump host in authorized_keys format. Unless InsecureSkipHostKeyCheck is
+	// set, the jump host is only connected to with a host key.
+	HostKey                  string `json:"hostKey,omitempty"`
+	InsecureSkipHostKeyCheck bool   `json:"insecureSkipHostKeyCheck,omitempty"`
+}
+
+// secrets returns the credentials of the tunnel
+func (t *SSHTunnel) secrets() []string {
+	if t == nil {
+		return nil
+	}
+	return []string{t.PrivateKey, t.PrivateKeyPassphrase, t.Password}
+}
+
+// address returns the address of the jump host
+func (t *SSHTunnel) address() string {
+	port := t.Port
+	if port == 0 {
+		port = DefaultSSHPort
+	}
+	return net.JoinHostPort(t.Host, strconv.Itoa(port))
+}
+
+// clientConfig returns the SSH client configuration of the tunnel. The returned close function
+// releases the connection to the SSH agent once the handshake is done.
+func (t *SSHTunnel) clientConfig() (*ssh.ClientConfig, func(), error) {
+	if t.Host == "" || t.Username == "" {
+		return nil, nil, errors.New("ssh tunnel host and username are required")
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case t.HostKey != "":
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(t.HostKey))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse ssh tunnel host key: %w", err)
+		}
+		hostKeyCallback = ssh.FixedHostKey(key)
+	case t.InsecureSkipHostKeyCheck:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, nil, fmt.Errorf("a host key is required to verify ssh tunnel host %s", t.Host)
+	}
+
+	closeAgent := func() {}
+	auth := make([]ssh.AuthMethod, 0, 3)
+	if t.PrivateKey != "" {
+		signer, err := t.signer()
+		if err != nil {
+			return nil, nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if t.Password != "" {
+		auth = append(auth, ssh.Password(t.Password))
+	}
+	if t.UseAgent {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, nil, errors.New("ssh tunnel uses the ssh agent but SSH_AUTH_SOCK is not set")
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to the ssh agent: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		closeAgent = func() { conn.Close() }
+	}
+	if len(auth) == 0 {
+		return nil, nil, errors.New("ssh tunnel needs a private key, a password or the ssh agent")
+	}
+
+	return &ssh.ClientConfig{
+		User:            t.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}, closeAgent, nil
+}
+
+// signer parses the private key of the tunnel
+func (t *SSHTunnel) signer() (ssh.Signer, error) {
+	key := []byte(t.PrivateKey)
+	if !strings.Contains(t.PrivateKey, "-----BEGIN") {
+		data, err := os.ReadFile(t.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssh tunnel private key: %w", err)
+		}
+		key = data
+	}
+
+	var signer ssh.Signer
+	var err error
+	if t.PrivateKeyPassphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(t.PrivateKeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh tunnel private key: %w", err)
+	}
+	return signer, nil
+}
+
+// sshTunnel is an open tunnel: connections to its local listener are forwarded through the
+// jump host to the target address.
+type sshTunnel struct {
+	client   *ssh.Client
+	listener net.Listener
+	target   string
+	lost     chan struct{} // closed when the connection to the jump host ends
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	done  bool
+	wg    sync.WaitGroup
+}
+
+// openSSHTunnel connects to the jump host of config and starts forwarding a local port to the
+// database at host and port.
+func openSSHTunnel(ctx context.Context, config *SSHTunnel, host string, port int) (*sshTunnel, error) {
+	clientConfig, closeAgent, err := config.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	addr := config.address()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh tunnel host %s: %w", addr, err)
+	}
+
+	// Bound the handshake by the context, which the dialer no longer covers
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake with tunnel host %s failed: %w", addr, err)
+	}
+	conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to listen for the ssh tunnel: %w", err)
+	}
+
+	tunnel := &sshTunnel{
+		client:   client,
+		listener: listener,
+		target:   net.JoinHostPort(host, strconv.Itoa(port)),
+		lost:     make(chan struct{}),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go func() {
+		client.Wait()
+		close(tunnel.lost)
+	}()
+	tunnel.wg.Add(1)
+	go tunnel.serve()
+	return tunnel, nil
+}
+
+// alive reports whether the tunnel is still connected to the jump host
+func (t *sshTunnel) alive() bool {
+	select {
+	case <-t.lost:
+		return false
+	default:
+		return true
+	}
+}
+
+// localPort returns the local port forwarded to the database
+func (t *sshTunnel) localPort() int {
+	return t.listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve forwards the connections to the local listener until it is closed
+func (t *sshTunnel) serve() {
+	defer t.wg.Done()
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+		if !t.track(local) {
+			local.Close()
+			return
+		}
+		t.wg.Add(1)
+		go t.forward(local)
+	}
+}
+
+// forward copies data between a local connection and the database until either side closes
+func (t *sshTunnel) forward(local net.Conn) {
+	defer t.wg.Done()
+	defer t.untrack(local)
+
+	remote, err := t.client.Dial("tcp", t.target)
+	if err != nil {
+		local.Close()
+		return
+	}
+	if !t.track(remote) {
+		local.Close()
+		remote.Close()
+		return
+	}
+	defer t.untrack(remote)
+
+	copied := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		copied <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		copied <- struct{}{}
+	}()
+	<-copied
+	local.Close()
+	remote.Close()
+	<-copied
+}
+
+// track registers a forwarded connection, or reports false once the tunnel is closed
+func (t *sshTunnel) track(conn net.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return false
+	}
+	t.conns[conn] = struct{}{}
+	return true
+}
+
+func (t *sshTunnel) untrack(conn net.Conn) {
+	t.mu.Lock()
+	delete(t.conns, conn)
+	t.mu.Unlock()
+}
+
+// Close stops forwarding, closes the forwarded connections and disconnects from the jump host.
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	if t.done {
+		t.mu.Unlock()
+		return nil
+	}
+	t.done = true
+	for conn := range t.conns {
+		conn.Close()
+	}
+	t.mu.Unlock()
+
+	t.listener.Close()
+	err := t.client.Close()
+	t.wg.Wait()
+	return err
+}
+
+// throughTunnel returns the address and TLS server name the adapter connects with through
+// tunnel: the local end of the tunnel, verified against the host name of the database.
+func throughTunnel(tunnel *sshTunnel, host, serverName string) (string, int, string) {
+	if serverName == "" {
+		serverName = host
+	}
+	return "127.0.0.1", tunnel.localPort(), serverName
+}
+
+// dialConnection connects an adapter to the database of a configuration, with the tokens of
+// its IAM authentication or its Kerberos tickets, through its SSH tunnel and at its socket if
+// it has them.
+func dialConnection(ctx context.Context, adapter DatabaseAdapter, config ConnectionConfig) (Connection, error) {
+	if err := config.withTokenSource(ctx); err != nil {
+		return nil, err
+	}
+	if err := config.withKerberosSPN(); err != nil {
+		return nil, err
+	}
+	if err := config.withSocketPath(); err != nil {
+		return nil, err
+	}
+	if config.SSHTunnel == nil {
+		return adapter.Connect(ctx, config)
+	}
+
+	tunnel, err := openSSHTunnel(ctx, config.SSHTunnel, config.Host, config.Port)
+	if err != nil {
+		return nil, err
+	}
+	config.Host, config.Port, config.TLSServerName = throughTunnel(tunnel, config.Host, config.TLSServerName)
+	config.SSHTunnel = nil
+
+	conn, err := adapter.Connect(ctx, config)
+	if err != nil {
+		tunnel.Close()
+		return nil, err
+	}
+	return &tunneledConnection{Connection: conn, tunnel: tunnel}, nil
+}
+
+// dialInstance connects an adapter to the instance of a configuration, with the tokens of its
+// IAM authentication or its Kerberos tickets, through its SSH tunnel and at its socket if it
+// has them.
+func dialInstance(ctx context.Context, adapter DatabaseAdapter, config InstanceConfig) (InstanceConnection, error) {
+	if err := config.withTokenSource(ctx); err != nil {
+		return nil, err
+	}
+	if err := config.withKerberosSPN(); err != nil {
+		return nil, err
+	}
+	if err := config.withSocketPath(); err != nil {
+		return nil, err
+	}
+	if config.SSHTunnel == nil {
+		return adapter.ConnectInstance(ctx, config)
+	}
+
+	tunnel, err := openSSHTunnel(ctx, config.SSHTunnel, config.Host, config.Port)
+	if err != nil {
+		return nil, err
+	}
+	config.Host, config.Port, config.TLSServerName = throughTunnel(tunnel, config.Host, config.TLSServerName)
+	config.SSHTunnel = nil
+
+	conn, err := adapter.ConnectInstance(ctx, config)
+	if err != nil {
+		tunnel.Close()
+		return nil, err
+	}
+	return &tunneledInstanceConnection{InstanceConnection: conn, tunnel: tunnel}, nil
+}
+
+// tunneledConnection is a database connection through an SSH tunnel, which is closed with it
+type tunneledConnection struct {
+	Connection
+	tunnel *sshTunnel
+}
+
+// IsConnected reports whether both the connection and its tunnel are up.
+func (c *tunneledConnection) IsConnected() bool {
+	return c.tunnel.alive() && c.Connection.IsConnected()
+}
+
+// Close closes the connection and then its tunnel.
+func (c *tunneledConnection) Close() error {
+	err := c.Connection.Close()
+	if tunnelErr := c.tunnel.Close(); err == nil {
+		err = tunnelErr
+	}
+	return err
+}
+
+// tunneledInstanceConnection is an instance connection through an SSH tunnel, which is closed
+// with it
+type tunneledInstanceConnection struct {
+	InstanceConnection
+	tunnel *sshTunnel
+}
+
+// IsConnected reports whether both the connection and its tunnel are up.
+func (c *tunneledInstanceConnection) IsConnected() bool {
+	return c.tunnel.alive() && c.InstanceConnection.IsConnected()
+}
+
+// Close closes the connection and then its tunnel.
+func (c *tunneledInstanceConnection) Close() error {
+	err := c.InstanceConnection.Close()
+	if tunnelErr := c.tunnel.Close(); err == nil {
+		err = tunnelErr
+	}
+	return err
+}
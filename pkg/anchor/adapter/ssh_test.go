@@ -0,0 +1,233 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startEchoServer starts a TCP server that echoes lines, standing in for a database
+func startEchoServer(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener
+}
+
+// startJumpHost starts an SSH server that accepts clientKey and forwards direct-tcpip
+// channels. It returns its address and host key in authorized_keys format.
+func startJumpHost(t *testing.T, clientKey ssh.PublicKey) (string, string) {
+	t.Helper()
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "redb" && string(key.Marshal()) == string(clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveJumpHost(conn, config)
+		}
+	}()
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey()))
+}
+
+func serveJumpHost(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(requests)
+		go func() {
+			defer channel.Close()
+			defer remote.Close()
+			go io.Copy(remote, channel)
+			io.Copy(channel, remote)
+		}()
+	}
+}
+
+func TestSSHTunnelForwardsToDatabase(t *testing.T) {
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	database := startEchoServer(t)
+	jumpAddr, hostKey := startJumpHost(t, clientSigner.PublicKey())
+	jumpHost, jumpPort, _ := net.SplitHostPort(jumpAddr)
+	dbHost, dbPort, _ := net.SplitHostPort(database.Addr().String())
+	port, _ := strconv.Atoi(jumpPort)
+	targetPort, _ := strconv.Atoi(dbPort)
+
+	config := &SSHTunnel{
+		Host:       jumpHost,
+		Port:       port,
+		Username:   "redb",
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		HostKey:    hostKey,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tunnel, err := openSSHTunnel(ctx, config, dbHost, targetPort)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(tunnel.localPort())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Write([]byte("select 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "select 1\n" {
+		t.Fatalf("read %q, %v through the tunnel, want the echo", line, err)
+	}
+
+	if err := tunnel.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if tunnel.alive() {
+		t.Fatal("tunnel alive after close")
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatal("forwarded connection still open after the tunnel was closed")
+	}
+}
+
+func TestSSHTunnelVerifiesHostKey(t *testing.T) {
+	_, clientPriv, _ := ed25519.GenerateKey(rand.Reader)
+	clientSigner, _ := ssh.NewSignerFromKey(clientPriv)
+	block, _ := ssh.MarshalPrivateKey(clientPriv, "")
+	jumpAddr, _ := startJumpHost(t, clientSigner.PublicKey())
+	jumpHost, jumpPort, _ := net.SplitHostPort(jumpAddr)
+	port, _ := strconv.Atoi(jumpPort)
+
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	otherKey, _ := ssh.NewPublicKey(otherPub)
+
+	config := &SSHTunnel{
+		Host:       jumpHost,
+		Port:       port,
+		Username:   "redb",
+		PrivateKey: string(pem.EncodeToMemory(block)),
+	}
+	if _, err := openSSHTunnel(context.Background(), config, "db", 5432); err == nil || !strings.Contains(err.Error(), "host key is required") {
+		t.Fatalf("tunnel without host key = %v, want it refused", err)
+	}
+
+	config.HostKey = string(ssh.MarshalAuthorizedKey(otherKey))
+	if _, err := openSSHTunnel(context.Background(), config, "db", 5432); err == nil {
+		t.Fatal("tunnel to a jump host with another host key succeeded")
+	}
+}
+
+func TestThroughTunnelKeepsServerName(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	tunnel := &sshTunnel{listener: listener}
+
+	host, port, name := throughTunnel(tunnel, "db.internal", "")
+	if host != "127.0.0.1" || port != listener.Addr().(*net.TCPAddr).Port || name != "db.internal" {
+		t.Fatalf("throughTunnel = %s, %d, %s", host, port, name)
+	}
+	if _, _, name := throughTunnel(tunnel, "db.internal", "db.example.com"); name != "db.example.com" {
+		t.Fatalf("explicit server name replaced by %s", name)
+	}
+
+	config := ConnectionConfig{SSL: true, Host: "127.0.0.1", TLSServerName: "db.internal"}
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig == nil || tlsConfig.ServerName != "db.internal" {
+		t.Fatalf("TLS configuration %+v does not verify the database host", tlsConfig)
+	}
+}
@@ -0,0 +1,667 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"golang.org/x/time/rate"
+)
+
+// Throttle wraps a connection so that its operations stay within the rate limits of the
+// database: every statement waits for a query token and a free statement slot, writes wait
+// until their rows fit in the row rate, and the rows returned by reads are charged to the
+// row rate afterwards, so that the following statements wait for them. Operations that
+// cannot start before the deadline of their context fail with ErrRateLimited. Connections
+// without limits are returned as they are.
+func Throttle(conn Connection, limits *RateLimits) Connection {
+	if conn == nil || limits.IsZero() {
+		return conn
+	}
+	return &throttledConnection{Connection: conn, throttle: newThrottle(*limits)}
+}
+
+// throttle enforces rate limits. It is shared by the operators of a connection, so that the
+// limits apply to all of its operations together.
+type throttle struct {
+	limits  RateLimits
+	queries *rate.Limiter // nil when unlimited
+	rows    *rate.Limiter // nil when unlimited
+	slots   chan struct{} // nil when unlimited
+}
+
+func newThrottle(limits RateLimits) *throttle {
+	t := &throttle{limits: limits}
+	if limits.QueriesPerSecond > 0 {
+		t.queries = rate.NewLimiter(rate.Limit(limits.QueriesPerSecond), burst(limits.QueriesPerSecond))
+	}
+	if limits.RowsPerSecond > 0 {
+		t.rows = rate.NewLimiter(rate.Limit(limits.RowsPerSecond), burst(limits.RowsPerSecond))
+	}
+	if limits.MaxConcurrentStatements > 0 {
+		t.slots = make(chan struct{}, limits.MaxConcurrentStatements)
+	}
+	return t
+}
+
+// burst allows one second worth of a rate at once, and at least one
+func burst(perSecond float64) int {
+	if perSecond < 1 {
+		return 1
+	}
+	return int(perSecond)
+}
+
+// begin waits until a statement writing rows can start, and returns the function ending it.
+func (t *throttle) begin(ctx context.Context, rows int) (func(), error) {
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	now := time.Now()
+	if t.queries != nil {
+		r := t.queries.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		delay = r.DelayFrom(now)
+	}
+	rowReservations, rowDelay := t.reserveRows(now, rows)
+	reservations = append(reservations, rowReservations...)
+	if rowDelay > delay {
+		delay = rowDelay
+	}
+
+	if err := t.wait(ctx, delay); err != nil {
+		// Reservations give their tokens back only if the later ones were canceled first, and
+		// only if canceled no later than they were due
+		for i := len(reservations) - 1; i >= 0; i-- {
+			reservations[i].CancelAt(now)
+		}
+		return nil, err
+	}
+
+	if t.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case t.slots <- struct{}{}:
+		return func() { <-t.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// charge takes the rows returned by a read from the row rate, waiting until they fit in it.
+// The rows stay charged when ctx is done first, so that the following statements wait.
+func (t *throttle) charge(ctx context.Context, rows int) {
+	now := time.Now()
+	_, delay := t.reserveRows(now, rows)
+	_ = t.wait(ctx, delay)
+}
+
+// reserveRows reserves rows in the row rate, in reservations of at most its burst, and
+// returns them with the delay until all rows fit.
+func (t *throttle) reserveRows(now time.Time, rows int) ([]*rate.Reservation, time.Duration) {
+	if t.rows == nil || rows <= 0 {
+		return nil, 0
+	}
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	for rows > 0 {
+		n := min(rows, t.rows.Burst())
+		r := t.rows.ReserveN(now, n)
+		reservations = append(reservations, r)
+		delay = r.DelayFrom(now)
+		rows -= n
+	}
+	return reservations, delay
+}
+
+func (t *throttle) wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return fmt.Errorf("%w: the operation would wait %s for the limits of %s", ErrRateLimited, delay.Round(time.Millisecond), t.describe())
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *throttle) describe() string {
+	return fmt.Sprintf("%g rows/s, %g queries/s and %d concurrent statements",
+		t.limits.RowsPerSecond, t.limits.QueriesPerSecond, t.limits.MaxConcurrentStatements)
+}
+
+type throttledConnection struct {
+	Connection
+	throttle *throttle
+}
+
+func (c *throttledConnection) SchemaOperations() SchemaOperator {
+	ops := c.Connection.SchemaOperations()
+	if ops == nil {
+		return nil
+	}
+	return &throttledSchemaOperator{ops: ops, throttle: c.throttle}
+}
+
+func (c *throttledConnection) DataOperations() DataOperator {
+	ops := c.Connection.DataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &throttledDataOperator{ops: ops, throttle: c.throttle}
+}
+
+func (c *throttledConnection) ReplicationOperations() ReplicationOperator {
+	ops := c.Connection.ReplicationOperations()
+	if ops == nil {
+		return nil
+	}
+	return &throttledReplicationOperator{ops: ops, throttle: c.throttle}
+}
+
+func (c *throttledConnection) MetadataOperations() MetadataOperator {
+	ops := c.Connection.MetadataOperations()
+	if ops == nil {
+		return nil
+	}
+	return &throttledMetadataOperator{ops: ops, throttle: c.throttle}
+}
+
+type throttledSchemaOperator struct {
+	ops      SchemaOperator
+	throttle *throttle
+}
+
+func (s *throttledSchemaOperator) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	end, err := s.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return s.ops.DiscoverSchema(ctx)
+}
+
+// DiscoverSchemaIncremental is only reached through DiscoverSchemaSince, which checks that
+// the wrapped operator discovers incrementally.
+func (s *throttledSchemaOperator) DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	end, err := s.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return s.ops.(IncrementalSchemaDiscoverer).DiscoverSchemaIncremental(ctx, previous)
+}
+
+func (s *throttledSchemaOperator) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	end, err := s.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return s.ops.CreateStructure(ctx, model)
+}
+
+func (s *throttledSchemaOperator) ListTables(ctx context.Context) ([]string, error) {
+	end, err := s.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return s.ops.ListTables(ctx)
+}
+
+func (s *throttledSchemaOperator) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	end, err := s.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return s.ops.GetTableSchema(ctx, tableName)
+}
+
+type throttledDataOperator struct {
+	ops      DataOperator
+	throttle *throttle
+}
+
+// read runs a statement returning rows and charges them to the row rate once the statement
+// ended.
+func (d *throttledDataOperator) read(ctx context.Context, fn func() ([]map[string]interface{}, error)) ([]map[string]interface{}, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := fn()
+	end()
+	if err == nil {
+		d.throttle.charge(ctx, len(rows))
+	}
+	return rows, err
+}
+
+// write runs a statement writing rows once they fit in the row rate.
+func (d *throttledDataOperator) write(ctx context.Context, rows int, fn func() (int64, error)) (int64, error) {
+	end, err := d.throttle.begin(ctx, rows)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+	return fn()
+}
+
+func (d *throttledDataOperator) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.read(ctx, func() ([]map[string]interface{}, error) {
+		return d.ops.Fetch(ctx, table, limit)
+	})
+}
+
+func (d *throttledDataOperator) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	return d.read(ctx, func() ([]map[string]interface{}, error) {
+		return d.ops.FetchWithColumns(ctx, table, columns, limit)
+	})
+}
+
+func (d *throttledDataOperator) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return d.write(ctx, len(data), func() (int64, error) {
+		return d.ops.Insert(ctx, table, data)
+	})
+}
+
+// BulkInsert uses the bulk load of the wrapped operator if it has one, so that
+// BulkInsert on a throttled connection behaves like on the connection itself.
+func (d *throttledDataOperator) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return d.write(ctx, len(data), func() (int64, error) {
+		return BulkInsert(ctx, d.ops, table, data)
+	})
+}
+
+func (d *throttledDataOperator) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return d.write(ctx, len(data), func() (int64, error) {
+		return d.ops.Update(ctx, table, data, whereColumns)
+	})
+}
+
+func (d *throttledDataOperator) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	return d.write(ctx, len(data), func() (int64, error) {
+		return d.ops.Upsert(ctx, table, data, uniqueColumns)
+	})
+}
+
+// Delete charges the deleted rows once the statement ended, as their number is only known then.
+func (d *throttledDataOperator) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	n, err := d.write(ctx, 0, func() (int64, error) {
+		return d.ops.Delete(ctx, table, conditions)
+	})
+	if err == nil {
+		d.throttle.charge(ctx, int(n))
+	}
+	return n, err
+}
+
+func (d *throttledDataOperator) Stream(ctx context.Context, params StreamParams) (StreamResult, error) {
+	var result StreamResult
+	_, err := d.read(ctx, func() ([]map[string]interface{}, error) {
+		var err error
+		result, err = d.ops.Stream(ctx, params)
+		return result.Data, err
+	})
+	return result, err
+}
+
+func (d *throttledDataOperator) FetchStream(ctx context.Context, table string, batchSize int) (RowBatchIterator, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	it, err := d.ops.FetchStream(ctx, table, batchSize)
+	end()
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBatchIterator{ctx: ctx, it: it, throttle: d.throttle}, nil
+}
+
+// KeyBounds and FetchKeyRange are only reached through AsKeyRangeReader, which checks
+// that the wrapped operator reads key ranges.
+func (d *throttledDataOperator) KeyBounds(ctx context.Context, table string, keyColumn string) (KeyRange, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return KeyRange{}, err
+	}
+	defer end()
+	return d.ops.(KeyRangeReader).KeyBounds(ctx, table, keyColumn)
+}
+
+func (d *throttledDataOperator) FetchKeyRange(ctx context.Context, table string, keyRange KeyRange, batchSize int) (RowBatchIterator, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	it, err := d.ops.(KeyRangeReader).FetchKeyRange(ctx, table, keyRange, batchSize)
+	end()
+	if err != nil {
+		return nil, err
+	}
+	return &throttledBatchIterator{ctx: ctx, it: it, throttle: d.throttle}, nil
+}
+
+// CreateSandbox, InsertSandbox, FetchSandbox and DropSandbox are only reached through
+// AsSandboxer, which checks that the wrapped operator writes into sandboxes.
+func (d *throttledDataOperator) CreateSandbox(ctx context.Context, sandbox string, tables []string) error {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return d.ops.(Sandboxer).CreateSandbox(ctx, sandbox, tables)
+}
+
+func (d *throttledDataOperator) InsertSandbox(ctx context.Context, sandbox string, table string, data []map[string]interface{}) (int64, error) {
+	return d.write(ctx, len(data), func() (int64, error) {
+		return d.ops.(Sandboxer).InsertSandbox(ctx, sandbox, table, data)
+	})
+}
+
+func (d *throttledDataOperator) FetchSandbox(ctx context.Context, sandbox string, table string, limit int) ([]map[string]interface{}, error) {
+	return d.read(ctx, func() ([]map[string]interface{}, error) {
+		return d.ops.(Sandboxer).FetchSandbox(ctx, sandbox, table, limit)
+	})
+}
+
+func (d *throttledDataOperator) DropSandbox(ctx context.Context, sandbox string) error {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return d.ops.(Sandboxer).DropSandbox(ctx, sandbox)
+}
+
+// ExecuteReadOnlyQuery is only reached through asReadOnlyQueryExecutor, which checks that
+// the wrapped operator runs read-only transactions.
+func (d *throttledDataOperator) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*QueryResult, error) {
+	var result *QueryResult
+	_, err := d.read(ctx, func() ([]map[string]interface{}, error) {
+		var err error
+		result, err = d.ops.(ReadOnlyQueryExecutor).ExecuteReadOnlyQuery(ctx, query, maxRows)
+		if result == nil {
+			return nil, err
+		}
+		return result.Rows, err
+	})
+	return result, err
+}
+
+// SearchVectors is only reached through AsVectorSearcher, which checks that the wrapped
+// operator searches vectors.
+func (d *throttledDataOperator) SearchVectors(ctx context.Context, table string, vector []float32, topK int) ([]VectorMatch, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := d.ops.(VectorSearcher).SearchVectors(ctx, table, vector, topK)
+	end()
+	if err == nil {
+		d.throttle.charge(ctx, len(matches))
+	}
+	return matches, err
+}
+
+// SampleEdges is only reached through AsEdgeSampler, which checks that the wrapped operator
+// samples edges.
+func (d *throttledDataOperator) SampleEdges(ctx context.Context, relationship string, limit int) ([]Edge, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := d.ops.(EdgeSampler).SampleEdges(ctx, relationship, limit)
+	end()
+	if err == nil {
+		d.throttle.charge(ctx, len(edges))
+	}
+	return edges, err
+}
+
+func (d *throttledDataOperator) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.ops.ExecuteQuery(ctx, query, args...)
+	end()
+	if err == nil {
+		d.throttle.charge(ctx, len(rows))
+	}
+	return rows, err
+}
+
+func (d *throttledDataOperator) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+	return d.ops.ExecuteCountQuery(ctx, query)
+}
+
+func (d *throttledDataOperator) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer end()
+	return d.ops.GetRowCount(ctx, table, whereClause)
+}
+
+func (d *throttledDataOperator) Wipe(ctx context.Context) error {
+	end, err := d.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return d.ops.Wipe(ctx)
+}
+
+// throttledBatchIterator runs every batch as a statement under the limits of its connection.
+type throttledBatchIterator struct {
+	ctx      context.Context
+	it       RowBatchIterator
+	throttle *throttle
+}
+
+func (i *throttledBatchIterator) Next() ([]map[string]interface{}, error) {
+	end, err := i.throttle.begin(i.ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := i.it.Next()
+	end()
+	if err == nil {
+		i.throttle.charge(i.ctx, len(rows))
+	}
+	return rows, err
+}
+
+func (i *throttledBatchIterator) Close() error {
+	return i.it.Close()
+}
+
+// throttledReplicationOperator limits the statements of replication. Replication streams
+// themselves are not limited once connected; applying their events is.
+type throttledReplicationOperator struct {
+	ops      ReplicationOperator
+	throttle *throttle
+}
+
+func (r *throttledReplicationOperator) IsSupported() bool {
+	return r.ops.IsSupported()
+}
+
+func (r *throttledReplicationOperator) GetSupportedMechanisms() []string {
+	return r.ops.GetSupportedMechanisms()
+}
+
+func (r *throttledReplicationOperator) CheckPrerequisites(ctx context.Context) error {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return r.ops.CheckPrerequisites(ctx)
+}
+
+func (r *throttledReplicationOperator) Connect(ctx context.Context, config ReplicationConfig) (ReplicationSource, error) {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return r.ops.Connect(ctx, config)
+}
+
+func (r *throttledReplicationOperator) GetStatus(ctx context.Context) (map[string]interface{}, error) {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return r.ops.GetStatus(ctx)
+}
+
+func (r *throttledReplicationOperator) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return r.ops.GetLag(ctx)
+}
+
+func (r *throttledReplicationOperator) ListSlots(ctx context.Context) ([]map[string]interface{}, error) {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return r.ops.ListSlots(ctx)
+}
+
+func (r *throttledReplicationOperator) DropSlot(ctx context.Context, slotName string) error {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return r.ops.DropSlot(ctx, slotName)
+}
+
+func (r *throttledReplicationOperator) ListPublications(ctx context.Context) ([]map[string]interface{}, error) {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return r.ops.ListPublications(ctx)
+}
+
+func (r *throttledReplicationOperator) DropPublication(ctx context.Context, publicationName string) error {
+	end, err := r.throttle.begin(ctx, 0)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return r.ops.DropPublication(ctx, publicationName)
+}
+
+func (r *throttledReplicationOperator) ParseEvent(ctx context.Context, rawEvent map[string]interface{}) (*CDCEvent, error) {
+	return r.ops.ParseEvent(ctx, rawEvent)
+}
+
+func (r *throttledReplicationOperator) ApplyCDCEvent(ctx context.Context, event *CDCEvent) error {
+	end, err := r.throttle.begin(ctx, 1)
+	if err != nil {
+		return err
+	}
+	defer end()
+	return r.ops.ApplyCDCEvent(ctx, event)
+}
+
+func (r *throttledReplicationOperator) TransformData(ctx context.Context, data map[string]interface{}, rules []TransformationRule, transformationServiceEndpoint string) (map[string]interface{}, error) {
+	return r.ops.TransformData(ctx, data, rules, transformationServiceEndpoint)
+}
+
+type throttledMetadataOperator struct {
+	ops      MetadataOperator
+	throttle *throttle
+}
+
+func (m *throttledMetadataOperator) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return m.ops.CollectDatabaseMetadata(ctx)
+}
+
+func (m *throttledMetadataOperator) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return m.ops.CollectInstanceMetadata(ctx)
+}
+
+func (m *throttledMetadataOperator) GetVersion(ctx context.Context) (string, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	defer end()
+	return m.ops.GetVersion(ctx)
+}
+
+func (m *throttledMetadataOperator) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return "", err
+	}
+	defer end()
+	return m.ops.GetUniqueIdentifier(ctx)
+}
+
+func (m *throttledMetadataOperator) GetDatabaseSize(ctx context.Context) (int64, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+	return m.ops.GetDatabaseSize(ctx)
+}
+
+func (m *throttledMetadataOperator) GetTableCount(ctx context.Context) (int, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer end()
+	return m.ops.GetTableCount(ctx)
+}
+
+func (m *throttledMetadataOperator) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	end, err := m.throttle.begin(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	return m.ops.ExecuteCommand(ctx, command)
+}
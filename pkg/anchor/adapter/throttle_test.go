@@ -0,0 +1,129 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// throttleTestConnection is a connection with only data operations
+type throttleTestConnection struct {
+	Connection
+	data *throttleTestData
+}
+
+func (c *throttleTestConnection) DataOperations() DataOperator { return c.data }
+
+// throttleTestData returns rows rows for every fetch and tracks the statements running at once
+type throttleTestData struct {
+	DataOperator
+	rows    int
+	delay   time.Duration
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (d *throttleTestData) statement() {
+	n := d.running.Add(1)
+	defer d.running.Add(-1)
+	for {
+		peak := d.peak.Load()
+		if n <= peak || d.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(d.delay)
+}
+
+func (d *throttleTestData) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	d.statement()
+	return make([]map[string]interface{}, d.rows), nil
+}
+
+func (d *throttleTestData) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	d.statement()
+	return int64(len(data)), nil
+}
+
+func TestThrottleWithoutLimitsReturnsConnection(t *testing.T) {
+	conn := &throttleTestConnection{data: &throttleTestData{}}
+	if Throttle(conn, nil) != Connection(conn) || Throttle(conn, &RateLimits{}) != Connection(conn) {
+		t.Fatal("connection without limits was wrapped")
+	}
+}
+
+func TestThrottleLimitsConcurrentStatements(t *testing.T) {
+	data := &throttleTestData{delay: 20 * time.Millisecond}
+	ops := Throttle(&throttleTestConnection{data: data}, &RateLimits{MaxConcurrentStatements: 2}).DataOperations()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ops.Insert(context.Background(), "orders", make([]map[string]interface{}, 1)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := data.peak.Load(); peak != 2 {
+		t.Fatalf("%d statements ran at once, want 2", peak)
+	}
+}
+
+func TestThrottleChargesRowsRead(t *testing.T) {
+	data := &throttleTestData{rows: 50}
+	ops := Throttle(&throttleTestConnection{data: data}, &RateLimits{RowsPerSecond: 100}).DataOperations()
+
+	// The first 100 rows fit in the burst, the next 50 wait for half a second
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := ops.Fetch(context.Background(), "orders", 50); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("150 rows at 100 rows/s were read in %s", elapsed)
+	}
+}
+
+func TestThrottleRejectsWritesPastDeadline(t *testing.T) {
+	data := &throttleTestData{}
+	ops := Throttle(&throttleTestConnection{data: data}, &RateLimits{RowsPerSecond: 100}).DataOperations()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := ops.Insert(ctx, "orders", make([]map[string]interface{}, 300))
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("insert of 300 rows at 100 rows/s within 100ms: %v, want ErrRateLimited", err)
+	}
+
+	// The rejected rows were given back
+	if _, err := ops.Insert(ctx, "orders", make([]map[string]interface{}, 100)); err != nil {
+		t.Fatalf("insert within the burst after a rejected insert: %v", err)
+	}
+}
+
+func TestThrottledOperatorKeepsOptionalInterfaces(t *testing.T) {
+	ops := Throttle(&throttleTestConnection{data: &throttleTestData{}}, &RateLimits{QueriesPerSecond: 10}).DataOperations()
+	if _, ok := AsKeyRangeReader(ops); ok {
+		t.Fatal("throttled operator reads key ranges although the wrapped one does not")
+	}
+	if _, ok := asReadOnlyQueryExecutor(ops); ok {
+		t.Fatal("throttled operator runs read-only queries although the wrapped one does not")
+	}
+	if _, ok := AsSandboxer(ops); ok {
+		t.Fatal("throttled operator writes into sandboxes although the wrapped one does not")
+	}
+	if _, ok := AsVectorSearcher(ops); ok {
+		t.Fatal("throttled operator searches vectors although the wrapped one does not")
+	}
+	if _, ok := AsEdgeSampler(ops); ok {
+		t.Fatal("throttled operator samples edges although the wrapped one does not")
+	}
+}
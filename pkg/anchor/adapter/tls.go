@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"crypto/tls"
+
+	"github.com/redbco/redb-open/pkg/tlsconfig"
+)
+
+// TLSConfig returns the TLS configuration of the connection, or nil if SSL is disabled or
+// none of the TLS fields is set, in which case adapters fall back to the SSL fields.
+// The server certificate is verified against TLSCABundle, or the system pool if it is
+// empty, and TLSClientCert and TLSClientKey are presented for mutual TLS. Without
+// TLSVerifyMode the verification follows SSLMode and SSLRejectUnauthorized. The host name of the
+// certificate is checked against TLSServerName, or Host if it is empty.
+func (c ConnectionConfig) TLSConfig() (*tls.Config, error) {
+	if !c.SSL || !hasTLSOptions(c.TLSClientCert, c.TLSClientKey, c.TLSCABundle, c.TLSVerifyMode, c.TLSServerName) {
+		return nil, nil
+	}
+	return tlsconfig.Build(tlsconfig.Options{
+		ServerName: serverName(c.TLSServerName, c.Host),
+		ClientCert: c.TLSClientCert,
+		ClientKey:  c.TLSClientKey,
+		CABundle:   c.TLSCABundle,
+		VerifyMode: verifyMode(c.TLSVerifyMode, c.SSLMode, c.SSLRejectUnauthorized, c.TLSCABundle),
+	})
+}
+
+// TLSConfig returns the TLS configuration of the instance connection, or nil if SSL is
+// disabled or none of the TLS fields is set.
+func (c InstanceConfig) TLSConfig() (*tls.Config, error) {
+	if !c.SSL || !hasTLSOptions(c.TLSClientCert, c.TLSClientKey, c.TLSCABundle, c.TLSVerifyMode, c.TLSServerName) {
+		return nil, nil
+	}
+	return tlsconfig.Build(tlsconfig.Options{
+		ServerName: serverName(c.TLSServerName, c.Host),
+		ClientCert: c.TLSClientCert,
+		ClientKey:  c.TLSClientKey,
+		CABundle:   c.TLSCABundle,
+		VerifyMode: verifyMode(c.TLSVerifyMode, c.SSLMode, c.SSLRejectUnauthorized, c.TLSCABundle),
+	})
+}
+
+// hasTLSOptions reports whether any of the TLS fields of a configuration is set
+func hasTLSOptions(values ...string) bool {
+	for _, value := range values {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyMode returns the explicit verification mode, or the one of the SSL mode
+func verifyMode(mode, sslMode string, rejectUnauthorized *bool, caBundle string) string {
+	if mode != "" {
+		return mode
+	}
+	return tlsconfig.FromSSLMode(sslMode, rejectUnauthorized, caBundle != "")
+}
+
+// serverName returns the explicit TLS server name, or the host
+func serverName(name, host string) string {
+	if name != "" {
+		return name
+	}
+	return host
+}
@@ -95,11 +95,107 @@ func NewUnsupportedReplicationOperator(dbType dbcapabilities.DatabaseType) Repli
 	return &UnsupportedReplicationOperator{dbType: dbType}
 }
 
+// UnsupportedDataOperator is a nil object pattern for connections that don't support data operations.
+type UnsupportedDataOperator struct {
+	dbType dbcapabilities.DatabaseType
+}
+
+func (u *UnsupportedDataOperator) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "fetch", "")
+}
+
+func (u *UnsupportedDataOperator) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "fetch", "")
+}
+
+func (u *UnsupportedDataOperator) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "insert", "")
+}
+
+func (u *UnsupportedDataOperator) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "update", "")
+}
+
+func (u *UnsupportedDataOperator) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "upsert", "")
+}
+
+func (u *UnsupportedDataOperator) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "delete", "")
+}
+
+func (u *UnsupportedDataOperator) Stream(ctx context.Context, params StreamParams) (StreamResult, error) {
+	return StreamResult{}, NewUnsupportedOperationError(u.dbType, "stream", "")
+}
+
+func (u *UnsupportedDataOperator) FetchStream(ctx context.Context, table string, batchSize int) (RowBatchIterator, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "fetch stream", "")
+}
+
+func (u *UnsupportedDataOperator) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "execute query", "")
+}
+
+func (u *UnsupportedDataOperator) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "execute count query", "")
+}
+
+func (u *UnsupportedDataOperator) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	return 0, false, NewUnsupportedOperationError(u.dbType, "get row count", "")
+}
+
+func (u *UnsupportedDataOperator) Wipe(ctx context.Context) error {
+	return NewUnsupportedOperationError(u.dbType, "wipe", "")
+}
+
+// NewUnsupportedDataOperator creates a new unsupported data operator.
+func NewUnsupportedDataOperator(dbType dbcapabilities.DatabaseType) DataOperator {
+	return &UnsupportedDataOperator{dbType: dbType}
+}
+
+// UnsupportedMetadataOperator is a nil object pattern for connections that don't support metadata operations.
+type UnsupportedMetadataOperator struct {
+	dbType dbcapabilities.DatabaseType
+}
+
+func (u *UnsupportedMetadataOperator) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "collect database metadata", "")
+}
+
+func (u *UnsupportedMetadataOperator) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "collect instance metadata", "")
+}
+
+func (u *UnsupportedMetadataOperator) GetVersion(ctx context.Context) (string, error) {
+	return "", NewUnsupportedOperationError(u.dbType, "get version", "")
+}
+
+func (u *UnsupportedMetadataOperator) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return "", NewUnsupportedOperationError(u.dbType, "get unique identifier", "")
+}
+
+func (u *UnsupportedMetadataOperator) GetDatabaseSize(ctx context.Context) (int64, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "get database size", "")
+}
+
+func (u *UnsupportedMetadataOperator) GetTableCount(ctx context.Context) (int, error) {
+	return 0, NewUnsupportedOperationError(u.dbType, "get table count", "")
+}
+
+func (u *UnsupportedMetadataOperator) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, NewUnsupportedOperationError(u.dbType, "execute command", "")
+}
+
+// NewUnsupportedMetadataOperator creates a new unsupported metadata operator.
+func NewUnsupportedMetadataOperator(dbType dbcapabilities.DatabaseType) MetadataOperator {
+	return &UnsupportedMetadataOperator{dbType: dbType}
+}
+
 // IsUnsupportedOperator checks if an operator is an unsupported operator.
 // This can be used to detect when an operation is not available.
 func IsUnsupportedOperator(op interface{}) bool {
 	switch op.(type) {
-	case *UnsupportedSchemaOperator, *UnsupportedReplicationOperator:
+	case *UnsupportedSchemaOperator, *UnsupportedReplicationOperator, *UnsupportedDataOperator, *UnsupportedMetadataOperator:
 		return true
 	default:
 		return false
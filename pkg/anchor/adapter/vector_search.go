@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxVectorSearchTopK is the most neighbors a vector search returns.
+const MaxVectorSearchTopK = 100
+
+// VectorMatch is a neighbor found by a vector search.
+type VectorMatch struct {
+	ID string
+	// Score is the distance or similarity of the neighbor as the database reports it,
+	// which depends on the metric of the index; matches are ordered nearest first.
+	Score float64
+}
+
+// VectorSearcher is implemented by data operators that can search the nearest neighbors
+// of a vector in a collection or index of a vector store.
+type VectorSearcher interface {
+	// SearchVectors returns the topK nearest neighbors of a vector in a table, using the
+	// vectors, index and metric the table was created with.
+	SearchVectors(ctx context.Context, table string, vector []float32, topK int) ([]VectorMatch, error)
+}
+
+// ValidateVectorSearch returns an error if the arguments of a vector search are invalid.
+func ValidateVectorSearch(vector []float32, topK int) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("%w: vector is empty", ErrInvalidQuery)
+	}
+	if topK < 1 || topK > MaxVectorSearchTopK {
+		return fmt.Errorf("%w: top k must be between 1 and %d, got %d", ErrInvalidQuery, MaxVectorSearchTopK, topK)
+	}
+	return nil
+}
+
+// AsVectorSearcher returns the vector searcher of a data operator, if it has one.
+func AsVectorSearcher(ops DataOperator) (VectorSearcher, bool) {
+	// The instrumented, routed and throttled operators always have the method; they search
+	// vectors only if the operator they wrap does
+	if instrumented, ok := ops.(*instrumentedDataOperator); ok {
+		if _, ok := AsVectorSearcher(instrumented.ops); !ok {
+			return nil, false
+		}
+		return instrumented, true
+	}
+	if throttled, ok := ops.(*throttledDataOperator); ok {
+		if _, ok := AsVectorSearcher(throttled.ops); !ok {
+			return nil, false
+		}
+		return throttled, true
+	}
+	if routed, ok := ops.(*routedDataOperator); ok {
+		if _, ok := AsVectorSearcher(routed.primary); !ok {
+			return nil, false
+		}
+		return routed, true
+	}
+	searcher, ok := ops.(VectorSearcher)
+	return searcher, ok
+}
@@ -0,0 +1,130 @@
+// Package blobstore stores large artifacts, such as schema snapshots, exports and diagnostics
+// bundles, outside of the internal PostgreSQL database: on local disk, or in an S3-compatible
+// object store (Amazon S3, MinIO, or Google Cloud Storage through its XML API).
+//
+// Blobs are addressed by slash-separated keys and can be handed out as signed URLs, which
+// allow downloading a blob without credentials until they expire:
+//
+//	store, err := blobstore.New(blobstore.Config{Backend: blobstore.BackendLocal, Path: "./blobs"})
+//	err = store.Put(ctx, "exports/tenant/export.json", reader, size, "application/json")
+//	url, err := store.SignedURL(ctx, "exports/tenant/export.json", 15*time.Minute)
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Backends
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+	BackendGCS   = "gcs"
+)
+
+// MaxSignedURLExpiry is the longest a signed URL can be valid, the limit of S3 presigned URLs
+const MaxSignedURLExpiry = 7 * 24 * time.Hour
+
+// ErrNotFound is returned for blobs that do not exist
+var ErrNotFound = errors.New("blob not found")
+
+// Info describes a stored blob
+type Info struct {
+	Key         string
+	Size        int64
+	ContentType string
+	Modified    time.Time
+}
+
+// Store stores blobs by key
+type Store interface {
+	// Put stores a blob, replacing any blob with the same key. A negative size reads the
+	// blob to the end before storing it.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens a blob for reading; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Info, error)
+
+	// Stat returns the description of a blob.
+	Stat(ctx context.Context, key string) (*Info, error)
+
+	// Delete removes a blob. Deleting a blob that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the blobs whose keys start with a prefix.
+	List(ctx context.Context, prefix string) ([]Info, error)
+
+	// SignedURL returns a URL the blob can be downloaded from without credentials until
+	// the expiry has passed.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// Config configures a store
+type Config struct {
+	Backend string // "local", "s3" or "gcs"
+
+	// Local disk
+	Path          string // Directory the blobs are stored in
+	PublicURL     string // URL of the endpoint serving signed downloads of local blobs
+	URLSigningKey string // Key signing download URLs of local blobs
+
+	// S3-compatible object stores; GCS is used through its XML API with HMAC keys
+	Bucket          string
+	Region          string // Defaults to us-east-1, or auto for GCS
+	Endpoint        string // Endpoint of S3-compatible stores other than AWS, e.g. https://minio:9000
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // Prefix of all keys in the bucket
+	PathStyle       bool   // Address the bucket in the path rather than the host name
+}
+
+// New creates the store of a configuration
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", BackendLocal:
+		return NewLocalStore(cfg.Path, cfg.PublicURL, cfg.URLSigningKey)
+	case BackendS3:
+		return NewS3Store(cfg)
+	case BackendGCS:
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+		cfg.PathStyle = true
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unknown blob store backend %q, expected %q, %q or %q", cfg.Backend, BackendLocal, BackendS3, BackendGCS)
+	}
+}
+
+// ValidateKey returns an error if a key is empty, absolute, or escapes its prefix
+func ValidateKey(key string) error {
+	if key == "" {
+		return errors.New("blob key is empty")
+	}
+	if strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	if cleaned := path.Clean(key); cleaned != key || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// clampExpiry bounds the expiry of a signed URL
+func clampExpiry(expiry time.Duration) (time.Duration, error) {
+	if expiry <= 0 {
+		return 0, errors.New("signed URL expiry must be positive")
+	}
+	if expiry > MaxSignedURLExpiry {
+		return MaxSignedURLExpiry, nil
+	}
+	return expiry, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/redbco/redb-open/pkg/keyring"
@@ -100,11 +101,25 @@ func FromProductionConfigWithUser(databaseName, databaseUser string) (PostgreSQL
 		Port:              5432,
 		Database:          dbName,
 		SSLMode:           "disable",
-		MaxConnections:    10,
+		MaxConnections:    maxConnectionsFromEnv(10),
 		ConnectionTimeout: 5 * time.Second,
 	}, nil
 }
 
+// maxConnectionsFromEnv returns the connection pool size set by the supervisor in
+// REDB_DATABASE_MAX_CONNECTIONS, e.g. reduced for the edge profile, or the default
+func maxConnectionsFromEnv(defaultMaxConnections int32) int32 {
+	value := os.Getenv("REDB_DATABASE_MAX_CONNECTIONS")
+	if value == "" {
+		return defaultMaxConnections
+	}
+	maxConnections, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || maxConnections <= 0 {
+		return defaultMaxConnections
+	}
+	return int32(maxConnections)
+}
+
 // DatabaseCredentialsManager provides access to database credentials
 type DatabaseCredentialsManager struct {
 	keyringManager *keyring.KeyringManager
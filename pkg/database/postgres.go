@@ -140,7 +140,7 @@ func FromGlobalConfig(cfg *config.Config) PostgreSQLConfig {
 		Port:              5432,
 		Database:          dbName,
 		SSLMode:           "disable",
-		MaxConnections:    40,
+		MaxConnections:    maxConnectionsFromEnv(40),
 		ConnectionTimeout: 5 * time.Second,
 	}
 }
@@ -1,6 +1,9 @@
 package dbcapabilities
 
-import "strings"
+import (
+	"strings"
+	"sync"
+)
 
 // DatabaseType is the canonical identifier for a database technology supported by reDB.
 // Use these constants to look up capability information.
@@ -19,6 +22,7 @@ const (
 	CockroachDB DatabaseType = "cockroach"
 	DuckDB      DatabaseType = "duckdb"
 	HANA        DatabaseType = "hana"
+	SQLite      DatabaseType = "sqlite"
 
 	// NoSQL / Other paradigms
 	Cassandra     DatabaseType = "cassandra"
@@ -43,7 +47,8 @@ const (
 	LanceDB  DatabaseType = "lancedb"
 
 	// Other
-	EdgeDB DatabaseType = "edgedb"
+	EdgeDB     DatabaseType = "edgedb"
+	JDBCBridge DatabaseType = "jdbc_bridge"
 
 	// Object Storage
 	S3        DatabaseType = "s3"
@@ -67,6 +72,17 @@ const (
 	Databricks  DatabaseType = "databricks"
 	Druid       DatabaseType = "druid"
 	ApachePinot DatabaseType = "apachepinot"
+
+	// File Sources
+	File DatabaseType = "file"
+
+	// Spreadsheet Applications
+	GoogleSheets DatabaseType = "googlesheets"
+	Airtable     DatabaseType = "airtable"
+
+	// CRM Applications
+	Salesforce DatabaseType = "salesforce"
+	HubSpot    DatabaseType = "hubspot"
 )
 
 // DataParadigm enumerates the primary data storage paradigms a database supports.
@@ -117,6 +133,15 @@ type Capability struct {
 	SupportsCDC   bool     `json:"supportsCDC"`
 	CDCMechanisms []string `json:"cdcMechanisms,omitempty"`
 
+	// Whether rows can be loaded with a native bulk load primitive instead of row inserts.
+	SupportsBulkLoad   bool     `json:"supportsBulkLoad"`
+	BulkLoadMechanisms []string `json:"bulkLoadMechanisms,omitempty"`
+
+	// Whether ad-hoc queries and commands can be passed through to the database, and the
+	// language they are written in, e.g. "sql".
+	SupportsQueryPassthrough bool   `json:"supportsQueryPassthrough"`
+	QueryLanguage            string `json:"queryLanguage,omitempty"`
+
 	// Whether the instance has a unique identifier
 	HasUniqueIdentifier bool `json:"hasUniqueIdentifier"`
 
@@ -143,9 +168,14 @@ type Capability struct {
 
 	// Common aliases (directory names, drivers, env labels) that map to this database.
 	Aliases []string `json:"aliases,omitempty"`
+
+	// Features services adapt their behavior to (transactions, upserts, identifiers, types).
+	Features FeatureMatrix `json:"features"`
 }
 
-// All is a registry of capabilities keyed by the canonical database ID.
+// All is a registry of capabilities keyed by the canonical database ID. Databases registered
+// or overridden at runtime (see Register and Override) change it, so read it through Get
+// once the process may do so.
 var All = map[DatabaseType]Capability{
 	PostgreSQL: {
 		Name:                     "PostgreSQL",
@@ -154,6 +184,10 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"postgres"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"logical_decoding", "wal2json", "pgoutput"},
+		SupportsBulkLoad:         true,
+		BulkLoadMechanisms:       []string{"copy"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: system_identifier from pg_control_system().
 		SupportsClustering:       false,
 		SupportedVendors:         []string{"custom", "aws-rds", "aws-aurora", "azure-database", "gcp-cloudsql", "supabase", "heroku-postgres"},
@@ -163,6 +197,15 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"postgresql", "pgsql"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteDoubleLower,
+			TypeSystem:           TypeSystemPostgreSQL,
+			MinVersions:          postgresMinVersions,
+		},
 	},
 	MySQL: {
 		Name:                     "MySQL",
@@ -171,6 +214,10 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"mysql"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"binlog"},
+		SupportsBulkLoad:         true,
+		BulkLoadMechanisms:       []string{"load_data_local_infile"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: @@server_uuid.
 		SupportsClustering:       false,
 		SupportedVendors:         []string{"custom", "aws-rds", "aws-aurora", "azure-database", "gcp-cloudsql"},
@@ -180,6 +227,21 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"aurora-mysql"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  64,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemMySQL,
+			MinVersions: map[VersionedFeature]string{
+				FeatureGeneratedColumns:   "5.7",
+				FeatureLogicalReplication: "5.1.5",
+				FeatureDDLReplication:     "5.1.5",
+				FeatureJSONType:           "5.7.8",
+				FeatureJSONFunctions:      "5.7.8",
+			},
+		},
 	},
 	MariaDB: {
 		Name:                     "MariaDB",
@@ -188,6 +250,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"mysql"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"binlog"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: @@server_uuid.
 		SupportsClustering:       false,
 		SupportedVendors:         []string{"custom", "mariadb-corporation", "aws-rds", "azure-database"},
@@ -196,6 +260,21 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "mysql://{username}:{password}@{host}:{port}/{database}?tls={tls}",
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  64,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemMySQL,
+			MinVersions: map[VersionedFeature]string{
+				FeatureGeneratedColumns:   "5.2",
+				FeatureLogicalReplication: "5.1",
+				FeatureDDLReplication:     "5.1",
+				FeatureJSONType:           "10.2.7",
+				FeatureJSONFunctions:      "10.2.3",
+			},
+		},
 	},
 	SQLServer: {
 		Name:                     "Microsoft SQL Server",
@@ -204,6 +283,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"master"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"cdc", "change_tracking"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: SERVERPROPERTY('ServerGuid').
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active", "active-passive"},
@@ -214,6 +295,19 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"sqlserver", "mssql", "azure-sql"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  128,
+			IdentifierQuoting:    quoteBrackets,
+			TypeSystem:           TypeSystemTSQL,
+			MinVersions: map[VersionedFeature]string{
+				FeatureGeneratedColumns: "8",
+				FeatureIdentityColumns:  "8",
+				FeatureJSONFunctions:    "13",
+			},
+		},
 	},
 	Oracle: {
 		Name:                     "Oracle Database",
@@ -222,6 +316,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"CDB$ROOT"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"logminer", "goldengate"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: DBID.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active", "active-passive"},
@@ -231,6 +327,20 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "oracle://{username}:{password}@{host}:{port}/{database}?server={server}&ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  128,
+			IdentifierQuoting:    quoteDoubleUpper,
+			TypeSystem:           TypeSystemOracle,
+			MinVersions: map[VersionedFeature]string{
+				FeatureGeneratedColumns: "11.1",
+				FeatureIdentityColumns:  "12.1",
+				FeatureJSONType:         "21",
+				FeatureJSONFunctions:    "12.1",
+			},
+		},
 	},
 	TiDB: {
 		Name:                     "TiDB",
@@ -239,6 +349,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"INFORMATION_SCHEMA", "mysql"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"tidb-binlog", "changefeed"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: CLUSTER_ID() function or pd/cluster_id from Placement Driver (PD) API.
 		SupportsClustering:       true, // TiDB is natively distributed, supporting active-active SQL layer with TiKV storage nodes.
 		ClusteringMechanisms:     []string{"active-active"},
@@ -249,6 +361,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"tidb", "pingcap-tidb"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  64,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemMySQL,
+		},
 	},
 	ClickHouse: {
 		Name:                     "ClickHouse",
@@ -256,6 +376,8 @@ var All = map[DatabaseType]Capability{
 		HasSystemDatabase:        true,
 		SystemDatabases:          []string{"system"},
 		SupportsCDC:              false,
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: server UUID.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active", "active-passive"},
@@ -265,6 +387,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "clickhouse://{username}:{password}@{host}:{port}/{database}?secure={secure}&compress={compress}",
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemClickHouse,
+		},
 	},
 	DB2: {
 		Name:                     "IBM Db2",
@@ -273,6 +402,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"SYSIBM"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"ibm-cdc"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: DBID.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active", "active-passive"},
@@ -283,6 +414,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"ibm-db2"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  128,
+			IdentifierQuoting:    quoteDoubleUpper,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	CockroachDB: {
 		Name:                     "CockroachDB",
@@ -291,6 +430,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"system"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"changefeed"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: cluster_id.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -301,6 +442,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"cockroachdb"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			IdentifierQuoting:    quoteDoubleLower,
+			TypeSystem:           TypeSystemPostgreSQL,
+		},
 	},
 	Cassandra: {
 		Name:                     "Apache Cassandra",
@@ -309,6 +457,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"system"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"commitlog-cdc"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "cql",
 		HasUniqueIdentifier:      true, // Unique ID: host_id.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -318,6 +468,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "cassandra://{username}:{password}@{host}:{port}/{database}?consistency={consistency}&ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmWideColumn, ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  48,
+			IdentifierQuoting:    quoteDoubleLower,
+			TypeSystem:           TypeSystemCQL,
+		},
 	},
 	DynamoDB: {
 		Name:                     "Amazon DynamoDB",
@@ -333,6 +491,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "dynamodb://{username}:{password}@{host}?endpoint={endpoint}&table={table}",
 		Paradigms:                []DataParadigm{ParadigmKeyValue, ParadigmWideColumn},
 		PrimaryContainers:        []PrimaryContainer{ContainerKeyValuePair},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	MongoDB: {
 		Name:                     "MongoDB",
@@ -350,6 +516,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "mongodb://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmDocument},
 		PrimaryContainers:        []PrimaryContainer{ContainerCollection},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	Redis: {
 		Name:                     "Redis",
@@ -365,6 +538,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "redis://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmKeyValue, ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerKeyValuePair},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemUntyped,
+		},
 	},
 	Neo4j: {
 		Name:                     "Neo4j",
@@ -372,6 +552,8 @@ var All = map[DatabaseType]Capability{
 		HasSystemDatabase:        true,
 		SystemDatabases:          []string{"system"},
 		SupportsCDC:              false,
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "cypher",
 		HasUniqueIdentifier:      true, // Unique ID: DatabaseType.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -381,6 +563,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "neo4j://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmGraph},
 		PrimaryContainers:        []PrimaryContainer{ContainerNode, ContainerRelationship},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemGraph,
+		},
 	},
 	Elasticsearch: {
 		Name:                     "Elasticsearch",
@@ -396,6 +585,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "elasticsearch://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmSearchIndex},
 		PrimaryContainers:        []PrimaryContainer{ContainerSearchDocument},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	OpenSearch: {
 		Name:                     "OpenSearch",
@@ -412,6 +609,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmSearchIndex},
 		PrimaryContainers:        []PrimaryContainer{ContainerSearchDocument},
 		Aliases:                  []string{"opensearch", "aws-opensearch"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	Solr: {
 		Name:                     "Apache Solr",
@@ -428,6 +633,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmSearchIndex},
 		PrimaryContainers:        []PrimaryContainer{ContainerSearchDocument},
 		Aliases:                  []string{"solr", "apache-solr"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	CosmosDB: {
 		Name:                     "Azure Cosmos DB",
@@ -443,6 +655,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "cosmosdb://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmDocument, ParadigmKeyValue, ParadigmGraph},
 		PrimaryContainers:        []PrimaryContainer{ContainerCollection, ContainerNode, ContainerRelationship},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 	Snowflake: {
 		Name:                     "Snowflake",
@@ -451,6 +671,10 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"SNOWFLAKE"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"streams"},
+		SupportsBulkLoad:         true,
+		BulkLoadMechanisms:       []string{"put_copy_into"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: ACCOUNT_ID.
 		SupportsClustering:       false,
 		SupportedVendors:         []string{"snowflake"},
@@ -459,6 +683,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "snowflake://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteDoubleUpper,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	Iceberg: {
 		Name:                     "Apache Iceberg",
@@ -475,6 +707,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar, ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"apache-iceberg"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	Milvus: {
 		Name:                     "Milvus",
@@ -490,6 +729,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "milvus://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmVector},
 		PrimaryContainers:        []PrimaryContainer{ContainerEmbedding},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemVector,
+		},
 	},
 	Weaviate: {
 		Name:                     "Weaviate",
@@ -505,6 +752,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "weaviate://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmVector},
 		PrimaryContainers:        []PrimaryContainer{ContainerEmbedding},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemVector,
+		},
 	},
 	Pinecone: {
 		Name:                     "Pinecone",
@@ -519,6 +773,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "pinecone://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmVector},
 		PrimaryContainers:        []PrimaryContainer{ContainerEmbedding},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  45,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemVector,
+		},
 	},
 	Chroma: {
 		Name:                     "Chroma",
@@ -533,6 +795,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "chroma://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmVector},
 		PrimaryContainers:        []PrimaryContainer{ContainerEmbedding},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemVector,
+		},
 	},
 	LanceDB: {
 		Name:                     "LanceDB",
@@ -547,6 +817,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "lancedb://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmVector},
 		PrimaryContainers:        []PrimaryContainer{ContainerEmbedding},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemVector,
+		},
 	},
 	DuckDB: {
 		Name:                     "DuckDB",
@@ -561,6 +838,13 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "duckdb://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	HANA: {
 		Name:                     "SAP HANA",
@@ -569,6 +853,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"SYSTEMDB"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"sda", "sdi", "triggers"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true,
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -579,6 +865,42 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational, ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"sap-hana", "hdb", "saphana"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  127,
+			IdentifierQuoting:    quoteDoubleUpper,
+			TypeSystem:           TypeSystemSQL,
+		},
+	},
+	SQLite: {
+		Name:                     "SQLite",
+		ID:                       SQLite,
+		HasSystemDatabase:        false,
+		SupportsCDC:              false,
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
+		HasUniqueIdentifier:      false,
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"custom"},
+		DefaultPort:              0,
+		DefaultSSLPort:           0,
+		ConnectionStringTemplate: "sqlite://{database}",
+		Paradigms:                []DataParadigm{ParadigmRelational},
+		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Aliases:                  []string{"sqlite3"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQLite,
+			MinVersions: map[VersionedFeature]string{
+				FeatureGeneratedColumns: "3.31",
+				FeatureJSONFunctions:    "3.38",
+			},
+		},
 	},
 	EdgeDB: {
 		Name:                     "EdgeDB",
@@ -595,6 +917,35 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmRelational, ParadigmGraph},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable, ContainerNode, ContainerRelationship},
 		Aliases:                  []string{"gel", "geldata"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemGraph,
+		},
+	},
+	JDBCBridge: {
+		Name:                     "JDBC Bridge",
+		ID:                       JDBCBridge,
+		HasSystemDatabase:        false,
+		SupportsCDC:              false,
+		HasUniqueIdentifier:      false,
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"custom", "teradata", "informix", "sybase"},
+		DefaultPort:              50070, // Port of the bridge sidecar, not of the database
+		DefaultSSLPort:           50070,
+		ConnectionStringTemplate: "jdbc-bridge://{username}:{password}@{host}:{port}/{database}?jdbc_url={jdbc_url}&driver_class={driver_class}",
+		Paradigms:                []DataParadigm{ParadigmRelational},
+		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Aliases:                  []string{"jdbc", "jdbcbridge", "jdbc-bridge"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       false,
+			SupportsSchemas:      true,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	S3: {
 		Name:                     "Amazon S3",
@@ -610,6 +961,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerBlob},
 		Aliases:                  []string{"aws-s3"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemUntyped,
+		},
 	},
 	GCS: {
 		Name:                     "Google Cloud Storage",
@@ -625,6 +984,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerBlob},
 		Aliases:                  []string{"google-cloud-storage"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemUntyped,
+		},
 	},
 	AzureBlob: {
 		Name:                     "Azure Blob Storage",
@@ -640,6 +1007,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerBlob},
 		Aliases:                  []string{"azure-blob", "azureblob"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemUntyped,
+		},
 	},
 	MinIO: {
 		Name:                     "MinIO",
@@ -655,6 +1030,14 @@ var All = map[DatabaseType]Capability{
 		ConnectionStringTemplate: "minio://{username}:{password}@{host}:{port}/{database}?ssl={ssl}",
 		Paradigms:                []DataParadigm{ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerBlob},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteNoneLower,
+			TypeSystem:           TypeSystemUntyped,
+		},
 	},
 	InfluxDB: {
 		Name:                     "InfluxDB",
@@ -673,6 +1056,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerTimeSeriesPoint},
 		Aliases:                  []string{"influx"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemTimeSeries,
+		},
 	},
 	TimescaleDB: {
 		Name:                     "TimescaleDB",
@@ -681,6 +1071,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"postgres"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"logical_decoding", "wal2json", "pgoutput"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: PostgreSQL system_identifier.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-passive"},
@@ -691,6 +1083,15 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmTimeSeries, ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTimeSeriesPoint, ContainerTable},
 		Aliases:                  []string{"timescale"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  63,
+			IdentifierQuoting:    quoteDoubleLower,
+			TypeSystem:           TypeSystemPostgreSQL,
+			MinVersions:          postgresMinVersions,
+		},
 	},
 	Prometheus: {
 		Name:                     "Prometheus",
@@ -708,6 +1109,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerTimeSeriesPoint},
 		Aliases:                  []string{"prom"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemTimeSeries,
+		},
 	},
 	QuestDB: {
 		Name:                     "QuestDB",
@@ -724,6 +1132,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmTimeSeries, ParadigmRelational},
 		PrimaryContainers:        []PrimaryContainer{ContainerTimeSeriesPoint, ContainerTable},
 		Aliases:                  []string{"quest"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	VictoriaMetrics: {
 		Name:                     "VictoriaMetrics",
@@ -741,14 +1156,22 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerTimeSeriesPoint},
 		Aliases:                  []string{"vm", "victoria"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemTimeSeries,
+		},
 	},
 	BigQuery: {
 		Name:                     "Google BigQuery",
 		ID:                       BigQuery,
 		HasSystemDatabase:        true,
 		SystemDatabases:          []string{"INFORMATION_SCHEMA"},
-		SupportsCDC:              true,
-		CDCMechanisms:            []string{"change_history", "log_analytics"},
+		SupportsCDC:              false,
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: project ID.
 		SupportsClustering:       false,
 		SupportedVendors:         []string{"gcp-bigquery"},
@@ -758,6 +1181,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"bq"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  1024,
+			IdentifierQuoting:    quoteBacktick,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	Redshift: {
 		Name:                     "Amazon Redshift",
@@ -766,6 +1197,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"dev"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"kinesis", "eventbridge"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: cluster identifier.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-passive"},
@@ -776,6 +1209,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"aws-redshift"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  127,
+			IdentifierQuoting:    quoteDoubleLower,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	Synapse: {
 		Name:                     "Azure Synapse Analytics",
@@ -784,6 +1225,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"master"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"cdc", "change_tracking"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: workspace ID.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-passive"},
@@ -794,6 +1237,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"azure-synapse"},
+		Features: FeatureMatrix{
+			SupportsTransactions: true,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  128,
+			IdentifierQuoting:    quoteBrackets,
+			TypeSystem:           TypeSystemTSQL,
+		},
 	},
 	Databricks: {
 		Name:                     "Databricks",
@@ -802,6 +1253,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"information_schema"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"delta_cdf"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      true, // Unique ID: workspace ID.
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -812,6 +1265,14 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar, ParadigmObjectStore},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"databricks-sql"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      true,
+			MaxIdentifierLength:  255,
+			IdentifierQuoting:    quoteBacktickLower,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	Druid: {
 		Name:                     "Apache Druid",
@@ -820,6 +1281,8 @@ var All = map[DatabaseType]Capability{
 		SystemDatabases:          []string{"INFORMATION_SCHEMA", "sys"},
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"kafka_indexing"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      false,
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -830,6 +1293,13 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar, ParadigmTimeSeries},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable, ContainerTimeSeriesPoint},
 		Aliases:                  []string{"druid"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQL,
+		},
 	},
 	ApachePinot: {
 		Name:                     "Apache Pinot",
@@ -837,6 +1307,8 @@ var All = map[DatabaseType]Capability{
 		HasSystemDatabase:        false,
 		SupportsCDC:              true,
 		CDCMechanisms:            []string{"kafka_stream", "pulsar_stream"},
+		SupportsQueryPassthrough: true,
+		QueryLanguage:            "sql",
 		HasUniqueIdentifier:      false,
 		SupportsClustering:       true,
 		ClusteringMechanisms:     []string{"active-active"},
@@ -847,13 +1319,140 @@ var All = map[DatabaseType]Capability{
 		Paradigms:                []DataParadigm{ParadigmColumnar},
 		PrimaryContainers:        []PrimaryContainer{ContainerTable},
 		Aliases:                  []string{"pinot"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteDouble,
+			TypeSystem:           TypeSystemSQL,
+		},
+	},
+	File: {
+		Name:                     "CSV/Excel File",
+		ID:                       File,
+		HasSystemDatabase:        false,
+		SupportsCDC:              false,
+		HasUniqueIdentifier:      false,
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"custom"},
+		DefaultPort:              0,
+		DefaultSSLPort:           0,
+		ConnectionStringTemplate: "file://{database}",
+		Paradigms:                []DataParadigm{ParadigmRelational},
+		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Aliases:                  []string{"csv", "xlsx", "excel", "filesource"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemUntyped,
+		},
+	},
+	GoogleSheets: {
+		Name:                     "Google Sheets",
+		ID:                       GoogleSheets,
+		HasSystemDatabase:        false,
+		SupportsCDC:              false,
+		HasUniqueIdentifier:      true, // Unique ID: spreadsheet ID.
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"google"},
+		DefaultPort:              443,
+		DefaultSSLPort:           443,
+		ConnectionStringTemplate: "googlesheets://{database}",
+		Paradigms:                []DataParadigm{ParadigmRelational},
+		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Aliases:                  []string{"google-sheets", "gsheets", "sheets"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       false,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  100,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemUntyped,
+		},
+	},
+	Airtable: {
+		Name:                     "Airtable",
+		ID:                       Airtable,
+		HasSystemDatabase:        false,
+		SupportsCDC:              false,
+		HasUniqueIdentifier:      true, // Unique ID: base ID.
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"airtable"},
+		DefaultPort:              443,
+		DefaultSSLPort:           443,
+		ConnectionStringTemplate: "airtable://{database}",
+		Paradigms:                []DataParadigm{ParadigmRelational},
+		PrimaryContainers:        []PrimaryContainer{ContainerTable},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemUntyped,
+		},
+	},
+	Salesforce: {
+		Name:                     "Salesforce",
+		ID:                       Salesforce,
+		HasSystemDatabase:        false,
+		SupportsCDC:              true,
+		CDCMechanisms:            []string{"change_data_capture"},
+		HasUniqueIdentifier:      true, // Unique ID: organization ID.
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"salesforce"},
+		DefaultPort:              443,
+		DefaultSSLPort:           443,
+		ConnectionStringTemplate: "salesforce://{host}",
+		Paradigms:                []DataParadigm{ParadigmDocument},
+		PrimaryContainers:        []PrimaryContainer{ContainerCollection},
+		Aliases:                  []string{"sfdc"},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			MaxIdentifierLength:  40,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
+	},
+	HubSpot: {
+		Name:                     "HubSpot",
+		ID:                       HubSpot,
+		HasSystemDatabase:        false,
+		SupportsCDC:              true,
+		CDCMechanisms:            []string{"incremental_polling"},
+		HasUniqueIdentifier:      true, // Unique ID: portal (hub) ID.
+		SupportsClustering:       false,
+		SupportedVendors:         []string{"hubspot"},
+		DefaultPort:              443,
+		DefaultSSLPort:           443,
+		ConnectionStringTemplate: "hubspot://{database}",
+		Paradigms:                []DataParadigm{ParadigmDocument},
+		PrimaryContainers:        []PrimaryContainer{ContainerCollection},
+		Features: FeatureMatrix{
+			SupportsTransactions: false,
+			SupportsUpsert:       true,
+			SupportsSchemas:      false,
+			IdentifierQuoting:    quoteNone,
+			TypeSystem:           TypeSystemDocument,
+		},
 	},
 }
 
 // nameToID is a normalized lookup index from any known name/alias to the canonical DatabaseType.
 var nameToID map[string]DatabaseType
 
+// registryMu guards All and nameToID against databases registered or overridden at runtime.
+var registryMu sync.RWMutex
+
 func init() {
+	indexNames()
+}
+
+// indexNames rebuilds nameToID from All. The caller holds registryMu or runs before any lookup.
+func indexNames() {
 	nameToID = make(map[string]DatabaseType, len(All)*2)
 	for id, cap := range All {
 		// Canonical ID
@@ -879,6 +1478,8 @@ func ParseID(name string) (DatabaseType, bool) {
 	if n == "" {
 		return "", false
 	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	id, ok := nameToID[n]
 	return id, ok
 }
@@ -926,6 +1527,8 @@ func SupportsParadigmString(name string, p DataParadigm) bool {
 
 // IDs returns the list of all known database IDs.
 func IDs() []DatabaseType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	out := make([]DatabaseType, 0, len(All))
 	for id := range All {
 		out = append(out, id)
@@ -935,6 +1538,8 @@ func IDs() []DatabaseType {
 
 // Get returns capabilities for the given ID and a boolean indicating existence.
 func Get(id DatabaseType) (Capability, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	c, ok := All[id]
 	return c, ok
 }
@@ -974,6 +1579,12 @@ func SupportsCDC(id DatabaseType) bool {
 	return ok && c.SupportsCDC
 }
 
+// SupportsQueryPassthrough reports whether ad-hoc queries and commands can be passed through.
+func SupportsQueryPassthrough(id DatabaseType) bool {
+	c, ok := Get(id)
+	return ok && c.SupportsQueryPassthrough
+}
+
 // GetByConnectionType returns the Capability by looking up using a connection type string.
 // This is useful for refactoring existing code that uses connection type strings.
 func GetByConnectionType(connectionType string) (Capability, bool) {
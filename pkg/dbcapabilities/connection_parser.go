@@ -60,6 +60,11 @@ func ParseConnectionString(connectionString string) (*ConnectionDetails, error)
 		Parameters:     make(map[string]string),
 	}
 
+	// File-based databases are addressed by path, e.g. sqlite:///var/data/app.db
+	if dbType == SQLite {
+		return parseFileConnection(details, parsedURL)
+	}
+
 	// Extract host and port
 	if parsedURL.Hostname() == "" {
 		return nil, fmt.Errorf("host is required in connection string")
@@ -127,6 +132,30 @@ func ParseConnectionString(connectionString string) (*ConnectionDetails, error)
 	return details, nil
 }
 
+// parseFileConnection fills in the details of a path-based connection string.
+// sqlite:///abs/path.db addresses an absolute path and sqlite://data/app.db a relative one.
+func parseFileConnection(details *ConnectionDetails, parsedURL *url.URL) (*ConnectionDetails, error) {
+	path := parsedURL.Path
+	if parsedURL.Host != "" {
+		path = parsedURL.Host + path
+	}
+	if parsedURL.Opaque != "" {
+		path = parsedURL.Opaque
+	}
+	if path == "" {
+		return nil, fmt.Errorf("database file path is required in connection string")
+	}
+
+	details.Host = "localhost"
+	details.DatabaseName = path
+	for key, values := range parsedURL.Query() {
+		if len(values) > 0 {
+			details.Parameters[key] = values[0]
+		}
+	}
+	return details, nil
+}
+
 // isSystemDatabase checks if the given database name is a system database
 func isSystemDatabase(dbName string, systemDatabases []string) bool {
 	for _, sysDB := range systemDatabases {
@@ -199,6 +199,41 @@ func TestParseConnectionString(t *testing.T) {
 	}
 }
 
+func TestParseFileConnectionString(t *testing.T) {
+	tests := []struct {
+		name          string
+		connectionStr string
+		expectedDB    string
+		expectError   bool
+	}{
+		{name: "absolute path", connectionStr: "sqlite:///var/data/app.db", expectedDB: "/var/data/app.db"},
+		{name: "relative path", connectionStr: "sqlite://data/app.db", expectedDB: "data/app.db"},
+		{name: "alias with parameters", connectionStr: "sqlite3:///tmp/test.db?read_only=true", expectedDB: "/tmp/test.db"},
+		{name: "missing path", connectionStr: "sqlite://", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := ParseConnectionString(tt.connectionStr)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if details.DatabaseType != string(SQLite) {
+				t.Errorf("expected type %s, got %s", SQLite, details.DatabaseType)
+			}
+			if details.DatabaseName != tt.expectedDB {
+				t.Errorf("expected database %q, got %q", tt.expectedDB, details.DatabaseName)
+			}
+		})
+	}
+}
+
 func TestGetSystemDatabaseName(t *testing.T) {
 	tests := []struct {
 		name         string
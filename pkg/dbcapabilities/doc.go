@@ -30,6 +30,27 @@
 //	    return dbcapabilities.Get(canonical)
 //	}
 //
+// Features that services adapt generated SQL and writes to (transactions, upserts, schemas,
+// identifier length and quoting, type system family) are in the feature matrix of a database:
+//
+//	name := dbcapabilities.TruncateIdentifier(dbcapabilities.MySQL, "idx_orders_customer_id")
+//	ddl := "DROP INDEX " + dbcapabilities.QuoteIdentifier(dbcapabilities.MySQL, name)
+//
+// Features that depend on the server version (generated columns, JSON types, replication of
+// DDL, ...) are looked up with the version the server reports:
+//
+//	caps, _ := dbcapabilities.GetForVersion(dbcapabilities.PostgreSQL, "11.22")
+//	if !caps.Supports(dbcapabilities.FeatureJSONBType) {
+//	    columnType = "JSON"
+//	}
+//
+// Deployments can register database types and override capabilities of known databases at
+// runtime, without recompiling. Services started by the supervisor apply the
+// database_capabilities section of its configuration on startup:
+//
+//	off := false
+//	err := dbcapabilities.Override("mysql", dbcapabilities.CapabilityOverride{SupportsCDC: &off})
+//
 // The package exposes constants for IDs (e.g., dbcapabilities.PostgreSQL) and a
 // registry `All` for advanced consumers.
 package dbcapabilities
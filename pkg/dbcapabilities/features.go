@@ -0,0 +1,167 @@
+package dbcapabilities
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FeatureMatrix describes the features of a database that services adapt their behavior to,
+// such as the SQL they generate or the way they write rows.
+type FeatureMatrix struct {
+	// Whether several statements can be committed or rolled back together.
+	SupportsTransactions bool `json:"supportsTransactions"`
+
+	// Whether a row can be inserted or, if one with the same key exists, updated in one statement.
+	SupportsUpsert bool `json:"supportsUpsert"`
+
+	// Whether a database has namespaces (schemas) holding its tables.
+	SupportsSchemas bool `json:"supportsSchemas"`
+
+	// Longest identifier (table, column, index, ...) the database accepts, in bytes; 0 if
+	// identifiers are not limited in practice.
+	MaxIdentifierLength int `json:"maxIdentifierLength,omitempty"`
+
+	// How identifiers are quoted and how unquoted identifiers are folded.
+	IdentifierQuoting IdentifierQuoting `json:"identifierQuoting"`
+
+	// Family of the type system of the database.
+	TypeSystem TypeSystem `json:"typeSystem"`
+
+	// Server version each versioned feature is supported from. Features missing from the map
+	// are not supported at any version.
+	MinVersions map[VersionedFeature]string `json:"minVersions,omitempty"`
+}
+
+// IdentifierQuoting describes how a database quotes identifiers. A database whose identifiers
+// are never quoted, e.g. because they are keys or paths, has no quote characters.
+type IdentifierQuoting struct {
+	Open  string `json:"open,omitempty"`  // Opening quote, e.g. `"`, "`" or "["
+	Close string `json:"close,omitempty"` // Closing quote, doubled when it appears in an identifier
+
+	// How the database folds identifiers that are not quoted.
+	UnquotedCase IdentifierCase `json:"unquotedCase"`
+}
+
+// IdentifierCase is the case a database folds unquoted identifiers to.
+type IdentifierCase string
+
+const (
+	CasePreserve IdentifierCase = "preserve"
+	CaseLower    IdentifierCase = "lower"
+	CaseUpper    IdentifierCase = "upper"
+)
+
+// TypeSystem is a family of type systems. Databases of a family share their type names and
+// the semantics of their types, so types translate between them as they are.
+type TypeSystem string
+
+const (
+	TypeSystemPostgreSQL TypeSystem = "postgresql" // PostgreSQL and its derivatives
+	TypeSystemMySQL      TypeSystem = "mysql"      // MySQL and its derivatives
+	TypeSystemTSQL       TypeSystem = "tsql"       // SQL Server and Azure Synapse
+	TypeSystemOracle     TypeSystem = "oracle"
+	TypeSystemClickHouse TypeSystem = "clickhouse"
+	TypeSystemSQL        TypeSystem = "sql"        // Other SQL databases, close to the SQL standard types
+	TypeSystemSQLite     TypeSystem = "sqlite"     // Types are affinities of values rather than of columns
+	TypeSystemDocument   TypeSystem = "document"   // JSON-like documents
+	TypeSystemCQL        TypeSystem = "cql"        // Cassandra Query Language
+	TypeSystemGraph      TypeSystem = "graph"      // Properties of nodes and relationships
+	TypeSystemVector     TypeSystem = "vector"     // Vectors with scalar metadata
+	TypeSystemTimeSeries TypeSystem = "timeseries" // Measurements with tags or labels
+	TypeSystemUntyped    TypeSystem = "untyped"    // Opaque values, e.g. of keys, objects or cells
+)
+
+// Quoting rules shared by the databases
+var (
+	quoteDouble        = IdentifierQuoting{Open: `"`, Close: `"`, UnquotedCase: CasePreserve}
+	quoteDoubleLower   = IdentifierQuoting{Open: `"`, Close: `"`, UnquotedCase: CaseLower}
+	quoteDoubleUpper   = IdentifierQuoting{Open: `"`, Close: `"`, UnquotedCase: CaseUpper}
+	quoteBacktick      = IdentifierQuoting{Open: "`", Close: "`", UnquotedCase: CasePreserve}
+	quoteBacktickLower = IdentifierQuoting{Open: "`", Close: "`", UnquotedCase: CaseLower}
+	quoteBrackets      = IdentifierQuoting{Open: "[", Close: "]", UnquotedCase: CasePreserve}
+	quoteNone          = IdentifierQuoting{UnquotedCase: CasePreserve}
+	quoteNoneLower     = IdentifierQuoting{UnquotedCase: CaseLower}
+)
+
+// GetFeatures returns the feature matrix of a database and false if the database is unknown.
+func GetFeatures(id DatabaseType) (FeatureMatrix, bool) {
+	c, ok := Get(id)
+	return c.Features, ok
+}
+
+// SupportsTransactions reports whether several statements can be committed together.
+func SupportsTransactions(id DatabaseType) bool {
+	c, ok := Get(id)
+	return ok && c.Features.SupportsTransactions
+}
+
+// SupportsUpsert reports whether rows can be inserted or updated in one statement.
+func SupportsUpsert(id DatabaseType) bool {
+	c, ok := Get(id)
+	return ok && c.Features.SupportsUpsert
+}
+
+// SupportsBulkLoad reports whether rows can be loaded with a native bulk load primitive.
+func SupportsBulkLoad(id DatabaseType) bool {
+	c, ok := Get(id)
+	return ok && c.SupportsBulkLoad
+}
+
+// SupportsSchemas reports whether a database has schemas holding its tables.
+func SupportsSchemas(id DatabaseType) bool {
+	c, ok := Get(id)
+	return ok && c.Features.SupportsSchemas
+}
+
+// MaxIdentifierLength returns the longest identifier a database accepts, or 0 if identifiers
+// are not limited or the database is unknown.
+func MaxIdentifierLength(id DatabaseType) int {
+	c, _ := Get(id)
+	return c.Features.MaxIdentifierLength
+}
+
+// GetTypeSystem returns the type system family of a database, or "" if the database is unknown.
+func GetTypeSystem(id DatabaseType) TypeSystem {
+	c, _ := Get(id)
+	return c.Features.TypeSystem
+}
+
+// QuoteIdentifier quotes an identifier for a database, escaping the quotes in it. Identifiers
+// of databases without quoting, or unknown ones, are returned as they are.
+func QuoteIdentifier(id DatabaseType, name string) string {
+	c, _ := Get(id)
+	return c.Features.IdentifierQuoting.Quote(name)
+}
+
+// Quote quotes an identifier, doubling the closing quotes in it.
+func (q IdentifierQuoting) Quote(name string) string {
+	if q.Open == "" {
+		return name
+	}
+	return q.Open + strings.ReplaceAll(name, q.Close, q.Close+q.Close) + q.Close
+}
+
+// Fold returns an unquoted identifier as the database stores it.
+func (q IdentifierQuoting) Fold(name string) string {
+	switch q.UnquotedCase {
+	case CaseLower:
+		return strings.ToLower(name)
+	case CaseUpper:
+		return strings.ToUpper(name)
+	default:
+		return name
+	}
+}
+
+// TruncateIdentifier shortens an identifier to the longest one a database accepts. Longer
+// identifiers are cut at a character boundary.
+func TruncateIdentifier(id DatabaseType, name string) string {
+	limit := MaxIdentifierLength(id)
+	if limit <= 0 || len(name) <= limit {
+		return name
+	}
+	for limit > 0 && !utf8.RuneStart(name[limit]) {
+		limit--
+	}
+	return name[:limit]
+}
@@ -0,0 +1,48 @@
+package dbcapabilities
+
+import "testing"
+
+func TestFeaturesHaveTypeSystem(t *testing.T) {
+	for id, c := range All {
+		if c.Features.TypeSystem == "" {
+			t.Errorf("%s: no type system", id)
+		}
+		if c.Features.IdentifierQuoting.UnquotedCase == "" {
+			t.Errorf("%s: no identifier case", id)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		id   DatabaseType
+		name string
+		want string
+	}{
+		{PostgreSQL, `a"b`, `"a""b"`},
+		{MySQL, "a`b", "`a``b`"},
+		{SQLServer, "a]b", "[a]]b]"},
+		{MongoDB, "a.b", "a.b"},
+		{DatabaseType("unknown"), "a", "a"},
+	}
+	for _, tt := range tests {
+		if got := QuoteIdentifier(tt.id, tt.name); got != tt.want {
+			t.Errorf("QuoteIdentifier(%s, %q) = %q, want %q", tt.id, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateIdentifier(t *testing.T) {
+	long := "idx_" + string(make([]byte, 70))
+	if got := TruncateIdentifier(PostgreSQL, long); len(got) != 63 {
+		t.Errorf("PostgreSQL identifier truncated to %d bytes, want 63", len(got))
+	}
+	if got := TruncateIdentifier(MongoDB, long); got != long {
+		t.Error("MongoDB identifier should not be truncated")
+	}
+	// A multi-byte character crossing the limit is dropped as a whole
+	name := string(make([]byte, 62)) + "é"
+	if got := TruncateIdentifier(PostgreSQL, name); len(got) != 62 {
+		t.Errorf("identifier truncated to %d bytes, want 62", len(got))
+	}
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// adapterImportPrefix is the import path of the adapter packages of the anchor service
+const adapterImportPrefix = "github.com/redbco/redb-open/services/anchor/internal/database/"
+
+// operation maps an operation of the matrix to the methods implementing it
+type operation struct {
+	op       dbcapabilities.Operation
+	receiver string   // Type of the adapter package with the methods
+	methods  []string // All are required
+	optional bool     // Methods of an optional interface, the operation is unsupported without them
+}
+
+// Adapter packages name their types after the interfaces they implement: Adapter,
+// InstanceConnection, SchemaOps, DataOps, ReplicationOps and MetadataOps.
+var operations = []operation{
+	{op: dbcapabilities.OpConnectInstance, receiver: "Adapter", methods: []string{"ConnectInstance"}},
+	{op: dbcapabilities.OpListDatabases, receiver: "InstanceConnection", methods: []string{"ListDatabases"}},
+	{op: dbcapabilities.OpCreateDatabase, receiver: "InstanceConnection", methods: []string{"CreateDatabase"}},
+	{op: dbcapabilities.OpDropDatabase, receiver: "InstanceConnection", methods: []string{"DropDatabase"}},
+	{op: dbcapabilities.OpSchemaDiscovery, receiver: "SchemaOps", methods: []string{"DiscoverSchema"}},
+	{op: dbcapabilities.OpIncrementalSchemaDiscovery, receiver: "SchemaOps", methods: []string{"DiscoverSchemaIncremental"}, optional: true},
+	{op: dbcapabilities.OpSchemaCreation, receiver: "SchemaOps", methods: []string{"CreateStructure"}},
+	{op: dbcapabilities.OpRead, receiver: "DataOps", methods: []string{"Fetch", "FetchWithColumns"}},
+	{op: dbcapabilities.OpStream, receiver: "DataOps", methods: []string{"Stream", "FetchStream"}},
+	{op: dbcapabilities.OpParallelRead, receiver: "DataOps", methods: []string{"KeyBounds", "FetchKeyRange"}, optional: true},
+	{op: dbcapabilities.OpInsert, receiver: "DataOps", methods: []string{"Insert"}},
+	{op: dbcapabilities.OpBulkLoad, receiver: "DataOps", methods: []string{"BulkInsert"}, optional: true},
+	{op: dbcapabilities.OpUpdate, receiver: "DataOps", methods: []string{"Update"}},
+	{op: dbcapabilities.OpUpsert, receiver: "DataOps", methods: []string{"Upsert"}},
+	{op: dbcapabilities.OpDelete, receiver: "DataOps", methods: []string{"Delete"}},
+	{op: dbcapabilities.OpWipe, receiver: "DataOps", methods: []string{"Wipe"}},
+	{op: dbcapabilities.OpSandbox, receiver: "DataOps", methods: []string{"CreateSandbox", "InsertSandbox", "FetchSandbox", "DropSandbox"}, optional: true},
+	{op: dbcapabilities.OpQuery, receiver: "DataOps", methods: []string{"ExecuteQuery", "ExecuteCountQuery"}},
+	{op: dbcapabilities.OpReadOnlyQuery, receiver: "DataOps", methods: []string{"ExecuteReadOnlyQuery"}, optional: true},
+	{op: dbcapabilities.OpQueryPlan, receiver: "DataOps", methods: []string{"ExplainFetch"}, optional: true},
+	{op: dbcapabilities.OpVectorSearch, receiver: "DataOps", methods: []string{"SearchVectors"}, optional: true},
+	{op: dbcapabilities.OpSampleEdges, receiver: "DataOps", methods: []string{"SampleEdges"}, optional: true},
+	{op: dbcapabilities.OpCDC, receiver: "ReplicationOps", methods: []string{"IsSupported", "Connect", "ParseEvent"}},
+	{op: dbcapabilities.OpCDCApply, receiver: "ReplicationOps", methods: []string{"ApplyCDCEvent"}},
+	{op: dbcapabilities.OpMetadata, receiver: "MetadataOps", methods: []string{"CollectDatabaseMetadata", "GetVersion"}},
+	{op: dbcapabilities.OpCommand, receiver: "MetadataOps", methods: []string{"ExecuteCommand"}},
+}
+
+// operatorAccessors are the methods of a connection returning the operators, which return the
+// unsupported operators of the adapter package for whole categories of operations
+var operatorAccessors = map[string]string{
+	"SchemaOperations":      "SchemaOps",
+	"DataOperations":        "DataOps",
+	"ReplicationOperations": "ReplicationOps",
+	"MetadataOperations":    "MetadataOps",
+}
+
+// adapterPackage holds the methods of an adapter package by receiver type
+type adapterPackage struct {
+	methods     map[string]map[string]*ast.FuncDecl
+	registered  bool            // An init function registers the adapter
+	unsupported map[string]bool // Receiver types replaced by an unsupported operator
+}
+
+// Generate builds the support matrix of the adapters registered by the anchor service in
+// anchorDir. Adapters are registered by importing their package in the main package of the
+// anchor; the edition of an adapter is the build of the anchor importing it.
+func Generate(anchorDir string) (dbcapabilities.SupportMatrix, error) {
+	matrix := dbcapabilities.SupportMatrix{
+		Operations: dbcapabilities.Operations,
+		Databases:  []dbcapabilities.AdapterSupport{},
+	}
+
+	editions := make(map[string]string)
+	for _, edition := range []string{dbcapabilities.EditionEnterprise, dbcapabilities.EditionCommunity} {
+		imports, err := importedAdapters(filepath.Join(anchorDir, "cmd", "imports_"+edition+".go"))
+		if err != nil {
+			return matrix, err
+		}
+		for _, name := range imports {
+			editions[name] = edition
+		}
+	}
+
+	names := make([]string, 0, len(editions))
+	for name := range editions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pkg, err := parseAdapterPackage(filepath.Join(anchorDir, "internal", "database", name))
+		if err != nil {
+			return matrix, err
+		}
+		if !pkg.registered {
+			continue
+		}
+
+		// Adapter packages are named after the database
+		id, ok := dbcapabilities.ParseID(name)
+		if !ok {
+			return matrix, fmt.Errorf("adapter package %s does not name a known database", name)
+		}
+		capability := dbcapabilities.MustGet(id)
+
+		support := dbcapabilities.AdapterSupport{
+			ID:         id,
+			Name:       capability.Name,
+			Edition:    editions[name],
+			Operations: make(map[dbcapabilities.Operation]dbcapabilities.OperationSupport),
+		}
+		for _, operation := range operations {
+			support.Operations[operation.op] = pkg.support(operation)
+		}
+		applyInstanceSupport(support.Operations)
+		applyCapabilities(support.Operations, capability)
+
+		matrix.Databases = append(matrix.Databases, support)
+	}
+
+	sort.Slice(matrix.Databases, func(i, j int) bool {
+		return matrix.Databases[i].ID < matrix.Databases[j].ID
+	})
+	return matrix, nil
+}
+
+// importedAdapters returns the adapter packages imported by a file of the anchor main package
+func importedAdapters(filename string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(importPath, adapterImportPrefix) {
+			names = append(names, path.Base(importPath))
+		}
+	}
+	return names, nil
+}
+
+func parseAdapterPackage(dir string) (*adapterPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &adapterPackage{
+		methods:     make(map[string]map[string]*ast.FuncDecl),
+		unsupported: make(map[string]bool),
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if fn.Recv == nil {
+				if fn.Name.Name == "init" && callsFunction(fn.Body, "Register") {
+					pkg.registered = true
+				}
+				continue
+			}
+
+			receiver := receiverType(fn)
+			if pkg.methods[receiver] == nil {
+				pkg.methods[receiver] = make(map[string]*ast.FuncDecl)
+			}
+			pkg.methods[receiver][fn.Name.Name] = fn
+
+			if operator, ok := operatorAccessors[fn.Name.Name]; ok && receiver == "Connection" &&
+				callsFunction(fn.Body, "NewUnsupported"+strings.TrimSuffix(fn.Name.Name, "Operations")+"Operator") {
+				pkg.unsupported[operator] = true
+			}
+		}
+	}
+	return pkg, nil
+}
+
+// support classifies the methods implementing an operation. An operation is unsupported if
+// one of its methods only returns an unsupported operation error, and partial if one of them
+// returns such an error in some cases.
+func (p *adapterPackage) support(operation operation) dbcapabilities.OperationSupport {
+	if p.unsupported[operation.receiver] {
+		return dbcapabilities.OperationSupport{Status: dbcapabilities.Unsupported}
+	}
+
+	support := dbcapabilities.OperationSupport{Status: dbcapabilities.Supported}
+	for _, name := range operation.methods {
+		fn, ok := p.methods[operation.receiver][name]
+		if !ok {
+			if operation.optional {
+				return dbcapabilities.OperationSupport{Status: dbcapabilities.Unsupported}
+			}
+			continue
+		}
+
+		if name == "IsSupported" {
+			if returnsFalse(fn) {
+				return dbcapabilities.OperationSupport{Status: dbcapabilities.Unsupported}
+			}
+			continue
+		}
+
+		reasons, stub := unsupportedReasons(fn)
+		if stub {
+			return dbcapabilities.OperationSupport{Status: dbcapabilities.Unsupported, Caveats: reasons}
+		}
+		if len(reasons) > 0 {
+			support.Status = dbcapabilities.Partial
+			support.Caveats = appendUnique(support.Caveats, reasons...)
+		}
+	}
+	return support
+}
+
+// applyInstanceSupport marks the instance operations unsupported when the adapter cannot
+// connect to instances
+func applyInstanceSupport(ops map[dbcapabilities.Operation]dbcapabilities.OperationSupport) {
+	if ops[dbcapabilities.OpConnectInstance].Status != dbcapabilities.Unsupported {
+		return
+	}
+	for _, op := range []dbcapabilities.Operation{dbcapabilities.OpListDatabases, dbcapabilities.OpCreateDatabase, dbcapabilities.OpDropDatabase} {
+		ops[op] = dbcapabilities.OperationSupport{
+			Status:  dbcapabilities.Unsupported,
+			Caveats: []string{"instance connections are not supported"},
+		}
+	}
+}
+
+// applyCapabilities adds the mechanisms declared by the capabilities of the database, and a
+// caveat where the capabilities and the adapter disagree
+func applyCapabilities(ops map[dbcapabilities.Operation]dbcapabilities.OperationSupport, capability dbcapabilities.Capability) {
+	declared := []struct {
+		op         dbcapabilities.Operation
+		declared   bool
+		mechanisms []string
+		name       string
+	}{
+		{dbcapabilities.OpCDC, capability.SupportsCDC, capability.CDCMechanisms, "CDC"},
+		{dbcapabilities.OpBulkLoad, capability.SupportsBulkLoad, capability.BulkLoadMechanisms, "bulk load"},
+		{dbcapabilities.OpQuery, capability.SupportsQueryPassthrough, nil, "query passthrough"},
+	}
+
+	for _, d := range declared {
+		support := ops[d.op]
+		implemented := support.Status != dbcapabilities.Unsupported
+		switch {
+		case implemented && d.declared:
+			support.Mechanisms = d.mechanisms
+		case implemented:
+			support.Caveats = append(support.Caveats, d.name+" is not declared in the capabilities of the database")
+		case d.declared:
+			support.Caveats = append(support.Caveats, d.name+" is declared in the capabilities of the database but not implemented by the adapter")
+		}
+		ops[d.op] = support
+	}
+}
+
+func receiverType(fn *ast.FuncDecl) string {
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// callsFunction returns true if a function body calls a function or method of that name
+func callsFunction(body *ast.BlockStmt, name string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && calleeName(call) == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func calleeName(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+// returnsFalse returns true if a method only returns false
+func returnsFalse(fn *ast.FuncDecl) bool {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	ident, ok := ret.Results[0].(*ast.Ident)
+	return ok && ident.Name == "false"
+}
+
+// unsupportedReasons returns the reasons of the unsupported operation errors a method returns,
+// and whether the method is a stub returning nothing else
+func unsupportedReasons(fn *ast.FuncDecl) ([]string, bool) {
+	if fn.Body == nil {
+		return nil, false
+	}
+
+	var reasons []string
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		reason, ok := unsupportedError(n)
+		if ok {
+			found = true
+			if reason != "" {
+				reasons = appendUnique(reasons, reason)
+			}
+		}
+		return !ok
+	})
+
+	stub := false
+	if len(fn.Body.List) == 1 {
+		_, stub = fn.Body.List[0].(*ast.ReturnStmt)
+	}
+	return reasons, found && stub
+}
+
+// unsupportedError recognizes the errors adapters return for unsupported operations:
+// adapter.NewUnsupportedOperationError, adapter.ErrOperationNotSupported, and errors created
+// with a message saying the operation is not supported or not implemented
+func unsupportedError(n ast.Node) (string, bool) {
+	switch node := n.(type) {
+	case *ast.SelectorExpr:
+		return "", node.Sel.Name == "ErrOperationNotSupported"
+	case *ast.CallExpr:
+		switch calleeName(node) {
+		case "NewUnsupportedOperationError":
+			if len(node.Args) != 3 {
+				return "", true
+			}
+			op := strings.ReplaceAll(stringLiteral(node.Args[1]), "_", " ")
+			reason := stringLiteral(node.Args[2])
+			switch {
+			case op != "" && reason != "":
+				return op + ": " + reason, true
+			case op != "":
+				return op + " is not supported", true
+			}
+			return reason, true
+		case "Errorf", "New":
+			if len(node.Args) == 0 {
+				return "", false
+			}
+			message := stringLiteral(node.Args[0])
+			lower := strings.ToLower(message)
+			if !strings.Contains(lower, "not supported") && !strings.Contains(lower, "not implemented") {
+				return "", false
+			}
+			// Drop the formatted details, such as the wrapped error
+			if i := strings.Index(message, "%"); i >= 0 {
+				message = strings.TrimRight(message[:i], " :")
+			}
+			return message, true
+		}
+	}
+	return "", false
+}
+
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func appendUnique(values []string, add ...string) []string {
+	for _, value := range add {
+		exists := false
+		for _, v := range values {
+			if v == value {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			values = append(values, value)
+		}
+	}
+	return values
+}
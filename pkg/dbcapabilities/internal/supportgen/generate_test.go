@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// TestSupportMatrixIsCurrent fails when the adapters changed without regenerating the matrix
+func TestSupportMatrixIsCurrent(t *testing.T) {
+	matrix, err := Generate(filepath.Join("..", "..", "..", "..", "services", "anchor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := encode(matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := os.ReadFile(filepath.Join("..", "..", "support_matrix.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, current) {
+		t.Fatal("support_matrix.json is out of date, run go generate in pkg/dbcapabilities")
+	}
+}
+
+func TestGenerateClassifiesOperations(t *testing.T) {
+	anchor := t.TempDir()
+	writeFile(t, filepath.Join(anchor, "cmd", "imports_community.go"), `package main
+
+import _ "github.com/redbco/redb-open/services/anchor/internal/database/redis"
+`)
+	writeFile(t, filepath.Join(anchor, "cmd", "imports_enterprise.go"), `package main
+
+import (
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/oracle"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/redis"
+)
+`)
+	writeFile(t, filepath.Join(anchor, "internal", "database", "redis", "adapter.go"), `package redis
+
+func init() { adapter.Register(NewAdapter()) }
+
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.Redis)
+}
+
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Redis, "update data", "keys are replaced with Upsert")
+}
+
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	if len(conditions) > 1 {
+		return 0, fmt.Errorf("deleting by more than one key is not supported: %v", conditions)
+	}
+	return d.deleteKey(ctx, conditions)
+}
+
+func (d *DataOps) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return d.pipeline(ctx, data)
+}
+`)
+	// Not registered, so not in the matrix
+	writeFile(t, filepath.Join(anchor, "internal", "database", "oracle", "adapter.go"), `package oracle
+`)
+
+	matrix, err := Generate(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matrix.Databases) != 1 {
+		t.Fatalf("got %d databases, want the registered redis adapter only", len(matrix.Databases))
+	}
+
+	redis := matrix.Databases[0]
+	if redis.ID != dbcapabilities.Redis || redis.Edition != dbcapabilities.EditionCommunity {
+		t.Fatalf("got %s in the %s edition", redis.ID, redis.Edition)
+	}
+
+	expected := map[dbcapabilities.Operation]dbcapabilities.OperationSupport{
+		dbcapabilities.OpInsert: {Status: dbcapabilities.Supported},
+		dbcapabilities.OpUpdate: {
+			Status:  dbcapabilities.Unsupported,
+			Caveats: []string{"update data: keys are replaced with Upsert"},
+		},
+		dbcapabilities.OpDelete: {
+			Status:  dbcapabilities.Partial,
+			Caveats: []string{"deleting by more than one key is not supported"},
+		},
+		dbcapabilities.OpBulkLoad: {
+			Status:  dbcapabilities.Supported,
+			Caveats: []string{"bulk load is not declared in the capabilities of the database"},
+		},
+		dbcapabilities.OpParallelRead: {Status: dbcapabilities.Unsupported},
+		dbcapabilities.OpCDC:          {Status: dbcapabilities.Unsupported},
+		dbcapabilities.OpCDCApply:     {Status: dbcapabilities.Unsupported},
+	}
+	for op, want := range expected {
+		if got := redis.Operations[op]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", op, got, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
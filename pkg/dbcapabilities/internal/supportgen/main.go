@@ -0,0 +1,46 @@
+// Command supportgen generates the support matrix of the database adapters: for every adapter
+// registered by the anchor service, which operations it implements, with the caveats found in
+// the code. Run it with go generate in pkg/dbcapabilities.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	anchorDir := flag.String("anchor", "../../services/anchor", "Directory of the anchor service")
+	out := flag.String("out", "support_matrix.json", "Output file")
+	flag.Parse()
+
+	matrix, err := Generate(*anchorDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "supportgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := encode(matrix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "supportgen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "supportgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// encode formats the matrix as indented JSON, which keeps the diffs of regenerations readable
+func encode(matrix interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(matrix); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,260 @@
+package dbcapabilities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvConfig is the environment variable ApplyFromEnv reads a Config from, as JSON. The supervisor
+// exports its database_capabilities settings through it so the services it starts inherit them.
+const EnvConfig = "REDB_DATABASE_CAPABILITIES"
+
+// Config describes the changes a deployment makes to the built-in registry: databases it adds
+// and fields of known databases it overrides. Its JSON keys are the ones of Capability.
+type Config struct {
+	// Databases added to the registry.
+	Databases []Capability `json:"databases,omitempty"`
+
+	// Overrides of known or added databases, keyed by ID, name or alias.
+	Overrides map[string]CapabilityOverride `json:"overrides,omitempty"`
+}
+
+// CapabilityOverride holds the fields of a Capability a deployment overrides. Fields that are
+// not set keep their value; a list replaces the list of the database, except aliases, which
+// are added to its aliases.
+type CapabilityOverride struct {
+	HasSystemDatabase *bool    `json:"hasSystemDatabase,omitempty"`
+	SystemDatabases   []string `json:"systemDatabases,omitempty"`
+
+	SupportsCDC   *bool    `json:"supportsCDC,omitempty"`
+	CDCMechanisms []string `json:"cdcMechanisms,omitempty"`
+
+	SupportsBulkLoad   *bool    `json:"supportsBulkLoad,omitempty"`
+	BulkLoadMechanisms []string `json:"bulkLoadMechanisms,omitempty"`
+
+	SupportsQueryPassthrough *bool `json:"supportsQueryPassthrough,omitempty"`
+	SupportsClustering       *bool `json:"supportsClustering,omitempty"`
+
+	SupportedVendors []string `json:"supportedVendors,omitempty"`
+
+	DefaultPort    *int `json:"defaultPort,omitempty"`
+	DefaultSSLPort *int `json:"defaultSSLPort,omitempty"`
+
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Minimum server versions of versioned features, merged into the ones of the database.
+	// An empty version removes the feature.
+	MinVersions map[VersionedFeature]string `json:"minVersions,omitempty"`
+}
+
+// ParseConfig reads a Config from JSON. Unknown keys are rejected so misspelled fields are not
+// silently ignored.
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid database capabilities: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks that the configuration applies to the registry, without applying it.
+func (c Config) Validate() error {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, err := c.applyTo(All)
+	return err
+}
+
+// Apply registers the databases of a configuration, then applies its overrides. Either the
+// whole configuration is applied or, on error, none of it.
+func Apply(cfg Config) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	reg, err := cfg.applyTo(All)
+	if err != nil {
+		return err
+	}
+	All = reg
+	indexNames()
+	return nil
+}
+
+// ApplyFromEnv applies the configuration exported in EnvConfig, if any.
+func ApplyFromEnv() error {
+	data := strings.TrimSpace(os.Getenv(EnvConfig))
+	if data == "" {
+		return nil
+	}
+	cfg, err := ParseConfig([]byte(data))
+	if err != nil {
+		return err
+	}
+	return Apply(cfg)
+}
+
+// Register adds a database to the registry, so a deployment can describe a database type
+// without recompiling. Its ID, name and aliases must not name a known database.
+func Register(c Capability) error {
+	return Apply(Config{Databases: []Capability{c}})
+}
+
+// Override changes fields of a known database, looked up by ID, name or alias.
+func Override(name string, o CapabilityOverride) error {
+	return Apply(Config{Overrides: map[string]CapabilityOverride{name: o}})
+}
+
+// applyTo returns a copy of a registry with the configuration applied.
+func (c Config) applyTo(all map[DatabaseType]Capability) (map[DatabaseType]Capability, error) {
+	reg := make(map[DatabaseType]Capability, len(all)+len(c.Databases))
+	for id, capability := range all {
+		reg[id] = capability
+	}
+
+	for _, capability := range c.Databases {
+		if err := register(reg, capability); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(c.Overrides))
+	for name := range c.Overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := override(reg, name, c.Overrides[name]); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// lookup resolves a name the way ParseID does, in a registry that is not indexed yet.
+func lookup(reg map[DatabaseType]Capability, name string) (DatabaseType, bool) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if n == "" {
+		return "", false
+	}
+	for id, c := range reg {
+		if strings.ToLower(string(id)) == n || strings.ToLower(c.Name) == n {
+			return id, true
+		}
+		for _, a := range c.Aliases {
+			if strings.ToLower(a) == n {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+func register(reg map[DatabaseType]Capability, c Capability) error {
+	if c.ID == "" || c.Name == "" {
+		return errors.New("registered databases need an id and a name")
+	}
+	if string(c.ID) != strings.ToLower(strings.TrimSpace(string(c.ID))) {
+		return fmt.Errorf("database id %q must be lower case without spaces", c.ID)
+	}
+	for _, name := range append([]string{string(c.ID), c.Name}, c.Aliases...) {
+		if id, ok := lookup(reg, name); ok {
+			return fmt.Errorf("cannot register database %s: %q already names database %s", c.ID, name, id)
+		}
+	}
+	if err := validateMinVersions(c.ID, c.Features.MinVersions); err != nil {
+		return err
+	}
+	reg[c.ID] = c
+	return nil
+}
+
+func override(reg map[DatabaseType]Capability, name string, o CapabilityOverride) error {
+	id, ok := lookup(reg, name)
+	if !ok {
+		return fmt.Errorf("cannot override unknown database %q", name)
+	}
+	c := reg[id]
+
+	if o.HasSystemDatabase != nil {
+		c.HasSystemDatabase = *o.HasSystemDatabase
+	}
+	if o.SystemDatabases != nil {
+		c.SystemDatabases = o.SystemDatabases
+	}
+	if o.SupportsCDC != nil {
+		c.SupportsCDC = *o.SupportsCDC
+	}
+	if o.CDCMechanisms != nil {
+		c.CDCMechanisms = o.CDCMechanisms
+	}
+	if o.SupportsBulkLoad != nil {
+		c.SupportsBulkLoad = *o.SupportsBulkLoad
+	}
+	if o.BulkLoadMechanisms != nil {
+		c.BulkLoadMechanisms = o.BulkLoadMechanisms
+	}
+	if o.SupportsQueryPassthrough != nil {
+		c.SupportsQueryPassthrough = *o.SupportsQueryPassthrough
+	}
+	if o.SupportsClustering != nil {
+		c.SupportsClustering = *o.SupportsClustering
+	}
+	if o.SupportedVendors != nil {
+		c.SupportedVendors = o.SupportedVendors
+	}
+	if o.DefaultPort != nil {
+		c.DefaultPort = *o.DefaultPort
+	}
+	if o.DefaultSSLPort != nil {
+		c.DefaultSSLPort = *o.DefaultSSLPort
+	}
+
+	if len(o.Aliases) > 0 {
+		for _, alias := range o.Aliases {
+			if other, ok := lookup(reg, alias); ok && other != id {
+				return fmt.Errorf("cannot add alias %q to database %s: it already names database %s", alias, id, other)
+			}
+		}
+		// Copied so the aliases of the capabilities handed out before are left as they are
+		c.Aliases = append(append([]string(nil), c.Aliases...), o.Aliases...)
+	}
+
+	if len(o.MinVersions) > 0 {
+		if err := validateMinVersions(id, o.MinVersions); err != nil {
+			return err
+		}
+		minVersions := make(map[VersionedFeature]string, len(c.Features.MinVersions)+len(o.MinVersions))
+		for f, v := range c.Features.MinVersions {
+			minVersions[f] = v
+		}
+		for f, v := range o.MinVersions {
+			if v == "" {
+				delete(minVersions, f)
+				continue
+			}
+			minVersions[f] = v
+		}
+		c.Features.MinVersions = minVersions
+	}
+
+	reg[id] = c
+	return nil
+}
+
+func validateMinVersions(id DatabaseType, minVersions map[VersionedFeature]string) error {
+	for f, v := range minVersions {
+		if v == "" {
+			continue
+		}
+		if _, err := ParseVersion(v); err != nil {
+			return fmt.Errorf("invalid minimum version of %s for database %s: %w", f, id, err)
+		}
+	}
+	return nil
+}
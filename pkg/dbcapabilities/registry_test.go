@@ -0,0 +1,130 @@
+package dbcapabilities
+
+import "testing"
+
+// restoreRegistry puts the built-in registry back once a test is done.
+func restoreRegistry(t *testing.T) {
+	t.Helper()
+	saved := All
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		All = saved
+		indexNames()
+	})
+}
+
+func TestRegister(t *testing.T) {
+	restoreRegistry(t)
+
+	err := Register(Capability{
+		Name:              "YugabyteDB",
+		ID:                "yugabytedb",
+		SupportsCDC:       true,
+		DefaultPort:       5433,
+		Paradigms:         []DataParadigm{ParadigmRelational},
+		PrimaryContainers: []PrimaryContainer{ContainerTable},
+		Aliases:           []string{"yugabyte", "ysql"},
+		Features:          FeatureMatrix{TypeSystem: TypeSystemPostgreSQL},
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	id, ok := ParseID("YSQL")
+	if !ok || id != "yugabytedb" {
+		t.Fatalf("ParseID(YSQL) = %q, %v", id, ok)
+	}
+	if !SupportsCDC("yugabytedb") || !SupportsCDCString("yugabyte") {
+		t.Error("registered database should support CDC")
+	}
+
+	tests := []Capability{
+		{Name: "No ID"},
+		{Name: "Upper", ID: "Upper"},
+		{Name: "Postgres clone", ID: "pgclone", Aliases: []string{"postgresql"}},
+		{Name: "YugabyteDB", ID: "yugabyte2"},
+	}
+	for _, c := range tests {
+		if err := Register(c); err == nil {
+			t.Errorf("Register(%s) should fail", c.ID)
+		}
+	}
+}
+
+func TestOverride(t *testing.T) {
+	restoreRegistry(t)
+
+	off := false
+	err := Override("mysql", CapabilityOverride{
+		SupportsCDC:     &off,
+		SystemDatabases: []string{"mysql", "sys"},
+		Aliases:         []string{"percona"},
+		MinVersions:     map[VersionedFeature]string{FeatureJSONType: "", FeatureGeneratedColumns: "8.0.13"},
+	})
+	if err != nil {
+		t.Fatalf("Override: %v", err)
+	}
+	c := MustGet(MySQL)
+	if c.SupportsCDC {
+		t.Error("CDC should be disabled")
+	}
+	if len(c.SystemDatabases) != 2 || c.SystemDatabases[1] != "sys" {
+		t.Errorf("SystemDatabases = %v", c.SystemDatabases)
+	}
+	if !c.HasSystemDatabase || c.DefaultPort != 3306 {
+		t.Error("fields that are not overridden should be kept")
+	}
+	if id, _ := ParseID("percona"); id != MySQL {
+		t.Errorf("ParseID(percona) = %q", id)
+	}
+	if SupportsAtVersion(MySQL, "8.0.40", FeatureJSONType) {
+		t.Error("JSON type should be removed")
+	}
+	if SupportsAtVersion(MySQL, "8.0.12", FeatureGeneratedColumns) || !SupportsAtVersion(MySQL, "8.0.13", FeatureGeneratedColumns) {
+		t.Error("generated columns should be supported from 8.0.13")
+	}
+
+	if err := Override("unknown", CapabilityOverride{}); err == nil {
+		t.Error("overriding an unknown database should fail")
+	}
+	if err := Override("mysql", CapabilityOverride{Aliases: []string{"postgresql"}}); err == nil {
+		t.Error("an alias of another database should be rejected")
+	}
+}
+
+func TestApplyIsAtomic(t *testing.T) {
+	restoreRegistry(t)
+
+	cfg, err := ParseConfig([]byte(`{
+		"databases": [{"id": "yugabytedb", "name": "YugabyteDB"}],
+		"overrides": {"postgres": {"minVersions": {"generated_columns": "not a version"}}}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate should fail")
+	}
+	if err := Apply(cfg); err == nil {
+		t.Fatal("Apply should fail")
+	}
+	if _, ok := Get("yugabytedb"); ok {
+		t.Error("a configuration that fails should not be applied in part")
+	}
+
+	if _, err := ParseConfig([]byte(`{"overrides": {"mysql": {"cdc": false}}}`)); err == nil {
+		t.Error("ParseConfig should reject unknown keys")
+	}
+}
+
+func TestApplyFromEnv(t *testing.T) {
+	restoreRegistry(t)
+
+	t.Setenv(EnvConfig, `{"overrides": {"PostgreSQL": {"systemDatabases": ["postgres", "rdsadmin"]}}}`)
+	if err := ApplyFromEnv(); err != nil {
+		t.Fatalf("ApplyFromEnv: %v", err)
+	}
+	if dbs := MustGet(PostgreSQL).SystemDatabases; len(dbs) != 2 || dbs[1] != "rdsadmin" {
+		t.Errorf("SystemDatabases = %v", dbs)
+	}
+}
@@ -0,0 +1,142 @@
+package dbcapabilities
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+)
+
+//go:generate go run ./internal/supportgen -anchor ../../services/anchor -out support_matrix.json
+
+// Operation identifies an operation of the support matrix.
+type Operation string
+
+const (
+	// Instances
+	OpConnectInstance Operation = "connect_instance"
+	OpListDatabases   Operation = "list_databases"
+	OpCreateDatabase  Operation = "create_database"
+	OpDropDatabase    Operation = "drop_database"
+
+	// Schemas
+	OpSchemaDiscovery            Operation = "schema_discovery"
+	OpIncrementalSchemaDiscovery Operation = "incremental_schema_discovery"
+	OpSchemaCreation             Operation = "schema_creation"
+
+	// Data
+	OpRead         Operation = "read"
+	OpStream       Operation = "stream"
+	OpParallelRead Operation = "parallel_read"
+	OpInsert       Operation = "insert"
+	OpBulkLoad     Operation = "bulk_load"
+	OpUpdate       Operation = "update"
+	OpUpsert       Operation = "upsert"
+	OpDelete       Operation = "delete"
+	OpWipe         Operation = "wipe"
+	OpSandbox      Operation = "sandbox"
+
+	// Queries
+	OpQuery         Operation = "query"
+	OpReadOnlyQuery Operation = "read_only_query"
+	OpQueryPlan     Operation = "query_plan"
+	OpVectorSearch  Operation = "vector_search"
+	OpSampleEdges   Operation = "sample_edges"
+
+	// Replication
+	OpCDC      Operation = "cdc"
+	OpCDCApply Operation = "cdc_apply"
+
+	// Metadata
+	OpMetadata Operation = "metadata"
+	OpCommand  Operation = "command"
+)
+
+// Operations lists the operations of the support matrix, in display order.
+var Operations = []Operation{
+	OpConnectInstance, OpListDatabases, OpCreateDatabase, OpDropDatabase,
+	OpSchemaDiscovery, OpIncrementalSchemaDiscovery, OpSchemaCreation,
+	OpRead, OpStream, OpParallelRead, OpInsert, OpBulkLoad, OpUpdate, OpUpsert, OpDelete, OpWipe, OpSandbox,
+	OpQuery, OpReadOnlyQuery, OpQueryPlan, OpVectorSearch, OpSampleEdges,
+	OpCDC, OpCDCApply,
+	OpMetadata, OpCommand,
+}
+
+// SupportStatus tells how completely an adapter implements an operation.
+type SupportStatus string
+
+const (
+	Supported   SupportStatus = "supported"
+	Partial     SupportStatus = "partial" // Implemented, but unsupported in some cases (see the caveats)
+	Unsupported SupportStatus = "unsupported"
+)
+
+// Editions of reDB an adapter is built into.
+const (
+	EditionCommunity  = "community"
+	EditionEnterprise = "enterprise"
+)
+
+// OperationSupport describes the support of an operation by an adapter.
+type OperationSupport struct {
+	Status     SupportStatus `json:"status"`
+	Caveats    []string      `json:"caveats,omitempty"`
+	Mechanisms []string      `json:"mechanisms,omitempty"` // CDC or bulk load mechanisms, from the capabilities
+}
+
+// AdapterSupport describes the operations supported by the adapter of a database.
+type AdapterSupport struct {
+	ID         DatabaseType                   `json:"id"`
+	Name       string                         `json:"name"`
+	Edition    string                         `json:"edition"` // Lowest edition the adapter is built into
+	Operations map[Operation]OperationSupport `json:"operations"`
+}
+
+// SupportMatrix lists the operations supported by every registered adapter, sorted by
+// database ID.
+type SupportMatrix struct {
+	Operations []Operation      `json:"operations"`
+	Databases  []AdapterSupport `json:"databases"`
+}
+
+// supportMatrixJSON is generated from the adapters of the anchor service by go generate, so
+// that it never drifts from the code. Do not edit it by hand.
+//
+//go:embed support_matrix.json
+var supportMatrixJSON []byte
+
+var supportMatrix SupportMatrix
+
+func init() {
+	if err := json.Unmarshal(supportMatrixJSON, &supportMatrix); err != nil {
+		panic("dbcapabilities: invalid support matrix: " + err.Error())
+	}
+	sort.Slice(supportMatrix.Databases, func(i, j int) bool {
+		return supportMatrix.Databases[i].ID < supportMatrix.Databases[j].ID
+	})
+}
+
+// GetSupportMatrix returns the support matrix of the registered adapters.
+func GetSupportMatrix() SupportMatrix {
+	return supportMatrix
+}
+
+// GetSupport returns the operations supported by the adapter of a database, and false if the
+// database has no adapter.
+func GetSupport(id DatabaseType) (AdapterSupport, bool) {
+	for _, support := range supportMatrix.Databases {
+		if support.ID == id {
+			return support, true
+		}
+	}
+	return AdapterSupport{}, false
+}
+
+// SupportsOperation reports whether the adapter of a database implements an operation, fully
+// or partially.
+func SupportsOperation(id DatabaseType, op Operation) bool {
+	support, ok := GetSupport(id)
+	if !ok {
+		return false
+	}
+	return support.Operations[op].Status != "" && support.Operations[op].Status != Unsupported
+}
@@ -0,0 +1,29 @@
+package dbcapabilities
+
+import "testing"
+
+func TestSupportMatrixCoversOperations(t *testing.T) {
+	matrix := GetSupportMatrix()
+	if len(matrix.Databases) == 0 {
+		t.Fatal("support matrix has no databases")
+	}
+	for _, support := range matrix.Databases {
+		if _, ok := Get(support.ID); !ok {
+			t.Errorf("%s: no capabilities", support.ID)
+		}
+		for _, op := range Operations {
+			if support.Operations[op].Status == "" {
+				t.Errorf("%s: no status for %s", support.ID, op)
+			}
+		}
+	}
+}
+
+func TestSupportsOperation(t *testing.T) {
+	if !SupportsOperation(PostgreSQL, OpCDC) {
+		t.Error("PostgreSQL should support CDC")
+	}
+	if SupportsOperation(DatabaseType("unknown"), OpRead) {
+		t.Error("unknown database type should support nothing")
+	}
+}
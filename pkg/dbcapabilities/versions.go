@@ -0,0 +1,145 @@
+package dbcapabilities
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionedFeature is a feature a database supports from a server version on.
+type VersionedFeature string
+
+const (
+	// Columns computed from an expression over the other columns of their row.
+	FeatureGeneratedColumns VersionedFeature = "generated_columns"
+	// Columns numbered by the database with standard identity syntax (or its equivalent).
+	FeatureIdentityColumns VersionedFeature = "identity_columns"
+	// Row changes streamed to consumers, which the CDC of the database relies on.
+	FeatureLogicalReplication VersionedFeature = "logical_replication"
+	// Schema changes (DDL) streamed along with row changes.
+	FeatureDDLReplication VersionedFeature = "ddl_replication"
+	// A native JSON column type.
+	FeatureJSONType VersionedFeature = "json_type"
+	// A binary, indexable JSON column type (JSONB).
+	FeatureJSONBType VersionedFeature = "jsonb_type"
+	// Functions or operators extracting values from JSON documents.
+	FeatureJSONFunctions VersionedFeature = "json_functions"
+)
+
+// Minimum versions shared by PostgreSQL and the databases running on its server
+var postgresMinVersions = map[VersionedFeature]string{
+	FeatureGeneratedColumns:   "12",
+	FeatureIdentityColumns:    "10",
+	FeatureLogicalReplication: "10",
+	FeatureJSONType:           "9.2",
+	FeatureJSONBType:          "9.4",
+	FeatureJSONFunctions:      "9.3",
+}
+
+// Version is a database server version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+){0,2}`)
+
+// ParseVersion reads the first version number in a string, so version strings as servers
+// report them are accepted, e.g. "PostgreSQL 16.2 on x86_64-pc-linux-gnu" or
+// "10.11.6-MariaDB-1:10.11.6+maria~ubu2204".
+func ParseVersion(s string) (Version, error) {
+	match := versionPattern.FindString(s)
+	if match == "" {
+		return Version{}, fmt.Errorf("no version number in %q", s)
+	}
+	var parts [3]int
+	for i, part := range strings.Split(match, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		parts[i] = n
+	}
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, nil
+}
+
+// String returns the version as major.minor.patch.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero reports whether the version is unknown.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as or newer than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range [...]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether v is o or newer.
+func (v Version) AtLeast(o Version) bool {
+	return v.Compare(o) >= 0
+}
+
+// VersionedCapability is the capability of a database at a server version.
+type VersionedCapability struct {
+	Capability
+
+	// Version of the server; zero if it is unknown.
+	Version Version
+}
+
+// GetForVersion returns the capability of a database at a server version, as reported by the
+// server or stored with the database. A version that cannot be read is treated as unknown, and
+// an unknown version as the latest one. It returns false if the database is unknown.
+func GetForVersion(id DatabaseType, version string) (VersionedCapability, bool) {
+	c, ok := Get(id)
+	if !ok {
+		return VersionedCapability{}, false
+	}
+	v, _ := ParseVersion(version)
+	return VersionedCapability{Capability: c, Version: v}, true
+}
+
+// Supports reports whether the server supports a feature at its version. Features the database
+// has no minimum version for are not supported.
+func (c VersionedCapability) Supports(f VersionedFeature) bool {
+	min, ok := c.Features.MinVersions[f]
+	if !ok {
+		return false
+	}
+	if c.Version.IsZero() {
+		return true
+	}
+	v, err := ParseVersion(min)
+	if err != nil {
+		return false
+	}
+	return c.Version.AtLeast(v)
+}
+
+// SupportsAtVersion reports whether a database supports a feature at a server version.
+func SupportsAtVersion(id DatabaseType, version string, f VersionedFeature) bool {
+	c, ok := GetForVersion(id, version)
+	return ok && c.Supports(f)
+}
+
+// MinVersion returns the version a database supports a feature from, and false if it does
+// not support it at any version.
+func MinVersion(id DatabaseType, f VersionedFeature) (string, bool) {
+	c, _ := Get(id)
+	min, ok := c.Features.MinVersions[f]
+	return min, ok
+}
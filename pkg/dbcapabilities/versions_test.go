@@ -0,0 +1,62 @@
+package dbcapabilities
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"16", Version{16, 0, 0}},
+		{"9.4", Version{9, 4, 0}},
+		{"PostgreSQL 16.2 on x86_64-pc-linux-gnu, compiled by gcc", Version{16, 2, 0}},
+		{"8.0.35-0ubuntu0.22.04.1", Version{8, 0, 35}},
+		{"10.11.6-MariaDB-1:10.11.6+maria~ubu2204", Version{10, 11, 6}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseVersion("unknown"); err == nil {
+		t.Error("ParseVersion should fail without a version number")
+	}
+}
+
+func TestMinVersionsParse(t *testing.T) {
+	for id, c := range All {
+		for f, min := range c.Features.MinVersions {
+			if _, err := ParseVersion(min); err != nil {
+				t.Errorf("%s: %s: %v", id, f, err)
+			}
+		}
+	}
+}
+
+func TestSupportsAtVersion(t *testing.T) {
+	tests := []struct {
+		id      DatabaseType
+		version string
+		feature VersionedFeature
+		want    bool
+	}{
+		{PostgreSQL, "11.22", FeatureGeneratedColumns, false},
+		{PostgreSQL, "12.0", FeatureGeneratedColumns, true},
+		{PostgreSQL, "PostgreSQL 16.2 on x86_64-pc-linux-gnu", FeatureGeneratedColumns, true},
+		{PostgreSQL, "9.3.25", FeatureJSONBType, false},
+		{PostgreSQL, "16", FeatureDDLReplication, false},
+		{MySQL, "5.7.7", FeatureJSONType, false},
+		{MySQL, "5.7.8", FeatureJSONType, true},
+		{MariaDB, "10.1.48-MariaDB", FeatureJSONType, false},
+		// An unknown version is treated as the latest one
+		{PostgreSQL, "", FeatureGeneratedColumns, true},
+		{MongoDB, "", FeatureGeneratedColumns, false},
+		{DatabaseType("unknown"), "", FeatureJSONType, false},
+	}
+	for _, tt := range tests {
+		if got := SupportsAtVersion(tt.id, tt.version, tt.feature); got != tt.want {
+			t.Errorf("SupportsAtVersion(%s, %q, %s) = %v, want %v", tt.id, tt.version, tt.feature, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package encryption
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -10,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/redbco/redb-open/pkg/keyring"
 )
@@ -235,6 +238,129 @@ func DecryptPassword(tenantID string, encryptedPassword string) (string, error)
 	return manager.Decrypt(tenantID, encryptedPassword)
 }
 
+// secretPrefix marks the secrets encrypted by EncryptSecret
+const secretPrefix = "redb-secret:v1:"
+
+// EncryptSecret encrypts a secret of any length, such as a private key, using the tenant's
+// public key. RSA-OAEP only encrypts payloads shorter than the key, so the secret is encrypted
+// with a random AES-256-GCM key, which is encrypted with the tenant's public key.
+func (tem *TenantEncryptionManager) EncryptSecret(tenantID string, secret string) (string, error) {
+	if tenantID == "" {
+		return "", errors.New("tenant ID is required")
+	}
+	if secret == "" {
+		return "", errors.New("secret is required")
+	}
+
+	publicKey, err := tem.getTenantPublicKey(tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tenant public key: %w", err)
+	}
+	return sealSecret(publicKey, secret)
+}
+
+// DecryptSecret decrypts a secret encrypted by EncryptSecret using the tenant's private key
+func (tem *TenantEncryptionManager) DecryptSecret(tenantID string, encryptedSecret string) (string, error) {
+	if tenantID == "" {
+		return "", errors.New("tenant ID is required")
+	}
+	if encryptedSecret == "" {
+		return "", errors.New("encrypted secret is required")
+	}
+
+	privateKey, err := tem.getTenantPrivateKey(tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tenant private key: %w", err)
+	}
+	return openSecret(privateKey, encryptedSecret)
+}
+
+// IsEncryptedSecret reports whether a value was encrypted by EncryptSecret, to tell it from a
+// value stored in plaintext
+func IsEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, secretPrefix)
+}
+
+// EncryptSecret is a convenience function for encrypting secrets longer than passwords
+func EncryptSecret(tenantID string, secret string) (string, error) {
+	return NewTenantEncryptionManager().EncryptSecret(tenantID, secret)
+}
+
+// DecryptSecret is a convenience function for decrypting secrets encrypted by EncryptSecret
+func DecryptSecret(tenantID string, encryptedSecret string) (string, error) {
+	return NewTenantEncryptionManager().DecryptSecret(tenantID, encryptedSecret)
+}
+
+// sealSecret encrypts a secret with a random AES-256-GCM key, and the key with an RSA public key,
+// as the prefix, the encrypted key and the nonce with the ciphertext, in base64
+func sealSecret(publicKey *rsa.PublicKey, secret string) (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt secret key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	ciphertext := gcm.Seal(nonce, nonce, []byte(secret), nil)
+
+	return secretPrefix + base64.StdEncoding.EncodeToString(encryptedKey) + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// openSecret decrypts a secret encrypted by sealSecret with the RSA private key
+func openSecret(privateKey *rsa.PrivateKey, sealed string) (string, error) {
+	if !IsEncryptedSecret(sealed) {
+		return "", errors.New("value is not an encrypted secret")
+	}
+	encodedKey, encodedCiphertext, ok := strings.Cut(strings.TrimPrefix(sealed, secretPrefix), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted secret")
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret key: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encodedCiphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+
+	key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, encryptedKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
 // ValidateTenantKeys checks if both public and private keys exist for a tenant
 func (tem *TenantEncryptionManager) ValidateTenantKeys(tenantID string) error {
 	if tenantID == "" {
@@ -0,0 +1,401 @@
+// Package errorcatalog defines stable, user-facing error codes (REDB-xxxx) with a description,
+// common causes and remediation steps for each, so that clients can show actionable guidance
+// instead of raw error messages.
+//
+// Codes travel as a prefix of error messages, which survives gRPC hops and errors wrapped with
+// fmt.Errorf:
+//
+//	return errorcatalog.Status(errorcatalog.DatabaseUnreachable, "failed to connect to %s: %v", name, err)
+//	// rpc error: code = Unavailable desc = REDB-2001: failed to connect to orders: ...
+//
+// Errors raised without a code are classified from their message and gRPC status code.
+package errorcatalog
+
+import (
+	"regexp"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code is a stable error code of the form REDB-xxxx. The first digit groups codes by area:
+// 1 for requests and the API, 2 for database connectivity, 3 for schemas and mappings, 4 for
+// replication and data movement, and 5 for the mesh and services.
+type Code string
+
+// Request and API errors
+const (
+	Internal          Code = "REDB-1000"
+	InvalidArgument   Code = "REDB-1001"
+	NotFound          Code = "REDB-1002"
+	AlreadyExists     Code = "REDB-1003"
+	PermissionDenied  Code = "REDB-1004"
+	Unauthenticated   Code = "REDB-1005"
+	Unavailable       Code = "REDB-1006"
+	DeadlineExceeded  Code = "REDB-1007"
+	ResourceExhausted Code = "REDB-1008"
+	Unimplemented     Code = "REDB-1009"
+)
+
+// Database connectivity errors
+const (
+	DatabaseUnreachable      Code = "REDB-2001"
+	DatabaseAuthFailed       Code = "REDB-2002"
+	DatabaseTLSFailed        Code = "REDB-2003"
+	DatabaseNotFound         Code = "REDB-2004"
+	DatabaseTypeNotSupported Code = "REDB-2005"
+	DatabasePermissionDenied Code = "REDB-2006"
+)
+
+// Schema and mapping errors
+const (
+	SchemaObjectNotFound  Code = "REDB-3001"
+	MappingInvalid        Code = "REDB-3002"
+	TypeConversionFailed  Code = "REDB-3003"
+	SchemaDeployConflict  Code = "REDB-3004"
+	OperationNotSupported Code = "REDB-3005"
+)
+
+// Replication and data movement errors
+const (
+	CDCNotConfigured       Code = "REDB-4001"
+	ReplicationSlotInUse   Code = "REDB-4002"
+	ConstraintViolation    Code = "REDB-4003"
+	ReplicationNotRunning  Code = "REDB-4004"
+	ConflictKeyUnsupported Code = "REDB-4005"
+)
+
+// Mesh and service errors
+const (
+	AnchorUnavailable Code = "REDB-5001"
+	NodeUnreachable   Code = "REDB-5002"
+	ServiceNotReady   Code = "REDB-5003"
+)
+
+// Entry describes an error code.
+type Entry struct {
+	Code        Code     `json:"code"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Causes      []string `json:"causes,omitempty"`
+	Remediation []string `json:"remediation,omitempty"`
+
+	// GRPCCode is the status code errors with this code are returned with
+	GRPCCode codes.Code `json:"-"`
+
+	// patterns match messages of errors raised without a code
+	patterns []*regexp.Regexp
+}
+
+var catalog = []Entry{
+	{
+		Code:        Internal,
+		Title:       "Internal error",
+		Description: "The request failed because of an unexpected error in a reDB service.",
+		Causes:      []string{"A bug or an unhandled condition in a service"},
+		Remediation: []string{
+			"Retry the request",
+			"If the error persists, collect the service logs and report the issue",
+		},
+		GRPCCode: codes.Internal,
+	},
+	{
+		Code:        InvalidArgument,
+		Title:       "Invalid request",
+		Description: "The request is missing a required value or contains a value that is not valid.",
+		Causes:      []string{"A required flag or field is missing", "A name, URI or option is malformed"},
+		Remediation: []string{"Check the command or request against its documentation and correct the highlighted value"},
+		GRPCCode:    codes.InvalidArgument,
+	},
+	{
+		Code:        NotFound,
+		Title:       "Resource not found",
+		Description: "The requested workspace, database, mapping or other resource does not exist.",
+		Causes:      []string{"The name is misspelled", "The resource was deleted", "The resource belongs to another workspace or tenant"},
+		Remediation: []string{"List the resources of the workspace to check the name", "Check that the right workspace is selected"},
+		GRPCCode:    codes.NotFound,
+	},
+	{
+		Code:        AlreadyExists,
+		Title:       "Resource already exists",
+		Description: "A resource with the same name already exists.",
+		Causes:      []string{"The resource was created by an earlier request"},
+		Remediation: []string{"Choose another name, or modify the existing resource instead"},
+		GRPCCode:    codes.AlreadyExists,
+	},
+	{
+		Code:        PermissionDenied,
+		Title:       "Permission denied",
+		Description: "The authenticated user is not allowed to perform the operation.",
+		Causes:      []string{"The user's roles do not grant the operation on this resource"},
+		Remediation: []string{"Ask a tenant administrator to grant the required permission"},
+		GRPCCode:    codes.PermissionDenied,
+	},
+	{
+		Code:        Unauthenticated,
+		Title:       "Not authenticated",
+		Description: "The request has no valid session.",
+		Causes:      []string{"The session expired", "The access token was revoked", "No credentials were sent"},
+		Remediation: []string{"Log in again (redb-cli auth login)"},
+		GRPCCode:    codes.Unauthenticated,
+	},
+	{
+		Code:        Unavailable,
+		Title:       "Service unavailable",
+		Description: "A reDB service needed for the request could not be reached.",
+		Causes:      []string{"The service is starting or restarting", "The service stopped"},
+		Remediation: []string{"Check the node status (redb-cli node status)", "Retry once all services report healthy"},
+		GRPCCode:    codes.Unavailable,
+	},
+	{
+		Code:        DeadlineExceeded,
+		Title:       "Request timed out",
+		Description: "The request did not complete in time.",
+		Causes:      []string{"The database or a service is slow or overloaded", "The operation processes a large amount of data"},
+		Remediation: []string{"Retry the request", "For large operations, use the streaming or background variant if available"},
+		GRPCCode:    codes.DeadlineExceeded,
+	},
+	{
+		Code:        ResourceExhausted,
+		Title:       "Limit exceeded",
+		Description: "The request exceeded a quota, rate limit or size limit.",
+		Causes:      []string{"Too many requests in a short time", "The request or response is too large"},
+		Remediation: []string{"Wait and retry", "Reduce the size of the request, e.g. with a smaller batch size"},
+		GRPCCode:    codes.ResourceExhausted,
+	},
+	{
+		Code:        Unimplemented,
+		Title:       "Operation not implemented",
+		Description: "The operation is not available in this version of reDB.",
+		Remediation: []string{"Check the release notes for the version that adds the operation"},
+		GRPCCode:    codes.Unimplemented,
+	},
+	{
+		Code:        DatabaseUnreachable,
+		Title:       "Database unreachable",
+		Description: "The database server could not be reached from the anchor service.",
+		Causes: []string{
+			"Wrong host or port",
+			"The database server is down",
+			"A firewall or security group blocks the connection from the reDB node",
+		},
+		Remediation: []string{
+			"Check the host and port of the instance or database",
+			"Check that the database accepts connections from the reDB node's address",
+		},
+		GRPCCode: codes.Unavailable,
+		patterns: compile(`connection refused`, `no such host`, `i/o timeout`, `network is unreachable`, `no route to host`, `dial tcp`),
+	},
+	{
+		Code:        DatabaseAuthFailed,
+		Title:       "Database authentication failed",
+		Description: "The database rejected the configured credentials.",
+		Causes:      []string{"Wrong username or password", "The password was rotated", "The user is not allowed to connect from the reDB node"},
+		Remediation: []string{"Update the credentials of the database (redb-cli databases modify)", "Check the database's access rules for the reDB node"},
+		GRPCCode:    codes.Unauthenticated,
+		patterns:    compile(`password authentication failed`, `access denied for user`, `login failed for user`),
+	},
+	{
+		Code:        DatabaseTLSFailed,
+		Title:       "Database TLS handshake failed",
+		Description: "A secure connection to the database could not be established.",
+		Causes:      []string{"The server requires TLS but it is disabled", "The server certificate is not trusted", "The certificate does not match the host name"},
+		Remediation: []string{"Check the SSL mode and certificates of the database", "Provide the CA certificate that signed the server certificate"},
+		GRPCCode:    codes.Unavailable,
+		patterns:    compile(`x509:`, `tls: `, `certificate`, `ssl is not enabled`, `server does not support ssl`),
+	},
+	{
+		Code:        DatabaseNotFound,
+		Title:       "Database does not exist",
+		Description: "The database server is reachable but the database name does not exist on it.",
+		Causes:      []string{"The database name is misspelled", "The database was dropped"},
+		Remediation: []string{"Check the database name", "Create the database on the server, or connect the instance and create it with reDB"},
+		GRPCCode:    codes.NotFound,
+		patterns:    compile(`database "[^"]*" does not exist`, `unknown database`, `cannot open database`),
+	},
+	{
+		Code:        DatabaseTypeNotSupported,
+		Title:       "Database type not supported",
+		Description: "The database type is unknown or has no adapter on this node.",
+		Remediation: []string{"Check the database type against the list of supported databases"},
+		GRPCCode:    codes.InvalidArgument,
+		patterns:    compile(`unsupported database type`, `no adapter registered`),
+	},
+	{
+		Code:        DatabasePermissionDenied,
+		Title:       "Insufficient database privileges",
+		Description: "The database user lacks a privilege needed for the operation.",
+		Causes:      []string{"The user can connect but not read catalogs, tables or replication streams"},
+		Remediation: []string{"Grant the privileges listed in the database's setup guide to the configured user"},
+		GRPCCode:    codes.PermissionDenied,
+		patterns:    compile(`permission denied for`, `insufficient privilege`, `command denied to user`),
+	},
+	{
+		Code:        SchemaObjectNotFound,
+		Title:       "Table or column not found",
+		Description: "A table or column referenced by the request does not exist in the database.",
+		Causes:      []string{"The schema changed since it was last discovered", "The name is misspelled or differs in case"},
+		Remediation: []string{"Refresh the schema of the database", "Check the mapping rules for renamed or dropped columns"},
+		GRPCCode:    codes.NotFound,
+		patterns:    compile(`relation "[^"]*" does not exist`, `column "[^"]*" .*does not exist`, `table .* doesn't exist`, `unknown column`, `invalid object name`),
+	},
+	{
+		Code:        MappingInvalid,
+		Title:       "Mapping is not valid",
+		Description: "The mapping cannot be used because its rules do not match the source and target schemas.",
+		Causes:      []string{"A rule references a column that no longer exists", "A required target column has no rule"},
+		Remediation: []string{"Validate the mapping and fix the reported rules", "Regenerate the mapping from the current schemas"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`mapping .*(is not valid|validation failed)`, `no mapping rules`),
+	},
+	{
+		Code:        TypeConversionFailed,
+		Title:       "Type conversion failed",
+		Description: "A value or column type could not be converted to the target database.",
+		Causes:      []string{"The target type cannot hold the source values", "No conversion exists between the two types"},
+		Remediation: []string{"Add a transformation to the mapping rule", "Choose a wider target type"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`cannot convert`, `conversion failed`, `invalid input syntax for type`, `out of range`),
+	},
+	{
+		Code:        SchemaDeployConflict,
+		Title:       "Schema deployment conflict",
+		Description: "The schema could not be deployed because the target already has conflicting objects.",
+		Causes:      []string{"A table with the same name exists on the target", "The target schema changed since the deployment was planned"},
+		Remediation: []string{"Compare the schemas and resolve the conflicting objects", "Deploy to an empty database or a new schema"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`(relation|table|type|index|collection) .*already exists`),
+	},
+	{
+		Code:        OperationNotSupported,
+		Title:       "Operation not supported by the database",
+		Description: "The database type does not support the requested operation.",
+		Remediation: []string{"Check the capabilities of the database type", "Use a database type that supports the operation"},
+		GRPCCode:    codes.Unimplemented,
+		patterns:    compile(`operation not supported`, `not supported by`, `unsupported operation`),
+	},
+	{
+		Code:        CDCNotConfigured,
+		Title:       "Change data capture not configured",
+		Description: "The source database is not configured for change data capture.",
+		Causes:      []string{"Logical replication or the binary log is disabled", "The user lacks the replication privilege"},
+		Remediation: []string{
+			"Enable change data capture on the source (e.g. wal_level = logical on PostgreSQL, binlog_format = ROW on MySQL)",
+			"Grant the replication privilege to the configured user",
+		},
+		GRPCCode: codes.FailedPrecondition,
+		patterns: compile(`wal_level`, `logical decoding requires`, `binlog`, `must be superuser or replication role`),
+	},
+	{
+		Code:        ReplicationSlotInUse,
+		Title:       "Replication slot in use",
+		Description: "The replication slot for the relationship is already in use or already exists.",
+		Causes:      []string{"Another replication of the same source is running", "A slot of a stopped relationship was not cleaned up"},
+		Remediation: []string{"Stop the other replication, or remove the relationship that owns the slot"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`replication slot .* (is active|already exists)`),
+	},
+	{
+		Code:        ConstraintViolation,
+		Title:       "Constraint violation on target",
+		Description: "Rows could not be written because they violate a constraint of the target table.",
+		Causes:      []string{"Rows were already copied by an earlier run", "The target has constraints the source does not enforce"},
+		Remediation: []string{"Configure conflict keys on the mapping so rows are upserted", "Clean the target table before the initial copy"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`duplicate key`, `unique constraint`, `violates .* constraint`, `duplicate entry`),
+	},
+	{
+		Code:        ReplicationNotRunning,
+		Title:       "Replication not running",
+		Description: "The relationship's replication is not running on the node.",
+		Causes:      []string{"The relationship was stopped", "The anchor service restarted"},
+		Remediation: []string{"Resume or start the relationship"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`replication .*not (found|running|active)`),
+	},
+	{
+		Code:        ConflictKeyUnsupported,
+		Title:       "Upsert not supported by target",
+		Description: "The mapping has conflict keys but the target database cannot upsert rows.",
+		Remediation: []string{"Remove the conflict keys from the mapping", "Replicate to a database type that supports upserts"},
+		GRPCCode:    codes.FailedPrecondition,
+		patterns:    compile(`upsert data.*not (yet implemented|supported)`),
+	},
+	{
+		Code:        AnchorUnavailable,
+		Title:       "Anchor service unavailable",
+		Description: "The anchor service, which connects to databases, could not be reached.",
+		Causes:      []string{"The anchor service is starting or crashed"},
+		Remediation: []string{"Check the node status (redb-cli node status) and the anchor logs"},
+		GRPCCode:    codes.Unavailable,
+		patterns:    compile(`failed to connect to anchor`, `anchor service .*unavailable`),
+	},
+	{
+		Code:        NodeUnreachable,
+		Title:       "Mesh node unreachable",
+		Description: "The mesh node owning the resource could not be reached.",
+		Causes:      []string{"The node is offline", "The network between the nodes is down"},
+		Remediation: []string{"Check the mesh status (redb-cli mesh show)", "Retry once the node is back online"},
+		GRPCCode:    codes.Unavailable,
+		patterns:    compile(`node .*(unreachable|not connected|offline)`),
+	},
+	{
+		Code:        ServiceNotReady,
+		Title:       "Service not ready",
+		Description: "A reDB service is running but has not finished initializing.",
+		Remediation: []string{"Retry in a few seconds"},
+		GRPCCode:    codes.Unavailable,
+		patterns:    compile(`not (yet )?initialized`, `not ready`),
+	},
+}
+
+// byCode indexes the catalog
+var byCode = func() map[Code]*Entry {
+	index := make(map[Code]*Entry, len(catalog))
+	for i := range catalog {
+		index[catalog[i].Code] = &catalog[i]
+	}
+	return index
+}()
+
+// byGRPCCode maps gRPC status codes to the generic code of errors without a more specific one
+var byGRPCCode = map[codes.Code]Code{
+	codes.Internal:           Internal,
+	codes.Unknown:            Internal,
+	codes.InvalidArgument:    InvalidArgument,
+	codes.NotFound:           NotFound,
+	codes.AlreadyExists:      AlreadyExists,
+	codes.PermissionDenied:   PermissionDenied,
+	codes.Unauthenticated:    Unauthenticated,
+	codes.Unavailable:        Unavailable,
+	codes.DeadlineExceeded:   DeadlineExceeded,
+	codes.ResourceExhausted:  ResourceExhausted,
+	codes.Unimplemented:      Unimplemented,
+	codes.FailedPrecondition: InvalidArgument,
+	codes.OutOfRange:         InvalidArgument,
+}
+
+func compile(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = regexp.MustCompile(`(?i)` + pattern)
+	}
+	return compiled
+}
+
+// All returns the entries of the catalog, sorted by code.
+func All() []Entry {
+	entries := make([]Entry, len(catalog))
+	copy(entries, catalog)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// Lookup returns the entry of a code.
+func Lookup(code Code) (Entry, bool) {
+	entry, ok := byCode[code]
+	if !ok {
+		return Entry{}, false
+	}
+	return *entry, true
+}
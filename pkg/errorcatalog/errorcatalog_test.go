@@ -0,0 +1,91 @@
+package errorcatalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCatalog_Entries(t *testing.T) {
+	seen := make(map[Code]bool)
+	for _, entry := range All() {
+		if !codePattern.MatchString(string(entry.Code)) {
+			t.Errorf("%s: code does not match REDB-xxxx", entry.Code)
+		}
+		if seen[entry.Code] {
+			t.Errorf("%s: duplicate code", entry.Code)
+		}
+		seen[entry.Code] = true
+		if entry.Title == "" || entry.Description == "" || len(entry.Remediation) == 0 {
+			t.Errorf("%s: title, description and remediation are required", entry.Code)
+		}
+		if entry.GRPCCode == codes.OK {
+			t.Errorf("%s: missing gRPC code", entry.Code)
+		}
+	}
+
+	if _, ok := Lookup(DatabaseUnreachable); !ok {
+		t.Error("expected to find DatabaseUnreachable")
+	}
+	if _, ok := Lookup("REDB-9999"); ok {
+		t.Error("expected unknown codes not to be found")
+	}
+}
+
+func TestStatus_CarriesCode(t *testing.T) {
+	cause := io.ErrUnexpectedEOF
+	err := Status(DatabaseUnreachable, "failed to connect to %s: %v", "orders", cause)
+
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatal("expected a gRPC status error")
+	}
+	if st.Code() != codes.Unavailable {
+		t.Errorf("expected Unavailable, got %v", st.Code())
+	}
+	if !strings.HasPrefix(st.Message(), "REDB-2001: failed to connect to orders") {
+		t.Errorf("unexpected message %q", st.Message())
+	}
+
+	// The code survives errors wrapped and re-raised by other services
+	wrapped := status.Error(codes.Internal, fmt.Sprintf("failed to add database: %v", st.Message()))
+	if code := FromError(wrapped); code != DatabaseUnreachable {
+		t.Errorf("expected %s, got %s", DatabaseUnreachable, code)
+	}
+
+	coded := Errorf(MappingInvalid, "mapping %s: %v", "users", cause)
+	if !errors.Is(coded, cause) {
+		t.Error("expected the error to unwrap to its cause")
+	}
+	if code := FromError(fmt.Errorf("validate: %w", coded)); code != MappingInvalid {
+		t.Errorf("expected %s, got %s", MappingInvalid, code)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		grpcCode codes.Code
+		message  string
+		expected Code
+	}{
+		{codes.Internal, "dial tcp 10.0.0.5:5432: connect: connection refused", DatabaseUnreachable},
+		{codes.Internal, `FATAL: password authentication failed for user "app"`, DatabaseAuthFailed},
+		{codes.Internal, `ERROR: relation "orders" does not exist`, SchemaObjectNotFound},
+		{codes.Internal, `duplicate key value violates unique constraint "users_pkey"`, ConstraintViolation},
+		{codes.Internal, "logical decoding requires wal_level >= logical", CDCNotConfigured},
+		{codes.NotFound, "workspace not found", NotFound},
+		{codes.AlreadyExists, "mapping already exists", AlreadyExists},
+		{codes.Unauthenticated, "invalid token", Unauthenticated},
+		{codes.Unknown, "something unexpected", Internal},
+	}
+	for _, tt := range tests {
+		if code := Classify(tt.grpcCode, tt.message); code != tt.expected {
+			t.Errorf("%q: expected %s, got %s", tt.message, tt.expected, code)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package errorcatalog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codePattern matches codes in error messages
+var codePattern = regexp.MustCompile(`\bREDB-[0-9]{4}\b`)
+
+// Error is an error with a catalog code. Its message is prefixed with the code.
+type Error struct {
+	Code    Code
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string { return string(e.Code) + ": " + e.Message }
+func (e *Error) Unwrap() error { return e.cause }
+
+// GRPCStatus returns the error as a gRPC status with the code's status code, so that the
+// error can be returned from gRPC handlers as is.
+func (e *Error) GRPCStatus() *status.Status {
+	grpcCode := codes.Unknown
+	if entry, ok := Lookup(e.Code); ok {
+		grpcCode = entry.GRPCCode
+	}
+	return status.New(grpcCode, e.Error())
+}
+
+// Errorf returns an error with a code. If an argument is an error, the returned error unwraps
+// to it.
+func Errorf(code Code, format string, args ...interface{}) error {
+	err := &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+	for _, arg := range args {
+		if cause, ok := arg.(error); ok {
+			err.cause = cause
+			break
+		}
+	}
+	return err
+}
+
+// Status returns a gRPC status error with a code, using the status code of the code's entry.
+func Status(code Code, format string, args ...interface{}) error {
+	return Errorf(code, format, args...).(*Error).GRPCStatus().Err()
+}
+
+// Extract returns the first code found in a message.
+func Extract(message string) (Code, bool) {
+	match := codePattern.FindString(message)
+	if match == "" {
+		return "", false
+	}
+	return Code(match), true
+}
+
+// Classify returns the code of an error message: the code it carries, else the code whose
+// patterns match the message, else the generic code of the gRPC status code. It returns
+// Internal for unknown errors.
+func Classify(grpcCode codes.Code, message string) Code {
+	if code, ok := Extract(message); ok {
+		return code
+	}
+	for _, entry := range catalog {
+		for _, pattern := range entry.patterns {
+			if pattern.MatchString(message) {
+				return entry.Code
+			}
+		}
+	}
+	if code, ok := byGRPCCode[grpcCode]; ok {
+		return code
+	}
+	return Internal
+}
+
+// FromError returns the code of an error, classifying errors raised without one.
+func FromError(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	if st, ok := status.FromError(err); ok {
+		return Classify(st.Code(), st.Message())
+	}
+	return Classify(codes.Unknown, err.Error())
+}
@@ -5,8 +5,9 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
+
+	"github.com/redbco/redb-open/pkg/spiffe"
 )
 
 // ClientOptions contains options for creating a gRPC client
@@ -31,10 +32,11 @@ func DefaultClientOptions() ClientOptions {
 	}
 }
 
-// NewClient creates a new gRPC client connection
+// NewClient creates a new gRPC client connection. The connection uses mTLS with the
+// workload identity of the process when SPIFFE is enabled.
 func NewClient(ctx context.Context, addr string, opts ClientOptions) (*grpc.ClientConn, error) {
 	dialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		spiffe.DialOption(),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                opts.KeepaliveTime,
 			Timeout:             opts.KeepaliveTimeout,
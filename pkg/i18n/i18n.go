@@ -0,0 +1,238 @@
+// Package i18n translates the user-facing messages of the CLI and the Client API.
+//
+// Messages are identified by their English text, so that English needs no bundle and a
+// message without a translation falls back to English. Translations are loaded from
+// bundles, JSON files holding the messages of a locale:
+//
+//	{
+//	  "locale": "de",
+//	  "messages": {
+//	    "failed to connect database": "Verbindung zur Datenbank fehlgeschlagen",
+//	    "Copied %d rows": "%d Zeilen kopiert"
+//	  }
+//	}
+//
+// Messages with format verbs are translated before formatting, so translations must keep
+// the verbs of the message in the same order.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of the messages themselves
+const DefaultLocale = "en"
+
+// Bundle holds the translated messages of a locale, keyed by the English message
+type Bundle struct {
+	Locale   string            `json:"locale"`
+	Messages map[string]string `json:"messages"`
+}
+
+// Catalog holds the bundles of the available locales. It is safe for concurrent use.
+type Catalog struct {
+	mu      sync.RWMutex
+	bundles map[string]map[string]string
+}
+
+// NewCatalog returns a catalog holding only the default locale
+func NewCatalog() *Catalog {
+	return &Catalog{bundles: make(map[string]map[string]string)}
+}
+
+// Add adds the messages of a bundle, replacing the messages already held for its locale
+func (c *Catalog) Add(bundle Bundle) error {
+	locale := NormalizeLocale(bundle.Locale)
+	if locale == "" {
+		return fmt.Errorf("bundle has no locale")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	messages, ok := c.bundles[locale]
+	if !ok {
+		messages = make(map[string]string, len(bundle.Messages))
+		c.bundles[locale] = messages
+	}
+	for message, translation := range bundle.Messages {
+		if translation != "" {
+			messages[message] = translation
+		}
+	}
+	return nil
+}
+
+// LoadFile adds the bundle of a JSON file
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle %s: %v", path, err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to parse bundle %s: %v", path, err)
+	}
+	if err := c.Add(bundle); err != nil {
+		return fmt.Errorf("invalid bundle %s: %v", path, err)
+	}
+	return nil
+}
+
+// LoadDir adds the bundles of all JSON files of a directory. A missing directory holds no
+// bundles.
+func (c *Catalog) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := c.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Locales returns the available locales, sorted, including the default locale
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := []string{DefaultLocale}
+	for locale := range c.bundles {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	return locales
+}
+
+// Translate returns the translation of a message in a locale. It falls back to the
+// language of a regional locale, then to the message itself.
+func (c *Catalog) Translate(locale, message string) string {
+	if c == nil || message == "" {
+		return message
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locale = NormalizeLocale(locale)
+	for _, candidate := range []string{locale, baseLanguage(locale)} {
+		if translation, ok := c.bundles[candidate][message]; ok {
+			return translation
+		}
+	}
+	return message
+}
+
+// Sprintf formats the translation of a format string in a locale
+func (c *Catalog) Sprintf(locale, format string, args ...interface{}) string {
+	return fmt.Sprintf(c.Translate(locale, format), args...)
+}
+
+// Negotiate returns the available locale best matching an Accept-Language header, or the
+// default locale if none matches.
+func (c *Catalog) Negotiate(acceptLanguage string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, requested := range parseAcceptLanguage(acceptLanguage) {
+		if requested == "*" || requested == DefaultLocale || baseLanguage(requested) == DefaultLocale {
+			return DefaultLocale
+		}
+		if _, ok := c.bundles[requested]; ok {
+			return requested
+		}
+		if _, ok := c.bundles[baseLanguage(requested)]; ok {
+			return baseLanguage(requested)
+		}
+	}
+	return DefaultLocale
+}
+
+// parseAcceptLanguage returns the locales of an Accept-Language header, by decreasing
+// quality. Locales with a quality of 0 are dropped.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := NormalizeLocale(fields[0])
+		if locale == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			entries = append(entries, weighted{locale: locale, quality: quality})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].quality > entries[j].quality
+	})
+
+	locales := make([]string, len(entries))
+	for i, entry := range entries {
+		locales[i] = entry.locale
+	}
+	return locales
+}
+
+// NormalizeLocale returns a locale as a lower-case tag, e.g. "de-ch" for "de_CH.UTF-8"
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+
+	// The C and POSIX locales of the environment mean no preference
+	if locale == "c" || locale == "posix" {
+		return ""
+	}
+	return locale
+}
+
+// LocaleFromEnv returns the locale of the environment from the first of variables set,
+// e.g. LC_ALL, LC_MESSAGES and LANG. It returns the default locale if none is set.
+func LocaleFromEnv(variables ...string) string {
+	for _, variable := range variables {
+		if locale := NormalizeLocale(os.Getenv(variable)); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+func baseLanguage(locale string) string {
+	if i := strings.Index(locale, "-"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	catalog := NewCatalog()
+	if err := catalog.Add(Bundle{Locale: "de", Messages: map[string]string{
+		"failed to connect database": "Verbindung zur Datenbank fehlgeschlagen",
+		"Copied %d rows":             "%d Zeilen kopiert",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := catalog.Add(Bundle{Locale: "fr_CA", Messages: map[string]string{
+		"failed to connect database": "échec de la connexion à la base de données",
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return catalog
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		locale   string
+		message  string
+		expected string
+	}{
+		{"de", "failed to connect database", "Verbindung zur Datenbank fehlgeschlagen"},
+		{"de-CH", "failed to connect database", "Verbindung zur Datenbank fehlgeschlagen"},
+		{"fr-ca", "failed to connect database", "échec de la connexion à la base de données"},
+		{"fr", "failed to connect database", "failed to connect database"},
+		{"de", "workspace not found", "workspace not found"},
+		{"en", "failed to connect database", "failed to connect database"},
+	}
+	for _, tt := range tests {
+		if got := catalog.Translate(tt.locale, tt.message); got != tt.expected {
+			t.Errorf("Translate(%q, %q) = %q, expected %q", tt.locale, tt.message, got, tt.expected)
+		}
+	}
+
+	if got := catalog.Sprintf("de", "Copied %d rows", 42); got != "42 Zeilen kopiert" {
+		t.Errorf("unexpected Sprintf result %q", got)
+	}
+}
+
+func TestCatalog_Negotiate(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT,de;q=0.9,en;q=0.8", "de"},
+		{"fr-CA", "fr-ca"},
+		{"es, de;q=0.5", "de"},
+		{"en-US, de;q=0.9", "en"},
+		{"de;q=0, ja", "en"},
+		{"*", "en"},
+	}
+	for _, tt := range tests {
+		if got := catalog.Negotiate(tt.header); got != tt.expected {
+			t.Errorf("Negotiate(%q) = %q, expected %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestCatalog_LoadDir(t *testing.T) {
+	dir := t.TempDir()
+	bundle := `{"locale": "nl", "messages": {"Access denied": "Toegang geweigerd"}}`
+	if err := os.WriteFile(filepath.Join(dir, "nl.json"), []byte(bundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := NewCatalog()
+	if err := catalog.LoadDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := catalog.LoadDir(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("expected a missing directory to hold no bundles, got %v", err)
+	}
+
+	if got := catalog.Translate("nl", "Access denied"); got != "Toegang geweigerd" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := catalog.Locales(); !reflect.DeepEqual(got, []string{"en", "nl"}) {
+		t.Errorf("unexpected locales %v", got)
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"de_CH.UTF-8":      "de-ch",
+		"en_US":            "en-us",
+		"sr_RS@latin":      "sr-rs",
+		"C":                "",
+		"POSIX":            "",
+		" pt-BR ":          "pt-br",
+		"de-DE.ISO-8859-1": "de-de",
+	}
+	for input, expected := range tests {
+		if got := NormalizeLocale(input); got != expected {
+			t.Errorf("NormalizeLocale(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
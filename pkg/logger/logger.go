@@ -7,6 +7,7 @@ import (
 	"time"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/redact"
 )
 
 // ANSI color codes for console output
@@ -155,6 +156,10 @@ func (l *Logger) EnableConsoleOutput() {
 }
 
 func (l *Logger) log(level, message string, fields map[string]string) {
+	// Scrub credentials and registered secrets before the entry leaves the service
+	message = redact.String(message)
+	fields = redact.Fields(fields)
+
 	now := time.Now()
 	entry := LogEntry{
 		Time:    now,
@@ -0,0 +1,42 @@
+package redact
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor scrubs the messages of errors returned by unary gRPC handlers.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, scrubStatus(err)
+	}
+}
+
+// StreamServerInterceptor scrubs the messages of errors returned by streaming gRPC handlers.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return scrubStatus(handler(srv, ss))
+	}
+}
+
+// scrubStatus scrubs the message of an error, keeping its gRPC status code
+func scrubStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return Error(err)
+	}
+	message := String(st.Message())
+	if message == st.Message() {
+		return err
+	}
+	scrubbed := st.Proto()
+	scrubbed.Message = message
+	return status.ErrorProto(scrubbed)
+}
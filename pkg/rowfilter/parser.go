@@ -0,0 +1,322 @@
+package rowfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a token of an expression
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+// keywords of the expression language, matched case-insensitively
+var keywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IS": true, "NULL": true,
+	"IN": true, "LIKE": true, "TRUE": true, "FALSE": true,
+}
+
+type token struct {
+	kind tokenKind
+	text string // Keywords are upper case, quoted identifiers and strings unquoted
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// tokenize splits an expression into tokens
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenOperator, text: "=", pos: i})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			operator, width := string(r), 1
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				operator, width = operator+string(runes[i+1]), 2
+			}
+			if operator == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d of the row filter", i+1)
+			}
+			if operator == "<>" {
+				operator = "!="
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: i})
+			i += width
+		case r == '\'' || r == '"' || r == '`':
+			text, end, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenIdentifier
+			if r == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: i})
+			i = end
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			word := string(runes[start:i])
+			if keywords[strings.ToUpper(word)] {
+				tokens = append(tokens, token{kind: tokenKeyword, text: strings.ToUpper(word), pos: start})
+			} else {
+				tokens = append(tokens, token{kind: tokenIdentifier, text: word, pos: start})
+			}
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at position %d of the row filter", r, i+1)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// readQuoted reads a quoted string or identifier starting at a quote, where a doubled quote
+// stands for the quote itself. It returns the unquoted text and the position after it.
+func readQuoted(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var b strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			b.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			b.WriteRune(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated %c at position %d of the row filter", quote, start+1)
+}
+
+// parser is a recursive descent parser of expressions:
+//
+//	or         = and { OR and }
+//	and        = not { AND not }
+//	not        = NOT not | primary
+//	primary    = "(" or ")" | column predicate
+//	predicate  = operator literal | IS [NOT] NULL | [NOT] IN "(" literal { "," literal } ")" | [NOT] LIKE string
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// acceptKeyword consumes the next token if it is the keyword
+func (p *parser) acceptKeyword(keyword string) bool {
+	if t := p.peek(); t.kind == tokenKeyword && t.text == keyword {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected(keyword)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	return fmt.Errorf("expected %s but found %s at position %d of the row filter", expected, t, t.pos+1)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.acceptKeyword("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	if p.peek().kind == tokenLeftParen {
+		p.next()
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRightParen {
+			return nil, p.unexpected("')'")
+		}
+		p.next()
+		return expression, nil
+	}
+
+	if p.peek().kind != tokenIdentifier {
+		return nil, p.unexpected("a column")
+	}
+	column := p.next().text
+	return p.parsePredicate(column)
+}
+
+func (p *parser) parsePredicate(column string) (node, error) {
+	if t := p.peek(); t.kind == tokenOperator {
+		p.next()
+		literal, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{column: column, operator: t.text, literal: literal}, nil
+	}
+
+	if p.acceptKeyword("IS") {
+		negated := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullNode{column: column, negated: negated}, nil
+	}
+
+	negated := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("IN"):
+		if p.peek().kind != tokenLeftParen {
+			return nil, p.unexpected("'('")
+		}
+		p.next()
+		var literals []interface{}
+		for {
+			literal, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			literals = append(literals, literal)
+			if p.peek().kind == tokenComma {
+				p.next()
+				continue
+			}
+			if p.peek().kind != tokenRightParen {
+				return nil, p.unexpected("',' or ')'")
+			}
+			p.next()
+			return &inNode{column: column, literals: literals, negated: negated}, nil
+		}
+	case p.acceptKeyword("LIKE"):
+		if p.peek().kind != tokenString {
+			return nil, p.unexpected("a quoted pattern")
+		}
+		return &likeNode{column: column, pattern: likePattern(p.next().text), negated: negated}, nil
+	}
+
+	if negated {
+		return nil, p.unexpected("IN or LIKE")
+	}
+	return nil, p.unexpected("a comparison, IS, IN or LIKE")
+}
+
+// parseLiteral parses a string, number, boolean or NULL. Numbers are float64, NULL is nil.
+func (p *parser) parseLiteral() (interface{}, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		return t.text, nil
+	case t.kind == tokenNumber:
+		p.next()
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d of the row filter", t.text, t.pos+1)
+		}
+		return number, nil
+	case t.kind == tokenKeyword && (t.text == "TRUE" || t.text == "FALSE"):
+		p.next()
+		return t.text == "TRUE", nil
+	case t.kind == tokenKeyword && t.text == "NULL":
+		p.next()
+		return nil, nil
+	}
+	return nil, p.unexpected("a value")
+}
@@ -0,0 +1,395 @@
+// Package rowfilter evaluates SQL-like row filter expressions, the conditions of mappings and
+// mapping rules that decide which source rows are written to a target during replication:
+//
+//	filter, err := rowfilter.Parse("region = 'EU' AND (amount >= 100 OR vip IS NOT NULL)")
+//	if filter.Match(row) { ... }
+//
+// An expression compares columns with literals using = != <> < <= > >=, IS [NOT] NULL,
+// [NOT] IN (...) and [NOT] LIKE (with % and _ wildcards), combined with AND, OR, NOT and
+// parentheses. Keywords are case-insensitive; column names can be quoted with double quotes or
+// backticks. As in SQL, a comparison with a NULL or missing column is never true.
+package rowfilter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a parsed row filter expression
+type Filter struct {
+	expression string
+	root       node
+}
+
+// Parse parses a row filter expression
+func Parse(expression string) (*Filter, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("the row filter is empty")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d of the row filter", t, t.pos+1)
+	}
+	return &Filter{expression: strings.TrimSpace(expression), root: root}, nil
+}
+
+// Match reports whether a row, keyed by column name, satisfies the filter
+func (f *Filter) Match(row map[string]interface{}) bool {
+	return f.root.eval(row) == truthTrue
+}
+
+// Columns returns the columns the filter reads, sorted
+func (f *Filter) Columns() []string {
+	seen := make(map[string]bool)
+	f.root.columns(seen)
+	columns := make([]string, 0, len(seen))
+	for column := range seen {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// String returns the expression the filter was parsed from
+func (f *Filter) String() string {
+	return f.expression
+}
+
+// truth is a three-valued SQL truth value
+type truth int
+
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+func (t truth) not() truth {
+	switch t {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	}
+	return truthUnknown
+}
+
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// node is a node of a parsed expression
+type node interface {
+	eval(row map[string]interface{}) truth
+	columns(seen map[string]bool)
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) eval(row map[string]interface{}) truth {
+	left := n.left.eval(row)
+	if left == truthFalse {
+		return truthFalse
+	}
+	right := n.right.eval(row)
+	if right == truthFalse {
+		return truthFalse
+	}
+	if left == truthUnknown || right == truthUnknown {
+		return truthUnknown
+	}
+	return truthTrue
+}
+
+func (n *andNode) columns(seen map[string]bool) {
+	n.left.columns(seen)
+	n.right.columns(seen)
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) eval(row map[string]interface{}) truth {
+	left := n.left.eval(row)
+	if left == truthTrue {
+		return truthTrue
+	}
+	right := n.right.eval(row)
+	if right == truthTrue {
+		return truthTrue
+	}
+	if left == truthUnknown || right == truthUnknown {
+		return truthUnknown
+	}
+	return truthFalse
+}
+
+func (n *orNode) columns(seen map[string]bool) {
+	n.left.columns(seen)
+	n.right.columns(seen)
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(row map[string]interface{}) truth {
+	return n.operand.eval(row).not()
+}
+
+func (n *notNode) columns(seen map[string]bool) {
+	n.operand.columns(seen)
+}
+
+// compareNode compares a column with a literal
+type compareNode struct {
+	column   string
+	operator string
+	literal  interface{}
+}
+
+func (n *compareNode) eval(row map[string]interface{}) truth {
+	value := row[n.column]
+	if value == nil || n.literal == nil {
+		return truthUnknown
+	}
+	cmp, ok := compare(value, n.literal)
+	if !ok {
+		return truthUnknown
+	}
+	switch n.operator {
+	case "=":
+		return truthOf(cmp == 0)
+	case "!=":
+		return truthOf(cmp != 0)
+	case "<":
+		return truthOf(cmp < 0)
+	case "<=":
+		return truthOf(cmp <= 0)
+	case ">":
+		return truthOf(cmp > 0)
+	case ">=":
+		return truthOf(cmp >= 0)
+	}
+	return truthUnknown
+}
+
+func (n *compareNode) columns(seen map[string]bool) {
+	seen[n.column] = true
+}
+
+type isNullNode struct {
+	column  string
+	negated bool
+}
+
+func (n *isNullNode) eval(row map[string]interface{}) truth {
+	return truthOf((row[n.column] == nil) != n.negated)
+}
+
+func (n *isNullNode) columns(seen map[string]bool) {
+	seen[n.column] = true
+}
+
+type inNode struct {
+	column   string
+	literals []interface{}
+	negated  bool
+}
+
+func (n *inNode) eval(row map[string]interface{}) truth {
+	value := row[n.column]
+	if value == nil {
+		return truthUnknown
+	}
+	result := truthFalse
+	for _, literal := range n.literals {
+		if literal == nil {
+			result = truthUnknown
+			continue
+		}
+		if cmp, ok := compare(value, literal); ok && cmp == 0 {
+			result = truthTrue
+			break
+		}
+	}
+	if n.negated {
+		return result.not()
+	}
+	return result
+}
+
+func (n *inNode) columns(seen map[string]bool) {
+	seen[n.column] = true
+}
+
+type likeNode struct {
+	column  string
+	pattern *regexp.Regexp
+	negated bool
+}
+
+func (n *likeNode) eval(row map[string]interface{}) truth {
+	value := row[n.column]
+	if value == nil {
+		return truthUnknown
+	}
+	return truthOf(n.pattern.MatchString(stringValue(value)) != n.negated)
+}
+
+func (n *likeNode) columns(seen map[string]bool) {
+	seen[n.column] = true
+}
+
+// likePattern compiles a LIKE pattern, where % matches any sequence of characters and _ any
+// single character
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// compare compares a row value with a literal, returning false when they cannot be compared.
+// Numbers are compared numerically (also with quoted literals), booleans with false before true,
+// and anything else as text, which also orders RFC 3339 timestamps.
+func compare(value, literal interface{}) (int, bool) {
+	switch lit := literal.(type) {
+	case float64:
+		number, ok := numericValue(value)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case number < lit:
+			return -1, true
+		case number > lit:
+			return 1, true
+		}
+		return 0, true
+	case bool:
+		b, ok := boolValue(value)
+		if !ok {
+			return 0, false
+		}
+		if b == lit {
+			return 0, true
+		}
+		if !b {
+			return -1, true
+		}
+		return 1, true
+	case string:
+		if number, ok := numericValue(value); ok && !isText(value) {
+			if parsed, err := strconv.ParseFloat(lit, 64); err == nil {
+				return compare(number, parsed)
+			}
+		}
+		return strings.Compare(stringValue(value), lit), true
+	}
+	return 0, false
+}
+
+// numericValue converts a row value to a number
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, !math.IsNaN(v)
+	case fmt.Stringer:
+		// json.Number and decimal types
+		if _, isTime := value.(time.Time); isTime {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	case []byte:
+		f, err := strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// boolValue converts a row value to a boolean
+func boolValue(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	if number, ok := numericValue(value); ok {
+		return number != 0, true
+	}
+	return false, false
+}
+
+// isText reports whether a row value is text, which is compared as text even when it looks numeric
+func isText(value interface{}) bool {
+	switch value.(type) {
+	case string, []byte:
+		return true
+	}
+	return false
+}
+
+// stringValue converts a row value to text
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,113 @@
+package rowfilter
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilter_Match(t *testing.T) {
+	row := map[string]interface{}{
+		"region":     "EU",
+		"country":    "de",
+		"amount":     int64(250),
+		"score":      json.Number("4.5"),
+		"active":     true,
+		"zip":        "01234",
+		"email":      "alice@example.com",
+		"created_at": time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		"deleted_at": nil,
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"region = 'EU'", true},
+		{"region = 'US'", false},
+		{"region <> 'US'", true},
+		{"region != 'EU'", false},
+		{"amount > 100", true},
+		{"amount >= 250 AND amount <= 250", true},
+		{"amount < -1", false},
+		{"amount = '250'", true},
+		{"score > 4", true},
+		{"active = TRUE", true},
+		{"active = false", false},
+		{"zip = '01234'", true},
+		{"zip = '1234'", false},
+		{"created_at >= '2024-01-01'", true},
+		{"created_at < '2024-01-01'", false},
+		{"deleted_at IS NULL", true},
+		{"deleted_at IS NOT NULL", false},
+		{"missing IS NULL", true},
+		{"deleted_at = 'x'", false},
+		{"NOT deleted_at = 'x'", false},
+		{"missing != 'x'", false},
+		{"region IN ('EU', 'UK')", true},
+		{"region NOT IN ('EU', 'UK')", false},
+		{"region NOT IN ('US', NULL)", false},
+		{"country in ('de')", true},
+		{"email LIKE '%@example.com'", true},
+		{"email NOT LIKE '%@example.com'", false},
+		{"email LIKE 'a_ice@%'", true},
+		{"email LIKE 'alice'", false},
+		{"region = 'US' OR (amount > 100 AND active = true)", true},
+		{"region = 'US' OR amount > 100 AND active = false", false},
+		{"NOT (region = 'US')", true},
+		{`"region" = 'EU' and ` + "`amount`" + ` > 1`, true},
+		{"deleted_at = 'x' OR region = 'EU'", true},
+	}
+	for _, tt := range tests {
+		filter, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expression, err)
+			continue
+		}
+		if got := filter.Match(row); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"", "empty"},
+		{"region", "expected a comparison"},
+		{"region = ", "expected a value"},
+		{"region = 'EU", "unterminated"},
+		{"(region = 'EU'", "expected ')'"},
+		{"region = 'EU' region", "unexpected"},
+		{"region IN 'EU'", "expected '('"},
+		{"region IN ('EU' 'UK')", "expected ',' or ')'"},
+		{"region LIKE 5", "expected a quoted pattern"},
+		{"region NOT = 'EU'", "expected IN or LIKE"},
+		{"region ! 'EU'", "unexpected '!'"},
+		{"region = 'EU'; DROP TABLE x", "unexpected ';'"},
+		{"'EU' = region", "expected a column"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expression)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFilter_Columns(t *testing.T) {
+	filter, err := Parse("region = 'EU' AND (amount > 1 OR \"Customer Id\" IS NOT NULL) AND region != 'x'")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, want := filter.Columns(), []string{"Customer Id", "amount", "region"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+	if got := filter.String(); got != "region = 'EU' AND (amount > 1 OR \"Customer Id\" IS NOT NULL) AND region != 'x'" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -13,15 +13,17 @@ import (
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	supervisorv1 "github.com/redbco/redb-open/api/proto/supervisor/v1"
 	"github.com/redbco/redb-open/pkg/config"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"github.com/redbco/redb-open/pkg/health"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/redact"
+	"github.com/redbco/redb-open/pkg/spiffe"
 )
 
 // Service interface that all microservices must implement
@@ -129,6 +131,25 @@ func (s *BaseService) Run(ctx context.Context) error {
 	// Set initial state
 	s.setState(commonv1.ServiceState_SERVICE_STATE_STARTING)
 
+	// Apply the database capabilities the deployment registers or overrides
+	if err := dbcapabilities.ApplyFromEnv(); err != nil {
+		return fmt.Errorf("failed to apply database capabilities: %w", err)
+	}
+
+	// Obtain the workload identity used to authenticate inter-service connections
+	spiffeCtx, spiffeCancel := context.WithTimeout(ctx, 30*time.Second)
+	err := spiffe.Init(spiffeCtx, spiffe.ConfigFromEnv())
+	spiffeCancel()
+	if err != nil {
+		return fmt.Errorf("failed to obtain SPIFFE identity: %w", err)
+	}
+	defer spiffe.Shutdown()
+	if identity := spiffe.Current(); identity != nil {
+		if id, err := identity.ID(); err == nil {
+			s.Logger.Infof("Using SPIFFE identity %s for inter-service connections", id)
+		}
+	}
+
 	// Connect to supervisor (unless in standalone mode)
 	if !s.standalone {
 		if err := s.connectToSupervisor(ctx); err != nil {
@@ -213,7 +234,7 @@ func (s *BaseService) Run(ctx context.Context) error {
 
 func (s *BaseService) connectToSupervisor(ctx context.Context) error {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, spiffe.DialOption())
 	opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
 		Time:                10 * time.Second,
 		Timeout:             3 * time.Second,
@@ -265,6 +286,12 @@ func (s *BaseService) startGRPCServer() error {
 			Time:              5 * time.Second,
 			Timeout:           1 * time.Second,
 		}))
+		opts = append(opts, spiffe.ServerOptions()...)
+		// Scrub credentials from the messages of errors returned to clients
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(redact.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(redact.StreamServerInterceptor()),
+		)
 
 		s.grpcServer = grpc.NewServer(opts...)
 
@@ -2,22 +2,55 @@ package service
 
 import (
 	"runtime"
+	"sync"
 	"syscall"
+	"time"
 )
 
+// Custom metrics the supervisor reads to recommend the number of replicas of a service to
+// external orchestrators. Services report those that apply to them from CollectMetrics.
+const (
+	// MetricQueueDepth is the number of requests the service is working on
+	MetricQueueDepth = "queue_depth"
+	// MetricPendingRuns is the number of jobs the service accepted and has not started yet
+	MetricPendingRuns = "pending_runs"
+)
+
+// cpuSample is the CPU time used by the process when the CPU usage was last measured
+var cpuSample struct {
+	sync.Mutex
+	at   time.Time
+	used time.Duration
+}
+
 func getMemoryUsage() int64 {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 	return int64(m.Alloc)
 }
 
+// getCPUUsage returns the CPU time used by the process since the previous call, as a
+// percentage of the CPUs it may run on (GOMAXPROCS). The first call returns 0.
 func getCPUUsage() float64 {
-	// TODO: Implement CPU usage tracking
 	var rusage syscall.Rusage
 	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
 		return 0
 	}
+	used := time.Duration(rusage.Utime.Nano() + rusage.Stime.Nano())
+	now := time.Now()
+
+	cpuSample.Lock()
+	defer cpuSample.Unlock()
+
+	previousAt, previousUsed := cpuSample.at, cpuSample.used
+	cpuSample.at, cpuSample.used = now, used
+	if previousAt.IsZero() {
+		return 0
+	}
 
-	// Convert to percentage
-	return float64(rusage.Utime.Sec+rusage.Stime.Sec) / 100.0
+	elapsed := now.Sub(previousAt)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(used-previousUsed) / float64(elapsed) / float64(runtime.GOMAXPROCS(0)) * 100
 }
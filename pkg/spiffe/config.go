@@ -0,0 +1,92 @@
+// Package spiffe provides SPIFFE workload identities for the gRPC connections
+// between reDB services. When enabled, X.509 SVIDs are obtained from the SPIRE
+// agent through the Workload API and every inter-service connection is mutually
+// authenticated against the configured trust domain or SPIFFE IDs.
+package spiffe
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+)
+
+// Environment variables read by ConfigFromEnv. The supervisor exports its
+// security.spiffe settings through them so the services it starts inherit them.
+const (
+	EnvEnabled     = "REDB_SPIFFE_ENABLED"
+	EnvSocketPath  = "REDB_SPIFFE_SOCKET_PATH"
+	EnvTrustDomain = "REDB_SPIFFE_TRUST_DOMAIN"
+	EnvAllowedIDs  = "REDB_SPIFFE_ALLOWED_IDS"
+)
+
+// Config describes how a process obtains and verifies workload identities
+type Config struct {
+	Enabled bool
+	// SocketPath is the Workload API endpoint, either a path or a unix:// URL.
+	// When empty the SPIFFE_ENDPOINT_SOCKET environment variable is used.
+	SocketPath string
+	// TrustDomain accepts every peer of the trust domain, e.g. "redb.example.org"
+	TrustDomain string
+	// AllowedIDs restricts peers to these SPIFFE IDs; it takes precedence over TrustDomain
+	AllowedIDs []string
+}
+
+// ConfigFromEnv reads the configuration from the REDB_SPIFFE_* environment variables
+func ConfigFromEnv() Config {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvEnabled))
+	cfg := Config{
+		Enabled:     enabled,
+		SocketPath:  strings.TrimSpace(os.Getenv(EnvSocketPath)),
+		TrustDomain: strings.TrimSpace(os.Getenv(EnvTrustDomain)),
+	}
+	for _, id := range strings.Split(os.Getenv(EnvAllowedIDs), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			cfg.AllowedIDs = append(cfg.AllowedIDs, id)
+		}
+	}
+	return cfg
+}
+
+// Validate checks that peers can be authorized
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	_, err := c.authorizer()
+	return err
+}
+
+// authorizer returns the check applied to the SPIFFE ID of every peer
+func (c Config) authorizer() (tlsconfig.Authorizer, error) {
+	if len(c.AllowedIDs) > 0 {
+		ids := make([]spiffeid.ID, 0, len(c.AllowedIDs))
+		for _, s := range c.AllowedIDs {
+			id, err := spiffeid.FromString(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed SPIFFE ID %q: %w", s, err)
+			}
+			ids = append(ids, id)
+		}
+		return tlsconfig.AuthorizeOneOf(ids...), nil
+	}
+	if c.TrustDomain == "" {
+		return nil, fmt.Errorf("a trust domain or allowed SPIFFE IDs are required")
+	}
+	td, err := spiffeid.TrustDomainFromString(c.TrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trust domain %q: %w", c.TrustDomain, err)
+	}
+	return tlsconfig.AuthorizeMemberOf(td), nil
+}
+
+// socketAddr returns the Workload API address in the unix:// form expected by the client
+func (c Config) socketAddr() string {
+	if c.SocketPath == "" || strings.Contains(c.SocketPath, "://") {
+		return c.SocketPath
+	}
+	return "unix://" + c.SocketPath
+}
@@ -0,0 +1,133 @@
+package spiffe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/spiffegrpc/grpccredentials"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Identity holds the X.509 SVID of the process. The SVID and trust bundle are
+// rotated by the Workload API client in the background.
+type Identity struct {
+	source     *workloadapi.X509Source
+	authorizer tlsconfig.Authorizer
+}
+
+// New connects to the Workload API and waits for the first SVID
+func New(ctx context.Context, cfg Config) (*Identity, error) {
+	authorizer, err := cfg.authorizer()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []workloadapi.X509SourceOption
+	if addr := cfg.socketAddr(); addr != "" {
+		opts = append(opts, workloadapi.WithClientOptions(workloadapi.WithAddr(addr)))
+	}
+	source, err := workloadapi.NewX509Source(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain X.509 SVID from the workload API: %w", err)
+	}
+	return &Identity{source: source, authorizer: authorizer}, nil
+}
+
+// ID returns the SPIFFE ID of the process
+func (i *Identity) ID() (spiffeid.ID, error) {
+	svid, err := i.source.GetX509SVID()
+	if err != nil {
+		return spiffeid.ID{}, err
+	}
+	return svid.ID, nil
+}
+
+// ServerCredentials returns mTLS credentials for a gRPC server
+func (i *Identity) ServerCredentials() credentials.TransportCredentials {
+	return grpccredentials.MTLSServerCredentials(i.source, i.source, i.authorizer)
+}
+
+// ClientCredentials returns mTLS credentials for a gRPC client
+func (i *Identity) ClientCredentials() credentials.TransportCredentials {
+	return grpccredentials.MTLSClientCredentials(i.source, i.source, i.authorizer)
+}
+
+// Close stops watching the Workload API
+func (i *Identity) Close() error {
+	return i.source.Close()
+}
+
+var (
+	mu      sync.RWMutex
+	current *Identity
+)
+
+// Init obtains the identity of the process when cfg is enabled. The identity is
+// then used by DialOption and ServerOptions until Shutdown is called.
+func Init(ctx context.Context, cfg Config) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	identity, err := New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	previous := current
+	current = identity
+	mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// Current returns the identity of the process, or nil when SPIFFE is disabled
+func Current() *Identity {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Shutdown closes the identity of the process
+func Shutdown() {
+	mu.Lock()
+	identity := current
+	current = nil
+	mu.Unlock()
+
+	if identity != nil {
+		identity.Close()
+	}
+}
+
+// DialOption returns the transport credentials for connecting to another service:
+// mTLS with the process identity, or plaintext when SPIFFE is disabled
+func DialOption() grpc.DialOption {
+	if identity := Current(); identity != nil {
+		return grpc.WithTransportCredentials(identity.ClientCredentials())
+	}
+	return grpc.WithTransportCredentials(insecure.NewCredentials())
+}
+
+// ServerOptions returns the options that make a gRPC server require mTLS with
+// the process identity; there are none when SPIFFE is disabled
+func ServerOptions() []grpc.ServerOption {
+	if identity := Current(); identity != nil {
+		return []grpc.ServerOption{grpc.Creds(identity.ServerCredentials())}
+	}
+	return nil
+}
+
+// PeerID returns the SPIFFE ID of the caller of a gRPC request served over mTLS
+func PeerID(ctx context.Context) (spiffeid.ID, bool) {
+	return grpccredentials.PeerIDFromContext(ctx)
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+)
+
+// ShardEnd is the checkpoint of a shard that was closed and fully consumed, after which
+// consumers move on to its child shards.
+const ShardEnd = "SHARD_END"
+
+// CheckpointStore persists the positions of consumers on platforms that leave checkpointing
+// to the application, such as the sequence numbers of Kinesis shards. Positions are opaque
+// to the store.
+type CheckpointStore interface {
+	// LoadCheckpoints returns the last checkpointed position per shard of a topic for a consumer
+	LoadCheckpoints(ctx context.Context, consumerID, topic string) (map[string]string, error)
+
+	// SaveCheckpoints records positions per shard of a topic for a consumer. Shards missing
+	// from positions keep their checkpoint.
+	SaveCheckpoints(ctx context.Context, consumerID, topic string, positions map[string]string) error
+}
+
+// CheckpointedConsumer is implemented by consumers that checkpoint through a CheckpointStore.
+// Without a store, checkpoints only last as long as the consumer.
+type CheckpointedConsumer interface {
+	ConsumerOperator
+
+	// SetCheckpointStore sets the store that Subscribe resumes from and Commit saves to
+	SetCheckpointStore(store CheckpointStore)
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]map[string]string // consumer/topic -> shard -> position
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]map[string]string)}
+}
+
+// LoadCheckpoints returns a copy of the checkpoints of a consumer on a topic.
+func (s *MemoryCheckpointStore) LoadCheckpoints(ctx context.Context, consumerID, topic string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	positions := make(map[string]string, len(s.checkpoints[consumerID+"/"+topic]))
+	for shard, position := range s.checkpoints[consumerID+"/"+topic] {
+		positions[shard] = position
+	}
+	return positions, nil
+}
+
+// SaveCheckpoints records the positions of a consumer on a topic.
+func (s *MemoryCheckpointStore) SaveCheckpoints(ctx context.Context, consumerID, topic string, positions map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := consumerID + "/" + topic
+	if s.checkpoints[key] == nil {
+		s.checkpoints[key] = make(map[string]string, len(positions))
+	}
+	for shard, position := range positions {
+		s.checkpoints[key][shard] = position
+	}
+	return nil
+}
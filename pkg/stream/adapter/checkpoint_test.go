@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCheckpointStore()
+
+	if err := store.SaveCheckpoints(ctx, "cdc", "orders", map[string]string{"shard-0": "100", "shard-1": "200"}); err != nil {
+		t.Fatal(err)
+	}
+	// Shards missing from a save keep their checkpoint
+	if err := store.SaveCheckpoints(ctx, "cdc", "orders", map[string]string{"shard-0": ShardEnd}); err != nil {
+		t.Fatal(err)
+	}
+
+	positions, err := store.LoadCheckpoints(ctx, "cdc", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if positions["shard-0"] != ShardEnd || positions["shard-1"] != "200" {
+		t.Errorf("positions = %v", positions)
+	}
+
+	// Loaded checkpoints are copies
+	positions["shard-1"] = "300"
+	if again, _ := store.LoadCheckpoints(ctx, "cdc", "orders"); again["shard-1"] != "200" {
+		t.Errorf("shard-1 = %q after modifying a loaded copy", again["shard-1"])
+	}
+
+	if other, _ := store.LoadCheckpoints(ctx, "audit", "orders"); len(other) != 0 {
+		t.Errorf("positions of another consumer = %v, want none", other)
+	}
+}
@@ -3,6 +3,7 @@ package adapter
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redbco/redb-open/pkg/streamcapabilities"
@@ -28,6 +29,73 @@ func NewConnectionConfig(id string, platform streamcapabilities.StreamPlatform)
 	}
 }
 
+// ConnectionConfigFromMap creates the configuration of a connection from the connection_config
+// of a stream. brokers is a list or a comma-separated string. String values are also copied to
+// Configuration, so that adapters can read platform-specific settings.
+func ConnectionConfigFromMap(id string, platform streamcapabilities.StreamPlatform, values map[string]interface{}) *ConnectionConfig {
+	config := NewConnectionConfig(id, platform)
+
+	switch brokers := values["brokers"].(type) {
+	case []interface{}:
+		for _, b := range brokers {
+			if str, ok := b.(string); ok && str != "" {
+				config.Brokers = append(config.Brokers, str)
+			}
+		}
+	case []string:
+		config.Brokers = append(config.Brokers, brokers...)
+	case string:
+		for _, b := range strings.Split(brokers, ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				config.Brokers = append(config.Brokers, b)
+			}
+		}
+	}
+
+	fields := map[string]*string{
+		"region":            &config.Region,
+		"project":           &config.Project,
+		"namespace":         &config.Namespace,
+		"endpoint":          &config.Endpoint,
+		"username":          &config.Username,
+		"password":          &config.Password,
+		"sasl_mechanism":    &config.SASLMechanism,
+		"tls_verify_mode":   &config.TLSVerifyMode,
+		"tls_client_cert":   &config.CertFile,
+		"tls_client_key":    &config.KeyFile,
+		"tls_ca_bundle":     &config.CAFile,
+		"group_id":          &config.GroupID,
+		"auto_offset_reset": &config.AutoOffsetReset,
+		"acks":              &config.Acks,
+		"compression":       &config.Compression,
+	}
+	for key, field := range fields {
+		if str, ok := values[key].(string); ok && str != "" {
+			*field = str
+		}
+	}
+
+	if tlsEnabled, ok := values["tls_enabled"].(bool); ok {
+		config.TLSEnabled = tlsEnabled
+	}
+	if tlsSkipVerify, ok := values["tls_skip_verify"].(bool); ok {
+		config.TLSSkipVerify = tlsSkipVerify
+	}
+	// JSON numbers decode as float64
+	if maxMessageSize, ok := values["max_message_size"].(float64); ok && maxMessageSize > 0 {
+		config.MaxMessageSize = int(maxMessageSize)
+	}
+
+	// Copy additional configuration
+	for k, v := range values {
+		if str, ok := v.(string); ok {
+			config.Configuration[k] = str
+		}
+	}
+
+	return config
+}
+
 // Validate checks if the connection configuration is valid.
 func (c *ConnectionConfig) Validate() error {
 	if c.ID == "" {
@@ -56,7 +124,7 @@ func (c *ConnectionConfig) Validate() error {
 		if c.Namespace == "" {
 			return fmt.Errorf("namespace is required for Event Hubs")
 		}
-	case "mqtt", "nats", "rabbitmq":
+	case "mqtt", "nats", "nats_jetstream", "rabbitmq":
 		if c.Endpoint == "" && len(c.Brokers) == 0 {
 			return fmt.Errorf("endpoint or brokers are required for %s", c.Platform)
 		}
@@ -98,6 +166,7 @@ func (c *ConnectionConfig) Clone() *ConnectionConfig {
 		CAFile:           c.CAFile,
 		TLSEnabled:       c.TLSEnabled,
 		TLSSkipVerify:    c.TLSSkipVerify,
+		TLSVerifyMode:    c.TLSVerifyMode,
 		Authentication:   make(map[string]string, len(c.Authentication)),
 		GroupID:          c.GroupID,
 		AutoOffsetReset:  c.AutoOffsetReset,
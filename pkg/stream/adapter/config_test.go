@@ -0,0 +1,43 @@
+package adapter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+)
+
+func TestConnectionConfigFromMap(t *testing.T) {
+	config := ConnectionConfigFromMap("stream-1", streamcapabilities.Kafka, map[string]interface{}{
+		"brokers":          []interface{}{"kafka-1:9092", "kafka-2:9092"},
+		"sasl_mechanism":   "SCRAM-SHA-512",
+		"username":         "redb",
+		"tls_enabled":      true,
+		"acks":             "1",
+		"max_message_size": float64(4194304),
+		"client.rack":      "eu-west-1a",
+	})
+
+	if !reflect.DeepEqual(config.Brokers, []string{"kafka-1:9092", "kafka-2:9092"}) {
+		t.Errorf("Brokers = %v", config.Brokers)
+	}
+	if config.SASLMechanism != "SCRAM-SHA-512" || config.Username != "redb" || !config.TLSEnabled {
+		t.Errorf("authentication = %q, %q, tls %v", config.SASLMechanism, config.Username, config.TLSEnabled)
+	}
+	if config.Acks != "1" || config.Compression != "none" || config.MaxMessageSize != 4194304 {
+		t.Errorf("producer settings = acks %q, compression %q, max message size %d", config.Acks, config.Compression, config.MaxMessageSize)
+	}
+	if config.Configuration["client.rack"] != "eu-west-1a" {
+		t.Errorf("Configuration = %v", config.Configuration)
+	}
+
+	config = ConnectionConfigFromMap("stream-2", streamcapabilities.Redpanda, map[string]interface{}{
+		"brokers": "redpanda-1:9092, redpanda-2:9092,",
+	})
+	if !reflect.DeepEqual(config.Brokers, []string{"redpanda-1:9092", "redpanda-2:9092"}) {
+		t.Errorf("Brokers = %v", config.Brokers)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -192,11 +192,12 @@ type ConnectionConfig struct {
 	Username       string
 	Password       string
 	SASLMechanism  string // PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, etc.
-	CertFile       string // Path to TLS certificate
-	KeyFile        string // Path to TLS key
-	CAFile         string // Path to CA certificate
+	CertFile       string // TLS client certificate, PEM or path
+	KeyFile        string // TLS client key, PEM or path
+	CAFile         string // CA bundle verifying the brokers, PEM or path
 	TLSEnabled     bool
 	TLSSkipVerify  bool
+	TLSVerifyMode  string            // tlsconfig.VerifyFull, VerifyCA or VerifyNone; TLSSkipVerify applies if empty
 	Authentication map[string]string // Additional auth parameters
 
 	// Consumer configuration
@@ -0,0 +1,171 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DefaultLedgerTable is the default name of the target-side offset ledger table.
+const DefaultLedgerTable = "redb_stream_offset_ledger"
+
+// SQLOffsetLedger stores applied stream positions in a table on the target database.
+// Batch writers call RecordPositions inside the transaction that writes the batch data,
+// which makes the data and the positions commit atomically.
+type SQLOffsetLedger struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+// NewSQLOffsetLedger creates a ledger backed by a SQL table.
+// dialect selects the placeholder style: "postgres" uses $n, anything else uses ?.
+func NewSQLOffsetLedger(db *sql.DB, table, dialect string) *SQLOffsetLedger {
+	if table == "" {
+		table = DefaultLedgerTable
+	}
+
+	placeholder := func(int) string { return "?" }
+	if dialect == "postgres" || dialect == "postgresql" {
+		placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+
+	return &SQLOffsetLedger{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+	}
+}
+
+// EnsureTable creates the ledger table if it does not exist.
+func (l *SQLOffsetLedger) EnsureTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	consumer_id VARCHAR(255) NOT NULL,
+	topic VARCHAR(255) NOT NULL,
+	partition_id INTEGER NOT NULL,
+	last_offset BIGINT NOT NULL,
+	batch_sequence BIGINT NOT NULL,
+	PRIMARY KEY (consumer_id, topic, partition_id)
+)`, l.table)
+
+	if _, err := l.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create ledger table %s: %w", l.table, err)
+	}
+	return nil
+}
+
+// LoadPositions returns the last applied position per topic partition for a consumer.
+func (l *SQLOffsetLedger) LoadPositions(ctx context.Context, consumerID string) ([]PartitionPosition, error) {
+	query := fmt.Sprintf("SELECT topic, partition_id, last_offset FROM %s WHERE consumer_id = %s",
+		l.table, l.placeholder(1))
+
+	rows, err := l.db.QueryContext(ctx, query, consumerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []PartitionPosition
+	for rows.Next() {
+		var pos PartitionPosition
+		if err := rows.Scan(&pos.Topic, &pos.Partition, &pos.Offset); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger row: %w", err)
+		}
+		positions = append(positions, pos)
+	}
+
+	return positions, rows.Err()
+}
+
+// RecordPositions records the batch positions within the caller's transaction.
+// Offsets only move forward, so replaying an older batch never rewinds the ledger.
+func (l *SQLOffsetLedger) RecordPositions(ctx context.Context, tx *sql.Tx, batch *Batch) error {
+	for _, pos := range batch.Positions {
+		update := fmt.Sprintf(
+			"UPDATE %s SET last_offset = %s, batch_sequence = %s WHERE consumer_id = %s AND topic = %s AND partition_id = %s AND last_offset < %s",
+			l.table, l.placeholder(1), l.placeholder(2), l.placeholder(3), l.placeholder(4), l.placeholder(5), l.placeholder(6))
+
+		result, err := tx.ExecContext(ctx, update,
+			pos.Offset, int64(batch.Sequence), batch.ConsumerID, pos.Topic, pos.Partition, pos.Offset)
+		if err != nil {
+			return fmt.Errorf("failed to update ledger for %s[%d]: %w", pos.Topic, pos.Partition, err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to read ledger update result: %w", err)
+		}
+		if affected > 0 {
+			continue
+		}
+
+		exists, err := l.positionExists(ctx, tx, batch.ConsumerID, pos)
+		if err != nil {
+			return err
+		}
+		if exists {
+			// The ledger is already at or beyond this offset
+			continue
+		}
+
+		insert := fmt.Sprintf(
+			"INSERT INTO %s (consumer_id, topic, partition_id, last_offset, batch_sequence) VALUES (%s)",
+			l.table, l.placeholders(5))
+		if _, err := tx.ExecContext(ctx, insert,
+			batch.ConsumerID, pos.Topic, pos.Partition, pos.Offset, int64(batch.Sequence)); err != nil {
+			return fmt.Errorf("failed to insert ledger position for %s[%d]: %w", pos.Topic, pos.Partition, err)
+		}
+	}
+
+	return nil
+}
+
+// Writer returns a BatchWriter that applies each batch with apply and records the
+// positions in the ledger within a single transaction.
+func (l *SQLOffsetLedger) Writer(apply func(ctx context.Context, tx *sql.Tx, batch *Batch) error) BatchWriter {
+	return BatchWriterFunc(func(ctx context.Context, batch *Batch) error {
+		tx, err := l.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+
+		if err := apply(ctx, tx, batch); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := l.RecordPositions(ctx, tx, batch); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit batch %d: %w", batch.Sequence, err)
+		}
+		return nil
+	})
+}
+
+func (l *SQLOffsetLedger) positionExists(ctx context.Context, tx *sql.Tx, consumerID string, pos PartitionPosition) (bool, error) {
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE consumer_id = %s AND topic = %s AND partition_id = %s",
+		l.table, l.placeholder(1), l.placeholder(2), l.placeholder(3))
+
+	var one int
+	err := tx.QueryRowContext(ctx, query, consumerID, pos.Topic, pos.Partition).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check ledger position: %w", err)
+	}
+	return true, nil
+}
+
+func (l *SQLOffsetLedger) placeholders(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = l.placeholder(i + 1)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,372 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PartitionPosition identifies the last applied offset for a topic partition.
+type PartitionPosition struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+}
+
+// Batch is a group of consumed messages that is applied to a target as a unit.
+type Batch struct {
+	// ConsumerID identifies the consumer the batch belongs to in the ledger
+	ConsumerID string
+
+	// Sequence is a monotonically increasing batch number for this coordinator
+	Sequence uint64
+
+	// Messages in consumption order
+	Messages []*Message
+
+	// Positions holds the highest offset per topic partition contained in the batch.
+	// Writers must persist these in the target-side ledger in the same transaction
+	// as the batch data.
+	Positions []PartitionPosition
+}
+
+// BatchWriter durably applies a batch to a target.
+// Implementations must write the batch data and record batch.Positions in the
+// target-side ledger atomically (for example, within one database transaction),
+// so that a successful return means both are durable and a failure means neither is.
+type BatchWriter interface {
+	WriteBatch(ctx context.Context, batch *Batch) error
+}
+
+// BatchWriterFunc adapts a function to the BatchWriter interface.
+type BatchWriterFunc func(ctx context.Context, batch *Batch) error
+
+// WriteBatch calls f(ctx, batch).
+func (f BatchWriterFunc) WriteBatch(ctx context.Context, batch *Batch) error {
+	return f(ctx, batch)
+}
+
+// OffsetLedger reads the positions that have been durably applied to a target.
+// The ledger lives on the target side and is the source of truth for recovery.
+type OffsetLedger interface {
+	// LoadPositions returns the last applied position per topic partition for a consumer.
+	LoadPositions(ctx context.Context, consumerID string) ([]PartitionPosition, error)
+}
+
+// CommitCoordinatorOptions configures a CommitCoordinator.
+type CommitCoordinatorOptions struct {
+	// ConsumerID identifies the consumer in the ledger (defaults to the connection group ID)
+	ConsumerID string
+
+	// MaxBatchSize flushes a batch once it holds this many messages
+	MaxBatchSize int
+
+	// MaxBatchDelay flushes a non-empty batch after this much time has passed
+	MaxBatchDelay time.Duration
+
+	// MaxFlushRetries is how many consecutive timed flushes may fail before consumption
+	// is stopped; failed batches stay pending and are retried with exponential backoff
+	MaxFlushRetries int
+
+	// RetryBackoff is the delay after the first failed timed flush, doubled on each retry
+	RetryBackoff time.Duration
+
+	// OnCommitError is called when committing offsets to the platform fails after the
+	// batch was applied. Such failures are safe, but persistent ones mean the platform
+	// keeps redelivering messages the ledger already records.
+	OnCommitError func(err error)
+}
+
+// CommitCoordinatorStats reports coordinator progress.
+type CommitCoordinatorStats struct {
+	BatchesApplied    uint64
+	MessagesApplied   uint64
+	DuplicatesSkipped uint64
+	CommitFailures    uint64
+}
+
+// CommitCoordinator coordinates consumer offset commits with target writes so that
+// offsets are only committed to the streaming platform after the corresponding batch
+// has been durably applied to the target.
+//
+// The protocol has two phases:
+//  1. The batch and its positions are written to the target and its ledger atomically.
+//  2. The consumer offsets are committed to the streaming platform.
+//
+// If the process crashes between the phases, the platform redelivers messages that
+// are already recorded in the ledger; the coordinator skips them on recovery, so no
+// gaps and no duplicate writes occur.
+type CommitCoordinator struct {
+	conn     Connection
+	consumer ConsumerOperator
+	ledger   OffsetLedger
+	writer   BatchWriter
+	opts     CommitCoordinatorOptions
+
+	mu       sync.Mutex
+	pending  []*Message
+	applied  map[string]int64 // topic/partition -> last applied offset
+	sequence uint64
+	stats    CommitCoordinatorStats
+}
+
+// NewCommitCoordinator creates a coordinator for a connection.
+// The connection must have auto-commit disabled, since offsets are committed by the coordinator.
+func NewCommitCoordinator(conn Connection, ledger OffsetLedger, writer BatchWriter, opts CommitCoordinatorOptions) (*CommitCoordinator, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("connection is required")
+	}
+	if ledger == nil {
+		return nil, fmt.Errorf("offset ledger is required")
+	}
+	if writer == nil {
+		return nil, fmt.Errorf("batch writer is required")
+	}
+
+	cfg := conn.Config()
+	if cfg.EnableAutoCommit {
+		return nil, fmt.Errorf("connection %s has auto-commit enabled; exactly-once delivery requires manual commits", conn.ID())
+	}
+
+	consumer := conn.ConsumerOperations()
+	if consumer == nil {
+		return nil, fmt.Errorf("platform %s does not support consumer operations", conn.Type())
+	}
+
+	if opts.ConsumerID == "" {
+		opts.ConsumerID = cfg.GroupID
+	}
+	if opts.ConsumerID == "" {
+		return nil, fmt.Errorf("consumer ID or connection group ID is required")
+	}
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = 500
+	}
+	if opts.MaxBatchDelay <= 0 {
+		opts.MaxBatchDelay = time.Second
+	}
+	if opts.MaxFlushRetries <= 0 {
+		opts.MaxFlushRetries = 5
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 500 * time.Millisecond
+	}
+
+	return &CommitCoordinator{
+		conn:     conn,
+		consumer: consumer,
+		ledger:   ledger,
+		writer:   writer,
+		opts:     opts,
+		applied:  make(map[string]int64),
+	}, nil
+}
+
+// Run subscribes to the topics, recovers positions from the ledger and consumes until
+// the context is cancelled or the target keeps rejecting batches. Pending messages are
+// flushed before Run returns.
+func (c *CommitCoordinator) Run(ctx context.Context, topics []string) error {
+	if err := c.consumer.Subscribe(ctx, topics, c.opts.ConsumerID); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	if err := c.Recover(ctx); err != nil {
+		return err
+	}
+
+	// The flusher stops consumption when timed flushes keep failing, so that messages
+	// do not pile up in memory while the target is unavailable
+	consumeCtx, stopConsuming := context.WithCancel(ctx)
+	defer stopConsuming()
+
+	flushErr := make(chan error, 1)
+	flusherDone := make(chan struct{})
+	go func() {
+		defer close(flusherDone)
+		c.runFlusher(consumeCtx, stopConsuming, flushErr)
+	}()
+
+	consumeErr := c.consumer.Consume(consumeCtx, c.handle)
+	stopConsuming()
+	<-flusherDone
+
+	select {
+	case err := <-flushErr:
+		// The pending batch was neither applied nor committed, so it is redelivered
+		return fmt.Errorf("stopped consuming after %d failed flushes: %w", c.opts.MaxFlushRetries+1, err)
+	default:
+	}
+
+	// Flush what has been consumed so far with a fresh context, since ctx may be cancelled
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.Flush(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to flush pending batch: %w", err)
+	}
+
+	if consumeErr != nil && consumeErr != context.Canceled {
+		return consumeErr
+	}
+	return nil
+}
+
+// Recover loads applied positions from the ledger and seeks the consumer past them.
+func (c *CommitCoordinator) Recover(ctx context.Context) error {
+	positions, err := c.ledger.LoadPositions(ctx, c.opts.ConsumerID)
+	if err != nil {
+		return fmt.Errorf("failed to load ledger positions: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, pos := range positions {
+		c.applied[positionKey(pos.Topic, pos.Partition)] = pos.Offset
+		if err := c.consumer.Seek(ctx, pos.Topic, pos.Partition, pos.Offset+1); err != nil {
+			return fmt.Errorf("failed to seek %s[%d] to %d: %w", pos.Topic, pos.Partition, pos.Offset+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Flush applies the pending batch to the target and then commits consumer offsets.
+func (c *CommitCoordinator) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flushLocked(ctx)
+}
+
+// Stats returns a snapshot of the coordinator statistics.
+func (c *CommitCoordinator) Stats() CommitCoordinatorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *CommitCoordinator) handle(ctx context.Context, msg *Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Skip messages that are already recorded in the ledger (redelivered after a crash
+	// between the target write and the offset commit)
+	if last, ok := c.applied[positionKey(msg.Topic, msg.Partition)]; ok && msg.Offset <= last {
+		c.stats.DuplicatesSkipped++
+		return nil
+	}
+
+	c.pending = append(c.pending, msg)
+	if len(c.pending) >= c.opts.MaxBatchSize {
+		return c.flushLocked(ctx)
+	}
+	return nil
+}
+
+// runFlusher flushes the pending batch every MaxBatchDelay. Failed flushes are retried
+// with exponential backoff; after MaxFlushRetries consecutive failures the error is
+// reported and consumption is stopped.
+func (c *CommitCoordinator) runFlusher(ctx context.Context, stopConsuming context.CancelFunc, errCh chan<- error) {
+	ticker := time.NewTicker(c.opts.MaxBatchDelay)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := c.Flush(ctx)
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		failures++
+		if failures > c.opts.MaxFlushRetries {
+			errCh <- err
+			stopConsuming()
+			return
+		}
+
+		backoff := time.NewTimer(c.opts.RetryBackoff << (failures - 1))
+		select {
+		case <-ctx.Done():
+			backoff.Stop()
+			return
+		case <-backoff.C:
+		}
+	}
+}
+
+func (c *CommitCoordinator) flushLocked(ctx context.Context) error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	c.sequence++
+	batch := &Batch{
+		ConsumerID: c.opts.ConsumerID,
+		Sequence:   c.sequence,
+		Messages:   c.pending,
+		Positions:  batchPositions(c.pending),
+	}
+
+	// Phase 1: durably apply the batch together with its ledger positions
+	if err := c.writer.WriteBatch(ctx, batch); err != nil {
+		// Keep the messages pending so the batch is retried on the next flush
+		c.sequence--
+		return fmt.Errorf("failed to apply batch %d: %w", batch.Sequence, err)
+	}
+
+	for _, pos := range batch.Positions {
+		c.applied[positionKey(pos.Topic, pos.Partition)] = pos.Offset
+	}
+	c.stats.BatchesApplied++
+	c.stats.MessagesApplied += uint64(len(batch.Messages))
+	c.pending = nil
+
+	// Phase 2: commit offsets to the platform. A failure here is safe because the
+	// ledger already records the batch and redelivered messages are skipped.
+	if err := c.consumer.Commit(ctx); err != nil {
+		c.stats.CommitFailures++
+		if c.opts.OnCommitError != nil {
+			c.opts.OnCommitError(fmt.Errorf("failed to commit offsets after batch %d: %w", batch.Sequence, err))
+		}
+	}
+
+	return nil
+}
+
+// batchPositions returns the highest offset per topic partition, sorted for stable ledger writes
+func batchPositions(messages []*Message) []PartitionPosition {
+	highest := make(map[string]PartitionPosition)
+	for _, msg := range messages {
+		key := positionKey(msg.Topic, msg.Partition)
+		if pos, ok := highest[key]; !ok || msg.Offset > pos.Offset {
+			highest[key] = PartitionPosition{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset}
+		}
+	}
+
+	positions := make([]PartitionPosition, 0, len(highest))
+	for _, pos := range highest {
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Topic != positions[j].Topic {
+			return positions[i].Topic < positions[j].Topic
+		}
+		return positions[i].Partition < positions[j].Partition
+	})
+	return positions
+}
+
+func positionKey(topic string, partition int32) string {
+	return fmt.Sprintf("%s/%d", topic, partition)
+}
@@ -0,0 +1,233 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+)
+
+type fakeConsumer struct {
+	messages  []*Message
+	seeks     []PartitionPosition
+	commits   int
+	commitErr error
+	block     bool // keep consuming until the context is cancelled
+}
+
+func (f *fakeConsumer) Subscribe(ctx context.Context, topics []string, groupID string) error {
+	return nil
+}
+
+func (f *fakeConsumer) Consume(ctx context.Context, handler MessageHandler) error {
+	for _, msg := range f.messages {
+		if err := handler(ctx, msg); err != nil {
+			return err
+		}
+	}
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (f *fakeConsumer) Commit(ctx context.Context) error {
+	f.commits++
+	return f.commitErr
+}
+
+func (f *fakeConsumer) Seek(ctx context.Context, topic string, partition int32, offset int64) error {
+	f.seeks = append(f.seeks, PartitionPosition{Topic: topic, Partition: partition, Offset: offset})
+	return nil
+}
+
+func (f *fakeConsumer) Close() error { return nil }
+
+type fakeConnection struct {
+	consumer *fakeConsumer
+	config   ConnectionConfig
+}
+
+func (f *fakeConnection) ID() string                              { return "test" }
+func (f *fakeConnection) Type() streamcapabilities.StreamPlatform { return "kafka" }
+func (f *fakeConnection) IsConnected() bool                       { return true }
+func (f *fakeConnection) Ping(ctx context.Context) error          { return nil }
+func (f *fakeConnection) Close() error                            { return nil }
+func (f *fakeConnection) ProducerOperations() ProducerOperator    { return nil }
+func (f *fakeConnection) ConsumerOperations() ConsumerOperator    { return f.consumer }
+func (f *fakeConnection) AdminOperations() AdminOperator          { return nil }
+func (f *fakeConnection) Raw() interface{}                        { return nil }
+func (f *fakeConnection) Config() ConnectionConfig                { return f.config }
+func (f *fakeConnection) Adapter() StreamAdapter                  { return nil }
+
+type memoryLedger struct {
+	positions map[string]PartitionPosition
+}
+
+func (m *memoryLedger) LoadPositions(ctx context.Context, consumerID string) ([]PartitionPosition, error) {
+	var out []PartitionPosition
+	for _, pos := range m.positions {
+		out = append(out, pos)
+	}
+	return out, nil
+}
+
+func newTestConnection(messages []*Message) *fakeConnection {
+	cfg := NewConnectionConfig("test", "kafka")
+	cfg.EnableAutoCommit = false
+	cfg.GroupID = "group"
+	return &fakeConnection{consumer: &fakeConsumer{messages: messages}, config: *cfg}
+}
+
+func TestCommitCoordinatorRejectsAutoCommit(t *testing.T) {
+	conn := newTestConnection(nil)
+	conn.config.EnableAutoCommit = true
+
+	_, err := NewCommitCoordinator(conn, &memoryLedger{}, BatchWriterFunc(func(context.Context, *Batch) error { return nil }), CommitCoordinatorOptions{})
+	if err == nil {
+		t.Fatal("expected error when auto-commit is enabled")
+	}
+}
+
+func TestCommitCoordinatorSkipsAppliedMessagesAndCommitsAfterWrite(t *testing.T) {
+	messages := []*Message{
+		{Topic: "orders", Partition: 0, Offset: 4},
+		{Topic: "orders", Partition: 0, Offset: 5},
+		{Topic: "orders", Partition: 0, Offset: 6},
+		{Topic: "orders", Partition: 1, Offset: 2},
+	}
+	conn := newTestConnection(messages)
+	ledger := &memoryLedger{positions: map[string]PartitionPosition{
+		"orders/0": {Topic: "orders", Partition: 0, Offset: 5},
+	}}
+
+	var written []*Batch
+	writer := BatchWriterFunc(func(ctx context.Context, batch *Batch) error {
+		if conn.consumer.commits != len(written) {
+			t.Fatalf("offsets committed before batch %d was applied", batch.Sequence)
+		}
+		written = append(written, batch)
+		return nil
+	})
+
+	coord, err := NewCommitCoordinator(conn, ledger, writer, CommitCoordinatorOptions{MaxBatchSize: 10})
+	if err != nil {
+		t.Fatalf("NewCommitCoordinator: %v", err)
+	}
+	if err := coord.Run(context.Background(), []string{"orders"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(conn.consumer.seeks) != 1 || conn.consumer.seeks[0].Offset != 6 {
+		t.Fatalf("expected seek to offset 6, got %+v", conn.consumer.seeks)
+	}
+	if len(written) != 1 || len(written[0].Messages) != 2 {
+		t.Fatalf("expected one batch with 2 messages, got %+v", written)
+	}
+	want := []PartitionPosition{{"orders", 0, 6}, {"orders", 1, 2}}
+	for i, pos := range written[0].Positions {
+		if pos != want[i] {
+			t.Errorf("position %d = %+v, want %+v", i, pos, want[i])
+		}
+	}
+	if stats := coord.Stats(); stats.DuplicatesSkipped != 2 || conn.consumer.commits != 1 {
+		t.Errorf("unexpected stats %+v, commits %d", stats, conn.consumer.commits)
+	}
+}
+
+func TestCommitCoordinatorKeepsBatchPendingOnWriteFailure(t *testing.T) {
+	conn := newTestConnection([]*Message{{Topic: "orders", Partition: 0, Offset: 1}})
+	fail := true
+	writer := BatchWriterFunc(func(ctx context.Context, batch *Batch) error {
+		if fail {
+			return errors.New("target unavailable")
+		}
+		return nil
+	})
+
+	coord, err := NewCommitCoordinator(conn, &memoryLedger{}, writer, CommitCoordinatorOptions{MaxBatchSize: 1})
+	if err != nil {
+		t.Fatalf("NewCommitCoordinator: %v", err)
+	}
+	if err := coord.Run(context.Background(), []string{"orders"}); err == nil {
+		t.Fatal("expected run to fail when the target write fails")
+	}
+	if conn.consumer.commits != 0 {
+		t.Fatal("offsets must not be committed when the target write fails")
+	}
+
+	fail = false
+	if err := coord.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if stats := coord.Stats(); stats.MessagesApplied != 1 || conn.consumer.commits != 1 {
+		t.Errorf("expected pending batch to be applied on retry, got %+v", stats)
+	}
+}
+
+func TestCommitCoordinatorStopsConsumingWhenFlushesKeepFailing(t *testing.T) {
+	conn := newTestConnection([]*Message{{Topic: "orders", Partition: 0, Offset: 1}})
+	conn.consumer.block = true
+
+	var attempts int32
+	writer := BatchWriterFunc(func(ctx context.Context, batch *Batch) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("target unavailable")
+	})
+
+	coord, err := NewCommitCoordinator(conn, &memoryLedger{}, writer, CommitCoordinatorOptions{
+		MaxBatchSize:    100,
+		MaxBatchDelay:   time.Millisecond,
+		MaxFlushRetries: 2,
+		RetryBackoff:    time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewCommitCoordinator: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- coord.Run(context.Background(), []string{"orders"}) }()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "target unavailable") {
+			t.Fatalf("Run = %v, want the flush error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumption was not stopped after repeated flush failures")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("batch was attempted %d times, want 3", n)
+	}
+	if conn.consumer.commits != 0 {
+		t.Error("offsets must not be committed when the target write fails")
+	}
+}
+
+func TestCommitCoordinatorReportsCommitFailures(t *testing.T) {
+	conn := newTestConnection([]*Message{{Topic: "orders", Partition: 0, Offset: 1}})
+	conn.consumer.commitErr = errors.New("coordinator not available")
+
+	var reported []error
+	coord, err := NewCommitCoordinator(conn, &memoryLedger{},
+		BatchWriterFunc(func(context.Context, *Batch) error { return nil }),
+		CommitCoordinatorOptions{MaxBatchSize: 1, OnCommitError: func(err error) { reported = append(reported, err) }})
+	if err != nil {
+		t.Fatalf("NewCommitCoordinator: %v", err)
+	}
+	if err := coord.Run(context.Background(), []string{"orders"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(reported) != 1 || !errors.Is(reported[0], conn.consumer.commitErr) {
+		t.Fatalf("reported commit errors = %v", reported)
+	}
+	if stats := coord.Stats(); stats.CommitFailures != 1 || stats.MessagesApplied != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,29 @@
+package adapter
+
+import (
+	"crypto/tls"
+
+	"github.com/redbco/redb-open/pkg/tlsconfig"
+)
+
+// TLSConfig returns the TLS configuration of the connection, or nil if TLS is disabled.
+// CertFile and KeyFile are presented to brokers that require mutual TLS and CAFile
+// replaces the system pool for verifying them. The server name is left empty so that
+// each broker is verified against the address it is dialed at.
+func (c *ConnectionConfig) TLSConfig() (*tls.Config, error) {
+	if !c.TLSEnabled {
+		return nil, nil
+	}
+
+	verifyMode := c.TLSVerifyMode
+	if verifyMode == "" && c.TLSSkipVerify {
+		verifyMode = tlsconfig.VerifyNone
+	}
+
+	return tlsconfig.Build(tlsconfig.Options{
+		ClientCert: c.CertFile,
+		ClientKey:  c.KeyFile,
+		CABundle:   c.CAFile,
+		VerifyMode: verifyMode,
+	})
+}
@@ -6,13 +6,14 @@ type StreamPlatform string
 
 const (
 	// Message Queue / Streaming Platforms
-	Kafka      StreamPlatform = "kafka"
-	Redpanda   StreamPlatform = "redpanda"
-	Pulsar     StreamPlatform = "pulsar"
-	RabbitMQ   StreamPlatform = "rabbitmq"
-	NATS       StreamPlatform = "nats"
-	MQTT       StreamPlatform = "mqtt"
-	MQTTServer StreamPlatform = "mqtt_server"
+	Kafka         StreamPlatform = "kafka"
+	Redpanda      StreamPlatform = "redpanda"
+	Pulsar        StreamPlatform = "pulsar"
+	RabbitMQ      StreamPlatform = "rabbitmq"
+	NATS          StreamPlatform = "nats"
+	NATSJetStream StreamPlatform = "nats_jetstream"
+	MQTT          StreamPlatform = "mqtt"
+	MQTTServer    StreamPlatform = "mqtt_server"
 
 	// Cloud Streaming Services
 	Kinesis   StreamPlatform = "kinesis"
@@ -22,6 +23,48 @@ const (
 	SNS       StreamPlatform = "sns"
 )
 
+// DeliveryGuarantee is how often a message is delivered to a consumer when producers, brokers
+// or consumers fail.
+type DeliveryGuarantee string
+
+const (
+	// AtMostOnce delivers a message once or not at all.
+	AtMostOnce DeliveryGuarantee = "at-most-once"
+	// AtLeastOnce delivers a message until it is acknowledged, so it may be delivered again.
+	AtLeastOnce DeliveryGuarantee = "at-least-once"
+	// ExactlyOnce delivers a message once, through idempotent producers and transactions or
+	// broker-side deduplication.
+	ExactlyOnce DeliveryGuarantee = "exactly-once"
+)
+
+// OrderingGuarantee is the scope within which consumers receive messages in the order they
+// were produced.
+type OrderingGuarantee string
+
+const (
+	// OrderingNone gives no ordering guarantee.
+	OrderingNone OrderingGuarantee = "none"
+	// OrderingPartition orders the messages of a partition or shard, which producers pick by key.
+	OrderingPartition OrderingGuarantee = "partition"
+	// OrderingKey orders the messages that share an ordering key.
+	OrderingKey OrderingGuarantee = "key"
+	// OrderingStream orders all messages of a topic or stream.
+	OrderingStream OrderingGuarantee = "stream"
+)
+
+// RetentionModel is how long a platform keeps the messages it delivered.
+type RetentionModel string
+
+const (
+	// RetentionNone keeps no messages: only connected consumers receive them.
+	RetentionNone RetentionModel = "none"
+	// RetentionQueue keeps a message until a consumer acknowledges it.
+	RetentionQueue RetentionModel = "queue"
+	// RetentionLog keeps messages for a retention period after they are consumed, so
+	// consumers can replay them from an offset.
+	RetentionLog RetentionModel = "log"
+)
+
 // Capability describes what a streaming platform supports in a way that microservices can consume uniformly.
 type Capability struct {
 	// Human-friendly platform name, e.g., "Apache Kafka".
@@ -71,6 +114,18 @@ type Capability struct {
 
 	// Whether the platform supports wildcards in topic/queue subscriptions
 	SupportsWildcards bool `json:"supportsWildcards"`
+
+	// Delivery guarantees the platform can provide, weakest first
+	DeliveryGuarantees []DeliveryGuarantee `json:"deliveryGuarantees"`
+
+	// Scope of the ordering guarantee, OrderingNone if SupportsOrdering is false
+	Ordering OrderingGuarantee `json:"ordering"`
+
+	// Default maximum size of a message in bytes; brokers may be configured with another limit
+	MaxMessageSize int64 `json:"maxMessageSize"`
+
+	// How long the platform keeps messages
+	Retention RetentionModel `json:"retention"`
 }
 
 // All is a registry of capabilities keyed by the canonical platform ID.
@@ -92,6 +147,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     true,
 		SupportsOrdering:         true,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingPartition,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionLog,
 	},
 	Redpanda: {
 		Name:                     "Redpanda",
@@ -110,6 +169,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     true,
 		SupportsOrdering:         true,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingPartition,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionLog,
 	},
 	Kinesis: {
 		Name:                     "AWS Kinesis",
@@ -128,6 +191,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         true,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce},
+		Ordering:                 OrderingPartition,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionLog,
 	},
 	PubSub: {
 		Name:                     "Google Cloud Pub/Sub",
@@ -146,6 +213,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         true,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingKey,
+		MaxMessageSize:           10000000,
+		Retention:                RetentionQueue,
 	},
 	EventHubs: {
 		Name:                     "Azure Event Hubs",
@@ -164,6 +235,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         true,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce},
+		Ordering:                 OrderingPartition,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionLog,
 	},
 	Pulsar: {
 		Name:                     "Apache Pulsar",
@@ -182,6 +257,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     true,
 		SupportsOrdering:         true,
 		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingPartition,
+		MaxMessageSize:           5242880,
+		Retention:                RetentionLog,
 	},
 	RabbitMQ: {
 		Name:                     "RabbitMQ",
@@ -200,6 +279,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     true,
 		SupportsOrdering:         false,
 		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           16777216,
+		Retention:                RetentionQueue,
 	},
 	NATS: {
 		Name:                     "NATS",
@@ -218,6 +301,32 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         false,
 		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionNone,
+	},
+	NATSJetStream: {
+		Name:                     "NATS JetStream",
+		ID:                       NATSJetStream,
+		SupportsProducer:         true,
+		SupportsConsumer:         true,
+		SupportsServerMode:       false,
+		SupportsPartitions:       false,
+		SupportsConsumerGroups:   true,
+		SupportsSASL:             false,
+		SupportsTLS:              true,
+		DefaultPort:              4222,
+		DefaultSSLPort:           4222,
+		SchemaRegistrySupport:    false,
+		ConnectionStringTemplate: "nats://{{hosts}}/{{stream}}",
+		SupportsTransactions:     false,
+		SupportsOrdering:         true,
+		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingStream,
+		MaxMessageSize:           1048576,
+		Retention:                RetentionLog,
 	},
 	MQTT: {
 		Name:                     "MQTT Client",
@@ -236,6 +345,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         false,
 		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           268435455,
+		Retention:                RetentionNone,
 	},
 	MQTTServer: {
 		Name:                     "MQTT Broker",
@@ -254,6 +367,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         false,
 		SupportsWildcards:        true,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtMostOnce, AtLeastOnce, ExactlyOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           268435455,
+		Retention:                RetentionNone,
 	},
 	SQS: {
 		Name:                     "AWS SQS",
@@ -272,6 +389,10 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         false,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtLeastOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           262144,
+		Retention:                RetentionQueue,
 	},
 	SNS: {
 		Name:                     "AWS SNS",
@@ -290,5 +411,9 @@ var All = map[StreamPlatform]Capability{
 		SupportsTransactions:     false,
 		SupportsOrdering:         false,
 		SupportsWildcards:        false,
+		DeliveryGuarantees:       []DeliveryGuarantee{AtLeastOnce},
+		Ordering:                 OrderingNone,
+		MaxMessageSize:           262144,
+		Retention:                RetentionNone,
 	},
 }
@@ -0,0 +1,44 @@
+package streamcapabilities
+
+import "testing"
+
+func TestDeliveryAndOrderingMetadata(t *testing.T) {
+	for id, c := range All {
+		if len(c.DeliveryGuarantees) == 0 {
+			t.Errorf("%s: no delivery guarantees", id)
+		}
+		if (c.Ordering != OrderingNone) != c.SupportsOrdering {
+			t.Errorf("%s: ordering %q does not match SupportsOrdering %v", id, c.Ordering, c.SupportsOrdering)
+		}
+		if c.MaxMessageSize <= 0 {
+			t.Errorf("%s: no maximum message size", id)
+		}
+		if c.Retention == "" {
+			t.Errorf("%s: no retention model", id)
+		}
+	}
+}
+
+func TestDeliveryGuaranteeHelpers(t *testing.T) {
+	if !SupportsExactlyOnce(Kafka) || SupportsExactlyOnce(SQS) {
+		t.Error("SupportsExactlyOnce: Kafka should and SQS should not")
+	}
+	if g := GetDefaultDeliveryGuarantee(Kafka); g != AtLeastOnce {
+		t.Errorf("GetDefaultDeliveryGuarantee(Kafka) = %q", g)
+	}
+	if g := GetDefaultDeliveryGuarantee(NATS); g != AtMostOnce {
+		t.Errorf("GetDefaultDeliveryGuarantee(NATS) = %q", g)
+	}
+	if g := GetStrongestDeliveryGuarantee("unknown"); g != "" {
+		t.Errorf("GetStrongestDeliveryGuarantee(unknown) = %q", g)
+	}
+	if !SupportsReplay(Kinesis) || SupportsReplay(RabbitMQ) {
+		t.Error("SupportsReplay: Kinesis should and RabbitMQ should not")
+	}
+	if !SupportsReplay(NATSJetStream) || SupportsReplay(NATS) {
+		t.Error("SupportsReplay: NATS JetStream should and core NATS should not")
+	}
+	if g := GetDefaultDeliveryGuarantee(NATSJetStream); g != AtLeastOnce {
+		t.Errorf("GetDefaultDeliveryGuarantee(NATSJetStream) = %q", g)
+	}
+}
@@ -1,6 +1,7 @@
 // Package streamcapabilities provides a shared registry describing the capabilities of
 // streaming platforms supported by the platform. Microservices can import this package to
-// make decisions based on uniform metadata (producer/consumer support, partitions, TLS).
+// make decisions based on uniform metadata (producer/consumer support, partitions, TLS,
+// delivery guarantees, ordering, message size and retention).
 //
 // Minimal usage example:
 //
@@ -30,6 +31,16 @@
 //	    return streamcapabilities.Get(canonical)
 //	}
 //
+// Example: Picking safe defaults for a stream from its delivery and ordering metadata:
+//
+//	guarantee := streamcapabilities.GetDefaultDeliveryGuarantee(platform)
+//	if streamcapabilities.GetOrdering(platform) == streamcapabilities.OrderingNone {
+//	    // Changes to the same row may arrive out of order, apply them by version
+//	}
+//	if size > streamcapabilities.GetMaxMessageSize(platform) {
+//	    // Split the batch
+//	}
+//
 // The package exposes constants for platform IDs (e.g., streamcapabilities.Kafka) and a
 // registry `All` for advanced consumers.
 package streamcapabilities
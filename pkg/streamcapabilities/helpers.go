@@ -88,6 +88,81 @@ func GetDefaultSSLPort(platform StreamPlatform) int {
 	return cap.DefaultSSLPort
 }
 
+// SupportsDeliveryGuarantee checks if the platform can provide a delivery guarantee.
+func SupportsDeliveryGuarantee(platform StreamPlatform, guarantee DeliveryGuarantee) bool {
+	cap, ok := Get(platform)
+	if !ok {
+		return false
+	}
+	for _, g := range cap.DeliveryGuarantees {
+		if g == guarantee {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsExactlyOnce checks if the platform can deliver messages exactly once.
+func SupportsExactlyOnce(platform StreamPlatform) bool {
+	return SupportsDeliveryGuarantee(platform, ExactlyOnce)
+}
+
+// GetStrongestDeliveryGuarantee returns the strongest delivery guarantee of the platform.
+// Returns an empty guarantee if the platform is not found.
+func GetStrongestDeliveryGuarantee(platform StreamPlatform) DeliveryGuarantee {
+	cap, ok := Get(platform)
+	if !ok || len(cap.DeliveryGuarantees) == 0 {
+		return ""
+	}
+	return cap.DeliveryGuarantees[len(cap.DeliveryGuarantees)-1]
+}
+
+// GetDefaultDeliveryGuarantee returns the delivery guarantee to use when none is configured:
+// at-least-once, which loses no messages without the cost of exactly-once, or the strongest
+// guarantee of platforms that cannot redeliver. Returns an empty guarantee if the platform is
+// not found.
+func GetDefaultDeliveryGuarantee(platform StreamPlatform) DeliveryGuarantee {
+	if SupportsDeliveryGuarantee(platform, AtLeastOnce) {
+		return AtLeastOnce
+	}
+	return GetStrongestDeliveryGuarantee(platform)
+}
+
+// GetOrdering returns the ordering guarantee of the platform.
+// Returns OrderingNone if the platform is not found.
+func GetOrdering(platform StreamPlatform) OrderingGuarantee {
+	cap, ok := Get(platform)
+	if !ok || cap.Ordering == "" {
+		return OrderingNone
+	}
+	return cap.Ordering
+}
+
+// GetMaxMessageSize returns the default maximum message size of the platform in bytes.
+// Returns 0 if the platform is not found.
+func GetMaxMessageSize(platform StreamPlatform) int64 {
+	cap, ok := Get(platform)
+	if !ok {
+		return 0
+	}
+	return cap.MaxMessageSize
+}
+
+// GetRetention returns the retention model of the platform.
+// Returns RetentionNone if the platform is not found.
+func GetRetention(platform StreamPlatform) RetentionModel {
+	cap, ok := Get(platform)
+	if !ok || cap.Retention == "" {
+		return RetentionNone
+	}
+	return cap.Retention
+}
+
+// SupportsReplay checks if consumers of the platform can replay messages they already consumed.
+func SupportsReplay(platform StreamPlatform) bool {
+	return GetRetention(platform) == RetentionLog
+}
+
 // IsValidPlatform checks if the given string is a valid streaming platform.
 func IsValidPlatform(platform string) bool {
 	_, ok := Get(StreamPlatform(strings.ToLower(platform)))
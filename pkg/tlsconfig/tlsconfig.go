@@ -0,0 +1,161 @@
+// Package tlsconfig builds the TLS configurations of connections to databases and streaming
+// platforms: client certificates for mutual TLS, custom CA bundles, and how strictly the
+// certificate of the server is verified.
+//
+// Certificates, keys and CA bundles are given either as PEM contents or as paths of PEM files
+// on the node that connects:
+//
+//	config, err := tlsconfig.Build(tlsconfig.Options{
+//		ServerName: "db.internal",
+//		ClientCert: certPEM,
+//		ClientKey:  keyPEM,
+//		CABundle:   "/etc/redb/ca.pem",
+//		VerifyMode: tlsconfig.VerifyCA,
+//	})
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Verification modes of server certificates
+const (
+	// VerifyFull verifies the certificate chain of the server and that the certificate is
+	// issued for the host connected to
+	VerifyFull = "verify-full"
+
+	// VerifyCA verifies the certificate chain of the server but not its host name, for
+	// servers reached through addresses their certificates do not name
+	VerifyCA = "verify-ca"
+
+	// VerifyNone encrypts the connection without verifying the server
+	VerifyNone = "none"
+)
+
+// pemMarker starts every PEM block; values without it are paths of PEM files
+const pemMarker = "-----BEGIN"
+
+// Options configures a TLS connection
+type Options struct {
+	ServerName string // Host name verified with VerifyFull
+	ClientCert string // Client certificate for mutual TLS, PEM or path
+	ClientKey  string // Private key of the client certificate, PEM or path
+	CABundle   string // CA certificates verifying the server instead of the system pool, PEM or path
+	VerifyMode string // VerifyFull if empty
+}
+
+// ValidVerifyMode reports whether mode is a known verification mode; empty selects VerifyFull
+func ValidVerifyMode(mode string) bool {
+	switch mode {
+	case "", VerifyFull, VerifyCA, VerifyNone:
+		return true
+	}
+	return false
+}
+
+// FromSSLMode returns the verification mode of a PostgreSQL-style SSL mode. As in libpq,
+// "require" verifies the certificate chain once a CA bundle is given; an empty mode verifies
+// fully unless unauthorized certificates are accepted.
+func FromSSLMode(sslMode string, rejectUnauthorized *bool, hasCABundle bool) string {
+	switch strings.ToLower(sslMode) {
+	case VerifyFull:
+		return VerifyFull
+	case VerifyCA:
+		return VerifyCA
+	case "require", "prefer", "allow":
+		if hasCABundle {
+			return VerifyCA
+		}
+		return VerifyNone
+	}
+	if rejectUnauthorized != nil && !*rejectUnauthorized {
+		return VerifyNone
+	}
+	return VerifyFull
+}
+
+// Build returns the TLS configuration of the options
+func Build(opts Options) (*tls.Config, error) {
+	if !ValidVerifyMode(opts.VerifyMode) {
+		return nil, fmt.Errorf("unknown TLS verification mode %q", opts.VerifyMode)
+	}
+
+	config := &tls.Config{
+		ServerName: opts.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, errors.New("client certificate and key must be given together")
+		}
+		certPEM, err := load(opts.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		keyPEM, err := load(opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client key: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.CABundle != "" {
+		bundle, err := load(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("CA bundle contains no certificates")
+		}
+		config.RootCAs = pool
+	}
+
+	switch opts.VerifyMode {
+	case VerifyCA:
+		// The standard verification includes the host name, so the chain is verified here
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyChain(state, config.RootCAs)
+		}
+	case VerifyNone:
+		config.InsecureSkipVerify = true
+	}
+
+	return config, nil
+}
+
+// verifyChain verifies the certificate chain of a server against roots, or the system pool
+// if roots is nil, without checking its host name
+func verifyChain(state tls.ConnectionState, roots *x509.CertPool) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// load returns PEM contents as is and reads paths of PEM files
+func load(value string) ([]byte, error) {
+	if strings.Contains(value, pemMarker) {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
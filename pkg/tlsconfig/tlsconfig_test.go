@@ -0,0 +1,223 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, hosts ...string) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     hosts,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// handshake connects a client with config to a server presenting server, which requires a
+// client certificate issued by ca and confirms the handshake once both sides are verified
+func handshake(t *testing.T, config *tls.Config, server, ca *testCert) error {
+	t.Helper()
+	serverCert, err := tls.X509KeyPair([]byte(server.certPEM), []byte(server.keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if err := conn.(*tls.Conn).Handshake(); err == nil {
+			_, _ = conn.Write([]byte("ok"))
+		}
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// With TLS 1.3 the server verifies the client certificate after the client's handshake
+	if _, err := io.ReadFull(conn, make([]byte, 2)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestBuild(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	otherCA := newTestCert(t, "other-ca", nil)
+	server := newTestCert(t, "server", ca, "db.internal")
+	client := newTestCert(t, "client", ca)
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"verify full", Options{ServerName: "db.internal", ClientCert: client.certPEM, ClientKey: client.keyPEM, CABundle: ca.certPEM}, false},
+		{"verify full wrong host", Options{ServerName: "10.0.0.5", ClientCert: client.certPEM, ClientKey: client.keyPEM, CABundle: ca.certPEM}, true},
+		{"verify ca wrong host", Options{ServerName: "10.0.0.5", ClientCert: client.certPEM, ClientKey: client.keyPEM, CABundle: ca.certPEM, VerifyMode: VerifyCA}, false},
+		{"verify ca wrong ca", Options{ServerName: "db.internal", ClientCert: client.certPEM, ClientKey: client.keyPEM, CABundle: otherCA.certPEM, VerifyMode: VerifyCA}, true},
+		{"verify none", Options{ClientCert: client.certPEM, ClientKey: client.keyPEM, VerifyMode: VerifyNone}, false},
+		{"no client certificate", Options{ServerName: "db.internal", CABundle: ca.certPEM}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Build(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = handshake(t, config, server, ca)
+			if tt.wantErr && err == nil {
+				t.Error("expected handshake to fail")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected handshake error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuild_Files(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca, "db.internal")
+	client := newTestCert(t, "client", ca)
+
+	dir := t.TempDir()
+	files := map[string]string{"client.pem": client.certPEM, "client.key": client.keyPEM, "ca.pem": ca.certPEM}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := Build(Options{
+		ServerName: "db.internal",
+		ClientCert: filepath.Join(dir, "client.pem"),
+		ClientKey:  filepath.Join(dir, "client.key"),
+		CABundle:   filepath.Join(dir, "ca.pem"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := handshake(t, config, server, ca); err != nil {
+		t.Errorf("unexpected handshake error: %v", err)
+	}
+}
+
+func TestBuild_Invalid(t *testing.T) {
+	client := newTestCert(t, "client", nil)
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"unknown verify mode", Options{VerifyMode: "strict"}},
+		{"certificate without key", Options{ClientCert: client.certPEM}},
+		{"key without certificate", Options{ClientKey: client.keyPEM}},
+		{"mismatched key", Options{ClientCert: client.certPEM, ClientKey: newTestCert(t, "other", nil).keyPEM}},
+		{"missing file", Options{CABundle: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"empty bundle", Options{CABundle: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Build(tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestFromSSLMode(t *testing.T) {
+	reject := false
+
+	tests := []struct {
+		name               string
+		sslMode            string
+		rejectUnauthorized *bool
+		hasCABundle        bool
+		expected           string
+	}{
+		{"verify full", "verify-full", nil, false, VerifyFull},
+		{"verify ca", "verify-ca", nil, true, VerifyCA},
+		{"require", "require", nil, false, VerifyNone},
+		{"require with ca", "require", nil, true, VerifyCA},
+		{"empty", "", nil, false, VerifyFull},
+		{"accept unauthorized", "", &reject, false, VerifyNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromSSLMode(tt.sslMode, tt.rejectUnauthorized, tt.hasCABundle); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
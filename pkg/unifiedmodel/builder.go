@@ -0,0 +1,314 @@
+package unifiedmodel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+var (
+	// ErrBuilderFrozen is returned when adding to a builder whose model has been built
+	ErrBuilderFrozen = errors.New("unified model builder is frozen")
+
+	// ErrDuplicateObject is returned when adding an object whose name is already taken
+	ErrDuplicateObject = errors.New("duplicate object")
+)
+
+// BuilderEvent describes an object added to a Builder.
+type BuilderEvent struct {
+	ObjectType ObjectType
+	Table      string // Table of columns, indexes and constraints added to a table
+	Name       string
+	Object     interface{}
+}
+
+// Builder builds a UnifiedModel incrementally. It is safe for concurrent use, so adapters can
+// discover objects in parallel and add them as they come. Objects are keyed by name, and adding
+// a name twice fails with ErrDuplicateObject. Once the model is built the builder is frozen and
+// further adds fail with ErrBuilderFrozen.
+type Builder struct {
+	mu         sync.Mutex
+	model      *UnifiedModel
+	frozen     bool
+	listeners  []func(BuilderEvent)
+	provenance Provenance
+}
+
+// NewBuilder creates a builder for a model of the given database type.
+func NewBuilder(databaseType dbcapabilities.DatabaseType) *Builder {
+	return &Builder{
+		model: &UnifiedModel{DatabaseType: databaseType},
+	}
+}
+
+// OnAdd registers a listener that is called after each object added with one of the Add methods,
+// e.g. to stream objects while discovery is still running. Listeners are called from the adding
+// goroutine, outside of the builder's lock, so events of concurrent adds arrive in any order.
+func (b *Builder) OnAdd(listener func(BuilderEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, listener)
+}
+
+// SetProvenance sets the provenance recorded for the objects added from now on, usually the
+// discovery run ID, discovery time and adapter version. Columns, indexes and constraints of
+// tables inherit the provenance of their table (see GetProvenance). Use RecordProvenance to add
+// source identifiers of single objects.
+func (b *Builder) SetProvenance(provenance Provenance) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.provenance = provenance
+}
+
+// RecordProvenance records the provenance of the object at path (see ObjectPath), with unset
+// fields taken from the provenance set with SetProvenance.
+func (b *Builder) RecordProvenance(path string, provenance Provenance) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.frozen {
+		return ErrBuilderFrozen
+	}
+	return b.model.SetProvenance(path, provenance.merged(b.provenance))
+}
+
+// AddSchema adds a schema.
+func (b *Builder) AddSchema(schema Schema) error {
+	return addObject(b, ObjectTypeSchema, func(um *UnifiedModel) *map[string]Schema { return &um.Schemas }, schema.Name, schema)
+}
+
+// AddTable adds a complete table. Use UpdateTable or the AddTable* methods to build a table
+// incrementally; AddTable fails for a table they already created.
+func (b *Builder) AddTable(table Table) error {
+	return addObject(b, ObjectTypeTable, func(um *UnifiedModel) *map[string]Table { return &um.Tables }, table.Name, table)
+}
+
+// AddCollection adds a collection.
+func (b *Builder) AddCollection(collection Collection) error {
+	return addObject(b, ObjectTypeCollection, func(um *UnifiedModel) *map[string]Collection { return &um.Collections }, collection.Name, collection)
+}
+
+// AddView adds a view.
+func (b *Builder) AddView(view View) error {
+	return addObject(b, ObjectTypeView, func(um *UnifiedModel) *map[string]View { return &um.Views }, view.Name, view)
+}
+
+// AddMaterializedView adds a materialized view.
+func (b *Builder) AddMaterializedView(view MaterializedView) error {
+	return addObject(b, ObjectTypeMaterializedView, func(um *UnifiedModel) *map[string]MaterializedView { return &um.MaterializedViews }, view.Name, view)
+}
+
+// AddType adds a user-defined type.
+func (b *Builder) AddType(typ Type) error {
+	return addObject(b, ObjectTypeType, func(um *UnifiedModel) *map[string]Type { return &um.Types }, typ.Name, typ)
+}
+
+// AddSequence adds a sequence.
+func (b *Builder) AddSequence(sequence Sequence) error {
+	return addObject(b, ObjectTypeSequence, func(um *UnifiedModel) *map[string]Sequence { return &um.Sequences }, sequence.Name, sequence)
+}
+
+// AddFunction adds a function.
+func (b *Builder) AddFunction(function Function) error {
+	return addObject(b, ObjectTypeFunction, func(um *UnifiedModel) *map[string]Function { return &um.Functions }, function.Name, function)
+}
+
+// AddProcedure adds a procedure.
+func (b *Builder) AddProcedure(procedure Procedure) error {
+	return addObject(b, ObjectTypeProcedure, func(um *UnifiedModel) *map[string]Procedure { return &um.Procedures }, procedure.Name, procedure)
+}
+
+// AddTrigger adds a trigger.
+func (b *Builder) AddTrigger(trigger Trigger) error {
+	return addObject(b, ObjectTypeTrigger, func(um *UnifiedModel) *map[string]Trigger { return &um.Triggers }, trigger.Name, trigger)
+}
+
+// AddExtension adds an extension.
+func (b *Builder) AddExtension(extension Extension) error {
+	return addObject(b, ObjectTypeExtension, func(um *UnifiedModel) *map[string]Extension { return &um.Extensions }, extension.Name, extension)
+}
+
+// AddIndex adds a model-level index, for databases whose indexes are not part of a table.
+func (b *Builder) AddIndex(index Index) error {
+	return addObject(b, ObjectTypeIndex, func(um *UnifiedModel) *map[string]Index { return &um.Indexes }, index.Name, index)
+}
+
+// AddConstraint adds a model-level constraint.
+func (b *Builder) AddConstraint(constraint Constraint) error {
+	return addObject(b, ObjectTypeConstraint, func(um *UnifiedModel) *map[string]Constraint { return &um.Constraints }, constraint.Name, constraint)
+}
+
+// AddTableColumn adds a column to a table, creating the table if it was not added yet.
+func (b *Builder) AddTableColumn(tableName string, column Column) error {
+	return addTableObject(b, ObjectTypeColumn, tableName, func(table *Table) *map[string]Column { return &table.Columns }, column.Name, column)
+}
+
+// AddTableIndex adds an index to a table, creating the table if it was not added yet.
+func (b *Builder) AddTableIndex(tableName string, index Index) error {
+	return addTableObject(b, ObjectTypeIndex, tableName, func(table *Table) *map[string]Index { return &table.Indexes }, index.Name, index)
+}
+
+// AddTableConstraint adds a constraint to a table, creating the table if it was not added yet.
+func (b *Builder) AddTableConstraint(tableName string, constraint Constraint) error {
+	return addTableObject(b, ObjectTypeConstraint, tableName, func(table *Table) *map[string]Constraint { return &table.Constraints }, constraint.Name, constraint)
+}
+
+// UpdateTable calls update with the named table, creating it if it was not added yet, and stores
+// the result. The builder is locked during the call, so update must not call the builder.
+func (b *Builder) UpdateTable(tableName string, update func(table *Table) error) error {
+	if tableName == "" {
+		return fmt.Errorf("table name cannot be empty")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.frozen {
+		return ErrBuilderFrozen
+	}
+
+	table := b.tableLocked(tableName)
+	if err := update(&table); err != nil {
+		return err
+	}
+	b.model.Tables[tableName] = table
+	b.recordProvenanceLocked(ObjectPath(ObjectTypeTable, tableName))
+	return nil
+}
+
+// Update calls update with the model under the builder's lock, for objects that have no Add
+// method. Listeners are not notified. update must not keep the model or call the builder.
+func (b *Builder) Update(update func(um *UnifiedModel) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.frozen {
+		return ErrBuilderFrozen
+	}
+	return update(b.model)
+}
+
+// Freeze stops the builder from accepting objects and returns the model. Calling Freeze again
+// returns the same model.
+func (b *Builder) Freeze() *UnifiedModel {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.frozen = true
+	return b.model
+}
+
+// Build freezes the builder and validates the model with the given options. The model and
+// report are returned even if the model has critical issues, in which case the error says so.
+func (b *Builder) Build(options ValidationOptions) (*UnifiedModel, *ValidationReport, error) {
+	um := b.Freeze()
+
+	report, err := Validate(um, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !report.Valid {
+		return um, report, fmt.Errorf("unified model has %d critical issue(s)", report.Count(ValidationErrorCritical))
+	}
+	return um, report, nil
+}
+
+// tableLocked returns the named table, or a new table if it was not added yet. The caller holds
+// the lock and stores the table back.
+func (b *Builder) tableLocked(tableName string) Table {
+	if b.model.Tables == nil {
+		b.model.Tables = make(map[string]Table)
+	}
+	table, exists := b.model.Tables[tableName]
+	if !exists {
+		table = Table{Name: tableName}
+	}
+	if table.Columns == nil {
+		table.Columns = make(map[string]Column)
+	}
+	return table
+}
+
+// recordProvenanceLocked records the builder's provenance for the object at path, unless the
+// object already has provenance. The caller holds the lock.
+func (b *Builder) recordProvenanceLocked(path string) {
+	if b.provenance.IsZero() {
+		return
+	}
+	if _, exists := b.model.Provenance[path]; exists {
+		return
+	}
+	b.model.SetProvenance(path, Provenance{}.merged(b.provenance))
+}
+
+// notify calls the listeners with an event. It is called without the lock held.
+func (b *Builder) notify(listeners []func(BuilderEvent), event BuilderEvent) {
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// addObject adds an object to one of the model's maps
+func addObject[T any](b *Builder, objectType ObjectType, field func(um *UnifiedModel) *map[string]T, name string, object T) error {
+	if name == "" {
+		return fmt.Errorf("%s name cannot be empty", objectType)
+	}
+
+	b.mu.Lock()
+	if b.frozen {
+		b.mu.Unlock()
+		return ErrBuilderFrozen
+	}
+
+	objects := field(b.model)
+	if *objects == nil {
+		*objects = make(map[string]T)
+	}
+	if _, exists := (*objects)[name]; exists {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: %s %q", ErrDuplicateObject, objectType, name)
+	}
+	(*objects)[name] = object
+	b.recordProvenanceLocked(ObjectPath(objectType, name))
+	listeners := b.listeners
+	b.mu.Unlock()
+
+	b.notify(listeners, BuilderEvent{ObjectType: objectType, Name: name, Object: object})
+	return nil
+}
+
+// addTableObject adds an object to one of a table's maps
+func addTableObject[T any](b *Builder, objectType ObjectType, tableName string, field func(table *Table) *map[string]T, name string, object T) error {
+	if tableName == "" {
+		return fmt.Errorf("table name cannot be empty")
+	}
+	if name == "" {
+		return fmt.Errorf("%s name cannot be empty", objectType)
+	}
+
+	b.mu.Lock()
+	if b.frozen {
+		b.mu.Unlock()
+		return ErrBuilderFrozen
+	}
+
+	table := b.tableLocked(tableName)
+	objects := field(&table)
+	if *objects == nil {
+		*objects = make(map[string]T)
+	}
+	if _, exists := (*objects)[name]; exists {
+		b.mu.Unlock()
+		return fmt.Errorf("%w: %s %q of table %q", ErrDuplicateObject, objectType, name, tableName)
+	}
+	(*objects)[name] = object
+	b.model.Tables[tableName] = table
+	b.recordProvenanceLocked(ObjectPath(ObjectTypeTable, tableName))
+	listeners := b.listeners
+	b.mu.Unlock()
+
+	b.notify(listeners, BuilderEvent{ObjectType: objectType, Table: tableName, Name: name, Object: object})
+	return nil
+}
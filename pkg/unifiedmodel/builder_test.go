@@ -0,0 +1,140 @@
+package unifiedmodel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func TestBuilder_ConcurrentAdds(t *testing.T) {
+	builder := NewBuilder(dbcapabilities.PostgreSQL)
+
+	var events int64
+	builder.OnAdd(func(event BuilderEvent) {
+		atomic.AddInt64(&events, 1)
+	})
+
+	const tables, columns = 20, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, tables*(columns+1))
+	for i := 0; i < tables; i++ {
+		tableName := fmt.Sprintf("table_%d", i)
+		for j := 0; j < columns; j++ {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				errs <- builder.AddTableColumn(tableName, Column{Name: fmt.Sprintf("col_%d", j), DataType: "integer", IsPrimaryKey: j == 0})
+			}(j)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- builder.UpdateTable(tableName, func(table *Table) error {
+				table.Comment = "discovered"
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	um, report, err := builder.Build(ValidationOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v (%+v)", err, report)
+	}
+	if len(um.Tables) != tables {
+		t.Fatalf("expected %d tables, got %d", tables, len(um.Tables))
+	}
+	for name, table := range um.Tables {
+		if len(table.Columns) != columns || table.Comment != "discovered" || table.Name != name {
+			t.Errorf("table %s was not built completely: %+v", name, table)
+		}
+	}
+	if events != tables*columns {
+		t.Errorf("expected %d events, got %d", tables*columns, events)
+	}
+}
+
+func TestBuilder_Duplicates(t *testing.T) {
+	builder := NewBuilder(dbcapabilities.PostgreSQL)
+
+	if err := builder.AddTableColumn("users", Column{Name: "id", DataType: "integer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := builder.AddTableColumn("users", Column{Name: "id", DataType: "bigint"}); !errors.Is(err, ErrDuplicateObject) {
+		t.Errorf("expected a duplicate column error, got %v", err)
+	}
+	if err := builder.AddTable(Table{Name: "users"}); !errors.Is(err, ErrDuplicateObject) {
+		t.Errorf("expected a duplicate table error, got %v", err)
+	}
+	if err := builder.AddSequence(Sequence{Name: "users_id_seq"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := builder.AddSequence(Sequence{Name: "users_id_seq"}); !errors.Is(err, ErrDuplicateObject) {
+		t.Errorf("expected a duplicate sequence error, got %v", err)
+	}
+	if err := builder.AddView(View{}); err == nil {
+		t.Error("expected an error for a view without name")
+	}
+}
+
+func TestBuilder_Frozen(t *testing.T) {
+	builder := NewBuilder(dbcapabilities.MySQL)
+	if err := builder.AddTable(Table{Name: "users", Columns: map[string]Column{"id": {Name: "id", DataType: "int", IsPrimaryKey: true}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	um := builder.Freeze()
+	if um.DatabaseType != dbcapabilities.MySQL || len(um.Tables) != 1 {
+		t.Fatalf("unexpected model: %+v", um)
+	}
+	if builder.Freeze() != um {
+		t.Error("expected Freeze to return the same model")
+	}
+
+	if err := builder.AddTable(Table{Name: "orders"}); !errors.Is(err, ErrBuilderFrozen) {
+		t.Errorf("expected a frozen error, got %v", err)
+	}
+	if err := builder.AddTableColumn("users", Column{Name: "email"}); !errors.Is(err, ErrBuilderFrozen) {
+		t.Errorf("expected a frozen error, got %v", err)
+	}
+	if err := builder.Update(func(um *UnifiedModel) error { return nil }); !errors.Is(err, ErrBuilderFrozen) {
+		t.Errorf("expected a frozen error, got %v", err)
+	}
+}
+
+func TestBuilder_BuildValidates(t *testing.T) {
+	builder := NewBuilder(dbcapabilities.PostgreSQL)
+	if err := builder.AddTableColumn("orders", Column{Name: "id", DataType: "integer", IsPrimaryKey: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := builder.AddTableConstraint("orders", Constraint{
+		Name:      "orders_user_fkey",
+		Type:      ConstraintTypeForeignKey,
+		Columns:   []string{"id"},
+		Reference: Reference{Table: "users", Columns: []string{"id"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	um, report, err := builder.Build(ValidationOptions{DisableLint: true})
+	if err == nil {
+		t.Fatal("expected an error for the missing referenced table")
+	}
+	if um == nil || report == nil || report.Valid {
+		t.Errorf("expected the model and an invalid report, got %v %+v", um, report)
+	}
+
+	if _, _, err := NewBuilder(dbcapabilities.PostgreSQL).Build(ValidationOptions{LintRules: []string{"no_such_rule"}}); err == nil {
+		t.Error("expected an error for invalid options")
+	}
+}
@@ -0,0 +1,181 @@
+package unifiedmodel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// MarshalCanonical returns the canonical JSON serialization of a schema. Two schemas with the
+// same content serialize to the same bytes, however they were built or loaded:
+//   - object keys are sorted and no insignificant whitespace is written
+//   - null values, empty objects and empty arrays are left out, so nil and empty maps are equal
+//   - strings are written without HTML escaping
+//   - provenance is left out, as it describes the discovery of objects rather than their content
+//
+// Array order is kept, as it is significant for columns of indexes, constraints and the like.
+// The canonical form is meant for hashing and comparison; use SerializeSchema for storage.
+func MarshalCanonical(schema *UnifiedModel) ([]byte, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema cannot be nil")
+	}
+
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	if members, ok := value.(map[string]interface{}); ok {
+		delete(members, "provenance")
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ContentHash returns the SHA-256 hash of the canonical serialization of a schema as a hex
+// string. Equal hashes mean equal content, which allows skipping schema comparisons, storing
+// a schema version only once and keying caches by schema content.
+func ContentHash(schema *UnifiedModel) (string, error) {
+	canonical, err := MarshalCanonical(schema)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ContentHashJSON returns the content hash of a schema stored as JSON. The JSON is loaded into a
+// UnifiedModel first, so the hash matches ContentHash of the schema it was serialized from.
+func ContentHashJSON(data []byte) (string, error) {
+	schema, err := DeserializeSchema(data)
+	if err != nil {
+		return "", err
+	}
+	return ContentHash(schema)
+}
+
+// SameContent reports whether two schemas have the same content. A nil schema only has the
+// same content as another nil schema.
+func SameContent(a, b *UnifiedModel) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+
+	hashA, err := ContentHash(a)
+	if err != nil {
+		return false, err
+	}
+	hashB, err := ContentHash(b)
+	if err != nil {
+		return false, err
+	}
+	return hashA == hashB, nil
+}
+
+// writeCanonical writes a decoded JSON value in canonical form
+func writeCanonical(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key, member := range v {
+			if !isEmptyJSONValue(member) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, element); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+
+	case string:
+		return writeCanonicalString(buf, v)
+
+	case json.Number:
+		buf.WriteString(v.String())
+
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+
+	case nil:
+		buf.WriteString("null")
+
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", value)
+	}
+
+	return nil
+}
+
+// writeCanonicalString writes a JSON string without HTML escaping
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("failed to encode string: %w", err)
+	}
+	// Encode terminates the value with a newline
+	buf.Write(bytes.TrimSuffix(out.Bytes(), []byte("\n")))
+	return nil
+}
+
+// isEmptyJSONValue reports whether a member is left out of the canonical form: null, or an
+// object or array with nothing but such members
+func isEmptyJSONValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, member := range v {
+			if !isEmptyJSONValue(member) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
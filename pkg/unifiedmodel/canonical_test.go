@@ -0,0 +1,138 @@
+package unifiedmodel
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func canonicalTestSchema() *UnifiedModel {
+	return &UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]Column{
+					"id":    {Name: "id", DataType: "integer", IsPrimaryKey: true},
+					"email": {Name: "email", DataType: "varchar(255)", Nullable: true},
+				},
+				Indexes: map[string]Index{
+					"users_email_idx": {Name: "users_email_idx", Columns: []string{"email", "id"}},
+				},
+				Options: map[string]any{"fillfactor": 90, "note": "<a&b>"},
+			},
+		},
+	}
+}
+
+func TestMarshalCanonical_Deterministic(t *testing.T) {
+	first, err := MarshalCanonical(canonicalTestSchema())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		next, err := MarshalCanonical(canonicalTestSchema())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(next) != string(first) {
+			t.Fatalf("canonical serialization is not deterministic:\n%s\n%s", first, next)
+		}
+	}
+}
+
+func TestMarshalCanonical_Format(t *testing.T) {
+	canonical, err := MarshalCanonical(&UnifiedModel{
+		DatabaseType: dbcapabilities.MySQL,
+		Tables: map[string]Table{
+			"b": {Name: "b", Options: map[string]any{"z": 1, "a": "x<y"}},
+		},
+		Views: map[string]View{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"database_type":"mysql","tables":{"b":{"name":"b","options":{"a":"x<y","z":1}}}}`
+	if string(canonical) != expected {
+		t.Errorf("unexpected canonical form:\n got: %s\nwant: %s", canonical, expected)
+	}
+}
+
+func TestContentHash_NilAndEmptyMapsAreEqual(t *testing.T) {
+	withNil := canonicalTestSchema()
+	withEmpty := canonicalTestSchema()
+	withEmpty.Views = map[string]View{}
+	withEmpty.Collections = map[string]Collection{}
+	table := withEmpty.Tables["users"]
+	table.Constraints = map[string]Constraint{}
+	withEmpty.Tables["users"] = table
+
+	hashNil, err := ContentHash(withNil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hashEmpty, err := ContentHash(withEmpty)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hashNil != hashEmpty {
+		t.Errorf("expected nil and empty maps to hash the same, got %s and %s", hashNil, hashEmpty)
+	}
+	if len(hashNil) != 64 {
+		t.Errorf("expected a hex SHA-256 hash, got %q", hashNil)
+	}
+}
+
+func TestContentHash_DetectsChanges(t *testing.T) {
+	original := canonicalTestSchema()
+	originalHash, _ := ContentHash(original)
+
+	changed := canonicalTestSchema()
+	column := changed.Tables["users"].Columns["email"]
+	column.Nullable = false
+	changed.Tables["users"].Columns["email"] = column
+	if hash, _ := ContentHash(changed); hash == originalHash {
+		t.Error("expected a column change to change the hash")
+	}
+
+	// Index column order is significant
+	reordered := canonicalTestSchema()
+	index := reordered.Tables["users"].Indexes["users_email_idx"]
+	index.Columns = []string{"id", "email"}
+	reordered.Tables["users"].Indexes["users_email_idx"] = index
+	if hash, _ := ContentHash(reordered); hash == originalHash {
+		t.Error("expected an index column reorder to change the hash")
+	}
+}
+
+func TestContentHashJSON_MatchesContentHash(t *testing.T) {
+	schema := canonicalTestSchema()
+	stored, err := SerializeSchema(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fromJSON, err := ContentHashJSON(stored)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fromModel, _ := ContentHash(schema)
+	if fromJSON != fromModel {
+		t.Errorf("expected the stored schema to hash like the model, got %s and %s", fromJSON, fromModel)
+	}
+}
+
+func TestSameContent(t *testing.T) {
+	same, err := SameContent(canonicalTestSchema(), canonicalTestSchema())
+	if err != nil || !same {
+		t.Errorf("expected equal schemas to have the same content, got %v, %v", same, err)
+	}
+
+	if same, _ := SameContent(nil, canonicalTestSchema()); same {
+		t.Error("expected a nil schema to differ from a non-nil schema")
+	}
+	if same, _ := SameContent(nil, nil); !same {
+		t.Error("expected two nil schemas to have the same content")
+	}
+}
@@ -586,6 +586,8 @@ func EnhancedCompareSchemas(source, target *UnifiedModel, options EnhancedCompar
 		allChanges = allChanges[:options.MaxDiffCount]
 	}
 
+	annotateProvenance(source, target, allChanges)
+
 	result.StructuralChanges = allChanges
 	result.HasStructuralChanges = len(allChanges) > 0
 	result.StructuralSimilarity = calculateStructuralSimilarity(source, target, allChanges)
@@ -658,25 +660,19 @@ func GenerateSchemaID(schema *UnifiedModel) string {
 }
 
 // GenerateSchemaHash generates a SHA-256 hash of the schema structure.
-// This is used for quick equality checks and change detection.
+// This is used for quick equality checks and change detection; see ContentHash.
 func GenerateSchemaHash(schema *UnifiedModel) string {
 	if schema == nil {
 		return ""
 	}
 
-	// Create a normalized representation for hashing
-	normalized := normalizeForHashing(schema)
-
-	// Convert to JSON for stable hashing
-	jsonBytes, err := json.Marshal(normalized)
+	hash, err := ContentHash(schema)
 	if err != nil {
 		// Fallback to string representation
-		jsonBytes = []byte(fmt.Sprintf("%+v", normalized))
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%+v", schema)))
+		return fmt.Sprintf("%x", sum)
 	}
-
-	// Generate SHA-256 hash
-	hash := sha256.Sum256(jsonBytes)
-	return fmt.Sprintf("%x", hash)
+	return hash
 }
 
 // ValidateSchema validates a UnifiedModel schema for consistency and completeness.
@@ -821,6 +817,12 @@ func MergeSchemas(base, overlay *UnifiedModel) (*UnifiedModel, error) {
 	mergeSchemaObjects(merged.Roles, overlay.Roles)
 	mergeSchemaObjects(merged.Grants, overlay.Grants)
 	mergeSchemaObjects(merged.Policies, overlay.Policies)
+	if len(overlay.Provenance) > 0 {
+		if merged.Provenance == nil {
+			merged.Provenance = make(map[string]Provenance, len(overlay.Provenance))
+		}
+		mergeSchemaObjects(merged.Provenance, overlay.Provenance)
+	}
 
 	return merged, nil
 }
@@ -828,6 +830,7 @@ func MergeSchemas(base, overlay *UnifiedModel) (*UnifiedModel, error) {
 // ValidationError represents a validation issue found in a schema
 type ValidationError struct {
 	Type       ValidationErrorType `json:"type"`
+	Rule       string              `json:"rule,omitempty"` // Check or lint rule that reported the issue, set by Validate
 	Field      string              `json:"field"`
 	Message    string              `json:"message"`
 	Suggestion string              `json:"suggestion,omitempty"`
@@ -843,18 +846,6 @@ const (
 
 // Helper functions for the newly added functions
 
-func normalizeForHashing(schema *UnifiedModel) *UnifiedModel {
-	// Create a copy for normalization
-	normalized, _ := CloneSchema(schema)
-	if normalized == nil {
-		return nil
-	}
-
-	// Sort maps by keys for stable hashing
-	// JSON marshaling will handle map key sorting automatically
-	return normalized
-}
-
 func validateTable(tableName string, table Table) []ValidationError {
 	var errors []ValidationError
 
@@ -957,6 +948,23 @@ func validateIndex(indexName string, index Index, schema *UnifiedModel) []Valida
 	return errors
 }
 
+// annotateProvenance sets the provenance of changed objects: that of the source for removed
+// objects, and that of the target otherwise
+func annotateProvenance(source, target *UnifiedModel, changes []StructuralChange) {
+	if len(source.Provenance) == 0 && len(target.Provenance) == 0 {
+		return
+	}
+	for i := range changes {
+		model := target
+		if changes[i].ChangeType == ChangeTypeRemoved {
+			model = source
+		}
+		if provenance, ok := model.GetProvenance(changes[i].ObjectPath); ok {
+			changes[i].Provenance = &provenance
+		}
+	}
+}
+
 func mergeSchemaObjects[T any](target, source map[string]T) {
 	if source == nil {
 		return
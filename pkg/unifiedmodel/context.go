@@ -196,7 +196,8 @@ type StructuralChange struct {
 	TargetValue *string        `json:"target_value,omitempty"`
 	Description string         `json:"description"`
 	Severity    ChangeSeverity `json:"severity"`
-	IsBreaking  bool           `json:"is_breaking"` // Breaking change for applications
+	IsBreaking  bool           `json:"is_breaking"`          // Breaking change for applications
+	Provenance  *Provenance    `json:"provenance,omitempty"` // Discovery of the added, modified or removed object, if recorded
 }
 
 // EnrichmentChange represents a difference in enrichment metadata
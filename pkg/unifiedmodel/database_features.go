@@ -422,6 +422,25 @@ func init() {
 		},
 	}
 
+	// SQLite features (Embedded relational)
+	DatabaseFeatureRegistry[dbcapabilities.SQLite] = DatabaseFeatureSupport{
+		DatabaseType: dbcapabilities.SQLite,
+		Paradigms:    []dbcapabilities.DataParadigm{dbcapabilities.ParadigmRelational},
+		SupportedObjects: map[ObjectType]ObjectSupport{
+			ObjectTypeTable:            FullSupport(),
+			ObjectTypeView:             FullSupport(),
+			ObjectTypeMaterializedView: UnsupportedObject([]ObjectType{ObjectTypeTable}, "Use tables populated by triggers"),
+			ObjectTypeCollection:       UnsupportedObject([]ObjectType{ObjectTypeTable}, "Use tables with JSON columns"),
+			ObjectTypeNode:             UnsupportedObject([]ObjectType{ObjectTypeTable}, "Use tables with foreign keys"),
+			ObjectTypeVector:           UnsupportedObject(nil, "Vector operations not supported"),
+		},
+		ConversionCapabilities: ConversionCapabilities{
+			CanBeSource:          true,
+			CanBeTarget:          true,
+			PreferredTargetTypes: []dbcapabilities.DatabaseType{dbcapabilities.PostgreSQL, dbcapabilities.MySQL},
+		},
+	}
+
 	// EdgeDB features (Next-gen relational)
 	DatabaseFeatureRegistry[dbcapabilities.EdgeDB] = DatabaseFeatureSupport{
 		DatabaseType: dbcapabilities.EdgeDB,
@@ -523,6 +542,26 @@ func init() {
 		},
 	}
 
+	// BigQuery features (Serverless data warehouse)
+	DatabaseFeatureRegistry[dbcapabilities.BigQuery] = DatabaseFeatureSupport{
+		DatabaseType: dbcapabilities.BigQuery,
+		Paradigms:    []dbcapabilities.DataParadigm{dbcapabilities.ParadigmColumnar},
+		SupportedObjects: map[ObjectType]ObjectSupport{
+			ObjectTypeTable:            FullSupport(),
+			ObjectTypeView:             FullSupport(),
+			ObjectTypeMaterializedView: FullSupport(),
+			ObjectTypeCollection:       UnsupportedObject([]ObjectType{ObjectTypeTable}, "Use tables with RECORD or JSON columns"),
+			ObjectTypeNode:             UnsupportedObject([]ObjectType{ObjectTypeTable}, "Use tables with foreign keys"),
+			ObjectTypeVector:           PartialSupport([]string{"vector search functions"}, "Vectors stored as repeated FLOAT64 columns"),
+		},
+		ConversionCapabilities: ConversionCapabilities{
+			CanBeSource:           true,
+			CanBeTarget:           true,
+			PreferredSourceTypes:  []dbcapabilities.DatabaseType{dbcapabilities.PostgreSQL, dbcapabilities.MySQL, dbcapabilities.Snowflake},
+			ConversionLimitations: []string{"Optimized for analytics workloads", "Primary and foreign keys are not enforced", "No change data capture"},
+		},
+	}
+
 	// Weaviate features (Vector search engine)
 	DatabaseFeatureRegistry[dbcapabilities.Weaviate] = DatabaseFeatureSupport{
 		DatabaseType: dbcapabilities.Weaviate,
@@ -1,11 +1,25 @@
 package unifiedmodel
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	pb "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// ConvertToProto converts a Go UnifiedModel to protobuf UnifiedModel
+// ConvertToProto converts a Go UnifiedModel to protobuf UnifiedModel.
+//
+// The conversion covers tables, schemas, views, functions, procedures, triggers, sequences, types
+// and provenance; other objects and options are dropped. Slices and label maps are shared with the
+// protobuf message rather than copied, so the model must not be modified while the message is in
+// use. Use ConvertToProtoStrict to reject models that do not convert without loss.
 func (um *UnifiedModel) ConvertToProto() *pb.UnifiedModel {
 	if um == nil {
 		return nil
@@ -13,78 +27,64 @@ func (um *UnifiedModel) ConvertToProto() *pb.UnifiedModel {
 
 	pbUM := &pb.UnifiedModel{
 		DatabaseType: string(um.DatabaseType),
-		Tables:       make(map[string]*pb.Table),
-		Schemas:      make(map[string]*pb.Schema),
-		Views:        make(map[string]*pb.View),
-		Functions:    make(map[string]*pb.Function),
-		Procedures:   make(map[string]*pb.Procedure),
-		Triggers:     make(map[string]*pb.Trigger),
-		Sequences:    make(map[string]*pb.Sequence),
-		Types:        make(map[string]*pb.Type),
-		Indexes:      make(map[string]*pb.Index),
-		Constraints:  make(map[string]*pb.Constraint),
+		Tables:       make(map[string]*pb.Table, len(um.Tables)),
+		Schemas:      make(map[string]*pb.Schema, len(um.Schemas)),
+		Views:        make(map[string]*pb.View, len(um.Views)),
+		Functions:    make(map[string]*pb.Function, len(um.Functions)),
+		Procedures:   make(map[string]*pb.Procedure, len(um.Procedures)),
+		Triggers:     make(map[string]*pb.Trigger, len(um.Triggers)),
+		Sequences:    make(map[string]*pb.Sequence, len(um.Sequences)),
+		Types:        make(map[string]*pb.Type, len(um.Types)),
 	}
 
 	// Convert tables
 	for name, table := range um.Tables {
 		pbTable := &pb.Table{
-			Name:        table.Name,
-			Owner:       table.Owner,
-			Comment:     table.Comment,
-			Labels:      table.Labels,
-			Columns:     make(map[string]*pb.Column),
-			Indexes:     make(map[string]*pb.Index),
-			Constraints: make(map[string]*pb.Constraint),
-		}
-
-		// Convert columns
-		for colName, col := range table.Columns {
-			pbTable.Columns[colName] = &pb.Column{
-				Name:                col.Name,
-				DataType:            col.DataType,
-				Nullable:            col.Nullable,
-				DefaultValue:        col.Default,
-				GeneratedExpression: col.GeneratedExpression,
-				IsPrimaryKey:        col.IsPrimaryKey,
-				IsPartitionKey:      col.IsPartitionKey,
-				IsClusteringKey:     col.IsClusteringKey,
-				AutoIncrement:       col.AutoIncrement,
-				Collation:           col.Collation,
-			}
+			Name:    table.Name,
+			Owner:   table.Owner,
+			Comment: table.Comment,
+			Labels:  table.Labels,
+			Columns: columnsToProto(table.Columns),
 		}
 
 		// Convert indexes
-		for idxName, idx := range table.Indexes {
-			pbTable.Indexes[idxName] = &pb.Index{
-				Name:       idx.Name,
-				Type:       string(idx.Type),
-				Columns:    idx.Columns,
-				Fields:     idx.Fields,
-				Expression: idx.Expression,
-				Predicate:  idx.Predicate,
-				Unique:     idx.Unique,
+		if len(table.Indexes) > 0 {
+			pbTable.Indexes = make(map[string]*pb.Index, len(table.Indexes))
+			for idxName, idx := range table.Indexes {
+				pbTable.Indexes[idxName] = &pb.Index{
+					Name:       idx.Name,
+					Type:       string(idx.Type),
+					Columns:    idx.Columns,
+					Fields:     idx.Fields,
+					Expression: idx.Expression,
+					Predicate:  idx.Predicate,
+					Unique:     idx.Unique,
+				}
 			}
 		}
 
 		// Convert constraints
-		for constName, constraint := range table.Constraints {
-			pbConstraint := &pb.Constraint{
-				Name:       constraint.Name,
-				Type:       string(constraint.Type),
-				Columns:    constraint.Columns,
-				Expression: constraint.Expression,
-			}
+		if len(table.Constraints) > 0 {
+			pbTable.Constraints = make(map[string]*pb.Constraint, len(table.Constraints))
+			for constName, constraint := range table.Constraints {
+				pbConstraint := &pb.Constraint{
+					Name:       constraint.Name,
+					Type:       string(constraint.Type),
+					Columns:    constraint.Columns,
+					Expression: constraint.Expression,
+				}
 
-			if constraint.Reference.Table != "" {
-				pbConstraint.Reference = &pb.Reference{
-					Table:    constraint.Reference.Table,
-					Columns:  constraint.Reference.Columns,
-					OnUpdate: constraint.Reference.OnUpdate,
-					OnDelete: constraint.Reference.OnDelete,
+				if ref := constraint.Reference; ref.Table != "" || len(ref.Columns) > 0 || ref.OnUpdate != "" || ref.OnDelete != "" {
+					pbConstraint.Reference = &pb.Reference{
+						Table:    constraint.Reference.Table,
+						Columns:  constraint.Reference.Columns,
+						OnUpdate: constraint.Reference.OnUpdate,
+						OnDelete: constraint.Reference.OnDelete,
+					}
 				}
-			}
 
-			pbTable.Constraints[constName] = pbConstraint
+				pbTable.Constraints[constName] = pbConstraint
+			}
 		}
 
 		pbUM.Tables[name] = pbTable
@@ -102,68 +102,33 @@ func (um *UnifiedModel) ConvertToProto() *pb.UnifiedModel {
 
 	// Convert views
 	for name, view := range um.Views {
-		pbView := &pb.View{
+		pbUM.Views[name] = &pb.View{
 			Name:       view.Name,
 			Definition: view.Definition,
 			Comment:    view.Comment,
-			Columns:    make(map[string]*pb.Column),
-		}
-
-		for colName, col := range view.Columns {
-			pbView.Columns[colName] = &pb.Column{
-				Name:                col.Name,
-				DataType:            col.DataType,
-				Nullable:            col.Nullable,
-				DefaultValue:        col.Default,
-				GeneratedExpression: col.GeneratedExpression,
-				IsPrimaryKey:        col.IsPrimaryKey,
-				IsPartitionKey:      col.IsPartitionKey,
-				IsClusteringKey:     col.IsClusteringKey,
-				AutoIncrement:       col.AutoIncrement,
-				Collation:           col.Collation,
-			}
+			Columns:    columnsToProto(view.Columns),
 		}
-
-		pbUM.Views[name] = pbView
 	}
 
 	// Convert functions
 	for name, function := range um.Functions {
-		pbFunction := &pb.Function{
+		pbUM.Functions[name] = &pb.Function{
 			Name:       function.Name,
 			Language:   function.Language,
 			Returns:    function.Returns,
 			Definition: function.Definition,
-			Arguments:  make([]*pb.Argument, len(function.Arguments)),
-		}
-
-		for i, arg := range function.Arguments {
-			pbFunction.Arguments[i] = &pb.Argument{
-				Name: arg.Name,
-				Type: arg.Type,
-			}
+			Arguments:  argumentsToProto(function.Arguments),
 		}
-
-		pbUM.Functions[name] = pbFunction
 	}
 
 	// Convert procedures
 	for name, procedure := range um.Procedures {
-		pbProcedure := &pb.Procedure{
+		pbUM.Procedures[name] = &pb.Procedure{
 			Name:       procedure.Name,
 			Language:   procedure.Language,
 			Definition: procedure.Definition,
-			Arguments:  make([]*pb.Argument, len(procedure.Arguments)),
-		}
-
-		for i, arg := range procedure.Arguments {
-			pbProcedure.Arguments[i] = &pb.Argument{
-				Name: arg.Name,
-				Type: arg.Type,
-			}
+			Arguments:  argumentsToProto(procedure.Arguments),
 		}
-
-		pbUM.Procedures[name] = pbProcedure
 	}
 
 	// Convert triggers
@@ -207,10 +172,79 @@ func (um *UnifiedModel) ConvertToProto() *pb.UnifiedModel {
 		}
 	}
 
+	// Convert provenance
+	if len(um.Provenance) > 0 {
+		pbUM.Provenance = make(map[string]*pb.Provenance, len(um.Provenance))
+		for path, provenance := range um.Provenance {
+			pbProvenance := &pb.Provenance{
+				DiscoveryRunId:    provenance.DiscoveryRunID,
+				SourceIdentifiers: provenance.SourceIdentifiers,
+				AdapterVersion:    provenance.AdapterVersion,
+			}
+			if !provenance.DiscoveredAt.IsZero() {
+				pbProvenance.DiscoveredAt = provenance.DiscoveredAt.UnixNano()
+			}
+			pbUM.Provenance[path] = pbProvenance
+		}
+	}
+
 	return pbUM
 }
 
-// ConvertFromProto converts a protobuf UnifiedModel to Go UnifiedModel
+// columnsToProto converts columns. The messages are allocated in one block, as tables and views
+// usually have many columns.
+func columnsToProto(columns map[string]Column) map[string]*pb.Column {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	block := make([]pb.Column, len(columns))
+	pbColumns := make(map[string]*pb.Column, len(columns))
+	i := 0
+	for colName, col := range columns {
+		pbCol := &block[i]
+		i++
+
+		pbCol.Name = col.Name
+		pbCol.DataType = col.DataType
+		pbCol.Nullable = col.Nullable
+		pbCol.DefaultValue = col.Default
+		pbCol.GeneratedExpression = col.GeneratedExpression
+		pbCol.IsPrimaryKey = col.IsPrimaryKey
+		pbCol.IsPartitionKey = col.IsPartitionKey
+		pbCol.IsClusteringKey = col.IsClusteringKey
+		pbCol.AutoIncrement = col.AutoIncrement
+		pbCol.Collation = col.Collation
+		if col.OrdinalPosition != nil {
+			pbCol.OrdinalPosition = int32(*col.OrdinalPosition)
+		}
+
+		pbColumns[colName] = pbCol
+	}
+	return pbColumns
+}
+
+// argumentsToProto converts function and procedure arguments
+func argumentsToProto(arguments []Argument) []*pb.Argument {
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	block := make([]pb.Argument, len(arguments))
+	pbArguments := make([]*pb.Argument, len(arguments))
+	for i, arg := range arguments {
+		block[i].Name = arg.Name
+		block[i].Type = arg.Type
+		pbArguments[i] = &block[i]
+	}
+	return pbArguments
+}
+
+// ConvertFromProto converts a protobuf UnifiedModel to Go UnifiedModel.
+//
+// Objects that have no Go counterpart in the conversion are dropped. As with ConvertToProto,
+// slices and label maps are shared with the message. Use ConvertFromProtoStrict to reject
+// messages that do not convert without loss.
 func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 	if pbUM == nil {
 		return nil
@@ -218,46 +252,37 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 	um := &UnifiedModel{
 		DatabaseType: dbcapabilities.DatabaseType(pbUM.DatabaseType),
-		Tables:       make(map[string]Table),
-		Schemas:      make(map[string]Schema),
-		Views:        make(map[string]View),
-		Functions:    make(map[string]Function),
-		Procedures:   make(map[string]Procedure),
-		Triggers:     make(map[string]Trigger),
-		Sequences:    make(map[string]Sequence),
-		Types:        make(map[string]Type),
+		Tables:       make(map[string]Table, len(pbUM.Tables)),
+		Schemas:      make(map[string]Schema, len(pbUM.Schemas)),
+		Views:        make(map[string]View, len(pbUM.Views)),
+		Functions:    make(map[string]Function, len(pbUM.Functions)),
+		Procedures:   make(map[string]Procedure, len(pbUM.Procedures)),
+		Triggers:     make(map[string]Trigger, len(pbUM.Triggers)),
+		Sequences:    make(map[string]Sequence, len(pbUM.Sequences)),
+		Types:        make(map[string]Type, len(pbUM.Types)),
 	}
 
 	// Convert tables
 	for name, pbTable := range pbUM.Tables {
+		if pbTable == nil {
+			continue
+		}
+
 		table := Table{
 			Name:        pbTable.Name,
 			Owner:       pbTable.Owner,
 			Comment:     pbTable.Comment,
 			Labels:      pbTable.Labels,
-			Columns:     make(map[string]Column),
-			Indexes:     make(map[string]Index),
-			Constraints: make(map[string]Constraint),
-		}
-
-		// Convert columns
-		for colName, pbCol := range pbTable.Columns {
-			table.Columns[colName] = Column{
-				Name:                pbCol.Name,
-				DataType:            pbCol.DataType,
-				Nullable:            pbCol.Nullable,
-				Default:             pbCol.DefaultValue,
-				GeneratedExpression: pbCol.GeneratedExpression,
-				IsPrimaryKey:        pbCol.IsPrimaryKey,
-				IsPartitionKey:      pbCol.IsPartitionKey,
-				IsClusteringKey:     pbCol.IsClusteringKey,
-				AutoIncrement:       pbCol.AutoIncrement,
-				Collation:           pbCol.Collation,
-			}
+			Columns:     columnsFromProto(pbTable.Columns),
+			Indexes:     make(map[string]Index, len(pbTable.Indexes)),
+			Constraints: make(map[string]Constraint, len(pbTable.Constraints)),
 		}
 
 		// Convert indexes
 		for idxName, pbIdx := range pbTable.Indexes {
+			if pbIdx == nil {
+				continue
+			}
 			table.Indexes[idxName] = Index{
 				Name:       pbIdx.Name,
 				Type:       IndexType(pbIdx.Type),
@@ -271,6 +296,10 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 		// Convert constraints
 		for constName, pbConstraint := range pbTable.Constraints {
+			if pbConstraint == nil {
+				continue
+			}
+
 			constraint := Constraint{
 				Name:       pbConstraint.Name,
 				Type:       ConstraintType(pbConstraint.Type),
@@ -295,6 +324,9 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 	// Convert schemas
 	for name, pbSchema := range pbUM.Schemas {
+		if pbSchema == nil {
+			continue
+		}
 		um.Schemas[name] = Schema{
 			Name:    pbSchema.Name,
 			Owner:   pbSchema.Owner,
@@ -305,72 +337,49 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 	// Convert views
 	for name, pbView := range pbUM.Views {
-		view := View{
+		if pbView == nil {
+			continue
+		}
+		um.Views[name] = View{
 			Name:       pbView.Name,
 			Definition: pbView.Definition,
 			Comment:    pbView.Comment,
-			Columns:    make(map[string]Column),
-		}
-
-		for colName, pbCol := range pbView.Columns {
-			view.Columns[colName] = Column{
-				Name:                pbCol.Name,
-				DataType:            pbCol.DataType,
-				Nullable:            pbCol.Nullable,
-				Default:             pbCol.DefaultValue,
-				GeneratedExpression: pbCol.GeneratedExpression,
-				IsPrimaryKey:        pbCol.IsPrimaryKey,
-				IsPartitionKey:      pbCol.IsPartitionKey,
-				IsClusteringKey:     pbCol.IsClusteringKey,
-				AutoIncrement:       pbCol.AutoIncrement,
-				Collation:           pbCol.Collation,
-			}
+			Columns:    columnsFromProto(pbView.Columns),
 		}
-
-		um.Views[name] = view
 	}
 
 	// Convert functions
 	for name, pbFunction := range pbUM.Functions {
-		function := Function{
+		if pbFunction == nil {
+			continue
+		}
+		um.Functions[name] = Function{
 			Name:       pbFunction.Name,
 			Language:   pbFunction.Language,
 			Returns:    pbFunction.Returns,
 			Definition: pbFunction.Definition,
-			Arguments:  make([]Argument, len(pbFunction.Arguments)),
-		}
-
-		for i, pbArg := range pbFunction.Arguments {
-			function.Arguments[i] = Argument{
-				Name: pbArg.Name,
-				Type: pbArg.Type,
-			}
+			Arguments:  argumentsFromProto(pbFunction.Arguments),
 		}
-
-		um.Functions[name] = function
 	}
 
 	// Convert procedures
 	for name, pbProcedure := range pbUM.Procedures {
-		procedure := Procedure{
+		if pbProcedure == nil {
+			continue
+		}
+		um.Procedures[name] = Procedure{
 			Name:       pbProcedure.Name,
 			Language:   pbProcedure.Language,
 			Definition: pbProcedure.Definition,
-			Arguments:  make([]Argument, len(pbProcedure.Arguments)),
-		}
-
-		for i, pbArg := range pbProcedure.Arguments {
-			procedure.Arguments[i] = Argument{
-				Name: pbArg.Name,
-				Type: pbArg.Type,
-			}
+			Arguments:  argumentsFromProto(pbProcedure.Arguments),
 		}
-
-		um.Procedures[name] = procedure
 	}
 
 	// Convert triggers
 	for name, pbTrigger := range pbUM.Triggers {
+		if pbTrigger == nil {
+			continue
+		}
 		um.Triggers[name] = Trigger{
 			Name:      pbTrigger.Name,
 			Table:     pbTrigger.Table,
@@ -382,6 +391,10 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 	// Convert sequences
 	for name, pbSequence := range pbUM.Sequences {
+		if pbSequence == nil {
+			continue
+		}
+
 		sequence := Sequence{
 			Name:      pbSequence.Name,
 			Start:     pbSequence.Start,
@@ -389,14 +402,18 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 			Cycle:     pbSequence.Cycle,
 		}
 
+		// Copy the values so that the model does not point into the message
 		if pbSequence.MinValue != 0 {
-			sequence.Min = &pbSequence.MinValue
+			minValue := pbSequence.MinValue
+			sequence.Min = &minValue
 		}
 		if pbSequence.MaxValue != 0 {
-			sequence.Max = &pbSequence.MaxValue
+			maxValue := pbSequence.MaxValue
+			sequence.Max = &maxValue
 		}
 		if pbSequence.Cache != 0 {
-			sequence.Cache = &pbSequence.Cache
+			cache := pbSequence.Cache
+			sequence.Cache = &cache
 		}
 
 		um.Sequences[name] = sequence
@@ -404,15 +421,224 @@ func ConvertFromProto(pbUM *pb.UnifiedModel) *UnifiedModel {
 
 	// Convert types
 	for name, pbType := range pbUM.Types {
+		if pbType == nil {
+			continue
+		}
 		um.Types[name] = Type{
 			Name:     pbType.Name,
 			Category: pbType.Category,
 		}
 	}
 
+	// Convert provenance
+	if len(pbUM.Provenance) > 0 {
+		um.Provenance = make(map[string]Provenance, len(pbUM.Provenance))
+		for path, pbProvenance := range pbUM.Provenance {
+			if pbProvenance == nil {
+				continue
+			}
+			provenance := Provenance{
+				DiscoveryRunID:    pbProvenance.DiscoveryRunId,
+				SourceIdentifiers: pbProvenance.SourceIdentifiers,
+				AdapterVersion:    pbProvenance.AdapterVersion,
+			}
+			if pbProvenance.DiscoveredAt != 0 {
+				provenance.DiscoveredAt = time.Unix(0, pbProvenance.DiscoveredAt).UTC()
+			}
+			um.Provenance[path] = provenance
+		}
+	}
+
 	return um
 }
 
+// columnsFromProto converts column messages
+func columnsFromProto(pbColumns map[string]*pb.Column) map[string]Column {
+	columns := make(map[string]Column, len(pbColumns))
+	for colName, pbCol := range pbColumns {
+		if pbCol == nil {
+			continue
+		}
+
+		col := Column{
+			Name:                pbCol.Name,
+			DataType:            pbCol.DataType,
+			Nullable:            pbCol.Nullable,
+			Default:             pbCol.DefaultValue,
+			GeneratedExpression: pbCol.GeneratedExpression,
+			IsPrimaryKey:        pbCol.IsPrimaryKey,
+			IsPartitionKey:      pbCol.IsPartitionKey,
+			IsClusteringKey:     pbCol.IsClusteringKey,
+			AutoIncrement:       pbCol.AutoIncrement,
+			Collation:           pbCol.Collation,
+		}
+		if pbCol.OrdinalPosition != 0 {
+			position := int(pbCol.OrdinalPosition)
+			col.OrdinalPosition = &position
+		}
+
+		columns[colName] = col
+	}
+	return columns
+}
+
+// argumentsFromProto converts argument messages
+func argumentsFromProto(pbArguments []*pb.Argument) []Argument {
+	if len(pbArguments) == 0 {
+		return nil
+	}
+
+	arguments := make([]Argument, 0, len(pbArguments))
+	for _, pbArg := range pbArguments {
+		if pbArg == nil {
+			continue
+		}
+		arguments = append(arguments, Argument{Name: pbArg.Name, Type: pbArg.Type})
+	}
+	return arguments
+}
+
+// ConversionLossError is returned by the strict conversions when content would be dropped. Paths
+// are the JSON paths of the lost content, e.g. "tables.users.options".
+type ConversionLossError struct {
+	Direction string // "to_proto" or "from_proto"
+	Paths     []string
+}
+
+func (e *ConversionLossError) Error() string {
+	const maxPaths = 10
+	paths := e.Paths
+	if len(paths) > maxPaths {
+		paths = paths[:maxPaths]
+	}
+	message := fmt.Sprintf("%s conversion would lose %d value(s): %s", e.Direction, len(e.Paths), strings.Join(paths, ", "))
+	if len(e.Paths) > maxPaths {
+		message += ", ..."
+	}
+	return message
+}
+
+// ConvertToProtoStrict converts a Go UnifiedModel to protobuf UnifiedModel, and returns a
+// *ConversionLossError if the message does not hold all of the model's content. The check
+// converts the message back, so it costs about three times a plain conversion.
+func (um *UnifiedModel) ConvertToProtoStrict() (*pb.UnifiedModel, error) {
+	if um == nil {
+		return nil, nil
+	}
+
+	pbUM := um.ConvertToProto()
+
+	original, err := json.Marshal(um)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal model: %w", err)
+	}
+	converted, err := json.Marshal(ConvertFromProto(pbUM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal converted model: %w", err)
+	}
+
+	if err := checkConversionLoss("to_proto", original, converted); err != nil {
+		return nil, err
+	}
+	return pbUM, nil
+}
+
+// ConvertFromProtoStrict converts a protobuf UnifiedModel to Go UnifiedModel, and returns a
+// *ConversionLossError if the model does not hold all of the message's content.
+func ConvertFromProtoStrict(pbUM *pb.UnifiedModel) (*UnifiedModel, error) {
+	if pbUM == nil {
+		return nil, nil
+	}
+
+	um := ConvertFromProto(pbUM)
+
+	options := protojson.MarshalOptions{UseProtoNames: true}
+	original, err := options.Marshal(pbUM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	converted, err := options.Marshal(um.ConvertToProto())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal converted message: %w", err)
+	}
+
+	if err := checkConversionLoss("from_proto", original, converted); err != nil {
+		return nil, err
+	}
+	return um, nil
+}
+
+// checkConversionLoss compares the JSON of a value with the JSON of its round trip through the
+// conversion and reports the content that did not survive it
+func checkConversionLoss(direction string, original, converted []byte) error {
+	originalValue, err := decodeJSONValue(original)
+	if err != nil {
+		return err
+	}
+	convertedValue, err := decodeJSONValue(converted)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	collectLostPaths("", originalValue, convertedValue, &paths)
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+	return &ConversionLossError{Direction: direction, Paths: paths}
+}
+
+// decodeJSONValue decodes JSON into generic values, keeping numbers exact
+func decodeJSONValue(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return value, nil
+}
+
+// collectLostPaths appends the paths of the values of original that are missing or different in
+// converted. Empty values are ignored, like in the canonical form.
+func collectLostPaths(path string, original, converted interface{}, paths *[]string) {
+	switch o := original.(type) {
+	case map[string]interface{}:
+		c, _ := converted.(map[string]interface{})
+		for key, member := range o {
+			if isEmptyJSONValue(member) {
+				continue
+			}
+			memberPath := key
+			if path != "" {
+				memberPath = path + "." + key
+			}
+			convertedMember := c[key]
+			if convertedMember == nil {
+				*paths = append(*paths, memberPath)
+				continue
+			}
+			collectLostPaths(memberPath, member, convertedMember, paths)
+		}
+
+	case []interface{}:
+		c, ok := converted.([]interface{})
+		if !ok || len(c) != len(o) {
+			*paths = append(*paths, path)
+			return
+		}
+		for i := range o {
+			collectLostPaths(path+"["+strconv.Itoa(i)+"]", o[i], c[i], paths)
+		}
+
+	default:
+		if original != converted {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
 // ToProto is a convenience method that calls ConvertToProto
 func (um *UnifiedModel) ToProto() *pb.UnifiedModel {
 	return um.ConvertToProto()
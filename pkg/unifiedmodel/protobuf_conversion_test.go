@@ -0,0 +1,320 @@
+package unifiedmodel
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	pb "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"google.golang.org/protobuf/proto"
+)
+
+// randomProtoModel generates a model using only content the protobuf conversion supports
+func randomProtoModel(r *rand.Rand, tables int) *UnifiedModel {
+	name := func(prefix string) string {
+		return fmt.Sprintf("%s_%d", prefix, r.Intn(1000))
+	}
+	names := func(prefix string) []string {
+		list := make([]string, r.Intn(4))
+		for i := range list {
+			list[i] = name(prefix)
+		}
+		return list
+	}
+	optionalInt64 := func() *int64 {
+		if r.Intn(2) == 0 {
+			return nil
+		}
+		value := r.Int63n(1<<40) + 1
+		return &value
+	}
+	columns := func() map[string]Column {
+		columns := make(map[string]Column)
+		for i := r.Intn(8); i >= 0; i-- {
+			col := Column{
+				Name:                name("col"),
+				DataType:            []string{"integer", "varchar(255)", "text", "numeric(10,2)", "jsonb"}[r.Intn(5)],
+				Nullable:            r.Intn(2) == 0,
+				Default:             []string{"", "0", "'x'", "now()"}[r.Intn(4)],
+				GeneratedExpression: []string{"", "a + b"}[r.Intn(2)],
+				IsPrimaryKey:        r.Intn(4) == 0,
+				IsPartitionKey:      r.Intn(8) == 0,
+				IsClusteringKey:     r.Intn(8) == 0,
+				AutoIncrement:       r.Intn(4) == 0,
+				Collation:           []string{"", "C", "en_US.utf8"}[r.Intn(3)],
+			}
+			if r.Intn(2) == 0 {
+				position := r.Intn(100) + 1
+				col.OrdinalPosition = &position
+			}
+			columns[col.Name] = col
+		}
+		return columns
+	}
+	labels := func() map[string]string {
+		if r.Intn(2) == 0 {
+			return nil
+		}
+		return map[string]string{name("label"): name("value")}
+	}
+
+	um := &UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables:       make(map[string]Table),
+		Schemas:      make(map[string]Schema),
+		Views:        make(map[string]View),
+		Functions:    make(map[string]Function),
+		Procedures:   make(map[string]Procedure),
+		Triggers:     make(map[string]Trigger),
+		Sequences:    make(map[string]Sequence),
+		Types:        make(map[string]Type),
+	}
+
+	for i := 0; i < tables; i++ {
+		table := Table{
+			Name:        fmt.Sprintf("table_%d", i),
+			Owner:       []string{"", "postgres"}[r.Intn(2)],
+			Comment:     []string{"", "a table", "ünïcødé <&>"}[r.Intn(3)],
+			Labels:      labels(),
+			Columns:     columns(),
+			Indexes:     make(map[string]Index),
+			Constraints: make(map[string]Constraint),
+		}
+		for j := r.Intn(3); j > 0; j-- {
+			idx := Index{
+				Name:      name("idx"),
+				Type:      []IndexType{"", IndexTypeBTree, IndexTypeHash}[r.Intn(3)],
+				Columns:   names("col"),
+				Fields:    names("field"),
+				Predicate: []string{"", "deleted_at IS NULL"}[r.Intn(2)],
+				Unique:    r.Intn(2) == 0,
+			}
+			table.Indexes[idx.Name] = idx
+		}
+		for j := r.Intn(3); j > 0; j-- {
+			constraint := Constraint{
+				Name:       name("con"),
+				Type:       []ConstraintType{ConstraintTypePrimaryKey, ConstraintTypeForeignKey, ConstraintTypeCheck}[r.Intn(3)],
+				Columns:    names("col"),
+				Expression: []string{"", "amount > 0"}[r.Intn(2)],
+			}
+			if constraint.Type == ConstraintTypeForeignKey {
+				constraint.Reference = Reference{
+					Table:    name("table"),
+					Columns:  names("col"),
+					OnUpdate: []string{"", "CASCADE"}[r.Intn(2)],
+					OnDelete: []string{"", "SET NULL"}[r.Intn(2)],
+				}
+			}
+			table.Constraints[constraint.Name] = constraint
+		}
+		um.Tables[table.Name] = table
+	}
+
+	for i := r.Intn(3); i > 0; i-- {
+		schema := Schema{Name: name("schema"), Owner: "postgres", Comment: "schema", Labels: labels()}
+		um.Schemas[schema.Name] = schema
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		view := View{Name: name("view"), Definition: "SELECT 1", Comment: "view", Columns: columns()}
+		um.Views[view.Name] = view
+	}
+	arguments := func() []Argument {
+		list := make([]Argument, r.Intn(3))
+		for i := range list {
+			list[i] = Argument{Name: name("arg"), Type: "integer"}
+		}
+		return list
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		function := Function{Name: name("fn"), Language: "plpgsql", Returns: "integer", Definition: "BEGIN RETURN 1; END", Arguments: arguments()}
+		um.Functions[function.Name] = function
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		procedure := Procedure{Name: name("proc"), Language: "sql", Definition: "SELECT 1", Arguments: arguments()}
+		um.Procedures[procedure.Name] = procedure
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		trigger := Trigger{Name: name("trg"), Table: "table_0", Timing: "BEFORE", Events: names("event"), Procedure: name("fn")}
+		um.Triggers[trigger.Name] = trigger
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		sequence := Sequence{
+			Name:      name("seq"),
+			Start:     r.Int63n(100),
+			Increment: r.Int63n(10),
+			Min:       optionalInt64(),
+			Max:       optionalInt64(),
+			Cache:     optionalInt64(),
+			Cycle:     r.Intn(2) == 0,
+		}
+		um.Sequences[sequence.Name] = sequence
+	}
+	for i := r.Intn(3); i > 0; i-- {
+		typ := Type{Name: name("type"), Category: []string{"enum", "composite", "domain"}[r.Intn(3)]}
+		um.Types[typ.Name] = typ
+	}
+
+	return um
+}
+
+func TestProtoConversion_RoundTripProperty(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		um := randomProtoModel(rand.New(rand.NewSource(seed)), int(seed%10))
+
+		roundTrip := ConvertFromProto(um.ConvertToProto())
+		same, err := SameContent(um, roundTrip)
+		if err != nil {
+			t.Fatalf("seed %d: unexpected error: %v", seed, err)
+		}
+		if !same {
+			t.Fatalf("seed %d: round trip changed the model", seed)
+		}
+
+		if _, err := um.ConvertToProtoStrict(); err != nil {
+			t.Fatalf("seed %d: unexpected loss: %v", seed, err)
+		}
+		if _, err := ConvertFromProtoStrict(um.ConvertToProto()); err != nil {
+			t.Fatalf("seed %d: unexpected loss: %v", seed, err)
+		}
+	}
+}
+
+func TestConvertToProtoStrict_RejectsLoss(t *testing.T) {
+	zero := int64(0)
+	um := randomProtoModel(rand.New(rand.NewSource(1)), 1)
+	um.Collections = map[string]Collection{"events": {Name: "events"}}
+	table := um.Tables["table_0"]
+	table.Options = map[string]any{"fillfactor": 70}
+	um.Tables["table_0"] = table
+	um.Sequences["seq_zero"] = Sequence{Name: "seq_zero", Min: &zero}
+
+	_, err := um.ConvertToProtoStrict()
+	var lossErr *ConversionLossError
+	if !errors.As(err, &lossErr) {
+		t.Fatalf("expected a conversion loss error, got %v", err)
+	}
+	expected := []string{"collections", "sequences.seq_zero.min", "tables.table_0.options"}
+	if strings.Join(lossErr.Paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected lost paths %v, got %v", expected, lossErr.Paths)
+	}
+	if lossErr.Direction != "to_proto" {
+		t.Errorf("expected to_proto direction, got %s", lossErr.Direction)
+	}
+}
+
+func TestConvertFromProtoStrict_RejectsLoss(t *testing.T) {
+	pbUM := &pb.UnifiedModel{
+		DatabaseType: "mongodb",
+		Collections:  map[string]*pb.Collection{"events": {Name: "events"}},
+		Tables: map[string]*pb.Table{
+			"users": {
+				Name:    "users",
+				Columns: map[string]*pb.Column{"id": {Name: "id", DataType: "integer"}},
+			},
+		},
+	}
+
+	_, err := ConvertFromProtoStrict(pbUM)
+	var lossErr *ConversionLossError
+	if !errors.As(err, &lossErr) {
+		t.Fatalf("expected a conversion loss error, got %v", err)
+	}
+	if len(lossErr.Paths) != 1 || lossErr.Paths[0] != "collections" {
+		t.Errorf("expected collections to be lost, got %v", lossErr.Paths)
+	}
+
+	pbUM.Collections = nil
+	if _, err := ConvertFromProtoStrict(pbUM); err != nil {
+		t.Errorf("unexpected loss: %v", err)
+	}
+}
+
+func TestConvertFromProto_NilEntries(t *testing.T) {
+	pbUM := &pb.UnifiedModel{
+		Tables: map[string]*pb.Table{
+			"users": {
+				Name:        "users",
+				Columns:     map[string]*pb.Column{"id": nil},
+				Indexes:     map[string]*pb.Index{"idx": nil},
+				Constraints: map[string]*pb.Constraint{"con": nil},
+			},
+			"orders": nil,
+		},
+		Functions: map[string]*pb.Function{"fn": {Name: "fn", Arguments: []*pb.Argument{nil}}},
+		Sequences: map[string]*pb.Sequence{"seq": nil},
+	}
+
+	um := ConvertFromProto(pbUM)
+	if len(um.Tables) != 1 || len(um.Tables["users"].Columns) != 0 {
+		t.Errorf("expected nil entries to be skipped, got %+v", um.Tables)
+	}
+	if len(um.Functions["fn"].Arguments) != 0 || len(um.Sequences) != 0 {
+		t.Errorf("expected nil entries to be skipped, got %+v %+v", um.Functions, um.Sequences)
+	}
+}
+
+func TestConvertFromProto_DoesNotAliasSequenceValues(t *testing.T) {
+	pbUM := &pb.UnifiedModel{
+		Sequences: map[string]*pb.Sequence{"seq": {Name: "seq", MinValue: 1, MaxValue: 10, Cache: 5}},
+	}
+
+	um := ConvertFromProto(pbUM)
+	pbUM.Sequences["seq"].MinValue = 2
+
+	if *um.Sequences["seq"].Min != 1 {
+		t.Errorf("expected the model to keep its own copy, got %d", *um.Sequences["seq"].Min)
+	}
+}
+
+// FuzzConvertFromProto checks that arbitrary messages convert without panicking, and that the
+// converted model survives a further round trip unchanged
+func FuzzConvertFromProto(f *testing.F) {
+	for seed := int64(0); seed < 5; seed++ {
+		data, err := proto.Marshal(randomProtoModel(rand.New(rand.NewSource(seed)), 3).ConvertToProto())
+		if err != nil {
+			f.Fatalf("failed to marshal seed: %v", err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var pbUM pb.UnifiedModel
+		if err := proto.Unmarshal(data, &pbUM); err != nil {
+			return
+		}
+
+		um := ConvertFromProto(&pbUM)
+		again := ConvertFromProto(um.ConvertToProto())
+		same, err := SameContent(um, again)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !same {
+			t.Fatal("converted model changed in a second round trip")
+		}
+	})
+}
+
+func BenchmarkConvertToProto(b *testing.B) {
+	um := randomProtoModel(rand.New(rand.NewSource(1)), 200)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		um.ConvertToProto()
+	}
+}
+
+func BenchmarkConvertFromProto(b *testing.B) {
+	pbUM := randomProtoModel(rand.New(rand.NewSource(1)), 200).ConvertToProto()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ConvertFromProto(pbUM)
+	}
+}
@@ -0,0 +1,128 @@
+package unifiedmodel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Provenance records where an object of a model came from, so that diffs and lineage can
+// explain when and how the object appeared. Provenance describes the discovery of an object
+// rather than the object itself, so it is not part of the model's content: it is left out of
+// MarshalCanonical, ContentHash and SameContent.
+type Provenance struct {
+	DiscoveryRunID    string            `json:"discovery_run_id,omitempty"`   // ID of the discovery run that found the object
+	SourceIdentifiers map[string]string `json:"source_identifiers,omitempty"` // Catalog identifiers of the object in the source, e.g. "oid"
+	DiscoveredAt      time.Time         `json:"discovered_at,omitzero"`       // When the object was discovered, in UTC
+	AdapterVersion    string            `json:"adapter_version,omitempty"`    // Version of the adapter that discovered the object
+}
+
+// IsZero reports whether the provenance records nothing
+func (p Provenance) IsZero() bool {
+	return p.DiscoveryRunID == "" && len(p.SourceIdentifiers) == 0 && p.DiscoveredAt.IsZero() && p.AdapterVersion == ""
+}
+
+// merged returns the provenance with its unset fields taken from defaults. Source identifiers
+// of both are kept, the provenance's own taking precedence.
+func (p Provenance) merged(defaults Provenance) Provenance {
+	if p.DiscoveryRunID == "" {
+		p.DiscoveryRunID = defaults.DiscoveryRunID
+	}
+	if p.DiscoveredAt.IsZero() {
+		p.DiscoveredAt = defaults.DiscoveredAt
+	}
+	if p.AdapterVersion == "" {
+		p.AdapterVersion = defaults.AdapterVersion
+	}
+	if len(defaults.SourceIdentifiers) > 0 {
+		identifiers := make(map[string]string, len(defaults.SourceIdentifiers)+len(p.SourceIdentifiers))
+		for key, value := range defaults.SourceIdentifiers {
+			identifiers[key] = value
+		}
+		for key, value := range p.SourceIdentifiers {
+			identifiers[key] = value
+		}
+		p.SourceIdentifiers = identifiers
+	}
+	return p
+}
+
+// objectPathContainers maps object types to the model member holding them, where the member
+// is not simply the plural of the object type
+var objectPathContainers = map[ObjectType]string{
+	ObjectTypeIndex:           "indexes",
+	ObjectTypeVectorIndex:     "vector_indexes",
+	ObjectTypeSearchIndex:     "search_indexes",
+	ObjectTypeKeyValue:        "key_value_pairs",
+	ObjectTypeProperty:        "properties",
+	ObjectTypePolicy:          "policies",
+	ObjectTypeMemoryTable:     "memory_tables",
+	ObjectTypeTimeSeriesPoint: "time_series_points",
+}
+
+// ObjectPath returns the path of a model-level object, e.g. "tables.users" for the table
+// users. Paths are the ones used by comparisons and validation reports, and key Provenance.
+func ObjectPath(objectType ObjectType, name string) string {
+	container, ok := objectPathContainers[objectType]
+	if !ok {
+		container = string(objectType) + "s"
+	}
+	return container + "." + name
+}
+
+// TableObjectPath returns the path of a column, index or constraint of a table, e.g.
+// "tables.users.columns.email".
+func TableObjectPath(tableName string, objectType ObjectType, name string) string {
+	return ObjectPath(ObjectTypeTable, tableName) + "." + ObjectPath(objectType, name)
+}
+
+// SetProvenance records the provenance of the object at path. The discovery time is stored in
+// UTC. A zero provenance removes the record.
+func (um *UnifiedModel) SetProvenance(path string, provenance Provenance) error {
+	if path == "" {
+		return fmt.Errorf("object path cannot be empty")
+	}
+	if provenance.IsZero() {
+		delete(um.Provenance, path)
+		return nil
+	}
+
+	if um.Provenance == nil {
+		um.Provenance = make(map[string]Provenance)
+	}
+	if !provenance.DiscoveredAt.IsZero() {
+		provenance.DiscoveredAt = provenance.DiscoveredAt.UTC()
+	}
+	um.Provenance[path] = provenance
+	return nil
+}
+
+// GetProvenance returns the provenance of the object at path. Objects inherit the provenance
+// of their parents, so a column without provenance of its own reports that of its table. The
+// path may also point into an object, e.g. "tables.users.columns.email.data_type".
+func (um *UnifiedModel) GetProvenance(path string) (Provenance, bool) {
+	for path != "" {
+		if provenance, ok := um.Provenance[path]; ok {
+			return provenance, true
+		}
+		idx := strings.LastIndex(path, ".")
+		if idx < 0 {
+			break
+		}
+		path = path[:idx]
+	}
+	return Provenance{}, false
+}
+
+// ObjectsDiscoveredBy returns the paths of the objects discovered by a discovery run, sorted.
+func (um *UnifiedModel) ObjectsDiscoveredBy(discoveryRunID string) []string {
+	var paths []string
+	for path, provenance := range um.Provenance {
+		if provenance.DiscoveryRunID == discoveryRunID {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
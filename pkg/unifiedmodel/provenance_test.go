@@ -0,0 +1,143 @@
+package unifiedmodel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func TestProvenance_NotPartOfContent(t *testing.T) {
+	a := &UnifiedModel{DatabaseType: dbcapabilities.PostgreSQL, Tables: map[string]Table{"users": {Name: "users"}}}
+	b := &UnifiedModel{DatabaseType: dbcapabilities.PostgreSQL, Tables: map[string]Table{"users": {Name: "users"}}}
+	if err := b.SetProvenance("tables.users", Provenance{DiscoveryRunID: "run-1", DiscoveredAt: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	same, err := SameContent(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !same {
+		t.Error("expected provenance to be left out of the content")
+	}
+}
+
+func TestProvenance_Inherited(t *testing.T) {
+	um := &UnifiedModel{}
+	discoveredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	if err := um.SetProvenance(ObjectPath(ObjectTypeTable, "users"), Provenance{DiscoveryRunID: "run-1", DiscoveredAt: discoveredAt}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := um.SetProvenance(TableObjectPath("users", ObjectTypeColumn, "email"), Provenance{DiscoveryRunID: "run-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		runID string
+		found bool
+	}{
+		{"tables.users", "run-1", true},
+		{"tables.users.columns.id", "run-1", true},
+		{"tables.users.columns.email", "run-2", true},
+		{"tables.users.columns.email.data_type", "run-2", true},
+		{"tables.orders", "", false},
+	}
+	for _, tt := range tests {
+		provenance, found := um.GetProvenance(tt.path)
+		if found != tt.found || provenance.DiscoveryRunID != tt.runID {
+			t.Errorf("%s: expected run %q (found %v), got %q (found %v)", tt.path, tt.runID, tt.found, provenance.DiscoveryRunID, found)
+		}
+	}
+
+	if provenance, _ := um.GetProvenance("tables.users"); provenance.DiscoveredAt.Location() != time.UTC || !provenance.DiscoveredAt.Equal(discoveredAt) {
+		t.Errorf("expected the discovery time in UTC, got %v", provenance.DiscoveredAt)
+	}
+	if paths := um.ObjectsDiscoveredBy("run-1"); !reflect.DeepEqual(paths, []string{"tables.users"}) {
+		t.Errorf("unexpected objects of run-1: %v", paths)
+	}
+
+	if err := um.SetProvenance("tables.users", Provenance{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := um.GetProvenance("tables.users.columns.id"); found {
+		t.Error("expected a zero provenance to remove the record")
+	}
+}
+
+func TestBuilder_RecordsProvenance(t *testing.T) {
+	discoveredAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	builder := NewBuilder(dbcapabilities.PostgreSQL)
+	builder.SetProvenance(Provenance{DiscoveryRunID: "run-1", DiscoveredAt: discoveredAt, AdapterVersion: "1.2.3"})
+
+	if err := builder.AddTableColumn("users", Column{Name: "id", DataType: "integer"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := builder.AddSequence(Sequence{Name: "users_id_seq"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := builder.RecordProvenance("tables.users", Provenance{SourceIdentifiers: map[string]string{"oid": "16384"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	um := builder.Freeze()
+	expected := Provenance{DiscoveryRunID: "run-1", DiscoveredAt: discoveredAt, AdapterVersion: "1.2.3", SourceIdentifiers: map[string]string{"oid": "16384"}}
+	if provenance, _ := um.GetProvenance("tables.users.columns.id"); !reflect.DeepEqual(provenance, expected) {
+		t.Errorf("expected %+v, got %+v", expected, provenance)
+	}
+	if paths := um.ObjectsDiscoveredBy("run-1"); !reflect.DeepEqual(paths, []string{"sequences.users_id_seq", "tables.users"}) {
+		t.Errorf("unexpected objects of run-1: %v", paths)
+	}
+
+	if err := builder.RecordProvenance("tables.users", Provenance{}); err != ErrBuilderFrozen {
+		t.Errorf("expected a frozen error, got %v", err)
+	}
+}
+
+func TestProvenance_ProtoRoundTrip(t *testing.T) {
+	um := &UnifiedModel{DatabaseType: dbcapabilities.PostgreSQL, Tables: map[string]Table{"users": {Name: "users"}}}
+	if err := um.SetProvenance("tables.users", Provenance{
+		DiscoveryRunID:    "run-1",
+		SourceIdentifiers: map[string]string{"oid": "16384"},
+		DiscoveredAt:      time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+		AdapterVersion:    "1.2.3",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pbUM, err := um.ConvertToProtoStrict()
+	if err != nil {
+		t.Fatalf("unexpected loss: %v", err)
+	}
+	if roundTrip := ConvertFromProto(pbUM); !reflect.DeepEqual(roundTrip.Provenance, um.Provenance) {
+		t.Errorf("expected %+v, got %+v", um.Provenance, roundTrip.Provenance)
+	}
+}
+
+func TestCompareSchemas_ReportsProvenance(t *testing.T) {
+	source := &UnifiedModel{DatabaseType: dbcapabilities.PostgreSQL, Tables: map[string]Table{"orders": {Name: "orders"}}}
+	target := &UnifiedModel{DatabaseType: dbcapabilities.PostgreSQL, Tables: map[string]Table{"users": {Name: "users"}}}
+	if err := source.SetProvenance("tables.orders", Provenance{DiscoveryRunID: "run-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := target.SetProvenance("tables.users", Provenance{DiscoveryRunID: "run-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := EnhancedCompareSchemas(source, target, DefaultEnhancedComparisonOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runs := make(map[string]string)
+	for _, change := range result.StructuralChanges {
+		if change.Provenance == nil {
+			t.Fatalf("expected provenance for %s", change.ObjectPath)
+		}
+		runs[change.ObjectPath] = change.Provenance.DiscoveryRunID
+	}
+	if expected := map[string]string{"tables.orders": "run-1", "tables.users": "run-2"}; !reflect.DeepEqual(runs, expected) {
+		t.Errorf("expected %v, got %v", expected, runs)
+	}
+}
@@ -25,6 +25,9 @@ import (
 type ObjectType string
 
 const (
+	// Structural organization
+	ObjectTypeSchema ObjectType = "schema"
+
 	// Data container types
 	ObjectTypeTable            ObjectType = "table"
 	ObjectTypeCollection       ObjectType = "collection"
@@ -163,6 +166,11 @@ const (
 )
 
 // UnifiedModel is a unified model for all database types
+//
+// The schema comparator of the unifiedmodel service compares all fields of the objects, and the
+// objects of their maps one by one. The compare tag of a field names it in the changes, or the
+// objects of its map (compare:"return type"), hides its values (compare:",opaque"), gives their
+// unit (compare:",unit=bytes") or leaves the field out (compare:"-").
 type UnifiedModel struct {
 	// Primary Data Containers
 	Tables           map[string]Table           `json:"tables"`             // Tabular Record Sets (Relational, Wide-Column)
@@ -213,7 +221,7 @@ type UnifiedModel struct {
 
 	// Data Organization Containers
 	Partitions    map[string]Partition    `json:"partitions"`
-	SubPartitions map[string]SubPartition `json:"sub_partitions"`
+	SubPartitions map[string]SubPartition `json:"sub_partitions" compare:"sub-partition"`
 	Shards        map[string]Shard        `json:"shards"`
 	Keyspaces     map[string]Keyspace     `json:"keyspaces"`
 	Namespaces    map[string]Namespace    `json:"namespaces"`
@@ -243,11 +251,11 @@ type UnifiedModel struct {
 	PackageBodies map[string]PackageBody  `json:"package_bodies"`
 	Macros        map[string]Macro        `json:"macros"`
 	Rules         map[string]Rule         `json:"rules"`
-	WindowFuncs   map[string]WindowFunc   `json:"window_functions"`
+	WindowFuncs   map[string]WindowFunc   `json:"window_functions" compare:"window function"`
 
 	// Security and access control
-	Users    map[string]DBUser `json:"users"`
-	Roles    map[string]DBRole `json:"roles"`
+	Users    map[string]DBUser `json:"users" compare:"user"`
+	Roles    map[string]DBRole `json:"roles" compare:"role"`
 	Grants   map[string]Grant  `json:"grants"`
 	Policies map[string]Policy `json:"policies"`
 
@@ -302,7 +310,7 @@ type UnifiedModel struct {
 	Backups        map[string]Backup        `json:"backups"`
 	Archives       map[string]Archive       `json:"archives"`
 	RecoveryPoints map[string]RecoveryPoint `json:"recovery_points"`
-	Versions       map[string]VersionNode   `json:"versions"`
+	Versions       map[string]VersionNode   `json:"versions" compare:"version"`
 	Migrations     map[string]Migration     `json:"migrations"`
 	Branches       map[string]Branch        `json:"branches"`
 	TimeTravel     map[string]TimeTravel    `json:"time_travel"`
@@ -312,13 +320,13 @@ type UnifiedModel struct {
 	Plugins          map[string]Plugin             `json:"plugins"`
 	ModuleExtensions map[string]ModuleExtension    `json:"module_extensions"`
 	TTLSettings      map[string]TTLSetting         `json:"ttl_settings"`
-	Dimensions       map[string]DimensionSpec      `json:"dimensions"`
-	DistanceMetrics  map[string]DistanceMetricSpec `json:"distance_metrics"`
+	Dimensions       map[string]DimensionSpec      `json:"dimensions" compare:"dimension"`
+	DistanceMetrics  map[string]DistanceMetricSpec `json:"distance_metrics" compare:"distance metric"`
 
 	// Advanced analytics
 	Projections     map[string]Projection         `json:"projections"`
-	AnalyticsAggs   map[string]AggregationOp      `json:"analytics_aggregations"`
-	Transformations map[string]TransformationStep `json:"transformations"`
+	AnalyticsAggs   map[string]AggregationOp      `json:"analytics_aggregations" compare:"analytics aggregation"`
+	Transformations map[string]TransformationStep `json:"transformations" compare:"transformation"`
 	Enrichments     map[string]Enrichment         `json:"enrichments"`
 	BufferPools     map[string]BufferPool         `json:"buffer_pools"`
 
@@ -327,6 +335,9 @@ type UnifiedModel struct {
 	Subscriptions    map[string]Subscription    `json:"subscriptions"`
 	ReplicationSlots map[string]ReplicationSlot `json:"replication_slots"`
 	FailoverGroups   map[string]FailoverGroup   `json:"failover_groups"`
+
+	// Provenance of objects, keyed by object path (see ObjectPath). Not part of the content.
+	Provenance map[string]Provenance `json:"provenance,omitempty" compare:"-"`
 }
 
 // GetBasicMetrics generates basic metrics (counts and simple calculations) from this UnifiedModel
@@ -439,7 +450,7 @@ type TransientTable struct {
 
 type View struct {
 	Name       string            `json:"name"`
-	Definition string            `json:"definition"`
+	Definition string            `json:"definition" compare:",opaque"`
 	Comment    string            `json:"comment,omitempty"`
 	Columns    map[string]Column `json:"columns,omitempty"`
 	Options    map[string]any    `json:"options,omitempty"`
@@ -447,20 +458,20 @@ type View struct {
 
 type LiveView struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type WindowView struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
-	WindowSpec string         `json:"window_spec,omitempty"`
+	Definition string         `json:"definition" compare:",opaque"`
+	WindowSpec string         `json:"window_spec,omitempty" compare:"window specification"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type MaterializedView struct {
 	Name        string            `json:"name"`
-	Definition  string            `json:"definition"`
+	Definition  string            `json:"definition" compare:",opaque"`
 	RefreshMode string            `json:"refresh_mode,omitempty"` // immediate, deferred, manual
 	RefreshCron string            `json:"refresh_cron,omitempty"`
 	Columns     map[string]Column `json:"columns,omitempty"`
@@ -494,7 +505,7 @@ type Graph struct {
 
 type VectorIndex struct {
 	Name       string         `json:"name"`
-	On         string         `json:"on"` // table/collection name
+	On         string         `json:"on" compare:"target"` // table/collection name
 	Fields     []string       `json:"fields"`
 	Metric     string         `json:"metric,omitempty"` // cosine, l2, ip
 	Dimension  int            `json:"dimension,omitempty"`
@@ -503,7 +514,7 @@ type VectorIndex struct {
 
 type SearchIndex struct {
 	Name     string         `json:"name"`
-	On       string         `json:"on"`
+	On       string         `json:"on" compare:"target"`
 	Fields   []string       `json:"fields,omitempty"`
 	Analyzer string         `json:"analyzer,omitempty"`
 	Options  map[string]any `json:"options,omitempty"`
@@ -573,15 +584,15 @@ type TimeSeriesPoint struct {
 }
 
 type Blob struct {
-	Name         string            `json:"name"`                    // Object key or blob identifier
-	Bucket       string            `json:"bucket"`                  // Bucket/container name
-	Path         string            `json:"path"`                    // Full path or key
-	Size         int64             `json:"size_bytes,omitempty"`    // Size in bytes
-	ContentType  string            `json:"content_type,omitempty"`  // MIME type
-	ETag         string            `json:"etag,omitempty"`          // Entity tag (hash/version)
-	Metadata     map[string]string `json:"metadata,omitempty"`      // Custom metadata
-	StorageClass string            `json:"storage_class,omitempty"` // Storage tier (e.g., STANDARD, GLACIER)
-	Encryption   string            `json:"encryption,omitempty"`    // Encryption status
+	Name         string            `json:"name"`                                       // Object key or blob identifier
+	Bucket       string            `json:"bucket"`                                     // Bucket/container name
+	Path         string            `json:"path"`                                       // Full path or key
+	Size         int64             `json:"size_bytes,omitempty" compare:",unit=bytes"` // Size in bytes
+	ContentType  string            `json:"content_type,omitempty"`                     // MIME type
+	ETag         string            `json:"etag,omitempty" compare:"ETag"`              // Entity tag (hash/version)
+	Metadata     map[string]string `json:"metadata,omitempty"`                         // Custom metadata
+	StorageClass string            `json:"storage_class,omitempty"`                    // Storage tier (e.g., STANDARD, GLACIER)
+	Encryption   string            `json:"encryption,omitempty"`                       // Encryption status
 	Labels       map[string]string `json:"labels,omitempty"`
 	Options      map[string]any    `json:"options,omitempty"`
 }
@@ -643,12 +654,12 @@ type Column struct {
 	Nullable            bool           `json:"nullable"`
 	Default             string         `json:"default,omitempty"`
 	GeneratedExpression string         `json:"generated_expression,omitempty"`
-	IsPrimaryKey        bool           `json:"is_primary_key,omitempty"`
-	IsPartitionKey      bool           `json:"is_partition_key,omitempty"`
-	IsClusteringKey     bool           `json:"is_clustering_key,omitempty"`
+	IsPrimaryKey        bool           `json:"is_primary_key,omitempty" compare:"primary key"`
+	IsPartitionKey      bool           `json:"is_partition_key,omitempty" compare:"partition key"`
+	IsClusteringKey     bool           `json:"is_clustering_key,omitempty" compare:"clustering key"`
 	AutoIncrement       bool           `json:"auto_increment,omitempty"`
 	Collation           string         `json:"collation,omitempty"`
-	OrdinalPosition     *int           `json:"ordinal_position,omitempty"`
+	OrdinalPosition     *int           `json:"ordinal_position,omitempty" compare:"-"`
 	Options             map[string]any `json:"options,omitempty"`
 }
 
@@ -693,7 +704,7 @@ type Constraint struct {
 	Name       string         `json:"name"`
 	Type       ConstraintType `json:"type"` // Use ConstraintType enum for type safety
 	Columns    []string       `json:"columns,omitempty"`
-	Expression string         `json:"expression,omitempty"`
+	Expression string         `json:"expression,omitempty" compare:",opaque"`
 	Reference  Reference      `json:"reference,omitempty"`
 	Options    map[string]any `json:"options,omitempty"`
 }
@@ -824,9 +835,9 @@ type UUIDGenerator struct {
 type Function struct {
 	Name       string         `json:"name"`
 	Language   string         `json:"language,omitempty"`
-	Returns    string         `json:"returns,omitempty"`
+	Returns    string         `json:"returns,omitempty" compare:"return type"`
 	Arguments  []Argument     `json:"arguments,omitempty"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
@@ -834,16 +845,16 @@ type Procedure struct {
 	Name       string         `json:"name"`
 	Language   string         `json:"language,omitempty"`
 	Arguments  []Argument     `json:"arguments,omitempty"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type Method struct {
 	Name       string         `json:"name"`
-	OfType     string         `json:"of_type,omitempty"` // object type
+	OfType     string         `json:"of_type,omitempty" compare:"object type"` // object type
 	Language   string         `json:"language,omitempty"`
 	Arguments  []Argument     `json:"arguments,omitempty"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
@@ -852,7 +863,7 @@ type Trigger struct {
 	Table     string         `json:"table,omitempty"`
 	Timing    string         `json:"timing"` // before, after, instead_of
 	Events    []string       `json:"events"` // insert, update, delete, truncate
-	Procedure string         `json:"procedure"`
+	Procedure string         `json:"procedure" compare:",opaque"`
 	Options   map[string]any `json:"options,omitempty"`
 }
 
@@ -876,20 +887,20 @@ type Operator struct {
 	Name       string `json:"name"`
 	LeftType   string `json:"left_type,omitempty"`
 	RightType  string `json:"right_type,omitempty"`
-	Returns    string `json:"returns,omitempty"`
-	Definition string `json:"definition,omitempty"`
+	Returns    string `json:"returns,omitempty" compare:"return type"`
+	Definition string `json:"definition,omitempty" compare:",opaque"`
 }
 
 type Package struct {
 	Name    string         `json:"name"`
-	Spec    string         `json:"spec,omitempty"`
-	Body    string         `json:"body,omitempty"`
+	Spec    string         `json:"spec,omitempty" compare:"specification,opaque"`
+	Body    string         `json:"body,omitempty" compare:",opaque"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
 type PackageBody struct {
 	Name    string         `json:"name"`
-	Body    string         `json:"body"`
+	Body    string         `json:"body" compare:"implementation,opaque"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -897,26 +908,26 @@ type Module struct {
 	Name     string         `json:"name"`
 	Comment  string         `json:"comment,omitempty"`
 	Language string         `json:"language,omitempty"`
-	Code     string         `json:"code,omitempty"`
+	Code     string         `json:"code,omitempty" compare:",opaque"`
 	Options  map[string]any `json:"options,omitempty"`
 }
 
 type Macro struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type Rule struct {
 	Name       string         `json:"name"`
 	Target     string         `json:"target"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type WindowFunc struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
@@ -954,7 +965,7 @@ type Policy struct {
 	Type       PolicyType     `json:"type"`  // Use PolicyType enum for type safety
 	Scope      string         `json:"scope"` // database, schema, table, column, function, etc.
 	Object     string         `json:"object,omitempty"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
@@ -973,7 +984,7 @@ type Filegroup struct {
 type Datafile struct {
 	Name    string         `json:"name"`
 	Path    string         `json:"path"`
-	Size    int64          `json:"size_bytes,omitempty"`
+	Size    int64          `json:"size_bytes,omitempty" compare:",unit=bytes"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -984,13 +995,13 @@ type Segment struct {
 
 type Extent struct {
 	Name    string         `json:"name"`
-	Size    int64          `json:"size_bytes,omitempty"`
+	Size    int64          `json:"size_bytes,omitempty" compare:",unit=bytes"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
 type Page struct {
 	Number  int            `json:"number"`
-	Size    int64          `json:"size_bytes,omitempty"`
+	Size    int64          `json:"size_bytes,omitempty" compare:",unit=bytes"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -1005,7 +1016,7 @@ type Server struct {
 type Connection struct {
 	Name    string         `json:"name"`
 	Driver  string         `json:"driver,omitempty"`
-	DSN     string         `json:"dsn,omitempty"`
+	DSN     string         `json:"dsn,omitempty" compare:",opaque"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -1052,7 +1063,7 @@ type Cluster struct {
 
 type Task struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition,omitempty"`
+	Definition string         `json:"definition,omitempty" compare:",opaque"`
 	Schedule   string         `json:"schedule,omitempty"`
 	Options    map[string]any `json:"options,omitempty"`
 }
@@ -1078,7 +1089,7 @@ type Pipeline struct {
 
 type Stream struct {
 	Name    string         `json:"name"`
-	On      string         `json:"on,omitempty"`
+	On      string         `json:"on,omitempty" compare:"source"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -1093,13 +1104,13 @@ type Event struct {
 type Notification struct {
 	Name    string         `json:"name"`
 	Channel string         `json:"channel,omitempty"`
-	Message string         `json:"message,omitempty"`
+	Message string         `json:"message,omitempty" compare:",opaque"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
 type Alert struct {
 	Name      string         `json:"name"`
-	Condition string         `json:"condition"`
+	Condition string         `json:"condition" compare:",opaque"`
 	Severity  string         `json:"severity,omitempty"`
 	Options   map[string]any `json:"options,omitempty"`
 }
@@ -1150,35 +1161,35 @@ type TextSearchComponent struct {
 // Metadata and documentation
 
 type Comment struct {
-	On      string `json:"on"` // qualified object name
-	Comment string `json:"comment"`
+	On      string `json:"on" compare:"target"` // qualified object name
+	Comment string `json:"comment" compare:"text,opaque"`
 }
 
 type Annotation struct {
-	On    string `json:"on"`
+	On    string `json:"on" compare:"target"`
 	Key   string `json:"key"`
 	Value any    `json:"value"`
 }
 
 type Tag struct {
-	On   string `json:"on"`
+	On   string `json:"on" compare:"target"`
 	Name string `json:"name"`
 }
 
 type Alias struct {
-	On    string `json:"on"`
-	Alias string `json:"alias"`
+	On    string `json:"on" compare:"target"`
+	Alias string `json:"alias" compare:"value"`
 }
 
 type Synonym struct {
-	On   string `json:"on"`
+	On   string `json:"on" compare:"target"`
 	Name string `json:"name"`
 }
 
 type Label struct {
-	On    string            `json:"on"`
+	On    string            `json:"on" compare:"target"`
 	Name  string            `json:"name"`
-	Props map[string]string `json:"props,omitempty"`
+	Props map[string]string `json:"props,omitempty" compare:"properties"`
 }
 
 type RelationshipType struct {
@@ -1208,29 +1219,29 @@ type Archive struct {
 
 type RecoveryPoint struct {
 	Name  string `json:"name"`
-	Point string `json:"point"` // LSN/GTID/TSO/timestamp
+	Point string `json:"point" compare:"location"` // LSN/GTID/TSO/timestamp
 }
 
 type VersionNode struct {
 	ID      string   `json:"id"`
 	Parents []string `json:"parents,omitempty"`
-	Message string   `json:"message,omitempty"`
+	Message string   `json:"message,omitempty" compare:",opaque"`
 }
 
 type Migration struct {
 	ID          string `json:"id"`
-	Description string `json:"description,omitempty"`
-	Script      string `json:"script,omitempty"`
+	Description string `json:"description,omitempty" compare:",opaque"`
+	Script      string `json:"script,omitempty" compare:",opaque"`
 }
 
 type Branch struct {
 	Name string `json:"name"`
-	From string `json:"from,omitempty"`
+	From string `json:"from,omitempty" compare:"source"`
 }
 
 type TimeTravel struct {
 	Object string `json:"object"`
-	AsOf   string `json:"as_of"` // timestamp or version id
+	AsOf   string `json:"as_of" compare:"timestamp"` // timestamp or version id
 }
 
 // Object storage
@@ -1265,31 +1276,31 @@ type DistanceMetricSpec struct {
 
 type Projection struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type AggregationOp struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type TransformationStep struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type Enrichment struct {
 	Name       string         `json:"name"`
-	Definition string         `json:"definition"`
+	Definition string         `json:"definition" compare:",opaque"`
 	Options    map[string]any `json:"options,omitempty"`
 }
 
 type BufferPool struct {
 	Name    string         `json:"name"`
-	Size    int64          `json:"size_bytes,omitempty"`
+	Size    int64          `json:"size_bytes,omitempty" compare:",unit=bytes"`
 	Options map[string]any `json:"options,omitempty"`
 }
 
@@ -0,0 +1,515 @@
+package unifiedmodel
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Consistency checks applied by Validate. Issues they report can't be configured away.
+const (
+	RuleStructure           = "structure"             // Checks of ValidateSchema
+	RuleForeignKeyReference = "foreign_key_reference" // Foreign keys reference existing tables and columns
+	RuleIndexColumns        = "index_columns"         // Index columns exist in their table
+	RuleConstraintColumns   = "constraint_columns"    // Constraint columns exist in their table
+	RuleUnknownType         = "unknown_type"          // Column types are known to the database type
+)
+
+// Lint rules applied by Validate unless disabled.
+const (
+	LintRuleNamingConvention    = "naming_convention"     // Object names follow the naming convention
+	LintRuleMissingPrimaryKey   = "missing_primary_key"   // Tables have a primary key
+	LintRuleUnindexedForeignKey = "unindexed_foreign_key" // Foreign key columns are covered by an index
+)
+
+// Naming conventions checked by the naming_convention lint rule.
+const (
+	NamingConventionSnakeCase      = "snake_case"
+	NamingConventionUpperSnakeCase = "upper_snake_case"
+	NamingConventionCamelCase      = "camel_case"
+	NamingConventionPascalCase     = "pascal_case"
+)
+
+// DefaultLintRules are the lint rules applied when ValidationOptions.LintRules is empty.
+var DefaultLintRules = []string{LintRuleNamingConvention, LintRuleMissingPrimaryKey, LintRuleUnindexedForeignKey}
+
+// lintRuleSeverities holds the default severity of each lint rule
+var lintRuleSeverities = map[string]ValidationErrorType{
+	LintRuleNamingConvention:    ValidationErrorWarning,
+	LintRuleMissingPrimaryKey:   ValidationErrorWarning,
+	LintRuleUnindexedForeignKey: ValidationErrorInfo,
+}
+
+var namingConventionPatterns = map[string]*regexp.Regexp{
+	NamingConventionSnakeCase:      regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	NamingConventionUpperSnakeCase: regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`),
+	NamingConventionCamelCase:      regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	NamingConventionPascalCase:     regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+}
+
+// validationTypes provides the type metadata used to recognize column types
+var validationTypes = sync.OnceValue(NewScalableTypeConverter)
+
+// ValidationOptions configures the lint rules applied by Validate.
+type ValidationOptions struct {
+	// DisableLint skips the lint rules, leaving only the consistency checks
+	DisableLint bool `json:"disable_lint,omitempty"`
+	// LintRules lists the lint rules to apply, DefaultLintRules when empty
+	LintRules []string `json:"lint_rules,omitempty"`
+	// NamingConvention is the convention checked by the naming rule, snake_case when empty
+	NamingConvention string `json:"naming_convention,omitempty"`
+	// Severities overrides the severity of lint rules by rule name
+	Severities map[string]ValidationErrorType `json:"severities,omitempty"`
+}
+
+// ValidationReport is the result of validating a schema.
+type ValidationReport struct {
+	Valid  bool              `json:"valid"` // No critical issues were found
+	Issues []ValidationError `json:"issues,omitempty"`
+}
+
+// Count returns the number of issues of the given severity.
+func (r *ValidationReport) Count(severity ValidationErrorType) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Type == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Critical returns the critical issues, which prevent deploying the schema.
+func (r *ValidationReport) Critical() []ValidationError {
+	var critical []ValidationError
+	for _, issue := range r.Issues {
+		if issue.Type == ValidationErrorCritical {
+			critical = append(critical, issue)
+		}
+	}
+	return critical
+}
+
+// Validate checks a schema for internal consistency and applies the configured lint rules.
+// On top of the checks of ValidateSchema it verifies that foreign keys reference existing
+// tables and columns, that index and constraint columns exist and that column types are known
+// to the database type of the schema. Types of databases without type metadata are not checked.
+//
+// Issues are sorted by field. The schema is valid when no critical issue was found; lint rules
+// report warnings and info by default. An error is returned for invalid options.
+func Validate(schema *UnifiedModel, options ValidationOptions) (*ValidationReport, error) {
+	lintRules, err := resolveLintRules(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []ValidationError
+	for _, issue := range ValidateSchema(schema) {
+		issue.Rule = RuleStructure
+		issues = append(issues, issue)
+	}
+
+	if schema != nil {
+		for tableName, table := range schema.Tables {
+			issues = append(issues, validateTableReferences(tableName, table, schema)...)
+			issues = append(issues, validateColumnTypes(tableName, table, schema)...)
+		}
+
+		for rule, severity := range lintRules {
+			for _, issue := range lintSchema(rule, schema, options) {
+				issue.Type = severity
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Field != issues[j].Field {
+			return issues[i].Field < issues[j].Field
+		}
+		if issues[i].Rule != issues[j].Rule {
+			return issues[i].Rule < issues[j].Rule
+		}
+		return issues[i].Message < issues[j].Message
+	})
+
+	report := &ValidationReport{Issues: issues}
+	report.Valid = report.Count(ValidationErrorCritical) == 0
+	return report, nil
+}
+
+// resolveLintRules returns the severity of each lint rule to apply
+func resolveLintRules(options ValidationOptions) (map[string]ValidationErrorType, error) {
+	if options.NamingConvention != "" {
+		if _, ok := namingConventionPatterns[options.NamingConvention]; !ok {
+			return nil, fmt.Errorf("unknown naming convention: %s", options.NamingConvention)
+		}
+	}
+	for rule, severity := range options.Severities {
+		if _, ok := lintRuleSeverities[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule: %s", rule)
+		}
+		switch severity {
+		case ValidationErrorCritical, ValidationErrorWarning, ValidationErrorInfo:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %s", severity, rule)
+		}
+	}
+
+	rules := make(map[string]ValidationErrorType)
+	if options.DisableLint {
+		return rules, nil
+	}
+
+	names := options.LintRules
+	if len(names) == 0 {
+		names = DefaultLintRules
+	}
+	for _, rule := range names {
+		severity, ok := lintRuleSeverities[rule]
+		if !ok {
+			return nil, fmt.Errorf("unknown lint rule: %s", rule)
+		}
+		if override, ok := options.Severities[rule]; ok {
+			severity = override
+		}
+		rules[rule] = severity
+	}
+	return rules, nil
+}
+
+// validateTableReferences checks that the indexes and constraints of a table reference existing
+// columns and that its foreign keys reference existing tables and columns
+func validateTableReferences(tableName string, table Table, schema *UnifiedModel) []ValidationError {
+	var errors []ValidationError
+
+	for indexName, index := range table.Indexes {
+		if index.Expression != "" {
+			continue
+		}
+		for _, col := range index.Columns {
+			col = indexColumnName(col)
+			if col == "" {
+				continue
+			}
+			if _, ok := table.Columns[col]; !ok {
+				errors = append(errors, ValidationError{
+					Type:       ValidationErrorCritical,
+					Rule:       RuleIndexColumns,
+					Field:      fmt.Sprintf("tables.%s.indexes.%s", tableName, indexName),
+					Message:    fmt.Sprintf("index references unknown column: %s", col),
+					Suggestion: "add the column to the table or remove it from the index",
+				})
+			}
+		}
+	}
+
+	for constraintName, constraint := range table.Constraints {
+		field := fmt.Sprintf("tables.%s.constraints.%s", tableName, constraintName)
+		for _, col := range constraint.Columns {
+			if _, ok := table.Columns[col]; !ok {
+				errors = append(errors, ValidationError{
+					Type:       ValidationErrorCritical,
+					Rule:       RuleConstraintColumns,
+					Field:      field,
+					Message:    fmt.Sprintf("constraint references unknown column: %s", col),
+					Suggestion: "add the column to the table or remove it from the constraint",
+				})
+			}
+		}
+
+		if constraint.Type == ConstraintTypeForeignKey {
+			errors = append(errors, validateForeignKey(field, constraint, schema)...)
+		}
+	}
+
+	return errors
+}
+
+// validateForeignKey checks that a foreign key references an existing table and columns
+func validateForeignKey(field string, constraint Constraint, schema *UnifiedModel) []ValidationError {
+	ref := constraint.Reference
+	if ref.Table == "" {
+		return []ValidationError{{
+			Type:       ValidationErrorCritical,
+			Rule:       RuleForeignKeyReference,
+			Field:      field,
+			Message:    "foreign key does not reference a table",
+			Suggestion: "set the referenced table of the foreign key",
+		}}
+	}
+
+	target, ok := findReferencedTable(schema, ref.Table)
+	if !ok {
+		return []ValidationError{{
+			Type:       ValidationErrorCritical,
+			Rule:       RuleForeignKeyReference,
+			Field:      field,
+			Message:    fmt.Sprintf("foreign key references unknown table: %s", ref.Table),
+			Suggestion: "add the referenced table to the schema or remove the foreign key",
+		}}
+	}
+
+	var errors []ValidationError
+	if len(ref.Columns) != len(constraint.Columns) {
+		errors = append(errors, ValidationError{
+			Type:       ValidationErrorCritical,
+			Rule:       RuleForeignKeyReference,
+			Field:      field,
+			Message:    fmt.Sprintf("foreign key has %d columns but references %d", len(constraint.Columns), len(ref.Columns)),
+			Suggestion: "reference one column for each column of the foreign key",
+		})
+	}
+	for _, col := range ref.Columns {
+		if _, ok := target.Columns[col]; !ok {
+			errors = append(errors, ValidationError{
+				Type:       ValidationErrorCritical,
+				Rule:       RuleForeignKeyReference,
+				Field:      field,
+				Message:    fmt.Sprintf("foreign key references unknown column %s of table %s", col, ref.Table),
+				Suggestion: "reference existing columns of the referenced table",
+			})
+		}
+	}
+	return errors
+}
+
+// findReferencedTable finds a table by key, by name or, for schema qualified references, by
+// unqualified name
+func findReferencedTable(schema *UnifiedModel, name string) (Table, bool) {
+	if table, ok := schema.Tables[name]; ok {
+		return table, true
+	}
+	unqualified := name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		unqualified = name[i+1:]
+	}
+	for key, table := range schema.Tables {
+		if table.Name == name || key == unqualified || table.Name == unqualified {
+			return table, true
+		}
+	}
+	return Table{}, false
+}
+
+// indexColumnName returns the column of an index entry without sort order, or an empty string
+// for expressions
+func indexColumnName(col string) string {
+	col = strings.TrimSpace(col)
+	if strings.Contains(col, "(") {
+		return ""
+	}
+	if fields := strings.Fields(col); len(fields) == 2 {
+		switch strings.ToUpper(fields[1]) {
+		case "ASC", "DESC":
+			return fields[0]
+		}
+	}
+	return col
+}
+
+// validateColumnTypes checks that the column types of a table are known to the database type
+// of the schema
+func validateColumnTypes(tableName string, table Table, schema *UnifiedModel) []ValidationError {
+	converter := validationTypes()
+	metadata, ok := converter.metadata[schema.DatabaseType]
+	if !ok {
+		return nil
+	}
+
+	var errors []ValidationError
+	for colName, col := range table.Columns {
+		field := fmt.Sprintf("tables.%s.columns.%s", tableName, colName)
+		if strings.TrimSpace(col.DataType) == "" {
+			errors = append(errors, ValidationError{
+				Type:       ValidationErrorWarning,
+				Rule:       RuleUnknownType,
+				Field:      field,
+				Message:    "column has no data type",
+				Suggestion: "set the data type of the column",
+			})
+			continue
+		}
+
+		typeName := converter.normalizeTypeName(baseTypeName(col.DataType))
+		if _, found := converter.findTypeInfo(typeName, metadata); found || isCustomType(schema, typeName) {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Type:       ValidationErrorWarning,
+			Rule:       RuleUnknownType,
+			Field:      field,
+			Message:    fmt.Sprintf("type %s is not recognized for %s", col.DataType, schema.DatabaseType),
+			Suggestion: "use a type supported by the database or define it as a custom type",
+		})
+	}
+	return errors
+}
+
+// baseTypeName strips length, precision and array modifiers from a type name
+func baseTypeName(dataType string) string {
+	typeName := strings.TrimSpace(dataType)
+	if open := strings.Index(typeName, "("); open >= 0 {
+		if end := strings.LastIndex(typeName, ")"); end > open {
+			typeName = typeName[:open] + typeName[end+1:]
+		} else {
+			typeName = typeName[:open]
+		}
+	}
+	for strings.HasSuffix(typeName, "[]") {
+		typeName = strings.TrimSuffix(typeName, "[]")
+	}
+	return strings.Join(strings.Fields(typeName), " ")
+}
+
+// isCustomType reports whether a type is defined in the schema
+func isCustomType(schema *UnifiedModel, typeName string) bool {
+	for key, t := range schema.Types {
+		if strings.EqualFold(key, typeName) || strings.EqualFold(t.Name, typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintSchema applies a lint rule to a schema
+func lintSchema(rule string, schema *UnifiedModel, options ValidationOptions) []ValidationError {
+	switch rule {
+	case LintRuleNamingConvention:
+		return lintNamingConvention(schema, options.NamingConvention)
+	case LintRuleMissingPrimaryKey:
+		return lintMissingPrimaryKeys(schema)
+	case LintRuleUnindexedForeignKey:
+		return lintUnindexedForeignKeys(schema)
+	}
+	return nil
+}
+
+// lintNamingConvention reports tables, columns, views and collections whose names do not follow
+// the naming convention
+func lintNamingConvention(schema *UnifiedModel, convention string) []ValidationError {
+	if convention == "" {
+		convention = NamingConventionSnakeCase
+	}
+	pattern := namingConventionPatterns[convention]
+
+	var errors []ValidationError
+	check := func(kind, field, name string) {
+		if name == "" || pattern.MatchString(name) {
+			return
+		}
+		errors = append(errors, ValidationError{
+			Rule:       LintRuleNamingConvention,
+			Field:      field,
+			Message:    fmt.Sprintf("%s name %s does not follow the %s naming convention", kind, name, convention),
+			Suggestion: fmt.Sprintf("rename the %s to follow the %s naming convention", kind, convention),
+		})
+	}
+
+	for tableName, table := range schema.Tables {
+		check("table", fmt.Sprintf("tables.%s", tableName), table.Name)
+		for colName, col := range table.Columns {
+			check("column", fmt.Sprintf("tables.%s.columns.%s", tableName, colName), col.Name)
+		}
+	}
+	for viewName, view := range schema.Views {
+		check("view", fmt.Sprintf("views.%s", viewName), view.Name)
+	}
+	for collectionName, collection := range schema.Collections {
+		check("collection", fmt.Sprintf("collections.%s", collectionName), collection.Name)
+	}
+	return errors
+}
+
+// lintMissingPrimaryKeys reports tables without a primary key
+func lintMissingPrimaryKeys(schema *UnifiedModel) []ValidationError {
+	var errors []ValidationError
+	for tableName, table := range schema.Tables {
+		if len(primaryKeyColumns(table)) > 0 {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Rule:       LintRuleMissingPrimaryKey,
+			Field:      fmt.Sprintf("tables.%s", tableName),
+			Message:    "table has no primary key",
+			Suggestion: "add a primary key so rows can be identified for replication and updates",
+		})
+	}
+	return errors
+}
+
+// primaryKeyColumns returns the primary key columns of a table from its constraints or columns
+func primaryKeyColumns(table Table) []string {
+	for _, constraint := range table.Constraints {
+		if constraint.Type == ConstraintTypePrimaryKey && len(constraint.Columns) > 0 {
+			return constraint.Columns
+		}
+	}
+	var columns []string
+	for name, col := range table.Columns {
+		if col.IsPrimaryKey {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+// lintUnindexedForeignKeys reports foreign keys whose columns are not the leading columns of an
+// index, primary key or unique constraint of their table
+func lintUnindexedForeignKeys(schema *UnifiedModel) []ValidationError {
+	var errors []ValidationError
+	for tableName, table := range schema.Tables {
+		var covering [][]string
+		for _, index := range table.Indexes {
+			covering = append(covering, index.Columns)
+		}
+		for _, constraint := range table.Constraints {
+			if constraint.Type == ConstraintTypePrimaryKey || constraint.Type == ConstraintTypeUnique {
+				covering = append(covering, constraint.Columns)
+			}
+		}
+
+		for constraintName, constraint := range table.Constraints {
+			if constraint.Type != ConstraintTypeForeignKey || len(constraint.Columns) == 0 {
+				continue
+			}
+			if coveredByIndex(constraint.Columns, covering) {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				Rule:       LintRuleUnindexedForeignKey,
+				Field:      fmt.Sprintf("tables.%s.constraints.%s", tableName, constraintName),
+				Message:    fmt.Sprintf("foreign key columns %s are not indexed", strings.Join(constraint.Columns, ", ")),
+				Suggestion: "add an index on the foreign key columns to speed up joins and deletes on the referenced table",
+			})
+		}
+	}
+	return errors
+}
+
+// coveredByIndex reports whether the columns are the leading columns of one of the indexes,
+// in any order
+func coveredByIndex(columns []string, indexes [][]string) bool {
+	for _, index := range indexes {
+		if len(index) < len(columns) {
+			continue
+		}
+		leading := make(map[string]bool, len(columns))
+		for _, col := range index[:len(columns)] {
+			leading[indexColumnName(col)] = true
+		}
+		covered := true
+		for _, col := range columns {
+			if !leading[col] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
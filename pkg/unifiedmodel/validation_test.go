@@ -0,0 +1,204 @@
+package unifiedmodel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+func validationTestSchema() *UnifiedModel {
+	return &UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]Column{
+					"id":    {Name: "id", DataType: "integer", IsPrimaryKey: true},
+					"email": {Name: "email", DataType: "varchar(255)"},
+				},
+				Indexes: map[string]Index{
+					"users_email_idx": {Name: "users_email_idx", Columns: []string{"email DESC"}},
+				},
+			},
+			"orders": {
+				Name: "orders",
+				Columns: map[string]Column{
+					"id":      {Name: "id", DataType: "bigint"},
+					"user_id": {Name: "user_id", DataType: "integer"},
+				},
+				Constraints: map[string]Constraint{
+					"orders_pkey": {Name: "orders_pkey", Type: ConstraintTypePrimaryKey, Columns: []string{"id"}},
+					"orders_user_id_fkey": {
+						Name:      "orders_user_id_fkey",
+						Type:      ConstraintTypeForeignKey,
+						Columns:   []string{"user_id"},
+						Reference: Reference{Table: "public.users", Columns: []string{"id"}},
+					},
+				},
+				Indexes: map[string]Index{
+					"orders_user_id_idx": {Name: "orders_user_id_idx", Columns: []string{"user_id"}},
+				},
+			},
+		},
+	}
+}
+
+func issuesByRule(report *ValidationReport, rule string) []ValidationError {
+	var issues []ValidationError
+	for _, issue := range report.Issues {
+		if issue.Rule == rule {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func TestValidate_ValidSchema(t *testing.T) {
+	report, err := Validate(validationTestSchema(), ValidationOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Valid || len(report.Issues) != 0 {
+		t.Errorf("expected no issues, got %+v", report.Issues)
+	}
+}
+
+func TestValidate_References(t *testing.T) {
+	schema := validationTestSchema()
+	orders := schema.Tables["orders"]
+	orders.Constraints["orders_user_id_fkey"] = Constraint{
+		Name:      "orders_user_id_fkey",
+		Type:      ConstraintTypeForeignKey,
+		Columns:   []string{"user_id"},
+		Reference: Reference{Table: "users", Columns: []string{"uid"}},
+	}
+	orders.Constraints["orders_customer_fkey"] = Constraint{
+		Name:      "orders_customer_fkey",
+		Type:      ConstraintTypeForeignKey,
+		Columns:   []string{"customer_id"},
+		Reference: Reference{Table: "customers", Columns: []string{"id"}},
+	}
+	orders.Indexes["orders_total_idx"] = Index{Name: "orders_total_idx", Columns: []string{"total"}}
+	orders.Indexes["orders_lower_idx"] = Index{Name: "orders_lower_idx", Columns: []string{"lower(status)"}}
+
+	report, err := Validate(schema, ValidationOptions{DisableLint: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Valid {
+		t.Fatal("expected schema to be invalid")
+	}
+
+	fk := issuesByRule(report, RuleForeignKeyReference)
+	if len(fk) != 2 {
+		t.Fatalf("expected 2 foreign key issues, got %+v", fk)
+	}
+	for _, issue := range fk {
+		if issue.Type != ValidationErrorCritical {
+			t.Errorf("expected critical foreign key issue, got %s", issue.Type)
+		}
+	}
+	if !strings.Contains(fk[0].Message, "customers") || !strings.Contains(fk[1].Message, "uid") {
+		t.Errorf("unexpected foreign key issues: %+v", fk)
+	}
+
+	if cols := issuesByRule(report, RuleConstraintColumns); len(cols) != 1 || cols[0].Field != "tables.orders.constraints.orders_customer_fkey" {
+		t.Errorf("expected one constraint column issue, got %+v", cols)
+	}
+	if idx := issuesByRule(report, RuleIndexColumns); len(idx) != 1 || idx[0].Field != "tables.orders.indexes.orders_total_idx" {
+		t.Errorf("expected one index column issue, got %+v", idx)
+	}
+}
+
+func TestValidate_UnknownType(t *testing.T) {
+	schema := validationTestSchema()
+	users := schema.Tables["users"]
+	users.Columns["status"] = Column{Name: "status", DataType: "user_status"}
+	users.Columns["tags"] = Column{Name: "tags", DataType: "text[]"}
+	users.Columns["score"] = Column{Name: "score", DataType: "fancyint"}
+	schema.Types = map[string]Type{"user_status": {Name: "user_status", Category: "enum"}}
+
+	report, err := Validate(schema, ValidationOptions{DisableLint: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issues := issuesByRule(report, RuleUnknownType)
+	if len(issues) != 1 || issues[0].Field != "tables.users.columns.score" || issues[0].Type != ValidationErrorWarning {
+		t.Errorf("expected one unknown type warning, got %+v", issues)
+	}
+	if !report.Valid {
+		t.Error("unknown types should not make the schema invalid")
+	}
+
+	// Databases without type metadata are not checked
+	schema.DatabaseType = dbcapabilities.JDBCBridge
+	report, err = Validate(schema, ValidationOptions{DisableLint: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issues := issuesByRule(report, RuleUnknownType); len(issues) != 0 {
+		t.Errorf("expected no type issues, got %+v", issues)
+	}
+}
+
+func TestValidate_LintRules(t *testing.T) {
+	schema := validationTestSchema()
+	schema.Tables["AuditLog"] = Table{
+		Name: "AuditLog",
+		Columns: map[string]Column{
+			"createdAt": {Name: "createdAt", DataType: "text"},
+		},
+	}
+	orders := schema.Tables["orders"]
+	delete(orders.Indexes, "orders_user_id_idx")
+
+	report, err := Validate(schema, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Valid {
+		t.Errorf("lint issues should not make the schema invalid: %+v", report.Critical())
+	}
+	if naming := issuesByRule(report, LintRuleNamingConvention); len(naming) != 2 {
+		t.Errorf("expected 2 naming issues, got %+v", naming)
+	}
+	if pk := issuesByRule(report, LintRuleMissingPrimaryKey); len(pk) != 1 || pk[0].Field != "tables.AuditLog" {
+		t.Errorf("expected missing primary key on AuditLog, got %+v", pk)
+	}
+	if fk := issuesByRule(report, LintRuleUnindexedForeignKey); len(fk) != 1 || fk[0].Type != ValidationErrorInfo {
+		t.Errorf("expected one unindexed foreign key info, got %+v", fk)
+	}
+
+	report, err = Validate(schema, ValidationOptions{
+		LintRules:        []string{LintRuleMissingPrimaryKey, LintRuleNamingConvention},
+		NamingConvention: NamingConventionPascalCase,
+		Severities:       map[string]ValidationErrorType{LintRuleMissingPrimaryKey: ValidationErrorCritical},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Valid || report.Count(ValidationErrorCritical) != 1 {
+		t.Errorf("expected the missing primary key to be critical, got %+v", report.Issues)
+	}
+	if fk := issuesByRule(report, LintRuleUnindexedForeignKey); len(fk) != 0 {
+		t.Errorf("expected unindexed foreign key rule to be disabled, got %+v", fk)
+	}
+	// users, email, id, orders, id, user_id, createdAt
+	if naming := issuesByRule(report, LintRuleNamingConvention); len(naming) != 7 {
+		t.Errorf("expected 7 pascal case issues, got %d", len(naming))
+	}
+}
+
+func TestValidate_InvalidOptions(t *testing.T) {
+	for name, options := range map[string]ValidationOptions{
+		"unknown rule":       {LintRules: []string{"no_such_rule"}},
+		"unknown convention": {NamingConvention: "kebab-case"},
+		"invalid severity":   {Severities: map[string]ValidationErrorType{LintRuleMissingPrimaryKey: "fatal"}},
+	} {
+		if _, err := Validate(validationTestSchema(), options); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	// Import community database adapters to trigger their init() registration
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/airtable"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/apachepinot"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/azureblob"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/bigquery"
@@ -18,9 +19,13 @@ import (
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/dynamodb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/edgedb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/elasticsearch"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/filesource"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/gcs"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/googlesheets"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/hubspot"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/iceberg"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/influxdb"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/jdbcbridge"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/mariadb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/milvus"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/minio"
@@ -35,10 +40,18 @@ import (
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/redis"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/redshift"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/s3"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/salesforce"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/snowflake"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/solr"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/sqlite"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/synapse"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/tidb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/timescaledb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/weaviate"
+
+	// Stream platform adapters
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/kafka"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/kinesis"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/natsjetstream"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/pubsub"
 )
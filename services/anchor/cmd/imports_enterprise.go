@@ -7,6 +7,7 @@ import (
 	// Import all database adapters (community + enterprise) to trigger their init() registration
 
 	// Community database adapters
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/airtable"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/apachepinot"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/azureblob"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/bigquery"
@@ -20,9 +21,13 @@ import (
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/dynamodb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/edgedb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/elasticsearch"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/filesource"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/gcs"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/googlesheets"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/hubspot"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/iceberg"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/influxdb"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/jdbcbridge"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/mariadb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/milvus"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/minio"
@@ -37,8 +42,10 @@ import (
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/redis"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/redshift"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/s3"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/salesforce"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/snowflake"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/solr"
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/sqlite"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/synapse"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/tidb"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/timescaledb"
@@ -48,4 +55,10 @@ import (
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/db2"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/hana"
 	_ "github.com/redbco/redb-open/services/anchor/internal/database/oracle"
+
+	// Stream platform adapters
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/kafka"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/kinesis"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/natsjetstream"
+	_ "github.com/redbco/redb-open/services/anchor/internal/stream/pubsub"
 )
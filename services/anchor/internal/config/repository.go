@@ -8,6 +8,8 @@ import (
 
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	"github.com/redbco/redb-open/pkg/database"
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
 	"github.com/redbco/redb-open/pkg/syslog"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 	"google.golang.org/grpc"
@@ -65,6 +67,9 @@ func (r *Repository) GetAllDatabaseConfigs(ctx context.Context, nodeID string) (
 			d.database_password,
 			d.database_db_name,
 			d.database_enabled,
+			d.database_replicas,
+			d.database_routing_policy,
+			d.database_rate_limits,
 			d.policy_ids,
 			d.owner_id,
 			d.database_status_message,
@@ -77,7 +82,11 @@ func (r *Repository) GetAllDatabaseConfigs(ctx context.Context, nodeID string) (
 			i.instance_ssl_cert,
 			i.instance_ssl_key,
 			i.instance_ssl_root_cert,
-			i.instance_ssl
+			i.instance_ssl,
+			i.instance_ssh_tunnel,
+			i.instance_iam_auth,
+			i.instance_socket_path,
+			i.instance_kerberos_auth
 		FROM databases d
 		LEFT JOIN instances i ON d.instance_id = i.instance_id
 		WHERE d.connected_to_node_id = $1 AND d.database_enabled = true
@@ -110,6 +119,9 @@ func (r *Repository) GetAllDatabaseConfigs(ctx context.Context, nodeID string) (
 			&config.Password,
 			&config.DatabaseName,
 			&config.Enabled,
+			&config.Replicas, // pgx decodes JSONB into the slice
+			&config.RoutingPolicy,
+			&config.RateLimits,
 			&policyIDs, // pgx handles PostgreSQL arrays automatically
 			&config.OwnerID,
 			&config.StatusMessage,
@@ -123,6 +135,10 @@ func (r *Repository) GetAllDatabaseConfigs(ctx context.Context, nodeID string) (
 			&config.SSLKey,
 			&config.SSLRootCert,
 			&config.SSL,
+			&config.SSHTunnel, // pgx decodes JSONB, NULL without a tunnel
+			&config.IAMAuth,
+			&config.SocketPath,
+			&config.KerberosAuth,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning database row: %w", err)
@@ -174,6 +190,10 @@ func (r *Repository) GetAllInstanceConfigs(ctx context.Context, nodeID string) (
 			instance_ssl_cert,
 			instance_ssl_key,
 			instance_ssl_root_cert,
+			instance_ssh_tunnel,
+			instance_iam_auth,
+			instance_socket_path,
+			instance_kerberos_auth,
 			policy_ids,
 			owner_id,
 			instance_status_message,
@@ -218,6 +238,10 @@ func (r *Repository) GetAllInstanceConfigs(ctx context.Context, nodeID string) (
 			&config.SSLCert,
 			&config.SSLKey,
 			&config.SSLRootCert,
+			&config.SSHTunnel,
+			&config.IAMAuth,
+			&config.SocketPath,
+			&config.KerberosAuth,
 			&policyIDs, // pgx handles PostgreSQL arrays automatically
 			&config.OwnerID,
 			&config.StatusMessage,
@@ -404,6 +428,9 @@ func (r *Repository) GetDatabaseConfigByID(ctx context.Context, databaseID strin
 			COALESCE(NULLIF(d.database_password, ''), i.instance_password) as password,
 			d.database_db_name,
 			d.database_enabled,
+			d.database_replicas,
+			d.database_routing_policy,
+			d.database_rate_limits,
 			d.policy_ids,
 			d.owner_id,
 			d.database_status_message,
@@ -416,7 +443,11 @@ func (r *Repository) GetDatabaseConfigByID(ctx context.Context, databaseID strin
 			i.instance_ssl_cert,
 			i.instance_ssl_key,
 			i.instance_ssl_root_cert,
-			i.instance_ssl
+			i.instance_ssl,
+			i.instance_ssh_tunnel,
+			i.instance_iam_auth,
+			i.instance_socket_path,
+			i.instance_kerberos_auth
 		FROM databases d
 		LEFT JOIN instances i ON d.instance_id = i.instance_id
 		WHERE d.database_id = $1
@@ -451,6 +482,9 @@ func (r *Repository) GetDatabaseConfigByID(ctx context.Context, databaseID strin
 		&config.Password,
 		&config.DatabaseName,
 		&config.Enabled,
+		&config.Replicas, // pgx decodes JSONB into the slice
+		&config.RoutingPolicy,
+		&config.RateLimits,
 		&policyIDs, // pgx handles PostgreSQL arrays automatically
 		&config.OwnerID,
 		&config.StatusMessage,
@@ -464,6 +498,10 @@ func (r *Repository) GetDatabaseConfigByID(ctx context.Context, databaseID strin
 		&config.SSLKey,
 		&config.SSLRootCert,
 		&config.SSL,
+		&config.SSHTunnel, // pgx decodes JSONB, NULL without a tunnel
+		&config.IAMAuth,
+		&config.SocketPath,
+		&config.KerberosAuth,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error scanning database configuration: %w", err)
@@ -508,6 +546,10 @@ func (r *Repository) GetInstanceConfigByID(ctx context.Context, instanceID strin
 			instance_ssl_cert,
 			instance_ssl_key,
 			instance_ssl_root_cert,
+			instance_ssh_tunnel,
+			instance_iam_auth,
+			instance_socket_path,
+			instance_kerberos_auth,
 			policy_ids,
 			owner_id,
 			instance_status_message,
@@ -554,6 +596,10 @@ func (r *Repository) GetInstanceConfigByID(ctx context.Context, instanceID strin
 		&config.SSLCert,
 		&config.SSLKey,
 		&config.SSLRootCert,
+		&config.SSHTunnel,
+		&config.IAMAuth,
+		&config.SocketPath,
+		&config.KerberosAuth,
 		&policyIDs, // pgx handles PostgreSQL arrays automatically
 		&config.OwnerID,
 		&config.StatusMessage,
@@ -581,6 +627,9 @@ type LatestSchemaResponse struct {
 	CommitExists            bool
 	DatabaseConnectedToRepo bool
 	Schema                  interface{}
+	// SnapshotHash is the SHA-256 of the schema when core keeps it in the blob store, in which
+	// case Schema only holds the reference to the snapshot
+	SnapshotHash string
 }
 
 // GetLatestStoredDatabaseSchema retrieves the latest stored database schema for a database
@@ -671,12 +720,19 @@ func (r *Repository) GetLatestStoredDatabaseSchema(ctx context.Context, database
 		}, fmt.Errorf("error scanning latest stored database schema: %w", err)
 	}
 
-	syslog.Info("anchor", "Successfully retrieved latest stored database schema for database %s", databaseID)
-	return &LatestSchemaResponse{
+	response := &LatestSchemaResponse{
 		CommitExists:            true,
 		DatabaseConnectedToRepo: true,
 		Schema:                  schema,
-	}, nil
+	}
+	if reference, ok := schema.(map[string]interface{}); ok {
+		if _, offloaded := reference["$artifact"]; offloaded {
+			response.SnapshotHash, _ = reference["$sha256"].(string)
+		}
+	}
+
+	syslog.Info("anchor", "Successfully retrieved latest stored database schema for database %s", databaseID)
+	return response, nil
 }
 
 // ReplicationSource represents a replication source in the database
@@ -959,3 +1015,356 @@ func (r *Repository) UpdateRelationshipStatus(ctx context.Context, relationshipI
 	syslog.Info("anchor", "Successfully updated relationship status for %s", relationshipID)
 	return nil
 }
+
+// QueryProxy is the stored configuration of a query proxy run by the anchor
+type QueryProxy struct {
+	ProxyID          string            `json:"proxy_id"`
+	TenantID         string            `json:"tenant_id"`
+	WorkspaceID      string            `json:"workspace_id"`
+	ProxyName        string            `json:"proxy_name"`
+	RelationshipID   string            `json:"relationship_id"`
+	SourceDatabaseID string            `json:"source_database_id"`
+	TargetDatabaseID string            `json:"target_database_id"`
+	Protocol         string            `json:"proxy_protocol"`
+	ListenPort       int               `json:"listen_port"`
+	Username         string            `json:"proxy_username"`
+	Password         string            `json:"proxy_password"` // Encrypted
+	ReadPercent      int               `json:"read_percent"`
+	WritePercent     int               `json:"write_percent"`
+	TableRoutes      map[string]string `json:"table_routes"`
+	Status           string            `json:"status"`
+}
+
+const queryProxyColumns = `
+			p.proxy_id,
+			p.tenant_id,
+			p.workspace_id,
+			p.proxy_name,
+			p.relationship_id,
+			r.relationship_source_database_id,
+			r.relationship_target_database_id,
+			p.proxy_protocol,
+			p.listen_port,
+			p.proxy_username,
+			p.proxy_password,
+			p.read_percent,
+			p.write_percent,
+			p.table_routes,
+			p.status
+		FROM query_proxies p
+		JOIN relationships r ON r.relationship_id = p.relationship_id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanQueryProxy(row rowScanner) (*QueryProxy, error) {
+	var proxy QueryProxy
+	var tableRoutes []byte
+	err := row.Scan(
+		&proxy.ProxyID,
+		&proxy.TenantID,
+		&proxy.WorkspaceID,
+		&proxy.ProxyName,
+		&proxy.RelationshipID,
+		&proxy.SourceDatabaseID,
+		&proxy.TargetDatabaseID,
+		&proxy.Protocol,
+		&proxy.ListenPort,
+		&proxy.Username,
+		&proxy.Password,
+		&proxy.ReadPercent,
+		&proxy.WritePercent,
+		&tableRoutes,
+		&proxy.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(tableRoutes) > 0 {
+		if err := json.Unmarshal(tableRoutes, &proxy.TableRoutes); err != nil {
+			return nil, fmt.Errorf("invalid table routes of query proxy %s: %w", proxy.ProxyID, err)
+		}
+	}
+	return &proxy, nil
+}
+
+// GetQueryProxy retrieves a query proxy by ID
+func (r *Repository) GetQueryProxy(ctx context.Context, proxyID string) (*QueryProxy, error) {
+	syslog.Info("anchor", "Getting query proxy by ID %s", proxyID)
+
+	query := `
+		SELECT` + queryProxyColumns + `
+		WHERE p.proxy_id = $1
+	`
+
+	proxy, err := scanQueryProxy(r.db.Pool().QueryRow(ctx, query, proxyID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting query proxy: %w", err)
+	}
+	return proxy, nil
+}
+
+// GetStartedQueryProxies retrieves the query proxies of a node that should be running
+func (r *Repository) GetStartedQueryProxies(ctx context.Context, nodeID string) ([]*QueryProxy, error) {
+	syslog.Info("anchor", "Getting started query proxies for node %s", nodeID)
+
+	query := `
+		SELECT` + queryProxyColumns + `
+		WHERE p.node_id = $1 AND p.status = 'STATUS_STARTED'
+		ORDER BY p.created
+	`
+
+	rows, err := r.db.Pool().Query(ctx, query, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying query proxies: %w", err)
+	}
+	defer rows.Close()
+
+	var proxies []*QueryProxy
+	for rows.Next() {
+		proxy, err := scanQueryProxy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning query proxy: %w", err)
+		}
+		proxies = append(proxies, proxy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating query proxies: %w", err)
+	}
+
+	syslog.Info("anchor", "Found %d started query proxies for node %s", len(proxies), nodeID)
+	return proxies, nil
+}
+
+// UpdateQueryProxyStatus updates the status of a query proxy
+func (r *Repository) UpdateQueryProxyStatus(ctx context.Context, proxyID string, status string, statusMessage string) error {
+	query := `
+		UPDATE query_proxies 
+		SET 
+			status = $1,
+			status_message = $2,
+			updated = CURRENT_TIMESTAMP
+		WHERE proxy_id = $3
+	`
+
+	result, err := r.db.Pool().Exec(ctx, query, status, statusMessage, proxyID)
+	if err != nil {
+		return fmt.Errorf("error updating query proxy status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("query proxy with ID %s not found", proxyID)
+	}
+	return nil
+}
+
+// StreamConfig is the stored configuration of a stream platform attached to the anchor
+type StreamConfig struct {
+	StreamID         string                 `json:"stream_id"`
+	TenantID         string                 `json:"tenant_id"`
+	Name             string                 `json:"stream_name"`
+	Platform         string                 `json:"stream_platform"`
+	ConnectionConfig map[string]interface{} `json:"connection_config"`
+	MonitoredTopics  []string               `json:"monitored_topics"`
+	Status           string                 `json:"status"`
+}
+
+// ToConnectionConfig converts the stored configuration to the configuration of a stream adapter
+func (c *StreamConfig) ToConnectionConfig() *streamadapter.ConnectionConfig {
+	return streamadapter.ConnectionConfigFromMap(c.StreamID, streamcapabilities.StreamPlatform(c.Platform), c.ConnectionConfig)
+}
+
+const streamColumns = `
+			stream_id,
+			tenant_id,
+			stream_name,
+			stream_platform,
+			connection_config,
+			monitored_topics,
+			status
+		FROM streams`
+
+func scanStreamConfig(row rowScanner) (*StreamConfig, error) {
+	var stream StreamConfig
+	var connectionConfig, monitoredTopics []byte
+	err := row.Scan(
+		&stream.StreamID,
+		&stream.TenantID,
+		&stream.Name,
+		&stream.Platform,
+		&connectionConfig,
+		&monitoredTopics,
+		&stream.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(connectionConfig) > 0 {
+		if err := json.Unmarshal(connectionConfig, &stream.ConnectionConfig); err != nil {
+			return nil, fmt.Errorf("invalid connection config of stream %s: %w", stream.StreamID, err)
+		}
+	}
+	if len(monitoredTopics) > 0 {
+		if err := json.Unmarshal(monitoredTopics, &stream.MonitoredTopics); err != nil {
+			return nil, fmt.Errorf("invalid monitored topics of stream %s: %w", stream.StreamID, err)
+		}
+	}
+	return &stream, nil
+}
+
+// GetStreamConfigByID retrieves the configuration of a stream by ID
+func (r *Repository) GetStreamConfigByID(ctx context.Context, streamID string) (*StreamConfig, error) {
+	syslog.Info("anchor", "Getting stream config by ID %s", streamID)
+
+	query := `
+		SELECT` + streamColumns + `
+		WHERE stream_id = $1
+	`
+
+	stream, err := scanStreamConfig(r.db.Pool().QueryRow(ctx, query, streamID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting stream config: %w", err)
+	}
+	return stream, nil
+}
+
+// GetConnectedStreamConfigs retrieves the streams of a node that were connected when the
+// anchor stopped
+func (r *Repository) GetConnectedStreamConfigs(ctx context.Context, nodeID string) ([]*StreamConfig, error) {
+	syslog.Info("anchor", "Getting connected streams for node %s", nodeID)
+
+	query := `
+		SELECT` + streamColumns + `
+		WHERE connected_to_node_id = $1 AND status = 'STATUS_CONNECTED'
+		ORDER BY created
+	`
+
+	rows, err := r.db.Pool().Query(ctx, query, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying streams: %w", err)
+	}
+	defer rows.Close()
+
+	var streams []*StreamConfig
+	for rows.Next() {
+		stream, err := scanStreamConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning stream: %w", err)
+		}
+		streams = append(streams, stream)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating streams: %w", err)
+	}
+
+	syslog.Info("anchor", "Found %d connected streams for node %s", len(streams), nodeID)
+	return streams, nil
+}
+
+// UpdateStreamConnectionStatus updates the connection status of a stream
+func (r *Repository) UpdateStreamConnectionStatus(ctx context.Context, streamID string, connected bool) error {
+	status := "STATUS_DISCONNECTED"
+	if connected {
+		status = "STATUS_CONNECTED"
+	}
+
+	query := `
+		UPDATE streams 
+		SET 
+			status = $1,
+			updated = CURRENT_TIMESTAMP
+		WHERE stream_id = $2
+	`
+
+	result, err := r.db.Pool().Exec(ctx, query, status, streamID)
+	if err != nil {
+		return fmt.Errorf("error updating stream connection status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("stream with ID %s not found", streamID)
+	}
+	return nil
+}
+
+// UpdateStreamMetadata merges metadata into the metadata of a stream
+func (r *Repository) UpdateStreamMetadata(ctx context.Context, streamID string, metadata map[string]interface{}) error {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("error marshaling stream metadata to JSON: %w", err)
+	}
+
+	query := `
+		UPDATE streams 
+		SET 
+			stream_metadata = stream_metadata || $1::jsonb,
+			updated = CURRENT_TIMESTAMP
+		WHERE stream_id = $2
+	`
+
+	result, err := r.db.Pool().Exec(ctx, query, metadataJSON, streamID)
+	if err != nil {
+		return fmt.Errorf("error updating stream metadata: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("stream with ID %s not found", streamID)
+	}
+	return nil
+}
+
+// LoadStreamCheckpoints returns the checkpointed position per shard of a consumer on a topic
+// of a stream
+func (r *Repository) LoadStreamCheckpoints(ctx context.Context, streamID, consumerID, topic string) (map[string]string, error) {
+	query := `
+		SELECT shard_id, position
+		FROM stream_checkpoints
+		WHERE stream_id = $1 AND consumer_id = $2 AND topic_name = $3
+	`
+
+	rows, err := r.db.Pool().Query(ctx, query, streamID, consumerID, topic)
+	if err != nil {
+		return nil, fmt.Errorf("error querying stream checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make(map[string]string)
+	for rows.Next() {
+		var shardID, position string
+		if err := rows.Scan(&shardID, &position); err != nil {
+			return nil, fmt.Errorf("error scanning stream checkpoint: %w", err)
+		}
+		positions[shardID] = position
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stream checkpoints: %w", err)
+	}
+	return positions, nil
+}
+
+// SaveStreamCheckpoints records the positions per shard of a consumer on a topic of a stream
+// in one statement, so that either all of them or none are saved
+func (r *Repository) SaveStreamCheckpoints(ctx context.Context, streamID, consumerID, topic string, positions map[string]string) error {
+	if len(positions) == 0 {
+		return nil
+	}
+	shardIDs := make([]string, 0, len(positions))
+	values := make([]string, 0, len(positions))
+	for shardID, position := range positions {
+		shardIDs = append(shardIDs, shardID)
+		values = append(values, position)
+	}
+
+	query := `
+		INSERT INTO stream_checkpoints (stream_id, consumer_id, topic_name, shard_id, position)
+		SELECT $1, $2, $3, c.shard_id, c.position
+		FROM unnest($4::text[], $5::text[]) AS c(shard_id, position)
+		ON CONFLICT (stream_id, consumer_id, topic_name, shard_id) DO UPDATE
+		SET position = EXCLUDED.position, updated = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.Pool().Exec(ctx, query, streamID, consumerID, topic, shardIDs, values); err != nil {
+		return fmt.Errorf("error saving stream checkpoints: %w", err)
+	}
+	return nil
+}
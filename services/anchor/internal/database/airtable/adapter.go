@@ -0,0 +1,221 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// Adapter implements adapter.DatabaseAdapter for Airtable.
+// The base ID is taken from DatabaseName and every Airtable table is exposed as a table.
+//
+// The token is read from Token (or Password) and may be a personal access token or an OAuth
+// access token obtained through the integration service. OAuth tokens are refreshed
+// automatically when options.refresh_token, options.client_id and options.client_secret are set.
+type Adapter struct{}
+
+// NewAdapter creates a new Airtable adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.Airtable
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.Airtable)
+}
+
+// Connect verifies access to the base.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	if config.DatabaseName == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Airtable, "databaseName", "base ID is required")
+	}
+
+	accessToken := config.Token
+	if accessToken == "" {
+		accessToken = config.Password
+	}
+	refreshToken := optionString(config, "refresh_token")
+	if accessToken == "" && refreshToken == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Airtable, "token", "a personal access token or OAuth token is required")
+	}
+
+	tokenURL := optionString(config, "token_url")
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	tokens := restclient.NewTokenSource(accessToken, refreshToken,
+		optionString(config, "client_id"), optionString(config, "client_secret"), tokenURL)
+
+	baseURL := defaultBaseURL
+	if config.Host != "" && config.Host != "api.airtable.com" {
+		baseURL = strings.TrimRight(config.Host, "/")
+	}
+
+	client := &Client{
+		api:    restclient.NewClient(baseURL, tokens, optionFloat(config, "requests_per_second", defaultRequestsPerSecond)),
+		baseID: config.DatabaseName,
+	}
+
+	if _, err := client.tables(ctx); err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.Airtable, config.Host, config.Port, err)
+	}
+
+	return &Connection{
+		id:        config.DatabaseID,
+		client:    client,
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance is not supported; each base is connected as a database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Airtable, "instance connection", "bases are connected as individual databases")
+}
+
+func optionString(config adapter.ConnectionConfig, key string) string {
+	if v, ok := config.Options[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func optionFloat(config adapter.ConnectionConfig, key string, def float64) float64 {
+	switch v := config.Options[key].(type) {
+	case float64:
+		if v > 0 {
+			return v
+		}
+	case int:
+		if v > 0 {
+			return float64(v)
+		}
+	}
+	return def
+}
+
+// Connection implements adapter.Connection for an Airtable base.
+type Connection struct {
+	id        string
+	client    *Client
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+
+	// cursorsMu guards cursors, the Airtable page cursors keyed by table and stream offset
+	cursorsMu sync.Mutex
+	cursors   map[string]string
+}
+
+// streamCursor returns the page cursor that resumes a stream at offset
+func (c *Connection) streamCursor(table string, offset int64) (string, bool) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	cursor, ok := c.cursors[fmt.Sprintf("%s:%d", table, offset)]
+	return cursor, ok
+}
+
+// setStreamCursor remembers the page cursor that resumes a stream at offset
+func (c *Connection) setStreamCursor(table string, offset int64, cursor string) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	if c.cursors == nil {
+		c.cursors = make(map[string]string)
+	}
+	c.cursors[fmt.Sprintf("%s:%d", table, offset)] = cursor
+}
+
+// table returns the definition of a table by name
+func (c *Connection) table(ctx context.Context, name string) (*tableSchema, error) {
+	tables, err := c.client.tables(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Airtable, "list_tables", err)
+	}
+	for i := range tables {
+		if tables[i].Name == name || tables[i].ID == name {
+			return &tables[i], nil
+		}
+	}
+	return nil, adapter.NewNotFoundError(dbcapabilities.Airtable, "table", name)
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.Airtable
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the base is still accessible.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if _, err := c.client.tables(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.Airtable, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return nil
+}
+
+// SchemaOperations returns the schema operator.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.Airtable)
+}
+
+// MetadataOperations returns the metadata operator.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the Airtable API client.
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
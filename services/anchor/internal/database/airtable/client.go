@@ -0,0 +1,159 @@
+package airtable
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+const (
+	defaultBaseURL  = "https://api.airtable.com/v0"
+	defaultTokenURL = "https://airtable.com/oauth2/v1/token"
+
+	// Airtable allows 5 requests per second per base
+	defaultRequestsPerSecond = 5
+	// Airtable caps page size and write batches
+	pageSize       = 100
+	writeBatchSize = 10
+)
+
+// field is an Airtable field definition
+type field struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description,omitempty"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+}
+
+// tableSchema is an Airtable table definition from the metadata API
+type tableSchema struct {
+	ID             string  `json:"id,omitempty"`
+	Name           string  `json:"name"`
+	Description    string  `json:"description,omitempty"`
+	PrimaryFieldID string  `json:"primaryFieldId,omitempty"`
+	Fields         []field `json:"fields"`
+}
+
+type record struct {
+	ID          string                 `json:"id,omitempty"`
+	CreatedTime string                 `json:"createdTime,omitempty"`
+	Fields      map[string]interface{} `json:"fields"`
+}
+
+type recordPage struct {
+	Records []record `json:"records"`
+	Offset  string   `json:"offset"`
+}
+
+// listOptions narrows a record listing
+type listOptions struct {
+	Fields  []string
+	Formula string
+	Offset  string
+	Limit   int
+}
+
+// Client wraps the Airtable REST API for a single base
+type Client struct {
+	api    *restclient.Client
+	baseID string
+}
+
+// tables returns the table definitions of the base
+func (c *Client) tables(ctx context.Context) ([]tableSchema, error) {
+	var result struct {
+		Tables []tableSchema `json:"tables"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/meta/bases/"+url.PathEscape(c.baseID)+"/tables", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Tables, nil
+}
+
+// createTable creates a table in the base
+func (c *Client) createTable(ctx context.Context, table tableSchema) error {
+	return c.api.Do(ctx, http.MethodPost, "/meta/bases/"+url.PathEscape(c.baseID)+"/tables", nil, table, nil)
+}
+
+// listRecords returns one page of records
+func (c *Client) listRecords(ctx context.Context, table string, opts listOptions) (*recordPage, error) {
+	query := url.Values{}
+	size := pageSize
+	if opts.Limit > 0 && opts.Limit < size {
+		size = opts.Limit
+	}
+	query.Set("pageSize", strconv.Itoa(size))
+	for _, f := range opts.Fields {
+		query.Add("fields[]", f)
+	}
+	if opts.Formula != "" {
+		query.Set("filterByFormula", opts.Formula)
+	}
+	if opts.Offset != "" {
+		query.Set("offset", opts.Offset)
+	}
+
+	var page recordPage
+	if err := c.api.Do(ctx, http.MethodGet, c.tablePath(table), query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// createRecords creates up to writeBatchSize records
+func (c *Client) createRecords(ctx context.Context, table string, records []record) error {
+	body := map[string]interface{}{"records": records, "typecast": true}
+	return c.api.Do(ctx, http.MethodPost, c.tablePath(table), nil, body, nil)
+}
+
+// upsertRecords updates records matching mergeOn, creating those that do not match.
+// It returns the number of records that were created and updated.
+func (c *Client) upsertRecords(ctx context.Context, table string, records []record, mergeOn []string) (int, int, error) {
+	body := map[string]interface{}{
+		"records":       records,
+		"typecast":      true,
+		"performUpsert": map[string]interface{}{"fieldsToMergeOn": mergeOn},
+	}
+	var result struct {
+		CreatedRecords []string `json:"createdRecords"`
+		UpdatedRecords []string `json:"updatedRecords"`
+	}
+	if err := c.api.Do(ctx, http.MethodPatch, c.tablePath(table), nil, body, &result); err != nil {
+		return 0, 0, err
+	}
+	return len(result.CreatedRecords), len(result.UpdatedRecords), nil
+}
+
+// updateRecords updates up to writeBatchSize records by ID
+func (c *Client) updateRecords(ctx context.Context, table string, records []record) error {
+	body := map[string]interface{}{"records": records, "typecast": true}
+	return c.api.Do(ctx, http.MethodPatch, c.tablePath(table), nil, body, nil)
+}
+
+// deleteRecords deletes up to writeBatchSize records by ID
+func (c *Client) deleteRecords(ctx context.Context, table string, ids []string) error {
+	query := url.Values{}
+	for _, id := range ids {
+		query.Add("records[]", id)
+	}
+	return c.api.Do(ctx, http.MethodDelete, c.tablePath(table), query, nil, nil)
+}
+
+// whoami returns the ID of the user the token belongs to
+func (c *Client) whoami(ctx context.Context) (string, error) {
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/meta/whoami", nil, nil, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+func (c *Client) tablePath(table string) string {
+	return "/" + url.PathEscape(c.baseID) + "/" + url.PathEscape(table)
+}
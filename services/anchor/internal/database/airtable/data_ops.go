@@ -0,0 +1,361 @@
+package airtable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// recordIDField is the pseudo-column carrying the Airtable record ID in fetched rows
+const recordIDField = "_record_id"
+
+// DataOps implements data operations for Airtable.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves records from a table.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves records with specific fields, following pagination until limit is reached.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	opts := listOptions{Fields: columns}
+
+	for {
+		if limit > 0 {
+			opts.Limit = limit - len(rows)
+		}
+		page, err := d.conn.client.listRecords(ctx, table, opts)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Airtable, "fetch", err)
+		}
+		for _, r := range page.Records {
+			rows = append(rows, recordRow(r))
+		}
+		if page.Offset == "" || (limit > 0 && len(rows) >= limit) {
+			break
+		}
+		opts.Offset = page.Offset
+	}
+
+	if limit > 0 && len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// Stream returns a batch of records starting at the given offset. Airtable pages with opaque
+// cursors, so the cursor that ended each batch is remembered on the connection and the next
+// batch resumes from it; unknown offsets are reached by paging from the start.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	batchSize := int(params.BatchSize)
+	if batchSize <= 0 || batchSize > pageSize {
+		batchSize = pageSize
+	}
+
+	opts := listOptions{Fields: params.Columns, Limit: batchSize}
+	skip := params.Offset
+	if cursor, ok := d.conn.streamCursor(params.Table, params.Offset); ok {
+		opts.Offset = cursor
+		skip = 0
+	}
+
+	var page *recordPage
+	for {
+		var err error
+		page, err = d.conn.client.listRecords(ctx, params.Table, opts)
+		if err != nil {
+			return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.Airtable, "stream", err)
+		}
+		if skip == 0 {
+			break
+		}
+		if skip < int64(len(page.Records)) {
+			page.Records = page.Records[skip:]
+			skip = 0
+			break
+		}
+		skip -= int64(len(page.Records))
+		if page.Offset == "" {
+			page.Records = nil
+			break
+		}
+		opts.Offset = page.Offset
+	}
+
+	rows := make([]map[string]interface{}, 0, len(page.Records))
+	for _, r := range page.Records {
+		rows = append(rows, recordRow(r))
+	}
+
+	next := params.Offset + int64(len(rows))
+	if page.Offset != "" {
+		d.conn.setStreamCursor(params.Table, next, page.Offset)
+	}
+
+	return adapter.StreamResult{
+		Data:       rows,
+		HasMore:    page.Offset != "",
+		NextCursor: strconv.FormatInt(next, 10),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// Insert creates records in batches of writeBatchSize.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	var inserted int64
+	for start := 0; start < len(data); start += writeBatchSize {
+		batch := toRecords(data[start:min(start+writeBatchSize, len(data))])
+		if err := d.conn.client.createRecords(ctx, table, batch); err != nil {
+			return inserted, adapter.WrapError(dbcapabilities.Airtable, "insert", err)
+		}
+		inserted += int64(len(batch))
+	}
+	return inserted, nil
+}
+
+// Update updates the records matching the where columns of each row.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	if len(whereColumns) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.Airtable, "whereColumns", "at least one where column is required")
+	}
+
+	var pending []record
+	for _, row := range data {
+		conditions := make(map[string]interface{}, len(whereColumns))
+		for _, col := range whereColumns {
+			conditions[col] = row[col]
+		}
+		ids, err := d.matchingIDs(ctx, table, conditions)
+		if err != nil {
+			return 0, err
+		}
+		fields := toRecords([]map[string]interface{}{row})[0].Fields
+		for _, id := range ids {
+			pending = append(pending, record{ID: id, Fields: fields})
+		}
+	}
+
+	var updated int64
+	for start := 0; start < len(pending); start += writeBatchSize {
+		batch := pending[start:min(start+writeBatchSize, len(pending))]
+		if err := d.conn.client.updateRecords(ctx, table, batch); err != nil {
+			return updated, adapter.WrapError(dbcapabilities.Airtable, "update", err)
+		}
+		updated += int64(len(batch))
+	}
+	return updated, nil
+}
+
+// Upsert merges records on the unique columns using Airtable's performUpsert.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(uniqueColumns) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.Airtable, "uniqueColumns", "at least one unique column is required")
+	}
+
+	var affected int64
+	for start := 0; start < len(data); start += writeBatchSize {
+		batch := toRecords(data[start:min(start+writeBatchSize, len(data))])
+		created, updated, err := d.conn.client.upsertRecords(ctx, table, batch, uniqueColumns)
+		if err != nil {
+			return affected, adapter.WrapError(dbcapabilities.Airtable, "upsert", err)
+		}
+		affected += int64(created + updated)
+	}
+	return affected, nil
+}
+
+// Delete deletes the records matching the conditions.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	ids, err := d.matchingIDs(ctx, table, conditions)
+	if err != nil {
+		return 0, err
+	}
+	return d.deleteIDs(ctx, table, ids)
+}
+
+// ExecuteQuery is not supported because Airtable has no query language.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Airtable, "execute query", "airtable has no query language")
+}
+
+// ExecuteCountQuery is not supported because Airtable has no query language.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Airtable, "execute count query", "airtable has no query language")
+}
+
+// GetRowCount counts the records of a table by paging through them. The where clause,
+// if given, is passed to Airtable as a filterByFormula expression.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	var count int64
+	opts := listOptions{Formula: whereClause, Fields: []string{}}
+	for {
+		page, err := d.conn.client.listRecords(ctx, table, opts)
+		if err != nil {
+			return 0, false, adapter.WrapError(dbcapabilities.Airtable, "row_count", err)
+		}
+		count += int64(len(page.Records))
+		if page.Offset == "" {
+			return count, true, nil
+		}
+		opts.Offset = page.Offset
+	}
+}
+
+// Wipe deletes all records from every table in the base.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	tables, err := d.conn.client.tables(ctx)
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.Airtable, "wipe", err)
+	}
+	for _, t := range tables {
+		ids, err := d.matchingIDs(ctx, t.ID, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := d.deleteIDs(ctx, t.ID, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchingIDs returns the IDs of all records matching the conditions
+func (d *DataOps) matchingIDs(ctx context.Context, table string, conditions map[string]interface{}) ([]string, error) {
+	var ids []string
+	opts := listOptions{Formula: buildFormula(conditions), Fields: []string{}}
+	for {
+		page, err := d.conn.client.listRecords(ctx, table, opts)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Airtable, "list_records", err)
+		}
+		for _, r := range page.Records {
+			ids = append(ids, r.ID)
+		}
+		if page.Offset == "" {
+			return ids, nil
+		}
+		opts.Offset = page.Offset
+	}
+}
+
+// deleteIDs deletes records in batches of writeBatchSize
+func (d *DataOps) deleteIDs(ctx context.Context, table string, ids []string) (int64, error) {
+	var deleted int64
+	for start := 0; start < len(ids); start += writeBatchSize {
+		batch := ids[start:min(start+writeBatchSize, len(ids))]
+		if err := d.conn.client.deleteRecords(ctx, table, batch); err != nil {
+			return deleted, adapter.WrapError(dbcapabilities.Airtable, "delete", err)
+		}
+		deleted += int64(len(batch))
+	}
+	return deleted, nil
+}
+
+// recordRow flattens a record into a row, keeping the record ID as a pseudo-column
+func recordRow(r record) map[string]interface{} {
+	row := make(map[string]interface{}, len(r.Fields)+1)
+	for k, v := range r.Fields {
+		row[k] = v
+	}
+	row[recordIDField] = r.ID
+	return row
+}
+
+// toRecords converts rows into records, dropping the record ID pseudo-column
+func toRecords(rows []map[string]interface{}) []record {
+	records := make([]record, 0, len(rows))
+	for _, row := range rows {
+		fields := make(map[string]interface{}, len(row))
+		for k, v := range row {
+			if k == recordIDField {
+				continue
+			}
+			fields[k] = fieldValue(v)
+		}
+		records = append(records, record{Fields: fields})
+	}
+	return records
+}
+
+// fieldValue converts a Go value into a value accepted by the Airtable API
+func fieldValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339)
+	default:
+		return val
+	}
+}
+
+// buildFormula builds a filterByFormula expression matching all conditions
+func buildFormula(conditions map[string]interface{}) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	terms := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == recordIDField {
+			terms = append(terms, "RECORD_ID()="+formulaLiteral(conditions[k]))
+			continue
+		}
+		ref := "{" + strings.ReplaceAll(k, "}", "\\}") + "}"
+		if conditions[k] == nil {
+			terms = append(terms, ref+"=BLANK()")
+			continue
+		}
+		terms = append(terms, ref+"="+formulaLiteral(conditions[k]))
+	}
+
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "AND(" + strings.Join(terms, ",") + ")"
+}
+
+// formulaLiteral renders a value as an Airtable formula literal
+func formulaLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return "TRUE()"
+		}
+		return "FALSE()"
+	case int:
+		return strconv.Itoa(val)
+	case int32:
+		return strconv.FormatInt(int64(val), 10)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return "DATETIME_PARSE(" + strconv.Quote(val.UTC().Format(time.RFC3339)) + ")"
+	default:
+		return strconv.Quote(fmt.Sprint(val))
+	}
+}
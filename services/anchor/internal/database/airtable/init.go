@@ -0,0 +1,8 @@
+package airtable
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register Airtable adapter with the global registry
+	adapter.Register(NewAdapter())
+}
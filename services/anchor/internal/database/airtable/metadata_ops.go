@@ -0,0 +1,80 @@
+package airtable
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations for Airtable.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the base.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	tables, err := m.conn.client.tables(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Airtable, "collect_database_metadata", err)
+	}
+
+	tableInfo := make([]map[string]interface{}, 0, len(tables))
+	for _, t := range tables {
+		tableInfo = append(tableInfo, map[string]interface{}{
+			"table_id":    t.ID,
+			"name":        t.Name,
+			"field_count": len(t.Fields),
+		})
+	}
+
+	return map[string]interface{}{
+		"database_type":     string(dbcapabilities.Airtable),
+		"base_id":           m.conn.client.baseID,
+		"table_count":       len(tables),
+		"tables":            tableInfo,
+		"unique_identifier": m.conn.client.baseID,
+	}, nil
+}
+
+// CollectInstanceMetadata returns metadata about the account the token belongs to.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	userID, err := m.conn.client.whoami(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Airtable, "collect_instance_metadata", err)
+	}
+	return map[string]interface{}{
+		"database_type": string(dbcapabilities.Airtable),
+		"user_id":       userID,
+		"version":       "v0",
+	}, nil
+}
+
+// GetVersion returns the Airtable API version.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return "v0", nil
+}
+
+// GetUniqueIdentifier returns the base ID.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return m.conn.client.baseID, nil
+}
+
+// GetDatabaseSize is not reported by the Airtable API.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Airtable, "get database size", "not reported by the airtable api")
+}
+
+// GetTableCount returns the number of tables in the base.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	tables, err := m.conn.client.tables(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Airtable, "get_table_count", err)
+	}
+	return len(tables), nil
+}
+
+// ExecuteCommand is not supported for Airtable.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Airtable, "execute command", "airtable does not accept commands")
+}
@@ -0,0 +1,230 @@
+package airtable
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// computedFieldTypes are field types whose values Airtable calculates and rejects on write
+var computedFieldTypes = map[string]bool{
+	"formula":              true,
+	"rollup":               true,
+	"count":                true,
+	"lookup":               true,
+	"multipleLookupValues": true,
+	"autoNumber":           true,
+	"createdTime":          true,
+	"lastModifiedTime":     true,
+	"createdBy":            true,
+	"lastModifiedBy":       true,
+	"button":               true,
+}
+
+// SchemaOps implements schema operations for Airtable.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema reads the table definitions of the base.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	tables, err := s.conn.client.tables(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Airtable, "discover_schema", err)
+	}
+
+	result := make(map[string]unifiedmodel.Table, len(tables))
+	for _, t := range tables {
+		result[t.Name] = convertTable(t)
+	}
+
+	return &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.Airtable,
+		Tables:       result,
+	}, nil
+}
+
+// CreateStructure creates every table of the model that does not exist in the base yet.
+// Existing tables are left untouched.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if model == nil {
+		return adapter.NewConfigurationError(dbcapabilities.Airtable, "model", "unified model cannot be nil")
+	}
+
+	tables, err := s.conn.client.tables(ctx)
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.Airtable, "create_structure", err)
+	}
+	existing := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		existing[t.Name] = true
+	}
+
+	for name, table := range model.Tables {
+		if existing[name] {
+			continue
+		}
+
+		def := tableSchema{Name: name}
+		for _, colName := range orderedColumns(table) {
+			col := table.Columns[colName]
+			def.Fields = append(def.Fields, fieldForColumn(col))
+		}
+		if len(def.Fields) == 0 {
+			// Airtable requires at least one field, which becomes the primary field
+			def.Fields = []field{{Name: "Name", Type: "singleLineText"}}
+		}
+
+		if err := s.conn.client.createTable(ctx, def); err != nil {
+			return adapter.WrapError(dbcapabilities.Airtable, "create_structure", err)
+		}
+	}
+	return nil
+}
+
+// ListTables returns the names of the tables in the base.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	tables, err := s.conn.client.tables(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Airtable, "list_tables", err)
+	}
+
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetTableSchema returns the schema of a single table.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	t, err := s.conn.table(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	table := convertTable(*t)
+	return &table, nil
+}
+
+// convertTable converts an Airtable table definition to a unified model table
+func convertTable(t tableSchema) unifiedmodel.Table {
+	table := unifiedmodel.Table{
+		Name:    t.Name,
+		Comment: t.Description,
+		Columns: make(map[string]unifiedmodel.Column, len(t.Fields)),
+		Options: map[string]any{"table_id": t.ID},
+	}
+
+	for i, f := range t.Fields {
+		position := i + 1
+		options := map[string]any{
+			"field_id":      f.ID,
+			"airtable_type": f.Type,
+		}
+		if f.ID == t.PrimaryFieldID {
+			options["primary_field"] = true
+		}
+		if computedFieldTypes[f.Type] {
+			options["computed"] = true
+		}
+		if choices, ok := f.Options["choices"].([]interface{}); ok {
+			names := make([]string, 0, len(choices))
+			for _, c := range choices {
+				if m, ok := c.(map[string]interface{}); ok {
+					if name, ok := m["name"].(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+			options["choices"] = names
+		}
+
+		table.Columns[f.Name] = unifiedmodel.Column{
+			Name:            f.Name,
+			DataType:        unifiedType(f),
+			Nullable:        true,
+			OrdinalPosition: &position,
+			Options:         options,
+		}
+	}
+
+	return table
+}
+
+// unifiedType maps an Airtable field type to a unified data type
+func unifiedType(f field) string {
+	switch f.Type {
+	case "number", "percent", "currency", "rating", "duration", "count":
+		if precision, ok := f.Options["precision"].(float64); ok && precision > 0 {
+			return "decimal"
+		}
+		if f.Type == "percent" || f.Type == "currency" {
+			return "decimal"
+		}
+		return "integer"
+	case "autoNumber":
+		return "integer"
+	case "checkbox":
+		return "boolean"
+	case "date":
+		return "date"
+	case "dateTime", "createdTime", "lastModifiedTime":
+		return "timestamp"
+	case "multipleSelects", "multipleRecordLinks", "multipleAttachments", "multipleCollaborators",
+		"multipleLookupValues", "lookup", "rollup", "singleCollaborator", "createdBy", "lastModifiedBy", "barcode", "button":
+		return "json"
+	case "multilineText", "richText":
+		return "text"
+	default:
+		return "string"
+	}
+}
+
+// fieldForColumn returns the Airtable field definition used to create a unified column
+func fieldForColumn(col unifiedmodel.Column) field {
+	switch col.DataType {
+	case "integer", "int", "bigint", "smallint":
+		return field{Name: col.Name, Type: "number", Options: map[string]interface{}{"precision": 0}}
+	case "decimal", "numeric", "float", "double", "real":
+		return field{Name: col.Name, Type: "number", Options: map[string]interface{}{"precision": 8}}
+	case "boolean", "bool":
+		return field{Name: col.Name, Type: "checkbox", Options: map[string]interface{}{"icon": "check", "color": "greenBright"}}
+	case "date":
+		return field{Name: col.Name, Type: "date", Options: map[string]interface{}{
+			"dateFormat": map[string]interface{}{"name": "iso"},
+		}}
+	case "timestamp", "datetime", "timestamptz":
+		return field{Name: col.Name, Type: "dateTime", Options: map[string]interface{}{
+			"dateFormat": map[string]interface{}{"name": "iso"},
+			"timeFormat": map[string]interface{}{"name": "24hour"},
+			"timeZone":   "utc",
+		}}
+	case "text", "json":
+		return field{Name: col.Name, Type: "multilineText"}
+	default:
+		return field{Name: col.Name, Type: "singleLineText"}
+	}
+}
+
+// orderedColumns returns column names by ordinal position, then by name
+func orderedColumns(table unifiedmodel.Table) []string {
+	names := make([]string, 0, len(table.Columns))
+	for name := range table.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := table.Columns[names[i]].OrdinalPosition, table.Columns[names[j]].OrdinalPosition
+		if pi != nil && pj != nil && *pi != *pj {
+			return *pi < *pj
+		}
+		if (pi == nil) != (pj == nil) {
+			return pi != nil
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
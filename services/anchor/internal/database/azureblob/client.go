@@ -34,6 +34,9 @@ func NewAzureBlobClient(ctx context.Context, cfg adapter.ConnectionConfig) (*Azu
 		// Build connection string from components
 		accountName := cfg.Username
 		accountKey := cfg.Password
+		if cfg.Emulator && accountName == "" {
+			accountName, accountKey = adapter.AzuriteAccountName, adapter.AzuriteAccountKey
+		}
 
 		if accountName == "" || accountKey == "" {
 			return nil, fmt.Errorf("Azure Blob requires account name and account key")
@@ -41,6 +44,11 @@ func NewAzureBlobClient(ctx context.Context, cfg adapter.ConnectionConfig) (*Azu
 
 		connStr := fmt.Sprintf("DefaultEndpointsProtocol=https;AccountName=%s;AccountKey=%s;EndpointSuffix=core.windows.net",
 			accountName, accountKey)
+		if cfg.Emulator {
+			// Azurite serves the account in the path of its address rather than in a subdomain
+			connStr = fmt.Sprintf("AccountName=%s;AccountKey=%s;BlobEndpoint=%s/%s;",
+				accountName, accountKey, adapter.EmulatorEndpoint(cfg.Host, cfg.Port, cfg.SSL), accountName)
+		}
 
 		client, err = azblob.NewClientFromConnectionString(connStr, nil)
 		if err != nil {
@@ -66,6 +74,8 @@ func NewAzureBlobClientFromInstance(ctx context.Context, cfg adapter.InstanceCon
 		Username:         cfg.Username,
 		Password:         cfg.Password,
 		ConnectionString: cfg.ConnectionString,
+		SSL:              cfg.SSL,
+		Emulator:         cfg.Emulator,
 	}
 
 	return NewAzureBlobClient(ctx, connCfg)
@@ -284,6 +284,12 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream is not supported, as Stream always returns the first batch and cannot be
+// paged by offset.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(d.conn.Type(), "fetch stream", "stream does not page by offset")
+}
+
 // ExecuteQuery is not supported for Azure Blob Storage.
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	return nil, fmt.Errorf("ExecuteQuery not supported for Azure Blob Storage")
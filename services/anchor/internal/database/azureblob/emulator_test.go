@@ -0,0 +1,22 @@
+package azureblob
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/emulatortest"
+)
+
+// TestEmulator runs the adapter against Azurite, see emulatortest
+func TestEmulator(t *testing.T) {
+	emulatortest.Run(t, NewAdapter(), emulatortest.Case{
+		// Prefixes of blob names are created by uploading blobs
+		Table: func(database string) emulatortest.Table {
+			return emulatortest.Table{Name: "items"}
+		},
+		Rows: []map[string]interface{}{
+			{"name": "items/a.json", "content": `{"name":"Ada"}`, "content_type": "application/json"},
+			{"name": "items/b.json", "content": `{"name":"Grace"}`, "content_type": "application/json"},
+		},
+		Key: "name",
+	})
+}
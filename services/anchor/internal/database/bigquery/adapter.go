@@ -126,7 +126,7 @@ func (c *Connection) DataOperations() adapter.DataOperator {
 
 // ReplicationOperations returns the replication operator.
 func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
-	return &ReplicationOps{conn: c}
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.BigQuery)
 }
 
 // MetadataOperations returns the metadata operator.
@@ -23,21 +23,31 @@ func NewBigQueryClient(ctx context.Context, cfg adapter.ConnectionConfig) (*BigQ
 	var opts []option.ClientOption
 
 	// Add credentials if provided
-	if cfg.CredentialsFile != "" {
+	if cfg.Emulator {
+		// The emulator serves the REST API over plain HTTP and does not authenticate
+		opts = append(opts,
+			option.WithEndpoint(adapter.EmulatorEndpoint(cfg.Host, cfg.Port, cfg.SSL)),
+			option.WithoutAuthentication())
+	} else if cfg.CredentialsFile != "" {
 		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
 	} else if cfg.CredentialsJSON != "" {
 		opts = append(opts, option.WithCredentialsJSON([]byte(cfg.CredentialsJSON)))
 	}
 
+	projectID := cfg.ProjectID
+	if cfg.Emulator && projectID == "" {
+		projectID = adapter.BigQueryEmulatorProject
+	}
+
 	// Create BigQuery client
-	client, err := bigquery.NewClient(ctx, cfg.ProjectID, opts...)
+	client, err := bigquery.NewClient(ctx, projectID, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
 	}
 
 	return &BigQueryClient{
 		client:    client,
-		projectID: cfg.ProjectID,
+		projectID: projectID,
 		datasetID: cfg.DatabaseName, // In BigQuery, dataset = database
 		location:  cfg.Location,
 	}, nil
@@ -51,6 +61,10 @@ func NewBigQueryClientFromInstance(ctx context.Context, cfg adapter.InstanceConf
 		CredentialsFile: cfg.CredentialsFile,
 		CredentialsJSON: cfg.CredentialsJSON,
 		Location:        cfg.Location,
+		Host:            cfg.Host,
+		Port:            cfg.Port,
+		SSL:             cfg.SSL,
+		Emulator:        cfg.Emulator,
 	}
 
 	return NewBigQueryClient(ctx, connCfg)
@@ -2,11 +2,13 @@ package bigquery
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"google.golang.org/api/iterator"
 )
 
@@ -29,14 +31,11 @@ func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []
 	}
 
 	// Build query
-	selectClause := "*"
-	if len(columns) > 0 {
-		selectClause = strings.Join(columns, ", ")
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList(columns), tableRef(projectID, datasetID, table))
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM `%s.%s.%s` LIMIT %d",
-		selectClause, projectID, datasetID, table, limit)
-
 	return d.executeQueryToRows(ctx, query)
 }
 
@@ -72,28 +71,63 @@ func (d *DataOps) executeQueryToRows(ctx context.Context, queryStr string) ([]ma
 	return rows, nil
 }
 
-// Insert inserts rows into BigQuery.
-func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
-	dataset := d.conn.client.GetDataset()
-	tableRef := dataset.Table(table)
+// insertBatchSize is the number of rows sent per streaming insert request
+const insertBatchSize = 500
+
+// mapSaver adapts a row map to bigquery.ValueSaver for streaming inserts.
+type mapSaver map[string]interface{}
 
-	inserter := tableRef.Inserter()
+// Save implements bigquery.ValueSaver. An empty insert ID lets BigQuery skip best-effort deduplication.
+func (m mapSaver) Save() (map[string]bigquery.Value, string, error) {
+	row := make(map[string]bigquery.Value, len(m))
+	for k, v := range m {
+		row[k] = v
+	}
+	return row, bigquery.NoDedupeID, nil
+}
 
-	// Convert data to BigQuery value saver
-	items := make([]*bigquery.StructSaver, 0, len(data))
-	for _, row := range data {
-		items = append(items, &bigquery.StructSaver{
-			Schema: nil, // Auto-detect schema
-			Struct: row,
-		})
+// Insert inserts rows into BigQuery using the streaming insert API. Rows rejected by
+// BigQuery are not counted; the first row error is returned after all batches are sent.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
 	}
 
-	err := inserter.Put(ctx, items)
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert rows: %w", err)
+	inserter := d.conn.client.GetDataset().Table(table).Inserter()
+
+	var inserted int64
+	var firstErr error
+	for start := 0; start < len(data); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		items := make([]mapSaver, 0, end-start)
+		for _, row := range data[start:end] {
+			items = append(items, mapSaver(row))
+		}
+
+		err := inserter.Put(ctx, items)
+		if err == nil {
+			inserted += int64(len(items))
+			continue
+		}
+
+		var multiErr bigquery.PutMultiError
+		if !errors.As(err, &multiErr) {
+			return inserted, adapter.WrapError(dbcapabilities.BigQuery, "insert", err)
+		}
+		inserted += int64(len(items) - len(multiErr))
+		if firstErr == nil {
+			firstErr = multiErr
+		}
 	}
 
-	return int64(len(items)), nil
+	if firstErr != nil {
+		return inserted, adapter.WrapError(dbcapabilities.BigQuery, "insert", firstErr)
+	}
+	return inserted, nil
 }
 
 // Update is not directly supported in BigQuery. Use UPDATE queries instead.
@@ -115,12 +149,12 @@ func (d *DataOps) Delete(ctx context.Context, table string, conditions map[strin
 	}
 
 	// Build WHERE clause
-	whereClause := buildWhereClause(conditions)
+	whereClause, params := buildWhereClause(conditions)
 
-	query := fmt.Sprintf("DELETE FROM `%s.%s.%s` WHERE %s",
-		projectID, datasetID, table, whereClause)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", tableRef(projectID, datasetID, table), whereClause)
 
 	q := d.conn.client.Client().Query(query)
+	q.Parameters = params
 	job, err := q.Run(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete rows: %w", err)
@@ -138,20 +172,48 @@ func (d *DataOps) Delete(ctx context.Context, table string, conditions map[strin
 	return 1, nil
 }
 
-// buildWhereClause builds a WHERE clause from conditions.
-func buildWhereClause(conditions map[string]interface{}) string {
+// buildWhereClause builds a WHERE clause from conditions. The values are passed as named query
+// parameters rather than literals, so that no value can end or escape a string literal.
+func buildWhereClause(conditions map[string]interface{}) (string, []bigquery.QueryParameter) {
 	clauses := make([]string, 0, len(conditions))
+	params := make([]bigquery.QueryParameter, 0, len(conditions))
 	for k, v := range conditions {
-		switch val := v.(type) {
-		case string:
-			clauses = append(clauses, fmt.Sprintf("%s = '%s'", k, val))
-		case int, int64, float64:
-			clauses = append(clauses, fmt.Sprintf("%s = %v", k, val))
-		default:
-			clauses = append(clauses, fmt.Sprintf("%s = '%v'", k, val))
+		if v == nil {
+			clauses = append(clauses, fmt.Sprintf("%s IS NULL", quoteIdent(k)))
+			continue
 		}
+		name := fmt.Sprintf("p%d", len(params))
+		clauses = append(clauses, fmt.Sprintf("%s = @%s", quoteIdent(k), name))
+		params = append(params, bigquery.QueryParameter{Name: name, Value: v})
+	}
+	if len(clauses) == 0 {
+		return "true", nil
+	}
+	return strings.Join(clauses, " AND "), params
+}
+
+// tableRef returns the fully qualified, backtick-quoted table reference.
+func tableRef(projectID, datasetID, table string) string {
+	return quoteIdent(projectID + "." + datasetID + "." + table)
+}
+
+// quoteIdent quotes a BigQuery identifier with backticks. Backslashes are escaped first, so
+// that a name ending in one cannot escape the closing backtick.
+func quoteIdent(name string) string {
+	name = strings.ReplaceAll(name, "\\", "\\\\")
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// selectList returns the quoted select list, or * when no columns are given.
+func selectList(columns []string) string {
+	if len(columns) == 0 {
+		return "*"
 	}
-	return strings.Join(clauses, " AND ")
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdent(c)
+	}
+	return strings.Join(quoted, ", ")
 }
 
 // Stream retrieves rows in batches.
@@ -162,20 +224,27 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 		return adapter.StreamResult{}, fmt.Errorf("no dataset specified")
 	}
 
-	selectClause := "*"
-	if len(params.Columns) > 0 {
-		selectClause = strings.Join(params.Columns, ", ")
+	batchSize := params.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM `%s.%s.%s` LIMIT %d OFFSET %d",
-		selectClause, projectID, datasetID, params.Table, params.BatchSize, params.Offset)
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList(params.Columns), tableRef(projectID, datasetID, params.Table))
+	if params.OrderBy != "" {
+		query += " ORDER BY " + quoteIdent(params.OrderBy)
+	}
+	// Fetch one extra row to find out whether another batch follows
+	query += fmt.Sprintf(" LIMIT %d OFFSET %d", batchSize+1, params.Offset)
 
 	rows, err := d.executeQueryToRows(ctx, query)
 	if err != nil {
 		return adapter.StreamResult{}, err
 	}
 
-	hasMore := len(rows) == int(params.BatchSize)
+	hasMore := len(rows) > int(batchSize)
+	if hasMore {
+		rows = rows[:batchSize]
+	}
 
 	return adapter.StreamResult{
 		Data:       rows,
@@ -184,9 +253,18 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
-// ExecuteQuery executes a SQL query.
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// ExecuteQuery executes a SQL query. The arguments are bound to the query's positional
+// parameters (?).
 func (d *DataOps) ExecuteQuery(ctx context.Context, queryStr string, args ...interface{}) ([]interface{}, error) {
 	query := d.conn.client.Client().Query(queryStr)
+	for _, arg := range args {
+		query.Parameters = append(query.Parameters, bigquery.QueryParameter{Value: arg})
+	}
 	it, err := query.Read(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
@@ -255,8 +333,7 @@ func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause str
 		return 0, false, fmt.Errorf("no dataset specified")
 	}
 
-	query := fmt.Sprintf("SELECT COUNT(*) as count FROM `%s.%s.%s`",
-		projectID, datasetID, table)
+	query := "SELECT COUNT(*) AS count FROM " + tableRef(projectID, datasetID, table)
 
 	if whereClause != "" {
 		query += " WHERE " + whereClause
@@ -292,8 +369,7 @@ func (d *DataOps) Wipe(ctx context.Context) error {
 		}
 
 		// Delete all rows from table
-		query := fmt.Sprintf("DELETE FROM `%s.%s.%s` WHERE true",
-			projectID, datasetID, table.TableID)
+		query := fmt.Sprintf("DELETE FROM %s WHERE true", tableRef(projectID, datasetID, table.TableID))
 
 		q := d.conn.client.Client().Query(query)
 		job, err := q.Run(ctx)
@@ -0,0 +1,36 @@
+package bigquery
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/database/emulatortest"
+)
+
+// TestEmulator runs the adapter against the BigQuery emulator, see emulatortest
+func TestEmulator(t *testing.T) {
+	emulatortest.Run(t, NewAdapter(), emulatortest.Case{
+		Table: func(database string) emulatortest.Table {
+			return emulatortest.Table{
+				Model: &unifiedmodel.UnifiedModel{
+					DatabaseType: dbcapabilities.BigQuery,
+					Tables: map[string]unifiedmodel.Table{"items": {
+						Name: "items",
+						Columns: map[string]unifiedmodel.Column{
+							"id":    {Name: "id", DataType: "string"},
+							"name":  {Name: "name", DataType: "string", Nullable: true},
+							"count": {Name: "count", DataType: "integer", Nullable: true},
+						},
+					}},
+				},
+				Name: "items",
+			}
+		},
+		Rows: []map[string]interface{}{
+			{"id": "a", "name": "Ada", "count": 1},
+			{"id": "b", "name": "Grace", "count": 2},
+		},
+		Key: "id",
+	})
+}
@@ -108,6 +108,11 @@ func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
 		return 0, fmt.Errorf("no dataset specified")
 	}
 
+	if m.conn.config.Emulator {
+		// The emulator has no __TABLES__ meta-table
+		return m.sumTableSizes(ctx, m.conn.client.GetDataset())
+	}
+
 	// Query INFORMATION_SCHEMA to get dataset size
 	query := fmt.Sprintf("SELECT SUM(size_bytes) as total_size FROM `%s.%s.__TABLES__`", projectID, datasetID)
 
@@ -167,6 +172,30 @@ func (m *MetadataOps) countTables(ctx context.Context, dataset *bigquery.Dataset
 	return count, nil
 }
 
+// sumTableSizes returns the total size of the tables of a dataset from their metadata.
+func (m *MetadataOps) sumTableSizes(ctx context.Context, dataset *bigquery.Dataset) (int64, error) {
+	it := dataset.Tables(ctx)
+	var totalSize int64
+
+	for {
+		table, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to list tables: %w", err)
+		}
+
+		meta, err := table.Metadata(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get metadata of table %s: %w", table.TableID, err)
+		}
+		totalSize += meta.NumBytes
+	}
+
+	return totalSize, nil
+}
+
 // ExecuteCommand executes a BigQuery SQL command.
 func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
 	if m.conn == nil {
@@ -2,10 +2,17 @@ package bigquery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 
 	"cloud.google.com/go/bigquery"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
@@ -14,191 +21,441 @@ type SchemaOps struct {
 	conn *Connection
 }
 
-// DiscoverSchema retrieves the schema of BigQuery dataset (tables and their schemas).
+// DiscoverSchema retrieves the schema of the BigQuery dataset. Tables, views and
+// materialized views are mapped to their UnifiedModel counterparts.
 func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
 	datasetID := s.conn.client.GetDatasetID()
 	if datasetID == "" {
-		return nil, fmt.Errorf("no dataset specified")
+		return nil, adapter.NewConfigurationError(dbcapabilities.BigQuery, "databaseName", "no dataset specified")
 	}
 
-	dataset := s.conn.client.GetDataset()
-	it := dataset.Tables(ctx)
-
-	tables := make([]*unifiedmodel.Table, 0)
+	model := &unifiedmodel.UnifiedModel{
+		DatabaseType:      dbcapabilities.BigQuery,
+		Tables:            make(map[string]unifiedmodel.Table),
+		Views:             make(map[string]unifiedmodel.View),
+		MaterializedViews: make(map[string]unifiedmodel.MaterializedView),
+	}
 
+	it := s.conn.client.GetDataset().Tables(ctx)
 	for {
 		table, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list tables: %w", err)
+			return nil, adapter.WrapError(dbcapabilities.BigQuery, "discover_schema", err)
 		}
 
-		// Get table metadata
 		metadata, err := table.Metadata(ctx)
 		if err != nil {
-			continue // Skip tables we can't read
+			return nil, adapter.WrapError(dbcapabilities.BigQuery, "discover_schema", fmt.Errorf("table %s: %w", table.TableID, err))
 		}
 
-		umTable, err := s.convertBigQueryTableToUnified(table.TableID, metadata)
-		if err != nil {
-			continue
+		switch metadata.Type {
+		case bigquery.ViewTable:
+			model.Views[table.TableID] = unifiedmodel.View{
+				Name:       table.TableID,
+				Definition: metadata.ViewQuery,
+				Comment:    metadata.Description,
+				Columns:    convertSchema(metadata.Schema),
+			}
+		case bigquery.MaterializedView:
+			mv := unifiedmodel.MaterializedView{
+				Name:    table.TableID,
+				Columns: convertSchema(metadata.Schema),
+			}
+			if metadata.MaterializedView != nil {
+				mv.Definition = metadata.MaterializedView.Query
+				if metadata.MaterializedView.EnableRefresh {
+					mv.RefreshMode = "deferred"
+				} else {
+					mv.RefreshMode = "manual"
+				}
+			}
+			model.MaterializedViews[table.TableID] = mv
+		default:
+			model.Tables[table.TableID] = convertTable(table.TableID, metadata)
 		}
+	}
+
+	return model, nil
+}
 
-		tables = append(tables, umTable)
+// convertTable converts BigQuery table metadata to a UnifiedModel table.
+func convertTable(tableID string, metadata *bigquery.TableMetadata) unifiedmodel.Table {
+	table := unifiedmodel.Table{
+		Name:        tableID,
+		Comment:     metadata.Description,
+		Labels:      metadata.Labels,
+		Columns:     convertSchema(metadata.Schema),
+		Constraints: make(map[string]unifiedmodel.Constraint),
+		Options: map[string]any{
+			"table_type": string(metadata.Type),
+			"num_rows":   metadata.NumRows,
+			"num_bytes":  metadata.NumBytes,
+		},
 	}
 
-	// Convert tables slice to map
-	tablesMap := make(map[string]unifiedmodel.Table)
-	for _, t := range tables {
-		tablesMap[t.Name] = *t
+	if tp := metadata.TimePartitioning; tp != nil {
+		partition := map[string]any{"type": string(tp.Type)}
+		if tp.Field != "" {
+			partition["field"] = tp.Field
+		}
+		if tp.Expiration > 0 {
+			partition["expiration_ms"] = tp.Expiration.Milliseconds()
+		}
+		table.Options["time_partitioning"] = partition
+		if col, ok := table.Columns[tp.Field]; ok {
+			col.IsPartitionKey = true
+			table.Columns[tp.Field] = col
+		}
+	}
+	if rp := metadata.RangePartitioning; rp != nil {
+		partition := map[string]any{"field": rp.Field}
+		if rp.Range != nil {
+			partition["start"] = rp.Range.Start
+			partition["end"] = rp.Range.End
+			partition["interval"] = rp.Range.Interval
+		}
+		table.Options["range_partitioning"] = partition
+		if col, ok := table.Columns[rp.Field]; ok {
+			col.IsPartitionKey = true
+			table.Columns[rp.Field] = col
+		}
+	}
+	if metadata.Clustering != nil && len(metadata.Clustering.Fields) > 0 {
+		table.Options["clustering_fields"] = metadata.Clustering.Fields
+		for _, field := range metadata.Clustering.Fields {
+			if col, ok := table.Columns[field]; ok {
+				col.IsClusteringKey = true
+				table.Columns[field] = col
+			}
+		}
 	}
 
-	model := &unifiedmodel.UnifiedModel{
-		DatabaseType: s.conn.Type(),
-		Tables:       tablesMap,
+	// Key constraints are informational in BigQuery and never enforced
+	if tc := metadata.TableConstraints; tc != nil {
+		if tc.PrimaryKey != nil && len(tc.PrimaryKey.Columns) > 0 {
+			name := "pk_" + tableID
+			table.Constraints[name] = unifiedmodel.Constraint{
+				Name:    name,
+				Type:    unifiedmodel.ConstraintTypePrimaryKey,
+				Columns: tc.PrimaryKey.Columns,
+				Options: map[string]any{"enforced": false},
+			}
+			for _, c := range tc.PrimaryKey.Columns {
+				if col, ok := table.Columns[c]; ok {
+					col.IsPrimaryKey = true
+					table.Columns[c] = col
+				}
+			}
+		}
+		for i, fk := range tc.ForeignKeys {
+			name := fk.Name
+			if name == "" {
+				name = fmt.Sprintf("fk_%s_%d", tableID, i+1)
+			}
+			constraint := unifiedmodel.Constraint{
+				Name:    name,
+				Type:    unifiedmodel.ConstraintTypeForeignKey,
+				Options: map[string]any{"enforced": false},
+			}
+			if fk.ReferencedTable != nil {
+				constraint.Reference.Table = fk.ReferencedTable.TableID
+			}
+			for _, ref := range fk.ColumnReferences {
+				constraint.Columns = append(constraint.Columns, ref.ReferencingColumn)
+				constraint.Reference.Columns = append(constraint.Reference.Columns, ref.ReferencedColumn)
+			}
+			table.Constraints[name] = constraint
+		}
 	}
 
-	return model, nil
+	return table
 }
 
-// convertBigQueryTableToUnified converts BigQuery table metadata to UnifiedModel table.
-func (s *SchemaOps) convertBigQueryTableToUnified(tableID string, metadata *bigquery.TableMetadata) (*unifiedmodel.Table, error) {
-	columnsMap := make(map[string]unifiedmodel.Column)
-
-	for _, field := range metadata.Schema {
+// convertSchema converts a BigQuery schema to UnifiedModel columns. RECORD columns
+// keep their nested fields in options and REPEATED columns are marked as arrays.
+func convertSchema(schema bigquery.Schema) map[string]unifiedmodel.Column {
+	columns := make(map[string]unifiedmodel.Column, len(schema))
+	for i, field := range schema {
+		position := i + 1
 		column := unifiedmodel.Column{
-			Name:     field.Name,
-			DataType: s.mapBigQueryType(field.Type),
-			Nullable: !field.Required,
+			Name:            field.Name,
+			DataType:        mapBigQueryType(field),
+			Nullable:        !field.Required,
+			OrdinalPosition: &position,
+			Default:         field.DefaultValueExpression,
 		}
-		columnsMap[field.Name] = column
-	}
 
-	table := &unifiedmodel.Table{
-		Name:    tableID,
-		Columns: columnsMap,
-	}
+		options := make(map[string]any)
+		if field.Description != "" {
+			options["description"] = field.Description
+		}
+		if field.Repeated {
+			options["repeated"] = true
+		}
+		if field.MaxLength > 0 {
+			options["max_length"] = field.MaxLength
+		}
+		if field.Precision > 0 {
+			options["precision"] = field.Precision
+			options["scale"] = field.Scale
+		}
+		if len(field.Schema) > 0 {
+			options["fields"] = convertSchema(field.Schema)
+		}
+		if len(options) > 0 {
+			column.Options = options
+		}
 
-	return table, nil
+		columns[field.Name] = column
+	}
+	return columns
 }
 
-// mapBigQueryType maps BigQuery field types to unified data types.
-func (s *SchemaOps) mapBigQueryType(fieldType bigquery.FieldType) string {
-	switch fieldType {
+// mapBigQueryType maps a BigQuery field to a unified data type.
+func mapBigQueryType(field *bigquery.FieldSchema) string {
+	var dataType string
+	switch field.Type {
 	case bigquery.StringFieldType:
-		return "string"
+		dataType = "string"
 	case bigquery.BytesFieldType:
-		return "bytes"
+		dataType = "bytes"
 	case bigquery.IntegerFieldType:
-		return "integer"
+		dataType = "integer"
 	case bigquery.FloatFieldType:
-		return "float"
+		dataType = "float"
 	case bigquery.BooleanFieldType:
-		return "boolean"
+		dataType = "boolean"
 	case bigquery.TimestampFieldType:
-		return "timestamp"
+		dataType = "timestamp"
 	case bigquery.DateFieldType:
-		return "date"
+		dataType = "date"
 	case bigquery.TimeFieldType:
-		return "time"
+		dataType = "time"
 	case bigquery.DateTimeFieldType:
-		return "datetime"
+		dataType = "datetime"
 	case bigquery.RecordFieldType:
-		return "record"
+		dataType = "record"
 	case bigquery.NumericFieldType:
-		return "numeric"
+		dataType = "numeric"
 	case bigquery.BigNumericFieldType:
-		return "bignumeric"
+		dataType = "bignumeric"
 	case bigquery.GeographyFieldType:
-		return "geography"
+		dataType = "geography"
 	case bigquery.JSONFieldType:
-		return "json"
+		dataType = "json"
+	case bigquery.IntervalFieldType:
+		dataType = "interval"
 	default:
-		return "string"
+		dataType = "string"
 	}
+	if field.Repeated {
+		return dataType + "[]"
+	}
+	return dataType
 }
 
-// CreateStructure creates BigQuery structure from a UnifiedModel.
+// CreateStructure creates BigQuery tables and views from a UnifiedModel. Tables that
+// already exist are left unchanged so the call can be repeated safely.
 func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if model == nil {
+		return adapter.NewConfigurationError(dbcapabilities.BigQuery, "model", "unified model cannot be nil")
+	}
 	dataset := s.conn.client.GetDataset()
 
-	for tableName, table := range model.Tables {
-		// Build BigQuery schema from UnifiedModel table
-		schemaFields := make(bigquery.Schema, 0, len(table.Columns))
+	tableNames := make([]string, 0, len(model.Tables))
+	for name := range model.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
 
-		for _, col := range table.Columns {
-			field := &bigquery.FieldSchema{
-				Name:     col.Name,
-				Type:     s.mapUnifiedTypeToBigQuery(col.DataType),
-				Required: !col.Nullable,
-			}
-			schemaFields = append(schemaFields, field)
+	for _, name := range tableNames {
+		metadata := tableMetadata(model.Tables[name])
+		if err := dataset.Table(name).Create(ctx, metadata); err != nil && !isAlreadyExists(err) {
+			return adapter.WrapError(dbcapabilities.BigQuery, "create_structure", fmt.Errorf("table %s: %w", name, err))
 		}
+	}
 
-		// Create table
-		tableRef := dataset.Table(tableName)
-		err := tableRef.Create(ctx, &bigquery.TableMetadata{
-			Schema: schemaFields,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create table %s: %w", tableName, err)
+	for name, view := range model.Views {
+		if view.Definition == "" {
+			continue
+		}
+		metadata := &bigquery.TableMetadata{ViewQuery: view.Definition, Description: view.Comment}
+		if err := dataset.Table(name).Create(ctx, metadata); err != nil && !isAlreadyExists(err) {
+			return adapter.WrapError(dbcapabilities.BigQuery, "create_structure", fmt.Errorf("view %s: %w", name, err))
 		}
 	}
 
 	return nil
 }
 
-// mapUnifiedTypeToBigQuery maps unified data types to BigQuery field types.
-func (s *SchemaOps) mapUnifiedTypeToBigQuery(dataType string) bigquery.FieldType {
-	switch dataType {
-	case "string", "text", "varchar":
+// tableMetadata builds the BigQuery metadata to create a unified table.
+func tableMetadata(table unifiedmodel.Table) *bigquery.TableMetadata {
+	metadata := &bigquery.TableMetadata{
+		Description: table.Comment,
+		Labels:      table.Labels,
+		Schema:      buildSchema(table.Columns),
+	}
+
+	var partitionField string
+	var clusterFields []string
+	for _, name := range orderedColumns(table.Columns) {
+		col := table.Columns[name]
+		if col.IsPartitionKey && partitionField == "" {
+			partitionField = name
+		}
+		if col.IsClusteringKey {
+			clusterFields = append(clusterFields, name)
+		}
+	}
+	if partitionField != "" {
+		switch mapUnifiedTypeToBigQuery(table.Columns[partitionField].DataType) {
+		case bigquery.TimestampFieldType, bigquery.DateFieldType, bigquery.DateTimeFieldType:
+			metadata.TimePartitioning = &bigquery.TimePartitioning{Type: bigquery.DayPartitioningType, Field: partitionField}
+		}
+	}
+	// BigQuery allows at most four clustering columns
+	if len(clusterFields) > 4 {
+		clusterFields = clusterFields[:4]
+	}
+	if len(clusterFields) > 0 {
+		metadata.Clustering = &bigquery.Clustering{Fields: clusterFields}
+	}
+
+	for _, c := range table.Constraints {
+		if c.Type == unifiedmodel.ConstraintTypePrimaryKey && len(c.Columns) > 0 {
+			metadata.TableConstraints = &bigquery.TableConstraints{
+				PrimaryKey: &bigquery.PrimaryKey{Columns: c.Columns},
+			}
+			break
+		}
+	}
+
+	return metadata
+}
+
+// buildSchema converts unified columns to a BigQuery schema in ordinal order.
+func buildSchema(columns map[string]unifiedmodel.Column) bigquery.Schema {
+	schema := make(bigquery.Schema, 0, len(columns))
+	for _, name := range orderedColumns(columns) {
+		col := columns[name]
+		dataType := col.DataType
+		repeated := strings.HasSuffix(dataType, "[]")
+		if r, ok := col.Options["repeated"].(bool); ok && r {
+			repeated = true
+		}
+		dataType = strings.TrimSuffix(dataType, "[]")
+
+		field := &bigquery.FieldSchema{
+			Name:     col.Name,
+			Type:     mapUnifiedTypeToBigQuery(dataType),
+			Repeated: repeated,
+			// REPEATED fields cannot also be REQUIRED
+			Required: !col.Nullable && !repeated,
+		}
+		if desc, ok := col.Options["description"].(string); ok {
+			field.Description = desc
+		}
+		if nested, ok := col.Options["fields"].(map[string]unifiedmodel.Column); ok && field.Type == bigquery.RecordFieldType {
+			field.Schema = buildSchema(nested)
+		}
+		// A RECORD without known fields cannot be created; store it as JSON instead
+		if field.Type == bigquery.RecordFieldType && len(field.Schema) == 0 {
+			field.Type = bigquery.JSONFieldType
+		}
+		schema = append(schema, field)
+	}
+	return schema
+}
+
+// mapUnifiedTypeToBigQuery maps unified and common native data types to BigQuery field types.
+func mapUnifiedTypeToBigQuery(dataType string) bigquery.FieldType {
+	t := strings.ToLower(strings.TrimSpace(dataType))
+	if i := strings.Index(t, "("); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+
+	switch t {
+	case "string", "text", "varchar", "char", "character", "character varying", "nvarchar", "nchar",
+		"uuid", "enum", "citext", "mediumtext", "longtext", "tinytext", "clob":
 		return bigquery.StringFieldType
-	case "integer", "int", "bigint":
+	case "integer", "int", "bigint", "smallint", "tinyint", "mediumint", "int2", "int4", "int8", "int64",
+		"serial", "bigserial", "smallserial":
 		return bigquery.IntegerFieldType
-	case "float", "double", "real":
+	case "float", "double", "double precision", "real", "float4", "float8", "float64":
 		return bigquery.FloatFieldType
-	case "boolean", "bool":
+	case "boolean", "bool", "bit":
 		return bigquery.BooleanFieldType
-	case "timestamp":
+	case "timestamp", "timestamptz", "timestamp with time zone":
 		return bigquery.TimestampFieldType
+	case "timestamp without time zone", "datetime", "datetime2", "smalldatetime":
+		return bigquery.DateTimeFieldType
 	case "date":
 		return bigquery.DateFieldType
-	case "time":
+	case "time", "time without time zone":
 		return bigquery.TimeFieldType
-	case "datetime":
-		return bigquery.DateTimeFieldType
-	case "bytes", "binary":
+	case "bytes", "binary", "varbinary", "bytea", "blob", "longblob":
 		return bigquery.BytesFieldType
-	case "numeric", "decimal":
+	case "numeric", "decimal", "money":
 		return bigquery.NumericFieldType
-	case "json":
+	case "bignumeric", "bigdecimal":
+		return bigquery.BigNumericFieldType
+	case "json", "jsonb":
 		return bigquery.JSONFieldType
+	case "geography", "geometry":
+		return bigquery.GeographyFieldType
+	case "record", "struct":
+		return bigquery.RecordFieldType
+	case "interval":
+		return bigquery.IntervalFieldType
 	default:
 		return bigquery.StringFieldType
 	}
 }
 
+// orderedColumns returns column names by ordinal position, then by name.
+func orderedColumns(columns map[string]unifiedmodel.Column) []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := columns[names[i]].OrdinalPosition, columns[names[j]].OrdinalPosition
+		if pi != nil && pj != nil && *pi != *pj {
+			return *pi < *pj
+		}
+		if (pi == nil) != (pj == nil) {
+			return pi != nil
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// isAlreadyExists reports whether err is BigQuery's 409 response for an existing resource.
+func isAlreadyExists(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
+
 // ListTables lists all tables in the dataset.
 func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
 	datasetID := s.conn.client.GetDatasetID()
 	if datasetID == "" {
-		return nil, fmt.Errorf("no dataset specified")
+		return nil, adapter.NewConfigurationError(dbcapabilities.BigQuery, "databaseName", "no dataset specified")
 	}
 
-	dataset := s.conn.client.GetDataset()
-	it := dataset.Tables(ctx)
-
+	it := s.conn.client.GetDataset().Tables(ctx)
 	tables := make([]string, 0)
-
 	for {
 		table, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to list tables: %w", err)
+			return nil, adapter.WrapError(dbcapabilities.BigQuery, "list_tables", err)
 		}
 		tables = append(tables, table.TableID)
 	}
@@ -208,18 +465,15 @@ func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
 
 // GetTableSchema retrieves the schema for a specific table.
 func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
-	dataset := s.conn.client.GetDataset()
-	table := dataset.Table(tableName)
-
-	metadata, err := table.Metadata(ctx)
+	metadata, err := s.conn.client.GetDataset().Table(tableName).Metadata(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get table metadata: %w", err)
-	}
-
-	umTable, err := s.convertBigQueryTableToUnified(tableName, metadata)
-	if err != nil {
-		return nil, err
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return nil, adapter.NewNotFoundError(dbcapabilities.BigQuery, "table", tableName)
+		}
+		return nil, adapter.WrapError(dbcapabilities.BigQuery, "get_table_schema", err)
 	}
 
-	return umTable, nil
+	table := convertTable(tableName, metadata)
+	return &table, nil
 }
@@ -2,6 +2,7 @@ package cassandra
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	"github.com/gocql/gocql"
@@ -125,6 +126,9 @@ type Connection struct {
 	config    adapter.ConnectionConfig
 	adapter   *Adapter
 	connected int32
+
+	// replicationSources holds the commit log CDC sources opened on this connection, by source ID
+	replicationSources sync.Map
 }
 
 func (c *Connection) ID() string                        { return c.id }
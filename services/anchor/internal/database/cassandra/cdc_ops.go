@@ -8,14 +8,14 @@ import (
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
 )
 
-// ParseEvent converts a Cassandra polling event to a standardized CDCEvent.
+// ParseEvent converts a Cassandra commit log CDC or polling event to a standardized CDCEvent.
 func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]interface{}) (*adapter.CDCEvent, error) {
 	event := &adapter.CDCEvent{
 		Timestamp: time.Now(),
@@ -73,8 +73,13 @@ func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]int
 		event.OldData = oldData
 	}
 
-	// Use a combination of timestamp and data for LSN
-	event.LSN = fmt.Sprintf("%s_%d", event.TableName, time.Now().UnixNano())
+	// Commit log CDC events carry their position; polling events have none, so a combination
+	// of table name and timestamp is used
+	if position, ok := rawEvent["position"].(string); ok {
+		event.LSN = position
+	} else {
+		event.LSN = fmt.Sprintf("%s_%d", event.TableName, time.Now().UnixNano())
+	}
 
 	// Validate the event
 	if err := event.Validate(); err != nil {
@@ -349,7 +354,7 @@ func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]inte
 	var grpcConn *grpc.ClientConn
 	if transformationServiceEndpoint != "" {
 		// Connect to transformation service
-		conn, err := grpc.Dial(transformationServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
 		if err == nil {
 			transformClient = transformationv1.NewTransformationServiceClient(conn)
 			grpcConn = conn
@@ -0,0 +1,405 @@
+package cassandra
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Commit log versions whose mutations can be read: 6 is Cassandra 3.x, 7 is 4.x, 8 is 5.x
+const (
+	minCommitLogVersion = 6
+	maxCommitLogVersion = 8
+)
+
+const (
+	// commitLogSyncMarkerSize is the size of the marker starting each sync section
+	commitLogSyncMarkerSize = 8
+
+	// commitLogEntryOverhead is the size of the length, length CRC and entry CRC of an entry
+	commitLogEntryOverhead = 12
+
+	// commitLogTimestampEpoch is the epoch of the timestamps in encoding stats, 2015-09-22, in microseconds
+	commitLogTimestampEpoch = 1442880000 * 1000000
+)
+
+// Flags of a serialized partition update
+const (
+	partitionFlagEmpty             = 0x01
+	partitionFlagPartitionDeletion = 0x04
+)
+
+var (
+	commitLogSegmentPattern = regexp.MustCompile(`^CommitLog-(\d+)-(\d+)\.log$`)
+	commitLogIndexPattern   = regexp.MustCompile(`^CommitLog-(\d+)-(\d+)_cdc\.idx$`)
+)
+
+// cdcSegment is a commit log segment in the cdc_raw directory
+type cdcSegment struct {
+	id        int64
+	path      string
+	indexPath string // Empty when Cassandra writes no CDC index (3.x)
+}
+
+// cdcPosition is a position in the CDC commit log: the offset within a segment after the last
+// mutation that was passed on
+type cdcPosition struct {
+	segment int64
+	offset  int64
+}
+
+func (p cdcPosition) String() string {
+	return fmt.Sprintf("%d:%d", p.segment, p.offset)
+}
+
+// parseCDCPosition parses a position formatted as "segment:offset"
+func parseCDCPosition(position string) (cdcPosition, error) {
+	parts := strings.Split(position, ":")
+	if len(parts) != 2 {
+		return cdcPosition{}, fmt.Errorf("invalid CDC position %q: expected segment:offset", position)
+	}
+	segment, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return cdcPosition{}, fmt.Errorf("invalid CDC position %q: %v", position, err)
+	}
+	offset, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || offset < 0 {
+		return cdcPosition{}, fmt.Errorf("invalid CDC position %q: invalid offset", position)
+	}
+	return cdcPosition{segment: segment, offset: offset}, nil
+}
+
+// before reports whether p is before other
+func (p cdcPosition) before(other cdcPosition) bool {
+	if p.segment != other.segment {
+		return p.segment < other.segment
+	}
+	return p.offset < other.offset
+}
+
+// listCDCSegments returns the commit log segments of a cdc_raw directory ordered by segment ID.
+// Cassandra 4.0 and later write a _cdc.idx file next to each segment with its durable length;
+// when the directory holds index files, segments without one are not yet readable and skipped.
+func listCDCSegments(dir string) ([]cdcSegment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CDC directory %s: %v", dir, err)
+	}
+
+	indexes := make(map[int64]string)
+	for _, entry := range entries {
+		if m := commitLogIndexPattern.FindStringSubmatch(entry.Name()); m != nil {
+			id, _ := strconv.ParseInt(m[2], 10, 64)
+			indexes[id] = filepath.Join(dir, entry.Name())
+		}
+	}
+
+	var segments []cdcSegment
+	for _, entry := range entries {
+		m := commitLogSegmentPattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		id, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		indexPath, indexed := indexes[id]
+		if len(indexes) > 0 && !indexed {
+			continue
+		}
+		segments = append(segments, cdcSegment{id: id, path: filepath.Join(dir, entry.Name()), indexPath: indexPath})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].id < segments[j].id })
+	return segments, nil
+}
+
+// readCDCIndex returns the durable length of a segment and whether Cassandra finished writing it.
+// Without an index the segment was moved to cdc_raw once complete, so all of it is durable.
+func readCDCIndex(segment cdcSegment) (durable int64, completed bool, err error) {
+	if segment.indexPath == "" {
+		info, err := os.Stat(segment.path)
+		if err != nil {
+			return 0, false, fmt.Errorf("error reading CDC segment %s: %v", segment.path, err)
+		}
+		return info.Size(), true, nil
+	}
+
+	f, err := os.Open(segment.indexPath)
+	if err != nil {
+		return 0, false, fmt.Errorf("error reading CDC index %s: %v", segment.indexPath, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if durable, err = strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64); err != nil {
+			return 0, false, fmt.Errorf("invalid CDC index %s: %v", segment.indexPath, err)
+		}
+	}
+	if scanner.Scan() {
+		completed = strings.TrimSpace(scanner.Text()) == "COMPLETED"
+	}
+	return durable, completed, scanner.Err()
+}
+
+// readCDCSegment reads the durable part of a segment
+func readCDCSegment(segment cdcSegment, durable int64) ([]byte, error) {
+	f, err := os.Open(segment.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading CDC segment %s: %v", segment.path, err)
+	}
+	defer f.Close()
+
+	data := make([]byte, durable)
+	n, err := io.ReadFull(f, data)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("error reading CDC segment %s: %v", segment.path, err)
+	}
+	return data[:n], nil
+}
+
+// removeCDCSegment deletes a consumed segment and its index, releasing its space in cdc_raw
+func removeCDCSegment(segment cdcSegment) error {
+	if err := os.Remove(segment.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if segment.indexPath != "" {
+		if err := os.Remove(segment.indexPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitLogHeader is the descriptor at the start of a commit log segment
+type commitLogHeader struct {
+	version    int32
+	id         int64
+	parameters map[string]interface{}
+	size       int64
+}
+
+// parseCommitLogHeader parses the descriptor of a segment. Compressed and encrypted segments
+// are rejected, as their sync sections cannot be read as plain mutations.
+func parseCommitLogHeader(data []byte) (*commitLogHeader, error) {
+	if len(data) < 18 {
+		return nil, fmt.Errorf("commit log header is truncated")
+	}
+
+	header := &commitLogHeader{
+		version: int32(binary.BigEndian.Uint32(data[0:4])),
+		id:      int64(binary.BigEndian.Uint64(data[4:12])),
+	}
+	if header.version < minCommitLogVersion || header.version > maxCommitLogVersion {
+		return nil, fmt.Errorf("unsupported commit log version %d", header.version)
+	}
+
+	paramsLength := int64(binary.BigEndian.Uint16(data[12:14]))
+	header.size = 14 + paramsLength + 4
+	if int64(len(data)) < header.size {
+		return nil, fmt.Errorf("commit log header is truncated")
+	}
+	params := data[14 : 14+paramsLength]
+
+	crc := crc32.NewIEEE()
+	writeCRCInt(crc, uint32(header.version))
+	writeCRCInt(crc, uint32(header.id))
+	writeCRCInt(crc, uint32(header.id>>32))
+	writeCRCInt(crc, uint32(paramsLength))
+	crc.Write(params)
+	if crc.Sum32() != binary.BigEndian.Uint32(data[14+paramsLength:header.size]) {
+		return nil, fmt.Errorf("commit log header checksum mismatch")
+	}
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &header.parameters); err != nil {
+			return nil, fmt.Errorf("invalid commit log parameters: %v", err)
+		}
+	}
+	for key := range header.parameters {
+		if key == "compressionClass" || strings.HasPrefix(key, "enc") {
+			return nil, fmt.Errorf("compressed or encrypted commit logs are not supported (parameter %s)", key)
+		}
+	}
+
+	return header, nil
+}
+
+// commitLogMutation is a mutation read from a segment. A mutation changes a single partition
+// of one or more tables of a keyspace; tableID is the first of these tables.
+type commitLogMutation struct {
+	end               int64 // Offset following the entry
+	tableID           [16]byte
+	tableCount        int
+	partitionKey      []byte
+	partitionDeletion bool
+	timestamp         int64 // Lowest write timestamp of the update in microseconds, 0 when unknown
+}
+
+// readCommitLogMutations returns the mutations of the entries starting at or after from, and
+// the offset up to which the segment was read. Reading stops at the end of the written sync
+// sections; entries failing their checksum are skipped and counted.
+func readCommitLogMutations(data []byte, header *commitLogHeader, from int64) (mutations []commitLogMutation, end int64, skipped int) {
+	end = from
+	size := int64(len(data))
+
+	for marker := header.size; marker+commitLogSyncMarkerSize <= size; {
+		next := int64(binary.BigEndian.Uint32(data[marker : marker+4]))
+		if next <= marker || next > size {
+			break
+		}
+
+		crc := crc32.NewIEEE()
+		writeCRCInt(crc, uint32(header.id))
+		writeCRCInt(crc, uint32(header.id>>32))
+		writeCRCInt(crc, uint32(marker))
+		if crc.Sum32() != binary.BigEndian.Uint32(data[marker+4:marker+8]) {
+			// Left over from a recycled segment
+			break
+		}
+
+		for offset := marker + commitLogSyncMarkerSize; offset+commitLogEntryOverhead <= next; {
+			length := int64(int32(binary.BigEndian.Uint32(data[offset : offset+4])))
+			if length <= 0 {
+				break
+			}
+			sizeCRC := crc32.NewIEEE()
+			writeCRCInt(sizeCRC, uint32(length))
+			if sizeCRC.Sum32() != binary.BigEndian.Uint32(data[offset+4:offset+8]) {
+				break
+			}
+			entryEnd := offset + 8 + length + 4
+			if entryEnd > next {
+				break
+			}
+
+			if offset >= from {
+				body := data[offset+8 : offset+8+length]
+				sizeCRC.Write(body)
+				if sizeCRC.Sum32() != binary.BigEndian.Uint32(data[offset+8+length:entryEnd]) {
+					skipped++
+				} else if mutation, err := parseMutation(body); err != nil {
+					skipped++
+				} else {
+					mutation.end = entryEnd
+					mutations = append(mutations, mutation)
+				}
+				end = entryEnd
+			}
+			offset = entryEnd
+		}
+
+		if next > end {
+			end = next
+		}
+		marker = next
+	}
+
+	return mutations, end, skipped
+}
+
+// parseMutation reads the start of a serialized mutation: the number of partition updates, then
+// per update the table ID, the partition key, the partition flags and the encoding stats starting
+// with the lowest timestamp. The rows that follow depend on the table schema and are not read.
+func parseMutation(body []byte) (commitLogMutation, error) {
+	count, n, err := readUnsignedVInt(body)
+	if err != nil {
+		return commitLogMutation{}, err
+	}
+	if count == 0 || len(body) < n+16 {
+		return commitLogMutation{}, fmt.Errorf("invalid mutation")
+	}
+
+	mutation := commitLogMutation{tableCount: int(count)}
+	copy(mutation.tableID[:], body[n:n+16])
+	body = body[n+16:]
+
+	keyLength, n, err := readUnsignedVInt(body)
+	if err != nil {
+		return commitLogMutation{}, err
+	}
+	if uint64(len(body)-n) < keyLength {
+		return commitLogMutation{}, fmt.Errorf("invalid mutation partition key")
+	}
+	mutation.partitionKey = body[n : n+int(keyLength)]
+	body = body[n+int(keyLength):]
+
+	if len(body) > 0 && body[0]&partitionFlagEmpty == 0 {
+		mutation.partitionDeletion = body[0]&partitionFlagPartitionDeletion != 0
+		if delta, _, err := readUnsignedVInt(body[1:]); err == nil {
+			// Updates without timestamps encode the lowest long, which wraps to a negative timestamp
+			if timestamp := int64(delta) + commitLogTimestampEpoch; timestamp > 0 {
+				mutation.timestamp = timestamp
+			}
+		}
+	}
+
+	return mutation, nil
+}
+
+// readUnsignedVInt reads a Cassandra unsigned variable length integer: the number of leading
+// one bits of the first byte gives the number of extra bytes
+func readUnsignedVInt(data []byte) (uint64, int, error) {
+	if len(data) == 0 {
+		return 0, 0, fmt.Errorf("truncated vint")
+	}
+
+	first := data[0]
+	extra := 0
+	for mask := byte(0x80); extra < 8 && first&mask != 0; mask >>= 1 {
+		extra++
+	}
+	if len(data) < 1+extra {
+		return 0, 0, fmt.Errorf("truncated vint")
+	}
+
+	value := uint64(first) & (0xff >> uint(extra))
+	for i := 1; i <= extra; i++ {
+		value = value<<8 | uint64(data[i])
+	}
+	return value, 1 + extra, nil
+}
+
+// splitPartitionKey splits a serialized partition key into the values of its columns. Composite
+// keys serialize each component as a 2 byte length, the value and an end-of-component byte.
+func splitPartitionKey(key []byte, columns int) ([][]byte, error) {
+	if columns <= 1 {
+		return [][]byte{key}, nil
+	}
+
+	components := make([][]byte, 0, columns)
+	for len(key) > 0 {
+		if len(key) < 2 {
+			return nil, fmt.Errorf("invalid composite partition key")
+		}
+		length := int(binary.BigEndian.Uint16(key[0:2]))
+		if len(key) < 2+length+1 {
+			return nil, fmt.Errorf("invalid composite partition key")
+		}
+		components = append(components, key[2:2+length])
+		key = key[2+length+1:]
+	}
+
+	if len(components) != columns {
+		return nil, fmt.Errorf("partition key has %d components, expected %d", len(components), columns)
+	}
+	return components, nil
+}
+
+// writeCRCInt adds an int to a checksum the way Cassandra does, in big-endian order
+func writeCRCInt(w io.Writer, v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
@@ -0,0 +1,247 @@
+package cassandra
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildCommitLogSegment builds a version 7 segment with one sync section holding the mutations
+func buildCommitLogSegment(id int64, mutations [][]byte) []byte {
+	var data []byte
+	putInt := func(v uint32) {
+		data = binary.BigEndian.AppendUint32(data, v)
+	}
+
+	params := []byte("{}")
+	putInt(7)
+	data = binary.BigEndian.AppendUint64(data, uint64(id))
+	data = binary.BigEndian.AppendUint16(data, uint16(len(params)))
+	data = append(data, params...)
+	crc := crc32.NewIEEE()
+	writeCRCInt(crc, 7)
+	writeCRCInt(crc, uint32(id))
+	writeCRCInt(crc, uint32(id>>32))
+	writeCRCInt(crc, uint32(len(params)))
+	crc.Write(params)
+	putInt(crc.Sum32())
+
+	marker := len(data)
+	data = append(data, make([]byte, commitLogSyncMarkerSize)...)
+	for _, mutation := range mutations {
+		sizeCRC := crc32.NewIEEE()
+		writeCRCInt(sizeCRC, uint32(len(mutation)))
+		putInt(uint32(len(mutation)))
+		putInt(sizeCRC.Sum32())
+		data = append(data, mutation...)
+		sizeCRC.Write(mutation)
+		putInt(sizeCRC.Sum32())
+	}
+
+	markerCRC := crc32.NewIEEE()
+	writeCRCInt(markerCRC, uint32(id))
+	writeCRCInt(markerCRC, uint32(id>>32))
+	writeCRCInt(markerCRC, uint32(marker))
+	binary.BigEndian.PutUint32(data[marker:], uint32(len(data)))
+	binary.BigEndian.PutUint32(data[marker+4:], markerCRC.Sum32())
+	return data
+}
+
+// buildMutation serializes the start of a mutation: the update count, table ID, partition key,
+// partition flags and the lowest timestamp, written 1000 microseconds after the epoch
+func buildMutation(tableID [16]byte, key []byte) []byte {
+	mutation := []byte{1}
+	mutation = append(mutation, tableID[:]...)
+	mutation = append(mutation, byte(len(key)))
+	mutation = append(mutation, key...)
+	mutation = append(mutation, partitionFlagPartitionDeletion, 0x83, 0xe8)
+	// Remainder of the partition update, not read
+	return append(mutation, 0x00, 0x01, 0x02)
+}
+
+func TestReadCommitLogMutations(t *testing.T) {
+	tableID := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	data := buildCommitLogSegment(1700000000001, [][]byte{
+		buildMutation(tableID, []byte("alice")),
+		buildMutation(tableID, []byte("bob")),
+	})
+
+	header, err := parseCommitLogHeader(data)
+	if err != nil {
+		t.Fatalf("unexpected header error: %v", err)
+	}
+	if header.id != 1700000000001 || header.version != 7 {
+		t.Fatalf("unexpected header %+v", header)
+	}
+
+	mutations, end, skipped := readCommitLogMutations(data, header, 0)
+	if skipped != 0 {
+		t.Errorf("expected no skipped entries, got %d", skipped)
+	}
+	if len(mutations) != 2 {
+		t.Fatalf("expected 2 mutations, got %d", len(mutations))
+	}
+	if string(mutations[0].partitionKey) != "alice" || string(mutations[1].partitionKey) != "bob" {
+		t.Errorf("unexpected partition keys %q and %q", mutations[0].partitionKey, mutations[1].partitionKey)
+	}
+	if mutations[0].tableID != tableID || mutations[0].tableCount != 1 {
+		t.Errorf("unexpected table of mutation: %+v", mutations[0])
+	}
+	if !mutations[0].partitionDeletion || mutations[0].timestamp != commitLogTimestampEpoch+1000 {
+		t.Errorf("unexpected flags or timestamp of mutation: %+v", mutations[0])
+	}
+	if end != int64(len(data)) {
+		t.Errorf("expected to read up to %d, got %d", len(data), end)
+	}
+
+	// Resuming after the first mutation only returns the second
+	mutations, _, _ = readCommitLogMutations(data, header, mutations[0].end)
+	if len(mutations) != 1 || string(mutations[0].partitionKey) != "bob" {
+		t.Errorf("expected only the second mutation when resuming, got %+v", mutations)
+	}
+
+	// A corrupted entry is skipped
+	data[len(data)-5] ^= 0xff
+	mutations, _, skipped = readCommitLogMutations(data, header, 0)
+	if len(mutations) != 1 || skipped != 1 {
+		t.Errorf("expected 1 mutation and 1 skipped entry, got %d and %d", len(mutations), skipped)
+	}
+}
+
+func TestReadCommitLogMutations_UnsyncedSection(t *testing.T) {
+	data := buildCommitLogSegment(2, [][]byte{buildMutation([16]byte{}, []byte("k"))})
+	header, err := parseCommitLogHeader(data)
+	if err != nil {
+		t.Fatalf("unexpected header error: %v", err)
+	}
+
+	// Clear the sync marker: the section was not synced yet
+	copy(data[header.size:], make([]byte, commitLogSyncMarkerSize))
+	mutations, end, _ := readCommitLogMutations(data, header, 0)
+	if len(mutations) != 0 || end != 0 {
+		t.Errorf("expected nothing to be read, got %d mutations up to %d", len(mutations), end)
+	}
+}
+
+func TestParseCommitLogHeader_Compressed(t *testing.T) {
+	data := buildCommitLogSegment(3, nil)
+	header, err := parseCommitLogHeader(data)
+	if err != nil || header.size != 4+8+2+2+4 {
+		t.Fatalf("unexpected header %+v, %v", header, err)
+	}
+
+	params := []byte(`{"compressionClass":"LZ4Compressor"}`)
+	compressed := binary.BigEndian.AppendUint32(nil, 7)
+	compressed = binary.BigEndian.AppendUint64(compressed, 3)
+	compressed = binary.BigEndian.AppendUint16(compressed, uint16(len(params)))
+	compressed = append(compressed, params...)
+	crc := crc32.NewIEEE()
+	writeCRCInt(crc, 7)
+	writeCRCInt(crc, 3)
+	writeCRCInt(crc, 0)
+	writeCRCInt(crc, uint32(len(params)))
+	crc.Write(params)
+	compressed = binary.BigEndian.AppendUint32(compressed, crc.Sum32())
+
+	if _, err := parseCommitLogHeader(compressed); err == nil {
+		t.Error("expected compressed segments to be rejected")
+	}
+}
+
+func TestReadUnsignedVInt(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected uint64
+		size     int
+	}{
+		{data: []byte{0x05}, expected: 5, size: 1},
+		{data: []byte{0x7f}, expected: 127, size: 1},
+		{data: []byte{0x80, 0x80}, expected: 128, size: 2},
+		{data: []byte{0xbf, 0xff}, expected: 16383, size: 2},
+		{data: []byte{0xc0, 0x40, 0x00}, expected: 16384, size: 3},
+	}
+
+	for _, tt := range tests {
+		value, size, err := readUnsignedVInt(tt.data)
+		if err != nil || value != tt.expected || size != tt.size {
+			t.Errorf("readUnsignedVInt(%x) = %d, %d, %v; expected %d, %d", tt.data, value, size, err, tt.expected, tt.size)
+		}
+	}
+
+	if _, _, err := readUnsignedVInt([]byte{0x80}); err == nil {
+		t.Error("expected an error for a truncated vint")
+	}
+}
+
+func TestSplitPartitionKey(t *testing.T) {
+	key := []byte{0, 2, 'a', 'b', 0, 0, 1, 'c', 0}
+	components, err := splitPartitionKey(key, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(components) != 2 || string(components[0]) != "ab" || string(components[1]) != "c" {
+		t.Errorf("unexpected components %q", components)
+	}
+
+	if _, err := splitPartitionKey(key, 3); err == nil {
+		t.Error("expected an error for a component count mismatch")
+	}
+	if components, _ := splitPartitionKey([]byte("single"), 1); string(components[0]) != "single" {
+		t.Errorf("expected a single column key to be returned as is, got %q", components)
+	}
+}
+
+func TestListCDCSegments(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"CommitLog-7-20.log":     "",
+		"CommitLog-7-20_cdc.idx": "1024\nCOMPLETED\n",
+		"CommitLog-7-3.log":      "",
+		"CommitLog-7-3_cdc.idx":  "512\n",
+		"CommitLog-7-40.log":     "",
+		"other.txt":              "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	segments, err := listCDCSegments(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Segment 40 has no index yet
+	if len(segments) != 2 || segments[0].id != 3 || segments[1].id != 20 {
+		t.Fatalf("unexpected segments %+v", segments)
+	}
+
+	durable, completed, err := readCDCIndex(segments[0])
+	if err != nil || durable != 512 || completed {
+		t.Errorf("unexpected index of segment 3: %d, %v, %v", durable, completed, err)
+	}
+	durable, completed, err = readCDCIndex(segments[1])
+	if err != nil || durable != 1024 || !completed {
+		t.Errorf("unexpected index of segment 20: %d, %v, %v", durable, completed, err)
+	}
+}
+
+func TestParseCDCPosition(t *testing.T) {
+	position, err := parseCDCPosition("1700000000001:4096")
+	if err != nil || position.segment != 1700000000001 || position.offset != 4096 {
+		t.Errorf("unexpected position %+v, %v", position, err)
+	}
+	if position.String() != "1700000000001:4096" {
+		t.Errorf("unexpected formatted position %s", position)
+	}
+	if !position.before(cdcPosition{segment: 1700000000002}) {
+		t.Error("expected position to be before the next segment")
+	}
+
+	for _, invalid := range []string{"", "12", "a:1", "1:-5"} {
+		if _, err := parseCDCPosition(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
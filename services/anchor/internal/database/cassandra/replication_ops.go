@@ -3,7 +3,9 @@ package cassandra
 import (
 	"context"
 	"fmt"
+	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,6 +16,17 @@ import (
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
 )
 
+const (
+	// commitLogPollInterval is the wait between two reads of the cdc_raw directory
+	commitLogPollInterval = time.Second
+
+	// commitLogCheckpointInterval is how often the position of a replication source is persisted
+	commitLogCheckpointInterval = 10 * time.Second
+
+	// commitLogRetryDelay is the wait after a failed read of the CDC commit log
+	commitLogRetryDelay = 5 * time.Second
+)
+
 // ReplicationOps implements adapter.ReplicationOperator for Cassandra.
 type ReplicationOps struct {
 	conn *Connection
@@ -44,7 +57,9 @@ func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
 	return nil
 }
 
-// Connect creates a new replication connection using Cassandra CDC or polling.
+// Connect creates a new replication connection using Cassandra CDC or polling. CDC is used
+// when the "cdc_raw_directory" option gives the cdc_raw directory of the node, which the
+// anchor reads the commit log segments from; otherwise the tables are polled.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
 	// For Cassandra, table names should be in format "keyspace.table"
 	if len(config.TableNames) == 0 {
@@ -55,6 +70,10 @@ func (r *ReplicationOps) Connect(ctx context.Context, config adapter.Replication
 		).WithContext("error", "at least one table name required")
 	}
 
+	if dir, _ := config.Options["cdc_raw_directory"].(string); dir != "" {
+		return r.connectCommitLog(ctx, config, dir)
+	}
+
 	// Create the replication source
 	source := &CassandraReplicationSource{
 		id:            config.ReplicationID,
@@ -91,14 +110,48 @@ func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{},
 		status["cdc_support"] = "polling_fallback"
 	}
 
+	sources := r.sources()
+	streams := make([]map[string]interface{}, 0, len(sources))
+	for _, s := range sources {
+		streams = append(streams, s.GetStatus())
+	}
+	status["replication_sources"] = streams
+
 	return status, nil
 }
 
-// GetLag returns the replication lag.
+// GetLag returns the replication lag. For commit log CDC sources the lag is reported as the
+// position read so far against the durable end of the CDC commit log.
 func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	sources := r.sources()
+	if len(sources) == 0 {
+		return map[string]interface{}{
+			"database_id": r.conn.id,
+			"lag":         "polling_based", // Polling has inherent lag based on poll interval
+		}, nil
+	}
+
+	lags := make([]map[string]interface{}, 0, len(sources))
+	for _, s := range sources {
+		lag := map[string]interface{}{
+			"source_id":  s.id,
+			"last_error": s.getError(),
+		}
+		position := s.getPosition()
+		lag["position"] = position.String()
+		if current, err := currentCDCPosition(s.directory); err != nil {
+			lag["error"] = err.Error()
+		} else {
+			lag["current_position"] = current.String()
+			lag["caught_up"] = !position.before(current)
+		}
+		lags = append(lags, lag)
+	}
+
 	return map[string]interface{}{
-		"database_id": r.conn.id,
-		"lag":         "polling_based", // Polling has inherent lag based on poll interval
+		"database_id":         r.conn.id,
+		"mechanism":           "commitlog_cdc",
+		"replication_sources": lags,
 	}, nil
 }
 
@@ -470,3 +523,781 @@ func (s *CassandraReplicationSource) getTrackedRowCount() int {
 	}
 	return count
 }
+
+// connectCommitLog creates a replication source reading the CDC commit log segments of a node.
+// CDC is enabled on replicated tables without it unless the "auto_enable_cdc" option is false,
+// and consumed segments are deleted from cdc_raw unless the "cdc_delete_consumed" option is false.
+func (r *ReplicationOps) connectCommitLog(ctx context.Context, config adapter.ReplicationConfig, dir string) (adapter.ReplicationSource, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, adapter.NewConfigurationError(
+			dbcapabilities.Cassandra,
+			"cdc_raw_directory",
+			fmt.Sprintf("%s is not a readable directory", dir),
+		)
+	}
+
+	autoEnable := true
+	if v, ok := config.Options["auto_enable_cdc"].(bool); ok {
+		autoEnable = v
+	}
+	deleteConsumed := true
+	if v, ok := config.Options["cdc_delete_consumed"].(bool); ok {
+		deleteConsumed = v
+	}
+
+	keyspaces, err := r.tableKeyspaces(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Cassandra, "list_tables", err)
+	}
+
+	source := &CassandraCDCReplicationSource{
+		id:             config.ReplicationID,
+		databaseID:     config.DatabaseID,
+		conn:           r.conn,
+		directory:      dir,
+		deleteConsumed: deleteConsumed,
+		tables:         make(map[[16]byte]*cdcTable),
+		keyspaces:      keyspaces,
+		rows:           make(map[string]map[string]map[string]map[string]interface{}),
+		eventHandler:   config.EventHandler,
+	}
+
+	for _, tableName := range config.TableNames {
+		table, err := r.resolveCDCTable(ctx, tableName, autoEnable)
+		if err != nil {
+			return nil, err
+		}
+		source.tables[table.id] = table
+		source.tableOrder = append(source.tableOrder, table)
+	}
+
+	// Set starting position if provided, otherwise start after the changes logged so far
+	if config.StartPosition != "" {
+		if err := source.SetPosition(config.StartPosition); err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Cassandra, "set_start_position", err)
+		}
+	} else {
+		current, err := currentCDCPosition(dir)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Cassandra, "get_current_position", err)
+		}
+		source.position = current
+	}
+	source.savedPosition = source.position
+
+	r.conn.replicationSources.Store(source.id, source)
+	return source, nil
+}
+
+// tableKeyspaces returns the keyspace of every table by table ID
+func (r *ReplicationOps) tableKeyspaces(ctx context.Context) (map[[16]byte]string, error) {
+	keyspaces := make(map[[16]byte]string)
+	iter := r.conn.session.Query("SELECT keyspace_name, id FROM system_schema.tables").WithContext(ctx).Iter()
+
+	var keyspace string
+	var id gocql.UUID
+	for iter.Scan(&keyspace, &id) {
+		keyspaces[id] = keyspace
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return keyspaces, nil
+}
+
+// resolveCDCTable looks up a replicated table, enabling CDC on it when allowed. Table names
+// without a keyspace use the keyspace of the connection.
+func (r *ReplicationOps) resolveCDCTable(ctx context.Context, tableName string, autoEnable bool) (*cdcTable, error) {
+	keyspace, name := r.conn.config.DatabaseName, tableName
+	if i := strings.Index(tableName, "."); i >= 0 {
+		keyspace, name = tableName[:i], tableName[i+1:]
+	}
+	if keyspace == "" {
+		return nil, adapter.NewConfigurationError(
+			dbcapabilities.Cassandra,
+			"table_names",
+			fmt.Sprintf("table %s has no keyspace and the connection has no default keyspace", tableName),
+		)
+	}
+
+	var id gocql.UUID
+	var cdc bool
+	err := r.conn.session.Query(
+		"SELECT id, cdc FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+		keyspace, name,
+	).WithContext(ctx).Scan(&id, &cdc)
+	if err == gocql.ErrNotFound {
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.Cassandra,
+			"resolve_cdc_table",
+			adapter.ErrTableNotFound,
+		).WithContext("table", tableName)
+	}
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Cassandra, "resolve_cdc_table", err)
+	}
+
+	if !cdc {
+		alter := fmt.Sprintf("ALTER TABLE %s.%s WITH cdc = true", QuoteIdentifier(keyspace), QuoteIdentifier(name))
+		if !autoEnable {
+			return nil, adapter.NewDatabaseError(
+				dbcapabilities.Cassandra,
+				"enable_cdc",
+				adapter.ErrConfigurationError,
+			).WithContext("table", tableName).
+				WithContext("error", "CDC is not enabled on the table. Enable with: "+alter)
+		}
+		if err := r.conn.session.Query(alter).WithContext(ctx).Exec(); err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Cassandra, "enable_cdc", err)
+		}
+	}
+
+	metadata, err := r.conn.session.KeyspaceMetadata(keyspace)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Cassandra, "get_table_metadata", err)
+	}
+	tableMetadata, ok := metadata.Tables[name]
+	if !ok {
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.Cassandra,
+			"get_table_metadata",
+			adapter.ErrTableNotFound,
+		).WithContext("table", tableName)
+	}
+
+	table := &cdcTable{
+		keyspace:     keyspace,
+		name:         name,
+		id:           id,
+		partitionKey: tableMetadata.PartitionKey,
+	}
+	for _, column := range tableMetadata.PartitionKey {
+		table.primaryKey = append(table.primaryKey, column.Name)
+	}
+	for _, column := range tableMetadata.ClusteringColumns {
+		table.primaryKey = append(table.primaryKey, column.Name)
+	}
+	for _, columnName := range tableMetadata.OrderedColumns {
+		column := tableMetadata.Columns[columnName]
+		table.columns = append(table.columns, columnName)
+		if column.Kind == gocql.ColumnRegular && hasWriteTime(column.Type) {
+			table.timedColumns = append(table.timedColumns, columnName)
+		}
+	}
+
+	return table, nil
+}
+
+// hasWriteTime reports whether WRITETIME can be selected for columns of a type: it is not
+// available for counters and non-frozen collections and user-defined types
+func hasWriteTime(info gocql.TypeInfo) bool {
+	switch info.Type() {
+	case gocql.TypeCounter, gocql.TypeList, gocql.TypeSet, gocql.TypeMap, gocql.TypeUDT, gocql.TypeCustom:
+		return false
+	}
+	return true
+}
+
+// currentCDCPosition returns the end of the durable part of the CDC commit log
+func currentCDCPosition(dir string) (cdcPosition, error) {
+	segments, err := listCDCSegments(dir)
+	if err != nil || len(segments) == 0 {
+		return cdcPosition{}, err
+	}
+
+	last := segments[len(segments)-1]
+	durable, _, err := readCDCIndex(last)
+	if err != nil {
+		return cdcPosition{}, err
+	}
+	return cdcPosition{segment: last.id, offset: durable}, nil
+}
+
+// sources returns the commit log CDC sources opened on the connection, ordered by source ID
+func (r *ReplicationOps) sources() []*CassandraCDCReplicationSource {
+	var sources []*CassandraCDCReplicationSource
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		sources = append(sources, value.(*CassandraCDCReplicationSource))
+		return true
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].id < sources[j].id })
+	return sources
+}
+
+// cdcTable is a table replicated from the CDC commit log
+type cdcTable struct {
+	keyspace     string
+	name         string
+	id           [16]byte
+	partitionKey []*gocql.ColumnMetadata
+	primaryKey   []string
+	columns      []string
+	timedColumns []string // Regular columns whose WRITETIME can be selected
+}
+
+func (t *cdcTable) qualifiedName() string {
+	return t.keyspace + "." + t.name
+}
+
+// CassandraCDCReplicationSource implements adapter.ReplicationSource by reading the commit log
+// segments Cassandra keeps in the cdc_raw directory for tables with cdc = true. The rows of a
+// mutation cannot be decoded without the serialization state of the node, so each partition
+// changed by a mutation is read back: rows written at or after the mutation are passed on as
+// inserts or updates, and rows passed on before that are gone as deletes.
+//
+// The position is the segment ID and the offset after the last mutation passed on. Only the
+// mutations written to the node owning the cdc_raw directory are read.
+type CassandraCDCReplicationSource struct {
+	id             string
+	databaseID     string
+	conn           *Connection
+	directory      string
+	deleteConsumed bool
+	tables         map[[16]byte]*cdcTable // Table ID -> replicated table
+	tableOrder     []*cdcTable
+	keyspaces      map[[16]byte]string // Table ID -> keyspace, for all tables
+	active         int32
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+	mu             sync.RWMutex
+	position       cdcPosition
+	savedPosition  cdcPosition
+	eventCount     int64
+	skippedCount   int64
+	lastError      string
+	eventHandler   func(map[string]interface{})
+	checkpointFn   func(context.Context, string) error
+
+	// Primary key values of the rows passed on, by table, partition key and row key. Only
+	// used by the run goroutine.
+	rows map[string]map[string]map[string]map[string]interface{}
+}
+
+// GetSourceID returns the replication source ID.
+func (s *CassandraCDCReplicationSource) GetSourceID() string {
+	return s.id
+}
+
+// GetDatabaseID returns the database ID.
+func (s *CassandraCDCReplicationSource) GetDatabaseID() string {
+	return s.databaseID
+}
+
+// GetStatus returns the replication source status.
+func (s *CassandraCDCReplicationSource) GetStatus() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tables := make([]string, 0, len(s.tableOrder))
+	for _, table := range s.tableOrder {
+		tables = append(tables, table.qualifiedName())
+	}
+
+	return map[string]interface{}{
+		"source_id":       s.id,
+		"database_id":     s.databaseID,
+		"active":          s.IsActive(),
+		"mechanism":       "commitlog_cdc",
+		"cdc_directory":   s.directory,
+		"tables":          tables,
+		"position":        s.position.String(),
+		"event_count":     atomic.LoadInt64(&s.eventCount),
+		"skipped_entries": atomic.LoadInt64(&s.skippedCount),
+		"last_error":      s.lastError,
+	}
+}
+
+// GetMetadata returns the replication source metadata.
+func (s *CassandraCDCReplicationSource) GetMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"source_type":     "commitlog_cdc",
+		"database_type":   "cassandra",
+		"replication_id":  s.id,
+		"database_id":     s.databaseID,
+		"supported_ops":   []string{"INSERT", "UPDATE", "DELETE"},
+		"resume_capable":  true,
+		"transaction_log": true,
+	}
+}
+
+// IsActive returns whether the replication source is active.
+func (s *CassandraCDCReplicationSource) IsActive() bool {
+	return atomic.LoadInt32(&s.active) == 1
+}
+
+// Start starts reading the CDC commit log.
+func (s *CassandraCDCReplicationSource) Start() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 0, 1) {
+		return adapter.NewDatabaseError(
+			dbcapabilities.Cassandra,
+			"start_replication",
+			adapter.ErrInvalidConfiguration,
+		).WithContext("error", "replication source is already active")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.run(ctx)
+	}()
+	return nil
+}
+
+// Stop stops the replication source and saves its final position.
+func (s *CassandraCDCReplicationSource) Stop() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 1, 0) {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	s.checkpoint(context.Background())
+	return nil
+}
+
+// Close stops the replication source and releases it.
+func (s *CassandraCDCReplicationSource) Close() error {
+	err := s.Stop()
+	s.conn.replicationSources.Delete(s.id)
+	return err
+}
+
+// GetPosition returns the current replication position as "segment:offset".
+func (s *CassandraCDCReplicationSource) GetPosition() (string, error) {
+	return s.getPosition().String(), nil
+}
+
+// SetPosition sets the starting replication position for resume.
+func (s *CassandraCDCReplicationSource) SetPosition(position string) error {
+	if position == "" {
+		return nil
+	}
+
+	parsed, err := parseCDCPosition(position)
+	if err != nil {
+		return err
+	}
+	s.setPosition(parsed)
+	return nil
+}
+
+// SaveCheckpoint persists the current replication position.
+func (s *CassandraCDCReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
+	s.mu.RLock()
+	fn := s.checkpointFn
+	s.mu.RUnlock()
+
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, position)
+}
+
+// SetCheckpointFunc sets the callback function for persisting checkpoints.
+func (s *CassandraCDCReplicationSource) SetCheckpointFunc(fn func(context.Context, string) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpointFn = fn
+}
+
+// run reads the CDC commit log until the source is stopped
+func (s *CassandraCDCReplicationSource) run(ctx context.Context) {
+	lastCheckpoint := time.Now()
+	for {
+		wait := commitLogPollInterval
+		if err := s.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.setError(err)
+			wait = commitLogRetryDelay
+		}
+
+		if time.Since(lastCheckpoint) >= commitLogCheckpointInterval {
+			s.checkpoint(ctx)
+			lastCheckpoint = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// poll passes on the mutations written to the CDC commit log after the current position.
+// Segments are read in order; a segment still being written holds back the ones after it.
+func (s *CassandraCDCReplicationSource) poll(ctx context.Context) error {
+	segments, err := listCDCSegments(s.directory)
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		position := s.getPosition()
+		if segment.id < position.segment {
+			continue
+		}
+		from := int64(0)
+		if segment.id == position.segment {
+			from = position.offset
+		}
+
+		durable, completed, err := readCDCIndex(segment)
+		if err != nil {
+			return err
+		}
+		if durable > from {
+			if err := s.readSegment(ctx, segment, from, durable); err != nil {
+				return err
+			}
+		}
+		if !completed {
+			return nil
+		}
+
+		// All of a completed segment was read
+		if end := (cdcPosition{segment: segment.id, offset: durable}); s.getPosition().before(end) {
+			s.setPosition(end)
+		}
+	}
+
+	return nil
+}
+
+// readSegment passes on the mutations of a segment between from and its durable end
+func (s *CassandraCDCReplicationSource) readSegment(ctx context.Context, segment cdcSegment, from, durable int64) error {
+	data, err := readCDCSegment(segment, durable)
+	if err != nil {
+		return err
+	}
+
+	header, err := parseCommitLogHeader(data)
+	if err != nil {
+		return fmt.Errorf("error reading CDC segment %s: %v", segment.path, err)
+	}
+	if header.id != segment.id {
+		return fmt.Errorf("CDC segment %s has segment ID %d", segment.path, header.id)
+	}
+
+	mutations, end, skipped := readCommitLogMutations(data, header, from)
+	if skipped > 0 {
+		atomic.AddInt64(&s.skippedCount, int64(skipped))
+		s.setError(fmt.Errorf("%d corrupted entries of CDC segment %s were skipped", skipped, segment.path))
+	}
+
+	for _, mutation := range mutations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		position := cdcPosition{segment: segment.id, offset: mutation.end}
+		if err := s.processMutation(ctx, mutation, position); err != nil {
+			return err
+		}
+		s.setPosition(position)
+	}
+
+	if end > from {
+		s.setPosition(cdcPosition{segment: segment.id, offset: end})
+	}
+	return nil
+}
+
+// processMutation passes on the changes of the partition changed by a mutation. A mutation
+// changing several tables of a keyspace only identifies the first, so the partition is also
+// read from the other replicated tables of that keyspace.
+func (s *CassandraCDCReplicationSource) processMutation(ctx context.Context, mutation commitLogMutation, position cdcPosition) error {
+	if table, ok := s.tables[mutation.tableID]; ok {
+		if err := s.emitPartition(ctx, table, mutation, true, position); err != nil {
+			return err
+		}
+	}
+
+	if mutation.tableCount < 2 {
+		return nil
+	}
+	keyspace, ok := s.keyspaces[mutation.tableID]
+	if !ok {
+		return nil
+	}
+	for _, table := range s.tableOrder {
+		if table.id == mutation.tableID || table.keyspace != keyspace {
+			continue
+		}
+		if err := s.emitPartition(ctx, table, mutation, false, position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitPartition reads a changed partition back and passes on its rows written at or after the
+// mutation, and the rows passed on before that are no longer present. certain is false when
+// the mutation may not have changed the table; an empty partition is then not reported.
+func (s *CassandraCDCReplicationSource) emitPartition(ctx context.Context, table *cdcTable, mutation commitLogMutation, certain bool, position cdcPosition) error {
+	keyValues, err := decodePartitionKey(table, mutation.partitionKey)
+	if err != nil {
+		if certain {
+			s.setError(fmt.Errorf("error decoding partition key of %s: %v", table.qualifiedName(), err))
+		}
+		return nil
+	}
+
+	selectors := make([]string, 0, len(table.columns)+len(table.timedColumns))
+	for _, column := range table.columns {
+		selectors = append(selectors, QuoteIdentifier(column))
+	}
+	for i, column := range table.timedColumns {
+		selectors = append(selectors, fmt.Sprintf("WRITETIME(%s) AS %s", QuoteIdentifier(column), writeTimeAlias(i)))
+	}
+	where := make([]string, 0, len(table.partitionKey))
+	args := make([]interface{}, 0, len(table.partitionKey))
+	for _, column := range table.partitionKey {
+		where = append(where, QuoteIdentifier(column.Name)+" = ?")
+		args = append(args, keyValues[column.Name])
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s.%s WHERE %s",
+		strings.Join(selectors, ", "),
+		QuoteIdentifier(table.keyspace),
+		QuoteIdentifier(table.name),
+		strings.Join(where, " AND "))
+
+	partition := string(mutation.partitionKey)
+	tableRows := s.rows[table.qualifiedName()]
+	if tableRows == nil {
+		tableRows = make(map[string]map[string]map[string]interface{})
+		s.rows[table.qualifiedName()] = tableRows
+	}
+	known := tableRows[partition]
+
+	present := make(map[string]map[string]interface{})
+	iter := s.conn.session.Query(query, args...).WithContext(ctx).Iter()
+	for {
+		row := make(map[string]interface{})
+		if !iter.MapScan(row) {
+			break
+		}
+
+		// Rows last written before the mutation were not changed by it
+		changedAt, unchangedColumns := int64(0), false
+		for i := range table.timedColumns {
+			alias := writeTimeAlias(i)
+			if writeTime, ok := row[alias].(int64); ok && writeTime != 0 {
+				if writeTime > changedAt {
+					changedAt = writeTime
+				}
+				if writeTime < mutation.timestamp {
+					unchangedColumns = true
+				}
+			}
+			delete(row, alias)
+		}
+		for column, value := range row {
+			row[column] = ConvertCassandraValueToGo(value)
+		}
+
+		rowKey := cdcRowKey(row, table.primaryKey)
+		primaryKey := primaryKeyValues(row, table.primaryKey)
+		present[rowKey] = primaryKey
+		if mutation.timestamp != 0 && changedAt != 0 && changedAt < mutation.timestamp {
+			continue
+		}
+
+		// Rows not passed on before are inserts, unless some of their columns predate the mutation
+		if _, ok := known[rowKey]; ok || (mutation.timestamp != 0 && unchangedColumns) || !certain {
+			s.emit(table, "UPDATE", row, primaryKey, position)
+		} else {
+			s.emit(table, "INSERT", row, nil, position)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("error reading partition of %s: %v", table.qualifiedName(), err)
+	}
+
+	knownKeys := make([]string, 0, len(known))
+	for rowKey := range known {
+		knownKeys = append(knownKeys, rowKey)
+	}
+	sort.Strings(knownKeys)
+	for _, rowKey := range knownKeys {
+		if _, ok := present[rowKey]; !ok {
+			s.emit(table, "DELETE", nil, known[rowKey], position)
+		}
+	}
+
+	if len(present) == 0 && len(known) == 0 && certain {
+		oldData := make(map[string]interface{}, len(keyValues))
+		for column, value := range keyValues {
+			oldData[column] = ConvertCassandraValueToGo(value)
+		}
+		s.emit(table, "DELETE", nil, oldData, position)
+	}
+
+	if len(present) == 0 {
+		delete(tableRows, partition)
+	} else {
+		tableRows[partition] = present
+	}
+	return nil
+}
+
+// emit passes on a row change
+func (s *CassandraCDCReplicationSource) emit(table *cdcTable, operation string, data, oldData map[string]interface{}, position cdcPosition) {
+	event := map[string]interface{}{
+		"table_name":  table.name,
+		"keyspace":    table.keyspace,
+		"operation":   operation,
+		"position":    position.String(),
+		"database_id": s.databaseID,
+	}
+	if data != nil {
+		event["data"] = data
+	}
+	if oldData != nil {
+		event["old_data"] = oldData
+	}
+
+	atomic.AddInt64(&s.eventCount, 1)
+	if s.eventHandler != nil {
+		s.eventHandler(event)
+	}
+}
+
+// checkpoint saves the current position if it changed since the last checkpoint, then deletes
+// the segments consumed by all commit log sources of the connection reading the same directory
+func (s *CassandraCDCReplicationSource) checkpoint(ctx context.Context) {
+	position := s.getPosition()
+
+	s.mu.RLock()
+	unchanged := position == s.savedPosition
+	s.mu.RUnlock()
+
+	if !unchanged {
+		if err := s.SaveCheckpoint(ctx, position.String()); err != nil {
+			s.setError(fmt.Errorf("error saving checkpoint: %v", err))
+			return
+		}
+		s.mu.Lock()
+		s.savedPosition = position
+		s.mu.Unlock()
+	}
+
+	if s.deleteConsumed {
+		if err := s.removeConsumedSegments(); err != nil {
+			s.setError(fmt.Errorf("error deleting consumed CDC segments: %v", err))
+		}
+	}
+}
+
+// removeConsumedSegments deletes the completed segments before the saved positions of all
+// commit log sources of the connection reading the same directory. Cassandra rejects writes to
+// CDC tables once cdc_raw exceeds cdc_total_space, so consumed segments must be deleted.
+func (s *CassandraCDCReplicationSource) removeConsumedSegments() error {
+	consumed := s.getSavedPosition()
+	s.conn.replicationSources.Range(func(_, value interface{}) bool {
+		other := value.(*CassandraCDCReplicationSource)
+		if other != s && other.directory == s.directory {
+			if saved := other.getSavedPosition(); saved.before(consumed) {
+				consumed = saved
+			}
+		}
+		return true
+	})
+
+	segments, err := listCDCSegments(s.directory)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment.id > consumed.segment {
+			break
+		}
+		durable, completed, err := readCDCIndex(segment)
+		if err != nil {
+			return err
+		}
+		if !completed || (segment.id == consumed.segment && consumed.offset < durable) {
+			break
+		}
+		if err := removeCDCSegment(segment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CassandraCDCReplicationSource) getPosition() cdcPosition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.position
+}
+
+func (s *CassandraCDCReplicationSource) setPosition(position cdcPosition) {
+	s.mu.Lock()
+	s.position = position
+	s.mu.Unlock()
+}
+
+func (s *CassandraCDCReplicationSource) getSavedPosition() cdcPosition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.savedPosition
+}
+
+func (s *CassandraCDCReplicationSource) setError(err error) {
+	s.mu.Lock()
+	s.lastError = err.Error()
+	s.mu.Unlock()
+}
+
+func (s *CassandraCDCReplicationSource) getError() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastError
+}
+
+// decodePartitionKey decodes the serialized partition key of a table into its column values
+func decodePartitionKey(table *cdcTable, key []byte) (map[string]interface{}, error) {
+	components, err := splitPartitionKey(key, len(table.partitionKey))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(components))
+	for i, column := range table.partitionKey {
+		value := column.Type.New()
+		if err := gocql.Unmarshal(column.Type, components[i], value); err != nil {
+			return nil, fmt.Errorf("error decoding column %s: %v", column.Name, err)
+		}
+		values[column.Name] = reflect.ValueOf(value).Elem().Interface()
+	}
+	return values, nil
+}
+
+// writeTimeAlias is the alias of the i-th selected WRITETIME
+func writeTimeAlias(i int) string {
+	return fmt.Sprintf("redb_writetime_%d", i)
+}
+
+// cdcRowKey identifies a row of a partition by its primary key values
+func cdcRowKey(row map[string]interface{}, primaryKey []string) string {
+	parts := make([]string, 0, len(primaryKey))
+	for _, column := range primaryKey {
+		parts = append(parts, fmt.Sprintf("%v", row[column]))
+	}
+	return strings.Join(parts, ":")
+}
+
+// primaryKeyValues returns the primary key columns of a row
+func primaryKeyValues(row map[string]interface{}, primaryKey []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(primaryKey))
+	for _, column := range primaryKey {
+		values[column] = row[column]
+	}
+	return values
+}
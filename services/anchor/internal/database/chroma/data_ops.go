@@ -67,6 +67,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.Chroma, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Chroma, "fetch stream", "not yet implemented")
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.client, query, args...)
 	if err != nil {
@@ -73,6 +73,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.conn, query, args...)
 	if err != nil {
@@ -2,6 +2,7 @@ package cockroach
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -160,6 +161,9 @@ type Connection struct {
 	config    adapter.ConnectionConfig
 	adapter   *Adapter
 	connected int32
+
+	// replicationSources holds the changefeeds opened on this connection, by source ID
+	replicationSources sync.Map
 }
 
 // ID returns the connection identifier.
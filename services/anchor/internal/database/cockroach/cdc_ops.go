@@ -8,8 +8,8 @@ import (
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
@@ -70,12 +70,13 @@ func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]int
 	if updated, ok := rawEvent["updated"].(string); ok {
 		event.Metadata["updated"] = updated
 		event.LSN = updated // Use updated timestamp as LSN
-		// Try to parse the timestamp
-		if t, err := time.Parse(time.RFC3339Nano, updated); err == nil {
+		// The updated timestamp is an HLC timestamp (wall time in nanoseconds and logical counter)
+		if t, err := parseHLCTimestamp(updated); err == nil {
 			event.Timestamp = t
 		}
 	}
-	if key, ok := rawEvent["key"].(map[string]interface{}); ok {
+	// The key holds the primary key values of the row in key column order
+	if key, ok := rawEvent["key"]; ok && key != nil {
 		event.Metadata["key"] = key
 	}
 
@@ -329,7 +330,7 @@ func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]inte
 	var grpcConn *grpc.ClientConn
 	if transformationServiceEndpoint != "" {
 		// Connect to transformation service
-		conn, err := grpc.Dial(transformationServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
 		if err == nil {
 			transformClient = transformationv1.NewTransformationServiceClient(conn)
 			grpcConn = conn
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/redbco/redb-open/pkg/encryption"
@@ -461,6 +462,54 @@ func ExecuteQuery(db interface{}, query string, args ...interface{}) ([]interfac
 	return results, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction and returns its columns
+// and at most maxRows rows. truncated reports whether the query returned more rows.
+func ExecuteReadOnlyQuery(ctx context.Context, pool *pgxpool.Pool, query string, maxRows int) (columns []string, results []map[string]interface{}, truncated bool, err error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	// Nothing can be written, so the transaction is always rolled back
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to execute cockroach query: %w", err)
+	}
+	defer rows.Close()
+
+	fieldDescriptions := rows.FieldDescriptions()
+	columns = make([]string, len(fieldDescriptions))
+	for i, desc := range fieldDescriptions {
+		columns[i] = string(desc.Name)
+	}
+
+	results = []map[string]interface{}{}
+	for rows.Next() {
+		if len(results) == maxRows {
+			truncated = true
+			break
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("failed to scan cockroach result: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, colName := range columns {
+			row[colName] = values[i]
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, fmt.Errorf("cockroach rows iteration error: %w", err)
+	}
+
+	return columns, results, truncated, nil
+}
+
 // ExecuteCountQuery executes a count query and returns the result
 func ExecuteCountQuery(db interface{}, query string) (int64, error) {
 	pool, ok := db.(*pgxpool.Pool)
@@ -115,6 +115,81 @@ func InsertData(pool *pgxpool.Pool, tableName string, data []map[string]interfac
 	return totalRowsAffected, nil
 }
 
+// UpsertData inserts rows into a table, updating the existing rows whose unique columns match
+func UpsertData(ctx context.Context, pool *pgxpool.Pool, tableName string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if len(uniqueColumns) == 0 {
+		return 0, fmt.Errorf("upsert into %s requires at least one unique column", tableName)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	columns := make([]string, 0, len(data[0]))
+	for col := range data[0] {
+		columns = append(columns, col)
+	}
+
+	placeholders := make([]string, len(columns))
+	quotedColumns := make([]string, len(columns))
+	updateSet := make([]string, 0, len(columns))
+	for i, col := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		quotedColumns[i] = QuoteIdentifier(col)
+		if !containsColumn(uniqueColumns, col) {
+			updateSet = append(updateSet, fmt.Sprintf("%s = excluded.%s", QuoteIdentifier(col), QuoteIdentifier(col)))
+		}
+	}
+
+	// Rows made only of unique columns have nothing to update
+	action := "DO NOTHING"
+	if len(updateSet) > 0 {
+		action = "DO UPDATE SET " + strings.Join(updateSet, ", ")
+	}
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+		QuoteIdentifier(tableName),
+		strings.Join(quotedColumns, ", "),
+		strings.Join(placeholders, ", "),
+		strings.Join(QuoteStringSlice(uniqueColumns), ", "),
+		action,
+	)
+
+	var totalRowsAffected int64
+	for _, row := range data {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+
+		result, err := tx.Exec(ctx, query, values...)
+		if err != nil {
+			return 0, err
+		}
+		totalRowsAffected += result.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return totalRowsAffected, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, col := range columns {
+		if col == column {
+			return true
+		}
+	}
+	return false
+}
+
 // WipeDatabase removes all data from the database
 func WipeDatabase(pool *pgxpool.Pool) error {
 	// Get all tables in the public schema
@@ -1,97 +1,125 @@
 package cockroach
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	"log"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// CreateReplicationSource sets up a replication source using CockroachDB's changefeed
-func CreateReplicationSource(db *sql.DB, tableName string, databaseID string, eventHandler func(map[string]interface{})) (*CockroachReplicationSourceDetails, error) {
-	// Generate unique ID for the changefeed
-	changefeedID := fmt.Sprintf("feed_%s_%s", databaseID, dbclient.GenerateUniqueID())
-
-	// For simplicity, we'll use a file sink in this example
-	// In a real implementation, you might use Kafka, cloud storage, or webhook
-	sinkURI := fmt.Sprintf("file:///tmp/changefeed_%s", changefeedID)
-
-	// Create the changefeed
-	query := fmt.Sprintf(
-		"CREATE CHANGEFEED FOR TABLE %s INTO '%s' WITH updated, resolved='10s'",
-		tableName, sinkURI)
-
-	_, err := db.Exec(query)
+// CheckChangefeedPrerequisites checks that rangefeeds, which changefeeds are built on, are enabled
+func CheckChangefeedPrerequisites(pool *pgxpool.Pool) error {
+	var enabled bool
+	err := pool.QueryRow(context.Background(), "SHOW CLUSTER SETTING kv.rangefeed.enabled").Scan(&enabled)
 	if err != nil {
-		return nil, fmt.Errorf("error creating changefeed: %v", err)
+		return fmt.Errorf("error checking kv.rangefeed.enabled: %v", err)
 	}
-
-	details := &CockroachReplicationSourceDetails{
-		ChangefeedID:   changefeedID,
-		TableName:      tableName,
-		DatabaseID:     databaseID,
-		SinkURI:        sinkURI,
-		ResolvedOption: "10s",
+	if !enabled {
+		return fmt.Errorf("kv.rangefeed.enabled is false but must be true for changefeeds. Please run SET CLUSTER SETTING kv.rangefeed.enabled = true")
 	}
-
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
-
-	return details, nil
+	return nil
 }
 
-// ReconnectToReplicationSource reconnects to an existing replication source
-func ReconnectToReplicationSource(db *sql.DB, details *CockroachReplicationSourceDetails, eventHandler func(map[string]interface{})) error {
-	// Verify that the changefeed still exists
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT job_id FROM [SHOW JOBS] WHERE description LIKE '%changefeed%' AND description LIKE $1)",
-		"%"+details.ChangefeedID+"%").Scan(&exists)
+// ListChangefeedJobs lists the changefeed jobs of the cluster
+func ListChangefeedJobs(pool *pgxpool.Pool) ([]map[string]interface{}, error) {
+	rows, err := pool.Query(context.Background(), `
+		SELECT job_id, job_type, description, status, created
+		FROM [SHOW JOBS]
+		WHERE job_type = 'CHANGEFEED'
+		ORDER BY created DESC
+	`)
 	if err != nil {
-		return fmt.Errorf("error checking changefeed: %v", err)
+		return nil, fmt.Errorf("error querying changefeed jobs: %v", err)
 	}
-
-	if !exists {
-		return fmt.Errorf("changefeed %s does not exist", details.ChangefeedID)
+	defer rows.Close()
+
+	var jobs []map[string]interface{}
+	for rows.Next() {
+		var jobID int64
+		var jobType, description, status string
+		var created time.Time
+		if err := rows.Scan(&jobID, &jobType, &description, &status, &created); err != nil {
+			return nil, fmt.Errorf("error scanning changefeed job: %v", err)
+		}
+		jobs = append(jobs, map[string]interface{}{
+			"job_id":      jobID,
+			"job_type":    jobType,
+			"description": description,
+			"status":      status,
+			"created":     created,
+		})
 	}
 
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
+	return jobs, rows.Err()
+}
 
+// CancelChangefeedJob cancels a changefeed job
+func CancelChangefeedJob(pool *pgxpool.Pool, jobID string) error {
+	id, err := strconv.ParseInt(jobID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid changefeed job ID %q: %v", jobID, err)
+	}
+	if _, err := pool.Exec(context.Background(), "CANCEL JOB $1", id); err != nil {
+		return fmt.Errorf("error cancelling changefeed job %d: %v", id, err)
+	}
 	return nil
 }
 
-func listenForReplicationEvents(db *sql.DB, details *CockroachReplicationSourceDetails, eventHandler func(map[string]interface{})) {
-	for {
-		changes, err := getReplicationChanges(db, details.SinkURI)
-		if err != nil {
-			log.Printf("Error getting replication changes: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		for _, change := range changes {
-			event := map[string]interface{}{
-				"table":     details.TableName,
-				"operation": change.Operation,
-				"data":      change.Data,
-				"old_data":  change.OldData,
-			}
-			eventHandler(event)
-		}
+// buildChangefeedQuery builds the core changefeed statement for the given tables. The
+// changefeed emits the before and after image of every row change and a resolved timestamp
+// every resolvedInterval. Without a cursor the changefeed starts from the current time
+// unless initialScan is set; with a cursor it resumes after that timestamp.
+func buildChangefeedQuery(tableNames []string, cursor string, resolvedInterval time.Duration, initialScan bool) string {
+	targets := make([]string, len(tableNames))
+	for i, t := range tableNames {
+		targets[i] = "TABLE " + quoteTableName(t)
+	}
 
-		time.Sleep(100 * time.Millisecond)
+	options := []string{
+		"updated",
+		"diff",
+		"format = json",
+		"envelope = wrapped",
+		fmt.Sprintf("resolved = '%s'", resolvedInterval),
+	}
+	if cursor != "" {
+		options = append(options, fmt.Sprintf("cursor = '%s'", cursor))
+	} else if !initialScan {
+		options = append(options, "no_initial_scan")
 	}
-}
 
-func getReplicationChanges(db *sql.DB, sinkURI string) ([]CockroachReplicationChange, error) {
-	// In a real implementation, you would read from the sink (file, Kafka, etc.)
-	// This is a simplified example that simulates reading changes
+	return fmt.Sprintf("EXPERIMENTAL CHANGEFEED FOR %s WITH %s",
+		strings.Join(targets, ", "), strings.Join(options, ", "))
+}
 
-	// For file sink, you might read the file content
-	// For this example, we'll return an empty slice
-	// In a real implementation, you would parse the changefeed output
+// quoteTableName quotes a possibly schema-qualified table name
+func quoteTableName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
 
-	return []CockroachReplicationChange{}, nil
+// parseHLCTimestamp parses a changefeed timestamp such as "1700000000000000000.0000000001",
+// the wall time in nanoseconds followed by the logical counter
+func parseHLCTimestamp(ts string) (time.Time, error) {
+	wall := ts
+	logical := ""
+	if i := strings.IndexByte(ts, '.'); i >= 0 {
+		wall, logical = ts[:i], ts[i+1:]
+	}
+	nanos, err := strconv.ParseInt(wall, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid changefeed timestamp %q", ts)
+	}
+	if logical != "" {
+		if _, err := strconv.ParseUint(logical, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid changefeed timestamp %q", ts)
+		}
+	}
+	return time.Unix(0, nanos).UTC(), nil
 }
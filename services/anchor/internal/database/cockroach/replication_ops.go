@@ -4,16 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
 )
 
+const (
+	// defaultResolvedInterval is how often changefeeds emit resolved timestamps, which
+	// are also the checkpoints a changefeed resumes from
+	defaultResolvedInterval = 10 * time.Second
+
+	// changefeedRetryDelay is the wait before a failed changefeed is restarted
+	changefeedRetryDelay = 5 * time.Second
+)
+
 // ReplicationOps implements adapter.ReplicationOperator for CockroachDB.
 type ReplicationOps struct {
 	conn *Connection
@@ -32,38 +40,49 @@ func (r *ReplicationOps) GetSupportedMechanisms() []string {
 
 // CheckPrerequisites checks if replication prerequisites are met.
 func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
-	// Check CockroachDB version to ensure changefeed support
-	var version string
-	err := r.conn.pool.QueryRow(ctx, "SELECT version()").Scan(&version)
-	if err != nil {
+	// CockroachDB Cloud always has rangefeeds enabled and does not expose the setting
+	if r.conn.config.DatabaseVendor == "cockroach-cloud" {
+		return nil
+	}
+	if err := CheckChangefeedPrerequisites(r.conn.pool); err != nil {
 		return adapter.WrapError(dbcapabilities.CockroachDB, "check_replication_prerequisites", err)
 	}
-
-	// CockroachDB changefeeds are available in all versions (core changefeeds are experimental)
-	// Enterprise changefeeds require a license
-	// For now, we'll assume prerequisites are met
 	return nil
 }
 
-// Connect creates a new replication connection using CockroachDB Changefeeds.
+// Connect creates a new replication connection using a core changefeed. The changefeed
+// streams the row changes of the configured tables over a connection of the pool.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
-	// Create the replication source
-	source := &CockroachDBReplicationSource{
-		id:         config.ReplicationID,
-		databaseID: config.DatabaseID,
-		pool:       r.conn.pool,
-		config:     config,
-		active:     0,
-		stopChan:   make(chan struct{}),
-		cursor:     "",
-	}
-
-	// Wrap the event handler to match the expected signature
-	if config.EventHandler != nil {
-		source.eventHandler = func(event map[string]interface{}) error {
-			config.EventHandler(event)
-			return nil
+	if len(config.TableNames) == 0 {
+		return nil, adapter.NewConfigurationError(
+			dbcapabilities.CockroachDB,
+			"table_names",
+			"at least one table is required for a changefeed",
+		)
+	}
+
+	resolvedInterval := defaultResolvedInterval
+	if v, ok := config.Options["resolved_interval"].(string); ok && v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, adapter.NewConfigurationError(
+				dbcapabilities.CockroachDB,
+				"resolved_interval",
+				fmt.Sprintf("invalid resolved interval %q", v),
+			)
 		}
+		resolvedInterval = d
+	}
+	initialScan, _ := config.Options["initial_scan"].(bool)
+
+	source := &CockroachDBReplicationSource{
+		id:               config.ReplicationID,
+		databaseID:       config.DatabaseID,
+		conn:             r.conn,
+		tableNames:       append([]string(nil), config.TableNames...),
+		resolvedInterval: resolvedInterval,
+		initialScan:      initialScan,
+		eventHandler:     config.EventHandler,
 	}
 
 	// Set starting position if provided
@@ -73,66 +92,73 @@ func (r *ReplicationOps) Connect(ctx context.Context, config adapter.Replication
 		}
 	}
 
+	r.conn.replicationSources.Store(source.id, source)
 	return source, nil
 }
 
 // GetStatus returns the replication status.
 func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{}, error) {
+	sources := r.sources()
+	changefeeds := make([]map[string]interface{}, 0, len(sources))
+	for _, s := range sources {
+		changefeeds = append(changefeeds, s.GetStatus())
+	}
+
 	return map[string]interface{}{
-		"database_id": r.conn.id,
-		"status":      "not_implemented", // TODO: Implement actual status retrieval
+		"database_id":       r.conn.id,
+		"mechanism":         "core_changefeed",
+		"changefeeds":       changefeeds,
+		"total_changefeeds": len(changefeeds),
+		"status":            "active",
 	}, nil
 }
 
-// GetLag returns the replication lag.
+// GetLag returns the replication lag of every changefeed, measured from its last
+// resolved timestamp: all changes up to that timestamp have been emitted.
 func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	now := time.Now()
+	lags := make([]map[string]interface{}, 0)
+	var maxLag time.Duration
+	for _, s := range r.sources() {
+		resolved := s.resolvedAt()
+		if resolved.IsZero() {
+			lags = append(lags, map[string]interface{}{
+				"source_id": s.id,
+				"resolved":  false,
+			})
+			continue
+		}
+		lag := now.Sub(resolved)
+		if lag > maxLag {
+			maxLag = lag
+		}
+		lags = append(lags, map[string]interface{}{
+			"source_id":          s.id,
+			"resolved":           true,
+			"resolved_timestamp": resolved.Format(time.RFC3339Nano),
+			"lag_seconds":        lag.Seconds(),
+		})
+	}
+
 	return map[string]interface{}{
-		"database_id": r.conn.id,
-		"lag":         "not_implemented", // TODO: Implement actual lag calculation
+		"database_id":     r.conn.id,
+		"changefeeds":     lags,
+		"max_lag_seconds": maxLag.Seconds(),
 	}, nil
 }
 
-// ListSlots is not directly applicable for CockroachDB Changefeeds.
+// ListSlots lists the changefeed jobs, CockroachDB's counterpart of replication slots.
 func (r *ReplicationOps) ListSlots(ctx context.Context) ([]map[string]interface{}, error) {
-	// CockroachDB changefeeds don't use slots, but we can list jobs
-	query := `
-		SELECT job_id, job_type, description, status, created
-		FROM [SHOW JOBS]
-		WHERE job_type = 'CHANGEFEED'
-		ORDER BY created DESC
-	`
-	rows, err := r.conn.pool.Query(ctx, query)
+	jobs, err := ListChangefeedJobs(r.conn.pool)
 	if err != nil {
 		return nil, adapter.WrapError(dbcapabilities.CockroachDB, "list_changefeed_jobs", err)
 	}
-	defer rows.Close()
-
-	var jobs []map[string]interface{}
-	for rows.Next() {
-		var jobID int64
-		var jobType, description, status string
-		var created time.Time
-		if err := rows.Scan(&jobID, &jobType, &description, &status, &created); err != nil {
-			return nil, adapter.WrapError(dbcapabilities.CockroachDB, "list_changefeed_jobs", err)
-		}
-		jobs = append(jobs, map[string]interface{}{
-			"job_id":      jobID,
-			"job_type":    jobType,
-			"description": description,
-			"status":      status,
-			"created":     created,
-		})
-	}
-
-	return jobs, rows.Err()
+	return jobs, nil
 }
 
-// DropSlot cancels a changefeed job (if job_id is provided).
+// DropSlot cancels a changefeed job, given its job ID.
 func (r *ReplicationOps) DropSlot(ctx context.Context, slotName string) error {
-	// In CockroachDB, we cancel changefeed jobs
-	query := fmt.Sprintf("CANCEL JOB %s", slotName)
-	_, err := r.conn.pool.Exec(ctx, query)
-	if err != nil {
+	if err := CancelChangefeedJob(r.conn.pool, slotName); err != nil {
 		return adapter.WrapError(dbcapabilities.CockroachDB, "cancel_changefeed_job", err)
 	}
 	return nil
@@ -148,18 +174,36 @@ func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName st
 	return adapter.NewUnsupportedOperationError(dbcapabilities.CockroachDB, "drop publication", "not applicable for CockroachDB Changefeeds")
 }
 
+// sources returns the changefeeds opened on the connection, ordered by source ID
+func (r *ReplicationOps) sources() []*CockroachDBReplicationSource {
+	var sources []*CockroachDBReplicationSource
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		sources = append(sources, value.(*CockroachDBReplicationSource))
+		return true
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].id < sources[j].id })
+	return sources
+}
+
 // CockroachDBReplicationSource implements adapter.ReplicationSource for CockroachDB Changefeeds.
+// The position is the last resolved timestamp of the changefeed: every change up to it
+// has been emitted, so a restarted changefeed resumes from it without losing changes.
 type CockroachDBReplicationSource struct {
-	id           string
-	databaseID   string
-	pool         *pgxpool.Pool
-	config       adapter.ReplicationConfig
-	active       int32
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
-	cursor       string // High-water timestamp
-	eventHandler func(event map[string]interface{}) error
-	mu           sync.Mutex
+	id               string
+	databaseID       string
+	conn             *Connection
+	tableNames       []string
+	resolvedInterval time.Duration
+	initialScan      bool
+	active           int32
+	stopChan         chan struct{}
+	wg               sync.WaitGroup
+	mu               sync.RWMutex
+	cursor           string // Last resolved timestamp
+	eventCount       int64
+	lastError        string
+	eventHandler     func(map[string]interface{})
+	checkpointFn     func(context.Context, string) error
 }
 
 // GetSourceID returns the replication source ID.
@@ -174,19 +218,33 @@ func (s *CockroachDBReplicationSource) GetDatabaseID() string {
 
 // GetStatus returns the replication source status.
 func (s *CockroachDBReplicationSource) GetStatus() map[string]interface{} {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	return map[string]interface{}{
-		"active":      s.IsActive(),
-		"cursor":      s.cursor,
-		"table_names": s.config.TableNames,
+		"source_id":         s.id,
+		"database_id":       s.databaseID,
+		"active":            s.IsActive(),
+		"mechanism":         "core_changefeed",
+		"table_names":       s.tableNames,
+		"cursor":            s.cursor,
+		"resolved_interval": s.resolvedInterval.String(),
+		"event_count":       atomic.LoadInt64(&s.eventCount),
+		"last_error":        s.lastError,
 	}
 }
 
 // GetMetadata returns the replication source metadata.
 func (s *CockroachDBReplicationSource) GetMetadata() map[string]interface{} {
-	return s.config.Options
+	return map[string]interface{}{
+		"source_type":     "changefeed",
+		"database_type":   string(dbcapabilities.CockroachDB),
+		"replication_id":  s.id,
+		"database_id":     s.databaseID,
+		"supported_ops":   []string{"INSERT", "UPDATE", "DELETE"},
+		"resume_capable":  true,
+		"transaction_log": false,
+	}
 }
 
 // IsActive returns whether the replication source is active.
@@ -194,176 +252,221 @@ func (s *CockroachDBReplicationSource) IsActive() bool {
 	return atomic.LoadInt32(&s.active) == 1
 }
 
-// Start starts the replication source.
+// Start starts the changefeed.
 func (s *CockroachDBReplicationSource) Start() error {
 	if !atomic.CompareAndSwapInt32(&s.active, 0, 1) {
-		return fmt.Errorf("replication source already active")
+		return adapter.NewDatabaseError(
+			dbcapabilities.CockroachDB,
+			"start_replication",
+			adapter.ErrInvalidConfiguration,
+		).WithContext("error", "replication source is already active")
 	}
 
+	s.stopChan = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
 	s.wg.Add(1)
-	go s.run()
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.run(ctx)
+	}()
+	go func(stop chan struct{}) {
+		<-stop
+		cancel()
+	}(s.stopChan)
 	return nil
 }
 
-// Stop stops the replication source.
+// Stop stops the changefeed.
 func (s *CockroachDBReplicationSource) Stop() error {
 	if !atomic.CompareAndSwapInt32(&s.active, 1, 0) {
-		return fmt.Errorf("replication source not active")
+		return adapter.NewDatabaseError(
+			dbcapabilities.CockroachDB,
+			"stop_replication",
+			adapter.ErrInvalidConfiguration,
+		).WithContext("error", "replication source is not active")
 	}
 	close(s.stopChan)
 	s.wg.Wait()
 	return nil
 }
 
-// Close closes the replication source.
+// Close stops the changefeed and releases the replication source.
 func (s *CockroachDBReplicationSource) Close() error {
 	if s.IsActive() {
 		s.Stop()
 	}
+	s.conn.replicationSources.Delete(s.id)
 	return nil
 }
 
-// GetPosition returns the current replication position (cursor).
+// GetPosition returns the last resolved timestamp.
 func (s *CockroachDBReplicationSource) GetPosition() (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.cursor == "" {
+		return "", fmt.Errorf("no resolved timestamp available")
+	}
 	return s.cursor, nil
 }
 
-// SetPosition sets the starting replication position.
+// SetPosition sets the timestamp the changefeed resumes after.
 func (s *CockroachDBReplicationSource) SetPosition(position string) error {
+	if position == "" {
+		return nil
+	}
+	if _, err := parseHLCTimestamp(position); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	s.cursor = position
+	s.mu.Unlock()
 	return nil
 }
 
-// SaveCheckpoint persists the current replication position.
+// SaveCheckpoint persists the given replication position.
 func (s *CockroachDBReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
-	return s.SetPosition(position)
+	s.mu.RLock()
+	fn := s.checkpointFn
+	s.mu.RUnlock()
+
+	if fn == nil {
+		return nil
+	}
+	return fn(ctx, position)
 }
 
 // SetCheckpointFunc sets the callback function for persisting checkpoints.
 func (s *CockroachDBReplicationSource) SetCheckpointFunc(fn func(context.Context, string) error) {
-	// This is where the external checkpointing mechanism would be hooked in.
-	// For this implementation, we'll rely on the internal SaveCheckpoint.
+	s.mu.Lock()
+	s.checkpointFn = fn
+	s.mu.Unlock()
+}
+
+// run streams the changefeed until the source is stopped. A failed changefeed is
+// restarted from the last resolved timestamp.
+func (s *CockroachDBReplicationSource) run(ctx context.Context) {
+	for {
+		err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("changefeed ended unexpectedly")
+		}
+		s.setError(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(changefeedRetryDelay):
+		}
+	}
+}
+
+// stream runs one changefeed from the current cursor and forwards its rows
+func (s *CockroachDBReplicationSource) stream(ctx context.Context) error {
+	s.mu.RLock()
+	cursor := s.cursor
+	s.mu.RUnlock()
+
+	query := buildChangefeedQuery(s.tableNames, cursor, s.resolvedInterval, s.initialScan)
+	rows, err := s.conn.pool.Query(ctx, query)
+	if err != nil {
+		return fmt.Errorf("error starting changefeed: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		// Changefeed rows are (table, key, value); resolved timestamps have no table or key
+		var table *string
+		var key, value []byte
+		if err := rows.Scan(&table, &key, &value); err != nil {
+			return fmt.Errorf("error reading changefeed row: %v", err)
+		}
+		if err := s.handleRow(ctx, table, key, value); err != nil {
+			s.setError(err)
+		}
+	}
+	return rows.Err()
 }
 
-func (s *CockroachDBReplicationSource) run() {
-	defer s.wg.Done()
+// handleRow forwards a row change to the event handler, or checkpoints a resolved timestamp
+func (s *CockroachDBReplicationSource) handleRow(ctx context.Context, table *string, key, value []byte) error {
+	var payload map[string]interface{}
+	if len(value) > 0 {
+		if err := json.Unmarshal(value, &payload); err != nil {
+			return fmt.Errorf("error decoding changefeed value: %v", err)
+		}
+	}
 
-	ctx := context.Background()
+	if table == nil {
+		resolved, ok := payload["resolved"].(string)
+		if !ok {
+			return nil
+		}
+		return s.resolve(ctx, resolved)
+	}
 
-	// Build table list for changefeed
-	tableList := ""
-	if len(s.config.TableNames) > 0 {
-		tables := make([]string, len(s.config.TableNames))
-		for i, t := range s.config.TableNames {
-			tables[i] = fmt.Sprintf("TABLE %s", t)
+	event := map[string]interface{}{
+		"table":       *table,
+		"database_id": s.databaseID,
+	}
+	for _, field := range []string{"after", "before", "updated"} {
+		if v, ok := payload[field]; ok && v != nil {
+			event[field] = v
+		}
+	}
+	if len(key) > 0 {
+		var k []interface{}
+		if err := json.Unmarshal(key, &k); err == nil {
+			event["key"] = k
 		}
-		tableList = strings.Join(tables, ", ")
-	} else {
-		// If no tables specified, we can't create a changefeed
-		return
 	}
 
-	// Build changefeed options
-	options := []string{
-		"updated",            // Include updated timestamp
-		"resolved='1s'",      // Emit resolved timestamps every 1 second
-		"diff",               // Include before/after images
-		"format=json",        // JSON format
-		"envelope='wrapped'", // Use wrapped envelope
+	atomic.AddInt64(&s.eventCount, 1)
+	if s.eventHandler != nil {
+		s.eventHandler(event)
 	}
+	return nil
+}
 
-	// Add cursor if resuming from a position
-	if s.cursor != "" {
-		options = append(options, fmt.Sprintf("cursor='%s'", s.cursor))
+// resolve advances the cursor to a resolved timestamp and persists it
+func (s *CockroachDBReplicationSource) resolve(ctx context.Context, resolved string) error {
+	if _, err := parseHLCTimestamp(resolved); err != nil {
+		return err
 	}
 
-	optionsStr := strings.Join(options, ", ")
+	s.mu.Lock()
+	s.cursor = resolved
+	s.mu.Unlock()
+
+	if err := s.SaveCheckpoint(ctx, resolved); err != nil {
+		return fmt.Errorf("error saving checkpoint %s: %v", resolved, err)
+	}
+	return nil
+}
 
-	// Create a core changefeed (experimental)
-	// Note: This requires EXPERIMENTAL CHANGEFEED to be enabled
-	query := fmt.Sprintf(
-		"EXPERIMENTAL CHANGEFEED FOR %s WITH %s",
-		tableList,
-		optionsStr,
-	)
+// resolvedAt returns the time of the last resolved timestamp, or the zero time
+func (s *CockroachDBReplicationSource) resolvedAt() time.Time {
+	s.mu.RLock()
+	cursor := s.cursor
+	s.mu.RUnlock()
 
-	// Execute the changefeed query
-	rows, err := s.pool.Query(ctx, query)
+	if cursor == "" {
+		return time.Time{}
+	}
+	t, err := parseHLCTimestamp(cursor)
 	if err != nil {
-		// Log error
-		return
+		return time.Time{}
 	}
-	defer rows.Close()
+	return t
+}
 
-	// Process changefeed events
-	for {
-		select {
-		case <-s.stopChan:
-			return
-		default:
-			if !rows.Next() {
-				// Check for errors
-				if err := rows.Err(); err != nil {
-					// Log error
-					return
-				}
-				// No more rows, exit
-				return
-			}
-
-			// Changefeed returns: table, key, value
-			var table, key, value *string
-			if err := rows.Scan(&table, &key, &value); err != nil {
-				// Log error
-				continue
-			}
-
-			// Skip resolved timestamps (they have nil key and value)
-			if key == nil && value == nil {
-				// This is a resolved timestamp marker
-				// We could update cursor here if needed
-				continue
-			}
-
-			// Parse the changefeed event
-			var eventMap map[string]interface{}
-			if value != nil {
-				if err := json.Unmarshal([]byte(*value), &eventMap); err != nil {
-					// Log error
-					continue
-				}
-			} else {
-				eventMap = make(map[string]interface{})
-			}
-
-			// Add table name and key to the event
-			if table != nil {
-				eventMap["table"] = *table
-			}
-			if key != nil {
-				var keyMap map[string]interface{}
-				if err := json.Unmarshal([]byte(*key), &keyMap); err == nil {
-					eventMap["key"] = keyMap
-				}
-			}
-
-			// Update cursor if present in the event
-			if updated, ok := eventMap["updated"].(string); ok {
-				s.mu.Lock()
-				s.cursor = updated
-				s.mu.Unlock()
-			}
-
-			// Call the event handler
-			if s.eventHandler != nil {
-				if err := s.eventHandler(eventMap); err != nil {
-					// Log error, continue processing
-				}
-			}
-		}
-	}
+func (s *CockroachDBReplicationSource) setError(err error) {
+	s.mu.Lock()
+	s.lastError = err.Error()
+	s.mu.Unlock()
 }
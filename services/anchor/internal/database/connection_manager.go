@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
@@ -17,6 +18,8 @@ type ConnectionManager struct {
 	connections map[string]adapter.Connection         // Database connections
 	instances   map[string]adapter.InstanceConnection // Instance connections
 	registry    *adapter.Registry                     // Adapter registry
+	metrics     *adapter.MetricsRecorder              // Operation metrics
+	metadataTTL time.Duration                         // How long metadata reads are cached
 	mu          sync.RWMutex                          // Protects maps
 	logger      *logger.Logger                        // Logger
 }
@@ -27,9 +30,23 @@ func NewConnectionManager() *ConnectionManager {
 		connections: make(map[string]adapter.Connection),
 		instances:   make(map[string]adapter.InstanceConnection),
 		registry:    adapter.GlobalRegistry(),
+		metrics:     adapter.DefaultMetrics(),
+		metadataTTL: DefaultMetadataCacheTTL,
 	}
 }
 
+// DefaultMetadataCacheTTL is how long the metadata of databases and instances is cached by
+// default
+const DefaultMetadataCacheTTL = 30 * time.Second
+
+// SetMetadataCacheTTL sets how long the metadata of connections made from now on is cached.
+// A TTL of 0 disables the cache.
+func (cm *ConnectionManager) SetMetadataCacheTTL(ttl time.Duration) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.metadataTTL = ttl
+}
+
 // SetLogger sets the logger for the connection manager
 func (cm *ConnectionManager) SetLogger(logger *logger.Logger) {
 	cm.mu.Lock()
@@ -66,25 +83,37 @@ func (cm *ConnectionManager) Connect(ctx context.Context, cfg adapter.Connection
 
 	cm.safeLog("info", "Connecting to database %s (type: %s)", cfg.DatabaseID, dbType)
 
-	// Get the appropriate adapter
-	adp, err := cm.registry.Get(dbType)
-	if err != nil {
+	// Make sure an adapter is registered
+	if _, err := cm.registry.Get(dbType); err != nil {
 		cm.safeLog("error", "No adapter found for database type %s: %v", dbType, err)
 		return fmt.Errorf("no adapter found for %s: %w", cfg.ConnectionType, err)
 	}
 
-	// Establish connection via adapter (cfg is already adapter.ConnectionConfig)
-	conn, err := adp.Connect(ctx, cfg)
+	// Get a connection from the registry pool, which reuses an open connection with the same settings
+	start := time.Now()
+	conn, err := cm.registry.Connect(ctx, cfg)
+	cm.metrics.Observe(dbType, cfg.DatabaseID, "connect", time.Since(start), err)
 	if err != nil {
 		cm.safeLog("error", "Failed to connect to database %s: %v", cfg.DatabaseID, err)
 		return fmt.Errorf("adapter connection failed: %w", err)
 	}
 
-	// Store the connection
+	// Store the connection, recording the latency and errors of its operations and holding
+	// them to the rate limits of the database; waiting for the limits is not recorded, and
+	// metadata served from the cache neither waits nor is recorded
 	cm.mu.Lock()
-	cm.connections[cfg.DatabaseID] = conn
+	previous, replaced := cm.connections[cfg.DatabaseID]
+	cm.connections[cfg.DatabaseID] = adapter.CacheMetadata(
+		adapter.Throttle(adapter.Instrument(conn, cm.metrics), cfg.RateLimits), cm.metadataTTL)
 	cm.mu.Unlock()
 
+	// Release the connection this one replaces
+	if replaced {
+		if err := previous.Close(); err != nil {
+			cm.safeLog("warn", "Error closing replaced connection %s: %v", cfg.DatabaseID, err)
+		}
+	}
+
 	cm.safeLog("info", "Successfully connected to database %s", cfg.DatabaseID)
 	return nil
 }
@@ -95,15 +124,16 @@ func (cm *ConnectionManager) ConnectInstance(ctx context.Context, cfg adapter.In
 
 	cm.safeLog("info", "Connecting to instance %s (type: %s)", cfg.InstanceID, dbType)
 
-	// Get the appropriate adapter
-	adp, err := cm.registry.Get(dbType)
-	if err != nil {
+	// Make sure an adapter is registered
+	if _, err := cm.registry.Get(dbType); err != nil {
 		cm.safeLog("error", "No adapter found for database type %s: %v", dbType, err)
 		return fmt.Errorf("no adapter found for %s: %w", cfg.ConnectionType, err)
 	}
 
-	// Establish connection via adapter (cfg is already adapter.InstanceConfig)
-	instance, err := adp.ConnectInstance(ctx, cfg)
+	// Get an instance connection from the registry pool
+	start := time.Now()
+	instance, err := cm.registry.ConnectInstance(ctx, cfg)
+	cm.metrics.Observe(dbType, cfg.InstanceID, "connect_instance", time.Since(start), err)
 	if err != nil {
 		cm.safeLog("error", "Failed to connect to instance %s: %v", cfg.InstanceID, err)
 		return fmt.Errorf("adapter instance connection failed: %w", err)
@@ -111,13 +141,26 @@ func (cm *ConnectionManager) ConnectInstance(ctx context.Context, cfg adapter.In
 
 	// Store the instance connection
 	cm.mu.Lock()
-	cm.instances[cfg.InstanceID] = instance
+	previous, replaced := cm.instances[cfg.InstanceID]
+	cm.instances[cfg.InstanceID] = adapter.CacheInstanceMetadata(adapter.InstrumentInstance(instance, cm.metrics), cm.metadataTTL)
 	cm.mu.Unlock()
 
+	// Release the instance connection this one replaces
+	if replaced {
+		if err := previous.Close(); err != nil {
+			cm.safeLog("warn", "Error closing replaced instance %s: %v", cfg.InstanceID, err)
+		}
+	}
+
 	cm.safeLog("info", "Successfully connected to instance %s", cfg.InstanceID)
 	return nil
 }
 
+// Metrics returns the recorder of adapter operation metrics
+func (cm *ConnectionManager) Metrics() *adapter.MetricsRecorder {
+	return cm.metrics
+}
+
 // GetConnection retrieves a database connection by ID
 func (cm *ConnectionManager) GetConnection(id string) (adapter.Connection, error) {
 	cm.mu.RLock()
@@ -142,6 +185,22 @@ func (cm *ConnectionManager) GetInstance(id string) (adapter.InstanceConnection,
 	return instance, nil
 }
 
+// InvalidateMetadata clears the cached metadata of a database and of its instance, so that
+// their next metadata reads query the database
+func (cm *ConnectionManager) InvalidateMetadata(id string) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	conn, exists := cm.connections[id]
+	if !exists {
+		return
+	}
+	adapter.InvalidateMetadata(conn)
+	if instance, exists := cm.instances[conn.Config().InstanceID]; exists {
+		adapter.InvalidateMetadata(instance)
+	}
+}
+
 // Disconnect closes and removes a database connection
 func (cm *ConnectionManager) Disconnect(ctx context.Context, id string) error {
 	cm.mu.Lock()
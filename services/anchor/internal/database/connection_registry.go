@@ -6,8 +6,12 @@ import (
 	"sync"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/encryption"
 	"github.com/redbco/redb-open/pkg/logger"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
+
+	// Register the cloud IAM token providers
+	_ "github.com/redbco/redb-open/services/anchor/internal/database/iam"
 )
 
 // ConnectionRegistry provides connection lifecycle management for watchers.
@@ -44,6 +48,11 @@ func (r *ConnectionRegistry) SetLogger(logger *logger.Logger) {
 
 // ConnectDatabase establishes a database connection and tracks it.
 func (r *ConnectionRegistry) ConnectDatabase(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
+	tunnel, err := sshTunnel(config.TenantID, config.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to adapter config
 	adapterConfig := adapter.ConnectionConfig{
 		DatabaseID:            config.DatabaseID,
@@ -67,9 +76,29 @@ func (r *ConnectionRegistry) ConnectDatabase(config dbclient.DatabaseConfig) (*d
 		SSLCert:               &config.SSLCert,
 		SSLKey:                &config.SSLKey,
 		SSLRootCert:           &config.SSLRootCert,
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
+		SSHTunnel:             tunnel,
+		IAMAuth:               iamAuth(config.IAMAuth),
+		KerberosAuth:          kerberosAuth(config.KerberosAuth),
+		SocketPath:            config.SocketPath,
+		Emulator:              config.Emulator,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
+		RoutingPolicy:         config.RoutingPolicy,
+		RateLimits:            rateLimits(config.RateLimits),
+	}
+	for _, replica := range config.Replicas {
+		adapterConfig.Replicas = append(adapterConfig.Replicas, adapter.ReplicaEndpoint{
+			Host:     replica.Host,
+			Port:     replica.Port,
+			Username: replica.Username,
+			Password: replica.Password,
+		})
 	}
 
 	// Connect via ConnectionManager
@@ -104,6 +133,11 @@ func (r *ConnectionRegistry) ConnectDatabase(config dbclient.DatabaseConfig) (*d
 
 // ConnectInstance establishes an instance connection and tracks it.
 func (r *ConnectionRegistry) ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient, error) {
+	tunnel, err := sshTunnel(config.TenantID, config.SSHTunnel)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to adapter config
 	adapterConfig := adapter.InstanceConfig{
 		InstanceID:            config.InstanceID,
@@ -126,6 +160,16 @@ func (r *ConnectionRegistry) ConnectInstance(config dbclient.InstanceConfig) (*d
 		SSLCert:               &config.SSLCert,
 		SSLKey:                &config.SSLKey,
 		SSLRootCert:           &config.SSLRootCert,
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
+		SSHTunnel:             tunnel,
+		IAMAuth:               iamAuth(config.IAMAuth),
+		KerberosAuth:          kerberosAuth(config.KerberosAuth),
+		SocketPath:            config.SocketPath,
+		Emulator:              config.Emulator,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
@@ -203,6 +247,11 @@ func (r *ConnectionRegistry) GetInstanceClient(id string) (*dbclient.InstanceCli
 	return client, nil
 }
 
+// InvalidateMetadata clears the cached metadata of a database and of its instance.
+func (r *ConnectionRegistry) InvalidateMetadata(id string) {
+	r.connMgr.InvalidateMetadata(id)
+}
+
 // DisconnectDatabase disconnects a database.
 func (r *ConnectionRegistry) DisconnectDatabase(id string) error {
 	ctx := context.Background()
@@ -336,3 +385,85 @@ func (r *ConnectionRegistry) DisconnectReplication(replicationID string) error {
 
 	return nil
 }
+
+// sshTunnel converts the SSH tunnel of a configuration for the adapters, decrypting its private
+// key, password and key passphrase. Private keys of instances stored before they were encrypted
+// are used as they are.
+func sshTunnel(tenantID string, config *dbclient.SSHTunnelConfig) (*adapter.SSHTunnel, error) {
+	if config == nil {
+		return nil, nil
+	}
+	tunnel := &adapter.SSHTunnel{
+		Host:                     config.Host,
+		Port:                     config.Port,
+		Username:                 config.Username,
+		PrivateKey:               config.PrivateKey,
+		UseAgent:                 config.UseAgent,
+		HostKey:                  config.HostKey,
+		InsecureSkipHostKeyCheck: config.InsecureSkipHostKeyCheck,
+	}
+	if encryption.IsEncryptedSecret(config.PrivateKey) {
+		privateKey, err := encryption.DecryptSecret(tenantID, config.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting ssh tunnel private key: %w", err)
+		}
+		tunnel.PrivateKey = privateKey
+	}
+	if config.Password != "" {
+		password, err := encryption.DecryptPassword(tenantID, config.Password)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting ssh tunnel password: %w", err)
+		}
+		tunnel.Password = password
+	}
+	if config.PrivateKeyPassphrase != "" {
+		passphrase, err := encryption.DecryptPassword(tenantID, config.PrivateKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting ssh tunnel key passphrase: %w", err)
+		}
+		tunnel.PrivateKeyPassphrase = passphrase
+	}
+	return tunnel, nil
+}
+
+// iamAuth converts the IAM authentication of a database or instance to the adapter
+// configuration. The token providers are registered by the iam package.
+func iamAuth(config *dbclient.IAMAuthConfig) *adapter.IAMAuth {
+	if config == nil {
+		return nil
+	}
+	return &adapter.IAMAuth{
+		Provider: config.Provider,
+		Region:   config.Region,
+		Scope:    config.Scope,
+		ClientID: config.ClientID,
+	}
+}
+
+// kerberosAuth converts the Kerberos authentication of a database or instance to the adapter
+// configuration
+func kerberosAuth(config *dbclient.KerberosAuthConfig) *adapter.KerberosAuth {
+	if config == nil {
+		return nil
+	}
+	return &adapter.KerberosAuth{
+		Principal: config.Principal,
+		Realm:     config.Realm,
+		Keytab:    config.Keytab,
+		CCache:    config.CCache,
+		Krb5Conf:  config.Krb5Conf,
+		SPN:       config.SPN,
+	}
+}
+
+// rateLimits converts the rate limits of a database to the adapter configuration
+func rateLimits(config *dbclient.RateLimitsConfig) *adapter.RateLimits {
+	if config == nil {
+		return nil
+	}
+	return &adapter.RateLimits{
+		RowsPerSecond:           config.RowsPerSecond,
+		QueriesPerSecond:        config.QueriesPerSecond,
+		MaxConcurrentStatements: config.MaxConcurrentStatements,
+	}
+}
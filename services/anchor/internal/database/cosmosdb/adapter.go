@@ -50,6 +50,7 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
+		Emulator:              config.Emulator,
 	}
 
 	client, err := Connect(legacyConfig)
@@ -97,6 +98,7 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
+		Emulator:              config.Emulator,
 		UniqueIdentifier:      config.UniqueIdentifier,
 		Version:               config.Version,
 	}
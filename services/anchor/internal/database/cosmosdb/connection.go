@@ -2,12 +2,15 @@ package cosmosdb
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/data/azcosmos"
 
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/encryption"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 )
@@ -29,20 +32,13 @@ func Connect(cfg dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 	endpoint := buildEndpointURL(cfg)
 
 	// Create credential using the primary key
-	credential, err := azcosmos.NewKeyCredential(decryptedPassword)
+	credential, err := azcosmos.NewKeyCredential(accountKey(cfg, decryptedPassword))
 	if err != nil {
 		return nil, fmt.Errorf("error creating CosmosDB credential: %v", err)
 	}
 
-	// Create client options
-	options := &azcosmos.ClientOptions{}
-	if cfg.SSL {
-		// SSL is handled automatically by the Azure SDK
-		// Additional SSL configuration would go here if needed
-	}
-
 	// Create CosmosDB client
-	client, err := azcosmos.NewClientWithKey(endpoint, credential, options)
+	client, err := azcosmos.NewClientWithKey(endpoint, credential, clientOptions(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("error creating CosmosDB client: %v", err)
 	}
@@ -102,22 +98,20 @@ func ConnectInstance(cfg dbclient.InstanceConfig) (*dbclient.InstanceClient, err
 		SSLKey:         cfg.SSLKey,
 		SSLRootCert:    cfg.SSLRootCert,
 		Role:           cfg.Role,
+		Emulator:       cfg.Emulator,
 	}
 
 	// Build endpoint URL
 	endpoint := buildEndpointURL(dbConfig)
 
 	// Create credential using the primary key
-	credential, err := azcosmos.NewKeyCredential(decryptedPassword)
+	credential, err := azcosmos.NewKeyCredential(accountKey(cfg, decryptedPassword))
 	if err != nil {
 		return nil, fmt.Errorf("error creating CosmosDB credential: %v", err)
 	}
 
-	// Create client options
-	options := &azcosmos.ClientOptions{}
-
 	// Create CosmosDB client
-	client, err := azcosmos.NewClientWithKey(endpoint, credential, options)
+	client, err := azcosmos.NewClientWithKey(endpoint, credential, clientOptions(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("error creating CosmosDB client: %v", err)
 	}
@@ -150,6 +144,10 @@ func buildEndpointURL(cfg dbclient.DatabaseConfig) string {
 	// CosmosDB endpoint format: https://{account}.documents.azure.com:443/
 	// The account name can be extracted from the Host field
 	host := cfg.Host
+	if cfg.Emulator {
+		// The emulator is reached at its address, not at an account of documents.azure.com
+		return adapter.EmulatorEndpoint(cfg.Host, cfg.Port, cfg.SSL) + "/"
+	}
 
 	// If host doesn't contain the full URL, construct it
 	if !strings.HasPrefix(host, "https://") && !strings.HasPrefix(host, "http://") {
@@ -167,6 +165,27 @@ func buildEndpointURL(cfg dbclient.DatabaseConfig) string {
 	return host
 }
 
+// accountKey returns the key authenticating to the account: the password, or the well-known
+// key of the emulator
+func accountKey(cfg dbclient.DatabaseConfig, password string) string {
+	if cfg.Emulator && password == "" {
+		return adapter.CosmosDBEmulatorKey
+	}
+	return password
+}
+
+// clientOptions returns the options of the CosmosDB client. The emulator serves HTTPS with a
+// certificate it generates when it starts, which clients cannot verify.
+func clientOptions(cfg dbclient.DatabaseConfig) *azcosmos.ClientOptions {
+	options := &azcosmos.ClientOptions{}
+	if cfg.Emulator && cfg.SSL {
+		options.Transport = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}
+	}
+	return options
+}
+
 // listDatabases lists all databases in the CosmosDB account (helper function for testing connection)
 func listDatabases(client *azcosmos.Client) ([]string, error) {
 	ctx := context.Background()
@@ -0,0 +1,34 @@
+package cosmosdb
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/database/emulatortest"
+)
+
+// TestEmulator runs the adapter against the Cosmos DB emulator, see emulatortest
+func TestEmulator(t *testing.T) {
+	emulatortest.Run(t, NewAdapter(), emulatortest.Case{
+		Table: func(database string) emulatortest.Table {
+			return emulatortest.Table{
+				Model: &unifiedmodel.UnifiedModel{
+					DatabaseType: dbcapabilities.CosmosDB,
+					Collections: map[string]unifiedmodel.Collection{"items": {
+						Name:   "items",
+						Owner:  database,
+						Fields: map[string]unifiedmodel.Field{"id": {Name: "id", Type: "string"}},
+					}},
+				},
+				Name:   database + ".items",
+				Listed: "items",
+			}
+		},
+		Rows: []map[string]interface{}{
+			{"id": "a", "name": "Ada", "count": 1},
+			{"id": "b", "name": "Grace", "count": 2},
+		},
+		Key: "id",
+	})
+}
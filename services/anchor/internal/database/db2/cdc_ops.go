@@ -11,8 +11,8 @@ import (
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
@@ -318,7 +318,7 @@ func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]inte
 	var grpcConn *grpc.ClientConn
 	if transformationServiceEndpoint != "" {
 		// Connect to transformation service
-		conn, err := grpc.Dial(transformationServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
 		if err == nil {
 			transformClient = transformationv1.NewTransformationServiceClient(conn)
 			grpcConn = conn
@@ -304,9 +304,23 @@ func (d *DataOps) Delete(ctx context.Context, table string, conditions map[strin
 	return rowsAffected, nil
 }
 
-// Stream streams data from a table (not fully implemented for DB2).
+// Stream streams data from a table in offset-based batches.
 func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
-	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.DB2, "stream", "not yet implemented")
+	data, hasMore, err := StreamTableData(ctx, d.conn.db, params.Table, params.Columns, params.OrderBy, params.BatchSize, params.Offset)
+	if err != nil {
+		return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.DB2, "stream", err)
+	}
+
+	return adapter.StreamResult{
+		Data:       data,
+		HasMore:    hasMore,
+		NextCursor: fmt.Sprintf("%d", params.Offset+int64(len(data))),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
 }
 
 // ExecuteQuery executes a raw SQL query.
@@ -454,6 +468,70 @@ func FetchData(db *sql.DB, tableName string, limit int) ([]map[string]interface{
 	return result, nil
 }
 
+// StreamTableData fetches a single batch of rows using OFFSET/FETCH FIRST pagination (helper function)
+func StreamTableData(ctx context.Context, db *sql.DB, tableName string, columns []string, orderBy string, batchSize int32, offset int64) ([]map[string]interface{}, bool, error) {
+	if tableName == "" {
+		return nil, false, fmt.Errorf("table name cannot be empty")
+	}
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	columnList := "*"
+	if len(columns) > 0 {
+		quotedColumns := make([]string, len(columns))
+		for i, col := range columns {
+			quotedColumns[i] = QuoteIdentifier(col)
+		}
+		columnList = strings.Join(quotedColumns, ", ")
+	}
+
+	// A stable order is required for offset pagination to be deterministic
+	orderClause := "1"
+	if orderBy != "" {
+		orderClause = QuoteIdentifier(orderBy)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s OFFSET %d ROWS FETCH FIRST %d ROWS ONLY",
+		columnList, QuoteIdentifier(tableName), orderClause, offset, batchSize)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, false, fmt.Errorf("error streaming table %s: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	columnNames, err := rows.Columns()
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting column names: %v", err)
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columnNames))
+		valuePtrs := make([]interface{}, len(columnNames))
+		for i := range columnNames {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, false, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		entry := make(map[string]interface{})
+		for i, col := range columnNames {
+			entry[col] = values[i]
+		}
+		result = append(result, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	return result, len(result) == int(batchSize), nil
+}
+
 // InsertData inserts data into a specified table (helper function)
 func InsertData(db *sql.DB, tableName string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
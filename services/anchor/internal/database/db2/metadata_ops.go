@@ -5,8 +5,10 @@ package db2
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
@@ -32,8 +34,8 @@ func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]i
 	// Get database size (in bytes)
 	var sizeBytes int64
 	err = m.conn.db.QueryRowContext(ctx, `
-		SELECT SUM(TBSP_USED_SIZE_KB) * 1024 
-		FROM TABLE(MON_GET_TABLESPACE('', -1)) AS T`).Scan(&sizeBytes)
+		SELECT COALESCE(SUM(TBSP_USED_PAGES * TBSP_PAGE_SIZE), 0)
+		FROM TABLE(MON_GET_TABLESPACE('', -2)) AS T`).Scan(&sizeBytes)
 	if err == nil {
 		metadata["size_bytes"] = sizeBytes
 	}
@@ -51,13 +53,29 @@ func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]i
 	// Get unique identifier (database name + instance name)
 	var dbName, instanceName string
 	err = m.conn.db.QueryRowContext(ctx,
-		"SELECT current database, current server FROM SYSIBMADM.ENV_SYS_INFO").Scan(&dbName, &instanceName)
+		"SELECT CURRENT SERVER, INST_NAME FROM SYSIBMADM.ENV_INST_INFO").Scan(&dbName, &instanceName)
 	if err == nil {
 		metadata["unique_identifier"] = fmt.Sprintf("%s_%s", instanceName, dbName)
 		metadata["database_name"] = dbName
 		metadata["instance_name"] = instanceName
 	}
 
+	// Get host system information
+	if system, err := collectSystemInfo(ctx, m.conn.db); err == nil {
+		metadata["system"] = system
+	}
+
+	// Get database configuration
+	if dbConfig, err := collectDatabaseConfig(ctx, m.conn.db); err == nil {
+		metadata["database_config"] = dbConfig
+	}
+
+	// Get tablespaces and their utilization
+	if tablespaces, err := collectTablespaceUsage(ctx, m.conn.db); err == nil {
+		metadata["tablespaces"] = tablespaces
+		metadata["tablespaces_count"] = len(tablespaces)
+	}
+
 	return metadata, nil
 }
 
@@ -76,7 +94,7 @@ func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
 func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
 	var dbName, instanceName string
 	err := m.conn.db.QueryRowContext(ctx,
-		"SELECT current database, current server FROM SYSIBMADM.ENV_SYS_INFO").Scan(&dbName, &instanceName)
+		"SELECT CURRENT SERVER, INST_NAME FROM SYSIBMADM.ENV_INST_INFO").Scan(&dbName, &instanceName)
 	if err != nil {
 		return m.conn.config.Host + ":" + fmt.Sprint(m.conn.config.Port), nil
 	}
@@ -92,8 +110,8 @@ func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]i
 func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
 	var sizeBytes int64
 	err := m.conn.db.QueryRowContext(ctx, `
-		SELECT SUM(TBSP_USED_SIZE_KB) * 1024 
-		FROM TABLE(MON_GET_TABLESPACE('', -1)) AS T`).Scan(&sizeBytes)
+		SELECT COALESCE(SUM(TBSP_USED_PAGES * TBSP_PAGE_SIZE), 0)
+		FROM TABLE(MON_GET_TABLESPACE('', -2)) AS T`).Scan(&sizeBytes)
 	if err != nil {
 		return 0, adapter.WrapError(dbcapabilities.DB2, "get_database_size", err)
 	}
@@ -194,7 +212,7 @@ func (i *InstanceMetadataOps) GetVersion(ctx context.Context) (string, error) {
 func (i *InstanceMetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
 	var dbName, instanceName string
 	err := i.conn.db.QueryRowContext(ctx,
-		"SELECT current database, current server FROM SYSIBMADM.ENV_SYS_INFO").Scan(&dbName, &instanceName)
+		"SELECT CURRENT SERVER, INST_NAME FROM SYSIBMADM.ENV_INST_INFO").Scan(&dbName, &instanceName)
 	if err != nil {
 		return i.conn.config.Host + ":" + fmt.Sprint(i.conn.config.Port), nil
 	}
@@ -210,8 +228,8 @@ func (i *InstanceMetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[
 func (i *InstanceMetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
 	var sizeBytes int64
 	err := i.conn.db.QueryRowContext(ctx, `
-		SELECT SUM(TBSP_USED_SIZE_KB) * 1024 
-		FROM TABLE(MON_GET_TABLESPACE('', -1)) AS T`).Scan(&sizeBytes)
+		SELECT COALESCE(SUM(TBSP_USED_PAGES * TBSP_PAGE_SIZE), 0)
+		FROM TABLE(MON_GET_TABLESPACE('', -2)) AS T`).Scan(&sizeBytes)
 	if err != nil {
 		return 0, adapter.WrapError(dbcapabilities.DB2, "get_database_size", err)
 	}
@@ -236,3 +254,81 @@ func (i *InstanceMetadataOps) ExecuteCommand(ctx context.Context, command string
 	// DB2 admin commands would require special handling
 	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.DB2, "execute command", "not yet implemented")
 }
+
+// collectSystemInfo returns operating system and hardware details of the DB2 server
+func collectSystemInfo(ctx context.Context, db *sql.DB) (map[string]interface{}, error) {
+	var osName, osVersion, osRelease, hostName string
+	var totalCPUs, totalMemoryMB int64
+	err := db.QueryRowContext(ctx, `
+		SELECT OS_NAME, OS_VERSION, OS_RELEASE, HOST_NAME, TOTAL_CPUS, TOTAL_MEMORY
+		FROM SYSIBMADM.ENV_SYS_INFO`).Scan(&osName, &osVersion, &osRelease, &hostName, &totalCPUs, &totalMemoryMB)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"os_name":         osName,
+		"os_version":      osVersion,
+		"os_release":      osRelease,
+		"host_name":       hostName,
+		"total_cpus":      totalCPUs,
+		"total_memory_mb": totalMemoryMB,
+	}, nil
+}
+
+// collectDatabaseConfig returns selected database configuration parameters
+func collectDatabaseConfig(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT NAME, COALESCE(VALUE, '')
+		FROM SYSIBMADM.DBCFG
+		WHERE NAME IN ('codeset', 'territory', 'pagesize', 'logarchmeth1', 'maxappls', 'hadr_db_role')`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	config := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		config[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return config, rows.Err()
+}
+
+// collectTablespaceUsage returns the state and utilization of every tablespace
+func collectTablespaceUsage(ctx context.Context, db *sql.DB) ([]map[string]interface{}, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT TBSP_NAME, TBSP_TYPE, TBSP_CONTENT_TYPE, TBSP_STATE, TBSP_PAGE_SIZE,
+			COALESCE(TBSP_TOTAL_PAGES, 0), COALESCE(TBSP_USED_PAGES, 0)
+		FROM TABLE(MON_GET_TABLESPACE('', -2)) AS T
+		ORDER BY TBSP_NAME`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tablespaces []map[string]interface{}
+	for rows.Next() {
+		var name, spaceType, contentType, state string
+		var pageSize, totalPages, usedPages int64
+		if err := rows.Scan(&name, &spaceType, &contentType, &state, &pageSize, &totalPages, &usedPages); err != nil {
+			return nil, err
+		}
+		tablespaces = append(tablespaces, map[string]interface{}{
+			"name":         name,
+			"type":         spaceType,
+			"content_type": contentType,
+			"state":        state,
+			"page_size":    pageSize,
+			"total_bytes":  totalPages * pageSize,
+			"used_bytes":   usedPages * pageSize,
+			"system":       strings.HasPrefix(name, "SYS"),
+		})
+	}
+
+	return tablespaces, rows.Err()
+}
@@ -24,6 +24,7 @@ func DiscoverSchema(db *sql.DB) (*unifiedmodel.UnifiedModel, error) {
 		Triggers:     make(map[string]unifiedmodel.Trigger),
 		Sequences:    make(map[string]unifiedmodel.Sequence),
 		Indexes:      make(map[string]unifiedmodel.Index),
+		Tablespaces:  make(map[string]unifiedmodel.Tablespace),
 	}
 
 	// Get tables and their columns
@@ -56,6 +57,11 @@ func DiscoverSchema(db *sql.DB) (*unifiedmodel.UnifiedModel, error) {
 		return nil, fmt.Errorf("error getting procedures: %v", err)
 	}
 
+	// Get tablespaces
+	if err := discoverTablespacesUnified(db, um); err != nil {
+		return nil, fmt.Errorf("error getting tablespaces: %v", err)
+	}
+
 	return um, nil
 }
 
@@ -134,7 +140,8 @@ func discoverTablesUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 			COALESCE(c.DEFAULT, '') AS DEFAULT_VALUE,
 			CASE WHEN pk.COLNAME IS NOT NULL THEN 1 ELSE 0 END AS IS_PRIMARY_KEY,
 			COALESCE(c.IDENTITY, 'N') AS IS_IDENTITY,
-			c.COLNO
+			c.COLNO,
+			COALESCE(t.TBSPACE, '') AS TBSPACE
 		FROM SYSCAT.TABLES t
 		INNER JOIN SYSCAT.COLUMNS c ON t.TABSCHEMA = c.TABSCHEMA AND t.TABNAME = c.TABNAME
 		LEFT JOIN (
@@ -158,12 +165,12 @@ func discoverTablesUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 	tables := make(map[string]*unifiedmodel.Table)
 
 	for rows.Next() {
-		var schemaName, tableName, columnName, dataType, defaultValue, isIdentity string
+		var schemaName, tableName, columnName, dataType, defaultValue, isIdentity, tablespace string
 		var length, scale, colNo int
 		var isNullable, isPrimaryKey bool
 
 		err := rows.Scan(&schemaName, &tableName, &columnName, &dataType,
-			&length, &scale, &isNullable, &defaultValue, &isPrimaryKey, &isIdentity, &colNo)
+			&length, &scale, &isNullable, &defaultValue, &isPrimaryKey, &isIdentity, &colNo, &tablespace)
 		if err != nil {
 			return fmt.Errorf("error scanning table row: %v", err)
 		}
@@ -178,7 +185,8 @@ func discoverTablesUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 				Indexes:     make(map[string]unifiedmodel.Index),
 				Constraints: make(map[string]unifiedmodel.Constraint),
 				Options: map[string]interface{}{
-					"schema": schemaName,
+					"schema":     schemaName,
+					"tablespace": tablespace,
 				},
 			}
 		}
@@ -552,6 +560,84 @@ func discoverProceduresUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error
 	return nil
 }
 
+// discoverTablespacesUnified discovers DB2 tablespaces directly into UnifiedModel
+func discoverTablespacesUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
+	query := `
+		SELECT 
+			TBSPACE,
+			TBSPACETYPE,
+			DATATYPE,
+			PAGESIZE,
+			EXTENTSIZE,
+			PREFETCHSIZE,
+			COALESCE(DBPGNAME, '') AS DBPGNAME,
+			COALESCE(SGNAME, '') AS SGNAME,
+			COALESCE(REMARKS, '') AS DESCRIPTION
+		FROM SYSCAT.TABLESPACES
+		ORDER BY TBSPACE
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("error querying tablespaces: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, spaceType, dataType, partitionGroup, storageGroup, description string
+		var pageSize, extentSize, prefetchSize int
+		if err := rows.Scan(&name, &spaceType, &dataType, &pageSize, &extentSize, &prefetchSize,
+			&partitionGroup, &storageGroup, &description); err != nil {
+			return fmt.Errorf("error scanning tablespace row: %v", err)
+		}
+
+		um.Tablespaces[name] = unifiedmodel.Tablespace{
+			Name: name,
+			Options: map[string]any{
+				"management":      tablespaceManagement(spaceType),
+				"content_type":    tablespaceContentType(dataType),
+				"page_size":       pageSize,
+				"extent_size":     extentSize,
+				"prefetch_size":   prefetchSize,
+				"partition_group": partitionGroup,
+				"storage_group":   storageGroup,
+				"comment":         description,
+				"system":          strings.HasPrefix(name, "SYS"),
+			},
+		}
+	}
+
+	return rows.Err()
+}
+
+// tablespaceManagement maps SYSCAT.TABLESPACES.TBSPACETYPE to a readable name
+func tablespaceManagement(spaceType string) string {
+	switch spaceType {
+	case "S":
+		return "system_managed"
+	case "D":
+		return "database_managed"
+	default:
+		return spaceType
+	}
+}
+
+// tablespaceContentType maps SYSCAT.TABLESPACES.DATATYPE to a readable name
+func tablespaceContentType(dataType string) string {
+	switch dataType {
+	case "A":
+		return "regular"
+	case "L":
+		return "large"
+	case "T":
+		return "system_temporary"
+	case "U":
+		return "user_temporary"
+	default:
+		return dataType
+	}
+}
+
 // Helper function to quote DB2 identifiers
 func QuoteIdentifier(name string) string {
 	return "\"" + strings.ReplaceAll(name, "\"", "\"\"") + "\""
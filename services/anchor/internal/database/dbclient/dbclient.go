@@ -1,6 +1,7 @@
 package dbclient
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ type DatabaseClient struct {
 	Name              string
 	Config            DatabaseConfig
 	LastSchema        interface{}
+	LastSchemaHash    string // Content hash of LastSchema when it was discovered
 	IsConnected       int32
 	AdapterConnection interface{} // Stores adapter.Connection when using adapter-based connections
 }
@@ -44,58 +46,137 @@ type InstanceClients struct {
 }
 
 type DatabaseConfig struct {
-	DatabaseID            string `json:"databaseId,omitempty"`            // Unique identifier for the database (same as config_id in v2)
-	WorkspaceID           string `json:"workspaceId,omitempty"`           // Workspace ID for the database connection
-	TenantID              string `json:"tenantId,omitempty"`              // Tenant ID for the database connection
-	EnvironmentID         string `json:"environmentId,omitempty"`         // Environment ID for the database connection
-	InstanceID            string `json:"instanceId,omitempty"`            // Associated instance ID
-	Name                  string `json:"name,omitempty"`                  // Name for the database connection
-	Description           string `json:"description,omitempty"`           // Description for the database connection
-	DatabaseVendor        string `json:"DatabaseVendor"`                  // Database provider (e.g., "postgres", "mysql")
-	ConnectionType        string `json:"connectionType"`                  // Connection type (e.g., "direct", "proxy")
-	Host                  string `json:"host"`                            // Database host
-	Port                  int    `json:"port"`                            // Database port
-	Username              string `json:"username,omitempty"`              // Database username
-	Password              string `json:"password,omitempty"`              // Database password
-	DatabaseName          string `json:"databaseName"`                    // Database name
-	Enabled               *bool  `json:"enabled,omitempty"`               // Optional field to ignore the connection if set to false
-	SSL                   bool   `json:"ssl,omitempty"`                   // Whether to use SSL/TLS
-	SSLMode               string `json:"sslMode,omitempty"`               // SSL mode (e.g., "verify-full", "require")
-	SSLRejectUnauthorized *bool  `json:"sslRejectUnauthorized,omitempty"` // Whether to reject unauthorized SSL certificates
-	SSLCert               string `json:"sslCert,omitempty"`               // Path to SSL certificate file
-	SSLKey                string `json:"sslKey,omitempty"`                // Path to SSL key file
-	SSLRootCert           string `json:"sslRootCert,omitempty"`           // Path to SSL root certificate file
-	Role                  string `json:"role,omitempty"`                  // Database role
-	ConnectedToNodeID     string `json:"connectedToNodeId,omitempty"`     // Node ID where database is connected
-	OwnerID               string `json:"ownerId,omitempty"`               // Owner ID
+	DatabaseID            string              `json:"databaseId,omitempty"`            // Unique identifier for the database (same as config_id in v2)
+	WorkspaceID           string              `json:"workspaceId,omitempty"`           // Workspace ID for the database connection
+	TenantID              string              `json:"tenantId,omitempty"`              // Tenant ID for the database connection
+	EnvironmentID         string              `json:"environmentId,omitempty"`         // Environment ID for the database connection
+	InstanceID            string              `json:"instanceId,omitempty"`            // Associated instance ID
+	Name                  string              `json:"name,omitempty"`                  // Name for the database connection
+	Description           string              `json:"description,omitempty"`           // Description for the database connection
+	DatabaseVendor        string              `json:"DatabaseVendor"`                  // Database provider (e.g., "postgres", "mysql")
+	ConnectionType        string              `json:"connectionType"`                  // Connection type (e.g., "direct", "proxy")
+	Host                  string              `json:"host"`                            // Database host
+	Port                  int                 `json:"port"`                            // Database port
+	Username              string              `json:"username,omitempty"`              // Database username
+	Password              string              `json:"password,omitempty"`              // Database password
+	AuthToken             TokenFunc           `json:"-"`                               // Returns IAM tokens in place of Password, set by the adapter
+	DatabaseName          string              `json:"databaseName"`                    // Database name
+	SocketPath            string              `json:"socketPath,omitempty"`            // Unix socket or named pipe on the anchor node, in place of Host and Port
+	Enabled               *bool               `json:"enabled,omitempty"`               // Optional field to ignore the connection if set to false
+	Emulator              bool                `json:"emulator,omitempty"`              // Local emulator of a cloud database, see adapter.EmulatorConfig
+	SSL                   bool                `json:"ssl,omitempty"`                   // Whether to use SSL/TLS
+	SSLMode               string              `json:"sslMode,omitempty"`               // SSL mode (e.g., "verify-full", "require")
+	SSLRejectUnauthorized *bool               `json:"sslRejectUnauthorized,omitempty"` // Whether to reject unauthorized SSL certificates
+	SSLCert               string              `json:"sslCert,omitempty"`               // Path to SSL certificate file
+	SSLKey                string              `json:"sslKey,omitempty"`                // Path to SSL key file
+	SSLRootCert           string              `json:"sslRootCert,omitempty"`           // Path to SSL root certificate file
+	TLSClientCert         string              `json:"tlsClientCert,omitempty"`         // Client certificate for mutual TLS, PEM or path
+	TLSClientKey          string              `json:"tlsClientKey,omitempty"`          // Private key of the client certificate, PEM or path
+	TLSCABundle           string              `json:"tlsCaBundle,omitempty"`           // CA bundle verifying the server, PEM or path
+	TLSVerifyMode         string              `json:"tlsVerifyMode,omitempty"`         // "verify-full", "verify-ca" or "none"
+	TLSServerName         string              `json:"tlsServerName,omitempty"`         // Host name verified against the server certificate, Host if empty
+	SSHTunnel             *SSHTunnelConfig    `json:"sshTunnel,omitempty"`             // Jump host through which the database is reached
+	IAMAuth               *IAMAuthConfig      `json:"iamAuth,omitempty"`               // Cloud IAM authentication in place of Password
+	KerberosAuth          *KerberosAuthConfig `json:"kerberosAuth,omitempty"`          // Kerberos authentication in place of Password
+	Role                  string              `json:"role,omitempty"`                  // Database role
+	ConnectedToNodeID     string              `json:"connectedToNodeId,omitempty"`     // Node ID where database is connected
+	OwnerID               string              `json:"ownerId,omitempty"`               // Owner ID
+	Replicas              []ReplicaConfig     `json:"replicas,omitempty"`              // Read replicas of the database
+	RoutingPolicy         string              `json:"routingPolicy,omitempty"`         // "primary" or "reads-from-replica"
+	RateLimits            *RateLimitsConfig   `json:"rateLimits,omitempty"`            // Load guardrails of the database
+}
+
+// RateLimitsConfig caps the load the anchor puts on a database, see adapter.RateLimits. Zero
+// fields are unlimited.
+type RateLimitsConfig struct {
+	RowsPerSecond           float64 `json:"rowsPerSecond,omitempty"`
+	QueriesPerSecond        float64 `json:"queriesPerSecond,omitempty"`
+	MaxConcurrentStatements int     `json:"maxConcurrentStatements,omitempty"`
+}
+
+// ReplicaConfig is a read replica of a database. Empty credentials are those of the database.
+type ReplicaConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// SSHTunnelConfig is the jump host through which a database or instance is reached. The password
+// and key passphrase are encrypted like the database password.
+type SSHTunnelConfig struct {
+	Host                     string `json:"host"`
+	Port                     int    `json:"port,omitempty"`
+	Username                 string `json:"username"`
+	PrivateKey               string `json:"privateKey,omitempty"`
+	PrivateKeyPassphrase     string `json:"privateKeyPassphrase,omitempty"`
+	Password                 string `json:"password,omitempty"`
+	UseAgent                 bool   `json:"useAgent,omitempty"`
+	HostKey                  string `json:"hostKey,omitempty"`
+	InsecureSkipHostKeyCheck bool   `json:"insecureSkipHostKeyCheck,omitempty"`
+}
+
+// TokenFunc returns the IAM token to authenticate a new connection with
+type TokenFunc func(ctx context.Context) (string, error)
+
+// IAMAuthConfig authenticates to a database or instance with short-lived tokens of a cloud
+// identity provider in place of the password, see adapter.IAMAuth.
+type IAMAuthConfig struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"clientId,omitempty"`
+}
+
+// KerberosAuthConfig authenticates to a database or instance with the Kerberos tickets of a
+// keytab or credential cache on the anchor node in place of the password, see
+// adapter.KerberosAuth.
+type KerberosAuthConfig struct {
+	Principal string `json:"principal,omitempty"`
+	Realm     string `json:"realm,omitempty"`
+	Keytab    string `json:"keytab,omitempty"`
+	CCache    string `json:"ccache,omitempty"`
+	Krb5Conf  string `json:"krb5Conf,omitempty"`
+	SPN       string `json:"spn,omitempty"`
 }
 
 type InstanceConfig struct {
-	InstanceID            string `json:"instanceId,omitempty"`            // Unique identifier for the instance (same as config_id in v2)
-	WorkspaceID           string `json:"workspaceId,omitempty"`           // Workspace ID for the instance connection
-	TenantID              string `json:"tenantId,omitempty"`              // Tenant ID for the instance connection
-	EnvironmentID         string `json:"environmentId,omitempty"`         // Environment ID for the instance connection
-	Name                  string `json:"name,omitempty"`                  // Name for the instance connection
-	Description           string `json:"description,omitempty"`           // Description for the instance connection
-	DatabaseVendor        string `json:"DatabaseVendor"`                  // Database provider (e.g., "postgres", "mysql")
-	ConnectionType        string `json:"connectionType"`                  // Connection type (e.g., "direct", "proxy")
-	Host                  string `json:"host"`                            // Database host
-	Port                  int    `json:"port"`                            // Database port
-	Username              string `json:"username,omitempty"`              // Database username
-	Password              string `json:"password,omitempty"`              // Database password
-	DatabaseName          string `json:"databaseName"`                    // System database name for connection
-	Enabled               *bool  `json:"enabled,omitempty"`               // Optional field to ignore the connection if set to false
-	SSL                   bool   `json:"ssl,omitempty"`                   // Whether to use SSL/TLS
-	SSLMode               string `json:"sslMode,omitempty"`               // SSL mode (e.g., "verify-full", "require")
-	SSLRejectUnauthorized *bool  `json:"sslRejectUnauthorized,omitempty"` // Whether to reject unauthorized SSL certificates
-	SSLCert               string `json:"sslCert,omitempty"`               // Path to SSL certificate file
-	SSLKey                string `json:"sslKey,omitempty"`                // Path to SSL key file
-	SSLRootCert           string `json:"sslRootCert,omitempty"`           // Path to SSL root certificate file
-	Role                  string `json:"role,omitempty"`                  // Database role
-	ConnectedToNodeID     string `json:"connectedToNodeId,omitempty"`     // Node ID where instance is connected
-	OwnerID               string `json:"ownerId,omitempty"`               // Owner ID
-	UniqueIdentifier      string `json:"uniqueIdentifier,omitempty"`      // Unique identifier for the instance
-	Version               string `json:"version,omitempty"`               // Instance version
+	InstanceID            string              `json:"instanceId,omitempty"`            // Unique identifier for the instance (same as config_id in v2)
+	WorkspaceID           string              `json:"workspaceId,omitempty"`           // Workspace ID for the instance connection
+	TenantID              string              `json:"tenantId,omitempty"`              // Tenant ID for the instance connection
+	EnvironmentID         string              `json:"environmentId,omitempty"`         // Environment ID for the instance connection
+	Name                  string              `json:"name,omitempty"`                  // Name for the instance connection
+	Description           string              `json:"description,omitempty"`           // Description for the instance connection
+	DatabaseVendor        string              `json:"DatabaseVendor"`                  // Database provider (e.g., "postgres", "mysql")
+	ConnectionType        string              `json:"connectionType"`                  // Connection type (e.g., "direct", "proxy")
+	Host                  string              `json:"host"`                            // Database host
+	Port                  int                 `json:"port"`                            // Database port
+	Username              string              `json:"username,omitempty"`              // Database username
+	Password              string              `json:"password,omitempty"`              // Database password
+	AuthToken             TokenFunc           `json:"-"`                               // Returns IAM tokens in place of Password, set by the adapter
+	DatabaseName          string              `json:"databaseName"`                    // System database name for connection
+	SocketPath            string              `json:"socketPath,omitempty"`            // Unix socket or named pipe on the anchor node, in place of Host and Port
+	Enabled               *bool               `json:"enabled,omitempty"`               // Optional field to ignore the connection if set to false
+	Emulator              bool                `json:"emulator,omitempty"`              // Local emulator of a cloud database, see adapter.EmulatorConfig
+	SSL                   bool                `json:"ssl,omitempty"`                   // Whether to use SSL/TLS
+	SSLMode               string              `json:"sslMode,omitempty"`               // SSL mode (e.g., "verify-full", "require")
+	SSLRejectUnauthorized *bool               `json:"sslRejectUnauthorized,omitempty"` // Whether to reject unauthorized SSL certificates
+	SSLCert               string              `json:"sslCert,omitempty"`               // Path to SSL certificate file
+	SSLKey                string              `json:"sslKey,omitempty"`                // Path to SSL key file
+	SSLRootCert           string              `json:"sslRootCert,omitempty"`           // Path to SSL root certificate file
+	TLSClientCert         string              `json:"tlsClientCert,omitempty"`         // Client certificate for mutual TLS, PEM or path
+	TLSClientKey          string              `json:"tlsClientKey,omitempty"`          // Private key of the client certificate, PEM or path
+	TLSCABundle           string              `json:"tlsCaBundle,omitempty"`           // CA bundle verifying the server, PEM or path
+	TLSVerifyMode         string              `json:"tlsVerifyMode,omitempty"`         // "verify-full", "verify-ca" or "none"
+	TLSServerName         string              `json:"tlsServerName,omitempty"`         // Host name verified against the server certificate, Host if empty
+	SSHTunnel             *SSHTunnelConfig    `json:"sshTunnel,omitempty"`             // Jump host through which the instance is reached
+	IAMAuth               *IAMAuthConfig      `json:"iamAuth,omitempty"`               // Cloud IAM authentication in place of Password
+	KerberosAuth          *KerberosAuthConfig `json:"kerberosAuth,omitempty"`          // Kerberos authentication in place of Password
+	Role                  string              `json:"role,omitempty"`                  // Database role
+	ConnectedToNodeID     string              `json:"connectedToNodeId,omitempty"`     // Node ID where instance is connected
+	OwnerID               string              `json:"ownerId,omitempty"`               // Owner ID
+	UniqueIdentifier      string              `json:"uniqueIdentifier,omitempty"`      // Unique identifier for the instance
+	Version               string              `json:"version,omitempty"`               // Instance version
 }
 
 type ConnectionResult struct {
@@ -297,6 +378,14 @@ type UnifiedInstanceConfig struct {
 	SSLRootCert           *string `json:"sslRootCert,omitempty" db:"instance_ssl_root_cert"`
 	Role                  string  `json:"role,omitempty"`
 
+	// Jump host, IAM and Kerberos authentication (inherited from instance)
+	SSHTunnel    *SSHTunnelConfig    `json:"sshTunnel,omitempty" db:"instance_ssh_tunnel"`
+	IAMAuth      *IAMAuthConfig      `json:"iamAuth,omitempty" db:"instance_iam_auth"`
+	KerberosAuth *KerberosAuthConfig `json:"kerberosAuth,omitempty" db:"instance_kerberos_auth"`
+
+	// Local socket in place of Host and Port (inherited from instance)
+	SocketPath *string `json:"socketPath,omitempty" db:"instance_socket_path"`
+
 	// Administrative fields (only for database storage)
 	PolicyIDs     []string  `json:"policyIds,omitempty" db:"policy_ids"`
 	StatusMessage string    `json:"statusMessage,omitempty" db:"instance_status_message"`
@@ -341,6 +430,21 @@ type UnifiedDatabaseConfig struct {
 	SSLRootCert           *string `json:"sslRootCert,omitempty" db:"instance_ssl_root_cert"`
 	Role                  string  `json:"role,omitempty"`
 
+	// Jump host, IAM and Kerberos authentication (inherited from instance)
+	SSHTunnel    *SSHTunnelConfig    `json:"sshTunnel,omitempty" db:"instance_ssh_tunnel"`
+	IAMAuth      *IAMAuthConfig      `json:"iamAuth,omitempty" db:"instance_iam_auth"`
+	KerberosAuth *KerberosAuthConfig `json:"kerberosAuth,omitempty" db:"instance_kerberos_auth"`
+
+	// Local socket in place of Host and Port (inherited from instance)
+	SocketPath *string `json:"socketPath,omitempty" db:"instance_socket_path"`
+
+	// Read replicas
+	Replicas      []ReplicaConfig `json:"replicas,omitempty" db:"database_replicas"`
+	RoutingPolicy string          `json:"routingPolicy,omitempty" db:"database_routing_policy"`
+
+	// Load guardrails
+	RateLimits *RateLimitsConfig `json:"rateLimits,omitempty" db:"database_rate_limits"`
+
 	// Administrative fields (only for database storage)
 	PolicyIDs     []string  `json:"policyIds,omitempty" db:"policy_ids"`
 	StatusMessage string    `json:"statusMessage,omitempty" db:"database_status_message"`
@@ -383,6 +487,10 @@ func (c *UnifiedInstanceConfig) ToConnectionConfig() InstanceConfig {
 		SSLCert:               stringFromPtr(c.SSLCert),
 		SSLKey:                stringFromPtr(c.SSLKey),
 		SSLRootCert:           stringFromPtr(c.SSLRootCert),
+		SSHTunnel:             c.SSHTunnel,
+		IAMAuth:               c.IAMAuth,
+		KerberosAuth:          c.KerberosAuth,
+		SocketPath:            stringFromPtr(c.SocketPath),
 		Role:                  c.Role,
 		ConnectedToNodeID:     c.ConnectedToNodeID,
 		OwnerID:               c.OwnerID,
@@ -426,9 +534,16 @@ func (c *UnifiedDatabaseConfig) ToConnectionConfig() DatabaseConfig {
 		SSLCert:               stringFromPtr(c.SSLCert),
 		SSLKey:                stringFromPtr(c.SSLKey),
 		SSLRootCert:           stringFromPtr(c.SSLRootCert),
+		SSHTunnel:             c.SSHTunnel,
+		IAMAuth:               c.IAMAuth,
+		KerberosAuth:          c.KerberosAuth,
+		SocketPath:            stringFromPtr(c.SocketPath),
 		Role:                  c.Role,
 		ConnectedToNodeID:     c.ConnectedToNodeID,
 		OwnerID:               c.OwnerID,
+		Replicas:              c.Replicas,
+		RoutingPolicy:         c.RoutingPolicy,
+		RateLimits:            c.RateLimits,
 	}
 }
 
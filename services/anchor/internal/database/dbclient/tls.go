@@ -0,0 +1,40 @@
+package dbclient
+
+import (
+	"crypto/tls"
+
+	"github.com/redbco/redb-open/pkg/tlsconfig"
+)
+
+// TLSConfig returns the TLS configuration of the database, or nil if SSL is disabled or
+// none of the TLS fields is set and the SSL fields apply
+func (c DatabaseConfig) TLSConfig() (*tls.Config, error) {
+	return buildTLSConfig(c.SSL, c.Host, c.TLSServerName, c.SSLMode, c.SSLRejectUnauthorized, c.TLSClientCert, c.TLSClientKey, c.TLSCABundle, c.TLSVerifyMode)
+}
+
+// TLSConfig returns the TLS configuration of the instance, or nil if SSL is disabled or
+// none of the TLS fields is set and the SSL fields apply
+func (c InstanceConfig) TLSConfig() (*tls.Config, error) {
+	return buildTLSConfig(c.SSL, c.Host, c.TLSServerName, c.SSLMode, c.SSLRejectUnauthorized, c.TLSClientCert, c.TLSClientKey, c.TLSCABundle, c.TLSVerifyMode)
+}
+
+// buildTLSConfig builds the TLS configuration of a connection. The server certificate is
+// verified against serverName, or host if it is empty.
+func buildTLSConfig(ssl bool, host, serverName, sslMode string, rejectUnauthorized *bool, clientCert, clientKey, caBundle, verifyMode string) (*tls.Config, error) {
+	if !ssl || clientCert == "" && clientKey == "" && caBundle == "" && verifyMode == "" && serverName == "" {
+		return nil, nil
+	}
+	if verifyMode == "" {
+		verifyMode = tlsconfig.FromSSLMode(sslMode, rejectUnauthorized, caBundle != "")
+	}
+	if serverName == "" {
+		serverName = host
+	}
+	return tlsconfig.Build(tlsconfig.Options{
+		ServerName: serverName,
+		ClientCert: clientCert,
+		ClientKey:  clientKey,
+		CABundle:   caBundle,
+		VerifyMode: verifyMode,
+	})
+}
@@ -50,6 +50,7 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
+		Emulator:              config.Emulator,
 	}
 
 	client, err := Connect(legacyConfig)
@@ -96,6 +97,7 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
+		Emulator:              config.Emulator,
 		UniqueIdentifier:      config.UniqueIdentifier,
 		Version:               config.Version,
 	}
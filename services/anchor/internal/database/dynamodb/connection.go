@@ -13,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/encryption"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 )
@@ -90,6 +91,7 @@ func ConnectInstance(cfg dbclient.InstanceConfig) (*dbclient.InstanceClient, err
 		SSLKey:         cfg.SSLKey,
 		SSLRootCert:    cfg.SSLRootCert,
 		Role:           cfg.Role,
+		Emulator:       cfg.Emulator,
 	}
 
 	// Build AWS config
@@ -123,46 +125,35 @@ func buildAWSConfig(cfg dbclient.DatabaseConfig, secretKey string) (aws.Config,
 	// For DynamoDB, we use the Username as Access Key ID and Password as Secret Access Key
 	// Region can be specified in the DatabaseName field or Host field
 	region := cfg.DatabaseName
-	if region == "" {
+	if region == "" && !cfg.Emulator {
 		region = cfg.Host
 	}
 	if region == "" {
 		region = "us-east-1" // Default region
 	}
 
-	// Parse endpoint if custom endpoint is specified (for DynamoDB Local)
-	var endpointResolver aws.EndpointResolverWithOptionsFunc
-	if cfg.Host != "" && !strings.Contains(cfg.Host, "amazonaws.com") {
-		// Custom endpoint (e.g., DynamoDB Local)
-		endpoint := fmt.Sprintf("http://%s:%d", cfg.Host, cfg.Port)
-		if cfg.SSL {
-			endpoint = fmt.Sprintf("https://%s:%d", cfg.Host, cfg.Port)
-		}
-
-		endpointResolver = func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-			return aws.Endpoint{
-				URL:           endpoint,
-				SigningRegion: region,
-			}, nil
-		}
+	accessKey := cfg.Username
+	if cfg.Emulator && accessKey == "" {
+		// DynamoDB Local rejects unsigned requests, but accepts any credentials
+		accessKey, secretKey = adapter.DynamoDBEmulatorAccessKey, adapter.DynamoDBEmulatorSecretKey
 	}
 
 	// Build AWS config
 	awsCfg, err := config.LoadDefaultConfig(context.Background(),
 		config.WithRegion(region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			cfg.Username, // Access Key ID
-			secretKey,    // Secret Access Key
-			"",           // Session Token (empty for long-term credentials)
+			accessKey, // Access Key ID
+			secretKey, // Secret Access Key
+			"",        // Session Token (empty for long-term credentials)
 		)),
 	)
 	if err != nil {
 		return aws.Config{}, fmt.Errorf("error loading AWS config: %v", err)
 	}
 
-	// Apply custom endpoint resolver if specified
-	if endpointResolver != nil {
-		awsCfg.EndpointResolverWithOptions = endpointResolver
+	// Custom endpoint (e.g., DynamoDB Local), which also serves DynamoDB Streams
+	if cfg.Emulator || (cfg.Host != "" && !strings.Contains(cfg.Host, "amazonaws.com")) {
+		awsCfg.BaseEndpoint = aws.String(adapter.EmulatorEndpoint(cfg.Host, cfg.Port, cfg.SSL))
 	}
 
 	return awsCfg, nil
@@ -246,7 +237,7 @@ func CollectDatabaseMetadata(ctx context.Context, db interface{}) (map[string]in
 		tableDetail := map[string]interface{}{
 			"name":         *table.TableName,
 			"status":       string(table.TableStatus),
-			"billing_mode": string(table.BillingModeSummary.BillingMode),
+			"billing_mode": billingMode(table),
 		}
 
 		if table.TableSizeBytes != nil {
@@ -676,3 +667,12 @@ func convertAttributeValue(av types.AttributeValue) interface{} {
 		return nil
 	}
 }
+
+// billingMode returns the billing mode of a table. Tables created with provisioned capacity,
+// and all tables of DynamoDB Local, have no billing mode summary.
+func billingMode(table *types.TableDescription) string {
+	if table.BillingModeSummary == nil {
+		return string(types.BillingModeProvisioned)
+	}
+	return string(table.BillingModeSummary.BillingMode)
+}
@@ -0,0 +1,36 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/database/emulatortest"
+)
+
+// TestEmulator runs the adapter against DynamoDB Local, see emulatortest
+func TestEmulator(t *testing.T) {
+	emulatortest.Run(t, NewAdapter(), emulatortest.Case{
+		Table: func(database string) emulatortest.Table {
+			name := database + "_items"
+			return emulatortest.Table{
+				Model: &unifiedmodel.UnifiedModel{
+					DatabaseType: dbcapabilities.DynamoDB,
+					Tables: map[string]unifiedmodel.Table{name: {
+						Name: name,
+						Columns: map[string]unifiedmodel.Column{
+							"id":   {Name: "id", DataType: "string", IsPrimaryKey: true, IsPartitionKey: true},
+							"name": {Name: "name", DataType: "string", Nullable: true},
+						},
+					}},
+				},
+				Name: name,
+			}
+		},
+		Rows: []map[string]interface{}{
+			{"id": "a", "name": "Ada", "count": 1},
+			{"id": "b", "name": "Grace", "count": 2},
+		},
+		Key: "id",
+	})
+}
@@ -38,13 +38,15 @@ func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
 
 // Connect creates a new replication connection using DynamoDB Streams.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
-	// Get AWS config from connection and create DynamoDB Streams client
-	// Note: We need to access the underlying AWS config which is stored during connection
-	// For now, we'll create the streams client using the same region as DynamoDB client
-	awsCfg := aws.Config{
-		Region: r.conn.client.Options().Region,
-	}
-	streamsClient := dynamodbstreams.NewFromConfig(awsCfg)
+	// Create the DynamoDB Streams client with the region, credentials and endpoint of the
+	// DynamoDB client, so that streams are read from the same emulator or custom endpoint
+	options := r.conn.client.Options()
+	streamsClient := dynamodbstreams.New(dynamodbstreams.Options{
+		Region:       options.Region,
+		Credentials:  options.Credentials,
+		BaseEndpoint: options.BaseEndpoint,
+		HTTPClient:   options.HTTPClient,
+	})
 
 	// Create the replication source
 	source := &DynamoDBReplicationSource{
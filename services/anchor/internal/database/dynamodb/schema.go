@@ -199,26 +199,43 @@ func createTableFromUnified(client *dynamodb.Client, table unifiedmodel.Table) e
 		return fmt.Errorf("table name cannot be empty")
 	}
 
-	// Build attribute definitions from columns
 	var attributeDefinitions []types.AttributeDefinition
 	var keySchema []types.KeySchemaElement
 
-	// Find primary key and partition key
+	// Find primary key and partition key, the partition key first
 	var partitionKey, sortKey string
 	for _, column := range table.Columns {
-		if column.IsPrimaryKey {
+		if column.IsPrimaryKey && column.IsPartitionKey && partitionKey == "" {
+			partitionKey = column.Name
+		}
+	}
+	for _, column := range table.Columns {
+		if column.IsPrimaryKey && column.Name != partitionKey {
 			if partitionKey == "" {
 				partitionKey = column.Name
 			} else if sortKey == "" {
 				sortKey = column.Name
 			}
 		}
+	}
 
-		// Add attribute definition
-		attributeDefinitions = append(attributeDefinitions, types.AttributeDefinition{
-			AttributeName: aws.String(column.Name),
-			AttributeType: mapDataTypeToAttributeType(column.DataType),
-		})
+	// Attribute definitions may only list the attributes of the table and index keys;
+	// DynamoDB rejects tables defining other attributes
+	keyAttributes := map[string]bool{partitionKey: true, sortKey: true}
+	for _, index := range table.Indexes {
+		for i, name := range index.Columns {
+			if i < 2 {
+				keyAttributes[name] = true
+			}
+		}
+	}
+	for _, column := range table.Columns {
+		if column.Name != "" && keyAttributes[column.Name] {
+			attributeDefinitions = append(attributeDefinitions, types.AttributeDefinition{
+				AttributeName: aws.String(column.Name),
+				AttributeType: mapDataTypeToAttributeType(column.DataType),
+			})
+		}
 	}
 
 	// Build key schema
@@ -0,0 +1,180 @@
+// Package emulatortest runs the adapters of cloud databases against local emulators, such as
+// DynamoDB Local, Azurite, the Cosmos DB emulator and the BigQuery emulator, so that they can
+// be developed without cloud accounts and exercised in CI. The emulator test of an adapter
+// calls Run, and is skipped unless the environment variable of its emulator (see
+// adapter.EmulatorEnv) points at a running emulator.
+package emulatortest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Table is the table a test writes to and reads from, in the database created for the test
+type Table struct {
+	Model  *unifiedmodel.UnifiedModel // Creates the table, nil if inserting creates it
+	Name   string                     // Name of the table in data operations
+	Listed string                     // Name of the table returned by ListTables, Name if empty
+}
+
+// Case is what an adapter is exercised with
+type Case struct {
+	// Table returns the table of the test in a database. Databases that have no databases,
+	// such as DynamoDB, keep the table at the top level of the emulator.
+	Table func(database string) Table
+
+	// Rows are inserted into the table and fetched back
+	Rows []map[string]interface{}
+
+	// Key is a field identifying the rows fetched back
+	Key string
+}
+
+// timeout bounds a test, including the wait for the emulator to start
+const timeout = 2 * time.Minute
+
+// Run connects an adapter to its emulator, creates a database and a table, writes rows and
+// reads them back, then discovers the schema and collects the metadata of the database.
+func Run(t *testing.T, a adapter.DatabaseAdapter, c Case) {
+	t.Helper()
+
+	cfg, ok, err := adapter.EmulatorConfig(a.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Skipf("%s is not set to the address of a %s emulator", adapter.EmulatorEnv(a.Type()), a.Type())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	instance := connectInstance(ctx, t, a, instanceConfig(cfg))
+	defer instance.Close()
+
+	// A database of its own keeps runs against the same emulator apart
+	database := fmt.Sprintf("redb_emulator_%d", time.Now().UnixNano())
+	switch err := instance.CreateDatabase(ctx, database, nil); {
+	case err == nil:
+		cfg.DatabaseName = database
+		defer func() {
+			if err := instance.DropDatabase(context.Background(), database, nil); err != nil {
+				t.Errorf("DropDatabase: %v", err)
+			}
+		}()
+	case adapter.IsUnsupported(err):
+	default:
+		t.Fatalf("CreateDatabase: %v", err)
+	}
+
+	conn, err := a.Connect(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	table := c.Table(database)
+	if table.Listed == "" {
+		table.Listed = table.Name
+	}
+	if table.Model != nil {
+		if err := conn.SchemaOperations().CreateStructure(ctx, table.Model); err != nil {
+			t.Fatalf("CreateStructure: %v", err)
+		}
+	}
+
+	inserted, err := conn.DataOperations().Insert(ctx, table.Name, c.Rows)
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if inserted != int64(len(c.Rows)) {
+		t.Errorf("Insert = %d rows, want %d", inserted, len(c.Rows))
+	}
+
+	fetched, err := conn.DataOperations().Fetch(ctx, table.Name, len(c.Rows)+10)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	keys := make(map[string]bool, len(fetched))
+	for _, row := range fetched {
+		keys[fmt.Sprint(row[c.Key])] = true
+	}
+	for _, row := range c.Rows {
+		if key := fmt.Sprint(row[c.Key]); !keys[key] {
+			t.Errorf("Fetch did not return the row with %s %s, got %v", c.Key, key, fetched)
+		}
+	}
+
+	tables, err := conn.SchemaOperations().ListTables(ctx)
+	if err != nil {
+		t.Fatalf("ListTables: %v", err)
+	}
+	if !contains(tables, table.Listed) {
+		t.Errorf("ListTables = %v, want %s among them", tables, table.Listed)
+	}
+
+	if _, err := conn.SchemaOperations().DiscoverSchema(ctx); err != nil {
+		t.Errorf("DiscoverSchema: %v", err)
+	}
+	if _, err := conn.MetadataOperations().CollectDatabaseMetadata(ctx); err != nil {
+		t.Errorf("CollectDatabaseMetadata: %v", err)
+	}
+}
+
+// connectInstance connects to the instance of the emulator, waiting for the emulator to start
+func connectInstance(ctx context.Context, t *testing.T, a adapter.DatabaseAdapter, cfg adapter.InstanceConfig) adapter.InstanceConnection {
+	t.Helper()
+	for {
+		instance, err := a.ConnectInstance(ctx, cfg)
+		if err == nil {
+			if err = instance.Ping(ctx); err == nil {
+				return instance
+			}
+			instance.Close()
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("connecting to the emulator at %s:%d: %v", cfg.Host, cfg.Port, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// instanceConfig returns the configuration connecting to the instance of a database
+func instanceConfig(cfg adapter.ConnectionConfig) adapter.InstanceConfig {
+	return adapter.InstanceConfig{
+		InstanceID:       strings.Replace(cfg.DatabaseID, "emulator-", "emulator-instance-", 1),
+		TenantID:         cfg.TenantID,
+		WorkspaceID:      cfg.WorkspaceID,
+		Name:             cfg.Name,
+		ConnectionType:   cfg.ConnectionType,
+		DatabaseVendor:   cfg.DatabaseVendor,
+		Host:             cfg.Host,
+		Port:             cfg.Port,
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		SSL:              cfg.SSL,
+		Emulator:         cfg.Emulator,
+		ProjectID:        cfg.ProjectID,
+		Location:         cfg.Location,
+		ConnectionString: cfg.ConnectionString,
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package filesource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// defaultUploadDir matches the client API default, so uploads are found when neither service is configured
+var defaultUploadDir = filepath.Join(os.TempDir(), "redb-uploads")
+
+// uploadDir holds the directory that file source paths must resolve under
+var uploadDir atomic.Value
+
+// SetUploadDir sets the directory that uploaded files are stored in. Connections to paths
+// outside of this directory are rejected. An empty dir restores the default.
+func SetUploadDir(dir string) {
+	if dir == "" {
+		dir = defaultUploadDir
+	}
+	uploadDir.Store(dir)
+}
+
+// UploadDir returns the directory that file source paths must resolve under.
+func UploadDir() string {
+	if dir, ok := uploadDir.Load().(string); ok {
+		return dir
+	}
+	return defaultUploadDir
+}
+
+// Adapter implements adapter.DatabaseAdapter for uploaded CSV/XLSX files.
+// The file path is taken from the connection's DatabaseName and must be inside the upload directory.
+type Adapter struct{}
+
+// NewAdapter creates a new file source adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.File
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.File)
+}
+
+// Connect opens and parses the file referenced by the connection configuration.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	filePath := config.DatabaseName
+	if filePath == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.File, "databaseName", "file path is required")
+	}
+	resolved, err := resolvePath(UploadDir(), filePath)
+	if err != nil {
+		return nil, adapter.NewConfigurationError(dbcapabilities.File, "databaseName", err.Error())
+	}
+
+	conn := &Connection{
+		id:        config.DatabaseID,
+		path:      resolved,
+		options:   readOptionsFromConfig(config),
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}
+
+	if _, err := conn.Sheets(); err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.File, config.Host, config.Port, err)
+	}
+
+	return conn, nil
+}
+
+// ConnectInstance is not supported; each file is a standalone database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.File, "instance connection", "file sources are connected as individual databases")
+}
+
+// resolvePath resolves a file path, following symlinks, and checks that it stays inside root
+func resolvePath(root, filePath string) (string, error) {
+	if !filepath.IsAbs(filePath) {
+		return "", fmt.Errorf("file path must be absolute")
+	}
+	for _, part := range strings.Split(filepath.ToSlash(filePath), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("file path must not contain parent directory references")
+		}
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(filepath.Clean(root))
+	if err != nil {
+		return "", fmt.Errorf("upload directory is not accessible: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(filePath))
+	if err != nil {
+		return "", fmt.Errorf("file is not accessible: %w", err)
+	}
+
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file path must be inside the upload directory")
+	}
+	return resolved, nil
+}
+
+func readOptionsFromConfig(config adapter.ConnectionConfig) ReadOptions {
+	opts := ReadOptions{HasHeader: true}
+	if v, ok := config.Options["has_header"].(bool); ok {
+		opts.HasHeader = v
+	}
+	if v, ok := config.Options["delimiter"].(string); ok && v != "" {
+		opts.Delimiter = []rune(v)[0]
+	}
+	return opts
+}
+
+// Connection implements adapter.Connection for a file source.
+type Connection struct {
+	id        string
+	path      string
+	options   ReadOptions
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+
+	mu      sync.Mutex
+	sheets  map[string]*Sheet
+	columns map[string][]ColumnStats
+	modTime time.Time
+}
+
+// Sheets returns the parsed file content, re-reading the file if it changed on disk.
+func (c *Connection) Sheets() (map[string]*Sheet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access file: %w", err)
+	}
+	if c.sheets != nil && info.ModTime().Equal(c.modTime) {
+		return c.sheets, nil
+	}
+
+	sheets, err := ReadFile(c.path, c.options)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string][]ColumnStats, len(sheets))
+	for name, sheet := range sheets {
+		columns[name] = InferColumns(sheet)
+	}
+
+	c.sheets = sheets
+	c.columns = columns
+	c.modTime = info.ModTime()
+	return sheets, nil
+}
+
+// sheet returns a single sheet and its inferred columns
+func (c *Connection) sheet(table string) (*Sheet, []ColumnStats, error) {
+	sheets, err := c.Sheets()
+	if err != nil {
+		return nil, nil, err
+	}
+	sheet, exists := sheets[table]
+	if !exists {
+		return nil, nil, adapter.NewNotFoundError(dbcapabilities.File, "table", table)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return sheet, c.columns[table], nil
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.File
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the file is still readable.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	_, err := os.Stat(c.path)
+	return err
+}
+
+// Close closes the connection and releases the parsed content.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	c.mu.Lock()
+	c.sheets = nil
+	c.columns = nil
+	c.mu.Unlock()
+	return nil
+}
+
+// SchemaOperations returns the schema operator.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.File)
+}
+
+// MetadataOperations returns the metadata operator.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the file path.
+func (c *Connection) Raw() interface{} {
+	return c.path
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
@@ -0,0 +1,51 @@
+package filesource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	inside := filepath.Join(root, "tenant", "data.csv")
+	if err := os.MkdirAll(filepath.Dir(inside), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(inside, []byte("a,b\n1,2\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(outside, "secret.csv")
+	if err := os.WriteFile(secret, []byte("x\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "tenant", "link.csv")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "file inside root", path: inside},
+		{name: "relative path", path: "tenant/data.csv", wantErr: true},
+		{name: "file outside root", path: secret, wantErr: true},
+		{name: "parent reference", path: root + "/tenant/../tenant/data.csv", wantErr: true},
+		{name: "symlink escaping root", path: link, wantErr: true},
+		{name: "root itself", path: root, wantErr: true},
+		{name: "missing file", path: filepath.Join(root, "missing.csv"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolvePath(root, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package filesource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DataOps implements data operations for file sources. File sources are read-only.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves rows from a table.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves rows with specific columns.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	rows, _, err := d.readRows(table, columns, 0, limit)
+	return rows, err
+}
+
+// Stream returns a batch of rows starting at the given offset.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	rows, total, err := d.readRows(params.Table, params.Columns, params.Offset, int(params.BatchSize))
+	if err != nil {
+		return adapter.StreamResult{}, err
+	}
+
+	next := params.Offset + int64(len(rows))
+	return adapter.StreamResult{
+		Data:       rows,
+		HasMore:    next < int64(total),
+		NextCursor: fmt.Sprintf("%d", next),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// readRows converts a window of sheet rows into typed records
+func (d *DataOps) readRows(table string, columns []string, offset int64, limit int) ([]map[string]interface{}, int, error) {
+	sheet, stats, err := d.conn.sheet(table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indexes := make([]int, 0, len(stats))
+	if len(columns) == 0 {
+		for i := range stats {
+			indexes = append(indexes, i)
+		}
+	} else {
+		positions := make(map[string]int, len(stats))
+		for i, col := range stats {
+			positions[col.Name] = i
+		}
+		for _, name := range columns {
+			i, exists := positions[name]
+			if !exists {
+				return nil, 0, adapter.NewNotFoundError(dbcapabilities.File, "column", name)
+			}
+			indexes = append(indexes, i)
+		}
+	}
+
+	total := len(sheet.Rows)
+	if offset < 0 || offset >= int64(total) {
+		return []map[string]interface{}{}, total, nil
+	}
+	end := total
+	if limit > 0 && int(offset)+limit < end {
+		end = int(offset) + limit
+	}
+
+	rows := make([]map[string]interface{}, 0, end-int(offset))
+	for _, raw := range sheet.Rows[offset:end] {
+		row := make(map[string]interface{}, len(indexes))
+		for _, i := range indexes {
+			row[stats[i].Name] = ConvertValue(stats[i].DataType, raw[i])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, total, nil
+}
+
+// Insert is not supported because file sources are read-only.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.File, "insert", "file sources are read-only")
+}
+
+// Update is not supported because file sources are read-only.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.File, "update", "file sources are read-only")
+}
+
+// Upsert is not supported because file sources are read-only.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.File, "upsert", "file sources are read-only")
+}
+
+// Delete is not supported because file sources are read-only.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.File, "delete", "file sources are read-only")
+}
+
+// ExecuteQuery is not supported because file sources have no query language.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.File, "execute query", "file sources have no query language")
+}
+
+// ExecuteCountQuery is not supported because file sources have no query language.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.File, "execute count query", "file sources have no query language")
+}
+
+// GetRowCount returns the exact number of rows in a table. Where clauses are not supported.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	if whereClause != "" {
+		return 0, false, adapter.NewUnsupportedOperationError(dbcapabilities.File, "filtered row count", "file sources have no query language")
+	}
+	sheet, _, err := d.conn.sheet(table)
+	if err != nil {
+		return 0, false, err
+	}
+	return int64(len(sheet.Rows)), true, nil
+}
+
+// Wipe is not supported because file sources are read-only.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.File, "wipe", "file sources are read-only")
+}
@@ -0,0 +1,143 @@
+package filesource
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Inferred column types, expressed as UnifiedModel data types
+const (
+	TypeInteger   = "integer"
+	TypeDecimal   = "decimal"
+	TypeBoolean   = "boolean"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+	TypeString    = "string"
+)
+
+// timestampLayouts are tried in order when detecting timestamp columns
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+}
+
+// dateLayouts are tried in order when detecting date columns
+var dateLayouts = []string{
+	"2006-01-02",
+	"01/02/2006",
+	"02.01.2006",
+	"2006/01/02",
+}
+
+// ColumnStats describes what was inferred for a column
+type ColumnStats struct {
+	Name     string
+	DataType string
+	Nullable bool
+	MaxLen   int
+}
+
+// InferColumns infers a type for every column of a sheet. A column gets the narrowest
+// type that all of its non-empty values parse as; empty values mark it nullable.
+func InferColumns(sheet *Sheet) []ColumnStats {
+	stats := make([]ColumnStats, len(sheet.Headers))
+	for i, header := range sheet.Headers {
+		candidates := map[string]bool{
+			TypeInteger: true, TypeDecimal: true, TypeBoolean: true,
+			TypeDate: true, TypeTimestamp: true,
+		}
+		nonEmpty := 0
+		col := ColumnStats{Name: header}
+
+		for _, row := range sheet.Rows {
+			value := strings.TrimSpace(row[i])
+			if value == "" {
+				col.Nullable = true
+				continue
+			}
+			nonEmpty++
+			if len(value) > col.MaxLen {
+				col.MaxLen = len(value)
+			}
+			for candidate := range candidates {
+				if _, ok := parseValue(candidate, value); !ok {
+					delete(candidates, candidate)
+				}
+			}
+		}
+
+		switch {
+		case nonEmpty == 0:
+			col.DataType = TypeString
+			col.Nullable = true
+		case candidates[TypeBoolean]:
+			col.DataType = TypeBoolean
+		case candidates[TypeInteger]:
+			col.DataType = TypeInteger
+		case candidates[TypeDecimal]:
+			col.DataType = TypeDecimal
+		case candidates[TypeDate]:
+			col.DataType = TypeDate
+		case candidates[TypeTimestamp]:
+			col.DataType = TypeTimestamp
+		default:
+			col.DataType = TypeString
+		}
+
+		stats[i] = col
+	}
+	return stats
+}
+
+// ConvertValue converts a raw cell into a typed value; empty cells become nil.
+func ConvertValue(dataType, raw string) interface{} {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil
+	}
+	if converted, ok := parseValue(dataType, value); ok {
+		return converted
+	}
+	return raw
+}
+
+func parseValue(dataType, value string) (interface{}, bool) {
+	switch dataType {
+	case TypeInteger:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return n, err == nil
+	case TypeDecimal:
+		f, err := strconv.ParseFloat(value, 64)
+		return f, err == nil
+	case TypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "y":
+			return true, true
+		case "false", "no", "n":
+			return false, true
+		}
+		return nil, false
+	case TypeDate:
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.Format("2006-01-02"), true
+			}
+		}
+		return nil, false
+	case TypeTimestamp:
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.UTC().Format(time.RFC3339Nano), true
+			}
+		}
+		return nil, false
+	default:
+		return value, true
+	}
+}
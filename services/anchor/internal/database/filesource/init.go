@@ -0,0 +1,8 @@
+package filesource
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register file source adapter with the global registry
+	adapter.Register(NewAdapter())
+}
@@ -0,0 +1,96 @@
+package filesource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations for file sources.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the file.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	info, err := os.Stat(m.conn.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access file: %w", err)
+	}
+
+	sheets, err := m.conn.Sheets()
+	if err != nil {
+		return nil, err
+	}
+
+	rowCounts := make(map[string]int, len(sheets))
+	for name, sheet := range sheets {
+		rowCounts[name] = len(sheet.Rows)
+	}
+
+	return map[string]interface{}{
+		"database_type": string(dbcapabilities.File),
+		"file_name":     filepath.Base(m.conn.path),
+		"file_format":   strings.TrimPrefix(strings.ToLower(filepath.Ext(m.conn.path)), "."),
+		"size_bytes":    info.Size(),
+		"last_modified": info.ModTime(),
+		"table_count":   len(sheets),
+		"row_counts":    rowCounts,
+	}, nil
+}
+
+// CollectInstanceMetadata returns the same metadata as the database, since a file has no instance.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return m.CollectDatabaseMetadata(ctx)
+}
+
+// GetVersion returns the file format.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(m.conn.path)), "."), nil
+}
+
+// GetUniqueIdentifier returns the SHA-256 of the file content.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	f, err := os.Open(m.conn.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to hash file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetDatabaseSize returns the file size in bytes.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	info, err := os.Stat(m.conn.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to access file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// GetTableCount returns the number of tables (worksheets) in the file.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	sheets, err := m.conn.Sheets()
+	if err != nil {
+		return 0, err
+	}
+	return len(sheets), nil
+}
+
+// ExecuteCommand is not supported for file sources.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.File, "execute command", "file sources do not accept commands")
+}
@@ -0,0 +1,360 @@
+package filesource
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Sheet holds the tabular content of a CSV file or a single worksheet of an XLSX file.
+type Sheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]string
+}
+
+// ReadOptions controls how a file is parsed.
+type ReadOptions struct {
+	Delimiter rune // CSV field delimiter (default ',')
+	HasHeader bool // Whether the first row contains column names
+}
+
+// ReadFile parses a CSV or XLSX file into sheets keyed by table name.
+func ReadFile(filePath string, opts ReadOptions) (map[string]*Sheet, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv", ".tsv", ".txt":
+		if strings.EqualFold(filepath.Ext(filePath), ".tsv") && opts.Delimiter == 0 {
+			opts.Delimiter = '\t'
+		}
+		sheet, err := readCSV(filePath, opts)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]*Sheet{sheet.Name: sheet}, nil
+	case ".xlsx":
+		return readXLSX(filePath, opts)
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s (supported: .csv, .tsv, .xlsx)", filepath.Ext(filePath))
+	}
+}
+
+func readCSV(filePath string, opts ReadOptions) (*Sheet, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	return NewSheet(TableName(base), records, opts.HasHeader), nil
+}
+
+// NewSheet splits raw records into headers and rows, generating and de-duplicating column names
+func NewSheet(name string, records [][]string, hasHeader bool) *Sheet {
+	sheet := &Sheet{Name: name}
+	if len(records) == 0 {
+		return sheet
+	}
+
+	width := 0
+	for _, record := range records {
+		if len(record) > width {
+			width = len(record)
+		}
+	}
+
+	var headerRow []string
+	if hasHeader {
+		headerRow = records[0]
+		records = records[1:]
+	}
+
+	seen := make(map[string]int)
+	sheet.Headers = make([]string, width)
+	for i := 0; i < width; i++ {
+		header := ""
+		if i < len(headerRow) {
+			header = ColumnName(headerRow[i])
+		}
+		if header == "" {
+			header = fmt.Sprintf("column_%d", i+1)
+		}
+		if n := seen[header]; n > 0 {
+			seen[header] = n + 1
+			header = fmt.Sprintf("%s_%d", header, n+1)
+		} else {
+			seen[header] = 1
+		}
+		sheet.Headers[i] = header
+	}
+
+	for _, record := range records {
+		if isBlankRecord(record) {
+			continue
+		}
+		row := make([]string, width)
+		copy(row, record)
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	return sheet
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// TableName converts a file or sheet name into a table name.
+func TableName(name string) string {
+	name = ColumnName(name)
+	if name == "" {
+		return "data"
+	}
+	return name
+}
+
+// ColumnName normalizes a header into a lowercase identifier.
+func ColumnName(header string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.TrimSpace(strings.ToLower(header)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore && b.Len() > 0 {
+			b.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+// XLSX structures (Office Open XML SpreadsheetML)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string        `xml:"r,attr"`
+			Type   string        `xml:"t,attr"`
+			Value  string        `xml:"v"`
+			Inline *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(filePath string, opts ReadOptions) (map[string]*Sheet, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX file: %w", err)
+	}
+	defer archive.Close()
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	var rels xlsxRelationships
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(target, "xl/") {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	var shared xlsxSharedStrings
+	if _, exists := files["xl/sharedStrings.xml"]; exists {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheets := make(map[string]*Sheet, len(workbook.Sheets))
+	for _, ws := range workbook.Sheets {
+		target, ok := targets[ws.RID]
+		if !ok {
+			return nil, fmt.Errorf("worksheet %s has no relationship target", ws.Name)
+		}
+
+		var worksheet xlsxWorksheet
+		if err := decodeZipXML(files, target, &worksheet); err != nil {
+			return nil, err
+		}
+
+		records, err := worksheetRecords(&worksheet, shared.Items)
+		if err != nil {
+			return nil, fmt.Errorf("worksheet %s: %w", ws.Name, err)
+		}
+
+		name := TableName(ws.Name)
+		if _, exists := sheets[name]; exists {
+			name = fmt.Sprintf("%s_%d", name, len(sheets)+1)
+		}
+		sheets[name] = NewSheet(name, records, opts.HasHeader)
+	}
+
+	return sheets, nil
+}
+
+func worksheetRecords(ws *xlsxWorksheet, shared []xlsxRichText) ([][]string, error) {
+	var records [][]string
+	for i, row := range ws.Rows {
+		rowIndex := row.Index
+		if rowIndex == 0 {
+			rowIndex = i + 1
+		}
+		// Preserve gaps between rows so row positions stay aligned
+		for len(records) < rowIndex-1 {
+			records = append(records, nil)
+		}
+
+		var record []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				parsed, err := columnIndex(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+				col = parsed
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("invalid shared string index %q in cell %s", cell.Value, cell.Ref)
+				}
+				record[col] = shared[idx].String()
+			case "inlineStr":
+				if cell.Inline != nil {
+					record[col] = cell.Inline.String()
+				}
+			case "b":
+				if cell.Value == "1" {
+					record[col] = "true"
+				} else {
+					record[col] = "false"
+				}
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// columnIndex converts a cell reference such as "AB12" into a zero-based column index
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			col = col*26 + int(r-'A'+1)
+			n++
+		} else {
+			break
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference: %s", ref)
+	}
+	return col - 1, nil
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	f, exists := files[name]
+	if !exists {
+		return fmt.Errorf("invalid XLSX file: missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, 512<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package filesource
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// SchemaOps implements schema operations for file sources.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema infers a table per CSV file or XLSX worksheet.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	sheets, err := s.conn.Sheets()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]unifiedmodel.Table, len(sheets))
+	for name := range sheets {
+		table, err := s.GetTableSchema(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		tables[name] = *table
+	}
+
+	return &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.File,
+		Tables:       tables,
+	}, nil
+}
+
+// CreateStructure is not supported because file sources are read-only.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.File, "create structure", "file sources are read-only")
+}
+
+// ListTables returns the table names derived from the file.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	sheets, err := s.conn.Sheets()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(sheets))
+	for name := range sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetTableSchema returns the inferred schema for a single table.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	sheet, columns, err := s.conn.sheet(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &unifiedmodel.Table{
+		Name:    tableName,
+		Columns: make(map[string]unifiedmodel.Column, len(columns)),
+		Options: map[string]any{
+			"row_count": len(sheet.Rows),
+			"source":    s.conn.path,
+		},
+	}
+
+	for i, col := range columns {
+		position := i + 1
+		column := unifiedmodel.Column{
+			Name:            col.Name,
+			DataType:        col.DataType,
+			Nullable:        col.Nullable,
+			OrdinalPosition: &position,
+		}
+		if col.DataType == TypeString {
+			column.Options = map[string]any{"max_length": col.MaxLen}
+		}
+		table.Columns[col.Name] = column
+	}
+
+	return table, nil
+}
@@ -0,0 +1,257 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/filesource"
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// Adapter implements adapter.DatabaseAdapter for Google Sheets.
+// The spreadsheet ID is taken from DatabaseName and every worksheet is exposed as a table.
+//
+// Credentials are OAuth tokens obtained through the integration service: the access token is
+// read from Token (or Password), and if options.refresh_token, options.client_id and
+// options.client_secret are set the access token is refreshed automatically.
+type Adapter struct{}
+
+// NewAdapter creates a new Google Sheets adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.GoogleSheets
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.GoogleSheets)
+}
+
+// Connect verifies access to the spreadsheet.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	if config.DatabaseName == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.GoogleSheets, "databaseName", "spreadsheet ID is required")
+	}
+
+	accessToken := config.Token
+	if accessToken == "" {
+		accessToken = config.Password
+	}
+	refreshToken := optionString(config, "refresh_token")
+	if accessToken == "" && refreshToken == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.GoogleSheets, "token", "an OAuth access token or refresh token is required")
+	}
+
+	tokenURL := optionString(config, "token_url")
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	tokens := restclient.NewTokenSource(accessToken, refreshToken,
+		optionString(config, "client_id"), optionString(config, "client_secret"), tokenURL)
+
+	baseURL := defaultBaseURL
+	if config.Host != "" && config.Host != "sheets.googleapis.com" {
+		baseURL = strings.TrimRight(config.Host, "/")
+	}
+
+	requestsPerMinute := optionFloat(config, "requests_per_minute", defaultRequestsPerMinute)
+	client := &Client{
+		api:           restclient.NewClient(baseURL, tokens, requestsPerMinute/60),
+		spreadsheetID: config.DatabaseName,
+	}
+
+	if _, err := client.getSpreadsheet(ctx); err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.GoogleSheets, config.Host, config.Port, err)
+	}
+
+	return &Connection{
+		id:        config.DatabaseID,
+		client:    client,
+		config:    config,
+		adapter:   a,
+		batchSize: int(optionFloat(config, "batch_size", defaultBatchSize)),
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance is not supported; each spreadsheet is connected as a database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "instance connection", "spreadsheets are connected as individual databases")
+}
+
+func optionString(config adapter.ConnectionConfig, key string) string {
+	if v, ok := config.Options[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func optionFloat(config adapter.ConnectionConfig, key string, def float64) float64 {
+	switch v := config.Options[key].(type) {
+	case float64:
+		if v > 0 {
+			return v
+		}
+	case int:
+		if v > 0 {
+			return float64(v)
+		}
+	}
+	return def
+}
+
+// Connection implements adapter.Connection for a spreadsheet.
+type Connection struct {
+	id        string
+	client    *Client
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	batchSize int
+	connected int32
+}
+
+// worksheet is a table backed by a worksheet
+type worksheet struct {
+	props   sheetProperties
+	sheet   *filesource.Sheet
+	columns []filesource.ColumnStats
+}
+
+// worksheets returns the worksheet properties keyed by table name
+func (c *Connection) worksheets(ctx context.Context) (map[string]sheetProperties, error) {
+	ss, err := c.client.getSpreadsheet(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.GoogleSheets, "list_sheets", err)
+	}
+	result := make(map[string]sheetProperties, len(ss.Sheets))
+	for _, s := range ss.Sheets {
+		result[filesource.TableName(s.Properties.Title)] = s.Properties
+	}
+	return result, nil
+}
+
+// loadTable reads a worksheet and infers its columns. The first row holds the headers.
+func (c *Connection) loadTable(ctx context.Context, table string) (*worksheet, error) {
+	sheets, err := c.worksheets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	props, exists := sheets[table]
+	if !exists {
+		return nil, adapter.NewNotFoundError(dbcapabilities.GoogleSheets, "table", table)
+	}
+
+	records, err := c.client.getValues(ctx, quoteSheet(props.Title))
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.GoogleSheets, "get_values", err)
+	}
+
+	sheet := filesource.NewSheet(table, records, true)
+	return &worksheet{props: props, sheet: sheet, columns: filesource.InferColumns(sheet)}, nil
+}
+
+// headers returns the normalized column names of a worksheet's header row
+func (c *Connection) headers(ctx context.Context, title string) ([]string, error) {
+	records, err := c.client.getValues(ctx, quoteSheet(title)+"!1:1")
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.GoogleSheets, "get_headers", err)
+	}
+	return filesource.NewSheet(title, records, true).Headers, nil
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.GoogleSheets
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the spreadsheet is still accessible.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if _, err := c.client.getSpreadsheet(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.GoogleSheets, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return nil
+}
+
+// SchemaOperations returns the schema operator.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.GoogleSheets)
+}
+
+// MetadataOperations returns the metadata operator.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the Sheets API client.
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
+
+// cellValue converts a Go value into a value accepted by the Sheets API
+func cellValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string, bool, float64, float32, int, int32, int64:
+		return val
+	case []byte:
+		return string(val)
+	case time.Time:
+		// USER_ENTERED input lets Sheets parse this as a date-time
+		return val.Format("2006-01-02 15:04:05")
+	case fmt.Stringer:
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
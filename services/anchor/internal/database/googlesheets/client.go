@@ -0,0 +1,133 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+const (
+	defaultBaseURL  = "https://sheets.googleapis.com/v4/spreadsheets"
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+
+	// Sheets allows 60 requests per minute per user by default
+	defaultRequestsPerMinute = 60
+	// defaultBatchSize is the number of rows sent per append request
+	defaultBatchSize = 500
+)
+
+// sheetProperties is the subset of a worksheet's properties the adapter uses
+type sheetProperties struct {
+	SheetID        int64  `json:"sheetId"`
+	Title          string `json:"title"`
+	Index          int    `json:"index"`
+	GridProperties struct {
+		RowCount    int `json:"rowCount"`
+		ColumnCount int `json:"columnCount"`
+	} `json:"gridProperties"`
+}
+
+// spreadsheet is the subset of the spreadsheet resource the adapter uses
+type spreadsheet struct {
+	SpreadsheetID string `json:"spreadsheetId"`
+	Properties    struct {
+		Title    string `json:"title"`
+		Locale   string `json:"locale"`
+		TimeZone string `json:"timeZone"`
+	} `json:"properties"`
+	Sheets []struct {
+		Properties sheetProperties `json:"properties"`
+	} `json:"sheets"`
+}
+
+type valueRange struct {
+	Range  string          `json:"range,omitempty"`
+	Values [][]interface{} `json:"values"`
+}
+
+// Client wraps the Sheets REST API for a single spreadsheet
+type Client struct {
+	api           *restclient.Client
+	spreadsheetID string
+}
+
+// getSpreadsheet returns the spreadsheet properties and its worksheets
+func (c *Client) getSpreadsheet(ctx context.Context) (*spreadsheet, error) {
+	var result spreadsheet
+	query := url.Values{"fields": {"spreadsheetId,properties(title,locale,timeZone),sheets.properties"}}
+	if err := c.api.Do(ctx, http.MethodGet, "/"+url.PathEscape(c.spreadsheetID), query, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getValues returns the formatted cell values of a range
+func (c *Client) getValues(ctx context.Context, rangeA1 string) ([][]string, error) {
+	var result valueRange
+	query := url.Values{
+		"valueRenderOption":    {"FORMATTED_VALUE"},
+		"dateTimeRenderOption": {"FORMATTED_STRING"},
+	}
+	if err := c.api.Do(ctx, http.MethodGet, c.valuesPath(rangeA1, ""), query, nil, &result); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, len(result.Values))
+	for i, row := range result.Values {
+		record := make([]string, len(row))
+		for j, v := range row {
+			if v != nil {
+				record[j] = fmt.Sprint(v)
+			}
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// appendValues appends rows after the last row of the table in rangeA1
+func (c *Client) appendValues(ctx context.Context, rangeA1 string, rows [][]interface{}) error {
+	query := url.Values{
+		"valueInputOption": {"USER_ENTERED"},
+		"insertDataOption": {"INSERT_ROWS"},
+	}
+	return c.api.Do(ctx, http.MethodPost, c.valuesPath(rangeA1, ":append"), query, valueRange{Values: rows}, nil)
+}
+
+// updateValues overwrites the cells of a range
+func (c *Client) updateValues(ctx context.Context, rangeA1 string, rows [][]interface{}) error {
+	query := url.Values{"valueInputOption": {"RAW"}}
+	return c.api.Do(ctx, http.MethodPut, c.valuesPath(rangeA1, ""), query, valueRange{Range: rangeA1, Values: rows}, nil)
+}
+
+// clearValues clears the cells of a range while keeping formatting
+func (c *Client) clearValues(ctx context.Context, rangeA1 string) error {
+	return c.api.Do(ctx, http.MethodPost, c.valuesPath(rangeA1, ":clear"), nil, struct{}{}, nil)
+}
+
+// addSheet creates a new worksheet
+func (c *Client) addSheet(ctx context.Context, title string) error {
+	body := map[string]interface{}{
+		"requests": []interface{}{
+			map[string]interface{}{
+				"addSheet": map[string]interface{}{
+					"properties": map[string]interface{}{"title": title},
+				},
+			},
+		},
+	}
+	return c.api.Do(ctx, http.MethodPost, "/"+url.PathEscape(c.spreadsheetID)+":batchUpdate", nil, body, nil)
+}
+
+func (c *Client) valuesPath(rangeA1, action string) string {
+	return "/" + url.PathEscape(c.spreadsheetID) + "/values/" + url.PathEscape(rangeA1) + action
+}
+
+// quoteSheet quotes a worksheet title for use in A1 notation
+func quoteSheet(title string) string {
+	return "'" + strings.ReplaceAll(title, "'", "''") + "'"
+}
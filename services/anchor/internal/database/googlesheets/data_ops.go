@@ -0,0 +1,220 @@
+package googlesheets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/filesource"
+)
+
+// DataOps implements data operations for Google Sheets.
+// Worksheets have no keys, so only appends are supported as writes.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves rows from a worksheet.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves rows with specific columns.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	rows, _, err := d.readRows(ctx, table, columns, 0, limit)
+	return rows, err
+}
+
+// Stream returns a batch of rows starting at the given offset.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	rows, total, err := d.readRows(ctx, params.Table, params.Columns, params.Offset, int(params.BatchSize))
+	if err != nil {
+		return adapter.StreamResult{}, err
+	}
+
+	next := params.Offset + int64(len(rows))
+	return adapter.StreamResult{
+		Data:       rows,
+		HasMore:    next < int64(total),
+		NextCursor: fmt.Sprintf("%d", next),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// readRows converts a window of worksheet rows into typed records
+func (d *DataOps) readRows(ctx context.Context, table string, columns []string, offset int64, limit int) ([]map[string]interface{}, int, error) {
+	ws, err := d.conn.loadTable(ctx, table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indexes := make([]int, 0, len(ws.columns))
+	if len(columns) == 0 {
+		for i := range ws.columns {
+			indexes = append(indexes, i)
+		}
+	} else {
+		positions := make(map[string]int, len(ws.columns))
+		for i, col := range ws.columns {
+			positions[col.Name] = i
+		}
+		for _, name := range columns {
+			i, exists := positions[name]
+			if !exists {
+				return nil, 0, adapter.NewNotFoundError(dbcapabilities.GoogleSheets, "column", name)
+			}
+			indexes = append(indexes, i)
+		}
+	}
+
+	total := len(ws.sheet.Rows)
+	if offset < 0 || offset >= int64(total) {
+		return []map[string]interface{}{}, total, nil
+	}
+	end := total
+	if limit > 0 && int(offset)+limit < end {
+		end = int(offset) + limit
+	}
+
+	rows := make([]map[string]interface{}, 0, end-int(offset))
+	for _, raw := range ws.sheet.Rows[offset:end] {
+		row := make(map[string]interface{}, len(indexes))
+		for _, i := range indexes {
+			row[ws.columns[i].Name] = filesource.ConvertValue(ws.columns[i].DataType, raw[i])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, total, nil
+}
+
+// Insert appends rows to a worksheet in batches. Columns are matched against the header row.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	sheets, err := d.conn.worksheets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	props, exists := sheets[table]
+	if !exists {
+		return 0, adapter.NewNotFoundError(dbcapabilities.GoogleSheets, "table", table)
+	}
+
+	headers, err := d.conn.headers(ctx, props.Title)
+	if err != nil {
+		return 0, err
+	}
+	if len(headers) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.GoogleSheets, "table", fmt.Sprintf("worksheet %q has no header row", props.Title))
+	}
+
+	positions := make(map[string]int, len(headers))
+	for i, h := range headers {
+		positions[h] = i
+	}
+
+	values := make([][]interface{}, 0, len(data))
+	for _, record := range data {
+		row := make([]interface{}, len(headers))
+		for i := range row {
+			row[i] = ""
+		}
+		for key, value := range record {
+			i, ok := positions[key]
+			if !ok {
+				i, ok = positions[filesource.ColumnName(key)]
+			}
+			if !ok {
+				return 0, adapter.NewNotFoundError(dbcapabilities.GoogleSheets, "column", key)
+			}
+			row[i] = cellValue(value)
+		}
+		values = append(values, row)
+	}
+
+	batchSize := d.conn.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	var inserted int64
+	for start := 0; start < len(values); start += batchSize {
+		end := start + batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+		if err := d.conn.client.appendValues(ctx, quoteSheet(props.Title), values[start:end]); err != nil {
+			return inserted, adapter.WrapError(dbcapabilities.GoogleSheets, "insert", err)
+		}
+		inserted += int64(end - start)
+	}
+
+	return inserted, nil
+}
+
+// Update is not supported because worksheets have no keys.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "update", "worksheets only support appending rows")
+}
+
+// Upsert is not supported because worksheets have no keys.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "upsert", "worksheets only support appending rows")
+}
+
+// Delete is not supported because worksheets have no keys.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "delete", "worksheets only support appending rows")
+}
+
+// ExecuteQuery is not supported because Google Sheets has no query language.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "execute query", "google sheets has no query language")
+}
+
+// ExecuteCountQuery is not supported because Google Sheets has no query language.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "execute count query", "google sheets has no query language")
+}
+
+// GetRowCount returns the number of data rows in a worksheet. Where clauses are not supported.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	if whereClause != "" {
+		return 0, false, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "filtered row count", "google sheets has no query language")
+	}
+	ws, err := d.conn.loadTable(ctx, table)
+	if err != nil {
+		return 0, false, err
+	}
+	return int64(len(ws.sheet.Rows)), true, nil
+}
+
+// Wipe clears all data rows, keeping the header row of every worksheet.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	sheets, err := d.conn.worksheets(ctx)
+	if err != nil {
+		return err
+	}
+	for _, props := range sheets {
+		if err := d.conn.client.clearValues(ctx, quoteSheet(props.Title)+"!2:"+fmt.Sprint(maxRows(props))); err != nil {
+			return adapter.WrapError(dbcapabilities.GoogleSheets, "wipe", err)
+		}
+	}
+	return nil
+}
+
+// maxRows returns the last row index of a worksheet's grid
+func maxRows(props sheetProperties) int {
+	if props.GridProperties.RowCount > 1 {
+		return props.GridProperties.RowCount
+	}
+	return 2
+}
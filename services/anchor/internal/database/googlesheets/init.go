@@ -0,0 +1,8 @@
+package googlesheets
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register Google Sheets adapter with the global registry
+	adapter.Register(NewAdapter())
+}
@@ -0,0 +1,76 @@
+package googlesheets
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations for Google Sheets.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the spreadsheet.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	ss, err := m.conn.client.getSpreadsheet(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.GoogleSheets, "collect_database_metadata", err)
+	}
+
+	sheets := make([]map[string]interface{}, 0, len(ss.Sheets))
+	for _, s := range ss.Sheets {
+		sheets = append(sheets, map[string]interface{}{
+			"sheet_id":     s.Properties.SheetID,
+			"title":        s.Properties.Title,
+			"row_count":    s.Properties.GridProperties.RowCount,
+			"column_count": s.Properties.GridProperties.ColumnCount,
+		})
+	}
+
+	return map[string]interface{}{
+		"database_type":     string(dbcapabilities.GoogleSheets),
+		"spreadsheet_id":    ss.SpreadsheetID,
+		"title":             ss.Properties.Title,
+		"locale":            ss.Properties.Locale,
+		"time_zone":         ss.Properties.TimeZone,
+		"table_count":       len(ss.Sheets),
+		"sheets":            sheets,
+		"unique_identifier": ss.SpreadsheetID,
+	}, nil
+}
+
+// CollectInstanceMetadata returns the same metadata as the database, since a spreadsheet has no instance.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return m.CollectDatabaseMetadata(ctx)
+}
+
+// GetVersion returns the Sheets API version.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return "v4", nil
+}
+
+// GetUniqueIdentifier returns the spreadsheet ID.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return m.conn.client.spreadsheetID, nil
+}
+
+// GetDatabaseSize is not reported by the Sheets API.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "get database size", "not reported by the sheets api")
+}
+
+// GetTableCount returns the number of worksheets.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	sheets, err := m.conn.worksheets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(sheets), nil
+}
+
+// ExecuteCommand is not supported for Google Sheets.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.GoogleSheets, "execute command", "google sheets does not accept commands")
+}
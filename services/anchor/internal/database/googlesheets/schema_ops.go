@@ -0,0 +1,138 @@
+package googlesheets
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/database/filesource"
+)
+
+// SchemaOps implements schema operations for Google Sheets.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema infers a table per worksheet.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	names, err := s.ListTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]unifiedmodel.Table, len(names))
+	for _, name := range names {
+		table, err := s.GetTableSchema(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		tables[name] = *table
+	}
+
+	return &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.GoogleSheets,
+		Tables:       tables,
+	}, nil
+}
+
+// CreateStructure adds a worksheet with a header row for every table that does not exist yet.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if model == nil {
+		return adapter.NewConfigurationError(dbcapabilities.GoogleSheets, "model", "unified model cannot be nil")
+	}
+
+	existing, err := s.conn.worksheets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, table := range model.Tables {
+		if _, exists := existing[filesource.TableName(name)]; exists {
+			continue
+		}
+		if err := s.conn.client.addSheet(ctx, name); err != nil {
+			return adapter.WrapError(dbcapabilities.GoogleSheets, "create_structure", err)
+		}
+
+		header := make([]interface{}, 0, len(table.Columns))
+		for _, col := range orderedColumns(table) {
+			header = append(header, col)
+		}
+		if len(header) > 0 {
+			if err := s.conn.client.updateValues(ctx, quoteSheet(name)+"!A1", [][]interface{}{header}); err != nil {
+				return adapter.WrapError(dbcapabilities.GoogleSheets, "create_structure", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ListTables returns the table names derived from the worksheet titles.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	sheets, err := s.conn.worksheets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(sheets))
+	for name := range sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetTableSchema returns the inferred schema for a single worksheet.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	ws, err := s.conn.loadTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &unifiedmodel.Table{
+		Name:    tableName,
+		Columns: make(map[string]unifiedmodel.Column, len(ws.columns)),
+		Options: map[string]any{
+			"sheet_id":    ws.props.SheetID,
+			"sheet_title": ws.props.Title,
+			"row_count":   len(ws.sheet.Rows),
+		},
+	}
+
+	for i, col := range ws.columns {
+		position := i + 1
+		column := unifiedmodel.Column{
+			Name:            col.Name,
+			DataType:        col.DataType,
+			Nullable:        col.Nullable,
+			OrdinalPosition: &position,
+		}
+		if col.DataType == filesource.TypeString {
+			column.Options = map[string]any{"max_length": col.MaxLen}
+		}
+		table.Columns[col.Name] = column
+	}
+
+	return table, nil
+}
+
+// orderedColumns returns column names by ordinal position, then by name
+func orderedColumns(table unifiedmodel.Table) []string {
+	names := make([]string, 0, len(table.Columns))
+	for name := range table.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := table.Columns[names[i]].OrdinalPosition, table.Columns[names[j]].OrdinalPosition
+		if pi != nil && pj != nil && *pi != *pj {
+			return *pi < *pj
+		}
+		if (pi == nil) != (pj == nil) {
+			return pi != nil
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,298 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// Adapter implements adapter.DatabaseAdapter for HubSpot.
+// Every CRM object type (contacts, companies, deals, ... and custom objects) is exposed as a collection.
+//
+// The token is read from Token (or Password) and may be a private app token or an OAuth access
+// token obtained through the integration service. OAuth tokens are refreshed automatically when
+// options.refresh_token, options.client_id and options.client_secret are set.
+//
+// Options:
+//   - objects: comma-separated object types to expose; defaults to the standard CRM objects and all custom objects
+//   - requests_per_second: client-side request rate limit
+type Adapter struct{}
+
+// NewAdapter creates a new HubSpot adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.HubSpot
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.HubSpot)
+}
+
+// Connect verifies access to the portal.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	accessToken := config.Token
+	if accessToken == "" {
+		accessToken = config.Password
+	}
+	refreshToken := optionString(config, "refresh_token")
+	if accessToken == "" && refreshToken == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.HubSpot, "token", "a private app token or OAuth token is required")
+	}
+
+	tokenURL := optionString(config, "token_url")
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	tokens := restclient.NewTokenSource(accessToken, refreshToken,
+		optionString(config, "client_id"), optionString(config, "client_secret"), tokenURL)
+
+	baseURL := defaultBaseURL
+	if config.Host != "" && config.Host != "api.hubapi.com" {
+		baseURL = strings.TrimRight(config.Host, "/")
+	}
+
+	client := &Client{
+		api: restclient.NewClient(baseURL, tokens, optionFloat(config, "requests_per_second", defaultRequestsPerSecond)),
+	}
+
+	info, err := client.accountInfo(ctx)
+	if err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.HubSpot, config.Host, config.Port, err)
+	}
+	portalID := ""
+	if id, ok := info["portalId"].(float64); ok {
+		portalID = fmt.Sprintf("%.0f", id)
+	}
+
+	var objects []string
+	for _, o := range strings.Split(optionString(config, "objects"), ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			objects = append(objects, o)
+		}
+	}
+
+	return &Connection{
+		id:        config.DatabaseID,
+		client:    client,
+		portalID:  portalID,
+		objects:   objects,
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance is not supported; each portal is connected as a database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "instance connection", "portals are connected as individual databases")
+}
+
+func optionString(config adapter.ConnectionConfig, key string) string {
+	if v, ok := config.Options[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func optionFloat(config adapter.ConnectionConfig, key string, def float64) float64 {
+	switch v := config.Options[key].(type) {
+	case float64:
+		if v > 0 {
+			return v
+		}
+	case int:
+		if v > 0 {
+			return float64(v)
+		}
+	}
+	return def
+}
+
+// Connection implements adapter.Connection for a HubSpot portal.
+type Connection struct {
+	id        string
+	client    *Client
+	portalID  string
+	objects   []string
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+
+	// cursorsMu guards cursors, the paging cursors keyed by object type and stream offset
+	cursorsMu sync.Mutex
+	cursors   map[string]string
+}
+
+// objectTypes returns the exposed object types mapped to the identifier used in API paths;
+// custom objects are addressed by their object type ID
+func (c *Connection) objectTypes(ctx context.Context) (map[string]string, error) {
+	types := make(map[string]string)
+	for _, name := range standardObjects {
+		types[name] = name
+	}
+	schemas, err := c.client.schemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		types[s.Name] = s.ObjectTypeID
+	}
+
+	if len(c.objects) == 0 {
+		return types, nil
+	}
+	selected := make(map[string]string, len(c.objects))
+	for _, name := range c.objects {
+		if id, ok := types[name]; ok {
+			selected[name] = id
+		}
+	}
+	return selected, nil
+}
+
+// objectNames returns the sorted names of the exposed object types
+func (c *Connection) objectNames(ctx context.Context) ([]string, error) {
+	types, err := c.objectTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// objectPath returns the API identifier of an object type
+func (c *Connection) objectPath(ctx context.Context, name string) (string, error) {
+	for _, s := range standardObjects {
+		if s == name {
+			return name, nil
+		}
+	}
+	types, err := c.objectTypes(ctx)
+	if err != nil {
+		return "", adapter.WrapError(dbcapabilities.HubSpot, "list_objects", err)
+	}
+	if id, ok := types[name]; ok {
+		return id, nil
+	}
+	// Object type IDs (2-1234567) are accepted as-is
+	if strings.HasPrefix(name, "2-") {
+		return name, nil
+	}
+	return "", adapter.NewNotFoundError(dbcapabilities.HubSpot, "object", name)
+}
+
+// propertyTypes returns the HubSpot type of every property of an object type
+func (c *Connection) propertyTypes(ctx context.Context, path string) (map[string]string, error) {
+	props, err := c.client.objectProperties(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]string, len(props))
+	for _, p := range props {
+		types[p.Name] = p.Type
+	}
+	return types, nil
+}
+
+// streamCursor returns the paging cursor that resumes a stream at offset
+func (c *Connection) streamCursor(object string, offset int64) (string, bool) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	cursor, ok := c.cursors[fmt.Sprintf("%s:%d", object, offset)]
+	return cursor, ok
+}
+
+// setStreamCursor remembers the paging cursor that resumes a stream at offset
+func (c *Connection) setStreamCursor(object string, offset int64, cursor string) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	if c.cursors == nil {
+		c.cursors = make(map[string]string)
+	}
+	c.cursors[fmt.Sprintf("%s:%d", object, offset)] = cursor
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.HubSpot
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the portal is still accessible.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if _, err := c.client.accountInfo(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.HubSpot, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return nil
+}
+
+// SchemaOperations returns the schema operator.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return &ReplicationOps{conn: c}
+}
+
+// MetadataOperations returns the metadata operator.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the HubSpot API client.
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
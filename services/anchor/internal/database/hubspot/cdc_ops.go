@@ -0,0 +1,269 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"google.golang.org/grpc"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+)
+
+// ParseEvent converts a polled record change to a standardized CDCEvent.
+func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]interface{}) (*adapter.CDCEvent, error) {
+	operation, _ := rawEvent["operation"].(string)
+	tableName, _ := rawEvent["table_name"].(string)
+	recordID, _ := rawEvent["record_id"].(string)
+	if operation == "" || tableName == "" || recordID == "" {
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.HubSpot,
+			"parse_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("error", "operation, table_name and record_id are required")
+	}
+
+	event := &adapter.CDCEvent{
+		TableName: tableName,
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"record_id": recordID,
+		},
+	}
+	if ms, ok := rawEvent["modified_at"].(int64); ok && ms > 0 {
+		event.Timestamp = time.UnixMilli(ms)
+		event.LSN = fmt.Sprintf("%d:%s", ms, recordID)
+	}
+
+	data := make(map[string]interface{})
+	if payload, ok := rawEvent["data"].(map[string]interface{}); ok {
+		for k, v := range payload {
+			data[k] = v
+		}
+	}
+	data[idField] = recordID
+
+	switch operation {
+	case "INSERT":
+		event.Operation = adapter.CDCInsert
+		event.Data = data
+	case "UPDATE":
+		event.Operation = adapter.CDCUpdate
+		event.Data = data
+		event.OldData = map[string]interface{}{idField: recordID}
+	default:
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.HubSpot,
+			"parse_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("operation", operation)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "parse_cdc_event", err)
+	}
+	return event, nil
+}
+
+// ApplyCDCEvent applies a standardized CDC event to HubSpot through the batch API.
+// Updates and deletes identify the record by the id field of the event.
+func (r *ReplicationOps) ApplyCDCEvent(ctx context.Context, event *adapter.CDCEvent) error {
+	if err := event.Validate(); err != nil {
+		return adapter.WrapError(dbcapabilities.HubSpot, "apply_cdc_event", err)
+	}
+	path, err := r.conn.objectPath(ctx, event.TableName)
+	if err != nil {
+		return err
+	}
+
+	var input batchInput
+	var action string
+	switch event.Operation {
+	case adapter.CDCInsert:
+		action = "create"
+		input = batchInput{Properties: r.recordProperties(event.Data)}
+	case adapter.CDCUpdate:
+		id := recordID(event)
+		if id == "" {
+			return adapter.NewDatabaseError(dbcapabilities.HubSpot, "apply_cdc_update", adapter.ErrInvalidData).
+				WithContext("error", "no id to identify the record for UPDATE")
+		}
+		action = "update"
+		input = batchInput{ID: id, Properties: r.recordProperties(event.Data)}
+	case adapter.CDCDelete:
+		id := recordID(event)
+		if id == "" {
+			return adapter.NewDatabaseError(dbcapabilities.HubSpot, "apply_cdc_delete", adapter.ErrInvalidData).
+				WithContext("error", "no id to identify the record for DELETE")
+		}
+		action = "archive"
+		input = batchInput{ID: id}
+	default:
+		return adapter.NewDatabaseError(
+			dbcapabilities.HubSpot,
+			"apply_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("operation", string(event.Operation))
+	}
+
+	resp, err := r.conn.client.batch(ctx, path, action, []batchInput{input})
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.HubSpot, "apply_cdc_event", err)
+	}
+	if err := resp.err(); err != nil {
+		return adapter.WrapError(dbcapabilities.HubSpot, "apply_cdc_event", err)
+	}
+	return nil
+}
+
+// recordID returns the HubSpot record ID of an event
+func recordID(event *adapter.CDCEvent) string {
+	for _, data := range []map[string]interface{}{event.OldData, event.Data} {
+		if id, ok := data[idField]; ok && id != nil {
+			return fmt.Sprint(id)
+		}
+	}
+	return ""
+}
+
+// recordProperties returns the event fields to write, without the id, metadata and read-only fields
+func (r *ReplicationOps) recordProperties(data map[string]interface{}) map[string]interface{} {
+	properties := writableProperties(data, idField)
+	for k := range properties {
+		if r.isMetadataField(k) {
+			delete(properties, k)
+		}
+	}
+	return properties
+}
+
+// TransformData applies transformation rules to event data.
+func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]interface{}, rules []adapter.TransformationRule, transformationServiceEndpoint string) (map[string]interface{}, error) {
+	if len(rules) == 0 {
+		return data, nil
+	}
+
+	transformedData := make(map[string]interface{})
+
+	// Create transformation service client if endpoint is provided
+	var transformClient transformationv1.TransformationServiceClient
+	var grpcConn *grpc.ClientConn
+	if transformationServiceEndpoint != "" {
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
+		if err == nil {
+			transformClient = transformationv1.NewTransformationServiceClient(conn)
+			grpcConn = conn
+			defer conn.Close()
+		}
+	}
+
+	// Apply each transformation rule
+	for _, rule := range rules {
+		sourceValue, exists := data[rule.SourceColumn]
+		if !exists {
+			continue
+		}
+
+		var transformedValue interface{}
+		var err error
+
+		if rule.TransformationName != "" && rule.TransformationName != "direct_mapping" && grpcConn != nil {
+			transformedValue, err = callTransformationService(ctx, transformClient, rule.TransformationName, sourceValue)
+			if err != nil {
+				transformedValue = sourceValue
+			}
+		} else {
+			transformType := rule.TransformationType
+			if transformType == "" && rule.TransformationName != "" {
+				transformType = rule.TransformationName
+			}
+
+			switch transformType {
+			case adapter.TransformDirect, "direct_mapping":
+				transformedValue = sourceValue
+			case adapter.TransformUppercase:
+				if str, ok := sourceValue.(string); ok {
+					transformedValue = strings.ToUpper(str)
+				} else {
+					transformedValue = sourceValue
+				}
+			case adapter.TransformLowercase:
+				if str, ok := sourceValue.(string); ok {
+					transformedValue = strings.ToLower(str)
+				} else {
+					transformedValue = sourceValue
+				}
+			case adapter.TransformCast:
+				transformedValue = sourceValue
+			case adapter.TransformDefault:
+				if sourceValue == nil {
+					if defaultVal, ok := rule.Parameters["default_value"]; ok {
+						transformedValue = defaultVal
+					} else {
+						transformedValue = nil
+					}
+				} else {
+					transformedValue = sourceValue
+				}
+			default:
+				transformedValue = sourceValue
+			}
+		}
+
+		transformedData[rule.TargetColumn] = transformedValue
+	}
+
+	return transformedData, nil
+}
+
+// callTransformationService calls the transformation service to apply a custom transformation.
+func callTransformationService(ctx context.Context, client transformationv1.TransformationServiceClient, transformationName string, value interface{}) (interface{}, error) {
+	var inputStr string
+	switch v := value.(type) {
+	case string:
+		inputStr = v
+	case nil:
+		return nil, nil
+	default:
+		inputStr = fmt.Sprintf("%v", v)
+	}
+
+	transformReq := &transformationv1.TransformRequest{
+		FunctionName: transformationName,
+		Input:        inputStr,
+	}
+
+	transformResp, err := client.Transform(ctx, transformReq)
+	if err != nil {
+		return nil, fmt.Errorf("transformation service error: %v", err)
+	}
+
+	if transformResp.Status != commonv1.Status_STATUS_SUCCESS {
+		return nil, fmt.Errorf("transformation failed: %s", transformResp.StatusMessage)
+	}
+
+	return transformResp.Output, nil
+}
+
+// isMetadataField checks if a field name is a metadata field or a property HubSpot sets itself.
+func (r *ReplicationOps) isMetadataField(fieldName string) bool {
+	metadataFields := map[string]bool{
+		"message_type":        true,
+		"database_id":         true,
+		"timestamp":           true,
+		"operation":           true,
+		"table_name":          true,
+		"record_id":           true,
+		"hs_object_id":        true,
+		"createdate":          true,
+		"hs_createdate":       true,
+		"lastmodifieddate":    true,
+		"hs_lastmodifieddate": true,
+	}
+	return metadataFields[fieldName]
+}
@@ -0,0 +1,224 @@
+package hubspot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+const (
+	defaultBaseURL           = "https://api.hubapi.com"
+	defaultTokenURL          = "https://api.hubapi.com/oauth/v1/token"
+	defaultRequestsPerSecond = 9
+
+	// pageSize is the maximum number of records per list or search request
+	pageSize = 100
+	// batchSize is the maximum number of inputs per batch request
+	batchSize = 100
+	// searchResultLimit is the number of results a single search can page through
+	searchResultLimit = 10000
+)
+
+// standardObjects are the CRM object types exposed besides custom objects.
+var standardObjects = []string{"companies", "contacts", "deals", "line_items", "products", "quotes", "tickets"}
+
+// Client is a HubSpot CRM API client for one portal.
+type Client struct {
+	api *restclient.Client
+
+	mu         sync.Mutex
+	properties map[string][]property
+}
+
+// objectSchema is a custom object definition.
+type objectSchema struct {
+	Name               string `json:"name"`
+	ObjectTypeID       string `json:"objectTypeId"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Labels             struct {
+		Singular string `json:"singular"`
+		Plural   string `json:"plural"`
+	} `json:"labels"`
+	PrimaryDisplayProperty string `json:"primaryDisplayProperty"`
+}
+
+// property is a CRM object property definition.
+type property struct {
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Type        string `json:"type"`
+	FieldType   string `json:"fieldType"`
+	Description string `json:"description"`
+	GroupName   string `json:"groupName"`
+	Options     []struct {
+		Label  string `json:"label"`
+		Value  string `json:"value"`
+		Hidden bool   `json:"hidden"`
+	} `json:"options"`
+	Calculated           bool `json:"calculated"`
+	HasUniqueValue       bool `json:"hasUniqueValue"`
+	Hidden               bool `json:"hidden"`
+	DisplayOrder         int  `json:"displayOrder"`
+	ModificationMetadata struct {
+		ReadOnlyValue bool `json:"readOnlyValue"`
+	} `json:"modificationMetadata"`
+}
+
+// crmObject is a CRM record.
+type crmObject struct {
+	ID         string                 `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+	CreatedAt  string                 `json:"createdAt"`
+	UpdatedAt  string                 `json:"updatedAt"`
+	Archived   bool                   `json:"archived"`
+}
+
+// objectPage is a page of list or search results.
+type objectPage struct {
+	Total   int64       `json:"total"`
+	Results []crmObject `json:"results"`
+	Paging  struct {
+		Next struct {
+			After string `json:"after"`
+		} `json:"next"`
+	} `json:"paging"`
+}
+
+// searchRequest is the body of a CRM search.
+type searchRequest struct {
+	FilterGroups []filterGroup `json:"filterGroups,omitempty"`
+	Sorts        []sortOrder   `json:"sorts,omitempty"`
+	Properties   []string      `json:"properties,omitempty"`
+	Limit        int           `json:"limit"`
+	After        string        `json:"after,omitempty"`
+}
+
+type filterGroup struct {
+	Filters []filter `json:"filters"`
+}
+
+type filter struct {
+	PropertyName string `json:"propertyName"`
+	Operator     string `json:"operator"`
+	Value        string `json:"value,omitempty"`
+}
+
+type sortOrder struct {
+	PropertyName string `json:"propertyName"`
+	Direction    string `json:"direction"`
+}
+
+// batchInput is an input of a batch create, update, upsert or archive request.
+type batchInput struct {
+	ID         string                 `json:"id,omitempty"`
+	IDProperty string                 `json:"idProperty,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// batchResponse is the response of a batch request; partial failures are listed in errors.
+type batchResponse struct {
+	Results []crmObject `json:"results"`
+	Errors  []struct {
+		Category string `json:"category"`
+		Message  string `json:"message"`
+	} `json:"errors"`
+}
+
+func (r *batchResponse) err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", r.Errors[0].Category, r.Errors[0].Message)
+}
+
+// schemas returns the custom object definitions of the portal
+func (c *Client) schemas(ctx context.Context) ([]objectSchema, error) {
+	var resp struct {
+		Results []objectSchema `json:"results"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/crm/v3/schemas", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+// objectProperties returns the property definitions of an object type. Results are cached
+// for the lifetime of the client.
+func (c *Client) objectProperties(ctx context.Context, objectType string) ([]property, error) {
+	c.mu.Lock()
+	cached, ok := c.properties[objectType]
+	c.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var resp struct {
+		Results []property `json:"results"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, "/crm/v3/properties/"+url.PathEscape(objectType), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.properties == nil {
+		c.properties = make(map[string][]property)
+	}
+	c.properties[objectType] = resp.Results
+	c.mu.Unlock()
+	return resp.Results, nil
+}
+
+// listObjects returns a page of records
+func (c *Client) listObjects(ctx context.Context, objectType string, properties []string, after string, limit int) (*objectPage, error) {
+	query := url.Values{}
+	if limit <= 0 || limit > pageSize {
+		limit = pageSize
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	if len(properties) > 0 {
+		query.Set("properties", strings.Join(properties, ","))
+	}
+	if after != "" {
+		query.Set("after", after)
+	}
+
+	var page objectPage
+	if err := c.api.Do(ctx, http.MethodGet, "/crm/v3/objects/"+url.PathEscape(objectType), query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// search runs a CRM search
+func (c *Client) search(ctx context.Context, objectType string, req searchRequest) (*objectPage, error) {
+	var page objectPage
+	if err := c.api.Do(ctx, http.MethodPost, "/crm/v3/objects/"+url.PathEscape(objectType)+"/search", nil, req, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// batch sends up to batchSize inputs to a batch endpoint (create, update, upsert or archive)
+func (c *Client) batch(ctx context.Context, objectType, action string, inputs []batchInput) (*batchResponse, error) {
+	var resp batchResponse
+	path := "/crm/v3/objects/" + url.PathEscape(objectType) + "/batch/" + action
+	if err := c.api.Do(ctx, http.MethodPost, path, nil, map[string]interface{}{"inputs": inputs}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// accountInfo returns the details of the portal the token belongs to
+func (c *Client) accountInfo(ctx context.Context) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	if err := c.api.Do(ctx, http.MethodGet, "/account-info/v3/details", nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
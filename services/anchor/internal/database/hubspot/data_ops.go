@@ -0,0 +1,507 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DataOps implements data operations for HubSpot.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves records of an object type.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves records with specific properties, paging through the list API.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	path, types, columns, err := d.prepare(ctx, table, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	after := ""
+	for {
+		pageLimit := pageSize
+		if limit > 0 && limit-len(rows) < pageLimit {
+			pageLimit = limit - len(rows)
+		}
+		page, err := d.conn.client.listObjects(ctx, path, columns, after, pageLimit)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.HubSpot, "fetch", err)
+		}
+		for _, obj := range page.Results {
+			rows = append(rows, convertObject(obj, types))
+		}
+		after = page.Paging.Next.After
+		if after == "" || (limit > 0 && len(rows) >= limit) {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// Stream returns a batch of records starting at the given offset. The paging cursor that ended
+// each batch is remembered on the connection so the next batch resumes from it; unknown offsets
+// page forward from the start.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	path, types, columns, err := d.prepare(ctx, params.Table, params.Columns)
+	if err != nil {
+		return adapter.StreamResult{}, err
+	}
+	batchSize := int(params.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	after, ok := d.conn.streamCursor(params.Table, params.Offset)
+	if !ok && params.Offset > 0 {
+		for skipped := int64(0); skipped < params.Offset; {
+			limit := pageSize
+			if remaining := params.Offset - skipped; remaining < int64(limit) {
+				limit = int(remaining)
+			}
+			page, err := d.conn.client.listObjects(ctx, path, []string{"hs_object_id"}, after, limit)
+			if err != nil {
+				return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.HubSpot, "stream", err)
+			}
+			skipped += int64(len(page.Results))
+			after = page.Paging.Next.After
+			if after == "" {
+				return adapter.StreamResult{NextCursor: strconv.FormatInt(params.Offset, 10)}, nil
+			}
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, batchSize)
+	hasMore := true
+	for len(rows) < batchSize {
+		limit := pageSize
+		if remaining := batchSize - len(rows); remaining < limit {
+			limit = remaining
+		}
+		page, err := d.conn.client.listObjects(ctx, path, columns, after, limit)
+		if err != nil {
+			return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.HubSpot, "stream", err)
+		}
+		for _, obj := range page.Results {
+			rows = append(rows, convertObject(obj, types))
+		}
+		after = page.Paging.Next.After
+		if after == "" {
+			hasMore = false
+			break
+		}
+	}
+
+	nextOffset := params.Offset + int64(len(rows))
+	if hasMore {
+		d.conn.setStreamCursor(params.Table, nextOffset, after)
+	}
+
+	return adapter.StreamResult{
+		Data:       rows,
+		HasMore:    hasMore,
+		NextCursor: strconv.FormatInt(nextOffset, 10),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// Insert creates records through the batch API. Records rejected by HubSpot are not
+// counted; the first rejection is returned after all batches are sent.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	inputs := make([]batchInput, len(data))
+	for i, row := range data {
+		inputs[i] = batchInput{Properties: writableProperties(row, idField)}
+	}
+	n, err := d.sendBatches(ctx, path, "create", inputs)
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.HubSpot, "insert", err)
+	}
+	return n, nil
+}
+
+// Update updates records. Rows are matched on their id unless other where columns are given,
+// in which case the matching records are looked up with a search first.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	if len(whereColumns) == 0 {
+		whereColumns = []string{idField}
+	}
+
+	var inputs []batchInput
+	for _, row := range data {
+		if len(whereColumns) == 1 && whereColumns[0] == idField {
+			id := fmt.Sprint(row[idField])
+			if row[idField] == nil || id == "" {
+				return 0, adapter.NewConfigurationError(dbcapabilities.HubSpot, "data", "rows must carry an id to be updated")
+			}
+			inputs = append(inputs, batchInput{ID: id, Properties: writableProperties(row, idField)})
+			continue
+		}
+
+		conditions := make(map[string]interface{}, len(whereColumns))
+		for _, c := range whereColumns {
+			conditions[c] = row[c]
+		}
+		ids, err := d.matchingIDs(ctx, path, conditions)
+		if err != nil {
+			return 0, adapter.WrapError(dbcapabilities.HubSpot, "update", err)
+		}
+		properties := writableProperties(row, append([]string{idField}, whereColumns...)...)
+		for _, id := range ids {
+			inputs = append(inputs, batchInput{ID: id, Properties: properties})
+		}
+	}
+
+	n, err := d.sendBatches(ctx, path, "update", inputs)
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.HubSpot, "update", err)
+	}
+	return n, nil
+}
+
+// Upsert upserts records matched on a single unique property, such as email for contacts.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(uniqueColumns) != 1 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.HubSpot, "uniqueColumns",
+			"hubspot upserts match on exactly one unique property")
+	}
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+	idProperty := uniqueColumns[0]
+
+	inputs := make([]batchInput, 0, len(data))
+	for _, row := range data {
+		if row[idProperty] == nil {
+			return 0, adapter.NewConfigurationError(dbcapabilities.HubSpot, "data",
+				fmt.Sprintf("rows must carry a value for %s to be upserted", idProperty))
+		}
+		input := batchInput{ID: fmt.Sprint(row[idProperty]), Properties: writableProperties(row, idField)}
+		if idProperty != idField {
+			input.IDProperty = idProperty
+		}
+		inputs = append(inputs, input)
+	}
+
+	n, err := d.sendBatches(ctx, path, "upsert", inputs)
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.HubSpot, "upsert", err)
+	}
+	return n, nil
+}
+
+// Delete archives the records matching all conditions. Archived records can be restored in
+// HubSpot for 90 days.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.HubSpot, "conditions", "conditions are required to delete records")
+	}
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []string
+	if id, ok := conditions[idField]; ok && len(conditions) == 1 {
+		ids = []string{fmt.Sprint(id)}
+	} else if ids, err = d.matchingIDs(ctx, path, conditions); err != nil {
+		return 0, adapter.WrapError(dbcapabilities.HubSpot, "delete", err)
+	}
+
+	inputs := make([]batchInput, len(ids))
+	for i, id := range ids {
+		inputs[i] = batchInput{ID: id}
+	}
+	n, err := d.sendBatches(ctx, path, "archive", inputs)
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.HubSpot, "delete", err)
+	}
+	return n, nil
+}
+
+// ExecuteQuery runs a CRM search. The query is the JSON body of a search request with an
+// additional objectType key, e.g. {"objectType":"contacts","filterGroups":[...]}.
+// All pages up to the search result limit are returned.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	path, req, err := d.parseSearch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	types, err := d.conn.propertyTypes(ctx, path)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "execute_query", err)
+	}
+
+	var result []interface{}
+	for {
+		page, err := d.conn.client.search(ctx, path, req)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.HubSpot, "execute_query", err)
+		}
+		for _, obj := range page.Results {
+			result = append(result, convertObject(obj, types))
+		}
+		req.After = page.Paging.Next.After
+		if req.After == "" {
+			break
+		}
+	}
+	return result, nil
+}
+
+// ExecuteCountQuery runs a CRM search in the format of ExecuteQuery and returns its total.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	path, req, err := d.parseSearch(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	req.Limit = 1
+	req.Properties = []string{"hs_object_id"}
+	page, err := d.conn.client.search(ctx, path, req)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.HubSpot, "execute_count_query", err)
+	}
+	return page.Total, nil
+}
+
+// GetRowCount returns the number of records of an object type. Where clauses are not
+// supported; use ExecuteCountQuery with search filters instead.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	if whereClause != "" {
+		return 0, false, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "row count with where clause",
+			"hubspot filters records with search filter groups")
+	}
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return 0, false, err
+	}
+	page, err := d.conn.client.search(ctx, path, searchRequest{Properties: []string{"hs_object_id"}, Limit: 1})
+	if err != nil {
+		return 0, false, adapter.WrapError(dbcapabilities.HubSpot, "get_row_count", err)
+	}
+	return page.Total, true, nil
+}
+
+// Wipe is not supported; archiving all CRM data of a portal is never done by a mapping.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "wipe", "archiving all records of a portal is not supported")
+}
+
+// prepare resolves an object type, its property types and the properties to read,
+// which default to all properties
+func (d *DataOps) prepare(ctx context.Context, table string, columns []string) (string, map[string]string, []string, error) {
+	path, err := d.conn.objectPath(ctx, table)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	types, err := d.conn.propertyTypes(ctx, path)
+	if err != nil {
+		return "", nil, nil, adapter.WrapError(dbcapabilities.HubSpot, "get_properties", err)
+	}
+
+	properties := make([]string, 0, len(types))
+	if len(columns) == 0 {
+		for name := range types {
+			properties = append(properties, name)
+		}
+		sort.Strings(properties)
+	} else {
+		for _, c := range columns {
+			if c != idField {
+				properties = append(properties, c)
+			}
+		}
+	}
+	return path, types, properties, nil
+}
+
+// parseSearch decodes a search query in the ExecuteQuery format
+func (d *DataOps) parseSearch(ctx context.Context, query string) (string, searchRequest, error) {
+	var req struct {
+		searchRequest
+		ObjectType string `json:"objectType"`
+	}
+	if err := json.Unmarshal([]byte(query), &req); err != nil {
+		return "", searchRequest{}, adapter.NewDatabaseError(dbcapabilities.HubSpot, "parse_query", adapter.ErrInvalidData).
+			WithContext("error", err.Error())
+	}
+	if req.ObjectType == "" {
+		return "", searchRequest{}, adapter.NewConfigurationError(dbcapabilities.HubSpot, "objectType", "search queries must name an objectType")
+	}
+	path, err := d.conn.objectPath(ctx, req.ObjectType)
+	if err != nil {
+		return "", searchRequest{}, err
+	}
+	if req.Limit <= 0 || req.Limit > pageSize {
+		req.Limit = pageSize
+	}
+	return path, req.searchRequest, nil
+}
+
+// matchingIDs returns the IDs of the records matching all conditions. Searches page
+// through at most searchResultLimit records.
+func (d *DataOps) matchingIDs(ctx context.Context, path string, conditions map[string]interface{}) ([]string, error) {
+	keys := make([]string, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	group := filterGroup{}
+	for _, k := range keys {
+		name := k
+		if name == idField {
+			name = "hs_object_id"
+		}
+		if conditions[k] == nil {
+			group.Filters = append(group.Filters, filter{PropertyName: name, Operator: "NOT_HAS_PROPERTY"})
+			continue
+		}
+		group.Filters = append(group.Filters, filter{PropertyName: name, Operator: "EQ", Value: propertyValue(conditions[k])})
+	}
+
+	req := searchRequest{
+		FilterGroups: []filterGroup{group},
+		Properties:   []string{"hs_object_id"},
+		Limit:        pageSize,
+	}
+	var ids []string
+	for {
+		page, err := d.conn.client.search(ctx, path, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Results {
+			ids = append(ids, obj.ID)
+		}
+		req.After = page.Paging.Next.After
+		if req.After == "" {
+			break
+		}
+		if len(ids) >= searchResultLimit {
+			return nil, fmt.Errorf("more than %d records match the conditions", searchResultLimit)
+		}
+	}
+	return ids, nil
+}
+
+// sendBatches sends inputs in batch API sized chunks and counts the successful ones
+func (d *DataOps) sendBatches(ctx context.Context, path, action string, inputs []batchInput) (int64, error) {
+	var saved int64
+	var firstErr error
+	for start := 0; start < len(inputs); start += batchSize {
+		end := start + batchSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		resp, err := d.conn.client.batch(ctx, path, action, inputs[start:end])
+		if err != nil {
+			return saved, err
+		}
+		if action == "archive" {
+			// Archive answers 204 without results
+			saved += int64(end - start)
+			continue
+		}
+		saved += int64(len(resp.Results))
+		if firstErr == nil {
+			firstErr = resp.err()
+		}
+	}
+	return saved, firstErr
+}
+
+// writableProperties copies a row without the excluded fields and formats values for the API
+func writableProperties(row map[string]interface{}, exclude ...string) map[string]interface{} {
+	skip := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		skip[e] = true
+	}
+	properties := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		if skip[k] {
+			continue
+		}
+		if v == nil {
+			properties[k] = ""
+			continue
+		}
+		properties[k] = propertyValue(v)
+	}
+	return properties
+}
+
+// propertyValue formats a value the way HubSpot stores property values
+func propertyValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		// Date and datetime properties accept millisecond timestamps
+		return strconv.FormatInt(val.UnixMilli(), 10)
+	case []string:
+		// Multiple checkbox properties store the selected values separated by semicolons
+		return strings.Join(val, ";")
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// convertObject converts a record to a row. HubSpot returns every property value as a
+// string; numbers and booleans are converted using the property types.
+func convertObject(obj crmObject, types map[string]string) map[string]interface{} {
+	row := make(map[string]interface{}, len(obj.Properties)+1)
+	row[idField] = obj.ID
+	for name, value := range obj.Properties {
+		s, ok := value.(string)
+		if !ok {
+			row[name] = value
+			continue
+		}
+		if s == "" {
+			row[name] = nil
+			continue
+		}
+		switch types[name] {
+		case "number":
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				row[name] = f
+				continue
+			}
+		case "bool":
+			if b, err := strconv.ParseBool(s); err == nil {
+				row[name] = b
+				continue
+			}
+		}
+		row[name] = s
+	}
+	return row
+}
@@ -0,0 +1,8 @@
+package hubspot
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register HubSpot adapter with the global registry
+	adapter.Register(NewAdapter())
+}
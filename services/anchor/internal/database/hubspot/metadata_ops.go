@@ -0,0 +1,82 @@
+package hubspot
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations for HubSpot.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the portal.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	info, err := m.conn.client.accountInfo(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "collect_database_metadata", err)
+	}
+	names, err := m.conn.objectNames(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "collect_database_metadata", err)
+	}
+
+	return map[string]interface{}{
+		"database_type":     string(dbcapabilities.HubSpot),
+		"portal_id":         m.conn.portalID,
+		"account_type":      info["accountType"],
+		"time_zone":         info["timeZone"],
+		"company_currency":  info["companyCurrency"],
+		"data_hosting":      info["dataHostingLocation"],
+		"object_count":      len(names),
+		"objects":           names,
+		"unique_identifier": m.conn.portalID,
+	}, nil
+}
+
+// CollectInstanceMetadata returns the account details of the portal.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	info, err := m.conn.client.accountInfo(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "collect_instance_metadata", err)
+	}
+	metadata := map[string]interface{}{
+		"database_type": string(dbcapabilities.HubSpot),
+		"version":       "v3",
+	}
+	for k, v := range info {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
+
+// GetVersion returns the CRM API version in use.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return "v3", nil
+}
+
+// GetUniqueIdentifier returns the portal (hub) ID.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return m.conn.portalID, nil
+}
+
+// GetDatabaseSize is not supported; HubSpot does not report storage usage.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "get database size", "hubspot does not report storage usage")
+}
+
+// GetTableCount returns the number of exposed object types.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	names, err := m.conn.objectNames(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.HubSpot, "get_table_count", err)
+	}
+	return len(names), nil
+}
+
+// ExecuteCommand is not supported for HubSpot.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "execute command", "hubspot does not accept commands")
+}
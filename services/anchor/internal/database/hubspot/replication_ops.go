@@ -0,0 +1,453 @@
+package hubspot
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// defaultPollInterval is the time between two polls of the modified records
+const defaultPollInterval = 30 * time.Second
+
+// ReplicationOps implements adapter.ReplicationOperator for HubSpot by polling the search API
+// for records modified since the last poll. HubSpot does not expose a change log to API
+// clients, so deleted (archived) records are not captured.
+type ReplicationOps struct {
+	conn *Connection
+}
+
+// IsSupported returns whether replication is supported.
+func (r *ReplicationOps) IsSupported() bool {
+	return true
+}
+
+// GetSupportedMechanisms returns the supported replication mechanisms.
+func (r *ReplicationOps) GetSupportedMechanisms() []string {
+	return []string{"incremental_polling"}
+}
+
+// CheckPrerequisites verifies that the token may search the exposed object types.
+func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
+	names, err := r.conn.objectNames(ctx)
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.HubSpot, "check_replication_prerequisites", err)
+	}
+	for _, name := range names {
+		path, err := r.conn.objectPath(ctx, name)
+		if err != nil {
+			return err
+		}
+		if _, err := r.conn.client.search(ctx, path, searchRequest{Properties: []string{"hs_object_id"}, Limit: 1}); err != nil {
+			return adapter.WrapError(dbcapabilities.HubSpot, "check_replication_prerequisites", err)
+		}
+	}
+	return nil
+}
+
+// Connect creates a replication source polling the configured object types, or all exposed
+// object types when no tables are configured.
+func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
+	names := config.TableNames
+	if len(names) == 0 {
+		var err error
+		if names, err = r.conn.objectNames(ctx); err != nil {
+			return nil, adapter.WrapError(dbcapabilities.HubSpot, "connect_replication", err)
+		}
+	}
+	objects := make(map[string]string, len(names))
+	for _, name := range names {
+		path, err := r.conn.objectPath(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		objects[name] = path
+	}
+
+	source := &HubSpotReplicationSource{
+		id:         config.ReplicationID,
+		databaseID: config.DatabaseID,
+		conn:       r.conn,
+		objects:    objects,
+		config:     config,
+		interval:   defaultPollInterval,
+		positions:  make(map[string]*objectPosition),
+		stopChan:   make(chan struct{}),
+	}
+	if config.EventHandler != nil {
+		source.eventHandler = config.EventHandler
+	}
+	switch v := config.Options["poll_interval_seconds"].(type) {
+	case int:
+		if v > 0 {
+			source.interval = time.Duration(v) * time.Second
+		}
+	case float64:
+		if v > 0 {
+			source.interval = time.Duration(v * float64(time.Second))
+		}
+	}
+	if from, ok := config.Options["start_from"].(string); ok && from == "earliest" {
+		source.startFrom = 0
+	} else {
+		source.startFrom = time.Now().UnixMilli()
+	}
+
+	if config.StartPosition != "" {
+		if err := source.SetPosition(config.StartPosition); err != nil {
+			return nil, adapter.WrapError(dbcapabilities.HubSpot, "set_start_position", err)
+		}
+	}
+	return source, nil
+}
+
+// GetStatus returns the replication status.
+func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"database_id": r.conn.id,
+		"portal_id":   r.conn.portalID,
+		"mechanism":   "incremental_polling",
+	}, nil
+}
+
+// GetLag returns the replication lag.
+func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"database_id": r.conn.id,
+		"mechanism":   "incremental_polling",
+		"note":        "changes are picked up within one poll interval",
+	}, nil
+}
+
+// ListSlots lists replication slots (not applicable for HubSpot).
+func (r *ReplicationOps) ListSlots(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "list replication slots", "HubSpot uses incremental polling, not replication slots")
+}
+
+// DropSlot drops a replication slot (not applicable for HubSpot).
+func (r *ReplicationOps) DropSlot(ctx context.Context, slotName string) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "drop replication slot", "HubSpot uses incremental polling, not replication slots")
+}
+
+// ListPublications lists publications (not applicable for HubSpot).
+func (r *ReplicationOps) ListPublications(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "list publications", "HubSpot uses incremental polling, not publications")
+}
+
+// DropPublication drops a publication (not applicable for HubSpot).
+func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName string) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "drop publication", "HubSpot uses incremental polling, not publications")
+}
+
+// objectPosition is the polling position of one object type: the last seen modification
+// timestamp (in milliseconds) and the IDs of the records already emitted at that timestamp.
+type objectPosition struct {
+	Timestamp int64    `json:"timestamp"`
+	IDs       []string `json:"ids,omitempty"`
+}
+
+// HubSpotReplicationSource implements adapter.ReplicationSource by polling for modified records.
+// The position is the objectPosition of every object type, encoded as JSON.
+type HubSpotReplicationSource struct {
+	id           string
+	databaseID   string
+	conn         *Connection
+	objects      map[string]string
+	config       adapter.ReplicationConfig
+	interval     time.Duration
+	startFrom    int64
+	active       int32
+	stopChan     chan struct{}
+	mu           sync.RWMutex
+	positions    map[string]*objectPosition
+	eventCount   int64
+	lastError    string
+	eventHandler func(map[string]interface{})
+	checkpointFn func(context.Context, string) error
+}
+
+// GetSourceID returns the replication source ID.
+func (s *HubSpotReplicationSource) GetSourceID() string {
+	return s.id
+}
+
+// GetDatabaseID returns the database ID.
+func (s *HubSpotReplicationSource) GetDatabaseID() string {
+	return s.databaseID
+}
+
+// GetStatus returns the replication source status.
+func (s *HubSpotReplicationSource) GetStatus() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects := make([]string, 0, len(s.objects))
+	for name := range s.objects {
+		objects = append(objects, name)
+	}
+	sort.Strings(objects)
+
+	return map[string]interface{}{
+		"source_id":     s.id,
+		"database_id":   s.databaseID,
+		"active":        s.IsActive(),
+		"mechanism":     "incremental_polling",
+		"objects":       objects,
+		"poll_interval": s.interval.String(),
+		"event_count":   atomic.LoadInt64(&s.eventCount),
+		"last_error":    s.lastError,
+	}
+}
+
+// GetMetadata returns the replication source metadata.
+func (s *HubSpotReplicationSource) GetMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"source_type":     "incremental_polling",
+		"database_type":   string(dbcapabilities.HubSpot),
+		"replication_id":  s.id,
+		"database_id":     s.databaseID,
+		"supported_ops":   []string{"INSERT", "UPDATE"},
+		"resume_capable":  true,
+		"transaction_log": false,
+	}
+}
+
+// IsActive returns whether the replication source is active.
+func (s *HubSpotReplicationSource) IsActive() bool {
+	return atomic.LoadInt32(&s.active) == 1
+}
+
+// Start starts polling for modified records.
+func (s *HubSpotReplicationSource) Start() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 0, 1) {
+		return adapter.NewDatabaseError(
+			dbcapabilities.HubSpot,
+			"start_replication",
+			adapter.ErrInvalidConfiguration,
+		).WithContext("error", "replication source is already active")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-s.stopChan
+		cancel()
+	}()
+	go s.run(ctx)
+	return nil
+}
+
+// run polls every object type once per interval until the source is stopped
+func (s *HubSpotReplicationSource) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		for name, path := range s.objects {
+			if err := s.poll(ctx, name, path); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.setError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll emits the records of one object type modified since its position. Searches can
+// page through at most searchResultLimit records, so the search is repeated from the
+// advanced position until all modified records are seen.
+func (s *HubSpotReplicationSource) poll(ctx context.Context, name, path string) error {
+	types, err := s.conn.propertyTypes(ctx, path)
+	if err != nil {
+		return err
+	}
+	properties := make([]string, 0, len(types))
+	for p := range types {
+		properties = append(properties, p)
+	}
+	sort.Strings(properties)
+
+	modified := "hs_lastmodifieddate"
+	if path == "contacts" {
+		modified = "lastmodifieddate"
+	}
+
+	pos := s.position(name)
+	// Records created after the position of the previous poll are reported as inserts
+	createdAfter := pos.Timestamp
+
+	for {
+		req := searchRequest{
+			FilterGroups: []filterGroup{{Filters: []filter{
+				{PropertyName: modified, Operator: "GTE", Value: strconv.FormatInt(pos.Timestamp, 10)},
+			}}},
+			Sorts:      []sortOrder{{PropertyName: modified, Direction: "ASCENDING"}},
+			Properties: properties,
+			Limit:      pageSize,
+		}
+		startTimestamp := pos.Timestamp
+		seen := 0
+		for {
+			page, err := s.conn.client.search(ctx, path, req)
+			if err != nil {
+				return err
+			}
+			for _, obj := range page.Results {
+				s.emit(name, obj, types, &pos, createdAfter)
+			}
+			seen += len(page.Results)
+			req.After = page.Paging.Next.After
+			if req.After == "" {
+				return nil
+			}
+			if seen+pageSize > searchResultLimit {
+				break
+			}
+		}
+		if pos.Timestamp == startTimestamp {
+			// More than searchResultLimit records share one timestamp; the rest is picked
+			// up once newer modifications move the position forward
+			return nil
+		}
+	}
+}
+
+// emit forwards one record to the event handler unless it was already emitted, and
+// advances the position
+func (s *HubSpotReplicationSource) emit(name string, obj crmObject, types map[string]string, pos *objectPosition, createdAfter int64) {
+	modifiedAt := parseTimestamp(obj.UpdatedAt)
+	if modifiedAt == pos.Timestamp {
+		for _, id := range pos.IDs {
+			if id == obj.ID {
+				return
+			}
+		}
+	}
+
+	createdAt := parseTimestamp(obj.CreatedAt)
+	operation := "UPDATE"
+	if createdAt >= createdAfter {
+		operation = "INSERT"
+	}
+
+	raw := map[string]interface{}{
+		"table_name":  name,
+		"operation":   operation,
+		"record_id":   obj.ID,
+		"created_at":  createdAt,
+		"modified_at": modifiedAt,
+		"data":        convertObject(obj, types),
+	}
+	if s.eventHandler != nil {
+		s.eventHandler(raw)
+	}
+	atomic.AddInt64(&s.eventCount, 1)
+
+	if modifiedAt > pos.Timestamp {
+		pos.Timestamp = modifiedAt
+		pos.IDs = []string{obj.ID}
+	} else {
+		pos.IDs = append(pos.IDs, obj.ID)
+	}
+
+	s.mu.Lock()
+	s.positions[name] = &objectPosition{Timestamp: pos.Timestamp, IDs: append([]string(nil), pos.IDs...)}
+	s.mu.Unlock()
+}
+
+// position returns a copy of the position of an object type
+func (s *HubSpotReplicationSource) position(name string) objectPosition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if pos, ok := s.positions[name]; ok {
+		return objectPosition{Timestamp: pos.Timestamp, IDs: append([]string(nil), pos.IDs...)}
+	}
+	return objectPosition{Timestamp: s.startFrom}
+}
+
+func (s *HubSpotReplicationSource) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err.Error()
+}
+
+// Stop stops the replication source.
+func (s *HubSpotReplicationSource) Stop() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 1, 0) {
+		return nil
+	}
+	close(s.stopChan)
+	return nil
+}
+
+// Close closes the replication source.
+func (s *HubSpotReplicationSource) Close() error {
+	return s.Stop()
+}
+
+// GetPosition returns the polling position of every object type.
+func (s *HubSpotReplicationSource) GetPosition() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.positions) == 0 {
+		return "", nil
+	}
+	position, err := json.Marshal(s.positions)
+	if err != nil {
+		return "", err
+	}
+	return string(position), nil
+}
+
+// SetPosition sets the polling positions to resume from.
+func (s *HubSpotReplicationSource) SetPosition(position string) error {
+	if position == "" {
+		return nil
+	}
+	positions := make(map[string]*objectPosition)
+	if err := json.Unmarshal([]byte(position), &positions); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positions = positions
+	return nil
+}
+
+// SaveCheckpoint persists the current replication position.
+func (s *HubSpotReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
+	if s.checkpointFn != nil {
+		return s.checkpointFn(ctx, position)
+	}
+	return nil
+}
+
+// SetCheckpointFunc sets the callback function for persisting checkpoints.
+func (s *HubSpotReplicationSource) SetCheckpointFunc(fn func(context.Context, string) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpointFn = fn
+}
+
+// parseTimestamp parses an API timestamp to milliseconds since the epoch
+func parseTimestamp(value string) int64 {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,207 @@
+package hubspot
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// idField is the pseudo-column carrying the HubSpot record ID in fetched rows
+const idField = "id"
+
+// SchemaOps implements schema operations for HubSpot.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema maps every exposed object type to a collection with its properties as fields.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	types, err := s.conn.objectTypes(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "discover_schema", err)
+	}
+
+	model := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.HubSpot,
+		Collections:  make(map[string]unifiedmodel.Collection, len(types)),
+	}
+	for name, path := range types {
+		props, err := s.conn.client.objectProperties(ctx, path)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.HubSpot, "discover_schema", err)
+		}
+		model.Collections[name] = convertCollection(name, path, props)
+	}
+	return model, nil
+}
+
+// CreateStructure checks that the model's collections exist as object types. Custom objects
+// require an Enterprise subscription and are defined in HubSpot, so missing ones are reported.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if model == nil {
+		return adapter.NewConfigurationError(dbcapabilities.HubSpot, "model", "unified model cannot be nil")
+	}
+
+	var names []string
+	for name := range model.Collections {
+		names = append(names, name)
+	}
+	for name := range model.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := s.conn.objectPath(ctx, name); err != nil {
+			var notFound *adapter.NotFoundError
+			if errors.As(err, &notFound) {
+				return adapter.NewUnsupportedOperationError(dbcapabilities.HubSpot, "create object "+name,
+					"object types must be defined in hubspot")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTables lists the exposed object types.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	names, err := s.conn.objectNames(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "list_tables", err)
+	}
+	return names, nil
+}
+
+// GetTableSchema returns an object type as a table with its properties as columns.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	path, err := s.conn.objectPath(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	props, err := s.conn.client.objectProperties(ctx, path)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.HubSpot, "get_table_schema", err)
+	}
+
+	position := 1
+	table := &unifiedmodel.Table{
+		Name:    tableName,
+		Columns: make(map[string]unifiedmodel.Column, len(props)+1),
+		Constraints: map[string]unifiedmodel.Constraint{
+			"pk_" + tableName: {Name: "pk_" + tableName, Type: unifiedmodel.ConstraintTypePrimaryKey, Columns: []string{idField}},
+		},
+		Options: map[string]any{"object_type_id": path},
+	}
+	table.Columns[idField] = unifiedmodel.Column{Name: idField, DataType: "string", IsPrimaryKey: true, OrdinalPosition: &position}
+
+	for i, p := range sortedProperties(props) {
+		pos := i + 2
+		table.Columns[p.Name] = unifiedmodel.Column{
+			Name:            p.Name,
+			DataType:        unifiedType(p),
+			Nullable:        true,
+			OrdinalPosition: &pos,
+			Options:         propertyOptions(p),
+		}
+	}
+	return table, nil
+}
+
+// convertCollection converts an object type and its properties to a UnifiedModel collection
+func convertCollection(name, path string, props []property) unifiedmodel.Collection {
+	collection := unifiedmodel.Collection{
+		Name:   name,
+		Fields: make(map[string]unifiedmodel.Field, len(props)+1),
+		Indexes: map[string]unifiedmodel.Index{
+			"pk_" + name: {Name: "pk_" + name, Columns: []string{idField}, Unique: true},
+		},
+		Options: map[string]any{"object_type_id": path},
+	}
+	collection.Fields[idField] = unifiedmodel.Field{Name: idField, Type: "string", Required: true}
+
+	for _, p := range props {
+		collection.Fields[p.Name] = unifiedmodel.Field{
+			Name:    p.Name,
+			Type:    unifiedType(p),
+			Options: propertyOptions(p),
+		}
+		if p.HasUniqueValue {
+			index := "idx_" + name + "_" + p.Name
+			collection.Indexes[index] = unifiedmodel.Index{Name: index, Columns: []string{p.Name}, Unique: true}
+		}
+	}
+	return collection
+}
+
+// propertyOptions returns the HubSpot specific property metadata kept in options
+func propertyOptions(p property) map[string]any {
+	options := map[string]any{
+		"label":        p.Label,
+		"hubspot_type": p.Type,
+		"field_type":   p.FieldType,
+		"group":        p.GroupName,
+		"read_only":    p.ModificationMetadata.ReadOnlyValue,
+	}
+	if p.Description != "" {
+		options["description"] = p.Description
+	}
+	if p.Calculated {
+		options["calculated"] = true
+	}
+	if p.HasUniqueValue {
+		options["unique"] = true
+	}
+	if p.Hidden {
+		options["hidden"] = true
+	}
+	if len(p.Options) > 0 {
+		values := make([]string, 0, len(p.Options))
+		for _, o := range p.Options {
+			if !o.Hidden {
+				values = append(values, o.Value)
+			}
+		}
+		options["enum_values"] = values
+	}
+	return options
+}
+
+// sortedProperties orders properties by display order, then name
+func sortedProperties(props []property) []property {
+	sorted := append([]property(nil), props...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].DisplayOrder != sorted[j].DisplayOrder {
+			return sorted[i].DisplayOrder < sorted[j].DisplayOrder
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// unifiedType maps a HubSpot property type to a unified data type
+func unifiedType(p property) string {
+	switch p.Type {
+	case "number":
+		return "decimal"
+	case "bool":
+		return "boolean"
+	case "date":
+		return "date"
+	case "datetime":
+		return "timestamp"
+	case "enumeration":
+		if p.FieldType == "checkbox" {
+			// Multiple checkboxes store the selected values separated by semicolons
+			return "text"
+		}
+		return "string"
+	default:
+		// string, phone_number, object_coordinates, json
+		return "string"
+	}
+}
@@ -0,0 +1,106 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// rdsTokenLifetime is how long RDS IAM authentication tokens are valid
+const rdsTokenLifetime = 15 * time.Minute
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// rdsTokenSource signs RDS IAM authentication tokens for a database user
+type rdsTokenSource struct {
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	region      string
+	endpoint    string
+	username    string
+}
+
+func newRDSTokenSource(ctx context.Context, auth adapter.IAMAuth, request adapter.TokenRequest) (adapter.TokenSource, error) {
+	if request.Host == "" || request.Port == 0 || request.Username == "" {
+		return nil, fmt.Errorf("RDS IAM authentication requires the host, port and username of the database")
+	}
+
+	region := auth.Region
+	if region == "" {
+		region = rdsRegion(request.Host)
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+	if awsConfig.Region == "" {
+		return nil, fmt.Errorf("the AWS region of %s is unknown, set the region of the IAM authentication", request.Host)
+	}
+	if awsConfig.Credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials found")
+	}
+
+	return &rdsTokenSource{
+		credentials: awsConfig.Credentials,
+		signer:      v4.NewSigner(),
+		region:      awsConfig.Region,
+		endpoint:    net.JoinHostPort(request.Host, strconv.Itoa(request.Port)),
+		username:    request.Username,
+	}, nil
+}
+
+// Token presigns a connect request of the database user, which RDS accepts as its password
+func (s *rdsTokenSource) Token(ctx context.Context) (adapter.AuthToken, error) {
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return adapter.AuthToken{}, fmt.Errorf("error retrieving AWS credentials: %w", err)
+	}
+
+	query := url.Values{
+		"Action":        {"connect"},
+		"DBUser":        {s.username},
+		"X-Amz-Expires": {strconv.Itoa(int(rdsTokenLifetime.Seconds()))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+s.endpoint+"/?"+query.Encode(), nil)
+	if err != nil {
+		return adapter.AuthToken{}, err
+	}
+
+	now := time.Now()
+	signed, _, err := s.signer.PresignHTTP(ctx, credentials, req, emptyPayloadHash, "rds-db", s.region, now)
+	if err != nil {
+		return adapter.AuthToken{}, fmt.Errorf("error signing RDS authentication token: %w", err)
+	}
+
+	expiry := now.Add(rdsTokenLifetime)
+	// Temporary credentials, such as those of an assumed role, invalidate the token when
+	// they expire
+	if credentials.CanExpire && credentials.Expires.Before(expiry) {
+		expiry = credentials.Expires
+	}
+	return adapter.AuthToken{Value: strings.TrimPrefix(signed, "https://"), Expiry: expiry}, nil
+}
+
+// rdsRegion returns the region of an RDS endpoint such as
+// mydb.c9akciq32.eu-west-1.rds.amazonaws.com, or "" for other hosts
+func rdsRegion(host string) string {
+	labels := strings.Split(strings.ToLower(host), ".")
+	for i := 1; i < len(labels)-2; i++ {
+		if labels[i] == "rds" && labels[i+1] == "amazonaws" {
+			return labels[i-1]
+		}
+	}
+	return ""
+}
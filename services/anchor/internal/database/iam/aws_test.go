@@ -0,0 +1,54 @@
+package iam
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestRDSRegion(t *testing.T) {
+	tests := map[string]string{
+		"mydb.c9akciq32.eu-west-1.rds.amazonaws.com":               "eu-west-1",
+		"cluster.cluster-ro-c9akciq32.us-east-2.rds.amazonaws.com": "us-east-2",
+		"mydb.c9akciq32.cn-north-1.rds.amazonaws.com.cn":           "cn-north-1",
+		"db.internal":       "",
+		"rds.amazonaws.com": "",
+	}
+	for host, want := range tests {
+		if got := rdsRegion(host); got != want {
+			t.Errorf("rdsRegion(%s) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestRDSTokenIsPresignedConnectRequest(t *testing.T) {
+	source := &rdsTokenSource{
+		credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		signer:      v4.NewSigner(),
+		region:      "eu-west-1",
+		endpoint:    "mydb.c9akciq32.eu-west-1.rds.amazonaws.com:5432",
+		username:    "redb",
+	}
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token.Value, "mydb.c9akciq32.eu-west-1.rds.amazonaws.com:5432/?") {
+		t.Fatalf("token %s is not a request to the database endpoint without scheme", token.Value)
+	}
+
+	query, err := url.ParseQuery(token.Value[strings.Index(token.Value, "?")+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("Action") != "connect" || query.Get("DBUser") != "redb" || query.Get("X-Amz-Expires") != "900" {
+		t.Fatalf("token query %v", query)
+	}
+	if !strings.Contains(query.Get("X-Amz-Credential"), "/eu-west-1/rds-db/aws4_request") || query.Get("X-Amz-Signature") == "" {
+		t.Fatalf("token not signed for rds-db in eu-west-1: %v", query)
+	}
+}
@@ -0,0 +1,64 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// Scopes of Microsoft Entra ID tokens for Azure SQL and Synapse, and for the Azure Database
+// for PostgreSQL and MySQL flexible servers
+const (
+	azureSQLScope      = "https://database.windows.net/.default"
+	azureOSSRDBMSScope = "https://ossrdbms-aad.database.windows.net/.default"
+)
+
+// azureADTokenSource returns the Microsoft Entra ID tokens of the default Azure credential
+// or of a user-assigned managed identity
+type azureADTokenSource struct {
+	credential azcore.TokenCredential
+	scope      string
+}
+
+func newAzureADTokenSource(ctx context.Context, auth adapter.IAMAuth, request adapter.TokenRequest) (adapter.TokenSource, error) {
+	scope := auth.Scope
+	if scope == "" {
+		switch dbcapabilities.DatabaseType(request.ConnectionType) {
+		case dbcapabilities.SQLServer, dbcapabilities.Synapse:
+			scope = azureSQLScope
+		case dbcapabilities.PostgreSQL, dbcapabilities.MySQL, dbcapabilities.MariaDB:
+			scope = azureOSSRDBMSScope
+		default:
+			return nil, fmt.Errorf("no Microsoft Entra ID scope known for %s databases, set the scope of the IAM authentication", request.ConnectionType)
+		}
+	}
+
+	var credential azcore.TokenCredential
+	var err error
+	if auth.ClientID != "" {
+		credential, err = azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+			ID: azidentity.ClientID(auth.ClientID),
+		})
+	} else {
+		credential, err = azidentity.NewDefaultAzureCredential(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating Azure credential: %w", err)
+	}
+	return &azureADTokenSource{credential: credential, scope: scope}, nil
+}
+
+// Token returns a Microsoft Entra ID access token for the database scope
+func (s *azureADTokenSource) Token(ctx context.Context) (adapter.AuthToken, error) {
+	token, err := s.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{s.scope}})
+	if err != nil {
+		return adapter.AuthToken{}, fmt.Errorf("error getting Microsoft Entra ID token: %w", err)
+	}
+	return adapter.AuthToken{Value: token.Token, Expiry: token.ExpiresOn}, nil
+}
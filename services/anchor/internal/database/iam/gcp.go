@@ -0,0 +1,42 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// cloudSQLLoginScope is the OAuth2 scope of Cloud SQL IAM database authentication
+const cloudSQLLoginScope = "https://www.googleapis.com/auth/sqlservice.login"
+
+// cloudSQLTokenSource returns the access tokens of the application default credentials,
+// which Cloud SQL accepts as the password of IAM database users
+type cloudSQLTokenSource struct {
+	tokens oauth2.TokenSource
+}
+
+func newCloudSQLTokenSource(ctx context.Context, auth adapter.IAMAuth, request adapter.TokenRequest) (adapter.TokenSource, error) {
+	scope := auth.Scope
+	if scope == "" {
+		scope = cloudSQLLoginScope
+	}
+	// The token source outlives the connection attempt, so it does not get its context
+	tokens, err := google.DefaultTokenSource(context.Background(), scope)
+	if err != nil {
+		return nil, fmt.Errorf("error finding Google application default credentials: %w", err)
+	}
+	return &cloudSQLTokenSource{tokens: tokens}, nil
+}
+
+// Token returns an access token of the application default credentials
+func (s *cloudSQLTokenSource) Token(ctx context.Context) (adapter.AuthToken, error) {
+	token, err := s.tokens.Token()
+	if err != nil {
+		return adapter.AuthToken{}, fmt.Errorf("error getting Google access token: %w", err)
+	}
+	return adapter.AuthToken{Value: token.AccessToken, Expiry: token.Expiry}, nil
+}
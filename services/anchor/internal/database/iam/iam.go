@@ -0,0 +1,15 @@
+// Package iam provides the cloud IAM token providers of the database adapters: RDS IAM
+// authentication tokens, Cloud SQL IAM tokens and Microsoft Entra ID tokens, obtained with the
+// credentials of the environment of the anchor. They are registered with the adapter package
+// on import, see adapter.IAMAuth.
+package iam
+
+import (
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+func init() {
+	adapter.RegisterTokenProvider(adapter.IAMProviderAWSRDS, newRDSTokenSource)
+	adapter.RegisterTokenProvider(adapter.IAMProviderGCPCloudSQL, newCloudSQLTokenSource)
+	adapter.RegisterTokenProvider(adapter.IAMProviderAzureAD, newAzureADTokenSource)
+}
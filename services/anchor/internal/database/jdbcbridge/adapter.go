@@ -0,0 +1,343 @@
+package jdbcbridge
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	jdbcbridgev1 "github.com/redbco/redb-open/api/proto/jdbcbridge/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/encryption"
+)
+
+// Adapter implements adapter.DatabaseAdapter for databases attached through the JDBC bridge
+// sidecar, for databases without a native Go driver such as Teradata, Informix or Sybase.
+//
+// Host and Port address the sidecar. The JDBC URL is read from options.jdbc_url, ConnectionString
+// or, when it starts with "jdbc:", DatabaseName. The driver class is read from
+// options.driver_class and may be left empty for JDBC 4 drivers. Other string options prefixed
+// with "jdbc." are passed to the driver as connection properties, options.schema sets the
+// schema used for unqualified table names and options.idle_timeout_seconds the time after which
+// the bridge closes an unused session.
+//
+// The bridge reports which operation categories it supports for a database. Only those are
+// exposed by the connection; the others return unsupported operators.
+type Adapter struct{}
+
+// NewAdapter creates a new JDBC bridge adapter.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.JDBCBridge
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.JDBCBridge)
+}
+
+// Connect opens a bridge session on the database.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	cfg, err := newSessionConfig(config.TenantID, config.Host, config.Port, config.Username, config.Password,
+		config.ConnectionString, config.DatabaseName, config.Options)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ssl = config.SSL
+	cfg.sslRejectUnauthorized = config.SSLRejectUnauthorized
+	cfg.sslCert = adapter.GetString(config.SSLCert)
+	cfg.sslKey = adapter.GetString(config.SSLKey)
+	cfg.sslRootCert = adapter.GetString(config.SSLRootCert)
+
+	client, err := openSession(ctx, cfg)
+	if err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.JDBCBridge, config.Host, config.Port, err)
+	}
+
+	return &Connection{
+		id:        config.DatabaseID,
+		client:    client,
+		schema:    optionString(config.Options, "schema"),
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance opens a bridge session for instance-level metadata. Databases can be listed
+// when the bridge supports listing catalogs, but not created or dropped.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	cfg, err := newSessionConfig(config.TenantID, config.Host, config.Port, config.Username, config.Password,
+		config.ConnectionString, config.DatabaseName, config.Options)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ssl = config.SSL
+	cfg.sslRejectUnauthorized = config.SSLRejectUnauthorized
+	cfg.sslCert = adapter.GetString(config.SSLCert)
+	cfg.sslKey = adapter.GetString(config.SSLKey)
+	cfg.sslRootCert = adapter.GetString(config.SSLRootCert)
+
+	client, err := openSession(ctx, cfg)
+	if err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.JDBCBridge, config.Host, config.Port, err)
+	}
+
+	return &InstanceConnection{
+		id:        config.InstanceID,
+		client:    client,
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// newSessionConfig builds the session configuration shared by database and instance connections
+func newSessionConfig(tenantID, host string, port int, username, password, connectionString, databaseName string, options map[string]interface{}) (sessionConfig, error) {
+	jdbcURL := optionString(options, "jdbc_url")
+	if jdbcURL == "" {
+		jdbcURL = connectionString
+	}
+	if jdbcURL == "" && strings.HasPrefix(databaseName, "jdbc:") {
+		jdbcURL = databaseName
+	}
+	if jdbcURL == "" {
+		return sessionConfig{}, adapter.NewConfigurationError(dbcapabilities.JDBCBridge, "jdbc_url", "a JDBC URL is required")
+	}
+	if host == "" {
+		return sessionConfig{}, adapter.NewConfigurationError(dbcapabilities.JDBCBridge, "host", "the address of the bridge sidecar is required")
+	}
+	if port == 0 {
+		port = dbcapabilities.MustGet(dbcapabilities.JDBCBridge).DefaultPort
+	}
+
+	if password != "" {
+		decrypted, err := encryption.DecryptPassword(tenantID, password)
+		if err != nil {
+			return sessionConfig{}, adapter.NewConnectionError(dbcapabilities.JDBCBridge, host, port,
+				fmt.Errorf("error decrypting password: %w", err))
+		}
+		password = decrypted
+	}
+
+	properties := make(map[string]string)
+	for key, value := range options {
+		if name, ok := strings.CutPrefix(key, "jdbc."); ok {
+			if s, ok := value.(string); ok {
+				properties[name] = s
+			}
+		}
+	}
+
+	var idleTimeout int32
+	if v, ok := options["idle_timeout_seconds"].(float64); ok && v > 0 {
+		idleTimeout = int32(v)
+	}
+
+	return sessionConfig{
+		endpoint:    net.JoinHostPort(host, strconv.Itoa(port)),
+		jdbcURL:     jdbcURL,
+		driverClass: optionString(options, "driver_class"),
+		username:    username,
+		password:    password,
+		properties:  properties,
+		idleTimeout: idleTimeout,
+	}, nil
+}
+
+func optionString(options map[string]interface{}, key string) string {
+	if v, ok := options[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Connection implements adapter.Connection for a database attached through the bridge.
+type Connection struct {
+	id        string
+	client    *Client
+	schema    string
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+}
+
+// dialect returns the SQL dialect of the connection
+func (c *Connection) dialect() sqlDialect {
+	return sqlDialect{quote: c.client.info.IdentifierQuote, schema: c.schema}
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.JDBCBridge
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the bridge session is still alive.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if err := c.client.ping(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.JDBCBridge, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the bridge session.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return c.client.close()
+}
+
+// SchemaOperations returns the schema operator if the bridge supports schema discovery.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	if !c.client.supports(jdbcbridgev1.Operation_OPERATION_SCHEMA_DISCOVERY) {
+		return adapter.NewUnsupportedSchemaOperator(dbcapabilities.JDBCBridge)
+	}
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator if the bridge supports reading data.
+// Writes additionally require the bridge to support writing data.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	if !c.client.supports(jdbcbridgev1.Operation_OPERATION_DATA_READ) {
+		return adapter.NewUnsupportedDataOperator(dbcapabilities.JDBCBridge)
+	}
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator. JDBC offers no change data capture,
+// so replication is not supported.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.JDBCBridge)
+}
+
+// MetadataOperations returns the metadata operator if the bridge supports metadata.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	if !c.client.supports(jdbcbridgev1.Operation_OPERATION_METADATA) {
+		return adapter.NewUnsupportedMetadataOperator(dbcapabilities.JDBCBridge)
+	}
+	return &MetadataOps{client: c.client}
+}
+
+// Raw returns the bridge client.
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
+
+// InstanceConnection implements adapter.InstanceConnection for the bridge.
+type InstanceConnection struct {
+	id        string
+	client    *Client
+	config    adapter.InstanceConfig
+	adapter   *Adapter
+	connected int32
+}
+
+// ID returns the instance connection identifier.
+func (i *InstanceConnection) ID() string {
+	return i.id
+}
+
+// Type returns the database type.
+func (i *InstanceConnection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.JDBCBridge
+}
+
+// IsConnected returns whether the connection is active.
+func (i *InstanceConnection) IsConnected() bool {
+	return atomic.LoadInt32(&i.connected) == 1
+}
+
+// Ping checks that the bridge session is still alive.
+func (i *InstanceConnection) Ping(ctx context.Context) error {
+	if !i.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if err := i.client.ping(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.JDBCBridge, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the bridge session.
+func (i *InstanceConnection) Close() error {
+	if !atomic.CompareAndSwapInt32(&i.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return i.client.close()
+}
+
+// ListDatabases lists the catalogs reported by the driver.
+func (i *InstanceConnection) ListDatabases(ctx context.Context) ([]string, error) {
+	if !i.client.supports(jdbcbridgev1.Operation_OPERATION_LIST_CATALOGS) {
+		return nil, adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "list databases", "the bridge does not support listing catalogs for this database")
+	}
+	catalogs, err := i.client.listCatalogs(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "list_databases", err)
+	}
+	return catalogs, nil
+}
+
+// CreateDatabase is not supported through the bridge.
+func (i *InstanceConnection) CreateDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "create database", "database creation is not portable across JDBC databases")
+}
+
+// DropDatabase is not supported through the bridge.
+func (i *InstanceConnection) DropDatabase(ctx context.Context, name string, options map[string]interface{}) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "drop database", "dropping databases is not portable across JDBC databases")
+}
+
+// MetadataOperations returns the metadata operator if the bridge supports metadata.
+func (i *InstanceConnection) MetadataOperations() adapter.MetadataOperator {
+	if !i.client.supports(jdbcbridgev1.Operation_OPERATION_METADATA) {
+		return adapter.NewUnsupportedMetadataOperator(dbcapabilities.JDBCBridge)
+	}
+	return &MetadataOps{client: i.client}
+}
+
+// Raw returns the bridge client.
+func (i *InstanceConnection) Raw() interface{} {
+	return i.client
+}
+
+// Config returns the instance configuration.
+func (i *InstanceConnection) Config() adapter.InstanceConfig {
+	return i.config
+}
+
+// Adapter returns the database adapter.
+func (i *InstanceConnection) Adapter() adapter.DatabaseAdapter {
+	return i.adapter
+}
@@ -0,0 +1,315 @@
+package jdbcbridge
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	jdbcbridgev1 "github.com/redbco/redb-open/api/proto/jdbcbridge/v1"
+	pkggrpc "github.com/redbco/redb-open/pkg/grpc"
+)
+
+// sessionConfig holds what is needed to open a session on the bridge sidecar
+type sessionConfig struct {
+	endpoint    string
+	jdbcURL     string
+	driverClass string
+	username    string
+	password    string
+	properties  map[string]string
+	idleTimeout int32
+
+	ssl                   bool
+	sslRejectUnauthorized *bool
+	sslCert               string
+	sslKey                string
+	sslRootCert           string
+}
+
+// Client is an open session on the JDBC bridge sidecar.
+type Client struct {
+	conn      *grpc.ClientConn
+	api       jdbcbridgev1.JdbcBridgeServiceClient
+	sessionID string
+	info      *jdbcbridgev1.BridgeInfo
+	supported map[jdbcbridgev1.Operation]bool
+}
+
+// queryResult is the collected result of a bridge query
+type queryResult struct {
+	columns []string
+	rows    []map[string]interface{}
+	hasMore bool
+}
+
+// openSession connects to the sidecar and opens a session on the database behind the JDBC URL
+func openSession(ctx context.Context, cfg sessionConfig) (*Client, error) {
+	opts := pkggrpc.DefaultClientOptions()
+	if cfg.ssl {
+		tlsConfig, err := createTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		// Replaces the SPIFFE credentials, the sidecar is not part of the trust domain
+		opts.DialOptions = append(opts.DialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	conn, err := pkggrpc.NewClient(ctx, cfg.endpoint, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to bridge at %s: %w", cfg.endpoint, err)
+	}
+
+	api := jdbcbridgev1.NewJdbcBridgeServiceClient(conn)
+	resp, err := api.Open(ctx, &jdbcbridgev1.OpenRequest{
+		JdbcUrl:            cfg.jdbcURL,
+		DriverClass:        cfg.driverClass,
+		Username:           cfg.username,
+		Password:           cfg.password,
+		Properties:         cfg.properties,
+		IdleTimeoutSeconds: cfg.idleTimeout,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error opening bridge session: %w", err)
+	}
+
+	client := &Client{
+		conn:      conn,
+		api:       api,
+		sessionID: resp.SessionId,
+		info:      resp.Info,
+		supported: make(map[jdbcbridgev1.Operation]bool),
+	}
+	if client.info == nil {
+		client.info = &jdbcbridgev1.BridgeInfo{}
+	}
+	for _, op := range client.info.SupportedOperations {
+		client.supported[op] = true
+	}
+	if client.info.ReadOnly {
+		delete(client.supported, jdbcbridgev1.Operation_OPERATION_DATA_WRITE)
+	}
+	return client, nil
+}
+
+// createTLSConfig builds the TLS configuration for the sidecar connection
+func createTLSConfig(cfg sessionConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.sslRejectUnauthorized != nil && !*cfg.sslRejectUnauthorized,
+	}
+
+	if cfg.sslCert != "" && cfg.sslKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.sslCert, cfg.sslKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.sslRootCert != "" {
+		caCert, err := os.ReadFile(cfg.sslRootCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return tlsConfig, nil
+}
+
+// supports reports whether the bridge supports an operation category for this session
+func (c *Client) supports(op jdbcbridgev1.Operation) bool {
+	return c.supported[op]
+}
+
+// supportedOperations returns the names of the operation categories the bridge reported
+func (c *Client) supportedOperations() []string {
+	var names []string
+	for _, op := range c.info.SupportedOperations {
+		if c.supported[op] {
+			names = append(names, op.String())
+		}
+	}
+	return names
+}
+
+// close closes the session and the connection to the sidecar
+func (c *Client) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, closeErr := c.api.Close(ctx, &jdbcbridgev1.CloseRequest{SessionId: c.sessionID})
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (c *Client) ping(ctx context.Context) error {
+	_, err := c.api.Ping(ctx, &jdbcbridgev1.PingRequest{SessionId: c.sessionID})
+	return err
+}
+
+func (c *Client) listCatalogs(ctx context.Context) ([]string, error) {
+	resp, err := c.api.ListCatalogs(ctx, &jdbcbridgev1.ListCatalogsRequest{SessionId: c.sessionID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Catalogs, nil
+}
+
+func (c *Client) listTables(ctx context.Context, schema string, types ...string) ([]*jdbcbridgev1.TableRef, error) {
+	resp, err := c.api.ListTables(ctx, &jdbcbridgev1.ListTablesRequest{
+		SessionId:     c.sessionID,
+		SchemaPattern: schema,
+		Types:         types,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tables, nil
+}
+
+func (c *Client) describeTable(ctx context.Context, schema, table string) (*jdbcbridgev1.TableDefinition, error) {
+	resp, err := c.api.DescribeTable(ctx, &jdbcbridgev1.DescribeTableRequest{
+		SessionId: c.sessionID,
+		Schema:    schema,
+		Table:     table,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Table == nil {
+		return nil, fmt.Errorf("bridge returned no definition for table %s", table)
+	}
+	return resp.Table, nil
+}
+
+func (c *Client) metadata(ctx context.Context) (*jdbcbridgev1.GetMetadataResponse, error) {
+	return c.api.GetMetadata(ctx, &jdbcbridgev1.GetMetadataRequest{SessionId: c.sessionID})
+}
+
+// query runs a statement and collects its result. skip leading rows are left out and at most
+// max rows are returned, 0 returning all rows.
+func (c *Client) query(ctx context.Context, sql string, args []interface{}, skip, max int64) (*queryResult, error) {
+	params, err := toValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.api.Query(ctx, &jdbcbridgev1.QueryRequest{
+		SessionId:  c.sessionID,
+		Sql:        sql,
+		Parameters: params,
+		MaxRows:    max,
+		SkipRows:   skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &queryResult{}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, col := range resp.Columns {
+			result.columns = append(result.columns, col.Name)
+		}
+		for _, row := range resp.Rows {
+			values := make(map[string]interface{}, len(result.columns))
+			for i, value := range row.Values {
+				if i < len(result.columns) {
+					values[result.columns[i]] = value.AsInterface()
+				}
+			}
+			result.rows = append(result.rows, values)
+		}
+		if resp.HasMore {
+			result.hasMore = true
+		}
+	}
+	return result, nil
+}
+
+// execute runs a statement once per parameter set as a batch. With transactional set, the
+// batch is rolled back on the first error.
+func (c *Client) execute(ctx context.Context, sql string, batches [][]interface{}, transactional bool) (int64, error) {
+	req := &jdbcbridgev1.ExecuteRequest{
+		SessionId:     c.sessionID,
+		Sql:           sql,
+		Transactional: transactional,
+	}
+	for _, args := range batches {
+		values, err := toValues(args)
+		if err != nil {
+			return 0, err
+		}
+		req.Batches = append(req.Batches, &jdbcbridgev1.ParameterSet{Values: values})
+	}
+
+	resp, err := c.api.Execute(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return resp.AffectedRows, nil
+}
+
+// toValues converts statement arguments to protobuf values
+func toValues(args []interface{}) ([]*structpb.Value, error) {
+	values := make([]*structpb.Value, 0, len(args))
+	for i, arg := range args {
+		value, err := toValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d: %w", i+1, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// toValue converts an argument to a protobuf value using the encoding of the bridge protocol:
+// temporal values as ISO-8601 strings and binary values as base64 strings
+func toValue(arg interface{}) (*structpb.Value, error) {
+	switch v := arg.(type) {
+	case time.Time:
+		return structpb.NewStringValue(v.Format(time.RFC3339Nano)), nil
+	case *time.Time:
+		if v == nil {
+			return structpb.NewNullValue(), nil
+		}
+		return structpb.NewStringValue(v.Format(time.RFC3339Nano)), nil
+	case []byte:
+		return structpb.NewStringValue(base64.StdEncoding.EncodeToString(v)), nil
+	case json.Number:
+		// Kept as a string so that large integers and decimals keep their precision
+		return structpb.NewStringValue(v.String()), nil
+	case fmt.Stringer:
+		return structpb.NewStringValue(v.String()), nil
+	}
+
+	value, err := structpb.NewValue(arg)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value of type %T", arg)
+	}
+	return value, nil
+}
@@ -0,0 +1,297 @@
+package jdbcbridge
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	jdbcbridgev1 "github.com/redbco/redb-open/api/proto/jdbcbridge/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DataOps implements data operations through the bridge.
+type DataOps struct {
+	conn *Connection
+}
+
+// requireWrite returns an error when the bridge does not support writing data
+func (d *DataOps) requireWrite(operation string) error {
+	if d.conn.client.supports(jdbcbridgev1.Operation_OPERATION_DATA_WRITE) {
+		return nil
+	}
+	return adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, operation, "the bridge does not support writing to this database")
+}
+
+// requireQuery returns an error when the bridge does not support arbitrary statements
+func (d *DataOps) requireQuery(operation string) error {
+	if d.conn.client.supports(jdbcbridgev1.Operation_OPERATION_QUERY) {
+		return nil
+	}
+	return adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, operation, "the bridge does not support arbitrary queries on this database")
+}
+
+// Fetch retrieves rows from a table.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves rows with specific columns from a table.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	result, err := d.conn.client.query(ctx, d.conn.dialect().selectSQL(table, columns, ""), nil, 0, int64(max(limit, 0)))
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "fetch", err)
+	}
+	return result.rows, nil
+}
+
+// Stream returns a batch of rows starting at the given offset. The bridge skips the leading
+// rows of the result, so batches are only stable across calls when OrderBy is set.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	batchSize := int64(params.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	sql := d.conn.dialect().selectSQL(params.Table, params.Columns, params.OrderBy)
+	result, err := d.conn.client.query(ctx, sql, nil, params.Offset, batchSize)
+	if err != nil {
+		return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.JDBCBridge, "stream", err)
+	}
+
+	return adapter.StreamResult{
+		Data:       result.rows,
+		HasMore:    result.hasMore,
+		NextCursor: strconv.FormatInt(params.Offset+int64(len(result.rows)), 10),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// Insert inserts rows, batching consecutive rows with the same columns into one statement.
+// Each batch is inserted in one transaction.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	if err := d.requireWrite("insert"); err != nil {
+		return 0, err
+	}
+
+	dialect := d.conn.dialect()
+	sqls := make([]string, 0, len(data))
+	args := make([][]interface{}, 0, len(data))
+	for _, row := range data {
+		columns := sortedColumns(row)
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+		sqls = append(sqls, dialect.insertSQL(table, columns))
+		args = append(args, values)
+	}
+
+	return d.executeBatches(ctx, "insert", sqls, args)
+}
+
+// Update updates the rows matching the where columns of each row.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	if err := d.requireWrite("update"); err != nil {
+		return 0, err
+	}
+	if len(whereColumns) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.JDBCBridge, "whereColumns", "at least one where column is required")
+	}
+
+	dialect := d.conn.dialect()
+	var sqls []string
+	var args [][]interface{}
+	for _, row := range data {
+		sql, values := dialect.updateSQL(table, row, whereColumns)
+		if sql == "" {
+			continue
+		}
+		sqls = append(sqls, sql)
+		args = append(args, values)
+	}
+
+	return d.executeBatches(ctx, "update", sqls, args)
+}
+
+// Upsert updates each row on the unique columns and inserts it when no row was updated.
+// There is no portable MERGE statement, so rows are merged one at a time and not atomically.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if err := d.requireWrite("upsert"); err != nil {
+		return 0, err
+	}
+	if len(uniqueColumns) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.JDBCBridge, "uniqueColumns", "at least one unique column is required")
+	}
+
+	dialect := d.conn.dialect()
+	var affected int64
+	for _, row := range data {
+		if sql, values := dialect.updateSQL(table, row, uniqueColumns); sql != "" {
+			updated, err := d.conn.client.execute(ctx, sql, [][]interface{}{values}, false)
+			if err != nil {
+				return affected, adapter.WrapError(dbcapabilities.JDBCBridge, "upsert", err)
+			}
+			if updated > 0 {
+				affected += updated
+				continue
+			}
+		} else {
+			// Only unique columns: the row is up to date if it exists
+			conditions := make(map[string]interface{}, len(uniqueColumns))
+			for _, col := range uniqueColumns {
+				conditions[col] = row[col]
+			}
+			where, whereArgs := dialect.whereSQL(conditions)
+			result, err := d.conn.client.query(ctx, dialect.selectSQL(table, uniqueColumns, "")+" WHERE "+where, whereArgs, 0, 1)
+			if err != nil {
+				return affected, adapter.WrapError(dbcapabilities.JDBCBridge, "upsert", err)
+			}
+			if len(result.rows) > 0 {
+				continue
+			}
+		}
+
+		columns := sortedColumns(row)
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+		inserted, err := d.conn.client.execute(ctx, dialect.insertSQL(table, columns), [][]interface{}{values}, false)
+		if err != nil {
+			return affected, adapter.WrapError(dbcapabilities.JDBCBridge, "upsert", err)
+		}
+		affected += inserted
+	}
+	return affected, nil
+}
+
+// Delete deletes the rows matching the conditions.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	if err := d.requireWrite("delete"); err != nil {
+		return 0, err
+	}
+	if len(conditions) == 0 {
+		return 0, adapter.NewDatabaseError(
+			dbcapabilities.JDBCBridge,
+			"delete",
+			adapter.ErrInvalidData,
+		).WithContext("error", "conditions cannot be empty")
+	}
+
+	sql, args := d.conn.dialect().deleteSQL(table, conditions)
+	deleted, err := d.conn.client.execute(ctx, sql, [][]interface{}{args}, false)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.JDBCBridge, "delete", err)
+	}
+	return deleted, nil
+}
+
+// ExecuteQuery runs a statement and returns its rows.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	if err := d.requireQuery("execute query"); err != nil {
+		return nil, err
+	}
+
+	result, err := d.conn.client.query(ctx, query, args, 0, 0)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "execute_query", err)
+	}
+
+	rows := make([]interface{}, len(result.rows))
+	for i, row := range result.rows {
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// ExecuteCountQuery runs a query returning a single count.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	if err := d.requireQuery("execute count query"); err != nil {
+		return 0, err
+	}
+
+	result, err := d.conn.client.query(ctx, query, nil, 0, 1)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.JDBCBridge, "execute_count_query", err)
+	}
+	return firstCount(result)
+}
+
+// GetRowCount counts the rows of a table matching the where clause.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	result, err := d.conn.client.query(ctx, d.conn.dialect().countSQL(table, whereClause), nil, 0, 1)
+	if err != nil {
+		return 0, false, adapter.WrapError(dbcapabilities.JDBCBridge, "get_row_count", err)
+	}
+
+	count, err := firstCount(result)
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
+
+// Wipe deletes all rows of every table of the configured schema.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	if err := d.requireWrite("wipe"); err != nil {
+		return err
+	}
+
+	refs, err := d.conn.client.listTables(ctx, d.conn.schema, "TABLE")
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.JDBCBridge, "wipe", err)
+	}
+
+	dialect := d.conn.dialect()
+	for _, ref := range refs {
+		sql := "DELETE FROM " + dialect.quoteIdentifier(ref.Name)
+		if ref.Schema != "" {
+			sql = "DELETE FROM " + dialect.quoteIdentifier(ref.Schema) + "." + dialect.quoteIdentifier(ref.Name)
+		}
+		if _, err := d.conn.client.execute(ctx, sql, nil, false); err != nil {
+			return adapter.WrapError(dbcapabilities.JDBCBridge, "wipe", fmt.Errorf("table %s: %w", ref.Name, err))
+		}
+	}
+	return nil
+}
+
+// executeBatches runs the statements grouped into batches, each batch in one transaction
+func (d *DataOps) executeBatches(ctx context.Context, operation string, sqls []string, args [][]interface{}) (int64, error) {
+	var affected int64
+	for _, batch := range batchStatements(sqls, args) {
+		n, err := d.conn.client.execute(ctx, batch.sql, batch.args, true)
+		if err != nil {
+			return affected, adapter.WrapError(dbcapabilities.JDBCBridge, operation, err)
+		}
+		affected += n
+	}
+	return affected, nil
+}
+
+// firstCount returns the first column of the first row of a result as a count
+func firstCount(result *queryResult) (int64, error) {
+	if len(result.rows) == 0 || len(result.columns) == 0 {
+		return 0, adapter.NewDatabaseError(dbcapabilities.JDBCBridge, "count", adapter.ErrInvalidData).
+			WithContext("error", "count query returned no rows")
+	}
+
+	switch v := result.rows[0][result.columns[0]].(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		count, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, adapter.NewDatabaseError(dbcapabilities.JDBCBridge, "count", adapter.ErrInvalidData).
+				WithContext("error", fmt.Sprintf("invalid count %q", v))
+		}
+		return count, nil
+	default:
+		return 0, adapter.NewDatabaseError(dbcapabilities.JDBCBridge, "count", adapter.ErrInvalidData).
+			WithContext("error", fmt.Sprintf("unexpected count of type %T", v))
+	}
+}
@@ -0,0 +1,7 @@
+package jdbcbridge
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	adapter.Register(NewAdapter())
+}
@@ -0,0 +1,108 @@
+package jdbcbridge
+
+import (
+	"context"
+	"encoding/json"
+
+	jdbcbridgev1 "github.com/redbco/redb-open/api/proto/jdbcbridge/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations through the bridge for database and instance
+// connections.
+type MetadataOps struct {
+	client *Client
+}
+
+// CollectDatabaseMetadata collects the metadata the driver reports about the database.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	resp, err := m.client.metadata(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "collect_database_metadata", err)
+	}
+
+	metadata := m.baseMetadata()
+	metadata["url"] = resp.Url
+	metadata["user_name"] = resp.UserName
+	metadata["table_count"] = resp.TableCount
+	if resp.SizeBytes >= 0 {
+		metadata["size_bytes"] = resp.SizeBytes
+	}
+	if resp.Properties != nil {
+		metadata["properties"] = resp.Properties.AsMap()
+	}
+	return metadata, nil
+}
+
+// CollectInstanceMetadata returns the product and driver information of the session.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	metadata := m.baseMetadata()
+	if m.client.supports(jdbcbridgev1.Operation_OPERATION_LIST_CATALOGS) {
+		catalogs, err := m.client.listCatalogs(ctx)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "collect_instance_metadata", err)
+		}
+		metadata["total_databases"] = len(catalogs)
+	}
+	return metadata, nil
+}
+
+// baseMetadata returns the information the bridge reported when the session was opened
+func (m *MetadataOps) baseMetadata() map[string]interface{} {
+	info := m.client.info
+	return map[string]interface{}{
+		"database_type":        string(dbcapabilities.JDBCBridge),
+		"product_name":         info.ProductName,
+		"version":              info.ProductVersion,
+		"driver_name":          info.DriverName,
+		"driver_version":       info.DriverVersion,
+		"bridge_version":       info.BridgeVersion,
+		"read_only":            info.ReadOnly,
+		"supported_operations": m.client.supportedOperations(),
+	}
+}
+
+// GetVersion returns the product version reported by the driver.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return m.client.info.ProductVersion, nil
+}
+
+// GetUniqueIdentifier is not supported: JDBC has no portable instance identifier.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return "", adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "get unique identifier", "jdbc has no portable instance identifier")
+}
+
+// GetDatabaseSize returns the database size if the bridge can determine it.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	resp, err := m.client.metadata(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.JDBCBridge, "get_database_size", err)
+	}
+	if resp.SizeBytes < 0 {
+		return 0, adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "get database size", "not reported by the bridge for this database")
+	}
+	return resp.SizeBytes, nil
+}
+
+// GetTableCount returns the number of tables reported by the bridge.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	resp, err := m.client.metadata(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.JDBCBridge, "get_table_count", err)
+	}
+	return int(resp.TableCount), nil
+}
+
+// ExecuteCommand executes a statement and returns the number of affected rows as JSON.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	if !m.client.supports(jdbcbridgev1.Operation_OPERATION_QUERY) {
+		return nil, adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "execute command", "the bridge does not support arbitrary statements on this database")
+	}
+
+	affected, err := m.client.execute(ctx, command, nil, false)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "execute_command", err)
+	}
+	return json.Marshal(map[string]interface{}{"affected_rows": affected})
+}
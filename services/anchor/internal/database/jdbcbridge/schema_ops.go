@@ -0,0 +1,182 @@
+package jdbcbridge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	jdbcbridgev1 "github.com/redbco/redb-open/api/proto/jdbcbridge/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// SchemaOps implements schema operations through the bridge.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema describes every table and view of the configured schema.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	refs, err := s.conn.client.listTables(ctx, s.conn.schema, "TABLE", "VIEW")
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "discover_schema", err)
+	}
+
+	model := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.JDBCBridge,
+		Tables:       make(map[string]unifiedmodel.Table),
+		Views:        make(map[string]unifiedmodel.View),
+	}
+	for _, ref := range refs {
+		def, err := s.conn.client.describeTable(ctx, ref.Schema, ref.Name)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "discover_schema", fmt.Errorf("table %s: %w", ref.Name, err))
+		}
+
+		table := convertTable(def)
+		if strings.EqualFold(ref.Type, "VIEW") {
+			model.Views[ref.Name] = unifiedmodel.View{
+				Name:    ref.Name,
+				Comment: ref.Remarks,
+				Columns: table.Columns,
+				Options: table.Options,
+			}
+			continue
+		}
+		model.Tables[ref.Name] = table
+	}
+
+	return model, nil
+}
+
+// CreateStructure is not supported: DDL is not portable across JDBC databases.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.JDBCBridge, "schema creation", "DDL is not portable across JDBC databases")
+}
+
+// ListTables returns the names of the tables of the configured schema.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	refs, err := s.conn.client.listTables(ctx, s.conn.schema, "TABLE")
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "list_tables", err)
+	}
+
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetTableSchema returns the schema of a single table. The name may be qualified with a schema.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	schema, name := s.conn.dialect().splitTable(tableName)
+	def, err := s.conn.client.describeTable(ctx, schema, name)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.JDBCBridge, "get_table_schema", fmt.Errorf("table %s: %w", tableName, err))
+	}
+	table := convertTable(def)
+	return &table, nil
+}
+
+// convertTable converts a table definition reported by the bridge to a unified model table
+func convertTable(def *jdbcbridgev1.TableDefinition) unifiedmodel.Table {
+	ref := def.Table
+	if ref == nil {
+		ref = &jdbcbridgev1.TableRef{}
+	}
+
+	table := unifiedmodel.Table{
+		Name:        ref.Name,
+		Comment:     ref.Remarks,
+		Columns:     make(map[string]unifiedmodel.Column, len(def.Columns)),
+		Indexes:     make(map[string]unifiedmodel.Index),
+		Constraints: make(map[string]unifiedmodel.Constraint),
+		Options:     map[string]any{"catalog": ref.Catalog, "schema": ref.Schema},
+	}
+
+	primaryKey := make(map[string]bool, len(def.PrimaryKey))
+	for _, col := range def.PrimaryKey {
+		primaryKey[col] = true
+	}
+
+	for _, col := range def.Columns {
+		position := int(col.OrdinalPosition)
+		column := unifiedmodel.Column{
+			Name:            col.Name,
+			DataType:        columnDataType(col),
+			Nullable:        col.Nullable,
+			Default:         col.DefaultValue,
+			IsPrimaryKey:    primaryKey[col.Name],
+			AutoIncrement:   col.AutoIncrement,
+			OrdinalPosition: &position,
+			Options:         map[string]any{"jdbc_type": col.JdbcType},
+		}
+		if col.Remarks != "" {
+			column.Options["comment"] = col.Remarks
+		}
+		if col.Generated {
+			column.Options["generated"] = true
+		}
+		table.Columns[col.Name] = column
+	}
+
+	if len(def.PrimaryKey) > 0 {
+		name := def.PrimaryKeyName
+		if name == "" {
+			name = ref.Name + "_pkey"
+		}
+		table.Constraints[name] = unifiedmodel.Constraint{
+			Name:    name,
+			Type:    unifiedmodel.ConstraintTypePrimaryKey,
+			Columns: def.PrimaryKey,
+		}
+	}
+
+	for _, idx := range def.Indexes {
+		table.Indexes[idx.Name] = unifiedmodel.Index{
+			Name:    idx.Name,
+			Columns: idx.Columns,
+			Unique:  idx.Unique,
+		}
+	}
+
+	for _, fk := range def.ForeignKeys {
+		name := fk.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_fkey", ref.Name, strings.Join(fk.Columns, "_"))
+		}
+		table.Constraints[name] = unifiedmodel.Constraint{
+			Name:    name,
+			Type:    unifiedmodel.ConstraintTypeForeignKey,
+			Columns: fk.Columns,
+			Reference: unifiedmodel.Reference{
+				Table:    fk.ReferencedTable,
+				Columns:  fk.ReferencedColumns,
+				OnUpdate: fk.OnUpdate,
+				OnDelete: fk.OnDelete,
+			},
+		}
+	}
+
+	return table
+}
+
+// columnDataType returns the database type of a column with its size where the type takes one
+func columnDataType(col *jdbcbridgev1.ColumnDefinition) string {
+	typeName := strings.ToLower(col.TypeName)
+	if col.Size <= 0 || strings.Contains(typeName, "(") {
+		return typeName
+	}
+
+	switch {
+	case strings.Contains(typeName, "char"), strings.Contains(typeName, "binary"):
+		return fmt.Sprintf("%s(%d)", typeName, col.Size)
+	case typeName == "decimal" || typeName == "numeric" || typeName == "number":
+		return fmt.Sprintf("%s(%d,%d)", typeName, col.Size, col.DecimalDigits)
+	}
+	return typeName
+}
@@ -0,0 +1,159 @@
+package jdbcbridge
+
+import (
+	"sort"
+	"strings"
+)
+
+// sqlDialect builds the statements sent through the bridge. Only SQL that every JDBC database
+// accepts is generated: ? placeholders and no LIMIT clauses, since the bridge limits results
+// through JDBC itself.
+type sqlDialect struct {
+	// quote is the identifier quote string reported by the driver; empty or a space if
+	// identifiers cannot be quoted
+	quote string
+	// schema qualifies unqualified table names; empty uses the default schema of the session
+	schema string
+}
+
+// quoteIdentifier quotes a single identifier
+func (d sqlDialect) quoteIdentifier(name string) string {
+	if strings.TrimSpace(d.quote) == "" {
+		return name
+	}
+	return d.quote + strings.ReplaceAll(name, d.quote, d.quote+d.quote) + d.quote
+}
+
+// splitTable splits a table name into its schema and name. Names without a schema get the
+// configured schema.
+func (d sqlDialect) splitTable(table string) (string, string) {
+	if i := strings.LastIndex(table, "."); i > 0 {
+		return table[:i], table[i+1:]
+	}
+	return d.schema, table
+}
+
+// qualifiedTable returns the quoted, schema-qualified name of a table
+func (d sqlDialect) qualifiedTable(table string) string {
+	schema, name := d.splitTable(table)
+	if schema == "" {
+		return d.quoteIdentifier(name)
+	}
+	return d.quoteIdentifier(schema) + "." + d.quoteIdentifier(name)
+}
+
+// columnList returns the quoted columns separated by commas, or * for none
+func (d sqlDialect) columnList(columns []string) string {
+	if len(columns) == 0 {
+		return "*"
+	}
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = d.quoteIdentifier(col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// selectSQL returns a query for the columns of a table, ordered by orderBy if given
+func (d sqlDialect) selectSQL(table string, columns []string, orderBy string) string {
+	sql := "SELECT " + d.columnList(columns) + " FROM " + d.qualifiedTable(table)
+	if orderBy != "" {
+		sql += " ORDER BY " + d.quoteIdentifier(orderBy)
+	}
+	return sql
+}
+
+// countSQL returns a query counting the rows of a table matching the where clause
+func (d sqlDialect) countSQL(table, whereClause string) string {
+	sql := "SELECT COUNT(*) FROM " + d.qualifiedTable(table)
+	if whereClause != "" {
+		sql += " WHERE " + whereClause
+	}
+	return sql
+}
+
+// insertSQL returns an insert statement for the columns
+func (d sqlDialect) insertSQL(table string, columns []string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return "INSERT INTO " + d.qualifiedTable(table) + " (" + d.columnList(columns) + ") VALUES (" + placeholders + ")"
+}
+
+// updateSQL returns an update statement setting the columns of a row that are not where
+// columns, and its arguments
+func (d sqlDialect) updateSQL(table string, row map[string]interface{}, whereColumns []string) (string, []interface{}) {
+	isWhere := make(map[string]bool, len(whereColumns))
+	for _, col := range whereColumns {
+		isWhere[col] = true
+	}
+
+	var sets []string
+	var args []interface{}
+	for _, col := range sortedColumns(row) {
+		if isWhere[col] {
+			continue
+		}
+		sets = append(sets, d.quoteIdentifier(col)+" = ?")
+		args = append(args, row[col])
+	}
+	if len(sets) == 0 {
+		return "", nil
+	}
+
+	conditions := make(map[string]interface{}, len(whereColumns))
+	for _, col := range whereColumns {
+		conditions[col] = row[col]
+	}
+	where, whereArgs := d.whereSQL(conditions)
+	return "UPDATE " + d.qualifiedTable(table) + " SET " + strings.Join(sets, ", ") + " WHERE " + where, append(args, whereArgs...)
+}
+
+// deleteSQL returns a delete statement for the rows matching the conditions, and its arguments
+func (d sqlDialect) deleteSQL(table string, conditions map[string]interface{}) (string, []interface{}) {
+	where, args := d.whereSQL(conditions)
+	return "DELETE FROM " + d.qualifiedTable(table) + " WHERE " + where, args
+}
+
+// whereSQL returns the conjunction of equality conditions, using IS NULL for nil values
+func (d sqlDialect) whereSQL(conditions map[string]interface{}) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for _, col := range sortedColumns(conditions) {
+		value := conditions[col]
+		if value == nil {
+			clauses = append(clauses, d.quoteIdentifier(col)+" IS NULL")
+			continue
+		}
+		clauses = append(clauses, d.quoteIdentifier(col)+" = ?")
+		args = append(args, value)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// sortedColumns returns the columns of a row in a stable order
+func sortedColumns(row map[string]interface{}) []string {
+	columns := make([]string, 0, len(row))
+	for col := range row {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// statementBatch is a statement with the arguments of each of its executions
+type statementBatch struct {
+	sql  string
+	args [][]interface{}
+}
+
+// batchStatements groups consecutive executions of the same statement, keeping their order
+func batchStatements(sqls []string, args [][]interface{}) []statementBatch {
+	var batches []statementBatch
+	for i, sql := range sqls {
+		if n := len(batches); n > 0 && batches[n-1].sql == sql {
+			batches[n-1].args = append(batches[n-1].args, args[i])
+			continue
+		}
+		batches = append(batches, statementBatch{sql: sql, args: [][]interface{}{args[i]}})
+	}
+	return batches
+}
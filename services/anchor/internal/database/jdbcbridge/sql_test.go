@@ -0,0 +1,86 @@
+package jdbcbridge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		quote    string
+		input    string
+		expected string
+	}{
+		{`"`, "orders", `"orders"`},
+		{`"`, `my"table`, `"my""table"`},
+		{"`", "orders", "`orders`"},
+		{" ", "orders", "orders"},
+		{"", "orders", "orders"},
+	}
+
+	for _, test := range tests {
+		result := sqlDialect{quote: test.quote}.quoteIdentifier(test.input)
+		if result != test.expected {
+			t.Errorf("quoteIdentifier(%q) with quote %q = %q, expected %q", test.input, test.quote, result, test.expected)
+		}
+	}
+}
+
+func TestQualifiedTable(t *testing.T) {
+	d := sqlDialect{quote: `"`, schema: "sales"}
+	if got := d.qualifiedTable("orders"); got != `"sales"."orders"` {
+		t.Errorf("unexpected qualified table %s", got)
+	}
+	if got := d.qualifiedTable("hr.staff"); got != `"hr"."staff"` {
+		t.Errorf("unexpected qualified table %s", got)
+	}
+	if got := (sqlDialect{quote: `"`}).qualifiedTable("orders"); got != `"orders"` {
+		t.Errorf("unexpected unqualified table %s", got)
+	}
+}
+
+func TestStatements(t *testing.T) {
+	d := sqlDialect{quote: `"`}
+
+	if got := d.selectSQL("orders", nil, ""); got != `SELECT * FROM "orders"` {
+		t.Errorf("unexpected select %s", got)
+	}
+	if got := d.selectSQL("orders", []string{"id", "total"}, "id"); got != `SELECT "id", "total" FROM "orders" ORDER BY "id"` {
+		t.Errorf("unexpected select %s", got)
+	}
+	if got := d.insertSQL("orders", []string{"id", "total"}); got != `INSERT INTO "orders" ("id", "total") VALUES (?, ?)` {
+		t.Errorf("unexpected insert %s", got)
+	}
+
+	sql, args := d.updateSQL("orders", map[string]interface{}{"id": 7, "total": 10.5, "note": nil}, []string{"id"})
+	if sql != `UPDATE "orders" SET "note" = ?, "total" = ? WHERE "id" = ?` {
+		t.Errorf("unexpected update %s", sql)
+	}
+	if !reflect.DeepEqual(args, []interface{}{nil, 10.5, 7}) {
+		t.Errorf("unexpected update arguments %v", args)
+	}
+	if sql, _ := d.updateSQL("orders", map[string]interface{}{"id": 7}, []string{"id"}); sql != "" {
+		t.Errorf("expected no update without columns to set, got %s", sql)
+	}
+
+	sql, args = d.deleteSQL("orders", map[string]interface{}{"id": 7, "deleted_at": nil})
+	if sql != `DELETE FROM "orders" WHERE "deleted_at" IS NULL AND "id" = ?` {
+		t.Errorf("unexpected delete %s", sql)
+	}
+	if !reflect.DeepEqual(args, []interface{}{7}) {
+		t.Errorf("unexpected delete arguments %v", args)
+	}
+}
+
+func TestBatchStatements(t *testing.T) {
+	batches := batchStatements(
+		[]string{"a", "a", "b", "a"},
+		[][]interface{}{{1}, {2}, {3}, {4}},
+	)
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+	if batches[0].sql != "a" || len(batches[0].args) != 2 || batches[2].sql != "a" || len(batches[2].args) != 1 {
+		t.Errorf("unexpected batches %+v", batches)
+	}
+}
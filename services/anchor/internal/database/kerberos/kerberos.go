@@ -0,0 +1,227 @@
+// Package kerberos obtains the Kerberos tickets of the database adapters supporting Kerberos
+// (GSSAPI) authentication, with the keytab or the credential cache of adapter.KerberosAuth.
+// The credentials of a client principal are shared by the connections authenticating as it,
+// so the connections of a pool reuse its ticket-granting ticket and service tickets.
+package kerberos
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/iana/nametype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// Credentials obtains the tickets of a client principal. Clients logged in with a keytab
+// renew their ticket-granting ticket before it expires, and log in again once it can no
+// longer be renewed. Clients reading a credential cache load it again when kinit or k5start
+// has replaced it, or when its ticket-granting ticket expired.
+type Credentials struct {
+	// ID identifies the credentials among the ones of the anchor, see ByID
+	ID string
+
+	user, realm string
+	auth        adapter.KerberosAuth
+
+	mu      sync.Mutex
+	client  *client.Client
+	modTime time.Time // of the credential cache the client was loaded from
+}
+
+// credentialsKey identifies the credentials of a client principal
+type credentialsKey struct {
+	user, realm, keytab, ccache, krb5Conf string
+}
+
+var registry = struct {
+	sync.Mutex
+	byKey map[credentialsKey]*Credentials
+	byID  map[string]*Credentials
+}{byKey: make(map[credentialsKey]*Credentials), byID: make(map[string]*Credentials)}
+
+// For returns the credentials of a Kerberos authentication, shared with the connections that
+// authenticate as the same principal with the same keytab or credential cache. username is
+// the principal when auth does not name one.
+func For(auth adapter.KerberosAuth, username string) (*Credentials, error) {
+	if err := auth.Validate(); err != nil {
+		return nil, err
+	}
+	user, realm := auth.ClientPrincipal(username)
+	if user == "" && auth.Keytab != "" {
+		return nil, fmt.Errorf("the principal of the keytab is required")
+	}
+	key := credentialsKey{user: user, realm: realm, keytab: auth.Keytab, ccache: auth.CCache, krb5Conf: auth.Krb5ConfPath()}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if c, ok := registry.byKey[key]; ok {
+		return c, nil
+	}
+	c := &Credentials{ID: strconv.Itoa(len(registry.byKey) + 1), user: user, realm: realm, auth: auth}
+	registry.byKey[key] = c
+	registry.byID[c.ID] = c
+	return c, nil
+}
+
+// ByID returns the credentials with an ID.
+func ByID(id string) (*Credentials, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	c, ok := registry.byID[id]
+	return c, ok
+}
+
+// InitToken returns the SPNEGO token initiating a security context with the service principal
+// spn, e.g. postgres/db.example.com, carrying a service ticket of the principal.
+func (c *Credentials) InitToken(spn string) ([]byte, error) {
+	cl, err := c.loggedIn()
+	if err != nil {
+		return nil, err
+	}
+	token, err := initToken(cl, spn)
+	if err != nil && c.auth.CCache != "" {
+		// The ticket-granting ticket may have expired while the cache was renewed in place
+		c.reset()
+		if cl, err = c.loggedIn(); err != nil {
+			return nil, err
+		}
+		token, err = initToken(cl, spn)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting a kerberos ticket for %s: %w", spn, err)
+	}
+	return token, nil
+}
+
+// CompleteContext checks the SPNEGO token a service answered an initial token with.
+func CompleteContext(token []byte) error {
+	var response spnego.SPNEGOToken
+	if err := response.Unmarshal(token); err != nil {
+		return fmt.Errorf("invalid kerberos response: %w", err)
+	}
+	if !response.Resp || response.NegTokenResp.State() != spnego.NegStateAcceptCompleted {
+		return fmt.Errorf("kerberos authentication was not accepted")
+	}
+	return nil
+}
+
+// TGTExpiry returns when the ticket-granting ticket of a credential cache expires, so that
+// drivers reading the cache themselves can be told that it needs renewing.
+func TGTExpiry(ccache string) (time.Time, error) {
+	cache, err := credentials.LoadCCache(ccache)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading credential cache %s: %w", ccache, err)
+	}
+	tgt, ok := cache.GetEntry(types.PrincipalName{
+		NameType:   nametype.KRB_NT_SRV_INST,
+		NameString: []string{"krbtgt", cache.DefaultPrincipal.Realm},
+	})
+	if !ok {
+		return time.Time{}, fmt.Errorf("credential cache %s holds no ticket-granting ticket", ccache)
+	}
+	return tgt.EndTime, nil
+}
+
+func initToken(cl *client.Client, spn string) ([]byte, error) {
+	token, err := spnego.SPNEGOClient(cl, spn).InitSecContext()
+	if err != nil {
+		return nil, err
+	}
+	return token.Marshal()
+}
+
+// loggedIn returns the client of the credentials, logging in or loading the credential cache
+// again when needed
+func (c *Credentials) loggedIn() (*client.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.auth.CCache != "" {
+		info, err := os.Stat(c.auth.CCache)
+		if err != nil {
+			return nil, fmt.Errorf("error reading credential cache: %w", err)
+		}
+		if c.client != nil && !info.ModTime().After(c.modTime) {
+			return c.client, nil
+		}
+		cl, err := c.fromCCache()
+		if err != nil {
+			return nil, err
+		}
+		if c.client != nil {
+			c.client.Destroy()
+		}
+		c.client, c.modTime = cl, info.ModTime()
+		return cl, nil
+	}
+
+	if c.client != nil {
+		return c.client, nil
+	}
+	cl, err := c.withKeytab()
+	if err != nil {
+		return nil, err
+	}
+	c.client = cl
+	return cl, nil
+}
+
+// reset drops the client, so the next ticket is obtained with a new one
+func (c *Credentials) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		c.client.Destroy()
+		c.client = nil
+	}
+}
+
+func (c *Credentials) withKeytab() (*client.Client, error) {
+	cfg, err := config.Load(c.auth.Krb5ConfPath())
+	if err != nil {
+		return nil, fmt.Errorf("error reading kerberos configuration: %w", err)
+	}
+	kt, err := keytab.Load(c.auth.Keytab)
+	if err != nil {
+		return nil, fmt.Errorf("error reading keytab: %w", err)
+	}
+	realm := c.realm
+	if realm == "" {
+		realm = cfg.LibDefaults.DefaultRealm
+	}
+	// Logging in starts the renewal of the ticket-granting ticket
+	cl := client.NewWithKeytab(c.user, realm, kt, cfg, client.DisablePAFXFAST(true))
+	if err := cl.Login(); err != nil {
+		return nil, fmt.Errorf("error logging in to kerberos as %s@%s: %w", c.user, realm, err)
+	}
+	return cl, nil
+}
+
+func (c *Credentials) fromCCache() (*client.Client, error) {
+	cfg, err := config.Load(c.auth.Krb5ConfPath())
+	if err != nil {
+		return nil, fmt.Errorf("error reading kerberos configuration: %w", err)
+	}
+	cache, err := credentials.LoadCCache(c.auth.CCache)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credential cache: %w", err)
+	}
+	if c.user != "" && cache.DefaultPrincipal.PrincipalName.PrincipalNameString() != c.user {
+		return nil, fmt.Errorf("credential cache holds the tickets of %s, not %s", cache.DefaultPrincipal.PrincipalName.PrincipalNameString(), c.user)
+	}
+	cl, err := client.NewFromCCache(cache, cfg, client.DisablePAFXFAST(true))
+	if err != nil {
+		return nil, fmt.Errorf("error loading credential cache: %w", err)
+	}
+	return cl, nil
+}
@@ -46,6 +46,7 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -107,6 +108,7 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -26,8 +26,9 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 		decryptedPassword = dp
 	}
 
+	// Sockets are local, SSL settings do not apply to them
 	var sslMode string
-	if config.SSL {
+	if config.SSL && config.SocketPath == "" {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			sslMode = "skip-verify"
 		} else {
@@ -38,11 +39,11 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 	}
 
 	// Build the connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?tls=%s",
-		config.Username, decryptedPassword, config.Host, config.Port, config.DatabaseName, sslMode)
+	dsn := fmt.Sprintf("%s:%s@%s/%s?tls=%s",
+		config.Username, decryptedPassword, address(config.Host, config.Port, config.SocketPath), config.DatabaseName, sslMode)
 
 	// Add SSL configuration if enabled
-	if config.SSL && config.SSLCert != "" && config.SSLKey != "" {
+	if sslMode != "false" && config.SSLCert != "" && config.SSLKey != "" {
 		dsn = fmt.Sprintf("%s&sslcert=%s&sslkey=%s", dsn, config.SSLCert, config.SSLKey)
 		if config.SSLRootCert != "" {
 			dsn = fmt.Sprintf("%s&sslrootcert=%s", dsn, config.SSLRootCert)
@@ -88,8 +89,9 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 		decryptedPassword = dp
 	}
 
+	// Sockets are local, SSL settings do not apply to them
 	var sslMode string
-	if config.SSL {
+	if config.SSL && config.SocketPath == "" {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			sslMode = "skip-verify"
 		} else {
@@ -100,11 +102,11 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 	}
 
 	// Build the connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?tls=%s",
-		config.Username, decryptedPassword, config.Host, config.Port, config.DatabaseName, sslMode)
+	dsn := fmt.Sprintf("%s:%s@%s/%s?tls=%s",
+		config.Username, decryptedPassword, address(config.Host, config.Port, config.SocketPath), config.DatabaseName, sslMode)
 
 	// Add SSL configuration if enabled
-	if config.SSL && config.SSLCert != "" && config.SSLKey != "" {
+	if sslMode != "false" && config.SSLCert != "" && config.SSLKey != "" {
 		dsn = fmt.Sprintf("%s&sslcert=%s&sslkey=%s", dsn, config.SSLCert, config.SSLKey)
 		if config.SSLRootCert != "" {
 			dsn = fmt.Sprintf("%s&sslrootcert=%s", dsn, config.SSLRootCert)
@@ -424,6 +426,59 @@ func ExecuteQuery(db interface{}, query string, args ...interface{}) ([]interfac
 	return results, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction and returns its columns
+// and at most maxRows rows. truncated reports whether the query returned more rows.
+func ExecuteReadOnlyQuery(ctx context.Context, db *sql.DB, query string, maxRows int) (columns []string, results []map[string]interface{}, truncated bool, err error) {
+	// START TRANSACTION READ ONLY makes the server reject writes to tables, including those
+	// made by stored functions the query calls
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	// Nothing can be written, so the transaction is always rolled back
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to execute mariadb query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err = rows.Columns()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get mariadb column names: %w", err)
+	}
+
+	results = []map[string]interface{}{}
+	for rows.Next() {
+		if len(results) == maxRows {
+			truncated = true
+			break
+		}
+
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, false, fmt.Errorf("failed to scan mariadb result: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, colName := range columns {
+			row[colName] = values[i]
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, fmt.Errorf("mariadb rows iteration error: %w", err)
+	}
+
+	return columns, results, truncated, nil
+}
+
 // ExecuteCountQuery executes a count query and returns the result
 func ExecuteCountQuery(db interface{}, query string) (int64, error) {
 	sqlDB, ok := db.(*sql.DB)
@@ -603,3 +658,12 @@ func DropDatabase(ctx context.Context, db interface{}, databaseName string, opti
 
 	return nil
 }
+
+// address returns the DSN address of a server: its unix socket when one is set, else its host
+// and port.
+func address(host string, port int, socketPath string) string {
+	if socketPath != "" {
+		return fmt.Sprintf("unix(%s)", socketPath)
+	}
+	return fmt.Sprintf("tcp(%s:%d)", host, port)
+}
@@ -110,6 +110,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 // ExecuteQuery executes a query and returns the results.
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	// Use existing ExecuteQuery function
@@ -120,6 +125,16 @@ func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interf
 	return result, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction, implementing
+// adapter.ReadOnlyQueryExecutor.
+func (d *DataOps) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*adapter.QueryResult, error) {
+	columns, rows, truncated, err := ExecuteReadOnlyQuery(ctx, d.conn.db, query, maxRows)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MariaDB, "execute_read_only_query", err)
+	}
+	return &adapter.QueryResult{Columns: columns, Rows: rows, Truncated: truncated}, nil
+}
+
 // ExecuteCountQuery executes a count query and returns the count.
 func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
 	// MariaDB doesn't have ExecuteCountQuery function
@@ -99,6 +99,14 @@ func CreateStructure(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 	if um == nil {
 		return fmt.Errorf("unified model cannot be nil")
 	}
+
+	// Look up the features of the server version, so no DDL it rejects is generated
+	var version string
+	if err := db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return fmt.Errorf("error fetching MariaDB version: %w", err)
+	}
+	caps, _ := dbcapabilities.GetForVersion(dbcapabilities.MariaDB, version)
+
 	// Start a transaction
 	tx, err := db.Begin()
 	if err != nil {
@@ -112,7 +120,7 @@ func CreateStructure(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 
 	// Create tables from UnifiedModel
 	for _, table := range um.Tables {
-		err = CreateTableFromUnified(tx, table)
+		err = CreateTableFromUnified(tx, table, caps)
 		if err != nil {
 			return fmt.Errorf("error creating table %s: %v", table.Name, err)
 		}
@@ -630,8 +638,9 @@ func discoverExtensionsUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error
 	return rows.Err()
 }
 
-// CreateTableFromUnified creates a table from UnifiedModel Table
-func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table) error {
+// CreateTableFromUnified creates a table from UnifiedModel Table. JSON columns and generated
+// columns fall back to LONGTEXT and plain columns on server versions without them.
+func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table, caps dbcapabilities.VersionedCapability) error {
 	if table.Name == "" {
 		return fmt.Errorf("table name is empty")
 	}
@@ -644,7 +653,11 @@ func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table) error {
 	columnDefs := make([]string, 0, len(table.Columns))
 	for _, col := range table.Columns {
 		var colDef strings.Builder
-		fmt.Fprintf(&colDef, "  %s %s", QuoteIdentifier(col.Name), col.DataType)
+		dataType := col.DataType
+		if strings.EqualFold(dataType, "json") && !caps.Supports(dbcapabilities.FeatureJSONType) {
+			dataType = "LONGTEXT"
+		}
+		fmt.Fprintf(&colDef, "  %s %s", QuoteIdentifier(col.Name), dataType)
 
 		// Note: Length is not part of unifiedmodel.Column
 		// For now, we skip length specification in CREATE TABLE
@@ -665,7 +678,7 @@ func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table) error {
 		}
 
 		// Add generated expression if specified
-		if col.GeneratedExpression != "" {
+		if col.GeneratedExpression != "" && caps.Supports(dbcapabilities.FeatureGeneratedColumns) {
 			fmt.Fprintf(&colDef, " AS (%s)", col.GeneratedExpression)
 		}
 
@@ -2,6 +2,7 @@ package milvus
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -67,6 +68,71 @@ func FetchData(client *MilvusClient, collectionName string, limit int) ([]map[st
 	return result, nil
 }
 
+// SearchData returns the topK nearest neighbors of a vector in a collection, with their
+// primary key and distance. The vector field and the primary key are read from the schema of
+// the collection.
+func SearchData(ctx context.Context, client *MilvusClient, collectionName string, vector []float32, topK int) ([]map[string]interface{}, string, error) {
+	if collectionName == "" {
+		return nil, "", fmt.Errorf("collection name cannot be empty")
+	}
+
+	collection, err := describeCollection(client, collectionName)
+	if err != nil {
+		return nil, "", err
+	}
+	var vectorField, primaryKey string
+	for _, field := range collection.Schema.Fields {
+		switch {
+		case field.PrimaryKey:
+			primaryKey = field.Name
+		case field.Type == "FloatVector" && vectorField == "":
+			vectorField = field.Name
+		}
+	}
+	if vectorField == "" || primaryKey == "" {
+		return nil, "", fmt.Errorf("collection %s has no float vector field or no primary key", collectionName)
+	}
+
+	jsonBody, err := json.Marshal(MilvusSearchRequest{
+		CollectionName: collectionName,
+		AnnsField:      vectorField,
+		Data:           [][]float32{vector},
+		Limit:          int64(topK),
+		OutputFields:   []string{primaryKey},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error marshaling request: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/search", client.BaseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client.Username != "" && client.Password != "" {
+		req.SetBasicAuth(client.Username, client.Password)
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error executing request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("search failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response MilvusQueryResult
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, "", fmt.Errorf("error decoding response: %v", err)
+	}
+	return response.Data, primaryKey, nil
+}
+
 // InsertData inserts vectors into a specified collection
 func InsertData(client *MilvusClient, collectionName string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
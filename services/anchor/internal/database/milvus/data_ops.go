@@ -2,6 +2,7 @@ package milvus
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
@@ -67,6 +68,32 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.Milvus, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Milvus, "fetch stream", "not yet implemented")
+}
+
+// SearchVectors returns the nearest neighbors of a vector in a collection, scored with the
+// distance of the metric of its index
+func (d *DataOps) SearchVectors(ctx context.Context, collectionName string, vector []float32, topK int) ([]adapter.VectorMatch, error) {
+	if err := adapter.ValidateVectorSearch(vector, topK); err != nil {
+		return nil, err
+	}
+	rows, primaryKey, err := SearchData(ctx, d.conn.client, collectionName, vector, topK)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Milvus, "search_vectors", err)
+	}
+	matches := make([]adapter.VectorMatch, 0, len(rows))
+	for _, row := range rows {
+		match := adapter.VectorMatch{ID: fmt.Sprint(row[primaryKey])}
+		if distance, ok := row["distance"].(float64); ok {
+			match.Score = distance
+		}
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.client, query, args...)
 	if err != nil {
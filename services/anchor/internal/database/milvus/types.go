@@ -46,6 +46,15 @@ type MilvusQueryRequest struct {
 	Params         map[string]interface{} `json:"params,omitempty"`
 }
 
+// MilvusSearchRequest represents a nearest neighbor search request to Milvus
+type MilvusSearchRequest struct {
+	CollectionName string      `json:"collection_name"`
+	AnnsField      string      `json:"anns_field"`
+	Data           [][]float32 `json:"data"`
+	Limit          int64       `json:"limit"`
+	OutputFields   []string    `json:"output_fields,omitempty"`
+}
+
 // MilvusQueryResult represents a query result from Milvus
 type MilvusQueryResult struct {
 	Status     string                   `json:"status"`
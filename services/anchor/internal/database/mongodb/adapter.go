@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
@@ -53,6 +54,11 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		SSLCert:               adapter.GetString(config.SSLCert),
 		SSLKey:                adapter.GetString(config.SSLKey),
 		SSLRootCert:           adapter.GetString(config.SSLRootCert),
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
@@ -114,6 +120,11 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		SSLCert:               adapter.GetString(config.SSLCert),
 		SSLKey:                adapter.GetString(config.SSLKey),
 		SSLRootCert:           adapter.GetString(config.SSLRootCert),
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
@@ -160,6 +171,9 @@ type Connection struct {
 	config    adapter.ConnectionConfig
 	adapter   *Adapter
 	connected int32
+
+	// replicationSources holds the change streams opened on this connection, by source ID
+	replicationSources sync.Map
 }
 
 // ID returns the connection identifier.
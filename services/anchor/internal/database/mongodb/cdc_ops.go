@@ -8,10 +8,10 @@ import (
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
@@ -82,6 +82,11 @@ func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]int
 			event.Data = fullDoc
 		}
 
+		// The document key identifies the updated document, also when it was deleted before the lookup
+		if docKey, ok := rawEvent["documentKey"].(map[string]interface{}); ok {
+			event.OldData = docKey
+		}
+
 		// Also capture the update description
 		if updateDesc, ok := rawEvent["updateDescription"].(map[string]interface{}); ok {
 			event.Metadata["update_description"] = updateDesc
@@ -105,7 +110,10 @@ func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]int
 	}
 
 	// Extract cluster time as LSN equivalent
-	if clusterTime, ok := rawEvent["clusterTime"]; ok {
+	if clusterTime, ok := rawEvent["clusterTime"].(bson.Timestamp); ok {
+		event.LSN = fmt.Sprintf("%d.%d", clusterTime.T, clusterTime.I)
+		event.Timestamp = time.Unix(int64(clusterTime.T), 0)
+	} else if clusterTime, ok := rawEvent["clusterTime"]; ok {
 		event.LSN = fmt.Sprintf("%v", clusterTime)
 	}
 
@@ -319,7 +327,7 @@ func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]inte
 	var grpcConn *grpc.ClientConn
 	if transformationServiceEndpoint != "" {
 		// Connect to transformation service
-		conn, err := grpc.Dial(transformationServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
 		if err == nil {
 			transformClient = transformationv1.NewTransformationServiceClient(conn)
 			grpcConn = conn
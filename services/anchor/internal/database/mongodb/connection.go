@@ -57,8 +57,16 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 		connString.WriteString("&tls=false")
 	}
 
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %v", err)
+	}
+
 	// Set client options
 	clientOptions := options.Client().ApplyURI(connString.String())
+	if tlsConfig != nil {
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
 
 	// Create client and connect (in v2, Connect handles both creation and connection)
 	client, err := mongo.Connect(clientOptions)
@@ -130,8 +138,16 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 		connString.WriteString("&tls=false")
 	}
 
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %v", err)
+	}
+
 	// Set client options
 	clientOptions := options.Client().ApplyURI(connString.String())
+	if tlsConfig != nil {
+		clientOptions.SetTLSConfig(tlsConfig)
+	}
 
 	// Create client and connect (in v2, Connect handles both creation and connection)
 	client, err := mongo.Connect(clientOptions)
@@ -47,6 +47,39 @@ func FetchData(db *mongo.Database, collectionName string, limit int) ([]map[stri
 	return result, nil
 }
 
+// ExplainFetch returns the explain output of the find FetchData runs, with the
+// queryPlanner verbosity or, with analyze, the executionStats verbosity which runs the query.
+func ExplainFetch(ctx context.Context, db *mongo.Database, collectionName string, limit int, analyze bool) (string, string, error) {
+	if collectionName == "" {
+		return "", "", fmt.Errorf("collection name cannot be empty")
+	}
+
+	find := bson.D{{Key: "find", Value: collectionName}, {Key: "filter", Value: bson.D{}}}
+	if limit > 0 {
+		find = append(find, bson.E{Key: "limit", Value: int64(limit)})
+	}
+	verbosity := "queryPlanner"
+	if analyze {
+		verbosity = "executionStats"
+	}
+
+	var result bson.M
+	cmd := bson.D{{Key: "explain", Value: find}, {Key: "verbosity", Value: verbosity}}
+	if err := db.RunCommand(ctx, cmd).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("error explaining find on collection %s: %v", collectionName, err)
+	}
+
+	query, err := bson.MarshalExtJSON(find, false, false)
+	if err != nil {
+		return "", "", fmt.Errorf("error encoding find command: %v", err)
+	}
+	plan, err := bson.MarshalExtJSON(result, false, false)
+	if err != nil {
+		return "", "", fmt.Errorf("error encoding explain output: %v", err)
+	}
+	return string(query), string(plan), nil
+}
+
 // InsertData inserts data into a specified collection
 func InsertData(db *mongo.Database, collectionName string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
@@ -21,6 +21,15 @@ func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[str
 	return data, nil
 }
 
+// ExplainFetch returns the execution plan of the find behind Fetch.
+func (d *DataOps) ExplainFetch(ctx context.Context, table string, limit int, analyze bool) (*adapter.QueryPlan, error) {
+	query, plan, err := ExplainFetch(ctx, d.conn.db, table, limit, analyze)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MongoDB, "explain_fetch", err)
+	}
+	return &adapter.QueryPlan{Query: query, Format: "json", Plan: plan, Analyzed: analyze}, nil
+}
+
 // FetchWithColumns retrieves specific fields from a collection.
 func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
 	// MongoDB FetchData doesn't support field filtering, so we fetch all and filter
@@ -100,6 +109,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 // ExecuteQuery executes a raw query (not typically used in MongoDB).
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	// MongoDB typically uses BSON queries, not SQL strings
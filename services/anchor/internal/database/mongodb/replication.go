@@ -1,10 +1,9 @@
 package mongodb
 
 import (
-	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -12,233 +11,72 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-// CreateReplicationSource sets up a replication source using MongoDB Change Streams
-func CreateReplicationSource(db *mongo.Database, collectionName string, databaseID string, eventHandler func(map[string]interface{})) (*MongoDBReplicationSourceDetails, error) {
-	// Validate inputs
-	if collectionName == "" {
-		return nil, fmt.Errorf("collection name cannot be empty")
-	}
-
-	// Create replication details
-	details := &MongoDBReplicationSourceDetails{
-		CollectionName: collectionName,
-		DatabaseID:     databaseID,
-	}
-
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
-
-	return details, nil
-}
-
-// ReconnectToReplicationSource reconnects to an existing replication source
-func ReconnectToReplicationSource(db *mongo.Database, details *MongoDBReplicationSourceDetails, eventHandler func(map[string]interface{})) error {
-	// Verify that the collection still exists
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// Server error codes of change streams that cannot be resumed
+const (
+	errCodeChangeStreamFatal       = 280
+	errCodeChangeStreamHistoryLost = 286
+)
 
-	collections, err := db.ListCollectionNames(ctx, bson.D{{Key: "name", Value: details.CollectionName}})
-	if err != nil {
-		return fmt.Errorf("error checking collection: %v", err)
-	}
+// changeStreamOperations are the operation types forwarded as row changes
+var changeStreamOperations = []string{"insert", "update", "replace", "delete"}
 
-	if len(collections) == 0 {
-		return fmt.Errorf("collection %s does not exist", details.CollectionName)
+// buildChangeStreamPipeline builds the pipeline of a database change stream that only
+// passes the document changes of the given collections, or of all collections when none are given
+func buildChangeStreamPipeline(collectionNames []string) mongo.Pipeline {
+	match := bson.D{{Key: "operationType", Value: bson.D{{Key: "$in", Value: changeStreamOperations}}}}
+	if len(collectionNames) > 0 {
+		match = append(match, bson.E{Key: "ns.coll", Value: bson.D{{Key: "$in", Value: collectionNames}}})
 	}
-
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
-
-	return nil
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
 }
 
-// listenForReplicationEvents listens for changes in the specified collection using Change Streams
-func listenForReplicationEvents(db *mongo.Database, details *MongoDBReplicationSourceDetails, eventHandler func(map[string]interface{})) {
-	// Get collection
-	collection := db.Collection(details.CollectionName)
-
-	// Set up change stream options
-	streamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
-
-	// If we have a resume token, use it
-	if details.ResumeToken != "" {
-		var resumeToken bson.Raw
-		if err := json.Unmarshal([]byte(details.ResumeToken), &resumeToken); err == nil {
-			streamOptions.SetResumeAfter(resumeToken)
-		}
+// changeStreamOptions returns the options of a change stream resuming after resumeToken,
+// or starting at the current time when resumeToken is nil
+func changeStreamOptions(resumeToken bson.Raw) *options.ChangeStreamOptionsBuilder {
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetMaxAwaitTime(time.Second)
+	if resumeToken != nil {
+		opts.SetResumeAfter(resumeToken)
 	}
+	return opts
+}
 
-	// Create pipeline for the change stream
-	pipeline := bson.A{}
-
-	for {
-		// Create change stream
-		ctx, cancel := context.WithCancel(context.Background())
-		stream, err := collection.Watch(ctx, pipeline, streamOptions)
-
-		if err != nil {
-			log.Printf("Error creating change stream: %v", err)
-			cancel()
-			time.Sleep(5 * time.Second)
-			continue
-		}
-
-		// Process change events
-		for stream.Next(ctx) {
-			var changeEvent bson.M
-			if err := stream.Decode(&changeEvent); err != nil {
-				log.Printf("Error decoding change event: %v", err)
-				continue
-			}
-
-			// Extract operation type
-			operationType, _ := changeEvent["operationType"].(string)
-
-			// Extract document data
-			var documentData map[string]interface{}
-			var oldDocumentData map[string]interface{}
-
-			// For inserts and replaces, get the full document
-			if fullDoc, ok := changeEvent["fullDocument"].(bson.M); ok {
-				documentData = convertBSONMToMap(fullDoc)
-			}
-
-			// For updates, get the update description
-			if updateDesc, ok := changeEvent["updateDescription"].(bson.M); ok {
-				if updatedFields, ok := updateDesc["updatedFields"].(bson.M); ok {
-					if documentData == nil {
-						documentData = make(map[string]interface{})
-					}
-					for k, v := range convertBSONMToMap(updatedFields) {
-						documentData[k] = v
-					}
-				}
-			}
-
-			// For deletes, get the document key
-			if documentKey, ok := changeEvent["documentKey"].(bson.M); ok {
-				if documentData == nil {
-					documentData = convertBSONMToMap(documentKey)
-				}
-			}
-
-			// Create event to send to handler
-			event := map[string]interface{}{
-				"table":     details.CollectionName,
-				"operation": operationType,
-				"data":      documentData,
-				"old_data":  oldDocumentData,
-			}
-
-			// Save resume token
-			if resumeToken := stream.ResumeToken(); resumeToken != nil {
-				tokenBytes, _ := json.Marshal(resumeToken)
-				details.ResumeToken = string(tokenBytes)
-			}
-
-			// Send event to handler
-			eventHandler(event)
-		}
-
-		// Check for errors
-		if err := stream.Err(); err != nil {
-			log.Printf("Error in change stream: %v", err)
-		}
-
-		// Close the stream and context
-		stream.Close(ctx)
-		cancel()
-
-		// Wait before reconnecting
-		time.Sleep(5 * time.Second)
+// encodeResumeToken formats a resume token as canonical extended JSON, e.g. {"_data":"8263..."}
+func encodeResumeToken(token bson.Raw) (string, error) {
+	b, err := bson.MarshalExtJSON(token, true, false)
+	if err != nil {
+		return "", fmt.Errorf("error encoding resume token: %v", err)
 	}
+	return string(b), nil
 }
 
-// GetReplicationChanges gets changes from a MongoDB change stream
-func GetReplicationChanges(db *mongo.Database, collectionName string, resumeToken string) ([]MongoDBReplicationChange, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Get collection
-	collection := db.Collection(collectionName)
-
-	// Set up change stream options
-	streamOptions := options.ChangeStream().SetFullDocument(options.UpdateLookup)
-
-	// If we have a resume token, use it
-	if resumeToken != "" {
-		var resumeTokenDoc bson.Raw
-		if err := json.Unmarshal([]byte(resumeToken), &resumeTokenDoc); err == nil {
-			streamOptions.SetResumeAfter(resumeTokenDoc)
+// decodeResumeToken parses a resume token given as extended JSON or as the bare _data string
+func decodeResumeToken(position string) (bson.Raw, error) {
+	var doc bson.D
+	if strings.HasPrefix(strings.TrimSpace(position), "{") {
+		if err := bson.UnmarshalExtJSON([]byte(position), true, &doc); err != nil {
+			return nil, fmt.Errorf("invalid resume token %q: %v", position, err)
 		}
+	} else {
+		doc = bson.D{{Key: "_data", Value: position}}
 	}
 
-	// Create pipeline for the change stream
-	pipeline := bson.A{}
-
-	// Create change stream
-	stream, err := collection.Watch(ctx, pipeline, streamOptions)
+	raw, err := bson.Marshal(doc)
 	if err != nil {
-		return nil, fmt.Errorf("error creating change stream: %v", err)
-	}
-	defer stream.Close(ctx)
-
-	var changes []MongoDBReplicationChange
-
-	// Process change events
-	for stream.Next(ctx) {
-		var changeEvent bson.M
-		if err := stream.Decode(&changeEvent); err != nil {
-			return nil, fmt.Errorf("error decoding change event: %v", err)
-		}
-
-		// Extract operation type
-		operationType, _ := changeEvent["operationType"].(string)
-
-		// Extract document data
-		var documentData map[string]interface{}
-		var oldDocumentData map[string]interface{}
-
-		// For inserts and replaces, get the full document
-		if fullDoc, ok := changeEvent["fullDocument"].(bson.M); ok {
-			documentData = convertBSONMToMap(fullDoc)
-		}
-
-		// For updates, get the update description
-		if updateDesc, ok := changeEvent["updateDescription"].(bson.M); ok {
-			if updatedFields, ok := updateDesc["updatedFields"].(bson.M); ok {
-				if documentData == nil {
-					documentData = make(map[string]interface{})
-				}
-				for k, v := range convertBSONMToMap(updatedFields) {
-					documentData[k] = v
-				}
-			}
-		}
-
-		// For deletes, get the document key
-		if documentKey, ok := changeEvent["documentKey"].(bson.M); ok {
-			if documentData == nil {
-				documentData = convertBSONMToMap(documentKey)
-			}
-		}
-
-		// Create change object
-		change := MongoDBReplicationChange{
-			Operation: operationType,
-			Data:      documentData,
-			OldData:   oldDocumentData,
-		}
-
-		changes = append(changes, change)
+		return nil, fmt.Errorf("invalid resume token %q: %v", position, err)
 	}
+	return bson.Raw(raw), nil
+}
 
-	// Check for errors
-	if err := stream.Err(); err != nil {
-		return nil, fmt.Errorf("error in change stream: %v", err)
+// isResumeTokenLost reports whether a change stream error means it cannot be resumed,
+// typically because the resume token has fallen off the oplog
+func isResumeTokenLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
 	}
-
-	return changes, nil
+	return serverErr.HasErrorCode(errCodeChangeStreamHistoryLost) || serverErr.HasErrorCode(errCodeChangeStreamFatal)
 }
 
 // Helper function to convert bson.M to map[string]interface{}
@@ -2,6 +2,8 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,7 +12,14 @@ import (
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// changeStreamCheckpointInterval is how often the resume token of a change stream is persisted
+	changeStreamCheckpointInterval = 10 * time.Second
+
+	// changeStreamRetryDelay is the wait before a failed change stream is reopened
+	changeStreamRetryDelay = 5 * time.Second
 )
 
 // ReplicationOps implements adapter.ReplicationOperator for MongoDB.
@@ -55,25 +64,15 @@ func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
 	return nil
 }
 
-// Connect creates a new replication connection using Change Streams.
+// Connect creates a new replication connection using Change Streams. The change stream
+// watches the database and passes the document changes of the configured collections.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
-	// Create the replication source
 	source := &MongoDBReplicationSource{
-		id:          config.ReplicationID,
-		databaseID:  config.DatabaseID,
-		db:          r.conn.db,
-		config:      config,
-		active:      0,
-		stopChan:    make(chan struct{}),
-		resumeToken: nil,
-	}
-
-	// Wrap the event handler to match the expected signature
-	if config.EventHandler != nil {
-		source.eventHandler = func(event map[string]interface{}) error {
-			config.EventHandler(event)
-			return nil
-		}
+		id:              config.ReplicationID,
+		databaseID:      config.DatabaseID,
+		conn:            r.conn,
+		collectionNames: append([]string(nil), config.TableNames...),
+		eventHandler:    config.EventHandler,
 	}
 
 	// Set starting position if provided
@@ -83,6 +82,7 @@ func (r *ReplicationOps) Connect(ctx context.Context, config adapter.Replication
 		}
 	}
 
+	r.conn.replicationSources.Store(source.id, source)
 	return source, nil
 }
 
@@ -95,13 +95,23 @@ func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{},
 		return nil, adapter.WrapError(dbcapabilities.MongoDB, "get_replication_status", err)
 	}
 
+	sources := r.sources()
+	streams := make([]map[string]interface{}, 0, len(sources))
+	for _, s := range sources {
+		streams = append(streams, s.GetStatus())
+	}
+
 	return map[string]interface{}{
-		"replica_set_status": status,
-		"database_id":        r.conn.id,
+		"replica_set_status":   status,
+		"database_id":          r.conn.id,
+		"mechanism":            "change_streams",
+		"change_streams":       streams,
+		"total_change_streams": len(streams),
 	}, nil
 }
 
-// GetLag returns the replication lag.
+// GetLag returns the replication lag: the replica set optime of the primary and, for
+// every change stream, the time since the cluster time of its last event.
 func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
 	// For MongoDB, lag is typically measured in replica set replication lag
 	var status bson.M
@@ -119,14 +129,30 @@ func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, er
 		for _, member := range members {
 			if m, ok := member.(bson.M); ok {
 				if stateStr, ok := m["stateStr"].(string); ok && stateStr == "PRIMARY" {
-					if optime, ok := m["optimeDate"].(time.Time); ok {
-						lag["primary_optime"] = optime
+					if optime, ok := m["optimeDate"].(bson.DateTime); ok {
+						lag["primary_optime"] = optime.Time()
 					}
 				}
 			}
 		}
 	}
 
+	now := time.Now()
+	streams := make([]map[string]interface{}, 0)
+	for _, s := range r.sources() {
+		last := s.lastClusterTime()
+		if last.IsZero() {
+			streams = append(streams, map[string]interface{}{"source_id": s.id})
+			continue
+		}
+		streams = append(streams, map[string]interface{}{
+			"source_id":         s.id,
+			"last_cluster_time": last.Format(time.RFC3339),
+			"lag_seconds":       now.Sub(last).Seconds(),
+		})
+	}
+	lag["change_streams"] = streams
+
 	return lag, nil
 }
 
@@ -166,19 +192,41 @@ func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName st
 	)
 }
 
+// sources returns the change streams opened on the connection, ordered by source ID
+func (r *ReplicationOps) sources() []*MongoDBReplicationSource {
+	var sources []*MongoDBReplicationSource
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		sources = append(sources, value.(*MongoDBReplicationSource))
+		return true
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].id < sources[j].id })
+	return sources
+}
+
+// GetDB returns the underlying MongoDB database connection (for internal use).
+func (r *ReplicationOps) GetDB() *mongo.Database {
+	return r.conn.db
+}
+
 // MongoDBReplicationSource implements adapter.ReplicationSource for MongoDB Change Streams.
+// The position is the resume token of the last event, or of the last empty batch when the
+// collections are idle; a restarted change stream resumes after it without losing changes.
 type MongoDBReplicationSource struct {
-	id           string
-	databaseID   string
-	db           *mongo.Database
-	config       adapter.ReplicationConfig
-	stream       *mongo.ChangeStream
-	active       int32
-	stopChan     chan struct{}
-	resumeToken  bson.Raw
-	mu           sync.RWMutex
-	eventHandler func(map[string]interface{}) error
-	checkpointFn func(context.Context, string) error
+	id              string
+	databaseID      string
+	conn            *Connection
+	collectionNames []string
+	active          int32
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	mu              sync.RWMutex
+	resumeToken     bson.Raw
+	clusterTime     bson.Timestamp // Cluster time of the last event
+	eventCount      int64
+	lastError       string
+	lastSavedToken  string
+	eventHandler    func(map[string]interface{})
+	checkpointFn    func(context.Context, string) error
 }
 
 // GetSourceID returns the replication source ID.
@@ -197,16 +245,19 @@ func (m *MongoDBReplicationSource) GetStatus() map[string]interface{} {
 	defer m.mu.RUnlock()
 
 	status := map[string]interface{}{
-		"source_id":   m.id,
-		"database_id": m.databaseID,
-		"active":      m.IsActive(),
-		"mechanism":   "change_streams",
+		"source_id":        m.id,
+		"database_id":      m.databaseID,
+		"active":           m.IsActive(),
+		"mechanism":        "change_streams",
+		"collection_names": m.collectionNames,
+		"event_count":      atomic.LoadInt64(&m.eventCount),
+		"last_error":       m.lastError,
 	}
-
 	if m.resumeToken != nil {
-		status["resume_token"] = m.resumeToken.String()
+		if token, err := encodeResumeToken(m.resumeToken); err == nil {
+			status["resume_token"] = token
+		}
 	}
-
 	return status
 }
 
@@ -217,7 +268,7 @@ func (m *MongoDBReplicationSource) GetMetadata() map[string]interface{} {
 		"database_type":   "mongodb",
 		"replication_id":  m.id,
 		"database_id":     m.databaseID,
-		"supported_ops":   []string{"insert", "update", "replace", "delete"},
+		"supported_ops":   changeStreamOperations,
 		"resume_capable":  true,
 		"transaction_log": false,
 	}
@@ -228,9 +279,9 @@ func (m *MongoDBReplicationSource) IsActive() bool {
 	return atomic.LoadInt32(&m.active) == 1
 }
 
-// Start starts the replication source.
+// Start starts the change stream.
 func (m *MongoDBReplicationSource) Start() error {
-	if m.IsActive() {
+	if !atomic.CompareAndSwapInt32(&m.active, 0, 1) {
 		return adapter.NewDatabaseError(
 			dbcapabilities.MongoDB,
 			"start_replication",
@@ -238,144 +289,74 @@ func (m *MongoDBReplicationSource) Start() error {
 		).WithContext("error", "replication source is already active")
 	}
 
-	// Create change stream options
-	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
-
-	// Set resume token if available
-	if m.resumeToken != nil {
-		opts.SetResumeAfter(m.resumeToken)
-	}
-
-	// Create pipeline to filter collections if specified
-	var pipeline mongo.Pipeline
-	if len(m.config.TableNames) > 0 {
-		// Filter by collection names
-		pipeline = mongo.Pipeline{
-			{{Key: "$match", Value: bson.D{
-				{Key: "ns.coll", Value: bson.D{{Key: "$in", Value: m.config.TableNames}}},
-			}}},
-		}
-	}
-
-	// Watch for changes
-	ctx := context.Background()
-	stream, err := m.db.Watch(ctx, pipeline, opts)
-	if err != nil {
-		return adapter.WrapError(dbcapabilities.MongoDB, "start_change_stream", err)
-	}
-
-	m.stream = stream
-	atomic.StoreInt32(&m.active, 1)
-
-	// Start event processing in a goroutine
-	go m.processEvents()
-
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+		m.run(ctx)
+	}()
 	return nil
 }
 
-// processEvents processes change stream events.
-func (m *MongoDBReplicationSource) processEvents() {
-	ctx := context.Background()
-
-	for m.IsActive() {
-		select {
-		case <-m.stopChan:
-			return
-		default:
-			// Try to get next event with timeout
-			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-			hasNext := m.stream.Next(ctx)
-			cancel()
-
-			if !hasNext {
-				if err := m.stream.Err(); err != nil {
-					// Log error but continue
-					continue
-				}
-				continue
-			}
-
-			// Get the change event
-			var changeEvent bson.M
-			if err := m.stream.Decode(&changeEvent); err != nil {
-				continue
-			}
-
-			// Update resume token
-			if resumeToken := m.stream.ResumeToken(); resumeToken != nil {
-				m.mu.Lock()
-				m.resumeToken = resumeToken
-				m.mu.Unlock()
-			}
-
-			// Call event handler if set
-			if m.eventHandler != nil {
-				if err := m.eventHandler(changeEvent); err != nil {
-					// Log error but continue processing
-					continue
-				}
-			}
-		}
-	}
-}
-
-// Stop stops the replication source.
+// Stop stops the change stream and saves its final position.
 func (m *MongoDBReplicationSource) Stop() error {
-	if !m.IsActive() {
+	if !atomic.CompareAndSwapInt32(&m.active, 1, 0) {
 		return nil
 	}
+	m.cancel()
+	m.wg.Wait()
 
-	atomic.StoreInt32(&m.active, 0)
-	close(m.stopChan)
-
-	if m.stream != nil {
-		return m.stream.Close(context.Background())
-	}
-
+	m.checkpoint(context.Background(), true)
 	return nil
 }
 
-// Close closes the replication source.
+// Close stops the change stream and releases the replication source.
 func (m *MongoDBReplicationSource) Close() error {
-	return m.Stop()
+	err := m.Stop()
+	m.conn.replicationSources.Delete(m.id)
+	return err
 }
 
-// GetPosition returns the current replication position (resume token).
+// GetPosition returns the current resume token as extended JSON.
 func (m *MongoDBReplicationSource) GetPosition() (string, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	if m.resumeToken == nil {
-		return "", nil
+		return "", fmt.Errorf("no resume token available")
 	}
-
-	// Convert BSON resume token to string
-	return m.resumeToken.String(), nil
+	return encodeResumeToken(m.resumeToken)
 }
 
-// SetPosition sets the starting replication position for resume.
+// SetPosition sets the resume token the change stream resumes after, given as
+// extended JSON (e.g. {"_data":"8263..."}) or as the bare _data string.
 func (m *MongoDBReplicationSource) SetPosition(position string) error {
 	if position == "" {
 		return nil
 	}
+	token, err := decodeResumeToken(position)
+	if err != nil {
+		return err
+	}
 
-	// Parse the resume token from string
-	// Note: In production, you'd need proper BSON parsing
-	// For now, we'll store it as raw BSON
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// This is a simplified implementation
-	// In production, you'd parse the position string back to bson.Raw
+	m.resumeToken = token
+	m.mu.Unlock()
 	return nil
 }
 
-// SaveCheckpoint persists the current replication position.
+// SaveCheckpoint persists the given replication position.
 func (m *MongoDBReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
-	if m.checkpointFn != nil {
-		return m.checkpointFn(ctx, position)
+	m.mu.RLock()
+	fn := m.checkpointFn
+	m.mu.RUnlock()
+
+	if fn == nil {
+		return nil
 	}
-	return nil
+	return fn(ctx, position)
 }
 
 // SetCheckpointFunc sets the callback function for persisting checkpoints.
@@ -385,7 +366,127 @@ func (m *MongoDBReplicationSource) SetCheckpointFunc(fn func(context.Context, st
 	m.checkpointFn = fn
 }
 
-// GetDB returns the underlying MongoDB database connection (for internal use).
-func (r *ReplicationOps) GetDB() *mongo.Database {
-	return r.conn.db
+// run streams changes until the source is stopped. A failed change stream is reopened
+// after the last resume token; a stream whose token is no longer in the oplog stops the
+// source, since resuming from the current time would lose changes.
+func (m *MongoDBReplicationSource) run(ctx context.Context) {
+	for {
+		err := m.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("change stream ended unexpectedly")
+		}
+		if isResumeTokenLost(err) {
+			m.setError(fmt.Errorf("change stream cannot be resumed, a new initial copy is required: %v", err))
+			atomic.StoreInt32(&m.active, 0)
+			return
+		}
+		m.setError(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(changeStreamRetryDelay):
+		}
+	}
+}
+
+// stream runs one change stream from the current resume token and forwards its events
+func (m *MongoDBReplicationSource) stream(ctx context.Context) error {
+	m.mu.RLock()
+	resumeToken := m.resumeToken
+	m.mu.RUnlock()
+
+	stream, err := m.conn.db.Watch(ctx, buildChangeStreamPipeline(m.collectionNames), changeStreamOptions(resumeToken))
+	if err != nil {
+		return fmt.Errorf("error opening change stream: %w", err)
+	}
+	defer stream.Close(context.Background())
+
+	lastCheckpoint := time.Now()
+	for {
+		if stream.TryNext(ctx) {
+			var event bson.M
+			if err := stream.Decode(&event); err != nil {
+				m.setError(fmt.Errorf("error decoding change event: %v", err))
+			} else {
+				m.handleEvent(event)
+			}
+		} else if err := stream.Err(); err != nil {
+			return err
+		} else if ctx.Err() != nil {
+			return nil
+		}
+
+		// The resume token also advances on empty batches, so idle streams keep a recent position
+		if token := stream.ResumeToken(); token != nil {
+			m.mu.Lock()
+			m.resumeToken = token
+			m.mu.Unlock()
+		}
+		if time.Since(lastCheckpoint) >= changeStreamCheckpointInterval {
+			m.checkpoint(ctx, false)
+			lastCheckpoint = time.Now()
+		}
+	}
+}
+
+// handleEvent forwards a change event to the event handler
+func (m *MongoDBReplicationSource) handleEvent(event bson.M) {
+	raw := convertBSONMToMap(event)
+	raw["database_id"] = m.databaseID
+
+	if ts, ok := event["clusterTime"].(bson.Timestamp); ok {
+		m.mu.Lock()
+		m.clusterTime = ts
+		m.mu.Unlock()
+	}
+
+	atomic.AddInt64(&m.eventCount, 1)
+	if m.eventHandler != nil {
+		m.eventHandler(raw)
+	}
+}
+
+// checkpoint saves the current resume token if it changed since the last checkpoint
+func (m *MongoDBReplicationSource) checkpoint(ctx context.Context, force bool) {
+	position, err := m.GetPosition()
+	if err != nil {
+		return
+	}
+
+	m.mu.RLock()
+	unchanged := position == m.lastSavedToken
+	m.mu.RUnlock()
+	if unchanged && !force {
+		return
+	}
+
+	if err := m.SaveCheckpoint(ctx, position); err != nil {
+		m.setError(fmt.Errorf("error saving checkpoint: %v", err))
+		return
+	}
+
+	m.mu.Lock()
+	m.lastSavedToken = position
+	m.mu.Unlock()
+}
+
+// lastClusterTime returns the cluster time of the last event, or the zero time
+func (m *MongoDBReplicationSource) lastClusterTime() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.clusterTime.T == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(m.clusterTime.T), 0).UTC()
+}
+
+func (m *MongoDBReplicationSource) setError(err error) {
+	m.mu.Lock()
+	m.lastError = err.Error()
+	m.mu.Unlock()
 }
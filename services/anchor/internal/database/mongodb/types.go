@@ -26,20 +26,6 @@ func inferFieldType(value interface{}) string {
 	}
 }
 
-// MongoDBReplicationSourceDetails contains information about a MongoDB change stream
-type MongoDBReplicationSourceDetails struct {
-	CollectionName string `json:"collection_name"`
-	DatabaseID     string `json:"database_id"`
-	ResumeToken    string `json:"resume_token,omitempty"`
-}
-
-// MongoDBReplicationChange represents a change in MongoDB
-type MongoDBReplicationChange struct {
-	Operation string
-	Data      map[string]interface{}
-	OldData   map[string]interface{}
-}
-
 // MongoDBSchemaField represents a field in a MongoDB schema
 type MongoDBSchemaField struct {
 	Type       string                        `json:"type"`
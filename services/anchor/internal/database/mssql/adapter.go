@@ -3,6 +3,7 @@ package mssql
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"sync/atomic"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
@@ -45,6 +46,7 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -57,6 +59,13 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		OwnerID:               config.OwnerID,
 	}
 
+	if config.IAMAuth != nil {
+		legacyConfig.AuthToken = config.AuthToken
+	}
+	if config.KerberosAuth != nil {
+		legacyConfig.KerberosAuth = kerberosAuth(*config.KerberosAuth)
+	}
+
 	client, err := Connect(legacyConfig)
 	if err != nil {
 		return nil, adapter.NewConnectionError(dbcapabilities.SQLServer, config.Host, config.Port, err)
@@ -92,6 +101,7 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -106,6 +116,13 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Version:               config.Version,
 	}
 
+	if config.IAMAuth != nil {
+		legacyConfig.AuthToken = config.AuthToken
+	}
+	if config.KerberosAuth != nil {
+		legacyConfig.KerberosAuth = kerberosAuth(*config.KerberosAuth)
+	}
+
 	client, err := ConnectInstance(legacyConfig)
 	if err != nil {
 		return nil, adapter.NewConnectionError(dbcapabilities.SQLServer, config.Host, config.Port, err)
@@ -125,6 +142,19 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 	}, nil
 }
 
+// kerberosAuth converts the Kerberos authentication of a configuration to the one of the
+// connection string
+func kerberosAuth(auth adapter.KerberosAuth) *dbclient.KerberosAuthConfig {
+	return &dbclient.KerberosAuthConfig{
+		Principal: auth.Principal,
+		Realm:     auth.Realm,
+		Keytab:    auth.Keytab,
+		CCache:    auth.CCache,
+		Krb5Conf:  auth.Krb5Conf,
+		SPN:       auth.SPN,
+	}
+}
+
 // Connection implements adapter.Connection for MS-SQL.
 type Connection struct {
 	id        string
@@ -132,6 +162,9 @@ type Connection struct {
 	config    adapter.ConnectionConfig
 	adapter   *Adapter
 	connected int32
+
+	// replicationSources holds the CDC sources opened on this connection, by source ID
+	replicationSources sync.Map
 }
 
 func (c *Connection) ID() string                                   { return c.id }
@@ -8,8 +8,9 @@ import (
 	"strings"
 	"time"
 
-	_ "github.com/microsoft/go-mssqldb"
+	mssql "github.com/microsoft/go-mssqldb"
 
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/encryption"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 )
@@ -29,16 +30,21 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 		decryptedPassword = dp
 	}
 
+	server, err := serverAddress(config.Host, config.Port, config.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build base connection string
-	fmt.Fprintf(&connString, "server=%s;port=%d;database=%s;user id=%s;password=%s",
-		config.Host,
-		config.Port,
+	fmt.Fprintf(&connString, "%s;database=%s;%s",
+		server,
 		config.DatabaseName,
-		config.Username,
-		decryptedPassword)
+		login(config.Username, decryptedPassword, config.KerberosAuth))
 
-	// Add SSL configuration
-	if config.SSL {
+	// Add SSL configuration; named pipes are local, SSL settings do not apply to them
+	if config.SocketPath != "" {
+		connString.WriteString(";encrypt=disable")
+	} else if config.SSL {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			connString.WriteString(";encrypt=true;trustservercertificate=true")
 		} else {
@@ -49,7 +55,7 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 	}
 
 	// Create connection
-	db, err := sql.Open("sqlserver", connString.String())
+	db, err := openDB(connString.String(), config.AuthToken)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to database: %v", err)
 	}
@@ -84,16 +90,21 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 		decryptedPassword = dp
 	}
 
+	server, err := serverAddress(config.Host, config.Port, config.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build base connection string
-	fmt.Fprintf(&connString, "server=%s;port=%d;database=%s;user id=%s;password=%s",
-		config.Host,
-		config.Port,
+	fmt.Fprintf(&connString, "%s;database=%s;%s",
+		server,
 		config.DatabaseName,
-		config.Username,
-		decryptedPassword)
+		login(config.Username, decryptedPassword, config.KerberosAuth))
 
-	// Add SSL configuration
-	if config.SSL {
+	// Add SSL configuration; named pipes are local, SSL settings do not apply to them
+	if config.SocketPath != "" {
+		connString.WriteString(";encrypt=disable")
+	} else if config.SSL {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			connString.WriteString(";encrypt=true;trustservercertificate=true")
 		} else {
@@ -104,7 +115,7 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 	}
 
 	// Create connection
-	db, err := sql.Open("sqlserver", connString.String())
+	db, err := openDB(connString.String(), config.AuthToken)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to database: %v", err)
 	}
@@ -124,6 +135,67 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 	}, nil
 }
 
+// serverAddress returns the connection string keys of a server: the host and name of its named
+// pipe when one is set, else its host and port
+func serverAddress(host string, port int, socketPath string) (string, error) {
+	if socketPath == "" {
+		return fmt.Sprintf("server=%s;port=%d", host, port), nil
+	}
+	pipeHost, pipe, err := adapter.SplitNamedPipe(socketPath)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("server=%s;protocol=np;pipe=%s", pipeHost, pipe), nil
+}
+
+// login returns the connection string keys authenticating the connection: the user and
+// password, or the keytab or credential cache of its Kerberos authentication. The driver reads
+// them for every connection it opens, so new connections of the pool use the tickets kinit or
+// k5start renewed in the credential cache, and log in again with the keytab.
+func login(username, password string, auth *dbclient.KerberosAuthConfig) string {
+	if auth == nil {
+		return fmt.Sprintf("user id=%s;password=%s", username, password)
+	}
+
+	var params strings.Builder
+	krb5Conf := auth.Krb5Conf
+	if krb5Conf == "" {
+		krb5Conf = adapter.DefaultKrb5Conf
+	}
+	fmt.Fprintf(&params, "authenticator=krb5;krb5-configfile=%s", krb5Conf)
+	if auth.Keytab != "" {
+		// The principal logs in with the keytab, the realm of krb5-configfile unless it names one
+		principal := auth.Principal
+		if principal == "" {
+			principal = username
+		}
+		fmt.Fprintf(&params, ";user id=%s;krb5-keytabfile=%s", principal, auth.Keytab)
+	} else {
+		// Without a user id the driver does not look for a default keytab
+		fmt.Fprintf(&params, ";krb5-credcachefile=%s", auth.CCache)
+	}
+	if auth.Realm != "" {
+		fmt.Fprintf(&params, ";krb5-realm=%s", auth.Realm)
+	}
+	fmt.Fprintf(&params, ";ServerSPN=%s", auth.SPN)
+	return params.String()
+}
+
+// openDB opens the database of the connection string, authenticating with the Microsoft Entra ID
+// tokens of authToken instead of the password when it is set
+func openDB(connString string, authToken dbclient.TokenFunc) (*sql.DB, error) {
+	if authToken == nil {
+		return sql.Open("sqlserver", connString)
+	}
+	connector, err := mssql.NewAccessTokenConnector(connString, func() (string, error) {
+		return authToken(context.Background())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
 // DiscoverDetails fetches the details of a Microsoft SQL Server database
 func DiscoverDetails(db interface{}) (map[string]interface{}, error) {
 	sqlDB, ok := db.(*sql.DB)
@@ -1,6 +1,7 @@
 package mssql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -120,6 +121,102 @@ func InsertData(db *sql.DB, tableName string, data []map[string]interface{}) (in
 	return totalRowsAffected, nil
 }
 
+// UpsertData merges rows into a table, updating the existing rows whose unique columns match
+func UpsertData(ctx context.Context, db *sql.DB, tableName string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if len(uniqueColumns) == 0 {
+		return 0, fmt.Errorf("upsert into %s requires at least one unique column", tableName)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	columns := make([]string, 0, len(data[0]))
+	for col := range data[0] {
+		columns = append(columns, col)
+	}
+
+	sourceColumns := make([]string, len(columns))
+	quotedColumns := make([]string, len(columns))
+	insertValues := make([]string, len(columns))
+	updateSet := make([]string, 0, len(columns))
+	for i, col := range columns {
+		quoted := QuoteIdentifier(col)
+		sourceColumns[i] = fmt.Sprintf("@p%d AS %s", i+1, quoted)
+		quotedColumns[i] = quoted
+		insertValues[i] = "source." + quoted
+		if !containsColumn(uniqueColumns, col) {
+			updateSet = append(updateSet, fmt.Sprintf("target.%s = source.%s", quoted, quoted))
+		}
+	}
+
+	matchConditions := make([]string, len(uniqueColumns))
+	for i, col := range uniqueColumns {
+		quoted := QuoteIdentifier(col)
+		matchConditions[i] = fmt.Sprintf("target.%s = source.%s", quoted, quoted)
+	}
+
+	// HOLDLOCK keeps concurrent merges of the same key from both inserting
+	query := fmt.Sprintf(
+		"MERGE INTO %s WITH (HOLDLOCK) AS target USING (SELECT %s) AS source ON %s",
+		tableName,
+		strings.Join(sourceColumns, ", "),
+		strings.Join(matchConditions, " AND "),
+	)
+	if len(updateSet) > 0 {
+		query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(updateSet, ", ")
+	}
+	query += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);",
+		strings.Join(quotedColumns, ", "),
+		strings.Join(insertValues, ", "),
+	)
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var totalRowsAffected int64
+	for _, row := range data {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+
+		result, err := stmt.ExecContext(ctx, values...)
+		if err != nil {
+			return 0, err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		totalRowsAffected += rowsAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return totalRowsAffected, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, col := range columns {
+		if col == column {
+			return true
+		}
+	}
+	return false
+}
+
 // WipeDatabase removes all data from the database
 func WipeDatabase(db *sql.DB) error {
 	// Get all user tables
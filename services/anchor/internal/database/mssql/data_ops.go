@@ -55,7 +55,11 @@ func (d *DataOps) Update(ctx context.Context, table string, data []map[string]in
 }
 
 func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
-	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.SQLServer, "upsert data", "not yet implemented")
+	count, err := UpsertData(ctx, d.conn.db, table, data, uniqueColumns)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLServer, "upsert_data", err)
+	}
+	return count, nil
 }
 
 func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
@@ -66,6 +70,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.SQLServer, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.SQLServer, "fetch stream", "not yet implemented")
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.db, query, args...)
 	if err != nil {
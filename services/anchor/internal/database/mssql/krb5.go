@@ -0,0 +1,4 @@
+package mssql
+
+// The driver authenticates with Kerberos (authenticator=krb5) once this package is imported
+import _ "github.com/microsoft/go-mssqldb/integratedauth/krb5"
@@ -0,0 +1,7 @@
+//go:build windows
+// +build windows
+
+package mssql
+
+// The driver connects through named pipes (protocol=np) once this package is imported
+import _ "github.com/microsoft/go-mssqldb/namedpipe"
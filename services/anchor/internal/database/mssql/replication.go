@@ -1,209 +1,143 @@
 package mssql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"strings"
-	"time"
-
-	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 )
 
-// CreateReplicationSource sets up a replication source
-func CreateReplicationSource(db *sql.DB, tableName string, databaseID string, eventHandler func(map[string]interface{})) (*MSSQLReplicationSourceDetails, error) {
-	// Generate unique names for publication
-	pubName := fmt.Sprintf("pub_%s_%s", databaseID, dbclient.GenerateUniqueID())
-	subName := fmt.Sprintf("sub_%s_%s", databaseID, dbclient.GenerateUniqueID())
-
-	// Check if SQL Server has replication enabled
-	var replicationEnabled int
-	err := db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM sys.databases 
-		WHERE name = 'distribution'
-	`).Scan(&replicationEnabled)
-	if err != nil {
-		return nil, fmt.Errorf("error checking replication status: %v", err)
-	}
-
-	if replicationEnabled == 0 {
-		return nil, fmt.Errorf("SQL Server replication is not configured on this instance")
-	}
-
-	// Split schema and table name
-	parts := strings.Split(tableName, ".")
-	var schema, table string
-	if len(parts) == 2 {
-		schema = parts[0]
-		table = parts[1]
-	} else {
-		schema = "dbo"
-		table = tableName
+// splitTableName splits a "schema.table" name, defaulting to the dbo schema
+func splitTableName(name string) (schema, table string) {
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
 	}
+	return "dbo", name
+}
 
-	// Create publication
-	_, err = db.Exec(fmt.Sprintf(`
-		EXEC sp_replicationdboption 
-			@dbname = DB_NAME(),
-			@optname = 'publish',
-			@value = 'true'
-		
-		EXEC sp_addpublication 
-			@publication = '%s',
-			@description = 'Replication for %s.%s',
-			@sync_method = 'native',
-			@allow_push = 'true',
-			@allow_pull = 'true',
-			@allow_anonymous = 'false',
-			@enabled_for_internet = 'false',
-			@independent_agent = 'true'
-		
-		EXEC sp_addarticle 
-			@publication = '%s',
-			@article = '%s',
-			@source_owner = '%s',
-			@source_table = '%s',
-			@type = 'logbased',
-			@description = 'Replication article for %s.%s',
-			@creation_script = NULL,
-			@pre_creation_cmd = 'drop',
-			@schema_option = 0x000000000803509F
-	`, pubName, schema, table, pubName, table, schema, table, schema, table))
+// isDatabaseCDCEnabled reports whether CDC is enabled on the current database
+func isDatabaseCDCEnabled(ctx context.Context, db *sql.DB) (bool, error) {
+	var enabled bool
+	err := db.QueryRowContext(ctx, `SELECT is_cdc_enabled FROM sys.databases WHERE name = DB_NAME()`).Scan(&enabled)
 	if err != nil {
-		return nil, fmt.Errorf("error creating publication: %v", err)
-	}
-
-	details := &MSSQLReplicationSourceDetails{
-		PublicationName: pubName,
-		TableName:       fmt.Sprintf("%s.%s", schema, table),
-		DatabaseID:      databaseID,
-		SubscriptionID:  subName,
+		return false, fmt.Errorf("error checking CDC status of database: %v", err)
 	}
-
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
-
-	return details, nil
+	return enabled, nil
 }
 
-// ReconnectToReplicationSource reconnects to an existing replication source
-func ReconnectToReplicationSource(db *sql.DB, details *MSSQLReplicationSourceDetails, eventHandler func(map[string]interface{})) error {
-	// Verify that the publication still exists
-	var exists int
-	err := db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM syspublications 
-		WHERE name = @p1
-	`, details.PublicationName).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("error checking publication: %v", err)
+// canEnableCDC reports whether the login may enable CDC: sys.sp_cdc_enable_db requires the
+// sysadmin server role and sys.sp_cdc_enable_table the db_owner database role
+func canEnableCDC(ctx context.Context, db *sql.DB, database bool) (bool, error) {
+	query := `SELECT ISNULL(IS_ROLEMEMBER('db_owner'), 0)`
+	if database {
+		query = `SELECT ISNULL(IS_SRVROLEMEMBER('sysadmin'), 0)`
 	}
 
-	if exists == 0 {
-		return fmt.Errorf("publication %s does not exist", details.PublicationName)
+	var member int
+	if err := db.QueryRowContext(ctx, query).Scan(&member); err != nil {
+		return false, fmt.Errorf("error checking CDC permissions: %v", err)
 	}
+	return member == 1, nil
+}
 
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
-
+// enableDatabaseCDC enables CDC on the current database
+func enableDatabaseCDC(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `EXEC sys.sp_cdc_enable_db`); err != nil {
+		return fmt.Errorf("error enabling CDC on database: %v", err)
+	}
 	return nil
 }
 
-func listenForReplicationEvents(db *sql.DB, details *MSSQLReplicationSourceDetails, eventHandler func(map[string]interface{})) {
-	// Create a change tracking query
-	// Note: This requires that change tracking is enabled on the database and table
-
-	// Enable change tracking if not already enabled
-	_, err := db.Exec(`
-		IF NOT EXISTS (SELECT 1 FROM sys.change_tracking_databases WHERE database_id = DB_ID())
-		BEGIN
-			ALTER DATABASE CURRENT SET CHANGE_TRACKING = ON (CHANGE_RETENTION = 2 DAYS, AUTO_CLEANUP = ON)
-		END
-	`)
-	if err != nil {
-		log.Printf("Error enabling change tracking on database: %v", err)
-		return
+// getCaptureInstance returns the newest CDC capture instance of a table, or "" when CDC is
+// not enabled on the table
+func getCaptureInstance(ctx context.Context, db *sql.DB, schema, table string) (string, error) {
+	var captureInstance string
+	err := db.QueryRowContext(ctx, `
+		SELECT TOP 1 ct.capture_instance
+		FROM cdc.change_tables ct
+		JOIN sys.tables t ON ct.source_object_id = t.object_id
+		JOIN sys.schemas s ON t.schema_id = s.schema_id
+		WHERE s.name = @p1 AND t.name = @p2
+		ORDER BY ct.create_date DESC
+	`, schema, table).Scan(&captureInstance)
+	if err == sql.ErrNoRows {
+		return "", nil
 	}
-
-	// Enable change tracking on the table if not already enabled
-	_, err = db.Exec(fmt.Sprintf(`
-		IF NOT EXISTS (SELECT 1 FROM sys.change_tracking_tables WHERE object_id = OBJECT_ID('%s'))
-		BEGIN
-			ALTER TABLE %s ENABLE CHANGE_TRACKING
-		END
-	`, details.TableName, details.TableName))
 	if err != nil {
-		log.Printf("Error enabling change tracking on table %s: %v", details.TableName, err)
-		return
+		return "", fmt.Errorf("error getting capture instance of %s.%s: %v", schema, table, err)
 	}
+	return captureInstance, nil
+}
 
-	// Get the current change tracking version
-	var currentVersion int64
-	err = db.QueryRow(`SELECT CHANGE_TRACKING_CURRENT_VERSION()`).Scan(&currentVersion)
+// enableTableCDC enables CDC on a table with a capture instance covering all columns
+func enableTableCDC(ctx context.Context, db *sql.DB, schema, table string) error {
+	_, err := db.ExecContext(ctx, `
+		EXEC sys.sp_cdc_enable_table
+			@source_schema = @p1,
+			@source_name = @p2,
+			@role_name = NULL,
+			@supports_net_changes = 0
+	`, schema, table)
 	if err != nil {
-		log.Printf("Error getting current change tracking version: %v", err)
-		return
+		return fmt.Errorf("error enabling CDC on %s.%s: %v", schema, table, err)
 	}
+	return nil
+}
 
-	for {
-		changes, err := getReplicationChanges(db, details.TableName, currentVersion)
-		if err != nil {
-			log.Printf("Error getting replication changes: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
+// enableTableCDCCommand returns the statements an administrator runs to enable CDC on a table
+func enableTableCDCCommand(schema, table string) string {
+	return fmt.Sprintf("EXEC sys.sp_cdc_enable_db; EXEC sys.sp_cdc_enable_table @source_schema = N'%s', @source_name = N'%s', @role_name = NULL",
+		strings.ReplaceAll(schema, "'", "''"), strings.ReplaceAll(table, "'", "''"))
+}
 
-		for _, change := range changes {
-			event := map[string]interface{}{
-				"table":     details.TableName,
-				"operation": change.Operation,
-				"data":      change.Data,
-				"old_data":  change.OldData,
-			}
-			eventHandler(event)
-		}
+// getMaxLSN returns the highest LSN in the CDC change tables, or nil when no change was captured yet
+func getMaxLSN(ctx context.Context, db *sql.DB) ([]byte, error) {
+	var lsn []byte
+	if err := db.QueryRowContext(ctx, `SELECT sys.fn_cdc_get_max_lsn()`).Scan(&lsn); err != nil {
+		return nil, fmt.Errorf("error getting max LSN: %v", err)
+	}
+	return lsn, nil
+}
 
-		// Update the current version
-		err = db.QueryRow(`SELECT CHANGE_TRACKING_CURRENT_VERSION()`).Scan(&currentVersion)
-		if err != nil {
-			log.Printf("Error getting current change tracking version: %v", err)
-		}
+// getMinLSN returns the lowest LSN still available for a capture instance
+func getMinLSN(ctx context.Context, db *sql.DB, captureInstance string) ([]byte, error) {
+	var lsn []byte
+	if err := db.QueryRowContext(ctx, `SELECT sys.fn_cdc_get_min_lsn(@p1)`, captureInstance).Scan(&lsn); err != nil {
+		return nil, fmt.Errorf("error getting min LSN of %s: %v", captureInstance, err)
+	}
+	return lsn, nil
+}
 
-		time.Sleep(1 * time.Second)
+// incrementLSN returns the LSN following lsn
+func incrementLSN(ctx context.Context, db *sql.DB, lsn []byte) ([]byte, error) {
+	var next []byte
+	if err := db.QueryRowContext(ctx, `SELECT sys.fn_cdc_increment_lsn(@p1)`, lsn).Scan(&next); err != nil {
+		return nil, fmt.Errorf("error incrementing LSN: %v", err)
 	}
+	return next, nil
 }
 
-func getReplicationChanges(db *sql.DB, tableName string, lastVersion int64) ([]MSSQLReplicationChange, error) {
-	// Query for changes since the last version
+// getChanges returns the changes of a capture instance between fromLSN and toLSN (inclusive)
+// in commit order. Updates are returned as their after image.
+func getChanges(ctx context.Context, db *sql.DB, captureInstance string, fromLSN, toLSN []byte) ([]map[string]interface{}, error) {
 	query := fmt.Sprintf(`
-		SELECT 
-			CT.SYS_CHANGE_OPERATION,
-			CT.SYS_CHANGE_VERSION,
-			CT.SYS_CHANGE_CREATION_VERSION,
-			CT.SYS_CHANGE_COLUMNS,
-			T.*
-		FROM CHANGETABLE(CHANGES %s, %d) AS CT
-		LEFT JOIN %s AS T
-		ON CT.SYS_CHANGE_OPERATION != 'D' AND CT.__$update_mask IS NOT NULL
-		ORDER BY CT.SYS_CHANGE_VERSION
-	`, tableName, lastVersion, tableName)
-
-	rows, err := db.Query(query)
+		SELECT *
+		FROM cdc.%s(@p1, @p2, N'all')
+		ORDER BY __$start_lsn, __$seqval
+	`, quoteMSSQLIdentifier("fn_cdc_get_all_changes_"+captureInstance))
+
+	rows, err := db.QueryContext(ctx, query, fromLSN, toLSN)
 	if err != nil {
-		return nil, fmt.Errorf("error querying for changes: %v", err)
+		return nil, fmt.Errorf("error querying changes of %s: %v", captureInstance, err)
 	}
 	defer rows.Close()
 
-	var changes []MSSQLReplicationChange
-
-	// Get column information
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, fmt.Errorf("error getting columns: %v", err)
 	}
 
-	// Process each row
+	var changes []map[string]interface{}
 	for rows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -212,28 +146,15 @@ func getReplicationChanges(db *sql.DB, tableName string, lastVersion int64) ([]M
 		}
 
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("error scanning row: %v", err)
-		}
-
-		// Extract operation type
-		var operation string
-		if values[0] != nil {
-			operation = values[0].(string)
-		}
-
-		// Create data map
-		data := make(map[string]interface{})
-		for i := 4; i < len(columns); i++ {
-			data[columns[i]] = values[i]
+			return nil, fmt.Errorf("error scanning change row: %v", err)
 		}
 
-		change := MSSQLReplicationChange{
-			Operation: operation,
-			Data:      data,
+		change := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			change[col] = values[i]
 		}
-
 		changes = append(changes, change)
 	}
 
-	return changes, nil
+	return changes, rows.Err()
 }
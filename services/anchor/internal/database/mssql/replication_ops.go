@@ -1,10 +1,12 @@
 package mssql
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,6 +15,17 @@ import (
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
 )
 
+const (
+	// cdcPollInterval is the wait between two reads of the CDC change tables
+	cdcPollInterval = time.Second
+
+	// cdcCheckpointInterval is how often the position of a replication source is persisted
+	cdcCheckpointInterval = 10 * time.Second
+
+	// cdcRetryDelay is the wait after a failed read of the CDC change tables
+	cdcRetryDelay = 5 * time.Second
+)
+
 // ReplicationOps implements adapter.ReplicationOperator for SQL Server.
 type ReplicationOps struct {
 	conn *Connection
@@ -28,17 +41,22 @@ func (r *ReplicationOps) GetSupportedMechanisms() []string {
 	return []string{"cdc", "change_tracking"}
 }
 
-// CheckPrerequisites checks if replication prerequisites are met.
+// CheckPrerequisites checks if replication prerequisites are met. A database without CDC is
+// accepted when the login may enable it, as Connect enables CDC on the replicated tables.
 func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
-	// Check if CDC is enabled on the database
-	var cdcEnabled int
-	query := `SELECT is_cdc_enabled FROM sys.databases WHERE name = DB_NAME()`
-	err := r.conn.db.QueryRowContext(ctx, query).Scan(&cdcEnabled)
+	cdcEnabled, err := isDatabaseCDCEnabled(ctx, r.conn.db)
 	if err != nil {
 		return adapter.WrapError(dbcapabilities.SQLServer, "check_cdc_enabled", err)
 	}
+	if cdcEnabled {
+		return nil
+	}
 
-	if cdcEnabled == 0 {
+	allowed, err := canEnableCDC(ctx, r.conn.db, true)
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.SQLServer, "check_cdc_permissions", err)
+	}
+	if !allowed {
 		return adapter.NewDatabaseError(
 			dbcapabilities.SQLServer,
 			"check_replication_prerequisites",
@@ -49,45 +67,131 @@ func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
 	return nil
 }
 
-// Connect creates a new replication connection using SQL Server CDC.
+// Connect creates a new replication connection using SQL Server CDC. CDC is enabled on the
+// database and on replicated tables without a capture instance when the login has the
+// required roles, unless the "auto_enable_cdc" option is false.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
-	// Create the replication source
+	if len(config.TableNames) == 0 {
+		return nil, adapter.NewConfigurationError(
+			dbcapabilities.SQLServer,
+			"table_names",
+			"at least one table is required for SQL Server CDC",
+		)
+	}
+
+	autoEnable := true
+	if v, ok := config.Options["auto_enable_cdc"].(bool); ok {
+		autoEnable = v
+	}
+
 	source := &MSSQLReplicationSource{
-		id:         config.ReplicationID,
-		databaseID: config.DatabaseID,
-		db:         r.conn.db,
-		config:     config,
-		active:     0,
-		stopChan:   make(chan struct{}),
-		lastLSN:    nil,
-	}
-
-	// Wrap the event handler to match the expected signature
-	if config.EventHandler != nil {
-		source.eventHandler = func(event map[string]interface{}) error {
-			config.EventHandler(event)
-			return nil
+		id:               config.ReplicationID,
+		databaseID:       config.DatabaseID,
+		conn:             r.conn,
+		tableNames:       append([]string(nil), config.TableNames...),
+		captureInstances: make(map[string]string),
+		newlyEnabled:     make(map[string]bool),
+		eventHandler:     config.EventHandler,
+	}
+
+	for _, tableName := range source.tableNames {
+		captureInstance, enabled, err := r.ensureCaptureInstance(ctx, tableName, autoEnable)
+		if err != nil {
+			return nil, err
 		}
+		source.captureInstances[tableName] = captureInstance
+		source.newlyEnabled[tableName] = enabled
 	}
 
-	// Set starting position if provided
+	// Set starting position if provided, otherwise start after the changes captured so far
 	if config.StartPosition != "" {
 		if err := source.SetPosition(config.StartPosition); err != nil {
 			return nil, adapter.WrapError(dbcapabilities.SQLServer, "set_start_position", err)
 		}
+		source.resumed = true
+	} else {
+		maxLSN, err := getMaxLSN(ctx, r.conn.db)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.SQLServer, "get_current_lsn", err)
+		}
+		source.lastLSN = maxLSN
 	}
 
+	r.conn.replicationSources.Store(source.id, source)
 	return source, nil
 }
 
+// ensureCaptureInstance returns the capture instance of a table, enabling CDC on the database
+// and the table when allowed. enabled reports whether CDC was enabled on the table by this call.
+func (r *ReplicationOps) ensureCaptureInstance(ctx context.Context, tableName string, autoEnable bool) (captureInstance string, enabled bool, err error) {
+	schema, table := splitTableName(tableName)
+
+	captureInstance, err = getCaptureInstance(ctx, r.conn.db, schema, table)
+	if err != nil {
+		return "", false, adapter.WrapError(dbcapabilities.SQLServer, "get_capture_instance", err)
+	}
+	if captureInstance != "" {
+		return captureInstance, false, nil
+	}
+
+	notEnabled := func(reason string) error {
+		return adapter.NewDatabaseError(
+			dbcapabilities.SQLServer,
+			"enable_cdc",
+			adapter.ErrConfigurationError,
+		).WithContext("table", tableName).
+			WithContext("error", reason+". Enable with: "+enableTableCDCCommand(schema, table))
+	}
+	if !autoEnable {
+		return "", false, notEnabled("CDC is not enabled on the table")
+	}
+
+	dbEnabled, err := isDatabaseCDCEnabled(ctx, r.conn.db)
+	if err != nil {
+		return "", false, adapter.WrapError(dbcapabilities.SQLServer, "check_cdc_enabled", err)
+	}
+	if !dbEnabled {
+		allowed, err := canEnableCDC(ctx, r.conn.db, true)
+		if err != nil {
+			return "", false, adapter.WrapError(dbcapabilities.SQLServer, "check_cdc_permissions", err)
+		}
+		if !allowed {
+			return "", false, notEnabled("CDC is not enabled on the database and enabling it requires the sysadmin role")
+		}
+		if err := enableDatabaseCDC(ctx, r.conn.db); err != nil {
+			return "", false, adapter.WrapError(dbcapabilities.SQLServer, "enable_database_cdc", err)
+		}
+	}
+
+	allowed, err := canEnableCDC(ctx, r.conn.db, false)
+	if err != nil {
+		return "", false, adapter.WrapError(dbcapabilities.SQLServer, "check_cdc_permissions", err)
+	}
+	if !allowed {
+		return "", false, notEnabled("CDC is not enabled on the table and enabling it requires the db_owner role")
+	}
+	if err := enableTableCDC(ctx, r.conn.db, schema, table); err != nil {
+		return "", false, adapter.WrapError(dbcapabilities.SQLServer, "enable_table_cdc", err)
+	}
+
+	captureInstance, err = getCaptureInstance(ctx, r.conn.db, schema, table)
+	if err != nil {
+		return "", false, adapter.WrapError(dbcapabilities.SQLServer, "get_capture_instance", err)
+	}
+	if captureInstance == "" {
+		return "", false, notEnabled("no capture instance was created for the table")
+	}
+	return captureInstance, true, nil
+}
+
 // GetStatus returns the replication status.
 func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{}, error) {
 	// Query CDC status
 	query := `
-		SELECT 
+		SELECT
 			is_cdc_enabled,
 			(SELECT COUNT(*) FROM cdc.change_tables) as cdc_table_count
-		FROM sys.databases 
+		FROM sys.databases
 		WHERE name = DB_NAME()
 	`
 
@@ -98,11 +202,18 @@ func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{},
 		return nil, adapter.WrapError(dbcapabilities.SQLServer, "get_replication_status", err)
 	}
 
+	sources := r.sources()
+	streams := make([]map[string]interface{}, 0, len(sources))
+	for _, s := range sources {
+		streams = append(streams, s.GetStatus())
+	}
+
 	return map[string]interface{}{
-		"database_id":     r.conn.id,
-		"mechanism":       "sql_server_cdc",
-		"cdc_enabled":     cdcEnabled == 1,
-		"cdc_table_count": tableCount,
+		"database_id":         r.conn.id,
+		"mechanism":           "sql_server_cdc",
+		"cdc_enabled":         cdcEnabled == 1,
+		"cdc_table_count":     tableCount,
+		"replication_sources": streams,
 	}, nil
 }
 
@@ -115,10 +226,22 @@ func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, er
 		return nil, adapter.WrapError(dbcapabilities.SQLServer, "get_current_lsn", err)
 	}
 
+	sources := make([]map[string]interface{}, 0)
+	for _, s := range r.sources() {
+		position, _ := s.GetPosition()
+		sources = append(sources, map[string]interface{}{
+			"source_id":  s.id,
+			"last_lsn":   position,
+			"caught_up":  position == hex.EncodeToString(currentLSN),
+			"last_error": s.getError(),
+		})
+	}
+
 	return map[string]interface{}{
-		"database_id": r.conn.id,
-		"current_lsn": hex.EncodeToString(currentLSN),
-		"mechanism":   "sql_server_cdc",
+		"database_id":         r.conn.id,
+		"current_lsn":         hex.EncodeToString(currentLSN),
+		"mechanism":           "sql_server_cdc",
+		"replication_sources": sources,
 	}, nil
 }
 
@@ -143,7 +266,7 @@ func (r *ReplicationOps) DropSlot(ctx context.Context, slotName string) error {
 // ListPublications lists all publications (CDC-enabled tables).
 func (r *ReplicationOps) ListPublications(ctx context.Context) ([]map[string]interface{}, error) {
 	query := `
-		SELECT 
+		SELECT
 			OBJECT_NAME(source_object_id) as table_name,
 			capture_instance,
 			start_lsn
@@ -185,18 +308,38 @@ func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName st
 	return nil
 }
 
+// sources returns the replication sources opened on the connection, ordered by source ID
+func (r *ReplicationOps) sources() []*MSSQLReplicationSource {
+	var sources []*MSSQLReplicationSource
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		sources = append(sources, value.(*MSSQLReplicationSource))
+		return true
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].id < sources[j].id })
+	return sources
+}
+
 // MSSQLReplicationSource implements adapter.ReplicationSource for SQL Server CDC.
+// The position is the last LSN whose changes were passed on for all tables; CDC LSNs
+// are database-wide, so a single position covers every capture instance.
 type MSSQLReplicationSource struct {
-	id           string
-	databaseID   string
-	db           *sql.DB
-	config       adapter.ReplicationConfig
-	active       int32
-	stopChan     chan struct{}
-	lastLSN      []byte
-	mu           sync.RWMutex
-	eventHandler func(map[string]interface{}) error
-	checkpointFn func(context.Context, string) error
+	id               string
+	databaseID       string
+	conn             *Connection
+	tableNames       []string
+	captureInstances map[string]string // Table name -> capture instance
+	newlyEnabled     map[string]bool   // Tables on which Connect enabled CDC
+	resumed          bool              // Started from a saved position
+	active           int32
+	cancel           context.CancelFunc
+	wg               sync.WaitGroup
+	mu               sync.RWMutex
+	lastLSN          []byte
+	eventCount       int64
+	lastError        string
+	lastSavedLSN     string
+	eventHandler     func(map[string]interface{})
+	checkpointFn     func(context.Context, string) error
 }
 
 // GetSourceID returns the replication source ID.
@@ -215,10 +358,13 @@ func (m *MSSQLReplicationSource) GetStatus() map[string]interface{} {
 	defer m.mu.RUnlock()
 
 	status := map[string]interface{}{
-		"source_id":   m.id,
-		"database_id": m.databaseID,
-		"active":      m.IsActive(),
-		"mechanism":   "sql_server_cdc",
+		"source_id":         m.id,
+		"database_id":       m.databaseID,
+		"active":            m.IsActive(),
+		"mechanism":         "sql_server_cdc",
+		"capture_instances": m.captureInstances,
+		"event_count":       atomic.LoadInt64(&m.eventCount),
+		"last_error":        m.lastError,
 	}
 
 	if m.lastLSN != nil {
@@ -246,9 +392,9 @@ func (m *MSSQLReplicationSource) IsActive() bool {
 	return atomic.LoadInt32(&m.active) == 1
 }
 
-// Start starts the replication source.
+// Start starts polling the CDC change tables.
 func (m *MSSQLReplicationSource) Start() error {
-	if m.IsActive() {
+	if !atomic.CompareAndSwapInt32(&m.active, 0, 1) {
 		return adapter.NewDatabaseError(
 			dbcapabilities.SQLServer,
 			"start_replication",
@@ -256,125 +402,34 @@ func (m *MSSQLReplicationSource) Start() error {
 		).WithContext("error", "replication source is already active")
 	}
 
-	atomic.StoreInt32(&m.active, 1)
-
-	// Start polling for changes
-	for _, tableName := range m.config.TableNames {
-		go m.pollTableChanges(tableName)
-	}
-
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+		m.run(ctx)
+	}()
 	return nil
 }
 
-// pollTableChanges polls CDC changes for a specific table.
-func (m *MSSQLReplicationSource) pollTableChanges(tableName string) {
-	ctx := context.Background()
-
-	for m.IsActive() {
-		select {
-		case <-m.stopChan:
-			return
-		default:
-			// Get the capture instance name for this table
-			var captureInstance string
-			query := fmt.Sprintf("SELECT capture_instance FROM cdc.change_tables WHERE OBJECT_NAME(source_object_id) = '%s'", tableName)
-			err := m.db.QueryRowContext(ctx, query).Scan(&captureInstance)
-			if err != nil {
-				time.Sleep(1 * time.Second)
-				continue
-			}
-
-			// Get current LSN range
-			var minLSN, maxLSN []byte
-			if m.lastLSN == nil {
-				// Get starting LSN
-				err = m.db.QueryRowContext(ctx, "SELECT sys.fn_cdc_get_min_lsn(@capture_instance)", sql.Named("capture_instance", captureInstance)).Scan(&minLSN)
-			} else {
-				// Use last processed LSN
-				minLSN = m.lastLSN
-			}
-
-			err = m.db.QueryRowContext(ctx, "SELECT sys.fn_cdc_get_max_lsn()").Scan(&maxLSN)
-			if err != nil {
-				time.Sleep(1 * time.Second)
-				continue
-			}
-
-			// Query for changes
-			cdcQuery := fmt.Sprintf(`
-				SELECT 
-					__$operation,
-					__$start_lsn,
-					*
-				FROM cdc.fn_cdc_get_all_changes_%s(@from_lsn, @to_lsn, 'all')
-			`, captureInstance)
-
-			rows, err := m.db.QueryContext(ctx, cdcQuery,
-				sql.Named("from_lsn", minLSN),
-				sql.Named("to_lsn", maxLSN))
-			if err != nil {
-				time.Sleep(1 * time.Second)
-				continue
-			}
-
-			// Process rows
-			columns, _ := rows.Columns()
-			for rows.Next() {
-				// Create slice for scanning
-				values := make([]interface{}, len(columns))
-				valuePtrs := make([]interface{}, len(columns))
-				for i := range values {
-					valuePtrs[i] = &values[i]
-				}
-
-				if err := rows.Scan(valuePtrs...); err != nil {
-					continue
-				}
-
-				// Build event map
-				event := make(map[string]interface{})
-				for i, col := range columns {
-					event[col] = values[i]
-				}
-				event["table_name"] = tableName
-
-				// Call event handler
-				if m.eventHandler != nil {
-					if err := m.eventHandler(event); err != nil {
-						continue
-					}
-				}
-
-				// Update last LSN
-				if lsn, ok := values[1].([]byte); ok {
-					m.mu.Lock()
-					m.lastLSN = lsn
-					m.mu.Unlock()
-				}
-			}
-			rows.Close()
-
-			// Poll interval
-			time.Sleep(1 * time.Second)
-		}
-	}
-}
-
-// Stop stops the replication source.
+// Stop stops the replication source and saves its final position.
 func (m *MSSQLReplicationSource) Stop() error {
-	if !m.IsActive() {
+	if !atomic.CompareAndSwapInt32(&m.active, 1, 0) {
 		return nil
 	}
+	m.cancel()
+	m.wg.Wait()
 
-	atomic.StoreInt32(&m.active, 0)
-	close(m.stopChan)
-
+	m.checkpoint(context.Background())
 	return nil
 }
 
-// Close closes the replication source.
+// Close stops the replication source and releases it.
 func (m *MSSQLReplicationSource) Close() error {
-	return m.Stop()
+	err := m.Stop()
+	m.conn.replicationSources.Delete(m.id)
+	return err
 }
 
 // GetPosition returns the current replication position (LSN).
@@ -410,10 +465,14 @@ func (m *MSSQLReplicationSource) SetPosition(position string) error {
 
 // SaveCheckpoint persists the current replication position.
 func (m *MSSQLReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
-	if m.checkpointFn != nil {
-		return m.checkpointFn(ctx, position)
+	m.mu.RLock()
+	fn := m.checkpointFn
+	m.mu.RUnlock()
+
+	if fn == nil {
+		return nil
 	}
-	return nil
+	return fn(ctx, position)
 }
 
 // SetCheckpointFunc sets the callback function for persisting checkpoints.
@@ -423,6 +482,134 @@ func (m *MSSQLReplicationSource) SetCheckpointFunc(fn func(context.Context, stri
 	m.checkpointFn = fn
 }
 
+// run polls the change tables until the source is stopped
+func (m *MSSQLReplicationSource) run(ctx context.Context) {
+	lastCheckpoint := time.Now()
+	for {
+		wait := cdcPollInterval
+		if err := m.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			m.setError(err)
+			wait = cdcRetryDelay
+		}
+
+		if time.Since(lastCheckpoint) >= cdcCheckpointInterval {
+			m.checkpoint(ctx)
+			lastCheckpoint = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// poll passes on the changes committed after the current position, table by table, and
+// advances the position once the changes of all tables were passed on
+func (m *MSSQLReplicationSource) poll(ctx context.Context) error {
+	db := m.conn.db
+
+	toLSN, err := getMaxLSN(ctx, db)
+	if err != nil {
+		return err
+	}
+	if toLSN == nil {
+		// Nothing was captured yet
+		return nil
+	}
+
+	m.mu.RLock()
+	lastLSN := m.lastLSN
+	m.mu.RUnlock()
+
+	var fromLSN []byte
+	if lastLSN != nil {
+		if bytes.Compare(lastLSN, toLSN) >= 0 {
+			return nil
+		}
+		if fromLSN, err = incrementLSN(ctx, db, lastLSN); err != nil {
+			return err
+		}
+	}
+
+	for _, tableName := range m.tableNames {
+		captureInstance := m.captureInstances[tableName]
+		minLSN, err := getMinLSN(ctx, db, captureInstance)
+		if err != nil {
+			return err
+		}
+
+		// Changes before the minimum LSN are not available: either the capture instance was
+		// created later, or the CDC cleanup job removed them before they were replicated
+		tableFrom := fromLSN
+		if tableFrom == nil || bytes.Compare(tableFrom, minLSN) < 0 {
+			if tableFrom != nil && m.resumed && !m.newlyEnabled[tableName] {
+				m.setError(fmt.Errorf("changes of %s before LSN %s are no longer available in the CDC change table", tableName, hex.EncodeToString(minLSN)))
+			}
+			tableFrom = minLSN
+		}
+		if bytes.Compare(tableFrom, toLSN) > 0 {
+			continue
+		}
+
+		changes, err := getChanges(ctx, db, captureInstance, tableFrom, toLSN)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			change["table_name"] = tableName
+			change["database_id"] = m.databaseID
+
+			atomic.AddInt64(&m.eventCount, 1)
+			if m.eventHandler != nil {
+				m.eventHandler(change)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.lastLSN = toLSN
+	m.mu.Unlock()
+	return nil
+}
+
+// checkpoint saves the current position if it changed since the last checkpoint
+func (m *MSSQLReplicationSource) checkpoint(ctx context.Context) {
+	position, _ := m.GetPosition()
+
+	m.mu.RLock()
+	unchanged := position == "" || position == m.lastSavedLSN
+	m.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	if err := m.SaveCheckpoint(ctx, position); err != nil {
+		m.setError(fmt.Errorf("error saving checkpoint: %v", err))
+		return
+	}
+
+	m.mu.Lock()
+	m.lastSavedLSN = position
+	m.mu.Unlock()
+}
+
+func (m *MSSQLReplicationSource) setError(err error) {
+	m.mu.Lock()
+	m.lastError = err.Error()
+	m.mu.Unlock()
+}
+
+func (m *MSSQLReplicationSource) getError() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastError
+}
+
 // GetDB returns the underlying database connection (for internal use).
 func (r *ReplicationOps) GetDB() *sql.DB {
 	return r.conn.db
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"sync/atomic"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
@@ -46,6 +47,7 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -53,11 +55,20 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		SSLCert:               adapter.GetString(config.SSLCert),
 		SSLKey:                adapter.GetString(config.SSLKey),
 		SSLRootCert:           adapter.GetString(config.SSLRootCert),
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
 	}
 
+	if config.IAMAuth != nil {
+		legacyConfig.AuthToken = config.AuthToken
+	}
+
 	// Use existing Connect function
 	client, err := Connect(legacyConfig)
 	if err != nil {
@@ -107,6 +118,7 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Username:              config.Username,
 		Password:              config.Password,
 		DatabaseName:          config.DatabaseName,
+		SocketPath:            config.SocketPath,
 		Enabled:               config.Enabled,
 		SSL:                   config.SSL,
 		SSLMode:               config.SSLMode,
@@ -114,6 +126,11 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		SSLCert:               adapter.GetString(config.SSLCert),
 		SSLKey:                adapter.GetString(config.SSLKey),
 		SSLRootCert:           adapter.GetString(config.SSLRootCert),
+		TLSClientCert:         config.TLSClientCert,
+		TLSClientKey:          config.TLSClientKey,
+		TLSCABundle:           config.TLSCABundle,
+		TLSVerifyMode:         config.TLSVerifyMode,
+		TLSServerName:         config.TLSServerName,
 		Role:                  config.Role,
 		ConnectedToNodeID:     config.ConnectedToNodeID,
 		OwnerID:               config.OwnerID,
@@ -121,6 +138,10 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		Version:               config.Version,
 	}
 
+	if config.IAMAuth != nil {
+		legacyConfig.AuthToken = config.AuthToken
+	}
+
 	// Use existing ConnectInstance function
 	client, err := ConnectInstance(legacyConfig)
 	if err != nil {
@@ -160,6 +181,9 @@ type Connection struct {
 	config    adapter.ConnectionConfig
 	adapter   *Adapter
 	connected int32
+
+	// replicationSources holds the binlog sources opened on this connection, by source ID
+	replicationSources sync.Map
 }
 
 // ID returns the connection identifier.
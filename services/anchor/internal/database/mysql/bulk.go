@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// MySQL error numbers returned when LOAD DATA LOCAL is disabled on the server or client
+const (
+	errNotAllowedCommand = 1148
+	errLoadLocalDisabled = 3948
+)
+
+// bulkReaderID makes the names of registered LOAD DATA readers unique
+var bulkReaderID uint64
+
+// BulkInsertData loads data into a specified table with LOAD DATA LOCAL INFILE, streaming
+// the rows as CSV through a registered reader. If the server does not allow local
+// loads, the rows are inserted with InsertData instead.
+func BulkInsertData(ctx context.Context, db *sql.DB, tableName string, columns []string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	for _, row := range data {
+		if err := writeBulkRow(&buf, columns, row); err != nil {
+			return 0, err
+		}
+	}
+
+	name := fmt.Sprintf("redb_bulk_%d", atomic.AddUint64(&bulkReaderID, 1))
+	mysql.RegisterReaderHandler(name, func() io.Reader { return &buf })
+	defer mysql.DeregisterReaderHandler(name)
+
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = QuoteIdentifier(col)
+	}
+
+	query := fmt.Sprintf(
+		"LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET utf8mb4 "+
+			"FIELDS TERMINATED BY ',' ENCLOSED BY '\"' ESCAPED BY '\\\\' LINES TERMINATED BY '\\n' (%s)",
+		name, QuoteIdentifier(tableName), strings.Join(quotedColumns, ", "))
+
+	result, err := db.ExecContext(ctx, query)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && (mysqlErr.Number == errNotAllowedCommand || mysqlErr.Number == errLoadLocalDisabled) {
+			return InsertData(db, tableName, data, nil)
+		}
+		return 0, fmt.Errorf("error loading data into table %s: %w", tableName, err)
+	}
+
+	return result.RowsAffected()
+}
+
+// writeBulkRow writes a row as a line of the CSV format read by BulkInsertData
+func writeBulkRow(buf *bytes.Buffer, columns []string, row map[string]interface{}) error {
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		var text string
+		switch v := row[col].(type) {
+		case nil:
+			buf.WriteString(`\N`)
+			continue
+		case bool:
+			text = "0"
+			if v {
+				text = "1"
+			}
+		case time.Time:
+			text = v.UTC().Format("2006-01-02 15:04:05.999999")
+		default:
+			var err error
+			if text, err = adapter.FormatBulkValue(v); err != nil {
+				return fmt.Errorf("error formatting column %s: %w", col, err)
+			}
+		}
+
+		buf.WriteByte('"')
+		for j := 0; j < len(text); j++ {
+			switch c := text[j]; c {
+			case '\\', '"':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case '\n':
+				buf.WriteString(`\n`)
+			case '\r':
+				buf.WriteString(`\r`)
+			case 0:
+				buf.WriteString(`\0`)
+			default:
+				buf.WriteByte(c)
+			}
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('\n')
+	return nil
+}
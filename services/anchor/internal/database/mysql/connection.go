@@ -2,12 +2,13 @@ package mysql
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/redbco/redb-open/pkg/encryption"
 	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
@@ -26,8 +27,9 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 		decryptedPassword = dp
 	}
 
+	// Sockets are local, SSL settings do not apply to them
 	var sslMode string
-	if config.SSL {
+	if config.SSL && config.SocketPath == "" {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			sslMode = "skip-verify"
 		} else {
@@ -38,19 +40,27 @@ func Connect(config dbclient.DatabaseConfig) (*dbclient.DatabaseClient, error) {
 	}
 
 	// Build the connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?tls=%s",
-		config.Username, decryptedPassword, config.Host, config.Port, config.DatabaseName, sslMode)
+	dsn := fmt.Sprintf("%s:%s@%s/%s?tls=%s",
+		config.Username, decryptedPassword, address(config.Host, config.Port, config.SocketPath), config.DatabaseName, sslMode)
 
 	// Add SSL configuration if enabled
-	if config.SSL && config.SSLCert != "" && config.SSLKey != "" {
+	if sslMode != "false" && config.SSLCert != "" && config.SSLKey != "" {
 		dsn = fmt.Sprintf("%s&sslcert=%s&sslkey=%s", dsn, config.SSLCert, config.SSLKey)
 		if config.SSLRootCert != "" {
 			dsn = fmt.Sprintf("%s&sslrootcert=%s", dsn, config.SSLRootCert)
 		}
 	}
 
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	if config.SocketPath != "" {
+		tlsConfig = nil
+	}
+
 	// Open the database connection
-	db, err := sql.Open("mysql", dsn)
+	db, err := openDB(dsn, tlsConfig, config.AuthToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open MySQL connection: %w", err)
 	}
@@ -88,8 +98,9 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 		decryptedPassword = dp
 	}
 
+	// Sockets are local, SSL settings do not apply to them
 	var sslMode string
-	if config.SSL {
+	if config.SSL && config.SocketPath == "" {
 		if config.SSLRejectUnauthorized != nil && !*config.SSLRejectUnauthorized {
 			sslMode = "skip-verify"
 		} else {
@@ -100,19 +111,27 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 	}
 
 	// Build the connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?tls=%s",
-		config.Username, decryptedPassword, config.Host, config.Port, config.DatabaseName, sslMode)
+	dsn := fmt.Sprintf("%s:%s@%s/%s?tls=%s",
+		config.Username, decryptedPassword, address(config.Host, config.Port, config.SocketPath), config.DatabaseName, sslMode)
 
 	// Add SSL configuration if enabled
-	if config.SSL && config.SSLCert != "" && config.SSLKey != "" {
+	if sslMode != "false" && config.SSLCert != "" && config.SSLKey != "" {
 		dsn = fmt.Sprintf("%s&sslcert=%s&sslkey=%s", dsn, config.SSLCert, config.SSLKey)
 		if config.SSLRootCert != "" {
 			dsn = fmt.Sprintf("%s&sslrootcert=%s", dsn, config.SSLRootCert)
 		}
 	}
 
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	if config.SocketPath != "" {
+		tlsConfig = nil
+	}
+
 	// Open the database connection
-	db, err := sql.Open("mysql", dsn)
+	db, err := openDB(dsn, tlsConfig, config.AuthToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open MySQL connection: %w", err)
 	}
@@ -137,6 +156,43 @@ func ConnectInstance(config dbclient.InstanceConfig) (*dbclient.InstanceClient,
 	}, nil
 }
 
+// openDB opens the database of the DSN, connecting with tlsConfig instead of the tls
+// parameter of the DSN when it is set, and with the tokens of authToken instead of the password
+// when it is set
+func openDB(dsn string, tlsConfig *tls.Config, authToken dbclient.TokenFunc) (*sql.DB, error) {
+	if tlsConfig == nil && authToken == nil {
+		return sql.Open("mysql", dsn)
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		cfg.TLS = tlsConfig
+	}
+	if authToken != nil {
+		// IAM tokens are sent with the cleartext authentication plugin, over TLS, and replace
+		// the password of every new connection
+		cfg.AllowCleartextPasswords = true
+		err := cfg.Apply(mysql.BeforeConnect(func(ctx context.Context, cfg *mysql.Config) error {
+			token, err := authToken(ctx)
+			if err != nil {
+				return err
+			}
+			cfg.Passwd = token
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
 // DiscoverDetails retrieves basic details about a MySQL database for metadata purposes
 func DiscoverDetails(db interface{}) (map[string]interface{}, error) {
 	sqlDB, ok := db.(*sql.DB)
@@ -217,6 +273,20 @@ func CollectDatabaseMetadata(ctx context.Context, db interface{}) (map[string]in
 	}
 	metadata["tables_count"] = tablesCount
 
+	// Get binlog state, used to pick and validate replication positions
+	if binlog, err := GetBinlogStatus(ctx, sqlDB); err == nil {
+		metadata["binlog_enabled"] = binlog.Enabled
+		metadata["binlog_format"] = binlog.Format
+		metadata["gtid_mode"] = binlog.GTIDMode
+		if binlog.Enabled {
+			metadata["binlog_file"] = binlog.File
+			metadata["binlog_position"] = binlog.Position
+		}
+		if binlog.GTIDMode {
+			metadata["gtid_executed"] = binlog.ExecutedGTIDSet
+		}
+	}
+
 	return metadata, nil
 }
 
@@ -380,6 +450,59 @@ func ExecuteQuery(db interface{}, query string, args ...interface{}) ([]interfac
 	return results, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction and returns its columns
+// and at most maxRows rows. truncated reports whether the query returned more rows.
+func ExecuteReadOnlyQuery(ctx context.Context, db *sql.DB, query string, maxRows int) (columns []string, results []map[string]interface{}, truncated bool, err error) {
+	// START TRANSACTION READ ONLY makes the server reject writes to tables, including those
+	// made by stored functions the query calls
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	// Nothing can be written, so the transaction is always rolled back
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to execute mysql query: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err = rows.Columns()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to get mysql column names: %w", err)
+	}
+
+	results = []map[string]interface{}{}
+	for rows.Next() {
+		if len(results) == maxRows {
+			truncated = true
+			break
+		}
+
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, false, fmt.Errorf("failed to scan mysql result: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, colName := range columns {
+			row[colName] = values[i]
+		}
+		results = append(results, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, fmt.Errorf("mysql rows iteration error: %w", err)
+	}
+
+	return columns, results, truncated, nil
+}
+
 // ExecuteCountQuery executes a count query and returns the result
 func ExecuteCountQuery(db interface{}, query string) (int64, error) {
 	sqlDB, ok := db.(*sql.DB)
@@ -554,3 +677,12 @@ func DropDatabase(ctx context.Context, db interface{}, databaseName string, opti
 
 	return nil
 }
+
+// address returns the DSN address of a server: its unix socket when one is set, else its host
+// and port.
+func address(host string, port int, socketPath string) string {
+	if socketPath != "" {
+		return fmt.Sprintf("unix(%s)", socketPath)
+	}
+	return fmt.Sprintf("tcp(%s:%d)", host, port)
+}
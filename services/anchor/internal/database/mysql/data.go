@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -117,12 +118,7 @@ func FetchData(db *sql.DB, tableName string, limit int, logger *logger.Logger) (
 	}
 
 	// Build and execute query
-	query := fmt.Sprintf("SELECT %s FROM %s",
-		strings.Join(columns, ", "),
-		QuoteIdentifier(tableName))
-	if limit > 0 {
-		query = fmt.Sprintf("%s LIMIT %d", query, limit)
-	}
+	query := buildFetchQuery(tableName, columns, limit)
 
 	if logger != nil {
 		logger.Debug("Executing query: %s", query)
@@ -641,6 +637,43 @@ func WipeDatabase(db *sql.DB, logger *logger.Logger) error {
 	return nil
 }
 
+// buildFetchQuery builds the query FetchData runs for the given columns
+func buildFetchQuery(tableName string, columns []string, limit int) string {
+	query := fmt.Sprintf("SELECT %s FROM %s",
+		strings.Join(columns, ", "),
+		QuoteIdentifier(tableName))
+	if limit > 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, limit)
+	}
+	return query
+}
+
+// ExplainFetch returns the execution plan of the query FetchData runs: the JSON plan
+// of EXPLAIN FORMAT=JSON, or with analyze the tree of EXPLAIN ANALYZE, which executes
+// the query and requires MySQL 8.0.18 or later.
+func ExplainFetch(ctx context.Context, db *sql.DB, tableName string, limit int, analyze bool) (string, string, error) {
+	if tableName == "" {
+		return "", "", fmt.Errorf("table name cannot be empty")
+	}
+
+	columns, err := getColumns(db, tableName)
+	if err != nil {
+		return "", "", err
+	}
+	query := buildFetchQuery(tableName, columns, limit)
+
+	explain := "EXPLAIN FORMAT=JSON "
+	if analyze {
+		explain = "EXPLAIN ANALYZE "
+	}
+
+	var plan string
+	if err := db.QueryRowContext(ctx, explain+query).Scan(&plan); err != nil {
+		return "", "", fmt.Errorf("error explaining query on table %s: %w", tableName, err)
+	}
+	return query, plan, nil
+}
+
 // getColumns retrieves column names for a table
 func getColumns(db *sql.DB, tableName string) ([]string, error) {
 	query := fmt.Sprintf(`
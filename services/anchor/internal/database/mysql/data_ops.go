@@ -21,6 +21,19 @@ func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[str
 	return data, nil
 }
 
+// ExplainFetch returns the execution plan of the query behind Fetch.
+func (d *DataOps) ExplainFetch(ctx context.Context, table string, limit int, analyze bool) (*adapter.QueryPlan, error) {
+	query, plan, err := ExplainFetch(ctx, d.conn.db, table, limit, analyze)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MySQL, "explain_fetch", err)
+	}
+	format := "json"
+	if analyze {
+		format = "text"
+	}
+	return &adapter.QueryPlan{Query: query, Format: format, Plan: plan, Analyzed: analyze}, nil
+}
+
 // FetchWithColumns retrieves specific columns from a table.
 func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
 	// MySQL FetchData doesn't support column filtering, so we fetch all and filter
@@ -56,6 +69,15 @@ func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]in
 	return rowsAffected, nil
 }
 
+// BulkInsert loads data into a table with LOAD DATA LOCAL INFILE.
+func (d *DataOps) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	rowsAffected, err := BulkInsertData(ctx, d.conn.db, table, adapter.BulkColumns(data), data)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.MySQL, "bulk_insert", err)
+	}
+	return rowsAffected, nil
+}
+
 // Update updates existing data in a table.
 func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
 	rowsAffected, err := UpdateData(d.conn.db, table, data, whereColumns, nil)
@@ -100,6 +122,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 // ExecuteQuery executes a raw SQL query.
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.db, query, args...)
@@ -109,6 +136,16 @@ func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interf
 	return result, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction, implementing
+// adapter.ReadOnlyQueryExecutor.
+func (d *DataOps) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*adapter.QueryResult, error) {
+	columns, rows, truncated, err := ExecuteReadOnlyQuery(ctx, d.conn.db, query, maxRows)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MySQL, "execute_read_only_query", err)
+	}
+	return &adapter.QueryResult{Columns: columns, Rows: rows, Truncated: truncated}, nil
+}
+
 // ExecuteCountQuery executes a count query.
 func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
 	count, err := ExecuteCountQuery(d.conn.db, query)
@@ -1,187 +1,470 @@
 package mysql
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
-	"errors"
 	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
 	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/redbco/redb-open/pkg/encryption"
+	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
+)
+
+const (
+	// binlogRetryDelay is the wait before a failed binlog stream is restarted
+	binlogRetryDelay = 5 * time.Second
+
+	// checkpointInterval is how often the position is persisted while streaming
+	checkpointInterval = 10 * time.Second
 )
 
-// CreateReplicationSource creates a replication source for a MySQL table
-func CreateReplicationSource(db *sql.DB, tableName string, databaseID string, eventHandler func(map[string]interface{})) (*MySQLReplicationSourceDetails, error) {
-	if tableName == "" {
-		return nil, fmt.Errorf("table name cannot be empty")
+// ConnectReplication prepares a binlog replication source for the configured tables.
+// The source starts from config.StartPosition when given, otherwise from the current
+// end of the binlog. In GTID mode the position is tracked as the executed GTID set,
+// which survives binlog purges and failovers to another server of the topology.
+func ConnectReplication(config dbclient.ReplicationConfig, db *sql.DB) (*dbclient.ReplicationClient, *MySQLReplicationSourceDetails, error) {
+	if config.DatabaseID == "" {
+		return nil, nil, fmt.Errorf("database ID is required")
+	}
+	if len(config.TableNames) == 0 {
+		return nil, nil, fmt.Errorf("at least one table name is required for replication")
+	}
+
+	// Decrypt the password (passwords are stored encrypted in the database)
+	var decryptedPassword string
+	if config.Password != "" {
+		dp, err := encryption.DecryptPassword(config.TenantID, config.Password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decrypting password: %w", err)
+		}
+		decryptedPassword = dp
 	}
 
-	// Check if the table exists
-	var tableExists int
-	err := db.QueryRow("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", tableName).Scan(&tableExists)
+	serverID, err := replicationServerID(config)
 	if err != nil {
-		return nil, fmt.Errorf("error checking if table exists: %w", err)
+		return nil, nil, err
 	}
-	if tableExists == 0 {
-		return nil, fmt.Errorf("table %s does not exist", tableName)
+
+	tlsConfig, err := replicationTLSConfig(config)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Get current binary log position
-	var binlogFile string
-	var binlogPosition uint32
-	err = db.QueryRow("SHOW MASTER STATUS").Scan(&binlogFile, &binlogPosition, nil, nil, nil)
+	ctx := context.Background()
+	binlog, err := GetBinlogStatus(ctx, db)
 	if err != nil {
-		return nil, fmt.Errorf("error getting binary log position: %w", err)
+		return nil, nil, err
+	}
+
+	tableSet := make(map[string]struct{}, len(config.TableNames))
+	for _, t := range config.TableNames {
+		tableSet[t] = struct{}{}
 	}
 
-	// Create replication details
 	details := &MySQLReplicationSourceDetails{
-		BinlogFile:     binlogFile,
-		BinlogPosition: binlogPosition,
-		TableName:      tableName,
-		DatabaseID:     databaseID,
+		DatabaseName: config.DatabaseName,
+		DatabaseID:   config.DatabaseID,
+		SlotName:     config.ReplicationID,
+		ServerID:     serverID,
+		TableNames:   tableSet,
+		EventHandler: config.EventHandler,
+		db:           db,
+		useGTID:      binlog.GTIDMode,
+		columns:      make(map[string][]string),
+		syncerConfig: replication.BinlogSyncerConfig{
+			ServerID:        serverID,
+			Flavor:          gomysql.MySQLFlavor,
+			Host:            config.Host,
+			Port:            uint16(config.Port),
+			User:            config.Username,
+			Password:        decryptedPassword,
+			TLSConfig:       tlsConfig,
+			HeartbeatPeriod: 30 * time.Second,
+			ReadTimeout:     90 * time.Second,
+		},
 	}
 
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
+	if config.StartPosition != "" {
+		if err := details.SetPosition(config.StartPosition); err != nil {
+			return nil, nil, fmt.Errorf("error setting start position: %w", err)
+		}
+		if details.useGTID && !binlog.GTIDMode {
+			return nil, nil, fmt.Errorf("start position is a GTID set but gtid_mode is not ON")
+		}
+	} else if logFile, ok := config.ReplicationOptions["log_file"].(string); ok && logFile != "" {
+		// Explicit binlog coordinates given through the log_file and log_position options
+		logPos := fmt.Sprintf("%v", config.ReplicationOptions["log_position"])
+		if err := details.SetPosition(logFile + ":" + logPos); err != nil {
+			return nil, nil, fmt.Errorf("error setting start position: %w", err)
+		}
+	} else {
+		// Start from the current end of the binlog
+		details.BinlogFile = binlog.File
+		details.BinlogPosition = binlog.Position
+		details.GTIDSet = binlog.ExecutedGTIDSet
+	}
 
-	return details, nil
+	client := &dbclient.ReplicationClient{
+		ReplicationID:     config.ReplicationID,
+		DatabaseID:        config.DatabaseID,
+		DatabaseType:      "mysql",
+		Config:            config,
+		Connection:        db,
+		ReplicationSource: details,
+		EventHandler:      config.EventHandler,
+		IsConnected:       1, // Mark as connected
+		Status:            "connected",
+		StatusMessage:     "Replication connection established",
+		CreatedAt:         time.Now(),
+		ConnectedAt:       &[]time.Time{time.Now()}[0],
+		LastActivity:      time.Now(),
+		TableNames:        tableSet,
+	}
+
+	return client, details, nil
 }
 
-// ReconnectToReplicationSource reconnects to an existing replication source
-func ReconnectToReplicationSource(db *sql.DB, details *MySQLReplicationSourceDetails, eventHandler func(map[string]interface{})) error {
-	if details == nil {
-		return fmt.Errorf("replication details cannot be nil")
-	}
+// BinlogStatus is the binlog state of a server
+type BinlogStatus struct {
+	Enabled         bool
+	Format          string
+	GTIDMode        bool
+	File            string
+	Position        uint32
+	ExecutedGTIDSet string
+}
+
+// GetBinlogStatus returns whether the binlog is enabled, its format, the GTID mode and
+// the current binlog position
+func GetBinlogStatus(ctx context.Context, db *sql.DB) (*BinlogStatus, error) {
+	status := &BinlogStatus{}
 
-	// Check if the table exists
-	var tableExists int
-	err := db.QueryRow("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", details.TableName).Scan(&tableExists)
+	var logBin, gtidMode string
+	err := db.QueryRowContext(ctx, "SELECT @@GLOBAL.log_bin, @@GLOBAL.binlog_format, @@GLOBAL.gtid_mode").
+		Scan(&logBin, &status.Format, &gtidMode)
 	if err != nil {
-		return fmt.Errorf("error checking if table exists: %w", err)
+		return nil, fmt.Errorf("error reading binlog settings: %w", err)
+	}
+	status.Enabled = logBin == "1" || strings.EqualFold(logBin, "ON")
+	status.GTIDMode = strings.EqualFold(gtidMode, "ON")
+
+	if !status.Enabled {
+		return status, nil
 	}
-	if tableExists == 0 {
-		return fmt.Errorf("table %s does not exist", details.TableName)
+
+	// MySQL 8.2 renamed SHOW MASTER STATUS and 8.4 removed the old statement
+	rows, err := db.QueryContext(ctx, "SHOW BINARY LOG STATUS")
+	if err != nil {
+		rows, err = db.QueryContext(ctx, "SHOW MASTER STATUS")
+		if err != nil {
+			return nil, fmt.Errorf("error getting binary log position: %w", err)
+		}
 	}
+	defer rows.Close()
 
-	// Start listening for replication events
-	go listenForReplicationEvents(db, details, eventHandler)
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error getting binary log position: %w", err)
+	}
+	if rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("error scanning binary log position: %w", err)
+		}
+		for i, col := range columns {
+			switch strings.ToLower(col) {
+			case "file":
+				status.File = values[i].String
+			case "position":
+				pos, _ := strconv.ParseUint(values[i].String, 10, 32)
+				status.Position = uint32(pos)
+			case "executed_gtid_set":
+				// Multi-source GTID sets are listed on several lines
+				status.ExecutedGTIDSet = strings.ReplaceAll(values[i].String, "\n", "")
+			}
+		}
+	}
 
-	return nil
+	return status, rows.Err()
 }
 
-// listenForReplicationEvents listens for replication events from a MySQL table
-func listenForReplicationEvents(db *sql.DB, details *MySQLReplicationSourceDetails, eventHandler func(map[string]interface{})) {
-	// In a real implementation, this would use a MySQL binlog client library
-	// For this example, we'll simulate by polling the table for changes
+// replicationServerID returns the server ID the binlog reader registers with. It must
+// differ from the IDs of the servers and other replicas of the topology; unless set
+// through the server_id option it is derived from the replication ID.
+func replicationServerID(config dbclient.ReplicationConfig) (uint32, error) {
+	if v, ok := config.ReplicationOptions["server_id"]; ok && v != nil {
+		id, err := strconv.ParseUint(fmt.Sprintf("%v", v), 10, 32)
+		if err != nil || id == 0 {
+			return 0, fmt.Errorf("invalid server_id %v", v)
+		}
+		return uint32(id), nil
+	}
 
-	// Keep track of the last time we checked
-	lastCheck := time.Now()
+	h := fnv.New32a()
+	h.Write([]byte(config.ReplicationID))
+	// Keep clear of the low IDs usually given to servers
+	return 1<<20 + h.Sum32()%(1<<31), nil
+}
 
-	for {
-		// Sleep to avoid excessive polling
-		time.Sleep(1 * time.Second)
+// replicationTLSConfig returns the TLS configuration of the binlog connection
+func replicationTLSConfig(config dbclient.ReplicationConfig) (*tls.Config, error) {
+	if !config.SSL {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: config.Host}
+	switch strings.ToLower(config.SSLMode) {
+	case "skip-verify", "preferred", "require", "required":
+		tlsConfig.InsecureSkipVerify = true
+	}
 
-		// Get changes since last check
-		changes, err := getReplicationChanges(db, details.TableName, lastCheck)
+	if config.SSLRootCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.SSLRootCert)) {
+			return nil, fmt.Errorf("invalid SSL root certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.SSLCert != "" && config.SSLKey != "" {
+		cert, err := tls.X509KeyPair([]byte(config.SSLCert), []byte(config.SSLKey))
 		if err != nil {
-			// Log error but continue
-			fmt.Printf("Error getting replication changes: %v\n", err)
-			continue
+			return nil, fmt.Errorf("invalid SSL client certificate: %w", err)
 		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
 
-		// Update last check time
-		lastCheck = time.Now()
+// streamBinlogEvents streams the binlog until ctx is cancelled. A failed stream is
+// restarted from the last committed position.
+func streamBinlogEvents(ctx context.Context, details *MySQLReplicationSourceDetails) {
+	for {
+		err := streamBinlog(ctx, details)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			details.setError(err)
+		}
 
-		// Process changes
-		for _, change := range changes {
-			// Call event handler with the change data
-			if change.Operation == "INSERT" || change.Operation == "UPDATE" {
-				eventHandler(change.Data)
-			}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(binlogRetryDelay):
 		}
 	}
 }
 
-// getReplicationChanges gets changes to a table since a specific time
-// This is a simplified implementation that polls for changes
-func getReplicationChanges(db *sql.DB, tableName string, since time.Time) ([]MySQLReplicationChange, error) {
-	// In a real implementation, this would use the binary log
-	// For this example, we'll use a timestamp column if available
-
-	// Check if the table has a timestamp column we can use
-	var timestampColumn string
-	query := `
-		SELECT COLUMN_NAME 
-		FROM information_schema.COLUMNS 
-		WHERE TABLE_SCHEMA = DATABASE() 
-		AND TABLE_NAME = ? 
-		AND (DATA_TYPE = 'timestamp' OR DATA_TYPE = 'datetime')
-		LIMIT 1`
-
-	err := db.QueryRow(query, tableName).Scan(&timestampColumn)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("error checking for timestamp column: %w", err)
-	}
-
-	var changes []MySQLReplicationChange
-
-	// If we have a timestamp column, use it to get changes
-	if timestampColumn != "" {
-		// Get rows updated since last check
-		query = fmt.Sprintf("SELECT * FROM %s WHERE %s > ?",
-			QuoteIdentifier(tableName),
-			QuoteIdentifier(timestampColumn))
-
-		rows, err := db.Query(query, since.Format("2006-01-02 15:04:05"))
-		if err != nil {
-			return nil, fmt.Errorf("error querying for changes: %w", err)
+// streamBinlog runs one binlog stream from the current position and forwards the
+// row changes of the replicated tables
+func streamBinlog(ctx context.Context, details *MySQLReplicationSourceDetails) error {
+	syncer := replication.NewBinlogSyncer(details.syncerConfig)
+	defer syncer.Close()
+
+	details.positionMutex.RLock()
+	useGTID := details.useGTID && details.GTIDSet != ""
+	gtidSet := details.GTIDSet
+	file := details.BinlogFile
+	pos := details.BinlogPosition
+	details.positionMutex.RUnlock()
+
+	var streamer *replication.BinlogStreamer
+	var err error
+	if useGTID {
+		gset, perr := gomysql.ParseGTIDSet(gomysql.MySQLFlavor, gtidSet)
+		if perr != nil {
+			return fmt.Errorf("invalid GTID set %q: %w", gtidSet, perr)
 		}
-		defer rows.Close()
+		streamer, err = syncer.StartSyncGTID(gset)
+	} else {
+		if pos < 4 {
+			// Events start after the 4-byte binlog file header
+			pos = 4
+		}
+		streamer, err = syncer.StartSync(gomysql.Position{Name: file, Pos: pos})
+	}
+	if err != nil {
+		return fmt.Errorf("error starting binlog stream: %w", err)
+	}
 
-		// Get column names
-		columns, err := rows.Columns()
+	lastCheckpoint := time.Now()
+	for {
+		ev, err := streamer.GetEvent(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error getting column names: %w", err)
+			return fmt.Errorf("error reading binlog event: %w", err)
 		}
 
-		// Process rows
-		for rows.Next() {
-			// Create a slice of interface{} to hold the values
-			values := make([]interface{}, len(columns))
-			valuePtrs := make([]interface{}, len(columns))
-			for i := range values {
-				valuePtrs[i] = &values[i]
+		switch e := ev.Event.(type) {
+		case *replication.RotateEvent:
+			file = string(e.NextLogName)
+			details.UpdatePosition(file, uint32(e.Position))
+
+		case *replication.RowsEvent:
+			if err := handleRowsEvent(ctx, details, ev.Header, e, file); err != nil {
+				details.setError(err)
 			}
 
-			// Scan the row into the slice of interface{}
-			if err := rows.Scan(valuePtrs...); err != nil {
-				return nil, fmt.Errorf("error scanning row: %w", err)
+		case *replication.XIDEvent:
+			// Transaction commit: everything up to here has been forwarded
+			details.UpdatePosition(file, ev.Header.LogPos)
+			if e.GSet != nil {
+				details.UpdateGTIDSet(e.GSet.String())
 			}
 
-			// Create a map for this row
-			rowMap := make(map[string]interface{})
-			for i, col := range columns {
-				val := values[i]
-				// Handle NULL values
-				if val == nil {
-					rowMap[col] = nil
-					continue
-				}
-
-				// Convert bytes to string for text types
-				switch v := val.(type) {
-				case []byte:
-					rowMap[col] = string(v)
-				default:
-					rowMap[col] = v
-				}
+		case *replication.QueryEvent:
+			query := strings.ToUpper(strings.TrimSpace(string(e.Query)))
+			if query == "BEGIN" {
+				continue
 			}
+			// DDL may change the columns of the replicated tables
+			details.columnsMutex.Lock()
+			details.columns = make(map[string][]string)
+			details.columnsMutex.Unlock()
 
-			// Add to changes
-			changes = append(changes, MySQLReplicationChange{
-				Operation: "UPDATE", // Assume update for simplicity
-				Data:      rowMap,
-			})
+			details.UpdatePosition(file, ev.Header.LogPos)
+			if e.GSet != nil {
+				details.UpdateGTIDSet(e.GSet.String())
+			}
+		}
+
+		if time.Since(lastCheckpoint) >= checkpointInterval {
+			details.checkpoint(ctx, false)
+			lastCheckpoint = time.Now()
 		}
 	}
+}
 
-	return changes, nil
+// handleRowsEvent converts the rows of a rows event to change events
+func handleRowsEvent(ctx context.Context, details *MySQLReplicationSourceDetails, header *replication.EventHeader, e *replication.RowsEvent, file string) error {
+	schema := string(e.Table.Schema)
+	table := string(e.Table.Table)
+	if !details.HasTable(schema, table) {
+		return nil
+	}
+
+	var operation string
+	switch header.EventType {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		operation = "INSERT"
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		operation = "UPDATE"
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		operation = "DELETE"
+	default:
+		return nil
+	}
+
+	columns, err := details.tableColumns(ctx, e.Table)
+	if err != nil {
+		return err
+	}
+
+	emit := func(data, oldData map[string]interface{}) {
+		event := map[string]interface{}{
+			"operation":       operation,
+			"table_name":      table,
+			"schema_name":     schema,
+			"database_id":     details.DatabaseID,
+			"data":            data,
+			"binlog_file":     file,
+			"binlog_position": header.LogPos,
+			"timestamp":       time.Unix(int64(header.Timestamp), 0).UTC().Format(time.RFC3339),
+		}
+		if oldData != nil {
+			event["old_data"] = oldData
+		}
+
+		details.positionMutex.Lock()
+		details.eventCount++
+		details.lastEventAt = time.Now()
+		details.positionMutex.Unlock()
+
+		if details.EventHandler != nil {
+			details.EventHandler(event)
+		}
+	}
+
+	if operation == "UPDATE" {
+		// Update events hold the before and after image of each row in turn
+		for i := 0; i+1 < len(e.Rows); i += 2 {
+			emit(rowToMap(columns, e.Rows[i+1]), rowToMap(columns, e.Rows[i]))
+		}
+		return nil
+	}
+	for _, row := range e.Rows {
+		emit(rowToMap(columns, row), nil)
+	}
+	return nil
+}
+
+// tableColumns returns the column names of a table in binlog order. They come from the
+// table map event when binlog_row_metadata is FULL, and from the information schema otherwise.
+func (m *MySQLReplicationSourceDetails) tableColumns(ctx context.Context, table *replication.TableMapEvent) ([]string, error) {
+	if names := table.ColumnNameString(); len(names) == int(table.ColumnCount) {
+		return names, nil
+	}
+
+	key := string(table.Schema) + "." + string(table.Table)
+	m.columnsMutex.Lock()
+	defer m.columnsMutex.Unlock()
+
+	if names, ok := m.columns[key]; ok && len(names) == int(table.ColumnCount) {
+		return names, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT COLUMN_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY ORDINAL_POSITION`, string(table.Schema), string(table.Table))
+	if err != nil {
+		return nil, fmt.Errorf("error getting columns of %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error getting columns of %s: %w", key, err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error getting columns of %s: %w", key, err)
+	}
+	if len(names) != int(table.ColumnCount) {
+		return nil, fmt.Errorf("table %s has %d columns but the binlog event has %d", key, len(names), table.ColumnCount)
+	}
+
+	m.columns[key] = names
+	return names, nil
+}
+
+// rowToMap maps the values of a binlog row to their column names
+func rowToMap(columns []string, row []interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(row))
+	for i, val := range row {
+		if i >= len(columns) {
+			break
+		}
+		// Convert bytes to string for text types
+		if b, ok := val.([]byte); ok {
+			data[columns[i]] = string(b)
+			continue
+		}
+		data[columns[i]] = val
+	}
+	return data
 }
@@ -2,9 +2,11 @@ package mysql
 
 import (
 	"context"
+	"time"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/dbclient"
 )
 
 // ReplicationOps implements adapter.ReplicationOperator for MySQL.
@@ -25,14 +27,12 @@ func (r *ReplicationOps) GetSupportedMechanisms() []string {
 
 // CheckPrerequisites checks if replication prerequisites are met.
 func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
-	// Check if binlog is enabled
-	var logBin string
-	err := r.conn.db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'log_bin'").Scan(&logBin, &logBin)
+	binlog, err := GetBinlogStatus(ctx, r.conn.db)
 	if err != nil {
 		return adapter.WrapError(dbcapabilities.MySQL, "check_replication_prerequisites", err)
 	}
 
-	if logBin != "ON" {
+	if !binlog.Enabled {
 		return adapter.NewDatabaseError(
 			dbcapabilities.MySQL,
 			"check_replication_prerequisites",
@@ -40,88 +40,123 @@ func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
 		).WithContext("error", "binary logging (binlog) is not enabled")
 	}
 
-	// Check binlog format
-	var binlogFormat string
-	err = r.conn.db.QueryRowContext(ctx, "SHOW VARIABLES LIKE 'binlog_format'").Scan(&binlogFormat, &binlogFormat)
-	if err != nil {
-		return adapter.WrapError(dbcapabilities.MySQL, "check_replication_prerequisites", err)
-	}
-
-	if binlogFormat != "ROW" && binlogFormat != "MIXED" {
+	// Statement-based events carry no row images, so every change must be logged as rows
+	if binlog.Format != "ROW" {
 		return adapter.NewDatabaseError(
 			dbcapabilities.MySQL,
 			"check_replication_prerequisites",
 			adapter.ErrConfigurationError,
-		).WithContext("error", "binlog_format must be ROW or MIXED for CDC")
+		).WithContext("error", "binlog_format must be ROW for CDC")
 	}
 
 	return nil
 }
 
-// Connect creates a new replication connection.
-// Note: Full MySQL binlog replication implementation would go here.
-// For now, this is a placeholder that will be implemented when MySQL source CDC is needed.
+// Connect creates a new binlog replication connection. The source registers as a
+// replica and streams the row events of the configured tables.
 func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
-	// MySQL binlog CDC implementation would go here
-	// This would involve:
-	// 1. Creating a binlog reader connection
-	// 2. Setting up binlog event parsing
-	// 3. Starting the event stream
-	//
-	// For now, return an error indicating this is not yet implemented for source
-	return nil, adapter.NewDatabaseError(
-		dbcapabilities.MySQL,
-		"connect_replication",
-		adapter.ErrOperationNotSupported,
-	).WithContext("error", "MySQL as CDC source is not yet implemented")
+	// Convert adapter config to legacy config
+	legacyConfig := dbclient.ReplicationConfig{
+		ReplicationID:      config.ReplicationID,
+		DatabaseID:         config.DatabaseID,
+		WorkspaceID:        config.WorkspaceID,
+		TenantID:           config.TenantID,
+		EnvironmentID:      adapter.GetString(config.EnvironmentID),
+		ReplicationName:    config.ReplicationName,
+		ConnectionType:     config.ConnectionType,
+		DatabaseVendor:     config.DatabaseVendor,
+		Host:               config.Host,
+		Port:               config.Port,
+		Username:           config.Username,
+		Password:           config.Password,
+		DatabaseName:       config.DatabaseName,
+		SSL:                config.SSL,
+		SSLMode:            config.SSLMode,
+		SSLCert:            config.SSLCert,
+		SSLKey:             config.SSLKey,
+		SSLRootCert:        config.SSLRootCert,
+		Role:               config.Role,
+		Enabled:            config.Enabled,
+		ConnectedToNodeID:  config.ConnectedToNodeID,
+		OwnerID:            config.OwnerID,
+		TableNames:         config.TableNames,
+		StartPosition:      config.StartPosition,
+		EventHandler:       config.EventHandler,
+		ReplicationOptions: config.Options,
+	}
+
+	_, source, err := ConnectReplication(legacyConfig, r.conn.db)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MySQL, "connect_replication", err)
+	}
+
+	r.conn.replicationSources.Store(source.GetSourceID(), source)
+	return &ReplicationSource{conn: r.conn, source: source}, nil
 }
 
 // GetStatus returns the replication status.
 func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{}, error) {
-	status := make(map[string]interface{})
-
-	// Get master status if this is a source
-	var file, position string
-	err := r.conn.db.QueryRowContext(ctx, "SHOW MASTER STATUS").Scan(&file, &position)
-	if err == nil {
-		status["binlog_file"] = file
-		status["binlog_position"] = position
-		status["role"] = "master"
+	binlog, err := GetBinlogStatus(ctx, r.conn.db)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.MySQL, "get_replication_status", err)
 	}
 
-	// Get slave status if this is a replica
-	rows, err := r.conn.db.QueryContext(ctx, "SHOW SLAVE STATUS")
-	if err == nil {
-		defer rows.Close()
-		if rows.Next() {
-			// Parse slave status (would need full column mapping)
-			status["role"] = "slave"
-		}
-	}
+	var sources []map[string]interface{}
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		sources = append(sources, value.(*MySQLReplicationSourceDetails).GetStatus())
+		return true
+	})
 
+	status := map[string]interface{}{
+		"database_id":     r.conn.id,
+		"binlog_enabled":  binlog.Enabled,
+		"binlog_format":   binlog.Format,
+		"gtid_mode":       binlog.GTIDMode,
+		"binlog_file":     binlog.File,
+		"binlog_position": binlog.Position,
+		"sources":         sources,
+		"total_sources":   len(sources),
+	}
+	if binlog.GTIDMode {
+		status["gtid_executed"] = binlog.ExecutedGTIDSet
+	}
 	return status, nil
 }
 
-// GetLag returns the replication lag.
+// GetLag returns the replication lag of every binlog source: the time since its last
+// event and, while it reads the current binlog file, the bytes it has yet to read.
 func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
-	lag := make(map[string]interface{})
-
-	// Query slave status for lag information
-	var secondsBehindMaster *int64
-	err := r.conn.db.QueryRowContext(ctx, "SHOW SLAVE STATUS").Scan(&secondsBehindMaster)
+	binlog, err := GetBinlogStatus(ctx, r.conn.db)
 	if err != nil {
-		// Not a slave or error querying
-		lag["seconds_behind_master"] = nil
-		return lag, nil
+		return nil, adapter.WrapError(dbcapabilities.MySQL, "get_replication_lag", err)
 	}
 
-	if secondsBehindMaster != nil {
-		lag["seconds_behind_master"] = *secondsBehindMaster
-	} else {
-		lag["seconds_behind_master"] = nil
-	}
+	lags := make([]map[string]interface{}, 0)
+	r.conn.replicationSources.Range(func(_, value interface{}) bool {
+		source := value.(*MySQLReplicationSourceDetails)
+		source.positionMutex.RLock()
+		lag := map[string]interface{}{
+			"source_id":       source.GetSourceID(),
+			"binlog_file":     source.BinlogFile,
+			"binlog_position": source.BinlogPosition,
+		}
+		if source.BinlogFile == binlog.File && binlog.Position >= source.BinlogPosition {
+			lag["bytes_behind"] = binlog.Position - source.BinlogPosition
+		}
+		if !source.lastEventAt.IsZero() {
+			lag["seconds_since_last_event"] = time.Since(source.lastEventAt).Seconds()
+		}
+		source.positionMutex.RUnlock()
+		lags = append(lags, lag)
+		return true
+	})
 
-	return lag, nil
+	return map[string]interface{}{
+		"database_id":     r.conn.id,
+		"binlog_file":     binlog.File,
+		"binlog_position": binlog.Position,
+		"sources":         lags,
+	}, nil
 }
 
 // ListSlots lists replication slots (MySQL doesn't have slots like PostgreSQL).
@@ -155,3 +190,70 @@ func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName st
 		adapter.ErrOperationNotSupported,
 	).WithContext("error", "MySQL does not use publications")
 }
+
+// ReplicationSource implements adapter.ReplicationSource for MySQL.
+type ReplicationSource struct {
+	conn   *Connection
+	source *MySQLReplicationSourceDetails
+}
+
+// GetSourceID returns the replication source ID.
+func (r *ReplicationSource) GetSourceID() string {
+	return r.source.GetSourceID()
+}
+
+// GetDatabaseID returns the database ID.
+func (r *ReplicationSource) GetDatabaseID() string {
+	return r.source.GetDatabaseID()
+}
+
+// GetStatus returns the replication source status.
+func (r *ReplicationSource) GetStatus() map[string]interface{} {
+	return r.source.GetStatus()
+}
+
+// GetMetadata returns the replication source metadata.
+func (r *ReplicationSource) GetMetadata() map[string]interface{} {
+	return r.source.GetMetadata()
+}
+
+// IsActive returns whether the replication source is active.
+func (r *ReplicationSource) IsActive() bool {
+	return r.source.IsActive()
+}
+
+// Start starts the replication source.
+func (r *ReplicationSource) Start() error {
+	return r.source.Start()
+}
+
+// Stop stops the replication source.
+func (r *ReplicationSource) Stop() error {
+	return r.source.Stop()
+}
+
+// Close closes the replication source.
+func (r *ReplicationSource) Close() error {
+	r.conn.replicationSources.Delete(r.source.GetSourceID())
+	return r.source.Close()
+}
+
+// GetPosition returns the current replication position (GTID set or binlog file:position).
+func (r *ReplicationSource) GetPosition() (string, error) {
+	return r.source.GetPosition()
+}
+
+// SetPosition sets the starting replication position for resume.
+func (r *ReplicationSource) SetPosition(position string) error {
+	return r.source.SetPosition(position)
+}
+
+// SaveCheckpoint persists the current replication position.
+func (r *ReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
+	return r.source.SaveCheckpoint(ctx, position)
+}
+
+// SetCheckpointFunc sets the callback function for persisting checkpoints.
+func (r *ReplicationSource) SetCheckpointFunc(fn func(context.Context, string) error) {
+	r.source.SetCheckpointFunc(fn)
+}
@@ -82,6 +82,13 @@ func CreateStructure(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 		return fmt.Errorf("unified model cannot be nil")
 	}
 
+	// Look up the features of the server version, so no DDL it rejects is generated
+	var version string
+	if err := db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return fmt.Errorf("error fetching MySQL version: %v", err)
+	}
+	caps, _ := dbcapabilities.GetForVersion(dbcapabilities.MySQL, version)
+
 	// Start a transaction
 	tx, err := db.Begin()
 	if err != nil {
@@ -127,7 +134,7 @@ func CreateStructure(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 
 	// Create tables
 	for _, table := range sortedTables {
-		if err := CreateTableFromUnified(tx, table, um.Types); err != nil {
+		if err := CreateTableFromUnified(tx, table, um.Types, caps); err != nil {
 			return fmt.Errorf("error creating table %s: %v", table.Name, err)
 		}
 	}
@@ -716,8 +723,9 @@ func getExtensionsUnified(db *sql.DB, um *unifiedmodel.UnifiedModel) error {
 	return nil
 }
 
-// CreateTableFromUnified creates a table from UnifiedModel Table
-func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table, types map[string]unifiedmodel.Type) error {
+// CreateTableFromUnified creates a table from UnifiedModel Table, with the column types the
+// server version supports
+func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table, types map[string]unifiedmodel.Type, caps dbcapabilities.VersionedCapability) error {
 	if tx == nil {
 		return fmt.Errorf("transaction is nil")
 	}
@@ -748,7 +756,7 @@ func CreateTableFromUnified(tx *sql.Tx, table unifiedmodel.Table, types map[stri
 		createTableSQL += fmt.Sprintf("%s ", QuoteIdentifier(column.Name))
 
 		// Handle data type
-		createTableSQL += mapUnifiedDataTypeToMySQL(column.DataType)
+		createTableSQL += mysqlTypeForVersion(mapUnifiedDataTypeToMySQL(column.DataType), caps)
 
 		if !column.Nullable {
 			createTableSQL += " NOT NULL"
@@ -935,6 +943,14 @@ func createSequence(tx *sql.Tx, sequence unifiedmodel.Sequence) error {
 	return nil
 }
 
+// mysqlTypeForVersion replaces the JSON type with LONGTEXT on server versions without it
+func mysqlTypeForVersion(dataType string, caps dbcapabilities.VersionedCapability) string {
+	if dataType == "JSON" && !caps.Supports(dbcapabilities.FeatureJSONType) {
+		return "LONGTEXT"
+	}
+	return dataType
+}
+
 // mapUnifiedDataTypeToMySQL maps UnifiedModel data types to MySQL types
 func mapUnifiedDataTypeToMySQL(dataType string) string {
 	switch strings.ToLower(dataType) {
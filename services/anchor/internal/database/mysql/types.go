@@ -2,17 +2,48 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
 )
 
+// gtidPositionPrefix marks a position given as a GTID set rather than a binlog file and offset
+const gtidPositionPrefix = "gtid:"
+
 // MySQLReplicationSourceDetails contains details about a MySQL replication source
 type MySQLReplicationSourceDetails struct {
 	BinlogFile     string `json:"binlog_file"`
 	BinlogPosition uint32 `json:"binlog_position"`
-	TableName      string `json:"table_name"`
+	GTIDSet        string `json:"gtid_set,omitempty"` // Executed GTID set when GTID mode is on
+	DatabaseName   string `json:"database_name"`
 	DatabaseID     string `json:"database_id"`
 	SlotName       string `json:"slot_name"` // For compatibility with interface
+	ServerID       uint32 `json:"server_id"` // Server ID the binlog reader registers as
+
+	// TableNames is the set of tables being replicated
+	TableNames   map[string]struct{}          `json:"table_names"`
+	EventHandler func(map[string]interface{}) `json:"-"`
+
+	db           *sql.DB
+	syncerConfig replication.BinlogSyncerConfig
+	useGTID      bool
+
+	// Column names by schema.table, for binlogs written without full row metadata
+	columns      map[string][]string
+	columnsMutex sync.Mutex
+
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	eventCount   int64
+	lastEventAt  time.Time
+	lastError    string
+	lastSavedPos string
 
 	// Position tracking for graceful shutdown and resume
 	positionMutex  sync.RWMutex
@@ -20,13 +51,6 @@ type MySQLReplicationSourceDetails struct {
 	isActive       bool
 }
 
-// MySQLReplicationChange represents a change in MySQL replication
-type MySQLReplicationChange struct {
-	Operation string
-	Data      map[string]interface{}
-	OldData   map[string]interface{}
-}
-
 // GetSourceID returns the replication source ID (slot name or database ID).
 func (m *MySQLReplicationSourceDetails) GetSourceID() string {
 	if m.SlotName != "" {
@@ -40,18 +64,50 @@ func (m *MySQLReplicationSourceDetails) GetDatabaseID() string {
 	return m.DatabaseID
 }
 
+// GetTables returns the tables being replicated
+func (m *MySQLReplicationSourceDetails) GetTables() []string {
+	tables := make([]string, 0, len(m.TableNames))
+	for t := range m.TableNames {
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// HasTable checks if the replication source is replicating a table of the given schema
+func (m *MySQLReplicationSourceDetails) HasTable(schema, table string) bool {
+	if _, ok := m.TableNames[schema+"."+table]; ok {
+		return true
+	}
+	if schema != m.DatabaseName {
+		return false
+	}
+	_, ok := m.TableNames[table]
+	return ok
+}
+
 // GetStatus returns the replication status.
 func (m *MySQLReplicationSourceDetails) GetStatus() map[string]interface{} {
 	m.positionMutex.RLock()
 	defer m.positionMutex.RUnlock()
 
-	return map[string]interface{}{
+	status := map[string]interface{}{
 		"binlog_file":     m.BinlogFile,
 		"binlog_position": m.BinlogPosition,
-		"table_name":      m.TableName,
+		"gtid_mode":       m.useGTID,
+		"table_names":     m.GetTables(),
 		"database_id":     m.DatabaseID,
+		"server_id":       m.ServerID,
 		"is_active":       m.isActive,
+		"event_count":     m.eventCount,
+		"last_error":      m.lastError,
+	}
+	if m.useGTID {
+		status["gtid_set"] = m.GTIDSet
 	}
+	if !m.lastEventAt.IsZero() {
+		status["last_event_at"] = m.lastEventAt.Format(time.RFC3339)
+	}
+	return status
 }
 
 // GetMetadata returns the replication metadata.
@@ -60,10 +116,12 @@ func (m *MySQLReplicationSourceDetails) GetMetadata() map[string]interface{} {
 	defer m.positionMutex.RUnlock()
 
 	return map[string]interface{}{
+		"source_type":     "binlog",
 		"binlog_file":     m.BinlogFile,
 		"binlog_position": m.BinlogPosition,
-		"table_name":      m.TableName,
+		"gtid_mode":       m.useGTID,
 		"database_id":     m.DatabaseID,
+		"server_id":       m.ServerID,
 	}
 }
 
@@ -74,7 +132,7 @@ func (m *MySQLReplicationSourceDetails) IsActive() bool {
 	return m.isActive
 }
 
-// Start starts the replication source.
+// Start starts streaming the binlog from the current position.
 func (m *MySQLReplicationSourceDetails) Start() error {
 	m.positionMutex.Lock()
 	defer m.positionMutex.Unlock()
@@ -83,22 +141,33 @@ func (m *MySQLReplicationSourceDetails) Start() error {
 		return nil // Already active
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
 	m.isActive = true
-	// TODO: Start MySQL binlog streaming when implemented
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		streamBinlogEvents(ctx, m)
+	}()
 	return nil
 }
 
-// Stop stops the replication source.
+// Stop stops the replication source and saves its final position.
 func (m *MySQLReplicationSourceDetails) Stop() error {
 	m.positionMutex.Lock()
-	defer m.positionMutex.Unlock()
-
 	if !m.isActive {
+		m.positionMutex.Unlock()
 		return nil // Already stopped
 	}
-
 	m.isActive = false
-	// TODO: Stop MySQL binlog streaming when implemented
+	cancel := m.cancel
+	m.positionMutex.Unlock()
+
+	cancel()
+	m.wg.Wait()
+
+	m.checkpoint(context.Background(), true)
 	return nil
 }
 
@@ -108,42 +177,64 @@ func (m *MySQLReplicationSourceDetails) Close() error {
 	if err := m.Stop(); err != nil {
 		return fmt.Errorf("failed to stop replication: %w", err)
 	}
-
-	// TODO: Close MySQL binlog connection when implemented
 	return nil
 }
 
-// GetPosition returns the current binlog position as a string.
-// Format: "filename:position" (e.g., "mysql-bin.000001:12345")
+// GetPosition returns the current replication position as a string.
+// Format: "gtid:<gtid set>" in GTID mode, "filename:position" otherwise
+// (e.g., "mysql-bin.000001:12345").
 func (m *MySQLReplicationSourceDetails) GetPosition() (string, error) {
 	m.positionMutex.RLock()
 	defer m.positionMutex.RUnlock()
 
+	return m.position()
+}
+
+// position formats the current position; the caller holds positionMutex
+func (m *MySQLReplicationSourceDetails) position() (string, error) {
+	if m.useGTID && m.GTIDSet != "" {
+		return gtidPositionPrefix + m.GTIDSet, nil
+	}
 	if m.BinlogFile == "" {
 		return "", fmt.Errorf("no binlog position available")
 	}
-
 	return fmt.Sprintf("%s:%d", m.BinlogFile, m.BinlogPosition), nil
 }
 
-// SetPosition sets the starting binlog position for replication resume.
-// The position string should be in format "filename:position" (e.g., "mysql-bin.000001:12345").
+// SetPosition sets the starting position for replication resume, either as
+// "gtid:<gtid set>" or as "filename:position" (e.g., "mysql-bin.000001:12345").
 func (m *MySQLReplicationSourceDetails) SetPosition(position string) error {
 	if position == "" {
-		return nil // No position to set, will start from beginning
+		return nil // No position to set, will start from the current position
+	}
+
+	if strings.HasPrefix(position, gtidPositionPrefix) {
+		gtidSet := strings.TrimPrefix(position, gtidPositionPrefix)
+		if _, err := gomysql.ParseGTIDSet(gomysql.MySQLFlavor, gtidSet); err != nil {
+			return fmt.Errorf("invalid GTID set %q: %w", gtidSet, err)
+		}
+
+		m.positionMutex.Lock()
+		m.GTIDSet = gtidSet
+		m.useGTID = true
+		m.positionMutex.Unlock()
+		return nil
 	}
 
 	// Parse position string "filename:position"
-	var file string
-	var pos uint32
-	_, err := fmt.Sscanf(position, "%s:%d", &file, &pos)
+	i := strings.LastIndex(position, ":")
+	if i <= 0 {
+		return fmt.Errorf("invalid binlog position %q (expected format: filename:position)", position)
+	}
+	pos, err := strconv.ParseUint(position[i+1:], 10, 32)
 	if err != nil {
 		return fmt.Errorf("invalid binlog position %q (expected format: filename:position): %w", position, err)
 	}
 
 	m.positionMutex.Lock()
-	m.BinlogFile = file
-	m.BinlogPosition = pos
+	m.BinlogFile = position[:i]
+	m.BinlogPosition = uint32(pos)
+	m.useGTID = false
 	m.positionMutex.Unlock()
 
 	return nil
@@ -151,12 +242,16 @@ func (m *MySQLReplicationSourceDetails) SetPosition(position string) error {
 
 // SaveCheckpoint persists the current replication position.
 func (m *MySQLReplicationSourceDetails) SaveCheckpoint(ctx context.Context, position string) error {
-	if m.checkpointFunc == nil {
+	m.positionMutex.RLock()
+	fn := m.checkpointFunc
+	m.positionMutex.RUnlock()
+
+	if fn == nil {
 		// No checkpoint function configured
 		return nil
 	}
 
-	return m.checkpointFunc(ctx, position)
+	return fn(ctx, position)
 }
 
 // UpdatePosition updates the current binlog position.
@@ -168,7 +263,42 @@ func (m *MySQLReplicationSourceDetails) UpdatePosition(file string, position uin
 	m.positionMutex.Unlock()
 }
 
+// UpdateGTIDSet updates the executed GTID set.
+func (m *MySQLReplicationSourceDetails) UpdateGTIDSet(gtidSet string) {
+	m.positionMutex.Lock()
+	m.GTIDSet = gtidSet
+	m.positionMutex.Unlock()
+}
+
 // SetCheckpointFunc sets the callback function for persisting checkpoints.
 func (m *MySQLReplicationSourceDetails) SetCheckpointFunc(fn func(context.Context, string) error) {
+	m.positionMutex.Lock()
 	m.checkpointFunc = fn
+	m.positionMutex.Unlock()
+}
+
+// checkpoint saves the current position if it changed since the last checkpoint
+func (m *MySQLReplicationSourceDetails) checkpoint(ctx context.Context, force bool) {
+	m.positionMutex.RLock()
+	position, err := m.position()
+	unchanged := position == m.lastSavedPos
+	m.positionMutex.RUnlock()
+
+	if err != nil || (unchanged && !force) {
+		return
+	}
+	if err := m.SaveCheckpoint(ctx, position); err != nil {
+		m.setError(fmt.Errorf("error saving checkpoint %s: %w", position, err))
+		return
+	}
+
+	m.positionMutex.Lock()
+	m.lastSavedPos = position
+	m.positionMutex.Unlock()
+}
+
+func (m *MySQLReplicationSourceDetails) setError(err error) {
+	m.positionMutex.Lock()
+	m.lastError = err.Error()
+	m.positionMutex.Unlock()
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 )
 
 // FetchData retrieves data from a specified label or relationship type
@@ -143,6 +144,33 @@ func InsertData(driver neo4j.DriverWithContext, labelOrType string, isRelationsh
 	return totalRowsAffected, nil
 }
 
+// FetchEdges retrieves up to limit relationships of a type as the element IDs of their start
+// and end nodes
+func FetchEdges(ctx context.Context, driver neo4j.DriverWithContext, relType string, limit int) ([]adapter.Edge, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	query := fmt.Sprintf("MATCH ()-[r:`%s`]->() RETURN r LIMIT $limit", strings.ReplaceAll(relType, "`", "``"))
+	result, err := session.Run(ctx, query, map[string]interface{}{"limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s: %v", relType, err)
+	}
+
+	var edges []adapter.Edge
+	for result.Next(ctx) {
+		value, _ := result.Record().Get("r")
+		relationship, ok := value.(neo4j.Relationship)
+		if !ok {
+			continue
+		}
+		edges = append(edges, adapter.Edge{From: relationship.StartElementId, To: relationship.EndElementId})
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", relType, err)
+	}
+	return edges, nil
+}
+
 // WipeDatabase removes all data from the database
 func WipeDatabase(driver neo4j.DriverWithContext) error {
 	ctx := context.Background()
@@ -67,6 +67,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.Neo4j, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Neo4j, "fetch stream", "not yet implemented")
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.driver, query, args...)
 	if err != nil {
@@ -87,6 +92,18 @@ func (d *DataOps) GetRowCount(ctx context.Context, label string, whereClause str
 	return count, exact, nil
 }
 
+// SampleEdges returns up to limit relationships of a type, from the node IDs of the database.
+func (d *DataOps) SampleEdges(ctx context.Context, relType string, limit int) ([]adapter.Edge, error) {
+	if err := adapter.ValidateEdgeSample(relType, limit); err != nil {
+		return nil, err
+	}
+	edges, err := FetchEdges(ctx, d.conn.driver, relType, limit)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Neo4j, "sample_edges", err)
+	}
+	return edges, nil
+}
+
 func (d *DataOps) Wipe(ctx context.Context) error {
 	err := WipeDatabase(d.conn.driver)
 	if err != nil {
@@ -55,13 +55,10 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 	}
 
 	// Build connection string for Oracle
-	// Format: user/password@host:port/service_name
-	connString := fmt.Sprintf("%s/%s@%s:%d/%s",
-		config.Username,
-		decryptedPassword,
-		config.Host,
-		config.Port,
-		config.DatabaseName)
+	connString, err := connectString(config.Username, decryptedPassword, config.Host, config.Port, config.DatabaseName, config.KerberosAuth)
+	if err != nil {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Oracle, "kerberosAuth", err.Error())
+	}
 
 	// Create connection
 	db, err := sql.Open("godror", connString)
@@ -126,12 +123,10 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		dbName = "ORCL" // Default service name
 	}
 
-	connString := fmt.Sprintf("%s/%s@%s:%d/%s",
-		config.Username,
-		decryptedPassword,
-		config.Host,
-		config.Port,
-		dbName)
+	connString, err := connectString(config.Username, decryptedPassword, config.Host, config.Port, dbName, config.KerberosAuth)
+	if err != nil {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Oracle, "kerberosAuth", err.Error())
+	}
 
 	// Create connection
 	db, err := sql.Open("godror", connString)
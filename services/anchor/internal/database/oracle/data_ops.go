@@ -362,6 +362,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	)
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 // ExecuteQuery executes a raw SQL query.
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	rows, err := d.conn.db.QueryContext(ctx, query, args...)
@@ -0,0 +1,36 @@
+//go:build enterprise
+// +build enterprise
+
+package oracle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/services/anchor/internal/database/kerberos"
+)
+
+// connectString returns the godror connection string of a server. With Kerberos authentication
+// the Oracle client authenticates externally with the credential cache and service name of its
+// sqlnet.ora (SQLNET.AUTHENTICATION_SERVICES = (KERBEROS5), SQLNET.KERBEROS5_CC_NAME and
+// SQLNET.AUTHENTICATION_KERBEROS5_SERVICE). It reads the cache for every connection it opens,
+// so kinit or k5start must keep it renewed; a keytab cannot be used.
+func connectString(username, password, host string, port int, service string, auth *adapter.KerberosAuth) (string, error) {
+	if auth == nil {
+		// Format: user/password@host:port/service_name
+		return fmt.Sprintf("%s/%s@%s:%d/%s", username, password, host, port, service), nil
+	}
+
+	if auth.CCache == "" {
+		return "", fmt.Errorf("oracle kerberos authentication requires a credential cache, the Oracle client does not log in with keytabs")
+	}
+	expiry, err := kerberos.TGTExpiry(auth.CCache)
+	if err != nil {
+		return "", err
+	}
+	if !expiry.After(time.Now()) {
+		return "", fmt.Errorf("the ticket-granting ticket of credential cache %s expired at %s", auth.CCache, expiry.Format(time.RFC3339))
+	}
+	return fmt.Sprintf(`user="" password="" connectString="%s:%d/%s" externalAuth=1`, host, port, service), nil
+}
@@ -2,6 +2,7 @@ package pinecone
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -90,6 +91,49 @@ func FetchData(client *PineconeClient, indexName string, namespace string, limit
 	return result, nil
 }
 
+// QueryVectors returns the topK nearest neighbors of a vector in an index and namespace, scored
+// with the metric of the index
+func QueryVectors(ctx context.Context, client *PineconeClient, indexName string, namespace string, vector []float32, topK int) ([]PineconeMatch, error) {
+	if indexName == "" {
+		return nil, fmt.Errorf("index name cannot be empty")
+	}
+
+	indexHost := fmt.Sprintf(pineconeAPIURL, indexName, client.ProjectID, client.Environment)
+	queryJSON, err := json.Marshal(PineconeQueryRequest{
+		Namespace: namespace,
+		TopK:      topK,
+		Vector:    vector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling query: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/query", indexHost), bytes.NewBuffer(queryJSON))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Api-Key", client.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("query failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var queryResult PineconeQueryResult
+	if err := json.NewDecoder(resp.Body).Decode(&queryResult); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return queryResult.Matches, nil
+}
+
 // InsertData inserts vectors into a specified index and namespace
 func InsertData(client *PineconeClient, indexName string, namespace string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
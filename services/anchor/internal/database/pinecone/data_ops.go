@@ -67,6 +67,28 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.Pinecone, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Pinecone, "fetch stream", "not yet implemented")
+}
+
+// SearchVectors returns the nearest neighbors of a vector in the default namespace of an index.
+// Scores are similarities for cosine and dotproduct indexes, and distances for euclidean ones.
+func (d *DataOps) SearchVectors(ctx context.Context, indexName string, vector []float32, topK int) ([]adapter.VectorMatch, error) {
+	if err := adapter.ValidateVectorSearch(vector, topK); err != nil {
+		return nil, err
+	}
+	matches, err := QueryVectors(ctx, d.conn.client, indexName, "", vector, topK)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Pinecone, "search_vectors", err)
+	}
+	result := make([]adapter.VectorMatch, len(matches))
+	for i, match := range matches {
+		result[i] = adapter.VectorMatch{ID: match.ID, Score: float64(match.Score)}
+	}
+	return result, nil
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.client, query, args...)
 	if err != nil {
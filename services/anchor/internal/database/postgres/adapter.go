@@ -3,9 +3,12 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"sync/atomic"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
@@ -52,12 +55,16 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		decryptedPassword = dp
 	}
 
-	// Build base connection string
+	// Build base connection string; connections to a socket only take the credentials from it
+	host, port := config.Host, config.Port
+	if config.SocketPath != "" {
+		host, port = "localhost", 5432
+	}
 	fmt.Fprintf(&connString, "postgres://%s:%s@%s:%d/%s",
 		config.Username,
 		decryptedPassword,
-		config.Host,
-		config.Port,
+		host,
+		port,
 		config.DatabaseName)
 
 	// Add SSL configuration
@@ -75,8 +82,52 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		connString.WriteString("?sslmode=disable")
 	}
 
+	poolConfig, err := pgxpool.ParseConfig(connString.String())
+	if err != nil {
+		return nil, adapter.NewConnectionError(
+			dbcapabilities.PostgreSQL,
+			config.Host,
+			config.Port,
+			fmt.Errorf("error parsing connection string: %w", err),
+		)
+	}
+
+	// Client certificates and CA bundles replace the TLS configuration of the SSL fields
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, adapter.NewConfigurationError(dbcapabilities.PostgreSQL, "tls", err.Error())
+	}
+	if tlsConfig != nil {
+		poolConfig.ConnConfig.TLSConfig = tlsConfig
+		poolConfig.ConnConfig.Fallbacks = nil
+	}
+
+	// Databases on the anchor node are connected to at their socket
+	if config.SocketPath != "" {
+		dialSocket(poolConfig.ConnConfig, config.SocketPath)
+	}
+
+	// Kerberos tickets authenticate every new connection of the pool in place of the password
+	if config.KerberosAuth != nil {
+		if err := withKerberos(poolConfig.ConnConfig, *config.KerberosAuth, config.Username); err != nil {
+			return nil, adapter.NewConfigurationError(dbcapabilities.PostgreSQL, "kerberosAuth", err.Error())
+		}
+	}
+
+	// IAM tokens replace the password of every new connection of the pool
+	if config.IAMAuth != nil {
+		poolConfig.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+			token, err := config.AuthToken(ctx)
+			if err != nil {
+				return err
+			}
+			cc.Password = token
+			return nil
+		}
+	}
+
 	// Create connection pool
-	pool, err := pgxpool.New(ctx, connString.String())
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, adapter.NewConnectionError(
 			dbcapabilities.PostgreSQL,
@@ -105,6 +156,14 @@ func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig)
 		connected: 1, // Mark as connected
 	}
 
+	// Drop the sandboxes left behind by copy runs that stopped with the anchor, in the
+	// background so that connecting does not wait on it
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, _ = DropStaleSandboxes(ctx, pool)
+	}()
+
 	return conn, nil
 }
 
@@ -130,12 +189,16 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		decryptedPassword = dp
 	}
 
-	// Build base connection string
+	// Build base connection string; connections to a socket only take the credentials from it
+	host, port := config.Host, config.Port
+	if config.SocketPath != "" {
+		host, port = "localhost", 5432
+	}
 	fmt.Fprintf(&connString, "postgres://%s:%s@%s:%d/%s",
 		config.Username,
 		decryptedPassword,
-		config.Host,
-		config.Port,
+		host,
+		port,
 		config.DatabaseName)
 
 	// Add SSL configuration
@@ -153,8 +216,52 @@ func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceCo
 		connString.WriteString("?sslmode=disable")
 	}
 
+	poolConfig, err := pgxpool.ParseConfig(connString.String())
+	if err != nil {
+		return nil, adapter.NewConnectionError(
+			dbcapabilities.PostgreSQL,
+			config.Host,
+			config.Port,
+			fmt.Errorf("error parsing connection string: %w", err),
+		)
+	}
+
+	// Client certificates and CA bundles replace the TLS configuration of the SSL fields
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, adapter.NewConfigurationError(dbcapabilities.PostgreSQL, "tls", err.Error())
+	}
+	if tlsConfig != nil {
+		poolConfig.ConnConfig.TLSConfig = tlsConfig
+		poolConfig.ConnConfig.Fallbacks = nil
+	}
+
+	// Databases on the anchor node are connected to at their socket
+	if config.SocketPath != "" {
+		dialSocket(poolConfig.ConnConfig, config.SocketPath)
+	}
+
+	// Kerberos tickets authenticate every new connection of the pool in place of the password
+	if config.KerberosAuth != nil {
+		if err := withKerberos(poolConfig.ConnConfig, *config.KerberosAuth, config.Username); err != nil {
+			return nil, adapter.NewConfigurationError(dbcapabilities.PostgreSQL, "kerberosAuth", err.Error())
+		}
+	}
+
+	// IAM tokens replace the password of every new connection of the pool
+	if config.IAMAuth != nil {
+		poolConfig.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+			token, err := config.AuthToken(ctx)
+			if err != nil {
+				return err
+			}
+			cc.Password = token
+			return nil
+		}
+	}
+
 	// Create connection pool
-	pool, err := pgxpool.New(ctx, connString.String())
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, adapter.NewConnectionError(
 			dbcapabilities.PostgreSQL,
@@ -444,3 +551,17 @@ func (i *InstanceConnection) Config() adapter.InstanceConfig {
 func (i *InstanceConnection) Adapter() adapter.DatabaseAdapter {
 	return i.adapter
 }
+
+// dialSocket connects to the Unix domain socket at path in place of the host and port of cc.
+// PostgreSQL does not encrypt connections to its sockets, so TLS is not negotiated.
+func dialSocket(cc *pgx.ConnConfig, path string) {
+	cc.TLSConfig = nil
+	cc.Fallbacks = nil
+	cc.LookupFunc = func(ctx context.Context, host string) ([]string, error) {
+		return []string{host}, nil
+	}
+	cc.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
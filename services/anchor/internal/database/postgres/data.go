@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,26 +16,11 @@ func FetchData(pool *pgxpool.Pool, tableName string, limit int) ([]map[string]in
 		return nil, fmt.Errorf("table name cannot be empty")
 	}
 
-	// Get columns for the table
-	columns, err := getColumns(pool, tableName)
+	query, columns, err := buildFetchQuery(pool, tableName, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build and execute query - cast all columns to text to handle custom types like ENUMs
-	quotedColumns := make([]string, len(columns))
-	for i, col := range columns {
-		// Cast each column to text to handle custom types (ENUMs, etc.)
-		quotedColumns[i] = fmt.Sprintf("%s::text", quoteIdentifier(col))
-	}
-
-	query := fmt.Sprintf("SELECT %s FROM %s",
-		strings.Join(quotedColumns, ", "),
-		quoteIdentifier(tableName))
-	if limit > 0 {
-		query += fmt.Sprintf(" LIMIT %d", limit)
-	}
-
 	rows, err := pool.Query(context.Background(), query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying table %s: %v", tableName, err)
@@ -62,6 +49,196 @@ func FetchData(pool *pgxpool.Pool, tableName string, limit int) ([]map[string]in
 	return result, nil
 }
 
+// FetchCursor reads a table in batches through a server-side cursor. The cursor runs in a
+// read-only repeatable read transaction, so all batches come from the same snapshot and
+// the table is read once, without the cost of paging by offset.
+type FetchCursor struct {
+	ctx       context.Context
+	tx        pgx.Tx
+	columns   []string
+	batchSize int
+	done      bool
+	closed    bool
+}
+
+// fetchCursorName is the name of the cursor; each cursor has its own transaction
+const fetchCursorName = "redb_fetch_cursor"
+
+// OpenFetchCursor declares a cursor over all rows of a table. The cursor holds a pooled
+// connection until it is closed.
+func OpenFetchCursor(ctx context.Context, pool *pgxpool.Pool, tableName string, batchSize int) (*FetchCursor, error) {
+	return openFetchCursor(ctx, pool, tableName, "", batchSize)
+}
+
+// OpenKeyRangeCursor declares a cursor over the rows of a table whose integer key column
+// is in [lower, upper). Cursors over separate ranges of a table can be read concurrently;
+// each reads its own snapshot.
+func OpenKeyRangeCursor(ctx context.Context, pool *pgxpool.Pool, tableName string, keyColumn string, lower, upper int64, batchSize int) (*FetchCursor, error) {
+	if keyColumn == "" {
+		return nil, fmt.Errorf("key column cannot be empty")
+	}
+
+	// DECLARE does not take parameters; the bounds are integers and safe to inline
+	whereClause := fmt.Sprintf("%s >= %d AND %s < %d",
+		quoteIdentifier(keyColumn), lower, quoteIdentifier(keyColumn), upper)
+	return openFetchCursor(ctx, pool, tableName, whereClause, batchSize)
+}
+
+func openFetchCursor(ctx context.Context, pool *pgxpool.Pool, tableName string, whereClause string, batchSize int) (*FetchCursor, error) {
+	if tableName == "" {
+		return nil, fmt.Errorf("table name cannot be empty")
+	}
+
+	query, columns, err := buildFetchQuery(pool, tableName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("error starting cursor transaction: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", fetchCursorName, query)); err != nil {
+		tx.Rollback(context.Background())
+		return nil, fmt.Errorf("error declaring cursor for table %s: %v", tableName, err)
+	}
+
+	return &FetchCursor{ctx: ctx, tx: tx, columns: columns, batchSize: batchSize}, nil
+}
+
+// Next fetches the next batch of rows, or returns io.EOF once all rows were fetched.
+func (c *FetchCursor) Next() ([]map[string]interface{}, error) {
+	if c.done || c.closed {
+		return nil, io.EOF
+	}
+
+	rows, err := c.tx.Query(c.ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", c.batchSize, fetchCursorName))
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("error fetching from cursor: %v", err)
+	}
+	defer rows.Close()
+
+	result := make([]map[string]interface{}, 0, c.batchSize)
+	for rows.Next() {
+		values := make([]interface{}, len(c.columns))
+		valuePtrs := make([]interface{}, len(c.columns))
+		for i := range c.columns {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			rows.Close()
+			c.Close()
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+
+		entry := make(map[string]interface{}, len(c.columns))
+		for i, col := range c.columns {
+			entry[col] = values[i]
+		}
+		result = append(result, entry)
+	}
+	if err := rows.Err(); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("error reading from cursor: %v", err)
+	}
+
+	if len(result) < c.batchSize {
+		c.done = true
+	}
+	if len(result) == 0 {
+		c.Close()
+		return nil, io.EOF
+	}
+	return result, nil
+}
+
+// Close closes the cursor and releases its connection. It is safe to call more than once.
+func (c *FetchCursor) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	// Ending the transaction closes the cursor
+	if err := c.tx.Rollback(context.Background()); err != nil && err != pgx.ErrTxClosed {
+		return fmt.Errorf("error closing cursor: %v", err)
+	}
+	return nil
+}
+
+// FetchKeyBounds returns the smallest and largest value of an integer key column of a
+// table. ok is false if the table has no rows.
+func FetchKeyBounds(ctx context.Context, pool *pgxpool.Pool, tableName string, keyColumn string) (lower, upper int64, ok bool, err error) {
+	if tableName == "" || keyColumn == "" {
+		return 0, 0, false, fmt.Errorf("table name and key column cannot be empty")
+	}
+
+	query := fmt.Sprintf("SELECT MIN(%s)::bigint, MAX(%s)::bigint FROM %s",
+		quoteIdentifier(keyColumn), quoteIdentifier(keyColumn), quoteIdentifier(tableName))
+
+	var minValue, maxValue *int64
+	if err := pool.QueryRow(ctx, query).Scan(&minValue, &maxValue); err != nil {
+		return 0, 0, false, fmt.Errorf("error reading bounds of %s.%s: %v", tableName, keyColumn, err)
+	}
+	if minValue == nil || maxValue == nil {
+		return 0, 0, false, nil
+	}
+	return *minValue, *maxValue, true, nil
+}
+
+// buildFetchQuery builds the query FetchData runs and returns it with the fetched columns
+func buildFetchQuery(pool *pgxpool.Pool, tableName string, limit int) (string, []string, error) {
+	// Get columns for the table
+	columns, err := getColumns(pool, tableName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Cast all columns to text to handle custom types like ENUMs
+	quotedColumns := make([]string, len(columns))
+	for i, col := range columns {
+		quotedColumns[i] = fmt.Sprintf("%s::text", quoteIdentifier(col))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s",
+		strings.Join(quotedColumns, ", "),
+		quoteIdentifier(tableName))
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return query, columns, nil
+}
+
+// ExplainFetch returns the JSON execution plan of the query FetchData runs. With analyze
+// the query is executed and the plan includes actual timings and buffer usage.
+func ExplainFetch(ctx context.Context, pool *pgxpool.Pool, tableName string, limit int, analyze bool) (string, string, error) {
+	if tableName == "" {
+		return "", "", fmt.Errorf("table name cannot be empty")
+	}
+
+	query, _, err := buildFetchQuery(pool, tableName, limit)
+	if err != nil {
+		return "", "", err
+	}
+
+	options := "FORMAT JSON"
+	if analyze {
+		options = "ANALYZE, BUFFERS, FORMAT JSON"
+	}
+
+	var plan string
+	if err := pool.QueryRow(ctx, fmt.Sprintf("EXPLAIN (%s) %s", options, query)).Scan(&plan); err != nil {
+		return "", "", fmt.Errorf("error explaining query on table %s: %v", tableName, err)
+	}
+	return query, plan, nil
+}
+
 // InsertData inserts data into a specified table
 func InsertData(pool *pgxpool.Pool, tableName string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
@@ -121,6 +298,29 @@ func InsertData(pool *pgxpool.Pool, tableName string, data []map[string]interfac
 	return totalRowsAffected, nil
 }
 
+// BulkInsertData loads data into a specified table with COPY FROM STDIN. Only the
+// given columns are loaded; rows without a value for a column load NULL.
+func BulkInsertData(ctx context.Context, pool *pgxpool.Pool, tableName string, columns []string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	rows := make([][]interface{}, len(data))
+	for i, row := range data {
+		values := make([]interface{}, len(columns))
+		for j, col := range columns {
+			values[j] = row[col]
+		}
+		rows[i] = values
+	}
+
+	count, err := pool.CopyFrom(ctx, pgx.Identifier{tableName}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return 0, fmt.Errorf("error copying data into table %s: %v", tableName, err)
+	}
+	return count, nil
+}
+
 // UpsertData inserts or updates data in a specified table based on unique constraints
 func UpsertData(pool *pgxpool.Pool, tableName string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
 	if len(data) == 0 {
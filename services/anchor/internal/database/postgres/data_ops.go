@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"math"
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
@@ -22,6 +24,15 @@ func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[str
 	return data, nil
 }
 
+// ExplainFetch returns the execution plan of the query behind Fetch.
+func (d *DataOps) ExplainFetch(ctx context.Context, table string, limit int, analyze bool) (*adapter.QueryPlan, error) {
+	query, plan, err := ExplainFetch(ctx, d.conn.pool, table, limit, analyze)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "explain_fetch", err)
+	}
+	return &adapter.QueryPlan{Query: query, Format: "json", Plan: plan, Analyzed: analyze}, nil
+}
+
 // FetchWithColumns retrieves specific columns from a table.
 func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
 	// Use existing FetchData and filter columns
@@ -61,6 +72,15 @@ func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]in
 	return count, nil
 }
 
+// BulkInsert loads data into a table with COPY.
+func (d *DataOps) BulkInsert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	count, err := BulkInsertData(ctx, d.conn.pool, table, adapter.BulkColumns(data), data)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.PostgreSQL, "bulk_insert", err)
+	}
+	return count, nil
+}
+
 // Update updates data in a table.
 func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
 	// Use existing UpdateData function
@@ -113,6 +133,92 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through a server-side cursor.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	if batchSize <= 0 {
+		batchSize = adapter.DefaultFetchBatchSize
+	}
+
+	cursor, err := OpenFetchCursor(ctx, d.conn.pool, table, batchSize)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "fetch_stream", err)
+	}
+	return cursor, nil
+}
+
+// KeyBounds returns the range holding all values of an integer key column of a table.
+func (d *DataOps) KeyBounds(ctx context.Context, table string, keyColumn string) (adapter.KeyRange, error) {
+	lower, upper, ok, err := FetchKeyBounds(ctx, d.conn.pool, table, keyColumn)
+	if err != nil {
+		return adapter.KeyRange{}, adapter.WrapError(dbcapabilities.PostgreSQL, "key_bounds", err)
+	}
+	if !ok {
+		return adapter.KeyRange{Column: keyColumn}, nil
+	}
+	if upper == math.MaxInt64 {
+		return adapter.KeyRange{}, adapter.WrapError(dbcapabilities.PostgreSQL, "key_bounds",
+			fmt.Errorf("key column %s of table %s holds the largest bigint and cannot be split", keyColumn, table))
+	}
+	return adapter.KeyRange{Column: keyColumn, Lower: lower, Upper: upper + 1}, nil
+}
+
+// FetchKeyRange returns the rows of a table whose key is in a range, in batches read
+// through a server-side cursor.
+func (d *DataOps) FetchKeyRange(ctx context.Context, table string, keyRange adapter.KeyRange, batchSize int) (adapter.RowBatchIterator, error) {
+	if batchSize <= 0 {
+		batchSize = adapter.DefaultFetchBatchSize
+	}
+
+	cursor, err := OpenKeyRangeCursor(ctx, d.conn.pool, table, keyRange.Column, keyRange.Lower, keyRange.Upper, batchSize)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "fetch_key_range", err)
+	}
+	return cursor, nil
+}
+
+// CreateSandbox creates a schema holding empty copies of tables, after dropping the stale
+// sandboxes of earlier runs.
+func (d *DataOps) CreateSandbox(ctx context.Context, sandbox string, tables []string) error {
+	if err := adapter.ValidateSandboxName(sandbox); err != nil {
+		return adapter.WrapError(dbcapabilities.PostgreSQL, "create_sandbox", err)
+	}
+	// Stale sandboxes are only dropped for their space; failing to drop them does not fail the run
+	_, _ = DropStaleSandboxes(ctx, d.conn.pool)
+	if err := CreateSandbox(ctx, d.conn.pool, sandbox, tables); err != nil {
+		return adapter.WrapError(dbcapabilities.PostgreSQL, "create_sandbox", err)
+	}
+	return nil
+}
+
+// InsertSandbox loads rows into the copy of a table in a sandbox schema with COPY.
+func (d *DataOps) InsertSandbox(ctx context.Context, sandbox string, table string, data []map[string]interface{}) (int64, error) {
+	count, err := InsertSandboxData(ctx, d.conn.pool, sandbox, table, adapter.BulkColumns(data), data)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.PostgreSQL, "insert_sandbox", err)
+	}
+	return count, nil
+}
+
+// FetchSandbox returns rows of the copy of a table in a sandbox schema.
+func (d *DataOps) FetchSandbox(ctx context.Context, sandbox string, table string, limit int) ([]map[string]interface{}, error) {
+	rows, err := FetchSandboxData(ctx, d.conn.pool, sandbox, table, limit)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "fetch_sandbox", err)
+	}
+	return rows, nil
+}
+
+// DropSandbox drops a sandbox schema.
+func (d *DataOps) DropSandbox(ctx context.Context, sandbox string) error {
+	if err := adapter.ValidateSandboxName(sandbox); err != nil {
+		return adapter.WrapError(dbcapabilities.PostgreSQL, "drop_sandbox", err)
+	}
+	if err := DropSandbox(ctx, d.conn.pool, sandbox); err != nil {
+		return adapter.WrapError(dbcapabilities.PostgreSQL, "drop_sandbox", err)
+	}
+	return nil
+}
+
 // ExecuteQuery executes a query and returns the results.
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	// Use existing ExecuteQuery function
@@ -123,6 +229,16 @@ func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interf
 	return result, nil
 }
 
+// ExecuteReadOnlyQuery executes a query in a read-only transaction, implementing
+// adapter.ReadOnlyQueryExecutor.
+func (d *DataOps) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*adapter.QueryResult, error) {
+	columns, rows, truncated, err := ExecuteReadOnlyQuery(ctx, d.conn.pool, query, maxRows)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "execute_read_only_query", err)
+	}
+	return &adapter.QueryResult{Columns: columns, Rows: rows, Truncated: truncated}, nil
+}
+
 // ExecuteCountQuery executes a count query and returns the count.
 func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
 	// Use existing ExecuteCountQuery function
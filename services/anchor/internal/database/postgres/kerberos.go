@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/services/anchor/internal/database/kerberos"
+)
+
+// gssSeparator separates the service principal of a connection from the ID of its Kerberos
+// credentials in KerberosSpn. pgx registers a single GSS provider for the process, which only
+// learns the SPN of the connection it authenticates.
+const gssSeparator = "#"
+
+var registerGSS sync.Once
+
+// withKerberos authenticates the connections of cc with the Kerberos tickets of auth. Each new
+// connection of the pool gets a service ticket of the shared credentials, which renew the
+// ticket-granting ticket as needed.
+func withKerberos(cc *pgx.ConnConfig, auth adapter.KerberosAuth, username string) error {
+	credentials, err := kerberos.For(auth, username)
+	if err != nil {
+		return err
+	}
+	registerGSS.Do(func() {
+		pgconn.RegisterGSSProvider(func() (pgconn.GSS, error) {
+			return gss{}, nil
+		})
+	})
+
+	cc.KerberosSpn = auth.SPN + gssSeparator + credentials.ID
+	if cc.User == "" {
+		cc.User, _ = auth.ClientPrincipal(username)
+	}
+	return nil
+}
+
+// gss authenticates a connection with the Kerberos credentials named by its SPN
+type gss struct{}
+
+func (gss) GetInitToken(host, service string) ([]byte, error) {
+	return nil, fmt.Errorf("kerberos connections of the anchor name their service principal")
+}
+
+func (gss) GetInitTokenFromSPN(spn string) ([]byte, error) {
+	spn, id, ok := strings.Cut(spn, gssSeparator)
+	credentials, found := kerberos.ByID(id)
+	if !ok || !found {
+		return nil, fmt.Errorf("no kerberos credentials for %s", spn)
+	}
+	return credentials.InitToken(spn)
+}
+
+func (gss) Continue(inToken []byte) (bool, []byte, error) {
+	return true, nil, kerberos.CompleteContext(inToken)
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// CreateSandbox creates a schema holding empty copies of tables. The copies have the columns,
+// defaults, NOT NULL and CHECK constraints, and indexes (so primary keys and unique constraints)
+// of the tables; foreign keys are not copied. Serial and identity columns take their values from
+// sequences of the sandbox, so that writing into the copies does not advance the sequences of
+// the tables. The schema is created in a transaction, so nothing is left behind if a table
+// cannot be copied.
+func CreateSandbox(ctx context.Context, pool *pgxpool.Pool, sandbox string, tables []string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE SCHEMA %s", quoteIdentifier(sandbox))); err != nil {
+		return fmt.Errorf("error creating sandbox schema %s: %v", sandbox, err)
+	}
+	for _, table := range tables {
+		query := fmt.Sprintf("CREATE TABLE %s.%s (LIKE %s INCLUDING ALL EXCLUDING DEFAULTS EXCLUDING IDENTITY)",
+			quoteIdentifier(sandbox), quoteIdentifier(table), quoteIdentifier(table))
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return fmt.Errorf("error copying table %s into sandbox %s: %v", table, sandbox, err)
+		}
+		if err := copySandboxDefaults(ctx, tx, sandbox, table); err != nil {
+			return fmt.Errorf("error copying defaults of table %s into sandbox %s: %v", table, sandbox, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// copySandboxDefaults gives the columns of the copy of a table in a sandbox the defaults of the
+// table. Identity columns and columns defaulting to the next value of a sequence get sequences
+// owned by the copy in place of those of the table.
+func copySandboxDefaults(ctx context.Context, tx pgx.Tx, sandbox string, table string) error {
+	rows, err := tx.Query(ctx, `
+		SELECT a.attname, COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attidentity::text
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+			AND a.attgenerated = '' AND (d.adbin IS NOT NULL OR a.attidentity <> '')
+		ORDER BY a.attnum`, quoteIdentifier(table))
+	if err != nil {
+		return err
+	}
+	type columnDefault struct {
+		column, expression, identity string
+	}
+	var defaults []columnDefault
+	for rows.Next() {
+		var d columnDefault
+		if err := rows.Scan(&d.column, &d.expression, &d.identity); err != nil {
+			rows.Close()
+			return err
+		}
+		defaults = append(defaults, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	copyTable := quoteIdentifier(sandbox) + "." + quoteIdentifier(table)
+	for _, d := range defaults {
+		column := quoteIdentifier(d.column)
+		var statements []string
+		switch {
+		case d.identity == "a":
+			statements = []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ADD GENERATED ALWAYS AS IDENTITY", copyTable, column)}
+		case d.identity == "d":
+			statements = []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY", copyTable, column)}
+		case strings.Contains(d.expression, "nextval("):
+			sequence := quoteIdentifier(sandbox) + "." + quoteIdentifier(table+"_"+d.column+"_seq")
+			statements = []string{
+				fmt.Sprintf("CREATE SEQUENCE %s OWNED BY %s.%s", sequence, copyTable, column),
+				fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval('%s')", copyTable, column, strings.ReplaceAll(sequence, "'", "''")),
+			}
+		default:
+			statements = []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", copyTable, column, d.expression)}
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("column %s: %v", d.column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DropStaleSandboxes drops the sandboxes of copy runs older than adapter.StaleSandboxAge, left
+// behind when core or the anchor stopped before the run could drop them. It returns the
+// sandboxes dropped.
+func DropStaleSandboxes(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	rows, err := pool.Query(ctx, "SELECT nspname FROM pg_namespace WHERE left(nspname, length($1)) = $1", adapter.SandboxPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sandbox schemas: %v", err)
+	}
+	schemas, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("error listing sandbox schemas: %v", err)
+	}
+
+	now := time.Now()
+	var dropped []string
+	for _, schema := range schemas {
+		if !adapter.IsStaleSandbox(schema, now) {
+			continue
+		}
+		if err := DropSandbox(ctx, pool, schema); err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, schema)
+	}
+	return dropped, nil
+}
+
+// InsertSandboxData loads rows into the copy of a table in a sandbox with COPY, which inserts
+// all rows or none.
+func InsertSandboxData(ctx context.Context, pool *pgxpool.Pool, sandbox string, tableName string, columns []string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	rows := make([][]interface{}, len(data))
+	for i, row := range data {
+		values := make([]interface{}, len(columns))
+		for j, col := range columns {
+			values[j] = row[col]
+		}
+		rows[i] = values
+	}
+
+	count, err := pool.CopyFrom(ctx, pgx.Identifier{sandbox, tableName}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return 0, fmt.Errorf("error copying data into table %s of sandbox %s: %v", tableName, sandbox, err)
+	}
+	return count, nil
+}
+
+// FetchSandboxData returns at most limit rows of the copy of a table in a sandbox
+func FetchSandboxData(ctx context.Context, pool *pgxpool.Pool, sandbox string, tableName string, limit int) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf("SELECT to_jsonb(t) FROM %s.%s AS t",
+		quoteIdentifier(sandbox), quoteIdentifier(tableName))
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table %s of sandbox %s: %v", tableName, sandbox, err)
+	}
+	defer rows.Close()
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		var row map[string]interface{}
+		if err := rows.Scan(&row); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// DropSandbox drops a sandbox schema and the tables in it
+func DropSandbox(ctx context.Context, pool *pgxpool.Pool, sandbox string) error {
+	if _, err := pool.Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", quoteIdentifier(sandbox))); err != nil {
+		return fmt.Errorf("error dropping sandbox schema %s: %v", sandbox, err)
+	}
+	return nil
+}
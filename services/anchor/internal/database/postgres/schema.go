@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"github.com/redbco/redb-open/pkg/unifiedmodel"
 
@@ -16,8 +18,72 @@ import (
 
 // DiscoverSchema fetches the current schema of a PostgreSQL database and returns a UnifiedModel
 func DiscoverSchema(pool *pgxpool.Pool) (*unifiedmodel.UnifiedModel, error) {
-	// Create the unified model
-	um := &unifiedmodel.UnifiedModel{
+	// Read the catalog versions first, so that a table changed during discovery is re-read
+	// by the next incremental discovery
+	versions, err := fetchTableCatalogVersions(pool)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching table catalog versions: %v", err)
+	}
+
+	um := newDiscoveredModel()
+
+	// Get tables and their columns directly as UnifiedModel types
+	err = discoverTablesAndColumnsUnified(pool, um, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error discovering tables: %v", err)
+	}
+	recordTableCatalogVersions(um, versions)
+
+	if err := discoverNonTableObjectsUnified(pool, um); err != nil {
+		return nil, err
+	}
+	return um, nil
+}
+
+// DiscoverSchemaIncremental fetches the current schema of a PostgreSQL database, reusing the
+// tables of a previously discovered schema whose catalog version did not change. Only new and
+// changed tables are re-read from the catalogs; the other objects are always re-read, as they
+// are few compared to tables.
+func DiscoverSchemaIncremental(pool *pgxpool.Pool, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	versions, err := fetchTableCatalogVersions(pool)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching table catalog versions: %v", err)
+	}
+
+	um := newDiscoveredModel()
+
+	var changed []string
+	for tableName, version := range versions {
+		path := unifiedmodel.ObjectPath(unifiedmodel.ObjectTypeTable, tableName)
+		table, ok := previous.Tables[tableName]
+		provenance, hasProvenance := previous.Provenance[path]
+		if ok && hasProvenance && provenance.SourceIdentifiers[adapter.CatalogVersionIdentifier] == version.catalogVersion {
+			um.Tables[tableName] = table
+			if err := um.SetProvenance(path, provenance); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		changed = append(changed, tableName)
+	}
+
+	if len(changed) > 0 {
+		err = discoverTablesAndColumnsUnified(pool, um, changed)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering tables: %v", err)
+		}
+	}
+	recordTableCatalogVersions(um, versions)
+
+	if err := discoverNonTableObjectsUnified(pool, um); err != nil {
+		return nil, err
+	}
+	return um, nil
+}
+
+// newDiscoveredModel returns an empty UnifiedModel for discovery into
+func newDiscoveredModel() *unifiedmodel.UnifiedModel {
+	return &unifiedmodel.UnifiedModel{
 		DatabaseType: dbcapabilities.PostgreSQL,
 		Tables:       make(map[string]unifiedmodel.Table),
 		Schemas:      make(map[string]unifiedmodel.Schema),
@@ -27,52 +93,47 @@ func DiscoverSchema(pool *pgxpool.Pool) (*unifiedmodel.UnifiedModel, error) {
 		Sequences:    make(map[string]unifiedmodel.Sequence),
 		Extensions:   make(map[string]unifiedmodel.Extension),
 	}
+}
 
-	var err error
-
-	// Get tables and their columns directly as UnifiedModel types
-	err = discoverTablesAndColumnsUnified(pool, um)
-	if err != nil {
-		return nil, fmt.Errorf("error discovering tables: %v", err)
-	}
-
+// discoverNonTableObjectsUnified discovers the objects of the database other than tables
+func discoverNonTableObjectsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
 	// Get enum types directly as UnifiedModel types
-	err = discoverEnumTypesUnified(pool, um)
+	err := discoverEnumTypesUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error discovering enum types: %v", err)
+		return fmt.Errorf("error discovering enum types: %v", err)
 	}
 
 	// Get schemas directly as UnifiedModel types
 	err = getSchemasUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error getting schemas: %v", err)
+		return fmt.Errorf("error getting schemas: %v", err)
 	}
 
 	// Get functions directly as UnifiedModel types
 	err = getFunctionsUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error getting functions: %v", err)
+		return fmt.Errorf("error getting functions: %v", err)
 	}
 
 	// Get triggers directly as UnifiedModel types
 	err = getTriggersUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error getting triggers: %v", err)
+		return fmt.Errorf("error getting triggers: %v", err)
 	}
 
 	// Get sequences directly as UnifiedModel types
 	err = getSequencesUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error getting sequences: %v", err)
+		return fmt.Errorf("error getting sequences: %v", err)
 	}
 
 	// Get extensions directly as UnifiedModel types
 	err = getExtensionsUnified(pool, um)
 	if err != nil {
-		return nil, fmt.Errorf("error getting extensions: %v", err)
+		return fmt.Errorf("error getting extensions: %v", err)
 	}
 
-	return um, nil
+	return nil
 }
 
 // CreateStructure creates database objects from a UnifiedModel
@@ -81,6 +142,13 @@ func CreateStructure(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
 		return fmt.Errorf("unified model cannot be nil")
 	}
 
+	// Look up the features of the server version, so no DDL it rejects is generated
+	var version string
+	if err := pool.QueryRow(context.Background(), "SHOW server_version").Scan(&version); err != nil {
+		return fmt.Errorf("error fetching PostgreSQL version: %v", err)
+	}
+	caps, _ := dbcapabilities.GetForVersion(dbcapabilities.PostgreSQL, version)
+
 	// Start a transaction
 	tx, err := pool.Begin(context.Background())
 	if err != nil {
@@ -126,7 +194,7 @@ func CreateStructure(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
 
 	// Create tables
 	for _, table := range sortedTables {
-		if err := CreateTableFromUnified(tx, table, um.Types); err != nil {
+		if err := CreateTableFromUnified(tx, table, um.Types, caps); err != nil {
 			return fmt.Errorf("error creating table %s: %v", table.Name, err)
 		}
 	}
@@ -223,8 +291,9 @@ func createSchema(tx pgx.Tx, schemaName string) error {
 	return nil
 }
 
-// CreateTableFromUnified creates a table from UnifiedModel Table
-func CreateTableFromUnified(tx pgx.Tx, table unifiedmodel.Table, types map[string]unifiedmodel.Type) error {
+// CreateTableFromUnified creates a table from UnifiedModel Table, with the column types the
+// server version supports
+func CreateTableFromUnified(tx pgx.Tx, table unifiedmodel.Table, types map[string]unifiedmodel.Type, caps dbcapabilities.VersionedCapability) error {
 	if tx == nil {
 		return fmt.Errorf("transaction is nil")
 	}
@@ -255,7 +324,7 @@ func CreateTableFromUnified(tx pgx.Tx, table unifiedmodel.Table, types map[strin
 		createTableSQL += fmt.Sprintf("%s ", column.Name)
 
 		// Handle data type
-		createTableSQL += mapUnifiedDataTypeToPostgres(column.DataType)
+		createTableSQL += postgresTypeForVersion(mapUnifiedDataTypeToPostgres(column.DataType), caps)
 
 		if !column.Nullable {
 			createTableSQL += " NOT NULL"
@@ -428,6 +497,25 @@ func createExtension(tx pgx.Tx, extension unifiedmodel.Extension) error {
 	return nil
 }
 
+// postgresTypeForVersion replaces JSON types the server version does not support with the
+// closest type it supports
+func postgresTypeForVersion(dataType string, caps dbcapabilities.VersionedCapability) string {
+	switch dataType {
+	case "JSONB":
+		if caps.Supports(dbcapabilities.FeatureJSONBType) {
+			return dataType
+		}
+		return postgresTypeForVersion("JSON", caps)
+	case "JSON":
+		if caps.Supports(dbcapabilities.FeatureJSONType) {
+			return dataType
+		}
+		return "TEXT"
+	default:
+		return dataType
+	}
+}
+
 // mapUnifiedDataTypeToPostgres maps UnifiedModel data types to PostgreSQL types
 func mapUnifiedDataTypeToPostgres(dataType string) string {
 	switch strings.ToLower(dataType) {
@@ -474,8 +562,9 @@ func isPrimaryKeyIndex(indexName, tableName string) bool {
 	return indexName == tableName+"_pkey" || strings.HasSuffix(indexName, "_pkey")
 }
 
-// discoverTablesAndColumnsUnified discovers tables and columns directly into UnifiedModel
-func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
+// discoverTablesAndColumnsUnified discovers tables and columns directly into UnifiedModel.
+// Only the tables of tableNames are discovered, or all tables if tableNames is nil.
+func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel, tableNames []string) error {
 	query := `
         SELECT 
             t.table_schema,
@@ -576,17 +665,20 @@ func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.Unifie
             t.table_schema = 'public' AND
             c.table_schema = 'public' AND
             a.attnum > 0 AND
-            t.table_type IN ('BASE TABLE', 'LOCAL TEMPORARY')
+            t.table_type IN ('BASE TABLE', 'LOCAL TEMPORARY') AND
+            ($1::text[] IS NULL OR t.table_name = ANY($1))
         ORDER BY 
             t.table_name, c.ordinal_position
     `
 
-	rows, err := pool.Query(context.Background(), query)
+	rows, err := pool.Query(context.Background(), query, tableNames)
 	if err != nil {
 		return fmt.Errorf("error fetching table and column information: %v", err)
 	}
 	defer rows.Close()
 
+	discovered := make(map[string]bool)
+
 	for rows.Next() {
 		var schemaName, tableName, columnName, dataType, isNullable string
 		var ordinalPosition int
@@ -645,16 +737,17 @@ func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.Unifie
 
 		table.Columns[columnName] = column
 		um.Tables[tableName] = table
+		discovered[tableName] = true
 	}
 
 	// Get indexes for all tables
-	err = discoverIndexesUnified(pool, um)
+	err = discoverIndexesUnified(pool, um, tableNames)
 	if err != nil {
 		return fmt.Errorf("error discovering indexes: %v", err)
 	}
 
 	// Get constraints for all tables
-	err = discoverConstraintsUnified(pool, um)
+	err = discoverConstraintsUnified(pool, um, tableNames)
 	if err != nil {
 		return fmt.Errorf("error discovering constraints: %v", err)
 	}
@@ -662,7 +755,8 @@ func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.Unifie
 	// Handle partitioning info for partitioned tables
 	// Note: We'll need to track table types during discovery to handle partitioning
 	// For now, we'll check all tables for partitioning info
-	for tableName, table := range um.Tables {
+	for tableName := range discovered {
+		table := um.Tables[tableName]
 		// Check if table has partitioning info by querying directly
 		err := fetchPartitioningInfoUnified(pool, tableName, &table)
 		if err != nil {
@@ -677,8 +771,84 @@ func discoverTablesAndColumnsUnified(pool *pgxpool.Pool, um *unifiedmodel.Unifie
 	return nil
 }
 
-// discoverIndexesUnified discovers indexes directly into UnifiedModel
-func discoverIndexesUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
+// tableCatalogVersion identifies a table in the catalogs and the version of its catalog entries
+type tableCatalogVersion struct {
+	oid            string
+	catalogVersion string
+}
+
+// fetchTableCatalogVersions returns the catalog versions of the tables, keyed by table name.
+// The version of a table is a digest of the transaction IDs (xmin) of the catalog rows
+// describing it: its pg_class row and those of its columns, defaults, indexes, constraints and
+// parents. Any DDL on the table rewrites one of these rows, and so changes the version.
+func fetchTableCatalogVersions(pool *pgxpool.Pool) (map[string]tableCatalogVersion, error) {
+	query := `
+		SELECT
+			c.relname,
+			c.oid::text,
+			md5(concat_ws('/',
+				c.xmin::text,
+				(SELECT string_agg(a.attnum || ':' || a.xmin::text, ',' ORDER BY a.attnum)
+				 FROM pg_attribute a WHERE a.attrelid = c.oid),
+				(SELECT string_agg(d.oid::text || ':' || d.xmin::text, ',' ORDER BY d.oid)
+				 FROM pg_attrdef d WHERE d.adrelid = c.oid),
+				(SELECT string_agg(i.indexrelid::text || ':' || i.xmin::text || ':' || ic.xmin::text, ',' ORDER BY i.indexrelid)
+				 FROM pg_index i JOIN pg_class ic ON ic.oid = i.indexrelid WHERE i.indrelid = c.oid),
+				(SELECT string_agg(co.oid::text || ':' || co.xmin::text, ',' ORDER BY co.oid)
+				 FROM pg_constraint co WHERE co.conrelid = c.oid),
+				(SELECT string_agg(inh.inhparent::text || ':' || inh.xmin::text, ',' ORDER BY inh.inhparent)
+				 FROM pg_inherits inh WHERE inh.inhrelid = c.oid)
+			)) AS catalog_version
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public'
+		AND c.relkind IN ('r', 'p')
+	`
+
+	rows, err := pool.Query(context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying table catalog versions: %v", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]tableCatalogVersion)
+	for rows.Next() {
+		var tableName string
+		var version tableCatalogVersion
+		if err := rows.Scan(&tableName, &version.oid, &version.catalogVersion); err != nil {
+			return nil, fmt.Errorf("error scanning table catalog version row: %v", err)
+		}
+		versions[tableName] = version
+	}
+	return versions, rows.Err()
+}
+
+// recordTableCatalogVersions records the catalog versions of the tables of the model in its
+// provenance. Tables already holding provenance are left as they are.
+func recordTableCatalogVersions(um *unifiedmodel.UnifiedModel, versions map[string]tableCatalogVersion) {
+	now := time.Now()
+	for tableName := range um.Tables {
+		path := unifiedmodel.ObjectPath(unifiedmodel.ObjectTypeTable, tableName)
+		if _, ok := um.Provenance[path]; ok {
+			continue
+		}
+		version, ok := versions[tableName]
+		if !ok {
+			continue
+		}
+		um.SetProvenance(path, unifiedmodel.Provenance{
+			SourceIdentifiers: map[string]string{
+				"oid":                            version.oid,
+				adapter.CatalogVersionIdentifier: version.catalogVersion,
+			},
+			DiscoveredAt: now,
+		})
+	}
+}
+
+// discoverIndexesUnified discovers indexes directly into UnifiedModel, for the tables of
+// tableNames or all tables if tableNames is nil
+func discoverIndexesUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel, tableNames []string) error {
 	query := `
 		SELECT 
 			schemaname,
@@ -687,10 +857,11 @@ func discoverIndexesUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) e
 			indexdef
 		FROM pg_indexes 
 		WHERE schemaname = 'public'
+		AND ($1::text[] IS NULL OR tablename = ANY($1))
 		ORDER BY tablename, indexname
 	`
 
-	rows, err := pool.Query(context.Background(), query)
+	rows, err := pool.Query(context.Background(), query, tableNames)
 	if err != nil {
 		return fmt.Errorf("error querying indexes: %v", err)
 	}
@@ -736,8 +907,9 @@ func discoverIndexesUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) e
 	return nil
 }
 
-// discoverConstraintsUnified discovers constraints directly into UnifiedModel
-func discoverConstraintsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel) error {
+// discoverConstraintsUnified discovers constraints directly into UnifiedModel, for the
+// tables of tableNames or all tables if tableNames is nil
+func discoverConstraintsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedModel, tableNames []string) error {
 	query := `
 		SELECT 
 			tc.table_name,
@@ -748,10 +920,11 @@ func discoverConstraintsUnified(pool *pgxpool.Pool, um *unifiedmodel.UnifiedMode
 		JOIN pg_constraint pgc ON pgc.conname = tc.constraint_name
 		WHERE tc.table_schema = 'public'
 		AND tc.constraint_type IN ('FOREIGN KEY', 'CHECK', 'UNIQUE')
+		AND ($1::text[] IS NULL OR tc.table_name = ANY($1))
 		ORDER BY tc.table_name, tc.constraint_name
 	`
 
-	rows, err := pool.Query(context.Background(), query)
+	rows, err := pool.Query(context.Background(), query, tableNames)
 	if err != nil {
 		return fmt.Errorf("error querying constraints: %v", err)
 	}
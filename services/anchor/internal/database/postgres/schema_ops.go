@@ -23,6 +23,16 @@ func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedMo
 	return um, nil
 }
 
+// DiscoverSchemaIncremental retrieves the schema of the PostgreSQL database, re-reading only
+// the tables whose catalog entries changed since previous was discovered.
+func (s *SchemaOps) DiscoverSchemaIncremental(ctx context.Context, previous *unifiedmodel.UnifiedModel) (*unifiedmodel.UnifiedModel, error) {
+	um, err := DiscoverSchemaIncremental(s.conn.pool, previous)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.PostgreSQL, "discover_schema_incremental", err)
+	}
+	return um, nil
+}
+
 // CreateStructure creates database objects from a UnifiedModel.
 func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
 	// Use existing CreateStructure function
@@ -303,3 +303,27 @@ func TestDataTypeMappingComprehensive(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresTypeForVersion(t *testing.T) {
+	testCases := []struct {
+		version      string
+		dataType     string
+		expectedType string
+	}{
+		{"16.2", "JSONB", "JSONB"},
+		{"9.4.26", "JSONB", "JSONB"},
+		{"9.3.25", "JSONB", "JSON"},
+		{"9.1.24", "JSONB", "TEXT"},
+		{"9.1.24", "JSON", "TEXT"},
+		{"9.1.24", "INTEGER", "INTEGER"},
+		{"", "JSONB", "JSONB"}, // Unknown versions are treated as the latest one
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version+"/"+tc.dataType, func(t *testing.T) {
+			caps, ok := dbcapabilities.GetForVersion(dbcapabilities.PostgreSQL, tc.version)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedType, postgresTypeForVersion(tc.dataType, caps))
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Package restclient provides the HTTP plumbing shared by adapters for SaaS APIs:
+// OAuth token refresh, client-side rate limiting and retries on throttling responses.
+package restclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource holds an OAuth access token and refreshes it when a refresh token is available.
+type TokenSource struct {
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	expiry       time.Time
+	httpClient   *http.Client
+}
+
+// NewTokenSource creates a token source. With an empty refreshToken the access token is used as-is,
+// which also covers static API keys and personal access tokens.
+func NewTokenSource(accessToken, refreshToken, clientID, clientSecret, tokenURL string) *TokenSource {
+	return &TokenSource{
+		accessToken:  accessToken,
+		refreshToken: refreshToken,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Token returns a valid access token, refreshing it if it expired
+func (t *TokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.refreshToken == "" {
+		if t.accessToken == "" {
+			return "", fmt.Errorf("no access token or refresh token configured")
+		}
+		return t.accessToken, nil
+	}
+	if t.accessToken != "" && !t.expiry.IsZero() && time.Now().Before(t.expiry.Add(-time.Minute)) {
+		return t.accessToken, nil
+	}
+	if err := t.refresh(ctx); err != nil {
+		return "", err
+	}
+	return t.accessToken, nil
+}
+
+// Invalidate forces a refresh on the next call, used after a 401 response
+func (t *TokenSource) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.refreshToken != "" {
+		t.expiry = time.Time{}
+		t.accessToken = ""
+	}
+}
+
+func (t *TokenSource) refresh(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {t.refreshToken},
+	}
+	if t.clientID != "" {
+		form.Set("client_id", t.clientID)
+	}
+	if t.clientSecret != "" {
+		form.Set("client_secret", t.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("token response did not contain an access token")
+	}
+
+	t.accessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		// Some providers rotate refresh tokens on every use
+		t.refreshToken = token.RefreshToken
+	}
+	if token.ExpiresIn > 0 {
+		t.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	} else {
+		t.expiry = time.Now().Add(time.Hour)
+	}
+	return nil
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Client is a rate-limited JSON HTTP client
+type Client struct {
+	BaseURL    string
+	Tokens     *TokenSource
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries for throttled (429) and unavailable (5xx) responses
+	MaxRetries int
+
+	mu          sync.Mutex
+	interval    time.Duration
+	nextRequest time.Time
+}
+
+// NewClient creates a client that issues at most requestsPerSecond requests per second
+func NewClient(baseURL string, tokens *TokenSource, requestsPerSecond float64) *Client {
+	c := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Tokens:     tokens,
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+		MaxRetries: 5,
+	}
+	if requestsPerSecond > 0 {
+		c.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return c
+}
+
+// wait blocks until the rate limiter allows the next request
+func (c *Client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	now := time.Now()
+	start := c.nextRequest
+	if start.Before(now) {
+		start = now
+	}
+	c.nextRequest = start.Add(c.interval)
+	c.mu.Unlock()
+
+	return sleep(ctx, time.Until(start))
+}
+
+// backoff pushes all further requests back, used when the server reports throttling
+func (c *Client) backoff(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until := time.Now().Add(d); until.After(c.nextRequest) {
+		c.nextRequest = until
+	}
+}
+
+// Do sends a JSON request and decodes the JSON response into out (if non-nil).
+// path may be absolute or relative to BaseURL.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	respBody, _, err := c.DoRaw(ctx, method, path, query, body, nil)
+	if err != nil {
+		return err
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// DoRaw sends a JSON request and returns the undecoded response body and headers,
+// for endpoints answering with CSV or paging through response headers. headers are
+// added to the request and override the JSON defaults.
+func (c *Client) DoRaw(ctx context.Context, method, path string, query url.Values, body interface{}, headers http.Header) ([]byte, http.Header, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = c.BaseURL + path
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	delay := time.Second
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		if err := c.wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+		if err != nil {
+			return nil, nil, err
+		}
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		for k, v := range headers {
+			req.Header[http.CanonicalHeaderKey(k)] = v
+		}
+		if c.Tokens != nil {
+			token, err := c.Tokens.Token(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if attempt < c.MaxRetries && ctx.Err() == nil {
+				if err := sleep(ctx, delay); err != nil {
+					return nil, nil, err
+				}
+				delay *= 2
+				continue
+			}
+			return nil, nil, err
+		}
+
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("failed to read response: %w", readErr)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && c.Tokens != nil && !refreshed:
+			// The access token may have been revoked or expired early
+			c.Tokens.Invalidate()
+			refreshed = true
+			continue
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500) && attempt < c.MaxRetries:
+			wait := retryAfter(resp.Header.Get("Retry-After"), delay)
+			c.backoff(wait)
+			delay *= 2
+			continue
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			return nil, nil, &APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+		}
+
+		return respBody, resp.Header, nil
+	}
+}
+
+// retryAfter parses a Retry-After header in seconds, falling back to def
+func retryAfter(header string, def time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return def
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
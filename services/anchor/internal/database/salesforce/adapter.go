@@ -0,0 +1,242 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// Adapter implements adapter.DatabaseAdapter for Salesforce.
+// Host is the org's instance URL (for example acme.my.salesforce.com) and every sObject
+// is exposed as a collection.
+//
+// The token is read from Token (or Password) and is an OAuth access token obtained through
+// the integration service. It is refreshed automatically when options.refresh_token,
+// options.client_id and options.client_secret are set.
+//
+// Options:
+//   - api_version: REST API version, defaults to 61.0
+//   - objects: comma-separated sObjects to expose; defaults to the common CRM objects and all custom objects
+//   - token_url: OAuth token endpoint, defaults to the production login endpoint
+//   - requests_per_second: client-side request rate limit
+type Adapter struct{}
+
+// NewAdapter creates a new Salesforce adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.Salesforce
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.Salesforce)
+}
+
+// Connect verifies access to the org.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	if config.Host == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Salesforce, "host", "instance URL is required")
+	}
+	instanceURL := strings.TrimRight(config.Host, "/")
+	if !strings.HasPrefix(instanceURL, "http://") && !strings.HasPrefix(instanceURL, "https://") {
+		instanceURL = "https://" + instanceURL
+	}
+
+	accessToken := config.Token
+	if accessToken == "" {
+		accessToken = config.Password
+	}
+	refreshToken := optionString(config, "refresh_token")
+	if accessToken == "" && refreshToken == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.Salesforce, "token", "an OAuth access token or refresh token is required")
+	}
+
+	tokenURL := optionString(config, "token_url")
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+	tokens := restclient.NewTokenSource(accessToken, refreshToken,
+		optionString(config, "client_id"), optionString(config, "client_secret"), tokenURL)
+
+	apiVersion := strings.TrimPrefix(optionString(config, "api_version"), "v")
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+
+	client := &Client{
+		api:         restclient.NewClient(instanceURL, tokens, optionFloat(config, "requests_per_second", defaultRequestsPerSecond)),
+		tokens:      tokens,
+		instanceURL: instanceURL,
+		apiVersion:  apiVersion,
+	}
+
+	org, err := client.organization(ctx)
+	if err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.Salesforce, config.Host, config.Port, err)
+	}
+	orgID, _ := org["Id"].(string)
+
+	return &Connection{
+		id:        config.DatabaseID,
+		client:    client,
+		orgID:     orgID,
+		objects:   splitList(optionString(config, "objects")),
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance is not supported; each org is connected as a database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "instance connection", "orgs are connected as individual databases")
+}
+
+func optionString(config adapter.ConnectionConfig, key string) string {
+	if v, ok := config.Options[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func optionFloat(config adapter.ConnectionConfig, key string, def float64) float64 {
+	switch v := config.Options[key].(type) {
+	case float64:
+		if v > 0 {
+			return v
+		}
+	case int:
+		if v > 0 {
+			return float64(v)
+		}
+	}
+	return def
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// bulkCursor resumes reading the results of a Bulk API query job
+type bulkCursor struct {
+	jobID   string
+	locator string
+}
+
+// Connection implements adapter.Connection for a Salesforce org.
+type Connection struct {
+	id        string
+	client    *Client
+	orgID     string
+	objects   []string
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+
+	// cursorsMu guards cursors, the bulk query result cursors keyed by object and stream offset
+	cursorsMu sync.Mutex
+	cursors   map[string]bulkCursor
+}
+
+// streamCursor returns the bulk cursor that resumes a stream at offset
+func (c *Connection) streamCursor(object string, offset int64) (bulkCursor, bool) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	cursor, ok := c.cursors[fmt.Sprintf("%s:%d", object, offset)]
+	return cursor, ok
+}
+
+// setStreamCursor remembers the bulk cursor that resumes a stream at offset
+func (c *Connection) setStreamCursor(object string, offset int64, cursor bulkCursor) {
+	c.cursorsMu.Lock()
+	defer c.cursorsMu.Unlock()
+	if c.cursors == nil {
+		c.cursors = make(map[string]bulkCursor)
+	}
+	c.cursors[fmt.Sprintf("%s:%d", object, offset)] = cursor
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.Salesforce
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks that the org is still accessible.
+func (c *Connection) Ping(ctx context.Context) error {
+	if !c.IsConnected() {
+		return adapter.ErrConnectionClosed
+	}
+	if _, err := c.client.limits(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.Salesforce, "ping", err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Connection) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.connected, 1, 0) {
+		return adapter.ErrConnectionClosed
+	}
+	return nil
+}
+
+// SchemaOperations returns the schema operator.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return &ReplicationOps{conn: c}
+}
+
+// MetadataOperations returns the metadata operator.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the Salesforce API client.
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
@@ -0,0 +1,287 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"google.golang.org/grpc"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+)
+
+// ParseEvent converts a Change Data Capture event to a standardized CDCEvent.
+// GAP events, published when Salesforce could not capture the changed values, carry only
+// the record ID and are flagged with the gap metadata entry.
+func (r *ReplicationOps) ParseEvent(ctx context.Context, rawEvent map[string]interface{}) (*adapter.CDCEvent, error) {
+	changeType, _ := rawEvent["change_type"].(string)
+	tableName, _ := rawEvent["table_name"].(string)
+	recordID, _ := rawEvent["record_id"].(string)
+	if changeType == "" || tableName == "" || recordID == "" {
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.Salesforce,
+			"parse_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("error", "change_type, table_name and record_id are required")
+	}
+
+	event := &adapter.CDCEvent{
+		TableName: tableName,
+		Timestamp: time.Now(),
+		Metadata: map[string]interface{}{
+			"change_type": changeType,
+			"channel":     rawEvent["channel"],
+		},
+	}
+	if ms, ok := rawEvent["commit_timestamp"].(float64); ok {
+		event.Timestamp = time.UnixMilli(int64(ms))
+	}
+	if key, ok := rawEvent["transaction_key"].(string); ok {
+		event.TransactionID = key
+	}
+	if replayID, ok := rawEvent["replay_id"].(int64); ok {
+		event.LSN = strconv.FormatInt(replayID, 10)
+	}
+	if fields, ok := rawEvent["changed_fields"]; ok && fields != nil {
+		event.Metadata["changed_fields"] = fields
+	}
+
+	data := make(map[string]interface{})
+	if payload, ok := rawEvent["data"].(map[string]interface{}); ok {
+		for k, v := range payload {
+			data[k] = v
+		}
+	}
+	data["Id"] = recordID
+
+	gap := strings.HasPrefix(changeType, "GAP_")
+	if gap {
+		event.Metadata["gap"] = true
+	}
+
+	switch strings.TrimPrefix(changeType, "GAP_") {
+	case "CREATE", "UNDELETE":
+		event.Operation = adapter.CDCInsert
+		event.Data = data
+	case "UPDATE":
+		event.Operation = adapter.CDCUpdate
+		event.Data = data
+		event.OldData = map[string]interface{}{"Id": recordID}
+	case "DELETE":
+		event.Operation = adapter.CDCDelete
+		event.OldData = map[string]interface{}{"Id": recordID}
+	default:
+		// GAP_OVERFLOW and unknown change types cannot be applied record by record
+		return nil, adapter.NewDatabaseError(
+			dbcapabilities.Salesforce,
+			"parse_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("change_type", changeType)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "parse_cdc_event", err)
+	}
+	return event, nil
+}
+
+// ApplyCDCEvent applies a standardized CDC event to Salesforce through the sObject Collections API.
+// Updates and deletes identify the record by the Id field of the event.
+func (r *ReplicationOps) ApplyCDCEvent(ctx context.Context, event *adapter.CDCEvent) error {
+	if err := event.Validate(); err != nil {
+		return adapter.WrapError(dbcapabilities.Salesforce, "apply_cdc_event", err)
+	}
+
+	var results []saveResult
+	var err error
+	switch event.Operation {
+	case adapter.CDCInsert:
+		results, err = r.conn.client.createRecords(ctx, event.TableName, []map[string]interface{}{r.recordFields(event.Data, "Id")})
+	case adapter.CDCUpdate:
+		id := recordID(event)
+		if id == "" {
+			return adapter.NewDatabaseError(dbcapabilities.Salesforce, "apply_cdc_update", adapter.ErrInvalidData).
+				WithContext("error", "no Id to identify the record for UPDATE")
+		}
+		record := r.recordFields(event.Data)
+		record["Id"] = id
+		results, err = r.conn.client.updateRecords(ctx, event.TableName, []map[string]interface{}{record})
+	case adapter.CDCDelete:
+		id := recordID(event)
+		if id == "" {
+			return adapter.NewDatabaseError(dbcapabilities.Salesforce, "apply_cdc_delete", adapter.ErrInvalidData).
+				WithContext("error", "no Id to identify the record for DELETE")
+		}
+		results, err = r.conn.client.deleteRecords(ctx, []string{id})
+	default:
+		return adapter.NewDatabaseError(
+			dbcapabilities.Salesforce,
+			"apply_cdc_event",
+			adapter.ErrInvalidData,
+		).WithContext("operation", string(event.Operation))
+	}
+
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.Salesforce, "apply_cdc_event", err)
+	}
+	for _, res := range results {
+		if resErr := res.err(); resErr != nil {
+			return adapter.WrapError(dbcapabilities.Salesforce, "apply_cdc_event", resErr)
+		}
+	}
+	return nil
+}
+
+// recordID returns the Salesforce record ID of an event
+func recordID(event *adapter.CDCEvent) string {
+	if id, ok := event.OldData["Id"].(string); ok && id != "" {
+		return id
+	}
+	id, _ := event.Data["Id"].(string)
+	return id
+}
+
+// recordFields returns the event fields to write, without metadata and system fields
+func (r *ReplicationOps) recordFields(data map[string]interface{}, exclude ...string) map[string]interface{} {
+	record := writableFields(data, exclude...)
+	for k := range record {
+		if r.isMetadataField(k) {
+			delete(record, k)
+		}
+	}
+	return record
+}
+
+// TransformData applies transformation rules to event data.
+func (r *ReplicationOps) TransformData(ctx context.Context, data map[string]interface{}, rules []adapter.TransformationRule, transformationServiceEndpoint string) (map[string]interface{}, error) {
+	if len(rules) == 0 {
+		return data, nil
+	}
+
+	transformedData := make(map[string]interface{})
+
+	// Create transformation service client if endpoint is provided
+	var transformClient transformationv1.TransformationServiceClient
+	var grpcConn *grpc.ClientConn
+	if transformationServiceEndpoint != "" {
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
+		if err == nil {
+			transformClient = transformationv1.NewTransformationServiceClient(conn)
+			grpcConn = conn
+			defer conn.Close()
+		}
+	}
+
+	// Apply each transformation rule
+	for _, rule := range rules {
+		sourceValue, exists := data[rule.SourceColumn]
+		if !exists {
+			continue
+		}
+
+		var transformedValue interface{}
+		var err error
+
+		if rule.TransformationName != "" && rule.TransformationName != "direct_mapping" && grpcConn != nil {
+			transformedValue, err = callTransformationService(ctx, transformClient, rule.TransformationName, sourceValue)
+			if err != nil {
+				transformedValue = sourceValue
+			}
+		} else {
+			transformType := rule.TransformationType
+			if transformType == "" && rule.TransformationName != "" {
+				transformType = rule.TransformationName
+			}
+
+			switch transformType {
+			case adapter.TransformDirect, "direct_mapping":
+				transformedValue = sourceValue
+			case adapter.TransformUppercase:
+				if str, ok := sourceValue.(string); ok {
+					transformedValue = strings.ToUpper(str)
+				} else {
+					transformedValue = sourceValue
+				}
+			case adapter.TransformLowercase:
+				if str, ok := sourceValue.(string); ok {
+					transformedValue = strings.ToLower(str)
+				} else {
+					transformedValue = sourceValue
+				}
+			case adapter.TransformCast:
+				transformedValue = sourceValue
+			case adapter.TransformDefault:
+				if sourceValue == nil {
+					if defaultVal, ok := rule.Parameters["default_value"]; ok {
+						transformedValue = defaultVal
+					} else {
+						transformedValue = nil
+					}
+				} else {
+					transformedValue = sourceValue
+				}
+			default:
+				transformedValue = sourceValue
+			}
+		}
+
+		transformedData[rule.TargetColumn] = transformedValue
+	}
+
+	return transformedData, nil
+}
+
+// callTransformationService calls the transformation service to apply a custom transformation.
+func callTransformationService(ctx context.Context, client transformationv1.TransformationServiceClient, transformationName string, value interface{}) (interface{}, error) {
+	var inputStr string
+	switch v := value.(type) {
+	case string:
+		inputStr = v
+	case nil:
+		return nil, nil
+	default:
+		inputStr = fmt.Sprintf("%v", v)
+	}
+
+	transformReq := &transformationv1.TransformRequest{
+		FunctionName: transformationName,
+		Input:        inputStr,
+	}
+
+	transformResp, err := client.Transform(ctx, transformReq)
+	if err != nil {
+		return nil, fmt.Errorf("transformation service error: %v", err)
+	}
+
+	if transformResp.Status != commonv1.Status_STATUS_SUCCESS {
+		return nil, fmt.Errorf("transformation failed: %s", transformResp.StatusMessage)
+	}
+
+	return transformResp.Output, nil
+}
+
+// isMetadataField checks if a field name is a metadata field or a system field Salesforce sets itself.
+func (r *ReplicationOps) isMetadataField(fieldName string) bool {
+	metadataFields := map[string]bool{
+		"message_type":     true,
+		"database_id":      true,
+		"timestamp":        true,
+		"operation":        true,
+		"table_name":       true,
+		"change_type":      true,
+		"replay_id":        true,
+		"CreatedDate":      true,
+		"CreatedById":      true,
+		"LastModifiedDate": true,
+		"LastModifiedById": true,
+		"SystemModstamp":   true,
+		"IsDeleted":        true,
+	}
+	return metadataFields[fieldName]
+}
@@ -0,0 +1,380 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+const (
+	defaultAPIVersion        = "61.0"
+	defaultTokenURL          = "https://login.salesforce.com/services/oauth2/token"
+	defaultRequestsPerSecond = 10
+
+	// collectionBatchSize is the maximum number of records per sObject Collections request
+	collectionBatchSize = 200
+	// restQueryLimit is the largest limit still fetched through the REST query API; larger
+	// and unlimited reads go through a Bulk API 2.0 query job
+	restQueryLimit = 2000
+	// bulkPollInterval is how often the state of a Bulk API query job is checked
+	bulkPollInterval = 2 * time.Second
+)
+
+// Client is a Salesforce REST and Bulk API 2.0 client for one org.
+type Client struct {
+	api         *restclient.Client
+	tokens      *restclient.TokenSource
+	instanceURL string
+	apiVersion  string
+
+	describeMu sync.Mutex
+	describes  map[string]*sobjectDescribe
+}
+
+// sobjectInfo is an entry of the global describe.
+type sobjectInfo struct {
+	Name       string `json:"name"`
+	Label      string `json:"label"`
+	Custom     bool   `json:"custom"`
+	Queryable  bool   `json:"queryable"`
+	Createable bool   `json:"createable"`
+	Updateable bool   `json:"updateable"`
+	Deletable  bool   `json:"deletable"`
+}
+
+// sobjectDescribe is the describe result of one sObject.
+type sobjectDescribe struct {
+	sobjectInfo
+	Fields []fieldInfo `json:"fields"`
+}
+
+// fieldInfo describes one sObject field.
+type fieldInfo struct {
+	Name              string          `json:"name"`
+	Label             string          `json:"label"`
+	Type              string          `json:"type"`
+	Length            int             `json:"length"`
+	Precision         int             `json:"precision"`
+	Scale             int             `json:"scale"`
+	Nillable          bool            `json:"nillable"`
+	DefaultedOnCreate bool            `json:"defaultedOnCreate"`
+	DefaultValue      interface{}     `json:"defaultValue"`
+	Createable        bool            `json:"createable"`
+	Updateable        bool            `json:"updateable"`
+	Unique            bool            `json:"unique"`
+	ExternalID        bool            `json:"externalId"`
+	Calculated        bool            `json:"calculated"`
+	CalculatedFormula string          `json:"calculatedFormula"`
+	ReferenceTo       []string        `json:"referenceTo"`
+	RelationshipName  string          `json:"relationshipName"`
+	InlineHelpText    string          `json:"inlineHelpText"`
+	PicklistValues    []picklistValue `json:"picklistValues"`
+	CompoundFieldName string          `json:"compoundFieldName"`
+}
+
+type picklistValue struct {
+	Value  string `json:"value"`
+	Label  string `json:"label"`
+	Active bool   `json:"active"`
+}
+
+// queryResult is a page of a REST SOQL query.
+type queryResult struct {
+	TotalSize      int64                    `json:"totalSize"`
+	Done           bool                     `json:"done"`
+	NextRecordsURL string                   `json:"nextRecordsUrl"`
+	Records        []map[string]interface{} `json:"records"`
+}
+
+// bulkJob is the state of a Bulk API 2.0 job.
+type bulkJob struct {
+	ID           string `json:"id"`
+	State        string `json:"state"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// saveResult is the per-record outcome of an sObject Collections request.
+type saveResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Errors  []struct {
+		StatusCode string   `json:"statusCode"`
+		Message    string   `json:"message"`
+		Fields     []string `json:"fields"`
+	} `json:"errors"`
+}
+
+func (r saveResult) err() error {
+	if r.Success || len(r.Errors) == 0 {
+		return nil
+	}
+	e := r.Errors[0]
+	if len(e.Fields) > 0 {
+		return fmt.Errorf("%s: %s (fields: %s)", e.StatusCode, e.Message, strings.Join(e.Fields, ", "))
+	}
+	return fmt.Errorf("%s: %s", e.StatusCode, e.Message)
+}
+
+func (c *Client) dataPath(path string) string {
+	return "/services/data/v" + c.apiVersion + path
+}
+
+// sobjects returns the global describe of the org
+func (c *Client) sobjects(ctx context.Context) ([]sobjectInfo, error) {
+	var resp struct {
+		SObjects []sobjectInfo `json:"sobjects"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, c.dataPath("/sobjects"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.SObjects, nil
+}
+
+// describe returns the describe of an sObject. Results are cached for the lifetime of the client.
+func (c *Client) describe(ctx context.Context, name string) (*sobjectDescribe, error) {
+	c.describeMu.Lock()
+	cached, ok := c.describes[strings.ToLower(name)]
+	c.describeMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var desc sobjectDescribe
+	if err := c.api.Do(ctx, http.MethodGet, c.dataPath("/sobjects/"+url.PathEscape(name)+"/describe"), nil, nil, &desc); err != nil {
+		return nil, err
+	}
+
+	c.describeMu.Lock()
+	if c.describes == nil {
+		c.describes = make(map[string]*sobjectDescribe)
+	}
+	c.describes[strings.ToLower(name)] = &desc
+	c.describeMu.Unlock()
+	return &desc, nil
+}
+
+// query runs a SOQL query through the REST API and returns the first page
+func (c *Client) query(ctx context.Context, soql string) (*queryResult, error) {
+	var result queryResult
+	if err := c.api.Do(ctx, http.MethodGet, c.dataPath("/query"), url.Values{"q": {soql}}, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// queryMore returns the page following a REST query page
+func (c *Client) queryMore(ctx context.Context, nextRecordsURL string) (*queryResult, error) {
+	var result queryResult
+	if err := c.api.Do(ctx, http.MethodGet, nextRecordsURL, nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// queryAll runs a SOQL query and follows pagination until limit records were read (limit <= 0 reads all)
+func (c *Client) queryAll(ctx context.Context, soql string, limit int) ([]map[string]interface{}, error) {
+	result, err := c.query(ctx, soql)
+	if err != nil {
+		return nil, err
+	}
+	var records []map[string]interface{}
+	for {
+		for _, r := range result.Records {
+			records = append(records, stripAttributes(r))
+		}
+		if result.Done || result.NextRecordsURL == "" || (limit > 0 && len(records) >= limit) {
+			break
+		}
+		if result, err = c.queryMore(ctx, result.NextRecordsURL); err != nil {
+			return nil, err
+		}
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// createQueryJob starts a Bulk API 2.0 query job
+func (c *Client) createQueryJob(ctx context.Context, soql string) (*bulkJob, error) {
+	var job bulkJob
+	body := map[string]interface{}{"operation": "query", "query": soql}
+	if err := c.api.Do(ctx, http.MethodPost, c.dataPath("/jobs/query"), nil, body, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// waitQueryJob blocks until a Bulk API 2.0 query job has completed
+func (c *Client) waitQueryJob(ctx context.Context, jobID string) error {
+	ticker := time.NewTicker(bulkPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var job bulkJob
+		if err := c.api.Do(ctx, http.MethodGet, c.dataPath("/jobs/query/"+jobID), nil, nil, &job); err != nil {
+			return err
+		}
+		switch job.State {
+		case "JobComplete":
+			return nil
+		case "Failed", "Aborted":
+			return fmt.Errorf("bulk query job %s %s: %s", jobID, strings.ToLower(job.State), job.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// queryJobResults reads up to maxRecords CSV rows of a completed query job starting at locator.
+// The returned locator is empty once all results were read.
+func (c *Client) queryJobResults(ctx context.Context, jobID, locator string, maxRecords int) ([]map[string]string, string, error) {
+	query := url.Values{}
+	if locator != "" {
+		query.Set("locator", locator)
+	}
+	if maxRecords > 0 {
+		query.Set("maxRecords", strconv.Itoa(maxRecords))
+	}
+
+	body, header, err := c.api.DoRaw(ctx, http.MethodGet, c.dataPath("/jobs/query/"+jobID+"/results"), query, nil,
+		http.Header{"Accept": {"text/csv"}})
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := parseCSV(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid bulk query results: %w", err)
+	}
+
+	next := header.Get("Sforce-Locator")
+	if next == "null" {
+		next = ""
+	}
+	return rows, next, nil
+}
+
+// createRecords inserts up to collectionBatchSize records of an sObject
+func (c *Client) createRecords(ctx context.Context, object string, records []map[string]interface{}) ([]saveResult, error) {
+	var results []saveResult
+	body := map[string]interface{}{"allOrNone": false, "records": withAttributes(object, records)}
+	err := c.api.Do(ctx, http.MethodPost, c.dataPath("/composite/sobjects"), nil, body, &results)
+	return results, err
+}
+
+// updateRecords updates up to collectionBatchSize records identified by their Id field
+func (c *Client) updateRecords(ctx context.Context, object string, records []map[string]interface{}) ([]saveResult, error) {
+	var results []saveResult
+	body := map[string]interface{}{"allOrNone": false, "records": withAttributes(object, records)}
+	err := c.api.Do(ctx, http.MethodPatch, c.dataPath("/composite/sobjects"), nil, body, &results)
+	return results, err
+}
+
+// upsertRecords upserts up to collectionBatchSize records matched on an external ID field
+func (c *Client) upsertRecords(ctx context.Context, object, externalIDField string, records []map[string]interface{}) ([]saveResult, error) {
+	var results []saveResult
+	body := map[string]interface{}{"allOrNone": false, "records": withAttributes(object, records)}
+	path := c.dataPath("/composite/sobjects/" + url.PathEscape(object) + "/" + url.PathEscape(externalIDField))
+	err := c.api.Do(ctx, http.MethodPatch, path, nil, body, &results)
+	return results, err
+}
+
+// deleteRecords deletes up to collectionBatchSize records by ID
+func (c *Client) deleteRecords(ctx context.Context, ids []string) ([]saveResult, error) {
+	var results []saveResult
+	query := url.Values{"ids": {strings.Join(ids, ",")}, "allOrNone": {"false"}}
+	err := c.api.Do(ctx, http.MethodDelete, c.dataPath("/composite/sobjects"), query, nil, &results)
+	return results, err
+}
+
+// organization returns the Organization record of the org
+func (c *Client) organization(ctx context.Context) (map[string]interface{}, error) {
+	records, err := c.queryAll(ctx, "SELECT Id, Name, OrganizationType, InstanceName, IsSandbox FROM Organization", 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("organization record not visible to this user")
+	}
+	return records[0], nil
+}
+
+// limits returns the org limits keyed by limit name
+func (c *Client) limits(ctx context.Context) (map[string]map[string]int64, error) {
+	var limits map[string]map[string]int64
+	if err := c.api.Do(ctx, http.MethodGet, c.dataPath("/limits"), nil, nil, &limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// withAttributes adds the sObject type attribute required by the Collections API
+func withAttributes(object string, records []map[string]interface{}) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(records))
+	for i, r := range records {
+		rec := make(map[string]interface{}, len(r)+1)
+		for k, v := range r {
+			rec[k] = v
+		}
+		rec["attributes"] = map[string]string{"type": object}
+		out[i] = rec
+	}
+	return out
+}
+
+// stripAttributes removes the attributes entry the REST API adds to every record
+func stripAttributes(record map[string]interface{}) map[string]interface{} {
+	delete(record, "attributes")
+	for k, v := range record {
+		if nested, ok := v.(map[string]interface{}); ok {
+			record[k] = stripAttributes(nested)
+		}
+	}
+	return record
+}
+
+// parseCSV parses Bulk API CSV results into rows keyed by header
+func parseCSV(data []byte) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
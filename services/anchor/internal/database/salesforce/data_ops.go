@@ -0,0 +1,442 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DataOps implements data operations for Salesforce.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves records of an sObject.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves records with specific fields. Small limits are read with the REST
+// query API; larger and unlimited reads run a Bulk API 2.0 query job.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	desc, err := d.conn.describe(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	soql := buildSelect(desc, columns, "")
+
+	if limit > 0 && limit <= restQueryLimit {
+		records, err := d.conn.client.queryAll(ctx, fmt.Sprintf("%s LIMIT %d", soql, limit), limit)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Salesforce, "fetch", err)
+		}
+		return records, nil
+	}
+
+	if limit > 0 {
+		soql += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	job, err := d.startQueryJob(ctx, soql)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "fetch", err)
+	}
+
+	var rows []map[string]interface{}
+	locator := ""
+	for {
+		page, next, err := d.conn.client.queryJobResults(ctx, job, locator, 0)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Salesforce, "fetch", err)
+		}
+		for _, r := range page {
+			rows = append(rows, convertBulkRow(desc, r))
+		}
+		if next == "" {
+			break
+		}
+		locator = next
+	}
+	return rows, nil
+}
+
+// Stream returns a batch of records starting at the given offset. The first batch starts a
+// Bulk API 2.0 query job; the job and result locator that ended each batch are remembered on
+// the connection so the next batch resumes from them. Unknown offsets start a new job and skip ahead.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	desc, err := d.conn.describe(ctx, params.Table)
+	if err != nil {
+		return adapter.StreamResult{}, err
+	}
+	batchSize := int(params.BatchSize)
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	cursor, ok := d.conn.streamCursor(params.Table, params.Offset)
+	if !ok {
+		job, err := d.startQueryJob(ctx, buildSelect(desc, params.Columns, params.OrderBy))
+		if err != nil {
+			return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.Salesforce, "stream", err)
+		}
+		cursor = bulkCursor{jobID: job}
+
+		for skip := params.Offset; skip > 0; {
+			page, next, err := d.conn.client.queryJobResults(ctx, cursor.jobID, cursor.locator, int(skip))
+			if err != nil {
+				return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.Salesforce, "stream", err)
+			}
+			skip -= int64(len(page))
+			cursor.locator = next
+			if next == "" {
+				return adapter.StreamResult{NextCursor: strconv.FormatInt(params.Offset, 10)}, nil
+			}
+		}
+	}
+
+	page, next, err := d.conn.client.queryJobResults(ctx, cursor.jobID, cursor.locator, batchSize)
+	if err != nil {
+		return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.Salesforce, "stream", err)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(page))
+	for _, r := range page {
+		rows = append(rows, convertBulkRow(desc, r))
+	}
+
+	nextOffset := params.Offset + int64(len(rows))
+	if next != "" {
+		d.conn.setStreamCursor(params.Table, nextOffset, bulkCursor{jobID: cursor.jobID, locator: next})
+	}
+
+	return adapter.StreamResult{
+		Data:       rows,
+		HasMore:    next != "",
+		NextCursor: strconv.FormatInt(nextOffset, 10),
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// Insert creates records through the sObject Collections API. Records rejected by
+// Salesforce are not counted; the first rejection is returned after all batches are sent.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	records := make([]map[string]interface{}, len(data))
+	for i, row := range data {
+		records[i] = writableFields(row, "Id")
+	}
+	n, err := saveBatches(records, func(batch []map[string]interface{}) ([]saveResult, error) {
+		return d.conn.client.createRecords(ctx, table, batch)
+	})
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.Salesforce, "insert", err)
+	}
+	return n, nil
+}
+
+// Update updates records. Rows carrying an Id are updated directly; otherwise the records
+// matching the where columns of each row are looked up first.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	if len(whereColumns) == 0 {
+		whereColumns = []string{"Id"}
+	}
+
+	var records []map[string]interface{}
+	for _, row := range data {
+		if id, ok := row["Id"].(string); ok && id != "" && len(whereColumns) == 1 && whereColumns[0] == "Id" {
+			records = append(records, writableFields(row))
+			continue
+		}
+
+		conditions := make(map[string]interface{}, len(whereColumns))
+		for _, c := range whereColumns {
+			conditions[c] = row[c]
+		}
+		ids, err := d.matchingIDs(ctx, table, conditions)
+		if err != nil {
+			return 0, adapter.WrapError(dbcapabilities.Salesforce, "update", err)
+		}
+		for _, id := range ids {
+			record := writableFields(row, whereColumns...)
+			record["Id"] = id
+			records = append(records, record)
+		}
+	}
+
+	n, err := saveBatches(records, func(batch []map[string]interface{}) ([]saveResult, error) {
+		return d.conn.client.updateRecords(ctx, table, batch)
+	})
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.Salesforce, "update", err)
+	}
+	return n, nil
+}
+
+// Upsert upserts records matched on a single external ID field (or Id).
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(uniqueColumns) != 1 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.Salesforce, "uniqueColumns",
+			"salesforce upserts match on exactly one external ID field")
+	}
+	externalID := uniqueColumns[0]
+
+	records := make([]map[string]interface{}, len(data))
+	for i, row := range data {
+		records[i] = writableFields(row)
+	}
+	n, err := saveBatches(records, func(batch []map[string]interface{}) ([]saveResult, error) {
+		return d.conn.client.upsertRecords(ctx, table, externalID, batch)
+	})
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.Salesforce, "upsert", err)
+	}
+	return n, nil
+}
+
+// Delete deletes the records matching all conditions.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	if len(conditions) == 0 {
+		return 0, adapter.NewConfigurationError(dbcapabilities.Salesforce, "conditions", "conditions are required to delete records")
+	}
+
+	ids, err := d.matchingIDs(ctx, table, conditions)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Salesforce, "delete", err)
+	}
+	n, err := d.deleteIDs(ctx, ids)
+	if err != nil {
+		return n, adapter.WrapError(dbcapabilities.Salesforce, "delete", err)
+	}
+	return n, nil
+}
+
+// ExecuteQuery runs a SOQL query and returns all records.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	records, err := d.conn.client.queryAll(ctx, query, 0)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "execute_query", err)
+	}
+	result := make([]interface{}, len(records))
+	for i, r := range records {
+		result[i] = r
+	}
+	return result, nil
+}
+
+// ExecuteCountQuery runs a SOQL query and returns its total size, as reported for SELECT COUNT() queries.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	result, err := d.conn.client.query(ctx, query)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Salesforce, "execute_count_query", err)
+	}
+	return result.TotalSize, nil
+}
+
+// GetRowCount returns the exact number of records, optionally filtered by a SOQL where clause.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	query := "SELECT COUNT() FROM " + table
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	count, err := d.ExecuteCountQuery(ctx, query)
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
+
+// Wipe is not supported; deleting all CRM data of an org is never done by a mapping.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "wipe", "deleting all records of an org is not supported")
+}
+
+// startQueryJob starts a bulk query job and waits for it to complete
+func (d *DataOps) startQueryJob(ctx context.Context, soql string) (string, error) {
+	job, err := d.conn.client.createQueryJob(ctx, soql)
+	if err != nil {
+		return "", err
+	}
+	if err := d.conn.client.waitQueryJob(ctx, job.ID); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+// matchingIDs returns the IDs of the records matching all conditions
+func (d *DataOps) matchingIDs(ctx context.Context, table string, conditions map[string]interface{}) ([]string, error) {
+	soql := "SELECT Id FROM " + table
+	if where := buildWhere(conditions); where != "" {
+		soql += " WHERE " + where
+	}
+	records, err := d.conn.client.queryAll(ctx, soql, 0)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		if id, ok := r["Id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// deleteIDs deletes records in batches and returns the number deleted
+func (d *DataOps) deleteIDs(ctx context.Context, ids []string) (int64, error) {
+	var deleted int64
+	var firstErr error
+	for start := 0; start < len(ids); start += collectionBatchSize {
+		end := start + collectionBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		results, err := d.conn.client.deleteRecords(ctx, ids[start:end])
+		if err != nil {
+			return deleted, err
+		}
+		for _, r := range results {
+			if r.Success {
+				deleted++
+			} else if firstErr == nil {
+				firstErr = r.err()
+			}
+		}
+	}
+	return deleted, firstErr
+}
+
+// saveBatches sends records in Collections API sized batches and counts the successful ones
+func saveBatches(records []map[string]interface{}, save func([]map[string]interface{}) ([]saveResult, error)) (int64, error) {
+	var saved int64
+	var firstErr error
+	for start := 0; start < len(records); start += collectionBatchSize {
+		end := start + collectionBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		results, err := save(records[start:end])
+		if err != nil {
+			return saved, err
+		}
+		for _, r := range results {
+			if r.Success {
+				saved++
+			} else if firstErr == nil {
+				firstErr = r.err()
+			}
+		}
+	}
+	return saved, firstErr
+}
+
+// writableFields copies a row without the excluded fields and formats values for the API
+func writableFields(row map[string]interface{}, exclude ...string) map[string]interface{} {
+	skip := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		skip[e] = true
+	}
+	record := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		if skip[k] {
+			continue
+		}
+		if t, ok := v.(time.Time); ok {
+			v = t.UTC().Format(time.RFC3339)
+		}
+		record[k] = v
+	}
+	return record
+}
+
+// buildSelect builds a SOQL select over the given fields, or all bulk-queryable fields
+func buildSelect(desc *sobjectDescribe, columns []string, orderBy string) string {
+	if len(columns) == 0 {
+		for _, f := range desc.Fields {
+			// Compound and binary fields cannot be read by Bulk API queries; their components can
+			if f.Type == "address" || f.Type == "location" || f.Type == "base64" {
+				continue
+			}
+			columns = append(columns, f.Name)
+		}
+	}
+	soql := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), desc.Name)
+	if orderBy != "" {
+		soql += " ORDER BY " + orderBy
+	}
+	return soql
+}
+
+// buildWhere builds a SOQL condition matching all conditions
+func buildWhere(conditions map[string]interface{}) string {
+	keys := make([]string, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s = %s", k, soqlLiteral(conditions[k]))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// soqlLiteral formats a value as a SOQL literal
+func soqlLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case int, int32, int64, float32, float64:
+		return fmt.Sprint(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339)
+	default:
+		s := strings.ReplaceAll(fmt.Sprint(val), `\`, `\\`)
+		return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+	}
+}
+
+// convertBulkRow converts CSV values of a bulk query result to typed values
+func convertBulkRow(desc *sobjectDescribe, row map[string]string) map[string]interface{} {
+	types := make(map[string]string, len(desc.Fields))
+	for _, f := range desc.Fields {
+		types[f.Name] = f.Type
+	}
+
+	out := make(map[string]interface{}, len(row))
+	for name, value := range row {
+		if value == "" {
+			out[name] = nil
+			continue
+		}
+		switch types[name] {
+		case "boolean":
+			if b, err := strconv.ParseBool(value); err == nil {
+				out[name] = b
+				continue
+			}
+		case "int", "long":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				out[name] = n
+				continue
+			}
+		case "double", "currency", "percent":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				out[name] = f
+				continue
+			}
+		}
+		out[name] = value
+	}
+	return out
+}
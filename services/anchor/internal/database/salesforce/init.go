@@ -0,0 +1,8 @@
+package salesforce
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register Salesforce adapter with the global registry
+	adapter.Register(NewAdapter())
+}
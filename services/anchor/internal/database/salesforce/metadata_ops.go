@@ -0,0 +1,98 @@
+package salesforce
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements metadata operations for Salesforce.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the org.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	org, err := m.conn.client.organization(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "collect_database_metadata", err)
+	}
+	names, err := m.conn.objectNames(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "collect_database_metadata", err)
+	}
+
+	metadata := map[string]interface{}{
+		"database_type":     string(dbcapabilities.Salesforce),
+		"organization_id":   m.conn.orgID,
+		"organization_name": org["Name"],
+		"organization_type": org["OrganizationType"],
+		"instance_name":     org["InstanceName"],
+		"is_sandbox":        org["IsSandbox"],
+		"instance_url":      m.conn.client.instanceURL,
+		"api_version":       m.conn.client.apiVersion,
+		"object_count":      len(names),
+		"objects":           names,
+		"unique_identifier": m.conn.orgID,
+	}
+	if size, err := m.GetDatabaseSize(ctx); err == nil {
+		metadata["size_bytes"] = size
+	}
+	return metadata, nil
+}
+
+// CollectInstanceMetadata returns the org's API limits.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	limits, err := m.conn.client.limits(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "collect_instance_metadata", err)
+	}
+	metadata := map[string]interface{}{
+		"database_type": string(dbcapabilities.Salesforce),
+		"version":       m.conn.client.apiVersion,
+	}
+	for _, name := range []string{"DailyApiRequests", "DailyBulkV2QueryJobs", "DailyBulkV2QueryFileStorageMB", "DataStorageMB", "FileStorageMB"} {
+		if l, ok := limits[name]; ok {
+			metadata[name] = l
+		}
+	}
+	return metadata, nil
+}
+
+// GetVersion returns the REST API version in use.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	return m.conn.client.apiVersion, nil
+}
+
+// GetUniqueIdentifier returns the organization ID.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return m.conn.orgID, nil
+}
+
+// GetDatabaseSize returns the data storage used by the org.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	limits, err := m.conn.client.limits(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Salesforce, "get_database_size", err)
+	}
+	storage, ok := limits["DataStorageMB"]
+	if !ok {
+		return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "get database size", "data storage limit not visible to this user")
+	}
+	return (storage["Max"] - storage["Remaining"]) * 1024 * 1024, nil
+}
+
+// GetTableCount returns the number of exposed sObjects.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	names, err := m.conn.objectNames(ctx)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Salesforce, "get_table_count", err)
+	}
+	return len(names), nil
+}
+
+// ExecuteCommand is not supported for Salesforce.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "execute command", "salesforce does not accept commands")
+}
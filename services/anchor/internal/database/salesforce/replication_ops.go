@@ -0,0 +1,378 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// ReplicationOps implements adapter.ReplicationOperator for Salesforce using
+// Change Data Capture events delivered by the Streaming API.
+type ReplicationOps struct {
+	conn *Connection
+}
+
+// IsSupported returns whether replication is supported.
+func (r *ReplicationOps) IsSupported() bool {
+	return true
+}
+
+// GetSupportedMechanisms returns the supported replication mechanisms.
+func (r *ReplicationOps) GetSupportedMechanisms() []string {
+	return []string{"change_data_capture"}
+}
+
+// CheckPrerequisites verifies that the user can open a Streaming API session.
+// Change events are only published for objects selected in the org's Change Data Capture setup.
+func (r *ReplicationOps) CheckPrerequisites(ctx context.Context) error {
+	client := newStreamingClient(r.conn.client)
+	if err := client.handshake(ctx); err != nil {
+		return adapter.WrapError(dbcapabilities.Salesforce, "check_replication_prerequisites", err)
+	}
+	_ = client.disconnect(ctx)
+	return nil
+}
+
+// Connect creates a replication source subscribed to the change events of the configured
+// objects, or to all selected objects when no tables are configured.
+func (r *ReplicationOps) Connect(ctx context.Context, config adapter.ReplicationConfig) (adapter.ReplicationSource, error) {
+	channels := make([]string, 0, len(config.TableNames))
+	for _, table := range config.TableNames {
+		channels = append(channels, changeEventChannel(table))
+	}
+	if len(channels) == 0 {
+		channels = []string{"/data/ChangeEvents"}
+	}
+
+	source := &SalesforceReplicationSource{
+		id:         config.ReplicationID,
+		databaseID: config.DatabaseID,
+		client:     newStreamingClient(r.conn.client),
+		channels:   channels,
+		config:     config,
+		replayIDs:  make(map[string]int64),
+		stopChan:   make(chan struct{}),
+	}
+	if config.EventHandler != nil {
+		source.eventHandler = config.EventHandler
+	}
+	if from, ok := config.Options["start_from"].(string); ok && from == "earliest" {
+		source.initialReplay = replayRetainedEvents
+	} else {
+		source.initialReplay = replayNewEvents
+	}
+
+	if config.StartPosition != "" {
+		if err := source.SetPosition(config.StartPosition); err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Salesforce, "set_start_position", err)
+		}
+	}
+	return source, nil
+}
+
+// GetStatus returns the replication status.
+func (r *ReplicationOps) GetStatus(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"database_id":     r.conn.id,
+		"organization_id": r.conn.orgID,
+		"mechanism":       "change_data_capture",
+	}, nil
+}
+
+// GetLag returns the replication lag.
+func (r *ReplicationOps) GetLag(ctx context.Context) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"database_id": r.conn.id,
+		"mechanism":   "change_data_capture",
+		"note":        "change events are retained by salesforce for 72 hours",
+	}, nil
+}
+
+// ListSlots lists replication slots (not applicable for Salesforce).
+func (r *ReplicationOps) ListSlots(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "list replication slots", "Salesforce uses change event channels, not replication slots")
+}
+
+// DropSlot drops a replication slot (not applicable for Salesforce).
+func (r *ReplicationOps) DropSlot(ctx context.Context, slotName string) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "drop replication slot", "Salesforce uses change event channels, not replication slots")
+}
+
+// ListPublications lists publications (not applicable for Salesforce).
+func (r *ReplicationOps) ListPublications(ctx context.Context) ([]map[string]interface{}, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "list publications", "Salesforce uses change event channels, not publications")
+}
+
+// DropPublication drops a publication (not applicable for Salesforce).
+func (r *ReplicationOps) DropPublication(ctx context.Context, publicationName string) error {
+	return adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "drop publication", "Salesforce uses change event channels, not publications")
+}
+
+// SalesforceReplicationSource implements adapter.ReplicationSource for Change Data Capture events.
+// The position is the last processed replay ID of every channel, encoded as JSON.
+type SalesforceReplicationSource struct {
+	id            string
+	databaseID    string
+	client        *streamingClient
+	channels      []string
+	config        adapter.ReplicationConfig
+	initialReplay int64
+	active        int32
+	stopChan      chan struct{}
+	mu            sync.RWMutex
+	replayIDs     map[string]int64
+	eventCount    int64
+	lastError     string
+	eventHandler  func(map[string]interface{})
+	checkpointFn  func(context.Context, string) error
+}
+
+// GetSourceID returns the replication source ID.
+func (s *SalesforceReplicationSource) GetSourceID() string {
+	return s.id
+}
+
+// GetDatabaseID returns the database ID.
+func (s *SalesforceReplicationSource) GetDatabaseID() string {
+	return s.databaseID
+}
+
+// GetStatus returns the replication source status.
+func (s *SalesforceReplicationSource) GetStatus() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return map[string]interface{}{
+		"source_id":   s.id,
+		"database_id": s.databaseID,
+		"active":      s.IsActive(),
+		"mechanism":   "change_data_capture",
+		"channels":    s.channels,
+		"event_count": atomic.LoadInt64(&s.eventCount),
+		"last_error":  s.lastError,
+	}
+}
+
+// GetMetadata returns the replication source metadata.
+func (s *SalesforceReplicationSource) GetMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"source_type":     "change_data_capture",
+		"database_type":   string(dbcapabilities.Salesforce),
+		"replication_id":  s.id,
+		"database_id":     s.databaseID,
+		"supported_ops":   []string{"CREATE", "UPDATE", "DELETE", "UNDELETE"},
+		"resume_capable":  true,
+		"transaction_log": false,
+	}
+}
+
+// IsActive returns whether the replication source is active.
+func (s *SalesforceReplicationSource) IsActive() bool {
+	return atomic.LoadInt32(&s.active) == 1
+}
+
+// Start opens a streaming session, subscribes to the channels and starts receiving events.
+func (s *SalesforceReplicationSource) Start() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 0, 1) {
+		return adapter.NewDatabaseError(
+			dbcapabilities.Salesforce,
+			"start_replication",
+			adapter.ErrInvalidConfiguration,
+		).WithContext("error", "replication source is already active")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.subscribeAll(ctx); err != nil {
+		cancel()
+		atomic.StoreInt32(&s.active, 0)
+		return adapter.WrapError(dbcapabilities.Salesforce, "start_replication", err)
+	}
+
+	go func() {
+		<-s.stopChan
+		cancel()
+	}()
+	go s.run(ctx)
+	return nil
+}
+
+// subscribeAll handshakes and subscribes to every channel from its last replay ID
+func (s *SalesforceReplicationSource) subscribeAll(ctx context.Context) error {
+	if err := s.client.handshake(ctx); err != nil {
+		return err
+	}
+	for _, channel := range s.channels {
+		s.mu.RLock()
+		replayID, ok := s.replayIDs[channel]
+		s.mu.RUnlock()
+		if !ok {
+			replayID = s.initialReplay
+		}
+		if err := s.client.subscribe(ctx, channel, replayID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run long-polls for events until the source is stopped
+func (s *SalesforceReplicationSource) run(ctx context.Context) {
+	for s.IsActive() {
+		messages, err := s.client.connect(ctx)
+		for _, m := range messages {
+			s.handleMessage(m)
+		}
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.setError(err)
+		if errors.Is(err, errRehandshake) {
+			err = s.subscribeAll(ctx)
+			if err == nil {
+				continue
+			}
+			s.setError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// handleMessage forwards one raw event per changed record to the event handler
+func (s *SalesforceReplicationSource) handleMessage(m bayeuxMessage) {
+	if len(m.Data) == 0 {
+		return
+	}
+	var event changeEvent
+	if err := json.Unmarshal(m.Data, &event); err != nil {
+		s.setError(err)
+		return
+	}
+	header, _ := event.Payload["ChangeEventHeader"].(map[string]interface{})
+	if header == nil {
+		return
+	}
+
+	data := make(map[string]interface{}, len(event.Payload))
+	for k, v := range event.Payload {
+		if k == "ChangeEventHeader" {
+			continue
+		}
+		// Compound fields such as Name or BillingAddress arrive nested; flatten them into their components
+		if nested, ok := v.(map[string]interface{}); ok {
+			for nk, nv := range nested {
+				data[nk] = nv
+			}
+			continue
+		}
+		data[k] = v
+	}
+
+	recordIDs, _ := header["recordIds"].([]interface{})
+	for _, id := range recordIDs {
+		raw := map[string]interface{}{
+			"channel":          m.Channel,
+			"replay_id":        event.Event.ReplayID,
+			"table_name":       header["entityName"],
+			"change_type":      header["changeType"],
+			"record_id":        id,
+			"commit_timestamp": header["commitTimestamp"],
+			"transaction_key":  header["transactionKey"],
+			"changed_fields":   header["changedFields"],
+			"change_origin":    header["changeOrigin"],
+			"data":             data,
+		}
+		if s.eventHandler != nil {
+			s.eventHandler(raw)
+		}
+		atomic.AddInt64(&s.eventCount, 1)
+	}
+
+	s.mu.Lock()
+	s.replayIDs[m.Channel] = event.Event.ReplayID
+	s.mu.Unlock()
+}
+
+func (s *SalesforceReplicationSource) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err.Error()
+}
+
+// Stop stops the replication source.
+func (s *SalesforceReplicationSource) Stop() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 1, 0) {
+		return nil
+	}
+	close(s.stopChan)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_ = s.client.disconnect(ctx)
+	return nil
+}
+
+// Close closes the replication source.
+func (s *SalesforceReplicationSource) Close() error {
+	return s.Stop()
+}
+
+// GetPosition returns the last processed replay ID per channel.
+func (s *SalesforceReplicationSource) GetPosition() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.replayIDs) == 0 {
+		return "", nil
+	}
+	position, err := json.Marshal(s.replayIDs)
+	if err != nil {
+		return "", err
+	}
+	return string(position), nil
+}
+
+// SetPosition sets the replay IDs to resume from.
+func (s *SalesforceReplicationSource) SetPosition(position string) error {
+	if position == "" {
+		return nil
+	}
+	replayIDs := make(map[string]int64)
+	if err := json.Unmarshal([]byte(position), &replayIDs); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replayIDs = replayIDs
+	return nil
+}
+
+// SaveCheckpoint persists the current replication position.
+func (s *SalesforceReplicationSource) SaveCheckpoint(ctx context.Context, position string) error {
+	if s.checkpointFn != nil {
+		return s.checkpointFn(ctx, position)
+	}
+	return nil
+}
+
+// SetCheckpointFunc sets the callback function for persisting checkpoints.
+func (s *SalesforceReplicationSource) SetCheckpointFunc(fn func(context.Context, string) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpointFn = fn
+}
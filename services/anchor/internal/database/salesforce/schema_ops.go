@@ -0,0 +1,305 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// standardObjects are the standard sObjects exposed when options.objects is not set.
+// Orgs have several hundred standard sObjects, most of them setup or system objects.
+var standardObjects = []string{
+	"Account", "Asset", "Campaign", "CampaignMember", "Case", "Contact", "Contract", "Event",
+	"Lead", "Opportunity", "OpportunityLineItem", "Order", "OrderItem", "Pricebook2",
+	"PricebookEntry", "Product2", "Quote", "QuoteLineItem", "Task", "User",
+}
+
+// SchemaOps implements schema operations for Salesforce.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema describes the exposed sObjects and maps each to a collection.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	names, err := s.conn.objectNames(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "discover_schema", err)
+	}
+
+	model := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.Salesforce,
+		Collections:  make(map[string]unifiedmodel.Collection, len(names)),
+	}
+	for _, name := range names {
+		desc, err := s.conn.client.describe(ctx, name)
+		if err != nil {
+			return nil, adapter.WrapError(dbcapabilities.Salesforce, "discover_schema", err)
+		}
+		model.Collections[desc.Name] = convertCollection(desc)
+	}
+	return model, nil
+}
+
+// CreateStructure checks that the model's collections exist as sObjects. Objects and fields
+// are administered in Salesforce setup, so missing objects are reported rather than created.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if model == nil {
+		return adapter.NewConfigurationError(dbcapabilities.Salesforce, "model", "unified model cannot be nil")
+	}
+
+	all, err := s.conn.client.sobjects(ctx)
+	if err != nil {
+		return adapter.WrapError(dbcapabilities.Salesforce, "create_structure", err)
+	}
+	existing := make(map[string]bool, len(all))
+	for _, o := range all {
+		existing[strings.ToLower(o.Name)] = true
+	}
+
+	var names []string
+	for name := range model.Collections {
+		names = append(names, name)
+	}
+	for name := range model.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !existing[strings.ToLower(name)] {
+			return adapter.NewUnsupportedOperationError(dbcapabilities.Salesforce, "create object "+name,
+				"sobjects must be created in salesforce setup")
+		}
+	}
+	return nil
+}
+
+// ListTables lists the exposed sObjects.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	names, err := s.conn.objectNames(ctx)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "list_tables", err)
+	}
+	return names, nil
+}
+
+// GetTableSchema returns an sObject as a table, with its fields as columns and
+// lookup relationships as foreign keys.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	desc, err := s.conn.describe(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &unifiedmodel.Table{
+		Name:        desc.Name,
+		Comment:     desc.Label,
+		Columns:     make(map[string]unifiedmodel.Column, len(desc.Fields)),
+		Constraints: make(map[string]unifiedmodel.Constraint),
+	}
+	for i, f := range desc.Fields {
+		position := i + 1
+		col := unifiedmodel.Column{
+			Name:            f.Name,
+			DataType:        unifiedType(f),
+			Nullable:        f.Nillable,
+			IsPrimaryKey:    f.Type == "id",
+			OrdinalPosition: &position,
+			Options:         fieldOptions(f),
+		}
+		if f.Calculated {
+			col.GeneratedExpression = f.CalculatedFormula
+		}
+		table.Columns[f.Name] = col
+
+		if f.Type == "reference" && len(f.ReferenceTo) == 1 {
+			name := "fk_" + desc.Name + "_" + f.Name
+			table.Constraints[name] = unifiedmodel.Constraint{
+				Name:      name,
+				Type:      unifiedmodel.ConstraintTypeForeignKey,
+				Columns:   []string{f.Name},
+				Reference: unifiedmodel.Reference{Table: f.ReferenceTo[0], Columns: []string{"Id"}},
+			}
+		}
+	}
+	table.Constraints["pk_"+desc.Name] = unifiedmodel.Constraint{
+		Name:    "pk_" + desc.Name,
+		Type:    unifiedmodel.ConstraintTypePrimaryKey,
+		Columns: []string{"Id"},
+	}
+	return table, nil
+}
+
+// objectNames returns the queryable sObjects exposed by the connection
+func (c *Connection) objectNames(ctx context.Context) ([]string, error) {
+	all, err := c.client.sobjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range c.objects {
+		wanted[strings.ToLower(name)] = true
+	}
+	if len(wanted) == 0 {
+		for _, name := range standardObjects {
+			wanted[strings.ToLower(name)] = true
+		}
+	}
+
+	var names []string
+	for _, o := range all {
+		if !o.Queryable {
+			continue
+		}
+		custom := len(c.objects) == 0 && o.Custom && strings.HasSuffix(o.Name, "__c")
+		if wanted[strings.ToLower(o.Name)] || custom {
+			names = append(names, o.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// describe returns the describe of an sObject, mapping a missing object to a not found error
+func (c *Connection) describe(ctx context.Context, object string) (*sobjectDescribe, error) {
+	desc, err := c.client.describe(ctx, object)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, adapter.NewNotFoundError(dbcapabilities.Salesforce, "object", object)
+		}
+		return nil, adapter.WrapError(dbcapabilities.Salesforce, "describe", err)
+	}
+	return desc, nil
+}
+
+// convertCollection converts an sObject describe to a UnifiedModel collection
+func convertCollection(desc *sobjectDescribe) unifiedmodel.Collection {
+	collection := unifiedmodel.Collection{
+		Name:    desc.Name,
+		Comment: desc.Label,
+		Fields:  make(map[string]unifiedmodel.Field, len(desc.Fields)),
+		Indexes: map[string]unifiedmodel.Index{
+			"pk_" + desc.Name: {Name: "pk_" + desc.Name, Columns: []string{"Id"}, Unique: true},
+		},
+		Options: map[string]any{
+			"label":      desc.Label,
+			"custom":     desc.Custom,
+			"createable": desc.Createable,
+			"updateable": desc.Updateable,
+			"deletable":  desc.Deletable,
+		},
+	}
+
+	for i, f := range desc.Fields {
+		options := fieldOptions(f)
+		options["ordinal_position"] = i + 1
+		collection.Fields[f.Name] = unifiedmodel.Field{
+			Name:     f.Name,
+			Type:     unifiedType(f),
+			Required: !f.Nillable && f.Createable && !f.DefaultedOnCreate,
+			Options:  options,
+		}
+		if f.ExternalID || (f.Unique && f.Type != "id") {
+			name := "idx_" + desc.Name + "_" + f.Name
+			collection.Indexes[name] = unifiedmodel.Index{Name: name, Columns: []string{f.Name}, Unique: f.Unique}
+		}
+	}
+	return collection
+}
+
+// fieldOptions returns the Salesforce specific field metadata kept in options
+func fieldOptions(f fieldInfo) map[string]any {
+	options := map[string]any{
+		"label":           f.Label,
+		"salesforce_type": f.Type,
+		"createable":      f.Createable,
+		"updateable":      f.Updateable,
+	}
+	if f.Length > 0 {
+		options["length"] = f.Length
+	}
+	if f.Precision > 0 {
+		options["precision"] = f.Precision
+		options["scale"] = f.Scale
+	}
+	if f.Unique {
+		options["unique"] = true
+	}
+	if f.ExternalID {
+		options["external_id"] = true
+	}
+	if f.Calculated {
+		options["calculated"] = true
+		if f.CalculatedFormula != "" {
+			options["formula"] = f.CalculatedFormula
+		}
+	}
+	if f.DefaultValue != nil {
+		options["default_value"] = f.DefaultValue
+	}
+	if len(f.ReferenceTo) > 0 {
+		options["reference_to"] = f.ReferenceTo
+		options["relationship_name"] = f.RelationshipName
+	}
+	if f.InlineHelpText != "" {
+		options["description"] = f.InlineHelpText
+	}
+	if f.CompoundFieldName != "" {
+		options["compound_field"] = f.CompoundFieldName
+	}
+	if len(f.PicklistValues) > 0 {
+		values := make([]string, 0, len(f.PicklistValues))
+		for _, v := range f.PicklistValues {
+			if v.Active {
+				values = append(values, v.Value)
+			}
+		}
+		options["picklist_values"] = values
+	}
+	return options
+}
+
+// unifiedType maps a Salesforce field type to a unified data type
+func unifiedType(f fieldInfo) string {
+	switch f.Type {
+	case "id", "reference":
+		return "varchar(18)"
+	case "boolean":
+		return "boolean"
+	case "int":
+		return "integer"
+	case "long":
+		return "bigint"
+	case "double", "currency", "percent":
+		return "decimal"
+	case "date":
+		return "date"
+	case "datetime":
+		return "timestamp"
+	case "time":
+		return "time"
+	case "base64":
+		return "bytes"
+	case "address", "location", "complexvalue", "anyType":
+		return "json"
+	case "textarea", "multipicklist", "encryptedstring":
+		return "text"
+	default:
+		// string, picklist, email, phone, url, combobox
+		return "string"
+	}
+}
+
+// isNotFound reports whether err is a 404 response
+func isNotFound(err error) bool {
+	var apiErr *restclient.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
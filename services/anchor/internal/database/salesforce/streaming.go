@@ -0,0 +1,212 @@
+package salesforce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/services/anchor/internal/database/restclient"
+)
+
+// Replay IDs understood by the Streaming API besides a stored event replay ID
+const (
+	replayNewEvents      int64 = -1
+	replayRetainedEvents int64 = -2
+)
+
+// errRehandshake is returned when the server dropped the client and a new handshake is needed
+var errRehandshake = errors.New("streaming client must handshake again")
+
+// bayeuxMessage is a message received from the Streaming API (CometD/Bayeux).
+type bayeuxMessage struct {
+	Channel      string          `json:"channel"`
+	ClientID     string          `json:"clientId"`
+	Successful   bool            `json:"successful"`
+	Error        string          `json:"error"`
+	Subscription string          `json:"subscription"`
+	Data         json.RawMessage `json:"data"`
+	Advice       *struct {
+		Reconnect string `json:"reconnect"`
+		Interval  int64  `json:"interval"`
+	} `json:"advice"`
+}
+
+// changeEvent is the data of a Change Data Capture event message.
+type changeEvent struct {
+	Event struct {
+		ReplayID int64 `json:"replayId"`
+	} `json:"event"`
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// streamingClient is a long-polling CometD client for the Salesforce Streaming API.
+type streamingClient struct {
+	endpoint string
+	tokens   *restclient.TokenSource
+	http     *http.Client
+
+	mu       sync.Mutex
+	clientID string
+}
+
+// newStreamingClient creates a streaming client for the org of the REST client
+func newStreamingClient(c *Client) *streamingClient {
+	jar, _ := cookiejar.New(nil)
+	return &streamingClient{
+		endpoint: c.instanceURL + "/cometd/" + c.apiVersion,
+		tokens:   c.tokens,
+		// Long polls are held open by the server for up to 110 seconds
+		http: &http.Client{Jar: jar, Timeout: 2 * time.Minute},
+	}
+}
+
+// send posts Bayeux messages and returns the response messages
+func (s *streamingClient) send(ctx context.Context, messages ...map[string]interface{}) ([]bayeuxMessage, error) {
+	payload, err := json.Marshal(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := s.tokens.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := s.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			s.tokens.Invalidate()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, &restclient.APIError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+		}
+
+		var out []bayeuxMessage
+		if err := json.Unmarshal(body, &out); err != nil {
+			return nil, fmt.Errorf("invalid streaming response: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// handshake starts a new streaming session
+func (s *streamingClient) handshake(ctx context.Context) error {
+	resp, err := s.send(ctx, map[string]interface{}{
+		"channel":                  "/meta/handshake",
+		"version":                  "1.0",
+		"supportedConnectionTypes": []string{"long-polling"},
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp) == 0 || !resp[0].Successful {
+		return fmt.Errorf("streaming handshake failed: %s", firstError(resp))
+	}
+
+	s.mu.Lock()
+	s.clientID = resp[0].ClientID
+	s.mu.Unlock()
+	return nil
+}
+
+// subscribe subscribes to a channel, replaying events after replayID
+func (s *streamingClient) subscribe(ctx context.Context, channel string, replayID int64) error {
+	resp, err := s.send(ctx, map[string]interface{}{
+		"channel":      "/meta/subscribe",
+		"clientId":     s.id(),
+		"subscription": channel,
+		"ext":          map[string]interface{}{"replay": map[string]int64{channel: replayID}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, m := range resp {
+		if m.Channel == "/meta/subscribe" && !m.Successful {
+			return fmt.Errorf("subscription to %s failed: %s", channel, m.Error)
+		}
+	}
+	return nil
+}
+
+// connect long-polls for events and returns the data messages received
+func (s *streamingClient) connect(ctx context.Context) ([]bayeuxMessage, error) {
+	resp, err := s.send(ctx, map[string]interface{}{
+		"channel":        "/meta/connect",
+		"clientId":       s.id(),
+		"connectionType": "long-polling",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []bayeuxMessage
+	for _, m := range resp {
+		if m.Channel == "/meta/connect" {
+			if !m.Successful {
+				if (m.Advice != nil && m.Advice.Reconnect == "handshake") || strings.HasPrefix(m.Error, "403::") {
+					return events, errRehandshake
+				}
+				return events, fmt.Errorf("streaming connect failed: %s", m.Error)
+			}
+			continue
+		}
+		events = append(events, m)
+	}
+	return events, nil
+}
+
+// disconnect ends the streaming session
+func (s *streamingClient) disconnect(ctx context.Context) error {
+	if s.id() == "" {
+		return nil
+	}
+	_, err := s.send(ctx, map[string]interface{}{"channel": "/meta/disconnect", "clientId": s.id()})
+	return err
+}
+
+func (s *streamingClient) id() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clientID
+}
+
+func firstError(messages []bayeuxMessage) string {
+	for _, m := range messages {
+		if m.Error != "" {
+			return m.Error
+		}
+	}
+	return "no response"
+}
+
+// changeEventChannel returns the Change Data Capture channel of an sObject
+func changeEventChannel(object string) string {
+	if strings.HasSuffix(object, "__c") {
+		return "/data/" + strings.TrimSuffix(object, "__c") + "__ChangeEvent"
+	}
+	return "/data/" + object + "ChangeEvent"
+}
@@ -1,10 +1,17 @@
 package snowflake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/snowflakedb/gosnowflake"
 )
 
 // FetchData retrieves data from a specified table
@@ -122,6 +129,89 @@ func InsertData(db *sql.DB, tableName string, data []map[string]interface{}) (in
 	return totalRowsAffected, nil
 }
 
+// UpsertData merges rows into a table, updating the existing rows whose unique columns match
+func UpsertData(ctx context.Context, db *sql.DB, tableName string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if len(uniqueColumns) == 0 {
+		return 0, fmt.Errorf("upsert into %s requires at least one unique column", tableName)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	columns := make([]string, 0, len(data[0]))
+	for col := range data[0] {
+		columns = append(columns, col)
+	}
+
+	unique := make(map[string]bool, len(uniqueColumns))
+	for _, col := range uniqueColumns {
+		unique[col] = true
+	}
+
+	sourceColumns := make([]string, len(columns))
+	insertValues := make([]string, len(columns))
+	updateSet := make([]string, 0, len(columns))
+	for i, col := range columns {
+		quoted := quoteIdentifier(col)
+		sourceColumns[i] = "? AS " + quoted
+		insertValues[i] = "source." + quoted
+		if !unique[col] {
+			updateSet = append(updateSet, fmt.Sprintf("target.%s = source.%s", quoted, quoted))
+		}
+	}
+
+	matchConditions := make([]string, len(uniqueColumns))
+	for i, col := range uniqueColumns {
+		quoted := quoteIdentifier(col)
+		matchConditions[i] = fmt.Sprintf("target.%s = source.%s", quoted, quoted)
+	}
+
+	query := fmt.Sprintf(
+		"MERGE INTO %s AS target USING (SELECT %s) AS source ON %s",
+		quoteIdentifier(tableName),
+		strings.Join(sourceColumns, ", "),
+		strings.Join(matchConditions, " AND "),
+	)
+	if len(updateSet) > 0 {
+		query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(updateSet, ", ")
+	}
+	query += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+		strings.Join(quoteIdentifiers(columns), ", "),
+		strings.Join(insertValues, ", "),
+	)
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("error preparing statement: %v", err)
+	}
+	defer stmt.Close()
+
+	var totalRowsAffected int64
+	for _, row := range data {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = row[col]
+		}
+
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return 0, fmt.Errorf("error executing merge: %v", err)
+		}
+		totalRowsAffected++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing transaction: %v", err)
+	}
+
+	return totalRowsAffected, nil
+}
+
 // WipeDatabase removes all data from the database
 func WipeDatabase(db *sql.DB) error {
 	// Get all tables in the current schema
@@ -165,66 +255,104 @@ func WipeDatabase(db *sql.DB) error {
 	return nil
 }
 
-// BulkInsertData performs a bulk insert operation using Snowflake's COPY command
-func BulkInsertData(db *sql.DB, tableName string, data []map[string]interface{}) (int64, error) {
+// bulkFileID makes the names of the files staged by BulkInsertData unique
+var bulkFileID uint64
+
+// BulkInsertData loads data into a specified table by staging it as a CSV file in the
+// table stage with PUT and loading the file with COPY INTO. The file is streamed from
+// memory and removed from the stage once loaded.
+func BulkInsertData(ctx context.Context, db *sql.DB, tableName string, columns []string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
 		return 0, nil
 	}
 
-	// Get columns from the first row
-	columns := make([]string, 0, len(data[0]))
-	for col := range data[0] {
-		columns = append(columns, col)
+	var buf bytes.Buffer
+	for _, row := range data {
+		if err := writeBulkRow(&buf, columns, row); err != nil {
+			return 0, err
+		}
 	}
 
-	// Create a temporary stage for the data
-	stageName := fmt.Sprintf("%s_temp_stage", tableName)
-	_, err := db.Exec(fmt.Sprintf("CREATE OR REPLACE TEMPORARY STAGE %s", stageName))
-	if err != nil {
-		return 0, fmt.Errorf("error creating temporary stage: %v", err)
-	}
+	fileName := fmt.Sprintf("redb_bulk_%d_%d.csv", time.Now().UnixNano(), atomic.AddUint64(&bulkFileID, 1))
+	stage := "@%" + quoteIdentifier(tableName)
 
-	// Put the data into the stage (this would typically use Snowflake's PUT command)
-	// For simplicity, we'll use a direct INSERT instead of the staging process
+	// The driver uploads the stream instead of reading the local file named in PUT
+	putCtx := gosnowflake.WithFileStream(ctx, &buf)
+	putQuery := fmt.Sprintf("PUT 'file:///tmp/%s' %s AUTO_COMPRESS=TRUE OVERWRITE=TRUE", fileName, stage)
+	if _, err := db.ExecContext(putCtx, putQuery); err != nil {
+		return 0, fmt.Errorf("error staging data for table %s: %v", tableName, err)
+	}
 
-	// Start a transaction
-	tx, err := db.Begin()
+	copyQuery := fmt.Sprintf(
+		"COPY INTO %s (%s) FROM %s FILES = ('%s.gz') "+
+			"FILE_FORMAT = (TYPE = CSV FIELD_OPTIONALLY_ENCLOSED_BY = '\"' NULL_IF = ('\\\\N')) PURGE = TRUE",
+		quoteIdentifier(tableName), strings.Join(quoteIdentifiers(columns), ", "), stage, fileName)
+	rows, err := db.QueryContext(ctx, copyQuery)
 	if err != nil {
-		return 0, fmt.Errorf("error starting transaction: %v", err)
+		return 0, fmt.Errorf("error copying data into table %s: %v", tableName, err)
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Prepare the statement
-	stmt, err := tx.Prepare(fmt.Sprintf(
-		"INSERT INTO %s (%s) VALUES (%s)",
-		quoteIdentifier(tableName),
-		strings.Join(quoteIdentifiers(columns), ", "),
-		strings.Join(generatePlaceholders(len(columns)), ", "),
-	))
+	// COPY INTO returns one row per loaded file
+	resultColumns, err := rows.Columns()
 	if err != nil {
-		return 0, fmt.Errorf("error preparing statement: %v", err)
+		return 0, err
+	}
+	var loaded int64
+	for rows.Next() {
+		values := make([]interface{}, len(resultColumns))
+		pointers := make([]interface{}, len(resultColumns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return 0, fmt.Errorf("error reading copy result: %v", err)
+		}
+		for i, col := range resultColumns {
+			if strings.EqualFold(col, "rows_loaded") {
+				count, err := strconv.ParseInt(fmt.Sprintf("%v", values[i]), 10, 64)
+				if err != nil {
+					return 0, fmt.Errorf("error reading copy result: %v", err)
+				}
+				loaded += count
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error reading copy result: %v", err)
 	}
-	defer stmt.Close()
 
-	// Insert each row
-	for _, row := range data {
-		values := make([]interface{}, len(columns))
-		for i, col := range columns {
-			values[i] = row[col]
+	return loaded, nil
+}
+
+// writeBulkRow writes a row as a line of the CSV file loaded by BulkInsertData. Every
+// value is enclosed in quotes so that empty strings are kept apart from NULL.
+func writeBulkRow(buf *bytes.Buffer, columns []string, row map[string]interface{}) error {
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
 		}
 
-		_, err := stmt.Exec(values...)
-		if err != nil {
-			return 0, fmt.Errorf("error executing insert: %v", err)
+		var text string
+		switch v := row[col].(type) {
+		case nil:
+			buf.WriteString(`\N`)
+			continue
+		case time.Time:
+			text = v.Format(time.RFC3339Nano)
+		default:
+			var err error
+			if text, err = adapter.FormatBulkValue(v); err != nil {
+				return fmt.Errorf("error formatting column %s: %v", col, err)
+			}
 		}
-	}
 
-	// Commit the transaction
-	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("error committing transaction: %v", err)
+		buf.WriteByte('"')
+		buf.WriteString(strings.ReplaceAll(text, `"`, `""`))
+		buf.WriteByte('"')
 	}
-
-	return int64(len(data)), nil
+	buf.WriteByte('\n')
+	return nil
 }
 
 // ExportData exports data from a table to a specified format
@@ -50,12 +50,24 @@ func (d *DataOps) Insert(ctx context.Context, tableName string, data []map[strin
 	return count, nil
 }
 
+func (d *DataOps) BulkInsert(ctx context.Context, tableName string, data []map[string]interface{}) (int64, error) {
+	count, err := BulkInsertData(ctx, d.conn.db, tableName, adapter.BulkColumns(data), data)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Snowflake, "bulk_insert", err)
+	}
+	return count, nil
+}
+
 func (d *DataOps) Update(ctx context.Context, tableName string, data []map[string]interface{}, whereColumns []string) (int64, error) {
 	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Snowflake, "update data", "not yet implemented")
 }
 
 func (d *DataOps) Upsert(ctx context.Context, tableName string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
-	return 0, adapter.NewUnsupportedOperationError(dbcapabilities.Snowflake, "upsert data", "not yet implemented")
+	count, err := UpsertData(ctx, d.conn.db, tableName, data, uniqueColumns)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.Snowflake, "upsert_data", err)
+	}
+	return count, nil
 }
 
 func (d *DataOps) Delete(ctx context.Context, tableName string, conditions map[string]interface{}) (int64, error) {
@@ -73,6 +85,11 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	}, nil
 }
 
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.db, query, args...)
 	if err != nil {
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+
+	_ "modernc.org/sqlite"
+)
+
+// memoryPath is the special path for an in-memory database
+const memoryPath = ":memory:"
+
+// Adapter implements adapter.DatabaseAdapter for SQLite.
+// The database file path is taken from DatabaseName (or options.path); host and port are ignored.
+//
+// Options:
+//   - path: database file path, overrides DatabaseName
+//   - read_only: open the file in read-only mode
+//   - create: create the file if it does not exist (otherwise a missing file is an error)
+//   - busy_timeout_ms: how long to wait on a locked database, defaults to 5000
+type Adapter struct{}
+
+// NewAdapter creates a new SQLite adapter instance.
+func NewAdapter() adapter.DatabaseAdapter {
+	return &Adapter{}
+}
+
+// Type returns the database type identifier.
+func (a *Adapter) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.SQLite
+}
+
+// Capabilities returns the capability metadata.
+func (a *Adapter) Capabilities() dbcapabilities.Capability {
+	return dbcapabilities.MustGet(dbcapabilities.SQLite)
+}
+
+// Connect opens the database file.
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	path := config.DatabaseName
+	if p, ok := config.Options["path"].(string); ok && p != "" {
+		path = p
+	}
+	if path == "" {
+		return nil, adapter.NewConfigurationError(dbcapabilities.SQLite, "databaseName", "database file path is required")
+	}
+
+	readOnly := optionBool(config, "read_only")
+	if path != memoryPath {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, adapter.NewConfigurationError(dbcapabilities.SQLite, "databaseName", err.Error())
+		}
+		path = abs
+
+		if _, err := os.Stat(path); err != nil {
+			if !os.IsNotExist(err) || readOnly || !optionBool(config, "create") {
+				return nil, adapter.NewConnectionError(dbcapabilities.SQLite, config.Host, config.Port, err)
+			}
+		}
+	}
+
+	db, err := sql.Open("sqlite", buildDSN(path, readOnly, optionInt(config, "busy_timeout_ms", 5000)))
+	if err != nil {
+		return nil, adapter.NewConnectionError(dbcapabilities.SQLite, config.Host, config.Port, err)
+	}
+
+	// SQLite allows a single writer; an in-memory database also only exists on its own connection
+	if !readOnly {
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, adapter.NewConnectionError(dbcapabilities.SQLite, config.Host, config.Port, err)
+	}
+
+	return &Connection{
+		id:        config.DatabaseID,
+		db:        db,
+		path:      path,
+		readOnly:  readOnly,
+		config:    config,
+		adapter:   a,
+		connected: 1,
+	}, nil
+}
+
+// ConnectInstance is not supported; each SQLite file is connected as a database.
+func (a *Adapter) ConnectInstance(ctx context.Context, config adapter.InstanceConfig) (adapter.InstanceConnection, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.SQLite, "instance connection", "sqlite files are connected as individual databases")
+}
+
+// buildDSN builds a modernc.org/sqlite DSN for the file
+func buildDSN(path string, readOnly bool, busyTimeoutMS int) string {
+	query := url.Values{}
+	if readOnly {
+		query.Set("mode", "ro")
+	} else {
+		query.Set("mode", "rwc")
+	}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeoutMS))
+	query.Add("_pragma", "foreign_keys(1)")
+
+	if path == memoryPath {
+		return memoryPath + "?" + query.Encode()
+	}
+	return "file:" + filepath.ToSlash(path) + "?" + query.Encode()
+}
+
+func optionBool(config adapter.ConnectionConfig, key string) bool {
+	switch v := config.Options[key].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true") || v == "1"
+	}
+	return false
+}
+
+func optionInt(config adapter.ConnectionConfig, key string, def int) int {
+	switch v := config.Options[key].(type) {
+	case int:
+		if v > 0 {
+			return v
+		}
+	case float64:
+		if v > 0 {
+			return int(v)
+		}
+	}
+	return def
+}
+
+// Connection implements adapter.Connection for SQLite.
+type Connection struct {
+	id        string
+	db        *sql.DB
+	path      string
+	readOnly  bool
+	config    adapter.ConnectionConfig
+	adapter   *Adapter
+	connected int32
+}
+
+// ID returns the connection identifier.
+func (c *Connection) ID() string {
+	return c.id
+}
+
+// Type returns the database type.
+func (c *Connection) Type() dbcapabilities.DatabaseType {
+	return dbcapabilities.SQLite
+}
+
+// IsConnected returns whether the connection is active.
+func (c *Connection) IsConnected() bool {
+	return atomic.LoadInt32(&c.connected) == 1
+}
+
+// Ping checks if the connection is alive.
+func (c *Connection) Ping(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Close closes the connection.
+func (c *Connection) Close() error {
+	atomic.StoreInt32(&c.connected, 0)
+	return c.db.Close()
+}
+
+// SchemaOperations returns the schema operator for SQLite.
+func (c *Connection) SchemaOperations() adapter.SchemaOperator {
+	return &SchemaOps{conn: c}
+}
+
+// DataOperations returns the data operator for SQLite.
+func (c *Connection) DataOperations() adapter.DataOperator {
+	return &DataOps{conn: c}
+}
+
+// ReplicationOperations returns the replication operator for SQLite.
+func (c *Connection) ReplicationOperations() adapter.ReplicationOperator {
+	return adapter.NewUnsupportedReplicationOperator(dbcapabilities.SQLite)
+}
+
+// MetadataOperations returns the metadata operator for SQLite.
+func (c *Connection) MetadataOperations() adapter.MetadataOperator {
+	return &MetadataOps{conn: c}
+}
+
+// Raw returns the underlying *sql.DB.
+func (c *Connection) Raw() interface{} {
+	return c.db
+}
+
+// Config returns the connection configuration.
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+// Adapter returns the database adapter.
+func (c *Connection) Adapter() adapter.DatabaseAdapter {
+	return c.adapter
+}
@@ -0,0 +1,418 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FetchData retrieves rows from a table, optionally restricted to columns. A limit <= 0 fetches all rows.
+func FetchData(ctx context.Context, db *sql.DB, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	if table == "" {
+		return nil, fmt.Errorf("table name cannot be empty")
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList(columns), quoteIdent(table))
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return queryRows(ctx, db, query)
+}
+
+// StreamTableData returns a batch of rows starting at offset, ordered by orderBy or the rowid.
+func StreamTableData(ctx context.Context, db *sql.DB, table string, columns []string, orderBy string, batchSize int32, offset int64) ([]map[string]interface{}, bool, string, error) {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	order := "rowid"
+	if orderBy != "" {
+		order = quoteIdent(orderBy)
+	}
+
+	// Fetch one extra row to find out whether another batch follows
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s LIMIT %d OFFSET %d",
+		selectList(columns), quoteIdent(table), order, batchSize+1, offset)
+	rows, err := queryRows(ctx, db, query)
+	if err != nil && orderBy == "" && strings.Contains(err.Error(), "no such column: rowid") {
+		// WITHOUT ROWID tables have no rowid; fall back to the natural order
+		query = fmt.Sprintf("SELECT %s FROM %s LIMIT %d OFFSET %d", selectList(columns), quoteIdent(table), batchSize+1, offset)
+		rows, err = queryRows(ctx, db, query)
+	}
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	hasMore := len(rows) > int(batchSize)
+	if hasMore {
+		rows = rows[:batchSize]
+	}
+	next := offset + int64(len(rows))
+	return rows, hasMore, strconv.FormatInt(next, 10), nil
+}
+
+// InsertData inserts rows in a single transaction.
+func InsertData(ctx context.Context, db *sql.DB, table string, data []map[string]interface{}) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	columns := unionColumns(data)
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(table), quoteIdents(columns), placeholders(len(columns)))
+
+	return execBatch(ctx, db, query, data, func(row map[string]interface{}) []interface{} {
+		return rowValues(row, columns)
+	})
+}
+
+// UpsertData inserts rows, updating the existing row when the unique columns conflict.
+func UpsertData(ctx context.Context, db *sql.DB, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if len(uniqueColumns) == 0 {
+		return 0, fmt.Errorf("unique columns are required for upsert")
+	}
+
+	columns := unionColumns(data)
+	unique := make(map[string]bool, len(uniqueColumns))
+	for _, c := range uniqueColumns {
+		unique[c] = true
+	}
+
+	var updates []string
+	for _, c := range columns {
+		if !unique[c] {
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", quoteIdent(c), quoteIdent(c)))
+		}
+	}
+	conflict := "DO NOTHING"
+	if len(updates) > 0 {
+		conflict = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+		quoteIdent(table), quoteIdents(columns), placeholders(len(columns)), quoteIdents(uniqueColumns), conflict)
+
+	return execBatch(ctx, db, query, data, func(row map[string]interface{}) []interface{} {
+		return rowValues(row, columns)
+	})
+}
+
+// UpdateData updates the rows matching the where columns of each data row.
+func UpdateData(ctx context.Context, db *sql.DB, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	if len(whereColumns) == 0 {
+		return 0, fmt.Errorf("where columns are required for update")
+	}
+
+	where := make(map[string]bool, len(whereColumns))
+	for _, c := range whereColumns {
+		where[c] = true
+	}
+	var setColumns []string
+	for _, c := range unionColumns(data) {
+		if !where[c] {
+			setColumns = append(setColumns, c)
+		}
+	}
+	if len(setColumns) == 0 {
+		return 0, nil
+	}
+
+	sets := make([]string, len(setColumns))
+	for i, c := range setColumns {
+		sets[i] = quoteIdent(c) + " = ?"
+	}
+	conds := make([]string, len(whereColumns))
+	for i, c := range whereColumns {
+		conds[i] = quoteIdent(c) + " IS ?"
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		quoteIdent(table), strings.Join(sets, ", "), strings.Join(conds, " AND "))
+
+	return execBatch(ctx, db, query, data, func(row map[string]interface{}) []interface{} {
+		return append(rowValues(row, setColumns), rowValues(row, whereColumns)...)
+	})
+}
+
+// DeleteData deletes the rows matching all conditions. Empty conditions delete every row.
+func DeleteData(ctx context.Context, db *sql.DB, table string, conditions map[string]interface{}) (int64, error) {
+	keys := make([]string, 0, len(conditions))
+	for k := range conditions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	query := "DELETE FROM " + quoteIdent(table)
+	args := make([]interface{}, 0, len(keys))
+	if len(keys) > 0 {
+		conds := make([]string, len(keys))
+		for i, k := range keys {
+			conds[i] = quoteIdent(k) + " IS ?"
+			args = append(args, sanitizeValue(conditions[k]))
+		}
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ExecuteQuery runs a query and returns the rows as maps.
+func ExecuteQuery(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := queryRows(ctx, db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(rows))
+	for i, row := range rows {
+		result[i] = row
+	}
+	return result, nil
+}
+
+// ExecuteCountQuery runs a query returning a single count.
+func ExecuteCountQuery(ctx context.Context, db *sql.DB, query string) (int64, error) {
+	var count int64
+	if err := db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetRowCount returns the exact number of rows in a table, optionally filtered by a where clause.
+func GetRowCount(ctx context.Context, db *sql.DB, table string, whereClause string) (int64, error) {
+	query := "SELECT COUNT(*) FROM " + quoteIdent(table)
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	return ExecuteCountQuery(ctx, db, query)
+}
+
+// WipeDatabase deletes all rows from every user table and resets AUTOINCREMENT counters.
+func WipeDatabase(ctx context.Context, db *sql.DB) error {
+	tables, err := ListTables(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check foreign keys at commit so tables can be emptied in any order
+	if _, err := tx.ExecContext(ctx, "PRAGMA defer_foreign_keys = ON"); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quoteIdent(table)); err != nil {
+			return fmt.Errorf("error wiping table %s: %v", table, err)
+		}
+	}
+
+	var hasSequence int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'sqlite_sequence'`).Scan(&hasSequence); err != nil {
+		return err
+	}
+	if hasSequence > 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM sqlite_sequence"); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// execBatch runs a statement once per row inside one transaction
+func execBatch(ctx context.Context, db *sql.DB, query string, data []map[string]interface{}, args func(map[string]interface{}) []interface{}) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var affected int64
+	for _, row := range data {
+		result, err := stmt.ExecContext(ctx, args(row)...)
+		if err != nil {
+			return 0, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		affected += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// queryRows runs a query and scans every row into a map
+func queryRows(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	_, result, _, err := scanRows(rows, 0)
+	return result, err
+}
+
+// scanRows scans at most maxRows rows into maps, or every row if maxRows is 0. truncated
+// reports whether there were more rows.
+func scanRows(rows *sql.Rows, maxRows int) (columns []string, result []map[string]interface{}, truncated bool, err error) {
+	columns, err = rows.Columns()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	for rows.Next() {
+		if maxRows > 0 && len(result) == maxRows {
+			return columns, result, true, nil
+		}
+
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, nil, false, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = convertValue(values[i], types[i].DatabaseTypeName())
+		}
+		result = append(result, row)
+	}
+	return columns, result, false, rows.Err()
+}
+
+// ExecuteReadOnlyQuery runs a query on a connection with query_only set, so that SQLite
+// rejects any statement modifying the database, and returns its columns and at most maxRows
+// rows. truncated reports whether the query returned more rows.
+func ExecuteReadOnlyQuery(ctx context.Context, db *sql.DB, query string, maxRows int) (columns []string, result []map[string]interface{}, truncated bool, err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to make the connection read-only: %w", err)
+	}
+	defer func() {
+		// A connection left read-only must not go back to the pool
+		if _, err := conn.ExecContext(context.Background(), "PRAGMA query_only = OFF"); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer rows.Close()
+
+	columns, result, truncated, err = scanRows(rows, maxRows)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if result == nil {
+		result = []map[string]interface{}{}
+	}
+	return columns, result, truncated, nil
+}
+
+// convertValue keeps blobs as bytes and turns text returned as bytes into strings
+func convertValue(value interface{}, declType string) interface{} {
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	t := strings.ToUpper(declType)
+	if t == "" || strings.Contains(t, "BLOB") || strings.Contains(t, "BINARY") {
+		return b
+	}
+	return string(b)
+}
+
+// sanitizeValue converts values SQLite cannot bind directly
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	default:
+		return value
+	}
+}
+
+// unionColumns returns the sorted union of keys over all rows
+func unionColumns(data []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range data {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// rowValues returns the values of a row in column order; missing keys bind as NULL
+func rowValues(row map[string]interface{}, columns []string) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = sanitizeValue(row[c])
+	}
+	return values
+}
+
+func selectList(columns []string) string {
+	if len(columns) == 0 {
+		return "*"
+	}
+	return quoteIdents(columns)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
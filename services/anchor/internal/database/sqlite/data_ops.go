@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// DataOps implements adapter.DataOperator for SQLite.
+type DataOps struct {
+	conn *Connection
+}
+
+// Fetch retrieves data from a table with a limit.
+func (d *DataOps) Fetch(ctx context.Context, table string, limit int) ([]map[string]interface{}, error) {
+	return d.FetchWithColumns(ctx, table, nil, limit)
+}
+
+// FetchWithColumns retrieves specific columns from a table.
+func (d *DataOps) FetchWithColumns(ctx context.Context, table string, columns []string, limit int) ([]map[string]interface{}, error) {
+	data, err := FetchData(ctx, d.conn.db, table, columns, limit)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "fetch", err)
+	}
+	return data, nil
+}
+
+// Insert inserts data into a table.
+func (d *DataOps) Insert(ctx context.Context, table string, data []map[string]interface{}) (int64, error) {
+	if err := d.checkWritable("insert"); err != nil {
+		return 0, err
+	}
+	rowsAffected, err := InsertData(ctx, d.conn.db, table, data)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "insert", err)
+	}
+	return rowsAffected, nil
+}
+
+// Update updates existing data in a table.
+func (d *DataOps) Update(ctx context.Context, table string, data []map[string]interface{}, whereColumns []string) (int64, error) {
+	if err := d.checkWritable("update"); err != nil {
+		return 0, err
+	}
+	rowsAffected, err := UpdateData(ctx, d.conn.db, table, data, whereColumns)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "update", err)
+	}
+	return rowsAffected, nil
+}
+
+// Upsert inserts or updates data based on unique columns.
+func (d *DataOps) Upsert(ctx context.Context, table string, data []map[string]interface{}, uniqueColumns []string) (int64, error) {
+	if err := d.checkWritable("upsert"); err != nil {
+		return 0, err
+	}
+	rowsAffected, err := UpsertData(ctx, d.conn.db, table, data, uniqueColumns)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "upsert", err)
+	}
+	return rowsAffected, nil
+}
+
+// Delete deletes data from a table based on conditions.
+func (d *DataOps) Delete(ctx context.Context, table string, conditions map[string]interface{}) (int64, error) {
+	if err := d.checkWritable("delete"); err != nil {
+		return 0, err
+	}
+	rowsAffected, err := DeleteData(ctx, d.conn.db, table, conditions)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "delete", err)
+	}
+	return rowsAffected, nil
+}
+
+// Stream streams data from a table in batches.
+func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adapter.StreamResult, error) {
+	data, hasMore, nextCursor, err := StreamTableData(ctx, d.conn.db, params.Table, params.Columns, params.OrderBy, params.BatchSize, params.Offset)
+	if err != nil {
+		return adapter.StreamResult{}, adapter.WrapError(dbcapabilities.SQLite, "stream", err)
+	}
+
+	return adapter.StreamResult{
+		Data:       data,
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// FetchStream returns the rows of a table in batches read through Stream.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return adapter.NewOffsetBatchIterator(ctx, d, table, batchSize), nil
+}
+
+// ExecuteQuery executes a raw SQL query.
+func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
+	result, err := ExecuteQuery(ctx, d.conn.db, query, args...)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "execute_query", err)
+	}
+	return result, nil
+}
+
+// ExecuteReadOnlyQuery executes a query on a read-only connection, implementing
+// adapter.ReadOnlyQueryExecutor.
+func (d *DataOps) ExecuteReadOnlyQuery(ctx context.Context, query string, maxRows int) (*adapter.QueryResult, error) {
+	columns, rows, truncated, err := ExecuteReadOnlyQuery(ctx, d.conn.db, query, maxRows)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "execute_read_only_query", err)
+	}
+	return &adapter.QueryResult{Columns: columns, Rows: rows, Truncated: truncated}, nil
+}
+
+// ExecuteCountQuery executes a count query.
+func (d *DataOps) ExecuteCountQuery(ctx context.Context, query string) (int64, error) {
+	count, err := ExecuteCountQuery(ctx, d.conn.db, query)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "execute_count_query", err)
+	}
+	return count, nil
+}
+
+// GetRowCount returns the number of rows in a table. SQLite counts are always exact.
+func (d *DataOps) GetRowCount(ctx context.Context, table string, whereClause string) (int64, bool, error) {
+	count, err := GetRowCount(ctx, d.conn.db, table, whereClause)
+	if err != nil {
+		return 0, false, adapter.WrapError(dbcapabilities.SQLite, "get_row_count", err)
+	}
+	return count, true, nil
+}
+
+// Wipe removes all data from the database.
+func (d *DataOps) Wipe(ctx context.Context) error {
+	if err := d.checkWritable("wipe"); err != nil {
+		return err
+	}
+	if err := WipeDatabase(ctx, d.conn.db); err != nil {
+		return adapter.WrapError(dbcapabilities.SQLite, "wipe", err)
+	}
+	return nil
+}
+
+// checkWritable rejects writes on read-only connections before they reach SQLite
+func (d *DataOps) checkWritable(operation string) error {
+	if d.conn.readOnly {
+		return adapter.NewUnsupportedOperationError(dbcapabilities.SQLite, operation, "database is opened read-only")
+	}
+	return nil
+}
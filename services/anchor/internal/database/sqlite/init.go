@@ -0,0 +1,8 @@
+package sqlite
+
+import "github.com/redbco/redb-open/pkg/anchor/adapter"
+
+func init() {
+	// Register SQLite adapter with the global registry
+	adapter.Register(NewAdapter())
+}
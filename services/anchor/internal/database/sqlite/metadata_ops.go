@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// MetadataOps implements adapter.MetadataOperator for SQLite.
+type MetadataOps struct {
+	conn *Connection
+}
+
+// CollectDatabaseMetadata collects metadata about the database file.
+func (m *MetadataOps) CollectDatabaseMetadata(ctx context.Context) (map[string]interface{}, error) {
+	metadata, err := CollectDatabaseMetadata(ctx, m.conn.db)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "collect_database_metadata", err)
+	}
+	metadata["path"] = m.conn.path
+	metadata["read_only"] = m.conn.readOnly
+	return metadata, nil
+}
+
+// CollectInstanceMetadata is not applicable; SQLite has no server instance.
+func (m *MetadataOps) CollectInstanceMetadata(ctx context.Context) (map[string]interface{}, error) {
+	return nil, adapter.NewConfigurationError(
+		dbcapabilities.SQLite,
+		"metadata",
+		"instance metadata collection not supported for sqlite",
+	)
+}
+
+// GetVersion returns the SQLite library version.
+func (m *MetadataOps) GetVersion(ctx context.Context) (string, error) {
+	var version string
+	if err := m.conn.db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return "", adapter.WrapError(dbcapabilities.SQLite, "get_version", err)
+	}
+	return version, nil
+}
+
+// GetUniqueIdentifier returns an empty string; SQLite files have no unique identifier.
+func (m *MetadataOps) GetUniqueIdentifier(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// GetDatabaseSize returns the size of the database in bytes.
+func (m *MetadataOps) GetDatabaseSize(ctx context.Context) (int64, error) {
+	size, err := databaseSize(ctx, m.conn.db)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "get_database_size", err)
+	}
+	return size, nil
+}
+
+// GetTableCount returns the number of tables in the database.
+func (m *MetadataOps) GetTableCount(ctx context.Context) (int, error) {
+	tables, err := ListTables(ctx, m.conn.db)
+	if err != nil {
+		return 0, adapter.WrapError(dbcapabilities.SQLite, "get_table_count", err)
+	}
+	return len(tables), nil
+}
+
+// ExecuteCommand executes a statement or pragma and returns its rows formatted as text.
+func (m *MetadataOps) ExecuteCommand(ctx context.Context, command string) ([]byte, error) {
+	rows, err := queryRows(ctx, m.conn.db, command)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "execute_command", err)
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		fmt.Fprintln(&b, row)
+	}
+	return []byte(b.String()), nil
+}
+
+// CollectDatabaseMetadata reads version, size and storage settings of the database.
+func CollectDatabaseMetadata(ctx context.Context, db *sql.DB) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return nil, err
+	}
+	metadata["version"] = version
+
+	size, err := databaseSize(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	metadata["size_bytes"] = size
+
+	tables, err := ListTables(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	metadata["tables_count"] = len(tables)
+
+	for _, pragma := range []string{"journal_mode", "encoding", "page_size", "user_version", "application_id", "auto_vacuum"} {
+		var value interface{}
+		if err := db.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(&value); err != nil {
+			return nil, fmt.Errorf("error reading pragma %s: %v", pragma, err)
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		metadata[pragma] = value
+	}
+
+	return metadata, nil
+}
+
+// databaseSize returns the number of bytes used by the database pages
+func databaseSize(ctx context.Context, db *sql.DB) (int64, error) {
+	var size int64
+	err := db.QueryRowContext(ctx,
+		"SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()").Scan(&size)
+	return size, err
+}
@@ -0,0 +1,612 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+var (
+	viewBodyPattern      = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?VIEW\s+.*?\s+AS\s+(.*)$`)
+	triggerTimingPattern = regexp.MustCompile(`(?i)\b(BEFORE|AFTER|INSTEAD\s+OF)\s+(INSERT|UPDATE|DELETE)\b`)
+	autoincrementPattern = regexp.MustCompile(`(?i)\bAUTOINCREMENT\b`)
+	literalDefault       = regexp.MustCompile(`(?i)^(NULL|TRUE|FALSE|CURRENT_TIMESTAMP|CURRENT_DATE|CURRENT_TIME|[-+]?[0-9]+(\.[0-9]+)?|'(?:[^']|'')*')$`)
+)
+
+// schemaObject is a row of sqlite_master
+type schemaObject struct {
+	Type      string
+	Name      string
+	TableName string
+	SQL       string
+}
+
+// DiscoverSchema reads tables, views, indexes, foreign keys and triggers from the database.
+func DiscoverSchema(ctx context.Context, db *sql.DB) (*unifiedmodel.UnifiedModel, error) {
+	um := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.SQLite,
+		Tables:       make(map[string]unifiedmodel.Table),
+		Views:        make(map[string]unifiedmodel.View),
+		Triggers:     make(map[string]unifiedmodel.Trigger),
+	}
+
+	objects, err := listSchemaObjects(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objects {
+		switch obj.Type {
+		case "table":
+			table, err := discoverTable(ctx, db, obj)
+			if err != nil {
+				return nil, err
+			}
+			um.Tables[table.Name] = *table
+		case "view":
+			view := unifiedmodel.View{Name: obj.Name, Definition: obj.SQL}
+			if m := viewBodyPattern.FindStringSubmatch(obj.SQL); m != nil {
+				view.Definition = strings.TrimSpace(m[1])
+			}
+			um.Views[obj.Name] = view
+		case "trigger":
+			trigger := unifiedmodel.Trigger{Name: obj.Name, Table: obj.TableName, Procedure: obj.SQL}
+			if m := triggerTimingPattern.FindStringSubmatch(obj.SQL); m != nil {
+				trigger.Timing = strings.ToLower(strings.Join(strings.Fields(m[1]), "_"))
+				trigger.Events = []string{strings.ToLower(m[2])}
+			}
+			um.Triggers[obj.Name] = trigger
+		}
+	}
+
+	return um, nil
+}
+
+// listSchemaObjects returns the user tables, views and triggers
+func listSchemaObjects(ctx context.Context, db *sql.DB) ([]schemaObject, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT type, name, tbl_name, COALESCE(sql, '')
+		FROM sqlite_master
+		WHERE type IN ('table', 'view', 'trigger') AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing schema objects: %v", err)
+	}
+	defer rows.Close()
+
+	var objects []schemaObject
+	for rows.Next() {
+		var obj schemaObject
+		if err := rows.Scan(&obj.Type, &obj.Name, &obj.TableName, &obj.SQL); err != nil {
+			return nil, fmt.Errorf("error scanning schema object: %v", err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, rows.Err()
+}
+
+// ListTables returns the names of all user tables.
+func ListTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// discoverTable reads columns, keys and indexes of a table
+func discoverTable(ctx context.Context, db *sql.DB, obj schemaObject) (*unifiedmodel.Table, error) {
+	table := &unifiedmodel.Table{
+		Name:        obj.Name,
+		Columns:     make(map[string]unifiedmodel.Column),
+		Indexes:     make(map[string]unifiedmodel.Index),
+		Constraints: make(map[string]unifiedmodel.Constraint),
+		Options:     map[string]any{"definition": obj.SQL},
+	}
+
+	// table_xinfo also reports generated columns, flagged through the hidden column
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_xinfo(%s)", quoteIdent(obj.Name)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns of %s: %v", obj.Name, err)
+	}
+
+	type pkColumn struct {
+		name     string
+		position int
+		dataType string
+	}
+	var pk []pkColumn
+
+	for rows.Next() {
+		var cid, notNull, pkPos, hidden int
+		var name, declType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &declType, &notNull, &dflt, &pkPos, &hidden); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning column of %s: %v", obj.Name, err)
+		}
+		// hidden 1 marks the hidden columns of virtual tables
+		if hidden == 1 {
+			continue
+		}
+
+		position := cid + 1
+		column := unifiedmodel.Column{
+			Name:            name,
+			DataType:        columnType(declType),
+			Nullable:        notNull == 0 && pkPos == 0,
+			IsPrimaryKey:    pkPos > 0,
+			OrdinalPosition: &position,
+		}
+		if dflt.Valid {
+			column.Default = dflt.String
+		}
+		if hidden == 2 || hidden == 3 {
+			column.Options = map[string]any{"generated": true, "stored": hidden == 3}
+		}
+		table.Columns[name] = column
+
+		if pkPos > 0 {
+			pk = append(pk, pkColumn{name: name, position: pkPos, dataType: strings.ToUpper(declType)})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(pk) > 0 {
+		sort.Slice(pk, func(i, j int) bool { return pk[i].position < pk[j].position })
+		names := make([]string, len(pk))
+		for i, c := range pk {
+			names[i] = c.name
+		}
+		table.Constraints["pk_"+obj.Name] = unifiedmodel.Constraint{
+			Name:    "pk_" + obj.Name,
+			Type:    unifiedmodel.ConstraintTypePrimaryKey,
+			Columns: names,
+		}
+
+		// A single INTEGER PRIMARY KEY aliases the rowid and is assigned automatically
+		if len(pk) == 1 && pk[0].dataType == "INTEGER" {
+			col := table.Columns[pk[0].name]
+			col.AutoIncrement = true
+			if autoincrementPattern.MatchString(obj.SQL) {
+				col.Options = map[string]any{"autoincrement": true}
+			}
+			table.Columns[pk[0].name] = col
+		}
+	}
+
+	if err := discoverIndexes(ctx, db, table); err != nil {
+		return nil, err
+	}
+	if err := discoverForeignKeys(ctx, db, table); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// discoverIndexes reads the indexes of a table. Unique constraints are backed by
+// automatic indexes and are reported as constraints.
+func discoverIndexes(ctx context.Context, db *sql.DB, table *unifiedmodel.Table) error {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_list(%s)", quoteIdent(table.Name)))
+	if err != nil {
+		return fmt.Errorf("error reading indexes of %s: %v", table.Name, err)
+	}
+
+	type indexInfo struct {
+		name    string
+		unique  bool
+		origin  string
+		partial bool
+	}
+	var indexes []indexInfo
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning index of %s: %v", table.Name, err)
+		}
+		indexes = append(indexes, indexInfo{name: name, unique: unique == 1, origin: origin, partial: partial == 1})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		if idx.origin == "pk" {
+			continue
+		}
+		columns, err := indexColumns(ctx, db, idx.name)
+		if err != nil {
+			return err
+		}
+
+		if idx.origin == "u" {
+			table.Constraints[idx.name] = unifiedmodel.Constraint{
+				Name:    idx.name,
+				Type:    unifiedmodel.ConstraintTypeUnique,
+				Columns: columns,
+			}
+			continue
+		}
+
+		index := unifiedmodel.Index{
+			Name:    idx.name,
+			Type:    unifiedmodel.IndexTypeBTree,
+			Columns: columns,
+			Unique:  idx.unique,
+		}
+		var definition string
+		if err := db.QueryRowContext(ctx, `SELECT COALESCE(sql, '') FROM sqlite_master WHERE type = 'index' AND name = ?`, idx.name).Scan(&definition); err == nil && definition != "" {
+			index.Options = map[string]any{"definition": definition}
+			if idx.partial {
+				index.Type = unifiedmodel.IndexTypePartial
+				if i := strings.LastIndex(strings.ToUpper(definition), " WHERE "); i >= 0 {
+					index.Predicate = strings.TrimSpace(definition[i+len(" WHERE "):])
+				}
+			}
+		}
+		table.Indexes[idx.name] = index
+	}
+	return nil
+}
+
+// indexColumns returns the columns of an index in key order
+func indexColumns(ctx context.Context, db *sql.DB, index string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_info(%s)", quoteIdent(index)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading columns of index %s: %v", index, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var seqno, cid int
+		var name sql.NullString
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, fmt.Errorf("error scanning column of index %s: %v", index, err)
+		}
+		// Expression index terms have no column name
+		if name.Valid {
+			columns = append(columns, name.String)
+		}
+	}
+	return columns, rows.Err()
+}
+
+// discoverForeignKeys reads the foreign keys of a table
+func discoverForeignKeys(ctx context.Context, db *sql.DB, table *unifiedmodel.Table) error {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA foreign_key_list(%s)", quoteIdent(table.Name)))
+	if err != nil {
+		return fmt.Errorf("error reading foreign keys of %s: %v", table.Name, err)
+	}
+	defer rows.Close()
+
+	fks := make(map[int]*unifiedmodel.Constraint)
+	var ids []int
+	for rows.Next() {
+		var id, seq int
+		var refTable, from, onUpdate, onDelete, match string
+		var to sql.NullString
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return fmt.Errorf("error scanning foreign key of %s: %v", table.Name, err)
+		}
+
+		fk, exists := fks[id]
+		if !exists {
+			fk = &unifiedmodel.Constraint{
+				Name: fmt.Sprintf("fk_%s_%d", table.Name, id),
+				Type: unifiedmodel.ConstraintTypeForeignKey,
+				Reference: unifiedmodel.Reference{
+					Table:    refTable,
+					OnUpdate: onUpdate,
+					OnDelete: onDelete,
+				},
+			}
+			fks[id] = fk
+			ids = append(ids, id)
+		}
+		fk.Columns = append(fk.Columns, from)
+		// A missing target column refers to the primary key of the parent table
+		if to.Valid {
+			fk.Reference.Columns = append(fk.Reference.Columns, to.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		table.Constraints[fks[id].Name] = *fks[id]
+	}
+	return nil
+}
+
+// CreateStructure creates tables, indexes and views from a unified model in one transaction.
+func CreateStructure(ctx context.Context, db *sql.DB, um *unifiedmodel.UnifiedModel) error {
+	if um == nil {
+		return fmt.Errorf("unified model cannot be nil")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	sameEngine := um.DatabaseType == dbcapabilities.SQLite
+
+	tableNames := make([]string, 0, len(um.Tables))
+	for name := range um.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	// SQLite resolves foreign key targets lazily, so tables can be created in any order
+	for _, name := range tableNames {
+		table := um.Tables[name]
+		if _, err := tx.ExecContext(ctx, createTableStatement(table, sameEngine)); err != nil {
+			return fmt.Errorf("error creating table %s: %v", table.Name, err)
+		}
+
+		for _, index := range table.Indexes {
+			stmt, ok := createIndexStatement(table.Name, index, sameEngine)
+			if !ok {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("error creating index %s: %v", index.Name, err)
+			}
+		}
+	}
+
+	for _, view := range um.Views {
+		if view.Definition == "" {
+			continue
+		}
+		stmt := view.Definition
+		if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(stmt)), "CREATE") {
+			stmt = fmt.Sprintf("CREATE VIEW IF NOT EXISTS %s AS %s", quoteIdent(view.Name), stmt)
+		}
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("error creating view %s: %v", view.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
+// createTableStatement builds a CREATE TABLE statement for a unified table
+func createTableStatement(table unifiedmodel.Table, sameEngine bool) string {
+	columns := orderedColumns(table)
+
+	var pkColumns []string
+	for _, c := range table.Constraints {
+		if c.Type == unifiedmodel.ConstraintTypePrimaryKey {
+			pkColumns = c.Columns
+			break
+		}
+	}
+	if len(pkColumns) == 0 {
+		for _, name := range columns {
+			if table.Columns[name].IsPrimaryKey {
+				pkColumns = append(pkColumns, name)
+			}
+		}
+	}
+
+	// A single auto-increment integer key becomes the rowid alias
+	inlinePK := ""
+	if len(pkColumns) == 1 {
+		col := table.Columns[pkColumns[0]]
+		if col.AutoIncrement && sqliteType(col.DataType) == "INTEGER" {
+			inlinePK = pkColumns[0]
+		}
+	}
+
+	var defs []string
+	for _, name := range columns {
+		col := table.Columns[name]
+		def := quoteIdent(name) + " " + sqliteType(col.DataType)
+		if name == inlinePK {
+			def = quoteIdent(name) + " INTEGER PRIMARY KEY"
+			if opt, ok := col.Options["autoincrement"].(bool); ok && opt {
+				def += " AUTOINCREMENT"
+			}
+			defs = append(defs, def)
+			continue
+		}
+		if !col.Nullable {
+			def += " NOT NULL"
+		}
+		if dflt, ok := defaultClause(col.Default, sameEngine); ok {
+			def += " DEFAULT " + dflt
+		}
+		defs = append(defs, def)
+	}
+
+	if len(pkColumns) > 0 && inlinePK == "" {
+		defs = append(defs, "PRIMARY KEY ("+quoteIdents(pkColumns)+")")
+	}
+
+	constraintNames := make([]string, 0, len(table.Constraints))
+	for name := range table.Constraints {
+		constraintNames = append(constraintNames, name)
+	}
+	sort.Strings(constraintNames)
+
+	for _, name := range constraintNames {
+		c := table.Constraints[name]
+		switch c.Type {
+		case unifiedmodel.ConstraintTypeUnique:
+			if len(c.Columns) > 0 {
+				defs = append(defs, "UNIQUE ("+quoteIdents(c.Columns)+")")
+			}
+		case unifiedmodel.ConstraintTypeForeignKey:
+			if len(c.Columns) == 0 || c.Reference.Table == "" {
+				continue
+			}
+			def := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s", quoteIdents(c.Columns), quoteIdent(c.Reference.Table))
+			if len(c.Reference.Columns) > 0 {
+				def += " (" + quoteIdents(c.Reference.Columns) + ")"
+			}
+			if action := referentialAction(c.Reference.OnDelete); action != "" {
+				def += " ON DELETE " + action
+			}
+			if action := referentialAction(c.Reference.OnUpdate); action != "" {
+				def += " ON UPDATE " + action
+			}
+			defs = append(defs, def)
+		case unifiedmodel.ConstraintTypeCheck:
+			if sameEngine && c.Expression != "" {
+				defs = append(defs, "CHECK ("+c.Expression+")")
+			}
+		}
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", quoteIdent(table.Name), strings.Join(defs, ",\n\t"))
+}
+
+// createIndexStatement builds a CREATE INDEX statement, skipping indexes without plain columns.
+// Definitions discovered from SQLite itself are replayed as-is to keep expressions and predicates.
+func createIndexStatement(table string, index unifiedmodel.Index, sameEngine bool) (string, bool) {
+	if def, ok := index.Options["definition"].(string); ok && sameEngine && strings.HasPrefix(strings.ToUpper(def), "CREATE") {
+		return strings.Replace(def, "INDEX ", "INDEX IF NOT EXISTS ", 1), true
+	}
+	if len(index.Columns) == 0 || index.Name == "" {
+		return "", false
+	}
+
+	stmt := "CREATE "
+	if index.Unique {
+		stmt += "UNIQUE "
+	}
+	stmt += fmt.Sprintf("INDEX IF NOT EXISTS %s ON %s (%s)", quoteIdent(index.Name), quoteIdent(table), quoteIdents(index.Columns))
+	return stmt, true
+}
+
+// columnType normalizes a declared SQLite column type; columns without a type have BLOB affinity
+func columnType(declType string) string {
+	declType = strings.ToLower(strings.TrimSpace(declType))
+	if declType == "" {
+		return "blob"
+	}
+	return declType
+}
+
+// sqliteType maps a unified or foreign column type onto an SQLite type using the affinity rules
+func sqliteType(dataType string) string {
+	t := strings.ToLower(dataType)
+	switch {
+	case t == "":
+		return "BLOB"
+	case strings.Contains(t, "[]") || strings.HasPrefix(t, "array"):
+		return "TEXT"
+	case strings.HasPrefix(t, "bool"):
+		return "BOOLEAN"
+	case strings.Contains(t, "int") || t == "serial" || t == "bigserial" || t == "smallserial":
+		return "INTEGER"
+	case strings.Contains(t, "char") || strings.Contains(t, "clob") || strings.Contains(t, "text") ||
+		t == "string" || t == "uuid" || strings.HasPrefix(t, "enum"):
+		return "TEXT"
+	case strings.HasPrefix(t, "json"):
+		return "JSON"
+	case strings.Contains(t, "blob") || strings.Contains(t, "binary") || t == "bytea" || t == "bytes":
+		return "BLOB"
+	case strings.Contains(t, "real") || strings.Contains(t, "floa") || strings.Contains(t, "doub"):
+		return "REAL"
+	case strings.HasPrefix(t, "timestamp") || strings.HasPrefix(t, "datetime"):
+		return "DATETIME"
+	case t == "date":
+		return "DATE"
+	case strings.HasPrefix(t, "time"):
+		return "TIME"
+	case strings.HasPrefix(t, "decimal") || strings.HasPrefix(t, "numeric") || t == "money":
+		return "NUMERIC"
+	default:
+		return "TEXT"
+	}
+}
+
+// defaultClause returns a default expression SQLite accepts. Defaults discovered from other
+// engines are only carried over when they are plain literals.
+func defaultClause(expr string, sameEngine bool) (string, bool) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "", false
+	}
+	if sameEngine {
+		return expr, true
+	}
+	if literalDefault.MatchString(expr) {
+		return expr, true
+	}
+	return "", false
+}
+
+// referentialAction normalizes a foreign key action, dropping NO ACTION
+func referentialAction(action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	switch action {
+	case "CASCADE", "RESTRICT", "SET NULL", "SET DEFAULT":
+		return action
+	}
+	return ""
+}
+
+// orderedColumns returns column names by ordinal position, then by name
+func orderedColumns(table unifiedmodel.Table) []string {
+	names := make([]string, 0, len(table.Columns))
+	for name := range table.Columns {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := table.Columns[names[i]].OrdinalPosition, table.Columns[names[j]].OrdinalPosition
+		if pi != nil && pj != nil && *pi != *pj {
+			return *pi < *pj
+		}
+		if (pi == nil) != (pj == nil) {
+			return pi != nil
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// quoteIdent quotes an identifier with double quotes
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
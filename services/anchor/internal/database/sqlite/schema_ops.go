@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// SchemaOps implements adapter.SchemaOperator for SQLite.
+type SchemaOps struct {
+	conn *Connection
+}
+
+// DiscoverSchema retrieves the complete schema of the database as a UnifiedModel.
+func (s *SchemaOps) DiscoverSchema(ctx context.Context) (*unifiedmodel.UnifiedModel, error) {
+	um, err := DiscoverSchema(ctx, s.conn.db)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "discover_schema", err)
+	}
+	return um, nil
+}
+
+// CreateStructure creates database objects from a UnifiedModel.
+func (s *SchemaOps) CreateStructure(ctx context.Context, model *unifiedmodel.UnifiedModel) error {
+	if s.conn.readOnly {
+		return adapter.NewUnsupportedOperationError(dbcapabilities.SQLite, "create structure", "database is opened read-only")
+	}
+	if err := CreateStructure(ctx, s.conn.db, model); err != nil {
+		return adapter.WrapError(dbcapabilities.SQLite, "create_structure", err)
+	}
+	return nil
+}
+
+// ListTables returns the names of all tables in the database.
+func (s *SchemaOps) ListTables(ctx context.Context) ([]string, error) {
+	tables, err := ListTables(ctx, s.conn.db)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "list_tables", err)
+	}
+	return tables, nil
+}
+
+// GetTableSchema retrieves the schema for a specific table.
+func (s *SchemaOps) GetTableSchema(ctx context.Context, tableName string) (*unifiedmodel.Table, error) {
+	var definition string
+	err := s.conn.db.QueryRowContext(ctx,
+		`SELECT COALESCE(sql, '') FROM sqlite_master WHERE type = 'table' AND name = ?`, tableName).Scan(&definition)
+	if err == sql.ErrNoRows {
+		return nil, adapter.NewNotFoundError(dbcapabilities.SQLite, "table", tableName)
+	}
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "get_table_schema", err)
+	}
+
+	table, err := discoverTable(ctx, s.conn.db, schemaObject{Type: "table", Name: tableName, TableName: tableName, SQL: definition})
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.SQLite, "get_table_schema", err)
+	}
+	return table, nil
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -92,6 +95,25 @@ func FetchData(client *WeaviateClient, className string, limit int) ([]map[strin
 	return result, nil
 }
 
+// classNamePattern is the form of Weaviate class names, which are written into GraphQL queries
+var classNamePattern = regexp.MustCompile(`^[A-Z][_0-9A-Za-z]*$`)
+
+// SearchData returns the topK nearest neighbors of a vector in a class, with their ID and
+// the distance of the metric of the class in the "_additional" map of each result
+func SearchData(client *WeaviateClient, className string, vector []float32, topK int) ([]interface{}, error) {
+	if !classNamePattern.MatchString(className) {
+		return nil, fmt.Errorf("invalid class name %q", className)
+	}
+
+	values := make([]string, len(vector))
+	for i, v := range vector {
+		values[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	query := fmt.Sprintf("{ Get { %s(nearVector: {vector: [%s]}, limit: %d) { _additional { id distance } } } }",
+		className, strings.Join(values, ", "), topK)
+	return ExecuteQuery(client, query)
+}
+
 // InsertData inserts objects into a specified class
 func InsertData(client *WeaviateClient, className string, data []map[string]interface{}) (int64, error) {
 	if len(data) == 0 {
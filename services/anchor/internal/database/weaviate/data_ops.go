@@ -67,6 +67,32 @@ func (d *DataOps) Stream(ctx context.Context, params adapter.StreamParams) (adap
 	return adapter.StreamResult{}, adapter.NewUnsupportedOperationError(dbcapabilities.Weaviate, "stream data", "not yet implemented")
 }
 
+// FetchStream is not supported until Stream is implemented.
+func (d *DataOps) FetchStream(ctx context.Context, table string, batchSize int) (adapter.RowBatchIterator, error) {
+	return nil, adapter.NewUnsupportedOperationError(dbcapabilities.Weaviate, "fetch stream", "not yet implemented")
+}
+
+// SearchVectors returns the nearest neighbors of a vector in a class, scored with the distance
+// of the metric of the class
+func (d *DataOps) SearchVectors(ctx context.Context, className string, vector []float32, topK int) ([]adapter.VectorMatch, error) {
+	if err := adapter.ValidateVectorSearch(vector, topK); err != nil {
+		return nil, err
+	}
+	results, err := SearchData(d.conn.client, className, vector, topK)
+	if err != nil {
+		return nil, adapter.WrapError(dbcapabilities.Weaviate, "search_vectors", err)
+	}
+	matches := make([]adapter.VectorMatch, 0, len(results))
+	for _, result := range results {
+		object, _ := result.(map[string]interface{})
+		additional, _ := object["_additional"].(map[string]interface{})
+		id, _ := additional["id"].(string)
+		distance, _ := additional["distance"].(float64)
+		matches = append(matches, adapter.VectorMatch{ID: id, Score: distance})
+	}
+	return matches, nil
+}
+
 func (d *DataOps) ExecuteQuery(ctx context.Context, query string, args ...interface{}) ([]interface{}, error) {
 	result, err := ExecuteQuery(d.conn.client, query, args...)
 	if err != nil {
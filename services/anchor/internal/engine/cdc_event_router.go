@@ -8,6 +8,7 @@ import (
 
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
 )
 
 // CDCEventRouter handles database-agnostic routing of CDC events from source to target.
@@ -16,6 +17,8 @@ type CDCEventRouter struct {
 	sourceAdapter                 adapter.Connection
 	targetAdapter                 adapter.Connection
 	transformRules                []adapter.TransformationRule
+	conflictKeys                  map[string][]string // target table -> columns identifying a row
+	rowFilters                    *rowFilters         // nil when the mapping and its rules have no row filters
 	transformationServiceEndpoint string
 	logger                        *logger.Logger
 	stats                         *adapter.CDCStatistics
@@ -26,6 +29,8 @@ func NewCDCEventRouter(
 	sourceAdapter adapter.Connection,
 	targetAdapter adapter.Connection,
 	mappingRulesJSON []byte,
+	conflictKeysJSON []byte,
+	rowFilter string,
 	transformationServiceEndpoint string,
 	logger *logger.Logger,
 ) (*CDCEventRouter, error) {
@@ -39,10 +44,26 @@ func NewCDCEventRouter(
 
 	// Parse mapping rules if provided
 	if len(mappingRulesJSON) > 0 {
-		if err := router.parseMappingRules(mappingRulesJSON); err != nil {
+		rules, err := parseMappingRules(mappingRulesJSON, logger)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse mapping rules: %v", err)
 		}
+		router.transformRules = rules
+	}
+
+	// Parse conflict keys if provided
+	if len(conflictKeysJSON) > 0 {
+		if err := json.Unmarshal(conflictKeysJSON, &router.conflictKeys); err != nil {
+			return nil, fmt.Errorf("failed to parse conflict keys: %v", err)
+		}
+	}
+
+	// Compile the row filters of the mapping and its rules
+	filters, err := newRowFilters(rowFilter, router.transformRules)
+	if err != nil {
+		return nil, err
 	}
+	router.rowFilters = filters
 
 	return router, nil
 }
@@ -62,7 +83,25 @@ func (r *CDCEventRouter) RouteEvent(ctx context.Context, rawEvent map[string]int
 		return fmt.Errorf("parse event failed: %w", err)
 	}
 
-	// Step 2: Apply transformations if rules are configured
+	// Step 2: Skip events of rows the row filters exclude, and map the others only with the
+	// rules whose conditions hold
+	rules := r.transformRules
+	if r.rowFilters != nil {
+		tableRules := tableTransformationRules(r.transformRules, event.TableName)
+		if len(tableRules) == 0 {
+			tableRules = r.transformRules
+		}
+		var written bool
+		rules, written = r.rowFilters.filterEvent(event, tableRules)
+		if !written {
+			if r.logger != nil {
+				r.logger.Debug("Skipping CDC event %s on %s excluded by the row filters", event.Operation, event.TableName)
+			}
+			return nil
+		}
+	}
+
+	// Step 3: Apply transformations if rules are configured
 	if len(r.transformRules) > 0 {
 		if r.logger != nil {
 			r.logger.Debug("Applying %d transformation rules to CDC event for table %s (operation: %s)",
@@ -70,7 +109,7 @@ func (r *CDCEventRouter) RouteEvent(ctx context.Context, rawEvent map[string]int
 			r.logger.Debug("Original event data columns: %v", getColumnNames(event.Data))
 		}
 
-		transformedData, err := r.applyTransformations(ctx, event.Data)
+		transformedData, err := r.applyTransformations(ctx, event.Data, rules)
 		if err != nil {
 			r.stats.RecordFailure()
 			if r.logger != nil {
@@ -87,7 +126,7 @@ func (r *CDCEventRouter) RouteEvent(ctx context.Context, rawEvent map[string]int
 
 		// Also transform old data if present (for UPDATE/DELETE)
 		if len(event.OldData) > 0 {
-			transformedOldData, err := r.applyTransformations(ctx, event.OldData)
+			transformedOldData, err := r.applyTransformations(ctx, event.OldData, r.transformRules)
 			if err != nil {
 				// Log warning but don't fail - old data transformation is less critical
 				if r.logger != nil {
@@ -103,13 +142,13 @@ func (r *CDCEventRouter) RouteEvent(ctx context.Context, rawEvent map[string]int
 		}
 	}
 
-	// Step 3: Map table name if specified in transformation rules
+	// Step 4: Map table name if specified in transformation rules
 	if targetTable := r.getTargetTableName(event.TableName); targetTable != "" {
 		event.TableName = targetTable
 	}
 
-	// Step 4: Apply event to target database using target adapter
-	if err := r.targetAdapter.ReplicationOperations().ApplyCDCEvent(ctx, event); err != nil {
+	// Step 5: Apply event to target database using target adapter
+	if err := r.applyEvent(ctx, event); err != nil {
 		r.stats.RecordFailure()
 		if r.logger != nil {
 			r.logger.Error("Failed to apply CDC event to target: %v", err)
@@ -117,7 +156,7 @@ func (r *CDCEventRouter) RouteEvent(ctx context.Context, rawEvent map[string]int
 		return fmt.Errorf("apply event failed: %w", err)
 	}
 
-	// Step 5: Record successful event processing
+	// Step 6: Record successful event processing
 	latency := time.Since(startTime)
 	r.stats.RecordEvent(event, latency)
 
@@ -140,15 +179,62 @@ func (r *CDCEventRouter) CreateEventHandler() func(map[string]interface{}) error
 	}
 }
 
+// applyEvent applies an event to the target database. Inserts and updates of tables with
+// conflict keys are applied as upserts, so that replaying a batch of changes does not
+// duplicate rows on targets that do not enforce primary keys.
+func (r *CDCEventRouter) applyEvent(ctx context.Context, event *adapter.CDCEvent) error {
+	if event.Operation != adapter.CDCInsert && event.Operation != adapter.CDCUpdate {
+		return r.targetAdapter.ReplicationOperations().ApplyCDCEvent(ctx, event)
+	}
+
+	keys := r.conflictKeys[event.TableName]
+	if len(keys) == 0 {
+		return r.targetAdapter.ReplicationOperations().ApplyCDCEvent(ctx, event)
+	}
+
+	row := make(map[string]interface{}, len(event.Data))
+	for col, val := range event.Data {
+		if !cdcMetadataFields[col] {
+			row[col] = val
+		}
+	}
+	for _, key := range keys {
+		if _, ok := row[key]; !ok {
+			// Without the whole key the row cannot be matched, e.g. partial updates
+			if r.logger != nil {
+				r.logger.Debug("CDC event on %s lacks conflict key column %s, applying it as %s", event.TableName, key, event.Operation)
+			}
+			return r.targetAdapter.ReplicationOperations().ApplyCDCEvent(ctx, event)
+		}
+	}
+
+	_, err := r.targetAdapter.DataOperations().Upsert(ctx, event.TableName, []map[string]interface{}{row}, keys)
+	return err
+}
+
+// cdcMetadataFields are fields some sources add to event data that are not table columns
+var cdcMetadataFields = map[string]bool{
+	"message_type": true,
+	"raw_data_b64": true,
+	"data_length":  true,
+	"is_update":    true,
+	"database_id":  true,
+	"slot_name":    true,
+	"timestamp":    true,
+	"schema_name":  true,
+	"operation":    true,
+	"table_name":   true,
+}
+
 // applyTransformations applies transformation rules to event data.
-func (r *CDCEventRouter) applyTransformations(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
-	if len(r.transformRules) == 0 {
+func (r *CDCEventRouter) applyTransformations(ctx context.Context, data map[string]interface{}, rules []adapter.TransformationRule) (map[string]interface{}, error) {
+	if len(rules) == 0 {
 		return data, nil
 	}
 
 	// Use target adapter's transform capabilities
 	// This allows database-specific transformation optimizations
-	return r.targetAdapter.ReplicationOperations().TransformData(ctx, data, r.transformRules, r.transformationServiceEndpoint)
+	return transformRow(ctx, r.targetAdapter.ReplicationOperations(), data, rules, r.transformationServiceEndpoint)
 }
 
 // getTargetTableName returns the target table name from transformation rules.
@@ -162,21 +248,21 @@ func (r *CDCEventRouter) getTargetTableName(sourceTable string) string {
 }
 
 // parseMappingRules parses JSON mapping rules into TransformationRules.
-func (r *CDCEventRouter) parseMappingRules(mappingRulesJSON []byte) error {
+func parseMappingRules(mappingRulesJSON []byte, logger *logger.Logger) ([]adapter.TransformationRule, error) {
 	// Try parsing as array of transformation rules
 	var rules []map[string]interface{}
 	if err := json.Unmarshal(mappingRulesJSON, &rules); err != nil {
-		if r.logger != nil {
-			r.logger.Error("Failed to unmarshal mapping rules JSON: %v", err)
+		if logger != nil {
+			logger.Error("Failed to unmarshal mapping rules JSON: %v", err)
 		}
-		return err
+		return nil, err
 	}
 
-	if r.logger != nil {
-		r.logger.Debug("Parsing %d mapping rules from JSON (size: %d bytes)", len(rules), len(mappingRulesJSON))
+	if logger != nil {
+		logger.Debug("Parsing %d mapping rules from JSON (size: %d bytes)", len(rules), len(mappingRulesJSON))
 	}
 
-	r.transformRules = make([]adapter.TransformationRule, 0, len(rules))
+	transformRules := make([]adapter.TransformationRule, 0, len(rules))
 
 	for idx, ruleMap := range rules {
 		rule := adapter.TransformationRule{}
@@ -187,8 +273,8 @@ func (r *CDCEventRouter) parseMappingRules(mappingRulesJSON []byte) error {
 			metadata, hasMetadata = ruleMap["Metadata"].(map[string]interface{})
 		}
 
-		if r.logger != nil && !hasMetadata {
-			r.logger.Warn("Rule %d has no metadata field. Available fields: %v", idx, getMapKeys(ruleMap))
+		if logger != nil && !hasMetadata {
+			logger.Warn("Rule %d has no metadata field. Available fields: %v", idx, getMapKeys(ruleMap))
 		}
 
 		// Extract source column from metadata (primary) or direct field
@@ -222,8 +308,15 @@ func (r *CDCEventRouter) parseMappingRules(mappingRulesJSON []byte) error {
 			}
 		}
 
-		if r.logger != nil && rule.SourceColumn == "" && rule.TargetColumn == "" {
-			r.logger.Warn("Rule %d: Could not extract source/target columns. Metadata keys: %v", idx, getMapKeys(metadata))
+		// Extract the row filter of the rule
+		if hasMetadata {
+			if rowFilter, ok := metadata["row_filter"].(string); ok {
+				rule.RowFilter = rowFilter
+			}
+		}
+
+		if logger != nil && rule.SourceColumn == "" && rule.TargetColumn == "" {
+			logger.Warn("Rule %d: Could not extract source/target columns. Metadata keys: %v", idx, getMapKeys(metadata))
 		}
 
 		// Extract transformation type (default to "direct")
@@ -267,26 +360,47 @@ func (r *CDCEventRouter) parseMappingRules(mappingRulesJSON []byte) error {
 			rule.TargetTable = targetTable
 		}
 
+		// The tenant of the rule keys its masking transformation
+		if tenantID, ok := ruleMap["TenantID"].(string); ok {
+			rule.TenantID = tenantID
+		}
+
 		// Extract transformation parameters (optional)
 		if params, ok := ruleMap["parameters"].(map[string]interface{}); ok {
 			rule.Parameters = params
 		}
+		if options, ok := metadata["transformation_options"].(map[string]interface{}); ok && rule.Parameters == nil {
+			rule.Parameters = options
+		}
+
+		// Many-to-one and one-to-many rules read and write the columns of their item URIs, and
+		// rules added by users those of their resource URIs
+		if hasMetadata {
+			rule.SourceColumns = ruleColumns(metadata, "source")
+			rule.TargetColumns = ruleColumns(metadata, "target")
+			if sourceURI, ok := metadata["source_resource_uri"].(string); ok && rule.SourceColumn == "" {
+				rule.SourceColumn, _ = columnOfURI(sourceURI)
+			}
+			if targetURI, ok := metadata["target_resource_uri"].(string); ok && rule.TargetColumn == "" {
+				rule.TargetColumn, _ = columnOfURI(targetURI)
+			}
+		}
 
 		// Only add rule if it has at least source and target columns
 		if rule.SourceColumn != "" && rule.TargetColumn != "" {
-			r.transformRules = append(r.transformRules, rule)
-			if r.logger != nil {
-				r.logger.Debug("Parsed mapping rule: %s.%s -> %s.%s (transformation: %s)",
+			transformRules = append(transformRules, rule)
+			if logger != nil {
+				logger.Debug("Parsed mapping rule: %s.%s -> %s.%s (transformation: %s)",
 					rule.SourceTable, rule.SourceColumn, rule.TargetTable, rule.TargetColumn, rule.TransformationName)
 			}
 		}
 	}
 
-	if r.logger != nil {
-		r.logger.Info("Parsed %d transformation rules for CDC replication", len(r.transformRules))
+	if logger != nil {
+		logger.Info("Parsed %d transformation rules for CDC replication", len(transformRules))
 	}
 
-	return nil
+	return transformRules, nil
 }
 
 // splitIdentifier splits a database identifier (format: "database.table.column")
@@ -317,6 +431,41 @@ func splitIdentifier(identifier string) []string {
 	return parts
 }
 
+// ruleColumns returns the source or target columns of the item URIs of a many-to-one or
+// one-to-many rule, or nil for other rules and rules re-pointed since they were added
+func ruleColumns(metadata map[string]interface{}, side string) []string {
+	uris, _ := metadata[side+"_uris"].([]interface{})
+	resourceURI, _ := metadata[side+"_resource_uri"].(string)
+	if len(uris) < 2 || uris[0] != resourceURI {
+		return nil
+	}
+
+	columns := make([]string, 0, len(uris))
+	for _, uri := range uris {
+		s, _ := uri.(string)
+		column, ok := columnOfURI(s)
+		if !ok {
+			return nil
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// columnOfURI returns the column of a database column URI, or false for the URIs of other
+// resources
+func columnOfURI(uri string) (string, bool) {
+	addr, err := resource.ParseResourceURI(uri)
+	if err != nil || !addr.IsDatabase() {
+		return "", false
+	}
+	segment := addr.LastPathSegment()
+	if segment == nil || segment.Name == "" {
+		return "", false
+	}
+	return segment.Name, true
+}
+
 // GetStatistics returns the current CDC statistics.
 func (r *CDCEventRouter) GetStatistics() *adapter.CDCStatistics {
 	return r.stats
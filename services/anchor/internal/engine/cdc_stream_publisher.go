@@ -5,63 +5,95 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
-	streamv1 "github.com/redbco/redb-open/api/proto/stream/v1"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/logger"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/anchor/internal/stream/schemaregistry"
 )
 
-// CDCStreamPublisher publishes CDC events to stream platforms (Kafka, Kinesis, etc.)
+// CDCStreamPublisher publishes CDC events to a topic of a stream platform (Kafka, etc.)
+// attached to the anchor, in place of applying them to a target database. Events are JSON, or
+// Avro or Protobuf values whose schemas are derived from the UnifiedModel of the source tables
+// and registered in a schema registry when the stream has one configured.
 type CDCStreamPublisher struct {
-	sourceAdapter   adapter.Connection
-	streamClient    streamv1.StreamServiceClient
-	integrationName string
-	topicName       string
-	logger          *logger.Logger
-	stats           *adapter.CDCStatistics
-	mappingRules    []adapter.TransformationRule
+	sourceAdapter                 adapter.Connection
+	producer                      streamadapter.ProducerOperator
+	streamID                      string
+	topicName                     string
+	transformationServiceEndpoint string
+	logger                        *logger.Logger
+	stats                         *adapter.CDCStatistics
+	mappingRules                  []adapter.TransformationRule
+	rowFilters                    *rowFilters                // nil when the mapping and its rules have no row filters
+	serializer                    *schemaregistry.Serializer // nil for JSON events
+
+	tablesMu sync.Mutex
+	tables   map[string]*schemaregistry.Table // schema.table -> shape of its rows
 }
 
-// NewCDCStreamPublisher creates a new CDC to stream publisher
+// NewCDCStreamPublisher creates a new CDC to stream publisher producing to a topic. registry
+// is the schema registry configuration of the stream, nil to produce JSON events.
 func NewCDCStreamPublisher(
 	sourceAdapter adapter.Connection,
-	streamServiceEndpoint string,
-	integrationName string,
+	producer streamadapter.ProducerOperator,
+	streamID string,
 	topicName string,
+	registry *schemaregistry.Config,
 	mappingRulesJSON []byte,
+	rowFilter string,
+	transformationServiceEndpoint string,
 	logger *logger.Logger,
 ) (*CDCStreamPublisher, error) {
-	// Connect to stream service
-	conn, err := grpc.Dial(streamServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to stream service: %w", err)
+	if producer == nil {
+		return nil, fmt.Errorf("stream %s does not support producing messages", streamID)
 	}
 
 	publisher := &CDCStreamPublisher{
-		sourceAdapter:   sourceAdapter,
-		streamClient:    streamv1.NewStreamServiceClient(conn),
-		integrationName: integrationName,
-		topicName:       topicName,
-		logger:          logger,
-		stats:           adapter.NewCDCStatistics(),
+		sourceAdapter:                 sourceAdapter,
+		producer:                      producer,
+		streamID:                      streamID,
+		topicName:                     topicName,
+		transformationServiceEndpoint: transformationServiceEndpoint,
+		logger:                        logger,
+		stats:                         adapter.NewCDCStatistics(),
+		tables:                        make(map[string]*schemaregistry.Table),
+	}
+	if registry != nil {
+		publisher.serializer = schemaregistry.NewSerializer(registry)
 	}
 
 	// Parse mapping rules if provided
 	if len(mappingRulesJSON) > 0 {
-		var rules []adapter.TransformationRule
-		if err := json.Unmarshal(mappingRulesJSON, &rules); err != nil {
+		rules, err := parseMappingRules(mappingRulesJSON, logger)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse mapping rules: %w", err)
 		}
 		publisher.mappingRules = rules
 	}
 
+	// Compile the row filters of the mapping and its rules
+	filters, err := newRowFilters(rowFilter, publisher.mappingRules)
+	if err != nil {
+		return nil, err
+	}
+	publisher.rowFilters = filters
+
 	return publisher, nil
 }
 
-// PublishEvent publishes a CDC event to a stream
+// CreateEventHandler creates the event handler of the replication source
+func (p *CDCStreamPublisher) CreateEventHandler() func(map[string]interface{}) error {
+	return func(rawEvent map[string]interface{}) error {
+		// Producing runs as long as the replication, not the RPC that started it
+		return p.PublishEvent(context.Background(), rawEvent)
+	}
+}
+
+// PublishEvent publishes a CDC event to the topic and returns once the brokers acknowledged it
 func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[string]interface{}) error {
 	startTime := time.Now()
 
@@ -75,9 +107,26 @@ func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[stri
 		return fmt.Errorf("parse event failed: %w", err)
 	}
 
+	// Skip events of rows the row filters exclude
+	rules := p.mappingRules
+	if p.rowFilters != nil {
+		tableRules := tableTransformationRules(p.mappingRules, event.TableName)
+		if len(tableRules) == 0 {
+			tableRules = p.mappingRules
+		}
+		var published bool
+		rules, published = p.rowFilters.filterEvent(event, tableRules)
+		if !published {
+			if p.logger != nil {
+				p.logger.Debugf("Skipping CDC event %s on %s excluded by the row filters", event.Operation, event.TableName)
+			}
+			return nil
+		}
+	}
+
 	// Apply transformations if mapping rules exist
 	if len(p.mappingRules) > 0 {
-		transformedData, err := p.applyTransformations(ctx, event.Data)
+		transformedData, err := p.applyTransformations(ctx, event.Data, rules)
 		if err != nil {
 			p.stats.RecordFailure()
 			if p.logger != nil {
@@ -89,7 +138,7 @@ func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[stri
 
 		// Transform old data for UPDATE/DELETE operations
 		if len(event.OldData) > 0 {
-			transformedOldData, err := p.applyTransformations(ctx, event.OldData)
+			transformedOldData, err := p.applyTransformations(ctx, event.OldData, p.mappingRules)
 			if err != nil {
 				if p.logger != nil {
 					p.logger.Warnf("Failed to transform old_data: %v", err)
@@ -101,7 +150,7 @@ func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[stri
 	}
 
 	// Convert CDC event to stream message format
-	messageBytes, partitionKey, headers, err := p.convertCDCEventToStreamMessage(event)
+	messageBytes, partitionKey, headers, err := p.convertCDCEventToStreamMessage(ctx, event)
 	if err != nil {
 		p.stats.RecordFailure()
 		if p.logger != nil {
@@ -110,22 +159,14 @@ func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[stri
 		return fmt.Errorf("conversion failed: %w", err)
 	}
 
-	// Publish to stream using ProduceMessages
-	produceReq := &streamv1.ProduceMessagesRequest{
-		TenantId:  "", // Will be set by service
-		StreamId:  p.integrationName,
-		TopicName: p.topicName,
-		Messages: []*streamv1.StreamMessage{
-			{
-				Key:     []byte(partitionKey),
-				Value:   messageBytes,
-				Headers: headers,
-			},
-		},
+	message := streamadapter.Message{
+		Topic:     p.topicName,
+		Key:       []byte(partitionKey),
+		Value:     messageBytes,
+		Headers:   headers,
+		Timestamp: event.Timestamp,
 	}
-
-	resp, err := p.streamClient.ProduceMessages(ctx, produceReq)
-	if err != nil {
+	if err := p.producer.Produce(ctx, p.topicName, []streamadapter.Message{message}); err != nil {
 		p.stats.RecordFailure()
 		if p.logger != nil {
 			p.logger.Errorf("Failed to publish message to stream: %v", err)
@@ -138,15 +179,37 @@ func (p *CDCStreamPublisher) PublishEvent(ctx context.Context, rawEvent map[stri
 	p.stats.RecordEvent(event, latency)
 
 	if p.logger != nil {
-		p.logger.Infof("Published CDC event to stream %s/%s: operation=%s, table=%s, count=%d",
-			p.integrationName, p.topicName, event.Operation, event.TableName, resp.MessagesProduced)
+		p.logger.Debugf("Published CDC event to stream %s/%s: operation=%s, table=%s",
+			p.streamID, p.topicName, event.Operation, event.TableName)
 	}
 
 	return nil
 }
 
 // convertCDCEventToStreamMessage converts a CDC event to stream message format
-func (p *CDCStreamPublisher) convertCDCEventToStreamMessage(event *adapter.CDCEvent) ([]byte, string, map[string]string, error) {
+func (p *CDCStreamPublisher) convertCDCEventToStreamMessage(ctx context.Context, event *adapter.CDCEvent) ([]byte, string, map[string]string, error) {
+	// Generate partition key from primary key or use table name
+	partitionKey := event.TableName
+	if event.TransactionID != "" {
+		partitionKey = event.TransactionID
+	}
+
+	// Build headers
+	headers := p.buildMessageHeaders(event)
+
+	if p.serializer != nil {
+		table, err := p.tableShape(ctx, event)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		value, err := p.serializer.Serialize(ctx, p.topicName, table, event)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		headers["cdc.format"] = string(p.serializer.Format())
+		return value, partitionKey, headers, nil
+	}
+
 	// Build message payload with CDC event structure
 	payload := map[string]interface{}{
 		"operation":  string(event.Operation),
@@ -171,16 +234,66 @@ func (p *CDCStreamPublisher) convertCDCEventToStreamMessage(event *adapter.CDCEv
 		return nil, "", nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	// Generate partition key from primary key or use table name
-	partitionKey := event.TableName
-	if event.TransactionID != "" {
-		partitionKey = event.TransactionID
+	return payloadBytes, partitionKey, headers, nil
+}
+
+// tableShape returns the shape of the rows of the table of an event, derived from the schema
+// of the source database. The schema is discovered again when the event has columns the shape
+// lacks, as the table changed since, bypassing the metadata cache of the connection.
+func (p *CDCStreamPublisher) tableShape(ctx context.Context, event *adapter.CDCEvent) (*schemaregistry.Table, error) {
+	key := event.SchemaName + "." + event.TableName
+	p.tablesMu.Lock()
+	table := p.tables[key]
+	p.tablesMu.Unlock()
+	if table != nil && len(table.Missing(event.Data)) == 0 && len(table.Missing(event.OldData)) == 0 {
+		return table, nil
 	}
 
-	// Build headers
-	headers := p.buildMessageHeaders(event)
+	if table != nil {
+		adapter.InvalidateMetadata(p.sourceAdapter)
+	}
+	model, err := p.sourceAdapter.SchemaOperations().DiscoverSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the schema of table %s: %w", event.TableName, err)
+	}
+	source, ok := findTable(model, event.SchemaName, event.TableName)
+	if !ok {
+		return nil, fmt.Errorf("table %s is not in the schema of the source database", event.TableName)
+	}
 
-	return payloadBytes, partitionKey, headers, nil
+	table = schemaregistry.NewTable(event.SchemaName, source, p.mappingRules)
+	missing := append(table.Missing(event.Data), table.Missing(event.OldData)...)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("columns %s of table %s are not in its schema", strings.Join(missing, ", "), event.TableName)
+	}
+
+	p.tablesMu.Lock()
+	p.tables[key] = table
+	p.tablesMu.Unlock()
+	if p.logger != nil {
+		p.logger.Infof("Derived %s schema of table %s with %d fields for stream %s/%s",
+			p.serializer.Format(), event.TableName, len(table.Fields), p.streamID, p.topicName)
+	}
+	return table, nil
+}
+
+// findTable returns a table of a model, keyed by its name alone or qualified by its schema
+func findTable(model *unifiedmodel.UnifiedModel, schemaName, tableName string) (unifiedmodel.Table, bool) {
+	if model == nil {
+		return unifiedmodel.Table{}, false
+	}
+	if schemaName != "" {
+		if table, ok := model.Tables[schemaName+"."+tableName]; ok {
+			return table, true
+		}
+		if schema, ok := model.Schemas[schemaName]; ok {
+			if table, ok := schema.Tables[tableName]; ok {
+				return table, true
+			}
+		}
+	}
+	table, ok := model.Tables[tableName]
+	return table, ok
 }
 
 // buildMessageHeaders creates metadata headers for the stream message
@@ -203,68 +316,10 @@ func (p *CDCStreamPublisher) buildMessageHeaders(event *adapter.CDCEvent) map[st
 	return headers
 }
 
-// applyTransformations applies mapping rules to transform data
-func (p *CDCStreamPublisher) applyTransformations(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
-	if len(p.mappingRules) == 0 {
-		return data, nil
-	}
-
-	result := make(map[string]interface{})
-
-	for _, rule := range p.mappingRules {
-		sourceValue, exists := data[rule.SourceColumn]
-		if !exists {
-			// Skip if source column doesn't exist in this event
-			continue
-		}
-
-		// Apply transformation based on type
-		transformedValue, err := p.applyTransformation(rule.TransformationType, sourceValue)
-		if err != nil {
-			if p.logger != nil {
-				p.logger.Warnf("Failed to apply transformation %s: %v", rule.TransformationType, err)
-			}
-			// Use original value if transformation fails
-			transformedValue = sourceValue
-		}
-
-		result[rule.TargetColumn] = transformedValue
-	}
-
-	// Include unmapped columns from source (pass-through)
-	for key, value := range data {
-		if _, exists := result[key]; !exists {
-			result[key] = value
-		}
-	}
-
-	return result, nil
-}
-
-// applyTransformation applies a single transformation
-func (p *CDCStreamPublisher) applyTransformation(transformationType string, value interface{}) (interface{}, error) {
-	switch transformationType {
-	case "direct_mapping", "":
-		return value, nil
-	case "uppercase":
-		if str, ok := value.(string); ok {
-			return strings.ToUpper(str), nil
-		}
-	case "lowercase":
-		if str, ok := value.(string); ok {
-			return strings.ToLower(str), nil
-		}
-	case "trim":
-		if str, ok := value.(string); ok {
-			return strings.TrimSpace(str), nil
-		}
-	default:
-		if p.logger != nil {
-			p.logger.Warnf("Unknown transformation type: %s", transformationType)
-		}
-	}
-
-	return value, nil
+// applyTransformations applies the mapping rules to the data of an event. Topics have no
+// target adapter, so the transformations of the source adapter are used.
+func (p *CDCStreamPublisher) applyTransformations(ctx context.Context, data map[string]interface{}, rules []adapter.TransformationRule) (map[string]interface{}, error) {
+	return transformRow(ctx, p.sourceAdapter.ReplicationOperations(), data, rules, p.transformationServiceEndpoint)
 }
 
 // GetStatistics returns CDC statistics
@@ -272,8 +327,10 @@ func (p *CDCStreamPublisher) GetStatistics() *adapter.CDCStatistics {
 	return p.stats
 }
 
-// Close closes the stream publisher
+// Close waits for the messages in flight. The producer belongs to the stream connection and
+// stays open.
 func (p *CDCStreamPublisher) Close() error {
-	// Cleanup resources if needed
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return p.producer.Flush(ctx)
 }
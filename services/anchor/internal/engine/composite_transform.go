@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// transformRow applies mapping rules to a row. The adapter transforms the values of one-to-one
+// rules; many-to-one and one-to-many rules, which adapters do not know, and one-to-one rules
+// with transformation options or masking transformations, whose options and tenant adapters do
+// not pass on, are applied here with the transformation service.
+func transformRow(ctx context.Context, ops adapter.ReplicationOperator, data map[string]interface{}, rules []adapter.TransformationRule, transformationServiceEndpoint string) (map[string]interface{}, error) {
+	var single, composite []adapter.TransformationRule
+	for _, rule := range rules {
+		if rule.IsComposite() || hasTransformationOptions(rule) || usesMasking(rule) {
+			composite = append(composite, rule)
+		} else {
+			single = append(single, rule)
+		}
+	}
+	if len(composite) == 0 {
+		return ops.TransformData(ctx, data, rules, transformationServiceEndpoint)
+	}
+
+	transformed := make(map[string]interface{})
+	if len(single) > 0 {
+		var err error
+		transformed, err = ops.TransformData(ctx, data, single, transformationServiceEndpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var client transformationv1.TransformationServiceClient
+	if transformationServiceEndpoint != "" {
+		conn, err := grpc.Dial(transformationServiceEndpoint, spiffe.DialOption())
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to transformation service: %v", err)
+		}
+		defer conn.Close()
+		client = transformationv1.NewTransformationServiceClient(conn)
+	}
+	for _, rule := range composite {
+		// NULL values of one-to-one rules stay NULL, as the adapters keep them
+		if !rule.IsComposite() && rule.SourceColumn != "" {
+			if value, exists := data[rule.SourceColumn]; exists && value == nil {
+				transformed[rule.TargetColumn] = nil
+				continue
+			}
+		}
+		values, err := applyCompositeRule(ctx, client, data, rule)
+		if err != nil {
+			sources, targets := transformationRuleColumns(rule)
+			return nil, fmt.Errorf("failed to apply the rule mapping %v to %v: %v", sources, targets, err)
+		}
+		for column, value := range values {
+			transformed[column] = value
+		}
+	}
+	return transformed, nil
+}
+
+// hasTransformationOptions reports whether a rule transforms its values with options, such as
+// the table of the lookup transformation
+func hasTransformationOptions(rule adapter.TransformationRule) bool {
+	return len(rule.Parameters) > 0 && rule.TransformationName != "" && rule.TransformationName != "direct_mapping"
+}
+
+// maskingTransformations are the transformations keyed with the salt of the tenant of the rule
+var maskingTransformations = map[string]bool{
+	"mask_format": true,
+	"tokenize":    true,
+	"hash_salted": true,
+}
+
+// usesMasking reports whether the transformation of a rule, or a step of its chain, is keyed
+// with the salt of its tenant
+func usesMasking(rule adapter.TransformationRule) bool {
+	for _, step := range strings.Split(rule.TransformationName, "|") {
+		if maskingTransformations[strings.TrimSpace(step)] {
+			return true
+		}
+	}
+	return false
+}
+
+// transformationRuleColumns returns the source and target columns of a rule, those of a
+// one-to-one rule included
+func transformationRuleColumns(rule adapter.TransformationRule) (sources, targets []string) {
+	sources, targets = rule.SourceColumns, rule.TargetColumns
+	if len(sources) == 0 {
+		sources = []string{rule.SourceColumn}
+	}
+	if len(targets) == 0 {
+		targets = []string{rule.TargetColumn}
+	}
+	return sources, targets
+}
+
+// applyCompositeRule applies a many-to-one or one-to-many rule, or a one-to-one rule with
+// transformation options, to a row, and returns the values of its target columns. Rows missing a source column are left to the other rules.
+func applyCompositeRule(ctx context.Context, client transformationv1.TransformationServiceClient, data map[string]interface{}, rule adapter.TransformationRule) (map[string]interface{}, error) {
+	sources, targets := transformationRuleColumns(rule)
+
+	inputs := make([]string, len(sources))
+	for i, column := range sources {
+		value, exists := data[column]
+		if !exists {
+			return nil, nil
+		}
+		if value != nil {
+			inputs[i] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	values := make(map[string]interface{}, len(targets))
+
+	// Without a transformation, a one-to-many rule writes its value to every target column
+	if rule.TransformationName == "" || rule.TransformationName == "direct_mapping" {
+		if len(sources) > 1 {
+			return nil, fmt.Errorf("no transformation combines the %d source columns", len(sources))
+		}
+		for _, column := range targets {
+			values[column] = data[sources[0]]
+		}
+		return values, nil
+	}
+
+	if client == nil {
+		return nil, fmt.Errorf("transformation '%s' needs the transformation service", rule.TransformationName)
+	}
+	req := &transformationv1.TransformRequest{
+		FunctionName: rule.TransformationName,
+		Inputs:       inputs,
+		OutputCount:  int32(len(targets)),
+		TenantId:     rule.TenantID,
+	}
+	if len(rule.Parameters) > 0 {
+		parameters, err := structpb.NewStruct(rule.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transformation parameters: %v", err)
+		}
+		req.Parameters = parameters
+	}
+
+	resp, err := client.Transform(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("transformation service error: %v", err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		return nil, fmt.Errorf("transformation failed: %s", resp.StatusMessage)
+	}
+	if len(resp.Outputs) != len(targets) {
+		return nil, fmt.Errorf("transformation returned %d values for %d target columns", len(resp.Outputs), len(targets))
+	}
+	for i, column := range targets {
+		values[column] = resp.Outputs[i]
+	}
+	return values, nil
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+func TestParseMappingRulesComposite(t *testing.T) {
+	rulesJSON := []byte(`[{"Metadata": {
+		"source_resource_uri": "redb://data/database/db_1/table/users/column/address",
+		"source_uris": ["redb://data/database/db_1/table/users/column/address"],
+		"target_resource_uri": "redb://data/database/db_2/table/people/column/street",
+		"target_uris": ["redb://data/database/db_2/table/people/column/street", "redb://data/database/db_2/table/people/column/city"],
+		"transformation_name": "split",
+		"transformation_options": {"separator": ", "}
+	}}]`)
+
+	rules, err := parseMappingRules(rulesJSON, nil)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("parseMappingRules = %d rules, %v", len(rules), err)
+	}
+	rule := rules[0]
+	if rule.SourceColumn != "address" || rule.TargetColumn != "street" {
+		t.Errorf("columns = %s -> %s, want address -> street", rule.SourceColumn, rule.TargetColumn)
+	}
+	if !rule.IsComposite() || !reflect.DeepEqual(rule.TargetColumns, []string{"street", "city"}) {
+		t.Errorf("target columns = %v, want [street city]", rule.TargetColumns)
+	}
+	if rule.Parameters["separator"] != ", " {
+		t.Errorf("parameters = %v, want the transformation options", rule.Parameters)
+	}
+
+	rule.TransformationName = ""
+	values, err := applyCompositeRule(context.Background(), nil, map[string]interface{}{"address": "1 Main St"}, rule)
+	if want := map[string]interface{}{"street": "1 Main St", "city": "1 Main St"}; err != nil || !reflect.DeepEqual(values, want) {
+		t.Errorf("fan-out = %v, %v, want %v", values, err, want)
+	}
+	rule.TransformationName = "split"
+	if _, err := applyCompositeRule(context.Background(), nil, map[string]interface{}{"address": "1 Main St"}, rule); err == nil {
+		t.Error("transformation without the transformation service was applied")
+	}
+}
+
+func TestTransformRowWithOptions(t *testing.T) {
+	rule := adapter.TransformationRule{
+		SourceColumn:       "country",
+		TargetColumn:       "country_iso",
+		TransformationName: "lookup",
+		Parameters:         map[string]interface{}{"table": "country_codes"},
+	}
+	if !hasTransformationOptions(rule) {
+		t.Fatal("a rule with options is left to the adapter, which does not pass them on")
+	}
+
+	// NULL values are kept without calling the transformation service
+	transformed, err := transformRow(context.Background(), nil, map[string]interface{}{"country": nil}, []adapter.TransformationRule{rule}, "")
+	if want := map[string]interface{}{"country_iso": nil}; err != nil || !reflect.DeepEqual(transformed, want) {
+		t.Errorf("transformRow = %v, %v, want %v", transformed, err, want)
+	}
+	if _, err := transformRow(context.Background(), nil, map[string]interface{}{"country": "UK"}, []adapter.TransformationRule{rule}, ""); err == nil {
+		t.Error("transformation with options was applied without the transformation service")
+	}
+}
+
+func TestUsesMasking(t *testing.T) {
+	rulesJSON := []byte(`[{"TenantID": "tenant_1", "Metadata": {
+		"source_column": "ssn", "target_column": "ssn_token", "transformation_name": "trim|tokenize"
+	}}]`)
+	rules, err := parseMappingRules(rulesJSON, nil)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("parseMappingRules = %d rules, %v", len(rules), err)
+	}
+	if rules[0].TenantID != "tenant_1" {
+		t.Errorf("tenant = %q, want tenant_1", rules[0].TenantID)
+	}
+	if !usesMasking(rules[0]) {
+		t.Error("a rule masking in a step of its chain is left to the adapter, which does not pass the tenant on")
+	}
+	if usesMasking(adapter.TransformationRule{TransformationName: "uppercase"}) {
+		t.Error("a rule without masking is not left to the adapter")
+	}
+}
@@ -4,39 +4,47 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/config"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/service"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	internalconfig "github.com/redbco/redb-open/services/anchor/internal/config"
 	internaldatabase "github.com/redbco/redb-open/services/anchor/internal/database"
+	"github.com/redbco/redb-open/services/anchor/internal/database/filesource"
+	"github.com/redbco/redb-open/services/anchor/internal/proxy"
 	"github.com/redbco/redb-open/services/anchor/internal/resources"
 	"github.com/redbco/redb-open/services/anchor/internal/state"
+	internalstream "github.com/redbco/redb-open/services/anchor/internal/stream"
 	"github.com/redbco/redb-open/services/anchor/internal/watcher"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Engine struct {
-	config               *config.Config
-	grpcServer           *grpc.Server
-	database             *database.PostgreSQL
-	coreConn             *grpc.ClientConn
-	umConn               *grpc.ClientConn
-	configWatcher        *watcher.ConfigWatcher
-	schemaWatcher        *watcher.SchemaWatcher
-	replicationWatcher   *watcher.ReplicationWatcher
+	config                *config.Config
+	grpcServer            *grpc.Server
+	database              *database.PostgreSQL
+	coreConn              *grpc.ClientConn
+	umConn                *grpc.ClientConn
+	configWatcher         *watcher.ConfigWatcher
+	schemaWatcher         *watcher.SchemaWatcher
+	replicationWatcher    *watcher.ReplicationWatcher
 	resourceStatusMonitor *watcher.ResourceStatusMonitor
-	nodeID               string
-	standalone           bool
-	logger               *logger.Logger
-	state                struct {
+	proxies               *proxy.Manager
+	streams               *internalstream.Manager
+	nodeID                string
+	standalone            bool
+	logger                *logger.Logger
+	state                 struct {
 		sync.Mutex
 		isRunning         bool
 		ongoingOperations int32
@@ -44,6 +52,7 @@ type Engine struct {
 	metrics struct {
 		requestsProcessed int64
 		errors            int64
+		pendingCopyJobs   int64 // Jobs of initial syncs waiting for a copy worker
 	}
 	// Add context and cancel function for watcher shutdown
 	watcherCtx    context.Context
@@ -129,19 +138,28 @@ func (e *Engine) Start(ctx context.Context) error {
 
 	// Initialize global state with database and nodeID
 	globalState := state.GetInstance()
+	globalState.GetConnectionManager().Metrics().SetPlanCapture(e.planCaptureConfig())
+	globalState.GetConnectionManager().SetMetadataCacheTTL(e.metadataCacheTTL())
+
+	// File sources may only read uploads from the directory shared with the client API
+	if e.config != nil {
+		filesource.SetUploadDir(e.config.Get("services.anchor.upload_dir"))
+	}
+	e.proxies = proxy.NewManager(e.logger)
+	e.streams = internalstream.NewManager(e.logger)
 
 	// Initialize gRPC connections to other services (unless standalone)
 	if !e.standalone {
 		// Initialize gRPC connection to Core service using dynamic address resolution
 		coreAddr := e.getServiceAddress("core")
-		e.coreConn, err = grpc.Dial(coreAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		e.coreConn, err = grpc.Dial(coreAddr, spiffe.DialOption())
 		if err != nil {
 			return fmt.Errorf("failed to connect to Core service: %w", err)
 		}
 
 		// Initialize gRPC connection to UnifiedModel service using dynamic address resolution
 		umAddr := e.getServiceAddress("unifiedmodel")
-		e.umConn, err = grpc.Dial(umAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		e.umConn, err = grpc.Dial(umAddr, spiffe.DialOption())
 		if err != nil {
 			return fmt.Errorf("failed to connect to UnifiedModel service: %w", err)
 		}
@@ -150,18 +168,19 @@ func (e *Engine) Start(ctx context.Context) error {
 		configRepository := internalconfig.NewRepository(e.database, e.coreConn)
 		globalState.Initialize(configRepository, e.nodeID)
 		globalState.SetDB(e.database)
+		e.streams.SetCheckpointRepository(configRepository)
 
 		// Create watchers
 		e.configWatcher = watcher.NewConfigWatcher(globalState.GetConfigRepository(), "", e.logger)
-	e.schemaWatcher = watcher.NewSchemaWatcher(globalState.GetDB(), e.umConn, e.coreConn, "", e.logger)
-	e.replicationWatcher = watcher.NewReplicationWatcher(globalState.GetConfigRepository(), e.logger)
+		e.schemaWatcher = watcher.NewSchemaWatcher(globalState.GetDB(), e.umConn, e.coreConn, "", e.logger)
+		e.replicationWatcher = watcher.NewReplicationWatcher(globalState.GetConfigRepository(), e.logger)
 
-	// Create resource repository and status monitor
-	resourceRepo := resources.NewRepository(e.database.Pool())
-	e.resourceStatusMonitor = watcher.NewResourceStatusMonitor(e.database.Pool(), resourceRepo, e.logger)
+		// Create resource repository and status monitor
+		resourceRepo := resources.NewRepository(e.database.Pool())
+		e.resourceStatusMonitor = watcher.NewResourceStatusMonitor(e.database.Pool(), resourceRepo, e.logger)
 
-	// Create context for watchers with cancellation
-	e.watcherCtx, e.watcherCancel = context.WithCancel(ctx)
+		// Create context for watchers with cancellation
+		e.watcherCtx, e.watcherCancel = context.WithCancel(ctx)
 
 		// Perform initial database connections with retry logic
 		maxRetries := 3
@@ -183,15 +202,21 @@ func (e *Engine) Start(ctx context.Context) error {
 			}
 		}
 
+		// Restart the query proxies of the node once their databases are connected
+		e.startQueryProxies(ctx)
+
+		// Attach the stream platforms of the node that were connected
+		e.connectStreams(ctx)
+
 		// Start watchers with the cancellable context
-	go e.configWatcher.Start(e.watcherCtx)
-	go e.schemaWatcher.Start(e.watcherCtx)
-	go e.replicationWatcher.Start(e.watcherCtx)
-	go e.resourceStatusMonitor.Start(e.watcherCtx)
-} else {
-	// In standalone mode, initialize state without external dependencies
-	globalState.Initialize(nil, e.nodeID)
-}
+		go e.configWatcher.Start(e.watcherCtx)
+		go e.schemaWatcher.Start(e.watcherCtx)
+		go e.replicationWatcher.Start(e.watcherCtx)
+		go e.resourceStatusMonitor.Start(e.watcherCtx)
+	} else {
+		// In standalone mode, initialize state without external dependencies
+		globalState.Initialize(nil, e.nodeID)
+	}
 
 	// Service is already registered in SetGRPCServer, just mark as running
 	e.state.isRunning = true
@@ -254,6 +279,11 @@ func (e *Engine) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Close the query proxies and the sessions of applications
+	if e.proxies != nil {
+		e.proxies.StopAll()
+	}
+
 	// Gracefully stop and save CDC replication streams
 	if e.logger != nil {
 		e.logger.Info("Stopping active CDC replication streams...")
@@ -331,6 +361,13 @@ func (e *Engine) Stop(ctx context.Context) error {
 				}
 			}
 
+			// Wait for the events being produced to the target stream
+			if stream.StreamPublisher != nil {
+				if err := stream.StreamPublisher.Close(); err != nil && e.logger != nil {
+					e.logger.Warnf("Error flushing stream publisher %s: %v", id, err)
+				}
+			}
+
 			// Signal stop
 			if stream.StopChan != nil {
 				close(stream.StopChan)
@@ -349,6 +386,12 @@ func (e *Engine) Stop(ctx context.Context) error {
 		e.logger.Info("No active CDC streams to shutdown")
 	}
 
+	// Close the connections to stream platforms once CDC no longer produces to them. Their
+	// status is kept, so they are attached again when the anchor starts.
+	if e.streams != nil {
+		e.streams.CloseAll()
+	}
+
 	// Update connection statuses before disconnecting (only in non-standalone mode)
 	if !e.standalone {
 		globalState := state.GetInstance()
@@ -454,9 +497,14 @@ func (e *Engine) Stop(ctx context.Context) error {
 }
 
 func (e *Engine) GetMetrics() map[string]int64 {
+	adapterCalls, adapterErrors := state.GetInstance().GetConnectionManager().Metrics().Totals()
 	return map[string]int64{
-		"requests_processed": atomic.LoadInt64(&e.metrics.requestsProcessed),
-		"errors":             atomic.LoadInt64(&e.metrics.errors),
+		"requests_processed":       atomic.LoadInt64(&e.metrics.requestsProcessed),
+		"errors":                   atomic.LoadInt64(&e.metrics.errors),
+		"adapter_operations":       adapterCalls,
+		"adapter_operation_errors": adapterErrors,
+		service.MetricQueueDepth:   int64(atomic.LoadInt32(&e.state.ongoingOperations)),
+		service.MetricPendingRuns:  atomic.LoadInt64(&e.metrics.pendingCopyJobs),
 	}
 }
 
@@ -587,3 +635,38 @@ func (e *Engine) GetState() *state.GlobalState {
 func (e *Engine) getServiceAddress(serviceName string) string {
 	return grpcconfig.GetServiceAddress(e.config, serviceName)
 }
+
+// planCaptureConfig returns the settings for capturing query plans of slow fetches.
+// A slow_fetch_threshold_ms of 0 disables the capture.
+func (e *Engine) planCaptureConfig() adapter.PlanCaptureConfig {
+	cfg := adapter.DefaultPlanCaptureConfig()
+	if e.config == nil {
+		return cfg
+	}
+	if v := e.config.Get("services.anchor.slow_fetch_threshold_ms"); v != "" {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil && ms >= 0 {
+			cfg.Threshold = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if v := e.config.Get("services.anchor.slow_fetch_explain_analyze"); v != "" {
+		if analyze, err := strconv.ParseBool(v); err == nil {
+			cfg.Analyze = analyze
+		}
+	}
+	return cfg
+}
+
+// metadataCacheTTL returns how long the metadata of databases and instances is cached.
+// A metadata_cache_ttl_ms of 0 disables the cache.
+func (e *Engine) metadataCacheTTL() time.Duration {
+	ttl := internaldatabase.DefaultMetadataCacheTTL
+	if e.config == nil {
+		return ttl
+	}
+	if v := e.config.Get("services.anchor.metadata_cache_ttl_ms"); v != "" {
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil && ms >= 0 {
+			ttl = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return ttl
+}
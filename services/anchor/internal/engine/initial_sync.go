@@ -0,0 +1,458 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+const (
+	defaultInitialSyncWorkers = 4
+	// defaultInitialSyncWorkersPerDatabase bounds the copy workers reading from or writing
+	// to a database at once, across all initial syncs of the node
+	defaultInitialSyncWorkersPerDatabase = 4
+)
+
+// initialSyncTable is a table being copied by an initial sync
+type initialSyncTable struct {
+	sourceTable string
+	targetTable string
+	rules       []adapter.TransformationRule
+	filters     *rowFilters // nil when the mapping and its rules have no row filters
+	pending     int         // ranges of the table left to copy
+	rowsCopied  int64       // guarded by the sync's mutex
+}
+
+// initialSyncJob is the work of one worker: a whole table, or a range of its key
+type initialSyncJob struct {
+	table    *initialSyncTable
+	keyRange *adapter.KeyRange
+}
+
+func (j initialSyncJob) String() string {
+	if j.keyRange == nil {
+		return j.table.sourceTable
+	}
+	return fmt.Sprintf("%s (%s)", j.table.sourceTable, j.keyRange)
+}
+
+// databaseCopyLimiter limits the copy workers using a database at once, so that parallel
+// initial syncs do not overload a database
+type databaseCopyLimiter struct {
+	mu    sync.Mutex
+	limit int
+	slots map[string]chan struct{}
+}
+
+var (
+	copyLimiter     *databaseCopyLimiter
+	copyLimiterOnce sync.Once
+)
+
+// getDatabaseCopyLimiter returns the limiter shared by all initial syncs of the node
+func (e *Engine) getDatabaseCopyLimiter() *databaseCopyLimiter {
+	copyLimiterOnce.Do(func() {
+		copyLimiter = &databaseCopyLimiter{
+			limit: e.initialSyncWorkersPerDatabase(),
+			slots: make(map[string]chan struct{}),
+		}
+	})
+	return copyLimiter
+}
+
+// acquire takes a slot on each database and returns the function releasing them. Slots
+// are taken in the order of the database IDs, so that workers cannot deadlock.
+func (l *databaseCopyLimiter) acquire(ctx context.Context, databaseIDs ...string) (func(), error) {
+	ids := append([]string(nil), databaseIDs...)
+	sort.Strings(ids)
+
+	var held []chan struct{}
+	release := func() {
+		for _, slot := range held {
+			<-slot
+		}
+	}
+
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		slot := l.slot(id)
+		select {
+		case slot <- struct{}{}:
+			held = append(held, slot)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+func (l *databaseCopyLimiter) slot(databaseID string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slot, ok := l.slots[databaseID]
+	if !ok {
+		slot = make(chan struct{}, l.limit)
+		l.slots[databaseID] = slot
+	}
+	return slot
+}
+
+// initialSyncWorkersPerDatabase returns the limit of copy workers per database
+func (e *Engine) initialSyncWorkersPerDatabase() int {
+	if e.config != nil {
+		if v := e.config.Get("services.anchor.initial_sync_workers_per_database"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return defaultInitialSyncWorkersPerDatabase
+}
+
+// CopyTables copies tables from a source to a target database for the initial sync of a
+// relationship. A pool of workers copies several tables at once, and splits large tables
+// with an integer primary key into ranges copied in parallel when the source database can
+// read key ranges. Progress is streamed whenever a table or range finishes.
+func (e *Engine) CopyTables(req *anchorv1.CopyTablesRequest, stream anchorv1.AnchorService_CopyTablesServer) error {
+	if req.SourceDatabaseId == "" || req.TargetDatabaseId == "" || len(req.Tables) == 0 {
+		return status.Errorf(codes.InvalidArgument, "source_database_id, target_database_id and tables are required")
+	}
+
+	registry := e.GetState().GetConnectionRegistry()
+	if registry == nil {
+		return status.Errorf(codes.Internal, "connection registry not available")
+	}
+
+	sourceConn, err := registry.GetAdapterConnection(req.SourceDatabaseId)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "source database not found: %v", err)
+	}
+	targetConn, err := registry.GetAdapterConnection(req.TargetDatabaseId)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "target database not found: %v", err)
+	}
+
+	batchSize := adapter.DefaultFetchBatchSize
+	if req.BatchSize != nil && *req.BatchSize > 0 {
+		batchSize = int(*req.BatchSize)
+	}
+	workers := defaultInitialSyncWorkers
+	if req.ParallelWorkers != nil && *req.ParallelWorkers > 0 {
+		workers = int(*req.ParallelWorkers)
+	}
+	rangesPerTable := workers
+	if req.RangesPerTable != nil && *req.RangesPerTable > 0 {
+		rangesPerTable = int(*req.RangesPerTable)
+	}
+
+	var rules []adapter.TransformationRule
+	if len(req.MappingRules) > 0 {
+		rules, err = parseMappingRules(req.MappingRules, e.logger)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to parse mapping rules: %v", err)
+		}
+	}
+	filters, err := newRowFilters(req.RowFilter, rules)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Plan the jobs, in the order of the tables of the request
+	var jobs []initialSyncJob
+	tables := make([]*initialSyncTable, 0, len(req.Tables))
+	for _, spec := range req.Tables {
+		table := &initialSyncTable{
+			sourceTable: spec.SourceTable,
+			targetTable: spec.TargetTable,
+			rules:       tableTransformationRules(rules, spec.SourceTable),
+			filters:     filters,
+		}
+		if table.targetTable == "" {
+			table.targetTable = table.sourceTable
+		}
+		tables = append(tables, table)
+
+		tableJobs := e.planTableCopy(ctx, sourceConn, table, rangesPerTable)
+		table.pending = len(tableJobs)
+		jobs = append(jobs, tableJobs...)
+	}
+
+	e.logger.Infof("Starting initial sync of %d tables (%d jobs) from %s to %s with %d workers",
+		len(tables), len(jobs), req.SourceDatabaseId, req.TargetDatabaseId, workers)
+
+	limiter := e.getDatabaseCopyLimiter()
+	transformationServiceEndpoint := e.getServiceAddress("transformation")
+
+	var (
+		mu         sync.Mutex // guards the progress and stream.Send
+		totalRows  int64
+		firstError error
+	)
+
+	jobChan := make(chan initialSyncJob)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(jobs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				rows, err := e.runCopyJob(ctx, limiter, req, sourceConn, targetConn, job, batchSize, transformationServiceEndpoint)
+
+				mu.Lock()
+				totalRows += rows
+				job.table.rowsCopied += rows
+				if err != nil {
+					if firstError == nil {
+						firstError = fmt.Errorf("failed to copy %s: %v", job, err)
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+
+				job.table.pending--
+				message := fmt.Sprintf("Copied %d rows of %s", rows, job)
+				if job.table.pending == 0 {
+					message = fmt.Sprintf("Copied %d rows from %s", job.table.rowsCopied, job.table.sourceTable)
+				}
+				if err := stream.Send(&anchorv1.CopyTablesResponse{
+					Message:         message,
+					Success:         true,
+					Status:          commonv1.Status_STATUS_PENDING,
+					SourceTable:     job.table.sourceTable,
+					TargetTable:     job.table.targetTable,
+					TableRowsCopied: job.table.rowsCopied,
+					TableComplete:   job.table.pending == 0,
+					TotalRowsCopied: totalRows,
+				}); err != nil && firstError == nil {
+					firstError = fmt.Errorf("failed to send progress: %v", err)
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	atomic.AddInt64(&e.metrics.pendingCopyJobs, int64(len(jobs)))
+	dispatched := 0
+dispatch:
+	for _, job := range jobs {
+		select {
+		case jobChan <- job:
+			dispatched++
+			atomic.AddInt64(&e.metrics.pendingCopyJobs, -1)
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	atomic.AddInt64(&e.metrics.pendingCopyJobs, int64(dispatched-len(jobs)))
+	close(jobChan)
+	wg.Wait()
+
+	if firstError == nil && ctx.Err() != nil {
+		firstError = ctx.Err()
+	}
+	if firstError != nil {
+		e.logger.Errorf("Initial sync from %s to %s failed after %d rows: %v",
+			req.SourceDatabaseId, req.TargetDatabaseId, totalRows, firstError)
+		return stream.Send(&anchorv1.CopyTablesResponse{
+			Message:         firstError.Error(),
+			Success:         false,
+			Status:          commonv1.Status_STATUS_ERROR,
+			TotalRowsCopied: totalRows,
+			IsComplete:      true,
+		})
+	}
+
+	e.logger.Infof("Initial sync from %s to %s completed: %d rows copied",
+		req.SourceDatabaseId, req.TargetDatabaseId, totalRows)
+	return stream.Send(&anchorv1.CopyTablesResponse{
+		Message:         fmt.Sprintf("Copied %d rows from %d tables", totalRows, len(tables)),
+		Success:         true,
+		Status:          commonv1.Status_STATUS_SUCCESS,
+		TotalRowsCopied: totalRows,
+		IsComplete:      true,
+	})
+}
+
+// planTableCopy returns the jobs copying a table: one per range of its key if the table
+// can be split, else a single job for the whole table
+func (e *Engine) planTableCopy(ctx context.Context, sourceConn adapter.Connection, table *initialSyncTable, rangesPerTable int) []initialSyncJob {
+	whole := []initialSyncJob{{table: table}}
+	if rangesPerTable < 2 {
+		return whole
+	}
+
+	reader, ok := adapter.AsKeyRangeReader(sourceConn.DataOperations())
+	if !ok {
+		return whole
+	}
+
+	keyColumn := e.splitKeyColumn(ctx, sourceConn, table.sourceTable)
+	if keyColumn == "" {
+		return whole
+	}
+
+	bounds, err := reader.KeyBounds(ctx, table.sourceTable, keyColumn)
+	if err != nil {
+		e.logger.Warnf("Failed to read key bounds of %s, copying it as a whole: %v", table.sourceTable, err)
+		return whole
+	}
+
+	ranges := adapter.SplitKeyRange(bounds, rangesPerTable)
+	if len(ranges) < 2 {
+		return whole
+	}
+
+	jobs := make([]initialSyncJob, len(ranges))
+	for i := range ranges {
+		jobs[i] = initialSyncJob{table: table, keyRange: &ranges[i]}
+	}
+	return jobs
+}
+
+// splitKeyColumn returns the primary key column a table can be split by: the only column
+// of its primary key, if it is an integer column. It returns "" otherwise.
+func (e *Engine) splitKeyColumn(ctx context.Context, conn adapter.Connection, tableName string) string {
+	table, err := conn.SchemaOperations().GetTableSchema(ctx, tableName)
+	if err != nil || table == nil {
+		return ""
+	}
+
+	var keyColumns []string
+	for _, constraint := range table.Constraints {
+		if constraint.Type == unifiedmodel.ConstraintTypePrimaryKey {
+			keyColumns = constraint.Columns
+			break
+		}
+	}
+	if len(keyColumns) == 0 {
+		for name, column := range table.Columns {
+			if column.IsPrimaryKey {
+				keyColumns = append(keyColumns, name)
+			}
+		}
+	}
+	if len(keyColumns) != 1 {
+		return ""
+	}
+
+	column, ok := table.Columns[keyColumns[0]]
+	if !ok || !isIntegerType(column.DataType) {
+		return ""
+	}
+	return keyColumns[0]
+}
+
+// isIntegerType reports whether a column data type is an integer type
+func isIntegerType(dataType string) bool {
+	switch strings.ToLower(strings.TrimSpace(dataType)) {
+	case "smallint", "integer", "int", "bigint", "int2", "int4", "int8",
+		"smallserial", "serial", "bigserial", "serial2", "serial4", "serial8",
+		"tinyint", "mediumint":
+		return true
+	}
+	return false
+}
+
+// runCopyJob copies a table or a range of it, batch by batch, and returns the rows copied
+func (e *Engine) runCopyJob(
+	ctx context.Context,
+	limiter *databaseCopyLimiter,
+	req *anchorv1.CopyTablesRequest,
+	sourceConn, targetConn adapter.Connection,
+	job initialSyncJob,
+	batchSize int,
+	transformationServiceEndpoint string,
+) (int64, error) {
+	release, err := limiter.acquire(ctx, req.SourceDatabaseId, req.TargetDatabaseId)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	var it adapter.RowBatchIterator
+	if job.keyRange != nil {
+		reader, _ := adapter.AsKeyRangeReader(sourceConn.DataOperations())
+		it, err = reader.FetchKeyRange(ctx, job.table.sourceTable, *job.keyRange, batchSize)
+	} else {
+		it, err = sourceConn.DataOperations().FetchStream(ctx, job.table.sourceTable, batchSize)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source: %v", err)
+	}
+	defer it.Close()
+
+	var rowsCopied int64
+	for {
+		rows, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rowsCopied, fmt.Errorf("failed to read source: %v", err)
+		}
+
+		if len(job.table.rules) > 0 || job.table.filters != nil {
+			written := rows[:0]
+			for _, row := range rows {
+				rules, ok := job.table.filters.selectRules(row, job.table.rules)
+				if !ok {
+					continue
+				}
+				if len(rules) > 0 {
+					row, err = transformRow(ctx, targetConn.ReplicationOperations(), row, rules, transformationServiceEndpoint)
+					if err != nil {
+						return rowsCopied, fmt.Errorf("failed to transform row: %v", err)
+					}
+				}
+				written = append(written, row)
+			}
+			rows = written
+			if len(rows) == 0 {
+				continue
+			}
+		}
+
+		n, err := adapter.BulkInsert(ctx, targetConn.DataOperations(), job.table.targetTable, rows)
+		if err != nil {
+			return rowsCopied, fmt.Errorf("failed to write target: %v", err)
+		}
+		rowsCopied += n
+	}
+
+	e.logger.Debugf("Copied %d rows of %s to %s", rowsCopied, job, job.table.targetTable)
+	return rowsCopied, nil
+}
+
+// tableTransformationRules returns the rules of a source table. Rules without a source
+// table apply to all tables.
+func tableTransformationRules(rules []adapter.TransformationRule, sourceTable string) []adapter.TransformationRule {
+	var tableRules []adapter.TransformationRule
+	for _, rule := range rules {
+		if rule.SourceTable == "" || rule.SourceTable == sourceTable {
+			tableRules = append(tableRules, rule)
+		}
+	}
+	return tableRules
+}
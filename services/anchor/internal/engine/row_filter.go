@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/rowfilter"
+)
+
+// rowFilters are the compiled row filters of a mapping and of its rules. A row is written to
+// the target only when it matches the filter of the mapping and, if the rules of its table
+// have filters, the filter of at least one of them; it is then mapped by the matching rules
+// and the rules without a filter. A nil rowFilters writes every row with all rules.
+type rowFilters struct {
+	mapping *rowfilter.Filter
+	rules   map[string]*rowfilter.Filter // by expression
+}
+
+// newRowFilters compiles the row filter of a mapping and those of its rules. It returns nil
+// when there are none.
+func newRowFilters(mappingFilter string, rules []adapter.TransformationRule) (*rowFilters, error) {
+	filters := &rowFilters{rules: make(map[string]*rowfilter.Filter)}
+	if mappingFilter != "" {
+		filter, err := rowfilter.Parse(mappingFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid row filter of the mapping: %w", err)
+		}
+		filters.mapping = filter
+	}
+	for _, rule := range rules {
+		if rule.RowFilter == "" || filters.rules[rule.RowFilter] != nil {
+			continue
+		}
+		filter, err := rowfilter.Parse(rule.RowFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid row filter of the rule mapping %s to %s: %w", rule.SourceColumn, rule.TargetColumn, err)
+		}
+		filters.rules[rule.RowFilter] = filter
+	}
+
+	if filters.mapping == nil && len(filters.rules) == 0 {
+		return nil, nil
+	}
+	return filters, nil
+}
+
+// selectRules returns the rules mapping a source row, and whether the row is written at all
+func (f *rowFilters) selectRules(row map[string]interface{}, rules []adapter.TransformationRule) ([]adapter.TransformationRule, bool) {
+	if f == nil {
+		return rules, true
+	}
+	if f.mapping != nil && !f.mapping.Match(row) {
+		return nil, false
+	}
+	if len(f.rules) == 0 || len(rules) == 0 {
+		return rules, true
+	}
+
+	selected := make([]adapter.TransformationRule, 0, len(rules))
+	filtered, matched := false, false
+	for _, rule := range rules {
+		filter := f.rules[rule.RowFilter]
+		if filter == nil {
+			selected = append(selected, rule)
+			continue
+		}
+		filtered = true
+		if filter.Match(row) {
+			matched = true
+			selected = append(selected, rule)
+		}
+	}
+	if filtered && !matched {
+		// None of the conditions of the rules of the table holds
+		return nil, false
+	}
+	return selected, true
+}
+
+// decides reports whether a row has all the columns the filters read. Deletes and the old
+// rows of updates often carry only the key of the row, which cannot tell whether the row was
+// written to the target.
+func (f *rowFilters) decides(row map[string]interface{}) bool {
+	if f == nil {
+		return true
+	}
+	check := func(filter *rowfilter.Filter) bool {
+		for _, column := range filter.Columns() {
+			if _, ok := row[column]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	if f.mapping != nil && !check(f.mapping) {
+		return false
+	}
+	for _, filter := range f.rules {
+		if !check(filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterEvent applies the row filters to a CDC event of a table with the given rules. It
+// returns the rules mapping the event, or false when the event is not written to the target.
+// Updates of rows that leave the filter become deletes and updates of rows that enter it
+// become inserts.
+func (f *rowFilters) filterEvent(event *adapter.CDCEvent, rules []adapter.TransformationRule) ([]adapter.TransformationRule, bool) {
+	if f == nil {
+		return rules, true
+	}
+
+	switch event.Operation {
+	case adapter.CDCInsert:
+		return f.selectRules(event.Data, rules)
+	case adapter.CDCDelete:
+		row := event.OldData
+		if len(row) == 0 {
+			row = event.Data
+		}
+		if !f.decides(row) {
+			// Deleting a row that was never written is harmless
+			return rules, true
+		}
+		return f.selectRules(row, rules)
+	case adapter.CDCUpdate:
+		selected, written := f.selectRules(event.Data, rules)
+		oldWritten := true
+		if len(event.OldData) > 0 && f.decides(event.OldData) {
+			_, oldWritten = f.selectRules(event.OldData, rules)
+		}
+		switch {
+		case written && !oldWritten:
+			event.Operation = adapter.CDCInsert
+			event.OldData = nil
+		case !written && oldWritten:
+			event.Operation = adapter.CDCDelete
+			if len(event.OldData) == 0 {
+				event.OldData = event.Data
+			}
+			return rules, true
+		}
+		return selected, written
+	}
+	return rules, true
+}
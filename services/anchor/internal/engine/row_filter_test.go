@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+func TestRowFiltersSelectRules(t *testing.T) {
+	rules := []adapter.TransformationRule{
+		{SourceColumn: "id", TargetColumn: "id"},
+		{SourceColumn: "price", TargetColumn: "price_eur", RowFilter: "currency = 'EUR'"},
+		{SourceColumn: "price", TargetColumn: "price_usd", RowFilter: "currency = 'USD'"},
+	}
+
+	filters, err := newRowFilters("region = 'EU'", rules)
+	if err != nil {
+		t.Fatalf("newRowFilters: %v", err)
+	}
+
+	selected, written := filters.selectRules(map[string]interface{}{"id": 1, "region": "EU", "currency": "EUR"}, rules)
+	if !written || len(selected) != 2 || selected[1].TargetColumn != "price_eur" {
+		t.Errorf("EUR row selected %v, written %v", selected, written)
+	}
+	if _, written := filters.selectRules(map[string]interface{}{"id": 2, "region": "US", "currency": "EUR"}, rules); written {
+		t.Error("row outside the mapping filter was written")
+	}
+	if _, written := filters.selectRules(map[string]interface{}{"id": 3, "region": "EU", "currency": "GBP"}, rules); written {
+		t.Error("row matching no rule condition was written")
+	}
+
+	if filters, err := newRowFilters("", []adapter.TransformationRule{{SourceColumn: "id"}}); err != nil || filters != nil {
+		t.Errorf("no filters = %v, %v, want nil", filters, err)
+	}
+	var none *rowFilters
+	if selected, written := none.selectRules(map[string]interface{}{"id": 1}, rules); !written || len(selected) != len(rules) {
+		t.Errorf("nil filters selected %d rules, written %v", len(selected), written)
+	}
+	if _, err := newRowFilters("region = ", nil); err == nil {
+		t.Error("invalid mapping filter was accepted")
+	}
+}
+
+func TestRowFiltersFilterEvent(t *testing.T) {
+	filters, err := newRowFilters("region = 'EU'", nil)
+	if err != nil {
+		t.Fatalf("newRowFilters: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		event       adapter.CDCEvent
+		wantWritten bool
+		wantOp      adapter.CDCOperation
+	}{
+		{"insert inside", adapter.CDCEvent{Operation: adapter.CDCInsert, Data: map[string]interface{}{"id": 1, "region": "EU"}}, true, adapter.CDCInsert},
+		{"insert outside", adapter.CDCEvent{Operation: adapter.CDCInsert, Data: map[string]interface{}{"id": 1, "region": "US"}}, false, adapter.CDCInsert},
+		{"update inside", adapter.CDCEvent{Operation: adapter.CDCUpdate,
+			Data: map[string]interface{}{"id": 1, "region": "EU"}, OldData: map[string]interface{}{"id": 1, "region": "EU"}}, true, adapter.CDCUpdate},
+		{"update entering", adapter.CDCEvent{Operation: adapter.CDCUpdate,
+			Data: map[string]interface{}{"id": 1, "region": "EU"}, OldData: map[string]interface{}{"id": 1, "region": "US"}}, true, adapter.CDCInsert},
+		{"update leaving", adapter.CDCEvent{Operation: adapter.CDCUpdate,
+			Data: map[string]interface{}{"id": 1, "region": "US"}, OldData: map[string]interface{}{"id": 1, "region": "EU"}}, true, adapter.CDCDelete},
+		{"update leaving, key only", adapter.CDCEvent{Operation: adapter.CDCUpdate,
+			Data: map[string]interface{}{"id": 1, "region": "US"}, OldData: map[string]interface{}{"id": 1}}, true, adapter.CDCDelete},
+		{"update outside", adapter.CDCEvent{Operation: adapter.CDCUpdate,
+			Data: map[string]interface{}{"id": 1, "region": "US"}, OldData: map[string]interface{}{"id": 1, "region": "UK"}}, false, adapter.CDCUpdate},
+		{"delete outside", adapter.CDCEvent{Operation: adapter.CDCDelete, OldData: map[string]interface{}{"id": 1, "region": "US"}}, false, adapter.CDCDelete},
+		{"delete, key only", adapter.CDCEvent{Operation: adapter.CDCDelete, OldData: map[string]interface{}{"id": 1}}, true, adapter.CDCDelete},
+	}
+	for _, tt := range tests {
+		event := tt.event
+		_, written := filters.filterEvent(&event, nil)
+		if written != tt.wantWritten || event.Operation != tt.wantOp {
+			t.Errorf("%s: written %v as %s, want %v as %s", tt.name, written, event.Operation, tt.wantWritten, tt.wantOp)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -259,6 +260,40 @@ func (s *Server) GetInstanceMetadata(ctx context.Context, req *pb.GetInstanceMet
 	}, nil
 }
 
+func (s *Server) ListInstanceDatabases(ctx context.Context, req *pb.ListInstanceDatabasesRequest) (*pb.ListInstanceDatabasesResponse, error) {
+	defer s.trackOperation()()
+
+	registry := s.engine.GetState().GetConnectionRegistry()
+	instance, err := registry.GetInstanceClient(req.InstanceId)
+	if err != nil {
+		return &pb.ListInstanceDatabasesResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Instance not found: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			InstanceId: req.InstanceId,
+		}, nil
+	}
+
+	conn := instance.AdapterConnection.(adapter.InstanceConnection)
+	databases, err := conn.ListDatabases(ctx)
+	if err != nil {
+		return &pb.ListInstanceDatabasesResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to list databases: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			InstanceId: req.InstanceId,
+		}, nil
+	}
+
+	return &pb.ListInstanceDatabasesResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d databases", len(databases)),
+		Status:        commonv1.Status_STATUS_SUCCESS,
+		InstanceId:    req.InstanceId,
+		DatabaseNames: databases,
+	}, nil
+}
+
 func (s *Server) CreateDatabase(ctx context.Context, req *pb.CreateDatabaseRequest) (*pb.CreateDatabaseResponse, error) {
 	defer s.trackOperation()()
 
@@ -484,6 +519,19 @@ func (s *Server) DeployDatabaseSchema(ctx context.Context, req *pb.DeployDatabas
 		}, nil
 	}
 
+	// Reject schemas that are not internally consistent before changing the database
+	if report, err := unifiedmodel.Validate(structure, unifiedmodel.ValidationOptions{DisableLint: true}); err == nil && !report.Valid {
+		var problems []string
+		for _, issue := range report.Critical() {
+			problems = append(problems, fmt.Sprintf("%s: %s", issue.Field, issue.Message))
+		}
+		return &pb.DeployDatabaseSchemaResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Schema validation failed: %s", strings.Join(problems, "; ")),
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+
 	// Deploy the database structure via adapter
 	registry := s.engine.GetState().GetConnectionRegistry()
 	client, err := registry.GetDatabaseClient(req.DatabaseId)
@@ -547,7 +595,12 @@ func (s *Server) FetchData(ctx context.Context, req *pb.FetchDataRequest) (*pb.F
 	// Note: Most adapters don't support offset directly, so we fetch with limit
 	// For proper pagination support, we would need to enhance each adapter
 	// For now, we just use the limit parameter
-	data, err := conn.DataOperations().Fetch(ctx, req.TableName, limit)
+	var data []map[string]interface{}
+	if sandbox := req.GetSandbox(); sandbox != "" {
+		data, err = s.fetchSandboxData(ctx, req.DatabaseId, sandbox, req.TableName, limit)
+	} else {
+		data, err = conn.DataOperations().Fetch(ctx, req.TableName, limit)
+	}
 	
 	if err != nil {
 		// Send error response
@@ -1262,6 +1315,63 @@ func (s *Server) ExecuteCommand(ctx context.Context, req *pb.ExecuteCommandReque
 	}, nil
 }
 
+func (s *Server) ExecuteQuery(ctx context.Context, req *pb.ExecuteQueryRequest) (*pb.ExecuteQueryResponse, error) {
+	defer s.trackOperation()()
+
+	registry := s.engine.GetState().GetConnectionRegistry()
+	client, err := registry.GetDatabaseClient(req.DatabaseId)
+	if err != nil {
+		return &pb.ExecuteQueryResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Database not found: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+
+	// The adapter checks that the database supports query passthrough and, for queries,
+	// that they cannot modify the database
+	conn := client.AdapterConnection.(adapter.Connection)
+	start := time.Now()
+	var result *adapter.QueryResult
+	if req.AllowWrites {
+		result, err = adapter.ExecuteCommand(ctx, conn, req.Query, int(req.MaxRows))
+	} else {
+		result, err = adapter.ExecuteQuery(ctx, conn, req.Query, int(req.MaxRows))
+	}
+	if err != nil {
+		return &pb.ExecuteQueryResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to execute query: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+	duration := time.Since(start)
+
+	resultJSON, err := json.Marshal(result.Rows)
+	if err != nil {
+		return &pb.ExecuteQueryResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to marshal result: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+
+	return &pb.ExecuteQueryResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Query returned %d rows", len(result.Rows)),
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		DatabaseId: req.DatabaseId,
+		Columns:    result.Columns,
+		Data:       resultJSON,
+		RowCount:   int32(len(result.Rows)),
+		Truncated:  result.Truncated,
+		DurationMs: duration.Milliseconds(),
+	}, nil
+}
+
 // Refactor CreateReplicationSource
 func (s *Server) CreateReplicationSource(ctx context.Context, req *pb.CreateReplicationSourceRequest) (*pb.CreateReplicationSourceResponse, error) {
 	defer s.trackOperation()()
@@ -1561,43 +1671,62 @@ func (s *Server) StreamTableData(req *pb.StreamTableDataRequest, stream pb.Ancho
 		})
 	}
 
-	// Build column list for SELECT
-	// Note: columns will be handled by the database manager's StreamTableData method
+	// Read the table through a batch iterator, so that memory use is bounded by the batch size
+	client, err := registry.GetDatabaseClient(req.DatabaseId)
+	if err != nil {
+		return stream.Send(&pb.StreamTableDataResponse{
+			Success: false,
+			Message: fmt.Sprintf("Database not found: %v", err),
+		})
+	}
 
-	// Note: Cursor-based pagination will be handled by the database manager
-	// For now, we use simple offset-based pagination
+	conn := client.AdapterConnection.(adapter.Connection)
+	it, err := conn.DataOperations().FetchStream(ctx, req.TableName, int(batchSize))
+	if err != nil {
+		return stream.Send(&pb.StreamTableDataResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to fetch data: %v", err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+			TableName:  req.TableName,
+		})
+	}
+	defer it.Close()
 
+	// Resuming from an offset skips the rows before it
+	skip := offset
 	batchNumber := int64(1)
 	currentOffset := offset
 
+	fetchStart := time.Now()
+	rows, fetchErr := nextStreamBatch(it, &skip)
+	fetchDuration := time.Since(fetchStart)
 	for {
-		// Execute query via adapter
-		registry := s.engine.GetState().GetConnectionRegistry()
-		client, err := registry.GetDatabaseClient(req.DatabaseId)
-		if err != nil {
+		if fetchErr != nil && fetchErr != io.EOF {
 			return stream.Send(&pb.StreamTableDataResponse{
-				Success: false,
-				Message: fmt.Sprintf("Database not found: %v", err),
+				Success:    false,
+				Message:    fmt.Sprintf("Failed to fetch data: %v", fetchErr),
+				Status:     commonv1.Status_STATUS_ERROR,
+				DatabaseId: req.DatabaseId,
+				TableName:  req.TableName,
 			})
 		}
 
-		conn := client.AdapterConnection.(adapter.Connection)
-		// Simple implementation - fetch with limit
-		allRows, err := conn.DataOperations().Fetch(ctx, req.TableName, int(batchSize))
-		if err != nil {
-			return stream.Send(&pb.StreamTableDataResponse{
-				Success: false,
-				Message: fmt.Sprintf("Failed to fetch data: %v", err),
-			})
+		// Read one batch ahead to know whether this batch is the last one
+		var nextRows []map[string]interface{}
+		var nextErr error = io.EOF
+		var nextFetchDuration time.Duration
+		if fetchErr == nil {
+			nextFetchStart := time.Now()
+			nextRows, nextErr = nextStreamBatch(it, &skip)
+			nextFetchDuration = time.Since(nextFetchStart)
 		}
+		isComplete := nextErr == io.EOF
 
-		// Slice for current batch
-		startIdx := int(currentOffset)
-		endIdx := min(startIdx+int(batchSize), len(allRows))
-
-		rows := allRows[startIdx:endIdx]
-		isComplete := endIdx >= len(allRows)
-		nextCursorValue := int64(endIdx)
+		if rows == nil {
+			rows = []map[string]interface{}{}
+		}
+		currentOffset += int64(len(rows))
 
 		// Convert rows to JSON
 		jsonData, err := json.Marshal(rows)
@@ -1611,8 +1740,6 @@ func (s *Server) StreamTableData(req *pb.StreamTableDataRequest, stream pb.Ancho
 			})
 		}
 
-		rowCount := int64(len(rows))
-
 		// Send batch response
 		err = stream.Send(&pb.StreamTableDataResponse{
 			Success:         true,
@@ -1622,11 +1749,11 @@ func (s *Server) StreamTableData(req *pb.StreamTableDataRequest, stream pb.Ancho
 			TableName:       req.TableName,
 			Data:            jsonData,
 			IsComplete:      isComplete,
-			NextCursorValue: fmt.Sprintf("%d", nextCursorValue),
+			NextCursorValue: fmt.Sprintf("%d", currentOffset),
 			BatchNumber:     batchNumber,
-			RowsInBatch:     rowCount,
+			RowsInBatch:     int64(len(rows)),
+			FetchDurationUs: fetchDuration.Microseconds(),
 		})
-
 		if err != nil {
 			return err
 		}
@@ -1638,12 +1765,29 @@ func (s *Server) StreamTableData(req *pb.StreamTableDataRequest, stream pb.Ancho
 
 		// Prepare for next batch
 		batchNumber++
-		currentOffset += int64(batchSize)
+		rows, fetchErr, fetchDuration = nextRows, nextErr, nextFetchDuration
 	}
 
 	return nil
 }
 
+// nextStreamBatch returns the next batch of an iterator, dropping the first skip rows
+func nextStreamBatch(it adapter.RowBatchIterator, skip *int64) ([]map[string]interface{}, error) {
+	for {
+		rows, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if *skip >= int64(len(rows)) {
+			*skip -= int64(len(rows))
+			continue
+		}
+		rows = rows[*skip:]
+		*skip = 0
+		return rows, nil
+	}
+}
+
 // InsertBatchData inserts a batch of data into a table efficiently
 func (s *Server) InsertBatchData(ctx context.Context, req *pb.InsertBatchDataRequest) (*pb.InsertBatchDataResponse, error) {
 	defer s.trackOperation()()
@@ -1697,11 +1841,21 @@ func (s *Server) InsertBatchData(ctx context.Context, req *pb.InsertBatchDataReq
 
 	// Use transaction if requested
 	useTransaction := req.UseTransaction != nil && *req.UseTransaction
+	sandbox := req.GetSandbox()
 
 	var rowsAffected int64
 	var errors []string
 
-	if useTransaction {
+	writeStart := time.Now()
+	if sandbox != "" {
+		// Sandbox tables are always written in a single transaction
+		affected, err := s.insertSandboxBatch(ctx, req.DatabaseId, sandbox, req.TableName, rows)
+		if err != nil {
+			errors = append(errors, err.Error())
+		} else {
+			rowsAffected = affected
+		}
+	} else if useTransaction {
 		// Execute as a single transaction
 		affected, err := s.insertBatchWithTransaction(client, req.TableName, rows)
 		if err != nil {
@@ -1721,6 +1875,8 @@ func (s *Server) InsertBatchData(ctx context.Context, req *pb.InsertBatchDataReq
 		}
 	}
 
+	writeDuration := time.Since(writeStart)
+
 	success := len(errors) == 0
 	message := fmt.Sprintf("Inserted %d rows successfully", rowsAffected)
 	if len(errors) > 0 {
@@ -1733,14 +1889,15 @@ func (s *Server) InsertBatchData(ctx context.Context, req *pb.InsertBatchDataReq
 	}
 
 	return &pb.InsertBatchDataResponse{
-		Success:      success,
-		Message:      message,
-		Status:       commonv1.Status_STATUS_SUCCESS,
-		DatabaseId:   req.DatabaseId,
-		TableName:    req.TableName,
-		RowsAffected: rowsAffected,
-		Errors:       errors,
-		OperationId:  operationID,
+		Success:         success,
+		Message:         message,
+		Status:          commonv1.Status_STATUS_SUCCESS,
+		DatabaseId:      req.DatabaseId,
+		TableName:       req.TableName,
+		RowsAffected:    rowsAffected,
+		Errors:          errors,
+		OperationId:     operationID,
+		WriteDurationUs: writeDuration.Microseconds(),
 	}, nil
 }
 
@@ -1772,11 +1929,10 @@ func (s *Server) GetTableRowCount(ctx context.Context, req *pb.GetTableRowCountR
 		}, nil
 	}
 
-	// Get row count via adapter - fetch all and count (simple implementation)
+	// Get row count via adapter
 	conn := client.AdapterConnection.(adapter.Connection)
-	rows, err := conn.DataOperations().Fetch(ctx, req.TableName, 1000000) // Large limit
-	rowCount := int64(len(rows))
-	isEstimate := false
+	rowCount, exact, err := conn.DataOperations().GetRowCount(ctx, req.TableName, "")
+	isEstimate := !exact
 	if err != nil {
 		return &pb.GetTableRowCountResponse{
 			Success:    false,
@@ -1831,10 +1987,26 @@ func (s *Server) executeCountQuery(client *dbclient.DatabaseClient, query string
 }
 
 func (s *Server) insertBatchWithTransaction(client *dbclient.DatabaseClient, tableName string, rows []map[string]interface{}) (int64, error) {
-	// Use adapter to insert data
+	// Use the native bulk load of the database when it has one
 	conn := client.AdapterConnection.(adapter.Connection)
 	ctx := context.Background()
-	return conn.DataOperations().Insert(ctx, tableName, rows)
+	return adapter.BulkInsert(ctx, conn.DataOperations(), tableName, rows)
+}
+
+func (s *Server) insertSandboxBatch(ctx context.Context, databaseID, sandbox, tableName string, rows []map[string]interface{}) (int64, error) {
+	sandboxer, err := s.sandboxer(databaseID)
+	if err != nil {
+		return 0, err
+	}
+	return sandboxer.InsertSandbox(ctx, sandbox, tableName, rows)
+}
+
+func (s *Server) fetchSandboxData(ctx context.Context, databaseID, sandbox, tableName string, limit int) ([]map[string]interface{}, error) {
+	sandboxer, err := s.sandboxer(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	return sandboxer.FetchSandbox(ctx, sandbox, tableName, limit)
 }
 
 func (s *Server) insertSingleRow(client *dbclient.DatabaseClient, tableName string, row map[string]interface{}) (int64, error) {
@@ -1860,6 +2032,12 @@ func (s *Server) insertSingleRow(client *dbclient.DatabaseClient, tableName stri
 
 // CDC Replication Management Methods
 
+// CopyTables copies tables in parallel for the initial sync of a relationship
+func (s *Server) CopyTables(req *pb.CopyTablesRequest, stream pb.AnchorService_CopyTablesServer) error {
+	defer s.trackOperation()()
+	return s.engine.CopyTables(req, stream)
+}
+
 // StartCDCReplication starts CDC replication for a relationship
 func (s *Server) StartCDCReplication(ctx context.Context, req *pb.StartCDCReplicationRequest) (*pb.StartCDCReplicationResponse, error) {
 	defer s.trackOperation()()
@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// GetAdapterMetrics returns the latency and error metrics of the adapter operations
+// run against the requested databases
+func (s *Server) GetAdapterMetrics(ctx context.Context, req *pb.GetAdapterMetricsRequest) (*pb.GetAdapterMetricsResponse, error) {
+	defer s.trackOperation()()
+
+	recorder := s.engine.GetState().GetConnectionManager().Metrics()
+	snapshot := recorder.Snapshot(req.DatabaseIds...)
+
+	metrics := make([]*pb.AdapterOperationMetrics, 0, len(snapshot))
+	for _, m := range snapshot {
+		metrics = append(metrics, adapterMetricsToProto(m))
+	}
+
+	captures := recorder.QueryPlans(req.DatabaseIds...)
+	plans := make([]*pb.AdapterQueryPlan, 0, len(captures))
+	for _, c := range captures {
+		plans = append(plans, queryPlanToProto(c))
+	}
+
+	bounds := adapter.LatencyBuckets()
+	boundsMs := make([]float64, len(bounds))
+	for i, b := range bounds {
+		boundsMs[i] = durationMs(b)
+	}
+
+	return &pb.GetAdapterMetricsResponse{
+		Message:               "Adapter metrics retrieved successfully",
+		Success:               true,
+		Status:                commonv1.Status_STATUS_SUCCESS,
+		Metrics:               metrics,
+		LatencyBucketBoundsMs: boundsMs,
+		QueryPlans:            plans,
+	}, nil
+}
+
+func adapterMetricsToProto(m adapter.OperationMetrics) *pb.AdapterOperationMetrics {
+	out := &pb.AdapterOperationMetrics{
+		DatabaseType:     string(m.DatabaseType),
+		DatabaseId:       m.DatabaseID,
+		Operation:        m.Operation,
+		Count:            m.Count,
+		Errors:           m.Errors,
+		AvgLatencyMs:     durationMs(m.AverageDuration()),
+		MinLatencyMs:     durationMs(m.MinDuration),
+		MaxLatencyMs:     durationMs(m.MaxDuration),
+		P50LatencyMs:     durationMs(m.Percentile(0.50)),
+		P95LatencyMs:     durationMs(m.Percentile(0.95)),
+		P99LatencyMs:     durationMs(m.Percentile(0.99)),
+		LastLatencyMs:    durationMs(m.LastDuration),
+		LastError:        m.LastError,
+		LatencyHistogram: m.Buckets,
+	}
+	if !m.LastCalledAt.IsZero() {
+		out.LastCalledAt = m.LastCalledAt.Format(time.RFC3339)
+	}
+	if !m.LastErrorAt.IsZero() {
+		out.LastErrorAt = m.LastErrorAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+func queryPlanToProto(c adapter.QueryPlanCapture) *pb.AdapterQueryPlan {
+	out := &pb.AdapterQueryPlan{
+		DatabaseType: string(c.DatabaseType),
+		DatabaseId:   c.DatabaseID,
+		Operation:    c.Operation,
+		TableName:    c.Table,
+		Limit:        int32(c.Limit),
+		DurationMs:   durationMs(c.Duration),
+		CapturedAt:   c.CapturedAt.Format(time.RFC3339),
+		Error:        c.Error,
+	}
+	if c.Plan != nil {
+		out.Query = c.Plan.Query
+		out.PlanFormat = c.Plan.Format
+		out.Plan = c.Plan.Plan
+		out.Analyzed = c.Plan.Analyzed
+	}
+	return out
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
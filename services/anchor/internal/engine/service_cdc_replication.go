@@ -13,6 +13,7 @@ import (
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/services/anchor/internal/stream/schemaregistry"
 )
 
 // CDCReplicationManager manages active CDC replication streams (database-agnostic version)
@@ -27,9 +28,12 @@ type CDCReplicationStream struct {
 	RelationshipID      string
 	SourceDatabaseID    string
 	TargetDatabaseID    string
+	TargetStreamID      string // Set when events are produced to a topic instead of a database
+	TargetTopicName     string
 	TableNames          []string
 	MappingRules        []byte
 	EventRouter         *CDCEventRouter
+	StreamPublisher     *CDCStreamPublisher
 	ReplicationSource   adapter.ReplicationSource
 	StopChan            chan struct{}
 	Status              string
@@ -53,6 +57,17 @@ func getCDCManager() *CDCReplicationManager {
 	return cdcManager
 }
 
+// statistics returns the statistics of the event router or stream publisher of the stream
+func (s *CDCReplicationStream) statistics() *adapter.CDCStatistics {
+	switch {
+	case s.EventRouter != nil:
+		return s.EventRouter.GetStatistics()
+	case s.StreamPublisher != nil:
+		return s.StreamPublisher.GetStatistics()
+	}
+	return nil
+}
+
 // StartCDCReplication starts CDC replication for a relationship (database-agnostic version)
 func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDCReplicationRequest) (*anchorv1.StartCDCReplicationResponse, error) {
 	e.logger.Info("Starting CDC replication for relationship %s", req.RelationshipId)
@@ -62,17 +77,12 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 		return nil, status.Errorf(codes.Internal, "connection registry not available")
 	}
 
-	// Step 1: Get source and target adapter connections
+	// Step 1: Get source adapter connection
 	sourceConn, err := registry.GetAdapterConnection(req.SourceDatabaseId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "source database not found: %v", err)
 	}
 
-	targetConn, err := registry.GetAdapterConnection(req.TargetDatabaseId)
-	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "target database not found: %v", err)
-	}
-
 	// Step 2: Check if source database supports CDC
 	sourceRepOps := sourceConn.ReplicationOperations()
 	if !sourceRepOps.IsSupported() {
@@ -81,25 +91,60 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 			sourceConn.Type())
 	}
 
-	// Step 3: Check if target database can receive CDC events
-	targetRepOps := targetConn.ReplicationOperations()
-	if !targetRepOps.IsSupported() {
-		return nil, status.Errorf(codes.InvalidArgument,
-			"target database type %s does not support CDC replication",
-			targetConn.Type())
-	}
+	// Steps 3 and 4: Create the handler of the events, producing them to a topic of a stream
+	// or applying them to a target database
+	transformationServiceEndpoint := e.getServiceAddress("transformation")
+	var eventRouter *CDCEventRouter
+	var streamPublisher *CDCStreamPublisher
+	var eventHandler func(map[string]interface{}) error
+	targetType := ""
+	if req.TargetStreamId != "" {
+		if req.TargetTopicName == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "a target topic is required to replicate to stream %s", req.TargetStreamId)
+		}
+		if e.streams == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "streams are not available")
+		}
+		streamConn, err := e.streams.Get(req.TargetStreamId)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "target stream not found: %v", err)
+		}
+		registryConfig, err := schemaregistry.ConfigFromMap(streamConn.Config().Configuration)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid schema registry configuration of stream %s: %v", req.TargetStreamId, err)
+		}
+		streamPublisher, err = NewCDCStreamPublisher(sourceConn, streamConn.ProducerOperations(), req.TargetStreamId, req.TargetTopicName, registryConfig, req.MappingRules, req.RowFilter, transformationServiceEndpoint, e.logger)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create stream publisher: %v", err)
+		}
+		eventHandler = streamPublisher.CreateEventHandler()
+		targetType = string(streamConn.Type())
+	} else {
+		targetConn, err := registry.GetAdapterConnection(req.TargetDatabaseId)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "target database not found: %v", err)
+		}
 
-	e.logger.Info("CDC support verified: source=%s, target=%s",
-		sourceConn.Type(), targetConn.Type())
+		// Check if target database can receive CDC events
+		targetRepOps := targetConn.ReplicationOperations()
+		if !targetRepOps.IsSupported() {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"target database type %s does not support CDC replication",
+				targetConn.Type())
+		}
 
-	// Step 4: Create CDC event router for transforming and routing events
-	// Get transformation service endpoint for custom transformations
-	transformationServiceEndpoint := e.getServiceAddress("transformation")
-	eventRouter, err := NewCDCEventRouter(sourceConn, targetConn, req.MappingRules, transformationServiceEndpoint, e.logger)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create event router: %v", err)
+		// Create CDC event router for transforming and routing events
+		eventRouter, err = NewCDCEventRouter(sourceConn, targetConn, req.MappingRules, req.ConflictKeys, req.RowFilter, transformationServiceEndpoint, e.logger)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create event router: %v", err)
+		}
+		eventHandler = eventRouter.CreateEventHandler()
+		targetType = string(targetConn.Type())
 	}
 
+	e.logger.Info("CDC support verified: source=%s, target=%s",
+		sourceConn.Type(), targetType)
+
 	// Step 5: Build replication configuration
 	replicationConfig := adapter.ReplicationConfig{
 		ReplicationID:   req.ReplicationSourceId,
@@ -120,7 +165,7 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 		SSLKey:          getStringValue(sourceConn.Config().SSLKey),
 		SSLRootCert:     getStringValue(sourceConn.Config().SSLRootCert),
 		TableNames:      req.TableNames,
-		EventHandler:    wrapEventHandler(eventRouter.CreateEventHandler()),
+		EventHandler:    wrapEventHandler(eventHandler),
 	}
 
 	// Step 6: Extract database-specific parameters from node_id if provided
@@ -183,7 +228,11 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 		"started_at":         time.Now().Format(time.RFC3339),
 		"table_names":        fmt.Sprintf("%v", req.TableNames),
 		"source_type":        string(sourceConn.Type()),
-		"target_type":        string(targetConn.Type()),
+		"target_type":        targetType,
+	}
+	if req.TargetStreamId != "" {
+		cdcDetails["target_stream_id"] = req.TargetStreamId
+		cdcDetails["target_topic_name"] = req.TargetTopicName
 	}
 
 	// Add database-specific metadata
@@ -200,9 +249,12 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 		RelationshipID:      req.RelationshipId,
 		SourceDatabaseID:    req.SourceDatabaseId,
 		TargetDatabaseID:    req.TargetDatabaseId,
+		TargetStreamID:      req.TargetStreamId,
+		TargetTopicName:     req.TargetTopicName,
 		TableNames:          req.TableNames,
 		MappingRules:        req.MappingRules,
 		EventRouter:         eventRouter,
+		StreamPublisher:     streamPublisher,
 		ReplicationSource:   replicationSource,
 		StopChan:            make(chan struct{}),
 		Status:              "active",
@@ -215,7 +267,7 @@ func (e *Engine) StartCDCReplication(ctx context.Context, req *anchorv1.StartCDC
 	manager.mu.Unlock()
 
 	e.logger.Info("CDC replication started successfully for relationship %s (source: %s -> target: %s)",
-		req.RelationshipId, sourceConn.Type(), targetConn.Type())
+		req.RelationshipId, sourceConn.Type(), targetType)
 
 	return &anchorv1.StartCDCReplicationResponse{
 		Message:             "CDC replication started successfully",
@@ -255,6 +307,13 @@ func (e *Engine) StopCDCReplication(ctx context.Context, req *anchorv1.StopCDCRe
 		}
 	}
 
+	// Wait for the events being produced to the target stream
+	if stream.StreamPublisher != nil {
+		if err := stream.StreamPublisher.Close(); err != nil {
+			e.logger.Warnf("Error flushing stream publisher: %v", err)
+		}
+	}
+
 	// Signal stop
 	close(stream.StopChan)
 
@@ -267,8 +326,7 @@ func (e *Engine) StopCDCReplication(ctx context.Context, req *anchorv1.StopCDCRe
 		preservedState["last_event_timestamp"] = stream.LastEventTimestamp.Format(time.RFC3339)
 
 		// Add statistics from event router
-		if stream.EventRouter != nil {
-			stats := stream.EventRouter.GetStatistics()
+		if stats := stream.statistics(); stats != nil {
 			preservedState["events_failed"] = fmt.Sprintf("%d", stats.EventsFailed)
 			preservedState["average_latency"] = stats.AverageLatency.String()
 		}
@@ -360,8 +418,7 @@ func (e *Engine) GetCDCReplicationStatus(ctx context.Context, req *anchorv1.GetC
 	var eventsFailed int64
 	cdcPosition := make(map[string]string)
 
-	if stream.EventRouter != nil {
-		stats := stream.EventRouter.GetStatistics()
+	if stats := stream.statistics(); stats != nil {
 		eventsProcessed = stats.EventsProcessed
 		eventsFailed = stats.EventsFailed
 		cdcPosition["last_event_timestamp"] = stats.LastEventTimestamp.Format(time.RFC3339)
@@ -431,6 +488,7 @@ func (e *Engine) parseReplicationParameters(nodeID *string, config *adapter.Repl
 	// NodeId format examples:
 	// - PostgreSQL: "slot:<slotname>:pub:<pubname>"
 	// - MySQL: "server_id:<id>:log_file:<file>:log_pos:<pos>"
+	// - Cassandra: "cdc_raw_directory:<path>"
 	parts := strings.Split(*nodeID, ":")
 
 	for i := 0; i < len(parts)-1; i += 2 {
@@ -457,6 +515,11 @@ func (e *Engine) parseReplicationParameters(nodeID *string, config *adapter.Repl
 				config.Options = make(map[string]interface{})
 			}
 			config.Options["log_position"] = value
+		case "cdc_raw_directory":
+			if config.Options == nil {
+				config.Options = make(map[string]interface{})
+			}
+			config.Options["cdc_raw_directory"] = value
 		}
 	}
 }
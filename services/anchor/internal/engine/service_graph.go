@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// SampleEdges returns relationships of a type of a graph database as the nodes they connect
+func (s *Server) SampleEdges(ctx context.Context, req *pb.SampleEdgesRequest) (*pb.SampleEdgesResponse, error) {
+	defer s.trackOperation()()
+
+	var edges []adapter.Edge
+	sampler, err := s.edgeSampler(req.DatabaseId)
+	if err == nil {
+		err = adapter.ValidateEdgeSample(req.Relationship, int(req.Limit))
+	}
+	if err == nil {
+		edges, err = sampler.SampleEdges(ctx, req.Relationship, int(req.Limit))
+	}
+	if err != nil {
+		return &pb.SampleEdgesResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to sample edges of %s: %v", req.Relationship, err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+
+	result := make([]*pb.GraphEdge, len(edges))
+	for i, edge := range edges {
+		result[i] = &pb.GraphEdge{From: edge.From, To: edge.To}
+	}
+	return &pb.SampleEdgesResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Sampled %d edges of %s", len(result), req.Relationship),
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		DatabaseId: req.DatabaseId,
+		Edges:      result,
+	}, nil
+}
+
+// edgeSampler returns the edge sampler of the connection of a database, or an error if the
+// database is not connected or its adapter cannot sample edges
+func (s *Server) edgeSampler(databaseID string) (adapter.EdgeSampler, error) {
+	registry := s.engine.GetState().GetConnectionRegistry()
+	client, err := registry.GetDatabaseClient(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("database connection not found for ID: %s", databaseID)
+	}
+
+	conn := client.AdapterConnection.(adapter.Connection)
+	sampler, ok := adapter.AsEdgeSampler(conn.DataOperations())
+	if !ok {
+		return nil, adapter.NewUnsupportedOperationError(conn.Type(), "edge sample", "the adapter cannot read relationships as edges")
+	}
+	return sampler, nil
+}
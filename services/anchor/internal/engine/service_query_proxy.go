@@ -0,0 +1,250 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/encryption"
+	"github.com/redbco/redb-open/pkg/tlsconfig"
+	internalconfig "github.com/redbco/redb-open/services/anchor/internal/config"
+	"github.com/redbco/redb-open/services/anchor/internal/proxy"
+)
+
+// StartQueryProxy starts a query proxy with its stored configuration, or applies its stored
+// routes if it is running
+func (s *Server) StartQueryProxy(ctx context.Context, req *pb.StartQueryProxyRequest) (*pb.StartQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	p, err := s.engine.applyQueryProxy(ctx, req.ProxyId)
+	if err != nil {
+		return &pb.StartQueryProxyResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to start query proxy %s: %v", req.ProxyId, err),
+			Status:  commonv1.Status_STATUS_ERROR,
+			ProxyId: req.ProxyId,
+		}, nil
+	}
+
+	return &pb.StartQueryProxyResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Query proxy %s listening on %s", req.ProxyId, p.Addr()),
+		Status:        commonv1.Status_STATUS_SUCCESS,
+		ProxyId:       req.ProxyId,
+		ListenAddress: p.Addr().String(),
+	}, nil
+}
+
+// StopQueryProxy stops a query proxy, closing its sessions
+func (s *Server) StopQueryProxy(ctx context.Context, req *pb.StopQueryProxyRequest) (*pb.StopQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	message := fmt.Sprintf("Query proxy %s stopped", req.ProxyId)
+	if s.engine.proxies == nil || !s.engine.proxies.Stop(req.ProxyId) {
+		message = fmt.Sprintf("Query proxy %s is not running", req.ProxyId)
+	}
+
+	return &pb.StopQueryProxyResponse{
+		Success: true,
+		Message: message,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+		ProxyId: req.ProxyId,
+	}, nil
+}
+
+// GetQueryProxyStatus returns the routes and activity of a query proxy
+func (s *Server) GetQueryProxyStatus(ctx context.Context, req *pb.GetQueryProxyStatusRequest) (*pb.GetQueryProxyStatusResponse, error) {
+	defer s.trackOperation()()
+
+	var p *proxy.Proxy
+	running := false
+	if s.engine.proxies != nil {
+		p, running = s.engine.proxies.Get(req.ProxyId)
+	}
+	if !running {
+		return &pb.GetQueryProxyStatusResponse{
+			Success: true,
+			Message: fmt.Sprintf("Query proxy %s is not running", req.ProxyId),
+			Status:  commonv1.Status_STATUS_SUCCESS,
+			ProxyId: req.ProxyId,
+		}, nil
+	}
+
+	routes := p.Routes()
+	tableRoutes := make(map[string]string, len(routes.Tables))
+	for table, db := range routes.Tables {
+		tableRoutes[table] = string(db)
+	}
+	stats := p.Stats()
+
+	return &pb.GetQueryProxyStatusResponse{
+		Success:          true,
+		Message:          fmt.Sprintf("Query proxy %s is running", req.ProxyId),
+		Status:           commonv1.Status_STATUS_SUCCESS,
+		ProxyId:          req.ProxyId,
+		Running:          true,
+		ListenAddress:    p.Addr().String(),
+		ReadPercent:      int32(routes.ReadPercent),
+		WritePercent:     int32(routes.WritePercent),
+		TableRoutes:      tableRoutes,
+		ActiveSessions:   stats.ActiveSessions,
+		TotalSessions:    stats.TotalSessions,
+		SourceStatements: stats.SourceStatements,
+		TargetStatements: stats.TargetStatements,
+		StartedAt:        stats.Started.Format(time.RFC3339),
+	}, nil
+}
+
+// applyQueryProxy loads the configuration of a query proxy and starts it, or applies its routes
+// if it is running
+func (e *Engine) applyQueryProxy(ctx context.Context, proxyID string) (*proxy.Proxy, error) {
+	configRepo := e.GetState().GetConfigRepository()
+	if configRepo == nil || e.proxies == nil {
+		return nil, fmt.Errorf("query proxies are not available in standalone mode")
+	}
+
+	stored, err := configRepo.GetQueryProxy(ctx, proxyID)
+	if err != nil {
+		return nil, err
+	}
+	config, err := e.queryProxyConfig(stored)
+	if err != nil {
+		return nil, err
+	}
+	return e.proxies.Apply(config)
+}
+
+// startQueryProxies starts the query proxies of the node that were running when the anchor
+// stopped. Proxies that fail to start are marked with an error.
+func (e *Engine) startQueryProxies(ctx context.Context) {
+	configRepo := e.GetState().GetConfigRepository()
+	stored, err := configRepo.GetStartedQueryProxies(ctx, e.nodeID)
+	if err != nil {
+		if e.logger != nil {
+			e.logger.Error("Failed to load query proxies: %v", err)
+		}
+		return
+	}
+
+	for _, qp := range stored {
+		config, err := e.queryProxyConfig(qp)
+		if err == nil {
+			_, err = e.proxies.Apply(config)
+		}
+		if err != nil {
+			if e.logger != nil {
+				e.logger.Error("Failed to start query proxy %s: %v", qp.ProxyName, err)
+			}
+			if err := configRepo.UpdateQueryProxyStatus(ctx, qp.ProxyID, "STATUS_ERROR", truncateStatusMessage(err.Error())); err != nil && e.logger != nil {
+				e.logger.Error("Failed to update status of query proxy %s: %v", qp.ProxyName, err)
+			}
+		}
+	}
+}
+
+// queryProxyConfig builds the configuration of a proxy from its stored configuration and the
+// connections of its databases
+func (e *Engine) queryProxyConfig(qp *internalconfig.QueryProxy) (proxy.Config, error) {
+	password, err := encryption.DecryptPassword(qp.TenantID, qp.Password)
+	if err != nil {
+		return proxy.Config{}, fmt.Errorf("error decrypting proxy password: %v", err)
+	}
+	source, err := e.queryProxyBackend(qp.SourceDatabaseID)
+	if err != nil {
+		return proxy.Config{}, err
+	}
+	target, err := e.queryProxyBackend(qp.TargetDatabaseID)
+	if err != nil {
+		return proxy.Config{}, err
+	}
+
+	routes := proxy.Routes{
+		ReadPercent:  qp.ReadPercent,
+		WritePercent: qp.WritePercent,
+		Tables:       make(map[string]proxy.Database, len(qp.TableRoutes)),
+	}
+	for table, db := range qp.TableRoutes {
+		routes.Tables[table] = proxy.Database(db)
+	}
+
+	// The proxy listens on all interfaces unless services.anchor.proxy_host restricts it
+	host := ""
+	if e.config != nil {
+		host = e.config.Get("services.anchor.proxy_host")
+	}
+
+	return proxy.Config{
+		ID:            qp.ProxyID,
+		Name:          qp.ProxyName,
+		Protocol:      proxy.Protocol(qp.Protocol),
+		ListenAddress: net.JoinHostPort(host, strconv.Itoa(qp.ListenPort)),
+		Username:      qp.Username,
+		Password:      password,
+		Source:        source,
+		Target:        target,
+		Routes:        routes,
+	}, nil
+}
+
+// queryProxyBackend returns the connection info of a database connected by the anchor
+func (e *Engine) queryProxyBackend(databaseID string) (proxy.Backend, error) {
+	client, err := e.GetState().GetConnectionRegistry().GetDatabaseClient(databaseID)
+	if err != nil {
+		return proxy.Backend{}, fmt.Errorf("database connection not found for ID: %s", databaseID)
+	}
+	config := client.Config
+	if config.SSHTunnel != nil {
+		return proxy.Backend{}, fmt.Errorf("database %s is reached through an SSH tunnel, which query proxies do not support", config.Name)
+	}
+	if config.SocketPath != "" {
+		return proxy.Backend{}, fmt.Errorf("database %s is connected through a local socket, which query proxies do not support", config.Name)
+	}
+	if config.KerberosAuth != nil {
+		return proxy.Backend{}, fmt.Errorf("database %s authenticates with kerberos, which query proxies do not support", config.Name)
+	}
+
+	password := ""
+	if config.Password != "" {
+		password, err = encryption.DecryptPassword(config.TenantID, config.Password)
+		if err != nil {
+			return proxy.Backend{}, fmt.Errorf("error decrypting password of database %s: %v", config.Name, err)
+		}
+	}
+
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return proxy.Backend{}, err
+	}
+	if tlsConfig == nil && config.SSL {
+		tlsConfig, err = tlsconfig.Build(tlsconfig.Options{
+			ServerName: config.Host,
+			VerifyMode: tlsconfig.FromSSLMode(config.SSLMode, config.SSLRejectUnauthorized, false),
+		})
+		if err != nil {
+			return proxy.Backend{}, err
+		}
+	}
+
+	return proxy.Backend{
+		DatabaseID:   databaseID,
+		Host:         config.Host,
+		Port:         config.Port,
+		Username:     config.Username,
+		Password:     password,
+		DatabaseName: config.DatabaseName,
+		TLS:          tlsConfig,
+		AuthToken:    config.AuthToken,
+	}, nil
+}
+
+// truncateStatusMessage fits a message into the status_message column
+func truncateStatusMessage(message string) string {
+	if len(message) > 255 {
+		return message[:255]
+	}
+	return message
+}
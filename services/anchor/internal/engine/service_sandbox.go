@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// CreateSandbox creates a sandbox holding empty copies of tables of a database, which
+// InsertBatchData and FetchData write and read when given the sandbox
+func (s *Server) CreateSandbox(ctx context.Context, req *pb.CreateSandboxRequest) (*pb.CreateSandboxResponse, error) {
+	defer s.trackOperation()()
+
+	sandboxer, err := s.sandboxer(req.DatabaseId)
+	if err == nil {
+		err = sandboxer.CreateSandbox(ctx, req.Sandbox, req.Tables)
+	}
+	if err != nil {
+		return &pb.CreateSandboxResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to create sandbox %s: %v", req.Sandbox, err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+			Sandbox:    req.Sandbox,
+		}, nil
+	}
+
+	return &pb.CreateSandboxResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Sandbox %s created with %d tables", req.Sandbox, len(req.Tables)),
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		DatabaseId: req.DatabaseId,
+		Sandbox:    req.Sandbox,
+	}, nil
+}
+
+// DropSandbox drops a sandbox and the tables in it
+func (s *Server) DropSandbox(ctx context.Context, req *pb.DropSandboxRequest) (*pb.DropSandboxResponse, error) {
+	defer s.trackOperation()()
+
+	sandboxer, err := s.sandboxer(req.DatabaseId)
+	if err == nil {
+		err = sandboxer.DropSandbox(ctx, req.Sandbox)
+	}
+	if err != nil {
+		return &pb.DropSandboxResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to drop sandbox %s: %v", req.Sandbox, err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+			Sandbox:    req.Sandbox,
+		}, nil
+	}
+
+	return &pb.DropSandboxResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Sandbox %s dropped", req.Sandbox),
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		DatabaseId: req.DatabaseId,
+		Sandbox:    req.Sandbox,
+	}, nil
+}
+
+// sandboxer returns the sandboxer of the connection of a database, or an error if the database
+// is not connected or its adapter has no sandboxes
+func (s *Server) sandboxer(databaseID string) (adapter.Sandboxer, error) {
+	registry := s.engine.GetState().GetConnectionRegistry()
+	client, err := registry.GetDatabaseClient(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("database connection not found for ID: %s", databaseID)
+	}
+
+	conn := client.AdapterConnection.(adapter.Connection)
+	sandboxer, ok := adapter.AsSandboxer(conn.DataOperations())
+	if !ok {
+		return nil, adapter.NewUnsupportedOperationError(conn.Type(), "sandbox", "the adapter cannot write into temporary copies of tables")
+	}
+	return sandboxer, nil
+}
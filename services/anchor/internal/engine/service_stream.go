@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	internalconfig "github.com/redbco/redb-open/services/anchor/internal/config"
+	internalstream "github.com/redbco/redb-open/services/anchor/internal/stream"
+)
+
+// ConnectStream attaches a stream platform with its stored configuration, discovers its topics
+// into the metadata of the stream and marks it connected
+func (s *Server) ConnectStream(ctx context.Context, req *pb.ConnectStreamRequest) (*pb.ConnectStreamResponse, error) {
+	defer s.trackOperation()()
+
+	topicCount, err := s.engine.connectStream(ctx, req.StreamId)
+	if err != nil {
+		return &pb.ConnectStreamResponse{
+			Success:  false,
+			Message:  fmt.Sprintf("Failed to connect stream %s: %v", req.StreamId, err),
+			Status:   commonv1.Status_STATUS_ERROR,
+			StreamId: req.StreamId,
+		}, nil
+	}
+
+	return &pb.ConnectStreamResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Stream %s connected with %d topics", req.StreamId, topicCount),
+		Status:     commonv1.Status_STATUS_CONNECTED,
+		StreamId:   req.StreamId,
+		TopicCount: int32(topicCount),
+	}, nil
+}
+
+// DisconnectStream closes the connection to a stream platform and marks it disconnected
+func (s *Server) DisconnectStream(ctx context.Context, req *pb.DisconnectStreamRequest) (*pb.DisconnectStreamResponse, error) {
+	defer s.trackOperation()()
+
+	message := fmt.Sprintf("Stream %s disconnected", req.StreamId)
+	if s.engine.streams == nil || !s.engine.streams.Disconnect(req.StreamId) {
+		message = fmt.Sprintf("Stream %s is not connected", req.StreamId)
+	}
+	if configRepo := s.engine.GetState().GetConfigRepository(); configRepo != nil {
+		if err := configRepo.UpdateStreamConnectionStatus(ctx, req.StreamId, false); err != nil {
+			return &pb.DisconnectStreamResponse{
+				Success:  false,
+				Message:  fmt.Sprintf("Failed to update status of stream %s: %v", req.StreamId, err),
+				Status:   commonv1.Status_STATUS_ERROR,
+				StreamId: req.StreamId,
+			}, nil
+		}
+	}
+
+	return &pb.DisconnectStreamResponse{
+		Success:  true,
+		Message:  message,
+		Status:   commonv1.Status_STATUS_DISCONNECTED,
+		StreamId: req.StreamId,
+	}, nil
+}
+
+// GetStreamSchema discovers the topics of a connected stream platform into a unified model
+func (s *Server) GetStreamSchema(ctx context.Context, req *pb.GetStreamSchemaRequest) (*pb.GetStreamSchemaResponse, error) {
+	defer s.trackOperation()()
+
+	if s.engine.streams == nil {
+		return &pb.GetStreamSchemaResponse{
+			Success:  false,
+			Message:  "Streams are not available",
+			Status:   commonv1.Status_STATUS_ERROR,
+			StreamId: req.StreamId,
+		}, nil
+	}
+	conn, err := s.engine.streams.Get(req.StreamId)
+	if err != nil {
+		return &pb.GetStreamSchemaResponse{
+			Success:  false,
+			Message:  fmt.Sprintf("Stream not found: %v", err),
+			Status:   commonv1.Status_STATUS_ERROR,
+			StreamId: req.StreamId,
+		}, nil
+	}
+
+	um, err := internalstream.DiscoverSchema(ctx, conn)
+	if err != nil {
+		return &pb.GetStreamSchemaResponse{
+			Success:  false,
+			Message:  fmt.Sprintf("Failed to discover topics: %v", err),
+			Status:   commonv1.Status_STATUS_ERROR,
+			StreamId: req.StreamId,
+		}, nil
+	}
+	schema, err := json.Marshal(um)
+	if err != nil {
+		return &pb.GetStreamSchemaResponse{
+			Success:  false,
+			Message:  fmt.Sprintf("Failed to marshal schema data: %v", err),
+			Status:   commonv1.Status_STATUS_ERROR,
+			StreamId: req.StreamId,
+		}, nil
+	}
+
+	return &pb.GetStreamSchemaResponse{
+		Success:  true,
+		Message:  "Schema retrieved successfully",
+		Status:   commonv1.Status_STATUS_SUCCESS,
+		StreamId: req.StreamId,
+		Schema:   schema,
+	}, nil
+}
+
+// connectStream loads the configuration of a stream, connects to its platform and records
+// its topics, returning how many were discovered
+func (e *Engine) connectStream(ctx context.Context, streamID string) (int, error) {
+	configRepo := e.GetState().GetConfigRepository()
+	if configRepo == nil || e.streams == nil {
+		return 0, fmt.Errorf("streams are not available in standalone mode")
+	}
+
+	stored, err := configRepo.GetStreamConfigByID(ctx, streamID)
+	if err != nil {
+		return 0, err
+	}
+	topicCount, err := e.attachStream(ctx, configRepo, stored)
+	if err != nil {
+		if statusErr := configRepo.UpdateStreamConnectionStatus(ctx, streamID, false); statusErr != nil && e.logger != nil {
+			e.logger.Warnf("Failed to update status of stream %s: %v", stored.Name, statusErr)
+		}
+		return 0, err
+	}
+	return topicCount, nil
+}
+
+// connectStreams attaches the stream platforms of the node that were connected when the
+// anchor stopped
+func (e *Engine) connectStreams(ctx context.Context) {
+	configRepo := e.GetState().GetConfigRepository()
+	stored, err := configRepo.GetConnectedStreamConfigs(ctx, e.nodeID)
+	if err != nil {
+		if e.logger != nil {
+			e.logger.Errorf("Failed to load streams: %v", err)
+		}
+		return
+	}
+
+	for _, st := range stored {
+		if _, err := e.attachStream(ctx, configRepo, st); err != nil && e.logger != nil {
+			e.logger.Errorf("Failed to connect stream %s: %v", st.Name, err)
+		}
+	}
+}
+
+func (e *Engine) attachStream(ctx context.Context, configRepo *internalconfig.Repository, stored *internalconfig.StreamConfig) (int, error) {
+	conn, err := e.streams.Connect(ctx, *stored.ToConnectionConfig())
+	if err != nil {
+		return 0, err
+	}
+
+	um, err := internalstream.DiscoverSchema(ctx, conn)
+	if err != nil {
+		e.streams.Disconnect(stored.StreamID)
+		return 0, err
+	}
+	metadata := internalstream.TopicSummary(um)
+	metadata["discovered_at"] = time.Now().UTC().Format(time.RFC3339)
+	if err := configRepo.UpdateStreamMetadata(ctx, stored.StreamID, metadata); err != nil {
+		e.streams.Disconnect(stored.StreamID)
+		return 0, err
+	}
+	if err := configRepo.UpdateStreamConnectionStatus(ctx, stored.StreamID, true); err != nil {
+		e.streams.Disconnect(stored.StreamID)
+		return 0, err
+	}
+
+	if e.logger != nil {
+		e.logger.Infof("Connected stream %s (%s) with %d topics", stored.Name, stored.Platform, len(um.Streams))
+	}
+	return len(um.Streams), nil
+}
@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// SearchVectors returns the nearest neighbors of a vector in a collection of a vector store
+func (s *Server) SearchVectors(ctx context.Context, req *pb.SearchVectorsRequest) (*pb.SearchVectorsResponse, error) {
+	defer s.trackOperation()()
+
+	var matches []adapter.VectorMatch
+	searcher, err := s.vectorSearcher(req.DatabaseId)
+	if err == nil {
+		err = adapter.ValidateVectorSearch(req.Vector, int(req.TopK))
+	}
+	if err == nil {
+		matches, err = searcher.SearchVectors(ctx, req.TableName, req.Vector, int(req.TopK))
+	}
+	if err != nil {
+		return &pb.SearchVectorsResponse{
+			Success:    false,
+			Message:    fmt.Sprintf("Failed to search vectors in %s: %v", req.TableName, err),
+			Status:     commonv1.Status_STATUS_ERROR,
+			DatabaseId: req.DatabaseId,
+		}, nil
+	}
+
+	result := make([]*pb.VectorMatch, len(matches))
+	for i, match := range matches {
+		result[i] = &pb.VectorMatch{Id: match.ID, Score: match.Score}
+	}
+	return &pb.SearchVectorsResponse{
+		Success:    true,
+		Message:    fmt.Sprintf("Found %d neighbors in %s", len(result), req.TableName),
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		DatabaseId: req.DatabaseId,
+		Matches:    result,
+	}, nil
+}
+
+// vectorSearcher returns the vector searcher of the connection of a database, or an error if
+// the database is not connected or its adapter cannot search vectors
+func (s *Server) vectorSearcher(databaseID string) (adapter.VectorSearcher, error) {
+	registry := s.engine.GetState().GetConnectionRegistry()
+	client, err := registry.GetDatabaseClient(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("database connection not found for ID: %s", databaseID)
+	}
+
+	conn := client.AdapterConnection.(adapter.Connection)
+	searcher, ok := adapter.AsVectorSearcher(conn.DataOperations())
+	if !ok {
+		return nil, adapter.NewUnsupportedOperationError(conn.Type(), "vector search", "the adapter cannot search the nearest neighbors of a vector")
+	}
+	return searcher, nil
+}
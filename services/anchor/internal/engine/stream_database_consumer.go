@@ -11,8 +11,8 @@ import (
 	streamv1 "github.com/redbco/redb-open/api/proto/stream/v1"
 	"github.com/redbco/redb-open/pkg/anchor/adapter"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // StreamDatabaseConsumer consumes messages from streams and writes them to databases
@@ -52,7 +52,7 @@ func NewStreamDatabaseConsumer(
 	logger *logger.Logger,
 ) (*StreamDatabaseConsumer, error) {
 	// Connect to stream service
-	conn, err := grpc.Dial(streamServiceEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(streamServiceEndpoint, spiffe.DialOption())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to stream service: %w", err)
 	}
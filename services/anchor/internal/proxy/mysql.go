@@ -0,0 +1,1266 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Capability flags of the MySQL protocol
+const (
+	myLongPassword     = 0x00000001
+	myFoundRows        = 0x00000002
+	myLongFlag         = 0x00000004
+	myConnectWithDB    = 0x00000008
+	myIgnoreSpace      = 0x00000100
+	myProtocol41       = 0x00000200
+	myInteractive      = 0x00000400
+	mySSL              = 0x00000800
+	myTransactions     = 0x00002000
+	mySecureConnection = 0x00008000
+	myMultiStatements  = 0x00010000
+	myMultiResults     = 0x00020000
+	myPSMultiResults   = 0x00040000
+	myPluginAuth       = 0x00080000
+	myPluginAuthLenenc = 0x00200000
+
+	// Capabilities the proxy offers to applications. TLS, compression, LOAD DATA LOCAL and
+	// responses without EOF packets are not supported.
+	myProxyCapabilities uint32 = myLongPassword | myFoundRows | myLongFlag | myConnectWithDB | myIgnoreSpace |
+		myProtocol41 | myInteractive | myTransactions | mySecureConnection | myMultiStatements |
+		myMultiResults | myPSMultiResults | myPluginAuth | myPluginAuthLenenc
+)
+
+// Commands of the MySQL protocol
+const (
+	comQuit             = 0x01
+	comInitDB           = 0x02
+	comQuery            = 0x03
+	comFieldList        = 0x04
+	comStatistics       = 0x09
+	comPing             = 0x0e
+	comStmtPrepare      = 0x16
+	comStmtExecute      = 0x17
+	comStmtSendLongData = 0x18
+	comStmtClose        = 0x19
+	comStmtReset        = 0x1a
+	comSetOption        = 0x1b
+	comStmtFetch        = 0x1c
+	comResetConnection  = 0x1f
+)
+
+// Server status flags of the MySQL protocol
+const (
+	myStatusInTrans      = 0x0001
+	myStatusAutocommit   = 0x0002
+	myStatusMoreResults  = 0x0008
+	myStatusCursorExists = 0x0040
+)
+
+const (
+	myMaxPacket         = 0xffffff
+	myNativePassword    = "mysql_native_password"
+	myCachingSHA2       = "caching_sha2_password"
+	myClearPassword     = "mysql_clear_password"
+	myDefaultVersion    = "8.0.0"
+	myCharsetUTF8MB4    = 45
+	myErrUnknownCommand = 1047
+)
+
+// myConn reads and writes the packets of a MySQL connection
+type myConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+	seq  byte
+}
+
+func newMyConn(conn net.Conn) *myConn {
+	return &myConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+}
+
+// read reads a packet, joining the packets a payload of 16MB or more is split into
+func (c *myConn) read() ([]byte, error) {
+	var payload []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(c.r, header[:]); err != nil {
+			return nil, err
+		}
+		n := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+		c.seq = header[3] + 1
+		start := len(payload)
+		payload = append(payload, make([]byte, n)...)
+		if _, err := io.ReadFull(c.r, payload[start:]); err != nil {
+			return nil, err
+		}
+		if n < myMaxPacket {
+			if len(payload) == 0 {
+				return nil, fmt.Errorf("empty packet")
+			}
+			return payload, nil
+		}
+	}
+}
+
+// write writes a packet with the next sequence number, without flushing it
+func (c *myConn) write(payload []byte) error {
+	for {
+		n := min(len(payload), myMaxPacket)
+		header := [4]byte{byte(n), byte(n >> 8), byte(n >> 16), c.seq}
+		c.seq++
+		if _, err := c.w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := c.w.Write(payload[:n]); err != nil {
+			return err
+		}
+		payload = payload[n:]
+		if n < myMaxPacket {
+			return nil
+		}
+	}
+}
+
+// command sends a command packet, which starts a new sequence
+func (c *myConn) command(payload []byte) error {
+	c.seq = 0
+	if err := c.write(payload); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+func (c *myConn) writeFlush(payload []byte) error {
+	if err := c.write(payload); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+// myBackend is the connection of a session to one of the databases
+type myBackend struct {
+	db      Database
+	c       *myConn
+	inTrans bool
+}
+
+func (b *myBackend) status(status uint16) {
+	b.inTrans = status&myStatusInTrans != 0
+}
+
+// myStatement is a statement prepared by an application
+type myStatement struct {
+	query    string
+	params   int
+	ids      map[Database]uint32 // Statement IDs on the databases it is prepared on
+	types    []byte              // Parameter types last bound by the application
+	longData [][]byte            // COM_STMT_SEND_LONG_DATA packets sent since the last execution
+	lastDB   Database            // Database of the last execution, which fetches its cursor
+}
+
+// mySession is the session of an application with a MySQL proxy
+type mySession struct {
+	p       *Proxy
+	client  *myConn
+	ticket  int
+	caps    uint32 // Capabilities agreed with the application
+	charset byte
+
+	backends map[Database]*myBackend
+	active   *myBackend // Backend of the current transaction
+	stmts    map[uint32]*myStatement
+	nextID   uint32
+	begin    string   // BEGIN deferred until the transaction runs a statement
+	setup    [][]byte // Session commands replayed on databases connected later
+}
+
+func (p *Proxy) serveMySQL(conn net.Conn) error {
+	s := &mySession{
+		p:        p,
+		client:   newMyConn(conn),
+		ticket:   newTicket(),
+		backends: make(map[Database]*myBackend),
+		stmts:    make(map[uint32]*myStatement),
+	}
+	defer func() {
+		for _, b := range s.backends {
+			b.c.conn.Close()
+		}
+	}()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	if err := s.authenticate(); err != nil {
+		return err
+	}
+
+	// Connect the database the writes of the session run on
+	db := Source
+	if s.ticket < p.Routes().WritePercent {
+		db = Target
+	}
+	if _, err := s.connect(db); err != nil {
+		s.client.writeFlush(myErrPacket(2003, "HY000", err.Error()))
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+	if err := s.client.writeFlush(myOKPacket(myStatusAutocommit)); err != nil {
+		return err
+	}
+	return s.run()
+}
+
+// authenticate greets the application and checks its credentials with
+// mysql_native_password authentication
+func (s *mySession) authenticate() error {
+	scramble := make([]byte, 20)
+	rand.Read(scramble)
+	for i := range scramble {
+		// The scramble is sent NUL terminated, and clients stop at the first NUL
+		scramble[i] = scramble[i]&0x7f | 0x01
+	}
+	var connectionID [4]byte
+	rand.Read(connectionID[:])
+
+	version := s.p.mysqlVersion
+	if version == "" {
+		version = myDefaultVersion
+	}
+	greeting := []byte{10}
+	greeting = append(greeting, version...)
+	greeting = append(greeting, 0)
+	greeting = append(greeting, connectionID[:]...)
+	greeting = append(greeting, scramble[:8]...)
+	greeting = append(greeting, 0)
+	greeting = binary.LittleEndian.AppendUint16(greeting, uint16(myProxyCapabilities&0xffff))
+	greeting = append(greeting, myCharsetUTF8MB4)
+	greeting = binary.LittleEndian.AppendUint16(greeting, myStatusAutocommit)
+	greeting = binary.LittleEndian.AppendUint16(greeting, uint16(myProxyCapabilities>>16))
+	greeting = append(greeting, 21)
+	greeting = append(greeting, make([]byte, 10)...)
+	greeting = append(greeting, scramble[8:]...)
+	greeting = append(greeting, 0)
+	greeting = append(greeting, myNativePassword...)
+	greeting = append(greeting, 0)
+	s.client.seq = 0
+	if err := s.client.writeFlush(greeting); err != nil {
+		return err
+	}
+
+	pkt, err := s.client.read()
+	if err != nil {
+		return err
+	}
+	response, err := parseHandshakeResponse(pkt)
+	if err != nil {
+		return err
+	}
+	if response.caps&mySSL != 0 {
+		return fmt.Errorf("TLS is not supported by the query proxy")
+	}
+	s.caps = response.caps & myProxyCapabilities
+	s.charset = response.charset
+
+	auth := response.auth
+	if response.caps&myPluginAuth != 0 && response.plugin != myNativePassword {
+		// Ask the application to answer the scramble with mysql_native_password instead
+		authSwitch := []byte{0xfe}
+		authSwitch = append(authSwitch, myNativePassword...)
+		authSwitch = append(authSwitch, 0)
+		authSwitch = append(authSwitch, scramble...)
+		authSwitch = append(authSwitch, 0)
+		if err := s.client.writeFlush(authSwitch); err != nil {
+			return err
+		}
+		if auth, err = s.client.read(); err != nil {
+			return err
+		}
+	}
+
+	expected := scrambleNative(scramble, s.p.config.Password)
+	if response.user != s.p.config.Username || subtle.ConstantTimeCompare(auth, expected) != 1 {
+		s.client.writeFlush(myErrPacket(1045, "28000", fmt.Sprintf("Access denied for user '%s'", response.user)))
+		return fmt.Errorf("authentication failed for user %q", response.user)
+	}
+	return nil
+}
+
+// myHandshakeResponse is the answer of an application to the greeting of the proxy
+type myHandshakeResponse struct {
+	caps    uint32
+	charset byte
+	user    string
+	auth    []byte
+	plugin  string
+}
+
+func parseHandshakeResponse(pkt []byte) (myHandshakeResponse, error) {
+	var r myHandshakeResponse
+	malformed := fmt.Errorf("malformed handshake response")
+	if len(pkt) < 32 {
+		return r, malformed
+	}
+	r.caps = binary.LittleEndian.Uint32(pkt)
+	if r.caps&myProtocol41 == 0 {
+		return r, fmt.Errorf("clients without the 4.1 protocol are not supported")
+	}
+	r.charset = pkt[8]
+	if r.caps&mySSL != 0 && len(pkt) == 32 {
+		// SSL request, sent instead of the response
+		return r, nil
+	}
+
+	user, pos, ok := nulString(pkt, 32)
+	if !ok {
+		return r, malformed
+	}
+	r.user = user
+	switch {
+	case r.caps&myPluginAuthLenenc != 0:
+		n, size, ok := lenEnc(pkt, pos)
+		if !ok || pos+size+int(n) > len(pkt) {
+			return r, malformed
+		}
+		r.auth = pkt[pos+size : pos+size+int(n)]
+		pos += size + int(n)
+	case r.caps&mySecureConnection != 0:
+		if pos >= len(pkt) || pos+1+int(pkt[pos]) > len(pkt) {
+			return r, malformed
+		}
+		r.auth = pkt[pos+1 : pos+1+int(pkt[pos])]
+		pos += 1 + int(pkt[pos])
+	default:
+		auth, next, ok := nulString(pkt, pos)
+		if !ok {
+			return r, malformed
+		}
+		r.auth, pos = []byte(auth), next
+	}
+	if r.caps&myConnectWithDB != 0 {
+		// The databases of the proxy are those of its configuration
+		if _, next, ok := nulString(pkt, pos); ok {
+			pos = next
+		}
+	}
+	if r.caps&myPluginAuth != 0 && pos < len(pkt) {
+		r.plugin, _, _ = nulString(pkt, pos)
+	}
+	return r, nil
+}
+
+// myGreeting is the initial handshake packet of a MySQL server
+type myGreeting struct {
+	version  string
+	caps     uint32
+	scramble []byte
+	plugin   string
+}
+
+func parseGreeting(pkt []byte) (myGreeting, error) {
+	var g myGreeting
+	if pkt[0] == 0xff {
+		return g, myPacketError(pkt)
+	}
+	if pkt[0] != 10 {
+		return g, fmt.Errorf("unsupported protocol version %d", pkt[0])
+	}
+	malformed := fmt.Errorf("malformed server greeting")
+	version, pos, ok := nulString(pkt, 1)
+	if !ok || pos+13 > len(pkt) {
+		return g, malformed
+	}
+	g.version = version
+	pos += 4 // Connection ID
+	g.scramble = append(g.scramble, pkt[pos:pos+8]...)
+	pos += 9
+	g.caps = uint32(binary.LittleEndian.Uint16(pkt[pos:]))
+	pos += 2
+	authLen := 0
+	if pos+16 <= len(pkt) {
+		g.caps |= uint32(binary.LittleEndian.Uint16(pkt[pos+3:])) << 16
+		authLen = int(pkt[pos+5])
+		pos += 16
+	}
+	if g.caps&mySecureConnection != 0 {
+		n := max(13, authLen-8)
+		if pos+n > len(pkt) {
+			return g, malformed
+		}
+		g.scramble = append(g.scramble, pkt[pos:pos+n-1]...)
+		pos += n
+	}
+	g.plugin = myNativePassword
+	if g.caps&myPluginAuth != 0 && pos < len(pkt) {
+		g.plugin, _, _ = nulString(pkt, pos)
+	}
+	return g, nil
+}
+
+// probeMySQLVersion returns the version a MySQL database greets its clients with, so the proxy
+// can greet applications with it
+func probeMySQLVersion(backend Backend) string {
+	conn, err := net.DialTimeout("tcp", backend.address(), 5*time.Second)
+	if err != nil {
+		return myDefaultVersion
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	pkt, err := newMyConn(conn).read()
+	if err != nil {
+		return myDefaultVersion
+	}
+	g, err := parseGreeting(pkt)
+	if err != nil {
+		return myDefaultVersion
+	}
+	return g.version
+}
+
+// connect returns the connection of the session to a database, connecting it first if needed
+func (s *mySession) connect(db Database) (*myBackend, error) {
+	if b, ok := s.backends[db]; ok {
+		return b, nil
+	}
+
+	backend := s.p.backend(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	password := backend.Password
+	if backend.AuthToken != nil {
+		token, err := backend.AuthToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get an authentication token for the %s database: %w", db, err)
+		}
+		password = token
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", backend.address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the %s database: %w", db, err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	c := newMyConn(conn)
+	if err := s.handshake(c, backend, password); err != nil {
+		c.conn.Close()
+		return nil, fmt.Errorf("failed to connect to the %s database: %w", db, err)
+	}
+	c.conn.SetDeadline(time.Time{})
+
+	b := &myBackend{db: db, c: c}
+	s.backends[db] = b
+	for _, pkt := range s.setup {
+		if err := s.discard(b, pkt); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// handshake authenticates the proxy with a database
+func (s *mySession) handshake(c *myConn, backend Backend, password string) error {
+	pkt, err := c.read()
+	if err != nil {
+		return err
+	}
+	g, err := parseGreeting(pkt)
+	if err != nil {
+		return err
+	}
+	if g.caps&myProtocol41 == 0 || g.caps&mySecureConnection == 0 || g.caps&myPluginAuth == 0 {
+		return fmt.Errorf("MySQL %s is not supported", g.version)
+	}
+
+	caps := s.caps&g.caps&^myConnectWithDB | myLongPassword | myProtocol41 | mySecureConnection | myPluginAuth
+	if g.caps&myPluginAuthLenenc != 0 {
+		caps |= myPluginAuthLenenc
+	}
+	if backend.DatabaseName != "" {
+		caps |= myConnectWithDB
+	}
+
+	secure := backend.TLS != nil
+	if secure {
+		if g.caps&mySSL == 0 {
+			return fmt.Errorf("the database does not support TLS")
+		}
+		caps |= mySSL
+		if err := c.writeFlush(myHandshakeHeader(caps, s.charset)); err != nil {
+			return err
+		}
+		tlsConn := tls.Client(c.conn, backend.TLS)
+		if err := tlsConn.Handshake(); err != nil {
+			return err
+		}
+		c.conn, c.r, c.w = tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+	}
+
+	auth, err := myAuthResponse(g.plugin, g.scramble, password)
+	if err != nil {
+		return err
+	}
+	response := myHandshakeHeader(caps, s.charset)
+	response = append(response, backend.Username...)
+	response = append(response, 0)
+	if caps&myPluginAuthLenenc != 0 {
+		response = appendLenEnc(response, uint64(len(auth)))
+	} else {
+		response = append(response, byte(len(auth)))
+	}
+	response = append(response, auth...)
+	if backend.DatabaseName != "" {
+		response = append(response, backend.DatabaseName...)
+		response = append(response, 0)
+	}
+	response = append(response, g.plugin...)
+	response = append(response, 0)
+	if err := c.writeFlush(response); err != nil {
+		return err
+	}
+
+	plugin, scramble := g.plugin, g.scramble
+	for {
+		pkt, err := c.read()
+		if err != nil {
+			return err
+		}
+		switch pkt[0] {
+		case 0x00:
+			return nil
+		case 0xff:
+			return myPacketError(pkt)
+		case 0xfe:
+			// Authentication switch
+			name, pos, ok := nulString(pkt, 1)
+			if !ok {
+				return fmt.Errorf("malformed authentication switch request")
+			}
+			plugin, scramble = name, pkt[pos:]
+			if n := len(scramble); n > 0 && scramble[n-1] == 0 {
+				scramble = scramble[:n-1]
+			}
+			auth, err := myAuthResponse(plugin, scramble, password)
+			if err != nil {
+				return err
+			}
+			if err := c.writeFlush(auth); err != nil {
+				return err
+			}
+		case 0x01:
+			if plugin != myCachingSHA2 || len(pkt) < 2 {
+				return fmt.Errorf("unexpected authentication data")
+			}
+			if pkt[1] == 0x03 {
+				// Fast authentication succeeded, an OK packet follows
+				continue
+			}
+			// Full authentication sends the password, in clear text over TLS and encrypted with
+			// the public key of the server otherwise
+			if secure {
+				err = c.writeFlush(append([]byte(password), 0))
+			} else {
+				err = s.sendEncryptedPassword(c, scramble, password)
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected authentication packet 0x%02x", pkt[0])
+		}
+	}
+}
+
+func (s *mySession) sendEncryptedPassword(c *myConn, scramble []byte, password string) error {
+	if err := c.writeFlush([]byte{0x02}); err != nil {
+		return err
+	}
+	pkt, err := c.read()
+	if err != nil {
+		return err
+	}
+	if pkt[0] != 0x01 {
+		return fmt.Errorf("the database did not send its public key")
+	}
+	block, _ := pem.Decode(pkt[1:])
+	if block == nil {
+		return fmt.Errorf("invalid public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	plain := append([]byte(password), 0)
+	for i := range plain {
+		plain[i] ^= scramble[i%len(scramble)]
+	}
+	encrypted, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, rsaKey, plain, nil)
+	if err != nil {
+		return err
+	}
+	return c.writeFlush(encrypted)
+}
+
+// myHandshakeHeader returns the fixed part of a handshake response, which is also the SSL request
+func myHandshakeHeader(caps uint32, charset byte) []byte {
+	header := binary.LittleEndian.AppendUint32(nil, caps)
+	header = binary.LittleEndian.AppendUint32(header, myMaxPacket)
+	header = append(header, charset)
+	return append(header, make([]byte, 23)...)
+}
+
+func myAuthResponse(plugin string, scramble []byte, password string) ([]byte, error) {
+	switch plugin {
+	case myNativePassword:
+		return scrambleNative(scramble, password), nil
+	case myCachingSHA2:
+		return scrambleSHA256(scramble, password), nil
+	case myClearPassword:
+		return append([]byte(password), 0), nil
+	default:
+		return nil, fmt.Errorf("authentication plugin %s is not supported", plugin)
+	}
+}
+
+// scrambleNative answers a scramble with mysql_native_password:
+// SHA1(password) XOR SHA1(scramble + SHA1(SHA1(password)))
+func scrambleNative(scramble []byte, password string) []byte {
+	if password == "" {
+		return []byte{}
+	}
+	h1 := sha1.Sum([]byte(password))
+	h2 := sha1.Sum(h1[:])
+	h := sha1.New()
+	h.Write(scramble[:min(20, len(scramble))])
+	h.Write(h2[:])
+	h3 := h.Sum(nil)
+	for i := range h3 {
+		h3[i] ^= h1[i]
+	}
+	return h3
+}
+
+// scrambleSHA256 answers a scramble with caching_sha2_password:
+// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + scramble)
+func scrambleSHA256(scramble []byte, password string) []byte {
+	if password == "" {
+		return []byte{}
+	}
+	m1 := sha256.Sum256([]byte(password))
+	m2 := sha256.Sum256(m1[:])
+	h := sha256.New()
+	h.Write(m2[:])
+	h.Write(scramble[:min(20, len(scramble))])
+	m3 := h.Sum(nil)
+	for i := range m3 {
+		m3[i] ^= m1[i]
+	}
+	return m3
+}
+
+// run relays the commands of the application until it quits
+func (s *mySession) run() error {
+	for {
+		pkt, err := s.client.read()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch pkt[0] {
+		case comQuit:
+			for _, b := range s.backends {
+				b.c.command(pkt)
+			}
+			return nil
+		case comPing:
+			err = s.client.writeFlush(myOKPacket(s.status()))
+		case comQuery:
+			err = s.query(pkt)
+		case comInitDB, comSetOption, comResetConnection:
+			err = s.session(pkt)
+		case comFieldList, comStatistics:
+			err = s.metadata(pkt)
+		case comStmtPrepare:
+			err = s.prepare(pkt)
+		case comStmtExecute:
+			err = s.execute(pkt)
+		case comStmtSendLongData:
+			if st := s.statement(pkt); st != nil {
+				st.longData = append(st.longData, pkt)
+			}
+		case comStmtClose:
+			err = s.closeStatement(pkt)
+		case comStmtReset:
+			err = s.resetStatement(pkt)
+		case comStmtFetch:
+			err = s.fetch(pkt)
+		default:
+			err = s.client.writeFlush(myErrPacket(myErrUnknownCommand, "08S01", fmt.Sprintf("Command 0x%02x is not supported by the query proxy", pkt[0])))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// status returns the server status flags the proxy answers with
+func (s *mySession) status() uint16 {
+	status := uint16(myStatusAutocommit)
+	if s.busy() || s.begin != "" {
+		status |= myStatusInTrans
+	}
+	return status
+}
+
+// busy reports whether the statements of the session must run on the active database, since it
+// is in a transaction
+func (s *mySession) busy() bool {
+	return s.active != nil && s.active.inTrans
+}
+
+// pick returns the database a statement runs on, and sends it the deferred BEGIN. It returns
+// the packet answering the statement instead if the database cannot run it.
+func (s *mySession) pick(stmt Statement) (*myBackend, []byte, error) {
+	if s.busy() {
+		s.p.count(s.active.db)
+		return s.active, nil, nil
+	}
+	db := s.p.route(stmt, s.ticket)
+	b, err := s.connect(db)
+	if err != nil {
+		return nil, myErrPacket(2013, "HY000", err.Error()), nil
+	}
+	if s.begin != "" {
+		begin := append([]byte{comQuery}, s.begin...)
+		s.begin = ""
+		var failed []byte
+		if err := b.c.command(begin); err != nil {
+			return nil, nil, err
+		}
+		err := s.readResult(b, comQuery, func(pkt []byte) error {
+			if pkt[0] == 0xff {
+				failed = pkt
+			}
+			return nil
+		})
+		if err != nil || failed != nil {
+			return nil, failed, err
+		}
+	}
+	s.active = b
+	return b, nil, nil
+}
+
+// forward sends a command to a database and relays its response to the application
+func (s *mySession) forward(b *myBackend, pkt []byte) error {
+	if err := b.c.command(pkt); err != nil {
+		return err
+	}
+	if err := s.readResult(b, pkt[0], s.client.write); err != nil {
+		return err
+	}
+	return s.client.w.Flush()
+}
+
+// discard sends a command to a database, dropping its response
+func (s *mySession) discard(b *myBackend, pkt []byte) error {
+	if err := b.c.command(pkt); err != nil {
+		return err
+	}
+	return s.readResult(b, pkt[0], func(pkt []byte) error {
+		if pkt[0] == 0xff {
+			s.p.logf("Query proxy %s: command of the proxy failed on the %s database: %v", s.p.config.Name, b.db, myPacketError(pkt))
+		}
+		return nil
+	})
+}
+
+// readResult reads the response of a database to a command, passing its packets to emit
+func (s *mySession) readResult(b *myBackend, cmd byte, emit func([]byte) error) error {
+	read := func() ([]byte, error) {
+		pkt, err := b.c.read()
+		if err == nil {
+			err = emit(pkt)
+		}
+		return pkt, err
+	}
+
+	switch cmd {
+	case comQuery, comStmtExecute:
+		for {
+			pkt, err := read()
+			if err != nil {
+				return err
+			}
+			switch pkt[0] {
+			case 0x00:
+				status := myOKStatus(pkt)
+				b.status(status)
+				if status&myStatusMoreResults == 0 {
+					return nil
+				}
+				continue
+			case 0xff:
+				return nil
+			case 0xfb:
+				return fmt.Errorf("LOAD DATA LOCAL is not supported by the query proxy")
+			}
+
+			// Result set: column definitions, EOF, rows, EOF
+			columns, _, _ := lenEnc(pkt, 0)
+			for i := uint64(0); i <= columns; i++ {
+				if pkt, err = read(); err != nil {
+					return err
+				}
+			}
+			status := myEOFStatus(pkt)
+			if cmd != comStmtExecute || status&myStatusCursorExists == 0 {
+				for {
+					if pkt, err = read(); err != nil {
+						return err
+					}
+					if pkt[0] == 0xff {
+						return nil
+					}
+					if isEOFPacket(pkt) {
+						status = myEOFStatus(pkt)
+						break
+					}
+				}
+			}
+			b.status(status)
+			if status&myStatusMoreResults == 0 {
+				return nil
+			}
+		}
+
+	case comStmtFetch, comFieldList:
+		// Rows or column definitions ended by EOF
+		for {
+			pkt, err := read()
+			if err != nil {
+				return err
+			}
+			if pkt[0] == 0xff {
+				return nil
+			}
+			if isEOFPacket(pkt) {
+				b.status(myEOFStatus(pkt))
+				return nil
+			}
+		}
+
+	case comStmtPrepare:
+		pkt, err := read()
+		if err != nil || pkt[0] != 0x00 || len(pkt) < 9 {
+			return err
+		}
+		columns := binary.LittleEndian.Uint16(pkt[5:])
+		params := binary.LittleEndian.Uint16(pkt[7:])
+		for _, n := range []uint16{params, columns} {
+			if n == 0 {
+				continue
+			}
+			for i := uint16(0); i <= n; i++ {
+				if _, err := read(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	default:
+		pkt, err := read()
+		if err != nil {
+			return err
+		}
+		switch {
+		case pkt[0] == 0x00 && cmd != comStatistics:
+			b.status(myOKStatus(pkt))
+		case isEOFPacket(pkt):
+			b.status(myEOFStatus(pkt))
+		}
+		return nil
+	}
+}
+
+// query runs a COM_QUERY
+func (s *mySession) query(pkt []byte) error {
+	query := string(pkt[1:])
+	stmt := Classify(query)
+	if !s.busy() {
+		switch {
+		case stmt.Kind == KindBegin && s.begin == "":
+			// The database of the transaction is only known once it runs a statement
+			s.begin = query
+			return s.client.writeFlush(myOKPacket(myStatusAutocommit | myStatusInTrans))
+		case stmt.Kind == KindEnd && s.begin != "":
+			s.begin = ""
+			return s.client.writeFlush(myOKPacket(myStatusAutocommit))
+		case stmt.Kind == KindSession && s.begin == "":
+			return s.session(pkt)
+		}
+	}
+
+	b, answer, err := s.pick(stmt)
+	if err != nil || answer != nil {
+		if answer != nil {
+			err = s.client.writeFlush(answer)
+		}
+		return err
+	}
+	return s.forward(b, pkt)
+}
+
+// session runs a command changing the state of the session on every database, answering with
+// the response of the database writes run on, and keeps it for the databases connected later
+func (s *mySession) session(pkt []byte) error {
+	b := s.active
+	if !s.busy() {
+		var err error
+		if b, err = s.connect(s.p.Routes().Route(Statement{Kind: KindSession}, s.ticket)); err != nil {
+			return s.client.writeFlush(myErrPacket(2013, "HY000", err.Error()))
+		}
+		s.active = b
+	}
+	if err := s.forward(b, pkt); err != nil {
+		return err
+	}
+	for db, o := range s.backends {
+		if db != b.db {
+			if err := s.discard(o, pkt); err != nil {
+				return err
+			}
+		}
+	}
+
+	if pkt[0] == comResetConnection {
+		// Resetting the connection drops the prepared statements and the session state
+		s.setup, s.stmts, s.begin = nil, make(map[uint32]*myStatement), ""
+		return nil
+	}
+	s.setup = append(s.setup, pkt)
+	return nil
+}
+
+// metadata runs COM_FIELD_LIST or COM_STATISTICS where the reads run
+func (s *mySession) metadata(pkt []byte) error {
+	b, answer, err := s.pick(Statement{Kind: KindRead})
+	if err != nil || answer != nil {
+		if answer != nil {
+			err = s.client.writeFlush(answer)
+		}
+		return err
+	}
+	return s.forward(b, pkt)
+}
+
+// statement returns the prepared statement a COM_STMT_* packet refers to
+func (s *mySession) statement(pkt []byte) *myStatement {
+	if len(pkt) < 5 {
+		return nil
+	}
+	return s.stmts[binary.LittleEndian.Uint32(pkt[1:])]
+}
+
+// withStatementID returns a copy of a COM_STMT_* packet referring to another statement ID
+func withStatementID(pkt []byte, id uint32) []byte {
+	out := append([]byte(nil), pkt...)
+	binary.LittleEndian.PutUint32(out[1:], id)
+	return out
+}
+
+// prepare runs a COM_STMT_PREPARE on the database its statement is routed to. The statement is
+// prepared on the other database once it runs there.
+func (s *mySession) prepare(pkt []byte) error {
+	query := string(pkt[1:])
+	db := s.p.Routes().Route(Classify(query), s.ticket)
+	if s.busy() {
+		db = s.active.db
+	}
+	b, err := s.connect(db)
+	if err != nil {
+		return s.client.writeFlush(myErrPacket(2013, "HY000", err.Error()))
+	}
+	if err := b.c.command(pkt); err != nil {
+		return err
+	}
+
+	clientID := s.nextID + 1
+	first := true
+	err = s.readResult(b, comStmtPrepare, func(resp []byte) error {
+		if first && resp[0] == 0x00 && len(resp) >= 9 {
+			s.nextID = clientID
+			s.stmts[clientID] = &myStatement{
+				query:  query,
+				params: int(binary.LittleEndian.Uint16(resp[7:])),
+				ids:    map[Database]uint32{db: binary.LittleEndian.Uint32(resp[1:])},
+				lastDB: db,
+			}
+			resp = withStatementID(resp, clientID)
+		}
+		first = false
+		return s.client.write(resp)
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.w.Flush()
+}
+
+// prepared returns the ID of a statement on a database, preparing it there if needed. It
+// returns the error packet of the database if the statement cannot be prepared.
+func (s *mySession) prepared(st *myStatement, b *myBackend) (uint32, []byte, error) {
+	if id, ok := st.ids[b.db]; ok {
+		return id, nil, nil
+	}
+	if err := b.c.command(append([]byte{comStmtPrepare}, st.query...)); err != nil {
+		return 0, nil, err
+	}
+	var id uint32
+	var failed []byte
+	first := true
+	err := s.readResult(b, comStmtPrepare, func(resp []byte) error {
+		if first {
+			if resp[0] == 0xff {
+				failed = resp
+			} else if len(resp) >= 5 {
+				id = binary.LittleEndian.Uint32(resp[1:])
+			}
+		}
+		first = false
+		return nil
+	})
+	if err != nil || failed != nil {
+		return 0, failed, err
+	}
+	st.ids[b.db] = id
+	return id, nil, nil
+}
+
+// execute runs a COM_STMT_EXECUTE on the database its statement is routed to
+func (s *mySession) execute(pkt []byte) error {
+	st := s.statement(pkt)
+	if st == nil {
+		return s.client.writeFlush(myErrPacket(1243, "HY000", "Unknown prepared statement handler given to mysqld_stmt_execute"))
+	}
+	b, answer, err := s.pick(Classify(st.query))
+	if err != nil {
+		return err
+	}
+	var id uint32
+	if answer == nil {
+		id, answer, err = s.prepared(st, b)
+		if err != nil {
+			return err
+		}
+	}
+	if answer != nil {
+		return s.client.writeFlush(answer)
+	}
+
+	for _, data := range st.longData {
+		b.c.seq = 0
+		if err := b.c.write(withStatementID(data, id)); err != nil {
+			return err
+		}
+	}
+	st.longData = nil
+
+	// The parameter types bound by the application are sent with every execution, since the
+	// previous one may have run on the other database
+	exec := withStatementID(pkt, id)
+	if st.params > 0 {
+		pos := 10 + (st.params+7)/8
+		if pos < len(exec) {
+			switch {
+			case exec[pos] == 1 && pos+1+2*st.params <= len(exec):
+				st.types = append([]byte(nil), exec[pos+1:pos+1+2*st.params]...)
+			case exec[pos] == 0 && st.types != nil:
+				rebound := append([]byte(nil), exec[:pos]...)
+				rebound = append(rebound, 1)
+				rebound = append(rebound, st.types...)
+				exec = append(rebound, exec[pos+1:]...)
+			}
+		}
+	}
+	st.lastDB = b.db
+	return s.forward(b, exec)
+}
+
+// fetch runs a COM_STMT_FETCH on the database the cursor of the statement is open on
+func (s *mySession) fetch(pkt []byte) error {
+	st := s.statement(pkt)
+	var b *myBackend
+	ok := false
+	if st != nil {
+		b, ok = s.backends[st.lastDB]
+	}
+	if !ok {
+		return s.client.writeFlush(myErrPacket(1243, "HY000", "Unknown prepared statement handler given to mysqld_stmt_fetch"))
+	}
+	id, ok := st.ids[b.db]
+	if !ok {
+		return s.client.writeFlush(myErrPacket(1421, "HY000", "The statement has no open cursor"))
+	}
+	s.p.count(b.db)
+	return s.forward(b, withStatementID(pkt, id))
+}
+
+// closeStatement runs a COM_STMT_CLOSE on every database the statement is prepared on. It has
+// no response.
+func (s *mySession) closeStatement(pkt []byte) error {
+	st := s.statement(pkt)
+	if st == nil {
+		return nil
+	}
+	delete(s.stmts, binary.LittleEndian.Uint32(pkt[1:]))
+	for db, id := range st.ids {
+		if b, ok := s.backends[db]; ok {
+			if err := b.c.command(withStatementID(pkt, id)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resetStatement runs a COM_STMT_RESET on every database the statement is prepared on
+func (s *mySession) resetStatement(pkt []byte) error {
+	st := s.statement(pkt)
+	if st == nil {
+		return s.client.writeFlush(myErrPacket(1243, "HY000", "Unknown prepared statement handler given to mysqld_stmt_reset"))
+	}
+	st.longData = nil
+	for db, id := range st.ids {
+		if b, ok := s.backends[db]; ok {
+			if err := s.discard(b, withStatementID(pkt, id)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.client.writeFlush(myOKPacket(s.status()))
+}
+
+func myOKPacket(status uint16) []byte {
+	pkt := []byte{0x00, 0, 0}
+	pkt = binary.LittleEndian.AppendUint16(pkt, status)
+	return append(pkt, 0, 0)
+}
+
+func myErrPacket(code uint16, state, message string) []byte {
+	pkt := []byte{0xff}
+	pkt = binary.LittleEndian.AppendUint16(pkt, code)
+	pkt = append(pkt, '#')
+	pkt = append(pkt, state...)
+	return append(pkt, message...)
+}
+
+// myPacketError returns the error of an ERR packet
+func myPacketError(pkt []byte) error {
+	if len(pkt) < 3 {
+		return fmt.Errorf("malformed error packet")
+	}
+	code := binary.LittleEndian.Uint16(pkt[1:])
+	message := pkt[3:]
+	if len(message) >= 6 && message[0] == '#' {
+		message = message[6:]
+	}
+	return fmt.Errorf("error %d: %s", code, message)
+}
+
+func isEOFPacket(pkt []byte) bool {
+	return pkt[0] == 0xfe && len(pkt) < 9
+}
+
+func myOKStatus(pkt []byte) uint16 {
+	_, n1, ok1 := lenEnc(pkt, 1)
+	_, n2, ok2 := lenEnc(pkt, 1+n1)
+	pos := 1 + n1 + n2
+	if !ok1 || !ok2 || pos+2 > len(pkt) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(pkt[pos:])
+}
+
+func myEOFStatus(pkt []byte) uint16 {
+	if len(pkt) < 5 {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(pkt[3:])
+}
+
+// lenEnc reads a length-encoded integer at b[pos], and returns it with its size
+func lenEnc(b []byte, pos int) (uint64, int, bool) {
+	if pos >= len(b) {
+		return 0, 0, false
+	}
+	size := 1
+	switch b[pos] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	}
+	if pos+size > len(b) {
+		return 0, 0, false
+	}
+	if size == 1 {
+		return uint64(b[pos]), 1, true
+	}
+	var v uint64
+	for i := size - 1; i >= 1; i-- {
+		v = v<<8 | uint64(b[pos+i])
+	}
+	return v, size, true
+}
+
+func appendLenEnc(b []byte, v uint64) []byte {
+	switch {
+	case v < 0xfb:
+		return append(b, byte(v))
+	case v <= 0xffff:
+		return append(b, 0xfc, byte(v), byte(v>>8))
+	case v <= 0xffffff:
+		return append(b, 0xfd, byte(v), byte(v>>8), byte(v>>16))
+	default:
+		return binary.LittleEndian.AppendUint64(append(b, 0xfe), v)
+	}
+}
+
+// nulString reads a NUL-terminated string at b[pos], and returns it with the index following
+// it. A string at the end of b may lack its NUL.
+func nulString(b []byte, pos int) (string, int, bool) {
+	if pos > len(b) {
+		return "", pos, false
+	}
+	for i := pos; i < len(b); i++ {
+		if b[i] == 0 {
+			return string(b[pos:i]), i + 1, true
+		}
+	}
+	return string(b[pos:]), len(b), true
+}
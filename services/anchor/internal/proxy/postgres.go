@@ -0,0 +1,745 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// Responses relayed to applications are flushed after each message, except rows and copy data
+// which are flushed in groups
+const pgFlushEvery = 64
+
+// pgStatement is a statement prepared by an application
+type pgStatement struct {
+	query string
+	oids  []uint32
+}
+
+// pgMessage is an extended protocol message held until the batch it belongs to is sent
+type pgMessage struct {
+	data       []byte
+	kind       byte // Message type, e.g. 'P' for Parse
+	name       string
+	query      string // Query of a Parse
+	objectType byte   // 'S' or 'P' for Describe and Close
+}
+
+// pgBackend is the connection of a session to one of the databases
+type pgBackend struct {
+	db       Database
+	conn     net.Conn
+	frontend *pgproto3.Frontend
+	w        *bufio.Writer
+	params   map[string]string
+
+	// Used by the session goroutine only
+	prepared map[string]string // Queries of the statements prepared on the database, by name
+	unsynced int               // Parse messages sent since the last Sync
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   int          // Cycles (Query or Sync) awaiting their ReadyForQuery
+	discard   int          // Cycles sent by the proxy itself, ahead of pending, whose responses are dropped
+	txStatus  byte         // Transaction status of the last ReadyForQuery
+	parses    int          // ParseComplete messages received in the current cycle
+	dropParse map[int]bool // ParseComplete messages of the current cycle answering Parse messages of the proxy
+	closed    bool
+}
+
+func (b *pgBackend) busy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pending > 0 || b.txStatus != 'I'
+}
+
+// waitIdle waits until the database answered all cycles sent to it
+func (b *pgBackend) waitIdle() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for (b.pending > 0 || b.discard > 0) && !b.closed {
+		b.cond.Wait()
+	}
+	if b.closed {
+		return net.ErrClosed
+	}
+	return nil
+}
+
+func (b *pgBackend) send(msgs ...pgproto3.FrontendMessage) error {
+	for _, msg := range msgs {
+		data, err := msg.Encode(nil)
+		if err != nil {
+			return err
+		}
+		if _, err := b.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return b.w.Flush()
+}
+
+// sendDiscard runs a query whose responses the application does not see
+func (b *pgBackend) sendDiscard(query string) error {
+	b.mu.Lock()
+	b.discard++
+	b.mu.Unlock()
+	return b.send(&pgproto3.Query{String: query})
+}
+
+// pgSession is the session of an application with a PostgreSQL proxy
+type pgSession struct {
+	p      *Proxy
+	conn   net.Conn
+	client *pgproto3.Backend
+	params map[string]string // Startup parameters of the application
+	ticket int
+
+	wmu sync.Mutex
+	w   *bufio.Writer
+
+	mu       sync.Mutex
+	backends map[Database]*pgBackend
+
+	active     *pgBackend             // Backend of the current transaction or batch
+	statements map[string]pgStatement // Statements prepared by the application, by name
+	batch      []pgMessage            // Extended protocol messages awaiting a Sync or Flush
+	inBatch    bool                   // Part of the current batch was sent to active
+	begin      string                 // BEGIN deferred until the transaction runs a statement
+	setup      []string               // Session statements replayed on databases connected later
+
+	closeOnce sync.Once
+}
+
+func (p *Proxy) servePostgres(conn net.Conn) error {
+	s := &pgSession{
+		p:          p,
+		conn:       conn,
+		client:     pgproto3.NewBackend(conn, conn),
+		w:          bufio.NewWriter(conn),
+		ticket:     newTicket(),
+		backends:   make(map[Database]*pgBackend),
+		statements: make(map[string]pgStatement),
+	}
+	defer s.close()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	startup, err := s.startup()
+	if err != nil || startup == nil {
+		return err
+	}
+	if err := s.authenticate(startup); err != nil {
+		return err
+	}
+
+	// Connect the database the writes of the session run on, which greets the application
+	routes := p.Routes()
+	db := Source
+	if s.ticket < routes.WritePercent {
+		db = Target
+	}
+	b, err := s.connect(db)
+	if err != nil {
+		s.reply(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "08006", Message: err.Error()})
+		return err
+	}
+	s.active = b
+	conn.SetDeadline(time.Time{})
+
+	msgs := []pgproto3.BackendMessage{&pgproto3.AuthenticationOk{}}
+	for name, value := range b.params {
+		msgs = append(msgs, &pgproto3.ParameterStatus{Name: name, Value: value})
+	}
+	var key [8]byte
+	rand.Read(key[:])
+	msgs = append(msgs,
+		&pgproto3.BackendKeyData{ProcessID: binary.BigEndian.Uint32(key[:4]), SecretKey: binary.BigEndian.Uint32(key[4:])},
+		&pgproto3.ReadyForQuery{TxStatus: 'I'},
+	)
+	if err := s.reply(msgs...); err != nil {
+		return err
+	}
+	return s.run()
+}
+
+// startup reads the startup message of the application, declining encryption. It returns nil
+// for cancel requests, which the proxy does not support.
+func (s *pgSession) startup() (*pgproto3.StartupMessage, error) {
+	for {
+		msg, err := s.client.ReceiveStartupMessage()
+		if err != nil {
+			return nil, err
+		}
+		switch m := msg.(type) {
+		case *pgproto3.SSLRequest, *pgproto3.GSSEncRequest:
+			if _, err := s.conn.Write([]byte{'N'}); err != nil {
+				return nil, err
+			}
+		case *pgproto3.CancelRequest:
+			return nil, nil
+		case *pgproto3.StartupMessage:
+			if replication, ok := m.Parameters["replication"]; ok && replication != "false" && replication != "0" {
+				s.reply(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "0A000", Message: "replication connections are not supported by the query proxy"})
+				return nil, fmt.Errorf("replication connection refused")
+			}
+			return m, nil
+		default:
+			return nil, fmt.Errorf("unexpected startup message %T", msg)
+		}
+	}
+}
+
+// authenticate checks the credentials of the application with MD5 password authentication
+func (s *pgSession) authenticate(startup *pgproto3.StartupMessage) error {
+	user := startup.Parameters["user"]
+	var salt [4]byte
+	rand.Read(salt[:])
+	if err := s.reply(&pgproto3.AuthenticationMD5Password{Salt: salt}); err != nil {
+		return err
+	}
+	if err := s.client.SetAuthType(pgproto3.AuthTypeMD5Password); err != nil {
+		return err
+	}
+	msg, err := s.client.Receive()
+	if err != nil {
+		return err
+	}
+	password, ok := msg.(*pgproto3.PasswordMessage)
+	if !ok {
+		return fmt.Errorf("expected a password message, got %T", msg)
+	}
+
+	expected := "md5" + md5Hex(md5Hex(s.p.config.Password+s.p.config.Username)+string(salt[:]))
+	if user != s.p.config.Username || subtle.ConstantTimeCompare([]byte(password.Password), []byte(expected)) != 1 {
+		s.reply(&pgproto3.ErrorResponse{Severity: "FATAL", Code: "28P01", Message: fmt.Sprintf("password authentication failed for user %q", user)})
+		return fmt.Errorf("authentication failed for user %q", user)
+	}
+
+	s.params = make(map[string]string)
+	for name, value := range startup.Parameters {
+		switch name {
+		case "user", "database", "replication":
+		default:
+			s.params[name] = value
+		}
+	}
+	return nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// connect returns the connection of the session to a database, connecting it first if needed
+func (s *pgSession) connect(db Database) (*pgBackend, error) {
+	s.mu.Lock()
+	b, ok := s.backends[db]
+	s.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	backend := s.p.backend(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	password := backend.Password
+	if backend.AuthToken != nil {
+		token, err := backend.AuthToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get an authentication token for the %s database: %w", db, err)
+		}
+		password = token
+	}
+	sslMode := "disable"
+	if backend.TLS != nil {
+		sslMode = "require"
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(backend.Username, password),
+		Host:     backend.address(),
+		Path:     "/" + backend.DatabaseName,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	config, err := pgconn.ParseConfig(u.String())
+	if err != nil {
+		return nil, err
+	}
+	config.TLSConfig = backend.TLS
+	config.Fallbacks = nil
+	for name, value := range s.params {
+		config.RuntimeParams[name] = value
+	}
+
+	pgConn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the %s database: %w", db, err)
+	}
+	hijacked, err := pgConn.Hijack()
+	if err != nil {
+		pgConn.Close(ctx)
+		return nil, err
+	}
+
+	b = &pgBackend{
+		db:        db,
+		conn:      hijacked.Conn,
+		frontend:  hijacked.Frontend,
+		w:         bufio.NewWriter(hijacked.Conn),
+		params:    hijacked.ParameterStatuses,
+		prepared:  make(map[string]string),
+		txStatus:  'I',
+		dropParse: make(map[int]bool),
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	s.mu.Lock()
+	s.backends[db] = b
+	s.mu.Unlock()
+	go s.relay(b)
+
+	for _, query := range s.setup {
+		if err := b.sendDiscard(query); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (s *pgSession) close() {
+	s.closeOnce.Do(func() {
+		s.conn.Close()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, b := range s.backends {
+			b.conn.Close()
+		}
+	})
+}
+
+// reply writes messages of the proxy itself to the application
+func (s *pgSession) reply(msgs ...pgproto3.BackendMessage) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	for _, msg := range msgs {
+		data, err := msg.Encode(nil)
+		if err != nil {
+			return err
+		}
+		if _, err := s.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return s.w.Flush()
+}
+
+// relay forwards the responses of a database to the application, dropping those of the cycles
+// sent by the proxy itself
+func (s *pgSession) relay(b *pgBackend) {
+	defer func() {
+		b.mu.Lock()
+		b.closed = true
+		b.cond.Broadcast()
+		b.mu.Unlock()
+		s.close()
+	}()
+
+	var buf []byte
+	unflushed := 0
+	for {
+		msg, err := b.frontend.Receive()
+		if err != nil {
+			return
+		}
+
+		b.mu.Lock()
+		drop := b.discard > 0
+		switch m := msg.(type) {
+		case *pgproto3.ReadyForQuery:
+			if b.discard > 0 {
+				b.discard--
+			} else {
+				b.pending--
+			}
+			b.txStatus = m.TxStatus
+			b.parses = 0
+			clear(b.dropParse)
+			b.cond.Broadcast()
+		case *pgproto3.ParseComplete:
+			if !drop {
+				drop = b.dropParse[b.parses]
+				b.parses++
+			}
+		case *pgproto3.ErrorResponse:
+			if drop {
+				s.p.logf("Query proxy %s: statement of the proxy failed on the %s database: %s", s.p.config.Name, b.db, m.Message)
+			}
+		}
+		b.mu.Unlock()
+		if drop {
+			continue
+		}
+
+		buf, err = msg.Encode(buf[:0])
+		if err != nil {
+			return
+		}
+		s.wmu.Lock()
+		_, err = s.w.Write(buf)
+		if err == nil {
+			switch msg.(type) {
+			case *pgproto3.DataRow, *pgproto3.CopyData:
+				unflushed++
+				if unflushed >= pgFlushEvery {
+					err = s.w.Flush()
+					unflushed = 0
+				}
+			default:
+				err = s.w.Flush()
+				unflushed = 0
+			}
+		}
+		s.wmu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// busy reports whether the statements of the session must run on the active database, since it
+// is in a transaction or answering earlier statements
+func (s *pgSession) busy() bool {
+	return s.inBatch || s.active != nil && s.active.busy()
+}
+
+// pick returns the database a statement runs on, and sends it the deferred BEGIN
+func (s *pgSession) pick(stmt Statement) (*pgBackend, error) {
+	if s.busy() {
+		s.p.count(s.active.db)
+		return s.active, nil
+	}
+	b, err := s.connect(s.p.route(stmt, s.ticket))
+	if err != nil {
+		return nil, err
+	}
+	if s.begin != "" {
+		if err := b.sendDiscard(s.begin); err != nil {
+			return nil, err
+		}
+		s.begin = ""
+	}
+	s.active = b
+	return b, nil
+}
+
+// run relays the messages of the application until it terminates the session
+func (s *pgSession) run() error {
+	for {
+		msg, err := s.client.Receive()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch m := msg.(type) {
+		case *pgproto3.Query:
+			err = s.query(m.String)
+		case *pgproto3.Parse:
+			s.statements[m.Name] = pgStatement{query: m.Query, oids: append([]uint32(nil), m.ParameterOIDs...)}
+			err = s.hold(msg, m.Name, 0, m.Query)
+		case *pgproto3.Bind:
+			err = s.hold(msg, m.PreparedStatement, 0, "")
+		case *pgproto3.Describe:
+			err = s.hold(msg, m.Name, m.ObjectType, "")
+		case *pgproto3.Close:
+			if m.ObjectType == 'S' {
+				delete(s.statements, m.Name)
+			}
+			err = s.hold(msg, m.Name, m.ObjectType, "")
+		case *pgproto3.Execute:
+			err = s.hold(msg, m.Portal, 0, "")
+		case *pgproto3.Flush:
+			if err = s.hold(msg, "", 0, ""); err == nil {
+				err = s.sendBatch(false)
+			}
+		case *pgproto3.Sync:
+			if err = s.hold(msg, "", 0, ""); err == nil {
+				err = s.sendBatch(true)
+			}
+		case *pgproto3.FunctionCall:
+			var b *pgBackend
+			if b, err = s.pick(Statement{Kind: KindWrite}); err == nil {
+				b.mu.Lock()
+				b.pending++
+				b.mu.Unlock()
+				err = b.send(msg)
+			}
+		case *pgproto3.CopyData, *pgproto3.CopyDone, *pgproto3.CopyFail:
+			// COPY FROM STDIN runs on the database the COPY statement was sent to
+			if s.active != nil {
+				err = s.active.send(msg)
+			}
+		case *pgproto3.Terminate:
+			s.mu.Lock()
+			for _, b := range s.backends {
+				b.send(msg)
+			}
+			s.mu.Unlock()
+			return nil
+		default:
+			return fmt.Errorf("unexpected message %T", msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// query runs a simple query
+func (s *pgSession) query(query string) error {
+	stmt := Classify(query)
+	if !s.busy() {
+		switch {
+		case stmt.Kind == KindBegin && s.begin == "":
+			// The database of the transaction is only known once it runs a statement
+			s.begin = query
+			return s.reply(&pgproto3.CommandComplete{CommandTag: []byte("BEGIN")}, &pgproto3.ReadyForQuery{TxStatus: 'T'})
+		case stmt.Kind == KindEnd && s.begin != "":
+			s.begin = ""
+			tag := "COMMIT"
+			if stmt.Tag == "ROLLBACK" || stmt.Tag == "ABORT" {
+				tag = "ROLLBACK"
+			}
+			return s.reply(&pgproto3.CommandComplete{CommandTag: []byte(tag)}, &pgproto3.ReadyForQuery{TxStatus: 'I'})
+		case stmt.Kind == KindSession && s.begin == "":
+			if err := s.broadcast([]string{query}); err != nil {
+				return err
+			}
+		}
+	}
+
+	b, err := s.pick(stmt)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.pending++
+	b.mu.Unlock()
+	return b.send(&pgproto3.Query{String: query})
+}
+
+// broadcast runs session statements on the databases the session is not about to send them to,
+// and keeps them for the databases it connects to later
+func (s *pgSession) broadcast(queries []string) error {
+	primary := s.p.Routes().Route(Statement{Kind: KindSession}, s.ticket)
+	s.mu.Lock()
+	var others []*pgBackend
+	for db, b := range s.backends {
+		if db != primary {
+			others = append(others, b)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, b := range others {
+		for _, query := range queries {
+			if err := b.sendDiscard(query); err != nil {
+				return err
+			}
+		}
+	}
+	s.setup = append(s.setup, queries...)
+	return nil
+}
+
+// hold keeps an extended protocol message until its batch is sent
+func (s *pgSession) hold(msg pgproto3.FrontendMessage, name string, objectType byte, query string) error {
+	data, err := msg.Encode(nil)
+	if err != nil {
+		return err
+	}
+	s.batch = append(s.batch, pgMessage{data: data, kind: data[0], name: name, objectType: objectType, query: query})
+	return nil
+}
+
+// classifyBatch classifies the statements of a batch together, and returns the session
+// statements among them. It returns false if the batch runs no known statement.
+func (s *pgSession) classifyBatch(batch []pgMessage) (Statement, []string, bool) {
+	var result Statement
+	var session []string
+	found := false
+	allSession := true
+	for _, m := range batch {
+		query := m.query
+		if m.kind == 'B' {
+			query = s.statements[m.name].query
+		}
+		if m.kind != 'P' && m.kind != 'B' || query == "" {
+			continue
+		}
+		stmt := Classify(query)
+		if stmt.Kind == KindBegin || stmt.Kind == KindEnd {
+			continue
+		}
+		if stmt.Kind == KindSession {
+			session = append(session, query)
+		} else {
+			allSession = false
+		}
+		if !found {
+			result, found = stmt, true
+			continue
+		}
+		result.Tables = append(result.Tables, stmt.Tables...)
+		if stmt.Kind != result.Kind {
+			result.Kind = KindWrite
+		}
+	}
+	if !found {
+		result.Kind = KindWrite
+	}
+	if !allSession {
+		session = nil
+	}
+	return result, session, found
+}
+
+// sendBatch sends the extended protocol messages held since the last Sync or Flush
+func (s *pgSession) sendBatch(sync bool) error {
+	batch := s.batch
+	s.batch = nil
+
+	var b *pgBackend
+	if s.busy() {
+		b = s.active
+		s.p.count(b.db)
+	} else {
+		stmt, session, ok := s.classifyBatch(batch)
+		if !ok && s.active != nil && s.begin == "" {
+			b = s.active
+		} else {
+			if len(session) > 0 && s.begin == "" {
+				if err := s.broadcast(session); err != nil {
+					return err
+				}
+			}
+			var err error
+			if b, err = s.pick(stmt); err != nil {
+				return err
+			}
+		}
+	}
+	s.active = b
+
+	// Prepare on the database the statements the batch uses but were prepared on the other one
+	var inject []pgproto3.FrontendMessage
+	parsed := make(map[string]bool)
+	for _, m := range batch {
+		if m.kind == 'P' {
+			parsed[m.name] = true
+			continue
+		}
+		if (m.kind == 'B' || m.kind == 'D' && m.objectType == 'S') && !parsed[m.name] {
+			st, ok := s.statements[m.name]
+			if ok && b.prepared[m.name] != st.query {
+				inject = append(inject, &pgproto3.Parse{Name: m.name, Query: st.query, ParameterOIDs: st.oids})
+				parsed[m.name] = true
+			}
+		}
+	}
+	if len(inject) > 0 {
+		if err := b.waitIdle(); err != nil {
+			return err
+		}
+		b.mu.Lock()
+		for i := range inject {
+			b.dropParse[b.unsynced+i] = true
+		}
+		b.mu.Unlock()
+		b.unsynced += len(inject)
+		for _, msg := range inject {
+			parse := msg.(*pgproto3.Parse)
+			b.prepared[parse.Name] = parse.Query
+			data, err := msg.Encode(nil)
+			if err != nil {
+				return err
+			}
+			if _, err := b.w.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+
+	var closed []string
+	for _, m := range batch {
+		switch {
+		case m.kind == 'P':
+			b.prepared[m.name] = m.query
+			b.unsynced++
+		case m.kind == 'C' && m.objectType == 'S':
+			delete(b.prepared, m.name)
+			closed = append(closed, m.name)
+		case m.kind == 'S':
+			b.mu.Lock()
+			b.pending++
+			b.mu.Unlock()
+			b.unsynced = 0
+		}
+		if _, err := b.w.Write(m.data); err != nil {
+			return err
+		}
+	}
+	if err := b.w.Flush(); err != nil {
+		return err
+	}
+	s.inBatch = !sync
+
+	// Close the statements on the other database too
+	if len(closed) > 0 {
+		s.mu.Lock()
+		var others []*pgBackend
+		for _, o := range s.backends {
+			if o != b {
+				others = append(others, o)
+			}
+		}
+		s.mu.Unlock()
+		for _, o := range others {
+			var msgs []pgproto3.FrontendMessage
+			for _, name := range closed {
+				if _, ok := o.prepared[name]; ok {
+					delete(o.prepared, name)
+					msgs = append(msgs, &pgproto3.Close{ObjectType: 'S', Name: name})
+				}
+			}
+			if len(msgs) == 0 {
+				continue
+			}
+			o.mu.Lock()
+			o.discard++
+			o.mu.Unlock()
+			if err := o.send(append(msgs, &pgproto3.Sync{})...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Package proxy routes the statements of applications between the source and the target
+// database of a relationship during a gradual cutover. Applications connect to a proxy as they
+// would to the database, with its wire protocol (PostgreSQL or MySQL); the proxy connects to
+// both databases and runs each statement on one of them according to its routes, which can be
+// changed while sessions are open.
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// Protocol is the wire protocol a proxy speaks with applications and databases
+type Protocol string
+
+const (
+	ProtocolPostgres Protocol = "postgres"
+	ProtocolMySQL    Protocol = "mysql"
+)
+
+// Backend is the connection info of a database the proxy connects to
+type Backend struct {
+	DatabaseID   string
+	Host         string
+	Port         int
+	Username     string
+	Password     string
+	DatabaseName string
+	TLS          *tls.Config                               // nil without TLS
+	AuthToken    func(ctx context.Context) (string, error) // IAM tokens in place of Password, if set
+}
+
+func (b Backend) address() string {
+	return net.JoinHostPort(b.Host, fmt.Sprint(b.Port))
+}
+
+// Config is the configuration of a proxy
+type Config struct {
+	ID            string
+	Name          string
+	Protocol      Protocol
+	ListenAddress string // host:port the proxy listens on
+	Username      string // Credentials applications connect with
+	Password      string
+	Source        Backend
+	Target        Backend
+	Routes        Routes
+}
+
+// Stats are the activity of a proxy since it started
+type Stats struct {
+	ActiveSessions   int64
+	TotalSessions    int64
+	SourceStatements int64 // Statements run on the source database
+	TargetStatements int64 // Statements run on the target database
+	Started          time.Time
+}
+
+// Proxy accepts the sessions of applications and routes their statements
+type Proxy struct {
+	config   Config
+	routes   atomic.Pointer[Routes]
+	listener net.Listener
+	logger   *logger.Logger
+
+	mu       sync.Mutex
+	sessions map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+
+	activeSessions   atomic.Int64
+	totalSessions    atomic.Int64
+	sourceStatements atomic.Int64
+	targetStatements atomic.Int64
+	started          time.Time
+
+	// Version the MySQL source database greets clients with, repeated to applications
+	mysqlVersion string
+}
+
+// Start listens on the address of a proxy and serves its sessions until it is closed
+func Start(config Config, logger *logger.Logger) (*Proxy, error) {
+	if config.Protocol != ProtocolPostgres && config.Protocol != ProtocolMySQL {
+		return nil, fmt.Errorf("unsupported proxy protocol %q", config.Protocol)
+	}
+	if err := config.Routes.Validate(); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", config.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", config.ListenAddress, err)
+	}
+
+	p := &Proxy{
+		config:   config,
+		listener: listener,
+		logger:   logger,
+		sessions: make(map[net.Conn]struct{}),
+		started:  time.Now(),
+	}
+	routes := config.Routes
+	p.routes.Store(&routes)
+	if config.Protocol == ProtocolMySQL {
+		p.mysqlVersion = probeMySQLVersion(config.Source)
+	}
+
+	p.wg.Add(1)
+	go p.serve()
+	p.logf("Query proxy %s listening on %s", config.Name, listener.Addr())
+	return p, nil
+}
+
+func (p *Proxy) serve() {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				p.logf("Query proxy %s stopped accepting sessions: %v", p.config.Name, err)
+			}
+			return
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			conn.Close()
+			return
+		}
+		p.sessions[conn] = struct{}{}
+		p.wg.Add(1)
+		p.mu.Unlock()
+
+		go func() {
+			defer p.wg.Done()
+			defer func() {
+				p.mu.Lock()
+				delete(p.sessions, conn)
+				p.mu.Unlock()
+				conn.Close()
+			}()
+
+			p.activeSessions.Add(1)
+			p.totalSessions.Add(1)
+			defer p.activeSessions.Add(-1)
+
+			var err error
+			if p.config.Protocol == ProtocolPostgres {
+				err = p.servePostgres(conn)
+			} else {
+				err = p.serveMySQL(conn)
+			}
+			if err != nil && !errors.Is(err, net.ErrClosed) {
+				p.logf("Query proxy %s session from %s ended: %v", p.config.Name, conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// Close stops listening and closes the open sessions
+func (p *Proxy) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	err := p.listener.Close()
+	for conn := range p.sessions {
+		conn.Close()
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+// Routes returns the routes of the proxy
+func (p *Proxy) Routes() Routes {
+	return *p.routes.Load()
+}
+
+// SetRoutes replaces the routes of the proxy. Open sessions use them from their next statement
+// outside a transaction.
+func (p *Proxy) SetRoutes(routes Routes) error {
+	if err := routes.Validate(); err != nil {
+		return err
+	}
+	p.routes.Store(&routes)
+	return nil
+}
+
+// Stats returns the activity of the proxy
+func (p *Proxy) Stats() Stats {
+	return Stats{
+		ActiveSessions:   p.activeSessions.Load(),
+		TotalSessions:    p.totalSessions.Load(),
+		SourceStatements: p.sourceStatements.Load(),
+		TargetStatements: p.targetStatements.Load(),
+		Started:          p.started,
+	}
+}
+
+// Addr returns the address the proxy listens on
+func (p *Proxy) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// route returns the database a statement of a session runs on, and counts it
+func (p *Proxy) route(stmt Statement, ticket int) Database {
+	db := p.Routes().Route(stmt, ticket)
+	p.count(db)
+	return db
+}
+
+func (p *Proxy) count(db Database) {
+	if db == Target {
+		p.targetStatements.Add(1)
+	} else {
+		p.sourceStatements.Add(1)
+	}
+}
+
+func (p *Proxy) backend(db Database) Backend {
+	if db == Target {
+		return p.config.Target
+	}
+	return p.config.Source
+}
+
+func (p *Proxy) logf(format string, args ...interface{}) {
+	if p.logger != nil {
+		p.logger.Infof(format, args...)
+	}
+}
+
+// newTicket draws the ticket deciding, with the percentages of the routes, where the reads and
+// writes of a session run
+func newTicket() int {
+	return rand.Intn(100)
+}
+
+// Manager runs the proxies of the anchor
+type Manager struct {
+	mu      sync.Mutex
+	proxies map[string]*Proxy
+	logger  *logger.Logger
+}
+
+// NewManager creates a manager without proxies
+func NewManager(logger *logger.Logger) *Manager {
+	return &Manager{
+		proxies: make(map[string]*Proxy),
+		logger:  logger,
+	}
+}
+
+// Apply starts a proxy, or updates the routes of a running one. A running proxy whose listen
+// address, credentials or databases changed is restarted, closing its sessions.
+func (m *Manager) Apply(config Config) (*Proxy, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p, ok := m.proxies[config.ID]; ok {
+		if sameConfig(p.config, config) {
+			if err := p.SetRoutes(config.Routes); err != nil {
+				return nil, err
+			}
+			return p, nil
+		}
+		p.Close()
+		delete(m.proxies, config.ID)
+	}
+
+	p, err := Start(config, m.logger)
+	if err != nil {
+		return nil, err
+	}
+	m.proxies[config.ID] = p
+	return p, nil
+}
+
+// sameConfig reports whether two configurations differ only by their routes. The TLS
+// configurations and token functions of the databases are not compared.
+func sameConfig(a, b Config) bool {
+	sameBackend := func(x, y Backend) bool {
+		return x.DatabaseID == y.DatabaseID && x.Host == y.Host && x.Port == y.Port &&
+			x.Username == y.Username && x.Password == y.Password && x.DatabaseName == y.DatabaseName &&
+			(x.TLS == nil) == (y.TLS == nil)
+	}
+	return a.Name == b.Name && a.Protocol == b.Protocol && a.ListenAddress == b.ListenAddress &&
+		a.Username == b.Username && a.Password == b.Password &&
+		sameBackend(a.Source, b.Source) && sameBackend(a.Target, b.Target)
+}
+
+// Get returns a running proxy
+func (m *Manager) Get(id string) (*Proxy, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.proxies[id]
+	return p, ok
+}
+
+// Stop closes a proxy, and returns false if it was not running
+func (m *Manager) Stop(id string) bool {
+	m.mu.Lock()
+	p, ok := m.proxies[id]
+	delete(m.proxies, id)
+	m.mu.Unlock()
+
+	if ok {
+		p.Close()
+	}
+	return ok
+}
+
+// StopAll closes all proxies
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	proxies := m.proxies
+	m.proxies = make(map[string]*Proxy)
+	m.mu.Unlock()
+
+	for _, p := range proxies {
+		p.Close()
+	}
+}
@@ -0,0 +1,430 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Database is one of the two databases a proxy routes statements to
+type Database string
+
+const (
+	Source Database = "source" // The database the applications move away from
+	Target Database = "target" // The database the applications move to
+)
+
+// Routes decide the database each statement runs on.
+//
+// Every session draws a ticket between 0 and 99 when it connects. Its reads run on the target
+// database if the ticket is below ReadPercent, and its writes if it is below WritePercent, so
+// raising the percentages moves sessions to the target database for good, and a session
+// writing to the target database also reads from it as long as ReadPercent >= WritePercent.
+// Statements on a table of Tables run on the database of the table whatever the percentages.
+type Routes struct {
+	ReadPercent  int                 `json:"read_percent"`
+	WritePercent int                 `json:"write_percent"`
+	Tables       map[string]Database `json:"tables,omitempty"` // Keyed by table name, optionally qualified by its schema
+}
+
+// Validate checks the percentages and the databases of the tables of the routes
+func (r Routes) Validate() error {
+	if r.ReadPercent < 0 || r.ReadPercent > 100 {
+		return fmt.Errorf("read percent must be between 0 and 100")
+	}
+	if r.WritePercent < 0 || r.WritePercent > 100 {
+		return fmt.Errorf("write percent must be between 0 and 100")
+	}
+	for table, db := range r.Tables {
+		if db != Source && db != Target {
+			return fmt.Errorf("table %s is routed to %q, expected %s or %s", table, db, Source, Target)
+		}
+	}
+	return nil
+}
+
+// Route returns the database a statement of a session runs on
+func (r Routes) Route(stmt Statement, ticket int) Database {
+	for _, table := range stmt.Tables {
+		if db, ok := r.table(table); ok {
+			return db
+		}
+	}
+	percent := r.ReadPercent
+	if stmt.Kind != KindRead {
+		percent = r.WritePercent
+	}
+	if ticket < percent {
+		return Target
+	}
+	return Source
+}
+
+// table returns the database of a table. Names are compared with their schema when both the
+// route and the statement qualify them, and without it otherwise.
+func (r Routes) table(name string) (Database, bool) {
+	for key, db := range r.Tables {
+		key = strings.ToLower(key)
+		if key == name {
+			return db, true
+		}
+		if strings.Contains(key, ".") != strings.Contains(name, ".") && unqualified(key) == unqualified(name) {
+			return db, true
+		}
+	}
+	return "", false
+}
+
+func unqualified(name string) string {
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
+
+// StatementKind is what a statement does, as far as routing is concerned
+type StatementKind int
+
+const (
+	KindRead    StatementKind = iota // Reads rows
+	KindWrite                        // Changes rows or the schema, or anything not known to be a read
+	KindBegin                        // Starts a transaction
+	KindEnd                          // Commits or rolls back a transaction
+	KindSession                      // Changes the state of the session, e.g. SET or USE
+)
+
+// Statement is what the router knows about a query
+type Statement struct {
+	Kind   StatementKind
+	Tables []string // Tables referenced by the query, lower case, qualified as written
+	Tag    string   // First keyword of the query, upper case
+}
+
+// Keywords starting statements that only read
+var readKeywords = map[string]bool{
+	"SELECT": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true,
+	"VALUES": true, "TABLE": true, "FETCH": true,
+}
+
+// Keywords starting statements that change the state of the session rather than data
+var sessionKeywords = map[string]bool{
+	"SET": true, "RESET": true, "USE": true, "DISCARD": true, "LISTEN": true, "UNLISTEN": true,
+}
+
+// Keywords followed by the names of the tables a statement reads or writes
+var tableKeywords = map[string]bool{
+	"FROM": true, "JOIN": true, "INTO": true, "UPDATE": true, "TABLE": true, "USING": true,
+	"TRUNCATE": true, "COPY": true,
+}
+
+// Keywords that may follow a table name, and so are not its alias
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true,
+	"NATURAL": true, "ON": true, "USING": true, "GROUP": true, "ORDER": true, "HAVING": true, "LIMIT": true,
+	"OFFSET": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "SET": true, "VALUES": true,
+	"SELECT": true, "RETURNING": true, "FOR": true, "WINDOW": true, "AS": true, "FROM": true, "TO": true,
+	"WITH": true, "DEFAULT": true, "PARTITION": true, "LOCK": true, "OUTER": true, "STRAIGHT_JOIN": true,
+}
+
+// Classify tells what a query does and the tables it references. Queries made of several
+// statements are writes unless all of them are reads.
+func Classify(query string) Statement {
+	var stmts [][]token
+	var current []token
+	for _, t := range tokenize(query) {
+		if t.kind == tokenPunct && t.text == ";" {
+			if len(current) > 0 {
+				stmts = append(stmts, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, t)
+	}
+	if len(current) > 0 {
+		stmts = append(stmts, current)
+	}
+	if len(stmts) == 0 {
+		return Statement{Kind: KindRead}
+	}
+
+	result := classifyStatement(stmts[0])
+	if len(stmts) == 1 {
+		return result
+	}
+	for _, tokens := range stmts[1:] {
+		stmt := classifyStatement(tokens)
+		result.Tables = append(result.Tables, stmt.Tables...)
+		if stmt.Kind != KindRead {
+			result.Kind = KindWrite
+		}
+	}
+	if result.Kind != KindRead {
+		result.Kind = KindWrite
+	}
+	return result
+}
+
+func classifyStatement(tokens []token) Statement {
+	// Skip the parentheses of e.g. "(SELECT ...) UNION (SELECT ...)"
+	first := 0
+	for first < len(tokens) && tokens[first].text == "(" {
+		first++
+	}
+	if first == len(tokens) {
+		return Statement{Kind: KindRead}
+	}
+	keyword := tokens[first].upper()
+	stmt := Statement{Tag: keyword, Tables: tablesOf(tokens)}
+
+	switch {
+	case keyword == "BEGIN" || keyword == "START" && next(tokens, first) == "TRANSACTION":
+		stmt.Kind = KindBegin
+	case keyword == "COMMIT" || keyword == "END" || keyword == "ABORT":
+		stmt.Kind = KindEnd
+	case keyword == "ROLLBACK":
+		// ROLLBACK TO SAVEPOINT stays in the transaction
+		stmt.Kind = KindEnd
+		if next(tokens, first) == "TO" {
+			stmt.Kind = KindWrite
+		}
+	case sessionKeywords[keyword]:
+		stmt.Kind = KindSession
+	case keyword == "WITH":
+		stmt.Kind = KindRead
+		for _, t := range tokens[first+1:] {
+			switch t.upper() {
+			case "INSERT", "UPDATE", "DELETE", "MERGE":
+				stmt.Kind = KindWrite
+			}
+		}
+	case keyword == "COPY":
+		// COPY ... TO exports rows
+		stmt.Kind = KindWrite
+		for _, t := range tokens[first+1:] {
+			if t.upper() == "TO" {
+				stmt.Kind = KindRead
+			}
+		}
+	case readKeywords[keyword]:
+		stmt.Kind = KindRead
+	default:
+		stmt.Kind = KindWrite
+	}
+
+	// Reads locking rows or creating tables run where the writes do
+	if stmt.Kind == KindRead {
+		for i, t := range tokens {
+			switch t.upper() {
+			case "INTO":
+				if keyword == "SELECT" {
+					stmt.Kind = KindWrite
+				}
+			case "FOR":
+				switch next(tokens, i) {
+				case "UPDATE", "SHARE", "NO", "KEY":
+					stmt.Kind = KindWrite
+				}
+			case "LOCK":
+				if next(tokens, i) == "IN" {
+					stmt.Kind = KindWrite
+				}
+			}
+		}
+	}
+	return stmt
+}
+
+// tablesOf returns the names following the keywords that introduce tables
+func tablesOf(tokens []token) []string {
+	var tables []string
+	for i := 0; i < len(tokens); i++ {
+		keyword := tokens[i].upper()
+		if tokens[i].kind != tokenWord || !tableKeywords[keyword] {
+			continue
+		}
+		j := i + 1
+		for {
+			// Skip the modifiers between the keyword and the name
+			for j < len(tokens) && tokens[j].kind == tokenWord {
+				switch tokens[j].upper() {
+				case "ONLY", "LATERAL", "TABLE", "IGNORE", "LOW_PRIORITY", "QUICK":
+					j++
+					continue
+				case "IF":
+					j++
+					for j < len(tokens) && (tokens[j].upper() == "NOT" || tokens[j].upper() == "EXISTS") {
+						j++
+					}
+					continue
+				}
+				break
+			}
+			name, end := qualifiedName(tokens, j)
+			if name == "" || clauseKeywords[strings.ToUpper(name)] {
+				break
+			}
+			// COPY ... FROM STDIN reads rows sent by the client
+			if keyword == "FROM" && (name == "stdin" || name == "stdout") && tokens[j].kind == tokenWord {
+				break
+			}
+			// A name followed by a parenthesis is a function, e.g. FROM generate_series(1, 3)
+			if end < len(tokens) && tokens[end].text == "(" && keyword != "INTO" && keyword != "COPY" && keyword != "TABLE" {
+				break
+			}
+			tables = append(tables, name)
+			j = end
+
+			// Only FROM and USING take lists of tables, e.g. FROM a x, b AS y
+			if keyword != "FROM" && keyword != "USING" {
+				break
+			}
+			if j < len(tokens) && tokens[j].upper() == "AS" {
+				j++
+			}
+			if j < len(tokens) && (tokens[j].kind == tokenWord && !clauseKeywords[tokens[j].upper()] || tokens[j].kind == tokenQuoted) {
+				j++
+			}
+			if j >= len(tokens) || tokens[j].text != "," {
+				break
+			}
+			j++
+		}
+		i = j - 1
+	}
+	return tables
+}
+
+// qualifiedName reads a name made of identifiers separated by dots at tokens[i], and returns it
+// lower case with the index of the token following it
+func qualifiedName(tokens []token, i int) (string, int) {
+	var parts []string
+	for i < len(tokens) && (tokens[i].kind == tokenWord || tokens[i].kind == tokenQuoted) {
+		parts = append(parts, strings.ToLower(tokens[i].text))
+		i++
+		if i < len(tokens) && tokens[i].text == "." {
+			i++
+			continue
+		}
+		break
+	}
+	return strings.Join(parts, "."), i
+}
+
+// next returns the keyword following tokens[i], upper case
+func next(tokens []token, i int) string {
+	if i+1 < len(tokens) {
+		return tokens[i+1].upper()
+	}
+	return ""
+}
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // Keyword or identifier
+	tokenQuoted                  // Quoted identifier, without its quotes
+	tokenString                  // String or number literal
+	tokenPunct                   // Any other character
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) upper() string {
+	if t.kind != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// tokenize splits a query into tokens, dropping the comments
+func tokenize(query string) []token {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'':
+			end := closingQuote(query, i, '\'')
+			tokens = append(tokens, token{tokenString, ""})
+			i = end
+		case c == '"' || c == '`':
+			end := closingQuote(query, i, c)
+			text := query[i+1 : max(i+1, end-1)]
+			text = strings.ReplaceAll(text, string([]byte{c, c}), string(c))
+			tokens = append(tokens, token{tokenQuoted, text})
+			i = end
+		case c == '$' && dollarTag(query[i:]) != "":
+			tag := dollarTag(query[i:])
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				return append(tokens, token{tokenString, ""})
+			}
+			tokens = append(tokens, token{tokenString, ""})
+			i += len(tag) + end + len(tag)
+		case isWordChar(c):
+			start := i
+			for i < len(query) && (isWordChar(query[i]) || query[i] == '$') {
+				i++
+			}
+			if c >= '0' && c <= '9' {
+				tokens = append(tokens, token{tokenString, ""})
+			} else {
+				tokens = append(tokens, token{tokenWord, query[start:i]})
+			}
+		default:
+			tokens = append(tokens, token{tokenPunct, string(c)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// closingQuote returns the index following the quote closing the quoted text at query[start].
+// Doubled quotes are part of the text.
+func closingQuote(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] == '\\' && quote == '\'' {
+			i++
+			continue
+		}
+		if query[i] == quote {
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// dollarTag returns the tag opening a dollar-quoted string, e.g. "$$" or "$body$", or "" if the
+// text does not start with one
+func dollarTag(text string) string {
+	for i := 1; i < len(text); i++ {
+		if text[i] == '$' {
+			return text[:i+1]
+		}
+		if !isWordChar(text[i]) || i == 1 && text[i] >= '0' && text[i] <= '9' {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
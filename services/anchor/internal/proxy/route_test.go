@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		kind   StatementKind
+		tables []string
+	}{
+		{"select", "SELECT id, name FROM users WHERE id = $1", KindRead, []string{"users"}},
+		{"join", "select * from public.orders o join Customers c on c.id = o.customer_id", KindRead, []string{"public.orders", "customers"}},
+		{"table list", "SELECT * FROM a x, b AS y, c WHERE x.id = y.id", KindRead, []string{"a", "b", "c"}},
+		{"quoted", `SELECT * FROM "Sales"."Order Lines"`, KindRead, []string{"sales.order lines"}},
+		{"function", "SELECT * FROM generate_series(1, 3)", KindRead, nil},
+		{"subquery", "SELECT * FROM (SELECT * FROM users) u", KindRead, []string{"users"}},
+		{"locking read", "SELECT * FROM accounts WHERE id = 1 FOR UPDATE", KindWrite, []string{"accounts"}},
+		{"select into", "SELECT * INTO archive FROM events", KindWrite, []string{"archive", "events"}},
+		{"insert", "INSERT INTO orders (id) VALUES (1)", KindWrite, []string{"orders"}},
+		{"mysql insert", "INSERT IGNORE INTO `shop`.`orders` VALUES (1)", KindWrite, []string{"shop.orders"}},
+		{"update", "UPDATE ONLY users SET name = 'FROM x' WHERE id = 2", KindWrite, []string{"users"}},
+		{"delete", "DELETE FROM sessions USING users WHERE sessions.user_id = users.id", KindWrite, []string{"sessions", "users"}},
+		{"truncate", "TRUNCATE TABLE IF EXISTS logs", KindWrite, []string{"logs"}},
+		{"ddl", "CREATE INDEX idx ON users (name)", KindWrite, nil},
+		{"with read", "WITH recent AS (SELECT * FROM orders) SELECT * FROM recent", KindRead, []string{"orders", "recent"}},
+		{"with write", "WITH moved AS (DELETE FROM queue RETURNING *) INSERT INTO done SELECT * FROM moved", KindWrite, []string{"queue", "done", "moved"}},
+		{"copy in", "COPY items FROM STDIN", KindWrite, []string{"items"}},
+		{"copy out", "COPY items TO STDOUT", KindRead, []string{"items"}},
+		{"begin", "BEGIN", KindBegin, nil},
+		{"start transaction", "START TRANSACTION READ ONLY", KindBegin, nil},
+		{"commit", "commit", KindEnd, nil},
+		{"rollback", "ROLLBACK", KindEnd, nil},
+		{"rollback to savepoint", "ROLLBACK TO SAVEPOINT sp", KindWrite, nil},
+		{"set", "SET search_path TO app", KindSession, nil},
+		{"use", "USE shop", KindSession, nil},
+		{"comments", "/* FROM secret */ -- DELETE\nSELECT 1", KindRead, nil},
+		{"dollar quoted", "SELECT $$ FROM secret $$", KindRead, nil},
+		{"multiple reads", "SELECT 1 FROM a; SELECT 2 FROM b;", KindRead, []string{"a", "b"}},
+		{"multiple with write", "SELECT 1; UPDATE t SET x = 1", KindWrite, []string{"t"}},
+		{"empty", "  ;  ", KindRead, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := Classify(tt.query)
+			assert.Equal(t, tt.kind, stmt.Kind)
+			assert.Equal(t, tt.tables, stmt.Tables)
+		})
+	}
+}
+
+func TestRoutesRoute(t *testing.T) {
+	read := Statement{Kind: KindRead, Tables: []string{"users"}}
+	write := Statement{Kind: KindWrite, Tables: []string{"users"}}
+
+	t.Run("percentages", func(t *testing.T) {
+		routes := Routes{ReadPercent: 50, WritePercent: 10}
+		assert.Equal(t, Target, routes.Route(read, 49))
+		assert.Equal(t, Source, routes.Route(read, 50))
+		assert.Equal(t, Target, routes.Route(write, 9))
+		assert.Equal(t, Source, routes.Route(write, 10))
+	})
+
+	t.Run("all or nothing", func(t *testing.T) {
+		assert.Equal(t, Source, Routes{}.Route(read, 0))
+		assert.Equal(t, Target, Routes{ReadPercent: 100, WritePercent: 100}.Route(write, 99))
+	})
+
+	t.Run("pinned tables", func(t *testing.T) {
+		routes := Routes{
+			ReadPercent: 100, WritePercent: 100,
+			Tables: map[string]Database{"Users": Source, "billing.invoices": Source},
+		}
+		assert.Equal(t, Source, routes.Route(read, 0))
+		assert.Equal(t, Source, routes.Route(Statement{Kind: KindWrite, Tables: []string{"app.users"}}, 0))
+		assert.Equal(t, Source, routes.Route(Statement{Kind: KindRead, Tables: []string{"invoices"}}, 0))
+		assert.Equal(t, Target, routes.Route(Statement{Kind: KindRead, Tables: []string{"sales.invoices"}}, 0))
+		assert.Equal(t, Target, routes.Route(Statement{Kind: KindRead, Tables: []string{"orders"}}, 0))
+	})
+}
+
+func TestRoutesValidate(t *testing.T) {
+	assert.NoError(t, Routes{ReadPercent: 100, Tables: map[string]Database{"users": Target}}.Validate())
+	assert.Error(t, Routes{ReadPercent: 101}.Validate())
+	assert.Error(t, Routes{WritePercent: -1}.Validate())
+	assert.Error(t, Routes{Tables: map[string]Database{"users": "replica"}}.Validate())
+}
@@ -0,0 +1,58 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// CheckpointRepository persists the checkpoints of consumers by stream, in the configuration
+// database of the anchor
+type CheckpointRepository interface {
+	LoadStreamCheckpoints(ctx context.Context, streamID, consumerID, topic string) (map[string]string, error)
+	SaveStreamCheckpoints(ctx context.Context, streamID, consumerID, topic string, positions map[string]string) error
+}
+
+// SetCheckpointRepository sets the repository that the consumers of streams checkpoint to.
+// Without a repository, checkpoints only last as long as their consumer.
+func (m *Manager) SetCheckpointRepository(repo CheckpointRepository) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkpoints = repo
+}
+
+// Consumer returns a new consumer of a stream. Consumers of platforms that leave checkpointing
+// to the application resume from the checkpoints of the stream in the checkpoint repository.
+func (m *Manager) Consumer(streamID string) (streamadapter.ConsumerOperator, error) {
+	conn, err := m.Get(streamID)
+	if err != nil {
+		return nil, err
+	}
+	consumer := conn.ConsumerOperations()
+	if consumer == nil {
+		return nil, fmt.Errorf("stream %s does not support consuming", streamID)
+	}
+
+	m.mu.Lock()
+	repo := m.checkpoints
+	m.mu.Unlock()
+	if checkpointed, ok := consumer.(streamadapter.CheckpointedConsumer); ok && repo != nil {
+		checkpointed.SetCheckpointStore(&checkpointStore{repo: repo, streamID: streamID})
+	}
+	return consumer, nil
+}
+
+// checkpointStore is the checkpoint store of the consumers of a stream
+type checkpointStore struct {
+	repo     CheckpointRepository
+	streamID string
+}
+
+func (s *checkpointStore) LoadCheckpoints(ctx context.Context, consumerID, topic string) (map[string]string, error) {
+	return s.repo.LoadStreamCheckpoints(ctx, s.streamID, consumerID, topic)
+}
+
+func (s *checkpointStore) SaveCheckpoints(ctx context.Context, consumerID, topic string, positions map[string]string) error {
+	return s.repo.SaveStreamCheckpoints(ctx, s.streamID, consumerID, topic, positions)
+}
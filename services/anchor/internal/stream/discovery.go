@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// DiscoverSchema lists the topics of a stream platform into a unified model holding a stream
+// per topic. The model carries the platform as its database type.
+func DiscoverSchema(ctx context.Context, conn streamadapter.Connection) (*unifiedmodel.UnifiedModel, error) {
+	admin := conn.AdminOperations()
+	if admin == nil {
+		return nil, fmt.Errorf("%s does not support listing topics", conn.Type())
+	}
+	topics, err := admin.ListTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	um := unifiedmodel.NewUnifiedModel(dbcapabilities.DatabaseType(conn.Type()))
+	for _, topic := range topics {
+		um.Streams[topic.Name] = topicStream(string(conn.Type()), topic)
+	}
+	return um, nil
+}
+
+// topicStream describes a topic as a stream of the unified model. The retention and cleanup
+// policy of the topic are lifted out of its configuration.
+func topicStream(platform string, topic streamadapter.TopicInfo) unifiedmodel.Stream {
+	options := map[string]any{
+		"platform":           platform,
+		"partitions":         topic.Partitions,
+		"replication_factor": topic.Replicas,
+	}
+	if retention, err := strconv.ParseInt(topic.Config["retention.ms"], 10, 64); err == nil {
+		options["retention_ms"] = retention
+	}
+	if policy := topic.Config["cleanup.policy"]; policy != "" {
+		options["cleanup_policy"] = policy
+	}
+	if len(topic.Config) > 0 {
+		options["config"] = topic.Config
+	}
+	return unifiedmodel.Stream{Name: topic.Name, Options: options}
+}
+
+// TopicSummary returns the metadata of a stream recording its discovered topics
+func TopicSummary(um *unifiedmodel.UnifiedModel) map[string]interface{} {
+	topics := make(map[string]interface{}, len(um.Streams))
+	for name, s := range um.Streams {
+		topics[name] = map[string]interface{}{
+			"partitions":         s.Options["partitions"],
+			"replication_factor": s.Options["replication_factor"],
+		}
+	}
+	return map[string]interface{}{
+		"topic_count": len(um.Streams),
+		"topics":      topics,
+	}
+}
@@ -0,0 +1,35 @@
+package stream
+
+import (
+	"testing"
+
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+func TestTopicStream(t *testing.T) {
+	s := topicStream("kafka", streamadapter.TopicInfo{
+		Name:       "orders",
+		Partitions: 6,
+		Replicas:   3,
+		Config:     map[string]string{"retention.ms": "604800000", "cleanup.policy": "compact"},
+	})
+
+	if s.Name != "orders" {
+		t.Errorf("Name = %q", s.Name)
+	}
+	if s.Options["partitions"] != int32(6) || s.Options["replication_factor"] != int32(3) {
+		t.Errorf("partitions = %v, replication factor = %v", s.Options["partitions"], s.Options["replication_factor"])
+	}
+	if s.Options["retention_ms"] != int64(604800000) || s.Options["cleanup_policy"] != "compact" {
+		t.Errorf("retention = %v, cleanup policy = %v", s.Options["retention_ms"], s.Options["cleanup_policy"])
+	}
+
+	// Topics whose configuration could not be described only carry their layout
+	s = topicStream("kafka", streamadapter.TopicInfo{Name: "events", Partitions: 1, Replicas: 1})
+	if _, ok := s.Options["retention_ms"]; ok {
+		t.Errorf("retention_ms = %v, want none", s.Options["retention_ms"])
+	}
+	if _, ok := s.Options["config"]; ok {
+		t.Errorf("config = %v, want none", s.Options["config"])
+	}
+}
@@ -0,0 +1,191 @@
+// Package kafka implements the stream adapter of Kafka and Kafka-compatible platforms with
+// kafka-go: producing to topics, consuming with consumer groups and administering topics.
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// Adapter implements the StreamAdapter interface for Kafka
+type Adapter struct{}
+
+// NewAdapter creates a Kafka adapter
+func NewAdapter() *Adapter {
+	return &Adapter{}
+}
+
+func (a *Adapter) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.Kafka
+}
+
+func (a *Adapter) Capabilities() streamcapabilities.Capability {
+	cap, _ := streamcapabilities.Get(streamcapabilities.Kafka)
+	return cap
+}
+
+// Connect checks that the brokers can be reached with the configured TLS and SASL settings
+// by fetching the metadata of the cluster
+func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker is required")
+	}
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	mechanism, err := saslMechanism(config.SASLMechanism, config.Username, config.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	connectTimeout := config.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = 30 * time.Second
+	}
+	transport := &kafka.Transport{
+		DialTimeout: connectTimeout,
+		ClientID:    "redb-anchor",
+		TLS:         tlsConfig,
+		SASL:        mechanism,
+	}
+
+	conn := &Connection{
+		id:        config.ID,
+		config:    config,
+		tlsConfig: tlsConfig,
+		mechanism: mechanism,
+		transport: transport,
+		client: &kafka.Client{
+			Addr:      kafka.TCP(config.Brokers...),
+			Timeout:   config.RequestTimeout,
+			Transport: transport,
+		},
+	}
+	if err := conn.Ping(ctx); err != nil {
+		transport.CloseIdleConnections()
+		return nil, err
+	}
+	conn.connected = true
+	return conn, nil
+}
+
+// Connection is a connection to a Kafka cluster. Its producer is shared by the callers of
+// ProducerOperations, while each consumer has its own reader.
+type Connection struct {
+	id        string
+	config    adapter.ConnectionConfig
+	tlsConfig *tls.Config // nil without TLS; client certificates for mutual TLS
+	mechanism sasl.Mechanism
+	transport *kafka.Transport
+	client    *kafka.Client
+
+	mu        sync.Mutex
+	connected bool
+	producer  *Producer
+}
+
+func (c *Connection) ID() string {
+	return c.id
+}
+
+func (c *Connection) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.Kafka
+}
+
+func (c *Connection) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Ping fetches the brokers of the cluster
+func (c *Connection) Ping(ctx context.Context) error {
+	if _, err := c.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{}}); err != nil {
+		return fmt.Errorf("failed to reach kafka brokers %s: %w", c.config.GetBrokerString(), err)
+	}
+	return nil
+}
+
+// Close flushes and closes the producer, and closes the connections to the brokers
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	producer := c.producer
+	c.producer = nil
+	c.connected = false
+	c.mu.Unlock()
+
+	var err error
+	if producer != nil {
+		err = producer.Close()
+	}
+	c.transport.CloseIdleConnections()
+	return err
+}
+
+func (c *Connection) ProducerOperations() adapter.ProducerOperator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.producer == nil {
+		c.producer = newProducer(c)
+	}
+	return c.producer
+}
+
+func (c *Connection) ConsumerOperations() adapter.ConsumerOperator {
+	return &Consumer{conn: c}
+}
+
+func (c *Connection) AdminOperations() adapter.AdminOperator {
+	return &Admin{conn: c}
+}
+
+// Raw returns the kafka-go client of the connection
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+func (c *Connection) Adapter() adapter.StreamAdapter {
+	return &Adapter{}
+}
+
+// dialer builds the kafka-go dialer of consumers from the connection's TLS and SASL settings
+func (c *Connection) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       c.transport.DialTimeout,
+		DualStack:     true,
+		ClientID:      c.transport.ClientID,
+		TLS:           c.tlsConfig,
+		SASLMechanism: c.mechanism,
+	}
+}
+
+func saslMechanism(name, username, password string) (sasl.Mechanism, error) {
+	switch strings.ToUpper(name) {
+	case "":
+		return nil, nil
+	case "PLAIN":
+		return plain.Mechanism{Username: username, Password: password}, nil
+	case "SCRAM-SHA-256":
+		return scram.Mechanism(scram.SHA256, username, password)
+	case "SCRAM-SHA-512":
+		return scram.Mechanism(scram.SHA512, username, password)
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism: %s", name)
+	}
+}
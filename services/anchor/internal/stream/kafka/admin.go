@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/segmentio/kafka-go"
+)
+
+// Admin reads the metadata and configuration of topics, and creates and deletes them
+type Admin struct {
+	conn *Connection
+}
+
+// ListTopics returns the topics of the cluster, without its internal topics
+func (a *Admin) ListTopics(ctx context.Context) ([]adapter.TopicInfo, error) {
+	metadata, err := a.conn.client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	var names []string
+	topics := make(map[string]kafka.Topic, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if topic.Internal || topic.Error != nil {
+			continue
+		}
+		names = append(names, topic.Name)
+		topics[topic.Name] = topic
+	}
+	sort.Strings(names)
+
+	configs, err := a.describeConfigs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]adapter.TopicInfo, 0, len(names))
+	for _, name := range names {
+		topic := topics[name]
+		info := adapter.TopicInfo{
+			Name:       name,
+			Partitions: int32(len(topic.Partitions)),
+			Config:     configs[name],
+		}
+		if len(topic.Partitions) > 0 {
+			info.Replicas = int32(len(topic.Partitions[0].Replicas))
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// CreateTopic creates a topic. RetentionMs sets retention.ms unless Config sets it.
+func (a *Admin) CreateTopic(ctx context.Context, name string, config adapter.TopicConfig) error {
+	topic := kafka.TopicConfig{
+		Topic:             name,
+		NumPartitions:     int(config.NumPartitions),
+		ReplicationFactor: int(config.ReplicationFactor),
+	}
+	// -1 leaves the partitions and replication factor to the defaults of the brokers
+	if topic.NumPartitions <= 0 {
+		topic.NumPartitions = -1
+	}
+	if topic.ReplicationFactor <= 0 {
+		topic.ReplicationFactor = -1
+	}
+	for k, v := range config.Config {
+		topic.ConfigEntries = append(topic.ConfigEntries, kafka.ConfigEntry{ConfigName: k, ConfigValue: v})
+	}
+	if _, ok := config.Config["retention.ms"]; !ok && config.RetentionMs != 0 {
+		topic.ConfigEntries = append(topic.ConfigEntries, kafka.ConfigEntry{
+			ConfigName:  "retention.ms",
+			ConfigValue: strconv.FormatInt(config.RetentionMs, 10),
+		})
+	}
+
+	resp, err := a.conn.client.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: []kafka.TopicConfig{topic}})
+	if err == nil {
+		err = resp.Errors[name]
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteTopic deletes a topic
+func (a *Admin) DeleteTopic(ctx context.Context, name string) error {
+	resp, err := a.conn.client.DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{name}})
+	if err == nil {
+		err = resp.Errors[name]
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete topic %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetTopicMetadata returns the partitions of a topic with their brokers and offsets
+func (a *Admin) GetTopicMetadata(ctx context.Context, topic string) (adapter.TopicMetadata, error) {
+	metadata, err := a.conn.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to get metadata of topic %s: %w", topic, err)
+	}
+	if len(metadata.Topics) == 0 {
+		return adapter.TopicMetadata{}, fmt.Errorf("topic %s not found", topic)
+	}
+	t := metadata.Topics[0]
+	if t.Error != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to get metadata of topic %s: %w", topic, t.Error)
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(t.Partitions))
+	for _, p := range t.Partitions {
+		requests = append(requests, kafka.FirstOffsetOf(p.ID), kafka.LastOffsetOf(p.ID))
+	}
+	offsets, err := a.conn.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: requests},
+	})
+	if err != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to get offsets of topic %s: %w", topic, err)
+	}
+	byPartition := make(map[int]kafka.PartitionOffsets)
+	for _, po := range offsets.Topics[topic] {
+		byPartition[po.Partition] = po
+	}
+
+	configs, err := a.describeConfigs(ctx, []string{topic})
+	if err != nil {
+		return adapter.TopicMetadata{}, err
+	}
+
+	result := adapter.TopicMetadata{
+		Name:     topic,
+		Config:   configs[topic],
+		Metadata: map[string]interface{}{"internal": t.Internal},
+	}
+	for _, p := range t.Partitions {
+		result.Partitions = append(result.Partitions, adapter.PartitionMetadata{
+			ID:       int32(p.ID),
+			Leader:   brokerAddr(p.Leader),
+			Replicas: brokerAddrs(p.Replicas),
+			ISR:      brokerAddrs(p.Isr),
+			Offset: adapter.OffsetInfo{
+				Oldest: byPartition[p.ID].FirstOffset,
+				Newest: byPartition[p.ID].LastOffset,
+			},
+		})
+	}
+	sort.Slice(result.Partitions, func(i, j int) bool { return result.Partitions[i].ID < result.Partitions[j].ID })
+	return result, nil
+}
+
+// GetTopicConfig returns the partitions, replication factor and configuration of a topic
+func (a *Admin) GetTopicConfig(ctx context.Context, topic string) (adapter.TopicConfig, error) {
+	metadata, err := a.GetTopicMetadata(ctx, topic)
+	if err != nil {
+		return adapter.TopicConfig{}, err
+	}
+	config := adapter.TopicConfig{
+		NumPartitions: int32(len(metadata.Partitions)),
+		Config:        metadata.Config,
+	}
+	if len(metadata.Partitions) > 0 {
+		config.ReplicationFactor = int32(len(metadata.Partitions[0].Replicas))
+	}
+	if retention, err := strconv.ParseInt(metadata.Config["retention.ms"], 10, 64); err == nil {
+		config.RetentionMs = retention
+	}
+	return config, nil
+}
+
+// describeConfigs returns the configuration entries of topics by topic name
+func (a *Admin) describeConfigs(ctx context.Context, topics []string) (map[string]map[string]string, error) {
+	configs := make(map[string]map[string]string, len(topics))
+	if len(topics) == 0 {
+		return configs, nil
+	}
+
+	resources := make([]kafka.DescribeConfigRequestResource, len(topics))
+	for i, topic := range topics {
+		resources[i] = kafka.DescribeConfigRequestResource{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic,
+		}
+	}
+	resp, err := a.conn.client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{Resources: resources})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topic configurations: %w", err)
+	}
+
+	for _, resource := range resp.Resources {
+		if resource.Error != nil {
+			continue
+		}
+		config := make(map[string]string, len(resource.ConfigEntries))
+		for _, entry := range resource.ConfigEntries {
+			if !entry.IsSensitive {
+				config[entry.ConfigName] = entry.ConfigValue
+			}
+		}
+		configs[resource.ResourceName] = config
+	}
+	return configs, nil
+}
+
+func brokerAddr(b kafka.Broker) string {
+	if b.Host == "" {
+		return strconv.Itoa(b.ID)
+	}
+	return fmt.Sprintf("%s:%d", b.Host, b.Port)
+}
+
+func brokerAddrs(brokers []kafka.Broker) []string {
+	addrs := make([]string, len(brokers))
+	for i, b := range brokers {
+		addrs[i] = brokerAddr(b)
+	}
+	return addrs
+}
@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/segmentio/kafka-go"
+)
+
+// Consumer reads from Kafka with a kafka-go reader.
+// With auto-commit disabled, offsets are only committed when Commit is called, which
+// lets adapter.CommitCoordinator commit after a batch has been applied to the target.
+type Consumer struct {
+	conn *Connection
+
+	mu          sync.Mutex
+	reader      *kafka.Reader
+	grouped     bool
+	uncommitted map[string]kafka.Message // topic/partition -> highest handled message not yet committed
+	floors      map[string]int64         // topic/partition -> first offset to deliver, set by Seek
+}
+
+func (c *Consumer) Subscribe(ctx context.Context, topics []string, groupID string) error {
+	if len(topics) == 0 {
+		return fmt.Errorf("at least one topic required")
+	}
+	if groupID == "" && len(topics) > 1 {
+		return fmt.Errorf("a consumer group is required to consume more than one topic")
+	}
+
+	readerConfig := kafka.ReaderConfig{
+		Brokers:     c.conn.config.Brokers,
+		Dialer:      c.conn.dialer(),
+		StartOffset: kafka.LastOffset,
+		MaxBytes:    c.conn.config.MaxMessageSize,
+	}
+	if c.conn.config.AutoOffsetReset == "earliest" {
+		readerConfig.StartOffset = kafka.FirstOffset
+	}
+	if groupID != "" {
+		readerConfig.GroupID = groupID
+		readerConfig.GroupTopics = topics
+		if c.conn.config.EnableAutoCommit {
+			readerConfig.CommitInterval = time.Second
+		}
+	} else {
+		readerConfig.Topic = topics[0]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reader != nil {
+		c.reader.Close()
+	}
+	c.reader = kafka.NewReader(readerConfig)
+	c.grouped = groupID != ""
+	c.uncommitted = make(map[string]kafka.Message)
+	c.floors = make(map[string]int64)
+	return nil
+}
+
+func (c *Consumer) Consume(ctx context.Context, handler adapter.MessageHandler) error {
+	c.mu.Lock()
+	reader := c.reader
+	c.mu.Unlock()
+	if reader == nil {
+		return fmt.Errorf("not subscribed to any topic")
+	}
+
+	for {
+		var msg kafka.Message
+		var err error
+		if c.conn.config.EnableAutoCommit {
+			msg, err = reader.ReadMessage(ctx)
+		} else {
+			msg, err = reader.FetchMessage(ctx)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		key := partitionKey(msg.Topic, msg.Partition)
+		c.mu.Lock()
+		floor, seeked := c.floors[key]
+		c.mu.Unlock()
+		if seeked && msg.Offset < floor {
+			continue
+		}
+
+		if err := handler(ctx, convertMessage(msg)); err != nil {
+			return err
+		}
+
+		// Only messages the handler has accepted are committed by the next Commit
+		if !c.conn.config.EnableAutoCommit {
+			c.mu.Lock()
+			if prev, ok := c.uncommitted[key]; !ok || msg.Offset > prev.Offset {
+				c.uncommitted[key] = msg
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *Consumer) Commit(ctx context.Context) error {
+	c.mu.Lock()
+	reader := c.reader
+	messages := make([]kafka.Message, 0, len(c.uncommitted))
+	for _, msg := range c.uncommitted {
+		messages = append(messages, msg)
+	}
+	c.mu.Unlock()
+
+	if reader == nil || len(messages) == 0 {
+		return nil
+	}
+	if err := reader.CommitMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+
+	c.mu.Lock()
+	for _, msg := range messages {
+		key := partitionKey(msg.Topic, msg.Partition)
+		if current, ok := c.uncommitted[key]; ok && current.Offset == msg.Offset {
+			delete(c.uncommitted, key)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Seek positions the consumer at offset. Consumer group readers cannot be repositioned
+// by kafka-go, so earlier redelivered messages of the partition are skipped instead.
+func (c *Consumer) Seek(ctx context.Context, topic string, partition int32, offset int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reader == nil {
+		return fmt.Errorf("not subscribed to any topic")
+	}
+	c.floors[partitionKey(topic, int(partition))] = offset
+	if c.grouped {
+		return nil
+	}
+	return c.reader.SetOffset(offset)
+}
+
+func (c *Consumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reader == nil {
+		return nil
+	}
+	err := c.reader.Close()
+	c.reader = nil
+	return err
+}
+
+func convertMessage(msg kafka.Message) *adapter.Message {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return &adapter.Message{
+		Topic:     msg.Topic,
+		Partition: int32(msg.Partition),
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Timestamp: msg.Time,
+	}
+}
+
+func partitionKey(topic string, partition int) string {
+	return fmt.Sprintf("%s/%d", topic, partition)
+}
@@ -0,0 +1,129 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/segmentio/kafka-go"
+)
+
+// Producer writes to Kafka with a kafka-go writer. Messages are assigned to partitions by a
+// hash of their key, so the messages of a key keep their order.
+type Producer struct {
+	writer  *kafka.Writer
+	pending sync.WaitGroup // ProduceAsync calls in flight
+	err     error          // invalid acks or compression settings
+}
+
+func newProducer(conn *Connection) *Producer {
+	config := conn.config
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(config.Brokers...),
+		Transport:    conn.transport,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Produce blocks until its batch is written, so batches are not held back waiting
+		// for more messages
+		BatchTimeout: 10 * time.Millisecond,
+	}
+	if config.RequestTimeout > 0 {
+		writer.WriteTimeout = config.RequestTimeout
+	}
+	if config.MaxMessageSize > 0 {
+		writer.BatchBytes = int64(config.MaxMessageSize)
+	}
+
+	p := &Producer{writer: writer}
+	if config.Acks != "" {
+		if err := writer.RequiredAcks.UnmarshalText([]byte(acksName(config.Acks))); err != nil {
+			p.err = fmt.Errorf("invalid acks setting: %w", err)
+		}
+	}
+	if config.Compression != "" {
+		if err := writer.Compression.UnmarshalText([]byte(config.Compression)); err != nil {
+			p.err = fmt.Errorf("invalid compression setting: %w", err)
+		}
+	}
+	return p
+}
+
+// acksName maps the 0, 1 and all acks settings of producers to the names of kafka-go
+func acksName(acks string) string {
+	switch acks {
+	case "0":
+		return "none"
+	case "1":
+		return "one"
+	case "-1":
+		return "all"
+	}
+	return acks
+}
+
+// Produce writes messages to a topic and returns once the brokers acknowledged them
+func (p *Producer) Produce(ctx context.Context, topic string, messages []adapter.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	batch := make([]kafka.Message, len(messages))
+	for i, msg := range messages {
+		batch[i] = kafka.Message{
+			Topic: topic,
+			Key:   msg.Key,
+			Value: msg.Value,
+			Time:  msg.Timestamp,
+		}
+		for k, v := range msg.Headers {
+			batch[i].Headers = append(batch[i].Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+	}
+
+	if err := p.writer.WriteMessages(ctx, batch...); err != nil {
+		return fmt.Errorf("failed to produce to topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// ProduceAsync writes messages in the background and calls callback with the result
+func (p *Producer) ProduceAsync(ctx context.Context, topic string, messages []adapter.Message, callback func(error)) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		err := p.Produce(ctx, topic, messages)
+		if callback != nil {
+			callback(err)
+		}
+	}()
+	return nil
+}
+
+// Flush waits for the messages of ProduceAsync calls to be written
+func (p *Producer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for the messages in flight and closes the writer
+func (p *Producer) Close() error {
+	p.pending.Wait()
+	return p.writer.Close()
+}
@@ -0,0 +1,149 @@
+// Package kinesis implements the stream adapter of AWS Kinesis Data Streams: producing to
+// streams in batches, consuming their shards with checkpoints and administering streams.
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+)
+
+// Adapter implements the StreamAdapter interface for Kinesis
+type Adapter struct{}
+
+// NewAdapter creates a Kinesis adapter
+func NewAdapter() *Adapter {
+	return &Adapter{}
+}
+
+func (a *Adapter) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.Kinesis
+}
+
+func (a *Adapter) Capabilities() streamcapabilities.Capability {
+	cap, _ := streamcapabilities.Get(streamcapabilities.Kinesis)
+	return cap
+}
+
+// Connect creates a Kinesis client for the region of the configuration and checks that streams
+// can be listed. The access_key_id, secret_access_key and session_token settings take
+// precedence over the default credential chain of the anchor, and endpoint overrides the
+// endpoint of the region (for VPC endpoints or LocalStack).
+func (a *Adapter) Connect(ctx context.Context, cfg adapter.ConnectionConfig) (adapter.Connection, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("region is required")
+	}
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}
+	if keyID, secret := cfg.Configuration["access_key_id"], cfg.Configuration["secret_access_key"]; keyID != "" && secret != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(keyID, secret, cfg.Configuration["session_token"])))
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := kinesis.NewFromConfig(awsConfig, func(o *kinesis.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+	})
+
+	conn := &Connection{
+		id:     cfg.ID,
+		config: cfg,
+		client: client,
+	}
+	if err := conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+	conn.connected = true
+	return conn, nil
+}
+
+// Connection is a connection to the Kinesis streams of a region. Its producer is shared by the
+// callers of ProducerOperations, while each consumer reads its own shards.
+type Connection struct {
+	id     string
+	config adapter.ConnectionConfig
+	client *kinesis.Client
+
+	mu        sync.Mutex
+	connected bool
+	producer  *Producer
+}
+
+func (c *Connection) ID() string {
+	return c.id
+}
+
+func (c *Connection) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.Kinesis
+}
+
+func (c *Connection) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Ping lists a stream of the region
+func (c *Connection) Ping(ctx context.Context) error {
+	if _, err := c.client.ListStreams(ctx, &kinesis.ListStreamsInput{Limit: aws.Int32(1)}); err != nil {
+		return fmt.Errorf("failed to reach kinesis in %s: %w", c.config.Region, err)
+	}
+	return nil
+}
+
+// Close waits for the records of the producer in flight. AWS clients hold no connections
+// that need closing.
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	producer := c.producer
+	c.producer = nil
+	c.connected = false
+	c.mu.Unlock()
+
+	if producer != nil {
+		return producer.Close()
+	}
+	return nil
+}
+
+func (c *Connection) ProducerOperations() adapter.ProducerOperator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.producer == nil {
+		c.producer = &Producer{conn: c}
+	}
+	return c.producer
+}
+
+func (c *Connection) ConsumerOperations() adapter.ConsumerOperator {
+	return newConsumer(c)
+}
+
+func (c *Connection) AdminOperations() adapter.AdminOperator {
+	return &Admin{conn: c}
+}
+
+// Raw returns the Kinesis client of the connection
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+func (c *Connection) Adapter() adapter.StreamAdapter {
+	return &Adapter{}
+}
@@ -0,0 +1,184 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// Kinesis keeps records for 24 hours unless the retention of a stream is increased
+const defaultRetentionHours = 24
+
+// Admin administers the streams of a region. Streams are the topics of Kinesis, and their open
+// shards its partitions.
+type Admin struct {
+	conn *Connection
+}
+
+// ListTopics returns the streams of the region with their open shards and retention
+func (a *Admin) ListTopics(ctx context.Context) ([]adapter.TopicInfo, error) {
+	var names []string
+	input := &kinesis.ListStreamsInput{Limit: aws.Int32(100)}
+	for {
+		output, err := a.conn.client.ListStreams(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list streams: %w", err)
+		}
+		names = append(names, output.StreamNames...)
+		if !aws.ToBool(output.HasMoreStreams) || output.NextToken == nil {
+			break
+		}
+		input = &kinesis.ListStreamsInput{Limit: aws.Int32(100), NextToken: output.NextToken}
+	}
+	sort.Strings(names)
+
+	topics := make([]adapter.TopicInfo, 0, len(names))
+	for _, name := range names {
+		summary, err := a.describe(ctx, name)
+		if err != nil {
+			// Streams being deleted cannot be described
+			continue
+		}
+		topics = append(topics, adapter.TopicInfo{
+			Name:       name,
+			Partitions: aws.ToInt32(summary.OpenShardCount),
+			Config:     streamConfig(summary),
+		})
+	}
+	return topics, nil
+}
+
+// CreateTopic creates a stream with NumPartitions shards, or an on-demand stream without
+// NumPartitions. A retention over 24 hours is set once the stream is active.
+func (a *Admin) CreateTopic(ctx context.Context, name string, config adapter.TopicConfig) error {
+	input := &kinesis.CreateStreamInput{
+		StreamName:        aws.String(name),
+		StreamModeDetails: &types.StreamModeDetails{StreamMode: types.StreamModeOnDemand},
+	}
+	if config.NumPartitions > 0 {
+		input.ShardCount = aws.Int32(config.NumPartitions)
+		input.StreamModeDetails.StreamMode = types.StreamModeProvisioned
+	}
+	if _, err := a.conn.client.CreateStream(ctx, input); err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", name, err)
+	}
+
+	hours := int32(config.RetentionMs / time.Hour.Milliseconds())
+	if hours <= defaultRetentionHours {
+		return nil
+	}
+	waiter := kinesis.NewStreamExistsWaiter(a.conn.client)
+	if err := waiter.Wait(ctx, &kinesis.DescribeStreamInput{StreamName: aws.String(name)}, 5*time.Minute); err != nil {
+		return fmt.Errorf("stream %s was created but did not become active: %w", name, err)
+	}
+	if _, err := a.conn.client.IncreaseStreamRetentionPeriod(ctx, &kinesis.IncreaseStreamRetentionPeriodInput{
+		StreamName:           aws.String(name),
+		RetentionPeriodHours: aws.Int32(hours),
+	}); err != nil {
+		return fmt.Errorf("stream %s was created but its retention could not be set: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteTopic deletes a stream along with its registered consumers
+func (a *Admin) DeleteTopic(ctx context.Context, name string) error {
+	if _, err := a.conn.client.DeleteStream(ctx, &kinesis.DeleteStreamInput{
+		StreamName:              aws.String(name),
+		EnforceConsumerDeletion: aws.Bool(true),
+	}); err != nil {
+		return fmt.Errorf("failed to delete stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetTopicMetadata returns the shards of a stream as its partitions, with the shard ID as
+// their leader
+func (a *Admin) GetTopicMetadata(ctx context.Context, topic string) (adapter.TopicMetadata, error) {
+	summary, err := a.describe(ctx, topic)
+	if err != nil {
+		return adapter.TopicMetadata{}, err
+	}
+
+	var shards []types.Shard
+	input := &kinesis.ListShardsInput{StreamName: aws.String(topic)}
+	for {
+		output, err := a.conn.client.ListShards(ctx, input)
+		if err != nil {
+			return adapter.TopicMetadata{}, fmt.Errorf("failed to list shards of stream %s: %w", topic, err)
+		}
+		shards = append(shards, output.Shards...)
+		if output.NextToken == nil {
+			break
+		}
+		input = &kinesis.ListShardsInput{NextToken: output.NextToken}
+	}
+
+	metadata := adapter.TopicMetadata{
+		Name:   topic,
+		Config: streamConfig(summary),
+		Metadata: map[string]interface{}{
+			"stream_arn":    aws.ToString(summary.StreamARN),
+			"stream_status": string(summary.StreamStatus),
+		},
+	}
+	for i, shard := range shards {
+		partition := adapter.PartitionMetadata{
+			ID:     int32(i),
+			Leader: aws.ToString(shard.ShardId),
+		}
+		if shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber != nil {
+			// Closed shards are kept until their records expire
+			partition.Leader += " (closed)"
+		}
+		metadata.Partitions = append(metadata.Partitions, partition)
+	}
+	return metadata, nil
+}
+
+// GetTopicConfig returns the open shards and retention of a stream
+func (a *Admin) GetTopicConfig(ctx context.Context, topic string) (adapter.TopicConfig, error) {
+	summary, err := a.describe(ctx, topic)
+	if err != nil {
+		return adapter.TopicConfig{}, err
+	}
+	return adapter.TopicConfig{
+		NumPartitions: aws.ToInt32(summary.OpenShardCount),
+		RetentionMs:   int64(aws.ToInt32(summary.RetentionPeriodHours)) * time.Hour.Milliseconds(),
+		Config:        streamConfig(summary),
+	}, nil
+}
+
+func (a *Admin) describe(ctx context.Context, name string) (*types.StreamDescriptionSummary, error) {
+	output, err := a.conn.client.DescribeStreamSummary(ctx, &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe stream %s: %w", name, err)
+	}
+	return output.StreamDescriptionSummary, nil
+}
+
+// streamConfig returns the settings of a stream under the names of topic configurations, so
+// that discovery reads the retention of Kinesis streams like that of Kafka topics
+func streamConfig(summary *types.StreamDescriptionSummary) map[string]string {
+	hours := aws.ToInt32(summary.RetentionPeriodHours)
+	if hours == 0 {
+		hours = defaultRetentionHours
+	}
+	config := map[string]string{
+		"retention.ms":    strconv.FormatInt(int64(hours)*time.Hour.Milliseconds(), 10),
+		"stream.status":   string(summary.StreamStatus),
+		"encryption.type": string(summary.EncryptionType),
+	}
+	if summary.StreamModeDetails != nil {
+		config["stream.mode"] = string(summary.StreamModeDetails.StreamMode)
+	}
+	return config
+}
@@ -0,0 +1,423 @@
+package kinesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+const (
+	// A shard serves 5 GetRecords calls per second
+	pollInterval      = 200 * time.Millisecond
+	idlePollInterval  = time.Second
+	defaultConsumerID = "redb-anchor"
+)
+
+// Consumer reads the shards of a Kinesis stream and checkpoints the sequence number of the last
+// handled record per shard in its checkpoint store. Subscribe resumes after the checkpoints, so
+// records handled but not checkpointed are delivered again.
+//
+// When a shard is closed by resharding, its child shards are read from their first record once
+// all their parents were read to the end. With auto-commit, checkpoints are saved after each
+// batch of records; otherwise Commit saves them.
+type Consumer struct {
+	conn  *Connection
+	store adapter.CheckpointStore
+
+	// handlerMu makes the handler see one record at a time, across shards
+	handlerMu sync.Mutex
+
+	mu          sync.Mutex
+	stream      string
+	consumerID  string
+	shards      map[string]*shard
+	uncommitted map[string]string // shard ID -> handled position not yet checkpointed
+	running     int
+	done        chan struct{}
+	errs        chan error
+}
+
+type shard struct {
+	id        string
+	partition int32
+	parents   []string
+	closed    bool   // the shard has an ending sequence number
+	position  string // last handled sequence number, or adapter.ShardEnd once read to the end
+	skipped   bool   // a closed shard not read since the consumer starts at the latest records
+	started   bool
+}
+
+func newConsumer(conn *Connection) *Consumer {
+	return &Consumer{
+		conn:  conn,
+		store: adapter.NewMemoryCheckpointStore(),
+	}
+}
+
+// SetCheckpointStore sets the store that Subscribe resumes from and Commit saves to
+func (c *Consumer) SetCheckpointStore(store adapter.CheckpointStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
+}
+
+// Subscribe lists the shards of a stream and loads the checkpoints of the consumer. Kinesis
+// streams are read one at a time; groupID identifies the consumer in the checkpoint store.
+func (c *Consumer) Subscribe(ctx context.Context, topics []string, groupID string) error {
+	if len(topics) != 1 {
+		return fmt.Errorf("kinesis consumers read exactly one stream")
+	}
+	consumerID := groupID
+	if consumerID == "" {
+		consumerID = c.conn.config.GroupID
+	}
+	if consumerID == "" {
+		consumerID = defaultConsumerID
+	}
+
+	listed, err := c.listShards(ctx, topics[0])
+	if err != nil {
+		return err
+	}
+	checkpoints, err := c.store.LoadCheckpoints(ctx, consumerID, topics[0])
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoints of stream %s: %w", topics[0], err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stream = topics[0]
+	c.consumerID = consumerID
+	c.shards = make(map[string]*shard, len(listed))
+	c.uncommitted = make(map[string]string)
+	for _, s := range listed {
+		c.addShardLocked(aws.ToString(s.ShardId), shardParents(s.ParentShardId, s.AdjacentParentShardId),
+			s.SequenceNumberRange != nil && s.SequenceNumberRange.EndingSequenceNumber != nil)
+	}
+	for id, position := range checkpoints {
+		if s, ok := c.shards[id]; ok {
+			s.position = position
+		}
+	}
+	return nil
+}
+
+// Consume reads the shards of the stream until the context is cancelled, the handler fails or
+// every shard was read to the end
+func (c *Consumer) Consume(ctx context.Context, handler adapter.MessageHandler) error {
+	c.mu.Lock()
+	if c.shards == nil {
+		c.mu.Unlock()
+		return fmt.Errorf("not subscribed to any stream")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for _, s := range c.shards {
+		s.started = false
+	}
+	c.running = 0
+	c.done = make(chan struct{})
+	c.errs = make(chan error, 1)
+	c.startReadyLocked(ctx, handler)
+	if c.running == 0 {
+		close(c.done)
+	}
+	done, errs := c.done, c.errs
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errs:
+		return err
+	case <-done:
+		return nil
+	}
+}
+
+// startReadyLocked starts reading the shards whose parents were read to the end
+func (c *Consumer) startReadyLocked(ctx context.Context, handler adapter.MessageHandler) {
+	for _, s := range c.shards {
+		if s.started || c.finishedLocked(s) || !c.parentsFinishedLocked(s) {
+			continue
+		}
+
+		iteratorType := types.ShardIteratorTypeLatest
+		switch {
+		case s.position != "":
+			iteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		case c.parentReadLocked(s), c.conn.config.AutoOffsetReset == "earliest":
+			iteratorType = types.ShardIteratorTypeTrimHorizon
+		case s.closed:
+			// A closed shard holds no records newer than the latest ones
+			s.skipped = true
+			continue
+		}
+
+		s.started = true
+		c.running++
+		go c.readShard(ctx, s.id, s.partition, iteratorType, s.position, handler)
+	}
+}
+
+func (c *Consumer) readShard(ctx context.Context, shardID string, partition int32, iteratorType types.ShardIteratorType, position string, handler adapter.MessageHandler) {
+	err := c.pollShard(ctx, shardID, partition, iteratorType, position, handler)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running--
+	if err != nil {
+		if ctx.Err() == nil {
+			select {
+			case c.errs <- err:
+			default:
+			}
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	c.startReadyLocked(ctx, handler)
+	if c.running == 0 {
+		close(c.done)
+	}
+}
+
+// pollShard reads a shard until it is closed and read to the end, or the context is cancelled
+func (c *Consumer) pollShard(ctx context.Context, shardID string, partition int32, iteratorType types.ShardIteratorType, position string, handler adapter.MessageHandler) error {
+	iterator, err := c.shardIterator(ctx, shardID, iteratorType, position)
+	if err != nil {
+		return err
+	}
+
+	backoff := pollInterval
+	for {
+		output, err := c.conn.client.GetRecords(ctx, &kinesis.GetRecordsInput{ShardIterator: iterator})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var expired *types.ExpiredIteratorException
+			switch {
+			case errors.As(err, &expired):
+				// The handler took longer than the iterator lives; resume after the last record
+				if position != "" {
+					iteratorType = types.ShardIteratorTypeAfterSequenceNumber
+				}
+				if iterator, err = c.shardIterator(ctx, shardID, iteratorType, position); err != nil {
+					return err
+				}
+				continue
+			case retryable(err):
+				if !sleep(ctx, backoff) {
+					return nil
+				}
+				backoff = min(backoff*2, 10*time.Second)
+				continue
+			}
+			return fmt.Errorf("failed to read shard %s of stream %s: %w", shardID, c.stream, err)
+		}
+		backoff = pollInterval
+
+		for _, record := range output.Records {
+			msg := &adapter.Message{
+				Topic:     c.stream,
+				Partition: partition,
+				Key:       []byte(aws.ToString(record.PartitionKey)),
+				Value:     record.Data,
+				Timestamp: aws.ToTime(record.ApproximateArrivalTimestamp),
+				Metadata: map[string]interface{}{
+					"shard_id":        shardID,
+					"sequence_number": aws.ToString(record.SequenceNumber),
+				},
+			}
+			c.handlerMu.Lock()
+			err := handler(ctx, msg)
+			c.handlerMu.Unlock()
+			if err != nil {
+				return err
+			}
+
+			position = aws.ToString(record.SequenceNumber)
+			c.mu.Lock()
+			c.shards[shardID].position = position
+			c.uncommitted[shardID] = position
+			c.mu.Unlock()
+		}
+
+		if output.NextShardIterator == nil {
+			c.finishShard(shardID, output.ChildShards)
+			return c.autoCommit(ctx)
+		}
+		if err := c.autoCommit(ctx); err != nil {
+			return err
+		}
+		iterator = output.NextShardIterator
+
+		interval := pollInterval
+		if len(output.Records) == 0 {
+			interval = idlePollInterval
+		}
+		if !sleep(ctx, interval) {
+			return nil
+		}
+	}
+}
+
+// finishShard records that a shard was read to the end and adds its child shards
+func (c *Consumer) finishShard(shardID string, children []types.ChildShard) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shards[shardID].position = adapter.ShardEnd
+	c.uncommitted[shardID] = adapter.ShardEnd
+	for _, child := range children {
+		if _, ok := c.shards[aws.ToString(child.ShardId)]; !ok {
+			c.addShardLocked(aws.ToString(child.ShardId), child.ParentShards, false)
+		}
+	}
+}
+
+func (c *Consumer) shardIterator(ctx context.Context, shardID string, iteratorType types.ShardIteratorType, position string) (*string, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String(c.stream),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: iteratorType,
+	}
+	if iteratorType == types.ShardIteratorTypeAfterSequenceNumber {
+		input.StartingSequenceNumber = aws.String(position)
+	}
+	output, err := c.conn.client.GetShardIterator(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get iterator of shard %s: %w", shardID, err)
+	}
+	return output.ShardIterator, nil
+}
+
+func (c *Consumer) autoCommit(ctx context.Context) error {
+	if !c.conn.config.EnableAutoCommit {
+		return nil
+	}
+	return c.Commit(ctx)
+}
+
+// Commit saves the positions of the records handled since the last commit
+func (c *Consumer) Commit(ctx context.Context) error {
+	c.mu.Lock()
+	positions := c.uncommitted
+	c.uncommitted = make(map[string]string)
+	store, consumerID, stream := c.store, c.consumerID, c.stream
+	c.mu.Unlock()
+
+	if len(positions) == 0 {
+		return nil
+	}
+	if err := store.SaveCheckpoints(ctx, consumerID, stream, positions); err != nil {
+		// Keep the positions for the next commit unless newer ones were handled meanwhile
+		c.mu.Lock()
+		for id, position := range positions {
+			if _, ok := c.uncommitted[id]; !ok {
+				c.uncommitted[id] = position
+			}
+		}
+		c.mu.Unlock()
+		return fmt.Errorf("failed to checkpoint stream %s: %w", stream, err)
+	}
+	return nil
+}
+
+// Seek is not supported, since Kinesis positions are sequence numbers; consumers resume from
+// the checkpoints of their store
+func (c *Consumer) Seek(ctx context.Context, topic string, partition int32, offset int64) error {
+	return fmt.Errorf("kinesis consumers resume from checkpoints and cannot seek to offsets")
+}
+
+// Close saves the positions that were handled
+func (c *Consumer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.autoCommit(ctx)
+}
+
+func (c *Consumer) listShards(ctx context.Context, stream string) ([]types.Shard, error) {
+	var shards []types.Shard
+	input := &kinesis.ListShardsInput{StreamName: aws.String(stream)}
+	for {
+		output, err := c.conn.client.ListShards(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shards of stream %s: %w", stream, err)
+		}
+		shards = append(shards, output.Shards...)
+		if output.NextToken == nil {
+			return shards, nil
+		}
+		// The stream name cannot be given with a pagination token
+		input = &kinesis.ListShardsInput{NextToken: output.NextToken}
+	}
+}
+
+func (c *Consumer) addShardLocked(id string, parents []string, closed bool) {
+	c.shards[id] = &shard{
+		id:        id,
+		partition: int32(len(c.shards)),
+		parents:   parents,
+		closed:    closed,
+	}
+}
+
+func (c *Consumer) finishedLocked(s *shard) bool {
+	return s.position == adapter.ShardEnd || s.skipped
+}
+
+// parentsFinishedLocked reports whether the parents of a shard were read to the end. Parents
+// past the retention of the stream are no longer listed.
+func (c *Consumer) parentsFinishedLocked(s *shard) bool {
+	for _, id := range s.parents {
+		if parent, ok := c.shards[id]; ok && !c.finishedLocked(parent) {
+			return false
+		}
+	}
+	return true
+}
+
+// parentReadLocked reports whether a parent of a shard was read, in which case the shard
+// continues from its first record
+func (c *Consumer) parentReadLocked(s *shard) bool {
+	for _, id := range s.parents {
+		if parent, ok := c.shards[id]; ok && parent.position == adapter.ShardEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func shardParents(ids ...*string) []string {
+	var parents []string
+	for _, id := range ids {
+		if id != nil {
+			parents = append(parents, *id)
+		}
+	}
+	return parents
+}
+
+// sleep waits for d and reports whether the context is still active
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,169 @@
+package kinesis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+const (
+	// Limits of a PutRecords request
+	maxBatchRecords = 500
+	maxBatchBytes   = 5 * 1024 * 1024
+	maxRecordBytes  = 1024 * 1024
+
+	maxPartitionKeyLength = 256
+	maxPutAttempts        = 5
+)
+
+// Producer writes to Kinesis streams with PutRecords. Records are assigned to shards by the hash
+// of their partition key, which is the message key.
+//
+// PutRecords can reject some records of a request. The records from the first rejected one on
+// are sent again, so that the records of a key keep their order; records accepted after the
+// first rejected one are then written twice.
+type Producer struct {
+	conn    *Connection
+	pending sync.WaitGroup // ProduceAsync calls in flight
+}
+
+// Produce writes messages to a stream and returns once all of them were accepted
+func (p *Producer) Produce(ctx context.Context, topic string, messages []adapter.Message) error {
+	entries := make([]types.PutRecordsRequestEntry, 0, len(messages))
+	for _, msg := range messages {
+		key := partitionKey(msg)
+		if len(msg.Value)+len(key) > maxRecordBytes {
+			return fmt.Errorf("message of %d bytes exceeds the kinesis record limit of %d bytes", len(msg.Value)+len(key), maxRecordBytes)
+		}
+		entries = append(entries, types.PutRecordsRequestEntry{
+			Data:         msg.Value,
+			PartitionKey: aws.String(key),
+		})
+	}
+
+	for len(entries) > 0 {
+		n := batchLength(entries)
+		if err := p.putRecords(ctx, topic, entries[:n]); err != nil {
+			return err
+		}
+		entries = entries[n:]
+	}
+	return nil
+}
+
+// putRecords writes a batch, sending the records from the first rejected one on again until all
+// were accepted
+func (p *Producer) putRecords(ctx context.Context, topic string, entries []types.PutRecordsRequestEntry) error {
+	backoff := 100 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		output, err := p.conn.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(topic),
+			Records:    entries,
+		})
+
+		var reason string
+		switch {
+		case err != nil:
+			if !retryable(err) {
+				return fmt.Errorf("failed to produce to stream %s: %w", topic, err)
+			}
+			reason = err.Error()
+		case aws.ToInt32(output.FailedRecordCount) == 0:
+			return nil
+		default:
+			for i, result := range output.Records {
+				if result.ErrorCode != nil {
+					reason = fmt.Sprintf("%s: %s", aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage))
+					entries = entries[i:]
+					break
+				}
+			}
+		}
+
+		if attempt == maxPutAttempts {
+			return fmt.Errorf("failed to produce %d records to stream %s after %d attempts: %s", len(entries), topic, attempt, reason)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// ProduceAsync writes messages in the background and calls callback with the result
+func (p *Producer) ProduceAsync(ctx context.Context, topic string, messages []adapter.Message, callback func(error)) error {
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		err := p.Produce(ctx, topic, messages)
+		if callback != nil {
+			callback(err)
+		}
+	}()
+	return nil
+}
+
+// Flush waits for the messages of ProduceAsync calls to be written
+func (p *Producer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for the messages in flight
+func (p *Producer) Close() error {
+	p.pending.Wait()
+	return nil
+}
+
+// batchLength returns how many of the entries fit in a PutRecords request
+func batchLength(entries []types.PutRecordsRequestEntry) int {
+	size := 0
+	for i, entry := range entries {
+		size += len(entry.Data) + len(aws.ToString(entry.PartitionKey))
+		if i == maxBatchRecords || size > maxBatchBytes {
+			return i
+		}
+	}
+	return len(entries)
+}
+
+// partitionKey returns the key of a message, hashed when longer than Kinesis allows. Messages
+// without a key are spread over the shards.
+func partitionKey(msg adapter.Message) string {
+	if len(msg.Key) == 0 {
+		return strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	if len(msg.Key) > maxPartitionKeyLength {
+		sum := sha256.Sum256(msg.Key)
+		return hex.EncodeToString(sum[:])
+	}
+	return string(msg.Key)
+}
+
+// retryable reports whether a request failed because of throttling
+func retryable(err error) bool {
+	var throughput *types.ProvisionedThroughputExceededException
+	var limit *types.LimitExceededException
+	return errors.As(err, &throughput) || errors.As(err, &limit)
+}
@@ -0,0 +1,96 @@
+// Package stream attaches the anchor to stream platforms through the adapters registered with
+// pkg/stream/adapter, and discovers their topics into unified models.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/redbco/redb-open/pkg/logger"
+	streamadapter "github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// Manager holds the connections of the anchor to stream platforms, by stream ID
+type Manager struct {
+	mu          sync.Mutex
+	conns       map[string]streamadapter.Connection
+	checkpoints CheckpointRepository
+	logger      *logger.Logger
+}
+
+// NewManager creates a manager without connections
+func NewManager(logger *logger.Logger) *Manager {
+	return &Manager{
+		conns:  make(map[string]streamadapter.Connection),
+		logger: logger,
+	}
+}
+
+// Connect connects to a stream platform with the adapter of its platform, replacing the
+// connection of the stream if it was connected
+func (m *Manager) Connect(ctx context.Context, config streamadapter.ConnectionConfig) (streamadapter.Connection, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	adapter, err := streamadapter.GetAdapter(config.Platform)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := adapter.Connect(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", config.Platform, err)
+	}
+
+	m.mu.Lock()
+	previous := m.conns[config.ID]
+	m.conns[config.ID] = conn
+	m.mu.Unlock()
+
+	if previous != nil {
+		m.close(config.ID, previous)
+	}
+	return conn, nil
+}
+
+// Get returns the connection of a stream
+func (m *Manager) Get(streamID string) (streamadapter.Connection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	conn, ok := m.conns[streamID]
+	if !ok {
+		return nil, fmt.Errorf("stream %s is not connected", streamID)
+	}
+	return conn, nil
+}
+
+// Disconnect closes the connection of a stream, and returns false if it was not connected
+func (m *Manager) Disconnect(streamID string) bool {
+	m.mu.Lock()
+	conn, ok := m.conns[streamID]
+	delete(m.conns, streamID)
+	m.mu.Unlock()
+
+	if ok {
+		m.close(streamID, conn)
+	}
+	return ok
+}
+
+// CloseAll closes all connections
+func (m *Manager) CloseAll() {
+	m.mu.Lock()
+	conns := m.conns
+	m.conns = make(map[string]streamadapter.Connection)
+	m.mu.Unlock()
+
+	for id, conn := range conns {
+		m.close(id, conn)
+	}
+}
+
+func (m *Manager) close(streamID string, conn streamadapter.Connection) {
+	if err := conn.Close(); err != nil && m.logger != nil {
+		m.logger.Warnf("Error closing connection of stream %s: %v", streamID, err)
+	}
+}
@@ -0,0 +1,192 @@
+// Package natsjetstream implements the stream adapter of NATS JetStream: publishing to the
+// subjects of streams with acknowledgements, consuming them through durable consumers and
+// administering streams.
+package natsjetstream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+)
+
+// Adapter implements the StreamAdapter interface for NATS JetStream
+type Adapter struct{}
+
+// NewAdapter creates a NATS JetStream adapter
+func NewAdapter() *Adapter {
+	return &Adapter{}
+}
+
+func (a *Adapter) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.NATSJetStream
+}
+
+func (a *Adapter) Capabilities() streamcapabilities.Capability {
+	cap, _ := streamcapabilities.Get(streamcapabilities.NATSJetStream)
+	return cap
+}
+
+// Connect connects to the NATS servers of the configuration, from brokers or endpoint, and
+// checks that JetStream is enabled for the account. Users authenticate with username and
+// password, or with the token, creds_file (a JWT and NKey credentials file) or nkey_seed_file
+// settings. The domain setting selects the JetStream domain of a leaf node deployment.
+func (a *Adapter) Connect(ctx context.Context, cfg adapter.ConnectionConfig) (adapter.Connection, error) {
+	servers := cfg.Brokers
+	if len(servers) == 0 && cfg.Endpoint != "" {
+		servers = []string{cfg.Endpoint}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("endpoint or brokers are required")
+	}
+
+	opts, err := connectOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	nc, err := nats.Connect(strings.Join(servers, ","), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	var js jetstream.JetStream
+	if domain := cfg.Configuration["domain"]; domain != "" {
+		js, err = jetstream.NewWithDomain(nc, domain)
+	} else {
+		js, err = jetstream.New(nc)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	conn := &Connection{
+		id:     cfg.ID,
+		config: cfg,
+		nc:     nc,
+		js:     js,
+	}
+	if err := conn.Ping(ctx); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connectOptions returns the options of the NATS connection for the authentication and TLS
+// settings of the configuration
+func connectOptions(cfg adapter.ConnectionConfig) ([]nats.Option, error) {
+	opts := []nats.Option{nats.Name("redb-" + cfg.ID)}
+	if cfg.ConnectTimeout > 0 {
+		opts = append(opts, nats.Timeout(cfg.ConnectTimeout))
+	}
+
+	switch {
+	case cfg.Configuration["creds_file"] != "":
+		opts = append(opts, nats.UserCredentials(cfg.Configuration["creds_file"]))
+	case cfg.Configuration["nkey_seed_file"] != "":
+		opt, err := nats.NkeyOptionFromSeed(cfg.Configuration["nkey_seed_file"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load nkey seed: %w", err)
+		}
+		opts = append(opts, opt)
+	case cfg.Configuration["token"] != "":
+		opts = append(opts, nats.Token(cfg.Configuration["token"]))
+	case cfg.Username != "":
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TLS config: %w", err)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+	return opts, nil
+}
+
+// Connection is a connection to the JetStream of a NATS account. Its producer is shared by the
+// callers of ProducerOperations, while each consumer reads through its own JetStream consumers.
+type Connection struct {
+	id     string
+	config adapter.ConnectionConfig
+	nc     *nats.Conn
+	js     jetstream.JetStream
+
+	mu       sync.Mutex
+	producer *Producer
+}
+
+func (c *Connection) ID() string {
+	return c.id
+}
+
+func (c *Connection) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.NATSJetStream
+}
+
+// IsConnected reports whether the NATS connection is up. It is not while the client
+// reconnects to another server.
+func (c *Connection) IsConnected() bool {
+	return c.nc.IsConnected()
+}
+
+// Ping reads the JetStream account information, which fails if JetStream is not enabled
+func (c *Connection) Ping(ctx context.Context) error {
+	if _, err := c.js.AccountInfo(ctx); err != nil {
+		return fmt.Errorf("failed to reach JetStream: %w", err)
+	}
+	return nil
+}
+
+// Close waits for the messages of the producer in flight and closes the NATS connection.
+// Durable consumers stay on the server.
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	producer := c.producer
+	c.producer = nil
+	c.mu.Unlock()
+
+	var err error
+	if producer != nil {
+		err = producer.Close()
+	}
+	c.nc.Close()
+	return err
+}
+
+func (c *Connection) ProducerOperations() adapter.ProducerOperator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.producer == nil {
+		c.producer = &Producer{conn: c}
+	}
+	return c.producer
+}
+
+func (c *Connection) ConsumerOperations() adapter.ConsumerOperator {
+	return &Consumer{conn: c}
+}
+
+func (c *Connection) AdminOperations() adapter.AdminOperator {
+	return &Admin{conn: c}
+}
+
+// Raw returns the JetStream context of the connection
+func (c *Connection) Raw() interface{} {
+	return c.js
+}
+
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+func (c *Connection) Adapter() adapter.StreamAdapter {
+	return &Adapter{}
+}
@@ -0,0 +1,169 @@
+package natsjetstream
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// Admin administers the streams of an account. Streams are the topics of JetStream: they
+// store the messages of their subjects, and have no partitions, so they are reported with a
+// single one whose offsets are the stream sequences.
+type Admin struct {
+	conn *Connection
+}
+
+// ListTopics returns the streams of the account with their retention
+func (a *Admin) ListTopics(ctx context.Context) ([]adapter.TopicInfo, error) {
+	var topics []adapter.TopicInfo
+	lister := a.conn.js.ListStreams(ctx)
+	for info := range lister.Info() {
+		topics = append(topics, adapter.TopicInfo{
+			Name:       info.Config.Name,
+			Partitions: 1,
+			Replicas:   int32(info.Config.Replicas),
+			Config:     streamConfig(info.Config),
+		})
+	}
+	if err := lister.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list streams: %w", err)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics, nil
+}
+
+// CreateTopic creates a file stream that keeps messages for RetentionMs, or until its limits
+// are reached without RetentionMs. The stream stores the comma-separated subjects of the
+// subjects setting of Config, or the subject named like the stream. The storage setting
+// selects memory storage.
+func (a *Admin) CreateTopic(ctx context.Context, name string, config adapter.TopicConfig) error {
+	streamConfig := jetstream.StreamConfig{
+		Name:     name,
+		Subjects: []string{name},
+		Storage:  jetstream.FileStorage,
+	}
+	if subjects := config.Config["subjects"]; subjects != "" {
+		streamConfig.Subjects = nil
+		for _, subject := range strings.Split(subjects, ",") {
+			if subject = strings.TrimSpace(subject); subject != "" {
+				streamConfig.Subjects = append(streamConfig.Subjects, subject)
+			}
+		}
+	}
+	if strings.EqualFold(config.Config["storage"], "memory") {
+		streamConfig.Storage = jetstream.MemoryStorage
+	}
+	if config.ReplicationFactor > 0 {
+		streamConfig.Replicas = int(config.ReplicationFactor)
+	}
+	if config.RetentionMs > 0 {
+		streamConfig.MaxAge = time.Duration(config.RetentionMs) * time.Millisecond
+	}
+
+	if _, err := a.conn.js.CreateStream(ctx, streamConfig); err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteTopic deletes a stream with its messages and consumers
+func (a *Admin) DeleteTopic(ctx context.Context, name string) error {
+	if err := a.conn.js.DeleteStream(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetTopicMetadata returns the configuration, sequences and consumers of a stream
+func (a *Admin) GetTopicMetadata(ctx context.Context, topic string) (adapter.TopicMetadata, error) {
+	stream, err := a.conn.js.Stream(ctx, topic)
+	if err != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to get stream %s: %w", topic, err)
+	}
+	info := stream.CachedInfo()
+
+	var consumers []string
+	names := stream.ConsumerNames(ctx)
+	for name := range names.Name() {
+		consumers = append(consumers, name)
+	}
+	if err := names.Err(); err != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to list consumers of stream %s: %w", topic, err)
+	}
+	sort.Strings(consumers)
+
+	partition := adapter.PartitionMetadata{
+		ID: 0,
+		Offset: adapter.OffsetInfo{
+			Oldest: int64(info.State.FirstSeq),
+			Newest: int64(info.State.LastSeq),
+		},
+	}
+	if info.Cluster != nil {
+		partition.Leader = info.Cluster.Leader
+		partition.Replicas = append(partition.Replicas, info.Cluster.Leader)
+		partition.ISR = append(partition.ISR, info.Cluster.Leader)
+		for _, peer := range info.Cluster.Replicas {
+			partition.Replicas = append(partition.Replicas, peer.Name)
+			if peer.Current {
+				partition.ISR = append(partition.ISR, peer.Name)
+			}
+		}
+	}
+
+	return adapter.TopicMetadata{
+		Name:       topic,
+		Partitions: []adapter.PartitionMetadata{partition},
+		Config:     streamConfig(info.Config),
+		Metadata: map[string]interface{}{
+			"subjects":  info.Config.Subjects,
+			"messages":  info.State.Msgs,
+			"bytes":     info.State.Bytes,
+			"consumers": consumers,
+		},
+	}, nil
+}
+
+// GetTopicConfig returns the replicas and retention of a stream
+func (a *Admin) GetTopicConfig(ctx context.Context, topic string) (adapter.TopicConfig, error) {
+	stream, err := a.conn.js.Stream(ctx, topic)
+	if err != nil {
+		return adapter.TopicConfig{}, fmt.Errorf("failed to get stream %s: %w", topic, err)
+	}
+	config := stream.CachedInfo().Config
+	return adapter.TopicConfig{
+		NumPartitions:     1,
+		ReplicationFactor: int32(config.Replicas),
+		RetentionMs:       config.MaxAge.Milliseconds(),
+		Config:            streamConfig(config),
+	}, nil
+}
+
+// streamConfig returns the settings of a stream under the names of topic configurations, so
+// that discovery reads the retention of streams like that of Kafka topics
+func streamConfig(config jetstream.StreamConfig) map[string]string {
+	result := map[string]string{
+		"subjects":         strings.Join(config.Subjects, ","),
+		"storage":          strings.ToLower(config.Storage.String()),
+		"retention.policy": strings.ToLower(config.Retention.String()),
+	}
+	if config.MaxAge > 0 {
+		result["retention.ms"] = strconv.FormatInt(config.MaxAge.Milliseconds(), 10)
+	}
+	if config.MaxBytes > 0 {
+		result["retention.bytes"] = strconv.FormatInt(config.MaxBytes, 10)
+	}
+	if config.MaxMsgSize > 0 {
+		result["max.message.bytes"] = strconv.FormatInt(int64(config.MaxMsgSize), 10)
+	}
+	if config.Duplicates > 0 {
+		result["duplicate.window.ms"] = strconv.FormatInt(config.Duplicates.Milliseconds(), 10)
+	}
+	return result
+}
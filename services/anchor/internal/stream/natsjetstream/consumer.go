@@ -0,0 +1,295 @@
+package natsjetstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// Consumer reads the subjects of JetStream streams through pull consumers, one per stream.
+// With a consumer group the consumers are durable and named after the group, so the server
+// keeps their position between connections and shares their messages among the members of
+// the group. Without a group they are ephemeral, and removed by the server once unused.
+//
+// Consumers acknowledge all messages up to the one acknowledged. With auto-commit a message is
+// acknowledged once the handler accepted it; otherwise Commit acknowledges the last handled
+// message of each stream and waits for the server to confirm. Messages not acknowledged within
+// the ack wait of the consumer are delivered again.
+type Consumer struct {
+	conn *Connection
+
+	mu        sync.Mutex
+	groupID   string
+	subjects  map[string][]string           // stream -> subscribed subjects
+	consumers map[string]jetstream.Consumer // stream -> consumer
+	unacked   map[string]jetstream.Msg      // stream -> last handled message not yet acknowledged
+}
+
+type received struct {
+	msg jetstream.Msg
+	err error
+}
+
+// Subscribe creates or updates a consumer for the streams that store the subjects. New
+// durable consumers start at the first retained message with the earliest reset policy, and
+// at the next published message otherwise; existing ones resume where the group left off.
+func (c *Consumer) Subscribe(ctx context.Context, topics []string, groupID string) error {
+	if len(topics) == 0 {
+		return fmt.Errorf("at least one topic required")
+	}
+	if groupID == "" {
+		groupID = c.conn.config.GroupID
+	}
+
+	subjects := make(map[string][]string)
+	for _, topic := range topics {
+		stream, err := c.conn.js.StreamNameBySubject(ctx, topic)
+		if err != nil {
+			return fmt.Errorf("failed to find the stream of subject %s: %w", topic, err)
+		}
+		subjects[stream] = append(subjects[stream], topic)
+	}
+
+	deliverPolicy := jetstream.DeliverNewPolicy
+	if c.conn.config.AutoOffsetReset == "earliest" {
+		deliverPolicy = jetstream.DeliverAllPolicy
+	}
+
+	consumers := make(map[string]jetstream.Consumer, len(subjects))
+	for stream, filters := range subjects {
+		config := consumerConfig(groupID, filters)
+		config.DeliverPolicy = deliverPolicy
+
+		// The start of a durable consumer cannot be changed, and is kept when it is updated
+		if groupID != "" {
+			existing, err := c.conn.js.Consumer(ctx, stream, groupID)
+			switch {
+			case err == nil:
+				info := existing.CachedInfo()
+				config.DeliverPolicy = info.Config.DeliverPolicy
+				config.OptStartSeq = info.Config.OptStartSeq
+				config.OptStartTime = info.Config.OptStartTime
+			case !errors.Is(err, jetstream.ErrConsumerNotFound):
+				return fmt.Errorf("failed to look up consumer %s of stream %s: %w", groupID, stream, err)
+			}
+		}
+
+		consumer, err := c.conn.js.CreateOrUpdateConsumer(ctx, stream, config)
+		if err != nil {
+			return fmt.Errorf("failed to create consumer of stream %s: %w", stream, err)
+		}
+		consumers[stream] = consumer
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nakLocked()
+	c.groupID = groupID
+	c.subjects = subjects
+	c.consumers = consumers
+	return nil
+}
+
+// consumerConfig returns the configuration of a consumer of subjects, durable if a group is set
+func consumerConfig(groupID string, subjects []string) jetstream.ConsumerConfig {
+	config := jetstream.ConsumerConfig{
+		Durable:   groupID,
+		AckPolicy: jetstream.AckAllPolicy,
+	}
+	// Servers before 2.10 only take a single filter subject
+	if len(subjects) == 1 {
+		config.FilterSubject = subjects[0]
+	} else {
+		config.FilterSubjects = append([]string(nil), subjects...)
+		sort.Strings(config.FilterSubjects)
+	}
+	return config
+}
+
+// Consume pulls from the consumers until ctx is cancelled or the handler fails. The message
+// the handler failed on is delivered again.
+func (c *Consumer) Consume(ctx context.Context, handler adapter.MessageHandler) error {
+	c.mu.Lock()
+	consumers := c.consumers
+	c.mu.Unlock()
+	if len(consumers) == 0 {
+		return fmt.Errorf("not subscribed to any topic")
+	}
+
+	pullCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan received)
+	for stream, consumer := range consumers {
+		iter, err := consumer.Messages()
+		if err != nil {
+			return fmt.Errorf("failed to pull from stream %s: %w", stream, err)
+		}
+		go func(iter jetstream.MessagesContext) {
+			defer iter.Stop()
+			for {
+				msg, err := iter.Next(jetstream.NextContext(pullCtx))
+				if pullCtx.Err() != nil {
+					return
+				}
+				select {
+				case messages <- received{msg: msg, err: err}:
+				case <-pullCtx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(iter)
+	}
+
+	for {
+		var r received
+		select {
+		case r = <-messages:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil {
+			return fmt.Errorf("failed to pull messages: %w", r.err)
+		}
+
+		msg, metadata, err := convertMessage(r.msg)
+		if err != nil {
+			return err
+		}
+		if err := handler(ctx, msg); err != nil {
+			r.msg.Nak()
+			return err
+		}
+
+		if c.conn.config.EnableAutoCommit {
+			if err := r.msg.Ack(); err != nil {
+				return fmt.Errorf("failed to acknowledge message %d of stream %s: %w", metadata.Sequence.Stream, metadata.Stream, err)
+			}
+			continue
+		}
+		c.mu.Lock()
+		c.unacked[metadata.Stream] = r.msg
+		c.mu.Unlock()
+	}
+}
+
+// Commit acknowledges the messages handled since the last commit
+func (c *Consumer) Commit(ctx context.Context) error {
+	c.mu.Lock()
+	unacked := make(map[string]jetstream.Msg, len(c.unacked))
+	for stream, msg := range c.unacked {
+		unacked[stream] = msg
+	}
+	c.mu.Unlock()
+
+	for stream, msg := range unacked {
+		if err := msg.DoubleAck(ctx); err != nil {
+			return fmt.Errorf("failed to acknowledge messages of stream %s: %w", stream, err)
+		}
+		c.mu.Lock()
+		if c.unacked[stream] == msg {
+			delete(c.unacked, stream)
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// Seek moves the consumer of the stream that stores a subject to offset, a stream sequence.
+// JetStream consumers cannot be moved, so the consumer is recreated starting at offset, which
+// also moves the other subjects it reads. A Consume in progress stops with an error, as its
+// consumer is deleted.
+func (c *Consumer) Seek(ctx context.Context, topic string, partition int32, offset int64) error {
+	if offset < 1 {
+		return fmt.Errorf("invalid stream sequence %d", offset)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stream string
+	for name, subjects := range c.subjects {
+		for _, subject := range subjects {
+			if subject == topic {
+				stream = name
+			}
+		}
+	}
+	consumer, ok := c.consumers[stream]
+	if !ok {
+		return fmt.Errorf("not subscribed to topic %s", topic)
+	}
+
+	if err := c.conn.js.DeleteConsumer(ctx, stream, consumer.CachedInfo().Name); err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return fmt.Errorf("failed to delete consumer of stream %s: %w", stream, err)
+	}
+	config := consumerConfig(c.groupID, c.subjects[stream])
+	config.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+	config.OptStartSeq = uint64(offset)
+	consumer, err := c.conn.js.CreateConsumer(ctx, stream, config)
+	if err != nil {
+		return fmt.Errorf("failed to recreate consumer of stream %s at sequence %d: %w", stream, offset, err)
+	}
+	c.consumers[stream] = consumer
+	delete(c.unacked, stream)
+	return nil
+}
+
+// Close returns the handled messages that were not committed, so that they are delivered again
+// without waiting for their ack wait. Durable consumers stay on the server.
+func (c *Consumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nakLocked()
+	c.consumers = nil
+	c.subjects = nil
+	return nil
+}
+
+func (c *Consumer) nakLocked() {
+	for _, msg := range c.unacked {
+		msg.Nak()
+	}
+	c.unacked = make(map[string]jetstream.Msg)
+}
+
+// convertMessage returns a JetStream message with its stream sequence as offset, which is the
+// position Seek takes
+func convertMessage(m jetstream.Msg) (*adapter.Message, *jetstream.MsgMetadata, error) {
+	metadata, err := m.Metadata()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read metadata of message on subject %s: %w", m.Subject(), err)
+	}
+
+	headers := make(map[string]string, len(m.Headers()))
+	for k, v := range m.Headers() {
+		if len(v) > 0 {
+			headers[k] = v[0]
+		}
+	}
+	key := headers[KeyHeader]
+	delete(headers, KeyHeader)
+
+	return &adapter.Message{
+		Topic:     m.Subject(),
+		Offset:    int64(metadata.Sequence.Stream),
+		Key:       []byte(key),
+		Value:     m.Data(),
+		Headers:   headers,
+		Timestamp: metadata.Timestamp,
+		Metadata: map[string]interface{}{
+			"stream":        metadata.Stream,
+			"consumer":      metadata.Consumer,
+			"num_delivered": metadata.NumDelivered,
+			"num_pending":   metadata.NumPending,
+		},
+	}, metadata, nil
+}
@@ -0,0 +1,94 @@
+package natsjetstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// KeyHeader carries the key of a message, as NATS messages have none. Consumers return it
+// as the key of the messages they receive.
+const KeyHeader = "Redb-Key"
+
+// Producer publishes to the subjects of JetStream streams; the topic of a message is its
+// subject. Messages with a Nats-Msg-Id header are deduplicated by the stream within its
+// duplicate window, so that publishing them again after a failure stores them once.
+type Producer struct {
+	conn    *Connection
+	pending sync.WaitGroup // ProduceAsync calls in flight
+}
+
+// Produce publishes messages to a subject and returns once the stream stored all of them
+func (p *Producer) Produce(ctx context.Context, topic string, messages []adapter.Message) error {
+	maxPayload := p.conn.nc.MaxPayload()
+
+	futures := make([]jetstream.PubAckFuture, 0, len(messages))
+	for _, msg := range messages {
+		if maxPayload > 0 && int64(len(msg.Value)) > maxPayload {
+			return fmt.Errorf("message of %d bytes exceeds the maximum payload of %d bytes of the NATS server", len(msg.Value), maxPayload)
+		}
+		m := nats.NewMsg(topic)
+		m.Data = msg.Value
+		for k, v := range msg.Headers {
+			m.Header.Set(k, v)
+		}
+		if len(msg.Key) > 0 {
+			m.Header.Set(KeyHeader, string(msg.Key))
+		}
+
+		future, err := p.conn.js.PublishMsgAsync(m)
+		if err != nil {
+			return fmt.Errorf("failed to publish to subject %s: %w", topic, err)
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return fmt.Errorf("failed to publish to subject %s: %w", topic, err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// ProduceAsync publishes messages in the background and calls callback with the result
+func (p *Producer) ProduceAsync(ctx context.Context, topic string, messages []adapter.Message, callback func(error)) error {
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		err := p.Produce(ctx, topic, messages)
+		if callback != nil {
+			callback(err)
+		}
+	}()
+	return nil
+}
+
+// Flush waits for the messages of ProduceAsync calls to be stored
+func (p *Producer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for the messages in flight
+func (p *Producer) Close() error {
+	p.pending.Wait()
+	return nil
+}
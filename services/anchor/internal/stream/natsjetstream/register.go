@@ -0,0 +1,13 @@
+package natsjetstream
+
+import (
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+)
+
+func init() {
+	// Register NATS JetStream adapter
+	adapter.RegisterAdapter(streamcapabilities.NATSJetStream, func() adapter.StreamAdapter {
+		return NewAdapter()
+	})
+}
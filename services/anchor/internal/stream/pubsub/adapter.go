@@ -0,0 +1,147 @@
+// Package pubsub implements the stream adapter of Google Cloud Pub/Sub: publishing to topics
+// with ordering keys, consuming through subscriptions and administering topics.
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/pkg/streamcapabilities"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// Adapter implements the StreamAdapter interface for Pub/Sub
+type Adapter struct{}
+
+// NewAdapter creates a Pub/Sub adapter
+func NewAdapter() *Adapter {
+	return &Adapter{}
+}
+
+func (a *Adapter) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.PubSub
+}
+
+func (a *Adapter) Capabilities() streamcapabilities.Capability {
+	cap, _ := streamcapabilities.Get(streamcapabilities.PubSub)
+	return cap
+}
+
+// Connect creates a Pub/Sub client for the project of the configuration and checks that its
+// topics can be listed. The credentials_json and credentials_file settings take precedence
+// over the application default credentials of the anchor, and endpoint overrides the global
+// endpoint (for regional endpoints; the emulator is selected by PUBSUB_EMULATOR_HOST).
+func (a *Adapter) Connect(ctx context.Context, cfg adapter.ConnectionConfig) (adapter.Connection, error) {
+	if cfg.Project == "" {
+		return nil, fmt.Errorf("project is required")
+	}
+
+	var opts []option.ClientOption
+	if json := cfg.Configuration["credentials_json"]; json != "" {
+		opts = append(opts, option.WithCredentialsJSON([]byte(json)))
+	} else if file := cfg.Configuration["credentials_file"]; file != "" {
+		opts = append(opts, option.WithCredentialsFile(file))
+	}
+	if cfg.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.Endpoint))
+	}
+
+	client, err := pubsub.NewClient(ctx, cfg.Project, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	conn := &Connection{
+		id:     cfg.ID,
+		config: cfg,
+		client: client,
+	}
+	if err := conn.Ping(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	conn.connected = true
+	return conn, nil
+}
+
+// Connection is a connection to the topics of a project. Its producer is shared by the callers
+// of ProducerOperations, while each consumer receives from its own subscriptions.
+type Connection struct {
+	id     string
+	config adapter.ConnectionConfig
+	client *pubsub.Client
+
+	mu        sync.Mutex
+	connected bool
+	producer  *Producer
+}
+
+func (c *Connection) ID() string {
+	return c.id
+}
+
+func (c *Connection) Type() streamcapabilities.StreamPlatform {
+	return streamcapabilities.PubSub
+}
+
+func (c *Connection) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+// Ping lists a topic of the project
+func (c *Connection) Ping(ctx context.Context) error {
+	if _, err := c.client.Topics(ctx).Next(); err != nil && err != iterator.Done {
+		return fmt.Errorf("failed to reach Pub/Sub project %s: %w", c.config.Project, err)
+	}
+	return nil
+}
+
+// Close publishes the messages of the producer in flight and closes the client
+func (c *Connection) Close() error {
+	c.mu.Lock()
+	producer := c.producer
+	c.producer = nil
+	c.connected = false
+	c.mu.Unlock()
+
+	if producer != nil {
+		producer.Close()
+	}
+	return c.client.Close()
+}
+
+func (c *Connection) ProducerOperations() adapter.ProducerOperator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.producer == nil {
+		c.producer = &Producer{conn: c, topics: make(map[string]*pubsub.Topic)}
+	}
+	return c.producer
+}
+
+func (c *Connection) ConsumerOperations() adapter.ConsumerOperator {
+	return &Consumer{conn: c}
+}
+
+func (c *Connection) AdminOperations() adapter.AdminOperator {
+	return &Admin{conn: c}
+}
+
+// Raw returns the Pub/Sub client of the connection
+func (c *Connection) Raw() interface{} {
+	return c.client
+}
+
+func (c *Connection) Config() adapter.ConnectionConfig {
+	return c.config
+}
+
+func (c *Connection) Adapter() adapter.StreamAdapter {
+	return &Adapter{}
+}
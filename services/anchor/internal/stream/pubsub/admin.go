@@ -0,0 +1,128 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"google.golang.org/api/iterator"
+)
+
+// Admin administers the topics of a project. Pub/Sub topics have no partitions, so they are
+// reported with a single one.
+type Admin struct {
+	conn *Connection
+}
+
+// ListTopics returns the topics of the project with their retention
+func (a *Admin) ListTopics(ctx context.Context) ([]adapter.TopicInfo, error) {
+	var topics []adapter.TopicInfo
+	it := a.conn.client.Topics(ctx)
+	for {
+		config, err := it.NextConfig()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list topics: %w", err)
+		}
+		topics = append(topics, adapter.TopicInfo{
+			Name:       config.ID(),
+			Partitions: 1,
+			Config:     topicConfig(config),
+		})
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Name < topics[j].Name })
+	return topics, nil
+}
+
+// CreateTopic creates a topic that retains messages for RetentionMs, or only until they are
+// acknowledged by all subscriptions without RetentionMs. Labels are taken from Config.
+func (a *Admin) CreateTopic(ctx context.Context, name string, config adapter.TopicConfig) error {
+	topicConfig := &pubsub.TopicConfig{Labels: config.Config}
+	if config.RetentionMs > 0 {
+		topicConfig.RetentionDuration = time.Duration(config.RetentionMs) * time.Millisecond
+	}
+	if _, err := a.conn.client.CreateTopicWithConfig(ctx, name, topicConfig); err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteTopic deletes a topic. Its subscriptions are detached but kept.
+func (a *Admin) DeleteTopic(ctx context.Context, name string) error {
+	if err := a.conn.client.Topic(name).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete topic %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetTopicMetadata returns the configuration and subscriptions of a topic
+func (a *Admin) GetTopicMetadata(ctx context.Context, topic string) (adapter.TopicMetadata, error) {
+	t := a.conn.client.Topic(topic)
+	config, err := t.Config(ctx)
+	if err != nil {
+		return adapter.TopicMetadata{}, fmt.Errorf("failed to get config of topic %s: %w", topic, err)
+	}
+
+	var subscriptions []string
+	it := t.Subscriptions(ctx)
+	for {
+		sub, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return adapter.TopicMetadata{}, fmt.Errorf("failed to list subscriptions of topic %s: %w", topic, err)
+		}
+		subscriptions = append(subscriptions, sub.ID())
+	}
+
+	return adapter.TopicMetadata{
+		Name:       topic,
+		Partitions: []adapter.PartitionMetadata{{ID: 0}},
+		Config:     topicConfig(&config),
+		Metadata: map[string]interface{}{
+			"topic_name":    config.String(),
+			"subscriptions": subscriptions,
+			"labels":        config.Labels,
+		},
+	}, nil
+}
+
+// GetTopicConfig returns the retention of a topic
+func (a *Admin) GetTopicConfig(ctx context.Context, topic string) (adapter.TopicConfig, error) {
+	config, err := a.conn.client.Topic(topic).Config(ctx)
+	if err != nil {
+		return adapter.TopicConfig{}, fmt.Errorf("failed to get config of topic %s: %w", topic, err)
+	}
+	result := adapter.TopicConfig{
+		NumPartitions: 1,
+		Config:        topicConfig(&config),
+	}
+	if retention, ok := config.RetentionDuration.(time.Duration); ok {
+		result.RetentionMs = retention.Milliseconds()
+	}
+	return result, nil
+}
+
+// topicConfig returns the settings of a topic under the names of topic configurations, so that
+// discovery reads the retention of Pub/Sub topics like that of Kafka topics
+func topicConfig(config *pubsub.TopicConfig) map[string]string {
+	result := make(map[string]string)
+	if retention, ok := config.RetentionDuration.(time.Duration); ok && retention > 0 {
+		result["retention.ms"] = strconv.FormatInt(retention.Milliseconds(), 10)
+	}
+	if config.KMSKeyName != "" {
+		result["kms.key.name"] = config.KMSKeyName
+	}
+	if regions := config.MessageStoragePolicy.AllowedPersistenceRegions; len(regions) > 0 {
+		result["message.storage.regions"] = strings.Join(regions, ",")
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+const (
+	ackDeadline            = time.Minute
+	maxOutstandingMessages = 1000
+)
+
+// Consumer receives from Pub/Sub subscriptions. Pub/Sub keeps the position of a subscription
+// by the messages acknowledged on it, so the subscription is the checkpoint of the consumer:
+// with auto-commit a message is acknowledged once the handler accepted it, otherwise Commit
+// acknowledges the handled messages. Messages not acknowledged are delivered again, to this
+// consumer or to the next one on the subscription.
+//
+// Subscriptions are named after the consumer group, and after the group and topic when more
+// than one topic is consumed. They are created with message ordering on first use.
+type Consumer struct {
+	conn *Connection
+
+	// handlerMu makes the handler see one message at a time, across subscriptions
+	handlerMu sync.Mutex
+
+	mu            sync.Mutex
+	subscriptions map[string]*pubsub.Subscription // topic -> subscription
+	unacked       []*pubsub.Message
+}
+
+// Subscribe opens a subscription per topic, creating the missing ones
+func (c *Consumer) Subscribe(ctx context.Context, topics []string, groupID string) error {
+	if len(topics) == 0 {
+		return fmt.Errorf("at least one topic required")
+	}
+	if groupID == "" {
+		groupID = c.conn.config.GroupID
+	}
+	if groupID == "" {
+		return fmt.Errorf("a consumer group is required to name the Pub/Sub subscription")
+	}
+
+	subscriptions := make(map[string]*pubsub.Subscription, len(topics))
+	for _, topic := range topics {
+		id := groupID
+		if len(topics) > 1 {
+			id = groupID + "-" + topic
+		}
+		sub, err := c.subscription(ctx, id, topic)
+		if err != nil {
+			return err
+		}
+		sub.ReceiveSettings.NumGoroutines = 1
+		sub.ReceiveSettings.MaxOutstandingMessages = maxOutstandingMessages
+		subscriptions[topic] = sub
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nackLocked()
+	c.subscriptions = subscriptions
+	return nil
+}
+
+// subscription returns the subscription of a topic, creating it when missing. New subscriptions
+// receive the messages published after their creation; with the earliest reset policy they are
+// moved back over the retention of the topic, if it retains messages.
+func (c *Consumer) subscription(ctx context.Context, id, topic string) (*pubsub.Subscription, error) {
+	sub := c.conn.client.Subscription(id)
+	exists, err := sub.Exists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up subscription %s: %w", id, err)
+	}
+	if exists {
+		return sub, nil
+	}
+
+	t := c.conn.client.Topic(topic)
+	sub, err = c.conn.client.CreateSubscription(ctx, id, pubsub.SubscriptionConfig{
+		Topic:                 t,
+		AckDeadline:           ackDeadline,
+		EnableMessageOrdering: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription %s on topic %s: %w", id, topic, err)
+	}
+
+	if c.conn.config.AutoOffsetReset == "earliest" {
+		config, err := t.Config(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config of topic %s: %w", topic, err)
+		}
+		if retention, ok := config.RetentionDuration.(time.Duration); ok && retention > 0 {
+			if err := sub.SeekToTime(ctx, time.Now().Add(-retention)); err != nil {
+				return nil, fmt.Errorf("failed to move subscription %s to the earliest retained message: %w", id, err)
+			}
+		}
+	}
+	return sub, nil
+}
+
+// Consume receives from the subscriptions until ctx is cancelled or the handler fails. The
+// message the handler failed on is delivered again.
+func (c *Consumer) Consume(ctx context.Context, handler adapter.MessageHandler) error {
+	c.mu.Lock()
+	subscriptions := c.subscriptions
+	c.mu.Unlock()
+	if len(subscriptions) == 0 {
+		return fmt.Errorf("not subscribed to any topic")
+	}
+
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var handlerErr error
+	var handlerErrOnce sync.Once
+	errs := make(chan error, len(subscriptions))
+	for topic, sub := range subscriptions {
+		go func(topic string, sub *pubsub.Subscription) {
+			errs <- sub.Receive(receiveCtx, func(_ context.Context, m *pubsub.Message) {
+				c.handlerMu.Lock()
+				err := handler(ctx, convertMessage(topic, m))
+				c.handlerMu.Unlock()
+
+				if err != nil {
+					m.Nack()
+					handlerErrOnce.Do(func() { handlerErr = err })
+					cancel()
+					return
+				}
+				if c.conn.config.EnableAutoCommit {
+					m.Ack()
+					return
+				}
+				c.mu.Lock()
+				c.unacked = append(c.unacked, m)
+				c.mu.Unlock()
+			})
+		}(topic, sub)
+	}
+
+	var receiveErr error
+	for range subscriptions {
+		if err := <-errs; err != nil && receiveErr == nil {
+			receiveErr = err
+			cancel()
+		}
+	}
+
+	switch {
+	case handlerErr != nil:
+		return handlerErr
+	case receiveErr != nil:
+		return fmt.Errorf("failed to receive messages: %w", receiveErr)
+	default:
+		return ctx.Err()
+	}
+}
+
+// Commit acknowledges the messages handled since the last commit
+func (c *Consumer) Commit(ctx context.Context) error {
+	c.mu.Lock()
+	messages := c.unacked
+	c.unacked = nil
+	c.mu.Unlock()
+
+	for _, m := range messages {
+		m.Ack()
+	}
+	return nil
+}
+
+// Seek moves the subscription of a topic to the messages published from offset on, in
+// milliseconds since the epoch. Messages acknowledged since are delivered again.
+func (c *Consumer) Seek(ctx context.Context, topic string, partition int32, offset int64) error {
+	c.mu.Lock()
+	sub, ok := c.subscriptions[topic]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("not subscribed to topic %s", topic)
+	}
+	if err := sub.SeekToTime(ctx, time.UnixMilli(offset)); err != nil {
+		return fmt.Errorf("failed to seek subscription %s: %w", sub.ID(), err)
+	}
+	return nil
+}
+
+// Close returns the handled messages that were not committed to the subscription, so that they
+// are delivered again without waiting for their deadline
+func (c *Consumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nackLocked()
+	c.subscriptions = nil
+	return nil
+}
+
+func (c *Consumer) nackLocked() {
+	for _, m := range c.unacked {
+		m.Nack()
+	}
+	c.unacked = nil
+}
+
+// convertMessage returns a Pub/Sub message with its publish time as offset, which is the
+// position Seek takes
+func convertMessage(topic string, m *pubsub.Message) *adapter.Message {
+	metadata := map[string]interface{}{"message_id": m.ID}
+	if m.DeliveryAttempt != nil {
+		metadata["delivery_attempt"] = *m.DeliveryAttempt
+	}
+	return &adapter.Message{
+		Topic:     topic,
+		Offset:    m.PublishTime.UnixMilli(),
+		Key:       []byte(m.OrderingKey),
+		Value:     m.Data,
+		Headers:   m.Attributes,
+		Timestamp: m.PublishTime,
+		Metadata:  metadata,
+	}
+}
@@ -0,0 +1,118 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+)
+
+// Pub/Sub rejects messages over 10MB
+const maxMessageBytes = 10 * 1024 * 1024
+
+// Producer publishes to Pub/Sub topics. The key of a message is its ordering key, so that
+// subscriptions with ordering enabled receive the messages of a key in order; messages without
+// a key are not ordered.
+//
+// After a failed publish, Pub/Sub rejects the later messages of the ordering key until the key
+// is resumed. Produce resumes the keys that failed before returning the error, so that the
+// caller can publish them again.
+type Producer struct {
+	conn    *Connection
+	pending sync.WaitGroup // ProduceAsync calls in flight
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+// Produce publishes messages to a topic and returns once all of them were accepted
+func (p *Producer) Produce(ctx context.Context, topic string, messages []adapter.Message) error {
+	t := p.topic(topic)
+
+	results := make([]*pubsub.PublishResult, 0, len(messages))
+	for _, msg := range messages {
+		if len(msg.Value) > maxMessageBytes {
+			return fmt.Errorf("message of %d bytes exceeds the Pub/Sub message limit of %d bytes", len(msg.Value), maxMessageBytes)
+		}
+		results = append(results, t.Publish(ctx, &pubsub.Message{
+			Data:        msg.Value,
+			Attributes:  msg.Headers,
+			OrderingKey: string(msg.Key),
+		}))
+	}
+
+	var firstErr error
+	failed := make(map[string]bool)
+	for i, result := range results {
+		if _, err := result.Get(ctx); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to publish to topic %s: %w", topic, err)
+			}
+			failed[string(messages[i].Key)] = true
+		}
+	}
+	for key := range failed {
+		if key != "" {
+			t.ResumePublish(key)
+		}
+	}
+	return firstErr
+}
+
+// ProduceAsync publishes messages in the background and calls callback with the result
+func (p *Producer) ProduceAsync(ctx context.Context, topic string, messages []adapter.Message, callback func(error)) error {
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		err := p.Produce(ctx, topic, messages)
+		if callback != nil {
+			callback(err)
+		}
+	}()
+	return nil
+}
+
+// Flush waits for the messages of ProduceAsync calls to be published
+func (p *Producer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for the messages in flight and stops the publishers of the topics
+func (p *Producer) Close() error {
+	p.pending.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for name, t := range p.topics {
+		t.Stop()
+		delete(p.topics, name)
+	}
+	return nil
+}
+
+// topic returns the publisher of a topic. Publishers batch the messages of a topic in the
+// background and are kept until the producer is closed.
+func (p *Producer) topic(name string) *pubsub.Topic {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t, ok := p.topics[name]; ok {
+		return t
+	}
+	t := p.conn.client.Topic(name)
+	t.EnableMessageOrdering = true
+	p.topics[name] = t
+	return t
+}
@@ -0,0 +1,161 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// avroSchema returns the Avro schema of the events of the table: an Event record with the
+// fields of the JSON events, whose data and old_data are Row records
+func (t *Table) avroSchema() string {
+	optional := func(schema interface{}) []interface{} { return []interface{}{"null", schema} }
+
+	rowFields := make([]map[string]interface{}, 0, len(t.Fields))
+	for _, f := range t.Fields {
+		field := map[string]interface{}{"name": f.Name, "type": optional(avroType(f)), "default": nil}
+		if f.Name != f.Column {
+			field["doc"] = "Column " + f.Column
+		}
+		rowFields = append(rowFields, field)
+	}
+	row := map[string]interface{}{"type": "record", "name": "Row", "fields": rowFields}
+
+	schema := map[string]interface{}{
+		"type":      "record",
+		"name":      "Event",
+		"namespace": t.Namespace,
+		"doc":       "Change of a row of table " + t.Name,
+		"fields": []map[string]interface{}{
+			{"name": "operation", "type": "string"},
+			{"name": "schema_name", "type": "string", "default": ""},
+			{"name": "table_name", "type": "string"},
+			{"name": "timestamp", "type": map[string]string{"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "transaction_id", "type": optional("string"), "default": nil},
+			{"name": "data", "type": optional(row), "default": nil},
+			{"name": "old_data", "type": optional("Row"), "default": nil},
+		},
+	}
+	// Field order is fixed by the slices, so the schema text only changes with the table
+	data, _ := json.Marshal(schema)
+	return string(data)
+}
+
+func avroType(f Field) interface{} {
+	switch f.Kind {
+	case KindBoolean, KindInt, KindLong, KindFloat, KindDouble, KindBytes, KindString:
+		return string(f.Kind)
+	case KindDecimal:
+		return map[string]interface{}{"type": "bytes", "logicalType": "decimal", "precision": f.Precision, "scale": f.Scale}
+	case KindDate:
+		return map[string]string{"type": "int", "logicalType": "date"}
+	case KindTimestamp:
+		return map[string]string{"type": "long", "logicalType": "timestamp-micros"}
+	case KindTime:
+		return map[string]string{"type": "long", "logicalType": "time-micros"}
+	case KindUUID:
+		return map[string]string{"type": "string", "logicalType": "uuid"}
+	}
+	return "string"
+}
+
+// encodeAvro appends the Avro binary encoding of an event to buf
+func (t *Table) encodeAvro(buf []byte, event *adapter.CDCEvent) ([]byte, error) {
+	buf = appendAvroString(buf, string(event.Operation))
+	buf = appendAvroString(buf, event.SchemaName)
+	buf = appendAvroString(buf, event.TableName)
+	buf = appendAvroLong(buf, event.Timestamp.UnixMilli())
+	if event.TransactionID == "" {
+		buf = appendAvroLong(buf, 0)
+	} else {
+		buf = appendAvroString(appendAvroLong(buf, 1), event.TransactionID)
+	}
+
+	var err error
+	for _, data := range []map[string]interface{}{event.Data, event.OldData} {
+		if len(data) == 0 {
+			buf = appendAvroLong(buf, 0)
+			continue
+		}
+		buf = appendAvroLong(buf, 1)
+		if buf, err = t.encodeAvroRow(buf, data); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func (t *Table) encodeAvroRow(buf []byte, data map[string]interface{}) ([]byte, error) {
+	for _, f := range t.Fields {
+		value, ok := data[f.Column]
+		if !ok || value == nil {
+			buf = appendAvroLong(buf, 0)
+			continue
+		}
+		buf = appendAvroLong(buf, 1)
+		var err error
+		if buf, err = appendAvroValue(buf, f, value); err != nil {
+			return nil, fmt.Errorf("column %s: %w", f.Column, err)
+		}
+	}
+	return buf, nil
+}
+
+func appendAvroValue(buf []byte, f Field, value interface{}) ([]byte, error) {
+	switch f.Kind {
+	case KindBoolean:
+		v, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if v {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case KindInt:
+		v, err := toInt32(value)
+		return appendAvroLong(buf, int64(v)), err
+	case KindLong:
+		v, err := toInt64(value)
+		return appendAvroLong(buf, v), err
+	case KindFloat:
+		v, err := toFloat64(value)
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v))), err
+	case KindDouble:
+		v, err := toFloat64(value)
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v)), err
+	case KindDecimal:
+		v, err := toUnscaled(value, f.Scale)
+		return appendAvroBytes(buf, v), err
+	case KindBytes:
+		v, err := toBytes(value)
+		return appendAvroBytes(buf, v), err
+	case KindDate:
+		v, err := toEpochDays(value)
+		return appendAvroLong(buf, int64(v)), err
+	case KindTimestamp:
+		v, err := toEpochMicros(value)
+		return appendAvroLong(buf, v), err
+	case KindTime:
+		v, err := toTimeMicros(value)
+		return appendAvroLong(buf, v), err
+	}
+	v, err := toString(value)
+	return appendAvroString(buf, v), err
+}
+
+// appendAvroLong appends an int or long, zig-zag encoded as a variable-length integer
+func appendAvroLong(buf []byte, v int64) []byte {
+	return binary.AppendUvarint(buf, uint64(v<<1)^uint64(v>>63))
+}
+
+func appendAvroBytes(buf []byte, v []byte) []byte {
+	return append(appendAvroLong(buf, int64(len(v))), v...)
+}
+
+func appendAvroString(buf []byte, v string) []byte {
+	return append(appendAvroLong(buf, int64(len(v))), v...)
+}
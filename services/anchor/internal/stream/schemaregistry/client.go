@@ -0,0 +1,136 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// maxResponse bounds the schema registry response read into memory
+const maxResponse = 4 << 20
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// Client registers schemas through the REST API of a Confluent-compatible schema registry.
+// Registered schemas are cached by subject and content, so a schema is sent to the registry
+// once per client, and again only when it changes.
+type Client struct {
+	baseURL       string
+	username      string
+	password      string
+	compatibility string
+	http          *http.Client
+
+	mu         sync.Mutex
+	ids        map[string]int  // subject and schema -> ID
+	configured map[string]bool // subjects whose compatibility was set
+}
+
+// NewClient creates a client of the registry of config
+func NewClient(config *Config) *Client {
+	baseURL := config.URL
+	if config.Type == RegistryApicurio && !strings.Contains(baseURL, "/apis/ccompat/") {
+		baseURL += "/apis/ccompat/v7"
+	}
+	return &Client{
+		baseURL:       baseURL,
+		username:      config.Username,
+		password:      config.Password,
+		compatibility: config.Compatibility,
+		http:          &http.Client{Timeout: config.Timeout},
+		ids:           make(map[string]int),
+		configured:    make(map[string]bool),
+	}
+}
+
+// Register registers a schema under a subject, or looks up its ID if the subject already has
+// it, and returns the ID. schemaType is AVRO or PROTOBUF. With a compatibility mode, the mode
+// of the subject is set first, so that the registry rejects versions that break it.
+func (c *Client) Register(ctx context.Context, subject, schemaType, schema string) (int, error) {
+	key := subject + "\x00" + schema
+	c.mu.Lock()
+	id, ok := c.ids[key]
+	configured := c.configured[subject]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	if c.compatibility != "" && !configured {
+		body := map[string]string{"compatibility": c.compatibility}
+		if err := c.do(ctx, http.MethodPut, "/config/"+url.PathEscape(subject), body, nil); err != nil {
+			return 0, fmt.Errorf("failed to set compatibility %s of subject %s: %w", c.compatibility, subject, err)
+		}
+		c.mu.Lock()
+		c.configured[subject] = true
+		c.mu.Unlock()
+	}
+
+	request := map[string]string{"schema": schema}
+	if schemaType != "AVRO" {
+		request["schemaType"] = schemaType
+	}
+	var response struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", request, &response); err != nil {
+		return 0, fmt.Errorf("failed to register schema of subject %s: %w", subject, err)
+	}
+
+	c.mu.Lock()
+	c.ids[key] = response.ID
+	c.mu.Unlock()
+	return response.ID, nil
+}
+
+// do sends a request to the registry and decodes its response into result, if not nil
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Errors carry the reason, such as the incompatibility of a schema, in message
+		var registryErr struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.Unmarshal(data, &registryErr) == nil && registryErr.Message != "" {
+			return fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, registryErr.Message)
+		}
+		return fmt.Errorf("schema registry returned %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("invalid schema registry response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package schemaregistry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format is the encoding of the values of CDC events produced to a stream
+type Format string
+
+const (
+	FormatJSON     Format = "json" // Self-describing JSON, without a schema registry
+	FormatAvro     Format = "avro"
+	FormatProtobuf Format = "protobuf"
+)
+
+// RegistryType selects the API of the schema registry
+type RegistryType string
+
+const (
+	RegistryConfluent RegistryType = "confluent"
+	// Apicurio is reached through its Confluent-compatible API, at /apis/ccompat/v7 of the
+	// registry unless the URL already points at a ccompat API
+	RegistryApicurio RegistryType = "apicurio"
+)
+
+// SubjectStrategy names the subject schemas are registered under, like the subject name
+// strategies of Confluent serializers
+type SubjectStrategy string
+
+const (
+	SubjectTopic       SubjectStrategy = "topic"        // <topic>-value, one table per topic
+	SubjectRecord      SubjectStrategy = "record"       // <record name>, shared by the topics of a table
+	SubjectTopicRecord SubjectStrategy = "topic_record" // <topic>-<record name>, several tables per topic
+)
+
+var compatibilityModes = []string{
+	"NONE", "BACKWARD", "BACKWARD_TRANSITIVE", "FORWARD", "FORWARD_TRANSITIVE", "FULL", "FULL_TRANSITIVE",
+}
+
+// Config is the schema registry configuration of a stream connection
+type Config struct {
+	URL             string
+	Type            RegistryType
+	Username        string // Basic authentication, such as a Confluent Cloud API key and secret
+	Password        string
+	Format          Format
+	Compatibility   string // Set on subjects before registering; empty keeps the registry default
+	SubjectStrategy SubjectStrategy
+	Timeout         time.Duration
+}
+
+// ConfigFromMap reads the schema registry settings of the configuration of a stream connection:
+//
+//	schema_format             json (default), avro or protobuf
+//	schema_registry_url       required for avro and protobuf
+//	schema_registry_type      confluent (default) or apicurio
+//	schema_registry_username  optional basic authentication
+//	schema_registry_password
+//	schema_compatibility      NONE, BACKWARD, FORWARD, FULL or their _TRANSITIVE variants
+//	schema_subject_strategy   topic (default), record or topic_record
+//
+// It returns nil when events are produced as JSON.
+func ConfigFromMap(values map[string]string) (*Config, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(values["schema_format"])))
+	switch format {
+	case "", FormatJSON:
+		return nil, nil
+	case FormatAvro, FormatProtobuf:
+	default:
+		return nil, fmt.Errorf("unsupported schema_format %q, expected json, avro or protobuf", format)
+	}
+
+	config := &Config{
+		URL:             strings.TrimRight(strings.TrimSpace(values["schema_registry_url"]), "/"),
+		Type:            RegistryType(strings.ToLower(values["schema_registry_type"])),
+		Username:        values["schema_registry_username"],
+		Password:        values["schema_registry_password"],
+		Format:          format,
+		Compatibility:   strings.ToUpper(strings.TrimSpace(values["schema_compatibility"])),
+		SubjectStrategy: SubjectStrategy(strings.ToLower(values["schema_subject_strategy"])),
+		Timeout:         30 * time.Second,
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("schema_registry_url is required for the %s format", format)
+	}
+	switch config.Type {
+	case "":
+		config.Type = RegistryConfluent
+	case RegistryConfluent, RegistryApicurio:
+	default:
+		return nil, fmt.Errorf("unsupported schema_registry_type %q, expected confluent or apicurio", config.Type)
+	}
+	switch config.SubjectStrategy {
+	case "":
+		config.SubjectStrategy = SubjectTopic
+	case SubjectTopic, SubjectRecord, SubjectTopicRecord:
+	default:
+		return nil, fmt.Errorf("unsupported schema_subject_strategy %q, expected topic, record or topic_record", config.SubjectStrategy)
+	}
+	if config.Compatibility != "" && !contains(compatibilityModes, config.Compatibility) {
+		return nil, fmt.Errorf("unsupported schema_compatibility %q, expected one of %s", config.Compatibility, strings.Join(compatibilityModes, ", "))
+	}
+	return config, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
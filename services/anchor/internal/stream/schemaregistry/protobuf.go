@@ -0,0 +1,177 @@
+package schemaregistry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the Event message
+const (
+	protoOperation     = 1
+	protoSchemaName    = 2
+	protoTableName     = 3
+	protoTimestamp     = 4
+	protoTransactionID = 5
+	protoData          = 6
+	protoOldData       = 7
+)
+
+// protobufSchema returns the Protobuf schema of the events of the table: an Event message with
+// the fields of the JSON events, whose data and old_data are nested Row messages. Row fields
+// are optional so that null columns are told apart from zero values.
+func (t *Table) protobufSchema() string {
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n\n", t.Namespace)
+	fmt.Fprintf(&b, "// Change of a row of table %s\n", t.Name)
+	b.WriteString("message Event {\n")
+	b.WriteString("  message Row {\n")
+	for _, f := range t.Fields {
+		fmt.Fprintf(&b, "    optional %s %s = %d;", protobufType(f.Kind), f.Name, f.Number)
+		if comment := protobufComment(f); comment != "" {
+			fmt.Fprintf(&b, " // %s", comment)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("  }\n\n")
+	fmt.Fprintf(&b, "  string operation = %d;\n", protoOperation)
+	fmt.Fprintf(&b, "  string schema_name = %d;\n", protoSchemaName)
+	fmt.Fprintf(&b, "  string table_name = %d;\n", protoTableName)
+	fmt.Fprintf(&b, "  int64 timestamp = %d; // Milliseconds since the Unix epoch\n", protoTimestamp)
+	fmt.Fprintf(&b, "  string transaction_id = %d;\n", protoTransactionID)
+	fmt.Fprintf(&b, "  Row data = %d;\n", protoData)
+	fmt.Fprintf(&b, "  Row old_data = %d;\n", protoOldData)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func protobufType(kind Kind) string {
+	switch kind {
+	case KindBoolean:
+		return "bool"
+	case KindInt, KindDate:
+		return "int32"
+	case KindLong, KindTimestamp, KindTime:
+		return "int64"
+	case KindFloat:
+		return "float"
+	case KindDouble:
+		return "double"
+	case KindBytes:
+		return "bytes"
+	}
+	return "string"
+}
+
+// protobufComment documents the encoding of the kinds Protobuf has no type for
+func protobufComment(f Field) string {
+	var notes []string
+	switch f.Kind {
+	case KindDecimal:
+		notes = append(notes, fmt.Sprintf("Decimal(%d,%d)", f.Precision, f.Scale))
+	case KindDate:
+		notes = append(notes, "Days since the Unix epoch")
+	case KindTimestamp:
+		notes = append(notes, "Microseconds since the Unix epoch")
+	case KindTime:
+		notes = append(notes, "Microseconds since midnight")
+	case KindUUID:
+		notes = append(notes, "UUID")
+	}
+	if f.Name != f.Column {
+		notes = append(notes, "Column "+f.Column)
+	}
+	return strings.Join(notes, ", ")
+}
+
+// encodeProtobuf appends the Protobuf encoding of an event to buf
+func (t *Table) encodeProtobuf(buf []byte, event *adapter.CDCEvent) ([]byte, error) {
+	buf = appendProtoString(buf, protoOperation, string(event.Operation))
+	buf = appendProtoString(buf, protoSchemaName, event.SchemaName)
+	buf = appendProtoString(buf, protoTableName, event.TableName)
+	if ms := event.Timestamp.UnixMilli(); ms != 0 {
+		buf = protowire.AppendTag(buf, protoTimestamp, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(ms))
+	}
+	buf = appendProtoString(buf, protoTransactionID, event.TransactionID)
+
+	rows := []struct {
+		number protowire.Number
+		data   map[string]interface{}
+	}{{protoData, event.Data}, {protoOldData, event.OldData}}
+	for _, r := range rows {
+		if len(r.data) == 0 {
+			continue
+		}
+		row, err := t.encodeProtobufRow(r.data)
+		if err != nil {
+			return nil, err
+		}
+		buf = protowire.AppendTag(buf, r.number, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, row)
+	}
+	return buf, nil
+}
+
+func (t *Table) encodeProtobufRow(data map[string]interface{}) ([]byte, error) {
+	var buf []byte
+	for _, f := range t.Fields {
+		value, ok := data[f.Column]
+		if !ok || value == nil {
+			continue
+		}
+		var err error
+		if buf, err = appendProtoValue(buf, f, value); err != nil {
+			return nil, fmt.Errorf("column %s: %w", f.Column, err)
+		}
+	}
+	return buf, nil
+}
+
+// appendProtoValue appends a field that is set; optional fields are written even when zero
+func appendProtoValue(buf []byte, f Field, value interface{}) ([]byte, error) {
+	number := protowire.Number(f.Number)
+	switch f.Kind {
+	case KindBoolean:
+		v, err := toBool(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), protowire.EncodeBool(v)), err
+	case KindInt:
+		v, err := toInt32(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), uint64(int64(v))), err
+	case KindLong:
+		v, err := toInt64(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), uint64(v)), err
+	case KindFloat:
+		v, err := toFloat64(value)
+		return protowire.AppendFixed32(protowire.AppendTag(buf, number, protowire.Fixed32Type), math.Float32bits(float32(v))), err
+	case KindDouble:
+		v, err := toFloat64(value)
+		return protowire.AppendFixed64(protowire.AppendTag(buf, number, protowire.Fixed64Type), math.Float64bits(v)), err
+	case KindBytes:
+		v, err := toBytes(value)
+		return protowire.AppendBytes(protowire.AppendTag(buf, number, protowire.BytesType), v), err
+	case KindDate:
+		v, err := toEpochDays(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), uint64(int64(v))), err
+	case KindTimestamp:
+		v, err := toEpochMicros(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), uint64(v)), err
+	case KindTime:
+		v, err := toTimeMicros(value)
+		return protowire.AppendVarint(protowire.AppendTag(buf, number, protowire.VarintType), uint64(v)), err
+	}
+	v, err := toString(value)
+	return protowire.AppendString(protowire.AppendTag(buf, number, protowire.BytesType), v), err
+}
+
+// appendProtoString appends a string field unless it is empty, as proto3 leaves out defaults
+func appendProtoString(buf []byte, number protowire.Number, v string) []byte {
+	if v == "" {
+		return buf
+	}
+	return protowire.AppendString(protowire.AppendTag(buf, number, protowire.BytesType), v)
+}
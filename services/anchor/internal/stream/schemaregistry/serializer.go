@@ -0,0 +1,65 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+)
+
+// Serializer encodes CDC events in the Confluent wire format: a zero byte, the 4-byte
+// big-endian ID of the schema in the registry, then the Avro or Protobuf value. Protobuf values
+// are preceded by the index of their message in the schema, a single zero byte for Event.
+//
+// The schema of a table is registered the first time one of its events is serialized, and
+// again when its shape changes, so that the registry checks each new version against the
+// compatibility mode of the subject.
+type Serializer struct {
+	config *Config
+	client *Client
+}
+
+// NewSerializer creates a serializer registering schemas in the registry of config
+func NewSerializer(config *Config) *Serializer {
+	return &Serializer{config: config, client: NewClient(config)}
+}
+
+// Format returns the format of the values produced by the serializer
+func (s *Serializer) Format() Format {
+	return s.config.Format
+}
+
+// Subject returns the subject the schema of a table is registered under when produced to topic
+func (s *Serializer) Subject(topic string, table *Table) string {
+	switch s.config.SubjectStrategy {
+	case SubjectRecord:
+		return table.RecordName()
+	case SubjectTopicRecord:
+		return topic + "-" + table.RecordName()
+	}
+	return topic + "-value"
+}
+
+// Serialize registers the schema of the table if needed and returns the encoded event
+func (s *Serializer) Serialize(ctx context.Context, topic string, table *Table, event *adapter.CDCEvent) ([]byte, error) {
+	schemaType, schema := "AVRO", table.avro
+	if s.config.Format == FormatProtobuf {
+		schemaType, schema = "PROTOBUF", table.protobuf
+	}
+	id, err := s.client.Register(ctx, s.Subject(topic, table), schemaType, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := binary.BigEndian.AppendUint32([]byte{0}, uint32(id))
+	if s.config.Format == FormatProtobuf {
+		buf, err = table.encodeProtobuf(append(buf, 0), event)
+	} else {
+		buf, err = table.encodeAvro(buf, event)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event of table %s as %s: %w", table.Name, s.config.Format, err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,340 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func position(n int) *int { return &n }
+
+func ordersTable() unifiedmodel.Table {
+	return unifiedmodel.Table{
+		Name: "orders",
+		Columns: map[string]unifiedmodel.Column{
+			"id":         {Name: "id", DataType: "bigint", OrdinalPosition: position(1)},
+			"total":      {Name: "total", DataType: "numeric(10,2)", OrdinalPosition: position(2)},
+			"paid":       {Name: "paid", DataType: "boolean", OrdinalPosition: position(4)},
+			"created-at": {Name: "created-at", DataType: "timestamp with time zone", OrdinalPosition: position(5)},
+			"note":       {Name: "note", DataType: "character varying(200)", OrdinalPosition: position(6)},
+		},
+	}
+}
+
+// registry is a schema registry recording the requests it receives
+type registry struct {
+	mu       sync.Mutex
+	requests []string
+	schemas  map[string]map[string]string
+}
+
+func newRegistry(t *testing.T) (*registry, *httptest.Server) {
+	r := &registry{schemas: make(map[string]map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req.Method+" "+req.URL.EscapedPath())
+		var payload map[string]string
+		json.Unmarshal(body, &payload)
+		switch {
+		case req.Method == http.MethodPut:
+			w.Write(body)
+		case strings.HasSuffix(req.URL.Path, "/versions"):
+			r.schemas[req.URL.Path] = payload
+			w.Write([]byte(`{"id":42}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func TestConfigFromMap(t *testing.T) {
+	config, err := ConfigFromMap(map[string]string{})
+	if config != nil || err != nil {
+		t.Errorf("ConfigFromMap() without a format = %v, %v, want JSON events", config, err)
+	}
+
+	config, err = ConfigFromMap(map[string]string{
+		"schema_format":        "Avro",
+		"schema_registry_url":  "http://registry:8080/",
+		"schema_registry_type": "apicurio",
+		"schema_compatibility": "backward_transitive",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Format != FormatAvro || config.URL != "http://registry:8080" || config.Compatibility != "BACKWARD_TRANSITIVE" || config.SubjectStrategy != SubjectTopic {
+		t.Errorf("config = %+v", config)
+	}
+	if client := NewClient(config); client.baseURL != "http://registry:8080/apis/ccompat/v7" {
+		t.Errorf("Apicurio base URL = %s", client.baseURL)
+	}
+
+	for _, values := range []map[string]string{
+		{"schema_format": "avro"},
+		{"schema_format": "thrift", "schema_registry_url": "http://registry"},
+		{"schema_format": "avro", "schema_registry_url": "http://registry", "schema_compatibility": "LOOSE"},
+		{"schema_format": "avro", "schema_registry_url": "http://registry", "schema_subject_strategy": "table"},
+	} {
+		if _, err := ConfigFromMap(values); err == nil {
+			t.Errorf("ConfigFromMap(%v) succeeded", values)
+		}
+	}
+}
+
+func TestNewTable(t *testing.T) {
+	table := NewTable("public", ordersTable(), nil)
+	if table.Namespace != "redb.cdc.public.orders" {
+		t.Errorf("namespace = %s", table.Namespace)
+	}
+	want := []Field{
+		{Column: "id", Name: "id", Number: 1, Kind: KindLong},
+		{Column: "total", Name: "total", Number: 2, Kind: KindDecimal, Precision: 10, Scale: 2},
+		{Column: "paid", Name: "paid", Number: 4, Kind: KindBoolean},
+		{Column: "created-at", Name: "created_at", Number: 5, Kind: KindTimestamp},
+		{Column: "note", Name: "note", Number: 6, Kind: KindString},
+	}
+	if len(table.Fields) != len(want) {
+		t.Fatalf("fields = %+v", table.Fields)
+	}
+	for i, f := range table.Fields {
+		if f != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, f, want[i])
+		}
+	}
+	if missing := table.Missing(map[string]interface{}{"id": 1, "discount": 2}); len(missing) != 1 || missing[0] != "discount" {
+		t.Errorf("Missing() = %v", missing)
+	}
+
+	rules := []adapter.TransformationRule{
+		{SourceColumn: "id", TargetColumn: "order_id", TransformationType: "direct"},
+		{SourceColumn: "note", TargetColumn: "note_upper", TransformationType: "function", TransformationName: "uppercase"},
+		{SourceColumn: "id", SourceTable: "customers", TargetColumn: "customer_id"},
+	}
+	mapped := NewTable("public", ordersTable(), rules)
+	if len(mapped.Fields) != 2 || mapped.Fields[0].Column != "order_id" || mapped.Fields[0].Kind != KindLong || mapped.Fields[1].Kind != KindString {
+		t.Errorf("mapped fields = %+v", mapped.Fields)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := map[string]Kind{
+		"integer":                     KindInt,
+		"int unsigned":                KindLong,
+		"bigint unsigned":             KindString,
+		"double precision":            KindDouble,
+		"NUMBER(12)":                  KindDecimal,
+		"numeric":                     KindString,
+		"timestamp(6) with time zone": KindTimestamp,
+		"datetime2":                   KindTimestamp,
+		"time without time zone":      KindTime,
+		"uniqueidentifier":            KindUUID,
+		"bytea":                       KindBytes,
+		"jsonb":                       KindString,
+		"integer[]":                   KindString,
+	}
+	for dataType, want := range tests {
+		if kind, _, _ := kindOf(dataType); kind != want {
+			t.Errorf("kindOf(%q) = %s, want %s", dataType, kind, want)
+		}
+	}
+}
+
+func testEvent() *adapter.CDCEvent {
+	return &adapter.CDCEvent{
+		Operation:  adapter.CDCUpdate,
+		SchemaName: "public",
+		TableName:  "orders",
+		Timestamp:  time.UnixMilli(1700000000123),
+		Data: map[string]interface{}{
+			"id":         int64(7),
+			"total":      "-12.50",
+			"paid":       true,
+			"created-at": "2024-01-02 03:04:05.000006+00",
+			"note":       nil,
+		},
+		OldData: map[string]interface{}{"id": json.Number("7")},
+	}
+}
+
+func TestSerializeAvro(t *testing.T) {
+	reg, server := newRegistry(t)
+	serializer := NewSerializer(&Config{URL: server.URL, Format: FormatAvro, Compatibility: "BACKWARD", SubjectStrategy: SubjectTopic, Timeout: time.Second})
+	table := NewTable("public", ordersTable(), nil)
+
+	for i := 0; i < 2; i++ {
+		value, err := serializer.Serialize(context.Background(), "orders-cdc", table, testEvent())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value[0] != 0 || binary.BigEndian.Uint32(value[1:5]) != 42 {
+			t.Fatalf("header = %x, want magic byte and schema ID 42", value[:5])
+		}
+
+		d := &avroReader{data: value[5:]}
+		if op, schema, name := d.string(), d.string(), d.string(); op != "UPDATE" || schema != "public" || name != "orders" {
+			t.Errorf("envelope = %s %s.%s", op, schema, name)
+		}
+		if ts := d.long(); ts != 1700000000123 {
+			t.Errorf("timestamp = %d", ts)
+		}
+		if d.long() != 0 {
+			t.Error("transaction_id is set")
+		}
+		if d.long() != 1 || d.long() != 1 || d.long() != 7 {
+			t.Error("data.id is not 7")
+		}
+		if d.long() != 1 || string(d.bytes()) != "\xfb\x1e" {
+			t.Error("data.total is not the unscaled -1250")
+		}
+		if d.long() != 1 || d.byte() != 1 {
+			t.Error("data.paid is not true")
+		}
+		if d.long() != 1 || d.long() != time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC).UnixMicro() {
+			t.Error("data.created_at is not the timestamp in microseconds")
+		}
+		if d.long() != 0 {
+			t.Error("data.note is not null")
+		}
+		if d.long() != 1 || d.long() != 1 || d.long() != 7 || d.long() != 0 || d.long() != 0 || d.long() != 0 || d.long() != 0 {
+			t.Error("old_data does not hold only the id")
+		}
+		if d.err != nil || len(d.data) != 0 {
+			t.Errorf("value has %d trailing bytes, err %v", len(d.data), d.err)
+		}
+	}
+
+	if len(reg.requests) != 2 || reg.requests[0] != "PUT /config/orders-cdc-value" || reg.requests[1] != "POST /subjects/orders-cdc-value/versions" {
+		t.Errorf("requests = %v, want the compatibility and schema set once", reg.requests)
+	}
+	var schema map[string]interface{}
+	registered := reg.schemas["/subjects/orders-cdc-value/versions"]
+	if err := json.Unmarshal([]byte(registered["schema"]), &schema); err != nil || schema["name"] != "Event" || registered["schemaType"] != "" {
+		t.Errorf("registered %v", registered)
+	}
+}
+
+func TestSerializeProtobuf(t *testing.T) {
+	reg, server := newRegistry(t)
+	serializer := NewSerializer(&Config{URL: server.URL, Format: FormatProtobuf, SubjectStrategy: SubjectTopicRecord, Timeout: time.Second})
+	table := NewTable("public", ordersTable(), nil)
+
+	value, err := serializer.Serialize(context.Background(), "cdc", table, testEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value[0] != 0 || binary.BigEndian.Uint32(value[1:5]) != 42 || value[5] != 0 {
+		t.Fatalf("header = %x, want magic byte, schema ID 42 and message index 0", value[:6])
+	}
+
+	fields := protoFields(t, value[6:])
+	if string(fields[protoOperation]) != "UPDATE" || string(fields[protoTableName]) != "orders" {
+		t.Errorf("envelope fields = %v", fields)
+	}
+	row := protoFields(t, fields[protoData])
+	if _, ok := row[6]; ok {
+		t.Error("null note is set")
+	}
+	if string(row[2]) != "-12.50" {
+		t.Errorf("total = %q, want the decimal as a string", row[2])
+	}
+
+	subject := "/subjects/cdc-redb.cdc.public.orders.Event/versions"
+	registered := reg.schemas[subject]
+	if registered["schemaType"] != "PROTOBUF" || !strings.Contains(registered["schema"], "optional int64 created_at = 5; // Microseconds since the Unix epoch, Column created-at") {
+		t.Errorf("registered under %v: %v", reg.requests, registered["schema"])
+	}
+}
+
+func TestRegisterReportsIncompatibleSchemas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error_code":409,"message":"Schema being registered is incompatible with an earlier schema"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{URL: server.URL, Timeout: time.Second})
+	_, err := client.Register(context.Background(), "orders-value", "AVRO", `"string"`)
+	if err == nil || !strings.Contains(err.Error(), "incompatible with an earlier schema") {
+		t.Errorf("Register() = %v, want the incompatibility", err)
+	}
+}
+
+// avroReader decodes the primitive values of an Avro binary encoding
+type avroReader struct {
+	data []byte
+	err  error
+}
+
+func (r *avroReader) long() int64 {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = io.ErrUnexpectedEOF
+		return -1
+	}
+	r.data = r.data[n:]
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *avroReader) bytes() []byte {
+	n := int(r.long())
+	if n < 0 || n > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *avroReader) string() string { return string(r.bytes()) }
+
+func (r *avroReader) byte() byte {
+	if len(r.data) == 0 {
+		r.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := r.data[0]
+	r.data = r.data[1:]
+	return v
+}
+
+// protoFields returns the length-delimited fields of a message, and the others as their raw bytes
+func protoFields(t *testing.T, data []byte) map[protowire.Number][]byte {
+	t.Helper()
+	fields := make(map[protowire.Number][]byte)
+	for len(data) > 0 {
+		number, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		data = data[n:]
+		m := protowire.ConsumeFieldValue(number, typ, data)
+		if m < 0 {
+			t.Fatalf("invalid field %d: %v", number, protowire.ParseError(m))
+		}
+		if typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(data)
+			fields[number] = v
+		} else {
+			fields[number] = data[:m]
+		}
+		data = data[m:]
+	}
+	return fields
+}
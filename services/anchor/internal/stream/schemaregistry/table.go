@@ -0,0 +1,271 @@
+package schemaregistry
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/anchor/adapter"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Kind is the type a column is encoded as, whatever database it comes from
+type Kind string
+
+const (
+	KindString    Kind = "string"
+	KindBoolean   Kind = "boolean"
+	KindInt       Kind = "int"  // 32-bit integer
+	KindLong      Kind = "long" // 64-bit integer
+	KindFloat     Kind = "float"
+	KindDouble    Kind = "double"
+	KindDecimal   Kind = "decimal" // Exact number with a declared precision and scale
+	KindBytes     Kind = "bytes"
+	KindDate      Kind = "date"      // Days since the Unix epoch
+	KindTimestamp Kind = "timestamp" // Microseconds since the Unix epoch
+	KindTime      Kind = "time"      // Microseconds since midnight
+	KindUUID      Kind = "uuid"
+)
+
+// Field is a column of the rows of a table
+type Field struct {
+	Column    string // Key of the column in the data of events
+	Name      string // Name of the field in schemas, the column name made a valid identifier
+	Number    int    // Protobuf field number
+	Kind      Kind
+	Precision int // Of decimals
+	Scale     int
+}
+
+// Table is the shape of the rows of a table in the events produced for it, derived from the
+// UnifiedModel of the source database. Every field is optional: old data of updates often only
+// holds the key columns, and optional fields let columns be added and dropped without breaking
+// backward or forward compatibility.
+type Table struct {
+	Schema    string // Schema of the table in the source database, may be empty
+	Name      string
+	Namespace string // Namespace of the records, redb.cdc[.<schema>].<table>
+	Fields    []Field
+
+	columns  map[string]bool
+	avro     string
+	protobuf string
+}
+
+// NewTable derives the shape of the rows of a table. With mapping rules, rows hold the target
+// columns of the rules of the table, typed like their source column when copied directly and
+// as strings when transformed.
+func NewTable(schema string, table unifiedmodel.Table, rules []adapter.TransformationRule) *Table {
+	t := &Table{
+		Schema:    schema,
+		Name:      table.Name,
+		Namespace: namespace(schema, table.Name),
+		columns:   make(map[string]bool),
+	}
+
+	var mapped []adapter.TransformationRule
+	for _, rule := range rules {
+		if rule.TargetColumn != "" && (rule.SourceTable == "" || rule.SourceTable == table.Name) {
+			mapped = append(mapped, rule)
+		}
+	}
+
+	if len(mapped) > 0 {
+		for i, rule := range mapped {
+			kind, precision, scale := KindString, 0, 0
+			if column, ok := table.Columns[rule.SourceColumn]; ok && (rule.TransformationType == "" || rule.TransformationType == "direct") {
+				kind, precision, scale = kindOf(column.DataType)
+			}
+			t.addField(rule.TargetColumn, i+1, kind, precision, scale)
+		}
+	} else {
+		for i, column := range orderedColumns(table) {
+			// Field numbers follow ordinal positions, which databases keep when other columns are
+			// dropped, so that Protobuf schemas stay compatible
+			number := i + 1
+			if column.OrdinalPosition != nil && *column.OrdinalPosition > 0 {
+				number = *column.OrdinalPosition
+			}
+			kind, precision, scale := kindOf(column.DataType)
+			t.addField(column.Name, number, kind, precision, scale)
+		}
+	}
+
+	t.avro = t.avroSchema()
+	t.protobuf = t.protobufSchema()
+	return t
+}
+
+func (t *Table) addField(column string, number int, kind Kind, precision, scale int) {
+	if t.columns[column] {
+		return
+	}
+	name := identifier(column)
+	for taken := true; taken; {
+		taken = false
+		for _, f := range t.Fields {
+			if f.Name == name {
+				name += "_"
+				taken = true
+			}
+			if f.Number == number {
+				number = t.maxNumber() + 1
+				taken = true
+			}
+		}
+	}
+	t.columns[column] = true
+	t.Fields = append(t.Fields, Field{Column: column, Name: name, Number: number, Kind: kind, Precision: precision, Scale: scale})
+}
+
+func (t *Table) maxNumber() int {
+	max := 0
+	for _, f := range t.Fields {
+		if f.Number > max {
+			max = f.Number
+		}
+	}
+	return max
+}
+
+// RecordName returns the full name of the event record, which names subjects with the record
+// strategies
+func (t *Table) RecordName() string {
+	return t.Namespace + ".Event"
+}
+
+// Missing returns the columns of data that rows of the table do not have, sorted. Events with
+// such columns were captured after a schema change, and need a new shape of the table.
+func (t *Table) Missing(data map[string]interface{}) []string {
+	var missing []string
+	for column := range data {
+		if !t.columns[column] {
+			missing = append(missing, column)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// orderedColumns returns the columns of a table by ordinal position, then name
+func orderedColumns(table unifiedmodel.Table) []unifiedmodel.Column {
+	columns := make([]unifiedmodel.Column, 0, len(table.Columns))
+	for name, column := range table.Columns {
+		if column.Name == "" {
+			column.Name = name
+		}
+		columns = append(columns, column)
+	}
+	position := func(c unifiedmodel.Column) int {
+		if c.OrdinalPosition == nil {
+			return int(^uint(0) >> 1)
+		}
+		return *c.OrdinalPosition
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		if pi, pj := position(columns[i]), position(columns[j]); pi != pj {
+			return pi < pj
+		}
+		return columns[i].Name < columns[j].Name
+	})
+	return columns
+}
+
+// kindOf maps the native data type of a column to the kind it is encoded as. Decimals without
+// a declared precision, unsigned 64-bit integers and types without an equivalent, such as JSON,
+// intervals and arrays, are encoded as strings.
+func kindOf(dataType string) (Kind, int, int) {
+	t := strings.ToLower(strings.TrimSpace(dataType))
+	if strings.HasSuffix(t, "[]") || strings.HasPrefix(t, "array") {
+		return KindString, 0, 0
+	}
+	var params []int
+	if open := strings.IndexByte(t, '('); open >= 0 {
+		if end := strings.IndexByte(t[open:], ')'); end >= 0 {
+			for _, p := range strings.Split(t[open+1:open+end], ",") {
+				if n, err := strconv.Atoi(strings.TrimSpace(p)); err == nil {
+					params = append(params, n)
+				}
+			}
+			t = strings.TrimSpace(t[:open] + t[open+end+1:])
+		}
+	}
+	unsigned := strings.Contains(t, "unsigned")
+	base := strings.Fields(strings.ReplaceAll(t, "unsigned", ""))
+	if len(base) == 0 {
+		return KindString, 0, 0
+	}
+
+	switch base[0] {
+	case "bool", "boolean":
+		return KindBoolean, 0, 0
+	case "tinyint", "smallint", "int2", "mediumint", "smallserial", "serial2", "year":
+		return KindInt, 0, 0
+	case "int", "integer", "int4", "serial", "serial4":
+		if unsigned {
+			return KindLong, 0, 0
+		}
+		return KindInt, 0, 0
+	case "bigint", "int8", "bigserial", "serial8", "long":
+		if unsigned {
+			return KindString, 0, 0
+		}
+		return KindLong, 0, 0
+	case "real", "float4", "binary_float":
+		return KindFloat, 0, 0
+	case "float", "float8", "double", "binary_double":
+		return KindDouble, 0, 0
+	case "decimal", "numeric", "number", "dec":
+		if len(params) == 0 || params[0] <= 0 {
+			return KindString, 0, 0
+		}
+		scale := 0
+		if len(params) > 1 && params[1] >= 0 {
+			scale = params[1]
+		}
+		return KindDecimal, params[0], scale
+	case "date":
+		return KindDate, 0, 0
+	case "timestamp", "timestamptz", "datetime", "datetime2", "smalldatetime", "datetimeoffset":
+		return KindTimestamp, 0, 0
+	case "time", "timetz":
+		return KindTime, 0, 0
+	case "uuid", "uniqueidentifier":
+		return KindUUID, 0, 0
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "raw", "image":
+		return KindBytes, 0, 0
+	}
+	return KindString, 0, 0
+}
+
+// namespace returns the namespace of the records of a table
+func namespace(schema, table string) string {
+	parts := []string{"redb", "cdc"}
+	if schema != "" {
+		parts = append(parts, identifier(schema))
+	}
+	return strings.Join(append(parts, identifier(table)), ".")
+}
+
+// identifier makes a name valid in Avro and Protobuf schemas: letters, digits and underscores,
+// not starting with a digit
+func identifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
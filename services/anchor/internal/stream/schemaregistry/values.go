@@ -0,0 +1,270 @@
+package schemaregistry
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of CDC events come from the drivers of the source databases, so conversions accept
+// the representations drivers use for a type: native Go values, strings and JSON numbers.
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+var timeOfDayLayouts = []string{
+	"15:04:05.999999999",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999Z07",
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "t", "true", "y", "yes", "1":
+			return true, nil
+		case "f", "false", "n", "no", "0":
+			return false, nil
+		}
+	default:
+		if n, err := toInt64(value); err == nil {
+			return n != 0, nil
+		}
+	}
+	return false, fmt.Errorf("cannot convert %T %v to a boolean", value, value)
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+	case float32:
+		return toInt64(float64(v))
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	case []byte:
+		return strconv.ParseInt(strings.TrimSpace(string(v)), 10, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T %v to an integer", value, value)
+}
+
+func toInt32(value interface{}) (int32, error) {
+	n, err := toInt64(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, fmt.Errorf("%d is out of the range of a 32-bit integer", n)
+	}
+	return int32(n), nil
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	case []byte:
+		return strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+	}
+	if n, err := toInt64(value); err == nil {
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T %v to a number", value, value)
+}
+
+// toString returns strings as they are and other values as text, structured values as JSON
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case [16]byte:
+		// UUIDs of drivers that return them as arrays
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16]), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	if n, err := toInt64(value); err == nil {
+		return strconv.FormatInt(n, 10), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert %T to a string", value)
+	}
+	return string(data), nil
+}
+
+// toBytes returns binary values; strings in the hex format of PostgreSQL bytea are decoded
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if strings.HasPrefix(v, `\x`) {
+			if data, err := hex.DecodeString(v[2:]); err == nil {
+				return data, nil
+			}
+		}
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to bytes", value)
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a timestamp", v)
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T %v to a timestamp", value, value)
+}
+
+// toEpochDays returns the days from the Unix epoch to the date of a value
+func toEpochDays(value interface{}) (int32, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return 0, err
+	}
+	y, m, d := t.Date()
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return int32(days), nil
+}
+
+// toEpochMicros returns the microseconds from the Unix epoch to a timestamp
+func toEpochMicros(value interface{}) (int64, error) {
+	t, err := toTime(value)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMicro(), nil
+}
+
+// toTimeMicros returns the microseconds from midnight to a time of day
+func toTimeMicros(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.Microseconds(), nil
+	case time.Time:
+		return int64(v.Hour())*3600e6 + int64(v.Minute())*60e6 + int64(v.Second())*1e6 + int64(v.Nanosecond())/1e3, nil
+	case string:
+		for _, layout := range timeOfDayLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return toTimeMicros(t)
+			}
+		}
+		return 0, fmt.Errorf("cannot parse %q as a time of day", v)
+	}
+	return 0, fmt.Errorf("cannot convert %T %v to a time of day", value, value)
+}
+
+// toUnscaled returns the unscaled value of a decimal at a scale as the big-endian two's
+// complement bytes Avro decimals are encoded as
+func toUnscaled(value interface{}, scale int) ([]byte, error) {
+	text, err := toString(value)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %q to a decimal", text)
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("%s has more than %d decimal places", text, scale)
+	}
+	return twosComplement(r.Num()), nil
+}
+
+// twosComplement returns the shortest big-endian two's complement representation of n
+func twosComplement(n *big.Int) []byte {
+	if n.Sign() >= 0 {
+		data := n.Bytes()
+		if len(data) == 0 || data[0]&0x80 != 0 {
+			data = append([]byte{0}, data...)
+		}
+		return data
+	}
+	// -n = ^(n-1), so the bytes of n are the complement of those of |n|-1
+	data := new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)).Bytes()
+	for i := range data {
+		data[i] = ^data[i]
+	}
+	if len(data) == 0 || data[0]&0x80 == 0 {
+		data = append([]byte{0xff}, data...)
+	}
+	return data
+}
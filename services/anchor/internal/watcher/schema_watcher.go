@@ -2,6 +2,8 @@ package watcher
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -396,13 +398,25 @@ func (w *SchemaWatcher) checkSchemaChanges(ctx context.Context) error {
 			continue
 		}
 
-		// Get current schema structure as UnifiedModel via adapter
+		// Get current schema structure as UnifiedModel via adapter, re-reading only what
+		// changed since the last schema where the adapter supports it
 		conn := client.AdapterConnection.(adapter.Connection)
-		currentUM, err := conn.SchemaOperations().DiscoverSchema(ctx)
+		lastUM, _ := client.LastSchema.(*unifiedmodel.UnifiedModel)
+		currentUM, err := adapter.DiscoverSchemaSince(ctx, conn.SchemaOperations(), lastUM, client.LastSchemaHash)
 		if err != nil {
 			w.logError("Failed to get schema for database %s: %v", clientID, err)
 			continue
 		}
+		currentHash, err := unifiedmodel.ContentHash(currentUM)
+		if err != nil {
+			w.logWarn("Failed to hash schema for database %s: %v", clientID, err)
+		}
+
+		// Metadata cached for the database, such as its table count, is stale once its schema
+		// changed since the last discovery
+		if currentHash == "" || currentHash != client.LastSchemaHash {
+			registry.InvalidateMetadata(clientID)
+		}
 
 		// Log schema discovery summary
 		collectionCount := len(currentUM.Collections)
@@ -460,6 +474,14 @@ func (w *SchemaWatcher) checkSchemaChanges(ctx context.Context) error {
 				forceStore = true
 			}
 
+			// Identical content needs neither a comparison nor a new schema version
+			if same, err := unifiedmodel.SameContent(previousUM, currentUM); err == nil && same && !forceStore {
+				w.logDebug("No schema changes detected for database %s (content hash unchanged)", clientID)
+				client.LastSchema = currentUM
+				client.LastSchemaHash = currentHash
+				continue
+			}
+
 			// Call UnifiedModel service to compare schemas using UnifiedModel objects
 			w.logDebug("Comparing schemas for database %s", clientID)
 			compareResp, err := w.umClient.CompareUnifiedModels(ctx, &pb.CompareUnifiedModelsRequest{
@@ -530,6 +552,24 @@ func (w *SchemaWatcher) checkSchemaChanges(ctx context.Context) error {
 			} else {
 				w.logInfo("Found existing commit for database %s, comparing with current schema", clientID)
 
+				// Core keeps large schemas in the blob store, leaving only their hash to compare
+				if latestCommit.SnapshotHash != "" {
+					if schemaSnapshotHash(currentBytes) == latestCommit.SnapshotHash {
+						w.logDebug("No schema changes detected for database %s (snapshot hash unchanged)", clientID)
+					} else {
+						w.logInfo("Schema changes detected for database %s", clientID)
+						_, _, err := w.ensureRepoBranchCommit(ctx, client.Config.WorkspaceID, client.Config.DatabaseID, client.Config.ConnectionType, currentBytes, "Discovered schema changes")
+						if err != nil {
+							w.logError("Failed to store schema changes: %v", err)
+							continue
+						}
+						w.invalidateMappingsForDatabase(ctx, client.Config.WorkspaceID, client.Config.DatabaseID)
+					}
+					client.LastSchema = currentUM
+					client.LastSchemaHash = currentHash
+					continue
+				}
+
 				// The stored schema might be JSON, so we need to handle conversion
 				var previousUM *unifiedmodel.UnifiedModel
 
@@ -561,6 +601,14 @@ func (w *SchemaWatcher) checkSchemaChanges(ctx context.Context) error {
 					}
 				}
 
+				// Identical content needs neither a comparison nor a new schema version
+				if same, err := unifiedmodel.SameContent(previousUM, currentUM); err == nil && same {
+					w.logDebug("No schema changes detected for database %s (content hash unchanged)", clientID)
+					client.LastSchema = currentUM
+					client.LastSchemaHash = currentHash
+					continue
+				}
+
 				// Call UnifiedModel service to compare schemas using UnifiedModel objects
 				compareResp, err := w.umClient.CompareUnifiedModels(ctx, &pb.CompareUnifiedModelsRequest{
 					PreviousUnifiedModel: previousUM.ToProto(),
@@ -601,11 +649,27 @@ func (w *SchemaWatcher) checkSchemaChanges(ctx context.Context) error {
 
 		// Update last known schema
 		client.LastSchema = currentUM
+		client.LastSchemaHash = currentHash
 	}
 
 	return nil
 }
 
+// schemaSnapshotHash returns the SHA-256 core records for a schema it keeps in the blob store,
+// computed over the schema as core re-encodes it
+func schemaSnapshotHash(schema []byte) string {
+	var structure map[string]interface{}
+	if err := json.Unmarshal(schema, &structure); err != nil {
+		return ""
+	}
+	data, err := json.Marshal(structure)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // invalidateMappingsForDatabase invalidates all mappings that target any table in the specified database
 func (w *SchemaWatcher) invalidateMappingsForDatabase(ctx context.Context, workspaceID, databaseID string) {
 	w.logInfo("Invalidating mappings that target database %s", databaseID)
@@ -455,5 +455,7 @@ func (ah *AnchorHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	ah.writeJSONResponse(w, statusCode, response)
 }
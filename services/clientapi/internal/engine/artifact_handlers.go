@@ -0,0 +1,289 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ArtifactHandlers contains the artifact endpoint handlers
+type ArtifactHandlers struct {
+	engine *Engine
+}
+
+// NewArtifactHandlers creates a new instance of ArtifactHandlers
+func NewArtifactHandlers(engine *Engine) *ArtifactHandlers {
+	return &ArtifactHandlers{
+		engine: engine,
+	}
+}
+
+// ListArtifacts handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/artifacts
+func (ah *ArtifactHandlers) ListArtifacts(w http.ResponseWriter, r *http.Request) {
+	ah.engine.TrackOperation()
+	defer ah.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ah.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ah.engine.artifactClient.ListArtifacts(ctx, &corev1.ListArtifactsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ArtifactKind:  r.URL.Query().Get("kind"),
+	})
+	if err != nil {
+		ah.handleGRPCError(w, err, "Failed to list artifacts")
+		return
+	}
+
+	artifacts := make([]Artifact, len(grpcResp.Artifacts))
+	for i, a := range grpcResp.Artifacts {
+		artifacts[i] = artifactFromProto(a)
+	}
+
+	ah.writeJSONResponse(w, http.StatusOK, ListArtifactsResponse{Artifacts: artifacts})
+}
+
+// GetArtifactURL handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/artifacts/{artifact_id}/url
+func (ah *ArtifactHandlers) GetArtifactURL(w http.ResponseWriter, r *http.Request) {
+	ah.engine.TrackOperation()
+	defer ah.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	artifactID := vars["artifact_id"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ah.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var expirySeconds int64
+	if v := r.URL.Query().Get("expiry_seconds"); v != "" {
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seconds <= 0 {
+			ah.writeErrorResponse(w, http.StatusBadRequest, "Invalid expiry_seconds", "expiry_seconds must be a positive number of seconds")
+			return
+		}
+		expirySeconds = seconds
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ah.engine.artifactClient.GetArtifactURL(ctx, &corev1.GetArtifactURLRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ArtifactId:    artifactID,
+		ExpirySeconds: expirySeconds,
+	})
+	if err != nil {
+		ah.handleGRPCError(w, err, "Failed to get artifact URL")
+		return
+	}
+
+	ah.writeJSONResponse(w, http.StatusOK, GetArtifactURLResponse{
+		Artifact: artifactFromProto(grpcResp.Artifact),
+		URL:      grpcResp.Url,
+		Expires:  grpcResp.Expires,
+	})
+}
+
+// DeleteArtifact handles DELETE /{tenant_url}/api/v1/workspaces/{workspace_name}/artifacts/{artifact_id}
+func (ah *ArtifactHandlers) DeleteArtifact(w http.ResponseWriter, r *http.Request) {
+	ah.engine.TrackOperation()
+	defer ah.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	artifactID := vars["artifact_id"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ah.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ah.engine.artifactClient.DeleteArtifact(ctx, &corev1.DeleteArtifactRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ArtifactId:    artifactID,
+	})
+	if err != nil {
+		ah.handleGRPCError(w, err, "Failed to delete artifact")
+		return
+	}
+
+	ah.writeJSONResponse(w, http.StatusOK, DeleteArtifactResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// DownloadBlob handles GET /api/v1/blobs/download, serving the signed URLs of the local blob
+// store. The signature takes the place of authentication.
+func (ah *ArtifactHandlers) DownloadBlob(w http.ResponseWriter, r *http.Request) {
+	ah.engine.TrackOperation()
+	defer ah.engine.UntrackOperation()
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || query.Get("key") == "" || query.Get("signature") == "" {
+		ah.writeErrorResponse(w, http.StatusBadRequest, "Invalid download URL", "key, expires and signature are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Minute)
+	defer cancel()
+
+	stream, err := ah.engine.artifactClient.ReadBlob(ctx, &corev1.ReadBlobRequest{
+		Key:       query.Get("key"),
+		Expires:   expires,
+		Signature: query.Get("signature"),
+	})
+	if err != nil {
+		ah.handleGRPCError(w, err, "Failed to download blob")
+		return
+	}
+
+	// The first message carries the metadata of the blob, and errors before it can still be reported
+	first, err := stream.Recv()
+	if err != nil {
+		ah.handleGRPCError(w, err, "Failed to download blob")
+		return
+	}
+	if first.ContentType != "" {
+		w.Header().Set("Content-Type", first.ContentType)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(first.SizeBytes, 10))
+	w.WriteHeader(http.StatusOK)
+
+	chunk := first
+	for {
+		if _, err := w.Write(chunk.Chunk); err != nil {
+			return
+		}
+		chunk, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			if ah.engine.logger != nil {
+				ah.engine.logger.Errorf("Blob download interrupted: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func artifactFromProto(a *corev1.Artifact) Artifact {
+	if a == nil {
+		return Artifact{}
+	}
+	return Artifact{
+		ArtifactID:   a.ArtifactId,
+		TenantID:     a.TenantId,
+		WorkspaceID:  a.WorkspaceId,
+		ArtifactKind: a.ArtifactKind,
+		ArtifactName: a.ArtifactName,
+		ContentType:  a.ContentType,
+		SizeBytes:    a.SizeBytes,
+		Created:      a.Created,
+	}
+}
+
+// handleGRPCError handles gRPC errors and converts them to HTTP responses
+func (ah *ArtifactHandlers) handleGRPCError(w http.ResponseWriter, err error, defaultMessage string) {
+	if ah.engine.logger != nil {
+		ah.engine.logger.Errorf("gRPC error: %v", err)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		ah.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, err.Error())
+		return
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		ah.writeErrorResponse(w, http.StatusNotFound, "Resource not found", st.Message())
+	case codes.InvalidArgument:
+		ah.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", st.Message())
+	case codes.FailedPrecondition:
+		ah.writeErrorResponse(w, http.StatusPreconditionFailed, "Precondition failed", st.Message())
+	case codes.PermissionDenied:
+		ah.writeErrorResponse(w, http.StatusForbidden, "Permission denied", st.Message())
+	case codes.Unauthenticated:
+		ah.writeErrorResponse(w, http.StatusUnauthorized, "Authentication required", st.Message())
+	case codes.Unavailable:
+		ah.writeErrorResponse(w, http.StatusServiceUnavailable, "Service unavailable", st.Message())
+	case codes.DeadlineExceeded:
+		ah.writeErrorResponse(w, http.StatusRequestTimeout, "Request timeout", st.Message())
+	default:
+		ah.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, st.Message())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (ah *ArtifactHandlers) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		if ah.engine.logger != nil {
+			ah.engine.logger.Errorf("Failed to encode JSON response: %v", err)
+		}
+	}
+}
+
+// writeErrorResponse writes an error response
+func (ah *ArtifactHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message, details string) {
+	if ah.engine.logger != nil {
+		if statusCode >= 500 {
+			ah.engine.logger.Errorf("HTTP %d - %s: %s", statusCode, message, details)
+		} else if statusCode >= 400 {
+			ah.engine.logger.Warnf("HTTP %d - %s: %s", statusCode, message, details)
+		}
+	}
+
+	response := ErrorResponse{
+		Error:   message,
+		Message: details,
+		Status:  StatusError,
+	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		if ah.engine.logger != nil {
+			ah.engine.logger.Errorf("Failed to encode error response: %v", err)
+		}
+	}
+}
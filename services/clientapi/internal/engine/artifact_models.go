@@ -0,0 +1,32 @@
+package engine
+
+// Artifact represents a large artifact kept in the blob store
+type Artifact struct {
+	ArtifactID   string `json:"artifact_id"`
+	TenantID     string `json:"tenant_id"`
+	WorkspaceID  string `json:"workspace_id"`
+	ArtifactKind string `json:"artifact_kind"`
+	ArtifactName string `json:"artifact_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Created      string `json:"created"`
+}
+
+// ListArtifactsResponse represents the list artifacts response
+type ListArtifactsResponse struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// GetArtifactURLResponse represents the signed artifact URL response
+type GetArtifactURLResponse struct {
+	Artifact Artifact `json:"artifact"`
+	URL      string   `json:"url"`
+	Expires  string   `json:"expires"`
+}
+
+// DeleteArtifactResponse represents the delete artifact response
+type DeleteArtifactResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
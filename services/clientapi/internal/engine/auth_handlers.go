@@ -815,6 +815,8 @@ func (ah *AuthHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int
 		Message: message,
 		Status:  StatusFailure,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -458,5 +458,7 @@ func (bh *BranchHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	bh.writeJSONResponse(w, statusCode, response)
 }
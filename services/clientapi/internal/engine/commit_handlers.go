@@ -3,6 +3,8 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
@@ -638,6 +640,125 @@ func (ch *CommitHandlers) ForkCommit(w http.ResponseWriter, r *http.Request) {
 	ch.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// ValidateCommitSchemaRequest represents the optional lint configuration for validating a commit schema
+type ValidateCommitSchemaRequest struct {
+	DisableLint      bool              `json:"disable_lint,omitempty"`
+	LintRules        []string          `json:"lint_rules,omitempty"`
+	NamingConvention string            `json:"naming_convention,omitempty"`
+	Severities       map[string]string `json:"severities,omitempty"`
+}
+
+// SchemaValidationIssue represents an issue found while validating a schema
+type SchemaValidationIssue struct {
+	Severity   string `json:"severity"`
+	Rule       string `json:"rule"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ValidateCommitSchemaResponse represents the response from validating a commit schema
+type ValidateCommitSchemaResponse struct {
+	Message       string                  `json:"message"`
+	Success       bool                    `json:"success"`
+	Status        string                  `json:"status"`
+	Valid         bool                    `json:"valid"`
+	Issues        []SchemaValidationIssue `json:"issues"`
+	CriticalCount int32                   `json:"critical_count"`
+	WarningCount  int32                   `json:"warning_count"`
+	InfoCount     int32                   `json:"info_count"`
+}
+
+// ValidateCommitSchema handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/repos/{repo_name}/branches/{branch_name}/commits/{commit_code}/validate
+func (ch *CommitHandlers) ValidateCommitSchema(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	repoName := vars["repo_name"]
+	branchName := vars["branch_name"]
+	commitCode := vars["commit_code"]
+
+	if tenantURL == "" || workspaceName == "" || repoName == "" || branchName == "" || commitCode == "" {
+		ch.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name, repo_name, branch_name, and commit_code are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Parse request body, which is optional
+	var req ValidateCommitSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		ch.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Log request
+	if ch.engine.logger != nil {
+		ch.engine.logger.Infof("Validate commit schema request for commit: %s, branch: %s, repo: %s, workspace: %s, tenant: %s", commitCode, branchName, repoName, workspaceName, profile.TenantId)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	// Call core service gRPC
+	grpcReq := &corev1.ValidateCommitSchemaRequest{
+		TenantId:         profile.TenantId,
+		WorkspaceName:    workspaceName,
+		RepoName:         repoName,
+		BranchName:       branchName,
+		CommitCode:       commitCode,
+		DisableLint:      req.DisableLint,
+		LintRules:        req.LintRules,
+		NamingConvention: req.NamingConvention,
+		Severities:       req.Severities,
+	}
+
+	grpcResp, err := ch.engine.commitClient.ValidateCommitSchema(ctx, grpcReq)
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to validate commit schema")
+		return
+	}
+
+	// Convert gRPC response to REST response
+	issues := make([]SchemaValidationIssue, 0, len(grpcResp.Issues))
+	for _, issue := range grpcResp.Issues {
+		issues = append(issues, SchemaValidationIssue{
+			Severity:   issue.Severity,
+			Rule:       issue.Rule,
+			Field:      issue.Field,
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+		})
+	}
+
+	response := ValidateCommitSchemaResponse{
+		Message:       grpcResp.Message,
+		Success:       grpcResp.Success,
+		Status:        string(convertStatus(grpcResp.Status)),
+		Valid:         grpcResp.Valid,
+		Issues:        issues,
+		CriticalCount: grpcResp.CriticalCount,
+		WarningCount:  grpcResp.WarningCount,
+		InfoCount:     grpcResp.InfoCount,
+	}
+
+	if ch.engine.logger != nil {
+		ch.engine.logger.Infof("Validated commit schema: %s, valid: %t, issues: %d", commitCode, grpcResp.Valid, len(issues))
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, response)
+}
+
 // Helper methods
 
 func (ch *CommitHandlers) handleGRPCError(w http.ResponseWriter, err error, defaultMessage string) {
@@ -681,5 +802,7 @@ func (ch *CommitHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	ch.writeJSONResponse(w, statusCode, response)
 }
@@ -0,0 +1,436 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CutoverHandlers contains the cutover endpoint handlers
+type CutoverHandlers struct {
+	engine *Engine
+}
+
+// NewCutoverHandlers creates a new instance of CutoverHandlers
+func NewCutoverHandlers(engine *Engine) *CutoverHandlers {
+	return &CutoverHandlers{
+		engine: engine,
+	}
+}
+
+// ListCutovers handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers
+func (ch *CutoverHandlers) ListCutovers(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ch.engine.cutoverClient.ListCutovers(ctx, &corev1.ListCutoversRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to list cutovers")
+		return
+	}
+
+	cutovers := make([]Cutover, len(grpcResp.Cutovers))
+	for i, c := range grpcResp.Cutovers {
+		cutovers[i] = cutoverFromProto(c)
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, ListCutoversResponse{Cutovers: cutovers})
+}
+
+// ShowCutover handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}
+func (ch *CutoverHandlers) ShowCutover(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	cutoverName := vars["cutover_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ch.engine.cutoverClient.ShowCutover(ctx, &corev1.ShowCutoverRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		CutoverName:   cutoverName,
+	})
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to show cutover")
+		return
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, ShowCutoverResponse{Cutover: cutoverFromProto(grpcResp.Cutover)})
+}
+
+// AddCutover handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers
+func (ch *CutoverHandlers) AddCutover(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req AddCutoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ch.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.CutoverName == "" || req.RelationshipName == "" || req.ConnectionName == "" {
+		ch.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "cutover_name, relationship_name and connection_name are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ch.engine.cutoverClient.AddCutover(ctx, &corev1.AddCutoverRequest{
+		TenantId:            profile.TenantId,
+		WorkspaceName:       workspaceName,
+		CutoverName:         req.CutoverName,
+		CutoverDescription:  req.CutoverDescription,
+		RelationshipName:    req.RelationshipName,
+		ConnectionName:      req.ConnectionName,
+		StopWritesHook:      req.StopWritesHook,
+		UnpauseHook:         req.UnpauseHook,
+		Gates:               req.Gates,
+		MaxRetries:          req.MaxRetries,
+		DrainTimeoutSeconds: req.DrainTimeoutSeconds,
+		OwnerId:             profile.UserId,
+	})
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to add cutover")
+		return
+	}
+
+	ch.writeJSONResponse(w, http.StatusCreated, CutoverResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Cutover: cutoverFromProto(grpcResp.Cutover),
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// DeleteCutover handles DELETE /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}
+func (ch *CutoverHandlers) DeleteCutover(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	cutoverName := vars["cutover_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ch.engine.cutoverClient.DeleteCutover(ctx, &corev1.DeleteCutoverRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		CutoverName:   cutoverName,
+	})
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to delete cutover")
+		return
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, DeleteCutoverResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// StartCutover handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}/start
+func (ch *CutoverHandlers) StartCutover(w http.ResponseWriter, r *http.Request) {
+	ch.cutoverOperation(w, r, "Failed to start cutover", func(ctx context.Context, tenantID, workspaceName, cutoverName string) (cutoverOperationResponse, error) {
+		return ch.engine.cutoverClient.StartCutover(ctx, &corev1.StartCutoverRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			CutoverName:   cutoverName,
+		})
+	})
+}
+
+// ApproveCutover handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}/approve
+func (ch *CutoverHandlers) ApproveCutover(w http.ResponseWriter, r *http.Request) {
+	// The step is optional, so is the body
+	var req ApproveCutoverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		ch.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	ch.cutoverOperation(w, r, "Failed to approve cutover", func(ctx context.Context, tenantID, workspaceName, cutoverName string) (cutoverOperationResponse, error) {
+		return ch.engine.cutoverClient.ApproveCutover(ctx, &corev1.ApproveCutoverRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			CutoverName:   cutoverName,
+			Step:          req.Step,
+		})
+	})
+}
+
+// RetryCutover handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}/retry
+func (ch *CutoverHandlers) RetryCutover(w http.ResponseWriter, r *http.Request) {
+	ch.cutoverOperation(w, r, "Failed to retry cutover", func(ctx context.Context, tenantID, workspaceName, cutoverName string) (cutoverOperationResponse, error) {
+		return ch.engine.cutoverClient.RetryCutover(ctx, &corev1.RetryCutoverRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			CutoverName:   cutoverName,
+		})
+	})
+}
+
+// AbortCutover handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/cutovers/{cutover_name}/abort
+func (ch *CutoverHandlers) AbortCutover(w http.ResponseWriter, r *http.Request) {
+	ch.cutoverOperation(w, r, "Failed to abort cutover", func(ctx context.Context, tenantID, workspaceName, cutoverName string) (cutoverOperationResponse, error) {
+		return ch.engine.cutoverClient.AbortCutover(ctx, &corev1.AbortCutoverRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			CutoverName:   cutoverName,
+		})
+	})
+}
+
+// ListApplicationConnections handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/application-connections
+func (ch *CutoverHandlers) ListApplicationConnections(w http.ResponseWriter, r *http.Request) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := ch.engine.cutoverClient.ListApplicationConnections(ctx, &corev1.ListApplicationConnectionsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		ch.handleGRPCError(w, err, "Failed to list application connections")
+		return
+	}
+
+	connections := make([]ApplicationConnection, len(grpcResp.Connections))
+	for i, a := range grpcResp.Connections {
+		connections[i] = ApplicationConnection{
+			TenantID:              a.TenantId,
+			WorkspaceID:           a.WorkspaceId,
+			ConnectionName:        a.ConnectionName,
+			DatabaseID:            a.DatabaseId,
+			DatabaseName:          a.DatabaseName,
+			DatabaseType:          a.DatabaseType,
+			Host:                  a.Host,
+			Port:                  a.Port,
+			DatabaseDBName:        a.DatabaseDbName,
+			PreviousDatabaseName:  a.PreviousDatabaseName,
+			SwitchedByCutoverName: a.SwitchedByCutoverName,
+			Updated:               a.Updated,
+		}
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, ListApplicationConnectionsResponse{Connections: connections})
+}
+
+// cutoverOperationResponse is the response of the operations that move a cutover to another state
+type cutoverOperationResponse interface {
+	GetMessage() string
+	GetSuccess() bool
+	GetCutover() *corev1.Cutover
+	GetStatus() commonv1.Status
+}
+
+// cutoverOperation runs an operation that moves a cutover to another state
+func (ch *CutoverHandlers) cutoverOperation(w http.ResponseWriter, r *http.Request, failure string, operation func(ctx context.Context, tenantID, workspaceName, cutoverName string) (cutoverOperationResponse, error)) {
+	ch.engine.TrackOperation()
+	defer ch.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	cutoverName := vars["cutover_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Aborting waits for the running step to stop and calls the unpause hook
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcResp, err := operation(ctx, profile.TenantId, workspaceName, cutoverName)
+	if err != nil {
+		ch.handleGRPCError(w, err, failure)
+		return
+	}
+
+	ch.writeJSONResponse(w, http.StatusOK, CutoverResponse{
+		Message: grpcResp.GetMessage(),
+		Success: grpcResp.GetSuccess(),
+		Cutover: cutoverFromProto(grpcResp.GetCutover()),
+		Status:  convertStatus(grpcResp.GetStatus()),
+	})
+}
+
+func cutoverFromProto(c *corev1.Cutover) Cutover {
+	if c == nil {
+		return Cutover{}
+	}
+	steps := make([]CutoverStep, len(c.Steps))
+	for i, step := range c.Steps {
+		steps[i] = CutoverStep{
+			Name:     step.Name,
+			Status:   step.Status,
+			Attempts: step.Attempts,
+			Gated:    step.Gated,
+			Approved: step.Approved,
+			Message:  step.Message,
+			Started:  step.Started,
+			Finished: step.Finished,
+		}
+	}
+	gates := c.Gates
+	if gates == nil {
+		gates = []string{}
+	}
+	return Cutover{
+		TenantID:            c.TenantId,
+		WorkspaceID:         c.WorkspaceId,
+		CutoverID:           c.CutoverId,
+		CutoverName:         c.CutoverName,
+		CutoverDescription:  c.CutoverDescription,
+		RelationshipName:    c.RelationshipName,
+		ConnectionName:      c.ConnectionName,
+		StopWritesHook:      c.StopWritesHook,
+		UnpauseHook:         c.UnpauseHook,
+		Gates:               gates,
+		MaxRetries:          c.MaxRetries,
+		DrainTimeoutSeconds: c.DrainTimeoutSeconds,
+		State:               c.State,
+		CurrentStep:         c.CurrentStep,
+		Steps:               steps,
+		OwnerID:             c.OwnerId,
+		Created:             c.Created,
+		Updated:             c.Updated,
+	}
+}
+
+// handleGRPCError handles gRPC errors and converts them to HTTP responses
+func (ch *CutoverHandlers) handleGRPCError(w http.ResponseWriter, err error, defaultMessage string) {
+	if ch.engine.logger != nil {
+		ch.engine.logger.Errorf("gRPC error: %v", err)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		ch.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, err.Error())
+		return
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		ch.writeErrorResponse(w, http.StatusNotFound, "Resource not found", st.Message())
+	case codes.InvalidArgument:
+		ch.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", st.Message())
+	case codes.FailedPrecondition:
+		ch.writeErrorResponse(w, http.StatusPreconditionFailed, "Precondition failed", st.Message())
+	case codes.PermissionDenied:
+		ch.writeErrorResponse(w, http.StatusForbidden, "Permission denied", st.Message())
+	case codes.Unauthenticated:
+		ch.writeErrorResponse(w, http.StatusUnauthorized, "Authentication required", st.Message())
+	case codes.Unavailable:
+		ch.writeErrorResponse(w, http.StatusServiceUnavailable, "Service unavailable", st.Message())
+	case codes.DeadlineExceeded:
+		ch.writeErrorResponse(w, http.StatusRequestTimeout, "Request timeout", st.Message())
+	default:
+		ch.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, st.Message())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (ch *CutoverHandlers) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		if ch.engine.logger != nil {
+			ch.engine.logger.Errorf("Failed to encode JSON response: %v", err)
+		}
+	}
+}
+
+// writeErrorResponse writes an error response
+func (ch *CutoverHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message, details string) {
+	if ch.engine.logger != nil {
+		if statusCode >= 500 {
+			ch.engine.logger.Errorf("HTTP %d - %s: %s", statusCode, message, details)
+		} else if statusCode >= 400 {
+			ch.engine.logger.Warnf("HTTP %d - %s: %s", statusCode, message, details)
+		}
+	}
+
+	response := ErrorResponse{
+		Error:   message,
+		Message: details,
+		Status:  StatusError,
+	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		if ch.engine.logger != nil {
+			ch.engine.logger.Errorf("Failed to encode error response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+// CutoverStep represents the progress of one step of a cutover
+type CutoverStep struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Attempts int32  `json:"attempts"`
+	Gated    bool   `json:"gated"`
+	Approved bool   `json:"approved"`
+	Message  string `json:"message,omitempty"`
+	Started  string `json:"started,omitempty"`
+	Finished string `json:"finished,omitempty"`
+}
+
+// Cutover represents the cutover of an application connection from the source to the target
+// database of a relationship
+type Cutover struct {
+	TenantID            string        `json:"tenant_id"`
+	WorkspaceID         string        `json:"workspace_id"`
+	CutoverID           string        `json:"cutover_id"`
+	CutoverName         string        `json:"cutover_name"`
+	CutoverDescription  string        `json:"cutover_description"`
+	RelationshipName    string        `json:"relationship_name"`
+	ConnectionName      string        `json:"connection_name"`
+	StopWritesHook      string        `json:"stop_writes_hook,omitempty"`
+	UnpauseHook         string        `json:"unpause_hook,omitempty"`
+	Gates               []string      `json:"gates"`
+	MaxRetries          int32         `json:"max_retries"`
+	DrainTimeoutSeconds int32         `json:"drain_timeout_seconds"`
+	State               string        `json:"state"`
+	CurrentStep         string        `json:"current_step,omitempty"`
+	Steps               []CutoverStep `json:"steps"`
+	OwnerID             string        `json:"owner_id"`
+	Created             string        `json:"created"`
+	Updated             string        `json:"updated"`
+}
+
+// ApplicationConnection represents the connection info of an application stored in reDB
+type ApplicationConnection struct {
+	TenantID              string `json:"tenant_id"`
+	WorkspaceID           string `json:"workspace_id"`
+	ConnectionName        string `json:"connection_name"`
+	DatabaseID            string `json:"database_id"`
+	DatabaseName          string `json:"database_name"`
+	DatabaseType          string `json:"database_type"`
+	Host                  string `json:"host"`
+	Port                  int32  `json:"port"`
+	DatabaseDBName        string `json:"database_db_name"`
+	PreviousDatabaseName  string `json:"previous_database_name,omitempty"`
+	SwitchedByCutoverName string `json:"switched_by_cutover_name,omitempty"`
+	Updated               string `json:"updated"`
+}
+
+// ListCutoversResponse represents the list cutovers response
+type ListCutoversResponse struct {
+	Cutovers []Cutover `json:"cutovers"`
+}
+
+// ShowCutoverResponse represents the show cutover response
+type ShowCutoverResponse struct {
+	Cutover Cutover `json:"cutover"`
+}
+
+// AddCutoverRequest represents the add cutover request
+type AddCutoverRequest struct {
+	CutoverName         string   `json:"cutover_name"`
+	CutoverDescription  string   `json:"cutover_description,omitempty"`
+	RelationshipName    string   `json:"relationship_name"`
+	ConnectionName      string   `json:"connection_name"`
+	StopWritesHook      string   `json:"stop_writes_hook,omitempty"`
+	UnpauseHook         string   `json:"unpause_hook,omitempty"`
+	Gates               []string `json:"gates,omitempty"`
+	MaxRetries          *int32   `json:"max_retries,omitempty"`
+	DrainTimeoutSeconds *int32   `json:"drain_timeout_seconds,omitempty"`
+}
+
+// ApproveCutoverRequest represents the approve cutover request
+type ApproveCutoverRequest struct {
+	Step string `json:"step,omitempty"`
+}
+
+// CutoverResponse represents the response of the operations that change a cutover
+type CutoverResponse struct {
+	Message string  `json:"message"`
+	Success bool    `json:"success"`
+	Cutover Cutover `json:"cutover"`
+	Status  Status  `json:"status"`
+}
+
+// DeleteCutoverResponse represents the delete cutover response
+type DeleteCutoverResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
+
+// ListApplicationConnectionsResponse represents the list application connections response
+type ListApplicationConnectionsResponse struct {
+	Connections []ApplicationConnection `json:"connections"`
+}
@@ -3,7 +3,9 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -68,6 +70,7 @@ func (dh *DatabaseHandlers) ListDatabases(w http.ResponseWriter, r *http.Request
 	grpcReq := &corev1.ListDatabasesRequest{
 		TenantId:      profile.TenantId,
 		WorkspaceName: workspaceName,
+		LabelSelector: r.URL.Query().Get("selector"),
 	}
 
 	grpcResp, err := dh.engine.databaseClient.ListDatabases(ctx, grpcReq)
@@ -111,6 +114,10 @@ func (dh *DatabaseHandlers) ListDatabases(w http.ResponseWriter, r *http.Request
 			InstanceSSL:           db.InstanceSsl,
 			InstanceStatusMessage: db.InstanceStatusMessage,
 			InstanceStatus:        db.InstanceStatus,
+			DatabaseReplicas:      databaseReplicasFromProto(db.DatabaseReplicas),
+			DatabaseRoutingPolicy: db.DatabaseRoutingPolicy,
+			DatabaseRateLimits:    databaseRateLimitsFromProto(db.DatabaseRateLimits),
+			DatabaseLabels:        db.DatabaseLabels,
 		}
 	}
 
@@ -205,6 +212,10 @@ func (dh *DatabaseHandlers) ShowDatabase(w http.ResponseWriter, r *http.Request)
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	// Convert resource containers
@@ -350,6 +361,10 @@ func (dh *DatabaseHandlers) ConnectDatabase(w http.ResponseWriter, r *http.Reque
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	response := ConnectDatabaseResponse{
@@ -471,6 +486,10 @@ func (dh *DatabaseHandlers) ConnectDatabaseWithInstance(w http.ResponseWriter, r
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	response := ConnectDatabaseWithInstanceResponse{
@@ -565,6 +584,10 @@ func (dh *DatabaseHandlers) ReconnectDatabase(w http.ResponseWriter, r *http.Req
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	response := ReconnectDatabaseResponse{
@@ -648,6 +671,31 @@ func (dh *DatabaseHandlers) ModifyDatabase(w http.ResponseWriter, r *http.Reques
 
 	grpcReq.Ssl = req.SSL
 
+	if req.RoutingPolicy != "" {
+		grpcReq.RoutingPolicy = &req.RoutingPolicy
+	}
+	if req.RateLimits != nil {
+		grpcReq.RateLimits = &corev1.DatabaseRateLimits{
+			RowsPerSecond:           req.RateLimits.RowsPerSecond,
+			QueriesPerSecond:        req.RateLimits.QueriesPerSecond,
+			MaxConcurrentStatements: req.RateLimits.MaxConcurrentStatements,
+		}
+	}
+	if req.Labels != nil {
+		grpcReq.Labels = &corev1.DatabaseLabels{Labels: *req.Labels}
+	}
+	if req.Replicas != nil {
+		grpcReq.Replicas = &corev1.DatabaseReplicaList{Replicas: make([]*corev1.DatabaseReplica, len(*req.Replicas))}
+		for i, replica := range *req.Replicas {
+			grpcReq.Replicas.Replicas[i] = &corev1.DatabaseReplica{
+				Host:     replica.Host,
+				Port:     replica.Port,
+				Username: replica.Username,
+				Password: replica.Password,
+			}
+		}
+	}
+
 	grpcResp, err := dh.engine.databaseClient.ModifyDatabase(ctx, grpcReq)
 	if err != nil {
 		dh.handleGRPCError(w, err, "Failed to modify database")
@@ -687,6 +735,10 @@ func (dh *DatabaseHandlers) ModifyDatabase(w http.ResponseWriter, r *http.Reques
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	response := ModifyDatabaseResponse{
@@ -848,6 +900,159 @@ func (dh *DatabaseHandlers) GetDatabaseDisconnectMetadata(w http.ResponseWriter,
 	dh.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// GetDatabaseMetrics handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/metrics
+// and GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/metrics
+func (dh *DatabaseHandlers) GetDatabaseMetrics(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters; database_name is only set for a single database
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url and workspace_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Log request
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Get database metrics request for database: %s, workspace: %s, tenant: %s", databaseName, workspaceName, profile.TenantId)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	// Call core service gRPC
+	grpcReq := &corev1.GetDatabaseMetricsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	}
+	if databaseName != "" {
+		grpcReq.DatabaseName = &databaseName
+	}
+
+	grpcResp, err := dh.engine.databaseClient.GetDatabaseMetrics(ctx, grpcReq)
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to get database metrics")
+		return
+	}
+
+	operations := make([]DatabaseOperationMetrics, 0, len(grpcResp.Metrics))
+	for _, m := range grpcResp.Metrics {
+		var errorRate float64
+		if m.Count > 0 {
+			errorRate = float64(m.Errors) / float64(m.Count)
+		}
+		operations = append(operations, DatabaseOperationMetrics{
+			DatabaseID:       m.DatabaseId,
+			DatabaseName:     m.DatabaseName,
+			DatabaseType:     m.DatabaseType,
+			Operation:        m.Operation,
+			Count:            m.Count,
+			Errors:           m.Errors,
+			ErrorRate:        errorRate,
+			AvgLatencyMs:     m.AvgLatencyMs,
+			MinLatencyMs:     m.MinLatencyMs,
+			MaxLatencyMs:     m.MaxLatencyMs,
+			P50LatencyMs:     m.P50LatencyMs,
+			P95LatencyMs:     m.P95LatencyMs,
+			P99LatencyMs:     m.P99LatencyMs,
+			LastLatencyMs:    m.LastLatencyMs,
+			LastCalledAt:     m.LastCalledAt,
+			LastError:        m.LastError,
+			LastErrorAt:      m.LastErrorAt,
+			LatencyHistogram: m.LatencyHistogram,
+		})
+	}
+
+	plans := make([]DatabaseQueryPlan, 0, len(grpcResp.QueryPlans))
+	for _, p := range grpcResp.QueryPlans {
+		plans = append(plans, DatabaseQueryPlan{
+			DatabaseID:   p.DatabaseId,
+			DatabaseName: p.DatabaseName,
+			DatabaseType: p.DatabaseType,
+			Operation:    p.Operation,
+			TableName:    p.TableName,
+			Limit:        p.Limit,
+			DurationMs:   p.DurationMs,
+			CapturedAt:   p.CapturedAt,
+			Query:        p.Query,
+			PlanFormat:   p.PlanFormat,
+			Plan:         p.Plan,
+			Analyzed:     p.Analyzed,
+			Error:        p.Error,
+		})
+	}
+
+	response := GetDatabaseMetricsResponse{
+		Message:               grpcResp.Message,
+		Success:               grpcResp.Success,
+		Status:                convertStatus(grpcResp.Status),
+		Databases:             summarizeDatabaseMetrics(operations),
+		Operations:            operations,
+		LatencyBucketBoundsMs: grpcResp.LatencyBucketBoundsMs,
+		QueryPlans:            plans,
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// summarizeDatabaseMetrics aggregates operation metrics per database, slowest databases first
+func summarizeDatabaseMetrics(operations []DatabaseOperationMetrics) []DatabaseMetricsSummary {
+	byID := make(map[string]*DatabaseMetricsSummary)
+	totalLatency := make(map[string]float64)
+	var summaries []*DatabaseMetricsSummary
+
+	for _, op := range operations {
+		summary, exists := byID[op.DatabaseID]
+		if !exists {
+			summary = &DatabaseMetricsSummary{
+				DatabaseID:   op.DatabaseID,
+				DatabaseName: op.DatabaseName,
+				DatabaseType: op.DatabaseType,
+			}
+			byID[op.DatabaseID] = summary
+			summaries = append(summaries, summary)
+		}
+
+		summary.Operations += op.Count
+		summary.Errors += op.Errors
+		totalLatency[op.DatabaseID] += op.AvgLatencyMs * float64(op.Count)
+		if op.P95LatencyMs > summary.MaxP95LatencyMs {
+			summary.MaxP95LatencyMs = op.P95LatencyMs
+			summary.SlowestOperation = op.Operation
+		}
+		if op.LastErrorAt > summary.LastErrorAt {
+			summary.LastError = op.LastError
+			summary.LastErrorAt = op.LastErrorAt
+		}
+	}
+
+	result := make([]DatabaseMetricsSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.Operations > 0 {
+			summary.ErrorRate = float64(summary.Errors) / float64(summary.Operations)
+			summary.AvgLatencyMs = totalLatency[summary.DatabaseID] / float64(summary.Operations)
+		}
+		result = append(result, *summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].MaxP95LatencyMs > result[j].MaxP95LatencyMs
+	})
+	return result
+}
+
 // GetLatestStoredDatabaseSchema handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/schema
 func (dh *DatabaseHandlers) GetLatestStoredDatabaseSchema(w http.ResponseWriter, r *http.Request) {
 	dh.engine.TrackOperation()
@@ -1149,6 +1354,8 @@ func (dh *DatabaseHandlers) handleGRPCError(w http.ResponseWriter, err error, de
 			dh.writeErrorResponse(w, http.StatusConflict, st.Message(), defaultMessage)
 		case codes.InvalidArgument:
 			dh.writeErrorResponse(w, http.StatusBadRequest, st.Message(), defaultMessage)
+		case codes.FailedPrecondition:
+			dh.writeErrorResponse(w, http.StatusPreconditionFailed, st.Message(), defaultMessage)
 		case codes.PermissionDenied:
 			dh.writeErrorResponse(w, http.StatusForbidden, st.Message(), defaultMessage)
 		case codes.Unauthenticated:
@@ -1192,6 +1399,8 @@ func (dh *DatabaseHandlers) writeErrorResponse(w http.ResponseWriter, statusCode
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	dh.writeJSONResponse(w, statusCode, response)
 }
 
@@ -1338,6 +1547,10 @@ func (dh *DatabaseHandlers) ConnectDatabaseString(w http.ResponseWriter, r *http
 		InstanceSSL:           grpcResp.Database.InstanceSsl,
 		InstanceStatusMessage: grpcResp.Database.InstanceStatusMessage,
 		InstanceStatus:        grpcResp.Database.InstanceStatus,
+		DatabaseReplicas:      databaseReplicasFromProto(grpcResp.Database.DatabaseReplicas),
+		DatabaseRoutingPolicy: grpcResp.Database.DatabaseRoutingPolicy,
+		DatabaseRateLimits:    databaseRateLimitsFromProto(grpcResp.Database.DatabaseRateLimits),
+		DatabaseLabels:        grpcResp.Database.DatabaseLabels,
 	}
 
 	response := ConnectDatabaseStringResponse{
@@ -1428,6 +1641,24 @@ type UpdateTableDataResponse struct {
 	RowsAffected int64  `json:"rows_affected"`
 }
 
+// ExecuteQueryRequest represents the request payload for an ad-hoc query or command
+type ExecuteQueryRequest struct {
+	Query   string `json:"query"`
+	MaxRows int32  `json:"max_rows,omitempty"` // Default 1000, capped at 10000
+}
+
+// ExecuteQueryResponse represents the response from an ad-hoc query or command
+type ExecuteQueryResponse struct {
+	Message    string                   `json:"message"`
+	Success    bool                     `json:"success"`
+	Status     string                   `json:"status"`
+	Columns    []string                 `json:"columns"`
+	Data       []map[string]interface{} `json:"data"`
+	RowCount   int32                    `json:"row_count"`
+	Truncated  bool                     `json:"truncated"`
+	DurationMs int64                    `json:"duration_ms"`
+}
+
 // CloneDatabaseRequest represents the request payload for cloning a database
 type CloneDatabaseRequest struct {
 	SourceDatabaseName string               `json:"source_database_name"`
@@ -1623,6 +1854,501 @@ func (dh *DatabaseHandlers) CloneDatabase(w http.ResponseWriter, r *http.Request
 	dh.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// ConvertDatabaseSchemaRequest represents the request payload for converting the schema of a database
+type ConvertDatabaseSchemaRequest struct {
+	TargetType            string            `json:"target_type"`
+	TransformationOptions map[string]string `json:"transformation_options,omitempty"`
+	StoreArtifacts        bool              `json:"store_artifacts,omitempty"`
+}
+
+// SchemaConversionWarning is a warning of a schema conversion
+type SchemaConversionWarning struct {
+	WarningType string `json:"warning_type"`
+	ObjectType  string `json:"object_type,omitempty"`
+	ObjectName  string `json:"object_name,omitempty"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity,omitempty"`
+	Suggestion  string `json:"suggestion,omitempty"`
+}
+
+// SchemaConversionMetrics are the object counts of a schema conversion
+type SchemaConversionMetrics struct {
+	ObjectsProcessed int32 `json:"objects_processed"`
+	ObjectsConverted int32 `json:"objects_converted"`
+	ObjectsSkipped   int32 `json:"objects_skipped"`
+	ObjectsDropped   int32 `json:"objects_dropped"`
+	TypesConverted   int32 `json:"types_converted"`
+	LossyConversions int32 `json:"lossy_conversions"`
+}
+
+// ConvertDatabaseSchemaResponse represents the response from converting the schema of a database
+type ConvertDatabaseSchemaResponse struct {
+	Message         string                    `json:"message"`
+	Success         bool                      `json:"success"`
+	Status          string                    `json:"status"`
+	DatabaseName    string                    `json:"database_name"`
+	SourceType      string                    `json:"source_type"`
+	TargetType      string                    `json:"target_type"`
+	ConvertedSchema json.RawMessage           `json:"converted_schema,omitempty"`
+	Statements      []string                  `json:"statements"`
+	GenerationError string                    `json:"generation_error,omitempty"`
+	Warnings        []SchemaConversionWarning `json:"warnings"`
+	Metrics         *SchemaConversionMetrics  `json:"metrics,omitempty"`
+	Lossy           bool                      `json:"lossy"`
+	ArtifactIDs     []string                  `json:"artifact_ids,omitempty"`
+}
+
+// ConvertDatabaseSchema handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/convert-schema
+func (dh *DatabaseHandlers) ConvertDatabaseSchema(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" || databaseName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name and database_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req ConvertDatabaseSchemaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.TargetType == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "target_type is required", "")
+		return
+	}
+
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Convert database schema request: database=%s, target=%s, workspace=%s, tenant=%s, user=%s",
+			databaseName, req.TargetType, workspaceName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 120*time.Second)
+	defer cancel()
+
+	grpcResp, err := dh.engine.databaseClient.ConvertDatabaseSchema(ctx, &corev1.ConvertDatabaseSchemaRequest{
+		TenantId:              profile.TenantId,
+		WorkspaceName:         workspaceName,
+		DatabaseName:          databaseName,
+		TargetType:            req.TargetType,
+		TransformationOptions: req.TransformationOptions,
+		StoreArtifacts:        req.StoreArtifacts,
+	})
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to convert database schema")
+		return
+	}
+
+	response := ConvertDatabaseSchemaResponse{
+		Message:         grpcResp.Message,
+		Success:         grpcResp.Success,
+		Status:          string(convertStatus(grpcResp.Status)),
+		DatabaseName:    databaseName,
+		SourceType:      grpcResp.SourceType,
+		TargetType:      grpcResp.TargetType,
+		Statements:      grpcResp.Statements,
+		GenerationError: grpcResp.GenerationError,
+		Warnings:        make([]SchemaConversionWarning, len(grpcResp.Warnings)),
+		Lossy:           grpcResp.Lossy,
+		ArtifactIDs:     grpcResp.ArtifactIds,
+	}
+	if grpcResp.ConvertedSchema != "" {
+		response.ConvertedSchema = json.RawMessage(grpcResp.ConvertedSchema)
+	}
+	for i, warning := range grpcResp.Warnings {
+		response.Warnings[i] = SchemaConversionWarning{
+			WarningType: warning.WarningType,
+			ObjectType:  warning.ObjectType,
+			ObjectName:  warning.ObjectName,
+			Message:     warning.Message,
+			Severity:    warning.Severity,
+			Suggestion:  warning.Suggestion,
+		}
+	}
+	if metrics := grpcResp.Metrics; metrics != nil {
+		response.Metrics = &SchemaConversionMetrics{
+			ObjectsProcessed: metrics.ObjectsProcessed,
+			ObjectsConverted: metrics.ObjectsConverted,
+			ObjectsSkipped:   metrics.ObjectsSkipped,
+			ObjectsDropped:   metrics.ObjectsDropped,
+			TypesConverted:   metrics.TypesConverted,
+			LossyConversions: metrics.LossyConversions,
+		}
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// PlanDatabaseMigrationRequest represents the request payload for planning the migration of a database
+// to the schema of another database, or to a unified model
+type PlanDatabaseMigrationRequest struct {
+	TargetDatabaseName string          `json:"target_database_name,omitempty"`
+	TargetSchema       json.RawMessage `json:"target_schema,omitempty"`
+	DryRun             *bool           `json:"dry_run,omitempty"` // Defaults to true
+	AllowDestructive   bool            `json:"allow_destructive,omitempty"`
+	ApplyRenames       bool            `json:"apply_renames,omitempty"`
+}
+
+// DatabaseMigrationStep is a statement of a migration plan
+type DatabaseMigrationStep struct {
+	Phase       string `json:"phase"`
+	ChangeType  string `json:"change_type"`
+	ObjectType  string `json:"object_type"`
+	ObjectName  string `json:"object_name"`
+	ParentName  string `json:"parent_name,omitempty"`
+	Statement   string `json:"statement"`
+	Destructive bool   `json:"destructive"`
+	Description string `json:"description"`
+	Executed    bool   `json:"executed"`
+	Error       string `json:"error,omitempty"`
+}
+
+// PlanDatabaseMigrationResponse represents the response from planning the migration of a database
+type PlanDatabaseMigrationResponse struct {
+	Message       string                  `json:"message"`
+	Success       bool                    `json:"success"`
+	Status        string                  `json:"status"`
+	DatabaseName  string                  `json:"database_name"`
+	DatabaseType  string                  `json:"database_type"`
+	HasChanges    bool                    `json:"has_changes"`
+	Steps         []DatabaseMigrationStep `json:"steps"`
+	Script        string                  `json:"script"`
+	Warnings      []string                `json:"warnings"`
+	Destructive   bool                    `json:"destructive"`
+	DryRun        bool                    `json:"dry_run"`
+	ExecutedSteps int32                   `json:"executed_steps"`
+}
+
+// PlanDatabaseMigration handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/plan-migration
+func (dh *DatabaseHandlers) PlanDatabaseMigration(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" || databaseName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name and database_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req PlanDatabaseMigrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if (req.TargetDatabaseName == "") == (len(req.TargetSchema) == 0) {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "either target_database_name or target_schema is required", "")
+		return
+	}
+
+	grpcReq := &corev1.PlanDatabaseMigrationRequest{
+		TenantId:         profile.TenantId,
+		WorkspaceName:    workspaceName,
+		DatabaseName:     databaseName,
+		DryRun:           req.DryRun,
+		AllowDestructive: req.AllowDestructive,
+		ApplyRenames:     req.ApplyRenames,
+	}
+	if req.TargetDatabaseName != "" {
+		grpcReq.TargetDatabaseName = &req.TargetDatabaseName
+	} else {
+		targetSchema := string(req.TargetSchema)
+		grpcReq.TargetSchema = &targetSchema
+	}
+
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Plan database migration request: database=%s, target_database=%s, dry_run=%t, workspace=%s, tenant=%s, user=%s",
+			databaseName, req.TargetDatabaseName, req.DryRun == nil || *req.DryRun, workspaceName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 300*time.Second)
+	defer cancel()
+
+	grpcResp, err := dh.engine.databaseClient.PlanDatabaseMigration(ctx, grpcReq)
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to plan database migration")
+		return
+	}
+
+	response := PlanDatabaseMigrationResponse{
+		Message:       grpcResp.Message,
+		Success:       grpcResp.Success,
+		Status:        string(convertStatus(grpcResp.Status)),
+		DatabaseName:  databaseName,
+		DatabaseType:  grpcResp.DatabaseType,
+		HasChanges:    grpcResp.HasChanges,
+		Steps:         make([]DatabaseMigrationStep, len(grpcResp.Steps)),
+		Script:        grpcResp.Script,
+		Warnings:      grpcResp.Warnings,
+		Destructive:   grpcResp.Destructive,
+		DryRun:        grpcResp.DryRun,
+		ExecutedSteps: grpcResp.ExecutedSteps,
+	}
+	for i, step := range grpcResp.Steps {
+		response.Steps[i] = DatabaseMigrationStep{
+			Phase:       step.Phase,
+			ChangeType:  step.ChangeType,
+			ObjectType:  step.ObjectType,
+			ObjectName:  step.ObjectName,
+			ParentName:  step.ParentName,
+			Statement:   step.Statement,
+			Destructive: step.Destructive,
+			Description: step.Description,
+			Executed:    step.Executed,
+			Error:       step.Error,
+		}
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// DatabaseSchemaSnapshot is a schema of a database as discovered at a point in time
+type DatabaseSchemaSnapshot struct {
+	SnapshotID string `json:"snapshot_id"`
+	Version    int32  `json:"version"`
+	Created    string `json:"created"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+// ListDatabaseSchemaSnapshotsResponse represents the response listing the schema snapshots of a database
+type ListDatabaseSchemaSnapshotsResponse struct {
+	Message      string                   `json:"message"`
+	Success      bool                     `json:"success"`
+	Status       string                   `json:"status"`
+	DatabaseName string                   `json:"database_name"`
+	Snapshots    []DatabaseSchemaSnapshot `json:"snapshots"`
+}
+
+// SchemaChange is a change between two schemas
+type SchemaChange struct {
+	ChangeType  string  `json:"change_type"`
+	ObjectType  string  `json:"object_type"`
+	ObjectName  string  `json:"object_name"`
+	ParentName  string  `json:"parent_name,omitempty"`
+	Field       string  `json:"field,omitempty"`
+	OldValue    string  `json:"old_value,omitempty"`
+	NewValue    string  `json:"new_value,omitempty"`
+	Severity    string  `json:"severity"`
+	Breaking    bool    `json:"breaking"`
+	Description string  `json:"description"`
+	Confidence  float64 `json:"confidence,omitempty"`
+}
+
+// DiffDatabaseSchemaSnapshotsResponse represents the response comparing two schema snapshots of a database
+type DiffDatabaseSchemaSnapshotsResponse struct {
+	Message      string                 `json:"message"`
+	Success      bool                   `json:"success"`
+	Status       string                 `json:"status"`
+	DatabaseName string                 `json:"database_name"`
+	From         DatabaseSchemaSnapshot `json:"from"`
+	To           DatabaseSchemaSnapshot `json:"to"`
+	HasChanges   bool                   `json:"has_changes"`
+	Changes      []string               `json:"changes"`
+	Records      []SchemaChange         `json:"change_records"`
+	Warnings     []string               `json:"warnings"`
+}
+
+// ListDatabaseSchemaSnapshots handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/schema/snapshots
+func (dh *DatabaseHandlers) ListDatabaseSchemaSnapshots(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" || databaseName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name and database_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	grpcReq := &corev1.ListDatabaseSchemaSnapshotsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		DatabaseName:  databaseName,
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || limit < 1 {
+			dh.writeErrorResponse(w, http.StatusBadRequest, "limit must be a positive integer", "")
+			return
+		}
+		limit32 := int32(limit)
+		grpcReq.Limit = &limit32
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := dh.engine.databaseClient.ListDatabaseSchemaSnapshots(ctx, grpcReq)
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to list schema snapshots")
+		return
+	}
+
+	response := ListDatabaseSchemaSnapshotsResponse{
+		Message:      grpcResp.Message,
+		Success:      grpcResp.Success,
+		Status:       string(convertStatus(grpcResp.Status)),
+		DatabaseName: databaseName,
+		Snapshots:    make([]DatabaseSchemaSnapshot, len(grpcResp.Snapshots)),
+	}
+	for i, snapshot := range grpcResp.Snapshots {
+		response.Snapshots[i] = convertSchemaSnapshot(snapshot)
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// DiffDatabaseSchemaSnapshots handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/schema/diff
+func (dh *DatabaseHandlers) DiffDatabaseSchemaSnapshots(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" || databaseName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name and database_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Get("from") == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "from is required", "")
+		return
+	}
+	from, err := parseSnapshotSelector(query.Get("from"))
+	if err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid from", err.Error())
+		return
+	}
+	to, err := parseSnapshotSelector(query.Get("to"))
+	if err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid to", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcResp, err := dh.engine.databaseClient.DiffDatabaseSchemaSnapshots(ctx, &corev1.DiffDatabaseSchemaSnapshotsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		DatabaseName:  databaseName,
+		From:          from,
+		To:            to,
+	})
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to compare schema snapshots")
+		return
+	}
+
+	response := DiffDatabaseSchemaSnapshotsResponse{
+		Message:      grpcResp.Message,
+		Success:      grpcResp.Success,
+		Status:       string(convertStatus(grpcResp.Status)),
+		DatabaseName: databaseName,
+		From:         convertSchemaSnapshot(grpcResp.From),
+		To:           convertSchemaSnapshot(grpcResp.To),
+		HasChanges:   grpcResp.HasChanges,
+		Changes:      grpcResp.Changes,
+		Records:      make([]SchemaChange, len(grpcResp.ChangeRecords)),
+		Warnings:     grpcResp.Warnings,
+	}
+	for i, record := range grpcResp.ChangeRecords {
+		response.Records[i] = SchemaChange{
+			ChangeType:  record.ChangeType,
+			ObjectType:  record.ObjectType,
+			ObjectName:  record.ObjectName,
+			ParentName:  record.ParentName,
+			Field:       record.Field,
+			OldValue:    record.OldValue,
+			NewValue:    record.NewValue,
+			Severity:    record.Severity,
+			Breaking:    record.Breaking,
+			Description: record.Description,
+			Confidence:  record.Confidence,
+		}
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// parseSnapshotSelector parses a snapshot version, or an RFC 3339 time selecting the snapshot
+// current at that time. An empty value selects the latest snapshot.
+func parseSnapshotSelector(value string) (*corev1.SchemaSnapshotSelector, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if version, err := strconv.ParseInt(value, 10, 32); err == nil {
+		if version < 1 {
+			return nil, fmt.Errorf("snapshot versions start at 1, got %d", version)
+		}
+		return &corev1.SchemaSnapshotSelector{Selector: &corev1.SchemaSnapshotSelector_Version{Version: int32(version)}}, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected a snapshot version or an RFC 3339 time, got %q", value)
+	}
+	return &corev1.SchemaSnapshotSelector{Selector: &corev1.SchemaSnapshotSelector_At{At: at.Format(time.RFC3339Nano)}}, nil
+}
+
+func convertSchemaSnapshot(snapshot *corev1.DatabaseSchemaSnapshot) DatabaseSchemaSnapshot {
+	if snapshot == nil {
+		return DatabaseSchemaSnapshot{}
+	}
+	return DatabaseSchemaSnapshot{
+		SnapshotID: snapshot.SnapshotId,
+		Version:    snapshot.Version,
+		Created:    snapshot.Created,
+		SizeBytes:  snapshot.SizeBytes,
+	}
+}
+
 // FetchTableData handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/tables/{table_name}/data
 func (dh *DatabaseHandlers) FetchTableData(w http.ResponseWriter, r *http.Request) {
 	dh.engine.TrackOperation()
@@ -1964,6 +2690,97 @@ func (dh *DatabaseHandlers) UpdateTableData(w http.ResponseWriter, r *http.Reque
 	dh.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// ExecuteQuery handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/query
+func (dh *DatabaseHandlers) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
+	dh.executeQuery(w, r, false)
+}
+
+// ExecuteCommand handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/command
+func (dh *DatabaseHandlers) ExecuteCommand(w http.ResponseWriter, r *http.Request) {
+	dh.executeQuery(w, r, true)
+}
+
+// executeQuery passes an ad-hoc query through to a database. Commands may modify the
+// database; queries are rejected unless they only read data.
+func (dh *DatabaseHandlers) executeQuery(w http.ResponseWriter, r *http.Request, allowWrites bool) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	databaseName := vars["database_name"]
+
+	if tenantURL == "" || workspaceName == "" || databaseName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name, and database_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Parse request body
+	var req ExecuteQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+	if req.Query == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "query is required", "")
+		return
+	}
+
+	// Log request; the query itself may hold literal secrets, so only its size is logged
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Execute query request: database=%s, workspace=%s, user=%s, allow_writes=%t, query_length=%d",
+			databaseName, workspaceName, profile.UserId, allowWrites, len(req.Query))
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcResp, err := dh.engine.databaseClient.ExecuteDatabaseQuery(ctx, &corev1.ExecuteDatabaseQueryRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		DatabaseName:  databaseName,
+		Query:         req.Query,
+		MaxRows:       req.MaxRows,
+		AllowWrites:   allowWrites,
+	})
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to execute query")
+		return
+	}
+
+	// Parse data bytes to JSON
+	dataRows := []map[string]interface{}{}
+	if len(grpcResp.Data) > 0 {
+		if err := json.Unmarshal(grpcResp.Data, &dataRows); err != nil {
+			dh.writeErrorResponse(w, http.StatusInternalServerError, "Failed to parse query result", "")
+			return
+		}
+	}
+
+	response := ExecuteQueryResponse{
+		Message:    grpcResp.Message,
+		Success:    grpcResp.Success,
+		Status:     string(convertStatus(grpcResp.Status)),
+		Columns:    grpcResp.Columns,
+		Data:       dataRows,
+		RowCount:   grpcResp.RowCount,
+		Truncated:  grpcResp.Truncated,
+		DurationMs: grpcResp.DurationMs,
+	}
+
+	dh.writeJSONResponse(w, http.StatusOK, response)
+}
+
 // convertProtoContainer converts a protobuf DatabaseResourceContainer to REST model
 func convertProtoContainer(proto *corev1.DatabaseResourceContainer) DatabaseResourceContainer {
 	container := DatabaseResourceContainer{
@@ -2077,3 +2894,27 @@ func convertProtoItem(proto *corev1.DatabaseResourceItem) DatabaseResourceItem {
 
 	return item
 }
+
+func databaseReplicasFromProto(replicas []*corev1.DatabaseReplica) []DatabaseReplica {
+	result := make([]DatabaseReplica, len(replicas))
+	for i, replica := range replicas {
+		result[i] = DatabaseReplica{
+			Host:     replica.Host,
+			Port:     replica.Port,
+			Username: replica.Username,
+			Password: replica.Password,
+		}
+	}
+	return result
+}
+
+func databaseRateLimitsFromProto(limits *corev1.DatabaseRateLimits) *DatabaseRateLimits {
+	if limits == nil {
+		return nil
+	}
+	return &DatabaseRateLimits{
+		RowsPerSecond:           limits.RowsPerSecond,
+		QueriesPerSecond:        limits.QueriesPerSecond,
+		MaxConcurrentStatements: limits.MaxConcurrentStatements,
+	}
+}
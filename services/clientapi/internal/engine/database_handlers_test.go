@@ -149,3 +149,23 @@ func TestTransformDataHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+func TestParseSnapshotSelector(t *testing.T) {
+	selector, err := parseSnapshotSelector("")
+	require.NoError(t, err)
+	assert.Nil(t, selector)
+
+	selector, err = parseSnapshotSelector("3")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), selector.GetVersion())
+
+	// Times keep their offset and fractional seconds
+	selector, err = parseSnapshotSelector("2026-10-13T09:00:00.50+02:00")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-13T09:00:00.5+02:00", selector.GetAt())
+
+	for _, value := range []string{"0", "-2", "last tuesday", "2026-10-13"} {
+		_, err := parseSnapshotSelector(value)
+		assert.Error(t, err, value)
+	}
+}
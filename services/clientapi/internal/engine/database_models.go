@@ -38,6 +38,31 @@ type Database struct {
 
 	// Resource registry data (structured)
 	ResourceContainers []DatabaseResourceContainer `json:"resource_containers,omitempty"`
+
+	// Read replicas
+	DatabaseReplicas      []DatabaseReplica `json:"database_replicas,omitempty"`
+	DatabaseRoutingPolicy string            `json:"database_routing_policy,omitempty"`
+
+	// Load guardrails, unset when the database is unlimited
+	DatabaseRateLimits *DatabaseRateLimits `json:"database_rate_limits,omitempty"`
+
+	// Labels selecting groups of databases
+	DatabaseLabels map[string]string `json:"database_labels,omitempty"`
+}
+
+// DatabaseReplica is a read replica of a database
+type DatabaseReplica struct {
+	Host     string `json:"host"`
+	Port     int32  `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// DatabaseRateLimits are the load guardrails of a database. Zero means unlimited.
+type DatabaseRateLimits struct {
+	RowsPerSecond           float64 `json:"rows_per_second,omitempty"`
+	QueriesPerSecond        float64 `json:"queries_per_second,omitempty"`
+	MaxConcurrentStatements int32   `json:"max_concurrent_statements,omitempty"`
 }
 
 // DatabaseResourceItem represents an item in a database resource container
@@ -116,6 +141,23 @@ type ConnectDatabaseResponse struct {
 	Status   Status   `json:"status"`
 }
 
+// UploadDatabaseFileRequest holds the multipart form fields of a file upload
+type UploadDatabaseFileRequest struct {
+	DatabaseName        string
+	DatabaseDescription string
+	NodeID              *string
+	EnvironmentID       string
+}
+
+type UploadDatabaseFileResponse struct {
+	Message  string   `json:"message"`
+	Success  bool     `json:"success"`
+	FileName string   `json:"file_name"`
+	FileSize int64    `json:"file_size"`
+	Database Database `json:"database"`
+	Status   Status   `json:"status"`
+}
+
 // ConnectDatabaseWithInstanceRequest represents the request for connecting a database to an existing instance
 type ConnectDatabaseWithInstanceRequest struct {
 	InstanceName        string  `json:"instance_name" validate:"required"`
@@ -138,23 +180,27 @@ type ConnectDatabaseWithInstanceResponse struct {
 }
 
 type ModifyDatabaseRequest struct {
-	DatabaseNameNew     string `json:"database_name_new,omitempty"`
-	DatabaseDescription string `json:"database_description,omitempty"`
-	DatabaseType        string `json:"database_type,omitempty"`
-	DatabaseVendor      string `json:"database_vendor,omitempty"`
-	Host                string `json:"host,omitempty"`
-	Port                *int32 `json:"port,omitempty"`
-	Username            string `json:"username,omitempty"`
-	Password            string `json:"password,omitempty"`
-	DBName              string `json:"db_name,omitempty"`
-	Enabled             *bool  `json:"enabled,omitempty"`
-	SSL                 *bool  `json:"ssl,omitempty"`
-	SSLMode             string `json:"ssl_mode,omitempty"`
-	SSLCert             string `json:"ssl_cert,omitempty"`
-	SSLKey              string `json:"ssl_key,omitempty"`
-	SSLRootCert         string `json:"ssl_root_cert,omitempty"`
-	EnvironmentID       string `json:"environment_id,omitempty"`
-	NodeID              string `json:"node_id,omitempty"`
+	DatabaseNameNew     string              `json:"database_name_new,omitempty"`
+	DatabaseDescription string              `json:"database_description,omitempty"`
+	DatabaseType        string              `json:"database_type,omitempty"`
+	DatabaseVendor      string              `json:"database_vendor,omitempty"`
+	Host                string              `json:"host,omitempty"`
+	Port                *int32              `json:"port,omitempty"`
+	Username            string              `json:"username,omitempty"`
+	Password            string              `json:"password,omitempty"`
+	DBName              string              `json:"db_name,omitempty"`
+	Enabled             *bool               `json:"enabled,omitempty"`
+	SSL                 *bool               `json:"ssl,omitempty"`
+	SSLMode             string              `json:"ssl_mode,omitempty"`
+	SSLCert             string              `json:"ssl_cert,omitempty"`
+	SSLKey              string              `json:"ssl_key,omitempty"`
+	SSLRootCert         string              `json:"ssl_root_cert,omitempty"`
+	EnvironmentID       string              `json:"environment_id,omitempty"`
+	NodeID              string              `json:"node_id,omitempty"`
+	Replicas            *[]DatabaseReplica  `json:"replicas,omitempty"`
+	RoutingPolicy       string              `json:"routing_policy,omitempty"`
+	RateLimits          *DatabaseRateLimits `json:"rate_limits,omitempty"`
+	Labels              *map[string]string  `json:"labels,omitempty"`
 }
 
 type ModifyDatabaseResponse struct {
@@ -208,6 +254,70 @@ type GetLatestStoredDatabaseSchemaResponse struct {
 	Schema  interface{} `json:"schema"`
 }
 
+// DatabaseOperationMetrics are the latency and error metrics of one adapter operation on one database
+type DatabaseOperationMetrics struct {
+	DatabaseID       string  `json:"database_id"`
+	DatabaseName     string  `json:"database_name"`
+	DatabaseType     string  `json:"database_type"`
+	Operation        string  `json:"operation"`
+	Count            int64   `json:"count"`
+	Errors           int64   `json:"errors"`
+	ErrorRate        float64 `json:"error_rate"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	MinLatencyMs     float64 `json:"min_latency_ms"`
+	MaxLatencyMs     float64 `json:"max_latency_ms"`
+	P50LatencyMs     float64 `json:"p50_latency_ms"`
+	P95LatencyMs     float64 `json:"p95_latency_ms"`
+	P99LatencyMs     float64 `json:"p99_latency_ms"`
+	LastLatencyMs    float64 `json:"last_latency_ms"`
+	LastCalledAt     string  `json:"last_called_at,omitempty"`
+	LastError        string  `json:"last_error,omitempty"`
+	LastErrorAt      string  `json:"last_error_at,omitempty"`
+	LatencyHistogram []int64 `json:"latency_histogram"`
+}
+
+// DatabaseMetricsSummary aggregates the operations of one database
+type DatabaseMetricsSummary struct {
+	DatabaseID       string  `json:"database_id"`
+	DatabaseName     string  `json:"database_name"`
+	DatabaseType     string  `json:"database_type"`
+	Operations       int64   `json:"operations"`
+	Errors           int64   `json:"errors"`
+	ErrorRate        float64 `json:"error_rate"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	MaxP95LatencyMs  float64 `json:"max_p95_latency_ms"`
+	SlowestOperation string  `json:"slowest_operation,omitempty"`
+	LastError        string  `json:"last_error,omitempty"`
+	LastErrorAt      string  `json:"last_error_at,omitempty"`
+}
+
+// DatabaseQueryPlan is the execution plan captured for a fetch slower than the anchor's threshold
+type DatabaseQueryPlan struct {
+	DatabaseID   string  `json:"database_id"`
+	DatabaseName string  `json:"database_name"`
+	DatabaseType string  `json:"database_type"`
+	Operation    string  `json:"operation"`
+	TableName    string  `json:"table_name"`
+	Limit        int32   `json:"limit"`
+	DurationMs   float64 `json:"duration_ms"`
+	CapturedAt   string  `json:"captured_at"`
+	Query        string  `json:"query,omitempty"`
+	PlanFormat   string  `json:"plan_format,omitempty"`
+	Plan         string  `json:"plan,omitempty"`
+	Analyzed     bool    `json:"analyzed"`
+	Error        string  `json:"error,omitempty"`
+}
+
+type GetDatabaseMetricsResponse struct {
+	Message               string                     `json:"message"`
+	Success               bool                       `json:"success"`
+	Status                Status                     `json:"status"`
+	Databases             []DatabaseMetricsSummary   `json:"databases"`
+	Operations            []DatabaseOperationMetrics `json:"operations"`
+	LatencyBucketBoundsMs []float64                  `json:"latency_bucket_bounds_ms"`
+	QueryPlans            []DatabaseQueryPlan        `json:"query_plans"`
+}
+
 type WipeDatabaseResponse struct {
 	Message string `json:"message"`
 	Success bool   `json:"success"`
@@ -0,0 +1,268 @@
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// defaultMaxUploadSize is the default limit for uploaded source files (100 MB)
+const defaultMaxUploadSize int64 = 100 << 20
+
+// allowedUploadExtensions lists the file types accepted as ad-hoc mapping sources
+var allowedUploadExtensions = map[string]bool{
+	".csv":  true,
+	".tsv":  true,
+	".xlsx": true,
+}
+
+// UploadDatabaseFile handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/upload
+// It stores an uploaded CSV/XLSX file on the node and connects it as a read-only file source
+// database, so its inferred schema can be mapped into database targets.
+func (dh *DatabaseHandlers) UploadDatabaseFile(w http.ResponseWriter, r *http.Request) {
+	dh.engine.TrackOperation()
+	defer dh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+
+	if tenantURL == "" || workspaceName == "" {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url and workspace_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	maxSize := dh.maxUploadSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid multipart upload", fmt.Sprintf("files are limited to %d bytes", maxSize))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "file is required")
+		return
+	}
+	defer file.Close()
+
+	req := UploadDatabaseFileRequest{
+		DatabaseName:        r.FormValue("database_name"),
+		DatabaseDescription: r.FormValue("database_description"),
+		EnvironmentID:       r.FormValue("environment_id"),
+	}
+	if nodeID := r.FormValue("node_id"); nodeID != "" {
+		req.NodeID = &nodeID
+	}
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !allowedUploadExtensions[ext] {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Unsupported file type", "supported file types are .csv, .tsv and .xlsx")
+		return
+	}
+
+	if req.DatabaseName == "" {
+		req.DatabaseName = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+	if req.DatabaseDescription == "" {
+		req.DatabaseDescription = fmt.Sprintf("Uploaded file %s", filepath.Base(header.Filename))
+	}
+
+	// Log request
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Upload database file request for workspace: %s, file: %s, tenant: %s", workspaceName, header.Filename, profile.TenantId)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	// The file is stored on this node, so it can only be connected by this node's anchor
+	localNodeID, err := dh.localNodeID(ctx)
+	if err != nil {
+		dh.handleGRPCError(w, err, "Failed to resolve local node")
+		return
+	}
+	if req.NodeID != nil && *req.NodeID != localNodeID {
+		dh.writeErrorResponse(w, http.StatusBadRequest, "Invalid node_id", fmt.Sprintf("uploaded files can only be connected on the receiving node %s", localNodeID))
+		return
+	}
+	req.NodeID = &localNodeID
+
+	storedPath, err := dh.storeUploadedFile(profile.TenantId, workspaceName, header.Filename, file)
+	if err != nil {
+		dh.writeErrorResponse(w, http.StatusInternalServerError, "Failed to store uploaded file", err.Error())
+		return
+	}
+
+	// Call core service gRPC. The file path is passed as the database name of the file source.
+	grpcReq := &corev1.ConnectDatabaseRequest{
+		TenantId:            profile.TenantId,
+		WorkspaceName:       workspaceName,
+		OwnerId:             profile.UserId,
+		DatabaseName:        req.DatabaseName,
+		DatabaseDescription: req.DatabaseDescription,
+		DatabaseType:        string(dbcapabilities.File),
+		DatabaseVendor:      "custom",
+		Host:                "localhost",
+		Port:                0,
+		DbName:              storedPath,
+		NodeId:              req.NodeID,
+	}
+
+	if req.EnvironmentID != "" {
+		grpcReq.EnvironmentId = &req.EnvironmentID
+	}
+
+	grpcResp, err := dh.engine.databaseClient.ConnectDatabase(ctx, grpcReq)
+	if err != nil {
+		os.Remove(storedPath)
+		dh.handleGRPCError(w, err, "Failed to connect uploaded file")
+		return
+	}
+
+	// Convert gRPC response to REST response
+	database := Database{
+		TenantID:              grpcResp.Database.TenantId,
+		WorkspaceID:           grpcResp.Database.WorkspaceId,
+		EnvironmentID:         grpcResp.Database.EnvironmentId,
+		ConnectedToNodeID:     grpcResp.Database.ConnectedToNodeId,
+		InstanceID:            grpcResp.Database.InstanceId,
+		InstanceName:          grpcResp.Database.InstanceName,
+		DatabaseID:            grpcResp.Database.DatabaseId,
+		DatabaseName:          grpcResp.Database.DatabaseName,
+		DatabaseDescription:   grpcResp.Database.DatabaseDescription,
+		DatabaseType:          grpcResp.Database.DatabaseType,
+		DatabaseVendor:        grpcResp.Database.DatabaseVendor,
+		DatabaseVersion:       grpcResp.Database.DatabaseVersion,
+		DatabaseDBName:        grpcResp.Database.DatabaseDbName,
+		DatabaseEnabled:       grpcResp.Database.DatabaseEnabled,
+		PolicyIDs:             grpcResp.Database.PolicyIds,
+		OwnerID:               grpcResp.Database.OwnerId,
+		DatabaseStatusMessage: grpcResp.Database.DatabaseStatusMessage,
+		Status:                convertStatus(grpcResp.Database.Status),
+		Created:               grpcResp.Database.Created,
+		Updated:               grpcResp.Database.Updated,
+	}
+
+	response := UploadDatabaseFileResponse{
+		Message:  grpcResp.Message,
+		Success:  grpcResp.Success,
+		FileName: filepath.Base(header.Filename),
+		FileSize: header.Size,
+		Database: database,
+		Status:   convertStatus(grpcResp.Status),
+	}
+
+	if dh.engine.logger != nil {
+		dh.engine.logger.Infof("Successfully connected uploaded file %s as database: %s for workspace: %s", header.Filename, req.DatabaseName, workspaceName)
+	}
+
+	dh.writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// localNodeID returns the ID of the node this client API runs on
+func (dh *DatabaseHandlers) localNodeID(ctx context.Context) (string, error) {
+	resp, err := dh.engine.meshClient.ShowNode(ctx, &corev1.ShowNodeRequest{})
+	if err != nil {
+		return "", err
+	}
+	if resp.Node == nil || resp.Node.NodeId == "" {
+		return "", fmt.Errorf("local node is not initialized")
+	}
+	return resp.Node.NodeId, nil
+}
+
+// storeUploadedFile writes the upload into the per-tenant upload directory and returns its absolute path
+func (dh *DatabaseHandlers) storeUploadedFile(tenantID, workspaceName, fileName string, src io.Reader) (string, error) {
+	dir := filepath.Join(dh.uploadDir(), sanitizePathSegment(tenantID), sanitizePathSegment(workspaceName))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate file name: %w", err)
+	}
+
+	base := sanitizePathSegment(strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName)))
+	target := filepath.Join(dir, fmt.Sprintf("%s_%s%s", base, hex.EncodeToString(suffix), strings.ToLower(filepath.Ext(fileName))))
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(target)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(target)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return filepath.Abs(target)
+}
+
+// uploadDir returns the configured upload directory, defaulting to a directory under the system temp dir
+func (dh *DatabaseHandlers) uploadDir() string {
+	if dh.engine.config != nil {
+		if dir := dh.engine.config.Get("services.clientapi.upload_dir"); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(os.TempDir(), "redb-uploads")
+}
+
+// maxUploadSize returns the configured upload size limit in bytes
+func (dh *DatabaseHandlers) maxUploadSize() int64 {
+	if dh.engine.config != nil {
+		if v := dh.engine.config.Get("services.clientapi.max_upload_size"); v != "" {
+			if parsed, err := strconv.ParseInt(v, 10, 64); err == nil && parsed > 0 {
+				return parsed
+			}
+		}
+	}
+	return defaultMaxUploadSize
+}
+
+// sanitizePathSegment keeps only characters that are safe in a single path segment
+func sanitizePathSegment(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "upload"
+	}
+	return b.String()
+}
@@ -473,10 +473,13 @@ func (dph *DataProductHandlers) protoToDataProduct(proto *corev1.DataProduct) Da
 func (dph *DataProductHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, detail string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
+	code, remediation := errorCatalogFields(statusCode, message, detail)
 	response := map[string]interface{}{
-		"error":   message,
-		"message": detail,
-		"success": false,
+		"error":       translate(w, message),
+		"message":     detail,
+		"success":     false,
+		"code":        code,
+		"remediation": translateAll(w, remediation),
 	}
 	json.NewEncoder(w).Encode(response)
 }
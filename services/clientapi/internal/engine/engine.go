@@ -20,8 +20,8 @@ import (
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/keyring"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 )
 
@@ -50,6 +50,8 @@ type Engine struct {
 	commitClient         corev1.CommitServiceClient
 	mappingClient        corev1.MappingServiceClient
 	relationshipClient   corev1.RelationshipServiceClient
+	cutoverClient        corev1.CutoverServiceClient
+	queryProxyClient     corev1.QueryProxyServiceClient
 	transformationClient corev1.TransformationServiceClient
 	policyClient         corev1.PolicyServiceClient
 	mcpClient            corev1.MCPServiceClient
@@ -66,6 +68,8 @@ type Engine struct {
 	importExportClient   corev1.ImportExportServiceClient
 	resourceClient       corev1.ResourceServiceClient
 	dataProductClient    corev1.DataProductServiceClient
+	artifactClient       corev1.ArtifactServiceClient
+	retentionClient      corev1.RetentionServiceClient
 	logger               *logger.Logger
 	state                struct {
 		sync.Mutex
@@ -114,7 +118,7 @@ func (e *Engine) Start(ctx context.Context) error {
 	defer cancel()
 
 	coreDialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		spiffe.DialOption(),
 		grpc.WithBlock(), // Wait for connection to be established
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                10 * time.Second, // Send keepalive pings every 10 seconds
@@ -153,6 +157,8 @@ func (e *Engine) Start(ctx context.Context) error {
 	e.commitClient = corev1.NewCommitServiceClient(coreConn)
 	e.mappingClient = corev1.NewMappingServiceClient(coreConn)
 	e.relationshipClient = corev1.NewRelationshipServiceClient(coreConn)
+	e.cutoverClient = corev1.NewCutoverServiceClient(coreConn)
+	e.queryProxyClient = corev1.NewQueryProxyServiceClient(coreConn)
 	e.transformationClient = corev1.NewTransformationServiceClient(coreConn)
 	e.policyClient = corev1.NewPolicyServiceClient(coreConn)
 	e.mcpClient = corev1.NewMCPServiceClient(coreConn)
@@ -169,6 +175,8 @@ func (e *Engine) Start(ctx context.Context) error {
 	e.importExportClient = corev1.NewImportExportServiceClient(coreConn)
 	e.resourceClient = corev1.NewResourceServiceClient(coreConn)
 	e.dataProductClient = corev1.NewDataProductServiceClient(coreConn)
+	e.artifactClient = corev1.NewArtifactServiceClient(coreConn)
+	e.retentionClient = corev1.NewRetentionServiceClient(coreConn)
 
 	// Connect to security service using dynamic address resolution
 	securityAddr := grpcconfig.GetServiceAddress(e.config, "security")
@@ -183,7 +191,7 @@ func (e *Engine) Start(ctx context.Context) error {
 	defer securityCancel()
 
 	securityDialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		spiffe.DialOption(),
 		grpc.WithBlock(), // Wait for connection to be established
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                10 * time.Second, // Send keepalive pings every 10 seconds
@@ -224,7 +232,7 @@ func (e *Engine) Start(ctx context.Context) error {
 
 	// Create connection without blocking - connection will be established in background
 	streamDialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		spiffe.DialOption(),
 		// Removed grpc.WithBlock() to allow non-blocking connection
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                10 * time.Second, // Send keepalive pings every 10 seconds
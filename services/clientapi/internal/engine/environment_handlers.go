@@ -517,6 +517,8 @@ func (eh *EnvironmentHandlers) writeErrorResponse(w http.ResponseWriter, statusC
 		Message: message,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/redbco/redb-open/pkg/errorcatalog"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorCatalogResponse lists the error codes of the API
+type ErrorCatalogResponse struct {
+	Errors []errorcatalog.Entry `json:"errors"`
+}
+
+// httpStatusGRPCCodes maps HTTP status codes to the gRPC codes errors are classified with
+var httpStatusGRPCCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusPreconditionFailed:  codes.FailedPrecondition,
+	http.StatusRequestTimeout:      codes.DeadlineExceeded,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// errorCatalogFields returns the error catalog code and remediation of an error response,
+// from the code carried by its messages or, failing that, classified from them
+func errorCatalogFields(statusCode int, messages ...string) (string, []string) {
+	grpcCode, ok := httpStatusGRPCCodes[statusCode]
+	if !ok {
+		grpcCode = codes.Unknown
+	}
+
+	code := errorcatalog.Classify(grpcCode, strings.Join(messages, "\n"))
+	entry, ok := errorcatalog.Lookup(code)
+	if !ok {
+		return string(code), nil
+	}
+	return string(code), entry.Remediation
+}
+
+// attachErrorCode sets the error catalog code and remediation of the response
+func (r *ErrorResponse) attachErrorCode(statusCode int) {
+	r.Code, r.Remediation = errorCatalogFields(statusCode, r.Error, r.Message)
+}
+
+// handleErrorCatalog handles GET /api/v1/errors
+func (s *Server) handleErrorCatalog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	entries := errorcatalog.All()
+	for i := range entries {
+		entries[i] = localizeEntry(w, entries[i])
+	}
+	json.NewEncoder(w).Encode(ErrorCatalogResponse{Errors: entries})
+}
+
+// handleErrorCode handles GET /api/v1/errors/{code}
+func (s *Server) handleErrorCode(w http.ResponseWriter, r *http.Request) {
+	code := errorcatalog.Code(strings.ToUpper(mux.Vars(r)["code"]))
+
+	w.Header().Set("Content-Type", "application/json")
+	entry, ok := errorcatalog.Lookup(code)
+	if !ok {
+		response := ErrorResponse{
+			Error:   "error code not found",
+			Message: "unknown error code " + string(code),
+			Status:  StatusError,
+		}
+		response.localize(w)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(localizeEntry(w, entry))
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -101,6 +102,10 @@ func (ih *InstanceHandlers) ListInstances(w http.ResponseWriter, r *http.Request
 			InstanceSSLCert:          inst.InstanceSslCert,
 			InstanceSSLKey:           inst.InstanceSslKey,
 			InstanceSSLRootCert:      inst.InstanceSslRootCert,
+			InstanceSSHTunnel:        sshTunnelFromProto(inst.InstanceSshTunnel),
+			InstanceIAMAuth:          iamAuthFromProto(inst.InstanceIamAuth),
+			InstanceSocketPath:       inst.InstanceSocketPath,
+			InstanceKerberosAuth:     kerberosAuthFromProto(inst.InstanceKerberosAuth),
 			PolicyIDs:                inst.PolicyIds,
 			OwnerID:                  inst.OwnerId,
 			InstanceStatusMessage:    inst.InstanceStatusMessage,
@@ -200,6 +205,10 @@ func (ih *InstanceHandlers) ShowInstance(w http.ResponseWriter, r *http.Request)
 		InstanceSSLCert:          grpcResp.Instance.InstanceSslCert,
 		InstanceSSLKey:           grpcResp.Instance.InstanceSslKey,
 		InstanceSSLRootCert:      grpcResp.Instance.InstanceSslRootCert,
+		InstanceSSHTunnel:        sshTunnelFromProto(grpcResp.Instance.InstanceSshTunnel),
+		InstanceIAMAuth:          iamAuthFromProto(grpcResp.Instance.InstanceIamAuth),
+		InstanceSocketPath:       grpcResp.Instance.InstanceSocketPath,
+		InstanceKerberosAuth:     kerberosAuthFromProto(grpcResp.Instance.InstanceKerberosAuth),
 		PolicyIDs:                grpcResp.Instance.PolicyIds,
 		OwnerID:                  grpcResp.Instance.OwnerId,
 		InstanceStatusMessage:    grpcResp.Instance.InstanceStatusMessage,
@@ -320,6 +329,16 @@ func (ih *InstanceHandlers) ConnectInstance(w http.ResponseWriter, r *http.Reque
 	if req.EnvironmentID != "" {
 		grpcReq.EnvironmentId = &req.EnvironmentID
 	}
+	if req.SSHTunnel != nil {
+		grpcReq.SshTunnel = sshTunnelToProto(req.SSHTunnel)
+	}
+	if req.IAMAuth != nil {
+		grpcReq.IamAuth = iamAuthToProto(req.IAMAuth)
+	}
+	grpcReq.SocketPath = req.SocketPath
+	if req.KerberosAuth != nil {
+		grpcReq.KerberosAuth = kerberosAuthToProto(req.KerberosAuth)
+	}
 
 	// Detect vendor from host if not provided
 	vendor := req.InstanceVendor
@@ -361,6 +380,10 @@ func (ih *InstanceHandlers) ConnectInstance(w http.ResponseWriter, r *http.Reque
 		InstanceSSLCert:          grpcResp.Instance.InstanceSslCert,
 		InstanceSSLKey:           grpcResp.Instance.InstanceSslKey,
 		InstanceSSLRootCert:      grpcResp.Instance.InstanceSslRootCert,
+		InstanceSSHTunnel:        sshTunnelFromProto(grpcResp.Instance.InstanceSshTunnel),
+		InstanceIAMAuth:          iamAuthFromProto(grpcResp.Instance.InstanceIamAuth),
+		InstanceSocketPath:       grpcResp.Instance.InstanceSocketPath,
+		InstanceKerberosAuth:     kerberosAuthFromProto(grpcResp.Instance.InstanceKerberosAuth),
 		PolicyIDs:                grpcResp.Instance.PolicyIds,
 		OwnerID:                  grpcResp.Instance.OwnerId,
 		InstanceStatusMessage:    grpcResp.Instance.InstanceStatusMessage,
@@ -536,6 +559,16 @@ func (ih *InstanceHandlers) ModifyInstance(w http.ResponseWriter, r *http.Reques
 	if req.NodeID != "" {
 		grpcReq.NodeId = &req.NodeID
 	}
+	if req.SSHTunnel != nil {
+		grpcReq.SshTunnel = sshTunnelToProto(req.SSHTunnel)
+	}
+	if req.IAMAuth != nil {
+		grpcReq.IamAuth = iamAuthToProto(req.IAMAuth)
+	}
+	grpcReq.SocketPath = req.SocketPath
+	if req.KerberosAuth != nil {
+		grpcReq.KerberosAuth = kerberosAuthToProto(req.KerberosAuth)
+	}
 
 	grpcResp, err := ih.engine.instanceClient.ModifyInstance(ctx, grpcReq)
 	if err != nil {
@@ -567,6 +600,10 @@ func (ih *InstanceHandlers) ModifyInstance(w http.ResponseWriter, r *http.Reques
 		InstanceSSLCert:          grpcResp.Instance.InstanceSslCert,
 		InstanceSSLKey:           grpcResp.Instance.InstanceSslKey,
 		InstanceSSLRootCert:      grpcResp.Instance.InstanceSslRootCert,
+		InstanceSSHTunnel:        sshTunnelFromProto(grpcResp.Instance.InstanceSshTunnel),
+		InstanceIAMAuth:          iamAuthFromProto(grpcResp.Instance.InstanceIamAuth),
+		InstanceSocketPath:       grpcResp.Instance.InstanceSocketPath,
+		InstanceKerberosAuth:     kerberosAuthFromProto(grpcResp.Instance.InstanceKerberosAuth),
 		PolicyIDs:                grpcResp.Instance.PolicyIds,
 		OwnerID:                  grpcResp.Instance.OwnerId,
 		InstanceStatusMessage:    grpcResp.Instance.InstanceStatusMessage,
@@ -722,6 +759,160 @@ func (ih *InstanceHandlers) CreateDatabase(w http.ResponseWriter, r *http.Reques
 	ih.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// ListInstanceDatabases handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/instances/{instance_name}/databases
+func (ih *InstanceHandlers) ListInstanceDatabases(w http.ResponseWriter, r *http.Request) {
+	ih.engine.TrackOperation()
+	defer ih.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	instanceName := vars["instance_name"]
+
+	if tenantURL == "" || workspaceName == "" || instanceName == "" {
+		ih.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name, and instance_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ih.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Log request
+	if ih.engine.logger != nil {
+		ih.engine.logger.Infof("List instance databases request for instance: %s, workspace: %s, tenant: %s", instanceName, workspaceName, profile.TenantId)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcReq := &corev1.ListInstanceDatabasesRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		InstanceName:  instanceName,
+		Filter:        r.URL.Query().Get("filter"),
+	}
+
+	grpcResp, err := ih.engine.instanceClient.ListInstanceDatabases(ctx, grpcReq)
+	if err != nil {
+		ih.handleGRPCError(w, err, "Failed to list instance databases")
+		return
+	}
+
+	databases := make([]InstanceDatabase, len(grpcResp.Databases))
+	for i, db := range grpcResp.Databases {
+		databases[i] = InstanceDatabase{
+			DBName:       db.DbName,
+			Attached:     db.Attached,
+			DatabaseName: db.DatabaseName,
+		}
+	}
+
+	response := ListInstanceDatabasesResponse{
+		Message:   grpcResp.Message,
+		Success:   grpcResp.Success,
+		Databases: databases,
+		Status:    convertStatus(grpcResp.Status),
+	}
+
+	ih.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// AttachInstanceDatabases handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/instances/{instance_name}/attach-databases
+func (ih *InstanceHandlers) AttachInstanceDatabases(w http.ResponseWriter, r *http.Request) {
+	ih.engine.TrackOperation()
+	defer ih.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	instanceName := vars["instance_name"]
+
+	if tenantURL == "" || workspaceName == "" || instanceName == "" {
+		ih.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name, and instance_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		ih.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Parse request body, an empty body attaching all databases
+	var req AttachInstanceDatabasesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		if ih.engine.logger != nil {
+			ih.engine.logger.Errorf("Failed to parse attach instance databases request body: %v", err)
+		}
+		ih.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	// Log request
+	if ih.engine.logger != nil {
+		ih.engine.logger.Infof("Attach instance databases request for instance: %s, workspace: %s, tenant: %s, filter: %q", instanceName, workspaceName, profile.TenantId, req.Filter)
+	}
+
+	// Attaching connects every database through the anchor service, which takes a while for
+	// large instances
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Minute)
+	defer cancel()
+
+	grpcReq := &corev1.AttachInstanceDatabasesRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		InstanceName:  instanceName,
+		Filter:        req.Filter,
+		Exclude:       req.Exclude,
+		NamePrefix:    req.NamePrefix,
+		NodeId:        req.NodeID,
+		Enabled:       req.Enabled,
+		EnvironmentId: req.EnvironmentID,
+		OwnerId:       profile.UserId,
+	}
+
+	grpcResp, err := ih.engine.instanceClient.AttachInstanceDatabases(ctx, grpcReq)
+	if err != nil {
+		ih.handleGRPCError(w, err, "Failed to attach instance databases")
+		return
+	}
+
+	results := make([]AttachedInstanceDatabase, len(grpcResp.Results))
+	for i, result := range grpcResp.Results {
+		results[i] = AttachedInstanceDatabase{
+			DBName:       result.DbName,
+			DatabaseName: result.DatabaseName,
+			Success:      result.Success,
+			Message:      result.Message,
+		}
+	}
+
+	response := AttachInstanceDatabasesResponse{
+		Message:       grpcResp.Message,
+		Success:       grpcResp.Success,
+		Results:       results,
+		AttachedCount: grpcResp.AttachedCount,
+		SkippedCount:  grpcResp.SkippedCount,
+		FailedCount:   grpcResp.FailedCount,
+		Status:        convertStatus(grpcResp.Status),
+	}
+
+	if ih.engine.logger != nil {
+		ih.engine.logger.Infof("Attached %d databases of instance %s (%d skipped, %d failed)", grpcResp.AttachedCount, instanceName, grpcResp.SkippedCount, grpcResp.FailedCount)
+	}
+
+	// Some databases may have failed while others were attached; the results tell which
+	ih.writeJSONResponse(w, http.StatusOK, response)
+}
+
 // Helper methods
 
 // handleGRPCError handles gRPC errors and converts them to HTTP responses
@@ -743,6 +934,8 @@ func (ih *InstanceHandlers) handleGRPCError(w http.ResponseWriter, err error, de
 		ih.writeErrorResponse(w, http.StatusConflict, "Resource already exists", st.Message())
 	case codes.InvalidArgument:
 		ih.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", st.Message())
+	case codes.FailedPrecondition:
+		ih.writeErrorResponse(w, http.StatusPreconditionFailed, "Precondition failed", st.Message())
 	case codes.PermissionDenied:
 		ih.writeErrorResponse(w, http.StatusForbidden, "Permission denied", st.Message())
 	case codes.Unauthenticated:
@@ -785,6 +978,8 @@ func (ih *InstanceHandlers) writeErrorResponse(w http.ResponseWriter, statusCode
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -1360,6 +1555,10 @@ func (ih *InstanceHandlers) ConnectInstanceString(w http.ResponseWriter, r *http
 		InstanceSSLCert:          grpcResp.Instance.InstanceSslCert,
 		InstanceSSLKey:           grpcResp.Instance.InstanceSslKey,
 		InstanceSSLRootCert:      grpcResp.Instance.InstanceSslRootCert,
+		InstanceSSHTunnel:        sshTunnelFromProto(grpcResp.Instance.InstanceSshTunnel),
+		InstanceIAMAuth:          iamAuthFromProto(grpcResp.Instance.InstanceIamAuth),
+		InstanceSocketPath:       grpcResp.Instance.InstanceSocketPath,
+		InstanceKerberosAuth:     kerberosAuthFromProto(grpcResp.Instance.InstanceKerberosAuth),
 		PolicyIDs:                grpcResp.Instance.PolicyIds,
 		OwnerID:                  grpcResp.Instance.OwnerId,
 		InstanceStatusMessage:    grpcResp.Instance.InstanceStatusMessage,
@@ -1386,3 +1585,77 @@ func (ih *InstanceHandlers) ConnectInstanceString(w http.ResponseWriter, r *http
 func (ih *InstanceHandlers) parseConnectionString(connectionString string) (*dbcapabilities.ConnectionDetails, error) {
 	return dbcapabilities.ParseConnectionString(connectionString)
 }
+
+func sshTunnelToProto(tunnel *InstanceSSHTunnel) *corev1.InstanceSSHTunnel {
+	return &corev1.InstanceSSHTunnel{
+		Host:                     tunnel.Host,
+		Port:                     tunnel.Port,
+		Username:                 tunnel.Username,
+		PrivateKey:               tunnel.PrivateKey,
+		PrivateKeyPassphrase:     tunnel.PrivateKeyPassphrase,
+		Password:                 tunnel.Password,
+		UseAgent:                 tunnel.UseAgent,
+		HostKey:                  tunnel.HostKey,
+		InsecureSkipHostKeyCheck: tunnel.InsecureSkipHostKeyCheck,
+	}
+}
+
+func sshTunnelFromProto(tunnel *corev1.InstanceSSHTunnel) *InstanceSSHTunnel {
+	if tunnel == nil {
+		return nil
+	}
+	return &InstanceSSHTunnel{
+		Host:                     tunnel.Host,
+		Port:                     tunnel.Port,
+		Username:                 tunnel.Username,
+		UseAgent:                 tunnel.UseAgent,
+		HostKey:                  tunnel.HostKey,
+		InsecureSkipHostKeyCheck: tunnel.InsecureSkipHostKeyCheck,
+	}
+}
+
+func iamAuthToProto(auth *InstanceIAMAuth) *corev1.InstanceIAMAuth {
+	return &corev1.InstanceIAMAuth{
+		Provider: auth.Provider,
+		Region:   auth.Region,
+		Scope:    auth.Scope,
+		ClientId: auth.ClientID,
+	}
+}
+
+func iamAuthFromProto(auth *corev1.InstanceIAMAuth) *InstanceIAMAuth {
+	if auth == nil {
+		return nil
+	}
+	return &InstanceIAMAuth{
+		Provider: auth.Provider,
+		Region:   auth.Region,
+		Scope:    auth.Scope,
+		ClientID: auth.ClientId,
+	}
+}
+
+func kerberosAuthToProto(auth *InstanceKerberosAuth) *corev1.InstanceKerberosAuth {
+	return &corev1.InstanceKerberosAuth{
+		Principal: auth.Principal,
+		Realm:     auth.Realm,
+		Keytab:    auth.Keytab,
+		Ccache:    auth.CCache,
+		Krb5Conf:  auth.Krb5Conf,
+		Spn:       auth.SPN,
+	}
+}
+
+func kerberosAuthFromProto(auth *corev1.InstanceKerberosAuth) *InstanceKerberosAuth {
+	if auth == nil {
+		return nil
+	}
+	return &InstanceKerberosAuth{
+		Principal: auth.Principal,
+		Realm:     auth.Realm,
+		Keytab:    auth.Keytab,
+		CCache:    auth.Ccache,
+		Krb5Conf:  auth.Krb5Conf,
+		SPN:       auth.Spn,
+	}
+}
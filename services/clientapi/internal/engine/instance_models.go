@@ -2,34 +2,72 @@ package engine
 
 // Instance represents an instance
 type Instance struct {
-	TenantID                 string   `json:"tenant_id"`
-	WorkspaceID              string   `json:"workspace_id"`
-	EnvironmentID            string   `json:"environment_id"`
-	InstanceID               string   `json:"instance_id"`
-	InstanceName             string   `json:"instance_name"`
-	InstanceDescription      string   `json:"instance_description,omitempty"`
-	InstanceType             string   `json:"instance_type"`
-	InstanceVendor           string   `json:"instance_vendor"`
-	InstanceVersion          string   `json:"instance_version"`
-	InstanceUniqueIdentifier string   `json:"instance_unique_identifier"`
-	ConnectedToNodeID        string   `json:"connected_to_node_id"`
-	InstanceHost             string   `json:"instance_host"`
-	InstancePort             int32    `json:"instance_port"`
-	InstanceUsername         string   `json:"instance_username"`
-	InstancePassword         string   `json:"instance_password"`
-	InstanceSystemDBName     string   `json:"instance_system_db_name"`
-	InstanceEnabled          bool     `json:"instance_enabled"`
-	InstanceSSL              bool     `json:"instance_ssl"`
-	InstanceSSLMode          string   `json:"instance_ssl_mode"`
-	InstanceSSLCert          string   `json:"instance_ssl_cert"`
-	InstanceSSLKey           string   `json:"instance_ssl_key"`
-	InstanceSSLRootCert      string   `json:"instance_ssl_root_cert"`
-	PolicyIDs                []string `json:"policy_ids"`
-	OwnerID                  string   `json:"owner_id"`
-	InstanceStatusMessage    string   `json:"instance_status_message"`
-	Status                   Status   `json:"status"`
-	Created                  string   `json:"created"`
-	Updated                  string   `json:"updated"`
+	TenantID                 string                `json:"tenant_id"`
+	WorkspaceID              string                `json:"workspace_id"`
+	EnvironmentID            string                `json:"environment_id"`
+	InstanceID               string                `json:"instance_id"`
+	InstanceName             string                `json:"instance_name"`
+	InstanceDescription      string                `json:"instance_description,omitempty"`
+	InstanceType             string                `json:"instance_type"`
+	InstanceVendor           string                `json:"instance_vendor"`
+	InstanceVersion          string                `json:"instance_version"`
+	InstanceUniqueIdentifier string                `json:"instance_unique_identifier"`
+	ConnectedToNodeID        string                `json:"connected_to_node_id"`
+	InstanceHost             string                `json:"instance_host"`
+	InstancePort             int32                 `json:"instance_port"`
+	InstanceUsername         string                `json:"instance_username"`
+	InstancePassword         string                `json:"instance_password"`
+	InstanceSystemDBName     string                `json:"instance_system_db_name"`
+	InstanceEnabled          bool                  `json:"instance_enabled"`
+	InstanceSSL              bool                  `json:"instance_ssl"`
+	InstanceSSLMode          string                `json:"instance_ssl_mode"`
+	InstanceSSLCert          string                `json:"instance_ssl_cert"`
+	InstanceSSLKey           string                `json:"instance_ssl_key"`
+	InstanceSSLRootCert      string                `json:"instance_ssl_root_cert"`
+	InstanceSSHTunnel        *InstanceSSHTunnel    `json:"instance_ssh_tunnel,omitempty"`
+	InstanceIAMAuth          *InstanceIAMAuth      `json:"instance_iam_auth,omitempty"`
+	InstanceSocketPath       string                `json:"instance_socket_path,omitempty"`
+	InstanceKerberosAuth     *InstanceKerberosAuth `json:"instance_kerberos_auth,omitempty"`
+	PolicyIDs                []string              `json:"policy_ids"`
+	OwnerID                  string                `json:"owner_id"`
+	InstanceStatusMessage    string                `json:"instance_status_message"`
+	Status                   Status                `json:"status"`
+	Created                  string                `json:"created"`
+	Updated                  string                `json:"updated"`
+}
+
+// InstanceSSHTunnel is the jump host through which an instance and its databases are reached.
+// Instances are returned with the tunnel's private key, passphrase and password left out.
+type InstanceSSHTunnel struct {
+	Host                     string `json:"host"`
+	Port                     int32  `json:"port,omitempty"`
+	Username                 string `json:"username"`
+	PrivateKey               string `json:"private_key,omitempty"`
+	PrivateKeyPassphrase     string `json:"private_key_passphrase,omitempty"`
+	Password                 string `json:"password,omitempty"`
+	UseAgent                 bool   `json:"use_agent,omitempty"`
+	HostKey                  string `json:"host_key,omitempty"`
+	InsecureSkipHostKeyCheck bool   `json:"insecure_skip_host_key_check,omitempty"`
+}
+
+// InstanceIAMAuth authenticates the anchor to an instance and its databases with short-lived
+// tokens of a cloud identity provider in place of the password.
+type InstanceIAMAuth struct {
+	Provider string `json:"provider"` // aws-rds, gcp-cloudsql or azure-ad
+	Region   string `json:"region,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// InstanceKerberosAuth authenticates the anchor to an instance and its databases with Kerberos
+// tickets of a keytab or credential cache on the anchor node in place of the password.
+type InstanceKerberosAuth struct {
+	Principal string `json:"principal,omitempty"`
+	Realm     string `json:"realm,omitempty"`
+	Keytab    string `json:"keytab,omitempty"`
+	CCache    string `json:"ccache,omitempty"`
+	Krb5Conf  string `json:"krb5_conf,omitempty"`
+	SPN       string `json:"spn,omitempty"`
 }
 
 type ListInstancesResponse struct {
@@ -41,22 +79,26 @@ type ShowInstanceResponse struct {
 }
 
 type ConnectInstanceRequest struct {
-	InstanceName        string  `json:"instance_name" validate:"required"`
-	InstanceDescription string  `json:"instance_description" validate:"required"`
-	InstanceType        string  `json:"instance_type" validate:"required"`
-	InstanceVendor      string  `json:"instance_vendor,omitempty"`
-	Host                string  `json:"host" validate:"required"`
-	Port                int32   `json:"port" validate:"required"`
-	Username            string  `json:"username,omitempty"`
-	Password            string  `json:"password,omitempty"`
-	NodeID              *string `json:"node_id,omitempty"`
-	Enabled             *bool   `json:"enabled,omitempty"`
-	SSL                 *bool   `json:"ssl,omitempty"`
-	SSLMode             string  `json:"ssl_mode,omitempty"`
-	SSLCert             string  `json:"ssl_cert,omitempty"`
-	SSLKey              string  `json:"ssl_key,omitempty"`
-	SSLRootCert         string  `json:"ssl_root_cert,omitempty"`
-	EnvironmentID       string  `json:"environment_id,omitempty"`
+	InstanceName        string                `json:"instance_name" validate:"required"`
+	InstanceDescription string                `json:"instance_description" validate:"required"`
+	InstanceType        string                `json:"instance_type" validate:"required"`
+	InstanceVendor      string                `json:"instance_vendor,omitempty"`
+	Host                string                `json:"host" validate:"required"`
+	Port                int32                 `json:"port" validate:"required"`
+	Username            string                `json:"username,omitempty"`
+	Password            string                `json:"password,omitempty"`
+	NodeID              *string               `json:"node_id,omitempty"`
+	Enabled             *bool                 `json:"enabled,omitempty"`
+	SSL                 *bool                 `json:"ssl,omitempty"`
+	SSLMode             string                `json:"ssl_mode,omitempty"`
+	SSLCert             string                `json:"ssl_cert,omitempty"`
+	SSLKey              string                `json:"ssl_key,omitempty"`
+	SSLRootCert         string                `json:"ssl_root_cert,omitempty"`
+	EnvironmentID       string                `json:"environment_id,omitempty"`
+	SSHTunnel           *InstanceSSHTunnel    `json:"ssh_tunnel,omitempty"`
+	IAMAuth             *InstanceIAMAuth      `json:"iam_auth,omitempty"`
+	SocketPath          string                `json:"socket_path,omitempty"`
+	KerberosAuth        *InstanceKerberosAuth `json:"kerberos_auth,omitempty"`
 }
 
 type ConnectInstanceResponse struct {
@@ -74,22 +116,26 @@ type ReconnectInstanceResponse struct {
 }
 
 type ModifyInstanceRequest struct {
-	InstanceNameNew     string `json:"instance_name_new,omitempty"`
-	InstanceDescription string `json:"instance_description,omitempty"`
-	InstanceType        string `json:"instance_type,omitempty"`
-	InstanceVendor      string `json:"instance_vendor,omitempty"`
-	Host                string `json:"host,omitempty"`
-	Port                *int32 `json:"port,omitempty"`
-	Username            string `json:"username,omitempty"`
-	Password            string `json:"password,omitempty"`
-	Enabled             *bool  `json:"enabled,omitempty"`
-	SSL                 *bool  `json:"ssl,omitempty"`
-	SSLMode             string `json:"ssl_mode,omitempty"`
-	SSLCert             string `json:"ssl_cert,omitempty"`
-	SSLKey              string `json:"ssl_key,omitempty"`
-	SSLRootCert         string `json:"ssl_root_cert,omitempty"`
-	EnvironmentID       string `json:"environment_id,omitempty"`
-	NodeID              string `json:"node_id,omitempty"`
+	InstanceNameNew     string                `json:"instance_name_new,omitempty"`
+	InstanceDescription string                `json:"instance_description,omitempty"`
+	InstanceType        string                `json:"instance_type,omitempty"`
+	InstanceVendor      string                `json:"instance_vendor,omitempty"`
+	Host                string                `json:"host,omitempty"`
+	Port                *int32                `json:"port,omitempty"`
+	Username            string                `json:"username,omitempty"`
+	Password            string                `json:"password,omitempty"`
+	Enabled             *bool                 `json:"enabled,omitempty"`
+	SSL                 *bool                 `json:"ssl,omitempty"`
+	SSLMode             string                `json:"ssl_mode,omitempty"`
+	SSLCert             string                `json:"ssl_cert,omitempty"`
+	SSLKey              string                `json:"ssl_key,omitempty"`
+	SSLRootCert         string                `json:"ssl_root_cert,omitempty"`
+	EnvironmentID       string                `json:"environment_id,omitempty"`
+	NodeID              string                `json:"node_id,omitempty"`
+	SSHTunnel           *InstanceSSHTunnel    `json:"ssh_tunnel,omitempty"`
+	IAMAuth             *InstanceIAMAuth      `json:"iam_auth,omitempty"`
+	SocketPath          *string               `json:"socket_path,omitempty"`
+	KerberosAuth        *InstanceKerberosAuth `json:"kerberos_auth,omitempty"`
 }
 
 type ModifyInstanceResponse struct {
@@ -144,3 +190,45 @@ type ConnectInstanceStringResponse struct {
 	Instance Instance `json:"instance"`
 	Status   Status   `json:"status"`
 }
+
+// InstanceDatabase is a database on an instance
+type InstanceDatabase struct {
+	DBName       string `json:"db_name"`
+	Attached     bool   `json:"attached"`
+	DatabaseName string `json:"database_name,omitempty"`
+}
+
+type ListInstanceDatabasesResponse struct {
+	Message   string             `json:"message"`
+	Success   bool               `json:"success"`
+	Databases []InstanceDatabase `json:"databases"`
+	Status    Status             `json:"status"`
+}
+
+// AttachInstanceDatabasesRequest attaches the databases on an instance matching a glob, or all of them
+type AttachInstanceDatabasesRequest struct {
+	Filter        string   `json:"filter,omitempty"`
+	Exclude       []string `json:"exclude,omitempty"`
+	NamePrefix    string   `json:"name_prefix,omitempty"`
+	NodeID        *string  `json:"node_id,omitempty"`
+	Enabled       *bool    `json:"enabled,omitempty"`
+	EnvironmentID *string  `json:"environment_id,omitempty"`
+}
+
+// AttachedInstanceDatabase is the outcome of attaching a database on an instance
+type AttachedInstanceDatabase struct {
+	DBName       string `json:"db_name"`
+	DatabaseName string `json:"database_name"`
+	Success      bool   `json:"success"`
+	Message      string `json:"message,omitempty"`
+}
+
+type AttachInstanceDatabasesResponse struct {
+	Message       string                     `json:"message"`
+	Success       bool                       `json:"success"`
+	Results       []AttachedInstanceDatabase `json:"results"`
+	AttachedCount int32                      `json:"attached_count"`
+	SkippedCount  int32                      `json:"skipped_count"`
+	FailedCount   int32                      `json:"failed_count"`
+	Status        Status                     `json:"status"`
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"net/http"
+
+	"github.com/redbco/redb-open/pkg/errorcatalog"
+	"github.com/redbco/redb-open/pkg/i18n"
+)
+
+// localeResponseWriter carries the locale negotiated for a request to the functions writing
+// its response, which are not given the request
+type localeResponseWriter struct {
+	http.ResponseWriter
+	locale       string
+	translations *i18n.Catalog
+}
+
+// Flush keeps streamed (SSE) responses working through the wrapper
+func (w *localeResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *localeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// loadTranslations loads the translation bundles of services.clientapi.locales_dir
+func (s *Server) loadTranslations() *i18n.Catalog {
+	translations := i18n.NewCatalog()
+	if s.engine.config == nil {
+		return translations
+	}
+
+	dir := s.engine.config.Get("services.clientapi.locales_dir")
+	if dir == "" {
+		return translations
+	}
+	if err := translations.LoadDir(dir); err != nil {
+		if s.engine.logger != nil {
+			s.engine.logger.Errorf("Failed to load translations from %s: %v", dir, err)
+		}
+		return translations
+	}
+	if s.engine.logger != nil {
+		s.engine.logger.Infof("Loaded translations from %s, locales: %v", dir, translations.Locales())
+	}
+	return translations
+}
+
+// localeMiddleware negotiates the locale of the response from the lang query parameter, else
+// the Accept-Language header
+func (s *Server) localeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested := r.URL.Query().Get("lang")
+		if requested == "" {
+			requested = r.Header.Get("Accept-Language")
+		}
+		locale := s.translations.Negotiate(requested)
+
+		w.Header().Set("Content-Language", locale)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(&localeResponseWriter{ResponseWriter: w, locale: locale, translations: s.translations}, r)
+	})
+}
+
+// translate returns the translation of a message in the locale of a response
+func translate(w http.ResponseWriter, message string) string {
+	lw, ok := w.(*localeResponseWriter)
+	if !ok {
+		return message
+	}
+	return lw.translations.Translate(lw.locale, message)
+}
+
+// translateAll returns the translations of messages in the locale of a response
+func translateAll(w http.ResponseWriter, messages []string) []string {
+	if len(messages) == 0 {
+		return messages
+	}
+	translated := make([]string, len(messages))
+	for i, message := range messages {
+		translated[i] = translate(w, message)
+	}
+	return translated
+}
+
+// localize translates the error and remediation of the response to the locale of w. The
+// message, which holds the raw error, is left as is.
+func (r *ErrorResponse) localize(w http.ResponseWriter) {
+	r.Error = translate(w, r.Error)
+	r.Remediation = translateAll(w, r.Remediation)
+}
+
+// localizeEntry translates an error catalog entry to the locale of w
+func localizeEntry(w http.ResponseWriter, entry errorcatalog.Entry) errorcatalog.Entry {
+	entry.Title = translate(w, entry.Title)
+	entry.Description = translate(w, entry.Description)
+	entry.Causes = translateAll(w, entry.Causes)
+	entry.Remediation = translateAll(w, entry.Remediation)
+	return entry
+}
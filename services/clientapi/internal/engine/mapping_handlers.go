@@ -3,7 +3,9 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -204,6 +206,9 @@ func (mh *MappingHandlers) ShowMapping(w http.ResponseWriter, r *http.Request) {
 		RelationshipNames:  grpcResp.Mapping.RelationshipNames,
 		MCPResourceNames:   grpcResp.Mapping.McpResourceNames,
 		MCPToolNames:       grpcResp.Mapping.McpToolNames,
+		ConflictKeys:       conflictKeysFromProto(grpcResp.Mapping.ConflictKeys),
+		RowFilter:          grpcResp.Mapping.RowFilter,
+		TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
 	}
 
 	// Extract parsed database and table information
@@ -398,6 +403,14 @@ func (mh *MappingHandlers) AddMapping(w http.ResponseWriter, r *http.Request) {
 	if req.PolicyID != "" {
 		grpcReq.PolicyId = &req.PolicyID
 	}
+	if req.RowFilter != "" {
+		grpcReq.RowFilter = &req.RowFilter
+	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
+	grpcReq.TablePins = tablePinsToProto(req.TablePins)
+	grpcReq.MatchOverrides = matchOverridesToProto(req.MatchOverrides)
 
 	grpcResp, err := mh.engine.mappingClient.AddMapping(ctx, grpcReq)
 	if err != nil {
@@ -416,13 +429,16 @@ func (mh *MappingHandlers) AddMapping(w http.ResponseWriter, r *http.Request) {
 		PolicyID:           grpcResp.Mapping.PolicyId,
 		OwnerID:            grpcResp.Mapping.OwnerId,
 		MappingRuleCount:   grpcResp.Mapping.MappingRuleCount,
+		RowFilter:          grpcResp.Mapping.RowFilter,
+		TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
 	}
 
 	response := AddMappingResponse{
-		Message: grpcResp.Message,
-		Success: grpcResp.Success,
-		Mapping: mapping,
-		Status:  convertStatus(grpcResp.Status),
+		Message:     grpcResp.Message,
+		Success:     grpcResp.Success,
+		Mapping:     mapping,
+		Status:      convertStatus(grpcResp.Status),
+		MatchReport: convertMatchReport(grpcResp.MatchReport),
 	}
 
 	if mh.engine.logger != nil {
@@ -494,6 +510,9 @@ func (mh *MappingHandlers) AddDatabaseMapping(w http.ResponseWriter, r *http.Req
 	if req.PolicyID != "" {
 		grpcReq.PolicyId = &req.PolicyID
 	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
 
 	grpcResp, err := mh.engine.mappingClient.AddDatabaseMapping(ctx, grpcReq)
 	if err != nil {
@@ -599,6 +618,9 @@ func (mh *MappingHandlers) AddTableMapping(w http.ResponseWriter, r *http.Reques
 	if req.PolicyID != "" {
 		grpcReq.PolicyId = &req.PolicyID
 	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
 
 	grpcResp, err := mh.engine.mappingClient.AddTableMapping(ctx, grpcReq)
 	if err != nil {
@@ -800,6 +822,26 @@ func (mh *MappingHandlers) ModifyMapping(w http.ResponseWriter, r *http.Request)
 	if req.PolicyID != "" {
 		grpcReq.PolicyId = &req.PolicyID
 	}
+	if req.ConflictKeys != nil {
+		if len(req.ConflictKeys) == 0 {
+			clearConflictKeys := true
+			grpcReq.ClearConflictKeys = &clearConflictKeys
+		}
+		for table, columns := range req.ConflictKeys {
+			grpcReq.ConflictKeys = append(grpcReq.ConflictKeys, &corev1.MappingConflictKey{
+				TargetTable: table,
+				Columns:     columns,
+			})
+		}
+	}
+	grpcReq.RowFilter = req.RowFilter
+	if req.TablePins != nil {
+		if len(req.TablePins) == 0 {
+			clearTablePins := true
+			grpcReq.ClearTablePins = &clearTablePins
+		}
+		grpcReq.TablePins = tablePinsToProto(req.TablePins)
+	}
 
 	grpcResp, err := mh.engine.mappingClient.ModifyMapping(ctx, grpcReq)
 	if err != nil {
@@ -818,6 +860,9 @@ func (mh *MappingHandlers) ModifyMapping(w http.ResponseWriter, r *http.Request)
 		PolicyID:           grpcResp.Mapping.PolicyId,
 		OwnerID:            grpcResp.Mapping.OwnerId,
 		MappingRuleCount:   grpcResp.Mapping.MappingRuleCount,
+		ConflictKeys:       conflictKeysFromProto(grpcResp.Mapping.ConflictKeys),
+		RowFilter:          grpcResp.Mapping.RowFilter,
+		TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
 	}
 
 	response := ModifyMappingResponse{
@@ -959,6 +1004,12 @@ func (mh *MappingHandlers) ListMappingRules(w http.ResponseWriter, r *http.Reque
 			MappingRuleTransformationID:      rule.MappingRuleTransformationId,
 			MappingRuleTransformationName:    rule.MappingRuleTransformationName,
 			MappingRuleTransformationOptions: rule.MappingRuleTransformationOptions,
+			MappingRuleTransformationChain:   rule.MappingRuleTransformationChain,
+			MappingRuleRowFilter:             rule.MappingRuleRowFilter,
+			Broken:                           rule.Broken,
+			BrokenReason:                     rule.BrokenReason,
+			MissingObjects:                   rule.MissingObjects,
+			RecommendedTransformation:        rule.RecommendedTransformation,
 			OwnerID:                          rule.OwnerId,
 			MappingCount:                     rule.MappingCount,
 		}
@@ -1033,6 +1084,15 @@ func (mh *MappingHandlers) ShowMappingRule(w http.ResponseWriter, r *http.Reques
 		MappingRuleTransformationID:      grpcResp.MappingRule.MappingRuleTransformationId,
 		MappingRuleTransformationName:    grpcResp.MappingRule.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: grpcResp.MappingRule.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   grpcResp.MappingRule.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             grpcResp.MappingRule.MappingRuleRowFilter,
+		MappingRuleCardinality:           grpcResp.MappingRule.MappingRuleCardinality,
+		SourceItemURIs:                   grpcResp.MappingRule.SourceItemUris,
+		TargetItemURIs:                   grpcResp.MappingRule.TargetItemUris,
+		Broken:                           grpcResp.MappingRule.Broken,
+		BrokenReason:                     grpcResp.MappingRule.BrokenReason,
+		MissingObjects:                   grpcResp.MappingRule.MissingObjects,
+		RecommendedTransformation:        grpcResp.MappingRule.RecommendedTransformation,
 		OwnerID:                          grpcResp.MappingRule.OwnerId,
 		MappingCount:                     grpcResp.MappingRule.MappingCount,
 	}
@@ -1097,8 +1157,8 @@ func (mh *MappingHandlers) AddMappingRule(w http.ResponseWriter, r *http.Request
 	}
 
 	// Validate required fields
-	if req.MappingRuleName == "" || req.MappingRuleDescription == "" || req.MappingRuleSource == "" || req.MappingRuleTarget == "" || req.MappingRuleTransformationName == "" {
-		mh.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "mapping_rule_name, mapping_rule_description, mapping_rule_source, mapping_rule_target, and mapping_rule_transformation_name are required")
+	if req.MappingRuleName == "" || req.MappingRuleDescription == "" || (req.MappingRuleSource == "" && len(req.SourceItemURIs) == 0) || (req.MappingRuleTarget == "" && len(req.TargetItemURIs) == 0) || (req.MappingRuleTransformationName == "" && len(req.MappingRuleTransformationChain) == 0) {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "mapping_rule_name, mapping_rule_description, mapping_rule_source or source_item_uris, mapping_rule_target or target_item_uris, and mapping_rule_transformation_name or mapping_rule_transformation_chain are required")
 		return
 	}
 
@@ -1122,6 +1182,11 @@ func (mh *MappingHandlers) AddMappingRule(w http.ResponseWriter, r *http.Request
 		MappingRuleTarget:                req.MappingRuleTarget,
 		MappingRuleTransformationName:    req.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: req.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   req.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             req.MappingRuleRowFilter,
+		MappingRuleCardinality:           req.MappingRuleCardinality,
+		SourceItemUris:                   req.SourceItemURIs,
+		TargetItemUris:                   req.TargetItemURIs,
 	}
 
 	grpcResp, err := mh.engine.mappingClient.AddMappingRule(ctx, grpcReq)
@@ -1143,6 +1208,15 @@ func (mh *MappingHandlers) AddMappingRule(w http.ResponseWriter, r *http.Request
 		MappingRuleTransformationID:      grpcResp.MappingRule.MappingRuleTransformationId,
 		MappingRuleTransformationName:    grpcResp.MappingRule.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: grpcResp.MappingRule.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   grpcResp.MappingRule.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             grpcResp.MappingRule.MappingRuleRowFilter,
+		MappingRuleCardinality:           grpcResp.MappingRule.MappingRuleCardinality,
+		SourceItemURIs:                   grpcResp.MappingRule.SourceItemUris,
+		TargetItemURIs:                   grpcResp.MappingRule.TargetItemUris,
+		Broken:                           grpcResp.MappingRule.Broken,
+		BrokenReason:                     grpcResp.MappingRule.BrokenReason,
+		MissingObjects:                   grpcResp.MappingRule.MissingObjects,
+		RecommendedTransformation:        grpcResp.MappingRule.RecommendedTransformation,
 		OwnerID:                          grpcResp.MappingRule.OwnerId,
 		MappingCount:                     grpcResp.MappingRule.MappingCount,
 	}
@@ -1228,6 +1302,12 @@ func (mh *MappingHandlers) ModifyMappingRule(w http.ResponseWriter, r *http.Requ
 	if req.MappingRuleTransformationOptions != "" {
 		grpcReq.MappingRuleTransformationOptions = &req.MappingRuleTransformationOptions
 	}
+	if req.MappingRuleTransformationChain != nil {
+		grpcReq.MappingRuleTransformationChain = &corev1.MappingRuleTransformationChain{
+			TransformationNames: req.MappingRuleTransformationChain,
+		}
+	}
+	grpcReq.MappingRuleRowFilter = req.MappingRuleRowFilter
 
 	grpcResp, err := mh.engine.mappingClient.ModifyMappingRule(ctx, grpcReq)
 	if err != nil {
@@ -1248,6 +1328,15 @@ func (mh *MappingHandlers) ModifyMappingRule(w http.ResponseWriter, r *http.Requ
 		MappingRuleTransformationID:      grpcResp.MappingRule.MappingRuleTransformationId,
 		MappingRuleTransformationName:    grpcResp.MappingRule.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: grpcResp.MappingRule.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   grpcResp.MappingRule.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             grpcResp.MappingRule.MappingRuleRowFilter,
+		MappingRuleCardinality:           grpcResp.MappingRule.MappingRuleCardinality,
+		SourceItemURIs:                   grpcResp.MappingRule.SourceItemUris,
+		TargetItemURIs:                   grpcResp.MappingRule.TargetItemUris,
+		Broken:                           grpcResp.MappingRule.Broken,
+		BrokenReason:                     grpcResp.MappingRule.BrokenReason,
+		MissingObjects:                   grpcResp.MappingRule.MissingObjects,
+		RecommendedTransformation:        grpcResp.MappingRule.RecommendedTransformation,
 		OwnerID:                          grpcResp.MappingRule.OwnerId,
 		MappingCount:                     grpcResp.MappingRule.MappingCount,
 	}
@@ -1324,6 +1413,146 @@ func (mh *MappingHandlers) DeleteMappingRule(w http.ResponseWriter, r *http.Requ
 	mh.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// AnalyzeMappingRuleImpact handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mapping-rules/{mapping_rule_name}/impact
+func (mh *MappingHandlers) AnalyzeMappingRuleImpact(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	// Extract path parameters
+	vars := mux.Vars(r)
+	tenantURL := vars["tenant_url"]
+	workspaceName := vars["workspace_name"]
+	mappingRuleName := vars["mapping_rule_name"]
+
+	if tenantURL == "" || workspaceName == "" || mappingRuleName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "tenant_url, workspace_name, and mapping_rule_name are required", "")
+		return
+	}
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Parse request body
+	var req AnalyzeMappingRuleImpactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if mh.engine.logger != nil {
+			mh.engine.logger.Errorf("Failed to parse mapping rule impact request body: %v", err)
+		}
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Mapping rule impact request for rule: %s, workspace: %s, delete: %v, tenant: %s", mappingRuleName, workspaceName, req.Delete, profile.TenantId)
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcReq := &corev1.AnalyzeMappingRuleImpactRequest{
+		TenantId:        profile.TenantId,
+		WorkspaceName:   workspaceName,
+		MappingRuleName: mappingRuleName,
+		Delete:          req.Delete,
+	}
+	if req.MappingRuleNameNew != "" {
+		grpcReq.MappingRuleNameNew = &req.MappingRuleNameNew
+	}
+	// Sources and targets are resource URIs, or database.table.column identifiers as taken by
+	// the rules of a mapping
+	for _, side := range []struct {
+		name       string
+		identifier string
+		uri        **string
+	}{
+		{"source", req.MappingRuleSource, &grpcReq.MappingRuleSource},
+		{"target", req.MappingRuleTarget, &grpcReq.MappingRuleTarget},
+	} {
+		if side.identifier == "" {
+			continue
+		}
+		uri := side.identifier
+		if !strings.Contains(uri, "://") {
+			converted, err := mh.convertToResourceURI(ctx, profile.TenantId, workspaceName, uri)
+			if err != nil {
+				mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid "+side.name+" format", err.Error())
+				return
+			}
+			uri = converted
+		}
+		*side.uri = &uri
+	}
+	if req.MappingRuleTransformationName != "" {
+		grpcReq.MappingRuleTransformationName = &req.MappingRuleTransformationName
+	}
+	if req.MappingRuleTransformationOptions != "" {
+		grpcReq.MappingRuleTransformationOptions = &req.MappingRuleTransformationOptions
+	}
+	if req.MappingRuleMetadata != "" {
+		grpcReq.MappingRuleMetadata = &req.MappingRuleMetadata
+	}
+
+	grpcResp, err := mh.engine.mappingClient.AnalyzeMappingRuleImpact(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to analyze mapping rule impact")
+		return
+	}
+
+	impact := MappingRuleImpact{
+		MappingRuleName:   grpcResp.Impact.GetMappingRuleName(),
+		Delete:            grpcResp.Impact.GetDelete(),
+		Destructive:       grpcResp.Impact.GetDestructive(),
+		ChangedFields:     grpcResp.Impact.GetChangedFields(),
+		AffectedMappings:  make([]MappingRuleImpactMapping, 0, len(grpcResp.Impact.GetAffectedMappings())),
+		Runs:              make([]MappingRuleImpactRun, 0, len(grpcResp.Impact.GetRuns())),
+		DownstreamTargets: make([]MappingRuleImpactTarget, 0, len(grpcResp.Impact.GetDownstreamTargets())),
+		FailingPolicies:   make([]MappingRuleImpactPolicy, 0, len(grpcResp.Impact.GetFailingPolicies())),
+	}
+	for _, m := range grpcResp.Impact.GetAffectedMappings() {
+		impact.AffectedMappings = append(impact.AffectedMappings, MappingRuleImpactMapping{
+			MappingName:        m.MappingName,
+			MappingDescription: m.MappingDescription,
+			MappingRuleCount:   m.MappingRuleCount,
+		})
+	}
+	for _, run := range grpcResp.Impact.GetRuns() {
+		impact.Runs = append(impact.Runs, MappingRuleImpactRun{
+			RelationshipName: run.RelationshipName,
+			MappingName:      run.MappingName,
+			Status:           run.Status,
+			Active:           run.Active,
+		})
+	}
+	for _, target := range grpcResp.Impact.GetDownstreamTargets() {
+		impact.DownstreamTargets = append(impact.DownstreamTargets, MappingRuleImpactTarget{
+			Kind:  target.Kind,
+			Name:  target.Name,
+			Via:   target.Via,
+			Depth: target.Depth,
+		})
+	}
+	for _, p := range grpcResp.Impact.GetFailingPolicies() {
+		impact.FailingPolicies = append(impact.FailingPolicies, MappingRuleImpactPolicy{
+			PolicyID:   p.PolicyId,
+			PolicyName: p.PolicyName,
+			Reason:     p.Reason,
+		})
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, AnalyzeMappingRuleImpactResponse{
+		Message:  grpcResp.Message,
+		Success:  grpcResp.Success,
+		Status:   convertStatus(grpcResp.Status),
+		Impact:   impact,
+		Warnings: grpcResp.Warnings,
+	})
+}
+
 // AttachMappingRule handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/attach-rule
 func (mh *MappingHandlers) AttachMappingRule(w http.ResponseWriter, r *http.Request) {
 	mh.engine.TrackOperation()
@@ -1477,6 +1706,39 @@ func (mh *MappingHandlers) DetachMappingRule(w http.ResponseWriter, r *http.Requ
 
 // Helper methods
 
+// conflictKeysFromProto converts mapping conflict keys to a map of target table to columns
+func conflictKeysFromProto(keys []*corev1.MappingConflictKey) map[string][]string {
+	if len(keys) == 0 {
+		return nil
+	}
+	conflictKeys := make(map[string][]string, len(keys))
+	for _, key := range keys {
+		conflictKeys[key.TargetTable] = key.Columns
+	}
+	return conflictKeys
+}
+
+// tablePinsFromProto converts the table pins of a mapping
+func tablePinsFromProto(pins []*corev1.MappingTablePin) []TablePin {
+	if len(pins) == 0 {
+		return nil
+	}
+	tablePins := make([]TablePin, len(pins))
+	for i, pin := range pins {
+		tablePins[i] = TablePin{SourceTable: pin.SourceTable, TargetTable: pin.TargetTable, Never: pin.Never}
+	}
+	return tablePins
+}
+
+// tablePinsToProto converts the table pins of a request
+func tablePinsToProto(pins []TablePin) []*corev1.MappingTablePin {
+	var protoPins []*corev1.MappingTablePin
+	for _, pin := range pins {
+		protoPins = append(protoPins, &corev1.MappingTablePin{SourceTable: pin.SourceTable, TargetTable: pin.TargetTable, Never: pin.Never})
+	}
+	return protoPins
+}
+
 // parseJSONString safely parses a JSON string into an interface{} object
 // If the string is empty or invalid JSON, it returns nil
 func (mh *MappingHandlers) parseJSONString(jsonStr string) interface{} {
@@ -1535,6 +1797,8 @@ func (mh *MappingHandlers) writeErrorResponse(w http.ResponseWriter, statusCode
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	mh.writeJSONResponse(w, statusCode, response)
 }
 
@@ -1813,6 +2077,13 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 		BatchSize       int32 `json:"batch_size"`
 		ParallelWorkers int32 `json:"parallel_workers"`
 		DryRun          bool  `json:"dry_run"`
+		Sandbox         bool  `json:"sandbox"`
+		SampleSize      int32 `json:"sample_size"`
+		ValidateVectors *bool `json:"validate_vectors"`
+		VectorSamples   int32 `json:"vector_samples"`
+		VerifyGraph     *bool `json:"verify_graph"`
+		EdgeSamples     int32 `json:"graph_edge_samples"`
+		Adaptive        *bool `json:"adaptive_parallelism"`
 		Progress        bool  `json:"progress"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -1833,8 +2104,8 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 
 	// Log request
 	if mh.engine.logger != nil {
-		mh.engine.logger.Infof("Copy mapping data request for mapping: %s, workspace: %s, tenant: %s, batch_size: %d, parallel_workers: %d, dry_run: %t",
-			mappingName, workspaceName, profile.TenantId, req.BatchSize, req.ParallelWorkers, req.DryRun)
+		mh.engine.logger.Infof("Copy mapping data request for mapping: %s, workspace: %s, tenant: %s, batch_size: %d, parallel_workers: %d, dry_run: %t, sandbox: %t",
+			mappingName, workspaceName, profile.TenantId, req.BatchSize, req.ParallelWorkers, req.DryRun, req.Sandbox)
 	}
 
 	// Create context with timeout (longer timeout for data copying operations)
@@ -1849,7 +2120,20 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 		BatchSize:       &req.BatchSize,
 		ParallelWorkers: &req.ParallelWorkers,
 		DryRun:          &req.DryRun,
+		Sandbox:         &req.Sandbox,
 	}
+	if req.SampleSize > 0 {
+		grpcReq.SampleSize = &req.SampleSize
+	}
+	grpcReq.ValidateVectors = req.ValidateVectors
+	if req.VectorSamples > 0 {
+		grpcReq.VectorSamples = &req.VectorSamples
+	}
+	grpcReq.VerifyGraph = req.VerifyGraph
+	if req.EdgeSamples > 0 {
+		grpcReq.GraphEdgeSamples = &req.EdgeSamples
+	}
+	grpcReq.AdaptiveParallelism = req.Adaptive
 
 	// For now, we'll handle this as a simple request-response
 	// TODO: Implement streaming response for real-time progress updates
@@ -1862,6 +2146,7 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 	// Collect all streaming responses
 	var lastResponse *corev1.CopyMappingDataResponse
 	var allErrors []string
+	samples := make(map[string]json.RawMessage)
 
 	for {
 		resp, err := stream.Recv()
@@ -1875,6 +2160,9 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 
 		lastResponse = resp
 		allErrors = append(allErrors, resp.Errors...)
+		if len(resp.SampleData) > 0 {
+			samples[resp.CurrentTable] = resp.SampleData
+		}
 
 		// Log progress if available
 		if mh.engine.logger != nil && resp.Status == "progress" {
@@ -1890,23 +2178,50 @@ func (mh *MappingHandlers) CopyMappingData(w http.ResponseWriter, r *http.Reques
 
 	// Create response
 	response := struct {
-		Message       string   `json:"message"`
-		Success       bool     `json:"success"`
-		Status        string   `json:"status"`
-		RowsProcessed int64    `json:"rows_processed"`
-		TotalRows     int64    `json:"total_rows"`
-		CurrentTable  string   `json:"current_table"`
-		Errors        []string `json:"errors"`
-		OperationID   string   `json:"operation_id"`
+		Message        string                     `json:"message"`
+		Success        bool                       `json:"success"`
+		Status         string                     `json:"status"`
+		RowsProcessed  int64                      `json:"rows_processed"`
+		TotalRows      int64                      `json:"total_rows"`
+		CurrentTable   string                     `json:"current_table"`
+		Errors         []string                   `json:"errors"`
+		OperationID    string                     `json:"operation_id"`
+		Sandbox        string                     `json:"sandbox,omitempty"`
+		SandboxSamples map[string]json.RawMessage `json:"sandbox_samples,omitempty"`
+		RowsRejected   int64                      `json:"rows_rejected"`
+		DeadLetterIDs  []string                   `json:"dead_letter_artifact_ids,omitempty"`
+		Validations    []VectorValidation         `json:"vector_validations,omitempty"`
+		Verifications  []GraphVerification        `json:"graph_verifications,omitempty"`
+		StageTimings   *StageTimings              `json:"stage_timings,omitempty"`
+		TableTimings   []StageTimings             `json:"table_pair_timings,omitempty"`
+		Parallelism    int32                      `json:"parallelism"`
+		Decisions      []ParallelismDecision      `json:"parallelism_decisions,omitempty"`
 	}{
-		Message:       lastResponse.Message,
-		Success:       lastResponse.Status == "completed",
-		Status:        lastResponse.Status,
-		RowsProcessed: lastResponse.RowsProcessed,
-		TotalRows:     lastResponse.TotalRows,
-		CurrentTable:  lastResponse.CurrentTable,
-		Errors:        allErrors,
-		OperationID:   lastResponse.OperationId,
+		Message: lastResponse.Message,
+		// Rows the target rejected do not fail the copy, they are kept as dead-letter artifacts
+		Success:        lastResponse.Status == "completed" || lastResponse.Status == "completed_with_rejected_rows",
+		Status:         lastResponse.Status,
+		RowsProcessed:  lastResponse.RowsProcessed,
+		TotalRows:      lastResponse.TotalRows,
+		CurrentTable:   lastResponse.CurrentTable,
+		Errors:         allErrors,
+		OperationID:    lastResponse.OperationId,
+		Sandbox:        lastResponse.Sandbox,
+		SandboxSamples: samples,
+		RowsRejected:   lastResponse.RowsRejected,
+		DeadLetterIDs:  lastResponse.DeadLetterArtifactIds,
+		Validations:    convertVectorValidations(lastResponse.VectorValidations),
+		Verifications:  convertGraphVerifications(lastResponse.GraphVerifications),
+		TableTimings:   make([]StageTimings, 0, len(lastResponse.TablePairTimings)),
+		Parallelism:    lastResponse.Parallelism,
+		Decisions:      convertParallelismDecisions(lastResponse.ParallelismDecisions),
+	}
+	if lastResponse.StageTimings != nil {
+		timings := convertStageTimings(lastResponse.StageTimings)
+		response.StageTimings = &timings
+	}
+	for _, timings := range lastResponse.TablePairTimings {
+		response.TableTimings = append(response.TableTimings, convertStageTimings(timings))
 	}
 
 	statusCode := http.StatusOK
@@ -1956,8 +2271,15 @@ func (mh *MappingHandlers) AddRuleToMapping(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Validate required fields
-	if req.RuleName == "" || req.Source == "" || req.Target == "" || req.Transformation == "" {
-		mh.writeErrorResponse(w, http.StatusBadRequest, "rule_name, source, target, and transformation are required", "")
+	sources, targets := req.Sources, req.Targets
+	if len(sources) == 0 && req.Source != "" {
+		sources = []string{req.Source}
+	}
+	if len(targets) == 0 && req.Target != "" {
+		targets = []string{req.Target}
+	}
+	if req.RuleName == "" || len(sources) == 0 || len(targets) == 0 || (req.Transformation == "" && len(req.TransformationChain) == 0) {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "rule_name, source or sources, target or targets, and transformation or transformation_chain are required", "")
 		return
 	}
 
@@ -1965,35 +2287,46 @@ func (mh *MappingHandlers) AddRuleToMapping(w http.ResponseWriter, r *http.Reque
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
-	// Convert source and target from database.table.column format to redb:// URI format
-	sourceURI, err := mh.convertToResourceURI(ctx, profile.TenantId, workspaceName, req.Source)
-	if err != nil {
-		if mh.engine.logger != nil {
-			mh.engine.logger.Errorf("Failed to convert source identifier to URI: %v", err)
+	// Convert sources and targets from database.table.column format to redb:// URI format
+	sourceURIs := make([]string, 0, len(sources))
+	for _, source := range sources {
+		sourceURI, err := mh.convertToResourceURI(ctx, profile.TenantId, workspaceName, source)
+		if err != nil {
+			if mh.engine.logger != nil {
+				mh.engine.logger.Errorf("Failed to convert source identifier to URI: %v", err)
+			}
+			mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid source format", err.Error())
+			return
 		}
-		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid source format", err.Error())
-		return
+		sourceURIs = append(sourceURIs, sourceURI)
 	}
 
-	targetURI, err := mh.convertToResourceURI(ctx, profile.TenantId, workspaceName, req.Target)
-	if err != nil {
-		if mh.engine.logger != nil {
-			mh.engine.logger.Errorf("Failed to convert target identifier to URI: %v", err)
+	targetURIs := make([]string, 0, len(targets))
+	for _, target := range targets {
+		targetURI, err := mh.convertToResourceURI(ctx, profile.TenantId, workspaceName, target)
+		if err != nil {
+			if mh.engine.logger != nil {
+				mh.engine.logger.Errorf("Failed to convert target identifier to URI: %v", err)
+			}
+			mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid target format", err.Error())
+			return
 		}
-		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid target format", err.Error())
-		return
+		targetURIs = append(targetURIs, targetURI)
 	}
 
-	// Step 1: Create the mapping rule
+	// Step 1: Create the mapping rule. Its cardinality follows from the numbers of sources and
+	// targets.
 	addRuleReq := &corev1.AddMappingRuleRequest{
 		TenantId:                         profile.TenantId,
 		WorkspaceName:                    workspaceName,
 		MappingRuleName:                  req.RuleName,
 		MappingRuleDescription:           fmt.Sprintf("Rule for %s mapping", mappingName),
-		MappingRuleSource:                sourceURI,
-		MappingRuleTarget:                targetURI,
+		SourceItemUris:                   sourceURIs,
+		TargetItemUris:                   targetURIs,
 		MappingRuleTransformationName:    req.Transformation,
-		MappingRuleTransformationOptions: "",
+		MappingRuleTransformationOptions: req.TransformationOptions,
+		MappingRuleTransformationChain:   req.TransformationChain,
+		MappingRuleRowFilter:             req.RowFilter,
 		OwnerId:                          profile.UserId,
 	}
 
@@ -2072,7 +2405,7 @@ func (mh *MappingHandlers) ModifyRuleInMapping(w http.ResponseWriter, r *http.Re
 	}
 
 	// At least one field must be provided
-	if req.Source == nil && req.Target == nil && req.Transformation == nil && req.Order == nil {
+	if req.Source == nil && req.Target == nil && req.Transformation == nil && req.TransformationChain == nil && req.RowFilter == nil && req.Order == nil {
 		mh.writeErrorResponse(w, http.StatusBadRequest, "At least one field must be provided for modification", "")
 		return
 	}
@@ -2116,6 +2449,12 @@ func (mh *MappingHandlers) ModifyRuleInMapping(w http.ResponseWriter, r *http.Re
 		MappingRuleTarget:             targetURI,
 		MappingRuleTransformationName: req.Transformation,
 	}
+	if req.TransformationChain != nil {
+		modifyReq.MappingRuleTransformationChain = &corev1.MappingRuleTransformationChain{
+			TransformationNames: req.TransformationChain,
+		}
+	}
+	modifyReq.MappingRuleRowFilter = req.RowFilter
 
 	ruleResp, err := mh.engine.mappingClient.ModifyMappingRule(ctx, modifyReq)
 	if err != nil {
@@ -2283,6 +2622,15 @@ func (mh *MappingHandlers) protoToMappingRule(proto *corev1.MappingRule) Mapping
 		MappingRuleTransformationID:      proto.MappingRuleTransformationId,
 		MappingRuleTransformationName:    proto.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: proto.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   proto.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             proto.MappingRuleRowFilter,
+		MappingRuleCardinality:           proto.MappingRuleCardinality,
+		SourceItemURIs:                   proto.SourceItemUris,
+		TargetItemURIs:                   proto.TargetItemUris,
+		Broken:                           proto.Broken,
+		BrokenReason:                     proto.BrokenReason,
+		MissingObjects:                   proto.MissingObjects,
+		RecommendedTransformation:        proto.RecommendedTransformation,
 		OwnerID:                          proto.OwnerId,
 		MappingCount:                     proto.MappingCount,
 	}
@@ -2336,6 +2684,9 @@ func (mh *MappingHandlers) protoToMappingRuleInMapping(proto *corev1.MappingRule
 		MappingRuleTransformationID:      proto.MappingRuleTransformationId,
 		MappingRuleTransformationName:    proto.MappingRuleTransformationName,
 		MappingRuleTransformationOptions: proto.MappingRuleTransformationOptions,
+		MappingRuleTransformationChain:   proto.MappingRuleTransformationChain,
+		MappingRuleRowFilter:             proto.MappingRuleRowFilter,
+		RecommendedTransformation:        proto.RecommendedTransformation,
 		SourceItems:                      sourceItems,
 		TargetItems:                      targetItems,
 	}
@@ -2507,10 +2858,22 @@ func (mh *MappingHandlers) ValidateMapping(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Prepare response
-	response := map[string]interface{}{
-		"is_valid": grpcResp.IsValid,
-		"errors":   grpcResp.ValidationErrors,
-		"warnings": grpcResp.ValidationWarnings,
+	response := ValidateMappingResponse{
+		IsValid:         grpcResp.IsValid,
+		Errors:          grpcResp.ValidationErrors,
+		Warnings:        grpcResp.ValidationWarnings,
+		RuleValidations: make([]MappingRuleValidation, 0, len(grpcResp.RuleValidations)),
+		MappingIssues:   convertValidationIssues(grpcResp.MappingIssues),
+		ValidatedAt:     grpcResp.ValidatedAt,
+	}
+	for _, validation := range grpcResp.RuleValidations {
+		response.RuleValidations = append(response.RuleValidations, MappingRuleValidation{
+			MappingRuleName:    validation.MappingRuleName,
+			Cardinality:        validation.Cardinality,
+			TransformationName: validation.TransformationName,
+			Valid:              validation.Valid,
+			Issues:             convertValidationIssues(validation.Issues),
+		})
 	}
 
 	// Log response
@@ -2524,3 +2887,680 @@ func (mh *MappingHandlers) ValidateMapping(w http.ResponseWriter, r *http.Reques
 		"status":  "success",
 	})
 }
+
+// convertValidationIssues converts the issues of a mapping validation from their protobuf messages
+func convertValidationIssues(issues []*corev1.MappingValidationIssue) []MappingValidationIssue {
+	converted := make([]MappingValidationIssue, 0, len(issues))
+	for _, issue := range issues {
+		converted = append(converted, MappingValidationIssue{
+			Check:     issue.Check,
+			Severity:  issue.Severity,
+			Message:   issue.Message,
+			ObjectURI: issue.ObjectUri,
+		})
+	}
+	return converted
+}
+
+// PlanRun handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/plan
+// and returns the stages a copy of the mapping would run, without running it
+func (mh *MappingHandlers) PlanRun(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	if workspaceName == "" || mappingName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// All options are optional, so an empty body is accepted
+	var req PlanRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if mh.engine.logger != nil {
+				mh.engine.logger.Errorf("Failed to parse plan run request body: %v", err)
+			}
+			mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+			return
+		}
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Plan run request for mapping: %s, workspace: %s, sandbox: %t, user: %s", mappingName, workspaceName, req.Sandbox, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcReq := &corev1.PlanRunRequest{
+		TenantId:        profile.TenantId,
+		WorkspaceName:   workspaceName,
+		MappingName:     mappingName,
+		Sandbox:         &req.Sandbox,
+		ValidateVectors: req.ValidateVectors,
+		VerifyGraph:     req.VerifyGraph,
+	}
+	if req.BatchSize > 0 {
+		grpcReq.BatchSize = &req.BatchSize
+	}
+	if req.ParallelWorkers > 0 {
+		grpcReq.ParallelWorkers = &req.ParallelWorkers
+	}
+
+	grpcResp, err := mh.engine.mappingClient.PlanRun(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to plan run")
+		return
+	}
+
+	plan := grpcResp.Plan
+	response := PlanRunResponse{
+		Message:  grpcResp.Message,
+		Success:  grpcResp.Success,
+		Status:   convertStatus(grpcResp.Status),
+		Warnings: grpcResp.Warnings,
+		Plan: RunPlan{
+			MappingName:   plan.GetMappingName(),
+			NodeID:        plan.GetNodeId(),
+			NodeName:      plan.GetNodeName(),
+			BatchSize:     plan.GetBatchSize(),
+			Parallelism:   plan.GetParallelism(),
+			EstimatedRows: plan.GetEstimatedRows(),
+			Adapters:      plan.GetAdapters(),
+			RowFilter:     plan.GetRowFilter(),
+			Stages:        make([]RunPlanStage, 0, len(plan.GetStages())),
+			SkippedRules:  plan.GetSkippedRules(),
+		},
+	}
+	for _, stage := range plan.GetStages() {
+		hops := make([]MeshHop, 0, len(stage.MeshHops))
+		for _, hop := range stage.MeshHops {
+			hops = append(hops, MeshHop{NodeID: hop.NodeId, NodeName: hop.NodeName})
+		}
+		response.Plan.Stages = append(response.Plan.Stages, RunPlanStage{
+			StageID:          stage.StageId,
+			Kind:             stage.Kind,
+			Description:      stage.Description,
+			DependsOn:        stage.DependsOn,
+			SourceTable:      stage.SourceTable,
+			TargetTable:      stage.TargetTable,
+			SourceDatabase:   stage.SourceDatabase,
+			TargetDatabase:   stage.TargetDatabase,
+			SourceAdapter:    stage.SourceAdapter,
+			TargetAdapter:    stage.TargetAdapter,
+			NodeID:           stage.NodeId,
+			MeshHops:         hops,
+			EstimatedRows:    stage.EstimatedRows,
+			EstimatedBatches: stage.EstimatedBatches,
+			BatchSize:        stage.BatchSize,
+			Parallelism:      stage.Parallelism,
+			Rules:            stage.Rules,
+			Transformations:  stage.Transformations,
+		})
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// PreviewMapping handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/preview
+// and returns sample source rows with the target rows the mapping would write for them
+func (mh *MappingHandlers) PreviewMapping(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	if workspaceName == "" || mappingName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// All options are optional, so an empty body is accepted
+	var req PreviewMappingRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if mh.engine.logger != nil {
+				mh.engine.logger.Errorf("Failed to parse preview mapping request body: %v", err)
+			}
+			mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+			return
+		}
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Preview mapping request for mapping: %s, workspace: %s, sample size: %d, user: %s", mappingName, workspaceName, req.SampleSize, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcReq := &corev1.PreviewMappingRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		MappingName:   mappingName,
+	}
+	if req.SampleSize > 0 {
+		grpcReq.SampleSize = &req.SampleSize
+	}
+
+	grpcResp, err := mh.engine.mappingClient.PreviewMapping(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to preview mapping")
+		return
+	}
+
+	response := PreviewMappingResponse{
+		Message:  grpcResp.Message,
+		Success:  grpcResp.Success,
+		Status:   convertStatus(grpcResp.Status),
+		Tables:   make([]PreviewTableSample, 0, len(grpcResp.Tables)),
+		Warnings: grpcResp.Warnings,
+	}
+	for _, table := range grpcResp.Tables {
+		sample := PreviewTableSample{
+			SourceTable: table.SourceTable,
+			TargetTable: table.TargetTable,
+			Rules:       table.Rules,
+			Rows:        make([]PreviewRow, 0, len(table.Rows)),
+		}
+		for _, row := range table.Rows {
+			previewRow := PreviewRow{
+				SourceRow:   json.RawMessage(row.SourceRow),
+				FilteredOut: row.FilteredOut,
+				Errors:      row.Errors,
+			}
+			if len(row.TargetRow) > 0 {
+				previewRow.TargetRow = json.RawMessage(row.TargetRow)
+			}
+			sample.Rows = append(sample.Rows, previewRow)
+		}
+		response.Tables = append(response.Tables, sample)
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetMappingIndexRecommendations handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/index-recommendations
+func (mh *MappingHandlers) GetMappingIndexRecommendations(w http.ResponseWriter, r *http.Request) {
+	mh.recommendMappingIndexes(w, r, nil)
+}
+
+// CreateMappingIndexes handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/index-recommendations
+// and creates the recommended indexes named in the request
+func (mh *MappingHandlers) CreateMappingIndexes(w http.ResponseWriter, r *http.Request) {
+	var req CreateMappingIndexesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if mh.engine.logger != nil {
+			mh.engine.logger.Errorf("Failed to parse create mapping indexes request body: %v", err)
+		}
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+	if len(req.IndexNames) == 0 {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "index_names is required", "")
+		return
+	}
+	mh.recommendMappingIndexes(w, r, req.IndexNames)
+}
+
+func (mh *MappingHandlers) recommendMappingIndexes(w http.ResponseWriter, r *http.Request, createIndexNames []string) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	if workspaceName == "" || mappingName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Mapping index recommendations request for mapping: %s, workspace: %s, create: %v, user: %s", mappingName, workspaceName, createIndexNames, profile.UserId)
+	}
+
+	// Creating indexes on large tables can take a while
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.RecommendMappingIndexes(ctx, &corev1.RecommendMappingIndexesRequest{
+		TenantId:         profile.TenantId,
+		WorkspaceName:    workspaceName,
+		MappingName:      mappingName,
+		CreateIndexNames: createIndexNames,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to recommend mapping indexes")
+		return
+	}
+
+	recommendations := make([]MappingIndexRecommendation, 0, len(grpcResp.Recommendations))
+	for _, rec := range grpcResp.Recommendations {
+		recommendations = append(recommendations, MappingIndexRecommendation{
+			IndexName:    rec.IndexName,
+			Side:         rec.Side,
+			DatabaseID:   rec.DatabaseId,
+			DatabaseName: rec.DatabaseName,
+			DatabaseType: rec.DatabaseType,
+			TableName:    rec.TableName,
+			Columns:      rec.Columns,
+			Unique:       rec.Unique,
+			Reason:       rec.Reason,
+			Statement:    rec.Statement,
+			Created:      rec.Created,
+			Error:        rec.Error,
+		})
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, MappingIndexRecommendationsResponse{
+		Message:         grpcResp.Message,
+		Success:         grpcResp.Success,
+		Status:          convertStatus(grpcResp.Status),
+		MappingName:     grpcResp.MappingName,
+		Recommendations: recommendations,
+		Warnings:        grpcResp.Warnings,
+	})
+}
+
+// RematchMapping handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/rematch
+// and re-runs the matcher of a database mapping with its table pins, replacing the rules it generated
+func (mh *MappingHandlers) RematchMapping(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	if workspaceName == "" || mappingName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// The body is optional
+	var req RematchMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		if mh.engine.logger != nil {
+			mh.engine.logger.Errorf("Failed to parse rematch mapping request body: %v", err)
+		}
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Rematch mapping request for mapping: %s, workspace: %s, user: %s", mappingName, workspaceName, profile.UserId)
+	}
+
+	// Matching large schemas can take a while
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	grpcReq := &corev1.RematchMappingRequest{
+		TenantId:       profile.TenantId,
+		WorkspaceName:  workspaceName,
+		MappingName:    mappingName,
+		MatchOverrides: matchOverridesToProto(req.MatchOverrides),
+	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
+
+	grpcResp, err := mh.engine.mappingClient.RematchMapping(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to rematch mapping")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, RematchMappingResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+		Mapping: Mapping{
+			TenantID:           grpcResp.Mapping.TenantId,
+			WorkspaceID:        grpcResp.Mapping.WorkspaceId,
+			MappingID:          grpcResp.Mapping.MappingId,
+			MappingName:        grpcResp.Mapping.MappingName,
+			MappingDescription: grpcResp.Mapping.MappingDescription,
+			MappingType:        grpcResp.Mapping.MappingType,
+			PolicyID:           grpcResp.Mapping.PolicyId,
+			OwnerID:            grpcResp.Mapping.OwnerId,
+			MappingRuleCount:   grpcResp.Mapping.MappingRuleCount,
+			Validated:          grpcResp.Mapping.Validated,
+			TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
+		},
+		RemovedRuleCount: grpcResp.RemovedRuleCount,
+		AddedRuleCount:   grpcResp.AddedRuleCount,
+		Warnings:         grpcResp.Warnings,
+		MatchReport:      convertMatchReport(grpcResp.MatchReport),
+	})
+}
+
+// RefreshMappingSuggestions handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/refresh-suggestions
+// Re-runs the matcher of a mapping on the current schemas and proposes rules to add and remove
+func (mh *MappingHandlers) RefreshMappingSuggestions(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	if workspaceName == "" || mappingName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// The body is optional
+	var req RefreshMappingSuggestionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		if mh.engine.logger != nil {
+			mh.engine.logger.Errorf("Failed to parse refresh mapping suggestions request body: %v", err)
+		}
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Refresh mapping suggestions request for mapping: %s, workspace: %s, apply: %t, user: %s", mappingName, workspaceName, req.Apply, profile.UserId)
+	}
+
+	// Matching large schemas can take a while
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	grpcReq := &corev1.RefreshMappingSuggestionsRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		MappingName:   mappingName,
+		Apply:         req.Apply,
+	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
+
+	grpcResp, err := mh.engine.mappingClient.RefreshMappingSuggestions(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to refresh mapping suggestions")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, RefreshMappingSuggestionsResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+		Mapping: Mapping{
+			TenantID:           grpcResp.Mapping.TenantId,
+			WorkspaceID:        grpcResp.Mapping.WorkspaceId,
+			MappingID:          grpcResp.Mapping.MappingId,
+			MappingName:        grpcResp.Mapping.MappingName,
+			MappingDescription: grpcResp.Mapping.MappingDescription,
+			MappingType:        grpcResp.Mapping.MappingType,
+			PolicyID:           grpcResp.Mapping.PolicyId,
+			OwnerID:            grpcResp.Mapping.OwnerId,
+			MappingRuleCount:   grpcResp.Mapping.MappingRuleCount,
+			Validated:          grpcResp.Mapping.Validated,
+			TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
+		},
+		Additions:        convertMappingRuleSuggestions(grpcResp.Additions),
+		Removals:         convertMappingRuleSuggestions(grpcResp.Removals),
+		Applied:          grpcResp.Applied,
+		AddedRuleCount:   grpcResp.AddedRuleCount,
+		RemovedRuleCount: grpcResp.RemovedRuleCount,
+		Warnings:         grpcResp.Warnings,
+	})
+}
+
+// ListBrokenMappingRules handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/mapping-rules/broken
+func (mh *MappingHandlers) ListBrokenMappingRules(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	if workspaceName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("List broken mapping rules request for workspace: %s, user: %s", workspaceName, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.ListBrokenMappingRules(ctx, &corev1.ListBrokenMappingRulesRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to list broken mapping rules")
+		return
+	}
+
+	mappingRules := make([]MappingRule, len(grpcResp.MappingRules))
+	for i, rule := range grpcResp.MappingRules {
+		mappingRules[i] = mh.protoToMappingRule(rule)
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, ListMappingRulesResponse{
+		MappingRules: mappingRules,
+	})
+}
+
+// RepairMappingRule handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mapping-rules/{mapping_rule_name}/repair
+func (mh *MappingHandlers) RepairMappingRule(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingRuleName := vars["mapping_rule_name"]
+
+	if workspaceName == "" || mappingRuleName == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name and mapping_rule_name are required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// The body is optional
+	var req RepairMappingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		if mh.engine.logger != nil {
+			mh.engine.logger.Errorf("Failed to parse repair mapping rule request body: %v", err)
+		}
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Repair mapping rule request for rule: %s, workspace: %s, apply: %t, user: %s", mappingRuleName, workspaceName, req.Apply, profile.UserId)
+	}
+
+	// Matching large schemas can take a while
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	grpcReq := &corev1.RepairMappingRuleRequest{
+		TenantId:        profile.TenantId,
+		WorkspaceName:   workspaceName,
+		MappingRuleName: mappingRuleName,
+		Apply:           req.Apply,
+	}
+	if req.MatchProfile != "" {
+		grpcReq.MatchProfile = &req.MatchProfile
+	}
+
+	grpcResp, err := mh.engine.mappingClient.RepairMappingRule(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to repair mapping rule")
+		return
+	}
+
+	repairs := make([]MappingRuleRepair, len(grpcResp.Repairs))
+	for i, repair := range grpcResp.Repairs {
+		repairs[i] = MappingRuleRepair{
+			Side:            repair.Side,
+			MissingURI:      repair.MissingUri,
+			SuggestedURI:    repair.SuggestedUri,
+			SuggestedTable:  repair.SuggestedTable,
+			SuggestedColumn: repair.SuggestedColumn,
+			Score:           repair.Score,
+			Reasons:         repair.Reasons,
+		}
+	}
+
+	response := RepairMappingRuleResponse{
+		Message:  grpcResp.Message,
+		Success:  grpcResp.Success,
+		Status:   convertStatus(grpcResp.Status),
+		Repairs:  repairs,
+		Applied:  grpcResp.Applied,
+		Warnings: grpcResp.Warnings,
+	}
+	if grpcResp.MappingRule != nil {
+		response.MappingRule = mh.protoToMappingRule(grpcResp.MappingRule)
+	}
+	mh.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// convertMappingRuleSuggestions converts the suggested changes to the rules of a mapping
+func convertMappingRuleSuggestions(suggestions []*corev1.MappingRuleSuggestion) []MappingRuleSuggestion {
+	result := make([]MappingRuleSuggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		result[i] = MappingRuleSuggestion{
+			Action:       suggestion.Action,
+			RuleName:     suggestion.RuleName,
+			SourceTable:  suggestion.SourceTable,
+			SourceColumn: suggestion.SourceColumn,
+			TargetTable:  suggestion.TargetTable,
+			TargetColumn: suggestion.TargetColumn,
+			Score:        suggestion.Score,
+			Reason:       suggestion.Reason,
+		}
+	}
+	return result
+}
+
+// convertVectorValidations converts the validations of the vectors a data copy wrote
+func convertVectorValidations(validations []*corev1.VectorValidation) []VectorValidation {
+	result := make([]VectorValidation, len(validations))
+	for i, v := range validations {
+		result[i] = VectorValidation{
+			TablePair:      v.TablePair,
+			Samples:        v.Samples,
+			TopK:           v.TopK,
+			Dimensions:     v.Dimensions,
+			SelfMatchRate:  v.SelfMatchRate,
+			SourceCompared: v.SourceCompared,
+			Recall:         v.Recall,
+			MinRecall:      v.MinRecall,
+			FailedSearches: v.FailedSearches,
+			Issues:         v.Issues,
+			Passed:         v.Passed,
+		}
+	}
+	return result
+}
+
+// convertStageTimings converts the time a data copy spent in each stage of processing its batches
+func convertStageTimings(timings *corev1.StageTimings) StageTimings {
+	return StageTimings{
+		TablePair:       timings.TablePair,
+		Batches:         timings.Batches,
+		FetchMs:         timings.FetchMs,
+		TransformMs:     timings.TransformMs,
+		NetworkMs:       timings.NetworkMs,
+		WriteMs:         timings.WriteMs,
+		Bottleneck:      timings.Bottleneck,
+		BottleneckShare: timings.BottleneckShare,
+		Summary:         timings.Summary,
+	}
+}
+
+// convertParallelismDecisions converts the changes of the parallelism of a data copy
+func convertParallelismDecisions(decisions []*corev1.ParallelismDecision) []ParallelismDecision {
+	result := make([]ParallelismDecision, len(decisions))
+	for i, d := range decisions {
+		result[i] = ParallelismDecision{
+			TablePair:     d.TablePair,
+			DecidedAt:     d.DecidedAt,
+			From:          d.From,
+			To:            d.To,
+			RowsPerSecond: d.RowsPerSecond,
+			ErrorRate:     d.ErrorRate,
+			Reason:        d.Reason,
+		}
+	}
+	return result
+}
+
+// convertGraphVerifications converts the verifications of the edges a data copy read from graph databases
+func convertGraphVerifications(verifications []*corev1.GraphVerification) []GraphVerification {
+	result := make([]GraphVerification, len(verifications))
+	for i, v := range verifications {
+		result[i] = GraphVerification{
+			TablePair:           v.TablePair,
+			SourceEdges:         v.SourceEdges,
+			TargetEdges:         v.TargetEdges,
+			SourceNodes:         v.SourceNodes,
+			TargetNodes:         v.TargetNodes,
+			Complete:            v.Complete,
+			OutDegreeDivergence: v.OutDegreeDivergence,
+			InDegreeDivergence:  v.InDegreeDivergence,
+			PathDivergence:      v.PathDivergence,
+			Issues:              v.Issues,
+			Passed:              v.Passed,
+		}
+	}
+	return result
+}
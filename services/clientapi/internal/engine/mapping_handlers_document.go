@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+)
+
+// ExportMapping handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/export
+func (mh *MappingHandlers) ExportMapping(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.ExportMapping(ctx, &corev1.ExportMappingRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		MappingName:   mappingName,
+		Format:        r.URL.Query().Get("format"),
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to export mapping")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, ExportMappingResponse{
+		Message:   grpcResp.Message,
+		Success:   grpcResp.Success,
+		Document:  grpcResp.Document,
+		Format:    grpcResp.Format,
+		RuleCount: grpcResp.RuleCount,
+		Status:    convertStatus(grpcResp.Status),
+	})
+}
+
+// ImportMapping handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/import
+func (mh *MappingHandlers) ImportMapping(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req ImportMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+	if req.Document == "" {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "document is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcReq := &corev1.ImportMappingRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		OwnerId:       profile.UserId,
+		Document:      req.Document,
+		DatabaseNames: req.DatabaseNames,
+		Replace:       req.Replace,
+	}
+	if req.MappingName != "" {
+		grpcReq.MappingName = &req.MappingName
+	}
+
+	grpcResp, err := mh.engine.mappingClient.ImportMapping(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to import mapping")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusCreated, ImportMappingResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Mapping: Mapping{
+			TenantID:           grpcResp.Mapping.TenantId,
+			WorkspaceID:        grpcResp.Mapping.WorkspaceId,
+			MappingID:          grpcResp.Mapping.MappingId,
+			MappingName:        grpcResp.Mapping.MappingName,
+			MappingDescription: grpcResp.Mapping.MappingDescription,
+			MappingType:        grpcResp.Mapping.MappingType,
+			PolicyID:           grpcResp.Mapping.PolicyId,
+			OwnerID:            grpcResp.Mapping.OwnerId,
+			MappingRuleCount:   grpcResp.Mapping.MappingRuleCount,
+			RowFilter:          grpcResp.Mapping.RowFilter,
+			TablePins:          tablePinsFromProto(grpcResp.Mapping.TablePins),
+		},
+		RuleCount:    grpcResp.RuleCount,
+		Replaced:     grpcResp.Replaced,
+		RenamedRules: grpcResp.RenamedRules,
+		Status:       convertStatus(grpcResp.Status),
+	})
+}
@@ -0,0 +1,394 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+)
+
+// ListMatchProfiles handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/match-profiles
+func (mh *MappingHandlers) ListMatchProfiles(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.ListMatchProfiles(ctx, &corev1.ListMatchProfilesRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to list match profiles")
+		return
+	}
+
+	profiles := make([]MatchProfile, 0, len(grpcResp.Profiles))
+	for _, p := range grpcResp.Profiles {
+		profiles = append(profiles, convertMatchProfile(p))
+	}
+	mh.writeJSONResponse(w, http.StatusOK, ListMatchProfilesResponse{Profiles: profiles})
+}
+
+// SetMatchProfile handles PUT /{tenant_url}/api/v1/workspaces/{workspace_name}/match-profiles/{profile_name}
+func (mh *MappingHandlers) SetMatchProfile(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	profileName := vars["profile_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req SetMatchProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Set match profile request for profile: %s, workspace: %s, tenant: %s", profileName, workspaceName, profile.TenantId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.SetMatchProfile(ctx, &corev1.SetMatchProfileRequest{
+		TenantId:                 profile.TenantId,
+		WorkspaceName:            workspaceName,
+		ProfileName:              profileName,
+		ProfileDescription:       req.ProfileDescription,
+		BasedOn:                  req.BasedOn,
+		NameSimilarityThreshold:  req.NameSimilarityThreshold,
+		PoorMatchThreshold:       req.PoorMatchThreshold,
+		NameWeight:               req.NameWeight,
+		TypeWeight:               req.TypeWeight,
+		ClassificationWeight:     req.ClassificationWeight,
+		PrivilegedDataWeight:     req.PrivilegedDataWeight,
+		TableStructureWeight:     req.TableStructureWeight,
+		EnableCrossTableMatching: req.EnableCrossTableMatching,
+		MinRuleScore:             req.MinRuleScore,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to set match profile")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, SetMatchProfileResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Profile: convertMatchProfile(grpcResp.Profile),
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// DeleteMatchProfile handles DELETE /{tenant_url}/api/v1/workspaces/{workspace_name}/match-profiles/{profile_name}
+func (mh *MappingHandlers) DeleteMatchProfile(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	profileName := vars["profile_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.DeleteMatchProfile(ctx, &corev1.DeleteMatchProfileRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ProfileName:   profileName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to delete match profile")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, DeleteMatchProfileResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// SetDefaultMatchProfile handles PUT /{tenant_url}/api/v1/workspaces/{workspace_name}/default-match-profile
+func (mh *MappingHandlers) SetDefaultMatchProfile(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req SetDefaultMatchProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		mh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.SetDefaultMatchProfile(ctx, &corev1.SetDefaultMatchProfileRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ProfileName:   req.ProfileName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to set the default match profile")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, SetDefaultMatchProfileResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// ListMatchFeedback handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/match-feedback
+func (mh *MappingHandlers) ListMatchFeedback(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.ListMatchFeedback(ctx, &corev1.ListMatchFeedbackRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to list match feedback")
+		return
+	}
+
+	feedback := make([]MatchFeedback, 0, len(grpcResp.Feedback))
+	for _, f := range grpcResp.Feedback {
+		feedback = append(feedback, MatchFeedback{
+			SourceColumn: f.SourceColumn,
+			TargetColumn: f.TargetColumn,
+			Accepted:     f.Accepted,
+			Rejected:     f.Rejected,
+			Adjustment:   f.Adjustment,
+			Updated:      f.Updated,
+		})
+	}
+	mh.writeJSONResponse(w, http.StatusOK, ListMatchFeedbackResponse{Feedback: feedback})
+}
+
+// ResetMatchFeedback handles DELETE /{tenant_url}/api/v1/workspaces/{workspace_name}/match-feedback
+// The source_column and target_column query parameters limit the reset to the feedback on their
+// matches.
+func (mh *MappingHandlers) ResetMatchFeedback(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	grpcReq := &corev1.ResetMatchFeedbackRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	}
+	if sourceColumn := r.URL.Query().Get("source_column"); sourceColumn != "" {
+		grpcReq.SourceColumn = &sourceColumn
+	}
+	if targetColumn := r.URL.Query().Get("target_column"); targetColumn != "" {
+		grpcReq.TargetColumn = &targetColumn
+	}
+
+	if mh.engine.logger != nil {
+		mh.engine.logger.Infof("Reset match feedback request for workspace: %s, tenant: %s", workspaceName, profile.TenantId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.ResetMatchFeedback(ctx, grpcReq)
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to reset match feedback")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, ResetMatchFeedbackResponse{
+		Message:    grpcResp.Message,
+		Success:    grpcResp.Success,
+		ResetCount: grpcResp.ResetCount,
+		Status:     convertStatus(grpcResp.Status),
+	})
+}
+
+func convertMatchProfile(p *corev1.MatchProfile) MatchProfile {
+	if p == nil {
+		return MatchProfile{}
+	}
+	return MatchProfile{
+		ProfileName:              p.ProfileName,
+		ProfileDescription:       p.ProfileDescription,
+		NameSimilarityThreshold:  p.NameSimilarityThreshold,
+		PoorMatchThreshold:       p.PoorMatchThreshold,
+		NameWeight:               p.NameWeight,
+		TypeWeight:               p.TypeWeight,
+		ClassificationWeight:     p.ClassificationWeight,
+		PrivilegedDataWeight:     p.PrivilegedDataWeight,
+		TableStructureWeight:     p.TableStructureWeight,
+		EnableCrossTableMatching: p.EnableCrossTableMatching,
+		MinRuleScore:             p.MinRuleScore,
+		BuiltIn:                  p.BuiltIn,
+		IsDefault:                p.IsDefault,
+		Updated:                  p.Updated,
+	}
+}
+
+// GetMappingMatchReport handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/mappings/{mapping_name}/match-report
+func (mh *MappingHandlers) GetMappingMatchReport(w http.ResponseWriter, r *http.Request) {
+	mh.engine.TrackOperation()
+	defer mh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	mappingName := vars["mapping_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		mh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := mh.engine.mappingClient.GetMappingMatchReport(ctx, &corev1.GetMappingMatchReportRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		MappingName:   mappingName,
+	})
+	if err != nil {
+		mh.handleGRPCError(w, err, "Failed to get mapping match report")
+		return
+	}
+
+	mh.writeJSONResponse(w, http.StatusOK, GetMappingMatchReportResponse{
+		Message:     grpcResp.Message,
+		Success:     grpcResp.Success,
+		MatchReport: *convertMatchReport(grpcResp.MatchReport),
+		Status:      convertStatus(grpcResp.Status),
+	})
+}
+
+func matchOverridesToProto(o *MatchOverrides) *corev1.MatchOverrides {
+	if o == nil {
+		return nil
+	}
+	return &corev1.MatchOverrides{
+		NameSimilarityThreshold:  o.NameSimilarityThreshold,
+		PoorMatchThreshold:       o.PoorMatchThreshold,
+		NameWeight:               o.NameWeight,
+		TypeWeight:               o.TypeWeight,
+		ClassificationWeight:     o.ClassificationWeight,
+		PrivilegedDataWeight:     o.PrivilegedDataWeight,
+		TableStructureWeight:     o.TableStructureWeight,
+		EnableCrossTableMatching: o.EnableCrossTableMatching,
+		MinRuleScore:             o.MinRuleScore,
+	}
+}
+
+func convertMatchReport(r *corev1.MatchReport) *MatchReport {
+	if r == nil {
+		return nil
+	}
+	report := &MatchReport{
+		MappingName:  r.MappingName,
+		Options:      convertMatchProfile(r.Options),
+		Overridden:   r.Overridden,
+		GeneratedAt:  r.GeneratedAt,
+		OverallScore: r.OverallScore,
+		Tables:       make([]MatchReportTable, 0, len(r.Tables)),
+		Warnings:     r.Warnings,
+		Outcomes:     r.Outcomes,
+		RulesCreated: r.RulesCreated,
+	}
+	for _, t := range r.Tables {
+		table := MatchReportTable{
+			SourceTable:        t.SourceTable,
+			TargetTable:        t.TargetTable,
+			Score:              t.Score,
+			PoorMatch:          t.PoorMatch,
+			Pinned:             t.Pinned,
+			MatchedColumns:     t.MatchedColumns,
+			TotalSourceColumns: t.TotalSourceColumns,
+			TotalTargetColumns: t.TotalTargetColumns,
+			Reasons:            t.Reasons,
+			Columns:            make([]MatchReportColumn, 0, len(t.Columns)),
+		}
+		for _, c := range t.Columns {
+			table.Columns = append(table.Columns, convertMatchReportColumn(c))
+		}
+		report.Tables = append(report.Tables, table)
+	}
+	for _, c := range r.UnmatchedColumns {
+		report.UnmatchedColumns = append(report.UnmatchedColumns, convertMatchReportColumn(c))
+	}
+	return report
+}
+
+func convertMatchReportColumn(c *corev1.MatchReportColumn) MatchReportColumn {
+	return MatchReportColumn{
+		SourceTable:    c.SourceTable,
+		SourceColumn:   c.SourceColumn,
+		TargetTable:    c.TargetTable,
+		TargetColumn:   c.TargetColumn,
+		Score:          c.Score,
+		TypeCompatible: c.TypeCompatible,
+		Reasons:        c.Reasons,
+		Outcome:        c.Outcome,
+		RuleName:       c.RuleName,
+		Detail:         c.Detail,
+	}
+}
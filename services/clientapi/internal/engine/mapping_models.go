@@ -1,5 +1,7 @@
 package engine
 
+import "encoding/json"
+
 // ResourceItem represents a resource item with all its details
 type ResourceItem struct {
 	ItemID                   string   `json:"item_id"`
@@ -43,33 +45,36 @@ type RelationshipInfo struct {
 
 // Mapping represents a mapping
 type Mapping struct {
-	TenantID           string             `json:"tenant_id"`
-	WorkspaceID        string             `json:"workspace_id"`
-	MappingID          string             `json:"mapping_id"`
-	MappingName        string             `json:"mapping_name"`
-	MappingDescription string             `json:"mapping_description,omitempty"`
-	MappingType        string             `json:"mapping_type,omitempty"`
-	PolicyID           string             `json:"policy_id,omitempty"`
-	OwnerID            string             `json:"owner_id"`
-	MappingRuleCount   int32              `json:"mapping_rule_count"`
-	Validated          bool               `json:"validated"`
-	ValidatedAt        string             `json:"validated_at,omitempty"`
-	ValidationErrors   []string           `json:"validation_errors,omitempty"`
-	ValidationWarnings []string           `json:"validation_warnings,omitempty"`
-	MappingSourceType  string             `json:"mapping_source_type,omitempty"`
-	MappingTargetType  string             `json:"mapping_target_type,omitempty"`
-	MappingSource      string             `json:"mapping_source,omitempty"`
-	MappingTarget      string             `json:"mapping_target,omitempty"`
-	SourceDatabaseID   string             `json:"source_database_id,omitempty"`
-	SourceDatabaseName string             `json:"source_database_name,omitempty"`
-	SourceTableName    string             `json:"source_table_name,omitempty"`
-	TargetDatabaseID   string             `json:"target_database_id,omitempty"`
-	TargetDatabaseName string             `json:"target_database_name,omitempty"`
-	TargetTableName    string             `json:"target_table_name,omitempty"`
-	RelationshipNames  []string           `json:"relationship_names,omitempty"`
-	RelationshipInfos  []RelationshipInfo `json:"relationship_infos,omitempty"`
-	MCPResourceNames   []string           `json:"mcp_resource_names,omitempty"`
-	MCPToolNames       []string           `json:"mcp_tool_names,omitempty"`
+	TenantID           string              `json:"tenant_id"`
+	WorkspaceID        string              `json:"workspace_id"`
+	MappingID          string              `json:"mapping_id"`
+	MappingName        string              `json:"mapping_name"`
+	MappingDescription string              `json:"mapping_description,omitempty"`
+	MappingType        string              `json:"mapping_type,omitempty"`
+	PolicyID           string              `json:"policy_id,omitempty"`
+	OwnerID            string              `json:"owner_id"`
+	MappingRuleCount   int32               `json:"mapping_rule_count"`
+	Validated          bool                `json:"validated"`
+	ValidatedAt        string              `json:"validated_at,omitempty"`
+	ValidationErrors   []string            `json:"validation_errors,omitempty"`
+	ValidationWarnings []string            `json:"validation_warnings,omitempty"`
+	MappingSourceType  string              `json:"mapping_source_type,omitempty"`
+	MappingTargetType  string              `json:"mapping_target_type,omitempty"`
+	MappingSource      string              `json:"mapping_source,omitempty"`
+	MappingTarget      string              `json:"mapping_target,omitempty"`
+	SourceDatabaseID   string              `json:"source_database_id,omitempty"`
+	SourceDatabaseName string              `json:"source_database_name,omitempty"`
+	SourceTableName    string              `json:"source_table_name,omitempty"`
+	TargetDatabaseID   string              `json:"target_database_id,omitempty"`
+	TargetDatabaseName string              `json:"target_database_name,omitempty"`
+	TargetTableName    string              `json:"target_table_name,omitempty"`
+	RelationshipNames  []string            `json:"relationship_names,omitempty"`
+	RelationshipInfos  []RelationshipInfo  `json:"relationship_infos,omitempty"`
+	MCPResourceNames   []string            `json:"mcp_resource_names,omitempty"`
+	MCPToolNames       []string            `json:"mcp_tool_names,omitempty"`
+	ConflictKeys       map[string][]string `json:"conflict_keys,omitempty"`
+	RowFilter          string              `json:"row_filter,omitempty"`
+	TablePins          []TablePin          `json:"table_pins,omitempty"`
 }
 
 type MappingWithRules struct {
@@ -102,6 +107,9 @@ type MappingWithRules struct {
 	MCPToolNames         []string               `json:"mcp_tool_names,omitempty"`
 	SourceContainerItems []ResourceItem         `json:"source_container_items,omitempty"`
 	TargetContainerItems []ResourceItem         `json:"target_container_items,omitempty"`
+	ConflictKeys         map[string][]string    `json:"conflict_keys,omitempty"`
+	RowFilter            string                 `json:"row_filter,omitempty"`
+	TablePins            []TablePin             `json:"table_pins,omitempty"`
 }
 
 type ListMappingsResponse struct {
@@ -120,13 +128,28 @@ type AddMappingRequest struct {
 	Target             string `json:"target" validate:"required"`
 	PolicyID           string `json:"policy_id,omitempty"`
 	GenerateRules      *bool  `json:"generate_rules,omitempty"` // Defaults to true if not provided
+	MatchProfile       string `json:"match_profile,omitempty"`  // Defaults to the workspace default, else the profile of the scope
+	RowFilter          string `json:"row_filter,omitempty"`     // Condition on source columns, only matching rows are replicated
+	// TablePins fix table pairs before the matcher of a database mapping generates its rules
+	TablePins []TablePin `json:"table_pins,omitempty"`
+	// MatchOverrides replace options of the match profile for this mapping only
+	MatchOverrides *MatchOverrides `json:"match_overrides,omitempty"`
+}
+
+// TablePin fixes the pairing of a source and a target table of a database mapping: the source
+// table always matches the target table, or, with never, never matches it
+type TablePin struct {
+	SourceTable string `json:"source_table"`
+	TargetTable string `json:"target_table"`
+	Never       bool   `json:"never,omitempty"`
 }
 
 type AddMappingResponse struct {
-	Message string  `json:"message"`
-	Success bool    `json:"success"`
-	Mapping Mapping `json:"mapping"`
-	Status  Status  `json:"status"`
+	Message     string       `json:"message"`
+	Success     bool         `json:"success"`
+	Mapping     Mapping      `json:"mapping"`
+	Status      Status       `json:"status"`
+	MatchReport *MatchReport `json:"match_report,omitempty"` // Set when the matcher generated the rules
 }
 
 type AddDatabaseMappingRequest struct {
@@ -135,6 +158,7 @@ type AddDatabaseMappingRequest struct {
 	MappingSourceDatabaseName string `json:"mapping_source_database_name" validate:"required"`
 	MappingTargetDatabaseName string `json:"mapping_target_database_name" validate:"required"`
 	PolicyID                  string `json:"policy_id,omitempty"`
+	MatchProfile              string `json:"match_profile,omitempty"`
 }
 
 type AddDatabaseMappingResponse struct {
@@ -152,6 +176,7 @@ type AddTableMappingRequest struct {
 	MappingTargetDatabaseName string `json:"mapping_target_database_name" validate:"required"`
 	MappingTargetTableName    string `json:"mapping_target_table_name" validate:"required"`
 	PolicyID                  string `json:"policy_id,omitempty"`
+	MatchProfile              string `json:"match_profile,omitempty"`
 }
 
 type AddTableMappingWithDeployRequest struct {
@@ -188,6 +213,12 @@ type ModifyMappingRequest struct {
 	MappingNameNew     string `json:"mapping_name_new,omitempty"`
 	MappingDescription string `json:"mapping_description,omitempty"`
 	PolicyID           string `json:"policy_id,omitempty"`
+	// ConflictKeys replaces the columns identifying rows of each target table; an empty object clears them
+	ConflictKeys map[string][]string `json:"conflict_keys,omitempty"`
+	// RowFilter replaces the condition source rows must match to be replicated; an empty string removes it
+	RowFilter *string `json:"row_filter,omitempty"`
+	// TablePins replaces the table pins of a database mapping; an empty array clears them
+	TablePins []TablePin `json:"table_pins,omitempty"`
 }
 
 type ModifyMappingResponse struct {
@@ -197,6 +228,85 @@ type ModifyMappingResponse struct {
 	Status  Status  `json:"status"`
 }
 
+// RematchMappingRequest re-runs the matcher of a database mapping with its table pins
+type RematchMappingRequest struct {
+	MatchProfile   string          `json:"match_profile,omitempty"` // Defaults to the workspace default, else the database profile
+	MatchOverrides *MatchOverrides `json:"match_overrides,omitempty"`
+}
+
+// RematchMappingResponse tells how the generated rules of a mapping were replaced
+type RematchMappingResponse struct {
+	Message          string       `json:"message"`
+	Success          bool         `json:"success"`
+	Status           Status       `json:"status"`
+	Mapping          Mapping      `json:"mapping"`
+	RemovedRuleCount int32        `json:"removed_rule_count"`
+	AddedRuleCount   int32        `json:"added_rule_count"`
+	Warnings         []string     `json:"warnings,omitempty"`
+	MatchReport      *MatchReport `json:"match_report,omitempty"`
+}
+
+// RefreshMappingSuggestionsRequest re-runs the matcher of a mapping after its schemas changed
+type RefreshMappingSuggestionsRequest struct {
+	MatchProfile string `json:"match_profile,omitempty"` // Defaults to the workspace default, else the profile of the mapping type
+	Apply        bool   `json:"apply,omitempty"`         // Apply the suggestions instead of only proposing them
+}
+
+// MappingRuleSuggestion is a proposed change to the rules of a mapping
+type MappingRuleSuggestion struct {
+	Action       string  `json:"action"`
+	RuleName     string  `json:"rule_name,omitempty"`
+	SourceTable  string  `json:"source_table,omitempty"`
+	SourceColumn string  `json:"source_column,omitempty"`
+	TargetTable  string  `json:"target_table,omitempty"`
+	TargetColumn string  `json:"target_column,omitempty"`
+	Score        float64 `json:"score,omitempty"`
+	Reason       string  `json:"reason"`
+}
+
+// RefreshMappingSuggestionsResponse lists the rules to add to and remove from a mapping
+type RefreshMappingSuggestionsResponse struct {
+	Message          string                  `json:"message"`
+	Success          bool                    `json:"success"`
+	Status           Status                  `json:"status"`
+	Mapping          Mapping                 `json:"mapping"`
+	Additions        []MappingRuleSuggestion `json:"additions"`
+	Removals         []MappingRuleSuggestion `json:"removals"`
+	Applied          bool                    `json:"applied"`
+	AddedRuleCount   int32                   `json:"added_rule_count"`
+	RemovedRuleCount int32                   `json:"removed_rule_count"`
+	Warnings         []string                `json:"warnings,omitempty"`
+}
+
+// RepairMappingRuleRequest suggests the current objects closest to the missing objects of a
+// broken rule
+type RepairMappingRuleRequest struct {
+	MatchProfile string `json:"match_profile,omitempty"` // Defaults to the workspace default, else the profile of the scope
+	Apply        bool   `json:"apply,omitempty"`         // Re-point the rule to the suggestions scoring at least the minimum rule score
+}
+
+// MappingRuleRepair is the current object suggested for a missing object of a broken rule
+type MappingRuleRepair struct {
+	Side            string   `json:"side"`
+	MissingURI      string   `json:"missing_uri"`
+	SuggestedURI    string   `json:"suggested_uri,omitempty"`
+	SuggestedTable  string   `json:"suggested_table,omitempty"`
+	SuggestedColumn string   `json:"suggested_column,omitempty"`
+	Score           float64  `json:"score,omitempty"`
+	Reasons         []string `json:"reasons,omitempty"`
+}
+
+// RepairMappingRuleResponse lists the suggested repairs of a broken rule
+type RepairMappingRuleResponse struct {
+	Message     string              `json:"message"`
+	Success     bool                `json:"success"`
+	Status      Status              `json:"status"`
+	Repairs     []MappingRuleRepair `json:"repairs"`
+	Applied     bool                `json:"applied"`
+	MappingRule MappingRule         `json:"mapping_rule"`
+	Warnings    []string            `json:"warnings,omitempty"`
+}
+
 type DeleteMappingResponse struct {
 	Message string `json:"message"`
 	Success bool   `json:"success"`
@@ -216,6 +326,15 @@ type MappingRule struct {
 	MappingRuleTransformationID      string      `json:"mapping_rule_transformation_id"`
 	MappingRuleTransformationName    string      `json:"mapping_rule_transformation_name"`
 	MappingRuleTransformationOptions string      `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleTransformationChain   []string    `json:"mapping_rule_transformation_chain,omitempty"`
+	MappingRuleRowFilter             string      `json:"mapping_rule_row_filter,omitempty"`
+	MappingRuleCardinality           string      `json:"mapping_rule_cardinality,omitempty"`
+	SourceItemURIs                   []string    `json:"source_item_uris,omitempty"`
+	TargetItemURIs                   []string    `json:"target_item_uris,omitempty"`
+	Broken                           bool        `json:"broken,omitempty"` // The rule references objects that no longer exist and is left out of runs
+	BrokenReason                     string      `json:"broken_reason,omitempty"`
+	MissingObjects                   []string    `json:"missing_objects,omitempty"`            // URIs of the missing objects
+	RecommendedTransformation        string      `json:"recommended_transformation,omitempty"` // Masking transformation recommended for the privileged data the rule copies unchanged
 	OwnerID                          string      `json:"owner_id"`
 	MappingCount                     int32       `json:"mapping_count"`
 	Mappings                         []Mapping   `json:"mappings"`
@@ -232,6 +351,9 @@ type MappingRuleInMapping struct {
 	MappingRuleTransformationID      string         `json:"mapping_rule_transformation_id"`
 	MappingRuleTransformationName    string         `json:"mapping_rule_transformation_name"`
 	MappingRuleTransformationOptions string         `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleTransformationChain   []string       `json:"mapping_rule_transformation_chain,omitempty"`
+	MappingRuleRowFilter             string         `json:"mapping_rule_row_filter,omitempty"`
+	RecommendedTransformation        string         `json:"recommended_transformation,omitempty"` // Masking transformation recommended for the privileged data the rule copies unchanged
 	SourceItems                      []ResourceItem `json:"source_items,omitempty"`
 	TargetItems                      []ResourceItem `json:"target_items,omitempty"`
 }
@@ -245,12 +367,17 @@ type ShowMappingRuleResponse struct {
 }
 
 type AddMappingRuleRequest struct {
-	MappingRuleName                  string `json:"mapping_rule_name" validate:"required"`
-	MappingRuleDescription           string `json:"mapping_rule_description" validate:"required"`
-	MappingRuleSource                string `json:"mapping_rule_source" validate:"required"`
-	MappingRuleTarget                string `json:"mapping_rule_target" validate:"required"`
-	MappingRuleTransformationName    string `json:"mapping_rule_transformation_name" validate:"required"`
-	MappingRuleTransformationOptions string `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleName                  string   `json:"mapping_rule_name" validate:"required"`
+	MappingRuleDescription           string   `json:"mapping_rule_description" validate:"required"`
+	MappingRuleSource                string   `json:"mapping_rule_source" validate:"required"`
+	MappingRuleTarget                string   `json:"mapping_rule_target" validate:"required"`
+	MappingRuleTransformationName    string   `json:"mapping_rule_transformation_name,omitempty"`
+	MappingRuleTransformationOptions string   `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleTransformationChain   []string `json:"mapping_rule_transformation_chain,omitempty"` // Transformations applied in order, instead of mapping_rule_transformation_name
+	MappingRuleRowFilter             string   `json:"mapping_rule_row_filter,omitempty"`           // Condition on source columns, the rule only maps matching rows
+	MappingRuleCardinality           string   `json:"mapping_rule_cardinality,omitempty"`          // Inferred from the item URIs when empty
+	SourceItemURIs                   []string `json:"source_item_uris,omitempty"`                  // Source columns of a many-to-one rule, instead of mapping_rule_source
+	TargetItemURIs                   []string `json:"target_item_uris,omitempty"`                  // Target columns of a one-to-many rule, instead of mapping_rule_target
 }
 
 type AddMappingRuleResponse struct {
@@ -261,12 +388,14 @@ type AddMappingRuleResponse struct {
 }
 
 type ModifyMappingRuleRequest struct {
-	MappingRuleNameNew               string `json:"mapping_rule_name_new,omitempty"`
-	MappingRuleDescription           string `json:"mapping_rule_description,omitempty"`
-	MappingRuleSource                string `json:"mapping_rule_source,omitempty"`
-	MappingRuleTarget                string `json:"mapping_rule_target,omitempty"`
-	MappingRuleTransformationName    string `json:"mapping_rule_transformation_name,omitempty"`
-	MappingRuleTransformationOptions string `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleNameNew               string   `json:"mapping_rule_name_new,omitempty"`
+	MappingRuleDescription           string   `json:"mapping_rule_description,omitempty"`
+	MappingRuleSource                string   `json:"mapping_rule_source,omitempty"`
+	MappingRuleTarget                string   `json:"mapping_rule_target,omitempty"`
+	MappingRuleTransformationName    string   `json:"mapping_rule_transformation_name,omitempty"`
+	MappingRuleTransformationOptions string   `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleTransformationChain   []string `json:"mapping_rule_transformation_chain,omitempty"` // Replaces the transformation when set
+	MappingRuleRowFilter             *string  `json:"mapping_rule_row_filter,omitempty"`           // Replaces the row filter when set, an empty string removes it
 }
 
 type ModifyMappingRuleResponse struct {
@@ -282,6 +411,63 @@ type DeleteMappingRuleResponse struct {
 	Status  Status `json:"status"`
 }
 
+// AnalyzeMappingRuleImpactRequest proposes a change of a mapping rule, with the fields of
+// ModifyMappingRuleRequest, or its deletion
+type AnalyzeMappingRuleImpactRequest struct {
+	Delete                           bool   `json:"delete,omitempty"`
+	MappingRuleNameNew               string `json:"mapping_rule_name_new,omitempty"`
+	MappingRuleSource                string `json:"mapping_rule_source,omitempty"`
+	MappingRuleTarget                string `json:"mapping_rule_target,omitempty"`
+	MappingRuleTransformationName    string `json:"mapping_rule_transformation_name,omitempty"`
+	MappingRuleTransformationOptions string `json:"mapping_rule_transformation_options,omitempty"`
+	MappingRuleMetadata              string `json:"mapping_rule_metadata,omitempty"`
+}
+
+type MappingRuleImpactMapping struct {
+	MappingName        string `json:"mapping_name"`
+	MappingDescription string `json:"mapping_description,omitempty"`
+	MappingRuleCount   int32  `json:"mapping_rule_count"`
+}
+
+type MappingRuleImpactRun struct {
+	RelationshipName string `json:"relationship_name"`
+	MappingName      string `json:"mapping_name"`
+	Status           string `json:"status"`
+	Active           bool   `json:"active"`
+}
+
+type MappingRuleImpactTarget struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Via   string `json:"via"`
+	Depth int32  `json:"depth"`
+}
+
+type MappingRuleImpactPolicy struct {
+	PolicyID   string `json:"policy_id,omitempty"`
+	PolicyName string `json:"policy_name,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+type MappingRuleImpact struct {
+	MappingRuleName   string                     `json:"mapping_rule_name"`
+	Delete            bool                       `json:"delete"`
+	Destructive       bool                       `json:"destructive"`
+	ChangedFields     []string                   `json:"changed_fields"`
+	AffectedMappings  []MappingRuleImpactMapping `json:"affected_mappings"`
+	Runs              []MappingRuleImpactRun     `json:"runs"`
+	DownstreamTargets []MappingRuleImpactTarget  `json:"downstream_targets"`
+	FailingPolicies   []MappingRuleImpactPolicy  `json:"failing_policies"`
+}
+
+type AnalyzeMappingRuleImpactResponse struct {
+	Message  string            `json:"message"`
+	Success  bool              `json:"success"`
+	Status   Status            `json:"status"`
+	Impact   MappingRuleImpact `json:"impact"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
 type AttachMappingRuleRequest struct {
 	MappingRuleName  string `json:"mapping_rule_name" validate:"required"`
 	MappingRuleOrder *int64 `json:"mapping_rule_order,omitempty"`
@@ -306,11 +492,16 @@ type DetachMappingRuleResponse struct {
 // New models for mapping rule operations within mappings
 
 type AddRuleToMappingRequest struct {
-	RuleName       string `json:"rule_name" validate:"required"`
-	Source         string `json:"source" validate:"required"`
-	Target         string `json:"target" validate:"required"`
-	Transformation string `json:"transformation" validate:"required"`
-	Order          *int32 `json:"order,omitempty"`
+	RuleName              string   `json:"rule_name" validate:"required"`
+	Source                string   `json:"source"`
+	Target                string   `json:"target"`
+	Sources               []string `json:"sources,omitempty"` // Source columns of a many-to-one rule, instead of source
+	Targets               []string `json:"targets,omitempty"` // Target columns of a one-to-many rule, instead of target
+	Transformation        string   `json:"transformation,omitempty"`
+	TransformationChain   []string `json:"transformation_chain,omitempty"`   // Transformations applied in order, instead of transformation
+	TransformationOptions string   `json:"transformation_options,omitempty"` // JSON object, e.g. {"separator": ", "}
+	RowFilter             string   `json:"row_filter,omitempty"`             // Condition on source columns, the rule only maps matching rows
+	Order                 *int32   `json:"order,omitempty"`
 }
 
 type AddRuleToMappingResponse struct {
@@ -321,10 +512,12 @@ type AddRuleToMappingResponse struct {
 }
 
 type ModifyRuleInMappingRequest struct {
-	Source         *string `json:"source,omitempty"`
-	Target         *string `json:"target,omitempty"`
-	Transformation *string `json:"transformation,omitempty"`
-	Order          *int32  `json:"order,omitempty"`
+	Source              *string  `json:"source,omitempty"`
+	Target              *string  `json:"target,omitempty"`
+	Transformation      *string  `json:"transformation,omitempty"`
+	TransformationChain []string `json:"transformation_chain,omitempty"` // Replaces the transformation when set
+	RowFilter           *string  `json:"row_filter,omitempty"`           // Replaces the row filter when set, an empty string removes it
+	Order               *int32   `json:"order,omitempty"`
 }
 
 type ModifyRuleInMappingResponse struct {
@@ -346,8 +539,380 @@ type ListRulesInMappingResponse struct {
 
 // ValidateMappingResponse represents the response for validating a mapping
 type ValidateMappingResponse struct {
-	IsValid     bool     `json:"is_valid"`
-	Errors      []string `json:"errors"`
-	Warnings    []string `json:"warnings"`
-	ValidatedAt string   `json:"validated_at"`
+	IsValid         bool                     `json:"is_valid"`
+	Errors          []string                 `json:"errors"`
+	Warnings        []string                 `json:"warnings"`
+	RuleValidations []MappingRuleValidation  `json:"rule_validations"`
+	MappingIssues   []MappingValidationIssue `json:"mapping_issues"`
+	ValidatedAt     string                   `json:"validated_at"`
+}
+
+// MappingValidationIssue is a problem found by a check of a mapping validation
+type MappingValidationIssue struct {
+	Check     string `json:"check"`    // existence, type_compatibility, transformation or cardinality
+	Severity  string `json:"severity"` // error or warning
+	Message   string `json:"message"`
+	ObjectURI string `json:"object_uri,omitempty"`
+}
+
+// MappingRuleValidation is the result of the checks of a mapping rule
+type MappingRuleValidation struct {
+	MappingRuleName    string                   `json:"mapping_rule_name"`
+	Cardinality        string                   `json:"cardinality"`
+	TransformationName string                   `json:"transformation_name,omitempty"`
+	Valid              bool                     `json:"valid"`
+	Issues             []MappingValidationIssue `json:"issues"`
+}
+
+// PlanRunRequest holds the options of the copy to plan
+type PlanRunRequest struct {
+	BatchSize       int32 `json:"batch_size,omitempty"`
+	ParallelWorkers int32 `json:"parallel_workers,omitempty"`
+	Sandbox         bool  `json:"sandbox,omitempty"`
+	ValidateVectors *bool `json:"validate_vectors,omitempty"`
+	VerifyGraph     *bool `json:"verify_graph,omitempty"`
+}
+
+// MeshHop is a node of the mesh the rows of a stage pass through
+type MeshHop struct {
+	NodeID   string `json:"node_id"`
+	NodeName string `json:"node_name,omitempty"`
+}
+
+// RunPlanStage is a stage of a run plan, which starts when the stages it depends on are completed
+type RunPlanStage struct {
+	StageID          string    `json:"stage_id"`
+	Kind             string    `json:"kind"`
+	Description      string    `json:"description"`
+	DependsOn        []string  `json:"depends_on"`
+	SourceTable      string    `json:"source_table,omitempty"`
+	TargetTable      string    `json:"target_table,omitempty"`
+	SourceDatabase   string    `json:"source_database,omitempty"`
+	TargetDatabase   string    `json:"target_database,omitempty"`
+	SourceAdapter    string    `json:"source_adapter,omitempty"`
+	TargetAdapter    string    `json:"target_adapter,omitempty"`
+	NodeID           string    `json:"node_id"`
+	MeshHops         []MeshHop `json:"mesh_hops,omitempty"`
+	EstimatedRows    int64     `json:"estimated_rows"`
+	EstimatedBatches int64     `json:"estimated_batches"`
+	BatchSize        int32     `json:"batch_size,omitempty"`
+	Parallelism      int32     `json:"parallelism"`
+	Rules            []string  `json:"rules,omitempty"`
+	Transformations  []string  `json:"transformations,omitempty"`
+}
+
+// RunPlan is the execution plan of a copy of a mapping
+type RunPlan struct {
+	MappingName   string         `json:"mapping_name"`
+	NodeID        string         `json:"node_id"`
+	NodeName      string         `json:"node_name,omitempty"`
+	BatchSize     int32          `json:"batch_size"`
+	Parallelism   int32          `json:"parallelism"`
+	EstimatedRows int64          `json:"estimated_rows"`
+	Adapters      []string       `json:"adapters"`
+	RowFilter     string         `json:"row_filter,omitempty"`
+	Stages        []RunPlanStage `json:"stages"`
+	SkippedRules  []string       `json:"skipped_rules,omitempty"`
+}
+
+type PlanRunResponse struct {
+	Message  string   `json:"message"`
+	Success  bool     `json:"success"`
+	Status   Status   `json:"status"`
+	Plan     RunPlan  `json:"plan"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PreviewMappingRequest holds the options of a mapping preview
+type PreviewMappingRequest struct {
+	SampleSize int32 `json:"sample_size,omitempty"`
+}
+
+// PreviewRow is a sample source row with the target row the mapping would write for it
+type PreviewRow struct {
+	SourceRow   json.RawMessage `json:"source_row"`
+	TargetRow   json.RawMessage `json:"target_row,omitempty"`
+	FilteredOut bool            `json:"filtered_out,omitempty"`
+	Errors      []string        `json:"errors,omitempty"`
+}
+
+// PreviewTableSample is the sample of a table pair of a mapping preview
+type PreviewTableSample struct {
+	SourceTable string       `json:"source_table"`
+	TargetTable string       `json:"target_table"`
+	Rules       []string     `json:"rules"`
+	Rows        []PreviewRow `json:"rows"`
+}
+
+// PreviewMappingResponse represents the response for previewing a mapping
+type PreviewMappingResponse struct {
+	Message  string               `json:"message"`
+	Success  bool                 `json:"success"`
+	Status   Status               `json:"status"`
+	Tables   []PreviewTableSample `json:"tables"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+// MappingIndexRecommendation is an index that would support a lookup the mapping runs
+type MappingIndexRecommendation struct {
+	IndexName    string   `json:"index_name"`
+	Side         string   `json:"side"`
+	DatabaseID   string   `json:"database_id"`
+	DatabaseName string   `json:"database_name"`
+	DatabaseType string   `json:"database_type"`
+	TableName    string   `json:"table_name"`
+	Columns      []string `json:"columns"`
+	Unique       bool     `json:"unique"`
+	Reason       string   `json:"reason"`
+	Statement    string   `json:"statement,omitempty"`
+	Created      bool     `json:"created"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// CreateMappingIndexesRequest approves recommended indexes for creation
+type CreateMappingIndexesRequest struct {
+	IndexNames []string `json:"index_names" validate:"required"`
+}
+
+type MappingIndexRecommendationsResponse struct {
+	Message         string                       `json:"message"`
+	Success         bool                         `json:"success"`
+	Status          Status                       `json:"status"`
+	MappingName     string                       `json:"mapping_name"`
+	Recommendations []MappingIndexRecommendation `json:"recommendations"`
+	Warnings        []string                     `json:"warnings,omitempty"`
+}
+
+// VectorValidation is the validation of the vectors a data copy wrote into a vector store
+type VectorValidation struct {
+	TablePair      string   `json:"table_pair"`
+	Samples        int32    `json:"samples"`
+	TopK           int32    `json:"top_k"`
+	Dimensions     []int32  `json:"dimensions"`
+	SelfMatchRate  float64  `json:"self_match_rate"`
+	SourceCompared bool     `json:"source_compared"`
+	Recall         float64  `json:"recall,omitempty"`
+	MinRecall      float64  `json:"min_recall,omitempty"`
+	FailedSearches int32    `json:"failed_searches"`
+	Issues         []string `json:"issues,omitempty"`
+	Passed         bool     `json:"passed"`
+}
+
+// GraphVerification is the verification of the edges a data copy read from a graph database
+type GraphVerification struct {
+	TablePair           string   `json:"table_pair"`
+	SourceEdges         int32    `json:"source_edges"`
+	TargetEdges         int32    `json:"target_edges"`
+	SourceNodes         int32    `json:"source_nodes"`
+	TargetNodes         int32    `json:"target_nodes"`
+	Complete            bool     `json:"complete"`
+	OutDegreeDivergence float64  `json:"out_degree_divergence"`
+	InDegreeDivergence  float64  `json:"in_degree_divergence"`
+	PathDivergence      float64  `json:"path_divergence"`
+	Issues              []string `json:"issues,omitempty"`
+	Passed              bool     `json:"passed"`
+}
+
+// StageTimings is the time a data copy spent in each stage of processing its batches, for a table
+// pair or the whole copy, and the stage most of it went to
+type StageTimings struct {
+	TablePair       string  `json:"table_pair,omitempty"`
+	Batches         int64   `json:"batches"`
+	FetchMs         int64   `json:"fetch_ms"`
+	TransformMs     int64   `json:"transform_ms"`
+	NetworkMs       int64   `json:"network_ms"`
+	WriteMs         int64   `json:"write_ms"`
+	Bottleneck      string  `json:"bottleneck"`
+	BottleneckShare float64 `json:"bottleneck_share"`
+	Summary         string  `json:"summary"`
+}
+
+// ParallelismDecision is a change of the parallelism of a data copy, with the throughput and error
+// rate of the window of batches it is based on
+type ParallelismDecision struct {
+	TablePair     string  `json:"table_pair"`
+	DecidedAt     string  `json:"decided_at"`
+	From          int32   `json:"from"`
+	To            int32   `json:"to"`
+	RowsPerSecond float64 `json:"rows_per_second"`
+	ErrorRate     float64 `json:"error_rate"`
+	Reason        string  `json:"reason"`
+}
+
+// MatchProfile is a set of matcher options new mappings of a workspace can be created with
+type MatchProfile struct {
+	ProfileName              string  `json:"profile_name"`
+	ProfileDescription       string  `json:"profile_description,omitempty"`
+	NameSimilarityThreshold  float64 `json:"name_similarity_threshold"`
+	PoorMatchThreshold       float64 `json:"poor_match_threshold"`
+	NameWeight               float64 `json:"name_weight"`
+	TypeWeight               float64 `json:"type_weight"`
+	ClassificationWeight     float64 `json:"classification_weight"`
+	PrivilegedDataWeight     float64 `json:"privileged_data_weight"`
+	TableStructureWeight     float64 `json:"table_structure_weight"`
+	EnableCrossTableMatching bool    `json:"enable_cross_table_matching"`
+	MinRuleScore             float64 `json:"min_rule_score"`
+	BuiltIn                  bool    `json:"built_in"`
+	IsDefault                bool    `json:"is_default"`
+	Updated                  string  `json:"updated,omitempty"`
+}
+
+type ListMatchProfilesResponse struct {
+	Profiles []MatchProfile `json:"profiles"`
+}
+
+// SetMatchProfileRequest sets the options of a match profile; the options not given are those of
+// the based_on profile, else of the profile being replaced, else of the built-in table profile
+type SetMatchProfileRequest struct {
+	ProfileDescription       *string  `json:"profile_description,omitempty"`
+	BasedOn                  *string  `json:"based_on,omitempty"`
+	NameSimilarityThreshold  *float64 `json:"name_similarity_threshold,omitempty"`
+	PoorMatchThreshold       *float64 `json:"poor_match_threshold,omitempty"`
+	NameWeight               *float64 `json:"name_weight,omitempty"`
+	TypeWeight               *float64 `json:"type_weight,omitempty"`
+	ClassificationWeight     *float64 `json:"classification_weight,omitempty"`
+	PrivilegedDataWeight     *float64 `json:"privileged_data_weight,omitempty"`
+	TableStructureWeight     *float64 `json:"table_structure_weight,omitempty"`
+	EnableCrossTableMatching *bool    `json:"enable_cross_table_matching,omitempty"`
+	MinRuleScore             *float64 `json:"min_rule_score,omitempty"`
+}
+
+type SetMatchProfileResponse struct {
+	Message string       `json:"message"`
+	Success bool         `json:"success"`
+	Profile MatchProfile `json:"profile"`
+	Status  Status       `json:"status"`
+}
+
+type DeleteMatchProfileResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
+
+// SetDefaultMatchProfileRequest sets the default match profile of a workspace; an empty profile
+// name clears it
+type SetDefaultMatchProfileRequest struct {
+	ProfileName string `json:"profile_name"`
+}
+
+type SetDefaultMatchProfileResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
+
+// MatchOverrides replace options of the match profile for a single run of the matcher; the
+// options not given are those of the profile
+type MatchOverrides struct {
+	NameSimilarityThreshold  *float64 `json:"name_similarity_threshold,omitempty"`
+	PoorMatchThreshold       *float64 `json:"poor_match_threshold,omitempty"`
+	NameWeight               *float64 `json:"name_weight,omitempty"`
+	TypeWeight               *float64 `json:"type_weight,omitempty"`
+	ClassificationWeight     *float64 `json:"classification_weight,omitempty"`
+	PrivilegedDataWeight     *float64 `json:"privileged_data_weight,omitempty"`
+	TableStructureWeight     *float64 `json:"table_structure_weight,omitempty"`
+	EnableCrossTableMatching *bool    `json:"enable_cross_table_matching,omitempty"`
+	MinRuleScore             *float64 `json:"min_rule_score,omitempty"`
+}
+
+// MatchReport is the outcome of the last run of the matcher generating the rules of a mapping:
+// the scores of its matches, and why the columns that got no rule got none
+type MatchReport struct {
+	MappingName      string              `json:"mapping_name"`
+	Options          MatchProfile        `json:"options"`              // The match profile with the overrides
+	Overridden       []string            `json:"overridden,omitempty"` // Options the request overrode
+	GeneratedAt      string              `json:"generated_at"`
+	OverallScore     float64             `json:"overall_score"`
+	Tables           []MatchReportTable  `json:"tables"`
+	UnmatchedColumns []MatchReportColumn `json:"unmatched_columns,omitempty"`
+	Warnings         []string            `json:"warnings,omitempty"`
+	Outcomes         map[string]int32    `json:"outcomes"` // Column matches of each outcome
+	RulesCreated     int32               `json:"rules_created"`
+}
+
+type MatchReportTable struct {
+	SourceTable        string              `json:"source_table"`
+	TargetTable        string              `json:"target_table"`
+	Score              float64             `json:"score"`
+	PoorMatch          bool                `json:"poor_match,omitempty"`
+	Pinned             bool                `json:"pinned,omitempty"`
+	MatchedColumns     int32               `json:"matched_columns"`
+	TotalSourceColumns int32               `json:"total_source_columns"`
+	TotalTargetColumns int32               `json:"total_target_columns"`
+	Reasons            []string            `json:"reasons,omitempty"`
+	Columns            []MatchReportColumn `json:"columns"`
+}
+
+// MatchReportColumn is the match of a source column; outcome is rule_created, below_min_rule_score,
+// poor_match, unmatched, kept_rule, blocked_by_policy or rule_failed
+type MatchReportColumn struct {
+	SourceTable    string   `json:"source_table"`
+	SourceColumn   string   `json:"source_column"`
+	TargetTable    string   `json:"target_table,omitempty"`
+	TargetColumn   string   `json:"target_column,omitempty"`
+	Score          float64  `json:"score"`
+	TypeCompatible bool     `json:"type_compatible"`
+	Reasons        []string `json:"reasons,omitempty"`
+	Outcome        string   `json:"outcome"`
+	RuleName       string   `json:"rule_name,omitempty"`
+	Detail         string   `json:"detail,omitempty"`
+}
+
+type GetMappingMatchReportResponse struct {
+	Message     string      `json:"message"`
+	Success     bool        `json:"success"`
+	MatchReport MatchReport `json:"match_report"`
+	Status      Status      `json:"status"`
+}
+
+// MatchFeedback is what the matcher of a workspace learned from users correcting the rules it
+// generated for the match of two columns; the adjustment is added to the scores of their matches
+type MatchFeedback struct {
+	SourceColumn string  `json:"source_column"`
+	TargetColumn string  `json:"target_column"`
+	Accepted     int32   `json:"accepted"`
+	Rejected     int32   `json:"rejected"`
+	Adjustment   float64 `json:"adjustment"`
+	Updated      string  `json:"updated"`
+}
+
+type ListMatchFeedbackResponse struct {
+	Feedback []MatchFeedback `json:"feedback"`
+}
+
+type ResetMatchFeedbackResponse struct {
+	Message    string `json:"message"`
+	Success    bool   `json:"success"`
+	ResetCount int32  `json:"reset_count"`
+	Status     Status `json:"status"`
+}
+
+// ExportMappingResponse holds a mapping and its rules as a mapping document, in YAML or JSON
+type ExportMappingResponse struct {
+	Message   string `json:"message"`
+	Success   bool   `json:"success"`
+	Document  string `json:"document"`
+	Format    string `json:"format"`
+	RuleCount int32  `json:"rule_count"`
+	Status    Status `json:"status"`
+}
+
+// ImportMappingRequest creates a mapping and its rules from a mapping document; database_names
+// renames the databases of the document to those of the workspace
+type ImportMappingRequest struct {
+	Document      string            `json:"document" validate:"required"`
+	MappingName   string            `json:"mapping_name,omitempty"`
+	DatabaseNames map[string]string `json:"database_names,omitempty"`
+	Replace       bool              `json:"replace,omitempty"`
+}
+
+type ImportMappingResponse struct {
+	Message      string            `json:"message"`
+	Success      bool              `json:"success"`
+	Mapping      Mapping           `json:"mapping"`
+	RuleCount    int32             `json:"rule_count"`
+	Replaced     bool              `json:"replaced"`
+	RenamedRules map[string]string `json:"renamed_rules,omitempty"`
+	Status       Status            `json:"status"`
 }
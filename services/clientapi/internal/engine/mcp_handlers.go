@@ -809,9 +809,12 @@ func (h *MCPHandlers) writeJSONResponse(w http.ResponseWriter, statusCode int, d
 func (h *MCPHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message, details string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
+	code, remediation := errorCatalogFields(statusCode, message, details)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":   message,
-		"details": details,
+		"error":       translate(w, message),
+		"details":     details,
+		"code":        code,
+		"remediation": translateAll(w, remediation),
 	})
 }
 
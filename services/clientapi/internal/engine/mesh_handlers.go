@@ -823,6 +823,8 @@ func (mh *MeshHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -178,6 +178,21 @@ func (m *Middleware) shouldSkipAuth(r *http.Request) bool {
 		return true
 	}
 
+	// Skip authentication for the error catalog (no auth required)
+	if strings.HasPrefix(path, "/api/v1/errors") && method == http.MethodGet {
+		return true
+	}
+
+	// Skip authentication for the adapter support matrix (no auth required)
+	if strings.HasPrefix(path, "/api/v1/support-matrix") && method == http.MethodGet {
+		return true
+	}
+
+	// Skip authentication for signed blob downloads (the signature authorizes them)
+	if path == "/api/v1/blobs/download" && method == http.MethodGet {
+		return true
+	}
+
 	return false
 }
 
@@ -261,6 +276,18 @@ func (m *Middleware) determinePermissions(r *http.Request) (resourceType, resour
 		}
 	}
 
+	// Ad-hoc queries and commands passed through to a database have actions of their own, so
+	// that policies can grant them apart from other database access:
+	// /{tenant_url}/api/v1/workspaces/{workspace_name}/databases/{database_name}/{query|command}
+	if resourceType == "workspaces" && len(pathParts) == 8 && pathParts[5] == "databases" && method == http.MethodPost {
+		switch pathParts[7] {
+		case "query":
+			return "databases", pathParts[6], "execute_query"
+		case "command":
+			return "databases", pathParts[6], "execute_command"
+		}
+	}
+
 	// Special cases for workspace endpoints
 	if resourceType == "workspaces" {
 		switch method {
@@ -318,6 +345,8 @@ func (m *Middleware) writeErrorResponse(w http.ResponseWriter, statusCode int, m
 		Message: message,
 		Status:  StatusFailure,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -48,7 +48,9 @@ const (
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Status  Status `json:"status"`
+	Error       string   `json:"error"`
+	Message     string   `json:"message"`
+	Status      Status   `json:"status"`
+	Code        string   `json:"code,omitempty"`        // Error catalog code, e.g. REDB-2001
+	Remediation []string `json:"remediation,omitempty"` // Steps to resolve the error, from the error catalog
 }
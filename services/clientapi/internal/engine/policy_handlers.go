@@ -432,5 +432,7 @@ func (ph *PolicyHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	ph.writeJSONResponse(w, statusCode, response)
 }
@@ -0,0 +1,378 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// QueryProxyHandlers contains the query proxy endpoint handlers
+type QueryProxyHandlers struct {
+	engine *Engine
+}
+
+// NewQueryProxyHandlers creates a new instance of QueryProxyHandlers
+func NewQueryProxyHandlers(engine *Engine) *QueryProxyHandlers {
+	return &QueryProxyHandlers{
+		engine: engine,
+	}
+}
+
+// ListQueryProxies handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies
+func (qh *QueryProxyHandlers) ListQueryProxies(w http.ResponseWriter, r *http.Request) {
+	qh.engine.TrackOperation()
+	defer qh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := qh.engine.queryProxyClient.ListQueryProxies(ctx, &corev1.ListQueryProxiesRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		qh.handleGRPCError(w, err, "Failed to list query proxies")
+		return
+	}
+
+	proxies := make([]QueryProxy, len(grpcResp.Proxies))
+	for i, p := range grpcResp.Proxies {
+		proxies[i] = queryProxyFromProto(p)
+	}
+
+	qh.writeJSONResponse(w, http.StatusOK, ListQueryProxiesResponse{Proxies: proxies})
+}
+
+// ShowQueryProxy handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies/{proxy_name}
+func (qh *QueryProxyHandlers) ShowQueryProxy(w http.ResponseWriter, r *http.Request) {
+	qh.engine.TrackOperation()
+	defer qh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	proxyName := vars["proxy_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := qh.engine.queryProxyClient.ShowQueryProxy(ctx, &corev1.ShowQueryProxyRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ProxyName:     proxyName,
+	})
+	if err != nil {
+		qh.handleGRPCError(w, err, "Failed to show query proxy")
+		return
+	}
+
+	qh.writeJSONResponse(w, http.StatusOK, ShowQueryProxyResponse{Proxy: queryProxyFromProto(grpcResp.Proxy)})
+}
+
+// AddQueryProxy handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies
+func (qh *QueryProxyHandlers) AddQueryProxy(w http.ResponseWriter, r *http.Request) {
+	qh.engine.TrackOperation()
+	defer qh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req AddQueryProxyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		qh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.ProxyName == "" || req.RelationshipName == "" || req.ListenPort == 0 || req.Username == "" || req.Password == "" {
+		qh.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "proxy_name, relationship_name, listen_port, username and password are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := qh.engine.queryProxyClient.AddQueryProxy(ctx, &corev1.AddQueryProxyRequest{
+		TenantId:         profile.TenantId,
+		WorkspaceName:    workspaceName,
+		ProxyName:        req.ProxyName,
+		ProxyDescription: req.ProxyDescription,
+		RelationshipName: req.RelationshipName,
+		ListenPort:       req.ListenPort,
+		Username:         req.Username,
+		Password:         req.Password,
+		ReadPercent:      req.ReadPercent,
+		WritePercent:     req.WritePercent,
+		TableRoutes:      req.TableRoutes,
+		OwnerId:          profile.UserId,
+	})
+	if err != nil {
+		qh.handleGRPCError(w, err, "Failed to add query proxy")
+		return
+	}
+
+	qh.writeJSONResponse(w, http.StatusCreated, QueryProxyResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Proxy:   queryProxyFromProto(grpcResp.Proxy),
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// DeleteQueryProxy handles DELETE /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies/{proxy_name}
+func (qh *QueryProxyHandlers) DeleteQueryProxy(w http.ResponseWriter, r *http.Request) {
+	qh.engine.TrackOperation()
+	defer qh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	proxyName := vars["proxy_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := qh.engine.queryProxyClient.DeleteQueryProxy(ctx, &corev1.DeleteQueryProxyRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+		ProxyName:     proxyName,
+	})
+	if err != nil {
+		qh.handleGRPCError(w, err, "Failed to delete query proxy")
+		return
+	}
+
+	qh.writeJSONResponse(w, http.StatusOK, DeleteQueryProxyResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// StartQueryProxy handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies/{proxy_name}/start
+func (qh *QueryProxyHandlers) StartQueryProxy(w http.ResponseWriter, r *http.Request) {
+	qh.queryProxyOperation(w, r, "Failed to start query proxy", func(ctx context.Context, tenantID, workspaceName, proxyName string) (queryProxyOperationResponse, error) {
+		return qh.engine.queryProxyClient.StartQueryProxy(ctx, &corev1.StartQueryProxyRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			ProxyName:     proxyName,
+		})
+	})
+}
+
+// StopQueryProxy handles POST /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies/{proxy_name}/stop
+func (qh *QueryProxyHandlers) StopQueryProxy(w http.ResponseWriter, r *http.Request) {
+	qh.queryProxyOperation(w, r, "Failed to stop query proxy", func(ctx context.Context, tenantID, workspaceName, proxyName string) (queryProxyOperationResponse, error) {
+		return qh.engine.queryProxyClient.StopQueryProxy(ctx, &corev1.StopQueryProxyRequest{
+			TenantId:      tenantID,
+			WorkspaceName: workspaceName,
+			ProxyName:     proxyName,
+		})
+	})
+}
+
+// UpdateQueryProxyRoutes handles PUT /{tenant_url}/api/v1/workspaces/{workspace_name}/query-proxies/{proxy_name}/routes
+func (qh *QueryProxyHandlers) UpdateQueryProxyRoutes(w http.ResponseWriter, r *http.Request) {
+	var req UpdateQueryProxyRoutesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		qh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	qh.queryProxyOperation(w, r, "Failed to update query proxy routes", func(ctx context.Context, tenantID, workspaceName, proxyName string) (queryProxyOperationResponse, error) {
+		return qh.engine.queryProxyClient.UpdateQueryProxyRoutes(ctx, &corev1.UpdateQueryProxyRoutesRequest{
+			TenantId:         tenantID,
+			WorkspaceName:    workspaceName,
+			ProxyName:        proxyName,
+			ReadPercent:      req.ReadPercent,
+			WritePercent:     req.WritePercent,
+			TableRoutes:      req.TableRoutes,
+			ClearTableRoutes: req.ClearTableRoutes,
+		})
+	})
+}
+
+// queryProxyOperationResponse is the response of the operations that change a query proxy
+type queryProxyOperationResponse interface {
+	GetMessage() string
+	GetSuccess() bool
+	GetProxy() *corev1.QueryProxy
+	GetStatus() commonv1.Status
+}
+
+// queryProxyOperation runs an operation that changes a query proxy
+func (qh *QueryProxyHandlers) queryProxyOperation(w http.ResponseWriter, r *http.Request, failure string, operation func(ctx context.Context, tenantID, workspaceName, proxyName string) (queryProxyOperationResponse, error)) {
+	qh.engine.TrackOperation()
+	defer qh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	proxyName := vars["proxy_name"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Stopping waits for the open sessions of the proxy to close
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcResp, err := operation(ctx, profile.TenantId, workspaceName, proxyName)
+	if err != nil {
+		qh.handleGRPCError(w, err, failure)
+		return
+	}
+
+	qh.writeJSONResponse(w, http.StatusOK, QueryProxyResponse{
+		Message: grpcResp.GetMessage(),
+		Success: grpcResp.GetSuccess(),
+		Proxy:   queryProxyFromProto(grpcResp.GetProxy()),
+		Status:  convertStatus(grpcResp.GetStatus()),
+	})
+}
+
+func queryProxyFromProto(p *corev1.QueryProxy) QueryProxy {
+	if p == nil {
+		return QueryProxy{}
+	}
+	tableRoutes := p.TableRoutes
+	if tableRoutes == nil {
+		tableRoutes = map[string]string{}
+	}
+	proxy := QueryProxy{
+		TenantID:           p.TenantId,
+		WorkspaceID:        p.WorkspaceId,
+		ProxyID:            p.ProxyId,
+		ProxyName:          p.ProxyName,
+		ProxyDescription:   p.ProxyDescription,
+		RelationshipName:   p.RelationshipName,
+		SourceDatabaseName: p.SourceDatabaseName,
+		TargetDatabaseName: p.TargetDatabaseName,
+		Protocol:           p.Protocol,
+		NodeID:             p.NodeId,
+		ListenPort:         p.ListenPort,
+		Username:           p.Username,
+		ReadPercent:        p.ReadPercent,
+		WritePercent:       p.WritePercent,
+		TableRoutes:        tableRoutes,
+		Status:             convertStatus(p.Status),
+		StatusMessage:      p.StatusMessage,
+		OwnerID:            p.OwnerId,
+		Created:            p.Created,
+		Updated:            p.Updated,
+	}
+	if p.Stats != nil {
+		proxy.Stats = &QueryProxyStats{
+			ListenAddress:    p.Stats.ListenAddress,
+			ActiveSessions:   p.Stats.ActiveSessions,
+			TotalSessions:    p.Stats.TotalSessions,
+			SourceStatements: p.Stats.SourceStatements,
+			TargetStatements: p.Stats.TargetStatements,
+			Started:          p.Stats.Started,
+		}
+	}
+	return proxy
+}
+
+// handleGRPCError handles gRPC errors and converts them to HTTP responses
+func (qh *QueryProxyHandlers) handleGRPCError(w http.ResponseWriter, err error, defaultMessage string) {
+	if qh.engine.logger != nil {
+		qh.engine.logger.Errorf("gRPC error: %v", err)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		qh.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, err.Error())
+		return
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		qh.writeErrorResponse(w, http.StatusNotFound, "Resource not found", st.Message())
+	case codes.InvalidArgument:
+		qh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", st.Message())
+	case codes.FailedPrecondition:
+		qh.writeErrorResponse(w, http.StatusPreconditionFailed, "Precondition failed", st.Message())
+	case codes.PermissionDenied:
+		qh.writeErrorResponse(w, http.StatusForbidden, "Permission denied", st.Message())
+	case codes.Unauthenticated:
+		qh.writeErrorResponse(w, http.StatusUnauthorized, "Authentication required", st.Message())
+	case codes.Unavailable:
+		qh.writeErrorResponse(w, http.StatusServiceUnavailable, "Service unavailable", st.Message())
+	case codes.DeadlineExceeded:
+		qh.writeErrorResponse(w, http.StatusRequestTimeout, "Request timeout", st.Message())
+	default:
+		qh.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, st.Message())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (qh *QueryProxyHandlers) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		if qh.engine.logger != nil {
+			qh.engine.logger.Errorf("Failed to encode JSON response: %v", err)
+		}
+	}
+}
+
+// writeErrorResponse writes an error response
+func (qh *QueryProxyHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message, details string) {
+	if qh.engine.logger != nil {
+		if statusCode >= 500 {
+			qh.engine.logger.Errorf("HTTP %d - %s: %s", statusCode, message, details)
+		} else if statusCode >= 400 {
+			qh.engine.logger.Warnf("HTTP %d - %s: %s", statusCode, message, details)
+		}
+	}
+
+	response := ErrorResponse{
+		Error:   message,
+		Message: details,
+		Status:  StatusError,
+	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		if qh.engine.logger != nil {
+			qh.engine.logger.Errorf("Failed to encode error response: %v", err)
+		}
+	}
+}
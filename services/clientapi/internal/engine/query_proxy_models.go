@@ -0,0 +1,83 @@
+package engine
+
+// QueryProxyStats represents the activity of a running query proxy
+type QueryProxyStats struct {
+	ListenAddress    string `json:"listen_address"`
+	ActiveSessions   int64  `json:"active_sessions"`
+	TotalSessions    int64  `json:"total_sessions"`
+	SourceStatements int64  `json:"source_statements"`
+	TargetStatements int64  `json:"target_statements"`
+	Started          string `json:"started"`
+}
+
+// QueryProxy represents a proxy routing the statements of applications between the source and
+// target database of a relationship
+type QueryProxy struct {
+	TenantID           string            `json:"tenant_id"`
+	WorkspaceID        string            `json:"workspace_id"`
+	ProxyID            string            `json:"proxy_id"`
+	ProxyName          string            `json:"proxy_name"`
+	ProxyDescription   string            `json:"proxy_description"`
+	RelationshipName   string            `json:"relationship_name"`
+	SourceDatabaseName string            `json:"source_database_name"`
+	TargetDatabaseName string            `json:"target_database_name"`
+	Protocol           string            `json:"protocol"`
+	NodeID             string            `json:"node_id"`
+	ListenPort         int32             `json:"listen_port"`
+	Username           string            `json:"username"`
+	ReadPercent        int32             `json:"read_percent"`
+	WritePercent       int32             `json:"write_percent"`
+	TableRoutes        map[string]string `json:"table_routes"`
+	Status             Status            `json:"status"`
+	StatusMessage      string            `json:"status_message,omitempty"`
+	Stats              *QueryProxyStats  `json:"stats,omitempty"`
+	OwnerID            string            `json:"owner_id"`
+	Created            string            `json:"created"`
+	Updated            string            `json:"updated"`
+}
+
+// ListQueryProxiesResponse represents the list query proxies response
+type ListQueryProxiesResponse struct {
+	Proxies []QueryProxy `json:"proxies"`
+}
+
+// ShowQueryProxyResponse represents the show query proxy response
+type ShowQueryProxyResponse struct {
+	Proxy QueryProxy `json:"proxy"`
+}
+
+// AddQueryProxyRequest represents the add query proxy request
+type AddQueryProxyRequest struct {
+	ProxyName        string            `json:"proxy_name"`
+	ProxyDescription string            `json:"proxy_description,omitempty"`
+	RelationshipName string            `json:"relationship_name"`
+	ListenPort       int32             `json:"listen_port"`
+	Username         string            `json:"username"`
+	Password         string            `json:"password"`
+	ReadPercent      int32             `json:"read_percent,omitempty"`
+	WritePercent     int32             `json:"write_percent,omitempty"`
+	TableRoutes      map[string]string `json:"table_routes,omitempty"`
+}
+
+// UpdateQueryProxyRoutesRequest represents the update query proxy routes request
+type UpdateQueryProxyRoutesRequest struct {
+	ReadPercent      *int32            `json:"read_percent,omitempty"`
+	WritePercent     *int32            `json:"write_percent,omitempty"`
+	TableRoutes      map[string]string `json:"table_routes,omitempty"`
+	ClearTableRoutes bool              `json:"clear_table_routes,omitempty"`
+}
+
+// QueryProxyResponse represents the response of the operations that change a query proxy
+type QueryProxyResponse struct {
+	Message string     `json:"message"`
+	Success bool       `json:"success"`
+	Proxy   QueryProxy `json:"proxy"`
+	Status  Status     `json:"status"`
+}
+
+// DeleteQueryProxyResponse represents the delete query proxy response
+type DeleteQueryProxyResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
@@ -499,6 +499,8 @@ func (rh *RegionHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: message,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
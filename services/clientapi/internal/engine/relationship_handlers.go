@@ -568,6 +568,7 @@ func (rh *RelationshipHandlers) StartRelationship(w http.ResponseWriter, r *http
 			"cdc_status":          resp.CdcStatus,
 			"progress_percentage": resp.ProgressPercentage,
 			"errors":              resp.Errors,
+			"warnings":            resp.Warnings,
 		})
 
 		fmt.Fprintf(w, "data: %s\n\n", eventData)
@@ -752,5 +753,7 @@ func (rh *RelationshipHandlers) writeErrorResponse(w http.ResponseWriter, status
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	rh.writeJSONResponse(w, statusCode, response)
 }
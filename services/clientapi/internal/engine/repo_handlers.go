@@ -526,6 +526,8 @@ func (rh *RepoHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	rh.writeJSONResponse(w, statusCode, response)
 }
 
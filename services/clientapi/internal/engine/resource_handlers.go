@@ -611,10 +611,13 @@ func (rh *ResourceHandlers) ModifyResourceItem(w http.ResponseWriter, r *http.Re
 func (rh *ResourceHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message string, detail string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
+	code, remediation := errorCatalogFields(statusCode, message, detail)
 	response := map[string]interface{}{
-		"error":   message,
-		"message": detail,
-		"success": false,
+		"error":       translate(w, message),
+		"message":     detail,
+		"success":     false,
+		"code":        code,
+		"remediation": translateAll(w, remediation),
 	}
 	json.NewEncoder(w).Encode(response)
 }
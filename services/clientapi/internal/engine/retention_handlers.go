@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetentionHandlers contains the retention policy endpoint handlers
+type RetentionHandlers struct {
+	engine *Engine
+}
+
+// NewRetentionHandlers creates a new instance of RetentionHandlers
+func NewRetentionHandlers(engine *Engine) *RetentionHandlers {
+	return &RetentionHandlers{
+		engine: engine,
+	}
+}
+
+// ListRetentionPolicies handles GET /{tenant_url}/api/v1/retention
+func (rh *RetentionHandlers) ListRetentionPolicies(w http.ResponseWriter, r *http.Request) {
+	rh.engine.TrackOperation()
+	defer rh.engine.UntrackOperation()
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		rh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := rh.engine.retentionClient.ListRetentionPolicies(ctx, &corev1.ListRetentionPoliciesRequest{
+		TenantId: profile.TenantId,
+	})
+	if err != nil {
+		rh.handleGRPCError(w, err, "Failed to list retention policies")
+		return
+	}
+
+	policies := make([]RetentionPolicy, len(grpcResp.Policies))
+	for i, p := range grpcResp.Policies {
+		policies[i] = retentionPolicyFromProto(p)
+	}
+
+	rh.writeJSONResponse(w, http.StatusOK, ListRetentionPoliciesResponse{Policies: policies})
+}
+
+// SetRetentionPolicy handles PUT /{tenant_url}/api/v1/retention/{resource_type}
+func (rh *RetentionHandlers) SetRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	rh.engine.TrackOperation()
+	defer rh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	resourceType := vars["resource_type"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		rh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req SetRetentionPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if rh.engine.logger != nil {
+			rh.engine.logger.Errorf("Failed to parse set retention policy request body: %v", err)
+		}
+		rh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if req.KeepDays < 0 || req.KeepVersions < 0 {
+		rh.writeErrorResponse(w, http.StatusBadRequest, "Invalid retention policy", "keep_days and keep_versions cannot be negative")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := rh.engine.retentionClient.SetRetentionPolicy(ctx, &corev1.SetRetentionPolicyRequest{
+		TenantId:     profile.TenantId,
+		ResourceType: resourceType,
+		KeepDays:     req.KeepDays,
+		KeepVersions: req.KeepVersions,
+	})
+	if err != nil {
+		rh.handleGRPCError(w, err, "Failed to set retention policy")
+		return
+	}
+
+	rh.writeJSONResponse(w, http.StatusOK, RetentionPolicyResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Policy:  retentionPolicyFromProto(grpcResp.Policy),
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// DeleteRetentionPolicy handles DELETE /{tenant_url}/api/v1/retention/{resource_type}
+func (rh *RetentionHandlers) DeleteRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	rh.engine.TrackOperation()
+	defer rh.engine.UntrackOperation()
+
+	vars := mux.Vars(r)
+	resourceType := vars["resource_type"]
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		rh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := rh.engine.retentionClient.DeleteRetentionPolicy(ctx, &corev1.DeleteRetentionPolicyRequest{
+		TenantId:     profile.TenantId,
+		ResourceType: resourceType,
+	})
+	if err != nil {
+		rh.handleGRPCError(w, err, "Failed to delete retention policy")
+		return
+	}
+
+	rh.writeJSONResponse(w, http.StatusOK, RetentionPolicyResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Policy:  retentionPolicyFromProto(grpcResp.Policy),
+		Status:  convertStatus(grpcResp.Status),
+	})
+}
+
+// PreviewRetention handles GET /{tenant_url}/api/v1/retention/preview
+func (rh *RetentionHandlers) PreviewRetention(w http.ResponseWriter, r *http.Request) {
+	rh.engine.TrackOperation()
+	defer rh.engine.UntrackOperation()
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		rh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	grpcResp, err := rh.engine.retentionClient.PreviewRetention(ctx, &corev1.PreviewRetentionRequest{
+		TenantId: profile.TenantId,
+	})
+	if err != nil {
+		rh.handleGRPCError(w, err, "Failed to preview retention")
+		return
+	}
+
+	purges := make([]RetentionPurge, len(grpcResp.Purges))
+	for i, p := range grpcResp.Purges {
+		purges[i] = RetentionPurge{
+			ResourceType: p.ResourceType,
+			Items:        p.Items,
+			Oldest:       p.Oldest,
+		}
+	}
+
+	rh.writeJSONResponse(w, http.StatusOK, PreviewRetentionResponse{
+		Purges:  purges,
+		NextRun: grpcResp.NextRun,
+	})
+}
+
+func retentionPolicyFromProto(p *corev1.RetentionPolicy) RetentionPolicy {
+	if p == nil {
+		return RetentionPolicy{}
+	}
+	return RetentionPolicy{
+		TenantID:     p.TenantId,
+		ResourceType: p.ResourceType,
+		KeepDays:     p.KeepDays,
+		KeepVersions: p.KeepVersions,
+		IsDefault:    p.IsDefault,
+		Updated:      p.Updated,
+	}
+}
+
+// handleGRPCError handles gRPC errors and converts them to HTTP responses
+func (rh *RetentionHandlers) handleGRPCError(w http.ResponseWriter, err error, defaultMessage string) {
+	if rh.engine.logger != nil {
+		rh.engine.logger.Errorf("gRPC error: %v", err)
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		rh.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, err.Error())
+		return
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		rh.writeErrorResponse(w, http.StatusNotFound, "Resource not found", st.Message())
+	case codes.InvalidArgument:
+		rh.writeErrorResponse(w, http.StatusBadRequest, "Invalid request", st.Message())
+	case codes.FailedPrecondition:
+		rh.writeErrorResponse(w, http.StatusPreconditionFailed, "Precondition failed", st.Message())
+	case codes.PermissionDenied:
+		rh.writeErrorResponse(w, http.StatusForbidden, "Permission denied", st.Message())
+	case codes.Unauthenticated:
+		rh.writeErrorResponse(w, http.StatusUnauthorized, "Authentication required", st.Message())
+	case codes.Unavailable:
+		rh.writeErrorResponse(w, http.StatusServiceUnavailable, "Service unavailable", st.Message())
+	case codes.DeadlineExceeded:
+		rh.writeErrorResponse(w, http.StatusRequestTimeout, "Request timeout", st.Message())
+	default:
+		rh.writeErrorResponse(w, http.StatusInternalServerError, defaultMessage, st.Message())
+	}
+}
+
+// writeJSONResponse writes a JSON response
+func (rh *RetentionHandlers) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		if rh.engine.logger != nil {
+			rh.engine.logger.Errorf("Failed to encode JSON response: %v", err)
+		}
+	}
+}
+
+// writeErrorResponse writes an error response
+func (rh *RetentionHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int, message, details string) {
+	if rh.engine.logger != nil {
+		if statusCode >= 500 {
+			rh.engine.logger.Errorf("HTTP %d - %s: %s", statusCode, message, details)
+		} else if statusCode >= 400 {
+			rh.engine.logger.Warnf("HTTP %d - %s: %s", statusCode, message, details)
+		}
+	}
+
+	response := ErrorResponse{
+		Error:   message,
+		Message: details,
+		Status:  StatusError,
+	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		if rh.engine.logger != nil {
+			rh.engine.logger.Errorf("Failed to encode error response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package engine
+
+// RetentionPolicy represents the retention policy of a tenant for a resource type
+type RetentionPolicy struct {
+	TenantID     string `json:"tenant_id"`
+	ResourceType string `json:"resource_type"`
+	KeepDays     int32  `json:"keep_days"`
+	KeepVersions int32  `json:"keep_versions"`
+	IsDefault    bool   `json:"is_default"`
+	Updated      string `json:"updated,omitempty"`
+}
+
+// RetentionPurge represents the items of a resource type due for purging
+type RetentionPurge struct {
+	ResourceType string `json:"resource_type"`
+	Items        int64  `json:"items"`
+	Oldest       string `json:"oldest,omitempty"`
+}
+
+// ListRetentionPoliciesResponse represents the list retention policies response
+type ListRetentionPoliciesResponse struct {
+	Policies []RetentionPolicy `json:"policies"`
+}
+
+// SetRetentionPolicyRequest represents the set retention policy request
+type SetRetentionPolicyRequest struct {
+	KeepDays     int32 `json:"keep_days"`
+	KeepVersions int32 `json:"keep_versions"`
+}
+
+// RetentionPolicyResponse represents the set and delete retention policy responses
+type RetentionPolicyResponse struct {
+	Message string          `json:"message"`
+	Success bool            `json:"success"`
+	Policy  RetentionPolicy `json:"policy"`
+	Status  Status          `json:"status"`
+}
+
+// PreviewRetentionResponse represents the retention preview response
+type PreviewRetentionResponse struct {
+	Purges  []RetentionPurge `json:"purges"`
+	NextRun string           `json:"next_run,omitempty"`
+}
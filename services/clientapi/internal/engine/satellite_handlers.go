@@ -512,6 +512,8 @@ func (sh *SatelliteHandlers) writeErrorResponse(w http.ResponseWriter, statusCod
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
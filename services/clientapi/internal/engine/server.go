@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/redbco/redb-open/pkg/i18n"
 )
 
 type Server struct {
@@ -27,6 +28,8 @@ type Server struct {
 	commitHandler         *CommitHandlers
 	mappingHandler        *MappingHandlers
 	relationshipHandler   *RelationshipHandlers
+	cutoverHandler        *CutoverHandlers
+	queryProxyHandler     *QueryProxyHandlers
 	transformationHandler *TransformationHandlers
 	policyHandler         *PolicyHandlers
 	mcpHandler            *MCPHandlers
@@ -34,7 +37,10 @@ type Server struct {
 	tenantHandler         *TenantHandlers
 	resourceHandler       *ResourceHandlers
 	dataProductHandler    *DataProductHandlers
+	artifactHandler       *ArtifactHandlers
+	retentionHandler      *RetentionHandlers
 	middleware            *Middleware
+	translations          *i18n.Catalog
 }
 
 func NewServer(engine *Engine) *Server {
@@ -56,6 +62,8 @@ func NewServer(engine *Engine) *Server {
 		commitHandler:         NewCommitHandlers(engine),
 		mappingHandler:        NewMappingHandlers(engine),
 		relationshipHandler:   NewRelationshipHandlers(engine),
+		cutoverHandler:        NewCutoverHandlers(engine),
+		queryProxyHandler:     NewQueryProxyHandlers(engine),
 		transformationHandler: NewTransformationHandlers(engine),
 		policyHandler:         NewPolicyHandlers(engine),
 		mcpHandler:            NewMCPHandlers(engine),
@@ -63,8 +71,11 @@ func NewServer(engine *Engine) *Server {
 		tenantHandler:         NewTenantHandlers(engine),
 		resourceHandler:       NewResourceHandlers(engine),
 		dataProductHandler:    NewDataProductHandlers(engine),
+		artifactHandler:       NewArtifactHandlers(engine),
+		retentionHandler:      NewRetentionHandlers(engine),
 		middleware:            NewMiddleware(engine),
 	}
+	s.translations = s.loadTranslations()
 	s.setupRoutes()
 	s.setupMiddleware()
 	return s
@@ -100,6 +111,9 @@ func (s *Server) setupMiddleware() {
 		})
 	})
 
+	// Locale middleware, so that authentication errors are localized too
+	s.router.Use(s.localeMiddleware)
+
 	// Authentication and Authorization middleware
 	s.router.Use(s.middleware.AuthenticationMiddleware)
 	s.router.Use(s.middleware.AuthorizationMiddleware)
@@ -112,6 +126,17 @@ func (s *Server) setupRoutes() {
 	// Node status endpoint (global, no authentication required)
 	s.router.HandleFunc("/api/v1/status", s.handleNodeStatus).Methods(http.MethodGet)
 
+	// Error catalog endpoints (global, no authentication required)
+	s.router.HandleFunc("/api/v1/errors", s.handleErrorCatalog).Methods(http.MethodGet)
+	s.router.HandleFunc("/api/v1/errors/{code}", s.handleErrorCode).Methods(http.MethodGet)
+
+	// Adapter support matrix endpoints (global, no authentication required)
+	s.router.HandleFunc("/api/v1/support-matrix", s.handleSupportMatrix).Methods(http.MethodGet)
+	s.router.HandleFunc("/api/v1/support-matrix/{database}", s.handleDatabaseSupport).Methods(http.MethodGet)
+
+	// Signed blob downloads (global, authorized by the URL signature)
+	s.router.HandleFunc("/api/v1/blobs/download", s.artifactHandler.DownloadBlob).Methods(http.MethodGet)
+
 	// Initial setup endpoint (no authentication required) - from API
 	// Disabled in the open-source version due to lack of multi-tenant support
 	//s.router.HandleFunc("/api/v1/setup", s.handleInitialSetup).Methods(http.MethodPost)
@@ -190,6 +215,8 @@ func (s *Server) setupRoutes() {
 	workspaces.HandleFunc("/{workspace_name}", s.workspaceHandler.ShowWorkspace).Methods(http.MethodGet)
 	workspaces.HandleFunc("/{workspace_name}", s.workspaceHandler.ModifyWorkspace).Methods(http.MethodPut)
 	workspaces.HandleFunc("/{workspace_name}", s.workspaceHandler.DeleteWorkspace).Methods(http.MethodDelete)
+	workspaces.HandleFunc("/{workspace_name}/health", s.workspaceHandler.GetWorkspaceHealth).Methods(http.MethodGet)
+	workspaces.HandleFunc("/{workspace_name}/ready", s.workspaceHandler.GetWorkspaceReadiness).Methods(http.MethodGet)
 
 	// Environment endpoints (nested under workspaces)
 	environments := workspaces.PathPrefix("/{workspace_name}/environments").Subrouter()
@@ -231,6 +258,16 @@ func (s *Server) setupRoutes() {
 	transformations := tenantRouter.PathPrefix("/transformations").Subrouter()
 	transformations.HandleFunc("", s.transformationHandler.ListTransformations).Methods(http.MethodGet)
 	transformations.HandleFunc("", s.transformationHandler.AddTransformation).Methods(http.MethodPost)
+	// Plugin and lookup table routes come before the transformation ID routes they would otherwise match
+	transformations.HandleFunc("/plugins", s.transformationHandler.ListTransformationPlugins).Methods(http.MethodGet)
+	transformations.HandleFunc("/plugins", s.transformationHandler.RegisterTransformationPlugin).Methods(http.MethodPost)
+	transformations.HandleFunc("/plugins/{plugin_name}", s.transformationHandler.DeleteTransformationPlugin).Methods(http.MethodDelete)
+	transformations.HandleFunc("/lookup-tables", s.transformationHandler.ListLookupTables).Methods(http.MethodGet)
+	transformations.HandleFunc("/lookup-tables", s.transformationHandler.AddLookupTable).Methods(http.MethodPost)
+	transformations.HandleFunc("/lookup-tables/{lookup_table_name}", s.transformationHandler.ShowLookupTable).Methods(http.MethodGet)
+	transformations.HandleFunc("/lookup-tables/{lookup_table_name}", s.transformationHandler.ModifyLookupTable).Methods(http.MethodPut)
+	transformations.HandleFunc("/lookup-tables/{lookup_table_name}", s.transformationHandler.DeleteLookupTable).Methods(http.MethodDelete)
+	transformations.HandleFunc("/lookup-tables/{lookup_table_name}/refresh", s.transformationHandler.RefreshLookupTable).Methods(http.MethodPost)
 	transformations.HandleFunc("/{transformation_id}", s.transformationHandler.ShowTransformation).Methods(http.MethodGet)
 	transformations.HandleFunc("/{transformation_id}", s.transformationHandler.ModifyTransformation).Methods(http.MethodPut)
 	transformations.HandleFunc("/{transformation_id}", s.transformationHandler.DeleteTransformation).Methods(http.MethodDelete)
@@ -243,6 +280,13 @@ func (s *Server) setupRoutes() {
 	policies.HandleFunc("/{policy_id}", s.policyHandler.ModifyPolicy).Methods(http.MethodPut)
 	policies.HandleFunc("/{policy_id}", s.policyHandler.DeletePolicy).Methods(http.MethodDelete)
 
+	// Retention policy endpoints (tenant-level)
+	retentionPolicies := tenantRouter.PathPrefix("/retention").Subrouter()
+	retentionPolicies.HandleFunc("", s.retentionHandler.ListRetentionPolicies).Methods(http.MethodGet)
+	retentionPolicies.HandleFunc("/preview", s.retentionHandler.PreviewRetention).Methods(http.MethodGet)
+	retentionPolicies.HandleFunc("/{resource_type}", s.retentionHandler.SetRetentionPolicy).Methods(http.MethodPut)
+	retentionPolicies.HandleFunc("/{resource_type}", s.retentionHandler.DeleteRetentionPolicy).Methods(http.MethodDelete)
+
 	// User endpoints (tenant-level)
 	users := tenantRouter.PathPrefix("/users").Subrouter()
 	users.HandleFunc("", s.userHandler.ListUsers).Methods(http.MethodGet)
@@ -261,21 +305,32 @@ func (s *Server) setupRoutes() {
 	instances.HandleFunc("/{instance_name}/reconnect", s.instanceHandler.ReconnectInstance).Methods(http.MethodPost)
 	instances.HandleFunc("/{instance_name}/disconnect", s.instanceHandler.DisconnectInstance).Methods(http.MethodPost)
 	instances.HandleFunc("/{instance_name}/create", s.instanceHandler.CreateDatabase).Methods(http.MethodPost)
+	instances.HandleFunc("/{instance_name}/databases", s.instanceHandler.ListInstanceDatabases).Methods(http.MethodGet)
+	instances.HandleFunc("/{instance_name}/attach-databases", s.instanceHandler.AttachInstanceDatabases).Methods(http.MethodPost)
 
 	// Database endpoints (workspace-level)
 	databases := workspaces.PathPrefix("/{workspace_name}/databases").Subrouter()
 	databases.HandleFunc("", s.databaseHandler.ListDatabases).Methods(http.MethodGet)
 	databases.HandleFunc("/connect", s.databaseHandler.ConnectDatabase).Methods(http.MethodPost)
+	databases.HandleFunc("/upload", s.databaseHandler.UploadDatabaseFile).Methods(http.MethodPost)
 	databases.HandleFunc("/connect-string", s.databaseHandler.ConnectDatabaseString).Methods(http.MethodPost)
 	databases.HandleFunc("/connect-with-instance", s.databaseHandler.ConnectDatabaseWithInstance).Methods(http.MethodPost)
+	databases.HandleFunc("/metrics", s.databaseHandler.GetDatabaseMetrics).Methods(http.MethodGet)
 	databases.HandleFunc("/{database_name}", s.databaseHandler.ShowDatabase).Methods(http.MethodGet)
 	databases.HandleFunc("/{database_name}/reconnect", s.databaseHandler.ReconnectDatabase).Methods(http.MethodPost)
 	databases.HandleFunc("/{database_name}", s.databaseHandler.ModifyDatabase).Methods(http.MethodPut)
 	databases.HandleFunc("/{database_name}/disconnect", s.databaseHandler.DisconnectDatabase).Methods(http.MethodPost)
 	databases.HandleFunc("/{database_name}/disconnect-metadata", s.databaseHandler.GetDatabaseDisconnectMetadata).Methods(http.MethodGet)
+	databases.HandleFunc("/{database_name}/metrics", s.databaseHandler.GetDatabaseMetrics).Methods(http.MethodGet)
 	databases.HandleFunc("/{database_name}/schema", s.databaseHandler.GetLatestStoredDatabaseSchema).Methods(http.MethodGet)
+	databases.HandleFunc("/{database_name}/schema/snapshots", s.databaseHandler.ListDatabaseSchemaSnapshots).Methods(http.MethodGet)
+	databases.HandleFunc("/{database_name}/schema/diff", s.databaseHandler.DiffDatabaseSchemaSnapshots).Methods(http.MethodGet)
 	databases.HandleFunc("/{database_name}/wipe", s.databaseHandler.WipeDatabase).Methods(http.MethodPost)
 	databases.HandleFunc("/{database_name}/drop", s.databaseHandler.DropDatabase).Methods(http.MethodPost)
+	databases.HandleFunc("/{database_name}/query", s.databaseHandler.ExecuteQuery).Methods(http.MethodPost)
+	databases.HandleFunc("/{database_name}/command", s.databaseHandler.ExecuteCommand).Methods(http.MethodPost)
+	databases.HandleFunc("/{database_name}/convert-schema", s.databaseHandler.ConvertDatabaseSchema).Methods(http.MethodPost)
+	databases.HandleFunc("/{database_name}/plan-migration", s.databaseHandler.PlanDatabaseMigration).Methods(http.MethodPost)
 	databases.HandleFunc("/transform", s.databaseHandler.TransformData).Methods(http.MethodPost)
 	databases.HandleFunc("/clone-database", s.databaseHandler.CloneDatabase).Methods(http.MethodPost)
 
@@ -319,6 +374,7 @@ func (s *Server) setupRoutes() {
 	commits.HandleFunc("/{commit_code}/branch", s.commitHandler.BranchCommit).Methods(http.MethodPost)
 	commits.HandleFunc("/{commit_code}/merge", s.commitHandler.MergeCommit).Methods(http.MethodPost)
 	commits.HandleFunc("/{commit_code}/deploy", s.commitHandler.DeployCommit).Methods(http.MethodPost)
+	commits.HandleFunc("/{commit_code}/validate", s.commitHandler.ValidateCommitSchema).Methods(http.MethodPost)
 
 	// Schema deployment endpoints (workspace-level for easier CLI access)
 	workspaces.HandleFunc("/{workspace_name}/commits/deploy-schema", s.commitHandler.DeployCommitSchema).Methods(http.MethodPost)
@@ -334,13 +390,22 @@ func (s *Server) setupRoutes() {
 	mappings.HandleFunc("/stream-to-table", s.mappingHandler.AddStreamToTableMapping).Methods(http.MethodPost)
 	mappings.HandleFunc("/table-to-stream", s.mappingHandler.AddTableToStreamMapping).Methods(http.MethodPost)
 	mappings.HandleFunc("/stream-to-stream", s.mappingHandler.AddStreamToStreamMapping).Methods(http.MethodPost)
+	mappings.HandleFunc("/import", s.mappingHandler.ImportMapping).Methods(http.MethodPost)
 	mappings.HandleFunc("/{mapping_name}", s.mappingHandler.ShowMapping).Methods(http.MethodGet)
 	mappings.HandleFunc("/{mapping_name}", s.mappingHandler.ModifyMapping).Methods(http.MethodPut)
 	mappings.HandleFunc("/{mapping_name}", s.mappingHandler.DeleteMapping).Methods(http.MethodDelete)
 	mappings.HandleFunc("/{mapping_name}/attach-rule", s.mappingHandler.AttachMappingRule).Methods(http.MethodPost)
 	mappings.HandleFunc("/{mapping_name}/detach-rule", s.mappingHandler.DetachMappingRule).Methods(http.MethodPost)
 	mappings.HandleFunc("/{mapping_name}/copy-data", s.mappingHandler.CopyMappingData).Methods(http.MethodPost)
+	mappings.HandleFunc("/{mapping_name}/plan", s.mappingHandler.PlanRun).Methods(http.MethodPost)
+	mappings.HandleFunc("/{mapping_name}/preview", s.mappingHandler.PreviewMapping).Methods(http.MethodPost)
 	mappings.HandleFunc("/{mapping_name}/validate", s.mappingHandler.ValidateMapping).Methods(http.MethodPost)
+	mappings.HandleFunc("/{mapping_name}/rematch", s.mappingHandler.RematchMapping).Methods(http.MethodPost)
+	mappings.HandleFunc("/{mapping_name}/refresh-suggestions", s.mappingHandler.RefreshMappingSuggestions).Methods(http.MethodPost)
+	mappings.HandleFunc("/{mapping_name}/match-report", s.mappingHandler.GetMappingMatchReport).Methods(http.MethodGet)
+	mappings.HandleFunc("/{mapping_name}/export", s.mappingHandler.ExportMapping).Methods(http.MethodGet)
+	mappings.HandleFunc("/{mapping_name}/index-recommendations", s.mappingHandler.GetMappingIndexRecommendations).Methods(http.MethodGet)
+	mappings.HandleFunc("/{mapping_name}/index-recommendations", s.mappingHandler.CreateMappingIndexes).Methods(http.MethodPost)
 
 	// Mapping rule operations within mappings
 	mappings.HandleFunc("/{mapping_name}/rules", s.mappingHandler.ListRulesInMapping).Methods(http.MethodGet)
@@ -352,9 +417,21 @@ func (s *Server) setupRoutes() {
 	mappingRules := workspaces.PathPrefix("/{workspace_name}/mapping-rules").Subrouter()
 	mappingRules.HandleFunc("", s.mappingHandler.ListMappingRules).Methods(http.MethodGet)
 	mappingRules.HandleFunc("", s.mappingHandler.AddMappingRule).Methods(http.MethodPost)
+	mappingRules.HandleFunc("/broken", s.mappingHandler.ListBrokenMappingRules).Methods(http.MethodGet)
 	mappingRules.HandleFunc("/{mapping_rule_name}", s.mappingHandler.ShowMappingRule).Methods(http.MethodGet)
 	mappingRules.HandleFunc("/{mapping_rule_name}", s.mappingHandler.ModifyMappingRule).Methods(http.MethodPut)
 	mappingRules.HandleFunc("/{mapping_rule_name}", s.mappingHandler.DeleteMappingRule).Methods(http.MethodDelete)
+	mappingRules.HandleFunc("/{mapping_rule_name}/impact", s.mappingHandler.AnalyzeMappingRuleImpact).Methods(http.MethodPost)
+	mappingRules.HandleFunc("/{mapping_rule_name}/repair", s.mappingHandler.RepairMappingRule).Methods(http.MethodPost)
+
+	// Match profile endpoints (workspace-level)
+	matchProfiles := workspaces.PathPrefix("/{workspace_name}/match-profiles").Subrouter()
+	matchProfiles.HandleFunc("", s.mappingHandler.ListMatchProfiles).Methods(http.MethodGet)
+	matchProfiles.HandleFunc("/{profile_name}", s.mappingHandler.SetMatchProfile).Methods(http.MethodPut)
+	matchProfiles.HandleFunc("/{profile_name}", s.mappingHandler.DeleteMatchProfile).Methods(http.MethodDelete)
+	workspaces.HandleFunc("/{workspace_name}/default-match-profile", s.mappingHandler.SetDefaultMatchProfile).Methods(http.MethodPut)
+	workspaces.HandleFunc("/{workspace_name}/match-feedback", s.mappingHandler.ListMatchFeedback).Methods(http.MethodGet)
+	workspaces.HandleFunc("/{workspace_name}/match-feedback", s.mappingHandler.ResetMatchFeedback).Methods(http.MethodDelete)
 
 	// MCP Server endpoints (workspace-level)
 	mcpservers := workspaces.PathPrefix("/{workspace_name}/mcpservers").Subrouter()
@@ -397,6 +474,28 @@ func (s *Server) setupRoutes() {
 	relationships.HandleFunc("/{relationship_name}/resume", relationshipOps.ResumeRelationship).Methods(http.MethodPost)
 	relationships.HandleFunc("/{relationship_name}/remove", relationshipOps.RemoveRelationship).Methods(http.MethodDelete)
 
+	// Cutover endpoints (workspace-level)
+	cutovers := workspaces.PathPrefix("/{workspace_name}/cutovers").Subrouter()
+	cutovers.HandleFunc("", s.cutoverHandler.ListCutovers).Methods(http.MethodGet)
+	cutovers.HandleFunc("", s.cutoverHandler.AddCutover).Methods(http.MethodPost)
+	cutovers.HandleFunc("/{cutover_name}", s.cutoverHandler.ShowCutover).Methods(http.MethodGet)
+	cutovers.HandleFunc("/{cutover_name}", s.cutoverHandler.DeleteCutover).Methods(http.MethodDelete)
+	cutovers.HandleFunc("/{cutover_name}/start", s.cutoverHandler.StartCutover).Methods(http.MethodPost)
+	cutovers.HandleFunc("/{cutover_name}/approve", s.cutoverHandler.ApproveCutover).Methods(http.MethodPost)
+	cutovers.HandleFunc("/{cutover_name}/retry", s.cutoverHandler.RetryCutover).Methods(http.MethodPost)
+	cutovers.HandleFunc("/{cutover_name}/abort", s.cutoverHandler.AbortCutover).Methods(http.MethodPost)
+	workspaces.HandleFunc("/{workspace_name}/application-connections", s.cutoverHandler.ListApplicationConnections).Methods(http.MethodGet)
+
+	// Query proxy endpoints (workspace-level)
+	queryProxies := workspaces.PathPrefix("/{workspace_name}/query-proxies").Subrouter()
+	queryProxies.HandleFunc("", s.queryProxyHandler.ListQueryProxies).Methods(http.MethodGet)
+	queryProxies.HandleFunc("", s.queryProxyHandler.AddQueryProxy).Methods(http.MethodPost)
+	queryProxies.HandleFunc("/{proxy_name}", s.queryProxyHandler.ShowQueryProxy).Methods(http.MethodGet)
+	queryProxies.HandleFunc("/{proxy_name}", s.queryProxyHandler.DeleteQueryProxy).Methods(http.MethodDelete)
+	queryProxies.HandleFunc("/{proxy_name}/start", s.queryProxyHandler.StartQueryProxy).Methods(http.MethodPost)
+	queryProxies.HandleFunc("/{proxy_name}/stop", s.queryProxyHandler.StopQueryProxy).Methods(http.MethodPost)
+	queryProxies.HandleFunc("/{proxy_name}/routes", s.queryProxyHandler.UpdateQueryProxyRoutes).Methods(http.MethodPut)
+
 	// Resource endpoints (workspace-level)
 	resources := workspaces.PathPrefix("/{workspace_name}/resources").Subrouter()
 	resources.HandleFunc("/containers", s.resourceHandler.ListResourceContainers).Methods(http.MethodGet)
@@ -413,6 +512,12 @@ func (s *Server) setupRoutes() {
 	dataproducts.HandleFunc("/{product_name}", s.dataProductHandler.ShowDataProduct).Methods(http.MethodGet)
 	dataproducts.HandleFunc("/{product_name}", s.dataProductHandler.ModifyDataProduct).Methods(http.MethodPut)
 	dataproducts.HandleFunc("/{product_name}", s.dataProductHandler.DeleteDataProduct).Methods(http.MethodDelete)
+
+	// Artifact endpoints (workspace-level)
+	artifacts := workspaces.PathPrefix("/{workspace_name}/artifacts").Subrouter()
+	artifacts.HandleFunc("", s.artifactHandler.ListArtifacts).Methods(http.MethodGet)
+	artifacts.HandleFunc("/{artifact_id}/url", s.artifactHandler.GetArtifactURL).Methods(http.MethodGet)
+	artifacts.HandleFunc("/{artifact_id}", s.artifactHandler.DeleteArtifact).Methods(http.MethodDelete)
 }
 
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+)
+
+// handleSupportMatrix handles GET /api/v1/support-matrix
+func (s *Server) handleSupportMatrix(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dbcapabilities.GetSupportMatrix())
+}
+
+// handleDatabaseSupport handles GET /api/v1/support-matrix/{database}
+func (s *Server) handleDatabaseSupport(w http.ResponseWriter, r *http.Request) {
+	database := mux.Vars(r)["database"]
+
+	w.Header().Set("Content-Type", "application/json")
+	var support dbcapabilities.AdapterSupport
+	id, ok := dbcapabilities.ParseID(database)
+	if ok {
+		support, ok = dbcapabilities.GetSupport(id)
+	}
+	if !ok {
+		response := ErrorResponse{
+			Error:   "database type not found",
+			Message: "no adapter for database type " + database,
+			Status:  StatusError,
+		}
+		response.localize(w)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(support)
+}
@@ -540,6 +540,8 @@ func (th *TenantHandlers) writeErrorResponse(w http.ResponseWriter, statusCode i
 		Message: message,
 		Status:  StatusFailure,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -452,5 +452,7 @@ func (th *TransformationHandlers) writeErrorResponse(w http.ResponseWriter, stat
 		Message: details,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	th.writeJSONResponse(w, statusCode, response)
 }
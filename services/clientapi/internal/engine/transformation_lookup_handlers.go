@@ -0,0 +1,313 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+)
+
+// ListLookupTables handles GET /{tenant_url}/api/v1/transformations/lookup-tables
+func (th *TransformationHandlers) ListLookupTables(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.ListLookupTables(ctx, &corev1.ListLookupTablesRequest{
+		TenantId: profile.TenantId,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to list lookup tables")
+		return
+	}
+
+	tables := make([]LookupTable, len(grpcResp.LookupTables))
+	for i, table := range grpcResp.LookupTables {
+		tables[i] = lookupTableFromProto(table)
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, ListLookupTablesResponse{LookupTables: tables})
+}
+
+// ShowLookupTable handles GET /{tenant_url}/api/v1/transformations/lookup-tables/{lookup_table_name}
+func (th *TransformationHandlers) ShowLookupTable(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	tableName := mux.Vars(r)["lookup_table_name"]
+	if tableName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "lookup_table_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.ShowLookupTable(ctx, &corev1.ShowLookupTableRequest{
+		TenantId:        profile.TenantId,
+		LookupTableName: tableName,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to show lookup table")
+		return
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, ShowLookupTableResponse{LookupTable: lookupTableFromProto(grpcResp.LookupTable)})
+}
+
+// AddLookupTable handles POST /{tenant_url}/api/v1/transformations/lookup-tables
+func (th *TransformationHandlers) AddLookupTable(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req AddLookupTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if th.engine.logger != nil {
+			th.engine.logger.Errorf("Failed to parse add lookup table request body: %v", err)
+		}
+		th.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if req.LookupTableName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "lookup_table_name is required")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Add lookup table request for table: %s, tenant: %s, user: %s", req.LookupTableName, profile.TenantId, profile.UserId)
+	}
+
+	// Loading the entries of a source table reads the whole table
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	grpcReq := &corev1.AddLookupTableRequest{
+		TenantId:               profile.TenantId,
+		OwnerId:                profile.UserId,
+		LookupTableName:        req.LookupTableName,
+		LookupTableDescription: req.LookupTableDescription,
+		Entries:                req.Entries,
+		CacheTtlSeconds:        req.CacheTTLSeconds,
+	}
+	if req.Source != nil {
+		grpcReq.Source = &corev1.LookupTableSource{
+			WorkspaceName: req.Source.WorkspaceName,
+			DatabaseName:  req.Source.DatabaseName,
+			TableName:     req.Source.TableName,
+			KeyColumn:     req.Source.KeyColumn,
+			ValueColumn:   req.Source.ValueColumn,
+		}
+	}
+
+	grpcResp, err := th.engine.transformationClient.AddLookupTable(ctx, grpcReq)
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to add lookup table")
+		return
+	}
+
+	response := AddLookupTableResponse{
+		Message:     grpcResp.Message,
+		Success:     grpcResp.Success,
+		LookupTable: lookupTableFromProto(grpcResp.LookupTable),
+		Status:      convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// ModifyLookupTable handles PUT /{tenant_url}/api/v1/transformations/lookup-tables/{lookup_table_name}
+func (th *TransformationHandlers) ModifyLookupTable(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	tableName := mux.Vars(r)["lookup_table_name"]
+	if tableName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "lookup_table_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	var req ModifyLookupTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if th.engine.logger != nil {
+			th.engine.logger.Errorf("Failed to parse modify lookup table request body: %v", err)
+		}
+		th.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Modify lookup table request for table: %s, tenant: %s, user: %s", tableName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.ModifyLookupTable(ctx, &corev1.ModifyLookupTableRequest{
+		TenantId:               profile.TenantId,
+		LookupTableName:        tableName,
+		LookupTableDescription: req.LookupTableDescription,
+		SetEntries:             req.SetEntries,
+		RemoveKeys:             req.RemoveKeys,
+		ReplaceEntries:         req.ReplaceEntries,
+		CacheTtlSeconds:        req.CacheTTLSeconds,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to modify lookup table")
+		return
+	}
+
+	response := ModifyLookupTableResponse{
+		Message:     grpcResp.Message,
+		Success:     grpcResp.Success,
+		LookupTable: lookupTableFromProto(grpcResp.LookupTable),
+		Status:      convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// DeleteLookupTable handles DELETE /{tenant_url}/api/v1/transformations/lookup-tables/{lookup_table_name}
+func (th *TransformationHandlers) DeleteLookupTable(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	tableName := mux.Vars(r)["lookup_table_name"]
+	if tableName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "lookup_table_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Delete lookup table request for table: %s, tenant: %s, user: %s", tableName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.DeleteLookupTable(ctx, &corev1.DeleteLookupTableRequest{
+		TenantId:        profile.TenantId,
+		LookupTableName: tableName,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to delete lookup table")
+		return
+	}
+
+	response := DeleteLookupTableResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// RefreshLookupTable handles POST /{tenant_url}/api/v1/transformations/lookup-tables/{lookup_table_name}/refresh
+func (th *TransformationHandlers) RefreshLookupTable(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	tableName := mux.Vars(r)["lookup_table_name"]
+	if tableName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "lookup_table_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Refresh lookup table request for table: %s, tenant: %s, user: %s", tableName, profile.TenantId, profile.UserId)
+	}
+
+	// Reloading the entries of a source table reads the whole table
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.RefreshLookupTable(ctx, &corev1.RefreshLookupTableRequest{
+		TenantId:        profile.TenantId,
+		LookupTableName: tableName,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to refresh lookup table")
+		return
+	}
+
+	response := RefreshLookupTableResponse{
+		Message:     grpcResp.Message,
+		Success:     grpcResp.Success,
+		LookupTable: lookupTableFromProto(grpcResp.LookupTable),
+		Status:      convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// lookupTableFromProto converts a lookup table to its REST form
+func lookupTableFromProto(table *corev1.LookupTable) LookupTable {
+	if table == nil {
+		return LookupTable{}
+	}
+	restTable := LookupTable{
+		TenantID:               table.TenantId,
+		LookupTableID:          table.LookupTableId,
+		LookupTableName:        table.LookupTableName,
+		LookupTableDescription: table.LookupTableDescription,
+		Entries:                table.Entries,
+		EntryCount:             table.EntryCount,
+		CacheTTLSeconds:        table.CacheTtlSeconds,
+		Refreshed:              table.Refreshed,
+		OwnerID:                table.OwnerId,
+	}
+	if table.Source != nil {
+		restTable.Source = &LookupTableSource{
+			WorkspaceName: table.Source.WorkspaceName,
+			DatabaseName:  table.Source.DatabaseName,
+			TableName:     table.Source.TableName,
+			KeyColumn:     table.Source.KeyColumn,
+			ValueColumn:   table.Source.ValueColumn,
+			DatabaseID:    table.Source.DatabaseId,
+		}
+	}
+	return restTable
+}
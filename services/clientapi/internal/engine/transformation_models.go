@@ -57,3 +57,123 @@ type DeleteTransformationResponse struct {
 	Success bool   `json:"success"`
 	Status  Status `json:"status"`
 }
+
+// TransformationPlugin is a user-defined transformation served by an external gRPC endpoint
+type TransformationPlugin struct {
+	TenantID           string `json:"tenant_id"`
+	PluginID           string `json:"plugin_id"`
+	PluginName         string `json:"plugin_name"`
+	PluginDescription  string `json:"plugin_description,omitempty"`
+	PluginKind         string `json:"plugin_kind"`
+	Endpoint           string `json:"endpoint,omitempty"`
+	TransformationType string `json:"transformation_type"`
+	InputType          string `json:"input_type,omitempty"`
+	OutputType         string `json:"output_type,omitempty"`
+	Cardinality        string `json:"cardinality"`
+	TimeoutSeconds     int32  `json:"timeout_seconds"`
+	OwnerID            string `json:"owner_id,omitempty"`
+}
+
+type RegisterTransformationPluginRequest struct {
+	PluginName         string `json:"plugin_name" validate:"required"`
+	PluginDescription  string `json:"plugin_description,omitempty"`
+	PluginKind         string `json:"plugin_kind,omitempty"` // "grpc" (default) or "wasm"
+	Endpoint           string `json:"endpoint,omitempty"`    // host:port of a gRPC plugin
+	WasmModule         []byte `json:"wasm_module,omitempty"` // base64, for WASM plugins
+	TransformationType string `json:"transformation_type,omitempty"`
+	InputType          string `json:"input_type,omitempty"`
+	OutputType         string `json:"output_type,omitempty"`
+	TimeoutSeconds     int32  `json:"timeout_seconds,omitempty"`
+}
+
+type RegisterTransformationPluginResponse struct {
+	Message string               `json:"message"`
+	Success bool                 `json:"success"`
+	Plugin  TransformationPlugin `json:"plugin"`
+	Status  Status               `json:"status"`
+}
+
+type ListTransformationPluginsResponse struct {
+	Plugins []TransformationPlugin `json:"plugins"`
+}
+
+type DeleteTransformationPluginResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
+
+// LookupTableSource is the database table the entries of a lookup table are loaded from
+type LookupTableSource struct {
+	WorkspaceName string `json:"workspace_name"`
+	DatabaseName  string `json:"database_name"`
+	TableName     string `json:"table_name"`
+	KeyColumn     string `json:"key_column"`
+	ValueColumn   string `json:"value_column"`
+	DatabaseID    string `json:"database_id,omitempty"`
+}
+
+// LookupTable is the reference data the lookup transformation maps values through
+type LookupTable struct {
+	TenantID               string             `json:"tenant_id"`
+	LookupTableID          string             `json:"lookup_table_id"`
+	LookupTableName        string             `json:"lookup_table_name"`
+	LookupTableDescription string             `json:"lookup_table_description,omitempty"`
+	Entries                map[string]string  `json:"entries,omitempty"`
+	EntryCount             int32              `json:"entry_count"`
+	Source                 *LookupTableSource `json:"source,omitempty"`
+	CacheTTLSeconds        int32              `json:"cache_ttl_seconds"`
+	Refreshed              string             `json:"refreshed,omitempty"`
+	OwnerID                string             `json:"owner_id,omitempty"`
+}
+
+type ListLookupTablesResponse struct {
+	LookupTables []LookupTable `json:"lookup_tables"`
+}
+
+type ShowLookupTableResponse struct {
+	LookupTable LookupTable `json:"lookup_table"`
+}
+
+type AddLookupTableRequest struct {
+	LookupTableName        string             `json:"lookup_table_name" validate:"required"`
+	LookupTableDescription string             `json:"lookup_table_description,omitempty"`
+	Entries                map[string]string  `json:"entries,omitempty"`
+	Source                 *LookupTableSource `json:"source,omitempty"` // Instead of entries
+	CacheTTLSeconds        *int32             `json:"cache_ttl_seconds,omitempty"`
+}
+
+type AddLookupTableResponse struct {
+	Message     string      `json:"message"`
+	Success     bool        `json:"success"`
+	LookupTable LookupTable `json:"lookup_table"`
+	Status      Status      `json:"status"`
+}
+
+type ModifyLookupTableRequest struct {
+	LookupTableDescription *string           `json:"lookup_table_description,omitempty"`
+	SetEntries             map[string]string `json:"set_entries,omitempty"`
+	RemoveKeys             []string          `json:"remove_keys,omitempty"`
+	ReplaceEntries         bool              `json:"replace_entries,omitempty"`
+	CacheTTLSeconds        *int32            `json:"cache_ttl_seconds,omitempty"`
+}
+
+type ModifyLookupTableResponse struct {
+	Message     string      `json:"message"`
+	Success     bool        `json:"success"`
+	LookupTable LookupTable `json:"lookup_table"`
+	Status      Status      `json:"status"`
+}
+
+type DeleteLookupTableResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+	Status  Status `json:"status"`
+}
+
+type RefreshLookupTableResponse struct {
+	Message     string      `json:"message"`
+	Success     bool        `json:"success"`
+	LookupTable LookupTable `json:"lookup_table"`
+	Status      Status      `json:"status"`
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	securityv1 "github.com/redbco/redb-open/api/proto/security/v1"
+)
+
+// RegisterTransformationPlugin handles POST /{tenant_url}/api/v1/transformations/plugins
+func (th *TransformationHandlers) RegisterTransformationPlugin(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	// Get tenant_id from authenticated profile
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	// Parse request body
+	var req RegisterTransformationPluginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if th.engine.logger != nil {
+			th.engine.logger.Errorf("Failed to parse register transformation plugin request body: %v", err)
+		}
+		th.writeErrorResponse(w, http.StatusBadRequest, "Invalid request body", "")
+		return
+	}
+
+	if req.PluginName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "Required fields missing", "plugin_name is required")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Register transformation plugin request for plugin: %s, tenant: %s, user: %s", req.PluginName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.RegisterTransformationPlugin(ctx, &corev1.RegisterTransformationPluginRequest{
+		TenantId:           profile.TenantId,
+		OwnerId:            profile.UserId,
+		PluginName:         req.PluginName,
+		PluginDescription:  req.PluginDescription,
+		PluginKind:         req.PluginKind,
+		Endpoint:           req.Endpoint,
+		WasmModule:         req.WasmModule,
+		TransformationType: req.TransformationType,
+		InputType:          req.InputType,
+		OutputType:         req.OutputType,
+		TimeoutSeconds:     req.TimeoutSeconds,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to register transformation plugin")
+		return
+	}
+
+	response := RegisterTransformationPluginResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Plugin:  transformationPluginFromProto(grpcResp.Plugin),
+		Status:  convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusCreated, response)
+}
+
+// ListTransformationPlugins handles GET /{tenant_url}/api/v1/transformations/plugins
+func (th *TransformationHandlers) ListTransformationPlugins(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.ListTransformationPlugins(ctx, &corev1.ListTransformationPluginsRequest{
+		TenantId: profile.TenantId,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to list transformation plugins")
+		return
+	}
+
+	plugins := make([]TransformationPlugin, len(grpcResp.Plugins))
+	for i, plugin := range grpcResp.Plugins {
+		plugins[i] = transformationPluginFromProto(plugin)
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, ListTransformationPluginsResponse{Plugins: plugins})
+}
+
+// DeleteTransformationPlugin handles DELETE /{tenant_url}/api/v1/transformations/plugins/{plugin_name}
+func (th *TransformationHandlers) DeleteTransformationPlugin(w http.ResponseWriter, r *http.Request) {
+	th.engine.TrackOperation()
+	defer th.engine.UntrackOperation()
+
+	pluginName := mux.Vars(r)["plugin_name"]
+	if pluginName == "" {
+		th.writeErrorResponse(w, http.StatusBadRequest, "plugin_name is required", "")
+		return
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		th.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return
+	}
+
+	if th.engine.logger != nil {
+		th.engine.logger.Infof("Delete transformation plugin request for plugin: %s, tenant: %s, user: %s", pluginName, profile.TenantId, profile.UserId)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := th.engine.transformationClient.DeleteTransformationPlugin(ctx, &corev1.DeleteTransformationPluginRequest{
+		TenantId:   profile.TenantId,
+		PluginName: pluginName,
+	})
+	if err != nil {
+		th.handleGRPCError(w, err, "Failed to delete transformation plugin")
+		return
+	}
+
+	response := DeleteTransformationPluginResponse{
+		Message: grpcResp.Message,
+		Success: grpcResp.Success,
+		Status:  convertStatus(grpcResp.Status),
+	}
+
+	th.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// transformationPluginFromProto converts a transformation plugin to its REST form
+func transformationPluginFromProto(plugin *corev1.TransformationPlugin) TransformationPlugin {
+	if plugin == nil {
+		return TransformationPlugin{}
+	}
+	return TransformationPlugin{
+		TenantID:           plugin.TenantId,
+		PluginID:           plugin.PluginId,
+		PluginName:         plugin.PluginName,
+		PluginDescription:  plugin.PluginDescription,
+		PluginKind:         plugin.PluginKind,
+		Endpoint:           plugin.Endpoint,
+		TransformationType: plugin.TransformationType,
+		InputType:          plugin.InputType,
+		OutputType:         plugin.OutputType,
+		Cardinality:        plugin.Cardinality,
+		TimeoutSeconds:     plugin.TimeoutSeconds,
+		OwnerID:            plugin.OwnerId,
+	}
+}
@@ -471,6 +471,8 @@ func (uh *UserHandlers) writeErrorResponse(w http.ResponseWriter, statusCode int
 		Message: message,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -405,6 +405,95 @@ func (wh *WorkspaceHandlers) DeleteWorkspace(w http.ResponseWriter, r *http.Requ
 	wh.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// GetWorkspaceHealth handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/health
+func (wh *WorkspaceHandlers) GetWorkspaceHealth(w http.ResponseWriter, r *http.Request) {
+	wh.engine.TrackOperation()
+	defer wh.engine.UntrackOperation()
+
+	health, ok := wh.workspaceHealth(w, r)
+	if !ok {
+		return
+	}
+	wh.writeJSONResponse(w, http.StatusOK, health)
+}
+
+// GetWorkspaceReadiness handles GET /{tenant_url}/api/v1/workspaces/{workspace_name}/ready.
+// It answers 503 while a health check fails, so that it can back readiness probes.
+func (wh *WorkspaceHandlers) GetWorkspaceReadiness(w http.ResponseWriter, r *http.Request) {
+	wh.engine.TrackOperation()
+	defer wh.engine.UntrackOperation()
+
+	health, ok := wh.workspaceHealth(w, r)
+	if !ok {
+		return
+	}
+
+	response := WorkspaceReadinessResponse{
+		WorkspaceName: health.WorkspaceName,
+		Ready:         health.Ready,
+	}
+	for _, check := range health.Checks {
+		if check.Status == "fail" {
+			response.FailingChecks = append(response.FailingChecks, check.Name)
+		}
+	}
+
+	statusCode := http.StatusOK
+	if !health.Ready {
+		statusCode = http.StatusServiceUnavailable
+	}
+	wh.writeJSONResponse(w, statusCode, response)
+}
+
+// workspaceHealth gets the health of the workspace of the request from the core service, or
+// writes the error response
+func (wh *WorkspaceHandlers) workspaceHealth(w http.ResponseWriter, r *http.Request) (*WorkspaceHealth, bool) {
+	vars := mux.Vars(r)
+	workspaceName := vars["workspace_name"]
+	if workspaceName == "" {
+		wh.writeErrorResponse(w, http.StatusBadRequest, "workspace_name is required", "")
+		return nil, false
+	}
+
+	profile, ok := r.Context().Value(profileContextKey).(*securityv1.Profile)
+	if !ok || profile == nil {
+		wh.writeErrorResponse(w, http.StatusInternalServerError, "Profile not found in context", "")
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	grpcResp, err := wh.engine.workspaceClient.GetWorkspaceHealth(ctx, &corev1.GetWorkspaceHealthRequest{
+		TenantId:      profile.TenantId,
+		WorkspaceName: workspaceName,
+	})
+	if err != nil {
+		wh.handleGRPCError(w, err, "Failed to get workspace health")
+		return nil, false
+	}
+
+	health := &WorkspaceHealth{
+		WorkspaceName: grpcResp.WorkspaceName,
+		Score:         grpcResp.Score,
+		Status:        grpcResp.HealthStatus,
+		Ready:         grpcResp.Ready,
+		Checks:        make([]WorkspaceHealthCheck, len(grpcResp.Checks)),
+		Computed:      grpcResp.Computed,
+	}
+	for i, check := range grpcResp.Checks {
+		health.Checks[i] = WorkspaceHealthCheck{
+			Name:    check.Name,
+			Status:  check.Status,
+			Score:   check.Score,
+			Weight:  check.Weight,
+			Message: check.Message,
+			Items:   check.Items,
+		}
+	}
+	return health, true
+}
+
 // Helper methods
 
 // handleGRPCError handles gRPC errors and converts them to HTTP responses
@@ -468,6 +557,8 @@ func (wh *WorkspaceHandlers) writeErrorResponse(w http.ResponseWriter, statusCod
 		Message: message,
 		Status:  StatusError,
 	}
+	response.attachErrorCode(statusCode)
+	response.localize(w)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
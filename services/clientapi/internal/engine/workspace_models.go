@@ -75,3 +75,30 @@ type DeleteWorkspaceResponse struct {
 	Success bool   `json:"success"`
 	Status  Status `json:"status"`
 }
+
+// WorkspaceHealthCheck represents one factor of the health score of a workspace
+type WorkspaceHealthCheck struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Score   int32    `json:"score"`
+	Weight  int32    `json:"weight"`
+	Message string   `json:"message"`
+	Items   []string `json:"items,omitempty"`
+}
+
+// WorkspaceHealth represents the workspace health response
+type WorkspaceHealth struct {
+	WorkspaceName string                 `json:"workspace_name"`
+	Score         int32                  `json:"score"`
+	Status        string                 `json:"status"`
+	Ready         bool                   `json:"ready"`
+	Checks        []WorkspaceHealthCheck `json:"checks"`
+	Computed      string                 `json:"computed"`
+}
+
+// WorkspaceReadinessResponse represents the workspace readiness response
+type WorkspaceReadinessResponse struct {
+	WorkspaceName string   `json:"workspace_name"`
+	Ready         bool     `json:"ready"`
+	FailingChecks []string `json:"failing_checks,omitempty"`
+}
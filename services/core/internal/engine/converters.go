@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
@@ -29,9 +30,9 @@ import (
 // Helper function to convert policy to protobuf
 func (s *Server) policyToProto(p *policy.Policy) (*corev1.Policy, error) {
 	// Parse JSON object into protobuf Struct
-	policyStruct, err := structpb.NewStruct(p.Conditions)
+	policyStruct, err := structpb.NewStruct(p.Object)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert policy conditions to struct: %w", err)
+		return nil, fmt.Errorf("failed to convert policy object to struct: %w", err)
 	}
 
 	return &corev1.Policy{
@@ -154,6 +155,11 @@ func (s *Server) instanceToProto(inst *instance.Instance) *corev1.Instance {
 		sslRootCert = *inst.SSLRootCert
 	}
 
+	socketPath := ""
+	if inst.SocketPath != nil {
+		socketPath = *inst.SocketPath
+	}
+
 	// Convert metadata map to protobuf Struct
 	var metadataStruct *structpb.Struct
 	if len(inst.Metadata) > 0 {
@@ -195,6 +201,90 @@ func (s *Server) instanceToProto(inst *instance.Instance) *corev1.Instance {
 		Status:                   statusStringToProto(inst.Status),
 		Created:                  inst.Created.Format("2006-01-02T15:04:05Z"),
 		Updated:                  inst.Updated.Format("2006-01-02T15:04:05Z"),
+		InstanceSshTunnel:        sshTunnelToProto(inst.SSHTunnel),
+		InstanceIamAuth:          iamAuthToProto(inst.IAMAuth),
+		InstanceSocketPath:       socketPath,
+		InstanceKerberosAuth:     kerberosAuthToProto(inst.KerberosAuth),
+	}
+}
+
+// kerberosAuthToProto converts the Kerberos authentication of an instance to protobuf
+func kerberosAuthToProto(auth *instance.KerberosAuth) *corev1.InstanceKerberosAuth {
+	if auth == nil {
+		return nil
+	}
+	return &corev1.InstanceKerberosAuth{
+		Principal: auth.Principal,
+		Realm:     auth.Realm,
+		Keytab:    auth.Keytab,
+		Ccache:    auth.CCache,
+		Krb5Conf:  auth.Krb5Conf,
+		Spn:       auth.SPN,
+	}
+}
+
+// kerberosAuthFromProto converts a Kerberos authentication request to the instance model
+func kerberosAuthFromProto(auth *corev1.InstanceKerberosAuth) instance.KerberosAuth {
+	return instance.KerberosAuth{
+		Principal: auth.Principal,
+		Realm:     auth.Realm,
+		Keytab:    auth.Keytab,
+		CCache:    auth.Ccache,
+		Krb5Conf:  auth.Krb5Conf,
+		SPN:       auth.Spn,
+	}
+}
+
+// iamAuthToProto converts the IAM authentication of an instance to protobuf
+func iamAuthToProto(auth *instance.IAMAuth) *corev1.InstanceIAMAuth {
+	if auth == nil {
+		return nil
+	}
+	return &corev1.InstanceIAMAuth{
+		Provider: auth.Provider,
+		Region:   auth.Region,
+		Scope:    auth.Scope,
+		ClientId: auth.ClientID,
+	}
+}
+
+// iamAuthFromProto converts an IAM authentication request to the instance model
+func iamAuthFromProto(auth *corev1.InstanceIAMAuth) instance.IAMAuth {
+	return instance.IAMAuth{
+		Provider: auth.Provider,
+		Region:   auth.Region,
+		Scope:    auth.Scope,
+		ClientID: auth.ClientId,
+	}
+}
+
+// sshTunnelToProto converts the SSH tunnel of an instance to protobuf, leaving out its secrets
+func sshTunnelToProto(tunnel *instance.SSHTunnel) *corev1.InstanceSSHTunnel {
+	if tunnel == nil {
+		return nil
+	}
+	return &corev1.InstanceSSHTunnel{
+		Host:                     tunnel.Host,
+		Port:                     int32(tunnel.Port),
+		Username:                 tunnel.Username,
+		UseAgent:                 tunnel.UseAgent,
+		HostKey:                  tunnel.HostKey,
+		InsecureSkipHostKeyCheck: tunnel.InsecureSkipHostKeyCheck,
+	}
+}
+
+// sshTunnelFromProto converts an SSH tunnel request to the instance model
+func sshTunnelFromProto(tunnel *corev1.InstanceSSHTunnel) instance.SSHTunnel {
+	return instance.SSHTunnel{
+		Host:                     tunnel.Host,
+		Port:                     int(tunnel.Port),
+		Username:                 tunnel.Username,
+		PrivateKey:               tunnel.PrivateKey,
+		PrivateKeyPassphrase:     tunnel.PrivateKeyPassphrase,
+		Password:                 tunnel.Password,
+		UseAgent:                 tunnel.UseAgent,
+		HostKey:                  tunnel.HostKey,
+		InsecureSkipHostKeyCheck: tunnel.InsecureSkipHostKeyCheck,
 	}
 }
 
@@ -237,6 +327,18 @@ func (s *Server) instanceToRecordData(inst *instance.Instance) map[string]interf
 	if inst.SSLRootCert != nil {
 		recordData["instance_ssl_root_cert"] = *inst.SSLRootCert
 	}
+	if inst.SSHTunnel != nil {
+		recordData["instance_ssh_tunnel"] = inst.SSHTunnel
+	}
+	if inst.IAMAuth != nil {
+		recordData["instance_iam_auth"] = inst.IAMAuth
+	}
+	if inst.SocketPath != nil {
+		recordData["instance_socket_path"] = *inst.SocketPath
+	}
+	if inst.KerberosAuth != nil {
+		recordData["instance_kerberos_auth"] = inst.KerberosAuth
+	}
 
 	return recordData
 }
@@ -311,6 +413,36 @@ func (s *Server) databaseToProto(db *database.Database) *corev1.Database {
 		InstanceStatusMessage: db.InstanceStatusMessage,
 		InstanceStatus:        db.InstanceStatus,
 		ResourceContainers:    protoContainers,
+		DatabaseReplicas:      replicasToProto(db.Replicas),
+		DatabaseRoutingPolicy: db.RoutingPolicy,
+		DatabaseRateLimits:    rateLimitsToProto(db.RateLimits),
+		DatabaseLabels:        db.Labels,
+	}
+}
+
+// replicasToProto converts the read replicas of a database to protobuf
+func replicasToProto(replicas []database.Replica) []*corev1.DatabaseReplica {
+	protoReplicas := make([]*corev1.DatabaseReplica, len(replicas))
+	for i, replica := range replicas {
+		protoReplicas[i] = &corev1.DatabaseReplica{
+			Host:     replica.Host,
+			Port:     int32(replica.Port),
+			Username: replica.Username,
+			Password: replica.Password,
+		}
+	}
+	return protoReplicas
+}
+
+// rateLimitsToProto converts the rate limits of a database to protobuf, nil when it is unlimited
+func rateLimitsToProto(limits *database.RateLimits) *corev1.DatabaseRateLimits {
+	if limits.IsZero() {
+		return nil
+	}
+	return &corev1.DatabaseRateLimits{
+		RowsPerSecond:           limits.RowsPerSecond,
+		QueriesPerSecond:        limits.QueriesPerSecond,
+		MaxConcurrentStatements: int32(limits.MaxConcurrentStatements),
 	}
 }
 
@@ -331,6 +463,10 @@ func (s *Server) databaseToRecordData(db *database.Database) map[string]interfac
 		"database_password":       db.Password,
 		"database_db_name":        db.DBName,
 		"database_enabled":        db.Enabled,
+		"database_replicas":       db.Replicas,
+		"database_routing_policy": db.RoutingPolicy,
+		"database_rate_limits":    db.RateLimits,
+		"database_labels":         db.Labels,
 		"owner_id":                db.OwnerID,
 		"database_status_message": db.StatusMessage,
 		"status":                  db.Status,
@@ -590,6 +726,21 @@ func (s *Server) mappingToProtoWithContext(ctx context.Context, m *mapping.Mappi
 		}
 	}
 
+	// Convert conflict keys to protobuf, sorted by table for stable output
+	conflictKeys := m.ConflictKeys()
+	conflictTables := make([]string, 0, len(conflictKeys))
+	for table := range conflictKeys {
+		conflictTables = append(conflictTables, table)
+	}
+	sort.Strings(conflictTables)
+	protoConflictKeys := make([]*corev1.MappingConflictKey, len(conflictTables))
+	for i, table := range conflictTables {
+		protoConflictKeys[i] = &corev1.MappingConflictKey{
+			TargetTable: table,
+			Columns:     conflictKeys[table],
+		}
+	}
+
 	// Set container IDs if present
 	sourceContainerID := ""
 	if m.SourceContainerID != nil {
@@ -626,13 +777,16 @@ func (s *Server) mappingToProtoWithContext(ctx context.Context, m *mapping.Mappi
 		McpResourceNames:         mcpResourceNames,
 		McpToolNames:             mcpToolNames,
 		Filters:                  protoFilters,
+		ConflictKeys:             protoConflictKeys,
+		RowFilter:                m.RowFilter(),
+		TablePins:                tablePinsToProto(m.TablePins()),
 	}, nil
 }
 
 // Helper function to convert mapping rule to protobuf
 func (s *Server) mappingRuleToProto(m *mapping.Rule) (*corev1.MappingRule, error) {
 	// Extract values from metadata (backward compatibility)
-	var sourceURI, targetURI, transformationID, transformationName string
+	var sourceURI, targetURI, transformationID, transformationName, rowFilter string
 	var transformationOptions map[string]interface{}
 
 	if m.Metadata != nil {
@@ -648,6 +802,9 @@ func (s *Server) mappingRuleToProto(m *mapping.Rule) (*corev1.MappingRule, error
 		if v, ok := m.Metadata["transformation_options"].(map[string]interface{}); ok {
 			transformationOptions = v
 		}
+		if v, ok := m.Metadata["row_filter"].(string); ok {
+			rowFilter = v
+		}
 	}
 
 	// Convert transformation options to JSON string
@@ -672,7 +829,7 @@ func (s *Server) mappingRuleToProto(m *mapping.Rule) (*corev1.MappingRule, error
 		}
 	}
 
-	return &corev1.MappingRule{
+	protoRule := &corev1.MappingRule{
 		TenantId:                         m.TenantID,
 		WorkspaceId:                      m.WorkspaceID,
 		MappingRuleId:                    m.ID,
@@ -686,7 +843,16 @@ func (s *Server) mappingRuleToProto(m *mapping.Rule) (*corev1.MappingRule, error
 		MappingRuleMetadata:              metadataJSON,
 		OwnerId:                          m.OwnerID,
 		MappingCount:                     m.MappingCount,
-	}, nil
+		MappingRuleTransformationChain:   mapping.TransformationChain(transformationName),
+		MappingRuleRowFilter:             rowFilter,
+		RecommendedTransformation:        getString(m.Metadata, "recommended_transformation"),
+	}
+	if breakage := m.Breakage(); breakage != nil {
+		protoRule.Broken = true
+		protoRule.BrokenReason = breakage.Reason
+		protoRule.MissingObjects = breakage.Missing
+	}
+	return protoRule, nil
 }
 
 // mappingRuleToProtoWithItems converts a mapping rule to protobuf format and includes full item details in metadata
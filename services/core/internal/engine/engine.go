@@ -2,6 +2,8 @@ package engine
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"sync"
@@ -12,11 +14,16 @@ import (
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	meshv1 "github.com/redbco/redb-open/api/proto/mesh/v1"
 	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/blobstore"
 	"github.com/redbco/redb-open/pkg/config"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/logger"
 	"github.com/redbco/redb-open/services/core/internal/mesh"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
+	"github.com/redbco/redb-open/services/core/internal/services/cutover"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/retention"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 )
@@ -44,10 +51,15 @@ type Engine struct {
 	syncManager      *mesh.DatabaseSyncManager
 	nodeID           uint64
 
+	// Retention janitor
+	janitorInterval time.Duration
+	nextJanitorRun  atomic.Int64 // Unix time of the next run
+
 	state struct {
 		sync.Mutex
 		isRunning         bool
 		ongoingOperations int32
+		cutovers          *cutover.Runner // Set once the engine started
 	}
 	metrics struct {
 		requestsProcessed int64
@@ -119,6 +131,8 @@ func (e *Engine) RegisterCoreServices() error {
 	corev1.RegisterCommitServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterMappingServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterRelationshipServiceServer(e.grpcServer, e.coreSvc)
+	corev1.RegisterCutoverServiceServer(e.grpcServer, e.coreSvc)
+	corev1.RegisterQueryProxyServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterTransformationServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterPolicyServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterMCPServiceServer(e.grpcServer, e.coreSvc)
@@ -135,6 +149,8 @@ func (e *Engine) RegisterCoreServices() error {
 	corev1.RegisterImportExportServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterResourceServiceServer(e.grpcServer, e.coreSvc)
 	corev1.RegisterDataProductServiceServer(e.grpcServer, e.coreSvc)
+	corev1.RegisterArtifactServiceServer(e.grpcServer, e.coreSvc)
+	corev1.RegisterRetentionServiceServer(e.grpcServer, e.coreSvc)
 
 	return nil
 }
@@ -288,6 +304,26 @@ func (e *Engine) Start(ctx context.Context) error {
 
 	// Message handlers are automatically registered by the mesh manager
 
+	// Keep large artifacts in the blob store, if one is configured
+	if err := e.initializeBlobStore(); err != nil {
+		e.logger.Warnf("Failed to initialize blob store, keeping artifacts in the database: %v", err)
+	}
+
+	// Purge expired artifacts, integration run history and logs, and schema versions
+	if err := e.initializeRetention(); err != nil {
+		e.logger.Warnf("Invalid retention configuration, only tenant retention policies apply: %v", err)
+	}
+	go e.runJanitor(ctx)
+
+	// Run cutovers in the background, resuming those that were running when the service stopped
+	cutovers := cutover.NewRunner(ctx, cutover.NewService(e.db, e.logger), &cutoverExecutor{server: e.coreSvc}, e.logger)
+	e.state.Lock()
+	e.state.cutovers = cutovers
+	e.state.Unlock()
+	if err := cutovers.Resume(ctx); err != nil {
+		e.logger.Warnf("Failed to resume running cutovers: %v", err)
+	}
+
 	if e.logger != nil {
 		e.logger.Info("Core engine started successfully")
 	}
@@ -477,6 +513,13 @@ func (e *Engine) GetAnchorClient() anchorv1.AnchorServiceClient {
 	return e.anchorClient
 }
 
+// CutoverRunner returns the runner of cutovers, or nil until the engine started
+func (e *Engine) CutoverRunner() *cutover.Runner {
+	e.state.Lock()
+	defer e.state.Unlock()
+	return e.state.cutovers
+}
+
 func (e *Engine) GetMeshControlClient() meshv1.MeshControlClient {
 	return e.meshControlClient
 }
@@ -818,3 +861,176 @@ func (e *Engine) closeAllConnections() {
 		e.logger.Infof("All gRPC connections closed")
 	}
 }
+
+// defaultJanitorInterval is how often expired items are purged unless configured otherwise
+const defaultJanitorInterval = time.Hour
+
+// initializeBlobStore configures the blob store of artifacts from the services.core.blobstore
+// settings. Without a backend, schema snapshots stay in the database.
+func (e *Engine) initializeBlobStore() error {
+	if e.config == nil || e.config.Get("services.core.blobstore.backend") == "" {
+		return nil
+	}
+
+	cfg := blobstore.Config{
+		Backend:         e.config.Get("services.core.blobstore.backend"),
+		Path:            e.config.Get("services.core.blobstore.path"),
+		PublicURL:       e.config.Get("services.core.blobstore.public_url"),
+		URLSigningKey:   e.config.Get("services.core.blobstore.url_signing_key"),
+		Bucket:          e.config.Get("services.core.blobstore.bucket"),
+		Region:          e.config.Get("services.core.blobstore.region"),
+		Endpoint:        e.config.Get("services.core.blobstore.endpoint"),
+		AccessKeyID:     e.config.Get("services.core.blobstore.access_key_id"),
+		SecretAccessKey: e.config.Get("services.core.blobstore.secret_access_key"),
+		Prefix:          e.config.Get("services.core.blobstore.prefix"),
+	}
+	if v := e.config.Get("services.core.blobstore.path_style"); v != "" {
+		if pathStyle, err := strconv.ParseBool(v); err == nil {
+			cfg.PathStyle = pathStyle
+		}
+	}
+	if cfg.Backend == blobstore.BackendLocal && cfg.URLSigningKey == "" {
+		// Signed URLs then only stay valid until core restarts
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate URL signing key: %w", err)
+		}
+		cfg.URLSigningKey = hex.EncodeToString(key)
+		e.logger.Warnf("No services.core.blobstore.url_signing_key configured, artifact URLs expire when core restarts")
+	}
+
+	store, err := blobstore.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	retention := make(map[string]time.Duration)
+	for _, kind := range artifact.Kinds {
+		if v := e.config.Get("services.core.artifact_retention." + kind); v != "" {
+			keep, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid retention of %s artifacts %q: %w", kind, v, err)
+			}
+			retention[kind] = keep
+		}
+	}
+
+	inlineMaxBytes := 0
+	if v := e.config.Get("services.core.blobstore.inline_max_bytes"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			inlineMaxBytes = n
+		}
+	}
+
+	artifact.Configure(store, retention, inlineMaxBytes)
+	e.logger.Infof("Blob store for artifacts initialized (backend: %s)", cfg.Backend)
+	return nil
+}
+
+// initializeRetention configures the default retention policies of tenants from the
+// services.core.retention.<resource type>.keep_days and keep_versions settings, and the janitor
+// interval from services.core.retention.interval
+func (e *Engine) initializeRetention() error {
+	e.janitorInterval = defaultJanitorInterval
+	if e.config == nil {
+		return nil
+	}
+
+	if v := e.config.Get("services.core.retention.interval"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid janitor interval %q", v)
+		}
+		e.janitorInterval = interval
+	}
+
+	defaults := make(map[string]retention.Policy)
+	for _, resourceType := range retention.ResourceTypes {
+		var policy retention.Policy
+		for key, limit := range map[string]*int{"keep_days": &policy.KeepDays, "keep_versions": &policy.KeepVersions} {
+			v := e.config.Get("services.core.retention." + resourceType + "." + key)
+			if v == "" {
+				continue
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid %s of %s retention %q", key, resourceType, v)
+			}
+			*limit = n
+		}
+		defaults[resourceType] = policy
+	}
+	retention.Configure(defaults)
+	return nil
+}
+
+// copyParallelismBounds returns the bounds within which data copies adjust their parallelism,
+// from the services.core.copy.min_parallelism and max_parallelism settings
+func (e *Engine) copyParallelismBounds() mapping.ParallelismBounds {
+	bounds := mapping.ParallelismBounds{Min: mapping.DefaultMinParallelism, Max: mapping.DefaultMaxParallelism}
+	if e.config == nil {
+		return bounds
+	}
+	for key, limit := range map[string]*int{"min_parallelism": &bounds.Min, "max_parallelism": &bounds.Max} {
+		v := e.config.Get("services.core.copy." + key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			e.logger.Warnf("Invalid services.core.copy.%s %q, using %d", key, v, *limit)
+			continue
+		}
+		*limit = n
+	}
+	if bounds.Max < bounds.Min {
+		e.logger.Warnf("services.core.copy.max_parallelism %d is below min_parallelism %d, using %d", bounds.Max, bounds.Min, bounds.Min)
+		bounds.Max = bounds.Min
+	}
+	return bounds
+}
+
+// runJanitor purges expired items every janitor interval until the context is done
+func (e *Engine) runJanitor(ctx context.Context) {
+	ticker := time.NewTicker(e.janitorInterval)
+	defer ticker.Stop()
+	e.nextJanitorRun.Store(time.Now().Add(e.janitorInterval).Unix())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.nextJanitorRun.Store(time.Now().Add(e.janitorInterval).Unix())
+
+			if artifact.Store() != nil {
+				deleted, err := artifact.NewService(e.db, e.logger).ApplyRetention(ctx)
+				if err != nil {
+					e.logger.Warnf("Failed to apply artifact retention: %v", err)
+				}
+				if deleted > 0 {
+					e.logger.Infof("Deleted %d expired artifacts", deleted)
+				}
+			}
+
+			purged, err := retention.NewService(e.db, e.logger).Apply(ctx)
+			if err != nil {
+				e.logger.Warnf("Failed to apply retention policies: %v", err)
+			}
+			for resourceType, n := range purged {
+				if n > 0 {
+					e.logger.Infof("Purged %d expired items of %s", n, resourceType)
+				}
+			}
+		}
+	}
+}
+
+// NextJanitorRun returns when the janitor purges expired items next, or the zero time if it
+// is not running
+func (e *Engine) NextJanitorRun() time.Time {
+	if next := e.nextJanitorRun.Load(); next > 0 {
+		return time.Unix(next, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/policy"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+)
+
+// transformationPolicies are the transformation policies of a tenant, enforced on the mapping
+// rules that are created by hand or generated by the matcher
+type transformationPolicies struct {
+	requirements []*policy.TransformationRequirement
+	workspaces   map[string]string // Workspace IDs by the workspace names of the requirements
+	mappings     *mapping.Service
+}
+
+// loadTransformationPolicies loads the transformation policies of a tenant
+func (s *Server) loadTransformationPolicies(ctx context.Context, tenantID string) (*transformationPolicies, error) {
+	requirements, err := policy.NewService(s.engine.db, s.engine.logger).TransformationRequirements(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transformation policies: %w", err)
+	}
+
+	policies := &transformationPolicies{
+		requirements: requirements,
+		workspaces:   make(map[string]string),
+		mappings:     mapping.NewService(s.engine.db, s.engine.logger),
+	}
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+	for _, requirement := range requirements {
+		if requirement.Workspace == "" {
+			continue
+		}
+		if _, ok := policies.workspaces[requirement.Workspace]; ok {
+			continue
+		}
+		// A policy on a workspace that no longer exists applies to nothing
+		workspaceID, err := workspaceService.GetWorkspaceID(ctx, tenantID, requirement.Workspace)
+		if err != nil {
+			s.engine.logger.Warnf("Workspace %s of policy %s not found: %v", requirement.Workspace, requirement.PolicyName, err)
+		}
+		policies.workspaces[requirement.Workspace] = workspaceID
+	}
+	return policies, nil
+}
+
+// enforce returns the transformation and options of a mapping rule from the source to the target
+// URIs under the policies. When a policy applies, its ID is recorded in the metadata of the rule.
+// A rule of privileged data that no policy or user gave a transformation is recommended a
+// masking transformation.
+func (p *transformationPolicies) enforce(ctx context.Context, sourceURIs, targetURIs []string, transformation string, options, metadata map[string]interface{}) (string, map[string]interface{}, error) {
+	required, err := p.required(ctx, sourceURIs, targetURIs)
+	if err != nil {
+		return transformation, options, err
+	}
+
+	if required != nil {
+		transformation, options, err = required.Enforce(transformation, options)
+		if err != nil {
+			return "", nil, err
+		}
+		if metadata != nil {
+			metadata["transformation_policy_id"] = required.PolicyID
+		}
+	}
+	p.recommend(ctx, sourceURIs, transformation, metadata)
+	return transformation, options, nil
+}
+
+// recommend records in the metadata of a rule copying the data of source columns unchanged the
+// masking transformation recommended for the classification of the first privileged one
+func (p *transformationPolicies) recommend(ctx context.Context, sourceURIs []string, transformation string, metadata map[string]interface{}) {
+	if metadata == nil {
+		return
+	}
+	delete(metadata, "recommended_transformation")
+	if transformation != "" && transformation != "direct_mapping" {
+		return
+	}
+	for _, sourceURI := range sourceURIs {
+		source, err := p.mappings.GetItemByURI(ctx, sourceURI)
+		if err != nil || !source.IsPrivileged {
+			continue
+		}
+		classification := ""
+		if source.PrivilegedClassification != nil {
+			classification = *source.PrivilegedClassification
+		}
+		metadata["recommended_transformation"] = recommendedMasking(classification)
+		return
+	}
+}
+
+// recommendedMasking returns the masking transformation recommended for the columns of a
+// privileged data classification: card and account numbers keep their last digits, identifiers
+// become tokens, contact details keep their format, and anything else is hashed
+func recommendedMasking(classification string) string {
+	switch strings.ToLower(classification) {
+	case "credit_card", "bank_account", "iban", "routing_number":
+		return "redact_partial"
+	case "ssn", "ein", "tax_id", "passport_us", "drivers_license", "national_id", "medical_record", "insurance_id", "npi":
+		return "tokenize"
+	case "email", "phone_us", "phone_intl", "ip_address", "ipv6":
+		return "mask_format"
+	default:
+		return "hash_salted"
+	}
+}
+
+// required returns the requirement that applies to a mapping rule from the source to the target
+// URIs, or nil if no policy applies
+func (p *transformationPolicies) required(ctx context.Context, sourceURIs, targetURIs []string) (*policy.TransformationRequirement, error) {
+	if len(p.requirements) == 0 {
+		return nil, nil
+	}
+
+	var required *policy.TransformationRequirement
+	for _, sourceURI := range sourceURIs {
+		// Only discovered columns carry a classification
+		source, err := p.mappings.GetItemByURI(ctx, sourceURI)
+		if err != nil || source.PrivilegedClassification == nil {
+			continue
+		}
+		for _, targetURI := range targetURIs {
+			if !p.leaves(ctx, source, targetURI) {
+				continue
+			}
+			requirement := p.requirement(source)
+			if requirement == nil {
+				continue
+			}
+			if required != nil && required.Transformation != requirement.Transformation {
+				return nil, fmt.Errorf("policies %s and %s require different transformations of the rule", required.PolicyName, requirement.PolicyName)
+			}
+			if required == nil {
+				required = requirement
+			}
+		}
+	}
+	return required, nil
+}
+
+// requirement returns the requirement that applies to the data of a classified source column
+func (p *transformationPolicies) requirement(source *mapping.ResourceItem) *policy.TransformationRequirement {
+	for _, requirement := range p.requirements {
+		if !requirement.Matches(*source.PrivilegedClassification) {
+			continue
+		}
+		if requirement.Workspace != "" && p.workspaces[requirement.Workspace] != source.WorkspaceID {
+			continue
+		}
+		return requirement
+	}
+	return nil
+}
+
+// leaves reports whether data of the source flowing to the target leaves the workspace of the
+// source: the target is in another workspace or is not a database, e.g. a stream topic, a webhook
+// or an MCP resource.
+func (p *transformationPolicies) leaves(ctx context.Context, source *mapping.ResourceItem, targetURI string) bool {
+	if target, err := p.mappings.GetItemByURI(ctx, targetURI); err == nil {
+		return target.WorkspaceID != source.WorkspaceID || target.Protocol != string(resource.ProtocolDatabase)
+	}
+	return !strings.HasPrefix(targetURI, string(resource.ProtocolDatabase)+"://")
+}
+
+// ruleURIs returns the source or target URIs in the metadata of a mapping rule
+func ruleURIs(metadata map[string]interface{}, side string) []string {
+	var uris []string
+	if list, ok := metadata[side+"_uris"].([]interface{}); ok {
+		for _, uri := range list {
+			if uri, ok := uri.(string); ok && uri != "" {
+				uris = append(uris, uri)
+			}
+		}
+	}
+	if uri, _ := metadata[side+"_resource_uri"].(string); uri != "" {
+		for _, existing := range uris {
+			if existing == uri {
+				return uris
+			}
+		}
+		uris = append(uris, uri)
+	}
+	return uris
+}
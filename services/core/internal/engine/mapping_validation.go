@@ -105,6 +105,15 @@ func validateTransformationCardinality(transformationType, ruleCardinality strin
 		transformationType, ruleCardinality, supported)
 }
 
+// validateSourceCombination checks that a rule reading several sources has a transformation
+// combining their values, without which it has no value to write
+func validateSourceCombination(cardinality, transformationName string) error {
+	if transformationName == "" && (cardinality == "many-to-one" || cardinality == "many-to-many") {
+		return fmt.Errorf("%s cardinality requires a transformation combining the values of the sources, e.g. concat", cardinality)
+	}
+	return nil
+}
+
 // validateFilterExpression validates the structure of a filter expression
 func validateFilterExpression(filterType string, expression map[string]interface{}) error {
 	if len(expression) == 0 {
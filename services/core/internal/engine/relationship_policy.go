@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/policy"
+	"github.com/redbco/redb-open/services/core/internal/services/relationship"
+)
+
+// schemaChangeCheck is the outcome of checking the changes to the schema of the source of a
+// relationship since it was last deployed against the schema change policies
+type schemaChangeCheck struct {
+	version  int32 // Latest schema snapshot version of the source database, 0 if it has none
+	findings []policy.SchemaChangeFinding
+}
+
+// checkRelationshipSchemaChanges finds the breaking changes to the source table of a relationship
+// since it was last deployed, and the actions the schema change policies of the workspace take on
+// them. A relationship that was never deployed has no changes.
+func (s *Server) checkRelationshipSchemaChanges(ctx context.Context, tenantID, workspaceName string, rel *relationship.Relationship) (*schemaChangeCheck, error) {
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	latest, err := databaseService.GetLatestSchemaSnapshot(ctx, rel.SourceDatabaseID)
+	if errors.Is(err, database.ErrSnapshotNotFound) {
+		return &schemaChangeCheck{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	check := &schemaChangeCheck{version: latest.Version}
+
+	deployed, err := relationship.NewService(s.engine.db, s.engine.logger).GetSourceSchemaVersion(ctx, rel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if deployed == 0 || deployed == latest.Version {
+		return check, nil
+	}
+	previous, err := databaseService.GetSchemaSnapshot(ctx, rel.SourceDatabaseID, deployed)
+	if errors.Is(err, database.ErrSnapshotNotFound) {
+		s.engine.logger.Warnf("Schema snapshot %d of the source of relationship %s not found, schema changes are not checked", deployed, rel.Name)
+		return check, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previousSchema, err := snapshotSchema(previous)
+	if err != nil {
+		return nil, err
+	}
+	latestSchema, err := snapshotSchema(latest)
+	if err != nil {
+		return nil, err
+	}
+	compareResp, err := s.compareSchemas(ctx, previousSchema, latestSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare schema snapshots %d and %d: %v", previous.Version, latest.Version, err)
+	}
+
+	var changes []policy.SchemaChange
+	for _, record := range compareResp.ChangeRecords {
+		if changesTable(record, rel.SourceTableName) {
+			changes = append(changes, policy.SchemaChange{
+				ChangeType:  record.ChangeType,
+				ObjectType:  record.ObjectType,
+				ObjectName:  record.ObjectName,
+				ParentName:  record.ParentName,
+				Field:       record.Field,
+				OldValue:    record.OldValue,
+				NewValue:    record.NewValue,
+				Breaking:    record.Breaking,
+				Description: record.Description,
+			})
+		}
+	}
+	if len(changes) == 0 {
+		return check, nil
+	}
+
+	policies, err := policy.NewService(s.engine.db, s.engine.logger).SchemaChangePolicies(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema change policies: %w", err)
+	}
+	check.findings = policy.EvaluateSchemaChanges(policies, workspaceName, changes)
+	return check, nil
+}
+
+// messages returns the findings the policies take an action on, as messages
+func (c *schemaChangeCheck) messages(action string) []string {
+	var messages []string
+	for _, finding := range c.findings {
+		if finding.Action != action {
+			continue
+		}
+		message := fmt.Sprintf("%s (%s)", finding.Change.Description, finding.Class)
+		if finding.PolicyName != "" {
+			message += fmt.Sprintf(", policy %s", finding.PolicyName)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// blockedError returns the error of a deployment the policies block, or nil
+func (c *schemaChangeCheck) blockedError(relationshipName string) error {
+	blocked := c.messages(policy.ActionBlock)
+	if len(blocked) == 0 {
+		return nil
+	}
+	return fmt.Errorf("breaking changes to the source schema of relationship %s since it was last deployed are blocked by policy: %s",
+		relationshipName, strings.Join(blocked, "; "))
+}
+
+// changesTable reports whether a change is to a table, or to an object of the table such as a
+// column. Table names are compared without their schema when either has none.
+func changesTable(record *unifiedmodelv1.ChangeRecord, table string) bool {
+	if record.ObjectType == "table" {
+		return sameTable(record.ObjectName, table) || (record.ChangeType == "renamed" && sameTable(record.OldValue, table))
+	}
+	// Objects within a column have the column in their parent, such as orders.total
+	return sameTable(record.ParentName, table) || strings.HasPrefix(strings.ToLower(record.ParentName), strings.ToLower(table)+".")
+}
+
+func sameTable(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	return strings.EqualFold(unqualified(a), unqualified(b)) && (!strings.Contains(a, ".") || !strings.Contains(b, "."))
+}
+
+func unqualified(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -23,6 +23,8 @@ type Server struct {
 	corev1.UnimplementedCommitServiceServer
 	corev1.UnimplementedMappingServiceServer
 	corev1.UnimplementedRelationshipServiceServer
+	corev1.UnimplementedCutoverServiceServer
+	corev1.UnimplementedQueryProxyServiceServer
 	corev1.UnimplementedTransformationServiceServer
 	corev1.UnimplementedPolicyServiceServer
 	corev1.UnimplementedMCPServiceServer
@@ -39,6 +41,8 @@ type Server struct {
 	corev1.UnimplementedImportExportServiceServer
 	corev1.UnimplementedResourceServiceServer
 	corev1.UnimplementedDataProductServiceServer
+	corev1.UnimplementedArtifactServiceServer
+	corev1.UnimplementedRetentionServiceServer
 
 	// Engine reference for tracking operations
 	engine *Engine
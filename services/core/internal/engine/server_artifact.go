@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/pkg/blobstore"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultArtifactURLExpiry is how long artifact download URLs are valid unless requested otherwise
+const defaultArtifactURLExpiry = 15 * time.Minute
+
+// readBlobChunkSize is the size of the chunks ReadBlob streams
+const readBlobChunkSize = 256 * 1024
+
+func (s *Server) ListArtifacts(ctx context.Context, req *corev1.ListArtifactsRequest) (*corev1.ListArtifactsResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if req.ArtifactKind != "" && !artifact.ValidKind(req.ArtifactKind) {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "unknown artifact kind %q", req.ArtifactKind)
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	artifacts, err := artifact.NewService(s.engine.db, s.engine.logger).List(ctx, req.TenantId, workspaceID, req.ArtifactKind)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list artifacts: %v", err)
+	}
+
+	protoArtifacts := make([]*corev1.Artifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		protoArtifacts = append(protoArtifacts, artifactToProto(a))
+	}
+
+	return &corev1.ListArtifactsResponse{
+		Artifacts: protoArtifacts,
+		Message:   fmt.Sprintf("Found %d artifacts", len(protoArtifacts)),
+		Success:   true,
+		Status:    commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) GetArtifactURL(ctx context.Context, req *corev1.GetArtifactURLRequest) (*corev1.GetArtifactURLResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if artifact.Store() == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.FailedPrecondition, "blob store is not configured")
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	artifactService := artifact.NewService(s.engine.db, s.engine.logger)
+	a, err := artifactService.Get(ctx, req.TenantId, workspaceID, req.ArtifactId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "artifact not found: %v", err)
+	}
+
+	expiry := defaultArtifactURLExpiry
+	if req.ExpirySeconds > 0 {
+		expiry = time.Duration(req.ExpirySeconds) * time.Second
+	}
+	if expiry > blobstore.MaxSignedURLExpiry {
+		expiry = blobstore.MaxSignedURLExpiry
+	}
+
+	url, err := artifactService.SignedURL(ctx, a, expiry)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to sign artifact URL: %v", err)
+	}
+
+	return &corev1.GetArtifactURLResponse{
+		Artifact: artifactToProto(a),
+		Url:      url,
+		Expires:  time.Now().Add(expiry).UTC().Format(time.RFC3339),
+		Message:  "Artifact URL signed",
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) DeleteArtifact(ctx context.Context, req *corev1.DeleteArtifactRequest) (*corev1.DeleteArtifactResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	artifactService := artifact.NewService(s.engine.db, s.engine.logger)
+	if _, err := artifactService.Get(ctx, req.TenantId, workspaceID, req.ArtifactId); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "artifact not found: %v", err)
+	}
+	if err := artifactService.Delete(ctx, req.TenantId, workspaceID, req.ArtifactId); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to delete artifact: %v", err)
+	}
+
+	return &corev1.DeleteArtifactResponse{
+		Message: fmt.Sprintf("Artifact %s deleted", req.ArtifactId),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ReadBlob streams a blob of the local blob store to the Client API, which serves the signed
+// URLs of local blobs. Object stores serve their presigned URLs themselves.
+func (s *Server) ReadBlob(req *corev1.ReadBlobRequest, stream corev1.ArtifactService_ReadBlobServer) error {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	localStore, ok := artifact.Store().(*blobstore.LocalStore)
+	if !ok {
+		s.engine.IncrementErrors()
+		return status.Error(codes.FailedPrecondition, "blob downloads are only served for the local blob store")
+	}
+	if err := localStore.VerifySignature(req.Key, req.Expires, req.Signature); err != nil {
+		s.engine.IncrementErrors()
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	reader, info, err := localStore.Get(stream.Context(), req.Key)
+	if err != nil {
+		s.engine.IncrementErrors()
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return status.Error(codes.NotFound, "blob not found")
+		}
+		return status.Errorf(codes.Internal, "failed to read blob: %v", err)
+	}
+	defer reader.Close()
+
+	buf := make([]byte, readBlobChunkSize)
+	first := true
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 || first {
+			chunk := &corev1.ReadBlobResponse{Chunk: buf[:n]}
+			if first {
+				chunk.ContentType = info.ContentType
+				chunk.SizeBytes = info.Size
+				first = false
+			}
+			if sendErr := stream.Send(chunk); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			s.engine.IncrementErrors()
+			return status.Errorf(codes.Internal, "failed to read blob: %v", err)
+		}
+	}
+}
+
+func artifactToProto(a *artifact.Artifact) *corev1.Artifact {
+	return &corev1.Artifact{
+		ArtifactId:   a.ID,
+		TenantId:     a.TenantID,
+		WorkspaceId:  a.WorkspaceID,
+		ArtifactKind: a.Kind,
+		ArtifactName: a.Name,
+		ContentType:  a.ContentType,
+		SizeBytes:    a.SizeBytes,
+		Created:      a.Created.Format(time.RFC3339),
+	}
+}
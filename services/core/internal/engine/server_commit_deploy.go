@@ -9,6 +9,7 @@ import (
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"github.com/redbco/redb-open/services/core/internal/services/branch"
 	"github.com/redbco/redb-open/services/core/internal/services/commit"
 	"github.com/redbco/redb-open/services/core/internal/services/database"
@@ -17,7 +18,6 @@ import (
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
@@ -90,7 +90,7 @@ func (s *Server) DeployCommitSchema(ctx context.Context, req *corev1.DeployCommi
 			return nil, status.Errorf(codes.Internal, "failed to serialize source schema: %v", err)
 		}
 
-		convertedSchemaStr, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDBType, targetDB.Type)
+		convertedSchemaStr, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDBType, targetDB.Type, req.Options.GetTransformationOptions())
 		if err != nil {
 			s.engine.IncrementErrors()
 			return nil, status.Errorf(codes.Internal, "failed to convert schema: %v", err)
@@ -275,7 +275,7 @@ func (s *Server) createNewDatabaseForDeploy(ctx context.Context, tenantID, works
 
 	// Connect to anchor service to create the logical database
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		return nil, "", status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
 	}
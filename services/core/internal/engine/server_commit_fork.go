@@ -57,7 +57,7 @@ func (s *Server) ForkCommit(ctx context.Context, req *corev1.ForkCommitRequest)
 			return nil, status.Errorf(codes.Internal, "failed to serialize source schema: %v", err)
 		}
 
-		convertedSchemaStr, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDBType, targetDBType)
+		convertedSchemaStr, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDBType, targetDBType, nil)
 		if err != nil {
 			s.engine.IncrementErrors()
 			return nil, status.Errorf(codes.Internal, "failed to convert schema: %v", err)
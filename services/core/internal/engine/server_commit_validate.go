@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidateCommitSchema validates the schema of a commit via the unifiedmodel service. The schema is
+// checked for internal consistency and against the requested lint rules, so that problems surface
+// before the commit is deployed.
+func (s *Server) ValidateCommitSchema(ctx context.Context, req *corev1.ValidateCommitSchemaRequest) (*corev1.ValidateCommitSchemaResponse, error) {
+	defer s.trackOperation()()
+
+	// Get workspace service to convert workspace name to ID
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+	workspaceID, err := workspaceService.GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ID: %v", err)
+	}
+
+	commitObj, schemaType, err := s.getCommitSchema(ctx, req.TenantId, workspaceID, req.RepoName, req.BranchName, req.CommitCode)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "failed to get commit: %v", err)
+	}
+
+	// Parse the commit schema as UnifiedModel
+	schemaJSON, err := json.Marshal(commitObj.SchemaStructure)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to serialize commit schema: %v", err)
+	}
+	var model unifiedmodelv1.UnifiedModel
+	if err := json.Unmarshal(schemaJSON, &model); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to parse commit schema: %v", err)
+	}
+	if model.DatabaseType == "" {
+		model.DatabaseType = schemaType
+	}
+
+	umClient := s.engine.GetUnifiedModelClient()
+	if umClient == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "unified model service not available")
+	}
+
+	validateResp, err := umClient.ValidateUnifiedModel(ctx, &unifiedmodelv1.ValidateUnifiedModelRequest{
+		UnifiedModel: &model,
+		Options: &unifiedmodelv1.ValidationOptions{
+			DisableLint:      req.DisableLint,
+			LintRules:        req.LintRules,
+			NamingConvention: req.NamingConvention,
+			Severities:       req.Severities,
+		},
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "failed to validate commit schema: %v", err)
+	}
+
+	issues := make([]*corev1.SchemaValidationIssue, 0, len(validateResp.Issues))
+	for _, issue := range validateResp.Issues {
+		issues = append(issues, &corev1.SchemaValidationIssue{
+			Severity:   issue.Severity,
+			Rule:       issue.Rule,
+			Field:      issue.Field,
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+		})
+	}
+
+	message := "Commit schema is valid"
+	if !validateResp.Valid {
+		message = "Commit schema has critical issues and cannot be deployed"
+	}
+
+	return &corev1.ValidateCommitSchemaResponse{
+		Message:       message,
+		Success:       true,
+		Status:        commonv1.Status_STATUS_SUCCESS,
+		Valid:         validateResp.Valid,
+		Issues:        issues,
+		CriticalCount: validateResp.CriticalCount,
+		WarningCount:  validateResp.WarningCount,
+		InfoCount:     validateResp.InfoCount,
+	}, nil
+}
@@ -0,0 +1,494 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/cutover"
+	"github.com/redbco/redb-open/services/core/internal/services/relationship"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// drainPollInterval is how often the replication of a cutover is polled while it drains. The
+// replication is drained once it has no pending events and processed none between two polls.
+const drainPollInterval = 5 * time.Second
+
+// ============================================================================
+// CutoverService gRPC handlers
+// ============================================================================
+
+func (s *Server) ListCutovers(ctx context.Context, req *corev1.ListCutoversRequest) (*corev1.ListCutoversResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	cutovers, err := cutover.NewService(s.engine.db, s.engine.logger).List(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list cutovers: %v", err)
+	}
+
+	protoCutovers := make([]*corev1.Cutover, len(cutovers))
+	for i, c := range cutovers {
+		protoCutovers[i] = cutoverToProto(c)
+	}
+	return &corev1.ListCutoversResponse{Cutovers: protoCutovers}, nil
+}
+
+func (s *Server) ShowCutover(ctx context.Context, req *corev1.ShowCutoverRequest) (*corev1.ShowCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	c, err := s.getCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	return &corev1.ShowCutoverResponse{Cutover: cutoverToProto(c)}, nil
+}
+
+func (s *Server) AddCutover(ctx context.Context, req *corev1.AddCutoverRequest) (*corev1.AddCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	if req.CutoverName == "" || req.RelationshipName == "" || req.ConnectionName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.InvalidArgument, "cutover_name, relationship_name and connection_name are required")
+	}
+	if err := cutover.ValidateGates(req.Gates); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gates: %v", err)
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	rel, err := relationship.NewService(s.engine.db, s.engine.logger).GetByName(ctx, req.TenantId, workspaceID, req.RelationshipName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "relationship not found: %v", err)
+	}
+
+	c := &cutover.Cutover{
+		TenantID:       req.TenantId,
+		WorkspaceID:    workspaceID,
+		Name:           req.CutoverName,
+		Description:    req.CutoverDescription,
+		RelationshipID: rel.ID,
+		ConnectionName: req.ConnectionName,
+		StopWritesHook: req.StopWritesHook,
+		UnpauseHook:    req.UnpauseHook,
+		Gates:          req.Gates,
+		MaxRetries:     cutover.DefaultMaxRetries,
+		DrainTimeout:   cutover.DefaultDrainTimeout,
+		OwnerID:        req.OwnerId,
+	}
+	if req.MaxRetries != nil {
+		c.MaxRetries = int(*req.MaxRetries)
+	}
+	if req.DrainTimeoutSeconds != nil {
+		c.DrainTimeout = time.Duration(*req.DrainTimeoutSeconds) * time.Second
+	}
+
+	created, err := cutover.NewService(s.engine.db, s.engine.logger).Create(ctx, c)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to create cutover: %v", err)
+	}
+
+	return &corev1.AddCutoverResponse{
+		Message: fmt.Sprintf("Cutover '%s' created successfully", created.Name),
+		Success: true,
+		Cutover: cutoverToProto(created),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) DeleteCutover(ctx context.Context, req *corev1.DeleteCutoverRequest) (*corev1.DeleteCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	c, err := s.getCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	if !c.Deletable() {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "cutover '%s' is %s; abort it before deleting it", c.Name, c.State)
+	}
+
+	if err := cutover.NewService(s.engine.db, s.engine.logger).Delete(ctx, c.TenantID, c.WorkspaceID, c.Name); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to delete cutover: %v", err)
+	}
+
+	return &corev1.DeleteCutoverResponse{
+		Message: fmt.Sprintf("Cutover '%s' deleted successfully", c.Name),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) StartCutover(ctx context.Context, req *corev1.StartCutoverRequest) (*corev1.StartCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	c, err := s.runCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName, func(runner *cutover.Runner, c *cutover.Cutover) error {
+		return runner.Start(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.StartCutoverResponse{
+		Message: fmt.Sprintf("Cutover '%s' started", c.Name),
+		Success: true,
+		Cutover: cutoverToProto(c),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) ApproveCutover(ctx context.Context, req *corev1.ApproveCutoverRequest) (*corev1.ApproveCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	c, err := s.runCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName, func(runner *cutover.Runner, c *cutover.Cutover) error {
+		return runner.Approve(ctx, c, req.Step)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ApproveCutoverResponse{
+		Message: fmt.Sprintf("Step %s of cutover '%s' approved", c.CurrentStep, c.Name),
+		Success: true,
+		Cutover: cutoverToProto(c),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) RetryCutover(ctx context.Context, req *corev1.RetryCutoverRequest) (*corev1.RetryCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	c, err := s.runCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName, func(runner *cutover.Runner, c *cutover.Cutover) error {
+		return runner.Retry(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.RetryCutoverResponse{
+		Message: fmt.Sprintf("Retrying step %s of cutover '%s'", c.CurrentStep, c.Name),
+		Success: true,
+		Cutover: cutoverToProto(c),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) AbortCutover(ctx context.Context, req *corev1.AbortCutoverRequest) (*corev1.AbortCutoverResponse, error) {
+	defer s.trackOperation()()
+
+	runner := s.engine.CutoverRunner()
+	if runner == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.Unavailable, "cutovers are not available until the core engine has started")
+	}
+	c, err := s.getCutover(ctx, req.TenantId, req.WorkspaceName, req.CutoverName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	// Abort returns the cutover aborted along with an error when writes could not be resumed
+	if err := runner.Abort(ctx, c); err != nil {
+		s.engine.IncrementErrors()
+		if c.State != cutover.StateAborted {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to abort cutover: %v", err)
+		}
+		return &corev1.AbortCutoverResponse{
+			Message: err.Error(),
+			Success: false,
+			Cutover: cutoverToProto(c),
+			Status:  commonv1.Status_STATUS_ERROR,
+		}, nil
+	}
+
+	return &corev1.AbortCutoverResponse{
+		Message: fmt.Sprintf("Cutover '%s' aborted", c.Name),
+		Success: true,
+		Cutover: cutoverToProto(c),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) ListApplicationConnections(ctx context.Context, req *corev1.ListApplicationConnectionsRequest) (*corev1.ListApplicationConnectionsResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	connections, err := cutover.NewService(s.engine.db, s.engine.logger).ListConnections(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list application connections: %v", err)
+	}
+
+	protoConnections := make([]*corev1.ApplicationConnection, len(connections))
+	for i, a := range connections {
+		protoConnections[i] = &corev1.ApplicationConnection{
+			TenantId:              a.TenantID,
+			WorkspaceId:           a.WorkspaceID,
+			ConnectionName:        a.Name,
+			DatabaseId:            a.DatabaseID,
+			DatabaseName:          a.DatabaseName,
+			DatabaseType:          a.DatabaseType,
+			Host:                  a.Host,
+			Port:                  a.Port,
+			DatabaseDbName:        a.DatabaseDBName,
+			PreviousDatabaseName:  a.PreviousDatabaseName,
+			SwitchedByCutoverName: a.SwitchedByCutoverName,
+			Updated:               a.Updated.Format(time.RFC3339),
+		}
+	}
+	return &corev1.ListApplicationConnectionsResponse{Connections: protoConnections}, nil
+}
+
+// getCutover returns a cutover of a workspace, or a gRPC status error
+func (s *Server) getCutover(ctx context.Context, tenantID, workspaceName, name string) (*cutover.Cutover, error) {
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, tenantID, workspaceName)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+	c, err := cutover.NewService(s.engine.db, s.engine.logger).GetByName(ctx, tenantID, workspaceID, name)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return c, nil
+}
+
+// runCutover moves a cutover to running through the runner and returns it as it was when its
+// steps started
+func (s *Server) runCutover(ctx context.Context, tenantID, workspaceName, name string, run func(*cutover.Runner, *cutover.Cutover) error) (*cutover.Cutover, error) {
+	runner := s.engine.CutoverRunner()
+	if runner == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.Unavailable, "cutovers are not available until the core engine has started")
+	}
+	c, err := s.getCutover(ctx, tenantID, workspaceName, name)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	if err := run(runner, c); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return c, nil
+}
+
+func cutoverToProto(c *cutover.Cutover) *corev1.Cutover {
+	steps := make([]*corev1.CutoverStep, len(c.Steps))
+	for i, step := range c.Steps {
+		protoStep := &corev1.CutoverStep{
+			Name:     step.Name,
+			Status:   step.Status,
+			Attempts: int32(step.Attempts),
+			Gated:    c.Gated(step.Name),
+			Approved: step.Approved,
+			Message:  step.Message,
+		}
+		if step.Started != nil {
+			protoStep.Started = step.Started.Format(time.RFC3339)
+		}
+		if step.Finished != nil {
+			protoStep.Finished = step.Finished.Format(time.RFC3339)
+		}
+		steps[i] = protoStep
+	}
+
+	return &corev1.Cutover{
+		TenantId:            c.TenantID,
+		WorkspaceId:         c.WorkspaceID,
+		CutoverId:           c.ID,
+		CutoverName:         c.Name,
+		CutoverDescription:  c.Description,
+		RelationshipName:    c.RelationshipName,
+		ConnectionName:      c.ConnectionName,
+		StopWritesHook:      c.StopWritesHook,
+		UnpauseHook:         c.UnpauseHook,
+		Gates:               c.Gates,
+		MaxRetries:          int32(c.MaxRetries),
+		DrainTimeoutSeconds: int32(c.DrainTimeout / time.Second),
+		State:               c.State,
+		CurrentStep:         c.CurrentStep,
+		Steps:               steps,
+		OwnerId:             c.OwnerID,
+		Created:             c.Created.Format(time.RFC3339),
+		Updated:             c.Updated.Format(time.RFC3339),
+	}
+}
+
+// cutoverExecutor runs the steps of cutovers that act on the databases of their relationship
+// through the anchor service
+type cutoverExecutor struct {
+	server *Server
+}
+
+// cutoverTable is a table replicated by the relationship of a cutover
+type cutoverTable struct {
+	replicationSourceID string
+	sourceDatabaseID    string
+	sourceTable         string
+	targetDatabaseID    string
+	targetTable         string
+}
+
+func (x *cutoverExecutor) tables(ctx context.Context, c *cutover.Cutover) ([]cutoverTable, error) {
+	rows, err := x.server.engine.db.Pool().Query(ctx, `
+		SELECT rs.replication_source_id, rs.database_id, rs.table_name,
+		       COALESCE(rs.target_database_id, r.relationship_target_database_id),
+		       COALESCE(NULLIF(rs.target_table_name, ''), r.relationship_target_table_name)
+		FROM replication_sources rs
+		JOIN relationships r ON r.relationship_id = rs.relationship_id
+		WHERE rs.relationship_id = $1
+		ORDER BY rs.table_name
+	`, c.RelationshipID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []cutoverTable
+	for rows.Next() {
+		var t cutoverTable
+		if err := rows.Scan(&t.replicationSourceID, &t.sourceDatabaseID, &t.sourceTable, &t.targetDatabaseID, &t.targetTable); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("relationship %s replicates no tables; start it before cutting over", c.RelationshipName)
+	}
+	return tables, nil
+}
+
+func (x *cutoverExecutor) anchorClient() (anchorv1.AnchorServiceClient, error) {
+	client := x.server.engine.GetAnchorClient()
+	if client == nil {
+		return nil, fmt.Errorf("anchor service is not available")
+	}
+	return client, nil
+}
+
+// DrainCDC waits until the replication of every table of the relationship has no pending
+// events and processed no event since the previous poll
+func (x *cutoverExecutor) DrainCDC(ctx context.Context, c *cutover.Cutover) (string, error) {
+	tables, err := x.tables(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	client, err := x.anchorClient()
+	if err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(c.DrainTimeout)
+	lastProcessed := int64(-1)
+	for {
+		var pending, processed int64
+		for _, t := range tables {
+			resp, err := client.GetCDCReplicationStatus(ctx, &anchorv1.GetCDCReplicationStatusRequest{
+				TenantId:            c.TenantID,
+				WorkspaceId:         c.WorkspaceID,
+				ReplicationSourceId: t.replicationSourceID,
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to get the replication status of table %s: %v", t.sourceTable, err)
+			}
+			if !resp.Success || resp.CdcStatus != "active" {
+				return "", fmt.Errorf("replication of table %s is %s: %s", t.sourceTable, resp.CdcStatus, strings.Join(append([]string{resp.Message}, resp.Errors...), "; "))
+			}
+			pending += resp.EventsPending
+			processed += resp.EventsProcessed
+		}
+
+		if pending == 0 && processed == lastProcessed {
+			return fmt.Sprintf("Replication of %d tables drained after %d events", len(tables), processed), nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("replication did not drain within %s: %d events pending", c.DrainTimeout, pending)
+		}
+		lastProcessed = -1
+		if pending == 0 {
+			lastProcessed = processed
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
+// Reconcile compares the row counts of the source and target tables of the relationship
+func (x *cutoverExecutor) Reconcile(ctx context.Context, c *cutover.Cutover) (string, error) {
+	tables, err := x.tables(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	client, err := x.anchorClient()
+	if err != nil {
+		return "", err
+	}
+
+	count := func(databaseID, table string) (int64, error) {
+		resp, err := client.GetTableRowCount(ctx, &anchorv1.GetTableRowCountRequest{
+			TenantId:    c.TenantID,
+			WorkspaceId: c.WorkspaceID,
+			DatabaseId:  databaseID,
+			TableName:   table,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to count the rows of table %s: %v", table, err)
+		}
+		if !resp.Success {
+			return 0, fmt.Errorf("failed to count the rows of table %s: %s", table, resp.Message)
+		}
+		return resp.RowCount, nil
+	}
+
+	var mismatches []string
+	var rows int64
+	for _, t := range tables {
+		sourceRows, err := count(t.sourceDatabaseID, t.sourceTable)
+		if err != nil {
+			return "", err
+		}
+		targetRows, err := count(t.targetDatabaseID, t.targetTable)
+		if err != nil {
+			return "", err
+		}
+		if sourceRows != targetRows {
+			mismatches = append(mismatches, fmt.Sprintf("%s has %d rows, %s has %d", t.sourceTable, sourceRows, t.targetTable, targetRows))
+		}
+		rows += sourceRows
+	}
+	if len(mismatches) > 0 {
+		return "", fmt.Errorf("tables differ: %s", strings.Join(mismatches, "; "))
+	}
+	return fmt.Sprintf("%d tables match with %d rows", len(tables), rows), nil
+}
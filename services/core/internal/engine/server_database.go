@@ -10,6 +10,8 @@ import (
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"github.com/redbco/redb-open/services/core/internal/services/branch"
 	"github.com/redbco/redb-open/services/core/internal/services/database"
 	"github.com/redbco/redb-open/services/core/internal/services/instance"
@@ -18,7 +20,6 @@ import (
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -40,6 +41,12 @@ func (s *Server) ListDatabases(ctx context.Context, req *corev1.ListDatabasesReq
 		return nil, status.Errorf(codes.Internal, "failed to get workspace ID: %v", err)
 	}
 
+	selector, err := database.ParseLabelSelector(req.LabelSelector)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
 	// List databases for the tenant and workspace
 	databases, err := databaseService.List(ctx, req.TenantId, workspaceID)
 	if err != nil {
@@ -48,9 +55,11 @@ func (s *Server) ListDatabases(ctx context.Context, req *corev1.ListDatabasesReq
 	}
 
 	// Convert to protobuf format
-	protoDatabases := make([]*corev1.Database, len(databases))
-	for i, db := range databases {
-		protoDatabases[i] = s.databaseToProto(db)
+	protoDatabases := make([]*corev1.Database, 0, len(databases))
+	for _, db := range databases {
+		if selector.Matches(db.Labels) {
+			protoDatabases = append(protoDatabases, s.databaseToProto(db))
+		}
 	}
 
 	return &corev1.ListDatabasesResponse{
@@ -230,7 +239,7 @@ func (s *Server) ConnectDatabase(ctx context.Context, req *corev1.ConnectDatabas
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -408,7 +417,7 @@ func (s *Server) ConnectDatabaseWithInstance(ctx context.Context, req *corev1.Co
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -501,7 +510,7 @@ func (s *Server) ReconnectDatabase(ctx context.Context, req *corev1.ReconnectDat
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -617,6 +626,57 @@ func (s *Server) ModifyDatabase(ctx context.Context, req *corev1.ModifyDatabaseR
 	if req.Enabled != nil {
 		updates["database_enabled"] = *req.Enabled
 	}
+	if req.Replicas != nil {
+		replicas := make([]database.Replica, len(req.Replicas.Replicas))
+		for i, replica := range req.Replicas.Replicas {
+			replicas[i] = database.Replica{
+				Host:     replica.Host,
+				Port:     int(replica.Port),
+				Username: replica.Username,
+				Password: replica.Password,
+			}
+		}
+		encrypted, err := database.EncryptReplicas(req.TenantId, replicas)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid replicas: %v", err)
+		}
+		updates["database_replicas"] = encrypted
+	}
+	if req.RoutingPolicy != nil && *req.RoutingPolicy != "" {
+		if !database.ValidRoutingPolicy(*req.RoutingPolicy) {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "unknown routing policy %q", *req.RoutingPolicy)
+		}
+		updates["database_routing_policy"] = *req.RoutingPolicy
+	}
+	if req.RateLimits != nil {
+		limits := &database.RateLimits{
+			RowsPerSecond:           req.RateLimits.RowsPerSecond,
+			QueriesPerSecond:        req.RateLimits.QueriesPerSecond,
+			MaxConcurrentStatements: int(req.RateLimits.MaxConcurrentStatements),
+		}
+		if err := database.ValidateRateLimits(limits); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rate limits: %v", err)
+		}
+		if limits.IsZero() {
+			updates["database_rate_limits"] = nil
+		} else {
+			updates["database_rate_limits"] = limits
+		}
+	}
+	if req.Labels != nil {
+		labels := req.Labels.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		if err := database.ValidateLabels(labels); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid labels: %v", err)
+		}
+		updates["database_labels"] = labels
+	}
 
 	// Update the database
 	updatedDatabase, err := databaseService.Update(ctx, req.TenantId, workspaceID, req.DatabaseName, updates)
@@ -683,7 +743,7 @@ func (s *Server) DisconnectDatabase(ctx context.Context, req *corev1.DisconnectD
 	// Get anchor service address using dynamic resolution
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -1073,7 +1133,7 @@ func (s *Server) WipeDatabase(ctx context.Context, req *corev1.WipeDatabaseReque
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -1150,7 +1210,7 @@ func (s *Server) FetchTableData(ctx context.Context, req *corev1.FetchTableDataR
 
 	// Get anchor service address
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
@@ -1316,7 +1376,7 @@ func (s *Server) WipeTable(ctx context.Context, req *corev1.WipeTableRequest) (*
 
 	// Get anchor service address
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
@@ -1382,7 +1442,7 @@ func (s *Server) DropTable(ctx context.Context, req *corev1.DropTableRequest) (*
 
 	// Get anchor service address
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
@@ -1447,7 +1507,7 @@ func (s *Server) UpdateTableData(ctx context.Context, req *corev1.UpdateTableDat
 
 	// Get anchor service address
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
@@ -1484,6 +1544,85 @@ func (s *Server) UpdateTableData(ctx context.Context, req *corev1.UpdateTableDat
 	}, nil
 }
 
+func (s *Server) ExecuteDatabaseQuery(ctx context.Context, req *corev1.ExecuteDatabaseQueryRequest) (*corev1.ExecuteDatabaseQueryResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if req.Query == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "query is required")
+	}
+
+	// Get services
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+
+	// Get workspace ID
+	workspaceID, err := workspaceService.GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ID: %v", err)
+	}
+
+	// Get the database
+	db, err := databaseService.Get(ctx, req.TenantId, workspaceID, req.DatabaseName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+	}
+
+	// Verify tenant access
+	if db.TenantID != req.TenantId {
+		return nil, status.Errorf(codes.PermissionDenied, "database not found in tenant")
+	}
+
+	if id, ok := dbcapabilities.ParseID(db.Type); !ok || !dbcapabilities.SupportsQueryPassthrough(id) {
+		return nil, status.Errorf(codes.FailedPrecondition, "query passthrough is not supported for %s databases", db.Type)
+	}
+
+	// Get anchor service address
+	anchorAddr := s.engine.getServiceAddress("anchor")
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
+	}
+	defer anchorConn.Close()
+
+	anchorClient := anchorv1.NewAnchorServiceClient(anchorConn)
+
+	anchorResp, err := anchorClient.ExecuteQuery(ctx, &anchorv1.ExecuteQueryRequest{
+		TenantId:    req.TenantId,
+		WorkspaceId: db.WorkspaceID,
+		DatabaseId:  db.ID,
+		Query:       req.Query,
+		MaxRows:     req.MaxRows,
+		AllowWrites: req.AllowWrites,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to execute query: %v", err)
+	}
+
+	// Failures are mostly those of the query itself, e.g. syntax errors or statements
+	// rejected as writes
+	if !anchorResp.Success {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%s", anchorResp.Message)
+	}
+
+	return &corev1.ExecuteDatabaseQueryResponse{
+		Message:    anchorResp.Message,
+		Success:    true,
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		Columns:    anchorResp.Columns,
+		Data:       anchorResp.Data,
+		RowCount:   anchorResp.RowCount,
+		Truncated:  anchorResp.Truncated,
+		DurationMs: anchorResp.DurationMs,
+	}, nil
+}
+
 func (s *Server) TransformData(ctx context.Context, req *corev1.TransformDataRequest) (*corev1.TransformDataResponse, error) {
 	s.engine.TrackOperation()
 	defer s.engine.UntrackOperation()
@@ -1508,6 +1647,12 @@ func (s *Server) TransformData(ctx context.Context, req *corev1.TransformDataReq
 		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
 	}
 
+	// Broken rules reference objects that no longer exist and are left out of the transformation
+	mappingRules, brokenRules := mapping.RunnableRules(mappingRules)
+	for _, rule := range brokenRules {
+		s.engine.logger.Warnf("Skipping broken mapping rule %s: %s", rule.Name, rule.Breakage().Reason)
+	}
+
 	if len(mappingRules) == 0 {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.FailedPrecondition, "mapping has no rules")
@@ -1571,7 +1716,7 @@ func (s *Server) TransformData(ctx context.Context, req *corev1.TransformDataReq
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -1738,6 +1883,12 @@ func (s *Server) TransformDataStream(req *corev1.TransformDataStreamRequest, str
 		return status.Errorf(codes.NotFound, "mapping not found: %v", err)
 	}
 
+	// Broken rules reference objects that no longer exist and are left out of the transformation
+	mappingRules, brokenRules := mapping.RunnableRules(mappingRules)
+	for _, rule := range brokenRules {
+		s.engine.logger.Warnf("Skipping broken mapping rule %s: %s", rule.Name, rule.Breakage().Reason)
+	}
+
 	if len(mappingRules) == 0 {
 		s.engine.IncrementErrors()
 		return status.Errorf(codes.FailedPrecondition, "mapping has no rules")
@@ -1801,7 +1952,7 @@ func (s *Server) TransformDataStream(req *corev1.TransformDataStreamRequest, str
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -2020,7 +2171,7 @@ func (s *Server) DropDatabase(ctx context.Context, req *corev1.DropDatabaseReque
 	anchorAddr := s.engine.getServiceAddress("anchor")
 
 	// Connect to anchor service
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// schemaConversionReport is the report of a schema conversion kept next to its DDL in the blob store
+type schemaConversionReport struct {
+	DatabaseName    string                            `json:"database_name"`
+	SourceType      string                            `json:"source_type"`
+	TargetType      string                            `json:"target_type"`
+	Lossy           bool                              `json:"lossy"`
+	Statements      int                               `json:"statements"`
+	GenerationError string                            `json:"generation_error,omitempty"`
+	Metrics         *corev1.SchemaConversionMetrics   `json:"metrics,omitempty"`
+	Warnings        []*corev1.SchemaConversionWarning `json:"warnings,omitempty"`
+}
+
+// ConvertDatabaseSchema converts the current schema of a database to another database type and
+// generates the DDL creating it, without deploying it. The conversion is lossy when the
+// unifiedmodel service reports data loss, lossy type conversions or dropped objects.
+func (s *Server) ConvertDatabaseSchema(ctx context.Context, req *corev1.ConvertDatabaseSchemaRequest) (*corev1.ConvertDatabaseSchemaResponse, error) {
+	defer s.trackOperation()()
+
+	if req.TargetType == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "target type is required")
+	}
+
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+	workspaceID, err := workspaceService.GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ID: %v", err)
+	}
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	sourceDB, err := databaseService.Get(ctx, req.TenantId, workspaceID, req.DatabaseName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+	}
+
+	currentSchema, err := databaseService.GetDatabaseSchema(ctx, sourceDB.ID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get database schema: %v", err)
+	}
+	if currentSchema == "" || currentSchema == "{}" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "database has no schema stored")
+	}
+	var sourceStructure unifiedmodelv1.UnifiedModel
+	if err := json.Unmarshal([]byte(currentSchema), &sourceStructure); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to parse database schema: %v", err)
+	}
+
+	umConn, err := grpc.Dial(s.engine.getServiceAddress("unifiedmodel"), spiffe.DialOption())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to connect to unifiedmodel service: %v", err)
+	}
+	defer umConn.Close()
+	umClient := unifiedmodelv1.NewUnifiedModelServiceClient(umConn)
+
+	translateResp, err := umClient.TranslateEnhanced(ctx, &unifiedmodelv1.TranslationEnhancedRequest{
+		SourceType:      sourceDB.Type,
+		TargetType:      req.TargetType,
+		SourceStructure: &sourceStructure,
+		Preferences:     conversionPreferencesFromOptions(req.TransformationOptions),
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to translate schema: %v", status.Convert(err).Message())
+	}
+	if translateResp.TargetStructure == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "schema translation failed: no target structure returned")
+	}
+
+	convertedSchema, err := json.Marshal(translateResp.TargetStructure)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to serialize converted schema: %v", err)
+	}
+
+	response := &corev1.ConvertDatabaseSchemaResponse{
+		Success:         true,
+		Status:          commonv1.Status_STATUS_SUCCESS,
+		SourceType:      sourceDB.Type,
+		TargetType:      req.TargetType,
+		ConvertedSchema: string(convertedSchema),
+	}
+
+	// Not all database types have a DDL generator, the converted schema is still returned
+	generateResp, err := umClient.Generate(ctx, &unifiedmodelv1.GenerationRequest{
+		SourceType: sourceDB.Type,
+		TargetType: req.TargetType,
+		Structure:  translateResp.TargetStructure,
+	})
+	if err != nil {
+		response.GenerationError = status.Convert(err).Message()
+	} else {
+		response.Statements = generateResp.Statements
+		for _, warning := range generateResp.Warnings {
+			response.Warnings = append(response.Warnings, &corev1.SchemaConversionWarning{
+				WarningType: "compatibility",
+				Message:     warning,
+				Severity:    "medium",
+			})
+		}
+	}
+
+	for _, warning := range translateResp.Warnings {
+		response.Warnings = append(response.Warnings, &corev1.SchemaConversionWarning{
+			WarningType: warning.WarningType,
+			ObjectType:  warning.ObjectType,
+			ObjectName:  warning.ObjectName,
+			Message:     warning.Message,
+			Severity:    warning.Severity,
+			Suggestion:  warning.Suggestion,
+		})
+		if warning.WarningType == "data_loss" {
+			response.Lossy = true
+		}
+	}
+	if metrics := translateResp.Metrics; metrics != nil {
+		response.Metrics = &corev1.SchemaConversionMetrics{
+			ObjectsProcessed: metrics.ObjectsProcessed,
+			ObjectsConverted: metrics.ObjectsConverted,
+			ObjectsSkipped:   metrics.ObjectsSkipped,
+			ObjectsDropped:   metrics.ObjectsDropped,
+			TypesConverted:   metrics.TypesConverted,
+			LossyConversions: metrics.LossyConversions,
+		}
+		if metrics.LossyConversions > 0 || metrics.ObjectsDropped > 0 {
+			response.Lossy = true
+		}
+	}
+
+	if req.StoreArtifacts {
+		response.ArtifactIds, err = s.storeSchemaConversion(ctx, req.TenantId, workspaceID, req.DatabaseName, response)
+		if errors.Is(err, artifact.ErrStoreNotConfigured) {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot store the conversion: %v", err)
+		}
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to store the conversion: %v", err)
+		}
+	}
+
+	response.Message = fmt.Sprintf("Converted schema of database %s from %s to %s", req.DatabaseName, sourceDB.Type, req.TargetType)
+	return response, nil
+}
+
+// storeSchemaConversion keeps the DDL, if any, and the report of a schema conversion as export
+// artifacts and returns their IDs
+func (s *Server) storeSchemaConversion(ctx context.Context, tenantID, workspaceID, databaseName string, conversion *corev1.ConvertDatabaseSchemaResponse) ([]string, error) {
+	artifactService := artifact.NewService(s.engine.db, s.engine.logger)
+	baseName := fmt.Sprintf("%s.%s", databaseName, conversion.TargetType)
+
+	var ids []string
+	if len(conversion.Statements) > 0 {
+		ddl, err := artifactService.Put(ctx, tenantID, workspaceID, artifact.KindExport, baseName+".sql", "application/sql", []byte(ddlScript(conversion.Statements)))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, ddl.ID)
+	}
+
+	report, err := json.MarshalIndent(schemaConversionReport{
+		DatabaseName:    databaseName,
+		SourceType:      conversion.SourceType,
+		TargetType:      conversion.TargetType,
+		Lossy:           conversion.Lossy,
+		Statements:      len(conversion.Statements),
+		GenerationError: conversion.GenerationError,
+		Metrics:         conversion.Metrics,
+		Warnings:        conversion.Warnings,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	reportArtifact, err := artifactService.Put(ctx, tenantID, workspaceID, artifact.KindExport, baseName+".report.json", "application/json", report)
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, reportArtifact.ID), nil
+}
+
+// conversionPreferencesFromOptions builds the preferences of an enhanced translation from the
+// transformation options, keeping the defaults the unifiedmodel service applies without preferences
+func conversionPreferencesFromOptions(options map[string]string) *unifiedmodelv1.TranslationPreferences {
+	prefs := translationPreferencesFromOptions(options)
+	if prefs == nil {
+		return nil
+	}
+	prefs.OptimizeForPerformance = true
+	prefs.PreserveRelationships = true
+	prefs.IncludeMetadata = true
+	prefs.GenerateComments = true
+	return prefs
+}
+
+// ddlScript joins DDL statements into a script, terminating each statement
+func ddlScript(statements []string) string {
+	var sb strings.Builder
+	for _, statement := range statements {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		sb.WriteString(statement)
+		if !strings.HasSuffix(statement, ";") {
+			sb.WriteString(";")
+		}
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
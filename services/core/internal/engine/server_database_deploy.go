@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"github.com/redbco/redb-open/services/core/internal/services/commit"
 	"github.com/redbco/redb-open/services/core/internal/services/database"
 	"github.com/redbco/redb-open/services/core/internal/services/instance"
@@ -17,7 +19,6 @@ import (
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
@@ -101,7 +102,7 @@ func (s *Server) CloneDatabase(ctx context.Context, req *corev1.CloneDatabaseReq
 	if sourceDB.Type != targetDB.Type {
 		s.engine.logger.Infof("Converting schema from %s to %s", sourceDB.Type, targetDB.Type)
 
-		convertedSchema, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, currentSchema, sourceDB.Type, targetDB.Type)
+		convertedSchema, convertWarnings, err := s.convertSchemaViaUnifiedModel(ctx, currentSchema, sourceDB.Type, targetDB.Type, req.Options.GetTransformationOptions())
 		if err != nil {
 			s.engine.IncrementErrors()
 			return nil, status.Errorf(codes.Internal, "failed to convert schema: %v", err)
@@ -210,7 +211,7 @@ func (s *Server) createNewDatabaseForClone(ctx context.Context, tenantID, worksp
 
 	// Connect to anchor service to create the logical database
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		return nil, "", status.Errorf(codes.Internal, "failed to connect to anchor service: %v", err)
 	}
@@ -255,11 +256,12 @@ func (s *Server) createNewDatabaseForClone(ctx context.Context, tenantID, worksp
 	return databaseObj, databaseObj.ID, nil
 }
 
-// convertSchemaViaUnifiedModel converts schema between database types using the unifiedmodel service
-func (s *Server) convertSchemaViaUnifiedModel(ctx context.Context, sourceSchema, sourceType, targetType string) (string, []string, error) {
+// convertSchemaViaUnifiedModel converts schema between database types using the unifiedmodel service.
+// The owner, grant and comment handling is taken from the transformation options, see translationPreferencesFromOptions.
+func (s *Server) convertSchemaViaUnifiedModel(ctx context.Context, sourceSchema, sourceType, targetType string, transformationOptions map[string]string) (string, []string, error) {
 	// Connect to unifiedmodel service
 	umAddr := s.engine.getServiceAddress("unifiedmodel")
-	umConn, err := grpc.Dial(umAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	umConn, err := grpc.Dial(umAddr, spiffe.DialOption())
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to connect to unifiedmodel service: %w", err)
 	}
@@ -279,6 +281,7 @@ func (s *Server) convertSchemaViaUnifiedModel(ctx context.Context, sourceSchema,
 		SourceType:      sourceType,
 		TargetType:      targetType,
 		SourceStructure: &sourceUnifiedModel,
+		Preferences:     translationPreferencesFromOptions(transformationOptions),
 	}
 
 	translateResp, err := umClient.Translate(ctx, translateReq)
@@ -300,11 +303,37 @@ func (s *Server) convertSchemaViaUnifiedModel(ctx context.Context, sourceSchema,
 	return string(convertedSchemaBytes), translateResp.Warnings, nil
 }
 
+// translationPreferencesFromOptions builds the owner, grant and comment handling of a schema
+// conversion from the transformation options "owner_handling", "grant_handling" and
+// "comment_handling" (preserve, remap or drop) and "owner_mappings" ("source=target,..."). It
+// returns nil when none is set, so that the unifiedmodel service applies its defaults.
+func translationPreferencesFromOptions(options map[string]string) *unifiedmodelv1.TranslationPreferences {
+	prefs := &unifiedmodelv1.TranslationPreferences{
+		OwnerHandling:   options["owner_handling"],
+		GrantHandling:   options["grant_handling"],
+		CommentHandling: options["comment_handling"],
+	}
+	if mappings := options["owner_mappings"]; mappings != "" {
+		prefs.OwnerMappings = make(map[string]string)
+		for _, pair := range strings.Split(mappings, ",") {
+			source, target, _ := strings.Cut(pair, "=")
+			if source = strings.TrimSpace(source); source != "" {
+				prefs.OwnerMappings[source] = strings.TrimSpace(target)
+			}
+		}
+	}
+
+	if prefs.OwnerHandling == "" && prefs.GrantHandling == "" && prefs.CommentHandling == "" && len(prefs.OwnerMappings) == 0 {
+		return nil
+	}
+	return prefs
+}
+
 // deploySchemaToDatabase deploys schema to target database via anchor service
 func (s *Server) deploySchemaToDatabase(ctx context.Context, databaseID, schema string, options *corev1.CloneOptions) error {
 	// Connect to anchor service
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		return fmt.Errorf("failed to connect to anchor service: %w", err)
 	}
@@ -352,7 +381,7 @@ func (s *Server) deploySchemaToDatabase(ctx context.Context, databaseID, schema
 func (s *Server) copyDatabaseData(ctx context.Context, sourceDB, targetDB *database.Database, options *corev1.CloneOptions) (int64, error) {
 	// Connect to anchor service
 	anchorAddr := s.engine.getServiceAddress("anchor")
-	anchorConn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		return 0, fmt.Errorf("failed to connect to anchor service: %w", err)
 	}
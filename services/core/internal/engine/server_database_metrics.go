@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetDatabaseMetrics returns the per-operation latency and error metrics the anchor
+// recorded for one database, or for every database of the workspace
+func (s *Server) GetDatabaseMetrics(ctx context.Context, req *corev1.GetDatabaseMetricsRequest) (*corev1.GetDatabaseMetricsResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+
+	// Get workspace ID
+	workspaceID, err := workspaceService.GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get workspace ID: %v", err)
+	}
+
+	// Resolve the databases to report; the anchor is only asked for databases of this workspace
+	var databases []*database.Database
+	if req.DatabaseName != nil && *req.DatabaseName != "" {
+		db, err := databaseService.Get(ctx, req.TenantId, workspaceID, *req.DatabaseName)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+		}
+		databases = append(databases, db)
+	} else {
+		databases, err = databaseService.List(ctx, req.TenantId, workspaceID)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to list databases: %v", err)
+		}
+	}
+
+	response := &corev1.GetDatabaseMetricsResponse{
+		Message: "Database metrics retrieved successfully",
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}
+	if len(databases) == 0 {
+		return response, nil
+	}
+
+	names := make(map[string]string, len(databases))
+	databaseIDs := make([]string, 0, len(databases))
+	for _, db := range databases {
+		names[db.ID] = db.Name
+		databaseIDs = append(databaseIDs, db.ID)
+	}
+
+	// Get anchor service address using dynamic resolution
+	anchorAddr := s.engine.getServiceAddress("anchor")
+
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to connect to anchor service at %s: %v", anchorAddr, err)
+	}
+	defer anchorConn.Close()
+
+	anchorClient := anchorv1.NewAnchorServiceClient(anchorConn)
+	anchorResp, err := anchorClient.GetAdapterMetrics(ctx, &anchorv1.GetAdapterMetricsRequest{
+		TenantId:    req.TenantId,
+		WorkspaceId: workspaceID,
+		DatabaseIds: databaseIDs,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get adapter metrics from anchor service: %v", err)
+	}
+
+	response.LatencyBucketBoundsMs = anchorResp.LatencyBucketBoundsMs
+	for _, m := range anchorResp.Metrics {
+		response.Metrics = append(response.Metrics, &corev1.DatabaseOperationMetrics{
+			DatabaseId:       m.DatabaseId,
+			DatabaseName:     names[m.DatabaseId],
+			DatabaseType:     m.DatabaseType,
+			Operation:        m.Operation,
+			Count:            m.Count,
+			Errors:           m.Errors,
+			AvgLatencyMs:     m.AvgLatencyMs,
+			MinLatencyMs:     m.MinLatencyMs,
+			MaxLatencyMs:     m.MaxLatencyMs,
+			P50LatencyMs:     m.P50LatencyMs,
+			P95LatencyMs:     m.P95LatencyMs,
+			P99LatencyMs:     m.P99LatencyMs,
+			LastLatencyMs:    m.LastLatencyMs,
+			LastCalledAt:     m.LastCalledAt,
+			LastError:        m.LastError,
+			LastErrorAt:      m.LastErrorAt,
+			LatencyHistogram: m.LatencyHistogram,
+		})
+	}
+	for _, p := range anchorResp.QueryPlans {
+		response.QueryPlans = append(response.QueryPlans, &corev1.DatabaseQueryPlan{
+			DatabaseId:   p.DatabaseId,
+			DatabaseName: names[p.DatabaseId],
+			DatabaseType: p.DatabaseType,
+			Operation:    p.Operation,
+			TableName:    p.TableName,
+			Limit:        p.Limit,
+			DurationMs:   p.DurationMs,
+			CapturedAt:   p.CapturedAt,
+			Query:        p.Query,
+			PlanFormat:   p.PlanFormat,
+			Plan:         p.Plan,
+			Analyzed:     p.Analyzed,
+			Error:        p.Error,
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PlanDatabaseMigration compares the schema of a database with the schema of another database,
+// or with a unified model, and plans the statements migrating the database to it. Unless it is a
+// dry run, the statements are executed in order through the anchor, stopping at the first failure.
+func (s *Server) PlanDatabaseMigration(ctx context.Context, req *corev1.PlanDatabaseMigrationRequest) (*corev1.PlanDatabaseMigrationResponse, error) {
+	defer s.trackOperation()()
+
+	if (req.TargetDatabaseName == nil) == (req.TargetSchema == nil) {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "either a target database or a target schema is required")
+	}
+	dryRun := req.DryRun == nil || *req.DryRun
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	db, err := databaseService.Get(ctx, req.TenantId, workspaceID, req.DatabaseName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+	}
+	currentSchema, err := storedSchema(ctx, databaseService, db)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	var warnings []string
+	targetSchema := &unifiedmodelv1.UnifiedModel{}
+	if req.TargetDatabaseName != nil {
+		targetDB, err := databaseService.Get(ctx, req.TenantId, workspaceID, *req.TargetDatabaseName)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.NotFound, "target database not found: %v", err)
+		}
+		targetSchema, err = storedSchema(ctx, databaseService, targetDB)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		if targetDB.Type != db.Type {
+			warnings = append(warnings, fmt.Sprintf("target database %s is a %s database, its schema is not converted to %s; convert it first for an exact plan", targetDB.Name, targetDB.Type, db.Type))
+		}
+	} else if err := json.Unmarshal([]byte(*req.TargetSchema), targetSchema); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse target schema: %v", err)
+	}
+
+	umConn, err := grpc.Dial(s.engine.getServiceAddress("unifiedmodel"), spiffe.DialOption())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to connect to unifiedmodel service: %v", err)
+	}
+	defer umConn.Close()
+
+	planResp, err := unifiedmodelv1.NewUnifiedModelServiceClient(umConn).PlanMigration(ctx, &unifiedmodelv1.PlanMigrationRequest{
+		PreviousUnifiedModel: currentSchema,
+		CurrentUnifiedModel:  targetSchema,
+		DatabaseType:         db.Type,
+		ApplyRenames:         req.ApplyRenames,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to plan migration: %v", status.Convert(err).Message())
+	}
+
+	response := &corev1.PlanDatabaseMigrationResponse{
+		Success:      true,
+		Status:       commonv1.Status_STATUS_SUCCESS,
+		DatabaseType: db.Type,
+		HasChanges:   planResp.HasChanges,
+		Script:       planResp.Script,
+		Warnings:     append(warnings, planResp.Warnings...),
+		Destructive:  planResp.Destructive,
+		DryRun:       dryRun,
+	}
+	for _, step := range planResp.Steps {
+		response.Steps = append(response.Steps, &corev1.DatabaseMigrationStep{
+			Phase:       step.Phase,
+			ChangeType:  step.ChangeType,
+			ObjectType:  step.ObjectType,
+			ObjectName:  step.ObjectName,
+			ParentName:  step.ParentName,
+			Statement:   step.Statement,
+			Destructive: step.Destructive,
+			Description: step.Description,
+		})
+	}
+
+	if dryRun || len(response.Steps) == 0 {
+		response.Message = fmt.Sprintf("Planned %d migration steps for database %s", len(response.Steps), req.DatabaseName)
+		return response, nil
+	}
+
+	if response.Destructive && !req.AllowDestructive {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "the migration of database %s has destructive steps, allow them to execute it", req.DatabaseName)
+	}
+
+	if err := s.executeMigration(ctx, req.TenantId, workspaceID, db.ID, response); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if int(response.ExecutedSteps) < len(response.Steps) {
+		response.Success = false
+		response.Status = commonv1.Status_STATUS_FAILURE
+		failed := response.Steps[response.ExecutedSteps]
+		response.Message = fmt.Sprintf("Migration of database %s failed at step %d (%s): %s; %d of %d steps were executed",
+			req.DatabaseName, response.ExecutedSteps+1, failed.Description, failed.Error, response.ExecutedSteps, len(response.Steps))
+		return response, nil
+	}
+
+	response.Message = fmt.Sprintf("Migrated database %s in %d steps", req.DatabaseName, response.ExecutedSteps)
+	return response, nil
+}
+
+// storedSchema returns the schema of a database last discovered by the anchor
+func storedSchema(ctx context.Context, databaseService *database.Service, db *database.Database) (*unifiedmodelv1.UnifiedModel, error) {
+	schema, err := databaseService.GetDatabaseSchema(ctx, db.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get schema of database %s: %v", db.Name, err)
+	}
+	if schema == "" || schema == "{}" {
+		return nil, status.Errorf(codes.FailedPrecondition, "database %s has no schema stored", db.Name)
+	}
+	var model unifiedmodelv1.UnifiedModel
+	if err := json.Unmarshal([]byte(schema), &model); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse schema of database %s: %v", db.Name, err)
+	}
+	return &model, nil
+}
+
+// executeMigration runs the steps of a migration plan in order through the anchor, stopping at
+// the first step that fails
+func (s *Server) executeMigration(ctx context.Context, tenantID, workspaceID, databaseID string, plan *corev1.PlanDatabaseMigrationResponse) error {
+	anchorAddr := s.engine.getServiceAddress("anchor")
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
+	if err != nil {
+		return fmt.Errorf("failed to connect to anchor service at %s: %v", anchorAddr, err)
+	}
+	defer anchorConn.Close()
+
+	anchorClient := anchorv1.NewAnchorServiceClient(anchorConn)
+	for _, step := range plan.Steps {
+		resp, err := anchorClient.ExecuteCommand(ctx, &anchorv1.ExecuteCommandRequest{
+			TenantId:    tenantID,
+			WorkspaceId: workspaceID,
+			DatabaseId:  databaseID,
+			Command:     step.Statement,
+		})
+		if err == nil && !resp.Success {
+			err = errors.New(resp.Message)
+		}
+		if err != nil {
+			step.Error = err.Error()
+			return nil
+		}
+		step.Executed = true
+		plan.ExecutedSteps++
+		s.engine.logger.Infof("Migration step executed on database %s: %s", databaseID, step.Description)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListDatabaseSchemaSnapshots lists the schema snapshots of a database, newest first
+func (s *Server) ListDatabaseSchemaSnapshots(ctx context.Context, req *corev1.ListDatabaseSchemaSnapshotsRequest) (*corev1.ListDatabaseSchemaSnapshotsResponse, error) {
+	defer s.trackOperation()()
+
+	databaseService, db, err := s.snapshotDatabase(ctx, req.TenantId, req.WorkspaceName, req.DatabaseName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	snapshots, err := databaseService.ListSchemaSnapshots(ctx, db.ID, req.GetLimit())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list schema snapshots: %v", err)
+	}
+
+	response := &corev1.ListDatabaseSchemaSnapshotsResponse{
+		Message: fmt.Sprintf("Found %d schema snapshots of database %s", len(snapshots), req.DatabaseName),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}
+	for _, snapshot := range snapshots {
+		response.Snapshots = append(response.Snapshots, snapshotToProto(snapshot))
+	}
+	return response, nil
+}
+
+// DiffDatabaseSchemaSnapshots compares two schema snapshots of a database, selected by version or
+// by the time they were current
+func (s *Server) DiffDatabaseSchemaSnapshots(ctx context.Context, req *corev1.DiffDatabaseSchemaSnapshotsRequest) (*corev1.DiffDatabaseSchemaSnapshotsResponse, error) {
+	defer s.trackOperation()()
+
+	if req.From.GetSelector() == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "the snapshot to compare from is required")
+	}
+
+	databaseService, db, err := s.snapshotDatabase(ctx, req.TenantId, req.WorkspaceName, req.DatabaseName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	from, err := selectSnapshot(ctx, databaseService, db, req.From)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	to, err := selectSnapshot(ctx, databaseService, db, req.To)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	fromSchema, err := snapshotSchema(from)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	toSchema, err := snapshotSchema(to)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	compareResp, err := s.compareSchemas(ctx, fromSchema, toSchema)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to compare schema snapshots: %v", err)
+	}
+
+	response := &corev1.DiffDatabaseSchemaSnapshotsResponse{
+		Message: fmt.Sprintf("Found %d changes in database %s from snapshot %d to snapshot %d",
+			len(compareResp.Changes), req.DatabaseName, from.Version, to.Version),
+		Success:    true,
+		Status:     commonv1.Status_STATUS_SUCCESS,
+		From:       snapshotToProto(from),
+		To:         snapshotToProto(to),
+		HasChanges: compareResp.HasChanges,
+		Changes:    compareResp.Changes,
+		Warnings:   compareResp.Warnings,
+	}
+	for _, record := range compareResp.ChangeRecords {
+		response.ChangeRecords = append(response.ChangeRecords, &corev1.SchemaChange{
+			ChangeType:  record.ChangeType,
+			ObjectType:  record.ObjectType,
+			ObjectName:  record.ObjectName,
+			ParentName:  record.ParentName,
+			Field:       record.Field,
+			OldValue:    record.OldValue,
+			NewValue:    record.NewValue,
+			Severity:    record.Severity,
+			Breaking:    record.Breaking,
+			Description: record.Description,
+			Confidence:  record.Confidence,
+		})
+	}
+	return response, nil
+}
+
+// compareSchemas compares two unified models with the comparator of the unifiedmodel service
+func (s *Server) compareSchemas(ctx context.Context, previous, current *unifiedmodelv1.UnifiedModel) (*unifiedmodelv1.CompareResponse, error) {
+	umConn, err := grpc.Dial(s.engine.getServiceAddress("unifiedmodel"), spiffe.DialOption())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to unifiedmodel service: %v", err)
+	}
+	defer umConn.Close()
+
+	compareResp, err := unifiedmodelv1.NewUnifiedModelServiceClient(umConn).CompareUnifiedModels(ctx, &unifiedmodelv1.CompareUnifiedModelsRequest{
+		PreviousUnifiedModel: previous,
+		CurrentUnifiedModel:  current,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s", status.Convert(err).Message())
+	}
+	return compareResp, nil
+}
+
+// snapshotDatabase returns the database service and the database whose snapshots are requested
+func (s *Server) snapshotDatabase(ctx context.Context, tenantID, workspaceName, databaseName string) (*database.Service, *database.Database, error) {
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, tenantID, workspaceName)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	db, err := databaseService.Get(ctx, tenantID, workspaceID, databaseName)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+	}
+	return databaseService, db, nil
+}
+
+// selectSnapshot returns the snapshot of a database a selector selects, or the latest one when
+// the selector is unset
+func selectSnapshot(ctx context.Context, databaseService *database.Service, db *database.Database, selector *corev1.SchemaSnapshotSelector) (*database.SchemaSnapshot, error) {
+	var snapshot *database.SchemaSnapshot
+	var err error
+	switch sel := selector.GetSelector().(type) {
+	case *corev1.SchemaSnapshotSelector_Version:
+		snapshot, err = databaseService.GetSchemaSnapshot(ctx, db.ID, sel.Version)
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "database %s has no schema snapshot %d", db.Name, sel.Version)
+		}
+	case *corev1.SchemaSnapshotSelector_At:
+		at, parseErr := time.Parse(time.RFC3339, sel.At)
+		if parseErr != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot time %q, expected RFC 3339: %v", sel.At, parseErr)
+		}
+		snapshot, err = databaseService.GetSchemaSnapshotAt(ctx, db.ID, at)
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.NotFound, "database %s has no schema snapshot from %s or earlier", db.Name, sel.At)
+		}
+	default:
+		snapshot, err = databaseService.GetLatestSchemaSnapshot(ctx, db.ID)
+		if errors.Is(err, database.ErrSnapshotNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "database %s has no schema snapshots", db.Name)
+		}
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return snapshot, nil
+}
+
+// snapshotSchema parses the unified model of a schema snapshot
+func snapshotSchema(snapshot *database.SchemaSnapshot) (*unifiedmodelv1.UnifiedModel, error) {
+	var model unifiedmodelv1.UnifiedModel
+	if err := json.Unmarshal([]byte(snapshot.Schema), &model); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse schema snapshot %d: %v", snapshot.Version, err)
+	}
+	return &model, nil
+}
+
+func snapshotToProto(snapshot *database.SchemaSnapshot) *corev1.DatabaseSchemaSnapshot {
+	return &corev1.DatabaseSchemaSnapshot{
+		SnapshotId: snapshot.ID,
+		Version:    snapshot.Version,
+		Created:    snapshot.Created.Format(time.RFC3339),
+		SizeBytes:  snapshot.SizeBytes,
+	}
+}
@@ -3,11 +3,14 @@ package engine
 import (
 	"context"
 	"fmt"
+	"path"
+	"sync"
 	"time"
 
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
 	"github.com/redbco/redb-open/services/core/internal/services/instance"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -86,6 +89,58 @@ func (s *Server) ConnectInstance(ctx context.Context, req *corev1.ConnectInstanc
 		sslRootCert = req.SslRootCert
 	}
 
+	// Validate the SSH tunnel before creating the instance
+	var sshTunnel *instance.SSHTunnel
+	if req.SshTunnel != nil {
+		var err error
+		sshTunnel, err = instance.EncryptSSHTunnel(req.TenantId, sshTunnelFromProto(req.SshTunnel))
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ssh tunnel: %v", err)
+		}
+	}
+
+	// Instances with IAM authentication are connected to with tokens only, no password is stored
+	var iamAuth *instance.IAMAuth
+	if req.IamAuth != nil {
+		if req.Password != "" {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "a password cannot be set with iam authentication")
+		}
+		auth := iamAuthFromProto(req.IamAuth)
+		if err := instance.ValidateIAMAuth(auth); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid iam authentication: %v", err)
+		}
+		iamAuth = &auth
+	}
+
+	// Instances with Kerberos authentication are connected to with tickets only, no password is
+	// stored
+	var kerberosAuth *instance.KerberosAuth
+	if req.KerberosAuth != nil {
+		if req.Password != "" {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "a password cannot be set with kerberos authentication")
+		}
+		if iamAuth != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "kerberos and iam authentication cannot both be set")
+		}
+		auth := kerberosAuthFromProto(req.KerberosAuth)
+		if err := instance.ValidateKerberosAuth(auth); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid kerberos authentication: %v", err)
+		}
+		kerberosAuth = &auth
+	}
+
+	// Co-located instances are connected to through a local socket instead of their host and port
+	if req.SocketPath != "" && sshTunnel != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "a socket cannot be connected to through an ssh tunnel")
+	}
+
 	// Create the instance using available fields from ConnectInstanceRequest
 	createdInstance, err := instanceService.Create(ctx, req.TenantId, req.WorkspaceName, req.InstanceName, req.InstanceDescription, req.InstanceType, req.InstanceVendor, req.Host, req.Username, req.Password, req.NodeId, req.Port, req.GetEnabled(), req.GetSsl(), req.GetSslMode(), req.GetEnvironmentId(), req.OwnerId, sslCert, sslKey, sslRootCert, nil)
 	if err != nil {
@@ -93,6 +148,29 @@ func (s *Server) ConnectInstance(ctx context.Context, req *corev1.ConnectInstanc
 		return nil, status.Errorf(codes.Internal, "failed to create instance: %v", err)
 	}
 
+	// The anchor reaches the instance through its SSH tunnel or socket and authenticates with its
+	// IAM or Kerberos authentication, so they are stored before connecting
+	if sshTunnel != nil || iamAuth != nil || kerberosAuth != nil || req.SocketPath != "" {
+		updates := make(map[string]interface{})
+		if sshTunnel != nil {
+			updates["instance_ssh_tunnel"] = sshTunnel
+		}
+		if iamAuth != nil {
+			updates["instance_iam_auth"] = iamAuth
+		}
+		if req.SocketPath != "" {
+			updates["instance_socket_path"] = req.SocketPath
+		}
+		if kerberosAuth != nil {
+			updates["instance_kerberos_auth"] = kerberosAuth
+		}
+		createdInstance, err = instanceService.Update(ctx, req.TenantId, req.WorkspaceName, req.InstanceName, updates)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to store connection settings of instance: %v", err)
+		}
+	}
+
 	// Use the shared anchor client from engine
 	anchorClient := s.engine.GetAnchorClient()
 	if anchorClient == nil {
@@ -226,6 +304,65 @@ func (s *Server) ModifyInstance(ctx context.Context, req *corev1.ModifyInstanceR
 	if req.Enabled != nil {
 		updates["instance_enabled"] = *req.Enabled
 	}
+	if req.SshTunnel != nil {
+		if req.SshTunnel.Host == "" {
+			updates["instance_ssh_tunnel"] = nil
+		} else {
+			sshTunnel, err := instance.EncryptSSHTunnel(req.TenantId, sshTunnelFromProto(req.SshTunnel))
+			if err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "invalid ssh tunnel: %v", err)
+			}
+			updates["instance_ssh_tunnel"] = sshTunnel
+		}
+	}
+	if req.IamAuth != nil {
+		if req.IamAuth.Provider == "" {
+			updates["instance_iam_auth"] = nil
+		} else {
+			if req.Password != nil && *req.Password != "" {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "a password cannot be set with iam authentication")
+			}
+			auth := iamAuthFromProto(req.IamAuth)
+			if err := instance.ValidateIAMAuth(auth); err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "invalid iam authentication: %v", err)
+			}
+			updates["instance_iam_auth"] = &auth
+			// The stored password is dropped, tokens replace it
+			updates["instance_password"] = ""
+		}
+	}
+	if req.SocketPath != nil {
+		if *req.SocketPath == "" {
+			updates["instance_socket_path"] = nil
+		} else {
+			updates["instance_socket_path"] = *req.SocketPath
+		}
+	}
+	if req.KerberosAuth != nil {
+		if req.KerberosAuth.Keytab == "" && req.KerberosAuth.Ccache == "" {
+			updates["instance_kerberos_auth"] = nil
+		} else {
+			if req.Password != nil && *req.Password != "" {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "a password cannot be set with kerberos authentication")
+			}
+			if req.IamAuth != nil && req.IamAuth.Provider != "" {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "kerberos and iam authentication cannot both be set")
+			}
+			auth := kerberosAuthFromProto(req.KerberosAuth)
+			if err := instance.ValidateKerberosAuth(auth); err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.InvalidArgument, "invalid kerberos authentication: %v", err)
+			}
+			updates["instance_kerberos_auth"] = &auth
+			// The stored password is dropped, tickets replace it
+			updates["instance_password"] = ""
+		}
+	}
 
 	// Update the instance
 	updatedInstance, err := instanceService.Update(ctx, req.TenantId, req.WorkspaceName, req.InstanceName, updates)
@@ -360,3 +497,222 @@ func (s *Server) DisconnectInstance(ctx context.Context, req *corev1.DisconnectI
 		Status:  commonv1.Status_STATUS_DISCONNECTED,
 	}, nil
 }
+
+// instanceDatabaseAttachWorkers is the number of databases of an instance attached at once
+const instanceDatabaseAttachWorkers = 4
+
+func (s *Server) ListInstanceDatabases(ctx context.Context, req *corev1.ListInstanceDatabasesRequest) (*corev1.ListInstanceDatabasesResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if err := validateDatabaseNameGlobs(req.Filter); err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	_, databases, err := s.instanceDatabases(ctx, req.TenantId, req.WorkspaceName, req.InstanceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	matching := make([]*corev1.InstanceDatabase, 0, len(databases))
+	for _, db := range databases {
+		if matchesDatabaseNameGlobs(db.DbName, req.Filter, nil) {
+			matching = append(matching, db)
+		}
+	}
+
+	return &corev1.ListInstanceDatabasesResponse{
+		Message:   fmt.Sprintf("Found %d databases on instance %s", len(matching), req.InstanceName),
+		Success:   true,
+		Databases: matching,
+		Status:    commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) AttachInstanceDatabases(ctx context.Context, req *corev1.AttachInstanceDatabasesRequest) (*corev1.AttachInstanceDatabasesResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if err := validateDatabaseNameGlobs(append([]string{req.Filter}, req.Exclude...)...); err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	_, databases, err := s.instanceDatabases(ctx, req.TenantId, req.WorkspaceName, req.InstanceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	// Databases already attached are skipped, so that the call can be repeated to attach
+	// databases added to the instance since
+	var toAttach []string
+	skipped := 0
+	for _, db := range databases {
+		if !matchesDatabaseNameGlobs(db.DbName, req.Filter, req.Exclude) {
+			continue
+		}
+		if db.Attached {
+			skipped++
+			continue
+		}
+		toAttach = append(toAttach, db.DbName)
+	}
+
+	results := make([]*corev1.AttachedInstanceDatabase, len(toAttach))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < instanceDatabaseAttachWorkers && i < len(toAttach); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = s.attachInstanceDatabase(ctx, req, toAttach[index])
+			}
+		}()
+	}
+	for i := range toAttach {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	attached, failed := 0, 0
+	for _, result := range results {
+		if result.Success {
+			attached++
+		} else {
+			failed++
+		}
+	}
+
+	response := &corev1.AttachInstanceDatabasesResponse{
+		Message:       fmt.Sprintf("Attached %d databases of instance %s, %d already attached, %d failed", attached, req.InstanceName, skipped, failed),
+		Success:       failed == 0,
+		Results:       results,
+		AttachedCount: int32(attached),
+		SkippedCount:  int32(skipped),
+		FailedCount:   int32(failed),
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}
+	if failed > 0 {
+		s.engine.IncrementErrors()
+		response.Status = commonv1.Status_STATUS_ERROR
+		if attached > 0 {
+			response.Status = commonv1.Status_STATUS_WARNING
+		}
+	}
+	return response, nil
+}
+
+// attachInstanceDatabase connects a database on an instance to the workspace, named after the
+// database on the instance
+func (s *Server) attachInstanceDatabase(ctx context.Context, req *corev1.AttachInstanceDatabasesRequest, dbName string) *corev1.AttachedInstanceDatabase {
+	databaseName := req.NamePrefix + dbName
+	result := &corev1.AttachedInstanceDatabase{
+		DbName:       dbName,
+		DatabaseName: databaseName,
+	}
+
+	resp, err := s.ConnectDatabaseWithInstance(ctx, &corev1.ConnectDatabaseWithInstanceRequest{
+		TenantId:            req.TenantId,
+		WorkspaceName:       req.WorkspaceName,
+		InstanceName:        req.InstanceName,
+		DatabaseName:        databaseName,
+		DatabaseDescription: fmt.Sprintf("Database %s on instance %s", dbName, req.InstanceName),
+		DbName:              dbName,
+		NodeId:              req.NodeId,
+		Enabled:             req.Enabled,
+		EnvironmentId:       req.EnvironmentId,
+		OwnerId:             req.OwnerId,
+	})
+	if err != nil {
+		s.engine.logger.Warnf("Failed to attach database %s of instance %s: %v", dbName, req.InstanceName, err)
+		result.Message = status.Convert(err).Message()
+		return result
+	}
+
+	result.Success = true
+	result.Message = resp.Message
+	return result
+}
+
+// instanceDatabases returns the databases on an instance, noting those connected to the workspace
+func (s *Server) instanceDatabases(ctx context.Context, tenantID, workspaceName, instanceName string) (*instance.Instance, []*corev1.InstanceDatabase, error) {
+	instanceService := instance.NewService(s.engine.db, s.engine.logger)
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+
+	inst, err := instanceService.Get(ctx, tenantID, workspaceName, instanceName)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.NotFound, "instance not found: %v", err)
+	}
+
+	anchorClient := s.engine.GetAnchorClient()
+	if anchorClient == nil {
+		return nil, nil, status.Error(codes.Internal, "anchor service client not available")
+	}
+
+	anchorResp, err := anchorClient.ListInstanceDatabases(ctx, &anchorv1.ListInstanceDatabasesRequest{
+		TenantId:    tenantID,
+		WorkspaceId: inst.WorkspaceID,
+		InstanceId:  inst.ID,
+	})
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list instance databases via anchor service: %v", err)
+	}
+	if !anchorResp.Success {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "failed to list the databases of instance %s, is it connected? %s", instanceName, anchorResp.Message)
+	}
+
+	workspaceDatabases, err := databaseService.List(ctx, tenantID, inst.WorkspaceID)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to list databases: %v", err)
+	}
+	attached := make(map[string]string)
+	for _, db := range workspaceDatabases {
+		if db.InstanceID == inst.ID {
+			attached[db.DBName] = db.Name
+		}
+	}
+
+	databases := make([]*corev1.InstanceDatabase, 0, len(anchorResp.DatabaseNames))
+	for _, dbName := range anchorResp.DatabaseNames {
+		databaseName, ok := attached[dbName]
+		databases = append(databases, &corev1.InstanceDatabase{
+			DbName:       dbName,
+			Attached:     ok,
+			DatabaseName: databaseName,
+		})
+	}
+	return inst, databases, nil
+}
+
+// validateDatabaseNameGlobs returns an invalid argument error if a glob is malformed
+func validateDatabaseNameGlobs(globs ...string) error {
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid database name pattern %q: %v", glob, err)
+		}
+	}
+	return nil
+}
+
+// matchesDatabaseNameGlobs reports whether a database name matches the filter, an empty filter
+// matching all names, and none of the exclusions
+func matchesDatabaseNameGlobs(name, filter string, exclude []string) bool {
+	if filter != "" {
+		if matched, _ := path.Match(filter, name); !matched {
+			return false
+		}
+	}
+	for _, glob := range exclude {
+		if matched, _ := path.Match(glob, name); matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,318 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/transformation"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListLookupTables lists the lookup tables of a tenant, without their entries
+func (s *Server) ListLookupTables(ctx context.Context, req *corev1.ListLookupTablesRequest) (*corev1.ListLookupTablesResponse, error) {
+	defer s.trackOperation()()
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	tables, err := transformationService.ListLookupTables(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list lookup tables: %v", err)
+	}
+
+	protoTables := make([]*corev1.LookupTable, len(tables))
+	for i, table := range tables {
+		protoTables[i] = lookupTableToProto(table)
+	}
+
+	return &corev1.ListLookupTablesResponse{
+		LookupTables: protoTables,
+	}, nil
+}
+
+// ShowLookupTable returns a lookup table of a tenant with its entries
+func (s *Server) ShowLookupTable(ctx context.Context, req *corev1.ShowLookupTableRequest) (*corev1.ShowLookupTableResponse, error) {
+	defer s.trackOperation()()
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	table, err := transformationService.GetLookupTable(ctx, req.TenantId, req.LookupTableName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "lookup table not found: %v", err)
+	}
+
+	return &corev1.ShowLookupTableResponse{
+		LookupTable: lookupTableToProto(table),
+	}, nil
+}
+
+// AddLookupTable creates a lookup table from the given entries, or from a database table whose
+// rows are loaded at once
+func (s *Server) AddLookupTable(ctx context.Context, req *corev1.AddLookupTableRequest) (*corev1.AddLookupTableResponse, error) {
+	defer s.trackOperation()()
+
+	if len(req.Entries) > 0 && req.Source != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "a lookup table has either entries or a source table, not both")
+	}
+
+	table := &transformation.LookupTable{
+		TenantID:        req.TenantId,
+		Name:            req.LookupTableName,
+		Description:     req.LookupTableDescription,
+		Entries:         req.Entries,
+		CacheTTLSeconds: transformation.DefaultLookupCacheTTL,
+		OwnerID:         req.OwnerId,
+	}
+	if req.CacheTtlSeconds != nil {
+		table.CacheTTLSeconds = int(*req.CacheTtlSeconds)
+	}
+
+	if req.Source != nil {
+		source, err := s.resolveLookupTableSource(ctx, req.TenantId, req.Source)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		entries, err := s.fetchLookupEntries(ctx, source)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to load the entries of the lookup table: %v", err)
+		}
+		refreshed := time.Now().UTC()
+		table.Source = source
+		table.Entries = entries
+		table.Refreshed = &refreshed
+	}
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	createdTable, err := transformationService.CreateLookupTable(ctx, table)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "failed to create lookup table: %v", err)
+	}
+
+	// A table deleted and added again may still be cached under its name
+	s.refreshLookupTableCache(ctx, createdTable.Name)
+
+	return &corev1.AddLookupTableResponse{
+		Message:     fmt.Sprintf("Lookup table created with %d entries", createdTable.EntryCount),
+		Success:     true,
+		LookupTable: lookupTableToProto(createdTable),
+		Status:      commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ModifyLookupTable changes the description, entries or cache TTL of a lookup table. The
+// entries of a table with a source are replaced when it is refreshed.
+func (s *Server) ModifyLookupTable(ctx context.Context, req *corev1.ModifyLookupTableRequest) (*corev1.ModifyLookupTableResponse, error) {
+	defer s.trackOperation()()
+
+	update := &transformation.LookupTableUpdate{
+		Description:    req.LookupTableDescription,
+		SetEntries:     req.SetEntries,
+		RemoveKeys:     req.RemoveKeys,
+		ReplaceEntries: req.ReplaceEntries,
+	}
+	if req.CacheTtlSeconds != nil {
+		ttl := int(*req.CacheTtlSeconds)
+		update.CacheTTLSeconds = &ttl
+	}
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	updatedTable, err := transformationService.UpdateLookupTable(ctx, req.TenantId, req.LookupTableName, update)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to update lookup table: %v", err)
+	}
+
+	s.refreshLookupTableCache(ctx, updatedTable.Name)
+
+	return &corev1.ModifyLookupTableResponse{
+		Message:     "Lookup table updated successfully",
+		Success:     true,
+		LookupTable: lookupTableToProto(updatedTable),
+		Status:      commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// DeleteLookupTable deletes a lookup table. Mapping rules looking values up in it fail until a
+// lookup table with its name is added again.
+func (s *Server) DeleteLookupTable(ctx context.Context, req *corev1.DeleteLookupTableRequest) (*corev1.DeleteLookupTableResponse, error) {
+	defer s.trackOperation()()
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	if err := transformationService.DeleteLookupTable(ctx, req.TenantId, req.LookupTableName); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "failed to delete lookup table: %v", err)
+	}
+
+	s.refreshLookupTableCache(ctx, req.LookupTableName)
+
+	return &corev1.DeleteLookupTableResponse{
+		Message: "Lookup table deleted successfully",
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// RefreshLookupTable reloads the entries of a lookup table from its source table, if it has one,
+// and drops the entries the transformation service cached, so that the next lookups use the
+// current entries
+func (s *Server) RefreshLookupTable(ctx context.Context, req *corev1.RefreshLookupTableRequest) (*corev1.RefreshLookupTableResponse, error) {
+	defer s.trackOperation()()
+
+	transformationService := transformation.NewService(s.engine.db, s.engine.logger)
+	table, err := transformationService.GetLookupTable(ctx, req.TenantId, req.LookupTableName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "lookup table not found: %v", err)
+	}
+
+	message := "Lookup table cache refreshed"
+	if table.Source != nil {
+		entries, err := s.fetchLookupEntries(ctx, table.Source)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to reload the entries of the lookup table: %v", err)
+		}
+		table, err = transformationService.SetLookupTableEntries(ctx, req.TenantId, table.Name, entries, time.Now().UTC())
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to update lookup table: %v", err)
+		}
+		message = fmt.Sprintf("Lookup table reloaded with %d entries from %s", table.EntryCount, table.Source.TableName)
+	}
+
+	if err := s.invalidateLookupTableCache(ctx, table.Name); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to refresh the cache of the transformation service: %v", err)
+	}
+
+	return &corev1.RefreshLookupTableResponse{
+		Message:     message,
+		Success:     true,
+		LookupTable: lookupTableToProto(table),
+		Status:      commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// resolveLookupTableSource checks the source table of a lookup table and resolves its database
+func (s *Server) resolveLookupTableSource(ctx context.Context, tenantID string, source *corev1.LookupTableSource) (*transformation.LookupTableSource, error) {
+	if source.DatabaseName == "" || source.TableName == "" || source.KeyColumn == "" || source.ValueColumn == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "the source of a lookup table needs a database, a table, a key column and a value column")
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, tenantID, source.WorkspaceName)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+	db, err := database.NewService(s.engine.db, s.engine.logger).Get(ctx, tenantID, workspaceID, source.DatabaseName)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "database not found: %v", err)
+	}
+
+	return &transformation.LookupTableSource{
+		WorkspaceName: source.WorkspaceName,
+		DatabaseName:  source.DatabaseName,
+		DatabaseID:    db.ID,
+		TableName:     source.TableName,
+		KeyColumn:     source.KeyColumn,
+		ValueColumn:   source.ValueColumn,
+	}, nil
+}
+
+// fetchLookupEntries reads the rows of the source table of a lookup table through the anchor
+// service and builds its entries. One row more than a lookup table holds is read, so that larger
+// tables are rejected rather than truncated.
+func (s *Server) fetchLookupEntries(ctx context.Context, source *transformation.LookupTableSource) (map[string]string, error) {
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+
+	options, err := json.Marshal(map[string]interface{}{"limit": transformation.MaxLookupEntries + 1})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := anchorClient.FetchData(ctx, &anchorv1.FetchDataRequest{
+		DatabaseId: source.DatabaseID,
+		TableName:  source.TableName,
+		Options:    options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(resp.Data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode the rows of table %s: %v", source.TableName, err)
+	}
+	return transformation.LookupEntriesFromRows(rows, source.KeyColumn, source.ValueColumn)
+}
+
+// invalidateLookupTableCache drops the entries of a lookup table cached by the transformation
+// service. Other instances of the service read the new entries when their cache TTL expires.
+func (s *Server) invalidateLookupTableCache(ctx context.Context, name string) error {
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		return err
+	}
+	resp, err := transformationClient.RefreshLookupTable(ctx, &transformationv1.RefreshLookupTableRequest{
+		LookupTableName: name,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		return fmt.Errorf("%s", resp.StatusMessage)
+	}
+	return nil
+}
+
+// refreshLookupTableCache drops the cached entries of a lookup table after a change, only
+// logging a failure, as the cache TTL bounds how long the old entries are used
+func (s *Server) refreshLookupTableCache(ctx context.Context, name string) {
+	if err := s.invalidateLookupTableCache(ctx, name); err != nil {
+		s.engine.logger.Warnf("Failed to refresh the cached entries of lookup table %s: %v", name, err)
+	}
+}
+
+// lookupTableToProto converts a lookup table to protobuf; tables of lists have no entries
+func lookupTableToProto(table *transformation.LookupTable) *corev1.LookupTable {
+	protoTable := &corev1.LookupTable{
+		TenantId:               table.TenantID,
+		LookupTableId:          table.ID,
+		LookupTableName:        table.Name,
+		LookupTableDescription: table.Description,
+		Entries:                table.Entries,
+		EntryCount:             int32(table.EntryCount),
+		CacheTtlSeconds:        int32(table.CacheTTLSeconds),
+		OwnerId:                table.OwnerID,
+	}
+	if table.Source != nil {
+		protoTable.Source = &corev1.LookupTableSource{
+			WorkspaceName: table.Source.WorkspaceName,
+			DatabaseName:  table.Source.DatabaseName,
+			TableName:     table.Source.TableName,
+			KeyColumn:     table.Source.KeyColumn,
+			ValueColumn:   table.Source.ValueColumn,
+			DatabaseId:    table.Source.DatabaseID,
+		}
+	}
+	if table.Refreshed != nil {
+		protoTable.Refreshed = table.Refreshed.Format(time.RFC3339)
+	}
+	return protoTable
+}
@@ -12,6 +12,7 @@ import (
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
 	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/pkg/rowfilter"
 	"github.com/redbco/redb-open/pkg/unifiedmodel"
 	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
 	"github.com/redbco/redb-open/services/core/internal/services/database"
@@ -261,6 +262,30 @@ func (s *Server) ShowMapping(ctx context.Context, req *corev1.ShowMappingRequest
 func (s *Server) AddMapping(ctx context.Context, req *corev1.AddMappingRequest) (*corev1.AddMappingResponse, error) {
 	defer s.trackOperation()()
 
+	// Validate the row filter before creating the mapping
+	rowFilter, err := parseRowFilter(req.GetRowFilter())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	resp, err := s.addMapping(ctx, req)
+	if err != nil || rowFilter == "" {
+		return resp, err
+	}
+
+	// Store the row filter on the created mapping
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	if err := mappingService.SetRowFilter(ctx, resp.Mapping.MappingId, rowFilter); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "mapping created, but %v", err)
+	}
+	resp.Mapping.RowFilter = rowFilter
+	return resp, nil
+}
+
+// addMapping creates a mapping of a unified add request, routed on its scope and target
+func (s *Server) addMapping(ctx context.Context, req *corev1.AddMappingRequest) (*corev1.AddMappingResponse, error) {
 	// Validate scope
 	if req.Scope != "database" && req.Scope != "table" {
 		s.engine.IncrementErrors()
@@ -270,6 +295,12 @@ func (s *Server) AddMapping(ctx context.Context, req *corev1.AddMappingRequest)
 	// Check if target is MCP resource
 	isMCPTarget := strings.HasPrefix(req.Target, "mcp://")
 
+	// Only the matcher of database-to-database mappings pairs tables
+	if len(req.TablePins) > 0 && (req.Scope != "database" || isMCPTarget) {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "table pins require a database scope mapping to a database")
+	}
+
 	// Parse source
 	sourceDB, sourceTable, err := s.parseSourceTarget(req.Source)
 	if err != nil {
@@ -317,6 +348,12 @@ func (s *Server) AddMapping(ctx context.Context, req *corev1.AddMappingRequest)
 func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappingRequest) (*corev1.AddMappingResponse, error) {
 	defer s.trackOperation()()
 
+	return s.addTableMapping(ctx, req, true)
+}
+
+// addTableMapping creates a table mapping, generating its rules from a match of the two tables
+// when generateRules is set
+func (s *Server) addTableMapping(ctx context.Context, req *corev1.AddTableMappingRequest, generateRules bool) (*corev1.AddMappingResponse, error) {
 	// Get workspace service
 	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
 
@@ -347,6 +384,25 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the generated rules are held to
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Resolve the match profile tuning the matcher, with the options the request overrides
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), "table")
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	profile, overridden, err := applyMatchOverrides(profile, req.MatchOverrides)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
 	// Build resource URIs and mapping type
 	sourceType := "table"
 	targetType := "table"
@@ -378,6 +434,7 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "unified model service not available")
 	}
+	var matchReport *corev1.MatchReport
 
 	// Convert source database schema to UnifiedModel
 	var sourceUM *unifiedmodelv1.UnifiedModel
@@ -436,22 +493,13 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 	}
 
 	// Use unified model service to match schemas
-	if sourceUM != nil && targetUM != nil {
+	if generateRules && sourceUM != nil && targetUM != nil {
 		matchReq := &unifiedmodelv1.MatchUnifiedModelsEnrichedRequest{
 			SourceUnifiedModel: sourceUM,
 			SourceEnrichment:   sourceEnrichment,
 			TargetUnifiedModel: targetUM,
 			TargetEnrichment:   targetEnrichment,
-			Options: &unifiedmodelv1.MatchOptions{
-				NameSimilarityThreshold:  0.3, // Lower threshold to allow more matches
-				PoorMatchThreshold:       0.2,
-				NameWeight:               0.4,
-				TypeWeight:               0.3,
-				ClassificationWeight:     0.2,
-				PrivilegedDataWeight:     0.1,
-				TableStructureWeight:     0.3,
-				EnableCrossTableMatching: false,
-			},
+			Options:            s.matchOptions(ctx, mappingService, workspaceID, profile),
 		}
 
 		s.engine.logger.Infof("Calling MatchUnifiedModelsEnriched with source table %s and target table %s", req.MappingSourceTableName, req.MappingTargetTableName)
@@ -461,11 +509,18 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 		if err != nil {
 			s.engine.logger.Warnf("Failed to match schemas using unified model service: %v", err)
 		} else {
-			// Create mapping rules for matched columns
+			// Create mapping rules for matched columns, recording the outcome of each match
 			s.engine.logger.Infof("Creating mapping rules for matched columns: %v", matchResp.TableMatches)
+			report := newMatchReport(profile, overridden, matchResp)
 			for _, tableMatch := range matchResp.TableMatches {
+				keys := keyColumnMetadata(tableMatch)
 				for _, columnMatch := range tableMatch.ColumnMatches {
-					if columnMatch.Score >= 0.5 && !columnMatch.IsPoorMatch && !columnMatch.IsUnmatched {
+					setOutcome := func(outcome, ruleName, detail string) {
+						report.SetOutcome(tableMatch.SourceTable, columnMatch.SourceColumn, tableMatch.TargetTable, columnMatch.TargetColumn, outcome, ruleName, detail)
+					}
+					if outcome := mapping.RuleOutcome(profile.Options, columnMatch.Score, columnMatch.IsPoorMatch, columnMatch.IsUnmatched); outcome != "" {
+						setOutcome(outcome, "", "")
+					} else {
 						// Create mapping rule for this column match
 						baseRuleName := fmt.Sprintf("%s_%s_to_%s_%s",
 							tableMatch.SourceTable, columnMatch.SourceColumn,
@@ -500,8 +555,13 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 							"match_score":          columnMatch.Score,
 							"type_compatible":      columnMatch.IsTypeCompatible,
 							"match_type":           "auto_generated",
+							"match_profile":        profile.Name,
+							"match_reasons":        columnMatch.Reasons,
 							"generated_at":         time.Now().Format(time.RFC3339),
 						}
+						if columnKeys := keys[columnMatch.SourceColumn]; len(columnKeys) > 0 {
+							metadata["key_matches"] = columnKeys
+						}
 
 						// Create empty transformation options (as requested)
 						transformationOptions := map[string]interface{}{}
@@ -510,6 +570,14 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 						sourceURI := s.buildResourceURI("column", sourceDB.ID, tableMatch.SourceTable, columnMatch.SourceColumn)
 						targetURI := s.buildResourceURI("column", targetDB.ID, tableMatch.TargetTable, columnMatch.TargetColumn)
 
+						// Apply the transformation policies of the tenant
+						transformationName, transformationOptions, err := policies.enforce(ctx, []string{sourceURI}, []string{targetURI}, "direct_mapping", transformationOptions, metadata)
+						if err != nil {
+							s.engine.logger.Warnf("Skipping mapping rule %s: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeBlocked, ruleName, err.Error())
+							continue
+						}
+
 						// Create the mapping rule
 						_, err = mappingService.CreateMappingRule(ctx, req.TenantId, workspaceID, ruleName,
 							fmt.Sprintf("Auto-generated rule for %s.%s.%s -> %s.%s.%s",
@@ -517,13 +585,14 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 								req.MappingTargetDatabaseName, tableMatch.TargetTable, columnMatch.TargetColumn),
 							sourceURI,
 							targetURI,
-							"direct_mapping", // Default transformation
+							transformationName,
 							transformationOptions,
 							metadata,
 							req.OwnerId)
 
 						if err != nil {
 							s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
 							continue
 						}
 
@@ -531,10 +600,14 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 						err = mappingService.AttachMappingRule(ctx, req.TenantId, workspaceID, req.MappingName, ruleName, nil)
 						if err != nil {
 							s.engine.logger.Warnf("Failed to attach mapping rule %s to mapping: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
+							continue
 						}
+						setOutcome(mapping.MatchOutcomeRuleCreated, ruleName, "")
 					}
 				}
 			}
+			matchReport = s.storeMatchReport(ctx, mappingService, createdMapping.ID, req.MappingName, report)
 		}
 	}
 
@@ -554,10 +627,11 @@ func (s *Server) AddTableMapping(ctx context.Context, req *corev1.AddTableMappin
 	}
 
 	return &corev1.AddMappingResponse{
-		Message: "Table mapping created successfully",
-		Success: true,
-		Mapping: protoMapping,
-		Status:  commonv1.Status_STATUS_SUCCESS,
+		Message:     "Table mapping created successfully",
+		Success:     true,
+		Mapping:     protoMapping,
+		Status:      commonv1.Status_STATUS_SUCCESS,
+		MatchReport: matchReport,
 	}, nil
 }
 
@@ -635,7 +709,7 @@ func (s *Server) AddTableMappingWithDeploy(ctx context.Context, req *corev1.AddT
 			return nil, status.Errorf(codes.Internal, "failed to serialize filtered schema: %v", err)
 		}
 
-		convertedSchemaStr, _, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDB.Type, targetDB.Type)
+		convertedSchemaStr, _, err := s.convertSchemaViaUnifiedModel(ctx, string(schemaJSON), sourceDB.Type, targetDB.Type, nil)
 		if err != nil {
 			s.engine.IncrementErrors()
 			return nil, status.Errorf(codes.Internal, "failed to convert schema: %v", err)
@@ -749,6 +823,26 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the generated rules are held to
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Resolve the match profile tuning the matcher; this legacy database mapping matches the
+	// columns of the databases like those of two tables
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), "table")
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	profile, overridden, err := applyMatchOverrides(profile, req.MatchOverrides)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
 	// Build resource URIs and mapping type
 	sourceType := "database"
 	targetType := "database"
@@ -778,6 +872,7 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "unified model service not available")
 	}
+	var matchReport *corev1.MatchReport
 
 	// Convert source database schema to UnifiedModel
 	var sourceUM *unifiedmodelv1.UnifiedModel
@@ -836,16 +931,7 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 			SourceEnrichment:   sourceEnrichment,
 			TargetUnifiedModel: targetUM,
 			TargetEnrichment:   targetEnrichment,
-			Options: &unifiedmodelv1.MatchOptions{
-				NameSimilarityThreshold:  0.3, // Lower threshold to allow more matches
-				PoorMatchThreshold:       0.2,
-				NameWeight:               0.4,
-				TypeWeight:               0.3,
-				ClassificationWeight:     0.2,
-				PrivilegedDataWeight:     0.1,
-				TableStructureWeight:     0.3,
-				EnableCrossTableMatching: false,
-			},
+			Options:            s.matchOptions(ctx, mappingService, workspaceID, profile),
 		}
 
 		s.engine.logger.Infof("Calling MatchUnifiedModelsEnriched with %d source tables and %d target tables", len(sourceUM.Tables), len(targetUM.Tables))
@@ -855,11 +941,18 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 		if err != nil {
 			s.engine.logger.Warnf("Failed to match schemas using unified model service: %v", err)
 		} else {
-			// Create mapping rules for matched columns
+			// Create mapping rules for matched columns, recording the outcome of each match
 			s.engine.logger.Infof("Creating mapping rules for matched columns: %v", matchResp.TableMatches)
+			report := newMatchReport(profile, overridden, matchResp)
 			for _, tableMatch := range matchResp.TableMatches {
+				keys := keyColumnMetadata(tableMatch)
 				for _, columnMatch := range tableMatch.ColumnMatches {
-					if columnMatch.Score >= 0.5 && !columnMatch.IsPoorMatch && !columnMatch.IsUnmatched {
+					setOutcome := func(outcome, ruleName, detail string) {
+						report.SetOutcome(tableMatch.SourceTable, columnMatch.SourceColumn, tableMatch.TargetTable, columnMatch.TargetColumn, outcome, ruleName, detail)
+					}
+					if outcome := mapping.RuleOutcome(profile.Options, columnMatch.Score, columnMatch.IsPoorMatch, columnMatch.IsUnmatched); outcome != "" {
+						setOutcome(outcome, "", "")
+					} else {
 						// Create mapping rule for this column match
 						baseRuleName := fmt.Sprintf("%s_%s_to_%s_%s",
 							tableMatch.SourceTable, columnMatch.SourceColumn,
@@ -894,8 +987,13 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 							"match_score":          columnMatch.Score,
 							"type_compatible":      columnMatch.IsTypeCompatible,
 							"match_type":           "auto_generated",
+							"match_profile":        profile.Name,
+							"match_reasons":        columnMatch.Reasons,
 							"generated_at":         time.Now().Format(time.RFC3339),
 						}
+						if columnKeys := keys[columnMatch.SourceColumn]; len(columnKeys) > 0 {
+							metadata["key_matches"] = columnKeys
+						}
 
 						// Create empty transformation options (as requested)
 						transformationOptions := map[string]interface{}{}
@@ -904,6 +1002,14 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 						sourceURI := s.buildResourceURI("column", sourceDB.ID, tableMatch.SourceTable, columnMatch.SourceColumn)
 						targetURI := s.buildResourceURI("column", targetDB.ID, tableMatch.TargetTable, columnMatch.TargetColumn)
 
+						// Apply the transformation policies of the tenant
+						transformationName, transformationOptions, err := policies.enforce(ctx, []string{sourceURI}, []string{targetURI}, "direct_mapping", transformationOptions, metadata)
+						if err != nil {
+							s.engine.logger.Warnf("Skipping mapping rule %s: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeBlocked, ruleName, err.Error())
+							continue
+						}
+
 						// Create the mapping rule
 						_, err = mappingService.CreateMappingRule(ctx, req.TenantId, workspaceID, ruleName,
 							fmt.Sprintf("Auto-generated rule for %s.%s.%s -> %s.%s.%s",
@@ -911,13 +1017,14 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 								req.MappingTargetDatabaseName, tableMatch.TargetTable, columnMatch.TargetColumn),
 							sourceURI,
 							targetURI,
-							"direct_mapping", // Default transformation
+							transformationName,
 							transformationOptions,
 							metadata,
 							req.OwnerId)
 
 						if err != nil {
 							s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
 							continue
 						}
 
@@ -925,10 +1032,14 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 						err = mappingService.AttachMappingRule(ctx, req.TenantId, workspaceID, req.MappingName, ruleName, nil)
 						if err != nil {
 							s.engine.logger.Warnf("Failed to attach mapping rule %s to mapping: %v", ruleName, err)
+							setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
+							continue
 						}
+						setOutcome(mapping.MatchOutcomeRuleCreated, ruleName, "")
 					}
 				}
 			}
+			matchReport = s.storeMatchReport(ctx, mappingService, createdMapping.ID, req.MappingName, report)
 		}
 	}
 
@@ -948,10 +1059,11 @@ func (s *Server) AddDatabaseMapping(ctx context.Context, req *corev1.AddDatabase
 	}
 
 	return &corev1.AddMappingResponse{
-		Message: "Database mapping created successfully",
-		Success: true,
-		Mapping: protoMapping,
-		Status:  commonv1.Status_STATUS_SUCCESS,
+		Message:     "Database mapping created successfully",
+		Success:     true,
+		Mapping:     protoMapping,
+		Status:      commonv1.Status_STATUS_SUCCESS,
+		MatchReport: matchReport,
 	}, nil
 }
 
@@ -1023,12 +1135,67 @@ func (s *Server) ModifyMapping(ctx context.Context, req *corev1.ModifyMappingReq
 		updates["policy_ids"] = []string{*req.PolicyId}
 	}
 
+	// Replace the conflict keys, the row filter and the table pins before renaming the mapping
+	var existingMapping *mapping.Mapping
+	setTablePins := len(req.TablePins) > 0 || req.GetClearTablePins()
+	if len(req.ConflictKeys) > 0 || req.GetClearConflictKeys() || req.RowFilter != nil || setTablePins {
+		existingMapping, err = mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+		}
+	}
+	if req.RowFilter != nil {
+		rowFilter, err := parseRowFilter(*req.RowFilter)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		if err := mappingService.SetRowFilter(ctx, existingMapping.ID, rowFilter); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+	if len(req.ConflictKeys) > 0 || req.GetClearConflictKeys() {
+		conflictKeys := make(map[string][]string, len(req.ConflictKeys))
+		for _, key := range req.ConflictKeys {
+			conflictKeys[key.TargetTable] = key.Columns
+		}
+		if err := mappingService.SetConflictKeys(ctx, existingMapping.ID, conflictKeys); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "failed to update conflict keys: %v", err)
+		}
+	}
+	if setTablePins {
+		if existingMapping.SourceType != "database" || existingMapping.TargetType != "database" {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "table pins require a database-to-database mapping")
+		}
+		tablePins, err := tablePinsFromProto(req.TablePins)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		if err := mappingService.SetTablePins(ctx, existingMapping.ID, tablePins); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
 	// Update the mapping
 	updatedMapping, err := mappingService.Update(ctx, req.TenantId, workspaceID, req.MappingName, updates)
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to update mapping: %v", err)
 	}
+	if len(updates) > 0 {
+		// Update does not return the mapping object, fetch the mapping again
+		updatedMapping, err = mappingService.Get(ctx, req.TenantId, workspaceID, updatedMapping.Name)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to get updated mapping: %v", err)
+		}
+	}
 
 	// Convert to protobuf format
 	protoMapping, err := s.mappingToProto(updatedMapping)
@@ -1137,6 +1304,12 @@ func (s *Server) DetachMappingRule(ctx context.Context, req *corev1.DetachMappin
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Get the rule before detaching it, for the match feedback of a generated rule
+	rule, err := mappingService.GetMappingRuleByName(ctx, req.TenantId, workspaceID, req.MappingRuleName)
+	if err != nil {
+		s.engine.logger.Warnf("Failed to get mapping rule for match feedback: %v", err)
+	}
+
 	// Detach the mapping rule
 	err = mappingService.DetachMappingRule(ctx, req.TenantId, workspaceID, req.MappingName, req.MappingRuleName)
 	if err != nil {
@@ -1144,6 +1317,11 @@ func (s *Server) DetachMappingRule(ctx context.Context, req *corev1.DetachMappin
 		return nil, status.Errorf(codes.Internal, "failed to detach mapping rule: %v", err)
 	}
 
+	// Removing a generated rule from a mapping rejects the match it was generated for
+	if rule != nil {
+		s.recordRuleRejection(ctx, mappingService, workspaceID, rule)
+	}
+
 	// Invalidate the mapping's validation status
 	mappingObj, err := mappingService.GetByName(ctx, req.TenantId, workspaceID, req.MappingName)
 	if err != nil {
@@ -1257,6 +1435,47 @@ func (s *Server) ShowMappingRule(ctx context.Context, req *corev1.ShowMappingRul
 	}, nil
 }
 
+// validateTransformationChain checks the transformations of a chain exist and fit together, and
+// returns the type of the chain as a whole
+func (s *Server) validateTransformationChain(ctx context.Context, client transformationv1.TransformationServiceClient, chain []string) (string, error) {
+	steps := make([]mapping.TransformationStep, 0, len(chain))
+	for _, name := range chain {
+		metadataResp, err := client.GetTransformationMetadata(ctx, &transformationv1.GetTransformationMetadataRequest{
+			TransformationName: name,
+		})
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "transformation '%s' of the chain does not exist or is invalid: %v", name, err)
+		}
+		if metadataResp.Status != commonv1.Status_STATUS_SUCCESS || metadataResp.Metadata == nil {
+			return "", status.Errorf(codes.InvalidArgument, "transformation '%s' of the chain does not exist: %s", name, metadataResp.StatusMessage)
+		}
+		steps = append(steps, mapping.TransformationStep{
+			Name:       name,
+			Type:       metadataResp.Metadata.Type,
+			InputType:  metadataResp.Metadata.InputType,
+			OutputType: metadataResp.Metadata.OutputType,
+		})
+	}
+
+	chainType, err := mapping.ValidateTransformationChain(steps)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid transformation chain: %v", err)
+	}
+	return chainType, nil
+}
+
+// parseRowFilter checks the syntax of a row filter and returns it trimmed, "" for no filter
+func parseRowFilter(expression string) (string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return "", nil
+	}
+	filter, err := rowfilter.Parse(expression)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid row filter: %v", err)
+	}
+	return filter.String(), nil
+}
+
 func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleRequest) (*corev1.AddMappingRuleResponse, error) {
 	defer s.trackOperation()()
 
@@ -1273,6 +1492,13 @@ func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleR
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the rule is held to
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
 	// Convert transformation options to map[string]interface{}
 	transformationOptions := make(map[string]interface{})
 	if req.MappingRuleTransformationOptions != "" {
@@ -1291,6 +1517,16 @@ func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleR
 		}
 	}
 
+	// The rule only maps the rows matching its row filter
+	rowFilter, err := parseRowFilter(req.MappingRuleRowFilter)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	if rowFilter != "" {
+		metadata["row_filter"] = rowFilter
+	}
+
 	// Determine source and target URIs from new or legacy fields
 	var sourceURIs []string
 	var targetURIs []string
@@ -1321,10 +1557,53 @@ func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleR
 		return nil, status.Errorf(codes.InvalidArgument, "invalid cardinality: %v", err)
 	}
 
+	// A transformation chain is stored as the name of a transformation applying its steps in order
+	requestedTransformation := req.MappingRuleTransformationName
+	if len(req.MappingRuleTransformationChain) > 0 {
+		if requestedTransformation != "" {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "specify either a transformation name or a transformation chain, not both")
+		}
+		requestedTransformation, err = mapping.TransformationChainName(req.MappingRuleTransformationChain)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	// Apply the transformation policies of the tenant, which may insert the transformation
+	transformationName, transformationOptions, err := policies.enforce(ctx, sourceURIs, targetURIs, requestedTransformation, transformationOptions, metadata)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+
 	// Validate transformation if provided
 	var transformationType string
-	if req.MappingRuleTransformationName != "" {
-		transformationName := req.MappingRuleTransformationName
+	if chain := mapping.TransformationChain(transformationName); chain != nil {
+		s.engine.logger.Infof("Validating transformation chain: %s", strings.Join(chain, " -> "))
+
+		transformationClient, err := s.getTransformationClient()
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
+		}
+
+		transformationType, err = s.validateTransformationChain(ctx, transformationClient, chain)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+
+		// Validate the chain supports the cardinality
+		if err := validateTransformationCardinality(transformationType, cardinality); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+
+		s.engine.logger.Infof("Transformation chain '%s' validated successfully (type: %s, cardinality: %s)",
+			transformationName, transformationType, cardinality)
+	} else if transformationName != "" {
 		s.engine.logger.Infof("Validating transformation: %s", transformationName)
 
 		// Get transformation client
@@ -1362,6 +1641,10 @@ func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleR
 		s.engine.logger.Infof("Transformation '%s' validated successfully (type: %s, cardinality: %s)",
 			transformationName, transformationType, cardinality)
 	}
+	if err := validateSourceCombination(cardinality, transformationName); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	// Resolve source URIs to item IDs
 	sourceItemIDs := make([]string, len(sourceURIs))
@@ -1407,7 +1690,7 @@ func (s *Server) AddMappingRule(ctx context.Context, req *corev1.AddMappingRuleR
 
 	createdRule, err := mappingService.CreateMappingRule(
 		ctx, req.TenantId, workspaceID, req.MappingRuleName, req.MappingRuleDescription,
-		legacySource, legacyTarget, req.MappingRuleTransformationName,
+		legacySource, legacyTarget, transformationName,
 		transformationOptions, metadata, req.OwnerId)
 	if err != nil {
 		s.engine.IncrementErrors()
@@ -1472,6 +1755,13 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the rule is held to
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
 	// Get the existing mapping rule to extract information for validation
 	existingRule, err := mappingService.GetMappingRuleByName(ctx, req.TenantId, workspaceID, req.MappingRuleName)
 	if err != nil {
@@ -1479,9 +1769,25 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 		return nil, status.Errorf(codes.NotFound, "mapping rule not found: %v", err)
 	}
 
+	// A transformation chain replaces the transformation of the rule, stored as the name of a
+	// transformation applying its steps in order
+	newTransformationName := req.MappingRuleTransformationName
+	if req.MappingRuleTransformationChain != nil {
+		if req.MappingRuleTransformationName != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "specify either a transformation name or a transformation chain, not both")
+		}
+		chainName, err := mapping.TransformationChainName(req.MappingRuleTransformationChain.TransformationNames)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		newTransformationName = &chainName
+	}
+
 	// Validate transformation if being changed
-	if req.MappingRuleTransformationName != nil && *req.MappingRuleTransformationName != "" {
-		transformationName := *req.MappingRuleTransformationName
+	if newTransformationName != nil && *newTransformationName != "" {
+		transformationName := *newTransformationName
 		s.engine.logger.Infof("Validating transformation: %s", transformationName)
 
 		// Get transformation client
@@ -1492,91 +1798,97 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 		}
 
 		{
-			// Call GetTransformationMetadata to validate transformation exists
-			metadataReq := &transformationv1.GetTransformationMetadataRequest{
-				TransformationName: transformationName,
-			}
+			var transformationType string
+			if chain := mapping.TransformationChain(transformationName); chain != nil {
+				transformationType, err = s.validateTransformationChain(ctx, transformationClient, chain)
+				if err != nil {
+					s.engine.IncrementErrors()
+					return nil, err
+				}
+			} else {
+				// Call GetTransformationMetadata to validate transformation exists
+				metadataReq := &transformationv1.GetTransformationMetadataRequest{
+					TransformationName: transformationName,
+				}
 
-			metadataResp, err := transformationClient.GetTransformationMetadata(ctx, metadataReq)
-			if err != nil {
-				s.engine.IncrementErrors()
-				return nil, status.Errorf(codes.InvalidArgument, "transformation '%s' does not exist or is invalid: %v", transformationName, err)
-			}
+				metadataResp, err := transformationClient.GetTransformationMetadata(ctx, metadataReq)
+				if err != nil {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument, "transformation '%s' does not exist or is invalid: %v", transformationName, err)
+				}
 
-			// Check if transformation was found (check Status field)
-			if metadataResp.Status != commonv1.Status_STATUS_SUCCESS || metadataResp.Metadata == nil {
-				s.engine.IncrementErrors()
-				return nil, status.Errorf(codes.InvalidArgument, "transformation '%s' does not exist: %s", transformationName, metadataResp.StatusMessage)
+				// Check if transformation was found (check Status field)
+				if metadataResp.Status != commonv1.Status_STATUS_SUCCESS || metadataResp.Metadata == nil {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument, "transformation '%s' does not exist: %s", transformationName, metadataResp.StatusMessage)
+				}
+				transformationType = metadataResp.Metadata.Type
 			}
 
 			// Validate transformation requirements based on type
-			if metadataResp.Metadata != nil {
-				transformationType := metadataResp.Metadata.Type
-
-				// Determine final source and target (use existing if not being updated)
-				// Extract from metadata
-				var finalSource, finalTarget string
-				if existingRule.Metadata != nil {
-					if src, ok := existingRule.Metadata["source_resource_uri"].(string); ok {
-						finalSource = src
-					}
-					if tgt, ok := existingRule.Metadata["target_resource_uri"].(string); ok {
-						finalTarget = tgt
-					}
+			// Determine final source and target (use existing if not being updated)
+			// Extract from metadata
+			var finalSource, finalTarget string
+			if existingRule.Metadata != nil {
+				if src, ok := existingRule.Metadata["source_resource_uri"].(string); ok {
+					finalSource = src
 				}
-
-				if req.MappingRuleSource != nil && *req.MappingRuleSource != "" {
-					finalSource = *req.MappingRuleSource
+				if tgt, ok := existingRule.Metadata["target_resource_uri"].(string); ok {
+					finalTarget = tgt
 				}
+			}
 
-				if req.MappingRuleTarget != nil && *req.MappingRuleTarget != "" {
-					finalTarget = *req.MappingRuleTarget
-				}
+			if req.MappingRuleSource != nil && *req.MappingRuleSource != "" {
+				finalSource = *req.MappingRuleSource
+			}
 
-				// Validate based on transformation type
-				switch transformationType {
-				case "generator":
-					// Generator transformations should not have a source
-					if finalSource != "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a generator type and should not have a source column", transformationName)
-					}
-					// Generator transformations must have a target
-					if finalTarget == "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a generator type and requires a target column", transformationName)
-					}
-				case "null_returning":
-					// Null-returning transformations should not have a target
-					if finalTarget != "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a null-returning type and should not have a target column", transformationName)
-					}
-					// Null-returning transformations must have a source
-					if finalSource == "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a null-returning type and requires a source column", transformationName)
-					}
-				case "passthrough":
-					// Passthrough transformations require both source and target
-					if finalSource == "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a passthrough type and requires a source column", transformationName)
-					}
-					if finalTarget == "" {
-						s.engine.IncrementErrors()
-						return nil, status.Errorf(codes.InvalidArgument,
-							"transformation '%s' is a passthrough type and requires a target column", transformationName)
-					}
-				}
+			if req.MappingRuleTarget != nil && *req.MappingRuleTarget != "" {
+				finalTarget = *req.MappingRuleTarget
+			}
 
-				s.engine.logger.Infof("Transformation '%s' validated successfully (type: %s)", transformationName, transformationType)
+			// Validate based on transformation type
+			switch transformationType {
+			case "generator":
+				// Generator transformations should not have a source
+				if finalSource != "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a generator type and should not have a source column", transformationName)
+				}
+				// Generator transformations must have a target
+				if finalTarget == "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a generator type and requires a target column", transformationName)
+				}
+			case "null_returning":
+				// Null-returning transformations should not have a target
+				if finalTarget != "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a null-returning type and should not have a target column", transformationName)
+				}
+				// Null-returning transformations must have a source
+				if finalSource == "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a null-returning type and requires a source column", transformationName)
+				}
+			case "passthrough":
+				// Passthrough transformations require both source and target
+				if finalSource == "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a passthrough type and requires a source column", transformationName)
+				}
+				if finalTarget == "" {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument,
+						"transformation '%s' is a passthrough type and requires a target column", transformationName)
+				}
 			}
+
+			s.engine.logger.Infof("Transformation '%s' validated successfully (type: %s)", transformationName, transformationType)
 		}
 	}
 
@@ -1620,8 +1932,8 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 	}
 
 	// Update transformation name in metadata if provided
-	if req.MappingRuleTransformationName != nil {
-		updatedMetadata["transformation_name"] = *req.MappingRuleTransformationName
+	if newTransformationName != nil {
+		updatedMetadata["transformation_name"] = *newTransformationName
 		needsMetadataUpdate = true
 	}
 
@@ -1650,6 +1962,55 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 		needsMetadataUpdate = true
 	}
 
+	// Replace the row filter of the rule if provided, an empty filter removes it
+	if req.MappingRuleRowFilter != nil {
+		rowFilter, err := parseRowFilter(*req.MappingRuleRowFilter)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		if rowFilter == "" {
+			delete(updatedMetadata, "row_filter")
+		} else {
+			updatedMetadata["row_filter"] = rowFilter
+		}
+		needsMetadataUpdate = true
+	}
+
+	// A generated rule re-pointed to other columns corrects the match it was generated for
+	var correction *matchCorrection
+	if req.MappingRuleSource != nil || req.MappingRuleTarget != nil {
+		correction = correctGeneratedRule(existingRule, updatedMetadata)
+	}
+
+	// Apply the transformation policies of the tenant to the changed rule
+	if needsMetadataUpdate {
+		transformationName, _ := updatedMetadata["transformation_name"].(string)
+		transformationOptions, _ := updatedMetadata["transformation_options"].(map[string]interface{})
+		transformationName, transformationOptions, err = policies.enforce(ctx, ruleURIs(updatedMetadata, "source"), ruleURIs(updatedMetadata, "target"),
+			transformationName, transformationOptions, updatedMetadata)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+		}
+		updatedMetadata["transformation_name"] = transformationName
+		if transformationOptions != nil {
+			updatedMetadata["transformation_options"] = transformationOptions
+		}
+	}
+
+	// A rule re-pointed to existing objects is no longer broken, one re-pointed to missing
+	// objects is
+	if needsMetadataUpdate {
+		delete(updatedMetadata, "broken")
+		if breakage := s.ruleBreakage(ctx, updatedMetadata, make(map[string]mapping.SchemaColumns)); breakage != nil {
+			if previous := existingRule.Breakage(); previous != nil {
+				breakage.Detected = previous.Detected
+			}
+			updatedMetadata["broken"] = breakage
+		}
+	}
+
 	// Add metadata to updates if it changed
 	if needsMetadataUpdate {
 		updates["mapping_rule_metadata"] = updatedMetadata
@@ -1661,6 +2022,7 @@ func (s *Server) ModifyMappingRule(ctx context.Context, req *corev1.ModifyMappin
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to update mapping rule: %v", err)
 	}
+	s.recordMatchCorrection(ctx, mappingService, workspaceID, req.MappingRuleName, correction)
 
 	// Invalidate all mappings that use this rule
 	mappings, err := mappingService.GetMappingsForRule(ctx, req.TenantId, workspaceID, req.MappingRuleName)
@@ -1711,6 +2073,10 @@ func (s *Server) DeleteMappingRule(ctx context.Context, req *corev1.DeleteMappin
 		s.engine.logger.Warnf("Failed to get mappings for rule invalidation: %v", err)
 		mappingsToInvalidate = nil
 	}
+	rule, err := mappingService.GetMappingRuleByName(ctx, req.TenantId, workspaceID, req.MappingRuleName)
+	if err != nil {
+		s.engine.logger.Warnf("Failed to get mapping rule for match feedback: %v", err)
+	}
 
 	// Delete the mapping rule
 	err = mappingService.DeleteMappingRule(ctx, req.TenantId, workspaceID, req.MappingRuleName)
@@ -1719,6 +2085,12 @@ func (s *Server) DeleteMappingRule(ctx context.Context, req *corev1.DeleteMappin
 		return nil, status.Errorf(codes.Internal, "failed to delete mapping rule: %v", err)
 	}
 
+	// Deleting a generated rule still in mappings rejects the match it was generated for; a rule
+	// detached before was rejected then
+	if rule != nil && len(mappingsToInvalidate) > 0 {
+		s.recordRuleRejection(ctx, mappingService, workspaceID, rule)
+	}
+
 	// Invalidate all mappings that used this rule
 	for _, mappingObj := range mappingsToInvalidate {
 		if err := mappingService.InvalidateMapping(ctx, mappingObj.ID); err != nil {
@@ -2075,6 +2447,8 @@ func (s *Server) addTableMappingUnified(ctx context.Context, req *corev1.AddMapp
 		MappingTargetDatabaseName: targetDB,
 		MappingTargetTableName:    targetTable,
 		OwnerId:                   req.OwnerId,
+		MatchProfile:              req.MatchProfile,
+		MatchOverrides:            req.MatchOverrides,
 	}
 
 	if req.PolicyId != nil {
@@ -2082,9 +2456,7 @@ func (s *Server) addTableMappingUnified(ctx context.Context, req *corev1.AddMapp
 	}
 
 	// Call existing AddTableMapping implementation
-	// Note: AddTableMapping doesn't have generateRules parameter yet, we'll need to refactor it
-	// For now, just call it - the refactoring will happen in a separate step
-	return s.AddTableMapping(ctx, legacyReq)
+	return s.addTableMapping(ctx, legacyReq, generateRules)
 }
 
 // addDatabaseMappingUnified handles database-scoped mapping creation from unified request with enhanced matching
@@ -2119,6 +2491,32 @@ func (s *Server) addDatabaseMappingUnified(ctx context.Context, req *corev1.AddM
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the generated rules are held to
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Resolve the match profile tuning the matcher, with the options the request overrides
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), "database")
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	profile, overridden, err := applyMatchOverrides(profile, req.MatchOverrides)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	// Validate the table pins the matcher is held to, stored on the mapping for later re-matches
+	tablePins, err := tablePinsFromProto(req.TablePins)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
 	// Build resource URIs and mapping type
 	sourceType := "database"
 	targetType := "database"
@@ -2133,6 +2531,9 @@ func (s *Server) addDatabaseMappingUnified(ctx context.Context, req *corev1.AddM
 		"target_database_name": targetDBObj.Name,
 		"target_database_id":   targetDBObj.ID,
 	}
+	if len(tablePins) > 0 {
+		mappingObject["table_pins"] = tablePins
+	}
 
 	// Create the mapping
 	createdMapping, err := mappingService.Create(ctx, req.TenantId, workspaceID, mappingType, req.MappingName, req.MappingDescription, req.OwnerId,
@@ -2149,6 +2550,74 @@ func (s *Server) addDatabaseMappingUnified(ctx context.Context, req *corev1.AddM
 		return nil, status.Errorf(codes.Internal, "unified model service not available")
 	}
 
+	// Perform enhanced database-to-database matching (only if generateRules is true)
+	var matchReport *corev1.MatchReport
+	if generateRules {
+		match := &databaseMappingMatch{
+			tenantID:    req.TenantId,
+			workspaceID: workspaceID,
+			mappingName: req.MappingName,
+			ownerID:     req.OwnerId,
+			sourceDB:    sourceDBObj,
+			targetDB:    targetDBObj,
+			profile:     profile,
+			tablePins:   tablePins,
+		}
+		matchResp, err := s.matchDatabaseMapping(ctx, umClient, mappingService, match)
+		if err != nil {
+			s.engine.logger.Warnf("Failed to match unified models: %v", err)
+		} else {
+			report := newMatchReport(profile, overridden, matchResp)
+			s.createDatabaseMappingRules(ctx, mappingService, policies, match, matchResp, nil, report)
+			matchReport = s.storeMatchReport(ctx, mappingService, createdMapping.ID, req.MappingName, report)
+		}
+	}
+
+	// Refresh the mapping to get the updated mapping rule count
+	updatedMapping, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.logger.Warnf("Failed to refresh mapping data: %v", err)
+		// Use the original mapping if refresh fails
+		updatedMapping = createdMapping
+	}
+
+	// Convert to protobuf format
+	protoMapping, err := s.mappingToProto(updatedMapping)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to convert mapping: %v", err)
+	}
+
+	return &corev1.AddMappingResponse{
+		Message:     "Database mapping created successfully",
+		Success:     true,
+		Mapping:     protoMapping,
+		Status:      commonv1.Status_STATUS_SUCCESS,
+		MatchReport: matchReport,
+	}, nil
+}
+
+// databaseMappingMatch is a match of the databases of a database mapping, generating its rules
+type databaseMappingMatch struct {
+	tenantID    string
+	workspaceID string
+	mappingName string
+	ownerID     string
+	sourceDB    *database.Database
+	targetDB    *database.Database
+	profile     *mapping.MatchProfile
+	tablePins   []mapping.TablePin
+
+	// The tables of a table mapping, matched alone
+	sourceTable string
+	targetTable string
+}
+
+// matchDatabaseMapping matches the schemas of the databases of a database mapping, with the
+// options of its match profile and its table pins
+func (s *Server) matchDatabaseMapping(ctx context.Context, umClient unifiedmodelv1.UnifiedModelServiceClient, mappingService *mapping.Service, match *databaseMappingMatch) (*unifiedmodelv1.MatchUnifiedModelsEnrichedResponse, error) {
+	sourceDBObj, targetDBObj := match.sourceDB, match.targetDB
+
 	// Convert source database schema to UnifiedModel
 	var sourceUM *unifiedmodelv1.UnifiedModel
 	var sourceEnrichment *unifiedmodelv1.UnifiedModelEnrichment
@@ -2199,134 +2668,170 @@ func (s *Server) addDatabaseMappingUnified(ctx context.Context, req *corev1.AddM
 		}
 	}
 
-	// Perform enhanced database-to-database matching (only if generateRules is true)
-	if generateRules && sourceUM != nil && targetUM != nil {
-		// Create matching request with database-optimized options
-		// For database-level mapping, we prioritize table name matching and structure
-		matchReq := &unifiedmodelv1.MatchUnifiedModelsEnrichedRequest{
-			SourceUnifiedModel: sourceUM,
-			TargetUnifiedModel: targetUM,
-			SourceEnrichment:   sourceEnrichment,
-			TargetEnrichment:   targetEnrichment,
-			Options: &unifiedmodelv1.MatchOptions{
-				NameSimilarityThreshold:  0.2,   // Lower threshold to catch more table name similarities
-				PoorMatchThreshold:       0.3,   // Lower threshold for poor matches
-				NameWeight:               0.6,   // Higher weight for table name similarity
-				TypeWeight:               0.15,  // Moderate weight for data types
-				ClassificationWeight:     0.15,  // Moderate weight for table classification
-				PrivilegedDataWeight:     0.05,  // Lower weight for privileged data
-				TableStructureWeight:     0.05,  // Lower weight for structure
-				EnableCrossTableMatching: false, // Disable cross-table matching for cleaner results
-			},
-		}
-
-		// Call unified model service for matching
-		s.engine.logger.Infof("Starting database-level matching with %d source tables and %d target tables",
-			len(sourceUM.Tables), len(targetUM.Tables))
+	if sourceUM == nil {
+		return nil, fmt.Errorf("source database %s has no schema to match", sourceDBObj.Name)
+	}
+	if targetUM == nil {
+		return nil, fmt.Errorf("target database %s has no schema to match", targetDBObj.Name)
+	}
 
-		matchResp, err := umClient.MatchUnifiedModelsEnriched(ctx, matchReq)
-		if err != nil {
-			s.engine.logger.Warnf("Failed to match unified models: %v", err)
-		} else {
-			// Process matching results and create mapping rules
-			s.engine.logger.Infof("Database matching completed: found %d table matches for mapping %s (overall score: %.3f)",
-				len(matchResp.TableMatches), req.MappingName, matchResp.OverallSimilarityScore)
+	// A table mapping matches its tables only
+	if match.sourceTable != "" {
+		sourceUM = s.filterUnifiedModelForTable(sourceUM, match.sourceTable)
+		sourceEnrichment = s.filterUnifiedModelEnrichmentForTable(sourceEnrichment, match.sourceTable)
+	}
+	if match.targetTable != "" {
+		targetUM = s.filterUnifiedModelForTable(targetUM, match.targetTable)
+		targetEnrichment = s.filterUnifiedModelEnrichmentForTable(targetEnrichment, match.targetTable)
+	}
 
-			for _, tableMatch := range matchResp.TableMatches {
-				s.engine.logger.Infof("Table match: %s -> %s (score: %.3f, %d/%d columns matched)",
-					tableMatch.SourceTable, tableMatch.TargetTable, tableMatch.Score,
-					tableMatch.MatchedColumns, tableMatch.TotalSourceColumns)
+	// Create matching request with the options of the match profile; the built-in database
+	// profile prioritizes table name matching and structure. The table pins fix the pairing of
+	// the tables users corrected.
+	options := s.matchOptions(ctx, mappingService, match.workspaceID, match.profile)
+	options.TablePins = tablePinsToMatchOptions(match.tablePins)
+	matchReq := &unifiedmodelv1.MatchUnifiedModelsEnrichedRequest{
+		SourceUnifiedModel: sourceUM,
+		TargetUnifiedModel: targetUM,
+		SourceEnrichment:   sourceEnrichment,
+		TargetEnrichment:   targetEnrichment,
+		Options:            options,
+	}
 
-				// Create mapping rules for each column match within this table match
-				for _, columnMatch := range tableMatch.ColumnMatches {
-					ruleName := fmt.Sprintf("%s_%s_%s_to_%s_%s_%s",
-						sourceDB, tableMatch.SourceTable, columnMatch.SourceColumn,
-						targetDB, tableMatch.TargetTable, columnMatch.TargetColumn)
-
-					// Create metadata for the mapping rule
-					metadata := map[string]interface{}{
-						"generated_at":         time.Now().UTC().Format(time.RFC3339),
-						"match_score":          columnMatch.Score,
-						"match_type":           "enriched_match",
-						"source_column":        columnMatch.SourceColumn,
-						"source_table":         tableMatch.SourceTable,
-						"source_database_name": sourceDBObj.Name,
-						"source_database_id":   sourceDBObj.ID,
-						"target_column":        columnMatch.TargetColumn,
-						"target_table":         tableMatch.TargetTable,
-						"target_database_name": targetDBObj.Name,
-						"target_database_id":   targetDBObj.ID,
-						"type_compatible":      columnMatch.IsTypeCompatible,
-						"table_match_score":    tableMatch.Score,
-					}
+	// Call unified model service for matching
+	s.engine.logger.Infof("Starting database-level matching with %d source tables, %d target tables and %d table pins",
+		len(sourceUM.Tables), len(targetUM.Tables), len(match.tablePins))
 
-					// Create empty transformation options
-					transformationOptions := map[string]interface{}{}
+	matchResp, err := umClient.MatchUnifiedModelsEnriched(ctx, matchReq)
+	if err != nil {
+		return nil, err
+	}
+	s.engine.logger.Infof("Database matching completed: found %d table matches for mapping %s (overall score: %.3f)",
+		len(matchResp.TableMatches), match.mappingName, matchResp.OverallSimilarityScore)
+	return matchResp, nil
+}
 
-					// Build proper resource URIs
-					sourceURI := s.buildResourceURI("column", sourceDBObj.ID, tableMatch.SourceTable, columnMatch.SourceColumn)
-					targetURI := s.buildResourceURI("column", targetDBObj.ID, tableMatch.TargetTable, columnMatch.TargetColumn)
+// createDatabaseMappingRules creates and attaches the rules of the column matches of a database
+// mapping, and returns how many were created. Matches of the target columns in skipTargets,
+// "table.column" mapped by rules users kept, are skipped. The outcome of each column match is
+// set in the report, unless it is nil.
+func (s *Server) createDatabaseMappingRules(ctx context.Context, mappingService *mapping.Service, policies *transformationPolicies, match *databaseMappingMatch, matchResp *unifiedmodelv1.MatchUnifiedModelsEnrichedResponse, skipTargets map[string]bool, report *mapping.MatchReport) int {
+	sourceDBObj, targetDBObj, profile := match.sourceDB, match.targetDB, match.profile
+	sourceDB, targetDB := sourceDBObj.Name, targetDBObj.Name
+
+	// The rules of a table mapping are generated like those AddTableMapping generates
+	matchType := "enriched_match"
+	if match.sourceTable != "" {
+		matchType = "auto_generated"
+	}
+
+	created := 0
+	for _, tableMatch := range matchResp.TableMatches {
+		s.engine.logger.Infof("Table match: %s -> %s (score: %.3f, %d/%d columns matched, pinned: %t)",
+			tableMatch.SourceTable, tableMatch.TargetTable, tableMatch.Score,
+			tableMatch.MatchedColumns, tableMatch.TotalSourceColumns, tableMatch.Pinned)
+
+		// Create mapping rules for each column match within this table match, the columns
+		// of matched composite keys with the keys they are part of
+		keys := keyColumnMetadata(tableMatch)
+		for _, columnMatch := range tableMatch.ColumnMatches {
+			setOutcome := func(outcome, ruleName, detail string) {
+				report.SetOutcome(tableMatch.SourceTable, columnMatch.SourceColumn, tableMatch.TargetTable, columnMatch.TargetColumn, outcome, ruleName, detail)
+			}
+			if columnMatch.Score < profile.Options.MinRuleScore {
+				setOutcome(mapping.MatchOutcomeBelowMinScore, "", "")
+				continue
+			}
+			if skipTargets[tableMatch.TargetTable+"."+columnMatch.TargetColumn] {
+				setOutcome(mapping.MatchOutcomeKeptRule, "", "")
+				continue
+			}
+			ruleName := fmt.Sprintf("%s_%s_%s_to_%s_%s_%s",
+				sourceDB, tableMatch.SourceTable, columnMatch.SourceColumn,
+				targetDB, tableMatch.TargetTable, columnMatch.TargetColumn)
+
+			// Create metadata for the mapping rule
+			metadata := map[string]interface{}{
+				"generated_at":         time.Now().UTC().Format(time.RFC3339),
+				"match_score":          columnMatch.Score,
+				"match_type":           matchType,
+				"match_profile":        profile.Name,
+				"match_reasons":        columnMatch.Reasons,
+				"source_column":        columnMatch.SourceColumn,
+				"source_table":         tableMatch.SourceTable,
+				"source_database_name": sourceDBObj.Name,
+				"source_database_id":   sourceDBObj.ID,
+				"target_column":        columnMatch.TargetColumn,
+				"target_table":         tableMatch.TargetTable,
+				"target_database_name": targetDBObj.Name,
+				"target_database_id":   targetDBObj.ID,
+				"type_compatible":      columnMatch.IsTypeCompatible,
+				"table_match_score":    tableMatch.Score,
+			}
+			if columnKeys := keys[columnMatch.SourceColumn]; len(columnKeys) > 0 {
+				metadata["key_matches"] = columnKeys
+			}
+			if tableMatch.Pinned {
+				metadata["table_pinned"] = true
+			}
 
-					_, err = mappingService.CreateMappingRule(ctx, req.TenantId, workspaceID, ruleName,
-						fmt.Sprintf("Auto-generated rule for %s.%s.%s -> %s.%s.%s",
-							sourceDB, tableMatch.SourceTable, columnMatch.SourceColumn,
-							targetDB, tableMatch.TargetTable, columnMatch.TargetColumn),
-						sourceURI,
-						targetURI,
-						"direct_mapping", // Default transformation
-						transformationOptions,
-						metadata,
-						req.OwnerId)
+			// Create empty transformation options
+			transformationOptions := map[string]interface{}{}
 
-					if err != nil {
-						s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
-						continue
-					}
+			// Build proper resource URIs
+			sourceURI := s.buildResourceURI("column", sourceDBObj.ID, tableMatch.SourceTable, columnMatch.SourceColumn)
+			targetURI := s.buildResourceURI("column", targetDBObj.ID, tableMatch.TargetTable, columnMatch.TargetColumn)
 
-					// Attach the mapping rule to the mapping
-					err = mappingService.AttachMappingRule(ctx, req.TenantId, workspaceID, req.MappingName, ruleName, nil)
-					if err != nil {
-						s.engine.logger.Warnf("Failed to attach mapping rule %s to mapping: %v", ruleName, err)
-					}
-				}
+			// Apply the transformation policies of the tenant
+			transformationName, transformationOptions, err := policies.enforce(ctx, []string{sourceURI}, []string{targetURI}, "direct_mapping", transformationOptions, metadata)
+			if err != nil {
+				s.engine.logger.Warnf("Skipping mapping rule %s: %v", ruleName, err)
+				setOutcome(mapping.MatchOutcomeBlocked, ruleName, err.Error())
+				continue
 			}
 
-			// Log unmatched columns as warnings
-			if len(matchResp.UnmatchedColumns) > 0 {
-				s.engine.logger.Warnf("Found %d unmatched columns in database mapping %s", len(matchResp.UnmatchedColumns), req.MappingName)
-				for _, unmatchedCol := range matchResp.UnmatchedColumns {
-					s.engine.logger.Warnf("Unmatched column: %s.%s", unmatchedCol.SourceTable, unmatchedCol.SourceColumn)
-				}
+			_, err = mappingService.CreateMappingRule(ctx, match.tenantID, match.workspaceID, ruleName,
+				fmt.Sprintf("Auto-generated rule for %s.%s.%s -> %s.%s.%s",
+					sourceDB, tableMatch.SourceTable, columnMatch.SourceColumn,
+					targetDB, tableMatch.TargetTable, columnMatch.TargetColumn),
+				sourceURI,
+				targetURI,
+				transformationName,
+				transformationOptions,
+				metadata,
+				match.ownerID)
+
+			if err != nil {
+				s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
+				setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
+				continue
 			}
 
-			// Log overall warnings
-			for _, warning := range matchResp.Warnings {
-				s.engine.logger.Warnf("Matching warning: %s", warning)
+			// Attach the mapping rule to the mapping
+			err = mappingService.AttachMappingRule(ctx, match.tenantID, match.workspaceID, match.mappingName, ruleName, nil)
+			if err != nil {
+				s.engine.logger.Warnf("Failed to attach mapping rule %s to mapping: %v", ruleName, err)
+				setOutcome(mapping.MatchOutcomeRuleFailed, ruleName, err.Error())
+				continue
 			}
+			setOutcome(mapping.MatchOutcomeRuleCreated, ruleName, "")
+			created++
 		}
 	}
 
-	// Refresh the mapping to get the updated mapping rule count
-	updatedMapping, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
-	if err != nil {
-		s.engine.logger.Warnf("Failed to refresh mapping data: %v", err)
-		// Use the original mapping if refresh fails
-		updatedMapping = createdMapping
+	// Log unmatched columns as warnings
+	if len(matchResp.UnmatchedColumns) > 0 {
+		s.engine.logger.Warnf("Found %d unmatched columns in database mapping %s", len(matchResp.UnmatchedColumns), match.mappingName)
+		for _, unmatchedCol := range matchResp.UnmatchedColumns {
+			s.engine.logger.Warnf("Unmatched column: %s.%s", unmatchedCol.SourceTable, unmatchedCol.SourceColumn)
+		}
 	}
 
-	// Convert to protobuf format
-	protoMapping, err := s.mappingToProto(updatedMapping)
-	if err != nil {
-		s.engine.IncrementErrors()
-		return nil, status.Errorf(codes.Internal, "failed to convert mapping: %v", err)
+	// Log overall warnings
+	for _, warning := range matchResp.Warnings {
+		s.engine.logger.Warnf("Matching warning: %s", warning)
 	}
-
-	return &corev1.AddMappingResponse{
-		Message: "Database mapping created successfully",
-		Success: true,
-		Mapping: protoMapping,
-		Status:  commonv1.Status_STATUS_SUCCESS,
-	}, nil
+	return created
 }
 
 // addMCPMapping creates a mapping from a database/table to an MCP resource
@@ -2455,6 +2960,12 @@ func (s *Server) autoGenerateMCPMappingRules(ctx context.Context, tenantID, work
 	// Get mapping service
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the generated rules are held to
+	policies, err := s.loadTransformationPolicies(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
 	// Generate virtual table name
 	virtualTableName := fmt.Sprintf("mcp_virtual_%s", mappingName)
 
@@ -2499,11 +3010,15 @@ func (s *Server) autoGenerateMCPMappingRules(ctx context.Context, tenantID, work
 			"generated_at":         time.Now().UTC().Format(time.RFC3339),
 		}
 
-		// Create empty transformation options
-		transformationOptions := map[string]interface{}{}
+		// Apply the transformation policies of the tenant to the otherwise direct mapping
+		transformationName, transformationOptions, err := policies.enforce(ctx, []string{sourceURI}, []string{targetURI}, "direct_mapping", map[string]interface{}{}, metadata)
+		if err != nil {
+			s.engine.logger.Warnf("Skipping mapping rule %s: %v", ruleName, err)
+			continue
+		}
 
 		// Create the mapping rule
-		_, err := mappingService.CreateMappingRule(ctx, tenantID, workspaceID, ruleName, fmt.Sprintf("Auto-generated rule for %s.%s", sourceTableName, columnName), sourceURI, targetURI, "direct_mapping", transformationOptions, metadata, ownerID)
+		_, err = mappingService.CreateMappingRule(ctx, tenantID, workspaceID, ruleName, fmt.Sprintf("Auto-generated rule for %s.%s", sourceTableName, columnName), sourceURI, targetURI, transformationName, transformationOptions, metadata, ownerID)
 		if err != nil {
 			s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
 			continue
@@ -2531,7 +3046,9 @@ func (s *Server) autoGenerateMCPMappingRules(ctx context.Context, tenantID, work
 	return nil
 }
 
-// ValidateMapping validates a mapping
+// ValidateMapping validates a mapping and each of its rules without running anything: the objects
+// the rules reference must exist, their transformations resolve, their cardinalities match and the
+// types of the values they write fit their targets
 func (s *Server) ValidateMapping(ctx context.Context, req *corev1.ValidateMappingRequest) (*corev1.ValidateMappingResponse, error) {
 	defer s.trackOperation()()
 
@@ -2611,6 +3128,32 @@ func (s *Server) ValidateMapping(ctx context.Context, req *corev1.ValidateMappin
 		}
 	}
 
+	// Check every rule, then the targets of the mapping as a whole
+	validator := s.newMappingValidator(mappingService)
+	var ruleValidations []*corev1.MappingRuleValidation
+	for _, rule := range rules {
+		validation := validator.validateRule(ctx, rule)
+		ruleValidations = append(ruleValidations, validation)
+		for _, issue := range validation.Issues {
+			message := fmt.Sprintf("rule %s: %s", rule.Name, issue.Message)
+			if issue.Severity == "error" {
+				errors = append(errors, message)
+				isValid = false
+			} else {
+				warnings = append(warnings, message)
+			}
+		}
+	}
+	mappingIssues := validator.validateMappingTargets(ctx, rules)
+	for _, issue := range mappingIssues {
+		if issue.Severity == "error" {
+			errors = append(errors, issue.Message)
+			isValid = false
+		} else {
+			warnings = append(warnings, issue.Message)
+		}
+	}
+
 	// Update validation status in database
 	err = mappingService.UpdateValidationStatus(ctx, mappingObj.ID, isValid, errors, warnings)
 	if err != nil {
@@ -2626,6 +3169,9 @@ func (s *Server) ValidateMapping(ctx context.Context, req *corev1.ValidateMappin
 		ValidationErrors:   errors,
 		ValidationWarnings: warnings,
 		Status:             commonv1.Status_STATUS_SUCCESS,
+		RuleValidations:    ruleValidations,
+		MappingIssues:      mappingIssues,
+		ValidatedAt:        time.Now().UTC().Format(time.RFC3339),
 	}, nil
 }
 
@@ -2926,6 +3472,12 @@ func (s *Server) AddStreamToStreamMapping(ctx context.Context, req *corev1.AddSt
 func (s *Server) autoGenerateStreamMappingRules(ctx context.Context, tenantID, workspaceID, mappingName, ownerID string, sourceItems, targetItems []*mapping.ResourceItem) error {
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
+	// Load the transformation policies the generated rules are held to
+	policies, err := s.loadTransformationPolicies(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
 	s.engine.logger.Infof("Auto-generating mapping rules for stream mapping %s: %d source fields, %d target columns", mappingName, len(sourceItems), len(targetItems))
 
 	// Create a map of target items by name for quick lookup
@@ -2970,8 +3522,15 @@ func (s *Server) autoGenerateStreamMappingRules(ctx context.Context, tenantID, w
 			"generated_at":  time.Now().Format(time.RFC3339),
 		}
 
+		// Apply the transformation policies of the tenant
+		transformationName, transformationOptions, err := policies.enforce(ctx, []string{sourceItem.ResourceURI}, []string{targetItem.ResourceURI}, "direct_mapping", map[string]interface{}{}, metadata)
+		if err != nil {
+			s.engine.logger.Warnf("Skipping mapping rule %s: %v", ruleName, err)
+			continue
+		}
+
 		// Create mapping rule
-		_, err := mappingService.CreateMappingRule(ctx, tenantID, workspaceID, ruleName, fmt.Sprintf("Auto-generated rule for %s -> %s", sourceItem.ItemName, targetItem.ItemName), sourceItem.ResourceURI, targetItem.ResourceURI, "direct_mapping", map[string]interface{}{}, metadata, ownerID)
+		_, err = mappingService.CreateMappingRule(ctx, tenantID, workspaceID, ruleName, fmt.Sprintf("Auto-generated rule for %s -> %s", sourceItem.ItemName, targetItem.ItemName), sourceItem.ResourceURI, targetItem.ResourceURI, transformationName, transformationOptions, metadata, ownerID)
 		if err != nil {
 			s.engine.logger.Warnf("Failed to create mapping rule %s: %v", ruleName, err)
 			continue
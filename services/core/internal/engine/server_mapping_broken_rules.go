@@ -0,0 +1,478 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// detectBrokenMappingRules checks the mapping rules of the workspace of a database against a new
+// schema of the database. Rules referencing tables or columns that no longer exist are marked
+// broken, which leaves them out of runs, and the owners of the rules and of their mappings are
+// notified. Broken rules whose objects all exist again are restored.
+func (s *Server) detectBrokenMappingRules(ctx context.Context, databaseID, schema string) error {
+	db, err := database.NewService(s.engine.db, s.engine.logger).GetByID(ctx, databaseID)
+	if err != nil {
+		return fmt.Errorf("database not found: %w", err)
+	}
+	columns, err := s.schemaColumns(schema)
+	if err != nil {
+		return fmt.Errorf("failed to convert the schema of database %s: %w", db.Name, err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	rules, err := mappingService.ListMappingRules(ctx, db.TenantID, db.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to list mapping rules: %w", err)
+	}
+
+	// The stored schema of the database is only updated after the change is committed
+	schemas := map[string]mapping.SchemaColumns{databaseID: columns}
+	broken, restored := 0, 0
+	for _, rule := range rules {
+		if !referencesDatabase(rule.Metadata, databaseID) {
+			continue
+		}
+
+		previous := rule.Breakage()
+		breakage := s.ruleBreakage(ctx, rule.Metadata, schemas)
+		switch {
+		case breakage == nil && previous == nil:
+			continue
+		case breakage == nil:
+			if err := mappingService.SetRuleBreakage(ctx, rule.ID, nil); err != nil {
+				return err
+			}
+			s.engine.logger.Infof("Mapping rule %s is no longer broken: the objects it references exist again", rule.Name)
+			restored++
+		case previous != nil && sameStrings(previous.Missing, breakage.Missing):
+			continue
+		default:
+			if previous != nil {
+				breakage.Detected = previous.Detected
+			}
+			if err := mappingService.SetRuleBreakage(ctx, rule.ID, breakage); err != nil {
+				return err
+			}
+			s.engine.logger.Warnf("Mapping rule %s is broken and left out of runs: %s", rule.Name, breakage.Reason)
+			broken++
+		}
+
+		// The mappings of the rule must be validated again
+		mappings, err := mappingService.GetMappingsForRule(ctx, rule.TenantID, rule.WorkspaceID, rule.Name)
+		if err != nil {
+			s.engine.logger.Warnf("Failed to get the mappings of mapping rule %s: %v", rule.Name, err)
+			continue
+		}
+		for _, mappingObj := range mappings {
+			if err := mappingService.InvalidateMapping(ctx, mappingObj.ID); err != nil {
+				s.engine.logger.Warnf("Failed to invalidate mapping %s: %v", mappingObj.Name, err)
+			}
+		}
+		if breakage != nil && previous == nil {
+			if err := mappingService.NotifyBrokenRule(ctx, rule, mappings, breakage); err != nil {
+				s.engine.logger.Warnf("Failed to notify the owners of broken mapping rule %s: %v", rule.Name, err)
+			}
+		}
+	}
+
+	if broken > 0 || restored > 0 {
+		s.engine.logger.Infof("Schema change of database %s broke %d mapping rules and restored %d", db.Name, broken, restored)
+	}
+	return nil
+}
+
+// ruleBreakage checks the references of the metadata of a rule against the schemas of their
+// databases, and returns why the rule is broken, or nil if it is not. The schemas missing from
+// schemas are loaded into it; references to databases without a schema are not checked.
+func (s *Server) ruleBreakage(ctx context.Context, metadata map[string]interface{}, schemas map[string]mapping.SchemaColumns) *mapping.RuleBreakage {
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	for _, reference := range mapping.RuleReferences(metadata) {
+		if _, loaded := schemas[reference.DatabaseID]; loaded {
+			continue
+		}
+		schemas[reference.DatabaseID] = nil
+		db, err := databaseService.GetByID(ctx, reference.DatabaseID)
+		if err != nil || db.Schema == "" {
+			continue
+		}
+		columns, err := s.schemaColumns(db.Schema)
+		if err != nil {
+			s.engine.logger.Warnf("Failed to convert the schema of database %s: %v", db.Name, err)
+			continue
+		}
+		schemas[reference.DatabaseID] = columns
+	}
+	return mapping.NewRuleBreakage(mapping.MissingReferences(metadata, schemas), time.Now())
+}
+
+// ListBrokenMappingRules lists the mapping rules of a workspace that reference tables or columns
+// that no longer exist
+func (s *Server) ListBrokenMappingRules(ctx context.Context, req *corev1.ListBrokenMappingRulesRequest) (*corev1.ListBrokenMappingRulesResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	rules, err := mapping.NewService(s.engine.db, s.engine.logger).ListMappingRules(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list mapping rules: %v", err)
+	}
+	_, broken := mapping.RunnableRules(rules)
+
+	protoRules := make([]*corev1.MappingRule, len(broken))
+	for i, rule := range broken {
+		protoRule, err := s.mappingRuleToProto(rule)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to convert mapping rule: %v", err)
+		}
+		protoRules[i] = protoRule
+	}
+
+	return &corev1.ListBrokenMappingRulesResponse{
+		MappingRules: protoRules,
+	}, nil
+}
+
+// RepairMappingRule suggests, for each missing object of a broken rule, the closest current
+// object: the matcher matches the database of the missing object with an object of the other
+// side of the rule that still exists. With apply, the rule is re-pointed to the suggestions that
+// score at least the minimum rule score of the match profile.
+func (s *Server) RepairMappingRule(ctx context.Context, req *corev1.RepairMappingRuleRequest) (*corev1.RepairMappingRuleResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	rule, err := mappingService.GetMappingRuleByName(ctx, req.TenantId, workspaceID, req.MappingRuleName)
+	if err != nil || rule == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping rule not found: %s", req.MappingRuleName)
+	}
+
+	// Check the rule against the current schemas rather than the recorded breakage
+	schemas := make(map[string]mapping.SchemaColumns)
+	breakage := s.ruleBreakage(ctx, rule.Metadata, schemas)
+	if breakage == nil {
+		if rule.Breakage() != nil {
+			if err := mappingService.SetRuleBreakage(ctx, rule.ID, nil); err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.Internal, "%v", err)
+			}
+		}
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "mapping rule %s is not broken", req.MappingRuleName)
+	}
+
+	umClient := s.engine.GetUnifiedModelClient()
+	if umClient == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "unified model service not available")
+	}
+
+	references := mapping.RuleReferences(rule.Metadata)
+	missing := mapping.MissingReferences(rule.Metadata, schemas)
+	response := &corev1.RepairMappingRuleResponse{
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}
+
+	type repair struct {
+		missing mapping.RuleReference
+		uri     string
+		score   float64
+	}
+	var repairs []repair
+	for _, reference := range missing {
+		protoRepair := &corev1.MappingRuleRepair{Side: reference.Side, MissingUri: reference.URI}
+		response.Repairs = append(response.Repairs, protoRepair)
+
+		// The missing object is matched against an object of the other side that still exists
+		anchor, ok := existingCounterpart(references, missing, reference.Side)
+		if !ok {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("no object of the other side of %s exists to match it with", reference))
+			continue
+		}
+		best, minScore, err := s.closestObject(ctx, umClient, mappingService, req, workspaceID, reference, anchor, schemas)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, err
+		}
+		if best == nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("the matcher found no object close to %s", reference))
+			continue
+		}
+
+		protoRepair.SuggestedUri = best.uri
+		protoRepair.SuggestedTable = best.table
+		protoRepair.SuggestedColumn = best.column
+		protoRepair.Score = best.score
+		protoRepair.Reasons = best.reasons
+		if best.score < minScore {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("the suggestion for %s scores %.2f, below the minimum rule score %.2f", reference, best.score, minScore))
+			continue
+		}
+		repairs = append(repairs, repair{missing: reference, uri: best.uri, score: best.score})
+	}
+
+	if !req.Apply {
+		response.Message = fmt.Sprintf("Found suggestions for %d of %d missing objects", countSuggestions(response.Repairs), len(missing))
+		protoRule, err := s.mappingRuleToProto(rule)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to convert mapping rule: %v", err)
+		}
+		response.MappingRule = protoRule
+		return response, nil
+	}
+
+	if len(repairs) == 0 {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "no suggestion to repair mapping rule %s with", req.MappingRuleName)
+	}
+
+	// Re-point the rule and check it again; it stays broken while objects remain missing
+	metadata := make(map[string]interface{}, len(rule.Metadata))
+	for key, value := range rule.Metadata {
+		metadata[key] = value
+	}
+	for _, r := range repairs {
+		if err := mapping.RepointReference(metadata, r.missing, r.uri); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+	delete(metadata, "broken")
+	if remaining := s.ruleBreakage(ctx, metadata, schemas); remaining != nil {
+		metadata["broken"] = remaining
+	}
+
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	transformationName, _ := metadata["transformation_name"].(string)
+	transformationOptions, _ := metadata["transformation_options"].(map[string]interface{})
+	transformationName, transformationOptions, err = policies.enforce(ctx, ruleURIs(metadata, "source"), ruleURIs(metadata, "target"),
+		transformationName, transformationOptions, metadata)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	metadata["transformation_name"] = transformationName
+	if transformationOptions != nil {
+		metadata["transformation_options"] = transformationOptions
+	}
+
+	updatedRule, err := mappingService.ModifyMappingRule(ctx, req.TenantId, workspaceID, rule.Name, map[string]interface{}{
+		"mapping_rule_metadata": metadata,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to update mapping rule: %v", err)
+	}
+
+	// The mappings of the rule must be validated again
+	mappings, err := mappingService.GetMappingsForRule(ctx, req.TenantId, workspaceID, rule.Name)
+	if err != nil {
+		s.engine.logger.Warnf("Failed to get mappings for rule invalidation: %v", err)
+	} else {
+		for _, mappingObj := range mappings {
+			if err := mappingService.InvalidateMapping(ctx, mappingObj.ID); err != nil {
+				s.engine.logger.Warnf("Failed to invalidate mapping %s: %v", mappingObj.Name, err)
+			}
+		}
+	}
+
+	protoRule, err := s.mappingRuleToProto(updatedRule)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to convert mapping rule: %v", err)
+	}
+	response.Applied = true
+	response.MappingRule = protoRule
+	if protoRule.Broken {
+		response.Message = fmt.Sprintf("Re-pointed %d of %d missing objects, mapping rule %s is still broken", len(repairs), len(missing), rule.Name)
+	} else {
+		response.Message = fmt.Sprintf("Repaired mapping rule %s", rule.Name)
+	}
+	return response, nil
+}
+
+// closestMatch is the current object the matcher found closest to a missing object
+type closestMatch struct {
+	uri     string
+	table   string
+	column  string
+	score   float64
+	reasons []string
+}
+
+// closestObject matches the database of a missing object of a rule with the anchor, an object
+// of the other side of the rule that still exists, and returns the object the matcher pairs with
+// the anchor, and the minimum rule score of the match profile. The table of the missing object
+// is matched alone while it exists, else the whole database is.
+func (s *Server) closestObject(ctx context.Context, umClient unifiedmodelv1.UnifiedModelServiceClient, mappingService *mapping.Service, req *corev1.RepairMappingRuleRequest, workspaceID string, missing, anchor mapping.RuleReference, schemas map[string]mapping.SchemaColumns) (*closestMatch, float64, error) {
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	missingDB, err := databaseService.GetByID(ctx, missing.DatabaseID)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.NotFound, "database of %s not found: %v", missing, err)
+	}
+	anchorDB, err := databaseService.GetByID(ctx, anchor.DatabaseID)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.NotFound, "database of %s not found: %v", anchor, err)
+	}
+
+	missingTable := ""
+	if _, exists := schemas[missing.DatabaseID][missing.Table]; exists {
+		missingTable = missing.Table
+	}
+	scope := "database"
+	if missingTable != "" {
+		scope = "table"
+	}
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), scope)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	match := &databaseMappingMatch{
+		tenantID:    req.TenantId,
+		workspaceID: workspaceID,
+		mappingName: req.MappingRuleName,
+		profile:     profile,
+	}
+	if missing.Side == "source" {
+		match.sourceDB, match.targetDB = missingDB, anchorDB
+		match.sourceTable, match.targetTable = missingTable, anchor.Table
+	} else {
+		match.sourceDB, match.targetDB = anchorDB, missingDB
+		match.sourceTable, match.targetTable = anchor.Table, missingTable
+	}
+	matchResp, err := s.matchDatabaseMapping(ctx, umClient, mappingService, match)
+	if err != nil {
+		return nil, 0, status.Errorf(codes.FailedPrecondition, "failed to match %s: %v", missing, err)
+	}
+
+	var best *closestMatch
+	for _, tableMatch := range matchResp.TableMatches {
+		anchorTable, candidateTable := tableMatch.TargetTable, tableMatch.SourceTable
+		if missing.Side == "target" {
+			anchorTable, candidateTable = tableMatch.SourceTable, tableMatch.TargetTable
+		}
+		if anchorTable != anchor.Table {
+			continue
+		}
+
+		// A missing table is replaced by the table matched with the table of the anchor
+		if missing.Column == "" {
+			if best == nil || tableMatch.Score > best.score {
+				best = &closestMatch{
+					uri:   s.buildResourceURI("table", missingDB.ID, candidateTable, ""),
+					table: candidateTable,
+					score: tableMatch.Score,
+				}
+			}
+			continue
+		}
+
+		for _, columnMatch := range tableMatch.ColumnMatches {
+			anchorColumn, candidateColumn := columnMatch.TargetColumn, columnMatch.SourceColumn
+			if missing.Side == "target" {
+				anchorColumn, candidateColumn = columnMatch.SourceColumn, columnMatch.TargetColumn
+			}
+			if anchorColumn != anchor.Column || columnMatch.IsUnmatched {
+				continue
+			}
+			if best == nil || columnMatch.Score > best.score {
+				best = &closestMatch{
+					uri:     s.buildResourceURI("column", missingDB.ID, candidateTable, candidateColumn),
+					table:   candidateTable,
+					column:  candidateColumn,
+					score:   columnMatch.Score,
+					reasons: columnMatch.Reasons,
+				}
+			}
+		}
+	}
+	return best, profile.Options.MinRuleScore, nil
+}
+
+// existingCounterpart returns an object of the side of a rule opposite to side that still
+// exists, a column if the rule has one
+func existingCounterpart(references, missing []mapping.RuleReference, side string) (mapping.RuleReference, bool) {
+	var counterpart mapping.RuleReference
+	found := false
+	for _, reference := range references {
+		if reference.Side == side || containsReference(missing, reference) {
+			continue
+		}
+		if !found || (counterpart.Column == "" && reference.Column != "") {
+			counterpart, found = reference, true
+		}
+	}
+	return counterpart, found
+}
+
+// containsReference reports whether references contain a reference
+func containsReference(references []mapping.RuleReference, reference mapping.RuleReference) bool {
+	for _, r := range references {
+		if r == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesDatabase reports whether the metadata of a rule references a database
+func referencesDatabase(metadata map[string]interface{}, databaseID string) bool {
+	for _, reference := range mapping.RuleReferences(metadata) {
+		if reference.DatabaseID == databaseID {
+			return true
+		}
+	}
+	return false
+}
+
+// countSuggestions counts the repairs with a suggested object
+func countSuggestions(repairs []*corev1.MappingRuleRepair) int {
+	count := 0
+	for _, repair := range repairs {
+		if repair.SuggestedUri != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// sameStrings reports whether two lists hold the same strings in the same order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,19 +3,26 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
 	"github.com/redbco/redb-open/pkg/grpcconfig"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
 	"github.com/redbco/redb-open/services/core/internal/services/mapping"
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // CopyMappingData handles the data copying operation for a mapping
@@ -52,7 +59,7 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
 
 	// Get the mapping
-	_, err = mappingService.Get(stream.Context(), req.TenantId, workspaceID, req.MappingName)
+	mappingObj, err := mappingService.Get(stream.Context(), req.TenantId, workspaceID, req.MappingName)
 	if err != nil {
 		s.engine.IncrementErrors()
 		return stream.Send(&corev1.CopyMappingDataResponse{
@@ -73,6 +80,23 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 		})
 	}
 
+	// Broken rules reference objects that no longer exist and are left out of the copy
+	mappingRules, brokenRules := mapping.RunnableRules(mappingRules)
+	if len(brokenRules) > 0 {
+		skipped := make([]string, len(brokenRules))
+		for i, rule := range brokenRules {
+			skipped[i] = fmt.Sprintf("skipped broken mapping rule %s: %s", rule.Name, rule.Breakage().Reason)
+		}
+		if err := stream.Send(&corev1.CopyMappingDataResponse{
+			Status:      "progress",
+			Message:     fmt.Sprintf("Skipping %d broken mapping rules", len(brokenRules)),
+			OperationId: operationID,
+			Errors:      skipped,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if len(mappingRules) == 0 {
 		return stream.Send(&corev1.CopyMappingDataResponse{
 			Status:      "error",
@@ -81,6 +105,17 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 		})
 	}
 
+	// Only the source rows matching the row filters of the mapping and its rules are copied
+	rowFilters, err := mapping.NewRowFilters(mappingObj.RowFilter(), mappingRules)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return stream.Send(&corev1.CopyMappingDataResponse{
+			Status:      "error",
+			Message:     err.Error(),
+			OperationId: operationID,
+		})
+	}
+
 	// Set defaults
 	batchSize := int32(1000)
 	if req.BatchSize != nil && *req.BatchSize > 0 {
@@ -97,8 +132,46 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 		dryRun = *req.DryRun
 	}
 
-	s.engine.logger.Infof("Starting data copy for mapping '%s': batch_size=%d, parallel_workers=%d, dry_run=%t, rules=%d",
-		req.MappingName, batchSize, parallelWorkers, dryRun, len(mappingRules))
+	sandbox := false
+	if req.Sandbox != nil {
+		sandbox = *req.Sandbox
+	}
+
+	sampleSize := int32(10)
+	if req.SampleSize != nil && *req.SampleSize > 0 {
+		sampleSize = *req.SampleSize
+	}
+
+	validateVectors := true
+	if req.ValidateVectors != nil {
+		validateVectors = *req.ValidateVectors
+	}
+
+	vectorSamples := int32(mapping.DefaultVectorSamples)
+	if req.VectorSamples != nil && *req.VectorSamples > 0 {
+		vectorSamples = *req.VectorSamples
+	}
+
+	verifyGraph := true
+	if req.VerifyGraph != nil {
+		verifyGraph = *req.VerifyGraph
+	}
+
+	graphEdgeSamples := int32(mapping.DefaultGraphEdgeSamples)
+	if req.GraphEdgeSamples != nil && *req.GraphEdgeSamples > 0 {
+		graphEdgeSamples = *req.GraphEdgeSamples
+	}
+
+	adaptiveParallelism := true
+	if req.AdaptiveParallelism != nil {
+		adaptiveParallelism = *req.AdaptiveParallelism
+	}
+
+	// The parallelism carries over from one table pair to the next
+	parallelism := mapping.NewParallelismController(s.engine.copyParallelismBounds(), int(parallelWorkers), adaptiveParallelism)
+
+	s.engine.logger.Infof("Starting data copy for mapping '%s': batch_size=%d, parallel_workers=%d, adaptive_parallelism=%t, dry_run=%t, sandbox=%t, rules=%d",
+		req.MappingName, batchSize, parallelism.Parallelism(), adaptiveParallelism, dryRun, sandbox, len(mappingRules))
 
 	if dryRun {
 		// For dry run, just validate the mapping and return success
@@ -114,8 +187,32 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 	// Group mapping rules by source/target table pairs
 	tablePairs := s.groupMappingRulesByTables(mappingRules)
 
+	// A sandbox run writes into copies of the target tables, which are dropped when it ends. The
+	// anchor drops sandboxes a day after the time in their names, should the run not drop them.
+	sandboxName := ""
+	if sandbox {
+		sandboxName = fmt.Sprintf("redb_sandbox_%d", time.Now().UnixNano())
+		databases, err := s.createCopySandbox(stream.Context(), sandboxName, tablePairs)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return stream.Send(&corev1.CopyMappingDataResponse{
+				Status:      "error",
+				Message:     fmt.Sprintf("Failed to create sandbox: %v", err),
+				OperationId: operationID,
+			})
+		}
+		defer s.dropCopySandbox(sandboxName, databases)
+	}
+
 	var totalRowsProcessed int64 = 0
 	var totalRowsEstimate int64 = 0
+	var totalRowsRejected int64 = 0
+	var deadLetterIDs []string
+	var vectorValidations []*corev1.VectorValidation
+	var graphVerifications []*corev1.GraphVerification
+	var runTimings mapping.StageTimings
+	var tablePairTimings []*corev1.StageTimings
+	var parallelismDecisions []*corev1.ParallelismDecision
 	var allErrors []string
 
 	// Process each table pair
@@ -130,13 +227,56 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 			TotalRows:     totalRowsEstimate,
 			CurrentTable:  currentTable,
 			OperationId:   operationID,
+			Sandbox:       sandboxName,
 		}); err != nil {
 			return err
 		}
 
 		// For now, simulate data copying
 		// TODO: Implement actual data copying logic with anchor service
-		rowsProcessed, err := s.copyTableData(stream.Context(), tablePair, batchSize)
+		run := newTableCopyRun(currentTable, parallelism)
+		rowsProcessed, rejected, err := s.copyTableData(stream.Context(), tablePair, rowFilters, batchSize, sandboxName, run)
+		totalRowsProcessed += rowsProcessed
+		pairTimings := run.timings
+		if pairTimings.Batches > 0 {
+			runTimings.Add(pairTimings)
+			tablePairTimings = append(tablePairTimings, stageTimingsProto(currentTable, pairTimings))
+			s.engine.logger.Infof("Stage timings of table pair %s: %s", currentTable, pairTimings.Summary())
+		}
+		if len(run.decisions) > 0 {
+			decisions := make([]*corev1.ParallelismDecision, 0, len(run.decisions))
+			for _, decision := range run.decisions {
+				decisions = append(decisions, parallelismDecisionProto(currentTable, decision))
+			}
+			parallelismDecisions = append(parallelismDecisions, decisions...)
+			if err := stream.Send(&corev1.CopyMappingDataResponse{
+				Status:               "progress",
+				Message:              fmt.Sprintf("Adjusted the parallelism of table pair %s %d times, to %d", currentTable, len(decisions), parallelism.Parallelism()),
+				RowsProcessed:        totalRowsProcessed,
+				TotalRows:            totalRowsEstimate,
+				CurrentTable:         currentTable,
+				OperationId:          operationID,
+				Sandbox:              sandboxName,
+				Parallelism:          int32(parallelism.Parallelism()),
+				ParallelismDecisions: decisions,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(rejected) > 0 {
+			// Rows rejected before a failure are kept as well
+			totalRowsRejected += int64(len(rejected))
+			id, storeErr := s.storeDeadLetterRows(stream.Context(), req.TenantId, workspaceID, operationID, tablePair, rejected)
+			if storeErr != nil {
+				errMsg := fmt.Sprintf("Target rejected %d rows of table pair %s, which could not be stored as a dead-letter artifact: %v (first error: %s)",
+					len(rejected), currentTable, storeErr, rejected[0].Error)
+				allErrors = append(allErrors, errMsg)
+				s.engine.logger.Errorf("%s", errMsg)
+			} else {
+				deadLetterIDs = append(deadLetterIDs, id)
+				s.engine.logger.Warnf("Target rejected %d rows of table pair %s, stored in dead-letter artifact %s", len(rejected), currentTable, id)
+			}
+		}
 		if err != nil {
 			errMsg := fmt.Sprintf("Failed to copy data for table pair %s: %v", currentTable, err)
 			allErrors = append(allErrors, errMsg)
@@ -144,8 +284,66 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 			continue
 		}
 
-		totalRowsProcessed += rowsProcessed
 		s.engine.logger.Infof("Completed copying %d rows for table pair: %s", rowsProcessed, currentTable)
+
+		// Vectors are read back from the target and searched again, as an index created with
+		// the wrong dimension or metric accepts them without an error
+		if validateVectors && !sandbox && rowsProcessed > 0 {
+			validation, err := s.validateCopiedVectors(stream.Context(), tablePair, vectorSamples)
+			if err != nil {
+				errMsg := fmt.Sprintf("Failed to validate the vectors of table pair %s: %v", currentTable, err)
+				allErrors = append(allErrors, errMsg)
+				s.engine.logger.Errorf("%s", errMsg)
+			} else if validation != nil {
+				vectorValidations = append(vectorValidations, validation)
+				if !validation.Passed {
+					errMsg := fmt.Sprintf("Vector validation failed for table pair %s: %s", currentTable, strings.Join(validation.Issues, "; "))
+					allErrors = append(allErrors, errMsg)
+					s.engine.logger.Warnf("%s", errMsg)
+				}
+			}
+		}
+
+		// Relationships read from a graph database are sampled again on both sides, as edges
+		// connecting the wrong nodes give the same row counts
+		if verifyGraph && !sandbox && rowsProcessed > 0 {
+			verification, err := s.verifyCopiedGraph(stream.Context(), tablePair, graphEdgeSamples)
+			if err != nil {
+				errMsg := fmt.Sprintf("Failed to verify the edges of table pair %s: %v", currentTable, err)
+				allErrors = append(allErrors, errMsg)
+				s.engine.logger.Errorf("%s", errMsg)
+			} else if verification != nil {
+				graphVerifications = append(graphVerifications, verification)
+				if !verification.Passed {
+					errMsg := fmt.Sprintf("Graph verification failed for table pair %s: %s", currentTable, strings.Join(verification.Issues, "; "))
+					allErrors = append(allErrors, errMsg)
+					s.engine.logger.Warnf("%s", errMsg)
+				}
+			}
+		}
+
+		if sandbox {
+			// Send the rows written into the sandbox, for the user to inspect
+			sample, err := s.fetchSandboxSample(stream.Context(), sandboxName, tablePair, sampleSize)
+			if err != nil {
+				errMsg := fmt.Sprintf("Failed to read sandbox rows for table pair %s: %v", currentTable, err)
+				allErrors = append(allErrors, errMsg)
+				s.engine.logger.Errorf("%s", errMsg)
+				continue
+			}
+			if err := stream.Send(&corev1.CopyMappingDataResponse{
+				Status:        "progress",
+				Message:       fmt.Sprintf("Wrote %d rows into the sandbox for table pair %s", rowsProcessed, currentTable),
+				RowsProcessed: totalRowsProcessed,
+				TotalRows:     totalRowsEstimate,
+				CurrentTable:  currentTable,
+				OperationId:   operationID,
+				Sandbox:       sandboxName,
+				SampleData:    sample,
+			}); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Send final completion response
@@ -155,18 +353,70 @@ func (s *Server) CopyMappingData(req *corev1.CopyMappingDataRequest, stream core
 	if len(allErrors) > 0 {
 		status = "completed_with_errors"
 		message = fmt.Sprintf("Data copy completed with %d errors. Processed %d rows across %d table pairs.", len(allErrors), totalRowsProcessed, len(tablePairs))
+	} else if totalRowsRejected > 0 {
+		status = "completed_with_rejected_rows"
+		message = fmt.Sprintf("Data copy completed. Processed %d rows across %d table pairs; the target rejected %d rows, which are in the dead-letter artifacts.",
+			totalRowsProcessed, len(tablePairs), totalRowsRejected)
+	}
+	if sandbox {
+		message = fmt.Sprintf("Sandbox run: %s The target tables were not modified and the sandbox %s is dropped.", message, sandboxName)
+	}
+
+	var stageTimings *corev1.StageTimings
+	if runTimings.Batches > 0 {
+		stageTimings = stageTimingsProto("", runTimings)
+		s.engine.logger.Infof("Stage timings of the copy of mapping '%s': %s", req.MappingName, runTimings.Summary())
 	}
 
 	return stream.Send(&corev1.CopyMappingDataResponse{
-		Status:        status,
-		Message:       message,
-		RowsProcessed: totalRowsProcessed,
-		TotalRows:     totalRowsProcessed, // For now, set total to processed
-		Errors:        allErrors,
-		OperationId:   operationID,
+		Status:                status,
+		Message:               message,
+		RowsProcessed:         totalRowsProcessed,
+		TotalRows:             totalRowsProcessed, // For now, set total to processed
+		Errors:                allErrors,
+		OperationId:           operationID,
+		Sandbox:               sandboxName,
+		RowsRejected:          totalRowsRejected,
+		DeadLetterArtifactIds: deadLetterIDs,
+		VectorValidations:     vectorValidations,
+		GraphVerifications:    graphVerifications,
+		StageTimings:          stageTimings,
+		TablePairTimings:      tablePairTimings,
+		Parallelism:           int32(parallelism.Parallelism()),
+		ParallelismDecisions:  parallelismDecisions,
 	})
 }
 
+// parallelismDecisionProto converts a change of the parallelism of a table pair to its protobuf message
+func parallelismDecisionProto(tablePair string, decision mapping.ParallelismDecision) *corev1.ParallelismDecision {
+	return &corev1.ParallelismDecision{
+		TablePair:     tablePair,
+		DecidedAt:     decision.DecidedAt.UTC().Format(time.RFC3339),
+		From:          int32(decision.From),
+		To:            int32(decision.To),
+		RowsPerSecond: decision.RowsPerSecond,
+		ErrorRate:     decision.ErrorRate,
+		Reason:        decision.Reason,
+	}
+}
+
+// stageTimingsProto converts the stage timings of a table pair, or of a whole copy without a table
+// pair, to their protobuf message
+func stageTimingsProto(tablePair string, timings mapping.StageTimings) *corev1.StageTimings {
+	bottleneck, share := timings.Bottleneck()
+	return &corev1.StageTimings{
+		TablePair:       tablePair,
+		Batches:         timings.Batches,
+		FetchMs:         timings.Fetch.Milliseconds(),
+		TransformMs:     timings.Transform.Milliseconds(),
+		NetworkMs:       timings.Network.Milliseconds(),
+		WriteMs:         timings.Write.Milliseconds(),
+		Bottleneck:      bottleneck,
+		BottleneckShare: share,
+		Summary:         timings.Summary(),
+	}
+}
+
 // GetCopyStatus returns the status of a data copy operation
 func (s *Server) GetCopyStatus(ctx context.Context, req *corev1.GetCopyStatusRequest) (*corev1.GetCopyStatusResponse, error) {
 	defer s.trackOperation()()
@@ -244,32 +494,37 @@ func (s *Server) groupMappingRulesByTables(rules []*mapping.Rule) []TablePair {
 	return tablePairs
 }
 
-// copyTableData copies data for a table pair using the Anchor service
-func (s *Server) copyTableData(ctx context.Context, tablePair TablePair, batchSize int32) (int64, error) {
+// copyTableData copies data for a table pair using the Anchor service, skipping the rows the
+// row filters exclude. With a sandbox, the data is inserted into the copy of the target table
+// in the sandbox. Batches the target fails to write are retried and split, and the rows it
+// rejects are returned instead of failing the copy. Batches are transformed and written as many
+// at once as the parallelism controller of the run sets, and the time they spend in each stage
+// is added to the run.
+func (s *Server) copyTableData(ctx context.Context, tablePair TablePair, rowFilters *mapping.RowFilters, batchSize int32, sandbox string, run *tableCopyRun) (int64, []mapping.RejectedRow, error) {
 	s.engine.logger.Infof("Copying data from %s to %s with %d column mappings",
 		tablePair.SourceTable, tablePair.TargetTable, len(tablePair.Rules))
 
 	// Parse source and target information
 	sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse source table: %v", err)
+		return 0, nil, fmt.Errorf("failed to parse source table: %v", err)
 	}
 
 	targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse target table: %v", err)
+		return 0, nil, fmt.Errorf("failed to parse target table: %v", err)
 	}
 
 	// Connect to Anchor service
 	anchorClient, err := s.getAnchorClient()
 	if err != nil {
-		return 0, fmt.Errorf("failed to connect to anchor service: %v", err)
+		return 0, nil, fmt.Errorf("failed to connect to anchor service: %v", err)
 	}
 
 	// Connect to Transformation service
 	transformationClient, err := s.getTransformationClient()
 	if err != nil {
-		return 0, fmt.Errorf("failed to connect to transformation service: %v", err)
+		return 0, nil, fmt.Errorf("failed to connect to transformation service: %v", err)
 	}
 
 	// Get row count for progress estimation
@@ -298,7 +553,7 @@ func (s *Server) copyTableData(ctx context.Context, tablePair TablePair, batchSi
 		BatchSize:  &batchSize,
 	}
 
-	// Get specific columns from mapping rules
+	// Get specific columns from mapping rules, and the columns the row filters read
 	sourceColumns := make([]string, len(tablePair.Rules))
 	for i, rule := range tablePair.Rules {
 		// Extract source URI from metadata
@@ -313,71 +568,579 @@ func (s *Server) copyTableData(ctx context.Context, tablePair TablePair, batchSi
 		}
 		sourceColumns[i] = sourceInfo.ColumnName
 	}
+	for _, column := range rowFilters.Columns() {
+		if !slices.Contains(sourceColumns, column) {
+			sourceColumns = append(sourceColumns, column)
+		}
+	}
 	if len(sourceColumns) > 0 {
 		streamReq.Columns = sourceColumns
 	}
 
+	// The stream is closed when a batch fails before it ends
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	stream, err := anchorClient.StreamTableData(ctx, streamReq)
 	if err != nil {
-		return 0, fmt.Errorf("failed to start data stream: %v", err)
+		return 0, nil, fmt.Errorf("failed to start data stream: %v", err)
 	}
 
-	var totalRowsProcessed int64
+	// Batches are inserted in a transaction, so a failing batch writes none of its rows and
+	// can be split to find the rows the target rejects. The network transfer of the inserts of
+	// a batch, retries included, is the part of their round trips the anchor does not report.
+	insert := func(ctx context.Context, rows []json.RawMessage, writeNetwork *time.Duration) (int64, error) {
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return 0, err
+		}
+		insertReq := &anchorv1.InsertBatchDataRequest{
+			DatabaseId:     targetInfo.DatabaseID,
+			TableName:      targetInfo.TableName,
+			Data:           data,
+			UseTransaction: &[]bool{true}[0], // Use transaction for batch insert
+		}
+		if sandbox != "" {
+			insertReq.Sandbox = &sandbox
+		}
 
-	// Process each batch
-	for {
-		batch, err := stream.Recv()
+		requestStart := time.Now()
+		insertResp, err := anchorClient.InsertBatchData(ctx, insertReq)
 		if err != nil {
-			if err.Error() == "EOF" {
-				break
+			return 0, err
+		}
+		_, network := mapping.SplitRoundTrip(time.Since(requestStart), time.Duration(insertResp.WriteDurationUs)*time.Microsecond)
+		*writeNetwork += network
+		if !insertResp.Success {
+			if len(insertResp.Errors) > 0 {
+				return 0, errors.New(strings.Join(insertResp.Errors, "; "))
 			}
-			return totalRowsProcessed, fmt.Errorf("error receiving batch: %v", err)
+			return 0, errors.New(insertResp.Message)
 		}
+		return insertResp.RowsAffected, nil
+	}
 
-		if !batch.Success {
-			return totalRowsProcessed, fmt.Errorf("batch error: %s", batch.Message)
-		}
+	// Transforms and writes a batch, alongside the other batches in flight
+	processBatch := func(batch *anchorv1.StreamTableDataResponse, chunk mapping.StageTimings) {
+		defer run.release()
 
 		// Apply transformations to the batch
-		transformedData, err := s.applyTransformations(ctx, transformationClient, batch.Data, tablePair.Rules)
+		transformStart := time.Now()
+		transformedData, err := s.applyTransformations(ctx, transformationClient, batch.Data, tablePair.Rules, rowFilters)
 		if err != nil {
+			if rowFilters != nil {
+				// The original data would include the rows the filters exclude
+				run.fail(fmt.Errorf("failed to apply transformations to batch: %v", err))
+				return
+			}
 			s.engine.logger.Warnf("Failed to apply transformations to batch: %v", err)
 			// Continue with original data if transformation fails
 			transformedData = batch.Data
 		}
 
-		// Insert transformed data into target table
-		insertReq := &anchorv1.InsertBatchDataRequest{
-			DatabaseId:     targetInfo.DatabaseID,
-			TableName:      targetInfo.TableName,
-			Data:           transformedData,
-			UseTransaction: &[]bool{true}[0], // Use transaction for batch insert
+		var rows []json.RawMessage
+		if err := json.Unmarshal(transformedData, &rows); err != nil {
+			run.fail(fmt.Errorf("failed to parse batch: %v", err))
+			return
 		}
+		chunk.Transform = time.Since(transformStart)
 
-		insertResp, err := anchorClient.InsertBatchData(ctx, insertReq)
+		// Insert transformed data into target table
+		writeStart := time.Now()
+		var writeNetwork time.Duration
+		writer := mapping.NewBatchWriter(func(ctx context.Context, rows []json.RawMessage) (int64, error) {
+			return insert(ctx, rows, &writeNetwork)
+		})
+		result, err := writer.Write(ctx, rows)
+		chunk.Network += writeNetwork
+		chunk.Write = time.Since(writeStart) - writeNetwork
+
+		totalRowsProcessed, decision, parallelism := run.record(chunk, result, err != nil)
+		if decision != nil {
+			s.engine.logger.Infof("Changed the parallelism of table pair %s from %d to %d: %s",
+				run.tablePair, decision.From, decision.To, decision.Reason)
+		}
 		if err != nil {
-			return totalRowsProcessed, fmt.Errorf("failed to insert batch: %v", err)
+			run.fail(fmt.Errorf("failed to insert batch: %v", err))
+			return
 		}
 
-		if !insertResp.Success {
-			return totalRowsProcessed, fmt.Errorf("insert batch failed: %s", insertResp.Message)
+		s.engine.logger.Infof("Processed batch %d: %d rows inserted, %d rejected, %d retries (total: %d), fetch %s, transform %s, network %s, write %s, parallelism %d",
+			batch.BatchNumber, result.RowsWritten, len(result.Rejected), result.Retries, totalRowsProcessed,
+			chunk.Fetch, chunk.Transform, chunk.Network, chunk.Write, parallelism)
+	}
+
+	// Process each batch. Batches are read ahead only as far as the parallelism lets them be
+	// transformed and written at once.
+	var wg sync.WaitGroup
+	for run.acquire() {
+		receiveStart := time.Now()
+		batch, err := stream.Recv()
+		if err != nil {
+			run.release()
+			if err.Error() != "EOF" {
+				run.fail(fmt.Errorf("error receiving batch: %v", err))
+			}
+			break
 		}
 
-		totalRowsProcessed += insertResp.RowsAffected
+		if !batch.Success {
+			run.release()
+			run.fail(fmt.Errorf("batch error: %s", batch.Message))
+			break
+		}
+		chunk := mapping.StageTimings{Batches: 1}
+		chunk.Fetch, chunk.Network = mapping.SplitRoundTrip(time.Since(receiveStart), time.Duration(batch.FetchDurationUs)*time.Microsecond)
 
-		s.engine.logger.Infof("Processed batch %d: %d rows inserted (total: %d)",
-			batch.BatchNumber, insertResp.RowsAffected, totalRowsProcessed)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			processBatch(batch, chunk)
+		}()
 
 		// Check if this was the last batch
 		if batch.IsComplete {
 			break
 		}
 	}
+	wg.Wait()
+
+	if run.err != nil {
+		return run.rows, run.rejected, run.err
+	}
 
 	s.engine.logger.Infof("Completed copying %d rows from %s to %s",
-		totalRowsProcessed, tablePair.SourceTable, tablePair.TargetTable)
+		run.rows, tablePair.SourceTable, tablePair.TargetTable)
+
+	return run.rows, run.rejected, nil
+}
+
+// tableCopyRun is what the batches of a table pair a copy processes at once share: the controller
+// of their parallelism, the decisions it took, and their totals
+type tableCopyRun struct {
+	tablePair   string
+	parallelism *mapping.ParallelismController // Shared by the table pairs of a copy, which run one at a time
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	inFlight  int
+	rows      int64
+	rejected  []mapping.RejectedRow
+	timings   mapping.StageTimings
+	decisions []mapping.ParallelismDecision
+	err       error // First failure, which stops the copy of the table pair
+}
+
+func newTableCopyRun(tablePair string, parallelism *mapping.ParallelismController) *tableCopyRun {
+	run := &tableCopyRun{tablePair: tablePair, parallelism: parallelism}
+	run.cond = sync.NewCond(&run.mu)
+	return run
+}
+
+// acquire waits until fewer batches than the parallelism are in flight and reserves a place for
+// one more, or returns false once a batch failed
+func (r *tableCopyRun) acquire() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.err == nil && r.inFlight >= r.parallelism.Parallelism() {
+		r.cond.Wait()
+	}
+	if r.err != nil {
+		return false
+	}
+	r.inFlight++
+	return true
+}
 
-	return totalRowsProcessed, nil
+// release frees the place of a batch that is no longer in flight
+func (r *tableCopyRun) release() {
+	r.mu.Lock()
+	r.inFlight--
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// fail records the failure of a batch, unless one failed before
+func (r *tableCopyRun) fail(err error) {
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// record adds a written batch to the totals and lets the controller adjust the parallelism to it.
+// It returns the rows written so far, the decision of the controller if it took one, and the
+// parallelism.
+func (r *tableCopyRun) record(chunk mapping.StageTimings, result *mapping.BatchResult, failed bool) (int64, *mapping.ParallelismDecision, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings.Add(chunk)
+	r.rows += result.RowsWritten
+	r.rejected = append(r.rejected, result.Rejected...)
+
+	// Retried and failed writes tell the target is overloaded, unlike rows it rejects
+	decision := r.parallelism.Observe(result.RowsWritten, failed || result.Retries > 0, time.Now())
+	if decision != nil {
+		r.decisions = append(r.decisions, *decision)
+		r.cond.Broadcast()
+	}
+	return r.rows, decision, r.parallelism.Parallelism()
+}
+
+// deadLetterRows is the content of a dead-letter artifact of a copy operation
+type deadLetterRows struct {
+	OperationID string                `json:"operation_id"`
+	SourceTable string                `json:"source_table"`
+	TargetTable string                `json:"target_table"`
+	Rows        []mapping.RejectedRow `json:"rows"`
+}
+
+// storeDeadLetterRows keeps the rows the target of a table pair rejected, with their errors, as
+// a dead-letter artifact and returns its ID
+func (s *Server) storeDeadLetterRows(ctx context.Context, tenantID, workspaceID, operationID string, tablePair TablePair, rows []mapping.RejectedRow) (string, error) {
+	data, err := json.MarshalIndent(deadLetterRows{
+		OperationID: operationID,
+		SourceTable: tablePair.SourceTable,
+		TargetTable: tablePair.TargetTable,
+		Rows:        rows,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s.%s.json", operationID, tablePair.TargetTable)
+	stored, err := artifact.NewService(s.engine.db, s.engine.logger).Put(ctx, tenantID, workspaceID, artifact.KindDeadLetter, name, "application/json", data)
+	if err != nil {
+		return "", err
+	}
+	return stored.ID, nil
+}
+
+// createCopySandbox creates a sandbox with copies of the target tables of the table pairs in
+// every target database, and returns the databases it was created in
+func (s *Server) createCopySandbox(ctx context.Context, sandbox string, tablePairs []TablePair) ([]string, error) {
+	// Target tables by database
+	var databases []string
+	tables := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, tablePair := range tablePairs {
+		if seen[tablePair.TargetTable] {
+			continue
+		}
+		seen[tablePair.TargetTable] = true
+
+		targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target table: %v", err)
+		}
+		if _, ok := tables[targetInfo.DatabaseID]; !ok {
+			databases = append(databases, targetInfo.DatabaseID)
+		}
+		tables[targetInfo.DatabaseID] = append(tables[targetInfo.DatabaseID], targetInfo.TableName)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+
+	for i, databaseID := range databases {
+		resp, err := anchorClient.CreateSandbox(ctx, &anchorv1.CreateSandboxRequest{
+			DatabaseId: databaseID,
+			Sandbox:    sandbox,
+			Tables:     tables[databaseID],
+		})
+		if err == nil && !resp.Success {
+			err = fmt.Errorf("%s", resp.Message)
+		}
+		if err != nil {
+			// Drop the sandboxes created in the other databases
+			s.dropCopySandbox(sandbox, databases[:i])
+			return nil, fmt.Errorf("database %s: %v", databaseID, err)
+		}
+	}
+	return databases, nil
+}
+
+// dropCopySandbox drops a sandbox from databases. It runs when a copy ends, even if the stream
+// of the copy was canceled, so it does not use the context of the stream.
+func (s *Server) dropCopySandbox(sandbox string, databases []string) {
+	if len(databases) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		s.engine.logger.Errorf("Failed to drop sandbox %s: %v", sandbox, err)
+		return
+	}
+	for _, databaseID := range databases {
+		resp, err := anchorClient.DropSandbox(ctx, &anchorv1.DropSandboxRequest{
+			DatabaseId: databaseID,
+			Sandbox:    sandbox,
+		})
+		if err == nil && !resp.Success {
+			err = fmt.Errorf("%s", resp.Message)
+		}
+		if err != nil {
+			s.engine.logger.Errorf("Failed to drop sandbox %s from database %s: %v", sandbox, databaseID, err)
+		}
+	}
+}
+
+// fetchSandboxSample returns JSON encoded rows of the copy of the target table of a table pair
+// in a sandbox
+func (s *Server) fetchSandboxSample(ctx context.Context, sandbox string, tablePair TablePair, sampleSize int32) ([]byte, error) {
+	targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target table: %v", err)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+
+	options, err := json.Marshal(map[string]interface{}{"limit": sampleSize})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := anchorClient.FetchData(ctx, &anchorv1.FetchDataRequest{
+		DatabaseId: targetInfo.DatabaseID,
+		TableName:  targetInfo.TableName,
+		Options:    options,
+		Sandbox:    &sandbox,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	return resp.Data, nil
+}
+
+// validateCopiedVectors samples vectors from the target table of a table pair and searches their
+// nearest neighbors in the target and, when it is a vector store too, in the source. It returns
+// nil if the target cannot search vectors.
+func (s *Server) validateCopiedVectors(ctx context.Context, tablePair TablePair, samples int32) (*corev1.VectorValidation, error) {
+	sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source table: %v", err)
+	}
+	targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target table: %v", err)
+	}
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	targetDB, err := databaseService.GetByID(ctx, targetInfo.DatabaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target database: %v", err)
+	}
+	if !searchesVectors(targetDB.Type) {
+		return nil, nil
+	}
+	sourceDB, err := databaseService.GetByID(ctx, sourceInfo.DatabaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source database: %v", err)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+	options, err := json.Marshal(map[string]interface{}{"limit": samples})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := anchorClient.FetchData(ctx, &anchorv1.FetchDataRequest{
+		DatabaseId: targetInfo.DatabaseID,
+		TableName:  targetInfo.TableName,
+		Options:    options,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vectors from the target: %v", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to read vectors from the target: %s", resp.Message)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(resp.Data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode the vectors of the target: %v", err)
+	}
+
+	validator := mapping.NewVectorValidator(vectorSearch(anchorClient, targetInfo), nil)
+	if searchesVectors(sourceDB.Type) {
+		validator.Source = vectorSearch(anchorClient, sourceInfo)
+	}
+	result := validator.Validate(ctx, mapping.SampleVectors(rows))
+
+	dimensions := make([]int32, len(result.Dimensions))
+	for i, dimension := range result.Dimensions {
+		dimensions[i] = int32(dimension)
+	}
+	return &corev1.VectorValidation{
+		TablePair:      fmt.Sprintf("%s -> %s", tablePair.SourceTable, tablePair.TargetTable),
+		Samples:        int32(result.Samples),
+		TopK:           int32(result.TopK),
+		Dimensions:     dimensions,
+		SelfMatchRate:  result.SelfMatchRate,
+		SourceCompared: result.SourceCompared,
+		Recall:         result.Recall,
+		MinRecall:      result.MinRecall,
+		FailedSearches: int32(result.FailedSearches),
+		Issues:         result.Issues,
+		Passed:         result.Passed,
+	}, nil
+}
+
+// searchesVectors reports whether the adapter of a database type searches nearest neighbors
+func searchesVectors(databaseType string) bool {
+	id, ok := dbcapabilities.ParseID(databaseType)
+	return ok && dbcapabilities.SupportsOperation(id, dbcapabilities.OpVectorSearch)
+}
+
+// vectorSearch returns a search of the nearest neighbors in a table through the anchor
+func vectorSearch(client anchorv1.AnchorServiceClient, table *TableIdentifierInfo) mapping.SearchFunc {
+	return func(ctx context.Context, vector []float32, topK int) ([]string, error) {
+		resp, err := client.SearchVectors(ctx, &anchorv1.SearchVectorsRequest{
+			DatabaseId: table.DatabaseID,
+			TableName:  table.TableName,
+			Vector:     vector,
+			TopK:       int32(topK),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("%s", resp.Message)
+		}
+		ids := make([]string, len(resp.Matches))
+		for i, match := range resp.Matches {
+			ids[i] = match.Id
+		}
+		return ids, nil
+	}
+}
+
+// verifyCopiedGraph samples the edges of the source table of a table pair and of its target, a
+// relationship of a graph database or an edge table, and compares their structure. It returns
+// nil if the source is not a relationship of a graph database, or if the target stores no edges.
+func (s *Server) verifyCopiedGraph(ctx context.Context, tablePair TablePair, limit int32) (*corev1.GraphVerification, error) {
+	sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source table: %v", err)
+	}
+	targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target table: %v", err)
+	}
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	sourceDB, err := databaseService.GetByID(ctx, sourceInfo.DatabaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source database: %v", err)
+	}
+	if !samplesEdges(sourceDB.Type) {
+		return nil, nil
+	}
+	targetDB, err := databaseService.GetByID(ctx, targetInfo.DatabaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target database: %v", err)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+	sourceEdges, err := sampleEdges(ctx, anchorClient, sourceInfo, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample the edges of the source: %v", err)
+	}
+	if len(sourceEdges) == 0 {
+		// The table pair copies nodes, or an empty relationship
+		return nil, nil
+	}
+
+	var targetEdges []mapping.Edge
+	if samplesEdges(targetDB.Type) {
+		targetEdges, err = sampleEdges(ctx, anchorClient, targetInfo, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample the edges of the target: %v", err)
+		}
+	} else {
+		options, err := json.Marshal(map[string]interface{}{"limit": limit})
+		if err != nil {
+			return nil, err
+		}
+		resp, err := anchorClient.FetchData(ctx, &anchorv1.FetchDataRequest{
+			DatabaseId: targetInfo.DatabaseID,
+			TableName:  targetInfo.TableName,
+			Options:    options,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read edges from the target: %v", err)
+		}
+		if !resp.Success {
+			return nil, fmt.Errorf("failed to read edges from the target: %s", resp.Message)
+		}
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(resp.Data, &rows); err != nil {
+			return nil, fmt.Errorf("failed to decode the edges of the target: %v", err)
+		}
+		var ok bool
+		targetEdges, ok = mapping.EdgesFromRows(rows)
+		if !ok {
+			s.engine.logger.Warnf("Target table %s has no edge columns, the edges copied into it are not verified", tablePair.TargetTable)
+			return nil, nil
+		}
+	}
+
+	result := mapping.NewGraphVerifier(int(limit)).Verify(sourceEdges, targetEdges)
+	return &corev1.GraphVerification{
+		TablePair:           fmt.Sprintf("%s -> %s", tablePair.SourceTable, tablePair.TargetTable),
+		SourceEdges:         int32(result.Source.Edges),
+		TargetEdges:         int32(result.Target.Edges),
+		SourceNodes:         int32(result.Source.Nodes),
+		TargetNodes:         int32(result.Target.Nodes),
+		Complete:            result.Complete,
+		OutDegreeDivergence: result.OutDegreeDivergence,
+		InDegreeDivergence:  result.InDegreeDivergence,
+		PathDivergence:      result.PathDivergence,
+		Issues:              result.Issues,
+		Passed:              result.Passed,
+	}, nil
+}
+
+// samplesEdges reports whether the adapter of a database type reads relationships as edges
+func samplesEdges(databaseType string) bool {
+	id, ok := dbcapabilities.ParseID(databaseType)
+	return ok && dbcapabilities.SupportsOperation(id, dbcapabilities.OpSampleEdges)
+}
+
+// sampleEdges reads up to limit edges of a relationship through the anchor
+func sampleEdges(ctx context.Context, client anchorv1.AnchorServiceClient, table *TableIdentifierInfo, limit int32) ([]mapping.Edge, error) {
+	resp, err := client.SampleEdges(ctx, &anchorv1.SampleEdgesRequest{
+		DatabaseId:   table.DatabaseID,
+		Relationship: table.TableName,
+		Limit:        limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	edges := make([]mapping.Edge, len(resp.Edges))
+	for i, edge := range resp.Edges {
+		edges[i] = mapping.Edge{From: edge.From, To: edge.To}
+	}
+	return edges, nil
 }
 
 // Helper method to parse table identifier (database_id.table_name)
@@ -403,7 +1166,7 @@ type TableIdentifierInfo struct {
 func (s *Server) getAnchorClient() (anchorv1.AnchorServiceClient, error) {
 	anchorAddr := grpcconfig.GetServiceAddress(s.engine.config, "anchor")
 
-	conn, err := grpc.Dial(anchorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to anchor service at %s: %v", anchorAddr, err)
 	}
@@ -415,7 +1178,7 @@ func (s *Server) getAnchorClient() (anchorv1.AnchorServiceClient, error) {
 func (s *Server) getTransformationClient() (transformationv1.TransformationServiceClient, error) {
 	transformationAddr := grpcconfig.GetServiceAddress(s.engine.config, "transformation")
 
-	conn, err := grpc.Dial(transformationAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(transformationAddr, spiffe.DialOption())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to transformation service at %s: %v", transformationAddr, err)
 	}
@@ -423,8 +1186,9 @@ func (s *Server) getTransformationClient() (transformationv1.TransformationServi
 	return transformationv1.NewTransformationServiceClient(conn), nil
 }
 
-// applyTransformations applies transformation rules to a batch of data
-func (s *Server) applyTransformations(ctx context.Context, client transformationv1.TransformationServiceClient, data []byte, rules []*mapping.Rule) ([]byte, error) {
+// applyTransformations applies transformation rules to a batch of data, leaving out the rows
+// the row filters exclude
+func (s *Server) applyTransformations(ctx context.Context, client transformationv1.TransformationServiceClient, data []byte, rules []*mapping.Rule, rowFilters *mapping.RowFilters) ([]byte, error) {
 	// Parse the JSON data (array of rows)
 	var sourceRows []map[string]interface{}
 	if err := json.Unmarshal(data, &sourceRows); err != nil {
@@ -434,62 +1198,161 @@ func (s *Server) applyTransformations(ctx context.Context, client transformation
 	// Transform each row
 	targetRows := make([]map[string]interface{}, 0, len(sourceRows))
 	for _, sourceRow := range sourceRows {
-		targetRow := make(map[string]interface{})
+		rowRules, written := rowFilters.SelectRules(sourceRow, rules)
+		if !written {
+			continue
+		}
+		targetRow, failures := s.transformRow(ctx, client, sourceRow, rowRules)
+		for _, failure := range failures {
+			s.engine.logger.Warnf("%s", failure)
+		}
+		targetRows = append(targetRows, targetRow)
+	}
 
-		// Apply each mapping rule
-		for _, rule := range rules {
-			// Extract source and target column names from metadata
-			sourceColumn, _ := rule.Metadata["source_column"].(string)
-			targetColumn, _ := rule.Metadata["target_column"].(string)
-			transformationName, _ := rule.Metadata["transformation_name"].(string)
+	// Convert back to JSON
+	transformedData, err := json.Marshal(targetRows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transformed data: %v", err)
+	}
 
-			if sourceColumn == "" || targetColumn == "" {
-				s.engine.logger.Warnf("Rule missing source or target column in metadata")
-				continue
-			}
+	return transformedData, nil
+}
+
+// transformRow applies mapping rules to a source row and returns the target row. Rules failing
+// on the row are reported: a rule with a single source column writes the source value unchanged
+// when its transformation fails, and other failing rules write nothing.
+func (s *Server) transformRow(ctx context.Context, client transformationv1.TransformationServiceClient, sourceRow map[string]interface{}, rules []*mapping.Rule) (map[string]interface{}, []string) {
+	targetRow := make(map[string]interface{})
+	var failures []string
 
-			// Get the source value
-			sourceValue, exists := sourceRow[sourceColumn]
-			if !exists {
-				s.engine.logger.Warnf("Source column '%s' not found in row data", sourceColumn)
+	// Apply each mapping rule
+	for _, rule := range rules {
+		// Extract source and target column names from metadata
+		sourceColumns, targetColumns := mapping.RuleColumns(rule)
+		transformationName, _ := rule.Metadata["transformation_name"].(string)
+
+		if len(sourceColumns) == 0 || len(targetColumns) == 0 {
+			failures = append(failures, fmt.Sprintf("Rule '%s' missing source or target column in metadata", rule.Name))
+			continue
+		}
+
+		// Many-to-one and one-to-many rules transform the values of all their columns at once
+		if len(sourceColumns) > 1 || len(targetColumns) > 1 {
+			targetValues, err := s.applyCompositeTransformation(ctx, client, rule, transformationName, sourceRow, sourceColumns, len(targetColumns))
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("Failed to apply mapping rule '%s' to columns %v: %v, skipping it",
+					rule.Name, sourceColumns, err))
 				continue
 			}
+			for i, targetColumn := range targetColumns {
+				targetRow[targetColumn] = targetValues[i]
+			}
+			continue
+		}
+		sourceColumn, targetColumn := sourceColumns[0], targetColumns[0]
 
-			// Apply transformation if needed
-			var targetValue interface{}
-			if transformationName != "" && transformationName != "direct_mapping" {
-				// Call transformation service for non-direct transformations
-				transformedValue, err := s.applyTransformation(ctx, client, transformationName, sourceValue)
-				if err != nil {
-					s.engine.logger.Warnf("Failed to apply transformation '%s' to column '%s': %v, using original value",
-						transformationName, sourceColumn, err)
-					targetValue = sourceValue
-				} else {
-					targetValue = transformedValue
-				}
-			} else {
-				// Direct mapping - no transformation needed
+		// Get the source value
+		sourceValue, exists := sourceRow[sourceColumn]
+		if !exists {
+			failures = append(failures, fmt.Sprintf("Source column '%s' of rule '%s' not found in row data", sourceColumn, rule.Name))
+			continue
+		}
+
+		// Apply transformation if needed
+		var targetValue interface{}
+		if transformationName != "" && transformationName != "direct_mapping" {
+			// Call transformation service for non-direct transformations
+			transformedValue, err := s.applyTransformation(ctx, client, rule, transformationName, sourceValue)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("Failed to apply transformation '%s' of rule '%s' to column '%s': %v, using original value",
+					transformationName, rule.Name, sourceColumn, err))
 				targetValue = sourceValue
+			} else {
+				targetValue = transformedValue
 			}
+		} else {
+			// Direct mapping - no transformation needed
+			targetValue = sourceValue
+		}
 
-			// Set the target column with the (possibly transformed) value
-			targetRow[targetColumn] = targetValue
+		// Set the target column with the (possibly transformed) value
+		targetRow[targetColumn] = targetValue
+	}
+
+	return targetRow, failures
+}
+
+// applyCompositeTransformation applies the transformation of a many-to-one or one-to-many rule to
+// the values of its source columns in a row, and returns the values of its target columns
+func (s *Server) applyCompositeTransformation(ctx context.Context, client transformationv1.TransformationServiceClient, rule *mapping.Rule, transformationName string, sourceRow map[string]interface{}, sourceColumns []string, targetCount int) ([]interface{}, error) {
+	inputs := make([]string, len(sourceColumns))
+	for i, column := range sourceColumns {
+		value, exists := sourceRow[column]
+		if !exists {
+			return nil, fmt.Errorf("source column '%s' not found in row data", column)
 		}
+		if value != nil {
+			inputs[i] = fmt.Sprintf("%v", value)
+		}
+	}
 
-		targetRows = append(targetRows, targetRow)
+	// Without a transformation, a one-to-many rule writes its value to every target column
+	if transformationName == "" || transformationName == "direct_mapping" {
+		if len(sourceColumns) > 1 {
+			return nil, fmt.Errorf("the rule has no transformation combining its %d source columns", len(sourceColumns))
+		}
+		targetValues := make([]interface{}, targetCount)
+		for i := range targetValues {
+			targetValues[i] = sourceRow[sourceColumns[0]]
+		}
+		return targetValues, nil
 	}
 
-	// Convert back to JSON
-	transformedData, err := json.Marshal(targetRows)
+	transformReq := &transformationv1.TransformRequest{
+		FunctionName: transformationName,
+		Inputs:       inputs,
+		OutputCount:  int32(targetCount),
+		TenantId:     rule.TenantID,
+	}
+	parameters, err := transformationParameters(rule)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal transformed data: %v", err)
+		return nil, err
 	}
+	transformReq.Parameters = parameters
 
-	return transformedData, nil
+	transformResp, err := client.Transform(ctx, transformReq)
+	if err != nil {
+		return nil, fmt.Errorf("transformation service error: %v", err)
+	}
+	if transformResp.Status != commonv1.Status_STATUS_SUCCESS {
+		return nil, fmt.Errorf("transformation failed: %s", transformResp.StatusMessage)
+	}
+	if len(transformResp.Outputs) != targetCount {
+		return nil, fmt.Errorf("transformation returned %d values for %d target columns", len(transformResp.Outputs), targetCount)
+	}
+
+	targetValues := make([]interface{}, targetCount)
+	for i, output := range transformResp.Outputs {
+		targetValues[i] = output
+	}
+	return targetValues, nil
+}
+
+// transformationParameters returns the transformation options of a rule, or nil without options
+func transformationParameters(rule *mapping.Rule) (*structpb.Struct, error) {
+	options, ok := rule.Metadata["transformation_options"].(map[string]interface{})
+	if !ok || len(options) == 0 {
+		return nil, nil
+	}
+	parameters, err := structpb.NewStruct(options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transformation options: %v", err)
+	}
+	return parameters, nil
 }
 
-// applyTransformation applies a single transformation to a value
-func (s *Server) applyTransformation(ctx context.Context, client transformationv1.TransformationServiceClient, transformationName string, value interface{}) (interface{}, error) {
+// applyTransformation applies the transformation of a rule, with its options, to a value
+func (s *Server) applyTransformation(ctx context.Context, client transformationv1.TransformationServiceClient, rule *mapping.Rule, transformationName string, value interface{}) (interface{}, error) {
 	// Convert value to string for transformation
 	var inputStr string
 	switch v := value.(type) {
@@ -503,9 +1366,15 @@ func (s *Server) applyTransformation(ctx context.Context, client transformationv
 	}
 
 	// Call transformation service
+	parameters, err := transformationParameters(rule)
+	if err != nil {
+		return nil, err
+	}
 	transformReq := &transformationv1.TransformRequest{
 		FunctionName: transformationName,
 		Input:        inputStr,
+		Parameters:   parameters,
+		TenantId:     rule.TenantID,
 	}
 
 	transformResp, err := client.Transform(ctx, transformReq)
@@ -0,0 +1,307 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExportMapping describes a table or database mapping and its rules as a mapping document, with
+// the databases it references by name, so that it can be kept in git and imported in other
+// workspaces
+func (s *Server) ExportMapping(ctx context.Context, req *corev1.ExportMappingRequest) (*corev1.ExportMappingResponse, error) {
+	defer s.trackOperation()()
+
+	format := strings.ToLower(req.Format)
+	if format == "" {
+		format = mapping.MappingDocumentFormatYAML
+	}
+	if format != mapping.MappingDocumentFormatYAML && format != mapping.MappingDocumentFormatJSON {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported format %q: must be yaml or json", req.Format)
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMapping(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	databaseNames, _, err := s.workspaceDatabaseNames(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	doc, err := mapping.NewMappingDocument(mappingObj, rules, databaseNames)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	data, err := mapping.EncodeMappingDocument(doc, format)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &corev1.ExportMappingResponse{
+		Document:  string(data),
+		Format:    format,
+		RuleCount: int32(len(doc.Rules)),
+		Message:   fmt.Sprintf("Mapping %s exported with %d rules", mappingObj.Name, len(doc.Rules)),
+		Success:   true,
+		Status:    commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ImportMapping creates a mapping and its rules, in order, from a mapping document. The rules of
+// the document are added as they are, without running the matcher, and are renamed when rules of
+// the workspace already use their names. The mapping and its rules are staged under temporary
+// names and swapped in once every rule is added, so that a failed import leaves the workspace,
+// and the mapping it would replace, as they were.
+func (s *Server) ImportMapping(ctx context.Context, req *corev1.ImportMappingRequest) (*corev1.ImportMappingResponse, error) {
+	defer s.trackOperation()()
+
+	doc, err := mapping.DecodeMappingDocument([]byte(req.Document))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if req.GetMappingName() != "" {
+		doc.Name = req.GetMappingName()
+	}
+	doc.RenameDatabases(req.DatabaseNames)
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	_, databaseIDs, err := s.workspaceDatabaseNames(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Resolve all references before changing anything, so that a document referencing a database
+	// missing from the workspace leaves an existing mapping in place
+	for _, reference := range []string{doc.Source, doc.Target} {
+		if strings.Contains(reference, ":/") {
+			continue
+		}
+		databaseName, _, _ := strings.Cut(reference, ".")
+		if _, ok := databaseIDs[databaseName]; !ok {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "database %s of %s not found in the workspace", databaseName, reference)
+		}
+	}
+	type resolvedRule struct {
+		sources []string
+		targets []string
+	}
+	resolved := make([]resolvedRule, len(doc.Rules))
+	for i, rule := range doc.Rules {
+		for _, side := range []struct {
+			references []string
+			uris       *[]string
+		}{
+			{rule.Sources, &resolved[i].sources},
+			{rule.Targets, &resolved[i].targets},
+		} {
+			for _, reference := range side.references {
+				uri, err := mapping.ResolveColumnReference(reference, databaseIDs)
+				if err != nil {
+					s.engine.IncrementErrors()
+					return nil, status.Errorf(codes.InvalidArgument, "rule %s: %v", rule.Name, err)
+				}
+				*side.uris = append(*side.uris, uri)
+			}
+		}
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	_, err = mappingService.Get(ctx, req.TenantId, workspaceID, doc.Name)
+	replace := err == nil
+	if replace && !req.Replace {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.AlreadyExists, "mapping %s already exists", doc.Name)
+	}
+
+	// The mapping and its rules are staged under temporary names, so that the transformations and
+	// policies of every rule are checked before the mapping replaced is touched
+	stageSuffix := fmt.Sprintf("_import_%d", time.Now().UnixNano())
+	stagedName := doc.Name + stageSuffix
+
+	tablePins := make([]*corev1.MappingTablePin, len(doc.TablePins))
+	for i, pin := range doc.TablePins {
+		tablePins[i] = &corev1.MappingTablePin{SourceTable: pin.SourceTable, TargetTable: pin.TargetTable, Never: pin.Never}
+	}
+	addReq := &corev1.AddMappingRequest{
+		TenantId:           req.TenantId,
+		WorkspaceName:      req.WorkspaceName,
+		MappingName:        stagedName,
+		MappingDescription: doc.Description,
+		Scope:              doc.Scope,
+		Source:             doc.Source,
+		Target:             doc.Target,
+		OwnerId:            req.OwnerId,
+		GenerateRules:      false,
+		TablePins:          tablePins,
+	}
+	if doc.RowFilter != "" {
+		addReq.RowFilter = &doc.RowFilter
+	}
+	addResp, err := s.AddMapping(ctx, addReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// removeStaged removes the staged mapping and its rules when the import fails
+	removeStaged := func(cause error) error {
+		if err := mappingService.Delete(ctx, req.TenantId, workspaceID, stagedName, false); err != nil {
+			s.engine.logger.Errorf("Failed to remove mapping %s after a failed import: %v", stagedName, err)
+		}
+		return cause
+	}
+
+	if len(doc.ConflictKeys) > 0 {
+		if err := mappingService.SetConflictKeys(ctx, addResp.Mapping.MappingId, doc.ConflictKeys); err != nil {
+			s.engine.IncrementErrors()
+			return nil, removeStaged(status.Errorf(codes.InvalidArgument, "invalid conflict keys: %v", err))
+		}
+	}
+
+	for i, rule := range doc.Rules {
+		stagedRuleName := rule.Name + stageSuffix
+		ruleReq := &corev1.AddMappingRuleRequest{
+			TenantId:                       req.TenantId,
+			WorkspaceName:                  req.WorkspaceName,
+			MappingRuleName:                stagedRuleName,
+			MappingRuleDescription:         rule.Description,
+			MappingRuleTransformationName:  rule.Transformation,
+			MappingRuleTransformationChain: rule.TransformationChain,
+			MappingRuleRowFilter:           rule.RowFilter,
+			OwnerId:                        req.OwnerId,
+			SourceItemUris:                 resolved[i].sources,
+			TargetItemUris:                 resolved[i].targets,
+		}
+		if len(rule.TransformationOptions) > 0 {
+			options, err := json.Marshal(rule.TransformationOptions)
+			if err != nil {
+				s.engine.IncrementErrors()
+				return nil, removeStaged(status.Errorf(codes.InvalidArgument, "rule %s: invalid transformation options: %v", rule.Name, err))
+			}
+			ruleReq.MappingRuleTransformationOptions = string(options)
+		}
+		if _, err := s.AddMappingRule(ctx, ruleReq); err != nil {
+			return nil, removeStaged(status.Errorf(status.Code(err), "rule %s: %s", rule.Name, status.Convert(err).Message()))
+		}
+		if err := mappingService.AttachMappingRule(ctx, req.TenantId, workspaceID, stagedName, stagedRuleName, nil); err != nil {
+			s.engine.IncrementErrors()
+			if deleteErr := mappingService.DeleteMappingRule(ctx, req.TenantId, workspaceID, stagedRuleName); deleteErr != nil {
+				s.engine.logger.Errorf("Failed to remove mapping rule %s after a failed import: %v", stagedRuleName, deleteErr)
+			}
+			return nil, removeStaged(status.Errorf(codes.Internal, "failed to attach rule %s: %v", rule.Name, err))
+		}
+	}
+
+	// Every rule was added: swap the staged mapping in
+	if replace {
+		if err := mappingService.Delete(ctx, req.TenantId, workspaceID, doc.Name, false); err != nil {
+			s.engine.IncrementErrors()
+			return nil, removeStaged(status.Errorf(codes.FailedPrecondition, "failed to replace mapping %s: %v", doc.Name, err))
+		}
+	}
+	if _, err := mappingService.Update(ctx, req.TenantId, workspaceID, stagedName, map[string]interface{}{"mapping_name": doc.Name}); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "mapping imported as %s, but it could not be renamed %s: %v", stagedName, doc.Name, err)
+	}
+
+	// Rules keep their names, unless rules outside the mapping already use them
+	workspaceRules, err := mappingService.ListMappingRules(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "mapping %s imported, but its rules could not be renamed: %v", doc.Name, err)
+	}
+	taken := make(map[string]bool, len(workspaceRules))
+	for _, rule := range workspaceRules {
+		taken[rule.Name] = true
+	}
+	renamedRules := make(map[string]string)
+	for _, rule := range doc.Rules {
+		name := mapping.ImportedRuleName(doc.Name, rule.Name, taken)
+		taken[name] = true
+		if _, err := mappingService.ModifyMappingRule(ctx, req.TenantId, workspaceID, rule.Name+stageSuffix, map[string]interface{}{"mapping_rule_name": name}); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "mapping %s imported, but rule %s could not be renamed from %s: %v", doc.Name, rule.Name, rule.Name+stageSuffix, err)
+		}
+		if name != rule.Name {
+			renamedRules[rule.Name] = name
+		}
+	}
+
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, doc.Name)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get imported mapping: %v", err)
+	}
+	protoMapping, err := s.mappingToProtoWithContext(ctx, mappingObj)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to convert mapping: %v", err)
+	}
+
+	message := fmt.Sprintf("Mapping %s imported with %d rules", doc.Name, len(doc.Rules))
+	if len(renamedRules) > 0 {
+		message += fmt.Sprintf(", %d renamed as their names are used in the workspace", len(renamedRules))
+	}
+	return &corev1.ImportMappingResponse{
+		Mapping:      protoMapping,
+		RuleCount:    int32(len(doc.Rules)),
+		Replaced:     replace,
+		RenamedRules: renamedRules,
+		Message:      message,
+		Success:      true,
+		Status:       commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// workspaceDatabaseNames returns the names of the databases of a workspace by ID, and their IDs
+// by name
+func (s *Server) workspaceDatabaseNames(ctx context.Context, tenantID, workspaceID string) (map[string]string, map[string]string, error) {
+	databases, err := database.NewService(s.engine.db, s.engine.logger).List(ctx, tenantID, workspaceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list databases: %w", err)
+	}
+	names := make(map[string]string, len(databases))
+	ids := make(map[string]string, len(databases))
+	for _, db := range databases {
+		names[db.ID] = db.Name
+		ids[db.Name] = db.ID
+	}
+	return names, ids, nil
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListMatchFeedback lists what the matcher of a workspace learned from the corrections of the
+// rules it generated
+func (s *Server) ListMatchFeedback(ctx context.Context, req *corev1.ListMatchFeedbackRequest) (*corev1.ListMatchFeedbackResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	feedback, err := mapping.NewService(s.engine.db, s.engine.logger).ListMatchFeedback(ctx, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list match feedback: %v", err)
+	}
+
+	protoFeedback := make([]*corev1.MatchFeedback, 0, len(feedback))
+	for _, f := range feedback {
+		protoFeedback = append(protoFeedback, &corev1.MatchFeedback{
+			SourceColumn: f.SourceColumn,
+			TargetColumn: f.TargetColumn,
+			Accepted:     int32(f.Accepted),
+			Rejected:     int32(f.Rejected),
+			Adjustment:   f.Adjustment(),
+			Updated:      f.Updated.Format(time.RFC3339),
+		})
+	}
+	return &corev1.ListMatchFeedbackResponse{
+		Feedback: protoFeedback,
+		Message:  fmt.Sprintf("Found %d corrected matches", len(protoFeedback)),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ResetMatchFeedback forgets what the matcher of a workspace learned, all of it or that of a
+// source and/or a target column
+func (s *Server) ResetMatchFeedback(ctx context.Context, req *corev1.ResetMatchFeedbackRequest) (*corev1.ResetMatchFeedbackResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	count, err := mapping.NewService(s.engine.db, s.engine.logger).ResetMatchFeedback(ctx, workspaceID, req.GetSourceColumn(), req.GetTargetColumn())
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to reset match feedback: %v", err)
+	}
+
+	return &corev1.ResetMatchFeedbackResponse{
+		ResetCount: int32(count),
+		Message:    fmt.Sprintf("Forgot the feedback on %d matches", count),
+		Success:    true,
+		Status:     commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// matchOptions returns the options of the matcher generating the rules of a new mapping: those
+// of its match profile, with the feedback of the workspace. Without the feedback, the matcher
+// still runs.
+func (s *Server) matchOptions(ctx context.Context, mappingService *mapping.Service, workspaceID string, profile *mapping.MatchProfile) *unifiedmodelv1.MatchOptions {
+	options := matchOptionsToProto(profile.Options)
+
+	feedback, err := mappingService.ListMatchFeedback(ctx, workspaceID)
+	if err != nil {
+		s.engine.logger.Warnf("Matching without the feedback of the workspace: %v", err)
+		return options
+	}
+	for _, f := range feedback {
+		if adjustment := f.Adjustment(); adjustment != 0 {
+			options.Feedback = append(options.Feedback, &unifiedmodelv1.MatchFeedback{
+				SourceColumn: f.SourceColumn,
+				TargetColumn: f.TargetColumn,
+				Adjustment:   adjustment,
+				Accepted:     int32(f.Accepted),
+				Rejected:     int32(f.Rejected),
+			})
+		}
+	}
+	return options
+}
+
+// recordRuleRejection records that users rejected the match a generated rule was created for,
+// by removing the rule from a mapping
+func (s *Server) recordRuleRejection(ctx context.Context, mappingService *mapping.Service, workspaceID string, rule *mapping.Rule) {
+	sourceColumn, targetColumn, ok := mapping.GeneratedMatch(rule)
+	if !ok {
+		return
+	}
+	if err := mappingService.RecordMatchFeedback(ctx, workspaceID, sourceColumn, targetColumn, false); err != nil {
+		s.engine.logger.Warnf("Failed to record the rejection of mapping rule %s: %v", rule.Name, err)
+	}
+}
+
+// matchCorrection is a change of the source or target of a generated rule: the columns of its
+// match are rejected and those it is re-pointed to accepted
+type matchCorrection struct {
+	rejectedSource, rejectedTarget string
+	acceptedSource, acceptedTarget string
+}
+
+// correctGeneratedRule updates the metadata of a generated rule re-pointed to other columns, and
+// returns the correction of its match, or nil when the rule was not generated or its columns do
+// not change. The rule is no longer generated, so that later changes are not recorded again.
+func correctGeneratedRule(rule *mapping.Rule, metadata map[string]interface{}) *matchCorrection {
+	sourceColumn, targetColumn, ok := mapping.GeneratedMatch(rule)
+	if !ok {
+		return nil
+	}
+
+	correction := &matchCorrection{
+		rejectedSource: sourceColumn, rejectedTarget: targetColumn,
+		acceptedSource: sourceColumn, acceptedTarget: targetColumn,
+	}
+	sourceTable, newSourceColumn, sourceOK := mapping.ColumnOfURI(getString(metadata, "source_resource_uri"))
+	if sourceOK {
+		correction.acceptedSource = newSourceColumn
+	}
+	targetTable, newTargetColumn, targetOK := mapping.ColumnOfURI(getString(metadata, "target_resource_uri"))
+	if targetOK {
+		correction.acceptedTarget = newTargetColumn
+	}
+	if correction.acceptedSource == sourceColumn && correction.acceptedTarget == targetColumn {
+		return nil
+	}
+
+	if sourceOK {
+		metadata["source_table"], metadata["source_column"] = sourceTable, newSourceColumn
+	}
+	if targetOK {
+		metadata["target_table"], metadata["target_column"] = targetTable, newTargetColumn
+	}
+	metadata["match_type"] = "user_defined"
+	delete(metadata, "match_reasons")
+	return correction
+}
+
+// recordMatchCorrection records the correction of the match of a generated rule
+func (s *Server) recordMatchCorrection(ctx context.Context, mappingService *mapping.Service, workspaceID, ruleName string, correction *matchCorrection) {
+	if correction == nil {
+		return
+	}
+	if err := mappingService.RecordMatchFeedback(ctx, workspaceID, correction.rejectedSource, correction.rejectedTarget, false); err != nil {
+		s.engine.logger.Warnf("Failed to record the correction of mapping rule %s: %v", ruleName, err)
+		return
+	}
+	if err := mappingService.RecordMatchFeedback(ctx, workspaceID, correction.acceptedSource, correction.acceptedTarget, true); err != nil {
+		s.engine.logger.Warnf("Failed to record the correction of mapping rule %s: %v", ruleName, err)
+	}
+}
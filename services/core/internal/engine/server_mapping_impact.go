@@ -0,0 +1,294 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of a mapping rule that change the data it moves; changing only the name or the
+// description of a rule is not destructive
+var dataFields = map[string]bool{
+	"source":                 true,
+	"target":                 true,
+	"transformation":         true,
+	"transformation_options": true,
+	"metadata":               true,
+}
+
+// AnalyzeMappingRuleImpact reports what a proposed change or deletion of a mapping rule would
+// affect, without applying it: the mappings using the rule, the relationships running them, the
+// resources downstream of the rule and the transformation policies the rule would stop satisfying.
+func (s *Server) AnalyzeMappingRuleImpact(ctx context.Context, req *corev1.AnalyzeMappingRuleImpactRequest) (*corev1.AnalyzeMappingRuleImpactResponse, error) {
+	defer s.trackOperation()()
+
+	// Get workspace ID from workspace name
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+
+	rule, err := mappingService.GetMappingRuleByName(ctx, req.TenantId, workspaceID, req.MappingRuleName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping rule not found: %v", err)
+	}
+
+	// Apply the proposed change to a copy of the metadata of the rule
+	proposed, changedFields, err := proposedRuleMetadata(rule.Metadata, req)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	impact := &corev1.MappingRuleImpact{
+		MappingRuleName: rule.Name,
+		Delete:          req.Delete,
+		ChangedFields:   changedFields,
+		Destructive:     req.Delete,
+	}
+	for _, field := range changedFields {
+		if dataFields[field] {
+			impact.Destructive = true
+		}
+	}
+
+	if !req.Delete && len(changedFields) == 0 {
+		return &corev1.AnalyzeMappingRuleImpactResponse{
+			Message: "The proposed change does not modify the mapping rule",
+			Success: true,
+			Status:  commonv1.Status_STATUS_SUCCESS,
+			Impact:  impact,
+		}, nil
+	}
+
+	var warnings []string
+
+	// Every mapping using the rule is invalidated by the change, and so are the relationships
+	// running them and the MCP resources and tools serving them
+	mappings, err := mappingService.GetMappingsForRule(ctx, req.TenantId, workspaceID, rule.Name)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mappings for rule: %v", err)
+	}
+	var exposed []*corev1.MappingRuleImpactTarget
+	for _, m := range mappings {
+		ruleCount, err := mappingService.GetMappingRuleCount(ctx, m.ID)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to count the rules of mapping %s: %v", m.Name, err))
+		}
+		impact.AffectedMappings = append(impact.AffectedMappings, &corev1.MappingRuleImpactMapping{
+			MappingName:        m.Name,
+			MappingDescription: m.Description,
+			MappingRuleCount:   ruleCount,
+		})
+
+		relationships, err := mappingService.GetRelationshipInfosByMappingID(ctx, m.ID)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to get the relationships of mapping %s: %v", m.Name, err))
+		}
+		for _, relationship := range relationships {
+			impact.Runs = append(impact.Runs, &corev1.MappingRuleImpactRun{
+				RelationshipName: relationship.Name,
+				MappingName:      m.Name,
+				Status:           relationship.Status,
+				Active:           relationship.Status == "STATUS_ACTIVE",
+			})
+		}
+
+		resources, err := mappingService.GetMCPResourceNamesByMappingID(ctx, m.ID)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to get the MCP resources of mapping %s: %v", m.Name, err))
+		}
+		for _, name := range resources {
+			exposed = append(exposed, &corev1.MappingRuleImpactTarget{Kind: "mcp_resource", Name: name, Via: m.Name, Depth: 1})
+		}
+		tools, err := mappingService.GetMCPToolNamesByMappingID(ctx, m.ID)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to get the MCP tools of mapping %s: %v", m.Name, err))
+		}
+		for _, name := range tools {
+			exposed = append(exposed, &corev1.MappingRuleImpactTarget{Kind: "mcp_tool", Name: name, Via: m.Name, Depth: 1})
+		}
+	}
+
+	// Follow the data from the targets of the rule through the rules reading them
+	rules, err := mappingService.ListMappingRules(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list mapping rules: %v", err)
+	}
+	impact.DownstreamTargets = append(ruleLineage(rule, rules), exposed...)
+
+	// Deleting a rule leaves no data for a policy to check
+	if !req.Delete {
+		failing, err := s.failingPolicies(ctx, req.TenantId, rule.Metadata, proposed)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		impact.FailingPolicies = failing
+	}
+
+	return &corev1.AnalyzeMappingRuleImpactResponse{
+		Message:  fmt.Sprintf("Analyzed the impact on %d mappings, %d relationships and %d downstream targets", len(impact.AffectedMappings), len(impact.Runs), len(impact.DownstreamTargets)),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+		Impact:   impact,
+		Warnings: warnings,
+	}, nil
+}
+
+// proposedRuleMetadata returns the metadata of a mapping rule after the proposed change, and the
+// fields the change modifies, the way ModifyMappingRule applies it
+func proposedRuleMetadata(current map[string]interface{}, req *corev1.AnalyzeMappingRuleImpactRequest) (map[string]interface{}, []string, error) {
+	proposed := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		proposed[k] = v
+	}
+	if req.Delete {
+		return proposed, nil, nil
+	}
+
+	var changed []string
+	set := func(field, key string, value interface{}) {
+		if fmt.Sprint(proposed[key]) != fmt.Sprint(value) {
+			changed = append(changed, field)
+		}
+		proposed[key] = value
+	}
+
+	if req.MappingRuleNameNew != nil && *req.MappingRuleNameNew != req.MappingRuleName {
+		changed = append(changed, "name")
+	}
+	if req.MappingRuleSource != nil {
+		set("source", "source_resource_uri", *req.MappingRuleSource)
+	}
+	if req.MappingRuleTarget != nil {
+		set("target", "target_resource_uri", *req.MappingRuleTarget)
+	}
+	if req.MappingRuleTransformationName != nil {
+		set("transformation", "transformation_name", *req.MappingRuleTransformationName)
+	}
+	if req.MappingRuleTransformationOptions != nil {
+		var options map[string]interface{}
+		if err := json.Unmarshal([]byte(*req.MappingRuleTransformationOptions), &options); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal transformation options: %w", err)
+		}
+		set("transformation_options", "transformation_options", options)
+	}
+	if req.MappingRuleMetadata != nil {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(*req.MappingRuleMetadata), &metadata); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+		modified := false
+		for k, v := range metadata {
+			if fmt.Sprint(proposed[k]) != fmt.Sprint(v) {
+				modified = true
+			}
+			proposed[k] = v
+		}
+		if modified {
+			changed = append(changed, "metadata")
+		}
+	}
+	return proposed, changed, nil
+}
+
+// failingPolicies returns the transformation policies that the rule satisfies now but would not
+// satisfy with the proposed metadata
+func (s *Server) failingPolicies(ctx context.Context, tenantID string, current, proposed map[string]interface{}) ([]*corev1.MappingRuleImpactPolicy, error) {
+	policies, err := s.loadTransformationPolicies(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := policies.enforce(ctx, ruleURIs(current, "source"), ruleURIs(current, "target"),
+		ruleTransformation(current), nil, nil); err != nil {
+		// Already failing, so the change does not make it fail
+		return nil, nil
+	}
+
+	required, err := policies.required(ctx, ruleURIs(proposed, "source"), ruleURIs(proposed, "target"))
+	if err != nil {
+		// Policies requiring different transformations of the rule cannot all be satisfied
+		return []*corev1.MappingRuleImpactPolicy{{Reason: err.Error()}}, nil
+	}
+	if required == nil {
+		return nil, nil
+	}
+	if _, _, err := required.Enforce(ruleTransformation(proposed), nil); err != nil {
+		return []*corev1.MappingRuleImpactPolicy{{
+			PolicyId:   required.PolicyID,
+			PolicyName: required.PolicyName,
+			Reason:     err.Error(),
+		}}, nil
+	}
+	return nil, nil
+}
+
+// ruleTransformation returns the transformation in the metadata of a mapping rule
+func ruleTransformation(metadata map[string]interface{}) string {
+	transformation, _ := metadata["transformation_name"].(string)
+	return transformation
+}
+
+// ruleLineage returns the resources that receive the data of a rule: its targets, then the targets
+// of the rules reading them, and so on
+func ruleLineage(rule *mapping.Rule, rules []*mapping.Rule) []*corev1.MappingRuleImpactTarget {
+	var lineage []*corev1.MappingRuleImpactTarget
+	seen := map[string]bool{}
+	visited := map[string]bool{rule.ID: true}
+
+	var frontier []string
+	for _, uri := range ruleURIs(rule.Metadata, "target") {
+		seen[uri] = true
+		frontier = append(frontier, uri)
+		lineage = append(lineage, &corev1.MappingRuleImpactTarget{Kind: "target", Name: uri, Via: rule.Name, Depth: 1})
+	}
+
+	for depth := int32(2); len(frontier) > 0; depth++ {
+		var next []string
+		for _, downstream := range rules {
+			if visited[downstream.ID] || !readsAny(ruleURIs(downstream.Metadata, "source"), frontier) {
+				continue
+			}
+			visited[downstream.ID] = true
+			for _, uri := range ruleURIs(downstream.Metadata, "target") {
+				if seen[uri] {
+					continue
+				}
+				seen[uri] = true
+				next = append(next, uri)
+				lineage = append(lineage, &corev1.MappingRuleImpactTarget{Kind: "rule_target", Name: uri, Via: downstream.Name, Depth: depth})
+			}
+		}
+		frontier = next
+	}
+	return lineage
+}
+
+// readsAny reports whether a rule reading the source URIs reads data written to any of the
+// target URIs. A table URI covers the URIs of its columns, and the other way round.
+func readsAny(sources, targets []string) bool {
+	for _, source := range sources {
+		for _, target := range targets {
+			if source == target || strings.HasPrefix(source, target+"/") || strings.HasPrefix(target, source+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
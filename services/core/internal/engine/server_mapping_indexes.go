@@ -0,0 +1,392 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/spiffe"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultIndexNameLength keeps generated index names within the PostgreSQL identifier limit
+// on databases whose identifier length is not known
+const defaultIndexNameLength = 63
+
+// RecommendMappingIndexes analyzes the lookups a mapping runs against its source and
+// target tables and recommends the indexes that are missing to support them:
+//   - target rows are looked up by the columns mapped from the source primary key when
+//     CDC updates and deletes are applied and when rows are upserted
+//   - source rows are filtered by the columns of column_condition, range and null_check filters
+//
+// Recommended indexes listed in create_index_names are created through the anchor.
+func (s *Server) RecommendMappingIndexes(ctx context.Context, req *corev1.RecommendMappingIndexesRequest) (*corev1.RecommendMappingIndexesResponse, error) {
+	defer s.trackOperation()()
+
+	if req.TenantId == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.WorkspaceName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "workspace_name is required")
+	}
+	if req.MappingName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "mapping_name is required")
+	}
+
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+	workspaceID, err := workspaceService.GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.GetByName(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMappingByID(ctx, req.TenantId, workspaceID, mappingObj.ID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	var warnings []string
+	recommender := &indexRecommender{
+		databaseService: database.NewService(s.engine.db, s.engine.logger),
+		databases:       make(map[string]*database.Database),
+		seen:            make(map[string]bool),
+	}
+
+	// Target key lookups
+	keys, keyWarnings := s.collectTargetKeyColumns(ctx, mappingService, rules)
+	warnings = append(warnings, keyWarnings...)
+	for _, k := range keys {
+		if k.covered() {
+			continue
+		}
+		if err := recommender.add(ctx, "target", k.databaseID, k.tableName, k.columns(), true,
+			"CDC updates and deletes and upserts look up target rows by the columns mapped from the source primary key"); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	// Source filter predicates
+	filterWarnings := s.recommendSourceFilterIndexes(ctx, mappingService, mappingObj, recommender)
+	warnings = append(warnings, filterWarnings...)
+
+	recommendations := recommender.recommendations
+
+	// Create the approved indexes
+	if len(req.CreateIndexNames) > 0 {
+		byName := make(map[string]*corev1.MappingIndexRecommendation, len(recommendations))
+		for _, r := range recommendations {
+			byName[r.IndexName] = r
+		}
+		var unknown []string
+		for _, name := range req.CreateIndexNames {
+			if _, ok := byName[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "not a recommended index of mapping %s: %s", req.MappingName, strings.Join(unknown, ", "))
+		}
+
+		if err := s.createRecommendedIndexes(ctx, req.TenantId, workspaceID, req.CreateIndexNames, byName); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	s.engine.logger.Infof("Recommended %d indexes for mapping '%s'", len(recommendations), req.MappingName)
+
+	return &corev1.RecommendMappingIndexesResponse{
+		Message:         fmt.Sprintf("%d index recommendations for mapping %s", len(recommendations), req.MappingName),
+		Success:         true,
+		Status:          commonv1.Status_STATUS_SUCCESS,
+		MappingName:     req.MappingName,
+		Recommendations: recommendations,
+		Warnings:        warnings,
+	}, nil
+}
+
+// targetKey holds the target columns of one table that are mapped from source primary key columns
+type targetKey struct {
+	databaseID string
+	tableName  string
+	items      map[string]*mapping.ResourceItem
+}
+
+func (k *targetKey) columns() []string {
+	cols := make([]string, 0, len(k.items))
+	for name := range k.items {
+		cols = append(cols, name)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// covered reports whether an existing index already serves the key lookup: a single
+// indexed column, or a key that is entirely part of the primary key
+func (k *targetKey) covered() bool {
+	if len(k.items) == 1 {
+		for _, item := range k.items {
+			return item.IsPrimaryKey || item.IsUnique || item.IsIndexed
+		}
+	}
+	for _, item := range k.items {
+		if !item.IsPrimaryKey {
+			return false
+		}
+	}
+	return true
+}
+
+// collectTargetKeyColumns finds, per target table, the columns mapped from source primary key columns
+func (s *Server) collectTargetKeyColumns(ctx context.Context, mappingService *mapping.Service, rules []*mapping.Rule) ([]*targetKey, []string) {
+	var warnings []string
+	keys := make(map[string]*targetKey)
+	var order []string
+
+	for _, rule := range rules {
+		sourceURI, _ := rule.Metadata["source_resource_uri"].(string)
+		targetURI, _ := rule.Metadata["target_resource_uri"].(string)
+		if sourceURI == "" || targetURI == "" {
+			continue
+		}
+
+		// Only database columns take part in key lookups
+		sourceInfo, err := s.parseResourceIdentifier(sourceURI)
+		if err != nil || sourceInfo.ColumnName == "" {
+			continue
+		}
+		targetInfo, err := s.parseResourceIdentifier(targetURI)
+		if err != nil || targetInfo.ColumnName == "" {
+			continue
+		}
+
+		sourceItem, err := mappingService.GetItemByURI(ctx, sourceURI)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("rule %s: could not resolve source column: %v", rule.Name, err))
+			continue
+		}
+		if !sourceItem.IsPrimaryKey {
+			continue
+		}
+		targetItem, err := mappingService.GetItemByURI(ctx, targetURI)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("rule %s: could not resolve target column: %v", rule.Name, err))
+			continue
+		}
+
+		tableKey := targetInfo.DatabaseID + "." + targetInfo.TableName
+		k, ok := keys[tableKey]
+		if !ok {
+			k = &targetKey{
+				databaseID: targetInfo.DatabaseID,
+				tableName:  targetInfo.TableName,
+				items:      make(map[string]*mapping.ResourceItem),
+			}
+			keys[tableKey] = k
+			order = append(order, tableKey)
+		}
+		k.items[targetInfo.ColumnName] = targetItem
+	}
+
+	result := make([]*targetKey, 0, len(order))
+	for _, key := range order {
+		result = append(result, keys[key])
+	}
+	return result, warnings
+}
+
+// recommendSourceFilterIndexes recommends an index for every unindexed source column a mapping filter tests
+func (s *Server) recommendSourceFilterIndexes(ctx context.Context, mappingService *mapping.Service, mappingObj *mapping.Mapping, recommender *indexRecommender) []string {
+	if mappingObj.SourceIdentifier == "" || mapping.IsStreamURI(mappingObj.SourceIdentifier) {
+		return nil
+	}
+
+	filters, err := mappingService.GetMappingFilters(ctx, mappingObj.ID)
+	if err != nil {
+		return []string{fmt.Sprintf("could not get mapping filters: %v", err)}
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+
+	container, err := mappingService.GetContainerByURI(ctx, mappingObj.SourceIdentifier)
+	if err != nil || container.DatabaseID == nil {
+		return []string{"could not resolve the source table of the mapping filters"}
+	}
+	items, err := mappingService.GetItemsForContainer(ctx, container.ContainerID)
+	if err != nil {
+		return []string{fmt.Sprintf("could not get source columns: %v", err)}
+	}
+	itemsByName := make(map[string]*mapping.ResourceItem, len(items))
+	for _, item := range items {
+		itemsByName[item.ItemName] = item
+	}
+
+	var warnings []string
+	for _, f := range filters {
+		if f.FilterType == "where_clause" || f.FilterType == "json_path" {
+			warnings = append(warnings, fmt.Sprintf("filter %s of type %s is not analyzed for index recommendations", f.FilterID, f.FilterType))
+			continue
+		}
+
+		column, _ := f.FilterExpression["column"].(string)
+		if column == "" {
+			column, _ = f.FilterExpression["field"].(string)
+		}
+		item, ok := itemsByName[column]
+		if column == "" || !ok {
+			warnings = append(warnings, fmt.Sprintf("filter %s does not reference a known source column", f.FilterID))
+			continue
+		}
+		if item.IsPrimaryKey || item.IsUnique || item.IsIndexed {
+			continue
+		}
+
+		reason := fmt.Sprintf("the mapping %s filter reads source rows by %s", f.FilterType, column)
+		if err := recommender.add(ctx, "source", *container.DatabaseID, container.ObjectName, []string{column}, false, reason); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return warnings
+}
+
+// createRecommendedIndexes runs the statements of the approved recommendations through the anchor
+func (s *Server) createRecommendedIndexes(ctx context.Context, tenantID, workspaceID string, names []string, byName map[string]*corev1.MappingIndexRecommendation) error {
+	anchorAddr := s.engine.getServiceAddress("anchor")
+	anchorConn, err := grpc.Dial(anchorAddr, spiffe.DialOption())
+	if err != nil {
+		return fmt.Errorf("failed to connect to anchor service at %s: %v", anchorAddr, err)
+	}
+	defer anchorConn.Close()
+
+	anchorClient := anchorv1.NewAnchorServiceClient(anchorConn)
+	for _, name := range names {
+		r := byName[name]
+		if r.Statement == "" {
+			r.Error = fmt.Sprintf("creating indexes is not supported for %s databases", r.DatabaseType)
+			continue
+		}
+
+		resp, err := anchorClient.ExecuteCommand(ctx, &anchorv1.ExecuteCommandRequest{
+			TenantId:    tenantID,
+			WorkspaceId: workspaceID,
+			DatabaseId:  r.DatabaseId,
+			Command:     r.Statement,
+		})
+		switch {
+		case err != nil:
+			r.Error = err.Error()
+		case !resp.Success:
+			r.Error = resp.Message
+		default:
+			r.Created = true
+			s.engine.logger.Infof("Created index %s on %s.%s", r.IndexName, r.DatabaseName, r.TableName)
+		}
+	}
+	return nil
+}
+
+// indexRecommender collects recommendations, skipping duplicates
+type indexRecommender struct {
+	databaseService *database.Service
+	databases       map[string]*database.Database
+	seen            map[string]bool
+	recommendations []*corev1.MappingIndexRecommendation
+}
+
+func (r *indexRecommender) add(ctx context.Context, side, databaseID, tableName string, columns []string, unique bool, reason string) error {
+	key := databaseID + "." + tableName + "(" + strings.Join(columns, ",") + ")"
+	if r.seen[key] {
+		return nil
+	}
+	r.seen[key] = true
+
+	db, ok := r.databases[databaseID]
+	if !ok {
+		var err error
+		db, err = r.databaseService.GetByID(ctx, databaseID)
+		if err != nil {
+			return fmt.Errorf("could not get database %s: %v", databaseID, err)
+		}
+		r.databases[databaseID] = db
+	}
+
+	name := indexName(db.Type, tableName, columns)
+	r.recommendations = append(r.recommendations, &corev1.MappingIndexRecommendation{
+		IndexName:    name,
+		Side:         side,
+		DatabaseId:   databaseID,
+		DatabaseName: db.Name,
+		DatabaseType: db.Type,
+		TableName:    tableName,
+		Columns:      columns,
+		Unique:       unique,
+		Reason:       reason,
+		Statement:    createIndexStatement(db.Type, name, tableName, columns, unique),
+	})
+	return nil
+}
+
+// indexName builds the name of a recommended index, idx_<table>_<columns>, within the
+// identifier length of the database type
+func indexName(dbType, tableName string, columns []string) string {
+	name := "idx_" + tableName + "_" + strings.Join(columns, "_")
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	limit := dbcapabilities.MaxIdentifierLength(dbcapabilities.DatabaseType(dbType))
+	if limit == 0 {
+		limit = defaultIndexNameLength
+	}
+	if len(name) > limit {
+		name = name[:limit]
+	}
+	return name
+}
+
+// createIndexStatement returns the DDL creating an index, or "" for database types
+// whose indexes are not created through SQL
+func createIndexStatement(dbType, name, tableName string, columns []string, unique bool) string {
+	id := dbcapabilities.DatabaseType(dbType)
+	switch id {
+	case dbcapabilities.PostgreSQL, dbcapabilities.CockroachDB, dbcapabilities.MySQL, dbcapabilities.MariaDB, dbcapabilities.SQLServer:
+	default:
+		return ""
+	}
+	quote := func(s string) string { return dbcapabilities.QuoteIdentifier(id, s) }
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quote(c)
+	}
+	kind := "INDEX"
+	if unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", kind, quote(name), quote(tableName), strings.Join(quoted, ", "))
+}
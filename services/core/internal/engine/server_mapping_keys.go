@@ -0,0 +1,37 @@
+package engine
+
+import (
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+)
+
+// keyColumnMetadata returns, by source column, the composite keys of a table match the column is
+// part of, as stored in the metadata of the generated rules under "key_matches". Together, the
+// rules of the columns of a key map the key of the source table to that of the target table:
+// primary and unique keys identify the same rows one-to-one, and foreign keys join many rows to
+// one row of the matched referenced tables.
+func keyColumnMetadata(tableMatch *unifiedmodelv1.EnrichedTableMatch) map[string][]map[string]interface{} {
+	keys := make(map[string][]map[string]interface{})
+	for _, keyMatch := range tableMatch.KeyMatches {
+		for position, sourceColumn := range keyMatch.SourceColumns {
+			key := map[string]interface{}{
+				"key_type":           keyMatch.KeyType,
+				"source_key":         keyMatch.SourceKey,
+				"target_key":         keyMatch.TargetKey,
+				"target_key_type":    keyMatch.TargetKeyType,
+				"key_position":       position,
+				"source_key_columns": keyMatch.SourceColumns,
+				"target_key_columns": keyMatch.TargetColumns,
+				"cardinality":        keyMatch.Cardinality,
+				"key_match_score":    keyMatch.Score,
+			}
+			if keyMatch.ReferencedSourceTable != "" {
+				key["referenced_source_table"] = keyMatch.ReferencedSourceTable
+				key["referenced_target_table"] = keyMatch.ReferencedTargetTable
+				key["referenced_source_columns"] = keyMatch.ReferencedSourceColumns
+				key["referenced_target_columns"] = keyMatch.ReferencedTargetColumns
+			}
+			keys[sourceColumn] = append(keys[sourceColumn], key)
+		}
+	}
+	return keys
+}
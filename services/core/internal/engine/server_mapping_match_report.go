@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetMappingMatchReport returns the match report of the last run of the matcher generating the
+// rules of a mapping: the scores of its matches, and why the columns it did not map got no rule
+func (s *Server) GetMappingMatchReport(ctx context.Context, req *corev1.GetMappingMatchReportRequest) (*corev1.GetMappingMatchReportResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	report, err := mappingService.GetMatchReport(ctx, mappingObj.ID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		if errors.Is(err, mapping.ErrMatchReportNotFound) {
+			return nil, status.Errorf(codes.NotFound, "mapping %s has no match report; its rules were not generated by the matcher", req.MappingName)
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &corev1.GetMappingMatchReportResponse{
+		MatchReport: matchReportToProto(mappingObj.Name, report),
+		Message:     fmt.Sprintf("Match report of mapping %s: %d rules created", mappingObj.Name, report.RulesCreated()),
+		Success:     true,
+		Status:      commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// applyMatchOverrides returns the match profile with the options a request overrides, and the
+// names of the options overridden
+func applyMatchOverrides(profile *mapping.MatchProfile, overrides *corev1.MatchOverrides) (*mapping.MatchProfile, []string, error) {
+	if overrides == nil {
+		return profile, nil, nil
+	}
+	options, overridden := mapping.MatchOverrides{
+		NameSimilarityThreshold:  overrides.NameSimilarityThreshold,
+		PoorMatchThreshold:       overrides.PoorMatchThreshold,
+		NameWeight:               overrides.NameWeight,
+		TypeWeight:               overrides.TypeWeight,
+		ClassificationWeight:     overrides.ClassificationWeight,
+		PrivilegedDataWeight:     overrides.PrivilegedDataWeight,
+		TableStructureWeight:     overrides.TableStructureWeight,
+		EnableCrossTableMatching: overrides.EnableCrossTableMatching,
+		MinRuleScore:             overrides.MinRuleScore,
+	}.Apply(profile.Options)
+	if err := options.Validate(); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid match overrides: %v", err)
+	}
+
+	overriddenProfile := *profile
+	overriddenProfile.Options = options
+	return &overriddenProfile, overridden, nil
+}
+
+// newMatchReport starts the match report of a run of the matcher from its response. The column
+// matches get their outcomes as their rules are created or skipped.
+func newMatchReport(profile *mapping.MatchProfile, overridden []string, matchResp *unifiedmodelv1.MatchUnifiedModelsEnrichedResponse) *mapping.MatchReport {
+	report := &mapping.MatchReport{
+		MatchProfile: profile.Name,
+		Options:      profile.Options,
+		Overridden:   overridden,
+		GeneratedAt:  time.Now().UTC(),
+		OverallScore: matchResp.OverallSimilarityScore,
+		Warnings:     matchResp.Warnings,
+	}
+	for _, tableMatch := range matchResp.TableMatches {
+		table := mapping.MatchReportTable{
+			SourceTable:        tableMatch.SourceTable,
+			TargetTable:        tableMatch.TargetTable,
+			Score:              tableMatch.Score,
+			PoorMatch:          tableMatch.IsPoorMatch,
+			Pinned:             tableMatch.Pinned,
+			MatchedColumns:     tableMatch.MatchedColumns,
+			TotalSourceColumns: tableMatch.TotalSourceColumns,
+			TotalTargetColumns: tableMatch.TotalTargetColumns,
+			Reasons:            tableMatch.Reasons,
+		}
+		for _, columnMatch := range tableMatch.ColumnMatches {
+			table.Columns = append(table.Columns, matchReportColumn(tableMatch.SourceTable, tableMatch.TargetTable, columnMatch))
+		}
+		report.Tables = append(report.Tables, table)
+	}
+	for _, columnMatch := range matchResp.UnmatchedColumns {
+		column := matchReportColumn(columnMatch.SourceTable, columnMatch.TargetTable, columnMatch)
+		column.Outcome = mapping.MatchOutcomeUnmatched
+		report.UnmatchedColumns = append(report.UnmatchedColumns, column)
+	}
+	return report
+}
+
+func matchReportColumn(sourceTable, targetTable string, columnMatch *unifiedmodelv1.EnrichedColumnMatch) mapping.MatchReportColumn {
+	return mapping.MatchReportColumn{
+		SourceTable:    sourceTable,
+		SourceColumn:   columnMatch.SourceColumn,
+		TargetTable:    targetTable,
+		TargetColumn:   columnMatch.TargetColumn,
+		Score:          columnMatch.Score,
+		TypeCompatible: columnMatch.IsTypeCompatible,
+		Reasons:        columnMatch.Reasons,
+	}
+}
+
+// storeMatchReport completes the match report of a mapping and stores it, replacing the report
+// of an earlier match, and returns it as a protobuf message. A report that cannot be stored is
+// still returned.
+func (s *Server) storeMatchReport(ctx context.Context, mappingService *mapping.Service, mappingID, mappingName string, report *mapping.MatchReport) *corev1.MatchReport {
+	report.Finish()
+	if err := mappingService.SetMatchReport(ctx, mappingID, report); err != nil {
+		s.engine.logger.Warnf("Failed to store the match report of mapping %s: %v", mappingName, err)
+	}
+	s.engine.logger.Infof("Matcher of mapping %s created %d rules; outcomes of the column matches: %v", mappingName, report.RulesCreated(), report.Outcomes)
+	return matchReportToProto(mappingName, report)
+}
+
+func matchReportToProto(mappingName string, report *mapping.MatchReport) *corev1.MatchReport {
+	options := matchProfileToProto(&mapping.MatchProfile{Name: report.MatchProfile, Options: report.Options})
+	protoReport := &corev1.MatchReport{
+		MappingName:  mappingName,
+		Options:      options,
+		Overridden:   report.Overridden,
+		GeneratedAt:  report.GeneratedAt.Format(time.RFC3339),
+		OverallScore: report.OverallScore,
+		Warnings:     report.Warnings,
+		Outcomes:     make(map[string]int32, len(report.Outcomes)),
+		RulesCreated: int32(report.RulesCreated()),
+	}
+	for outcome, count := range report.Outcomes {
+		protoReport.Outcomes[outcome] = int32(count)
+	}
+	for _, table := range report.Tables {
+		protoTable := &corev1.MatchReportTable{
+			SourceTable:        table.SourceTable,
+			TargetTable:        table.TargetTable,
+			Score:              table.Score,
+			PoorMatch:          table.PoorMatch,
+			Pinned:             table.Pinned,
+			MatchedColumns:     table.MatchedColumns,
+			TotalSourceColumns: table.TotalSourceColumns,
+			TotalTargetColumns: table.TotalTargetColumns,
+			Reasons:            table.Reasons,
+		}
+		for _, column := range table.Columns {
+			protoTable.Columns = append(protoTable.Columns, matchReportColumnToProto(column))
+		}
+		protoReport.Tables = append(protoReport.Tables, protoTable)
+	}
+	for _, column := range report.UnmatchedColumns {
+		protoReport.UnmatchedColumns = append(protoReport.UnmatchedColumns, matchReportColumnToProto(column))
+	}
+	return protoReport
+}
+
+func matchReportColumnToProto(column mapping.MatchReportColumn) *corev1.MatchReportColumn {
+	return &corev1.MatchReportColumn{
+		SourceTable:    column.SourceTable,
+		SourceColumn:   column.SourceColumn,
+		TargetTable:    column.TargetTable,
+		TargetColumn:   column.TargetColumn,
+		Score:          column.Score,
+		TypeCompatible: column.TypeCompatible,
+		Reasons:        column.Reasons,
+		Outcome:        column.Outcome,
+		RuleName:       column.RuleName,
+		Detail:         column.Detail,
+	}
+}
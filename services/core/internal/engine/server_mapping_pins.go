@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RematchMapping re-runs the matcher of a database mapping with its table pins, and replaces the
+// rules the matcher generated before. Rules users added or kept from other matches stay, and
+// no rule is generated for the target columns they map.
+func (s *Server) RematchMapping(ctx context.Context, req *corev1.RematchMappingRequest) (*corev1.RematchMappingResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+	if mappingObj.SourceType != "database" || mappingObj.TargetType != "database" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "mapping %s is not a database-to-database mapping", req.MappingName)
+	}
+
+	// Resolve the databases of the mapping
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	sourceDBObj, err := databaseService.GetByID(ctx, getString(mappingObj.MappingObject, "source_database_id"))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "source database not found: %v", err)
+	}
+	targetDBObj, err := databaseService.GetByID(ctx, getString(mappingObj.MappingObject, "target_database_id"))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "target database not found: %v", err)
+	}
+
+	policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), "database")
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	profile, overridden, err := applyMatchOverrides(profile, req.MatchOverrides)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	umClient := s.engine.GetUnifiedModelClient()
+	if umClient == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "unified model service not available")
+	}
+
+	// Match before removing any rule, so that a failed match leaves the mapping as it was
+	match := &databaseMappingMatch{
+		tenantID:    req.TenantId,
+		workspaceID: workspaceID,
+		mappingName: mappingObj.Name,
+		ownerID:     mappingObj.OwnerID,
+		sourceDB:    sourceDBObj,
+		targetDB:    targetDBObj,
+		profile:     profile,
+		tablePins:   mappingObj.TablePins(),
+	}
+	matchResp, err := s.matchDatabaseMapping(ctx, umClient, mappingService, match)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to match the databases of the mapping: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMappingByID(ctx, req.TenantId, workspaceID, mappingObj.ID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	// Remove the rules the matcher generated; the others are kept
+	removed := 0
+	keptTargets := make(map[string]bool)
+	for _, rule := range rules {
+		if matchType, _ := rule.Metadata["match_type"].(string); matchType != "enriched_match" {
+			for _, uri := range ruleTargetURIs(rule) {
+				if table, column, ok := mapping.ColumnOfURI(uri); ok {
+					keptTargets[table+"."+column] = true
+				}
+			}
+			continue
+		}
+		if err := mappingService.DetachMappingRule(ctx, req.TenantId, workspaceID, mappingObj.Name, rule.Name); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to remove generated rule %s: %v", rule.Name, err)
+		}
+		removed++
+		// A rule still attached to other mappings is only detached from this one
+		if err := mappingService.DeleteMappingRule(ctx, req.TenantId, workspaceID, rule.Name); err != nil {
+			s.engine.logger.Warnf("Kept mapping rule %s detached from mapping %s: %v", rule.Name, mappingObj.Name, err)
+		}
+	}
+
+	report := newMatchReport(profile, overridden, matchResp)
+	added := s.createDatabaseMappingRules(ctx, mappingService, policies, match, matchResp, keptTargets, report)
+	matchReport := s.storeMatchReport(ctx, mappingService, mappingObj.ID, mappingObj.Name, report)
+
+	// The rules changed, the mapping must be validated again
+	if err := mappingService.InvalidateMapping(ctx, mappingObj.ID); err != nil {
+		s.engine.logger.Warnf("Failed to invalidate mapping %s: %v", mappingObj.Name, err)
+	}
+
+	updatedMapping, err := mappingService.Get(ctx, req.TenantId, workspaceID, mappingObj.Name)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping: %v", err)
+	}
+	protoMapping, err := s.mappingToProto(updatedMapping)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to convert mapping: %v", err)
+	}
+
+	return &corev1.RematchMappingResponse{
+		Message:          fmt.Sprintf("Replaced %d generated rules with %d rules matched with %d table pins", removed, added, len(match.tablePins)),
+		Success:          true,
+		Status:           commonv1.Status_STATUS_SUCCESS,
+		Mapping:          protoMapping,
+		RemovedRuleCount: int32(removed),
+		AddedRuleCount:   int32(added),
+		MatchReport:      matchReport,
+		Warnings:         matchResp.Warnings,
+	}, nil
+}
+
+// ruleTargetURIs returns the target resource URIs of a mapping rule
+func ruleTargetURIs(rule *mapping.Rule) []string {
+	var uris []string
+	if uri := getString(rule.Metadata, "target_resource_uri"); uri != "" {
+		uris = append(uris, uri)
+	}
+	if targets, ok := rule.Metadata["target_uris"].([]interface{}); ok {
+		for _, target := range targets {
+			if uri, ok := target.(string); ok && uri != "" {
+				uris = append(uris, uri)
+			}
+		}
+	}
+	return uris
+}
+
+// tablePinsFromProto converts and validates the table pins of a request
+func tablePinsFromProto(protoPins []*corev1.MappingTablePin) ([]mapping.TablePin, error) {
+	pins := make([]mapping.TablePin, 0, len(protoPins))
+	for _, pin := range protoPins {
+		pins = append(pins, mapping.TablePin{
+			SourceTable: pin.SourceTable,
+			TargetTable: pin.TargetTable,
+			Never:       pin.Never,
+		})
+	}
+	if err := mapping.ValidateTablePins(pins); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid table pins: %v", err)
+	}
+	return pins, nil
+}
+
+// tablePinsToProto converts the table pins of a mapping to protobuf
+func tablePinsToProto(pins []mapping.TablePin) []*corev1.MappingTablePin {
+	protoPins := make([]*corev1.MappingTablePin, len(pins))
+	for i, pin := range pins {
+		protoPins[i] = &corev1.MappingTablePin{
+			SourceTable: pin.SourceTable,
+			TargetTable: pin.TargetTable,
+			Never:       pin.Never,
+		}
+	}
+	return protoPins
+}
+
+// tablePinsToMatchOptions converts the table pins of a mapping to those of the matcher
+func tablePinsToMatchOptions(pins []mapping.TablePin) []*unifiedmodelv1.TablePin {
+	matchPins := make([]*unifiedmodelv1.TablePin, len(pins))
+	for i, pin := range pins {
+		matchPins[i] = &unifiedmodelv1.TablePin{
+			SourceTable: pin.SourceTable,
+			TargetTable: pin.TargetTable,
+			Never:       pin.Never,
+		}
+	}
+	return matchPins
+}
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PlanRun returns the execution plan of a copy of a mapping without running it: the stages the
+// copy would run as a DAG, with the table pairs they copy, the adapters reading and writing
+// them, the rows and batches estimated from the source row counts, and the nodes of the mesh
+// the rows pass through. Warnings report what would make the copy fail.
+func (s *Server) PlanRun(ctx context.Context, req *corev1.PlanRunRequest) (*corev1.PlanRunResponse, error) {
+	defer s.trackOperation()()
+
+	if req.TenantId == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.WorkspaceName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "workspace_name is required")
+	}
+	if req.MappingName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "mapping_name is required")
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMapping(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	// The options default as in CopyMappingData
+	batchSize := int32(1000)
+	if req.BatchSize != nil && *req.BatchSize > 0 {
+		batchSize = *req.BatchSize
+	}
+	parallelWorkers := int32(4)
+	if req.ParallelWorkers != nil && *req.ParallelWorkers > 0 {
+		parallelWorkers = *req.ParallelWorkers
+	}
+	validateVectors := req.ValidateVectors == nil || *req.ValidateVectors
+	verifyGraph := req.VerifyGraph == nil || *req.VerifyGraph
+
+	var warnings []string
+	rules, brokenRules := mapping.RunnableRules(rules)
+	skippedRules := make([]string, len(brokenRules))
+	for i, rule := range brokenRules {
+		skippedRules[i] = rule.Name
+		warnings = append(warnings, fmt.Sprintf("broken mapping rule %s is skipped: %s", rule.Name, rule.Breakage().Reason))
+	}
+	if len(rules) == 0 {
+		warnings = append(warnings, "the mapping has no rules to copy")
+	}
+	if _, err := mapping.NewRowFilters(mappingObj.RowFilter(), rules); err != nil {
+		warnings = append(warnings, err.Error())
+	}
+
+	nodeID, err := s.getLocalNodeID(ctx)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get the local node: %v", err)
+	}
+	routes, nodeNames, err := s.meshRoutes(ctx)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Source rows are counted through the anchor, as the copy does for its progress
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to connect to anchor service: %v", err))
+	}
+
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	databases := make(map[string]*database.Database)
+	getDatabase := func(id string) *database.Database {
+		db, ok := databases[id]
+		if !ok {
+			db, err = databaseService.GetByID(ctx, id)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("database %s not found: %v", id, err))
+				db = nil
+			}
+			databases[id] = db
+		}
+		return db
+	}
+
+	tablePairs := s.groupMappingRulesByTables(rules)
+	pairs := make([]mapping.PlanTablePair, 0, len(tablePairs))
+	for _, tablePair := range tablePairs {
+		pair := mapping.PlanTablePair{
+			SourceTable:   tablePair.SourceTable,
+			TargetTable:   tablePair.TargetTable,
+			EstimatedRows: -1,
+		}
+		seen := make(map[string]bool)
+		for _, rule := range tablePair.Rules {
+			pair.Rules = append(pair.Rules, rule.Name)
+			transformationName, _ := rule.Metadata["transformation_name"].(string)
+			if transformationName != "" && transformationName != "direct_mapping" && !seen[transformationName] {
+				seen[transformationName] = true
+				pair.Transformations = append(pair.Transformations, transformationName)
+			}
+		}
+
+		sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		if sourceDB := getDatabase(sourceInfo.DatabaseID); sourceDB != nil {
+			pair.SourceDatabase = sourceDB.Name
+			pair.SourceAdapter = sourceDB.Type
+			pair.SourceNode = sourceDB.ConnectedToNodeID
+			pair.VerifyGraph = verifyGraph && samplesEdges(sourceDB.Type)
+		}
+		if targetDB := getDatabase(targetInfo.DatabaseID); targetDB != nil {
+			pair.TargetDatabase = targetDB.Name
+			pair.TargetAdapter = targetDB.Type
+			pair.TargetNode = targetDB.ConnectedToNodeID
+			pair.ValidateVectors = validateVectors && searchesVectors(targetDB.Type)
+		}
+
+		if anchorClient != nil {
+			countResp, err := anchorClient.GetTableRowCount(ctx, &anchorv1.GetTableRowCountRequest{
+				DatabaseId: sourceInfo.DatabaseID,
+				TableName:  sourceInfo.TableName,
+			})
+			if err != nil {
+				s.engine.logger.Warnf("Failed to get row count for %s: %v", tablePair.SourceTable, err)
+			} else if countResp.Success {
+				pair.EstimatedRows = countResp.RowCount
+			}
+		}
+		pairs = append(pairs, pair)
+	}
+
+	stages, planWarnings := mapping.PlanRun(pairs, mapping.PlanOptions{
+		BatchSize:   batchSize,
+		Parallelism: int32(s.engine.copyParallelismBounds().Clamp(int(parallelWorkers))),
+		Sandbox:     req.Sandbox != nil && *req.Sandbox,
+		Node:        nodeID,
+		Routes:      routes,
+	})
+	warnings = append(warnings, planWarnings...)
+
+	plan := &corev1.RunPlan{
+		MappingName: req.MappingName,
+		NodeId:      nodeID,
+		NodeName:    nodeNames[nodeID],
+		BatchSize:   batchSize,
+		// The copy runs its stages one at a time
+		Parallelism:  1,
+		RowFilter:    mappingObj.RowFilter(),
+		SkippedRules: skippedRules,
+	}
+	adapters := make(map[string]bool)
+	for _, pair := range pairs {
+		if plan.EstimatedRows >= 0 {
+			if pair.EstimatedRows < 0 {
+				plan.EstimatedRows = -1
+			} else {
+				plan.EstimatedRows += pair.EstimatedRows
+			}
+		}
+		for _, adapter := range []string{pair.SourceAdapter, pair.TargetAdapter} {
+			if adapter != "" {
+				adapters[adapter] = true
+			}
+		}
+	}
+	for adapter := range adapters {
+		plan.Adapters = append(plan.Adapters, adapter)
+	}
+	sort.Strings(plan.Adapters)
+
+	for _, stage := range stages {
+		planStage := &corev1.RunPlanStage{
+			StageId:          stage.ID,
+			Kind:             stage.Kind,
+			Description:      stage.Description,
+			DependsOn:        stage.DependsOn,
+			NodeId:           stage.Node,
+			EstimatedRows:    stage.EstimatedRows,
+			EstimatedBatches: stage.EstimatedBatches,
+			Parallelism:      stage.Parallelism,
+		}
+		if stage.Kind == mapping.StageCopyTable {
+			planStage.BatchSize = batchSize
+		}
+		if pair := stage.Pair; pair != nil {
+			planStage.SourceTable = pair.SourceTable
+			planStage.TargetTable = pair.TargetTable
+			planStage.SourceDatabase = pair.SourceDatabase
+			planStage.TargetDatabase = pair.TargetDatabase
+			planStage.SourceAdapter = pair.SourceAdapter
+			planStage.TargetAdapter = pair.TargetAdapter
+			planStage.Rules = pair.Rules
+			planStage.Transformations = pair.Transformations
+		}
+		for _, hop := range stage.MeshHops {
+			planStage.MeshHops = append(planStage.MeshHops, &corev1.MeshHop{NodeId: hop, NodeName: nodeNames[hop]})
+		}
+		plan.Stages = append(plan.Stages, planStage)
+	}
+
+	return &corev1.PlanRunResponse{
+		Message:  fmt.Sprintf("Planned %d stages for %d table pairs of mapping %s", len(plan.Stages), len(pairs), req.MappingName),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+		Plan:     plan,
+		Warnings: warnings,
+	}, nil
+}
+
+// meshRoutes returns the routes of the mesh and the names of its nodes by ID
+func (s *Server) meshRoutes(ctx context.Context) ([]mapping.MeshRoute, map[string]string, error) {
+	nodeNames := make(map[string]string)
+	rows, err := s.engine.db.Pool().Query(ctx, `SELECT node_id, node_name FROM nodes`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the nodes of the mesh: %v", err)
+	}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to scan node: %v", err)
+		}
+		nodeNames[id] = name
+	}
+	rows.Close()
+
+	var routes []mapping.MeshRoute
+	rows, err = s.engine.db.Pool().Query(ctx, `SELECT a_node, b_node FROM routes`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the routes of the mesh: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var route mapping.MeshRoute
+		if err := rows.Scan(&route.ANode, &route.BNode); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan route: %v", err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nodeNames, rows.Err()
+}
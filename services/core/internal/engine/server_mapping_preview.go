@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPreviewSampleSize bounds the rows a preview reads from each source table
+const maxPreviewSampleSize = 100
+
+// PreviewMapping reads sample rows of the source tables of a mapping and transforms them with its
+// rules as a copy would, without writing anything, and returns each source row with the target
+// row it would become
+func (s *Server) PreviewMapping(ctx context.Context, req *corev1.PreviewMappingRequest) (*corev1.PreviewMappingResponse, error) {
+	defer s.trackOperation()()
+
+	if req.TenantId == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id is required")
+	}
+	if req.WorkspaceName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "workspace_name is required")
+	}
+	if req.MappingName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "mapping_name is required")
+	}
+
+	sampleSize := int32(10)
+	if req.SampleSize != nil && *req.SampleSize > 0 {
+		sampleSize = *req.SampleSize
+	}
+	if sampleSize > maxPreviewSampleSize {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "sample_size must be at most %d", maxPreviewSampleSize)
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMapping(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	// Broken rules are left out, as in a copy
+	var warnings []string
+	rules, brokenRules := mapping.RunnableRules(rules)
+	for _, rule := range brokenRules {
+		warnings = append(warnings, fmt.Sprintf("broken mapping rule %s is skipped: %s", rule.Name, rule.Breakage().Reason))
+	}
+	if len(rules) == 0 {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "mapping %s has no rules to preview", req.MappingName)
+	}
+
+	rowFilters, err := mapping.NewRowFilters(mappingObj.RowFilter(), rules)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to anchor service: %v", err)
+	}
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
+	}
+
+	var tables []*corev1.PreviewTableSample
+	for _, tablePair := range s.groupMappingRulesByTables(rules) {
+		sample, err := s.previewTablePair(ctx, anchorClient, transformationClient, tablePair, rowFilters, sampleSize)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to preview %s -> %s: %v", tablePair.SourceTable, tablePair.TargetTable, err))
+			continue
+		}
+		tables = append(tables, sample)
+	}
+
+	return &corev1.PreviewMappingResponse{
+		Message:  fmt.Sprintf("Previewed %d table pairs of mapping %s", len(tables), req.MappingName),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+		Tables:   tables,
+		Warnings: warnings,
+	}, nil
+}
+
+// previewTablePair reads sample rows of the source table of a table pair and transforms them
+// with the rules of the pair. The source rows keep only the columns the rules and row filters read.
+func (s *Server) previewTablePair(ctx context.Context, anchorClient anchorv1.AnchorServiceClient, transformationClient transformationv1.TransformationServiceClient, tablePair TablePair, rowFilters *mapping.RowFilters, sampleSize int32) (*corev1.PreviewTableSample, error) {
+	sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source table: %v", err)
+	}
+
+	options, err := json.Marshal(map[string]interface{}{"limit": sampleSize})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := anchorClient.FetchData(ctx, &anchorv1.FetchDataRequest{
+		DatabaseId: sourceInfo.DatabaseID,
+		TableName:  sourceInfo.TableName,
+		Options:    options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%s", resp.Message)
+	}
+	var sourceRows []map[string]interface{}
+	if err := json.Unmarshal(resp.Data, &sourceRows); err != nil {
+		return nil, fmt.Errorf("failed to parse source data: %v", err)
+	}
+
+	sample := &corev1.PreviewTableSample{
+		SourceTable: tablePair.SourceTable,
+		TargetTable: tablePair.TargetTable,
+	}
+	var readColumns []string
+	for _, rule := range tablePair.Rules {
+		sample.Rules = append(sample.Rules, rule.Name)
+		sourceColumns, _ := mapping.RuleColumns(rule)
+		for _, column := range sourceColumns {
+			if !slices.Contains(readColumns, column) {
+				readColumns = append(readColumns, column)
+			}
+		}
+	}
+	for _, column := range rowFilters.Columns() {
+		if !slices.Contains(readColumns, column) {
+			readColumns = append(readColumns, column)
+		}
+	}
+
+	for _, sourceRow := range sourceRows {
+		read := make(map[string]interface{}, len(readColumns))
+		for _, column := range readColumns {
+			if value, ok := sourceRow[column]; ok {
+				read[column] = value
+			}
+		}
+		row := &corev1.PreviewRow{}
+		if row.SourceRow, err = json.Marshal(read); err != nil {
+			return nil, fmt.Errorf("failed to marshal source row: %v", err)
+		}
+
+		rowRules, written := rowFilters.SelectRules(sourceRow, tablePair.Rules)
+		if !written {
+			row.FilteredOut = true
+			sample.Rows = append(sample.Rows, row)
+			continue
+		}
+		targetRow, failures := s.transformRow(ctx, transformationClient, sourceRow, rowRules)
+		row.Errors = failures
+		if row.TargetRow, err = json.Marshal(targetRow); err != nil {
+			return nil, fmt.Errorf("failed to marshal target row: %v", err)
+		}
+		sample.Rows = append(sample.Rows, row)
+	}
+	return sample, nil
+}
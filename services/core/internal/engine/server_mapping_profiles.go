@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListMatchProfiles lists the built-in match profiles and the match profiles of a workspace
+func (s *Server) ListMatchProfiles(ctx context.Context, req *corev1.ListMatchProfilesRequest) (*corev1.ListMatchProfilesResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	profiles, err := mapping.NewService(s.engine.db, s.engine.logger).ListMatchProfiles(ctx, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list match profiles: %v", err)
+	}
+
+	protoProfiles := make([]*corev1.MatchProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		protoProfiles = append(protoProfiles, matchProfileToProto(profile))
+	}
+	return &corev1.ListMatchProfilesResponse{
+		Profiles: protoProfiles,
+		Message:  fmt.Sprintf("Found %d match profiles", len(protoProfiles)),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// SetMatchProfile creates or replaces a match profile of a workspace
+func (s *Server) SetMatchProfile(ctx context.Context, req *corev1.SetMatchProfileRequest) (*corev1.SetMatchProfileResponse, error) {
+	defer s.trackOperation()()
+
+	if req.ProfileName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.InvalidArgument, "profile_name is required")
+	}
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+
+	// The options not given are those of the profile it is based on, or that it replaces
+	base := mapping.BuiltInMatchProfile(mapping.MatchProfileTable)
+	baseName := req.GetBasedOn()
+	if baseName == "" {
+		baseName = req.ProfileName
+	}
+	existing, err := mappingService.GetMatchProfile(ctx, workspaceID, baseName)
+	switch {
+	case err == nil:
+		base = existing
+	case !errors.Is(err, mapping.ErrMatchProfileNotFound):
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get match profile: %v", err)
+	case req.BasedOn != nil:
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "match profile %s to base the profile on not found", req.GetBasedOn())
+	}
+
+	options := base.Options
+	if req.NameSimilarityThreshold != nil {
+		options.NameSimilarityThreshold = req.GetNameSimilarityThreshold()
+	}
+	if req.PoorMatchThreshold != nil {
+		options.PoorMatchThreshold = req.GetPoorMatchThreshold()
+	}
+	if req.NameWeight != nil {
+		options.NameWeight = req.GetNameWeight()
+	}
+	if req.TypeWeight != nil {
+		options.TypeWeight = req.GetTypeWeight()
+	}
+	if req.ClassificationWeight != nil {
+		options.ClassificationWeight = req.GetClassificationWeight()
+	}
+	if req.PrivilegedDataWeight != nil {
+		options.PrivilegedDataWeight = req.GetPrivilegedDataWeight()
+	}
+	if req.TableStructureWeight != nil {
+		options.TableStructureWeight = req.GetTableStructureWeight()
+	}
+	if req.EnableCrossTableMatching != nil {
+		options.EnableCrossTableMatching = req.GetEnableCrossTableMatching()
+	}
+	if req.MinRuleScore != nil {
+		options.MinRuleScore = req.GetMinRuleScore()
+	}
+	description := req.GetProfileDescription()
+	if req.ProfileDescription == nil && existing != nil && !existing.BuiltIn && existing.Name == req.ProfileName {
+		description = existing.Description
+	}
+	if err := options.Validate(); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "invalid match profile: %v", err)
+	}
+
+	profile, err := mappingService.SetMatchProfile(ctx, workspaceID, req.ProfileName, description, options)
+	if err != nil {
+		s.engine.IncrementErrors()
+		if errors.Is(err, mapping.ErrBuiltInMatchProfile) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is a built-in match profile; choose another name", req.ProfileName)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set match profile: %v", err)
+	}
+
+	return &corev1.SetMatchProfileResponse{
+		Profile: matchProfileToProto(profile),
+		Message: fmt.Sprintf("Match profile %s set successfully", profile.Name),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// DeleteMatchProfile deletes a match profile of a workspace
+func (s *Server) DeleteMatchProfile(ctx context.Context, req *corev1.DeleteMatchProfileRequest) (*corev1.DeleteMatchProfileResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	if err := mapping.NewService(s.engine.db, s.engine.logger).DeleteMatchProfile(ctx, workspaceID, req.ProfileName); err != nil {
+		s.engine.IncrementErrors()
+		switch {
+		case errors.Is(err, mapping.ErrBuiltInMatchProfile):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, mapping.ErrMatchProfileNotFound):
+			return nil, status.Errorf(codes.NotFound, "match profile %s not found", req.ProfileName)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete match profile: %v", err)
+	}
+
+	return &corev1.DeleteMatchProfileResponse{
+		Message: fmt.Sprintf("Match profile %s deleted successfully", req.ProfileName),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// SetDefaultMatchProfile sets the match profile new mappings of a workspace use by default
+func (s *Server) SetDefaultMatchProfile(ctx context.Context, req *corev1.SetDefaultMatchProfileRequest) (*corev1.SetDefaultMatchProfileResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	if err := mapping.NewService(s.engine.db, s.engine.logger).SetDefaultMatchProfile(ctx, workspaceID, req.ProfileName); err != nil {
+		s.engine.IncrementErrors()
+		if errors.Is(err, mapping.ErrMatchProfileNotFound) {
+			return nil, status.Errorf(codes.NotFound, "match profile %s not found", req.ProfileName)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set the default match profile: %v", err)
+	}
+
+	message := fmt.Sprintf("Match profile %s is now the default of workspace %s", req.ProfileName, req.WorkspaceName)
+	if req.ProfileName == "" {
+		message = fmt.Sprintf("Workspace %s uses the built-in match profiles of the mapping scopes again", req.WorkspaceName)
+	}
+	return &corev1.SetDefaultMatchProfileResponse{
+		Message: message,
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// resolveMatchProfile returns the match profile generating the rules of a new mapping of a
+// scope: the named profile, else the default profile of the workspace, else the built-in profile
+// of the scope
+func (s *Server) resolveMatchProfile(ctx context.Context, mappingService *mapping.Service, workspaceID, name, scope string) (*mapping.MatchProfile, error) {
+	profile, err := mappingService.ResolveMatchProfile(ctx, workspaceID, name, scope)
+	if errors.Is(err, mapping.ErrMatchProfileNotFound) {
+		return nil, status.Errorf(codes.InvalidArgument, "match profile %s not found", name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to resolve match profile: %v", err)
+	}
+	s.engine.logger.Infof("Matching with match profile %s", profile.Name)
+	return profile, nil
+}
+
+func matchOptionsToProto(options mapping.MatchOptions) *unifiedmodelv1.MatchOptions {
+	return &unifiedmodelv1.MatchOptions{
+		NameSimilarityThreshold:  options.NameSimilarityThreshold,
+		PoorMatchThreshold:       options.PoorMatchThreshold,
+		NameWeight:               options.NameWeight,
+		TypeWeight:               options.TypeWeight,
+		ClassificationWeight:     options.ClassificationWeight,
+		PrivilegedDataWeight:     options.PrivilegedDataWeight,
+		TableStructureWeight:     options.TableStructureWeight,
+		EnableCrossTableMatching: options.EnableCrossTableMatching,
+	}
+}
+
+func matchProfileToProto(profile *mapping.MatchProfile) *corev1.MatchProfile {
+	protoProfile := &corev1.MatchProfile{
+		ProfileName:              profile.Name,
+		ProfileDescription:       profile.Description,
+		NameSimilarityThreshold:  profile.Options.NameSimilarityThreshold,
+		PoorMatchThreshold:       profile.Options.PoorMatchThreshold,
+		NameWeight:               profile.Options.NameWeight,
+		TypeWeight:               profile.Options.TypeWeight,
+		ClassificationWeight:     profile.Options.ClassificationWeight,
+		PrivilegedDataWeight:     profile.Options.PrivilegedDataWeight,
+		TableStructureWeight:     profile.Options.TableStructureWeight,
+		EnableCrossTableMatching: profile.Options.EnableCrossTableMatching,
+		MinRuleScore:             profile.Options.MinRuleScore,
+		BuiltIn:                  profile.BuiltIn,
+		IsDefault:                profile.IsDefault,
+	}
+	if !profile.Updated.IsZero() {
+		protoProfile.Updated = profile.Updated.Format(time.RFC3339)
+	}
+	return protoProfile
+}
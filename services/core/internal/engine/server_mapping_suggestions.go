@@ -0,0 +1,266 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	unifiedmodelv1 "github.com/redbco/redb-open/api/proto/unifiedmodel/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/database"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// RefreshMappingSuggestions re-runs the matcher of a table or database mapping on the current
+// schemas of its databases, and diffs the result against the rules of the mapping: rules mapping
+// dropped columns are proposed for removal, and matches of columns no rule maps, such as added
+// columns, are proposed as new rules. With apply, the suggestions are applied.
+func (s *Server) RefreshMappingSuggestions(ctx context.Context, req *corev1.RefreshMappingSuggestionsRequest) (*corev1.RefreshMappingSuggestionsResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	mappingObj, err := mappingService.Get(ctx, req.TenantId, workspaceID, req.MappingName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "mapping not found: %v", err)
+	}
+
+	scope := mappingObj.SourceType
+	if scope != mappingObj.TargetType || (scope != "table" && scope != "database") {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "mapping %s is neither a table-to-table nor a database-to-database mapping", req.MappingName)
+	}
+
+	// Resolve the databases of the mapping, with their current schemas
+	databaseService := database.NewService(s.engine.db, s.engine.logger)
+	sourceDBObj, err := databaseService.GetByID(ctx, getString(mappingObj.MappingObject, "source_database_id"))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "source database not found: %v", err)
+	}
+	targetDBObj, err := databaseService.GetByID(ctx, getString(mappingObj.MappingObject, "target_database_id"))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "target database not found: %v", err)
+	}
+	sourceColumns, err := s.databaseSchemaColumns(sourceDBObj)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	targetColumns, err := s.databaseSchemaColumns(targetDBObj)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	profile, err := s.resolveMatchProfile(ctx, mappingService, workspaceID, req.GetMatchProfile(), scope)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	umClient := s.engine.GetUnifiedModelClient()
+	if umClient == nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "unified model service not available")
+	}
+
+	match := &databaseMappingMatch{
+		tenantID:    req.TenantId,
+		workspaceID: workspaceID,
+		mappingName: mappingObj.Name,
+		ownerID:     mappingObj.OwnerID,
+		sourceDB:    sourceDBObj,
+		targetDB:    targetDBObj,
+		profile:     profile,
+	}
+	if scope == "table" {
+		match.sourceTable = getString(mappingObj.MappingObject, "source_table_name")
+		match.targetTable = getString(mappingObj.MappingObject, "target_table_name")
+	} else {
+		match.tablePins = mappingObj.TablePins()
+	}
+	matchResp, err := s.matchDatabaseMapping(ctx, umClient, mappingService, match)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to match the databases of the mapping: %v", err)
+	}
+
+	rules, err := mappingService.GetMappingRulesForMappingByID(ctx, req.TenantId, workspaceID, mappingObj.ID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get mapping rules: %v", err)
+	}
+
+	// The candidates are the column matches rules would be generated for
+	var candidates []mapping.MatchCandidate
+	for _, tableMatch := range matchResp.TableMatches {
+		for _, columnMatch := range tableMatch.ColumnMatches {
+			if columnMatch.Score < profile.Options.MinRuleScore {
+				continue
+			}
+			if scope == "table" && (columnMatch.IsPoorMatch || columnMatch.IsUnmatched) {
+				continue
+			}
+			candidates = append(candidates, mapping.MatchCandidate{
+				Source: mapping.ColumnRef{Table: tableMatch.SourceTable, Column: columnMatch.SourceColumn},
+				Target: mapping.ColumnRef{Table: tableMatch.TargetTable, Column: columnMatch.TargetColumn},
+				Score:  columnMatch.Score,
+			})
+		}
+	}
+	additions, removals := mapping.SuggestRuleChanges(rules, sourceColumns, targetColumns, candidates)
+
+	response := &corev1.RefreshMappingSuggestionsResponse{
+		Success:   true,
+		Status:    commonv1.Status_STATUS_SUCCESS,
+		Additions: ruleSuggestionsToProto(additions),
+		Removals:  ruleSuggestionsToProto(removals),
+		Warnings:  matchResp.Warnings,
+	}
+
+	if req.Apply {
+		removed := 0
+		for _, removal := range removals {
+			if err := mappingService.DetachMappingRule(ctx, req.TenantId, workspaceID, mappingObj.Name, removal.RuleName); err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.Internal, "failed to remove rule %s: %v", removal.RuleName, err)
+			}
+			removed++
+			// A rule still attached to other mappings is only detached from this one
+			if err := mappingService.DeleteMappingRule(ctx, req.TenantId, workspaceID, removal.RuleName); err != nil {
+				s.engine.logger.Warnf("Kept mapping rule %s detached from mapping %s: %v", removal.RuleName, mappingObj.Name, err)
+			}
+		}
+
+		added := 0
+		if len(additions) > 0 {
+			policies, err := s.loadTransformationPolicies(ctx, req.TenantId)
+			if err != nil {
+				s.engine.IncrementErrors()
+				return nil, status.Errorf(codes.Internal, "%v", err)
+			}
+			added = s.createDatabaseMappingRules(ctx, mappingService, policies, match, suggestedMatches(matchResp, additions), nil, nil)
+		}
+
+		// The rules changed, the mapping must be validated again
+		if removed > 0 || added > 0 {
+			if err := mappingService.InvalidateMapping(ctx, mappingObj.ID); err != nil {
+				s.engine.logger.Warnf("Failed to invalidate mapping %s: %v", mappingObj.Name, err)
+			}
+		}
+		if added < len(additions) {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%d of %d suggested rules could not be created", len(additions)-added, len(additions)))
+		}
+
+		response.Applied = true
+		response.AddedRuleCount = int32(added)
+		response.RemovedRuleCount = int32(removed)
+		response.Message = fmt.Sprintf("Removed %d rules and added %d rules", removed, added)
+
+		mappingObj, err = mappingService.Get(ctx, req.TenantId, workspaceID, mappingObj.Name)
+		if err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.Internal, "failed to get mapping: %v", err)
+		}
+	} else {
+		response.Message = fmt.Sprintf("Suggested %d rules to add and %d rules to remove", len(additions), len(removals))
+	}
+
+	protoMapping, err := s.mappingToProto(mappingObj)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to convert mapping: %v", err)
+	}
+	response.Mapping = protoMapping
+	return response, nil
+}
+
+// databaseSchemaColumns returns the columns of the tables of the current schema of a database
+func (s *Server) databaseSchemaColumns(db *database.Database) (mapping.SchemaColumns, error) {
+	if db.Schema == "" {
+		return nil, fmt.Errorf("database %s has no schema to match", db.Name)
+	}
+	columns, err := s.schemaColumns(db.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the schema of database %s: %v", db.Name, err)
+	}
+	return columns, nil
+}
+
+// schemaColumns returns the columns of the tables of a schema
+func (s *Server) schemaColumns(schema string) (mapping.SchemaColumns, error) {
+	um, err := s.convertDatabaseSchemaToUnifiedModel(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(mapping.SchemaColumns, len(um.Tables))
+	for tableName, table := range um.Tables {
+		tableColumns := make(map[string]bool, len(table.Columns))
+		for columnName := range table.Columns {
+			tableColumns[columnName] = true
+		}
+		columns[tableName] = tableColumns
+	}
+	return columns, nil
+}
+
+// suggestedMatches returns the response of the matcher with the column matches of the suggested
+// additions only
+func suggestedMatches(matchResp *unifiedmodelv1.MatchUnifiedModelsEnrichedResponse, additions []mapping.RuleSuggestion) *unifiedmodelv1.MatchUnifiedModelsEnrichedResponse {
+	suggested := make(map[[2]mapping.ColumnRef]bool, len(additions))
+	for _, addition := range additions {
+		suggested[[2]mapping.ColumnRef{addition.Source, addition.Target}] = true
+	}
+
+	filtered := &unifiedmodelv1.MatchUnifiedModelsEnrichedResponse{}
+	for _, tableMatch := range matchResp.TableMatches {
+		var columnMatches []*unifiedmodelv1.EnrichedColumnMatch
+		for _, columnMatch := range tableMatch.ColumnMatches {
+			pair := [2]mapping.ColumnRef{
+				{Table: tableMatch.SourceTable, Column: columnMatch.SourceColumn},
+				{Table: tableMatch.TargetTable, Column: columnMatch.TargetColumn},
+			}
+			if suggested[pair] {
+				columnMatches = append(columnMatches, columnMatch)
+			}
+		}
+		if len(columnMatches) == 0 {
+			continue
+		}
+		filteredMatch := proto.Clone(tableMatch).(*unifiedmodelv1.EnrichedTableMatch)
+		filteredMatch.ColumnMatches = columnMatches
+		filtered.TableMatches = append(filtered.TableMatches, filteredMatch)
+	}
+	return filtered
+}
+
+// ruleSuggestionsToProto converts suggested rule changes to protobuf
+func ruleSuggestionsToProto(suggestions []mapping.RuleSuggestion) []*corev1.MappingRuleSuggestion {
+	protoSuggestions := make([]*corev1.MappingRuleSuggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		protoSuggestions[i] = &corev1.MappingRuleSuggestion{
+			Action:       suggestion.Action,
+			RuleName:     suggestion.RuleName,
+			SourceTable:  suggestion.Source.Table,
+			SourceColumn: suggestion.Source.Column,
+			TargetTable:  suggestion.Target.Table,
+			TargetColumn: suggestion.Target.Column,
+			Score:        suggestion.Score,
+			Reason:       suggestion.Reason,
+		}
+	}
+	return protoSuggestions
+}
@@ -0,0 +1,329 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+)
+
+// Checks of a mapping validation
+const (
+	checkExistence         = "existence"
+	checkTypeCompatibility = "type_compatibility"
+	checkTransformation    = "transformation"
+	checkCardinality       = "cardinality"
+)
+
+// mappingValidator checks the rules of a mapping against the current schemas of their databases,
+// the items of the resource registry and the transformation service, without running anything
+type mappingValidator struct {
+	server         *Server
+	mappingService *mapping.Service
+	schemas        map[string]mapping.SchemaColumns
+	items          map[string]*mapping.ResourceItem // By URI, nil when not registered
+
+	transformationClient transformationv1.TransformationServiceClient
+	transformationErr    error
+	transformations      map[string]*transformationv1.TransformationMetadata // By name, nil when it does not exist
+}
+
+func (s *Server) newMappingValidator(mappingService *mapping.Service) *mappingValidator {
+	v := &mappingValidator{
+		server:          s,
+		mappingService:  mappingService,
+		schemas:         make(map[string]mapping.SchemaColumns),
+		items:           make(map[string]*mapping.ResourceItem),
+		transformations: make(map[string]*transformationv1.TransformationMetadata),
+	}
+	v.transformationClient, v.transformationErr = s.getTransformationClient()
+	return v
+}
+
+// validateRule checks that the objects of a rule exist, that its transformation resolves and
+// supports its cardinality, which must match its sources and targets, and that the values it
+// writes fit the types of its target columns
+func (v *mappingValidator) validateRule(ctx context.Context, rule *mapping.Rule) *corev1.MappingRuleValidation {
+	transformationName, _ := rule.Metadata["transformation_name"].(string)
+	validation := &corev1.MappingRuleValidation{
+		MappingRuleName:    rule.Name,
+		TransformationName: transformationName,
+	}
+	addIssue := func(check, severity, objectURI, format string, args ...interface{}) {
+		validation.Issues = append(validation.Issues, &corev1.MappingValidationIssue{
+			Check:     check,
+			Severity:  severity,
+			Message:   fmt.Sprintf(format, args...),
+			ObjectUri: objectURI,
+		})
+	}
+
+	// Existence of the tables and columns in the current schemas, and in the resource registry
+	v.server.ruleBreakage(ctx, rule.Metadata, v.schemas)
+	missing := make(map[string]bool)
+	for _, reference := range mapping.MissingReferences(rule.Metadata, v.schemas) {
+		missing[reference.URI] = true
+		addIssue(checkExistence, "error", reference.URI, "%s no longer exists", reference)
+	}
+	var sourceItems, targetItems []*mapping.ResourceItem
+	for _, reference := range mapping.RuleReferences(rule.Metadata) {
+		if reference.Column == "" || missing[reference.URI] {
+			continue
+		}
+		item := v.item(ctx, reference.URI)
+		if item == nil {
+			addIssue(checkExistence, "warning", reference.URI, "%s is not in the resource registry, so its type is not checked", reference)
+			continue
+		}
+		if reference.Side == "source" {
+			sourceItems = append(sourceItems, item)
+		} else {
+			targetItems = append(targetItems, item)
+		}
+	}
+
+	// Cardinality of the rule against its sources and targets
+	sourceCount, targetCount := ruleItemCount(rule.Metadata, "source"), ruleItemCount(rule.Metadata, "target")
+	cardinality := rule.Cardinality
+	if cardinality == "" {
+		cardinality = inferCardinality(sourceCount, targetCount)
+	}
+	validation.Cardinality = cardinality
+	if err := validateCardinality(cardinality, sourceCount, targetCount); err != nil {
+		addIssue(checkCardinality, "error", "", "%v", err)
+	}
+
+	// Transformation, and the type of the values it writes
+	transformationType, outputType := "passthrough", ""
+	direct := transformationName == "" || transformationName == "direct_mapping"
+	if !direct {
+		var err error
+		transformationType, outputType, err = v.resolveTransformation(ctx, transformationName)
+		switch {
+		case err != nil && v.transformationErr != nil:
+			addIssue(checkTransformation, "warning", "", "transformation '%s' is not checked: %v", transformationName, err)
+			transformationType = ""
+		case err != nil:
+			addIssue(checkTransformation, "error", "", "%v", err)
+			transformationType = ""
+		}
+	}
+	if transformationType != "" {
+		if err := validateTransformationCardinality(transformationType, cardinality); err != nil {
+			addIssue(checkCardinality, "error", "", "%v", err)
+		}
+	}
+	if err := validateSourceCombination(cardinality, transformationName); err != nil {
+		addIssue(checkCardinality, "error", "", "%v", err)
+	}
+
+	// Types of the values written to the target columns
+	switch {
+	case direct && len(sourceItems) == 1:
+		source := sourceItems[0]
+		for _, target := range targetItems {
+			checkWrittenType(addIssue, itemDataType(source), target)
+			if source.IsNullable && !target.IsNullable && target.DefaultValue == nil {
+				addIssue(checkTypeCompatibility, "warning", target.ResourceURI,
+					"%s is nullable but %s is not: rows with NULL values are rejected by the target", source.ItemName, target.ItemName)
+			}
+			if source.MaxLength != nil && target.MaxLength != nil && *target.MaxLength < *source.MaxLength {
+				addIssue(checkTypeCompatibility, "warning", target.ResourceURI,
+					"%s holds up to %d characters but %s only %d: longer values are rejected by the target",
+					source.ItemName, *source.MaxLength, target.ItemName, *target.MaxLength)
+			}
+		}
+	case !direct && transformationType != "null_returning" && outputType != "" && outputType != "any":
+		for _, target := range targetItems {
+			checkWrittenType(addIssue, outputType, target)
+		}
+	}
+
+	validation.Valid = true
+	for _, issue := range validation.Issues {
+		if issue.Severity == "error" {
+			validation.Valid = false
+		}
+	}
+	return validation
+}
+
+// checkWrittenType checks that values of a data type can be written to a target item
+func checkWrittenType(addIssue func(check, severity, objectURI, format string, args ...interface{}), dataType string, target *mapping.ResourceItem) {
+	compatibility := mapping.CheckTypeCompatibility(dataType, itemDataType(target))
+	switch {
+	case !compatibility.Compatible:
+		addIssue(checkTypeCompatibility, "error", target.ResourceURI, "%s: %s", target.ItemName, compatibility.Reason)
+	case compatibility.Lossy:
+		addIssue(checkTypeCompatibility, "warning", target.ResourceURI, "%s: %s", target.ItemName, compatibility.Reason)
+	}
+}
+
+// validateMappingTargets checks the mapping as a whole: a target column written by several
+// rules gets the value of whichever runs last, and required target columns no rule writes make
+// the target reject the rows
+func (v *mappingValidator) validateMappingTargets(ctx context.Context, rules []*mapping.Rule) []*corev1.MappingValidationIssue {
+	var issues []*corev1.MappingValidationIssue
+
+	writers := make(map[string][]*mapping.Rule)
+	var targets []mapping.RuleReference
+	tables := make(map[[2]string]bool)
+	for _, rule := range rules {
+		for _, reference := range mapping.RuleReferences(rule.Metadata) {
+			if reference.Side != "target" || reference.Column == "" {
+				continue
+			}
+			if len(writers[reference.URI]) == 0 {
+				targets = append(targets, reference)
+			}
+			writers[reference.URI] = append(writers[reference.URI], rule)
+			tables[[2]string{reference.DatabaseID, reference.Table}] = true
+		}
+	}
+
+	for _, target := range targets {
+		rulesWriting := writers[target.URI]
+		if len(rulesWriting) < 2 {
+			continue
+		}
+		names := make([]string, len(rulesWriting))
+		filtered := false
+		for i, rule := range rulesWriting {
+			names[i] = rule.Name
+			if rowFilter, _ := rule.Metadata["row_filter"].(string); rowFilter != "" {
+				filtered = true
+			}
+		}
+		// Rules with row filters may write the same column for different rows
+		severity, message := "error", fmt.Sprintf("target column %s.%s is written by rules %s", target.Table, target.Column, strings.Join(names, ", "))
+		if filtered {
+			severity, message = "warning", message+", which must have exclusive row filters"
+		}
+		issues = append(issues, &corev1.MappingValidationIssue{
+			Check:     checkCardinality,
+			Severity:  severity,
+			Message:   message,
+			ObjectUri: target.URI,
+		})
+	}
+
+	tableKeys := make([][2]string, 0, len(tables))
+	for table := range tables {
+		tableKeys = append(tableKeys, table)
+	}
+	sort.Slice(tableKeys, func(i, j int) bool {
+		return tableKeys[i][0]+"/"+tableKeys[i][1] < tableKeys[j][0]+"/"+tableKeys[j][1]
+	})
+	for _, table := range tableKeys {
+		container, err := v.mappingService.GetContainerByURI(ctx, v.server.buildResourceURI("table", table[0], table[1], ""))
+		if err != nil {
+			continue
+		}
+		items, err := v.mappingService.GetItemsForContainer(ctx, container.ContainerID)
+		if err != nil {
+			continue
+		}
+		for _, item := range items {
+			if item.IsNullable || item.DefaultValue != nil || len(writers[item.ResourceURI]) > 0 {
+				continue
+			}
+			issues = append(issues, &corev1.MappingValidationIssue{
+				Check:     checkExistence,
+				Severity:  "warning",
+				Message:   fmt.Sprintf("required target column %s.%s is not written by any rule: the target rejects the rows unless it fills the column", table[1], item.ItemName),
+				ObjectUri: item.ResourceURI,
+			})
+		}
+	}
+	return issues
+}
+
+// item returns the item of the resource registry with the URI, or nil when there is none
+func (v *mappingValidator) item(ctx context.Context, uri string) *mapping.ResourceItem {
+	item, loaded := v.items[uri]
+	if !loaded {
+		item, _ = v.mappingService.GetItemByURI(ctx, uri)
+		v.items[uri] = item
+	}
+	return item
+}
+
+// resolveTransformation returns the type and the output data type of a transformation or a
+// transformation chain, or why it does not resolve
+func (v *mappingValidator) resolveTransformation(ctx context.Context, transformationName string) (string, string, error) {
+	names := mapping.TransformationChain(transformationName)
+	if names == nil {
+		names = []string{transformationName}
+	}
+
+	steps := make([]mapping.TransformationStep, 0, len(names))
+	for _, name := range names {
+		metadata, err := v.transformation(ctx, name)
+		if err != nil {
+			return "", "", err
+		}
+		steps = append(steps, mapping.TransformationStep{
+			Name:       name,
+			Type:       metadata.Type,
+			InputType:  metadata.InputType,
+			OutputType: metadata.OutputType,
+		})
+	}
+	if len(steps) == 1 {
+		return steps[0].Type, steps[0].OutputType, nil
+	}
+
+	chainType, err := mapping.ValidateTransformationChain(steps)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid transformation chain: %v", err)
+	}
+	return chainType, steps[len(steps)-1].OutputType, nil
+}
+
+// transformation returns the metadata of a transformation from the transformation service
+func (v *mappingValidator) transformation(ctx context.Context, name string) (*transformationv1.TransformationMetadata, error) {
+	if v.transformationErr != nil {
+		return nil, v.transformationErr
+	}
+	metadata, loaded := v.transformations[name]
+	if !loaded {
+		resp, err := v.transformationClient.GetTransformationMetadata(ctx, &transformationv1.GetTransformationMetadataRequest{
+			TransformationName: name,
+		})
+		if err == nil && resp.Status == commonv1.Status_STATUS_SUCCESS {
+			metadata = resp.Metadata
+		}
+		v.transformations[name] = metadata
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("transformation '%s' does not exist", name)
+	}
+	return metadata, nil
+}
+
+// ruleItemCount returns the number of sources or targets of the metadata of a rule
+func ruleItemCount(metadata map[string]interface{}, side string) int {
+	if uris, ok := metadata[side+"_uris"].([]interface{}); ok && len(uris) > 0 {
+		return len(uris)
+	}
+	if uris, ok := metadata[side+"_uris"].([]string); ok && len(uris) > 0 {
+		return len(uris)
+	}
+	if uri, _ := metadata[side+"_resource_uri"].(string); uri != "" {
+		return 1
+	}
+	return 0
+}
+
+// itemDataType returns the unified data type of an item, or its data type in its database
+func itemDataType(item *mapping.ResourceItem) string {
+	if item.UnifiedDataType != nil && *item.UnifiedDataType != "" {
+		return *item.UnifiedDataType
+	}
+	return item.DataType
+}
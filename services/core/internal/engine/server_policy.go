@@ -2,9 +2,11 @@ package engine
 
 import (
 	"context"
+	"fmt"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	transformationv1 "github.com/redbco/redb-open/api/proto/transformation/v1"
 	"github.com/redbco/redb-open/services/core/internal/services/policy"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -74,8 +76,15 @@ func (s *Server) AddPolicy(ctx context.Context, req *corev1.AddPolicyRequest) (*
 	// Get policy service
 	policyService := policy.NewService(s.engine.db, s.engine.logger)
 
+	// Validate the policy object
+	policyObject := req.PolicyObject.AsMap()
+	if err := s.validatePolicyObject(ctx, policyObject); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "invalid policy object: %v", err)
+	}
+
 	// Create the policy
-	createdPolicy, err := policyService.Create(ctx, req.TenantId, req.PolicyName, req.PolicyDescription, "access", "allow", []string{}, []string{}, map[string]interface{}{}, 0, req.OwnerId)
+	createdPolicy, err := policyService.Create(ctx, req.TenantId, req.PolicyName, req.PolicyDescription, policyObject, req.OwnerId)
 	if err != nil {
 		s.engine.IncrementErrors()
 		return nil, status.Errorf(codes.Internal, "failed to create policy: %v", err)
@@ -111,6 +120,10 @@ func (s *Server) ModifyPolicy(ctx context.Context, req *corev1.ModifyPolicyReque
 		updates["policy_description"] = *req.PolicyDescription
 	}
 	if req.PolicyObject != nil {
+		if err := s.validatePolicyObject(ctx, req.PolicyObject.AsMap()); err != nil {
+			s.engine.IncrementErrors()
+			return nil, status.Errorf(codes.InvalidArgument, "invalid policy object: %v", err)
+		}
 		jsonBytes, err := req.PolicyObject.MarshalJSON()
 		if err != nil {
 			s.engine.IncrementErrors()
@@ -160,3 +173,31 @@ func (s *Server) DeletePolicy(ctx context.Context, req *corev1.DeletePolicyReque
 		Status:  commonv1.Status_STATUS_SUCCESS,
 	}, nil
 }
+
+// validatePolicyObject checks the object of a policy. The transformation a transformation policy
+// requires must exist.
+func (s *Server) validatePolicyObject(ctx context.Context, object map[string]interface{}) error {
+	if _, err := policy.ParseSchemaChangePolicy(object); err != nil {
+		return err
+	}
+
+	requirement, err := policy.ParseTransformationRequirement(object)
+	if err != nil || requirement == nil {
+		return err
+	}
+
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		return err
+	}
+	resp, err := transformationClient.GetTransformationMetadata(ctx, &transformationv1.GetTransformationMetadataRequest{
+		TransformationName: requirement.Transformation,
+	})
+	if err != nil {
+		return fmt.Errorf("transformation '%s' does not exist or is invalid: %v", requirement.Transformation, err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS || resp.Metadata == nil {
+		return fmt.Errorf("transformation '%s' does not exist: %s", requirement.Transformation, resp.StatusMessage)
+	}
+	return nil
+}
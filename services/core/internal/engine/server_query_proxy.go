@@ -0,0 +1,367 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/queryproxy"
+	"github.com/redbco/redb-open/services/core/internal/services/relationship"
+	"github.com/redbco/redb-open/services/core/internal/services/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// QueryProxyService gRPC handlers
+// ============================================================================
+
+func (s *Server) ListQueryProxies(ctx context.Context, req *corev1.ListQueryProxiesRequest) (*corev1.ListQueryProxiesResponse, error) {
+	defer s.trackOperation()()
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	proxies, err := queryproxy.NewService(s.engine.db, s.engine.logger).List(ctx, req.TenantId, workspaceID)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list query proxies: %v", err)
+	}
+
+	protoProxies := make([]*corev1.QueryProxy, len(proxies))
+	for i, p := range proxies {
+		protoProxies[i] = queryProxyToProto(p)
+	}
+	return &corev1.ListQueryProxiesResponse{Proxies: protoProxies}, nil
+}
+
+func (s *Server) ShowQueryProxy(ctx context.Context, req *corev1.ShowQueryProxyRequest) (*corev1.ShowQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	p, err := s.getQueryProxy(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	protoProxy := queryProxyToProto(p)
+	if p.Status == "STATUS_STARTED" {
+		protoProxy.Stats = s.queryProxyStats(ctx, p)
+	}
+	return &corev1.ShowQueryProxyResponse{Proxy: protoProxy}, nil
+}
+
+func (s *Server) AddQueryProxy(ctx context.Context, req *corev1.AddQueryProxyRequest) (*corev1.AddQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	if req.ProxyName == "" || req.RelationshipName == "" {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.InvalidArgument, "proxy_name and relationship_name are required")
+	}
+	if err := queryproxy.ValidateRoutes(req.ReadPercent, req.WritePercent, req.TableRoutes); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "invalid routes: %v", err)
+	}
+
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	rel, err := relationship.NewService(s.engine.db, s.engine.logger).GetByName(ctx, req.TenantId, workspaceID, req.RelationshipName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "relationship not found: %v", err)
+	}
+
+	p := &queryproxy.QueryProxy{
+		TenantID:       req.TenantId,
+		WorkspaceID:    workspaceID,
+		Name:           req.ProxyName,
+		Description:    req.ProxyDescription,
+		RelationshipID: rel.ID,
+		ListenPort:     req.ListenPort,
+		Username:       req.Username,
+		ReadPercent:    req.ReadPercent,
+		WritePercent:   req.WritePercent,
+		TableRoutes:    req.TableRoutes,
+		OwnerID:        req.OwnerId,
+	}
+
+	created, err := queryproxy.NewService(s.engine.db, s.engine.logger).Create(ctx, p, req.Password)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to create query proxy: %v", err)
+	}
+
+	return &corev1.AddQueryProxyResponse{
+		Message: fmt.Sprintf("Query proxy '%s' created successfully", created.Name),
+		Success: true,
+		Proxy:   queryProxyToProto(created),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) DeleteQueryProxy(ctx context.Context, req *corev1.DeleteQueryProxyRequest) (*corev1.DeleteQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	p, err := s.getQueryProxy(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	if p.Status == "STATUS_STARTED" {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "query proxy '%s' is running; stop it before deleting it", p.Name)
+	}
+
+	if err := queryproxy.NewService(s.engine.db, s.engine.logger).Delete(ctx, p.TenantID, p.WorkspaceID, p.Name); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to delete query proxy: %v", err)
+	}
+
+	return &corev1.DeleteQueryProxyResponse{
+		Message: fmt.Sprintf("Query proxy '%s' deleted successfully", p.Name),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) StartQueryProxy(ctx context.Context, req *corev1.StartQueryProxyRequest) (*corev1.StartQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	p, client, err := s.queryProxyOnNode(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+	service := queryproxy.NewService(s.engine.db, s.engine.logger)
+
+	resp, err := client.StartQueryProxy(ctx, &anchorv1.StartQueryProxyRequest{
+		TenantId:    p.TenantID,
+		WorkspaceId: p.WorkspaceID,
+		ProxyId:     p.ID,
+	})
+	if err == nil && !resp.Success {
+		err = fmt.Errorf("%s", resp.Message)
+	}
+	if err != nil {
+		s.engine.IncrementErrors()
+		if statusErr := service.SetStatus(ctx, p, "STATUS_ERROR", err.Error()); statusErr != nil {
+			s.engine.logger.Errorf("%v", statusErr)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to start query proxy: %v", err)
+	}
+
+	if err := service.SetStatus(ctx, p, "STATUS_STARTED", fmt.Sprintf("Listening on %s", resp.ListenAddress)); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &corev1.StartQueryProxyResponse{
+		Message: fmt.Sprintf("Query proxy '%s' listening on %s", p.Name, resp.ListenAddress),
+		Success: true,
+		Proxy:   queryProxyToProto(p),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) StopQueryProxy(ctx context.Context, req *corev1.StopQueryProxyRequest) (*corev1.StopQueryProxyResponse, error) {
+	defer s.trackOperation()()
+
+	p, client, err := s.queryProxyOnNode(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	resp, err := client.StopQueryProxy(ctx, &anchorv1.StopQueryProxyRequest{
+		TenantId:    p.TenantID,
+		WorkspaceId: p.WorkspaceID,
+		ProxyId:     p.ID,
+	})
+	if err == nil && !resp.Success {
+		err = fmt.Errorf("%s", resp.Message)
+	}
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to stop query proxy: %v", err)
+	}
+
+	if err := queryproxy.NewService(s.engine.db, s.engine.logger).SetStatus(ctx, p, "STATUS_STOPPED", ""); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return &corev1.StopQueryProxyResponse{
+		Message: fmt.Sprintf("Query proxy '%s' stopped", p.Name),
+		Success: true,
+		Proxy:   queryProxyToProto(p),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) UpdateQueryProxyRoutes(ctx context.Context, req *corev1.UpdateQueryProxyRoutesRequest) (*corev1.UpdateQueryProxyRoutesResponse, error) {
+	defer s.trackOperation()()
+
+	p, err := s.getQueryProxy(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, err
+	}
+
+	if req.ReadPercent != nil {
+		p.ReadPercent = *req.ReadPercent
+	}
+	if req.WritePercent != nil {
+		p.WritePercent = *req.WritePercent
+	}
+	if req.ClearTableRoutes || p.TableRoutes == nil {
+		p.TableRoutes = map[string]string{}
+	}
+	for table, db := range req.TableRoutes {
+		if db == "" {
+			delete(p.TableRoutes, table)
+		} else {
+			p.TableRoutes[table] = db
+		}
+	}
+	if err := queryproxy.ValidateRoutes(p.ReadPercent, p.WritePercent, p.TableRoutes); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "invalid routes: %v", err)
+	}
+
+	if err := queryproxy.NewService(s.engine.db, s.engine.logger).SaveRoutes(ctx, p); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// A running proxy applies the stored routes; its open sessions use them from their next
+	// statement outside a transaction
+	message := fmt.Sprintf("Routes of query proxy '%s' updated", p.Name)
+	if p.Status == "STATUS_STARTED" {
+		_, client, err := s.queryProxyOnNode(ctx, req.TenantId, req.WorkspaceName, req.ProxyName)
+		if err == nil {
+			var resp *anchorv1.StartQueryProxyResponse
+			resp, err = client.StartQueryProxy(ctx, &anchorv1.StartQueryProxyRequest{
+				TenantId:    p.TenantID,
+				WorkspaceId: p.WorkspaceID,
+				ProxyId:     p.ID,
+			})
+			if err == nil && !resp.Success {
+				err = fmt.Errorf("%s", resp.Message)
+			}
+		}
+		if err != nil {
+			s.engine.IncrementErrors()
+			return &corev1.UpdateQueryProxyRoutesResponse{
+				Message: fmt.Sprintf("Routes of query proxy '%s' saved but not applied: %v", p.Name, err),
+				Success: false,
+				Proxy:   queryProxyToProto(p),
+				Status:  commonv1.Status_STATUS_ERROR,
+			}, nil
+		}
+		message += " and applied"
+	}
+
+	return &corev1.UpdateQueryProxyRoutesResponse{
+		Message: message,
+		Success: true,
+		Proxy:   queryProxyToProto(p),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// getQueryProxy returns a query proxy of a workspace, or a gRPC status error
+func (s *Server) getQueryProxy(ctx context.Context, tenantID, workspaceName, name string) (*queryproxy.QueryProxy, error) {
+	workspaceID, err := workspace.NewService(s.engine.db, s.engine.logger).GetWorkspaceID(ctx, tenantID, workspaceName)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+	p, err := queryproxy.NewService(s.engine.db, s.engine.logger).GetByName(ctx, tenantID, workspaceID, name)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return p, nil
+}
+
+// queryProxyOnNode returns a query proxy and the anchor client that runs it. A proxy runs on the
+// node connecting its databases, so it is started and stopped through the core of that node.
+func (s *Server) queryProxyOnNode(ctx context.Context, tenantID, workspaceName, name string) (*queryproxy.QueryProxy, anchorv1.AnchorServiceClient, error) {
+	p, err := s.getQueryProxy(ctx, tenantID, workspaceName, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nodeID, err := s.engine.getNodeIDFromDatabase(ctx)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if strconv.FormatUint(nodeID, 10) != p.NodeID {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "query proxy '%s' runs on node %s; manage it through that node", p.Name, p.NodeID)
+	}
+
+	client := s.engine.GetAnchorClient()
+	if client == nil {
+		return nil, nil, status.Error(codes.Unavailable, "anchor service is not available")
+	}
+	return p, client, nil
+}
+
+// queryProxyStats returns the activity of a running proxy as reported by the anchor, or nil if
+// it cannot be reached
+func (s *Server) queryProxyStats(ctx context.Context, p *queryproxy.QueryProxy) *corev1.QueryProxyStats {
+	client := s.engine.GetAnchorClient()
+	if client == nil {
+		return nil
+	}
+	resp, err := client.GetQueryProxyStatus(ctx, &anchorv1.GetQueryProxyStatusRequest{
+		TenantId:    p.TenantID,
+		WorkspaceId: p.WorkspaceID,
+		ProxyId:     p.ID,
+	})
+	if err != nil || !resp.Running {
+		return nil
+	}
+	return &corev1.QueryProxyStats{
+		ListenAddress:    resp.ListenAddress,
+		ActiveSessions:   resp.ActiveSessions,
+		TotalSessions:    resp.TotalSessions,
+		SourceStatements: resp.SourceStatements,
+		TargetStatements: resp.TargetStatements,
+		Started:          resp.StartedAt,
+	}
+}
+
+func queryProxyToProto(p *queryproxy.QueryProxy) *corev1.QueryProxy {
+	return &corev1.QueryProxy{
+		TenantId:           p.TenantID,
+		WorkspaceId:        p.WorkspaceID,
+		ProxyId:            p.ID,
+		ProxyName:          p.Name,
+		ProxyDescription:   p.Description,
+		RelationshipName:   p.RelationshipName,
+		SourceDatabaseName: p.SourceDatabaseName,
+		TargetDatabaseName: p.TargetDatabaseName,
+		Protocol:           p.Protocol,
+		NodeId:             p.NodeID,
+		ListenPort:         p.ListenPort,
+		Username:           p.Username,
+		ReadPercent:        p.ReadPercent,
+		WritePercent:       p.WritePercent,
+		TableRoutes:        p.TableRoutes,
+		Status:             statusStringToProto(p.Status),
+		StatusMessage:      p.StatusMessage,
+		OwnerId:            p.OwnerID,
+		Created:            p.Created.Format(time.RFC3339),
+		Updated:            p.Updated.Format(time.RFC3339),
+	}
+}
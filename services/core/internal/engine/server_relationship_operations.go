@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -13,6 +14,7 @@ import (
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	"github.com/redbco/redb-open/services/core/internal/services/database"
 	"github.com/redbco/redb-open/services/core/internal/services/mapping"
+	"github.com/redbco/redb-open/services/core/internal/services/policy"
 	"github.com/redbco/redb-open/services/core/internal/services/relationship"
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
 )
@@ -62,6 +64,24 @@ func (s *Server) StartRelationship(req *corev1.StartRelationshipRequest, stream
 		return status.Errorf(codes.NotFound, "mapping rules not found: %v", err)
 	}
 
+	// Broken rules reference objects that no longer exist and are left out of the relationship
+	mappingRules, brokenRules := mapping.RunnableRules(mappingRules)
+	if len(brokenRules) > 0 {
+		warnings := make([]string, len(brokenRules))
+		for i, rule := range brokenRules {
+			warnings[i] = fmt.Sprintf("skipped broken mapping rule %s: %s", rule.Name, rule.Breakage().Reason)
+		}
+		if err := stream.Send(&corev1.StartRelationshipResponse{
+			Message:  fmt.Sprintf("Skipping %d broken mapping rules", len(brokenRules)),
+			Success:  true,
+			Status:   commonv1.Status_STATUS_PENDING,
+			Phase:    "checking_rules",
+			Warnings: warnings,
+		}); err != nil {
+			return err
+		}
+	}
+
 	if len(mappingRules) == 0 {
 		s.engine.IncrementErrors()
 		return status.Errorf(codes.FailedPrecondition, "mapping has no rules")
@@ -82,6 +102,29 @@ func (s *Server) StartRelationship(req *corev1.StartRelationshipRequest, stream
 
 	s.engine.logger.Infof("Starting relationship '%s': %s -> %s", rel.Name, sourceDB.Name, targetDB.Name)
 
+	// Breaking changes to the source schema since the relationship was last deployed are subject
+	// to the schema change policies of the workspace
+	schemaCheck, err := s.checkRelationshipSchemaChanges(ctx, req.TenantId, req.WorkspaceName, rel)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return status.Errorf(codes.Internal, "failed to check schema changes: %v", err)
+	}
+	if err := schemaCheck.blockedError(rel.Name); err != nil {
+		s.engine.IncrementErrors()
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if warnings := schemaCheck.messages(policy.ActionWarn); len(warnings) > 0 {
+		if err := stream.Send(&corev1.StartRelationshipResponse{
+			Message:  fmt.Sprintf("%d breaking changes to the source schema since the last deployment", len(warnings)),
+			Success:  true,
+			Status:   commonv1.Status_STATUS_PENDING,
+			Phase:    "checking_schema",
+			Warnings: warnings,
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Check if we should skip initial data copy by checking if target table already has data
 	// This is more reliable than checking replication sources (which might exist from a previous attempt)
 	skipDataCopy := false
@@ -120,9 +163,14 @@ func (s *Server) StartRelationship(req *corev1.StartRelationshipRequest, stream
 			batchSize = *req.BatchSize
 		}
 
+		parallelWorkers := int32(4)
+		if req.ParallelWorkers != nil && *req.ParallelWorkers > 0 {
+			parallelWorkers = *req.ParallelWorkers
+		}
+
 		// Perform initial data copy
 		var err error
-		totalRows, err = s.performInitialDataCopy(ctx, stream, mappingRules, sourceDB, targetDB, batchSize)
+		totalRows, err = s.performInitialDataCopy(ctx, stream, rel, mappingRules, sourceDB, targetDB, batchSize, parallelWorkers)
 		if err != nil {
 			s.engine.IncrementErrors()
 			// Update relationship status to error (truncate message to fit DB limit)
@@ -195,6 +243,11 @@ func (s *Server) StartRelationship(req *corev1.StartRelationshipRequest, stream
 	}); err != nil {
 		s.engine.logger.Warnf("Failed to update relationship status: %v", err)
 	}
+	if schemaCheck.version > 0 {
+		if err := relationshipService.SetSourceSchemaVersion(ctx, rel.ID, schemaCheck.version); err != nil {
+			s.engine.logger.Warnf("Failed to record the deployed source schema version: %v", err)
+		}
+	}
 
 	// Send final success status
 	if err := stream.Send(&corev1.StartRelationshipResponse{
@@ -322,6 +375,29 @@ func (s *Server) ResumeRelationship(req *corev1.ResumeRelationshipRequest, strea
 
 	s.engine.logger.Infof("Resuming relationship '%s'", rel.Name)
 
+	// Breaking changes to the source schema since the relationship was last deployed are subject
+	// to the schema change policies of the workspace
+	schemaCheck, err := s.checkRelationshipSchemaChanges(ctx, req.TenantId, req.WorkspaceName, rel)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return status.Errorf(codes.Internal, "failed to check schema changes: %v", err)
+	}
+	if err := schemaCheck.blockedError(rel.Name); err != nil {
+		s.engine.IncrementErrors()
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if warnings := schemaCheck.messages(policy.ActionWarn); len(warnings) > 0 {
+		if err := stream.Send(&corev1.ResumeRelationshipResponse{
+			Message:  fmt.Sprintf("%d breaking changes to the source schema since the last deployment", len(warnings)),
+			Success:  true,
+			Status:   commonv1.Status_STATUS_PENDING,
+			Phase:    "checking_schema",
+			Warnings: warnings,
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Get replication sources for this relationship
 	replicationSources, err := s.getReplicationSourcesForRelationship(ctx, rel.ID)
 	if err != nil {
@@ -366,6 +442,11 @@ func (s *Server) ResumeRelationship(req *corev1.ResumeRelationshipRequest, strea
 	}); err != nil {
 		s.engine.logger.Warnf("Failed to update relationship status: %v", err)
 	}
+	if schemaCheck.version > 0 {
+		if err := relationshipService.SetSourceSchemaVersion(ctx, rel.ID, schemaCheck.version); err != nil {
+			s.engine.logger.Warnf("Failed to record the deployed source schema version: %v", err)
+		}
+	}
 
 	// Send final success status
 	if err := stream.Send(&corev1.ResumeRelationshipResponse{
@@ -473,8 +554,9 @@ func (s *Server) RemoveRelationship(ctx context.Context, req *corev1.RemoveRelat
 
 // Helper functions
 
-// performInitialDataCopy copies all data from source to target using the mapping
-func (s *Server) performInitialDataCopy(ctx context.Context, stream corev1.RelationshipService_StartRelationshipServer, mappingRules []*mapping.Rule, sourceDB, targetDB *database.Database, batchSize int32) (int64, error) {
+// performInitialDataCopy copies all data from source to target using the mapping. The anchor
+// copies the tables, and ranges of large tables, with parallelWorkers workers.
+func (s *Server) performInitialDataCopy(ctx context.Context, stream corev1.RelationshipService_StartRelationshipServer, rel *relationship.Relationship, mappingRules []*mapping.Rule, sourceDB, targetDB *database.Database, batchSize, parallelWorkers int32) (int64, error) {
 	if len(mappingRules) == 0 {
 		return 0, fmt.Errorf("mapping has no rules")
 	}
@@ -482,25 +564,79 @@ func (s *Server) performInitialDataCopy(ctx context.Context, stream corev1.Relat
 	// Build table pairs from mapping rules (similar to copy-data)
 	tablePairs := s.groupMappingRulesByTables(mappingRules)
 
-	var totalRowsCopied int64
-
-	// Copy data for each table pair
+	tables := make([]*anchorv1.CopyTableSpec, 0, len(tablePairs))
 	for _, tablePair := range tablePairs {
-		rowsCopied, err := s.copyTableData(ctx, tablePair, batchSize)
+		sourceInfo, err := s.parseTableIdentifier(tablePair.SourceTable)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse source table: %v", err)
+		}
+		targetInfo, err := s.parseTableIdentifier(tablePair.TargetTable)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse target table: %v", err)
+		}
+		tables = append(tables, &anchorv1.CopyTableSpec{
+			SourceTable: sourceInfo.TableName,
+			TargetTable: targetInfo.TableName,
+		})
+	}
+
+	mappingRulesJSON, err := json.Marshal(mappingRules)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal mapping rules: %v", err)
+	}
+
+	// Only the rows matching the row filter of the mapping are copied
+	relMapping, err := mapping.NewService(s.engine.db, s.engine.logger).GetByID(ctx, rel.MappingID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mapping: %v", err)
+	}
+
+	anchorClient, err := s.getAnchorClient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to anchor service: %v", err)
+	}
+
+	copyStream, err := anchorClient.CopyTables(ctx, &anchorv1.CopyTablesRequest{
+		TenantId:         rel.TenantID,
+		WorkspaceId:      rel.WorkspaceID,
+		SourceDatabaseId: sourceDB.ID,
+		TargetDatabaseId: targetDB.ID,
+		Tables:           tables,
+		MappingRules:     mappingRulesJSON,
+		BatchSize:        &batchSize,
+		ParallelWorkers:  &parallelWorkers,
+		RowFilter:        relMapping.RowFilter(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start table copy: %v", err)
+	}
+
+	var totalRowsCopied int64
+	for {
+		progress, err := copyStream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return totalRowsCopied, fmt.Errorf("failed to copy table %s: %v", tablePair.SourceTable, err)
+			return totalRowsCopied, fmt.Errorf("table copy failed: %v", err)
 		}
 
-		totalRowsCopied += rowsCopied
+		totalRowsCopied = progress.TotalRowsCopied
+		if !progress.Success {
+			return totalRowsCopied, fmt.Errorf("%s", progress.Message)
+		}
+		if progress.IsComplete {
+			break
+		}
 
 		// Send progress update
 		if err := stream.Send(&corev1.StartRelationshipResponse{
-			Message:      fmt.Sprintf("Copied %d rows from %s", rowsCopied, tablePair.SourceTable),
+			Message:      progress.Message,
 			Success:      true,
 			Status:       commonv1.Status_STATUS_PENDING,
 			Phase:        "copying_data",
 			RowsCopied:   totalRowsCopied,
-			CurrentTable: tablePair.SourceTable,
+			CurrentTable: progress.SourceTable,
 		}); err != nil {
 			s.engine.logger.Warnf("Failed to send progress update: %v", err)
 		}
@@ -554,6 +690,21 @@ func (s *Server) setupCDCReplication(ctx context.Context, rel *relationship.Rela
 		return "", fmt.Errorf("failed to marshal mapping rules: %v", err)
 	}
 
+	// Pass the mapping's conflict keys, so that replayed changes are upserted on the target,
+	// and its row filter, so that only the changes of matching rows are replicated
+	var conflictKeysJSON []byte
+	mappingService := mapping.NewService(s.engine.db, s.engine.logger)
+	relMapping, err := mappingService.GetByID(ctx, rel.MappingID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mapping: %v", err)
+	}
+	if conflictKeys := relMapping.ConflictKeys(); len(conflictKeys) > 0 {
+		conflictKeysJSON, err = json.Marshal(conflictKeys)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal conflict keys: %v", err)
+		}
+	}
+
 	// Start CDC replication via Anchor
 	startCDCReq := &anchorv1.StartCDCReplicationRequest{
 		TenantId:            rel.TenantID,
@@ -564,6 +715,8 @@ func (s *Server) setupCDCReplication(ctx context.Context, rel *relationship.Rela
 		ReplicationSourceId: replicationSourceID,
 		TableNames:          tableNames,
 		MappingRules:        mappingRulesJSON,
+		ConflictKeys:        conflictKeysJSON,
+		RowFilter:           relMapping.RowFilter(),
 	}
 
 	cdcResp, err := anchorClient.StartCDCReplication(ctx, startCDCReq)
@@ -715,6 +715,16 @@ func (s *Server) CreateCommitByAnchor(ctx context.Context, req *corev1.CreateCom
 		return nil, status.Errorf(codes.Internal, "failed to create commit: %v", err)
 	}
 
+	// Quarantine the mapping rules referencing objects the schema change dropped
+	databaseID, err := branch.NewService(s.engine.db, s.engine.logger).GetConnectedDatabaseID(ctx, req.BranchId)
+	if err != nil {
+		s.engine.logger.Warnf("Failed to get the database of branch %s: %v", req.BranchId, err)
+	} else if databaseID != "" {
+		if err := s.detectBrokenMappingRules(ctx, databaseID, req.SchemaStructure); err != nil {
+			s.engine.logger.Warnf("Failed to check the mapping rules of database %s: %v", databaseID, err)
+		}
+	}
+
 	return &corev1.CreateCommitByAnchorResponse{
 		Message:  "Commit created successfully",
 		Success:  createdCommit.Success,
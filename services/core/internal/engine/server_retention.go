@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	"github.com/redbco/redb-open/services/core/internal/services/retention"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListRetentionPolicies(ctx context.Context, req *corev1.ListRetentionPoliciesRequest) (*corev1.ListRetentionPoliciesResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	policies, err := retention.NewService(s.engine.db, s.engine.logger).List(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list retention policies: %v", err)
+	}
+
+	protoPolicies := make([]*corev1.RetentionPolicy, 0, len(policies))
+	for _, p := range policies {
+		protoPolicies = append(protoPolicies, retentionPolicyToProto(p))
+	}
+
+	return &corev1.ListRetentionPoliciesResponse{
+		Policies: protoPolicies,
+		Message:  fmt.Sprintf("Found %d retention policies", len(protoPolicies)),
+		Success:  true,
+		Status:   commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) SetRetentionPolicy(ctx context.Context, req *corev1.SetRetentionPolicyRequest) (*corev1.SetRetentionPolicyResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if !retention.ValidResourceType(req.ResourceType) {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "unknown resource type %q", req.ResourceType)
+	}
+	if req.KeepDays < 0 || req.KeepVersions < 0 {
+		s.engine.IncrementErrors()
+		return nil, status.Error(codes.InvalidArgument, "keep_days and keep_versions cannot be negative")
+	}
+
+	policy, err := retention.NewService(s.engine.db, s.engine.logger).Set(ctx, req.TenantId, req.ResourceType, int(req.KeepDays), int(req.KeepVersions))
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to set retention policy: %v", err)
+	}
+
+	return &corev1.SetRetentionPolicyResponse{
+		Policy:  retentionPolicyToProto(policy),
+		Message: "Retention policy set successfully",
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) DeleteRetentionPolicy(ctx context.Context, req *corev1.DeleteRetentionPolicyRequest) (*corev1.DeleteRetentionPolicyResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	if !retention.ValidResourceType(req.ResourceType) {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "unknown resource type %q", req.ResourceType)
+	}
+
+	retentionService := retention.NewService(s.engine.db, s.engine.logger)
+	if err := retentionService.Delete(ctx, req.TenantId, req.ResourceType); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to delete retention policy: %v", err)
+	}
+
+	policy, err := retentionService.Get(ctx, req.TenantId, req.ResourceType)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get retention policy: %v", err)
+	}
+
+	return &corev1.DeleteRetentionPolicyResponse{
+		Policy:  retentionPolicyToProto(policy),
+		Message: "Retention policy deleted successfully",
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func (s *Server) PreviewRetention(ctx context.Context, req *corev1.PreviewRetentionRequest) (*corev1.PreviewRetentionResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+	s.engine.IncrementRequestsProcessed()
+
+	purges, err := retention.NewService(s.engine.db, s.engine.logger).Preview(ctx, req.TenantId)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to preview retention: %v", err)
+	}
+
+	protoPurges := make([]*corev1.RetentionPurge, 0, len(purges))
+	var items int64
+	for _, p := range purges {
+		purge := &corev1.RetentionPurge{
+			ResourceType: p.ResourceType,
+			Items:        p.Items,
+		}
+		if p.Oldest != nil {
+			purge.Oldest = p.Oldest.UTC().Format(time.RFC3339)
+		}
+		protoPurges = append(protoPurges, purge)
+		items += p.Items
+	}
+
+	var nextRun string
+	if next := s.engine.NextJanitorRun(); !next.IsZero() {
+		nextRun = next.UTC().Format(time.RFC3339)
+	}
+
+	return &corev1.PreviewRetentionResponse{
+		Purges:  protoPurges,
+		NextRun: nextRun,
+		Message: fmt.Sprintf("%d items are due for purging", items),
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+func retentionPolicyToProto(p *retention.Policy) *corev1.RetentionPolicy {
+	policy := &corev1.RetentionPolicy{
+		TenantId:     p.TenantID,
+		ResourceType: p.ResourceType,
+		KeepDays:     int32(p.KeepDays),
+		KeepVersions: int32(p.KeepVersions),
+		IsDefault:    p.IsDefault,
+	}
+	if !p.Updated.IsZero() {
+		policy.Updated = p.Updated.Format(time.RFC3339)
+	}
+	return policy
+}
@@ -4,13 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	anchorv1 "github.com/redbco/redb-open/api/proto/anchor/v1"
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
-	streamv1 "github.com/redbco/redb-open/api/proto/stream/v1"
 	"github.com/redbco/redb-open/services/core/internal/services/stream"
 	"github.com/redbco/redb-open/services/core/internal/services/workspace"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -126,39 +124,42 @@ func (s *Server) ConnectStream(ctx context.Context, req *corev1.ConnectStreamReq
 		}, nil
 	}
 
-	// Connect to stream service via gRPC
-	streamAddr := s.engine.getServiceAddress("stream")
-
-	if s.engine.logger != nil {
-		s.engine.logger.Infof("Connecting to stream service at: %s", streamAddr)
-	}
-
-	streamConn, err := grpc.Dial(streamAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
+	// Attach the stream platform to the anchor, which discovers its topics
+	anchorClient := s.engine.GetAnchorClient()
+	if anchorClient == nil {
 		s.engine.IncrementErrors()
 		return &corev1.ConnectStreamResponse{
 			Success: false,
-			Message: fmt.Sprintf("Failed to connect to stream service at %s: %v", streamAddr, err),
+			Message: "Anchor service is not available",
 			Status:  commonv1.Status_STATUS_ERROR,
 		}, nil
 	}
-	defer streamConn.Close()
-
-	streamClient := streamv1.NewStreamServiceClient(streamConn)
-	connectResp, err := streamClient.ConnectStream(ctx, &streamv1.ConnectStreamRequest{
+	connectResp, err := anchorClient.ConnectStream(ctx, &anchorv1.ConnectStreamRequest{
 		TenantId: req.TenantId,
 		StreamId: st.ID,
 	})
-
-	if err != nil || !connectResp.Success {
+	if err != nil {
 		s.engine.IncrementErrors()
 		return &corev1.ConnectStreamResponse{
 			Success: false,
-			Message: fmt.Sprintf("Failed to connect stream: %v", err),
+			Message: fmt.Sprintf("Failed to connect stream via anchor service: %v", err),
+			Status:  commonv1.Status_STATUS_ERROR,
+		}, nil
+	}
+	if !connectResp.Success {
+		s.engine.IncrementErrors()
+		return &corev1.ConnectStreamResponse{
+			Success: false,
+			Message: connectResp.Message,
 			Status:  commonv1.Status_STATUS_ERROR,
 		}, nil
 	}
 
+	// Reload the stream for the status and topics recorded by the anchor
+	if connected, err := streamService.Get(ctx, req.TenantId, req.StreamName); err == nil {
+		st = connected
+	}
+
 	return &corev1.ConnectStreamResponse{
 		Stream:  streamToProto(st),
 		Success: true,
@@ -183,17 +184,33 @@ func (s *Server) ReconnectStream(ctx context.Context, req *corev1.ReconnectStrea
 		}, nil
 	}
 
-	// Reconnect via stream service
-	streamAddr := s.engine.getServiceAddress("stream")
-
-	streamConn, err := grpc.Dial(streamAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err == nil {
-		defer streamConn.Close()
-		streamClient := streamv1.NewStreamServiceClient(streamConn)
-		streamClient.ConnectStream(ctx, &streamv1.ConnectStreamRequest{
-			TenantId: req.TenantId,
-			StreamId: st.ID,
-		})
+	anchorClient := s.engine.GetAnchorClient()
+	if anchorClient == nil {
+		s.engine.IncrementErrors()
+		return &corev1.ReconnectStreamResponse{
+			Success: false,
+			Message: "Anchor service is not available",
+			Status:  commonv1.Status_STATUS_ERROR,
+		}, nil
+	}
+	connectResp, err := anchorClient.ConnectStream(ctx, &anchorv1.ConnectStreamRequest{
+		TenantId: req.TenantId,
+		StreamId: st.ID,
+	})
+	if err != nil || !connectResp.Success {
+		message := fmt.Sprintf("Failed to reconnect stream: %v", err)
+		if err == nil {
+			message = connectResp.Message
+		}
+		s.engine.IncrementErrors()
+		return &corev1.ReconnectStreamResponse{
+			Success: false,
+			Message: message,
+			Status:  commonv1.Status_STATUS_ERROR,
+		}, nil
+	}
+	if connected, err := streamService.Get(ctx, req.TenantId, req.StreamName); err == nil {
+		st = connected
 	}
 
 	return &corev1.ReconnectStreamResponse{
@@ -246,16 +263,14 @@ func (s *Server) DisconnectStream(ctx context.Context, req *corev1.DisconnectStr
 		}, nil
 	}
 
-	// Disconnect via stream service
-	streamAddr := s.engine.getServiceAddress("stream")
-	streamConn, err := grpc.Dial(streamAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err == nil {
-		defer streamConn.Close()
-		streamClient := streamv1.NewStreamServiceClient(streamConn)
-		streamClient.DisconnectStream(ctx, &streamv1.DisconnectStreamRequest{
+	// Detach the stream platform from the anchor
+	if anchorClient := s.engine.GetAnchorClient(); anchorClient != nil {
+		if _, err := anchorClient.DisconnectStream(ctx, &anchorv1.DisconnectStreamRequest{
 			TenantId: req.TenantId,
 			StreamId: st.ID,
-		})
+		}); err != nil && s.engine.logger != nil {
+			s.engine.logger.Warnf("Failed to disconnect stream %s via anchor service: %v", st.Name, err)
+		}
 	}
 
 	// Delete if requested
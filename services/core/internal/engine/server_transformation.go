@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"fmt"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
@@ -27,7 +28,7 @@ func (s *Server) ListTransformations(ctx context.Context, req *corev1.ListTransf
 			return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
 		}
 
-		listReq := &transformationv1.ListTransformationsRequest{}
+		listReq := &transformationv1.ListTransformationsRequest{TenantId: req.TenantId}
 		listResp, err := transformationClient.ListTransformations(ctx, listReq)
 		if err != nil {
 			s.engine.IncrementErrors()
@@ -48,6 +49,11 @@ func (s *Server) ListTransformations(ctx context.Context, req *corev1.ListTransf
 				OwnerId:     "",
 				IsBuiltin:   true,
 			}
+			// Plugins are listed with the built-in transformations, as they are used the same way
+			if tm.PluginKind != transformationv1.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_UNSPECIFIED {
+				protoTransformations[i].TenantId = req.TenantId
+				protoTransformations[i].IsBuiltin = false
+			}
 		}
 
 		return &corev1.ListTransformationsResponse{
@@ -180,3 +186,147 @@ func (s *Server) DeleteTransformation(ctx context.Context, req *corev1.DeleteTra
 		Status:  commonv1.Status_STATUS_SUCCESS,
 	}, nil
 }
+
+// RegisterTransformationPlugin registers a user-defined transformation with the transformation
+// service, which calls its endpoint for each value the transformation is applied to
+func (s *Server) RegisterTransformationPlugin(ctx context.Context, req *corev1.RegisterTransformationPluginRequest) (*corev1.RegisterTransformationPluginResponse, error) {
+	defer s.trackOperation()()
+
+	kind, err := transformationPluginKindFromString(req.PluginKind)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
+	}
+
+	resp, err := transformationClient.RegisterTransformationPlugin(ctx, &transformationv1.RegisterTransformationPluginRequest{
+		TenantId:       req.TenantId,
+		Name:           req.PluginName,
+		Description:    req.PluginDescription,
+		Kind:           kind,
+		Endpoint:       req.Endpoint,
+		WasmModule:     req.WasmModule,
+		Type:           req.TransformationType,
+		InputType:      req.InputType,
+		OutputType:     req.OutputType,
+		TimeoutSeconds: req.TimeoutSeconds,
+		OwnerId:        req.OwnerId,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to register transformation plugin: %v", err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%s", resp.StatusMessage)
+	}
+
+	return &corev1.RegisterTransformationPluginResponse{
+		Message: "Transformation plugin registered successfully",
+		Success: true,
+		Plugin:  transformationPluginToProto(resp.Plugin),
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ListTransformationPlugins lists the transformation plugins of a tenant
+func (s *Server) ListTransformationPlugins(ctx context.Context, req *corev1.ListTransformationPluginsRequest) (*corev1.ListTransformationPluginsResponse, error) {
+	defer s.trackOperation()()
+
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
+	}
+
+	resp, err := transformationClient.ListTransformationPlugins(ctx, &transformationv1.ListTransformationPluginsRequest{
+		TenantId: req.TenantId,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to list transformation plugins: %v", err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.InvalidArgument, "%s", resp.StatusMessage)
+	}
+
+	plugins := make([]*corev1.TransformationPlugin, len(resp.Plugins))
+	for i, plugin := range resp.Plugins {
+		plugins[i] = transformationPluginToProto(plugin)
+	}
+
+	return &corev1.ListTransformationPluginsResponse{
+		Plugins: plugins,
+	}, nil
+}
+
+// DeleteTransformationPlugin removes a transformation plugin of a tenant. Mapping rules using it
+// fail until a plugin with its name is registered again.
+func (s *Server) DeleteTransformationPlugin(ctx context.Context, req *corev1.DeleteTransformationPluginRequest) (*corev1.DeleteTransformationPluginResponse, error) {
+	defer s.trackOperation()()
+
+	transformationClient, err := s.getTransformationClient()
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to transformation service: %v", err)
+	}
+
+	resp, err := transformationClient.DeleteTransformationPlugin(ctx, &transformationv1.DeleteTransformationPluginRequest{
+		TenantId: req.TenantId,
+		Name:     req.PluginName,
+	})
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to delete transformation plugin: %v", err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "%s", resp.StatusMessage)
+	}
+
+	return &corev1.DeleteTransformationPluginResponse{
+		Message: "Transformation plugin deleted successfully",
+		Success: true,
+		Status:  commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// transformationPluginKindFromString converts the kind of a plugin as given by users
+func transformationPluginKindFromString(kind string) (transformationv1.TransformationPluginKind, error) {
+	switch kind {
+	case "grpc", "":
+		return transformationv1.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC, nil
+	case "wasm":
+		return transformationv1.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_WASM, nil
+	default:
+		return transformationv1.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_UNSPECIFIED, fmt.Errorf("invalid plugin kind '%s': expected grpc or wasm", kind)
+	}
+}
+
+// transformationPluginToProto converts a plugin of the transformation service to protobuf
+func transformationPluginToProto(plugin *transformationv1.TransformationPluginInfo) *corev1.TransformationPlugin {
+	kind := "grpc"
+	if plugin.Kind == transformationv1.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_WASM {
+		kind = "wasm"
+	}
+	return &corev1.TransformationPlugin{
+		TenantId:           plugin.TenantId,
+		PluginId:           plugin.PluginId,
+		PluginName:         plugin.Name,
+		PluginDescription:  plugin.Description,
+		PluginKind:         kind,
+		Endpoint:           plugin.Endpoint,
+		TransformationType: plugin.Type,
+		InputType:          plugin.InputType,
+		OutputType:         plugin.OutputType,
+		Cardinality:        plugin.Cardinality,
+		TimeoutSeconds:     plugin.TimeoutSeconds,
+		OwnerId:            plugin.OwnerId,
+	}
+}
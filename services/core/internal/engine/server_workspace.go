@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"time"
 
 	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
 	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
@@ -191,3 +192,43 @@ func (s *Server) DeleteWorkspace(ctx context.Context, req *corev1.DeleteWorkspac
 		Status:  commonv1.Status_STATUS_SUCCESS,
 	}, nil
 }
+
+func (s *Server) GetWorkspaceHealth(ctx context.Context, req *corev1.GetWorkspaceHealthRequest) (*corev1.GetWorkspaceHealthResponse, error) {
+	defer s.trackOperation()()
+
+	// Get workspace service
+	workspaceService := workspace.NewService(s.engine.db, s.engine.logger)
+
+	// Verify workspace exists and belongs to tenant
+	if _, err := workspaceService.Get(ctx, req.TenantId, req.WorkspaceName); err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.NotFound, "workspace not found: %v", err)
+	}
+
+	health, err := workspaceService.Health(ctx, req.TenantId, req.WorkspaceName)
+	if err != nil {
+		s.engine.IncrementErrors()
+		return nil, status.Errorf(codes.Internal, "failed to get workspace health: %v", err)
+	}
+
+	checks := make([]*corev1.WorkspaceHealthCheck, len(health.Checks))
+	for i, check := range health.Checks {
+		checks[i] = &corev1.WorkspaceHealthCheck{
+			Name:    check.Name,
+			Status:  check.Status,
+			Score:   int32(check.Score),
+			Weight:  int32(check.Weight),
+			Message: check.Message,
+			Items:   check.Items,
+		}
+	}
+
+	return &corev1.GetWorkspaceHealthResponse{
+		WorkspaceName: health.WorkspaceName,
+		Score:         int32(health.Score),
+		HealthStatus:  health.Status,
+		Ready:         health.Ready,
+		Checks:        checks,
+		Computed:      health.Computed.Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,404 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redbco/redb-open/pkg/blobstore"
+	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// Artifact kinds
+const (
+	KindSchemaSnapshot = "schema_snapshot"
+	KindDeadLetter     = "dead_letter"
+	KindExport         = "export"
+	KindDiagnostics    = "diagnostics"
+)
+
+// Kinds lists the artifact kinds
+var Kinds = []string{KindSchemaSnapshot, KindDeadLetter, KindExport, KindDiagnostics}
+
+// DefaultInlineMaxBytes is the largest schema snapshot kept inline in its commit
+const DefaultInlineMaxBytes = 1 << 20
+
+// Keys of the reference stored in place of an offloaded schema snapshot: the artifact ID and the
+// SHA-256 of the snapshot, which lets the anchor detect schema changes without the snapshot
+const (
+	referenceKey     = "$artifact"
+	referenceHashKey = "$sha256"
+)
+
+// ErrStoreNotConfigured is returned when no blob store is configured
+var ErrStoreNotConfigured = errors.New("blob store is not configured")
+
+var (
+	mu             sync.RWMutex
+	store          blobstore.Store
+	retention      map[string]time.Duration
+	inlineMaxBytes = DefaultInlineMaxBytes
+)
+
+// Configure sets the blob store artifacts are kept in, how long artifacts of each kind are
+// retained (zero keeps them forever), and the size above which schema snapshots are offloaded
+func Configure(s blobstore.Store, kindRetention map[string]time.Duration, maxInlineBytes int) {
+	mu.Lock()
+	defer mu.Unlock()
+	store = s
+	retention = kindRetention
+	if maxInlineBytes > 0 {
+		inlineMaxBytes = maxInlineBytes
+	} else {
+		inlineMaxBytes = DefaultInlineMaxBytes
+	}
+}
+
+// Store returns the configured blob store, or nil if none is configured
+func Store() blobstore.Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return store
+}
+
+// Service handles artifact-related operations
+type Service struct {
+	db     *database.PostgreSQL
+	logger *logger.Logger
+}
+
+// NewService creates a new artifact service
+func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
+	return &Service{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Artifact represents an artifact in the system
+type Artifact struct {
+	ID          string
+	TenantID    string
+	WorkspaceID string
+	Kind        string
+	Name        string
+	BlobKey     string
+	ContentType string
+	SizeBytes   int64
+	Created     time.Time
+}
+
+// ValidKind reports whether a kind is a known artifact kind
+func ValidKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Put stores the data of an artifact in the blob store and records it
+func (s *Service) Put(ctx context.Context, tenantID, workspaceID, kind, name, contentType string, data []byte) (*Artifact, error) {
+	blobs := Store()
+	if blobs == nil {
+		return nil, ErrStoreNotConfigured
+	}
+	if !ValidKind(kind) {
+		return nil, fmt.Errorf("unknown artifact kind: %s", kind)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	key := path.Join(kind, tenantID, workspaceID, fmt.Sprintf("%d-%s", time.Now().UnixNano(), blobName(name)))
+	if err := blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	artifact := Artifact{
+		TenantID:    tenantID,
+		WorkspaceID: workspaceID,
+		Kind:        kind,
+		Name:        name,
+		BlobKey:     key,
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+	}
+	err := s.db.Pool().QueryRow(ctx, `
+		INSERT INTO artifacts (tenant_id, workspace_id, artifact_kind, artifact_name, blob_key, content_type, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING artifact_id, created
+	`, tenantID, workspaceID, kind, name, key, contentType, artifact.SizeBytes).Scan(&artifact.ID, &artifact.Created)
+	if err != nil {
+		if deleteErr := blobs.Delete(ctx, key); deleteErr != nil {
+			s.logger.Warnf("Failed to delete blob %s of unrecorded artifact: %v", key, deleteErr)
+		}
+		s.logger.Errorf("Failed to record artifact: %v", err)
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+// Get retrieves an artifact by ID
+func (s *Service) Get(ctx context.Context, tenantID, workspaceID, artifactID string) (*Artifact, error) {
+	query := `
+		SELECT artifact_id, tenant_id, workspace_id, artifact_kind, artifact_name, blob_key,
+		       COALESCE(content_type, ''), COALESCE(size_bytes, 0), created
+		FROM artifacts
+		WHERE tenant_id = $1 AND workspace_id = $2 AND artifact_id = $3
+	`
+
+	var artifact Artifact
+	err := s.db.Pool().QueryRow(ctx, query, tenantID, workspaceID, artifactID).Scan(
+		&artifact.ID,
+		&artifact.TenantID,
+		&artifact.WorkspaceID,
+		&artifact.Kind,
+		&artifact.Name,
+		&artifact.BlobKey,
+		&artifact.ContentType,
+		&artifact.SizeBytes,
+		&artifact.Created,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("artifact not found")
+		}
+		s.logger.Errorf("Failed to get artifact: %v", err)
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+// List retrieves the artifacts of a workspace, optionally of one kind
+func (s *Service) List(ctx context.Context, tenantID, workspaceID, kind string) ([]*Artifact, error) {
+	query := `
+		SELECT artifact_id, tenant_id, workspace_id, artifact_kind, artifact_name, blob_key,
+		       COALESCE(content_type, ''), COALESCE(size_bytes, 0), created
+		FROM artifacts
+		WHERE tenant_id = $1 AND workspace_id = $2 AND ($3 = '' OR artifact_kind = $3)
+		ORDER BY created DESC
+	`
+
+	rows, err := s.db.Pool().Query(ctx, query, tenantID, workspaceID, kind)
+	if err != nil {
+		s.logger.Errorf("Failed to list artifacts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var artifacts []*Artifact
+	for rows.Next() {
+		var artifact Artifact
+		err := rows.Scan(
+			&artifact.ID,
+			&artifact.TenantID,
+			&artifact.WorkspaceID,
+			&artifact.Kind,
+			&artifact.Name,
+			&artifact.BlobKey,
+			&artifact.ContentType,
+			&artifact.SizeBytes,
+			&artifact.Created,
+		)
+		if err != nil {
+			s.logger.Errorf("Failed to scan artifact: %v", err)
+			return nil, err
+		}
+		artifacts = append(artifacts, &artifact)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Errorf("Error after scanning artifacts: %v", err)
+		return nil, err
+	}
+
+	return artifacts, nil
+}
+
+// Read returns the data of an artifact
+func (s *Service) Read(ctx context.Context, artifact *Artifact) ([]byte, error) {
+	blobs := Store()
+	if blobs == nil {
+		return nil, ErrStoreNotConfigured
+	}
+	reader, _, err := blobs.Get(ctx, artifact.BlobKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact %s: %w", artifact.ID, err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// SignedURL returns a URL the artifact can be downloaded from until the expiry has passed
+func (s *Service) SignedURL(ctx context.Context, artifact *Artifact, expiry time.Duration) (string, error) {
+	blobs := Store()
+	if blobs == nil {
+		return "", ErrStoreNotConfigured
+	}
+	return blobs.SignedURL(ctx, artifact.BlobKey, expiry)
+}
+
+// Delete removes an artifact and its blob
+func (s *Service) Delete(ctx context.Context, tenantID, workspaceID, artifactID string) error {
+	artifact, err := s.Get(ctx, tenantID, workspaceID, artifactID)
+	if err != nil {
+		return err
+	}
+	if artifact.Kind == KindSchemaSnapshot {
+		var referenced bool
+		err := s.db.Pool().QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM commits WHERE schema_structure->>'$artifact' = $1)
+		`, artifact.ID).Scan(&referenced)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return errors.New("artifact is the schema snapshot of a commit")
+		}
+	}
+	return s.delete(ctx, artifact)
+}
+
+func (s *Service) delete(ctx context.Context, artifact *Artifact) error {
+	if blobs := Store(); blobs != nil {
+		if err := blobs.Delete(ctx, artifact.BlobKey); err != nil {
+			return err
+		}
+	}
+	_, err := s.db.Pool().Exec(ctx, "DELETE FROM artifacts WHERE artifact_id = $1", artifact.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to delete artifact: %v", err)
+	}
+	return err
+}
+
+// ApplyRetention deletes the artifacts older than the retention of their kind. Schema snapshots
+// live as long as their commits and are never expired.
+func (s *Service) ApplyRetention(ctx context.Context) (int, error) {
+	mu.RLock()
+	policies := make(map[string]time.Duration, len(retention))
+	for kind, keep := range retention {
+		policies[kind] = keep
+	}
+	mu.RUnlock()
+
+	deleted := 0
+	for kind, keep := range policies {
+		if kind == KindSchemaSnapshot || keep <= 0 {
+			continue
+		}
+		rows, err := s.db.Pool().Query(ctx, `
+			SELECT artifact_id, tenant_id, workspace_id, artifact_kind, artifact_name, blob_key
+			FROM artifacts
+			WHERE artifact_kind = $1 AND created < $2
+		`, kind, time.Now().Add(-keep))
+		if err != nil {
+			return deleted, err
+		}
+		var expired []*Artifact
+		for rows.Next() {
+			var artifact Artifact
+			if err := rows.Scan(&artifact.ID, &artifact.TenantID, &artifact.WorkspaceID, &artifact.Kind, &artifact.Name, &artifact.BlobKey); err != nil {
+				rows.Close()
+				return deleted, err
+			}
+			expired = append(expired, &artifact)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return deleted, err
+		}
+
+		for _, artifact := range expired {
+			if err := s.delete(ctx, artifact); err != nil {
+				s.logger.Warnf("Failed to delete expired artifact %s: %v", artifact.ID, err)
+				continue
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// OffloadSchema stores a schema structure larger than the inline limit as a schema snapshot
+// artifact, returning the reference to keep in the commit instead. Without a blob store, or
+// for small schemas, the structure is returned unchanged.
+func (s *Service) OffloadSchema(ctx context.Context, tenantID, workspaceID, name string, structure map[string]interface{}) (map[string]interface{}, error) {
+	if Store() == nil {
+		return structure, nil
+	}
+	data, err := json.Marshal(structure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema structure: %w", err)
+	}
+	mu.RLock()
+	limit := inlineMaxBytes
+	mu.RUnlock()
+	if len(data) <= limit {
+		return structure, nil
+	}
+
+	artifact, err := s.Put(ctx, tenantID, workspaceID, KindSchemaSnapshot, name, "application/json", data)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infof("Stored schema snapshot of %d bytes as artifact %s", len(data), artifact.ID)
+	hash := sha256.Sum256(data)
+	return map[string]interface{}{
+		referenceKey:     artifact.ID,
+		referenceHashKey: hex.EncodeToString(hash[:]),
+	}, nil
+}
+
+// ResolveSchema replaces a schema snapshot reference with the schema structure it points to
+func (s *Service) ResolveSchema(ctx context.Context, tenantID, workspaceID string, structure map[string]interface{}) (map[string]interface{}, error) {
+	artifactID, ok := structure[referenceKey].(string)
+	if !ok || len(structure) > 2 {
+		return structure, nil
+	}
+	artifact, err := s.Get(ctx, tenantID, workspaceID, artifactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schema snapshot %s: %w", artifactID, err)
+	}
+	data, err := s.Read(ctx, artifact)
+	if err != nil {
+		return nil, err
+	}
+	var resolved map[string]interface{}
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to decode schema snapshot %s: %w", artifactID, err)
+	}
+	return resolved, nil
+}
+
+// blobName turns an artifact name into a safe key segment
+func blobName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	name = strings.Trim(name, ".")
+	if name == "" {
+		return "artifact"
+	}
+	return name
+}
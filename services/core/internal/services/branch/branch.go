@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
 )
 
 // Service handles branch-related operations
@@ -172,6 +173,26 @@ func (s *Service) GetDatabaseIDByName(ctx context.Context, tenantID, workspaceID
 	return databaseID, nil
 }
 
+// GetConnectedDatabaseID returns the ID of the database a branch is connected to, or an empty
+// string if it is not connected
+func (s *Service) GetConnectedDatabaseID(ctx context.Context, branchID string) (string, error) {
+	query := `
+		SELECT COALESCE(connected_database_id::text, '')
+		FROM branches
+		WHERE branch_id = $1 AND connected_to_database
+	`
+
+	var databaseID string
+	err := s.db.Pool().QueryRow(ctx, query, branchID).Scan(&databaseID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return databaseID, nil
+}
+
 // AttachToDatabase attaches a branch to a database
 func (s *Service) AttachToDatabase(ctx context.Context, tenantID, workspaceID, repoID, branchName, databaseName string) (*Branch, error) {
 	s.logger.Infof("Attaching branch %s to database %s", branchName, databaseName)
@@ -534,6 +555,15 @@ func (s *Service) GetCommitsForBranch(ctx context.Context, tenantID, workspaceID
 		return nil, err
 	}
 
+	artifacts := artifact.NewService(s.db, s.logger)
+	for _, commit := range commits {
+		commit.SchemaStructure, err = artifacts.ResolveSchema(ctx, commit.TenantID, commit.WorkspaceID, commit.SchemaStructure)
+		if err != nil {
+			s.logger.Errorf("Failed to resolve schema of commit %d: %v", commit.ID, err)
+			return nil, err
+		}
+	}
+
 	return commits, nil
 }
 
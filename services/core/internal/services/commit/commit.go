@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
 )
 
 // Service handles commit-related operations
@@ -83,6 +84,10 @@ func (s *Service) Get(ctx context.Context, tenantID, workspaceID, repoID, branch
 		return nil, err
 	}
 
+	if err := s.resolveSchema(ctx, &commit); err != nil {
+		return nil, err
+	}
+
 	return &commit, nil
 }
 
@@ -121,6 +126,10 @@ func (s *Service) GetByCode(ctx context.Context, tenantID, workspaceID, repoID,
 		return nil, err
 	}
 
+	if err := s.resolveSchema(ctx, &commit); err != nil {
+		return nil, err
+	}
+
 	return &commit, nil
 }
 
@@ -268,6 +277,12 @@ func (s *Service) List(ctx context.Context, tenantID, workspaceID, repoID, branc
 		return nil, err
 	}
 
+	for _, commit := range commits {
+		if err := s.resolveSchema(ctx, commit); err != nil {
+			return nil, err
+		}
+	}
+
 	return commits, nil
 }
 
@@ -296,6 +311,12 @@ func (s *Service) CreateCommitByAnchor(ctx context.Context, branchID string, com
 		return nil, fmt.Errorf("failed to get branch details: %w", err)
 	}
 
+	// Keep large schemas in the blob store rather than in the commit
+	schemaStructure, err = artifact.NewService(s.db, s.logger).OffloadSchema(ctx, tenantID, workspaceID, "commit-"+branchID+".json", schemaStructure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store schema snapshot: %w", err)
+	}
+
 	// Set all existing commits in this branch to not be head
 	_, err = tx.Exec(ctx, `
 		UPDATE commits 
@@ -329,3 +350,14 @@ func (s *Service) CreateCommitByAnchor(ctx context.Context, branchID string, com
 		Success:  true,
 	}, nil
 }
+
+// resolveSchema loads the schema structure of a commit whose snapshot is kept in the blob store
+func (s *Service) resolveSchema(ctx context.Context, commit *Commit) error {
+	structure, err := artifact.NewService(s.db, s.logger).ResolveSchema(ctx, commit.TenantID, commit.WorkspaceID, commit.SchemaStructure)
+	if err != nil {
+		s.logger.Errorf("Failed to resolve schema of commit %d: %v", commit.ID, err)
+		return err
+	}
+	commit.SchemaStructure = structure
+	return nil
+}
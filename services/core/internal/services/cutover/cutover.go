@@ -0,0 +1,297 @@
+package cutover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// Defaults of the options of a cutover
+const (
+	DefaultMaxRetries   = 3
+	DefaultDrainTimeout = 10 * time.Minute
+)
+
+// Cutover moves the applications using a connection from the source to the target database of
+// a relationship, through its steps
+type Cutover struct {
+	ID               string
+	TenantID         string
+	WorkspaceID      string
+	Name             string
+	Description      string
+	RelationshipID   string
+	RelationshipName string
+	SourceDatabaseID string
+	TargetDatabaseID string
+	ConnectionName   string
+	StopWritesHook   string
+	UnpauseHook      string
+	Gates            []string
+	MaxRetries       int
+	DrainTimeout     time.Duration
+	State            string
+	CurrentStep      string
+	Steps            []*Step
+	OwnerID          string
+	Created          time.Time
+	Updated          time.Time
+}
+
+// ApplicationConnection is the connection info of applications: the database they use
+type ApplicationConnection struct {
+	TenantID              string
+	WorkspaceID           string
+	Name                  string
+	DatabaseID            string
+	DatabaseName          string
+	DatabaseType          string
+	Host                  string
+	Port                  int32
+	DatabaseDBName        string
+	PreviousDatabaseName  string
+	SwitchedByCutoverName string
+	Updated               time.Time
+}
+
+// Service handles cutovers and application connections
+type Service struct {
+	db     *database.PostgreSQL
+	logger *logger.Logger
+}
+
+// NewService creates a new cutover service
+func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
+	return &Service{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const cutoverColumns = `
+	c.cutover_id, c.tenant_id, c.workspace_id, c.cutover_name, COALESCE(c.cutover_description, ''),
+	c.relationship_id, r.relationship_name, r.relationship_source_database_id, r.relationship_target_database_id,
+	c.connection_name, c.stop_writes_hook, c.unpause_hook, c.gates, c.max_retries, c.drain_timeout_seconds,
+	c.cutover_state, c.current_step, c.cutover_steps, c.owner_id, c.created, c.updated`
+
+const cutoverFrom = `
+	FROM cutovers c
+	JOIN relationships r ON r.relationship_id = c.relationship_id`
+
+func scanCutover(row pgx.Row) (*Cutover, error) {
+	var c Cutover
+	var drainSeconds int
+	var steps []byte
+	err := row.Scan(
+		&c.ID, &c.TenantID, &c.WorkspaceID, &c.Name, &c.Description,
+		&c.RelationshipID, &c.RelationshipName, &c.SourceDatabaseID, &c.TargetDatabaseID,
+		&c.ConnectionName, &c.StopWritesHook, &c.UnpauseHook, &c.Gates, &c.MaxRetries, &drainSeconds,
+		&c.State, &c.CurrentStep, &steps, &c.OwnerID, &c.Created, &c.Updated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.DrainTimeout = time.Duration(drainSeconds) * time.Second
+	if err := json.Unmarshal(steps, &c.Steps); err != nil {
+		return nil, fmt.Errorf("invalid steps of cutover %s: %w", c.Name, err)
+	}
+	return &c, nil
+}
+
+// Create adds a pending cutover of a relationship. The application connection is created,
+// pointing to the source database of the relationship, if it does not exist; an existing one
+// must point to the source database.
+func (s *Service) Create(ctx context.Context, c *Cutover) (*Cutover, error) {
+	if err := ValidateGates(c.Gates); err != nil {
+		return nil, err
+	}
+	if c.MaxRetries < 0 {
+		return nil, errors.New("max retries cannot be negative")
+	}
+	if c.DrainTimeout <= 0 {
+		return nil, errors.New("drain timeout must be positive")
+	}
+	if c.Gates == nil {
+		c.Gates = []string{}
+	}
+
+	steps, err := json.Marshal(NewSteps())
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var connectionDatabaseID string
+	err = tx.QueryRow(ctx, `
+		SELECT database_id FROM application_connections WHERE workspace_id = $1 AND connection_name = $2
+	`, c.WorkspaceID, c.ConnectionName).Scan(&connectionDatabaseID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		_, err = tx.Exec(ctx, `
+			INSERT INTO application_connections (tenant_id, workspace_id, connection_name, database_id)
+			SELECT tenant_id, workspace_id, $3, relationship_source_database_id
+			FROM relationships WHERE workspace_id = $1 AND relationship_id = $2
+		`, c.WorkspaceID, c.RelationshipID, c.ConnectionName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create application connection: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get application connection: %w", err)
+	}
+
+	var id string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO cutovers (tenant_id, workspace_id, cutover_name, cutover_description, relationship_id,
+		                      connection_name, stop_writes_hook, unpause_hook, gates, max_retries,
+		                      drain_timeout_seconds, cutover_steps, owner_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING cutover_id
+	`, c.TenantID, c.WorkspaceID, c.Name, c.Description, c.RelationshipID, c.ConnectionName,
+		c.StopWritesHook, c.UnpauseHook, c.Gates, c.MaxRetries, int(c.DrainTimeout/time.Second), steps, c.OwnerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cutover: %w", err)
+	}
+
+	created, err := scanCutover(tx.QueryRow(ctx, "SELECT "+cutoverColumns+cutoverFrom+" WHERE c.cutover_id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if connectionDatabaseID != "" && connectionDatabaseID != created.SourceDatabaseID {
+		return nil, fmt.Errorf("connection %s does not point to the source database of relationship %s", c.ConnectionName, created.RelationshipName)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
+// GetByName returns a cutover of a workspace
+func (s *Service) GetByName(ctx context.Context, tenantID, workspaceID, name string) (*Cutover, error) {
+	c, err := scanCutover(s.db.Pool().QueryRow(ctx, "SELECT "+cutoverColumns+cutoverFrom+`
+		WHERE c.tenant_id = $1 AND c.workspace_id = $2 AND c.cutover_name = $3
+	`, tenantID, workspaceID, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("cutover not found")
+	}
+	return c, err
+}
+
+// List returns the cutovers of a workspace
+func (s *Service) List(ctx context.Context, tenantID, workspaceID string) ([]*Cutover, error) {
+	return s.query(ctx, "SELECT "+cutoverColumns+cutoverFrom+`
+		WHERE c.tenant_id = $1 AND c.workspace_id = $2
+		ORDER BY c.cutover_name
+	`, tenantID, workspaceID)
+}
+
+// ListRunning returns the cutovers of all tenants whose steps are running
+func (s *Service) ListRunning(ctx context.Context) ([]*Cutover, error) {
+	return s.query(ctx, "SELECT "+cutoverColumns+cutoverFrom+" WHERE c.cutover_state = $1", StateRunning)
+}
+
+func (s *Service) query(ctx context.Context, query string, args ...interface{}) ([]*Cutover, error) {
+	rows, err := s.db.Pool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cutovers []*Cutover
+	for rows.Next() {
+		c, err := scanCutover(rows)
+		if err != nil {
+			return nil, err
+		}
+		cutovers = append(cutovers, c)
+	}
+	return cutovers, rows.Err()
+}
+
+// Save stores the state and the steps of a cutover
+func (s *Service) Save(ctx context.Context, c *Cutover) error {
+	steps, err := json.Marshal(c.Steps)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		UPDATE cutovers SET cutover_state = $2, current_step = $3, cutover_steps = $4, updated = CURRENT_TIMESTAMP
+		WHERE cutover_id = $1
+	`, c.ID, c.State, c.CurrentStep, steps)
+	if err != nil {
+		return fmt.Errorf("failed to save cutover %s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// Delete deletes a cutover. The application connection it switched is kept.
+func (s *Service) Delete(ctx context.Context, tenantID, workspaceID, name string) error {
+	result, err := s.db.Pool().Exec(ctx, `
+		DELETE FROM cutovers WHERE tenant_id = $1 AND workspace_id = $2 AND cutover_name = $3
+	`, tenantID, workspaceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete cutover: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("cutover not found")
+	}
+	return nil
+}
+
+// SwitchConnection points the application connection of a cutover to the target database. A
+// connection that already points to it is left as it is, so the switch can be retried.
+func (s *Service) SwitchConnection(ctx context.Context, c *Cutover) error {
+	_, err := s.db.Pool().Exec(ctx, `
+		UPDATE application_connections
+		SET previous_database_id = database_id, database_id = $3, switched_by_cutover_id = $4, updated = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1 AND connection_name = $2 AND database_id <> $3
+	`, c.WorkspaceID, c.ConnectionName, c.TargetDatabaseID, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to switch connection %s: %w", c.ConnectionName, err)
+	}
+	return nil
+}
+
+// ListConnections returns the application connections of a workspace with the databases they
+// point to
+func (s *Service) ListConnections(ctx context.Context, tenantID, workspaceID string) ([]*ApplicationConnection, error) {
+	rows, err := s.db.Pool().Query(ctx, `
+		SELECT a.tenant_id, a.workspace_id, a.connection_name, a.database_id, d.database_name, d.database_type,
+		       i.instance_host, i.instance_port, d.database_db_name, COALESCE(p.database_name, ''),
+		       COALESCE(c.cutover_name, ''), a.updated
+		FROM application_connections a
+		JOIN databases d ON d.database_id = a.database_id
+		JOIN instances i ON i.instance_id = d.instance_id
+		LEFT JOIN databases p ON p.database_id = a.previous_database_id
+		LEFT JOIN cutovers c ON c.cutover_id = a.switched_by_cutover_id
+		WHERE a.tenant_id = $1 AND a.workspace_id = $2
+		ORDER BY a.connection_name
+	`, tenantID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var connections []*ApplicationConnection
+	for rows.Next() {
+		var a ApplicationConnection
+		if err := rows.Scan(&a.TenantID, &a.WorkspaceID, &a.Name, &a.DatabaseID, &a.DatabaseName, &a.DatabaseType,
+			&a.Host, &a.Port, &a.DatabaseDBName, &a.PreviousDatabaseName, &a.SwitchedByCutoverName, &a.Updated); err != nil {
+			return nil, err
+		}
+		connections = append(connections, &a)
+	}
+	return connections, rows.Err()
+}
@@ -0,0 +1,83 @@
+package cutover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Hook events, the steps that call the hooks
+const (
+	EventStopWrites = StepStopWrites
+	EventUnpause    = StepUnpause
+)
+
+// hookTimeout is how long a hook has to answer
+const hookTimeout = 30 * time.Second
+
+// HookEvent is the JSON body POSTed to the hooks of a cutover. A hook answers with a 2xx status
+// once the applications stopped or resumed their writes; any other status fails the step.
+type HookEvent struct {
+	Event            string `json:"event"` // stop_writes or unpause
+	CutoverID        string `json:"cutover_id"`
+	CutoverName      string `json:"cutover_name"`
+	TenantID         string `json:"tenant_id"`
+	WorkspaceID      string `json:"workspace_id"`
+	RelationshipName string `json:"relationship_name"`
+	ConnectionName   string `json:"connection_name"`
+	DatabaseID       string `json:"database_id"`       // Database the connection points to
+	Aborted          bool   `json:"aborted,omitempty"` // Writes resume because the cutover was aborted
+}
+
+// newHookEvent returns the event of a hook of a cutover
+func newHookEvent(c *Cutover, event string) *HookEvent {
+	databaseID := c.SourceDatabaseID
+	if step := c.Step(StepSwitchConnection); step != nil && step.done() {
+		databaseID = c.TargetDatabaseID
+	}
+	return &HookEvent{
+		Event:            event,
+		CutoverID:        c.ID,
+		CutoverName:      c.Name,
+		TenantID:         c.TenantID,
+		WorkspaceID:      c.WorkspaceID,
+		RelationshipName: c.RelationshipName,
+		ConnectionName:   c.ConnectionName,
+		DatabaseID:       databaseID,
+		Aborted:          c.State == StateAborted,
+	}
+}
+
+// callHook POSTs an event to a hook
+func callHook(ctx context.Context, client *http.Client, url string, event *HookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid %s hook: %w", event.Event, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %w", event.Event, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("%s hook answered %s: %s", event.Event, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
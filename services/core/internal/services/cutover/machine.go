@@ -0,0 +1,191 @@
+package cutover
+
+import (
+	"fmt"
+	"time"
+)
+
+// Steps of a cutover, run in this order
+const (
+	StepStopWrites       = "stop_writes"       // Call the stop writes hook
+	StepDrainCDC         = "drain_cdc"         // Wait until the relationship has no pending CDC events
+	StepReconcile        = "reconcile"         // Compare the row counts of the source and target tables
+	StepSwitchConnection = "switch_connection" // Point the application connection to the target database
+	StepUnpause          = "unpause"           // Call the unpause hook
+)
+
+// StepNames lists the steps of a cutover in the order they run
+var StepNames = []string{StepStopWrites, StepDrainCDC, StepReconcile, StepSwitchConnection, StepUnpause}
+
+// States of a cutover
+const (
+	StatePending          = "pending"           // Not started
+	StateRunning          = "running"           // Its steps run in the background
+	StateAwaitingApproval = "awaiting_approval" // Waiting at a gate for the approval of the current step
+	StateFailed           = "failed"            // The current step failed after its retries
+	StateCompleted        = "completed"
+	StateAborted          = "aborted"
+)
+
+// Statuses of a step
+const (
+	StepPending   = "pending"
+	StepRunning   = "running"
+	StepSucceeded = "succeeded"
+	StepSkipped   = "skipped" // Nothing to do, e.g. a hook that is not configured
+	StepFailed    = "failed"
+)
+
+// Step is the progress of one step of a cutover
+type Step struct {
+	Name     string     `json:"name"`
+	Status   string     `json:"status"`
+	Attempts int        `json:"attempts"`
+	Approved bool       `json:"approved,omitempty"`
+	Message  string     `json:"message,omitempty"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+}
+
+// done reports whether a step no longer has to run
+func (s *Step) done() bool {
+	return s.Status == StepSucceeded || s.Status == StepSkipped
+}
+
+// NewSteps returns the steps of a cutover that has not started
+func NewSteps() []*Step {
+	steps := make([]*Step, len(StepNames))
+	for i, name := range StepNames {
+		steps[i] = &Step{Name: name, Status: StepPending}
+	}
+	return steps
+}
+
+// ValidateGates returns an error if a gate is not a step
+func ValidateGates(gates []string) error {
+	for _, gate := range gates {
+		if !validStep(gate) {
+			return fmt.Errorf("unknown step %q; steps are %v", gate, StepNames)
+		}
+	}
+	return nil
+}
+
+func validStep(name string) bool {
+	for _, step := range StepNames {
+		if step == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Gated reports whether a step waits for an approval before it runs
+func (c *Cutover) Gated(step string) bool {
+	for _, gate := range c.Gates {
+		if gate == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Step returns a step of the cutover, or nil
+func (c *Cutover) Step(name string) *Step {
+	for _, step := range c.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// Next returns the first step that has not succeeded or been skipped, or nil once all have
+func (c *Cutover) Next() *Step {
+	for _, step := range c.Steps {
+		if !step.done() {
+			return step
+		}
+	}
+	return nil
+}
+
+// WritesStopped reports whether the stop writes hook was called and the unpause hook was not
+func (c *Cutover) WritesStopped() bool {
+	stop, unpause := c.Step(StepStopWrites), c.Step(StepUnpause)
+	return stop != nil && stop.Status == StepSucceeded && (unpause == nil || !unpause.done())
+}
+
+// clone returns a copy of the cutover whose steps can change independently
+func (c *Cutover) clone() *Cutover {
+	copied := *c
+	copied.Steps = make([]*Step, len(c.Steps))
+	for i, step := range c.Steps {
+		s := *step
+		copied.Steps[i] = &s
+	}
+	return &copied
+}
+
+// Start moves a pending cutover to running
+func (c *Cutover) Start() error {
+	if c.State != StatePending {
+		return fmt.Errorf("cutover %s is %s, only a pending cutover can be started", c.Name, c.State)
+	}
+	c.State = StateRunning
+	return nil
+}
+
+// Approve approves the step a cutover waits at and moves it back to running
+func (c *Cutover) Approve(step string) error {
+	if c.State != StateAwaitingApproval {
+		return fmt.Errorf("cutover %s is %s, not awaiting approval", c.Name, c.State)
+	}
+	if step != "" && step != c.CurrentStep {
+		return fmt.Errorf("cutover %s awaits approval of %s, not %s", c.Name, c.CurrentStep, step)
+	}
+	current := c.Step(c.CurrentStep)
+	if current == nil {
+		return fmt.Errorf("cutover %s has no step %s", c.Name, c.CurrentStep)
+	}
+	current.Approved = true
+	c.State = StateRunning
+	return nil
+}
+
+// Retry resets the step a cutover failed at and moves it back to running
+func (c *Cutover) Retry() error {
+	if c.State != StateFailed {
+		return fmt.Errorf("cutover %s is %s, only a failed cutover can be retried", c.Name, c.State)
+	}
+	current := c.Step(c.CurrentStep)
+	if current == nil {
+		return fmt.Errorf("cutover %s has no step %s", c.Name, c.CurrentStep)
+	}
+	current.Status = StepPending
+	current.Attempts = 0
+	c.State = StateRunning
+	return nil
+}
+
+// Abort stops a cutover that has not ended
+func (c *Cutover) Abort() error {
+	if c.State == StateCompleted || c.State == StateAborted {
+		return fmt.Errorf("cutover %s is already %s", c.Name, c.State)
+	}
+	c.State = StateAborted
+	return nil
+}
+
+// Deletable reports whether a cutover can be deleted: it is not running and does not hold the
+// writes of the applications
+func (c *Cutover) Deletable() bool {
+	switch c.State {
+	case StatePending, StateCompleted, StateAborted:
+		return true
+	case StateFailed:
+		return !c.WritesStopped()
+	default:
+		return false
+	}
+}
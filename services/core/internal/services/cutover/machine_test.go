@@ -0,0 +1,128 @@
+package cutover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newCutover(gates ...string) *Cutover {
+	return &Cutover{
+		ID:               "cutover_1",
+		Name:             "orders",
+		SourceDatabaseID: "db_source",
+		TargetDatabaseID: "db_target",
+		ConnectionName:   "orders-app",
+		Gates:            gates,
+		State:            StatePending,
+		Steps:            NewSteps(),
+	}
+}
+
+func TestCutoverTransitions(t *testing.T) {
+	c := newCutover(StepSwitchConnection)
+
+	if err := c.Approve(""); err == nil {
+		t.Error("a pending cutover should not be approved")
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := c.Start(); err == nil {
+		t.Error("a running cutover should not be started again")
+	}
+
+	// The steps before the gate ran
+	for _, name := range []string{StepStopWrites, StepDrainCDC, StepReconcile} {
+		c.Step(name).Status = StepSucceeded
+	}
+	if next := c.Next(); next == nil || next.Name != StepSwitchConnection {
+		t.Fatalf("next step = %v, want %s", next, StepSwitchConnection)
+	}
+	if !c.Gated(StepSwitchConnection) || c.Gated(StepUnpause) {
+		t.Error("only switch_connection should be gated")
+	}
+	if !c.WritesStopped() {
+		t.Error("writes should be stopped")
+	}
+
+	c.State = StateAwaitingApproval
+	c.CurrentStep = StepSwitchConnection
+	if err := c.Approve(StepUnpause); err == nil {
+		t.Error("approving another step than the current one should fail")
+	}
+	if err := c.Approve(StepSwitchConnection); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if c.State != StateRunning || !c.Step(StepSwitchConnection).Approved {
+		t.Error("approved cutover should be running with the step approved")
+	}
+
+	c.Step(StepSwitchConnection).Status = StepFailed
+	c.Step(StepSwitchConnection).Attempts = 4
+	c.State = StateFailed
+	if c.Deletable() {
+		t.Error("a failed cutover holding the writes should not be deletable")
+	}
+	if err := c.Retry(); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if step := c.Step(StepSwitchConnection); step.Status != StepPending || step.Attempts != 0 {
+		t.Errorf("retried step = %+v, want pending without attempts", step)
+	}
+
+	c.Step(StepSwitchConnection).Status = StepSucceeded
+	c.Step(StepUnpause).Status = StepSkipped
+	if c.Next() != nil {
+		t.Error("all steps are done")
+	}
+	if c.WritesStopped() {
+		t.Error("writes should no longer be stopped")
+	}
+	c.State = StateCompleted
+	if err := c.Abort(); err == nil {
+		t.Error("a completed cutover should not be aborted")
+	}
+}
+
+func TestValidateGates(t *testing.T) {
+	if err := ValidateGates([]string{StepSwitchConnection, StepUnpause}); err != nil {
+		t.Errorf("ValidateGates: %v", err)
+	}
+	if err := ValidateGates([]string{"switch"}); err == nil {
+		t.Error("unknown gate should be rejected")
+	}
+}
+
+func TestCallHook(t *testing.T) {
+	var received HookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid hook body: %v", err)
+		}
+		if received.Event == EventUnpause {
+			http.Error(w, "writes are still draining", http.StatusConflict)
+		}
+	}))
+	defer server.Close()
+
+	c := newCutover()
+	if err := callHook(context.Background(), server.Client(), server.URL, newHookEvent(c, EventStopWrites)); err != nil {
+		t.Fatalf("stop_writes hook: %v", err)
+	}
+	if received.ConnectionName != "orders-app" || received.DatabaseID != "db_source" {
+		t.Errorf("hook event = %+v", received)
+	}
+
+	// Once switched, the connection points to the target database
+	c.Step(StepSwitchConnection).Status = StepSucceeded
+	err := callHook(context.Background(), server.Client(), server.URL, newHookEvent(c, EventUnpause))
+	if err == nil {
+		t.Fatal("unpause hook answering 409 should fail")
+	}
+	if received.DatabaseID != "db_target" {
+		t.Errorf("unpause event database = %s, want db_target", received.DatabaseID)
+	}
+}
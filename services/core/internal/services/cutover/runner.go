@@ -0,0 +1,290 @@
+package cutover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// retryDelay is the delay before the first retry of a failing step; later retries wait longer
+const retryDelay = 10 * time.Second
+
+// errSkipped is returned by a step that has nothing to do
+var errSkipped = errors.New("skipped")
+
+// Executor runs the steps of a cutover that act on the databases of its relationship
+type Executor interface {
+	// DrainCDC waits until the replication of the relationship has no pending events, and
+	// fails if it still has some after the drain timeout of the cutover
+	DrainCDC(ctx context.Context, c *Cutover) (string, error)
+
+	// Reconcile compares the source and target tables of the relationship, and fails if
+	// they differ
+	Reconcile(ctx context.Context, c *Cutover) (string, error)
+}
+
+// Runner runs the steps of cutovers in the background, one goroutine per running cutover. A
+// cutover runs until it reaches a gate, a step fails after its retries, or all steps are done;
+// its progress is saved after every step, so cutovers still running when the service stops
+// resume where they were when it starts again.
+type Runner struct {
+	ctx      context.Context
+	service  *Service
+	executor Executor
+	client   *http.Client
+	logger   *logger.Logger
+
+	mu   sync.Mutex
+	runs map[string]*run // By cutover ID
+}
+
+// run is a cutover whose steps are running
+type run struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRunner creates a runner whose cutovers run until ctx is done
+func NewRunner(ctx context.Context, service *Service, executor Executor, logger *logger.Logger) *Runner {
+	return &Runner{
+		ctx:      ctx,
+		service:  service,
+		executor: executor,
+		client:   &http.Client{},
+		logger:   logger,
+		runs:     make(map[string]*run),
+	}
+}
+
+// Resume runs the cutovers that were running when the service stopped
+func (r *Runner) Resume(ctx context.Context) error {
+	cutovers, err := r.service.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range cutovers {
+		r.logger.Infof("Resuming cutover %s at step %s", c.Name, c.CurrentStep)
+		r.Run(c)
+	}
+	return nil
+}
+
+// Start starts a pending cutover
+func (r *Runner) Start(ctx context.Context, c *Cutover) error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return r.saveAndRun(ctx, c)
+}
+
+// Approve approves the step a cutover waits at and runs the steps that follow
+func (r *Runner) Approve(ctx context.Context, c *Cutover, step string) error {
+	if err := c.Approve(step); err != nil {
+		return err
+	}
+	return r.saveAndRun(ctx, c)
+}
+
+// Retry runs the step a cutover failed at again
+func (r *Runner) Retry(ctx context.Context, c *Cutover) error {
+	if err := c.Retry(); err != nil {
+		return err
+	}
+	return r.saveAndRun(ctx, c)
+}
+
+func (r *Runner) saveAndRun(ctx context.Context, c *Cutover) error {
+	// Steps that just reached a gate or failed may still be finishing
+	r.mu.Lock()
+	rn, ok := r.runs[c.ID]
+	r.mu.Unlock()
+	if ok {
+		<-rn.done
+	}
+
+	if err := r.service.Save(ctx, c); err != nil {
+		return err
+	}
+	r.Run(c)
+	return nil
+}
+
+// Abort stops the steps of a cutover and aborts it. Writes that were stopped are resumed
+// through the unpause hook; if the hook fails, the cutover is aborted anyway and the error
+// is returned so that writes can be resumed by hand.
+func (r *Runner) Abort(ctx context.Context, c *Cutover) error {
+	r.stop(c.ID)
+
+	// The cutover may have moved on while its steps were stopping
+	current, err := r.service.GetByName(ctx, c.TenantID, c.WorkspaceID, c.Name)
+	if err != nil {
+		return err
+	}
+	*c = *current
+
+	if err := c.Abort(); err != nil {
+		return err
+	}
+	var hookErr error
+	if c.WritesStopped() && c.UnpauseHook != "" {
+		hookErr = callHook(ctx, r.client, c.UnpauseHook, newHookEvent(c, EventUnpause))
+	}
+	if err := r.service.Save(ctx, c); err != nil {
+		return err
+	}
+	if hookErr != nil {
+		return fmt.Errorf("cutover aborted, but writes were not resumed: %w", hookErr)
+	}
+	return nil
+}
+
+// Run runs the steps of a running cutover in the background, unless they already run. The
+// steps run on a copy of the cutover.
+func (r *Runner) Run(c *Cutover) {
+	c = c.clone()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.runs[c.ID]; ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	rn := &run{cancel: cancel, done: make(chan struct{})}
+	r.runs[c.ID] = rn
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			delete(r.runs, c.ID)
+			r.mu.Unlock()
+			cancel()
+			close(rn.done)
+		}()
+		r.runSteps(ctx, c)
+	}()
+}
+
+// stop cancels the steps of a cutover and waits until they stopped
+func (r *Runner) stop(id string) {
+	r.mu.Lock()
+	rn, ok := r.runs[id]
+	r.mu.Unlock()
+	if ok {
+		rn.cancel()
+		<-rn.done
+	}
+}
+
+// runSteps runs the steps of a cutover until it reaches a gate, fails or completes, or ctx is
+// done. A step cancelled by ctx is left pending, to run again when the cutover resumes.
+func (r *Runner) runSteps(ctx context.Context, c *Cutover) {
+	// Progress is saved even when ctx is cancelled
+	save := func() {
+		if err := r.service.Save(context.Background(), c); err != nil {
+			r.logger.Errorf("Failed to save cutover %s: %v", c.Name, err)
+		}
+	}
+
+	for {
+		step := c.Next()
+		if step == nil {
+			c.State = StateCompleted
+			c.CurrentStep = ""
+			save()
+			r.logger.Infof("Cutover %s completed", c.Name)
+			return
+		}
+		c.CurrentStep = step.Name
+		if c.Gated(step.Name) && !step.Approved {
+			c.State = StateAwaitingApproval
+			save()
+			r.logger.Infof("Cutover %s awaits approval of %s", c.Name, step.Name)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		started := time.Now().UTC()
+		step.Status = StepRunning
+		step.Attempts++
+		step.Started = &started
+		step.Finished = nil
+		save()
+
+		message, err := r.runStep(ctx, c, step.Name)
+		if ctx.Err() != nil {
+			step.Status = StepPending
+			step.Attempts--
+			step.Message = "interrupted"
+			save()
+			return
+		}
+
+		finished := time.Now().UTC()
+		step.Finished = &finished
+		switch {
+		case errors.Is(err, errSkipped):
+			step.Status = StepSkipped
+			step.Message = message
+		case err == nil:
+			step.Status = StepSucceeded
+			step.Message = message
+		case step.Attempts <= c.MaxRetries:
+			step.Status = StepPending
+			step.Message = err.Error()
+			save()
+			r.logger.Warnf("Cutover %s step %s failed (attempt %d), retrying: %v", c.Name, step.Name, step.Attempts, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay * time.Duration(step.Attempts)):
+			}
+			continue
+		default:
+			step.Status = StepFailed
+			step.Message = err.Error()
+			c.State = StateFailed
+			save()
+			r.logger.Errorf("Cutover %s failed at step %s: %v", c.Name, step.Name, err)
+			return
+		}
+		save()
+	}
+}
+
+// runStep runs one step of a cutover and returns its outcome
+func (r *Runner) runStep(ctx context.Context, c *Cutover, step string) (string, error) {
+	switch step {
+	case StepStopWrites:
+		return r.runHook(ctx, c, c.StopWritesHook, EventStopWrites)
+	case StepDrainCDC:
+		return r.executor.DrainCDC(ctx, c)
+	case StepReconcile:
+		return r.executor.Reconcile(ctx, c)
+	case StepSwitchConnection:
+		if err := r.service.SwitchConnection(ctx, c); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Connection %s points to the target database", c.ConnectionName), nil
+	case StepUnpause:
+		return r.runHook(ctx, c, c.UnpauseHook, EventUnpause)
+	default:
+		return "", fmt.Errorf("unknown step %s", step)
+	}
+}
+
+func (r *Runner) runHook(ctx context.Context, c *Cutover, url, event string) (string, error) {
+	if url == "" {
+		return "No hook configured", errSkipped
+	}
+	if err := callHook(ctx, r.client, url, newHookEvent(c, event)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s hook called", event), nil
+}
@@ -27,6 +27,67 @@ func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
 	}
 }
 
+// Routing policies of databases with read replicas
+const (
+	RoutingPolicyPrimary          = "primary"
+	RoutingPolicyReadsFromReplica = "reads-from-replica"
+)
+
+// Replica is a read replica of a database. Empty credentials are those of the database; the
+// password is stored encrypted like the database password.
+type Replica struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// RateLimits are the load guardrails of a database, enforced by the anchor on every operation.
+// Zero means unlimited.
+type RateLimits struct {
+	RowsPerSecond           float64 `json:"rowsPerSecond,omitempty"`
+	QueriesPerSecond        float64 `json:"queriesPerSecond,omitempty"`
+	MaxConcurrentStatements int     `json:"maxConcurrentStatements,omitempty"`
+}
+
+// IsZero reports whether no limit is set
+func (l *RateLimits) IsZero() bool {
+	return l == nil || (l.RowsPerSecond == 0 && l.QueriesPerSecond == 0 && l.MaxConcurrentStatements == 0)
+}
+
+// ValidateRateLimits checks that the rate limits of a database are not negative
+func ValidateRateLimits(limits *RateLimits) error {
+	if limits.RowsPerSecond < 0 || limits.QueriesPerSecond < 0 || limits.MaxConcurrentStatements < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+	return nil
+}
+
+// ValidRoutingPolicy reports whether policy is a known routing policy
+func ValidRoutingPolicy(policy string) bool {
+	return policy == RoutingPolicyPrimary || policy == RoutingPolicyReadsFromReplica
+}
+
+// EncryptReplicas validates the read replicas of a database and returns them with their
+// passwords encrypted for storage.
+func EncryptReplicas(tenantID string, replicas []Replica) ([]Replica, error) {
+	encrypted := make([]Replica, len(replicas))
+	for i, replica := range replicas {
+		if replica.Host == "" || replica.Port <= 0 || replica.Port > 65535 {
+			return nil, fmt.Errorf("replica %d: host and a valid port are required", i+1)
+		}
+		if replica.Password != "" {
+			password, err := encryption.EncryptPassword(tenantID, replica.Password)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt password of replica %d: %w", i+1, err)
+			}
+			replica.Password = password
+		}
+		encrypted[i] = replica
+	}
+	return encrypted, nil
+}
+
 // Database represents a database in the system
 type Database struct {
 	ID                string
@@ -44,6 +105,10 @@ type Database struct {
 	Password          string
 	DBName            string
 	Enabled           bool
+	Replicas          []Replica
+	RoutingPolicy     string
+	RateLimits        *RateLimits
+	Labels            map[string]string
 	PolicyIDs         []string
 	Metadata          map[string]interface{}
 	OwnerID           string
@@ -155,7 +220,7 @@ func (s *Service) Create(ctx context.Context, tenantID, workspaceID, name, descr
 	query := `
 		INSERT INTO databases (tenant_id, workspace_id, environment_id, connected_to_node_id, instance_id, database_name, database_description, database_type, database_vendor, database_version, database_username, database_password, database_db_name, database_enabled, owner_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_id, database_name, database_description, database_type, database_vendor, database_version, database_username, database_password, database_db_name, database_enabled, policy_ids, database_metadata, owner_id, database_status_message, status, created, updated
+		RETURNING database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_id, database_name, database_description, database_type, database_vendor, database_version, database_username, database_password, database_db_name, database_enabled, database_replicas, database_routing_policy, database_rate_limits, database_labels, policy_ids, database_metadata, owner_id, database_status_message, status, created, updated
 	`
 
 	var database Database
@@ -180,6 +245,10 @@ func (s *Service) Create(ctx context.Context, tenantID, workspaceID, name, descr
 		&database.Password,
 		&database.DBName,
 		&database.Enabled,
+		&database.Replicas,
+		&database.RoutingPolicy,
+		&database.RateLimits,
+		&database.Labels,
 		&database.PolicyIDs,
 		&database.Metadata,
 		&database.OwnerID,
@@ -209,7 +278,7 @@ func (s *Service) Get(ctx context.Context, tenantID, workspaceID, name string) (
 		SELECT database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, 
 			instance_id, database_name, database_description, database_type, database_vendor, 
 			database_version, database_username, database_password, database_db_name, 
-			database_enabled, policy_ids, database_metadata, owner_id, database_status_message, 
+			database_enabled, database_replicas, database_routing_policy, database_rate_limits, database_labels, policy_ids, database_metadata, owner_id, database_status_message, 
 			status, created, updated, database_schema, database_tables
 		FROM databases
 		WHERE tenant_id = $1 AND workspace_id = $2 AND database_name = $3
@@ -232,6 +301,10 @@ func (s *Service) Get(ctx context.Context, tenantID, workspaceID, name string) (
 		&database.Password,
 		&database.DBName,
 		&database.Enabled,
+		&database.Replicas,
+		&database.RoutingPolicy,
+		&database.RateLimits,
+		&database.Labels,
 		&database.PolicyIDs,
 		&database.Metadata,
 		&database.OwnerID,
@@ -266,7 +339,7 @@ func (s *Service) GetByID(ctx context.Context, databaseID string) (*Database, er
 		SELECT database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, 
 			instance_id, database_name, database_description, database_type, database_vendor, 
 			database_version, database_username, database_password, database_db_name, 
-			database_enabled, policy_ids, database_metadata, owner_id, database_status_message, 
+			database_enabled, database_replicas, database_routing_policy, database_rate_limits, database_labels, policy_ids, database_metadata, owner_id, database_status_message, 
 			status, created, updated, database_schema, database_tables
 		FROM databases
 		WHERE database_id = $1
@@ -289,6 +362,10 @@ func (s *Service) GetByID(ctx context.Context, databaseID string) (*Database, er
 		&database.Password,
 		&database.DBName,
 		&database.Enabled,
+		&database.Replicas,
+		&database.RoutingPolicy,
+		&database.RateLimits,
+		&database.Labels,
 		&database.PolicyIDs,
 		&database.Metadata,
 		&database.OwnerID,
@@ -376,7 +453,7 @@ func (s *Service) List(ctx context.Context, tenantID, workspaceID string) ([]*Da
 		SELECT database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, 
 			instance_id, database_name, database_description, database_type, database_vendor, 
 			database_version, database_username, database_password, database_db_name, 
-			database_enabled, policy_ids, database_metadata, owner_id, database_status_message, 
+			database_enabled, database_replicas, database_routing_policy, database_rate_limits, database_labels, policy_ids, database_metadata, owner_id, database_status_message, 
 			status, created, updated
 		FROM databases
 		WHERE tenant_id = $1 AND workspace_id = $2
@@ -409,6 +486,10 @@ func (s *Service) List(ctx context.Context, tenantID, workspaceID string) ([]*Da
 			&database.Password,
 			&database.DBName,
 			&database.Enabled,
+			&database.Replicas,
+			&database.RoutingPolicy,
+			&database.RateLimits,
+			&database.Labels,
 			&database.PolicyIDs,
 			&database.Metadata,
 			&database.OwnerID,
@@ -459,7 +540,7 @@ func (s *Service) Update(ctx context.Context, tenantID, workspaceID, name string
 	}
 
 	// Add the WHERE clause
-	query += fmt.Sprintf(" WHERE tenant_id = $%d AND workspace_id = $%d AND database_name = $%d RETURNING database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_id, database_name, database_description, database_type, database_vendor, database_version, database_username, database_password, database_db_name, database_enabled, policy_ids, database_metadata, owner_id, database_status_message, status, created, updated", argIndex, argIndex+1, argIndex+2)
+	query += fmt.Sprintf(" WHERE tenant_id = $%d AND workspace_id = $%d AND database_name = $%d RETURNING database_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_id, database_name, database_description, database_type, database_vendor, database_version, database_username, database_password, database_db_name, database_enabled, database_replicas, database_routing_policy, database_rate_limits, database_labels, policy_ids, database_metadata, owner_id, database_status_message, status, created, updated", argIndex, argIndex+1, argIndex+2)
 	args = append(args, tenantID, workspaceID, name)
 
 	var database Database
@@ -479,6 +560,10 @@ func (s *Service) Update(ctx context.Context, tenantID, workspaceID, name string
 		&database.Password,
 		&database.DBName,
 		&database.Enabled,
+		&database.Replicas,
+		&database.RoutingPolicy,
+		&database.RateLimits,
+		&database.Labels,
 		&database.PolicyIDs,
 		&database.Metadata,
 		&database.OwnerID,
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxLabelLength is the longest label key or value
+const maxLabelLength = 63
+
+// ValidateLabels checks that the keys of the labels of a database are not empty and that keys
+// and values are made of letters, digits, '-', '_', '.' and '/'
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if key == "" {
+			return fmt.Errorf("label keys must not be empty")
+		}
+		if !validLabelText(key) {
+			return fmt.Errorf("invalid label key %q", key)
+		}
+		if !validLabelText(value) {
+			return fmt.Errorf("invalid value of label %s: %q", key, value)
+		}
+	}
+	return nil
+}
+
+func validLabelText(text string) bool {
+	if len(text) > maxLabelLength {
+		return false
+	}
+	for _, r := range text {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// labelRequirement is a condition of a label selector on one label
+type labelRequirement struct {
+	key    string
+	value  string
+	negate bool // The label must not have the value, or must be missing when exists is set
+	exists bool // Only the presence of the label is checked
+}
+
+// LabelSelector selects databases by their labels. All of its requirements must match.
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector parses a comma-separated list of requirements on labels: "key=value" (or
+// "key==value"), "key!=value", "key" for a label that is set and "!key" for a label that is
+// not. An empty selector matches all databases.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var requirements LabelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var requirement labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			requirement.key, requirement.value, _ = strings.Cut(part, "!=")
+			requirement.negate = true
+		case strings.Contains(part, "=="):
+			requirement.key, requirement.value, _ = strings.Cut(part, "==")
+		case strings.Contains(part, "="):
+			requirement.key, requirement.value, _ = strings.Cut(part, "=")
+		case strings.HasPrefix(part, "!"):
+			requirement.key = strings.TrimPrefix(part, "!")
+			requirement.negate = true
+			requirement.exists = true
+		default:
+			requirement.key = part
+			requirement.exists = true
+		}
+
+		requirement.key = strings.TrimSpace(requirement.key)
+		requirement.value = strings.TrimSpace(requirement.value)
+		if requirement.key == "" || !validLabelText(requirement.key) {
+			return nil, fmt.Errorf("invalid label selector %q", part)
+		}
+		if !validLabelText(requirement.value) {
+			return nil, fmt.Errorf("invalid label value in selector %q", part)
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
+
+// Matches reports whether labels satisfy all requirements of the selector
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		value, ok := labels[requirement.key]
+		if requirement.exists {
+			if ok == requirement.negate {
+				return false
+			}
+			continue
+		}
+		if (ok && value == requirement.value) == requirement.negate {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package database
+
+import "testing"
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"team": "analytics", "tier": "gold"}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=analytics", true},
+		{"team==analytics", true},
+		{"team=sales", false},
+		{"team=analytics, tier!=silver", true},
+		{"team=analytics,tier!=gold", false},
+		{"region!=eu", true},
+		{"tier", true},
+		{"region", false},
+		{"!region", true},
+		{"!tier", false},
+	}
+	for _, test := range tests {
+		selector, err := ParseLabelSelector(test.selector)
+		if err != nil {
+			t.Fatalf("ParseLabelSelector(%q): %v", test.selector, err)
+		}
+		if got := selector.Matches(labels); got != test.want {
+			t.Errorf("%q matches = %v, want %v", test.selector, got, test.want)
+		}
+	}
+
+	for _, selector := range []string{"=analytics", "team=a b", "!"} {
+		if _, err := ParseLabelSelector(selector); err == nil {
+			t.Errorf("ParseLabelSelector(%q) should fail", selector)
+		}
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	if err := ValidateLabels(map[string]string{"team": "analytics", "app.redb/owner": ""}); err != nil {
+		t.Fatalf("ValidateLabels: %v", err)
+	}
+	for _, labels := range []map[string]string{{"": "x"}, {"team name": "x"}, {"team": "a,b"}} {
+		if err := ValidateLabels(labels); err == nil {
+			t.Errorf("ValidateLabels(%v) should fail", labels)
+		}
+	}
+}
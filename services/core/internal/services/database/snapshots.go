@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrSnapshotNotFound is returned when a database has no schema snapshot matching a selection
+var ErrSnapshotNotFound = errors.New("schema snapshot not found")
+
+// SchemaSnapshot is a schema of a database as discovered at a point in time. Snapshots are stored
+// by a trigger whenever the stored schema of a database changes, and numbered from 1.
+type SchemaSnapshot struct {
+	ID         string
+	DatabaseID string
+	Version    int32
+	Schema     string // Empty in listings
+	SizeBytes  int64
+	Created    time.Time
+}
+
+// ListSchemaSnapshots returns the schema snapshots of a database without their schemas, newest
+// first. A limit of 0 returns all of them.
+func (s *Service) ListSchemaSnapshots(ctx context.Context, databaseID string, limit int32) ([]*SchemaSnapshot, error) {
+	query := `
+		SELECT snapshot_id, database_id, snapshot_version, octet_length(schema_structure::text), created
+		FROM database_schema_snapshots
+		WHERE database_id = $1
+		ORDER BY snapshot_version DESC
+	`
+	args := []interface{}{databaseID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Pool().Query(ctx, query, args...)
+	if err != nil {
+		s.logger.Errorf("Failed to list schema snapshots: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*SchemaSnapshot
+	for rows.Next() {
+		var snapshot SchemaSnapshot
+		if err := rows.Scan(&snapshot.ID, &snapshot.DatabaseID, &snapshot.Version, &snapshot.SizeBytes, &snapshot.Created); err != nil {
+			s.logger.Errorf("Failed to scan schema snapshot: %v", err)
+			return nil, err
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// GetSchemaSnapshot returns a schema snapshot of a database by version
+func (s *Service) GetSchemaSnapshot(ctx context.Context, databaseID string, version int32) (*SchemaSnapshot, error) {
+	return s.getSchemaSnapshot(ctx, `WHERE database_id = $1 AND snapshot_version = $2`, databaseID, version)
+}
+
+// GetSchemaSnapshotAt returns the schema snapshot of a database that was current at a point in
+// time: the last one stored at or before it
+func (s *Service) GetSchemaSnapshotAt(ctx context.Context, databaseID string, at time.Time) (*SchemaSnapshot, error) {
+	return s.getSchemaSnapshot(ctx, `WHERE database_id = $1 AND created <= $2 ORDER BY snapshot_version DESC LIMIT 1`, databaseID, at.UTC())
+}
+
+// GetLatestSchemaSnapshot returns the last schema snapshot of a database
+func (s *Service) GetLatestSchemaSnapshot(ctx context.Context, databaseID string) (*SchemaSnapshot, error) {
+	return s.getSchemaSnapshot(ctx, `WHERE database_id = $1 ORDER BY snapshot_version DESC LIMIT 1`, databaseID)
+}
+
+func (s *Service) getSchemaSnapshot(ctx context.Context, where string, args ...interface{}) (*SchemaSnapshot, error) {
+	query := `
+		SELECT snapshot_id, database_id, snapshot_version, schema_structure::text, octet_length(schema_structure::text), created
+		FROM database_schema_snapshots
+		` + where
+
+	var snapshot SchemaSnapshot
+	err := s.db.Pool().QueryRow(ctx, query, args...).Scan(
+		&snapshot.ID,
+		&snapshot.DatabaseID,
+		&snapshot.Version,
+		&snapshot.Schema,
+		&snapshot.SizeBytes,
+		&snapshot.Created,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSnapshotNotFound
+		}
+		s.logger.Errorf("Failed to get schema snapshot: %v", err)
+		return nil, fmt.Errorf("failed to get schema snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -59,6 +60,108 @@ func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
 	}
 }
 
+// SSHTunnel is the jump host through which the anchor reaches an instance and its databases.
+// The password and key passphrase are stored encrypted like the instance password. The private
+// key is PEM contents or the path of a key file on the anchor node, like the SSL key, and is
+// stored encrypted as a secret, being longer than a password can be.
+type SSHTunnel struct {
+	Host                     string `json:"host"`
+	Port                     int    `json:"port,omitempty"`
+	Username                 string `json:"username"`
+	PrivateKey               string `json:"privateKey,omitempty"`
+	PrivateKeyPassphrase     string `json:"privateKeyPassphrase,omitempty"`
+	Password                 string `json:"password,omitempty"`
+	UseAgent                 bool   `json:"useAgent,omitempty"`
+	HostKey                  string `json:"hostKey,omitempty"`
+	InsecureSkipHostKeyCheck bool   `json:"insecureSkipHostKeyCheck,omitempty"`
+}
+
+// EncryptSSHTunnel validates the SSH tunnel of an instance and returns it with its private key,
+// password and key passphrase encrypted for storage.
+func EncryptSSHTunnel(tenantID string, tunnel SSHTunnel) (*SSHTunnel, error) {
+	if tunnel.Host == "" || tunnel.Username == "" {
+		return nil, errors.New("ssh tunnel host and username are required")
+	}
+	if tunnel.Port < 0 || tunnel.Port > 65535 {
+		return nil, fmt.Errorf("invalid ssh tunnel port %d", tunnel.Port)
+	}
+	if tunnel.HostKey == "" && !tunnel.InsecureSkipHostKeyCheck {
+		return nil, errors.New("ssh tunnel host key is required unless host key checking is skipped")
+	}
+	if tunnel.PrivateKey == "" && tunnel.Password == "" && !tunnel.UseAgent {
+		return nil, errors.New("ssh tunnel needs a private key, a password or the ssh agent")
+	}
+
+	if tunnel.PrivateKey != "" {
+		privateKey, err := encryption.EncryptSecret(tenantID, tunnel.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt ssh tunnel private key: %w", err)
+		}
+		tunnel.PrivateKey = privateKey
+	}
+	if tunnel.Password != "" {
+		password, err := encryption.EncryptPassword(tenantID, tunnel.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt ssh tunnel password: %w", err)
+		}
+		tunnel.Password = password
+	}
+	if tunnel.PrivateKeyPassphrase != "" {
+		passphrase, err := encryption.EncryptPassword(tenantID, tunnel.PrivateKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt ssh tunnel key passphrase: %w", err)
+		}
+		tunnel.PrivateKeyPassphrase = passphrase
+	}
+	return &tunnel, nil
+}
+
+// IAM authentication providers of instances
+var iamProviders = []string{"aws-rds", "gcp-cloudsql", "azure-ad"}
+
+// IAMAuth authenticates the anchor to an instance and its databases with short-lived tokens of a
+// cloud identity provider in place of the password. It holds no secrets: the anchor obtains the
+// tokens with the credentials of its environment.
+type IAMAuth struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	ClientID string `json:"clientId,omitempty"`
+}
+
+// ValidateIAMAuth validates the IAM authentication of an instance.
+func ValidateIAMAuth(auth IAMAuth) error {
+	for _, provider := range iamProviders {
+		if auth.Provider == provider {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown iam provider %q, expected one of %s", auth.Provider, strings.Join(iamProviders, ", "))
+}
+
+// KerberosAuth authenticates the anchor to an instance and its databases with Kerberos tickets in
+// place of the password. It holds no secrets: the keytab or credential cache is a file on the
+// anchor node.
+type KerberosAuth struct {
+	Principal string `json:"principal,omitempty"`
+	Realm     string `json:"realm,omitempty"`
+	Keytab    string `json:"keytab,omitempty"`
+	CCache    string `json:"ccache,omitempty"`
+	Krb5Conf  string `json:"krb5Conf,omitempty"`
+	SPN       string `json:"spn,omitempty"`
+}
+
+// ValidateKerberosAuth validates the Kerberos authentication of an instance.
+func ValidateKerberosAuth(auth KerberosAuth) error {
+	switch {
+	case auth.Keytab == "" && auth.CCache == "":
+		return fmt.Errorf("a keytab or a credential cache is required")
+	case auth.Keytab != "" && auth.CCache != "":
+		return fmt.Errorf("a keytab and a credential cache cannot both be set")
+	}
+	return nil
+}
+
 // Instance represents an instance in the system
 type Instance struct {
 	ID                string
@@ -83,6 +186,10 @@ type Instance struct {
 	SSLCert           *string
 	SSLKey            *string
 	SSLRootCert       *string
+	SSHTunnel         *SSHTunnel
+	IAMAuth           *IAMAuth
+	SocketPath        *string
+	KerberosAuth      *KerberosAuth
 	Metadata          map[string]interface{}
 	PolicyIDs         []string
 	OwnerID           string
@@ -204,7 +311,7 @@ func (s *Service) Create(ctx context.Context, tenantID, workspaceName, name, des
 	query := `
 		INSERT INTO instances (tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_metadata, policy_ids, owner_id)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
-		RETURNING instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
+		RETURNING instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_ssh_tunnel, instance_iam_auth, instance_socket_path, instance_kerberos_auth, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
 	`
 
 	var instance Instance
@@ -239,6 +346,10 @@ func (s *Service) Create(ctx context.Context, tenantID, workspaceName, name, des
 		&instance.SSLCert,
 		&instance.SSLKey,
 		&instance.SSLRootCert,
+		&instance.SSHTunnel,
+		&instance.IAMAuth,
+		&instance.SocketPath,
+		&instance.KerberosAuth,
 		&metadataJSON,
 		&policyIDsArray,
 		&instance.OwnerID,
@@ -281,7 +392,7 @@ func (s *Service) Get(ctx context.Context, tenantID, workspaceName, name string)
 	s.logger.Infof("Found workspace_id='%s' for name='%s'", workspaceID, workspaceName)
 
 	query := `
-		SELECT instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
+		SELECT instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_ssh_tunnel, instance_iam_auth, instance_socket_path, instance_kerberos_auth, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
 		FROM instances
 		WHERE tenant_id = $1 AND workspace_id = $2 AND instance_name = $3
 	`
@@ -313,6 +424,10 @@ func (s *Service) Get(ctx context.Context, tenantID, workspaceName, name string)
 		&instance.SSLCert,
 		&instance.SSLKey,
 		&instance.SSLRootCert,
+		&instance.SSHTunnel,
+		&instance.IAMAuth,
+		&instance.SocketPath,
+		&instance.KerberosAuth,
 		&metadataJSON,
 		&policyIDsArray,
 		&instance.OwnerID,
@@ -355,7 +470,7 @@ func (s *Service) List(ctx context.Context, tenantID, workspaceName string) ([]*
 	}
 
 	query := `
-		SELECT instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
+		SELECT instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_ssh_tunnel, instance_iam_auth, instance_socket_path, instance_kerberos_auth, instance_metadata, policy_ids, owner_id, instance_status_message, status, created, updated
 		FROM instances
 		WHERE tenant_id = $1 AND workspace_id = $2
 		ORDER BY instance_name
@@ -397,6 +512,10 @@ func (s *Service) List(ctx context.Context, tenantID, workspaceName string) ([]*
 			&instance.SSLCert,
 			&instance.SSLKey,
 			&instance.SSLRootCert,
+			&instance.SSHTunnel,
+			&instance.IAMAuth,
+			&instance.SocketPath,
+			&instance.KerberosAuth,
 			&metadataJSON,
 			&policyIDsArray,
 			&instance.OwnerID,
@@ -668,7 +787,7 @@ func (s *Service) Update(ctx context.Context, tenantID, workspaceName, name stri
 	}
 
 	// Add the WHERE clause
-	query += fmt.Sprintf(" WHERE tenant_id = $%d AND workspace_id = $%d AND instance_id = $%d RETURNING instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, owner_id, instance_status_message, status, created, updated", argIndex, argIndex+1, argIndex+2)
+	query += fmt.Sprintf(" WHERE tenant_id = $%d AND workspace_id = $%d AND instance_id = $%d RETURNING instance_id, tenant_id, workspace_id, environment_id, connected_to_node_id, instance_name, instance_description, instance_type, instance_vendor, instance_version, instance_unique_identifier, instance_host, instance_port, instance_username, instance_password, instance_system_db_name, instance_enabled, instance_ssl, instance_ssl_mode, instance_ssl_cert, instance_ssl_key, instance_ssl_root_cert, instance_ssh_tunnel, instance_iam_auth, instance_socket_path, instance_kerberos_auth, owner_id, instance_status_message, status, created, updated", argIndex, argIndex+1, argIndex+2)
 	args = append(args, tenantID, workspaceID, instanceID)
 
 	var instance Instance
@@ -695,6 +814,10 @@ func (s *Service) Update(ctx context.Context, tenantID, workspaceName, name stri
 		&instance.SSLCert,
 		&instance.SSLKey,
 		&instance.SSLRootCert,
+		&instance.SSHTunnel,
+		&instance.IAMAuth,
+		&instance.SocketPath,
+		&instance.KerberosAuth,
 		&instance.OwnerID,
 		&instance.StatusMessage,
 		&instance.Status,
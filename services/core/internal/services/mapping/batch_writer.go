@@ -0,0 +1,159 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of a batch writer
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+)
+
+// transientMessages are parts of the error messages of target databases for failures that
+// usually succeed when retried, as opposed to rows the target rejects
+var transientMessages = []string{
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"bad connection",
+	"i/o timeout",
+	"timed out",
+	"timeout expired",
+	"deadlock",
+	"could not serialize",
+	"serialization failure",
+	"lock wait timeout",
+	"too many connections",
+	"temporarily unavailable",
+	"try again",
+	"server is shutting down",
+	"database system is starting up",
+}
+
+// InsertFunc writes rows into a target table and returns the number of rows written. The rows
+// of a call are written together or not at all.
+type InsertFunc func(ctx context.Context, rows []json.RawMessage) (int64, error)
+
+// RejectedRow is a row the target rejected, with the error it was rejected with
+type RejectedRow struct {
+	Row   json.RawMessage `json:"row"`
+	Error string          `json:"error"`
+}
+
+// BatchResult is the outcome of writing a batch
+type BatchResult struct {
+	RowsWritten int64
+	Retries     int           // Transient failures that were retried
+	Rejected    []RejectedRow // Rows that failed on their own, and were not written
+}
+
+// BatchWriter writes batches of rows into a target. Transient failures are retried with
+// exponential backoff; a batch that fails otherwise is split in halves, recursively, so the rows
+// the target accepts are written and only the rows that fail on their own are rejected.
+type BatchWriter struct {
+	Insert         InsertFunc
+	MaxAttempts    int           // Attempts of a write before a transient failure is returned
+	InitialBackoff time.Duration // Delay before the first retry, doubled for each further retry
+	MaxBackoff     time.Duration
+}
+
+// NewBatchWriter creates a batch writer with the default retries
+func NewBatchWriter(insert InsertFunc) *BatchWriter {
+	return &BatchWriter{
+		Insert:         insert,
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// Write writes a batch of rows. It fails only when the context is done or a transient failure
+// persists after the retries; rows written before are not rolled back.
+func (w *BatchWriter) Write(ctx context.Context, rows []json.RawMessage) (*BatchResult, error) {
+	result := &BatchResult{}
+	if len(rows) == 0 {
+		return result, nil
+	}
+	return result, w.write(ctx, rows, result)
+}
+
+func (w *BatchWriter) write(ctx context.Context, rows []json.RawMessage, result *BatchResult) error {
+	written, err := w.insert(ctx, rows, result)
+	if err == nil {
+		result.RowsWritten += written
+		return nil
+	}
+	if ctx.Err() != nil || IsTransient(err) {
+		return err
+	}
+
+	if len(rows) == 1 {
+		result.Rejected = append(result.Rejected, RejectedRow{Row: rows[0], Error: err.Error()})
+		return nil
+	}
+	half := len(rows) / 2
+	if err := w.write(ctx, rows[:half], result); err != nil {
+		return err
+	}
+	return w.write(ctx, rows[half:], result)
+}
+
+// insert writes rows, retrying transient failures with backoff
+func (w *BatchWriter) insert(ctx context.Context, rows []json.RawMessage, result *BatchResult) (int64, error) {
+	backoff := w.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		written, err := w.Insert(ctx, rows)
+		if err == nil || !IsTransient(err) {
+			return written, err
+		}
+		if attempt >= w.MaxAttempts {
+			return 0, fmt.Errorf("%d rows failed after %d attempts: %w", len(rows), attempt, err)
+		}
+
+		result.Retries++
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if w.MaxBackoff > 0 && backoff > w.MaxBackoff {
+			backoff = w.MaxBackoff
+		}
+	}
+}
+
+// IsTransient reports whether a write failed for a reason that retrying may resolve, such as an
+// unavailable service or a lost connection, rather than because of the rows written
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return true
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, m := range transientMessages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+	return false
+}
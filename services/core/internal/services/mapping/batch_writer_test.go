@@ -0,0 +1,123 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTarget inserts rows in a transaction, failing the whole call when one of the rows is bad
+type fakeTarget struct {
+	bad        map[string]bool
+	transient  int // Calls failing with a transient error before the target accepts writes
+	calls      int
+	rowsStored int64
+}
+
+func (f *fakeTarget) insert(ctx context.Context, rows []json.RawMessage) (int64, error) {
+	f.calls++
+	if f.transient > 0 {
+		f.transient--
+		return 0, status.Error(codes.Unavailable, "connection refused")
+	}
+	for _, row := range rows {
+		if f.bad[string(row)] {
+			return 0, fmt.Errorf("duplicate key value violates unique constraint: %s", row)
+		}
+	}
+	f.rowsStored += int64(len(rows))
+	return int64(len(rows)), nil
+}
+
+func testRows(n int) []json.RawMessage {
+	rows := make([]json.RawMessage, n)
+	for i := range rows {
+		rows[i] = json.RawMessage(fmt.Sprintf(`{"id":%d}`, i+1))
+	}
+	return rows
+}
+
+func testWriter(target *fakeTarget) *BatchWriter {
+	w := NewBatchWriter(target.insert)
+	w.InitialBackoff = 0
+	return w
+}
+
+func TestBatchWriterIsolatesBadRows(t *testing.T) {
+	target := &fakeTarget{bad: map[string]bool{`{"id":3}`: true, `{"id":6}`: true}}
+
+	result, err := testWriter(target).Write(context.Background(), testRows(8))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if result.RowsWritten != 6 || target.rowsStored != 6 {
+		t.Errorf("rows written = %d, stored = %d, want 6", result.RowsWritten, target.rowsStored)
+	}
+	if len(result.Rejected) != 2 || string(result.Rejected[0].Row) != `{"id":3}` || string(result.Rejected[1].Row) != `{"id":6}` {
+		t.Fatalf("rejected = %+v, want rows 3 and 6", result.Rejected)
+	}
+	if result.Rejected[0].Error == "" {
+		t.Error("a rejected row should have its error")
+	}
+	if result.Retries != 0 {
+		t.Errorf("retries = %d, want 0", result.Retries)
+	}
+}
+
+func TestBatchWriterRetriesTransientFailures(t *testing.T) {
+	target := &fakeTarget{transient: 2}
+
+	result, err := testWriter(target).Write(context.Background(), testRows(4))
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if result.RowsWritten != 4 || result.Retries != 2 || len(result.Rejected) != 0 {
+		t.Errorf("result = %+v, want 4 rows written after 2 retries", result)
+	}
+	if target.calls != 3 {
+		t.Errorf("calls = %d, want 3", target.calls)
+	}
+}
+
+func TestBatchWriterGivesUpOnPersistentTransientFailures(t *testing.T) {
+	target := &fakeTarget{transient: 100}
+	w := testWriter(target)
+	w.MaxAttempts = 3
+
+	result, err := w.Write(context.Background(), testRows(4))
+	if err == nil {
+		t.Fatal("a transient failure persisting after the retries should fail the write")
+	}
+	if len(result.Rejected) != 0 {
+		t.Errorf("rows should not be rejected because of a transient failure, got %d", len(result.Rejected))
+	}
+	if target.calls != 3 {
+		t.Errorf("calls = %d, want 3", target.calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{status.Error(codes.Unavailable, "unavailable"), true},
+		{status.Error(codes.InvalidArgument, "bad request"), false},
+		{errors.New("pq: deadlock detected"), true},
+		{errors.New("dial tcp 10.0.0.1:5432: connect: connection refused"), true},
+		{errors.New(`pq: null value in column "id" violates not-null constraint`), false},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
+)
+
+// RuleReference is a table or a column of a database a rule reads or writes
+type RuleReference struct {
+	URI        string
+	Side       string // source, target
+	DatabaseID string
+	Table      string
+	Column     string // Empty for a table
+}
+
+// String describes the object, e.g. "source column users.email"
+func (r RuleReference) String() string {
+	if r.Column == "" {
+		return fmt.Sprintf("%s table %s", r.Side, r.Table)
+	}
+	return fmt.Sprintf("%s column %s.%s", r.Side, r.Table, r.Column)
+}
+
+// RuleBreakage is why a rule is broken: tables or columns it references no longer exist. Broken
+// rules are kept with their mappings, but left out of their runs until they are repaired.
+type RuleBreakage struct {
+	Missing  []string  `json:"missing"` // URIs of the missing objects
+	Reason   string    `json:"reason"`
+	Detected time.Time `json:"detected"`
+}
+
+// RuleReferences returns the tables and columns of databases the metadata of a rule references,
+// from its item URIs and its resource URIs. Other resources, such as MCP resources and stream
+// topics, are left out.
+func RuleReferences(metadata map[string]interface{}) []RuleReference {
+	var references []RuleReference
+	for _, side := range []string{"source", "target"} {
+		uris := stringList(metadata[side+"_uris"])
+		if resourceURI, _ := metadata[side+"_resource_uri"].(string); resourceURI != "" && !containsString(uris, resourceURI) {
+			uris = append(uris, resourceURI)
+		}
+		for _, uri := range uris {
+			addr, err := resource.ParseResourceURI(uri)
+			if err != nil || !addr.IsDatabase() || addr.DatabaseID == "" || addr.ObjectName == "" {
+				continue
+			}
+			reference := RuleReference{URI: uri, Side: side, DatabaseID: addr.DatabaseID, Table: addr.ObjectName}
+			if segment := addr.LastPathSegment(); segment != nil {
+				reference.Column = segment.Name
+			}
+			references = append(references, reference)
+		}
+	}
+	return references
+}
+
+// MissingReferences returns the references of the metadata of a rule to tables and columns
+// missing from the schemas of their databases, by database ID. References to databases
+// without a schema are not checked.
+func MissingReferences(metadata map[string]interface{}, schemas map[string]SchemaColumns) []RuleReference {
+	var missing []RuleReference
+	for _, reference := range RuleReferences(metadata) {
+		schema := schemas[reference.DatabaseID]
+		if schema == nil {
+			continue
+		}
+		columns, tableExists := schema[reference.Table]
+		if !tableExists || (reference.Column != "" && !columns[reference.Column]) {
+			missing = append(missing, reference)
+		}
+	}
+	return missing
+}
+
+// NewRuleBreakage describes the breakage of a rule by its missing references, or returns nil
+// when none is missing
+func NewRuleBreakage(missing []RuleReference, detected time.Time) *RuleBreakage {
+	if len(missing) == 0 {
+		return nil
+	}
+	breakage := &RuleBreakage{Detected: detected.UTC()}
+	for _, reference := range missing {
+		breakage.Missing = append(breakage.Missing, reference.URI)
+	}
+	breakage.Reason = missing[0].String() + " no longer exists"
+	if len(missing) > 1 {
+		breakage.Reason += fmt.Sprintf(", with %d other referenced objects", len(missing)-1)
+	}
+	return breakage
+}
+
+// Breakage returns why the rule is broken, or nil if it is not
+func (r *Rule) Breakage() *RuleBreakage {
+	value, ok := r.Metadata["broken"]
+	if !ok || value == nil {
+		return nil
+	}
+	// The metadata is read back from JSON
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var breakage RuleBreakage
+	if err := json.Unmarshal(data, &breakage); err != nil || len(breakage.Missing) == 0 {
+		return nil
+	}
+	return &breakage
+}
+
+// RunnableRules splits the rules of a mapping into those a run executes and the broken rules it
+// leaves out
+func RunnableRules(rules []*Rule) (runnable, broken []*Rule) {
+	for _, rule := range rules {
+		if rule.Breakage() != nil {
+			broken = append(broken, rule)
+			continue
+		}
+		runnable = append(runnable, rule)
+	}
+	return runnable, broken
+}
+
+// RepointReference re-points the references of the metadata of a rule to a missing object to
+// another table or column URI of the same side, in its resource URI, its item URIs and the table
+// and column it names
+func RepointReference(metadata map[string]interface{}, missing RuleReference, uri string) error {
+	addr, err := resource.ParseResourceURI(uri)
+	if err != nil || !addr.IsDatabase() || addr.ObjectName == "" {
+		return fmt.Errorf("%s is not the URI of a table or a column", uri)
+	}
+	column := ""
+	if segment := addr.LastPathSegment(); segment != nil {
+		column = segment.Name
+	}
+
+	side := missing.Side
+	if resourceURI, _ := metadata[side+"_resource_uri"].(string); resourceURI == missing.URI {
+		metadata[side+"_resource_uri"] = uri
+	}
+	if uris := stringList(metadata[side+"_uris"]); len(uris) > 0 {
+		repointed := make([]interface{}, len(uris))
+		for i, item := range uris {
+			if item == missing.URI {
+				item = uri
+			}
+			repointed[i] = item
+		}
+		metadata[side+"_uris"] = repointed
+	}
+	table, _ := metadata[side+"_table"].(string)
+	tableColumn, _ := metadata[side+"_column"].(string)
+	if table == missing.Table && tableColumn == missing.Column {
+		metadata[side+"_table"] = addr.ObjectName
+		if column != "" {
+			metadata[side+"_column"] = column
+		}
+	}
+	return nil
+}
+
+// SetRuleBreakage marks a rule broken, or clears the mark with a nil breakage
+func (s *Service) SetRuleBreakage(ctx context.Context, ruleID string, breakage *RuleBreakage) error {
+	if breakage == nil {
+		_, err := s.db.Pool().Exec(ctx, `
+			UPDATE mapping_rules SET mapping_rule_metadata = mapping_rule_metadata - 'broken', updated = CURRENT_TIMESTAMP
+			WHERE mapping_rule_id = $1
+		`, ruleID)
+		if err != nil {
+			return fmt.Errorf("failed to clear broken mapping rule: %w", err)
+		}
+		return nil
+	}
+
+	breakageJSON, err := json.Marshal(breakage)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		UPDATE mapping_rules SET mapping_rule_metadata = jsonb_set(mapping_rule_metadata, '{broken}', $2::jsonb), updated = CURRENT_TIMESTAMP
+		WHERE mapping_rule_id = $1
+	`, ruleID, string(breakageJSON))
+	if err != nil {
+		return fmt.Errorf("failed to mark mapping rule broken: %w", err)
+	}
+	return nil
+}
+
+// NotifyBrokenRule records the breakage of a rule in the audit log for the owners of the rule
+// and of the mappings it is attached to
+func (s *Service) NotifyBrokenRule(ctx context.Context, rule *Rule, mappings []*Mapping, breakage *RuleBreakage) error {
+	details := map[string]interface{}{
+		"missing":  breakage.Missing,
+		"reason":   breakage.Reason,
+		"mappings": mappingNames(mappings),
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	owners := []string{rule.OwnerID}
+	for _, m := range mappings {
+		if !containsString(owners, m.OwnerID) {
+			owners = append(owners, m.OwnerID)
+		}
+	}
+	for _, owner := range owners {
+		if owner == "" {
+			continue
+		}
+		_, err := s.db.Pool().Exec(ctx, `
+			INSERT INTO audit_log (tenant_id, user_id, action, resource_type, resource_id, resource_name, change_details, status)
+			VALUES ($1, $2, 'mapping_rule_broken', 'mapping_rule', $3, $4, $5, 'STATUS_WARNING')
+		`, rule.TenantID, owner, rule.ID, rule.Name, detailsJSON)
+		if err != nil {
+			return fmt.Errorf("failed to notify owner %s of broken mapping rule %s: %w", owner, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// mappingNames returns the names of mappings
+func mappingNames(mappings []*Mapping) []string {
+	names := make([]string, len(mappings))
+	for i, m := range mappings {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// containsString reports whether a list contains a string
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package mapping
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMissingReferences(t *testing.T) {
+	column := func(db, table, name string) string {
+		return "redb://data/database/" + db + "/table/" + table + "/column/" + name
+	}
+	metadata := map[string]interface{}{
+		"source_resource_uri": column("db_1", "users", "first_name"),
+		"source_uris":         []interface{}{column("db_1", "users", "first_name"), column("db_1", "users", "middle_name")},
+		"target_resource_uri": column("db_2", "people", "full_name"),
+	}
+
+	references := RuleReferences(metadata)
+	if len(references) != 3 {
+		t.Fatalf("expected 3 references, got %+v", references)
+	}
+
+	schemas := map[string]SchemaColumns{
+		"db_1": {"users": {"first_name": true}},
+		// The schema of db_2 is unknown, its references are not checked
+	}
+	missing := MissingReferences(metadata, schemas)
+	if len(missing) != 1 || missing[0].Side != "source" || missing[0].Table != "users" || missing[0].Column != "middle_name" {
+		t.Fatalf("expected the middle_name column to be missing, got %+v", missing)
+	}
+
+	schemas["db_2"] = SchemaColumns{"persons": {"full_name": true}}
+	missing = MissingReferences(metadata, schemas)
+	if len(missing) != 2 {
+		t.Fatalf("expected 2 missing references, got %+v", missing)
+	}
+
+	breakage := NewRuleBreakage(missing, time.Now())
+	if breakage.Reason != "source column users.middle_name no longer exists, with 1 other referenced objects" {
+		t.Errorf("unexpected reason %q", breakage.Reason)
+	}
+	if NewRuleBreakage(nil, time.Now()) != nil {
+		t.Error("expected no breakage without missing references")
+	}
+}
+
+func TestRunnableRules(t *testing.T) {
+	breakage := NewRuleBreakage([]RuleReference{{
+		URI: "redb://data/database/db_1/table/users/column/fax", Side: "source", DatabaseID: "db_1", Table: "users", Column: "fax",
+	}}, time.Now())
+
+	// The metadata of stored rules is read back from JSON
+	data, err := json.Marshal(map[string]interface{}{"broken": breakage})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		t.Fatal(err)
+	}
+	broken := &Rule{Name: "fax", Metadata: metadata}
+	working := &Rule{Name: "email", Metadata: map[string]interface{}{}}
+
+	if got := broken.Breakage(); got == nil || got.Reason != "source column users.fax no longer exists" {
+		t.Fatalf("unexpected breakage %+v", got)
+	}
+
+	runnable, skipped := RunnableRules([]*Rule{broken, working})
+	if len(runnable) != 1 || runnable[0] != working || len(skipped) != 1 || skipped[0] != broken {
+		t.Errorf("expected only the broken rule to be skipped, got %+v and %+v", runnable, skipped)
+	}
+}
+
+func TestRepointReference(t *testing.T) {
+	oldURI := "redb://data/database/db_1/table/users/column/email"
+	newURI := "redb://data/database/db_1/table/users/column/email_address"
+	metadata := map[string]interface{}{
+		"source_resource_uri": oldURI,
+		"source_uris":         []interface{}{oldURI},
+		"source_table":        "users",
+		"source_column":       "email",
+		"target_resource_uri": "redb://data/database/db_2/table/people/column/email",
+	}
+	missing := RuleReferences(metadata)[0]
+
+	if err := RepointReference(metadata, missing, newURI); err != nil {
+		t.Fatal(err)
+	}
+	if metadata["source_resource_uri"] != newURI || metadata["source_column"] != "email_address" {
+		t.Errorf("expected the source to be re-pointed, got %+v", metadata)
+	}
+	if uris := stringList(metadata["source_uris"]); len(uris) != 1 || uris[0] != newURI {
+		t.Errorf("expected the source URIs to be re-pointed, got %+v", uris)
+	}
+	if metadata["target_resource_uri"] != "redb://data/database/db_2/table/people/column/email" {
+		t.Errorf("expected the target to be left alone, got %+v", metadata["target_resource_uri"])
+	}
+
+	if err := RepointReference(metadata, missing, "mcp://resource/users"); err == nil {
+		t.Error("expected an error for a URI that is not a column or a table")
+	}
+}
@@ -0,0 +1,317 @@
+package mapping
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
+	"gopkg.in/yaml.v3"
+)
+
+// MappingDocumentVersion is the version of the format of mapping documents
+const MappingDocumentVersion = "redb/v1"
+
+// Formats mapping documents are encoded in
+const (
+	MappingDocumentFormatYAML = "yaml"
+	MappingDocumentFormatJSON = "json"
+)
+
+// MappingDocument is a table or database mapping with its rules, in a declarative form that does
+// not depend on the workspace it was exported from: databases are referenced by name rather than
+// by ID, so that the document can be kept in git and applied to other workspaces. Columns are
+// referenced as database.table.column; other resources, such as MCP resources, by their URIs.
+type MappingDocument struct {
+	Version      string                `json:"version" yaml:"version"`
+	Name         string                `json:"name" yaml:"name"`
+	Description  string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Scope        string                `json:"scope" yaml:"scope"`   // database, table
+	Source       string                `json:"source" yaml:"source"` // database[.table]
+	Target       string                `json:"target" yaml:"target"` // database[.table], or mcp://resource
+	RowFilter    string                `json:"row_filter,omitempty" yaml:"row_filter,omitempty"`
+	ConflictKeys map[string][]string   `json:"conflict_keys,omitempty" yaml:"conflict_keys,omitempty"`
+	TablePins    []TablePin            `json:"table_pins,omitempty" yaml:"table_pins,omitempty"`
+	Rules        []MappingDocumentRule `json:"rules" yaml:"rules"`
+}
+
+// MappingDocumentRule is a rule of a mapping document, in the order the mapping applies it
+type MappingDocumentRule struct {
+	Name                  string                 `json:"name" yaml:"name"`
+	Description           string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Sources               []string               `json:"sources" yaml:"sources"`
+	Targets               []string               `json:"targets" yaml:"targets"`
+	Transformation        string                 `json:"transformation,omitempty" yaml:"transformation,omitempty"`
+	TransformationChain   []string               `json:"transformation_chain,omitempty" yaml:"transformation_chain,omitempty"`
+	TransformationOptions map[string]interface{} `json:"transformation_options,omitempty" yaml:"transformation_options,omitempty"`
+	RowFilter             string                 `json:"row_filter,omitempty" yaml:"row_filter,omitempty"`
+}
+
+// NewMappingDocument describes a mapping and its rules, in order, as a mapping document. The
+// database names are those of the databases the mapping and its rules reference, by ID.
+func NewMappingDocument(m *Mapping, rules []*Rule, databaseNames map[string]string) (*MappingDocument, error) {
+	if m.SourceType != "database" && m.SourceType != "table" {
+		return nil, fmt.Errorf("only table and database mappings can be exported, mapping %s maps a %s", m.Name, m.SourceType)
+	}
+
+	source, err := PortableReference(m.SourceIdentifier, databaseNames)
+	if err != nil {
+		return nil, fmt.Errorf("source of mapping %s: %w", m.Name, err)
+	}
+	target, err := PortableReference(m.TargetIdentifier, databaseNames)
+	if err != nil {
+		return nil, fmt.Errorf("target of mapping %s: %w", m.Name, err)
+	}
+
+	doc := &MappingDocument{
+		Version:      MappingDocumentVersion,
+		Name:         m.Name,
+		Description:  m.Description,
+		Scope:        m.SourceType,
+		Source:       source,
+		Target:       target,
+		RowFilter:    m.RowFilter(),
+		ConflictKeys: m.ConflictKeys(),
+		TablePins:    m.TablePins(),
+		Rules:        make([]MappingDocumentRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		docRule := MappingDocumentRule{
+			Name:        rule.Name,
+			Description: rule.Description,
+		}
+		for _, side := range []struct {
+			uris       []string
+			references *[]string
+		}{
+			{ruleURIs(rule.Metadata, "source"), &docRule.Sources},
+			{ruleURIs(rule.Metadata, "target"), &docRule.Targets},
+		} {
+			for _, uri := range side.uris {
+				reference, err := PortableReference(uri, databaseNames)
+				if err != nil {
+					return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+				}
+				*side.references = append(*side.references, reference)
+			}
+		}
+
+		transformationName, _ := rule.Metadata["transformation_name"].(string)
+		if chain := TransformationChain(transformationName); chain != nil {
+			docRule.TransformationChain = chain
+		} else {
+			docRule.Transformation = transformationName
+		}
+		if options, ok := rule.Metadata["transformation_options"].(map[string]interface{}); ok && len(options) > 0 {
+			docRule.TransformationOptions = options
+		}
+		docRule.RowFilter, _ = rule.Metadata["row_filter"].(string)
+
+		doc.Rules = append(doc.Rules, docRule)
+	}
+	return doc, nil
+}
+
+// ruleURIs returns the URIs of the resources a side of a rule reads or writes: its item URIs,
+// unless the rule was re-pointed since it was added, else its resource URI
+func ruleURIs(metadata map[string]interface{}, side string) []string {
+	resourceURI, _ := metadata[side+"_resource_uri"].(string)
+	uris := stringList(metadata[side+"_uris"])
+	if len(uris) > 0 && containsString(uris, resourceURI) {
+		return uris
+	}
+	if resourceURI == "" {
+		return nil
+	}
+	return []string{resourceURI}
+}
+
+// PortableReference returns the reference of a mapping document to a resource URI: a database as
+// its name, a table as database.table and a column as database.table.column. URIs of other
+// resources are returned as they are.
+func PortableReference(uri string, databaseNames map[string]string) (string, error) {
+	if !strings.HasPrefix(uri, string(resource.ProtocolDatabase)+":") {
+		return uri, nil
+	}
+
+	databaseID, table, column, err := splitDatabaseURI(uri)
+	if err != nil {
+		return "", err
+	}
+	name, ok := databaseNames[databaseID]
+	if !ok {
+		return "", fmt.Errorf("database %s of %s is not in the workspace", databaseID, uri)
+	}
+
+	reference := name
+	if table != "" {
+		reference += "." + table
+	}
+	if column != "" {
+		reference += "." + column
+	}
+	return reference, nil
+}
+
+// splitDatabaseURI returns the database ID, and the table and column, of a database, table or
+// column URI
+func splitDatabaseURI(uri string) (databaseID, table, column string, err error) {
+	addr, parseErr := resource.ParseResourceURI(uri)
+	if parseErr != nil {
+		// The resource parser requires an object; a database URI has none
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, string(resource.ProtocolDatabase)+":"), "//")
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		if len(parts) == 3 && parts[1] == "database" && parts[2] != "" {
+			return parts[2], "", "", nil
+		}
+		return "", "", "", fmt.Errorf("invalid resource URI %s: %w", uri, parseErr)
+	}
+	if addr.ObjectType != resource.ObjectTypeTable {
+		return "", "", "", fmt.Errorf("resource URI %s does not reference a table or a column", uri)
+	}
+	if segment := addr.LastPathSegment(); segment != nil {
+		column = segment.Name
+	}
+	return addr.DatabaseID, addr.ObjectName, column, nil
+}
+
+// ResolveColumnReference returns the resource URI of a database.table.column reference of a
+// mapping document, with the database IDs of the workspace it is applied to, by name. URIs of
+// other resources are returned as they are.
+func ResolveColumnReference(reference string, databaseIDs map[string]string) (string, error) {
+	if strings.Contains(reference, ":/") {
+		return reference, nil
+	}
+
+	parts := strings.Split(reference, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid column %q: expected database.table.column", reference)
+	}
+	databaseID, ok := databaseIDs[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("database %s of column %s not found in the workspace", parts[0], reference)
+	}
+	return fmt.Sprintf("redb://data/database/%s/table/%s/column/%s", databaseID, parts[1], parts[2]), nil
+}
+
+// RenameDatabases replaces the names of databases the document references, for a workspace
+// whose databases are named differently
+func (d *MappingDocument) RenameDatabases(names map[string]string) {
+	if len(names) == 0 {
+		return
+	}
+	rename := func(reference string) string {
+		if strings.Contains(reference, ":/") {
+			return reference
+		}
+		database, rest, _ := strings.Cut(reference, ".")
+		if name, ok := names[database]; ok {
+			return strings.TrimSuffix(name+"."+rest, ".")
+		}
+		return reference
+	}
+
+	d.Source = rename(d.Source)
+	d.Target = rename(d.Target)
+	for i := range d.Rules {
+		for j := range d.Rules[i].Sources {
+			d.Rules[i].Sources[j] = rename(d.Rules[i].Sources[j])
+		}
+		for j := range d.Rules[i].Targets {
+			d.Rules[i].Targets[j] = rename(d.Rules[i].Targets[j])
+		}
+	}
+}
+
+// ImportedRuleName returns the name of a rule of a mapping document imported into a workspace
+// whose rules take the given names: the name of the rule if it is free, else the name prefixed
+// with the name of the mapping, numbered if that is taken too
+func ImportedRuleName(mappingName, ruleName string, taken map[string]bool) string {
+	if !taken[ruleName] {
+		return ruleName
+	}
+	name := mappingName + "_" + ruleName
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s_%s_%d", mappingName, ruleName, i)
+	}
+	return name
+}
+
+// Validate checks that a mapping document can be applied: its version is known, it has a name, a
+// scope, a source and a target, and each of its rules has a unique name, sources and targets, and
+// a transformation or a transformation chain but not both
+func (d *MappingDocument) Validate() error {
+	if d.Version != MappingDocumentVersion {
+		return fmt.Errorf("unsupported mapping document version %q, expected %q", d.Version, MappingDocumentVersion)
+	}
+	if d.Name == "" {
+		return fmt.Errorf("mapping document has no name")
+	}
+	if d.Scope != "database" && d.Scope != "table" {
+		return fmt.Errorf("invalid scope %q: must be database or table", d.Scope)
+	}
+	if d.Source == "" || d.Target == "" {
+		return fmt.Errorf("mapping document must have a source and a target")
+	}
+	if err := ValidateTablePins(d.TablePins); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(d.Rules))
+	for i, rule := range d.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %s is defined twice", rule.Name)
+		}
+		names[rule.Name] = true
+		if len(rule.Sources) == 0 || len(rule.Targets) == 0 {
+			return fmt.Errorf("rule %s must have sources and targets", rule.Name)
+		}
+		if rule.Transformation != "" && len(rule.TransformationChain) > 0 {
+			return fmt.Errorf("rule %s has both a transformation and a transformation chain", rule.Name)
+		}
+	}
+	return nil
+}
+
+// EncodeMappingDocument encodes a mapping document as YAML, or as JSON
+func EncodeMappingDocument(doc *MappingDocument, format string) ([]byte, error) {
+	switch format {
+	case MappingDocumentFormatYAML, "":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode mapping document: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode mapping document: %w", err)
+		}
+		return buf.Bytes(), nil
+	case MappingDocumentFormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode mapping document: %w", err)
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: must be yaml or json", format)
+	}
+}
+
+// DecodeMappingDocument decodes a mapping document from YAML or JSON, and validates it
+func DecodeMappingDocument(data []byte) (*MappingDocument, error) {
+	var doc MappingDocument
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid mapping document: %w", err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mapping document: %w", err)
+	}
+	return &doc, nil
+}
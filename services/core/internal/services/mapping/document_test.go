@@ -0,0 +1,188 @@
+package mapping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPortableReference(t *testing.T) {
+	names := map[string]string{"db_1": "shop"}
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"redb://data/database/db_1", "shop", false},
+		{"redb://data/database/db_1/table/users", "shop.users", false},
+		{"redb://data/database/db_1/table/users/column/email", "shop.users.email", false},
+		{"redb:/data/database/db_1/table/users/column/email", "shop.users.email", false},
+		{"mcp://users_resource", "mcp://users_resource", false},
+		{"redb://data/database/db_2/table/users/column/email", "", true},
+	}
+	for _, tt := range tests {
+		got, err := PortableReference(tt.uri, names)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("PortableReference(%q) = %q, %v; want %q, error %t", tt.uri, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolveColumnReference(t *testing.T) {
+	ids := map[string]string{"shop": "db_9"}
+
+	uri, err := ResolveColumnReference("shop.users.email", ids)
+	if err != nil || uri != "redb://data/database/db_9/table/users/column/email" {
+		t.Fatalf("ResolveColumnReference = %q, %v", uri, err)
+	}
+	if back, err := PortableReference(uri, map[string]string{"db_9": "shop"}); err != nil || back != "shop.users.email" {
+		t.Errorf("PortableReference of the resolved URI = %q, %v", back, err)
+	}
+	if uri, err := ResolveColumnReference("mcp://users_resource", ids); err != nil || uri != "mcp://users_resource" {
+		t.Errorf("URI not kept: %q, %v", uri, err)
+	}
+	for _, reference := range []string{"shop.users", "warehouse.users.email", "shop..email"} {
+		if _, err := ResolveColumnReference(reference, ids); err == nil {
+			t.Errorf("ResolveColumnReference(%q) succeeded", reference)
+		}
+	}
+}
+
+func TestMappingDocumentRoundTrip(t *testing.T) {
+	m := &Mapping{
+		Name:             "users_to_people",
+		Description:      "Users to people",
+		SourceType:       "table",
+		SourceIdentifier: "redb://data/database/db_1/table/users",
+		TargetIdentifier: "redb://data/database/db_2/table/people",
+		MappingObject: map[string]interface{}{
+			"row_filter":    "region = 'EU'",
+			"conflict_keys": map[string]interface{}{"people": []interface{}{"id"}},
+		},
+	}
+	rules := []*Rule{
+		{
+			Name: "users_id_to_people_id",
+			Metadata: map[string]interface{}{
+				"source_resource_uri": "redb://data/database/db_1/table/users/column/id",
+				"target_resource_uri": "redb://data/database/db_2/table/people/column/id",
+				"transformation_name": "direct_mapping",
+				"match_score":         0.95,
+			},
+		},
+		{
+			Name: "full_name",
+			Metadata: map[string]interface{}{
+				"source_resource_uri":    "redb://data/database/db_1/table/users/column/first_name",
+				"source_uris":            []interface{}{"redb://data/database/db_1/table/users/column/first_name", "redb://data/database/db_1/table/users/column/last_name"},
+				"target_resource_uri":    "redb://data/database/db_2/table/people/column/full_name",
+				"transformation_name":    "concat|trim",
+				"transformation_options": map[string]interface{}{"separator": " "},
+				"row_filter":             "last_name IS NOT NULL",
+			},
+		},
+	}
+
+	doc, err := NewMappingDocument(m, rules, map[string]string{"db_1": "shop", "db_2": "crm"})
+	if err != nil {
+		t.Fatalf("NewMappingDocument: %v", err)
+	}
+	want := &MappingDocument{
+		Version:      MappingDocumentVersion,
+		Name:         "users_to_people",
+		Description:  "Users to people",
+		Scope:        "table",
+		Source:       "shop.users",
+		Target:       "crm.people",
+		RowFilter:    "region = 'EU'",
+		ConflictKeys: map[string][]string{"people": {"id"}},
+		Rules: []MappingDocumentRule{
+			{Name: "users_id_to_people_id", Sources: []string{"shop.users.id"}, Targets: []string{"crm.people.id"}, Transformation: "direct_mapping"},
+			{
+				Name:                  "full_name",
+				Sources:               []string{"shop.users.first_name", "shop.users.last_name"},
+				Targets:               []string{"crm.people.full_name"},
+				TransformationChain:   []string{"concat", "trim"},
+				TransformationOptions: map[string]interface{}{"separator": " "},
+				RowFilter:             "last_name IS NOT NULL",
+			},
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Fatalf("document = %+v, want %+v", doc, want)
+	}
+
+	for _, format := range []string{MappingDocumentFormatYAML, MappingDocumentFormatJSON} {
+		data, err := EncodeMappingDocument(doc, format)
+		if err != nil {
+			t.Fatalf("EncodeMappingDocument(%s): %v", format, err)
+		}
+		decoded, err := DecodeMappingDocument(data)
+		if err != nil {
+			t.Fatalf("DecodeMappingDocument(%s): %v", format, err)
+		}
+		if !reflect.DeepEqual(decoded, want) {
+			t.Errorf("%s round trip = %+v, want %+v", format, decoded, want)
+		}
+	}
+}
+
+func TestMappingDocumentRenameDatabases(t *testing.T) {
+	doc := &MappingDocument{
+		Source: "shop",
+		Target: "crm",
+		Rules: []MappingDocumentRule{
+			{Sources: []string{"shop.users.id"}, Targets: []string{"crm.people.id", "mcp://people"}},
+		},
+	}
+	doc.RenameDatabases(map[string]string{"shop": "shop_staging"})
+
+	if doc.Source != "shop_staging" || doc.Target != "crm" {
+		t.Errorf("source, target = %s, %s", doc.Source, doc.Target)
+	}
+	rule := doc.Rules[0]
+	if rule.Sources[0] != "shop_staging.users.id" || rule.Targets[0] != "crm.people.id" || rule.Targets[1] != "mcp://people" {
+		t.Errorf("rule = %+v", rule)
+	}
+}
+
+func TestImportedRuleName(t *testing.T) {
+	taken := map[string]bool{"id": true, "users_id": true, "email": true}
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"name", "name"},
+		{"email", "users_email"},
+		{"id", "users_id_2"},
+	}
+	for _, tt := range tests {
+		if got := ImportedRuleName("users", tt.rule, taken); got != tt.want {
+			t.Errorf("ImportedRuleName(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeMappingDocumentInvalid(t *testing.T) {
+	valid := "version: redb/v1\nname: m\nscope: table\nsource: shop.users\ntarget: crm.people\n"
+	tests := []struct {
+		name     string
+		document string
+		wantErr  string
+	}{
+		{"version", strings.Replace(valid, "redb/v1", "redb/v0", 1), "unsupported mapping document version"},
+		{"scope", strings.Replace(valid, "scope: table", "scope: stream", 1), "invalid scope"},
+		{"unknown field", valid + "owner: someone\n", "field owner not found"},
+		{"duplicate rule", valid + "rules:\n  - {name: r, sources: [a.b.c], targets: [d.e.f]}\n  - {name: r, sources: [a.b.c], targets: [d.e.g]}\n", "defined twice"},
+		{"no targets", valid + "rules:\n  - {name: r, sources: [a.b.c]}\n", "must have sources and targets"},
+		{"chain and transformation", valid + "rules:\n  - {name: r, sources: [a.b.c], targets: [d.e.f], transformation: trim, transformation_chain: [trim]}\n", "both a transformation"},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeMappingDocument([]byte(tt.document)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+	if _, err := DecodeMappingDocument([]byte(valid)); err != nil {
+		t.Errorf("valid document: %v", err)
+	}
+}
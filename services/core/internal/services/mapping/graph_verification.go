@@ -0,0 +1,260 @@
+package mapping
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Defaults of the verification of the relationships a data copy reads from a graph database
+const (
+	DefaultGraphEdgeSamples   = 10000
+	DefaultMaxGraphDivergence = 0.1
+)
+
+const (
+	graphPathStarts = 50 // Nodes the paths are sampled from
+	graphPathHops   = 3  // Longest paths sampled
+)
+
+// Edge is a relationship read from a graph database or an edge table, as the IDs of the nodes
+// it connects
+type Edge struct {
+	From string
+	To   string
+}
+
+// GraphProfile describes the structure of a sample of edges without the IDs of its nodes, which
+// differ between databases, so that the samples of the source and the target can be compared
+type GraphProfile struct {
+	Edges      int
+	Nodes      int
+	SelfLoops  int
+	OutDegrees map[int]int // Nodes by number of outgoing edges
+	InDegrees  map[int]int // Nodes by number of incoming edges
+
+	// Hops is the share of the nodes reached from the sampled start nodes at one, two and three
+	// hops, following the edges in their direction
+	Hops []float64
+}
+
+// ProfileGraph returns the profile of a sample of edges. Paths are followed from nodes spread
+// over the sample ordered by degree, so that both sides start from nodes of the same degrees.
+func ProfileGraph(edges []Edge) GraphProfile {
+	profile := GraphProfile{
+		Edges:      len(edges),
+		OutDegrees: make(map[int]int),
+		InDegrees:  make(map[int]int),
+		Hops:       make([]float64, graphPathHops),
+	}
+
+	outgoing := make(map[string][]string)
+	inDegree := make(map[string]int)
+	for _, edge := range edges {
+		if edge.From == edge.To {
+			profile.SelfLoops++
+		}
+		outgoing[edge.From] = append(outgoing[edge.From], edge.To)
+		inDegree[edge.To]++
+		if _, ok := inDegree[edge.From]; !ok {
+			inDegree[edge.From] = 0
+		}
+	}
+
+	nodes := make([]string, 0, len(inDegree))
+	for node, in := range inDegree {
+		nodes = append(nodes, node)
+		profile.OutDegrees[len(outgoing[node])]++
+		profile.InDegrees[in]++
+	}
+	profile.Nodes = len(nodes)
+	if len(nodes) == 0 {
+		return profile
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if len(outgoing[a]) != len(outgoing[b]) {
+			return len(outgoing[a]) > len(outgoing[b])
+		}
+		if inDegree[a] != inDegree[b] {
+			return inDegree[a] > inDegree[b]
+		}
+		return a < b
+	})
+	starts := graphPathStarts
+	if starts > len(nodes) {
+		starts = len(nodes)
+	}
+	reached := make([]int, graphPathHops)
+	total := 0
+	for i := 0; i < starts; i++ {
+		start := nodes[i*len(nodes)/starts]
+		visited := map[string]bool{start: true}
+		frontier := []string{start}
+		for hop := 0; hop < graphPathHops && len(frontier) > 0; hop++ {
+			var next []string
+			for _, node := range frontier {
+				for _, to := range outgoing[node] {
+					if !visited[to] {
+						visited[to] = true
+						next = append(next, to)
+					}
+				}
+			}
+			reached[hop] += len(next)
+			total += len(next)
+			frontier = next
+		}
+	}
+	if total > 0 {
+		for hop, count := range reached {
+			profile.Hops[hop] = float64(count) / float64(total)
+		}
+	}
+	return profile
+}
+
+// GraphVerification is the outcome of the comparison of the edges of the source and the target
+// of a copy
+type GraphVerification struct {
+	Source GraphProfile
+	Target GraphProfile
+
+	// Complete is set when neither sample reached the limit, so that they hold every edge and
+	// their counts are compared too
+	Complete bool
+
+	// Divergences are total variation distances, from 0 for the same distribution to 1 for
+	// distributions without anything in common
+	OutDegreeDivergence float64
+	InDegreeDivergence  float64
+	PathDivergence      float64
+
+	Issues []string // Why the verification failed, empty when it passed
+	Passed bool
+}
+
+// GraphVerifier compares edges sampled from the source and the target of a copy
+type GraphVerifier struct {
+	Limit         int     // Edges sampled from each side
+	MaxDivergence float64 // Largest divergence for the verification to pass
+}
+
+// NewGraphVerifier creates a verifier of samples of up to limit edges with the default divergence
+func NewGraphVerifier(limit int) *GraphVerifier {
+	return &GraphVerifier{Limit: limit, MaxDivergence: DefaultMaxGraphDivergence}
+}
+
+// Verify compares the structure of edges sampled from the source and the target
+func (v *GraphVerifier) Verify(source, target []Edge) GraphVerification {
+	result := GraphVerification{
+		Source:   ProfileGraph(source),
+		Target:   ProfileGraph(target),
+		Complete: len(source) < v.Limit && len(target) < v.Limit,
+	}
+	if len(target) == 0 {
+		result.Issues = append(result.Issues, fmt.Sprintf("no edges were found in the target, the source has %d", len(source)))
+		return result
+	}
+
+	result.OutDegreeDivergence = degreeDivergence(result.Source.OutDegrees, result.Target.OutDegrees)
+	result.InDegreeDivergence = degreeDivergence(result.Source.InDegrees, result.Target.InDegrees)
+	result.PathDivergence = hopDivergence(result.Source.Hops, result.Target.Hops)
+
+	if result.Complete {
+		if result.Source.Edges != result.Target.Edges || result.Source.Nodes != result.Target.Nodes {
+			result.Issues = append(result.Issues, fmt.Sprintf("the source has %d edges between %d nodes, the target %d edges between %d nodes",
+				result.Source.Edges, result.Source.Nodes, result.Target.Edges, result.Target.Nodes))
+		}
+		if result.Source.SelfLoops != result.Target.SelfLoops {
+			result.Issues = append(result.Issues, fmt.Sprintf("the source has %d edges from a node to itself, the target %d",
+				result.Source.SelfLoops, result.Target.SelfLoops))
+		}
+	}
+	if result.OutDegreeDivergence > v.MaxDivergence || result.InDegreeDivergence > v.MaxDivergence {
+		result.Issues = append(result.Issues, fmt.Sprintf("the degree distributions diverge by %.2f outgoing and %.2f incoming, above %.2f, check the direction and the endpoints of the edges",
+			result.OutDegreeDivergence, result.InDegreeDivergence, v.MaxDivergence))
+	}
+	if result.PathDivergence > v.MaxDivergence {
+		result.Issues = append(result.Issues, fmt.Sprintf("the paths of up to %d hops diverge by %.2f, above %.2f, edges connect other nodes than in the source",
+			graphPathHops, result.PathDivergence, v.MaxDivergence))
+	}
+	result.Passed = len(result.Issues) == 0
+	return result
+}
+
+// degreeDivergence returns the total variation distance of two distributions of nodes by degree
+func degreeDivergence(a, b map[int]int) float64 {
+	totalA, totalB := 0, 0
+	for _, n := range a {
+		totalA += n
+	}
+	for _, n := range b {
+		totalB += n
+	}
+	if totalA == 0 || totalB == 0 {
+		if totalA == totalB {
+			return 0
+		}
+		return 1
+	}
+	distance := 0.0
+	for degree, n := range a {
+		distance += math.Abs(float64(n)/float64(totalA) - float64(b[degree])/float64(totalB))
+	}
+	for degree, n := range b {
+		if _, ok := a[degree]; !ok {
+			distance += float64(n) / float64(totalB)
+		}
+	}
+	return distance / 2
+}
+
+// hopDivergence returns the total variation distance of two distributions of reached nodes by hop
+func hopDivergence(a, b []float64) float64 {
+	distance := 0.0
+	for i := range a {
+		distance += math.Abs(a[i] - b[i])
+	}
+	return distance / 2
+}
+
+// edgeColumns are the pairs of columns an edge table stores the nodes of an edge in, as the
+// graph translations and the relationship copies name them
+var edgeColumns = [][2]string{
+	{"source_node_id", "target_node_id"},
+	{"_sourceId", "_targetId"},
+	{"_startNodeId", "_endNodeId"},
+	{"source_id", "target_id"},
+	{"from_id", "to_id"},
+	{"start_id", "end_id"},
+	{"source", "target"},
+	{"from", "to"},
+}
+
+// EdgesFromRows returns the edges stored in rows of an edge table, from the first pair of edge
+// columns the rows have. It returns false if the rows have none.
+func EdgesFromRows(rows []map[string]interface{}) ([]Edge, bool) {
+	if len(rows) == 0 {
+		return nil, true
+	}
+	for _, columns := range edgeColumns {
+		if _, ok := rows[0][columns[0]]; !ok {
+			continue
+		}
+		if _, ok := rows[0][columns[1]]; !ok {
+			continue
+		}
+		edges := make([]Edge, 0, len(rows))
+		for _, row := range rows {
+			from, to := row[columns[0]], row[columns[1]]
+			if from == nil || to == nil {
+				continue
+			}
+			edges = append(edges, Edge{From: fmt.Sprint(from), To: fmt.Sprint(to)})
+		}
+		return edges, true
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+package mapping
+
+import (
+	"fmt"
+	"testing"
+)
+
+// cycle returns the edges of a directed cycle through the nodes prefix0 to prefixN-1
+func cycle(prefix string, n int) []Edge {
+	edges := make([]Edge, n)
+	for i := range edges {
+		edges[i] = Edge{From: fmt.Sprintf("%s%d", prefix, i), To: fmt.Sprintf("%s%d", prefix, (i+1)%n)}
+	}
+	return edges
+}
+
+// star returns the edges from a center to n leaves
+func star(prefix string, n int) []Edge {
+	edges := make([]Edge, n)
+	for i := range edges {
+		edges[i] = Edge{From: prefix + "center", To: fmt.Sprintf("%s%d", prefix, i)}
+	}
+	return edges
+}
+
+func TestGraphVerifierPasses(t *testing.T) {
+	// The same graph under other node IDs, as a copy into another database gives
+	source := append(star("a", 4), cycle("b", 5)...)
+	target := append(cycle("node-", 5), star("n", 4)...)
+
+	result := NewGraphVerifier(DefaultGraphEdgeSamples).Verify(source, target)
+	if !result.Passed {
+		t.Fatalf("verification failed: %v", result.Issues)
+	}
+	if !result.Complete || result.OutDegreeDivergence != 0 || result.InDegreeDivergence != 0 || result.PathDivergence != 0 {
+		t.Errorf("result = %+v, want complete samples without divergence", result)
+	}
+	if result.Target.Edges != 9 || result.Target.Nodes != 10 {
+		t.Errorf("target has %d edges and %d nodes, want 9 and 10", result.Target.Edges, result.Target.Nodes)
+	}
+}
+
+func TestGraphVerifierDetectsReversedEdges(t *testing.T) {
+	source := star("a", 5)
+	target := make([]Edge, len(source))
+	for i, edge := range source {
+		target[i] = Edge{From: edge.To, To: edge.From}
+	}
+
+	result := NewGraphVerifier(DefaultGraphEdgeSamples).Verify(source, target)
+	if result.Passed {
+		t.Fatal("verification passed although the edges are reversed")
+	}
+	// The center becomes the only node without outgoing edges, and the leaves have one each
+	if result.OutDegreeDivergence < 0.8 || result.InDegreeDivergence < 0.8 {
+		t.Errorf("degree divergences = %v, %v, want 5/6", result.OutDegreeDivergence, result.InDegreeDivergence)
+	}
+}
+
+func TestGraphVerifierDetectsRewiredEdges(t *testing.T) {
+	// Every node keeps one incoming and one outgoing edge, but a cycle of six becomes two
+	// cycles of three, which only the paths tell apart
+	source := cycle("a", 6)
+	target := append(cycle("b", 3), cycle("c", 3)...)
+
+	result := NewGraphVerifier(DefaultGraphEdgeSamples).Verify(source, target)
+	if result.Passed {
+		t.Fatal("verification passed although the edges connect other nodes")
+	}
+	if result.OutDegreeDivergence != 0 || result.InDegreeDivergence != 0 {
+		t.Errorf("degree divergences = %v, %v, want 0", result.OutDegreeDivergence, result.InDegreeDivergence)
+	}
+	if d := result.PathDivergence; d < 0.33 || d > 0.34 {
+		t.Errorf("path divergence = %v, want 1/3", d)
+	}
+	if len(result.Issues) != 1 {
+		t.Errorf("issues = %v, want the paths", result.Issues)
+	}
+}
+
+func TestGraphVerifierComparesCompleteCounts(t *testing.T) {
+	source := cycle("a", 40)
+	target := cycle("b", 39)
+
+	// Samples holding every edge are compared by count as well
+	result := NewGraphVerifier(DefaultGraphEdgeSamples).Verify(source, target)
+	if result.Passed || len(result.Issues) != 1 {
+		t.Fatalf("issues = %v, want the counts", result.Issues)
+	}
+
+	// Samples cut at the limit are not
+	result = NewGraphVerifier(39).Verify(source, target)
+	if !result.Passed || result.Complete {
+		t.Errorf("result = %+v, want a passed verification of partial samples", result)
+	}
+
+	result = NewGraphVerifier(39).Verify(source, nil)
+	if result.Passed {
+		t.Error("verification passed without edges in the target")
+	}
+}
+
+func TestEdgesFromRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "source_node_id": float64(10), "target_node_id": float64(11), "since": "2020"},
+		{"id": 2, "source_node_id": float64(11), "target_node_id": nil},
+	}
+	edges, ok := EdgesFromRows(rows)
+	if !ok || len(edges) != 1 || edges[0] != (Edge{From: "10", To: "11"}) {
+		t.Errorf("edges = %v, %t, want 10 -> 11", edges, ok)
+	}
+
+	if _, ok := EdgesFromRows([]map[string]interface{}{{"id": 1, "name": "x"}}); ok {
+		t.Error("rows without edge columns gave edges")
+	}
+}
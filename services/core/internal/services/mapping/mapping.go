@@ -1345,6 +1345,96 @@ func (s *Service) UpdateValidationStatus(ctx context.Context, mappingID string,
 	return nil
 }
 
+// ConflictKeys returns the conflict keys of the mapping: for each target table, the columns
+// identifying a row. Replicated inserts and updates of these tables are applied as upserts.
+func (m *Mapping) ConflictKeys() map[string][]string {
+	raw, ok := m.MappingObject["conflict_keys"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	conflictKeys := make(map[string][]string, len(raw))
+	for table, value := range raw {
+		columns, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, column := range columns {
+			if name, ok := column.(string); ok && name != "" {
+				conflictKeys[table] = append(conflictKeys[table], name)
+			}
+		}
+	}
+	return conflictKeys
+}
+
+// SetConflictKeys replaces the conflict keys of a mapping. An empty map clears them.
+func (s *Service) SetConflictKeys(ctx context.Context, mappingID string, conflictKeys map[string][]string) error {
+	for table, columns := range conflictKeys {
+		if table == "" || len(columns) == 0 {
+			return fmt.Errorf("conflict key of table %q must have at least one column", table)
+		}
+	}
+
+	var err error
+	if len(conflictKeys) == 0 {
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = COALESCE(mapping_object, '{}') - 'conflict_keys',
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $1
+		`, mappingID)
+	} else {
+		conflictKeysJSON, marshalErr := json.Marshal(conflictKeys)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal conflict keys: %w", marshalErr)
+		}
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = jsonb_set(COALESCE(mapping_object, '{}'), '{conflict_keys}', $1::jsonb),
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $2
+		`, conflictKeysJSON, mappingID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update conflict keys: %w", err)
+	}
+
+	return nil
+}
+
+// RowFilter returns the row filter of the mapping, the condition on source columns that rows
+// must match to be replicated, or "" when all rows are replicated
+func (m *Mapping) RowFilter() string {
+	rowFilter, _ := m.MappingObject["row_filter"].(string)
+	return rowFilter
+}
+
+// SetRowFilter replaces the row filter of a mapping. An empty filter removes it.
+func (s *Service) SetRowFilter(ctx context.Context, mappingID, rowFilter string) error {
+	var err error
+	if rowFilter == "" {
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = COALESCE(mapping_object, '{}') - 'row_filter',
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $1
+		`, mappingID)
+	} else {
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = jsonb_set(COALESCE(mapping_object, '{}'), '{row_filter}', to_jsonb($1::text)),
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $2
+		`, rowFilter, mappingID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update row filter: %w", err)
+	}
+
+	return nil
+}
+
 // InvalidateMapping invalidates a mapping's validation status (sets validated to false and clears validation data)
 func (s *Service) InvalidateMapping(ctx context.Context, mappingID string) error {
 	query := `
@@ -0,0 +1,123 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel/resource"
+)
+
+// Each correction of a generated rule moves the scores of later matches of its columns by
+// feedbackStep, up to maxFeedbackAdjustment either way
+const (
+	feedbackStep          = 0.15
+	maxFeedbackAdjustment = 0.45
+)
+
+// MatchFeedback counts how often users of a workspace accepted and rejected the match of a
+// source and a target column when correcting the rules the matcher generated: removing a
+// generated rule or re-pointing its source or target rejects its match, and the columns it is
+// re-pointed to are accepted.
+type MatchFeedback struct {
+	SourceColumn string
+	TargetColumn string
+	Accepted     int
+	Rejected     int
+	Updated      time.Time
+}
+
+// Adjustment is what the matcher adds to the scores of matches of the columns
+func (f *MatchFeedback) Adjustment() float64 {
+	adjustment := feedbackStep * float64(f.Accepted-f.Rejected)
+	return math.Max(-maxFeedbackAdjustment, math.Min(maxFeedbackAdjustment, adjustment))
+}
+
+// GeneratedMatch returns the columns of the match the matcher generated a rule for, or false
+// when the rule was not generated or was changed by users since
+func GeneratedMatch(rule *Rule) (sourceColumn, targetColumn string, ok bool) {
+	if matchType, _ := rule.Metadata["match_type"].(string); matchType != "auto_generated" {
+		return "", "", false
+	}
+	sourceColumn, _ = rule.Metadata["source_column"].(string)
+	targetColumn, _ = rule.Metadata["target_column"].(string)
+	return sourceColumn, targetColumn, sourceColumn != "" && targetColumn != ""
+}
+
+// ColumnOfURI returns the table and the column of a database column URI, or false for the URIs
+// of other resources
+func ColumnOfURI(uri string) (table, column string, ok bool) {
+	addr, err := resource.ParseResourceURI(uri)
+	if err != nil || !addr.IsDatabase() || addr.ObjectName == "" {
+		return "", "", false
+	}
+	segment := addr.LastPathSegment()
+	if segment == nil || segment.Name == "" {
+		return "", "", false
+	}
+	return addr.ObjectName, segment.Name, true
+}
+
+// RecordMatchFeedback counts the match of a source and a target column as accepted or rejected
+// by users of a workspace. Column names are compared case-insensitively.
+func (s *Service) RecordMatchFeedback(ctx context.Context, workspaceID, sourceColumn, targetColumn string, accepted bool) error {
+	acceptedCount, rejectedCount := 0, 1
+	if accepted {
+		acceptedCount, rejectedCount = 1, 0
+	}
+	_, err := s.db.Pool().Exec(ctx, `
+		INSERT INTO match_feedback (workspace_id, source_column, target_column, accepted, rejected)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (workspace_id, source_column, target_column)
+		DO UPDATE SET accepted = match_feedback.accepted + EXCLUDED.accepted,
+			rejected = match_feedback.rejected + EXCLUDED.rejected, updated = CURRENT_TIMESTAMP
+	`, workspaceID, strings.ToLower(sourceColumn), strings.ToLower(targetColumn), acceptedCount, rejectedCount)
+	if err != nil {
+		return fmt.Errorf("failed to record match feedback: %w", err)
+	}
+	return nil
+}
+
+// ListMatchFeedback returns the match feedback of a workspace, the most recent first
+func (s *Service) ListMatchFeedback(ctx context.Context, workspaceID string) ([]*MatchFeedback, error) {
+	rows, err := s.db.Pool().Query(ctx, `
+		SELECT source_column, target_column, accepted, rejected, updated
+		FROM match_feedback
+		WHERE workspace_id = $1
+		ORDER BY updated DESC, source_column, target_column
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list match feedback: %w", err)
+	}
+	defer rows.Close()
+
+	var feedback []*MatchFeedback
+	for rows.Next() {
+		var f MatchFeedback
+		if err := rows.Scan(&f.SourceColumn, &f.TargetColumn, &f.Accepted, &f.Rejected, &f.Updated); err != nil {
+			return nil, fmt.Errorf("failed to scan match feedback: %w", err)
+		}
+		feedback = append(feedback, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list match feedback: %w", err)
+	}
+	return feedback, nil
+}
+
+// ResetMatchFeedback forgets the match feedback of a workspace: all of it, or that of a source
+// and/or a target column when given. It returns the number of matches forgotten.
+func (s *Service) ResetMatchFeedback(ctx context.Context, workspaceID, sourceColumn, targetColumn string) (int64, error) {
+	result, err := s.db.Pool().Exec(ctx, `
+		DELETE FROM match_feedback
+		WHERE workspace_id = $1
+		  AND ($2 = '' OR source_column = $2)
+		  AND ($3 = '' OR target_column = $3)
+	`, workspaceID, strings.ToLower(sourceColumn), strings.ToLower(targetColumn))
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset match feedback: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
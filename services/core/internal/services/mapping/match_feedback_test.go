@@ -0,0 +1,45 @@
+package mapping
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatchFeedbackAdjustment(t *testing.T) {
+	tests := []struct {
+		accepted, rejected int
+		want               float64
+	}{
+		{0, 1, -0.15},
+		{2, 0, 0.3},
+		{1, 1, 0},
+		{0, 5, -0.45},
+		{9, 1, 0.45},
+	}
+	for _, tt := range tests {
+		feedback := &MatchFeedback{Accepted: tt.accepted, Rejected: tt.rejected}
+		if got := feedback.Adjustment(); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("accepted %d, rejected %d: adjustment %g, want %g", tt.accepted, tt.rejected, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedMatch(t *testing.T) {
+	generated := &Rule{Metadata: map[string]interface{}{"match_type": "auto_generated", "source_column": "email", "target_column": "contact"}}
+	if source, target, ok := GeneratedMatch(generated); !ok || source != "email" || target != "contact" {
+		t.Errorf("generated rule: got %s, %s, %v", source, target, ok)
+	}
+	userDefined := &Rule{Metadata: map[string]interface{}{"match_type": "user_defined", "source_column": "email", "target_column": "contact"}}
+	if _, _, ok := GeneratedMatch(userDefined); ok {
+		t.Error("user-defined rule reported as generated")
+	}
+
+	if table, column, ok := ColumnOfURI("redb://data/database/db_1/table/users/column/email"); !ok || table != "users" || column != "email" {
+		t.Errorf("column URI: got %s, %s, %v", table, column, ok)
+	}
+	for _, uri := range []string{"redb://data/database/db_1/table/users", "mcp://users_resource", "not a uri"} {
+		if _, _, ok := ColumnOfURI(uri); ok {
+			t.Errorf("%s reported as a column URI", uri)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Built-in match profiles. Mappings created without a profile, in a workspace without a default
+// profile, use the profile of their scope: table or database.
+const (
+	MatchProfileTable      = "table"       // Tuned for matching the columns of two tables
+	MatchProfileDatabase   = "database"    // Tuned for matching the tables of two databases, by name first
+	MatchProfileStrict     = "strict"      // Only close matches get rules
+	MatchProfileLenient    = "lenient"     // Loose matches get rules too
+	MatchProfileCrossTable = "cross-table" // Columns may match columns of other tables
+)
+
+var (
+	// ErrMatchProfileNotFound is returned for a profile that is neither built in nor in the workspace
+	ErrMatchProfileNotFound = errors.New("match profile not found")
+	// ErrBuiltInMatchProfile is returned when modifying or deleting a built-in profile
+	ErrBuiltInMatchProfile = errors.New("built-in match profiles cannot be modified or deleted")
+)
+
+// MatchOptions are the thresholds and weights of the matcher generating the rules of new mappings
+type MatchOptions struct {
+	NameSimilarityThreshold  float64 `json:"name_similarity_threshold"`
+	PoorMatchThreshold       float64 `json:"poor_match_threshold"`
+	NameWeight               float64 `json:"name_weight"`
+	TypeWeight               float64 `json:"type_weight"`
+	ClassificationWeight     float64 `json:"classification_weight"`
+	PrivilegedDataWeight     float64 `json:"privileged_data_weight"`
+	TableStructureWeight     float64 `json:"table_structure_weight"`
+	EnableCrossTableMatching bool    `json:"enable_cross_table_matching"`
+	MinRuleScore             float64 `json:"min_rule_score"` // Column matches scoring less get no rule
+}
+
+// MatchProfile is a named set of match options of a workspace
+type MatchProfile struct {
+	Name        string
+	Description string
+	Options     MatchOptions
+	BuiltIn     bool
+	IsDefault   bool // The default profile of the workspace
+	Updated     time.Time
+}
+
+var builtInMatchProfiles = map[string]MatchProfile{
+	MatchProfileTable: {
+		Name:        MatchProfileTable,
+		Description: "Matches the columns of two tables; used for table mappings by default",
+		Options: MatchOptions{
+			NameSimilarityThreshold: 0.3, PoorMatchThreshold: 0.2,
+			NameWeight: 0.4, TypeWeight: 0.3, ClassificationWeight: 0.2, PrivilegedDataWeight: 0.1, TableStructureWeight: 0.3,
+			MinRuleScore: 0.5,
+		},
+	},
+	MatchProfileDatabase: {
+		Name:        MatchProfileDatabase,
+		Description: "Matches the tables of two databases by name first; used for database mappings by default",
+		Options: MatchOptions{
+			NameSimilarityThreshold: 0.2, PoorMatchThreshold: 0.3,
+			NameWeight: 0.6, TypeWeight: 0.15, ClassificationWeight: 0.15, PrivilegedDataWeight: 0.05, TableStructureWeight: 0.05,
+		},
+	},
+	MatchProfileStrict: {
+		Name:        MatchProfileStrict,
+		Description: "Only close matches of name and type get rules",
+		Options: MatchOptions{
+			NameSimilarityThreshold: 0.6, PoorMatchThreshold: 0.5,
+			NameWeight: 0.5, TypeWeight: 0.35, ClassificationWeight: 0.1, PrivilegedDataWeight: 0.05, TableStructureWeight: 0.2,
+			MinRuleScore: 0.75,
+		},
+	},
+	MatchProfileLenient: {
+		Name:        MatchProfileLenient,
+		Description: "Loose matches get rules too; review the generated rules",
+		Options: MatchOptions{
+			NameSimilarityThreshold: 0.15, PoorMatchThreshold: 0.1,
+			NameWeight: 0.4, TypeWeight: 0.2, ClassificationWeight: 0.3, PrivilegedDataWeight: 0.1, TableStructureWeight: 0.3,
+			MinRuleScore: 0.35,
+		},
+	},
+	MatchProfileCrossTable: {
+		Name:        MatchProfileCrossTable,
+		Description: "Columns may match columns of other tables, e.g. for denormalized targets",
+		Options: MatchOptions{
+			NameSimilarityThreshold: 0.3, PoorMatchThreshold: 0.2,
+			NameWeight: 0.4, TypeWeight: 0.3, ClassificationWeight: 0.2, PrivilegedDataWeight: 0.1, TableStructureWeight: 0.1,
+			EnableCrossTableMatching: true, MinRuleScore: 0.5,
+		},
+	},
+}
+
+// BuiltInMatchProfile returns a built-in profile, or nil if there is none of the name
+func BuiltInMatchProfile(name string) *MatchProfile {
+	profile, ok := builtInMatchProfiles[name]
+	if !ok {
+		return nil
+	}
+	profile.BuiltIn = true
+	return &profile
+}
+
+// Validate checks that the thresholds and the minimum rule score are between 0 and 1, and that the
+// weights are not negative and not all 0
+func (o MatchOptions) Validate() error {
+	for name, value := range map[string]float64{
+		"name_similarity_threshold": o.NameSimilarityThreshold,
+		"poor_match_threshold":      o.PoorMatchThreshold,
+		"min_rule_score":            o.MinRuleScore,
+	} {
+		if value < 0 || value > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %g", name, value)
+		}
+	}
+	total := 0.0
+	for name, value := range map[string]float64{
+		"name_weight":            o.NameWeight,
+		"type_weight":            o.TypeWeight,
+		"classification_weight":  o.ClassificationWeight,
+		"privileged_data_weight": o.PrivilegedDataWeight,
+		"table_structure_weight": o.TableStructureWeight,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s cannot be negative, got %g", name, value)
+		}
+		total += value
+	}
+	if total == 0 {
+		return errors.New("at least one weight must be above 0")
+	}
+	return nil
+}
+
+// ListMatchProfiles returns the built-in profiles and the profiles of a workspace, by name
+func (s *Service) ListMatchProfiles(ctx context.Context, workspaceID string) ([]*MatchProfile, error) {
+	defaultName, err := s.defaultMatchProfile(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*MatchProfile
+	for name := range builtInMatchProfiles {
+		profiles = append(profiles, BuiltInMatchProfile(name))
+	}
+
+	rows, err := s.db.Pool().Query(ctx, `
+		SELECT profile_name, profile_description, profile_options, updated
+		FROM match_profiles
+		WHERE workspace_id = $1
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list match profiles: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		profile, err := scanMatchProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list match profiles: %w", err)
+	}
+
+	for _, profile := range profiles {
+		profile.IsDefault = profile.Name == defaultName
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// GetMatchProfile returns a built-in profile or a profile of a workspace
+func (s *Service) GetMatchProfile(ctx context.Context, workspaceID, name string) (*MatchProfile, error) {
+	if profile := BuiltInMatchProfile(name); profile != nil {
+		return profile, nil
+	}
+	row := s.db.Pool().QueryRow(ctx, `
+		SELECT profile_name, profile_description, profile_options, updated
+		FROM match_profiles
+		WHERE workspace_id = $1 AND profile_name = $2
+	`, workspaceID, name)
+	profile, err := scanMatchProfile(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMatchProfileNotFound
+	}
+	return profile, err
+}
+
+// ResolveMatchProfile returns the profile a new mapping of a scope is matched with: the named
+// profile, else the default profile of the workspace, else the built-in profile of the scope
+func (s *Service) ResolveMatchProfile(ctx context.Context, workspaceID, name, scope string) (*MatchProfile, error) {
+	if name == "" {
+		defaultName, err := s.defaultMatchProfile(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		name = defaultName
+	}
+	if name == "" {
+		name = MatchProfileTable
+		if scope == "database" {
+			name = MatchProfileDatabase
+		}
+	}
+	return s.GetMatchProfile(ctx, workspaceID, name)
+}
+
+// SetMatchProfile creates or replaces a profile of a workspace
+func (s *Service) SetMatchProfile(ctx context.Context, workspaceID, name, description string, options MatchOptions) (*MatchProfile, error) {
+	if BuiltInMatchProfile(name) != nil {
+		return nil, ErrBuiltInMatchProfile
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode match options: %w", err)
+	}
+
+	row := s.db.Pool().QueryRow(ctx, `
+		INSERT INTO match_profiles (workspace_id, profile_name, profile_description, profile_options)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (workspace_id, profile_name)
+		DO UPDATE SET profile_description = EXCLUDED.profile_description, profile_options = EXCLUDED.profile_options, updated = CURRENT_TIMESTAMP
+		RETURNING profile_name, profile_description, profile_options, updated
+	`, workspaceID, name, description, optionsJSON)
+	profile, err := scanMatchProfile(row)
+	if err != nil {
+		s.logger.Errorf("Failed to set match profile: %v", err)
+		return nil, err
+	}
+
+	defaultName, err := s.defaultMatchProfile(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	profile.IsDefault = profile.Name == defaultName
+	return profile, nil
+}
+
+// DeleteMatchProfile deletes a profile of a workspace. A workspace whose default profile it was
+// falls back to the built-in profiles of the scopes.
+func (s *Service) DeleteMatchProfile(ctx context.Context, workspaceID, name string) error {
+	if BuiltInMatchProfile(name) != nil {
+		return ErrBuiltInMatchProfile
+	}
+	result, err := s.db.Pool().Exec(ctx, "DELETE FROM match_profiles WHERE workspace_id = $1 AND profile_name = $2", workspaceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete match profile: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrMatchProfileNotFound
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		UPDATE workspaces SET workspace_match_profile = NULL, updated = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1 AND workspace_match_profile = $2
+	`, workspaceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to reset the default match profile: %w", err)
+	}
+	return nil
+}
+
+// SetDefaultMatchProfile makes a profile the default of a workspace. An empty name restores the
+// built-in profiles of the scopes.
+func (s *Service) SetDefaultMatchProfile(ctx context.Context, workspaceID, name string) error {
+	var value interface{}
+	if name != "" {
+		if _, err := s.GetMatchProfile(ctx, workspaceID, name); err != nil {
+			return err
+		}
+		value = name
+	}
+	_, err := s.db.Pool().Exec(ctx, "UPDATE workspaces SET workspace_match_profile = $2, updated = CURRENT_TIMESTAMP WHERE workspace_id = $1", workspaceID, value)
+	if err != nil {
+		return fmt.Errorf("failed to set the default match profile: %w", err)
+	}
+	return nil
+}
+
+// defaultMatchProfile returns the name of the default profile of a workspace, empty if it has none
+func (s *Service) defaultMatchProfile(ctx context.Context, workspaceID string) (string, error) {
+	var name *string
+	err := s.db.Pool().QueryRow(ctx, "SELECT workspace_match_profile FROM workspaces WHERE workspace_id = $1", workspaceID).Scan(&name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the default match profile: %w", err)
+	}
+	if name == nil {
+		return "", nil
+	}
+	return *name, nil
+}
+
+func scanMatchProfile(row pgx.Row) (*MatchProfile, error) {
+	var profile MatchProfile
+	var optionsJSON []byte
+	if err := row.Scan(&profile.Name, &profile.Description, &optionsJSON, &profile.Updated); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(optionsJSON, &profile.Options); err != nil {
+		return nil, fmt.Errorf("failed to decode match profile %s: %w", profile.Name, err)
+	}
+	return &profile, nil
+}
@@ -0,0 +1,67 @@
+package mapping
+
+import (
+	"testing"
+)
+
+func TestBuiltInMatchProfiles(t *testing.T) {
+	for _, name := range []string{MatchProfileTable, MatchProfileDatabase, MatchProfileStrict, MatchProfileLenient, MatchProfileCrossTable} {
+		profile := BuiltInMatchProfile(name)
+		if profile == nil {
+			t.Fatalf("built-in match profile %s not found", name)
+		}
+		if !profile.BuiltIn || profile.Name != name {
+			t.Errorf("%s: got %+v", name, profile)
+		}
+		if err := profile.Options.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if BuiltInMatchProfile("custom") != nil {
+		t.Error("custom should not be a built-in match profile")
+	}
+
+	// The profiles of the scopes keep the options mappings were created with before profiles
+	table := BuiltInMatchProfile(MatchProfileTable).Options
+	if table.NameWeight != 0.4 || table.TypeWeight != 0.3 || table.MinRuleScore != 0.5 || table.EnableCrossTableMatching {
+		t.Errorf("table: got %+v", table)
+	}
+	database := BuiltInMatchProfile(MatchProfileDatabase).Options
+	if database.NameWeight != 0.6 || database.TableStructureWeight != 0.05 || database.MinRuleScore != 0 {
+		t.Errorf("database: got %+v", database)
+	}
+}
+
+func TestMatchOptionsValidate(t *testing.T) {
+	valid := BuiltInMatchProfile(MatchProfileTable).Options
+
+	tests := []struct {
+		name    string
+		modify  func(o *MatchOptions)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(o *MatchOptions) {}},
+		{name: "threshold above 1", modify: func(o *MatchOptions) { o.NameSimilarityThreshold = 1.5 }, wantErr: true},
+		{name: "negative poor match threshold", modify: func(o *MatchOptions) { o.PoorMatchThreshold = -0.1 }, wantErr: true},
+		{name: "minimum rule score above 1", modify: func(o *MatchOptions) { o.MinRuleScore = 2 }, wantErr: true},
+		{name: "negative weight", modify: func(o *MatchOptions) { o.TypeWeight = -1 }, wantErr: true},
+		{name: "weights above 1", modify: func(o *MatchOptions) { o.NameWeight = 3 }},
+		{
+			name: "all weights 0",
+			modify: func(o *MatchOptions) {
+				o.NameWeight, o.TypeWeight, o.ClassificationWeight, o.PrivilegedDataWeight, o.TableStructureWeight = 0, 0, 0, 0, 0
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := valid
+			tt.modify(&options)
+			if err := options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Outcomes of the column matches of a match report
+const (
+	MatchOutcomeRuleCreated   = "rule_created"
+	MatchOutcomeBelowMinScore = "below_min_rule_score" // Scored less than the minimum rule score
+	MatchOutcomePoorMatch     = "poor_match"           // Scored less than the poor match threshold
+	MatchOutcomeUnmatched     = "unmatched"            // No column of the target matched
+	MatchOutcomeKeptRule      = "kept_rule"            // The target column is mapped by a rule users kept
+	MatchOutcomeBlocked       = "blocked_by_policy"    // A transformation policy of the tenant refused the rule
+	MatchOutcomeRuleFailed    = "rule_failed"          // The rule could not be created or attached
+)
+
+// ErrMatchReportNotFound is returned for a mapping whose rules were not generated by the matcher
+var ErrMatchReportNotFound = errors.New("match report not found")
+
+// MatchOverrides replace options of a match profile for a single match. Nil fields keep the
+// options of the profile.
+type MatchOverrides struct {
+	NameSimilarityThreshold  *float64
+	PoorMatchThreshold       *float64
+	NameWeight               *float64
+	TypeWeight               *float64
+	ClassificationWeight     *float64
+	PrivilegedDataWeight     *float64
+	TableStructureWeight     *float64
+	EnableCrossTableMatching *bool
+	MinRuleScore             *float64
+}
+
+// Apply returns the options with the overrides, and the names of the options they replace
+func (o MatchOverrides) Apply(options MatchOptions) (MatchOptions, []string) {
+	var overridden []string
+	for _, override := range []struct {
+		name   string
+		value  *float64
+		option *float64
+	}{
+		{"name_similarity_threshold", o.NameSimilarityThreshold, &options.NameSimilarityThreshold},
+		{"poor_match_threshold", o.PoorMatchThreshold, &options.PoorMatchThreshold},
+		{"name_weight", o.NameWeight, &options.NameWeight},
+		{"type_weight", o.TypeWeight, &options.TypeWeight},
+		{"classification_weight", o.ClassificationWeight, &options.ClassificationWeight},
+		{"privileged_data_weight", o.PrivilegedDataWeight, &options.PrivilegedDataWeight},
+		{"table_structure_weight", o.TableStructureWeight, &options.TableStructureWeight},
+		{"min_rule_score", o.MinRuleScore, &options.MinRuleScore},
+	} {
+		if override.value != nil {
+			*override.option = *override.value
+			overridden = append(overridden, override.name)
+		}
+	}
+	if o.EnableCrossTableMatching != nil {
+		options.EnableCrossTableMatching = *o.EnableCrossTableMatching
+		overridden = append(overridden, "enable_cross_table_matching")
+	}
+	return options, overridden
+}
+
+// MatchReport is the outcome of a run of the matcher generating the rules of a mapping: the
+// scores of the table and column matches, which of them got rules and why the others did not
+type MatchReport struct {
+	MatchProfile     string              `json:"match_profile"`
+	Options          MatchOptions        `json:"options"`              // Options of the profile with the overrides
+	Overridden       []string            `json:"overridden,omitempty"` // Options the request overrode
+	GeneratedAt      time.Time           `json:"generated_at"`
+	OverallScore     float64             `json:"overall_score"`
+	Tables           []MatchReportTable  `json:"tables"`
+	UnmatchedColumns []MatchReportColumn `json:"unmatched_columns,omitempty"`
+	Warnings         []string            `json:"warnings,omitempty"`
+	Outcomes         map[string]int      `json:"outcomes"` // Column matches of each outcome
+}
+
+// MatchReportTable is the match of a source table with a target table
+type MatchReportTable struct {
+	SourceTable        string              `json:"source_table"`
+	TargetTable        string              `json:"target_table"`
+	Score              float64             `json:"score"`
+	PoorMatch          bool                `json:"poor_match,omitempty"`
+	Pinned             bool                `json:"pinned,omitempty"`
+	MatchedColumns     int32               `json:"matched_columns"`
+	TotalSourceColumns int32               `json:"total_source_columns"`
+	TotalTargetColumns int32               `json:"total_target_columns"`
+	Reasons            []string            `json:"reasons,omitempty"`
+	Columns            []MatchReportColumn `json:"columns"`
+}
+
+// MatchReportColumn is the match of a source column, with the rule it got or why it got none
+type MatchReportColumn struct {
+	SourceTable    string   `json:"source_table"`
+	SourceColumn   string   `json:"source_column"`
+	TargetTable    string   `json:"target_table,omitempty"`
+	TargetColumn   string   `json:"target_column,omitempty"`
+	Score          float64  `json:"score"`
+	TypeCompatible bool     `json:"type_compatible"`
+	Reasons        []string `json:"reasons,omitempty"`
+	Outcome        string   `json:"outcome"`
+	RuleName       string   `json:"rule_name,omitempty"`
+	Detail         string   `json:"detail,omitempty"` // Error of a failed rule or refusal of a policy
+}
+
+// RuleOutcome returns why a column match gets no rule under the options, or "" when it gets one
+func RuleOutcome(options MatchOptions, score float64, poorMatch, unmatched bool) string {
+	switch {
+	case unmatched:
+		return MatchOutcomeUnmatched
+	case poorMatch:
+		return MatchOutcomePoorMatch
+	case score < options.MinRuleScore:
+		return MatchOutcomeBelowMinScore
+	}
+	return ""
+}
+
+// SetOutcome sets the outcome of the match of a source column with a target column. A nil
+// report, of a match that is not reported, is left as is.
+func (r *MatchReport) SetOutcome(sourceTable, sourceColumn, targetTable, targetColumn, outcome, ruleName, detail string) {
+	if r == nil {
+		return
+	}
+	for i := range r.Tables {
+		if r.Tables[i].SourceTable != sourceTable || r.Tables[i].TargetTable != targetTable {
+			continue
+		}
+		for j := range r.Tables[i].Columns {
+			column := &r.Tables[i].Columns[j]
+			if column.SourceColumn == sourceColumn && column.TargetColumn == targetColumn {
+				column.Outcome, column.RuleName, column.Detail = outcome, ruleName, detail
+				return
+			}
+		}
+	}
+}
+
+// Finish counts the column matches of each outcome, the unmatched columns included
+func (r *MatchReport) Finish() {
+	r.Outcomes = make(map[string]int)
+	for _, table := range r.Tables {
+		for _, column := range table.Columns {
+			r.Outcomes[column.Outcome]++
+		}
+	}
+	for _, column := range r.UnmatchedColumns {
+		r.Outcomes[column.Outcome]++
+	}
+}
+
+// RulesCreated returns the number of column matches that got a rule
+func (r *MatchReport) RulesCreated() int {
+	return r.Outcomes[MatchOutcomeRuleCreated]
+}
+
+// SetMatchReport stores the match report of a mapping, replacing the report of an earlier match
+func (s *Service) SetMatchReport(ctx context.Context, mappingID string, report *MatchReport) error {
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode match report: %w", err)
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		INSERT INTO mapping_match_reports (mapping_id, match_report)
+		VALUES ($1, $2)
+		ON CONFLICT (mapping_id)
+		DO UPDATE SET match_report = EXCLUDED.match_report, created = CURRENT_TIMESTAMP
+	`, mappingID, reportJSON)
+	if err != nil {
+		return fmt.Errorf("failed to store match report: %w", err)
+	}
+	return nil
+}
+
+// GetMatchReport returns the match report of the last match generating the rules of a mapping
+func (s *Service) GetMatchReport(ctx context.Context, mappingID string) (*MatchReport, error) {
+	var reportJSON []byte
+	err := s.db.Pool().QueryRow(ctx, "SELECT match_report FROM mapping_match_reports WHERE mapping_id = $1", mappingID).Scan(&reportJSON)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrMatchReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match report: %w", err)
+	}
+
+	var report MatchReport
+	if err := json.Unmarshal(reportJSON, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode match report: %w", err)
+	}
+	return &report, nil
+}
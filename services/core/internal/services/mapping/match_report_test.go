@@ -0,0 +1,74 @@
+package mapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchOverridesApply(t *testing.T) {
+	profile := BuiltInMatchProfile(MatchProfileTable).Options
+	minRuleScore, nameWeight, crossTable := 0.3, 0.7, true
+
+	options, overridden := MatchOverrides{MinRuleScore: &minRuleScore, NameWeight: &nameWeight, EnableCrossTableMatching: &crossTable}.Apply(profile)
+	if options.MinRuleScore != 0.3 || options.NameWeight != 0.7 || !options.EnableCrossTableMatching {
+		t.Errorf("overrides not applied: %+v", options)
+	}
+	if options.TypeWeight != profile.TypeWeight || options.NameSimilarityThreshold != profile.NameSimilarityThreshold {
+		t.Errorf("options not overridden changed: %+v", options)
+	}
+	if want := []string{"name_weight", "min_rule_score", "enable_cross_table_matching"}; !reflect.DeepEqual(overridden, want) {
+		t.Errorf("overridden = %v, want %v", overridden, want)
+	}
+
+	if options, overridden := (MatchOverrides{}).Apply(profile); options != profile || overridden != nil {
+		t.Errorf("no overrides changed the options: %+v, %v", options, overridden)
+	}
+}
+
+func TestRuleOutcome(t *testing.T) {
+	options := MatchOptions{MinRuleScore: 0.5}
+	tests := []struct {
+		score     float64
+		poor      bool
+		unmatched bool
+		want      string
+	}{
+		{0.8, false, false, ""},
+		{0.5, false, false, ""},
+		{0.45, false, false, MatchOutcomeBelowMinScore},
+		{0.8, true, false, MatchOutcomePoorMatch},
+		{0, false, true, MatchOutcomeUnmatched},
+	}
+	for _, tt := range tests {
+		if got := RuleOutcome(options, tt.score, tt.poor, tt.unmatched); got != tt.want {
+			t.Errorf("RuleOutcome(%v, %t, %t) = %q, want %q", tt.score, tt.poor, tt.unmatched, got, tt.want)
+		}
+	}
+}
+
+func TestMatchReportOutcomes(t *testing.T) {
+	report := &MatchReport{
+		Tables: []MatchReportTable{{
+			SourceTable: "users",
+			TargetTable: "people",
+			Columns: []MatchReportColumn{
+				{SourceColumn: "id", TargetColumn: "id", Outcome: MatchOutcomeRuleCreated},
+				{SourceColumn: "mail", TargetColumn: "email", Outcome: MatchOutcomeBelowMinScore},
+			},
+		}},
+		UnmatchedColumns: []MatchReportColumn{{SourceTable: "users", SourceColumn: "legacy_flag", Outcome: MatchOutcomeUnmatched}},
+	}
+
+	report.SetOutcome("users", "id", "people", "id", MatchOutcomeRuleFailed, "users_id_to_people_id", "duplicate rule")
+	report.SetOutcome("users", "id", "other", "id", MatchOutcomeRuleCreated, "", "")
+	report.Finish()
+
+	column := report.Tables[0].Columns[0]
+	if column.Outcome != MatchOutcomeRuleFailed || column.RuleName != "users_id_to_people_id" || column.Detail != "duplicate rule" {
+		t.Errorf("outcome not set: %+v", column)
+	}
+	want := map[string]int{MatchOutcomeRuleFailed: 1, MatchOutcomeBelowMinScore: 1, MatchOutcomeUnmatched: 1}
+	if !reflect.DeepEqual(report.Outcomes, want) || report.RulesCreated() != 0 {
+		t.Errorf("outcomes = %v, want %v", report.Outcomes, want)
+	}
+}
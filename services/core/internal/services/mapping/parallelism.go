@@ -0,0 +1,148 @@
+package mapping
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults of the parallelism of copies
+const (
+	DefaultMinParallelism = 1
+	DefaultMaxParallelism = 8
+
+	// MaxErrorRate is the share of failed or retried writes of a window above which the
+	// parallelism is halved
+	MaxErrorRate = 0.1
+	// minThroughputGain is the gain of throughput a higher parallelism must bring to be kept
+	minThroughputGain = 1.05
+	// holdWindows is the number of windows the parallelism is held after a step back, before it
+	// is probed higher again
+	holdWindows = 3
+)
+
+// ParallelismBounds are the lowest and highest number of batches a copy processes at once
+type ParallelismBounds struct {
+	Min int
+	Max int
+}
+
+// Clamp returns the parallelism within the bounds
+func (b ParallelismBounds) Clamp(parallelism int) int {
+	return max(b.Min, min(b.Max, parallelism))
+}
+
+// ParallelismDecision is a change of the parallelism of a copy, with the observations it is based on
+type ParallelismDecision struct {
+	DecidedAt     time.Time
+	From          int
+	To            int
+	RowsPerSecond float64 // Throughput of the window
+	ErrorRate     float64 // Failed or retried writes per batch of the window
+	Reason        string
+}
+
+// ParallelismController adjusts the parallelism of a copy to the throughput and errors it
+// observes, within its bounds. Over each window of batches it probes a higher parallelism while
+// that raises the throughput, steps back when it does not, and halves the parallelism when the
+// target fails or retries too many writes. Without adaptation the parallelism stays as set.
+type ParallelismController struct {
+	bounds   ParallelismBounds
+	adaptive bool
+	current  int
+
+	windowStart   time.Time
+	windowBatches int
+	windowRows    int64
+	windowErrors  int
+
+	lastThroughput float64 // Throughput before the last change
+	lastChange     int     // +1 when the last change was a probe higher
+	hold           int     // Windows left before probing higher again
+}
+
+// NewParallelismController creates a controller starting at the initial parallelism within the
+// bounds. A controller that is not adaptive keeps the initial parallelism.
+func NewParallelismController(bounds ParallelismBounds, initial int, adaptive bool) *ParallelismController {
+	if bounds.Min < 1 {
+		bounds.Min = 1
+	}
+	if bounds.Max < bounds.Min {
+		bounds.Max = bounds.Min
+	}
+	return &ParallelismController{
+		bounds:   bounds,
+		adaptive: adaptive,
+		current:  bounds.Clamp(initial),
+	}
+}
+
+// Parallelism returns the number of batches to process at once
+func (c *ParallelismController) Parallelism() int {
+	return c.current
+}
+
+// windowSize returns the number of batches a decision is based on
+func (c *ParallelismController) windowSize() int {
+	return max(4, 2*c.current)
+}
+
+// Observe records a processed batch, with the rows written and whether its writes failed or
+// were retried, and returns the decision taken at the end of a window, or nil when the
+// parallelism is unchanged
+func (c *ParallelismController) Observe(rows int64, errored bool, now time.Time) *ParallelismDecision {
+	if !c.adaptive {
+		return nil
+	}
+	if c.windowBatches == 0 && c.windowStart.IsZero() {
+		c.windowStart = now
+	}
+	c.windowBatches++
+	c.windowRows += rows
+	if errored {
+		c.windowErrors++
+	}
+	if c.windowBatches < c.windowSize() {
+		return nil
+	}
+
+	elapsed := now.Sub(c.windowStart).Seconds()
+	throughput := 0.0
+	if elapsed > 0 {
+		throughput = float64(c.windowRows) / elapsed
+	}
+	errorRate := float64(c.windowErrors) / float64(c.windowBatches)
+	c.windowStart, c.windowBatches, c.windowRows, c.windowErrors = now, 0, 0, 0
+
+	next, reason := c.current, ""
+	switch {
+	case errorRate > MaxErrorRate:
+		next = c.bounds.Clamp(c.current / 2)
+		reason = fmt.Sprintf("%.0f%% of the writes failed or were retried, above %.0f%%", errorRate*100, MaxErrorRate*100)
+		c.hold = holdWindows
+	case c.lastChange > 0 && throughput < c.lastThroughput*minThroughputGain:
+		next = c.bounds.Clamp(c.current - 1)
+		reason = fmt.Sprintf("throughput of %.0f rows/s did not improve on %.0f rows/s at parallelism %d", throughput, c.lastThroughput, c.current-1)
+		c.hold = holdWindows
+	case c.hold > 0:
+		c.hold--
+	case c.current < c.bounds.Max:
+		next = c.current + 1
+		reason = fmt.Sprintf("throughput of %.0f rows/s without errors, probing a higher parallelism", throughput)
+	}
+
+	c.lastChange = next - c.current
+	c.lastThroughput = throughput
+	if next == c.current {
+		return nil
+	}
+	decision := &ParallelismDecision{
+		DecidedAt:     now,
+		From:          c.current,
+		To:            next,
+		RowsPerSecond: throughput,
+		ErrorRate:     errorRate,
+		Reason:        reason,
+	}
+	c.current = next
+	return decision
+}
@@ -0,0 +1,70 @@
+package mapping
+
+import (
+	"testing"
+	"time"
+)
+
+// observeWindow observes a window of batches of the controller spread over a second
+func observeWindow(c *ParallelismController, start time.Time, rows int64, errors int) (*ParallelismDecision, time.Time) {
+	size := c.windowSize()
+	var decision *ParallelismDecision
+	for i := 0; i < size; i++ {
+		if d := c.Observe(rows/int64(size), i < errors, start.Add(time.Duration(i+1)*time.Second/time.Duration(size))); d != nil {
+			decision = d
+		}
+	}
+	return decision, start.Add(time.Second)
+}
+
+func TestParallelismControllerProbesAndStepsBack(t *testing.T) {
+	c := NewParallelismController(ParallelismBounds{Min: 1, Max: 4}, 2, true)
+	now := time.Unix(0, 0)
+
+	decision, now := observeWindow(c, now, 1000, 0)
+	if decision == nil || decision.To != 3 {
+		t.Fatalf("expected a probe to 3, got %+v", decision)
+	}
+
+	// A higher throughput keeps the probe going
+	decision, now = observeWindow(c, now, 2000, 0)
+	if decision == nil || decision.To != 4 {
+		t.Fatalf("expected a probe to 4, got %+v", decision)
+	}
+
+	// No gain steps back and holds
+	decision, now = observeWindow(c, now, 2000, 0)
+	if decision == nil || decision.To != 3 {
+		t.Fatalf("expected a step back to 3, got %+v", decision)
+	}
+	for i := 0; i < holdWindows; i++ {
+		if decision, now = observeWindow(c, now, 2000, 0); decision != nil {
+			t.Fatalf("expected the parallelism to be held, got %+v", decision)
+		}
+	}
+}
+
+func TestParallelismControllerHalvesOnErrors(t *testing.T) {
+	c := NewParallelismController(ParallelismBounds{Min: 2, Max: 16}, 8, true)
+	decision, _ := observeWindow(c, time.Unix(0, 0), 1000, 4)
+	if decision == nil || decision.From != 8 || decision.To != 4 || decision.ErrorRate != 0.25 {
+		t.Fatalf("expected a halving to 4 at a 25%% error rate, got %+v", decision)
+	}
+
+	c = NewParallelismController(ParallelismBounds{Min: 2, Max: 16}, 3, true)
+	c.current = 2
+	if decision, _ := observeWindow(c, time.Unix(0, 0), 1000, 4); decision != nil {
+		t.Fatalf("expected the minimum to be kept, got %+v", decision)
+	}
+}
+
+func TestParallelismControllerBounds(t *testing.T) {
+	if got := NewParallelismController(ParallelismBounds{Min: 2, Max: 4}, 10, true).Parallelism(); got != 4 {
+		t.Errorf("expected the initial parallelism to be clamped to 4, got %d", got)
+	}
+
+	c := NewParallelismController(ParallelismBounds{Min: 1, Max: 8}, 3, false)
+	if decision, _ := observeWindow(c, time.Unix(0, 0), 1000, 0); decision != nil || c.Parallelism() != 3 {
+		t.Errorf("expected a fixed parallelism of 3, got %d and %+v", c.Parallelism(), decision)
+	}
+}
@@ -0,0 +1,107 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/rowfilter"
+)
+
+// RuleRowFilter returns the row filter of a mapping rule, the condition on source columns that
+// rows must match for the rule to map them, or "" when the rule maps all rows
+func RuleRowFilter(rule *Rule) string {
+	rowFilter, _ := rule.Metadata["row_filter"].(string)
+	return rowFilter
+}
+
+// RowFilters are the compiled row filters of a mapping and of its rules. A source row is
+// written only when it matches the filter of the mapping and, if rules of its table have
+// filters, the filter of at least one of them; it is then mapped by the matching rules and the
+// rules without a filter. The anchor applies the same semantics to replicated rows.
+type RowFilters struct {
+	mapping *rowfilter.Filter
+	rules   map[string]*rowfilter.Filter // by expression
+}
+
+// NewRowFilters compiles the row filter of a mapping and those of its rules. It returns nil
+// when there are none.
+func NewRowFilters(mappingFilter string, rules []*Rule) (*RowFilters, error) {
+	filters := &RowFilters{rules: make(map[string]*rowfilter.Filter)}
+	if mappingFilter != "" {
+		filter, err := rowfilter.Parse(mappingFilter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid row filter of the mapping: %w", err)
+		}
+		filters.mapping = filter
+	}
+	for _, rule := range rules {
+		expression := RuleRowFilter(rule)
+		if expression == "" || filters.rules[expression] != nil {
+			continue
+		}
+		filter, err := rowfilter.Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid row filter of rule %s: %w", rule.Name, err)
+		}
+		filters.rules[expression] = filter
+	}
+
+	if filters.mapping == nil && len(filters.rules) == 0 {
+		return nil, nil
+	}
+	return filters, nil
+}
+
+// Columns returns the source columns the filters read, sorted
+func (f *RowFilters) Columns() []string {
+	if f == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	if f.mapping != nil {
+		for _, column := range f.mapping.Columns() {
+			seen[column] = true
+		}
+	}
+	for _, filter := range f.rules {
+		for _, column := range filter.Columns() {
+			seen[column] = true
+		}
+	}
+	columns := make([]string, 0, len(seen))
+	for column := range seen {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// SelectRules returns the rules of a table mapping a source row, and whether the row is
+// written at all
+func (f *RowFilters) SelectRules(row map[string]interface{}, rules []*Rule) ([]*Rule, bool) {
+	if f == nil {
+		return rules, true
+	}
+	if f.mapping != nil && !f.mapping.Match(row) {
+		return nil, false
+	}
+
+	selected := make([]*Rule, 0, len(rules))
+	filtered, matched := false, false
+	for _, rule := range rules {
+		filter := f.rules[RuleRowFilter(rule)]
+		if filter == nil {
+			selected = append(selected, rule)
+			continue
+		}
+		filtered = true
+		if filter.Match(row) {
+			matched = true
+			selected = append(selected, rule)
+		}
+	}
+	if filtered && !matched {
+		return nil, false
+	}
+	return selected, true
+}
@@ -0,0 +1,46 @@
+package mapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowFilters(t *testing.T) {
+	id := &Rule{Name: "id", Metadata: map[string]interface{}{"source_column": "id"}}
+	eu := &Rule{Name: "eu_price", Metadata: map[string]interface{}{"source_column": "price", "row_filter": "currency = 'EUR'"}}
+	us := &Rule{Name: "us_price", Metadata: map[string]interface{}{"source_column": "price", "row_filter": "currency = 'USD'"}}
+	rules := []*Rule{id, eu, us}
+
+	filters, err := NewRowFilters("region IN ('EU', 'US')", rules)
+	if err != nil {
+		t.Fatalf("NewRowFilters: %v", err)
+	}
+	if got, want := filters.Columns(), []string{"currency", "region"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() = %v, want %v", got, want)
+	}
+
+	selected, written := filters.SelectRules(map[string]interface{}{"id": 1, "region": "US", "currency": "USD"}, rules)
+	if !written || !reflect.DeepEqual(selected, []*Rule{id, us}) {
+		t.Errorf("USD row: written %v with %d rules", written, len(selected))
+	}
+	if _, written := filters.SelectRules(map[string]interface{}{"id": 2, "region": "APAC", "currency": "USD"}, rules); written {
+		t.Error("row outside the mapping filter was written")
+	}
+	if _, written := filters.SelectRules(map[string]interface{}{"id": 3, "region": "EU", "currency": "GBP"}, rules); written {
+		t.Error("row matching no rule condition was written")
+	}
+	if selected, written := filters.SelectRules(map[string]interface{}{"id": 4, "region": "EU"}, []*Rule{id}); !written || len(selected) != 1 {
+		t.Errorf("table without rule filters: written %v with %d rules", written, len(selected))
+	}
+
+	if filters, err := NewRowFilters("", []*Rule{id}); err != nil || filters != nil {
+		t.Errorf("no filters = %v, %v, want nil", filters, err)
+	}
+	var none *RowFilters
+	if selected, written := none.SelectRules(map[string]interface{}{}, rules); !written || len(selected) != len(rules) {
+		t.Errorf("nil filters: written %v with %d rules", written, len(selected))
+	}
+	if _, err := NewRowFilters("", []*Rule{{Name: "bad", Metadata: map[string]interface{}{"row_filter": "currency ="}}}); err == nil {
+		t.Error("invalid rule filter was accepted")
+	}
+}
@@ -0,0 +1,54 @@
+package mapping
+
+// RuleColumns returns the source and target columns of a mapping rule, in the order of its
+// items. A many-to-one or one-to-many rule reads or writes the columns of its item URIs; other
+// rules those of their generated match, or of their resource URIs.
+func RuleColumns(rule *Rule) (sourceColumns, targetColumns []string) {
+	return ruleColumns(rule.Metadata, "source"), ruleColumns(rule.Metadata, "target")
+}
+
+// ruleColumns returns the source or target columns of the metadata of a rule
+func ruleColumns(metadata map[string]interface{}, side string) []string {
+	resourceURI, _ := metadata[side+"_resource_uri"].(string)
+
+	// The item URIs are those the rule was added with; the resource URI is the first of them
+	// unless the rule was re-pointed since
+	uris := stringList(metadata[side+"_uris"])
+	if len(uris) > 1 && uris[0] == resourceURI {
+		columns := make([]string, 0, len(uris))
+		for _, uri := range uris {
+			if _, column, ok := ColumnOfURI(uri); ok {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) == len(uris) {
+			return columns
+		}
+	}
+
+	if column, _ := metadata[side+"_column"].(string); column != "" {
+		return []string{column}
+	}
+	if _, column, ok := ColumnOfURI(resourceURI); ok {
+		return []string{column}
+	}
+	return nil
+}
+
+// stringList returns the strings of a metadata value, which is a []interface{} once read back
+// from the database
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package mapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleColumns(t *testing.T) {
+	column := func(table, name string) string {
+		return "redb://data/database/db_1/table/" + table + "/column/" + name
+	}
+	tests := []struct {
+		name            string
+		metadata        map[string]interface{}
+		sources, target []string
+	}{
+		{
+			name: "many-to-one",
+			metadata: map[string]interface{}{
+				"source_resource_uri": column("users", "first_name"),
+				"source_uris":         []interface{}{column("users", "first_name"), column("users", "last_name")},
+				"target_resource_uri": column("people", "full_name"),
+				"target_uris":         []interface{}{column("people", "full_name")},
+			},
+			sources: []string{"first_name", "last_name"},
+			target:  []string{"full_name"},
+		},
+		{
+			name: "generated match",
+			metadata: map[string]interface{}{
+				"source_column":       "email",
+				"target_column":       "email_address",
+				"source_resource_uri": column("users", "email"),
+				"target_resource_uri": column("people", "email_address"),
+			},
+			sources: []string{"email"},
+			target:  []string{"email_address"},
+		},
+		{
+			name: "re-pointed one-to-many",
+			metadata: map[string]interface{}{
+				"source_resource_uri": column("users", "address"),
+				"target_resource_uri": column("people", "city"),
+				"target_uris":         []string{column("people", "street"), column("people", "city")},
+			},
+			sources: []string{"address"},
+			target:  []string{"city"},
+		},
+	}
+	for _, tt := range tests {
+		sources, targets := RuleColumns(&Rule{Name: tt.name, Metadata: tt.metadata})
+		if !reflect.DeepEqual(sources, tt.sources) || !reflect.DeepEqual(targets, tt.target) {
+			t.Errorf("%s: columns %v -> %v, want %v -> %v", tt.name, sources, targets, tt.sources, tt.target)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kinds of the stages of a run plan
+const (
+	StageCreateSandbox   = "create_sandbox"
+	StageCopyTable       = "copy_table"
+	StageValidateVectors = "validate_vectors"
+	StageVerifyGraph     = "verify_graph"
+	StageDropSandbox     = "drop_sandbox"
+)
+
+// PlanTablePair is a table pair of a planned copy, with the databases and nodes it reads from
+// and writes to
+type PlanTablePair struct {
+	SourceTable     string // database_id.table_name
+	TargetTable     string
+	SourceDatabase  string // Database names
+	TargetDatabase  string
+	SourceAdapter   string // Database types
+	TargetAdapter   string
+	SourceNode      string // Nodes the databases are connected to
+	TargetNode      string
+	Rules           []string
+	Transformations []string
+	EstimatedRows   int64 // Source rows before row filters, -1 when unknown
+	ValidateVectors bool  // The target searches vectors, and they are validated after the copy
+	VerifyGraph     bool  // The source reads edges, and they are verified after the copy
+}
+
+// MeshRoute is a route of the mesh between two nodes, usable in both directions
+type MeshRoute struct {
+	ANode string
+	BNode string
+}
+
+// PlanOptions are the options of a planned copy
+type PlanOptions struct {
+	BatchSize   int32
+	Parallelism int32 // Batches of a table pair processed at once at the start of the copy
+	Sandbox     bool
+	Node        string // Node running the copy
+	Routes      []MeshRoute
+}
+
+// PlanStage is a stage of a run plan. A stage starts when the stages it depends on are
+// completed.
+type PlanStage struct {
+	ID               string
+	Kind             string
+	Description      string
+	DependsOn        []string
+	Pair             *PlanTablePair // Table pair of the stage, nil for sandbox stages
+	Node             string
+	MeshHops         []string // Nodes from the source database to the target database
+	EstimatedRows    int64
+	EstimatedBatches int64
+	Parallelism      int32
+}
+
+// PlanRun returns the stages of a copy of the table pairs, in the order the copy runs them,
+// and what would make the copy fail. The copy processes the table pairs one at a time, and the
+// batches of a table pair as many at a time as its parallelism: batches are read from the source
+// in order, and transformed and written to the target at once. An adaptive copy adjusts the
+// parallelism while it runs, so the plan has the one it starts with. The vectors and edges of a
+// table pair are checked before the next table pair is copied.
+//
+// The copy reaches the databases through the anchor of the node running it, so table pairs of
+// databases connected to other nodes are reported as failing, with the mesh hops the rows
+// would take between the nodes.
+func PlanRun(pairs []PlanTablePair, options PlanOptions) ([]PlanStage, []string) {
+	var stages []PlanStage
+	var warnings []string
+
+	// previous is the stage the next one waits for
+	previous := ""
+	add := func(stage PlanStage) {
+		if previous != "" {
+			stage.DependsOn = []string{previous}
+		}
+		stages = append(stages, stage)
+		previous = stage.ID
+	}
+
+	if options.Sandbox {
+		add(PlanStage{
+			ID:          StageCreateSandbox,
+			Kind:        StageCreateSandbox,
+			Description: "Create temporary copies of the target tables, which the rows are written into",
+			Node:        options.Node,
+			Parallelism: 1,
+		})
+	}
+
+	for i := range pairs {
+		pair := &pairs[i]
+		copyID := fmt.Sprintf("%s_%d", StageCopyTable, i+1)
+
+		hops := MeshPath(options.Routes, pair.SourceNode, options.Node)
+		if hops != nil {
+			toTarget := MeshPath(options.Routes, options.Node, pair.TargetNode)
+			if toTarget == nil {
+				hops = nil
+			} else {
+				hops = append(hops, toTarget[1:]...)
+			}
+		}
+		for _, side := range []struct{ database, node string }{
+			{pair.SourceDatabase, pair.SourceNode},
+			{pair.TargetDatabase, pair.TargetNode},
+		} {
+			if side.node != "" && side.node != options.Node {
+				warnings = append(warnings, fmt.Sprintf("table pair %s -> %s: database %s is connected to node %s, but the copy runs on node %s and reaches only the databases of its anchor",
+					pair.SourceTable, pair.TargetTable, side.database, side.node, options.Node))
+			}
+		}
+		if hops == nil {
+			warnings = append(warnings, fmt.Sprintf("table pair %s -> %s: no route of the mesh connects node %s to the nodes of its databases",
+				pair.SourceTable, pair.TargetTable, options.Node))
+		}
+		if pair.EstimatedRows < 0 {
+			warnings = append(warnings, fmt.Sprintf("table pair %s -> %s: the rows of the source could not be counted", pair.SourceTable, pair.TargetTable))
+		}
+
+		add(PlanStage{
+			ID:               copyID,
+			Kind:             StageCopyTable,
+			Description:      fmt.Sprintf("Copy %s to %s with %d rules", pair.SourceTable, pair.TargetTable, len(pair.Rules)),
+			Pair:             pair,
+			Node:             options.Node,
+			MeshHops:         hops,
+			EstimatedRows:    pair.EstimatedRows,
+			EstimatedBatches: estimatedBatches(pair.EstimatedRows, options.BatchSize),
+			Parallelism:      max(options.Parallelism, 1),
+		})
+
+		// Written rows are checked in the target, which a sandbox run leaves untouched
+		if options.Sandbox {
+			continue
+		}
+		if pair.ValidateVectors {
+			add(PlanStage{
+				ID:          fmt.Sprintf("%s_%d", StageValidateVectors, i+1),
+				Kind:        StageValidateVectors,
+				Description: fmt.Sprintf("Search sampled vectors of %s again to validate the copy", pair.TargetTable),
+				Pair:        pair,
+				Node:        options.Node,
+				Parallelism: 1,
+			})
+		}
+		if pair.VerifyGraph {
+			add(PlanStage{
+				ID:          fmt.Sprintf("%s_%d", StageVerifyGraph, i+1),
+				Kind:        StageVerifyGraph,
+				Description: fmt.Sprintf("Compare the structure of edges sampled from %s and %s", pair.SourceTable, pair.TargetTable),
+				Pair:        pair,
+				Node:        options.Node,
+				Parallelism: 1,
+			})
+		}
+	}
+
+	if options.Sandbox {
+		add(PlanStage{
+			ID:          StageDropSandbox,
+			Kind:        StageDropSandbox,
+			Description: "Drop the temporary copies of the target tables",
+			Node:        options.Node,
+			Parallelism: 1,
+		})
+	}
+
+	return stages, warnings
+}
+
+// estimatedBatches returns the batches rows are read in, or -1 when the rows are unknown
+func estimatedBatches(rows int64, batchSize int32) int64 {
+	if rows < 0 || batchSize <= 0 {
+		return -1
+	}
+	return (rows + int64(batchSize) - 1) / int64(batchSize)
+}
+
+// MeshPath returns the nodes of a shortest path between two nodes over the routes of the mesh,
+// from and to included, or nil when no route connects them
+func MeshPath(routes []MeshRoute, from, to string) []string {
+	if from == to {
+		return []string{from}
+	}
+
+	neighbors := make(map[string][]string)
+	for _, route := range routes {
+		neighbors[route.ANode] = append(neighbors[route.ANode], route.BNode)
+		neighbors[route.BNode] = append(neighbors[route.BNode], route.ANode)
+	}
+	// Neighbors are visited in order, so that equally short paths are chosen the same way
+	for _, nodes := range neighbors {
+		sort.Strings(nodes)
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range neighbors[node] {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = node
+			if next == to {
+				path := []string{to}
+				for step := node; step != ""; step = previous[step] {
+					path = append([]string{step}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
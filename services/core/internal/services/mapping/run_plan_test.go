@@ -0,0 +1,80 @@
+package mapping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMeshPath(t *testing.T) {
+	routes := []MeshRoute{
+		{ANode: "node_a", BNode: "node_b"},
+		{ANode: "node_c", BNode: "node_b"},
+		{ANode: "node_c", BNode: "node_d"},
+		{ANode: "node_a", BNode: "node_d"},
+	}
+
+	if got := MeshPath(routes, "node_a", "node_a"); !reflect.DeepEqual(got, []string{"node_a"}) {
+		t.Errorf("expected the node itself, got %v", got)
+	}
+	if got := MeshPath(routes, "node_c", "node_a"); !reflect.DeepEqual(got, []string{"node_c", "node_b", "node_a"}) {
+		t.Errorf("expected the path through node_b, got %v", got)
+	}
+	if got := MeshPath(routes, "node_a", "node_e"); got != nil {
+		t.Errorf("expected no path to an unconnected node, got %v", got)
+	}
+}
+
+func TestPlanRun(t *testing.T) {
+	pairs := []PlanTablePair{
+		{
+			SourceTable: "db_1.users", TargetTable: "db_2.people",
+			SourceDatabase: "pg", TargetDatabase: "vectors",
+			SourceNode: "node_a", TargetNode: "node_a",
+			Rules: []string{"email", "name"}, EstimatedRows: 2500, ValidateVectors: true,
+		},
+		{
+			SourceTable: "db_1.orders", TargetTable: "db_3.orders",
+			SourceDatabase: "pg", TargetDatabase: "remote",
+			SourceNode: "node_a", TargetNode: "node_b",
+			Rules: []string{"id"}, EstimatedRows: -1,
+		},
+	}
+	options := PlanOptions{
+		BatchSize: 1000,
+		Node:      "node_a",
+		Routes:    []MeshRoute{{ANode: "node_a", BNode: "node_b"}},
+	}
+
+	stages, warnings := PlanRun(pairs, options)
+	ids := make([]string, len(stages))
+	for i, stage := range stages {
+		ids[i] = stage.ID
+	}
+	if want := []string{"copy_table_1", "validate_vectors_1", "copy_table_2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected stages %v, got %v", want, ids)
+	}
+	if len(stages[0].DependsOn) != 0 || !reflect.DeepEqual(stages[2].DependsOn, []string{"validate_vectors_1"}) {
+		t.Errorf("expected the table pairs to run one after another, got %v and %v", stages[0].DependsOn, stages[2].DependsOn)
+	}
+	if stages[0].EstimatedBatches != 3 || stages[2].EstimatedBatches != -1 {
+		t.Errorf("unexpected batches %d and %d", stages[0].EstimatedBatches, stages[2].EstimatedBatches)
+	}
+	if !reflect.DeepEqual(stages[2].MeshHops, []string{"node_a", "node_b"}) {
+		t.Errorf("expected the rows to hop to node_b, got %v", stages[2].MeshHops)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "database remote is connected to node node_b") || !strings.Contains(warnings[1], "could not be counted") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	// A sandbox run creates and drops the sandbox around the copies, and checks nothing
+	options.Sandbox = true
+	stages, _ = PlanRun(pairs[:1], options)
+	ids = ids[:0]
+	for _, stage := range stages {
+		ids = append(ids, stage.ID)
+	}
+	if want := []string{"create_sandbox", "copy_table_1", "drop_sandbox"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected stages %v, got %v", want, ids)
+	}
+}
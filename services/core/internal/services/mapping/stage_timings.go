@@ -0,0 +1,91 @@
+package mapping
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stages of the processing of a batch by a copy
+const (
+	TimingFetch     = "fetch"
+	TimingTransform = "transform"
+	TimingNetwork   = "network"
+	TimingWrite     = "write"
+)
+
+// timingHints describes each stage as the bottleneck of a copy, with the tuning knob addressing it
+var timingHints = map[string]string{
+	TimingFetch:     "source reads: a larger batch size or an index matching the read order of the source table speeds them up",
+	TimingTransform: "transformations: direct mappings or fewer transformation chain steps speed them up",
+	TimingNetwork:   "network transfer: running the copy on the node of the databases or a larger batch size reduces it",
+	TimingWrite:     "target writes: a larger batch size, fewer target indexes or a target with more capacity speeds them up",
+}
+
+// StageTimings is the time a copy spends in each stage of processing its batches
+type StageTimings struct {
+	Batches   int64
+	Fetch     time.Duration // Reading batches from the source database
+	Transform time.Duration // Applying the transformations of the rules
+	Network   time.Duration // Transferring batches to and from the anchor
+	Write     time.Duration // Writing batches to the target database, retries included
+}
+
+// Add adds the timings of other batches
+func (t *StageTimings) Add(other StageTimings) {
+	t.Batches += other.Batches
+	t.Fetch += other.Fetch
+	t.Transform += other.Transform
+	t.Network += other.Network
+	t.Write += other.Write
+}
+
+// Total returns the time spent in all stages
+func (t StageTimings) Total() time.Duration {
+	return t.Fetch + t.Transform + t.Network + t.Write
+}
+
+// Bottleneck returns the stage the most time is spent in and its share of the total time, or ""
+// when no time was spent
+func (t StageTimings) Bottleneck() (string, float64) {
+	total := t.Total()
+	if total <= 0 {
+		return "", 0
+	}
+	stage, longest := TimingFetch, t.Fetch
+	for _, timing := range []struct {
+		stage    string
+		duration time.Duration
+	}{
+		{TimingTransform, t.Transform},
+		{TimingNetwork, t.Network},
+		{TimingWrite, t.Write},
+	} {
+		if timing.duration > longest {
+			stage, longest = timing.stage, timing.duration
+		}
+	}
+	return stage, float64(longest) / float64(total)
+}
+
+// Summary describes the bottleneck, e.g. "78% of time in target writes: ...", or returns "" when
+// no time was spent
+func (t StageTimings) Summary() string {
+	stage, share := t.Bottleneck()
+	if stage == "" {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% of time in %s", share*100, timingHints[stage])
+}
+
+// SplitRoundTrip splits the round trip of a request to the anchor into the time the anchor reported
+// spending on it and the network transfer. Without a report, as from older anchors, the whole
+// round trip is taken as the stage.
+func SplitRoundTrip(roundTrip, reported time.Duration) (stage, network time.Duration) {
+	if reported <= 0 {
+		return roundTrip, 0
+	}
+	if reported >= roundTrip {
+		return roundTrip, 0
+	}
+	return reported, roundTrip - reported
+}
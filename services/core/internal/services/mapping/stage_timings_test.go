@@ -0,0 +1,43 @@
+package mapping
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStageTimingsBottleneck(t *testing.T) {
+	var timings StageTimings
+	if stage, _ := timings.Bottleneck(); stage != "" || timings.Summary() != "" {
+		t.Errorf("expected no bottleneck without timings, got %q", stage)
+	}
+
+	timings.Add(StageTimings{Batches: 1, Fetch: 10 * time.Millisecond, Transform: 5 * time.Millisecond, Network: 7 * time.Millisecond, Write: 60 * time.Millisecond})
+	timings.Add(StageTimings{Batches: 1, Fetch: 10 * time.Millisecond, Transform: 5 * time.Millisecond, Network: 3 * time.Millisecond, Write: 100 * time.Millisecond})
+	if timings.Batches != 2 || timings.Total() != 200*time.Millisecond {
+		t.Fatalf("expected 2 batches in 200ms, got %d in %s", timings.Batches, timings.Total())
+	}
+	stage, share := timings.Bottleneck()
+	if stage != TimingWrite || share != 0.8 {
+		t.Errorf("expected write at 80%%, got %s at %v", stage, share)
+	}
+	if summary := timings.Summary(); !strings.HasPrefix(summary, "80% of time in target writes") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+}
+
+func TestSplitRoundTrip(t *testing.T) {
+	tests := []struct {
+		roundTrip, reported, stage, network time.Duration
+	}{
+		{100 * time.Millisecond, 70 * time.Millisecond, 70 * time.Millisecond, 30 * time.Millisecond},
+		{100 * time.Millisecond, 0, 100 * time.Millisecond, 0},
+		{100 * time.Millisecond, 120 * time.Millisecond, 100 * time.Millisecond, 0},
+	}
+	for _, tt := range tests {
+		stage, network := SplitRoundTrip(tt.roundTrip, tt.reported)
+		if stage != tt.stage || network != tt.network {
+			t.Errorf("SplitRoundTrip(%s, %s) = %s, %s, want %s, %s", tt.roundTrip, tt.reported, stage, network, tt.stage, tt.network)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package mapping
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ColumnRef is a column of a table
+type ColumnRef struct {
+	Table  string
+	Column string
+}
+
+// String returns the column as "table.column"
+func (c ColumnRef) String() string {
+	return c.Table + "." + c.Column
+}
+
+// SchemaColumns are the columns of the tables of a schema, by table name
+type SchemaColumns map[string]map[string]bool
+
+// RuleSuggestion is a proposed change to the rules of a mapping after a schema changed: a rule
+// to add for a match of unmapped columns, or a rule to remove that maps a dropped column
+type RuleSuggestion struct {
+	Action   string // add, remove
+	RuleName string // Rule to remove
+	Source   ColumnRef
+	Target   ColumnRef
+	Score    float64 // Match score of an addition
+	Reason   string
+}
+
+// MatchCandidate is a column match of the matcher, a candidate rule
+type MatchCandidate struct {
+	Source ColumnRef
+	Target ColumnRef
+	Score  float64
+}
+
+// SuggestRuleChanges diffs the rules of a mapping against the current schemas of its source and
+// target, and the matches of the matcher on them. Rules reading or writing a column that no
+// longer exists are suggested for removal; matches of a source and a target column that no
+// remaining rule maps are suggested as additions, best first. Rules without column URIs are
+// left alone.
+func SuggestRuleChanges(rules []*Rule, source, target SchemaColumns, candidates []MatchCandidate) (additions, removals []RuleSuggestion) {
+	mappedSources := make(map[ColumnRef]bool)
+	mappedTargets := make(map[ColumnRef]bool)
+	for _, rule := range rules {
+		sourceRefs := ruleColumnRefs(rule.Metadata, "source")
+		targetRefs := ruleColumnRefs(rule.Metadata, "target")
+
+		reason := missingColumnReason("source", source, sourceRefs)
+		if reason == "" {
+			reason = missingColumnReason("target", target, targetRefs)
+		}
+		if reason != "" {
+			removal := RuleSuggestion{Action: "remove", RuleName: rule.Name, Reason: reason}
+			if len(sourceRefs) > 0 {
+				removal.Source = sourceRefs[0]
+			}
+			if len(targetRefs) > 0 {
+				removal.Target = targetRefs[0]
+			}
+			removals = append(removals, removal)
+			continue
+		}
+
+		for _, ref := range sourceRefs {
+			mappedSources[ref] = true
+		}
+		for _, ref := range targetRefs {
+			mappedTargets[ref] = true
+		}
+	}
+
+	// The best matches take the columns first
+	sorted := make([]MatchCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+	for _, candidate := range sorted {
+		if mappedSources[candidate.Source] || mappedTargets[candidate.Target] {
+			continue
+		}
+		if !source.has(candidate.Source) || !target.has(candidate.Target) {
+			continue
+		}
+		mappedSources[candidate.Source] = true
+		mappedTargets[candidate.Target] = true
+		additions = append(additions, RuleSuggestion{
+			Action: "add",
+			Source: candidate.Source,
+			Target: candidate.Target,
+			Score:  candidate.Score,
+			Reason: fmt.Sprintf("source column %s and target column %s are not mapped by any rule", candidate.Source, candidate.Target),
+		})
+	}
+	return additions, removals
+}
+
+// has reports whether the schema has a column
+func (s SchemaColumns) has(ref ColumnRef) bool {
+	return s[ref.Table][ref.Column]
+}
+
+// missingColumnReason explains why a rule maps a column missing from a schema, or returns an
+// empty string if all its columns exist
+func missingColumnReason(side string, schema SchemaColumns, refs []ColumnRef) string {
+	for _, ref := range refs {
+		if _, ok := schema[ref.Table]; !ok {
+			return fmt.Sprintf("%s table %s no longer exists", side, ref.Table)
+		}
+		if !schema.has(ref) {
+			return fmt.Sprintf("%s column %s no longer exists", side, ref)
+		}
+	}
+	return ""
+}
+
+// ruleColumnRefs returns the source or target columns of the metadata of a rule with their
+// tables, read from its item URIs like ruleColumns
+func ruleColumnRefs(metadata map[string]interface{}, side string) []ColumnRef {
+	resourceURI, _ := metadata[side+"_resource_uri"].(string)
+	uris := stringList(metadata[side+"_uris"])
+	if len(uris) <= 1 || uris[0] != resourceURI {
+		uris = []string{resourceURI}
+	}
+
+	refs := make([]ColumnRef, 0, len(uris))
+	for _, uri := range uris {
+		if table, column, ok := ColumnOfURI(uri); ok {
+			refs = append(refs, ColumnRef{Table: table, Column: column})
+		}
+	}
+	if len(refs) > 0 {
+		return refs
+	}
+
+	table, _ := metadata[side+"_table"].(string)
+	column, _ := metadata[side+"_column"].(string)
+	if table != "" && column != "" {
+		return []ColumnRef{{Table: table, Column: column}}
+	}
+	return nil
+}
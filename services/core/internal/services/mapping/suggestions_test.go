@@ -0,0 +1,87 @@
+package mapping
+
+import (
+	"testing"
+)
+
+func TestSuggestRuleChanges(t *testing.T) {
+	column := func(table, name string) string {
+		return "redb://data/database/db_1/table/" + table + "/column/" + name
+	}
+	rule := func(name, sourceTable, sourceColumn, targetTable, targetColumn string) *Rule {
+		return &Rule{Name: name, Metadata: map[string]interface{}{
+			"source_resource_uri": column(sourceTable, sourceColumn),
+			"target_resource_uri": column(targetTable, targetColumn),
+		}}
+	}
+
+	rules := []*Rule{
+		rule("id", "users", "id", "people", "id"),
+		rule("email", "users", "email", "people", "email_address"),
+		rule("fax", "users", "fax", "people", "fax"),
+		rule("legacy", "legacy_users", "id", "people", "legacy_id"),
+		rule("nickname", "users", "nickname", "people", "nickname"),
+		// A many-to-one rule is removed if any of its columns was dropped
+		{Name: "full_name", Metadata: map[string]interface{}{
+			"source_resource_uri": column("users", "first_name"),
+			"source_uris":         []interface{}{column("users", "first_name"), column("users", "middle_name")},
+			"target_resource_uri": column("people", "full_name"),
+		}},
+		// Rules without column URIs are left alone
+		{Name: "mcp", Metadata: map[string]interface{}{"target_resource_uri": "mcp://resource/users"}},
+	}
+	source := SchemaColumns{
+		"users": {"id": true, "email": true, "first_name": true, "phone": true, "mobile": true, "nickname": true},
+	}
+	target := SchemaColumns{
+		"people": {"id": true, "email_address": true, "full_name": true, "legacy_id": true, "phone_number": true},
+	}
+	candidates := []MatchCandidate{
+		{Source: ColumnRef{"users", "id"}, Target: ColumnRef{"people", "id"}, Score: 1},
+		{Source: ColumnRef{"users", "mobile"}, Target: ColumnRef{"people", "phone_number"}, Score: 0.7},
+		{Source: ColumnRef{"users", "phone"}, Target: ColumnRef{"people", "phone_number"}, Score: 0.9},
+		// The target column of a removed rule is free again
+		{Source: ColumnRef{"users", "first_name"}, Target: ColumnRef{"people", "full_name"}, Score: 0.6},
+	}
+
+	additions, removals := SuggestRuleChanges(rules, source, target, candidates)
+
+	wantRemovals := map[string]string{
+		"fax":       "source column users.fax no longer exists",
+		"legacy":    "source table legacy_users no longer exists",
+		"nickname":  "target column people.nickname no longer exists",
+		"full_name": "source column users.middle_name no longer exists",
+	}
+	if len(removals) != len(wantRemovals) {
+		t.Fatalf("expected %d removals, got %+v", len(wantRemovals), removals)
+	}
+	for _, removal := range removals {
+		if removal.Action != "remove" || removal.Reason != wantRemovals[removal.RuleName] {
+			t.Errorf("unexpected removal %+v", removal)
+		}
+	}
+
+	if len(additions) != 2 {
+		t.Fatalf("expected 2 additions, got %+v", additions)
+	}
+	if additions[0].Source != (ColumnRef{"users", "phone"}) || additions[0].Target != (ColumnRef{"people", "phone_number"}) || additions[0].Score != 0.9 {
+		t.Errorf("expected the best match of phone_number first, got %+v", additions[0])
+	}
+	if additions[1].Source != (ColumnRef{"users", "first_name"}) || additions[1].Target != (ColumnRef{"people", "full_name"}) {
+		t.Errorf("expected full_name to be mapped again, got %+v", additions[1])
+	}
+}
+
+func TestSuggestRuleChangesUpToDate(t *testing.T) {
+	rules := []*Rule{{Name: "email", Metadata: map[string]interface{}{
+		"source_table": "users", "source_column": "email",
+		"target_table": "people", "target_column": "email",
+	}}}
+	schema := SchemaColumns{"users": {"email": true}, "people": {"email": true}}
+	candidates := []MatchCandidate{{Source: ColumnRef{"users", "email"}, Target: ColumnRef{"people", "email"}, Score: 1}}
+
+	additions, removals := SuggestRuleChanges(rules, schema, schema, candidates)
+	if len(additions) != 0 || len(removals) != 0 {
+		t.Errorf("expected no suggestions, got additions %+v and removals %+v", additions, removals)
+	}
+}
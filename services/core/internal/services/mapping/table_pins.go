@@ -0,0 +1,104 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TablePin fixes the pairing of a source and a target table of a database mapping for the
+// matcher: the source table always matches the target table, or, with Never, never matches it
+type TablePin struct {
+	SourceTable string `json:"source_table" yaml:"source_table"`
+	TargetTable string `json:"target_table" yaml:"target_table"`
+	Never       bool   `json:"never,omitempty" yaml:"never,omitempty"`
+}
+
+// ValidateTablePins checks that table pins name their tables and do not contradict each other:
+// a source or a target table is pinned to match a single table, and a pair is not pinned both to
+// match and never to match
+func ValidateTablePins(pins []TablePin) error {
+	matches := make(map[string]string)
+	matchedBy := make(map[string]string)
+	never := make(map[TablePin]bool)
+	for _, pin := range pins {
+		if pin.SourceTable == "" || pin.TargetTable == "" {
+			return fmt.Errorf("table pin must have a source and a target table")
+		}
+		if pin.Never {
+			never[TablePin{SourceTable: pin.SourceTable, TargetTable: pin.TargetTable}] = true
+			continue
+		}
+		if target, exists := matches[pin.SourceTable]; exists && target != pin.TargetTable {
+			return fmt.Errorf("source table %s is pinned to both %s and %s", pin.SourceTable, target, pin.TargetTable)
+		}
+		if source, exists := matchedBy[pin.TargetTable]; exists && source != pin.SourceTable {
+			return fmt.Errorf("target table %s is pinned to both %s and %s", pin.TargetTable, source, pin.SourceTable)
+		}
+		matches[pin.SourceTable] = pin.TargetTable
+		matchedBy[pin.TargetTable] = pin.SourceTable
+	}
+	for pin := range never {
+		if matches[pin.SourceTable] == pin.TargetTable {
+			return fmt.Errorf("tables %s and %s are pinned both to match and never to match", pin.SourceTable, pin.TargetTable)
+		}
+	}
+	return nil
+}
+
+// TablePins returns the table pins of the mapping, in the order they were set
+func (m *Mapping) TablePins() []TablePin {
+	raw, ok := m.MappingObject["table_pins"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	pins := make([]TablePin, 0, len(raw))
+	for _, value := range raw {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pin := TablePin{}
+		pin.SourceTable, _ = object["source_table"].(string)
+		pin.TargetTable, _ = object["target_table"].(string)
+		pin.Never, _ = object["never"].(bool)
+		if pin.SourceTable != "" && pin.TargetTable != "" {
+			pins = append(pins, pin)
+		}
+	}
+	return pins
+}
+
+// SetTablePins replaces the table pins of a mapping. No pins clears them.
+func (s *Service) SetTablePins(ctx context.Context, mappingID string, pins []TablePin) error {
+	if err := ValidateTablePins(pins); err != nil {
+		return err
+	}
+
+	var err error
+	if len(pins) == 0 {
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = COALESCE(mapping_object, '{}') - 'table_pins',
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $1
+		`, mappingID)
+	} else {
+		pinsJSON, marshalErr := json.Marshal(pins)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal table pins: %w", marshalErr)
+		}
+		_, err = s.db.Pool().Exec(ctx, `
+			UPDATE mappings
+			SET mapping_object = jsonb_set(COALESCE(mapping_object, '{}'), '{table_pins}', $1::jsonb),
+			    updated = CURRENT_TIMESTAMP
+			WHERE mapping_id = $2
+		`, pinsJSON, mappingID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update table pins: %w", err)
+	}
+
+	return nil
+}
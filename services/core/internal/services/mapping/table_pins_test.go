@@ -0,0 +1,48 @@
+package mapping
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateTablePins(t *testing.T) {
+	tests := []struct {
+		name    string
+		pins    []TablePin
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"match and never", []TablePin{{"orders", "sales_orders", false}, {"orders", "archive_orders", true}}, false},
+		{"repeated", []TablePin{{"orders", "sales_orders", false}, {"orders", "sales_orders", false}}, false},
+		{"missing table", []TablePin{{"orders", "", false}}, true},
+		{"source pinned twice", []TablePin{{"orders", "sales_orders", false}, {"orders", "archive_orders", false}}, true},
+		{"target pinned twice", []TablePin{{"orders", "sales_orders", false}, {"order_lines", "sales_orders", false}}, true},
+		{"contradiction", []TablePin{{"orders", "sales_orders", true}, {"orders", "sales_orders", false}}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateTablePins(tt.pins); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMappingTablePins(t *testing.T) {
+	// Pins are read back from the JSON mapping object they are stored in
+	pinsJSON, err := json.Marshal([]TablePin{{"orders", "sales_orders", false}, {"customers", "archive_customers", true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored []interface{}
+	if err := json.Unmarshal(pinsJSON, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Mapping{MappingObject: map[string]interface{}{"table_pins": stored}}
+	pins := m.TablePins()
+	if len(pins) != 2 || pins[0] != (TablePin{"orders", "sales_orders", false}) || pins[1] != (TablePin{"customers", "archive_customers", true}) {
+		t.Errorf("unexpected table pins %+v", pins)
+	}
+	if pins := (&Mapping{MappingObject: map[string]interface{}{}}).TablePins(); pins != nil {
+		t.Errorf("expected no table pins, got %+v", pins)
+	}
+}
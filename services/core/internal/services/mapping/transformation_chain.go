@@ -0,0 +1,110 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransformationChainSeparator separates the steps of a transformation chain in the transformation
+// name of a mapping rule, e.g. "trim|lowercase|hash_sha256". The transformation service applies the
+// steps of such a name in order, so a chain runs wherever a rule's transformation runs.
+const TransformationChainSeparator = "|"
+
+// anyDataType is the data type of transformations that read or return values of any type
+const anyDataType = "any"
+
+// TransformationStep is a transformation of a chain, as described by the transformation service
+type TransformationStep struct {
+	Name       string
+	Type       string // "passthrough", "generator", "null_returning", "merge" or "split"
+	InputType  string // Data type the transformation reads, empty for generators
+	OutputType string // Data type the transformation returns, empty for null-returning ones
+}
+
+// TransformationChainName returns the transformation name of a mapping rule applying the
+// transformations of a chain in order
+func TransformationChainName(chain []string) (string, error) {
+	steps := make([]string, 0, len(chain))
+	for i, step := range chain {
+		step = strings.TrimSpace(step)
+		if step == "" {
+			return "", fmt.Errorf("step %d of the transformation chain has no transformation", i+1)
+		}
+		if strings.Contains(step, TransformationChainSeparator) {
+			return "", fmt.Errorf("step %d of the transformation chain contains %q", i+1, TransformationChainSeparator)
+		}
+		steps = append(steps, step)
+	}
+	return strings.Join(steps, TransformationChainSeparator), nil
+}
+
+// TransformationChain returns the steps of the transformation of a mapping rule, or nil when the
+// rule applies a single transformation
+func TransformationChain(transformationName string) []string {
+	if !strings.Contains(transformationName, TransformationChainSeparator) {
+		return nil
+	}
+	steps := strings.Split(transformationName, TransformationChainSeparator)
+	for i := range steps {
+		steps[i] = strings.TrimSpace(steps[i])
+	}
+	return steps
+}
+
+// ValidateTransformationChain checks that each step of a chain reads the data type the previous
+// step returns, that only the first step generates values or merges those of several source
+// columns, and only the last discards values or splits them into several target columns. It
+// returns the type of the chain as a whole.
+func ValidateTransformationChain(steps []TransformationStep) (string, error) {
+	if len(steps) == 0 {
+		return "", fmt.Errorf("the transformation chain has no steps")
+	}
+
+	last := len(steps) - 1
+	for i, step := range steps {
+		if step.Type == "generator" && i > 0 {
+			return "", fmt.Errorf("transformation '%s' is a generator and can only be the first step of a chain", step.Name)
+		}
+		if step.Type == "null_returning" && i < last {
+			return "", fmt.Errorf("transformation '%s' is null-returning and can only be the last step of a chain", step.Name)
+		}
+		if step.Type == "merge" && i > 0 {
+			return "", fmt.Errorf("transformation '%s' merges values and can only be the first step of a chain", step.Name)
+		}
+		if step.Type == "split" && i < last {
+			return "", fmt.Errorf("transformation '%s' splits values and can only be the last step of a chain", step.Name)
+		}
+		if i > 0 && !dataTypesCompatible(steps[i-1].OutputType, step.InputType) {
+			return "", fmt.Errorf("transformation '%s' returns %s, which the next step '%s' cannot read (it reads %s)",
+				steps[i-1].Name, steps[i-1].OutputType, step.Name, step.InputType)
+		}
+	}
+
+	first, final := steps[0].Type, steps[last].Type
+	switch {
+	case first == "generator" && final == "null_returning":
+		return "", fmt.Errorf("a transformation chain cannot both generate and discard its values")
+	case first == "merge" && final == "null_returning":
+		return "", fmt.Errorf("a transformation chain cannot both merge and discard its values")
+	case first == "merge" && final == "split":
+		return "", fmt.Errorf("a transformation chain cannot both merge and split its values")
+	case first == "generator":
+		return "generator", nil
+	case final == "null_returning":
+		return "null_returning", nil
+	case first == "merge":
+		return "merge", nil
+	case final == "split":
+		return "split", nil
+	}
+	return "passthrough", nil
+}
+
+// dataTypesCompatible reports whether a transformation reading the input type can read values of
+// the output type. Transformations that do not describe their types are taken to be compatible.
+func dataTypesCompatible(outputType, inputType string) bool {
+	if outputType == "" || inputType == "" || outputType == anyDataType || inputType == anyDataType {
+		return true
+	}
+	return strings.EqualFold(outputType, inputType)
+}
@@ -0,0 +1,71 @@
+package mapping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransformationChainName(t *testing.T) {
+	name, err := TransformationChainName([]string{"trim", " lowercase ", "hash_sha256"})
+	if err != nil || name != "trim|lowercase|hash_sha256" {
+		t.Fatalf("got %q, %v", name, err)
+	}
+	if got := TransformationChain(name); !reflect.DeepEqual(got, []string{"trim", "lowercase", "hash_sha256"}) {
+		t.Errorf("TransformationChain(%q) = %v", name, got)
+	}
+	if got := TransformationChain("uppercase"); got != nil {
+		t.Errorf("a single transformation is not a chain, got %v", got)
+	}
+
+	for _, chain := range [][]string{{"trim", ""}, {"trim", "lowercase|reverse"}} {
+		if _, err := TransformationChainName(chain); err == nil {
+			t.Errorf("%q was accepted", chain)
+		}
+	}
+}
+
+func TestValidateTransformationChain(t *testing.T) {
+	trim := TransformationStep{Name: "trim", Type: "passthrough", InputType: "string", OutputType: "string"}
+	csvToJSON := TransformationStep{Name: "csv_to_json", Type: "passthrough", InputType: "string", OutputType: "json"}
+	jsonToCSV := TransformationStep{Name: "json_to_csv", Type: "passthrough", InputType: "json", OutputType: "string"}
+	direct := TransformationStep{Name: "direct_mapping", Type: "passthrough", InputType: "any", OutputType: "any"}
+	uuid := TransformationStep{Name: "uuid_generator", Type: "generator", OutputType: "string"}
+	export := TransformationStep{Name: "null_export", Type: "null_returning", InputType: "any"}
+	concat := TransformationStep{Name: "concat", Type: "merge", InputType: "string", OutputType: "string"}
+	split := TransformationStep{Name: "split", Type: "split", InputType: "string", OutputType: "string"}
+
+	tests := []struct {
+		name     string
+		steps    []TransformationStep
+		wantType string
+		wantErr  string
+	}{
+		{"matching types", []TransformationStep{trim, csvToJSON, jsonToCSV}, "passthrough", ""},
+		{"any reads everything", []TransformationStep{csvToJSON, direct, trim}, "passthrough", ""},
+		{"generator first", []TransformationStep{uuid, trim}, "generator", ""},
+		{"null-returning last", []TransformationStep{trim, export}, "null_returning", ""},
+		{"mismatched types", []TransformationStep{csvToJSON, trim}, "", "'csv_to_json' returns json"},
+		{"generator later", []TransformationStep{trim, uuid}, "", "can only be the first step"},
+		{"null-returning earlier", []TransformationStep{export, trim}, "", "can only be the last step"},
+		{"generate and discard", []TransformationStep{uuid, export}, "", "both generate and discard"},
+		{"merge first", []TransformationStep{concat, trim}, "merge", ""},
+		{"split last", []TransformationStep{trim, split}, "split", ""},
+		{"merge later", []TransformationStep{trim, concat}, "", "can only be the first step"},
+		{"split earlier", []TransformationStep{split, trim}, "", "can only be the last step"},
+		{"merge and split", []TransformationStep{concat, split}, "", "both merge and split"},
+		{"no steps", nil, "", "no steps"},
+	}
+	for _, tt := range tests {
+		got, err := ValidateTransformationChain(tt.steps)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.wantType {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, err, tt.wantType)
+		}
+	}
+}
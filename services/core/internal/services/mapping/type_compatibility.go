@@ -0,0 +1,99 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Categories of data types, across the types of the databases and of transformations
+const (
+	typeInteger  = "integer"
+	typeDecimal  = "decimal"
+	typeString   = "string"
+	typeBoolean  = "boolean"
+	typeDateTime = "datetime"
+	typeBinary   = "binary"
+	typeJSON     = "json"
+	typeUUID     = "uuid"
+	typeVector   = "vector"
+)
+
+// dataTypeCategories maps the base names of data types to their category
+var dataTypeCategories = map[string]string{
+	"integer": typeInteger, "int": typeInteger, "bigint": typeInteger, "smallint": typeInteger, "tinyint": typeInteger,
+	"mediumint": typeInteger, "int2": typeInteger, "int4": typeInteger, "int8": typeInteger, "serial": typeInteger,
+	"bigserial": typeInteger, "smallserial": typeInteger, "long": typeInteger, "int32": typeInteger, "int64": typeInteger,
+
+	"decimal": typeDecimal, "numeric": typeDecimal, "float": typeDecimal, "double": typeDecimal,
+	"double precision": typeDecimal, "real": typeDecimal, "float4": typeDecimal, "float8": typeDecimal,
+	"number": typeDecimal, "money": typeDecimal, "float32": typeDecimal, "float64": typeDecimal,
+
+	"string": typeString, "text": typeString, "varchar": typeString, "char": typeString, "character": typeString,
+	"character varying": typeString, "nvarchar": typeString, "nchar": typeString, "ntext": typeString,
+	"varchar2": typeString, "nvarchar2": typeString, "clob": typeString, "nclob": typeString, "tinytext": typeString,
+	"mediumtext": typeString, "longtext": typeString, "citext": typeString, "enum": typeString,
+
+	"boolean": typeBoolean, "bool": typeBoolean, "bit": typeBoolean,
+
+	"date": typeDateTime, "time": typeDateTime, "datetime": typeDateTime, "datetime2": typeDateTime,
+	"timestamp": typeDateTime, "timestamptz": typeDateTime, "timetz": typeDateTime, "smalldatetime": typeDateTime,
+	"datetimeoffset": typeDateTime, "timestamp with time zone": typeDateTime, "timestamp without time zone": typeDateTime,
+
+	"bytea": typeBinary, "blob": typeBinary, "binary": typeBinary, "varbinary": typeBinary, "longblob": typeBinary,
+	"mediumblob": typeBinary, "tinyblob": typeBinary, "raw": typeBinary, "bytes": typeBinary,
+
+	"json": typeJSON, "jsonb": typeJSON, "object": typeJSON, "document": typeJSON, "map": typeJSON,
+
+	"uuid": typeUUID, "uniqueidentifier": typeUUID,
+
+	"vector": typeVector, "embedding": typeVector,
+}
+
+// DataTypeCategory returns the category of a data type, e.g. "integer" for "BIGINT" or
+// "string" for "varchar(255)", or "" when the type is not known
+func DataTypeCategory(dataType string) string {
+	name := strings.ToLower(strings.TrimSpace(dataType))
+	if i := strings.Index(name, "("); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	name = strings.TrimSuffix(name, "[]")
+	name = strings.TrimSuffix(name, " unsigned")
+	return dataTypeCategories[name]
+}
+
+// TypeCompatibility is whether values of a source data type can be written to a target data type
+type TypeCompatibility struct {
+	Compatible bool
+	Lossy      bool   // Some values may fail to convert or lose precision
+	Reason     string // Why the types are incompatible or the conversion lossy
+}
+
+// CheckTypeCompatibility checks that values of the source data type can be written to columns of
+// the target data type. Types of unknown categories are taken to be compatible, as nothing can be
+// told about them.
+func CheckTypeCompatibility(sourceType, targetType string) TypeCompatibility {
+	source, target := DataTypeCategory(sourceType), DataTypeCategory(targetType)
+	if source == "" || target == "" || source == target {
+		return TypeCompatibility{Compatible: true}
+	}
+
+	lossy := func(reason string) TypeCompatibility {
+		return TypeCompatibility{Compatible: true, Lossy: true, Reason: reason}
+	}
+	switch {
+	case target == typeString && source != typeBinary && source != typeVector:
+		// Values are written as their text
+		return TypeCompatibility{Compatible: true}
+	case source == typeInteger && target == typeDecimal:
+		return TypeCompatibility{Compatible: true}
+	case source == typeDecimal && target == typeInteger:
+		return lossy(fmt.Sprintf("%s values lose their fractional digits in %s", sourceType, targetType))
+	case source == typeBoolean && (target == typeInteger || target == typeDecimal):
+		return TypeCompatibility{Compatible: true}
+	case source == typeString && target != typeBinary && target != typeVector:
+		return lossy(fmt.Sprintf("%s values that are not valid %s values are rejected by the target", sourceType, targetType))
+	case source == typeJSON && target == typeVector, source == typeVector && target == typeJSON:
+		return lossy(fmt.Sprintf("%s values must be arrays of numbers to be written as %s", sourceType, targetType))
+	}
+	return TypeCompatibility{Reason: fmt.Sprintf("%s values cannot be written to %s", sourceType, targetType)}
+}
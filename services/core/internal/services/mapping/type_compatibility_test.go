@@ -0,0 +1,29 @@
+package mapping
+
+import "testing"
+
+func TestCheckTypeCompatibility(t *testing.T) {
+	tests := []struct {
+		source, target    string
+		compatible, lossy bool
+	}{
+		{"INTEGER", "bigint", true, false},
+		{"varchar(255)", "TEXT", true, false},
+		{"int4", "numeric(10,2)", true, false},
+		{"timestamp", "varchar", true, false},
+		{"double precision", "integer", true, true},
+		{"text", "integer", true, true},
+		{"timestamptz", "boolean", false, false},
+		{"bytea", "text", false, false},
+		{"geometry", "integer", true, false},
+	}
+	for _, tt := range tests {
+		got := CheckTypeCompatibility(tt.source, tt.target)
+		if got.Compatible != tt.compatible || got.Lossy != tt.lossy {
+			t.Errorf("CheckTypeCompatibility(%q, %q) = %+v, want compatible=%t lossy=%t", tt.source, tt.target, got, tt.compatible, tt.lossy)
+		}
+		if (!got.Compatible || got.Lossy) && got.Reason == "" {
+			t.Errorf("CheckTypeCompatibility(%q, %q) gives no reason", tt.source, tt.target)
+		}
+	}
+}
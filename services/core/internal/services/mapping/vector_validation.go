@@ -0,0 +1,220 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Defaults of the validation of the vectors a data copy writes into a vector store
+const (
+	DefaultVectorSamples   = 20
+	DefaultVectorTopK      = 10
+	DefaultMinVectorRecall = 0.9
+)
+
+// VectorSample is a vector read back from the target of a copy
+type VectorSample struct {
+	ID     string
+	Vector []float32
+}
+
+// SearchFunc returns the IDs of the topK nearest neighbors of a vector in a database, nearest
+// first
+type SearchFunc func(ctx context.Context, vector []float32, topK int) ([]string, error)
+
+// VectorValidation is the outcome of the validation of the vectors copied into a vector store
+type VectorValidation struct {
+	Samples    int
+	TopK       int
+	Dimensions []int // Distinct dimensions of the sampled vectors, one unless vectors were cut or padded
+
+	// SelfMatchRate is the share of the sampled vectors the target finds as their own nearest
+	// neighbor. An index built with another metric or dimension than the vectors lowers it.
+	SelfMatchRate float64
+
+	// Recall is the mean share of the neighbors the source finds for a sampled vector that the
+	// target finds as well, and MinRecall the lowest share of a sample. They are only measured
+	// when the source searches vectors too, and assume that the copy keeps the IDs.
+	SourceCompared bool
+	Recall         float64
+	MinRecall      float64
+
+	FailedSearches int
+	Issues         []string // Why the validation failed, empty when it passed
+	Passed         bool
+}
+
+// VectorValidator re-queries the nearest neighbors of vectors sampled from the target of a
+// copy, on the target and, when it can search vectors, on the source
+type VectorValidator struct {
+	Target    SearchFunc
+	Source    SearchFunc // Nil if the source cannot search vectors
+	TopK      int
+	MinRecall float64 // Least self-match rate and recall for the validation to pass
+}
+
+// NewVectorValidator creates a validator with the default neighbors and recall
+func NewVectorValidator(target, source SearchFunc) *VectorValidator {
+	return &VectorValidator{
+		Target:    target,
+		Source:    source,
+		TopK:      DefaultVectorTopK,
+		MinRecall: DefaultMinVectorRecall,
+	}
+}
+
+// Validate searches the neighbors of each sample and reports how well the target finds them.
+// A failed search counts against the validation without stopping it.
+func (v *VectorValidator) Validate(ctx context.Context, samples []VectorSample) VectorValidation {
+	result := VectorValidation{Samples: len(samples), TopK: v.TopK, SourceCompared: v.Source != nil}
+	if len(samples) == 0 {
+		result.Issues = append(result.Issues, "no vectors could be read back from the target")
+		return result
+	}
+
+	dimensions := make(map[int]bool)
+	var firstError error
+	selfMatches, compared := 0, 0
+	totalRecall, minRecall := 0.0, 1.0
+	for _, sample := range samples {
+		if err := ctx.Err(); err != nil {
+			result.Issues = append(result.Issues, fmt.Sprintf("validation interrupted: %v", err))
+			return result
+		}
+		dimensions[len(sample.Vector)] = true
+
+		targetIDs, err := v.Target(ctx, sample.Vector, v.TopK)
+		if err != nil {
+			result.FailedSearches++
+			if firstError == nil {
+				firstError = fmt.Errorf("target search of vector %s: %w", sample.ID, err)
+			}
+			continue
+		}
+		if len(targetIDs) > 0 && targetIDs[0] == sample.ID {
+			selfMatches++
+		}
+
+		if v.Source == nil {
+			continue
+		}
+		sourceIDs, err := v.Source(ctx, sample.Vector, v.TopK)
+		if err != nil {
+			result.FailedSearches++
+			if firstError == nil {
+				firstError = fmt.Errorf("source search of vector %s: %w", sample.ID, err)
+			}
+			continue
+		}
+		recall := overlap(sourceIDs, targetIDs)
+		totalRecall += recall
+		if recall < minRecall {
+			minRecall = recall
+		}
+		compared++
+	}
+
+	for dimension := range dimensions {
+		result.Dimensions = append(result.Dimensions, dimension)
+	}
+	sort.Ints(result.Dimensions)
+	result.SelfMatchRate = float64(selfMatches) / float64(len(samples))
+	if compared > 0 {
+		result.Recall = totalRecall / float64(compared)
+		result.MinRecall = minRecall
+	}
+
+	if len(result.Dimensions) > 1 {
+		result.Issues = append(result.Issues, fmt.Sprintf("sampled vectors have different dimensions: %s", joinInts(result.Dimensions)))
+	}
+	if firstError != nil {
+		result.Issues = append(result.Issues, fmt.Sprintf("%d searches failed, first: %v", result.FailedSearches, firstError))
+	}
+	if result.SelfMatchRate < v.MinRecall {
+		result.Issues = append(result.Issues, fmt.Sprintf("only %.0f%% of the sampled vectors are their own nearest neighbor in the target, check the metric and dimension of its index",
+			result.SelfMatchRate*100))
+	}
+	if compared > 0 && result.Recall < v.MinRecall {
+		result.Issues = append(result.Issues, fmt.Sprintf("the target finds %.0f%% of the %d nearest neighbors the source finds (lowest %.0f%%), below %.0f%%",
+			result.Recall*100, v.TopK, result.MinRecall*100, v.MinRecall*100))
+	}
+	result.Passed = len(result.Issues) == 0
+	return result
+}
+
+// SampleVectors returns the vectors of rows read from a vector store: the "id" of a row and
+// its longest array of numbers, whatever the store names it (embedding, values or vector).
+// Rows without both are skipped.
+func SampleVectors(rows []map[string]interface{}) []VectorSample {
+	var samples []VectorSample
+	for _, row := range rows {
+		id, ok := row["id"]
+		if !ok || id == nil {
+			continue
+		}
+		var vector []float32
+		for _, value := range row {
+			if v, ok := toVector(value); ok && len(v) > len(vector) {
+				vector = v
+			}
+		}
+		if len(vector) == 0 {
+			continue
+		}
+		samples = append(samples, VectorSample{ID: fmt.Sprint(id), Vector: vector})
+	}
+	return samples
+}
+
+// toVector converts an array of numbers, as decoded from JSON or returned by an adapter
+func toVector(value interface{}) ([]float32, bool) {
+	switch v := value.(type) {
+	case []float32:
+		return v, true
+	case []float64:
+		vector := make([]float32, len(v))
+		for i, f := range v {
+			vector[i] = float32(f)
+		}
+		return vector, true
+	case []interface{}:
+		vector := make([]float32, len(v))
+		for i, element := range v {
+			f, ok := element.(float64)
+			if !ok {
+				return nil, false
+			}
+			vector[i] = float32(f)
+		}
+		return vector, true
+	}
+	return nil, false
+}
+
+// overlap returns the share of the reference IDs found in the other IDs
+func overlap(reference, other []string) float64 {
+	if len(reference) == 0 {
+		return 1
+	}
+	found := make(map[string]bool, len(other))
+	for _, id := range other {
+		found[id] = true
+	}
+	common := 0
+	for _, id := range reference {
+		if found[id] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(reference))
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,127 @@
+package mapping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fakeVectorStore searches its vectors by brute force with a similarity
+type fakeVectorStore struct {
+	vectors    []VectorSample
+	similarity func(a, b []float32) float64
+}
+
+func (f *fakeVectorStore) search(ctx context.Context, vector []float32, topK int) ([]string, error) {
+	if len(vector) != len(f.vectors[0].Vector) {
+		return nil, errors.New("vector dimension mismatch")
+	}
+	ranked := append([]VectorSample(nil), f.vectors...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return f.similarity(vector, ranked[i].Vector) > f.similarity(vector, ranked[j].Vector)
+	})
+	ids := make([]string, 0, topK)
+	for i := 0; i < topK && i < len(ranked); i++ {
+		ids = append(ids, ranked[i].ID)
+	}
+	return ids, nil
+}
+
+func dot(a, b []float32) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func cosine(a, b []float32) float64 {
+	return dot(a, b) / math.Sqrt(dot(a, a)*dot(b, b))
+}
+
+// testVectors point in different directions with different lengths, so that an inner product
+// finds the longest vectors nearest instead of the vectors themselves
+func testVectors() []VectorSample {
+	return []VectorSample{
+		{ID: "a", Vector: []float32{1, 0, 0}},
+		{ID: "b", Vector: []float32{0, 1, 0}},
+		{ID: "c", Vector: []float32{0, 0, 1}},
+		{ID: "d", Vector: []float32{8, 8, 1}},
+		{ID: "e", Vector: []float32{1, 9, 9}},
+	}
+}
+
+func TestVectorValidatorPasses(t *testing.T) {
+	source := &fakeVectorStore{vectors: testVectors(), similarity: cosine}
+	target := &fakeVectorStore{vectors: testVectors(), similarity: cosine}
+	validator := NewVectorValidator(target.search, source.search)
+	validator.TopK = 3
+
+	result := validator.Validate(context.Background(), testVectors())
+	if !result.Passed {
+		t.Fatalf("validation failed: %v", result.Issues)
+	}
+	if result.SelfMatchRate != 1 || result.Recall != 1 || result.MinRecall != 1 || !result.SourceCompared {
+		t.Errorf("result = %+v, want every vector found with full recall", result)
+	}
+	if len(result.Dimensions) != 1 || result.Dimensions[0] != 3 {
+		t.Errorf("dimensions = %v, want [3]", result.Dimensions)
+	}
+}
+
+func TestVectorValidatorDetectsWrongMetric(t *testing.T) {
+	source := &fakeVectorStore{vectors: testVectors(), similarity: cosine}
+	target := &fakeVectorStore{vectors: testVectors(), similarity: dot}
+	validator := NewVectorValidator(target.search, source.search)
+	validator.TopK = 2
+
+	result := validator.Validate(context.Background(), testVectors())
+	if result.Passed {
+		t.Fatal("validation passed although the target searches with an inner product")
+	}
+	if result.SelfMatchRate >= 1 || result.Recall >= 1 {
+		t.Errorf("self match rate = %v, recall = %v, want both below 1", result.SelfMatchRate, result.Recall)
+	}
+	if len(result.Issues) != 2 {
+		t.Errorf("issues = %v, want the self match rate and the recall", result.Issues)
+	}
+}
+
+func TestVectorValidatorWithoutSource(t *testing.T) {
+	target := &fakeVectorStore{vectors: testVectors(), similarity: cosine}
+	samples := append(testVectors(), VectorSample{ID: "f", Vector: []float32{1, 1}})
+
+	result := NewVectorValidator(target.search, nil).Validate(context.Background(), samples)
+	if result.Passed || result.SourceCompared {
+		t.Fatalf("result = %+v, want a failed validation without the source", result)
+	}
+	if result.FailedSearches != 1 {
+		t.Errorf("failed searches = %d, want 1", result.FailedSearches)
+	}
+	if len(result.Dimensions) != 2 || !strings.Contains(strings.Join(result.Issues, "\n"), "different dimensions: 2, 3") {
+		t.Errorf("issues = %v, want the dimensions 2 and 3 reported", result.Issues)
+	}
+}
+
+func TestSampleVectors(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": "a", "embedding": []interface{}{0.5, 1.0}, "tags": []interface{}{"x"}},
+		{"id": float64(7), "values": []float32{1, 2, 3}, "bucket": []interface{}{1.0}},
+		{"id": "no_vector", "document": "text"},
+		{"vector": []interface{}{1.0}},
+	}
+
+	samples := SampleVectors(rows)
+	if len(samples) != 2 {
+		t.Fatalf("samples = %+v, want 2", samples)
+	}
+	if samples[0].ID != "a" || len(samples[0].Vector) != 2 || samples[0].Vector[1] != 1 {
+		t.Errorf("first sample = %+v", samples[0])
+	}
+	if samples[1].ID != "7" || len(samples[1].Vector) != 3 {
+		t.Errorf("second sample = %+v", samples[1])
+	}
+}
@@ -31,20 +31,14 @@ type Policy struct {
 	TenantID    string
 	Name        string
 	Description string
-	Type        string
-	Effect      string
-	Actions     []string
-	Resources   []string
-	Conditions  map[string]interface{}
-	Priority    int32
-	Enabled     bool
+	Object      map[string]interface{}
 	OwnerID     string
 	Created     time.Time
 	Updated     time.Time
 }
 
 // Create creates a new policy
-func (s *Service) Create(ctx context.Context, tenantID, name, description, policyType, effect string, actions, resources []string, conditions map[string]interface{}, priority int32, ownerID string) (*Policy, error) {
+func (s *Service) Create(ctx context.Context, tenantID, name, description string, object map[string]interface{}, ownerID string) (*Policy, error) {
 	s.logger.Infof("Creating policy in database for tenant: %s, name: %s", tenantID, name)
 
 	// Check if the tenant exists
@@ -69,30 +63,19 @@ func (s *Service) Create(ctx context.Context, tenantID, name, description, polic
 
 	// Insert the policy into the database
 	query := `
-		INSERT INTO policies (tenant_id, policy_name, policy_description, policy_type, 
-		                     policy_effect, policy_actions, policy_resources, 
-		                     policy_conditions, policy_priority, owner_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING policy_id, tenant_id, policy_name, policy_description, policy_type, 
-		          policy_effect, COALESCE(policy_actions, '{}') as policy_actions, 
-		          COALESCE(policy_resources, '{}') as policy_resources,
-		          COALESCE(policy_conditions, '{}') as policy_conditions, 
-		          policy_priority, policy_enabled, owner_id, created, updated
+		INSERT INTO policies (tenant_id, policy_name, policy_description, policy_object, owner_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING policy_id, tenant_id, policy_name, policy_description,
+		          COALESCE(policy_object, '{}') as policy_object, owner_id, created, updated
 	`
 
 	var policy Policy
-	err = s.db.Pool().QueryRow(ctx, query, tenantID, name, description, policyType, effect, actions, resources, conditions, priority, ownerID).Scan(
+	err = s.db.Pool().QueryRow(ctx, query, tenantID, name, description, object, ownerID).Scan(
 		&policy.ID,
 		&policy.TenantID,
 		&policy.Name,
 		&policy.Description,
-		&policy.Type,
-		&policy.Effect,
-		&policy.Actions,
-		&policy.Resources,
-		&policy.Conditions,
-		&policy.Priority,
-		&policy.Enabled,
+		&policy.Object,
 		&policy.OwnerID,
 		&policy.Created,
 		&policy.Updated,
@@ -109,11 +92,8 @@ func (s *Service) Create(ctx context.Context, tenantID, name, description, polic
 func (s *Service) Get(ctx context.Context, tenantID, id string) (*Policy, error) {
 	s.logger.Infof("Retrieving policy from database with ID: %s", id)
 	query := `
-		SELECT policy_id, tenant_id, policy_name, policy_description, policy_type, 
-		       policy_effect, COALESCE(policy_actions, '{}') as policy_actions, 
-		       COALESCE(policy_resources, '{}') as policy_resources,
-		       COALESCE(policy_conditions, '{}') as policy_conditions, 
-		       policy_priority, policy_enabled, owner_id, created, updated
+		SELECT policy_id, tenant_id, policy_name, policy_description,
+		       COALESCE(policy_object, '{}') as policy_object, owner_id, created, updated
 		FROM policies
 		WHERE tenant_id = $1 AND policy_id = $2
 	`
@@ -124,13 +104,7 @@ func (s *Service) Get(ctx context.Context, tenantID, id string) (*Policy, error)
 		&policy.TenantID,
 		&policy.Name,
 		&policy.Description,
-		&policy.Type,
-		&policy.Effect,
-		&policy.Actions,
-		&policy.Resources,
-		&policy.Conditions,
-		&policy.Priority,
-		&policy.Enabled,
+		&policy.Object,
 		&policy.OwnerID,
 		&policy.Created,
 		&policy.Updated,
@@ -150,14 +124,11 @@ func (s *Service) Get(ctx context.Context, tenantID, id string) (*Policy, error)
 func (s *Service) List(ctx context.Context, tenantID string) ([]*Policy, error) {
 	s.logger.Infof("Listing policies for tenant: %s", tenantID)
 	query := `
-		SELECT policy_id, tenant_id, policy_name, policy_description, policy_type, 
-		       policy_effect, COALESCE(policy_actions, '{}') as policy_actions, 
-		       COALESCE(policy_resources, '{}') as policy_resources,
-		       COALESCE(policy_conditions, '{}') as policy_conditions, 
-		       policy_priority, policy_enabled, owner_id, created, updated
+		SELECT policy_id, tenant_id, policy_name, policy_description,
+		       COALESCE(policy_object, '{}') as policy_object, owner_id, created, updated
 		FROM policies
 		WHERE tenant_id = $1
-		ORDER BY policy_priority DESC, policy_name
+		ORDER BY policy_name
 	`
 
 	rows, err := s.db.Pool().Query(ctx, query, tenantID)
@@ -175,13 +146,7 @@ func (s *Service) List(ctx context.Context, tenantID string) ([]*Policy, error)
 			&policy.TenantID,
 			&policy.Name,
 			&policy.Description,
-			&policy.Type,
-			&policy.Effect,
-			&policy.Actions,
-			&policy.Resources,
-			&policy.Conditions,
-			&policy.Priority,
-			&policy.Enabled,
+			&policy.Object,
 			&policy.OwnerID,
 			&policy.Created,
 			&policy.Updated,
@@ -222,9 +187,7 @@ func (s *Service) Update(ctx context.Context, tenantID, id string, updates map[s
 
 	for field, value := range updates {
 		switch field {
-		case "policy_name", "policy_description", "policy_type", "policy_effect",
-			"policy_actions", "policy_resources", "policy_conditions",
-			"policy_priority", "policy_enabled":
+		case "policy_name", "policy_description", "policy_object":
 			setParts = append(setParts, fmt.Sprintf("%s = $%d", field, argIndex))
 			args = append(args, value)
 			argIndex++
@@ -249,11 +212,8 @@ func (s *Service) Update(ctx context.Context, tenantID, id string, updates map[s
 		UPDATE policies 
 		SET %s
 		WHERE tenant_id = $1 AND policy_id = $2
-		RETURNING policy_id, tenant_id, policy_name, policy_description, policy_type, 
-		          policy_effect, COALESCE(policy_actions, '{}') as policy_actions, 
-		          COALESCE(policy_resources, '{}') as policy_resources,
-		          COALESCE(policy_conditions, '{}') as policy_conditions, 
-		          policy_priority, policy_enabled, owner_id, created, updated
+		RETURNING policy_id, tenant_id, policy_name, policy_description,
+		          COALESCE(policy_object, '{}') as policy_object, owner_id, created, updated
 	`, setClause)
 
 	var policy Policy
@@ -262,13 +222,7 @@ func (s *Service) Update(ctx context.Context, tenantID, id string, updates map[s
 		&policy.TenantID,
 		&policy.Name,
 		&policy.Description,
-		&policy.Type,
-		&policy.Effect,
-		&policy.Actions,
-		&policy.Resources,
-		&policy.Conditions,
-		&policy.Priority,
-		&policy.Enabled,
+		&policy.Object,
 		&policy.OwnerID,
 		&policy.Created,
 		&policy.Updated,
@@ -0,0 +1,370 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TypeSchemaChange is the type of policies that decide what happens when a relationship is
+// deployed again after breaking changes to the schema of its source. The policy object of such a
+// policy is e.g.
+//
+//	{
+//	  "type": "schema_change",
+//	  "workspace": "production",
+//	  "default_action": "warn",
+//	  "actions": {"column_dropped": "block", "type_narrowed": "block", "not_null_added": "warn"}
+//	}
+//
+// The actions are allow, warn or block, by class of change; classes without an action get the
+// default action, warn if none is given. Without a workspace the policy applies to all workspaces.
+const TypeSchemaChange = "schema_change"
+
+// Classes of breaking schema changes
+const (
+	ChangeTableDropped        = "table_dropped"
+	ChangeTableRenamed        = "table_renamed"
+	ChangeColumnDropped       = "column_dropped"
+	ChangeColumnRenamed       = "column_renamed"
+	ChangeTypeNarrowed        = "type_narrowed"
+	ChangeTypeChanged         = "type_changed"
+	ChangeNotNullAdded        = "not_null_added"
+	ChangeRequiredColumnAdded = "required_column_added"
+	ChangeOtherBreaking       = "other_breaking"
+)
+
+// schemaChangeClasses are the classes a policy can give an action
+var schemaChangeClasses = map[string]bool{
+	ChangeTableDropped: true, ChangeTableRenamed: true, ChangeColumnDropped: true, ChangeColumnRenamed: true,
+	ChangeTypeNarrowed: true, ChangeTypeChanged: true, ChangeNotNullAdded: true,
+	ChangeRequiredColumnAdded: true, ChangeOtherBreaking: true,
+}
+
+// Actions of schema change policies, from the least to the most severe
+const (
+	ActionAllow = "allow"
+	ActionWarn  = "warn"
+	ActionBlock = "block"
+)
+
+var actionSeverity = map[string]int{ActionAllow: 0, ActionWarn: 1, ActionBlock: 2}
+
+// SchemaChangePolicy is what a schema change policy does on the classes of breaking changes
+type SchemaChangePolicy struct {
+	PolicyID      string
+	PolicyName    string
+	Workspace     string            // Name of the workspace the policy applies to, empty for all workspaces
+	DefaultAction string            // Action on the classes without an action
+	Actions       map[string]string // Action by class of change
+}
+
+// SchemaChange is a change between two schemas, as recorded by the schema comparator
+type SchemaChange struct {
+	ChangeType  string // added, removed, modified or renamed
+	ObjectType  string // Such as table or column
+	ObjectName  string
+	ParentName  string // Table of a column, empty for tables
+	Field       string // Modified property, such as data_type or nullable
+	OldValue    string
+	NewValue    string
+	Breaking    bool
+	Description string
+}
+
+// SchemaChangeFinding is a breaking change and the action the policies take on it
+type SchemaChangeFinding struct {
+	Change     SchemaChange
+	Class      string
+	Action     string
+	PolicyName string // Policy deciding the action, empty for the default warning
+}
+
+// ParseSchemaChangePolicy reads the schema change policy of a policy object. It returns nil when
+// the policy is of another type.
+func ParseSchemaChangePolicy(object map[string]interface{}) (*SchemaChangePolicy, error) {
+	if policyType, _ := object["type"].(string); policyType != TypeSchemaChange {
+		return nil, nil
+	}
+
+	schemaPolicy := &SchemaChangePolicy{DefaultAction: ActionWarn, Actions: make(map[string]string)}
+	schemaPolicy.Workspace, _ = object["workspace"].(string)
+	if value, ok := object["default_action"]; ok {
+		action, _ := value.(string)
+		if _, ok := actionSeverity[action]; !ok {
+			return nil, fmt.Errorf("default_action of a schema change policy must be allow, warn or block, not %v", value)
+		}
+		schemaPolicy.DefaultAction = action
+	}
+	if value, ok := object["actions"]; ok {
+		actions, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("actions of a schema change policy must be an object")
+		}
+		for class, value := range actions {
+			if !schemaChangeClasses[class] {
+				return nil, fmt.Errorf("unknown class of schema change %q", class)
+			}
+			action, _ := value.(string)
+			if _, ok := actionSeverity[action]; !ok {
+				return nil, fmt.Errorf("action on %s must be allow, warn or block, not %v", class, value)
+			}
+			schemaPolicy.Actions[class] = action
+		}
+	}
+	return schemaPolicy, nil
+}
+
+// action returns the action of the policy on a class of change
+func (p *SchemaChangePolicy) action(class string) string {
+	if action, ok := p.Actions[class]; ok {
+		return action
+	}
+	return p.DefaultAction
+}
+
+// ClassifySchemaChange returns the class of a breaking change, or an empty string for changes
+// that do not break the applications reading the object, such as a widened data type
+func ClassifySchemaChange(change SchemaChange) string {
+	switch change.ChangeType {
+	case "removed":
+		switch change.ObjectType {
+		case "table":
+			return ChangeTableDropped
+		case "column":
+			return ChangeColumnDropped
+		}
+	case "renamed":
+		switch change.ObjectType {
+		case "table":
+			return ChangeTableRenamed
+		case "column":
+			return ChangeColumnRenamed
+		}
+	case "added":
+		if change.ObjectType == "column" && change.Breaking {
+			return ChangeRequiredColumnAdded
+		}
+	case "modified":
+		if change.ObjectType == "column" {
+			switch change.Field {
+			case "data_type":
+				switch compareDataTypes(change.OldValue, change.NewValue) {
+				case typeWidened:
+					return ""
+				case typeNarrowed:
+					return ChangeTypeNarrowed
+				}
+				return ChangeTypeChanged
+			case "nullable":
+				if change.OldValue == "true" && change.NewValue == "false" {
+					return ChangeNotNullAdded
+				}
+				return ""
+			}
+		}
+	}
+	if change.Breaking {
+		return ChangeOtherBreaking
+	}
+	return ""
+}
+
+// EvaluateSchemaChanges returns the breaking changes among the changes of a schema in a
+// workspace, with the most severe action of the policies applying to the workspace. Without a
+// policy, breaking changes are warned about.
+func EvaluateSchemaChanges(policies []*SchemaChangePolicy, workspace string, changes []SchemaChange) []SchemaChangeFinding {
+	var applying []*SchemaChangePolicy
+	for _, p := range policies {
+		if p.Workspace == "" || p.Workspace == workspace {
+			applying = append(applying, p)
+		}
+	}
+
+	var findings []SchemaChangeFinding
+	for _, change := range changes {
+		class := ClassifySchemaChange(change)
+		if class == "" {
+			continue
+		}
+		finding := SchemaChangeFinding{Change: change, Class: class, Action: ActionWarn}
+		for i, p := range applying {
+			action := p.action(class)
+			if i == 0 || actionSeverity[action] > actionSeverity[finding.Action] {
+				finding.Action, finding.PolicyName = action, p.PolicyName
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Blocked reports whether the policies block any of the findings
+func Blocked(findings []SchemaChangeFinding) bool {
+	for _, finding := range findings {
+		if finding.Action == ActionBlock {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaChangePolicies returns the schema change policies of a tenant
+func (s *Service) SchemaChangePolicies(ctx context.Context, tenantID string) ([]*SchemaChangePolicy, error) {
+	policies, err := s.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaPolicies []*SchemaChangePolicy
+	for _, p := range policies {
+		schemaPolicy, err := ParseSchemaChangePolicy(p.Object)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		if schemaPolicy == nil {
+			continue
+		}
+		schemaPolicy.PolicyID = p.ID
+		schemaPolicy.PolicyName = p.Name
+		schemaPolicies = append(schemaPolicies, schemaPolicy)
+	}
+	return schemaPolicies, nil
+}
+
+// Results of comparing two data types
+const (
+	typeUnrelated = iota // Of different families, or not comparable
+	typeWidened          // Every value of the old type fits in the new type
+	typeNarrowed         // Some values of the old type may not fit in the new type
+)
+
+// typeKind is the family of a data type and its rank in the family
+type typeKind struct {
+	family string
+	rank   int
+}
+
+// typeFamilies rank the data types of a family by width; a rank of 0 means unbounded, and the
+// width of the types of a family without ranks is given by their length or precision
+var typeFamilies = map[string]typeKind{
+	"tinyint":     {"integer", 1},
+	"int1":        {"integer", 1},
+	"smallint":    {"integer", 2},
+	"int2":        {"integer", 2},
+	"smallserial": {"integer", 2},
+	"mediumint":   {"integer", 3},
+	"int":         {"integer", 4},
+	"integer":     {"integer", 4},
+	"int4":        {"integer", 4},
+	"serial":      {"integer", 4},
+	"bigint":      {"integer", 5},
+	"int8":        {"integer", 5},
+	"bigserial":   {"integer", 5},
+
+	"real":             {"float", 1},
+	"float4":           {"float", 1},
+	"float":            {"float", 2},
+	"float8":           {"float", 2},
+	"double":           {"float", 2},
+	"double precision": {"float", 2},
+
+	"char":              {"string", -1},
+	"character":         {"string", -1},
+	"nchar":             {"string", -1},
+	"varchar":           {"string", -1},
+	"character varying": {"string", -1},
+	"nvarchar":          {"string", -1},
+	"varchar2":          {"string", -1},
+	"string":            {"string", -1},
+	"text":              {"string", 0},
+	"mediumtext":        {"string", 0},
+	"longtext":          {"string", 0},
+	"clob":              {"string", 0},
+
+	"binary":    {"binary", -1},
+	"varbinary": {"binary", -1},
+	"bytea":     {"binary", 0},
+	"blob":      {"binary", 0},
+	"longblob":  {"binary", 0},
+
+	"numeric": {"numeric", -1},
+	"decimal": {"numeric", -1},
+	"number":  {"numeric", -1},
+}
+
+var dataTypePattern = regexp.MustCompile(`^\s*([a-z][a-z0-9_ ]*?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*$`)
+
+// compareDataTypes tells whether a change from one data type to another widens or narrows it
+func compareDataTypes(oldType, newType string) int {
+	oldFamily, oldRank, oldParams, ok := parseDataType(oldType)
+	if !ok {
+		return typeUnrelated
+	}
+	newFamily, newRank, newParams, ok := parseDataType(newType)
+	if !ok || oldFamily != newFamily {
+		return typeUnrelated
+	}
+
+	switch {
+	case oldRank > 0 && newRank > 0:
+		return widthChange(oldRank, newRank)
+	case oldRank == 0 && newRank == 0:
+		return typeWidened
+	case newRank == 0 || newParams == nil:
+		// Unbounded, or a length or precision left to its maximum
+		return typeWidened
+	case oldRank == 0 || oldParams == nil:
+		return typeNarrowed
+	}
+
+	// Lengths, or precisions and scales
+	result := widthChange(oldParams[0], newParams[0])
+	if len(oldParams) > 1 || len(newParams) > 1 {
+		oldScale, newScale := 0, 0
+		if len(oldParams) > 1 {
+			oldScale = oldParams[1]
+		}
+		if len(newParams) > 1 {
+			newScale = newParams[1]
+		}
+		// The digits before the point must not shrink either
+		if newScale < oldScale || newParams[0]-newScale < oldParams[0]-oldScale {
+			return typeNarrowed
+		}
+	}
+	return result
+}
+
+func widthChange(oldWidth, newWidth int) int {
+	if newWidth < oldWidth {
+		return typeNarrowed
+	}
+	return typeWidened
+}
+
+// parseDataType returns the family, rank and length or precision and scale of a data type
+func parseDataType(dataType string) (string, int, []int, bool) {
+	match := dataTypePattern.FindStringSubmatch(strings.ToLower(dataType))
+	if match == nil {
+		return "", 0, nil, false
+	}
+	kind, ok := typeFamilies[match[1]]
+	if !ok {
+		return "", 0, nil, false
+	}
+	var params []int
+	for _, param := range match[2:] {
+		if param == "" {
+			break
+		}
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return "", 0, nil, false
+		}
+		params = append(params, n)
+	}
+	return kind.family, kind.rank, params, true
+}
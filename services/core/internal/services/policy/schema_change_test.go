@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSchemaChangePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]interface{}
+		want    *SchemaChangePolicy
+		wantErr bool
+	}{
+		{
+			name:   "other policy type",
+			object: map[string]interface{}{"type": "transformation"},
+		},
+		{
+			name:   "defaults",
+			object: map[string]interface{}{"type": "schema_change"},
+			want:   &SchemaChangePolicy{DefaultAction: "warn", Actions: map[string]string{}},
+		},
+		{
+			name: "schema change policy",
+			object: map[string]interface{}{
+				"type":           "schema_change",
+				"workspace":      "production",
+				"default_action": "allow",
+				"actions":        map[string]interface{}{"column_dropped": "block", "not_null_added": "warn"},
+			},
+			want: &SchemaChangePolicy{
+				Workspace:     "production",
+				DefaultAction: "allow",
+				Actions:       map[string]string{"column_dropped": "block", "not_null_added": "warn"},
+			},
+		},
+		{
+			name:    "unknown default action",
+			object:  map[string]interface{}{"type": "schema_change", "default_action": "deny"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown class",
+			object:  map[string]interface{}{"type": "schema_change", "actions": map[string]interface{}{"index_dropped": "block"}},
+			wantErr: true,
+		},
+		{
+			name:    "actions that are not an object",
+			object:  map[string]interface{}{"type": "schema_change", "actions": "block"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchemaChangePolicy(tt.object)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifySchemaChange(t *testing.T) {
+	tests := []struct {
+		change SchemaChange
+		want   string
+	}{
+		{SchemaChange{ChangeType: "removed", ObjectType: "table", ObjectName: "orders"}, ChangeTableDropped},
+		{SchemaChange{ChangeType: "removed", ObjectType: "column", ObjectName: "total", ParentName: "orders"}, ChangeColumnDropped},
+		{SchemaChange{ChangeType: "renamed", ObjectType: "column", ObjectName: "amount", ParentName: "orders"}, ChangeColumnRenamed},
+		{SchemaChange{ChangeType: "added", ObjectType: "column", ObjectName: "note", ParentName: "orders"}, ""},
+		{SchemaChange{ChangeType: "added", ObjectType: "column", ObjectName: "region", ParentName: "orders", Breaking: true}, ChangeRequiredColumnAdded},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "nullable", OldValue: "true", NewValue: "false"}, ChangeNotNullAdded},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "nullable", OldValue: "false", NewValue: "true"}, ""},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "varchar(255)", NewValue: "varchar(100)"}, ChangeTypeNarrowed},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "varchar(100)", NewValue: "text"}, ""},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "text", NewValue: "character varying(100)"}, ChangeTypeNarrowed},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "bigint", NewValue: "integer"}, ChangeTypeNarrowed},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "int", NewValue: "BIGINT"}, ""},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "double precision", NewValue: "real"}, ChangeTypeNarrowed},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "numeric(10,2)", NewValue: "numeric(12,2)"}, ""},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "numeric(10,2)", NewValue: "numeric(10,4)"}, ChangeTypeNarrowed},
+		{SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "data_type", OldValue: "integer", NewValue: "varchar(20)"}, ChangeTypeChanged},
+		{SchemaChange{ChangeType: "modified", ObjectType: "index", Field: "unique", Breaking: true}, ChangeOtherBreaking},
+		{SchemaChange{ChangeType: "added", ObjectType: "index", ObjectName: "orders_total_idx"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifySchemaChange(tt.change); got != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.change, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateSchemaChanges(t *testing.T) {
+	dropped := SchemaChange{ChangeType: "removed", ObjectType: "column", ObjectName: "total", ParentName: "orders"}
+	notNull := SchemaChange{ChangeType: "modified", ObjectType: "column", Field: "nullable", OldValue: "true", NewValue: "false"}
+	added := SchemaChange{ChangeType: "added", ObjectType: "column", ObjectName: "note", ParentName: "orders"}
+	changes := []SchemaChange{dropped, notNull, added}
+
+	// Without policies breaking changes are warned about
+	findings := EvaluateSchemaChanges(nil, "production", changes)
+	if len(findings) != 2 || findings[0].Action != ActionWarn || findings[1].Action != ActionWarn || Blocked(findings) {
+		t.Fatalf("without policies: got %+v, want two warnings", findings)
+	}
+
+	policies := []*SchemaChangePolicy{
+		{PolicyName: "lenient", DefaultAction: ActionAllow, Actions: map[string]string{}},
+		{PolicyName: "production-schemas", Workspace: "production", DefaultAction: ActionWarn, Actions: map[string]string{ChangeColumnDropped: ActionBlock}},
+	}
+
+	// The most severe action of the policies of the workspace applies
+	findings = EvaluateSchemaChanges(policies, "production", changes)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(findings))
+	}
+	if findings[0].Class != ChangeColumnDropped || findings[0].Action != ActionBlock || findings[0].PolicyName != "production-schemas" {
+		t.Errorf("dropped column: got %+v, want blocked by production-schemas", findings[0])
+	}
+	if findings[1].Class != ChangeNotNullAdded || findings[1].Action != ActionWarn {
+		t.Errorf("NOT NULL: got %+v, want a warning", findings[1])
+	}
+	if !Blocked(findings) {
+		t.Error("the findings should be blocked")
+	}
+
+	// The policy of another workspace does not apply
+	findings = EvaluateSchemaChanges(policies, "staging", changes)
+	if Blocked(findings) || findings[0].Action != ActionAllow || findings[0].PolicyName != "lenient" {
+		t.Errorf("staging: got %+v, want allowed by lenient", findings)
+	}
+}
@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TypeTransformation is the type of policies that require a transformation on the columns of a
+// classification when their data leaves a workspace. The policy object of such a policy is e.g.
+//
+//	{
+//	  "type": "transformation",
+//	  "classification": "email",
+//	  "transformation": "mask_email",
+//	  "workspace": "production"
+//	}
+//
+// Without a workspace the policy applies to data leaving any workspace.
+const TypeTransformation = "transformation"
+
+// directMapping is the transformation of rules that copy the value unchanged
+const directMapping = "direct_mapping"
+
+// chainSeparator separates the steps of the transformation of a rule with a transformation chain
+const chainSeparator = "|"
+
+// TransformationRequirement is what a transformation policy requires of the mapping rules it applies to
+type TransformationRequirement struct {
+	PolicyID       string
+	PolicyName     string
+	Classification string                 // Privileged data classification of the source columns
+	Transformation string                 // Transformation the data must pass through
+	Options        map[string]interface{} // Options of the transformation, nil to keep those of the rule
+	Workspace      string                 // Name of the workspace the data leaves, empty for any workspace
+}
+
+// ParseTransformationRequirement reads the requirement of a policy object. It returns nil when the
+// policy is of another type.
+func ParseTransformationRequirement(object map[string]interface{}) (*TransformationRequirement, error) {
+	if policyType, _ := object["type"].(string); policyType != TypeTransformation {
+		return nil, nil
+	}
+
+	requirement := &TransformationRequirement{}
+	requirement.Classification, _ = object["classification"].(string)
+	requirement.Transformation, _ = object["transformation"].(string)
+	requirement.Workspace, _ = object["workspace"].(string)
+	if requirement.Classification == "" || requirement.Transformation == "" {
+		return nil, errors.New("a transformation policy requires a classification and a transformation")
+	}
+	if requirement.Transformation == directMapping {
+		return nil, fmt.Errorf("a transformation policy cannot require %s", directMapping)
+	}
+	if options, ok := object["transformation_options"]; ok {
+		if requirement.Options, ok = options.(map[string]interface{}); !ok {
+			return nil, errors.New("transformation_options of a transformation policy must be an object")
+		}
+	}
+	return requirement, nil
+}
+
+// Matches reports whether the requirement applies to a column of the classification
+func (r *TransformationRequirement) Matches(classification string) bool {
+	return strings.EqualFold(r.Classification, classification)
+}
+
+// Enforce returns the transformation and options of a mapping rule under the requirement. A rule
+// without a transformation, or with direct_mapping, gets the required one; a rule with a chain of
+// transformations is accepted when one of its steps is the required one; a rule with another
+// transformation is rejected.
+func (r *TransformationRequirement) Enforce(transformation string, options map[string]interface{}) (string, map[string]interface{}, error) {
+	switch transformation {
+	case r.Transformation:
+		if r.Options != nil {
+			options = r.Options
+		}
+		return transformation, options, nil
+	case "", directMapping:
+		if r.Options != nil {
+			options = r.Options
+		}
+		return r.Transformation, options, nil
+	}
+	for _, step := range strings.Split(transformation, chainSeparator) {
+		if step == r.Transformation {
+			return transformation, options, nil
+		}
+	}
+	return "", nil, fmt.Errorf("policy %s requires columns classified as %s to pass through %s, not %s",
+		r.PolicyName, r.Classification, r.Transformation, transformation)
+}
+
+// TransformationRequirements returns the requirements of the transformation policies of a tenant
+func (s *Service) TransformationRequirements(ctx context.Context, tenantID string) ([]*TransformationRequirement, error) {
+	policies, err := s.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var requirements []*TransformationRequirement
+	for _, p := range policies {
+		requirement, err := ParseTransformationRequirement(p.Object)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		if requirement == nil {
+			continue
+		}
+		requirement.PolicyID = p.ID
+		requirement.PolicyName = p.Name
+		requirements = append(requirements, requirement)
+	}
+	return requirements, nil
+}
@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTransformationRequirement(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  map[string]interface{}
+		want    *TransformationRequirement
+		wantErr bool
+	}{
+		{
+			name:   "other policy type",
+			object: map[string]interface{}{"type": "access"},
+		},
+		{
+			name: "transformation policy",
+			object: map[string]interface{}{
+				"type":           "transformation",
+				"classification": "email",
+				"transformation": "mask_email",
+				"workspace":      "production",
+			},
+			want: &TransformationRequirement{Classification: "email", Transformation: "mask_email", Workspace: "production"},
+		},
+		{
+			name:    "without transformation",
+			object:  map[string]interface{}{"type": "transformation", "classification": "email"},
+			wantErr: true,
+		},
+		{
+			name:    "requiring direct mapping",
+			object:  map[string]interface{}{"type": "transformation", "classification": "email", "transformation": "direct_mapping"},
+			wantErr: true,
+		},
+		{
+			name: "options that are not an object",
+			object: map[string]interface{}{
+				"type":                   "transformation",
+				"classification":         "email",
+				"transformation":         "mask_email",
+				"transformation_options": "keep_domain",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTransformationRequirement(tt.object)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformationRequirementEnforce(t *testing.T) {
+	requirement := &TransformationRequirement{
+		PolicyName:     "mask-emails",
+		Classification: "email",
+		Transformation: "mask_email",
+		Options:        map[string]interface{}{"keep_domain": true},
+	}
+
+	for _, transformation := range []string{"", "direct_mapping", "mask_email"} {
+		got, options, err := requirement.Enforce(transformation, map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("%q: %v", transformation, err)
+		}
+		if got != "mask_email" || !reflect.DeepEqual(options, requirement.Options) {
+			t.Errorf("%q: got %s with %v, want mask_email with the options of the policy", transformation, got, options)
+		}
+	}
+
+	if _, _, err := requirement.Enforce("uppercase", nil); err == nil {
+		t.Error("a rule with another transformation was accepted")
+	}
+	if got, _, err := requirement.Enforce("trim|mask_email", nil); err != nil || got != "trim|mask_email" {
+		t.Errorf("a chain through mask_email: got %q, %v, want the chain kept", got, err)
+	}
+	if _, _, err := requirement.Enforce("trim|lowercase", nil); err == nil {
+		t.Error("a chain without the required transformation was accepted")
+	}
+	if !requirement.Matches("EMAIL") || requirement.Matches("phone") {
+		t.Error("classifications are matched case-insensitively and exactly")
+	}
+}
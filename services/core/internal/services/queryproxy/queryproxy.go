@@ -0,0 +1,280 @@
+package queryproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/encryption"
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// Wire protocols of query proxies
+const (
+	ProtocolPostgres = "postgres"
+	ProtocolMySQL    = "mysql"
+)
+
+// Databases a table route pins the statements on a table to
+const (
+	RouteSource = "source"
+	RouteTarget = "target"
+)
+
+// QueryProxy routes the statements of applications between the source and target database of a
+// relationship. The anchor of its node runs it.
+type QueryProxy struct {
+	ID                 string
+	TenantID           string
+	WorkspaceID        string
+	Name               string
+	Description        string
+	RelationshipID     string
+	RelationshipName   string
+	SourceDatabaseName string
+	TargetDatabaseName string
+	Protocol           string
+	NodeID             string
+	ListenPort         int32
+	Username           string
+	ReadPercent        int32
+	WritePercent       int32
+	TableRoutes        map[string]string
+	Status             string
+	StatusMessage      string
+	OwnerID            string
+	Created            time.Time
+	Updated            time.Time
+}
+
+// Service handles query proxies
+type Service struct {
+	db     *database.PostgreSQL
+	logger *logger.Logger
+}
+
+// NewService creates a new query proxy service
+func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
+	return &Service{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ProtocolOf returns the wire protocol of a database type, or "" if proxies do not support it
+func ProtocolOf(databaseType string) string {
+	switch strings.ToLower(databaseType) {
+	case "postgres", "postgresql", "timescaledb":
+		return ProtocolPostgres
+	case "mysql", "mariadb", "tidb":
+		return ProtocolMySQL
+	}
+	return ""
+}
+
+// ValidateRoutes checks the percentages of a proxy and the databases its table routes pin tables to
+func ValidateRoutes(readPercent, writePercent int32, tableRoutes map[string]string) error {
+	if readPercent < 0 || readPercent > 100 {
+		return fmt.Errorf("read percent %d is not between 0 and 100", readPercent)
+	}
+	if writePercent < 0 || writePercent > 100 {
+		return fmt.Errorf("write percent %d is not between 0 and 100", writePercent)
+	}
+	for table, db := range tableRoutes {
+		if table == "" {
+			return errors.New("table routes need a table name")
+		}
+		if db != RouteSource && db != RouteTarget {
+			return fmt.Errorf("table %s is routed to %q; expected %s or %s", table, db, RouteSource, RouteTarget)
+		}
+	}
+	return nil
+}
+
+const queryProxyColumns = `
+	p.proxy_id, p.tenant_id, p.workspace_id, p.proxy_name, COALESCE(p.proxy_description, ''),
+	p.relationship_id, r.relationship_name, sd.database_name, td.database_name, p.proxy_protocol, p.node_id::text,
+	p.listen_port, p.proxy_username, p.read_percent, p.write_percent, p.table_routes,
+	p.status, COALESCE(p.status_message, ''), p.owner_id, p.created, p.updated`
+
+const queryProxyFrom = `
+	FROM query_proxies p
+	JOIN relationships r ON r.relationship_id = p.relationship_id
+	JOIN databases sd ON sd.database_id = r.relationship_source_database_id
+	JOIN databases td ON td.database_id = r.relationship_target_database_id`
+
+func scanQueryProxy(row pgx.Row) (*QueryProxy, error) {
+	var p QueryProxy
+	var tableRoutes []byte
+	err := row.Scan(
+		&p.ID, &p.TenantID, &p.WorkspaceID, &p.Name, &p.Description,
+		&p.RelationshipID, &p.RelationshipName, &p.SourceDatabaseName, &p.TargetDatabaseName, &p.Protocol, &p.NodeID,
+		&p.ListenPort, &p.Username, &p.ReadPercent, &p.WritePercent, &tableRoutes,
+		&p.Status, &p.StatusMessage, &p.OwnerID, &p.Created, &p.Updated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tableRoutes, &p.TableRoutes); err != nil {
+		return nil, fmt.Errorf("invalid table routes of query proxy %s: %w", p.Name, err)
+	}
+	return &p, nil
+}
+
+// Create adds a stopped proxy between the databases of a relationship. Both databases must speak
+// the same wire protocol and be connected by the same node, whose anchor runs the proxy.
+func (s *Service) Create(ctx context.Context, p *QueryProxy, password string) (*QueryProxy, error) {
+	if p.ListenPort <= 0 || p.ListenPort > 65535 {
+		return nil, fmt.Errorf("listen port %d is not a valid port", p.ListenPort)
+	}
+	if p.Username == "" || password == "" {
+		return nil, errors.New("query proxies need the username and password applications connect with")
+	}
+	if err := ValidateRoutes(p.ReadPercent, p.WritePercent, p.TableRoutes); err != nil {
+		return nil, err
+	}
+	if p.TableRoutes == nil {
+		p.TableRoutes = map[string]string{}
+	}
+
+	var sourceName, sourceType, sourceNode, targetName, targetType, targetNode string
+	err := s.db.Pool().QueryRow(ctx, `
+		SELECT sd.database_name, sd.database_type, sd.connected_to_node_id::text,
+		       td.database_name, td.database_type, td.connected_to_node_id::text
+		FROM relationships r
+		JOIN databases sd ON sd.database_id = r.relationship_source_database_id
+		JOIN databases td ON td.database_id = r.relationship_target_database_id
+		WHERE r.workspace_id = $1 AND r.relationship_id = $2
+	`, p.WorkspaceID, p.RelationshipID).Scan(&sourceName, &sourceType, &sourceNode, &targetName, &targetType, &targetNode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the databases of the relationship: %w", err)
+	}
+
+	protocol := ProtocolOf(sourceType)
+	if protocol == "" {
+		return nil, fmt.Errorf("query proxies do not support %s databases", sourceType)
+	}
+	if ProtocolOf(targetType) != protocol {
+		return nil, fmt.Errorf("source database %s (%s) and target database %s (%s) do not speak the same wire protocol", sourceName, sourceType, targetName, targetType)
+	}
+	if sourceNode != targetNode {
+		return nil, fmt.Errorf("source database %s and target database %s are connected by different nodes", sourceName, targetName)
+	}
+
+	encryptedPassword, err := encryption.EncryptPassword(p.TenantID, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt password: %w", err)
+	}
+	tableRoutes, err := json.Marshal(p.TableRoutes)
+	if err != nil {
+		return nil, err
+	}
+
+	var id string
+	err = s.db.Pool().QueryRow(ctx, `
+		INSERT INTO query_proxies (tenant_id, workspace_id, proxy_name, proxy_description, relationship_id,
+		                           proxy_protocol, node_id, listen_port, proxy_username, proxy_password,
+		                           read_percent, write_percent, table_routes, owner_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::bigint, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING proxy_id
+	`, p.TenantID, p.WorkspaceID, p.Name, p.Description, p.RelationshipID, protocol, sourceNode,
+		p.ListenPort, p.Username, encryptedPassword, p.ReadPercent, p.WritePercent, tableRoutes, p.OwnerID,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query proxy: %w", err)
+	}
+
+	return scanQueryProxy(s.db.Pool().QueryRow(ctx, "SELECT "+queryProxyColumns+queryProxyFrom+" WHERE p.proxy_id = $1", id))
+}
+
+// GetByName returns a query proxy of a workspace
+func (s *Service) GetByName(ctx context.Context, tenantID, workspaceID, name string) (*QueryProxy, error) {
+	p, err := scanQueryProxy(s.db.Pool().QueryRow(ctx, "SELECT "+queryProxyColumns+queryProxyFrom+`
+		WHERE p.tenant_id = $1 AND p.workspace_id = $2 AND p.proxy_name = $3
+	`, tenantID, workspaceID, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("query proxy not found")
+	}
+	return p, err
+}
+
+// List returns the query proxies of a workspace
+func (s *Service) List(ctx context.Context, tenantID, workspaceID string) ([]*QueryProxy, error) {
+	rows, err := s.db.Pool().Query(ctx, "SELECT "+queryProxyColumns+queryProxyFrom+`
+		WHERE p.tenant_id = $1 AND p.workspace_id = $2
+		ORDER BY p.proxy_name
+	`, tenantID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proxies []*QueryProxy
+	for rows.Next() {
+		p, err := scanQueryProxy(rows)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, rows.Err()
+}
+
+// SaveRoutes stores the percentages and table routes of a query proxy
+func (s *Service) SaveRoutes(ctx context.Context, p *QueryProxy) error {
+	if err := ValidateRoutes(p.ReadPercent, p.WritePercent, p.TableRoutes); err != nil {
+		return err
+	}
+	if p.TableRoutes == nil {
+		p.TableRoutes = map[string]string{}
+	}
+	tableRoutes, err := json.Marshal(p.TableRoutes)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		UPDATE query_proxies SET read_percent = $2, write_percent = $3, table_routes = $4, updated = CURRENT_TIMESTAMP
+		WHERE proxy_id = $1
+	`, p.ID, p.ReadPercent, p.WritePercent, tableRoutes)
+	if err != nil {
+		return fmt.Errorf("failed to save the routes of query proxy %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// SetStatus stores the status of a query proxy. The anchor restarts the proxies of its node
+// whose status is STATUS_STARTED.
+func (s *Service) SetStatus(ctx context.Context, p *QueryProxy, status, message string) error {
+	if len(message) > 255 {
+		message = message[:255]
+	}
+	_, err := s.db.Pool().Exec(ctx, `
+		UPDATE query_proxies SET status = $2, status_message = $3, updated = CURRENT_TIMESTAMP
+		WHERE proxy_id = $1
+	`, p.ID, status, message)
+	if err != nil {
+		return fmt.Errorf("failed to update the status of query proxy %s: %w", p.Name, err)
+	}
+	p.Status = status
+	p.StatusMessage = message
+	return nil
+}
+
+// Delete deletes a query proxy
+func (s *Service) Delete(ctx context.Context, tenantID, workspaceID, name string) error {
+	result, err := s.db.Pool().Exec(ctx, `
+		DELETE FROM query_proxies WHERE tenant_id = $1 AND workspace_id = $2 AND proxy_name = $3
+	`, tenantID, workspaceID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete query proxy: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("query proxy not found")
+	}
+	return nil
+}
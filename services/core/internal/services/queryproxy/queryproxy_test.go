@@ -0,0 +1,32 @@
+package queryproxy
+
+import "testing"
+
+func TestProtocolOf(t *testing.T) {
+	for databaseType, want := range map[string]string{
+		"postgres":    ProtocolPostgres,
+		"TimescaleDB": ProtocolPostgres,
+		"mariadb":     ProtocolMySQL,
+		"tidb":        ProtocolMySQL,
+		"mongodb":     "",
+	} {
+		if got := ProtocolOf(databaseType); got != want {
+			t.Errorf("ProtocolOf(%q) = %q, want %q", databaseType, got, want)
+		}
+	}
+}
+
+func TestValidateRoutes(t *testing.T) {
+	if err := ValidateRoutes(25, 0, map[string]string{"orders": RouteTarget, "audit.events": RouteSource}); err != nil {
+		t.Errorf("valid routes rejected: %v", err)
+	}
+	if err := ValidateRoutes(101, 0, nil); err == nil {
+		t.Error("a read percent above 100 should be rejected")
+	}
+	if err := ValidateRoutes(0, -5, nil); err == nil {
+		t.Error("a negative write percent should be rejected")
+	}
+	if err := ValidateRoutes(0, 0, map[string]string{"orders": "replica"}); err == nil {
+		t.Error("a route to an unknown database should be rejected")
+	}
+}
@@ -525,3 +525,30 @@ func (s *Service) DeleteByName(ctx context.Context, tenantID, workspaceID, name
 	// Use the existing Delete method with the relationship ID
 	return s.Delete(ctx, tenantID, workspaceID, relationship.ID)
 }
+
+// GetSourceSchemaVersion returns the schema snapshot version of the source database of a
+// relationship when it was last deployed, or 0 if it was never deployed
+func (s *Service) GetSourceSchemaVersion(ctx context.Context, id string) (int32, error) {
+	var version *int32
+	err := s.db.Pool().QueryRow(ctx, "SELECT relationship_source_schema_version FROM relationships WHERE relationship_id = $1", id).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("relationship not found")
+		}
+		return 0, fmt.Errorf("failed to get source schema version: %w", err)
+	}
+	if version == nil {
+		return 0, nil
+	}
+	return *version, nil
+}
+
+// SetSourceSchemaVersion records the schema snapshot version of the source database of a
+// relationship as it is deployed
+func (s *Service) SetSourceSchemaVersion(ctx context.Context, id string, version int32) error {
+	_, err := s.db.Pool().Exec(ctx, "UPDATE relationships SET relationship_source_schema_version = $2 WHERE relationship_id = $1", id, version)
+	if err != nil {
+		return fmt.Errorf("failed to set source schema version: %w", err)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
 )
 
 // Service handles repo-related operations
@@ -488,6 +489,15 @@ func (s *Service) GetCommitsForBranch(ctx context.Context, tenantID, workspaceID
 		return nil, err
 	}
 
+	artifacts := artifact.NewService(s.db, s.logger)
+	for _, commit := range commits {
+		commit.SchemaStructure, err = artifacts.ResolveSchema(ctx, commit.TenantID, commit.WorkspaceID, commit.SchemaStructure)
+		if err != nil {
+			s.logger.Errorf("Failed to resolve schema of commit %d: %v", commit.ID, err)
+			return nil, err
+		}
+	}
+
 	return commits, nil
 }
 
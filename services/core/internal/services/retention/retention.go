@@ -0,0 +1,376 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/services/core/internal/services/artifact"
+)
+
+// Resource types retention policies apply to
+const (
+	ResourceRunHistory    = "run_history"    // Integration runs with their logs, per integration
+	ResourceRunLog        = "run_log"        // Log lines of integration runs, per integration
+	ResourceDeadLetter    = "dead_letter"    // Dead-letter artifacts
+	ResourceSchemaVersion = "schema_version" // Commits, per branch; head and branch point commits are always kept
+)
+
+// ResourceTypes lists the resource types retention policies apply to
+var ResourceTypes = []string{ResourceRunHistory, ResourceRunLog, ResourceDeadLetter, ResourceSchemaVersion}
+
+// purgeBatchSize is the most items of a resource type purged per tenant and run; the rest are
+// purged by the following runs
+const purgeBatchSize = 10000
+
+// Policy is the retention of a resource type. An item is purged once it is neither among the
+// KeepVersions newest items of its group nor younger than KeepDays; a zero limit does not keep
+// anything, and a policy without limits keeps everything.
+type Policy struct {
+	TenantID     string
+	ResourceType string
+	KeepDays     int
+	KeepVersions int
+	IsDefault    bool // The tenant has no policy of its own; the configured default applies
+	Updated      time.Time
+}
+
+// Active reports whether the policy purges anything
+func (p *Policy) Active() bool {
+	return p.KeepDays > 0 || p.KeepVersions > 0
+}
+
+// Purge describes the items of a resource type a policy purges
+type Purge struct {
+	ResourceType string
+	Items        int64
+	Oldest       *time.Time // Creation time of the oldest item purged
+}
+
+var (
+	mu       sync.RWMutex
+	defaults = map[string]Policy{}
+)
+
+// Configure sets the policies of tenants without a policy of their own for a resource type
+func Configure(defaultPolicies map[string]Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaults = defaultPolicies
+}
+
+func defaultPolicy(tenantID, resourceType string) *Policy {
+	mu.RLock()
+	policy := defaults[resourceType]
+	mu.RUnlock()
+
+	policy.TenantID = tenantID
+	policy.ResourceType = resourceType
+	policy.IsDefault = true
+	return &policy
+}
+
+// ValidResourceType reports whether resourceType is a known resource type
+func ValidResourceType(resourceType string) bool {
+	for _, t := range ResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Service handles retention policies and the purging of expired items
+type Service struct {
+	db     *database.PostgreSQL
+	logger *logger.Logger
+}
+
+// NewService creates a new retention service
+func NewService(db *database.PostgreSQL, logger *logger.Logger) *Service {
+	return &Service{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// List returns the policies of a tenant for all resource types, including the defaults that
+// apply to the resource types the tenant has no policy for
+func (s *Service) List(ctx context.Context, tenantID string) ([]*Policy, error) {
+	policies := make([]*Policy, 0, len(ResourceTypes))
+	for _, resourceType := range ResourceTypes {
+		policy, err := s.Get(ctx, tenantID, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// Get returns the policy of a tenant for a resource type, or the default if it has none
+func (s *Service) Get(ctx context.Context, tenantID, resourceType string) (*Policy, error) {
+	var policy Policy
+	err := s.db.Pool().QueryRow(ctx, `
+		SELECT tenant_id, resource_type, keep_days, keep_versions, updated
+		FROM retention_policies
+		WHERE tenant_id = $1 AND resource_type = $2
+	`, tenantID, resourceType).Scan(&policy.TenantID, &policy.ResourceType, &policy.KeepDays, &policy.KeepVersions, &policy.Updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return defaultPolicy(tenantID, resourceType), nil
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to get retention policy: %v", err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Set sets the policy of a tenant for a resource type
+func (s *Service) Set(ctx context.Context, tenantID, resourceType string, keepDays, keepVersions int) (*Policy, error) {
+	if !ValidResourceType(resourceType) {
+		return nil, fmt.Errorf("unknown resource type %q", resourceType)
+	}
+	if keepDays < 0 || keepVersions < 0 {
+		return nil, errors.New("keep_days and keep_versions cannot be negative")
+	}
+
+	var policy Policy
+	err := s.db.Pool().QueryRow(ctx, `
+		INSERT INTO retention_policies (tenant_id, resource_type, keep_days, keep_versions)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, resource_type)
+		DO UPDATE SET keep_days = EXCLUDED.keep_days, keep_versions = EXCLUDED.keep_versions, updated = CURRENT_TIMESTAMP
+		RETURNING tenant_id, resource_type, keep_days, keep_versions, updated
+	`, tenantID, resourceType, keepDays, keepVersions).Scan(&policy.TenantID, &policy.ResourceType, &policy.KeepDays, &policy.KeepVersions, &policy.Updated)
+	if err != nil {
+		s.logger.Errorf("Failed to set retention policy: %v", err)
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Delete removes the policy of a tenant for a resource type, so that the default applies again
+func (s *Service) Delete(ctx context.Context, tenantID, resourceType string) error {
+	_, err := s.db.Pool().Exec(ctx, `
+		DELETE FROM retention_policies WHERE tenant_id = $1 AND resource_type = $2
+	`, tenantID, resourceType)
+	if err != nil {
+		s.logger.Errorf("Failed to delete retention policy: %v", err)
+	}
+	return err
+}
+
+// Preview returns the items of a tenant the current policies purge, per resource type
+func (s *Service) Preview(ctx context.Context, tenantID string) ([]*Purge, error) {
+	policies, err := s.List(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	purges := make([]*Purge, 0, len(policies))
+	for _, policy := range policies {
+		purge := &Purge{ResourceType: policy.ResourceType}
+		if policy.Active() {
+			query := fmt.Sprintf("SELECT COUNT(*), MIN(created) FROM (%s) expired", expiredQuery(policy.ResourceType))
+			err := s.db.Pool().QueryRow(ctx, query, tenantID, policy.KeepVersions, policy.KeepDays).Scan(&purge.Items, &purge.Oldest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to preview %s retention: %w", policy.ResourceType, err)
+			}
+		}
+		purges = append(purges, purge)
+	}
+	return purges, nil
+}
+
+// Apply purges the expired items of all tenants and returns the number of items purged per
+// resource type
+func (s *Service) Apply(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.db.Pool().Query(ctx, "SELECT tenant_id FROM tenants")
+	if err != nil {
+		return nil, err
+	}
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	purged := make(map[string]int64)
+	for _, tenantID := range tenantIDs {
+		policies, err := s.List(ctx, tenantID)
+		if err != nil {
+			return purged, err
+		}
+		for _, policy := range policies {
+			if !policy.Active() {
+				continue
+			}
+			n, err := s.purge(ctx, policy)
+			purged[policy.ResourceType] += n
+			if err != nil {
+				s.logger.Warnf("Failed to apply %s retention of tenant %s: %v", policy.ResourceType, tenantID, err)
+			}
+		}
+	}
+	return purged, nil
+}
+
+// purge deletes the oldest expired items of a policy, at most purgeBatchSize
+func (s *Service) purge(ctx context.Context, policy *Policy) (int64, error) {
+	expired := fmt.Sprintf("SELECT id FROM (%s) expired ORDER BY created LIMIT %d", expiredQuery(policy.ResourceType), purgeBatchSize)
+	args := []interface{}{policy.TenantID, policy.KeepVersions, policy.KeepDays}
+
+	switch policy.ResourceType {
+	case ResourceRunHistory:
+		// The logs of the deleted runs are deleted with them
+		tag, err := s.db.Pool().Exec(ctx, "DELETE FROM integration_runs WHERE run_id IN ("+expired+")", args...)
+		if err != nil {
+			return 0, err
+		}
+		return tag.RowsAffected(), nil
+
+	case ResourceRunLog:
+		tag, err := s.db.Pool().Exec(ctx, "DELETE FROM integration_run_logs WHERE log_id IN ("+expired+")", args...)
+		if err != nil {
+			return 0, err
+		}
+		return tag.RowsAffected(), nil
+
+	case ResourceDeadLetter:
+		// Artifacts are deleted one by one, with their blobs
+		rows, err := s.db.Pool().Query(ctx, "SELECT artifact_id, workspace_id FROM artifacts WHERE artifact_id IN ("+expired+")", args...)
+		if err != nil {
+			return 0, err
+		}
+		return s.deleteArtifacts(ctx, policy.TenantID, rows)
+
+	case ResourceSchemaVersion:
+		// Offloaded schema snapshots of the deleted commits are deleted with them
+		rows, err := s.db.Pool().Query(ctx, `
+			DELETE FROM commits WHERE commit_id IN (`+expired+`)
+			RETURNING schema_structure->>'$artifact', workspace_id
+		`, args...)
+		if err != nil {
+			return 0, err
+		}
+		var deleted int64
+		var snapshots [][2]string
+		for rows.Next() {
+			var artifactID *string
+			var workspaceID string
+			if err := rows.Scan(&artifactID, &workspaceID); err != nil {
+				rows.Close()
+				return deleted, err
+			}
+			deleted++
+			if artifactID != nil && *artifactID != "" {
+				snapshots = append(snapshots, [2]string{*artifactID, workspaceID})
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return deleted, err
+		}
+
+		artifactService := artifact.NewService(s.db, s.logger)
+		for _, snapshot := range snapshots {
+			if err := artifactService.Delete(ctx, policy.TenantID, snapshot[1], snapshot[0]); err != nil {
+				s.logger.Warnf("Failed to delete schema snapshot %s of a purged commit: %v", snapshot[0], err)
+			}
+		}
+		return deleted, nil
+	}
+	return 0, fmt.Errorf("unknown resource type %q", policy.ResourceType)
+}
+
+// deleteArtifacts deletes the artifacts of a tenant listed by rows of artifact and workspace IDs
+func (s *Service) deleteArtifacts(ctx context.Context, tenantID string, rows pgx.Rows) (int64, error) {
+	var ids [][2]string
+	for rows.Next() {
+		var artifactID, workspaceID string
+		if err := rows.Scan(&artifactID, &workspaceID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, [2]string{artifactID, workspaceID})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	artifactService := artifact.NewService(s.db, s.logger)
+	var deleted int64
+	for _, id := range ids {
+		if err := artifactService.Delete(ctx, tenantID, id[1], id[0]); err != nil {
+			s.logger.Warnf("Failed to delete expired artifact %s: %v", id[0], err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// expiredQuery returns the query of the expired items of a resource type as (id, created)
+// rows. Its parameters are the tenant ID, the number of versions and the number of days kept.
+func expiredQuery(resourceType string) string {
+	var ranked string
+	switch resourceType {
+	case ResourceRunHistory:
+		ranked = `
+			SELECT run_id AS id, created, ROW_NUMBER() OVER (PARTITION BY integration_id ORDER BY created DESC, run_id DESC) AS version
+			FROM integration_runs
+			WHERE tenant_id = $1`
+	case ResourceRunLog:
+		ranked = `
+			SELECT log_id AS id, created, ROW_NUMBER() OVER (PARTITION BY integration_id ORDER BY created DESC, log_id DESC) AS version
+			FROM integration_run_logs
+			WHERE tenant_id = $1`
+	case ResourceDeadLetter:
+		ranked = `
+			SELECT artifact_id AS id, created, ROW_NUMBER() OVER (ORDER BY created DESC) AS version
+			FROM artifacts
+			WHERE tenant_id = $1 AND artifact_kind = '` + artifact.KindDeadLetter + `'`
+	case ResourceSchemaVersion:
+		// Head commits count as versions but are never purged, and neither are the commits other
+		// branches were created from: the newest commit of the parent branch at their creation
+		ranked = `
+			SELECT id, created, version FROM (
+				SELECT commit_id AS id, created, commit_is_head, ROW_NUMBER() OVER (PARTITION BY branch_id ORDER BY created DESC, commit_id DESC) AS version
+				FROM commits
+				WHERE tenant_id = $1
+			) branch_commits
+			WHERE NOT commit_is_head
+				AND id NOT IN (
+					SELECT branch_point.commit_id
+					FROM branches child
+					CROSS JOIN LATERAL (
+						SELECT c.commit_id FROM commits c
+						WHERE c.branch_id = child.parent_branch_id AND c.created <= child.created
+						ORDER BY c.created DESC, c.commit_id DESC
+						LIMIT 1
+					) branch_point
+					WHERE child.tenant_id = $1 AND child.parent_branch_id IS NOT NULL
+				)`
+	}
+
+	return fmt.Sprintf(`
+		SELECT id, created FROM (%s
+		) ranked
+		WHERE ($2::int = 0 OR version > $2::int)
+			AND ($3::int = 0 OR created < CURRENT_TIMESTAMP - make_interval(days => $3::int))`, ranked)
+}
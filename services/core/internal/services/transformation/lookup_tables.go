@@ -0,0 +1,383 @@
+package transformation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MaxLookupEntries is the most entries a lookup table holds, as the transformation service keeps
+// them in memory
+const MaxLookupEntries = 100000
+
+// DefaultLookupCacheTTL is how long the transformation service caches the entries of a lookup
+// table, in seconds, unless given
+const DefaultLookupCacheTTL = 300
+
+// lookupTableNamePattern matches the names of lookup tables, which transformation options refer to
+var lookupTableNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LookupTableSource is the database table the entries of a lookup table are loaded from: the
+// values of the key column map to those of the value column
+type LookupTableSource struct {
+	WorkspaceName string `json:"workspace_name"`
+	DatabaseName  string `json:"database_name"`
+	DatabaseID    string `json:"database_id"`
+	TableName     string `json:"table_name"`
+	KeyColumn     string `json:"key_column"`
+	ValueColumn   string `json:"value_column"`
+}
+
+// LookupTable is the reference data the lookup transformation maps values through. Its name is
+// unique across tenants, as transformation requests carry no tenant.
+type LookupTable struct {
+	ID              string
+	TenantID        string
+	Name            string
+	Description     string
+	Entries         map[string]string // Nil in lists
+	EntryCount      int
+	Source          *LookupTableSource // Nil for tables whose entries are given by users
+	CacheTTLSeconds int
+	Refreshed       *time.Time
+	OwnerID         string
+	Created         time.Time
+	Updated         time.Time
+}
+
+// LookupTableUpdate holds the changes to a lookup table
+type LookupTableUpdate struct {
+	Description     *string
+	SetEntries      map[string]string
+	RemoveKeys      []string
+	ReplaceEntries  bool // SetEntries replace all the entries
+	CacheTTLSeconds *int
+}
+
+// ValidateLookupTableName checks that a lookup table name is snake_case
+func ValidateLookupTableName(name string) error {
+	if !lookupTableNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid lookup table name '%s': expected lowercase letters, digits and underscores, starting with a letter", name)
+	}
+	return nil
+}
+
+// LookupEntriesFromRows builds the entries of a lookup table from the rows of its source table.
+// Rows with a NULL key are skipped and NULL values map to empty strings. A key repeated with
+// another value is rejected, as lookups through it would be ambiguous.
+func LookupEntriesFromRows(rows []map[string]interface{}, keyColumn, valueColumn string) (map[string]string, error) {
+	if len(rows) > MaxLookupEntries {
+		return nil, fmt.Errorf("the source table has more than %d rows", MaxLookupEntries)
+	}
+
+	entries := make(map[string]string, len(rows))
+	for _, row := range rows {
+		keyValue, ok := row[keyColumn]
+		if !ok {
+			return nil, fmt.Errorf("key column %s not found in the source table", keyColumn)
+		}
+		value, ok := row[valueColumn]
+		if !ok {
+			return nil, fmt.Errorf("value column %s not found in the source table", valueColumn)
+		}
+		if keyValue == nil {
+			continue
+		}
+
+		key := lookupValueString(keyValue)
+		mapped := lookupValueString(value)
+		if existing, found := entries[key]; found && existing != mapped {
+			return nil, fmt.Errorf("key '%s' maps to both '%s' and '%s'", key, existing, mapped)
+		}
+		entries[key] = mapped
+	}
+	return entries, nil
+}
+
+// lookupValueString converts a value fetched from a database to the string the lookup
+// transformation compares, writing numbers without exponent
+func lookupValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// CreateLookupTable creates a lookup table
+func (s *Service) CreateLookupTable(ctx context.Context, table *LookupTable) (*LookupTable, error) {
+	s.logger.Infof("Creating lookup table %s for tenant: %s", table.Name, table.TenantID)
+
+	if err := ValidateLookupTableName(table.Name); err != nil {
+		return nil, err
+	}
+	if len(table.Entries) > MaxLookupEntries {
+		return nil, fmt.Errorf("a lookup table holds at most %d entries", MaxLookupEntries)
+	}
+	if table.CacheTTLSeconds < 0 {
+		return nil, errors.New("cache TTL cannot be negative")
+	}
+
+	var exists bool
+	err := s.db.Pool().QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM transformation_lookup_tables WHERE lookup_table_name = $1)", table.Name).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check lookup table existence: %w", err)
+	}
+	if exists {
+		return nil, errors.New("lookup table with this name already exists")
+	}
+
+	entriesJSON, sourceJSON, err := marshalLookupTable(table.Entries, table.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO transformation_lookup_tables (tenant_id, lookup_table_name, lookup_table_description,
+		                                          lookup_entries, lookup_source, cache_ttl_seconds, owner_id, refreshed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING lookup_table_id
+	`
+	var id string
+	err = s.db.Pool().QueryRow(ctx, query, table.TenantID, table.Name, table.Description, entriesJSON, sourceJSON,
+		table.CacheTTLSeconds, table.OwnerID, table.Refreshed).Scan(&id)
+	if err != nil {
+		s.logger.Errorf("Failed to create lookup table: %v", err)
+		return nil, err
+	}
+
+	return s.GetLookupTable(ctx, table.TenantID, table.Name)
+}
+
+// GetLookupTable retrieves a lookup table of a tenant by its name, with its entries
+func (s *Service) GetLookupTable(ctx context.Context, tenantID, name string) (*LookupTable, error) {
+	query := `
+		SELECT lookup_table_id, tenant_id, lookup_table_name, lookup_table_description, lookup_entries,
+		       lookup_source, cache_ttl_seconds, refreshed, owner_id, created, updated
+		FROM transformation_lookup_tables
+		WHERE tenant_id = $1 AND lookup_table_name = $2
+	`
+
+	var table LookupTable
+	var entriesJSON, sourceJSON []byte
+	err := s.db.Pool().QueryRow(ctx, query, tenantID, name).Scan(
+		&table.ID,
+		&table.TenantID,
+		&table.Name,
+		&table.Description,
+		&entriesJSON,
+		&sourceJSON,
+		&table.CacheTTLSeconds,
+		&table.Refreshed,
+		&table.OwnerID,
+		&table.Created,
+		&table.Updated,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("lookup table not found")
+		}
+		s.logger.Errorf("Failed to get lookup table: %v", err)
+		return nil, err
+	}
+
+	if err := json.Unmarshal(entriesJSON, &table.Entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lookup table entries: %w", err)
+	}
+	table.EntryCount = len(table.Entries)
+	if table.Source, err = unmarshalLookupSource(sourceJSON); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// ListLookupTables retrieves the lookup tables of a tenant, without their entries
+func (s *Service) ListLookupTables(ctx context.Context, tenantID string) ([]*LookupTable, error) {
+	query := `
+		SELECT lookup_table_id, tenant_id, lookup_table_name, lookup_table_description,
+		       (SELECT COUNT(*) FROM jsonb_object_keys(lookup_entries)), lookup_source,
+		       cache_ttl_seconds, refreshed, owner_id, created, updated
+		FROM transformation_lookup_tables
+		WHERE tenant_id = $1
+		ORDER BY lookup_table_name
+	`
+
+	rows, err := s.db.Pool().Query(ctx, query, tenantID)
+	if err != nil {
+		s.logger.Errorf("Failed to list lookup tables: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*LookupTable
+	for rows.Next() {
+		var table LookupTable
+		var sourceJSON []byte
+		err := rows.Scan(
+			&table.ID,
+			&table.TenantID,
+			&table.Name,
+			&table.Description,
+			&table.EntryCount,
+			&sourceJSON,
+			&table.CacheTTLSeconds,
+			&table.Refreshed,
+			&table.OwnerID,
+			&table.Created,
+			&table.Updated,
+		)
+		if err != nil {
+			s.logger.Errorf("Failed to scan lookup table: %v", err)
+			return nil, err
+		}
+		if table.Source, err = unmarshalLookupSource(sourceJSON); err != nil {
+			return nil, err
+		}
+		tables = append(tables, &table)
+	}
+
+	if err = rows.Err(); err != nil {
+		s.logger.Errorf("Error after scanning lookup tables: %v", err)
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// UpdateLookupTable changes the description, entries or cache TTL of a lookup table
+func (s *Service) UpdateLookupTable(ctx context.Context, tenantID, name string, update *LookupTableUpdate) (*LookupTable, error) {
+	s.logger.Infof("Updating lookup table %s", name)
+
+	table, err := s.GetLookupTable(ctx, tenantID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Description != nil {
+		table.Description = *update.Description
+	}
+	if update.CacheTTLSeconds != nil {
+		if *update.CacheTTLSeconds < 0 {
+			return nil, errors.New("cache TTL cannot be negative")
+		}
+		table.CacheTTLSeconds = *update.CacheTTLSeconds
+	}
+	table.Entries = ApplyLookupEntryChanges(table.Entries, update)
+	if len(table.Entries) > MaxLookupEntries {
+		return nil, fmt.Errorf("a lookup table holds at most %d entries", MaxLookupEntries)
+	}
+
+	entriesJSON, _, err := marshalLookupTable(table.Entries, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.db.Pool().Exec(ctx, `
+		UPDATE transformation_lookup_tables
+		SET lookup_table_description = $3, lookup_entries = $4, cache_ttl_seconds = $5, updated = CURRENT_TIMESTAMP
+		WHERE tenant_id = $1 AND lookup_table_name = $2
+	`, tenantID, name, table.Description, entriesJSON, table.CacheTTLSeconds)
+	if err != nil {
+		s.logger.Errorf("Failed to update lookup table: %v", err)
+		return nil, err
+	}
+
+	return s.GetLookupTable(ctx, tenantID, name)
+}
+
+// ApplyLookupEntryChanges returns the entries of a lookup table with the entry changes of an
+// update: all entries replaced, or entries set and then keys removed
+func ApplyLookupEntryChanges(entries map[string]string, update *LookupTableUpdate) map[string]string {
+	changed := make(map[string]string, len(entries)+len(update.SetEntries))
+	if !update.ReplaceEntries {
+		for key, value := range entries {
+			changed[key] = value
+		}
+	}
+	for key, value := range update.SetEntries {
+		changed[key] = value
+	}
+	for _, key := range update.RemoveKeys {
+		delete(changed, key)
+	}
+	return changed
+}
+
+// SetLookupTableEntries replaces the entries of a lookup table loaded from its source
+func (s *Service) SetLookupTableEntries(ctx context.Context, tenantID, name string, entries map[string]string, refreshed time.Time) (*LookupTable, error) {
+	entriesJSON, _, err := marshalLookupTable(entries, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.db.Pool().Exec(ctx, `
+		UPDATE transformation_lookup_tables
+		SET lookup_entries = $3, refreshed = $4, updated = CURRENT_TIMESTAMP
+		WHERE tenant_id = $1 AND lookup_table_name = $2
+	`, tenantID, name, entriesJSON, refreshed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set lookup table entries: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, errors.New("lookup table not found")
+	}
+	return s.GetLookupTable(ctx, tenantID, name)
+}
+
+// DeleteLookupTable deletes a lookup table
+func (s *Service) DeleteLookupTable(ctx context.Context, tenantID, name string) error {
+	s.logger.Infof("Deleting lookup table %s", name)
+
+	result, err := s.db.Pool().Exec(ctx, "DELETE FROM transformation_lookup_tables WHERE tenant_id = $1 AND lookup_table_name = $2",
+		tenantID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete lookup table: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("lookup table not found")
+	}
+
+	return nil
+}
+
+// marshalLookupTable marshals the entries and the source of a lookup table for their JSONB
+// columns; a nil source is stored as NULL
+func marshalLookupTable(entries map[string]string, source *LookupTableSource) ([]byte, []byte, error) {
+	if entries == nil {
+		entries = map[string]string{}
+	}
+	entriesJSON, err := json.Marshal(entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal lookup table entries: %w", err)
+	}
+	if source == nil {
+		return entriesJSON, nil, nil
+	}
+	sourceJSON, err := json.Marshal(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal lookup table source: %w", err)
+	}
+	return entriesJSON, sourceJSON, nil
+}
+
+// unmarshalLookupSource unmarshals the source of a lookup table, nil when it has none
+func unmarshalLookupSource(sourceJSON []byte) (*LookupTableSource, error) {
+	if len(sourceJSON) == 0 {
+		return nil, nil
+	}
+	var source LookupTableSource
+	if err := json.Unmarshal(sourceJSON, &source); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lookup table source: %w", err)
+	}
+	return &source, nil
+}
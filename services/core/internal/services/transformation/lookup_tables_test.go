@@ -0,0 +1,63 @@
+package transformation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookupEntriesFromRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"legacy_code": "UK", "iso_code": "GB"},
+		{"legacy_code": "UK", "iso_code": "GB"},
+		{"legacy_code": float64(840), "iso_code": "US"},
+		{"legacy_code": nil, "iso_code": "ZZ"},
+		{"legacy_code": "XX", "iso_code": nil},
+	}
+	entries, err := LookupEntriesFromRows(rows, "legacy_code", "iso_code")
+	if err != nil {
+		t.Fatalf("LookupEntriesFromRows: %v", err)
+	}
+	want := map[string]string{"UK": "GB", "840": "US", "XX": ""}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries = %v, want %v", entries, want)
+	}
+
+	conflicting := append(rows, map[string]interface{}{"legacy_code": "UK", "iso_code": "UA"})
+	if _, err := LookupEntriesFromRows(conflicting, "legacy_code", "iso_code"); err == nil {
+		t.Fatal("expected a key with two values to be rejected")
+	}
+	if _, err := LookupEntriesFromRows(rows, "legacy_code", "alpha2"); err == nil {
+		t.Fatal("expected a missing value column to be rejected")
+	}
+}
+
+func TestApplyLookupEntryChanges(t *testing.T) {
+	entries := map[string]string{"UK": "GB", "EL": "GR"}
+
+	changed := ApplyLookupEntryChanges(entries, &LookupTableUpdate{
+		SetEntries: map[string]string{"UK": "GB", "YU": "RS"},
+		RemoveKeys: []string{"EL"},
+	})
+	if want := map[string]string{"UK": "GB", "YU": "RS"}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("changed = %v, want %v", changed, want)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("the original entries were modified: %v", entries)
+	}
+
+	replaced := ApplyLookupEntryChanges(entries, &LookupTableUpdate{SetEntries: map[string]string{"SU": "RU"}, ReplaceEntries: true})
+	if want := map[string]string{"SU": "RU"}; !reflect.DeepEqual(replaced, want) {
+		t.Fatalf("replaced = %v, want %v", replaced, want)
+	}
+}
+
+func TestValidateLookupTableName(t *testing.T) {
+	if err := ValidateLookupTableName("country_codes"); err != nil {
+		t.Fatalf("ValidateLookupTableName: %v", err)
+	}
+	for _, name := range []string{"", "Country", "1codes", "country-codes", "country codes"} {
+		if err := ValidateLookupTableName(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Health checks combined into the health score of a workspace
+const (
+	CheckConnectivity     = "connectivity"      // Enabled databases and instances that are not connected
+	CheckCDCLag           = "cdc_lag"           // Replication sources behind on pending events
+	CheckDrift            = "drift"             // Connected branches whose head commit differs from the database schema
+	CheckFailingRuns      = "failing_runs"      // Relationships in a failed state
+	CheckPolicyViolations = "policy_violations" // Resources attached to policies that no longer exist
+)
+
+// Check statuses
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Workspace health statuses
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// checkWeights are the weights of the checks in the health score, in the order they are reported
+var checkWeights = []struct {
+	name   string
+	weight int
+}{
+	{CheckConnectivity, 35},
+	{CheckCDCLag, 20},
+	{CheckFailingRuns, 20},
+	{CheckDrift, 15},
+	{CheckPolicyViolations, 10},
+}
+
+// Replication sources with pending events are lagging once their last sync is older than
+// cdcLagWarn, and failing once it is older than cdcLagFail
+const (
+	cdcLagWarn = 5 * time.Minute
+	cdcLagFail = time.Hour
+)
+
+// healthCacheTTL is how long a computed health is served before it is computed again, so that
+// status pages polling the endpoint do not query every table on each request
+const healthCacheTTL = 30 * time.Second
+
+// HealthCheck is one factor of the health score of a workspace
+type HealthCheck struct {
+	Name    string
+	Status  string
+	Score   int // 0 to 100
+	Weight  int
+	Message string
+	Items   []string // The objects lowering the score
+}
+
+// Health is the scored health of a workspace. It is ready when no check fails.
+type Health struct {
+	WorkspaceName string
+	Score         int // 0 to 100, the weighted average of the check scores
+	Status        string
+	Ready         bool
+	Checks        []*HealthCheck
+	Computed      time.Time
+}
+
+// finding is an object of a check that is not healthy
+type finding struct {
+	item   string
+	failed bool // Failed rather than degraded
+}
+
+// checkInput is what a check found: the number of objects it looked at and those not healthy
+type checkInput struct {
+	total    int
+	findings []finding
+}
+
+func (c *checkInput) add(item string, failed bool) {
+	c.findings = append(c.findings, finding{item: item, failed: failed})
+}
+
+type cachedHealth struct {
+	health  *Health
+	expires time.Time
+}
+
+var (
+	healthMu    sync.Mutex
+	healthCache = map[string]cachedHealth{}
+)
+
+// Health returns the health of a workspace, computed at most every healthCacheTTL
+func (s *Service) Health(ctx context.Context, tenantID, name string) (*Health, error) {
+	workspaceID, err := s.GetWorkspaceID(ctx, tenantID, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New("workspace not found")
+		}
+		return nil, err
+	}
+
+	healthMu.Lock()
+	cached, ok := healthCache[workspaceID]
+	healthMu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.health, nil
+	}
+
+	inputs := make(map[string]*checkInput, len(checkWeights))
+	collectors := map[string]func(context.Context, string, *checkInput) error{
+		CheckConnectivity:     s.collectConnectivity,
+		CheckCDCLag:           s.collectCDCLag,
+		CheckFailingRuns:      s.collectFailingRuns,
+		CheckDrift:            s.collectDrift,
+		CheckPolicyViolations: s.collectPolicyViolations,
+	}
+	for check, collect := range collectors {
+		input := &checkInput{}
+		if err := collect(ctx, workspaceID, input); err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", check, err)
+		}
+		inputs[check] = input
+	}
+
+	health := scoreHealth(name, inputs)
+	health.Computed = time.Now().UTC()
+
+	cacheHealth(workspaceID, health, time.Now())
+	return health, nil
+}
+
+// cacheHealth caches the health of a workspace, evicting the expired healths so that the cache
+// only holds the workspaces checked within healthCacheTTL
+func cacheHealth(workspaceID string, health *Health, now time.Time) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	for id, entry := range healthCache {
+		if !now.Before(entry.expires) {
+			delete(healthCache, id)
+		}
+	}
+	healthCache[workspaceID] = cachedHealth{health: health, expires: now.Add(healthCacheTTL)}
+}
+
+// scoreHealth turns the findings of the checks into scored checks and the workspace score.
+// A check scores the share of its objects that are healthy, counting degraded ones half.
+func scoreHealth(name string, inputs map[string]*checkInput) *Health {
+	health := &Health{WorkspaceName: name, Ready: true}
+
+	allPass := true
+	var weighted, weights int
+	for _, cw := range checkWeights {
+		input := inputs[cw.name]
+		if input == nil {
+			input = &checkInput{}
+		}
+		check := &HealthCheck{Name: cw.name, Status: CheckPass, Score: 100, Weight: cw.weight}
+
+		var failed, degraded int
+		for _, f := range input.findings {
+			check.Items = append(check.Items, f.item)
+			if f.failed {
+				failed++
+			} else {
+				degraded++
+			}
+		}
+		if input.total > 0 {
+			healthy := float64(input.total) - float64(failed) - float64(degraded)/2
+			check.Score = int(100 * healthy / float64(input.total))
+			if check.Score < 0 {
+				check.Score = 0
+			}
+		}
+
+		switch {
+		case failed > 0:
+			check.Status = CheckFail
+			health.Ready = false
+		case degraded > 0:
+			check.Status = CheckWarn
+		}
+		allPass = allPass && check.Status == CheckPass
+		check.Message = fmt.Sprintf("%d of %d healthy", input.total-failed-degraded, input.total)
+		if input.total == 0 {
+			check.Message = "nothing to check"
+		}
+
+		weighted += check.Score * cw.weight
+		weights += cw.weight
+		health.Checks = append(health.Checks, check)
+	}
+
+	health.Score = weighted / weights
+	switch {
+	case health.Score < 60:
+		health.Status = HealthUnhealthy
+	case health.Score < 90 || !allPass:
+		health.Status = HealthDegraded
+	default:
+		health.Status = HealthHealthy
+	}
+	return health
+}
+
+// collectConnectivity finds enabled databases and instances that are not connected
+func (s *Service) collectConnectivity(ctx context.Context, workspaceID string, input *checkInput) error {
+	query := `
+		SELECT 'database ' || database_name, COALESCE(status::text, '') FROM databases WHERE workspace_id = $1 AND database_enabled
+		UNION ALL
+		SELECT 'instance ' || instance_name, COALESCE(status::text, '') FROM instances WHERE workspace_id = $1 AND instance_enabled
+	`
+	return s.scanHealthRows(ctx, query, workspaceID, func(rows pgx.Rows) error {
+		var item, status string
+		if err := rows.Scan(&item, &status); err != nil {
+			return err
+		}
+		input.total++
+		switch status {
+		case "STATUS_DISCONNECTED", "STATUS_ERROR", "STATUS_FAILURE", "STATUS_UNHEALTHY":
+			input.add(fmt.Sprintf("%s: %s", item, status), true)
+		case "STATUS_DEGRADED", "STATUS_RECONNECTING", "STATUS_WARNING":
+			input.add(fmt.Sprintf("%s: %s", item, status), false)
+		}
+		return nil
+	})
+}
+
+// collectCDCLag finds replication sources that are failing or behind on pending events
+func (s *Service) collectCDCLag(ctx context.Context, workspaceID string, input *checkInput) error {
+	query := `
+		SELECT d.database_name || '.' || r.table_name, COALESCE(r.status::text, ''), COALESCE(r.events_pending, 0),
+			COALESCE(EXTRACT(EPOCH FROM LOCALTIMESTAMP - COALESCE(r.last_sync_timestamp, r.created))::bigint, 0)
+		FROM replication_sources r
+		JOIN databases d ON d.database_id = r.database_id
+		WHERE r.workspace_id = $1 AND r.status NOT IN ('STATUS_STOPPED', 'STATUS_CANCELLED', 'STATUS_DELETED')
+	`
+	return s.scanHealthRows(ctx, query, workspaceID, func(rows pgx.Rows) error {
+		var item, status string
+		var pending, lagSeconds int64
+		if err := rows.Scan(&item, &status, &pending, &lagSeconds); err != nil {
+			return err
+		}
+		input.total++
+		lag := time.Duration(lagSeconds) * time.Second
+		switch {
+		case status == "STATUS_ERROR" || status == "STATUS_FAILURE":
+			input.add(fmt.Sprintf("%s: %s", item, status), true)
+		case pending > 0 && lag > cdcLagFail:
+			input.add(fmt.Sprintf("%s: %d events pending, last synced %s ago", item, pending, lag), true)
+		case pending > 0 && lag > cdcLagWarn:
+			input.add(fmt.Sprintf("%s: %d events pending, last synced %s ago", item, pending, lag), false)
+		}
+		return nil
+	})
+}
+
+// collectFailingRuns finds relationships that failed or are degraded
+func (s *Service) collectFailingRuns(ctx context.Context, workspaceID string, input *checkInput) error {
+	query := `
+		SELECT relationship_name, COALESCE(status::text, ''), COALESCE(status_message, '')
+		FROM relationships
+		WHERE workspace_id = $1
+	`
+	return s.scanHealthRows(ctx, query, workspaceID, func(rows pgx.Rows) error {
+		var name, status, message string
+		if err := rows.Scan(&name, &status, &message); err != nil {
+			return err
+		}
+		input.total++
+		item := fmt.Sprintf("relationship %s: %s", name, status)
+		if message != "" {
+			item += " (" + message + ")"
+		}
+		switch status {
+		case "STATUS_ERROR", "STATUS_FAILURE", "STATUS_UNHEALTHY":
+			input.add(item, true)
+		case "STATUS_DEGRADED", "STATUS_WARNING":
+			input.add(item, false)
+		}
+		return nil
+	})
+}
+
+// collectDrift finds branches connected to a database whose schema no longer matches the head
+// commit. Head commits with an offloaded schema and databases not yet discovered are skipped.
+func (s *Service) collectDrift(ctx context.Context, workspaceID string, input *checkInput) error {
+	query := `
+		SELECT r.repo_name || '/' || b.branch_name, d.database_name, c.schema_structure = d.database_schema
+		FROM branches b
+		JOIN repos r ON r.repo_id = b.repo_id
+		JOIN databases d ON d.database_id = b.connected_database_id
+		JOIN commits c ON c.branch_id = b.branch_id AND c.commit_is_head
+		WHERE b.workspace_id = $1 AND b.connected_to_database
+			AND NOT c.schema_structure ? '$artifact' AND d.database_schema <> '{}'::jsonb
+	`
+	return s.scanHealthRows(ctx, query, workspaceID, func(rows pgx.Rows) error {
+		var branch, databaseName string
+		var inSync bool
+		if err := rows.Scan(&branch, &databaseName, &inSync); err != nil {
+			return err
+		}
+		input.total++
+		if !inSync {
+			input.add(fmt.Sprintf("branch %s: database %s differs from the head commit", branch, databaseName), false)
+		}
+		return nil
+	})
+}
+
+// collectPolicyViolations finds policy references of the workspace and its resources to
+// policies that no longer exist, which leaves those resources unprotected
+func (s *Service) collectPolicyViolations(ctx context.Context, workspaceID string, input *checkInput) error {
+	query := `
+		SELECT refs.item, refs.policy_id, EXISTS (SELECT 1 FROM policies p WHERE p.policy_id = refs.policy_id)
+		FROM (
+			SELECT 'workspace ' || workspace_name AS item, unnest(policy_ids) AS policy_id FROM workspaces WHERE workspace_id = $1
+			UNION ALL
+			SELECT 'instance ' || instance_name, unnest(policy_ids) FROM instances WHERE workspace_id = $1
+			UNION ALL
+			SELECT 'database ' || database_name, unnest(policy_ids) FROM databases WHERE workspace_id = $1
+			UNION ALL
+			SELECT 'repo ' || repo_name, unnest(policy_ids) FROM repos WHERE workspace_id = $1
+			UNION ALL
+			SELECT 'relationship ' || relationship_name, unnest(policy_ids) FROM relationships WHERE workspace_id = $1
+		) refs
+	`
+	return s.scanHealthRows(ctx, query, workspaceID, func(rows pgx.Rows) error {
+		var item, policyID string
+		var exists bool
+		if err := rows.Scan(&item, &policyID, &exists); err != nil {
+			return err
+		}
+		input.total++
+		if !exists {
+			input.add(fmt.Sprintf("%s: policy %s does not exist", item, policyID), false)
+		}
+		return nil
+	})
+}
+
+func (s *Service) scanHealthRows(ctx context.Context, query, workspaceID string, scan func(pgx.Rows) error) error {
+	rows, err := s.db.Pool().Query(ctx, query, workspaceID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreHealth(t *testing.T) {
+	t.Run("nothing to check is healthy", func(t *testing.T) {
+		health := scoreHealth("ws", map[string]*checkInput{})
+		if health.Score != 100 || health.Status != HealthHealthy || !health.Ready {
+			t.Fatalf("got score %d, status %s, ready %v", health.Score, health.Status, health.Ready)
+		}
+		if len(health.Checks) != len(checkWeights) {
+			t.Fatalf("got %d checks, want %d", len(health.Checks), len(checkWeights))
+		}
+	})
+
+	t.Run("degraded objects count half and keep the workspace ready", func(t *testing.T) {
+		drift := &checkInput{total: 4}
+		drift.add("branch main", false)
+		health := scoreHealth("ws", map[string]*checkInput{CheckDrift: drift})
+
+		check := health.Checks[3]
+		if check.Name != CheckDrift || check.Status != CheckWarn || check.Score != 87 {
+			t.Fatalf("drift check = %+v", check)
+		}
+		if !health.Ready || health.Status != HealthDegraded {
+			t.Fatalf("got status %s, ready %v, want degraded and ready", health.Status, health.Ready)
+		}
+	})
+
+	t.Run("failed objects fail the check and readiness", func(t *testing.T) {
+		connectivity := &checkInput{total: 2}
+		connectivity.add("database a", true)
+		connectivity.add("database b", true)
+		health := scoreHealth("ws", map[string]*checkInput{CheckConnectivity: connectivity})
+
+		if health.Checks[0].Status != CheckFail || health.Checks[0].Score != 0 {
+			t.Fatalf("connectivity check = %+v", health.Checks[0])
+		}
+		// Connectivity weighs 35 of 100
+		if health.Ready || health.Score != 65 || health.Status != HealthDegraded {
+			t.Fatalf("got score %d, status %s, ready %v", health.Score, health.Status, health.Ready)
+		}
+	})
+}
+
+func TestCacheHealthEvictsExpired(t *testing.T) {
+	now := time.Now()
+	cacheHealth("ws_1", &Health{WorkspaceName: "one"}, now)
+	cacheHealth("ws_2", &Health{WorkspaceName: "two"}, now.Add(healthCacheTTL))
+
+	healthMu.Lock()
+	_, kept := healthCache["ws_2"]
+	_, evicted := healthCache["ws_1"]
+	healthMu.Unlock()
+	if !kept || evicted {
+		t.Fatalf("cache holds ws_1: %t, ws_2: %t; want only ws_2", evicted, kept)
+	}
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"fmt"
+
+	pb "github.com/redbco/redb-open/api/proto/integration/v1"
+	"github.com/redbco/redb-open/pkg/keyring"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// CredentialKeyringService is the keyring service that holds integration secrets. The key of
+// an integration's secret is its credential_key.
+const CredentialKeyringService = "redb-integration"
+
+// secretConfigKeys lists, per integration type, the config keys whose values are secrets. They
+// are moved to the keyring when the integration is saved and never stored in its config.
+var secretConfigKeys = map[pb.IntegrationType]string{
+	pb.IntegrationType_INTEGRATION_TYPE_LDAP: "bind_password",
+}
+
+// newCredentialKeyring opens the keyring holding integration secrets
+func newCredentialKeyring() *keyring.KeyringManager {
+	return keyring.NewKeyringManager(keyring.GetDefaultKeyringPath(), keyring.GetMasterPasswordFromEnv())
+}
+
+// storeCredential moves the secret of an integration's config to the keyring under its
+// credential key, assigning one if it has none. Without a secret in the config, a secret
+// stored before is kept.
+func (e *Engine) storeCredential(integ *pb.Integration) error {
+	key, ok := secretConfigKeys[integ.Type]
+	if !ok || integ.Config == nil {
+		return nil
+	}
+	value, present := integ.Config.Fields[key]
+	if !present {
+		return nil
+	}
+
+	cfg := integ.Config.AsMap()
+	delete(cfg, key)
+	stripped, err := structpb.NewStruct(cfg)
+	if err != nil {
+		return err
+	}
+
+	if integ.CredentialKey == "" {
+		integ.CredentialKey = fmt.Sprintf("integration-%s-credential", integ.Id)
+	}
+	if err := e.keyring.Set(CredentialKeyringService, integ.CredentialKey, value.GetStringValue()); err != nil {
+		return fmt.Errorf("failed to store %s in the keyring: %w", key, err)
+	}
+	integ.Config = stripped
+	return nil
+}
+
+// credential returns the secret of an integration from the keyring, or "" if it has none
+func (e *Engine) credential(integ *pb.Integration) (string, error) {
+	if integ.CredentialKey == "" {
+		return "", nil
+	}
+	secret, err := e.keyring.Get(CredentialKeyringService, integ.CredentialKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential %s from the keyring: %w", integ.CredentialKey, err)
+	}
+	return secret, nil
+}
+
+// deleteCredential removes the secret of an integration from the keyring
+func (e *Engine) deleteCredential(integ *pb.Integration) error {
+	if _, ok := secretConfigKeys[integ.Type]; !ok || integ.CredentialKey == "" {
+		return nil
+	}
+	return e.keyring.Delete(CredentialKeyringService, integ.CredentialKey)
+}
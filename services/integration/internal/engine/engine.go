@@ -3,13 +3,19 @@ package engine
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
 	pb "github.com/redbco/redb-open/api/proto/integration/v1"
 	"github.com/redbco/redb-open/pkg/config"
 	"github.com/redbco/redb-open/pkg/database"
+	"github.com/redbco/redb-open/pkg/keyring"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/services/integration/internal/filedrop"
+	"github.com/redbco/redb-open/services/integration/internal/ldapsync"
 	"google.golang.org/grpc"
 )
 
@@ -29,12 +35,22 @@ type Engine struct {
 	}
 	// in-memory store for integrations for now
 	store *MemoryStore
+	// fileDrops runs the watchers of file drop integrations
+	fileDrops *filedrop.Manager
+	// ldapSyncs runs the scheduled reconciliation of LDAP sync integrations
+	ldapSyncs *ldapsync.Manager
+	// keyring holds the secrets of integrations under their credential keys
+	keyring *keyring.KeyringManager
+	// core client used to run file drop mappings, connected on first use
+	coreClientMu sync.Mutex
+	coreClient   corev1.DatabaseServiceClient
 }
 
 func NewEngine(cfg *config.Config) *Engine {
 	return &Engine{
-		config: cfg,
-		store:  NewMemoryStore(),
+		config:  cfg,
+		store:   NewMemoryStore(),
+		keyring: newCredentialKeyring(),
 	}
 }
 
@@ -60,16 +76,41 @@ func (e *Engine) Start(ctx context.Context) error {
 	if e.grpcServer == nil {
 		return fmt.Errorf("gRPC server not set - call SetGRPCServer first")
 	}
+	var ledger filedrop.Ledger = filedrop.NewMemoryLedger(0)
+	if e.db != nil {
+		ledger = filedrop.NewPostgresLedger(e.db.Pool())
+	}
+	e.fileDrops = filedrop.NewManager(e.runFileDropMapping, ledger, e.fileDropStagingRoot(), e.logger)
+	if e.db != nil {
+		e.ldapSyncs = ldapsync.NewManager(ldapsync.NewPostgresStore(e.db.Pool()), e.logger)
+	}
 	e.state.isRunning = true
 	return nil
 }
 
+// fileDropStagingRoot returns the directory that file drops stage files under. It defaults to the
+// upload directory that the anchor's file source adapter reads from.
+func (e *Engine) fileDropStagingRoot() string {
+	if e.config != nil {
+		if dir := e.config.Get("services.integration.filedrop_staging_root"); dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(os.TempDir(), "redb-uploads")
+}
+
 func (e *Engine) Stop(ctx context.Context) error {
 	e.state.Lock()
 	defer e.state.Unlock()
 	if !e.state.isRunning {
 		return nil
 	}
+	if e.fileDrops != nil {
+		e.fileDrops.StopAll()
+	}
+	if e.ldapSyncs != nil {
+		e.ldapSyncs.StopAll()
+	}
 	e.state.isRunning = false
 	return nil
 }
@@ -46,6 +46,11 @@ func (s *IntegrationServer) CreateIntegration(ctx context.Context, req *pb.Creat
 	if req.Integration.Id == "" {
 		req.Integration.Id = fmt.Sprintf("integration_%d", time.Now().UnixNano())
 	}
+	// Secrets go to the keyring, not into the stored config
+	if err := s.engine.storeCredential(req.Integration); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.CreateIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+	}
 	// Persist to DB (best-effort) and cache in memory
 	if _, err := s.engine.insertIntegration(ctx, req.Integration); err != nil {
 		// log via metric; still proceed to in-memory store for now
@@ -59,6 +64,14 @@ func (s *IntegrationServer) CreateIntegration(ctx context.Context, req *pb.Creat
 			StatusMessage: err.Error(),
 		}, nil
 	}
+	if err := s.startFileDrop(integ); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.CreateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_FAILURE, StatusMessage: err.Error()}, nil
+	}
+	if err := s.startLDAPSync(integ); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.CreateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_FAILURE, StatusMessage: err.Error()}, nil
+	}
 	return &pb.CreateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "created"}, nil
 }
 
@@ -78,11 +91,31 @@ func (s *IntegrationServer) UpdateIntegration(ctx context.Context, req *pb.Updat
 	s.engine.TrackOperation()
 	defer s.engine.UntrackOperation()
 	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+	if req.Integration == nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.UpdateIntegrationResponse{Status: commonv1.Status_STATUS_FAILURE, StatusMessage: "integration is required"}, nil
+	}
+	// Keep the stored secret unless the update replaces it
+	if cur, err := s.engine.store.Get(req.Integration.Id); err == nil && req.Integration.CredentialKey == "" {
+		req.Integration.CredentialKey = cur.CredentialKey
+	}
+	if err := s.engine.storeCredential(req.Integration); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.UpdateIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+	}
 	integ, err := s.engine.store.Update(req.Integration)
 	if err != nil {
 		atomic.AddInt64(&s.engine.metrics.errors, 1)
 		return &pb.UpdateIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
 	}
+	if err := s.startFileDrop(integ); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.UpdateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_FAILURE, StatusMessage: err.Error()}, nil
+	}
+	if err := s.startLDAPSync(integ); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.UpdateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_FAILURE, StatusMessage: err.Error()}, nil
+	}
 	return &pb.UpdateIntegrationResponse{Integration: integ, Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "updated"}, nil
 }
 
@@ -90,10 +123,24 @@ func (s *IntegrationServer) DeleteIntegration(ctx context.Context, req *pb.Delet
 	s.engine.TrackOperation()
 	defer s.engine.UntrackOperation()
 	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+	integ, _ := s.engine.store.Get(req.Id)
 	if err := s.engine.store.Delete(req.Id); err != nil {
 		atomic.AddInt64(&s.engine.metrics.errors, 1)
 		return &pb.DeleteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
 	}
+	if s.engine.fileDrops != nil {
+		if err := s.engine.fileDrops.Remove(ctx, req.Id); err != nil && s.engine.logger != nil {
+			s.engine.logger.Warnf("Failed to remove file drop arrivals of %s: %v", req.Id, err)
+		}
+	}
+	if s.engine.ldapSyncs != nil {
+		s.engine.ldapSyncs.Remove(req.Id)
+	}
+	if integ != nil {
+		if err := s.engine.deleteCredential(integ); err != nil && s.engine.logger != nil {
+			s.engine.logger.Warnf("Failed to delete the credential of %s: %v", req.Id, err)
+		}
+	}
 	return &pb.DeleteIntegrationResponse{Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "deleted"}, nil
 }
 
@@ -167,6 +214,22 @@ func (s *IntegrationServer) ExecuteIntegration(ctx context.Context, req *pb.Exec
 		}
 	}
 
+	// Route file drop and LDAP sync operations
+	switch req.Operation {
+	case OperationFileDropPoll, OperationFileDropPush, OperationFileDropHistory:
+		if _, err := s.engine.store.Get(req.Id); err != nil {
+			atomic.AddInt64(&s.engine.metrics.errors, 1)
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: fmt.Sprintf("integration not found: %v", err)}, nil
+		}
+		return s.executeFileDrop(ctx, req)
+	case OperationLDAPSync, OperationLDAPSyncHistory, OperationLDAPLinkUser:
+		if _, err := s.engine.store.Get(req.Id); err != nil {
+			atomic.AddInt64(&s.engine.metrics.errors, 1)
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: fmt.Sprintf("integration not found: %v", err)}, nil
+		}
+		return s.executeLDAPSync(ctx, req)
+	}
+
 	// Default behavior: echo payload and report success.
 	if _, err := s.engine.store.Get(req.Id); err != nil {
 		atomic.AddInt64(&s.engine.metrics.errors, 1)
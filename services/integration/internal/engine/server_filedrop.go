@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	corev1 "github.com/redbco/redb-open/api/proto/core/v1"
+	pb "github.com/redbco/redb-open/api/proto/integration/v1"
+	pkggrpc "github.com/redbco/redb-open/pkg/grpc"
+	"github.com/redbco/redb-open/pkg/grpcconfig"
+	"github.com/redbco/redb-open/services/integration/internal/filedrop"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// File drop operations accepted by ExecuteIntegration
+const (
+	OperationFileDropPoll    = "FILE_DROP_POLL"
+	OperationFileDropPush    = "FILE_DROP_PUSH"
+	OperationFileDropHistory = "FILE_DROP_HISTORY"
+)
+
+// startFileDrop (re)starts the watcher of a file drop integration
+func (s *IntegrationServer) startFileDrop(integ *pb.Integration) error {
+	if integ.Type != pb.IntegrationType_INTEGRATION_TYPE_FILE_DROP {
+		return nil
+	}
+	if s.engine.fileDrops == nil {
+		return fmt.Errorf("integration service is not running")
+	}
+	cfgMap := map[string]any{}
+	if integ.Config != nil {
+		cfgMap = integ.Config.AsMap()
+	}
+	cfg, err := filedrop.ParseConfig(integ.TenantId, cfgMap)
+	if err != nil {
+		return fmt.Errorf("invalid file drop configuration: %w", err)
+	}
+	s.engine.fileDrops.Start(integ.Id, cfg)
+	return nil
+}
+
+// executeFileDrop handles the FILE_DROP_* operations
+func (s *IntegrationServer) executeFileDrop(ctx context.Context, req *pb.ExecuteIntegrationRequest) (*pb.ExecuteIntegrationResponse, error) {
+	if s.engine.fileDrops == nil {
+		return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: "integration service is not running"}, nil
+	}
+
+	var arrivals []*filedrop.Arrival
+	switch req.Operation {
+	case OperationFileDropPoll:
+		polled, err := s.engine.fileDrops.Poll(ctx, req.Id)
+		if err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+		}
+		arrivals = polled
+	case OperationFileDropPush:
+		m := map[string]any{}
+		if req.Payload != nil {
+			m = req.Payload.AsMap()
+		}
+		fileName, _ := m["file_name"].(string)
+		encoded, _ := m["content"].(string)
+		if fileName == "" || encoded == "" {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_FAILURE, StatusMessage: "payload.file_name and payload.content (base64) are required"}, nil
+		}
+		content, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_FAILURE, StatusMessage: fmt.Sprintf("content is not valid base64: %v", err)}, nil
+		}
+		arrival, err := s.engine.fileDrops.Push(ctx, req.Id, fileName, bytes.NewReader(content))
+		if err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+		}
+		arrivals = []*filedrop.Arrival{arrival}
+	case OperationFileDropHistory:
+		limit := 50
+		if req.Payload != nil {
+			if v, ok := req.Payload.AsMap()["limit"].(float64); ok && v > 0 {
+				limit = int(v)
+			}
+		}
+		history, err := s.engine.fileDrops.History(ctx, req.Id, limit)
+		if err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+		}
+		arrivals = history
+	}
+
+	payload, err := arrivalsPayload(arrivals)
+	if err != nil {
+		return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+	}
+	if stagingPath, err := s.engine.fileDrops.StagingPath(req.Id); err == nil {
+		payload.Fields["staging_path"] = structpb.NewStringValue(stagingPath)
+	}
+	return &pb.ExecuteIntegrationResponse{Payload: payload, Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "ok"}, nil
+}
+
+// arrivalsPayload converts arrivals into a response payload via their JSON form
+func arrivalsPayload(arrivals []*filedrop.Arrival) (*structpb.Struct, error) {
+	raw, err := json.Marshal(arrivals)
+	if err != nil {
+		return nil, err
+	}
+	var list []any
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(map[string]any{"arrivals": list})
+}
+
+// runFileDropMapping runs the configured mapping after a dropped file has been staged
+func (e *Engine) runFileDropMapping(ctx context.Context, cfg *filedrop.Config, arrival *filedrop.Arrival) (*filedrop.RunResult, error) {
+	client, err := e.getCoreClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options, _ := json.Marshal(map[string]any{
+		"source_file": arrival.FileName,
+		"sha256":      arrival.SHA256,
+	})
+	resp, err := client.TransformData(ctx, &corev1.TransformDataRequest{
+		TenantId:      cfg.TenantID,
+		WorkspaceName: cfg.WorkspaceName,
+		MappingName:   cfg.MappingName,
+		Mode:          cfg.Mode,
+		Options:       options,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mapping run failed: %w", err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("mapping run failed: %s", resp.Message)
+	}
+	return &filedrop.RunResult{RowsProcessed: resp.RowsProcessed}, nil
+}
+
+// getCoreClient lazily connects to the core service
+func (e *Engine) getCoreClient(ctx context.Context) (corev1.DatabaseServiceClient, error) {
+	e.coreClientMu.Lock()
+	defer e.coreClientMu.Unlock()
+	if e.coreClient != nil {
+		return e.coreClient, nil
+	}
+
+	addr := e.config.Get("services.core.grpc_address")
+	if addr == "" {
+		addr = grpcconfig.GetServiceAddress(e.config, "core")
+	}
+	conn, err := pkggrpc.NewClient(ctx, addr, pkggrpc.DefaultClientOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to core service: %w", err)
+	}
+	e.coreClient = corev1.NewDatabaseServiceClient(conn)
+	return e.coreClient, nil
+}
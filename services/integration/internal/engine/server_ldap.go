@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	pb "github.com/redbco/redb-open/api/proto/integration/v1"
+	"github.com/redbco/redb-open/services/integration/internal/ldapsync"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// LDAP sync operations accepted by ExecuteIntegration
+const (
+	OperationLDAPSync        = "LDAP_SYNC"
+	OperationLDAPSyncHistory = "LDAP_SYNC_HISTORY"
+	// OperationLDAPLinkUser links an existing tenant user to a directory entry; the sync never
+	// links existing users on its own
+	OperationLDAPLinkUser = "LDAP_LINK_USER"
+)
+
+// startLDAPSync (re)schedules the reconciliation of an LDAP sync integration
+func (s *IntegrationServer) startLDAPSync(integ *pb.Integration) error {
+	if integ.Type != pb.IntegrationType_INTEGRATION_TYPE_LDAP {
+		return nil
+	}
+	if s.engine.ldapSyncs == nil {
+		return fmt.Errorf("ldap sync requires the integration service database")
+	}
+	cfgMap := map[string]any{}
+	if integ.Config != nil {
+		cfgMap = integ.Config.AsMap()
+	}
+	password, err := s.engine.credential(integ)
+	if err != nil {
+		return err
+	}
+	if password != "" {
+		cfgMap["bind_password"] = password
+	}
+	cfg, err := ldapsync.ParseConfig(integ.TenantId, cfgMap)
+	if err != nil {
+		return fmt.Errorf("invalid ldap sync configuration: %w", err)
+	}
+	s.engine.ldapSyncs.Start(integ.Id, cfg)
+	return nil
+}
+
+// executeLDAPSync handles the LDAP_SYNC* operations
+func (s *IntegrationServer) executeLDAPSync(ctx context.Context, req *pb.ExecuteIntegrationRequest) (*pb.ExecuteIntegrationResponse, error) {
+	if s.engine.ldapSyncs == nil {
+		return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: "ldap sync requires the integration service database"}, nil
+	}
+	m := map[string]any{}
+	if req.Payload != nil {
+		m = req.Payload.AsMap()
+	}
+
+	var runs []*ldapsync.Run
+	switch req.Operation {
+	case OperationLDAPSync:
+		dryRun, _ := m["dry_run"].(bool)
+		run, err := s.engine.ldapSyncs.Sync(ctx, req.Id, dryRun)
+		if err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+		}
+		runs = []*ldapsync.Run{run}
+	case OperationLDAPLinkUser:
+		userID, _ := m["user_id"].(string)
+		directoryID, _ := m["directory_id"].(string)
+		if err := s.engine.ldapSyncs.LinkUser(ctx, req.Id, userID, directoryID); err != nil {
+			return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+		}
+		return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "linked"}, nil
+	case OperationLDAPSyncHistory:
+		limit := 20
+		if v, ok := m["limit"].(float64); ok && v > 0 {
+			limit = int(v)
+		}
+		runs = s.engine.ldapSyncs.History(req.Id, limit)
+	}
+
+	payload, err := runsPayload(runs)
+	if err != nil {
+		return &pb.ExecuteIntegrationResponse{Status: commonv1.Status_STATUS_ERROR, StatusMessage: err.Error()}, nil
+	}
+	if req.Operation == OperationLDAPSync && runs[0].Status == ldapsync.RunFailed {
+		return &pb.ExecuteIntegrationResponse{Payload: payload, Status: commonv1.Status_STATUS_FAILURE, StatusMessage: runs[0].Error}, nil
+	}
+	return &pb.ExecuteIntegrationResponse{Payload: payload, Status: commonv1.Status_STATUS_SUCCESS, StatusMessage: "ok"}, nil
+}
+
+// runsPayload converts sync runs into a response payload via their JSON form
+func runsPayload(runs []*ldapsync.Run) (*structpb.Struct, error) {
+	raw, err := json.Marshal(runs)
+	if err != nil {
+		return nil, err
+	}
+	var list []any
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(map[string]any{"runs": list})
+}
@@ -0,0 +1,223 @@
+package filedrop
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Protocol identifies the remote transfer protocol of a file drop
+type Protocol string
+
+const (
+	ProtocolSFTP Protocol = "sftp"
+	ProtocolFTPS Protocol = "ftps"
+	// ProtocolPush accepts files pushed through ExecuteIntegration instead of polling a server
+	ProtocolPush Protocol = "push"
+)
+
+// Config describes a file drop integration. It is decoded from the integration's config struct.
+type Config struct {
+	Protocol   Protocol
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	PrivateKey string
+	// HostKey is the expected SSH public key in authorized_keys format, required for SFTP
+	HostKey string
+	// InsecureSkipHostKeyCheck accepts any SSH host key when HostKey is empty
+	InsecureSkipHostKeyCheck bool
+	// InsecureSkipVerify disables TLS certificate verification for FTPS
+	InsecureSkipVerify bool
+
+	// Directory is the remote directory that is watched for new files
+	Directory string
+	// Pattern is a shell glob matched against file names (e.g. "orders_*.csv")
+	Pattern string
+	// ArchiveDir receives files that were processed successfully (or skipped as duplicates)
+	ArchiveDir string
+	// QuarantineDir receives files whose mapping run failed
+	QuarantineDir string
+	PollInterval  time.Duration
+	// MinFileAge is how long a file's size must stay unchanged before it is picked up
+	MinFileAge time.Duration
+
+	// StagingDir is the directory, relative to the service's staging root, that holds the staged file
+	StagingDir string
+	// FileFormat is the extension of the staged file ("csv", "tsv" or "xlsx")
+	FileFormat    string
+	TenantID      string
+	WorkspaceName string
+	MappingName   string
+	// Mode is passed to the mapping run ("append", "replace", "update")
+	Mode string
+}
+
+// ParseConfig builds a Config from an integration config map
+func ParseConfig(tenantID string, cfg map[string]any) (*Config, error) {
+	c := &Config{
+		Protocol:                 Protocol(strings.ToLower(stringValue(cfg, "protocol"))),
+		Host:                     stringValue(cfg, "host"),
+		Port:                     intValue(cfg, "port"),
+		Username:                 stringValue(cfg, "username"),
+		Password:                 stringValue(cfg, "password"),
+		PrivateKey:               stringValue(cfg, "private_key"),
+		HostKey:                  stringValue(cfg, "host_key"),
+		InsecureSkipHostKeyCheck: boolValue(cfg, "insecure_skip_host_key_check"),
+		InsecureSkipVerify:       boolValue(cfg, "insecure_skip_verify"),
+		Directory:                stringValue(cfg, "directory"),
+		Pattern:                  stringValue(cfg, "pattern"),
+		ArchiveDir:               stringValue(cfg, "archive_dir"),
+		QuarantineDir:            stringValue(cfg, "quarantine_dir"),
+		PollInterval:             durationValue(cfg, "poll_interval", time.Minute),
+		MinFileAge:               durationValue(cfg, "min_file_age", 30*time.Second),
+		StagingDir:               stringValue(cfg, "staging_dir"),
+		FileFormat:               strings.ToLower(strings.TrimPrefix(stringValue(cfg, "file_format"), ".")),
+		TenantID:                 tenantID,
+		WorkspaceName:            stringValue(cfg, "workspace_name"),
+		MappingName:              stringValue(cfg, "mapping_name"),
+		Mode:                     stringValue(cfg, "mode"),
+	}
+
+	if c.Protocol == "" {
+		c.Protocol = ProtocolSFTP
+	}
+	if c.Directory == "" {
+		c.Directory = "."
+	}
+	if c.Pattern == "" {
+		c.Pattern = "*"
+	}
+	if c.ArchiveDir == "" {
+		c.ArchiveDir = path.Join(c.Directory, "archive")
+	}
+	if c.QuarantineDir == "" {
+		c.QuarantineDir = path.Join(c.Directory, "quarantine")
+	}
+	if c.Mode == "" {
+		c.Mode = "append"
+	}
+	if c.StagingDir == "" {
+		c.StagingDir = "filedrop"
+	}
+	if c.FileFormat == "" {
+		c.FileFormat = "csv"
+		if ext := strings.ToLower(strings.TrimPrefix(path.Ext(c.Pattern), ".")); stagingFormats[ext] {
+			c.FileFormat = ext
+		}
+	}
+	if c.Port == 0 {
+		switch c.Protocol {
+		case ProtocolSFTP:
+			c.Port = 22
+		case ProtocolFTPS:
+			c.Port = 21
+		}
+	}
+
+	return c, c.Validate()
+}
+
+// Validate checks that the configuration is complete
+func (c *Config) Validate() error {
+	switch c.Protocol {
+	case ProtocolSFTP, ProtocolFTPS:
+		if c.Host == "" {
+			return fmt.Errorf("host is required for %s file drops", c.Protocol)
+		}
+		if c.Username == "" {
+			return fmt.Errorf("username is required for %s file drops", c.Protocol)
+		}
+		if c.Protocol == ProtocolSFTP && c.Password == "" && c.PrivateKey == "" {
+			return fmt.Errorf("password or private_key is required for sftp file drops")
+		}
+		if c.Protocol == ProtocolSFTP && c.HostKey == "" && !c.InsecureSkipHostKeyCheck {
+			return fmt.Errorf("host_key is required for sftp file drops (or set insecure_skip_host_key_check)")
+		}
+	case ProtocolPush:
+	default:
+		return fmt.Errorf("unsupported file drop protocol: %s", c.Protocol)
+	}
+
+	if _, err := path.Match(c.Pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", c.Pattern, err)
+	}
+	if !filepath.IsLocal(c.StagingDir) {
+		return fmt.Errorf("staging_dir must be a relative path inside the staging root")
+	}
+	if !stagingFormats[c.FileFormat] {
+		return fmt.Errorf("unsupported file_format %q", c.FileFormat)
+	}
+	if c.WorkspaceName == "" || c.MappingName == "" {
+		return fmt.Errorf("workspace_name and mapping_name are required")
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be positive")
+	}
+	return nil
+}
+
+// stagingFormats lists the file extensions the file source adapter can read
+var stagingFormats = map[string]bool{
+	"csv":  true,
+	"tsv":  true,
+	"xlsx": true,
+}
+
+// StagingPath returns the path of the staged file under root. Each arrival replaces this file,
+// and the file source database of the mapping must be connected to it.
+func (c *Config) StagingPath(root, integrationID string) string {
+	return filepath.Join(root, c.StagingDir, "filedrop_"+sanitizeName(integrationID)+"."+c.FileFormat)
+}
+
+// sanitizeName keeps only characters that are safe in a file name
+func sanitizeName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func stringValue(cfg map[string]any, key string) string {
+	if v, ok := cfg[key].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
+
+func intValue(cfg map[string]any, key string) int {
+	switch v := cfg[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+func boolValue(cfg map[string]any, key string) bool {
+	v, _ := cfg[key].(bool)
+	return v
+}
+
+// durationValue accepts either a Go duration string ("5m") or a number of seconds
+func durationValue(cfg map[string]any, key string, def time.Duration) time.Duration {
+	switch v := cfg[key].(type) {
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	case float64:
+		return time.Duration(v * float64(time.Second))
+	}
+	return def
+}
@@ -0,0 +1,136 @@
+package filedrop
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// ftpsFS implements RemoteFS over FTP with explicit TLS (AUTH TLS).
+// The control connection handles one command at a time, so access is serialized.
+type ftpsFS struct {
+	mu   sync.Mutex
+	conn *ftp.ServerConn
+}
+
+func dialFTPS(ctx context.Context, cfg *Config) (RemoteFS, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.Host,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	conn, err := ftp.Dial(addr, ftp.DialWithContext(ctx), ftp.DialWithExplicitTLS(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if err := conn.Login(cfg.Username, cfg.Password); err != nil {
+		conn.Quit()
+		return nil, fmt.Errorf("ftps login failed: %w", err)
+	}
+
+	return &ftpsFS{conn: conn}, nil
+}
+
+func (f *ftpsFS) List(ctx context.Context, dir string) ([]RemoteFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.conn.List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]RemoteFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type != ftp.EntryTypeFile {
+			continue
+		}
+		files = append(files, RemoteFile{
+			Name:    entry.Name,
+			Path:    path.Join(dir, entry.Name),
+			Size:    int64(entry.Size),
+			ModTime: entry.Time,
+		})
+	}
+	return files, nil
+}
+
+// Open holds the connection lock until the returned reader is closed,
+// because FTP cannot issue commands while a transfer is in progress.
+func (f *ftpsFS) Open(ctx context.Context, p string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	resp, err := f.conn.Retr(p)
+	if err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	return &lockedReader{ReadCloser: resp, unlock: f.mu.Unlock}, nil
+}
+
+func (f *ftpsFS) Rename(ctx context.Context, from, to string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conn.Rename(from, to)
+}
+
+func (f *ftpsFS) MkdirAll(ctx context.Context, dir string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if dir == "." || dir == "/" {
+		return nil
+	}
+	if err := f.conn.ChangeDir(dir); err == nil {
+		return f.conn.ChangeDir("/")
+	}
+
+	// Create each missing component; errors for existing parents are ignored
+	current := ""
+	if path.IsAbs(dir) {
+		current = "/"
+	}
+	for _, part := range splitPath(dir) {
+		current = path.Join(current, part)
+		_ = f.conn.MakeDir(current)
+	}
+	if err := f.conn.ChangeDir(dir); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	return f.conn.ChangeDir("/")
+}
+
+func (f *ftpsFS) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conn.Quit()
+}
+
+// lockedReader releases the connection lock when the transfer is closed
+type lockedReader struct {
+	io.ReadCloser
+	unlock func()
+	once   sync.Once
+}
+
+func (r *lockedReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.unlock)
+	return err
+}
+
+func splitPath(p string) []string {
+	var parts []string
+	for dir := path.Clean(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		parts = append([]string{path.Base(dir)}, parts...)
+	}
+	return parts
+}
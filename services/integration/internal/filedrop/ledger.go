@@ -0,0 +1,234 @@
+package filedrop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ArrivalStatus is the outcome of processing a dropped file
+type ArrivalStatus string
+
+const (
+	StatusProcessed   ArrivalStatus = "processed"
+	StatusDuplicate   ArrivalStatus = "duplicate"
+	StatusQuarantined ArrivalStatus = "quarantined"
+)
+
+// Arrival records a single dropped file and what happened to it
+type Arrival struct {
+	IntegrationID string        `json:"integration_id"`
+	FileName      string        `json:"file_name"`
+	RemotePath    string        `json:"remote_path,omitempty"`
+	Size          int64         `json:"size"`
+	ModTime       time.Time     `json:"mod_time,omitempty"`
+	SHA256        string        `json:"sha256"`
+	ReceivedAt    time.Time     `json:"received_at"`
+	Status        ArrivalStatus `json:"status"`
+	MovedTo       string        `json:"moved_to,omitempty"`
+	RowsProcessed int64         `json:"rows_processed,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// Ledger remembers processed files so that re-uploads of identical content are skipped
+type Ledger interface {
+	// Lookup returns the earlier processed arrival with the same content hash, if any
+	Lookup(ctx context.Context, integrationID, sha256 string) (*Arrival, bool, error)
+	// LookupFile returns the earlier arrival of the same remote file, identified by path, size and
+	// modification time, so a file that was handled but not moved away is not downloaded again
+	LookupFile(ctx context.Context, integrationID, remotePath string, size int64, modTime time.Time) (*Arrival, bool, error)
+	// Record stores the outcome of an arrival
+	Record(ctx context.Context, arrival *Arrival) error
+	// Recent returns the latest arrivals of an integration, newest first
+	Recent(ctx context.Context, integrationID string, limit int) ([]*Arrival, error)
+	// Forget drops all arrivals of an integration
+	Forget(ctx context.Context, integrationID string) error
+}
+
+// MemoryLedger is an in-memory Ledger bounded per integration. It is used when no database is
+// configured; arrivals are lost on restart.
+type MemoryLedger struct {
+	mu       sync.RWMutex
+	capacity int
+	byHash   map[string]map[string]*Arrival
+	history  map[string][]*Arrival
+}
+
+// NewMemoryLedger creates a ledger that keeps at most capacity arrivals per integration
+func NewMemoryLedger(capacity int) *MemoryLedger {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryLedger{
+		capacity: capacity,
+		byHash:   map[string]map[string]*Arrival{},
+		history:  map[string][]*Arrival{},
+	}
+}
+
+func (l *MemoryLedger) Lookup(ctx context.Context, integrationID, sha256 string) (*Arrival, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	a, ok := l.byHash[integrationID][sha256]
+	return a, ok, nil
+}
+
+func (l *MemoryLedger) LookupFile(ctx context.Context, integrationID, remotePath string, size int64, modTime time.Time) (*Arrival, bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	history := l.history[integrationID]
+	for i := len(history) - 1; i >= 0; i-- {
+		a := history[i]
+		if a.RemotePath == remotePath && a.Size == size && a.ModTime.Equal(modTime) {
+			return a, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (l *MemoryLedger) Record(ctx context.Context, arrival *Arrival) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Only successfully processed content counts for duplicate detection,
+	// so a corrected re-upload of a quarantined file is processed again
+	if arrival.Status == StatusProcessed {
+		hashes, ok := l.byHash[arrival.IntegrationID]
+		if !ok {
+			hashes = map[string]*Arrival{}
+			l.byHash[arrival.IntegrationID] = hashes
+		}
+		hashes[arrival.SHA256] = arrival
+	}
+
+	history := append(l.history[arrival.IntegrationID], arrival)
+	if len(history) > l.capacity {
+		evicted := history[0]
+		history = history[1:]
+		if l.byHash[arrival.IntegrationID][evicted.SHA256] == evicted {
+			delete(l.byHash[arrival.IntegrationID], evicted.SHA256)
+		}
+	}
+	l.history[arrival.IntegrationID] = history
+	return nil
+}
+
+func (l *MemoryLedger) Recent(ctx context.Context, integrationID string, limit int) ([]*Arrival, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	history := l.history[integrationID]
+	if limit <= 0 || limit > len(history) {
+		limit = len(history)
+	}
+	out := make([]*Arrival, 0, limit)
+	for i := len(history) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, history[i])
+	}
+	return out, nil
+}
+
+func (l *MemoryLedger) Forget(ctx context.Context, integrationID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.byHash, integrationID)
+	delete(l.history, integrationID)
+	return nil
+}
+
+// PostgresLedger is a Ledger on the integration_file_arrivals table, so duplicates are
+// still detected after the integration service restarts
+type PostgresLedger struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLedger creates a ledger on the given pool
+func NewPostgresLedger(pool *pgxpool.Pool) *PostgresLedger {
+	return &PostgresLedger{pool: pool}
+}
+
+const arrivalColumns = `integration_id, file_name, remote_path, file_size, file_mtime, sha256,
+	status, moved_to, rows_processed, error_message, received`
+
+func (l *PostgresLedger) Lookup(ctx context.Context, integrationID, sha256 string) (*Arrival, bool, error) {
+	return l.one(ctx, `SELECT `+arrivalColumns+` FROM integration_file_arrivals
+		WHERE integration_id = $1 AND sha256 = $2 AND status = $3
+		ORDER BY received DESC LIMIT 1`, integrationID, sha256, string(StatusProcessed))
+}
+
+func (l *PostgresLedger) LookupFile(ctx context.Context, integrationID, remotePath string, size int64, modTime time.Time) (*Arrival, bool, error) {
+	return l.one(ctx, `SELECT `+arrivalColumns+` FROM integration_file_arrivals
+		WHERE integration_id = $1 AND remote_path = $2 AND file_size = $3 AND file_mtime = $4
+		ORDER BY received DESC LIMIT 1`, integrationID, remotePath, size, modTime.UTC())
+}
+
+func (l *PostgresLedger) Record(ctx context.Context, a *Arrival) error {
+	var modTime *time.Time
+	if !a.ModTime.IsZero() {
+		t := a.ModTime.UTC()
+		modTime = &t
+	}
+	_, err := l.pool.Exec(ctx, `INSERT INTO integration_file_arrivals (`+arrivalColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		a.IntegrationID, a.FileName, a.RemotePath, a.Size, modTime, a.SHA256,
+		string(a.Status), a.MovedTo, a.RowsProcessed, a.Error, a.ReceivedAt.UTC())
+	return err
+}
+
+func (l *PostgresLedger) Recent(ctx context.Context, integrationID string, limit int) ([]*Arrival, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := l.pool.Query(ctx, `SELECT `+arrivalColumns+` FROM integration_file_arrivals
+		WHERE integration_id = $1 ORDER BY received DESC, arrival_id DESC LIMIT $2`, integrationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*Arrival
+	for rows.Next() {
+		a, err := scanArrival(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (l *PostgresLedger) Forget(ctx context.Context, integrationID string) error {
+	_, err := l.pool.Exec(ctx, `DELETE FROM integration_file_arrivals WHERE integration_id = $1`, integrationID)
+	return err
+}
+
+func (l *PostgresLedger) one(ctx context.Context, sql string, args ...any) (*Arrival, bool, error) {
+	a, err := scanArrival(l.pool.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return a, true, nil
+}
+
+func scanArrival(row pgx.Row) (*Arrival, error) {
+	var (
+		a       Arrival
+		status  string
+		modTime *time.Time
+	)
+	if err := row.Scan(&a.IntegrationID, &a.FileName, &a.RemotePath, &a.Size, &modTime, &a.SHA256,
+		&status, &a.MovedTo, &a.RowsProcessed, &a.Error, &a.ReceivedAt); err != nil {
+		return nil, err
+	}
+	a.Status = ArrivalStatus(status)
+	if modTime != nil {
+		a.ModTime = *modTime
+	}
+	return &a, nil
+}
@@ -0,0 +1,138 @@
+package filedrop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// Manager runs one watcher per file drop integration
+type Manager struct {
+	mu          sync.Mutex
+	trigger     Trigger
+	ledger      Ledger
+	stagingRoot string
+	logger      *logger.Logger
+	watchers    map[string]*managedWatcher
+}
+
+type managedWatcher struct {
+	watcher *Watcher
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewManager creates a manager that runs mappings through trigger, records arrivals in ledger
+// and stages files under stagingRoot
+func NewManager(trigger Trigger, ledger Ledger, stagingRoot string, log *logger.Logger) *Manager {
+	return &Manager{
+		trigger:     trigger,
+		ledger:      ledger,
+		stagingRoot: stagingRoot,
+		logger:      log,
+		watchers:    map[string]*managedWatcher{},
+	}
+}
+
+// Start begins watching for an integration, replacing any existing watcher for it
+func (m *Manager) Start(id string, cfg *Config) {
+	m.Stop(id)
+
+	if cfg.Protocol == ProtocolSFTP && cfg.HostKey == "" && m.logger != nil {
+		m.logger.Warnf("File drop %s does not verify the SSH host key of %s (insecure_skip_host_key_check is set)", id, cfg.Host)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mw := &managedWatcher{
+		watcher: NewWatcher(id, cfg, m.stagingRoot, m.trigger, m.ledger, m.logger),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	m.watchers[id] = mw
+	m.mu.Unlock()
+
+	go func() {
+		defer close(mw.done)
+		mw.watcher.Run(ctx)
+	}()
+}
+
+// Stop stops the watcher of an integration and waits for an in-flight poll to finish
+func (m *Manager) Stop(id string) {
+	m.mu.Lock()
+	mw, ok := m.watchers[id]
+	delete(m.watchers, id)
+	m.mu.Unlock()
+
+	if ok {
+		mw.cancel()
+		<-mw.done
+	}
+}
+
+// StopAll stops every watcher
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.watchers))
+	for id := range m.watchers {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.Stop(id)
+	}
+}
+
+// Poll triggers an immediate poll of an integration
+func (m *Manager) Poll(ctx context.Context, id string) ([]*Arrival, error) {
+	w, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return w.Poll(ctx)
+}
+
+// Push processes a file pushed to an integration
+func (m *Manager) Push(ctx context.Context, id, fileName string, content io.Reader) (*Arrival, error) {
+	w, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return w.Push(ctx, fileName, content)
+}
+
+// History returns the latest arrivals of an integration, newest first
+func (m *Manager) History(ctx context.Context, id string, limit int) ([]*Arrival, error) {
+	return m.ledger.Recent(ctx, id, limit)
+}
+
+// StagingPath returns the staged file of a running integration
+func (m *Manager) StagingPath(id string) (string, error) {
+	w, err := m.get(id)
+	if err != nil {
+		return "", err
+	}
+	return w.StagingPath(), nil
+}
+
+func (m *Manager) get(id string) (*Watcher, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mw, ok := m.watchers[id]
+	if !ok {
+		return nil, fmt.Errorf("file drop %s is not running", id)
+	}
+	return mw.watcher, nil
+}
+
+// Remove stops the watcher of an integration and forgets its arrivals
+func (m *Manager) Remove(ctx context.Context, id string) error {
+	m.Stop(id)
+	return m.ledger.Forget(ctx, id)
+}
@@ -0,0 +1,41 @@
+package filedrop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RemoteFile describes a file in the watched remote directory
+type RemoteFile struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// RemoteFS is the subset of remote file system operations a file drop needs
+type RemoteFS interface {
+	// List returns the regular files in dir
+	List(ctx context.Context, dir string) ([]RemoteFile, error)
+	// Open opens a remote file for reading
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+	// Rename moves a remote file, used for archival and quarantine
+	Rename(ctx context.Context, from, to string) error
+	// MkdirAll creates a remote directory and its parents if they do not exist
+	MkdirAll(ctx context.Context, dir string) error
+	Close() error
+}
+
+// Dial connects to the remote server described by cfg
+func Dial(ctx context.Context, cfg *Config) (RemoteFS, error) {
+	switch cfg.Protocol {
+	case ProtocolSFTP:
+		return dialSFTP(ctx, cfg)
+	case ProtocolFTPS:
+		return dialFTPS(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("protocol %s has no remote server", cfg.Protocol)
+	}
+}
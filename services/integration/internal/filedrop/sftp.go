@@ -0,0 +1,122 @@
+package filedrop
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpFS implements RemoteFS over SFTP
+type sftpFS struct {
+	ssh    *ssh.Client
+	client *sftp.Client
+}
+
+func dialSFTP(ctx context.Context, cfg *Config) (RemoteFS, error) {
+	auth := make([]ssh.AuthMethod, 0, 2)
+	if cfg.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case cfg.HostKey != "":
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse host key: %w", err)
+		}
+		hostKeyCallback = ssh.FixedHostKey(key)
+	case cfg.InsecureSkipHostKeyCheck:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, fmt.Errorf("host_key is required to verify %s", cfg.Host)
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake with %s failed: %w", addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("failed to start sftp session: %w", err)
+	}
+
+	return &sftpFS{ssh: sshClient, client: client}, nil
+}
+
+func (s *sftpFS) List(ctx context.Context, dir string) ([]RemoteFile, error) {
+	entries, err := s.client.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]RemoteFile, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, RemoteFile{
+			Name:    entry.Name(),
+			Path:    path.Join(dir, entry.Name()),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+func (s *sftpFS) Open(ctx context.Context, p string) (io.ReadCloser, error) {
+	return s.client.Open(p)
+}
+
+func (s *sftpFS) Rename(ctx context.Context, from, to string) error {
+	// Prefer the posix-rename extension, which replaces existing targets;
+	// servers without it only support plain rename
+	if err := s.client.PosixRename(from, to); err != nil {
+		return s.client.Rename(from, to)
+	}
+	return nil
+}
+
+func (s *sftpFS) MkdirAll(ctx context.Context, dir string) error {
+	if info, err := s.client.Stat(dir); err == nil && info.IsDir() {
+		return nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.client.MkdirAll(dir)
+}
+
+func (s *sftpFS) Close() error {
+	s.client.Close()
+	return s.ssh.Close()
+}
@@ -0,0 +1,309 @@
+package filedrop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// RunResult is reported by a Trigger after a mapping run
+type RunResult struct {
+	RowsProcessed int64
+}
+
+// Trigger runs the file source mapping once the new file has been staged
+type Trigger func(ctx context.Context, cfg *Config, arrival *Arrival) (*RunResult, error)
+
+// DialFunc opens a connection to the remote server
+type DialFunc func(ctx context.Context, cfg *Config) (RemoteFS, error)
+
+// observation tracks a remote file until its size stops changing
+type observation struct {
+	size      int64
+	firstSeen time.Time
+}
+
+// Watcher polls a remote directory and processes each matching file once
+type Watcher struct {
+	id      string
+	cfg     *Config
+	trigger Trigger
+	ledger  Ledger
+	dial    DialFunc
+	logger  *logger.Logger
+
+	// stagingRoot confines the staged file; stagingPath is the file each arrival replaces
+	stagingRoot string
+	stagingPath string
+
+	// mu serializes polls and pushes, since every arrival replaces the same staging file
+	mu      sync.Mutex
+	pending map[string]observation
+	now     func() time.Time
+}
+
+// NewWatcher creates a watcher for a single file drop integration that stages files under stagingRoot
+func NewWatcher(id string, cfg *Config, stagingRoot string, trigger Trigger, ledger Ledger, log *logger.Logger) *Watcher {
+	return &Watcher{
+		id:          id,
+		cfg:         cfg,
+		trigger:     trigger,
+		ledger:      ledger,
+		dial:        Dial,
+		logger:      log,
+		stagingRoot: stagingRoot,
+		stagingPath: cfg.StagingPath(stagingRoot, id),
+		pending:     map[string]observation{},
+		now:         time.Now,
+	}
+}
+
+// StagingPath returns the file that the mapping's file source database must be connected to
+func (w *Watcher) StagingPath() string {
+	return w.stagingPath
+}
+
+// Run polls until ctx is cancelled. Push-only watchers return immediately.
+func (w *Watcher) Run(ctx context.Context) {
+	if w.cfg.Protocol == ProtocolPush {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil && ctx.Err() == nil && w.logger != nil {
+			w.logger.Warnf("File drop %s poll failed: %v", w.id, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll lists the remote directory once and processes every stable, matching file
+func (w *Watcher) Poll(ctx context.Context) ([]*Arrival, error) {
+	if w.cfg.Protocol == ProtocolPush {
+		return nil, fmt.Errorf("file drop %s only accepts pushed files", w.id)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	fs, err := w.dial(ctx, w.cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	files, err := fs.List(ctx, w.cfg.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", w.cfg.Directory, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime.Before(files[j].ModTime) })
+
+	now := w.now()
+	seen := make(map[string]bool, len(files))
+	var arrivals []*Arrival
+	for _, file := range files {
+		if matched, _ := path.Match(w.cfg.Pattern, file.Name); !matched {
+			continue
+		}
+		seen[file.Path] = true
+		if !w.isStable(file, now) {
+			continue
+		}
+
+		arrival, err := w.processRemote(ctx, fs, file)
+		delete(w.pending, file.Path)
+		if err != nil {
+			return arrivals, err
+		}
+		arrivals = append(arrivals, arrival)
+	}
+
+	// Forget observations of files that disappeared before they were picked up
+	for p := range w.pending {
+		if !seen[p] {
+			delete(w.pending, p)
+		}
+	}
+
+	return arrivals, nil
+}
+
+// isStable reports whether a file has stopped growing for at least MinFileAge
+func (w *Watcher) isStable(file RemoteFile, now time.Time) bool {
+	obs, ok := w.pending[file.Path]
+	if !ok || obs.size != file.Size {
+		w.pending[file.Path] = observation{size: file.Size, firstSeen: now}
+		return w.cfg.MinFileAge <= 0
+	}
+	return now.Sub(obs.firstSeen) >= w.cfg.MinFileAge
+}
+
+// processRemote downloads, runs and then archives or quarantines a remote file.
+// Files that were already handled but not moved away are only moved.
+func (w *Watcher) processRemote(ctx context.Context, fs RemoteFS, file RemoteFile) (*Arrival, error) {
+	arrival, handled, err := w.ledger.LookupFile(ctx, w.id, file.Path, file.Size, file.ModTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", file.Path, err)
+	}
+	if !handled {
+		reader, err := fs.Open(ctx, file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.Path, err)
+		}
+		arrival, err = w.ingest(ctx, &Arrival{
+			IntegrationID: w.id,
+			FileName:      file.Name,
+			RemotePath:    file.Path,
+			Size:          file.Size,
+			ModTime:       file.ModTime,
+			ReceivedAt:    w.now(),
+		}, reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	targetDir := w.cfg.ArchiveDir
+	if arrival.Status == StatusQuarantined {
+		targetDir = w.cfg.QuarantineDir
+	}
+	if err := fs.MkdirAll(ctx, targetDir); err != nil {
+		return arrival, fmt.Errorf("failed to create %s: %w", targetDir, err)
+	}
+	target := path.Join(targetDir, fmt.Sprintf("%s_%s", arrival.ReceivedAt.UTC().Format("20060102T150405Z"), file.Name))
+	if err := fs.Rename(ctx, file.Path, target); err != nil {
+		return arrival, fmt.Errorf("failed to move %s to %s: %w", file.Path, target, err)
+	}
+	arrival.MovedTo = target
+
+	return arrival, nil
+}
+
+// Push processes a file delivered directly to the integration
+func (w *Watcher) Push(ctx context.Context, fileName string, content io.Reader) (*Arrival, error) {
+	if matched, _ := path.Match(w.cfg.Pattern, fileName); !matched {
+		return nil, fmt.Errorf("file %s does not match pattern %s", fileName, w.cfg.Pattern)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.ingest(ctx, &Arrival{
+		IntegrationID: w.id,
+		FileName:      fileName,
+		ReceivedAt:    w.now(),
+	}, content)
+}
+
+// ingest stages the content next to the file source database, skips duplicates and runs the mapping.
+// An error means the file could not be staged; remote files are then left in place and retried.
+func (w *Watcher) ingest(ctx context.Context, arrival *Arrival, content io.Reader) (*Arrival, error) {
+	dir, err := w.stagingDir()
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".filedrop-*"+filepath.Ext(w.stagingPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", arrival.FileName, err)
+	}
+	arrival.Size = size
+	arrival.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	previous, ok, err := w.ledger.Lookup(ctx, w.id, arrival.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", arrival.FileName, err)
+	}
+	if ok {
+		arrival.Status = StatusDuplicate
+		arrival.Error = fmt.Sprintf("identical to %s received at %s", previous.FileName, previous.ReceivedAt.UTC().Format(time.RFC3339))
+		w.record(ctx, arrival)
+		return arrival, nil
+	}
+
+	// The file adapter reloads the staging file when its modification time changes
+	if err := os.Rename(tmp.Name(), w.stagingPath); err != nil {
+		return nil, fmt.Errorf("failed to stage %s: %w", arrival.FileName, err)
+	}
+
+	result, err := w.trigger(ctx, w.cfg, arrival)
+	if err != nil {
+		arrival.Status = StatusQuarantined
+		arrival.Error = err.Error()
+		w.record(ctx, arrival)
+		return arrival, nil
+	}
+	if result != nil {
+		arrival.RowsProcessed = result.RowsProcessed
+	}
+	arrival.Status = StatusProcessed
+	w.record(ctx, arrival)
+	return arrival, nil
+}
+
+// stagingDir creates the directory of the staged file and checks that it resolves inside the staging root
+func (w *Watcher) stagingDir() (string, error) {
+	dir := filepath.Dir(w.stagingPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(w.stagingRoot)
+	if err != nil {
+		return "", fmt.Errorf("staging root is not accessible: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("staging directory is not accessible: %w", err)
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("staging directory %s is outside of the staging root", dir)
+	}
+	return dir, nil
+}
+
+func (w *Watcher) record(ctx context.Context, arrival *Arrival) {
+	err := w.ledger.Record(ctx, arrival)
+	if w.logger == nil {
+		return
+	}
+	if err != nil {
+		w.logger.Errorf("File drop %s failed to record %s: %v", w.id, arrival.FileName, err)
+	}
+	switch arrival.Status {
+	case StatusQuarantined:
+		w.logger.Warnf("File drop %s quarantined %s: %s", w.id, arrival.FileName, arrival.Error)
+	case StatusDuplicate:
+		w.logger.Infof("File drop %s skipped duplicate %s", w.id, arrival.FileName)
+	default:
+		w.logger.Infof("File drop %s processed %s (%d rows)", w.id, arrival.FileName, arrival.RowsProcessed)
+	}
+}
@@ -0,0 +1,255 @@
+package filedrop
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFS is an in-memory RemoteFS
+type fakeFS struct {
+	mu      sync.Mutex
+	files   map[string][]byte
+	modTime map[string]time.Time
+}
+
+func newFakeFS() *fakeFS {
+	return &fakeFS{files: map[string][]byte{}, modTime: map[string]time.Time{}}
+}
+
+func (f *fakeFS) put(p, content string, modTime time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[p] = []byte(content)
+	f.modTime[p] = modTime
+}
+
+func (f *fakeFS) has(p string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.files[p]
+	return ok
+}
+
+func (f *fakeFS) List(ctx context.Context, dir string) ([]RemoteFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var files []RemoteFile
+	for p, content := range f.files {
+		if path.Dir(p) != dir {
+			continue
+		}
+		files = append(files, RemoteFile{Name: path.Base(p), Path: p, Size: int64(len(content)), ModTime: f.modTime[p]})
+	}
+	return files, nil
+}
+
+func (f *fakeFS) Open(ctx context.Context, p string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.files[p]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (f *fakeFS) Rename(ctx context.Context, from, to string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.files[from]
+	if !ok {
+		return os.ErrNotExist
+	}
+	f.files[to] = content
+	f.modTime[to] = f.modTime[from]
+	delete(f.files, from)
+	delete(f.modTime, from)
+	return nil
+}
+
+func (f *fakeFS) MkdirAll(ctx context.Context, dir string) error { return nil }
+
+func (f *fakeFS) Close() error { return nil }
+
+// failingRenameFS fails every move, leaving files in the watched directory
+type failingRenameFS struct{ *fakeFS }
+
+func (f failingRenameFS) Rename(ctx context.Context, from, to string) error {
+	return fmt.Errorf("permission denied")
+}
+
+func newTestWatcher(t *testing.T, fs RemoteFS, ledger Ledger, minAge time.Duration, runs *int) (*Watcher, *time.Time) {
+	t.Helper()
+	cfg, err := ParseConfig("tenant", map[string]any{
+		"protocol":                     "sftp",
+		"host":                         "sftp.example.com",
+		"username":                     "drop",
+		"password":                     "secret",
+		"insecure_skip_host_key_check": true,
+		"directory":                    "/in",
+		"pattern":                      "*.csv",
+		"workspace_name":               "default",
+		"mapping_name":                 "orders",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.MinFileAge = minAge
+
+	trigger := func(ctx context.Context, cfg *Config, arrival *Arrival) (*RunResult, error) {
+		*runs++
+		return &RunResult{RowsProcessed: 1}, nil
+	}
+	w := NewWatcher("integ1", cfg, t.TempDir(), trigger, ledger, nil)
+	w.dial = func(ctx context.Context, cfg *Config) (RemoteFS, error) { return fs, nil }
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	return w, &now
+}
+
+func TestWatcherSkipsDuplicateContent(t *testing.T) {
+	fs := newFakeFS()
+	runs := 0
+	w, now := newTestWatcher(t, fs, NewMemoryLedger(0), 0, &runs)
+
+	fs.put("/in/orders_1.csv", "id\n1\n", *now)
+	arrivals, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arrivals) != 1 || arrivals[0].Status != StatusProcessed {
+		t.Fatalf("first poll = %+v, want one processed arrival", arrivals)
+	}
+
+	// The same content under a new name is archived without running the mapping again
+	*now = now.Add(time.Minute)
+	fs.put("/in/orders_2.csv", "id\n1\n", *now)
+	arrivals, err = w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arrivals) != 1 || arrivals[0].Status != StatusDuplicate {
+		t.Fatalf("second poll = %+v, want one duplicate arrival", arrivals)
+	}
+	if runs != 1 {
+		t.Fatalf("mapping ran %d times, want 1", runs)
+	}
+	if fs.has("/in/orders_2.csv") {
+		t.Fatal("duplicate was not moved out of the watched directory")
+	}
+
+	staged, err := os.ReadFile(w.StagingPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(staged) != "id\n1\n" {
+		t.Fatalf("staged content = %q", staged)
+	}
+}
+
+func TestWatcherWaitsForPartialWrites(t *testing.T) {
+	fs := newFakeFS()
+	runs := 0
+	w, now := newTestWatcher(t, fs, NewMemoryLedger(0), 30*time.Second, &runs)
+
+	// First sighting only starts the observation
+	fs.put("/in/orders.csv", "id\n1\n", *now)
+	if arrivals, err := w.Poll(context.Background()); err != nil || len(arrivals) != 0 {
+		t.Fatalf("first poll = %v, %v, want no arrivals", arrivals, err)
+	}
+
+	// The file is still growing, so the observation restarts
+	*now = now.Add(20 * time.Second)
+	fs.put("/in/orders.csv", "id\n1\n2\n", *now)
+	if arrivals, err := w.Poll(context.Background()); err != nil || len(arrivals) != 0 {
+		t.Fatalf("poll while growing = %v, %v, want no arrivals", arrivals, err)
+	}
+
+	// Unchanged, but not yet for MinFileAge
+	*now = now.Add(20 * time.Second)
+	if arrivals, err := w.Poll(context.Background()); err != nil || len(arrivals) != 0 {
+		t.Fatalf("poll before min age = %v, %v, want no arrivals", arrivals, err)
+	}
+
+	*now = now.Add(15 * time.Second)
+	arrivals, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arrivals) != 1 || arrivals[0].Size != int64(len("id\n1\n2\n")) {
+		t.Fatalf("stable poll = %+v, want the complete file", arrivals)
+	}
+	if runs != 1 {
+		t.Fatalf("mapping ran %d times, want 1", runs)
+	}
+}
+
+func TestWatcherDoesNotReprocessUnmovedFiles(t *testing.T) {
+	fs := failingRenameFS{newFakeFS()}
+	ledger := NewMemoryLedger(0)
+	runs := 0
+	w, now := newTestWatcher(t, fs, ledger, 0, &runs)
+
+	fs.put("/in/orders.csv", "id\n1\n", *now)
+	if _, err := w.Poll(context.Background()); err == nil {
+		t.Fatal("expected the failed move to be reported")
+	}
+
+	// A new watcher on the same ledger, as after a restart, must not run the mapping again
+	restarted, _ := newTestWatcher(t, fs, ledger, 0, &runs)
+	if _, err := restarted.Poll(context.Background()); err == nil {
+		t.Fatal("expected the failed move to be reported")
+	}
+	if runs != 1 {
+		t.Fatalf("mapping ran %d times, want 1", runs)
+	}
+}
+
+func TestConfigStagingPath(t *testing.T) {
+	base := map[string]any{"protocol": "push", "workspace_name": "default", "mapping_name": "orders"}
+
+	cfg, err := ParseConfig("tenant", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.StagingPath("/srv/uploads", "integ/1"), filepath.Join("/srv/uploads", "filedrop", "filedrop_integ_1.csv"); got != want {
+		t.Fatalf("StagingPath() = %q, want %q", got, want)
+	}
+
+	for _, dir := range []string{"/etc", "../outside", "a/../../b"} {
+		m := map[string]any{"staging_dir": dir}
+		for k, v := range base {
+			m[k] = v
+		}
+		if _, err := ParseConfig("tenant", m); err == nil {
+			t.Errorf("staging_dir %q was accepted", dir)
+		}
+	}
+}
+
+func TestConfigRequiresSFTPHostKey(t *testing.T) {
+	cfg := map[string]any{
+		"protocol":       "sftp",
+		"host":           "sftp.example.com",
+		"username":       "drop",
+		"password":       "secret",
+		"workspace_name": "default",
+		"mapping_name":   "orders",
+	}
+	if _, err := ParseConfig("tenant", cfg); err == nil {
+		t.Fatal("sftp config without host_key was accepted")
+	}
+	cfg["insecure_skip_host_key_check"] = true
+	if _, err := ParseConfig("tenant", cfg); err != nil {
+		t.Fatalf("explicit opt-out was rejected: %v", err)
+	}
+}
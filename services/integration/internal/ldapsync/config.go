@@ -0,0 +1,261 @@
+package ldapsync
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schema selects the attribute and filter defaults of a directory flavour
+type Schema string
+
+const (
+	SchemaOpenLDAP        Schema = "openldap"
+	SchemaActiveDirectory Schema = "active_directory"
+)
+
+// RoleMapping grants a tenant role to the members of a directory group
+type RoleMapping struct {
+	// Group is the group name (cn) or full DN
+	Group string
+	// Role is the name of an existing tenant role
+	Role string
+}
+
+// Config describes an LDAP sync integration. It is decoded from the integration's config struct.
+type Config struct {
+	// URL is the server address, ldap://host:389 or ldaps://host:636
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	BindDN             string
+	BindPassword       string
+
+	Schema      Schema
+	BaseDN      string
+	UserBaseDN  string
+	GroupBaseDN string
+	UserFilter  string
+	GroupFilter string
+	// IDAttribute holds the stable identifier that links entries to tenant users, entryUUID or
+	// objectGUID; unlike the DN and email it does not change when the entry is renamed
+	IDAttribute string
+	// EmailAttribute holds the email of new tenant users; entries without it are skipped
+	EmailAttribute     string
+	NameAttribute      string
+	GroupNameAttribute string
+	// MemberAttributes list the DNs of group members; nested groups are expanded
+	MemberAttributes []string
+	PageSize         uint32
+
+	// SyncGroups mirrors directory groups as tenant groups
+	SyncGroups bool
+	// GroupFilterNames restricts the synced groups to these names; empty syncs all groups found
+	GroupFilterNames []string
+	RoleMappings     []RoleMapping
+	// DefaultRoles are granted to every synced user
+	DefaultRoles []string
+	// DisableMissing disables synced users that are no longer found in the directory
+	DisableMissing bool
+
+	SyncInterval time.Duration
+	TenantID     string
+	// OwnerID is the tenant user recorded as owner and grantor of synced objects
+	OwnerID string
+}
+
+// ParseConfig builds a Config from an integration config map
+func ParseConfig(tenantID string, cfg map[string]any) (*Config, error) {
+	c := &Config{
+		URL:                stringValue(cfg, "url"),
+		StartTLS:           boolValue(cfg, "start_tls", false),
+		InsecureSkipVerify: boolValue(cfg, "insecure_skip_verify", false),
+		BindDN:             stringValue(cfg, "bind_dn"),
+		BindPassword:       stringValue(cfg, "bind_password"),
+		Schema:             Schema(strings.ToLower(stringValue(cfg, "schema"))),
+		BaseDN:             stringValue(cfg, "base_dn"),
+		UserBaseDN:         stringValue(cfg, "user_base_dn"),
+		GroupBaseDN:        stringValue(cfg, "group_base_dn"),
+		UserFilter:         stringValue(cfg, "user_filter"),
+		GroupFilter:        stringValue(cfg, "group_filter"),
+		IDAttribute:        stringValue(cfg, "id_attribute"),
+		EmailAttribute:     stringValue(cfg, "email_attribute"),
+		NameAttribute:      stringValue(cfg, "name_attribute"),
+		GroupNameAttribute: stringValue(cfg, "group_name_attribute"),
+		MemberAttributes:   stringList(cfg, "member_attributes"),
+		PageSize:           uint32(intValue(cfg, "page_size")),
+		SyncGroups:         boolValue(cfg, "sync_groups", true),
+		GroupFilterNames:   stringList(cfg, "groups"),
+		DefaultRoles:       stringList(cfg, "default_roles"),
+		DisableMissing:     boolValue(cfg, "disable_missing", true),
+		SyncInterval:       durationValue(cfg, "sync_interval", time.Hour),
+		TenantID:           tenantID,
+		OwnerID:            stringValue(cfg, "owner_id"),
+	}
+
+	if list, ok := cfg["role_mappings"].([]any); ok {
+		for _, item := range list {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("role_mappings entries must be objects with group and role")
+			}
+			c.RoleMappings = append(c.RoleMappings, RoleMapping{Group: stringValue(m, "group"), Role: stringValue(m, "role")})
+		}
+	}
+
+	if c.Schema == "" {
+		c.Schema = SchemaOpenLDAP
+	}
+	c.applySchemaDefaults()
+	if c.UserBaseDN == "" {
+		c.UserBaseDN = c.BaseDN
+	}
+	if c.GroupBaseDN == "" {
+		c.GroupBaseDN = c.BaseDN
+	}
+	if c.PageSize == 0 {
+		c.PageSize = 500
+	}
+
+	return c, c.Validate()
+}
+
+// applySchemaDefaults fills attributes and filters that were not configured
+func (c *Config) applySchemaDefaults() {
+	setDefault := func(v *string, def string) {
+		if *v == "" {
+			*v = def
+		}
+	}
+
+	setDefault(&c.EmailAttribute, "mail")
+	setDefault(&c.GroupNameAttribute, "cn")
+	if len(c.MemberAttributes) == 0 {
+		c.MemberAttributes = []string{"member"}
+	}
+
+	switch c.Schema {
+	case SchemaActiveDirectory:
+		setDefault(&c.IDAttribute, "objectGUID")
+		setDefault(&c.NameAttribute, "displayName")
+		setDefault(&c.UserFilter, "(&(objectCategory=person)(objectClass=user)(mail=*))")
+		setDefault(&c.GroupFilter, "(objectClass=group)")
+	default:
+		setDefault(&c.IDAttribute, "entryUUID")
+		setDefault(&c.NameAttribute, "cn")
+		setDefault(&c.UserFilter, "(&(objectClass=inetOrgPerson)(mail=*))")
+		setDefault(&c.GroupFilter, "(|(objectClass=groupOfNames)(objectClass=groupOfUniqueNames))")
+		if len(c.MemberAttributes) == 1 && c.MemberAttributes[0] == "member" {
+			c.MemberAttributes = append(c.MemberAttributes, "uniqueMember")
+		}
+	}
+}
+
+// Validate checks that the configuration is complete
+func (c *Config) Validate() error {
+	switch c.Schema {
+	case SchemaOpenLDAP, SchemaActiveDirectory:
+	default:
+		return fmt.Errorf("unsupported directory schema: %s", c.Schema)
+	}
+	if !strings.HasPrefix(c.URL, "ldap://") && !strings.HasPrefix(c.URL, "ldaps://") {
+		return fmt.Errorf("url must start with ldap:// or ldaps://")
+	}
+	if c.StartTLS && strings.HasPrefix(c.URL, "ldaps://") {
+		return fmt.Errorf("start_tls cannot be combined with an ldaps:// url")
+	}
+	if c.BindDN != "" && c.BindPassword == "" {
+		return fmt.Errorf("bind_password is required when bind_dn is set")
+	}
+	if c.BaseDN == "" && (c.UserBaseDN == "" || c.GroupBaseDN == "") {
+		return fmt.Errorf("base_dn is required")
+	}
+	if c.OwnerID == "" {
+		return fmt.Errorf("owner_id is required to record the owner of synced users and groups")
+	}
+	for _, m := range c.RoleMappings {
+		if m.Group == "" || m.Role == "" {
+			return fmt.Errorf("role_mappings entries require group and role")
+		}
+	}
+	if c.SyncInterval <= 0 {
+		return fmt.Errorf("sync_interval must be positive")
+	}
+	return nil
+}
+
+// ManagedRoles returns the names of all roles assigned by the sync
+func (c *Config) ManagedRoles() []string {
+	seen := map[string]bool{}
+	var roles []string
+	for _, r := range c.DefaultRoles {
+		if !seen[r] {
+			seen[r] = true
+			roles = append(roles, r)
+		}
+	}
+	for _, m := range c.RoleMappings {
+		if !seen[m.Role] {
+			seen[m.Role] = true
+			roles = append(roles, m.Role)
+		}
+	}
+	return roles
+}
+
+func stringValue(cfg map[string]any, key string) string {
+	if v, ok := cfg[key].(string); ok {
+		return strings.TrimSpace(v)
+	}
+	return ""
+}
+
+// stringList accepts either a list of strings or a comma-separated string
+func stringList(cfg map[string]any, key string) []string {
+	var out []string
+	switch v := cfg[key].(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func intValue(cfg map[string]any, key string) int {
+	switch v := cfg[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+func boolValue(cfg map[string]any, key string, def bool) bool {
+	if v, ok := cfg[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// durationValue accepts either a Go duration string ("30m") or a number of seconds
+func durationValue(cfg map[string]any, key string, def time.Duration) time.Duration {
+	switch v := cfg[key].(type) {
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	case float64:
+		return time.Duration(v * float64(time.Second))
+	}
+	return def
+}
@@ -0,0 +1,229 @@
+package ldapsync
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// adAccountDisabled is the ACCOUNTDISABLE flag of the Active Directory userAccountControl attribute
+const adAccountDisabled = 0x2
+
+// DirectoryUser is a user entry read from the directory
+type DirectoryUser struct {
+	// ID is the stable identifier of the entry, see Config.IDAttribute
+	ID       string
+	DN       string
+	Email    string
+	Name     string
+	Disabled bool
+}
+
+// DirectoryGroup is a group entry with its members resolved to user DNs, including
+// the members of nested groups
+type DirectoryGroup struct {
+	DN          string
+	Name        string
+	Description string
+	Members     []string
+}
+
+// Snapshot is the state of the directory at one point in time
+type Snapshot struct {
+	Users  []DirectoryUser
+	Groups []DirectoryGroup
+}
+
+// Directory reads users and groups from a directory server
+type Directory interface {
+	Read(ctx context.Context) (*Snapshot, error)
+}
+
+// DirectoryFunc opens the directory of a configuration
+type DirectoryFunc func(cfg *Config) Directory
+
+// NewLDAPDirectory returns a Directory backed by an LDAP server
+func NewLDAPDirectory(cfg *Config) Directory {
+	return &ldapDirectory{cfg: cfg}
+}
+
+type ldapDirectory struct {
+	cfg *Config
+}
+
+// Read binds with the configured account and reads all users and groups with paged searches
+func (d *ldapDirectory) Read(ctx context.Context) (*Snapshot, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetTimeout(time.Until(deadline))
+	}
+
+	users, err := d.readUsers(conn)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := d.readGroups(conn)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Users: users, Groups: groups}, nil
+}
+
+func (d *ldapDirectory) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: d.cfg.InsecureSkipVerify}
+	conn, err := ldap.DialURL(d.cfg.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", d.cfg.URL, err)
+	}
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to bind as %s: %w", d.cfg.BindDN, err)
+		}
+	}
+	return conn, nil
+}
+
+func (d *ldapDirectory) readUsers(conn *ldap.Conn) ([]DirectoryUser, error) {
+	attributes := []string{d.cfg.IDAttribute, d.cfg.EmailAttribute, d.cfg.NameAttribute}
+	if d.cfg.Schema == SchemaActiveDirectory {
+		attributes = append(attributes, "userAccountControl")
+	}
+	entries, err := d.search(conn, d.cfg.UserBaseDN, d.cfg.UserFilter, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	users := make([]DirectoryUser, 0, len(entries))
+	for _, e := range entries {
+		email := strings.ToLower(strings.TrimSpace(e.GetAttributeValue(d.cfg.EmailAttribute)))
+		if email == "" {
+			continue
+		}
+		user := DirectoryUser{ID: entryID(e, d.cfg.IDAttribute), DN: e.DN, Email: email, Name: e.GetAttributeValue(d.cfg.NameAttribute)}
+		if d.cfg.Schema == SchemaActiveDirectory {
+			if uac, err := strconv.ParseInt(e.GetAttributeValue("userAccountControl"), 10, 64); err == nil {
+				user.Disabled = uac&adAccountDisabled != 0
+			}
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// entryID returns the identifier attribute of an entry as a string. Active Directory's
+// objectGUID is binary and is formatted like a GUID.
+func entryID(e *ldap.Entry, attribute string) string {
+	if strings.EqualFold(attribute, "objectGUID") {
+		return formatGUID(e.GetRawAttributeValue(attribute))
+	}
+	return strings.ToLower(strings.TrimSpace(e.GetAttributeValue(attribute)))
+}
+
+// formatGUID formats a 16-byte objectGUID in the usual textual form, whose first three groups
+// are stored little-endian
+func formatGUID(b []byte) string {
+	if len(b) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
+		b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8], b[9], b[10], b[11], b[12], b[13], b[14], b[15])
+}
+
+func (d *ldapDirectory) readGroups(conn *ldap.Conn) ([]DirectoryGroup, error) {
+	attributes := append([]string{d.cfg.GroupNameAttribute, "description"}, d.cfg.MemberAttributes...)
+	entries, err := d.search(conn, d.cfg.GroupBaseDN, d.cfg.GroupFilter, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search groups: %w", err)
+	}
+
+	direct := make(map[string][]string, len(entries))
+	groups := make([]DirectoryGroup, 0, len(entries))
+	for _, e := range entries {
+		var members []string
+		for _, attr := range d.cfg.MemberAttributes {
+			members = append(members, e.GetAttributeValues(attr)...)
+		}
+		direct[normalizeDN(e.DN)] = members
+		groups = append(groups, DirectoryGroup{
+			DN:          e.DN,
+			Name:        e.GetAttributeValue(d.cfg.GroupNameAttribute),
+			Description: e.GetAttributeValue("description"),
+		})
+	}
+
+	for i := range groups {
+		groups[i].Members = expandMembers(groups[i].DN, direct)
+	}
+	return groups, nil
+}
+
+func (d *ldapDirectory) search(conn *ldap.Conn, baseDN, filter string, attributes []string) ([]*ldap.Entry, error) {
+	req := ldap.NewSearchRequest(baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, attributes, nil)
+	res, err := conn.SearchWithPaging(req, d.cfg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return res.Entries, nil
+}
+
+// expandMembers returns the non-group members of a group, following nested groups
+func expandMembers(groupDN string, direct map[string][]string) []string {
+	visited := map[string]bool{}
+	seen := map[string]bool{}
+	var members []string
+
+	var walk func(dn string)
+	walk = func(dn string) {
+		key := normalizeDN(dn)
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, member := range direct[key] {
+			memberKey := normalizeDN(member)
+			if _, isGroup := direct[memberKey]; isGroup {
+				walk(member)
+				continue
+			}
+			if !seen[memberKey] {
+				seen[memberKey] = true
+				members = append(members, member)
+			}
+		}
+	}
+	walk(groupDN)
+	return members
+}
+
+// normalizeDN returns a comparable form of a DN; attribute names and values are
+// compared case-insensitively and spaces after separators are ignored
+func normalizeDN(dn string) string {
+	if parsed, err := ldap.ParseDN(dn); err == nil {
+		parts := make([]string, 0, len(parsed.RDNs))
+		for _, rdn := range parsed.RDNs {
+			attrs := make([]string, 0, len(rdn.Attributes))
+			for _, a := range rdn.Attributes {
+				attrs = append(attrs, strings.ToLower(a.Type)+"="+strings.ToLower(a.Value))
+			}
+			parts = append(parts, strings.Join(attrs, "+"))
+		}
+		return strings.Join(parts, ",")
+	}
+	return strings.ToLower(strings.TrimSpace(dn))
+}
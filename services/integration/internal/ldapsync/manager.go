@@ -0,0 +1,227 @@
+package ldapsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redbco/redb-open/pkg/logger"
+)
+
+// historySize is the number of runs kept in memory per integration; all runs are recorded in
+// the run history of the database, which retention policies prune
+const historySize = 50
+
+// RunStatus is the outcome of a sync run
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// Run records a single reconciliation
+type Run struct {
+	IntegrationID   string    `json:"integration_id"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DryRun          bool      `json:"dry_run"`
+	Status          RunStatus `json:"status"`
+	DirectoryUsers  int       `json:"directory_users"`
+	DirectoryGroups int       `json:"directory_groups"`
+	Summary         Summary   `json:"summary"`
+	Warnings        []string  `json:"warnings,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Manager runs the scheduled reconciliation of every LDAP sync integration
+type Manager struct {
+	mu        sync.Mutex
+	store     Store
+	directory DirectoryFunc
+	logger    *logger.Logger
+	syncers   map[string]*syncer
+	history   map[string][]*Run
+}
+
+type syncer struct {
+	cfg    *Config
+	cancel context.CancelFunc
+	done   chan struct{}
+	// running serializes scheduled and on-demand runs of one integration
+	running sync.Mutex
+}
+
+// NewManager creates a manager that reconciles tenants through store
+func NewManager(store Store, log *logger.Logger) *Manager {
+	return &Manager{
+		store:     store,
+		directory: NewLDAPDirectory,
+		logger:    log,
+		syncers:   map[string]*syncer{},
+		history:   map[string][]*Run{},
+	}
+}
+
+// Start schedules the reconciliation of an integration, replacing any existing schedule for it
+func (m *Manager) Start(id string, cfg *Config) {
+	m.Stop(id)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &syncer{cfg: cfg, cancel: cancel, done: make(chan struct{})}
+
+	m.mu.Lock()
+	m.syncers[id] = s
+	m.mu.Unlock()
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(cfg.SyncInterval)
+		defer ticker.Stop()
+		for {
+			run := m.run(ctx, id, s, false)
+			if run.Status == RunFailed && ctx.Err() == nil && m.logger != nil {
+				m.logger.Warnf("LDAP sync %s failed: %s", id, run.Error)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the schedule of an integration and waits for an in-flight run to finish
+func (m *Manager) Stop(id string) {
+	m.mu.Lock()
+	s, ok := m.syncers[id]
+	delete(m.syncers, id)
+	m.mu.Unlock()
+
+	if ok {
+		s.cancel()
+		<-s.done
+	}
+}
+
+// StopAll stops every schedule
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.syncers))
+	for id := range m.syncers {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.Stop(id)
+	}
+}
+
+// Sync reconciles an integration immediately. A dry run only reports the planned changes.
+func (m *Manager) Sync(ctx context.Context, id string, dryRun bool) (*Run, error) {
+	m.mu.Lock()
+	s, ok := m.syncers[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("ldap sync %s is not running", id)
+	}
+	return m.run(ctx, id, s, dryRun), nil
+}
+
+// LinkUser links an existing tenant user to a directory entry, so that the next run syncs the
+// user with the entry instead of skipping it
+func (m *Manager) LinkUser(ctx context.Context, id, userID, directoryID string) error {
+	m.mu.Lock()
+	s, ok := m.syncers[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("ldap sync %s is not running", id)
+	}
+	if userID == "" || directoryID == "" {
+		return fmt.Errorf("user_id and directory_id are required")
+	}
+	return m.store.LinkUser(ctx, id, s.cfg.TenantID, userID, strings.ToLower(strings.TrimSpace(directoryID)))
+}
+
+// History returns the latest runs of an integration, newest first
+func (m *Manager) History(id string, limit int) []*Run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	runs := m.history[id]
+	if limit <= 0 || limit > len(runs) {
+		limit = len(runs)
+	}
+	out := make([]*Run, 0, limit)
+	for i := len(runs) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, runs[i])
+	}
+	return out
+}
+
+// Remove stops the schedule of an integration and forgets its runs
+func (m *Manager) Remove(id string) {
+	m.Stop(id)
+	m.mu.Lock()
+	delete(m.history, id)
+	m.mu.Unlock()
+}
+
+// run reads the directory, plans the changes and applies them unless dryRun is set
+func (m *Manager) run(ctx context.Context, id string, s *syncer, dryRun bool) *Run {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	run := &Run{IntegrationID: id, StartedAt: time.Now(), DryRun: dryRun}
+	err := func() error {
+		snapshot, err := m.directory(s.cfg).Read(ctx)
+		if err != nil {
+			return err
+		}
+		run.DirectoryUsers = len(snapshot.Users)
+		run.DirectoryGroups = len(snapshot.Groups)
+
+		state, err := m.store.Load(ctx, id, s.cfg.TenantID)
+		if err != nil {
+			return err
+		}
+		plan := Reconcile(s.cfg, snapshot, state)
+		run.Summary = plan.Summary()
+		run.Warnings = plan.Warnings
+		if dryRun {
+			return nil
+		}
+
+		warnings, err := m.store.Apply(ctx, id, s.cfg, plan)
+		run.Warnings = append(run.Warnings, warnings...)
+		return err
+	}()
+
+	run.FinishedAt = time.Now()
+	run.Status = RunSucceeded
+	if err != nil {
+		run.Status = RunFailed
+		run.Error = err.Error()
+	} else if m.logger != nil && !dryRun {
+		m.logger.Infof("LDAP sync %s: %d users created, %d updated, %d disabled, %d groups created, %d roles granted, %d revoked",
+			id, run.Summary.UsersCreated, run.Summary.UsersUpdated, run.Summary.UsersDisabled,
+			run.Summary.GroupsCreated, run.Summary.RolesGranted, run.Summary.RolesRevoked)
+	}
+
+	if err := m.store.RecordRun(ctx, s.cfg.TenantID, run); err != nil && m.logger != nil {
+		m.logger.Warnf("Failed to record LDAP sync run of %s: %v", id, err)
+	}
+
+	m.mu.Lock()
+	runs := append(m.history[id], run)
+	if len(runs) > historySize {
+		runs = runs[len(runs)-historySize:]
+	}
+	m.history[id] = runs
+	m.mu.Unlock()
+
+	return run
+}
@@ -0,0 +1,360 @@
+package ldapsync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LocalUser is a tenant user
+type LocalUser struct {
+	ID      string
+	Email   string
+	Name    string
+	Enabled bool
+}
+
+// LocalGroup is a tenant group
+type LocalGroup struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+// TenantState is the part of a tenant the sync reconciles
+type TenantState struct {
+	// Users are keyed by lower-cased email
+	Users map[string]LocalUser
+	// Groups are keyed by name
+	Groups map[string]LocalGroup
+	// Roles maps role names to IDs
+	Roles map[string]string
+	// LinkedUsers maps the directory IDs of entries to the IDs of the users linked to them
+	LinkedUsers map[string]string
+	// TrackedUsers and TrackedGroups are the IDs of objects managed by this integration
+	TrackedUsers  map[string]bool
+	TrackedGroups map[string]bool
+	// UserGroups and UserRoles map user IDs to the group and role IDs assigned to them
+	UserGroups map[string]map[string]bool
+	UserRoles  map[string]map[string]bool
+}
+
+// UserUpdate changes the name or enabled state of an existing user
+type UserUpdate struct {
+	ID      string
+	Email   string
+	Name    string
+	Enabled bool
+}
+
+// Link records a tenant object as managed by the integration
+type Link struct {
+	ID string
+	DN string
+	// DirectoryID is the stable identifier of the directory entry, empty for groups
+	DirectoryID string
+}
+
+// Assignment adds or removes a user to a group (by name) or role (by ID).
+// Users are referenced by email since users created by the same run have no ID yet.
+type Assignment struct {
+	Email  string
+	Target string
+}
+
+// Plan lists the changes that bring a tenant in line with the directory
+type Plan struct {
+	CreateUsers  []DirectoryUser
+	UpdateUsers  []UserUpdate
+	LinkUsers    []Link
+	CreateGroups []DirectoryGroup
+	UpdateGroups []LocalGroup
+	LinkGroups   []Link
+
+	AddGroupMembers    []Assignment
+	RemoveGroupMembers []Assignment
+	AddUserRoles       []Assignment
+	RemoveUserRoles    []Assignment
+
+	Warnings []string
+}
+
+// Summary counts the changes of a plan
+type Summary struct {
+	UsersCreated   int `json:"users_created"`
+	UsersUpdated   int `json:"users_updated"`
+	UsersDisabled  int `json:"users_disabled"`
+	GroupsCreated  int `json:"groups_created"`
+	GroupsUpdated  int `json:"groups_updated"`
+	MembersAdded   int `json:"members_added"`
+	MembersRemoved int `json:"members_removed"`
+	RolesGranted   int `json:"roles_granted"`
+	RolesRevoked   int `json:"roles_revoked"`
+}
+
+// Summary counts the changes of the plan
+func (p *Plan) Summary() Summary {
+	s := Summary{
+		UsersCreated:   len(p.CreateUsers),
+		GroupsCreated:  len(p.CreateGroups),
+		GroupsUpdated:  len(p.UpdateGroups),
+		MembersAdded:   len(p.AddGroupMembers),
+		MembersRemoved: len(p.RemoveGroupMembers),
+		RolesGranted:   len(p.AddUserRoles),
+		RolesRevoked:   len(p.RemoveUserRoles),
+	}
+	for _, u := range p.UpdateUsers {
+		if u.Enabled {
+			s.UsersUpdated++
+		} else {
+			s.UsersDisabled++
+		}
+	}
+	return s
+}
+
+// Reconcile compares the directory with the tenant and plans the changes:
+//   - directory users are matched to tenant users through the links recorded by earlier runs,
+//     which are keyed by the entries' stable IDs, and kept enabled or disabled like their
+//     entry; linked users missing from the directory are disabled when DisableMissing is set
+//   - unlinked directory users are created, unless a tenant user already has their email: such
+//     an account is only synced once an administrator links it to the entry, since anyone able
+//     to set the email of a directory entry could otherwise take it over
+//   - directory groups are created or linked by name, and their membership follows the directory
+//     for synced users; other members added in reDB are left alone
+//   - managed roles (default roles and role mapping targets) of synced users follow the mappings
+//
+// The owner of the integration is never disabled and never loses roles.
+func Reconcile(cfg *Config, snapshot *Snapshot, state *TenantState) *Plan {
+	plan := &Plan{}
+
+	usersByID := make(map[string]LocalUser, len(state.Users))
+	for _, local := range state.Users {
+		usersByID[local.ID] = local
+	}
+
+	// Users present in the directory, by the email of the tenant user they are linked to or will
+	// be created with, and by DN
+	dirUsers := map[string]DirectoryUser{}
+	emailByDN := map[string]string{}
+	for _, u := range snapshot.Users {
+		if u.ID == "" {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("skipped %s: the entry has no %s", u.DN, cfg.IDAttribute))
+			continue
+		}
+		email, linked := u.Email, false
+		if userID, ok := state.LinkedUsers[u.ID]; ok {
+			if local, ok := usersByID[userID]; ok {
+				email, linked = strings.ToLower(local.Email), true
+			}
+		}
+		if _, exists := state.Users[email]; exists && !linked {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("skipped %s: user %s already exists and must be linked to the entry by an administrator", u.DN, email))
+			continue
+		}
+		if _, dup := dirUsers[email]; dup {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("skipped %s: email %s is already used by another directory entry", u.DN, email))
+			continue
+		}
+		dirUsers[email] = u
+		emailByDN[normalizeDN(u.DN)] = email
+	}
+	owner := ""
+	for email, local := range state.Users {
+		if local.ID == cfg.OwnerID {
+			owner = email
+		}
+	}
+
+	// managed holds the emails of the users whose memberships and roles the sync controls
+	managed := map[string]bool{}
+	for _, email := range sortedKeys(dirUsers) {
+		u := dirUsers[email]
+		managed[email] = true
+		enabled := !u.Disabled
+		if email == owner && !enabled {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("owner %s is disabled in the directory but stays enabled", email))
+			enabled = true
+		}
+
+		local, exists := state.Users[email]
+		if !exists {
+			u.Disabled = !enabled
+			plan.CreateUsers = append(plan.CreateUsers, u)
+			continue
+		}
+		plan.LinkUsers = append(plan.LinkUsers, Link{ID: local.ID, DN: u.DN, DirectoryID: u.ID})
+		name := u.Name
+		if name == "" {
+			name = local.Name
+		}
+		if name != local.Name || enabled != local.Enabled {
+			plan.UpdateUsers = append(plan.UpdateUsers, UserUpdate{ID: local.ID, Email: email, Name: name, Enabled: enabled})
+		}
+	}
+
+	for _, email := range sortedKeys(state.Users) {
+		local := state.Users[email]
+		if _, present := dirUsers[email]; present || !state.TrackedUsers[local.ID] || email == owner {
+			continue
+		}
+		// Users removed from the directory lose their synced groups and managed roles
+		managed[email] = true
+		if cfg.DisableMissing && local.Enabled {
+			plan.UpdateUsers = append(plan.UpdateUsers, UserUpdate{ID: local.ID, Email: email, Name: local.Name, Enabled: false})
+		}
+	}
+
+	// Directory groups resolved to the emails of their members
+	groupMembers := map[string]map[string]bool{}
+	var selected []DirectoryGroup
+	for _, g := range snapshot.Groups {
+		if g.Name == "" {
+			continue
+		}
+		members := map[string]bool{}
+		for _, dn := range g.Members {
+			if email, ok := emailByDN[normalizeDN(dn)]; ok {
+				members[email] = true
+			}
+		}
+		groupMembers[normalizeDN(g.DN)] = members
+		groupMembers[strings.ToLower(g.Name)] = members
+		if matchesAny(g, cfg.GroupFilterNames) {
+			selected = append(selected, g)
+		}
+	}
+
+	if cfg.SyncGroups {
+		planGroups(cfg, plan, state, selected, groupMembers, managed)
+	}
+	planRoles(cfg, plan, state, dirUsers, groupMembers, managed, owner)
+
+	return plan
+}
+
+// planGroups plans the creation of groups and the changes of their membership
+func planGroups(cfg *Config, plan *Plan, state *TenantState, groups []DirectoryGroup, groupMembers map[string]map[string]bool, managed map[string]bool) {
+	for _, g := range groups {
+		desired := groupMembers[normalizeDN(g.DN)]
+		current := map[string]bool{}
+
+		local, exists := state.Groups[g.Name]
+		if !exists {
+			plan.CreateGroups = append(plan.CreateGroups, g)
+		} else {
+			plan.LinkGroups = append(plan.LinkGroups, Link{ID: local.ID, DN: g.DN})
+			if g.Description != "" && g.Description != local.Description {
+				plan.UpdateGroups = append(plan.UpdateGroups, LocalGroup{ID: local.ID, Name: local.Name, Description: g.Description})
+			}
+			for email, u := range state.Users {
+				if managed[email] && state.UserGroups[u.ID][local.ID] {
+					current[email] = true
+				}
+			}
+		}
+
+		for _, email := range sortedKeys(desired) {
+			if !current[email] {
+				plan.AddGroupMembers = append(plan.AddGroupMembers, Assignment{Email: email, Target: g.Name})
+			}
+		}
+		for _, email := range sortedKeys(current) {
+			if !desired[email] {
+				plan.RemoveGroupMembers = append(plan.RemoveGroupMembers, Assignment{Email: email, Target: g.Name})
+			}
+		}
+	}
+}
+
+// planRoles plans the grants and revocations of the managed roles
+func planRoles(cfg *Config, plan *Plan, state *TenantState, dirUsers map[string]DirectoryUser, groupMembers map[string]map[string]bool, managed map[string]bool, owner string) {
+	roleIDs := map[string]bool{}
+	desired := map[string]map[string]bool{}
+	grant := func(email, roleID string) {
+		if desired[email] == nil {
+			desired[email] = map[string]bool{}
+		}
+		desired[email][roleID] = true
+	}
+
+	for _, name := range cfg.ManagedRoles() {
+		id, ok := state.Roles[name]
+		if !ok {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("role %s does not exist in the tenant", name))
+			continue
+		}
+		roleIDs[id] = true
+	}
+
+	for _, name := range cfg.DefaultRoles {
+		if id, ok := state.Roles[name]; ok {
+			for email := range dirUsers {
+				grant(email, id)
+			}
+		}
+	}
+	for _, m := range cfg.RoleMappings {
+		id, ok := state.Roles[m.Role]
+		if !ok {
+			continue
+		}
+		members, ok := groupMembers[normalizeDN(m.Group)]
+		if !ok {
+			members, ok = groupMembers[strings.ToLower(m.Group)]
+		}
+		if !ok {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("group %s of the %s role mapping was not found in the directory", m.Group, m.Role))
+			continue
+		}
+		for email := range members {
+			grant(email, id)
+		}
+	}
+
+	for _, email := range sortedKeys(managed) {
+		current := map[string]bool{}
+		if local, ok := state.Users[email]; ok {
+			for roleID := range state.UserRoles[local.ID] {
+				if roleIDs[roleID] {
+					current[roleID] = true
+				}
+			}
+		}
+		for _, roleID := range sortedKeys(desired[email]) {
+			if !current[roleID] {
+				plan.AddUserRoles = append(plan.AddUserRoles, Assignment{Email: email, Target: roleID})
+			}
+		}
+		if email == owner {
+			continue
+		}
+		for _, roleID := range sortedKeys(current) {
+			if !desired[email][roleID] {
+				plan.RemoveUserRoles = append(plan.RemoveUserRoles, Assignment{Email: email, Target: roleID})
+			}
+		}
+	}
+}
+
+// matchesAny reports whether a group is selected by name or DN; an empty selection matches all groups
+func matchesAny(g DirectoryGroup, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, g.Name) || normalizeDN(n) == normalizeDN(g.DN) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
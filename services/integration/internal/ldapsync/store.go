@@ -0,0 +1,407 @@
+package ldapsync
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// runOperation is the operation recorded in integration_runs for sync runs
+const runOperation = "LDAP_SYNC"
+
+// Object types recorded in integration_directory_objects
+const (
+	objectTypeUser  = "user"
+	objectTypeGroup = "group"
+)
+
+// Store loads and changes the users, groups and role assignments of a tenant
+type Store interface {
+	Load(ctx context.Context, integrationID, tenantID string) (*TenantState, error)
+	// Apply carries out a plan and returns warnings about changes that were skipped
+	Apply(ctx context.Context, integrationID string, cfg *Config, plan *Plan) ([]string, error)
+	// LinkUser links an existing tenant user to the directory entry with the given ID, replacing
+	// any other link of the user or the entry
+	LinkUser(ctx context.Context, integrationID, tenantID, userID, directoryID string) error
+	// RecordRun adds a finished run and its warnings to the run history of the integration
+	RecordRun(ctx context.Context, tenantID string, run *Run) error
+}
+
+// PostgresStore is a Store on the reDB database
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore creates a store on the given pool
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+// Load reads the tenant's users, groups, roles and assignments and the objects tracked by the integration
+func (s *PostgresStore) Load(ctx context.Context, integrationID, tenantID string) (*TenantState, error) {
+	state := &TenantState{
+		Users:         map[string]LocalUser{},
+		Groups:        map[string]LocalGroup{},
+		Roles:         map[string]string{},
+		LinkedUsers:   map[string]string{},
+		TrackedUsers:  map[string]bool{},
+		TrackedGroups: map[string]bool{},
+		UserGroups:    map[string]map[string]bool{},
+		UserRoles:     map[string]map[string]bool{},
+	}
+
+	err := s.query(ctx, `SELECT user_id, user_email, user_name, user_enabled FROM users WHERE tenant_id = $1`,
+		[]any{tenantID}, func(rows pgx.Rows) error {
+			var u LocalUser
+			if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Enabled); err != nil {
+				return err
+			}
+			state.Users[strings.ToLower(u.Email)] = u
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	err = s.query(ctx, `SELECT group_id, group_name, group_description FROM groups WHERE tenant_id = $1`,
+		[]any{tenantID}, func(rows pgx.Rows) error {
+			var g LocalGroup
+			if err := rows.Scan(&g.ID, &g.Name, &g.Description); err != nil {
+				return err
+			}
+			state.Groups[g.Name] = g
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load groups: %w", err)
+	}
+
+	err = s.query(ctx, `SELECT role_id, role_name FROM roles WHERE tenant_id = $1`,
+		[]any{tenantID}, func(rows pgx.Rows) error {
+			var id, name string
+			if err := rows.Scan(&id, &name); err != nil {
+				return err
+			}
+			state.Roles[name] = id
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	err = s.query(ctx, `SELECT object_type, object_id, directory_id FROM integration_directory_objects WHERE integration_id = $1`,
+		[]any{integrationID}, func(rows pgx.Rows) error {
+			var objectType, id, directoryID string
+			if err := rows.Scan(&objectType, &id, &directoryID); err != nil {
+				return err
+			}
+			switch objectType {
+			case objectTypeUser:
+				state.TrackedUsers[id] = true
+				if directoryID != "" {
+					state.LinkedUsers[directoryID] = id
+				}
+			case objectTypeGroup:
+				state.TrackedGroups[id] = true
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracked objects: %w", err)
+	}
+
+	err = s.query(ctx, `SELECT user_id, group_id FROM user_groups WHERE tenant_id = $1`,
+		[]any{tenantID}, func(rows pgx.Rows) error {
+			return scanPair(rows, state.UserGroups)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load group memberships: %w", err)
+	}
+
+	err = s.query(ctx, `SELECT user_id, role_id FROM user_roles WHERE tenant_id = $1`,
+		[]any{tenantID}, func(rows pgx.Rows) error {
+			return scanPair(rows, state.UserRoles)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role assignments: %w", err)
+	}
+
+	return state, nil
+}
+
+// Apply carries out a plan in a single transaction
+func (s *PostgresStore) Apply(ctx context.Context, integrationID string, cfg *Config, plan *Plan) ([]string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var warnings []string
+	userIDs := map[string]string{}
+	groupIDs := map[string]string{}
+
+	// Existing users and groups referenced by assignments
+	rows, err := tx.Query(ctx, `SELECT user_id, user_email FROM users WHERE tenant_id = $1`, cfg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id, email string
+		if err := rows.Scan(&id, &email); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		userIDs[strings.ToLower(email)] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows, err = tx.Query(ctx, `SELECT group_id, group_name FROM groups WHERE tenant_id = $1`, cfg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		groupIDs[name] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, u := range plan.CreateUsers {
+		hash, err := unusablePasswordHash()
+		if err != nil {
+			return nil, err
+		}
+		var id string
+		err = tx.QueryRow(ctx, `INSERT INTO users (tenant_id, user_email, user_name, user_password_hash, user_enabled)
+			VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_email) DO NOTHING RETURNING user_id`,
+			cfg.TenantID, u.Email, u.Name, hash, !u.Disabled).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			warnings = append(warnings, fmt.Sprintf("skipped %s: the email is used by a user of another tenant", u.Email))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user %s: %w", u.Email, err)
+		}
+		userIDs[u.Email] = id
+		plan.LinkUsers = append(plan.LinkUsers, Link{ID: id, DN: u.DN, DirectoryID: u.ID})
+	}
+
+	for _, u := range plan.UpdateUsers {
+		if _, err := tx.Exec(ctx, `UPDATE users SET user_name = $1, user_enabled = $2, updated = CURRENT_TIMESTAMP
+			WHERE user_id = $3 AND tenant_id = $4`, u.Name, u.Enabled, u.ID, cfg.TenantID); err != nil {
+			return nil, fmt.Errorf("failed to update user %s: %w", u.Email, err)
+		}
+	}
+
+	for _, g := range plan.CreateGroups {
+		var id string
+		if err := tx.QueryRow(ctx, `INSERT INTO groups (tenant_id, group_name, group_description, owner_id)
+			VALUES ($1, $2, $3, $4) RETURNING group_id`,
+			cfg.TenantID, g.Name, g.Description, cfg.OwnerID).Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create group %s: %w", g.Name, err)
+		}
+		groupIDs[g.Name] = id
+		plan.LinkGroups = append(plan.LinkGroups, Link{ID: id, DN: g.DN})
+	}
+
+	for _, g := range plan.UpdateGroups {
+		if _, err := tx.Exec(ctx, `UPDATE groups SET group_description = $1, updated = CURRENT_TIMESTAMP
+			WHERE group_id = $2 AND tenant_id = $3`, g.Description, g.ID, cfg.TenantID); err != nil {
+			return nil, fmt.Errorf("failed to update group %s: %w", g.Name, err)
+		}
+	}
+
+	for _, link := range plan.LinkUsers {
+		if err := track(ctx, tx, integrationID, objectTypeUser, link); err != nil {
+			return nil, err
+		}
+	}
+	for _, link := range plan.LinkGroups {
+		if err := track(ctx, tx, integrationID, objectTypeGroup, link); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range plan.AddGroupMembers {
+		userID, groupID := userIDs[a.Email], groupIDs[a.Target]
+		if userID == "" || groupID == "" {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO user_groups (tenant_id, user_id, group_id, granted_by, owner_id)
+			VALUES ($1, $2, $3, $4, $4) ON CONFLICT DO NOTHING`, cfg.TenantID, userID, groupID, cfg.OwnerID); err != nil {
+			return nil, fmt.Errorf("failed to add %s to group %s: %w", a.Email, a.Target, err)
+		}
+	}
+	for _, a := range plan.RemoveGroupMembers {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_groups WHERE tenant_id = $1 AND user_id = $2 AND group_id = $3`,
+			cfg.TenantID, userIDs[a.Email], groupIDs[a.Target]); err != nil {
+			return nil, fmt.Errorf("failed to remove %s from group %s: %w", a.Email, a.Target, err)
+		}
+	}
+
+	for _, a := range plan.AddUserRoles {
+		userID := userIDs[a.Email]
+		if userID == "" {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO user_roles (tenant_id, user_id, role_id, granted_by, owner_id)
+			VALUES ($1, $2, $3, $4, $4) ON CONFLICT DO NOTHING`, cfg.TenantID, userID, a.Target, cfg.OwnerID); err != nil {
+			return nil, fmt.Errorf("failed to grant role %s to %s: %w", a.Target, a.Email, err)
+		}
+	}
+	for _, a := range plan.RemoveUserRoles {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE tenant_id = $1 AND user_id = $2 AND role_id = $3`,
+			cfg.TenantID, userIDs[a.Email], a.Target); err != nil {
+			return nil, fmt.Errorf("failed to revoke role %s from %s: %w", a.Target, a.Email, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (s *PostgresStore) query(ctx context.Context, sql string, args []any, scan func(pgx.Rows) error) error {
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanPair(rows pgx.Rows, into map[string]map[string]bool) error {
+	var a, b string
+	if err := rows.Scan(&a, &b); err != nil {
+		return err
+	}
+	if into[a] == nil {
+		into[a] = map[string]bool{}
+	}
+	into[a][b] = true
+	return nil
+}
+
+// LinkUser links an existing tenant user to a directory entry
+func (s *PostgresStore) LinkUser(ctx context.Context, integrationID, tenantID, userID, directoryID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND tenant_id = $2)`,
+		userID, tenantID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("user %s not found in the tenant", userID)
+	}
+	if err := track(ctx, tx, integrationID, objectTypeUser, Link{ID: userID, DirectoryID: directoryID}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// RecordRun adds a run to integration_runs and its warnings and error to integration_run_logs
+func (s *PostgresStore) RecordRun(ctx context.Context, tenantID string, run *Run) error {
+	summary, err := json.Marshal(map[string]any{
+		"dry_run":          run.DryRun,
+		"directory_users":  run.DirectoryUsers,
+		"directory_groups": run.DirectoryGroups,
+		"summary":          run.Summary,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var runID string
+	err = tx.QueryRow(ctx, `INSERT INTO integration_runs (integration_id, tenant_id, operation, status, summary, error_message, started, created)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING run_id`,
+		run.IntegrationID, tenantID, runOperation, string(run.Status), summary, run.Error, run.StartedAt, run.FinishedAt).Scan(&runID)
+	if err != nil {
+		return fmt.Errorf("failed to record run: %w", err)
+	}
+
+	type logLine struct{ level, message string }
+	lines := make([]logLine, 0, len(run.Warnings)+1)
+	for _, w := range run.Warnings {
+		lines = append(lines, logLine{"warning", w})
+	}
+	if run.Error != "" {
+		lines = append(lines, logLine{"error", run.Error})
+	}
+	for _, line := range lines {
+		if _, err := tx.Exec(ctx, `INSERT INTO integration_run_logs (run_id, integration_id, tenant_id, log_level, message, created)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			runID, run.IntegrationID, tenantID, line.level, line.message, run.FinishedAt); err != nil {
+			return fmt.Errorf("failed to record run log: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// track records an object as managed by the integration. A link to a directory entry replaces
+// the entry's link to another object, e.g. to a user that has since been deleted.
+func track(ctx context.Context, tx pgx.Tx, integrationID, objectType string, link Link) error {
+	if link.DirectoryID != "" {
+		if _, err := tx.Exec(ctx, `DELETE FROM integration_directory_objects
+			WHERE integration_id = $1 AND object_type = $2 AND directory_id = $3 AND object_id <> $4`,
+			integrationID, objectType, link.DirectoryID, link.ID); err != nil {
+			return fmt.Errorf("failed to unlink %s entry %s: %w", objectType, link.DirectoryID, err)
+		}
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO integration_directory_objects (integration_id, object_type, object_id, directory_dn, directory_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (integration_id, object_type, object_id) DO UPDATE
+		SET directory_dn = EXCLUDED.directory_dn, directory_id = EXCLUDED.directory_id, synced = CURRENT_TIMESTAMP`,
+		integrationID, objectType, link.ID, link.DN, link.DirectoryID)
+	if err != nil {
+		return fmt.Errorf("failed to track %s %s: %w", objectType, link.ID, err)
+	}
+	return nil
+}
+
+// unusablePasswordHash returns the hash of a random, discarded password. The directory
+// password is not readable, so synced users can sign in once an administrator sets a password.
+func unusablePasswordHash() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/logger"
 	"github.com/redbco/redb-open/pkg/models"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"github.com/redbco/redb-open/services/mcpserver/internal/audit"
 	"github.com/redbco/redb-open/services/mcpserver/internal/auth"
 	"github.com/redbco/redb-open/services/mcpserver/internal/prompts"
@@ -22,7 +23,6 @@ import (
 	"github.com/redbco/redb-open/services/mcpserver/internal/resources"
 	"github.com/redbco/redb-open/services/mcpserver/internal/tools"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Engine handles MCP server core functionality
@@ -408,7 +408,7 @@ func (e *Engine) initAnchorClient() error {
 	if addr == "" {
 		addr = grpcconfig.GetServiceAddress(e.config, "anchor")
 	}
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(addr, spiffe.DialOption())
 	if err != nil {
 		return err
 	}
@@ -422,7 +422,7 @@ func (e *Engine) initSecurityClient() error {
 	if addr == "" {
 		addr = grpcconfig.GetServiceAddress(e.config, "security")
 	}
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(addr, spiffe.DialOption())
 	if err != nil {
 		return err
 	}
@@ -14,10 +14,10 @@ import (
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/logger"
 	"github.com/redbco/redb-open/pkg/models"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	"github.com/redbco/redb-open/services/mcpserver/internal/auth"
 	"github.com/redbco/redb-open/services/mcpserver/internal/protocol"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Handler handles MCP resource operations
@@ -665,7 +665,7 @@ func (h *Handler) getTransformationClient(ctx context.Context) (transformationv1
 
 	// Connect to transformation service without blocking
 	// The connection is established lazily when first used
-	conn, err := grpc.Dial(transformationAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(transformationAddr, spiffe.DialOption())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to transformation service at %s: %w", transformationAddr, err)
 	}
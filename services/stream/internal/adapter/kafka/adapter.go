@@ -2,6 +2,8 @@ package kafka
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 
 	"github.com/redbco/redb-open/pkg/stream/adapter"
 	"github.com/redbco/redb-open/pkg/streamcapabilities"
@@ -28,15 +30,21 @@ func (a *Adapter) Capabilities() streamcapabilities.Capability {
 }
 
 func (a *Adapter) Connect(ctx context.Context, config adapter.ConnectionConfig) (adapter.Connection, error) {
+	tlsConfig, err := config.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
 	return &Connection{
-		id:     config.ID,
-		config: config,
+		id:        config.ID,
+		config:    config,
+		tlsConfig: tlsConfig,
 	}, nil
 }
 
 type Connection struct {
-	id     string
-	config adapter.ConnectionConfig
+	id        string
+	config    adapter.ConnectionConfig
+	tlsConfig *tls.Config // nil without TLS; client certificates for mutual TLS
 }
 
 func (c *Connection) ID() string {
@@ -64,7 +72,7 @@ func (c *Connection) ProducerOperations() adapter.ProducerOperator {
 }
 
 func (c *Connection) ConsumerOperations() adapter.ConsumerOperator {
-	return &Consumer{}
+	return &Consumer{conn: c}
 }
 
 func (c *Connection) AdminOperations() adapter.AdminOperator {
@@ -115,68 +115,7 @@ func (r *Repository) GetStreamConfigByID(ctx context.Context, streamID string) (
 
 // ToConnectionConfig converts a StreamConfig to an adapter.ConnectionConfig
 func (sc *StreamConfig) ToConnectionConfig() *adapter.ConnectionConfig {
-	config := adapter.NewConnectionConfig(sc.ID, streamcapabilities.StreamPlatform(sc.Platform))
-
-	// Parse connection config fields
-	if brokers, ok := sc.ConnectionConfig["brokers"].([]interface{}); ok {
-		config.Brokers = make([]string, len(brokers))
-		for i, b := range brokers {
-			if str, ok := b.(string); ok {
-				config.Brokers[i] = str
-			}
-		}
-	}
-
-	if region, ok := sc.ConnectionConfig["region"].(string); ok {
-		config.Region = region
-	}
-
-	if project, ok := sc.ConnectionConfig["project"].(string); ok {
-		config.Project = project
-	}
-
-	if namespace, ok := sc.ConnectionConfig["namespace"].(string); ok {
-		config.Namespace = namespace
-	}
-
-	if endpoint, ok := sc.ConnectionConfig["endpoint"].(string); ok {
-		config.Endpoint = endpoint
-	}
-
-	// Authentication
-	if username, ok := sc.ConnectionConfig["username"].(string); ok {
-		config.Username = username
-	}
-
-	if password, ok := sc.ConnectionConfig["password"].(string); ok {
-		config.Password = password
-	}
-
-	if saslMech, ok := sc.ConnectionConfig["sasl_mechanism"].(string); ok {
-		config.SASLMechanism = saslMech
-	}
-
-	if tlsEnabled, ok := sc.ConnectionConfig["tls_enabled"].(bool); ok {
-		config.TLSEnabled = tlsEnabled
-	}
-
-	if tlsSkipVerify, ok := sc.ConnectionConfig["tls_skip_verify"].(bool); ok {
-		config.TLSSkipVerify = tlsSkipVerify
-	}
-
-	// Consumer config
-	if groupID, ok := sc.ConnectionConfig["group_id"].(string); ok {
-		config.GroupID = groupID
-	}
-
-	// Copy additional configuration
-	for k, v := range sc.ConnectionConfig {
-		if str, ok := v.(string); ok {
-			config.Configuration[k] = str
-		}
-	}
-
-	return config
+	return adapter.ConnectionConfigFromMap(sc.ID, streamcapabilities.StreamPlatform(sc.Platform), sc.ConnectionConfig)
 }
 
 // UpdateStreamConnectionStatus updates the connection status of a stream
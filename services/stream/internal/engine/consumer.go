@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redbco/redb-open/pkg/stream/adapter"
+	"github.com/redbco/redb-open/services/stream/internal/state"
+)
+
+// RunLedgerConsumer consumes topics of a connected stream and applies them to a target
+// through writer, committing offsets only after each batch and its positions are durable
+// in the target-side ledger. It blocks until ctx is cancelled or consumption fails.
+func (e *Engine) RunLedgerConsumer(ctx context.Context, streamID string, topics []string, ledger adapter.OffsetLedger, writer adapter.BatchWriter, opts adapter.CommitCoordinatorOptions) error {
+	conn, exists := state.GetInstance().GetConnection(streamID)
+	if !exists {
+		return fmt.Errorf("stream %s is not connected", streamID)
+	}
+
+	if opts.OnCommitError == nil {
+		opts.OnCommitError = func(err error) {
+			if e.logger != nil {
+				e.logger.Warnf("Stream %s: %v", streamID, err)
+			}
+		}
+	}
+
+	coordinator, err := adapter.NewCommitCoordinator(conn, ledger, writer, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create commit coordinator for stream %s: %w", streamID, err)
+	}
+
+	if e.logger != nil {
+		e.logger.Infof("Starting ledger consumer for stream %s on topics %v", streamID, topics)
+	}
+
+	err = coordinator.Run(ctx, topics)
+	stats := coordinator.Stats()
+	if e.logger != nil {
+		e.logger.Infof("Ledger consumer for stream %s stopped: %d batches, %d messages applied, %d duplicates skipped, %d commit failures",
+			streamID, stats.BatchesApplied, stats.MessagesApplied, stats.DuplicatesSkipped, stats.CommitFailures)
+	}
+	if err != nil {
+		return fmt.Errorf("ledger consumer for stream %s failed: %w", streamID, err)
+	}
+	return nil
+}
@@ -13,10 +13,10 @@ import (
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/grpcconfig"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/spiffe"
 	internalconfig "github.com/redbco/redb-open/services/stream/internal/config"
 	"github.com/redbco/redb-open/services/stream/internal/state"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Engine struct {
@@ -111,14 +111,14 @@ func (e *Engine) Start(ctx context.Context) error {
 	if !e.standalone {
 		// Initialize gRPC connection to Core service
 		coreAddr := e.getServiceAddress("core")
-		e.coreConn, err = grpc.Dial(coreAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		e.coreConn, err = grpc.Dial(coreAddr, spiffe.DialOption())
 		if err != nil {
 			return fmt.Errorf("failed to connect to Core service: %w", err)
 		}
 
 		// Initialize gRPC connection to UnifiedModel service
 		umAddr := e.getServiceAddress("unifiedmodel")
-		e.umConn, err = grpc.Dial(umAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		e.umConn, err = grpc.Dial(umAddr, spiffe.DialOption())
 		if err != nil {
 			return fmt.Errorf("failed to connect to UnifiedModel service: %w", err)
 		}
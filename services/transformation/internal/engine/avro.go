@@ -0,0 +1,378 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// avroSchema is a parsed Avro schema node
+type avroSchema struct {
+	Type      string
+	Name      string
+	Fields    []avroField
+	Symbols   []string
+	Items     *avroSchema
+	Values    *avroSchema
+	Branches  []*avroSchema
+	Size      int
+	Logical   string
+	reference string // named type reference resolved after parsing
+}
+
+type avroField struct {
+	Name   string
+	Schema *avroSchema
+}
+
+// parseAvroSchema parses an Avro schema in its JSON representation
+func parseAvroSchema(schemaJSON string) (*avroSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(schemaJSON), &raw); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %v", err)
+	}
+
+	named := make(map[string]*avroSchema)
+	schema, err := parseAvroNode(raw, "", named)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveAvroReferences(schema, named, make(map[*avroSchema]bool)); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func parseAvroNode(raw interface{}, namespace string, named map[string]*avroSchema) (*avroSchema, error) {
+	switch node := raw.(type) {
+	case string:
+		switch node {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{Type: node}, nil
+		default:
+			return &avroSchema{reference: qualifyAvroName(node, namespace)}, nil
+		}
+
+	case []interface{}:
+		union := &avroSchema{Type: "union"}
+		for _, branch := range node {
+			parsed, err := parseAvroNode(branch, namespace, named)
+			if err != nil {
+				return nil, err
+			}
+			union.Branches = append(union.Branches, parsed)
+		}
+		return union, nil
+
+	case map[string]interface{}:
+		typeName, _ := node["type"].(string)
+		logical, _ := node["logicalType"].(string)
+
+		if ns, ok := node["namespace"].(string); ok && ns != "" {
+			namespace = ns
+		}
+		name, _ := node["name"].(string)
+		fullName := qualifyAvroName(name, namespace)
+		if idx := strings.LastIndex(fullName, "."); idx >= 0 {
+			namespace = fullName[:idx]
+		}
+
+		switch typeName {
+		case "record", "error":
+			schema := &avroSchema{Type: "record", Name: fullName}
+			named[fullName] = schema
+			fields, _ := node["fields"].([]interface{})
+			for _, f := range fields {
+				fieldMap, ok := f.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("invalid field in record %s", fullName)
+				}
+				fieldName, _ := fieldMap["name"].(string)
+				fieldSchema, err := parseAvroNode(fieldMap["type"], namespace, named)
+				if err != nil {
+					return nil, fmt.Errorf("field %s.%s: %w", fullName, fieldName, err)
+				}
+				schema.Fields = append(schema.Fields, avroField{Name: fieldName, Schema: fieldSchema})
+			}
+			return schema, nil
+
+		case "enum":
+			schema := &avroSchema{Type: "enum", Name: fullName}
+			symbols, _ := node["symbols"].([]interface{})
+			for _, s := range symbols {
+				if str, ok := s.(string); ok {
+					schema.Symbols = append(schema.Symbols, str)
+				}
+			}
+			named[fullName] = schema
+			return schema, nil
+
+		case "fixed":
+			size, _ := node["size"].(float64)
+			schema := &avroSchema{Type: "fixed", Name: fullName, Size: int(size), Logical: logical}
+			named[fullName] = schema
+			return schema, nil
+
+		case "array":
+			items, err := parseAvroNode(node["items"], namespace, named)
+			if err != nil {
+				return nil, err
+			}
+			return &avroSchema{Type: "array", Items: items}, nil
+
+		case "map":
+			values, err := parseAvroNode(node["values"], namespace, named)
+			if err != nil {
+				return nil, err
+			}
+			return &avroSchema{Type: "map", Values: values}, nil
+
+		default:
+			// Primitive type wrapped in an object, possibly with a logical type
+			schema, err := parseAvroNode(node["type"], namespace, named)
+			if err != nil {
+				return nil, err
+			}
+			schema.Logical = logical
+			return schema, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid Avro schema node: %v", raw)
+	}
+}
+
+func resolveAvroReferences(schema *avroSchema, named map[string]*avroSchema, seen map[*avroSchema]bool) error {
+	if schema == nil || seen[schema] {
+		return nil
+	}
+	seen[schema] = true
+
+	if schema.reference != "" {
+		target, exists := named[schema.reference]
+		if !exists {
+			return fmt.Errorf("unknown Avro type: %s", schema.reference)
+		}
+		*schema = *target
+		return nil
+	}
+
+	for i := range schema.Fields {
+		if err := resolveAvroReferences(schema.Fields[i].Schema, named, seen); err != nil {
+			return err
+		}
+	}
+	for _, branch := range schema.Branches {
+		if err := resolveAvroReferences(branch, named, seen); err != nil {
+			return err
+		}
+	}
+	if err := resolveAvroReferences(schema.Items, named, seen); err != nil {
+		return err
+	}
+	return resolveAvroReferences(schema.Values, named, seen)
+}
+
+func qualifyAvroName(name, namespace string) string {
+	if name == "" || strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// Limits applied while decoding untrusted Avro payloads
+const (
+	maxAvroCollectionItems = 1 << 20 // items of a single array or map, across all blocks
+	maxAvroDepth           = 64      // nesting of records, arrays, maps and unions
+)
+
+// avroReader reads Avro binary encoded values
+type avroReader struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+// decodeAvro decodes a single Avro binary datum
+func decodeAvro(schema *avroSchema, data []byte) (interface{}, error) {
+	r := &avroReader{data: data}
+	value, err := r.read(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Avro payload: %v", err)
+	}
+	return value, nil
+}
+
+func (r *avroReader) read(schema *avroSchema) (interface{}, error) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if r.depth > maxAvroDepth {
+		return nil, fmt.Errorf("value nested deeper than %d levels", maxAvroDepth)
+	}
+
+	switch schema.Type {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.byte()
+		return b == 1, err
+	case "int":
+		n, err := r.long()
+		return int32(n), err
+	case "long":
+		return r.long()
+	case "float":
+		bytes, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(bytes)), nil
+	case "double":
+		bytes, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(bytes)), nil
+	case "bytes":
+		bytes, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(bytes), nil
+	case "string":
+		bytes, err := r.bytes()
+		return string(bytes), err
+	case "fixed":
+		bytes, err := r.next(schema.Size)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(bytes), nil
+	case "enum":
+		idx, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 || int(idx) >= len(schema.Symbols) {
+			return nil, fmt.Errorf("enum index %d out of range for %s", idx, schema.Name)
+		}
+		return schema.Symbols[idx], nil
+	case "union":
+		idx, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 || int(idx) >= len(schema.Branches) {
+			return nil, fmt.Errorf("union index %d out of range", idx)
+		}
+		return r.read(schema.Branches[idx])
+	case "record":
+		record := make(map[string]interface{}, len(schema.Fields))
+		for _, field := range schema.Fields {
+			value, err := r.read(field.Schema)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", schema.Name, field.Name, err)
+			}
+			record[field.Name] = value
+		}
+		return record, nil
+	case "array":
+		var items []interface{}
+		err := r.blocks(func() error {
+			item, err := r.read(schema.Items)
+			items = append(items, item)
+			return err
+		})
+		return items, err
+	case "map":
+		values := make(map[string]interface{})
+		err := r.blocks(func() error {
+			key, err := r.bytes()
+			if err != nil {
+				return err
+			}
+			value, err := r.read(schema.Values)
+			values[string(key)] = value
+			return err
+		})
+		return values, err
+	default:
+		return nil, fmt.Errorf("unsupported Avro type: %s", schema.Type)
+	}
+}
+
+// blocks reads the block-encoded items of arrays and maps
+func (r *avroReader) blocks(readItem func() error) error {
+	var total int64
+	for {
+		count, err := r.long()
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			// Negative counts are followed by the block size in bytes
+			if count == math.MinInt64 {
+				return fmt.Errorf("invalid block count at offset %d", r.pos)
+			}
+			count = -count
+			if _, err := r.long(); err != nil {
+				return err
+			}
+		}
+		// Items of zero-length types such as null consume no input, so the count alone
+		// must not be trusted
+		if count > maxAvroCollectionItems-total {
+			return fmt.Errorf("collection exceeds %d items at offset %d", maxAvroCollectionItems, r.pos)
+		}
+		total += count
+		for i := int64(0); i < count; i++ {
+			if err := readItem(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *avroReader) long() (int64, error) {
+	value, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint at offset %d", r.pos)
+	}
+	r.pos += n
+	// Zig-zag decoding
+	return int64(value>>1) ^ -int64(value&1), nil
+}
+
+func (r *avroReader) bytes() ([]byte, error) {
+	length, err := r.long()
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 || length > int64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("invalid length %d at offset %d", length, r.pos)
+	}
+	return r.next(int(length))
+}
+
+func (r *avroReader) byte() (byte, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *avroReader) next(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, fmt.Errorf("unexpected end of data at offset %d", r.pos)
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
@@ -108,6 +108,31 @@ func GetBuiltInTransformations() []BuiltInTransformation {
 			},
 			ExecuteFunc: transformLowercase,
 		},
+		{
+			Name:           "trim",
+			Description:    "Remove leading and trailing whitespace",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformTrim",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The string to trim",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The trimmed string",
+				},
+			},
+			ExecuteFunc: transformTrim,
+		},
 		{
 			Name:           "reverse",
 			Description:    "Reverse the input string",
@@ -470,6 +495,281 @@ func GetBuiltInTransformations() []BuiltInTransformation {
 			},
 			ExecuteFunc: transformNullExport,
 		},
+		{
+			Name:           "window_aggregate",
+			Description:    "Aggregate stream events into tumbling or sliding windows with watermark-based late-data handling",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformWindowAggregate",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "json",
+					IsMandatory: true,
+					Description: "JSON object with window_id, spec (on first call), events and optional flush flag",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "json",
+					Description: "The closed windows with their aggregated values, the current watermark and late event count",
+				},
+			},
+			// ExecuteFunc is bound to the engine's WindowManager in InitializeRegistry
+		},
+		{
+			Name:           "mask_format",
+			Description:    "Mask letters and digits with others of the same kind, keeping the format, e.g. of emails and phone numbers",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformMaskFormat",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The value to mask",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The masked value, of the same length and format",
+				},
+			},
+			// No ExecuteFunc: the engine's MaskingSalts apply it in Transform with the tenant of
+			// the request, which workflow nodes do not pass
+		},
+		{
+			Name:           "tokenize",
+			Description:    "Replace a value with a deterministic token, the same for the same value within a tenant",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformTokenize",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The value to tokenize",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The token",
+				},
+			},
+			// No ExecuteFunc: the engine's MaskingSalts apply it in Transform with the tenant of
+			// the request, which workflow nodes do not pass
+		},
+		{
+			Name:           "redact_partial",
+			Description:    "Redact all but the first and last characters of a value, e.g. all but the last 4 digits of a card number",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformRedactPartial",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The value to redact",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The redacted value",
+				},
+			},
+			// No ExecuteFunc: it is applied in Transform with the options of the request, which
+			// workflow nodes do not pass
+		},
+		{
+			Name:           "hash_salted",
+			Description:    "Generate an HMAC-SHA256 hash keyed with the salt of the tenant",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformHashSalted",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The string to hash",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The salted hash",
+				},
+			},
+			// No ExecuteFunc: the engine's MaskingSalts apply it in Transform with the tenant of
+			// the request, which workflow nodes do not pass
+		},
+		{
+			Name:           "lookup",
+			Description:    "Map values through a lookup table, e.g. legacy country codes to ISO codes",
+			Type:           "passthrough",
+			Cardinality:    "one-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformLookup",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The value to look up",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The value the lookup table maps the input to",
+				},
+			},
+			// No ExecuteFunc: the engine's LookupTables apply it in Transform with the options of
+			// the request, which workflow nodes do not pass
+		},
+		{
+			Name:           "protobuf_decode",
+			Description:    "Decode a binary protobuf payload into structured fields using a registered descriptor set",
+			Type:           "passthrough",
+			Cardinality:    "one-to-many",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformProtobufDecode",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "json",
+					IsMandatory: true,
+					Description: "JSON object with the base64 payload, a registered descriptor_id and the message type",
+				},
+				{
+					Name:        "outputs",
+					IOType:      "output",
+					DataType:    "any",
+					IsArray:     true,
+					Description: "The decoded fields, mappable to target columns",
+				},
+			},
+			// ExecuteFunc is bound to the engine's PayloadDecoder in InitializeRegistry
+		},
+		{
+			Name:           "avro_decode",
+			Description:    "Decode a binary Avro payload into structured fields using an inline or registry schema",
+			Type:           "passthrough",
+			Cardinality:    "one-to-many",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformAvroDecode",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "json",
+					IsMandatory: true,
+					Description: "JSON object with the base64 payload and an Avro schema, optional when a schema registry is configured",
+				},
+				{
+					Name:        "outputs",
+					IOType:      "output",
+					DataType:    "any",
+					IsArray:     true,
+					Description: "The decoded fields, mappable to target columns",
+				},
+			},
+			// ExecuteFunc is bound to the engine's PayloadDecoder in InitializeRegistry
+		},
+		{
+			Name:           "concat",
+			Description:    "Join the values of several source columns into one, e.g. first and last name into a full name",
+			Type:           "merge",
+			Cardinality:    "many-to-one",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformConcat",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "values",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					IsArray:     true,
+					Description: "The values to join, in the order of the source columns",
+				},
+				{
+					Name:         "separator",
+					IOType:       "input",
+					DataType:     "string",
+					IsMandatory:  false,
+					DefaultValue: defaultSeparator,
+					Description:  "The separator placed between the values",
+				},
+				{
+					Name:        "result",
+					IOType:      "output",
+					DataType:    "string",
+					Description: "The joined value",
+				},
+			},
+			ExecuteFunc: transformConcatInputs,
+		},
+		{
+			Name:           "split",
+			Description:    "Split a value into those of several target columns, e.g. a full name into first and last name",
+			Type:           "split",
+			Cardinality:    "one-to-many",
+			RequiresInput:  true,
+			ProducesOutput: true,
+			Implementation: "transformSplit",
+			IODefinitions: []IODefinition{
+				{
+					Name:        "value",
+					IOType:      "input",
+					DataType:    "string",
+					IsMandatory: true,
+					Description: "The value to split",
+				},
+				{
+					Name:         "separator",
+					IOType:       "input",
+					DataType:     "string",
+					IsMandatory:  false,
+					DefaultValue: defaultSeparator,
+					Description:  "The separator to split at; the last target column keeps the rest of the value",
+				},
+				{
+					Name:        "outputs",
+					IOType:      "output",
+					DataType:    "string",
+					IsArray:     true,
+					Description: "One value per target column, empty when the value has fewer parts",
+				},
+			},
+			ExecuteFunc: transformSplitWords,
+		},
 		{
 			Name:           "combine_to_json",
 			Description:    "Combine multiple inputs into a JSON object",
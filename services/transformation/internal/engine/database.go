@@ -2,8 +2,10 @@ package engine
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/logger"
@@ -362,6 +364,68 @@ func (db *DatabaseOps) ListTransformations(ctx context.Context, tenantID string)
 	return records, rows.Err()
 }
 
+// ListPluginTransformations retrieves the transformations of all tenants that are plugins
+func (db *DatabaseOps) ListPluginTransformations(ctx context.Context) ([]*TransformationRecord, error) {
+	query := `
+		SELECT transformation_id
+		FROM transformations
+		WHERE transformation_implementation = $1 AND transformation_enabled = true
+		ORDER BY transformation_name
+	`
+
+	rows, err := db.db.Pool().Query(ctx, query, pluginImplementation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugin transformations: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan plugin transformation: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list plugin transformations: %w", err)
+	}
+
+	records := make([]*TransformationRecord, 0, len(ids))
+	for _, id := range ids {
+		record, err := db.GetTransformation(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// GetPluginTransformation retrieves the plugin transformation with the given name, of any tenant
+func (db *DatabaseOps) GetPluginTransformation(ctx context.Context, name string) (*TransformationRecord, error) {
+	query := `
+		SELECT transformation_id
+		FROM transformations
+		WHERE transformation_implementation = $1 AND transformation_name = $2 AND transformation_enabled = true
+		LIMIT 1
+	`
+
+	var id string
+	if err := db.db.Pool().QueryRow(ctx, query, pluginImplementation, name).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to get plugin transformation %s: %w", name, err)
+	}
+	return db.GetTransformation(ctx, id)
+}
+
+// DeleteTransformation deletes a transformation and its I/O definitions
+func (db *DatabaseOps) DeleteTransformation(ctx context.Context, transformationID string) error {
+	if _, err := db.db.Pool().Exec(ctx, `DELETE FROM transformations WHERE transformation_id = $1`, transformationID); err != nil {
+		return fmt.Errorf("failed to delete transformation: %w", err)
+	}
+	return nil
+}
+
 // SeedBuiltInTransformations seeds the database with built-in transformations
 func (db *DatabaseOps) SeedBuiltInTransformations(ctx context.Context, tenantID, ownerID string) error {
 	db.logger.Info("Seeding built-in transformations...")
@@ -420,3 +484,74 @@ func (db *DatabaseOps) SeedBuiltInTransformations(ctx context.Context, tenantID,
 	db.logger.Info("Built-in transformations seeded successfully")
 	return nil
 }
+
+// SaveDescriptorSet stores a serialized FileDescriptorSet under its content hash
+func (db *DatabaseOps) SaveDescriptorSet(ctx context.Context, descriptorID string, descriptorSet []byte) error {
+	query := `
+		INSERT INTO transformation_descriptor_sets (descriptor_id, descriptor_set)
+		VALUES ($1, $2)
+		ON CONFLICT (descriptor_id) DO NOTHING
+	`
+
+	if _, err := db.db.Pool().Exec(ctx, query, descriptorID, descriptorSet); err != nil {
+		return fmt.Errorf("failed to save descriptor set: %w", err)
+	}
+	return nil
+}
+
+// GetDescriptorSet retrieves a serialized FileDescriptorSet by its ID
+func (db *DatabaseOps) GetDescriptorSet(ctx context.Context, descriptorID string) ([]byte, error) {
+	query := `SELECT descriptor_set FROM transformation_descriptor_sets WHERE descriptor_id = $1`
+
+	var descriptorSet []byte
+	if err := db.db.Pool().QueryRow(ctx, query, descriptorID).Scan(&descriptorSet); err != nil {
+		return nil, fmt.Errorf("failed to get descriptor set %s: %w", descriptorID, err)
+	}
+	return descriptorSet, nil
+}
+
+// GetLookupTable retrieves the entries and cache TTL of a lookup table by its name, which is
+// unique across tenants
+func (db *DatabaseOps) GetLookupTable(ctx context.Context, name string) (*LookupTableRecord, error) {
+	query := `SELECT lookup_entries, cache_ttl_seconds FROM transformation_lookup_tables WHERE lookup_table_name = $1`
+
+	var entriesJSON []byte
+	var ttlSeconds int
+	if err := db.db.Pool().QueryRow(ctx, query, name).Scan(&entriesJSON, &ttlSeconds); err != nil {
+		return nil, fmt.Errorf("failed to get lookup table %s: %w", name, err)
+	}
+
+	record := &LookupTableRecord{
+		Name:     name,
+		Entries:  make(map[string]string),
+		CacheTTL: time.Duration(ttlSeconds) * time.Second,
+	}
+	if err := json.Unmarshal(entriesJSON, &record.Entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal entries of lookup table %s: %w", name, err)
+	}
+	return record, nil
+}
+
+// GetMaskingSalt returns the masking salt of a tenant, creating it when the tenant has none
+func (db *DatabaseOps) GetMaskingSalt(ctx context.Context, tenantID string) ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate masking salt: %w", err)
+	}
+
+	// Of concurrent first uses, the salt inserted first is kept
+	insert := `
+		INSERT INTO transformation_masking_salts (tenant_id, salt)
+		VALUES ($1, $2)
+		ON CONFLICT (tenant_id) DO NOTHING
+	`
+	if _, err := db.db.Pool().Exec(ctx, insert, tenantID, salt); err != nil {
+		return nil, fmt.Errorf("failed to create masking salt of tenant %s: %w", tenantID, err)
+	}
+
+	query := `SELECT salt FROM transformation_masking_salts WHERE tenant_id = $1`
+	if err := db.db.Pool().QueryRow(ctx, query, tenantID).Scan(&salt); err != nil {
+		return nil, fmt.Errorf("failed to get masking salt of tenant %s: %w", tenantID, err)
+	}
+	return salt, nil
+}
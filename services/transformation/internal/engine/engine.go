@@ -3,13 +3,16 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
 	"github.com/redbco/redb-open/pkg/config"
 	"github.com/redbco/redb-open/pkg/database"
 	"github.com/redbco/redb-open/pkg/logger"
+	"github.com/redbco/redb-open/pkg/service"
 	"google.golang.org/grpc"
 )
 
@@ -20,6 +23,11 @@ type Engine struct {
 	db             *database.PostgreSQL
 	registry       *TransformationRegistry
 	workflowEngine *WorkflowEngine
+	payloads       *PayloadDecoder
+	windows        *WindowManager
+	plugins        *PluginManager
+	lookups        *LookupTables
+	maskingSalts   *MaskingSalts
 	state          struct {
 		sync.Mutex
 		isRunning         bool
@@ -80,6 +88,29 @@ func (e *Engine) InitializeRegistry(ctx context.Context) error {
 	// Register built-in functions
 	e.registry.RegisterBuiltIn()
 
+	// The payload decoders only reach the schema registry configured for the service
+	cacheSize, _ := strconv.Atoi(e.config.Get("services.transformation.payload_cache_size"))
+	e.payloads = NewPayloadDecoder(e.registry.db, e.config.Get("services.transformation.schema_registry_url"), cacheSize)
+	e.registry.RegisterFunction("transformProtobufDecode", e.payloads.DecodeProtobuf)
+	e.registry.RegisterFunction("transformAvroDecode", e.payloads.DecodeAvro)
+
+	// Window state lives as long as the engine and is dropped after the idle TTL (in seconds)
+	idleTTL, _ := strconv.Atoi(e.config.Get("services.transformation.window_idle_ttl"))
+	e.windows = NewWindowManager(time.Duration(idleTTL) * time.Second)
+	e.registry.RegisterFunction("transformWindowAggregate", e.windows.Process)
+
+	// Lookup tables are read from the database when first used and cached for their TTL
+	e.lookups = NewLookupTables(e.registry.db)
+
+	// Masking salts are created for a tenant when its rules are first masked
+	e.maskingSalts = NewMaskingSalts(e.registry.db)
+
+	// Plugins of all tenants; a plugin the database cannot return now is loaded when first used
+	e.plugins = NewPluginManager(e.registry.db, e.logger)
+	if err := e.plugins.Load(ctx); err != nil {
+		e.logger.Warnf("Failed to load transformation plugins: %v", err)
+	}
+
 	e.logger.Info("Transformation registry initialized")
 	return nil
 }
@@ -136,6 +167,10 @@ func (e *Engine) Stop(ctx context.Context) error {
 		return nil
 	}
 
+	if e.plugins != nil {
+		e.plugins.Close()
+	}
+
 	// Close database connection
 	if e.db != nil {
 		e.db.Close()
@@ -149,8 +184,9 @@ func (e *Engine) Stop(ctx context.Context) error {
 
 func (e *Engine) GetMetrics() map[string]int64 {
 	return map[string]int64{
-		"requests_processed": atomic.LoadInt64(&e.metrics.requestsProcessed),
-		"errors":             atomic.LoadInt64(&e.metrics.errors),
+		"requests_processed":     atomic.LoadInt64(&e.metrics.requestsProcessed),
+		"errors":                 atomic.LoadInt64(&e.metrics.errors),
+		service.MetricQueueDepth: int64(atomic.LoadInt32(&e.state.ongoingOperations)),
 	}
 }
 
@@ -26,6 +26,11 @@ func transformLowercase(input string) string {
 	return strings.ToLower(input)
 }
 
+// transformTrim removes leading and trailing whitespace from input
+func transformTrim(input string) string {
+	return strings.TrimSpace(input)
+}
+
 // transformReverse reverses the input string
 func transformReverse(input string) string {
 	runes := []rune(input)
@@ -237,3 +242,46 @@ func transformSplitJSON(input string) (map[string]interface{}, error) {
 	}
 	return result, nil
 }
+
+// defaultSeparator joins the values merged by concat and divides those split by split
+const defaultSeparator = " "
+
+// transformConcat joins the values of several source columns into one, e.g. first and last
+// name into a full name
+func transformConcat(values []string, separator string) string {
+	return strings.Join(values, separator)
+}
+
+// transformSplit divides a value into the given number of parts at the separator, e.g. a full
+// name into first and last name. The last part keeps the rest of the value; missing parts are
+// empty.
+func transformSplit(input, separator string, parts int) []string {
+	values := strings.SplitN(strings.TrimSpace(input), separator, parts)
+	for len(values) < parts {
+		values = append(values, "")
+	}
+	return values
+}
+
+// transformConcatInputs joins the "values" input of a workflow node with its optional
+// "separator" input
+func transformConcatInputs(inputs map[string]interface{}) (string, error) {
+	rawValues, ok := inputs["values"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("concat needs a 'values' array input")
+	}
+	values := make([]string, len(rawValues))
+	for i, value := range rawValues {
+		values[i] = fmt.Sprintf("%v", value)
+	}
+	separator := defaultSeparator
+	if s, ok := inputs["separator"].(string); ok {
+		separator = s
+	}
+	return transformConcat(values, separator), nil
+}
+
+// transformSplitWords splits the value of a workflow node at whitespace
+func transformSplitWords(input string) []string {
+	return strings.Fields(input)
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// LookupTableRecord is a lookup table as stored in the database
+type LookupTableRecord struct {
+	Name     string
+	Entries  map[string]string
+	CacheTTL time.Duration // 0 caches the entries until the table is refreshed
+}
+
+// lookupTableStore reads lookup tables
+type lookupTableStore interface {
+	GetLookupTable(ctx context.Context, name string) (*LookupTableRecord, error)
+}
+
+// cachedLookupTable is a lookup table loaded for the lookup transformation
+type cachedLookupTable struct {
+	entries map[string]string
+	folded  map[string]string // Keys in lowercase, for case-insensitive lookups
+	expires time.Time         // Zero when the entries do not expire
+}
+
+// LookupTables maps values through the lookup tables of tenants, caching their entries for the
+// cache TTL of each table or until it is refreshed
+type LookupTables struct {
+	mu     sync.Mutex
+	tables map[string]*cachedLookupTable
+	store  lookupTableStore
+	now    func() time.Time
+}
+
+// NewLookupTables creates the lookup tables read from the given store
+func NewLookupTables(store lookupTableStore) *LookupTables {
+	return &LookupTables{
+		tables: make(map[string]*cachedLookupTable),
+		store:  store,
+		now:    time.Now,
+	}
+}
+
+// Lookup returns the value the lookup table named by the table parameter maps the input to.
+// A value missing from the table is kept, replaced by the default parameter when given, or
+// rejected when on_missing is "error". With case_insensitive, keys are compared ignoring case.
+func (l *LookupTables) Lookup(ctx context.Context, input string, parameters *structpb.Struct) (string, error) {
+	params := parameters.GetFields()
+	tableName := params["table"].GetStringValue()
+	if tableName == "" {
+		return "", fmt.Errorf("lookup requires the table option naming a lookup table")
+	}
+
+	table, err := l.table(ctx, tableName)
+	if err != nil {
+		return "", err
+	}
+
+	if output, found := table.entries[input]; found {
+		return output, nil
+	}
+	if params["case_insensitive"].GetBoolValue() {
+		if output, found := table.folded[strings.ToLower(input)]; found {
+			return output, nil
+		}
+	}
+
+	if defaultValue, ok := params["default"]; ok {
+		return defaultValue.GetStringValue(), nil
+	}
+	switch onMissing := params["on_missing"].GetStringValue(); onMissing {
+	case "", "keep":
+		return input, nil
+	case "error":
+		return "", fmt.Errorf("value '%s' not found in lookup table %s", input, tableName)
+	default:
+		return "", fmt.Errorf("invalid on_missing '%s': expected keep or error", onMissing)
+	}
+}
+
+// Refresh drops the cached entries of a lookup table, or of all tables when name is empty
+func (l *LookupTables) Refresh(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if name == "" {
+		l.tables = make(map[string]*cachedLookupTable)
+		return
+	}
+	delete(l.tables, name)
+}
+
+// table returns the cached entries of a lookup table, loading them when they are not cached or
+// have expired
+func (l *LookupTables) table(ctx context.Context, name string) (*cachedLookupTable, error) {
+	l.mu.Lock()
+	table, cached := l.tables[name]
+	l.mu.Unlock()
+	if cached && (table.expires.IsZero() || l.now().Before(table.expires)) {
+		return table, nil
+	}
+
+	if l.store == nil {
+		return nil, fmt.Errorf("lookup table %s not found", name)
+	}
+	record, err := l.store.GetLookupTable(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	table = &cachedLookupTable{
+		entries: record.Entries,
+		folded:  make(map[string]string, len(record.Entries)),
+	}
+	keys := make([]string, 0, len(record.Entries))
+	for key := range record.Entries {
+		keys = append(keys, key)
+	}
+	// Of several keys differing only by case, the first in order wins
+	sort.Strings(keys)
+	for _, key := range keys {
+		folded := strings.ToLower(key)
+		if _, exists := table.folded[folded]; !exists {
+			table.folded[folded] = record.Entries[key]
+		}
+	}
+	if record.CacheTTL > 0 {
+		table.expires = l.now().Add(record.CacheTTL)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tables[name] = table
+	return table, nil
+}
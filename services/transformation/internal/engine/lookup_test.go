@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// memoryLookupStore returns lookup tables from memory and counts the reads
+type memoryLookupStore struct {
+	tables map[string]*LookupTableRecord
+	reads  int
+}
+
+func (s *memoryLookupStore) GetLookupTable(ctx context.Context, name string) (*LookupTableRecord, error) {
+	s.reads++
+	record, ok := s.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("lookup table %s not found", name)
+	}
+	return record, nil
+}
+
+func lookupOptions(t *testing.T, options map[string]interface{}) *structpb.Struct {
+	t.Helper()
+	parameters, err := structpb.NewStruct(options)
+	if err != nil {
+		t.Fatalf("NewStruct: %v", err)
+	}
+	return parameters
+}
+
+func TestLookup(t *testing.T) {
+	store := &memoryLookupStore{tables: map[string]*LookupTableRecord{
+		"country_codes": {Name: "country_codes", Entries: map[string]string{"UK": "GB", "FRA": "FR"}},
+	}}
+	lookups := NewLookupTables(store)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		input   string
+		options map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{"exact match", "UK", map[string]interface{}{"table": "country_codes"}, "GB", false},
+		{"missing value is kept", "DE", map[string]interface{}{"table": "country_codes"}, "DE", false},
+		{"case sensitive by default", "uk", map[string]interface{}{"table": "country_codes"}, "uk", false},
+		{"case insensitive", "fra", map[string]interface{}{"table": "country_codes", "case_insensitive": true}, "FR", false},
+		{"default", "DE", map[string]interface{}{"table": "country_codes", "default": "ZZ"}, "ZZ", false},
+		{"missing value rejected", "DE", map[string]interface{}{"table": "country_codes", "on_missing": "error"}, "", true},
+		{"invalid on_missing", "DE", map[string]interface{}{"table": "country_codes", "on_missing": "drop"}, "", true},
+		{"table required", "UK", map[string]interface{}{}, "", true},
+		{"unknown table", "UK", map[string]interface{}{"table": "currencies"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookups.Lookup(ctx, tt.input, lookupOptions(t, tt.options))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupCache(t *testing.T) {
+	record := &LookupTableRecord{Name: "country_codes", Entries: map[string]string{"UK": "GB"}, CacheTTL: time.Minute}
+	store := &memoryLookupStore{tables: map[string]*LookupTableRecord{"country_codes": record}}
+	lookups := NewLookupTables(store)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lookups.now = func() time.Time { return now }
+	ctx := context.Background()
+	options := lookupOptions(t, map[string]interface{}{"table": "country_codes"})
+
+	lookup := func(input string) string {
+		t.Helper()
+		output, err := lookups.Lookup(ctx, input, options)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", input, err)
+		}
+		return output
+	}
+
+	lookup("UK")
+	store.tables["country_codes"] = &LookupTableRecord{Name: "country_codes", Entries: map[string]string{"UK": "GB", "EL": "GR"}, CacheTTL: time.Minute}
+
+	// The cached entries are used until the TTL expires
+	if got := lookup("EL"); got != "EL" || store.reads != 1 {
+		t.Fatalf("Lookup(EL) before expiry = %q with %d reads", got, store.reads)
+	}
+	now = now.Add(2 * time.Minute)
+	if got := lookup("EL"); got != "GR" || store.reads != 2 {
+		t.Fatalf("Lookup(EL) after expiry = %q with %d reads", got, store.reads)
+	}
+
+	// A refresh drops the cached entries at once
+	store.tables["country_codes"] = &LookupTableRecord{Name: "country_codes", Entries: map[string]string{"UK": "XX"}}
+	lookups.Refresh("country_codes")
+	if got := lookup("UK"); got != "XX" || store.reads != 3 {
+		t.Fatalf("Lookup(UK) after refresh = %q with %d reads", got, store.reads)
+	}
+
+	// Without a TTL, the entries are cached until the next refresh
+	now = now.Add(24 * time.Hour)
+	lookup("UK")
+	if store.reads != 3 {
+		t.Fatalf("expected the entries without TTL to stay cached, got %d reads", store.reads)
+	}
+}
+
+func TestTransformLookupChain(t *testing.T) {
+	store := &memoryLookupStore{tables: map[string]*LookupTableRecord{
+		"country_codes": {Name: "country_codes", Entries: map[string]string{"UK": "GB"}},
+	}}
+	s := &TransformationServer{engine: &Engine{lookups: NewLookupTables(store)}}
+	req := &pb.TransformRequest{
+		FunctionName: "trim|uppercase|lookup",
+		Input:        " uk ",
+		Parameters:   lookupOptions(t, map[string]interface{}{"table": "country_codes"}),
+	}
+
+	outputs, err := s.executeTransformation(context.Background(), req)
+	if err != nil || len(outputs) != 1 || outputs[0] != "GB" {
+		t.Fatalf("trim|uppercase|lookup = %v, %v", outputs, err)
+	}
+}
@@ -0,0 +1,207 @@
+package engine
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// defaultTokenPrefix prefixes the tokens of the tokenize transformation
+	defaultTokenPrefix = "tok_"
+
+	// defaultTokenLength is the number of hex characters of a token after its prefix
+	defaultTokenLength = 32
+
+	// defaultRedactKeepLast is the number of trailing letters and digits redact_partial keeps
+	defaultRedactKeepLast = 4
+)
+
+// maskingSaltStore reads the masking salts of tenants
+type maskingSaltStore interface {
+	GetMaskingSalt(ctx context.Context, tenantID string) ([]byte, error)
+}
+
+// MaskingSalts keys the masking transformations with the salt of each tenant: the masks, tokens
+// and hashes of a value are stable within a tenant, so masked columns still join, but differ
+// between tenants and cannot be recomputed without the salt. Salts are created when first used
+// and cached for the life of the engine.
+type MaskingSalts struct {
+	mu    sync.Mutex
+	salts map[string][]byte
+	store maskingSaltStore
+}
+
+// NewMaskingSalts creates the masking salts read from the given store
+func NewMaskingSalts(store maskingSaltStore) *MaskingSalts {
+	return &MaskingSalts{
+		salts: make(map[string][]byte),
+		store: store,
+	}
+}
+
+// Mask applies a salted masking transformation to the input with the salt of the tenant:
+//   - mask_format replaces each letter and digit with another of the same kind and case, and
+//     keeps the other characters, such as the separators of emails and phone numbers. The
+//     keep_last option keeps the last letters and digits.
+//   - tokenize replaces the input with a token of the prefix and length options.
+//   - hash_salted returns the hex HMAC-SHA256 of the input.
+func (m *MaskingSalts) Mask(ctx context.Context, functionName, tenantID, input string, parameters *structpb.Struct) (string, error) {
+	if tenantID == "" {
+		return "", fmt.Errorf("%s is keyed with the salt of the tenant and requires the tenant of the rule", functionName)
+	}
+	salt, err := m.salt(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	params := parameters.GetFields()
+	switch functionName {
+	case "mask_format":
+		return maskFormat(salt, input, intOption(params, "keep_last", 0)), nil
+	case "tokenize":
+		prefix := defaultTokenPrefix
+		if value, ok := params["prefix"]; ok {
+			prefix = value.GetStringValue()
+		}
+		length := intOption(params, "length", defaultTokenLength)
+		if length < 16 || length > 64 {
+			return "", fmt.Errorf("invalid token length %d: expected 16 to 64", length)
+		}
+		return prefix + hex.EncodeToString(saltedHash(salt, input))[:length], nil
+	case "hash_salted":
+		return hex.EncodeToString(saltedHash(salt, input)), nil
+	default:
+		return "", fmt.Errorf("unknown masking transformation: %s", functionName)
+	}
+}
+
+// salt returns the salt of a tenant, loading it when it is not cached
+func (m *MaskingSalts) salt(ctx context.Context, tenantID string) ([]byte, error) {
+	m.mu.Lock()
+	salt, cached := m.salts[tenantID]
+	m.mu.Unlock()
+	if cached {
+		return salt, nil
+	}
+
+	if m.store == nil {
+		return nil, fmt.Errorf("no masking salt of tenant %s", tenantID)
+	}
+	salt, err := m.store.GetMaskingSalt(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.salts[tenantID] = salt
+	return salt, nil
+}
+
+// saltedHash returns the HMAC-SHA256 of the input keyed with the salt
+func saltedHash(salt []byte, input string) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+// maskFormat replaces the letters and digits of the input, but the last keepLast, with letters
+// and digits derived from the salted hash of the whole input, keeping their case and the other
+// characters
+func maskFormat(salt []byte, input string, keepLast int) string {
+	runes := []rune(input)
+	keepFrom := len(runes)
+	for i := len(runes) - 1; i >= 0 && keepLast > 0; i-- {
+		if unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) {
+			keepLast--
+			keepFrom = i
+		}
+	}
+
+	// The hash of the input is stretched to a byte per character
+	seed := saltedHash(salt, input)
+	stream := make([]byte, 0, len(runes))
+	for counter := uint32(0); len(stream) < len(runes); counter++ {
+		block := make([]byte, 4)
+		binary.BigEndian.PutUint32(block, counter)
+		stream = append(stream, saltedHash(seed, string(block))...)
+	}
+
+	var masked strings.Builder
+	for i, r := range runes {
+		if i >= keepFrom {
+			masked.WriteRune(r)
+			continue
+		}
+		b := int(stream[i])
+		switch {
+		case unicode.IsDigit(r):
+			masked.WriteRune(rune('0' + b%10))
+		case unicode.IsUpper(r):
+			masked.WriteRune(rune('A' + b%26))
+		case unicode.IsLetter(r):
+			masked.WriteRune(rune('a' + b%26))
+		default:
+			masked.WriteRune(r)
+		}
+	}
+	return masked.String()
+}
+
+// redactPartial replaces the letters and digits of the input with the mask_char option, "*" by
+// default, but the first keep_first and the last keep_last, 4 by default. Other characters,
+// such as the dashes of a card number, are kept.
+func redactPartial(input string, parameters *structpb.Struct) (string, error) {
+	params := parameters.GetFields()
+	keepFirst := intOption(params, "keep_first", 0)
+	keepLast := intOption(params, "keep_last", defaultRedactKeepLast)
+	if keepFirst < 0 || keepLast < 0 {
+		return "", fmt.Errorf("keep_first and keep_last cannot be negative")
+	}
+	maskChar := "*"
+	if value, ok := params["mask_char"]; ok {
+		maskChar = value.GetStringValue()
+	}
+
+	runes := []rune(input)
+	total := 0
+	for _, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			total++
+		}
+	}
+
+	var redacted strings.Builder
+	position := 0
+	for _, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			redacted.WriteRune(r)
+			continue
+		}
+		if position < keepFirst || position >= total-keepLast {
+			redacted.WriteRune(r)
+		} else {
+			redacted.WriteString(maskChar)
+		}
+		position++
+	}
+	return redacted.String(), nil
+}
+
+// intOption returns an integer option, or the default when it is not set
+func intOption(params map[string]*structpb.Value, name string, defaultValue int) int {
+	value, ok := params[name]
+	if !ok {
+		return defaultValue
+	}
+	return int(value.GetNumberValue())
+}
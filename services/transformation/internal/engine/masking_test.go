@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// memorySaltStore returns fixed salts of tenants and counts the reads
+type memorySaltStore struct {
+	salts map[string][]byte
+	reads int
+}
+
+func (s *memorySaltStore) GetMaskingSalt(ctx context.Context, tenantID string) ([]byte, error) {
+	s.reads++
+	salt, ok := s.salts[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("tenant %s not found", tenantID)
+	}
+	return salt, nil
+}
+
+func TestMask(t *testing.T) {
+	store := &memorySaltStore{salts: map[string][]byte{
+		"tenant_a": []byte("salt of tenant a"),
+		"tenant_b": []byte("salt of tenant b"),
+	}}
+	salts := NewMaskingSalts(store)
+	ctx := context.Background()
+
+	mask := func(functionName, tenantID, input string, options map[string]interface{}) string {
+		t.Helper()
+		output, err := salts.Mask(ctx, functionName, tenantID, input, lookupOptions(t, options))
+		if err != nil {
+			t.Fatalf("%s(%q): %v", functionName, input, err)
+		}
+		return output
+	}
+
+	// Masks keep the format of the value and are stable within a tenant
+	masked := mask("mask_format", "tenant_a", "John.Doe@example.com", nil)
+	if len(masked) != len("John.Doe@example.com") || masked[4] != '.' || masked[8] != '@' || masked[16] != '.' {
+		t.Errorf("expected the format of the email to be kept, got %q", masked)
+	}
+	if masked[0] < 'A' || masked[0] > 'Z' || masked[1] < 'a' || masked[1] > 'z' {
+		t.Errorf("expected the case of letters to be kept, got %q", masked)
+	}
+	if masked == "John.Doe@example.com" {
+		t.Error("expected the email to be masked")
+	}
+	if again := mask("mask_format", "tenant_a", "John.Doe@example.com", nil); again != masked {
+		t.Errorf("expected the same mask within a tenant, got %q and %q", masked, again)
+	}
+	if other := mask("mask_format", "tenant_b", "John.Doe@example.com", nil); other == masked {
+		t.Error("expected another mask in another tenant")
+	}
+
+	phone := mask("mask_format", "tenant_a", "+1 (555) 123-4567", map[string]interface{}{"keep_last": 4})
+	if !strings.HasSuffix(phone, "-4567") || phone[0] != '+' || phone[3] != '(' {
+		t.Errorf("expected the separators and the last 4 digits to be kept, got %q", phone)
+	}
+
+	token := mask("tokenize", "tenant_a", "123-45-6789", nil)
+	if !strings.HasPrefix(token, "tok_") || len(token) != len("tok_")+32 {
+		t.Errorf("unexpected token %q", token)
+	}
+	if again := mask("tokenize", "tenant_a", "123-45-6789", nil); again != token {
+		t.Errorf("expected deterministic tokens, got %q and %q", token, again)
+	}
+	if short := mask("tokenize", "tenant_a", "123-45-6789", map[string]interface{}{"prefix": "ssn_", "length": 16}); short != "ssn_"+token[4:20] {
+		t.Errorf("expected a shorter token with another prefix, got %q", short)
+	}
+
+	hash := mask("hash_salted", "tenant_a", "123-45-6789", nil)
+	if len(hash) != 64 || hash == mask("hash_salted", "tenant_b", "123-45-6789", nil) {
+		t.Errorf("expected a 64 character hash differing between tenants, got %q", hash)
+	}
+
+	// Salts are read once per tenant
+	if store.reads != 2 {
+		t.Errorf("expected 2 salt reads, got %d", store.reads)
+	}
+
+	if _, err := salts.Mask(ctx, "tokenize", "", "123-45-6789", nil); err == nil {
+		t.Error("expected an error without a tenant")
+	}
+	if _, err := salts.Mask(ctx, "tokenize", "tenant_a", "123-45-6789", lookupOptions(t, map[string]interface{}{"length": 8})); err == nil {
+		t.Error("expected an error for a too short token")
+	}
+}
+
+func TestRedactPartial(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options map[string]interface{}
+		want    string
+	}{
+		{"last 4 digits by default", "4111-1111-1111-1234", nil, "****-****-****-1234"},
+		{"first and last", "john@example.com", map[string]interface{}{"keep_first": 1, "keep_last": 3}, "j***@*******.com"},
+		{"mask char", "123456789", map[string]interface{}{"keep_last": 0, "mask_char": "#"}, "#########"},
+		{"short value is kept", "ab", nil, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := redactPartial(tt.input, lookupOptions(t, tt.options))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("redactPartial(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// payloadDecodeRequest is the JSON input accepted by the payload decoding transformations
+type payloadDecodeRequest struct {
+	// Payload is the serialized value, base64 encoded (or hex when Encoding is "hex")
+	Payload  string `json:"payload"`
+	Encoding string `json:"encoding,omitempty"`
+
+	// Protobuf: the ID of a descriptor set registered with RegisterDescriptorSet
+	// and the fully qualified message name
+	DescriptorID string `json:"descriptor_id,omitempty"`
+	MessageType  string `json:"message_type,omitempty"`
+
+	// Avro: an inline writer schema. Without it, Confluent wire format payloads are
+	// resolved against the schema registry configured for the service.
+	Schema string `json:"schema,omitempty"`
+}
+
+func parsePayloadDecodeRequest(input string) (*payloadDecodeRequest, []byte, error) {
+	var req payloadDecodeRequest
+	if err := json.Unmarshal([]byte(input), &req); err != nil {
+		return nil, nil, fmt.Errorf("invalid decode request: %v", err)
+	}
+	if req.Payload == "" {
+		return nil, nil, fmt.Errorf("payload is required")
+	}
+
+	var payload []byte
+	var err error
+	switch req.Encoding {
+	case "", "base64":
+		payload, err = base64.StdEncoding.DecodeString(req.Payload)
+	case "hex":
+		payload, err = hex.DecodeString(strings.TrimPrefix(req.Payload, "\\x"))
+	default:
+		return nil, nil, fmt.Errorf("unsupported payload encoding: %s", req.Encoding)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s payload: %v", req.Encoding, err)
+	}
+
+	return &req, payload, nil
+}
+
+// descriptorStore persists registered descriptor sets
+type descriptorStore interface {
+	SaveDescriptorSet(ctx context.Context, descriptorID string, descriptorSet []byte) error
+	GetDescriptorSet(ctx context.Context, descriptorID string) ([]byte, error)
+}
+
+const (
+	defaultPayloadCacheSize = 128
+	descriptorLoadTimeout   = 10 * time.Second
+)
+
+// PayloadDecoder holds the state of the protobuf_decode and avro_decode transformations.
+// Descriptor sets are registered once and referenced by ID; the schema registry comes
+// from the service configuration, never from the request.
+type PayloadDecoder struct {
+	store       descriptorStore
+	descriptors *lruCache[*protoregistry.Files]
+	registry    *avroRegistryClient
+}
+
+// NewPayloadDecoder creates a decoder on the given descriptor store. registryURL may be empty,
+// in which case Avro payloads must carry an inline schema.
+func NewPayloadDecoder(store descriptorStore, registryURL string, cacheSize int) *PayloadDecoder {
+	if cacheSize <= 0 {
+		cacheSize = defaultPayloadCacheSize
+	}
+	d := &PayloadDecoder{
+		store:       store,
+		descriptors: newLRUCache[*protoregistry.Files](cacheSize),
+	}
+	if registryURL != "" {
+		d.registry = &avroRegistryClient{
+			url:     strings.TrimRight(registryURL, "/"),
+			schemas: newLRUCache[*avroSchema](cacheSize),
+			client:  &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return d
+}
+
+// RegisterDescriptorSet validates and stores a serialized FileDescriptorSet and returns its ID,
+// the hex SHA-256 of its content
+func (d *PayloadDecoder) RegisterDescriptorSet(ctx context.Context, raw []byte) (string, error) {
+	files, err := parseDescriptorSet(raw)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	id := hex.EncodeToString(sum[:])
+	if err := d.store.SaveDescriptorSet(ctx, id, raw); err != nil {
+		return "", err
+	}
+	d.descriptors.add(id, files)
+	return id, nil
+}
+
+// DecodeProtobuf decodes a protobuf payload into its fields using a registered descriptor set
+func (d *PayloadDecoder) DecodeProtobuf(input string) (map[string]interface{}, error) {
+	req, payload, err := parsePayloadDecodeRequest(input)
+	if err != nil {
+		return nil, err
+	}
+	if req.DescriptorID == "" || req.MessageType == "" {
+		return nil, fmt.Errorf("descriptor_id and message_type are required")
+	}
+
+	files, err := d.loadDescriptorSet(req.DescriptorID)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(req.MessageType))
+	if err != nil {
+		return nil, fmt.Errorf("message type %s not found in descriptor set: %v", req.MessageType, err)
+	}
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message type", req.MessageType)
+	}
+
+	// Confluent wire format prefixes the payload with a magic byte, a schema ID and message indexes;
+	// only the raw encoding is supported here
+	msg := dynamicpb.NewMessage(msgDesc)
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf payload: %v", err)
+	}
+
+	jsonBytes, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert protobuf message: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to convert protobuf message: %v", err)
+	}
+	return result, nil
+}
+
+func (d *PayloadDecoder) loadDescriptorSet(id string) (*protoregistry.Files, error) {
+	if files, exists := d.descriptors.get(id); exists {
+		return files, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), descriptorLoadTimeout)
+	defer cancel()
+
+	raw, err := d.store.GetDescriptorSet(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor set %s is not registered: %v", id, err)
+	}
+
+	files, err := parseDescriptorSet(raw)
+	if err != nil {
+		return nil, err
+	}
+	d.descriptors.add(id, files)
+	return files, nil
+}
+
+func parseDescriptorSet(raw []byte) (*protoregistry.Files, error) {
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %v", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build descriptors: %v", err)
+	}
+	return files, nil
+}
+
+// DecodeAvro decodes an Avro binary payload into its fields.
+// Payloads in Confluent wire format (magic byte 0 followed by a 4-byte schema ID)
+// are resolved against the configured schema registry when no inline schema is given.
+func (d *PayloadDecoder) DecodeAvro(input string) (map[string]interface{}, error) {
+	req, payload, err := parsePayloadDecodeRequest(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema *avroSchema
+	if req.Schema != "" {
+		schema, err = parseAvroSchema(req.Schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(payload) >= 5 && payload[0] == 0 && d.registry != nil {
+		schemaID := binary.BigEndian.Uint32(payload[1:5])
+		payload = payload[5:]
+		if schema == nil {
+			schema, err = d.registry.fetch(schemaID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("schema is required when no schema registry is configured")
+	}
+
+	value, err := decodeAvro(schema, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{"value": value}, nil
+	}
+	return record, nil
+}
+
+// avroRegistryClient fetches and caches writer schemas from a Confluent-compatible schema registry
+type avroRegistryClient struct {
+	url     string
+	schemas *lruCache[*avroSchema]
+	client  *http.Client
+}
+
+func (c *avroRegistryClient) fetch(schemaID uint32) (*avroSchema, error) {
+	key := strconv.FormatUint(uint64(schemaID), 10)
+	if schema, exists := c.schemas.get(key); exists {
+		return schema, nil
+	}
+
+	resp, err := c.client.Get(fmt.Sprintf("%s/schemas/ids/%d", c.url, schemaID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %d from registry: %v", schemaID, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistryResponse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema %d from registry: %v", schemaID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry returned %d for schema %d", resp.StatusCode, schemaID)
+	}
+
+	var result struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid schema registry response: %v", err)
+	}
+
+	schema, err := parseAvroSchema(result.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema %d: %w", schemaID, err)
+	}
+	c.schemas.add(key, schema)
+	return schema, nil
+}
+
+// maxRegistryResponse bounds the schema registry response read into memory
+const maxRegistryResponse = 4 << 20
+
+// lruCache is a fixed-size, concurrency-safe least recently used cache
+type lruCache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRUCache[V any](capacity int) *lruCache[V] {
+	return &lruCache[V]{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*lruEntry[V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *lruCache[V]) add(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+	}
+}
+
+func (c *lruCache[V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// encodeDecodedFields encodes decoded payload fields as a JSON string for single-output callers
+func encodeDecodedFields(fields map[string]interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode decoded fields: %v", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,418 @@
+package engine
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// memoryDescriptorStore is an in-memory descriptorStore
+type memoryDescriptorStore struct {
+	sets  map[string][]byte
+	loads int
+}
+
+func newMemoryDescriptorStore() *memoryDescriptorStore {
+	return &memoryDescriptorStore{sets: map[string][]byte{}}
+}
+
+func (s *memoryDescriptorStore) SaveDescriptorSet(ctx context.Context, id string, set []byte) error {
+	s.sets[id] = set
+	return nil
+}
+
+func (s *memoryDescriptorStore) GetDescriptorSet(ctx context.Context, id string) ([]byte, error) {
+	s.loads++
+	set, ok := s.sets[id]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	return set, nil
+}
+
+// orderDescriptorSet describes test.Order { int64 id = 1; string name = 2; repeated string tags = 3; }
+func orderDescriptorSet(t *testing.T) ([]byte, protoreflect.MessageDescriptor) {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("order.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+				field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+			},
+		}},
+	}
+
+	raw, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw, fd.Messages().ByName("Order")
+}
+
+func decodeInput(t *testing.T, fields map[string]interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	store := newMemoryDescriptorStore()
+	decoder := NewPayloadDecoder(store, "", 0)
+
+	raw, desc := orderDescriptorSet(t)
+	id, err := decoder.RegisterDescriptorSet(context.Background(), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("id"), protoreflect.ValueOfInt64(42))
+	msg.Set(desc.Fields().ByName("name"), protoreflect.ValueOfString("widget"))
+	tags := msg.Mutable(desc.Fields().ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString("b"))
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			input: map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(payload), "descriptor_id": id, "message_type": "test.Order"},
+			want:  `{"id":"42","name":"widget","tags":["a","b"]}`,
+		},
+		{
+			name:    "truncated payload",
+			input:   map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(payload[:len(payload)-1]), "descriptor_id": id, "message_type": "test.Order"},
+			wantErr: "failed to decode protobuf payload",
+		},
+		{
+			name:    "length beyond payload",
+			input:   map[string]interface{}{"payload": base64.StdEncoding.EncodeToString([]byte{0x12, 0xff, 0xff, 0xff, 0xff, 0x0f, 'x'}), "descriptor_id": id, "message_type": "test.Order"},
+			wantErr: "failed to decode protobuf payload",
+		},
+		{
+			name:    "unknown descriptor",
+			input:   map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(payload), "descriptor_id": "missing", "message_type": "test.Order"},
+			wantErr: "is not registered",
+		},
+		{
+			name:    "unknown message type",
+			input:   map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(payload), "descriptor_id": id, "message_type": "test.Missing"},
+			wantErr: "not found in descriptor set",
+		},
+		{
+			name:    "inline descriptor set is not accepted",
+			input:   map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(payload), "descriptor_set": base64.StdEncoding.EncodeToString(raw), "message_type": "test.Order"},
+			wantErr: "descriptor_id and message_type are required",
+		},
+		{
+			name:    "invalid payload encoding",
+			input:   map[string]interface{}{"payload": "not base64!", "descriptor_id": id, "message_type": "test.Order"},
+			wantErr: "invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeDecodedFields(decoder.DecodeProtobuf(decodeInput(t, tt.input)))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("decoded = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDescriptorSet(t *testing.T) {
+	store := newMemoryDescriptorStore()
+	decoder := NewPayloadDecoder(store, "", 1)
+
+	if _, err := decoder.RegisterDescriptorSet(context.Background(), []byte{0xff, 0xff}); err == nil {
+		t.Fatal("invalid descriptor set was registered")
+	}
+
+	raw, _ := orderDescriptorSet(t)
+	id, err := decoder.RegisterDescriptorSet(context.Background(), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := decoder.RegisterDescriptorSet(context.Background(), raw)
+	if err != nil || again != id {
+		t.Fatalf("re-registering returned %q, %v, want %q", again, err, id)
+	}
+
+	// A decoder that did not register the set loads it from the store once
+	fresh := NewPayloadDecoder(store, "", 1)
+	for i := 0; i < 3; i++ {
+		if _, err := fresh.loadDescriptorSet(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.loads != 1 {
+		t.Fatalf("store was read %d times, want 1", store.loads)
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRUCache[int](2)
+	cache.add("a", 1)
+	cache.add("b", 2)
+	cache.get("a")
+	cache.add("c", 3)
+
+	if _, ok := cache.get("b"); ok {
+		t.Fatal("least recently used entry was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Fatalf("entry %s was evicted", key)
+		}
+	}
+	if cache.len() != 2 {
+		t.Fatalf("len = %d, want 2", cache.len())
+	}
+}
+
+// avroLong zig-zag encodes v as an Avro long
+func avroLong(v int64) []byte {
+	return binary.AppendUvarint(nil, uint64((v<<1)^(v>>63)))
+}
+
+func avroBytes(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func avroString(s string) []byte {
+	return append(avroLong(int64(len(s))), s...)
+}
+
+const orderAvroSchema = `{"type":"record","name":"Order","fields":[
+	{"name":"id","type":"long"},
+	{"name":"name","type":"string"},
+	{"name":"tags","type":{"type":"array","items":"string"}}
+]}`
+
+func TestDecodeAvro(t *testing.T) {
+	decoder := NewPayloadDecoder(newMemoryDescriptorStore(), "", 0)
+
+	valid := avroBytes(avroLong(42), avroString("widget"), avroLong(2), avroString("a"), avroString("b"), avroLong(0))
+
+	tests := []struct {
+		name    string
+		schema  string
+		payload []byte
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			schema:  orderAvroSchema,
+			payload: valid,
+			want:    `{"id":42,"name":"widget","tags":["a","b"]}`,
+		},
+		{
+			name:    "negative block count",
+			schema:  orderAvroSchema,
+			payload: avroBytes(avroLong(42), avroString("widget"), avroLong(-1), avroLong(2), avroString("a"), avroLong(0)),
+			want:    `{"id":42,"name":"widget","tags":["a"]}`,
+		},
+		{
+			name:    "truncated",
+			schema:  orderAvroSchema,
+			payload: valid[:len(valid)-2],
+			wantErr: "invalid length 1",
+		},
+		{
+			name:    "unterminated varint",
+			schema:  orderAvroSchema,
+			payload: []byte{0x80},
+			wantErr: "invalid varint",
+		},
+		{
+			name:    "string length overflows offset",
+			schema:  orderAvroSchema,
+			payload: avroBytes(avroLong(42), avroLong(1<<62), []byte("x")),
+			wantErr: "invalid length",
+		},
+		{
+			name:    "negative string length",
+			schema:  orderAvroSchema,
+			payload: avroBytes(avroLong(42), avroLong(-5)),
+			wantErr: "invalid length",
+		},
+		{
+			name:    "varint overflow",
+			schema:  `"long"`,
+			payload: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+			wantErr: "invalid varint",
+		},
+		{
+			name:    "huge block of null items",
+			schema:  `{"type":"array","items":"null"}`,
+			payload: avroLong(1 << 40),
+			wantErr: "collection exceeds",
+		},
+		{
+			name:    "blocks adding up past the item limit",
+			schema:  `{"type":"array","items":"null"}`,
+			payload: avroBytes(avroLong(maxAvroCollectionItems), avroLong(1)),
+			wantErr: "collection exceeds",
+		},
+		{
+			name:    "minimum block count",
+			schema:  `{"type":"map","values":"null"}`,
+			payload: avroLong(-1 << 63),
+			wantErr: "invalid block count",
+		},
+		{
+			name:    "union index out of range",
+			schema:  `["null","string"]`,
+			payload: avroLong(7),
+			wantErr: "union index 7 out of range",
+		},
+		{
+			name:    "enum index out of range",
+			schema:  `{"type":"enum","name":"Color","symbols":["RED"]}`,
+			payload: avroLong(-1),
+			wantErr: "enum index -1 out of range",
+		},
+		{
+			name:    "deeply nested recursive record",
+			schema:  `{"type":"record","name":"Node","fields":[{"name":"next","type":["null","Node"]}]}`,
+			payload: []byte(strings.Repeat("\x02", 1000)),
+			wantErr: "nested deeper than",
+		},
+		{
+			name:    "fixed longer than payload",
+			schema:  `{"type":"fixed","name":"Hash","size":16}`,
+			payload: []byte{1, 2, 3},
+			wantErr: "unexpected end of data",
+		},
+		{
+			name:    "missing schema",
+			payload: valid,
+			wantErr: "schema is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := map[string]interface{}{"payload": base64.StdEncoding.EncodeToString(tt.payload)}
+			if tt.schema != "" {
+				input["schema"] = tt.schema
+			}
+
+			got, err := encodeDecodedFields(decoder.DecodeAvro(decodeInput(t, input)))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("decoded = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeAvroUsesConfiguredRegistry(t *testing.T) {
+	var requests int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/schemas/ids/7" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"schema": orderAvroSchema})
+	}))
+	defer registry.Close()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request-supplied registry was contacted: %s", r.URL)
+	}))
+	defer other.Close()
+
+	decoder := NewPayloadDecoder(newMemoryDescriptorStore(), registry.URL+"/", 0)
+
+	framed := append([]byte{0, 0, 0, 0, 7}, avroBytes(avroLong(1), avroString("x"), avroLong(0))...)
+	input := decodeInput(t, map[string]interface{}{
+		"payload":      base64.StdEncoding.EncodeToString(framed),
+		"registry_url": other.URL,
+	})
+
+	for i := 0; i < 2; i++ {
+		got, err := encodeDecodedFields(decoder.DecodeAvro(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"id":1,"name":"x","tags":null}`; got != want {
+			t.Fatalf("decoded = %s, want %s", got, want)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("registry was queried %d times, want 1", n)
+	}
+
+	// Without a configured registry a framed payload needs an inline schema
+	unconfigured := NewPayloadDecoder(newMemoryDescriptorStore(), "", 0)
+	if _, err := unconfigured.DecodeAvro(input); err == nil {
+		t.Fatal("framed payload without schema or registry was decoded")
+	}
+}
@@ -0,0 +1,380 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"github.com/redbco/redb-open/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// pluginImplementation marks the transformations of the database that are plugins
+const pluginImplementation = "plugin"
+
+// defaultPluginTimeout bounds each call to a plugin that has no timeout of its own
+const defaultPluginTimeout = 10 * time.Second
+
+// pluginNamePattern is the form of plugin names: snake_case like the built-in transformations,
+// so that they never contain the chain separator
+var pluginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// pluginCardinalities are the cardinalities of the transformation types a plugin can have
+var pluginCardinalities = map[string]string{
+	"passthrough":    "one-to-one",
+	"merge":          "many-to-one",
+	"split":          "one-to-many",
+	"generator":      "generator",
+	"null_returning": "sink",
+}
+
+// TransformationPlugin is a user-defined transformation run outside the service. It is used by
+// name in mapping rules and chains like a built-in transformation.
+type TransformationPlugin struct {
+	ID          string
+	TenantID    string
+	Name        string
+	Description string
+	Kind        pb.TransformationPluginKind
+	Endpoint    string
+	Type        string
+	InputType   string
+	OutputType  string
+	Timeout     time.Duration
+	OwnerID     string
+}
+
+// Cardinality returns how many values the plugin reads and returns, e.g. "many-to-one"
+func (p *TransformationPlugin) Cardinality() string {
+	return pluginCardinalities[p.Type]
+}
+
+// Metadata returns the metadata of the plugin as reported for transformations
+func (p *TransformationPlugin) Metadata() *pb.TransformationMetadata {
+	return &pb.TransformationMetadata{
+		Name:                  p.Name,
+		Description:           p.Description,
+		Type:                  p.Type,
+		RequiresSource:        p.Type != "generator",
+		RequiresTarget:        p.Type != "null_returning",
+		AllowsMultipleTargets: p.Type != "merge" && p.Type != "null_returning",
+		InputType:             p.InputType,
+		OutputType:            p.OutputType,
+		Cardinality:           p.Cardinality(),
+		PluginKind:            p.Kind,
+	}
+}
+
+// Info returns the plugin as reported by ListTransformationPlugins
+func (p *TransformationPlugin) Info() *pb.TransformationPluginInfo {
+	return &pb.TransformationPluginInfo{
+		PluginId:       p.ID,
+		TenantId:       p.TenantID,
+		Name:           p.Name,
+		Description:    p.Description,
+		Kind:           p.Kind,
+		Endpoint:       p.Endpoint,
+		Type:           p.Type,
+		InputType:      p.InputType,
+		OutputType:     p.OutputType,
+		Cardinality:    p.Cardinality(),
+		TimeoutSeconds: int32(p.Timeout / time.Second),
+		OwnerId:        p.OwnerID,
+	}
+}
+
+// validatePlugin checks a plugin before it is registered and fills in its defaults: a
+// passthrough type, string input and output types, and the default timeout
+func validatePlugin(p *TransformationPlugin) error {
+	if !pluginNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid plugin name '%s': use lowercase letters, digits and underscores, starting with a letter", p.Name)
+	}
+	if _, builtIn := getTransformationMetadata(p.Name); builtIn {
+		return fmt.Errorf("plugin name '%s' is the name of a built-in transformation", p.Name)
+	}
+
+	switch p.Kind {
+	case pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC:
+		if p.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for gRPC plugins")
+		}
+	case pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_WASM:
+		return fmt.Errorf("WASM plugins are not supported: the transformation service has no WebAssembly runtime, serve the module behind a gRPC endpoint instead")
+	default:
+		return fmt.Errorf("plugin kind is required")
+	}
+
+	if p.Type == "" {
+		p.Type = "passthrough"
+	}
+	if _, ok := pluginCardinalities[p.Type]; !ok {
+		return fmt.Errorf("invalid plugin type '%s': expected passthrough, merge, split, generator or null_returning", p.Type)
+	}
+	if p.InputType == "" && p.Type != "generator" {
+		p.InputType = "string"
+	}
+	if p.OutputType == "" && p.Type != "null_returning" {
+		p.OutputType = "string"
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if p.Timeout == 0 {
+		p.Timeout = defaultPluginTimeout
+	}
+	return nil
+}
+
+// PluginManager keeps the transformation plugins of all tenants and the connections to their
+// endpoints. Plugin names are unique across tenants, as transformation requests carry no tenant.
+type PluginManager struct {
+	mu      sync.RWMutex
+	plugins map[string]*TransformationPlugin // by name
+	conns   map[string]*grpc.ClientConn      // by endpoint
+	db      *DatabaseOps
+	logger  *logger.Logger
+}
+
+// NewPluginManager creates a plugin manager on the given database
+func NewPluginManager(db *DatabaseOps, logger *logger.Logger) *PluginManager {
+	return &PluginManager{
+		plugins: make(map[string]*TransformationPlugin),
+		conns:   make(map[string]*grpc.ClientConn),
+		db:      db,
+		logger:  logger,
+	}
+}
+
+// Load loads the plugins of all tenants from the database
+func (m *PluginManager) Load(ctx context.Context) error {
+	records, err := m.db.ListPluginTransformations(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, record := range records {
+		plugin := pluginFromRecord(record)
+		m.plugins[plugin.Name] = plugin
+	}
+	m.logger.Infof("Loaded %d transformation plugins", len(records))
+	return nil
+}
+
+// Get returns the plugin with the given name, or nil
+func (m *PluginManager) Get(name string) *TransformationPlugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.plugins[name]
+}
+
+// Resolve returns the plugin with the given name, loading it from the database when another
+// instance of the service registered it. It returns nil when there is no such plugin.
+func (m *PluginManager) Resolve(ctx context.Context, name string) *TransformationPlugin {
+	if plugin := m.Get(name); plugin != nil || m.db == nil || !pluginNamePattern.MatchString(name) {
+		return plugin
+	}
+
+	record, err := m.db.GetPluginTransformation(ctx, name)
+	if err != nil {
+		return nil
+	}
+	plugin := pluginFromRecord(record)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plugins[plugin.Name] = plugin
+	return plugin
+}
+
+// List returns the plugins of a tenant, or of all tenants when tenantID is empty, by name
+func (m *PluginManager) List(tenantID string) []*TransformationPlugin {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plugins := make([]*TransformationPlugin, 0, len(m.plugins))
+	for _, plugin := range m.plugins {
+		if tenantID == "" || plugin.TenantID == tenantID {
+			plugins = append(plugins, plugin)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Register validates a plugin, stores it and makes it available to transformations
+func (m *PluginManager) Register(ctx context.Context, plugin *TransformationPlugin) error {
+	if err := validatePlugin(plugin); err != nil {
+		return err
+	}
+	if existing := m.Resolve(ctx, plugin.Name); existing != nil {
+		return fmt.Errorf("a transformation plugin named '%s' already exists", plugin.Name)
+	}
+
+	record := pluginToRecord(plugin)
+	pluginID, err := m.db.CreateTransformation(ctx, record)
+	if err != nil {
+		return err
+	}
+	plugin.ID = pluginID
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plugins[plugin.Name] = plugin
+	m.logger.Infof("Registered transformation plugin %s at %s", plugin.Name, plugin.Endpoint)
+	return nil
+}
+
+// Delete removes a plugin of a tenant. Rules using it fail until it is registered again.
+func (m *PluginManager) Delete(ctx context.Context, tenantID, name string) error {
+	plugin := m.Resolve(ctx, name)
+	if plugin == nil || plugin.TenantID != tenantID {
+		return fmt.Errorf("transformation plugin '%s' not found", name)
+	}
+
+	if err := m.db.DeleteTransformation(ctx, plugin.ID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.plugins, name)
+	m.logger.Infof("Deleted transformation plugin %s", name)
+	return nil
+}
+
+// Transform runs a step of a transformation on a plugin and returns the values it produced,
+// which must number outputCount
+func (m *PluginManager) Transform(ctx context.Context, plugin *TransformationPlugin, req *pb.TransformRequest, outputCount int) ([]string, error) {
+	conn, err := m.conn(plugin.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, plugin.Timeout)
+	defer cancel()
+
+	resp, err := pb.NewTransformationPluginClient(conn).Transform(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %v", plugin.Name, err)
+	}
+	if resp.Status != commonv1.Status_STATUS_SUCCESS {
+		return nil, fmt.Errorf("plugin %s failed: %s", plugin.Name, resp.StatusMessage)
+	}
+
+	outputs := resp.Outputs
+	if len(outputs) == 0 {
+		outputs = []string{resp.Output}
+	}
+	if len(outputs) != outputCount {
+		return nil, fmt.Errorf("plugin %s returned %d values, expected %d", plugin.Name, len(outputs), outputCount)
+	}
+	return outputs, nil
+}
+
+// conn returns the connection to a plugin endpoint, shared by the plugins it serves. Plugin
+// endpoints are outside the trust domain of the services, so the connection is not mTLS.
+func (m *PluginManager) conn(endpoint string) (*grpc.ClientConn, error) {
+	m.mu.RLock()
+	conn, exists := m.conns[endpoint]
+	m.mu.RUnlock()
+	if exists {
+		return conn, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conn, exists := m.conns[endpoint]; exists {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to plugin endpoint %s: %v", endpoint, err)
+	}
+	m.conns[endpoint] = conn
+	return conn, nil
+}
+
+// Close closes the connections to the plugin endpoints
+func (m *PluginManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for endpoint, conn := range m.conns {
+		conn.Close()
+		delete(m.conns, endpoint)
+	}
+}
+
+// pluginToRecord converts a plugin to its transformation record. The endpoint, kind and
+// timeout are kept in the metadata, the data types in the I/O definitions.
+func pluginToRecord(p *TransformationPlugin) *TransformationRecord {
+	record := &TransformationRecord{
+		TenantID:       p.TenantID,
+		Name:           p.Name,
+		Description:    p.Description,
+		Type:           p.Type,
+		Version:        "1.0.0",
+		Function:       p.Endpoint,
+		Cardinality:    p.Cardinality(),
+		RequiresInput:  p.Type != "generator",
+		ProducesOutput: p.Type != "null_returning",
+		Implementation: pluginImplementation,
+		Metadata: map[string]interface{}{
+			"plugin_kind":     "grpc",
+			"endpoint":        p.Endpoint,
+			"timeout_seconds": int(p.Timeout / time.Second),
+		},
+		Enabled: true,
+		OwnerID: p.OwnerID,
+	}
+	if p.InputType != "" {
+		record.IODefinitions = append(record.IODefinitions, IODefinitionRecord{
+			IOType: "input", Name: "input", DataType: p.InputType, IsMandatory: true,
+		})
+	}
+	if p.OutputType != "" {
+		record.IODefinitions = append(record.IODefinitions, IODefinitionRecord{
+			IOType: "output", Name: "output", DataType: p.OutputType, IsMandatory: true,
+		})
+	}
+	return record
+}
+
+// pluginFromRecord converts a transformation record of a plugin back to the plugin
+func pluginFromRecord(record *TransformationRecord) *TransformationPlugin {
+	plugin := &TransformationPlugin{
+		ID:          record.ID,
+		TenantID:    record.TenantID,
+		Name:        record.Name,
+		Description: record.Description,
+		Kind:        pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC,
+		Endpoint:    record.Function,
+		Type:        record.Type,
+		Timeout:     defaultPluginTimeout,
+		OwnerID:     record.OwnerID,
+	}
+	if endpoint, ok := record.Metadata["endpoint"].(string); ok && endpoint != "" {
+		plugin.Endpoint = endpoint
+	}
+	if seconds, ok := record.Metadata["timeout_seconds"].(float64); ok && seconds > 0 {
+		plugin.Timeout = time.Duration(seconds) * time.Second
+	}
+	for _, ioDef := range record.IODefinitions {
+		switch ioDef.IOType {
+		case "input":
+			plugin.InputType = ioDef.DataType
+		case "output":
+			plugin.OutputType = ioDef.DataType
+		}
+	}
+	return plugin
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"google.golang.org/grpc"
+)
+
+// testPlugin masks all but the last 4 characters of values, and splits or merges them on ";"
+type testPlugin struct {
+	pb.UnimplementedTransformationPluginServer
+}
+
+func (testPlugin) Transform(ctx context.Context, req *pb.TransformRequest) (*pb.TransformResponse, error) {
+	switch req.FunctionName {
+	case "mask":
+		if len(req.Input) <= 4 {
+			return &pb.TransformResponse{Output: req.Input, Status: commonv1.Status_STATUS_SUCCESS}, nil
+		}
+		return &pb.TransformResponse{Output: strings.Repeat("*", len(req.Input)-4) + req.Input[len(req.Input)-4:], Status: commonv1.Status_STATUS_SUCCESS}, nil
+	case "join_semicolon":
+		return &pb.TransformResponse{Output: strings.Join(req.Inputs, ";"), Status: commonv1.Status_STATUS_SUCCESS}, nil
+	case "split_semicolon":
+		return &pb.TransformResponse{Outputs: strings.SplitN(req.Input, ";", int(req.OutputCount)), Status: commonv1.Status_STATUS_SUCCESS}, nil
+	default:
+		return &pb.TransformResponse{StatusMessage: "unknown function " + req.FunctionName, Status: commonv1.Status_STATUS_FAILURE}, nil
+	}
+}
+
+func startTestPlugin(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterTransformationPluginServer(server, testPlugin{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestExecuteTransformationPlugins(t *testing.T) {
+	endpoint := startTestPlugin(t)
+	plugins := NewPluginManager(nil, nil)
+	t.Cleanup(plugins.Close)
+	for _, plugin := range []*TransformationPlugin{
+		{Name: "mask", Kind: pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC, Endpoint: endpoint},
+		{Name: "join_semicolon", Kind: pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC, Endpoint: endpoint, Type: "merge"},
+		{Name: "split_semicolon", Kind: pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC, Endpoint: endpoint, Type: "split"},
+		{Name: "broken", Kind: pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC, Endpoint: endpoint},
+	} {
+		if err := validatePlugin(plugin); err != nil {
+			t.Fatalf("validatePlugin(%s): %v", plugin.Name, err)
+		}
+		plugins.plugins[plugin.Name] = plugin
+	}
+	s := &TransformationServer{engine: &Engine{plugins: plugins}}
+	ctx := context.Background()
+
+	outputs, err := s.executeTransformation(ctx, &pb.TransformRequest{FunctionName: "trim|mask", Input: " 4111111111111111 "})
+	if err != nil || outputs[0] != "************1111" {
+		t.Fatalf("trim|mask = %v, %v", outputs, err)
+	}
+
+	outputs, err = s.executeTransformation(ctx, &pb.TransformRequest{FunctionName: "join_semicolon|uppercase", Inputs: []string{"a", "b"}})
+	if err != nil || outputs[0] != "A;B" {
+		t.Fatalf("join_semicolon|uppercase = %v, %v", outputs, err)
+	}
+
+	outputs, err = s.executeTransformation(ctx, &pb.TransformRequest{FunctionName: "lowercase|split_semicolon", Input: "X;Y", OutputCount: 2})
+	if err != nil || len(outputs) != 2 || outputs[0] != "x" || outputs[1] != "y" {
+		t.Fatalf("lowercase|split_semicolon = %v, %v", outputs, err)
+	}
+
+	// Plugins are placed in chains by their type like built-in transformations
+	if _, err := s.executeTransformation(ctx, &pb.TransformRequest{FunctionName: "trim|join_semicolon", Inputs: []string{"a", "b"}}); err == nil {
+		t.Fatal("expected a merge plugin after the first step to be rejected")
+	}
+	if _, err := s.executeTransformation(ctx, &pb.TransformRequest{FunctionName: "broken", Input: "a"}); err == nil || !strings.Contains(err.Error(), "unknown function broken") {
+		t.Fatalf("expected the failure of the plugin, got %v", err)
+	}
+
+	metadata, ok := s.transformationMetadata(ctx, "split_semicolon")
+	if !ok || metadata.Cardinality != "one-to-many" || metadata.PluginKind != pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC {
+		t.Fatalf("metadata of split_semicolon = %v", metadata)
+	}
+}
+
+func TestValidatePlugin(t *testing.T) {
+	grpcKind := pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_GRPC
+
+	plugin := &TransformationPlugin{Name: "normalize_phone", Kind: grpcKind, Endpoint: "cleanser:50051"}
+	if err := validatePlugin(plugin); err != nil {
+		t.Fatalf("validatePlugin: %v", err)
+	}
+	if plugin.Type != "passthrough" || plugin.InputType != "string" || plugin.OutputType != "string" || plugin.Timeout != defaultPluginTimeout {
+		t.Fatalf("defaults not filled in: %+v", plugin)
+	}
+
+	for _, invalid := range []*TransformationPlugin{
+		{Name: "Normalize", Kind: grpcKind, Endpoint: "cleanser:50051"},
+		{Name: "trim|upper", Kind: grpcKind, Endpoint: "cleanser:50051"},
+		{Name: "uppercase", Kind: grpcKind, Endpoint: "cleanser:50051"},
+		{Name: "normalize", Kind: grpcKind},
+		{Name: "normalize", Kind: grpcKind, Endpoint: "cleanser:50051", Type: "aggregate"},
+		{Name: "normalize", Kind: pb.TransformationPluginKind_TRANSFORMATION_PLUGIN_KIND_WASM},
+		{Name: "normalize"},
+	} {
+		if err := validatePlugin(invalid); err == nil {
+			t.Errorf("expected plugin %+v to be rejected", invalid)
+		}
+	}
+}
@@ -37,6 +37,10 @@ func (r *TransformationRegistry) RegisterBuiltIn() {
 
 	builtIns := GetBuiltInTransformations()
 	for _, builtIn := range builtIns {
+		// Functions with engine state are bound separately with RegisterFunction
+		if builtIn.ExecuteFunc == nil {
+			continue
+		}
 		r.functions[builtIn.Implementation] = builtIn.ExecuteFunc
 		r.logger.Debugf("Registered built-in function: %s", builtIn.Name)
 	}
@@ -44,6 +48,14 @@ func (r *TransformationRegistry) RegisterBuiltIn() {
 	r.logger.Info("Built-in transformation functions registered")
 }
 
+// RegisterFunction registers the implementation of a built-in function that depends on engine state
+func (r *TransformationRegistry) RegisterFunction(implementation string, fn interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.functions[implementation] = fn
+}
+
 // LoadFromDatabase loads transformation definitions from the database for a tenant
 func (r *TransformationRegistry) LoadFromDatabase(ctx context.Context, tenantID string) error {
 	r.logger.Infof("Loading transformations from database for tenant: %s", tenantID)
@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -52,7 +53,7 @@ func (s *TransformationServer) Transform(ctx context.Context, req *pb.TransformR
 	}
 
 	// Execute transformation function
-	output, err := s.executeTransformation(req)
+	outputs, err := s.executeTransformation(ctx, req)
 	if err != nil {
 		atomic.AddInt64(&s.engine.metrics.errors, 1)
 		return &pb.TransformResponse{
@@ -62,54 +63,206 @@ func (s *TransformationServer) Transform(ctx context.Context, req *pb.TransformR
 		}, nil
 	}
 
-	return &pb.TransformResponse{
-		Output:        output,
+	response := &pb.TransformResponse{
+		Output:        outputs[0],
 		StatusMessage: "transformation completed successfully",
 		Status:        commonv1.Status_STATUS_SUCCESS,
-	}, nil
+	}
+	if len(req.Inputs) > 0 || req.OutputCount > 0 {
+		response.Outputs = outputs
+	}
+	return response, nil
+}
+
+// TransformationChainSeparator separates the steps of a chain of transformations in a function
+// name, e.g. "trim|lowercase|hash_sha256". The steps are applied in order, each to the output of
+// the previous one.
+const TransformationChainSeparator = "|"
+
+// executeTransformation applies a transformation or a chain of transformations to the values of the
+// source columns of a rule, and returns those of its target columns: one value per input and
+// output unless the request has several inputs or an output count. A merge step (concat) joins
+// the inputs and can only start a chain, a split step (split) divides its value into the output
+// count and can only end one; the other steps transform a single value. Without a split step,
+// the value is written to every target column. Plugins run as the steps of their type.
+func (s *TransformationServer) executeTransformation(ctx context.Context, req *pb.TransformRequest) ([]string, error) {
+	values := req.Inputs
+	if len(values) == 0 {
+		values = []string{req.Input}
+	}
+	outputCount := int(req.OutputCount)
+	if outputCount < 1 {
+		outputCount = 1
+	}
+	separator := defaultSeparator
+	if req.Parameters != nil {
+		if value, ok := req.Parameters.Fields["separator"]; ok {
+			separator = value.GetStringValue()
+		}
+	}
+
+	steps := strings.Split(req.FunctionName, TransformationChainSeparator)
+	for i, step := range steps {
+		step = strings.TrimSpace(step)
+		outputs, err := s.executeStep(ctx, req, step, values, separator, outputCount, i == 0, i == len(steps)-1)
+		if err != nil {
+			if len(steps) == 1 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("step %d (%s) of the transformation chain: %w", i+1, step, err)
+		}
+		values = outputs
+	}
+
+	if len(values) == 1 && outputCount > 1 {
+		fanned := make([]string, outputCount)
+		for i := range fanned {
+			fanned[i] = values[0]
+		}
+		values = fanned
+	}
+	if len(values) != outputCount {
+		return nil, fmt.Errorf("transformation %s returned %d values for %d target columns", req.FunctionName, len(values), outputCount)
+	}
+	return values, nil
+}
+
+// executeStep applies a step of a transformation to the values of the previous step
+func (s *TransformationServer) executeStep(ctx context.Context, req *pb.TransformRequest, step string, values []string, separator string, outputCount int, first, last bool) ([]string, error) {
+	var stepType string
+	if metadata, ok := s.transformationMetadata(ctx, step); ok {
+		stepType = metadata.Type
+	}
+
+	stepOutputs := 1
+	switch stepType {
+	case "merge":
+		if !first {
+			return nil, fmt.Errorf("%s merges the values of the source columns and can only be the first step", step)
+		}
+		if len(values) < 2 {
+			return nil, fmt.Errorf("%s merges the values of 2 or more source columns, got %d", step, len(values))
+		}
+	case "split":
+		if !last {
+			return nil, fmt.Errorf("%s splits a value into the target columns and can only be the last step", step)
+		}
+		if outputCount < 2 {
+			return nil, fmt.Errorf("%s splits a value into 2 or more target columns, got %d", step, outputCount)
+		}
+		stepOutputs = outputCount
+	default:
+		if len(values) > 1 {
+			return nil, fmt.Errorf("%s transforms a single value; merge the %d source columns first", step, len(values))
+		}
+	}
+
+	if plugin := s.plugin(ctx, step); plugin != nil {
+		pluginReq := &pb.TransformRequest{
+			FunctionName: step,
+			Input:        values[0],
+			Parameters:   req.Parameters,
+			Key:          req.Key,
+		}
+		if stepType == "merge" {
+			pluginReq.Inputs = values
+		}
+		if stepType == "split" {
+			pluginReq.OutputCount = int32(stepOutputs)
+		}
+		return s.engine.plugins.Transform(ctx, plugin, pluginReq, stepOutputs)
+	}
+
+	switch stepType {
+	case "merge":
+		return []string{transformConcat(values, separator)}, nil
+	case "split":
+		return transformSplit(values[0], separator, outputCount), nil
+	default:
+		output, err := s.executeFunction(ctx, step, values[0], req)
+		if err != nil {
+			return nil, err
+		}
+		return []string{output}, nil
+	}
+}
+
+// plugin returns the transformation plugin with the given name, or nil
+func (s *TransformationServer) plugin(ctx context.Context, name string) *TransformationPlugin {
+	if s.engine == nil || s.engine.plugins == nil {
+		return nil
+	}
+	return s.engine.plugins.Resolve(ctx, name)
 }
 
-func (s *TransformationServer) executeTransformation(req *pb.TransformRequest) (string, error) {
+// transformationMetadata returns the metadata of a built-in transformation or of a plugin
+func (s *TransformationServer) transformationMetadata(ctx context.Context, name string) (*pb.TransformationMetadata, bool) {
+	if metadata, exists := getTransformationMetadata(name); exists {
+		return metadata, true
+	}
+	if plugin := s.plugin(ctx, name); plugin != nil {
+		return plugin.Metadata(), true
+	}
+	return nil, false
+}
+
+// executeFunction applies a single transformation function to the input, with the options and
+// the tenant of the request for the functions that take them
+func (s *TransformationServer) executeFunction(ctx context.Context, functionName, input string, req *pb.TransformRequest) (string, error) {
 	// Route to specific transformation function based on function_name
-	switch req.FunctionName {
+	switch functionName {
 	case "direct_mapping":
-		return transformDirectMapping(req.Input), nil
+		return transformDirectMapping(input), nil
 	case "uppercase":
-		return transformUppercase(req.Input), nil
+		return transformUppercase(input), nil
 	case "lowercase":
-		return transformLowercase(req.Input), nil
+		return transformLowercase(input), nil
+	case "trim":
+		return transformTrim(input), nil
 	case "reverse":
-		return transformReverse(req.Input), nil
+		return transformReverse(input), nil
 	case "base64_encode":
-		return transformBase64Encode(req.Input), nil
+		return transformBase64Encode(input), nil
 	case "base64_decode":
-		return transformBase64Decode(req.Input)
+		return transformBase64Decode(input)
 	case "json_format":
-		return transformJSONFormat(req.Input)
+		return transformJSONFormat(input)
 	case "xml_format":
-		return transformXMLFormat(req.Input)
+		return transformXMLFormat(input)
 	case "csv_to_json":
-		return transformCSVToJSON(req.Input)
+		return transformCSVToJSON(input)
 	case "json_to_csv":
-		return transformJSONToCSV(req.Input)
+		return transformJSONToCSV(input)
 	case "hash_sha256":
-		return transformHashSHA256(req.Input), nil
+		return transformHashSHA256(input), nil
 	case "hash_md5":
-		return transformHashMD5(req.Input), nil
+		return transformHashMD5(input), nil
 	case "url_encode":
-		return transformURLEncode(req.Input), nil
+		return transformURLEncode(input), nil
 	case "url_decode":
-		return transformURLDecode(req.Input)
+		return transformURLDecode(input)
 	case "timestamp_to_iso":
-		return transformTimestampToISO(req.Input)
+		return transformTimestampToISO(input)
 	case "iso_to_timestamp":
-		return transformISOToTimestamp(req.Input)
+		return transformISOToTimestamp(input)
 	case "uuid_generator":
 		return transformUUIDGenerator(), nil
 	case "null_export":
-		return transformNullExport(req.Input), nil
+		return transformNullExport(input), nil
+	case "window_aggregate":
+		return s.engine.windows.Process(input)
+	case "lookup":
+		return s.engine.lookups.Lookup(ctx, input, req.Parameters)
+	case "mask_format", "tokenize", "hash_salted":
+		return s.engine.maskingSalts.Mask(ctx, functionName, req.TenantId, input, req.Parameters)
+	case "redact_partial":
+		return redactPartial(input, req.Parameters)
+	case "protobuf_decode":
+		return encodeDecodedFields(s.engine.payloads.DecodeProtobuf(input))
+	case "avro_decode":
+		return encodeDecodedFields(s.engine.payloads.DecodeAvro(input))
 	default:
-		return "", fmt.Errorf("unknown transformation function: %s", req.FunctionName)
+		return "", fmt.Errorf("unknown transformation function: %s", functionName)
 	}
 }
 
@@ -131,7 +284,7 @@ func (s *TransformationServer) GetTransformationMetadata(ctx context.Context, re
 	}
 
 	// Get metadata for the requested transformation
-	metadata, exists := getTransformationMetadata(req.TransformationName)
+	metadata, exists := s.transformationMetadata(ctx, req.TransformationName)
 	if !exists {
 		atomic.AddInt64(&s.engine.metrics.errors, 1)
 		return &pb.GetTransformationMetadataResponse{
@@ -148,7 +301,8 @@ func (s *TransformationServer) GetTransformationMetadata(ctx context.Context, re
 	}, nil
 }
 
-// ListTransformations returns a list of all available transformations
+// ListTransformations returns a list of all available transformations, with the plugins of the
+// tenant when the request has one
 func (s *TransformationServer) ListTransformations(ctx context.Context, req *pb.ListTransformationsRequest) (*pb.ListTransformationsResponse, error) {
 	s.engine.TrackOperation()
 	defer s.engine.UntrackOperation()
@@ -157,12 +311,41 @@ func (s *TransformationServer) ListTransformations(ctx context.Context, req *pb.
 
 	// Get all transformation metadata
 	transformations := getAllTransformationMetadata()
+	if req.TenantId != "" && s.engine.plugins != nil {
+		for _, plugin := range s.engine.plugins.List(req.TenantId) {
+			transformations = append(transformations, plugin.Metadata())
+		}
+	}
 
 	return &pb.ListTransformationsResponse{
 		Transformations: transformations,
 	}, nil
 }
 
+// RefreshLookupTable drops the cached entries of a lookup table, or of all lookup tables when no
+// name is given, so that the lookup transformation reads them again from the database
+func (s *TransformationServer) RefreshLookupTable(ctx context.Context, req *pb.RefreshLookupTableRequest) (*pb.RefreshLookupTableResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+
+	if s.engine.lookups == nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.RefreshLookupTableResponse{
+			StatusMessage: "lookup tables are not initialized",
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	s.engine.lookups.Refresh(req.LookupTableName)
+
+	return &pb.RefreshLookupTableResponse{
+		StatusMessage: "lookup table cache refreshed successfully",
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
 // getTransformationMetadata returns metadata for a specific transformation
 func getTransformationMetadata(name string) (*pb.TransformationMetadata, bool) {
 	metadataMap := map[string]*pb.TransformationMetadata{
@@ -190,6 +373,14 @@ func getTransformationMetadata(name string) (*pb.TransformationMetadata, bool) {
 			RequiresTarget:        true,
 			AllowsMultipleTargets: true,
 		},
+		"trim": {
+			Name:                  "trim",
+			Description:           "Remove leading and trailing whitespace",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
 		"reverse": {
 			Name:                  "reverse",
 			Description:           "Reverse the input string",
@@ -310,20 +501,137 @@ func getTransformationMetadata(name string) (*pb.TransformationMetadata, bool) {
 			RequiresTarget:        false,
 			AllowsMultipleTargets: false,
 		},
+		"mask_format": {
+			Name:                  "mask_format",
+			Description:           "Mask letters and digits with others of the same kind, keeping the format, e.g. of emails and phone numbers",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"tokenize": {
+			Name:                  "tokenize",
+			Description:           "Replace a value with a deterministic token, the same for the same value within a tenant",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"redact_partial": {
+			Name:                  "redact_partial",
+			Description:           "Redact all but the first and last characters of a value, e.g. all but the last 4 digits of a card number",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"hash_salted": {
+			Name:                  "hash_salted",
+			Description:           "Generate an HMAC-SHA256 hash keyed with the salt of the tenant",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"lookup": {
+			Name:                  "lookup",
+			Description:           "Map values through a lookup table, e.g. legacy country codes to ISO codes",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: false,
+		},
+		"window_aggregate": {
+			Name:                  "window_aggregate",
+			Description:           "Aggregate stream events into tumbling or sliding windows with watermark-based late-data handling",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: false,
+		},
+		"concat": {
+			Name:                  "concat",
+			Description:           "Join the values of several source columns into one, e.g. first and last name into a full name",
+			Type:                  "merge",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: false,
+		},
+		"split": {
+			Name:                  "split",
+			Description:           "Split a value into those of several target columns, e.g. a full name into first and last name",
+			Type:                  "split",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"protobuf_decode": {
+			Name:                  "protobuf_decode",
+			Description:           "Decode a binary protobuf payload into structured fields using a registered descriptor set",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
+		"avro_decode": {
+			Name:                  "avro_decode",
+			Description:           "Decode a binary Avro payload into structured fields using an inline or registry schema",
+			Type:                  "passthrough",
+			RequiresSource:        true,
+			RequiresTarget:        true,
+			AllowsMultipleTargets: true,
+		},
 	}
 
 	metadata, exists := metadataMap[name]
+	if exists {
+		metadata.InputType, metadata.OutputType = builtInIOTypes(name)
+		metadata.Cardinality = builtInCardinality(name)
+	}
 	return metadata, exists
 }
 
+// builtInIOTypes returns the data types a built-in transformation reads and returns, as in its
+// I/O definitions. A type is empty when the transformation has no such I/O.
+func builtInIOTypes(name string) (string, string) {
+	var inputType, outputType string
+	for _, builtIn := range GetBuiltInTransformations() {
+		if builtIn.Name != name {
+			continue
+		}
+		for _, ioDef := range builtIn.IODefinitions {
+			switch {
+			case ioDef.IOType == "input" && inputType == "":
+				inputType = ioDef.DataType
+			case ioDef.IOType == "output" && outputType == "":
+				outputType = ioDef.DataType
+			}
+		}
+	}
+	return inputType, outputType
+}
+
+// builtInCardinality returns how many values a built-in transformation reads and returns, e.g.
+// "many-to-one"
+func builtInCardinality(name string) string {
+	for _, builtIn := range GetBuiltInTransformations() {
+		if builtIn.Name == name {
+			return builtIn.Cardinality
+		}
+	}
+	return ""
+}
+
 // getAllTransformationMetadata returns all transformation metadata
 func getAllTransformationMetadata() []*pb.TransformationMetadata {
 	transformations := []string{
-		"direct_mapping", "uppercase", "lowercase", "reverse",
+		"direct_mapping", "uppercase", "lowercase", "trim", "reverse",
 		"base64_encode", "base64_decode", "json_format", "xml_format",
 		"csv_to_json", "json_to_csv", "hash_sha256", "hash_md5",
 		"url_encode", "url_decode", "timestamp_to_iso", "iso_to_timestamp",
-		"uuid_generator", "null_export",
+		"uuid_generator", "null_export", "window_aggregate", "lookup",
+		"protobuf_decode", "avro_decode", "concat", "split",
+		"mask_format", "tokenize", "redact_partial", "hash_salted",
 	}
 
 	result := make([]*pb.TransformationMetadata, 0, len(transformations))
@@ -607,3 +915,35 @@ func (s *TransformationServer) GetTransformationIO(ctx context.Context, req *pb.
 		Status:        commonv1.Status_STATUS_SUCCESS,
 	}, nil
 }
+
+// RegisterDescriptorSet stores a protobuf descriptor set for protobuf_decode and returns its ID
+func (s *TransformationServer) RegisterDescriptorSet(ctx context.Context, req *pb.RegisterDescriptorSetRequest) (*pb.RegisterDescriptorSetResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+
+	// Validate request
+	if len(req.DescriptorSet) == 0 {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.RegisterDescriptorSetResponse{
+			StatusMessage: "descriptor_set is required",
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	descriptorID, err := s.engine.payloads.RegisterDescriptorSet(ctx, req.DescriptorSet)
+	if err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.RegisterDescriptorSetResponse{
+			StatusMessage: fmt.Sprintf("failed to register descriptor set: %v", err),
+			Status:        commonv1.Status_STATUS_ERROR,
+		}, nil
+	}
+
+	return &pb.RegisterDescriptorSetResponse{
+		DescriptorId:  descriptorID,
+		StatusMessage: "descriptor set registered successfully",
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	commonv1 "github.com/redbco/redb-open/api/proto/common/v1"
+	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
+)
+
+// RegisterTransformationPlugin registers a user-defined transformation served by an external
+// gRPC endpoint implementing the TransformationPlugin service
+func (s *TransformationServer) RegisterTransformationPlugin(ctx context.Context, req *pb.RegisterTransformationPluginRequest) (*pb.RegisterTransformationPluginResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+
+	// Validate request
+	if req.TenantId == "" || req.Name == "" || req.OwnerId == "" {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.RegisterTransformationPluginResponse{
+			StatusMessage: "tenant_id, name and owner_id are required",
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	plugin := &TransformationPlugin{
+		TenantID:    req.TenantId,
+		Name:        req.Name,
+		Description: req.Description,
+		Kind:        req.Kind,
+		Endpoint:    req.Endpoint,
+		Type:        req.Type,
+		InputType:   req.InputType,
+		OutputType:  req.OutputType,
+		Timeout:     time.Duration(req.TimeoutSeconds) * time.Second,
+		OwnerID:     req.OwnerId,
+	}
+	if err := s.engine.plugins.Register(ctx, plugin); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.RegisterTransformationPluginResponse{
+			StatusMessage: fmt.Sprintf("failed to register plugin: %v", err),
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	return &pb.RegisterTransformationPluginResponse{
+		Plugin:        plugin.Info(),
+		StatusMessage: "plugin registered successfully",
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// ListTransformationPlugins returns the transformation plugins of a tenant
+func (s *TransformationServer) ListTransformationPlugins(ctx context.Context, req *pb.ListTransformationPluginsRequest) (*pb.ListTransformationPluginsResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+
+	if req.TenantId == "" {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.ListTransformationPluginsResponse{
+			StatusMessage: "tenant_id is required",
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	plugins := s.engine.plugins.List(req.TenantId)
+	infos := make([]*pb.TransformationPluginInfo, len(plugins))
+	for i, plugin := range plugins {
+		infos[i] = plugin.Info()
+	}
+
+	return &pb.ListTransformationPluginsResponse{
+		Plugins:       infos,
+		StatusMessage: "plugins retrieved successfully",
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
+
+// DeleteTransformationPlugin removes a transformation plugin of a tenant
+func (s *TransformationServer) DeleteTransformationPlugin(ctx context.Context, req *pb.DeleteTransformationPluginRequest) (*pb.DeleteTransformationPluginResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	atomic.AddInt64(&s.engine.metrics.requestsProcessed, 1)
+
+	if req.TenantId == "" || req.Name == "" {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.DeleteTransformationPluginResponse{
+			StatusMessage: "tenant_id and name are required",
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	if err := s.engine.plugins.Delete(ctx, req.TenantId, req.Name); err != nil {
+		atomic.AddInt64(&s.engine.metrics.errors, 1)
+		return &pb.DeleteTransformationPluginResponse{
+			StatusMessage: fmt.Sprintf("failed to delete plugin: %v", err),
+			Status:        commonv1.Status_STATUS_FAILURE,
+		}, nil
+	}
+
+	return &pb.DeleteTransformationPluginResponse{
+		StatusMessage: "plugin deleted successfully",
+		Status:        commonv1.Status_STATUS_SUCCESS,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/redbco/redb-open/api/proto/transformation/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestExecuteTransformationChain(t *testing.T) {
+	s := &TransformationServer{}
+
+	outputs, err := s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "trim|lowercase|hash_sha256", Input: "  Alice@Example.COM "})
+	if err != nil {
+		t.Fatalf("executeTransformation: %v", err)
+	}
+	if want := transformHashSHA256("alice@example.com"); outputs[0] != want {
+		t.Errorf("chain output = %q, want %q", outputs[0], want)
+	}
+
+	outputs, err = s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "uppercase", Input: "abc"})
+	if err != nil || outputs[0] != "ABC" {
+		t.Errorf("single transformation = %v, %v, want ABC", outputs, err)
+	}
+
+	_, err = s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "trim|unknown", Input: "abc"})
+	if err == nil || !strings.Contains(err.Error(), "step 2 (unknown)") {
+		t.Errorf("unknown step error = %v, want it to name step 2", err)
+	}
+}
+
+func TestExecuteCompositeTransformation(t *testing.T) {
+	s := &TransformationServer{}
+
+	outputs, err := s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "concat|uppercase", Inputs: []string{"Ada", "Lovelace"}})
+	if err != nil || !reflect.DeepEqual(outputs, []string{"ADA LOVELACE"}) {
+		t.Errorf("many-to-one = %v, %v, want [ADA LOVELACE]", outputs, err)
+	}
+
+	separator, _ := structpb.NewStruct(map[string]interface{}{"separator": ", "})
+	outputs, err = s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "trim|split", Input: " 1 Main St, Springfield, IL, 62701 ", OutputCount: 3, Parameters: separator})
+	if want := []string{"1 Main St", "Springfield", "IL, 62701"}; err != nil || !reflect.DeepEqual(outputs, want) {
+		t.Errorf("one-to-many = %v, %v, want %v", outputs, err, want)
+	}
+
+	outputs, err = s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "split", Input: "Ada", OutputCount: 2})
+	if err != nil || !reflect.DeepEqual(outputs, []string{"Ada", ""}) {
+		t.Errorf("split of a single part = %v, %v, want [Ada \"\"]", outputs, err)
+	}
+
+	outputs, err = s.executeTransformation(context.Background(), &pb.TransformRequest{FunctionName: "lowercase", Input: "X", OutputCount: 2})
+	if err != nil || !reflect.DeepEqual(outputs, []string{"x", "x"}) {
+		t.Errorf("fan-out = %v, %v, want [x x]", outputs, err)
+	}
+
+	for _, req := range []*pb.TransformRequest{
+		{FunctionName: "uppercase", Inputs: []string{"a", "b"}},
+		{FunctionName: "concat", Inputs: []string{"a"}},
+		{FunctionName: "trim|concat", Inputs: []string{"a", "b"}},
+		{FunctionName: "split|trim", Input: "a b", OutputCount: 2},
+		{FunctionName: "split", Input: "a b"},
+	} {
+		if _, err := s.executeTransformation(context.Background(), req); err == nil {
+			t.Errorf("%s with %d inputs and %d outputs was accepted", req.FunctionName, len(req.Inputs), req.OutputCount)
+		}
+	}
+}
+
+func TestTransformationMetadataIOTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputType  string
+		outputType string
+	}{
+		{"trim", "string", "string"},
+		{"csv_to_json", "string", "json"},
+		{"direct_mapping", "any", "any"},
+		{"uuid_generator", "", "string"},
+		{"null_export", "any", ""},
+		{"concat", "string", "string"},
+		{"split", "string", "string"},
+	}
+	for _, tt := range tests {
+		metadata, ok := getTransformationMetadata(tt.name)
+		if !ok {
+			t.Fatalf("no metadata for %s", tt.name)
+		}
+		if metadata.InputType != tt.inputType || metadata.OutputType != tt.outputType {
+			t.Errorf("%s types = %q -> %q, want %q -> %q", tt.name, metadata.InputType, metadata.OutputType, tt.inputType, tt.outputType)
+		}
+	}
+}
@@ -46,6 +46,9 @@ func (s *Service) Initialize(ctx context.Context, cfg *config.Config) error {
 	cfg.SetRestartKeys([]string{
 		"services.transformation.grpc_port",
 		"services.transformation.timeout",
+		"services.transformation.schema_registry_url",
+		"services.transformation.payload_cache_size",
+		"services.transformation.window_idle_ttl",
 		// Add other configuration keys that require service restart
 	})
 
@@ -0,0 +1,574 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Window types supported by the window aggregator
+const (
+	WindowTypeTumbling = "tumbling"
+	WindowTypeSliding  = "sliding"
+)
+
+// Aggregation functions supported by the window aggregator
+const (
+	AggCount          = "count"
+	AggSum            = "sum"
+	AggMin            = "min"
+	AggMax            = "max"
+	AggApproxDistinct = "approx_distinct"
+)
+
+// WindowSpec describes how stream events are grouped into windows and aggregated
+type WindowSpec struct {
+	Type            string   `json:"type"`                       // "tumbling" or "sliding"
+	SizeMs          int64    `json:"size_ms"`                    // window length
+	SlideMs         int64    `json:"slide_ms,omitempty"`         // slide interval for sliding windows
+	AllowedLateness int64    `json:"allowed_lateness_ms"`        // how far the watermark trails the max event time
+	TimeField       string   `json:"time_field"`                 // event field holding the event time
+	KeyField        string   `json:"key_field,omitempty"`        // optional grouping field
+	ValueField      string   `json:"value_field,omitempty"`      // field aggregated by sum/min/max/approx_distinct
+	Aggregations    []string `json:"aggregations"`               // aggregations to compute
+	DistinctField   string   `json:"distinct_field,omitempty"`   // overrides ValueField for approx_distinct
+	TimestampFormat string   `json:"timestamp_format,omitempty"` // "unix_ms" (default), "unix" or "rfc3339"
+}
+
+// Validate checks the window specification and fills defaults
+func (s *WindowSpec) Validate() error {
+	if s.Type == "" {
+		s.Type = WindowTypeTumbling
+	}
+	if s.SizeMs <= 0 {
+		return fmt.Errorf("window size_ms must be positive")
+	}
+	switch s.Type {
+	case WindowTypeTumbling:
+		s.SlideMs = s.SizeMs
+	case WindowTypeSliding:
+		if s.SlideMs <= 0 || s.SlideMs > s.SizeMs {
+			return fmt.Errorf("sliding window slide_ms must be between 1 and size_ms")
+		}
+	default:
+		return fmt.Errorf("unsupported window type: %s", s.Type)
+	}
+	if s.AllowedLateness < 0 {
+		return fmt.Errorf("allowed_lateness_ms must not be negative")
+	}
+	if s.TimeField == "" {
+		return fmt.Errorf("time_field is required")
+	}
+	if len(s.Aggregations) == 0 {
+		s.Aggregations = []string{AggCount}
+	}
+	for _, agg := range s.Aggregations {
+		switch agg {
+		case AggCount:
+		case AggSum, AggMin, AggMax:
+			if s.ValueField == "" {
+				return fmt.Errorf("aggregation %s requires value_field", agg)
+			}
+		case AggApproxDistinct:
+			if s.ValueField == "" && s.DistinctField == "" {
+				return fmt.Errorf("aggregation %s requires value_field or distinct_field", agg)
+			}
+		default:
+			return fmt.Errorf("unsupported aggregation: %s", agg)
+		}
+	}
+	return nil
+}
+
+// WindowResult is the aggregated output of a closed window
+type WindowResult struct {
+	WindowStart int64                  `json:"window_start"`
+	WindowEnd   int64                  `json:"window_end"`
+	Key         string                 `json:"key,omitempty"`
+	Values      map[string]interface{} `json:"values"`
+}
+
+// windowState accumulates events for a single window and key
+type windowState struct {
+	start    int64
+	end      int64
+	key      string
+	count    int64
+	sum      float64
+	min      float64
+	max      float64
+	hasValue bool
+	distinct *hyperLogLog
+}
+
+// WindowAggregator computes tumbling or sliding window aggregations over event time.
+// Windows are emitted once the watermark (max event time minus allowed lateness)
+// passes the window end; events that arrive for already emitted windows are dropped
+// and counted as late.
+type WindowAggregator struct {
+	mu         sync.Mutex
+	spec       WindowSpec
+	open       map[string]*windowState
+	watermark  int64
+	maxEvent   int64
+	lateEvents int64
+}
+
+// NewWindowAggregator creates a new window aggregator for the given spec
+func NewWindowAggregator(spec WindowSpec) (*WindowAggregator, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &WindowAggregator{
+		spec:      spec,
+		open:      make(map[string]*windowState),
+		watermark: math.MinInt64,
+		maxEvent:  math.MinInt64,
+	}, nil
+}
+
+// Watermark returns the current watermark in milliseconds
+func (w *WindowAggregator) Watermark() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.watermark
+}
+
+// LateEvents returns the number of events dropped because they arrived after their window closed
+func (w *WindowAggregator) LateEvents() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lateEvents
+}
+
+// Add processes a batch of events and returns the windows closed by the advanced watermark.
+// The batch is validated before any state changes, so a rejected batch leaves the windows untouched.
+func (w *WindowAggregator) Add(events []map[string]interface{}) ([]WindowResult, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	times := make([]int64, len(events))
+	for i, event := range events {
+		ts, err := w.eventTime(event)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+		times[i] = ts
+	}
+
+	for i, event := range events {
+		ts := times[i]
+
+		key := ""
+		if w.spec.KeyField != "" {
+			if v, ok := event[w.spec.KeyField]; ok && v != nil {
+				key = fmt.Sprintf("%v", v)
+			}
+		}
+
+		accepted := false
+		for _, start := range w.windowStarts(ts) {
+			end := start + w.spec.SizeMs
+			if end <= w.watermark {
+				continue
+			}
+			accepted = true
+			w.accumulate(start, end, key, event)
+		}
+		if !accepted {
+			w.lateEvents++
+			continue
+		}
+
+		if ts > w.maxEvent {
+			w.maxEvent = ts
+			w.watermark = ts - w.spec.AllowedLateness
+		}
+	}
+
+	return w.emit(w.watermark), nil
+}
+
+// Flush closes and returns all open windows regardless of the watermark
+func (w *WindowAggregator) Flush() []WindowResult {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.emit(math.MaxInt64)
+}
+
+// windowStarts returns the start times of all windows containing the timestamp
+func (w *WindowAggregator) windowStarts(ts int64) []int64 {
+	slide := w.spec.SlideMs
+	last := floorDiv(ts, slide) * slide
+	var starts []int64
+	for start := last; start > ts-w.spec.SizeMs; start -= slide {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+func (w *WindowAggregator) accumulate(start, end int64, key string, event map[string]interface{}) {
+	id := strconv.FormatInt(start, 10) + "|" + key
+	state, exists := w.open[id]
+	if !exists {
+		state = &windowState{start: start, end: end, key: key}
+		w.open[id] = state
+	}
+
+	state.count++
+
+	if w.spec.ValueField != "" {
+		if num, ok := toFloat64(event[w.spec.ValueField]); ok {
+			state.sum += num
+			if !state.hasValue || num < state.min {
+				state.min = num
+			}
+			if !state.hasValue || num > state.max {
+				state.max = num
+			}
+			state.hasValue = true
+		}
+	}
+
+	distinctField := w.spec.DistinctField
+	if distinctField == "" {
+		distinctField = w.spec.ValueField
+	}
+	if distinctField != "" {
+		if v, ok := event[distinctField]; ok && v != nil {
+			if state.distinct == nil {
+				state.distinct = newHyperLogLog()
+			}
+			state.distinct.Add(fmt.Sprintf("%v", v))
+		}
+	}
+}
+
+// emit removes and returns all windows ending at or before the given time, ordered by start and key
+func (w *WindowAggregator) emit(upTo int64) []WindowResult {
+	var closed []*windowState
+	for id, state := range w.open {
+		if state.end <= upTo {
+			closed = append(closed, state)
+			delete(w.open, id)
+		}
+	}
+
+	sort.Slice(closed, func(i, j int) bool {
+		if closed[i].start != closed[j].start {
+			return closed[i].start < closed[j].start
+		}
+		return closed[i].key < closed[j].key
+	})
+
+	results := make([]WindowResult, 0, len(closed))
+	for _, state := range closed {
+		values := make(map[string]interface{}, len(w.spec.Aggregations))
+		for _, agg := range w.spec.Aggregations {
+			switch agg {
+			case AggCount:
+				values[agg] = state.count
+			case AggSum:
+				values[agg] = state.sum
+			case AggMin:
+				if state.hasValue {
+					values[agg] = state.min
+				} else {
+					values[agg] = nil
+				}
+			case AggMax:
+				if state.hasValue {
+					values[agg] = state.max
+				} else {
+					values[agg] = nil
+				}
+			case AggApproxDistinct:
+				if state.distinct != nil {
+					values[agg] = state.distinct.Estimate()
+				} else {
+					values[agg] = uint64(0)
+				}
+			}
+		}
+		results = append(results, WindowResult{
+			WindowStart: state.start,
+			WindowEnd:   state.end,
+			Key:         state.key,
+			Values:      values,
+		})
+	}
+
+	return results
+}
+
+// eventTime extracts the event time in milliseconds from an event
+func (w *WindowAggregator) eventTime(event map[string]interface{}) (int64, error) {
+	raw, exists := event[w.spec.TimeField]
+	if !exists || raw == nil {
+		return 0, fmt.Errorf("event is missing time field '%s'", w.spec.TimeField)
+	}
+
+	switch w.spec.TimestampFormat {
+	case "rfc3339":
+		str, ok := raw.(string)
+		if !ok {
+			return 0, fmt.Errorf("time field '%s' is not a string", w.spec.TimeField)
+		}
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return 0, fmt.Errorf("invalid RFC 3339 time in field '%s': %v", w.spec.TimeField, err)
+		}
+		return t.UnixMilli(), nil
+	case "unix":
+		num, ok := toFloat64(raw)
+		if !ok {
+			return 0, fmt.Errorf("time field '%s' is not numeric", w.spec.TimeField)
+		}
+		return int64(num * 1000), nil
+	case "", "unix_ms":
+		num, ok := toFloat64(raw)
+		if !ok {
+			return 0, fmt.Errorf("time field '%s' is not numeric", w.spec.TimeField)
+		}
+		return int64(num), nil
+	default:
+		return 0, fmt.Errorf("unsupported timestamp format: %s", w.spec.TimestampFormat)
+	}
+}
+
+// DefaultWindowIdleTTL is how long window state is kept without requests
+const DefaultWindowIdleTTL = 30 * time.Minute
+
+// WindowManager keeps window aggregator state across calls, keyed by window ID.
+// State that has not been used for the idle TTL is discarded, so abandoned windows
+// do not accumulate.
+type WindowManager struct {
+	mu          sync.Mutex
+	aggregators map[string]*managedWindow
+	idleTTL     time.Duration
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+type managedWindow struct {
+	aggregator *WindowAggregator
+	lastUsed   time.Time
+}
+
+// NewWindowManager creates a new window manager that discards state idle for longer than idleTTL
+func NewWindowManager(idleTTL time.Duration) *WindowManager {
+	if idleTTL <= 0 {
+		idleTTL = DefaultWindowIdleTTL
+	}
+	return &WindowManager{
+		aggregators: make(map[string]*managedWindow),
+		idleTTL:     idleTTL,
+		now:         time.Now,
+	}
+}
+
+// Len returns the number of windows with state
+func (m *WindowManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.aggregators)
+}
+
+// windowRequest is the JSON input accepted by the window_aggregate transformation
+type windowRequest struct {
+	WindowID string                   `json:"window_id"`
+	Spec     *WindowSpec              `json:"spec,omitempty"`
+	Events   []map[string]interface{} `json:"events"`
+	Flush    bool                     `json:"flush,omitempty"`
+}
+
+// windowResponse is the JSON output produced by the window_aggregate transformation
+type windowResponse struct {
+	WindowID   string         `json:"window_id"`
+	Windows    []WindowResult `json:"windows"`
+	Watermark  *int64         `json:"watermark,omitempty"`
+	LateEvents int64          `json:"late_events"`
+}
+
+// Process handles a JSON-encoded window request and returns the JSON-encoded closed windows.
+// The spec is only required on the first call for a window ID; later calls reuse the state.
+func (m *WindowManager) Process(input string) (string, error) {
+	var req windowRequest
+	if err := json.Unmarshal([]byte(input), &req); err != nil {
+		return "", fmt.Errorf("invalid window request: %v", err)
+	}
+	if req.WindowID == "" {
+		return "", fmt.Errorf("window_id is required")
+	}
+
+	agg, err := m.aggregator(req.WindowID, req.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := agg.Add(req.Events)
+	if err != nil {
+		return "", err
+	}
+	if req.Flush {
+		results = append(results, agg.Flush()...)
+		m.Remove(req.WindowID)
+	}
+
+	resp := windowResponse{
+		WindowID:   req.WindowID,
+		Windows:    results,
+		LateEvents: agg.LateEvents(),
+	}
+	if wm := agg.Watermark(); wm != math.MinInt64 {
+		resp.Watermark = &wm
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode window results: %v", err)
+	}
+	return string(out), nil
+}
+
+// Remove discards the state for a window ID
+func (m *WindowManager) Remove(windowID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.aggregators, windowID)
+}
+
+func (m *WindowManager) aggregator(windowID string, spec *WindowSpec) (*WindowAggregator, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if window, exists := m.aggregators[windowID]; exists {
+		window.lastUsed = now
+		return window.aggregator, nil
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("window %s has no state; spec is required on the first request", windowID)
+	}
+
+	agg, err := NewWindowAggregator(*spec)
+	if err != nil {
+		return nil, err
+	}
+	m.aggregators[windowID] = &managedWindow{aggregator: agg, lastUsed: now}
+	return agg, nil
+}
+
+// sweep discards idle windows, at most once per quarter of the idle TTL
+func (m *WindowManager) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.idleTTL/4 {
+		return
+	}
+	m.lastSweep = now
+
+	for id, window := range m.aggregators {
+		if now.Sub(window.lastUsed) > m.idleTTL {
+			delete(m.aggregators, id)
+		}
+	}
+}
+
+// hyperLogLog is a small HyperLogLog sketch used for approximate distinct counts
+type hyperLogLog struct {
+	registers []uint8
+}
+
+const hllPrecision = 12
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+// Add adds a value to the sketch
+func (h *hyperLogLog) Add(value string) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(value))
+	x := mix64(hasher.Sum64())
+
+	idx := x >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+// Estimate returns the approximate number of distinct values added
+func (h *hyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1.0 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// Small range correction using linear counting
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// mix64 improves the bit distribution of FNV hashes (splitmix64 finalizer)
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// toFloat64 converts numeric JSON values to float64
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
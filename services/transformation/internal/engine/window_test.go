@@ -0,0 +1,230 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func event(ts int64, key string, value float64) map[string]interface{} {
+	return map[string]interface{}{"ts": float64(ts), "key": key, "value": value}
+}
+
+// windowBounds returns the [start, end) pairs of the results
+func windowBounds(results []WindowResult) [][2]int64 {
+	bounds := make([][2]int64, 0, len(results))
+	for _, r := range results {
+		bounds = append(bounds, [2]int64{r.WindowStart, r.WindowEnd})
+	}
+	return bounds
+}
+
+func TestTumblingWindowBoundaries(t *testing.T) {
+	agg, err := NewWindowAggregator(WindowSpec{
+		Type:         WindowTypeTumbling,
+		SizeMs:       1000,
+		TimeField:    "ts",
+		ValueField:   "value",
+		Aggregations: []string{AggCount, AggSum, AggMin, AggMax},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 999 is the last instant of [0, 1000); 1000 opens the next window and advances the
+	// watermark to exactly the first window's end, which closes it
+	results, err := agg.Add([]map[string]interface{}{event(0, "", 1), event(999, "", 2), event(1000, "", 5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := windowBounds(results), [][2]int64{{0, 1000}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("closed windows = %v, want %v", got, want)
+	}
+	if got, want := results[0].Values, map[string]interface{}{AggCount: int64(2), AggSum: 3.0, AggMin: 1.0, AggMax: 2.0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("values = %v, want %v", got, want)
+	}
+
+	// An event for the closed window is late and dropped
+	if _, err := agg.Add([]map[string]interface{}{event(500, "", 100)}); err != nil {
+		t.Fatal(err)
+	}
+	if agg.LateEvents() != 1 {
+		t.Fatalf("late events = %d, want 1", agg.LateEvents())
+	}
+
+	results = agg.Flush()
+	if got, want := windowBounds(results), [][2]int64{{1000, 2000}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("flushed windows = %v, want %v", got, want)
+	}
+	if results[0].Values[AggCount] != int64(1) {
+		t.Fatalf("flushed count = %v, want 1", results[0].Values[AggCount])
+	}
+}
+
+func TestTumblingWindowNegativeTimestamps(t *testing.T) {
+	agg, err := NewWindowAggregator(WindowSpec{SizeMs: 1000, TimeField: "ts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := agg.Add([]map[string]interface{}{event(-1, "", 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := windowBounds(agg.Flush()), [][2]int64{{-1000, 0}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("windows = %v, want %v", got, want)
+	}
+}
+
+func TestSlidingWindowBoundaries(t *testing.T) {
+	agg, err := NewWindowAggregator(WindowSpec{
+		Type:      WindowTypeSliding,
+		SizeMs:    1000,
+		SlideMs:   250,
+		TimeField: "ts",
+		KeyField:  "key",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1000 falls into the four windows starting at 250, 500, 750 and 1000, but not into
+	// [0, 1000) which ends exactly at it
+	if _, err := agg.Add([]map[string]interface{}{event(1000, "a", 0)}); err != nil {
+		t.Fatal(err)
+	}
+	results := agg.Flush()
+	if got, want := windowBounds(results), [][2]int64{{250, 1250}, {500, 1500}, {750, 1750}, {1000, 2000}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("windows = %v, want %v", got, want)
+	}
+
+	// Windows are closed one slide at a time as the watermark passes their ends
+	agg, err = NewWindowAggregator(WindowSpec{Type: WindowTypeSliding, SizeMs: 1000, SlideMs: 250, TimeField: "ts", KeyField: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err = agg.Add([]map[string]interface{}{event(0, "a", 0), event(999, "b", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := windowBounds(results), [][2]int64{{-750, 250}, {-500, 500}, {-250, 750}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("closed windows = %v, want %v", got, want)
+	}
+
+	results, err = agg.Add([]map[string]interface{}{event(1250, "a", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []WindowResult{
+		{WindowStart: 0, WindowEnd: 1000, Key: "a", Values: map[string]interface{}{AggCount: int64(1)}},
+		{WindowStart: 0, WindowEnd: 1000, Key: "b", Values: map[string]interface{}{AggCount: int64(1)}},
+		{WindowStart: 250, WindowEnd: 1250, Key: "b", Values: map[string]interface{}{AggCount: int64(1)}},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Fatalf("closed windows = %+v, want %+v", results, want)
+	}
+}
+
+func TestWindowAllowedLateness(t *testing.T) {
+	agg, err := NewWindowAggregator(WindowSpec{SizeMs: 1000, AllowedLateness: 500, TimeField: "ts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The watermark trails by 500ms, so [0, 1000) stays open until an event at 1500
+	results, err := agg.Add([]map[string]interface{}{event(100, "", 0), event(1499, "", 0), event(900, "", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 || agg.LateEvents() != 0 {
+		t.Fatalf("closed = %v, late = %d, want the window still open", results, agg.LateEvents())
+	}
+
+	results, err = agg.Add([]map[string]interface{}{event(1500, "", 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Values[AggCount] != int64(2) {
+		t.Fatalf("closed = %+v, want [0, 1000) with 2 events", results)
+	}
+}
+
+func TestWindowAddRejectsBatchWithoutPartialUpdates(t *testing.T) {
+	agg, err := NewWindowAggregator(WindowSpec{SizeMs: 1000, TimeField: "ts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := []map[string]interface{}{event(100, "", 0), event(5000, "", 0), {"key": "no time"}}
+	if _, err := agg.Add(bad); err == nil {
+		t.Fatal("batch with an invalid event was accepted")
+	}
+	if agg.Watermark() != math.MinInt64 {
+		t.Fatalf("watermark moved to %d by a rejected batch", agg.Watermark())
+	}
+	if flushed := agg.Flush(); len(flushed) != 0 {
+		t.Fatalf("rejected batch left windows %+v", flushed)
+	}
+}
+
+func TestHyperLogLogErrorBounds(t *testing.T) {
+	// The standard error for 4096 registers is 1.04/sqrt(4096), about 1.6%; allow three of them
+	const maxRelativeError = 3 * 1.04 / 64
+
+	for _, n := range []int{10, 100, 1000, 10000, 100000} {
+		hll := newHyperLogLog()
+		for i := 0; i < n; i++ {
+			hll.Add(fmt.Sprintf("user-%d", i))
+			// Repeats must not change the estimate
+			hll.Add(fmt.Sprintf("user-%d", i))
+		}
+
+		estimate := float64(hll.Estimate())
+		if relErr := math.Abs(estimate-float64(n)) / float64(n); relErr > maxRelativeError {
+			t.Errorf("n=%d: estimate %.0f has relative error %.3f, want at most %.3f", n, estimate, relErr, maxRelativeError)
+		}
+	}
+
+	if got := newHyperLogLog().Estimate(); got != 0 {
+		t.Errorf("empty sketch estimate = %d, want 0", got)
+	}
+}
+
+func TestWindowManagerEvictsIdleWindows(t *testing.T) {
+	m := NewWindowManager(time.Minute)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	request := func(id string, withSpec bool) error {
+		req := map[string]interface{}{"window_id": id, "events": []map[string]interface{}{event(0, "", 0)}}
+		if withSpec {
+			req["spec"] = WindowSpec{SizeMs: 1000, TimeField: "ts"}
+		}
+		input, _ := json.Marshal(req)
+		_, err := m.Process(string(input))
+		return err
+	}
+
+	if err := request("idle", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := request("active", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keep one window in use while the other goes idle past the TTL
+	for i := 0; i < 4; i++ {
+		now = now.Add(20 * time.Second)
+		if err := request("active", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if m.Len() != 1 {
+		t.Fatalf("windows with state = %d, want 1", m.Len())
+	}
+	if err := request("idle", false); err == nil {
+		t.Fatal("idle window kept its state past the TTL")
+	}
+}
@@ -0,0 +1,281 @@
+package comparison
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// ChangeRecord is a change found by the comparator as data, so that consumers can act on
+// changes without parsing their descriptions
+type ChangeRecord struct {
+	ChangeType  unifiedmodel.ChangeType     // Added, removed or modified
+	ObjectType  unifiedmodel.ObjectType     // Kind of the changed object, such as table or column
+	ObjectName  string                      // Name of the changed object
+	ParentName  string                      // Object containing the changed object, such as the table of a column, empty for objects of the model
+	Field       string                      // Modified property, such as data_type, empty for added and removed objects
+	OldValue    string                      // Value before a modification, empty if it cannot be shown
+	NewValue    string                      // Value after a modification, empty if it cannot be shown
+	Severity    unifiedmodel.ChangeSeverity // Impact of the change
+	IsBreaking  bool                        // Applications using the object may fail after the change
+	Confidence  float64                     // Likelihood of a rename, from the threshold of the comparator to 1; 0 for other changes
+	Description string                      // The change as listed in the Changes of the result
+}
+
+// objectType returns the type of the objects named by a label of the descriptions, such as
+// materialized_view for "materialized view"
+func objectType(label string) unifiedmodel.ObjectType {
+	return unifiedmodel.ObjectType(snakeCase(label))
+}
+
+func snakeCase(s string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(s))
+}
+
+// capitalize upper-cases the first letter of a label that starts a description
+func capitalize(label string) string {
+	for i, r := range label {
+		return string(unicode.ToUpper(r)) + label[i+len(string(r)):]
+	}
+	return label
+}
+
+// changeScope records the changes of the objects of a model, or of the objects within an object,
+// such as the columns of a table
+type changeScope struct {
+	result *UnifiedCompareResult
+	parent string
+}
+
+// in returns the scope recording the changes of the objects within an object, or of the model
+func (r *UnifiedCompareResult) in(parent string) changeScope {
+	return changeScope{result: r, parent: parent}
+}
+
+// within returns the scope recording the changes of the objects within an object of the scope
+func (s changeScope) within(name string) changeScope {
+	return s.result.in(s.qualified(name))
+}
+
+// record adds a change to the result, classifying it unless its severity is already set
+func (r *UnifiedCompareResult) record(change ChangeRecord) {
+	if change.Severity == "" {
+		classify(&change)
+	}
+	r.Changes = append(r.Changes, change.Description)
+	r.Records = append(r.Records, change)
+}
+
+func (s changeScope) qualified(name string) string {
+	if s.parent == "" {
+		return name
+	}
+	return s.parent + "." + name
+}
+
+func (s changeScope) added(label, name string, object interface{}) {
+	change := ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeAdded,
+		ObjectType:  objectType(label),
+		ObjectName:  name,
+		ParentName:  s.parent,
+		Description: fmt.Sprintf("Added %s: %s", label, s.qualified(name)),
+	}
+	if breakingAddition(object) {
+		change.Severity, change.IsBreaking = unifiedmodel.ChangeSeverityMajor, true
+	}
+	s.result.record(change)
+}
+
+func (s changeScope) removed(label, name string) {
+	s.result.record(ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeRemoved,
+		ObjectType:  objectType(label),
+		ObjectName:  name,
+		ParentName:  s.parent,
+		Description: fmt.Sprintf("Removed %s: %s", label, s.qualified(name)),
+	})
+}
+
+// renamed records an object found under another name, with the confidence of the match. The
+// name is the modified field, from the previous to the current name.
+func (s changeScope) renamed(label, from, to string, confidence float64) {
+	s.result.record(ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeRenamed,
+		ObjectType:  objectType(label),
+		ObjectName:  to,
+		ParentName:  s.parent,
+		Field:       "name",
+		OldValue:    from,
+		NewValue:    to,
+		Confidence:  confidence,
+		Description: fmt.Sprintf("Renamed %s: %s -> %s (confidence %.2f)", label, s.qualified(from), to, confidence),
+	})
+}
+
+// modified records a property of an object that changed from one value to another, in a unit
+// shown after the values if any
+func (s changeScope) modified(label, name, field string, oldValue, newValue interface{}, unit string) {
+	description := fmt.Sprintf("%s %s %s changed: %v -> %v", capitalize(label), s.qualified(name), field, oldValue, newValue)
+	if unit != "" {
+		description += " " + unit
+	}
+	s.result.record(ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeModified,
+		ObjectType:  objectType(label),
+		ObjectName:  name,
+		ParentName:  s.parent,
+		Field:       snakeCase(field),
+		OldValue:    fmt.Sprint(oldValue),
+		NewValue:    fmt.Sprint(newValue),
+		Description: description,
+	})
+}
+
+// changed records a property of an object that changed, whose values are not shown
+func (s changeScope) changed(label, name, field string) {
+	s.result.record(ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeModified,
+		ObjectType:  objectType(label),
+		ObjectName:  name,
+		ParentName:  s.parent,
+		Field:       snakeCase(field),
+		Description: fmt.Sprintf("%s %s %s changed", capitalize(label), s.qualified(name), field),
+	})
+}
+
+// breakingAddition tells whether adding an object breaks applications: inserts written before
+// do not set a new column, or a new required field, that has no value to fall back on
+func breakingAddition(object interface{}) bool {
+	switch o := object.(type) {
+	case unifiedmodel.Column:
+		return !o.Nullable && o.Default == "" && o.GeneratedExpression == "" && !o.AutoIncrement
+	case unifiedmodel.Field:
+		return o.Required
+	}
+	return false
+}
+
+// Removing these objects does not break applications: they only affect performance, integrity
+// checks, operations or metadata
+var nonBreakingRemovals = map[unifiedmodel.ObjectType]unifiedmodel.ChangeSeverity{
+	"index":           unifiedmodel.ChangeSeverityMajor,
+	"constraint":      unifiedmodel.ChangeSeverityMajor,
+	"trigger":         unifiedmodel.ChangeSeverityMajor,
+	"event_trigger":   unifiedmodel.ChangeSeverityMajor,
+	"policy":          unifiedmodel.ChangeSeverityMajor,
+	"statistic":       unifiedmodel.ChangeSeverityMinor,
+	"histogram":       unifiedmodel.ChangeSeverityMinor,
+	"search_index":    unifiedmodel.ChangeSeverityMajor,
+	"vector_index":    unifiedmodel.ChangeSeverityMajor,
+	"job":             unifiedmodel.ChangeSeverityMajor,
+	"task":            unifiedmodel.ChangeSeverityMajor,
+	"schedule":        unifiedmodel.ChangeSeverityMajor,
+	"pipeline":        unifiedmodel.ChangeSeverityMajor,
+	"monitor":         unifiedmodel.ChangeSeverityMinor,
+	"monitor_metric":  unifiedmodel.ChangeSeverityMinor,
+	"threshold":       unifiedmodel.ChangeSeverityMinor,
+	"alert":           unifiedmodel.ChangeSeverityMinor,
+	"notification":    unifiedmodel.ChangeSeverityMinor,
+	"event":           unifiedmodel.ChangeSeverityMinor,
+	"comment":         unifiedmodel.ChangeSeverityMinor,
+	"annotation":      unifiedmodel.ChangeSeverityMinor,
+	"tag":             unifiedmodel.ChangeSeverityMinor,
+	"label":           unifiedmodel.ChangeSeverityMinor,
+	"snapshot":        unifiedmodel.ChangeSeverityMinor,
+	"backup":          unifiedmodel.ChangeSeverityMajor,
+	"archive":         unifiedmodel.ChangeSeverityMajor,
+	"recovery_point":  unifiedmodel.ChangeSeverityMinor,
+	"version":         unifiedmodel.ChangeSeverityMinor,
+	"migration":       unifiedmodel.ChangeSeverityMinor,
+	"branch":          unifiedmodel.ChangeSeverityMinor,
+	"time_travel":     unifiedmodel.ChangeSeverityMinor,
+	"ttl_setting":     unifiedmodel.ChangeSeverityMajor,
+	"buffer_pool":     unifiedmodel.ChangeSeverityMinor,
+	"extent":          unifiedmodel.ChangeSeverityMinor,
+	"page":            unifiedmodel.ChangeSeverityMinor,
+	"segment":         unifiedmodel.ChangeSeverityMinor,
+	"replica":         unifiedmodel.ChangeSeverityMajor,
+	"projection":      unifiedmodel.ChangeSeverityMajor,
+	"cache":           unifiedmodel.ChangeSeverityMajor,
+	"distance_metric": unifiedmodel.ChangeSeverityMinor,
+}
+
+// Modifying these properties changes the shape or identity of an object, so applications
+// written against the previous value may fail
+var breakingFields = map[string]bool{
+	"name":        true,
+	"data_type":   true,
+	"type":        true,
+	"category":    true,
+	"primary_key": true,
+	"return_type": true,
+	"arguments":   true,
+	"input_types": true,
+	"left_type":   true,
+	"right_type":  true,
+	"state_type":  true,
+	"final_type":  true,
+	"object_type": true,
+	"fields":      true,
+	"properties":  true,
+	"table":       true,
+	"dimension":   true,
+}
+
+// Modifying these properties only changes metadata
+var minorFields = map[string]bool{
+	"comment":     true,
+	"description": true,
+	"owner":       true,
+	"size":        true,
+	"cache":       true,
+	"cache_value": true,
+	"start":       true,
+	"labels":      true,
+	"message":     true,
+	"severity":    true,
+	"timestamp":   true,
+	"id":          true,
+}
+
+// classify sets the severity of a change, and whether it breaks applications. Added objects
+// are minor, removed objects are critical and breaking unless they only serve performance,
+// integrity or operations, and modifications depend on the property that changed.
+func classify(change *ChangeRecord) {
+	switch change.ChangeType {
+	case unifiedmodel.ChangeTypeAdded:
+		change.Severity = unifiedmodel.ChangeSeverityMinor
+		if change.ObjectType == unifiedmodel.ObjectTypeConstraint {
+			// Rows that were accepted may violate the new constraint
+			change.Severity, change.IsBreaking = unifiedmodel.ChangeSeverityMajor, true
+		}
+	case unifiedmodel.ChangeTypeRemoved:
+		if severity, ok := nonBreakingRemovals[change.ObjectType]; ok {
+			change.Severity = severity
+		} else {
+			change.Severity, change.IsBreaking = unifiedmodel.ChangeSeverityCritical, true
+		}
+	default:
+		switch {
+		case breakingFields[change.Field]:
+			change.Severity, change.IsBreaking = unifiedmodel.ChangeSeverityCritical, true
+		case minorFields[change.Field]:
+			change.Severity = unifiedmodel.ChangeSeverityMinor
+		case change.Field == "nullable" || change.Field == "auto_increment":
+			// Columns that stop accepting nulls, or stop generating their values, reject
+			// inserts that worked before
+			change.Severity = unifiedmodel.ChangeSeverityMinor
+			if change.NewValue == "false" {
+				change.Severity, change.IsBreaking = unifiedmodel.ChangeSeverityMajor, true
+			}
+		case change.Field == "unique":
+			change.Severity = unifiedmodel.ChangeSeverityMajor
+			change.IsBreaking = change.NewValue == "true"
+		default:
+			change.Severity = unifiedmodel.ChangeSeverityMajor
+		}
+	}
+}
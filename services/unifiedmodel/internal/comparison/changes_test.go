@@ -0,0 +1,104 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRecords(t *testing.T) {
+	comparator := NewUnifiedSchemaComparator()
+
+	prevModel := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":    {Name: "id", DataType: "integer", IsPrimaryKey: true},
+					"email": {Name: "email", DataType: "varchar", Nullable: true},
+					"age":   {Name: "age", DataType: "integer", Nullable: true},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_email": {Name: "idx_email", Columns: []string{"email"}},
+				},
+			},
+			"audit": {Name: "audit"},
+		},
+		TimeTravel: map[string]unifiedmodel.TimeTravel{
+			"users_snapshot": {Object: "users"},
+		},
+	}
+	currModel := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name:    "users",
+				Comment: "Registered users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":      {Name: "id", DataType: "bigint", IsPrimaryKey: true},
+					"email":   {Name: "email", DataType: "varchar", Nullable: false},
+					"age":     {Name: "age", DataType: "integer", Nullable: true},
+					"country": {Name: "country", DataType: "varchar"},
+					"city":    {Name: "city", DataType: "varchar", Nullable: true},
+				},
+			},
+		},
+		TimeTravel: map[string]unifiedmodel.TimeTravel{
+			"users_snapshot": {Object: "accounts"},
+		},
+	}
+
+	result, err := comparator.CompareUnifiedModels(prevModel, currModel)
+	require.NoError(t, err)
+	require.Len(t, result.Records, len(result.Changes))
+
+	records := make(map[string]ChangeRecord)
+	for i, record := range result.Records {
+		assert.Equal(t, result.Changes[i], record.Description)
+		records[record.Description] = record
+	}
+
+	assert.Equal(t, ChangeRecord{
+		ChangeType:  unifiedmodel.ChangeTypeModified,
+		ObjectType:  unifiedmodel.ObjectTypeColumn,
+		ObjectName:  "id",
+		ParentName:  "users",
+		Field:       "data_type",
+		OldValue:    "integer",
+		NewValue:    "bigint",
+		Severity:    unifiedmodel.ChangeSeverityCritical,
+		IsBreaking:  true,
+		Description: "Column users.id data type changed: integer -> bigint",
+	}, records["Column users.id data type changed: integer -> bigint"])
+
+	nullable := records["Column users.email nullable changed: true -> false"]
+	assert.Equal(t, "nullable", nullable.Field)
+	assert.True(t, nullable.IsBreaking)
+
+	comment := records["Table users comment changed:  -> Registered users"]
+	assert.Equal(t, unifiedmodel.ObjectTypeTable, comment.ObjectType)
+	assert.Equal(t, unifiedmodel.ChangeSeverityMinor, comment.Severity)
+	assert.False(t, comment.IsBreaking)
+
+	// Inserts that do not set a new NOT NULL column without default fail
+	assert.True(t, records["Added column: users.country"].IsBreaking)
+	assert.False(t, records["Added column: users.city"].IsBreaking)
+
+	removedIndex := records["Removed index: users.idx_email"]
+	assert.Equal(t, unifiedmodel.ChangeTypeRemoved, removedIndex.ChangeType)
+	assert.Equal(t, "users", removedIndex.ParentName)
+	assert.False(t, removedIndex.IsBreaking)
+
+	removedTable := records["Removed table: audit"]
+	assert.Equal(t, unifiedmodel.ChangeSeverityCritical, removedTable.Severity)
+	assert.True(t, removedTable.IsBreaking)
+
+	timeTravel := records["Time travel users_snapshot object changed: users -> accounts"]
+	assert.Equal(t, unifiedmodel.ObjectType("time_travel"), timeTravel.ObjectType)
+	assert.Equal(t, "object", timeTravel.Field)
+	assert.Equal(t, "accounts", timeTravel.NewValue)
+}
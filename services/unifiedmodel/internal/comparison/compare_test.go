@@ -460,7 +460,7 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		assert.Contains(t, result.Changes, "Collection users owner changed: admin -> system")
 		assert.Contains(t, result.Changes, "Collection users comment changed: User collection -> Updated user collection")
 		assert.Contains(t, result.Changes, "Collection users shard key changed")
-		assert.Contains(t, result.Changes, "Collection users fields changed")
+		assert.Contains(t, result.Changes, "Added field: users.email")
 	})
 
 	t.Run("detect_detailed_node_changes", func(t *testing.T) {
@@ -489,7 +489,7 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		assert.NoError(t, err)
 		assert.True(t, result.HasChanges)
 		assert.Contains(t, result.Changes, "Node person label changed: Person -> User")
-		assert.Contains(t, result.Changes, "Node person properties changed")
+		assert.Contains(t, result.Changes, "Added property: person.email")
 	})
 
 	t.Run("detect_detailed_memory_table_changes", func(t *testing.T) {
@@ -665,7 +665,7 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		assert.Contains(t, result.Changes, "Relationship KNOWS type changed: KNOWS -> FRIENDS_WITH")
 		assert.Contains(t, result.Changes, "Relationship KNOWS from label changed: Person -> User")
 		assert.Contains(t, result.Changes, "Relationship KNOWS to label changed: Person -> User")
-		assert.Contains(t, result.Changes, "Relationship KNOWS properties changed")
+		assert.Contains(t, result.Changes, "Added property: KNOWS.strength")
 	})
 
 	t.Run("detect_detailed_external_table_changes", func(t *testing.T) {
@@ -781,8 +781,8 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		result, err := comparator.CompareUnifiedModels(prevModel, currModel)
 		assert.NoError(t, err)
 		assert.True(t, result.HasChanges)
-		assert.Contains(t, result.Changes, "Graph social_graph node labels changed")
-		assert.Contains(t, result.Changes, "Graph social_graph relationship types changed")
+		assert.Contains(t, result.Changes, "Added node: social_graph.User")
+		assert.Contains(t, result.Changes, "Added relationship: social_graph.FRIENDS_WITH")
 		assert.Contains(t, result.Changes, "Added index: social_graph.user_email_idx")
 	})
 
@@ -1310,7 +1310,7 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		result, err := comparator.CompareUnifiedModels(prevModel, currModel)
 		assert.NoError(t, err)
 		assert.True(t, result.HasChanges)
-		assert.Contains(t, result.Changes, "Module auth_module comment changed")
+		assert.Contains(t, result.Changes, "Module auth_module comment changed: Authentication module -> Enhanced authentication module")
 		assert.Contains(t, result.Changes, "Module auth_module language changed: python -> javascript")
 		assert.Contains(t, result.Changes, "Module auth_module code changed")
 	})
@@ -2284,7 +2284,7 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		assert.Contains(t, result.Changes, "Text search component english_config parser changed: default -> enhanced")
 		assert.Contains(t, result.Changes, "Text search component english_config dictionaries changed")
 		assert.Contains(t, result.Changes, "Text search component english_config chain changed")
-		assert.Contains(t, result.Changes, "Text search component english_config comment changed")
+		assert.Contains(t, result.Changes, "Text search component english_config comment changed: English text search configuration -> Enhanced English text search configuration")
 	})
 
 	t.Run("detect_detailed_comment_changes", func(t *testing.T) {
@@ -3017,4 +3017,69 @@ func TestUnifiedSchemaComparator(t *testing.T) {
 		assert.Contains(t, result.Changes, "Window view user_activity_window definition changed")
 		assert.Contains(t, result.Changes, "Window view user_activity_window window specification changed: PARTITION BY user_id ORDER BY timestamp ROWS BETWEEN 10 PRECEDING AND CURRENT ROW -> PARTITION BY user_id, region ORDER BY timestamp ROWS BETWEEN 50 PRECEDING AND CURRENT ROW")
 	})
+	t.Run("detect_changes_keeping_the_counts", func(t *testing.T) {
+		min := int64(1)
+		prevModel := &unifiedmodel.UnifiedModel{
+			Tables: map[string]unifiedmodel.Table{
+				"users": {
+					Name:    "users",
+					Indexes: map[string]unifiedmodel.Index{"idx_email": {Name: "idx_email", Columns: []string{"email"}}},
+				},
+			},
+			Collections: map[string]unifiedmodel.Collection{
+				"events": {
+					Name:   "events",
+					Fields: map[string]unifiedmodel.Field{"at": {Name: "at", Type: "string"}},
+				},
+			},
+			Sequences: map[string]unifiedmodel.Sequence{
+				"user_id_seq": {Name: "user_id_seq", Min: &min},
+			},
+			Publications: map[string]unifiedmodel.Publication{
+				"all_tables": {Name: "all_tables", Objects: []string{"users"}},
+			},
+			Extents: map[string]unifiedmodel.Extent{
+				"ext_1": {Name: "ext_1", Size: 1024},
+			},
+		}
+
+		currModel := &unifiedmodel.UnifiedModel{
+			Tables: map[string]unifiedmodel.Table{
+				"users": {
+					Name:    "users",
+					Indexes: map[string]unifiedmodel.Index{"idx_email": {Name: "idx_email", Columns: []string{"lower_email"}}},
+				},
+			},
+			Collections: map[string]unifiedmodel.Collection{
+				"events": {
+					Name: "events",
+					Fields: map[string]unifiedmodel.Field{
+						"at":   {Name: "at", Type: "date"},
+						"type": {Name: "type", Type: "string", Required: true},
+					},
+				},
+			},
+			Sequences: map[string]unifiedmodel.Sequence{
+				"user_id_seq": {Name: "user_id_seq"},
+			},
+			Publications: map[string]unifiedmodel.Publication{
+				"all_tables": {Name: "all_tables", Objects: []string{"accounts"}},
+			},
+			Extents: map[string]unifiedmodel.Extent{
+				"ext_1": {Name: "ext_1", Size: 2048},
+			},
+		}
+
+		result, err := comparator.CompareUnifiedModels(prevModel, currModel)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Index users.idx_email columns changed",
+			"Field events.at type changed: string -> date",
+			"Added field: events.type",
+			"Sequence user_id_seq min changed: 1 -> none",
+			"Extent ext_1 size changed: 1024 -> 2048 bytes",
+			"Publication all_tables objects changed",
+		}, result.Changes)
+		assert.True(t, result.Records[2].IsBreaking)
+	})
 }
@@ -0,0 +1,281 @@
+package comparison
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// MergeStrategy selects the side whose value a merge keeps for a conflict
+type MergeStrategy string
+
+const (
+	MergeStrategyOurs   MergeStrategy = "ours"
+	MergeStrategyTheirs MergeStrategy = "theirs"
+)
+
+// Kinds of merge conflicts
+const (
+	ConflictModifyModify = "modify/modify" // Both sides changed a field to different values
+	ConflictAddAdd       = "add/add"       // Both sides added an object, with different values of a field
+	ConflictDeleteModify = "delete/modify" // Ours removed an object that theirs changed
+	ConflictModifyDelete = "modify/delete" // Ours changed an object that theirs removed
+)
+
+// MergeOptions configures a three-way merge
+type MergeOptions struct {
+	Strategy MergeStrategy // Side kept for conflicts, ours by default
+
+	// VersionID, if set, adds a version with this ID to the merged model, whose parents are the
+	// latest versions of both sides
+	VersionID      string
+	VersionMessage string
+}
+
+// MergeConflict is an object or a field that both sides of a merge changed differently
+type MergeConflict struct {
+	Kind       string                  // modify/modify, add/add, delete/modify or modify/delete
+	ObjectType unifiedmodel.ObjectType // Kind of the object, such as table or column
+	ObjectName string
+	ParentName string // Object containing the object, such as the table of a column, empty for objects of the model
+	Field      string // Conflicting property, empty for delete/modify and modify/delete
+
+	// Values of the field on each side, empty if they cannot be shown; add/add conflicts have
+	// no base value
+	BaseValue  string
+	OurValue   string
+	TheirValue string
+
+	Resolution  MergeStrategy // Side whose value the merged model has
+	Description string
+}
+
+// UnifiedMergeResult is the outcome of a three-way merge
+type UnifiedMergeResult struct {
+	Merged       *unifiedmodel.UnifiedModel
+	HasConflicts bool
+	Conflicts    []MergeConflict
+}
+
+// UnifiedSchemaMerger merges the changes two sides made to a common base model
+type UnifiedSchemaMerger struct {
+	options MergeOptions
+}
+
+// NewUnifiedSchemaMerger creates a merger with the given options
+func NewUnifiedSchemaMerger(options MergeOptions) (*UnifiedSchemaMerger, error) {
+	switch options.Strategy {
+	case "":
+		options.Strategy = MergeStrategyOurs
+	case MergeStrategyOurs, MergeStrategyTheirs:
+	default:
+		return nil, fmt.Errorf("unknown merge strategy %q, expected ours or theirs", options.Strategy)
+	}
+	return &UnifiedSchemaMerger{options: options}, nil
+}
+
+// MergeUnifiedModels merges theirs into ours, both derived from base. Like the comparison, all
+// object maps are merged by name and each object field by field: a change made by one side only
+// is kept, and a field or an object both sides changed differently is a conflict, resolved with
+// the side of the strategy. The merged model always has a resolution, so callers decide whether
+// to accept it when there are conflicts.
+func (m *UnifiedSchemaMerger) MergeUnifiedModels(base, ours, theirs *unifiedmodel.UnifiedModel) (*UnifiedMergeResult, error) {
+	empty := NewUnifiedSchemaComparator().createEmptyUnifiedModel()
+	if base == nil {
+		base = empty
+	}
+	if ours == nil {
+		ours = empty
+	}
+	if theirs == nil {
+		theirs = empty
+	}
+
+	result := &UnifiedMergeResult{Conflicts: make([]MergeConflict, 0)}
+	scope := mergeScope{merger: m, result: result}
+	merged := scope.mergeObject("model", "", reflect.ValueOf(base).Elem(), reflect.ValueOf(ours).Elem(), reflect.ValueOf(theirs).Elem(), false)
+	model := merged.Addr().Interface().(*unifiedmodel.UnifiedModel)
+
+	if m.options.VersionID != "" {
+		if model.Versions == nil {
+			model.Versions = make(map[string]unifiedmodel.VersionNode)
+		}
+		model.Versions[m.options.VersionID] = unifiedmodel.VersionNode{
+			ID:      m.options.VersionID,
+			Parents: mergeParents(ours, theirs, m.options.VersionID),
+			Message: m.options.VersionMessage,
+		}
+	}
+
+	result.Merged = model
+	result.HasConflicts = len(result.Conflicts) > 0
+	return result, nil
+}
+
+// mergeScope merges the objects of a model, or the objects within an object
+type mergeScope struct {
+	merger *UnifiedSchemaMerger
+	result *UnifiedMergeResult
+	parent string
+}
+
+// within returns the scope merging the objects within an object of the scope
+func (s mergeScope) within(name string) mergeScope {
+	return mergeScope{merger: s.merger, result: s.result, parent: s.qualified(name)}
+}
+
+func (s mergeScope) qualified(name string) string {
+	if s.parent == "" {
+		return name
+	}
+	return s.parent + "." + name
+}
+
+// mergeObjects merges three maps of objects by name, in the order of the names
+func (s mergeScope) mergeObjects(label string, base, ours, theirs reflect.Value) reflect.Value {
+	names := map[string]bool{}
+	for _, objects := range []reflect.Value{base, ours, theirs} {
+		for _, name := range sortedNames(objects) {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	merged := reflect.MakeMapWithSize(ours.Type(), len(sorted))
+	for _, name := range sorted {
+		baseObject := base.MapIndex(mapKey(base, name))
+		ourObject := ours.MapIndex(mapKey(ours, name))
+		theirObject := theirs.MapIndex(mapKey(theirs, name))
+
+		var object reflect.Value
+		switch {
+		case !baseObject.IsValid() && ourObject.IsValid() && theirObject.IsValid():
+			object = s.mergeObject(label, name, reflect.Zero(ourObject.Type()), ourObject, theirObject, true)
+		case !baseObject.IsValid():
+			object = ourObject
+			if !object.IsValid() {
+				object = theirObject
+			}
+		case ourObject.IsValid() && theirObject.IsValid():
+			object = s.mergeObject(label, name, baseObject, ourObject, theirObject, false)
+		case !ourObject.IsValid() && theirObject.IsValid() && !equal(baseObject, theirObject):
+			if s.conflict(ConflictDeleteModify, label, name, nil, reflect.Value{}, reflect.Value{}, reflect.Value{}) == MergeStrategyTheirs {
+				object = theirObject
+			}
+		case ourObject.IsValid() && !theirObject.IsValid() && !equal(baseObject, ourObject):
+			if s.conflict(ConflictModifyDelete, label, name, nil, reflect.Value{}, reflect.Value{}, reflect.Value{}) == MergeStrategyOurs {
+				object = ourObject
+			}
+		}
+		// Otherwise one side removed the object and the other left it as it was, or both removed it
+
+		if object.IsValid() {
+			merged.SetMapIndex(mapKey(merged, name), object)
+		}
+	}
+
+	if merged.Len() == 0 && ours.IsNil() {
+		return ours
+	}
+	return merged
+}
+
+// mergeObject merges the fields of three versions of an object. Fields that are not compared
+// keep the value of ours. An object both sides added has no base, so its fields conflict
+// wherever the sides differ.
+func (s mergeScope) mergeObject(label, name string, base, ours, theirs reflect.Value, added bool) reflect.Value {
+	merged := reflect.New(ours.Type()).Elem()
+	merged.Set(ours)
+
+	kind := ConflictModifyModify
+	if added {
+		kind = ConflictAddAdd
+	}
+	for _, f := range fieldsOf(ours.Type()) {
+		baseValue, ourValue, theirValue := base.Field(f.index), ours.Field(f.index), theirs.Field(f.index)
+		switch {
+		case f.nested:
+			merged.Field(f.index).Set(s.within(name).mergeObjects(f.label, baseValue, ourValue, theirValue))
+		case equal(ourValue, theirValue):
+		case !added && equal(baseValue, theirValue):
+		case !added && equal(baseValue, ourValue):
+			merged.Field(f.index).Set(theirValue)
+		default:
+			if s.conflict(kind, label, name, &f, baseValue, ourValue, theirValue) == MergeStrategyTheirs {
+				merged.Field(f.index).Set(theirValue)
+			}
+		}
+	}
+	return merged
+}
+
+// conflict records a conflict on an object, or on a field of it, and returns the side kept
+func (s mergeScope) conflict(kind, label, name string, f *field, base, ours, theirs reflect.Value) MergeStrategy {
+	resolution := s.merger.options.Strategy
+	subject := label
+	if name != "" {
+		subject += " " + s.qualified(name)
+	}
+
+	conflict := MergeConflict{
+		Kind:       kind,
+		ObjectType: objectType(label),
+		ObjectName: name,
+		ParentName: s.parent,
+		Resolution: resolution,
+	}
+	switch {
+	case kind == ConflictDeleteModify:
+		conflict.Description = fmt.Sprintf("Conflict (%s) on %s: removed by ours, changed by theirs; kept %s", kind, subject, resolution)
+	case kind == ConflictModifyDelete:
+		conflict.Description = fmt.Sprintf("Conflict (%s) on %s: changed by ours, removed by theirs; kept %s", kind, subject, resolution)
+	case f.opaque || !isScalar(ours.Type()):
+		conflict.Field = snakeCase(f.label)
+		conflict.Description = fmt.Sprintf("Conflict (%s) on %s %s; kept %s", kind, subject, f.label, resolution)
+	default:
+		conflict.Field = snakeCase(f.label)
+		conflict.OurValue = fmt.Sprint(display(ours))
+		conflict.TheirValue = fmt.Sprint(display(theirs))
+		values := fmt.Sprintf("ours %s, theirs %s", conflict.OurValue, conflict.TheirValue)
+		if kind != ConflictAddAdd {
+			conflict.BaseValue = fmt.Sprint(display(base))
+			values += ", base " + conflict.BaseValue
+		}
+		conflict.Description = fmt.Sprintf("Conflict (%s) on %s %s: %s; kept %s", kind, subject, f.label, values, resolution)
+	}
+
+	s.result.Conflicts = append(s.result.Conflicts, conflict)
+	return resolution
+}
+
+// mergeParents returns the parents of the version recording a merge: the latest versions of
+// both sides, those no other version of their model derives from
+func mergeParents(ours, theirs *unifiedmodel.UnifiedModel, versionID string) []string {
+	parents := map[string]bool{}
+	for _, model := range []*unifiedmodel.UnifiedModel{ours, theirs} {
+		derived := map[string]bool{}
+		for _, version := range model.Versions {
+			for _, parent := range version.Parents {
+				derived[parent] = true
+			}
+		}
+		for id := range model.Versions {
+			if !derived[id] && id != versionID {
+				parents[id] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(parents))
+	for id := range parents {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,166 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeModels() (base, ours, theirs *unifiedmodel.UnifiedModel) {
+	base = &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":    {Name: "id", DataType: "integer"},
+					"email": {Name: "email", DataType: "varchar", Nullable: true},
+					"age":   {Name: "age", DataType: "integer", Nullable: true},
+				},
+			},
+			"legacy": {Name: "legacy"},
+			"audit":  {Name: "audit"},
+		},
+		Versions: map[string]unifiedmodel.VersionNode{
+			"v1": {ID: "v1"},
+		},
+	}
+
+	// Dev: emails are required and users get a name; legacy is dropped, audit gets a comment
+	ours = &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":    {Name: "id", DataType: "integer"},
+					"email": {Name: "email", DataType: "text"},
+					"age":   {Name: "age", DataType: "integer", Nullable: true},
+					"name":  {Name: "name", DataType: "text", Nullable: true},
+				},
+			},
+			"audit": {Name: "audit", Comment: "Audit trail"},
+		},
+		Versions: map[string]unifiedmodel.VersionNode{
+			"v1":  {ID: "v1"},
+			"dev": {ID: "dev", Parents: []string{"v1"}},
+		},
+	}
+
+	// Prod: ages become bigint, emails citext, users get a name too; audit is dropped
+	theirs = &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":    {Name: "id", DataType: "integer"},
+					"email": {Name: "email", DataType: "citext", Nullable: true},
+					"age":   {Name: "age", DataType: "bigint", Nullable: true},
+					"name":  {Name: "name", DataType: "varchar", Nullable: true},
+				},
+			},
+			"legacy": {Name: "legacy"},
+		},
+		Versions: map[string]unifiedmodel.VersionNode{
+			"v1":   {ID: "v1"},
+			"prod": {ID: "prod", Parents: []string{"v1"}},
+		},
+	}
+	return base, ours, theirs
+}
+
+func TestMergeUnifiedModels(t *testing.T) {
+	base, ours, theirs := mergeModels()
+	merger, err := NewUnifiedSchemaMerger(MergeOptions{VersionID: "merge", VersionMessage: "Promote dev"})
+	require.NoError(t, err)
+
+	result, err := merger.MergeUnifiedModels(base, ours, theirs)
+	require.NoError(t, err)
+	merged := result.Merged
+
+	// Changes made by one side only are kept
+	users := merged.Tables["users"]
+	assert.Equal(t, "bigint", users.Columns["age"].DataType)
+	assert.False(t, users.Columns["email"].Nullable)
+	assert.NotContains(t, merged.Tables, "legacy")
+
+	// Conflicts are resolved with ours
+	assert.Equal(t, "text", users.Columns["email"].DataType)
+	assert.Equal(t, "text", users.Columns["name"].DataType)
+	assert.Contains(t, merged.Tables, "audit")
+
+	require.True(t, result.HasConflicts)
+	assert.Equal(t, []string{
+		"Conflict (modify/delete) on table audit: changed by ours, removed by theirs; kept ours",
+		"Conflict (modify/modify) on column users.email data type: ours text, theirs citext, base varchar; kept ours",
+		"Conflict (add/add) on column users.name data type: ours text, theirs varchar; kept ours",
+	}, conflictDescriptions(result))
+
+	email := result.Conflicts[1]
+	assert.Equal(t, ConflictModifyModify, email.Kind)
+	assert.Equal(t, unifiedmodel.ObjectTypeColumn, email.ObjectType)
+	assert.Equal(t, "email", email.ObjectName)
+	assert.Equal(t, "users", email.ParentName)
+	assert.Equal(t, "data_type", email.Field)
+	assert.Equal(t, "varchar", email.BaseValue)
+	assert.Equal(t, MergeStrategyOurs, email.Resolution)
+
+	// The merge is recorded as a version derived from the latest versions of both sides
+	assert.Equal(t, unifiedmodel.VersionNode{ID: "merge", Parents: []string{"dev", "prod"}, Message: "Promote dev"}, merged.Versions["merge"])
+	assert.Len(t, merged.Versions, 4)
+
+	// The inputs are left as they were
+	assert.Equal(t, "citext", theirs.Tables["users"].Columns["email"].DataType)
+	assert.NotContains(t, ours.Versions, "merge")
+}
+
+func TestMergeUnifiedModelsTheirs(t *testing.T) {
+	base, ours, theirs := mergeModels()
+	merger, err := NewUnifiedSchemaMerger(MergeOptions{Strategy: MergeStrategyTheirs})
+	require.NoError(t, err)
+
+	result, err := merger.MergeUnifiedModels(base, ours, theirs)
+	require.NoError(t, err)
+
+	users := result.Merged.Tables["users"]
+	assert.Equal(t, "citext", users.Columns["email"].DataType)
+	assert.Equal(t, "varchar", users.Columns["name"].DataType)
+	assert.NotContains(t, result.Merged.Tables, "audit")
+	assert.NotContains(t, result.Merged.Versions, "merge")
+	for _, conflict := range result.Conflicts {
+		assert.Equal(t, MergeStrategyTheirs, conflict.Resolution)
+	}
+}
+
+func TestMergeUnifiedModelsWithoutConflicts(t *testing.T) {
+	base, ours, _ := mergeModels()
+	merger, err := NewUnifiedSchemaMerger(MergeOptions{})
+	require.NoError(t, err)
+
+	// Nothing changed on their side, so the merge is ours
+	result, err := merger.MergeUnifiedModels(base, ours, base)
+	require.NoError(t, err)
+	assert.False(t, result.HasConflicts)
+	assert.Empty(t, result.Conflicts)
+
+	compare, err := NewUnifiedSchemaComparator().CompareUnifiedModels(ours, result.Merged)
+	require.NoError(t, err)
+	assert.False(t, compare.HasChanges, compare.Changes)
+}
+
+func TestNewUnifiedSchemaMergerStrategy(t *testing.T) {
+	_, err := NewUnifiedSchemaMerger(MergeOptions{Strategy: "union"})
+	assert.Error(t, err)
+}
+
+func conflictDescriptions(result *UnifiedMergeResult) []string {
+	descriptions := make([]string, len(result.Conflicts))
+	for i, conflict := range result.Conflicts {
+		descriptions[i] = conflict.Description
+	}
+	return descriptions
+}
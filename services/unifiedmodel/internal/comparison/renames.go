@@ -0,0 +1,216 @@
+package comparison
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// DefaultRenameThreshold is the confidence from which a removed and an added object are
+// reported as a rename
+const DefaultRenameThreshold = 0.8
+
+// Weights of the structure and of the name in the confidence of a rename
+const (
+	renameStructureWeight = 0.75
+	renameNameWeight      = 0.25
+)
+
+var (
+	tableType  = reflect.TypeOf(unifiedmodel.Table{})
+	columnType = reflect.TypeOf(unifiedmodel.Column{})
+)
+
+// rename is an object of the previous model found under another name in the current model
+type rename struct {
+	from       string
+	confidence float64
+}
+
+// renames pairs the tables or columns removed from a map with the ones added to it that have
+// the same structure under another name, by new name. Each object is paired once, the most
+// likely pairs first.
+func (c *UnifiedSchemaComparator) renames(prev, curr reflect.Value) map[string]rename {
+	if c.RenameThreshold <= 0 {
+		return nil
+	}
+	var similarity func(prev, curr reflect.Value) float64
+	switch prev.Type().Elem() {
+	case tableType:
+		similarity = tableSimilarity
+	case columnType:
+		similarity = objectSimilarity
+	default:
+		return nil
+	}
+
+	var removed, added []string
+	for _, name := range sortedNames(prev) {
+		if !curr.MapIndex(mapKey(curr, name)).IsValid() {
+			removed = append(removed, name)
+		}
+	}
+	for _, name := range sortedNames(curr) {
+		if !prev.MapIndex(mapKey(prev, name)).IsValid() {
+			added = append(added, name)
+		}
+	}
+
+	type candidate struct {
+		from, to   string
+		confidence float64
+	}
+	var candidates []candidate
+	for _, from := range removed {
+		for _, to := range added {
+			confidence := renameStructureWeight*similarity(prev.MapIndex(mapKey(prev, from)), curr.MapIndex(mapKey(curr, to))) +
+				renameNameWeight*nameSimilarity(from, to)
+			confidence = math.Round(confidence*100) / 100
+			if confidence >= c.RenameThreshold {
+				candidates = append(candidates, candidate{from: from, to: to, confidence: confidence})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].confidence > candidates[j].confidence
+	})
+
+	renames := make(map[string]rename)
+	paired := make(map[string]bool)
+	for _, candidate := range candidates {
+		if _, ok := renames[candidate.to]; ok || paired[candidate.from] {
+			continue
+		}
+		renames[candidate.to] = rename{from: candidate.from, confidence: candidate.confidence}
+		paired[candidate.from] = true
+	}
+	return renames
+}
+
+// objectSimilarity returns the share of the compared fields two objects have in common, leaving
+// out their names and the fields neither sets
+func objectSimilarity(prev, curr reflect.Value) float64 {
+	set, same := 0, 0
+	for _, f := range fieldsOf(prev.Type()) {
+		if f.nested || f.label == "name" {
+			continue
+		}
+		prevValue, currValue := prev.Field(f.index), curr.Field(f.index)
+		if isUnset(prevValue) && isUnset(currValue) {
+			continue
+		}
+		set++
+		if equal(prevValue, currValue) {
+			same++
+		}
+	}
+	if set == 0 {
+		return 0
+	}
+	return float64(same) / float64(set)
+}
+
+// tableSimilarity returns the share of the columns of two tables that have the same structure
+// in both, whatever their names, pairing columns of the same name first
+func tableSimilarity(prev, curr reflect.Value) float64 {
+	prevColumns := prev.Interface().(unifiedmodel.Table).Columns
+	currColumns := curr.Interface().(unifiedmodel.Table).Columns
+	same := func(a, b unifiedmodel.Column) bool {
+		return objectSimilarity(reflect.ValueOf(a), reflect.ValueOf(b)) == 1
+	}
+
+	paired := make(map[string]bool)
+	var unpaired []string
+	for _, name := range sortedKeys(prevColumns) {
+		if column, ok := currColumns[name]; ok && same(prevColumns[name], column) {
+			paired[name] = true
+		} else {
+			unpaired = append(unpaired, name)
+		}
+	}
+	matches := len(paired)
+	currNames := sortedKeys(currColumns)
+	for _, name := range unpaired {
+		for _, other := range currNames {
+			if !paired[other] && same(prevColumns[name], currColumns[other]) {
+				paired[other] = true
+				matches++
+				break
+			}
+		}
+	}
+
+	all := len(prevColumns) + len(currColumns) - matches
+	if all == 0 {
+		return 0
+	}
+	return float64(matches) / float64(all)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isUnset tells whether a value is the zero value of its type, or an empty map or slice
+func isUnset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// nameSimilarity returns how close two names are, from 0 for names without a letter in common
+// to 1 for names differing only in case
+func nameSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	longest := len([]rune(a))
+	if n := len([]rune(b)); n > longest {
+		longest = n
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance returns the Levenshtein distance of two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			next := min(row[j]+1, row[j-1]+1, diagonal+cost)
+			diagonal, row[j] = row[j], next
+		}
+	}
+	return row[len(rb)]
+}
+
+// renamedObject returns a copy of the previous version of a renamed object with its new name,
+// so that only the other changes of the object are compared
+func renamedObject(prev, curr reflect.Value) reflect.Value {
+	renamed := reflect.New(prev.Type()).Elem()
+	renamed.Set(prev)
+	if name := renamed.FieldByName("Name"); name.IsValid() && name.CanSet() {
+		name.Set(curr.FieldByName("Name"))
+	}
+	return renamed
+}
@@ -0,0 +1,121 @@
+package comparison
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renameModels() (prev, curr *unifiedmodel.UnifiedModel) {
+	prev = &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"clients": {
+				Name: "clients",
+				Columns: map[string]unifiedmodel.Column{
+					"id":        {Name: "id", DataType: "integer"},
+					"full_name": {Name: "full_name", DataType: "text", Nullable: true},
+					"nickname":  {Name: "nickname", DataType: "varchar", Nullable: true},
+					"created":   {Name: "created", DataType: "timestamp"},
+				},
+			},
+		},
+	}
+	curr = &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers": {
+				Name: "customers",
+				Columns: map[string]unifiedmodel.Column{
+					"id":       {Name: "id", DataType: "integer"},
+					"name":     {Name: "name", DataType: "text", Nullable: true},
+					"nickname": {Name: "nickname", DataType: "varchar", Nullable: true},
+					"created":  {Name: "created", DataType: "timestamp"},
+				},
+			},
+		},
+	}
+	return prev, curr
+}
+
+func TestCompareRenames(t *testing.T) {
+	prev, curr := renameModels()
+
+	result, err := NewUnifiedSchemaComparator().CompareUnifiedModels(prev, curr)
+	require.NoError(t, err)
+
+	// The columns have the same structure, so the table is renamed, and full_name within it
+	assert.Equal(t, []string{
+		"Renamed table: clients -> customers (confidence 0.81)",
+		"Renamed column: customers.full_name -> name (confidence 0.86)",
+	}, result.Changes)
+
+	table := result.Records[0]
+	assert.Equal(t, unifiedmodel.ChangeTypeRenamed, table.ChangeType)
+	assert.Equal(t, unifiedmodel.ObjectTypeTable, table.ObjectType)
+	assert.Equal(t, "customers", table.ObjectName)
+	assert.Equal(t, "name", table.Field)
+	assert.Equal(t, "clients", table.OldValue)
+	assert.Equal(t, "customers", table.NewValue)
+	assert.Equal(t, 0.81, table.Confidence)
+	assert.True(t, table.IsBreaking)
+
+	column := result.Records[1]
+	assert.Equal(t, "customers", column.ParentName)
+	assert.Equal(t, "full_name", column.OldValue)
+}
+
+func TestCompareRenamesNeedSameStructure(t *testing.T) {
+	prev := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"mail":    {Name: "mail", DataType: "varchar", Nullable: true},
+					"created": {Name: "created", DataType: "timestamp"},
+					"flag":    {Name: "flag", DataType: "boolean"},
+				},
+			},
+		},
+	}
+	curr := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"email":      {Name: "email", DataType: "varchar", Nullable: true},
+					"created_at": {Name: "created_at", DataType: "timestamptz"},
+					"is_active":  {Name: "is_active", DataType: "boolean"},
+				},
+			},
+		},
+	}
+
+	result, err := NewUnifiedSchemaComparator().CompareUnifiedModels(prev, curr)
+	require.NoError(t, err)
+
+	// created changed type, and flag has the structure of is_active but not a close name
+	assert.Equal(t, []string{
+		"Removed column: users.created",
+		"Removed column: users.flag",
+		"Added column: users.created_at",
+		"Renamed column: users.mail -> email (confidence 0.95)",
+		"Added column: users.is_active",
+	}, result.Changes)
+}
+
+func TestCompareRenamesDisabled(t *testing.T) {
+	prev, curr := renameModels()
+	comparator := NewUnifiedSchemaComparator()
+	comparator.RenameThreshold = 0
+
+	result, err := comparator.CompareUnifiedModels(prev, curr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Removed table: clients", "Added table: customers"}, result.Changes)
+}
+
+func TestNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, nameSimilarity("Users", "users"))
+	assert.Equal(t, 0.0, nameSimilarity("abc", "xyz"))
+	assert.InDelta(t, 0.8, nameSimilarity("users", "user"), 0.001)
+}
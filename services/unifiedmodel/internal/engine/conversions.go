@@ -409,6 +409,31 @@ func (s *Server) convertUnifiedModelToProto(model *unifiedmodel.UnifiedModel) *p
 		protoModel.Types[name] = s.convertTypeToProto(typ)
 	}
 
+	for name, grant := range model.Grants {
+		protoModel.Grants[name] = s.convertGrantToProto(grant)
+	}
+
+	if len(model.Comments) > 0 {
+		protoModel.Comments = make(map[string]*pb.Comment, len(model.Comments))
+		for name, comment := range model.Comments {
+			protoModel.Comments[name] = &pb.Comment{On: comment.On, Comment: comment.Comment}
+		}
+	}
+
+	if len(model.Versions) > 0 {
+		protoModel.Versions = make(map[string]*pb.VersionNode, len(model.Versions))
+		for name, version := range model.Versions {
+			protoModel.Versions[name] = &pb.VersionNode{Id: version.ID, Parents: version.Parents, Message: version.Message}
+		}
+	}
+
+	if len(model.Branches) > 0 {
+		protoModel.Branches = make(map[string]*pb.Branch, len(model.Branches))
+		for name, branch := range model.Branches {
+			protoModel.Branches[name] = &pb.Branch{Name: branch.Name, From: branch.From}
+		}
+	}
+
 	// TODO: Add conversions for remaining types as needed
 	// The framework is in place for all types, but conversions can be added incrementally
 	// based on usage requirements
@@ -578,6 +603,31 @@ func (s *Server) convertProtoToUnifiedModel(protoModel *pb.UnifiedModel) *unifie
 		model.Types[name] = s.convertProtoToType(typ)
 	}
 
+	for name, grant := range protoModel.Grants {
+		model.Grants[name] = s.convertProtoToGrant(grant)
+	}
+
+	if len(protoModel.Comments) > 0 {
+		model.Comments = make(map[string]unifiedmodel.Comment, len(protoModel.Comments))
+		for name, comment := range protoModel.Comments {
+			model.Comments[name] = unifiedmodel.Comment{On: comment.On, Comment: comment.Comment}
+		}
+	}
+
+	if len(protoModel.Versions) > 0 {
+		model.Versions = make(map[string]unifiedmodel.VersionNode, len(protoModel.Versions))
+		for name, version := range protoModel.Versions {
+			model.Versions[name] = unifiedmodel.VersionNode{ID: version.Id, Parents: version.Parents, Message: version.Message}
+		}
+	}
+
+	if len(protoModel.Branches) > 0 {
+		model.Branches = make(map[string]unifiedmodel.Branch, len(protoModel.Branches))
+		for name, branch := range protoModel.Branches {
+			model.Branches[name] = unifiedmodel.Branch{Name: branch.Name, From: branch.From}
+		}
+	}
+
 	// TODO: Add conversions for remaining types as needed
 	// The framework is in place for all types, but conversions can be added incrementally
 	// based on usage requirements
@@ -985,3 +1035,23 @@ func (s *Server) convertProtoToType(protoType *pb.Type) unifiedmodel.Type {
 		Category: protoType.Category,
 	}
 }
+
+func (s *Server) convertGrantToProto(grant unifiedmodel.Grant) *pb.Grant {
+	return &pb.Grant{
+		Principal: grant.Principal,
+		Privilege: grant.Privilege,
+		Scope:     grant.Scope,
+		Object:    grant.Object,
+		Columns:   grant.Columns,
+	}
+}
+
+func (s *Server) convertProtoToGrant(protoGrant *pb.Grant) unifiedmodel.Grant {
+	return unifiedmodel.Grant{
+		Principal: protoGrant.Principal,
+		Privilege: protoGrant.Privilege,
+		Scope:     protoGrant.Scope,
+		Object:    protoGrant.Object,
+		Columns:   protoGrant.Columns,
+	}
+}
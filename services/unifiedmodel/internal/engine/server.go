@@ -13,6 +13,7 @@ import (
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/detection"
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/generators"
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/matching"
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/migration"
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/translator"
 	"github.com/redbco/redb-open/services/unifiedmodel/internal/translator/core"
 )
@@ -70,6 +71,12 @@ func (s *Server) Translate(ctx context.Context, req *pb.TranslationRequest) (*pb
 		RequestID:   fmt.Sprintf("translate-%d", time.Now().UnixNano()),
 		RequestedAt: time.Now(),
 	}
+	if prefs := req.Preferences; prefs != nil {
+		translationReq.Preferences.OwnerHandling = core.AttributeHandling(prefs.OwnerHandling)
+		translationReq.Preferences.GrantHandling = core.AttributeHandling(prefs.GrantHandling)
+		translationReq.Preferences.CommentHandling = core.AttributeHandling(prefs.CommentHandling)
+		translationReq.Preferences.OwnerMappings = prefs.OwnerMappings
+	}
 
 	// Perform translation
 	result, err := unifiedTranslator.Translate(ctx, translationReq)
@@ -296,9 +303,192 @@ func (s *Server) CompareUnifiedModels(ctx context.Context, req *pb.CompareUnifie
 	}
 
 	return &pb.CompareResponse{
-		HasChanges: result.HasChanges,
-		Changes:    result.Changes,
-		Warnings:   result.Warnings,
+		HasChanges:    result.HasChanges,
+		Changes:       result.Changes,
+		Warnings:      result.Warnings,
+		ChangeRecords: changeRecordsToProto(result.Records),
+	}, nil
+}
+
+// PlanMigration compares two UnifiedModel objects and converts the changes into the ordered
+// statements migrating a database of the requested type from the previous to the current model
+func (s *Server) PlanMigration(ctx context.Context, req *pb.PlanMigrationRequest) (*pb.PlanMigrationResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	if req.DatabaseType == "" {
+		return nil, fmt.Errorf("database type is required")
+	}
+
+	var previousModel, currentModel *unifiedmodel.UnifiedModel
+	if req.PreviousUnifiedModel != nil {
+		previousModel = s.convertProtoToUnifiedModel(req.PreviousUnifiedModel)
+	}
+	if req.CurrentUnifiedModel != nil {
+		currentModel = s.convertProtoToUnifiedModel(req.CurrentUnifiedModel)
+	}
+
+	result, err := comparison.NewUnifiedSchemaComparator().CompareUnifiedModels(previousModel, currentModel)
+	if err != nil {
+		return nil, fmt.Errorf("unified model comparison failed: %w", err)
+	}
+
+	planner := migration.NewPlanner()
+	planner.ApplyRenames = req.ApplyRenames
+	plan, err := planner.Plan(req.DatabaseType, previousModel, currentModel, result)
+	if err != nil {
+		return nil, fmt.Errorf("migration planning failed: %w", err)
+	}
+
+	steps := make([]*pb.MigrationStep, len(plan.Steps))
+	for i, step := range plan.Steps {
+		steps[i] = &pb.MigrationStep{
+			Phase:       step.Phase.String(),
+			ChangeType:  string(step.ChangeType),
+			ObjectType:  string(step.ObjectType),
+			ObjectName:  step.ObjectName,
+			ParentName:  step.ParentName,
+			Statement:   step.Statement,
+			Destructive: step.Destructive,
+			Description: step.Description,
+		}
+	}
+
+	return &pb.PlanMigrationResponse{
+		HasChanges:    result.HasChanges,
+		Steps:         steps,
+		Script:        plan.Script(),
+		Warnings:      append(result.Warnings, plan.Warnings...),
+		Destructive:   plan.Destructive(),
+		ChangeRecords: changeRecordsToProto(result.Records),
+	}, nil
+}
+
+// MergeUnifiedModels merges the changes two unified models made to a common base, such as a
+// schema promoted from a development workspace to production
+func (s *Server) MergeUnifiedModels(ctx context.Context, req *pb.MergeUnifiedModelsRequest) (*pb.MergeUnifiedModelsResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	merger, err := comparison.NewUnifiedSchemaMerger(comparison.MergeOptions{
+		Strategy:       comparison.MergeStrategy(req.Strategy),
+		VersionID:      req.VersionId,
+		VersionMessage: req.VersionMessage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var baseModel, ourModel, theirModel *unifiedmodel.UnifiedModel
+	if req.BaseUnifiedModel != nil {
+		baseModel = s.convertProtoToUnifiedModel(req.BaseUnifiedModel)
+	}
+	if req.OurUnifiedModel != nil {
+		ourModel = s.convertProtoToUnifiedModel(req.OurUnifiedModel)
+	}
+	if req.TheirUnifiedModel != nil {
+		theirModel = s.convertProtoToUnifiedModel(req.TheirUnifiedModel)
+	}
+
+	result, err := merger.MergeUnifiedModels(baseModel, ourModel, theirModel)
+	if err != nil {
+		return nil, fmt.Errorf("unified model merge failed: %w", err)
+	}
+	changes, err := comparison.NewUnifiedSchemaComparator().CompareUnifiedModels(ourModel, result.Merged)
+	if err != nil {
+		return nil, fmt.Errorf("unified model comparison failed: %w", err)
+	}
+
+	conflicts := make([]*pb.MergeConflict, len(result.Conflicts))
+	for i, conflict := range result.Conflicts {
+		conflicts[i] = &pb.MergeConflict{
+			Kind:        conflict.Kind,
+			ObjectType:  string(conflict.ObjectType),
+			ObjectName:  conflict.ObjectName,
+			ParentName:  conflict.ParentName,
+			Field:       conflict.Field,
+			BaseValue:   conflict.BaseValue,
+			OurValue:    conflict.OurValue,
+			TheirValue:  conflict.TheirValue,
+			Resolution:  string(conflict.Resolution),
+			Description: conflict.Description,
+		}
+	}
+
+	return &pb.MergeUnifiedModelsResponse{
+		MergedUnifiedModel: s.convertUnifiedModelToProto(result.Merged),
+		HasConflicts:       result.HasConflicts,
+		Conflicts:          conflicts,
+		ChangeRecords:      changeRecordsToProto(changes.Records),
+	}, nil
+}
+
+// changeRecordsToProto converts the change records of a comparison
+func changeRecordsToProto(changes []comparison.ChangeRecord) []*pb.ChangeRecord {
+	records := make([]*pb.ChangeRecord, len(changes))
+	for i, record := range changes {
+		records[i] = &pb.ChangeRecord{
+			ChangeType:  string(record.ChangeType),
+			ObjectType:  string(record.ObjectType),
+			ObjectName:  record.ObjectName,
+			ParentName:  record.ParentName,
+			Field:       record.Field,
+			OldValue:    record.OldValue,
+			NewValue:    record.NewValue,
+			Severity:    string(record.Severity),
+			Breaking:    record.IsBreaking,
+			Description: record.Description,
+			Confidence:  record.Confidence,
+		}
+	}
+	return records
+}
+
+// ValidateUnifiedModel checks a UnifiedModel for internal consistency and applies the requested lint rules
+func (s *Server) ValidateUnifiedModel(ctx context.Context, req *pb.ValidateUnifiedModelRequest) (*pb.ValidateUnifiedModelResponse, error) {
+	s.engine.TrackOperation()
+	defer s.engine.UntrackOperation()
+
+	unifiedModel := s.convertProtoToUnifiedModel(req.UnifiedModel)
+	if unifiedModel == nil {
+		return nil, fmt.Errorf("unified model is required")
+	}
+
+	var options unifiedmodel.ValidationOptions
+	if opts := req.Options; opts != nil {
+		options.DisableLint = opts.DisableLint
+		options.LintRules = opts.LintRules
+		options.NamingConvention = opts.NamingConvention
+		if len(opts.Severities) > 0 {
+			options.Severities = make(map[string]unifiedmodel.ValidationErrorType, len(opts.Severities))
+			for rule, severity := range opts.Severities {
+				options.Severities[rule] = unifiedmodel.ValidationErrorType(severity)
+			}
+		}
+	}
+
+	report, err := unifiedmodel.Validate(unifiedModel, options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation options: %w", err)
+	}
+
+	issues := make([]*pb.ValidationIssue, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		issues = append(issues, &pb.ValidationIssue{
+			Severity:   string(issue.Type),
+			Rule:       issue.Rule,
+			Field:      issue.Field,
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+		})
+	}
+
+	return &pb.ValidateUnifiedModelResponse{
+		Valid:         report.Valid,
+		Issues:        issues,
+		CriticalCount: int32(report.Count(unifiedmodel.ValidationErrorCritical)),
+		WarningCount:  int32(report.Count(unifiedmodel.ValidationErrorWarning)),
+		InfoCount:     int32(report.Count(unifiedmodel.ValidationErrorInfo)),
 	}, nil
 }
 
@@ -758,7 +948,7 @@ func (s *Server) convertMatchOptions(protoOptions *pb.MatchOptions) *matching.Un
 		return &defaultOptions
 	}
 
-	return &matching.UnifiedMatchOptions{
+	options := &matching.UnifiedMatchOptions{
 		NameSimilarityThreshold:  protoOptions.NameSimilarityThreshold,
 		PoorMatchThreshold:       protoOptions.PoorMatchThreshold,
 		NameWeight:               protoOptions.NameWeight,
@@ -768,6 +958,23 @@ func (s *Server) convertMatchOptions(protoOptions *pb.MatchOptions) *matching.Un
 		TableStructureWeight:     protoOptions.TableStructureWeight,
 		EnableCrossTableMatching: protoOptions.EnableCrossTableMatching,
 	}
+	for _, feedback := range protoOptions.Feedback {
+		options.Feedback = append(options.Feedback, matching.MatchFeedback{
+			SourceColumn: feedback.SourceColumn,
+			TargetColumn: feedback.TargetColumn,
+			Adjustment:   feedback.Adjustment,
+			Accepted:     int(feedback.Accepted),
+			Rejected:     int(feedback.Rejected),
+		})
+	}
+	for _, pin := range protoOptions.TablePins {
+		options.TablePins = append(options.TablePins, matching.TablePin{
+			SourceTable: pin.SourceTable,
+			TargetTable: pin.TargetTable,
+			Never:       pin.Never,
+		})
+	}
+	return options
 }
 
 // convertTableMatchesToProto converts internal table matches to protobuf format
@@ -787,6 +994,10 @@ func (s *Server) convertTableMatchesToProto(matches []matching.UnifiedTableMatch
 			TotalSourceColumns:           int32(match.TotalSourceColumns),
 			TotalTargetColumns:           int32(match.TotalTargetColumns),
 			ColumnMatches:                s.convertColumnMatchesToProto(match.ColumnMatches),
+			ScoreFactors:                 convertScoreFactorsToProto(match.ScoreFactors),
+			Reasons:                      match.Reasons,
+			KeyMatches:                   convertKeyMatchesToProto(match.KeyMatches),
+			Pinned:                       match.Pinned,
 		}
 		protoMatches = append(protoMatches, protoMatch)
 	}
@@ -811,9 +1022,51 @@ func (s *Server) convertColumnMatchesToProto(matches []matching.UnifiedColumnMat
 			PrivilegedDataMatch:      match.PrivilegedDataMatch,
 			DataCategoryMatch:        match.DataCategoryMatch,
 			PrivilegedConfidenceDiff: match.PrivilegedConfidenceDiff,
+			ScoreFactors:             convertScoreFactorsToProto(match.ScoreFactors),
+			Reasons:                  match.Reasons,
 		}
 		protoMatches = append(protoMatches, protoMatch)
 	}
 
 	return protoMatches
 }
+
+// convertKeyMatchesToProto converts the composite key matches of a table match to protobuf format
+func convertKeyMatchesToProto(matches []matching.UnifiedKeyMatch) []*pb.KeyMatch {
+	protoMatches := make([]*pb.KeyMatch, 0, len(matches))
+	for _, match := range matches {
+		protoMatches = append(protoMatches, &pb.KeyMatch{
+			SourceTable:             match.SourceTable,
+			TargetTable:             match.TargetTable,
+			KeyType:                 match.KeyType,
+			SourceKey:               match.SourceKey,
+			TargetKey:               match.TargetKey,
+			TargetKeyType:           match.TargetKeyType,
+			SourceColumns:           match.SourceColumns,
+			TargetColumns:           match.TargetColumns,
+			Cardinality:             match.Cardinality,
+			Score:                   match.Score,
+			Reasons:                 match.Reasons,
+			ReferencedSourceTable:   match.ReferencedSourceTable,
+			ReferencedTargetTable:   match.ReferencedTargetTable,
+			ReferencedSourceColumns: match.ReferencedSourceColumns,
+			ReferencedTargetColumns: match.ReferencedTargetColumns,
+		})
+	}
+	return protoMatches
+}
+
+// convertScoreFactorsToProto converts the factors of the score of a match to protobuf format
+func convertScoreFactorsToProto(factors []matching.ScoreFactor) []*pb.MatchScoreFactor {
+	protoFactors := make([]*pb.MatchScoreFactor, 0, len(factors))
+	for _, factor := range factors {
+		protoFactors = append(protoFactors, &pb.MatchScoreFactor{
+			Factor:       factor.Factor,
+			Score:        factor.Score,
+			Weight:       factor.Weight,
+			Contribution: factor.Contribution,
+			Reason:       factor.Reason,
+		})
+	}
+	return protoFactors
+}
@@ -36,6 +36,10 @@ func (s *Server) convertProtoToPreferences(proto *pb.TranslationPreferences) cor
 		AutoApproveSimple:      proto.AutoApproveSimple,
 		CustomMappings:         proto.CustomMappings,
 		ExcludeObjects:         proto.ExcludeObjects,
+		OwnerHandling:          core.AttributeHandling(proto.OwnerHandling),
+		GrantHandling:          core.AttributeHandling(proto.GrantHandling),
+		CommentHandling:        core.AttributeHandling(proto.CommentHandling),
+		OwnerMappings:          proto.OwnerMappings,
 	}
 }
 
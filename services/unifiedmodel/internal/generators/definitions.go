@@ -0,0 +1,58 @@
+package generators
+
+import (
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// DefinitionGenerator is implemented by the generators of relational databases, whose column and
+// constraint definitions can be used in ALTER TABLE statements as well as in CREATE TABLE
+type DefinitionGenerator interface {
+	GenerateColumnDefinition(col unifiedmodel.Column) (string, error)
+	GenerateConstraintDefinition(constraint unifiedmodel.Constraint) (string, error)
+	MapDataType(dataType string) string
+}
+
+// GenerateColumnDefinition implements DefinitionGenerator interface
+func (pg *PostgresGenerator) GenerateColumnDefinition(col unifiedmodel.Column) (string, error) {
+	return pg.generateColumnDefinition(col)
+}
+
+// GenerateConstraintDefinition implements DefinitionGenerator interface
+func (pg *PostgresGenerator) GenerateConstraintDefinition(constraint unifiedmodel.Constraint) (string, error) {
+	return pg.generateConstraintDefinition(constraint)
+}
+
+// MapDataType implements DefinitionGenerator interface
+func (pg *PostgresGenerator) MapDataType(dataType string) string {
+	return pg.mapDataType(dataType)
+}
+
+// GenerateColumnDefinition implements DefinitionGenerator interface
+func (g *MySQLGenerator) GenerateColumnDefinition(col unifiedmodel.Column) (string, error) {
+	return g.generateColumnDefinition(col)
+}
+
+// GenerateConstraintDefinition implements DefinitionGenerator interface
+func (g *MySQLGenerator) GenerateConstraintDefinition(constraint unifiedmodel.Constraint) (string, error) {
+	return g.generateConstraintDefinition(constraint)
+}
+
+// MapDataType implements DefinitionGenerator interface
+func (g *MySQLGenerator) MapDataType(dataType string) string {
+	return g.mapDataType(dataType)
+}
+
+// GenerateColumnDefinition implements DefinitionGenerator interface
+func (g *MariaDBGenerator) GenerateColumnDefinition(col unifiedmodel.Column) (string, error) {
+	return g.generateColumnDefinition(col)
+}
+
+// GenerateConstraintDefinition implements DefinitionGenerator interface
+func (g *MariaDBGenerator) GenerateConstraintDefinition(constraint unifiedmodel.Constraint) (string, error) {
+	return g.generateConstraintDefinition(constraint)
+}
+
+// MapDataType implements DefinitionGenerator interface
+func (g *MariaDBGenerator) MapDataType(dataType string) string {
+	return g.mapDataType(dataType)
+}
@@ -0,0 +1,261 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Factors of the score of a match
+const (
+	FactorNameSimilarity          = "name_similarity"
+	FactorTypeCompatibility       = "type_compatibility"
+	FactorClassificationAgreement = "classification_agreement"
+	FactorStructure               = "structure"
+	FactorFeedback                = "feedback"
+)
+
+// ScoreFactor is a factor of the score of a match and why it scored as it did. The contributions
+// of the factors of a match add up to its score.
+type ScoreFactor struct {
+	Factor       string  `json:"factor"`
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Reason       string  `json:"reason"`
+}
+
+// explainColumnMatch returns the factors of the score of a column match: the similarity of the
+// names, the compatibility of the data types and the agreement of the classifications
+func (m *UnifiedModelMatcher) explainColumnMatch(
+	sourceColumnName string, sourceColumn unifiedmodel.Column,
+	targetColumnName string, targetColumn unifiedmodel.Column,
+	sourceEnrichment, targetEnrichment *unifiedmodel.ColumnEnrichment,
+	options *UnifiedMatchOptions,
+) []ScoreFactor {
+	nameScore := m.calculateStringSimilarity(sourceColumnName, targetColumnName)
+	factors := []ScoreFactor{
+		{
+			Factor: FactorNameSimilarity,
+			Score:  nameScore,
+			Weight: options.NameWeight,
+			Reason: m.nameSimilarityReason("column", sourceColumnName, targetColumnName, nameScore),
+		},
+	}
+
+	typeFactor := ScoreFactor{
+		Factor: FactorTypeCompatibility,
+		Weight: options.TypeWeight,
+		Reason: fmt.Sprintf("data types %s and %s are not compatible", sourceColumn.DataType, targetColumn.DataType),
+	}
+	switch {
+	case sourceColumn.DataType == targetColumn.DataType:
+		typeFactor.Score = 1.0
+		typeFactor.Reason = fmt.Sprintf("both columns are %s", sourceColumn.DataType)
+	case m.areTypesCompatible(sourceColumn.DataType, targetColumn.DataType):
+		typeFactor.Score = 1.0
+		typeFactor.Reason = fmt.Sprintf("data types %s and %s are compatible", sourceColumn.DataType, targetColumn.DataType)
+	}
+	factors = append(factors, typeFactor)
+
+	classificationFactor := ScoreFactor{
+		Factor: FactorClassificationAgreement,
+		Weight: options.PrivilegedDataWeight,
+		Reason: "the columns are not both classified",
+	}
+	if sourceEnrichment != nil && targetEnrichment != nil {
+		classificationFactor.Score = m.calculatePrivilegedDataSimilarity(*sourceEnrichment, *targetEnrichment)
+		classificationFactor.Reason = columnClassificationReason(sourceEnrichment, targetEnrichment)
+	}
+	factors = append(factors, classificationFactor)
+
+	factors = withContributions(factors)
+	if factor := options.feedbackFactor(sourceColumnName, targetColumnName); factor != nil {
+		factors = append(factors, *factor)
+	}
+	return factors
+}
+
+// explainTableMatch returns the factors of the score of a table match: the similarity of the
+// names, of the structures and of the classifications
+func (m *UnifiedModelMatcher) explainTableMatch(
+	sourceTable unifiedmodel.Table, sourceEnrichment *unifiedmodel.TableEnrichment,
+	targetTable unifiedmodel.Table, targetEnrichment *unifiedmodel.TableEnrichment,
+	options *UnifiedMatchOptions,
+) []ScoreFactor {
+	nameScore := m.calculateStringSimilarity(sourceTable.Name, targetTable.Name)
+	factors := []ScoreFactor{
+		{
+			Factor: FactorNameSimilarity,
+			Score:  nameScore,
+			Weight: options.NameWeight,
+			Reason: m.nameSimilarityReason("table", sourceTable.Name, targetTable.Name, nameScore),
+		},
+		{
+			Factor: FactorStructure,
+			Score:  m.calculateStructureSimilarity(sourceTable, targetTable),
+			Weight: options.TableStructureWeight,
+			Reason: fmt.Sprintf("%d and %d columns, %d of the data types in common",
+				len(sourceTable.Columns), len(targetTable.Columns), commonDataTypes(sourceTable, targetTable)),
+		},
+	}
+
+	classificationFactor := ScoreFactor{
+		Factor: FactorClassificationAgreement,
+		Weight: options.ClassificationWeight,
+		Reason: "the tables are not both classified",
+	}
+	if sourceEnrichment != nil && targetEnrichment != nil {
+		classificationFactor.Score = m.calculateClassificationSimilarity(sourceEnrichment, targetEnrichment)
+		classificationFactor.Reason = tableClassificationReason(sourceEnrichment, targetEnrichment)
+	}
+	factors = append(factors, classificationFactor)
+
+	return withContributions(factors)
+}
+
+// withContributions sets the contributions of the factors to the score of the match, weighted as
+// by the similarity calculations
+func withContributions(factors []ScoreFactor) []ScoreFactor {
+	totalWeight := 0.0
+	for _, factor := range factors {
+		totalWeight += factor.Weight
+	}
+	if totalWeight == 0 {
+		totalWeight = 1.0
+	}
+	for i := range factors {
+		factors[i].Contribution = factors[i].Score * factors[i].Weight / totalWeight
+	}
+	return factors
+}
+
+// matchReasons returns why a match was proposed: the reasons of its weighted factors and of the
+// feedback adjusting it, from the most contributing one, and whether its score makes it a poor
+// match
+func matchReasons(factors []ScoreFactor, score float64, options *UnifiedMatchOptions) []string {
+	sorted := make([]ScoreFactor, 0, len(factors))
+	for _, factor := range factors {
+		if factor.Weight > 0 || factor.Factor == FactorFeedback {
+			sorted = append(sorted, factor)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Contribution > sorted[j].Contribution
+	})
+
+	reasons := make([]string, 0, len(sorted)+1)
+	for _, factor := range sorted {
+		reasons = append(reasons, fmt.Sprintf("%s (%s %.2f, %+.2f)", factor.Reason, strings.ReplaceAll(factor.Factor, "_", " "), factor.Score, factor.Contribution))
+	}
+	if score < options.PoorMatchThreshold {
+		reasons = append(reasons, fmt.Sprintf("score %.2f is below the poor match threshold %.2f", score, options.PoorMatchThreshold))
+	}
+	return reasons
+}
+
+// nameSimilarityReason tells why two names are as similar as calculateStringSimilarity finds them
+func (m *UnifiedModelMatcher) nameSimilarityReason(kind, sourceName, targetName string, score float64) string {
+	sourceLower := strings.ToLower(sourceName)
+	targetLower := strings.ToLower(targetName)
+
+	switch {
+	case sourceLower == targetLower:
+		return fmt.Sprintf("%s names %s and %s are the same", kind, sourceName, targetName)
+	case strings.Contains(sourceLower, targetLower):
+		return fmt.Sprintf("%s name %s contains %s", kind, sourceName, targetName)
+	case strings.Contains(targetLower, sourceLower):
+		return fmt.Sprintf("%s name %s contains %s", kind, targetName, sourceName)
+	case score > 0:
+		for _, word := range []string{"user", "email", "id"} {
+			if strings.Contains(sourceLower, word) && strings.Contains(targetLower, word) {
+				return fmt.Sprintf("%s names %s and %s both contain %q", kind, sourceName, targetName, word)
+			}
+		}
+	}
+	return fmt.Sprintf("%s names %s and %s are not similar", kind, sourceName, targetName)
+}
+
+// columnClassificationReason tells how the data categories, risk levels and privileged data
+// flags of two columns agree
+func columnClassificationReason(source, target *unifiedmodel.ColumnEnrichment) string {
+	var parts []string
+	if source.DataCategory == target.DataCategory {
+		parts = append(parts, fmt.Sprintf("both classified as %s", displayValue(string(source.DataCategory))))
+	} else {
+		parts = append(parts, fmt.Sprintf("classified as %s and %s", displayValue(string(source.DataCategory)), displayValue(string(target.DataCategory))))
+	}
+	if source.RiskLevel == target.RiskLevel {
+		parts = append(parts, fmt.Sprintf("same risk level %s", displayValue(string(source.RiskLevel))))
+	} else {
+		parts = append(parts, fmt.Sprintf("risk levels %s and %s", displayValue(string(source.RiskLevel)), displayValue(string(target.RiskLevel))))
+	}
+	switch {
+	case source.IsPrivilegedData && target.IsPrivilegedData:
+		parts = append(parts, "both privileged data")
+	case source.IsPrivilegedData:
+		parts = append(parts, "only the source column is privileged data")
+	case target.IsPrivilegedData:
+		parts = append(parts, "only the target column is privileged data")
+	default:
+		parts = append(parts, "neither privileged data")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// tableClassificationReason tells how the categories, classification confidences and access
+// patterns of two tables agree
+func tableClassificationReason(source, target *unifiedmodel.TableEnrichment) string {
+	var parts []string
+	if source.PrimaryCategory == target.PrimaryCategory {
+		parts = append(parts, fmt.Sprintf("both classified as %s", displayValue(string(source.PrimaryCategory))))
+	} else {
+		parts = append(parts, fmt.Sprintf("classified as %s and %s", displayValue(string(source.PrimaryCategory)), displayValue(string(target.PrimaryCategory))))
+	}
+	parts = append(parts, fmt.Sprintf("confidences %.2f apart", math.Abs(source.ClassificationConfidence-target.ClassificationConfidence)))
+	if source.AccessPattern == target.AccessPattern {
+		parts = append(parts, fmt.Sprintf("same access pattern %s", displayValue(string(source.AccessPattern))))
+	} else {
+		parts = append(parts, fmt.Sprintf("access patterns %s and %s", displayValue(string(source.AccessPattern)), displayValue(string(target.AccessPattern))))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// commonDataTypes counts the columns of two tables paired by data type, as compared by
+// calculateStructureSimilarity
+func commonDataTypes(sourceTable, targetTable unifiedmodel.Table) int {
+	targetTypes := make(map[string]int)
+	for _, col := range targetTable.Columns {
+		targetTypes[col.DataType]++
+	}
+	common := 0
+	for _, col := range sourceTable.Columns {
+		if targetTypes[col.DataType] > 0 {
+			targetTypes[col.DataType]--
+			common++
+		}
+	}
+	return common
+}
+
+func displayValue(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// columnEnrichment returns the enrichment of a column, or nil when it has none
+func (m *UnifiedModelMatcher) columnEnrichment(enrichment *unifiedmodel.UnifiedModelEnrichment, tableName, columnName string) *unifiedmodel.ColumnEnrichment {
+	if enrichment == nil {
+		return nil
+	}
+	columnEnrichment, exists := enrichment.ColumnEnrichments[fmt.Sprintf("%s.%s", tableName, columnName)]
+	if !exists {
+		return nil
+	}
+	return &columnEnrichment
+}
@@ -0,0 +1,82 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// MatchFeedback is an adjustment of the scores of column matches learned from users correcting
+// the rules generated for a match: removing a generated rule or re-pointing it rejects the match,
+// and the columns it is re-pointed to are accepted. It applies to the columns of any table whose
+// names are those of the corrected match, ignoring case and punctuation.
+type MatchFeedback struct {
+	SourceColumn string  `json:"sourceColumn"`
+	TargetColumn string  `json:"targetColumn"`
+	Adjustment   float64 `json:"adjustment"`
+	Accepted     int     `json:"accepted"`
+	Rejected     int     `json:"rejected"`
+}
+
+// feedbackFor returns the feedback on the matches of two columns, summed over the corrected
+// matches of the same names, or nil when there is none
+func (o *UnifiedMatchOptions) feedbackFor(sourceColumnName, targetColumnName string) *MatchFeedback {
+	if o == nil || len(o.Feedback) == 0 {
+		return nil
+	}
+	source := normalizeColumnName(sourceColumnName)
+	target := normalizeColumnName(targetColumnName)
+
+	var total *MatchFeedback
+	for _, feedback := range o.Feedback {
+		if normalizeColumnName(feedback.SourceColumn) != source || normalizeColumnName(feedback.TargetColumn) != target {
+			continue
+		}
+		if total == nil {
+			total = &MatchFeedback{SourceColumn: sourceColumnName, TargetColumn: targetColumnName}
+		}
+		total.Adjustment += feedback.Adjustment
+		total.Accepted += feedback.Accepted
+		total.Rejected += feedback.Rejected
+	}
+	return total
+}
+
+// applyFeedback adds the feedback on the matches of two columns to the score of their match,
+// keeping it between 0 and 1
+func (o *UnifiedMatchOptions) applyFeedback(score float64, sourceColumnName, targetColumnName string) float64 {
+	feedback := o.feedbackFor(sourceColumnName, targetColumnName)
+	if feedback == nil {
+		return score
+	}
+	return math.Max(0, math.Min(1, score+feedback.Adjustment))
+}
+
+// feedbackFactor returns the feedback on the matches of two columns as a factor of the score of
+// their match, or nil when there is none. It has no weight; its contribution is the adjustment.
+func (o *UnifiedMatchOptions) feedbackFactor(sourceColumnName, targetColumnName string) *ScoreFactor {
+	feedback := o.feedbackFor(sourceColumnName, targetColumnName)
+	if feedback == nil || feedback.Adjustment == 0 {
+		return nil
+	}
+	return &ScoreFactor{
+		Factor:       FactorFeedback,
+		Score:        feedback.Adjustment,
+		Contribution: feedback.Adjustment,
+		Reason: fmt.Sprintf("users of the workspace accepted %s -> %s %d times and rejected it %d times",
+			sourceColumnName, targetColumnName, feedback.Accepted, feedback.Rejected),
+	}
+}
+
+// normalizeColumnName lower-cases a column name and drops everything but letters and digits, so
+// that e.g. EmailAddress, email_address and "email-address" are the same
+func normalizeColumnName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
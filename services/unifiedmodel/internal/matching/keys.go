@@ -0,0 +1,337 @@
+package matching
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Cardinalities of the rows of key matches: the rows of a table and of its copy are identified
+// one-to-one by their primary and unique keys, and many rows of a table reference one row of the
+// referenced table with a foreign key
+const (
+	KeyCardinalityOneToOne  = "one-to-one"
+	KeyCardinalityManyToOne = "many-to-one"
+)
+
+// UnifiedKeyMatch is the match of a composite key of a source table, a primary key, a unique key
+// or a multi-column foreign key, with a key of its target table. Its columns are matched in
+// order: SourceColumns[i] is mapped to TargetColumns[i].
+type UnifiedKeyMatch struct {
+	SourceTable   string   `json:"sourceTable"`
+	TargetTable   string   `json:"targetTable"`
+	KeyType       string   `json:"keyType"`
+	SourceKey     string   `json:"sourceKey"`
+	TargetKey     string   `json:"targetKey"`
+	TargetKeyType string   `json:"targetKeyType"`
+	SourceColumns []string `json:"sourceColumns"`
+	TargetColumns []string `json:"targetColumns"`
+	Cardinality   string   `json:"cardinality"`
+	Score         float64  `json:"score"`
+	Reasons       []string `json:"reasons,omitempty"`
+
+	// Foreign keys only: the tables and columns they reference
+	ReferencedSourceTable   string   `json:"referencedSourceTable,omitempty"`
+	ReferencedTargetTable   string   `json:"referencedTargetTable,omitempty"`
+	ReferencedSourceColumns []string `json:"referencedSourceColumns,omitempty"`
+	ReferencedTargetColumns []string `json:"referencedTargetColumns,omitempty"`
+}
+
+// tableKey is a key of a table spanning several columns
+type tableKey struct {
+	name              string
+	keyType           unifiedmodel.ConstraintType
+	columns           []string
+	referencedTable   string
+	referencedColumns []string
+}
+
+// identifies reports whether the key identifies the rows of its table
+func (k tableKey) identifies() bool {
+	return k.keyType == unifiedmodel.ConstraintTypePrimaryKey || k.keyType == unifiedmodel.ConstraintTypeUnique
+}
+
+// compositeKeys returns the keys of a table spanning several columns: its primary and unique key
+// constraints, its unique indexes, its foreign keys and, without a primary key constraint, the
+// columns flagged as primary key. Keys are sorted by type and name, primary keys first.
+func compositeKeys(table unifiedmodel.Table) []tableKey {
+	var keys []tableKey
+	seen := make(map[string]bool)
+	add := func(key tableKey) {
+		if len(key.columns) < 2 {
+			return
+		}
+		signature := string(key.keyType) + ":" + strings.Join(key.columns, ",") + ":" + key.referencedTable
+		if key.identifies() {
+			// A unique index on the columns of a constraint is the same key
+			signature = "identity:" + strings.Join(key.columns, ",")
+		}
+		if seen[signature] {
+			return
+		}
+		seen[signature] = true
+		keys = append(keys, key)
+	}
+
+	constraintNames := make([]string, 0, len(table.Constraints))
+	for name := range table.Constraints {
+		constraintNames = append(constraintNames, name)
+	}
+	sort.Strings(constraintNames)
+
+	hasPrimaryKey := false
+	for _, name := range constraintNames {
+		constraint := table.Constraints[name]
+		if constraint.Type == unifiedmodel.ConstraintTypePrimaryKey {
+			hasPrimaryKey = true
+			add(tableKey{name: name, keyType: constraint.Type, columns: constraint.Columns})
+		}
+	}
+	if !hasPrimaryKey {
+		var columns []string
+		for name, column := range table.Columns {
+			if column.IsPrimaryKey {
+				columns = append(columns, name)
+			}
+		}
+		sort.Slice(columns, func(i, j int) bool {
+			return ordinalPosition(table.Columns[columns[i]], columns[i]) < ordinalPosition(table.Columns[columns[j]], columns[j])
+		})
+		add(tableKey{name: table.Name + "_pkey", keyType: unifiedmodel.ConstraintTypePrimaryKey, columns: columns})
+	}
+	for _, name := range constraintNames {
+		if constraint := table.Constraints[name]; constraint.Type == unifiedmodel.ConstraintTypeUnique {
+			add(tableKey{name: name, keyType: constraint.Type, columns: constraint.Columns})
+		}
+	}
+
+	indexNames := make([]string, 0, len(table.Indexes))
+	for name := range table.Indexes {
+		indexNames = append(indexNames, name)
+	}
+	sort.Strings(indexNames)
+	for _, name := range indexNames {
+		if index := table.Indexes[name]; index.Unique && index.Predicate == "" && index.Expression == "" {
+			add(tableKey{name: name, keyType: unifiedmodel.ConstraintTypeUnique, columns: index.Columns})
+		}
+	}
+
+	for _, name := range constraintNames {
+		if constraint := table.Constraints[name]; constraint.Type == unifiedmodel.ConstraintTypeForeignKey {
+			add(tableKey{
+				name:              name,
+				keyType:           constraint.Type,
+				columns:           constraint.Columns,
+				referencedTable:   constraint.Reference.Table,
+				referencedColumns: constraint.Reference.Columns,
+			})
+		}
+	}
+	return keys
+}
+
+// ordinalPosition sorts columns by their position in the table, then by name
+func ordinalPosition(column unifiedmodel.Column, name string) string {
+	if column.OrdinalPosition != nil {
+		return fmt.Sprintf("%08d", *column.OrdinalPosition)
+	}
+	return "~" + name
+}
+
+// matchKeys matches the composite keys of a source table with the keys of its target table. A
+// primary or unique key matches a primary or unique key with as many columns, and a foreign key
+// a foreign key with as many columns referencing the target table the referenced table is
+// matched with. The columns of two keys are paired by their similarity, and the keys match when
+// every pair scores at least the name similarity threshold; the key they are part of makes up for
+// the evidence single columns would lack. The key match scores the average of its pairs.
+func (m *UnifiedModelMatcher) matchKeys(
+	sourceTableName string, sourceTable unifiedmodel.Table,
+	targetTableName string, targetTable unifiedmodel.Table,
+	tablePairs map[string]string,
+	sourceEnrichment, targetEnrichment *unifiedmodel.UnifiedModelEnrichment,
+	options *UnifiedMatchOptions,
+) []UnifiedKeyMatch {
+	targetKeys := compositeKeys(targetTable)
+	usedTargetKeys := make(map[string]bool)
+	pairedColumns := make(map[string]string)
+
+	var matches []UnifiedKeyMatch
+	for _, sourceKey := range compositeKeys(sourceTable) {
+		var best *UnifiedKeyMatch
+		for _, targetKey := range targetKeys {
+			if usedTargetKeys[targetKey.name] || len(targetKey.columns) != len(sourceKey.columns) {
+				continue
+			}
+			if sourceKey.identifies() != targetKey.identifies() {
+				continue
+			}
+			if sourceKey.keyType == unifiedmodel.ConstraintTypeForeignKey {
+				if referenced, ok := tablePairs[sourceKey.referencedTable]; !ok || referenced != targetKey.referencedTable {
+					continue
+				}
+			}
+
+			sourceColumns, targetColumns, score, ok := m.pairKeyColumns(
+				sourceTableName, sourceTable, sourceKey,
+				targetTableName, targetTable, targetKey,
+				pairedColumns, sourceEnrichment, targetEnrichment, options,
+			)
+			if !ok {
+				continue
+			}
+			// Prefer a key of the same type, e.g. the primary key to a unique key
+			if targetKey.keyType != sourceKey.keyType {
+				score *= 0.9
+			}
+			if best != nil && score <= best.Score {
+				continue
+			}
+			best = &UnifiedKeyMatch{
+				SourceTable:   sourceTableName,
+				TargetTable:   targetTableName,
+				KeyType:       string(sourceKey.keyType),
+				SourceKey:     sourceKey.name,
+				TargetKey:     targetKey.name,
+				TargetKeyType: string(targetKey.keyType),
+				SourceColumns: sourceColumns,
+				TargetColumns: targetColumns,
+				Cardinality:   KeyCardinalityOneToOne,
+				Score:         score,
+			}
+			if sourceKey.keyType == unifiedmodel.ConstraintTypeForeignKey {
+				best.Cardinality = KeyCardinalityManyToOne
+				best.ReferencedSourceTable = sourceKey.referencedTable
+				best.ReferencedTargetTable = targetKey.referencedTable
+				best.ReferencedSourceColumns = referencedColumns(sourceKey, sourceColumns)
+				best.ReferencedTargetColumns = referencedColumns(targetKey, targetColumns)
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		usedTargetKeys[best.TargetKey] = true
+		for i, column := range best.SourceColumns {
+			pairedColumns[column] = best.TargetColumns[i]
+		}
+		best.Reasons = keyMatchReasons(best)
+		matches = append(matches, *best)
+	}
+	return matches
+}
+
+// pairKeyColumns pairs the columns of two keys, the most similar first, and returns them in the
+// order of the source key with their average score. A column already paired by another key
+// keeps its pair; ok is false when a column cannot be paired with a score of at least the name
+// similarity threshold.
+func (m *UnifiedModelMatcher) pairKeyColumns(
+	sourceTableName string, sourceTable unifiedmodel.Table, sourceKey tableKey,
+	targetTableName string, targetTable unifiedmodel.Table, targetKey tableKey,
+	pairedColumns map[string]string,
+	sourceEnrichment, targetEnrichment *unifiedmodel.UnifiedModelEnrichment,
+	options *UnifiedMatchOptions,
+) (sourceColumns, targetColumns []string, score float64, ok bool) {
+	type pair struct {
+		source, target int
+		score          float64
+	}
+	var pairs []pair
+	for i, sourceColumnName := range sourceKey.columns {
+		sourceColumn, exists := sourceTable.Columns[sourceColumnName]
+		if !exists {
+			return nil, nil, 0, false
+		}
+		for j, targetColumnName := range targetKey.columns {
+			targetColumn, exists := targetTable.Columns[targetColumnName]
+			if !exists {
+				return nil, nil, 0, false
+			}
+			if paired, exists := pairedColumns[sourceColumnName]; exists && paired != targetColumnName {
+				continue
+			}
+			pairScore := m.calculateColumnSimilarity(
+				sourceColumn, targetColumn,
+				sourceTableName, sourceColumnName,
+				targetTableName, targetColumnName,
+				sourceEnrichment, targetEnrichment,
+				options,
+			)
+			pairScore = options.applyFeedback(pairScore, sourceColumnName, targetColumnName)
+			if pairScore > 0 && pairScore >= options.NameSimilarityThreshold {
+				pairs = append(pairs, pair{source: i, target: j, score: pairScore})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].score > pairs[b].score })
+
+	targets := make([]int, len(sourceKey.columns))
+	for i := range targets {
+		targets[i] = -1
+	}
+	usedTargets := make(map[int]bool)
+	total := 0.0
+	for _, p := range pairs {
+		if targets[p.source] >= 0 || usedTargets[p.target] {
+			continue
+		}
+		targets[p.source] = p.target
+		usedTargets[p.target] = true
+		total += p.score
+	}
+
+	for i, target := range targets {
+		if target < 0 {
+			return nil, nil, 0, false
+		}
+		sourceColumns = append(sourceColumns, sourceKey.columns[i])
+		targetColumns = append(targetColumns, targetKey.columns[target])
+	}
+	return sourceColumns, targetColumns, total / float64(len(targets)), true
+}
+
+// referencedColumns returns the referenced columns of a foreign key in the order of its columns
+// as paired, or nil when the foreign key does not list them
+func referencedColumns(key tableKey, columns []string) []string {
+	if len(key.referencedColumns) != len(key.columns) {
+		return nil
+	}
+	position := make(map[string]int, len(key.columns))
+	for i, column := range key.columns {
+		position[column] = i
+	}
+	referenced := make([]string, 0, len(columns))
+	for _, column := range columns {
+		referenced = append(referenced, key.referencedColumns[position[column]])
+	}
+	return referenced
+}
+
+// keyMatchReasons explains a key match
+func keyMatchReasons(match *UnifiedKeyMatch) []string {
+	pairs := make([]string, 0, len(match.SourceColumns))
+	for i, column := range match.SourceColumns {
+		pairs = append(pairs, fmt.Sprintf("%s -> %s", column, match.TargetColumns[i]))
+	}
+	reasons := []string{fmt.Sprintf("%s %s (%s) matches %s %s with its columns paired as %s",
+		keyTypeName(match.KeyType), match.SourceKey, strings.Join(match.SourceColumns, ", "),
+		keyTypeName(match.TargetKeyType), match.TargetKey, strings.Join(pairs, ", "))}
+	if match.KeyType == string(unifiedmodel.ConstraintTypeForeignKey) {
+		reasons = append(reasons, fmt.Sprintf("both reference the matched tables %s and %s",
+			match.ReferencedSourceTable, match.ReferencedTargetTable))
+	}
+	return reasons
+}
+
+// keyTypeName names a key type in reasons
+func keyTypeName(keyType string) string {
+	switch unifiedmodel.ConstraintType(keyType) {
+	case unifiedmodel.ConstraintTypePrimaryKey:
+		return "primary key"
+	case unifiedmodel.ConstraintTypeForeignKey:
+		return "foreign key"
+	default:
+		return "unique key"
+	}
+}
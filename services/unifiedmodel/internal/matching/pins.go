@@ -0,0 +1,115 @@
+package matching
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// TablePin fixes the pairing of a source and a target table before matching: the source table
+// is paired with the target table whatever their score, or, with Never, never paired with it.
+// Pins keep users from correcting the same bad table matches each time a mapping is re-matched.
+type TablePin struct {
+	SourceTable string `json:"sourceTable"`
+	TargetTable string `json:"targetTable"`
+	Never       bool   `json:"never,omitempty"`
+}
+
+// tablePins are the table pins of a match, resolved against the tables of the models
+type tablePins struct {
+	pairs []tablePair // Pinned pairs, in the order of the pins
+	never map[string]map[string]bool
+}
+
+type tablePair struct {
+	source, target string
+}
+
+// resolveTablePins resolves the table pins of the options against the tables of the models.
+// Pins of missing tables, and match pins conflicting with an earlier pin, are ignored with a
+// warning.
+func resolveTablePins(sourceModel, targetModel *unifiedmodel.UnifiedModel, options *UnifiedMatchOptions) (*tablePins, []string) {
+	pins := &tablePins{never: make(map[string]map[string]bool)}
+	var warnings []string
+
+	type resolvedPin struct {
+		TablePin
+		source, target string
+	}
+	var resolved []resolvedPin
+	for _, pin := range options.TablePins {
+		source, sourceOK := resolveTableName(sourceModel, pin.SourceTable)
+		target, targetOK := resolveTableName(targetModel, pin.TargetTable)
+		switch {
+		case !sourceOK:
+			warnings = append(warnings, fmt.Sprintf("ignoring the pin %s: source table %s not found", pin, pin.SourceTable))
+		case !targetOK:
+			warnings = append(warnings, fmt.Sprintf("ignoring the pin %s: target table %s not found", pin, pin.TargetTable))
+		default:
+			resolved = append(resolved, resolvedPin{TablePin: pin, source: source, target: target})
+		}
+	}
+
+	for _, pin := range resolved {
+		if pin.Never {
+			if pins.never[pin.source] == nil {
+				pins.never[pin.source] = make(map[string]bool)
+			}
+			pins.never[pin.source][pin.target] = true
+		}
+	}
+
+	pairedSources := make(map[string]string)
+	pairedTargets := make(map[string]string)
+	for _, pin := range resolved {
+		if pin.Never {
+			continue
+		}
+		switch {
+		case pins.never[pin.source][pin.target]:
+			warnings = append(warnings, fmt.Sprintf("ignoring the pin %s: the tables are also pinned never to match", pin))
+		case pairedSources[pin.source] != "" && pairedSources[pin.source] != pin.target:
+			warnings = append(warnings, fmt.Sprintf("ignoring the pin %s: source table %s is already pinned to %s", pin, pin.source, pairedSources[pin.source]))
+		case pairedTargets[pin.target] != "" && pairedTargets[pin.target] != pin.source:
+			warnings = append(warnings, fmt.Sprintf("ignoring the pin %s: target table %s is already pinned to %s", pin, pin.target, pairedTargets[pin.target]))
+		case pairedSources[pin.source] == "":
+			pairedSources[pin.source] = pin.target
+			pairedTargets[pin.target] = pin.source
+			pins.pairs = append(pins.pairs, tablePair{source: pin.source, target: pin.target})
+		}
+	}
+	return pins, warnings
+}
+
+// isNever tells whether a source table is pinned never to match a target table
+func (p *tablePins) isNever(sourceTableName, targetTableName string) bool {
+	return p.never[sourceTableName][targetTableName]
+}
+
+// resolveTableName returns the name of a table of a model, compared case-insensitively when no
+// table has the exact name and a single one has it ignoring case
+func resolveTableName(model *unifiedmodel.UnifiedModel, name string) (string, bool) {
+	if _, exists := model.Tables[name]; exists {
+		return name, true
+	}
+	found := ""
+	for tableName := range model.Tables {
+		if strings.EqualFold(tableName, name) {
+			if found != "" {
+				return "", false
+			}
+			found = tableName
+		}
+	}
+	return found, found != ""
+}
+
+// String returns the pin as written by users, e.g. "orders -> sales_orders" or
+// "orders -/> archive_orders" for a pin never to match
+func (p TablePin) String() string {
+	if p.Never {
+		return fmt.Sprintf("%s -/> %s", p.SourceTable, p.TargetTable)
+	}
+	return fmt.Sprintf("%s -> %s", p.SourceTable, p.TargetTable)
+}
@@ -27,6 +27,12 @@ type UnifiedMatchOptions struct {
 	PrivilegedDataWeight     float64 `json:"privilegedDataWeight"`
 	TableStructureWeight     float64 `json:"tableStructureWeight"`
 	EnableCrossTableMatching bool    `json:"enableCrossTableMatching"`
+
+	// Feedback adjusts the scores of column matches users corrected before
+	Feedback []MatchFeedback `json:"feedback,omitempty"`
+
+	// TablePins fix the pairing of tables before matching
+	TablePins []TablePin `json:"tablePins,omitempty"`
 }
 
 // DefaultUnifiedMatchOptions returns default unified matching options
@@ -45,17 +51,19 @@ func DefaultUnifiedMatchOptions() UnifiedMatchOptions {
 
 // UnifiedColumnMatch represents a column match result using shared types
 type UnifiedColumnMatch struct {
-	SourceTable              string  `json:"sourceTable"`
-	TargetTable              string  `json:"targetTable"`
-	SourceColumn             string  `json:"sourceColumn"`
-	TargetColumn             string  `json:"targetColumn"`
-	Score                    float64 `json:"score"`
-	IsTypeCompatible         bool    `json:"isTypeCompatible"`
-	IsPoorMatch              bool    `json:"isPoorMatch"`
-	IsUnmatched              bool    `json:"isUnmatched"`
-	PrivilegedDataMatch      bool    `json:"privilegedDataMatch"`
-	DataCategoryMatch        string  `json:"dataCategoryMatch"`
-	PrivilegedConfidenceDiff float64 `json:"privilegedConfidenceDiff"`
+	SourceTable              string        `json:"sourceTable"`
+	TargetTable              string        `json:"targetTable"`
+	SourceColumn             string        `json:"sourceColumn"`
+	TargetColumn             string        `json:"targetColumn"`
+	Score                    float64       `json:"score"`
+	IsTypeCompatible         bool          `json:"isTypeCompatible"`
+	IsPoorMatch              bool          `json:"isPoorMatch"`
+	IsUnmatched              bool          `json:"isUnmatched"`
+	PrivilegedDataMatch      bool          `json:"privilegedDataMatch"`
+	DataCategoryMatch        string        `json:"dataCategoryMatch"`
+	PrivilegedConfidenceDiff float64       `json:"privilegedConfidenceDiff"`
+	ScoreFactors             []ScoreFactor `json:"scoreFactors,omitempty"`
+	Reasons                  []string      `json:"reasons,omitempty"`
 }
 
 // UnifiedTableMatch represents a table match result using shared types
@@ -71,6 +79,10 @@ type UnifiedTableMatch struct {
 	TotalSourceColumns           int                  `json:"totalSourceColumns"`
 	TotalTargetColumns           int                  `json:"totalTargetColumns"`
 	ColumnMatches                []UnifiedColumnMatch `json:"columnMatches"`
+	KeyMatches                   []UnifiedKeyMatch    `json:"keyMatches,omitempty"`
+	ScoreFactors                 []ScoreFactor        `json:"scoreFactors,omitempty"`
+	Reasons                      []string             `json:"reasons,omitempty"`
+	Pinned                       bool                 `json:"pinned,omitempty"`
 }
 
 // UnifiedMatchResult represents the complete matching result
@@ -147,15 +159,32 @@ func (m *UnifiedModelMatcher) MatchUnifiedModels(
 		}
 	}
 
-	// Find best table matches using Hungarian algorithm (simplified greedy approach)
+	// Pair the pinned tables first, the others are paired around them
+	pins, pinWarnings := resolveTablePins(sourceModel, targetModel, options)
+	warnings = append(warnings, pinWarnings...)
+
 	usedTargetTables := make(map[string]bool)
+	tablePairs := make(map[string]string)
+	pinnedSourceTables := make(map[string]bool)
+	var pairedSourceTables []string
+
+	for _, pair := range pins.pairs {
+		usedTargetTables[pair.target] = true
+		tablePairs[pair.source] = pair.target
+		pinnedSourceTables[pair.source] = true
+		pairedSourceTables = append(pairedSourceTables, pair.source)
+	}
 
+	// Find best table matches using Hungarian algorithm (simplified greedy approach)
 	for _, sourceTableName := range sourceTableNames {
+		if pinnedSourceTables[sourceTableName] {
+			continue
+		}
 		bestTargetTable := ""
 		bestScore := 0.0
 
 		for targetTableName, score := range tableScores[sourceTableName] {
-			if !usedTargetTables[targetTableName] && score > bestScore {
+			if !usedTargetTables[targetTableName] && !pins.isNever(sourceTableName, targetTableName) && score > bestScore {
 				bestScore = score
 				bestTargetTable = targetTableName
 			}
@@ -163,34 +192,46 @@ func (m *UnifiedModelMatcher) MatchUnifiedModels(
 
 		if bestTargetTable != "" && bestScore > 0.0 {
 			usedTargetTables[bestTargetTable] = true
+			tablePairs[sourceTableName] = bestTargetTable
+			pairedSourceTables = append(pairedSourceTables, sourceTableName)
+		}
+	}
 
-			// Create detailed table match
-			sourceTable := sourceModel.Tables[sourceTableName]
-			targetTable := targetModel.Tables[bestTargetTable]
+	// Create the detailed table matches once all tables are paired, so that foreign keys can be
+	// matched by the tables they reference
+	for _, sourceTableName := range pairedSourceTables {
+		bestTargetTable := tablePairs[sourceTableName]
 
-			var sourceTableEnrichment *unifiedmodel.TableEnrichment
-			if sourceEnrichment != nil {
-				if enrichment, exists := sourceEnrichment.TableEnrichments[sourceTableName]; exists {
-					sourceTableEnrichment = &enrichment
-				}
-			}
+		// Create detailed table match
+		sourceTable := sourceModel.Tables[sourceTableName]
+		targetTable := targetModel.Tables[bestTargetTable]
 
-			var targetTableEnrichment *unifiedmodel.TableEnrichment
-			if targetEnrichment != nil {
-				if enrichment, exists := targetEnrichment.TableEnrichments[bestTargetTable]; exists {
-					targetTableEnrichment = &enrichment
-				}
+		var sourceTableEnrichment *unifiedmodel.TableEnrichment
+		if sourceEnrichment != nil {
+			if enrichment, exists := sourceEnrichment.TableEnrichments[sourceTableName]; exists {
+				sourceTableEnrichment = &enrichment
 			}
+		}
 
-			tableMatch := m.createTableMatch(
-				sourceTableName, sourceTable, sourceTableEnrichment,
-				bestTargetTable, targetTable, targetTableEnrichment,
-				sourceEnrichment, targetEnrichment,
-				options,
-			)
+		var targetTableEnrichment *unifiedmodel.TableEnrichment
+		if targetEnrichment != nil {
+			if enrichment, exists := targetEnrichment.TableEnrichments[bestTargetTable]; exists {
+				targetTableEnrichment = &enrichment
+			}
+		}
 
-			tableMatches = append(tableMatches, tableMatch)
+		tableMatch := m.createTableMatch(
+			sourceTableName, sourceTable, sourceTableEnrichment,
+			bestTargetTable, targetTable, targetTableEnrichment,
+			tablePairs, sourceEnrichment, targetEnrichment,
+			options,
+		)
+		if pinnedSourceTables[sourceTableName] {
+			tableMatch.Pinned = true
+			tableMatch.Reasons = append([]string{fmt.Sprintf("tables %s and %s are pinned to match", sourceTableName, bestTargetTable)}, tableMatch.Reasons...)
 		}
+
+		tableMatches = append(tableMatches, tableMatch)
 	}
 
 	// Calculate overall similarity score
@@ -348,17 +389,26 @@ func (m *UnifiedModelMatcher) calculateClassificationSimilarity(
 func (m *UnifiedModelMatcher) createTableMatch(
 	sourceTableName string, sourceTable unifiedmodel.Table, sourceEnrichment *unifiedmodel.TableEnrichment,
 	targetTableName string, targetTable unifiedmodel.Table, targetEnrichment *unifiedmodel.TableEnrichment,
+	tablePairs map[string]string,
 	sourceModelEnrichment, targetModelEnrichment *unifiedmodel.UnifiedModelEnrichment,
 	options *UnifiedMatchOptions,
 ) UnifiedTableMatch {
 	// Calculate table-level score
 	tableScore := m.calculateTableSimilarity(sourceTable, sourceEnrichment, targetTable, targetEnrichment, options)
 
+	// Match composite keys first, their columns are matched as the keys pair them
+	keyMatches := m.matchKeys(
+		sourceTableName, sourceTable,
+		targetTableName, targetTable,
+		tablePairs, sourceModelEnrichment, targetModelEnrichment,
+		options,
+	)
+
 	// Match columns
 	columnMatches := m.matchColumns(
 		sourceTableName, sourceTable,
 		targetTableName, targetTable,
-		sourceModelEnrichment, targetModelEnrichment,
+		keyMatches, sourceModelEnrichment, targetModelEnrichment,
 		options,
 	)
 
@@ -381,6 +431,8 @@ func (m *UnifiedModelMatcher) createTableMatch(
 		}
 	}
 
+	scoreFactors := m.explainTableMatch(sourceTable, sourceEnrichment, targetTable, targetEnrichment, options)
+
 	return UnifiedTableMatch{
 		SourceTable:                  sourceTableName,
 		TargetTable:                  targetTableName,
@@ -393,13 +445,18 @@ func (m *UnifiedModelMatcher) createTableMatch(
 		TotalSourceColumns:           len(sourceTable.Columns),
 		TotalTargetColumns:           len(targetTable.Columns),
 		ColumnMatches:                columnMatches,
+		KeyMatches:                   keyMatches,
+		ScoreFactors:                 scoreFactors,
+		Reasons:                      matchReasons(scoreFactors, tableScore, options),
 	}
 }
 
-// matchColumns matches columns between two tables
+// matchColumns matches columns between two tables. The columns of matched keys are matched with
+// the columns the keys pair them with.
 func (m *UnifiedModelMatcher) matchColumns(
 	sourceTableName string, sourceTable unifiedmodel.Table,
 	targetTableName string, targetTable unifiedmodel.Table,
+	keyMatches []UnifiedKeyMatch,
 	sourceEnrichment, targetEnrichment *unifiedmodel.UnifiedModelEnrichment,
 	options *UnifiedMatchOptions,
 ) []UnifiedColumnMatch {
@@ -418,6 +475,16 @@ func (m *UnifiedModelMatcher) matchColumns(
 
 	usedTargetColumns := make(map[string]bool)
 
+	keyColumns := make(map[string]string)
+	keyOfColumn := make(map[string]*UnifiedKeyMatch)
+	for i := range keyMatches {
+		for j, sourceColumnName := range keyMatches[i].SourceColumns {
+			keyColumns[sourceColumnName] = keyMatches[i].TargetColumns[j]
+			keyOfColumn[sourceColumnName] = &keyMatches[i]
+			usedTargetColumns[keyMatches[i].TargetColumns[j]] = true
+		}
+	}
+
 	for _, sourceColumnName := range sourceColumns {
 		sourceColumn := sourceTable.Columns[sourceColumnName]
 
@@ -425,7 +492,11 @@ func (m *UnifiedModelMatcher) matchColumns(
 		bestScore := 0.0
 
 		for _, targetColumnName := range targetColumns {
-			if usedTargetColumns[targetColumnName] {
+			if keyTarget, ok := keyColumns[sourceColumnName]; ok {
+				if targetColumnName != keyTarget {
+					continue
+				}
+			} else if usedTargetColumns[targetColumnName] {
 				continue
 			}
 
@@ -437,6 +508,7 @@ func (m *UnifiedModelMatcher) matchColumns(
 				sourceEnrichment, targetEnrichment,
 				options,
 			)
+			score = options.applyFeedback(score, sourceColumnName, targetColumnName)
 
 			if score > bestScore {
 				bestScore = score
@@ -454,6 +526,12 @@ func (m *UnifiedModelMatcher) matchColumns(
 				sourceEnrichment, targetEnrichment,
 				bestScore, options,
 			)
+			if key := keyOfColumn[sourceColumnName]; key != nil {
+				// The key match vouches for the pair even when its columns alone match poorly
+				match.IsPoorMatch = false
+				match.Reasons = append(match.Reasons, fmt.Sprintf("paired as a column of %s %s with %s %s",
+					keyTypeName(key.KeyType), key.SourceKey, keyTypeName(key.TargetKeyType), key.TargetKey))
+			}
 			matches = append(matches, match)
 		} else {
 			// Unmatched source column
@@ -462,6 +540,7 @@ func (m *UnifiedModelMatcher) matchColumns(
 				SourceColumn: sourceColumnName,
 				IsUnmatched:  true,
 				Score:        0.0,
+				Reasons:      []string{fmt.Sprintf("no remaining column of %s has a name, type or classification in common with %s", targetTableName, sourceColumnName)},
 			}
 			matches = append(matches, match)
 		}
@@ -616,6 +695,13 @@ func (m *UnifiedModelMatcher) createColumnMatch(
 		}
 	}
 
+	scoreFactors := m.explainColumnMatch(
+		sourceColumnName, sourceColumn, targetColumnName, targetColumn,
+		m.columnEnrichment(sourceEnrichment, sourceTableName, sourceColumnName),
+		m.columnEnrichment(targetEnrichment, targetTableName, targetColumnName),
+		options,
+	)
+
 	return UnifiedColumnMatch{
 		SourceTable:              sourceTableName,
 		TargetTable:              targetTableName,
@@ -628,6 +714,8 @@ func (m *UnifiedModelMatcher) createColumnMatch(
 		PrivilegedDataMatch:      privilegedDataMatch,
 		DataCategoryMatch:        dataCategoryMatch,
 		PrivilegedConfidenceDiff: privilegedConfidenceDiff,
+		ScoreFactors:             scoreFactors,
+		Reasons:                  matchReasons(scoreFactors, score, options),
 	}
 }
 
@@ -1,6 +1,9 @@
 package matching
 
 import (
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/redbco/redb-open/pkg/unifiedmodel"
@@ -559,3 +562,370 @@ func TestCalculateOverallSimilarity(t *testing.T) {
 		t.Errorf("Expected penalty for unmatched tables, got %f", score)
 	}
 }
+
+func TestMatchUnifiedModels_ExplainsScores(t *testing.T) {
+	matcher := NewUnifiedModelMatcher()
+
+	sourceModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers": {
+				Name: "customers",
+				Columns: map[string]unifiedmodel.Column{
+					"email_address": {Name: "email_address", DataType: "varchar"},
+				},
+			},
+		},
+	}
+	targetModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers": {
+				Name: "customers",
+				Columns: map[string]unifiedmodel.Column{
+					"email": {Name: "email", DataType: "text"},
+				},
+			},
+		},
+	}
+	sourceEnrichment := &unifiedmodel.UnifiedModelEnrichment{
+		ColumnEnrichments: map[string]unifiedmodel.ColumnEnrichment{
+			"customers.email_address": {IsPrivilegedData: true, DataCategory: unifiedmodel.DataCategoryEmail, RiskLevel: unifiedmodel.RiskLevelHigh},
+		},
+	}
+	targetEnrichment := &unifiedmodel.UnifiedModelEnrichment{
+		ColumnEnrichments: map[string]unifiedmodel.ColumnEnrichment{
+			"customers.email": {IsPrivilegedData: true, DataCategory: unifiedmodel.DataCategoryEmail, RiskLevel: unifiedmodel.RiskLevelHigh},
+		},
+	}
+
+	result, err := matcher.MatchUnifiedModels(sourceModel, sourceEnrichment, targetModel, targetEnrichment, nil)
+	if err != nil {
+		t.Fatalf("MatchUnifiedModels failed: %v", err)
+	}
+	if len(result.TableMatches) != 1 || len(result.TableMatches[0].ColumnMatches) != 1 {
+		t.Fatalf("Expected 1 table match with 1 column match, got %+v", result.TableMatches)
+	}
+
+	tableMatch := result.TableMatches[0]
+	if len(tableMatch.ScoreFactors) != 3 || tableMatch.ScoreFactors[1].Factor != FactorStructure {
+		t.Errorf("Expected name, structure and classification factors for the table match, got %+v", tableMatch.ScoreFactors)
+	}
+
+	columnMatch := tableMatch.ColumnMatches[0]
+	factors := map[string]ScoreFactor{}
+	contributions := 0.0
+	for _, factor := range columnMatch.ScoreFactors {
+		factors[factor.Factor] = factor
+		contributions += factor.Contribution
+	}
+	if math.Abs(contributions-columnMatch.Score) > 1e-9 {
+		t.Errorf("Expected contributions adding up to the score %f, got %f", columnMatch.Score, contributions)
+	}
+
+	name := factors[FactorNameSimilarity]
+	if name.Score <= 0 || !strings.Contains(name.Reason, "email_address contains email") {
+		t.Errorf("Unexpected name similarity factor %+v", name)
+	}
+	dataType := factors[FactorTypeCompatibility]
+	if dataType.Score != 1.0 || dataType.Reason != "data types varchar and text are compatible" {
+		t.Errorf("Unexpected type compatibility factor %+v", dataType)
+	}
+	classification := factors[FactorClassificationAgreement]
+	if classification.Score != 1.0 || !strings.Contains(classification.Reason, "both classified as email") ||
+		!strings.Contains(classification.Reason, "both privileged data") {
+		t.Errorf("Unexpected classification agreement factor %+v", classification)
+	}
+
+	if len(columnMatch.Reasons) != 3 {
+		t.Fatalf("Expected a reason per factor, got %v", columnMatch.Reasons)
+	}
+	// Reasons start with the most contributing factor
+	if !strings.HasPrefix(columnMatch.Reasons[0], "data types varchar and text are compatible") &&
+		!strings.HasPrefix(columnMatch.Reasons[0], "both classified as email") {
+		t.Errorf("Expected the reasons to start with a fully scoring factor, got %v", columnMatch.Reasons)
+	}
+}
+
+func TestNameSimilarityReason(t *testing.T) {
+	matcher := NewUnifiedModelMatcher()
+
+	tests := []struct {
+		source, target string
+		want           string
+	}{
+		{"Email", "email", "column names Email and email are the same"},
+		{"user", "user_name", "column name user_name contains user"},
+		{"user_ref", "owner_user", `column names user_ref and owner_user both contain "user"`},
+		{"price", "quantity", "column names price and quantity are not similar"},
+	}
+
+	for _, tt := range tests {
+		score := matcher.calculateStringSimilarity(tt.source, tt.target)
+		if got := matcher.nameSimilarityReason("column", tt.source, tt.target, score); got != tt.want {
+			t.Errorf("nameSimilarityReason(%s, %s) = %q, want %q", tt.source, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestMatchUnifiedModels_Feedback(t *testing.T) {
+	matcher := NewUnifiedModelMatcher()
+
+	sourceModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {
+				Name: "orders",
+				Columns: map[string]unifiedmodel.Column{
+					"contact_email": {Name: "contact_email", DataType: "varchar"},
+				},
+			},
+		},
+	}
+	targetModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {
+				Name: "orders",
+				Columns: map[string]unifiedmodel.Column{
+					"email":         {Name: "email", DataType: "varchar"},
+					"billing_email": {Name: "billing_email", DataType: "varchar"},
+				},
+			},
+		},
+	}
+
+	match := func(options *UnifiedMatchOptions) UnifiedColumnMatch {
+		t.Helper()
+		result, err := matcher.MatchUnifiedModels(sourceModel, nil, targetModel, nil, options)
+		if err != nil {
+			t.Fatalf("MatchUnifiedModels failed: %v", err)
+		}
+		if len(result.TableMatches) != 1 || len(result.TableMatches[0].ColumnMatches) != 1 {
+			t.Fatalf("Expected 1 table match with 1 column match, got %+v", result.TableMatches)
+		}
+		return result.TableMatches[0].ColumnMatches[0]
+	}
+
+	options := DefaultUnifiedMatchOptions()
+	if got := match(&options); got.TargetColumn != "billing_email" {
+		t.Fatalf("Expected contact_email to match billing_email without feedback, got %s", got.TargetColumn)
+	}
+
+	// Users re-pointed the rules of the match to email; names are compared ignoring case and
+	// punctuation
+	options.Feedback = []MatchFeedback{
+		{SourceColumn: "ContactEmail", TargetColumn: "billing-email", Adjustment: -0.45, Rejected: 3},
+		{SourceColumn: "contact_email", TargetColumn: "Email", Adjustment: 0.3, Accepted: 2},
+	}
+	got := match(&options)
+	if got.TargetColumn != "email" {
+		t.Fatalf("Expected contact_email to match email with feedback, got %s", got.TargetColumn)
+	}
+
+	var feedback *ScoreFactor
+	contributions := 0.0
+	for i, factor := range got.ScoreFactors {
+		contributions += factor.Contribution
+		if factor.Factor == FactorFeedback {
+			feedback = &got.ScoreFactors[i]
+		}
+	}
+	if feedback == nil || feedback.Contribution != 0.3 || !strings.Contains(feedback.Reason, "accepted contact_email -> email 2 times") {
+		t.Fatalf("Expected a feedback factor adding 0.3, got %+v", got.ScoreFactors)
+	}
+	if math.Abs(contributions-got.Score) > 1e-9 {
+		t.Errorf("Expected contributions adding up to the score %f, got %f", got.Score, contributions)
+	}
+	found := false
+	for _, reason := range got.Reasons {
+		found = found || strings.HasSuffix(reason, "(feedback 0.30, +0.30)")
+	}
+	if !found {
+		t.Errorf("Expected a reason for the feedback, got %v", got.Reasons)
+	}
+}
+
+func TestMatchUnifiedModels_CompositeKeys(t *testing.T) {
+	matcher := NewUnifiedModelMatcher()
+
+	sourceModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {
+				Name: "orders",
+				Columns: map[string]unifiedmodel.Column{
+					"store_id": {Name: "store_id", DataType: "integer"},
+					"order_no": {Name: "order_no", DataType: "integer"},
+					"total":    {Name: "total", DataType: "numeric"},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"orders_pkey": {Name: "orders_pkey", Type: unifiedmodel.ConstraintTypePrimaryKey, Columns: []string{"store_id", "order_no"}},
+				},
+			},
+			"order_lines": {
+				Name: "order_lines",
+				Columns: map[string]unifiedmodel.Column{
+					"store_id": {Name: "store_id", DataType: "integer", IsPrimaryKey: true},
+					"order_no": {Name: "order_no", DataType: "integer", IsPrimaryKey: true},
+					"line_no":  {Name: "line_no", DataType: "integer", IsPrimaryKey: true},
+					"quantity": {Name: "quantity", DataType: "integer"},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"order_lines_order_fkey": {
+						Name:      "order_lines_order_fkey",
+						Type:      unifiedmodel.ConstraintTypeForeignKey,
+						Columns:   []string{"store_id", "order_no"},
+						Reference: unifiedmodel.Reference{Table: "orders", Columns: []string{"store_id", "order_no"}},
+					},
+				},
+			},
+		},
+	}
+	targetModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {
+				Name: "orders",
+				Columns: map[string]unifiedmodel.Column{
+					"store": {Name: "store", DataType: "bigint"},
+					"order": {Name: "order", DataType: "bigint"},
+					"total": {Name: "total", DataType: "numeric"},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"orders_store_order_idx": {Name: "orders_store_order_idx", Columns: []string{"order", "store"}, Unique: true},
+				},
+			},
+			"order_lines": {
+				Name: "order_lines",
+				Columns: map[string]unifiedmodel.Column{
+					"store":    {Name: "store", DataType: "bigint"},
+					"order":    {Name: "order", DataType: "bigint"},
+					"line":     {Name: "line", DataType: "bigint"},
+					"quantity": {Name: "quantity", DataType: "integer"},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"order_lines_pkey": {Name: "order_lines_pkey", Type: unifiedmodel.ConstraintTypePrimaryKey, Columns: []string{"store", "order", "line"}},
+					"order_lines_orders_fkey": {
+						Name:      "order_lines_orders_fkey",
+						Type:      unifiedmodel.ConstraintTypeForeignKey,
+						Columns:   []string{"order", "store"},
+						Reference: unifiedmodel.Reference{Table: "orders", Columns: []string{"order", "store"}},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := matcher.MatchUnifiedModels(sourceModel, nil, targetModel, nil, nil)
+	if err != nil {
+		t.Fatalf("MatchUnifiedModels failed: %v", err)
+	}
+
+	tableMatches := make(map[string]UnifiedTableMatch)
+	for _, match := range result.TableMatches {
+		tableMatches[match.SourceTable] = match
+	}
+
+	orders := tableMatches["orders"]
+	if len(orders.KeyMatches) != 1 {
+		t.Fatalf("Expected 1 key match for orders, got %+v", orders.KeyMatches)
+	}
+	key := orders.KeyMatches[0]
+	if key.KeyType != "primary_key" || key.TargetKeyType != "unique" || key.Cardinality != KeyCardinalityOneToOne {
+		t.Errorf("Expected the primary key to match the unique index one-to-one, got %+v", key)
+	}
+	if !reflect.DeepEqual(key.SourceColumns, []string{"store_id", "order_no"}) || !reflect.DeepEqual(key.TargetColumns, []string{"store", "order"}) {
+		t.Errorf("Expected store_id, order_no paired with store, order, got %v and %v", key.SourceColumns, key.TargetColumns)
+	}
+
+	lines := tableMatches["order_lines"]
+	if len(lines.KeyMatches) != 2 {
+		t.Fatalf("Expected 2 key matches for order_lines, got %+v", lines.KeyMatches)
+	}
+	primaryKey, foreignKey := lines.KeyMatches[0], lines.KeyMatches[1]
+	if primaryKey.SourceKey != "order_lines_pkey" || primaryKey.TargetKey != "order_lines_pkey" ||
+		!reflect.DeepEqual(primaryKey.TargetColumns, []string{"line", "order", "store"}) {
+		t.Errorf("Expected the primary key flagged on columns to match order_lines_pkey, got %+v", primaryKey)
+	}
+	if foreignKey.KeyType != "foreign_key" || foreignKey.Cardinality != KeyCardinalityManyToOne ||
+		foreignKey.ReferencedSourceTable != "orders" || foreignKey.ReferencedTargetTable != "orders" {
+		t.Errorf("Expected the foreign keys to match many-to-one, got %+v", foreignKey)
+	}
+	if !reflect.DeepEqual(foreignKey.TargetColumns, []string{"store", "order"}) ||
+		!reflect.DeepEqual(foreignKey.ReferencedTargetColumns, []string{"store", "order"}) {
+		t.Errorf("Expected the foreign key columns paired in the source order, got %v referencing %v",
+			foreignKey.TargetColumns, foreignKey.ReferencedTargetColumns)
+	}
+
+	for _, columnMatch := range lines.ColumnMatches {
+		want := map[string]string{"store_id": "store", "order_no": "order", "line_no": "line"}[columnMatch.SourceColumn]
+		if want != "" && columnMatch.TargetColumn != want {
+			t.Errorf("Expected key column %s to match %s, got %s", columnMatch.SourceColumn, want, columnMatch.TargetColumn)
+		}
+	}
+}
+
+func TestMatchUnifiedModels_TablePins(t *testing.T) {
+	matcher := NewUnifiedModelMatcher()
+
+	columns := map[string]unifiedmodel.Column{
+		"id":   {Name: "id", DataType: "integer"},
+		"name": {Name: "name", DataType: "varchar"},
+	}
+	sourceModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers": {Name: "customers", Columns: columns},
+			"orders":    {Name: "orders", Columns: columns},
+		},
+	}
+	targetModel := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers":      {Name: "customers", Columns: columns},
+			"orders":         {Name: "orders", Columns: columns},
+			"archive_orders": {Name: "archive_orders", Columns: columns},
+		},
+	}
+
+	pairs := func(options *UnifiedMatchOptions) (map[string]string, *UnifiedMatchResult) {
+		t.Helper()
+		result, err := matcher.MatchUnifiedModels(sourceModel, nil, targetModel, nil, options)
+		if err != nil {
+			t.Fatalf("MatchUnifiedModels failed: %v", err)
+		}
+		pairs := make(map[string]string)
+		for _, match := range result.TableMatches {
+			pairs[match.SourceTable] = match.TargetTable
+		}
+		return pairs, result
+	}
+
+	options := DefaultUnifiedMatchOptions()
+	if got, _ := pairs(&options); got["customers"] != "customers" || got["orders"] != "orders" {
+		t.Fatalf("Expected the tables of the same names to match without pins, got %v", got)
+	}
+
+	// Orders is pinned to archive_orders, names compared ignoring case, and customers never to
+	// match customers
+	options.TablePins = []TablePin{
+		{SourceTable: "Orders", TargetTable: "archive_orders"},
+		{SourceTable: "customers", TargetTable: "customers", Never: true},
+		{SourceTable: "customers", TargetTable: "customers"},
+		{SourceTable: "invoices", TargetTable: "orders"},
+	}
+	got, result := pairs(&options)
+	if got["orders"] != "archive_orders" {
+		t.Errorf("Expected orders pinned to archive_orders, got %v", got)
+	}
+	if got["customers"] == "customers" || got["customers"] == "archive_orders" {
+		t.Errorf("Expected customers not to match customers nor the pinned archive_orders, got %v", got)
+	}
+	for _, match := range result.TableMatches {
+		if match.Pinned != (match.SourceTable == "orders") {
+			t.Errorf("Expected only orders to be pinned, got %s pinned %v", match.SourceTable, match.Pinned)
+		}
+		if match.Pinned && (len(match.Reasons) == 0 || !strings.Contains(match.Reasons[0], "pinned")) {
+			t.Errorf("Expected the pin first among the reasons, got %v", match.Reasons)
+		}
+	}
+	if len(result.Warnings) != 2 {
+		t.Fatalf("Expected warnings for the conflicting pin and the missing table, got %v", result.Warnings)
+	}
+	if !strings.Contains(result.Warnings[0], "invoices not found") || !strings.Contains(result.Warnings[1], "also pinned never") {
+		t.Errorf("Unexpected warnings %v", result.Warnings)
+	}
+}
@@ -0,0 +1,359 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/generators"
+)
+
+// dialect generates the statements altering and dropping the objects of a database, which the
+// generators do not cover as they only create schemas
+type dialect interface {
+	dropTable(table string) string
+	renameTable(table, name string) string
+	commentTable(table, comment string) string
+
+	addColumn(table string, column unifiedmodel.Column) (string, error)
+	dropColumn(table, column string) string
+	renameColumn(table, column, name string) string
+	// alters tells whether alterColumn applies the changes of a property of columns
+	alters(field string) bool
+	alterColumn(table string, prev, curr unifiedmodel.Column) ([]string, error)
+
+	createIndex(table string, index unifiedmodel.Index) (string, error)
+	dropIndex(table, index string) string
+
+	addConstraint(table string, constraint unifiedmodel.Constraint) (string, error)
+	dropConstraint(table string, constraint unifiedmodel.Constraint) (string, error)
+
+	// drop drops an object of the model, the table being set for triggers
+	drop(objectType unifiedmodel.ObjectType, name, table string) (string, error)
+}
+
+// dialectFor returns the dialect of a database type, using the definitions of its generator
+func dialectFor(databaseType string, generator generators.StatementGenerator) (dialect, error) {
+	definitions, ok := generator.(generators.DefinitionGenerator)
+	if ok {
+		switch databaseType {
+		case "postgres":
+			return &postgresDialect{definitions: definitions}, nil
+		case "mysql":
+			return &mysqlDialect{definitions: definitions}, nil
+		case "mariadb":
+			return &mysqlDialect{definitions: definitions, mariadb: true}, nil
+		}
+	}
+	return nil, fmt.Errorf("migration plans are not supported for database type: %s", databaseType)
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// postgresDialect does not quote identifiers, like the PostgreSQL generator
+type postgresDialect struct {
+	definitions generators.DefinitionGenerator
+}
+
+// Index methods of PostgreSQL, other index types only describe the index
+var postgresIndexMethods = map[unifiedmodel.IndexType]bool{
+	unifiedmodel.IndexTypeBTree: true,
+	unifiedmodel.IndexTypeHash:  true,
+	unifiedmodel.IndexTypeGIN:   true,
+	unifiedmodel.IndexTypeGiST:  true,
+	"brin":                      true,
+	"spgist":                    true,
+}
+
+func (d *postgresDialect) dropTable(table string) string {
+	return fmt.Sprintf("DROP TABLE %s;", table)
+}
+
+func (d *postgresDialect) renameTable(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", table, name)
+}
+
+func (d *postgresDialect) commentTable(table, comment string) string {
+	if comment == "" {
+		return fmt.Sprintf("COMMENT ON TABLE %s IS NULL;", table)
+	}
+	return fmt.Sprintf("COMMENT ON TABLE %s IS %s;", table, quoteString(comment))
+}
+
+func (d *postgresDialect) addColumn(table string, column unifiedmodel.Column) (string, error) {
+	definition, err := d.definitions.GenerateColumnDefinition(column)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", table, definition), nil
+}
+
+func (d *postgresDialect) dropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", table, column)
+}
+
+func (d *postgresDialect) renameColumn(table, column, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", table, column, name)
+}
+
+func (d *postgresDialect) alters(field string) bool {
+	switch field {
+	case "data_type", "collation", "nullable", "default":
+		return true
+	}
+	return false
+}
+
+func (d *postgresDialect) alterColumn(table string, prev, curr unifiedmodel.Column) ([]string, error) {
+	var statements []string
+	if prev.DataType != curr.DataType || prev.Collation != curr.Collation {
+		dataType := d.definitions.MapDataType(curr.DataType)
+		statement := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", table, curr.Name, dataType)
+		if curr.Collation != "" {
+			statement += fmt.Sprintf(" COLLATE \"%s\"", curr.Collation)
+		}
+		if prev.DataType != curr.DataType {
+			statement += fmt.Sprintf(" USING %s::%s", curr.Name, dataType)
+		}
+		statements = append(statements, statement+";")
+	}
+	if prev.Nullable != curr.Nullable {
+		action := "SET NOT NULL"
+		if curr.Nullable {
+			action = "DROP NOT NULL"
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;", table, curr.Name, action))
+	}
+	if prev.Default != curr.Default {
+		action := "DROP DEFAULT"
+		if curr.Default != "" {
+			action = "SET DEFAULT " + curr.Default
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;", table, curr.Name, action))
+	}
+	return statements, nil
+}
+
+func (d *postgresDialect) createIndex(table string, index unifiedmodel.Index) (string, error) {
+	columns, err := indexColumns(index, func(name string) string { return name })
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if index.Unique {
+		sb.WriteString("CREATE UNIQUE INDEX ")
+	} else {
+		sb.WriteString("CREATE INDEX ")
+	}
+	sb.WriteString(fmt.Sprintf("%s ON %s", index.Name, table))
+	if postgresIndexMethods[index.Type] {
+		sb.WriteString(fmt.Sprintf(" USING %s", strings.ToUpper(string(index.Type))))
+	}
+	sb.WriteString(fmt.Sprintf(" (%s)", columns))
+	if index.Predicate != "" {
+		sb.WriteString(fmt.Sprintf(" WHERE %s", index.Predicate))
+	}
+	sb.WriteString(";")
+	return sb.String(), nil
+}
+
+func (d *postgresDialect) dropIndex(table, index string) string {
+	// Indexes belong to the schema in PostgreSQL, not to the table
+	return fmt.Sprintf("DROP INDEX %s;", index)
+}
+
+func (d *postgresDialect) addConstraint(table string, constraint unifiedmodel.Constraint) (string, error) {
+	definition, err := d.definitions.GenerateConstraintDefinition(constraint)
+	if err != nil {
+		return "", err
+	}
+	if definition == "" {
+		return "", fmt.Errorf("unsupported constraint type: %s", constraint.Type)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", table, definition), nil
+}
+
+func (d *postgresDialect) dropConstraint(table string, constraint unifiedmodel.Constraint) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", table, constraint.Name), nil
+}
+
+func (d *postgresDialect) drop(objectType unifiedmodel.ObjectType, name, table string) (string, error) {
+	switch objectType {
+	case unifiedmodel.ObjectTypeTrigger:
+		return fmt.Sprintf("DROP TRIGGER %s ON %s;", name, table), nil
+	case unifiedmodel.ObjectTypeMaterializedView:
+		return fmt.Sprintf("DROP MATERIALIZED VIEW %s;", name), nil
+	case unifiedmodel.ObjectTypeView, unifiedmodel.ObjectTypeFunction, unifiedmodel.ObjectTypeProcedure,
+		unifiedmodel.ObjectTypeSequence, unifiedmodel.ObjectTypeType, unifiedmodel.ObjectTypeSchema,
+		unifiedmodel.ObjectTypeExtension:
+		return fmt.Sprintf("DROP %s %s;", strings.ToUpper(string(objectType)), name), nil
+	}
+	return "", fmt.Errorf("unsupported object type: %s", objectType)
+}
+
+// mysqlDialect serves MySQL and MariaDB, quoting identifiers with backticks like their generators
+type mysqlDialect struct {
+	definitions generators.DefinitionGenerator
+	mariadb     bool
+}
+
+func (d *mysqlDialect) quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (d *mysqlDialect) dropTable(table string) string {
+	return fmt.Sprintf("DROP TABLE %s;", d.quote(table))
+}
+
+func (d *mysqlDialect) renameTable(table, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", d.quote(table), d.quote(name))
+}
+
+func (d *mysqlDialect) commentTable(table, comment string) string {
+	return fmt.Sprintf("ALTER TABLE %s COMMENT = %s;", d.quote(table), quoteString(comment))
+}
+
+func (d *mysqlDialect) addColumn(table string, column unifiedmodel.Column) (string, error) {
+	definition, err := d.definitions.GenerateColumnDefinition(column)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", d.quote(table), definition), nil
+}
+
+func (d *mysqlDialect) dropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", d.quote(table), d.quote(column))
+}
+
+func (d *mysqlDialect) renameColumn(table, column, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", d.quote(table), d.quote(column), d.quote(name))
+}
+
+func (d *mysqlDialect) alters(field string) bool {
+	switch field {
+	case "data_type", "nullable", "default", "auto_increment":
+		return true
+	}
+	return false
+}
+
+func (d *mysqlDialect) alterColumn(table string, prev, curr unifiedmodel.Column) ([]string, error) {
+	// MODIFY COLUMN restates the whole column; the primary key is kept by the table, not restated
+	curr.IsPrimaryKey = false
+	definition, err := d.definitions.GenerateColumnDefinition(curr)
+	if err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", d.quote(table), definition)}, nil
+}
+
+func (d *mysqlDialect) createIndex(table string, index unifiedmodel.Index) (string, error) {
+	if index.Predicate != "" {
+		return "", fmt.Errorf("partial indexes are not supported")
+	}
+	columns, err := indexColumns(index, d.quote)
+	if err != nil {
+		return "", err
+	}
+	if index.Expression != "" {
+		// Functional key parts are enclosed in their own parentheses
+		columns = "(" + columns + ")"
+	}
+
+	var sb strings.Builder
+	switch {
+	case index.Type == unifiedmodel.IndexTypeFullText:
+		sb.WriteString("CREATE FULLTEXT INDEX ")
+	case index.Type == unifiedmodel.IndexTypeSpatial:
+		sb.WriteString("CREATE SPATIAL INDEX ")
+	case index.Unique:
+		sb.WriteString("CREATE UNIQUE INDEX ")
+	default:
+		sb.WriteString("CREATE INDEX ")
+	}
+	sb.WriteString(fmt.Sprintf("%s ON %s (%s)", d.quote(index.Name), d.quote(table), columns))
+	if index.Type == unifiedmodel.IndexTypeBTree || index.Type == unifiedmodel.IndexTypeHash {
+		sb.WriteString(fmt.Sprintf(" USING %s", strings.ToUpper(string(index.Type))))
+	}
+	sb.WriteString(";")
+	return sb.String(), nil
+}
+
+func (d *mysqlDialect) dropIndex(table, index string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;", d.quote(index), d.quote(table))
+}
+
+func (d *mysqlDialect) addConstraint(table string, constraint unifiedmodel.Constraint) (string, error) {
+	definition, err := d.definitions.GenerateConstraintDefinition(constraint)
+	if err != nil {
+		return "", err
+	}
+	if definition == "" {
+		return "", fmt.Errorf("unsupported constraint type: %s", constraint.Type)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", d.quote(table), definition), nil
+}
+
+func (d *mysqlDialect) dropConstraint(table string, constraint unifiedmodel.Constraint) (string, error) {
+	var action string
+	switch constraint.Type {
+	case unifiedmodel.ConstraintTypePrimaryKey:
+		action = "DROP PRIMARY KEY"
+	case unifiedmodel.ConstraintTypeForeignKey:
+		action = "DROP FOREIGN KEY " + d.quote(constraint.Name)
+	case unifiedmodel.ConstraintTypeUnique:
+		action = "DROP INDEX " + d.quote(constraint.Name)
+	case unifiedmodel.ConstraintTypeCheck:
+		if d.mariadb {
+			action = "DROP CONSTRAINT " + d.quote(constraint.Name)
+		} else {
+			action = "DROP CHECK " + d.quote(constraint.Name)
+		}
+	default:
+		return "", fmt.Errorf("unsupported constraint type: %s", constraint.Type)
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s;", d.quote(table), action), nil
+}
+
+func (d *mysqlDialect) drop(objectType unifiedmodel.ObjectType, name, table string) (string, error) {
+	switch objectType {
+	case unifiedmodel.ObjectTypeView, unifiedmodel.ObjectTypeFunction, unifiedmodel.ObjectTypeProcedure,
+		unifiedmodel.ObjectTypeTrigger:
+		return fmt.Sprintf("DROP %s %s;", strings.ToUpper(string(objectType)), d.quote(name)), nil
+	case unifiedmodel.ObjectTypeSequence:
+		if d.mariadb {
+			return fmt.Sprintf("DROP SEQUENCE %s;", d.quote(name)), nil
+		}
+		// The MySQL generator simulates sequences with tables
+		return fmt.Sprintf("DROP TABLE %s;", d.quote(name+"_seq")), nil
+	case unifiedmodel.ObjectTypeExtension:
+		// Extensions are plugins
+		return fmt.Sprintf("UNINSTALL PLUGIN %s;", name), nil
+	}
+	return "", fmt.Errorf("unsupported object type: %s", objectType)
+}
+
+// indexColumns returns the key of an index, its expression or its quoted columns or fields
+func indexColumns(index unifiedmodel.Index, quote func(string) string) (string, error) {
+	if index.Name == "" {
+		return "", fmt.Errorf("index name cannot be empty")
+	}
+	if index.Expression != "" {
+		return index.Expression, nil
+	}
+	names := index.Columns
+	if len(names) == 0 {
+		names = index.Fields
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("index must have columns, fields, or expression")
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote(name)
+	}
+	return strings.Join(quoted, ", "), nil
+}
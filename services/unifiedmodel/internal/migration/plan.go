@@ -0,0 +1,752 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/comparison"
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/generators"
+)
+
+// Phase orders the steps of a migration plan: objects are dropped before the objects they
+// depend on, and created after them. Renames come first, so that the other steps use the
+// current names.
+type Phase int
+
+const (
+	PhaseRenameTables Phase = iota + 1
+	PhaseRenameColumns
+	PhaseDropTriggers
+	PhaseDropViews
+	PhaseDropForeignKeys
+	PhaseDropConstraints
+	PhaseDropIndexes
+	PhaseDropRoutines
+	PhaseCreateSchemas
+	PhaseCreateTypes
+	PhaseCreateTables
+	PhaseAlterTables
+	PhaseDropColumns
+	PhaseDropTables
+	PhaseDropTypes
+	PhaseDropSchemas
+	PhaseCreateRoutines
+	PhaseCreateIndexes
+	PhaseAddConstraints
+	PhaseAddForeignKeys
+	PhaseCreateViews
+	PhaseCreateTriggers
+)
+
+var phaseNames = map[Phase]string{
+	PhaseRenameTables:    "rename_tables",
+	PhaseRenameColumns:   "rename_columns",
+	PhaseDropTriggers:    "drop_triggers",
+	PhaseDropViews:       "drop_views",
+	PhaseDropForeignKeys: "drop_foreign_keys",
+	PhaseDropConstraints: "drop_constraints",
+	PhaseDropIndexes:     "drop_indexes",
+	PhaseDropRoutines:    "drop_routines",
+	PhaseCreateSchemas:   "create_schemas",
+	PhaseCreateTypes:     "create_types",
+	PhaseCreateTables:    "create_tables",
+	PhaseAlterTables:     "alter_tables",
+	PhaseDropColumns:     "drop_columns",
+	PhaseDropTables:      "drop_tables",
+	PhaseDropTypes:       "drop_types",
+	PhaseDropSchemas:     "drop_schemas",
+	PhaseCreateRoutines:  "create_routines",
+	PhaseCreateIndexes:   "create_indexes",
+	PhaseAddConstraints:  "add_constraints",
+	PhaseAddForeignKeys:  "add_foreign_keys",
+	PhaseCreateViews:     "create_views",
+	PhaseCreateTriggers:  "create_triggers",
+}
+
+func (p Phase) String() string {
+	if name, ok := phaseNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("phase_%d", int(p))
+}
+
+// Phases creating and dropping the objects of the model that are not table-scoped
+var (
+	createPhases = map[unifiedmodel.ObjectType]Phase{
+		unifiedmodel.ObjectTypeSchema:           PhaseCreateSchemas,
+		unifiedmodel.ObjectTypeExtension:        PhaseCreateSchemas,
+		unifiedmodel.ObjectTypeType:             PhaseCreateTypes,
+		unifiedmodel.ObjectTypeSequence:         PhaseCreateTypes,
+		unifiedmodel.ObjectTypeFunction:         PhaseCreateRoutines,
+		unifiedmodel.ObjectTypeProcedure:        PhaseCreateRoutines,
+		unifiedmodel.ObjectTypeView:             PhaseCreateViews,
+		unifiedmodel.ObjectTypeMaterializedView: PhaseCreateViews,
+		unifiedmodel.ObjectTypeTrigger:          PhaseCreateTriggers,
+	}
+	dropPhases = map[unifiedmodel.ObjectType]Phase{
+		unifiedmodel.ObjectTypeSchema:           PhaseDropSchemas,
+		unifiedmodel.ObjectTypeExtension:        PhaseDropSchemas,
+		unifiedmodel.ObjectTypeType:             PhaseDropTypes,
+		unifiedmodel.ObjectTypeSequence:         PhaseDropTypes,
+		unifiedmodel.ObjectTypeFunction:         PhaseDropRoutines,
+		unifiedmodel.ObjectTypeProcedure:        PhaseDropRoutines,
+		unifiedmodel.ObjectTypeView:             PhaseDropViews,
+		unifiedmodel.ObjectTypeMaterializedView: PhaseDropViews,
+		unifiedmodel.ObjectTypeTrigger:          PhaseDropTriggers,
+	}
+)
+
+// Step is a statement of a migration plan
+type Step struct {
+	Phase       Phase
+	ChangeType  unifiedmodel.ChangeType // Change of the comparison the step applies
+	ObjectType  unifiedmodel.ObjectType
+	ObjectName  string
+	ParentName  string // Table of a column, index or constraint
+	Statement   string
+	Destructive bool // The step drops data, or may not keep it
+	Description string
+}
+
+// Plan is an ordered migration script from a schema to another, for a database type
+type Plan struct {
+	DatabaseType string
+	Steps        []Step
+	Warnings     []string // Changes that the plan does not apply, or that need attention
+}
+
+// Destructive tells whether any step of the plan drops data, or may not keep it
+func (p *Plan) Destructive() bool {
+	for _, step := range p.Steps {
+		if step.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// Statements returns the statements of the plan in order
+func (p *Plan) Statements() []string {
+	statements := make([]string, 0, len(p.Steps))
+	for _, step := range p.Steps {
+		statements = append(statements, step.Statement)
+	}
+	return statements
+}
+
+// Script returns the plan as a script, each statement preceded by a comment describing it
+func (p *Plan) Script() string {
+	var sb strings.Builder
+	for i, step := range p.Steps {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("-- %d. %s", i+1, step.Description))
+		if step.Destructive {
+			sb.WriteString(" (destructive)")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(step.Statement)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Planner converts the changes between two versions of a schema into a migration plan
+type Planner struct {
+	factory *generators.GeneratorFactory
+
+	// ApplyRenames plans the tables and columns the comparison found renamed as renames. Otherwise
+	// they are dropped and created again under their new name, which loses their data.
+	ApplyRenames bool
+}
+
+// NewPlanner creates a new migration planner
+func NewPlanner() *Planner {
+	return &Planner{factory: generators.NewGeneratorFactory()}
+}
+
+// Plan converts the changes from the previous to the current model into the statements that
+// migrate a database of the given type. Changes that cannot be applied with the statements of
+// the database are left out of the plan with a warning.
+func (p *Planner) Plan(databaseType string, previous, current *unifiedmodel.UnifiedModel, result *comparison.UnifiedCompareResult) (*Plan, error) {
+	generator, ok := p.factory.GetGenerator(databaseType)
+	if !ok {
+		return nil, fmt.Errorf("no generator found for database type: %s", databaseType)
+	}
+	d, err := dialectFor(databaseType, generator)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == nil {
+		previous = &unifiedmodel.UnifiedModel{}
+	}
+	if current == nil {
+		current = &unifiedmodel.UnifiedModel{}
+	}
+
+	b := &planBuilder{
+		dialect:        d,
+		generator:      generator,
+		previous:       previous,
+		current:        current,
+		plan:           &Plan{DatabaseType: databaseType, Steps: make([]Step, 0), Warnings: make([]string, 0)},
+		done:           make(map[string]bool),
+		renamedTables:  make(map[string]string),
+		tableNames:     make(map[string]string),
+		renamedColumns: make(map[string]string),
+	}
+	records := result.Records
+	if p.ApplyRenames {
+		b.trackRenames(records)
+	} else {
+		records = b.expandRenames(records)
+	}
+	for _, change := range records {
+		b.apply(change)
+	}
+	b.warnRenames(result.Records)
+
+	sort.SliceStable(b.plan.Steps, func(i, j int) bool {
+		return b.plan.Steps[i].Phase < b.plan.Steps[j].Phase
+	})
+	return b.plan, nil
+}
+
+// planBuilder adds the steps applying the changes of a comparison to a plan
+type planBuilder struct {
+	dialect           dialect
+	generator         generators.StatementGenerator
+	previous, current *unifiedmodel.UnifiedModel
+	plan              *Plan
+	done              map[string]bool // Objects whose modifications are already planned
+
+	// Renames applied by the plan
+	renamedTables  map[string]string // Previous name by current name
+	tableNames     map[string]string // Current name by previous name
+	renamedColumns map[string]string // Previous name by current table and name
+}
+
+func (b *planBuilder) apply(change comparison.ChangeRecord) {
+	switch change.ObjectType {
+	case unifiedmodel.ObjectTypeTable:
+		b.table(change)
+	case unifiedmodel.ObjectTypeColumn:
+		b.column(change)
+	case unifiedmodel.ObjectTypeIndex:
+		b.index(change)
+	case unifiedmodel.ObjectTypeConstraint:
+		b.constraint(change)
+	default:
+		if _, ok := createPhases[change.ObjectType]; ok && change.ParentName == "" {
+			b.object(change)
+		} else {
+			b.unsupported(change)
+		}
+	}
+}
+
+func (b *planBuilder) add(step Step) {
+	b.plan.Steps = append(b.plan.Steps, step)
+}
+
+func (b *planBuilder) warn(format string, args ...interface{}) {
+	b.plan.Warnings = append(b.plan.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (b *planBuilder) unsupported(change comparison.ChangeRecord) {
+	b.warn("%s: not supported by the migration plan, apply it manually", change.Description)
+}
+
+// once tells whether the modifications of an object are planned for the first time
+func (b *planBuilder) once(change comparison.ChangeRecord) bool {
+	key := fmt.Sprintf("%s:%s.%s", change.ObjectType, change.ParentName, change.ObjectName)
+	if b.done[key] {
+		return false
+	}
+	b.done[key] = true
+	return true
+}
+
+func (b *planBuilder) table(change comparison.ChangeRecord) {
+	if change.ParentName != "" {
+		// Sub-tables, such as partition children
+		b.unsupported(change)
+		return
+	}
+
+	switch change.ChangeType {
+	case unifiedmodel.ChangeTypeAdded:
+		table := b.current.Tables[change.ObjectName]
+
+		// Foreign keys are added once all the tables exist
+		created := table
+		created.Constraints = make(map[string]unifiedmodel.Constraint)
+		var foreignKeys []unifiedmodel.Constraint
+		for _, name := range sortedKeys(table.Constraints) {
+			if c := table.Constraints[name]; c.Type == unifiedmodel.ConstraintTypeForeignKey {
+				foreignKeys = append(foreignKeys, c)
+			} else {
+				created.Constraints[name] = c
+			}
+		}
+
+		statement, err := b.generator.GenerateCreateTableSQL(created)
+		if err != nil {
+			b.warn("Cannot create table %s: %v", change.ObjectName, err)
+			return
+		}
+		b.add(Step{Phase: PhaseCreateTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: statement, Description: fmt.Sprintf("Create table %s", change.ObjectName)})
+
+		for _, name := range sortedKeys(table.Indexes) {
+			b.createIndex(change, change.ObjectName, table.Indexes[name])
+		}
+		for _, c := range foreignKeys {
+			b.addConstraint(change, change.ObjectName, c)
+		}
+
+	case unifiedmodel.ChangeTypeRenamed:
+		b.add(Step{Phase: PhaseRenameTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: b.dialect.renameTable(change.OldValue, change.ObjectName),
+			Description: fmt.Sprintf("Rename table %s to %s (confidence %.2f)", change.OldValue, change.ObjectName, change.Confidence)})
+
+	case unifiedmodel.ChangeTypeRemoved:
+		b.add(Step{Phase: PhaseDropTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: b.dialect.dropTable(change.ObjectName), Destructive: true,
+			Description: fmt.Sprintf("Drop table %s", change.ObjectName)})
+
+	default:
+		if change.Field != "comment" {
+			b.unsupported(change)
+			return
+		}
+		comment := b.current.Tables[change.ObjectName].Comment
+		b.add(Step{Phase: PhaseAlterTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: b.dialect.commentTable(change.ObjectName, comment),
+			Description: fmt.Sprintf("Set the comment of table %s", change.ObjectName)})
+	}
+}
+
+// parentTable returns the table of a table-scoped change, in the current model for added and
+// modified objects and in the previous model for removed objects
+func (b *planBuilder) parentTable(change comparison.ChangeRecord) (unifiedmodel.Table, bool) {
+	if change.ChangeType == unifiedmodel.ChangeTypeRemoved {
+		return b.previousTable(change.ParentName)
+	}
+	table, ok := b.current.Tables[change.ParentName]
+	return table, ok
+}
+
+// previousTable returns the previous version of a table of the current model, under its
+// current name as the renames run first
+func (b *planBuilder) previousTable(name string) (unifiedmodel.Table, bool) {
+	previousName := name
+	if renamed, ok := b.renamedTables[name]; ok {
+		previousName = renamed
+	}
+	table, ok := b.previous.Tables[previousName]
+	table.Name = name
+	return table, ok
+}
+
+// previousColumn returns the previous version of a column of a table of the current model
+func (b *planBuilder) previousColumn(table, name string) unifiedmodel.Column {
+	previousTable, _ := b.previousTable(table)
+	if renamed, ok := b.renamedColumns[table+"."+name]; ok {
+		name = renamed
+	}
+	return previousTable.Columns[name]
+}
+
+// currentTableName returns the name a table of the previous model has in the current model
+func (b *planBuilder) currentTableName(name string) string {
+	if renamed, ok := b.tableNames[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+func (b *planBuilder) column(change comparison.ChangeRecord) {
+	table, ok := b.parentTable(change)
+	if !ok {
+		b.unsupported(change)
+		return
+	}
+
+	switch change.ChangeType {
+	case unifiedmodel.ChangeTypeAdded:
+		column := table.Columns[change.ObjectName]
+		statement, err := b.dialect.addColumn(table.Name, column)
+		if err != nil {
+			b.warn("Cannot add column %s.%s: %v", table.Name, change.ObjectName, err)
+			return
+		}
+		b.add(Step{Phase: PhaseAlterTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, ParentName: table.Name, Statement: statement,
+			Description: fmt.Sprintf("Add column %s.%s", table.Name, change.ObjectName)})
+		if !column.Nullable && column.Default == "" && column.GeneratedExpression == "" && !column.AutoIncrement {
+			b.warn("Column %s.%s is added as NOT NULL without a default, which fails if table %s has rows", table.Name, change.ObjectName, table.Name)
+		}
+
+	case unifiedmodel.ChangeTypeRenamed:
+		b.add(Step{Phase: PhaseRenameColumns, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, ParentName: table.Name, Statement: b.dialect.renameColumn(table.Name, change.OldValue, change.ObjectName),
+			Description: fmt.Sprintf("Rename column %s.%s to %s (confidence %.2f)", table.Name, change.OldValue, change.ObjectName, change.Confidence)})
+
+	case unifiedmodel.ChangeTypeRemoved:
+		b.add(Step{Phase: PhaseDropColumns, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, ParentName: table.Name, Statement: b.dialect.dropColumn(table.Name, change.ObjectName),
+			Destructive: true, Description: fmt.Sprintf("Drop column %s.%s", table.Name, change.ObjectName)})
+
+	default:
+		if !b.dialect.alters(change.Field) {
+			b.unsupported(change)
+			return
+		}
+		if !b.once(change) {
+			return
+		}
+		prev := b.previousColumn(change.ParentName, change.ObjectName)
+		curr := table.Columns[change.ObjectName]
+		statements, err := b.dialect.alterColumn(table.Name, prev, curr)
+		if err != nil {
+			b.warn("Cannot alter column %s.%s: %v", table.Name, change.ObjectName, err)
+			return
+		}
+		// Converting values to another type may fail, or lose precision
+		destructive := prev.DataType != curr.DataType
+		for _, statement := range statements {
+			b.add(Step{Phase: PhaseAlterTables, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+				ObjectName: change.ObjectName, ParentName: table.Name, Statement: statement, Destructive: destructive,
+				Description: fmt.Sprintf("Alter column %s.%s", table.Name, change.ObjectName)})
+		}
+	}
+}
+
+// scopedIndex returns an index and its table, for indexes of tables and indexes of the model
+// naming their table in the table option
+func (b *planBuilder) scopedIndex(change comparison.ChangeRecord, model *unifiedmodel.UnifiedModel) (unifiedmodel.Index, string, bool) {
+	if change.ParentName != "" {
+		table, ok := b.scopeTable(change.ParentName, model)
+		if !ok {
+			return unifiedmodel.Index{}, "", false
+		}
+		index, ok := table.Indexes[change.ObjectName]
+		return index, table.Name, ok
+	}
+	index, ok := model.Indexes[change.ObjectName]
+	table, _ := index.Options["table"].(string)
+	if model == b.previous {
+		table = b.currentTableName(table)
+	}
+	return index, table, ok && table != ""
+}
+
+// scopeTable returns a table of the current model, or its previous version
+func (b *planBuilder) scopeTable(name string, model *unifiedmodel.UnifiedModel) (unifiedmodel.Table, bool) {
+	if model == b.previous {
+		return b.previousTable(name)
+	}
+	table, ok := model.Tables[name]
+	return table, ok
+}
+
+func (b *planBuilder) index(change comparison.ChangeRecord) {
+	if change.ChangeType != unifiedmodel.ChangeTypeAdded {
+		if change.ChangeType == unifiedmodel.ChangeTypeModified && !b.once(change) {
+			return
+		}
+		index, table, ok := b.scopedIndex(change, b.previous)
+		if !ok {
+			b.unsupported(change)
+			return
+		}
+		b.add(Step{Phase: PhaseDropIndexes, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: index.Name, ParentName: table, Statement: b.dialect.dropIndex(table, index.Name),
+			Description: fmt.Sprintf("Drop index %s on %s", index.Name, table)})
+	}
+	if change.ChangeType != unifiedmodel.ChangeTypeRemoved {
+		index, table, ok := b.scopedIndex(change, b.current)
+		if !ok {
+			b.unsupported(change)
+			return
+		}
+		b.createIndex(change, table, index)
+	}
+}
+
+func (b *planBuilder) createIndex(change comparison.ChangeRecord, table string, index unifiedmodel.Index) {
+	statement, err := b.dialect.createIndex(table, index)
+	if err != nil {
+		b.warn("Cannot create index %s on %s: %v", index.Name, table, err)
+		return
+	}
+	b.add(Step{Phase: PhaseCreateIndexes, ChangeType: change.ChangeType, ObjectType: unifiedmodel.ObjectTypeIndex,
+		ObjectName: index.Name, ParentName: table, Statement: statement,
+		Description: fmt.Sprintf("Create index %s on %s", index.Name, table)})
+}
+
+// scopedConstraint returns a constraint and its table, for constraints of tables and constraints
+// of the model naming their table in the table option
+func (b *planBuilder) scopedConstraint(change comparison.ChangeRecord, model *unifiedmodel.UnifiedModel) (unifiedmodel.Constraint, string, bool) {
+	if change.ParentName != "" {
+		table, ok := b.scopeTable(change.ParentName, model)
+		if !ok {
+			return unifiedmodel.Constraint{}, "", false
+		}
+		constraint, ok := table.Constraints[change.ObjectName]
+		return constraint, table.Name, ok
+	}
+	constraint, ok := model.Constraints[change.ObjectName]
+	table, _ := constraint.Options["table"].(string)
+	if model == b.previous {
+		table = b.currentTableName(table)
+	}
+	return constraint, table, ok && table != ""
+}
+
+func (b *planBuilder) constraint(change comparison.ChangeRecord) {
+	if change.ChangeType != unifiedmodel.ChangeTypeAdded {
+		if change.ChangeType == unifiedmodel.ChangeTypeModified && !b.once(change) {
+			return
+		}
+		constraint, table, ok := b.scopedConstraint(change, b.previous)
+		if !ok {
+			b.unsupported(change)
+			return
+		}
+		statement, err := b.dialect.dropConstraint(table, constraint)
+		if err != nil {
+			b.warn("Cannot drop constraint %s on %s: %v", constraint.Name, table, err)
+			return
+		}
+		phase := PhaseDropConstraints
+		if constraint.Type == unifiedmodel.ConstraintTypeForeignKey {
+			phase = PhaseDropForeignKeys
+		}
+		b.add(Step{Phase: phase, ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: constraint.Name, ParentName: table, Statement: statement,
+			Description: fmt.Sprintf("Drop constraint %s on %s", constraint.Name, table)})
+	}
+	if change.ChangeType != unifiedmodel.ChangeTypeRemoved {
+		constraint, table, ok := b.scopedConstraint(change, b.current)
+		if !ok {
+			b.unsupported(change)
+			return
+		}
+		b.addConstraint(change, table, constraint)
+	}
+}
+
+func (b *planBuilder) addConstraint(change comparison.ChangeRecord, table string, constraint unifiedmodel.Constraint) {
+	statement, err := b.dialect.addConstraint(table, constraint)
+	if err != nil {
+		b.warn("Cannot add constraint %s on %s: %v", constraint.Name, table, err)
+		return
+	}
+	phase := PhaseAddConstraints
+	if constraint.Type == unifiedmodel.ConstraintTypeForeignKey {
+		phase = PhaseAddForeignKeys
+	}
+	b.add(Step{Phase: phase, ChangeType: change.ChangeType, ObjectType: unifiedmodel.ObjectTypeConstraint,
+		ObjectName: constraint.Name, ParentName: table, Statement: statement,
+		Description: fmt.Sprintf("Add constraint %s on %s", constraint.Name, table)})
+}
+
+// object plans the changes of the objects of the model that are created whole, such as views,
+// routines and triggers. Modified objects are dropped and created again, unless the statement
+// creating them replaces them.
+func (b *planBuilder) object(change comparison.ChangeRecord) {
+	label := strings.ReplaceAll(string(change.ObjectType), "_", " ")
+
+	var create string
+	if change.ChangeType != unifiedmodel.ChangeTypeRemoved {
+		switch change.ObjectType {
+		case unifiedmodel.ObjectTypeSchema, unifiedmodel.ObjectTypeExtension, unifiedmodel.ObjectTypeType:
+			if change.ChangeType == unifiedmodel.ChangeTypeModified {
+				// Dropping them would drop, or fail on, the objects using them
+				b.unsupported(change)
+				return
+			}
+		}
+		if change.ChangeType == unifiedmodel.ChangeTypeModified && !b.once(change) {
+			return
+		}
+		statement, err := b.create(change.ObjectType, change.ObjectName)
+		if err != nil {
+			b.warn("Cannot create %s %s: %v", label, change.ObjectName, err)
+			return
+		}
+		create = statement
+	}
+
+	replaces := strings.HasPrefix(strings.ToUpper(create), "CREATE OR REPLACE")
+	if change.ChangeType == unifiedmodel.ChangeTypeRemoved || (change.ChangeType == unifiedmodel.ChangeTypeModified && !replaces) {
+		table := ""
+		if change.ObjectType == unifiedmodel.ObjectTypeTrigger {
+			table = b.currentTableName(b.previous.Triggers[change.ObjectName].Table)
+		}
+		statement, err := b.dialect.drop(change.ObjectType, change.ObjectName, table)
+		if err != nil {
+			b.warn("Cannot drop %s %s: %v", label, change.ObjectName, err)
+			return
+		}
+		b.add(Step{Phase: dropPhases[change.ObjectType], ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: statement,
+			// Sequences start over when created again
+			Destructive: change.ObjectType == unifiedmodel.ObjectTypeSequence || change.ObjectType == unifiedmodel.ObjectTypeSchema,
+			Description: fmt.Sprintf("Drop %s %s", label, change.ObjectName)})
+	}
+
+	if create != "" {
+		b.add(Step{Phase: createPhases[change.ObjectType], ChangeType: change.ChangeType, ObjectType: change.ObjectType,
+			ObjectName: change.ObjectName, Statement: create, Description: fmt.Sprintf("Create %s %s", label, change.ObjectName)})
+	}
+}
+
+// create generates the statement creating an object of the current model
+func (b *planBuilder) create(objectType unifiedmodel.ObjectType, name string) (string, error) {
+	m := b.current
+	switch objectType {
+	case unifiedmodel.ObjectTypeSchema:
+		return b.generator.GenerateCreateSchemaSQL(m.Schemas[name])
+	case unifiedmodel.ObjectTypeExtension:
+		return b.generator.GenerateCreateExtensionSQL(m.Extensions[name])
+	case unifiedmodel.ObjectTypeType:
+		return b.generator.GenerateCreateTypeSQL(m.Types[name])
+	case unifiedmodel.ObjectTypeSequence:
+		return b.generator.GenerateCreateSequenceSQL(m.Sequences[name])
+	case unifiedmodel.ObjectTypeFunction:
+		return b.generator.GenerateCreateFunctionSQL(m.Functions[name])
+	case unifiedmodel.ObjectTypeProcedure:
+		return b.generator.GenerateCreateProcedureSQL(m.Procedures[name])
+	case unifiedmodel.ObjectTypeView:
+		return b.generator.GenerateCreateViewSQL(m.Views[name])
+	case unifiedmodel.ObjectTypeMaterializedView:
+		return b.generator.GenerateCreateMaterializedViewSQL(m.MaterializedViews[name])
+	case unifiedmodel.ObjectTypeTrigger:
+		return b.generator.GenerateCreateTriggerSQL(m.Triggers[name])
+	}
+	return "", fmt.Errorf("unsupported object type: %s", objectType)
+}
+
+// trackRenames keeps the tables and columns the plan renames, to find the previous versions of
+// the objects under their current names
+func (b *planBuilder) trackRenames(records []comparison.ChangeRecord) {
+	for _, change := range records {
+		if change.ChangeType != unifiedmodel.ChangeTypeRenamed {
+			continue
+		}
+		switch {
+		case change.ObjectType == unifiedmodel.ObjectTypeTable && change.ParentName == "":
+			b.renamedTables[change.ObjectName] = change.OldValue
+			b.tableNames[change.OldValue] = change.ObjectName
+		case change.ObjectType == unifiedmodel.ObjectTypeColumn:
+			b.renamedColumns[change.ParentName+"."+change.ObjectName] = change.OldValue
+		}
+	}
+}
+
+// expandRenames replaces the renamed tables and columns of a comparison with the removal of the
+// object and the addition of the new one, leaving out the changes within the new one as it is
+// created whole, and warns that the data of the object is lost
+func (b *planBuilder) expandRenames(records []comparison.ChangeRecord) []comparison.ChangeRecord {
+	var renames []comparison.ChangeRecord
+	for _, change := range records {
+		if change.ChangeType == unifiedmodel.ChangeTypeRenamed {
+			renames = append(renames, change)
+		}
+	}
+	if len(renames) == 0 {
+		return records
+	}
+
+	// within tells whether a change is part of an object that is created again
+	within := func(change comparison.ChangeRecord) bool {
+		for _, r := range renames {
+			if r == change {
+				continue
+			}
+			switch r.ObjectType {
+			case unifiedmodel.ObjectTypeTable:
+				scope := r.ObjectName
+				if r.ParentName != "" {
+					scope = r.ParentName + "." + r.ObjectName
+				}
+				if change.ParentName == scope || strings.HasPrefix(change.ParentName, scope+".") {
+					return true
+				}
+			case unifiedmodel.ObjectTypeColumn:
+				if change.ObjectType == unifiedmodel.ObjectTypeColumn && change.ParentName == r.ParentName && change.ObjectName == r.ObjectName {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	expanded := make([]comparison.ChangeRecord, 0, len(records)+len(renames))
+	for _, change := range records {
+		if within(change) {
+			continue
+		}
+		if change.ChangeType != unifiedmodel.ChangeTypeRenamed {
+			expanded = append(expanded, change)
+			continue
+		}
+
+		removed, added := change, change
+		removed.ChangeType, removed.ObjectName = unifiedmodel.ChangeTypeRemoved, change.OldValue
+		added.ChangeType = unifiedmodel.ChangeTypeAdded
+		for _, c := range []*comparison.ChangeRecord{&removed, &added} {
+			c.Field, c.OldValue, c.NewValue, c.Confidence = "", "", "", 0
+		}
+		expanded = append(expanded, removed, added)
+
+		label := strings.ReplaceAll(string(change.ObjectType), "_", " ")
+		qualified := func(name string) string {
+			if change.ParentName == "" {
+				return name
+			}
+			return change.ParentName + "." + name
+		}
+		b.warn("%s %s looks renamed to %s (confidence %.2f): it is dropped and created again, which loses its data; apply renames to rename it instead",
+			strings.ToUpper(label[:1])+label[1:], qualified(change.OldValue), qualified(change.ObjectName), change.Confidence)
+	}
+	return expanded
+}
+
+// warnRenames warns about tables that lose columns and gain others that the comparison did not
+// find renamed, as the plan drops their data
+func (b *planBuilder) warnRenames(records []comparison.ChangeRecord) {
+	removed, added := map[string]bool{}, map[string]bool{}
+	for _, change := range records {
+		if change.ObjectType != unifiedmodel.ObjectTypeColumn {
+			continue
+		}
+		switch change.ChangeType {
+		case unifiedmodel.ChangeTypeRemoved:
+			removed[change.ParentName] = true
+		case unifiedmodel.ChangeTypeAdded:
+			added[change.ParentName] = true
+		}
+	}
+	for _, table := range sortedKeys(removed) {
+		if added[table] {
+			b.warn("Table %s drops columns and adds others: if columns were renamed, rename them manually instead, as dropping them loses their data", table)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
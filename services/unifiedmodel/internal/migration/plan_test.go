@@ -0,0 +1,279 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+	"github.com/redbco/redb-open/services/unifiedmodel/internal/comparison"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func plan(t *testing.T, databaseType string, prev, curr *unifiedmodel.UnifiedModel) *Plan {
+	t.Helper()
+	result, err := comparison.NewUnifiedSchemaComparator().CompareUnifiedModels(prev, curr)
+	require.NoError(t, err)
+	p, err := NewPlanner().Plan(databaseType, prev, curr, result)
+	require.NoError(t, err)
+	return p
+}
+
+func TestPlan(t *testing.T) {
+	prev := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":       {Name: "id", DataType: "integer"},
+					"email":    {Name: "email", DataType: "varchar", Nullable: true},
+					"age":      {Name: "age", DataType: "integer", Nullable: true},
+					"nickname": {Name: "nickname", DataType: "text", Nullable: true},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_email": {Name: "idx_email", Columns: []string{"email"}},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"users_pkey": {Name: "users_pkey", Type: unifiedmodel.ConstraintTypePrimaryKey, Columns: []string{"id"}},
+				},
+			},
+			"legacy": {
+				Name:    "legacy",
+				Columns: map[string]unifiedmodel.Column{"id": {Name: "id", DataType: "integer"}},
+			},
+		},
+		Views: map[string]unifiedmodel.View{
+			"adults": {Name: "adults", Definition: "SELECT * FROM users WHERE age >= 18"},
+		},
+	}
+	curr := &unifiedmodel.UnifiedModel{
+		DatabaseType: dbcapabilities.PostgreSQL,
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":    {Name: "id", DataType: "integer"},
+					"email": {Name: "email", DataType: "varchar"},
+					"age":   {Name: "age", DataType: "bigint", Nullable: true},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_email": {Name: "idx_email", Columns: []string{"email"}, Unique: true},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"users_pkey": {Name: "users_pkey", Type: unifiedmodel.ConstraintTypePrimaryKey, Columns: []string{"id"}},
+				},
+			},
+			"orders": {
+				Name: "orders",
+				Columns: map[string]unifiedmodel.Column{
+					"user_id": {Name: "user_id", DataType: "integer"},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_orders_user": {Name: "idx_orders_user", Columns: []string{"user_id"}},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"orders_user_fk": {
+						Name:      "orders_user_fk",
+						Type:      unifiedmodel.ConstraintTypeForeignKey,
+						Columns:   []string{"user_id"},
+						Reference: unifiedmodel.Reference{Table: "users", Columns: []string{"id"}},
+					},
+				},
+			},
+		},
+		Views: map[string]unifiedmodel.View{
+			"adults": {Name: "adults", Definition: "SELECT id, email FROM users WHERE age >= 18"},
+		},
+	}
+
+	p := plan(t, "postgres", prev, curr)
+
+	assert.Equal(t, []string{
+		"DROP VIEW adults;",
+		"DROP INDEX idx_email;",
+		"CREATE TABLE orders (user_id INTEGER NOT NULL);",
+		"ALTER TABLE users ALTER COLUMN age TYPE BIGINT USING age::BIGINT;",
+		"ALTER TABLE users ALTER COLUMN email SET NOT NULL;",
+		"ALTER TABLE users DROP COLUMN nickname;",
+		"DROP TABLE legacy;",
+		"CREATE INDEX idx_orders_user ON orders (user_id);",
+		"CREATE UNIQUE INDEX idx_email ON users (email);",
+		"ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id);",
+		"CREATE VIEW adults AS\nSELECT id, email FROM users WHERE age >= 18;",
+	}, p.Statements())
+
+	assert.True(t, p.Destructive())
+	for _, step := range p.Steps {
+		switch step.Statement {
+		case "DROP TABLE legacy;", "ALTER TABLE users DROP COLUMN nickname;",
+			"ALTER TABLE users ALTER COLUMN age TYPE BIGINT USING age::BIGINT;":
+			assert.True(t, step.Destructive, step.Statement)
+		default:
+			assert.False(t, step.Destructive, step.Statement)
+		}
+	}
+	assert.Empty(t, p.Warnings)
+	assert.Contains(t, p.Script(), "-- 7. Drop table legacy (destructive)\nDROP TABLE legacy;\n")
+}
+
+func TestPlanMySQL(t *testing.T) {
+	prev := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":   {Name: "id", DataType: "int"},
+					"name": {Name: "name", DataType: "varchar", Nullable: true},
+				},
+				Constraints: map[string]unifiedmodel.Constraint{
+					"users_name_check": {Name: "users_name_check", Type: unifiedmodel.ConstraintTypeCheck, Expression: "name <> ''"},
+				},
+			},
+		},
+		Triggers: map[string]unifiedmodel.Trigger{
+			"users_audit": {Name: "users_audit", Table: "users", Timing: "after", Events: []string{"insert"}, Procedure: "audit"},
+		},
+	}
+	curr := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":   {Name: "id", DataType: "int"},
+					"name": {Name: "name", DataType: "text", Nullable: true},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_name": {Name: "idx_name", Columns: []string{"name"}, Type: unifiedmodel.IndexTypeFullText},
+				},
+			},
+		},
+	}
+
+	t.Run("mysql", func(t *testing.T) {
+		p := plan(t, "mysql", prev, curr)
+		assert.Equal(t, []string{
+			"DROP TRIGGER `users_audit`;",
+			"ALTER TABLE `users` DROP CHECK `users_name_check`;",
+			"ALTER TABLE `users` MODIFY COLUMN `name` TEXT;",
+			"CREATE FULLTEXT INDEX `idx_name` ON `users` (`name`);",
+		}, p.Statements())
+	})
+
+	t.Run("mariadb", func(t *testing.T) {
+		p := plan(t, "mariadb", prev, curr)
+		assert.Equal(t, "ALTER TABLE `users` DROP CONSTRAINT `users_name_check`;", p.Steps[1].Statement)
+	})
+}
+
+func TestPlanWarnings(t *testing.T) {
+	prev := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name: "users",
+				Columns: map[string]unifiedmodel.Column{
+					"id":   {Name: "id", DataType: "integer"},
+					"name": {Name: "name", DataType: "text", Nullable: true},
+				},
+			},
+		},
+		Indexes: map[string]unifiedmodel.Index{
+			"idx_orphan": {Name: "idx_orphan", Columns: []string{"id"}},
+		},
+	}
+	curr := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"users": {
+				Name:  "users",
+				Owner: "app",
+				Columns: map[string]unifiedmodel.Column{
+					"id":        {Name: "id", DataType: "integer"},
+					"full_name": {Name: "full_name", DataType: "text"},
+				},
+			},
+		},
+	}
+
+	p := plan(t, "postgres", prev, curr)
+
+	assert.Equal(t, []string{
+		"ALTER TABLE users ADD COLUMN full_name TEXT NOT NULL;",
+		"ALTER TABLE users DROP COLUMN name;",
+	}, p.Statements())
+	assert.Equal(t, []string{
+		"Table users owner changed:  -> app: not supported by the migration plan, apply it manually",
+		"Column users.full_name is added as NOT NULL without a default, which fails if table users has rows",
+		"Removed index: idx_orphan: not supported by the migration plan, apply it manually",
+		"Table users drops columns and adds others: if columns were renamed, rename them manually instead, as dropping them loses their data",
+	}, p.Warnings)
+}
+
+func TestPlanUnsupportedDatabase(t *testing.T) {
+	_, err := NewPlanner().Plan("mongodb", nil, nil, &comparison.UnifiedCompareResult{})
+	assert.Error(t, err)
+
+	_, err = NewPlanner().Plan("unknown", nil, nil, &comparison.UnifiedCompareResult{})
+	assert.Error(t, err)
+}
+
+func TestPlanRenames(t *testing.T) {
+	prev := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"clients": {
+				Name: "clients",
+				Columns: map[string]unifiedmodel.Column{
+					"id":        {Name: "id", DataType: "integer"},
+					"full_name": {Name: "full_name", DataType: "text", Nullable: true},
+					"created":   {Name: "created", DataType: "timestamp"},
+				},
+			},
+		},
+	}
+	curr := &unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"customers": {
+				Name: "customers",
+				Columns: map[string]unifiedmodel.Column{
+					"id":      {Name: "id", DataType: "integer"},
+					"name":    {Name: "name", DataType: "text", Nullable: true},
+					"created": {Name: "created", DataType: "timestamp"},
+				},
+				Indexes: map[string]unifiedmodel.Index{
+					"idx_name": {Name: "idx_name", Columns: []string{"name"}},
+				},
+			},
+		},
+	}
+	result, err := comparison.NewUnifiedSchemaComparator().CompareUnifiedModels(prev, curr)
+	require.NoError(t, err)
+
+	t.Run("applied", func(t *testing.T) {
+		planner := NewPlanner()
+		planner.ApplyRenames = true
+		p, err := planner.Plan("postgres", prev, curr, result)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"ALTER TABLE clients RENAME TO customers;",
+			"ALTER TABLE customers RENAME COLUMN full_name TO name;",
+			"CREATE INDEX idx_name ON customers (name);",
+		}, p.Statements())
+		assert.False(t, p.Destructive())
+		assert.Empty(t, p.Warnings)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		p, err := NewPlanner().Plan("postgres", prev, curr, result)
+		require.NoError(t, err)
+
+		statements := p.Statements()
+		require.Len(t, statements, 3)
+		assert.Contains(t, statements[0], "CREATE TABLE customers (")
+		assert.Equal(t, "DROP TABLE clients;", statements[1])
+		assert.Equal(t, "CREATE INDEX idx_name ON customers (name);", statements[2])
+		assert.True(t, p.Destructive())
+		assert.Equal(t, []string{
+			"Table clients looks renamed to customers (confidence 0.81): it is dropped and created again, which loses its data; apply renames to rename it instead",
+		}, p.Warnings)
+	})
+}
@@ -0,0 +1,288 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+// Default handling of owners, grants and comments. Grants are dropped by default because
+// the grantees of the source rarely exist on the target.
+const (
+	defaultOwnerHandling   = AttributeHandlingPreserve
+	defaultGrantHandling   = AttributeHandlingDrop
+	defaultCommentHandling = AttributeHandlingPreserve
+)
+
+// EffectiveOwnerHandling returns the owner handling, applying the default
+func (p TranslationPreferences) EffectiveOwnerHandling() AttributeHandling {
+	if p.OwnerHandling == "" {
+		return defaultOwnerHandling
+	}
+	return p.OwnerHandling
+}
+
+// EffectiveGrantHandling returns the grant handling, applying the default
+func (p TranslationPreferences) EffectiveGrantHandling() AttributeHandling {
+	if p.GrantHandling == "" {
+		return defaultGrantHandling
+	}
+	return p.GrantHandling
+}
+
+// EffectiveCommentHandling returns the comment handling, applying the default
+func (p TranslationPreferences) EffectiveCommentHandling() AttributeHandling {
+	if p.CommentHandling == "" {
+		return defaultCommentHandling
+	}
+	return p.CommentHandling
+}
+
+// validateAttributeHandling validates the owner, grant and comment handling preferences
+func validateAttributeHandling(p TranslationPreferences) []ValidationError {
+	var errors []ValidationError
+
+	check := func(field string, handling AttributeHandling, allowRemap bool) {
+		switch handling {
+		case "", AttributeHandlingPreserve, AttributeHandlingDrop:
+		case AttributeHandlingRemap:
+			if !allowRemap {
+				errors = append(errors, ValidationError{
+					Type:    ValidationErrorCritical,
+					Field:   field,
+					Message: fmt.Sprintf("%s does not support remap", field),
+				})
+			} else if len(p.OwnerMappings) == 0 {
+				errors = append(errors, ValidationError{
+					Type:       ValidationErrorCritical,
+					Field:      "owner_mappings",
+					Message:    fmt.Sprintf("%s remap requires owner mappings", field),
+					Suggestion: "Provide owner_mappings from source to target owners",
+				})
+			}
+		default:
+			errors = append(errors, ValidationError{
+				Type:       ValidationErrorCritical,
+				Field:      field,
+				Message:    fmt.Sprintf("Unsupported %s: %s", field, handling),
+				Suggestion: "Use preserve, remap or drop",
+			})
+		}
+	}
+
+	check("owner_handling", p.OwnerHandling, true)
+	check("grant_handling", p.GrantHandling, true)
+	check("comment_handling", p.CommentHandling, false)
+
+	return errors
+}
+
+// applyAttributePolicies applies the owner, grant and comment handling to the target schema.
+// Translators carry some of these attributes and ignore others; applying the handling after
+// translation makes the result independent of the translator used.
+func applyAttributePolicies(ctx *TranslationContext) {
+	if ctx.TargetSchema == nil || ctx.SourceSchema == nil {
+		return
+	}
+
+	applyOwnerHandling(ctx)
+	applyGrantHandling(ctx)
+	applyCommentHandling(ctx)
+}
+
+// applyOwnerHandling carries, remaps or drops the owners of the target objects
+func applyOwnerHandling(ctx *TranslationContext) {
+	source, target := ctx.SourceSchema, ctx.TargetSchema
+	handling := ctx.Preferences.EffectiveOwnerHandling()
+
+	if handling != AttributeHandlingDrop {
+		// Carry owners the translator did not carry
+		for name, table := range target.Tables {
+			if src, ok := source.Tables[name]; ok && table.Owner == "" && src.Owner != "" {
+				table.Owner = src.Owner
+				target.Tables[name] = table
+			}
+		}
+		for name, collection := range target.Collections {
+			if src, ok := source.Collections[name]; ok && collection.Owner == "" && src.Owner != "" {
+				collection.Owner = src.Owner
+				target.Collections[name] = collection
+			}
+		}
+	}
+
+	if handling == AttributeHandlingPreserve {
+		return
+	}
+
+	unmapped := make(map[string]bool)
+	owner := func(current string) string {
+		if current == "" || handling == AttributeHandlingDrop {
+			return ""
+		}
+		mapped, ok := ctx.Preferences.OwnerMappings[current]
+		if !ok {
+			unmapped[current] = true
+			return current
+		}
+		return mapped
+	}
+
+	for name, catalog := range target.Catalogs {
+		catalog.Owner = owner(catalog.Owner)
+		target.Catalogs[name] = catalog
+	}
+	for name, database := range target.Databases {
+		database.Owner = owner(database.Owner)
+		target.Databases[name] = database
+	}
+	for name, schema := range target.Schemas {
+		schema.Owner = owner(schema.Owner)
+		target.Schemas[name] = schema
+	}
+	for name, table := range target.Tables {
+		table.Owner = owner(table.Owner)
+		target.Tables[name] = table
+	}
+	for name, collection := range target.Collections {
+		collection.Owner = owner(collection.Owner)
+		target.Collections[name] = collection
+	}
+
+	for _, name := range sortedKeys(unmapped) {
+		ctx.AddWarning(
+			WarningTypeSecurity,
+			"owner",
+			name,
+			fmt.Sprintf("Owner %s has no owner mapping and was kept", name),
+			"medium",
+			"Add the owner to owner_mappings, or map it to an empty owner to drop it",
+		)
+	}
+}
+
+// applyGrantHandling carries, remaps or drops the grants of the source
+func applyGrantHandling(ctx *TranslationContext) {
+	source, target := ctx.SourceSchema, ctx.TargetSchema
+	handling := ctx.Preferences.EffectiveGrantHandling()
+
+	target.Grants = make(map[string]unifiedmodel.Grant)
+
+	if handling == AttributeHandlingDrop {
+		if len(source.Grants) > 0 {
+			ctx.AddWarning(
+				WarningTypeSecurity,
+				"grant",
+				"",
+				fmt.Sprintf("%d grants were not carried to the target", len(source.Grants)),
+				"low",
+				"Set grant_handling to preserve or remap to carry grants",
+			)
+		}
+		return
+	}
+
+	unmapped := make(map[string]bool)
+	for key, grant := range source.Grants {
+		if grant.Object != "" && ctx.IsObjectExcluded(grant.Object) {
+			continue
+		}
+		if handling == AttributeHandlingRemap {
+			mapped, ok := ctx.Preferences.OwnerMappings[grant.Principal]
+			if !ok {
+				unmapped[grant.Principal] = true
+			} else if mapped == "" {
+				continue
+			} else {
+				grant.Principal = mapped
+			}
+		}
+		target.Grants[key] = grant
+	}
+
+	for _, name := range sortedKeys(unmapped) {
+		ctx.AddWarning(
+			WarningTypeSecurity,
+			"grant",
+			name,
+			fmt.Sprintf("Grantee %s has no owner mapping and its grants were kept", name),
+			"medium",
+			"Add the grantee to owner_mappings, or map it to an empty grantee to drop its grants",
+		)
+	}
+}
+
+// applyCommentHandling carries or drops the comments of the target objects
+func applyCommentHandling(ctx *TranslationContext) {
+	source, target := ctx.SourceSchema, ctx.TargetSchema
+
+	if ctx.Preferences.EffectiveCommentHandling() == AttributeHandlingDrop {
+		for name, catalog := range target.Catalogs {
+			catalog.Comment = ""
+			target.Catalogs[name] = catalog
+		}
+		for name, database := range target.Databases {
+			database.Comment = ""
+			target.Databases[name] = database
+		}
+		for name, schema := range target.Schemas {
+			schema.Comment = ""
+			target.Schemas[name] = schema
+		}
+		for name, table := range target.Tables {
+			table.Comment = ""
+			target.Tables[name] = table
+		}
+		for name, collection := range target.Collections {
+			collection.Comment = ""
+			target.Collections[name] = collection
+		}
+		for name, view := range target.Views {
+			view.Comment = ""
+			target.Views[name] = view
+		}
+		target.Comments = make(map[string]unifiedmodel.Comment)
+		return
+	}
+
+	// Carry comments the translator did not carry
+	for name, table := range target.Tables {
+		if src, ok := source.Tables[name]; ok && table.Comment == "" && src.Comment != "" {
+			table.Comment = src.Comment
+			target.Tables[name] = table
+		}
+	}
+	for name, collection := range target.Collections {
+		if src, ok := source.Collections[name]; ok && collection.Comment == "" && src.Comment != "" {
+			collection.Comment = src.Comment
+			target.Collections[name] = collection
+		}
+	}
+	for name, view := range target.Views {
+		if src, ok := source.Views[name]; ok && view.Comment == "" && src.Comment != "" {
+			view.Comment = src.Comment
+			target.Views[name] = view
+		}
+	}
+	for key, comment := range source.Comments {
+		if ctx.IsObjectExcluded(comment.On) {
+			continue
+		}
+		if target.Comments == nil {
+			target.Comments = make(map[string]unifiedmodel.Comment)
+		}
+		if _, exists := target.Comments[key]; !exists {
+			target.Comments[key] = comment
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redbco/redb-open/pkg/dbcapabilities"
+	"github.com/redbco/redb-open/pkg/unifiedmodel"
+)
+
+func newAttributePolicyContext(preferences TranslationPreferences) *TranslationContext {
+	ctx := NewTranslationContext(context.Background(), &TranslationRequest{
+		SourceDatabase: dbcapabilities.PostgreSQL,
+		TargetDatabase: dbcapabilities.MySQL,
+		Preferences:    preferences,
+		RequestID:      "attribute-policy-test",
+	})
+	ctx.SetSourceSchema(&unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {Name: "orders", Owner: "app_owner", Comment: "Customer orders"},
+		},
+		Grants: map[string]unifiedmodel.Grant{
+			"reporting_select_orders": {Principal: "reporting", Privilege: "SELECT", Scope: "table", Object: "orders"},
+			"etl_insert_orders":       {Principal: "etl", Privilege: "INSERT", Scope: "table", Object: "orders"},
+		},
+	})
+	ctx.SetTargetSchema(&unifiedmodel.UnifiedModel{
+		Tables: map[string]unifiedmodel.Table{
+			"orders": {Name: "orders"},
+		},
+	})
+	return ctx
+}
+
+func TestApplyAttributePolicies_Defaults(t *testing.T) {
+	ctx := newAttributePolicyContext(TranslationPreferences{})
+	applyAttributePolicies(ctx)
+
+	table := ctx.TargetSchema.Tables["orders"]
+	if table.Owner != "app_owner" {
+		t.Errorf("expected owner app_owner to be preserved, got %q", table.Owner)
+	}
+	if table.Comment != "Customer orders" {
+		t.Errorf("expected comment to be preserved, got %q", table.Comment)
+	}
+	if len(ctx.TargetSchema.Grants) != 0 {
+		t.Errorf("expected grants to be dropped by default, got %d", len(ctx.TargetSchema.Grants))
+	}
+	if len(ctx.Warnings) != 1 || ctx.Warnings[0].ObjectType != "grant" {
+		t.Errorf("expected a warning for the dropped grants, got %+v", ctx.Warnings)
+	}
+}
+
+func TestApplyAttributePolicies_Remap(t *testing.T) {
+	ctx := newAttributePolicyContext(TranslationPreferences{
+		OwnerHandling:   AttributeHandlingRemap,
+		GrantHandling:   AttributeHandlingRemap,
+		CommentHandling: AttributeHandlingDrop,
+		OwnerMappings: map[string]string{
+			"app_owner": "svc_owner",
+			"reporting": "bi_reader",
+			"etl":       "",
+		},
+	})
+	applyAttributePolicies(ctx)
+
+	table := ctx.TargetSchema.Tables["orders"]
+	if table.Owner != "svc_owner" {
+		t.Errorf("expected owner svc_owner, got %q", table.Owner)
+	}
+	if table.Comment != "" {
+		t.Errorf("expected comment to be dropped, got %q", table.Comment)
+	}
+	if len(ctx.TargetSchema.Grants) != 1 {
+		t.Fatalf("expected 1 grant, got %d", len(ctx.TargetSchema.Grants))
+	}
+	if grant := ctx.TargetSchema.Grants["reporting_select_orders"]; grant.Principal != "bi_reader" {
+		t.Errorf("expected grantee bi_reader, got %q", grant.Principal)
+	}
+	if len(ctx.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %+v", ctx.Warnings)
+	}
+}
+
+func TestApplyAttributePolicies_RemapUnmappedOwner(t *testing.T) {
+	ctx := newAttributePolicyContext(TranslationPreferences{
+		OwnerHandling: AttributeHandlingRemap,
+		OwnerMappings: map[string]string{"someone_else": "svc_owner"},
+	})
+	applyAttributePolicies(ctx)
+
+	if owner := ctx.TargetSchema.Tables["orders"].Owner; owner != "app_owner" {
+		t.Errorf("expected unmapped owner to be kept, got %q", owner)
+	}
+
+	found := false
+	for _, warning := range ctx.Warnings {
+		if warning.ObjectType == "owner" && warning.ObjectName == "app_owner" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a warning for the unmapped owner, got %+v", ctx.Warnings)
+	}
+}
+
+func TestValidateAttributeHandling(t *testing.T) {
+	tests := []struct {
+		name           string
+		preferences    TranslationPreferences
+		expectedErrors int
+	}{
+		{name: "defaults", preferences: TranslationPreferences{}, expectedErrors: 0},
+		{
+			name:           "remap with mappings",
+			preferences:    TranslationPreferences{OwnerHandling: AttributeHandlingRemap, OwnerMappings: map[string]string{"a": "b"}},
+			expectedErrors: 0,
+		},
+		{name: "remap without mappings", preferences: TranslationPreferences{GrantHandling: AttributeHandlingRemap}, expectedErrors: 1},
+		{name: "comment remap", preferences: TranslationPreferences{CommentHandling: AttributeHandlingRemap}, expectedErrors: 1},
+		{name: "unknown handling", preferences: TranslationPreferences{OwnerHandling: "keep"}, expectedErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validateAttributeHandling(tt.preferences)
+			if len(errors) != tt.expectedErrors {
+				t.Errorf("expected %d errors, got %d: %+v", tt.expectedErrors, len(errors), errors)
+			}
+		})
+	}
+}
@@ -67,6 +67,12 @@ type TranslationPreferences struct {
 	IncludeOriginalNames bool `json:"include_original_names"`
 	UseQualifiedNames    bool `json:"use_qualified_names"`
 	PreserveCaseStyle    bool `json:"preserve_case_style"`
+
+	// Handling of owners, grants and comments on the target
+	OwnerHandling   AttributeHandling `json:"owner_handling,omitempty"`   // Default: preserve
+	GrantHandling   AttributeHandling `json:"grant_handling,omitempty"`   // Default: drop
+	CommentHandling AttributeHandling `json:"comment_handling,omitempty"` // Default: preserve, remap is not supported
+	OwnerMappings   map[string]string `json:"owner_mappings,omitempty"`   // Source owner or grantee -> target owner or grantee, "" drops it
 }
 
 // TranslationResult contains the results of schema translation
@@ -212,6 +218,15 @@ const (
 	WarningTypeSecurity      WarningType = "security"
 )
 
+// AttributeHandling defines how owners, grants and comments of source objects are carried to the target
+type AttributeHandling string
+
+const (
+	AttributeHandlingPreserve AttributeHandling = "preserve" // Carry as in the source
+	AttributeHandlingRemap    AttributeHandling = "remap"    // Carry with owners and grantees replaced through OwnerMappings
+	AttributeHandlingDrop     AttributeHandling = "drop"     // Do not carry
+)
+
 type UnsupportedFeature struct {
 	FeatureType   string   `json:"feature_type"`
 	ObjectType    string   `json:"object_type"`
@@ -82,6 +82,9 @@ func (ut *UnifiedTranslatorImpl) Translate(ctx context.Context, request *Transla
 		return ut.createErrorResult(request, translationErr), nil
 	}
 
+	// Apply owner, grant and comment handling
+	applyAttributePolicies(translationCtx)
+
 	// Finalize processing
 	translationCtx.FinishProcessing()
 
@@ -234,6 +237,9 @@ func (ut *UnifiedTranslatorImpl) ValidateRequest(request *TranslationRequest) []
 		}
 	}
 
+	// Owner, grant and comment handling
+	errors = append(errors, validateAttributeHandling(request.Preferences)...)
+
 	// Source schema validation (UnifiedModel is already validated by Go type system)
 	// No additional validation needed since it's a typed struct
 
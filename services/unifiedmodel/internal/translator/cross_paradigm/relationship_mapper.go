@@ -552,18 +552,9 @@ func (rm *RelationshipMapper) mapPropertyTypeToColumnType(propertyType string) s
 
 // getParadigmFromDatabase determines the paradigm based on database type
 func (rm *RelationshipMapper) getParadigmFromDatabase(dbType dbcapabilities.DatabaseType) dbcapabilities.DataParadigm {
-	switch dbType {
-	case dbcapabilities.PostgreSQL, dbcapabilities.MySQL, dbcapabilities.SQLServer, dbcapabilities.Oracle, dbcapabilities.MariaDB:
-		return dbcapabilities.ParadigmRelational
-	case dbcapabilities.MongoDB:
-		return dbcapabilities.ParadigmDocument
-	case dbcapabilities.Neo4j:
-		return dbcapabilities.ParadigmGraph
-	case dbcapabilities.Redis:
-		return dbcapabilities.ParadigmKeyValue
-	case dbcapabilities.Cassandra:
-		return dbcapabilities.ParadigmWideColumn
-	default:
-		return dbcapabilities.ParadigmRelational // Default fallback
+	// The first paradigm of a database is its primary one
+	if c, ok := dbcapabilities.Get(dbType); ok && len(c.Paradigms) > 0 {
+		return c.Paradigms[0]
 	}
+	return dbcapabilities.ParadigmRelational // Default fallback
 }
@@ -167,14 +167,14 @@ func (bs *BaseStrategy) SanitizeColumnName(name string) string {
 
 // DetermineJSONBColumnType returns the appropriate JSONB column type for the target database
 func (bs *BaseStrategy) DetermineJSONBColumnType(targetDB dbcapabilities.DatabaseType) string {
-	switch targetDB {
-	case dbcapabilities.PostgreSQL, dbcapabilities.CockroachDB:
+	switch dbcapabilities.GetTypeSystem(targetDB) {
+	case dbcapabilities.TypeSystemPostgreSQL:
 		return "jsonb"
-	case dbcapabilities.MySQL, dbcapabilities.MariaDB:
+	case dbcapabilities.TypeSystemMySQL:
 		return "json"
-	case dbcapabilities.SQLServer:
+	case dbcapabilities.TypeSystemTSQL:
 		return "nvarchar(max)" // SQL Server uses nvarchar for JSON
-	case dbcapabilities.Oracle:
+	case dbcapabilities.TypeSystemOracle:
 		return "clob" // Oracle can use CLOB for JSON
 	default:
 		return "text" // Fallback to text